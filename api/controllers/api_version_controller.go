@@ -0,0 +1,40 @@
+/*
+ * @module api/controllers/api_version_controller
+ * @description API版本说明，返回各版本的路径前缀、当前版本、版本和路由的弃用计划，供调用方规划迁移
+ * @architecture MVC架构 - 控制器层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow 读取版本登记表 -> 返回版本说明
+ * @rules 无需认证（在白名单中）；不挂载在版本前缀下
+ * @dependencies datahub-service/api/middleware, github.com/go-chi/render
+ * @refs api/middleware/api_version.go, api/routes.go
+ */
+
+package controllers
+
+import (
+	"datahub-service/api/middleware"
+	"net/http"
+
+	"github.com/go-chi/render"
+)
+
+// APIVersionController API版本控制器
+type APIVersionController struct {
+	versions *middleware.APIVersionRegistry
+}
+
+// NewAPIVersionController 创建API版本控制器实例
+func NewAPIVersionController(versions *middleware.APIVersionRegistry) *APIVersionController {
+	return &APIVersionController{versions: versions}
+}
+
+// GetAPIVersions 获取API版本说明
+// @Summary 获取API版本说明
+// @Description 返回各API版本的路径前缀、是否为当前版本以及弃用计划。当前版本的接口路径为/api/v1加上文档中的路径；无版本前缀的旧路径由legacy版本提供，响应头带Deprecation、Sunset（配置了停用日期时）和指向/api/v1同一路径的Link。新版本只登记有破坏性变更的路由，未变更的路由与上一版本相同
+// @Tags 系统
+// @Produce json
+// @Success 200 {object} APIResponse[[]middleware.APIVersionInfo] "获取成功"
+// @Router /api/versions [get]
+func (c *APIVersionController) GetAPIVersions(w http.ResponseWriter, r *http.Request) {
+	render.JSON(w, r, SuccessResponse("获取API版本说明成功", c.versions.Describe()))
+}
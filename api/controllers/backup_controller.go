@@ -0,0 +1,94 @@
+/*
+ * @module api/controllers/backup_controller
+ * @description 备份记录控制器，提供备份记录及其完整性校验结果的查询，以及手动触发校验的接口
+ * @architecture 分层架构 - 控制器层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow HTTP请求 -> 控制器 -> 数据治理服务/备份校验服务 -> 数据库
+ * @rules 统一的错误处理和响应格式；手动校验同步执行，同一备份记录同时只允许一个校验
+ * @dependencies datahub-service/service, datahub-service/service/backup, github.com/go-chi/chi/v5
+ * @refs service/backup/verifier.go
+ */
+
+package controllers
+
+import (
+	"datahub-service/service"
+	"datahub-service/service/backup"
+	"datahub-service/service/models"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+)
+
+// BackupController 备份记录控制器
+type BackupController struct {
+}
+
+// NewBackupController 创建备份记录控制器实例
+func NewBackupController() *BackupController {
+	return &BackupController{}
+}
+
+// BackupRecordListResponse 备份记录列表响应结构
+type BackupRecordListResponse struct {
+	List []models.BackupRecord `json:"list"`
+	models.PageMeta
+}
+
+// GetBackupRecords 获取备份记录列表
+// @Summary 获取备份记录列表
+// @Description 分页获取备份记录及其完整性校验结果
+// @Tags 备份管理
+// @Produce json
+// @Param page query int false "页码" default(1)
+// @Param size query int false "每页数量" default(10)
+// @Param config_id query string false "备份配置ID"
+// @Param status query string false "备份状态" Enums(in_progress, success, failure)
+// @Param verification_status query string false "校验状态" Enums(unverified, verified, corrupt, failed)
+// @Success 200 {object} APIResponse[BackupRecordListResponse] "获取成功"
+// @Router /backups/records [get]
+func (c *BackupController) GetBackupRecords(w http.ResponseWriter, r *http.Request) {
+	page, size := parsePagination(r)
+	query := r.URL.Query()
+
+	records, total, err := service.GlobalGovernanceService.GetBackupRecords(page, size, query.Get("config_id"), query.Get("status"), query.Get("verification_status"))
+	if err != nil {
+		render.JSON(w, r, MapErrorResponse("获取备份记录列表失败", err))
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("获取备份记录列表成功", BackupRecordListResponse{
+		List:     records,
+		PageMeta: models.NewPageMeta(total, page, size),
+	}))
+}
+
+// VerifyBackupRecord 校验备份完整性
+// @Summary 校验备份完整性
+// @Description 下载备份文件并还原到临时schema，比对行数和校验和后将记录标记为verified/corrupt，校验出错时标记为failed
+// @Tags 备份管理
+// @Produce json
+// @Param id path string true "备份记录ID"
+// @Success 200 {object} APIResponse[models.BackupRecord] "校验完成"
+// @Failure 400 {object} APIResponse[any] "备份记录不可校验"
+// @Failure 404 {object} APIResponse[any] "备份记录不存在"
+// @Failure 409 {object} APIResponse[any] "正在校验中"
+// @Router /backups/records/{id}/verify [post]
+func (c *BackupController) VerifyBackupRecord(w http.ResponseWriter, r *http.Request) {
+	record, err := service.GlobalBackupVerifier.VerifyRecord(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		switch {
+		case errors.Is(err, backup.ErrBackupNotVerifiable):
+			render.JSON(w, r, BadRequestResponse("校验备份完整性失败", err))
+		case errors.Is(err, backup.ErrVerificationRunning):
+			render.JSON(w, r, ConflictResponse("校验备份完整性失败", err))
+		default:
+			render.JSON(w, r, MapErrorResponse("校验备份完整性失败", err))
+		}
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("备份完整性校验完成", record))
+}
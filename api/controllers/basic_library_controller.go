@@ -14,14 +14,22 @@ package controllers
 import (
 	"datahub-service/service"
 	"datahub-service/service/basic_library"
+	"datahub-service/service/config"
 	"datahub-service/service/database"
+	"datahub-service/service/meta"
 	"datahub-service/service/models"
+	"datahub-service/service/rbac"
+	"datahub-service/service/utils"
+	"errors"
+	"fmt"
+	"io"
 	"net/http"
 	"strconv"
 	"strings"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/render"
+	"gorm.io/gorm"
 )
 
 // BasicLibraryController 数据基础库控制器
@@ -112,6 +120,7 @@ type UpdateDataInterfaceRequest struct {
 	InterfaceConfig   map[string]interface{} `json:"interface_config,omitempty"`
 	ParseConfig       map[string]interface{} `json:"parse_config,omitempty"`
 	TableFieldsConfig map[string]interface{} `json:"table_fields_config,omitempty"`
+	RowVersion        int64                  `json:"row_version,omitempty" example:"3"` // 期望版本，也可通过If-Match头传递
 }
 
 // UpdateInterfaceFieldsRequest 更新接口字段配置请求结构
@@ -124,26 +133,20 @@ type UpdateInterfaceFieldsRequest struct {
 
 // BasicLibraryListResponse 数据基础库列表响应结构
 type BasicLibraryListResponse struct {
-	List  []models.BasicLibrary `json:"list"`
-	Total int64                 `json:"total"`
-	Page  int                   `json:"page"`
-	Size  int                   `json:"size"`
+	List []models.BasicLibrary `json:"list"`
+	models.PageMeta
 }
 
 // DataSourceListResponse 数据源列表响应结构
 type DataSourceListResponse struct {
-	List  []models.DataSource `json:"list"`
-	Total int64               `json:"total"`
-	Page  int                 `json:"page"`
-	Size  int                 `json:"size"`
+	List []models.DataSource `json:"list"`
+	models.PageMeta
 }
 
 // DataInterfaceListResponse 数据接口列表响应结构
 type DataInterfaceListResponse struct {
-	List  []models.DataInterface `json:"list"`
-	Total int64                  `json:"total"`
-	Page  int                    `json:"page"`
-	Size  int                    `json:"size"`
+	List []models.DataInterface `json:"list"`
+	models.PageMeta
 }
 
 // ImportCSVRequest CSV导入请求结构
@@ -168,20 +171,19 @@ type ImportCSVResponse struct {
 // @Accept json
 // @Produce json
 // @Param request body models.BasicLibrary true "数据基础库请求"
-// @Success 200 {object} APIResponse
-// @Failure 400 {object} APIResponse
-// @Failure 500 {object} APIResponse
+// @Success 200 {object} APIResponse[any]
+// @Failure 400 {object} APIResponse[any]
+// @Failure 500 {object} APIResponse[any]
 // @Router /basic-libraries/add-basic-library [post]
 func (c *BasicLibraryController) AddBasicLibrary(w http.ResponseWriter, r *http.Request) {
 	var req models.BasicLibrary
-	if err := render.DecodeJSON(r.Body, &req); err != nil {
-		render.JSON(w, r, BadRequestResponse("请求参数格式错误", err))
+	if !decodeAndValidate(w, r, &req) {
 		return
 	}
 
-	err := c.service.CreateBasicLibrary(&req)
+	err := c.service.CreateBasicLibrary(r.Context(), &req)
 	if err != nil {
-		render.JSON(w, r, InternalErrorResponse("添加数据基础库失败", err))
+		render.JSON(w, r, MapErrorResponse("添加数据基础库失败", err))
 		return
 	}
 
@@ -193,9 +195,9 @@ func (c *BasicLibraryController) AddBasicLibrary(w http.ResponseWriter, r *http.
 // @Tags 数据基础库
 // @Produce json
 // @Param id path string true "数据基础库ID"
-// @Success 200 {object} APIResponse
-// @Failure 400 {object} APIResponse
-// @Failure 500 {object} APIResponse
+// @Success 200 {object} APIResponse[any]
+// @Failure 400 {object} APIResponse[any]
+// @Failure 500 {object} APIResponse[any]
 // @Router /basic-libraries/{id} [delete]
 func (c *BasicLibraryController) DeleteBasicLibrary(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
@@ -205,16 +207,16 @@ func (c *BasicLibraryController) DeleteBasicLibrary(w http.ResponseWriter, r *ht
 	}
 
 	// 先根据ID查询基础库信息
-	library, err := c.service.GetBasicLibrary(id)
+	library, err := c.service.GetBasicLibrary(r.Context(), id)
 	if err != nil {
-		render.JSON(w, r, InternalErrorResponse("查询数据基础库失败", err))
+		render.JSON(w, r, MapErrorResponse("查询数据基础库失败", err))
 		return
 	}
 
 	// 调用删除方法
-	err = c.service.DeleteBasicLibrary(library)
+	err = c.service.DeleteBasicLibrary(r.Context(), library)
 	if err != nil {
-		render.JSON(w, r, InternalErrorResponse("删除数据基础库失败", err))
+		render.JSON(w, r, MapErrorResponse("删除数据基础库失败", err))
 		return
 	}
 
@@ -227,14 +229,13 @@ func (c *BasicLibraryController) DeleteBasicLibrary(w http.ResponseWriter, r *ht
 // @Accept json
 // @Produce json
 // @Param request body UpdateBasicLibraryRequest true "修改数据基础库请求"
-// @Success 200 {object} APIResponse
-// @Failure 400 {object} APIResponse
-// @Failure 500 {object} APIResponse
+// @Success 200 {object} APIResponse[any]
+// @Failure 400 {object} APIResponse[any]
+// @Failure 500 {object} APIResponse[any]
 // @Router /basic-libraries/update-basic-library [post]
 func (c *BasicLibraryController) UpdateBasicLibrary(w http.ResponseWriter, r *http.Request) {
 	var req UpdateBasicLibraryRequest
-	if err := render.DecodeJSON(r.Body, &req); err != nil {
-		render.JSON(w, r, BadRequestResponse("请求参数格式错误", err))
+	if !decodeAndValidate(w, r, &req) {
 		return
 	}
 
@@ -263,9 +264,9 @@ func (c *BasicLibraryController) UpdateBasicLibrary(w http.ResponseWriter, r *ht
 		return
 	}
 
-	err := c.service.UpdateBasicLibrary(req.ID, updates)
+	err := c.service.UpdateBasicLibrary(r.Context(), req.ID, updates)
 	if err != nil {
-		render.JSON(w, r, InternalErrorResponse("修改数据基础库失败", err))
+		render.JSON(w, r, MapErrorResponse("修改数据基础库失败", err))
 		return
 	}
 
@@ -278,20 +279,19 @@ func (c *BasicLibraryController) UpdateBasicLibrary(w http.ResponseWriter, r *ht
 // @Accept json
 // @Produce json
 // @Param request body models.DataSource true "数据源请求"
-// @Success 200 {object} APIResponse
-// @Failure 400 {object} APIResponse
-// @Failure 500 {object} APIResponse
+// @Success 200 {object} APIResponse[any]
+// @Failure 400 {object} APIResponse[any]
+// @Failure 500 {object} APIResponse[any]
 // @Router /basic-libraries/add-datasource [post]
 func (c *BasicLibraryController) AddDataSource(w http.ResponseWriter, r *http.Request) {
 	var req models.DataSource
-	if err := render.DecodeJSON(r.Body, &req); err != nil {
-		render.JSON(w, r, BadRequestResponse("请求参数格式错误", err))
+	if !decodeAndValidate(w, r, &req) {
 		return
 	}
 
 	err := c.service.CreateDataSource(&req)
 	if err != nil {
-		render.JSON(w, r, InternalErrorResponse("添加数据源失败", err))
+		render.JSON(w, r, MapErrorResponse("添加数据源失败", err))
 		return
 	}
 
@@ -304,14 +304,13 @@ func (c *BasicLibraryController) AddDataSource(w http.ResponseWriter, r *http.Re
 // @Accept json
 // @Produce json
 // @Param request body UpdateDataSourceRequest true "修改数据源请求"
-// @Success 200 {object} APIResponse
-// @Failure 400 {object} APIResponse
-// @Failure 500 {object} APIResponse
+// @Success 200 {object} APIResponse[any]
+// @Failure 400 {object} APIResponse[any]
+// @Failure 500 {object} APIResponse[any]
 // @Router /basic-libraries/update-datasource [post]
 func (c *BasicLibraryController) UpdateDataSource(w http.ResponseWriter, r *http.Request) {
 	var req UpdateDataSourceRequest
-	if err := render.DecodeJSON(r.Body, &req); err != nil {
-		render.JSON(w, r, BadRequestResponse("请求参数格式错误", err))
+	if !decodeAndValidate(w, r, &req) {
 		return
 	}
 
@@ -349,7 +348,7 @@ func (c *BasicLibraryController) UpdateDataSource(w http.ResponseWriter, r *http
 
 	err := c.service.UpdateDataSource(req.ID, updates)
 	if err != nil {
-		render.JSON(w, r, InternalErrorResponse("修改数据源失败", err))
+		render.JSON(w, r, MapErrorResponse("修改数据源失败", err))
 		return
 	}
 
@@ -361,9 +360,9 @@ func (c *BasicLibraryController) UpdateDataSource(w http.ResponseWriter, r *http
 // @Tags 数据基础库
 // @Produce json
 // @Param id path string true "数据源ID"
-// @Success 200 {object} APIResponse
-// @Failure 400 {object} APIResponse
-// @Failure 500 {object} APIResponse
+// @Success 200 {object} APIResponse[any]
+// @Failure 400 {object} APIResponse[any]
+// @Failure 500 {object} APIResponse[any]
 // @Router /basic-libraries/datasources/{id} [delete]
 func (c *BasicLibraryController) DeleteDataSource(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
@@ -375,40 +374,101 @@ func (c *BasicLibraryController) DeleteDataSource(w http.ResponseWriter, r *http
 	// 先根据ID查询数据源信息
 	dataSource, err := c.service.GetDataSource(id)
 	if err != nil {
-		render.JSON(w, r, InternalErrorResponse("查询数据源失败", err))
+		render.JSON(w, r, MapErrorResponse("查询数据源失败", err))
 		return
 	}
 
 	// 调用删除方法
 	err = c.service.DeleteDataSource(dataSource)
 	if err != nil {
-		render.JSON(w, r, InternalErrorResponse("删除数据源失败", err))
+		render.JSON(w, r, MapErrorResponse("删除数据源失败", err))
 		return
 	}
 
 	render.JSON(w, r, SuccessResponse("删除数据源成功", nil))
 }
 
+// maxFixtureUploadSize 样例文件大小上限
+const maxFixtureUploadSize = 10 << 20
+
+// @Summary 上传模拟数据源样例
+// @Description 上传JSON文件作为模拟数据源在指定URL路径上的响应，同一路径已有样例时覆盖
+// @Tags 数据基础库
+// @Accept multipart/form-data
+// @Produce json
+// @Param id path string true "数据源ID"
+// @Param path formData string true "URL路径，与接口的URL后缀一致，* 表示默认响应"
+// @Param method formData string false "请求方法，为空时匹配所有方法"
+// @Param file formData file true "JSON样例文件"
+// @Success 200 {object} APIResponse[any]
+// @Failure 400 {object} APIResponse[any]
+// @Failure 500 {object} APIResponse[any]
+// @Router /basic-libraries/datasources/{id}/fixtures [post]
+func (c *BasicLibraryController) UploadFixture(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		render.JSON(w, r, BadRequestResponse("数据源ID不能为空", nil))
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxFixtureUploadSize)
+	if err := r.ParseMultipartForm(maxFixtureUploadSize); err != nil {
+		render.JSON(w, r, BadRequestResponse("解析上传文件失败", err))
+		return
+	}
+
+	path := strings.TrimSpace(r.FormValue("path"))
+	if path == "" {
+		render.JSON(w, r, BadRequestResponse("URL路径不能为空", nil))
+		return
+	}
+	if path != meta.MockFixtureFallbackKey && !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	key := path
+	if method := strings.ToUpper(strings.TrimSpace(r.FormValue("method"))); method != "" {
+		key = method + " " + path
+	}
+
+	file, _, err := r.FormFile("file")
+	if err != nil {
+		render.JSON(w, r, BadRequestResponse("缺少样例文件", err))
+		return
+	}
+	defer file.Close()
+	content, err := io.ReadAll(file)
+	if err != nil {
+		render.JSON(w, r, BadRequestResponse("读取样例文件失败", err))
+		return
+	}
+
+	if err := c.service.SaveFixture(id, key, content); err != nil {
+		render.JSON(w, r, MapErrorResponse("保存样例数据失败", err))
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("保存样例数据成功", map[string]interface{}{"fixture": key, "size": len(content)}))
+}
+
 // @Summary 添加数据接口
 // @Description 添加数据接口
 // @Tags 数据基础库
 // @Accept json
 // @Produce json
 // @Param request body models.DataInterface true "数据接口请求"
-// @Success 200 {object} APIResponse
-// @Failure 400 {object} APIResponse
-// @Failure 500 {object} APIResponse
+// @Success 200 {object} APIResponse[any]
+// @Failure 400 {object} APIResponse[any]
+// @Failure 500 {object} APIResponse[any]
 // @Router /basic-libraries/add-interface [post]
 func (c *BasicLibraryController) AddInterface(w http.ResponseWriter, r *http.Request) {
 	var req models.DataInterface
-	if err := render.DecodeJSON(r.Body, &req); err != nil {
-		render.JSON(w, r, BadRequestResponse("请求参数格式错误", err))
+	if !decodeAndValidate(w, r, &req) {
 		return
 	}
 
 	err := c.service.CreateDataInterface(&req)
 	if err != nil {
-		render.JSON(w, r, InternalErrorResponse("添加数据接口失败", err))
+		render.JSON(w, r, MapErrorResponse("添加数据接口失败", err))
 		return
 	}
 
@@ -421,14 +481,15 @@ func (c *BasicLibraryController) AddInterface(w http.ResponseWriter, r *http.Req
 // @Accept json
 // @Produce json
 // @Param request body UpdateDataInterfaceRequest true "修改数据接口请求"
-// @Success 200 {object} APIResponse
-// @Failure 400 {object} APIResponse
-// @Failure 500 {object} APIResponse
+// @Param If-Match header string false "期望版本（ETag），不一致时返回409"
+// @Success 200 {object} APIResponse[any]
+// @Failure 400 {object} APIResponse[any]
+// @Failure 409 {object} APIResponse[VersionConflictData] "版本冲突"
+// @Failure 500 {object} APIResponse[any]
 // @Router /basic-libraries/update-interface [post]
 func (c *BasicLibraryController) UpdateInterface(w http.ResponseWriter, r *http.Request) {
 	var req UpdateDataInterfaceRequest
-	if err := render.DecodeJSON(r.Body, &req); err != nil {
-		render.JSON(w, r, BadRequestResponse("请求参数格式错误", err))
+	if !decodeAndValidate(w, r, &req) {
 		return
 	}
 
@@ -472,9 +533,18 @@ func (c *BasicLibraryController) UpdateInterface(w http.ResponseWriter, r *http.
 		return
 	}
 
-	err := c.service.UpdateDataInterface(req.ID, updates)
+	expectedVersion, err := expectedRowVersion(r, req.RowVersion)
 	if err != nil {
-		render.JSON(w, r, InternalErrorResponse("修改数据接口失败", err))
+		render.JSON(w, r, BadRequestResponse(err.Error(), err))
+		return
+	}
+
+	err = c.service.UpdateDataInterface(req.ID, expectedVersion, updates)
+	if err != nil {
+		if handleVersionConflict(w, r, err) {
+			return
+		}
+		render.JSON(w, r, MapErrorResponse("修改数据接口失败", err))
 		return
 	}
 
@@ -486,9 +556,9 @@ func (c *BasicLibraryController) UpdateInterface(w http.ResponseWriter, r *http.
 // @Tags 数据基础库
 // @Produce json
 // @Param id path string true "数据接口ID"
-// @Success 200 {object} APIResponse
-// @Failure 400 {object} APIResponse
-// @Failure 500 {object} APIResponse
+// @Success 200 {object} APIResponse[any]
+// @Failure 400 {object} APIResponse[any]
+// @Failure 500 {object} APIResponse[any]
 // @Router /basic-libraries/interfaces/{id} [delete]
 func (c *BasicLibraryController) DeleteInterface(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
@@ -500,14 +570,14 @@ func (c *BasicLibraryController) DeleteInterface(w http.ResponseWriter, r *http.
 	// 先根据ID查询数据接口信息
 	dataInterface, err := c.service.GetDataInterface(id)
 	if err != nil {
-		render.JSON(w, r, InternalErrorResponse("查询数据接口失败", err))
+		render.JSON(w, r, MapErrorResponse("查询数据接口失败", err))
 		return
 	}
 
 	// 调用删除方法
 	err = c.service.DeleteDataInterface(dataInterface)
 	if err != nil {
-		render.JSON(w, r, InternalErrorResponse("删除数据接口失败", err))
+		render.JSON(w, r, MapErrorResponse("删除数据接口失败", err))
 		return
 	}
 
@@ -521,20 +591,19 @@ func (c *BasicLibraryController) DeleteInterface(w http.ResponseWriter, r *http.
 // @Accept json
 // @Produce json
 // @Param request body DataSourceTestRequest true "测试请求"
-// @Success 200 {object} APIResponse{data=DataSourceTestResponse}
-// @Failure 400 {object} APIResponse
-// @Failure 500 {object} APIResponse
+// @Success 200 {object} APIResponse[DataSourceTestResponse]
+// @Failure 400 {object} APIResponse[any]
+// @Failure 500 {object} APIResponse[any]
 // @Router /basic-libraries/test-datasource [post]
 func (c *BasicLibraryController) TestDataSource(w http.ResponseWriter, r *http.Request) {
 	var req DataSourceTestRequest
-	if err := render.DecodeJSON(r.Body, &req); err != nil {
-		render.JSON(w, r, BadRequestResponse("请求参数格式错误", err))
+	if !decodeAndValidate(w, r, &req) {
 		return
 	}
 
 	result, err := c.service.TestDataSource(req.DataSourceID, req.TestType, req.Config)
 	if err != nil {
-		render.JSON(w, r, InternalErrorResponse("数据源测试失败", err))
+		render.JSON(w, r, MapErrorResponse("数据源测试失败", err))
 		return
 	}
 
@@ -548,35 +617,91 @@ func (c *BasicLibraryController) TestDataSource(w http.ResponseWriter, r *http.R
 // @Accept json
 // @Produce json
 // @Param request body InterfaceTestRequest true "测试请求"
-// @Success 200 {object} APIResponse{data=InterfaceTestResponse}
-// @Failure 400 {object} APIResponse
-// @Failure 500 {object} APIResponse
+// @Success 200 {object} APIResponse[InterfaceTestResponse]
+// @Failure 400 {object} APIResponse[any]
+// @Failure 500 {object} APIResponse[any]
 // @Router /basic-libraries/test-interface [post]
 func (c *BasicLibraryController) TestInterface(w http.ResponseWriter, r *http.Request) {
 	var req InterfaceTestRequest
-	if err := render.DecodeJSON(r.Body, &req); err != nil {
-		render.JSON(w, r, BadRequestResponse("请求参数格式错误", err))
+	if !decodeAndValidate(w, r, &req) {
 		return
 	}
 
 	result, err := c.service.TestInterface(req.InterfaceID, req.TestType, req.Parameters, req.Options)
 	if err != nil {
-		render.JSON(w, r, InternalErrorResponse("接口测试失败", err))
+		render.JSON(w, r, MapErrorResponse("接口测试失败", err))
 		return
 	}
 
 	render.JSON(w, r, SuccessResponse("测试完成", result))
 }
 
+// ExportDataSourceHealthReport 导出数据源接口健康报告
+// @Summary 导出数据源接口健康报告
+// @Description 统计数据源下所有接口近N天的调用成功率、平均延迟和错误分类明细，format=xlsx时导出xlsx工作簿（概览、接口统计、错误明细三个工作表），format=html时返回可打印的HTML页面
+// @Tags 数据基础库
+// @Produce json
+// @Produce application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Produce text/html
+// @Param id path string true "数据源ID"
+// @Param days query int false "统计天数" default(30)
+// @Param format query string false "导出格式" Enums(json,xlsx,html) default(json)
+// @Success 200 {object} APIResponse[basic_library.DataSourceHealthReport]
+// @Failure 400 {object} APIResponse[any]
+// @Failure 404 {object} APIResponse[any]
+// @Failure 500 {object} APIResponse[any]
+// @Router /basic-libraries/datasources/{id}/health-report [get]
+func (c *BasicLibraryController) ExportDataSourceHealthReport(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	days, _ := strconv.Atoi(r.URL.Query().Get("days"))
+	format := r.URL.Query().Get("format")
+
+	report, err := c.service.GetHealthReportService().GenerateDataSourceHealthReport(r.Context(), id, days)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			render.JSON(w, r, NotFoundResponse("数据源不存在", err))
+			return
+		}
+		render.JSON(w, r, MapErrorResponse("生成接口健康报告失败", err))
+		return
+	}
+
+	filename := fmt.Sprintf("interface_health_%s_%s", report.DataSourceID, report.GeneratedAt.Format("20060102"))
+	switch format {
+	case "", "json":
+		render.JSON(w, r, SuccessResponse("生成接口健康报告成功", report))
+	case "xlsx":
+		content, err := basic_library.RenderHealthReportXLSX(report)
+		if err != nil {
+			render.JSON(w, r, MapErrorResponse("生成接口健康报告失败", err))
+			return
+		}
+		w.Header().Set("Content-Type", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet")
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%s.xlsx", filename))
+		w.Write(content)
+	case "html":
+		content, err := basic_library.RenderHealthReportHTML(report)
+		if err != nil {
+			render.JSON(w, r, MapErrorResponse("生成接口健康报告失败", err))
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Header().Set("Content-Disposition", fmt.Sprintf("inline; filename=%s.html", filename))
+		w.Write(content)
+	default:
+		render.JSON(w, r, BadRequestResponse("不支持的导出格式: "+format, nil))
+	}
+}
+
 // GetDataSourceStatus 获取数据源状态
 // @Summary 获取数据源运行状态
 // @Description 获取数据源的连接状态、最近同步时间等信息
 // @Tags 数据基础库
 // @Produce json
 // @Param id path string true "数据源ID"
-// @Success 200 {object} APIResponse
-// @Failure 400 {object} APIResponse
-// @Failure 500 {object} APIResponse
+// @Success 200 {object} APIResponse[any]
+// @Failure 400 {object} APIResponse[any]
+// @Failure 500 {object} APIResponse[any]
 // @Router /basic-libraries/datasource-status/{id} [get]
 func (c *BasicLibraryController) GetDataSourceStatus(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
@@ -587,7 +712,7 @@ func (c *BasicLibraryController) GetDataSourceStatus(w http.ResponseWriter, r *h
 
 	status, err := c.service.GetDataSourceStatus(id)
 	if err != nil {
-		render.JSON(w, r, InternalErrorResponse("获取数据源状态失败: "+err.Error(), err))
+		render.JSON(w, r, MapErrorResponse("获取数据源状态失败: "+err.Error(), err))
 		return
 	}
 
@@ -605,38 +730,29 @@ func (c *BasicLibraryController) GetDataSourceStatus(w http.ResponseWriter, r *h
 // @Param name query string false "名称搜索（支持中英文）"
 // @Param status query string false "状态过滤" Enums(active,inactive)
 // @Param created_by query string false "创建者过滤"
-// @Success 200 {object} APIResponse{data=BasicLibraryListResponse} "获取成功"
-// @Failure 400 {object} APIResponse "请求参数错误"
-// @Failure 500 {object} APIResponse "服务器内部错误"
+// @Success 200 {object} APIResponse[BasicLibraryListResponse] "获取成功"
+// @Failure 400 {object} APIResponse[any] "请求参数错误"
+// @Failure 500 {object} APIResponse[any] "服务器内部错误"
 // @Router /basic-libraries [get]
 func (c *BasicLibraryController) GetBasicLibraryList(w http.ResponseWriter, r *http.Request) {
 	// 解析查询参数
-	page := 1
-	size := 10
 	name := r.URL.Query().Get("name")
 	status := r.URL.Query().Get("status")
 	createdBy := r.URL.Query().Get("created_by")
 
-	if p, err := strconv.Atoi(r.URL.Query().Get("page")); err == nil && p > 0 {
-		page = p
-	}
-	if s, err := strconv.Atoi(r.URL.Query().Get("size")); err == nil && s > 0 && s <= 100 {
-		size = s
-	}
+	page, size := parsePagination(r)
 
 	// 调用服务层方法
-	libraries, total, err := c.service.GetBasicLibraryList(page, size, name, status, createdBy)
+	libraries, total, err := c.service.GetBasicLibraryList(r.Context(), page, size, name, status, createdBy)
 	if err != nil {
-		render.JSON(w, r, InternalErrorResponse("获取数据基础库列表失败", err))
+		render.JSON(w, r, MapErrorResponse("获取数据基础库列表失败", err))
 		return
 	}
 
 	// 构建响应
 	response := BasicLibraryListResponse{
-		List:  libraries,
-		Total: total,
-		Page:  page,
-		Size:  size,
+		List:     libraries,
+		PageMeta: models.NewPageMeta(total, page, size),
 	}
 
 	render.JSON(w, r, SuccessResponse("获取数据基础库列表成功", response))
@@ -655,40 +771,31 @@ func (c *BasicLibraryController) GetBasicLibraryList(w http.ResponseWriter, r *h
 // @Param type query string false "数据源类型过滤（如：mysql, postgresql, http等）"
 // @Param status query string false "状态过滤" Enums(active,inactive)
 // @Param name query string false "名称搜索"
-// @Success 200 {object} APIResponse{data=DataSourceListResponse} "获取成功"
-// @Failure 400 {object} APIResponse "请求参数错误"
-// @Failure 500 {object} APIResponse "服务器内部错误"
+// @Success 200 {object} APIResponse[DataSourceListResponse] "获取成功"
+// @Failure 400 {object} APIResponse[any] "请求参数错误"
+// @Failure 500 {object} APIResponse[any] "服务器内部错误"
 // @Router /basic-libraries/datasources [get]
 func (c *BasicLibraryController) GetDataSourceList(w http.ResponseWriter, r *http.Request) {
 	// 解析查询参数
-	page := 1
-	size := 10
 	libraryID := r.URL.Query().Get("library_id")
 	category := r.URL.Query().Get("category")
 	source_type := r.URL.Query().Get("type")
 	status := r.URL.Query().Get("status")
 	name := r.URL.Query().Get("name")
 
-	if p, err := strconv.Atoi(r.URL.Query().Get("page")); err == nil && p > 0 {
-		page = p
-	}
-	if s, err := strconv.Atoi(r.URL.Query().Get("size")); err == nil && s > 0 && s <= 100 {
-		size = s
-	}
+	page, size := parsePagination(r)
 
 	// 调用服务层方法
-	dataSources, total, err := c.service.GetDataSourceList(page, size, libraryID, category, source_type, status, name)
+	dataSources, total, err := c.service.GetDataSourceList(r.Context(), page, size, libraryID, category, source_type, status, name)
 	if err != nil {
-		render.JSON(w, r, InternalErrorResponse("获取数据源列表失败", err))
+		render.JSON(w, r, MapErrorResponse("获取数据源列表失败", err))
 		return
 	}
 
 	// 构建响应
 	response := DataSourceListResponse{
-		List:  dataSources,
-		Total: total,
-		Page:  page,
-		Size:  size,
+		List:     dataSources,
+		PageMeta: models.NewPageMeta(total, page, size),
 	}
 
 	render.JSON(w, r, SuccessResponse("获取数据源列表成功", response))
@@ -700,10 +807,10 @@ func (c *BasicLibraryController) GetDataSourceList(w http.ResponseWriter, r *htt
 // @Tags 数据基础库
 // @Produce json
 // @Param id path string true "接口ID" example:"550e8400-e29b-41d4-a716-446655440000"
-// @Success 200 {object} APIResponse{data=models.DataInterface} "获取成功"
-// @Failure 400 {object} APIResponse "请求参数错误"
-// @Failure 404 {object} APIResponse "接口不存在"
-// @Failure 500 {object} APIResponse "服务器内部错误"
+// @Success 200 {object} APIResponse[models.DataInterface] "获取成功"
+// @Failure 400 {object} APIResponse[any] "请求参数错误"
+// @Failure 404 {object} APIResponse[any] "接口不存在"
+// @Failure 500 {object} APIResponse[any] "服务器内部错误"
 // @Router /basic-libraries/interfaces/{id} [get]
 func (c *BasicLibraryController) GetDataInterface(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
@@ -718,7 +825,11 @@ func (c *BasicLibraryController) GetDataInterface(w http.ResponseWriter, r *http
 		render.JSON(w, r, NotFoundResponse("数据接口不存在", err))
 		return
 	}
+	if !visibleOrNotFound(w, r, rbac.ResourceBasicLibrary, interfaceData.LibraryID, "数据接口不存在") {
+		return
+	}
 
+	setETag(w, interfaceData.RowVersion)
 	render.JSON(w, r, SuccessResponse("获取数据接口详情成功", interfaceData))
 }
 
@@ -730,60 +841,56 @@ func (c *BasicLibraryController) GetDataInterface(w http.ResponseWriter, r *http
 // @Produce json
 // @Param page query int false "页码" default(1)
 // @Param size query int false "每页大小" default(10)
+// @Param fields query string false "返回字段，逗号分隔，始终包含id"
+// @Param light query bool false "轻量模式，不返回interface_config,parse_config,table_fields_config及关联对象"
 // @Param library_id query string false "基础库ID过滤"
 // @Param data_source_id query string false "数据源ID过滤"
 // @Param interface_type query string false "接口类型过滤（如：realtime, batch）"
 // @Param status query string false "状态过滤" Enums(active,inactive)
 // @Param name query string false "名称搜索"
-// @Success 200 {object} APIResponse{data=DataInterfaceListResponse} "获取成功"
-// @Failure 400 {object} APIResponse "请求参数错误"
-// @Failure 500 {object} APIResponse "服务器内部错误"
+// @Success 200 {object} APIResponse[DataInterfaceListResponse] "获取成功"
+// @Failure 400 {object} APIResponse[any] "请求参数错误"
+// @Failure 500 {object} APIResponse[any] "服务器内部错误"
 // @Router /basic-libraries/interfaces [get]
 func (c *BasicLibraryController) GetDataInterfaceList(w http.ResponseWriter, r *http.Request) {
 	// 解析查询参数
-	page := 1
-	size := 10
 	libraryID := r.URL.Query().Get("library_id")
 	dataSourceID := r.URL.Query().Get("data_source_id")
 	interfaceType := r.URL.Query().Get("interface_type")
 	status := r.URL.Query().Get("status")
 	name := r.URL.Query().Get("name")
 
-	if p, err := strconv.Atoi(r.URL.Query().Get("page")); err == nil && p > 0 {
-		page = p
-	}
-	if s, err := strconv.Atoi(r.URL.Query().Get("size")); err == nil && s > 0 && s <= 100 {
-		size = s
-	}
+	page, size := parsePagination(r)
 
 	// 调用服务层方法
-	interfaces, total, err := c.service.GetDataInterfaceList(page, size, libraryID, dataSourceID, interfaceType, status, name)
+	interfaces, total, err := c.service.GetDataInterfaceList(r.Context(), page, size, libraryID, dataSourceID, interfaceType, status, name)
 	if err != nil {
-		render.JSON(w, r, InternalErrorResponse("获取数据接口列表失败", err))
+		render.JSON(w, r, MapErrorResponse("获取数据接口列表失败", err))
 		return
 	}
 
 	// 构建响应
 	response := DataInterfaceListResponse{
-		List:  interfaces,
-		Total: total,
-		Page:  page,
-		Size:  size,
+		List:     interfaces,
+		PageMeta: models.NewPageMeta(total, page, size),
 	}
 
-	render.JSON(w, r, SuccessResponse("获取数据接口列表成功", response))
+	sel := parseFieldSelection(r, dataInterfaceHeavyFields...)
+	render.JSON(w, r, SuccessResponse("获取数据接口列表成功", sel.applyToList(response)))
 }
 
 // PreviewInterfaceData 预览接口数据
 // @Summary 预览接口数据
-// @Description 获取接口的样例数据用于预览
+// @Description 获取接口的样例数据用于预览。请求头Accept为text/csv或application/x-ndjson时逐行返回样例数据的CSV（首行为列名，按列名排序）或NDJSON，不包含预览的其他信息
 // @Tags 数据基础库
 // @Produce json
+// @Produce text/csv
+// @Produce application/x-ndjson
 // @Param id path string true "接口ID"
-// @Param limit query int false "数据条数" default(10)
-// @Success 200 {object} APIResponse
-// @Failure 400 {object} APIResponse
-// @Failure 500 {object} APIResponse
+// @Param limit query int false "数据条数，默认和上限由运行时配置preview_default_limit、preview_max_limit决定" default(10)
+// @Success 200 {object} APIResponse[any]
+// @Failure 400 {object} APIResponse[any]
+// @Failure 500 {object} APIResponse[any]
 // @Router /basic-libraries/interface-preview/{id} [get]
 func (c *BasicLibraryController) PreviewInterfaceData(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
@@ -792,7 +899,7 @@ func (c *BasicLibraryController) PreviewInterfaceData(w http.ResponseWriter, r *
 		return
 	}
 
-	limit := 10
+	limit := config.PreviewDefaultLimit()
 	if l := r.URL.Query().Get("limit"); l != "" {
 		if parsed, err := strconv.Atoi(l); err == nil && parsed > 0 {
 			limit = parsed
@@ -801,13 +908,166 @@ func (c *BasicLibraryController) PreviewInterfaceData(w http.ResponseWriter, r *
 
 	data, err := c.service.PreviewInterfaceData(id, limit)
 	if err != nil {
-		render.JSON(w, r, InternalErrorResponse("数据预览失败: "+err.Error(), err))
+		render.JSON(w, r, MapErrorResponse("数据预览失败: "+err.Error(), err))
+		return
+	}
+
+	if format := negotiateRowFormat(r); format != utils.RowFormatJSON {
+		preview, _ := data.(map[string]interface{})
+		rows, _ := preview["preview_data"].([]map[string]interface{})
+		writeRows(w, r, format, rows, -1, -1)
 		return
 	}
 
 	render.JSON(w, r, SuccessResponse("数据预览成功", data))
 }
 
+// SuggestFieldMappingRequest 字段映射建议请求结构
+type SuggestFieldMappingRequest struct {
+	Sample []map[string]interface{} `json:"sample,omitempty"` // 源数据样例，为空时从数据源读取
+}
+
+// SuggestFieldMapping 生成字段映射建议
+// @Summary 生成字段映射建议
+// @Description 对比源数据样例字段与接口表字段（名称相似度含驼峰/下划线转换和中文名拼音，类型兼容性），返回按得分排序的fieldMapping建议，可直接写入parseConfig；未传样例时从数据源读取
+// @Tags 数据基础库
+// @Accept json
+// @Produce json
+// @Param id path string true "接口ID"
+// @Param request body SuggestFieldMappingRequest false "源数据样例"
+// @Success 200 {object} APIResponse[basic_library.FieldMappingSuggestion]
+// @Failure 400 {object} APIResponse[any]
+// @Failure 404 {object} APIResponse[any]
+// @Failure 500 {object} APIResponse[any]
+// @Router /basic-libraries/interfaces/{id}/suggest-mapping [post]
+func (c *BasicLibraryController) SuggestFieldMapping(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		render.JSON(w, r, BadRequestResponse("接口ID参数不能为空", nil))
+		return
+	}
+
+	var req SuggestFieldMappingRequest
+	if r.ContentLength > 0 {
+		if !decodeAndValidate(w, r, &req) {
+			return
+		}
+	}
+
+	suggestion, err := c.service.SuggestFieldMapping(id, req.Sample)
+	if err != nil {
+		render.JSON(w, r, MapErrorResponse("生成字段映射建议失败: "+err.Error(), err))
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("生成字段映射建议成功", suggestion))
+}
+
+// SuggestIncrementalKeysRequest 增量字段识别请求结构
+type SuggestIncrementalKeysRequest struct {
+	Sample []map[string]interface{} `json:"sample,omitempty"` // 源数据样例，为空时从数据源读取
+}
+
+// SuggestIncrementalKeys 识别增量字段
+// @Summary 识别增量字段
+// @Description 分析源数据样例（能否解析为时间或整数、非空率、去重率、有序度）、字段名和接口表字段，返回按置信度排序的增量字段候选，区分更新时间、创建时间、版本号和自增ID；最佳候选置信度足够时给出可写入interface_config的incremental_config。未传样例时从数据源读取
+// @Tags 数据基础库
+// @Accept json
+// @Produce json
+// @Param id path string true "接口ID"
+// @Param request body SuggestIncrementalKeysRequest false "源数据样例"
+// @Success 200 {object} APIResponse[basic_library.IncrementalKeySuggestion]
+// @Failure 400 {object} APIResponse[any]
+// @Failure 404 {object} APIResponse[any]
+// @Failure 500 {object} APIResponse[any]
+// @Router /basic-libraries/interfaces/{id}/suggest-incremental-key [post]
+func (c *BasicLibraryController) SuggestIncrementalKeys(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		render.JSON(w, r, BadRequestResponse("接口ID参数不能为空", nil))
+		return
+	}
+
+	var req SuggestIncrementalKeysRequest
+	if r.ContentLength > 0 {
+		if !decodeAndValidate(w, r, &req) {
+			return
+		}
+	}
+
+	suggestion, err := c.service.SuggestIncrementalKeys(id, req.Sample)
+	if err != nil {
+		render.JSON(w, r, MapErrorResponse("识别增量字段失败: "+err.Error(), err))
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("识别增量字段成功", suggestion))
+}
+
+// GetInterfaceJSONSchema 获取接口数据的JSON Schema
+// @Summary 获取接口数据的JSON Schema
+// @Description 根据接口表字段配置生成JSON Schema（2020-12），包括字段类型、格式、长度和必填字段，可提供给数据提供方校验推送的数据
+// @Tags 数据基础库
+// @Produce json
+// @Param id path string true "接口ID"
+// @Success 200 {object} APIResponse[utils.JSONSchema]
+// @Failure 400 {object} APIResponse[any]
+// @Failure 404 {object} APIResponse[any]
+// @Router /basic-libraries/interfaces/{id}/json-schema [get]
+func (c *BasicLibraryController) GetInterfaceJSONSchema(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		render.JSON(w, r, BadRequestResponse("接口ID参数不能为空", nil))
+		return
+	}
+
+	schema, err := c.service.GetInterfaceJSONSchema(id)
+	if err != nil {
+		render.JSON(w, r, MapErrorResponse("生成JSON Schema失败: "+err.Error(), err))
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("生成JSON Schema成功", schema))
+}
+
+// ValidateInterfacePayloadRequest 接口数据校验请求结构
+type ValidateInterfacePayloadRequest struct {
+	Data []map[string]interface{} `json:"data" validate:"required,min=1"` // 源数据，按接口的字段映射转换后校验
+}
+
+// ValidateInterfacePayload 按接口JSON Schema校验数据
+// @Summary 按接口JSON Schema校验数据
+// @Description 对源数据应用接口的字段映射后按JSON Schema逐行校验，返回违规清单（行号、字段、不满足的关键字、违规值和说明），最多返回100条
+// @Tags 数据基础库
+// @Accept json
+// @Produce json
+// @Param id path string true "接口ID"
+// @Param request body ValidateInterfacePayloadRequest true "待校验的数据"
+// @Success 200 {object} APIResponse[utils.PayloadValidationResult]
+// @Failure 400 {object} APIResponse[any]
+// @Failure 404 {object} APIResponse[any]
+// @Router /basic-libraries/interfaces/{id}/json-schema/validate [post]
+func (c *BasicLibraryController) ValidateInterfacePayload(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		render.JSON(w, r, BadRequestResponse("接口ID参数不能为空", nil))
+		return
+	}
+
+	var req ValidateInterfacePayloadRequest
+	if !decodeAndValidate(w, r, &req) {
+		return
+	}
+
+	result, err := c.service.ValidateInterfacePayload(id, req.Data)
+	if err != nil {
+		render.JSON(w, r, MapErrorResponse("校验数据失败: "+err.Error(), err))
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("校验数据完成", result))
+}
+
 // UpdateInterfaceFields 更新接口字段配置
 // @Summary 更新接口字段配置
 // @Description 更新数据接口的字段配置，并可选择同时更新数据库表结构
@@ -815,14 +1075,13 @@ func (c *BasicLibraryController) PreviewInterfaceData(w http.ResponseWriter, r *
 // @Accept json
 // @Produce json
 // @Param request body UpdateInterfaceFieldsRequest true "更新字段配置请求"
-// @Success 200 {object} APIResponse
-// @Failure 400 {object} APIResponse
-// @Failure 500 {object} APIResponse
+// @Success 200 {object} APIResponse[any]
+// @Failure 400 {object} APIResponse[any]
+// @Failure 500 {object} APIResponse[any]
 // @Router /basic-libraries/update-interface-fields [post]
 func (c *BasicLibraryController) UpdateInterfaceFields(w http.ResponseWriter, r *http.Request) {
 	var req UpdateInterfaceFieldsRequest
-	if err := render.DecodeJSON(r.Body, &req); err != nil {
-		render.JSON(w, r, BadRequestResponse("请求参数格式错误", err))
+	if !decodeAndValidate(w, r, &req) {
 		return
 	}
 
@@ -866,14 +1125,14 @@ func (c *BasicLibraryController) UpdateInterfaceFields(w http.ResponseWriter, r
 			return
 		}
 		if strings.Contains(err.Error(), "创建表结构失败") {
-			render.JSON(w, r, InternalErrorResponse("创建数据库表结构失败", err))
+			render.JSON(w, r, MapErrorResponse("创建数据库表结构失败", err))
 			return
 		}
 		if strings.Contains(err.Error(), "更新表结构失败") {
-			render.JSON(w, r, InternalErrorResponse("更新数据库表结构失败，但字段配置已保存", err))
+			render.JSON(w, r, MapErrorResponse("更新数据库表结构失败，但字段配置已保存", err))
 			return
 		}
-		render.JSON(w, r, InternalErrorResponse("更新接口字段配置失败", err))
+		render.JSON(w, r, MapErrorResponse("更新接口字段配置失败", err))
 		return
 	}
 
@@ -885,8 +1144,8 @@ func (c *BasicLibraryController) UpdateInterfaceFields(w http.ResponseWriter, r
 // @Description 获取数据源管理器的运行统计信息，包括总数、类型分布、在线状态等
 // @Tags 数据基础库
 // @Produce json
-// @Success 200 {object} APIResponse
-// @Failure 500 {object} APIResponse
+// @Success 200 {object} APIResponse[any]
+// @Failure 500 {object} APIResponse[any]
 // @Router /basic-libraries/datasource-manager-stats [get]
 func (c *BasicLibraryController) GetDataSourceManagerStats(w http.ResponseWriter, r *http.Request) {
 	datasourceInitService := c.service.GetDatasourceInitService()
@@ -900,8 +1159,8 @@ func (c *BasicLibraryController) GetDataSourceManagerStats(w http.ResponseWriter
 // @Description 获取所有常驻数据源的运行状态和统计信息
 // @Tags 数据基础库
 // @Produce json
-// @Success 200 {object} APIResponse
-// @Failure 500 {object} APIResponse
+// @Success 200 {object} APIResponse[any]
+// @Failure 500 {object} APIResponse[any]
 // @Router /basic-libraries/resident-datasources [get]
 func (c *BasicLibraryController) GetResidentDataSources(w http.ResponseWriter, r *http.Request) {
 	datasourceInitService := c.service.GetDatasourceInitService()
@@ -916,9 +1175,9 @@ func (c *BasicLibraryController) GetResidentDataSources(w http.ResponseWriter, r
 // @Tags 数据基础库
 // @Produce json
 // @Param id path string true "数据源ID"
-// @Success 200 {object} APIResponse
-// @Failure 400 {object} APIResponse
-// @Failure 500 {object} APIResponse
+// @Success 200 {object} APIResponse[any]
+// @Failure 400 {object} APIResponse[any]
+// @Failure 500 {object} APIResponse[any]
 // @Router /basic-libraries/restart-resident-datasource/{id} [post]
 func (c *BasicLibraryController) RestartResidentDataSource(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
@@ -932,7 +1191,7 @@ func (c *BasicLibraryController) RestartResidentDataSource(w http.ResponseWriter
 
 	err := datasourceInitService.RestartResidentDataSource(ctx, id)
 	if err != nil {
-		render.JSON(w, r, InternalErrorResponse("重启常驻数据源失败: "+err.Error(), err))
+		render.JSON(w, r, MapErrorResponse("重启常驻数据源失败: "+err.Error(), err))
 		return
 	}
 
@@ -945,9 +1204,9 @@ func (c *BasicLibraryController) RestartResidentDataSource(w http.ResponseWriter
 // @Tags 数据基础库
 // @Produce json
 // @Param id path string true "数据源ID"
-// @Success 200 {object} APIResponse
-// @Failure 400 {object} APIResponse
-// @Failure 500 {object} APIResponse
+// @Success 200 {object} APIResponse[any]
+// @Failure 400 {object} APIResponse[any]
+// @Failure 500 {object} APIResponse[any]
 // @Router /basic-libraries/reload-datasource/{id} [post]
 func (c *BasicLibraryController) ReloadDataSource(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
@@ -961,7 +1220,7 @@ func (c *BasicLibraryController) ReloadDataSource(w http.ResponseWriter, r *http
 
 	err := datasourceInitService.ReloadDataSource(ctx, id)
 	if err != nil {
-		render.JSON(w, r, InternalErrorResponse("重新加载数据源失败: "+err.Error(), err))
+		render.JSON(w, r, MapErrorResponse("重新加载数据源失败: "+err.Error(), err))
 		return
 	}
 
@@ -973,8 +1232,8 @@ func (c *BasicLibraryController) ReloadDataSource(w http.ResponseWriter, r *http
 // @Description 对管理器中的所有数据源进行健康检查
 // @Tags 数据基础库
 // @Produce json
-// @Success 200 {object} APIResponse
-// @Failure 500 {object} APIResponse
+// @Success 200 {object} APIResponse[any]
+// @Failure 500 {object} APIResponse[any]
 // @Router /basic-libraries/health-check-all [post]
 func (c *BasicLibraryController) HealthCheckAllDataSources(w http.ResponseWriter, r *http.Request) {
 	datasourceInitService := c.service.GetDatasourceInitService()
@@ -992,14 +1251,13 @@ func (c *BasicLibraryController) HealthCheckAllDataSources(w http.ResponseWriter
 // @Accept json
 // @Produce json
 // @Param request body ImportCSVRequest true "CSV导入请求"
-// @Success 200 {object} APIResponse{data=ImportCSVResponse}
-// @Failure 400 {object} APIResponse
-// @Failure 500 {object} APIResponse
+// @Success 200 {object} APIResponse[ImportCSVResponse]
+// @Failure 400 {object} APIResponse[any]
+// @Failure 500 {object} APIResponse[any]
 // @Router /basic-libraries/import-csv [post]
 func (c *BasicLibraryController) ImportCSV(w http.ResponseWriter, r *http.Request) {
 	var req ImportCSVRequest
-	if err := render.DecodeJSON(r.Body, &req); err != nil {
-		render.JSON(w, r, BadRequestResponse("请求参数格式错误", err))
+	if !decodeAndValidate(w, r, &req) {
 		return
 	}
 
@@ -1016,7 +1274,7 @@ func (c *BasicLibraryController) ImportCSV(w http.ResponseWriter, r *http.Reques
 	// 调用服务层方法导入CSV数据
 	result, err := c.service.GetInterfaceService().ImportCSVData(req.InterfaceID, req.CSVContent)
 	if err != nil {
-		render.JSON(w, r, InternalErrorResponse("CSV导入失败", err))
+		render.JSON(w, r, MapErrorResponse("CSV导入失败", err))
 		return
 	}
 
@@ -1034,9 +1292,9 @@ type TableColumnsResponse struct {
 // @Tags 数据基础库
 // @Produce json
 // @Param id path string true "接口ID" example:"550e8400-e29b-41d4-a716-446655440000"
-// @Success 200 {object} APIResponse{data=[]database.ColumnDefinition} "返回表字段列表"
-// @Failure 400 {object} APIResponse "请求参数错误"
-// @Failure 500 {object} APIResponse "服务器内部错误"
+// @Success 200 {object} APIResponse[[]database.ColumnDefinition] "返回表字段列表"
+// @Failure 400 {object} APIResponse[any] "请求参数错误"
+// @Failure 500 {object} APIResponse[any] "服务器内部错误"
 // @Router /basic-libraries/interfaces/{id}/table-columns [get]
 func (c *BasicLibraryController) GetInterfaceTableColumns(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
@@ -1048,7 +1306,7 @@ func (c *BasicLibraryController) GetInterfaceTableColumns(w http.ResponseWriter,
 	// 获取接口信息
 	interfaceData, err := c.service.GetDataInterface(id)
 	if err != nil {
-		render.JSON(w, r, InternalErrorResponse("获取接口信息失败", err))
+		render.JSON(w, r, MapErrorResponse("获取接口信息失败", err))
 		return
 	}
 
@@ -1060,9 +1318,9 @@ func (c *BasicLibraryController) GetInterfaceTableColumns(w http.ResponseWriter,
 
 	// 从数据库获取表字段信息
 	schemaService := c.service.GetInterfaceService().GetSchemaService()
-	columns, err := schemaService.GetTableColumns(interfaceData.BasicLibrary.NameEn, interfaceData.NameEn)
+	columns, err := schemaService.GetTableColumns(interfaceData.BasicLibrary.GetSchemaName(), interfaceData.NameEn)
 	if err != nil {
-		render.JSON(w, r, InternalErrorResponse("获取表字段信息失败", err))
+		render.JSON(w, r, MapErrorResponse("获取表字段信息失败", err))
 		return
 	}
 
@@ -1080,9 +1338,9 @@ type TableIndexesResponse struct {
 // @Tags 数据基础库
 // @Produce json
 // @Param id path string true "接口ID" example:"550e8400-e29b-41d4-a716-446655440000"
-// @Success 200 {object} APIResponse{data=[]database.IndexDefinition} "返回索引列表"
-// @Failure 400 {object} APIResponse "请求参数错误"
-// @Failure 500 {object} APIResponse "服务器内部错误"
+// @Success 200 {object} APIResponse[[]database.IndexDefinition] "返回索引列表"
+// @Failure 400 {object} APIResponse[any] "请求参数错误"
+// @Failure 500 {object} APIResponse[any] "服务器内部错误"
 // @Router /basic-libraries/interfaces/{id}/table-indexes [get]
 func (c *BasicLibraryController) GetInterfaceTableIndexes(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
@@ -1094,7 +1352,7 @@ func (c *BasicLibraryController) GetInterfaceTableIndexes(w http.ResponseWriter,
 	// 获取接口信息
 	interfaceData, err := c.service.GetDataInterface(id)
 	if err != nil {
-		render.JSON(w, r, InternalErrorResponse("获取接口信息失败", err))
+		render.JSON(w, r, MapErrorResponse("获取接口信息失败", err))
 		return
 	}
 
@@ -1106,9 +1364,9 @@ func (c *BasicLibraryController) GetInterfaceTableIndexes(w http.ResponseWriter,
 
 	// 从数据库获取索引信息
 	schemaService := c.service.GetInterfaceService().GetSchemaService()
-	indexes, err := schemaService.GetTableIndexes(interfaceData.BasicLibrary.NameEn, interfaceData.NameEn)
+	indexes, err := schemaService.GetTableIndexes(interfaceData.BasicLibrary.GetSchemaName(), interfaceData.NameEn)
 	if err != nil {
-		render.JSON(w, r, InternalErrorResponse("获取索引信息失败", err))
+		render.JSON(w, r, MapErrorResponse("获取索引信息失败", err))
 		return
 	}
 
@@ -1131,14 +1389,13 @@ type CreateInterfaceTableIndexRequest struct {
 // @Accept json
 // @Produce json
 // @Param request body CreateInterfaceTableIndexRequest true "创建索引请求"
-// @Success 200 {object} APIResponse{data=nil} "创建成功"
-// @Failure 400 {object} APIResponse "请求参数错误或表未创建"
-// @Failure 500 {object} APIResponse "服务器内部错误"
+// @Success 200 {object} APIResponse[any] "创建成功"
+// @Failure 400 {object} APIResponse[any] "请求参数错误或表未创建"
+// @Failure 500 {object} APIResponse[any] "服务器内部错误"
 // @Router /basic-libraries/interfaces/create-table-index [post]
 func (c *BasicLibraryController) CreateInterfaceTableIndex(w http.ResponseWriter, r *http.Request) {
 	var req CreateInterfaceTableIndexRequest
-	if err := render.DecodeJSON(r.Body, &req); err != nil {
-		render.JSON(w, r, BadRequestResponse("请求参数格式错误", err))
+	if !decodeAndValidate(w, r, &req) {
 		return
 	}
 
@@ -1160,7 +1417,7 @@ func (c *BasicLibraryController) CreateInterfaceTableIndex(w http.ResponseWriter
 	// 获取接口信息
 	interfaceData, err := c.service.GetDataInterface(req.InterfaceID)
 	if err != nil {
-		render.JSON(w, r, InternalErrorResponse("获取接口信息失败", err))
+		render.JSON(w, r, MapErrorResponse("获取接口信息失败", err))
 		return
 	}
 
@@ -1173,7 +1430,7 @@ func (c *BasicLibraryController) CreateInterfaceTableIndex(w http.ResponseWriter
 	// 创建索引
 	schemaService := c.service.GetInterfaceService().GetSchemaService()
 	err = schemaService.CreateIndex(
-		interfaceData.BasicLibrary.NameEn,
+		interfaceData.BasicLibrary.GetSchemaName(),
 		interfaceData.NameEn,
 		req.IndexName,
 		req.Columns,
@@ -1181,7 +1438,7 @@ func (c *BasicLibraryController) CreateInterfaceTableIndex(w http.ResponseWriter
 		req.IndexType,
 	)
 	if err != nil {
-		render.JSON(w, r, InternalErrorResponse("创建索引失败", err))
+		render.JSON(w, r, MapErrorResponse("创建索引失败", err))
 		return
 	}
 
@@ -1201,14 +1458,13 @@ type DropInterfaceTableIndexRequest struct {
 // @Accept json
 // @Produce json
 // @Param request body DropInterfaceTableIndexRequest true "删除索引请求"
-// @Success 200 {object} APIResponse{data=nil} "删除成功"
-// @Failure 400 {object} APIResponse "请求参数错误"
-// @Failure 500 {object} APIResponse "服务器内部错误"
+// @Success 200 {object} APIResponse[any] "删除成功"
+// @Failure 400 {object} APIResponse[any] "请求参数错误"
+// @Failure 500 {object} APIResponse[any] "服务器内部错误"
 // @Router /basic-libraries/interfaces/drop-table-index [post]
 func (c *BasicLibraryController) DropInterfaceTableIndex(w http.ResponseWriter, r *http.Request) {
 	var req DropInterfaceTableIndexRequest
-	if err := render.DecodeJSON(r.Body, &req); err != nil {
-		render.JSON(w, r, BadRequestResponse("请求参数格式错误", err))
+	if !decodeAndValidate(w, r, &req) {
 		return
 	}
 
@@ -1225,15 +1481,15 @@ func (c *BasicLibraryController) DropInterfaceTableIndex(w http.ResponseWriter,
 	// 获取接口信息
 	interfaceData, err := c.service.GetDataInterface(req.InterfaceID)
 	if err != nil {
-		render.JSON(w, r, InternalErrorResponse("获取接口信息失败", err))
+		render.JSON(w, r, MapErrorResponse("获取接口信息失败", err))
 		return
 	}
 
 	// 删除索引
 	schemaService := c.service.GetInterfaceService().GetSchemaService()
-	err = schemaService.DropIndex(interfaceData.BasicLibrary.NameEn, req.IndexName)
+	err = schemaService.DropIndex(interfaceData.BasicLibrary.GetSchemaName(), req.IndexName)
 	if err != nil {
-		render.JSON(w, r, InternalErrorResponse("删除索引失败", err))
+		render.JSON(w, r, MapErrorResponse("删除索引失败", err))
 		return
 	}
 
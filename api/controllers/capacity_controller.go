@@ -0,0 +1,107 @@
+/*
+ * @module api/controllers/capacity_controller
+ * @description 存储容量控制器，提供按库、租户的存储容量和增长报表、库的接口明细和趋势，以及手动采集存储快照的接口
+ * @architecture 分层架构 - 控制器层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow HTTP请求 -> 控制器 -> 存储容量统计服务 -> 数据库
+ * @rules 统一的错误处理和响应格式；统计天数默认30天、最多365天；报表按请求租户过滤
+ * @dependencies datahub-service/service, datahub-service/service/capacity, github.com/go-chi/chi/v5
+ * @refs service/capacity/report.go, service/capacity/service.go
+ */
+
+package controllers
+
+import (
+	"datahub-service/service"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+)
+
+// CapacityController 存储容量控制器
+type CapacityController struct {
+}
+
+// NewCapacityController 创建存储容量控制器实例
+func NewCapacityController() *CapacityController {
+	return &CapacityController{}
+}
+
+// GetLibraryCapacityReports 获取各库的存储容量
+// @Summary 获取各库的存储容量
+// @Description 按库汇总接口表的行数和占用空间，以及统计期内的增长量和日均增长，按占用空间倒序
+// @Tags 存储容量
+// @Produce json
+// @Param days query int false "统计天数，默认30，最多365" default(30)
+// @Param library_type query string false "库类型，为空表示全部" Enums(basic_library, thematic_library)
+// @Success 200 {object} APIResponse[[]capacity.LibraryCapacity] "获取成功"
+// @Router /capacity/libraries [get]
+func (c *CapacityController) GetLibraryCapacityReports(w http.ResponseWriter, r *http.Request) {
+	days, _ := strconv.Atoi(r.URL.Query().Get("days"))
+	reports, err := service.GlobalCapacityService.GetLibraryReports(r.Context(), days, r.URL.Query().Get("library_type"))
+	if err != nil {
+		render.JSON(w, r, MapErrorResponse("获取库存储容量失败", err))
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("获取库存储容量成功", reports))
+}
+
+// GetLibraryCapacityDetail 获取库的存储容量明细
+// @Summary 获取库的存储容量明细
+// @Description 获取库中各接口表的行数、占用空间和增长，以及库的每日容量趋势
+// @Tags 存储容量
+// @Produce json
+// @Param id path string true "基础库或主题库ID"
+// @Param days query int false "统计天数，默认30，最多365" default(30)
+// @Success 200 {object} APIResponse[capacity.LibraryCapacityDetail] "获取成功"
+// @Failure 404 {object} APIResponse[any] "统计期内没有该库的存储快照"
+// @Router /capacity/libraries/{id} [get]
+func (c *CapacityController) GetLibraryCapacityDetail(w http.ResponseWriter, r *http.Request) {
+	days, _ := strconv.Atoi(r.URL.Query().Get("days"))
+	detail, err := service.GlobalCapacityService.GetLibraryDetail(r.Context(), chi.URLParam(r, "id"), days)
+	if err != nil {
+		render.JSON(w, r, MapErrorResponse("获取库存储容量明细失败", err))
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("获取库存储容量明细成功", detail))
+}
+
+// GetTenantCapacityReports 获取各租户的存储容量
+// @Summary 获取各租户的存储容量
+// @Description 按租户汇总库的行数和占用空间，以及统计期内的增长量和日均增长，按占用空间倒序
+// @Tags 存储容量
+// @Produce json
+// @Param days query int false "统计天数，默认30，最多365" default(30)
+// @Success 200 {object} APIResponse[[]capacity.TenantCapacity] "获取成功"
+// @Router /capacity/tenants [get]
+func (c *CapacityController) GetTenantCapacityReports(w http.ResponseWriter, r *http.Request) {
+	days, _ := strconv.Atoi(r.URL.Query().Get("days"))
+	reports, err := service.GlobalCapacityService.GetTenantReports(r.Context(), days)
+	if err != nil {
+		render.JSON(w, r, MapErrorResponse("获取租户存储容量失败", err))
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("获取租户存储容量成功", reports))
+}
+
+// CollectStorageSnapshots 采集存储快照
+// @Summary 采集存储快照
+// @Description 立即采集已建表接口的行数和占用空间快照，请求携带租户时只采集该租户的接口
+// @Tags 存储容量
+// @Produce json
+// @Success 200 {object} APIResponse[capacity.CollectResult] "采集完成"
+// @Router /capacity/snapshots [post]
+func (c *CapacityController) CollectStorageSnapshots(w http.ResponseWriter, r *http.Request) {
+	result, err := service.GlobalCapacityService.Collect(r.Context())
+	if err != nil {
+		render.JSON(w, r, MapErrorResponse("采集存储快照失败", err))
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("存储快照采集完成", result))
+}
@@ -0,0 +1,60 @@
+/*
+ * @module api/controllers/catalog_cache_controller
+ * @description 目录数据缓存管理接口，查看缓存命中统计，必要时手动清空缓存
+ * @architecture 分层架构 - 控制器层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow HTTP请求 -> 控制器 -> 目录数据缓存
+ * @rules 缓存未启用时统计返回enabled=false；清空只影响当前实例
+ * @dependencies datahub-service/service/catalog_cache
+ * @refs service/catalog_cache/cache.go
+ */
+
+package controllers
+
+import (
+	"datahub-service/service/catalog_cache"
+	"net/http"
+
+	"github.com/go-chi/render"
+)
+
+// CatalogCacheController 目录数据缓存控制器
+type CatalogCacheController struct {
+}
+
+// NewCatalogCacheController 创建目录数据缓存控制器实例
+func NewCatalogCacheController() *CatalogCacheController {
+	return &CatalogCacheController{}
+}
+
+// GetCatalogCacheStats 获取目录数据缓存统计
+// @Summary 获取目录数据缓存统计
+// @Description 获取当前实例目录数据缓存的TTL、缓存项数和命中统计
+// @Tags 系统配置
+// @Produce json
+// @Success 200 {object} APIResponse[catalog_cache.Stats] "获取成功"
+// @Router /admin/catalog-cache [get]
+func (c *CatalogCacheController) GetCatalogCacheStats(w http.ResponseWriter, r *http.Request) {
+	stats := catalog_cache.Stats{}
+	if cache := catalog_cache.Default(); cache != nil {
+		stats = cache.Stats()
+	}
+
+	render.JSON(w, r, SuccessResponse("获取目录数据缓存统计成功", stats))
+}
+
+// FlushCatalogCache 清空目录数据缓存
+// @Summary 清空目录数据缓存
+// @Description 清空当前实例的目录数据缓存，用于原生SQL修改目录数据后立即生效
+// @Tags 系统配置
+// @Produce json
+// @Success 200 {object} APIResponse[map[string]int] "清空成功"
+// @Router /admin/catalog-cache [delete]
+func (c *CatalogCacheController) FlushCatalogCache(w http.ResponseWriter, r *http.Request) {
+	flushed := 0
+	if cache := catalog_cache.Default(); cache != nil {
+		flushed = cache.Flush()
+	}
+
+	render.JSON(w, r, SuccessResponse("清空目录数据缓存成功", map[string]int{"flushed": flushed}))
+}
@@ -0,0 +1,110 @@
+/*
+ * @module api/controllers/catalog_import_controller
+ * @description 系统台账导入控制器，上传Excel系统台账批量创建草稿基础库、数据源和元数据，并提供台账模板下载
+ * @architecture 分层架构 - 控制器层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow HTTP请求 -> 控制器 -> 系统台账导入服务 -> 基础库服务/数据库
+ * @rules 统一的错误处理和响应格式；台账文件以multipart/form-data的file字段上传，大小不超过maxCatalogImportSize；dry_run=true时只校验不创建
+ * @dependencies datahub-service/service, datahub-service/service/basic_library, github.com/go-chi/render
+ * @refs service/basic_library/catalog_import_service.go
+ */
+
+package controllers
+
+import (
+	"datahub-service/service"
+	"datahub-service/service/basic_library"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/render"
+)
+
+// maxCatalogImportSize 台账文件大小上限
+const maxCatalogImportSize = 20 << 20
+
+// CatalogImportController 系统台账导入控制器
+type CatalogImportController struct {
+}
+
+// NewCatalogImportController 创建系统台账导入控制器实例
+func NewCatalogImportController() *CatalogImportController {
+	return &CatalogImportController{}
+}
+
+// ImportCatalog 导入系统台账
+// @Summary 导入系统台账
+// @Description 上传Excel(xlsx)系统台账，"系统"工作表每行创建一个草稿基础库和草稿数据源，"联系人"和"数据项"工作表写入关联到基础库的元数据。校验失败的行跳过并在issues中返回，dry_run=true时只校验不创建
+// @Tags 数据基础库
+// @Accept multipart/form-data
+// @Produce json
+// @Param file formData file true "xlsx系统台账"
+// @Param dry_run formData bool false "只校验不创建" default(false)
+// @Success 200 {object} APIResponse[basic_library.CatalogImportResult] "导入完成"
+// @Failure 400 {object} APIResponse[any] "文件不是有效的xlsx或缺少必需的工作表和列"
+// @Router /admin/catalog/import [post]
+func (c *CatalogImportController) ImportCatalog(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxCatalogImportSize)
+	if err := r.ParseMultipartForm(maxCatalogImportSize); err != nil {
+		render.JSON(w, r, BadRequestResponse("解析上传文件失败", err))
+		return
+	}
+	dryRun := false
+	if value := r.FormValue("dry_run"); value != "" {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			render.JSON(w, r, BadRequestResponse("dry_run参数无效", err))
+			return
+		}
+		dryRun = parsed
+	}
+
+	file, _, err := r.FormFile("file")
+	if err != nil {
+		render.JSON(w, r, BadRequestResponse("缺少台账文件", err))
+		return
+	}
+	defer file.Close()
+	content, err := io.ReadAll(file)
+	if err != nil {
+		render.JSON(w, r, BadRequestResponse("读取台账文件失败", err))
+		return
+	}
+
+	result, err := service.GlobalCatalogImportService.Import(r.Context(), content, dryRun, getCurrentUsername(r))
+	if err != nil {
+		if errors.Is(err, basic_library.ErrInvalidCatalogImport) {
+			render.JSON(w, r, BadRequestResponse("导入系统台账失败", err))
+			return
+		}
+		render.JSON(w, r, MapErrorResponse("导入系统台账失败", err))
+		return
+	}
+
+	msg := "导入系统台账完成"
+	if dryRun {
+		msg = "系统台账校验完成"
+	}
+	render.JSON(w, r, SuccessResponse(msg, result))
+}
+
+// GetCatalogImportTemplate 下载系统台账模板
+// @Summary 下载系统台账模板
+// @Description 下载包含系统、联系人、数据项三个工作表的xlsx模板，每个工作表有表头和一行示例
+// @Tags 数据基础库
+// @Produce application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Success 200 {file} file "xlsx模板"
+// @Router /admin/catalog/import/template [get]
+func (c *CatalogImportController) GetCatalogImportTemplate(w http.ResponseWriter, r *http.Request) {
+	content, err := service.GlobalCatalogImportService.Template()
+	if err != nil {
+		render.JSON(w, r, InternalErrorResponse("生成系统台账模板失败", err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet")
+	w.Header().Set("Content-Disposition", `attachment; filename="catalog_import_template.xlsx"`)
+	w.Write(content)
+}
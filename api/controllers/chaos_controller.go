@@ -0,0 +1,167 @@
+/*
+ * @module api/controllers/chaos_controller
+ * @description 故障注入管理接口，用于在非生产环境创建、查看和停止演练故障
+ * @architecture 分层架构 - 控制器层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow HTTP请求 -> 控制器 -> 故障注入服务 -> 数据库/系统日志
+ * @rules 未启用故障注入的环境返回403；审计记录通过系统日志接口按object_type=fault_injection查询
+ * @dependencies datahub-service/service/chaos, github.com/go-chi/chi/v5
+ * @refs service/chaos/chaos_service.go
+ */
+
+package controllers
+
+import (
+	"datahub-service/service"
+	"datahub-service/service/chaos"
+	"datahub-service/service/models"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"gorm.io/gorm"
+)
+
+// ChaosController 故障注入控制器
+type ChaosController struct {
+	chaosService *chaos.Service
+}
+
+// NewChaosController 创建故障注入控制器实例
+func NewChaosController() *ChaosController {
+	return &ChaosController{
+		chaosService: service.GlobalChaosService,
+	}
+}
+
+// CreateFaultInjectionRequest 创建故障注入请求结构
+type CreateFaultInjectionRequest struct {
+	FaultType       string  `json:"fault_type" validate:"required" example:"datasource_timeout"` // datasource_timeout/db_write_failure/lock_contention/schedule_delay
+	Target          string  `json:"target" example:"550e8400-e29b-41d4-a716-446655440000"`       // 数据源ID、表名、锁键或任务ID，为空表示全部
+	Probability     float64 `json:"probability" example:"0.5"`                                   // 命中概率，默认1
+	DelayMs         int     `json:"delay_ms" example:"30000"`                                    // 注入延迟（毫秒）
+	ErrorMessage    string  `json:"error_message"`                                               // 自定义错误信息
+	DurationMinutes int     `json:"duration_minutes" validate:"required" example:"30"`           // 生效时长（分钟），最长24小时
+	Description     string  `json:"description"`
+}
+
+// GetChaosStatus 获取故障注入状态
+// @Summary 获取故障注入状态
+// @Description 获取当前环境是否启用故障注入、运行环境、生效中的故障数量和支持的故障类型
+// @Tags 故障演练
+// @Produce json
+// @Success 200 {object} APIResponse[chaos.StatusInfo] "获取成功"
+// @Router /chaos/status [get]
+func (c *ChaosController) GetChaosStatus(w http.ResponseWriter, r *http.Request) {
+	render.JSON(w, r, SuccessResponse("获取故障注入状态成功", c.chaosService.Status()))
+}
+
+// CreateFaultInjection 创建故障注入
+// @Summary 创建故障注入
+// @Description 创建演练故障：数据源超时、写库失败、锁争用或调度延迟，到期自动失效；仅非生产环境可用，操作写入系统日志
+// @Tags 故障演练
+// @Accept json
+// @Produce json
+// @Param fault body CreateFaultInjectionRequest true "故障配置"
+// @Success 200 {object} APIResponse[models.FaultInjection] "创建成功"
+// @Failure 400 {object} APIResponse[any] "请求参数错误"
+// @Failure 403 {object} APIResponse[any] "当前环境未启用故障注入"
+// @Router /chaos/faults [post]
+func (c *ChaosController) CreateFaultInjection(w http.ResponseWriter, r *http.Request) {
+	var req CreateFaultInjectionRequest
+	if !decodeAndValidate(w, r, &req) {
+		return
+	}
+	if req.DurationMinutes <= 0 {
+		render.JSON(w, r, BadRequestResponse("生效时长必须大于0", nil))
+		return
+	}
+
+	fault := &models.FaultInjection{
+		FaultType:    req.FaultType,
+		Target:       req.Target,
+		Probability:  req.Probability,
+		DelayMs:      req.DelayMs,
+		ErrorMessage: req.ErrorMessage,
+		Description:  req.Description,
+		ExpiresAt:    time.Now().Add(time.Duration(req.DurationMinutes) * time.Minute),
+	}
+	if err := c.chaosService.CreateFault(fault, getCurrentUsername(r), getClientIP(r)); err != nil {
+		respondChaosError(w, r, "创建故障注入失败", err)
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("创建故障注入成功", fault))
+}
+
+// GetFaultInjections 获取故障注入列表
+// @Summary 获取故障注入列表
+// @Description 获取故障注入记录，按创建时间倒序，包含命中次数
+// @Tags 故障演练
+// @Produce json
+// @Param status query string false "状态过滤" Enums(active, stopped, expired)
+// @Success 200 {object} APIResponse[[]models.FaultInjection] "获取成功"
+// @Failure 500 {object} APIResponse[any] "服务器内部错误"
+// @Router /chaos/faults [get]
+func (c *ChaosController) GetFaultInjections(w http.ResponseWriter, r *http.Request) {
+	faults, err := c.chaosService.ListFaults(r.URL.Query().Get("status"))
+	if err != nil {
+		render.JSON(w, r, MapErrorResponse("获取故障注入列表失败", err))
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("获取故障注入列表成功", faults))
+}
+
+// StopFaultInjection 停止故障注入
+// @Summary 停止故障注入
+// @Description 立即停止指定的故障注入，操作写入系统日志
+// @Tags 故障演练
+// @Produce json
+// @Param id path string true "故障ID"
+// @Success 200 {object} APIResponse[any] "停止成功"
+// @Failure 404 {object} APIResponse[any] "故障不存在"
+// @Failure 409 {object} APIResponse[any] "故障已停止或已到期"
+// @Router /chaos/faults/{id}/stop [post]
+func (c *ChaosController) StopFaultInjection(w http.ResponseWriter, r *http.Request) {
+	if err := c.chaosService.StopFault(chi.URLParam(r, "id"), getCurrentUsername(r), getClientIP(r)); err != nil {
+		respondChaosError(w, r, "停止故障注入失败", err)
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("停止故障注入成功", nil))
+}
+
+// StopAllFaultInjections 停止全部故障注入
+// @Summary 停止全部故障注入
+// @Description 立即停止所有生效中的故障注入，用于中止演练
+// @Tags 故障演练
+// @Produce json
+// @Success 200 {object} APIResponse[any] "停止成功，data为停止的数量"
+// @Failure 500 {object} APIResponse[any] "服务器内部错误"
+// @Router /chaos/faults/stop-all [post]
+func (c *ChaosController) StopAllFaultInjections(w http.ResponseWriter, r *http.Request) {
+	count, err := c.chaosService.StopAllFaults(getCurrentUsername(r), getClientIP(r))
+	if err != nil {
+		render.JSON(w, r, MapErrorResponse("停止全部故障注入失败", err))
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("停止全部故障注入成功", map[string]int{"stopped_count": count}))
+}
+
+// respondChaosError 按错误类型返回故障注入操作的错误响应
+func respondChaosError(w http.ResponseWriter, r *http.Request, msg string, err error) {
+	switch {
+	case errors.Is(err, chaos.ErrChaosDisabled):
+		render.JSON(w, r, ErrorResponse(StatusForbidden, err.Error(), err))
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		render.JSON(w, r, NotFoundResponse(msg+": 故障不存在", err))
+	case errors.Is(err, chaos.ErrFaultNotActive):
+		render.JSON(w, r, ConflictResponse(msg+": "+err.Error(), err))
+	default:
+		render.JSON(w, r, BadRequestResponse(msg+": "+err.Error(), err))
+	}
+}
@@ -0,0 +1,94 @@
+/*
+ * @module api/controllers/compliance_controller
+ * @description 合规证据包控制器，生成个人信息保护法、等保2.0审计用的证据包，支持JSON和xlsx两种格式
+ * @architecture 分层架构 - 控制器层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow HTTP请求 -> 控制器 -> 合规证据包服务 -> JSON响应或xlsx文件
+ * @rules 统一的错误处理和响应格式；时段参数为RFC3339格式，缺省为最近30天；生成人为当前用户
+ * @dependencies datahub-service/service, datahub-service/service/compliance, github.com/go-chi/render
+ * @refs service/compliance/compliance_service.go, service/compliance/export.go
+ */
+
+package controllers
+
+import (
+	"datahub-service/service"
+	"datahub-service/service/compliance"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/render"
+)
+
+// ComplianceController 合规证据包控制器
+type ComplianceController struct {
+}
+
+// NewComplianceController 创建合规证据包控制器实例
+func NewComplianceController() *ComplianceController {
+	return &ComplianceController{}
+}
+
+// GetComplianceReport 生成合规证据包
+// @Summary 生成合规证据包
+// @Description 汇总敏感数据清单、脱敏和加密覆盖率、访问审计、备份状态和保留期执行情况，给出发现的问题和个人信息保护法、等保2.0控制项结论。访问审计和备份记录按start_time、end_time时段统计，缺省为最近30天；format=xlsx时下载多工作表的xlsx文件
+// @Tags 合规审计
+// @Produce json
+// @Produce application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Param start_time query string false "时段开始（RFC3339格式）"
+// @Param end_time query string false "时段结束（RFC3339格式），默认为当前时间"
+// @Param format query string false "输出格式" Enums(json, xlsx) default(json)
+// @Success 200 {object} APIResponse[compliance.Report] "生成成功；format=xlsx时返回xlsx文件"
+// @Failure 400 {object} APIResponse[any] "请求参数错误"
+// @Failure 500 {object} APIResponse[any] "服务器内部错误"
+// @Router /compliance/report [get]
+func (c *ComplianceController) GetComplianceReport(w http.ResponseWriter, r *http.Request) {
+	format := r.URL.Query().Get("format")
+	if format != "" && format != "json" && format != "xlsx" {
+		render.JSON(w, r, BadRequestResponse("format只支持json或xlsx", nil))
+		return
+	}
+
+	query := compliance.ReportQuery{Operator: getCurrentUsername(r)}
+	if value := r.URL.Query().Get("start_time"); value != "" {
+		t, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			render.JSON(w, r, BadRequestResponse("start_time格式错误，应为RFC3339格式", err))
+			return
+		}
+		query.Start = &t
+	}
+	if value := r.URL.Query().Get("end_time"); value != "" {
+		t, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			render.JSON(w, r, BadRequestResponse("end_time格式错误，应为RFC3339格式", err))
+			return
+		}
+		query.End = &t
+	}
+
+	report, err := service.GlobalComplianceService.Generate(r.Context(), query)
+	if err != nil {
+		if errors.Is(err, compliance.ErrInvalidPeriod) {
+			render.JSON(w, r, BadRequestResponse(err.Error(), err))
+			return
+		}
+		render.JSON(w, r, InternalErrorResponse("生成合规证据包失败", err))
+		return
+	}
+
+	if format != "xlsx" {
+		render.JSON(w, r, SuccessResponse("生成合规证据包成功", report))
+		return
+	}
+
+	content, err := compliance.RenderXLSX(report)
+	if err != nil {
+		render.JSON(w, r, InternalErrorResponse("导出合规证据包失败", err))
+		return
+	}
+	w.Header().Set("Content-Type", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet")
+	w.Header().Set("Content-Disposition", `attachment; filename="`+compliance.FileName(report)+`"`)
+	w.Write(content)
+}
@@ -0,0 +1,48 @@
+/*
+ * @module api/controllers/config_lint_controller
+ * @description 接口配置检查控制器，在首次同步前检查接口配置、字段映射、表字段和增量配置是否一致
+ * @architecture 分层架构 - 控制器层
+ * @documentReference ai_docs/interfaces.md
+ * @stateFlow HTTP请求 -> 控制器 -> 接口配置检查服务 -> 返回问题列表
+ * @rules 统一的错误处理和响应格式；检查发现问题时仍返回200，由valid和findings说明
+ * @dependencies datahub-service/service, github.com/go-chi/chi/v5
+ * @refs service/basic_library/config_lint_service.go
+ */
+
+package controllers
+
+import (
+	"datahub-service/service"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+)
+
+// ConfigLintController 接口配置检查控制器
+type ConfigLintController struct {
+}
+
+// NewConfigLintController 创建接口配置检查控制器实例
+func NewConfigLintController() *ConfigLintController {
+	return &ConfigLintController{}
+}
+
+// ValidateInterfaceConfig 检查接口配置
+// @Summary 检查接口配置
+// @Description 静态检查接口的请求配置、解析配置和表字段配置是否一致：启用分页时分页参数是否完整、字段映射目标是否为接口表字段、增量字段是否存在于接口表且类型可比较等。只读检查，不访问源系统。问题分为error（同步会失败）、warning（结果不符合预期）和info（提示）三级，没有error时valid为true
+// @Tags 数据基础库
+// @Produce json
+// @Param id path string true "接口ID"
+// @Success 200 {object} APIResponse[basic_library.ConfigLintResult] "检查完成"
+// @Failure 404 {object} APIResponse[any] "接口不存在"
+// @Router /basic-libraries/interfaces/{id}/validate-config [post]
+func (c *ConfigLintController) ValidateInterfaceConfig(w http.ResponseWriter, r *http.Request) {
+	result, err := service.GlobalConfigLintService.Lint(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		render.JSON(w, r, MapErrorResponse("检查接口配置失败", err))
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("检查接口配置完成", result))
+}
@@ -0,0 +1,138 @@
+/*
+ * @module api/controllers/contract_controller
+ * @description 接口数据契约控制器，提供数据契约的查询、配置、删除和合规报告接口
+ * @architecture 分层架构 - 控制器层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow HTTP请求 -> 控制器 -> 接口数据契约服务 -> 数据库
+ * @rules 统一的错误处理和响应格式；契约配置不合法时返回400；修改契约后从下一次同步开始生效
+ * @dependencies datahub-service/service, datahub-service/service/basic_library, github.com/go-chi/chi/v5
+ * @refs service/basic_library/contract_service.go
+ */
+
+package controllers
+
+import (
+	"datahub-service/service"
+	"datahub-service/service/basic_library"
+	"datahub-service/service/models"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+)
+
+// ContractController 接口数据契约控制器
+type ContractController struct {
+}
+
+// NewContractController 创建接口数据契约控制器实例
+func NewContractController() *ContractController {
+	return &ContractController{}
+}
+
+// SaveInterfaceContractRequest 数据契约配置请求
+type SaveInterfaceContractRequest struct {
+	Columns        models.ContractColumns `json:"columns" validate:"required,min=1"`
+	CadenceSeconds int                    `json:"cadence_seconds" validate:"min=0" example:"86400"` // 约定的交付周期，0表示不约定
+	Strictness     string                 `json:"strictness" validate:"omitempty,oneof=warn fail" example:"warn"`
+	Enabled        *bool                  `json:"enabled,omitempty"` // 默认启用
+	Description    string                 `json:"description" validate:"max=500"`
+}
+
+// GetInterfaceContract 获取接口数据契约
+// @Summary 获取接口数据契约
+// @Description 获取接口当前的数据契约，包括预期的列、类型、可空性、枚举取值、交付周期和违规处理方式
+// @Tags 数据基础库
+// @Produce json
+// @Param id path string true "接口ID"
+// @Success 200 {object} APIResponse[models.InterfaceContract] "获取成功"
+// @Failure 404 {object} APIResponse[any] "接口不存在或未配置数据契约"
+// @Router /basic-libraries/interfaces/{id}/contract [get]
+func (c *ContractController) GetInterfaceContract(w http.ResponseWriter, r *http.Request) {
+	contract, err := service.GlobalContractService.GetContract(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		render.JSON(w, r, MapErrorResponse("获取接口数据契约失败", err))
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("获取接口数据契约成功", contract))
+}
+
+// SaveInterfaceContract 配置接口数据契约
+// @Summary 配置接口数据契约
+// @Description 创建或更新接口的数据契约，每次修改版本号加一，从下一次同步开始按新契约校验；strictness为fail时不符合契约的数据拒绝写入
+// @Tags 数据基础库
+// @Accept json
+// @Produce json
+// @Param id path string true "接口ID"
+// @Param request body SaveInterfaceContractRequest true "数据契约"
+// @Success 200 {object} APIResponse[models.InterfaceContract] "配置成功"
+// @Failure 400 {object} APIResponse[any] "请求参数错误"
+// @Failure 404 {object} APIResponse[any] "接口不存在"
+// @Router /basic-libraries/interfaces/{id}/contract [put]
+func (c *ContractController) SaveInterfaceContract(w http.ResponseWriter, r *http.Request) {
+	var req SaveInterfaceContractRequest
+	if !decodeAndValidate(w, r, &req) {
+		return
+	}
+
+	contract, err := service.GlobalContractService.SaveContract(r.Context(), chi.URLParam(r, "id"), &basic_library.ContractInput{
+		Columns:        req.Columns,
+		CadenceSeconds: req.CadenceSeconds,
+		Strictness:     req.Strictness,
+		Enabled:        req.Enabled,
+		Description:    req.Description,
+	}, getCurrentUsername(r))
+	if err != nil {
+		if errors.Is(err, basic_library.ErrInvalidContract) {
+			render.JSON(w, r, BadRequestResponse("配置接口数据契约失败", err))
+			return
+		}
+		render.JSON(w, r, MapErrorResponse("配置接口数据契约失败", err))
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("配置接口数据契约成功", contract))
+}
+
+// DeleteInterfaceContract 删除接口数据契约
+// @Summary 删除接口数据契约
+// @Description 删除接口的数据契约，之后的同步不再校验，历史校验记录保留
+// @Tags 数据基础库
+// @Produce json
+// @Param id path string true "接口ID"
+// @Success 200 {object} APIResponse[any] "删除成功"
+// @Failure 404 {object} APIResponse[any] "接口不存在或未配置数据契约"
+// @Router /basic-libraries/interfaces/{id}/contract [delete]
+func (c *ContractController) DeleteInterfaceContract(w http.ResponseWriter, r *http.Request) {
+	if err := service.GlobalContractService.DeleteContract(r.Context(), chi.URLParam(r, "id")); err != nil {
+		render.JSON(w, r, MapErrorResponse("删除接口数据契约失败", err))
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("删除接口数据契约成功", nil))
+}
+
+// GetContractComplianceReport 获取接口数据契约合规报告
+// @Summary 获取接口数据契约合规报告
+// @Description 汇总接口近N天每次同步的数据契约校验结果，包括合规率、按列和违规类型的统计以及最近的校验记录
+// @Tags 数据基础库
+// @Produce json
+// @Param id path string true "接口ID"
+// @Param days query int false "统计天数，最多180天" default(30)
+// @Success 200 {object} APIResponse[basic_library.ContractComplianceReport] "获取成功"
+// @Failure 404 {object} APIResponse[any] "接口不存在"
+// @Router /basic-libraries/interfaces/{id}/contract/compliance [get]
+func (c *ContractController) GetContractComplianceReport(w http.ResponseWriter, r *http.Request) {
+	days, _ := strconv.Atoi(r.URL.Query().Get("days"))
+
+	report, err := service.GlobalContractService.GetComplianceReport(r.Context(), chi.URLParam(r, "id"), days)
+	if err != nil {
+		render.JSON(w, r, MapErrorResponse("获取数据契约合规报告失败", err))
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("获取数据契约合规报告成功", report))
+}
@@ -0,0 +1,118 @@
+/*
+ * @module api/controllers/credential_rotation_controller
+ * @description 数据源凭据轮换控制器，提供轮换凭据、宽限期内回滚和查询轮换记录的接口
+ * @architecture 分层架构 - 控制器层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow HTTP请求 -> 控制器 -> 凭据轮换服务 -> 连通性测试 -> 替换凭据并写审计日志
+ * @rules 统一的错误处理和响应格式；请求不合法或新凭据连通性测试失败时返回400，没有可回滚的轮换时返回409；响应不包含凭据值
+ * @dependencies datahub-service/service, datahub-service/service/basic_library, github.com/go-chi/chi/v5
+ * @refs service/basic_library/credential_rotation_service.go
+ */
+
+package controllers
+
+import (
+	"datahub-service/service"
+	"datahub-service/service/basic_library"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+)
+
+// CredentialRotationController 数据源凭据轮换控制器
+type CredentialRotationController struct {
+}
+
+// NewCredentialRotationController 创建数据源凭据轮换控制器实例
+func NewCredentialRotationController() *CredentialRotationController {
+	return &CredentialRotationController{}
+}
+
+// RotateCredentialsRequest 凭据轮换请求
+type RotateCredentialsRequest struct {
+	Credentials        map[string]interface{} `json:"credentials" validate:"required,min=1"`                           // 连接配置中的凭据项，嵌套配置用点号分隔，如password、auth.access_token
+	GracePeriodSeconds int                    `json:"grace_period_seconds" validate:"omitempty,min=0" example:"86400"` // 旧凭据保留秒数，默认86400，最长7天
+}
+
+// RotateCredentials 轮换数据源凭据
+// @Summary 轮换数据源凭据
+// @Description 用新凭据替换数据源连接配置中的凭据项。替换前用新凭据测试连通性，测试失败时不修改配置；替换在事务中完成，旧凭据在宽限期内保留以便回滚，轮换写入审计日志（不含凭据值）
+// @Tags 数据基础库
+// @Accept json
+// @Produce json
+// @Param id path string true "数据源ID"
+// @Param request body RotateCredentialsRequest true "新凭据"
+// @Success 200 {object} APIResponse[models.DataSourceCredentialRotation] "轮换成功"
+// @Failure 400 {object} APIResponse[any] "请求不合法或新凭据连通性测试失败"
+// @Failure 404 {object} APIResponse[any] "数据源不存在"
+// @Router /basic-libraries/datasources/{id}/rotate-credentials [post]
+func (c *CredentialRotationController) RotateCredentials(w http.ResponseWriter, r *http.Request) {
+	var req RotateCredentialsRequest
+	if !decodeAndValidate(w, r, &req) {
+		return
+	}
+
+	rotation, err := service.GlobalCredentialRotationService.Rotate(r.Context(), chi.URLParam(r, "id"), &basic_library.CredentialRotationRequest{
+		Credentials:        req.Credentials,
+		GracePeriodSeconds: req.GracePeriodSeconds,
+	}, getCurrentUsername(r), getClientIP(r))
+	if err != nil {
+		respondCredentialRotationError(w, r, "轮换数据源凭据失败", err)
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("轮换数据源凭据成功", rotation))
+}
+
+// RollbackCredentials 回滚数据源凭据
+// @Summary 回滚数据源凭据
+// @Description 在宽限期内把数据源凭据恢复为最近一次轮换前的值，回滚写入审计日志
+// @Tags 数据基础库
+// @Produce json
+// @Param id path string true "数据源ID"
+// @Success 200 {object} APIResponse[models.DataSourceCredentialRotation] "回滚成功"
+// @Failure 404 {object} APIResponse[any] "数据源不存在"
+// @Failure 409 {object} APIResponse[any] "没有宽限期内可回滚的轮换"
+// @Router /basic-libraries/datasources/{id}/rotate-credentials/rollback [post]
+func (c *CredentialRotationController) RollbackCredentials(w http.ResponseWriter, r *http.Request) {
+	rotation, err := service.GlobalCredentialRotationService.Rollback(r.Context(), chi.URLParam(r, "id"), getCurrentUsername(r), getClientIP(r))
+	if err != nil {
+		respondCredentialRotationError(w, r, "回滚数据源凭据失败", err)
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("回滚数据源凭据成功", rotation))
+}
+
+// ListCredentialRotations 查询数据源凭据轮换记录
+// @Summary 查询数据源凭据轮换记录
+// @Description 按轮换时间倒序返回数据源的凭据轮换记录，包括轮换的配置项、状态和宽限期截止时间
+// @Tags 数据基础库
+// @Produce json
+// @Param id path string true "数据源ID"
+// @Success 200 {object} APIResponse[[]models.DataSourceCredentialRotation] "查询成功"
+// @Failure 404 {object} APIResponse[any] "数据源不存在"
+// @Router /basic-libraries/datasources/{id}/credential-rotations [get]
+func (c *CredentialRotationController) ListCredentialRotations(w http.ResponseWriter, r *http.Request) {
+	rotations, err := service.GlobalCredentialRotationService.ListRotations(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		respondCredentialRotationError(w, r, "查询凭据轮换记录失败", err)
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("查询凭据轮换记录成功", rotations))
+}
+
+// respondCredentialRotationError 按错误类型返回凭据轮换错误响应
+func respondCredentialRotationError(w http.ResponseWriter, r *http.Request, msg string, err error) {
+	switch {
+	case errors.Is(err, basic_library.ErrInvalidCredentialRotation), errors.Is(err, basic_library.ErrCredentialTestFailed):
+		render.JSON(w, r, BadRequestResponse(msg, err))
+	case errors.Is(err, basic_library.ErrNoRollbackableRotation):
+		render.JSON(w, r, ConflictResponse(msg, err))
+	default:
+		render.JSON(w, r, MapErrorResponse(msg, err))
+	}
+}
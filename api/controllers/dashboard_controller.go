@@ -12,11 +12,14 @@
 package controllers
 
 import (
+	"context"
 	"database/sql"
 	"datahub-service/service"
+	"datahub-service/service/capacity"
 	"datahub-service/service/governance"
 	"datahub-service/service/models"
 	"datahub-service/service/sharing"
+	"log/slog"
 	"net/http"
 	"time"
 
@@ -60,6 +63,9 @@ type DashboardOverviewResponse struct {
 	// 系统活动统计
 	SystemActivityStats SystemActivityStats `json:"system_activity_stats"`
 
+	// 配额用量，已达到上限的排在前面
+	QuotaUsage []capacity.QuotaUsage `json:"quota_usage"`
+
 	// 更新时间
 	UpdatedAt time.Time `json:"updated_at"`
 }
@@ -348,11 +354,11 @@ type PeakUsageTimeInfo struct {
 
 // GetDashboardOverview 获取Dashboard总览数据
 // @Summary 获取Dashboard总览数据
-// @Description 获取系统各模块的统计数据和关键指标
+// @Description 获取系统各模块的统计数据和关键指标，以及各库和租户的配额用量
 // @Tags Dashboard
 // @Produce json
-// @Success 200 {object} APIResponse{data=DashboardOverviewResponse} "获取成功"
-// @Failure 500 {object} APIResponse "服务器内部错误"
+// @Success 200 {object} APIResponse[DashboardOverviewResponse] "获取成功"
+// @Failure 500 {object} APIResponse[any] "服务器内部错误"
 // @Router /dashboard/overview [get]
 func (c *DashboardController) GetDashboardOverview(w http.ResponseWriter, r *http.Request) {
 	overview := DashboardOverviewResponse{
@@ -362,12 +368,23 @@ func (c *DashboardController) GetDashboardOverview(w http.ResponseWriter, r *htt
 		DataQualityStats:     c.getDataQualityStats(),
 		DataSharingStats:     c.getDataSharingStats(),
 		SystemActivityStats:  c.getSystemActivityStats(),
+		QuotaUsage:           c.getQuotaUsage(r.Context()),
 		UpdatedAt:            time.Now(),
 	}
 
 	render.JSON(w, r, SuccessResponse("获取Dashboard总览数据成功", overview))
 }
 
+// getQuotaUsage 获取全部配额的用量，查询失败时返回空列表
+func (c *DashboardController) getQuotaUsage(ctx context.Context) []capacity.QuotaUsage {
+	usage, err := service.GlobalQuotaService.ListUsage(ctx)
+	if err != nil {
+		slog.Error("获取配额用量失败", "error", err)
+		return []capacity.QuotaUsage{}
+	}
+	return usage
+}
+
 // getBasicLibraryStats 获取基础库统计数据
 func (c *DashboardController) getBasicLibraryStats() BasicLibraryStats {
 	stats := BasicLibraryStats{
@@ -798,8 +815,8 @@ func (c *DashboardController) getSystemActivityStats() SystemActivityStats {
 // @Description 获取基础库的详细统计信息
 // @Tags Dashboard
 // @Produce json
-// @Success 200 {object} APIResponse{data=BasicLibraryStats} "获取成功"
-// @Failure 500 {object} APIResponse "服务器内部错误"
+// @Success 200 {object} APIResponse[BasicLibraryStats] "获取成功"
+// @Failure 500 {object} APIResponse[any] "服务器内部错误"
 // @Router /dashboard/basic-library-stats [get]
 func (c *DashboardController) GetBasicLibraryStats(w http.ResponseWriter, r *http.Request) {
 	stats := c.getBasicLibraryStats()
@@ -811,8 +828,8 @@ func (c *DashboardController) GetBasicLibraryStats(w http.ResponseWriter, r *htt
 // @Description 获取主题库的详细统计信息
 // @Tags Dashboard
 // @Produce json
-// @Success 200 {object} APIResponse{data=ThematicLibraryStats} "获取成功"
-// @Failure 500 {object} APIResponse "服务器内部错误"
+// @Success 200 {object} APIResponse[ThematicLibraryStats] "获取成功"
+// @Failure 500 {object} APIResponse[any] "服务器内部错误"
 // @Router /dashboard/thematic-library-stats [get]
 func (c *DashboardController) GetThematicLibraryStats(w http.ResponseWriter, r *http.Request) {
 	stats := c.getThematicLibraryStats()
@@ -824,8 +841,8 @@ func (c *DashboardController) GetThematicLibraryStats(w http.ResponseWriter, r *
 // @Description 获取同步任务的详细统计信息
 // @Tags Dashboard
 // @Produce json
-// @Success 200 {object} APIResponse{data=SyncTaskStats} "获取成功"
-// @Failure 500 {object} APIResponse "服务器内部错误"
+// @Success 200 {object} APIResponse[SyncTaskStats] "获取成功"
+// @Failure 500 {object} APIResponse[any] "服务器内部错误"
 // @Router /dashboard/sync-task-stats [get]
 func (c *DashboardController) GetSyncTaskStats(w http.ResponseWriter, r *http.Request) {
 	stats := c.getSyncTaskStats()
@@ -837,8 +854,8 @@ func (c *DashboardController) GetSyncTaskStats(w http.ResponseWriter, r *http.Re
 // @Description 获取数据质量的详细统计信息
 // @Tags Dashboard
 // @Produce json
-// @Success 200 {object} APIResponse{data=DataQualityStats} "获取成功"
-// @Failure 500 {object} APIResponse "服务器内部错误"
+// @Success 200 {object} APIResponse[DataQualityStats] "获取成功"
+// @Failure 500 {object} APIResponse[any] "服务器内部错误"
 // @Router /dashboard/data-quality-stats [get]
 func (c *DashboardController) GetDataQualityStats(w http.ResponseWriter, r *http.Request) {
 	stats := c.getDataQualityStats()
@@ -850,8 +867,8 @@ func (c *DashboardController) GetDataQualityStats(w http.ResponseWriter, r *http
 // @Description 获取数据共享的详细统计信息
 // @Tags Dashboard
 // @Produce json
-// @Success 200 {object} APIResponse{data=DataSharingStats} "获取成功"
-// @Failure 500 {object} APIResponse "服务器内部错误"
+// @Success 200 {object} APIResponse[DataSharingStats] "获取成功"
+// @Failure 500 {object} APIResponse[any] "服务器内部错误"
 // @Router /dashboard/data-sharing-stats [get]
 func (c *DashboardController) GetDataSharingStats(w http.ResponseWriter, r *http.Request) {
 	stats := c.getDataSharingStats()
@@ -863,8 +880,8 @@ func (c *DashboardController) GetDataSharingStats(w http.ResponseWriter, r *http
 // @Description 获取系统活动的详细统计信息
 // @Tags Dashboard
 // @Produce json
-// @Success 200 {object} APIResponse{data=SystemActivityStats} "获取成功"
-// @Failure 500 {object} APIResponse "服务器内部错误"
+// @Success 200 {object} APIResponse[SystemActivityStats] "获取成功"
+// @Failure 500 {object} APIResponse[any] "服务器内部错误"
 // @Router /dashboard/system-activity-stats [get]
 func (c *DashboardController) GetSystemActivityStats(w http.ResponseWriter, r *http.Request) {
 	stats := c.getSystemActivityStats()
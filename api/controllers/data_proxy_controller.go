@@ -12,19 +12,25 @@
 package controllers
 
 import (
+	"bytes"
 	"context"
 	"datahub-service/client"
 	"datahub-service/service/governance"
+	"datahub-service/service/metrics"
 	"datahub-service/service/models"
 	"datahub-service/service/rate_limiter"
 	"datahub-service/service/sharing"
+	"datahub-service/service/utils"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log/slog"
 	"net/http"
+	"net/url"
 	"os"
 	"sort"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -158,18 +164,24 @@ func (c *DataProxyController) removePostgRESTClient(apiKeyID string) {
 
 // ProxyDataAccess 数据访问代理处理器
 // @Summary 数据访问代理（只读查询）
-// @Description 代理对PostgREST的查询请求，实现统一的鉴权、日志、限流和路由功能，仅支持数据查询操作
-// @Tags 数据共享服务
+// @Description 代理对PostgREST的查询请求，实现统一的鉴权、日志、限流和路由功能，仅支持数据查询操作。带bbox或near参数时按空间范围直接查询接口表，只能与geo_field、select、limit、offset参数组合，空间字段以GeoJSON返回，Content-Range给出本页范围和总数。请求头Accept为text/csv或application/x-ndjson时逐行返回CSV（首行为列名，顺序与查询返回的字段一致）或NDJSON，不需要在内存中组装整个JSON数组，适合拉取大结果集；脱敏规则同样逐行应用。接口或其主题接口已弃用时响应带Deprecation头（弃用时间）、Sunset头（下线时间）和指向替代接口的Link头，到下线时间后返回410
+// @Tags 数据访问
 // @Accept json
 // @Produce json
+// @Produce text/csv
+// @Produce application/x-ndjson
 // @Param app_path path string true "应用路径"
 // @Param interface_path path string true "接口路径"
+// @Param bbox query string false "与矩形范围相交：最小经度,最小纬度,最大经度,最大纬度" example("120.1,30.2,120.3,30.4")
+// @Param near query string false "在圆形范围内：经度,纬度,半径(米)" example("120.2,30.3,500")
+// @Param geo_field query string false "空间筛选字段，接口只有一个空间字段时可不填"
 // @Param Authorization header string true "Bearer Token格式的API Key"
 // @Success 200 {object} interface{} "查询成功"
-// @Failure 401 {object} APIResponse "未授权"
-// @Failure 404 {object} APIResponse "资源不存在"
-// @Failure 429 {object} APIResponse "请求过于频繁"
-// @Failure 500 {object} APIResponse "服务器内部错误"
+// @Failure 401 {object} APIResponse[any] "未授权"
+// @Failure 404 {object} APIResponse[any] "资源不存在"
+// @Failure 410 {object} APIResponse[any] "接口已到下线时间"
+// @Failure 429 {object} APIResponse[any] "请求过于频繁"
+// @Failure 500 {object} APIResponse[any] "服务器内部错误"
 // @Router /api/v1/share/{app_path}/{interface_path} [get]
 // @Router /api/v1/share/{app_path}/{interface_path} [head]
 func (c *DataProxyController) ProxyDataAccess(w http.ResponseWriter, r *http.Request) {
@@ -179,7 +191,7 @@ func (c *DataProxyController) ProxyDataAccess(w http.ResponseWriter, r *http.Req
 	if r.Method != http.MethodGet && r.Method != http.MethodHead {
 		c.logApiUsage(r, "", "", http.StatusMethodNotAllowed, time.Since(startTime), "不支持的HTTP方法: "+r.Method)
 		w.Header().Set("Allow", "GET, HEAD")
-		render.JSON(w, r, APIResponse{
+		render.JSON(w, r, APIResponse[any]{
 			Status: http.StatusMethodNotAllowed,
 			Msg:    "仅支持GET和HEAD方法进行数据查询",
 		})
@@ -194,7 +206,7 @@ func (c *DataProxyController) ProxyDataAccess(w http.ResponseWriter, r *http.Req
 	authHeader := r.Header.Get("Authorization")
 	if authHeader == "" {
 		c.logApiUsage(r, "", "", http.StatusUnauthorized, time.Since(startTime), "缺少Authorization头")
-		render.JSON(w, r, APIResponse{
+		render.JSON(w, r, APIResponse[any]{
 			Status: http.StatusUnauthorized,
 			Msg:    "缺少Authorization头",
 		})
@@ -204,7 +216,7 @@ func (c *DataProxyController) ProxyDataAccess(w http.ResponseWriter, r *http.Req
 	// 解析Bearer Token
 	if !strings.HasPrefix(authHeader, "Bearer ") {
 		c.logApiUsage(r, "", "", http.StatusUnauthorized, time.Since(startTime), "无效的Authorization格式")
-		render.JSON(w, r, APIResponse{
+		render.JSON(w, r, APIResponse[any]{
 			Status: http.StatusUnauthorized,
 			Msg:    "无效的Authorization格式，请使用Bearer Token",
 		})
@@ -214,10 +226,10 @@ func (c *DataProxyController) ProxyDataAccess(w http.ResponseWriter, r *http.Req
 	apiKeyValue := strings.TrimPrefix(authHeader, "Bearer ")
 
 	// 3. 验证API Key
-	apiKey, err := c.sharingService.VerifyApiKey(apiKeyValue)
+	apiKey, err := c.sharingService.AuthenticateApiKey(apiKeyValue, models.ApiKeyScopeShareRead, getClientIP(r))
 	if err != nil {
 		c.logApiUsage(r, "", "", http.StatusUnauthorized, time.Since(startTime), err.Error())
-		render.JSON(w, r, APIResponse{
+		render.JSON(w, r, APIResponse[any]{
 			Status: http.StatusUnauthorized,
 			Msg:    "API Key验证失败: " + err.Error(),
 		})
@@ -228,7 +240,7 @@ func (c *DataProxyController) ProxyDataAccess(w http.ResponseWriter, r *http.Req
 	apiInterface, err := c.sharingService.GetApiInterfaceByAppPathAndInterfacePath(appPath, interfacePath)
 	if err != nil {
 		c.logApiUsage(r, "", apiKey.ID, http.StatusNotFound, time.Since(startTime), "接口不存在或已禁用")
-		render.JSON(w, r, APIResponse{
+		render.JSON(w, r, APIResponse[any]{
 			Status: http.StatusNotFound,
 			Msg:    "接口不存在或已禁用",
 		})
@@ -239,13 +251,29 @@ func (c *DataProxyController) ProxyDataAccess(w http.ResponseWriter, r *http.Req
 	hasAccess, err := c.verifyApiKeyAccess(apiKey.ID, apiInterface.ApiApplicationID)
 	if err != nil || !hasAccess {
 		c.logApiUsage(r, apiInterface.ApiApplicationID, apiKey.ID, http.StatusUnauthorized, time.Since(startTime), "API Key无权访问该应用接口")
-		render.JSON(w, r, APIResponse{
+		render.JSON(w, r, APIResponse[any]{
 			Status: http.StatusUnauthorized,
 			Msg:    "API Key无权访问该应用接口",
 		})
 		return
 	}
 
+	// 5.2. 弃用的接口在响应头中提示订阅方，到达下线时间后不再返回数据
+	deprecation, err := c.sharingService.ResolveApiInterfaceDeprecation(apiInterface)
+	if err != nil {
+		slog.Error("查询接口弃用记录失败", "api_interface_id", apiInterface.ID, "error", err)
+	} else if deprecation != nil {
+		setDeprecationHeaders(w, deprecation)
+		if deprecation.Sunset(time.Now()) {
+			c.logApiUsage(r, apiInterface.ApiApplicationID, apiKey.ID, http.StatusGone, time.Since(startTime), "接口已下线")
+			render.JSON(w, r, APIResponse[any]{
+				Status: http.StatusGone,
+				Msg:    fmt.Sprintf("接口已于 %s 下线", deprecation.SunsetAt.Format(time.DateTime)),
+			})
+			return
+		}
+	}
+
 	// 5.5. 检查限流（全局 -> 密钥 -> 应用）
 	if c.rateLimiter != nil {
 		rateLimitResult, err := c.checkRateLimit(r.Context(), apiKey.ID, apiInterface.ApiApplicationID)
@@ -261,7 +289,7 @@ func (c *DataProxyController) ProxyDataAccess(w http.ResponseWriter, r *http.Req
 			w.Header().Set("X-RateLimit-Reset", fmt.Sprintf("%d", rateLimitResult.ResetAt))
 			w.Header().Set("X-RateLimit-Type", rateLimitResult.RateLimitType)
 
-			render.JSON(w, r, APIResponse{
+			render.JSON(w, r, APIResponse[any]{
 				Status: http.StatusTooManyRequests,
 				Msg:    rateLimitResult.Message,
 			})
@@ -270,33 +298,84 @@ func (c *DataProxyController) ProxyDataAccess(w http.ResponseWriter, r *http.Req
 	}
 
 	// 6. 获取主题库schema和主题接口信息（table_name）
-	schema := apiInterface.ApiApplication.ThematicLibrary.NameEn
+	schema := apiInterface.ApiApplication.ThematicLibrary.GetSchemaName()
 	if schema == "" {
 		c.logApiUsage(r, apiInterface.ApiApplicationID, apiKey.ID, http.StatusInternalServerError, time.Since(startTime), "主题库英文名为空")
-		render.JSON(w, r, APIResponse{
+		render.JSON(w, r, APIResponse[any]{
 			Status: http.StatusInternalServerError,
 			Msg:    "主题库配置错误",
 		})
 		return
 	}
 
-	tableName := apiInterface.ThematicInterface.NameEn
+	// 灰度发布期间，灰度订阅方访问新版本主题接口
+	thematicInterface, release, err := c.sharingService.ResolveReleaseInterface(apiInterface, apiKey.ID)
+	if err != nil {
+		c.logApiUsage(r, apiInterface.ApiApplicationID, apiKey.ID, http.StatusInternalServerError, time.Since(startTime), "解析灰度发布失败: "+err.Error())
+		render.JSON(w, r, APIResponse[any]{
+			Status: http.StatusInternalServerError,
+			Msg:    "解析接口版本失败",
+		})
+		return
+	}
+	if release != nil {
+		w.Header().Set("X-Data-Release", release.ID)
+	}
+
+	tableName := thematicInterface.NameEn
 	if tableName == "" {
 		c.logApiUsage(r, apiInterface.ApiApplicationID, apiKey.ID, http.StatusInternalServerError, time.Since(startTime), "主题接口英文名为空")
-		render.JSON(w, r, APIResponse{
+		render.JSON(w, r, APIResponse[any]{
 			Status: http.StatusInternalServerError,
 			Msg:    "主题接口配置错误",
 		})
 		return
 	}
 
+	// 7. 解析行级安全策略，匹配的过滤条件追加到查询参数
+	rowFilter, err := c.sharingService.ResolveRowFilter(schema, tableName, sharing.RowPolicySubject{
+		ApiKeyID:      apiKey.ID,
+		ApplicationID: apiInterface.ApiApplicationID,
+	})
+	if err != nil {
+		if errors.Is(err, sharing.ErrRowAccessDenied) {
+			c.logApiUsage(r, apiInterface.ApiApplicationID, apiKey.ID, http.StatusForbidden, time.Since(startTime), err.Error())
+			render.JSON(w, r, APIResponse[any]{
+				Status: http.StatusForbidden,
+				Msg:    "无权访问该接口数据",
+			})
+			return
+		}
+		c.logApiUsage(r, apiInterface.ApiApplicationID, apiKey.ID, http.StatusInternalServerError, time.Since(startTime), err.Error())
+		render.JSON(w, r, APIResponse[any]{
+			Status: http.StatusInternalServerError,
+			Msg:    "行级安全策略解析失败",
+		})
+		return
+	}
+	// 8. PostgREST不支持空间函数，带空间范围筛选的请求直接查询主题接口表
+	if sharing.IsGeoQuery(r.URL.Query()) {
+		c.serveGeoQuery(w, r, startTime, apiInterface, apiKey.ID, schema, tableName, rowFilter)
+		return
+	}
+
+	rawQuery := r.URL.RawQuery
+	if !rowFilter.IsEmpty() {
+		policyParam := "and=" + url.QueryEscape(rowFilter.PostgRESTFilter())
+		if rawQuery == "" {
+			rawQuery = policyParam
+		} else {
+			rawQuery = rawQuery + "&" + policyParam
+		}
+	}
+
 	// 9. 读取请求体
 	var bodyBytes []byte
 	if r.Body != nil {
 		bodyBytes, err = io.ReadAll(r.Body)
 		if err != nil {
 			c.logApiUsage(r, apiInterface.ApiApplicationID, apiKey.ID, http.StatusInternalServerError, time.Since(startTime), "读取请求体失败")
-			render.JSON(w, r, APIResponse{
+			render.JSON(w, r, APIResponse[any]{
 				Status: http.StatusInternalServerError,
 				Msg:    "读取请求体失败",
 			})
@@ -318,6 +397,12 @@ func (c *DataProxyController) ProxyDataAccess(w http.ResponseWriter, r *http.Req
 		}
 	}
 
+	// 请求CSV或NDJSON时向PostgREST请求JSON，逐行应用脱敏规则后再转换格式
+	format := negotiateRowFormat(r)
+	if format != utils.RowFormatJSON {
+		additionalHeaders["Accept"] = utils.MediaTypeJSON
+	}
+
 	// 设置schema头
 	if r.Method == "GET" || r.Method == "HEAD" {
 		additionalHeaders["Accept-Profile"] = schema
@@ -329,7 +414,7 @@ func (c *DataProxyController) ProxyDataAccess(w http.ResponseWriter, r *http.Req
 	postgrestClient, err := c.getOrCreatePostgRESTClient(apiKey.ID, schema)
 	if err != nil {
 		c.logApiUsage(r, apiInterface.ApiApplicationID, apiKey.ID, http.StatusInternalServerError, time.Since(startTime), "获取PostgREST客户端失败: "+err.Error())
-		render.JSON(w, r, APIResponse{
+		render.JSON(w, r, APIResponse[any]{
 			Status: http.StatusInternalServerError,
 			Msg:    "服务初始化失败",
 		})
@@ -337,14 +422,14 @@ func (c *DataProxyController) ProxyDataAccess(w http.ResponseWriter, r *http.Req
 	}
 
 	// 12. 使用PostgREST客户端发送请求
-	proxyResp, err := postgrestClient.ProxyRequest(r.Method, tableName, r.URL.RawQuery, bodyBytes, additionalHeaders)
+	proxyResp, err := postgrestClient.ProxyRequest(r.Method, tableName, rawQuery, bodyBytes, additionalHeaders)
 	if err != nil {
 		// 如果是认证错误，可能需要重新创建客户端
 		if strings.Contains(err.Error(), "401") || strings.Contains(err.Error(), "unauthorized") {
 			c.removePostgRESTClient(apiKey.ID)
 		}
 		c.logApiUsage(r, apiInterface.ApiApplicationID, apiKey.ID, http.StatusInternalServerError, time.Since(startTime), "代理请求失败: "+err.Error())
-		render.JSON(w, r, APIResponse{
+		render.JSON(w, r, APIResponse[any]{
 			Status: http.StatusInternalServerError,
 			Msg:    "代理请求失败",
 		})
@@ -352,11 +437,16 @@ func (c *DataProxyController) ProxyDataAccess(w http.ResponseWriter, r *http.Req
 	}
 	defer proxyResp.Body.Close()
 
+	if format != utils.RowFormatJSON && (proxyResp.StatusCode == http.StatusOK || proxyResp.StatusCode == http.StatusPartialContent) {
+		c.streamProxyRows(w, r, startTime, apiInterface, apiKey.ID, proxyResp, format, int64(len(bodyBytes)))
+		return
+	}
+
 	// 13. 读取响应体以便应用脱敏规则
 	responseBody, err := io.ReadAll(proxyResp.Body)
 	if err != nil {
 		c.logApiUsage(r, apiInterface.ApiApplicationID, apiKey.ID, http.StatusInternalServerError, time.Since(startTime), "读取响应体失败: "+err.Error())
-		render.JSON(w, r, APIResponse{
+		render.JSON(w, r, APIResponse[any]{
 			Status: http.StatusInternalServerError,
 			Msg:    "读取响应体失败",
 		})
@@ -367,40 +457,7 @@ func (c *DataProxyController) ProxyDataAccess(w http.ResponseWriter, r *http.Req
 	var finalResponseBody []byte
 	if len(apiInterface.MaskingRules) > 0 && proxyResp.StatusCode == http.StatusOK {
 		// 解析脱敏规则
-		var maskingConfigs []models.DataMaskingConfig
-		for _, ruleValue := range apiInterface.MaskingRules {
-			if ruleData, ok := ruleValue.(map[string]interface{}); ok {
-				var rule models.DataMaskingConfig
-
-				// 解析规则配置
-				if templateID, ok := ruleData["template_id"].(string); ok {
-					rule.TemplateID = templateID
-				}
-				if targetFields, ok := ruleData["target_fields"].([]interface{}); ok {
-					for _, field := range targetFields {
-						if fieldStr, ok := field.(string); ok {
-							rule.TargetFields = append(rule.TargetFields, fieldStr)
-						}
-					}
-				}
-				if maskingConfig, ok := ruleData["masking_config"].(map[string]interface{}); ok {
-					rule.MaskingConfig = maskingConfig
-				}
-				if applyCondition, ok := ruleData["apply_condition"].(string); ok {
-					rule.ApplyCondition = applyCondition
-				}
-				if preserveFormat, ok := ruleData["preserve_format"].(bool); ok {
-					rule.PreserveFormat = preserveFormat
-				}
-				if isEnabled, ok := ruleData["is_enabled"].(bool); ok {
-					rule.IsEnabled = isEnabled
-				} else {
-					rule.IsEnabled = true
-				}
-
-				maskingConfigs = append(maskingConfigs, rule)
-			}
-		}
+		maskingConfigs := parseMaskingRules(apiInterface.MaskingRules)
 
 		// 应用脱敏处理
 		maskedData, maskErr := c.applyMaskingToResponseData(responseBody, maskingConfigs)
@@ -440,6 +497,259 @@ func (c *DataProxyController) ProxyDataAccess(w http.ResponseWriter, r *http.Req
 	c.logApiUsageWithSize(r, apiInterface.ApiApplicationID, apiKey.ID, proxyResp.StatusCode, time.Since(startTime), "", int64(len(bodyBytes)), int64(responseSize))
 }
 
+// streamProxyRows 逐个读取PostgREST返回的JSON数组元素，应用脱敏规则后以CSV或NDJSON写出
+func (c *DataProxyController) streamProxyRows(w http.ResponseWriter, r *http.Request, startTime time.Time, apiInterface *models.ApiInterface, apiKeyID string, proxyResp *http.Response, format utils.RowFormat, requestSize int64) {
+	appID := apiInterface.ApiApplicationID
+	for key, values := range proxyResp.Header {
+		if key == "Content-Type" || key == "Content-Length" {
+			continue
+		}
+		for _, value := range values {
+			w.Header().Add(key, value)
+		}
+	}
+	if r.Method == http.MethodHead {
+		_, _ = startRowStream(w, r, format, nil, proxyResp.StatusCode)
+		c.logApiUsageWithSize(r, appID, apiKeyID, proxyResp.StatusCode, time.Since(startTime), "", requestSize, 0)
+		return
+	}
+
+	maskingConfigs := parseMaskingRules(apiInterface.MaskingRules)
+	var stream *rowStream
+	err := utils.StreamJSONArray(proxyResp.Body, func(element json.RawMessage) error {
+		if stream == nil {
+			// CSV列顺序与PostgREST返回的字段顺序（即select参数的顺序）一致
+			columns, err := utils.JSONObjectKeys(element)
+			if err != nil {
+				return err
+			}
+			if stream, err = startRowStream(w, r, format, columns, proxyResp.StatusCode); err != nil {
+				return err
+			}
+		}
+
+		decoder := json.NewDecoder(bytes.NewReader(element))
+		decoder.UseNumber()
+		var record map[string]interface{}
+		if err := decoder.Decode(&record); err != nil {
+			return err
+		}
+		if len(maskingConfigs) > 0 {
+			masked, maskErr := c.maskSingleRecord(record, maskingConfigs)
+			if maskErr != nil {
+				slog.Error("应用脱敏规则失败", "error", maskErr, "interface_id", apiInterface.ID)
+			} else {
+				record = masked
+			}
+		}
+		return stream.Write(record)
+	})
+	if err != nil && stream == nil {
+		c.logApiUsage(r, appID, apiKeyID, http.StatusInternalServerError, time.Since(startTime), "读取查询结果失败: "+err.Error())
+		render.JSON(w, r, APIResponse[any]{Status: http.StatusInternalServerError, Msg: "读取查询结果失败"})
+		return
+	}
+	if stream == nil {
+		// 查询结果为空
+		if stream, err = startRowStream(w, r, format, nil, proxyResp.StatusCode); err != nil {
+			return
+		}
+	}
+	if closeErr := stream.Close(); err == nil {
+		err = closeErr
+	}
+	errorMsg := ""
+	if err != nil {
+		// 响应头已写出，只能中断响应
+		errorMsg = "写出查询结果失败: " + err.Error()
+		slog.Warn("写出查询结果失败", "interface_id", apiInterface.ID, "format", format, "error", err)
+	}
+	c.logApiUsageWithSize(r, appID, apiKeyID, proxyResp.StatusCode, time.Since(startTime), errorMsg, requestSize, stream.Written())
+}
+
+// serveGeoQuery 按bbox/near空间范围查询主题接口表，返回与PostgREST相同格式的JSON数组，Content-Range给出本页范围和总数
+func (c *DataProxyController) serveGeoQuery(w http.ResponseWriter, r *http.Request, startTime time.Time, apiInterface *models.ApiInterface, apiKeyID, schema, tableName string, rowFilter *sharing.RowFilter) {
+	appID := apiInterface.ApiApplicationID
+	query, err := sharing.ParseGeoQuery(r.URL.Query())
+	var result *sharing.GeoQueryResult
+	if err == nil {
+		result, err = c.sharingService.QueryGeoRows(schema, tableName, query, rowFilter)
+	}
+	if err != nil {
+		status, msg := http.StatusInternalServerError, "空间范围查询失败"
+		if errors.Is(err, sharing.ErrInvalidGeoQuery) {
+			status, msg = http.StatusBadRequest, err.Error()
+		}
+		c.logApiUsage(r, appID, apiKeyID, status, time.Since(startTime), err.Error())
+		render.JSON(w, r, APIResponse[any]{Status: status, Msg: msg})
+		return
+	}
+
+	records := make([]interface{}, len(result.Rows))
+	for i, row := range result.Rows {
+		records[i] = row
+	}
+	if maskingConfigs := parseMaskingRules(apiInterface.MaskingRules); len(maskingConfigs) > 0 {
+		masked, err := c.maskMultipleRecords(records, maskingConfigs)
+		if err != nil {
+			slog.Error("应用脱敏规则失败", "error", err, "interface_id", apiInterface.ID)
+		} else {
+			records = masked
+		}
+	}
+	if format := negotiateRowFormat(r); format != utils.RowFormatJSON {
+		rows := make([]map[string]interface{}, 0, len(records))
+		for _, record := range records {
+			if row, ok := record.(map[string]interface{}); ok {
+				rows = append(rows, row)
+			}
+		}
+		responseSize := writeRows(w, r, format, rows, query.Offset, result.Total)
+		c.logApiUsageWithSize(r, appID, apiKeyID, http.StatusOK, time.Since(startTime), "", 0, responseSize)
+		return
+	}
+
+	body, err := json.Marshal(records)
+	if err != nil {
+		c.logApiUsage(r, appID, apiKeyID, http.StatusInternalServerError, time.Since(startTime), "序列化查询结果失败: "+err.Error())
+		render.JSON(w, r, APIResponse[any]{Status: http.StatusInternalServerError, Msg: "空间范围查询失败"})
+		return
+	}
+
+	contentRange := fmt.Sprintf("*/%d", result.Total)
+	if len(records) > 0 {
+		contentRange = fmt.Sprintf("%d-%d/%d", query.Offset, query.Offset+len(records)-1, result.Total)
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.Header().Set("Content-Range", contentRange)
+	w.Header().Set("Content-Length", fmt.Sprintf("%d", len(body)))
+	w.WriteHeader(http.StatusOK)
+	responseSize := 0
+	if r.Method != http.MethodHead {
+		responseSize, _ = w.Write(body)
+	}
+	c.logApiUsageWithSize(r, appID, apiKeyID, http.StatusOK, time.Since(startTime), "", 0, int64(responseSize))
+}
+
+// GetInterfaceChangelog 拉取共享接口的变更日志
+// @Summary 拉取共享接口的变更日志
+// @Description 按游标拉取共享接口对应主题接口的变更日志（新增、修改、删除的主键和修改的列，不含数据值），下游按主键补读变化的行即可，不必重新读取整张表。since为上次返回的next_since，也可以是RFC3339时间；为空时从保留的第一条开始。resync_required为true表示since之后有变更已超出保留期被清理，需要重新读取全量数据。主题接口须由管理员开启变更日志；接口配置了匹配该API Key的行级安全策略时不能拉取变更日志；主键字段按接口的脱敏规则脱敏
+// @Tags 数据访问
+// @Produce json
+// @Param interface_id path string true "共享接口ID"
+// @Param since query string false "起始游标（不含），变更序号或RFC3339时间"
+// @Param limit query int false "每页条数，默认1000，最多10000"
+// @Param Authorization header string true "Bearer Token格式的API Key"
+// @Success 200 {object} APIResponse[sharing.ChangelogPage] "获取成功"
+// @Failure 400 {object} APIResponse[any] "since不合法"
+// @Failure 401 {object} APIResponse[any] "未授权"
+// @Failure 403 {object} APIResponse[any] "接口配置了行级安全策略"
+// @Failure 404 {object} APIResponse[any] "接口不存在或未开启变更日志"
+// @Failure 429 {object} APIResponse[any] "请求过于频繁"
+// @Router /api/v1/share/changelog/{interface_id} [get]
+func (c *DataProxyController) GetInterfaceChangelog(w http.ResponseWriter, r *http.Request) {
+	startTime := time.Now()
+
+	authHeader := r.Header.Get("Authorization")
+	if !strings.HasPrefix(authHeader, "Bearer ") {
+		c.logApiUsage(r, "", "", http.StatusUnauthorized, time.Since(startTime), "缺少或无效的Authorization头")
+		render.JSON(w, r, APIResponse[any]{Status: http.StatusUnauthorized, Msg: "缺少或无效的Authorization头，请使用Bearer Token"})
+		return
+	}
+	apiKey, err := c.sharingService.AuthenticateApiKey(strings.TrimPrefix(authHeader, "Bearer "), models.ApiKeyScopeShareRead, getClientIP(r))
+	if err != nil {
+		c.logApiUsage(r, "", "", http.StatusUnauthorized, time.Since(startTime), err.Error())
+		render.JSON(w, r, APIResponse[any]{Status: http.StatusUnauthorized, Msg: "API Key验证失败: " + err.Error()})
+		return
+	}
+
+	apiInterface, err := c.sharingService.GetActiveApiInterface(chi.URLParam(r, "interface_id"))
+	if err != nil {
+		c.logApiUsage(r, "", apiKey.ID, http.StatusNotFound, time.Since(startTime), "接口不存在或已禁用")
+		render.JSON(w, r, APIResponse[any]{Status: http.StatusNotFound, Msg: "接口不存在或已禁用"})
+		return
+	}
+	appID := apiInterface.ApiApplicationID
+	if hasAccess, err := c.verifyApiKeyAccess(apiKey.ID, appID); err != nil || !hasAccess {
+		c.logApiUsage(r, appID, apiKey.ID, http.StatusUnauthorized, time.Since(startTime), "API Key无权访问该应用接口")
+		render.JSON(w, r, APIResponse[any]{Status: http.StatusUnauthorized, Msg: "API Key无权访问该应用接口"})
+		return
+	}
+
+	if c.rateLimiter != nil {
+		rateLimitResult, err := c.checkRateLimit(r.Context(), apiKey.ID, appID)
+		if err != nil {
+			slog.Error("限流检查失败", "error", err)
+		} else if !rateLimitResult.Allowed {
+			c.logApiUsage(r, appID, apiKey.ID, http.StatusTooManyRequests, time.Since(startTime), rateLimitResult.Message)
+			w.Header().Set("X-RateLimit-Limit", fmt.Sprintf("%d", rateLimitResult.Limit))
+			w.Header().Set("X-RateLimit-Remaining", fmt.Sprintf("%d", rateLimitResult.Remaining))
+			w.Header().Set("X-RateLimit-Reset", fmt.Sprintf("%d", rateLimitResult.ResetAt))
+			w.Header().Set("X-RateLimit-Type", rateLimitResult.RateLimitType)
+			render.JSON(w, r, APIResponse[any]{Status: http.StatusTooManyRequests, Msg: rateLimitResult.Message})
+			return
+		}
+	}
+
+	// 灰度订阅方拉取新版本主题接口的变更日志
+	thematicInterface, release, err := c.sharingService.ResolveReleaseInterface(apiInterface, apiKey.ID)
+	if err != nil {
+		c.logApiUsage(r, appID, apiKey.ID, http.StatusInternalServerError, time.Since(startTime), "解析灰度发布失败: "+err.Error())
+		render.JSON(w, r, APIResponse[any]{Status: http.StatusInternalServerError, Msg: "解析接口版本失败"})
+		return
+	}
+	if release != nil {
+		w.Header().Set("X-Data-Release", release.ID)
+	}
+
+	// 变更日志无法按行级条件过滤，匹配行级安全策略的调用方不能拉取
+	rowFilter, err := c.sharingService.ResolveRowFilter(apiInterface.ApiApplication.ThematicLibrary.GetSchemaName(), thematicInterface.NameEn, sharing.RowPolicySubject{
+		ApiKeyID:      apiKey.ID,
+		ApplicationID: appID,
+	})
+	if err != nil && !errors.Is(err, sharing.ErrRowAccessDenied) {
+		c.logApiUsage(r, appID, apiKey.ID, http.StatusInternalServerError, time.Since(startTime), err.Error())
+		render.JSON(w, r, APIResponse[any]{Status: http.StatusInternalServerError, Msg: "行级安全策略解析失败"})
+		return
+	}
+	if err != nil || !rowFilter.IsEmpty() {
+		c.logApiUsage(r, appID, apiKey.ID, http.StatusForbidden, time.Since(startTime), "接口配置了行级安全策略，不能拉取变更日志")
+		render.JSON(w, r, APIResponse[any]{Status: http.StatusForbidden, Msg: "接口配置了行级安全策略，不能拉取变更日志"})
+		return
+	}
+
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+	page, err := c.sharingService.ReadChangelog(thematicInterface.ID, r.URL.Query().Get("since"), limit)
+	if err != nil {
+		status, msg := http.StatusInternalServerError, "拉取变更日志失败"
+		switch {
+		case errors.Is(err, sharing.ErrInvalidChangelogCursor):
+			status, msg = http.StatusBadRequest, err.Error()
+		case errors.Is(err, sharing.ErrChangelogNotEnabled):
+			status, msg = http.StatusNotFound, err.Error()
+		}
+		c.logApiUsage(r, appID, apiKey.ID, status, time.Since(startTime), err.Error())
+		render.JSON(w, r, APIResponse[any]{Status: status, Msg: msg})
+		return
+	}
+	page.InterfaceID = apiInterface.ID
+
+	// 主键字段按接口的脱敏规则脱敏
+	if maskingConfigs := parseMaskingRules(apiInterface.MaskingRules); len(maskingConfigs) > 0 {
+		for i := range page.Entries {
+			masked, err := c.maskSingleRecord(page.Entries[i].PK, maskingConfigs)
+			if err != nil {
+				slog.Error("变更日志主键脱敏失败", "error", err, "interface_id", apiInterface.ID)
+				continue
+			}
+			page.Entries[i].PK = masked
+		}
+	}
+
+	c.logApiUsage(r, appID, apiKey.ID, http.StatusOK, time.Since(startTime), "")
+	render.JSON(w, r, SuccessResponse("拉取变更日志成功", page))
+}
+
 // logApiUsage 记录API使用日志
 func (c *DataProxyController) logApiUsage(r *http.Request, appID, keyID string, statusCode int, duration time.Duration, errorMsg string) {
 	c.logApiUsageWithSize(r, appID, keyID, statusCode, duration, errorMsg, 0, 0)
@@ -447,6 +757,8 @@ func (c *DataProxyController) logApiUsage(r *http.Request, appID, keyID string,
 
 // logApiUsageWithSize 记录带大小信息的API使用日志
 func (c *DataProxyController) logApiUsageWithSize(r *http.Request, appID, keyID string, statusCode int, duration time.Duration, errorMsg string, requestSize, responseSize int64) {
+	metrics.ObserveSharingRequest(appID, statusCode, duration)
+
 	log := &models.ApiUsageLog{
 		ApiPath:      r.URL.Path,
 		Method:       r.Method,
@@ -675,6 +987,18 @@ func convertToSimplifiedApplicationInfo(app *models.ApiApplication) *SimplifiedA
 	return simplified
 }
 
+// setDeprecationHeaders 设置弃用响应头：Deprecation为弃用时间(RFC 9745)，Sunset为下线时间(RFC 8594)，
+// 替代接口是地址时在Link头中以successor-version给出
+func setDeprecationHeaders(w http.ResponseWriter, deprecation *models.InterfaceDeprecation) {
+	w.Header().Set("Deprecation", fmt.Sprintf("@%d", deprecation.DeprecatedAt.Unix()))
+	if deprecation.SunsetAt != nil {
+		w.Header().Set("Sunset", deprecation.SunsetAt.UTC().Format(http.TimeFormat))
+	}
+	if deprecation.Replacement != "" && !strings.ContainsAny(deprecation.Replacement, " \t<>") {
+		w.Header().Add("Link", fmt.Sprintf("<%s>; rel=\"successor-version\"", deprecation.Replacement))
+	}
+}
+
 // verifyApiKeyAccess 验证API Key是否可以访问指定的应用
 func (c *DataProxyController) verifyApiKeyAccess(apiKeyID, appID string) (bool, error) {
 	// 通过API Key获取可访问的应用列表
@@ -696,16 +1020,16 @@ func (c *DataProxyController) verifyApiKeyAccess(apiKeyID, appID string) (bool,
 // GetApplicationInfo 获取应用信息和相关接口信息
 // @Summary 获取API应用信息和接口列表
 // @Description 根据应用路径获取API应用的详细信息以及该应用下的所有接口信息，包括主题接口的字段定义
-// @Tags 数据共享服务
+// @Tags 数据访问
 // @Accept json
 // @Produce json
 // @Param app_path path string true "应用路径"
 // @Param Authorization header string true "Bearer Token格式的API Key"
 // @Success 200 {object} interface{} "获取成功"
-// @Failure 401 {object} APIResponse "未授权"
-// @Failure 404 {object} APIResponse "应用不存在"
-// @Failure 500 {object} APIResponse "服务器内部错误"
-// @Router /api/v1/{app_path} [get]
+// @Failure 401 {object} APIResponse[any] "未授权"
+// @Failure 404 {object} APIResponse[any] "应用不存在"
+// @Failure 500 {object} APIResponse[any] "服务器内部错误"
+// @Router /api/v1/share/{app_path} [get]
 func (c *DataProxyController) GetApplicationInfo(w http.ResponseWriter, r *http.Request) {
 	startTime := time.Now()
 
@@ -713,7 +1037,7 @@ func (c *DataProxyController) GetApplicationInfo(w http.ResponseWriter, r *http.
 	if r.Method != http.MethodGet {
 		c.logApiUsage(r, "", "", http.StatusMethodNotAllowed, time.Since(startTime), "不支持的HTTP方法: "+r.Method)
 		w.Header().Set("Allow", "GET")
-		render.JSON(w, r, APIResponse{
+		render.JSON(w, r, APIResponse[any]{
 			Status: http.StatusMethodNotAllowed,
 			Msg:    "仅支持GET方法获取应用信息",
 		})
@@ -724,7 +1048,7 @@ func (c *DataProxyController) GetApplicationInfo(w http.ResponseWriter, r *http.
 	appPath := chi.URLParam(r, "app_path")
 	if appPath == "" {
 		c.logApiUsage(r, "", "", http.StatusBadRequest, time.Since(startTime), "应用路径参数为空")
-		render.JSON(w, r, APIResponse{
+		render.JSON(w, r, APIResponse[any]{
 			Status: http.StatusBadRequest,
 			Msg:    "应用路径参数不能为空",
 		})
@@ -735,7 +1059,7 @@ func (c *DataProxyController) GetApplicationInfo(w http.ResponseWriter, r *http.
 	authHeader := r.Header.Get("Authorization")
 	if authHeader == "" {
 		c.logApiUsage(r, "", "", http.StatusUnauthorized, time.Since(startTime), "缺少Authorization头")
-		render.JSON(w, r, APIResponse{
+		render.JSON(w, r, APIResponse[any]{
 			Status: http.StatusUnauthorized,
 			Msg:    "缺少Authorization头",
 		})
@@ -745,7 +1069,7 @@ func (c *DataProxyController) GetApplicationInfo(w http.ResponseWriter, r *http.
 	// 解析Bearer Token
 	if !strings.HasPrefix(authHeader, "Bearer ") {
 		c.logApiUsage(r, "", "", http.StatusUnauthorized, time.Since(startTime), "无效的Authorization格式")
-		render.JSON(w, r, APIResponse{
+		render.JSON(w, r, APIResponse[any]{
 			Status: http.StatusUnauthorized,
 			Msg:    "无效的Authorization格式，请使用Bearer Token",
 		})
@@ -755,10 +1079,10 @@ func (c *DataProxyController) GetApplicationInfo(w http.ResponseWriter, r *http.
 	apiKeyValue := strings.TrimPrefix(authHeader, "Bearer ")
 
 	// 4. 验证API Key
-	apiKey, err := c.sharingService.VerifyApiKey(apiKeyValue)
+	apiKey, err := c.sharingService.AuthenticateApiKey(apiKeyValue, models.ApiKeyScopeShareRead, getClientIP(r))
 	if err != nil {
 		c.logApiUsage(r, "", "", http.StatusUnauthorized, time.Since(startTime), err.Error())
-		render.JSON(w, r, APIResponse{
+		render.JSON(w, r, APIResponse[any]{
 			Status: http.StatusUnauthorized,
 			Msg:    "API Key验证失败: " + err.Error(),
 		})
@@ -769,7 +1093,7 @@ func (c *DataProxyController) GetApplicationInfo(w http.ResponseWriter, r *http.
 	appInfo, err := c.sharingService.GetApiApplicationByApiKeyAndPath(apiKey.ID, appPath)
 	if err != nil {
 		c.logApiUsage(r, "", apiKey.ID, http.StatusNotFound, time.Since(startTime), "应用不存在、已禁用或API Key无权访问")
-		render.JSON(w, r, APIResponse{
+		render.JSON(w, r, APIResponse[any]{
 			Status: http.StatusNotFound,
 			Msg:    "应用不存在、已禁用或API Key无权访问",
 		})
@@ -781,7 +1105,7 @@ func (c *DataProxyController) GetApplicationInfo(w http.ResponseWriter, r *http.
 
 	// 8. 转换为简化的响应结构并返回
 	simplifiedInfo := convertToSimplifiedApplicationInfo(appInfo)
-	render.JSON(w, r, APIResponse{
+	render.JSON(w, r, APIResponse[any]{
 		Status: http.StatusOK,
 		Msg:    "获取应用信息成功",
 		Data:   simplifiedInfo,
@@ -791,14 +1115,14 @@ func (c *DataProxyController) GetApplicationInfo(w http.ResponseWriter, r *http.
 // GetApiApplicationByKey 通过API Key获取应用信息和相关接口信息
 // @Summary 通过API Key获取API应用信息和接口列表
 // @Description 根据API Key获取该Key所属的API应用详细信息以及该应用下的所有接口信息，包括主题接口的字段定义
-// @Tags 数据共享服务
+// @Tags 数据访问
 // @Accept json
 // @Produce json
 // @Param Authorization header string true "Bearer Token格式的API Key"
 // @Success 200 {object} interface{} "获取成功"
-// @Failure 401 {object} APIResponse "未授权"
-// @Failure 404 {object} APIResponse "应用不存在"
-// @Failure 500 {object} APIResponse "服务器内部错误"
+// @Failure 401 {object} APIResponse[any] "未授权"
+// @Failure 404 {object} APIResponse[any] "应用不存在"
+// @Failure 500 {object} APIResponse[any] "服务器内部错误"
 // @Router /api/v1/share/ [get]
 func (c *DataProxyController) GetApiApplicationByKey(w http.ResponseWriter, r *http.Request) {
 	startTime := time.Now()
@@ -807,7 +1131,7 @@ func (c *DataProxyController) GetApiApplicationByKey(w http.ResponseWriter, r *h
 	if r.Method != http.MethodGet {
 		c.logApiUsage(r, "", "", http.StatusMethodNotAllowed, time.Since(startTime), "不支持的HTTP方法: "+r.Method)
 		w.Header().Set("Allow", "GET")
-		render.JSON(w, r, APIResponse{
+		render.JSON(w, r, APIResponse[any]{
 			Status: http.StatusMethodNotAllowed,
 			Msg:    "仅支持GET方法获取应用信息",
 		})
@@ -818,7 +1142,7 @@ func (c *DataProxyController) GetApiApplicationByKey(w http.ResponseWriter, r *h
 	authHeader := r.Header.Get("Authorization")
 	if authHeader == "" {
 		c.logApiUsage(r, "", "", http.StatusUnauthorized, time.Since(startTime), "缺少Authorization头")
-		render.JSON(w, r, APIResponse{
+		render.JSON(w, r, APIResponse[any]{
 			Status: http.StatusUnauthorized,
 			Msg:    "缺少Authorization头",
 		})
@@ -828,7 +1152,7 @@ func (c *DataProxyController) GetApiApplicationByKey(w http.ResponseWriter, r *h
 	// 解析Bearer Token
 	if !strings.HasPrefix(authHeader, "Bearer ") {
 		c.logApiUsage(r, "", "", http.StatusUnauthorized, time.Since(startTime), "无效的Authorization格式")
-		render.JSON(w, r, APIResponse{
+		render.JSON(w, r, APIResponse[any]{
 			Status: http.StatusUnauthorized,
 			Msg:    "无效的Authorization格式，请使用Bearer Token",
 		})
@@ -838,10 +1162,10 @@ func (c *DataProxyController) GetApiApplicationByKey(w http.ResponseWriter, r *h
 	apiKeyValue := strings.TrimPrefix(authHeader, "Bearer ")
 
 	// 3. 验证API Key
-	apiKey, err := c.sharingService.VerifyApiKey(apiKeyValue)
+	apiKey, err := c.sharingService.AuthenticateApiKey(apiKeyValue, models.ApiKeyScopeShareRead, getClientIP(r))
 	if err != nil {
 		c.logApiUsage(r, "", "", http.StatusUnauthorized, time.Since(startTime), err.Error())
-		render.JSON(w, r, APIResponse{
+		render.JSON(w, r, APIResponse[any]{
 			Status: http.StatusUnauthorized,
 			Msg:    "API Key验证失败: " + err.Error(),
 		})
@@ -852,7 +1176,7 @@ func (c *DataProxyController) GetApiApplicationByKey(w http.ResponseWriter, r *h
 	appInfos, err := c.sharingService.GetApiApplicationsByApiKey(apiKey.ID)
 	if err != nil || len(appInfos) == 0 {
 		c.logApiUsage(r, "", apiKey.ID, http.StatusNotFound, time.Since(startTime), "该API Key无可访问的应用")
-		render.JSON(w, r, APIResponse{
+		render.JSON(w, r, APIResponse[any]{
 			Status: http.StatusNotFound,
 			Msg:    "该API Key无可访问的应用",
 		})
@@ -868,7 +1192,7 @@ func (c *DataProxyController) GetApiApplicationByKey(w http.ResponseWriter, r *h
 		simplifiedInfo := convertToSimplifiedApplicationInfo(&appInfo)
 		simplifiedInfos = append(simplifiedInfos, *simplifiedInfo)
 	}
-	render.JSON(w, r, APIResponse{
+	render.JSON(w, r, APIResponse[any]{
 		Status: http.StatusOK,
 		Msg:    "获取应用信息成功",
 		Data:   simplifiedInfos,
@@ -951,3 +1275,42 @@ func (c *DataProxyController) maskMultipleRecords(records []interface{}, masking
 
 	return maskedRecords, nil
 }
+
+// parseMaskingRules 解析共享接口配置的脱敏规则，未指定is_enabled的规则视为启用
+func parseMaskingRules(rules models.JSONB) []models.DataMaskingConfig {
+	var maskingConfigs []models.DataMaskingConfig
+	for _, ruleValue := range rules {
+		if ruleData, ok := ruleValue.(map[string]interface{}); ok {
+			var rule models.DataMaskingConfig
+
+			// 解析规则配置
+			if templateID, ok := ruleData["template_id"].(string); ok {
+				rule.TemplateID = templateID
+			}
+			if targetFields, ok := ruleData["target_fields"].([]interface{}); ok {
+				for _, field := range targetFields {
+					if fieldStr, ok := field.(string); ok {
+						rule.TargetFields = append(rule.TargetFields, fieldStr)
+					}
+				}
+			}
+			if maskingConfig, ok := ruleData["masking_config"].(map[string]interface{}); ok {
+				rule.MaskingConfig = maskingConfig
+			}
+			if applyCondition, ok := ruleData["apply_condition"].(string); ok {
+				rule.ApplyCondition = applyCondition
+			}
+			if preserveFormat, ok := ruleData["preserve_format"].(bool); ok {
+				rule.PreserveFormat = preserveFormat
+			}
+			if isEnabled, ok := ruleData["is_enabled"].(bool); ok {
+				rule.IsEnabled = isEnabled
+			} else {
+				rule.IsEnabled = true
+			}
+
+			maskingConfigs = append(maskingConfigs, rule)
+		}
+	}
+	return maskingConfigs
+}
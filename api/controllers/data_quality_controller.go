@@ -12,8 +12,14 @@
 package controllers
 
 import (
+	"context"
+	"datahub-service/service"
 	"datahub-service/service/governance"
 	"datahub-service/service/models"
+	"datahub-service/service/rbac"
+	"datahub-service/service/udf"
+	"errors"
+	"fmt"
 	"net/http"
 	"strconv"
 	"time"
@@ -43,14 +49,13 @@ func NewDataQualityController(governanceService *governance.GovernanceService) *
 // @Accept json
 // @Produce json
 // @Param rule body governance.CreateQualityRuleRequest true "数据质量规则信息"
-// @Success 201 {object} APIResponse{data=governance.QualityRuleResponse} "创建成功"
-// @Failure 400 {object} APIResponse "请求参数错误"
-// @Failure 500 {object} APIResponse "服务器内部错误"
+// @Success 201 {object} APIResponse[governance.QualityRuleResponse] "创建成功"
+// @Failure 400 {object} APIResponse[any] "请求参数错误"
+// @Failure 500 {object} APIResponse[any] "服务器内部错误"
 // @Router /data-quality/rules [post]
 func (c *DataQualityController) CreateQualityRule(w http.ResponseWriter, r *http.Request) {
 	var req governance.CreateQualityRuleRequest
-	if err := render.DecodeJSON(r.Body, &req); err != nil {
-		render.JSON(w, r, BadRequestResponse("请求参数格式错误", err))
+	if !decodeAndValidate(w, r, &req) {
 		return
 	}
 
@@ -66,8 +71,8 @@ func (c *DataQualityController) CreateQualityRule(w http.ResponseWriter, r *http
 		Tags:          req.Tags,
 	}
 
-	if err := c.governanceService.CreateQualityRule(rule); err != nil {
-		render.JSON(w, r, InternalErrorResponse("创建数据质量规则失败", err))
+	if err := c.governanceService.CreateQualityRule(r.Context(), rule); err != nil {
+		render.JSON(w, r, ruleLogicErrorResponse("创建数据质量规则失败", err))
 		return
 	}
 
@@ -88,6 +93,7 @@ func (c *DataQualityController) CreateQualityRule(w http.ResponseWriter, r *http
 		CreatedBy:     rule.CreatedBy,
 		UpdatedAt:     rule.UpdatedAt,
 		UpdatedBy:     rule.UpdatedBy,
+		RowVersion:    rule.RowVersion,
 	}
 
 	render.JSON(w, r, SuccessResponse("创建数据质量规则成功", response))
@@ -101,27 +107,22 @@ func (c *DataQualityController) CreateQualityRule(w http.ResponseWriter, r *http
 // @Produce json
 // @Param page query int false "页码" default(1)
 // @Param size query int false "每页数量" default(10)
+// @Param fields query string false "返回字段，逗号分隔，始终包含id"
+// @Param light query bool false "轻量模式，不返回rule_logic,parameters,default_config"
 // @Param type query string false "规则类型" Enums(completeness,accuracy,consistency,validity,uniqueness,timeliness,standardization)
 // @Param object_type query string false "关联对象类型" Enums(interface,thematic_interface)
-// @Success 200 {object} APIResponse{data=governance.QualityRuleListResponse} "获取成功"
-// @Failure 500 {object} APIResponse "服务器内部错误"
+// @Success 200 {object} APIResponse[governance.QualityRuleListResponse] "获取成功"
+// @Failure 500 {object} APIResponse[any] "服务器内部错误"
 // @Router /data-quality/rules [get]
 func (c *DataQualityController) GetQualityRules(w http.ResponseWriter, r *http.Request) {
-	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
-	if page <= 0 {
-		page = 1
-	}
-	size, _ := strconv.Atoi(r.URL.Query().Get("size"))
-	if size <= 0 {
-		size = 10
-	}
+	page, size := parsePagination(r)
 
 	ruleType := r.URL.Query().Get("type")
 	objectType := r.URL.Query().Get("object_type")
 
-	rules, total, err := c.governanceService.GetQualityRules(page, size, ruleType, objectType)
+	rules, total, err := c.governanceService.GetQualityRules(r.Context(), page, size, ruleType, objectType)
 	if err != nil {
-		render.JSON(w, r, InternalErrorResponse("获取数据质量规则列表失败", err))
+		render.JSON(w, r, MapErrorResponse("获取数据质量规则列表失败", err))
 		return
 	}
 
@@ -144,17 +145,17 @@ func (c *DataQualityController) GetQualityRules(w http.ResponseWriter, r *http.R
 			CreatedBy:     rule.CreatedBy,
 			UpdatedAt:     rule.UpdatedAt,
 			UpdatedBy:     rule.UpdatedBy,
+			RowVersion:    rule.RowVersion,
 		})
 	}
 
 	response := governance.QualityRuleListResponse{
-		List:  ruleResponses,
-		Total: total,
-		Page:  page,
-		Size:  size,
+		List:     ruleResponses,
+		PageMeta: models.NewPageMeta(total, page, size),
 	}
 
-	render.JSON(w, r, SuccessResponse("获取数据质量规则列表成功", response))
+	sel := parseFieldSelection(r, qualityRuleHeavyFields...)
+	render.JSON(w, r, SuccessResponse("获取数据质量规则列表成功", sel.applyToList(response)))
 }
 
 // GetQualityRuleByID 根据ID获取数据质量规则
@@ -164,16 +165,19 @@ func (c *DataQualityController) GetQualityRules(w http.ResponseWriter, r *http.R
 // @Accept json
 // @Produce json
 // @Param id path string true "规则ID"
-// @Success 200 {object} APIResponse{data=governance.QualityRuleResponse} "获取成功"
-// @Failure 404 {object} APIResponse "规则不存在"
-// @Failure 500 {object} APIResponse "服务器内部错误"
+// @Success 200 {object} APIResponse[governance.QualityRuleResponse] "获取成功"
+// @Failure 404 {object} APIResponse[any] "规则不存在"
+// @Failure 500 {object} APIResponse[any] "服务器内部错误"
 // @Router /data-quality/rules/{id} [get]
 func (c *DataQualityController) GetQualityRuleByID(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 
-	rule, err := c.governanceService.GetQualityRuleByID(id)
+	rule, err := c.governanceService.GetQualityRuleByID(r.Context(), id)
 	if err != nil {
-		render.JSON(w, r, InternalErrorResponse("数据质量规则不存在", err))
+		render.JSON(w, r, MapErrorResponse("获取数据质量规则失败", err))
+		return
+	}
+	if !visibleOrNotFound(w, r, rbac.ResourceQualityRule, rule.ID, "数据质量规则不存在") {
 		return
 	}
 
@@ -194,8 +198,11 @@ func (c *DataQualityController) GetQualityRuleByID(w http.ResponseWriter, r *htt
 		CreatedBy:     rule.CreatedBy,
 		UpdatedAt:     rule.UpdatedAt,
 		UpdatedBy:     rule.UpdatedBy,
+		RowVersion:    rule.RowVersion,
 	}
 
+	setETag(w, rule.RowVersion)
+	setLastModified(w, rule.UpdatedAt)
 	render.JSON(w, r, SuccessResponse("获取数据质量规则成功", response))
 }
 
@@ -206,18 +213,25 @@ func (c *DataQualityController) GetQualityRuleByID(w http.ResponseWriter, r *htt
 // @Accept json
 // @Produce json
 // @Param id path string true "规则ID"
+// @Param If-Match header string false "期望版本（ETag），不一致时返回409"
 // @Param updates body governance.UpdateQualityRuleRequest true "更新信息"
-// @Success 200 {object} APIResponse "更新成功"
-// @Failure 400 {object} APIResponse "请求参数错误"
-// @Failure 404 {object} APIResponse "规则不存在"
-// @Failure 500 {object} APIResponse "服务器内部错误"
+// @Success 200 {object} APIResponse[any] "更新成功"
+// @Failure 400 {object} APIResponse[any] "请求参数错误"
+// @Failure 404 {object} APIResponse[any] "规则不存在"
+// @Failure 409 {object} APIResponse[VersionConflictData] "版本冲突"
+// @Failure 500 {object} APIResponse[any] "服务器内部错误"
 // @Router /data-quality/rules/{id} [put]
 func (c *DataQualityController) UpdateQualityRule(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 
 	var req governance.UpdateQualityRuleRequest
-	if err := render.DecodeJSON(r.Body, &req); err != nil {
-		render.JSON(w, r, BadRequestResponse("请求参数格式错误", err))
+	if !decodeAndValidate(w, r, &req) {
+		return
+	}
+
+	expectedVersion, err := expectedRowVersion(r, req.RowVersion)
+	if err != nil {
+		render.JSON(w, r, BadRequestResponse(err.Error(), err))
 		return
 	}
 
@@ -241,8 +255,11 @@ func (c *DataQualityController) UpdateQualityRule(w http.ResponseWriter, r *http
 		updates["description"] = req.Description
 	}
 
-	if err := c.governanceService.UpdateQualityRule(id, updates); err != nil {
-		render.JSON(w, r, InternalErrorResponse("更新数据质量规则失败", err))
+	if err := c.governanceService.UpdateQualityRule(r.Context(), id, expectedVersion, updates); err != nil {
+		if handleVersionConflict(w, r, err) {
+			return
+		}
+		render.JSON(w, r, ruleLogicErrorResponse("更新数据质量规则失败", err))
 		return
 	}
 
@@ -256,21 +273,48 @@ func (c *DataQualityController) UpdateQualityRule(w http.ResponseWriter, r *http
 // @Accept json
 // @Produce json
 // @Param id path string true "规则ID"
-// @Success 200 {object} APIResponse "删除成功"
-// @Failure 404 {object} APIResponse "规则不存在"
-// @Failure 500 {object} APIResponse "服务器内部错误"
+// @Success 200 {object} APIResponse[any] "删除成功"
+// @Failure 404 {object} APIResponse[any] "规则不存在"
+// @Failure 500 {object} APIResponse[any] "服务器内部错误"
 // @Router /data-quality/rules/{id} [delete]
 func (c *DataQualityController) DeleteQualityRule(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 
-	if err := c.governanceService.DeleteQualityRule(id); err != nil {
-		render.JSON(w, r, InternalErrorResponse("删除数据质量规则失败", err))
+	if err := c.governanceService.DeleteQualityRule(r.Context(), id); err != nil {
+		render.JSON(w, r, MapErrorResponse("删除数据质量规则失败", err))
 		return
 	}
 
 	render.JSON(w, r, SuccessResponse("删除数据质量规则成功", nil))
 }
 
+// BatchOperateQualityRules 批量操作数据质量规则
+// @Summary 批量操作数据质量规则
+// @Description 在单个事务中批量启用、停用或删除数据质量规则，任一项失败则整体回滚，data.items为逐项结果
+// @Tags 数据质量
+// @Accept json
+// @Produce json
+// @Param request body governance.BatchOperationRequest true "批量操作请求"
+// @Success 200 {object} APIResponse[governance.BatchOperationResult] "全部成功"
+// @Failure 400 {object} APIResponse[any] "请求参数错误"
+// @Failure 409 {object} APIResponse[governance.BatchOperationResult] "存在失败项，已整体回滚"
+// @Failure 500 {object} APIResponse[any] "服务器内部错误"
+// @Router /data-quality/rules/batch [post]
+func (c *DataQualityController) BatchOperateQualityRules(w http.ResponseWriter, r *http.Request) {
+	var req governance.BatchOperationRequest
+	if !decodeAndValidate(w, r, &req) {
+		return
+	}
+
+	result, err := c.governanceService.BatchOperateQualityRules(req.Action, req.IDs)
+	if err != nil {
+		render.JSON(w, r, MapErrorResponse("批量操作数据质量规则失败", err))
+		return
+	}
+
+	render.JSON(w, r, batchOperationResponse("批量操作数据质量规则", result))
+}
+
 // === 数据脱敏规则管理 ===
 
 // CreateMaskingRule 创建数据脱敏规则
@@ -280,14 +324,13 @@ func (c *DataQualityController) DeleteQualityRule(w http.ResponseWriter, r *http
 // @Accept json
 // @Produce json
 // @Param rule body governance.CreateMaskingRuleRequest true "数据脱敏规则信息"
-// @Success 201 {object} APIResponse{data=governance.MaskingRuleResponse} "创建成功"
-// @Failure 400 {object} APIResponse "请求参数错误"
-// @Failure 500 {object} APIResponse "服务器内部错误"
+// @Success 201 {object} APIResponse[governance.MaskingRuleResponse] "创建成功"
+// @Failure 400 {object} APIResponse[any] "请求参数错误"
+// @Failure 500 {object} APIResponse[any] "服务器内部错误"
 // @Router /data-quality/masking-rules [post]
 func (c *DataQualityController) CreateMaskingRule(w http.ResponseWriter, r *http.Request) {
 	var req governance.CreateMaskingRuleRequest
-	if err := render.DecodeJSON(r.Body, &req); err != nil {
-		render.JSON(w, r, BadRequestResponse("请求参数格式错误", err))
+	if !decodeAndValidate(w, r, &req) {
 		return
 	}
 
@@ -303,8 +346,8 @@ func (c *DataQualityController) CreateMaskingRule(w http.ResponseWriter, r *http
 		Tags:          req.Tags,
 	}
 
-	if err := c.governanceService.CreateMaskingRule(rule); err != nil {
-		render.JSON(w, r, InternalErrorResponse("创建数据脱敏规则失败", err))
+	if err := c.governanceService.CreateMaskingRule(r.Context(), rule); err != nil {
+		render.JSON(w, r, MapErrorResponse("创建数据脱敏规则失败", err))
 		return
 	}
 
@@ -325,6 +368,7 @@ func (c *DataQualityController) CreateMaskingRule(w http.ResponseWriter, r *http
 		CreatedBy:     rule.CreatedBy,
 		UpdatedAt:     rule.UpdatedAt,
 		UpdatedBy:     rule.UpdatedBy,
+		RowVersion:    rule.RowVersion,
 	}
 
 	render.JSON(w, r, SuccessResponse("创建数据脱敏规则成功", response))
@@ -337,28 +381,23 @@ func (c *DataQualityController) CreateMaskingRule(w http.ResponseWriter, r *http
 // @Accept json
 // @Produce json
 // @Param page query int false "页码" default(1)
-// @Param page_size query int false "每页数量" default(10)
+// @Param size query int false "每页数量" default(10)
+// @Param fields query string false "返回字段，逗号分隔，始终包含id"
+// @Param light query bool false "轻量模式，不返回masking_logic,parameters"
 // @Param data_source query string false "数据源"
 // @Param masking_type query string false "脱敏类型" Enums(mask,replace,encrypt,pseudonymize)
-// @Success 200 {object} APIResponse{data=governance.MaskingRuleListResponse} "获取成功"
-// @Failure 500 {object} APIResponse "服务器内部错误"
+// @Success 200 {object} APIResponse[governance.MaskingRuleListResponse] "获取成功"
+// @Failure 500 {object} APIResponse[any] "服务器内部错误"
 // @Router /data-quality/masking-rules [get]
 func (c *DataQualityController) GetMaskingRules(w http.ResponseWriter, r *http.Request) {
-	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
-	if page <= 0 {
-		page = 1
-	}
-	pageSize, _ := strconv.Atoi(r.URL.Query().Get("page_size"))
-	if pageSize <= 0 {
-		pageSize = 10
-	}
+	page, pageSize := parsePagination(r)
 
 	dataSource := r.URL.Query().Get("data_source")
 	maskingType := r.URL.Query().Get("masking_type")
 
-	rules, total, err := c.governanceService.GetMaskingRules(page, pageSize, dataSource, maskingType)
+	rules, total, err := c.governanceService.GetMaskingRules(r.Context(), page, pageSize, dataSource, maskingType)
 	if err != nil {
-		render.JSON(w, r, InternalErrorResponse("获取数据脱敏规则列表失败", err))
+		render.JSON(w, r, MapErrorResponse("获取数据脱敏规则列表失败", err))
 		return
 	}
 
@@ -381,17 +420,17 @@ func (c *DataQualityController) GetMaskingRules(w http.ResponseWriter, r *http.R
 			CreatedBy:     rule.CreatedBy,
 			UpdatedAt:     rule.UpdatedAt,
 			UpdatedBy:     rule.UpdatedBy,
+			RowVersion:    rule.RowVersion,
 		})
 	}
 
 	response := governance.MaskingRuleListResponse{
-		List:  ruleResponses,
-		Total: total,
-		Page:  page,
-		Size:  pageSize,
+		List:     ruleResponses,
+		PageMeta: models.NewPageMeta(total, page, pageSize),
 	}
 
-	render.JSON(w, r, SuccessResponse("获取数据脱敏规则列表成功", response))
+	sel := parseFieldSelection(r, maskingRuleHeavyFields...)
+	render.JSON(w, r, SuccessResponse("获取数据脱敏规则列表成功", sel.applyToList(response)))
 }
 
 // GetMaskingRuleByID 根据ID获取数据脱敏规则
@@ -401,16 +440,19 @@ func (c *DataQualityController) GetMaskingRules(w http.ResponseWriter, r *http.R
 // @Accept json
 // @Produce json
 // @Param id path string true "规则ID"
-// @Success 200 {object} APIResponse{data=governance.MaskingRuleResponse} "获取成功"
-// @Failure 404 {object} APIResponse "规则不存在"
-// @Failure 500 {object} APIResponse "服务器内部错误"
+// @Success 200 {object} APIResponse[governance.MaskingRuleResponse] "获取成功"
+// @Failure 404 {object} APIResponse[any] "规则不存在"
+// @Failure 500 {object} APIResponse[any] "服务器内部错误"
 // @Router /data-quality/masking-rules/{id} [get]
 func (c *DataQualityController) GetMaskingRuleByID(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 
-	rule, err := c.governanceService.GetMaskingRuleByID(id)
+	rule, err := c.governanceService.GetMaskingRuleByID(r.Context(), id)
 	if err != nil {
-		render.JSON(w, r, InternalErrorResponse("数据脱敏规则不存在", err))
+		render.JSON(w, r, MapErrorResponse("获取数据脱敏规则失败", err))
+		return
+	}
+	if !visibleOrNotFound(w, r, rbac.ResourceMaskingRule, rule.ID, "数据脱敏规则不存在") {
 		return
 	}
 
@@ -431,8 +473,11 @@ func (c *DataQualityController) GetMaskingRuleByID(w http.ResponseWriter, r *htt
 		CreatedBy:     rule.CreatedBy,
 		UpdatedAt:     rule.UpdatedAt,
 		UpdatedBy:     rule.UpdatedBy,
+		RowVersion:    rule.RowVersion,
 	}
 
+	setETag(w, rule.RowVersion)
+	setLastModified(w, rule.UpdatedAt)
 	render.JSON(w, r, SuccessResponse("获取数据脱敏规则成功", response))
 }
 
@@ -443,18 +488,25 @@ func (c *DataQualityController) GetMaskingRuleByID(w http.ResponseWriter, r *htt
 // @Accept json
 // @Produce json
 // @Param id path string true "规则ID"
+// @Param If-Match header string false "期望版本（ETag），不一致时返回409"
 // @Param updates body governance.UpdateMaskingRuleRequest true "更新信息"
-// @Success 200 {object} APIResponse "更新成功"
-// @Failure 400 {object} APIResponse "请求参数错误"
-// @Failure 404 {object} APIResponse "规则不存在"
-// @Failure 500 {object} APIResponse "服务器内部错误"
+// @Success 200 {object} APIResponse[any] "更新成功"
+// @Failure 400 {object} APIResponse[any] "请求参数错误"
+// @Failure 404 {object} APIResponse[any] "规则不存在"
+// @Failure 409 {object} APIResponse[VersionConflictData] "版本冲突"
+// @Failure 500 {object} APIResponse[any] "服务器内部错误"
 // @Router /data-quality/masking-rules/{id} [put]
 func (c *DataQualityController) UpdateMaskingRule(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 
 	var req governance.UpdateMaskingRuleRequest
-	if err := render.DecodeJSON(r.Body, &req); err != nil {
-		render.JSON(w, r, BadRequestResponse("请求参数格式错误", err))
+	if !decodeAndValidate(w, r, &req) {
+		return
+	}
+
+	expectedVersion, err := expectedRowVersion(r, req.RowVersion)
+	if err != nil {
+		render.JSON(w, r, BadRequestResponse(err.Error(), err))
 		return
 	}
 
@@ -475,8 +527,11 @@ func (c *DataQualityController) UpdateMaskingRule(w http.ResponseWriter, r *http
 		updates["description"] = req.Description
 	}
 
-	if err := c.governanceService.UpdateMaskingRule(id, updates); err != nil {
-		render.JSON(w, r, InternalErrorResponse("更新数据脱敏规则失败", err))
+	if err := c.governanceService.UpdateMaskingRule(r.Context(), id, expectedVersion, updates); err != nil {
+		if handleVersionConflict(w, r, err) {
+			return
+		}
+		render.JSON(w, r, MapErrorResponse("更新数据脱敏规则失败", err))
 		return
 	}
 
@@ -490,21 +545,48 @@ func (c *DataQualityController) UpdateMaskingRule(w http.ResponseWriter, r *http
 // @Accept json
 // @Produce json
 // @Param id path string true "规则ID"
-// @Success 200 {object} APIResponse "删除成功"
-// @Failure 404 {object} APIResponse "规则不存在"
-// @Failure 500 {object} APIResponse "服务器内部错误"
+// @Success 200 {object} APIResponse[any] "删除成功"
+// @Failure 404 {object} APIResponse[any] "规则不存在"
+// @Failure 500 {object} APIResponse[any] "服务器内部错误"
 // @Router /data-quality/masking-rules/{id} [delete]
 func (c *DataQualityController) DeleteMaskingRule(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 
-	if err := c.governanceService.DeleteMaskingRule(id); err != nil {
-		render.JSON(w, r, InternalErrorResponse("删除数据脱敏规则失败", err))
+	if err := c.governanceService.DeleteMaskingRule(r.Context(), id); err != nil {
+		render.JSON(w, r, MapErrorResponse("删除数据脱敏规则失败", err))
 		return
 	}
 
 	render.JSON(w, r, SuccessResponse("删除数据脱敏规则成功", nil))
 }
 
+// BatchOperateMaskingRules 批量操作数据脱敏规则
+// @Summary 批量操作数据脱敏规则
+// @Description 在单个事务中批量启用、停用或删除数据脱敏规则，任一项失败则整体回滚，data.items为逐项结果
+// @Tags 数据质量
+// @Accept json
+// @Produce json
+// @Param request body governance.BatchOperationRequest true "批量操作请求"
+// @Success 200 {object} APIResponse[governance.BatchOperationResult] "全部成功"
+// @Failure 400 {object} APIResponse[any] "请求参数错误"
+// @Failure 409 {object} APIResponse[governance.BatchOperationResult] "存在失败项，已整体回滚"
+// @Failure 500 {object} APIResponse[any] "服务器内部错误"
+// @Router /data-quality/masking-rules/batch [post]
+func (c *DataQualityController) BatchOperateMaskingRules(w http.ResponseWriter, r *http.Request) {
+	var req governance.BatchOperationRequest
+	if !decodeAndValidate(w, r, &req) {
+		return
+	}
+
+	result, err := c.governanceService.BatchOperateMaskingRules(req.Action, req.IDs)
+	if err != nil {
+		render.JSON(w, r, MapErrorResponse("批量操作数据脱敏规则失败", err))
+		return
+	}
+
+	render.JSON(w, r, batchOperationResponse("批量操作数据脱敏规则", result))
+}
+
 // === 质量检查执行 ===
 
 // RunQualityCheck 执行数据质量检查
@@ -514,20 +596,19 @@ func (c *DataQualityController) DeleteMaskingRule(w http.ResponseWriter, r *http
 // @Accept json
 // @Produce json
 // @Param request body governance.RunQualityCheckRequest true "质量检查请求"
-// @Success 200 {object} APIResponse{data=governance.QualityReportResponse} "检查成功"
-// @Failure 400 {object} APIResponse "请求参数错误"
-// @Failure 500 {object} APIResponse "服务器内部错误"
+// @Success 200 {object} APIResponse[governance.QualityReportResponse] "检查成功"
+// @Failure 400 {object} APIResponse[any] "请求参数错误"
+// @Failure 500 {object} APIResponse[any] "服务器内部错误"
 // @Router /data-quality/checks [post]
 func (c *DataQualityController) RunQualityCheck(w http.ResponseWriter, r *http.Request) {
 	var req governance.RunQualityCheckRequest
-	if err := render.DecodeJSON(r.Body, &req); err != nil {
-		render.JSON(w, r, BadRequestResponse("请求参数格式错误", err))
+	if !decodeAndValidate(w, r, &req) {
 		return
 	}
 
 	report, err := c.governanceService.RunQualityCheck(req.ObjectID, req.ObjectType)
 	if err != nil {
-		render.JSON(w, r, InternalErrorResponse("执行数据质量检查失败", err))
+		render.JSON(w, r, MapErrorResponse("执行数据质量检查失败", err))
 		return
 	}
 
@@ -556,26 +637,19 @@ func (c *DataQualityController) RunQualityCheck(w http.ResponseWriter, r *http.R
 // @Accept json
 // @Produce json
 // @Param page query int false "页码" default(1)
-// @Param page_size query int false "每页数量" default(10)
+// @Param size query int false "每页数量" default(10)
 // @Param object_type query string false "对象类型" Enums(interface,thematic_interface)
-// @Success 200 {object} APIResponse{data=governance.QualityReportListResponse} "获取成功"
-// @Failure 500 {object} APIResponse "服务器内部错误"
+// @Success 200 {object} APIResponse[governance.QualityReportListResponse] "获取成功"
+// @Failure 500 {object} APIResponse[any] "服务器内部错误"
 // @Router /data-quality/reports [get]
 func (c *DataQualityController) GetQualityReports(w http.ResponseWriter, r *http.Request) {
-	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
-	if page <= 0 {
-		page = 1
-	}
-	pageSize, _ := strconv.Atoi(r.URL.Query().Get("page_size"))
-	if pageSize <= 0 {
-		pageSize = 10
-	}
+	page, pageSize := parsePagination(r)
 
 	objectType := r.URL.Query().Get("object_type")
 
 	reports, total, err := c.governanceService.GetQualityReports(page, pageSize, objectType)
 	if err != nil {
-		render.JSON(w, r, InternalErrorResponse("获取数据质量报告列表失败", err))
+		render.JSON(w, r, MapErrorResponse("获取数据质量报告列表失败", err))
 		return
 	}
 
@@ -596,10 +670,8 @@ func (c *DataQualityController) GetQualityReports(w http.ResponseWriter, r *http
 	}
 
 	response := governance.QualityReportListResponse{
-		List:  reportResponses,
-		Total: total,
-		Page:  page,
-		Size:  pageSize,
+		List:     reportResponses,
+		PageMeta: models.NewPageMeta(total, page, pageSize),
 	}
 
 	render.JSON(w, r, SuccessResponse("获取数据质量报告列表成功", response))
@@ -612,16 +684,16 @@ func (c *DataQualityController) GetQualityReports(w http.ResponseWriter, r *http
 // @Accept json
 // @Produce json
 // @Param id path string true "报告ID"
-// @Success 200 {object} APIResponse{data=governance.QualityReportResponse} "获取成功"
-// @Failure 404 {object} APIResponse "报告不存在"
-// @Failure 500 {object} APIResponse "服务器内部错误"
+// @Success 200 {object} APIResponse[governance.QualityReportResponse] "获取成功"
+// @Failure 404 {object} APIResponse[any] "报告不存在"
+// @Failure 500 {object} APIResponse[any] "服务器内部错误"
 // @Router /data-quality/reports/{id} [get]
 func (c *DataQualityController) GetQualityReportByID(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 
 	report, err := c.governanceService.GetQualityReportByID(id)
 	if err != nil {
-		render.JSON(w, r, InternalErrorResponse("数据质量报告不存在", err))
+		render.JSON(w, r, MapErrorResponse("获取数据质量报告失败", err))
 		return
 	}
 
@@ -650,14 +722,13 @@ func (c *DataQualityController) GetQualityReportByID(w http.ResponseWriter, r *h
 // @Accept json
 // @Produce json
 // @Param metadata body governance.CreateMetadataRequest true "元数据信息"
-// @Success 201 {object} APIResponse{data=governance.MetadataResponse} "创建成功"
-// @Failure 400 {object} APIResponse "请求参数错误"
-// @Failure 500 {object} APIResponse "服务器内部错误"
+// @Success 201 {object} APIResponse[governance.MetadataResponse] "创建成功"
+// @Failure 400 {object} APIResponse[any] "请求参数错误"
+// @Failure 500 {object} APIResponse[any] "服务器内部错误"
 // @Router /data-quality/metadata [post]
 func (c *DataQualityController) CreateMetadata(w http.ResponseWriter, r *http.Request) {
 	var req governance.CreateMetadataRequest
-	if err := render.DecodeJSON(r.Body, &req); err != nil {
-		render.JSON(w, r, BadRequestResponse("请求参数格式错误", err))
+	if !decodeAndValidate(w, r, &req) {
 		return
 	}
 
@@ -670,7 +741,7 @@ func (c *DataQualityController) CreateMetadata(w http.ResponseWriter, r *http.Re
 	}
 
 	if err := c.governanceService.CreateMetadata(metadata); err != nil {
-		render.JSON(w, r, InternalErrorResponse("创建元数据失败", err))
+		render.JSON(w, r, MapErrorResponse("创建元数据失败", err))
 		return
 	}
 
@@ -700,25 +771,18 @@ func (c *DataQualityController) CreateMetadata(w http.ResponseWriter, r *http.Re
 // @Param size query int false "每页数量" default(10)
 // @Param type query string false "元数据类型" Enums(technical,business,management)
 // @Param name query string false "元数据名称"
-// @Success 200 {object} APIResponse{data=governance.MetadataListResponse} "获取成功"
-// @Failure 500 {object} APIResponse "服务器内部错误"
+// @Success 200 {object} APIResponse[governance.MetadataListResponse] "获取成功"
+// @Failure 500 {object} APIResponse[any] "服务器内部错误"
 // @Router /data-quality/metadata [get]
 func (c *DataQualityController) GetMetadataList(w http.ResponseWriter, r *http.Request) {
-	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
-	if page <= 0 {
-		page = 1
-	}
-	size, _ := strconv.Atoi(r.URL.Query().Get("size"))
-	if size <= 0 {
-		size = 10
-	}
+	page, size := parsePagination(r)
 
 	metadataType := r.URL.Query().Get("type")
 	name := r.URL.Query().Get("name")
 
 	metadataList, total, err := c.governanceService.GetMetadataList(page, size, metadataType, name)
 	if err != nil {
-		render.JSON(w, r, InternalErrorResponse("获取元数据列表失败", err))
+		render.JSON(w, r, MapErrorResponse("获取元数据列表失败", err))
 		return
 	}
 
@@ -747,10 +811,8 @@ func (c *DataQualityController) GetMetadataList(w http.ResponseWriter, r *http.R
 	}
 
 	response := governance.MetadataListResponse{
-		List:  metadataResponses,
-		Total: total,
-		Page:  page,
-		Size:  size,
+		List:     metadataResponses,
+		PageMeta: models.NewPageMeta(total, page, size),
 	}
 
 	render.JSON(w, r, SuccessResponse("获取元数据列表成功", response))
@@ -763,16 +825,16 @@ func (c *DataQualityController) GetMetadataList(w http.ResponseWriter, r *http.R
 // @Accept json
 // @Produce json
 // @Param id path string true "元数据ID"
-// @Success 200 {object} APIResponse{data=governance.MetadataResponse} "获取成功"
-// @Failure 404 {object} APIResponse "元数据不存在"
-// @Failure 500 {object} APIResponse "服务器内部错误"
+// @Success 200 {object} APIResponse[governance.MetadataResponse] "获取成功"
+// @Failure 404 {object} APIResponse[any] "元数据不存在"
+// @Failure 500 {object} APIResponse[any] "服务器内部错误"
 // @Router /data-quality/metadata/{id} [get]
 func (c *DataQualityController) GetMetadataByID(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 
 	metadata, err := c.governanceService.GetMetadataByID(id)
 	if err != nil {
-		render.JSON(w, r, InternalErrorResponse("元数据不存在", err))
+		render.JSON(w, r, MapErrorResponse("获取元数据失败", err))
 		return
 	}
 
@@ -808,17 +870,16 @@ func (c *DataQualityController) GetMetadataByID(w http.ResponseWriter, r *http.R
 // @Produce json
 // @Param id path string true "元数据ID"
 // @Param updates body governance.UpdateMetadataRequest true "更新信息"
-// @Success 200 {object} APIResponse "更新成功"
-// @Failure 400 {object} APIResponse "请求参数错误"
-// @Failure 404 {object} APIResponse "元数据不存在"
-// @Failure 500 {object} APIResponse "服务器内部错误"
+// @Success 200 {object} APIResponse[any] "更新成功"
+// @Failure 400 {object} APIResponse[any] "请求参数错误"
+// @Failure 404 {object} APIResponse[any] "元数据不存在"
+// @Failure 500 {object} APIResponse[any] "服务器内部错误"
 // @Router /data-quality/metadata/{id} [put]
 func (c *DataQualityController) UpdateMetadata(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 
 	var req governance.UpdateMetadataRequest
-	if err := render.DecodeJSON(r.Body, &req); err != nil {
-		render.JSON(w, r, BadRequestResponse("请求参数格式错误", err))
+	if !decodeAndValidate(w, r, &req) {
 		return
 	}
 
@@ -834,7 +895,7 @@ func (c *DataQualityController) UpdateMetadata(w http.ResponseWriter, r *http.Re
 	}
 
 	if err := c.governanceService.UpdateMetadata(id, updates); err != nil {
-		render.JSON(w, r, InternalErrorResponse("更新元数据失败", err))
+		render.JSON(w, r, MapErrorResponse("更新元数据失败", err))
 		return
 	}
 
@@ -848,15 +909,15 @@ func (c *DataQualityController) UpdateMetadata(w http.ResponseWriter, r *http.Re
 // @Accept json
 // @Produce json
 // @Param id path string true "元数据ID"
-// @Success 200 {object} APIResponse "删除成功"
-// @Failure 404 {object} APIResponse "元数据不存在"
-// @Failure 500 {object} APIResponse "服务器内部错误"
+// @Success 200 {object} APIResponse[any] "删除成功"
+// @Failure 404 {object} APIResponse[any] "元数据不存在"
+// @Failure 500 {object} APIResponse[any] "服务器内部错误"
 // @Router /data-quality/metadata/{id} [delete]
 func (c *DataQualityController) DeleteMetadata(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 
 	if err := c.governanceService.DeleteMetadata(id); err != nil {
-		render.JSON(w, r, InternalErrorResponse("删除元数据失败", err))
+		render.JSON(w, r, MapErrorResponse("删除元数据失败", err))
 		return
 	}
 
@@ -872,14 +933,13 @@ func (c *DataQualityController) DeleteMetadata(w http.ResponseWriter, r *http.Re
 // @Accept json
 // @Produce json
 // @Param rule body governance.CreateCleansingRuleRequest true "数据清洗规则信息"
-// @Success 201 {object} APIResponse{data=governance.CleansingRuleResponse} "创建成功"
-// @Failure 400 {object} APIResponse "请求参数错误"
-// @Failure 500 {object} APIResponse "服务器内部错误"
+// @Success 201 {object} APIResponse[governance.CleansingRuleResponse] "创建成功"
+// @Failure 400 {object} APIResponse[any] "请求参数错误"
+// @Failure 500 {object} APIResponse[any] "服务器内部错误"
 // @Router /data-quality/cleansing-rules [post]
 func (c *DataQualityController) CreateCleansingRule(w http.ResponseWriter, r *http.Request) {
 	var req governance.CreateCleansingRuleRequest
-	if err := render.DecodeJSON(r.Body, &req); err != nil {
-		render.JSON(w, r, BadRequestResponse("请求参数格式错误", err))
+	if !decodeAndValidate(w, r, &req) {
 		return
 	}
 
@@ -896,8 +956,8 @@ func (c *DataQualityController) CreateCleansingRule(w http.ResponseWriter, r *ht
 		Tags:            models.JSONB(req.Tags),
 	}
 
-	if err := c.governanceService.CreateCleansingRule(rule); err != nil {
-		render.JSON(w, r, InternalErrorResponse("创建数据清洗规则失败", err))
+	if err := c.governanceService.CreateCleansingRule(r.Context(), rule); err != nil {
+		render.JSON(w, r, ruleLogicErrorResponse("创建数据清洗规则失败", err))
 		return
 	}
 
@@ -919,6 +979,7 @@ func (c *DataQualityController) CreateCleansingRule(w http.ResponseWriter, r *ht
 		CreatedBy:       rule.CreatedBy,
 		UpdatedAt:       rule.UpdatedAt,
 		UpdatedBy:       rule.UpdatedBy,
+		RowVersion:      rule.RowVersion,
 	}
 
 	render.JSON(w, r, SuccessResponse("创建数据清洗规则成功", response))
@@ -932,27 +993,22 @@ func (c *DataQualityController) CreateCleansingRule(w http.ResponseWriter, r *ht
 // @Produce json
 // @Param page query int false "页码" default(1)
 // @Param size query int false "每页数量" default(10)
+// @Param fields query string false "返回字段，逗号分隔，始终包含id"
+// @Param light query bool false "轻量模式，不返回cleansing_logic,parameters,default_config"
 // @Param rule_type query string false "规则类型" Enums(standardization,deduplication,validation,transformation,enrichment)
 // @Param target_table query string false "目标表"
-// @Success 200 {object} APIResponse{data=governance.CleansingRuleListResponse} "获取成功"
-// @Failure 500 {object} APIResponse "服务器内部错误"
+// @Success 200 {object} APIResponse[governance.CleansingRuleListResponse] "获取成功"
+// @Failure 500 {object} APIResponse[any] "服务器内部错误"
 // @Router /data-quality/cleansing-rules [get]
 func (c *DataQualityController) GetCleansingRules(w http.ResponseWriter, r *http.Request) {
-	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
-	if page <= 0 {
-		page = 1
-	}
-	size, _ := strconv.Atoi(r.URL.Query().Get("size"))
-	if size <= 0 {
-		size = 10
-	}
+	page, size := parsePagination(r)
 
 	ruleType := r.URL.Query().Get("rule_type")
 	targetTable := r.URL.Query().Get("target_table")
 
-	rules, total, err := c.governanceService.GetCleansingRules(page, size, ruleType, targetTable)
+	rules, total, err := c.governanceService.GetCleansingRules(r.Context(), page, size, ruleType, targetTable)
 	if err != nil {
-		render.JSON(w, r, InternalErrorResponse("获取数据清洗规则列表失败", err))
+		render.JSON(w, r, MapErrorResponse("获取数据清洗规则列表失败", err))
 		return
 	}
 
@@ -976,17 +1032,17 @@ func (c *DataQualityController) GetCleansingRules(w http.ResponseWriter, r *http
 			CreatedBy:       rule.CreatedBy,
 			UpdatedAt:       rule.UpdatedAt,
 			UpdatedBy:       rule.UpdatedBy,
+			RowVersion:      rule.RowVersion,
 		})
 	}
 
 	response := governance.CleansingRuleListResponse{
-		List:  ruleResponses,
-		Total: total,
-		Page:  page,
-		Size:  size,
+		List:     ruleResponses,
+		PageMeta: models.NewPageMeta(total, page, size),
 	}
 
-	render.JSON(w, r, SuccessResponse("获取数据清洗规则列表成功", response))
+	sel := parseFieldSelection(r, cleansingRuleHeavyFields...)
+	render.JSON(w, r, SuccessResponse("获取数据清洗规则列表成功", sel.applyToList(response)))
 }
 
 // GetCleansingRuleByID 根据ID获取数据清洗规则
@@ -996,16 +1052,19 @@ func (c *DataQualityController) GetCleansingRules(w http.ResponseWriter, r *http
 // @Accept json
 // @Produce json
 // @Param id path string true "规则ID"
-// @Success 200 {object} APIResponse{data=governance.CleansingRuleResponse} "获取成功"
-// @Failure 404 {object} APIResponse "规则不存在"
-// @Failure 500 {object} APIResponse "服务器内部错误"
+// @Success 200 {object} APIResponse[governance.CleansingRuleResponse] "获取成功"
+// @Failure 404 {object} APIResponse[any] "规则不存在"
+// @Failure 500 {object} APIResponse[any] "服务器内部错误"
 // @Router /data-quality/cleansing-rules/{id} [get]
 func (c *DataQualityController) GetCleansingRuleByID(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 
-	rule, err := c.governanceService.GetCleansingRuleByID(id)
+	rule, err := c.governanceService.GetCleansingRuleByID(r.Context(), id)
 	if err != nil {
-		render.JSON(w, r, InternalErrorResponse("数据清洗规则不存在", err))
+		render.JSON(w, r, MapErrorResponse("获取数据清洗规则失败", err))
+		return
+	}
+	if !visibleOrNotFound(w, r, rbac.ResourceCleansingRule, rule.ID, "数据清洗规则不存在") {
 		return
 	}
 
@@ -1027,8 +1086,11 @@ func (c *DataQualityController) GetCleansingRuleByID(w http.ResponseWriter, r *h
 		CreatedBy:       rule.CreatedBy,
 		UpdatedAt:       rule.UpdatedAt,
 		UpdatedBy:       rule.UpdatedBy,
+		RowVersion:      rule.RowVersion,
 	}
 
+	setETag(w, rule.RowVersion)
+	setLastModified(w, rule.UpdatedAt)
 	render.JSON(w, r, SuccessResponse("获取数据清洗规则成功", response))
 }
 
@@ -1039,18 +1101,25 @@ func (c *DataQualityController) GetCleansingRuleByID(w http.ResponseWriter, r *h
 // @Accept json
 // @Produce json
 // @Param id path string true "规则ID"
+// @Param If-Match header string false "期望版本（ETag），不一致时返回409"
 // @Param updates body governance.UpdateCleansingRuleRequest true "更新信息"
-// @Success 200 {object} APIResponse "更新成功"
-// @Failure 400 {object} APIResponse "请求参数错误"
-// @Failure 404 {object} APIResponse "规则不存在"
-// @Failure 500 {object} APIResponse "服务器内部错误"
+// @Success 200 {object} APIResponse[any] "更新成功"
+// @Failure 400 {object} APIResponse[any] "请求参数错误"
+// @Failure 404 {object} APIResponse[any] "规则不存在"
+// @Failure 409 {object} APIResponse[VersionConflictData] "版本冲突"
+// @Failure 500 {object} APIResponse[any] "服务器内部错误"
 // @Router /data-quality/cleansing-rules/{id} [put]
 func (c *DataQualityController) UpdateCleansingRule(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 
 	var req governance.UpdateCleansingRuleRequest
-	if err := render.DecodeJSON(r.Body, &req); err != nil {
-		render.JSON(w, r, BadRequestResponse("请求参数格式错误", err))
+	if !decodeAndValidate(w, r, &req) {
+		return
+	}
+
+	expectedVersion, err := expectedRowVersion(r, req.RowVersion)
+	if err != nil {
+		render.JSON(w, r, BadRequestResponse(err.Error(), err))
 		return
 	}
 
@@ -1074,8 +1143,11 @@ func (c *DataQualityController) UpdateCleansingRule(w http.ResponseWriter, r *ht
 		updates["is_enabled"] = *req.IsEnabled
 	}
 
-	if err := c.governanceService.UpdateCleansingRule(id, updates); err != nil {
-		render.JSON(w, r, InternalErrorResponse("更新数据清洗规则失败", err))
+	if err := c.governanceService.UpdateCleansingRule(r.Context(), id, expectedVersion, updates); err != nil {
+		if handleVersionConflict(w, r, err) {
+			return
+		}
+		render.JSON(w, r, ruleLogicErrorResponse("更新数据清洗规则失败", err))
 		return
 	}
 
@@ -1089,21 +1161,48 @@ func (c *DataQualityController) UpdateCleansingRule(w http.ResponseWriter, r *ht
 // @Accept json
 // @Produce json
 // @Param id path string true "规则ID"
-// @Success 200 {object} APIResponse "删除成功"
-// @Failure 404 {object} APIResponse "规则不存在"
-// @Failure 500 {object} APIResponse "服务器内部错误"
+// @Success 200 {object} APIResponse[any] "删除成功"
+// @Failure 404 {object} APIResponse[any] "规则不存在"
+// @Failure 500 {object} APIResponse[any] "服务器内部错误"
 // @Router /data-quality/cleansing-rules/{id} [delete]
 func (c *DataQualityController) DeleteCleansingRule(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 
-	if err := c.governanceService.DeleteCleansingRule(id); err != nil {
-		render.JSON(w, r, InternalErrorResponse("删除数据清洗规则失败", err))
+	if err := c.governanceService.DeleteCleansingRule(r.Context(), id); err != nil {
+		render.JSON(w, r, MapErrorResponse("删除数据清洗规则失败", err))
 		return
 	}
 
 	render.JSON(w, r, SuccessResponse("删除数据清洗规则成功", nil))
 }
 
+// BatchOperateCleansingRules 批量操作数据清洗规则
+// @Summary 批量操作数据清洗规则
+// @Description 在单个事务中批量启用、停用或删除数据清洗规则，任一项失败则整体回滚，data.items为逐项结果
+// @Tags 数据质量
+// @Accept json
+// @Produce json
+// @Param request body governance.BatchOperationRequest true "批量操作请求"
+// @Success 200 {object} APIResponse[governance.BatchOperationResult] "全部成功"
+// @Failure 400 {object} APIResponse[any] "请求参数错误"
+// @Failure 409 {object} APIResponse[governance.BatchOperationResult] "存在失败项，已整体回滚"
+// @Failure 500 {object} APIResponse[any] "服务器内部错误"
+// @Router /data-quality/cleansing-rules/batch [post]
+func (c *DataQualityController) BatchOperateCleansingRules(w http.ResponseWriter, r *http.Request) {
+	var req governance.BatchOperationRequest
+	if !decodeAndValidate(w, r, &req) {
+		return
+	}
+
+	result, err := c.governanceService.BatchOperateCleansingRules(req.Action, req.IDs)
+	if err != nil {
+		render.JSON(w, r, MapErrorResponse("批量操作数据清洗规则失败", err))
+		return
+	}
+
+	render.JSON(w, r, batchOperationResponse("批量操作数据清洗规则", result))
+}
+
 // === 数据质量检测任务管理 ===
 
 // CreateQualityTask 创建数据质量检测任务
@@ -1113,20 +1212,19 @@ func (c *DataQualityController) DeleteCleansingRule(w http.ResponseWriter, r *ht
 // @Accept json
 // @Produce json
 // @Param task body governance.CreateQualityTaskRequest true "质量检测任务信息"
-// @Success 201 {object} APIResponse{data=governance.QualityTaskResponse} "创建成功"
-// @Failure 400 {object} APIResponse "请求参数错误"
-// @Failure 500 {object} APIResponse "服务器内部错误"
+// @Success 201 {object} APIResponse[governance.QualityTaskResponse] "创建成功"
+// @Failure 400 {object} APIResponse[any] "请求参数错误"
+// @Failure 500 {object} APIResponse[any] "服务器内部错误"
 // @Router /data-quality/tasks [post]
 func (c *DataQualityController) CreateQualityTask(w http.ResponseWriter, r *http.Request) {
 	var req governance.CreateQualityTaskRequest
-	if err := render.DecodeJSON(r.Body, &req); err != nil {
-		render.JSON(w, r, BadRequestResponse("请求参数格式错误", err))
+	if !decodeAndValidate(w, r, &req) {
 		return
 	}
 
 	task, err := c.governanceService.CreateQualityTask(&req)
 	if err != nil {
-		render.JSON(w, r, InternalErrorResponse("创建数据质量检测任务失败", err))
+		render.JSON(w, r, MapErrorResponse("创建数据质量检测任务失败", err))
 		return
 	}
 
@@ -1145,18 +1243,11 @@ func (c *DataQualityController) CreateQualityTask(w http.ResponseWriter, r *http
 // @Param library_type query string false "库类型" Enums(thematic,basic)
 // @Param library_id query string false "库ID"
 // @Param interface_id query string false "接口ID"
-// @Success 200 {object} APIResponse{data=governance.QualityTaskListResponse} "获取成功"
-// @Failure 500 {object} APIResponse "服务器内部错误"
+// @Success 200 {object} APIResponse[governance.QualityTaskListResponse] "获取成功"
+// @Failure 500 {object} APIResponse[any] "服务器内部错误"
 // @Router /data-quality/tasks [get]
 func (c *DataQualityController) GetQualityTasks(w http.ResponseWriter, r *http.Request) {
-	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
-	if page <= 0 {
-		page = 1
-	}
-	size, _ := strconv.Atoi(r.URL.Query().Get("size"))
-	if size <= 0 {
-		size = 10
-	}
+	page, size := parsePagination(r)
 
 	status := r.URL.Query().Get("status")
 	libraryType := r.URL.Query().Get("library_type")
@@ -1165,15 +1256,13 @@ func (c *DataQualityController) GetQualityTasks(w http.ResponseWriter, r *http.R
 
 	tasks, total, err := c.governanceService.GetQualityTasks(page, size, status, libraryType, libraryID, interfaceID)
 	if err != nil {
-		render.JSON(w, r, InternalErrorResponse("获取数据质量检测任务列表失败", err))
+		render.JSON(w, r, MapErrorResponse("获取数据质量检测任务列表失败", err))
 		return
 	}
 
 	response := governance.QualityTaskListResponse{
-		List:  tasks,
-		Total: total,
-		Page:  page,
-		Size:  size,
+		List:     tasks,
+		PageMeta: models.NewPageMeta(total, page, size),
 	}
 
 	render.JSON(w, r, SuccessResponse("获取数据质量检测任务列表成功", response))
@@ -1186,43 +1275,51 @@ func (c *DataQualityController) GetQualityTasks(w http.ResponseWriter, r *http.R
 // @Accept json
 // @Produce json
 // @Param id path string true "任务ID"
-// @Success 200 {object} APIResponse{data=governance.QualityTaskResponse} "获取成功"
-// @Failure 404 {object} APIResponse "任务不存在"
-// @Failure 500 {object} APIResponse "服务器内部错误"
+// @Success 200 {object} APIResponse[governance.QualityTaskResponse] "获取成功"
+// @Failure 404 {object} APIResponse[any] "任务不存在"
+// @Failure 500 {object} APIResponse[any] "服务器内部错误"
 // @Router /data-quality/tasks/{id} [get]
 func (c *DataQualityController) GetQualityTaskByID(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 
 	task, err := c.governanceService.GetQualityTaskByID(id)
 	if err != nil {
-		render.JSON(w, r, InternalErrorResponse("数据质量检测任务不存在", err))
+		render.JSON(w, r, MapErrorResponse("获取数据质量检测任务失败", err))
 		return
 	}
 
+	setETag(w, task.RowVersion)
 	render.JSON(w, r, SuccessResponse("获取数据质量检测任务成功", task))
 }
 
 // StartQualityTask 启动数据质量检测任务
 // @Summary 启动数据质量检测任务
-// @Description 手动启动指定的数据质量检测任务
+// @Description 手动启动指定的数据质量检测任务，返回长时操作，通过/operations/{id}查询执行状态或取消
 // @Tags 数据质量
 // @Accept json
 // @Produce json
 // @Param id path string true "任务ID"
-// @Success 200 {object} APIResponse{data=governance.QualityTaskExecutionResponse} "启动成功"
-// @Failure 404 {object} APIResponse "任务不存在"
-// @Failure 500 {object} APIResponse "服务器内部错误"
+// @Success 200 {object} APIResponse[models.Operation] "启动成功"
+// @Failure 404 {object} APIResponse[any] "任务不存在"
+// @Failure 500 {object} APIResponse[any] "服务器内部错误"
 // @Router /data-quality/tasks/{id}/start [post]
 func (c *DataQualityController) StartQualityTask(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 
-	execution, err := c.governanceService.StartQualityTask(id)
+	op, err := service.GlobalOperationService.Start(r.Context(), models.OperationKindQualityTask, id, getCurrentUsername(r),
+		func(ctx context.Context) (string, error) {
+			execution, err := c.governanceService.StartQualityTask(id)
+			if err != nil {
+				return "", err
+			}
+			return execution.ID, nil
+		})
 	if err != nil {
-		render.JSON(w, r, InternalErrorResponse("启动数据质量检测任务失败", err))
+		render.JSON(w, r, MapErrorResponse("启动数据质量检测任务失败", err))
 		return
 	}
 
-	render.JSON(w, r, SuccessResponse("启动数据质量检测任务成功", execution))
+	render.JSON(w, r, SuccessResponse("启动数据质量检测任务成功", op))
 }
 
 // StopQualityTask 停止数据质量检测任务
@@ -1232,15 +1329,15 @@ func (c *DataQualityController) StartQualityTask(w http.ResponseWriter, r *http.
 // @Accept json
 // @Produce json
 // @Param id path string true "任务ID"
-// @Success 200 {object} APIResponse "停止成功"
-// @Failure 404 {object} APIResponse "任务不存在"
-// @Failure 500 {object} APIResponse "服务器内部错误"
+// @Success 200 {object} APIResponse[any] "停止成功"
+// @Failure 404 {object} APIResponse[any] "任务不存在"
+// @Failure 500 {object} APIResponse[any] "服务器内部错误"
 // @Router /data-quality/tasks/{id}/stop [post]
 func (c *DataQualityController) StopQualityTask(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 
 	if err := c.governanceService.StopQualityTask(id); err != nil {
-		render.JSON(w, r, InternalErrorResponse("停止数据质量检测任务失败", err))
+		render.JSON(w, r, MapErrorResponse("停止数据质量检测任务失败", err))
 		return
 	}
 
@@ -1256,31 +1353,22 @@ func (c *DataQualityController) StopQualityTask(w http.ResponseWriter, r *http.R
 // @Param id path string true "任务ID"
 // @Param page query int false "页码" default(1)
 // @Param size query int false "每页数量" default(10)
-// @Success 200 {object} APIResponse{data=governance.QualityTaskExecutionListResponse} "获取成功"
-// @Failure 500 {object} APIResponse "服务器内部错误"
+// @Success 200 {object} APIResponse[governance.QualityTaskExecutionListResponse] "获取成功"
+// @Failure 500 {object} APIResponse[any] "服务器内部错误"
 // @Router /data-quality/tasks/{id}/executions [get]
 func (c *DataQualityController) GetQualityTaskExecutions(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
-	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
-	if page <= 0 {
-		page = 1
-	}
-	size, _ := strconv.Atoi(r.URL.Query().Get("size"))
-	if size <= 0 {
-		size = 10
-	}
+	page, size := parsePagination(r)
 
 	executions, total, err := c.governanceService.GetQualityTaskExecutions(id, page, size)
 	if err != nil {
-		render.JSON(w, r, InternalErrorResponse("获取数据质量检测任务执行记录失败", err))
+		render.JSON(w, r, MapErrorResponse("获取数据质量检测任务执行记录失败", err))
 		return
 	}
 
 	response := governance.QualityTaskExecutionListResponse{
-		List:  executions,
-		Total: total,
-		Page:  page,
-		Size:  size,
+		List:     executions,
+		PageMeta: models.NewPageMeta(total, page, size),
 	}
 
 	render.JSON(w, r, SuccessResponse("获取数据质量检测任务执行记录成功", response))
@@ -1293,23 +1381,34 @@ func (c *DataQualityController) GetQualityTaskExecutions(w http.ResponseWriter,
 // @Accept json
 // @Produce json
 // @Param id path string true "任务ID"
+// @Param If-Match header string false "期望版本（ETag），不一致时返回409"
 // @Param updates body governance.UpdateQualityTaskRequest true "更新信息"
-// @Success 200 {object} APIResponse "更新成功"
-// @Failure 400 {object} APIResponse "请求参数错误"
-// @Failure 404 {object} APIResponse "任务不存在"
-// @Failure 500 {object} APIResponse "服务器内部错误"
+// @Success 200 {object} APIResponse[any] "更新成功"
+// @Failure 400 {object} APIResponse[any] "请求参数错误"
+// @Failure 404 {object} APIResponse[any] "任务不存在"
+// @Failure 409 {object} APIResponse[VersionConflictData] "版本冲突"
+// @Failure 500 {object} APIResponse[any] "服务器内部错误"
 // @Router /data-quality/tasks/{id} [put]
 func (c *DataQualityController) UpdateQualityTask(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 
 	var req governance.UpdateQualityTaskRequest
-	if err := render.DecodeJSON(r.Body, &req); err != nil {
-		render.JSON(w, r, BadRequestResponse("请求参数格式错误", err))
+	if !decodeAndValidate(w, r, &req) {
+		return
+	}
+
+	expectedVersion, err := expectedRowVersion(r, req.RowVersion)
+	if err != nil {
+		render.JSON(w, r, BadRequestResponse(err.Error(), err))
 		return
 	}
+	req.RowVersion = expectedVersion
 
 	if err := c.governanceService.UpdateQualityTask(id, &req); err != nil {
-		render.JSON(w, r, InternalErrorResponse("更新数据质量检测任务失败", err))
+		if handleVersionConflict(w, r, err) {
+			return
+		}
+		render.JSON(w, r, MapErrorResponse("更新数据质量检测任务失败", err))
 		return
 	}
 
@@ -1323,21 +1422,48 @@ func (c *DataQualityController) UpdateQualityTask(w http.ResponseWriter, r *http
 // @Accept json
 // @Produce json
 // @Param id path string true "任务ID"
-// @Success 200 {object} APIResponse "删除成功"
-// @Failure 404 {object} APIResponse "任务不存在"
-// @Failure 500 {object} APIResponse "服务器内部错误"
+// @Success 200 {object} APIResponse[any] "删除成功"
+// @Failure 404 {object} APIResponse[any] "任务不存在"
+// @Failure 500 {object} APIResponse[any] "服务器内部错误"
 // @Router /data-quality/tasks/{id} [delete]
 func (c *DataQualityController) DeleteQualityTask(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 
 	if err := c.governanceService.DeleteQualityTask(id); err != nil {
-		render.JSON(w, r, InternalErrorResponse("删除数据质量检测任务失败", err))
+		render.JSON(w, r, MapErrorResponse("删除数据质量检测任务失败", err))
 		return
 	}
 
 	render.JSON(w, r, SuccessResponse("删除数据质量检测任务成功", nil))
 }
 
+// BatchOperateQualityTasks 批量操作数据质量检测任务
+// @Summary 批量操作数据质量检测任务
+// @Description 在单个事务中批量启用、停用或删除数据质量检测任务，任一项失败则整体回滚，data.items为逐项结果；正在运行的任务不能删除
+// @Tags 数据质量
+// @Accept json
+// @Produce json
+// @Param request body governance.BatchOperationRequest true "批量操作请求"
+// @Success 200 {object} APIResponse[governance.BatchOperationResult] "全部成功"
+// @Failure 400 {object} APIResponse[any] "请求参数错误"
+// @Failure 409 {object} APIResponse[governance.BatchOperationResult] "存在失败项，已整体回滚"
+// @Failure 500 {object} APIResponse[any] "服务器内部错误"
+// @Router /data-quality/tasks/batch [post]
+func (c *DataQualityController) BatchOperateQualityTasks(w http.ResponseWriter, r *http.Request) {
+	var req governance.BatchOperationRequest
+	if !decodeAndValidate(w, r, &req) {
+		return
+	}
+
+	result, err := c.governanceService.BatchOperateQualityTasks(req.Action, req.IDs)
+	if err != nil {
+		render.JSON(w, r, MapErrorResponse("批量操作数据质量检测任务失败", err))
+		return
+	}
+
+	render.JSON(w, r, batchOperationResponse("批量操作数据质量检测任务", result))
+}
+
 // === 数据血缘管理 ===
 
 // CreateDataLineage 创建数据血缘关系
@@ -1347,20 +1473,19 @@ func (c *DataQualityController) DeleteQualityTask(w http.ResponseWriter, r *http
 // @Accept json
 // @Produce json
 // @Param lineage body governance.CreateDataLineageRequest true "数据血缘信息"
-// @Success 201 {object} APIResponse{data=governance.DataLineageResponse} "创建成功"
-// @Failure 400 {object} APIResponse "请求参数错误"
-// @Failure 500 {object} APIResponse "服务器内部错误"
+// @Success 201 {object} APIResponse[governance.DataLineageResponse] "创建成功"
+// @Failure 400 {object} APIResponse[any] "请求参数错误"
+// @Failure 500 {object} APIResponse[any] "服务器内部错误"
 // @Router /data-quality/data-lineage [post]
 func (c *DataQualityController) CreateDataLineage(w http.ResponseWriter, r *http.Request) {
 	var req governance.CreateDataLineageRequest
-	if err := render.DecodeJSON(r.Body, &req); err != nil {
-		render.JSON(w, r, BadRequestResponse("请求参数格式错误", err))
+	if !decodeAndValidate(w, r, &req) {
 		return
 	}
 
 	lineage, err := c.governanceService.CreateDataLineage(&req)
 	if err != nil {
-		render.JSON(w, r, InternalErrorResponse("创建数据血缘关系失败", err))
+		render.JSON(w, r, MapErrorResponse("创建数据血缘关系失败", err))
 		return
 	}
 
@@ -1377,8 +1502,8 @@ func (c *DataQualityController) CreateDataLineage(w http.ResponseWriter, r *http
 // @Param object_type query string false "对象类型" Enums(table,interface,thematic_interface)
 // @Param direction query string false "血缘方向" Enums(upstream,downstream,both) default(both)
 // @Param depth query int false "血缘深度" default(3)
-// @Success 200 {object} APIResponse{data=governance.DataLineageGraphResponse} "获取成功"
-// @Failure 500 {object} APIResponse "服务器内部错误"
+// @Success 200 {object} APIResponse[governance.DataLineageGraphResponse] "获取成功"
+// @Failure 500 {object} APIResponse[any] "服务器内部错误"
 // @Router /data-quality/data-lineage/{object_id} [get]
 func (c *DataQualityController) GetDataLineage(w http.ResponseWriter, r *http.Request) {
 	objectID := chi.URLParam(r, "object_id")
@@ -1394,7 +1519,7 @@ func (c *DataQualityController) GetDataLineage(w http.ResponseWriter, r *http.Re
 
 	lineageGraph, err := c.governanceService.GetDataLineage(objectID, objectType, direction, depth)
 	if err != nil {
-		render.JSON(w, r, InternalErrorResponse("获取数据血缘图失败", err))
+		render.JSON(w, r, MapErrorResponse("获取数据血缘图失败", err))
 		return
 	}
 
@@ -1410,23 +1535,16 @@ func (c *DataQualityController) GetDataLineage(w http.ResponseWriter, r *http.Re
 // @Accept json
 // @Produce json
 // @Param page query int false "页码" default(1)
-// @Param page_size query int false "每页数量" default(10)
+// @Param size query int false "每页数量" default(10)
 // @Param operation_type query string false "操作类型"
 // @Param object_type query string false "对象类型"
 // @Param start_time query string false "开始时间" format(date-time)
 // @Param end_time query string false "结束时间" format(date-time)
-// @Success 200 {object} APIResponse{data=governance.SystemLogListResponse} "获取成功"
-// @Failure 500 {object} APIResponse "服务器内部错误"
+// @Success 200 {object} APIResponse[governance.SystemLogListResponse] "获取成功"
+// @Failure 500 {object} APIResponse[any] "服务器内部错误"
 // @Router /data-quality/system-logs [get]
 func (c *DataQualityController) GetSystemLogs(w http.ResponseWriter, r *http.Request) {
-	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
-	if page <= 0 {
-		page = 1
-	}
-	pageSize, _ := strconv.Atoi(r.URL.Query().Get("page_size"))
-	if pageSize <= 0 {
-		pageSize = 10
-	}
+	page, pageSize := parsePagination(r)
 
 	operationType := r.URL.Query().Get("operation_type")
 	objectType := r.URL.Query().Get("object_type")
@@ -1445,7 +1563,7 @@ func (c *DataQualityController) GetSystemLogs(w http.ResponseWriter, r *http.Req
 
 	logs, total, err := c.governanceService.GetSystemLogs(page, pageSize, operationType, objectType, startTime, endTime)
 	if err != nil {
-		render.JSON(w, r, InternalErrorResponse("获取系统日志列表失败", err))
+		render.JSON(w, r, MapErrorResponse("获取系统日志列表失败", err))
 		return
 	}
 
@@ -1482,10 +1600,8 @@ func (c *DataQualityController) GetSystemLogs(w http.ResponseWriter, r *http.Req
 	}
 
 	response := governance.SystemLogListResponse{
-		List:  logResponses,
-		Total: total,
-		Page:  page,
-		Size:  pageSize,
+		List:     logResponses,
+		PageMeta: models.NewPageMeta(total, page, pageSize),
 	}
 
 	render.JSON(w, r, SuccessResponse("获取系统日志列表成功", response))
@@ -1504,18 +1620,11 @@ func (c *DataQualityController) GetSystemLogs(w http.ResponseWriter, r *http.Req
 // @Param rule_type query string false "规则类型" Enums(completeness,accuracy,consistency,validity,uniqueness,timeliness,standardization)
 // @Param category query string false "分类" Enums(basic_quality,data_cleansing,data_validation)
 // @Param is_built_in query bool false "是否为内置模板"
-// @Success 200 {object} APIResponse{data=governance.QualityRuleTemplateListResponse} "获取成功"
-// @Failure 500 {object} APIResponse "服务器内部错误"
+// @Success 200 {object} APIResponse[governance.QualityRuleTemplateListResponse] "获取成功"
+// @Failure 500 {object} APIResponse[any] "服务器内部错误"
 // @Router /data-quality/templates/quality-rules [get]
 func (c *DataQualityController) GetQualityRuleTemplates(w http.ResponseWriter, r *http.Request) {
-	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
-	if page <= 0 {
-		page = 1
-	}
-	size, _ := strconv.Atoi(r.URL.Query().Get("size"))
-	if size <= 0 {
-		size = 10
-	}
+	page, size := parsePagination(r)
 
 	ruleType := r.URL.Query().Get("rule_type")
 	category := r.URL.Query().Get("category")
@@ -1529,7 +1638,7 @@ func (c *DataQualityController) GetQualityRuleTemplates(w http.ResponseWriter, r
 	templateService := c.governanceService.GetTemplateService()
 	templates, total, err := templateService.GetQualityRuleTemplates(page, size, ruleType, category, isBuiltIn)
 	if err != nil {
-		render.JSON(w, r, InternalErrorResponse("获取数据质量规则模板列表失败", err))
+		render.JSON(w, r, MapErrorResponse("获取数据质量规则模板列表失败", err))
 		return
 	}
 
@@ -1556,10 +1665,8 @@ func (c *DataQualityController) GetQualityRuleTemplates(w http.ResponseWriter, r
 	}
 
 	response := governance.QualityRuleTemplateListResponse{
-		List:  templateResponses,
-		Total: total,
-		Page:  page,
-		Size:  size,
+		List:     templateResponses,
+		PageMeta: models.NewPageMeta(total, page, size),
 	}
 
 	render.JSON(w, r, SuccessResponse("获取数据质量规则模板列表成功", response))
@@ -1577,18 +1684,11 @@ func (c *DataQualityController) GetQualityRuleTemplates(w http.ResponseWriter, r
 // @Param category query string false "分类" Enums(personal_info,financial,medical,business,custom)
 // @Param security_level query string false "安全级别" Enums(low,medium,high,critical)
 // @Param is_built_in query bool false "是否为内置模板"
-// @Success 200 {object} APIResponse{data=governance.DataMaskingTemplateListResponse} "获取成功"
-// @Failure 500 {object} APIResponse "服务器内部错误"
+// @Success 200 {object} APIResponse[governance.DataMaskingTemplateListResponse] "获取成功"
+// @Failure 500 {object} APIResponse[any] "服务器内部错误"
 // @Router /data-quality/templates/masking-rules [get]
 func (c *DataQualityController) GetDataMaskingTemplates(w http.ResponseWriter, r *http.Request) {
-	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
-	if page <= 0 {
-		page = 1
-	}
-	size, _ := strconv.Atoi(r.URL.Query().Get("size"))
-	if size <= 0 {
-		size = 10
-	}
+	page, size := parsePagination(r)
 
 	maskingType := r.URL.Query().Get("masking_type")
 	category := r.URL.Query().Get("category")
@@ -1603,7 +1703,7 @@ func (c *DataQualityController) GetDataMaskingTemplates(w http.ResponseWriter, r
 	templateService := c.governanceService.GetTemplateService()
 	templates, total, err := templateService.GetDataMaskingTemplates(page, size, maskingType, category, securityLevel, isBuiltIn)
 	if err != nil {
-		render.JSON(w, r, InternalErrorResponse("获取数据脱敏模板列表失败", err))
+		render.JSON(w, r, MapErrorResponse("获取数据脱敏模板列表失败", err))
 		return
 	}
 
@@ -1632,10 +1732,8 @@ func (c *DataQualityController) GetDataMaskingTemplates(w http.ResponseWriter, r
 	}
 
 	response := governance.DataMaskingTemplateListResponse{
-		List:  templateResponses,
-		Total: total,
-		Page:  page,
-		Size:  size,
+		List:     templateResponses,
+		PageMeta: models.NewPageMeta(total, page, size),
 	}
 
 	render.JSON(w, r, SuccessResponse("获取数据脱敏模板列表成功", response))
@@ -1652,18 +1750,11 @@ func (c *DataQualityController) GetDataMaskingTemplates(w http.ResponseWriter, r
 // @Param rule_type query string false "规则类型" Enums(standardization,deduplication,validation,transformation,enrichment)
 // @Param category query string false "分类" Enums(data_format,data_quality,data_integrity)
 // @Param is_built_in query bool false "是否为内置模板"
-// @Success 200 {object} APIResponse{data=governance.DataCleansingTemplateListResponse} "获取成功"
-// @Failure 500 {object} APIResponse "服务器内部错误"
+// @Success 200 {object} APIResponse[governance.DataCleansingTemplateListResponse] "获取成功"
+// @Failure 500 {object} APIResponse[any] "服务器内部错误"
 // @Router /data-quality/templates/cleansing-rules [get]
 func (c *DataQualityController) GetDataCleansingTemplates(w http.ResponseWriter, r *http.Request) {
-	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
-	if page <= 0 {
-		page = 1
-	}
-	size, _ := strconv.Atoi(r.URL.Query().Get("size"))
-	if size <= 0 {
-		size = 10
-	}
+	page, size := parsePagination(r)
 
 	ruleType := r.URL.Query().Get("rule_type")
 	category := r.URL.Query().Get("category")
@@ -1677,7 +1768,7 @@ func (c *DataQualityController) GetDataCleansingTemplates(w http.ResponseWriter,
 	templateService := c.governanceService.GetTemplateService()
 	templates, total, err := templateService.GetDataCleansingTemplates(page, size, ruleType, category, isBuiltIn)
 	if err != nil {
-		render.JSON(w, r, InternalErrorResponse("获取数据清洗模板列表失败", err))
+		render.JSON(w, r, MapErrorResponse("获取数据清洗模板列表失败", err))
 		return
 	}
 
@@ -1706,10 +1797,8 @@ func (c *DataQualityController) GetDataCleansingTemplates(w http.ResponseWriter,
 	}
 
 	response := governance.DataCleansingTemplateListResponse{
-		List:  templateResponses,
-		Total: total,
-		Page:  page,
-		Size:  size,
+		List:     templateResponses,
+		PageMeta: models.NewPageMeta(total, page, size),
 	}
 
 	render.JSON(w, r, SuccessResponse("获取数据清洗模板列表成功", response))
@@ -1724,20 +1813,19 @@ func (c *DataQualityController) GetDataCleansingTemplates(w http.ResponseWriter,
 // @Accept json
 // @Produce json
 // @Param request body governance.TestQualityRuleRequest true "质量规则测试请求"
-// @Success 200 {object} APIResponse{data=governance.TestRuleResponse} "测试成功"
-// @Failure 400 {object} APIResponse "请求参数错误"
-// @Failure 500 {object} APIResponse "服务器内部错误"
+// @Success 200 {object} APIResponse[governance.TestRuleResponse] "测试成功"
+// @Failure 400 {object} APIResponse[any] "请求参数错误"
+// @Failure 500 {object} APIResponse[any] "服务器内部错误"
 // @Router /data-quality/test/quality-rule [post]
 func (c *DataQualityController) TestQualityRule(w http.ResponseWriter, r *http.Request) {
 	var req governance.TestQualityRuleRequest
-	if err := render.DecodeJSON(r.Body, &req); err != nil {
-		render.JSON(w, r, BadRequestResponse("请求参数格式错误", err))
+	if !decodeAndValidate(w, r, &req) {
 		return
 	}
 
-	result, err := c.governanceService.TestQualityRule(&req)
+	result, err := c.governanceService.TestQualityRule(r.Context(), &req)
 	if err != nil {
-		render.JSON(w, r, InternalErrorResponse("测试质量规则失败", err))
+		render.JSON(w, r, MapErrorResponse("测试质量规则失败", err))
 		return
 	}
 
@@ -1751,20 +1839,19 @@ func (c *DataQualityController) TestQualityRule(w http.ResponseWriter, r *http.R
 // @Accept json
 // @Produce json
 // @Param request body governance.TestMaskingRuleRequest true "脱敏规则测试请求"
-// @Success 200 {object} APIResponse{data=governance.TestRuleResponse} "测试成功"
-// @Failure 400 {object} APIResponse "请求参数错误"
-// @Failure 500 {object} APIResponse "服务器内部错误"
+// @Success 200 {object} APIResponse[governance.TestRuleResponse] "测试成功"
+// @Failure 400 {object} APIResponse[any] "请求参数错误"
+// @Failure 500 {object} APIResponse[any] "服务器内部错误"
 // @Router /data-quality/test/masking-rule [post]
 func (c *DataQualityController) TestMaskingRule(w http.ResponseWriter, r *http.Request) {
 	var req governance.TestMaskingRuleRequest
-	if err := render.DecodeJSON(r.Body, &req); err != nil {
-		render.JSON(w, r, BadRequestResponse("请求参数格式错误", err))
+	if !decodeAndValidate(w, r, &req) {
 		return
 	}
 
-	result, err := c.governanceService.TestMaskingRule(&req)
+	result, err := c.governanceService.TestMaskingRule(r.Context(), &req)
 	if err != nil {
-		render.JSON(w, r, InternalErrorResponse("测试脱敏规则失败", err))
+		render.JSON(w, r, MapErrorResponse("测试脱敏规则失败", err))
 		return
 	}
 
@@ -1778,20 +1865,19 @@ func (c *DataQualityController) TestMaskingRule(w http.ResponseWriter, r *http.R
 // @Accept json
 // @Produce json
 // @Param request body governance.TestCleansingRuleRequest true "清洗规则测试请求"
-// @Success 200 {object} APIResponse{data=governance.TestRuleResponse} "测试成功"
-// @Failure 400 {object} APIResponse "请求参数错误"
-// @Failure 500 {object} APIResponse "服务器内部错误"
+// @Success 200 {object} APIResponse[governance.TestRuleResponse] "测试成功"
+// @Failure 400 {object} APIResponse[any] "请求参数错误"
+// @Failure 500 {object} APIResponse[any] "服务器内部错误"
 // @Router /data-quality/test/cleansing-rule [post]
 func (c *DataQualityController) TestCleansingRule(w http.ResponseWriter, r *http.Request) {
 	var req governance.TestCleansingRuleRequest
-	if err := render.DecodeJSON(r.Body, &req); err != nil {
-		render.JSON(w, r, BadRequestResponse("请求参数格式错误", err))
+	if !decodeAndValidate(w, r, &req) {
 		return
 	}
 
-	result, err := c.governanceService.TestCleansingRule(&req)
+	result, err := c.governanceService.TestCleansingRule(r.Context(), &req)
 	if err != nil {
-		render.JSON(w, r, InternalErrorResponse("测试清洗规则失败", err))
+		render.JSON(w, r, MapErrorResponse("测试清洗规则失败", err))
 		return
 	}
 
@@ -1805,20 +1891,19 @@ func (c *DataQualityController) TestCleansingRule(w http.ResponseWriter, r *http
 // @Accept json
 // @Produce json
 // @Param request body governance.TestBatchRulesRequest true "批量规则测试请求"
-// @Success 200 {object} APIResponse{data=governance.TestRuleResponse} "测试成功"
-// @Failure 400 {object} APIResponse "请求参数错误"
-// @Failure 500 {object} APIResponse "服务器内部错误"
+// @Success 200 {object} APIResponse[governance.TestRuleResponse] "测试成功"
+// @Failure 400 {object} APIResponse[any] "请求参数错误"
+// @Failure 500 {object} APIResponse[any] "服务器内部错误"
 // @Router /data-quality/test/batch-rules [post]
 func (c *DataQualityController) TestBatchRules(w http.ResponseWriter, r *http.Request) {
 	var req governance.TestBatchRulesRequest
-	if err := render.DecodeJSON(r.Body, &req); err != nil {
-		render.JSON(w, r, BadRequestResponse("请求参数格式错误", err))
+	if !decodeAndValidate(w, r, &req) {
 		return
 	}
 
-	result, err := c.governanceService.TestBatchRules(&req)
+	result, err := c.governanceService.TestBatchRules(r.Context(), &req)
 	if err != nil {
-		render.JSON(w, r, InternalErrorResponse("批量测试规则失败", err))
+		render.JSON(w, r, MapErrorResponse("批量测试规则失败", err))
 		return
 	}
 
@@ -1832,20 +1917,19 @@ func (c *DataQualityController) TestBatchRules(w http.ResponseWriter, r *http.Re
 // @Accept json
 // @Produce json
 // @Param request body governance.TestRulePreviewRequest true "规则预览请求"
-// @Success 200 {object} APIResponse{data=governance.TestRulePreviewResponse} "预览成功"
-// @Failure 400 {object} APIResponse "请求参数错误"
-// @Failure 500 {object} APIResponse "服务器内部错误"
+// @Success 200 {object} APIResponse[governance.TestRulePreviewResponse] "预览成功"
+// @Failure 400 {object} APIResponse[any] "请求参数错误"
+// @Failure 500 {object} APIResponse[any] "服务器内部错误"
 // @Router /data-quality/test/rule-preview [post]
 func (c *DataQualityController) TestRulePreview(w http.ResponseWriter, r *http.Request) {
 	var req governance.TestRulePreviewRequest
-	if err := render.DecodeJSON(r.Body, &req); err != nil {
-		render.JSON(w, r, BadRequestResponse("请求参数格式错误", err))
+	if !decodeAndValidate(w, r, &req) {
 		return
 	}
 
-	result, err := c.governanceService.TestRulePreview(&req)
+	result, err := c.governanceService.TestRulePreview(r.Context(), &req)
 	if err != nil {
-		render.JSON(w, r, InternalErrorResponse("预览规则执行效果失败", err))
+		render.JSON(w, r, MapErrorResponse("预览规则执行效果失败", err))
 		return
 	}
 
@@ -1866,8 +1950,8 @@ func (c *DataQualityController) TestRulePreview(w http.ResponseWriter, r *http.R
 // @Param severity query string false "严重程度" Enums(low,medium,high,critical)
 // @Param page query int false "页码" default(1)
 // @Param size query int false "每页数量" default(10)
-// @Success 200 {object} APIResponse{data=governance.QualityIssueRecordListResponse} "获取成功"
-// @Failure 500 {object} APIResponse "服务器内部错误"
+// @Success 200 {object} APIResponse[governance.QualityIssueRecordListResponse] "获取成功"
+// @Failure 500 {object} APIResponse[any] "服务器内部错误"
 // @Router /data-quality/issue-records [get]
 func (c *DataQualityController) GetQualityIssueRecords(w http.ResponseWriter, r *http.Request) {
 	taskID := r.URL.Query().Get("task_id")
@@ -1875,26 +1959,17 @@ func (c *DataQualityController) GetQualityIssueRecords(w http.ResponseWriter, r
 	fieldName := r.URL.Query().Get("field_name")
 	severity := r.URL.Query().Get("severity")
 
-	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
-	if page <= 0 {
-		page = 1
-	}
-	size, _ := strconv.Atoi(r.URL.Query().Get("size"))
-	if size <= 0 {
-		size = 10
-	}
+	page, size := parsePagination(r)
 
 	records, total, err := c.governanceService.GetQualityIssueRecords(taskID, executionID, page, size, fieldName, severity)
 	if err != nil {
-		render.JSON(w, r, InternalErrorResponse("获取质量问题记录列表失败", err))
+		render.JSON(w, r, MapErrorResponse("获取质量问题记录列表失败", err))
 		return
 	}
 
 	response := governance.QualityIssueRecordListResponse{
-		List:  records,
-		Total: total,
-		Page:  page,
-		Size:  size,
+		List:     records,
+		PageMeta: models.NewPageMeta(total, page, size),
 	}
 
 	render.JSON(w, r, SuccessResponse("获取质量问题记录列表成功", response))
@@ -1912,8 +1987,8 @@ func (c *DataQualityController) GetQualityIssueRecords(w http.ResponseWriter, r
 // @Param severity query string false "严重程度" Enums(low,medium,high,critical)
 // @Param page query int false "页码" default(1)
 // @Param size query int false "每页数量" default(10)
-// @Success 200 {object} APIResponse{data=governance.QualityIssueRecordListResponse} "获取成功"
-// @Failure 500 {object} APIResponse "服务器内部错误"
+// @Success 200 {object} APIResponse[governance.QualityIssueRecordListResponse] "获取成功"
+// @Failure 500 {object} APIResponse[any] "服务器内部错误"
 // @Router /data-quality/tasks/{id}/issue-records [get]
 func (c *DataQualityController) GetTaskIssueRecords(w http.ResponseWriter, r *http.Request) {
 	taskID := chi.URLParam(r, "id")
@@ -1921,27 +1996,167 @@ func (c *DataQualityController) GetTaskIssueRecords(w http.ResponseWriter, r *ht
 	fieldName := r.URL.Query().Get("field_name")
 	severity := r.URL.Query().Get("severity")
 
-	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
-	if page <= 0 {
-		page = 1
-	}
-	size, _ := strconv.Atoi(r.URL.Query().Get("size"))
-	if size <= 0 {
-		size = 10
-	}
+	page, size := parsePagination(r)
 
 	records, total, err := c.governanceService.GetQualityIssueRecords(taskID, executionID, page, size, fieldName, severity)
 	if err != nil {
-		render.JSON(w, r, InternalErrorResponse("获取质量问题记录失败", err))
+		render.JSON(w, r, MapErrorResponse("获取质量问题记录失败", err))
 		return
 	}
 
 	response := governance.QualityIssueRecordListResponse{
-		List:  records,
-		Total: total,
-		Page:  page,
-		Size:  size,
+		List:     records,
+		PageMeta: models.NewPageMeta(total, page, size),
 	}
 
 	render.JSON(w, r, SuccessResponse("获取质量问题记录成功", response))
 }
+
+// === 规则推荐 ===
+
+// GenerateRuleRecommendations 生成规则推荐
+// @Summary 生成规则推荐
+// @Description 对目标表采样计算字段画像（类型、空值率、取值模式），推荐适合的质量规则与清洗模板及参数。传interface_id时对接口表画像，接口表字段配置中的主键必推荐完整性和唯一性检查，推荐记录关联该接口
+// @Tags 数据质量
+// @Accept json
+// @Produce json
+// @Param request body governance.GenerateRecommendationsRequest true "推荐目标"
+// @Success 200 {object} APIResponse[governance.GenerateRecommendationsResponse] "生成成功"
+// @Failure 400 {object} APIResponse[any] "请求参数错误"
+// @Router /data-quality/recommendations/generate [post]
+func (c *DataQualityController) GenerateRuleRecommendations(w http.ResponseWriter, r *http.Request) {
+	var req governance.GenerateRecommendationsRequest
+	if !decodeAndValidate(w, r, &req) {
+		return
+	}
+
+	result, err := c.governanceService.GenerateRuleRecommendations(&req, getCurrentUsername(r))
+	if err != nil {
+		render.JSON(w, r, BadRequestResponse("生成规则推荐失败: "+err.Error(), err))
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("生成规则推荐成功", result))
+}
+
+// GetRuleRecommendations 获取规则推荐列表
+// @Summary 获取规则推荐列表
+// @Description 分页获取规则推荐，可按表、状态和推荐类型过滤
+// @Tags 数据质量
+// @Produce json
+// @Param target_schema query string false "目标schema"
+// @Param target_table query string false "目标表名"
+// @Param status query string false "状态" Enums(pending,applied,rejected,superseded)
+// @Param recommendation_type query string false "推荐类型" Enums(quality,cleansing)
+// @Param page query int false "页码" default(1)
+// @Param size query int false "每页数量" default(10)
+// @Success 200 {object} APIResponse[governance.RuleRecommendationListResponse] "获取成功"
+// @Failure 500 {object} APIResponse[any] "服务器内部错误"
+// @Router /data-quality/recommendations [get]
+func (c *DataQualityController) GetRuleRecommendations(w http.ResponseWriter, r *http.Request) {
+	page, size := parsePagination(r)
+
+	query := r.URL.Query()
+	list, total, err := c.governanceService.GetRuleRecommendations(page, size,
+		query.Get("target_schema"), query.Get("target_table"), query.Get("status"), query.Get("recommendation_type"))
+	if err != nil {
+		render.JSON(w, r, MapErrorResponse("获取规则推荐列表失败", err))
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("获取规则推荐列表成功", governance.RuleRecommendationListResponse{
+		List:     list,
+		PageMeta: models.NewPageMeta(total, page, size),
+	}))
+}
+
+// ApplyRuleRecommendations 批量采纳规则推荐
+// @Summary 批量采纳规则推荐
+// @Description 一键将推荐的质量规则写入质量检测任务，清洗规则写入主题同步任务。传batch_id时采纳该批次中置信度不低于min_confidence的全部待处理推荐；未指定任务且create_quality_task为true时，为推荐所属接口创建手动执行的质量检测任务
+// @Tags 数据质量
+// @Accept json
+// @Produce json
+// @Param request body governance.ApplyRecommendationsRequest true "采纳请求"
+// @Success 200 {object} APIResponse[governance.ApplyRecommendationsResponse] "采纳成功"
+// @Failure 400 {object} APIResponse[any] "请求参数错误"
+// @Router /data-quality/recommendations/apply [post]
+func (c *DataQualityController) ApplyRuleRecommendations(w http.ResponseWriter, r *http.Request) {
+	var req governance.ApplyRecommendationsRequest
+	if !decodeAndValidate(w, r, &req) {
+		return
+	}
+
+	result, err := c.governanceService.ApplyRuleRecommendations(&req, getCurrentUsername(r))
+	if err != nil {
+		render.JSON(w, r, BadRequestResponse("采纳规则推荐失败: "+err.Error(), err))
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("采纳规则推荐成功", result))
+}
+
+// RejectRuleRecommendations 批量拒绝规则推荐
+// @Summary 批量拒绝规则推荐
+// @Description 将待处理的推荐标记为拒绝，计入采纳率统计
+// @Tags 数据质量
+// @Accept json
+// @Produce json
+// @Param request body governance.RejectRecommendationsRequest true "拒绝请求"
+// @Success 200 {object} APIResponse[any] "拒绝成功"
+// @Failure 400 {object} APIResponse[any] "请求参数错误"
+// @Router /data-quality/recommendations/reject [post]
+func (c *DataQualityController) RejectRuleRecommendations(w http.ResponseWriter, r *http.Request) {
+	var req governance.RejectRecommendationsRequest
+	if !decodeAndValidate(w, r, &req) {
+		return
+	}
+
+	count, err := c.governanceService.RejectRuleRecommendations(req.RecommendationIDs, getCurrentUsername(r))
+	if err != nil {
+		render.JSON(w, r, BadRequestResponse("拒绝规则推荐失败: "+err.Error(), err))
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("拒绝规则推荐成功", map[string]interface{}{"rejected_count": count}))
+}
+
+// GetRecommendationAdoptionStats 获取推荐采纳率统计
+// @Summary 获取推荐采纳率统计
+// @Description 统计推荐的采纳、拒绝和待处理数量及采纳率，支持按模板查看
+// @Tags 数据质量
+// @Produce json
+// @Param target_schema query string false "目标schema"
+// @Param target_table query string false "目标表名"
+// @Success 200 {object} APIResponse[governance.RecommendationAdoptionStats] "获取成功"
+// @Failure 500 {object} APIResponse[any] "服务器内部错误"
+// @Router /data-quality/recommendations/adoption-stats [get]
+func (c *DataQualityController) GetRecommendationAdoptionStats(w http.ResponseWriter, r *http.Request) {
+	stats, err := c.governanceService.GetRecommendationAdoptionStats(r.URL.Query().Get("target_schema"), r.URL.Query().Get("target_table"))
+	if err != nil {
+		render.JSON(w, r, MapErrorResponse("获取推荐采纳率统计失败", err))
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("获取推荐采纳率统计成功", stats))
+}
+
+// batchOperationResponse 根据批量操作是否提交创建响应，回滚时返回BATCH_ROLLED_BACK并携带逐项结果
+func batchOperationResponse(label string, result *governance.BatchOperationResult) render.Renderer {
+	if result.Committed {
+		return SuccessResponse(label+"成功", result)
+	}
+	return &APIResponse[any]{
+		Status: StatusConflict,
+		Code:   CodeBatchRolledBack,
+		Msg:    fmt.Sprintf("%s失败: %d项失败，已整体回滚", label, result.FailedCount),
+		Data:   result,
+	}
+}
+
+// ruleLogicErrorResponse 规则逻辑引用的自定义函数不存在、未审批或参数不符合签名时返回400
+func ruleLogicErrorResponse(msg string, err error) render.Renderer {
+	if errors.Is(err, udf.ErrInvalidUDF) || errors.Is(err, udf.ErrRegistryUnavailable) {
+		return BadRequestResponse(msg+": "+err.Error(), err)
+	}
+	return MapErrorResponse(msg, err)
+}
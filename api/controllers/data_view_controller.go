@@ -12,19 +12,26 @@
 package controllers
 
 import (
+	"errors"
 	"fmt"
 	"log/slog"
 	"net/http"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/render"
 	"gorm.io/gorm"
 
+	"datahub-service/api/middleware"
 	"datahub-service/service"
+	"datahub-service/service/clickhouse"
 	"datahub-service/service/database"
+	"datahub-service/service/encryption"
 	"datahub-service/service/models"
+	"datahub-service/service/sharing"
+	"datahub-service/service/utils"
 )
 
 // DataViewController 数据查看控制器
@@ -108,10 +115,10 @@ type Relationship struct {
 // @Param library_id path string true "库ID" format(uuid)
 // @Param include_columns query bool false "是否包含列信息" default(false)
 // @Param include_relationships query bool false "是否包含关系信息" default(false)
-// @Success 200 {object} APIResponse{data=LibraryTablesResponse}
-// @Failure 400 {object} APIResponse
-// @Failure 404 {object} APIResponse
-// @Failure 500 {object} APIResponse
+// @Success 200 {object} APIResponse[LibraryTablesResponse]
+// @Failure 400 {object} APIResponse[any]
+// @Failure 404 {object} APIResponse[any]
+// @Failure 500 {object} APIResponse[any]
 // @Router /data-view/{library_type}/{library_id}/tables [get]
 func (c *DataViewController) GetLibraryTables(w http.ResponseWriter, r *http.Request) {
 	libraryType := chi.URLParam(r, "library_type")
@@ -155,10 +162,15 @@ func (c *DataViewController) GetLibraryTables(w http.ResponseWriter, r *http.Req
 		slog.Error("GetLibraryTables - 获取表信息失败",
 			"schema", libraryInfo.SchemaName,
 			"error", err)
-		render.JSON(w, r, InternalErrorResponse("获取表信息失败: "+err.Error(), err))
+		render.JSON(w, r, MapErrorResponse("获取表信息失败: "+err.Error(), err))
 		return
 	}
 
+	// 主题库中存储在ClickHouse的接口表不在PostgreSQL schema下，单独列出
+	if libraryType == "thematic_library" {
+		tableNames = append(tableNames, c.clickHouseTables(libraryID)...)
+	}
+
 	slog.Debug("GetLibraryTables - 获取到表数量",
 		"count", len(tableNames),
 		"table_names", tableNames)
@@ -225,20 +237,29 @@ func (c *DataViewController) GetLibraryTables(w http.ResponseWriter, r *http.Req
 
 // GetTableData 获取表数据
 // @Summary 获取表数据
-// @Description 获取指定表的数据内容
+// @Description 获取指定表的数据内容。请求头Accept为text/csv或application/x-ndjson时逐行返回CSV（首行为列名，按列名排序）或NDJSON，只包含数据行，Content-Range给出本页范围和总数
 // @Tags 数据查看
 // @Accept json
 // @Produce json
+// @Produce text/csv
+// @Produce application/x-ndjson
 // @Param library_type path string true "库类型" Enums(basic_library,thematic_library)
 // @Param library_id path string true "库ID" format(uuid)
 // @Param table_name path string true "表名"
 // @Param limit query int false "限制返回行数" default(100) minimum(1) maximum(1000)
 // @Param offset query int false "偏移量" default(0) minimum(0)
 // @Param where query string false "WHERE条件(不包含WHERE关键字，由前端拼好并转义)" example("age > 18 AND status = 'active'")
-// @Success 200 {object} APIResponse
-// @Failure 400 {object} APIResponse
-// @Failure 404 {object} APIResponse
-// @Failure 500 {object} APIResponse
+// @Param source_id query string false "按来源数据源筛选(_source_id)，仅开启来源追溯的基础库接口表"
+// @Param sync_execution_id query string false "按同步任务执行筛选(_sync_execution_id)，仅开启来源追溯的基础库接口表"
+// @Param ingested_from query string false "入库时间下限(含，RFC3339)，仅开启来源追溯的基础库接口表" example("2026-01-01T00:00:00+08:00")
+// @Param ingested_to query string false "入库时间上限(不含，RFC3339)，仅开启来源追溯的基础库接口表" example("2026-01-02T00:00:00+08:00")
+// @Param bbox query string false "与矩形范围相交：最小经度,最小纬度,最大经度,最大纬度" example("120.1,30.2,120.3,30.4")
+// @Param near query string false "在圆形范围内：经度,纬度,半径(米)" example("120.2,30.3,500")
+// @Param geo_field query string false "空间筛选字段，表只有一个空间字段时可不填"
+// @Success 200 {object} APIResponse[any]
+// @Failure 400 {object} APIResponse[any]
+// @Failure 404 {object} APIResponse[any]
+// @Failure 500 {object} APIResponse[any]
 // @Router /data-view/{library_type}/{library_id}/tables/{table_name}/data [get]
 func (c *DataViewController) GetTableData(w http.ResponseWriter, r *http.Request) {
 	libraryType := chi.URLParam(r, "library_type")
@@ -268,6 +289,22 @@ func (c *DataViewController) GetTableData(w http.ResponseWriter, r *http.Request
 		}
 	}
 
+	provenance, err := parseProvenanceFilter(r)
+	if err != nil {
+		render.JSON(w, r, BadRequestResponse(err.Error(), err))
+		return
+	}
+	if !provenance.IsEmpty() && libraryType != "basic_library" {
+		render.JSON(w, r, BadRequestResponse("只有基础库接口表支持按来源追溯列筛选", nil))
+		return
+	}
+	query := r.URL.Query()
+	geoFilter, err := models.ParseGeoFilter(query.Get("geo_field"), query.Get("bbox"), query.Get("near"))
+	if err != nil {
+		render.JSON(w, r, BadRequestResponse(err.Error(), err))
+		return
+	}
+
 	slog.Debug("GetTableData - 请求参数",
 		"library_type", libraryType,
 		"library_id", libraryID,
@@ -283,15 +320,80 @@ func (c *DataViewController) GetTableData(w http.ResponseWriter, r *http.Request
 		return
 	}
 
-	// 使用schema服务获取表数据
+	// 解析当前用户的行级安全策略
+	rowFilter, rowFilterArgs, err := c.resolveRowFilter(r, libraryInfo.SchemaName, tableName)
+	if err != nil {
+		if errors.Is(err, sharing.ErrRowAccessDenied) {
+			render.JSON(w, r, ErrorResponse(StatusForbidden, "无权查看该表数据", err))
+			return
+		}
+		render.JSON(w, r, MapErrorResponse("解析行级安全策略失败", err))
+		return
+	}
+
+	// 来源追溯筛选与行级安全策略一起作为数据来源的过滤条件
+	if provenanceSQL, provenanceArgs := provenance.SQLFilter(); provenanceSQL != "" {
+		if rowFilter != "" {
+			rowFilter = "(" + rowFilter + ") AND " + provenanceSQL
+		} else {
+			rowFilter = provenanceSQL
+		}
+		rowFilterArgs = append(rowFilterArgs, provenanceArgs...)
+	}
+
+	// 空间范围筛选同样作为数据来源的过滤条件，ClickHouse存储的表没有空间列
+	isClickHouse := libraryType == "thematic_library" && c.isClickHouseTable(libraryID, tableName)
+	if !geoFilter.IsEmpty() {
+		if isClickHouse {
+			render.JSON(w, r, BadRequestResponse("ClickHouse存储的主题接口表不支持空间范围筛选", nil))
+			return
+		}
+		geoColumns, err := c.schemaService.GetGeoColumns(libraryInfo.SchemaName, tableName)
+		if err != nil {
+			render.JSON(w, r, MapErrorResponse("查询空间字段失败", err))
+			return
+		}
+		if err := geoFilter.ResolveColumn(geoColumns); err != nil {
+			render.JSON(w, r, BadRequestResponse(err.Error(), err))
+			return
+		}
+		geoSQL, geoArgs := geoFilter.SQLFilter()
+		if rowFilter != "" {
+			rowFilter = "(" + rowFilter + ") AND " + geoSQL
+		} else {
+			rowFilter = geoSQL
+		}
+		rowFilterArgs = append(rowFilterArgs, geoArgs...)
+	}
+
+	// 使用schema服务获取表数据，ClickHouse存储的主题接口表直接查询ClickHouse
 	fullTableName := libraryInfo.SchemaName + "." + tableName
-	data, totalCount, err := c.schemaService.GetTableData(fullTableName, limit, offset, whereCondition)
+	var data []map[string]interface{}
+	var totalCount int
+	if isClickHouse {
+		data, totalCount, err = c.getClickHouseTableData(r, libraryInfo.SchemaName, tableName, limit, offset, whereCondition, rowFilter)
+	} else {
+		data, totalCount, err = c.schemaService.GetTableDataWithRowFilter(fullTableName, limit, offset, whereCondition, rowFilter, rowFilterArgs...)
+	}
 	if err != nil {
+		if errors.Is(err, sharing.ErrRowAccessDenied) {
+			render.JSON(w, r, ErrorResponse(StatusForbidden, "无权查看该表数据", err))
+			return
+		}
 		slog.Error("GetTableData - 获取表数据失败",
 			"table", fullTableName,
 			"where", whereCondition,
 			"error", err)
-		render.JSON(w, r, InternalErrorResponse("获取表数据失败: "+err.Error(), err))
+		render.JSON(w, r, MapErrorResponse("获取表数据失败: "+err.Error(), err))
+		return
+	}
+
+	// 加密列按角色解密，无权限时遮蔽
+	data = encryption.DecryptRows(r.Context(), libraryInfo.SchemaName, tableName, data, c.currentRoles(r))
+
+	// 请求CSV或NDJSON时逐行写出数据，Content-Range给出本页范围和总数
+	if format := negotiateRowFormat(r); format != utils.RowFormatJSON {
+		writeRows(w, r, format, data, offset, int64(totalCount))
 		return
 	}
 
@@ -311,6 +413,67 @@ func (c *DataViewController) GetTableData(w http.ResponseWriter, r *http.Request
 	render.JSON(w, r, SuccessResponse("获取表数据成功", response))
 }
 
+// resolveRowFilter 根据当前用户及其角色解析行级过滤条件，管理员不受行级策略限制
+func (c *DataViewController) resolveRowFilter(r *http.Request, schemaName, tableName string) (string, []interface{}, error) {
+	if service.GlobalSharingService == nil {
+		return "", nil, nil
+	}
+
+	subject := sharing.RowPolicySubject{}
+	if userInfo, ok := middleware.GetUserInfoFromContext(r.Context()); ok {
+		subject.Username = userInfo.Username
+		subject.Roles = c.currentRoles(r)
+	}
+	for _, role := range subject.Roles {
+		if role == models.RoleAdmin {
+			return "", nil, nil
+		}
+	}
+
+	filter, err := service.GlobalSharingService.ResolveRowFilter(schemaName, tableName, subject)
+	if err != nil {
+		return "", nil, err
+	}
+	sql, args := filter.SQLFilter()
+	return sql, args, nil
+}
+
+// parseProvenanceFilter 解析按来源追溯系统列筛选的查询参数
+func parseProvenanceFilter(r *http.Request) (models.ProvenanceFilter, error) {
+	query := r.URL.Query()
+	filter := models.ProvenanceFilter{
+		SourceID:    strings.TrimSpace(query.Get("source_id")),
+		ExecutionID: strings.TrimSpace(query.Get("sync_execution_id")),
+	}
+	for _, bound := range []struct {
+		name   string
+		target **time.Time
+	}{{"ingested_from", &filter.IngestedFrom}, {"ingested_to", &filter.IngestedTo}} {
+		value := strings.TrimSpace(query.Get(bound.name))
+		if value == "" {
+			continue
+		}
+		at, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return filter, fmt.Errorf("%s须为RFC3339时间: %w", bound.name, err)
+		}
+		*bound.target = &at
+	}
+	return filter, nil
+}
+
+// currentRoles 获取当前用户的有效角色
+func (c *DataViewController) currentRoles(r *http.Request) []string {
+	userInfo, ok := middleware.GetUserInfoFromContext(r.Context())
+	if !ok {
+		return nil
+	}
+	if service.GlobalRBACService != nil {
+		return service.GlobalRBACService.ResolveRoles(userInfo.Username, userInfo.Roles)
+	}
+	return userInfo.Roles
+}
+
 // GetTableStructure 获取表结构
 // @Summary 获取表结构
 // @Description 获取指定表的结构信息
@@ -320,10 +483,10 @@ func (c *DataViewController) GetTableData(w http.ResponseWriter, r *http.Request
 // @Param library_type path string true "库类型" Enums(basic_library,thematic_library)
 // @Param library_id path string true "库ID" format(uuid)
 // @Param table_name path string true "表名"
-// @Success 200 {object} APIResponse
-// @Failure 400 {object} APIResponse
-// @Failure 404 {object} APIResponse
-// @Failure 500 {object} APIResponse
+// @Success 200 {object} APIResponse[any]
+// @Failure 400 {object} APIResponse[any]
+// @Failure 404 {object} APIResponse[any]
+// @Failure 500 {object} APIResponse[any]
 // @Router /data-view/{library_type}/{library_id}/tables/{table_name}/structure [get]
 func (c *DataViewController) GetTableStructure(w http.ResponseWriter, r *http.Request) {
 	libraryType := chi.URLParam(r, "library_type")
@@ -346,7 +509,7 @@ func (c *DataViewController) GetTableStructure(w http.ResponseWriter, r *http.Re
 	// 获取表结构
 	structure, err := c.schemaService.GetTableInfo(libraryInfo.SchemaName, tableName)
 	if err != nil {
-		render.JSON(w, r, InternalErrorResponse("获取表结构失败: "+err.Error(), err))
+		render.JSON(w, r, MapErrorResponse("获取表结构失败: "+err.Error(), err))
 		return
 	}
 
@@ -371,10 +534,10 @@ func (c *DataViewController) GetTableStructure(w http.ResponseWriter, r *http.Re
 // @Param schema_name query string true "Schema名称"
 // @Param table_name query string true "表名"
 // @Param record_identifier query string true "记录标识符" example("id=123" or "key1=val1&key2=val2")
-// @Success 200 {object} APIResponse
-// @Failure 400 {object} APIResponse
-// @Failure 404 {object} APIResponse
-// @Failure 500 {object} APIResponse
+// @Success 200 {object} APIResponse[any]
+// @Failure 400 {object} APIResponse[any]
+// @Failure 404 {object} APIResponse[any]
+// @Failure 500 {object} APIResponse[any]
 // @Router /data-view/record-by-pk [get]
 func (c *DataViewController) GetRecordByPrimaryKey(w http.ResponseWriter, r *http.Request) {
 	schemaName := r.URL.Query().Get("schema_name")
@@ -411,10 +574,12 @@ func (c *DataViewController) GetRecordByPrimaryKey(w http.ResponseWriter, r *htt
 			return
 		}
 		slog.Error("GetRecordByPrimaryKey - 查询记录失败", "error", result.Error)
-		render.JSON(w, r, InternalErrorResponse("查询记录失败: "+result.Error.Error(), result.Error))
+		render.JSON(w, r, MapErrorResponse("查询记录失败: "+result.Error.Error(), result.Error))
 		return
 	}
 
+	record = encryption.DecryptRows(r.Context(), schemaName, tableName, []map[string]interface{}{record}, c.currentRoles(r))[0]
+
 	response := map[string]interface{}{
 		"schema_name":       schemaName,
 		"table_name":        tableName,
@@ -471,6 +636,44 @@ type LibraryInfo struct {
 	SchemaName string
 }
 
+// clickHouseTables 列出主题库中已在ClickHouse建表的接口表名
+func (c *DataViewController) clickHouseTables(libraryID string) []string {
+	var tableNames []string
+	if err := c.db.Model(&models.ThematicInterface{}).
+		Where("library_id = ? AND storage_engine = ? AND is_table_created = ?", libraryID, models.StorageEngineClickHouse, true).
+		Order("name_en").Pluck("name_en", &tableNames).Error; err != nil {
+		slog.Warn("查询ClickHouse接口表失败", "library_id", libraryID, "error", err)
+	}
+	return tableNames
+}
+
+// isClickHouseTable 主题库中的表是否存储在ClickHouse
+func (c *DataViewController) isClickHouseTable(libraryID, tableName string) bool {
+	var count int64
+	c.db.Model(&models.ThematicInterface{}).
+		Where("library_id = ? AND name_en = ? AND storage_engine = ?", libraryID, tableName, models.StorageEngineClickHouse).
+		Count(&count)
+	return count > 0
+}
+
+// getClickHouseTableData 查询ClickHouse接口表数据，WHERE条件透传给ClickHouse；
+// 行级安全策略的参数化条件是PostgreSQL方言，命中策略的用户无法在ClickHouse上等价执行，直接拒绝
+func (c *DataViewController) getClickHouseTableData(r *http.Request, database, tableName string, limit, offset int, whereCondition, rowFilter string) ([]map[string]interface{}, int, error) {
+	if rowFilter != "" {
+		return nil, 0, fmt.Errorf("%w: ClickHouse存储的表暂不支持行级安全策略", sharing.ErrRowAccessDenied)
+	}
+	client := clickhouse.Default()
+	if client == nil {
+		return nil, 0, errors.New("未配置ClickHouse")
+	}
+
+	data, total, err := client.BrowseTable(r.Context(), database, tableName, whereCondition, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	return data, int(total), nil
+}
+
 // getLibraryInfo 获取库信息
 func (c *DataViewController) getLibraryInfo(libraryType, libraryID string) (*LibraryInfo, error) {
 	// 这里需要根据库类型查询对应的库信息
@@ -510,7 +713,7 @@ func (c *DataViewController) getBasicLibraryInfo(libraryID string) (*LibraryInfo
 	return &LibraryInfo{
 		ID:         basicLibrary.ID,
 		Name:       basicLibrary.NameZh,
-		SchemaName: basicLibrary.NameEn, // 使用英文名作为schema名称
+		SchemaName: basicLibrary.GetSchemaName(), // 库对应的schema名称
 	}, nil
 }
 
@@ -537,6 +740,6 @@ func (c *DataViewController) getThematicLibraryInfo(libraryID string) (*LibraryI
 	return &LibraryInfo{
 		ID:         thematicLibrary.ID,
 		Name:       thematicLibrary.NameZh,
-		SchemaName: thematicLibrary.NameEn, // 使用英文名作为schema名称
+		SchemaName: thematicLibrary.GetSchemaName(), // 库对应的schema名称
 	}, nil
 }
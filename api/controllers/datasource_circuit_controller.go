@@ -0,0 +1,67 @@
+/*
+ * @module api/controllers/datasource_circuit_controller
+ * @description 数据源熔断控制器，查看数据源的熔断状态、连续失败次数和冷却结束时间，上游确认恢复后人工结束熔断
+ * @architecture 分层架构 - 控制器层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow HTTP请求 -> 控制器 -> 数据源熔断服务 -> 读取或更新数据源熔断状态 -> (人工恢复)写入审计日志和恢复事件
+ * @rules 统一的错误处理和响应格式；熔断阈值和冷却时间通过运行时配置修改
+ * @dependencies datahub-service/service, github.com/go-chi/chi/v5
+ * @refs service/basic_library/datasource_circuit_service.go, api/controllers/datasource_maintenance_controller.go
+ */
+
+package controllers
+
+import (
+	"datahub-service/service"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+)
+
+// DataSourceCircuitController 数据源熔断控制器
+type DataSourceCircuitController struct {
+}
+
+// NewDataSourceCircuitController 创建数据源熔断控制器实例
+func NewDataSourceCircuitController() *DataSourceCircuitController {
+	return &DataSourceCircuitController{}
+}
+
+// GetDataSourceCircuit 获取数据源熔断状态
+// @Summary 获取数据源熔断状态
+// @Description 返回数据源的熔断状态(closed、open、half_open)、连续失败次数、熔断阈值、冷却秒数、最近一次失败信息，熔断中时返回冷却结束时间。数据源连续拉取失败(网络、源系统不可用、认证、限流)达到阈值后熔断，冷却期内依赖的同步任务调度跳过并写入skipped执行记录，手动启动返回409；冷却结束后下一次执行先探测数据源连接，探测成功恢复执行
+// @Tags 数据基础库
+// @Produce json
+// @Param id path string true "数据源ID"
+// @Success 200 {object} APIResponse[basic_library.DataSourceCircuitStatus] "获取成功"
+// @Failure 404 {object} APIResponse[any] "数据源不存在"
+// @Router /basic-libraries/datasources/{id}/circuit [get]
+func (c *DataSourceCircuitController) GetDataSourceCircuit(w http.ResponseWriter, r *http.Request) {
+	status, err := service.GlobalCircuitService.GetCircuit(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		render.JSON(w, r, MapErrorResponse("获取数据源熔断状态失败", err))
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("获取数据源熔断状态成功", status))
+}
+
+// ResetDataSourceCircuit 人工结束数据源熔断
+// @Summary 人工结束数据源熔断
+// @Description 上游确认恢复后不等冷却结束直接恢复执行，连续失败次数清零。熔断中时记录datasource_circuit_closed事件，操作写入审计日志
+// @Tags 数据基础库
+// @Produce json
+// @Param id path string true "数据源ID"
+// @Success 200 {object} APIResponse[basic_library.DataSourceCircuitStatus] "恢复成功"
+// @Failure 404 {object} APIResponse[any] "数据源不存在"
+// @Router /basic-libraries/datasources/{id}/circuit [delete]
+func (c *DataSourceCircuitController) ResetDataSourceCircuit(w http.ResponseWriter, r *http.Request) {
+	status, err := service.GlobalCircuitService.ResetCircuit(r.Context(), chi.URLParam(r, "id"), getCurrentUsername(r), getClientIP(r))
+	if err != nil {
+		render.JSON(w, r, MapErrorResponse("结束数据源熔断失败", err))
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("结束数据源熔断成功", status))
+}
@@ -0,0 +1,132 @@
+/*
+ * @module api/controllers/datasource_maintenance_controller
+ * @description 数据源维护模式控制器，上游计划停机前开启维护，依赖同步任务的调度跳过并记录，结束维护时补跑跳过过的任务
+ * @architecture 分层架构 - 控制器层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow HTTP请求 -> 控制器 -> 数据源维护模式服务 -> 更新维护状态并写入审计日志 -> (结束维护)登记补跑的长时操作
+ * @rules 统一的错误处理和响应格式；重复开启或未开启时结束返回409；补跑的任务登记长时操作，可在/operations查询
+ * @dependencies datahub-service/service, github.com/go-chi/chi/v5
+ * @refs service/basic_library/datasource_maintenance_service.go
+ */
+
+package controllers
+
+import (
+	"datahub-service/service"
+	"datahub-service/service/basic_library"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+)
+
+// DataSourceMaintenanceController 数据源维护模式控制器
+type DataSourceMaintenanceController struct {
+}
+
+// NewDataSourceMaintenanceController 创建数据源维护模式控制器实例
+func NewDataSourceMaintenanceController() *DataSourceMaintenanceController {
+	return &DataSourceMaintenanceController{}
+}
+
+// StartMaintenanceRequest 开启维护请求
+type StartMaintenanceRequest struct {
+	Reason string `json:"reason" validate:"max=500" example:"停车系统升级，预计停机2小时"` // 维护原因
+}
+
+// EndMaintenanceRequest 结束维护请求
+type EndMaintenanceRequest struct {
+	CatchUp *bool `json:"catch_up,omitempty" example:"true"` // 是否补跑维护期间跳过过调度的任务，默认true
+}
+
+// GetDataSourceMaintenance 获取数据源维护状态
+// @Summary 获取数据源维护状态
+// @Description 返回数据源是否处于维护模式、维护原因和开始时间、依赖数据源的激活调度任务数，以及本次维护期间跳过的调度次数
+// @Tags 数据基础库
+// @Produce json
+// @Param id path string true "数据源ID"
+// @Success 200 {object} APIResponse[basic_library.DataSourceMaintenanceStatus] "获取成功"
+// @Failure 404 {object} APIResponse[any] "数据源不存在"
+// @Router /basic-libraries/datasources/{id}/maintenance [get]
+func (c *DataSourceMaintenanceController) GetDataSourceMaintenance(w http.ResponseWriter, r *http.Request) {
+	status, err := service.GlobalMaintenanceService.GetMaintenance(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		render.JSON(w, r, MapErrorResponse("获取数据源维护状态失败", err))
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("获取数据源维护状态成功", status))
+}
+
+// StartDataSourceMaintenance 开启数据源维护模式
+// @Summary 开启数据源维护模式
+// @Description 数据源进入维护模式。维护期间依赖数据源的同步任务保持原有状态，调度触发时跳过执行并写入skipped执行记录（不计为失败、不发失败事件），手动启动返回409；接口数据过期不告警。操作写入审计日志
+// @Tags 数据基础库
+// @Accept json
+// @Produce json
+// @Param id path string true "数据源ID"
+// @Param request body StartMaintenanceRequest false "维护原因"
+// @Success 200 {object} APIResponse[basic_library.DataSourceMaintenanceStatus] "开启成功"
+// @Failure 400 {object} APIResponse[any] "请求参数错误"
+// @Failure 404 {object} APIResponse[any] "数据源不存在"
+// @Failure 409 {object} APIResponse[any] "数据源已处于维护模式"
+// @Router /basic-libraries/datasources/{id}/maintenance [post]
+func (c *DataSourceMaintenanceController) StartDataSourceMaintenance(w http.ResponseWriter, r *http.Request) {
+	var req StartMaintenanceRequest
+	if r.ContentLength > 0 {
+		if !decodeAndValidate(w, r, &req) {
+			return
+		}
+	}
+
+	status, err := service.GlobalMaintenanceService.StartMaintenance(r.Context(), chi.URLParam(r, "id"),
+		req.Reason, getCurrentUsername(r), getClientIP(r))
+	if err != nil {
+		respondDataSourceMaintenanceError(w, r, "开启数据源维护模式失败", err)
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("开启数据源维护模式成功", status))
+}
+
+// EndDataSourceMaintenance 结束数据源维护模式
+// @Summary 结束数据源维护模式
+// @Description 数据源结束维护模式，默认为维护期间跳过过调度的激活任务各补跑一次，返回跳过的调度次数和每个任务的补跑结果（含长时操作ID）。catch_up=false时只结束维护，等待下次调度。操作写入审计日志
+// @Tags 数据基础库
+// @Accept json
+// @Produce json
+// @Param id path string true "数据源ID"
+// @Param request body EndMaintenanceRequest false "补跑选项"
+// @Success 200 {object} APIResponse[basic_library.EndMaintenanceResult] "结束成功"
+// @Failure 404 {object} APIResponse[any] "数据源不存在"
+// @Failure 409 {object} APIResponse[any] "数据源未处于维护模式"
+// @Router /basic-libraries/datasources/{id}/maintenance [delete]
+func (c *DataSourceMaintenanceController) EndDataSourceMaintenance(w http.ResponseWriter, r *http.Request) {
+	var req EndMaintenanceRequest
+	if r.ContentLength > 0 {
+		if !decodeAndValidate(w, r, &req) {
+			return
+		}
+	}
+	catchUp := req.CatchUp == nil || *req.CatchUp
+
+	username := getCurrentUsername(r)
+	result, err := service.GlobalMaintenanceService.EndMaintenance(r.Context(), chi.URLParam(r, "id"),
+		catchUp, username, getClientIP(r), syncTaskOperationRunner(username))
+	if err != nil {
+		respondDataSourceMaintenanceError(w, r, "结束数据源维护模式失败", err)
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("结束数据源维护模式成功", result))
+}
+
+// respondDataSourceMaintenanceError 按错误类型返回维护模式错误响应
+func respondDataSourceMaintenanceError(w http.ResponseWriter, r *http.Request, msg string, err error) {
+	if errors.Is(err, basic_library.ErrDataSourceInMaintenance) || errors.Is(err, basic_library.ErrDataSourceNotInMaintenance) {
+		render.JSON(w, r, ConflictResponse(msg, err))
+		return
+	}
+	render.JSON(w, r, MapErrorResponse(msg, err))
+}
@@ -0,0 +1,117 @@
+/*
+ * @module api/controllers/datasource_template_controller
+ * @description 数据源模板控制器，提供园区常见子系统模板目录的查询和按模板创建数据源及接口
+ * @architecture 分层架构 - 控制器层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow HTTP请求 -> 控制器 -> 数据源模板服务 -> 基础库服务创建数据源、接口和接口表
+ * @rules 统一的错误处理和响应格式；模板不存在返回404，缺少必填连接项或接口名冲突返回400
+ * @dependencies datahub-service/service, datahub-service/service/basic_library, github.com/go-chi/chi/v5
+ * @refs service/basic_library/datasource_template_service.go, service/meta/datasource_template.go
+ */
+
+package controllers
+
+import (
+	"datahub-service/service"
+	"datahub-service/service/basic_library"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+)
+
+// DataSourceTemplateController 数据源模板控制器
+type DataSourceTemplateController struct {
+}
+
+// NewDataSourceTemplateController 创建数据源模板控制器实例
+func NewDataSourceTemplateController() *DataSourceTemplateController {
+	return &DataSourceTemplateController{}
+}
+
+// InstantiateTemplateRequest 模板实例化请求
+type InstantiateTemplateRequest struct {
+	LibraryID        string                 `json:"library_id" validate:"required" example:"lib-1"`                       // 目标基础库ID
+	Name             string                 `json:"name" example:"一期停车系统"`                                                // 数据源名称，为空时使用模板名称
+	ConnectionConfig map[string]interface{} `json:"connection_config" validate:"required"`                                // 模板required_fields对应的地址和凭据
+	Interfaces       []string               `json:"interfaces,omitempty"`                                                 // 要创建的模板接口英文名，为空时创建全部
+	InterfacePrefix  string                 `json:"interface_prefix,omitempty" validate:"omitempty,max=20" example:"p1_"` // 接口英文名前缀
+}
+
+// ListDataSourceTemplates 查询数据源模板目录
+// @Summary 查询数据源模板目录
+// @Description 返回园区常见子系统（视频监控、停车、能耗、门禁）的数据源模板，包括需要填写的连接配置项和预置的接口
+// @Tags 数据基础库
+// @Produce json
+// @Param subsystem query string false "子系统：video/parking/energy/access_control"
+// @Success 200 {object} APIResponse[[]meta.DataSourceTemplate] "查询成功"
+// @Router /basic-libraries/datasource-templates [get]
+func (c *DataSourceTemplateController) ListDataSourceTemplates(w http.ResponseWriter, r *http.Request) {
+	templates := service.GlobalDataSourceTemplateService.ListTemplates(r.URL.Query().Get("subsystem"))
+	render.JSON(w, r, SuccessResponse("查询数据源模板成功", templates))
+}
+
+// GetDataSourceTemplate 查询数据源模板详情
+// @Summary 查询数据源模板详情
+// @Description 返回模板的预置连接配置、需要填写的连接配置项和预置接口的配置与字段
+// @Tags 数据基础库
+// @Produce json
+// @Param id path string true "模板ID"
+// @Success 200 {object} APIResponse[meta.DataSourceTemplate] "查询成功"
+// @Failure 404 {object} APIResponse[any] "模板不存在"
+// @Router /basic-libraries/datasource-templates/{id} [get]
+func (c *DataSourceTemplateController) GetDataSourceTemplate(w http.ResponseWriter, r *http.Request) {
+	template, err := service.GlobalDataSourceTemplateService.GetTemplate(chi.URLParam(r, "id"))
+	if err != nil {
+		respondDataSourceTemplateError(w, r, "查询数据源模板失败", err)
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("查询数据源模板成功", template))
+}
+
+// InstantiateDataSourceTemplate 按模板创建数据源和接口
+// @Summary 按模板创建数据源和接口
+// @Description 在基础库中按模板创建数据源和预置接口，接口的请求配置、字段映射和接口表一并创建，只需填写模板要求的地址和凭据。任一接口创建失败时删除已创建的接口和数据源
+// @Tags 数据基础库
+// @Accept json
+// @Produce json
+// @Param id path string true "模板ID"
+// @Param request body InstantiateTemplateRequest true "实例化参数"
+// @Success 200 {object} APIResponse[basic_library.TemplateInstance] "创建成功"
+// @Failure 400 {object} APIResponse[any] "缺少必填连接项或接口名冲突"
+// @Failure 404 {object} APIResponse[any] "模板或基础库不存在"
+// @Router /basic-libraries/datasource-templates/{id}/instantiate [post]
+func (c *DataSourceTemplateController) InstantiateDataSourceTemplate(w http.ResponseWriter, r *http.Request) {
+	var req InstantiateTemplateRequest
+	if !decodeAndValidate(w, r, &req) {
+		return
+	}
+
+	instance, err := service.GlobalDataSourceTemplateService.Instantiate(r.Context(), chi.URLParam(r, "id"), &basic_library.TemplateInstanceRequest{
+		LibraryID:        req.LibraryID,
+		Name:             req.Name,
+		ConnectionConfig: req.ConnectionConfig,
+		Interfaces:       req.Interfaces,
+		InterfacePrefix:  req.InterfacePrefix,
+	}, getCurrentUsername(r))
+	if err != nil {
+		respondDataSourceTemplateError(w, r, "按模板创建数据源失败", err)
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("按模板创建数据源成功", instance))
+}
+
+// respondDataSourceTemplateError 按错误类型返回数据源模板错误响应
+func respondDataSourceTemplateError(w http.ResponseWriter, r *http.Request, msg string, err error) {
+	switch {
+	case errors.Is(err, basic_library.ErrDataSourceTemplateNotFound):
+		render.JSON(w, r, NotFoundResponse(msg, err))
+	case errors.Is(err, basic_library.ErrInvalidTemplateInstance):
+		render.JSON(w, r, BadRequestResponse(msg, err))
+	default:
+		render.JSON(w, r, MapErrorResponse(msg, err))
+	}
+}
@@ -0,0 +1,48 @@
+/*
+ * @module api/controllers/datasource_usage_controller
+ * @description 数据源使用情况控制器，列出依赖数据源的接口、同步任务和主题同步任务，供下线或轮换上游系统前评估影响
+ * @architecture 分层架构 - 控制器层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow HTTP请求 -> 控制器 -> 数据源使用情况服务 -> 汇总依赖和最近使用时间
+ * @rules 统一的错误处理和响应格式；只读查询
+ * @dependencies datahub-service/service, github.com/go-chi/chi/v5
+ * @refs service/basic_library/datasource_usage_service.go
+ */
+
+package controllers
+
+import (
+	"datahub-service/service"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+)
+
+// DataSourceUsageController 数据源使用情况控制器
+type DataSourceUsageController struct {
+}
+
+// NewDataSourceUsageController 创建数据源使用情况控制器实例
+func NewDataSourceUsageController() *DataSourceUsageController {
+	return &DataSourceUsageController{}
+}
+
+// GetDataSourceUsage 获取数据源使用情况
+// @Summary 获取数据源使用情况
+// @Description 列出使用该数据源的接口及最近成功同步时间、引用数据源或其接口的同步任务及最近执行时间、源配置读取这些接口的主题同步任务及最近同步时间。没有激活的同步任务和主题同步任务时safe_to_decommission为true
+// @Tags 数据基础库
+// @Produce json
+// @Param id path string true "数据源ID"
+// @Success 200 {object} APIResponse[basic_library.DataSourceUsage] "获取成功"
+// @Failure 404 {object} APIResponse[any] "数据源不存在"
+// @Router /basic-libraries/datasources/{id}/usage [get]
+func (c *DataSourceUsageController) GetDataSourceUsage(w http.ResponseWriter, r *http.Request) {
+	usage, err := service.GlobalDataSourceUsageService.GetUsage(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		render.JSON(w, r, MapErrorResponse("获取数据源使用情况失败", err))
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("获取数据源使用情况成功", usage))
+}
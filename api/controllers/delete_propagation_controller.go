@@ -0,0 +1,139 @@
+/*
+ * @module api/controllers/delete_propagation_controller
+ * @description 接口源端删除同步控制器，提供查询、设置、关闭删除同步的接口
+ * @architecture 分层架构 - 控制器层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow HTTP请求 -> 控制器 -> 删除同步配置服务 -> 准备软删除列或归档表并保存接口配置
+ * @rules 统一的错误处理和响应格式；配置不合法时返回400；If-Match与接口版本不一致时返回409
+ * @dependencies datahub-service/service, datahub-service/service/basic_library, github.com/go-chi/chi/v5
+ * @refs service/basic_library/delete_propagation_service.go
+ */
+
+package controllers
+
+import (
+	"datahub-service/service"
+	"datahub-service/service/basic_library"
+	"datahub-service/service/models"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+)
+
+// DeletePropagationController 接口源端删除同步控制器
+type DeletePropagationController struct {
+}
+
+// NewDeletePropagationController 创建接口源端删除同步控制器实例
+func NewDeletePropagationController() *DeletePropagationController {
+	return &DeletePropagationController{}
+}
+
+// UpdateDeletePropagationRequest 设置删除同步请求
+type UpdateDeletePropagationRequest struct {
+	Detection  string   `json:"detection" validate:"required,oneof=full_compare source_flag" example:"source_flag"`      // 识别方式：全量比对或源端删除标记
+	FlagField  string   `json:"flag_field" example:"is_deleted"`                                                         // 源数据中的删除标记字段，按删除标记识别时必填
+	FlagValues []string `json:"flag_values" example:"1"`                                                                 // 表示已删除的取值，为空时使用true/1/y/yes/deleted
+	Action     string   `json:"action" validate:"omitempty,oneof=hard_delete soft_delete archive" example:"soft_delete"` // 处理方式，默认硬删除
+}
+
+// GetDeletePropagation 获取接口删除同步配置
+// @Summary 获取接口删除同步配置
+// @Description 获取接口是否同步源端删除，以及识别方式和处理方式
+// @Tags 数据基础库
+// @Produce json
+// @Param id path string true "接口ID"
+// @Success 200 {object} APIResponse[models.DeletePropagationConfig] "获取成功"
+// @Failure 404 {object} APIResponse[any] "接口不存在"
+// @Router /basic-libraries/interfaces/{id}/delete-propagation [get]
+func (c *DeletePropagationController) GetDeletePropagation(w http.ResponseWriter, r *http.Request) {
+	config, err := service.GlobalDeletePropagationService.GetConfig(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		render.JSON(w, r, MapErrorResponse("获取删除同步配置失败", err))
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("获取删除同步配置成功", config))
+}
+
+// UpdateDeletePropagation 开启或修改接口删除同步
+// @Summary 开启或修改接口删除同步
+// @Description 全量比对每次同步拉取源端全量数据，接口表中不在源端的主键视为已删除；删除标记按源数据中的标记字段识别。已删除的记录可硬删除、写入_deleted_at软删除时间或移入归档表。开启后全量同步不再清空表，删除行数随执行结果返回
+// @Tags 数据基础库
+// @Accept json
+// @Produce json
+// @Param id path string true "接口ID"
+// @Param If-Match header string false "期望版本（ETag），不一致时返回409"
+// @Param request body UpdateDeletePropagationRequest true "删除同步配置"
+// @Success 200 {object} APIResponse[models.DeletePropagationConfig] "设置成功"
+// @Failure 400 {object} APIResponse[any] "配置不合法"
+// @Failure 404 {object} APIResponse[any] "接口不存在"
+// @Failure 409 {object} APIResponse[VersionConflictData] "版本冲突"
+// @Router /basic-libraries/interfaces/{id}/delete-propagation [put]
+func (c *DeletePropagationController) UpdateDeletePropagation(w http.ResponseWriter, r *http.Request) {
+	var req UpdateDeletePropagationRequest
+	if !decodeAndValidate(w, r, &req) {
+		return
+	}
+
+	expectedVersion, err := expectedRowVersion(r, 0)
+	if err != nil {
+		render.JSON(w, r, BadRequestResponse(err.Error(), err))
+		return
+	}
+
+	config := &models.DeletePropagationConfig{
+		Detection:  req.Detection,
+		FlagField:  req.FlagField,
+		FlagValues: req.FlagValues,
+		Action:     req.Action,
+	}
+	result, err := service.GlobalDeletePropagationService.UpdateConfig(r.Context(), chi.URLParam(r, "id"), expectedVersion, config, getCurrentUsername(r))
+	if err != nil {
+		respondDeletePropagationError(w, r, "设置删除同步失败", err)
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("设置删除同步成功", result))
+}
+
+// DisableDeletePropagation 关闭接口删除同步
+// @Summary 关闭接口删除同步
+// @Description 关闭后同步不再处理源端删除，全量同步恢复为清空重写；软删除列和归档表保留
+// @Tags 数据基础库
+// @Produce json
+// @Param id path string true "接口ID"
+// @Param If-Match header string false "期望版本（ETag），不一致时返回409"
+// @Success 200 {object} APIResponse[any] "关闭成功"
+// @Failure 400 {object} APIResponse[any] "接口未开启删除同步"
+// @Failure 404 {object} APIResponse[any] "接口不存在"
+// @Failure 409 {object} APIResponse[VersionConflictData] "版本冲突"
+// @Router /basic-libraries/interfaces/{id}/delete-propagation [delete]
+func (c *DeletePropagationController) DisableDeletePropagation(w http.ResponseWriter, r *http.Request) {
+	expectedVersion, err := expectedRowVersion(r, 0)
+	if err != nil {
+		render.JSON(w, r, BadRequestResponse(err.Error(), err))
+		return
+	}
+
+	if err := service.GlobalDeletePropagationService.DisableConfig(r.Context(), chi.URLParam(r, "id"), expectedVersion, getCurrentUsername(r)); err != nil {
+		respondDeletePropagationError(w, r, "关闭删除同步失败", err)
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("关闭删除同步成功", nil))
+}
+
+// respondDeletePropagationError 版本冲突时返回409，配置不合法时返回400，其余按错误类型映射
+func respondDeletePropagationError(w http.ResponseWriter, r *http.Request, msg string, err error) {
+	if handleVersionConflict(w, r, err) {
+		return
+	}
+	if errors.Is(err, basic_library.ErrInvalidDeletePropagationRequest) {
+		render.JSON(w, r, BadRequestResponse(msg, err))
+		return
+	}
+	render.JSON(w, r, MapErrorResponse(msg, err))
+}
@@ -0,0 +1,138 @@
+/*
+ * @module api/controllers/deprecation_controller
+ * @description 接口弃用管理接口，标记共享接口、主题接口或基础接口弃用并设置下线时间，查询和撤销弃用
+ * @architecture 分层架构 - 控制器层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow HTTP请求 -> 控制器 -> 数据共享服务 -> 数据库/事件日志
+ * @rules 统一的错误处理和响应格式；参数无效返回400，资源或弃用记录不存在返回404
+ * @dependencies datahub-service/service/sharing, github.com/go-chi/chi/v5
+ * @refs service/sharing/deprecation.go
+ */
+
+package controllers
+
+import (
+	"datahub-service/service/sharing"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"gorm.io/gorm"
+)
+
+// DeprecateInterfaceRequest 标记接口弃用请求结构
+type DeprecateInterfaceRequest struct {
+	Reason      string     `json:"reason" validate:"required" example:"已由人口v2接口替代"`
+	Replacement string     `json:"replacement" example:"/api/v1/share/population/residents-v2"` // 替代接口的地址或说明
+	SunsetAt    *time.Time `json:"sunset_at" example:"2026-12-31T00:00:00+08:00"`               // 下线时间，为空表示暂不下线
+}
+
+func (req *DeprecateInterfaceRequest) toInput() *sharing.DeprecationInput {
+	return &sharing.DeprecationInput{
+		Reason:      req.Reason,
+		Replacement: req.Replacement,
+		SunsetAt:    req.SunsetAt,
+	}
+}
+
+// DeprecateInterface 标记接口弃用
+// @Summary 标记接口弃用
+// @Description 标记共享接口(api_interface)、主题接口(thematic_interface)或基础接口(basic_interface)弃用并设置下线时间。首次标记或修改内容时为每个订阅方记录一条弃用通知事件：订阅该资源的生效中数据订阅，共享接口另包括其主题接口的数据订阅和可访问所在应用的ApiKey。弃用的资源不能新建订阅；共享接口响应带Deprecation、Sunset和Link头，到下线时间后返回410
+// @Tags 数据共享服务
+// @Accept json
+// @Produce json
+// @Param resource_type path string true "资源类型" Enums(api_interface, thematic_interface, basic_interface)
+// @Param resource_id path string true "资源ID"
+// @Param deprecation body DeprecateInterfaceRequest true "弃用信息"
+// @Success 200 {object} APIResponse[models.InterfaceDeprecation] "标记成功"
+// @Failure 400 {object} APIResponse[any] "请求参数错误"
+// @Failure 404 {object} APIResponse[any] "资源不存在"
+// @Router /sharing/deprecations/{resource_type}/{resource_id} [put]
+func (c *SharingController) DeprecateInterface(w http.ResponseWriter, r *http.Request) {
+	var req DeprecateInterfaceRequest
+	if !decodeAndValidate(w, r, &req) {
+		return
+	}
+
+	deprecation, err := c.sharingService.DeprecateInterface(r.Context(), chi.URLParam(r, "resource_type"), chi.URLParam(r, "resource_id"),
+		req.toInput(), getCurrentUsername(r))
+	if err != nil {
+		respondDeprecationError(w, r, "标记接口弃用失败", err)
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("标记接口弃用成功", deprecation))
+}
+
+// GetInterfaceDeprecations 查询接口弃用记录
+// @Summary 查询接口弃用记录
+// @Description 按下线时间先后列出弃用记录，未设置下线时间的排在最后
+// @Tags 数据共享服务
+// @Produce json
+// @Param resource_type query string false "资源类型" Enums(api_interface, thematic_interface, basic_interface)
+// @Success 200 {object} APIResponse[[]models.InterfaceDeprecation] "获取成功"
+// @Failure 500 {object} APIResponse[any] "服务器内部错误"
+// @Router /sharing/deprecations [get]
+func (c *SharingController) GetInterfaceDeprecations(w http.ResponseWriter, r *http.Request) {
+	deprecations, err := c.sharingService.ListInterfaceDeprecations(r.Context(), r.URL.Query().Get("resource_type"))
+	if err != nil {
+		render.JSON(w, r, InternalErrorResponse("获取接口弃用记录失败", err))
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("获取接口弃用记录成功", deprecations))
+}
+
+// GetInterfaceDeprecation 获取资源的弃用记录
+// @Summary 获取资源的弃用记录
+// @Description 获取共享接口、主题接口或基础接口的弃用记录
+// @Tags 数据共享服务
+// @Produce json
+// @Param resource_type path string true "资源类型" Enums(api_interface, thematic_interface, basic_interface)
+// @Param resource_id path string true "资源ID"
+// @Success 200 {object} APIResponse[models.InterfaceDeprecation] "获取成功"
+// @Failure 404 {object} APIResponse[any] "资源未弃用"
+// @Router /sharing/deprecations/{resource_type}/{resource_id} [get]
+func (c *SharingController) GetInterfaceDeprecation(w http.ResponseWriter, r *http.Request) {
+	deprecation, err := c.sharingService.GetInterfaceDeprecation(r.Context(), chi.URLParam(r, "resource_type"), chi.URLParam(r, "resource_id"))
+	if err != nil {
+		respondDeprecationError(w, r, "获取接口弃用记录失败", err)
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("获取接口弃用记录成功", deprecation))
+}
+
+// CancelInterfaceDeprecation 撤销接口弃用
+// @Summary 撤销接口弃用
+// @Description 删除弃用记录，并为每个订阅方记录一条撤销弃用的通知事件
+// @Tags 数据共享服务
+// @Produce json
+// @Param resource_type path string true "资源类型" Enums(api_interface, thematic_interface, basic_interface)
+// @Param resource_id path string true "资源ID"
+// @Success 200 {object} APIResponse[any] "撤销成功"
+// @Failure 404 {object} APIResponse[any] "资源未弃用"
+// @Router /sharing/deprecations/{resource_type}/{resource_id} [delete]
+func (c *SharingController) CancelInterfaceDeprecation(w http.ResponseWriter, r *http.Request) {
+	if err := c.sharingService.CancelInterfaceDeprecation(r.Context(), chi.URLParam(r, "resource_type"), chi.URLParam(r, "resource_id"),
+		getCurrentUsername(r)); err != nil {
+		respondDeprecationError(w, r, "撤销接口弃用失败", err)
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("撤销接口弃用成功", nil))
+}
+
+// respondDeprecationError 按错误类型返回接口弃用操作的错误响应
+func respondDeprecationError(w http.ResponseWriter, r *http.Request, msg string, err error) {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		render.JSON(w, r, NotFoundResponse(msg+": 资源不存在或未弃用", err))
+	case errors.Is(err, sharing.ErrInvalidDeprecation):
+		render.JSON(w, r, BadRequestResponse(msg+": "+err.Error(), err))
+	default:
+		render.JSON(w, r, MapErrorResponse(msg, err))
+	}
+}
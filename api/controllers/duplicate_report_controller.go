@@ -0,0 +1,113 @@
+/*
+ * @module api/controllers/duplicate_report_controller
+ * @description 接口表重复数据检测控制器，提供发起检测、查询检测报告列表和报告详情的接口
+ * @architecture 分层架构 - 控制器层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow HTTP请求 -> 控制器 -> 重复数据检测服务 -> 数据库
+ * @rules 统一的错误处理和响应格式；检测参数不合法时返回400；检测在后台执行，发起后通过报告详情查询进度和结果
+ * @dependencies datahub-service/service, datahub-service/service/basic_library, github.com/go-chi/chi/v5
+ * @refs service/basic_library/duplicate_report_service.go
+ */
+
+package controllers
+
+import (
+	"datahub-service/service"
+	"datahub-service/service/basic_library"
+	"datahub-service/service/models"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+)
+
+// DuplicateReportController 接口表重复数据检测控制器
+type DuplicateReportController struct {
+}
+
+// NewDuplicateReportController 创建重复数据检测控制器实例
+func NewDuplicateReportController() *DuplicateReportController {
+	return &DuplicateReportController{}
+}
+
+// CreateDuplicateReportRequest 发起重复数据检测请求
+type CreateDuplicateReportRequest struct {
+	KeyFields    []string                     `json:"key_fields" example:"plate_no,pass_time"` // 为空时比较除主键和派生列外的全部字段
+	MatchOptions models.DuplicateMatchOptions `json:"match_options"`
+	SampleSize   int                          `json:"sample_size" validate:"min=0" example:"10000"` // 默认10000，最多100000
+}
+
+// CreateDuplicateReport 发起接口表重复数据检测
+// @Summary 发起接口表重复数据检测
+// @Description 对接口表采样，按键字段精确(exact)或模糊(fuzzy)匹配找出重复簇；检测在后台执行，返回running状态的报告，完成后报告中包含重复簇的行数、样例行和建议的去重配置
+// @Tags 数据基础库
+// @Accept json
+// @Produce json
+// @Param id path string true "接口ID"
+// @Param request body CreateDuplicateReportRequest true "检测参数"
+// @Success 200 {object} APIResponse[models.DuplicateReport] "已发起检测"
+// @Failure 400 {object} APIResponse[any] "请求参数错误"
+// @Failure 404 {object} APIResponse[any] "接口不存在"
+// @Router /basic-libraries/interfaces/{id}/duplicate-reports [post]
+func (c *DuplicateReportController) CreateDuplicateReport(w http.ResponseWriter, r *http.Request) {
+	var req CreateDuplicateReportRequest
+	if !decodeAndValidate(w, r, &req) {
+		return
+	}
+
+	report, err := service.GlobalDuplicateReportService.StartReport(r.Context(), chi.URLParam(r, "id"), &basic_library.DuplicateReportRequest{
+		KeyFields:    req.KeyFields,
+		MatchOptions: req.MatchOptions,
+		SampleSize:   req.SampleSize,
+	}, getCurrentUsername(r))
+	if err != nil {
+		if errors.Is(err, basic_library.ErrInvalidDuplicateRequest) {
+			render.JSON(w, r, BadRequestResponse("发起重复数据检测失败", err))
+			return
+		}
+		render.JSON(w, r, MapErrorResponse("发起重复数据检测失败", err))
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("已发起重复数据检测", report))
+}
+
+// GetDuplicateReports 获取接口表重复数据检测报告列表
+// @Summary 获取接口表重复数据检测报告列表
+// @Description 获取接口最近20次重复数据检测的汇总结果，不含重复簇明细
+// @Tags 数据基础库
+// @Produce json
+// @Param id path string true "接口ID"
+// @Success 200 {object} APIResponse[[]models.DuplicateReport] "获取成功"
+// @Failure 404 {object} APIResponse[any] "接口不存在"
+// @Router /basic-libraries/interfaces/{id}/duplicate-reports [get]
+func (c *DuplicateReportController) GetDuplicateReports(w http.ResponseWriter, r *http.Request) {
+	reports, err := service.GlobalDuplicateReportService.ListReports(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		render.JSON(w, r, MapErrorResponse("获取重复数据检测报告失败", err))
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("获取重复数据检测报告成功", reports))
+}
+
+// GetDuplicateReport 获取接口表重复数据检测报告详情
+// @Summary 获取接口表重复数据检测报告详情
+// @Description 获取一次重复数据检测的状态和结果，包括重复簇的键字段取值、行数、样例行以及建议的去重配置
+// @Tags 数据基础库
+// @Produce json
+// @Param id path string true "接口ID"
+// @Param report_id path string true "报告ID"
+// @Success 200 {object} APIResponse[models.DuplicateReport] "获取成功"
+// @Failure 404 {object} APIResponse[any] "接口或报告不存在"
+// @Router /basic-libraries/interfaces/{id}/duplicate-reports/{report_id} [get]
+func (c *DuplicateReportController) GetDuplicateReport(w http.ResponseWriter, r *http.Request) {
+	report, err := service.GlobalDuplicateReportService.GetReport(r.Context(), chi.URLParam(r, "id"), chi.URLParam(r, "report_id"))
+	if err != nil {
+		render.JSON(w, r, MapErrorResponse("获取重复数据检测报告失败", err))
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("获取重复数据检测报告成功", report))
+}
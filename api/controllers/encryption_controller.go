@@ -0,0 +1,269 @@
+/*
+ * @module api/controllers/encryption_controller
+ * @description 敏感列加密控制器，提供列加密配置的增删改查、密钥使用统计、密钥轮换和列解密接口
+ * @architecture 分层架构 - 控制器层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow HTTP请求 -> 控制器 -> 敏感列加密服务 -> 数据库
+ * @rules 统一的错误处理和响应格式；列位置创建后不可修改；列中仍有密文时不能删除配置
+ * @dependencies datahub-service/service, datahub-service/service/encryption, github.com/go-chi/chi/v5
+ * @refs service/encryption/encryption_service.go
+ */
+
+package controllers
+
+import (
+	"datahub-service/service"
+	"datahub-service/service/encryption"
+	"datahub-service/service/models"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"gorm.io/gorm"
+)
+
+// EncryptionController 敏感列加密控制器
+type EncryptionController struct {
+}
+
+// NewEncryptionController 创建敏感列加密控制器实例
+func NewEncryptionController() *EncryptionController {
+	return &EncryptionController{}
+}
+
+// CreateColumnEncryptionRequest 创建列加密配置请求结构
+type CreateColumnEncryptionRequest struct {
+	SchemaName      string   `json:"schema_name" validate:"required" example:"population_lib"`
+	TableName       string   `json:"table_name" validate:"required" example:"residents"`
+	ColumnName      string   `json:"column_name" validate:"required" example:"id_card_no"`
+	Mode            string   `json:"mode" example:"aes"` // aes/pgcrypto，默认aes
+	AuthorizedRoles []string `json:"authorized_roles" example:"steward"`
+	Description     string   `json:"description"`
+}
+
+// UpdateColumnEncryptionRequest 更新列加密配置请求结构
+type UpdateColumnEncryptionRequest struct {
+	Mode            *string   `json:"mode,omitempty" example:"pgcrypto"`
+	AuthorizedRoles *[]string `json:"authorized_roles,omitempty"`
+	Description     *string   `json:"description,omitempty"`
+}
+
+// ColumnEncryptionListResponse 列加密配置列表响应结构
+type ColumnEncryptionListResponse struct {
+	List []models.ColumnEncryption `json:"list"`
+	models.PageMeta
+}
+
+// GetEncryptionStatus 获取加密功能状态
+// @Summary 获取加密功能状态
+// @Description 获取密钥是否已配置、当前密钥版本和全部密钥版本
+// @Tags 敏感列加密
+// @Produce json
+// @Success 200 {object} APIResponse[encryption.Status] "获取成功"
+// @Router /encryption/status [get]
+func (c *EncryptionController) GetEncryptionStatus(w http.ResponseWriter, r *http.Request) {
+	render.JSON(w, r, SuccessResponse("获取加密功能状态成功", service.GlobalEncryptionService.GetStatus()))
+}
+
+// GetColumnEncryptions 获取列加密配置列表
+// @Summary 获取列加密配置列表
+// @Description 分页获取列加密配置，可按schema和表过滤
+// @Tags 敏感列加密
+// @Produce json
+// @Param page query int false "页码" default(1)
+// @Param size query int false "每页数量" default(10)
+// @Param schema_name query string false "schema名称"
+// @Param table_name query string false "表名"
+// @Success 200 {object} APIResponse[ColumnEncryptionListResponse] "获取成功"
+// @Router /encryption/columns [get]
+func (c *EncryptionController) GetColumnEncryptions(w http.ResponseWriter, r *http.Request) {
+	page, size := parsePagination(r)
+	query := r.URL.Query()
+
+	configs, total, err := service.GlobalEncryptionService.GetColumnEncryptions(r.Context(), page, size, query.Get("schema_name"), query.Get("table_name"))
+	if err != nil {
+		render.JSON(w, r, MapErrorResponse("获取列加密配置列表失败", err))
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("获取列加密配置列表成功", ColumnEncryptionListResponse{
+		List:     configs,
+		PageMeta: models.NewPageMeta(total, page, size),
+	}))
+}
+
+// CreateColumnEncryption 创建列加密配置
+// @Summary 创建列加密配置
+// @Description 指定需要加密存储的文本列，之后同步写入的值自动加密；已有数据需调用轮换接口加密
+// @Tags 敏感列加密
+// @Accept json
+// @Produce json
+// @Param request body CreateColumnEncryptionRequest true "列加密配置"
+// @Success 200 {object} APIResponse[models.ColumnEncryption] "创建成功"
+// @Failure 400 {object} APIResponse[any] "请求参数错误"
+// @Router /encryption/columns [post]
+func (c *EncryptionController) CreateColumnEncryption(w http.ResponseWriter, r *http.Request) {
+	var req CreateColumnEncryptionRequest
+	if !decodeAndValidate(w, r, &req) {
+		return
+	}
+
+	operator := getCurrentUsername(r)
+	cfg := &models.ColumnEncryption{
+		SchemaName:      req.SchemaName,
+		TableName:       req.TableName,
+		ColumnName:      req.ColumnName,
+		Mode:            req.Mode,
+		AuthorizedRoles: req.AuthorizedRoles,
+		Description:     req.Description,
+		CreatedBy:       operator,
+		UpdatedBy:       operator,
+	}
+	if err := service.GlobalEncryptionService.CreateColumnEncryption(r.Context(), cfg); err != nil {
+		render.JSON(w, r, BadRequestResponse("创建列加密配置失败", err))
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("创建列加密配置成功", cfg))
+}
+
+// GetColumnEncryption 获取列加密配置详情
+// @Summary 获取列加密配置详情
+// @Description 根据ID获取列加密配置
+// @Tags 敏感列加密
+// @Produce json
+// @Param id path string true "配置ID"
+// @Success 200 {object} APIResponse[models.ColumnEncryption] "获取成功"
+// @Failure 404 {object} APIResponse[any] "配置不存在"
+// @Router /encryption/columns/{id} [get]
+func (c *EncryptionController) GetColumnEncryption(w http.ResponseWriter, r *http.Request) {
+	cfg, err := service.GlobalEncryptionService.GetColumnEncryption(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		render.JSON(w, r, MapErrorResponse("获取列加密配置失败", err))
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("获取列加密配置成功", cfg))
+}
+
+// UpdateColumnEncryption 更新列加密配置
+// @Summary 更新列加密配置
+// @Description 更新加密方式、可查看明文的角色和描述；修改加密方式后需调用轮换接口转换已有数据
+// @Tags 敏感列加密
+// @Accept json
+// @Produce json
+// @Param id path string true "配置ID"
+// @Param request body UpdateColumnEncryptionRequest true "更新内容"
+// @Success 200 {object} APIResponse[any] "更新成功"
+// @Failure 400 {object} APIResponse[any] "请求参数错误"
+// @Failure 404 {object} APIResponse[any] "配置不存在"
+// @Router /encryption/columns/{id} [put]
+func (c *EncryptionController) UpdateColumnEncryption(w http.ResponseWriter, r *http.Request) {
+	var req UpdateColumnEncryptionRequest
+	if !decodeAndValidate(w, r, &req) {
+		return
+	}
+
+	updates := map[string]interface{}{"updated_by": getCurrentUsername(r)}
+	if req.Mode != nil {
+		updates["mode"] = *req.Mode
+	}
+	if req.AuthorizedRoles != nil {
+		updates["authorized_roles"] = models.JSONBStringArray(*req.AuthorizedRoles)
+	}
+	if req.Description != nil {
+		updates["description"] = *req.Description
+	}
+
+	if err := service.GlobalEncryptionService.UpdateColumnEncryption(r.Context(), chi.URLParam(r, "id"), updates); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			render.JSON(w, r, NotFoundResponse("列加密配置不存在", err))
+			return
+		}
+		render.JSON(w, r, BadRequestResponse("更新列加密配置失败", err))
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("更新列加密配置成功", nil))
+}
+
+// DeleteColumnEncryption 删除列加密配置
+// @Summary 删除列加密配置
+// @Description 删除列加密配置，列中仍有密文时需先调用解密接口
+// @Tags 敏感列加密
+// @Produce json
+// @Param id path string true "配置ID"
+// @Success 200 {object} APIResponse[any] "删除成功"
+// @Failure 404 {object} APIResponse[any] "配置不存在"
+// @Failure 409 {object} APIResponse[any] "列中仍有密文"
+// @Router /encryption/columns/{id} [delete]
+func (c *EncryptionController) DeleteColumnEncryption(w http.ResponseWriter, r *http.Request) {
+	if err := service.GlobalEncryptionService.DeleteColumnEncryption(r.Context(), chi.URLParam(r, "id")); err != nil {
+		if errors.Is(err, encryption.ErrColumnHasCiphertext) {
+			render.JSON(w, r, ConflictResponse("删除列加密配置失败", err))
+			return
+		}
+		render.JSON(w, r, MapErrorResponse("删除列加密配置失败", err))
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("删除列加密配置成功", nil))
+}
+
+// GetColumnKeyUsage 获取列的密钥使用情况
+// @Summary 获取列的密钥使用情况
+// @Description 统计列中各加密方式和密钥版本的值数量，plaintext为未加密的值；旧版本数量为0后方可移除旧密钥
+// @Tags 敏感列加密
+// @Produce json
+// @Param id path string true "配置ID"
+// @Success 200 {object} APIResponse[encryption.KeyUsage] "获取成功"
+// @Failure 404 {object} APIResponse[any] "配置不存在"
+// @Router /encryption/columns/{id}/key-usage [get]
+func (c *EncryptionController) GetColumnKeyUsage(w http.ResponseWriter, r *http.Request) {
+	usage, err := service.GlobalEncryptionService.GetKeyUsage(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		render.JSON(w, r, MapErrorResponse("获取密钥使用情况失败", err))
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("获取密钥使用情况成功", usage))
+}
+
+// RotateColumnKey 轮换列的加密密钥
+// @Summary 轮换列的加密密钥
+// @Description 使用当前密钥版本和配置的加密方式重新加密列中的旧版本密文和未加密的值
+// @Tags 敏感列加密
+// @Produce json
+// @Param id path string true "配置ID"
+// @Success 200 {object} APIResponse[encryption.RewriteResult] "轮换完成"
+// @Failure 404 {object} APIResponse[any] "配置不存在"
+// @Router /encryption/columns/{id}/rotate [post]
+func (c *EncryptionController) RotateColumnKey(w http.ResponseWriter, r *http.Request) {
+	result, err := service.GlobalEncryptionService.RotateColumn(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		render.JSON(w, r, MapErrorResponse("轮换密钥失败", err))
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("轮换密钥完成", result))
+}
+
+// DecryptColumn 解密列数据
+// @Summary 解密列数据
+// @Description 将列中的密文还原为明文，用于取消列加密；请先删除或停用相关同步，避免解密期间写入新密文
+// @Tags 敏感列加密
+// @Produce json
+// @Param id path string true "配置ID"
+// @Success 200 {object} APIResponse[encryption.RewriteResult] "解密完成"
+// @Failure 404 {object} APIResponse[any] "配置不存在"
+// @Router /encryption/columns/{id}/decrypt [post]
+func (c *EncryptionController) DecryptColumn(w http.ResponseWriter, r *http.Request) {
+	result, err := service.GlobalEncryptionService.DecryptColumn(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		render.JSON(w, r, MapErrorResponse("解密列数据失败", err))
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("解密列数据完成", result))
+}
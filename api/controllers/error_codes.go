@@ -0,0 +1,79 @@
+/*
+ * @module api/controllers/error_codes
+ * @description 错误码目录，APIResponse.code携带机器可读的错误类型，前端据此分支处理而不依赖中文提示
+ * @architecture 分层架构 - 控制器层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow 控制器返回错误 -> 按业务状态码映射默认错误码 / 显式指定错误码 -> 响应code字段
+ * @rules 错误码一经发布不修改含义；新增错误码需同时加入ErrorCodeCatalog
+ * @dependencies net/http
+ * @refs api/controllers/response.go, api/controllers/validator.go
+ */
+
+package controllers
+
+// 错误码
+const (
+	CodeInvalidRequest     = "INVALID_REQUEST"     // 请求体不是合法的JSON或字段类型不匹配
+	CodeValidationFailed   = "VALIDATION_FAILED"   // 请求参数校验失败，data.errors为字段错误列表
+	CodeBadRequest         = "BAD_REQUEST"         // 其他请求参数错误
+	CodeUnauthorized       = "UNAUTHORIZED"        // 未认证
+	CodeForbidden          = "FORBIDDEN"           // 无权限或当前环境不允许
+	CodeNotFound           = "NOT_FOUND"           // 资源不存在
+	CodeConflict           = "CONFLICT"            // 资源状态不允许该操作
+	CodeVersionConflict    = "VERSION_CONFLICT"    // 乐观锁版本冲突，data.current_version为最新版本
+	CodeDuplicateResource  = "DUPLICATE_RESOURCE"  // 违反唯一约束，数据已存在
+	CodeReferenceViolation = "REFERENCE_VIOLATION" // 违反外键约束，关联数据不存在或仍被引用
+	CodeBatchRolledBack    = "BATCH_ROLLED_BACK"   // 批量操作存在失败项已整体回滚，data.items为逐项结果
+	CodeQuotaExceeded      = "QUOTA_EXCEEDED"      // 库或租户用量达到配额上限
+	CodeInternalError      = "INTERNAL_ERROR"      // 服务器内部错误
+	CodeServiceUnavailable = "SERVICE_UNAVAILABLE" // 依赖服务不可用
+)
+
+// ErrorCodeInfo 错误码说明
+type ErrorCodeInfo struct {
+	Code        string `json:"code" example:"VALIDATION_FAILED"`
+	Status      int    `json:"status" example:"400"`
+	Description string `json:"description" example:"请求参数校验失败"`
+}
+
+// ErrorCodeCatalog 错误码目录
+var ErrorCodeCatalog = []ErrorCodeInfo{
+	{Code: CodeInvalidRequest, Status: StatusBadRequest, Description: "请求体不是合法的JSON或字段类型不匹配"},
+	{Code: CodeValidationFailed, Status: StatusBadRequest, Description: "请求参数校验失败，data.errors为字段错误列表"},
+	{Code: CodeBadRequest, Status: StatusBadRequest, Description: "请求参数错误"},
+	{Code: CodeUnauthorized, Status: StatusUnauthorized, Description: "未认证"},
+	{Code: CodeForbidden, Status: StatusForbidden, Description: "无权限或当前环境不允许该操作"},
+	{Code: CodeNotFound, Status: StatusNotFound, Description: "资源不存在"},
+	{Code: CodeConflict, Status: StatusConflict, Description: "资源状态不允许该操作"},
+	{Code: CodeVersionConflict, Status: StatusConflict, Description: "数据已被修改，data.current_version为最新版本"},
+	{Code: CodeDuplicateResource, Status: StatusConflict, Description: "数据已存在（违反唯一约束）"},
+	{Code: CodeReferenceViolation, Status: StatusUnprocessableEntity, Description: "关联数据不存在或仍被引用（违反外键约束）"},
+	{Code: CodeBatchRolledBack, Status: StatusConflict, Description: "批量操作存在失败项，已整体回滚，data.items为逐项结果"},
+	{Code: CodeQuotaExceeded, Status: StatusForbidden, Description: "库或租户的数据行数、存储空间或同步任务数达到配额上限"},
+	{Code: CodeInternalError, Status: StatusInternalError, Description: "服务器内部错误"},
+	{Code: CodeServiceUnavailable, Status: StatusServiceUnavailable, Description: "依赖服务不可用"},
+}
+
+// defaultErrorCode 业务状态码对应的默认错误码
+func defaultErrorCode(businessStatus int) string {
+	switch businessStatus {
+	case StatusBadRequest:
+		return CodeBadRequest
+	case StatusUnauthorized:
+		return CodeUnauthorized
+	case StatusForbidden:
+		return CodeForbidden
+	case StatusNotFound:
+		return CodeNotFound
+	case StatusConflict:
+		return CodeConflict
+	case StatusUnprocessableEntity:
+		return CodeReferenceViolation
+	case StatusServiceUnavailable:
+		return CodeServiceUnavailable
+	case StatusSuccess:
+		return ""
+	default:
+		return CodeInternalError
+	}
+}
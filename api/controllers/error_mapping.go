@@ -0,0 +1,67 @@
+/*
+ * @module api/controllers/error_mapping
+ * @description 服务层/数据库错误到响应状态码的映射，避免记录不存在、唯一约束冲突等客户端可处理的错误统一返回500
+ * @architecture 分层架构 - 控制器层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow 服务层返回错误 -> 按错误类型识别 -> 记录不存在404 / 唯一约束冲突409 / 外键约束冲突422 / 超出配额403 / 其他500
+ * @rules 服务层常以%v包装错误导致类型丢失，因此在errors.Is之外按PostgreSQL SQLSTATE和SQLite错误信息兜底识别
+ * @dependencies gorm.io/gorm
+ * @refs api/controllers/response.go, api/controllers/error_codes.go
+ */
+
+package controllers
+
+import (
+	"datahub-service/service/capacity"
+	"errors"
+	"strings"
+
+	"github.com/go-chi/render"
+	"gorm.io/gorm"
+)
+
+// 各类数据库错误的识别特征（小写）
+var (
+	notFoundMarkers   = []string{"record not found"}
+	duplicateMarkers  = []string{"sqlstate 23505", "duplicate key value", "unique constraint failed"}
+	foreignKeyMarkers = []string{"sqlstate 23503", "violates foreign key constraint", "foreign key constraint failed"}
+)
+
+// mapError 识别错误类型，返回业务状态码、错误码和原因说明；无法识别时ok为false
+func mapError(err error) (status int, code string, reason string, ok bool) {
+	if err == nil {
+		return 0, "", "", false
+	}
+
+	msg := strings.ToLower(err.Error())
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound) || containsAny(msg, notFoundMarkers):
+		return StatusNotFound, CodeNotFound, "记录不存在", true
+	case errors.Is(err, gorm.ErrDuplicatedKey) || containsAny(msg, duplicateMarkers):
+		return StatusConflict, CodeDuplicateResource, "数据已存在", true
+	case errors.Is(err, gorm.ErrForeignKeyViolated) || containsAny(msg, foreignKeyMarkers):
+		return StatusUnprocessableEntity, CodeReferenceViolation, "关联数据不存在或仍被引用", true
+	case errors.Is(err, capacity.ErrQuotaExceeded):
+		return StatusForbidden, CodeQuotaExceeded, "超出配额", true
+	default:
+		return 0, "", "", false
+	}
+}
+
+// MapErrorResponse 按错误类型创建错误响应，无法识别的错误返回服务器内部错误
+func MapErrorResponse(msg string, err error) render.Renderer {
+	if status, code, reason, ok := mapError(err); ok {
+		return ErrorResponseWithCode(status, code, msg+": "+reason, err)
+	}
+	return InternalErrorResponse(msg, err)
+}
+
+// containsAny 判断字符串是否包含任一特征
+func containsAny(s string, markers []string) bool {
+	for _, marker := range markers {
+		if strings.Contains(s, marker) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,95 @@
+/*
+ * @module api/controllers/error_mapping_test
+ * @description 错误映射测试，覆盖记录不存在、唯一约束冲突、外键约束冲突及包装后的错误
+ * @architecture 测试层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow 构造数据库错误 -> 映射响应 -> 验证业务状态码和错误码
+ * @rules 使用SQLite内存数据库产生真实约束错误，PostgreSQL错误按驱动错误信息格式构造
+ * @dependencies github.com/stretchr/testify, gorm.io/driver/sqlite
+ * @refs api/controllers/error_mapping.go
+ */
+
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+type mappingParent struct {
+	ID   string `gorm:"primaryKey"`
+	Name string `gorm:"uniqueIndex"`
+}
+
+type mappingChild struct {
+	ID       string `gorm:"primaryKey"`
+	ParentID string
+	Parent   mappingParent `gorm:"foreignKey:ParentID"`
+}
+
+func setupMappingDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open("file::memory:?_foreign_keys=on"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+	require.NoError(t, db.Exec("PRAGMA foreign_keys = ON").Error)
+	require.NoError(t, db.AutoMigrate(&mappingParent{}, &mappingChild{}))
+	require.NoError(t, db.Create(&mappingParent{ID: "p1", Name: "parent"}).Error)
+	return db
+}
+
+func mappedResponse(msg string, err error) *APIResponse[any] {
+	return MapErrorResponse(msg, err).(*APIResponse[any])
+}
+
+func TestMapErrorResponseWithSQLite(t *testing.T) {
+	db := setupMappingDB(t)
+
+	var parent mappingParent
+	err := db.First(&parent, "id = ?", "missing").Error
+	resp := mappedResponse("获取失败", err)
+	assert.Equal(t, StatusNotFound, resp.Status)
+	assert.Equal(t, CodeNotFound, resp.Code)
+	assert.Equal(t, "获取失败: 记录不存在", resp.Msg)
+
+	err = db.Create(&mappingParent{ID: "p2", Name: "parent"}).Error
+	resp = mappedResponse("创建失败", err)
+	assert.Equal(t, StatusConflict, resp.Status)
+	assert.Equal(t, CodeDuplicateResource, resp.Code)
+
+	err = db.Omit("Parent").Create(&mappingChild{ID: "c1", ParentID: "missing"}).Error
+	resp = mappedResponse("创建失败", err)
+	assert.Equal(t, StatusUnprocessableEntity, resp.Status)
+	assert.Equal(t, CodeReferenceViolation, resp.Code)
+}
+
+func TestMapErrorResponseWrappedErrors(t *testing.T) {
+	cases := []struct {
+		name   string
+		err    error
+		status int
+		code   string
+	}{
+		{"包装的记录不存在", fmt.Errorf("获取规则失败: %w", gorm.ErrRecordNotFound), StatusNotFound, CodeNotFound},
+		{"%v包装的记录不存在", fmt.Errorf("获取规则失败: %v", gorm.ErrRecordNotFound), StatusNotFound, CodeNotFound},
+		{"gorm翻译的唯一约束", gorm.ErrDuplicatedKey, StatusConflict, CodeDuplicateResource},
+		{"PostgreSQL唯一约束", errors.New(`创建失败: ERROR: duplicate key value violates unique constraint "uk_name" (SQLSTATE 23505)`), StatusConflict, CodeDuplicateResource},
+		{"PostgreSQL外键约束", errors.New(`ERROR: update or delete on table "a" violates foreign key constraint "fk_b" on table "b" (SQLSTATE 23503)`), StatusUnprocessableEntity, CodeReferenceViolation},
+		{"其他错误", errors.New("连接超时"), StatusInternalError, CodeInternalError},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			resp := mappedResponse("操作失败", tc.err)
+			assert.Equal(t, tc.status, resp.Status)
+			assert.Equal(t, tc.code, resp.Code)
+		})
+	}
+}
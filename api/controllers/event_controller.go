@@ -18,7 +18,6 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
-	"strconv"
 	"time"
 
 	"github.com/go-chi/chi/v5"
@@ -108,12 +107,11 @@ func (c *EventController) HandleSSE(w http.ResponseWriter, r *http.Request) {
 // @Accept json
 // @Produce json
 // @Param request body SendEventRequest true "发送事件请求"
-// @Success 200 {object} APIResponse
+// @Success 200 {object} APIResponse[any]
 // @Router /events/send [post]
 func (c *EventController) SendEvent(w http.ResponseWriter, r *http.Request) {
 	var req SendEventRequest
-	if err := render.DecodeJSON(r.Body, &req); err != nil {
-		render.Render(w, r, ErrorResponse(http.StatusBadRequest, "请求参数解析失败", err))
+	if !decodeAndValidate(w, r, &req) {
 		return
 	}
 
@@ -137,7 +135,7 @@ func (c *EventController) SendEvent(w http.ResponseWriter, r *http.Request) {
 
 	// 发送事件
 	if err := c.eventService.SendEventToUser(req.UserName, event); err != nil {
-		render.Render(w, r, ErrorResponse(http.StatusInternalServerError, "发送事件失败", err))
+		render.Render(w, r, MapErrorResponse("发送事件失败", err))
 		return
 	}
 
@@ -153,12 +151,11 @@ func (c *EventController) SendEvent(w http.ResponseWriter, r *http.Request) {
 // @Accept json
 // @Produce json
 // @Param request body BroadcastEventRequest true "广播事件请求"
-// @Success 200 {object} APIResponse
+// @Success 200 {object} APIResponse[any]
 // @Router /events/broadcast [post]
 func (c *EventController) BroadcastEvent(w http.ResponseWriter, r *http.Request) {
 	var req BroadcastEventRequest
-	if err := render.DecodeJSON(r.Body, &req); err != nil {
-		render.Render(w, r, ErrorResponse(http.StatusBadRequest, "请求参数解析失败", err))
+	if !decodeAndValidate(w, r, &req) {
 		return
 	}
 
@@ -177,7 +174,7 @@ func (c *EventController) BroadcastEvent(w http.ResponseWriter, r *http.Request)
 
 	// 广播事件
 	if err := c.eventService.BroadcastEvent(event); err != nil {
-		render.Render(w, r, ErrorResponse(http.StatusInternalServerError, "广播事件失败", err))
+		render.Render(w, r, MapErrorResponse("广播事件失败", err))
 		return
 	}
 
@@ -212,14 +209,12 @@ type BroadcastEventRequest struct {
 // @Param user_name query string false "用户名过滤"
 // @Param is_active query bool false "连接状态过滤"
 // @Param client_ip query string false "客户端IP过滤"
-// @Success 200 {object} APIResponse{data=SSEConnectionListResponse} "获取成功"
-// @Failure 400 {object} APIResponse "请求参数错误"
-// @Failure 500 {object} APIResponse "服务器内部错误"
+// @Success 200 {object} APIResponse[SSEConnectionListResponse] "获取成功"
+// @Failure 400 {object} APIResponse[any] "请求参数错误"
+// @Failure 500 {object} APIResponse[any] "服务器内部错误"
 // @Router /events/connections [get]
 func (c *EventController) GetSSEConnectionList(w http.ResponseWriter, r *http.Request) {
 	// 解析查询参数
-	page := 1
-	size := 10
 	userName := r.URL.Query().Get("user_name")
 	clientIP := r.URL.Query().Get("client_ip")
 	isActiveStr := r.URL.Query().Get("is_active")
@@ -235,26 +230,19 @@ func (c *EventController) GetSSEConnectionList(w http.ResponseWriter, r *http.Re
 		}
 	}
 
-	if p, err := strconv.Atoi(r.URL.Query().Get("page")); err == nil && p > 0 {
-		page = p
-	}
-	if s, err := strconv.Atoi(r.URL.Query().Get("size")); err == nil && s > 0 && s <= 100 {
-		size = s
-	}
+	page, size := parsePagination(r)
 
 	// 调用服务层方法
 	connections, total, err := c.eventService.GetSSEConnectionList(page, size, userName, clientIP, isActive)
 	if err != nil {
-		render.Render(w, r, ErrorResponse(http.StatusInternalServerError, "获取SSE连接列表失败", err))
+		render.Render(w, r, MapErrorResponse("获取SSE连接列表失败", err))
 		return
 	}
 
 	// 构建响应
 	response := SSEConnectionListResponse{
-		List:  connections,
-		Total: total,
-		Page:  page,
-		Size:  size,
+		List:     connections,
+		PageMeta: models.NewPageMeta(total, page, size),
 	}
 
 	render.Render(w, r, SuccessResponse("获取SSE连接列表成功", response))
@@ -272,14 +260,12 @@ func (c *EventController) GetSSEConnectionList(w http.ResponseWriter, r *http.Re
 // @Param event_type query string false "事件类型过滤"
 // @Param sent query bool false "发送状态过滤"
 // @Param read query bool false "读取状态过滤"
-// @Success 200 {object} APIResponse{data=EventHistoryListResponse} "获取成功"
-// @Failure 400 {object} APIResponse "请求参数错误"
-// @Failure 500 {object} APIResponse "服务器内部错误"
+// @Success 200 {object} APIResponse[EventHistoryListResponse] "获取成功"
+// @Failure 400 {object} APIResponse[any] "请求参数错误"
+// @Failure 500 {object} APIResponse[any] "服务器内部错误"
 // @Router /events/history [get]
 func (c *EventController) GetEventHistoryList(w http.ResponseWriter, r *http.Request) {
 	// 解析查询参数
-	page := 1
-	size := 10
 	userName := r.URL.Query().Get("user_name")
 	eventType := r.URL.Query().Get("event_type")
 	sentStr := r.URL.Query().Get("sent")
@@ -307,26 +293,19 @@ func (c *EventController) GetEventHistoryList(w http.ResponseWriter, r *http.Req
 		}
 	}
 
-	if p, err := strconv.Atoi(r.URL.Query().Get("page")); err == nil && p > 0 {
-		page = p
-	}
-	if s, err := strconv.Atoi(r.URL.Query().Get("size")); err == nil && s > 0 && s <= 100 {
-		size = s
-	}
+	page, size := parsePagination(r)
 
 	// 调用服务层方法
 	events, total, err := c.eventService.GetEventHistoryList(page, size, userName, eventType, sent, read)
 	if err != nil {
-		render.Render(w, r, ErrorResponse(http.StatusInternalServerError, "获取事件历史列表失败", err))
+		render.Render(w, r, MapErrorResponse("获取事件历史列表失败", err))
 		return
 	}
 
 	// 构建响应
 	response := EventHistoryListResponse{
-		List:  events,
-		Total: total,
-		Page:  page,
-		Size:  size,
+		List:     events,
+		PageMeta: models.NewPageMeta(total, page, size),
 	}
 
 	render.Render(w, r, SuccessResponse("获取事件历史列表成功", response))
@@ -336,18 +315,14 @@ func (c *EventController) GetEventHistoryList(w http.ResponseWriter, r *http.Req
 
 // SSEConnectionListResponse SSE连接列表响应结构
 type SSEConnectionListResponse struct {
-	List  []models.SSEConnection `json:"list"`
-	Total int64                  `json:"total"`
-	Page  int                    `json:"page"`
-	Size  int                    `json:"size"`
+	List []models.SSEConnection `json:"list"`
+	models.PageMeta
 }
 
 // EventHistoryListResponse 事件历史列表响应结构
 type EventHistoryListResponse struct {
-	List  []models.SSEEvent `json:"list"`
-	Total int64             `json:"total"`
-	Page  int               `json:"page"`
-	Size  int               `json:"size"`
+	List []models.SSEEvent `json:"list"`
+	models.PageMeta
 }
 
 // toJSON 将对象转换为JSON字符串
@@ -0,0 +1,73 @@
+/*
+ * @module api/controllers/event_log_controller
+ * @description 应用事件查询接口，按响应中的request_id查找该请求触发的任务启动、批次失败等事件
+ * @architecture 分层架构 - 控制器层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow HTTP请求 -> 控制器 -> 应用事件日志服务 -> 数据库
+ * @rules 只读接口；按创建时间倒序分页返回
+ * @dependencies datahub-service/service/eventlog
+ * @refs service/eventlog/eventlog_service.go, api/middleware/request_id.go
+ */
+
+package controllers
+
+import (
+	"datahub-service/service"
+	"datahub-service/service/eventlog"
+	"datahub-service/service/models"
+	"net/http"
+
+	"github.com/go-chi/render"
+)
+
+// EventLogController 应用事件控制器
+type EventLogController struct {
+	eventLogService *eventlog.Service
+}
+
+// NewEventLogController 创建应用事件控制器实例
+func NewEventLogController() *EventLogController {
+	return &EventLogController{
+		eventLogService: service.GlobalEventLogService,
+	}
+}
+
+// ApplicationEventListResponse 应用事件列表响应
+type ApplicationEventListResponse struct {
+	List []models.ApplicationEvent `json:"list"`
+	models.PageMeta
+}
+
+// GetApplicationEvents 查询应用事件
+// @Summary 查询应用事件
+// @Description 按请求ID、事件类型或对象ID查询应用事件，请求ID取自响应体request_id或响应头X-Request-ID
+// @Tags 系统配置
+// @Produce json
+// @Param request_id query string false "请求ID"
+// @Param event_type query string false "事件类型" Enums(task_started, task_completed, task_failed, batch_failed)
+// @Param object_id query string false "对象ID，如同步任务ID"
+// @Param page query int false "页码" default(1)
+// @Param size query int false "每页数量" default(10)
+// @Success 200 {object} APIResponse[ApplicationEventListResponse] "获取成功"
+// @Failure 500 {object} APIResponse[any] "服务器内部错误"
+// @Router /admin/events [get]
+func (c *EventLogController) GetApplicationEvents(w http.ResponseWriter, r *http.Request) {
+	page, size := parsePagination(r)
+	query := r.URL.Query()
+	events, total, err := c.eventLogService.ListEvents(eventlog.ListQuery{
+		RequestID: query.Get("request_id"),
+		EventType: query.Get("event_type"),
+		ObjectID:  query.Get("object_id"),
+		Page:      page,
+		Size:      size,
+	})
+	if err != nil {
+		render.JSON(w, r, InternalErrorResponse("查询应用事件失败", err))
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("查询应用事件成功", ApplicationEventListResponse{
+		List:     events,
+		PageMeta: models.NewPageMeta(total, page, size),
+	}))
+}
@@ -0,0 +1,53 @@
+/*
+ * @module api/controllers/execution_diagnostics_controller
+ * @description 同步执行诊断接口，为技术支持一次性导出某次基础库同步执行的配置快照、数据源健康、批次耗时、错误调用栈和相关事件
+ * @architecture 分层架构 - 控制器层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow HTTP请求 -> 控制器 -> 同步执行诊断服务 -> 数据库
+ * @rules 只读接口；敏感配置由服务层脱敏后返回
+ * @dependencies datahub-service/service/basic_library, github.com/go-chi/chi/v5
+ * @refs service/basic_library/diagnostics_service.go
+ */
+
+package controllers
+
+import (
+	"datahub-service/service"
+	"datahub-service/service/basic_library"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+)
+
+// ExecutionDiagnosticsController 同步执行诊断控制器
+type ExecutionDiagnosticsController struct {
+	diagnosticsService *basic_library.ExecutionDiagnosticsService
+}
+
+// NewExecutionDiagnosticsController 创建同步执行诊断控制器实例
+func NewExecutionDiagnosticsController() *ExecutionDiagnosticsController {
+	return &ExecutionDiagnosticsController{
+		diagnosticsService: basic_library.NewExecutionDiagnosticsService(service.DB),
+	}
+}
+
+// GetExecutionDiagnostics 获取同步执行诊断数据
+// @Summary 获取同步执行诊断数据
+// @Description 汇总指定基础库同步执行的任务配置、执行时的接口配置快照、数据源健康状态、各接口批次耗时、错误及调用栈和执行期间的应用事件；敏感配置已脱敏
+// @Tags 基础库同步任务
+// @Produce json
+// @Param id path string true "执行记录ID"
+// @Success 200 {object} APIResponse[basic_library.ExecutionDiagnostics] "获取成功"
+// @Failure 404 {object} APIResponse[any] "执行记录不存在"
+// @Failure 500 {object} APIResponse[any] "服务器内部错误"
+// @Router /admin/executions/{id}/diagnostics [get]
+func (c *ExecutionDiagnosticsController) GetExecutionDiagnostics(w http.ResponseWriter, r *http.Request) {
+	diagnostics, err := c.diagnosticsService.GetExecutionDiagnostics(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		render.JSON(w, r, MapErrorResponse("获取同步执行诊断数据失败", err))
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("获取同步执行诊断数据成功", diagnostics))
+}
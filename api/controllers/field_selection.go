@@ -0,0 +1,106 @@
+/*
+ * @module api/controllers/field_selection
+ * @description 列表接口字段选择，支持fields参数指定返回字段和light=true轻量模式裁剪重字段
+ * @architecture 分层架构 - 控制器层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow 查询参数 -> 解析字段选择 -> 列表响应序列化 -> 裁剪list中每项的字段 -> 返回
+ * @rules fields为逗号分隔的JSON字段名，始终保留id；light=true时去除各接口声明的重字段，fields中显式指定的字段不受影响；分页信息不裁剪
+ * @dependencies encoding/json, net/http
+ * @refs api/controllers/pagination.go
+ */
+
+package controllers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// 各列表接口在轻量模式下去除的重字段
+var (
+	qualityRuleHeavyFields       = []string{"rule_logic", "parameters", "default_config"}
+	maskingRuleHeavyFields       = []string{"masking_logic", "parameters"}
+	cleansingRuleHeavyFields     = []string{"cleansing_logic", "parameters", "default_config"}
+	dataInterfaceHeavyFields     = []string{"interface_config", "parse_config", "table_fields_config", "basic_library", "data_source", "clean_rules"}
+	thematicInterfaceHeavyFields = []string{"interface_config", "parse_config", "table_fields_config", "view_config", "thematic_library"}
+)
+
+// fieldSelection 列表字段选择
+type fieldSelection struct {
+	fields map[string]bool
+	omit   []string
+}
+
+// parseFieldSelection 解析fields和light查询参数，heavyFields为轻量模式下去除的字段
+func parseFieldSelection(r *http.Request, heavyFields ...string) fieldSelection {
+	query := r.URL.Query()
+	sel := fieldSelection{}
+
+	if raw := query.Get("fields"); raw != "" {
+		sel.fields = map[string]bool{"id": true}
+		for _, field := range strings.Split(raw, ",") {
+			if field = strings.TrimSpace(field); field != "" {
+				sel.fields[field] = true
+			}
+		}
+	}
+
+	if light, _ := strconv.ParseBool(query.Get("light")); light {
+		sel.omit = heavyFields
+	}
+
+	return sel
+}
+
+// active 是否需要裁剪
+func (s fieldSelection) active() bool {
+	return s.fields != nil || len(s.omit) > 0
+}
+
+// applyToList 裁剪列表响应中list的每一项，未指定字段选择或处理失败时原样返回
+func (s fieldSelection) applyToList(response interface{}) interface{} {
+	if !s.active() {
+		return response
+	}
+
+	data, err := json.Marshal(response)
+	if err != nil {
+		return response
+	}
+
+	var envelope map[string]interface{}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&envelope); err != nil {
+		return response
+	}
+
+	items, ok := envelope["list"].([]interface{})
+	if !ok {
+		return response
+	}
+
+	for _, item := range items {
+		obj, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if s.fields != nil {
+			for key := range obj {
+				if !s.fields[key] {
+					delete(obj, key)
+				}
+			}
+		}
+		for _, key := range s.omit {
+			if !s.fields[key] {
+				delete(obj, key)
+			}
+		}
+	}
+
+	return envelope
+}
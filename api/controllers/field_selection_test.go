@@ -0,0 +1,106 @@
+/*
+ * @module api/controllers/field_selection_test
+ * @description 列表字段选择测试，覆盖fields参数、light轻量模式及组合使用
+ * @architecture 测试层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow 构造查询参数 -> 解析字段选择 -> 裁剪列表响应 -> 验证保留字段
+ * @rules 不依赖外部服务
+ * @dependencies testing, net/http/httptest, stretchr/testify
+ * @refs api/controllers/field_selection.go
+ */
+
+package controllers
+
+import (
+	"datahub-service/service/models"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fieldSelectionItem struct {
+	ID         string                 `json:"id"`
+	Name       string                 `json:"name"`
+	RuleLogic  map[string]interface{} `json:"rule_logic"`
+	Parameters map[string]interface{} `json:"parameters"`
+	RowVersion int64                  `json:"row_version"`
+}
+
+type fieldSelectionListResponse struct {
+	List []fieldSelectionItem `json:"list"`
+	models.PageMeta
+}
+
+func selectFields(t *testing.T, query string) map[string]interface{} {
+	t.Helper()
+
+	response := fieldSelectionListResponse{
+		List: []fieldSelectionItem{{
+			ID:         "r1",
+			Name:       "非空检查",
+			RuleLogic:  map[string]interface{}{"expr": "x is not null"},
+			Parameters: map[string]interface{}{"field": "x"},
+			RowVersion: 9007199254740993,
+		}},
+		PageMeta: models.NewPageMeta(1, 1, 10),
+	}
+
+	r := httptest.NewRequest(http.MethodGet, "/list"+query, nil)
+	sel := parseFieldSelection(r, "rule_logic", "parameters")
+
+	data, err := json.Marshal(sel.applyToList(response))
+	require.NoError(t, err)
+
+	var result map[string]interface{}
+	require.NoError(t, json.Unmarshal(data, &result))
+	assert.EqualValues(t, 1, result["total"])
+	return result["list"].([]interface{})[0].(map[string]interface{})
+}
+
+func keysOf(obj map[string]interface{}) []string {
+	keys := make([]string, 0, len(obj))
+	for key := range obj {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+func TestFieldSelection(t *testing.T) {
+	t.Run("未指定时原样返回", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodGet, "/list", nil)
+		sel := parseFieldSelection(r, "rule_logic")
+		assert.False(t, sel.active())
+
+		response := fieldSelectionListResponse{}
+		assert.Equal(t, response, sel.applyToList(response))
+	})
+
+	t.Run("fields指定字段并保留id", func(t *testing.T) {
+		item := selectFields(t, "?fields=name,%20row_version")
+		assert.ElementsMatch(t, []string{"id", "name", "row_version"}, keysOf(item))
+	})
+
+	t.Run("light去除重字段", func(t *testing.T) {
+		item := selectFields(t, "?light=true")
+		assert.ElementsMatch(t, []string{"id", "name", "row_version"}, keysOf(item))
+	})
+
+	t.Run("fields显式指定的重字段不受light影响", func(t *testing.T) {
+		item := selectFields(t, "?light=true&fields=name,rule_logic,parameters")
+		assert.ElementsMatch(t, []string{"id", "name", "rule_logic", "parameters"}, keysOf(item))
+	})
+
+	t.Run("大整数不丢失精度", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodGet, "/list?fields=row_version", nil)
+		sel := parseFieldSelection(r)
+		data, err := json.Marshal(sel.applyToList(fieldSelectionListResponse{
+			List: []fieldSelectionItem{{ID: "r1", RowVersion: 9007199254740993}},
+		}))
+		require.NoError(t, err)
+		assert.Contains(t, string(data), `"row_version":9007199254740993`)
+	})
+}
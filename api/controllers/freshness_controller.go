@@ -0,0 +1,156 @@
+/*
+ * @module api/controllers/freshness_controller
+ * @description 接口数据新鲜度控制器，提供新鲜度状态查询、新鲜度SLA配置和手动触发检查的接口
+ * @architecture 分层架构 - 控制器层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow HTTP请求 -> 控制器 -> 数据新鲜度监控服务 -> 数据库
+ * @rules 统一的错误处理和响应格式；SLA为0表示按同步任务调度推导预期周期；配置SLA后立即重新检查
+ * @dependencies datahub-service/service, datahub-service/service/basic_library, github.com/go-chi/chi/v5
+ * @refs service/basic_library/freshness_service.go
+ */
+
+package controllers
+
+import (
+	"datahub-service/service"
+	"datahub-service/service/basic_library"
+	"datahub-service/service/models"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+)
+
+// FreshnessController 接口数据新鲜度控制器
+type FreshnessController struct {
+}
+
+// NewFreshnessController 创建接口数据新鲜度控制器实例
+func NewFreshnessController() *FreshnessController {
+	return &FreshnessController{}
+}
+
+// InterfaceFreshnessListResponse 接口数据新鲜度列表响应结构
+type InterfaceFreshnessListResponse struct {
+	List []basic_library.InterfaceFreshnessView `json:"list"`
+	models.PageMeta
+}
+
+// UpdateFreshnessSLARequest 新鲜度SLA配置请求
+type UpdateFreshnessSLARequest struct {
+	SLASeconds int `json:"sla_seconds" validate:"min=0" example:"7200"` // 0表示按同步任务调度推导
+}
+
+// GetInterfaceFreshnessList 获取接口数据新鲜度列表
+// @Summary 获取接口数据新鲜度列表
+// @Description 分页获取已检查过的接口数据新鲜度，过期的接口排在前面
+// @Tags 数据基础库
+// @Produce json
+// @Param page query int false "页码" default(1)
+// @Param size query int false "每页数量" default(10)
+// @Param status query string false "新鲜度状态" Enums(fresh, stale, unknown)
+// @Param library_id query string false "基础库ID"
+// @Success 200 {object} APIResponse[InterfaceFreshnessListResponse] "获取成功"
+// @Router /basic-libraries/interfaces/freshness [get]
+func (c *FreshnessController) GetInterfaceFreshnessList(w http.ResponseWriter, r *http.Request) {
+	page, size := parsePagination(r)
+	query := r.URL.Query()
+
+	views, total, err := service.GlobalFreshnessService.GetFreshnessList(r.Context(), page, size, query.Get("status"), query.Get("library_id"))
+	if err != nil {
+		render.JSON(w, r, MapErrorResponse("获取接口数据新鲜度列表失败", err))
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("获取接口数据新鲜度列表成功", InterfaceFreshnessListResponse{
+		List:     views,
+		PageMeta: models.NewPageMeta(total, page, size),
+	}))
+}
+
+// CheckAllInterfaceFreshness 检查所有接口的数据新鲜度
+// @Summary 检查所有接口的数据新鲜度
+// @Description 立即检查所有启用接口的数据新鲜度，新进入过期状态的接口会发出告警
+// @Tags 数据基础库
+// @Produce json
+// @Success 200 {object} APIResponse[basic_library.FreshnessCheckResult] "检查完成"
+// @Router /basic-libraries/interfaces/freshness/check [post]
+func (c *FreshnessController) CheckAllInterfaceFreshness(w http.ResponseWriter, r *http.Request) {
+	result, err := service.GlobalFreshnessService.CheckAll(r.Context())
+	if err != nil {
+		render.JSON(w, r, MapErrorResponse("检查接口数据新鲜度失败", err))
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("接口数据新鲜度检查完成", result))
+}
+
+// GetInterfaceFreshness 获取接口数据新鲜度
+// @Summary 获取接口数据新鲜度
+// @Description 获取接口最近一次成功同步时间、预期同步周期和新鲜度状态
+// @Tags 数据基础库
+// @Produce json
+// @Param id path string true "接口ID"
+// @Success 200 {object} APIResponse[basic_library.InterfaceFreshnessView] "获取成功"
+// @Failure 404 {object} APIResponse[any] "接口不存在"
+// @Router /basic-libraries/interfaces/{id}/freshness [get]
+func (c *FreshnessController) GetInterfaceFreshness(w http.ResponseWriter, r *http.Request) {
+	view, err := service.GlobalFreshnessService.GetFreshness(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		render.JSON(w, r, MapErrorResponse("获取接口数据新鲜度失败", err))
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("获取接口数据新鲜度成功", view))
+}
+
+// UpdateInterfaceFreshnessSLA 配置接口新鲜度SLA
+// @Summary 配置接口新鲜度SLA
+// @Description 配置接口的新鲜度SLA（秒），超过SLA未同步成功即视为过期；设置为0时按同步任务调度推导
+// @Tags 数据基础库
+// @Accept json
+// @Produce json
+// @Param id path string true "接口ID"
+// @Param request body UpdateFreshnessSLARequest true "新鲜度SLA"
+// @Success 200 {object} APIResponse[basic_library.InterfaceFreshnessView] "配置成功"
+// @Failure 400 {object} APIResponse[any] "请求参数错误"
+// @Failure 404 {object} APIResponse[any] "接口不存在"
+// @Router /basic-libraries/interfaces/{id}/freshness [put]
+func (c *FreshnessController) UpdateInterfaceFreshnessSLA(w http.ResponseWriter, r *http.Request) {
+	var req UpdateFreshnessSLARequest
+	if !decodeAndValidate(w, r, &req) {
+		return
+	}
+
+	view, err := service.GlobalFreshnessService.UpdateSLA(r.Context(), chi.URLParam(r, "id"), req.SLASeconds, getCurrentUsername(r))
+	if err != nil {
+		if errors.Is(err, basic_library.ErrInvalidFreshnessSLA) {
+			render.JSON(w, r, BadRequestResponse("配置新鲜度SLA失败", err))
+			return
+		}
+		render.JSON(w, r, MapErrorResponse("配置新鲜度SLA失败", err))
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("配置新鲜度SLA成功", view))
+}
+
+// CheckInterfaceFreshness 检查接口数据新鲜度
+// @Summary 检查接口数据新鲜度
+// @Description 立即重新计算接口的预期同步周期和新鲜度状态
+// @Tags 数据基础库
+// @Produce json
+// @Param id path string true "接口ID"
+// @Success 200 {object} APIResponse[basic_library.InterfaceFreshnessView] "检查完成"
+// @Failure 404 {object} APIResponse[any] "接口不存在"
+// @Router /basic-libraries/interfaces/{id}/freshness/check [post]
+func (c *FreshnessController) CheckInterfaceFreshness(w http.ResponseWriter, r *http.Request) {
+	view, err := service.GlobalFreshnessService.CheckInterface(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		render.JSON(w, r, MapErrorResponse("检查接口数据新鲜度失败", err))
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("接口数据新鲜度检查完成", view))
+}
@@ -0,0 +1,276 @@
+/*
+ * @module api/controllers/golden_dataset_controller
+ * @description 接口基准数据集控制器，提供基准数据集的上传、从快照创建、比较设置、基准行查询、手动比较和比较记录查询的接口
+ * @architecture 分层架构 - 控制器层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow HTTP请求 -> 控制器 -> 接口基准数据集服务 -> 数据库
+ * @rules 统一的错误处理和响应格式；基准数据集不合法时返回400；基准行以JSON数组上传，rows和snapshot_id二选一
+ * @dependencies datahub-service/service, datahub-service/service/basic_library, github.com/go-chi/chi/v5
+ * @refs service/basic_library/golden_dataset_service.go
+ */
+
+package controllers
+
+import (
+	"datahub-service/service"
+	"datahub-service/service/basic_library"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+)
+
+// GoldenDatasetController 接口基准数据集控制器
+type GoldenDatasetController struct {
+}
+
+// NewGoldenDatasetController 创建接口基准数据集控制器实例
+func NewGoldenDatasetController() *GoldenDatasetController {
+	return &GoldenDatasetController{}
+}
+
+// GoldenDatasetSettingsRequest 基准数据集比较设置
+type GoldenDatasetSettingsRequest struct {
+	Name             string             `json:"name" validate:"required,max=100" example:"2024年6月车辆进出基准"`
+	Description      string             `json:"description" validate:"max=500"`
+	MatchMode        string             `json:"match_mode" validate:"omitempty,oneof=subset exact" example:"subset"` // subset只检查基准行，exact时接口表多出的行也算差异
+	AbsTolerance     float64            `json:"abs_tolerance" validate:"min=0" example:"0.01"`                       // 数值允许的绝对误差
+	RelTolerance     float64            `json:"rel_tolerance" validate:"min=0" example:"0.001"`                      // 数值允许的相对误差
+	ColumnTolerances map[string]float64 `json:"column_tolerances,omitempty"`                                         // 按列覆盖的绝对误差
+	IgnoreColumns    []string           `json:"ignore_columns,omitempty" example:"updated_at"`                       // 不比较取值的列
+	AutoCompare      bool               `json:"auto_compare"`                                                        // 接口同步成功后自动比较
+}
+
+func (req *GoldenDatasetSettingsRequest) toSettings() basic_library.GoldenDatasetSettings {
+	return basic_library.GoldenDatasetSettings{
+		Name:             req.Name,
+		Description:      req.Description,
+		MatchMode:        req.MatchMode,
+		AbsTolerance:     req.AbsTolerance,
+		RelTolerance:     req.RelTolerance,
+		ColumnTolerances: req.ColumnTolerances,
+		IgnoreColumns:    req.IgnoreColumns,
+		AutoCompare:      req.AutoCompare,
+	}
+}
+
+// CreateGoldenDatasetRequest 创建基准数据集请求
+type CreateGoldenDatasetRequest struct {
+	GoldenDatasetSettingsRequest
+	KeyFields  []string                 `json:"key_fields,omitempty" example:"id"` // 匹配行的键字段，为空时取接口主键
+	Rows       []map[string]interface{} `json:"rows,omitempty"`                    // 上传的基准行
+	SnapshotID string                   `json:"snapshot_id,omitempty"`             // 取该接口快照中的数据作为基准
+}
+
+// CreateGoldenDataset 创建接口基准数据集
+// @Summary 创建接口基准数据集
+// @Description 上传基准行(rows)或选择接口的快照(snapshot_id)作为基准数据，最多10000行。列类型按当前接口字段配置记录，之后比较时与字段配置对照；键字段为空时取接口主键，基准行的键值不能为空或重复
+// @Tags 数据基础库
+// @Accept json
+// @Produce json
+// @Param id path string true "接口ID"
+// @Param request body CreateGoldenDatasetRequest true "基准数据集"
+// @Success 200 {object} APIResponse[models.InterfaceGoldenDataset] "创建成功"
+// @Failure 400 {object} APIResponse[any] "请求参数错误"
+// @Failure 404 {object} APIResponse[any] "接口或快照不存在"
+// @Router /basic-libraries/interfaces/{id}/golden-datasets [post]
+func (c *GoldenDatasetController) CreateGoldenDataset(w http.ResponseWriter, r *http.Request) {
+	var req CreateGoldenDatasetRequest
+	if !decodeAndValidate(w, r, &req) {
+		return
+	}
+
+	dataset, err := service.GlobalGoldenService.CreateDataset(r.Context(), chi.URLParam(r, "id"), &basic_library.GoldenDatasetInput{
+		GoldenDatasetSettings: req.toSettings(),
+		KeyFields:             req.KeyFields,
+		Rows:                  req.Rows,
+		SnapshotID:            req.SnapshotID,
+	}, getCurrentUsername(r))
+	if err != nil {
+		respondGoldenError(w, r, "创建基准数据集失败", err)
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("创建基准数据集成功", dataset))
+}
+
+// GetGoldenDatasets 获取接口基准数据集列表
+// @Summary 获取接口基准数据集列表
+// @Description 按创建时间倒序获取接口的基准数据集，包括比较设置和最近一次比较的结果
+// @Tags 数据基础库
+// @Produce json
+// @Param id path string true "接口ID"
+// @Success 200 {object} APIResponse[[]models.InterfaceGoldenDataset] "获取成功"
+// @Failure 404 {object} APIResponse[any] "接口不存在"
+// @Router /basic-libraries/interfaces/{id}/golden-datasets [get]
+func (c *GoldenDatasetController) GetGoldenDatasets(w http.ResponseWriter, r *http.Request) {
+	datasets, err := service.GlobalGoldenService.ListDatasets(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		render.JSON(w, r, MapErrorResponse("获取基准数据集列表失败", err))
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("获取基准数据集列表成功", datasets))
+}
+
+// GetGoldenDataset 获取接口基准数据集
+// @Summary 获取接口基准数据集
+// @Description 获取基准数据集的列、键字段、比较设置和最近一次比较的结果
+// @Tags 数据基础库
+// @Produce json
+// @Param id path string true "接口ID"
+// @Param dataset_id path string true "基准数据集ID"
+// @Success 200 {object} APIResponse[models.InterfaceGoldenDataset] "获取成功"
+// @Failure 404 {object} APIResponse[any] "接口或基准数据集不存在"
+// @Router /basic-libraries/interfaces/{id}/golden-datasets/{dataset_id} [get]
+func (c *GoldenDatasetController) GetGoldenDataset(w http.ResponseWriter, r *http.Request) {
+	dataset, err := service.GlobalGoldenService.GetDataset(r.Context(), chi.URLParam(r, "id"), chi.URLParam(r, "dataset_id"))
+	if err != nil {
+		render.JSON(w, r, MapErrorResponse("获取基准数据集失败", err))
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("获取基准数据集成功", dataset))
+}
+
+// UpdateGoldenDataset 修改接口基准数据集的比较设置
+// @Summary 修改接口基准数据集的比较设置
+// @Description 修改名称、匹配模式、误差、忽略的列和是否在同步后自动比较，基准行不变；需要更换基准行时重新创建基准数据集
+// @Tags 数据基础库
+// @Accept json
+// @Produce json
+// @Param id path string true "接口ID"
+// @Param dataset_id path string true "基准数据集ID"
+// @Param request body GoldenDatasetSettingsRequest true "比较设置"
+// @Success 200 {object} APIResponse[models.InterfaceGoldenDataset] "修改成功"
+// @Failure 400 {object} APIResponse[any] "请求参数错误"
+// @Failure 404 {object} APIResponse[any] "接口或基准数据集不存在"
+// @Router /basic-libraries/interfaces/{id}/golden-datasets/{dataset_id} [put]
+func (c *GoldenDatasetController) UpdateGoldenDataset(w http.ResponseWriter, r *http.Request) {
+	var req GoldenDatasetSettingsRequest
+	if !decodeAndValidate(w, r, &req) {
+		return
+	}
+
+	settings := req.toSettings()
+	dataset, err := service.GlobalGoldenService.UpdateDataset(r.Context(), chi.URLParam(r, "id"), chi.URLParam(r, "dataset_id"), &settings, getCurrentUsername(r))
+	if err != nil {
+		respondGoldenError(w, r, "修改基准数据集失败", err)
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("修改基准数据集成功", dataset))
+}
+
+// DeleteGoldenDataset 删除接口基准数据集
+// @Summary 删除接口基准数据集
+// @Description 删除基准数据集及其基准行和比较记录
+// @Tags 数据基础库
+// @Produce json
+// @Param id path string true "接口ID"
+// @Param dataset_id path string true "基准数据集ID"
+// @Success 200 {object} APIResponse[any] "删除成功"
+// @Failure 404 {object} APIResponse[any] "接口或基准数据集不存在"
+// @Router /basic-libraries/interfaces/{id}/golden-datasets/{dataset_id} [delete]
+func (c *GoldenDatasetController) DeleteGoldenDataset(w http.ResponseWriter, r *http.Request) {
+	if err := service.GlobalGoldenService.DeleteDataset(r.Context(), chi.URLParam(r, "id"), chi.URLParam(r, "dataset_id")); err != nil {
+		render.JSON(w, r, MapErrorResponse("删除基准数据集失败", err))
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("删除基准数据集成功", nil))
+}
+
+// GetGoldenDatasetRows 查询基准行
+// @Summary 查询基准行
+// @Description 按上传顺序分页查询基准数据集中的基准行
+// @Tags 数据基础库
+// @Produce json
+// @Param id path string true "接口ID"
+// @Param dataset_id path string true "基准数据集ID"
+// @Param page query int false "页码" default(1)
+// @Param size query int false "每页数量" default(10)
+// @Success 200 {object} APIResponse[basic_library.GoldenDatasetRows] "查询成功"
+// @Failure 404 {object} APIResponse[any] "接口或基准数据集不存在"
+// @Router /basic-libraries/interfaces/{id}/golden-datasets/{dataset_id}/rows [get]
+func (c *GoldenDatasetController) GetGoldenDatasetRows(w http.ResponseWriter, r *http.Request) {
+	page, size := parsePagination(r)
+	rows, err := service.GlobalGoldenService.GetRows(r.Context(), chi.URLParam(r, "id"), chi.URLParam(r, "dataset_id"), page, size)
+	if err != nil {
+		render.JSON(w, r, MapErrorResponse("查询基准行失败", err))
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("查询基准行成功", rows))
+}
+
+// RunGoldenComparison 比较接口表与基准数据集
+// @Summary 比较接口表与基准数据集
+// @Description 立即把接口表与基准数据集比较并保存比较记录：对照列和字段类型，按键字段匹配行，统计缺失的基准行、取值超出误差的行和(exact模式)多出的行，给出差异样例和各列不一致的行数。未通过时记录golden_comparison_failed事件
+// @Tags 数据基础库
+// @Produce json
+// @Param id path string true "接口ID"
+// @Param dataset_id path string true "基准数据集ID"
+// @Success 200 {object} APIResponse[models.InterfaceGoldenRun] "比较完成"
+// @Failure 404 {object} APIResponse[any] "接口或基准数据集不存在"
+// @Router /basic-libraries/interfaces/{id}/golden-datasets/{dataset_id}/runs [post]
+func (c *GoldenDatasetController) RunGoldenComparison(w http.ResponseWriter, r *http.Request) {
+	run, err := service.GlobalGoldenService.RunComparison(r.Context(), chi.URLParam(r, "id"), chi.URLParam(r, "dataset_id"), getCurrentUsername(r))
+	if err != nil {
+		render.JSON(w, r, MapErrorResponse("比较基准数据集失败", err))
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("比较基准数据集完成", run))
+}
+
+// GetGoldenComparisonRuns 获取基准数据比较记录
+// @Summary 获取基准数据比较记录
+// @Description 按时间倒序获取基准数据集最近50次比较的结果，包括手动比较和同步后的自动比较
+// @Tags 数据基础库
+// @Produce json
+// @Param id path string true "接口ID"
+// @Param dataset_id path string true "基准数据集ID"
+// @Success 200 {object} APIResponse[[]models.InterfaceGoldenRun] "获取成功"
+// @Failure 404 {object} APIResponse[any] "接口或基准数据集不存在"
+// @Router /basic-libraries/interfaces/{id}/golden-datasets/{dataset_id}/runs [get]
+func (c *GoldenDatasetController) GetGoldenComparisonRuns(w http.ResponseWriter, r *http.Request) {
+	runs, err := service.GlobalGoldenService.ListRuns(r.Context(), chi.URLParam(r, "id"), chi.URLParam(r, "dataset_id"))
+	if err != nil {
+		render.JSON(w, r, MapErrorResponse("获取比较记录失败", err))
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("获取比较记录成功", runs))
+}
+
+// GetGoldenComparisonRun 获取一次基准数据比较的结果
+// @Summary 获取一次基准数据比较的结果
+// @Description 获取比较记录，包括表结构差异、缺失行的键样例和取值差异样例
+// @Tags 数据基础库
+// @Produce json
+// @Param id path string true "接口ID"
+// @Param dataset_id path string true "基准数据集ID"
+// @Param run_id path string true "比较记录ID"
+// @Success 200 {object} APIResponse[models.InterfaceGoldenRun] "获取成功"
+// @Failure 404 {object} APIResponse[any] "接口、基准数据集或比较记录不存在"
+// @Router /basic-libraries/interfaces/{id}/golden-datasets/{dataset_id}/runs/{run_id} [get]
+func (c *GoldenDatasetController) GetGoldenComparisonRun(w http.ResponseWriter, r *http.Request) {
+	run, err := service.GlobalGoldenService.GetRun(r.Context(), chi.URLParam(r, "id"), chi.URLParam(r, "dataset_id"), chi.URLParam(r, "run_id"))
+	if err != nil {
+		render.JSON(w, r, MapErrorResponse("获取比较记录失败", err))
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("获取比较记录成功", run))
+}
+
+// respondGoldenError 基准数据集不合法时返回400，其余按错误类型映射
+func respondGoldenError(w http.ResponseWriter, r *http.Request, msg string, err error) {
+	if errors.Is(err, basic_library.ErrInvalidGoldenDataset) {
+		render.JSON(w, r, BadRequestResponse(msg, err))
+		return
+	}
+	render.JSON(w, r, MapErrorResponse(msg, err))
+}
@@ -0,0 +1,188 @@
+/*
+ * @module api/controllers/interface_release_controller
+ * @description 共享接口灰度发布管理接口，支持指定订阅方先访问新版本、全量切换和回退
+ * @architecture 分层架构 - 控制器层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow HTTP请求 -> 控制器 -> 数据共享服务 -> 数据库
+ * @rules 统一的错误处理和响应格式；状态不允许的操作返回409
+ * @dependencies datahub-service/service/sharing, github.com/go-chi/chi/v5
+ * @refs service/sharing/interface_release.go
+ */
+
+package controllers
+
+import (
+	"datahub-service/service/models"
+	"datahub-service/service/sharing"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"gorm.io/gorm"
+)
+
+// CreateApiInterfaceReleaseRequest 创建灰度发布请求结构
+type CreateApiInterfaceReleaseRequest struct {
+	TargetThematicInterfaceID string     `json:"target_thematic_interface_id" validate:"required"` // 新版本主题接口ID
+	CanaryApiKeyIDs           []string   `json:"canary_api_key_ids" validate:"required"`           // 灰度订阅方的ApiKey ID
+	ObserveUntil              *time.Time `json:"observe_until"`                                    // 观察期结束时间
+	Description               string     `json:"description"`
+}
+
+// UpdateApiInterfaceReleaseSubscribersRequest 调整灰度订阅方请求结构
+type UpdateApiInterfaceReleaseSubscribersRequest struct {
+	CanaryApiKeyIDs []string   `json:"canary_api_key_ids" validate:"required"`
+	ObserveUntil    *time.Time `json:"observe_until"`
+}
+
+// RollbackApiInterfaceReleaseRequest 回退灰度发布请求结构
+type RollbackApiInterfaceReleaseRequest struct {
+	Reason string `json:"reason"`
+}
+
+// CreateApiInterfaceRelease 创建灰度发布
+// @Summary 创建共享接口灰度发布
+// @Description 指定订阅方（API密钥）访问新版本主题接口，其余订阅方继续访问当前版本
+// @Tags 数据共享服务
+// @Accept json
+// @Produce json
+// @Param id path string true "接口ID"
+// @Param release body CreateApiInterfaceReleaseRequest true "灰度发布信息"
+// @Success 200 {object} APIResponse[models.ApiInterfaceRelease] "创建成功"
+// @Failure 400 {object} APIResponse[any] "请求参数错误"
+// @Failure 409 {object} APIResponse[any] "已存在进行中的灰度发布"
+// @Router /sharing/api-interfaces/{id}/releases [post]
+func (c *SharingController) CreateApiInterfaceRelease(w http.ResponseWriter, r *http.Request) {
+	var req CreateApiInterfaceReleaseRequest
+	if !decodeAndValidate(w, r, &req) {
+		return
+	}
+
+	operator := getCurrentUsername(r)
+	release := &models.ApiInterfaceRelease{
+		ApiInterfaceID:            chi.URLParam(r, "id"),
+		TargetThematicInterfaceID: req.TargetThematicInterfaceID,
+		CanaryApiKeyIDs:           req.CanaryApiKeyIDs,
+		ObserveUntil:              req.ObserveUntil,
+		Description:               req.Description,
+		CreatedBy:                 operator,
+	}
+	if err := c.sharingService.CreateApiInterfaceRelease(release); err != nil {
+		respondReleaseError(w, r, "创建灰度发布失败", err)
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("创建灰度发布成功", release))
+}
+
+// GetApiInterfaceReleases 获取灰度发布记录
+// @Summary 获取共享接口灰度发布记录
+// @Description 获取接口的全部灰度发布记录，按创建时间倒序
+// @Tags 数据共享服务
+// @Produce json
+// @Param id path string true "接口ID"
+// @Success 200 {object} APIResponse[[]models.ApiInterfaceRelease] "获取成功"
+// @Failure 500 {object} APIResponse[any] "服务器内部错误"
+// @Router /sharing/api-interfaces/{id}/releases [get]
+func (c *SharingController) GetApiInterfaceReleases(w http.ResponseWriter, r *http.Request) {
+	releases, err := c.sharingService.GetApiInterfaceReleases(chi.URLParam(r, "id"))
+	if err != nil {
+		render.JSON(w, r, MapErrorResponse("获取灰度发布记录失败", err))
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("获取灰度发布记录成功", releases))
+}
+
+// UpdateApiInterfaceReleaseSubscribers 调整灰度订阅方
+// @Summary 调整灰度订阅方
+// @Description 替换灰度订阅方列表和观察期，仅灰度中的发布可调整
+// @Tags 数据共享服务
+// @Accept json
+// @Produce json
+// @Param id path string true "接口ID"
+// @Param release_id path string true "发布ID"
+// @Param subscribers body UpdateApiInterfaceReleaseSubscribersRequest true "灰度订阅方"
+// @Success 200 {object} APIResponse[any] "更新成功"
+// @Failure 400 {object} APIResponse[any] "请求参数错误"
+// @Failure 404 {object} APIResponse[any] "发布不存在"
+// @Failure 409 {object} APIResponse[any] "发布状态不允许调整"
+// @Router /sharing/api-interfaces/{id}/releases/{release_id}/subscribers [put]
+func (c *SharingController) UpdateApiInterfaceReleaseSubscribers(w http.ResponseWriter, r *http.Request) {
+	var req UpdateApiInterfaceReleaseSubscribersRequest
+	if !decodeAndValidate(w, r, &req) {
+		return
+	}
+
+	if err := c.sharingService.UpdateApiInterfaceReleaseSubscribers(chi.URLParam(r, "id"), chi.URLParam(r, "release_id"),
+		req.CanaryApiKeyIDs, req.ObserveUntil, getCurrentUsername(r)); err != nil {
+		respondReleaseError(w, r, "调整灰度订阅方失败", err)
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("调整灰度订阅方成功", nil))
+}
+
+// PromoteApiInterfaceRelease 全量切换
+// @Summary 灰度发布全量切换
+// @Description 将接口切换到新版本主题接口，所有订阅方访问新版本；切换后仍可回退
+// @Tags 数据共享服务
+// @Produce json
+// @Param id path string true "接口ID"
+// @Param release_id path string true "发布ID"
+// @Success 200 {object} APIResponse[any] "切换成功"
+// @Failure 404 {object} APIResponse[any] "发布不存在"
+// @Failure 409 {object} APIResponse[any] "发布状态不允许切换"
+// @Router /sharing/api-interfaces/{id}/releases/{release_id}/promote [post]
+func (c *SharingController) PromoteApiInterfaceRelease(w http.ResponseWriter, r *http.Request) {
+	if err := c.sharingService.PromoteApiInterfaceRelease(chi.URLParam(r, "id"), chi.URLParam(r, "release_id"), getCurrentUsername(r)); err != nil {
+		respondReleaseError(w, r, "全量切换失败", err)
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("全量切换成功", nil))
+}
+
+// RollbackApiInterfaceRelease 回退灰度发布
+// @Summary 回退灰度发布
+// @Description 灰度中的发布直接结束；已全量切换的发布将接口恢复到旧版本
+// @Tags 数据共享服务
+// @Accept json
+// @Produce json
+// @Param id path string true "接口ID"
+// @Param release_id path string true "发布ID"
+// @Param rollback body RollbackApiInterfaceReleaseRequest false "回退原因"
+// @Success 200 {object} APIResponse[any] "回退成功"
+// @Failure 404 {object} APIResponse[any] "发布不存在"
+// @Failure 409 {object} APIResponse[any] "发布状态不允许回退"
+// @Router /sharing/api-interfaces/{id}/releases/{release_id}/rollback [post]
+func (c *SharingController) RollbackApiInterfaceRelease(w http.ResponseWriter, r *http.Request) {
+	var req RollbackApiInterfaceReleaseRequest
+	if r.ContentLength > 0 {
+		if !decodeAndValidate(w, r, &req) {
+			return
+		}
+	}
+
+	if err := c.sharingService.RollbackApiInterfaceRelease(chi.URLParam(r, "id"), chi.URLParam(r, "release_id"),
+		getCurrentUsername(r), req.Reason); err != nil {
+		respondReleaseError(w, r, "回退灰度发布失败", err)
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("回退灰度发布成功", nil))
+}
+
+// respondReleaseError 按错误类型返回灰度发布操作的错误响应
+func respondReleaseError(w http.ResponseWriter, r *http.Request, msg string, err error) {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		render.JSON(w, r, NotFoundResponse(msg+": 接口或发布不存在", err))
+	case errors.Is(err, sharing.ErrReleaseInProgress), errors.Is(err, sharing.ErrReleaseStateInvalid), errors.Is(err, sharing.ErrThematicInterfaceNotPublished):
+		render.JSON(w, r, ConflictResponse(msg+": "+err.Error(), err))
+	default:
+		render.JSON(w, r, BadRequestResponse(msg+": "+err.Error(), err))
+	}
+}
@@ -1,6 +1,7 @@
 package controllers
 
 import (
+	"datahub-service/service/interface_executor"
 	"datahub-service/service/meta"
 	"net/http"
 
@@ -18,8 +19,8 @@ func NewMetaController() *MetaController {
 // @Description 获取所有数据源类型元数据
 // @Tags 元数据
 // @Produce json
-// @Success 200 {object} APIResponse{data=map[string]meta.DataSourceTypeDefinition}
-// @Failure 500 {object} APIResponse
+// @Success 200 {object} APIResponse[map[string]meta.DataSourceTypeDefinition]
+// @Failure 500 {object} APIResponse[any]
 // @Router /meta/basic-libraries/data-sources [get]
 func (c *MetaController) GetDataSourceTypes(w http.ResponseWriter, r *http.Request) {
 	render.JSON(w, r, SuccessResponse("获取数据源类型元数据成功", meta.DataSourceTypes))
@@ -29,8 +30,8 @@ func (c *MetaController) GetDataSourceTypes(w http.ResponseWriter, r *http.Reque
 // @Description 获取所有数据接口配置元数据
 // @Tags 元数据
 // @Produce json
-// @Success 200 {object} APIResponse{data=map[string]meta.DataInterfaceConfigDefinition}
-// @Failure 500 {object} APIResponse
+// @Success 200 {object} APIResponse[map[string]meta.DataInterfaceConfigDefinition]
+// @Failure 500 {object} APIResponse[any]
 // @Router /meta/basic-libraries/data-interface-configs [get]
 func (c *MetaController) GetDataInterfaceConfigs(w http.ResponseWriter, r *http.Request) {
 	render.JSON(w, r, SuccessResponse("获取数据接口配置元数据成功", meta.DataInterfaceConfigDefinitions))
@@ -40,8 +41,8 @@ func (c *MetaController) GetDataInterfaceConfigs(w http.ResponseWriter, r *http.
 // @Description 获取所有同步任务相关元数据，包括任务类型、状态、调度类型等
 // @Tags 元数据
 // @Produce json
-// @Success 200 {object} APIResponse{data=map[string]interface{}}
-// @Failure 500 {object} APIResponse
+// @Success 200 {object} APIResponse[any]{data=map[string]interface{}}
+// @Failure 500 {object} APIResponse[any]
 // @Router /meta/sync-tasks [get]
 func (c *MetaController) GetSyncTaskMeta(w http.ResponseWriter, r *http.Request) {
 	syncTaskMeta := map[string]interface{}{
@@ -60,8 +61,8 @@ func (c *MetaController) GetSyncTaskMeta(w http.ResponseWriter, r *http.Request)
 // @Description 获取所有数据主题库分类元数据
 // @Tags 元数据
 // @Produce json
-// @Success 200 {object} APIResponse{data=[]meta.ThematicLibraryCategory}
-// @Failure 500 {object} APIResponse
+// @Success 200 {object} APIResponse[[]meta.ThematicLibraryCategory]
+// @Failure 500 {object} APIResponse[any]
 // @Router /meta/thematic-libraries/categories [get]
 func (c *MetaController) GetThematicLibraryCategories(w http.ResponseWriter, r *http.Request) {
 	render.JSON(w, r, SuccessResponse("获取数据主题库分类元数据成功", meta.ThematicLibraryCategories))
@@ -71,8 +72,8 @@ func (c *MetaController) GetThematicLibraryCategories(w http.ResponseWriter, r *
 // @Description 获取所有数据主题库域元数据
 // @Tags 元数据
 // @Produce json
-// @Success 200 {object} APIResponse{data=[]meta.ThematicLibraryDomain}
-// @Failure 500 {object} APIResponse
+// @Success 200 {object} APIResponse[[]meta.ThematicLibraryDomain]
+// @Failure 500 {object} APIResponse[any]
 // @Router /meta/thematic-libraries/domains [get]
 func (c *MetaController) GetThematicLibraryDomains(w http.ResponseWriter, r *http.Request) {
 	render.JSON(w, r, SuccessResponse("获取数据主题库域元数据成功", meta.ThematicLibraryDomains))
@@ -82,8 +83,8 @@ func (c *MetaController) GetThematicLibraryDomains(w http.ResponseWriter, r *htt
 // @Description 获取所有数据主题库访问级别元数据
 // @Tags 元数据
 // @Produce json
-// @Success 200 {object} APIResponse{data=[]meta.ThematicLibraryAccessLevel}
-// @Failure 500 {object} APIResponse
+// @Success 200 {object} APIResponse[[]meta.ThematicLibraryAccessLevel]
+// @Failure 500 {object} APIResponse[any]
 // @Router /meta/thematic-libraries/access-levels [get]
 func (c *MetaController) GetThematicLibraryAccessLevels(w http.ResponseWriter, r *http.Request) {
 	render.JSON(w, r, SuccessResponse("获取数据主题库访问级别元数据成功", meta.ThematicLibraryAccessLevels))
@@ -93,8 +94,8 @@ func (c *MetaController) GetThematicLibraryAccessLevels(w http.ResponseWriter, r
 // @Description 获取所有主题库同步任务相关元数据，包括任务状态、触发类型、执行状态等
 // @Tags 元数据
 // @Produce json
-// @Success 200 {object} APIResponse{data=map[string]interface{}}
-// @Failure 500 {object} APIResponse
+// @Success 200 {object} APIResponse[any]{data=map[string]interface{}}
+// @Failure 500 {object} APIResponse[any]
 // @Router /meta/thematic-sync-tasks [get]
 func (c *MetaController) GetThematicSyncTaskMeta(w http.ResponseWriter, r *http.Request) {
 	render.JSON(w, r, SuccessResponse("获取主题库同步任务元数据成功", meta.ThematicSyncMetas))
@@ -104,8 +105,8 @@ func (c *MetaController) GetThematicSyncTaskMeta(w http.ResponseWriter, r *http.
 // @Description 获取主题库同步各种配置的字段定义，用于前端动态生成配置表单
 // @Tags 元数据
 // @Produce json
-// @Success 200 {object} APIResponse{data=map[string]meta.ThematicSyncConfigDefinition}
-// @Failure 500 {object} APIResponse
+// @Success 200 {object} APIResponse[map[string]meta.ThematicSyncConfigDefinition]
+// @Failure 500 {object} APIResponse[any]
 // @Router /meta/thematic-sync-configs [get]
 func (c *MetaController) GetThematicSyncConfigDefinitions(w http.ResponseWriter, r *http.Request) {
 	render.JSON(w, r, SuccessResponse("获取主题库同步配置定义成功", meta.ThematicSyncConfigDefinitions))
@@ -115,8 +116,8 @@ func (c *MetaController) GetThematicSyncConfigDefinitions(w http.ResponseWriter,
 // @Description 获取主题库状态元数据
 // @Tags 元数据
 // @Produce json
-// @Success 200 {object} APIResponse{data=[]meta.ThematicLibraryStatus}
-// @Failure 500 {object} APIResponse
+// @Success 200 {object} APIResponse[[]meta.ThematicLibraryStatus]
+// @Failure 500 {object} APIResponse[any]
 // @Router /meta/thematic-libraries/statuses [get]
 func (c *MetaController) GetThematicLibraryStatuses(w http.ResponseWriter, r *http.Request) {
 	render.JSON(w, r, SuccessResponse("获取主题库状态元数据成功", meta.GetThematicLibraryStatuses()))
@@ -126,8 +127,8 @@ func (c *MetaController) GetThematicLibraryStatuses(w http.ResponseWriter, r *ht
 // @Description 获取主题接口类型元数据
 // @Tags 元数据
 // @Produce json
-// @Success 200 {object} APIResponse{data=[]meta.ThematicInterfaceType}
-// @Failure 500 {object} APIResponse
+// @Success 200 {object} APIResponse[[]meta.ThematicInterfaceType]
+// @Failure 500 {object} APIResponse[any]
 // @Router /meta/thematic-libraries/interface-types [get]
 func (c *MetaController) GetThematicInterfaceTypes(w http.ResponseWriter, r *http.Request) {
 	render.JSON(w, r, SuccessResponse("获取主题接口类型元数据成功", meta.GetThematicInterfaceTypes()))
@@ -137,8 +138,8 @@ func (c *MetaController) GetThematicInterfaceTypes(w http.ResponseWriter, r *htt
 // @Description 获取主题接口状态元数据
 // @Tags 元数据
 // @Produce json
-// @Success 200 {object} APIResponse{data=[]meta.ThematicInterfaceStatus}
-// @Failure 500 {object} APIResponse
+// @Success 200 {object} APIResponse[[]meta.ThematicInterfaceStatus]
+// @Failure 500 {object} APIResponse[any]
 // @Router /meta/thematic-libraries/interface-statuses [get]
 func (c *MetaController) GetThematicInterfaceStatuses(w http.ResponseWriter, r *http.Request) {
 	render.JSON(w, r, SuccessResponse("获取主题接口状态元数据成功", meta.GetThematicInterfaceStatuses()))
@@ -158,8 +159,8 @@ type ThematicLibraryMetadataResponse struct {
 // @Description 获取主题库相关的所有元数据，包括分类、数据域、访问级别、状态等
 // @Tags 元数据
 // @Produce json
-// @Success 200 {object} APIResponse{data=ThematicLibraryMetadataResponse} "获取成功"
-// @Failure 500 {object} APIResponse "服务器内部错误"
+// @Success 200 {object} APIResponse[ThematicLibraryMetadataResponse] "获取成功"
+// @Failure 500 {object} APIResponse[any] "服务器内部错误"
 // @Router /meta/thematic-libraries/all [get]
 func (c *MetaController) GetThematicLibraryAllMetadata(w http.ResponseWriter, r *http.Request) {
 	response := ThematicLibraryMetadataResponse{
@@ -212,8 +213,8 @@ type DataGovernanceMetadataResponse struct {
 // @Description 获取数据治理相关的所有元数据，包括质量规则类型、检查状态、脱敏类型、转换规则类型、校验规则类型、任务类型、血缘关系类型等
 // @Tags 元数据
 // @Produce json
-// @Success 200 {object} APIResponse{data=DataGovernanceMetadataResponse} "获取成功"
-// @Failure 500 {object} APIResponse "服务器内部错误"
+// @Success 200 {object} APIResponse[DataGovernanceMetadataResponse] "获取成功"
+// @Failure 500 {object} APIResponse[any] "服务器内部错误"
 // @Router /meta/data-governance [get]
 func (c *MetaController) GetDataGovernanceMetadata(w http.ResponseWriter, r *http.Request) {
 	response := DataGovernanceMetadataResponse{
@@ -249,3 +250,23 @@ func (c *MetaController) GetDataGovernanceMetadata(w http.ResponseWriter, r *htt
 
 	render.JSON(w, r, SuccessResponse("获取数据治理完整元数据成功", response))
 }
+
+// @Summary 获取错误码目录
+// @Description 获取接口响应code字段的全部取值及含义，前端可据此按错误类型分支处理
+// @Tags 元数据
+// @Produce json
+// @Success 200 {object} APIResponse[[]ErrorCodeInfo] "获取成功"
+// @Router /meta/error-codes [get]
+func (c *MetaController) GetErrorCodes(w http.ResponseWriter, r *http.Request) {
+	render.JSON(w, r, SuccessResponse("获取错误码目录成功", ErrorCodeCatalog))
+}
+
+// @Summary 获取同步失败码目录
+// @Description 获取同步执行失败根因分析使用的失败码、所属分类、说明和处理建议
+// @Tags 元数据
+// @Produce json
+// @Success 200 {object} APIResponse[[]interface_executor.FailureCodeInfo] "获取成功"
+// @Router /meta/sync-failure-codes [get]
+func (c *MetaController) GetSyncFailureCodes(w http.ResponseWriter, r *http.Request) {
+	render.JSON(w, r, SuccessResponse("获取同步失败码目录成功", interface_executor.FailureCodeCatalog))
+}
@@ -0,0 +1,225 @@
+/*
+ * @module api/controllers/metric_store_controller
+ * @description 指标层控制器，提供业务指标定义的管理、立即刷新和物化指标值查询接口，供看板读取
+ * @architecture 分层架构 - 控制器层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow HTTP请求 -> 控制器 -> 指标层服务 -> 数据库/只读副本
+ * @rules 统一的错误处理和响应格式；定义不合法返回400，立即刷新失败返回422并附带失败原因；指标值按时间范围和维度取值过滤
+ * @dependencies datahub-service/service, datahub-service/service/metric_store, github.com/go-chi/chi/v5
+ * @refs service/metric_store/service.go
+ */
+
+package controllers
+
+import (
+	"datahub-service/service"
+	"datahub-service/service/metric_store"
+	"datahub-service/service/models"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+)
+
+// MetricStoreController 指标层控制器
+type MetricStoreController struct {
+}
+
+// NewMetricStoreController 创建指标层控制器实例
+func NewMetricStoreController() *MetricStoreController {
+	return &MetricStoreController{}
+}
+
+// MetricDefinitionListResponse 指标列表响应结构
+type MetricDefinitionListResponse struct {
+	List []models.MetricDefinition `json:"list"`
+	models.PageMeta
+}
+
+// GetMetrics 获取指标列表
+// @Summary 获取指标列表
+// @Description 分页获取指标定义及最近一次刷新结果，可按名称或显示名称关键字、接口过滤
+// @Tags 指标层
+// @Produce json
+// @Param page query int false "页码" default(1)
+// @Param size query int false "每页数量" default(10)
+// @Param keyword query string false "名称关键字"
+// @Param interface_id query string false "接口ID"
+// @Success 200 {object} APIResponse[MetricDefinitionListResponse] "获取成功"
+// @Router /metrics-store/metrics [get]
+func (c *MetricStoreController) GetMetrics(w http.ResponseWriter, r *http.Request) {
+	page, size := parsePagination(r)
+	query := r.URL.Query()
+	metrics, total, err := service.GlobalMetricStoreService.ListMetrics(r.Context(), query.Get("keyword"), query.Get("interface_id"), page, size)
+	if err != nil {
+		render.JSON(w, r, MapErrorResponse("获取指标列表失败", err))
+		return
+	}
+	render.JSON(w, r, SuccessResponse("获取指标列表成功", MetricDefinitionListResponse{
+		List:     metrics,
+		PageMeta: models.NewPageMeta(total, page, size),
+	}))
+}
+
+// CreateMetric 创建指标
+// @Summary 创建指标
+// @Description 基于接口表定义业务指标：expression为聚合表达式（如count(*)、sum(fee)），filter为过滤条件，dimensions为分组维度字段，time_column和time_grain按时间周期分组，refresh_schedule为刷新周期cron表达式（支持秒字段）。表达式和过滤条件不允许子查询，敏感字段不能作为维度
+// @Tags 指标层
+// @Accept json
+// @Produce json
+// @Param request body metric_store.MetricRequest true "指标定义"
+// @Success 200 {object} APIResponse[models.MetricDefinition] "创建成功"
+// @Failure 400 {object} APIResponse[any] "定义不合法"
+// @Router /metrics-store/metrics [post]
+func (c *MetricStoreController) CreateMetric(w http.ResponseWriter, r *http.Request) {
+	var req metric_store.MetricRequest
+	if !decodeAndValidate(w, r, &req) {
+		return
+	}
+	metric, err := service.GlobalMetricStoreService.CreateMetric(r.Context(), &req, getCurrentUsername(r))
+	if err != nil {
+		render.JSON(w, r, metricStoreErrorResponse("创建指标失败", err))
+		return
+	}
+	render.JSON(w, r, SuccessResponse("创建指标成功", metric))
+}
+
+// GetMetric 获取指标
+// @Summary 获取指标
+// @Description 获取指标定义及最近一次刷新结果
+// @Tags 指标层
+// @Produce json
+// @Param id path string true "指标ID"
+// @Success 200 {object} APIResponse[models.MetricDefinition] "获取成功"
+// @Failure 404 {object} APIResponse[any] "指标不存在"
+// @Router /metrics-store/metrics/{id} [get]
+func (c *MetricStoreController) GetMetric(w http.ResponseWriter, r *http.Request) {
+	metric, err := service.GlobalMetricStoreService.GetMetric(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		render.JSON(w, r, MapErrorResponse("获取指标失败", err))
+		return
+	}
+	render.JSON(w, r, SuccessResponse("获取指标成功", metric))
+}
+
+// UpdateMetric 修改指标
+// @Summary 修改指标
+// @Description 修改指标定义，接口、表达式、过滤条件、维度、时间字段、粒度或回溯天数变化时清空已物化的指标值，下一次检查时重新物化
+// @Tags 指标层
+// @Accept json
+// @Produce json
+// @Param id path string true "指标ID"
+// @Param request body metric_store.MetricRequest true "指标定义"
+// @Success 200 {object} APIResponse[models.MetricDefinition] "修改成功"
+// @Failure 400 {object} APIResponse[any] "定义不合法"
+// @Failure 404 {object} APIResponse[any] "指标不存在"
+// @Router /metrics-store/metrics/{id} [put]
+func (c *MetricStoreController) UpdateMetric(w http.ResponseWriter, r *http.Request) {
+	var req metric_store.MetricRequest
+	if !decodeAndValidate(w, r, &req) {
+		return
+	}
+	metric, err := service.GlobalMetricStoreService.UpdateMetric(r.Context(), chi.URLParam(r, "id"), &req, getCurrentUsername(r))
+	if err != nil {
+		render.JSON(w, r, metricStoreErrorResponse("修改指标失败", err))
+		return
+	}
+	render.JSON(w, r, SuccessResponse("修改指标成功", metric))
+}
+
+// DeleteMetric 删除指标
+// @Summary 删除指标
+// @Description 删除指标定义及其物化的指标值
+// @Tags 指标层
+// @Produce json
+// @Param id path string true "指标ID"
+// @Success 200 {object} APIResponse[any] "删除成功"
+// @Failure 404 {object} APIResponse[any] "指标不存在"
+// @Router /metrics-store/metrics/{id} [delete]
+func (c *MetricStoreController) DeleteMetric(w http.ResponseWriter, r *http.Request) {
+	if err := service.GlobalMetricStoreService.DeleteMetric(r.Context(), chi.URLParam(r, "id")); err != nil {
+		render.JSON(w, r, MapErrorResponse("删除指标失败", err))
+		return
+	}
+	render.JSON(w, r, SuccessResponse("删除指标成功", nil))
+}
+
+// RefreshMetric 立即刷新指标
+// @Summary 立即刷新指标
+// @Description 立即物化指标值，不影响定时刷新周期；物化失败时保留上一次的指标值
+// @Tags 指标层
+// @Produce json
+// @Param id path string true "指标ID"
+// @Success 200 {object} APIResponse[models.MetricDefinition] "刷新成功"
+// @Failure 404 {object} APIResponse[any] "指标不存在"
+// @Failure 409 {object} APIResponse[any] "指标正在刷新"
+// @Failure 422 {object} APIResponse[any] "物化失败"
+// @Router /metrics-store/metrics/{id}/refresh [post]
+func (c *MetricStoreController) RefreshMetric(w http.ResponseWriter, r *http.Request) {
+	metric, err := service.GlobalMetricStoreService.RefreshMetric(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		render.JSON(w, r, metricStoreErrorResponse("刷新指标失败", err))
+		return
+	}
+	render.JSON(w, r, SuccessResponse("刷新指标成功", metric))
+}
+
+// GetMetricValues 获取指标值
+// @Summary 获取指标值
+// @Description 获取指标最近一次物化的指标值，按时间周期排序。start、end为RFC3339时间，按时间周期起点过滤（只对配置了时间粒度的指标有效）；dimension为"维度字段:取值"，可重复，按取值文本过滤
+// @Tags 指标层
+// @Produce json
+// @Param id path string true "指标ID"
+// @Param start query string false "时间周期起点不早于该时间" example(2026-10-01T00:00:00+08:00)
+// @Param end query string false "时间周期起点早于该时间"
+// @Param dimension query []string false "维度过滤，如parking_lot:P1" collectionFormat(multi)
+// @Success 200 {object} APIResponse[metric_store.MetricValues] "获取成功"
+// @Failure 400 {object} APIResponse[any] "参数格式错误"
+// @Failure 404 {object} APIResponse[any] "指标不存在"
+// @Router /metrics-store/metrics/{id}/values [get]
+func (c *MetricStoreController) GetMetricValues(w http.ResponseWriter, r *http.Request) {
+	query := metric_store.ValueQuery{Dimensions: map[string]string{}}
+	params := r.URL.Query()
+	for name, target := range map[string]**time.Time{"start": &query.Start, "end": &query.End} {
+		if value := params.Get(name); value != "" {
+			t, err := time.Parse(time.RFC3339, value)
+			if err != nil {
+				render.JSON(w, r, BadRequestResponse(name+"须为RFC3339格式的时间", err))
+				return
+			}
+			*target = &t
+		}
+	}
+	for _, filter := range params["dimension"] {
+		name, value, ok := strings.Cut(filter, ":")
+		if !ok || name == "" {
+			render.JSON(w, r, BadRequestResponse("dimension须为\"维度字段:取值\"格式", nil))
+			return
+		}
+		query.Dimensions[name] = value
+	}
+
+	values, err := service.GlobalMetricStoreService.GetValues(r.Context(), chi.URLParam(r, "id"), query)
+	if err != nil {
+		render.JSON(w, r, MapErrorResponse("获取指标值失败", err))
+		return
+	}
+	render.JSON(w, r, SuccessResponse("获取指标值成功", values))
+}
+
+// metricStoreErrorResponse 按指标层的错误类型创建错误响应
+func metricStoreErrorResponse(msg string, err error) render.Renderer {
+	switch {
+	case errors.Is(err, metric_store.ErrInvalidMetric):
+		return BadRequestResponse(msg+": "+err.Error(), err)
+	case errors.Is(err, metric_store.ErrMetricRefreshing):
+		return ErrorResponse(StatusConflict, err.Error(), err)
+	case errors.Is(err, metric_store.ErrMetricRefreshFailed):
+		return ErrorResponse(StatusUnprocessableEntity, msg+": "+err.Error(), err)
+	default:
+		return MapErrorResponse(msg, err)
+	}
+}
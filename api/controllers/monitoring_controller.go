@@ -36,9 +36,9 @@ func NewMonitoringController() *MonitoringController {
 // @Accept json
 // @Produce json
 // @Param query body meta.MonitorQueryRequest true "查询请求"
-// @Success 200 {object} APIResponse{data=object}
-// @Failure 400 {object} APIResponse
-// @Failure 500 {object} APIResponse
+// @Success 200 {object} APIResponse[any]{data=object}
+// @Failure 400 {object} APIResponse[any]
+// @Failure 500 {object} APIResponse[any]
 // @Router /monitoring/query/metrics [post]
 func (c *MonitoringController) QueryMetrics(w http.ResponseWriter, r *http.Request) {
 	var req meta.MonitorQueryRequest
@@ -95,9 +95,9 @@ func (c *MonitoringController) QueryMetrics(w http.ResponseWriter, r *http.Reque
 // @Accept json
 // @Produce json
 // @Param query body meta.MonitorQueryRequest true "查询请求"
-// @Success 200 {object} APIResponse{data=object}
-// @Failure 400 {object} APIResponse
-// @Failure 500 {object} APIResponse
+// @Success 200 {object} APIResponse[any]{data=object}
+// @Failure 400 {object} APIResponse[any]
+// @Failure 500 {object} APIResponse[any]
 // @Router /monitoring/query/logs [post]
 func (c *MonitoringController) QueryLogs(w http.ResponseWriter, r *http.Request) {
 	var req meta.MonitorQueryRequest
@@ -148,8 +148,8 @@ func (c *MonitoringController) QueryLogs(w http.ResponseWriter, r *http.Request)
 // @Tags 监控模板
 // @Accept json
 // @Produce json
-// @Success 200 {object} APIResponse{data=map[string]string}
-// @Failure 500 {object} APIResponse
+// @Success 200 {object} APIResponse[map[string]string]
+// @Failure 500 {object} APIResponse[any]
 // @Router /monitoring/templates/metrics [get]
 func (c *MonitoringController) GetMetricTemplates(w http.ResponseWriter, r *http.Request) {
 	c.writeSuccessResponse(w, "获取指标模板成功", meta.CommonMetricTemplates)
@@ -161,8 +161,8 @@ func (c *MonitoringController) GetMetricTemplates(w http.ResponseWriter, r *http
 // @Tags 监控模板
 // @Accept json
 // @Produce json
-// @Success 200 {object} APIResponse{data=map[string]string}
-// @Failure 500 {object} APIResponse
+// @Success 200 {object} APIResponse[map[string]string]
+// @Failure 500 {object} APIResponse[any]
 // @Router /monitoring/templates/logs [get]
 func (c *MonitoringController) GetLogTemplates(w http.ResponseWriter, r *http.Request) {
 	c.writeSuccessResponse(w, "获取日志模板成功", meta.CommonLogTemplates)
@@ -174,7 +174,7 @@ func (c *MonitoringController) GetLogTemplates(w http.ResponseWriter, r *http.Re
 // @Tags 监控模板
 // @Accept json
 // @Produce json
-// @Success 200 {object} APIResponse{data=map[string]string}
+// @Success 200 {object} APIResponse[map[string]string]
 // @Router /monitoring/metrics/descriptions [get]
 func (c *MonitoringController) GetMetricDescriptions(w http.ResponseWriter, r *http.Request) {
 	c.writeSuccessResponse(w, "获取指标描述成功", meta.MetricDescriptions)
@@ -186,7 +186,7 @@ func (c *MonitoringController) GetMetricDescriptions(w http.ResponseWriter, r *h
 // @Tags 监控模板
 // @Accept json
 // @Produce json
-// @Success 200 {object} APIResponse{data=map[string]string}
+// @Success 200 {object} APIResponse[map[string]string]
 // @Router /monitoring/logs/descriptions [get]
 func (c *MonitoringController) GetLogTemplateDescriptions(w http.ResponseWriter, r *http.Request) {
 	c.writeSuccessResponse(w, "获取日志模板描述成功", meta.LogTemplateDescriptions)
@@ -199,9 +199,9 @@ func (c *MonitoringController) GetLogTemplateDescriptions(w http.ResponseWriter,
 // @Accept json
 // @Produce json
 // @Param label path string true "标签名称"
-// @Success 200 {object} APIResponse{data=[]string}
-// @Failure 400 {object} APIResponse
-// @Failure 500 {object} APIResponse
+// @Success 200 {object} APIResponse[[]string]
+// @Failure 400 {object} APIResponse[any]
+// @Failure 500 {object} APIResponse[any]
 // @Router /monitoring/loki/labels/{label}/values [get]
 func (c *MonitoringController) GetLokiLabels(w http.ResponseWriter, r *http.Request) {
 	label := chi.URLParam(r, "label")
@@ -227,9 +227,9 @@ func (c *MonitoringController) GetLokiLabels(w http.ResponseWriter, r *http.Requ
 // @Accept json
 // @Produce json
 // @Param query body meta.MonitorQueryRequest true "查询请求"
-// @Success 200 {object} APIResponse{data=object}
-// @Failure 400 {object} APIResponse
-// @Failure 500 {object} APIResponse
+// @Success 200 {object} APIResponse[any]{data=object}
+// @Failure 400 {object} APIResponse[any]
+// @Failure 500 {object} APIResponse[any]
 // @Router /monitoring/query [post]
 func (c *MonitoringController) ExecuteCustomQuery(w http.ResponseWriter, r *http.Request) {
 	var req meta.MonitorQueryRequest
@@ -314,7 +314,7 @@ func (c *MonitoringController) ExecuteCustomQuery(w http.ResponseWriter, r *http
 // @Tags 监控配置
 // @Accept json
 // @Produce json
-// @Success 200 {object} APIResponse{data=object}
+// @Success 200 {object} APIResponse[any]{data=object}
 // @Router /monitoring/config [get]
 func (c *MonitoringController) GetMonitoringConfig(w http.ResponseWriter, r *http.Request) {
 	config := map[string]interface{}{
@@ -340,8 +340,8 @@ func (c *MonitoringController) GetMonitoringConfig(w http.ResponseWriter, r *htt
 // @Accept json
 // @Produce json
 // @Param query body meta.MonitorQueryRequest true "查询请求"
-// @Success 200 {object} APIResponse{data=object}
-// @Failure 400 {object} APIResponse
+// @Success 200 {object} APIResponse[any]{data=object}
+// @Failure 400 {object} APIResponse[any]
 // @Router /monitoring/query/validate [post]
 func (c *MonitoringController) ValidateQuery(w http.ResponseWriter, r *http.Request) {
 	var req meta.MonitorQueryRequest
@@ -384,7 +384,7 @@ func (c *MonitoringController) ValidateQuery(w http.ResponseWriter, r *http.Requ
 
 // writeSuccessResponse 写入成功响应
 func (c *MonitoringController) writeSuccessResponse(w http.ResponseWriter, message string, data interface{}) {
-	response := &APIResponse{
+	response := &APIResponse[any]{
 		Status: 0,
 		Msg:    message,
 		Data:   data,
@@ -401,7 +401,7 @@ func (c *MonitoringController) writeErrorResponse(w http.ResponseWriter, statusC
 		errorMsg = fmt.Sprintf("%s: %v", message, err)
 	}
 
-	response := &APIResponse{
+	response := &APIResponse[any]{
 		Status: -1,
 		Msg:    errorMsg,
 		Data:   nil,
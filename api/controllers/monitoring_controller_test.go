@@ -38,7 +38,7 @@ func TestGetMetricTemplates(t *testing.T) {
 
 	assert.Equal(t, http.StatusOK, w.Code)
 
-	var response APIResponse
+	var response APIResponse[any]
 	err := json.NewDecoder(w.Body).Decode(&response)
 	require.NoError(t, err)
 	assert.Equal(t, 0, response.Status)
@@ -66,7 +66,7 @@ func TestGetLogTemplates(t *testing.T) {
 
 	assert.Equal(t, http.StatusOK, w.Code)
 
-	var response APIResponse
+	var response APIResponse[any]
 	err := json.NewDecoder(w.Body).Decode(&response)
 	require.NoError(t, err)
 	assert.Equal(t, 0, response.Status)
@@ -93,7 +93,7 @@ func TestGetMetricDescriptions(t *testing.T) {
 
 	assert.Equal(t, http.StatusOK, w.Code)
 
-	var response APIResponse
+	var response APIResponse[any]
 	err := json.NewDecoder(w.Body).Decode(&response)
 	require.NoError(t, err)
 	assert.Equal(t, 0, response.Status)
@@ -122,7 +122,7 @@ func TestGetMonitoringConfig(t *testing.T) {
 
 	assert.Equal(t, http.StatusOK, w.Code)
 
-	var response APIResponse
+	var response APIResponse[any]
 	err := json.NewDecoder(w.Body).Decode(&response)
 	require.NoError(t, err)
 	assert.Equal(t, 0, response.Status)
@@ -155,7 +155,7 @@ func TestQueryMetrics_EmptyQuery(t *testing.T) {
 
 	assert.Equal(t, http.StatusBadRequest, w.Code)
 
-	var response APIResponse
+	var response APIResponse[any]
 	err := json.NewDecoder(w.Body).Decode(&response)
 	require.NoError(t, err)
 	assert.Equal(t, -1, response.Status)
@@ -175,7 +175,7 @@ func TestQueryMetrics_InvalidJSON(t *testing.T) {
 
 	assert.Equal(t, http.StatusBadRequest, w.Code)
 
-	var response APIResponse
+	var response APIResponse[any]
 	err := json.NewDecoder(w.Body).Decode(&response)
 	require.NoError(t, err)
 	assert.Equal(t, -1, response.Status)
@@ -247,7 +247,7 @@ func TestQueryLogs_EmptyQuery(t *testing.T) {
 
 	assert.Equal(t, http.StatusBadRequest, w.Code)
 
-	var response APIResponse
+	var response APIResponse[any]
 	err := json.NewDecoder(w.Body).Decode(&response)
 	require.NoError(t, err)
 	assert.Equal(t, -1, response.Status)
@@ -319,7 +319,7 @@ func TestExecuteCustomQuery_InvalidType(t *testing.T) {
 
 	assert.Equal(t, http.StatusBadRequest, w.Code)
 
-	var response APIResponse
+	var response APIResponse[any]
 	err := json.NewDecoder(w.Body).Decode(&response)
 	require.NoError(t, err)
 	assert.Equal(t, -1, response.Status)
@@ -344,7 +344,7 @@ func TestExecuteCustomQuery_EmptyType(t *testing.T) {
 
 	assert.Equal(t, http.StatusBadRequest, w.Code)
 
-	var response APIResponse
+	var response APIResponse[any]
 	err := json.NewDecoder(w.Body).Decode(&response)
 	require.NoError(t, err)
 	assert.Equal(t, -1, response.Status)
@@ -412,7 +412,7 @@ func TestValidateQuery_EmptyQuery(t *testing.T) {
 
 	assert.Equal(t, http.StatusBadRequest, w.Code)
 
-	var response APIResponse
+	var response APIResponse[any]
 	err := json.NewDecoder(w.Body).Decode(&response)
 	require.NoError(t, err)
 	assert.Equal(t, -1, response.Status)
@@ -437,7 +437,7 @@ func TestValidateQuery_MetricsQuery(t *testing.T) {
 	// 验证应该返回200，无论查询是否有效（验证过程本身应该成功）
 	assert.Equal(t, http.StatusOK, w.Code)
 
-	var response APIResponse
+	var response APIResponse[any]
 	err := json.NewDecoder(w.Body).Decode(&response)
 	require.NoError(t, err)
 	assert.Equal(t, 0, response.Status)
@@ -469,7 +469,7 @@ func TestGetLokiLabels_EmptyLabel(t *testing.T) {
 
 	assert.Equal(t, http.StatusBadRequest, w.Code)
 
-	var response APIResponse
+	var response APIResponse[any]
 	err := json.NewDecoder(w.Body).Decode(&response)
 	require.NoError(t, err)
 	assert.Equal(t, -1, response.Status)
@@ -508,7 +508,7 @@ func TestGetLogTemplateDescriptions(t *testing.T) {
 
 	assert.Equal(t, http.StatusOK, w.Code)
 
-	var response APIResponse
+	var response APIResponse[any]
 	err := json.NewDecoder(w.Body).Decode(&response)
 	require.NoError(t, err)
 	assert.Equal(t, 0, response.Status)
@@ -583,7 +583,7 @@ func TestLogTemplates_RealWorld(t *testing.T) {
 			w := httptest.NewRecorder()
 			controller.GetLogTemplates(w, req)
 
-			var response APIResponse
+			var response APIResponse[any]
 			err := json.NewDecoder(w.Body).Decode(&response)
 			require.NoError(t, err)
 
@@ -621,7 +621,7 @@ func TestLogQuery_InvalidRegex(t *testing.T) {
 	// 应该返回错误
 	assert.Equal(t, http.StatusInternalServerError, w.Code)
 
-	var response APIResponse
+	var response APIResponse[any]
 	err := json.NewDecoder(w.Body).Decode(&response)
 	require.NoError(t, err)
 	assert.Equal(t, -1, response.Status)
@@ -653,13 +653,13 @@ func TestLogQuery_WithTimeRange(t *testing.T) {
 	assert.True(t, w.Code == http.StatusOK || w.Code == http.StatusInternalServerError)
 
 	if w.Code == http.StatusOK {
-		var response APIResponse
+		var response APIResponse[any]
 		err := json.NewDecoder(w.Body).Decode(&response)
 		require.NoError(t, err)
 		assert.Equal(t, 0, response.Status)
 		t.Logf("查询成功，返回数据类型: %T", response.Data)
 	} else {
-		var response APIResponse
+		var response APIResponse[any]
 		err := json.NewDecoder(w.Body).Decode(&response)
 		require.NoError(t, err)
 		t.Logf("查询失败: %s", response.Msg)
@@ -715,7 +715,7 @@ func TestLogQuery_KubernetesLogs(t *testing.T) {
 			assert.True(t, w.Code == http.StatusOK || w.Code == http.StatusInternalServerError)
 
 			if w.Code == http.StatusOK {
-				var response APIResponse
+				var response APIResponse[any]
 				err := json.NewDecoder(w.Body).Decode(&response)
 				require.NoError(t, err)
 				assert.Equal(t, 0, response.Status)
@@ -0,0 +1,708 @@
+/*
+ * @module api/controllers/notification_controller
+ * @description 通知中心控制器，提供通知渠道、订阅规则和消息模板的管理、渠道测试、模板预览以及站内通知的查询和已读标记接口
+ * @architecture 分层架构 - 控制器层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow HTTP请求 -> 控制器 -> 通知中心服务 -> 数据库/通知渠道
+ * @rules 统一的错误处理和响应格式；渠道被订阅引用时不能删除；渠道测试直接返回发送结果
+ * @dependencies datahub-service/service, datahub-service/service/notification, github.com/go-chi/chi/v5
+ * @refs service/notification/notification_service.go
+ */
+
+package controllers
+
+import (
+	"datahub-service/service"
+	"datahub-service/service/models"
+	"datahub-service/service/notification"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"gorm.io/gorm"
+)
+
+// NotificationController 通知中心控制器
+type NotificationController struct {
+}
+
+// NewNotificationController 创建通知中心控制器实例
+func NewNotificationController() *NotificationController {
+	return &NotificationController{}
+}
+
+// NotifyChannelRequest 通知渠道请求结构
+type NotifyChannelRequest struct {
+	Name        string       `json:"name" validate:"required" example:"数据组钉钉群"`
+	Type        string       `json:"type" validate:"required" example:"dingtalk"` // webhook/email/dingtalk/wecom
+	Config      models.JSONB `json:"config" validate:"required"`                  // webhook: url、headers；email: recipients；dingtalk/wecom: webhook_url、secret
+	Locale      string       `json:"locale" example:"zh"`                         // 消息模板语言：zh/en，默认zh
+	IsEnabled   *bool        `json:"is_enabled,omitempty"`
+	Description string       `json:"description"`
+}
+
+// NotifySubscriptionRequest 订阅规则请求结构
+type NotifySubscriptionRequest struct {
+	Name        string   `json:"name" validate:"required" example:"同步失败告警"`
+	ChannelID   *string  `json:"channel_id,omitempty"`              // 为空时只生成站内通知
+	ObjectType  string   `json:"object_type" example:"sync_task"`   // sync_task/thematic_sync_task/quality_task/backup_record/data_interface，为空不限
+	ObjectID    string   `json:"object_id"`                         // 为空不限
+	EventTypes  []string `json:"event_types" example:"task_failed"` // 为空不限
+	MinLevel    string   `json:"min_level" example:"warn"`          // info/warn/error，默认warn
+	IsEnabled   *bool    `json:"is_enabled,omitempty"`
+	Description string   `json:"description"`
+}
+
+// NotifyTemplateRequest 消息模板请求结构
+type NotifyTemplateRequest struct {
+	EventType     string `json:"event_type" validate:"required" example:"task_failed"` // 事件类型，default为兜底模板
+	ChannelType   string `json:"channel_type" example:"dingtalk"`                      // 为空适用于所有渠道和站内通知
+	Locale        string `json:"locale" example:"zh"`                                  // zh/en，默认zh
+	TitleTemplate string `json:"title_template" validate:"required" example:"同步失败：{{.ObjectName}}"`
+	BodyTemplate  string `json:"body_template" validate:"required" example:"原因：{{.Summary}}"`
+	IsEnabled     *bool  `json:"is_enabled,omitempty"`
+	Description   string `json:"description"`
+}
+
+// NotifyTemplatePreviewRequest 消息模板预览请求结构，未提供模板内容时预览当前生效的模板
+type NotifyTemplatePreviewRequest struct {
+	EventType     string `json:"event_type" example:"task_failed"`
+	ChannelType   string `json:"channel_type" example:"dingtalk"`
+	Locale        string `json:"locale" example:"zh"`
+	TitleTemplate string `json:"title_template"`
+	BodyTemplate  string `json:"body_template"`
+}
+
+// NotifyTemplatePreviewResponse 消息模板预览响应结构
+type NotifyTemplatePreviewResponse struct {
+	Title   string `json:"title"`
+	Content string `json:"content"`
+}
+
+// NotifyChannelListResponse 通知渠道列表响应结构
+type NotifyChannelListResponse struct {
+	List []models.NotifyChannel `json:"list"`
+	models.PageMeta
+}
+
+// NotifySubscriptionListResponse 订阅规则列表响应结构
+type NotifySubscriptionListResponse struct {
+	List []models.NotifySubscription `json:"list"`
+	models.PageMeta
+}
+
+// NotifyTemplateListResponse 消息模板列表响应结构
+type NotifyTemplateListResponse struct {
+	List []models.NotifyTemplate `json:"list"`
+	models.PageMeta
+}
+
+// NotificationListResponse 站内通知列表响应结构
+type NotificationListResponse struct {
+	List []models.Notification `json:"list"`
+	models.PageMeta
+}
+
+// UnreadCountResponse 未读数量响应结构
+type UnreadCountResponse struct {
+	Count int64 `json:"count"`
+}
+
+// === 通知渠道 ===
+
+// GetNotifyChannels 获取通知渠道列表
+// @Summary 获取通知渠道列表
+// @Description 分页获取通知渠道，可按类型过滤
+// @Tags 通知中心
+// @Produce json
+// @Param page query int false "页码" default(1)
+// @Param size query int false "每页数量" default(10)
+// @Param type query string false "渠道类型" Enums(webhook, email, dingtalk, wecom)
+// @Success 200 {object} APIResponse[NotifyChannelListResponse] "获取成功"
+// @Router /notifications/channels [get]
+func (c *NotificationController) GetNotifyChannels(w http.ResponseWriter, r *http.Request) {
+	page, size := parsePagination(r)
+	channels, total, err := service.GlobalNotificationService.GetChannels(page, size, r.URL.Query().Get("type"))
+	if err != nil {
+		render.JSON(w, r, MapErrorResponse("获取通知渠道列表失败", err))
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("获取通知渠道列表成功", NotifyChannelListResponse{
+		List:     channels,
+		PageMeta: models.NewPageMeta(total, page, size),
+	}))
+}
+
+// CreateNotifyChannel 创建通知渠道
+// @Summary 创建通知渠道
+// @Description 创建Webhook、邮件、钉钉或企业微信机器人通知渠道
+// @Tags 通知中心
+// @Accept json
+// @Produce json
+// @Param request body NotifyChannelRequest true "通知渠道"
+// @Success 200 {object} APIResponse[models.NotifyChannel] "创建成功"
+// @Failure 400 {object} APIResponse[any] "请求参数错误"
+// @Router /notifications/channels [post]
+func (c *NotificationController) CreateNotifyChannel(w http.ResponseWriter, r *http.Request) {
+	var req NotifyChannelRequest
+	if !decodeAndValidate(w, r, &req) {
+		return
+	}
+
+	operator := getCurrentUsername(r)
+	channel := &models.NotifyChannel{
+		Name:        req.Name,
+		Type:        req.Type,
+		Config:      req.Config,
+		Locale:      req.Locale,
+		IsEnabled:   req.IsEnabled == nil || *req.IsEnabled,
+		Description: req.Description,
+		CreatedBy:   operator,
+		UpdatedBy:   operator,
+	}
+	if err := service.GlobalNotificationService.CreateChannel(channel); err != nil {
+		render.JSON(w, r, BadRequestResponse("创建通知渠道失败", err))
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("创建通知渠道成功", channel))
+}
+
+// GetNotifyChannel 获取通知渠道详情
+// @Summary 获取通知渠道详情
+// @Description 根据ID获取通知渠道
+// @Tags 通知中心
+// @Produce json
+// @Param id path string true "渠道ID"
+// @Success 200 {object} APIResponse[models.NotifyChannel] "获取成功"
+// @Failure 404 {object} APIResponse[any] "渠道不存在"
+// @Router /notifications/channels/{id} [get]
+func (c *NotificationController) GetNotifyChannel(w http.ResponseWriter, r *http.Request) {
+	channel, err := service.GlobalNotificationService.GetChannel(chi.URLParam(r, "id"))
+	if err != nil {
+		render.JSON(w, r, MapErrorResponse("获取通知渠道失败", err))
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("获取通知渠道成功", channel))
+}
+
+// UpdateNotifyChannel 更新通知渠道
+// @Summary 更新通知渠道
+// @Description 更新通知渠道的名称、类型、配置、语言和启用状态
+// @Tags 通知中心
+// @Accept json
+// @Produce json
+// @Param id path string true "渠道ID"
+// @Param request body NotifyChannelRequest true "通知渠道"
+// @Success 200 {object} APIResponse[any] "更新成功"
+// @Failure 400 {object} APIResponse[any] "请求参数错误"
+// @Failure 404 {object} APIResponse[any] "渠道不存在"
+// @Router /notifications/channels/{id} [put]
+func (c *NotificationController) UpdateNotifyChannel(w http.ResponseWriter, r *http.Request) {
+	var req NotifyChannelRequest
+	if !decodeAndValidate(w, r, &req) {
+		return
+	}
+
+	updates := map[string]interface{}{
+		"name":        req.Name,
+		"type":        req.Type,
+		"config":      req.Config,
+		"description": req.Description,
+		"updated_by":  getCurrentUsername(r),
+	}
+	if req.Locale != "" {
+		updates["locale"] = req.Locale
+	}
+	if req.IsEnabled != nil {
+		updates["is_enabled"] = *req.IsEnabled
+	}
+
+	if err := service.GlobalNotificationService.UpdateChannel(chi.URLParam(r, "id"), updates); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			render.JSON(w, r, NotFoundResponse("通知渠道不存在", err))
+			return
+		}
+		render.JSON(w, r, BadRequestResponse("更新通知渠道失败", err))
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("更新通知渠道成功", nil))
+}
+
+// DeleteNotifyChannel 删除通知渠道
+// @Summary 删除通知渠道
+// @Description 删除通知渠道，渠道被订阅规则引用时不能删除
+// @Tags 通知中心
+// @Produce json
+// @Param id path string true "渠道ID"
+// @Success 200 {object} APIResponse[any] "删除成功"
+// @Failure 404 {object} APIResponse[any] "渠道不存在"
+// @Failure 409 {object} APIResponse[any] "渠道被订阅规则或报表订阅引用"
+// @Router /notifications/channels/{id} [delete]
+func (c *NotificationController) DeleteNotifyChannel(w http.ResponseWriter, r *http.Request) {
+	if err := service.GlobalNotificationService.DeleteChannel(chi.URLParam(r, "id")); err != nil {
+		if errors.Is(err, notification.ErrChannelInUse) || errors.Is(err, notification.ErrReportChannelInUse) {
+			render.JSON(w, r, ConflictResponse("删除通知渠道失败", err))
+			return
+		}
+		render.JSON(w, r, MapErrorResponse("删除通知渠道失败", err))
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("删除通知渠道成功", nil))
+}
+
+// TestNotifyChannel 测试通知渠道
+// @Summary 测试通知渠道
+// @Description 向渠道发送一条测试消息并返回发送结果
+// @Tags 通知中心
+// @Produce json
+// @Param id path string true "渠道ID"
+// @Success 200 {object} APIResponse[any] "发送成功"
+// @Failure 400 {object} APIResponse[any] "发送失败"
+// @Failure 404 {object} APIResponse[any] "渠道不存在"
+// @Router /notifications/channels/{id}/test [post]
+func (c *NotificationController) TestNotifyChannel(w http.ResponseWriter, r *http.Request) {
+	if err := service.GlobalNotificationService.TestChannel(r.Context(), chi.URLParam(r, "id")); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			render.JSON(w, r, NotFoundResponse("通知渠道不存在", err))
+			return
+		}
+		render.JSON(w, r, BadRequestResponse("测试消息发送失败", err))
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("测试消息发送成功", nil))
+}
+
+// === 订阅规则 ===
+
+// GetNotifySubscriptions 获取订阅规则列表
+// @Summary 获取订阅规则列表
+// @Description 分页获取订阅规则，可按渠道和对象类型过滤
+// @Tags 通知中心
+// @Produce json
+// @Param page query int false "页码" default(1)
+// @Param size query int false "每页数量" default(10)
+// @Param channel_id query string false "渠道ID"
+// @Param object_type query string false "对象类型"
+// @Success 200 {object} APIResponse[NotifySubscriptionListResponse] "获取成功"
+// @Router /notifications/subscriptions [get]
+func (c *NotificationController) GetNotifySubscriptions(w http.ResponseWriter, r *http.Request) {
+	page, size := parsePagination(r)
+	query := r.URL.Query()
+	subs, total, err := service.GlobalNotificationService.GetSubscriptions(page, size, query.Get("channel_id"), query.Get("object_type"))
+	if err != nil {
+		render.JSON(w, r, MapErrorResponse("获取订阅规则列表失败", err))
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("获取订阅规则列表成功", NotifySubscriptionListResponse{
+		List:     subs,
+		PageMeta: models.NewPageMeta(total, page, size),
+	}))
+}
+
+// CreateNotifySubscription 创建订阅规则
+// @Summary 创建订阅规则
+// @Description 按对象、事件类型和最低级别订阅应用事件，匹配的事件生成站内通知并投递到指定渠道
+// @Tags 通知中心
+// @Accept json
+// @Produce json
+// @Param request body NotifySubscriptionRequest true "订阅规则"
+// @Success 200 {object} APIResponse[models.NotifySubscription] "创建成功"
+// @Failure 400 {object} APIResponse[any] "请求参数错误"
+// @Router /notifications/subscriptions [post]
+func (c *NotificationController) CreateNotifySubscription(w http.ResponseWriter, r *http.Request) {
+	var req NotifySubscriptionRequest
+	if !decodeAndValidate(w, r, &req) {
+		return
+	}
+
+	operator := getCurrentUsername(r)
+	sub := req.toModel()
+	sub.CreatedBy = operator
+	sub.UpdatedBy = operator
+	if err := service.GlobalNotificationService.CreateSubscription(sub); err != nil {
+		render.JSON(w, r, BadRequestResponse("创建订阅规则失败", err))
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("创建订阅规则成功", sub))
+}
+
+// GetNotifySubscription 获取订阅规则详情
+// @Summary 获取订阅规则详情
+// @Description 根据ID获取订阅规则
+// @Tags 通知中心
+// @Produce json
+// @Param id path string true "订阅ID"
+// @Success 200 {object} APIResponse[models.NotifySubscription] "获取成功"
+// @Failure 404 {object} APIResponse[any] "订阅不存在"
+// @Router /notifications/subscriptions/{id} [get]
+func (c *NotificationController) GetNotifySubscription(w http.ResponseWriter, r *http.Request) {
+	sub, err := service.GlobalNotificationService.GetSubscription(chi.URLParam(r, "id"))
+	if err != nil {
+		render.JSON(w, r, MapErrorResponse("获取订阅规则失败", err))
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("获取订阅规则成功", sub))
+}
+
+// UpdateNotifySubscription 更新订阅规则
+// @Summary 更新订阅规则
+// @Description 整体更新订阅规则
+// @Tags 通知中心
+// @Accept json
+// @Produce json
+// @Param id path string true "订阅ID"
+// @Param request body NotifySubscriptionRequest true "订阅规则"
+// @Success 200 {object} APIResponse[any] "更新成功"
+// @Failure 400 {object} APIResponse[any] "请求参数错误"
+// @Failure 404 {object} APIResponse[any] "订阅不存在"
+// @Router /notifications/subscriptions/{id} [put]
+func (c *NotificationController) UpdateNotifySubscription(w http.ResponseWriter, r *http.Request) {
+	var req NotifySubscriptionRequest
+	if !decodeAndValidate(w, r, &req) {
+		return
+	}
+
+	sub := req.toModel()
+	sub.UpdatedBy = getCurrentUsername(r)
+	if err := service.GlobalNotificationService.UpdateSubscription(chi.URLParam(r, "id"), sub); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			render.JSON(w, r, NotFoundResponse("订阅规则不存在", err))
+			return
+		}
+		render.JSON(w, r, BadRequestResponse("更新订阅规则失败", err))
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("更新订阅规则成功", nil))
+}
+
+// DeleteNotifySubscription 删除订阅规则
+// @Summary 删除订阅规则
+// @Description 根据ID删除订阅规则
+// @Tags 通知中心
+// @Produce json
+// @Param id path string true "订阅ID"
+// @Success 200 {object} APIResponse[any] "删除成功"
+// @Failure 404 {object} APIResponse[any] "订阅不存在"
+// @Router /notifications/subscriptions/{id} [delete]
+func (c *NotificationController) DeleteNotifySubscription(w http.ResponseWriter, r *http.Request) {
+	if err := service.GlobalNotificationService.DeleteSubscription(chi.URLParam(r, "id")); err != nil {
+		render.JSON(w, r, MapErrorResponse("删除订阅规则失败", err))
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("删除订阅规则成功", nil))
+}
+
+// toModel 请求转换为订阅规则模型
+func (req *NotifySubscriptionRequest) toModel() *models.NotifySubscription {
+	return &models.NotifySubscription{
+		Name:        req.Name,
+		ChannelID:   req.ChannelID,
+		ObjectType:  req.ObjectType,
+		ObjectID:    req.ObjectID,
+		EventTypes:  req.EventTypes,
+		MinLevel:    req.MinLevel,
+		IsEnabled:   req.IsEnabled == nil || *req.IsEnabled,
+		Description: req.Description,
+	}
+}
+
+// === 消息模板 ===
+
+// GetNotifyTemplates 获取消息模板列表
+// @Summary 获取消息模板列表
+// @Description 分页获取自定义消息模板，可按事件类型和语言过滤
+// @Tags 通知中心
+// @Produce json
+// @Param page query int false "页码" default(1)
+// @Param size query int false "每页数量" default(10)
+// @Param event_type query string false "事件类型"
+// @Param locale query string false "语言" Enums(zh, en)
+// @Success 200 {object} APIResponse[NotifyTemplateListResponse] "获取成功"
+// @Router /notifications/templates [get]
+func (c *NotificationController) GetNotifyTemplates(w http.ResponseWriter, r *http.Request) {
+	page, size := parsePagination(r)
+	query := r.URL.Query()
+	templates, total, err := service.GlobalNotificationService.GetTemplates(page, size, query.Get("event_type"), query.Get("locale"))
+	if err != nil {
+		render.JSON(w, r, MapErrorResponse("获取消息模板列表失败", err))
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("获取消息模板列表成功", NotifyTemplateListResponse{
+		List:     templates,
+		PageMeta: models.NewPageMeta(total, page, size),
+	}))
+}
+
+// CreateNotifyTemplate 创建消息模板
+// @Summary 创建消息模板
+// @Description 创建Go模板语法的自定义消息模板，覆盖指定事件类型、渠道类型和语言的内置模板，保存前使用示例数据试渲染
+// @Tags 通知中心
+// @Accept json
+// @Produce json
+// @Param request body NotifyTemplateRequest true "消息模板"
+// @Success 200 {object} APIResponse[models.NotifyTemplate] "创建成功"
+// @Failure 400 {object} APIResponse[any] "请求参数错误"
+// @Router /notifications/templates [post]
+func (c *NotificationController) CreateNotifyTemplate(w http.ResponseWriter, r *http.Request) {
+	var req NotifyTemplateRequest
+	if !decodeAndValidate(w, r, &req) {
+		return
+	}
+
+	operator := getCurrentUsername(r)
+	tpl := req.toModel()
+	tpl.CreatedBy = operator
+	tpl.UpdatedBy = operator
+	if err := service.GlobalNotificationService.CreateTemplate(tpl); err != nil {
+		render.JSON(w, r, BadRequestResponse("创建消息模板失败", err))
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("创建消息模板成功", tpl))
+}
+
+// GetNotifyTemplate 获取消息模板详情
+// @Summary 获取消息模板详情
+// @Description 根据ID获取自定义消息模板
+// @Tags 通知中心
+// @Produce json
+// @Param id path string true "模板ID"
+// @Success 200 {object} APIResponse[models.NotifyTemplate] "获取成功"
+// @Failure 404 {object} APIResponse[any] "模板不存在"
+// @Router /notifications/templates/{id} [get]
+func (c *NotificationController) GetNotifyTemplate(w http.ResponseWriter, r *http.Request) {
+	tpl, err := service.GlobalNotificationService.GetTemplate(chi.URLParam(r, "id"))
+	if err != nil {
+		render.JSON(w, r, MapErrorResponse("获取消息模板失败", err))
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("获取消息模板成功", tpl))
+}
+
+// UpdateNotifyTemplate 更新消息模板
+// @Summary 更新消息模板
+// @Description 整体更新自定义消息模板
+// @Tags 通知中心
+// @Accept json
+// @Produce json
+// @Param id path string true "模板ID"
+// @Param request body NotifyTemplateRequest true "消息模板"
+// @Success 200 {object} APIResponse[any] "更新成功"
+// @Failure 400 {object} APIResponse[any] "请求参数错误"
+// @Failure 404 {object} APIResponse[any] "模板不存在"
+// @Router /notifications/templates/{id} [put]
+func (c *NotificationController) UpdateNotifyTemplate(w http.ResponseWriter, r *http.Request) {
+	var req NotifyTemplateRequest
+	if !decodeAndValidate(w, r, &req) {
+		return
+	}
+
+	tpl := req.toModel()
+	tpl.UpdatedBy = getCurrentUsername(r)
+	if err := service.GlobalNotificationService.UpdateTemplate(chi.URLParam(r, "id"), tpl); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			render.JSON(w, r, NotFoundResponse("消息模板不存在", err))
+			return
+		}
+		render.JSON(w, r, BadRequestResponse("更新消息模板失败", err))
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("更新消息模板成功", nil))
+}
+
+// DeleteNotifyTemplate 删除消息模板
+// @Summary 删除消息模板
+// @Description 删除自定义消息模板，删除后恢复使用内置模板
+// @Tags 通知中心
+// @Produce json
+// @Param id path string true "模板ID"
+// @Success 200 {object} APIResponse[any] "删除成功"
+// @Failure 404 {object} APIResponse[any] "模板不存在"
+// @Router /notifications/templates/{id} [delete]
+func (c *NotificationController) DeleteNotifyTemplate(w http.ResponseWriter, r *http.Request) {
+	if err := service.GlobalNotificationService.DeleteTemplate(chi.URLParam(r, "id")); err != nil {
+		render.JSON(w, r, MapErrorResponse("删除消息模板失败", err))
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("删除消息模板成功", nil))
+}
+
+// PreviewNotifyTemplate 预览消息模板
+// @Summary 预览消息模板
+// @Description 使用示例数据渲染模板；未提供模板内容时按事件类型、渠道类型和语言预览当前生效的模板
+// @Tags 通知中心
+// @Accept json
+// @Produce json
+// @Param request body NotifyTemplatePreviewRequest true "预览参数"
+// @Success 200 {object} APIResponse[NotifyTemplatePreviewResponse] "预览成功"
+// @Failure 400 {object} APIResponse[any] "模板错误"
+// @Router /notifications/templates/preview [post]
+func (c *NotificationController) PreviewNotifyTemplate(w http.ResponseWriter, r *http.Request) {
+	var req NotifyTemplatePreviewRequest
+	if !decodeAndValidate(w, r, &req) {
+		return
+	}
+
+	title, content, err := service.GlobalNotificationService.PreviewTemplate(&models.NotifyTemplate{
+		EventType:     req.EventType,
+		ChannelType:   req.ChannelType,
+		Locale:        req.Locale,
+		TitleTemplate: req.TitleTemplate,
+		BodyTemplate:  req.BodyTemplate,
+	})
+	if err != nil {
+		render.JSON(w, r, BadRequestResponse("预览消息模板失败", err))
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("预览消息模板成功", NotifyTemplatePreviewResponse{Title: title, Content: content}))
+}
+
+// toModel 请求转换为消息模板模型
+func (req *NotifyTemplateRequest) toModel() *models.NotifyTemplate {
+	return &models.NotifyTemplate{
+		EventType:     req.EventType,
+		ChannelType:   req.ChannelType,
+		Locale:        req.Locale,
+		TitleTemplate: req.TitleTemplate,
+		BodyTemplate:  req.BodyTemplate,
+		IsEnabled:     req.IsEnabled == nil || *req.IsEnabled,
+		Description:   req.Description,
+	}
+}
+
+// === 站内通知 ===
+
+// GetNotifications 获取站内通知列表
+// @Summary 获取站内通知列表
+// @Description 按已读状态、级别、事件类型和对象过滤站内通知，按时间倒序
+// @Tags 通知中心
+// @Produce json
+// @Param page query int false "页码" default(1)
+// @Param size query int false "每页数量" default(10)
+// @Param is_read query bool false "是否已读"
+// @Param level query string false "级别" Enums(info, warn, error)
+// @Param event_type query string false "事件类型"
+// @Param object_type query string false "对象类型"
+// @Param object_id query string false "对象ID"
+// @Success 200 {object} APIResponse[NotificationListResponse] "获取成功"
+// @Router /notifications [get]
+func (c *NotificationController) GetNotifications(w http.ResponseWriter, r *http.Request) {
+	page, size := parsePagination(r)
+	query := r.URL.Query()
+	notificationQuery := notification.NotificationQuery{
+		Level:      query.Get("level"),
+		EventType:  query.Get("event_type"),
+		ObjectType: query.Get("object_type"),
+		ObjectID:   query.Get("object_id"),
+		Page:       page,
+		Size:       size,
+	}
+	if value := query.Get("is_read"); value != "" {
+		isRead, err := strconv.ParseBool(value)
+		if err != nil {
+			render.JSON(w, r, BadRequestResponse("is_read参数无效", err))
+			return
+		}
+		notificationQuery.IsRead = &isRead
+	}
+
+	notifications, total, err := service.GlobalNotificationService.ListNotifications(notificationQuery)
+	if err != nil {
+		render.JSON(w, r, MapErrorResponse("获取站内通知列表失败", err))
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("获取站内通知列表成功", NotificationListResponse{
+		List:     notifications,
+		PageMeta: models.NewPageMeta(total, page, size),
+	}))
+}
+
+// GetUnreadNotificationCount 获取未读通知数量
+// @Summary 获取未读通知数量
+// @Description 获取未读站内通知数量
+// @Tags 通知中心
+// @Produce json
+// @Success 200 {object} APIResponse[UnreadCountResponse] "获取成功"
+// @Router /notifications/unread-count [get]
+func (c *NotificationController) GetUnreadNotificationCount(w http.ResponseWriter, r *http.Request) {
+	count, err := service.GlobalNotificationService.UnreadCount()
+	if err != nil {
+		render.JSON(w, r, MapErrorResponse("获取未读通知数量失败", err))
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("获取未读通知数量成功", UnreadCountResponse{Count: count}))
+}
+
+// GetNotification 获取站内通知详情
+// @Summary 获取站内通知详情
+// @Description 获取站内通知及各渠道投递记录
+// @Tags 通知中心
+// @Produce json
+// @Param id path string true "通知ID"
+// @Success 200 {object} APIResponse[models.Notification] "获取成功"
+// @Failure 404 {object} APIResponse[any] "通知不存在"
+// @Router /notifications/{id} [get]
+func (c *NotificationController) GetNotification(w http.ResponseWriter, r *http.Request) {
+	n, err := service.GlobalNotificationService.GetNotification(chi.URLParam(r, "id"))
+	if err != nil {
+		render.JSON(w, r, MapErrorResponse("获取站内通知失败", err))
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("获取站内通知成功", n))
+}
+
+// MarkNotificationRead 标记通知已读
+// @Summary 标记通知已读
+// @Description 将指定站内通知标记为已读
+// @Tags 通知中心
+// @Produce json
+// @Param id path string true "通知ID"
+// @Success 200 {object} APIResponse[any] "标记成功"
+// @Failure 404 {object} APIResponse[any] "通知不存在"
+// @Router /notifications/{id}/read [post]
+func (c *NotificationController) MarkNotificationRead(w http.ResponseWriter, r *http.Request) {
+	if err := service.GlobalNotificationService.MarkRead(chi.URLParam(r, "id"), getCurrentUsername(r)); err != nil {
+		render.JSON(w, r, MapErrorResponse("标记通知已读失败", err))
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("标记通知已读成功", nil))
+}
+
+// MarkAllNotificationsRead 全部标记已读
+// @Summary 全部标记已读
+// @Description 将全部未读站内通知标记为已读
+// @Tags 通知中心
+// @Produce json
+// @Success 200 {object} APIResponse[UnreadCountResponse] "标记成功，返回标记数量"
+// @Router /notifications/read-all [post]
+func (c *NotificationController) MarkAllNotificationsRead(w http.ResponseWriter, r *http.Request) {
+	count, err := service.GlobalNotificationService.MarkAllRead(getCurrentUsername(r))
+	if err != nil {
+		render.JSON(w, r, MapErrorResponse("全部标记已读失败", err))
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("全部标记已读成功", UnreadCountResponse{Count: count}))
+}
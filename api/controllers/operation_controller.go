@@ -0,0 +1,176 @@
+/*
+ * @module api/controllers/operation_controller
+ * @description 长时操作控制器，提供异步启动接口返回的操作资源的查询和取消接口
+ * @architecture 分层架构 - 控制器层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow HTTP请求 -> 控制器 -> 按操作类型鉴权 -> 长时操作服务 -> 执行方刷新/取消
+ * @rules 统一的错误处理和响应格式；操作类型与受保护资源同名，查询需要该资源的read权限，取消需要execute权限；已结束或不支持取消的操作返回409
+ * @dependencies datahub-service/service, datahub-service/service/operation, github.com/go-chi/chi/v5
+ * @refs service/operation/service.go, service/models/operation.go
+ */
+
+package controllers
+
+import (
+	"datahub-service/api/middleware"
+	"datahub-service/service"
+	"datahub-service/service/governance"
+	"datahub-service/service/models"
+	"datahub-service/service/operation"
+	"datahub-service/service/thematic_library"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+)
+
+// operationKinds 所有操作类型
+var operationKinds = []string{
+	models.OperationKindSyncTask,
+	models.OperationKindQualityTask,
+	models.OperationKindThematicSync,
+}
+
+// OperationController 长时操作控制器
+type OperationController struct {
+}
+
+// NewOperationController 创建长时操作控制器实例
+func NewOperationController() *OperationController {
+	return &OperationController{}
+}
+
+// OperationListResponse 操作列表响应结构
+type OperationListResponse struct {
+	List []models.Operation `json:"list"`
+	models.PageMeta
+}
+
+// GetOperations 获取操作列表
+// @Summary 获取操作列表
+// @Description 分页获取长时操作，按创建时间倒序；只返回当前用户有读取权限的操作类型，未结束的操作返回前按执行记录刷新
+// @Tags 长时操作
+// @Produce json
+// @Param kind query string false "操作类型" Enums(sync_task, quality_task, thematic_sync)
+// @Param target_id query string false "被操作的对象ID"
+// @Param status query string false "操作状态" Enums(running, succeeded, failed, canceled)
+// @Param page query int false "页码" default(1)
+// @Param size query int false "每页数量" default(10)
+// @Success 200 {object} APIResponse[OperationListResponse] "获取成功"
+// @Router /operations [get]
+func (c *OperationController) GetOperations(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	page, size := parsePagination(r)
+	filter := operation.ListFilter{
+		Kind:     query.Get("kind"),
+		TargetID: query.Get("target_id"),
+		Status:   query.Get("status"),
+		Page:     page,
+		Size:     size,
+	}
+	if c.rbacEnabled() {
+		filter.Kinds = []string{}
+		for _, kind := range operationKinds {
+			if c.allowed(r, kind, models.ActionRead) {
+				filter.Kinds = append(filter.Kinds, kind)
+			}
+		}
+	}
+
+	operations, total, err := service.GlobalOperationService.List(r.Context(), filter)
+	if err != nil {
+		render.JSON(w, r, MapErrorResponse("获取操作列表失败", err))
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("获取操作列表成功", OperationListResponse{
+		List:     operations,
+		PageMeta: models.NewPageMeta(total, page, size),
+	}))
+}
+
+// GetOperation 获取操作
+// @Summary 获取操作
+// @Description 获取长时操作的状态、进度、结果链接和取消链接，未结束的操作返回前按执行记录刷新
+// @Tags 长时操作
+// @Produce json
+// @Param id path string true "操作ID"
+// @Success 200 {object} APIResponse[models.Operation] "获取成功"
+// @Failure 403 {object} APIResponse[any] "缺少操作类型的读取权限"
+// @Failure 404 {object} APIResponse[any] "操作不存在"
+// @Router /operations/{id} [get]
+func (c *OperationController) GetOperation(w http.ResponseWriter, r *http.Request) {
+	op, err := service.GlobalOperationService.Get(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		render.JSON(w, r, MapErrorResponse("获取操作失败", err))
+		return
+	}
+	if !c.allowed(r, op.Kind, models.ActionRead) {
+		render.JSON(w, r, c.forbidden(op.Kind, models.ActionRead))
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("获取操作成功", op))
+}
+
+// CancelOperation 取消操作
+// @Summary 取消操作
+// @Description 取消未结束的长时操作：同步任务停止执行，质量检测执行和主题同步执行记为已取消并提前结束
+// @Tags 长时操作
+// @Produce json
+// @Param id path string true "操作ID"
+// @Success 200 {object} APIResponse[models.Operation] "取消成功"
+// @Failure 403 {object} APIResponse[any] "缺少操作类型的执行权限"
+// @Failure 404 {object} APIResponse[any] "操作不存在"
+// @Failure 409 {object} APIResponse[any] "操作已结束或不支持取消"
+// @Router /operations/{id}/cancel [post]
+func (c *OperationController) CancelOperation(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	op, err := service.GlobalOperationService.Get(r.Context(), id)
+	if err != nil {
+		render.JSON(w, r, MapErrorResponse("取消操作失败", err))
+		return
+	}
+	if !c.allowed(r, op.Kind, models.ActionExecute) {
+		render.JSON(w, r, c.forbidden(op.Kind, models.ActionExecute))
+		return
+	}
+
+	op, err = service.GlobalOperationService.Cancel(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, operation.ErrOperationFinished) || errors.Is(err, operation.ErrCancelNotSupported) ||
+			errors.Is(err, governance.ErrQualityExecutionFinished) || errors.Is(err, thematic_library.ErrThematicExecutionFinished) {
+			render.JSON(w, r, ConflictResponse("取消操作失败", err))
+			return
+		}
+		render.JSON(w, r, MapErrorResponse("取消操作失败", err))
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("取消操作成功", op))
+}
+
+// rbacEnabled 是否启用了访问控制
+func (c *OperationController) rbacEnabled() bool {
+	return service.GlobalRBACService != nil && service.GlobalRBACService.IsEnabled()
+}
+
+// allowed 当前用户是否拥有操作类型对应资源的权限
+func (c *OperationController) allowed(r *http.Request, kind, action string) bool {
+	if !c.rbacEnabled() {
+		return true
+	}
+	userInfo, ok := middleware.GetUserInfoFromContext(r.Context())
+	if !ok {
+		return false
+	}
+	roles := service.GlobalRBACService.ResolveRoles(userInfo.Username, userInfo.Roles)
+	return service.GlobalRBACService.HasPermission(roles, userInfo.Permissions, kind, action)
+}
+
+// forbidden 创建缺少权限的响应
+func (c *OperationController) forbidden(kind, action string) render.Renderer {
+	return ErrorResponse(StatusForbidden, fmt.Sprintf("缺少所需权限: %s:%s", kind, action), nil)
+}
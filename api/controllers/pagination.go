@@ -0,0 +1,33 @@
+/*
+ * @module api/controllers/pagination
+ * @description 列表接口分页参数解析，统一使用page、size参数并限制每页数量上限
+ * @architecture 分层架构 - 控制器层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow 查询参数 -> 解析page、size（兼容旧参数page_size） -> 规范化默认值和上限
+ * @rules 非法参数按默认值处理；size超过上限时按上限返回，响应中的size为实际生效值
+ * @dependencies datahub-service/service/models
+ * @refs service/models/pagination.go
+ */
+
+package controllers
+
+import (
+	"datahub-service/service/models"
+	"net/http"
+	"strconv"
+)
+
+// parsePagination 解析分页参数，返回规范化后的页码和每页数量
+func parsePagination(r *http.Request) (page, size int) {
+	query := r.URL.Query()
+	page, _ = strconv.Atoi(query.Get("page"))
+
+	sizeParam := query.Get("size")
+	if sizeParam == "" {
+		// 兼容旧参数名
+		sizeParam = query.Get("page_size")
+	}
+	size, _ = strconv.Atoi(sizeParam)
+
+	return models.NormalizePage(page, size)
+}
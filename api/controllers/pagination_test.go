@@ -0,0 +1,42 @@
+/*
+ * @module api/controllers/pagination_test
+ * @description 分页参数解析测试，覆盖默认值、上限和旧参数名兼容
+ * @architecture 测试层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow 构造查询参数 -> 解析 -> 验证页码和每页数量
+ * @rules 不依赖外部服务
+ * @dependencies testing, net/http/httptest, stretchr/testify
+ * @refs api/controllers/pagination.go
+ */
+
+package controllers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParsePagination(t *testing.T) {
+	cases := []struct {
+		query    string
+		wantPage int
+		wantSize int
+	}{
+		{"", 1, 10},
+		{"?page=3&size=20", 3, 20},
+		{"?page=abc&size=-1", 1, 10},
+		{"?size=1000", 1, 100},
+		{"?page=2&page_size=50", 2, 50},
+		{"?size=30&page_size=50", 1, 30},
+	}
+
+	for _, tc := range cases {
+		r := httptest.NewRequest(http.MethodGet, "/list"+tc.query, nil)
+		page, size := parsePagination(r)
+		assert.Equal(t, tc.wantPage, page, tc.query)
+		assert.Equal(t, tc.wantSize, size, tc.query)
+	}
+}
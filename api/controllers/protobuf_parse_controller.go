@@ -0,0 +1,194 @@
+/*
+ * @module api/controllers/protobuf_parse_controller
+ * @description 接口Protobuf解析配置控制器，上传.proto描述并配置消息类型和字段路径，支持用样例消息试解码
+ * @architecture 分层架构 - 控制器层
+ * @documentReference ai_docs/datasource_req1.md
+ * @stateFlow HTTP请求 -> 控制器 -> Protobuf解析配置服务 -> 编译校验并保存到接口解析配置
+ * @rules 统一的错误处理和响应格式；配置不合法或消息无法解码时返回400；If-Match与接口版本不一致时返回409；.proto文件不超过1MB，试解码消息不超过4MB
+ * @dependencies datahub-service/service, datahub-service/service/basic_library, github.com/go-chi/chi/v5
+ * @refs service/basic_library/protobuf_parse_service.go
+ */
+
+package controllers
+
+import (
+	"datahub-service/service"
+	"datahub-service/service/basic_library"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+)
+
+// .proto文件和试解码消息的大小上限
+const (
+	maxProtoUploadSize   = 1 << 20
+	maxProtoPayloadSize  = 4 << 20
+	protoUploadFormLimit = maxProtoUploadSize + 64<<10
+)
+
+// ProtobufParseController 接口Protobuf解析配置控制器
+type ProtobufParseController struct {
+}
+
+// NewProtobufParseController 创建接口Protobuf解析配置控制器实例
+func NewProtobufParseController() *ProtobufParseController {
+	return &ProtobufParseController{}
+}
+
+// GetProtobufParse 获取接口Protobuf解析配置
+// @Summary 获取接口Protobuf解析配置
+// @Description 获取接口已保存的.proto、消息类型、记录路径和字段路径，以及.proto中定义的全部消息
+// @Tags 数据基础库
+// @Produce json
+// @Param id path string true "接口ID"
+// @Success 200 {object} APIResponse[basic_library.ProtobufParseConfigView] "获取成功"
+// @Failure 404 {object} APIResponse[any] "接口不存在"
+// @Router /basic-libraries/interfaces/{id}/protobuf [get]
+func (c *ProtobufParseController) GetProtobufParse(w http.ResponseWriter, r *http.Request) {
+	view, err := service.GlobalProtobufParseService.GetConfig(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		render.JSON(w, r, MapErrorResponse("获取Protobuf解析配置失败", err))
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("获取Protobuf解析配置成功", view))
+}
+
+// SaveProtobufParse 保存接口Protobuf解析配置
+// @Summary 保存接口Protobuf解析配置
+// @Description 上传.proto文件并配置消息类型和字段路径，MQTT和HTTP POST数据源收到的非JSON消息按此解码为行后写入接口表。records_path指向repeated消息字段时每个元素一行；field_paths为输出字段名到字段路径的JSON对象，路径用点号分隔，以$.开头时从消息根部取值；输出字段再按解析配置的字段映射写入表字段。更新路径时可不上传文件，沿用已保存的.proto
+// @Tags 数据基础库
+// @Accept multipart/form-data
+// @Produce json
+// @Param id path string true "接口ID"
+// @Param If-Match header string false "期望版本（ETag），不一致时返回409"
+// @Param file formData file false ".proto文件，首次配置时必填"
+// @Param message_type formData string false "消息全名，为空时取第一个顶层消息"
+// @Param records_path formData string false "repeated消息字段的路径，为空时一条消息一行"
+// @Param field_paths formData string false "字段路径JSON对象，如 {\"device_id\": \"$.gateway.id\", \"value\": \"value\"}"
+// @Success 200 {object} APIResponse[basic_library.ProtobufParseConfigView] "保存成功"
+// @Failure 400 {object} APIResponse[any] ".proto无法解析或路径与消息定义不符"
+// @Failure 404 {object} APIResponse[any] "接口不存在"
+// @Failure 409 {object} APIResponse[VersionConflictData] "版本冲突"
+// @Router /basic-libraries/interfaces/{id}/protobuf [put]
+func (c *ProtobufParseController) SaveProtobufParse(w http.ResponseWriter, r *http.Request) {
+	expectedVersion, err := expectedRowVersion(r, 0)
+	if err != nil {
+		render.JSON(w, r, BadRequestResponse(err.Error(), err))
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, protoUploadFormLimit)
+	if err := r.ParseMultipartForm(protoUploadFormLimit); err != nil {
+		render.JSON(w, r, BadRequestResponse("解析上传内容失败", err))
+		return
+	}
+
+	req := &basic_library.ProtobufParseRequest{
+		MessageType: strings.TrimSpace(r.FormValue("message_type")),
+		RecordsPath: strings.TrimSpace(r.FormValue("records_path")),
+	}
+	if fieldPaths := strings.TrimSpace(r.FormValue("field_paths")); fieldPaths != "" {
+		if err := json.Unmarshal([]byte(fieldPaths), &req.FieldPaths); err != nil {
+			render.JSON(w, r, BadRequestResponse("field_paths须为字段名到路径的JSON对象", err))
+			return
+		}
+	}
+	file, header, err := r.FormFile("file")
+	if err == nil {
+		defer file.Close()
+		content, err := io.ReadAll(io.LimitReader(file, maxProtoUploadSize+1))
+		if err != nil {
+			render.JSON(w, r, BadRequestResponse("读取.proto文件失败", err))
+			return
+		}
+		if len(content) > maxProtoUploadSize {
+			render.JSON(w, r, BadRequestResponse(".proto文件过大", fmt.Errorf("不能超过%dKB", maxProtoUploadSize>>10)))
+			return
+		}
+		req.FileName, req.ProtoFile = header.Filename, string(content)
+	} else if !errors.Is(err, http.ErrMissingFile) {
+		render.JSON(w, r, BadRequestResponse("读取.proto文件失败", err))
+		return
+	}
+
+	view, err := service.GlobalProtobufParseService.SaveConfig(r.Context(), chi.URLParam(r, "id"), expectedVersion, getCurrentUsername(r), req)
+	if err != nil {
+		respondProtobufParseError(w, r, "保存Protobuf解析配置失败", err)
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("保存Protobuf解析配置成功", view))
+}
+
+// DeleteProtobufParse 删除接口Protobuf解析配置
+// @Summary 删除接口Protobuf解析配置
+// @Description 删除后接口不再接收二进制消息，重新接收JSON数据
+// @Tags 数据基础库
+// @Produce json
+// @Param id path string true "接口ID"
+// @Param If-Match header string false "期望版本（ETag），不一致时返回409"
+// @Success 200 {object} APIResponse[any] "删除成功"
+// @Failure 400 {object} APIResponse[any] "接口未配置Protobuf解析"
+// @Failure 404 {object} APIResponse[any] "接口不存在"
+// @Failure 409 {object} APIResponse[VersionConflictData] "版本冲突"
+// @Router /basic-libraries/interfaces/{id}/protobuf [delete]
+func (c *ProtobufParseController) DeleteProtobufParse(w http.ResponseWriter, r *http.Request) {
+	expectedVersion, err := expectedRowVersion(r, 0)
+	if err != nil {
+		render.JSON(w, r, BadRequestResponse(err.Error(), err))
+		return
+	}
+
+	if err := service.GlobalProtobufParseService.DeleteConfig(r.Context(), chi.URLParam(r, "id"), expectedVersion, getCurrentUsername(r)); err != nil {
+		respondProtobufParseError(w, r, "删除Protobuf解析配置失败", err)
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("删除Protobuf解析配置成功", nil))
+}
+
+// DecodeProtobuf 试解码Protobuf消息
+// @Summary 试解码Protobuf消息
+// @Description 请求体为一条二进制Protobuf消息，按接口已保存的配置解码，返回按字段路径取值的行和经过字段映射的行，不写入接口表，最多返回100行
+// @Tags 数据基础库
+// @Accept application/octet-stream
+// @Produce json
+// @Param id path string true "接口ID"
+// @Success 200 {object} APIResponse[basic_library.ProtobufDecodeResult] "解码成功"
+// @Failure 400 {object} APIResponse[any] "接口未配置Protobuf解析或消息无法解码"
+// @Failure 404 {object} APIResponse[any] "接口不存在"
+// @Router /basic-libraries/interfaces/{id}/protobuf/decode [post]
+func (c *ProtobufParseController) DecodeProtobuf(w http.ResponseWriter, r *http.Request) {
+	payload, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxProtoPayloadSize))
+	if err != nil {
+		render.JSON(w, r, BadRequestResponse("读取消息失败", err))
+		return
+	}
+
+	result, err := service.GlobalProtobufParseService.Decode(r.Context(), chi.URLParam(r, "id"), payload)
+	if err != nil {
+		respondProtobufParseError(w, r, "试解码Protobuf消息失败", err)
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("试解码Protobuf消息成功", result))
+}
+
+// respondProtobufParseError 版本冲突时返回409，配置不合法时返回400，其余按错误类型映射
+func respondProtobufParseError(w http.ResponseWriter, r *http.Request, msg string, err error) {
+	if handleVersionConflict(w, r, err) {
+		return
+	}
+	if errors.Is(err, basic_library.ErrInvalidProtobufParseRequest) {
+		render.JSON(w, r, BadRequestResponse(msg, err))
+		return
+	}
+	render.JSON(w, r, MapErrorResponse(msg, err))
+}
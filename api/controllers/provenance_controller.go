@@ -0,0 +1,117 @@
+/*
+ * @module api/controllers/provenance_controller
+ * @description 接口记录级来源追溯控制器，提供查询、开启、关闭来源追溯的接口
+ * @architecture 分层架构 - 控制器层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow HTTP请求 -> 控制器 -> 来源追溯配置服务 -> 增加或删除系统列并保存接口配置
+ * @rules 统一的错误处理和响应格式；配置不合法时返回400；If-Match与接口版本不一致时返回409
+ * @dependencies datahub-service/service, datahub-service/service/basic_library, github.com/go-chi/chi/v5
+ * @refs service/basic_library/provenance_service.go
+ */
+
+package controllers
+
+import (
+	"datahub-service/service"
+	"datahub-service/service/basic_library"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+)
+
+// ProvenanceController 接口记录级来源追溯控制器
+type ProvenanceController struct {
+}
+
+// NewProvenanceController 创建接口记录级来源追溯控制器实例
+func NewProvenanceController() *ProvenanceController {
+	return &ProvenanceController{}
+}
+
+// GetProvenance 获取接口来源追溯配置
+// @Summary 获取接口来源追溯配置
+// @Description 获取接口是否开启记录级来源追溯
+// @Tags 数据基础库
+// @Produce json
+// @Param id path string true "接口ID"
+// @Success 200 {object} APIResponse[models.ProvenanceConfig] "获取成功"
+// @Failure 404 {object} APIResponse[any] "接口不存在"
+// @Router /basic-libraries/interfaces/{id}/provenance [get]
+func (c *ProvenanceController) GetProvenance(w http.ResponseWriter, r *http.Request) {
+	config, err := service.GlobalProvenanceService.GetConfig(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		render.JSON(w, r, MapErrorResponse("获取来源追溯配置失败", err))
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("获取来源追溯配置成功", config))
+}
+
+// EnableProvenance 开启接口来源追溯
+// @Summary 开启接口来源追溯
+// @Description 为接口表增加_source_id(来源数据源)、_sync_execution_id(同步任务执行ID)、_ingested_at(入库时间)三个系统列，之后同步写入的每一行都自动填写，数据查看可按这些列筛选。开启前已有的行在下次同步写入后填写
+// @Tags 数据基础库
+// @Produce json
+// @Param id path string true "接口ID"
+// @Param If-Match header string false "期望版本（ETag），不一致时返回409"
+// @Success 200 {object} APIResponse[models.ProvenanceConfig] "开启成功"
+// @Failure 400 {object} APIResponse[any] "接口表尚未创建"
+// @Failure 404 {object} APIResponse[any] "接口不存在"
+// @Failure 409 {object} APIResponse[VersionConflictData] "版本冲突"
+// @Router /basic-libraries/interfaces/{id}/provenance [put]
+func (c *ProvenanceController) EnableProvenance(w http.ResponseWriter, r *http.Request) {
+	expectedVersion, err := expectedRowVersion(r, 0)
+	if err != nil {
+		render.JSON(w, r, BadRequestResponse(err.Error(), err))
+		return
+	}
+
+	config, err := service.GlobalProvenanceService.EnableConfig(r.Context(), chi.URLParam(r, "id"), expectedVersion, getCurrentUsername(r))
+	if err != nil {
+		respondProvenanceError(w, r, "开启来源追溯失败", err)
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("开启来源追溯成功", config))
+}
+
+// DisableProvenance 关闭接口来源追溯
+// @Summary 关闭接口来源追溯
+// @Description 关闭后同步不再填写来源信息，并删除接口表的三个系统列
+// @Tags 数据基础库
+// @Produce json
+// @Param id path string true "接口ID"
+// @Param If-Match header string false "期望版本（ETag），不一致时返回409"
+// @Success 200 {object} APIResponse[any] "关闭成功"
+// @Failure 400 {object} APIResponse[any] "接口未开启来源追溯"
+// @Failure 404 {object} APIResponse[any] "接口不存在"
+// @Failure 409 {object} APIResponse[VersionConflictData] "版本冲突"
+// @Router /basic-libraries/interfaces/{id}/provenance [delete]
+func (c *ProvenanceController) DisableProvenance(w http.ResponseWriter, r *http.Request) {
+	expectedVersion, err := expectedRowVersion(r, 0)
+	if err != nil {
+		render.JSON(w, r, BadRequestResponse(err.Error(), err))
+		return
+	}
+
+	if err := service.GlobalProvenanceService.DisableConfig(r.Context(), chi.URLParam(r, "id"), expectedVersion, getCurrentUsername(r)); err != nil {
+		respondProvenanceError(w, r, "关闭来源追溯失败", err)
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("关闭来源追溯成功", nil))
+}
+
+// respondProvenanceError 版本冲突时返回409，配置不合法时返回400，其余按错误类型映射
+func respondProvenanceError(w http.ResponseWriter, r *http.Request, msg string, err error) {
+	if handleVersionConflict(w, r, err) {
+		return
+	}
+	if errors.Is(err, basic_library.ErrInvalidProvenanceRequest) {
+		render.JSON(w, r, BadRequestResponse(msg, err))
+		return
+	}
+	render.JSON(w, r, MapErrorResponse(msg, err))
+}
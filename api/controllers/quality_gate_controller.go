@@ -0,0 +1,148 @@
+/*
+ * @module api/controllers/quality_gate_controller
+ * @description 同步任务质量门禁控制器，提供门禁配置的查询、设置、关闭，以及被阻断接口的查询和人工解除
+ * @architecture 分层架构 - 控制器层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow HTTP请求 -> 控制器 -> 质量门禁服务 -> 保存同步任务配置或更新阻断记录
+ * @rules 统一的错误处理和响应格式；配置不合法时返回400；操作人取自当前登录用户
+ * @dependencies datahub-service/service, datahub-service/service/basic_library, github.com/go-chi/chi/v5
+ * @refs service/basic_library/quality_gate_service.go, service/models/quality_gate.go
+ */
+
+package controllers
+
+import (
+	"datahub-service/service"
+	"datahub-service/service/basic_library"
+	"datahub-service/service/models"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+)
+
+// QualityGateController 同步任务质量门禁控制器
+type QualityGateController struct {
+}
+
+// NewQualityGateController 创建同步任务质量门禁控制器实例
+func NewQualityGateController() *QualityGateController {
+	return &QualityGateController{}
+}
+
+// UpdateQualityGateRequest 设置质量门禁请求
+type UpdateQualityGateRequest struct {
+	MinScore      *float64 `json:"min_score" validate:"omitempty,min=0,max=100" example:"90"` // 综合评分下限(0-100)，为空时不检查评分
+	RequiredRules []string `json:"required_rules"`                                            // 必须全部通过的质量检测字段规则ID
+}
+
+// GetQualityGate 获取同步任务质量门禁
+// @Summary 获取同步任务质量门禁
+// @Description 获取同步任务写入后的质量门禁配置，未开启时enabled为false
+// @Tags 基础库同步任务
+// @Produce json
+// @Param id path string true "同步任务ID"
+// @Success 200 {object} APIResponse[models.QualityGateConfig] "获取成功"
+// @Failure 404 {object} APIResponse[any] "同步任务不存在"
+// @Router /sync/tasks/{id}/quality-gate [get]
+func (c *QualityGateController) GetQualityGate(w http.ResponseWriter, r *http.Request) {
+	gate, err := service.GlobalQualityGateService.GetConfig(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		render.JSON(w, r, MapErrorResponse("获取质量门禁失败", err))
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("获取质量门禁成功", gate))
+}
+
+// UpdateQualityGate 开启或修改同步任务质量门禁
+// @Summary 开启或修改同步任务质量门禁
+// @Description 同步写入后执行写入接口上启用的质量检测任务，综合评分低于min_score或required_rules中的规则有不通过记录时，执行记为失败并发送quality_gate_failed告警，写入的接口被阻断，读取这些接口的主题同步任务跳过执行，直至门禁再次通过或人工解除
+// @Tags 基础库同步任务
+// @Accept json
+// @Produce json
+// @Param id path string true "同步任务ID"
+// @Param request body UpdateQualityGateRequest true "质量门禁配置"
+// @Success 200 {object} APIResponse[models.QualityGateConfig] "设置成功"
+// @Failure 400 {object} APIResponse[any] "配置不合法"
+// @Failure 404 {object} APIResponse[any] "同步任务不存在"
+// @Router /sync/tasks/{id}/quality-gate [put]
+func (c *QualityGateController) UpdateQualityGate(w http.ResponseWriter, r *http.Request) {
+	var req UpdateQualityGateRequest
+	if !decodeAndValidate(w, r, &req) {
+		return
+	}
+
+	gate := &models.QualityGateConfig{MinScore: req.MinScore, RequiredRules: req.RequiredRules}
+	result, err := service.GlobalQualityGateService.UpdateConfig(r.Context(), chi.URLParam(r, "id"), gate, getCurrentUsername(r))
+	if err != nil {
+		respondQualityGateError(w, r, "设置质量门禁失败", err)
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("设置质量门禁成功", result))
+}
+
+// DisableQualityGate 关闭同步任务质量门禁
+// @Summary 关闭同步任务质量门禁
+// @Description 关闭后同步写入不再检查质量，该任务造成的接口阻断一并解除
+// @Tags 基础库同步任务
+// @Produce json
+// @Param id path string true "同步任务ID"
+// @Success 200 {object} APIResponse[any] "关闭成功"
+// @Failure 400 {object} APIResponse[any] "任务未开启质量门禁"
+// @Failure 404 {object} APIResponse[any] "同步任务不存在"
+// @Router /sync/tasks/{id}/quality-gate [delete]
+func (c *QualityGateController) DisableQualityGate(w http.ResponseWriter, r *http.Request) {
+	if err := service.GlobalQualityGateService.DisableConfig(r.Context(), chi.URLParam(r, "id"), getCurrentUsername(r)); err != nil {
+		respondQualityGateError(w, r, "关闭质量门禁失败", err)
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("关闭质量门禁成功", nil))
+}
+
+// GetQualityGateBlocks 获取被质量门禁阻断的接口
+// @Summary 获取被质量门禁阻断的接口
+// @Description 按阻断时间倒序返回未通过质量门禁的接口，以及造成阻断的同步任务、执行、评分和原因
+// @Tags 基础库同步任务
+// @Produce json
+// @Success 200 {object} APIResponse[[]models.QualityGateBlock] "获取成功"
+// @Router /sync/quality-gate-blocks [get]
+func (c *QualityGateController) GetQualityGateBlocks(w http.ResponseWriter, r *http.Request) {
+	blocks, err := service.GlobalQualityGateService.ListBlocks(r.Context())
+	if err != nil {
+		render.JSON(w, r, MapErrorResponse("获取质量门禁阻断失败", err))
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("获取质量门禁阻断成功", blocks))
+}
+
+// ReleaseQualityGateBlock 人工解除接口阻断
+// @Summary 人工解除接口阻断
+// @Description 确认数据可用后解除接口阻断，下游主题同步任务恢复执行，原执行结果不变
+// @Tags 基础库同步任务
+// @Produce json
+// @Param interface_id path string true "接口ID"
+// @Success 200 {object} APIResponse[any] "解除成功"
+// @Failure 404 {object} APIResponse[any] "接口未被阻断"
+// @Router /sync/quality-gate-blocks/{interface_id} [delete]
+func (c *QualityGateController) ReleaseQualityGateBlock(w http.ResponseWriter, r *http.Request) {
+	if err := service.GlobalQualityGateService.ReleaseBlock(r.Context(), chi.URLParam(r, "interface_id"), getCurrentUsername(r)); err != nil {
+		render.JSON(w, r, MapErrorResponse("解除质量门禁阻断失败", err))
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("解除质量门禁阻断成功", nil))
+}
+
+// respondQualityGateError 配置不合法时返回400，其余按错误类型映射
+func respondQualityGateError(w http.ResponseWriter, r *http.Request, msg string, err error) {
+	if errors.Is(err, basic_library.ErrInvalidQualityGate) {
+		render.JSON(w, r, BadRequestResponse(msg, err))
+		return
+	}
+	render.JSON(w, r, MapErrorResponse(msg, err))
+}
@@ -0,0 +1,113 @@
+/*
+ * @module api/controllers/query_insight_controller
+ * @description 查询性能分析控制器，提供共享接口表的扫描统计、慢语句和索引建议查询，以及一键创建建议索引的接口
+ * @architecture 分层架构 - 控制器层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow HTTP请求 -> 控制器 -> 查询性能分析服务 -> PostgreSQL系统视图/建索引
+ * @rules 统一的错误处理和响应格式；只分析启用的共享接口使用的主题接口表；索引字段必须是表中存在的字段且未被已有索引覆盖
+ * @dependencies datahub-service/service, datahub-service/service/query_insight, github.com/go-chi/chi/v5
+ * @refs service/query_insight/analyzer.go
+ */
+
+package controllers
+
+import (
+	"datahub-service/service"
+	"datahub-service/service/query_insight"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+)
+
+// QueryInsightController 查询性能分析控制器
+type QueryInsightController struct {
+}
+
+// NewQueryInsightController 创建查询性能分析控制器实例
+func NewQueryInsightController() *QueryInsightController {
+	return &QueryInsightController{}
+}
+
+// CreateIndexRequest 创建索引请求
+type CreateIndexRequest struct {
+	Columns []string `json:"columns" validate:"required,min=1,max=3" example:"status,updated_at"` // 索引字段，等值过滤字段在前
+}
+
+// GetTableInsights 获取共享接口表的查询性能分析
+// @Summary 获取共享接口表的查询性能分析
+// @Description 分析启用的共享接口使用的主题接口表的扫描统计、慢语句和索引建议，有索引建议的表按涉及耗时倒序排在前面
+// @Tags 查询性能分析
+// @Produce json
+// @Success 200 {object} APIResponse[[]query_insight.TableInsight] "获取成功"
+// @Router /query-insights/tables [get]
+func (c *QueryInsightController) GetTableInsights(w http.ResponseWriter, r *http.Request) {
+	insights, err := service.GlobalQueryInsightService.ListInsights(r.Context())
+	if err != nil {
+		render.JSON(w, r, MapErrorResponse("获取查询性能分析失败", err))
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("获取查询性能分析成功", insights))
+}
+
+// GetTableInsight 获取主题接口表的查询性能分析
+// @Summary 获取主题接口表的查询性能分析
+// @Description 分析指定主题接口表的扫描统计、已有索引、慢语句和索引建议
+// @Tags 查询性能分析
+// @Produce json
+// @Param id path string true "主题接口ID"
+// @Success 200 {object} APIResponse[query_insight.TableInsight] "获取成功"
+// @Failure 404 {object} APIResponse[any] "不是共享接口使用的主题接口表或表不存在"
+// @Router /query-insights/tables/{id} [get]
+func (c *QueryInsightController) GetTableInsight(w http.ResponseWriter, r *http.Request) {
+	insight, err := service.GlobalQueryInsightService.GetInsight(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		if errors.Is(err, query_insight.ErrTableNotShared) {
+			render.JSON(w, r, NotFoundResponse("获取查询性能分析失败", err))
+			return
+		}
+		render.JSON(w, r, MapErrorResponse("获取查询性能分析失败", err))
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("获取查询性能分析成功", insight))
+}
+
+// CreateRecommendedIndex 创建建议索引
+// @Summary 创建建议索引
+// @Description 在共享接口使用的主题接口表上以CREATE INDEX CONCURRENTLY创建索引，不阻塞共享接口读写
+// @Tags 查询性能分析
+// @Accept json
+// @Produce json
+// @Param id path string true "主题接口ID"
+// @Param request body CreateIndexRequest true "索引字段"
+// @Success 200 {object} APIResponse[query_insight.CreatedIndex] "创建成功"
+// @Failure 400 {object} APIResponse[any] "索引字段无效"
+// @Failure 404 {object} APIResponse[any] "不是共享接口使用的主题接口表"
+// @Failure 409 {object} APIResponse[any] "已有索引覆盖这些字段"
+// @Router /query-insights/tables/{id}/indexes [post]
+func (c *QueryInsightController) CreateRecommendedIndex(w http.ResponseWriter, r *http.Request) {
+	var req CreateIndexRequest
+	if !decodeAndValidate(w, r, &req) {
+		return
+	}
+
+	created, err := service.GlobalQueryInsightService.CreateIndex(r.Context(), chi.URLParam(r, "id"), req.Columns)
+	if err != nil {
+		switch {
+		case errors.Is(err, query_insight.ErrTableNotShared):
+			render.JSON(w, r, NotFoundResponse("创建索引失败", err))
+		case errors.Is(err, query_insight.ErrInvalidColumns):
+			render.JSON(w, r, BadRequestResponse("创建索引失败", err))
+		case errors.Is(err, query_insight.ErrIndexCovered):
+			render.JSON(w, r, ConflictResponse("创建索引失败", err))
+		default:
+			render.JSON(w, r, MapErrorResponse("创建索引失败", err))
+		}
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("创建索引成功", created))
+}
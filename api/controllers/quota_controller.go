@@ -0,0 +1,135 @@
+/*
+ * @module api/controllers/quota_controller
+ * @description 配额控制器，为基础库、主题库或租户设置数据行数、存储空间和同步任务数上限，并查看各配额的当前用量
+ * @architecture 分层架构 - 控制器层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow HTTP请求 -> 控制器 -> 配额服务 -> 数据库
+ * @rules 统一的错误处理和响应格式；配额范围为library或tenant；上限为0表示不限制；超出配额的创建任务和执行同步请求返回403，错误码QUOTA_EXCEEDED
+ * @dependencies datahub-service/service, datahub-service/service/capacity, github.com/go-chi/chi/v5
+ * @refs service/capacity/quota.go, api/controllers/capacity_controller.go
+ */
+
+package controllers
+
+import (
+	"datahub-service/service"
+	"datahub-service/service/capacity"
+	"datahub-service/service/models"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+)
+
+// QuotaController 配额控制器
+type QuotaController struct {
+}
+
+// NewQuotaController 创建配额控制器实例
+func NewQuotaController() *QuotaController {
+	return &QuotaController{}
+}
+
+// SetQuotaRequest 设置配额请求
+type SetQuotaRequest struct {
+	MaxRows         int64  `json:"max_rows" validate:"min=0" example:"100000000"`                       // 数据行数上限，0表示不限制
+	MaxStorageBytes int64  `json:"max_storage_bytes" validate:"min=0" example:"107374182400"`           // 存储空间上限（字节），0表示不限制
+	MaxTasks        int64  `json:"max_tasks" validate:"min=0" example:"50"`                             // 同步任务数上限，0表示不限制
+	Enforcement     string `json:"enforcement" validate:"omitempty,oneof=reject warn" example:"reject"` // 超出时的处理方式，默认reject
+}
+
+// ListQuotaUsage 获取配额用量
+// @Summary 获取配额用量
+// @Description 列出全部库和租户配额及当前用量，已达到上限的排在前面。行数和存储用量取最近一次存储快照
+// @Tags 存储容量
+// @Produce json
+// @Success 200 {object} APIResponse[[]capacity.QuotaUsage] "获取成功"
+// @Router /capacity/quotas [get]
+func (c *QuotaController) ListQuotaUsage(w http.ResponseWriter, r *http.Request) {
+	usage, err := service.GlobalQuotaService.ListUsage(r.Context())
+	if err != nil {
+		render.JSON(w, r, MapErrorResponse("获取配额用量失败", err))
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("获取配额用量成功", usage))
+}
+
+// GetQuotaUsage 获取库或租户的配额用量
+// @Summary 获取库或租户的配额用量
+// @Description 获取单个库或租户的配额和当前的行数、存储空间、同步任务数
+// @Tags 存储容量
+// @Produce json
+// @Param scope_type path string true "配额范围" Enums(library, tenant)
+// @Param scope_id path string true "库ID或租户ID"
+// @Success 200 {object} APIResponse[capacity.QuotaUsage] "获取成功"
+// @Failure 404 {object} APIResponse[any] "配额不存在"
+// @Router /capacity/quotas/{scope_type}/{scope_id} [get]
+func (c *QuotaController) GetQuotaUsage(w http.ResponseWriter, r *http.Request) {
+	usage, err := service.GlobalQuotaService.GetUsage(r.Context(), chi.URLParam(r, "scope_type"), chi.URLParam(r, "scope_id"))
+	if err != nil {
+		render.JSON(w, r, MapErrorResponse("获取配额用量失败", err))
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("获取配额用量成功", usage))
+}
+
+// SetQuota 设置库或租户的配额
+// @Summary 设置库或租户的配额
+// @Description 设置基础库、主题库或租户的数据行数、存储空间和同步任务数上限，已存在时覆盖。创建同步任务时检查任务数，执行同步前检查行数和存储，同时检查库配额和所属租户配额；用量达到上限时记录quota_exceeded事件，enforcement为reject时拒绝并返回403，为warn时只告警
+// @Tags 存储容量
+// @Accept json
+// @Produce json
+// @Param scope_type path string true "配额范围" Enums(library, tenant)
+// @Param scope_id path string true "库ID或租户ID"
+// @Param request body SetQuotaRequest true "配额"
+// @Success 200 {object} APIResponse[models.LibraryQuota] "设置成功"
+// @Failure 400 {object} APIResponse[any] "请求参数错误"
+// @Failure 404 {object} APIResponse[any] "库或租户不存在"
+// @Router /capacity/quotas/{scope_type}/{scope_id} [put]
+func (c *QuotaController) SetQuota(w http.ResponseWriter, r *http.Request) {
+	var req SetQuotaRequest
+	if !decodeAndValidate(w, r, &req) {
+		return
+	}
+
+	quota, err := service.GlobalQuotaService.SetQuota(r.Context(), &models.LibraryQuota{
+		ScopeType:       chi.URLParam(r, "scope_type"),
+		ScopeID:         chi.URLParam(r, "scope_id"),
+		MaxRows:         req.MaxRows,
+		MaxStorageBytes: req.MaxStorageBytes,
+		MaxTasks:        req.MaxTasks,
+		Enforcement:     req.Enforcement,
+	}, getCurrentUsername(r))
+	if err != nil {
+		if errors.Is(err, capacity.ErrInvalidQuota) {
+			render.JSON(w, r, BadRequestResponse("设置配额失败", err))
+			return
+		}
+		render.JSON(w, r, MapErrorResponse("设置配额失败", err))
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("设置配额成功", quota))
+}
+
+// DeleteQuota 删除库或租户的配额
+// @Summary 删除库或租户的配额
+// @Description 删除配额后不再限制该库或租户
+// @Tags 存储容量
+// @Produce json
+// @Param scope_type path string true "配额范围" Enums(library, tenant)
+// @Param scope_id path string true "库ID或租户ID"
+// @Success 200 {object} APIResponse[any] "删除成功"
+// @Failure 404 {object} APIResponse[any] "配额不存在"
+// @Router /capacity/quotas/{scope_type}/{scope_id} [delete]
+func (c *QuotaController) DeleteQuota(w http.ResponseWriter, r *http.Request) {
+	if err := service.GlobalQuotaService.DeleteQuota(r.Context(), chi.URLParam(r, "scope_type"), chi.URLParam(r, "scope_id")); err != nil {
+		render.JSON(w, r, MapErrorResponse("删除配额失败", err))
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("删除配额成功", nil))
+}
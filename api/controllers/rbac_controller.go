@@ -0,0 +1,344 @@
+/*
+ * @module api/controllers/rbac_controller
+ * @description 访问控制控制器，提供角色权限查询配置、用户角色分配、目录对象授权和当前用户可执行操作查询接口
+ * @architecture 分层架构 - 控制器层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow HTTP请求 -> 控制器 -> 访问控制服务 -> 数据库
+ * @rules 统一的错误处理和响应格式
+ * @dependencies datahub-service/service, github.com/go-chi/chi/v5
+ * @refs service/rbac, api/middleware/rbac.go
+ */
+
+package controllers
+
+import (
+	"datahub-service/api/middleware"
+	"datahub-service/service"
+	"datahub-service/service/identity"
+	"datahub-service/service/models"
+	"datahub-service/service/rbac"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"gorm.io/gorm"
+)
+
+// RBACController 访问控制控制器
+type RBACController struct {
+}
+
+// NewRBACController 创建访问控制控制器实例
+func NewRBACController() *RBACController {
+	return &RBACController{}
+}
+
+// AssignRoleRequest 分配角色请求结构
+type AssignRoleRequest struct {
+	Username    string `json:"username" validate:"required"`
+	Role        string `json:"role" validate:"required" example:"steward"`
+	Description string `json:"description"`
+}
+
+// UpdateRolePermissionsRequest 更新角色权限请求结构
+type UpdateRolePermissionsRequest struct {
+	Permissions []rbac.PermissionItem `json:"permissions" validate:"required"`
+}
+
+// CreateResourceGrantRequest 创建对象授权请求结构
+type CreateResourceGrantRequest struct {
+	Resource    string `json:"resource" validate:"required" example:"basic_library"` // basic_library/thematic_library/quality_rule/masking_rule/cleansing_rule
+	ObjectID    string `json:"object_id" validate:"required"`
+	GranteeType string `json:"grantee_type" validate:"required" example:"user"` // user/role
+	Grantee     string `json:"grantee" validate:"required" example:"zhangsan"`
+	Description string `json:"description"`
+}
+
+// ResourceGrantListResponse 对象授权列表响应结构
+type ResourceGrantListResponse struct {
+	List []models.ResourceGrant `json:"list"`
+	models.PageMeta
+}
+
+// RoleAssignmentListResponse 角色分配列表响应结构
+type RoleAssignmentListResponse struct {
+	List []models.UserRoleAssignment `json:"list"`
+	models.PageMeta
+}
+
+// getCurrentUsername 获取当前请求的认证用户名，未认证时返回system；审计字段一律以此为准，不采信请求体
+func getCurrentUsername(r *http.Request) string {
+	return identity.Operator(r.Context())
+}
+
+// GetRoles 获取角色列表
+// @Summary 获取角色列表
+// @Description 获取所有内置角色及其权限配置
+// @Tags 访问控制
+// @Produce json
+// @Success 200 {object} APIResponse[[]rbac.RoleInfo] "获取成功"
+// @Router /rbac/roles [get]
+func (c *RBACController) GetRoles(w http.ResponseWriter, r *http.Request) {
+	render.JSON(w, r, SuccessResponse("获取角色列表成功", service.GlobalRBACService.GetRoles()))
+}
+
+// UpdateRolePermissions 更新角色权限
+// @Summary 更新角色权限
+// @Description 替换指定角色的全部权限，权限以资源和操作表示，支持通配符*
+// @Tags 访问控制
+// @Accept json
+// @Produce json
+// @Param role path string true "角色名"
+// @Param request body UpdateRolePermissionsRequest true "权限列表"
+// @Success 200 {object} APIResponse[any] "更新成功"
+// @Failure 400 {object} APIResponse[any] "请求参数错误"
+// @Router /rbac/roles/{role}/permissions [put]
+func (c *RBACController) UpdateRolePermissions(w http.ResponseWriter, r *http.Request) {
+	role := chi.URLParam(r, "role")
+
+	var req UpdateRolePermissionsRequest
+	if !decodeAndValidate(w, r, &req) {
+		return
+	}
+
+	if !rbac.IsValidRole(role) {
+		render.JSON(w, r, NotFoundResponse("角色不存在", nil))
+		return
+	}
+
+	if err := service.GlobalRBACService.SetRolePermissions(role, req.Permissions, getCurrentUsername(r)); err != nil {
+		render.JSON(w, r, BadRequestResponse("更新角色权限失败", err))
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("更新角色权限成功", nil))
+}
+
+// GetRoleAssignments 获取用户角色分配列表
+// @Summary 获取用户角色分配列表
+// @Description 分页获取用户角色分配记录
+// @Tags 访问控制
+// @Produce json
+// @Param page query int false "页码" default(1)
+// @Param size query int false "每页数量" default(10)
+// @Param username query string false "用户名"
+// @Param role query string false "角色"
+// @Success 200 {object} APIResponse[RoleAssignmentListResponse] "获取成功"
+// @Router /rbac/assignments [get]
+func (c *RBACController) GetRoleAssignments(w http.ResponseWriter, r *http.Request) {
+	page, size := parsePagination(r)
+
+	assignments, total, err := service.GlobalRBACService.GetRoleAssignments(page, size, r.URL.Query().Get("username"), r.URL.Query().Get("role"))
+	if err != nil {
+		render.JSON(w, r, MapErrorResponse("获取角色分配列表失败", err))
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("获取角色分配列表成功", RoleAssignmentListResponse{
+		List:     assignments,
+		PageMeta: models.NewPageMeta(total, page, size),
+	}))
+}
+
+// AssignRole 为用户分配角色
+// @Summary 为用户分配角色
+// @Description 为指定用户分配内置角色（admin/steward/developer/consumer）
+// @Tags 访问控制
+// @Accept json
+// @Produce json
+// @Param request body AssignRoleRequest true "角色分配信息"
+// @Success 200 {object} APIResponse[models.UserRoleAssignment] "分配成功"
+// @Failure 400 {object} APIResponse[any] "请求参数错误"
+// @Router /rbac/assignments [post]
+func (c *RBACController) AssignRole(w http.ResponseWriter, r *http.Request) {
+	var req AssignRoleRequest
+	if !decodeAndValidate(w, r, &req) {
+		return
+	}
+
+	assignment, err := service.GlobalRBACService.AssignRole(req.Username, req.Role, req.Description, getCurrentUsername(r))
+	if err != nil {
+		render.JSON(w, r, BadRequestResponse("分配角色失败", err))
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("分配角色成功", assignment))
+}
+
+// RevokeRoleAssignment 撤销用户角色分配
+// @Summary 撤销用户角色分配
+// @Description 根据分配记录ID撤销用户角色
+// @Tags 访问控制
+// @Produce json
+// @Param id path string true "分配记录ID"
+// @Success 200 {object} APIResponse[any] "撤销成功"
+// @Failure 404 {object} APIResponse[any] "分配记录不存在"
+// @Router /rbac/assignments/{id} [delete]
+func (c *RBACController) RevokeRoleAssignment(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	if err := service.GlobalRBACService.RevokeRoleAssignment(id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			render.JSON(w, r, NotFoundResponse("角色分配记录不存在", err))
+			return
+		}
+		render.JSON(w, r, MapErrorResponse("撤销角色失败", err))
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("撤销角色成功", nil))
+}
+
+// GetMyAccess 获取当前用户的有效角色和权限
+// @Summary 获取当前用户权限
+// @Description 获取当前用户合并JWT角色和已分配角色后的有效角色与权限
+// @Tags 访问控制
+// @Produce json
+// @Success 200 {object} APIResponse[rbac.EffectiveAccess] "获取成功"
+// @Failure 401 {object} APIResponse[any] "未认证"
+// @Router /rbac/me [get]
+func (c *RBACController) GetMyAccess(w http.ResponseWriter, r *http.Request) {
+	userInfo, ok := middleware.GetUserInfoFromContext(r.Context())
+	if !ok {
+		render.JSON(w, r, ErrorResponse(StatusUnauthorized, "未找到用户信息", nil))
+		return
+	}
+
+	access := service.GlobalRBACService.GetEffectiveAccess(userInfo.Username, userInfo.Roles)
+	render.JSON(w, r, SuccessResponse("获取当前用户权限成功", access))
+}
+
+// GetMyPermissions 获取当前用户可执行的操作
+// @Summary 获取当前用户可执行的操作
+// @Description 按资源返回当前用户可执行的操作（read/write/delete/execute），以及授予该用户及其角色的目录对象，前端据此隐藏无权操作；unrestricted_scope为true时可查看全部目录对象
+// @Tags 访问控制
+// @Produce json
+// @Success 200 {object} APIResponse[rbac.MyPermissions] "获取成功"
+// @Failure 401 {object} APIResponse[any] "未认证"
+// @Router /me/permissions [get]
+func (c *RBACController) GetMyPermissions(w http.ResponseWriter, r *http.Request) {
+	userInfo, ok := middleware.GetUserInfoFromContext(r.Context())
+	if !ok {
+		render.JSON(w, r, ErrorResponse(StatusUnauthorized, "未找到用户信息", nil))
+		return
+	}
+
+	permissions, err := service.GlobalRBACService.GetMyPermissions(r.Context(), userInfo.Username, userInfo.Roles, userInfo.Permissions)
+	if err != nil {
+		render.JSON(w, r, MapErrorResponse("获取当前用户可执行操作失败", err))
+		return
+	}
+	render.JSON(w, r, SuccessResponse("获取当前用户可执行操作成功", permissions))
+}
+
+// GetResourceGrants 获取对象授权列表
+// @Summary 获取对象授权列表
+// @Description 分页获取目录对象授权，对象有授权记录时只对被授权的用户和角色可见
+// @Tags 访问控制
+// @Produce json
+// @Param page query int false "页码" default(1)
+// @Param size query int false "每页数量" default(10)
+// @Param resource query string false "资源" Enums(basic_library,thematic_library,quality_rule,masking_rule,cleansing_rule)
+// @Param object_id query string false "对象ID"
+// @Param grantee query string false "被授权的用户或角色"
+// @Success 200 {object} APIResponse[ResourceGrantListResponse] "获取成功"
+// @Router /rbac/grants [get]
+func (c *RBACController) GetResourceGrants(w http.ResponseWriter, r *http.Request) {
+	page, size := parsePagination(r)
+	query := r.URL.Query()
+
+	grants, total, err := service.GlobalRBACService.ListGrants(r.Context(), rbac.GrantQuery{
+		Resource: query.Get("resource"),
+		ObjectID: query.Get("object_id"),
+		Grantee:  query.Get("grantee"),
+		Page:     page,
+		PageSize: size,
+	})
+	if err != nil {
+		render.JSON(w, r, MapErrorResponse("获取对象授权列表失败", err))
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("获取对象授权列表成功", ResourceGrantListResponse{
+		List:     grants,
+		PageMeta: models.NewPageMeta(total, page, size),
+	}))
+}
+
+// CreateResourceGrant 创建对象授权
+// @Summary 创建对象授权
+// @Description 授予用户或角色查看指定的基础库、主题库或规则模板；对象的第一条授权创建后，未被授权的用户在列表中看不到该对象，详情返回404；库的授权同时作用于库下的接口
+// @Tags 访问控制
+// @Accept json
+// @Produce json
+// @Param request body CreateResourceGrantRequest true "授权信息"
+// @Success 200 {object} APIResponse[models.ResourceGrant] "授权成功"
+// @Failure 400 {object} APIResponse[any] "请求参数错误"
+// @Failure 404 {object} APIResponse[any] "对象不存在"
+// @Failure 409 {object} APIResponse[any] "已授权"
+// @Router /rbac/grants [post]
+func (c *RBACController) CreateResourceGrant(w http.ResponseWriter, r *http.Request) {
+	var req CreateResourceGrantRequest
+	if !decodeAndValidate(w, r, &req) {
+		return
+	}
+
+	grant := &models.ResourceGrant{
+		Resource:    req.Resource,
+		ObjectID:    req.ObjectID,
+		GranteeType: req.GranteeType,
+		Grantee:     req.Grantee,
+		Description: req.Description,
+		CreatedBy:   getCurrentUsername(r),
+	}
+	if err := service.GlobalRBACService.CreateGrant(r.Context(), grant); err != nil {
+		render.JSON(w, r, grantErrorResponse("创建对象授权失败", err))
+		return
+	}
+	render.JSON(w, r, SuccessResponse("创建对象授权成功", grant))
+}
+
+// DeleteResourceGrant 撤销对象授权
+// @Summary 撤销对象授权
+// @Description 撤销对象授权，对象的最后一条授权撤销后恢复为所有有资源读取权限的用户可见
+// @Tags 访问控制
+// @Produce json
+// @Param id path string true "授权ID"
+// @Success 200 {object} APIResponse[any] "撤销成功"
+// @Failure 404 {object} APIResponse[any] "授权不存在"
+// @Router /rbac/grants/{id} [delete]
+func (c *RBACController) DeleteResourceGrant(w http.ResponseWriter, r *http.Request) {
+	if err := service.GlobalRBACService.DeleteGrant(r.Context(), chi.URLParam(r, "id")); err != nil {
+		render.JSON(w, r, grantErrorResponse("撤销对象授权失败", err))
+		return
+	}
+	render.JSON(w, r, SuccessResponse("撤销对象授权成功", nil))
+}
+
+// grantErrorResponse 对象授权错误响应
+func grantErrorResponse(msg string, err error) render.Renderer {
+	switch {
+	case errors.Is(err, rbac.ErrInvalidGrant):
+		return BadRequestResponse(err.Error(), err)
+	case errors.Is(err, rbac.ErrGrantExists):
+		return ConflictResponse(err.Error(), err)
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return NotFoundResponse("授权对象或授权记录不存在", err)
+	}
+	return MapErrorResponse(msg, err)
+}
+
+// visibleOrNotFound 校验目录对象对当前用户可见，不可见时按不存在返回404，不暴露受限对象
+func visibleOrNotFound(w http.ResponseWriter, r *http.Request, resource, objectID, notFoundMsg string) bool {
+	visible, err := service.GlobalRBACService.IsVisible(r.Context(), resource, objectID)
+	if err != nil {
+		render.JSON(w, r, InternalErrorResponse("校验对象授权失败", err))
+		return false
+	}
+	if !visible {
+		render.JSON(w, r, NotFoundResponse(notFoundMsg, nil))
+		return false
+	}
+	return true
+}
@@ -0,0 +1,133 @@
+/*
+ * @module api/controllers/reconciliation_controller
+ * @description 元数据一致性核对控制器，提供立即核对、核对报告查询和问题修复的接口
+ * @architecture 分层架构 - 控制器层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow HTTP请求 -> 控制器 -> 元数据一致性核对服务 -> 数据库
+ * @rules 统一的错误处理和响应格式；核对正在执行时返回409；修复请求体可省略，省略时修复报告中所有待修复的问题
+ * @dependencies datahub-service/service, datahub-service/service/reconcile, github.com/go-chi/chi/v5
+ * @refs service/reconcile/service.go, service/reconcile/fix.go
+ */
+
+package controllers
+
+import (
+	"datahub-service/service"
+	"datahub-service/service/models"
+	"datahub-service/service/reconcile"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+)
+
+// ReconciliationController 元数据一致性核对控制器
+type ReconciliationController struct {
+}
+
+// NewReconciliationController 创建元数据一致性核对控制器实例
+func NewReconciliationController() *ReconciliationController {
+	return &ReconciliationController{}
+}
+
+// ReconciliationReportListResponse 核对报告列表响应结构
+type ReconciliationReportListResponse struct {
+	List []models.ReconciliationReport `json:"list"`
+	models.PageMeta
+}
+
+// GetReconciliationReports 获取核对报告列表
+// @Summary 获取核对报告列表
+// @Description 分页获取元数据一致性核对报告，按核对时间倒序，不包含问题项
+// @Tags 元数据核对
+// @Produce json
+// @Param page query int false "页码" default(1)
+// @Param size query int false "每页数量" default(10)
+// @Success 200 {object} APIResponse[ReconciliationReportListResponse] "获取成功"
+// @Router /reconciliation/reports [get]
+func (c *ReconciliationController) GetReconciliationReports(w http.ResponseWriter, r *http.Request) {
+	page, size := parsePagination(r)
+	reports, total, err := service.GlobalReconcileService.GetReports(r.Context(), page, size)
+	if err != nil {
+		render.JSON(w, r, MapErrorResponse("获取核对报告列表失败", err))
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("获取核对报告列表成功", ReconciliationReportListResponse{
+		List:     reports,
+		PageMeta: models.NewPageMeta(total, page, size),
+	}))
+}
+
+// RunReconciliation 立即执行核对
+// @Summary 立即执行核对
+// @Description 核对接口的物理表、血缘关系和脱敏配置与目录是否一致，生成核对报告；核对只记录问题，不修改目录
+// @Tags 元数据核对
+// @Produce json
+// @Success 200 {object} APIResponse[models.ReconciliationReport] "核对完成"
+// @Failure 409 {object} APIResponse[any] "核对正在执行"
+// @Router /reconciliation/reports [post]
+func (c *ReconciliationController) RunReconciliation(w http.ResponseWriter, r *http.Request) {
+	report, err := service.GlobalReconcileService.Run(r.Context(), models.ReconcileTriggerManual, getCurrentUsername(r))
+	if err != nil {
+		if errors.Is(err, reconcile.ErrReconcileRunning) {
+			render.JSON(w, r, ConflictResponse("执行元数据核对失败", err))
+			return
+		}
+		render.JSON(w, r, MapErrorResponse("执行元数据核对失败", err))
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("元数据核对完成", report))
+}
+
+// GetReconciliationReport 获取核对报告
+// @Summary 获取核对报告
+// @Description 获取核对报告及问题项，问题项可按类别和状态过滤
+// @Tags 元数据核对
+// @Produce json
+// @Param id path string true "核对报告ID"
+// @Param category query string false "问题类别" Enums(missing_table, dangling_lineage, orphan_lineage, stale_masking_field)
+// @Param status query string false "问题状态" Enums(open, fixed, resolved, failed)
+// @Success 200 {object} APIResponse[models.ReconciliationReport] "获取成功"
+// @Failure 404 {object} APIResponse[any] "核对报告不存在"
+// @Router /reconciliation/reports/{id} [get]
+func (c *ReconciliationController) GetReconciliationReport(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	report, err := service.GlobalReconcileService.GetReport(r.Context(), chi.URLParam(r, "id"), query.Get("category"), query.Get("status"))
+	if err != nil {
+		render.JSON(w, r, MapErrorResponse("获取核对报告失败", err))
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("获取核对报告成功", report))
+}
+
+// FixReconciliationFindings 修复核对问题
+// @Summary 修复核对问题
+// @Description 修复核对报告中待修复或修复失败的问题：物理表不存在的接口标记为未建表、停用悬空的血缘关系、删除孤立的主题数据血缘记录、从脱敏配置中移除不存在的字段。修复前重新确认问题，已不存在的问题标记为resolved
+// @Tags 元数据核对
+// @Accept json
+// @Produce json
+// @Param id path string true "核对报告ID"
+// @Param request body reconcile.FixRequest false "要修复的问题项或类别，省略时修复全部"
+// @Success 200 {object} APIResponse[reconcile.FixResult] "修复完成"
+// @Failure 404 {object} APIResponse[any] "核对报告不存在"
+// @Router /reconciliation/reports/{id}/fix [post]
+func (c *ReconciliationController) FixReconciliationFindings(w http.ResponseWriter, r *http.Request) {
+	var req reconcile.FixRequest
+	if r.ContentLength > 0 {
+		if !decodeAndValidate(w, r, &req) {
+			return
+		}
+	}
+
+	result, err := service.GlobalReconcileService.FixFindings(r.Context(), chi.URLParam(r, "id"), &req, getCurrentUsername(r))
+	if err != nil {
+		render.JSON(w, r, MapErrorResponse("修复核对问题失败", err))
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("核对问题修复完成", result))
+}
@@ -0,0 +1,247 @@
+/*
+ * @module api/controllers/reference_controller
+ * @description 接口引用完整性控制器，提供逻辑外键的增删改查、立即检查、检查记录和孤立引用质量问题查询接口
+ * @architecture 分层架构 - 控制器层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow HTTP请求 -> 控制器 -> 接口引用完整性服务 -> 数据库
+ * @rules 统一的错误处理和响应格式；引用关系配置不合法时返回400；修改引用关系后从下一次检查开始生效
+ * @dependencies datahub-service/service, datahub-service/service/basic_library, github.com/go-chi/chi/v5
+ * @refs service/basic_library/reference_service.go
+ */
+
+package controllers
+
+import (
+	"datahub-service/service"
+	"datahub-service/service/basic_library"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+)
+
+// ReferenceController 接口引用完整性控制器
+type ReferenceController struct {
+}
+
+// NewReferenceController 创建接口引用完整性控制器实例
+func NewReferenceController() *ReferenceController {
+	return &ReferenceController{}
+}
+
+// SaveInterfaceReferenceRequest 引用关系配置请求
+type SaveInterfaceReferenceRequest struct {
+	Name              string   `json:"name" validate:"required,max=100" example:"设备所属楼栋"`
+	ChildInterfaceID  string   `json:"child_interface_id" validate:"required"`
+	ChildFields       []string `json:"child_fields" validate:"required,min=1" example:"building_id"`
+	ParentInterfaceID string   `json:"parent_interface_id" validate:"required"`
+	ParentFields      []string `json:"parent_fields" validate:"required,min=1" example:"id"`
+	Severity          string   `json:"severity" validate:"omitempty,oneof=low medium high critical" example:"medium"`
+	Enabled           *bool    `json:"enabled,omitempty"` // 默认启用
+	Description       string   `json:"description" validate:"max=500"`
+}
+
+func (req *SaveInterfaceReferenceRequest) toInput() *basic_library.ReferenceInput {
+	return &basic_library.ReferenceInput{
+		Name:              req.Name,
+		ChildInterfaceID:  req.ChildInterfaceID,
+		ChildFields:       req.ChildFields,
+		ParentInterfaceID: req.ParentInterfaceID,
+		ParentFields:      req.ParentFields,
+		Severity:          req.Severity,
+		Enabled:           req.Enabled,
+		Description:       req.Description,
+	}
+}
+
+// GetInterfaceReferences 获取接口引用关系列表
+// @Summary 获取接口引用关系列表
+// @Description 获取基础库接口间声明的逻辑外键，指定接口时返回该接口作为子接口或父接口的引用关系，附带最近一次检查的结果
+// @Tags 数据基础库
+// @Produce json
+// @Param interface_id query string false "接口ID"
+// @Success 200 {object} APIResponse[[]models.InterfaceReference] "获取成功"
+// @Router /basic-libraries/references [get]
+func (c *ReferenceController) GetInterfaceReferences(w http.ResponseWriter, r *http.Request) {
+	references, err := service.GlobalReferenceService.ListReferences(r.Context(), r.URL.Query().Get("interface_id"))
+	if err != nil {
+		render.JSON(w, r, MapErrorResponse("获取接口引用关系失败", err))
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("获取接口引用关系成功", references))
+}
+
+// CreateInterfaceReference 声明接口引用关系
+// @Summary 声明接口引用关系
+// @Description 声明子接口字段引用父接口字段的逻辑外键(如 device.building_id -> building.id)，多个字段按顺序一一对应；只用于引用完整性检查，不约束同步写入
+// @Tags 数据基础库
+// @Accept json
+// @Produce json
+// @Param request body SaveInterfaceReferenceRequest true "引用关系"
+// @Success 200 {object} APIResponse[models.InterfaceReference] "创建成功"
+// @Failure 400 {object} APIResponse[any] "请求参数错误"
+// @Router /basic-libraries/references [post]
+func (c *ReferenceController) CreateInterfaceReference(w http.ResponseWriter, r *http.Request) {
+	var req SaveInterfaceReferenceRequest
+	if !decodeAndValidate(w, r, &req) {
+		return
+	}
+
+	reference, err := service.GlobalReferenceService.CreateReference(r.Context(), req.toInput(), getCurrentUsername(r))
+	if err != nil {
+		c.renderSaveError(w, r, "创建接口引用关系失败", err)
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("创建接口引用关系成功", reference))
+}
+
+// GetInterfaceReference 获取接口引用关系
+// @Summary 获取接口引用关系
+// @Description 获取一个逻辑外键的配置和最近一次检查的结果
+// @Tags 数据基础库
+// @Produce json
+// @Param id path string true "引用关系ID"
+// @Success 200 {object} APIResponse[models.InterfaceReference] "获取成功"
+// @Failure 404 {object} APIResponse[any] "引用关系不存在"
+// @Router /basic-libraries/references/{id} [get]
+func (c *ReferenceController) GetInterfaceReference(w http.ResponseWriter, r *http.Request) {
+	reference, err := service.GlobalReferenceService.GetReference(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		render.JSON(w, r, MapErrorResponse("获取接口引用关系失败", err))
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("获取接口引用关系成功", reference))
+}
+
+// UpdateInterfaceReference 修改接口引用关系
+// @Summary 修改接口引用关系
+// @Description 修改逻辑外键的字段、严重程度或启用状态，从下一次检查开始生效
+// @Tags 数据基础库
+// @Accept json
+// @Produce json
+// @Param id path string true "引用关系ID"
+// @Param request body SaveInterfaceReferenceRequest true "引用关系"
+// @Success 200 {object} APIResponse[models.InterfaceReference] "修改成功"
+// @Failure 400 {object} APIResponse[any] "请求参数错误"
+// @Failure 404 {object} APIResponse[any] "引用关系不存在"
+// @Router /basic-libraries/references/{id} [put]
+func (c *ReferenceController) UpdateInterfaceReference(w http.ResponseWriter, r *http.Request) {
+	var req SaveInterfaceReferenceRequest
+	if !decodeAndValidate(w, r, &req) {
+		return
+	}
+
+	reference, err := service.GlobalReferenceService.UpdateReference(r.Context(), chi.URLParam(r, "id"), req.toInput(), getCurrentUsername(r))
+	if err != nil {
+		c.renderSaveError(w, r, "修改接口引用关系失败", err)
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("修改接口引用关系成功", reference))
+}
+
+// DeleteInterfaceReference 删除接口引用关系
+// @Summary 删除接口引用关系
+// @Description 删除逻辑外键及其检查记录，未处理的孤立引用质量问题标记为忽略
+// @Tags 数据基础库
+// @Produce json
+// @Param id path string true "引用关系ID"
+// @Success 200 {object} APIResponse[any] "删除成功"
+// @Failure 404 {object} APIResponse[any] "引用关系不存在"
+// @Router /basic-libraries/references/{id} [delete]
+func (c *ReferenceController) DeleteInterfaceReference(w http.ResponseWriter, r *http.Request) {
+	if err := service.GlobalReferenceService.DeleteReference(r.Context(), chi.URLParam(r, "id"), getCurrentUsername(r)); err != nil {
+		render.JSON(w, r, MapErrorResponse("删除接口引用关系失败", err))
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("删除接口引用关系成功", nil))
+}
+
+// CheckAllInterfaceReferences 立即检查所有启用的引用关系
+// @Summary 立即检查所有启用的引用关系
+// @Description 检查所有启用的逻辑外键，统计在父接口中找不到的孤立引用，孤立取值记为质量问题
+// @Tags 数据基础库
+// @Produce json
+// @Success 200 {object} APIResponse[basic_library.ReferenceCheckSummary] "检查完成"
+// @Router /basic-libraries/references/check [post]
+func (c *ReferenceController) CheckAllInterfaceReferences(w http.ResponseWriter, r *http.Request) {
+	summary, err := service.GlobalReferenceService.CheckAll(r.Context())
+	if err != nil {
+		render.JSON(w, r, MapErrorResponse("检查接口引用完整性失败", err))
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("检查接口引用完整性完成", summary))
+}
+
+// CheckInterfaceReference 立即检查一个引用关系
+// @Summary 立即检查一个引用关系
+// @Description 检查一个逻辑外键，返回子接口表行数、孤立行数、孤立取值数和按行数排列的孤立取值样例
+// @Tags 数据基础库
+// @Produce json
+// @Param id path string true "引用关系ID"
+// @Success 200 {object} APIResponse[models.InterfaceReferenceCheck] "检查完成"
+// @Failure 404 {object} APIResponse[any] "引用关系不存在"
+// @Router /basic-libraries/references/{id}/check [post]
+func (c *ReferenceController) CheckInterfaceReference(w http.ResponseWriter, r *http.Request) {
+	check, err := service.GlobalReferenceService.CheckReference(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		render.JSON(w, r, MapErrorResponse("检查接口引用完整性失败", err))
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("检查接口引用完整性完成", check))
+}
+
+// GetInterfaceReferenceChecks 获取引用关系的检查记录
+// @Summary 获取引用关系的检查记录
+// @Description 获取一个逻辑外键最近20次检查的结果
+// @Tags 数据基础库
+// @Produce json
+// @Param id path string true "引用关系ID"
+// @Success 200 {object} APIResponse[[]models.InterfaceReferenceCheck] "获取成功"
+// @Failure 404 {object} APIResponse[any] "引用关系不存在"
+// @Router /basic-libraries/references/{id}/checks [get]
+func (c *ReferenceController) GetInterfaceReferenceChecks(w http.ResponseWriter, r *http.Request) {
+	checks, err := service.GlobalReferenceService.GetChecks(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		render.JSON(w, r, MapErrorResponse("获取引用完整性检查记录失败", err))
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("获取引用完整性检查记录成功", checks))
+}
+
+// GetInterfaceReferenceIssues 获取引用关系的孤立引用质量问题
+// @Summary 获取引用关系的孤立引用质量问题
+// @Description 每个孤立取值对应一条质量问题，复查时不再出现的取值自动标记为已解决
+// @Tags 数据基础库
+// @Produce json
+// @Param id path string true "引用关系ID"
+// @Param status query string false "问题状态：open, resolved, ignored"
+// @Success 200 {object} APIResponse[[]models.QualityIssueTracker] "获取成功"
+// @Failure 404 {object} APIResponse[any] "引用关系不存在"
+// @Router /basic-libraries/references/{id}/issues [get]
+func (c *ReferenceController) GetInterfaceReferenceIssues(w http.ResponseWriter, r *http.Request) {
+	issues, err := service.GlobalReferenceService.GetIssues(r.Context(), chi.URLParam(r, "id"), r.URL.Query().Get("status"))
+	if err != nil {
+		render.JSON(w, r, MapErrorResponse("获取孤立引用问题失败", err))
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("获取孤立引用问题成功", issues))
+}
+
+// renderSaveError 引用关系配置不合法时返回400
+func (c *ReferenceController) renderSaveError(w http.ResponseWriter, r *http.Request, message string, err error) {
+	if errors.Is(err, basic_library.ErrInvalidReference) {
+		render.JSON(w, r, BadRequestResponse(message, err))
+		return
+	}
+	render.JSON(w, r, MapErrorResponse(message, err))
+}
@@ -0,0 +1,189 @@
+/*
+ * @module api/controllers/report_subscription_controller
+ * @description 报表订阅控制器，提供保存的查询和指标按日、按周定时生成文件并通过邮件或企业微信发送的订阅管理和立即发送接口
+ * @architecture 分层架构 - 控制器层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow HTTP请求 -> 控制器 -> 报表订阅服务 -> SQL工作台/指标层 -> 通知渠道
+ * @rules 统一的错误处理和响应格式；订阅不合法返回400，正在发送返回409，立即发送失败返回422并附带失败原因；只能查看和修改自己的订阅，管理员不受限制
+ * @dependencies datahub-service/service, datahub-service/service/report, github.com/go-chi/chi/v5
+ * @refs service/report/subscription_service.go
+ */
+
+package controllers
+
+import (
+	"datahub-service/api/middleware"
+	"datahub-service/service"
+	"datahub-service/service/models"
+	"datahub-service/service/report"
+	"datahub-service/service/workbench"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+)
+
+// ReportSubscriptionController 报表订阅控制器
+type ReportSubscriptionController struct {
+}
+
+// NewReportSubscriptionController 创建报表订阅控制器实例
+func NewReportSubscriptionController() *ReportSubscriptionController {
+	return &ReportSubscriptionController{}
+}
+
+// ReportSubscriptionListResponse 报表订阅列表响应结构
+type ReportSubscriptionListResponse struct {
+	List []models.ReportSubscription `json:"list"`
+	models.PageMeta
+}
+
+// GetReportSubscriptions 获取报表订阅列表
+// @Summary 获取报表订阅列表
+// @Description 分页获取自己的报表订阅及最近一次发送结果，管理员可以看到全部订阅
+// @Tags 报表订阅
+// @Produce json
+// @Param page query int false "页码" default(1)
+// @Param size query int false "每页数量" default(10)
+// @Param keyword query string false "名称关键字"
+// @Success 200 {object} APIResponse[ReportSubscriptionListResponse] "获取成功"
+// @Router /report-subscriptions [get]
+func (c *ReportSubscriptionController) GetReportSubscriptions(w http.ResponseWriter, r *http.Request) {
+	page, size := parsePagination(r)
+	subs, total, err := service.GlobalReportService.ListSubscriptions(r.Context(), r.URL.Query().Get("keyword"), page, size, reportSubject(r))
+	if err != nil {
+		render.JSON(w, r, MapErrorResponse("获取报表订阅列表失败", err))
+		return
+	}
+	render.JSON(w, r, SuccessResponse("获取报表订阅列表成功", ReportSubscriptionListResponse{
+		List:     subs,
+		PageMeta: models.NewPageMeta(total, page, size),
+	}))
+}
+
+// CreateReportSubscription 创建报表订阅
+// @Summary 创建报表订阅
+// @Description 订阅SQL工作台保存的查询（source_type=saved_query）或指标值（source_type=metric），按frequency在send_time（服务所在时区HH:MM，按周时weekday为星期，0为星期日）生成csv或xlsx文件，通过channel_id指定的邮件或企业微信渠道发送。订阅以创建人的身份取数，敏感字段按创建人的角色脱敏；recipients只用于邮件渠道，为空时使用渠道配置的收件人
+// @Tags 报表订阅
+// @Accept json
+// @Produce json
+// @Param request body report.SubscriptionRequest true "订阅信息"
+// @Success 200 {object} APIResponse[models.ReportSubscription] "创建成功"
+// @Failure 400 {object} APIResponse[any] "订阅不合法"
+// @Router /report-subscriptions [post]
+func (c *ReportSubscriptionController) CreateReportSubscription(w http.ResponseWriter, r *http.Request) {
+	var req report.SubscriptionRequest
+	if !decodeAndValidate(w, r, &req) {
+		return
+	}
+	sub, err := service.GlobalReportService.CreateSubscription(r.Context(), &req, reportSubject(r))
+	if err != nil {
+		render.JSON(w, r, reportErrorResponse("创建报表订阅失败", err))
+		return
+	}
+	render.JSON(w, r, SuccessResponse("创建报表订阅成功", sub))
+}
+
+// GetReportSubscription 获取报表订阅
+// @Summary 获取报表订阅
+// @Description 获取报表订阅及最近一次发送结果
+// @Tags 报表订阅
+// @Produce json
+// @Param id path string true "订阅ID"
+// @Success 200 {object} APIResponse[models.ReportSubscription] "获取成功"
+// @Failure 404 {object} APIResponse[any] "订阅不存在"
+// @Router /report-subscriptions/{id} [get]
+func (c *ReportSubscriptionController) GetReportSubscription(w http.ResponseWriter, r *http.Request) {
+	sub, err := service.GlobalReportService.GetSubscription(r.Context(), chi.URLParam(r, "id"), reportSubject(r))
+	if err != nil {
+		render.JSON(w, r, MapErrorResponse("获取报表订阅失败", err))
+		return
+	}
+	render.JSON(w, r, SuccessResponse("获取报表订阅成功", sub))
+}
+
+// UpdateReportSubscription 修改报表订阅
+// @Summary 修改报表订阅
+// @Description 修改报表订阅并重新计算下次发送时间，数据来源按订阅创建人的权限检查
+// @Tags 报表订阅
+// @Accept json
+// @Produce json
+// @Param id path string true "订阅ID"
+// @Param request body report.SubscriptionRequest true "订阅信息"
+// @Success 200 {object} APIResponse[models.ReportSubscription] "修改成功"
+// @Failure 400 {object} APIResponse[any] "订阅不合法"
+// @Failure 404 {object} APIResponse[any] "订阅不存在"
+// @Router /report-subscriptions/{id} [put]
+func (c *ReportSubscriptionController) UpdateReportSubscription(w http.ResponseWriter, r *http.Request) {
+	var req report.SubscriptionRequest
+	if !decodeAndValidate(w, r, &req) {
+		return
+	}
+	sub, err := service.GlobalReportService.UpdateSubscription(r.Context(), chi.URLParam(r, "id"), &req, reportSubject(r))
+	if err != nil {
+		render.JSON(w, r, reportErrorResponse("修改报表订阅失败", err))
+		return
+	}
+	render.JSON(w, r, SuccessResponse("修改报表订阅成功", sub))
+}
+
+// DeleteReportSubscription 删除报表订阅
+// @Summary 删除报表订阅
+// @Description 删除报表订阅
+// @Tags 报表订阅
+// @Produce json
+// @Param id path string true "订阅ID"
+// @Success 200 {object} APIResponse[any] "删除成功"
+// @Failure 404 {object} APIResponse[any] "订阅不存在"
+// @Router /report-subscriptions/{id} [delete]
+func (c *ReportSubscriptionController) DeleteReportSubscription(w http.ResponseWriter, r *http.Request) {
+	if err := service.GlobalReportService.DeleteSubscription(r.Context(), chi.URLParam(r, "id"), reportSubject(r)); err != nil {
+		render.JSON(w, r, MapErrorResponse("删除报表订阅失败", err))
+		return
+	}
+	render.JSON(w, r, SuccessResponse("删除报表订阅成功", nil))
+}
+
+// SendReportSubscription 立即发送报表
+// @Summary 立即发送报表
+// @Description 立即生成文件并发送，不改变下次发送时间
+// @Tags 报表订阅
+// @Produce json
+// @Param id path string true "订阅ID"
+// @Success 200 {object} APIResponse[models.ReportSubscription] "发送成功"
+// @Failure 404 {object} APIResponse[any] "订阅不存在"
+// @Failure 409 {object} APIResponse[any] "报表正在发送"
+// @Failure 422 {object} APIResponse[any] "发送失败"
+// @Router /report-subscriptions/{id}/send [post]
+func (c *ReportSubscriptionController) SendReportSubscription(w http.ResponseWriter, r *http.Request) {
+	sub, err := service.GlobalReportService.SendNow(r.Context(), chi.URLParam(r, "id"), reportSubject(r))
+	if err != nil {
+		render.JSON(w, r, reportErrorResponse("发送报表失败", err))
+		return
+	}
+	render.JSON(w, r, SuccessResponse("发送报表成功", sub))
+}
+
+// reportSubject 获取当前用户及令牌中的角色，分配的角色由报表订阅服务在取数时合并
+func reportSubject(r *http.Request) workbench.Subject {
+	subject := workbench.Subject{Username: getCurrentUsername(r), ClientIP: getClientIP(r)}
+	if userInfo, ok := middleware.GetUserInfoFromContext(r.Context()); ok {
+		subject.Roles = userInfo.Roles
+	}
+	return subject
+}
+
+// reportErrorResponse 按报表订阅的错误类型创建错误响应
+func reportErrorResponse(msg string, err error) render.Renderer {
+	switch {
+	case errors.Is(err, report.ErrInvalidSubscription):
+		return BadRequestResponse(msg+": "+err.Error(), err)
+	case errors.Is(err, report.ErrDeliveryRunning):
+		return ErrorResponse(StatusConflict, err.Error(), err)
+	case errors.Is(err, report.ErrDeliveryFailed):
+		return ErrorResponse(StatusUnprocessableEntity, msg+": "+err.Error(), err)
+	default:
+		return MapErrorResponse(msg, err)
+	}
+}
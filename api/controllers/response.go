@@ -1,6 +1,7 @@
 package controllers
 
 import (
+	"datahub-service/logger"
 	"net/http"
 
 	"github.com/go-chi/render"
@@ -8,25 +9,30 @@ import (
 
 // 业务状态码定义
 const (
-	StatusSuccess            = 0   // 成功
-	StatusBadRequest         = 400 // 请求参数错误
-	StatusUnauthorized       = 401 // 未授权
-	StatusForbidden          = 403 // 禁止访问
-	StatusNotFound           = 404 // 资源不存在
-	StatusConflict           = 409 // 冲突（如资源状态不允许操作）
-	StatusInternalError      = 500 // 服务器内部错误
-	StatusServiceUnavailable = 503 // 服务不可用
+	StatusSuccess             = 0   // 成功
+	StatusBadRequest          = 400 // 请求参数错误
+	StatusUnauthorized        = 401 // 未授权
+	StatusForbidden           = 403 // 禁止访问
+	StatusNotFound            = 404 // 资源不存在
+	StatusConflict            = 409 // 冲突（如资源状态不允许操作）
+	StatusUnprocessableEntity = 422 // 请求语义错误（如引用的关联数据不存在）
+	StatusInternalError       = 500 // 服务器内部错误
+	StatusServiceUnavailable  = 503 // 服务不可用
 )
 
-// APIResponse 统一API响应结构
-type APIResponse struct {
-	Status int         `json:"status" example:"0"`
-	Msg    string      `json:"msg" example:"操作成功"`
-	Data   interface{} `json:"data,omitempty"`
+// APIResponse 统一API响应结构，T为data的类型
+// 接口注释中以APIResponse[T]声明响应（如APIResponse[models.BasicLibrary]），生成的文档带有具体的data类型，
+// 便于生成强类型的SDK；data没有固定结构时使用APIResponse[any]
+type APIResponse[T any] struct {
+	RequestID string `json:"request_id,omitempty" example:"6f1c2a8e-3b7d-4c1e-9a2f-0d5e8b7c4a31"` // 请求ID，由RequestID中间件写出，与响应头X-Request-ID一致
+	Status    int    `json:"status" example:"0"`
+	Code      string `json:"code,omitempty" example:"VALIDATION_FAILED"` // 错误码，成功时为空，取值见ErrorCodeCatalog
+	Msg       string `json:"msg" example:"操作成功"`
+	Data      T      `json:"data,omitempty"`
 }
 
 // Response 实现render.Renderer接口
-func (a *APIResponse) Render(w http.ResponseWriter, r *http.Request) error {
+func (a *APIResponse[T]) Render(w http.ResponseWriter, r *http.Request) error {
 	// 统一设置HTTP状态码为200
 	w.WriteHeader(http.StatusOK)
 	return nil
@@ -34,22 +40,29 @@ func (a *APIResponse) Render(w http.ResponseWriter, r *http.Request) error {
 
 // SuccessResponse 创建成功响应
 func SuccessResponse(msg string, data interface{}) render.Renderer {
-	return &APIResponse{
+	return &APIResponse[any]{
 		Status: StatusSuccess,
 		Msg:    msg,
 		Data:   data,
 	}
 }
 
-// ErrorResponse 创建错误响应
+// ErrorResponse 创建错误响应，错误码按业务状态码取默认值
 func ErrorResponse(businessStatus int, msg string, err error) render.Renderer {
-	response := &APIResponse{
+	return ErrorResponseWithCode(businessStatus, defaultErrorCode(businessStatus), msg, err)
+}
+
+// ErrorResponseWithCode 创建指定错误码的错误响应
+func ErrorResponseWithCode(businessStatus int, code, msg string, err error) render.Renderer {
+	response := &APIResponse[any]{
 		Status: businessStatus,
-		Msg:    msg,
+		Code:   code,
+		Msg:    logger.SanitizeString(msg),
 	}
 
+	// 上游数据和数据库错误可能带有行数据，敏感字段脱敏后再返回
 	if err != nil {
-		response.Data = map[string]string{"error": err.Error()}
+		response.Data = map[string]string{"error": logger.SanitizeString(err.Error())}
 	}
 
 	return response
@@ -74,3 +87,13 @@ func ConflictResponse(msg string, err error) render.Renderer {
 func InternalErrorResponse(msg string, err error) render.Renderer {
 	return ErrorResponse(StatusInternalError, msg, err)
 }
+
+// ValidationErrorResponse 创建参数校验失败响应，data.errors为字段错误列表
+func ValidationErrorResponse(errs ValidationErrors) render.Renderer {
+	return &APIResponse[any]{
+		Status: StatusBadRequest,
+		Code:   CodeValidationFailed,
+		Msg:    "请求参数校验失败: " + errs.Error(),
+		Data:   map[string]interface{}{"errors": errs},
+	}
+}
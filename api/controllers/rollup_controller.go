@@ -0,0 +1,216 @@
+/*
+ * @module api/controllers/rollup_controller
+ * @description 时间序列降采样任务控制器，提供降采样任务的查询、创建、修改、删除、立即运行以及把汇总表注册为接口的接口
+ * @architecture 分层架构 - 控制器层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow HTTP请求 -> 控制器 -> 降采样任务服务 -> 汇总表DDL、任务记录、接口和血缘
+ * @rules 统一的错误处理和响应格式；配置不合法时返回400；时间桶和指标创建后不能修改
+ * @dependencies datahub-service/service, datahub-service/service/basic_library, github.com/go-chi/chi/v5
+ * @refs service/basic_library/rollup_service.go
+ */
+
+package controllers
+
+import (
+	"datahub-service/service"
+	"datahub-service/service/basic_library"
+	"datahub-service/service/models"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+)
+
+// RollupController 时间序列降采样任务控制器
+type RollupController struct {
+}
+
+// NewRollupController 创建时间序列降采样任务控制器实例
+func NewRollupController() *RollupController {
+	return &RollupController{}
+}
+
+// CreateRollupRequest 创建降采样任务请求
+type CreateRollupRequest struct {
+	Name                string                    `json:"name" validate:"required" example:"avg_5m"`               // 任务名，汇总表名为"接口表名_任务名"
+	BucketInterval      string                    `json:"bucket_interval" validate:"required" example:"5 minutes"` // 时间桶宽度
+	Metrics             []models.TimeSeriesMetric `json:"metrics" validate:"required"`                             // 聚合指标
+	RetentionPeriod     string                    `json:"retention_period" example:"1 year"`                       // 汇总表的保留时间，为空时永久保留
+	Lookback            string                    `json:"lookback" example:"1 hour"`                               // 每次运行重新汇总水位线之前这段时间，默认一个时间桶
+	RegisterAsInterface bool                      `json:"register_as_interface" example:"true"`                    // 是否同时把汇总表注册为接口
+	InterfaceNameZh     string                    `json:"interface_name_zh,omitempty" example:"设备温度（5分钟均值）"`       // 注册的接口中文名，默认按源接口名生成
+}
+
+// UpdateRollupRequest 修改降采样任务请求
+type UpdateRollupRequest struct {
+	Enabled         bool   `json:"enabled" example:"true"`
+	RetentionPeriod string `json:"retention_period" example:"1 year"` // 汇总表的保留时间，为空时永久保留
+	Lookback        string `json:"lookback" example:"1 hour"`         // 默认一个时间桶
+}
+
+// RegisterRollupRequest 把汇总表注册为接口请求
+type RegisterRollupRequest struct {
+	NameZh string `json:"name_zh,omitempty" example:"设备温度（5分钟均值）"` // 接口中文名，默认按源接口名生成
+}
+
+// ListRollups 获取接口的降采样任务
+// @Summary 获取接口的降采样任务
+// @Description 获取时间序列接口的降采样任务，包括汇总表、水位线和最近一次运行结果
+// @Tags 数据基础库
+// @Produce json
+// @Param id path string true "接口ID"
+// @Success 200 {object} APIResponse[[]models.TimeSeriesRollup] "获取成功"
+// @Failure 404 {object} APIResponse[any] "接口不存在"
+// @Router /basic-libraries/interfaces/{id}/timeseries/rollups [get]
+func (c *RollupController) ListRollups(w http.ResponseWriter, r *http.Request) {
+	rollups, err := service.GlobalRollupService.ListRollups(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		render.JSON(w, r, MapErrorResponse("获取降采样任务失败", err))
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("获取降采样任务成功", rollups))
+}
+
+// CreateRollup 创建降采样任务
+// @Summary 创建降采样任务
+// @Description 在源接口所在schema创建汇总表"接口表名_任务名"（bucket列、标签列和"列名_函数"指标列），转换为超表并按保留时间设置保留策略。调度器定时把新的完整时间桶汇总写入，首次运行从源表最早的数据开始补齐。源接口须已开启时间序列存储，原始数据的保留期在源接口的时间序列配置中设置
+// @Tags 数据基础库
+// @Accept json
+// @Produce json
+// @Param id path string true "接口ID"
+// @Param request body CreateRollupRequest true "降采样任务配置"
+// @Success 200 {object} APIResponse[models.TimeSeriesRollup] "创建成功"
+// @Failure 400 {object} APIResponse[any] "配置不合法"
+// @Failure 404 {object} APIResponse[any] "接口不存在"
+// @Router /basic-libraries/interfaces/{id}/timeseries/rollups [post]
+func (c *RollupController) CreateRollup(w http.ResponseWriter, r *http.Request) {
+	var req CreateRollupRequest
+	if !decodeAndValidate(w, r, &req) {
+		return
+	}
+
+	rollup := &models.TimeSeriesRollup{
+		Name:            req.Name,
+		BucketInterval:  req.BucketInterval,
+		Metrics:         req.Metrics,
+		RetentionPeriod: req.RetentionPeriod,
+		Lookback:        req.Lookback,
+	}
+	rollup, err := service.GlobalRollupService.CreateRollup(r.Context(), chi.URLParam(r, "id"), rollup,
+		req.RegisterAsInterface, req.InterfaceNameZh, getCurrentUsername(r))
+	if err != nil {
+		respondRollupError(w, r, "创建降采样任务失败", err)
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("创建降采样任务成功", rollup))
+}
+
+// UpdateRollup 修改降采样任务
+// @Summary 修改降采样任务
+// @Description 启停任务，修改汇总表的保留时间和回看时间；汇总表已注册为接口时保留时间同步到接口的时间序列配置。时间桶和指标不能修改，须删除后重建
+// @Tags 数据基础库
+// @Accept json
+// @Produce json
+// @Param id path string true "接口ID"
+// @Param name path string true "任务名"
+// @Param request body UpdateRollupRequest true "降采样任务配置"
+// @Success 200 {object} APIResponse[models.TimeSeriesRollup] "修改成功"
+// @Failure 400 {object} APIResponse[any] "配置不合法"
+// @Failure 404 {object} APIResponse[any] "接口或任务不存在"
+// @Router /basic-libraries/interfaces/{id}/timeseries/rollups/{name} [put]
+func (c *RollupController) UpdateRollup(w http.ResponseWriter, r *http.Request) {
+	var req UpdateRollupRequest
+	if !decodeAndValidate(w, r, &req) {
+		return
+	}
+
+	rollup, err := service.GlobalRollupService.UpdateRollup(r.Context(), chi.URLParam(r, "id"), chi.URLParam(r, "name"),
+		req.Enabled, req.RetentionPeriod, req.Lookback, getCurrentUsername(r))
+	if err != nil {
+		respondRollupError(w, r, "修改降采样任务失败", err)
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("修改降采样任务成功", rollup))
+}
+
+// DeleteRollup 删除降采样任务
+// @Summary 删除降采样任务
+// @Description 删除任务；汇总表未注册为接口时随之删除，已注册时汇总表和接口保留，降采样血缘停用
+// @Tags 数据基础库
+// @Produce json
+// @Param id path string true "接口ID"
+// @Param name path string true "任务名"
+// @Success 200 {object} APIResponse[any] "删除成功"
+// @Failure 404 {object} APIResponse[any] "接口或任务不存在"
+// @Router /basic-libraries/interfaces/{id}/timeseries/rollups/{name} [delete]
+func (c *RollupController) DeleteRollup(w http.ResponseWriter, r *http.Request) {
+	err := service.GlobalRollupService.DeleteRollup(r.Context(), chi.URLParam(r, "id"), chi.URLParam(r, "name"), getCurrentUsername(r))
+	if err != nil {
+		respondRollupError(w, r, "删除降采样任务失败", err)
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("删除降采样任务成功", nil))
+}
+
+// RunRollup 立即运行降采样任务
+// @Summary 立即运行降采样任务
+// @Description 立即汇总新的完整时间桶，返回运行后的水位线和运行结果；单次最多汇总一千个时间桶
+// @Tags 数据基础库
+// @Produce json
+// @Param id path string true "接口ID"
+// @Param name path string true "任务名"
+// @Success 200 {object} APIResponse[models.TimeSeriesRollup] "运行成功"
+// @Failure 404 {object} APIResponse[any] "接口或任务不存在"
+// @Failure 500 {object} APIResponse[any] "汇总失败"
+// @Router /basic-libraries/interfaces/{id}/timeseries/rollups/{name}/run [post]
+func (c *RollupController) RunRollup(w http.ResponseWriter, r *http.Request) {
+	rollup, err := service.GlobalRollupService.RunRollup(r.Context(), chi.URLParam(r, "id"), chi.URLParam(r, "name"))
+	if err != nil {
+		respondRollupError(w, r, "运行降采样任务失败", err)
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("运行降采样任务成功", rollup))
+}
+
+// RegisterRollup 把汇总表注册为接口
+// @Summary 把降采样汇总表注册为接口
+// @Description 在源接口所在基础库创建批量接口（英文名为汇总表名，时间序列配置指向bucket列），并自动建立源接口到该接口的aggregated血缘
+// @Tags 数据基础库
+// @Accept json
+// @Produce json
+// @Param id path string true "接口ID"
+// @Param name path string true "任务名"
+// @Param request body RegisterRollupRequest false "接口名称"
+// @Success 200 {object} APIResponse[models.DataInterface] "注册成功"
+// @Failure 400 {object} APIResponse[any] "已注册或接口名重复"
+// @Failure 404 {object} APIResponse[any] "接口或任务不存在"
+// @Router /basic-libraries/interfaces/{id}/timeseries/rollups/{name}/register [post]
+func (c *RollupController) RegisterRollup(w http.ResponseWriter, r *http.Request) {
+	var req RegisterRollupRequest
+	if r.ContentLength > 0 && !decodeAndValidate(w, r, &req) {
+		return
+	}
+
+	iface, err := service.GlobalRollupService.RegisterRollup(r.Context(), chi.URLParam(r, "id"), chi.URLParam(r, "name"), req.NameZh, getCurrentUsername(r))
+	if err != nil {
+		respondRollupError(w, r, "注册汇总接口失败", err)
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("注册汇总接口成功", iface))
+}
+
+// respondRollupError 配置不合法时返回400，其余按错误类型映射
+func respondRollupError(w http.ResponseWriter, r *http.Request, msg string, err error) {
+	if errors.Is(err, basic_library.ErrInvalidRollupRequest) || errors.Is(err, basic_library.ErrInvalidTimeSeriesRequest) {
+		render.JSON(w, r, BadRequestResponse(msg, err))
+		return
+	}
+	render.JSON(w, r, MapErrorResponse(msg, err))
+}
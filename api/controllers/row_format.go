@@ -0,0 +1,126 @@
+/*
+ * @module api/controllers/row_format
+ * @description 数据行接口的内容协商，请求头Accept为text/csv或application/x-ndjson时逐行写出CSV或NDJSON，不构造JSON数组
+ * @architecture 分层架构 - 控制器层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow Accept请求头 -> 协商格式 -> 设置Content-Type和分页头 -> 逐行写出 -> 每rowFlushInterval行刷新到客户端
+ * @rules 未请求CSV/NDJSON时各接口保持原有的JSON响应；错误仍以JSON返回；开始写出后出错只能中断响应并记录日志；HEAD请求只返回响应头
+ * @dependencies datahub-service/service/utils
+ * @refs service/utils/row_stream.go, data_view_controller.go, basic_library_controller.go, data_proxy_controller.go
+ */
+
+package controllers
+
+import (
+	"datahub-service/service/utils"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+)
+
+// rowFlushInterval 流式写出时每写出多少行刷新一次
+const rowFlushInterval = 500
+
+// negotiateRowFormat 按请求头Accept协商数据行的输出格式
+func negotiateRowFormat(r *http.Request) utils.RowFormat {
+	return utils.NegotiateRowFormat(r.Header.Get("Accept"))
+}
+
+// rowStream 逐行写出响应
+type rowStream struct {
+	writer     utils.RowWriter
+	controller *http.ResponseController
+	counter    *countingWriter
+	rows       int
+}
+
+// countingWriter 统计写出的字节数
+type countingWriter struct {
+	w       io.Writer
+	written int64
+}
+
+// Write 写出并计数
+func (cw *countingWriter) Write(p []byte) (int, error) {
+	n, err := cw.w.Write(p)
+	cw.written += int64(n)
+	return n, err
+}
+
+// startRowStream 写出响应头并创建行写入器，columns为CSV的列顺序，为空时取第一行的字段；HEAD请求返回nil
+func startRowStream(w http.ResponseWriter, r *http.Request, format utils.RowFormat, columns []string, status int) (*rowStream, error) {
+	counter := &countingWriter{w: w}
+	writer, err := utils.NewRowWriter(counter, format, columns)
+	if err != nil {
+		return nil, err
+	}
+
+	w.Header().Set("Content-Type", format.ContentType())
+	w.Header().Del("Content-Length")
+	w.WriteHeader(status)
+	if r.Method == http.MethodHead {
+		return nil, nil
+	}
+	return &rowStream{writer: writer, controller: http.NewResponseController(w), counter: counter}, nil
+}
+
+// Write 写出一行，每rowFlushInterval行刷新到客户端
+func (s *rowStream) Write(row map[string]interface{}) error {
+	if err := s.writer.WriteRow(row); err != nil {
+		return err
+	}
+	s.rows++
+	if s.rows%rowFlushInterval == 0 {
+		return s.flush()
+	}
+	return nil
+}
+
+// Close 刷新剩余的数据
+func (s *rowStream) Close() error {
+	return s.flush()
+}
+
+// Written 已写出的字节数
+func (s *rowStream) Written() int64 {
+	return s.counter.written
+}
+
+// flush 刷新行写入器和响应
+func (s *rowStream) flush() error {
+	if err := s.writer.Flush(); err != nil {
+		return err
+	}
+	if err := s.controller.Flush(); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		return err
+	}
+	return nil
+}
+
+// writeRows 以协商的格式写出已查询的数据行，offset和total非负时通过Content-Range给出本页范围和总数
+func writeRows(w http.ResponseWriter, r *http.Request, format utils.RowFormat, rows []map[string]interface{}, offset int, total int64) int64 {
+	if offset >= 0 && total >= 0 {
+		contentRange := fmt.Sprintf("*/%d", total)
+		if len(rows) > 0 {
+			contentRange = fmt.Sprintf("%d-%d/%d", offset, offset+len(rows)-1, total)
+		}
+		w.Header().Set("Content-Range", contentRange)
+	}
+
+	stream, err := startRowStream(w, r, format, nil, http.StatusOK)
+	if err != nil || stream == nil {
+		return 0
+	}
+	for _, row := range rows {
+		if err := stream.Write(row); err != nil {
+			slog.Warn("写出数据行失败", "path", r.URL.Path, "format", format, "error", err)
+			return stream.Written()
+		}
+	}
+	if err := stream.Close(); err != nil {
+		slog.Warn("写出数据行失败", "path", r.URL.Path, "format", format, "error", err)
+	}
+	return stream.Written()
+}
@@ -0,0 +1,185 @@
+/*
+ * @module api/controllers/row_policy_controller
+ * @description 行级安全策略管理接口，策略在数据共享代理和数据查看接口中自动生效
+ * @architecture 分层架构 - 控制器层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow HTTP请求 -> 控制器 -> 数据共享服务 -> 数据库
+ * @rules 统一的错误处理和响应格式
+ * @dependencies datahub-service/service/sharing, github.com/go-chi/chi/v5
+ * @refs service/sharing/row_policy.go
+ */
+
+package controllers
+
+import (
+	"datahub-service/service/models"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"gorm.io/gorm"
+)
+
+// RowLevelPolicyRequest 行级安全策略请求结构
+type RowLevelPolicyRequest struct {
+	Name           string            `json:"name" validate:"required" example:"物业公司A楼栋范围"`
+	Description    string            `json:"description"`
+	SchemaName     string            `json:"schema_name" validate:"required" example:"property_theme"`
+	TableName      string            `json:"table_name" validate:"required" example:"buildings"`
+	SubjectType    string            `json:"subject_type" validate:"required" example:"api_key"` // api_key/application/role/user
+	SubjectID      string            `json:"subject_id" validate:"required"`
+	Conditions     models.JSONBArray `json:"conditions" validate:"required"` // [{"field":"company_id","operator":"eq","value":"A"}]
+	ConditionLogic string            `json:"condition_logic" example:"and"`  // and/or
+	IsEnabled      *bool             `json:"is_enabled"`
+}
+
+// RowLevelPolicyListResponse 行级安全策略列表响应结构
+type RowLevelPolicyListResponse struct {
+	List []models.RowLevelPolicy `json:"list"`
+	models.PageMeta
+}
+
+// toModel 转换为策略模型
+func (req *RowLevelPolicyRequest) toModel(operator string) *models.RowLevelPolicy {
+	isEnabled := true
+	if req.IsEnabled != nil {
+		isEnabled = *req.IsEnabled
+	}
+	return &models.RowLevelPolicy{
+		Name:           req.Name,
+		Description:    req.Description,
+		SchemaName:     req.SchemaName,
+		TableName:      req.TableName,
+		SubjectType:    req.SubjectType,
+		SubjectID:      req.SubjectID,
+		Conditions:     req.Conditions,
+		ConditionLogic: req.ConditionLogic,
+		IsEnabled:      isEnabled,
+		CreatedBy:      operator,
+		UpdatedBy:      operator,
+	}
+}
+
+// CreateRowLevelPolicy 创建行级安全策略
+// @Summary 创建行级安全策略
+// @Description 为指定表定义按API密钥、应用、角色或用户生效的行过滤条件，条件值支持${username}、${api_key_id}、${application_id}变量
+// @Tags 数据共享服务
+// @Accept json
+// @Produce json
+// @Param policy body RowLevelPolicyRequest true "行级安全策略"
+// @Success 200 {object} APIResponse[models.RowLevelPolicy] "创建成功"
+// @Failure 400 {object} APIResponse[any] "请求参数错误"
+// @Router /sharing/row-policies [post]
+func (c *SharingController) CreateRowLevelPolicy(w http.ResponseWriter, r *http.Request) {
+	var req RowLevelPolicyRequest
+	if !decodeAndValidate(w, r, &req) {
+		return
+	}
+
+	policy := req.toModel(getCurrentUsername(r))
+	if err := c.sharingService.CreateRowLevelPolicy(policy); err != nil {
+		render.JSON(w, r, BadRequestResponse("创建行级安全策略失败: "+err.Error(), err))
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("创建行级安全策略成功", policy))
+}
+
+// GetRowLevelPolicies 获取行级安全策略列表
+// @Summary 获取行级安全策略列表
+// @Description 分页获取行级安全策略，可按表和主体过滤
+// @Tags 数据共享服务
+// @Produce json
+// @Param page query int false "页码" default(1)
+// @Param size query int false "每页数量" default(10)
+// @Param schema_name query string false "库英文名"
+// @Param table_name query string false "表名"
+// @Param subject_type query string false "主体类型：api_key/application/role/user"
+// @Param subject_id query string false "主体标识"
+// @Success 200 {object} APIResponse[RowLevelPolicyListResponse] "获取成功"
+// @Failure 500 {object} APIResponse[any] "服务器内部错误"
+// @Router /sharing/row-policies [get]
+func (c *SharingController) GetRowLevelPolicies(w http.ResponseWriter, r *http.Request) {
+	page, size := parsePagination(r)
+
+	query := r.URL.Query()
+	policies, total, err := c.sharingService.GetRowLevelPolicies(page, size,
+		query.Get("schema_name"), query.Get("table_name"), query.Get("subject_type"), query.Get("subject_id"))
+	if err != nil {
+		render.JSON(w, r, MapErrorResponse("获取行级安全策略列表失败", err))
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("获取行级安全策略列表成功", RowLevelPolicyListResponse{
+		List:     policies,
+		PageMeta: models.NewPageMeta(total, page, size),
+	}))
+}
+
+// GetRowLevelPolicyByID 获取行级安全策略详情
+// @Summary 获取行级安全策略详情
+// @Description 根据ID获取行级安全策略
+// @Tags 数据共享服务
+// @Produce json
+// @Param id path string true "策略ID"
+// @Success 200 {object} APIResponse[models.RowLevelPolicy] "获取成功"
+// @Failure 404 {object} APIResponse[any] "策略不存在"
+// @Router /sharing/row-policies/{id} [get]
+func (c *SharingController) GetRowLevelPolicyByID(w http.ResponseWriter, r *http.Request) {
+	policy, err := c.sharingService.GetRowLevelPolicyByID(chi.URLParam(r, "id"))
+	if err != nil {
+		render.JSON(w, r, NotFoundResponse("行级安全策略不存在", err))
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("获取行级安全策略成功", policy))
+}
+
+// UpdateRowLevelPolicy 更新行级安全策略
+// @Summary 更新行级安全策略
+// @Description 替换行级安全策略的配置
+// @Tags 数据共享服务
+// @Accept json
+// @Produce json
+// @Param id path string true "策略ID"
+// @Param policy body RowLevelPolicyRequest true "行级安全策略"
+// @Success 200 {object} APIResponse[any] "更新成功"
+// @Failure 400 {object} APIResponse[any] "请求参数错误"
+// @Failure 404 {object} APIResponse[any] "策略不存在"
+// @Router /sharing/row-policies/{id} [put]
+func (c *SharingController) UpdateRowLevelPolicy(w http.ResponseWriter, r *http.Request) {
+	var req RowLevelPolicyRequest
+	if !decodeAndValidate(w, r, &req) {
+		return
+	}
+
+	if err := c.sharingService.UpdateRowLevelPolicy(chi.URLParam(r, "id"), req.toModel(getCurrentUsername(r))); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			render.JSON(w, r, NotFoundResponse("行级安全策略不存在", err))
+			return
+		}
+		render.JSON(w, r, BadRequestResponse("更新行级安全策略失败: "+err.Error(), err))
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("更新行级安全策略成功", nil))
+}
+
+// DeleteRowLevelPolicy 删除行级安全策略
+// @Summary 删除行级安全策略
+// @Description 删除指定的行级安全策略
+// @Tags 数据共享服务
+// @Produce json
+// @Param id path string true "策略ID"
+// @Success 200 {object} APIResponse[any] "删除成功"
+// @Failure 500 {object} APIResponse[any] "服务器内部错误"
+// @Router /sharing/row-policies/{id} [delete]
+func (c *SharingController) DeleteRowLevelPolicy(w http.ResponseWriter, r *http.Request) {
+	if err := c.sharingService.DeleteRowLevelPolicy(chi.URLParam(r, "id")); err != nil {
+		render.JSON(w, r, MapErrorResponse("删除行级安全策略失败", err))
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("删除行级安全策略成功", nil))
+}
@@ -0,0 +1,89 @@
+/*
+ * @module api/controllers/row_version
+ * @description 乐观并发控制的HTTP约定：查询接口通过ETag返回对象版本、通过Last-Modified返回更新时间，更新接口通过If-Match携带期望版本
+ * @architecture 分层架构 - 控制器层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow GET返回ETag -> PUT携带If-Match -> 版本一致则更新 / 版本冲突返回409和最新版本
+ * @rules 未携带If-Match或为*时不校验版本；If-Match优先于请求体中的row_version；ConditionalGET中间件会把ETag改写为"版本-内容摘要"，If-Match只取版本部分
+ * @dependencies datahub-service/service/models
+ * @refs service/models/row_version.go
+ */
+
+package controllers
+
+import (
+	"datahub-service/service/models"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/render"
+)
+
+// VersionConflictData 版本冲突响应数据
+type VersionConflictData struct {
+	Error          string `json:"error"`
+	CurrentVersion int64  `json:"current_version" example:"4"`
+}
+
+// parseIfMatch 解析If-Match请求头中的版本号，兼容"版本-内容摘要"形式的ETag，未携带或为*时返回0
+func parseIfMatch(r *http.Request) (int64, error) {
+	value := strings.TrimSpace(r.Header.Get("If-Match"))
+	if value == "" || value == "*" {
+		return 0, nil
+	}
+	value = strings.Trim(strings.TrimPrefix(value, "W/"), `"`)
+	if i := strings.IndexByte(value, '-'); i > 0 {
+		value = value[:i]
+	}
+	version, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || version <= 0 {
+		return 0, fmt.Errorf("无效的If-Match版本: %s", r.Header.Get("If-Match"))
+	}
+	return version, nil
+}
+
+// expectedRowVersion 获取期望版本，If-Match优先于请求体中的版本
+func expectedRowVersion(r *http.Request, bodyVersion int64) (int64, error) {
+	version, err := parseIfMatch(r)
+	if err != nil || version > 0 {
+		return version, err
+	}
+	return bodyVersion, nil
+}
+
+// setETag 设置对象版本对应的ETag响应头
+func setETag(w http.ResponseWriter, version int64) {
+	if version > 0 {
+		w.Header().Set("ETag", fmt.Sprintf(`"%d"`, version))
+	}
+}
+
+// setLastModified 设置对象更新时间对应的Last-Modified响应头
+func setLastModified(w http.ResponseWriter, updatedAt time.Time) {
+	if !updatedAt.IsZero() {
+		w.Header().Set("Last-Modified", updatedAt.UTC().Format(http.TimeFormat))
+	}
+}
+
+// handleVersionConflict 版本冲突时返回409并携带最新版本，返回true表示已处理
+func handleVersionConflict(w http.ResponseWriter, r *http.Request, err error) bool {
+	var conflict *models.VersionConflictError
+	if !errors.As(err, &conflict) {
+		return false
+	}
+	setETag(w, conflict.Current)
+	render.JSON(w, r, &APIResponse[any]{
+		Status: StatusConflict,
+		Code:   CodeVersionConflict,
+		Msg:    models.ErrVersionConflict.Error(),
+		Data: VersionConflictData{
+			Error:          conflict.Error(),
+			CurrentVersion: conflict.Current,
+		},
+	})
+	return true
+}
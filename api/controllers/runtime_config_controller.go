@@ -0,0 +1,92 @@
+/*
+ * @module api/controllers/runtime_config_controller
+ * @description 运行时配置管理控制器，查看和修改同步批量大小、预览条数等调优参数，修改后无需重启即生效
+ * @architecture 分层架构 - 控制器层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow HTTP请求 -> 控制器 -> 配置服务 -> 数据库 -> 重新加载运行时配置快照
+ * @rules 只允许修改已定义的配置项且取值必须在范围内；修改在当前实例立即生效，其他实例在定时重新加载后生效
+ * @dependencies datahub-service/service, datahub-service/service/config
+ * @refs service/config/runtime.go, api/routes.go
+ */
+
+package controllers
+
+import (
+	"datahub-service/service"
+	"datahub-service/service/config"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/render"
+)
+
+// RuntimeConfigController 运行时配置控制器
+type RuntimeConfigController struct {
+}
+
+// NewRuntimeConfigController 创建运行时配置控制器实例
+func NewRuntimeConfigController() *RuntimeConfigController {
+	return &RuntimeConfigController{}
+}
+
+// UpdateRuntimeConfigRequest 更新运行时配置请求结构
+type UpdateRuntimeConfigRequest struct {
+	Values map[string]int `json:"values" validate:"required"`
+}
+
+// GetRuntimeConfig 获取运行时配置
+// @Summary 获取运行时配置
+// @Description 获取全部运行时配置项的定义、当前生效值和来源（database/env/default）
+// @Tags 系统配置
+// @Produce json
+// @Success 200 {object} APIResponse[config.RuntimeConfig] "获取成功"
+// @Router /admin/config [get]
+func (c *RuntimeConfigController) GetRuntimeConfig(w http.ResponseWriter, r *http.Request) {
+	render.JSON(w, r, SuccessResponse("获取运行时配置成功", service.GlobalConfigService.GetRuntimeConfig()))
+}
+
+// UpdateRuntimeConfig 更新运行时配置
+// @Summary 更新运行时配置
+// @Description 修改一个或多个运行时配置项，保存到数据库后立即重新加载，无需重启服务
+// @Tags 系统配置
+// @Accept json
+// @Produce json
+// @Param request body UpdateRuntimeConfigRequest true "配置项和新值"
+// @Success 200 {object} APIResponse[config.RuntimeConfig] "更新成功"
+// @Failure 400 {object} APIResponse[any] "配置项不存在或取值不合法"
+// @Router /admin/config [put]
+func (c *RuntimeConfigController) UpdateRuntimeConfig(w http.ResponseWriter, r *http.Request) {
+	var req UpdateRuntimeConfigRequest
+	if !decodeAndValidate(w, r, &req) {
+		return
+	}
+
+	runtimeConfig, err := service.GlobalConfigService.UpdateRuntimeConfig(req.Values, getCurrentUsername(r))
+	if err != nil {
+		if errors.Is(err, config.ErrInvalidRuntimeConfig) {
+			render.JSON(w, r, BadRequestResponse("更新运行时配置失败", err))
+			return
+		}
+		render.JSON(w, r, MapErrorResponse("更新运行时配置失败", err))
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("更新运行时配置成功", runtimeConfig))
+}
+
+// ReloadRuntimeConfig 重新加载运行时配置
+// @Summary 重新加载运行时配置
+// @Description 立即从数据库和环境变量重新加载运行时配置，用于直接修改数据库后使当前实例生效
+// @Tags 系统配置
+// @Produce json
+// @Success 200 {object} APIResponse[config.RuntimeConfig] "加载成功"
+// @Router /admin/config/reload [post]
+func (c *RuntimeConfigController) ReloadRuntimeConfig(w http.ResponseWriter, r *http.Request) {
+	runtimeConfig, err := service.GlobalConfigService.ReloadRuntimeConfig()
+	if err != nil {
+		render.JSON(w, r, MapErrorResponse("重新加载运行时配置失败", err))
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("重新加载运行时配置成功", runtimeConfig))
+}
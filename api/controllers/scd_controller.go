@@ -0,0 +1,130 @@
+/*
+ * @module api/controllers/scd_controller
+ * @description 接口历史追踪（SCD Type 2）控制器，提供查询、开启或修改、关闭历史追踪的接口
+ * @architecture 分层架构 - 控制器层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow HTTP请求 -> 控制器 -> 历史追踪配置服务 -> 修改表结构并保存接口配置
+ * @rules 统一的错误处理和响应格式；配置不合法时返回400；If-Match与接口版本不一致时返回409；关闭会删除历史版本
+ * @dependencies datahub-service/service, datahub-service/service/basic_library, github.com/go-chi/chi/v5
+ * @refs service/basic_library/scd_service.go
+ */
+
+package controllers
+
+import (
+	"datahub-service/service"
+	"datahub-service/service/basic_library"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+)
+
+// SCDController 接口历史追踪控制器
+type SCDController struct {
+}
+
+// NewSCDController 创建接口历史追踪控制器实例
+func NewSCDController() *SCDController {
+	return &SCDController{}
+}
+
+// EnableSCDRequest 开启或修改历史追踪请求
+type EnableSCDRequest struct {
+	NaturalKeys    []string `json:"natural_keys" example:"plate_no"`    // 自然键，为空时使用当前主键；已开启时不能修改
+	TrackedColumns []string `json:"tracked_columns" example:"owner_id"` // 变化时产生新版本的列，为空表示除自然键外的全部列
+}
+
+// GetSCDConfig 获取接口历史追踪配置
+// @Summary 获取接口历史追踪配置
+// @Description 获取接口是否开启缓慢变化维历史追踪，以及自然键和跟踪列
+// @Tags 数据基础库
+// @Produce json
+// @Param id path string true "接口ID"
+// @Success 200 {object} APIResponse[models.SCDConfig] "获取成功"
+// @Failure 404 {object} APIResponse[any] "接口不存在"
+// @Router /basic-libraries/interfaces/{id}/scd [get]
+func (c *SCDController) GetSCDConfig(w http.ResponseWriter, r *http.Request) {
+	config, err := service.GlobalSCDService.GetSCDConfig(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		render.JSON(w, r, MapErrorResponse("获取历史追踪配置失败", err))
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("获取历史追踪配置成功", config))
+}
+
+// EnableSCD 开启或修改接口历史追踪
+// @Summary 开启或修改接口历史追踪
+// @Description 开启后接口表增加代理键、生效时间、失效时间和当前版本列，主键改为代理键，已有数据成为当前版本；之后的同步按自然键比较跟踪列，变化时关闭当前版本并插入新版本，只有非跟踪列变化时直接更新当前版本。已开启时只能修改跟踪列
+// @Tags 数据基础库
+// @Accept json
+// @Produce json
+// @Param id path string true "接口ID"
+// @Param If-Match header string false "期望版本（ETag），不一致时返回409"
+// @Param request body EnableSCDRequest true "历史追踪配置"
+// @Success 200 {object} APIResponse[models.SCDConfig] "设置成功"
+// @Failure 400 {object} APIResponse[any] "配置不合法"
+// @Failure 404 {object} APIResponse[any] "接口不存在"
+// @Failure 409 {object} APIResponse[VersionConflictData] "版本冲突"
+// @Router /basic-libraries/interfaces/{id}/scd [put]
+func (c *SCDController) EnableSCD(w http.ResponseWriter, r *http.Request) {
+	var req EnableSCDRequest
+	if !decodeAndValidate(w, r, &req) {
+		return
+	}
+
+	expectedVersion, err := expectedRowVersion(r, 0)
+	if err != nil {
+		render.JSON(w, r, BadRequestResponse(err.Error(), err))
+		return
+	}
+
+	config, err := service.GlobalSCDService.EnableSCD(r.Context(), chi.URLParam(r, "id"), expectedVersion, req.NaturalKeys, req.TrackedColumns, getCurrentUsername(r))
+	if err != nil {
+		respondSCDError(w, r, "设置历史追踪失败", err)
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("设置历史追踪成功", config))
+}
+
+// DisableSCD 关闭接口历史追踪
+// @Summary 关闭接口历史追踪
+// @Description 删除全部历史版本，只保留当前版本，去掉版本列并恢复自然键主键，之后的同步按原方式覆盖数据
+// @Tags 数据基础库
+// @Produce json
+// @Param id path string true "接口ID"
+// @Param If-Match header string false "期望版本（ETag），不一致时返回409"
+// @Success 200 {object} APIResponse[any] "关闭成功"
+// @Failure 400 {object} APIResponse[any] "接口未开启历史追踪"
+// @Failure 404 {object} APIResponse[any] "接口不存在"
+// @Failure 409 {object} APIResponse[VersionConflictData] "版本冲突"
+// @Router /basic-libraries/interfaces/{id}/scd [delete]
+func (c *SCDController) DisableSCD(w http.ResponseWriter, r *http.Request) {
+	expectedVersion, err := expectedRowVersion(r, 0)
+	if err != nil {
+		render.JSON(w, r, BadRequestResponse(err.Error(), err))
+		return
+	}
+
+	if err := service.GlobalSCDService.DisableSCD(r.Context(), chi.URLParam(r, "id"), expectedVersion, getCurrentUsername(r)); err != nil {
+		respondSCDError(w, r, "关闭历史追踪失败", err)
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("关闭历史追踪成功", nil))
+}
+
+// respondSCDError 版本冲突时返回409，配置不合法时返回400，其余按错误类型映射
+func respondSCDError(w http.ResponseWriter, r *http.Request, msg string, err error) {
+	if handleVersionConflict(w, r, err) {
+		return
+	}
+	if errors.Is(err, basic_library.ErrInvalidSCDRequest) {
+		render.JSON(w, r, BadRequestResponse(msg, err))
+		return
+	}
+	render.JSON(w, r, MapErrorResponse(msg, err))
+}
@@ -0,0 +1,56 @@
+/*
+ * @module api/controllers/schema_registry_controller
+ * @description 消息数据源schema注册中心控制器，返回注册中心主题映射的接口表字段，并检查指定版本与基准版本的兼容性
+ * @architecture 分层架构 - 控制器层
+ * @documentReference ai_docs/datasource_req1.md
+ * @stateFlow HTTP请求 -> 控制器 -> schema注册中心查询服务 -> 获取schema并映射字段
+ * @rules 统一的错误处理和响应格式；只读查询；数据源未配置注册中心时返回400
+ * @dependencies datahub-service/service, datahub-service/service/basic_library, github.com/go-chi/chi/v5
+ * @refs service/basic_library/schema_registry_service.go
+ */
+
+package controllers
+
+import (
+	"datahub-service/service"
+	"datahub-service/service/basic_library"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+)
+
+// SchemaRegistryController 消息数据源schema注册中心控制器
+type SchemaRegistryController struct {
+}
+
+// NewSchemaRegistryController 创建消息数据源schema注册中心控制器实例
+func NewSchemaRegistryController() *SchemaRegistryController {
+	return &SchemaRegistryController{}
+}
+
+// GetRegistrySchema 获取数据源注册中心schema的字段映射
+// @Summary 获取数据源注册中心schema的字段映射
+// @Description 按数据源参数中配置的schema注册中心(Confluent/Apicurio)和主题获取Avro或Protobuf schema，返回映射的接口表字段，可直接用作接口的表字段配置。指定version时返回该版本的字段映射，并检查它与基准版本的兼容性：删除必填字段或类型变化超出允许的提升(int->long/float/double、long->float/double、float->double、string<->bytes、enum->string)时不兼容，这类消息在同步时会被拒绝
+// @Tags 数据基础库
+// @Produce json
+// @Param id path string true "数据源ID"
+// @Param version query string false "要检查的主题版本(latest或版本号)，为空时返回基准版本"
+// @Success 200 {object} APIResponse[basic_library.RegistrySchema] "获取成功"
+// @Failure 400 {object} APIResponse[any] "数据源未配置schema注册中心"
+// @Failure 404 {object} APIResponse[any] "数据源不存在"
+// @Router /basic-libraries/datasources/{id}/registry-schema [get]
+func (c *SchemaRegistryController) GetRegistrySchema(w http.ResponseWriter, r *http.Request) {
+	schema, err := service.GlobalSchemaRegistryService.GetSchema(r.Context(), chi.URLParam(r, "id"), r.URL.Query().Get("version"))
+	if err != nil {
+		if errors.Is(err, basic_library.ErrSchemaRegistryNotConfigured) {
+			render.JSON(w, r, BadRequestResponse("获取注册中心schema失败", err))
+			return
+		}
+		render.JSON(w, r, MapErrorResponse("获取注册中心schema失败", err))
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("获取注册中心schema成功", schema))
+}
@@ -14,9 +14,9 @@ package controllers
 import (
 	"datahub-service/service/models"
 	"datahub-service/service/sharing"
+	"errors"
 	"fmt"
 	"net/http"
-	"strconv"
 	"time"
 
 	"github.com/go-chi/chi/v5"
@@ -47,42 +47,38 @@ type CreateApiApplicationRequest struct {
 
 // ApiApplicationListResponse API应用列表响应结构
 type ApiApplicationListResponse struct {
-	List  []models.ApiApplication `json:"list"`
-	Total int64                   `json:"total"`
-	Page  int                     `json:"page"`
-	Size  int                     `json:"size"`
+	List []models.ApiApplication `json:"list"`
+	models.PageMeta
 }
 
 // ApiRateLimitListResponse API限流规则列表响应结构
 type ApiRateLimitListResponse struct {
-	List  []models.ApiRateLimit `json:"list"`
-	Total int64                 `json:"total"`
-	Page  int                   `json:"page"`
-	Size  int                   `json:"size"`
+	List []models.ApiRateLimit `json:"list"`
+	models.PageMeta
 }
 
 // DataSubscriptionListResponse 数据订阅列表响应结构
 type DataSubscriptionListResponse struct {
-	List  []models.DataSubscription `json:"list"`
-	Total int64                     `json:"total"`
-	Page  int                       `json:"page"`
-	Size  int                       `json:"size"`
+	List []models.DataSubscription `json:"list"`
+	models.PageMeta
 }
 
 // DataAccessRequestListResponse 数据使用申请列表响应结构
 type DataAccessRequestListResponse struct {
-	List  []models.DataAccessRequest `json:"list"`
-	Total int64                      `json:"total"`
-	Page  int                        `json:"page"`
-	Size  int                        `json:"size"`
+	List []models.DataAccessRequest `json:"list"`
+	models.PageMeta
+}
+
+// DataAccessGrantListResponse 数据使用授权列表响应结构
+type DataAccessGrantListResponse struct {
+	List []models.DataAccessGrant `json:"list"`
+	models.PageMeta
 }
 
 // ApiUsageLogListResponse API使用日志列表响应结构
 type ApiUsageLogListResponse struct {
-	List  []models.ApiUsageLog `json:"list"`
-	Total int64                `json:"total"`
-	Page  int                  `json:"page"`
-	Size  int                  `json:"size"`
+	List []models.ApiUsageLog `json:"list"`
+	models.PageMeta
 }
 
 // === API应用管理 ===
@@ -94,14 +90,13 @@ type ApiUsageLogListResponse struct {
 // @Accept json
 // @Produce json
 // @Param app body CreateApiApplicationRequest true "API应用信息"
-// @Success 201 {object} APIResponse{data=models.ApiApplication} "创建成功"
-// @Failure 400 {object} APIResponse "请求参数错误"
-// @Failure 500 {object} APIResponse "服务器内部错误"
+// @Success 201 {object} APIResponse[models.ApiApplication] "创建成功"
+// @Failure 400 {object} APIResponse[any] "请求参数错误"
+// @Failure 500 {object} APIResponse[any] "服务器内部错误"
 // @Router /sharing/api-applications [post]
 func (c *SharingController) CreateApiApplication(w http.ResponseWriter, r *http.Request) {
 	var req CreateApiApplicationRequest
-	if err := render.DecodeJSON(r.Body, &req); err != nil {
-		render.JSON(w, r, BadRequestResponse("请求参数格式错误", err))
+	if !decodeAndValidate(w, r, &req) {
 		return
 	}
 
@@ -114,8 +109,8 @@ func (c *SharingController) CreateApiApplication(w http.ResponseWriter, r *http.
 		ContactPhone:      req.ContactPhone,
 	}
 
-	if err := c.sharingService.CreateApiApplication(app); err != nil {
-		render.JSON(w, r, InternalErrorResponse("创建API应用失败", err))
+	if err := c.sharingService.CreateApiApplication(r.Context(), app); err != nil {
+		render.JSON(w, r, MapErrorResponse("创建API应用失败", err))
 		return
 	}
 
@@ -134,33 +129,24 @@ func (c *SharingController) CreateApiApplication(w http.ResponseWriter, r *http.
 // @Param page query int false "页码" default(1)
 // @Param size query int false "每页数量" default(10)
 // @Param status query string false "应用状态"
-// @Success 200 {object} APIResponse{data=ApiApplicationListResponse} "获取成功"
-// @Failure 500 {object} APIResponse "服务器内部错误"
+// @Success 200 {object} APIResponse[ApiApplicationListResponse] "获取成功"
+// @Failure 500 {object} APIResponse[any] "服务器内部错误"
 // @Router /sharing/api-applications [get]
 func (c *SharingController) GetApiApplications(w http.ResponseWriter, r *http.Request) {
-	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
-	if page <= 0 {
-		page = 1
-	}
-	size, _ := strconv.Atoi(r.URL.Query().Get("size"))
-	if size <= 0 {
-		size = 10
-	}
+	page, size := parsePagination(r)
 
 	status := r.URL.Query().Get("status")
 
-	apps, total, err := c.sharingService.GetApiApplications(page, size, status)
+	apps, total, err := c.sharingService.GetApiApplications(r.Context(), page, size, status)
 	if err != nil {
-		render.JSON(w, r, InternalErrorResponse("获取API应用列表失败", err))
+		render.JSON(w, r, MapErrorResponse("获取API应用列表失败", err))
 		return
 	}
 
 	// 构建响应
 	response := ApiApplicationListResponse{
-		List:  apps,
-		Total: total,
-		Page:  page,
-		Size:  size,
+		List:     apps,
+		PageMeta: models.NewPageMeta(total, page, size),
 	}
 
 	render.JSON(w, r, SuccessResponse("获取API应用列表成功", response))
@@ -173,16 +159,16 @@ func (c *SharingController) GetApiApplications(w http.ResponseWriter, r *http.Re
 // @Accept json
 // @Produce json
 // @Param id path string true "应用ID"
-// @Success 200 {object} APIResponse{data=models.ApiApplication} "获取成功"
-// @Failure 404 {object} APIResponse "应用不存在"
-// @Failure 500 {object} APIResponse "服务器内部错误"
+// @Success 200 {object} APIResponse[models.ApiApplication] "获取成功"
+// @Failure 404 {object} APIResponse[any] "应用不存在"
+// @Failure 500 {object} APIResponse[any] "服务器内部错误"
 // @Router /sharing/api-applications/{id} [get]
 func (c *SharingController) GetApiApplicationByID(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 
-	app, err := c.sharingService.GetApiApplicationByID(id)
+	app, err := c.sharingService.GetApiApplicationByID(r.Context(), id)
 	if err != nil {
-		render.JSON(w, r, InternalErrorResponse("API应用不存在", err))
+		render.JSON(w, r, MapErrorResponse("获取API应用失败", err))
 		return
 	}
 
@@ -197,22 +183,21 @@ func (c *SharingController) GetApiApplicationByID(w http.ResponseWriter, r *http
 // @Produce json
 // @Param id path string true "应用ID"
 // @Param updates body map[string]interface{} true "更新信息"
-// @Success 200 {object} APIResponse "更新成功"
-// @Failure 400 {object} APIResponse "请求参数错误"
-// @Failure 404 {object} APIResponse "应用不存在"
-// @Failure 500 {object} APIResponse "服务器内部错误"
+// @Success 200 {object} APIResponse[any] "更新成功"
+// @Failure 400 {object} APIResponse[any] "请求参数错误"
+// @Failure 404 {object} APIResponse[any] "应用不存在"
+// @Failure 500 {object} APIResponse[any] "服务器内部错误"
 // @Router /sharing/api-applications/{id} [put]
 func (c *SharingController) UpdateApiApplication(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 
 	var updates map[string]interface{}
-	if err := render.DecodeJSON(r.Body, &updates); err != nil {
-		render.JSON(w, r, BadRequestResponse("请求参数格式错误", err))
+	if !decodeAndValidate(w, r, &updates) {
 		return
 	}
 
-	if err := c.sharingService.UpdateApiApplication(id, updates); err != nil {
-		render.JSON(w, r, InternalErrorResponse("更新API应用失败", err))
+	if err := c.sharingService.UpdateApiApplication(r.Context(), id, updates); err != nil {
+		render.JSON(w, r, MapErrorResponse("更新API应用失败", err))
 		return
 	}
 
@@ -226,15 +211,15 @@ func (c *SharingController) UpdateApiApplication(w http.ResponseWriter, r *http.
 // @Accept json
 // @Produce json
 // @Param id path string true "应用ID"
-// @Success 200 {object} APIResponse "删除成功"
-// @Failure 404 {object} APIResponse "应用不存在"
-// @Failure 500 {object} APIResponse "服务器内部错误"
+// @Success 200 {object} APIResponse[any] "删除成功"
+// @Failure 404 {object} APIResponse[any] "应用不存在"
+// @Failure 500 {object} APIResponse[any] "服务器内部错误"
 // @Router /sharing/api-applications/{id} [delete]
 func (c *SharingController) DeleteApiApplication(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 
-	if err := c.sharingService.DeleteApiApplication(id); err != nil {
-		render.JSON(w, r, InternalErrorResponse("删除API应用失败", err))
+	if err := c.sharingService.DeleteApiApplication(r.Context(), id); err != nil {
+		render.JSON(w, r, MapErrorResponse("删除API应用失败", err))
 		return
 	}
 
@@ -259,14 +244,13 @@ type CreateApiRateLimitRequest struct {
 // @Accept json
 // @Produce json
 // @Param limit body CreateApiRateLimitRequest true "API限流规则信息"
-// @Success 201 {object} APIResponse{data=models.ApiRateLimit} "创建成功"
-// @Failure 400 {object} APIResponse "请求参数错误"
-// @Failure 500 {object} APIResponse "服务器内部错误"
+// @Success 201 {object} APIResponse[models.ApiRateLimit] "创建成功"
+// @Failure 400 {object} APIResponse[any] "请求参数错误"
+// @Failure 500 {object} APIResponse[any] "服务器内部错误"
 // @Router /sharing/api-rate-limits [post]
 func (c *SharingController) CreateApiRateLimit(w http.ResponseWriter, r *http.Request) {
 	var req CreateApiRateLimitRequest
-	if err := render.DecodeJSON(r.Body, &req); err != nil {
-		render.JSON(w, r, BadRequestResponse("请求参数格式错误", err))
+	if !decodeAndValidate(w, r, &req) {
 		return
 	}
 
@@ -280,7 +264,7 @@ func (c *SharingController) CreateApiRateLimit(w http.ResponseWriter, r *http.Re
 	}
 
 	if err := c.sharingService.CreateApiRateLimit(limit); err != nil {
-		render.JSON(w, r, InternalErrorResponse("创建API限流规则失败: "+err.Error(), err))
+		render.JSON(w, r, MapErrorResponse("创建API限流规则失败: "+err.Error(), err))
 		return
 	}
 
@@ -297,34 +281,25 @@ func (c *SharingController) CreateApiRateLimit(w http.ResponseWriter, r *http.Re
 // @Param size query int false "每页数量" default(10)
 // @Param rate_limit_type query string false "限流类型：global/api_key/application"
 // @Param target_id query string false "目标ID（api_key_id或application_id）"
-// @Success 200 {object} APIResponse{data=ApiRateLimitListResponse} "获取成功"
-// @Failure 500 {object} APIResponse "服务器内部错误"
+// @Success 200 {object} APIResponse[ApiRateLimitListResponse] "获取成功"
+// @Failure 500 {object} APIResponse[any] "服务器内部错误"
 // @Router /sharing/api-rate-limits [get]
 func (c *SharingController) GetApiRateLimits(w http.ResponseWriter, r *http.Request) {
-	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
-	if page <= 0 {
-		page = 1
-	}
-	size, _ := strconv.Atoi(r.URL.Query().Get("size"))
-	if size <= 0 {
-		size = 10
-	}
+	page, size := parsePagination(r)
 
 	rateLimitType := r.URL.Query().Get("rate_limit_type")
 	targetID := r.URL.Query().Get("target_id")
 
 	limits, total, err := c.sharingService.GetApiRateLimits(page, size, rateLimitType, targetID)
 	if err != nil {
-		render.JSON(w, r, InternalErrorResponse("获取API限流规则列表失败", err))
+		render.JSON(w, r, MapErrorResponse("获取API限流规则列表失败", err))
 		return
 	}
 
 	// 构建响应
 	response := ApiRateLimitListResponse{
-		List:  limits,
-		Total: total,
-		Page:  page,
-		Size:  size,
+		List:     limits,
+		PageMeta: models.NewPageMeta(total, page, size),
 	}
 
 	render.JSON(w, r, SuccessResponse("获取API限流规则列表成功", response))
@@ -338,22 +313,21 @@ func (c *SharingController) GetApiRateLimits(w http.ResponseWriter, r *http.Requ
 // @Produce json
 // @Param id path string true "规则ID"
 // @Param updates body map[string]interface{} true "更新信息"
-// @Success 200 {object} APIResponse "更新成功"
-// @Failure 400 {object} APIResponse "请求参数错误"
-// @Failure 404 {object} APIResponse "规则不存在"
-// @Failure 500 {object} APIResponse "服务器内部错误"
+// @Success 200 {object} APIResponse[any] "更新成功"
+// @Failure 400 {object} APIResponse[any] "请求参数错误"
+// @Failure 404 {object} APIResponse[any] "规则不存在"
+// @Failure 500 {object} APIResponse[any] "服务器内部错误"
 // @Router /sharing/api-rate-limits/{id} [put]
 func (c *SharingController) UpdateApiRateLimit(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 
 	var updates map[string]interface{}
-	if err := render.DecodeJSON(r.Body, &updates); err != nil {
-		render.JSON(w, r, BadRequestResponse("请求参数格式错误", err))
+	if !decodeAndValidate(w, r, &updates) {
 		return
 	}
 
 	if err := c.sharingService.UpdateApiRateLimit(id, updates); err != nil {
-		render.JSON(w, r, InternalErrorResponse("更新API限流规则失败", err))
+		render.JSON(w, r, MapErrorResponse("更新API限流规则失败", err))
 		return
 	}
 
@@ -367,15 +341,15 @@ func (c *SharingController) UpdateApiRateLimit(w http.ResponseWriter, r *http.Re
 // @Accept json
 // @Produce json
 // @Param id path string true "规则ID"
-// @Success 200 {object} APIResponse "删除成功"
-// @Failure 404 {object} APIResponse "规则不存在"
-// @Failure 500 {object} APIResponse "服务器内部错误"
+// @Success 200 {object} APIResponse[any] "删除成功"
+// @Failure 404 {object} APIResponse[any] "规则不存在"
+// @Failure 500 {object} APIResponse[any] "服务器内部错误"
 // @Router /sharing/api-rate-limits/{id} [delete]
 func (c *SharingController) DeleteApiRateLimit(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 
 	if err := c.sharingService.DeleteApiRateLimit(id); err != nil {
-		render.JSON(w, r, InternalErrorResponse("删除API限流规则失败", err))
+		render.JSON(w, r, MapErrorResponse("删除API限流规则失败", err))
 		return
 	}
 
@@ -391,19 +365,23 @@ func (c *SharingController) DeleteApiRateLimit(w http.ResponseWriter, r *http.Re
 // @Accept json
 // @Produce json
 // @Param subscription body models.DataSubscription true "数据订阅信息"
-// @Success 201 {object} APIResponse{data=models.DataSubscription} "创建成功"
-// @Failure 400 {object} APIResponse "请求参数错误"
-// @Failure 500 {object} APIResponse "服务器内部错误"
+// @Success 201 {object} APIResponse[models.DataSubscription] "创建成功"
+// @Failure 400 {object} APIResponse[any] "请求参数错误"
+// @Failure 409 {object} APIResponse[any] "资源已弃用"
+// @Failure 500 {object} APIResponse[any] "服务器内部错误"
 // @Router /sharing/data-subscriptions [post]
 func (c *SharingController) CreateDataSubscription(w http.ResponseWriter, r *http.Request) {
 	var subscription models.DataSubscription
-	if err := render.DecodeJSON(r.Body, &subscription); err != nil {
-		render.JSON(w, r, BadRequestResponse("请求参数格式错误", err))
+	if !decodeAndValidate(w, r, &subscription) {
 		return
 	}
 
 	if err := c.sharingService.CreateDataSubscription(&subscription); err != nil {
-		render.JSON(w, r, InternalErrorResponse("创建数据订阅失败", err))
+		if errors.Is(err, sharing.ErrResourceDeprecated) {
+			render.JSON(w, r, ConflictResponse("创建数据订阅失败: "+err.Error(), err))
+			return
+		}
+		render.JSON(w, r, MapErrorResponse("创建数据订阅失败", err))
 		return
 	}
 
@@ -421,18 +399,11 @@ func (c *SharingController) CreateDataSubscription(w http.ResponseWriter, r *htt
 // @Param subscriber_id query string false "订阅者ID"
 // @Param resource_type query string false "资源类型"
 // @Param status query string false "订阅状态"
-// @Success 200 {object} APIResponse{data=DataSubscriptionListResponse} "获取成功"
-// @Failure 500 {object} APIResponse "服务器内部错误"
+// @Success 200 {object} APIResponse[DataSubscriptionListResponse] "获取成功"
+// @Failure 500 {object} APIResponse[any] "服务器内部错误"
 // @Router /sharing/data-subscriptions [get]
 func (c *SharingController) GetDataSubscriptions(w http.ResponseWriter, r *http.Request) {
-	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
-	if page <= 0 {
-		page = 1
-	}
-	size, _ := strconv.Atoi(r.URL.Query().Get("size"))
-	if size <= 0 {
-		size = 10
-	}
+	page, size := parsePagination(r)
 
 	subscriberID := r.URL.Query().Get("subscriber_id")
 	resourceType := r.URL.Query().Get("resource_type")
@@ -440,16 +411,14 @@ func (c *SharingController) GetDataSubscriptions(w http.ResponseWriter, r *http.
 
 	subscriptions, total, err := c.sharingService.GetDataSubscriptions(page, size, subscriberID, resourceType, status)
 	if err != nil {
-		render.JSON(w, r, InternalErrorResponse("获取数据订阅列表失败", err))
+		render.JSON(w, r, MapErrorResponse("获取数据订阅列表失败", err))
 		return
 	}
 
 	// 构建响应
 	response := DataSubscriptionListResponse{
-		List:  subscriptions,
-		Total: total,
-		Page:  page,
-		Size:  size,
+		List:     subscriptions,
+		PageMeta: models.NewPageMeta(total, page, size),
 	}
 
 	render.JSON(w, r, SuccessResponse("获取数据订阅列表成功", response))
@@ -462,16 +431,16 @@ func (c *SharingController) GetDataSubscriptions(w http.ResponseWriter, r *http.
 // @Accept json
 // @Produce json
 // @Param id path string true "订阅ID"
-// @Success 200 {object} APIResponse{data=models.DataSubscription} "获取成功"
-// @Failure 404 {object} APIResponse "订阅不存在"
-// @Failure 500 {object} APIResponse "服务器内部错误"
+// @Success 200 {object} APIResponse[models.DataSubscription] "获取成功"
+// @Failure 404 {object} APIResponse[any] "订阅不存在"
+// @Failure 500 {object} APIResponse[any] "服务器内部错误"
 // @Router /sharing/data-subscriptions/{id} [get]
 func (c *SharingController) GetDataSubscriptionByID(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 
 	subscription, err := c.sharingService.GetDataSubscriptionByID(id)
 	if err != nil {
-		render.JSON(w, r, InternalErrorResponse("数据订阅不存在", err))
+		render.JSON(w, r, MapErrorResponse("获取数据订阅失败", err))
 		return
 	}
 
@@ -486,22 +455,21 @@ func (c *SharingController) GetDataSubscriptionByID(w http.ResponseWriter, r *ht
 // @Produce json
 // @Param id path string true "订阅ID"
 // @Param updates body map[string]interface{} true "更新信息"
-// @Success 200 {object} APIResponse "更新成功"
-// @Failure 400 {object} APIResponse "请求参数错误"
-// @Failure 404 {object} APIResponse "订阅不存在"
-// @Failure 500 {object} APIResponse "服务器内部错误"
+// @Success 200 {object} APIResponse[any] "更新成功"
+// @Failure 400 {object} APIResponse[any] "请求参数错误"
+// @Failure 404 {object} APIResponse[any] "订阅不存在"
+// @Failure 500 {object} APIResponse[any] "服务器内部错误"
 // @Router /sharing/data-subscriptions/{id} [put]
 func (c *SharingController) UpdateDataSubscription(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 
 	var updates map[string]interface{}
-	if err := render.DecodeJSON(r.Body, &updates); err != nil {
-		render.JSON(w, r, BadRequestResponse("请求参数格式错误", err))
+	if !decodeAndValidate(w, r, &updates) {
 		return
 	}
 
 	if err := c.sharingService.UpdateDataSubscription(id, updates); err != nil {
-		render.JSON(w, r, InternalErrorResponse("更新数据订阅失败", err))
+		render.JSON(w, r, MapErrorResponse("更新数据订阅失败", err))
 		return
 	}
 
@@ -515,15 +483,15 @@ func (c *SharingController) UpdateDataSubscription(w http.ResponseWriter, r *htt
 // @Accept json
 // @Produce json
 // @Param id path string true "订阅ID"
-// @Success 200 {object} APIResponse "删除成功"
-// @Failure 404 {object} APIResponse "订阅不存在"
-// @Failure 500 {object} APIResponse "服务器内部错误"
+// @Success 200 {object} APIResponse[any] "删除成功"
+// @Failure 404 {object} APIResponse[any] "订阅不存在"
+// @Failure 500 {object} APIResponse[any] "服务器内部错误"
 // @Router /sharing/data-subscriptions/{id} [delete]
 func (c *SharingController) DeleteDataSubscription(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 
 	if err := c.sharingService.DeleteDataSubscription(id); err != nil {
-		render.JSON(w, r, InternalErrorResponse("删除数据订阅失败", err))
+		render.JSON(w, r, MapErrorResponse("删除数据订阅失败", err))
 		return
 	}
 
@@ -534,24 +502,30 @@ func (c *SharingController) DeleteDataSubscription(w http.ResponseWriter, r *htt
 
 // CreateDataAccessRequest 创建数据使用申请
 // @Summary 创建数据使用申请
-// @Description 创建新的数据使用申请
+// @Description 创建新的数据使用申请，必须填写使用目的(purpose)和使用期限(duration_days，1-365天)，申请人为空时取当前用户
 // @Tags 数据共享服务
 // @Accept json
 // @Produce json
 // @Param request body models.DataAccessRequest true "数据使用申请信息"
-// @Success 201 {object} APIResponse{data=models.DataAccessRequest} "创建成功"
-// @Failure 400 {object} APIResponse "请求参数错误"
-// @Failure 500 {object} APIResponse "服务器内部错误"
+// @Success 201 {object} APIResponse[models.DataAccessRequest] "创建成功"
+// @Failure 400 {object} APIResponse[any] "请求参数错误"
+// @Failure 500 {object} APIResponse[any] "服务器内部错误"
 // @Router /sharing/data-access-requests [post]
 func (c *SharingController) CreateDataAccessRequest(w http.ResponseWriter, r *http.Request) {
 	var request models.DataAccessRequest
-	if err := render.DecodeJSON(r.Body, &request); err != nil {
-		render.JSON(w, r, BadRequestResponse("请求参数格式错误", err))
+	if !decodeAndValidate(w, r, &request) {
 		return
 	}
+	if request.RequesterID == "" {
+		request.RequesterID = getCurrentUsername(r)
+	}
 
 	if err := c.sharingService.CreateDataAccessRequest(&request); err != nil {
-		render.JSON(w, r, InternalErrorResponse("创建数据使用申请失败", err))
+		if errors.Is(err, sharing.ErrInvalidAccessRequest) {
+			render.JSON(w, r, BadRequestResponse("创建数据使用申请失败: "+err.Error(), err))
+			return
+		}
+		render.JSON(w, r, MapErrorResponse("创建数据使用申请失败", err))
 		return
 	}
 
@@ -569,18 +543,11 @@ func (c *SharingController) CreateDataAccessRequest(w http.ResponseWriter, r *ht
 // @Param requester_id query string false "申请人ID"
 // @Param resource_type query string false "资源类型"
 // @Param status query string false "申请状态"
-// @Success 200 {object} APIResponse{data=DataAccessRequestListResponse} "获取成功"
-// @Failure 500 {object} APIResponse "服务器内部错误"
+// @Success 200 {object} APIResponse[DataAccessRequestListResponse] "获取成功"
+// @Failure 500 {object} APIResponse[any] "服务器内部错误"
 // @Router /sharing/data-access-requests [get]
 func (c *SharingController) GetDataAccessRequests(w http.ResponseWriter, r *http.Request) {
-	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
-	if page <= 0 {
-		page = 1
-	}
-	size, _ := strconv.Atoi(r.URL.Query().Get("size"))
-	if size <= 0 {
-		size = 10
-	}
+	page, size := parsePagination(r)
 
 	requesterID := r.URL.Query().Get("requester_id")
 	resourceType := r.URL.Query().Get("resource_type")
@@ -588,16 +555,14 @@ func (c *SharingController) GetDataAccessRequests(w http.ResponseWriter, r *http
 
 	requests, total, err := c.sharingService.GetDataAccessRequests(page, size, requesterID, resourceType, status)
 	if err != nil {
-		render.JSON(w, r, InternalErrorResponse("获取数据使用申请列表失败", err))
+		render.JSON(w, r, MapErrorResponse("获取数据使用申请列表失败", err))
 		return
 	}
 
 	// 构建响应
 	response := DataAccessRequestListResponse{
-		List:  requests,
-		Total: total,
-		Page:  page,
-		Size:  size,
+		List:     requests,
+		PageMeta: models.NewPageMeta(total, page, size),
 	}
 
 	render.JSON(w, r, SuccessResponse("获取数据使用申请列表成功", response))
@@ -610,16 +575,16 @@ func (c *SharingController) GetDataAccessRequests(w http.ResponseWriter, r *http
 // @Accept json
 // @Produce json
 // @Param id path string true "申请ID"
-// @Success 200 {object} APIResponse{data=models.DataAccessRequest} "获取成功"
-// @Failure 404 {object} APIResponse "申请不存在"
-// @Failure 500 {object} APIResponse "服务器内部错误"
+// @Success 200 {object} APIResponse[models.DataAccessRequest] "获取成功"
+// @Failure 404 {object} APIResponse[any] "申请不存在"
+// @Failure 500 {object} APIResponse[any] "服务器内部错误"
 // @Router /sharing/data-access-requests/{id} [get]
 func (c *SharingController) GetDataAccessRequestByID(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 
 	request, err := c.sharingService.GetDataAccessRequestByID(id)
 	if err != nil {
-		render.JSON(w, r, InternalErrorResponse("数据使用申请不存在", err))
+		render.JSON(w, r, MapErrorResponse("获取数据使用申请失败", err))
 		return
 	}
 
@@ -634,35 +599,157 @@ type ApproveDataAccessRequestRequest struct {
 
 // ApproveDataAccessRequest 审批数据使用申请
 // @Summary 审批数据使用申请
-// @Description 审批数据使用申请
+// @Description 审批待审批的数据使用申请，通过时按申请的使用期限生成绑定使用目的的授权并返回，到期自动回收；驳回时data为空
 // @Tags 数据共享服务
 // @Accept json
 // @Produce json
 // @Param id path string true "申请ID"
 // @Param approval body ApproveDataAccessRequestRequest true "审批信息"
-// @Success 200 {object} APIResponse "审批成功"
-// @Failure 400 {object} APIResponse "请求参数错误"
-// @Failure 404 {object} APIResponse "申请不存在"
-// @Failure 500 {object} APIResponse "服务器内部错误"
+// @Success 200 {object} APIResponse[models.DataAccessGrant] "审批成功"
+// @Failure 400 {object} APIResponse[any] "请求参数错误"
+// @Failure 404 {object} APIResponse[any] "申请不存在"
+// @Failure 409 {object} APIResponse[any] "申请不是待审批状态"
+// @Failure 500 {object} APIResponse[any] "服务器内部错误"
 // @Router /sharing/data-access-requests/{id}/approve [post]
 func (c *SharingController) ApproveDataAccessRequest(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 
 	var req ApproveDataAccessRequestRequest
-	if err := render.DecodeJSON(r.Body, &req); err != nil {
-		render.JSON(w, r, BadRequestResponse("请求参数格式错误", err))
+	if !decodeAndValidate(w, r, &req) {
+		return
+	}
+
+	grant, err := c.sharingService.ApproveDataAccessRequest(r.Context(), id, getCurrentUsername(r), req.Approved, req.Comment)
+	if err != nil {
+		respondAccessGrantError(w, r, "审批数据使用申请失败", err)
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("审批数据使用申请成功", grant))
+}
+
+// GetDataAccessGrants 查询数据使用授权
+// @Summary 查询数据使用授权
+// @Description 按申请人、资源、状态、使用目的和生效时段分页查询数据使用授权，包含已到期和已撤销的授权，供合规审计使用；start_time、end_time返回该时段内生效过的授权
+// @Tags 数据共享服务
+// @Produce json
+// @Param page query int false "页码" default(1)
+// @Param size query int false "每页数量" default(10)
+// @Param requester_id query string false "申请人ID"
+// @Param resource_type query string false "资源类型"
+// @Param resource_id query string false "资源ID"
+// @Param status query string false "授权状态(active/expired/revoked)"
+// @Param purpose query string false "使用目的（模糊匹配）"
+// @Param start_time query string false "时段开始（RFC3339格式）"
+// @Param end_time query string false "时段结束（RFC3339格式）"
+// @Success 200 {object} APIResponse[DataAccessGrantListResponse] "获取成功"
+// @Failure 400 {object} APIResponse[any] "请求参数错误"
+// @Failure 500 {object} APIResponse[any] "服务器内部错误"
+// @Router /sharing/data-access-grants [get]
+func (c *SharingController) GetDataAccessGrants(w http.ResponseWriter, r *http.Request) {
+	page, size := parsePagination(r)
+	query := sharing.DataAccessGrantQuery{
+		RequesterID:  r.URL.Query().Get("requester_id"),
+		ResourceType: r.URL.Query().Get("resource_type"),
+		ResourceID:   r.URL.Query().Get("resource_id"),
+		Status:       r.URL.Query().Get("status"),
+		Purpose:      r.URL.Query().Get("purpose"),
+		Page:         page,
+		Size:         size,
+	}
+	if value := r.URL.Query().Get("start_time"); value != "" {
+		t, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			render.JSON(w, r, BadRequestResponse("start_time格式错误，应为RFC3339格式", err))
+			return
+		}
+		query.From = &t
+	}
+	if value := r.URL.Query().Get("end_time"); value != "" {
+		t, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			render.JSON(w, r, BadRequestResponse("end_time格式错误，应为RFC3339格式", err))
+			return
+		}
+		query.To = &t
+	}
+
+	grants, total, err := c.sharingService.ListDataAccessGrants(r.Context(), query)
+	if err != nil {
+		render.JSON(w, r, InternalErrorResponse("查询数据使用授权失败", err))
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("查询数据使用授权成功", DataAccessGrantListResponse{
+		List:     grants,
+		PageMeta: models.NewPageMeta(total, page, size),
+	}))
+}
+
+// GetDataAccessGrantByID 获取数据使用授权
+// @Summary 获取数据使用授权
+// @Description 根据ID获取数据使用授权详情
+// @Tags 数据共享服务
+// @Produce json
+// @Param id path string true "授权ID"
+// @Success 200 {object} APIResponse[models.DataAccessGrant] "获取成功"
+// @Failure 404 {object} APIResponse[any] "授权不存在"
+// @Failure 500 {object} APIResponse[any] "服务器内部错误"
+// @Router /sharing/data-access-grants/{id} [get]
+func (c *SharingController) GetDataAccessGrantByID(w http.ResponseWriter, r *http.Request) {
+	grant, err := c.sharingService.GetDataAccessGrant(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		render.JSON(w, r, MapErrorResponse("获取数据使用授权失败", err))
 		return
 	}
 
-	// TODO: 从认证信息中获取审批人ID
-	approverID := "system" // 临时使用系统ID
+	render.JSON(w, r, SuccessResponse("获取数据使用授权成功", grant))
+}
+
+// RevokeDataAccessGrantRequest 撤销数据使用授权请求结构
+type RevokeDataAccessGrantRequest struct {
+	Reason string `json:"reason" validate:"required,max=500" example:"项目提前结束"`
+}
+
+// RevokeDataAccessGrant 撤销数据使用授权
+// @Summary 撤销数据使用授权
+// @Description 在到期前撤销生效中的数据使用授权，对应申请记为expired，授权记录保留
+// @Tags 数据共享服务
+// @Accept json
+// @Produce json
+// @Param id path string true "授权ID"
+// @Param request body RevokeDataAccessGrantRequest true "撤销原因"
+// @Success 200 {object} APIResponse[models.DataAccessGrant] "撤销成功"
+// @Failure 400 {object} APIResponse[any] "请求参数错误"
+// @Failure 404 {object} APIResponse[any] "授权不存在"
+// @Failure 409 {object} APIResponse[any] "授权已到期或已撤销"
+// @Failure 500 {object} APIResponse[any] "服务器内部错误"
+// @Router /sharing/data-access-grants/{id}/revoke [post]
+func (c *SharingController) RevokeDataAccessGrant(w http.ResponseWriter, r *http.Request) {
+	var req RevokeDataAccessGrantRequest
+	if !decodeAndValidate(w, r, &req) {
+		return
+	}
 
-	if err := c.sharingService.ApproveDataAccessRequest(id, approverID, req.Approved, req.Comment); err != nil {
-		render.JSON(w, r, InternalErrorResponse("审批数据使用申请失败", err))
+	grant, err := c.sharingService.RevokeDataAccessGrant(r.Context(), chi.URLParam(r, "id"), getCurrentUsername(r), req.Reason)
+	if err != nil {
+		respondAccessGrantError(w, r, "撤销数据使用授权失败", err)
 		return
 	}
 
-	render.JSON(w, r, SuccessResponse("审批数据使用申请成功", nil))
+	render.JSON(w, r, SuccessResponse("撤销数据使用授权成功", grant))
+}
+
+// respondAccessGrantError 按错误类型返回数据使用审批和授权操作的错误响应
+func respondAccessGrantError(w http.ResponseWriter, r *http.Request, msg string, err error) {
+	switch {
+	case errors.Is(err, sharing.ErrInvalidAccessRequest):
+		render.JSON(w, r, BadRequestResponse(msg+": "+err.Error(), err))
+	case errors.Is(err, sharing.ErrAccessRequestNotPending), errors.Is(err, sharing.ErrAccessGrantNotActive):
+		render.JSON(w, r, ConflictResponse(msg+": "+err.Error(), err))
+	default:
+		render.JSON(w, r, MapErrorResponse(msg, err))
+	}
 }
 
 // === API使用日志管理 ===
@@ -679,18 +766,11 @@ func (c *SharingController) ApproveDataAccessRequest(w http.ResponseWriter, r *h
 // @Param user_id query string false "用户ID"
 // @Param start_time query string false "开始时间"
 // @Param end_time query string false "结束时间"
-// @Success 200 {object} APIResponse{data=ApiUsageLogListResponse} "获取成功"
-// @Failure 500 {object} APIResponse "服务器内部错误"
+// @Success 200 {object} APIResponse[ApiUsageLogListResponse] "获取成功"
+// @Failure 500 {object} APIResponse[any] "服务器内部错误"
 // @Router /sharing/api-usage-logs [get]
 func (c *SharingController) GetApiUsageLogs(w http.ResponseWriter, r *http.Request) {
-	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
-	if page <= 0 {
-		page = 1
-	}
-	size, _ := strconv.Atoi(r.URL.Query().Get("size"))
-	if size <= 0 {
-		size = 10
-	}
+	page, size := parsePagination(r)
 
 	applicationID := r.URL.Query().Get("application_id")
 	userID := r.URL.Query().Get("user_id")
@@ -709,16 +789,14 @@ func (c *SharingController) GetApiUsageLogs(w http.ResponseWriter, r *http.Reque
 
 	logs, total, err := c.sharingService.GetApiUsageLogs(page, size, applicationID, userID, startTime, endTime)
 	if err != nil {
-		render.JSON(w, r, InternalErrorResponse("获取API使用日志列表失败", err))
+		render.JSON(w, r, MapErrorResponse("获取API使用日志列表失败", err))
 		return
 	}
 
 	// 构建响应
 	response := ApiUsageLogListResponse{
-		List:  logs,
-		Total: total,
-		Page:  page,
-		Size:  size,
+		List:     logs,
+		PageMeta: models.NewPageMeta(total, page, size),
 	}
 
 	render.JSON(w, r, SuccessResponse("获取API使用日志列表成功", response))
@@ -731,6 +809,7 @@ type CreateApiKeyRequest struct {
 	Name           string     `json:"name" validate:"required"`
 	Description    string     `json:"description"`
 	ApplicationIDs []string   `json:"application_ids" validate:"required,min=1"` // 关联的应用ID列表
+	Scopes         []string   `json:"scopes"`                                    // 授权范围：share:read/ingest:write/*，默认share:read
 	ExpiresAt      *time.Time `json:"expires_at"`
 }
 
@@ -740,6 +819,21 @@ type CreateApiKeyResponse struct {
 	KeyValue string        `json:"key_value"` // 完整的Key值，仅返回一次
 }
 
+// RotateApiKeyRequest 轮换ApiKey请求结构
+type RotateApiKeyRequest struct {
+	GracePeriodHours int `json:"grace_period_hours"` // 旧Key宽限期（小时），0表示立即吊销
+}
+
+// RevokeApiKeyRequest 吊销ApiKey请求结构
+type RevokeApiKeyRequest struct {
+	Reason string `json:"reason"`
+}
+
+// UpdateApiKeyScopesRequest 更新ApiKey授权范围请求结构
+type UpdateApiKeyScopesRequest struct {
+	Scopes []string `json:"scopes" validate:"required,min=1"`
+}
+
 // UpdateApiKeyApplicationsRequest 更新ApiKey关联应用请求结构
 type UpdateApiKeyApplicationsRequest struct {
 	ApplicationIDs []string `json:"application_ids" validate:"required,min=1"` // 关联的应用ID列表
@@ -752,20 +846,19 @@ type UpdateApiKeyApplicationsRequest struct {
 // @Accept json
 // @Produce json
 // @Param key body CreateApiKeyRequest true "ApiKey信息"
-// @Success 201 {object} APIResponse{data=CreateApiKeyResponse} "创建成功"
-// @Failure 400 {object} APIResponse "请求参数错误"
-// @Failure 500 {object} APIResponse "服务器内部错误"
+// @Success 201 {object} APIResponse[CreateApiKeyResponse] "创建成功"
+// @Failure 400 {object} APIResponse[any] "请求参数错误"
+// @Failure 500 {object} APIResponse[any] "服务器内部错误"
 // @Router /sharing/api-keys [post]
 func (c *SharingController) CreateApiKey(w http.ResponseWriter, r *http.Request) {
 	var req CreateApiKeyRequest
-	if err := render.DecodeJSON(r.Body, &req); err != nil {
-		render.JSON(w, r, BadRequestResponse("请求参数格式错误", err))
+	if !decodeAndValidate(w, r, &req) {
 		return
 	}
 
-	apiKey, keyValue, err := c.sharingService.CreateApiKey(req.Name, req.Description, req.ApplicationIDs, req.ExpiresAt)
+	apiKey, keyValue, err := c.sharingService.CreateApiKey(req.Name, req.Description, req.ApplicationIDs, req.Scopes, req.ExpiresAt)
 	if err != nil {
-		render.JSON(w, r, InternalErrorResponse("生成API密钥失败: "+err.Error(), err))
+		render.JSON(w, r, MapErrorResponse("生成API密钥失败: "+err.Error(), err))
 		return
 	}
 
@@ -782,15 +875,15 @@ func (c *SharingController) CreateApiKey(w http.ResponseWriter, r *http.Request)
 // @Accept json
 // @Produce json
 // @Param app_id query string false "应用ID，用于过滤特定应用的ApiKey"
-// @Success 200 {object} APIResponse{data=[]models.ApiKey} "获取成功"
-// @Failure 500 {object} APIResponse "服务器内部错误"
+// @Success 200 {object} APIResponse[[]models.ApiKey] "获取成功"
+// @Failure 500 {object} APIResponse[any] "服务器内部错误"
 // @Router /sharing/api-keys [get]
 func (c *SharingController) GetApiKeys(w http.ResponseWriter, r *http.Request) {
 	appID := r.URL.Query().Get("app_id")
 
 	keys, err := c.sharingService.GetApiKeys(appID)
 	if err != nil {
-		render.JSON(w, r, InternalErrorResponse("获取API密钥列表失败", err))
+		render.JSON(w, r, MapErrorResponse("获取API密钥列表失败", err))
 		return
 	}
 
@@ -804,16 +897,16 @@ func (c *SharingController) GetApiKeys(w http.ResponseWriter, r *http.Request) {
 // @Accept json
 // @Produce json
 // @Param id path string true "密钥ID"
-// @Success 200 {object} APIResponse{data=models.ApiKey} "获取成功"
-// @Failure 404 {object} APIResponse "密钥不存在"
-// @Failure 500 {object} APIResponse "服务器内部错误"
+// @Success 200 {object} APIResponse[models.ApiKey] "获取成功"
+// @Failure 404 {object} APIResponse[any] "密钥不存在"
+// @Failure 500 {object} APIResponse[any] "服务器内部错误"
 // @Router /sharing/api-keys/{id} [get]
 func (c *SharingController) GetApiKeyByID(w http.ResponseWriter, r *http.Request) {
 	keyID := chi.URLParam(r, "id")
 
 	key, err := c.sharingService.GetApiKeyByID(keyID)
 	if err != nil {
-		render.JSON(w, r, InternalErrorResponse("API密钥不存在", err))
+		render.JSON(w, r, MapErrorResponse("获取API密钥失败", err))
 		return
 	}
 
@@ -828,21 +921,20 @@ func (c *SharingController) GetApiKeyByID(w http.ResponseWriter, r *http.Request
 // @Produce json
 // @Param id path string true "密钥ID"
 // @Param updates body map[string]interface{} true "更新信息"
-// @Success 200 {object} APIResponse "更新成功"
-// @Failure 400 {object} APIResponse "请求参数错误"
-// @Failure 500 {object} APIResponse "服务器内部错误"
+// @Success 200 {object} APIResponse[any] "更新成功"
+// @Failure 400 {object} APIResponse[any] "请求参数错误"
+// @Failure 500 {object} APIResponse[any] "服务器内部错误"
 // @Router /sharing/api-keys/{id} [put]
 func (c *SharingController) UpdateApiKey(w http.ResponseWriter, r *http.Request) {
 	keyID := chi.URLParam(r, "id")
 
 	var updates map[string]interface{}
-	if err := render.DecodeJSON(r.Body, &updates); err != nil {
-		render.JSON(w, r, BadRequestResponse("请求参数格式错误", err))
+	if !decodeAndValidate(w, r, &updates) {
 		return
 	}
 
 	if err := c.sharingService.UpdateApiKey(keyID, updates); err != nil {
-		render.JSON(w, r, InternalErrorResponse("更新API密钥失败", err))
+		render.JSON(w, r, MapErrorResponse("更新API密钥失败", err))
 		return
 	}
 
@@ -857,27 +949,123 @@ func (c *SharingController) UpdateApiKey(w http.ResponseWriter, r *http.Request)
 // @Produce json
 // @Param id path string true "密钥ID"
 // @Param applications body UpdateApiKeyApplicationsRequest true "关联应用信息"
-// @Success 200 {object} APIResponse "更新成功"
-// @Failure 400 {object} APIResponse "请求参数错误"
-// @Failure 500 {object} APIResponse "服务器内部错误"
+// @Success 200 {object} APIResponse[any] "更新成功"
+// @Failure 400 {object} APIResponse[any] "请求参数错误"
+// @Failure 500 {object} APIResponse[any] "服务器内部错误"
 // @Router /sharing/api-keys/{id}/applications [put]
 func (c *SharingController) UpdateApiKeyApplications(w http.ResponseWriter, r *http.Request) {
 	keyID := chi.URLParam(r, "id")
 
 	var req UpdateApiKeyApplicationsRequest
-	if err := render.DecodeJSON(r.Body, &req); err != nil {
-		render.JSON(w, r, BadRequestResponse("请求参数格式错误", err))
+	if !decodeAndValidate(w, r, &req) {
 		return
 	}
 
 	if err := c.sharingService.UpdateApiKeyApplications(keyID, req.ApplicationIDs); err != nil {
-		render.JSON(w, r, InternalErrorResponse("更新API密钥关联应用失败: "+err.Error(), err))
+		render.JSON(w, r, MapErrorResponse("更新API密钥关联应用失败: "+err.Error(), err))
 		return
 	}
 
 	render.JSON(w, r, SuccessResponse("更新API密钥关联应用成功", nil))
 }
 
+// RotateApiKey 轮换ApiKey
+// @Summary 轮换API密钥
+// @Description 生成继承名称、关联应用和授权范围的新Key，旧Key在宽限期后失效，返回新Key的完整值（仅此一次）
+// @Tags 数据共享服务
+// @Accept json
+// @Produce json
+// @Param id path string true "密钥ID"
+// @Param rotate body RotateApiKeyRequest false "轮换参数"
+// @Success 200 {object} APIResponse[CreateApiKeyResponse] "轮换成功"
+// @Failure 400 {object} APIResponse[any] "请求参数错误"
+// @Failure 500 {object} APIResponse[any] "服务器内部错误"
+// @Router /sharing/api-keys/{id}/rotate [post]
+func (c *SharingController) RotateApiKey(w http.ResponseWriter, r *http.Request) {
+	keyID := chi.URLParam(r, "id")
+
+	var req RotateApiKeyRequest
+	if r.ContentLength > 0 {
+		if !decodeAndValidate(w, r, &req) {
+			return
+		}
+	}
+
+	gracePeriod := time.Duration(req.GracePeriodHours) * time.Hour
+	apiKey, keyValue, err := c.sharingService.RotateApiKey(keyID, gracePeriod, getCurrentUsername(r))
+	if err != nil {
+		render.JSON(w, r, MapErrorResponse("轮换API密钥失败: "+err.Error(), err))
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("轮换API密钥成功", CreateApiKeyResponse{
+		ApiKey:   *apiKey,
+		KeyValue: keyValue,
+	}))
+}
+
+// RevokeApiKey 吊销ApiKey
+// @Summary 吊销API密钥
+// @Description 吊销ApiKey，保留记录用于审计，吊销后立即无法认证
+// @Tags 数据共享服务
+// @Accept json
+// @Produce json
+// @Param id path string true "密钥ID"
+// @Param revoke body RevokeApiKeyRequest false "吊销原因"
+// @Success 200 {object} APIResponse[any] "吊销成功"
+// @Failure 400 {object} APIResponse[any] "请求参数错误"
+// @Failure 500 {object} APIResponse[any] "服务器内部错误"
+// @Router /sharing/api-keys/{id}/revoke [post]
+func (c *SharingController) RevokeApiKey(w http.ResponseWriter, r *http.Request) {
+	keyID := chi.URLParam(r, "id")
+
+	var req RevokeApiKeyRequest
+	if r.ContentLength > 0 {
+		if !decodeAndValidate(w, r, &req) {
+			return
+		}
+	}
+
+	if err := c.sharingService.RevokeApiKey(keyID, getCurrentUsername(r), req.Reason); err != nil {
+		render.JSON(w, r, MapErrorResponse("吊销API密钥失败: "+err.Error(), err))
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("吊销API密钥成功", nil))
+}
+
+// UpdateApiKeyScopes 更新ApiKey授权范围
+// @Summary 更新API密钥授权范围
+// @Description 更新ApiKey的授权范围（share:read、ingest:write、*）
+// @Tags 数据共享服务
+// @Accept json
+// @Produce json
+// @Param id path string true "密钥ID"
+// @Param scopes body UpdateApiKeyScopesRequest true "授权范围"
+// @Success 200 {object} APIResponse[any] "更新成功"
+// @Failure 400 {object} APIResponse[any] "请求参数错误"
+// @Failure 500 {object} APIResponse[any] "服务器内部错误"
+// @Router /sharing/api-keys/{id}/scopes [put]
+func (c *SharingController) UpdateApiKeyScopes(w http.ResponseWriter, r *http.Request) {
+	keyID := chi.URLParam(r, "id")
+
+	var req UpdateApiKeyScopesRequest
+	if !decodeAndValidate(w, r, &req) {
+		return
+	}
+	if len(req.Scopes) == 0 {
+		render.JSON(w, r, BadRequestResponse("授权范围不能为空", nil))
+		return
+	}
+
+	if err := c.sharingService.UpdateApiKeyScopes(keyID, req.Scopes); err != nil {
+		render.JSON(w, r, MapErrorResponse("更新API密钥授权范围失败: "+err.Error(), err))
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("更新API密钥授权范围成功", nil))
+}
+
 // DeleteApiKey 吊销（删除）一个ApiKey
 // @Summary 删除API密钥
 // @Description 吊销（删除）一个ApiKey
@@ -885,14 +1073,14 @@ func (c *SharingController) UpdateApiKeyApplications(w http.ResponseWriter, r *h
 // @Accept json
 // @Produce json
 // @Param id path string true "密钥ID"
-// @Success 200 {object} APIResponse "删除成功"
-// @Failure 500 {object} APIResponse "服务器内部错误"
+// @Success 200 {object} APIResponse[any] "删除成功"
+// @Failure 500 {object} APIResponse[any] "服务器内部错误"
 // @Router /sharing/api-keys/{id} [delete]
 func (c *SharingController) DeleteApiKey(w http.ResponseWriter, r *http.Request) {
 	keyID := chi.URLParam(r, "id")
 
 	if err := c.sharingService.DeleteApiKey(keyID); err != nil {
-		render.JSON(w, r, InternalErrorResponse("删除API密钥失败", err))
+		render.JSON(w, r, MapErrorResponse("删除API密钥失败", err))
 		return
 	}
 
@@ -917,14 +1105,14 @@ type CreateApiInterfaceRequest struct {
 // @Accept json
 // @Produce json
 // @Param interface_param body CreateApiInterfaceRequest true "接口信息"
-// @Success 201 {object} APIResponse{data=models.ApiInterface} "创建成功"
-// @Failure 400 {object} APIResponse "请求参数错误"
-// @Failure 500 {object} APIResponse "服务器内部错误"
+// @Success 201 {object} APIResponse[models.ApiInterface] "创建成功"
+// @Failure 400 {object} APIResponse[any] "请求参数错误"
+// @Failure 409 {object} APIResponse[any] "主题接口未发布或已弃用"
+// @Failure 500 {object} APIResponse[any] "服务器内部错误"
 // @Router /sharing/api-interfaces [post]
 func (c *SharingController) CreateApiInterface(w http.ResponseWriter, r *http.Request) {
 	var req CreateApiInterfaceRequest
-	if err := render.DecodeJSON(r.Body, &req); err != nil {
-		render.JSON(w, r, BadRequestResponse("请求参数格式错误", err))
+	if !decodeAndValidate(w, r, &req) {
 		return
 	}
 
@@ -952,7 +1140,11 @@ func (c *SharingController) CreateApiInterface(w http.ResponseWriter, r *http.Re
 	}
 
 	if err := c.sharingService.CreateApiInterface(apiInterface); err != nil {
-		render.JSON(w, r, InternalErrorResponse("创建共享接口失败: "+err.Error(), err))
+		if errors.Is(err, sharing.ErrThematicInterfaceNotPublished) || errors.Is(err, sharing.ErrResourceDeprecated) {
+			render.JSON(w, r, ConflictResponse("创建共享接口失败: "+err.Error(), err))
+			return
+		}
+		render.JSON(w, r, MapErrorResponse("创建共享接口失败: "+err.Error(), err))
 		return
 	}
 
@@ -966,15 +1158,15 @@ func (c *SharingController) CreateApiInterface(w http.ResponseWriter, r *http.Re
 // @Accept json
 // @Produce json
 // @Param api_application_id query string false "应用ID"
-// @Success 200 {object} APIResponse{data=[]models.ApiInterface} "获取成功"
-// @Failure 500 {object} APIResponse "服务器内部错误"
+// @Success 200 {object} APIResponse[[]models.ApiInterface] "获取成功"
+// @Failure 500 {object} APIResponse[any] "服务器内部错误"
 // @Router /sharing/api-interfaces [get]
 func (c *SharingController) GetApiInterfaces(w http.ResponseWriter, r *http.Request) {
 	appID := r.URL.Query().Get("api_application_id")
 
 	interfaces, err := c.sharingService.GetApiInterfaces(appID)
 	if err != nil {
-		render.JSON(w, r, InternalErrorResponse("获取共享接口列表失败", err))
+		render.JSON(w, r, MapErrorResponse("获取共享接口列表失败", err))
 		return
 	}
 
@@ -988,19 +1180,20 @@ func (c *SharingController) GetApiInterfaces(w http.ResponseWriter, r *http.Requ
 // @Accept json
 // @Produce json
 // @Param id path string true "接口ID"
-// @Success 200 {object} APIResponse{data=models.ApiInterface} "获取成功"
-// @Failure 404 {object} APIResponse "接口不存在"
-// @Failure 500 {object} APIResponse "服务器内部错误"
+// @Success 200 {object} APIResponse[models.ApiInterface] "获取成功"
+// @Failure 404 {object} APIResponse[any] "接口不存在"
+// @Failure 500 {object} APIResponse[any] "服务器内部错误"
 // @Router /sharing/api-interfaces/{id} [get]
 func (c *SharingController) GetApiInterfaceByID(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 
 	apiInterface, err := c.sharingService.GetApiInterfaceByID(id)
 	if err != nil {
-		render.JSON(w, r, InternalErrorResponse("获取共享接口失败", err))
+		render.JSON(w, r, MapErrorResponse("获取共享接口失败", err))
 		return
 	}
 
+	setETag(w, apiInterface.RowVersion)
 	render.JSON(w, r, SuccessResponse("获取共享接口成功", apiInterface))
 }
 
@@ -1009,6 +1202,7 @@ type UpdateApiInterfaceRequest struct {
 	Path        *string `json:"path,omitempty"`
 	Description *string `json:"description,omitempty"`
 	Status      *string `json:"status,omitempty"`
+	RowVersion  int64   `json:"row_version,omitempty"` // 期望版本，也可通过If-Match头传递
 }
 
 // UpdateApiInterface 更新共享接口
@@ -1019,17 +1213,18 @@ type UpdateApiInterfaceRequest struct {
 // @Produce json
 // @Param id path string true "接口ID"
 // @Param updates body UpdateApiInterfaceRequest true "更新信息"
-// @Success 200 {object} APIResponse "更新成功"
-// @Failure 400 {object} APIResponse "请求参数错误"
-// @Failure 404 {object} APIResponse "接口不存在"
-// @Failure 500 {object} APIResponse "服务器内部错误"
+// @Param If-Match header string false "期望版本（ETag），不一致时返回409"
+// @Success 200 {object} APIResponse[any] "更新成功"
+// @Failure 400 {object} APIResponse[any] "请求参数错误"
+// @Failure 404 {object} APIResponse[any] "接口不存在"
+// @Failure 409 {object} APIResponse[VersionConflictData] "版本冲突"
+// @Failure 500 {object} APIResponse[any] "服务器内部错误"
 // @Router /sharing/api-interfaces/{id} [put]
 func (c *SharingController) UpdateApiInterface(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 
 	var req UpdateApiInterfaceRequest
-	if err := render.DecodeJSON(r.Body, &req); err != nil {
-		render.JSON(w, r, BadRequestResponse("请求参数格式错误", err))
+	if !decodeAndValidate(w, r, &req) {
 		return
 	}
 
@@ -1055,8 +1250,21 @@ func (c *SharingController) UpdateApiInterface(w http.ResponseWriter, r *http.Re
 		return
 	}
 
-	if err := c.sharingService.UpdateApiInterface(id, updates); err != nil {
-		render.JSON(w, r, InternalErrorResponse("更新共享接口失败: "+err.Error(), err))
+	expectedVersion, err := expectedRowVersion(r, req.RowVersion)
+	if err != nil {
+		render.JSON(w, r, BadRequestResponse(err.Error(), err))
+		return
+	}
+
+	if err := c.sharingService.UpdateApiInterface(id, expectedVersion, updates); err != nil {
+		if handleVersionConflict(w, r, err) {
+			return
+		}
+		if errors.Is(err, sharing.ErrThematicInterfaceNotPublished) {
+			render.JSON(w, r, ConflictResponse("更新共享接口失败: "+err.Error(), err))
+			return
+		}
+		render.JSON(w, r, MapErrorResponse("更新共享接口失败: "+err.Error(), err))
 		return
 	}
 
@@ -1070,14 +1278,14 @@ func (c *SharingController) UpdateApiInterface(w http.ResponseWriter, r *http.Re
 // @Accept json
 // @Produce json
 // @Param id path string true "接口ID"
-// @Success 200 {object} APIResponse "删除成功"
-// @Failure 500 {object} APIResponse "服务器内部错误"
+// @Success 200 {object} APIResponse[any] "删除成功"
+// @Failure 500 {object} APIResponse[any] "服务器内部错误"
 // @Router /sharing/api-interfaces/{id} [delete]
 func (c *SharingController) DeleteApiInterface(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 
 	if err := c.sharingService.DeleteApiInterface(id); err != nil {
-		render.JSON(w, r, InternalErrorResponse("删除共享接口失败", err))
+		render.JSON(w, r, MapErrorResponse("删除共享接口失败", err))
 		return
 	}
 
@@ -1099,22 +1307,21 @@ type UpdateApiInterfaceMaskingRulesRequest struct {
 // @Produce json
 // @Param id path string true "接口ID"
 // @Param rules body UpdateApiInterfaceMaskingRulesRequest true "脱敏规则配置"
-// @Success 200 {object} APIResponse "更新成功"
-// @Failure 400 {object} APIResponse "请求参数错误"
-// @Failure 404 {object} APIResponse "接口不存在"
-// @Failure 500 {object} APIResponse "服务器内部错误"
+// @Success 200 {object} APIResponse[any] "更新成功"
+// @Failure 400 {object} APIResponse[any] "请求参数错误"
+// @Failure 404 {object} APIResponse[any] "接口不存在"
+// @Failure 500 {object} APIResponse[any] "服务器内部错误"
 // @Router /sharing/api-interfaces/{id}/masking-rules [put]
 func (c *SharingController) UpdateApiInterfaceMaskingRules(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 
 	var req UpdateApiInterfaceMaskingRulesRequest
-	if err := render.DecodeJSON(r.Body, &req); err != nil {
-		render.JSON(w, r, BadRequestResponse("请求参数格式错误", err))
+	if !decodeAndValidate(w, r, &req) {
 		return
 	}
 
 	if err := c.sharingService.UpdateApiInterfaceMaskingRules(id, req.MaskingRules); err != nil {
-		render.JSON(w, r, InternalErrorResponse("更新API接口脱敏规则失败: "+err.Error(), err))
+		render.JSON(w, r, MapErrorResponse("更新API接口脱敏规则失败: "+err.Error(), err))
 		return
 	}
 
@@ -1128,16 +1335,16 @@ func (c *SharingController) UpdateApiInterfaceMaskingRules(w http.ResponseWriter
 // @Accept json
 // @Produce json
 // @Param id path string true "接口ID"
-// @Success 200 {object} APIResponse{data=[]models.DataMaskingConfig} "获取成功"
-// @Failure 404 {object} APIResponse "接口不存在"
-// @Failure 500 {object} APIResponse "服务器内部错误"
+// @Success 200 {object} APIResponse[[]models.DataMaskingConfig] "获取成功"
+// @Failure 404 {object} APIResponse[any] "接口不存在"
+// @Failure 500 {object} APIResponse[any] "服务器内部错误"
 // @Router /sharing/api-interfaces/{id}/masking-rules [get]
 func (c *SharingController) GetApiInterfaceMaskingRules(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 
 	rules, err := c.sharingService.GetApiInterfaceMaskingRules(id)
 	if err != nil {
-		render.JSON(w, r, InternalErrorResponse("获取API接口脱敏规则失败: "+err.Error(), err))
+		render.JSON(w, r, MapErrorResponse("获取API接口脱敏规则失败: "+err.Error(), err))
 		return
 	}
 
@@ -1152,13 +1359,13 @@ func (c *SharingController) GetApiInterfaceMaskingRules(w http.ResponseWriter, r
 // @Tags 数据共享服务
 // @Accept json
 // @Produce json
-// @Success 200 {object} APIResponse{data=models.ApiRateLimitStatistics} "获取成功"
-// @Failure 500 {object} APIResponse "服务器内部错误"
+// @Success 200 {object} APIResponse[models.ApiRateLimitStatistics] "获取成功"
+// @Failure 500 {object} APIResponse[any] "服务器内部错误"
 // @Router /sharing/api-rate-limits/statistics [get]
 func (c *SharingController) GetApiRateLimitStatistics(w http.ResponseWriter, r *http.Request) {
 	stats, err := c.sharingService.GetApiRateLimitStatistics()
 	if err != nil {
-		render.JSON(w, r, InternalErrorResponse("获取限流统计失败", err))
+		render.JSON(w, r, MapErrorResponse("获取限流统计失败", err))
 		return
 	}
 
@@ -1173,8 +1380,8 @@ func (c *SharingController) GetApiRateLimitStatistics(w http.ResponseWriter, r *
 // @Produce json
 // @Param start_time query string false "开始时间（RFC3339格式）"
 // @Param end_time query string false "结束时间（RFC3339格式）"
-// @Success 200 {object} APIResponse{data=models.ApiUsageStatistics} "获取成功"
-// @Failure 500 {object} APIResponse "服务器内部错误"
+// @Success 200 {object} APIResponse[models.ApiUsageStatistics] "获取成功"
+// @Failure 500 {object} APIResponse[any] "服务器内部错误"
 // @Router /sharing/api-usage-logs/statistics [get]
 func (c *SharingController) GetApiUsageStatistics(w http.ResponseWriter, r *http.Request) {
 	var startTime, endTime *time.Time
@@ -1195,7 +1402,7 @@ func (c *SharingController) GetApiUsageStatistics(w http.ResponseWriter, r *http
 
 	stats, err := c.sharingService.GetApiUsageStatistics(startTime, endTime)
 	if err != nil {
-		render.JSON(w, r, InternalErrorResponse("获取使用统计失败", err))
+		render.JSON(w, r, MapErrorResponse("获取使用统计失败", err))
 		return
 	}
 
@@ -0,0 +1,232 @@
+/*
+ * @module api/controllers/snapshot_controller
+ * @description 接口表快照控制器，提供快照策略配置、手动快照、快照列表、快照数据查询、时间点查询和快照比较的接口
+ * @architecture 分层架构 - 控制器层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow HTTP请求 -> 控制器 -> 接口表快照服务 -> 数据库
+ * @rules 统一的错误处理和响应格式；参数不合法时返回400；时间点没有快照时返回404
+ * @dependencies datahub-service/service, datahub-service/service/basic_library, github.com/go-chi/chi/v5
+ * @refs service/basic_library/snapshot_service.go
+ */
+
+package controllers
+
+import (
+	"datahub-service/service"
+	"datahub-service/service/basic_library"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+)
+
+// SnapshotController 接口表快照控制器
+type SnapshotController struct {
+}
+
+// NewSnapshotController 创建接口表快照控制器实例
+func NewSnapshotController() *SnapshotController {
+	return &SnapshotController{}
+}
+
+// UpdateSnapshotPolicyRequest 更新快照策略请求
+type UpdateSnapshotPolicyRequest struct {
+	Enabled   bool `json:"enabled" example:"true"`                          // 开启后每次同步成功都保存快照
+	Retention int  `json:"retention" validate:"min=0,max=365" example:"30"` // 保留的快照个数，为0时使用默认值30
+}
+
+// GetSnapshotPolicy 获取接口快照策略
+// @Summary 获取接口快照策略
+// @Description 获取接口是否在同步后保存快照以及保留的快照个数，未配置时返回默认策略（不开启，保留30个）
+// @Tags 数据基础库
+// @Produce json
+// @Param id path string true "接口ID"
+// @Success 200 {object} APIResponse[models.InterfaceSnapshotPolicy] "获取成功"
+// @Failure 404 {object} APIResponse[any] "接口不存在"
+// @Router /basic-libraries/interfaces/{id}/snapshot-policy [get]
+func (c *SnapshotController) GetSnapshotPolicy(w http.ResponseWriter, r *http.Request) {
+	policy, err := service.GlobalSnapshotService.GetPolicy(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		render.JSON(w, r, MapErrorResponse("获取快照策略失败", err))
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("获取快照策略成功", policy))
+}
+
+// UpdateSnapshotPolicy 更新接口快照策略
+// @Summary 更新接口快照策略
+// @Description 开启或关闭接口的同步后快照并设置保留个数，减少保留个数时立即删除多余的最早快照
+// @Tags 数据基础库
+// @Accept json
+// @Produce json
+// @Param id path string true "接口ID"
+// @Param request body UpdateSnapshotPolicyRequest true "快照策略"
+// @Success 200 {object} APIResponse[models.InterfaceSnapshotPolicy] "更新成功"
+// @Failure 400 {object} APIResponse[any] "请求参数错误"
+// @Failure 404 {object} APIResponse[any] "接口不存在"
+// @Router /basic-libraries/interfaces/{id}/snapshot-policy [put]
+func (c *SnapshotController) UpdateSnapshotPolicy(w http.ResponseWriter, r *http.Request) {
+	var req UpdateSnapshotPolicyRequest
+	if !decodeAndValidate(w, r, &req) {
+		return
+	}
+
+	policy, err := service.GlobalSnapshotService.UpdatePolicy(r.Context(), chi.URLParam(r, "id"), req.Enabled, req.Retention, getCurrentUsername(r))
+	if err != nil {
+		respondSnapshotError(w, r, "更新快照策略失败", err)
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("更新快照策略成功", policy))
+}
+
+// GetSnapshots 获取接口快照列表
+// @Summary 获取接口快照列表
+// @Description 按时间倒序获取接口的快照，包括触发快照的同步执行和快照时的行数
+// @Tags 数据基础库
+// @Produce json
+// @Param id path string true "接口ID"
+// @Success 200 {object} APIResponse[[]models.InterfaceTableSnapshot] "获取成功"
+// @Failure 404 {object} APIResponse[any] "接口不存在"
+// @Router /basic-libraries/interfaces/{id}/snapshots [get]
+func (c *SnapshotController) GetSnapshots(w http.ResponseWriter, r *http.Request) {
+	snapshots, err := service.GlobalSnapshotService.ListSnapshots(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		render.JSON(w, r, MapErrorResponse("获取快照列表失败", err))
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("获取快照列表成功", snapshots))
+}
+
+// CreateSnapshot 手动保存接口快照
+// @Summary 手动保存接口快照
+// @Description 立即复制接口表保存一次快照，不要求开启快照策略，同样受保留个数限制
+// @Tags 数据基础库
+// @Produce json
+// @Param id path string true "接口ID"
+// @Success 200 {object} APIResponse[models.InterfaceTableSnapshot] "保存成功"
+// @Failure 400 {object} APIResponse[any] "接口表尚未创建"
+// @Failure 404 {object} APIResponse[any] "接口不存在"
+// @Router /basic-libraries/interfaces/{id}/snapshots [post]
+func (c *SnapshotController) CreateSnapshot(w http.ResponseWriter, r *http.Request) {
+	snapshot, err := service.GlobalSnapshotService.CaptureSnapshot(r.Context(), chi.URLParam(r, "id"), getCurrentUsername(r))
+	if err != nil {
+		respondSnapshotError(w, r, "保存快照失败", err)
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("保存快照成功", snapshot))
+}
+
+// DeleteSnapshot 删除接口快照
+// @Summary 删除接口快照
+// @Description 删除快照记录及其快照表
+// @Tags 数据基础库
+// @Produce json
+// @Param id path string true "接口ID"
+// @Param snapshot_id path string true "快照ID"
+// @Success 200 {object} APIResponse[any] "删除成功"
+// @Failure 404 {object} APIResponse[any] "接口或快照不存在"
+// @Router /basic-libraries/interfaces/{id}/snapshots/{snapshot_id} [delete]
+func (c *SnapshotController) DeleteSnapshot(w http.ResponseWriter, r *http.Request) {
+	if err := service.GlobalSnapshotService.DeleteSnapshot(r.Context(), chi.URLParam(r, "id"), chi.URLParam(r, "snapshot_id")); err != nil {
+		render.JSON(w, r, MapErrorResponse("删除快照失败", err))
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("删除快照成功", nil))
+}
+
+// GetSnapshotData 查询快照数据
+// @Summary 查询快照数据
+// @Description 分页查询快照中的数据，有主键时按主键排序
+// @Tags 数据基础库
+// @Produce json
+// @Param id path string true "接口ID"
+// @Param snapshot_id path string true "快照ID"
+// @Param page query int false "页码" default(1)
+// @Param size query int false "每页数量" default(10)
+// @Success 200 {object} APIResponse[basic_library.SnapshotData] "查询成功"
+// @Failure 404 {object} APIResponse[any] "接口或快照不存在"
+// @Router /basic-libraries/interfaces/{id}/snapshots/{snapshot_id}/data [get]
+func (c *SnapshotController) GetSnapshotData(w http.ResponseWriter, r *http.Request) {
+	page, size := parsePagination(r)
+	data, err := service.GlobalSnapshotService.GetSnapshotData(r.Context(), chi.URLParam(r, "id"), chi.URLParam(r, "snapshot_id"), page, size)
+	if err != nil {
+		render.JSON(w, r, MapErrorResponse("查询快照数据失败", err))
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("查询快照数据成功", data))
+}
+
+// GetDataAsOf 查询接口在某一时间点的数据
+// @Summary 查询接口在某一时间点的数据
+// @Description 返回指定时间之前最近一次快照中的数据，用于核对某次上报时的数据
+// @Tags 数据基础库
+// @Produce json
+// @Param id path string true "接口ID"
+// @Param time query string true "时间点，RFC3339格式" example(2024-06-30T23:59:59+08:00)
+// @Param page query int false "页码" default(1)
+// @Param size query int false "每页数量" default(10)
+// @Success 200 {object} APIResponse[basic_library.SnapshotData] "查询成功"
+// @Failure 400 {object} APIResponse[any] "时间格式错误"
+// @Failure 404 {object} APIResponse[any] "接口不存在或该时间之前没有快照"
+// @Router /basic-libraries/interfaces/{id}/snapshots/as-of [get]
+func (c *SnapshotController) GetDataAsOf(w http.ResponseWriter, r *http.Request) {
+	asOf, err := time.Parse(time.RFC3339, r.URL.Query().Get("time"))
+	if err != nil {
+		render.JSON(w, r, BadRequestResponse("时间格式错误，应为RFC3339格式", err))
+		return
+	}
+
+	page, size := parsePagination(r)
+	data, err := service.GlobalSnapshotService.GetDataAsOf(r.Context(), chi.URLParam(r, "id"), asOf, page, size)
+	if err != nil {
+		render.JSON(w, r, MapErrorResponse("查询时间点数据失败", err))
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("查询时间点数据成功", data))
+}
+
+// CompareSnapshots 比较接口快照
+// @Summary 比较接口快照
+// @Description 比较两个快照，或快照与当前接口表（不传to）；有主键时按主键统计新增、删除、修改的行，给出主键样例和各列修改行数，没有主键时按整行统计新增和删除
+// @Tags 数据基础库
+// @Produce json
+// @Param id path string true "接口ID"
+// @Param from query string true "旧快照ID"
+// @Param to query string false "新快照ID，不传时与当前数据比较"
+// @Success 200 {object} APIResponse[basic_library.SnapshotComparison] "比较成功"
+// @Failure 400 {object} APIResponse[any] "请求参数错误"
+// @Failure 404 {object} APIResponse[any] "接口或快照不存在"
+// @Router /basic-libraries/interfaces/{id}/snapshots/compare [get]
+func (c *SnapshotController) CompareSnapshots(w http.ResponseWriter, r *http.Request) {
+	from := r.URL.Query().Get("from")
+	if from == "" {
+		render.JSON(w, r, BadRequestResponse("比较快照失败", errors.New("缺少from参数")))
+		return
+	}
+
+	comparison, err := service.GlobalSnapshotService.CompareSnapshots(r.Context(), chi.URLParam(r, "id"), from, r.URL.Query().Get("to"))
+	if err != nil {
+		respondSnapshotError(w, r, "比较快照失败", err)
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("比较快照成功", comparison))
+}
+
+// respondSnapshotError 快照请求不合法时返回400，其余按错误类型映射
+func respondSnapshotError(w http.ResponseWriter, r *http.Request, msg string, err error) {
+	if errors.Is(err, basic_library.ErrInvalidSnapshotRequest) {
+		render.JSON(w, r, BadRequestResponse(msg, err))
+		return
+	}
+	render.JSON(w, r, MapErrorResponse(msg, err))
+}
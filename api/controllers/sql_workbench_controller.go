@@ -0,0 +1,231 @@
+/*
+ * @module api/controllers/sql_workbench_controller
+ * @description SQL工作台控制器，提供只读查询执行、保存的查询管理和个人执行记录接口
+ * @architecture 分层架构 - 控制器层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow HTTP请求 -> 控制器 -> SQL工作台服务 -> 只读副本/数据库
+ * @rules 统一的错误处理和响应格式；语句未通过检查返回400，访问未授权的表返回403，语句执行出错或超时返回422；每次执行都写入审计日志
+ * @dependencies datahub-service/service, datahub-service/service/workbench, github.com/go-chi/chi/v5
+ * @refs service/workbench/workbench_service.go
+ */
+
+package controllers
+
+import (
+	"datahub-service/api/middleware"
+	"datahub-service/service"
+	"datahub-service/service/models"
+	"datahub-service/service/workbench"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+)
+
+// SQLWorkbenchController SQL工作台控制器
+type SQLWorkbenchController struct {
+}
+
+// NewSQLWorkbenchController 创建SQL工作台控制器实例
+func NewSQLWorkbenchController() *SQLWorkbenchController {
+	return &SQLWorkbenchController{}
+}
+
+// SavedQueryListResponse 保存的查询列表响应结构
+type SavedQueryListResponse struct {
+	List []models.SavedQuery `json:"list"`
+	models.PageMeta
+}
+
+// WorkbenchHistoryResponse 执行记录列表响应结构
+type WorkbenchHistoryResponse struct {
+	List []models.SystemLog `json:"list"`
+	models.PageMeta
+}
+
+// ExecuteQuery 执行查询
+// @Summary 执行SQL查询
+// @Description 在只读事务中执行一条SELECT查询，只能访问当前租户的基础库和主题库schema。结果最多返回limit行（不超过运行时配置workbench_max_rows），超时由workbench_statement_timeout_seconds控制；敏感字段和加密列按配置脱敏。提交saved_query_id时执行保存的查询。每次执行都写入审计日志
+// @Tags SQL工作台
+// @Accept json
+// @Produce json
+// @Param request body workbench.QueryRequest true "查询请求"
+// @Success 200 {object} APIResponse[workbench.QueryResult] "执行成功"
+// @Failure 400 {object} APIResponse[any] "语句未通过检查"
+// @Failure 403 {object} APIResponse[any] "访问未授权的schema或受行级策略保护的表"
+// @Failure 422 {object} APIResponse[any] "语句执行出错或超时"
+// @Router /workbench/execute [post]
+func (c *SQLWorkbenchController) ExecuteQuery(w http.ResponseWriter, r *http.Request) {
+	var req workbench.QueryRequest
+	if !decodeAndValidate(w, r, &req) {
+		return
+	}
+	if req.SQL == "" && req.SavedQueryID == "" {
+		render.JSON(w, r, BadRequestResponse("sql和saved_query_id不能同时为空", nil))
+		return
+	}
+
+	result, err := service.GlobalWorkbenchService.Execute(r.Context(), &req, workbenchSubject(r))
+	if err != nil {
+		render.JSON(w, r, workbenchErrorResponse("执行查询失败", err))
+		return
+	}
+	render.JSON(w, r, SuccessResponse("执行查询成功", result))
+}
+
+// GetHistory 获取执行记录
+// @Summary 获取SQL工作台执行记录
+// @Description 分页获取当前用户的执行记录（系统日志中object_type=sql_workbench的记录），按执行时间倒序。全部用户的记录可通过/system-logs?object_type=sql_workbench查询
+// @Tags SQL工作台
+// @Produce json
+// @Param page query int false "页码" default(1)
+// @Param size query int false "每页数量" default(10)
+// @Success 200 {object} APIResponse[WorkbenchHistoryResponse] "获取成功"
+// @Router /workbench/history [get]
+func (c *SQLWorkbenchController) GetHistory(w http.ResponseWriter, r *http.Request) {
+	page, size := parsePagination(r)
+	logs, total, err := service.GlobalWorkbenchService.GetHistory(r.Context(), getCurrentUsername(r), page, size)
+	if err != nil {
+		render.JSON(w, r, MapErrorResponse("获取执行记录失败", err))
+		return
+	}
+	render.JSON(w, r, SuccessResponse("获取执行记录成功", WorkbenchHistoryResponse{
+		List:     logs,
+		PageMeta: models.NewPageMeta(total, page, size),
+	}))
+}
+
+// GetSavedQueries 获取保存的查询列表
+// @Summary 获取保存的查询列表
+// @Description 分页获取当前用户创建的和同租户共享的查询，可按名称搜索
+// @Tags SQL工作台
+// @Produce json
+// @Param page query int false "页码" default(1)
+// @Param size query int false "每页数量" default(10)
+// @Param keyword query string false "名称关键字"
+// @Success 200 {object} APIResponse[SavedQueryListResponse] "获取成功"
+// @Router /workbench/saved-queries [get]
+func (c *SQLWorkbenchController) GetSavedQueries(w http.ResponseWriter, r *http.Request) {
+	page, size := parsePagination(r)
+	queries, total, err := service.GlobalWorkbenchService.ListSavedQueries(r.Context(), getCurrentUsername(r), r.URL.Query().Get("keyword"), page, size)
+	if err != nil {
+		render.JSON(w, r, MapErrorResponse("获取保存的查询失败", err))
+		return
+	}
+	render.JSON(w, r, SuccessResponse("获取保存的查询成功", SavedQueryListResponse{
+		List:     queries,
+		PageMeta: models.NewPageMeta(total, page, size),
+	}))
+}
+
+// CreateSavedQuery 保存查询
+// @Summary 保存查询
+// @Description 保存一条查询语句，语句须通过与执行相同的静态检查；is_shared为true时同租户用户可以查看和执行
+// @Tags SQL工作台
+// @Accept json
+// @Produce json
+// @Param request body workbench.SavedQueryRequest true "保存查询请求"
+// @Success 200 {object} APIResponse[models.SavedQuery] "保存成功"
+// @Failure 400 {object} APIResponse[any] "名称为空或语句未通过检查"
+// @Router /workbench/saved-queries [post]
+func (c *SQLWorkbenchController) CreateSavedQuery(w http.ResponseWriter, r *http.Request) {
+	var req workbench.SavedQueryRequest
+	if !decodeAndValidate(w, r, &req) {
+		return
+	}
+	query, err := service.GlobalWorkbenchService.CreateSavedQuery(r.Context(), &req, getCurrentUsername(r))
+	if err != nil {
+		render.JSON(w, r, workbenchErrorResponse("保存查询失败", err))
+		return
+	}
+	render.JSON(w, r, SuccessResponse("保存查询成功", query))
+}
+
+// GetSavedQuery 获取保存的查询
+// @Summary 获取保存的查询
+// @Description 获取当前用户创建的或共享的查询，其他用户的私有查询返回404
+// @Tags SQL工作台
+// @Produce json
+// @Param id path string true "查询ID"
+// @Success 200 {object} APIResponse[models.SavedQuery] "获取成功"
+// @Failure 404 {object} APIResponse[any] "查询不存在"
+// @Router /workbench/saved-queries/{id} [get]
+func (c *SQLWorkbenchController) GetSavedQuery(w http.ResponseWriter, r *http.Request) {
+	query, err := service.GlobalWorkbenchService.GetSavedQuery(r.Context(), chi.URLParam(r, "id"), workbenchSubject(r))
+	if err != nil {
+		render.JSON(w, r, MapErrorResponse("获取保存的查询失败", err))
+		return
+	}
+	render.JSON(w, r, SuccessResponse("获取保存的查询成功", query))
+}
+
+// UpdateSavedQuery 修改保存的查询
+// @Summary 修改保存的查询
+// @Description 修改查询的名称、说明、语句和共享状态，只有创建人和管理员可以修改
+// @Tags SQL工作台
+// @Accept json
+// @Produce json
+// @Param id path string true "查询ID"
+// @Param request body workbench.SavedQueryRequest true "保存查询请求"
+// @Success 200 {object} APIResponse[models.SavedQuery] "修改成功"
+// @Failure 400 {object} APIResponse[any] "名称为空或语句未通过检查"
+// @Failure 403 {object} APIResponse[any] "不是创建人"
+// @Failure 404 {object} APIResponse[any] "查询不存在"
+// @Router /workbench/saved-queries/{id} [put]
+func (c *SQLWorkbenchController) UpdateSavedQuery(w http.ResponseWriter, r *http.Request) {
+	var req workbench.SavedQueryRequest
+	if !decodeAndValidate(w, r, &req) {
+		return
+	}
+	query, err := service.GlobalWorkbenchService.UpdateSavedQuery(r.Context(), chi.URLParam(r, "id"), &req, workbenchSubject(r))
+	if err != nil {
+		render.JSON(w, r, workbenchErrorResponse("修改保存的查询失败", err))
+		return
+	}
+	render.JSON(w, r, SuccessResponse("修改保存的查询成功", query))
+}
+
+// DeleteSavedQuery 删除保存的查询
+// @Summary 删除保存的查询
+// @Description 删除保存的查询，只有创建人和管理员可以删除
+// @Tags SQL工作台
+// @Produce json
+// @Param id path string true "查询ID"
+// @Success 200 {object} APIResponse[any] "删除成功"
+// @Failure 403 {object} APIResponse[any] "不是创建人"
+// @Failure 404 {object} APIResponse[any] "查询不存在"
+// @Router /workbench/saved-queries/{id} [delete]
+func (c *SQLWorkbenchController) DeleteSavedQuery(w http.ResponseWriter, r *http.Request) {
+	if err := service.GlobalWorkbenchService.DeleteSavedQuery(r.Context(), chi.URLParam(r, "id"), workbenchSubject(r)); err != nil {
+		render.JSON(w, r, workbenchErrorResponse("删除保存的查询失败", err))
+		return
+	}
+	render.JSON(w, r, SuccessResponse("删除保存的查询成功", nil))
+}
+
+// workbenchSubject 获取当前用户及其有效角色
+func workbenchSubject(r *http.Request) workbench.Subject {
+	subject := workbench.Subject{Username: getCurrentUsername(r), ClientIP: getClientIP(r)}
+	if userInfo, ok := middleware.GetUserInfoFromContext(r.Context()); ok {
+		subject.Roles = userInfo.Roles
+		if service.GlobalRBACService != nil {
+			subject.Roles = service.GlobalRBACService.ResolveRoles(userInfo.Username, userInfo.Roles)
+		}
+	}
+	return subject
+}
+
+// workbenchErrorResponse 按SQL工作台的错误类型创建错误响应
+func workbenchErrorResponse(msg string, err error) render.Renderer {
+	switch {
+	case errors.Is(err, workbench.ErrStatementRejected), errors.Is(err, workbench.ErrInvalidSavedQuery):
+		return BadRequestResponse(msg+": "+err.Error(), err)
+	case errors.Is(err, workbench.ErrAccessDenied), errors.Is(err, workbench.ErrSavedQueryForbidden):
+		return ErrorResponse(StatusForbidden, msg+": "+err.Error(), err)
+	case errors.Is(err, workbench.ErrQueryFailed), errors.Is(err, workbench.ErrQueryTimeout):
+		return ErrorResponse(StatusUnprocessableEntity, msg+": "+err.Error(), err)
+	default:
+		return MapErrorResponse(msg, err)
+	}
+}
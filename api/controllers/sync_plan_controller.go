@@ -0,0 +1,75 @@
+/*
+ * @module api/controllers/sync_plan_controller
+ * @description 同步容量规划控制器，按接口的历史执行指标估算提议的批次大小和并发数下全量同步的耗时、批次数和数据库负载
+ * @architecture 分层架构 - 控制器层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow HTTP请求 -> 控制器 -> 同步容量规划服务 -> 历史指标拟合 -> 估算结果
+ * @rules 统一的错误处理和响应格式；参数不合法或没有历史执行记录时返回400；估算只读，不访问源系统
+ * @dependencies datahub-service/service, datahub-service/service/basic_library, github.com/go-chi/chi/v5
+ * @refs service/basic_library/sync_plan_service.go
+ */
+
+package controllers
+
+import (
+	"datahub-service/service"
+	"datahub-service/service/basic_library"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+)
+
+// SyncPlanController 同步容量规划控制器
+type SyncPlanController struct {
+}
+
+// NewSyncPlanController 创建同步容量规划控制器实例
+func NewSyncPlanController() *SyncPlanController {
+	return &SyncPlanController{}
+}
+
+// EstimateSyncPlanRequest 同步容量规划请求
+type EstimateSyncPlanRequest struct {
+	BatchSize   int   `json:"batch_size" validate:"required,min=1" example:"5000"`       // 提议的每批条数
+	Concurrency int   `json:"concurrency" validate:"omitempty,min=1,max=64" example:"4"` // 提议的并发数，默认1
+	TotalRows   int64 `json:"total_rows" validate:"omitempty,min=0" example:"0"`         // 待同步行数，为0时按存储容量快照或历史估计
+	Days        int   `json:"days" validate:"omitempty,min=1,max=180" example:"30"`      // 统计的历史天数，默认30
+}
+
+// EstimateSyncPlan 估算接口全量同步容量
+// @Summary 估算接口全量同步容量
+// @Description 根据接口近期成功执行的写入行数、耗时和批次数拟合单行耗时和单批开销，估算提议的每批条数和并发数下全量同步的耗时、批次数、数据库连接数、事务频率、写入量和内存驻留行数，并给出接口当前配置下的估算供对比。估算只读，不访问源系统
+// @Tags 数据基础库
+// @Accept json
+// @Produce json
+// @Param id path string true "接口ID"
+// @Param request body EstimateSyncPlanRequest true "提议的同步参数"
+// @Success 200 {object} APIResponse[basic_library.SyncPlan] "估算成功"
+// @Failure 400 {object} APIResponse[any] "参数不合法或没有可用的历史执行记录"
+// @Failure 404 {object} APIResponse[any] "接口不存在"
+// @Router /basic-libraries/interfaces/{id}/sync-plan [post]
+func (c *SyncPlanController) EstimateSyncPlan(w http.ResponseWriter, r *http.Request) {
+	var req EstimateSyncPlanRequest
+	if !decodeAndValidate(w, r, &req) {
+		return
+	}
+
+	plan, err := service.GlobalSyncPlanService.Estimate(r.Context(), chi.URLParam(r, "id"), &basic_library.SyncPlanRequest{
+		BatchSize:   req.BatchSize,
+		Concurrency: req.Concurrency,
+		TotalRows:   req.TotalRows,
+		Days:        req.Days,
+	})
+	if err != nil {
+		if errors.Is(err, basic_library.ErrInvalidSyncPlanRequest) || errors.Is(err, basic_library.ErrNoSyncHistory) {
+			render.JSON(w, r, BadRequestResponse("估算同步容量失败", err))
+			return
+		}
+		render.JSON(w, r, MapErrorResponse("估算同步容量失败", err))
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("估算同步容量成功", plan))
+}
@@ -12,10 +12,12 @@
 package controllers
 
 import (
+	"context"
 	"datahub-service/service"
 	"datahub-service/service/basic_library"
 	"datahub-service/service/meta"
-	"encoding/json"
+	"datahub-service/service/models"
+	"errors"
 	"net/http"
 	"strconv"
 	"time"
@@ -26,13 +28,17 @@ import (
 
 // SyncTaskController 基础库同步任务控制器
 type SyncTaskController struct {
-	syncTaskService *basic_library.SyncTaskService
+	syncTaskService        *basic_library.SyncTaskService
+	failureAnalysisService *basic_library.FailureAnalysisService
+	executionDiffService   *basic_library.ExecutionDiffService
 }
 
 // NewSyncTaskController 创建基础库同步任务控制器
 func NewSyncTaskController() *SyncTaskController {
 	return &SyncTaskController{
-		syncTaskService: service.GlobalSyncTaskService,
+		syncTaskService:        service.GlobalSyncTaskService,
+		failureAnalysisService: basic_library.NewFailureAnalysisService(service.DB),
+		executionDiffService:   basic_library.NewExecutionDiffService(service.DB),
 	}
 }
 
@@ -54,7 +60,6 @@ type SyncTaskCreateRequest struct {
 	IntervalSeconds  int                       `json:"interval_seconds,omitempty" example:"3600"`
 	ScheduledTime    *string                   `json:"scheduled_time,omitempty" example:"2024-01-01T00:00:00Z"`
 	Config           map[string]interface{}    `json:"config,omitempty"` // 任务级别的全局配置
-	CreatedBy        string                    `json:"created_by" example:"admin"`
 
 	// 向后兼容字段（已废弃，但保留以支持旧版本API）
 	InterfaceID *string `json:"interface_id,omitempty" example:"550e8400-e29b-41d4-a716-446655440000"`
@@ -70,8 +75,8 @@ type SyncTaskUpdateRequest struct {
 	Config           map[string]interface{}    `json:"config,omitempty"`            // 任务级别的全局配置
 	InterfaceIDs     []string                  `json:"interface_ids,omitempty"`     // 更新接口列表
 	InterfaceConfigs []SyncTaskInterfaceConfig `json:"interface_configs,omitempty"` // 更新接口级别的配置
-	UpdatedBy        string                    `json:"updated_by" example:"admin"`
 	TaskType         string                    `json:"task_type,omitempty" example:"batch_sync"`
+	RowVersion       int64                     `json:"row_version,omitempty" example:"3"` // 期望版本，也可通过If-Match头传递
 }
 
 // SyncTaskListRequest 基础库同步任务列表请求
@@ -89,6 +94,13 @@ type BatchDeleteRequest struct {
 	TaskIDs []string `json:"task_ids" binding:"required"`
 }
 
+// SyncTaskBatchOperationRequest 按筛选条件批量操作同步任务请求
+type SyncTaskBatchOperationRequest struct {
+	Action string                            `json:"action" validate:"required,oneof=activate pause resume delete run" example:"pause"` // activate, pause, resume, delete, run
+	Filter basic_library.SyncTaskBatchFilter `json:"filter"`                                                                            // 至少指定一个筛选条件
+	DryRun bool                              `json:"dry_run" example:"false"`                                                           // 只返回匹配的任务，不执行动作
+}
+
 // SyncTaskExecutionListRequest 同步任务执行记录列表请求
 type SyncTaskExecutionListRequest struct {
 	Page          int    `json:"page" example:"1"`
@@ -128,14 +140,13 @@ type SyncTaskExecutionListRequest struct {
 // @Accept json
 // @Produce json
 // @Param task body SyncTaskCreateRequest true "基础库同步任务创建信息"
-// @Success 200 {object} APIResponse{data=models.SyncTask} "创建成功"
-// @Failure 400 {object} APIResponse "请求参数错误"
-// @Failure 500 {object} APIResponse "服务器内部错误"
+// @Success 200 {object} APIResponse[models.SyncTask] "创建成功"
+// @Failure 400 {object} APIResponse[any] "请求参数错误"
+// @Failure 500 {object} APIResponse[any] "服务器内部错误"
 // @Router /sync/tasks [post]
 func (c *SyncTaskController) CreateSyncTask(w http.ResponseWriter, r *http.Request) {
 	var req SyncTaskCreateRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		render.JSON(w, r, BadRequestResponse("请求参数解析失败", err))
+	if !decodeAndValidate(w, r, &req) {
 		return
 	}
 
@@ -200,12 +211,12 @@ func (c *SyncTaskController) CreateSyncTask(w http.ResponseWriter, r *http.Reque
 		IntervalSeconds:  req.IntervalSeconds,
 		ScheduledTime:    scheduledTime,
 		Config:           req.Config,
-		CreatedBy:        req.CreatedBy,
+		CreatedBy:        getCurrentUsername(r),
 	}
 
 	task, err := c.syncTaskService.CreateSyncTask(r.Context(), serviceReq)
 	if err != nil {
-		render.JSON(w, r, InternalErrorResponse("创建同步任务失败", err))
+		render.JSON(w, r, MapErrorResponse("创建同步任务失败", err))
 		return
 	}
 
@@ -232,27 +243,20 @@ func (c *SyncTaskController) CreateSyncTask(w http.ResponseWriter, r *http.Reque
 // @Param data_source_id query string false "数据源ID"
 // @Param status query string false "任务状态"
 // @Param task_type query string false "任务类型"
-// @Success 200 {object} APIResponse{data=basic_library.SyncTaskListResponse} "获取成功"
-// @Failure 400 {object} APIResponse "请求参数错误"
-// @Failure 500 {object} APIResponse "服务器内部错误"
+// @Success 200 {object} APIResponse[basic_library.SyncTaskListResponse] "获取成功"
+// @Failure 400 {object} APIResponse[any] "请求参数错误"
+// @Failure 500 {object} APIResponse[any] "服务器内部错误"
 // @Router /sync/tasks [get]
 func (c *SyncTaskController) GetSyncTaskList(w http.ResponseWriter, r *http.Request) {
 	// 解析查询参数
 	req := SyncTaskListRequest{
-		Page:         1,
-		Size:         10,
 		LibraryID:    r.URL.Query().Get("library_id"),
 		DataSourceID: r.URL.Query().Get("data_source_id"),
 		Status:       r.URL.Query().Get("status"),
 		TaskType:     r.URL.Query().Get("task_type"),
 	}
 
-	if page, err := strconv.Atoi(r.URL.Query().Get("page")); err == nil && page > 0 {
-		req.Page = page
-	}
-	if size, err := strconv.Atoi(r.URL.Query().Get("size")); err == nil && size > 0 && size <= 100 {
-		req.Size = size
-	}
+	req.Page, req.Size = parsePagination(r)
 
 	// 创建服务请求（基础库同步任务）
 	serviceReq := &basic_library.GetSyncTaskListRequest{
@@ -267,7 +271,7 @@ func (c *SyncTaskController) GetSyncTaskList(w http.ResponseWriter, r *http.Requ
 
 	response, err := c.syncTaskService.GetSyncTaskList(r.Context(), serviceReq)
 	if err != nil {
-		render.JSON(w, r, InternalErrorResponse("获取同步任务列表失败", err))
+		render.JSON(w, r, MapErrorResponse("获取同步任务列表失败", err))
 		return
 	}
 
@@ -281,10 +285,10 @@ func (c *SyncTaskController) GetSyncTaskList(w http.ResponseWriter, r *http.Requ
 // @Accept json
 // @Produce json
 // @Param id path string true "任务ID"
-// @Success 200 {object} APIResponse{data=models.SyncTask} "获取成功"
-// @Failure 400 {object} APIResponse "请求参数错误"
-// @Failure 404 {object} APIResponse "任务不存在"
-// @Failure 500 {object} APIResponse "服务器内部错误"
+// @Success 200 {object} APIResponse[models.SyncTask] "获取成功"
+// @Failure 400 {object} APIResponse[any] "请求参数错误"
+// @Failure 404 {object} APIResponse[any] "任务不存在"
+// @Failure 500 {object} APIResponse[any] "服务器内部错误"
 // @Router /sync/tasks/{id} [get]
 func (c *SyncTaskController) GetSyncTask(w http.ResponseWriter, r *http.Request) {
 	taskID := chi.URLParam(r, "id")
@@ -299,6 +303,7 @@ func (c *SyncTaskController) GetSyncTask(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	setETag(w, task.RowVersion)
 	render.JSON(w, r, SuccessResponse("获取同步任务成功", task))
 }
 
@@ -319,11 +324,12 @@ func (c *SyncTaskController) GetSyncTask(w http.ResponseWriter, r *http.Request)
 // @Produce json
 // @Param id path string true "任务ID"
 // @Param task body SyncTaskUpdateRequest true "更新信息"
-// @Success 200 {object} APIResponse{data=models.SyncTask} "更新成功"
-// @Failure 400 {object} APIResponse "请求参数错误"
-// @Failure 404 {object} APIResponse "任务不存在"
-// @Failure 409 {object} APIResponse "任务状态不允许更新"
-// @Failure 500 {object} APIResponse "服务器内部错误"
+// @Param If-Match header string false "期望版本（ETag），不一致时返回409"
+// @Success 200 {object} APIResponse[models.SyncTask] "更新成功"
+// @Failure 400 {object} APIResponse[any] "请求参数错误"
+// @Failure 404 {object} APIResponse[any] "任务不存在"
+// @Failure 409 {object} APIResponse[VersionConflictData] "任务状态不允许更新或版本冲突"
+// @Failure 500 {object} APIResponse[any] "服务器内部错误"
 // @Router /sync/tasks/{id} [put]
 func (c *SyncTaskController) UpdateSyncTask(w http.ResponseWriter, r *http.Request) {
 	taskID := chi.URLParam(r, "id")
@@ -333,8 +339,7 @@ func (c *SyncTaskController) UpdateSyncTask(w http.ResponseWriter, r *http.Reque
 	}
 
 	var req SyncTaskUpdateRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		render.JSON(w, r, ErrorResponse(http.StatusBadRequest, "请求参数解析失败", err))
+	if !decodeAndValidate(w, r, &req) {
 		return
 	}
 
@@ -359,6 +364,12 @@ func (c *SyncTaskController) UpdateSyncTask(w http.ResponseWriter, r *http.Reque
 		}
 	}
 
+	expectedVersion, err := expectedRowVersion(r, req.RowVersion)
+	if err != nil {
+		render.JSON(w, r, BadRequestResponse(err.Error(), err))
+		return
+	}
+
 	// 创建更新请求
 	updateReq := &basic_library.UpdateSyncTaskRequest{
 		Status:           req.Status,
@@ -368,17 +379,22 @@ func (c *SyncTaskController) UpdateSyncTask(w http.ResponseWriter, r *http.Reque
 		Config:           req.Config,
 		InterfaceIDs:     req.InterfaceIDs,
 		InterfaceConfigs: interfaceConfigs,
-		UpdatedBy:        req.UpdatedBy,
+		UpdatedBy:        getCurrentUsername(r),
 		TaskType:         req.TaskType,
 		ScheduledTime:    scheduledTime,
+		RowVersion:       expectedVersion,
 	}
 
 	task, err := c.syncTaskService.UpdateSyncTask(r.Context(), taskID, updateReq)
 	if err != nil {
-		render.JSON(w, r, ErrorResponse(http.StatusInternalServerError, "更新同步任务失败", err))
+		if handleVersionConflict(w, r, err) {
+			return
+		}
+		render.JSON(w, r, MapErrorResponse("更新同步任务失败", err))
 		return
 	}
 
+	setETag(w, task.RowVersion)
 	render.JSON(w, r, SuccessResponse("更新同步任务成功", task))
 }
 
@@ -389,11 +405,11 @@ func (c *SyncTaskController) UpdateSyncTask(w http.ResponseWriter, r *http.Reque
 // @Accept json
 // @Produce json
 // @Param id path string true "任务ID"
-// @Success 200 {object} APIResponse "删除成功"
-// @Failure 400 {object} APIResponse "请求参数错误"
-// @Failure 404 {object} APIResponse "任务不存在"
-// @Failure 409 {object} APIResponse "任务状态不允许删除"
-// @Failure 500 {object} APIResponse "服务器内部错误"
+// @Success 200 {object} APIResponse[any] "删除成功"
+// @Failure 400 {object} APIResponse[any] "请求参数错误"
+// @Failure 404 {object} APIResponse[any] "任务不存在"
+// @Failure 409 {object} APIResponse[any] "任务状态不允许删除"
+// @Failure 500 {object} APIResponse[any] "服务器内部错误"
 // @Router /sync/tasks/{id} [delete]
 func (c *SyncTaskController) DeleteSyncTask(w http.ResponseWriter, r *http.Request) {
 	taskID := chi.URLParam(r, "id")
@@ -404,7 +420,7 @@ func (c *SyncTaskController) DeleteSyncTask(w http.ResponseWriter, r *http.Reque
 
 	err := c.syncTaskService.DeleteSyncTask(r.Context(), taskID)
 	if err != nil {
-		render.JSON(w, r, ErrorResponse(http.StatusInternalServerError, "删除同步任务失败", err))
+		render.JSON(w, r, MapErrorResponse("删除同步任务失败", err))
 		return
 	}
 
@@ -413,16 +429,16 @@ func (c *SyncTaskController) DeleteSyncTask(w http.ResponseWriter, r *http.Reque
 
 // StartSyncTask 启动同步任务
 // @Summary 启动同步任务
-// @Description 启动指定的同步任务，将任务提交给同步引擎执行
+// @Description 启动指定的同步任务，将任务提交给同步引擎执行，返回长时操作，通过/operations/{id}查询执行状态和进度或取消
 // @Tags 基础库同步任务
 // @Accept json
 // @Produce json
 // @Param id path string true "任务ID"
-// @Success 200 {object} APIResponse "启动成功"
-// @Failure 400 {object} APIResponse "请求参数错误"
-// @Failure 404 {object} APIResponse "任务不存在"
-// @Failure 409 {object} APIResponse "任务状态不允许启动"
-// @Failure 500 {object} APIResponse "服务器内部错误"
+// @Success 200 {object} APIResponse[models.Operation] "启动成功"
+// @Failure 400 {object} APIResponse[any] "请求参数错误"
+// @Failure 404 {object} APIResponse[any] "任务不存在"
+// @Failure 409 {object} APIResponse[any] "任务状态不允许启动、数据源维护中或已熔断"
+// @Failure 500 {object} APIResponse[any] "服务器内部错误"
 // @Router /sync/tasks/{id}/start [post]
 func (c *SyncTaskController) StartSyncTask(w http.ResponseWriter, r *http.Request) {
 	taskID := chi.URLParam(r, "id")
@@ -431,13 +447,20 @@ func (c *SyncTaskController) StartSyncTask(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
-	err := c.syncTaskService.StartSyncTask(r.Context(), taskID)
+	op, err := service.GlobalOperationService.Start(r.Context(), models.OperationKindSyncTask, taskID, getCurrentUsername(r),
+		func(ctx context.Context) (string, error) {
+			return "", c.syncTaskService.StartSyncTask(ctx, taskID)
+		})
 	if err != nil {
-		render.JSON(w, r, ErrorResponse(http.StatusInternalServerError, "启动同步任务失败", err))
+		if errors.Is(err, basic_library.ErrDataSourceInMaintenance) || errors.Is(err, basic_library.ErrDataSourceCircuitOpen) {
+			render.JSON(w, r, ConflictResponse("启动同步任务失败", err))
+			return
+		}
+		render.JSON(w, r, MapErrorResponse("启动同步任务失败", err))
 		return
 	}
 
-	render.JSON(w, r, SuccessResponse("启动同步任务成功", nil))
+	render.JSON(w, r, SuccessResponse("启动同步任务成功", op))
 }
 
 // StopSyncTask 停止同步任务
@@ -447,11 +470,11 @@ func (c *SyncTaskController) StartSyncTask(w http.ResponseWriter, r *http.Reques
 // @Accept json
 // @Produce json
 // @Param id path string true "任务ID"
-// @Success 200 {object} APIResponse "停止成功"
-// @Failure 400 {object} APIResponse "请求参数错误"
-// @Failure 404 {object} APIResponse "任务不存在"
-// @Failure 409 {object} APIResponse "任务状态不允许停止"
-// @Failure 500 {object} APIResponse "服务器内部错误"
+// @Success 200 {object} APIResponse[any] "停止成功"
+// @Failure 400 {object} APIResponse[any] "请求参数错误"
+// @Failure 404 {object} APIResponse[any] "任务不存在"
+// @Failure 409 {object} APIResponse[any] "任务状态不允许停止"
+// @Failure 500 {object} APIResponse[any] "服务器内部错误"
 // @Router /sync/tasks/{id}/stop [post]
 func (c *SyncTaskController) StopSyncTask(w http.ResponseWriter, r *http.Request) {
 	taskID := chi.URLParam(r, "id")
@@ -462,7 +485,7 @@ func (c *SyncTaskController) StopSyncTask(w http.ResponseWriter, r *http.Request
 
 	err := c.syncTaskService.StopSyncTask(r.Context(), taskID)
 	if err != nil {
-		render.JSON(w, r, ErrorResponse(http.StatusInternalServerError, "停止同步任务失败", err))
+		render.JSON(w, r, MapErrorResponse("停止同步任务失败", err))
 		return
 	}
 
@@ -480,11 +503,11 @@ func (c *SyncTaskController) StopSyncTask(w http.ResponseWriter, r *http.Request
 // @Accept json
 // @Produce json
 // @Param id path string true "任务ID"
-// @Success 200 {object} APIResponse "暂停成功"
-// @Failure 400 {object} APIResponse "请求参数错误"
-// @Failure 404 {object} APIResponse "任务不存在"
-// @Failure 409 {object} APIResponse "任务状态不允许暂停"
-// @Failure 500 {object} APIResponse "服务器内部错误"
+// @Success 200 {object} APIResponse[any] "暂停成功"
+// @Failure 400 {object} APIResponse[any] "请求参数错误"
+// @Failure 404 {object} APIResponse[any] "任务不存在"
+// @Failure 409 {object} APIResponse[any] "任务状态不允许暂停"
+// @Failure 500 {object} APIResponse[any] "服务器内部错误"
 // @Router /sync/tasks/{id}/cancel [post]
 func (c *SyncTaskController) CancelSyncTask(w http.ResponseWriter, r *http.Request) {
 	taskID := chi.URLParam(r, "id")
@@ -495,7 +518,7 @@ func (c *SyncTaskController) CancelSyncTask(w http.ResponseWriter, r *http.Reque
 
 	err := c.syncTaskService.CancelSyncTask(r.Context(), taskID)
 	if err != nil {
-		render.JSON(w, r, ErrorResponse(http.StatusInternalServerError, "暂停同步任务失败", err))
+		render.JSON(w, r, MapErrorResponse("暂停同步任务失败", err))
 		return
 	}
 
@@ -509,11 +532,11 @@ func (c *SyncTaskController) CancelSyncTask(w http.ResponseWriter, r *http.Reque
 // @Accept json
 // @Produce json
 // @Param id path string true "任务ID"
-// @Success 200 {object} APIResponse{data=models.SyncTask} "重试成功"
-// @Failure 400 {object} APIResponse "请求参数错误"
-// @Failure 404 {object} APIResponse "任务不存在"
-// @Failure 409 {object} APIResponse "任务状态不允许重试"
-// @Failure 500 {object} APIResponse "服务器内部错误"
+// @Success 200 {object} APIResponse[models.SyncTask] "重试成功"
+// @Failure 400 {object} APIResponse[any] "请求参数错误"
+// @Failure 404 {object} APIResponse[any] "任务不存在"
+// @Failure 409 {object} APIResponse[any] "任务状态不允许重试"
+// @Failure 500 {object} APIResponse[any] "服务器内部错误"
 // @Router /sync/tasks/{id}/retry [post]
 func (c *SyncTaskController) RetrySyncTask(w http.ResponseWriter, r *http.Request) {
 	taskID := chi.URLParam(r, "id")
@@ -524,7 +547,7 @@ func (c *SyncTaskController) RetrySyncTask(w http.ResponseWriter, r *http.Reques
 
 	newTask, err := c.syncTaskService.RetrySyncTask(r.Context(), taskID)
 	if err != nil {
-		render.JSON(w, r, ErrorResponse(http.StatusInternalServerError, "重试同步任务失败", err))
+		render.JSON(w, r, MapErrorResponse("重试同步任务失败", err))
 		return
 	}
 
@@ -538,10 +561,10 @@ func (c *SyncTaskController) RetrySyncTask(w http.ResponseWriter, r *http.Reques
 // @Accept json
 // @Produce json
 // @Param id path string true "任务ID"
-// @Success 200 {object} APIResponse{data=basic_library.SyncTaskStatusResponse} "获取成功"
-// @Failure 400 {object} APIResponse "请求参数错误"
-// @Failure 404 {object} APIResponse "任务不存在"
-// @Failure 500 {object} APIResponse "服务器内部错误"
+// @Success 200 {object} APIResponse[basic_library.SyncTaskStatusResponse] "获取成功"
+// @Failure 400 {object} APIResponse[any] "请求参数错误"
+// @Failure 404 {object} APIResponse[any] "任务不存在"
+// @Failure 500 {object} APIResponse[any] "服务器内部错误"
 // @Router /sync/tasks/{id}/status [get]
 func (c *SyncTaskController) GetSyncTaskStatus(w http.ResponseWriter, r *http.Request) {
 	taskID := chi.URLParam(r, "id")
@@ -552,7 +575,7 @@ func (c *SyncTaskController) GetSyncTaskStatus(w http.ResponseWriter, r *http.Re
 
 	status, err := c.syncTaskService.GetSyncTaskStatus(r.Context(), taskID)
 	if err != nil {
-		render.JSON(w, r, ErrorResponse(http.StatusInternalServerError, "获取同步任务状态失败", err))
+		render.JSON(w, r, MapErrorResponse("获取同步任务状态失败", err))
 		return
 	}
 
@@ -566,14 +589,13 @@ func (c *SyncTaskController) GetSyncTaskStatus(w http.ResponseWriter, r *http.Re
 // @Accept json
 // @Produce json
 // @Param tasks body BatchDeleteRequest true "批量删除请求"
-// @Success 200 {object} APIResponse{data=basic_library.BatchDeleteResponse} "删除成功"
-// @Failure 400 {object} APIResponse "请求参数错误"
-// @Failure 500 {object} APIResponse "服务器内部错误"
+// @Success 200 {object} APIResponse[basic_library.BatchDeleteResponse] "删除成功"
+// @Failure 400 {object} APIResponse[any] "请求参数错误"
+// @Failure 500 {object} APIResponse[any] "服务器内部错误"
 // @Router /sync/tasks/batch-delete [post]
 func (c *SyncTaskController) BatchDeleteSyncTasks(w http.ResponseWriter, r *http.Request) {
 	var req BatchDeleteRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		render.JSON(w, r, ErrorResponse(http.StatusBadRequest, "请求参数解析失败", err))
+	if !decodeAndValidate(w, r, &req) {
 		return
 	}
 
@@ -584,13 +606,61 @@ func (c *SyncTaskController) BatchDeleteSyncTasks(w http.ResponseWriter, r *http
 
 	response, err := c.syncTaskService.BatchDeleteSyncTasks(r.Context(), req.TaskIDs)
 	if err != nil {
-		render.JSON(w, r, ErrorResponse(http.StatusInternalServerError, "批量删除同步任务失败", err))
+		render.JSON(w, r, MapErrorResponse("批量删除同步任务失败", err))
 		return
 	}
 
 	render.JSON(w, r, SuccessResponse("批量删除同步任务成功", response))
 }
 
+// BatchOperateSyncTasks 按筛选条件批量操作同步任务
+// @Summary 按筛选条件批量操作同步任务
+// @Description 按基础库、数据源、状态、任务类型或任务ID选出任务，逐个激活、暂停、恢复、删除或启动执行，返回每个任务的处理结果；dry_run只返回匹配的任务
+// @Tags 基础库同步任务
+// @Accept json
+// @Produce json
+// @Param request body SyncTaskBatchOperationRequest true "批量操作请求"
+// @Success 200 {object} APIResponse[basic_library.SyncTaskBatchResponse] "操作完成"
+// @Failure 400 {object} APIResponse[any] "请求参数错误"
+// @Failure 500 {object} APIResponse[any] "服务器内部错误"
+// @Router /sync/tasks/batch [post]
+func (c *SyncTaskController) BatchOperateSyncTasks(w http.ResponseWriter, r *http.Request) {
+	var req SyncTaskBatchOperationRequest
+	if !decodeAndValidate(w, r, &req) {
+		return
+	}
+
+	response, err := c.syncTaskService.BatchOperateSyncTasks(r.Context(), &basic_library.SyncTaskBatchRequest{
+		Action: req.Action,
+		Filter: req.Filter,
+		DryRun: req.DryRun,
+	}, syncTaskOperationRunner(getCurrentUsername(r)))
+	if err != nil {
+		if errors.Is(err, basic_library.ErrInvalidSyncTaskBatch) {
+			render.JSON(w, r, BadRequestResponse("批量操作同步任务失败", err))
+			return
+		}
+		render.JSON(w, r, MapErrorResponse("批量操作同步任务失败", err))
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("批量操作同步任务完成", response))
+}
+
+// syncTaskOperationRunner 启动同步任务并登记长时操作，返回操作ID，批量启动和维护结束补跑共用
+func syncTaskOperationRunner(createdBy string) basic_library.SyncTaskRunFunc {
+	return func(ctx context.Context, taskID string) (string, error) {
+		op, err := service.GlobalOperationService.Start(ctx, models.OperationKindSyncTask, taskID, createdBy,
+			func(ctx context.Context) (string, error) {
+				return "", service.GlobalSyncTaskService.StartSyncTask(ctx, taskID)
+			})
+		if err != nil {
+			return "", err
+		}
+		return op.ID, nil
+	}
+}
+
 // GetSyncTaskStatistics 获取同步任务统计信息
 // @Summary 获取同步任务统计信息
 // @Description 获取同步任务的统计数据，包括各状态任务数量、成功率等
@@ -599,8 +669,8 @@ func (c *SyncTaskController) BatchDeleteSyncTasks(w http.ResponseWriter, r *http
 // @Produce json
 // @Param library_id query string false "基础库ID过滤"
 // @Param data_source_id query string false "数据源ID过滤"
-// @Success 200 {object} APIResponse{data=basic_library.SyncTaskStatistics} "获取成功"
-// @Failure 500 {object} APIResponse "服务器内部错误"
+// @Success 200 {object} APIResponse[basic_library.SyncTaskStatistics] "获取成功"
+// @Failure 500 {object} APIResponse[any] "服务器内部错误"
 // @Router /sync/tasks/statistics [get]
 func (c *SyncTaskController) GetSyncTaskStatistics(w http.ResponseWriter, r *http.Request) {
 	libraryID := r.URL.Query().Get("library_id")
@@ -609,7 +679,7 @@ func (c *SyncTaskController) GetSyncTaskStatistics(w http.ResponseWriter, r *htt
 	// 固定为基础库类型
 	statistics, err := c.syncTaskService.GetSyncTaskStatistics(r.Context(), meta.LibraryTypeBasic, libraryID, dataSourceID)
 	if err != nil {
-		render.JSON(w, r, ErrorResponse(http.StatusInternalServerError, "获取同步任务统计信息失败", err))
+		render.JSON(w, r, MapErrorResponse("获取同步任务统计信息失败", err))
 		return
 	}
 
@@ -627,26 +697,19 @@ func (c *SyncTaskController) GetSyncTaskStatistics(w http.ResponseWriter, r *htt
 // @Param task_id query string false "任务ID"
 // @Param status query string false "执行状态"
 // @Param execution_type query string false "执行类型"
-// @Success 200 {object} APIResponse{data=basic_library.SyncTaskExecutionListResponse} "获取成功"
-// @Failure 400 {object} APIResponse "请求参数错误"
-// @Failure 500 {object} APIResponse "服务器内部错误"
+// @Success 200 {object} APIResponse[basic_library.SyncTaskExecutionListResponse] "获取成功"
+// @Failure 400 {object} APIResponse[any] "请求参数错误"
+// @Failure 500 {object} APIResponse[any] "服务器内部错误"
 // @Router /sync/tasks/executions [get]
 func (c *SyncTaskController) GetSyncTaskExecutions(w http.ResponseWriter, r *http.Request) {
 	// 解析查询参数
 	req := SyncTaskExecutionListRequest{
-		Page:          1,
-		Size:          10,
 		TaskID:        r.URL.Query().Get("task_id"),
 		Status:        r.URL.Query().Get("status"),
 		ExecutionType: r.URL.Query().Get("execution_type"),
 	}
 
-	if page, err := strconv.Atoi(r.URL.Query().Get("page")); err == nil && page > 0 {
-		req.Page = page
-	}
-	if size, err := strconv.Atoi(r.URL.Query().Get("size")); err == nil && size > 0 && size <= 100 {
-		req.Size = size
-	}
+	req.Page, req.Size = parsePagination(r)
 
 	// 创建服务请求
 	serviceReq := &basic_library.GetSyncTaskExecutionListRequest{
@@ -659,7 +722,7 @@ func (c *SyncTaskController) GetSyncTaskExecutions(w http.ResponseWriter, r *htt
 
 	response, err := c.syncTaskService.GetSyncTaskExecutionList(r.Context(), serviceReq)
 	if err != nil {
-		render.JSON(w, r, InternalErrorResponse("获取同步任务执行记录列表失败", err))
+		render.JSON(w, r, MapErrorResponse("获取同步任务执行记录列表失败", err))
 		return
 	}
 
@@ -673,10 +736,10 @@ func (c *SyncTaskController) GetSyncTaskExecutions(w http.ResponseWriter, r *htt
 // @Accept json
 // @Produce json
 // @Param id path string true "执行记录ID"
-// @Success 200 {object} APIResponse{data=models.SyncTaskExecution} "获取成功"
-// @Failure 400 {object} APIResponse "请求参数错误"
-// @Failure 404 {object} APIResponse "执行记录不存在"
-// @Failure 500 {object} APIResponse "服务器内部错误"
+// @Success 200 {object} APIResponse[models.SyncTaskExecution] "获取成功"
+// @Failure 400 {object} APIResponse[any] "请求参数错误"
+// @Failure 404 {object} APIResponse[any] "执行记录不存在"
+// @Failure 500 {object} APIResponse[any] "服务器内部错误"
 // @Router /sync/tasks/executions/{id} [get]
 func (c *SyncTaskController) GetSyncTaskExecution(w http.ResponseWriter, r *http.Request) {
 	executionID := chi.URLParam(r, "id")
@@ -694,6 +757,26 @@ func (c *SyncTaskController) GetSyncTaskExecution(w http.ResponseWriter, r *http
 	render.JSON(w, r, SuccessResponse("获取同步任务执行记录成功", execution))
 }
 
+// GetSyncExecutionDiff 获取同步执行的数据变更摘要
+// @Summary 获取同步执行的数据变更摘要
+// @Description 返回一次基础库同步执行中各增量同步接口新增、更新、未变化的行数，新增和更新的主键样例（各最多100个）以及修改行数最多的列；全量同步的接口没有变更摘要
+// @Tags 基础库同步任务
+// @Produce json
+// @Param id path string true "执行记录ID"
+// @Success 200 {object} APIResponse[basic_library.ExecutionDiff] "获取成功"
+// @Failure 404 {object} APIResponse[any] "执行记录不存在"
+// @Failure 500 {object} APIResponse[any] "服务器内部错误"
+// @Router /sync-executions/{id}/diff [get]
+func (c *SyncTaskController) GetSyncExecutionDiff(w http.ResponseWriter, r *http.Request) {
+	diff, err := c.executionDiffService.GetExecutionDiff(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		render.JSON(w, r, MapErrorResponse("获取同步执行数据变更摘要失败", err))
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("获取同步执行数据变更摘要成功", diff))
+}
+
 // GetTaskExecutions 获取指定任务的执行记录
 // @Summary 获取指定任务的执行记录
 // @Description 获取指定同步任务的所有执行记录
@@ -703,9 +786,9 @@ func (c *SyncTaskController) GetSyncTaskExecution(w http.ResponseWriter, r *http
 // @Param id path string true "任务ID"
 // @Param page query int false "页码" default(1)
 // @Param size query int false "每页大小" default(10)
-// @Success 200 {object} APIResponse{data=basic_library.SyncTaskExecutionListResponse} "获取成功"
-// @Failure 400 {object} APIResponse "请求参数错误"
-// @Failure 500 {object} APIResponse "服务器内部错误"
+// @Success 200 {object} APIResponse[basic_library.SyncTaskExecutionListResponse] "获取成功"
+// @Failure 400 {object} APIResponse[any] "请求参数错误"
+// @Failure 500 {object} APIResponse[any] "服务器内部错误"
 // @Router /sync/tasks/{id}/executions [get]
 func (c *SyncTaskController) GetTaskExecutions(w http.ResponseWriter, r *http.Request) {
 	taskID := chi.URLParam(r, "id")
@@ -715,14 +798,7 @@ func (c *SyncTaskController) GetTaskExecutions(w http.ResponseWriter, r *http.Re
 	}
 
 	// 解析查询参数
-	page := 1
-	size := 10
-	if p, err := strconv.Atoi(r.URL.Query().Get("page")); err == nil && p > 0 {
-		page = p
-	}
-	if s, err := strconv.Atoi(r.URL.Query().Get("size")); err == nil && s > 0 && s <= 100 {
-		size = s
-	}
+	page, size := parsePagination(r)
 
 	// 创建服务请求
 	serviceReq := &basic_library.GetSyncTaskExecutionListRequest{
@@ -733,13 +809,35 @@ func (c *SyncTaskController) GetTaskExecutions(w http.ResponseWriter, r *http.Re
 
 	response, err := c.syncTaskService.GetSyncTaskExecutionList(r.Context(), serviceReq)
 	if err != nil {
-		render.JSON(w, r, InternalErrorResponse("获取任务执行记录列表失败", err))
+		render.JSON(w, r, MapErrorResponse("获取任务执行记录列表失败", err))
 		return
 	}
 
 	render.JSON(w, r, SuccessResponse("获取任务执行记录列表成功", response))
 }
 
+// GetSyncTaskFailureAnalysis 获取同步任务失败根因分析
+// @Summary 获取同步任务失败根因分析
+// @Description 按认证、网络、表结构、数据类型、约束、配额等分类统计任务近days天的失败次数和每日趋势，列出各失败码的说明、涉及接口、最近错误和处理建议
+// @Tags 基础库同步任务
+// @Produce json
+// @Param id path string true "任务ID"
+// @Param days query int false "统计天数，最大180" default(30)
+// @Success 200 {object} APIResponse[basic_library.TaskFailureAnalysis] "获取成功"
+// @Failure 404 {object} APIResponse[any] "任务不存在"
+// @Failure 500 {object} APIResponse[any] "服务器内部错误"
+// @Router /sync/tasks/{id}/failure-analysis [get]
+func (c *SyncTaskController) GetSyncTaskFailureAnalysis(w http.ResponseWriter, r *http.Request) {
+	days, _ := strconv.Atoi(r.URL.Query().Get("days"))
+	analysis, err := c.failureAnalysisService.AnalyzeTaskFailures(r.Context(), chi.URLParam(r, "id"), days)
+	if err != nil {
+		render.JSON(w, r, MapErrorResponse("获取同步任务失败分析失败", err))
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("获取同步任务失败分析成功", analysis))
+}
+
 // ActivateSyncTask 激活同步任务
 // @Summary 激活同步任务
 // @Description 激活同步任务，将 draft 或 paused 状态改为 active，并添加到调度器
@@ -755,11 +853,11 @@ func (c *SyncTaskController) GetTaskExecutions(w http.ResponseWriter, r *http.Re
 // @Accept json
 // @Produce json
 // @Param id path string true "任务ID"
-// @Success 200 {object} APIResponse "激活成功"
-// @Failure 400 {object} APIResponse "请求参数错误"
-// @Failure 404 {object} APIResponse "任务不存在"
-// @Failure 409 {object} APIResponse "任务状态不允许激活"
-// @Failure 500 {object} APIResponse "服务器内部错误"
+// @Success 200 {object} APIResponse[any] "激活成功"
+// @Failure 400 {object} APIResponse[any] "请求参数错误"
+// @Failure 404 {object} APIResponse[any] "任务不存在"
+// @Failure 409 {object} APIResponse[any] "任务状态不允许激活"
+// @Failure 500 {object} APIResponse[any] "服务器内部错误"
 // @Router /sync/tasks/{id}/activate [post]
 func (c *SyncTaskController) ActivateSyncTask(w http.ResponseWriter, r *http.Request) {
 	taskID := chi.URLParam(r, "id")
@@ -770,7 +868,7 @@ func (c *SyncTaskController) ActivateSyncTask(w http.ResponseWriter, r *http.Req
 
 	err := c.syncTaskService.ActivateSyncTask(r.Context(), taskID)
 	if err != nil {
-		render.JSON(w, r, ErrorResponse(http.StatusInternalServerError, "激活同步任务失败", err))
+		render.JSON(w, r, MapErrorResponse("激活同步任务失败", err))
 		return
 	}
 
@@ -792,11 +890,11 @@ func (c *SyncTaskController) ActivateSyncTask(w http.ResponseWriter, r *http.Req
 // @Accept json
 // @Produce json
 // @Param id path string true "任务ID"
-// @Success 200 {object} APIResponse "暂停成功"
-// @Failure 400 {object} APIResponse "请求参数错误"
-// @Failure 404 {object} APIResponse "任务不存在"
-// @Failure 409 {object} APIResponse "任务状态不允许暂停"
-// @Failure 500 {object} APIResponse "服务器内部错误"
+// @Success 200 {object} APIResponse[any] "暂停成功"
+// @Failure 400 {object} APIResponse[any] "请求参数错误"
+// @Failure 404 {object} APIResponse[any] "任务不存在"
+// @Failure 409 {object} APIResponse[any] "任务状态不允许暂停"
+// @Failure 500 {object} APIResponse[any] "服务器内部错误"
 // @Router /sync/tasks/{id}/pause [post]
 func (c *SyncTaskController) PauseSyncTask(w http.ResponseWriter, r *http.Request) {
 	taskID := chi.URLParam(r, "id")
@@ -807,7 +905,7 @@ func (c *SyncTaskController) PauseSyncTask(w http.ResponseWriter, r *http.Reques
 
 	err := c.syncTaskService.PauseSyncTask(r.Context(), taskID)
 	if err != nil {
-		render.JSON(w, r, ErrorResponse(http.StatusInternalServerError, "暂停同步任务失败", err))
+		render.JSON(w, r, MapErrorResponse("暂停同步任务失败", err))
 		return
 	}
 
@@ -829,11 +927,11 @@ func (c *SyncTaskController) PauseSyncTask(w http.ResponseWriter, r *http.Reques
 // @Accept json
 // @Produce json
 // @Param id path string true "任务ID"
-// @Success 200 {object} APIResponse "恢复成功"
-// @Failure 400 {object} APIResponse "请求参数错误"
-// @Failure 404 {object} APIResponse "任务不存在"
-// @Failure 409 {object} APIResponse "任务状态不允许恢复"
-// @Failure 500 {object} APIResponse "服务器内部错误"
+// @Success 200 {object} APIResponse[any] "恢复成功"
+// @Failure 400 {object} APIResponse[any] "请求参数错误"
+// @Failure 404 {object} APIResponse[any] "任务不存在"
+// @Failure 409 {object} APIResponse[any] "任务状态不允许恢复"
+// @Failure 500 {object} APIResponse[any] "服务器内部错误"
 // @Router /sync/tasks/{id}/resume [post]
 func (c *SyncTaskController) ResumeSyncTask(w http.ResponseWriter, r *http.Request) {
 	taskID := chi.URLParam(r, "id")
@@ -844,7 +942,7 @@ func (c *SyncTaskController) ResumeSyncTask(w http.ResponseWriter, r *http.Reque
 
 	err := c.syncTaskService.ResumeSyncTask(r.Context(), taskID)
 	if err != nil {
-		render.JSON(w, r, ErrorResponse(http.StatusInternalServerError, "恢复同步任务失败", err))
+		render.JSON(w, r, MapErrorResponse("恢复同步任务失败", err))
 		return
 	}
 
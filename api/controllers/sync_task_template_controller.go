@@ -0,0 +1,304 @@
+/*
+ * @module api/controllers/sync_task_template_controller
+ * @description 同步任务模板控制器，提供模板的查询、创建、修改、删除，按模板创建同步任务，以及关联任务的查询、同步和解除关联
+ * @architecture 分层架构 - 控制器层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow HTTP请求 -> 控制器 -> 同步任务模板服务 -> 保存模板 / 通过同步任务服务创建和修改任务
+ * @rules 统一的错误处理和响应格式；模板不合法时返回400；修改模板支持If-Match乐观锁；操作人取自当前登录用户
+ * @dependencies datahub-service/service, datahub-service/service/basic_library, github.com/go-chi/chi/v5
+ * @refs service/basic_library/sync_task_template_service.go, service/models/sync_task_template.go
+ */
+
+package controllers
+
+import (
+	"datahub-service/service"
+	"datahub-service/service/basic_library"
+	"datahub-service/service/models"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+)
+
+// SyncTaskTemplateController 同步任务模板控制器
+type SyncTaskTemplateController struct {
+}
+
+// NewSyncTaskTemplateController 创建同步任务模板控制器实例
+func NewSyncTaskTemplateController() *SyncTaskTemplateController {
+	return &SyncTaskTemplateController{}
+}
+
+// SyncTaskTemplateRequest 创建或修改同步任务模板请求
+type SyncTaskTemplateRequest struct {
+	Name                 string                 `json:"name" validate:"required,max=100" example:"夜间全量同步"`
+	Description          string                 `json:"description" example:"每天凌晨2点全量同步，失败重试3次"`
+	TaskType             string                 `json:"task_type" validate:"required" example:"batch_sync"`                         // batch_sync, realtime_sync
+	TriggerType          string                 `json:"trigger_type" validate:"required,oneof=manual interval cron" example:"cron"` // manual, interval, cron
+	CronExpression       string                 `json:"cron_expression,omitempty" example:"0 0 2 * * *"`
+	IntervalSeconds      int                    `json:"interval_seconds,omitempty" example:"3600"`
+	BatchConfig          map[string]interface{} `json:"batch_config,omitempty"`                                                           // 写入任务配置的批次参数，如batch_size、timeout
+	MaxRetries           int                    `json:"max_retries" validate:"min=0,max=10" example:"3"`                                  // 接口可重试失败的最大重试次数，0表示不重试
+	RetryIntervalSeconds int                    `json:"retry_interval_seconds" validate:"min=0,max=3600" example:"60"`                    // 重试间隔秒数，第n次重试等待n倍间隔
+	QualityGateMinScore  *float64               `json:"quality_gate_min_score,omitempty" validate:"omitempty,min=0,max=100" example:"90"` // 质量门禁最低评分，为空时不改变任务的质量门禁
+	RowVersion           int64                  `json:"row_version,omitempty" example:"3"`                                                // 修改时的期望版本，也可通过If-Match头传递
+	Propagate            bool                   `json:"propagate,omitempty" example:"true"`                                               // 修改时是否同步到关联的任务
+}
+
+// CreateTaskFromTemplateRequest 按模板创建同步任务请求
+type CreateTaskFromTemplateRequest struct {
+	LibraryID        string                    `json:"library_id" validate:"required" example:"550e8400-e29b-41d4-a716-446655440000"`
+	DataSourceID     string                    `json:"data_source_id" validate:"required" example:"550e8400-e29b-41d4-a716-446655440001"`
+	InterfaceIDs     []string                  `json:"interface_ids" validate:"required,min=1"`
+	InterfaceConfigs []SyncTaskInterfaceConfig `json:"interface_configs,omitempty"`
+	Config           map[string]interface{}    `json:"config,omitempty"`                 // 任务自己的配置，与模板批次参数重名的以模板为准
+	Detach           bool                      `json:"detach,omitempty" example:"false"` // 为true时只按模板创建，不关联模板
+}
+
+// SyncTaskTemplateUpdateResult 修改同步任务模板的结果
+type SyncTaskTemplateUpdateResult struct {
+	Template    *models.SyncTaskTemplate                 `json:"template"`
+	Propagation *basic_library.TemplatePropagationResult `json:"propagation,omitempty"` // 选择同步到关联任务时返回
+}
+
+// toTemplate 转换为模板
+func (req *SyncTaskTemplateRequest) toTemplate() *models.SyncTaskTemplate {
+	return &models.SyncTaskTemplate{
+		Name:                 req.Name,
+		Description:          req.Description,
+		TaskType:             req.TaskType,
+		TriggerType:          req.TriggerType,
+		CronExpression:       req.CronExpression,
+		IntervalSeconds:      req.IntervalSeconds,
+		BatchConfig:          req.BatchConfig,
+		MaxRetries:           req.MaxRetries,
+		RetryIntervalSeconds: req.RetryIntervalSeconds,
+		QualityGateMinScore:  req.QualityGateMinScore,
+	}
+}
+
+// GetSyncTaskTemplates 获取同步任务模板列表
+// @Summary 获取同步任务模板列表
+// @Description 获取同步任务模板及各模板关联的任务数，可按名称模糊查询
+// @Tags 基础库同步任务
+// @Produce json
+// @Param name query string false "模板名称"
+// @Success 200 {object} APIResponse[[]models.SyncTaskTemplate] "获取成功"
+// @Router /sync/task-templates [get]
+func (c *SyncTaskTemplateController) GetSyncTaskTemplates(w http.ResponseWriter, r *http.Request) {
+	templates, err := service.GlobalSyncTaskTemplateService.ListTemplates(r.Context(), r.URL.Query().Get("name"))
+	if err != nil {
+		render.JSON(w, r, InternalErrorResponse("获取同步任务模板列表失败", err))
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("获取同步任务模板列表成功", templates))
+}
+
+// GetSyncTaskTemplate 获取同步任务模板详情
+// @Summary 获取同步任务模板详情
+// @Description 获取同步任务模板的调度、批次参数、重试策略和质量门禁，ETag为模板版本
+// @Tags 基础库同步任务
+// @Produce json
+// @Param id path string true "模板ID"
+// @Success 200 {object} APIResponse[models.SyncTaskTemplate] "获取成功"
+// @Failure 404 {object} APIResponse[any] "模板不存在"
+// @Router /sync/task-templates/{id} [get]
+func (c *SyncTaskTemplateController) GetSyncTaskTemplate(w http.ResponseWriter, r *http.Request) {
+	template, err := service.GlobalSyncTaskTemplateService.GetTemplate(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		render.JSON(w, r, MapErrorResponse("获取同步任务模板失败", err))
+		return
+	}
+
+	setETag(w, template.RowVersion)
+	render.JSON(w, r, SuccessResponse("获取同步任务模板成功", template))
+}
+
+// CreateSyncTaskTemplate 创建同步任务模板
+// @Summary 创建同步任务模板
+// @Description 创建可复用的同步任务模板，包括任务类型、执行时机（manual/interval/cron）、批次参数、重试策略和质量门禁最低评分。单次执行（once）的计划时间因任务而异，不支持放在模板中
+// @Tags 基础库同步任务
+// @Accept json
+// @Produce json
+// @Param request body SyncTaskTemplateRequest true "同步任务模板"
+// @Success 200 {object} APIResponse[models.SyncTaskTemplate] "创建成功"
+// @Failure 400 {object} APIResponse[any] "模板不合法"
+// @Router /sync/task-templates [post]
+func (c *SyncTaskTemplateController) CreateSyncTaskTemplate(w http.ResponseWriter, r *http.Request) {
+	var req SyncTaskTemplateRequest
+	if !decodeAndValidate(w, r, &req) {
+		return
+	}
+
+	template, err := service.GlobalSyncTaskTemplateService.CreateTemplate(r.Context(), req.toTemplate(), getCurrentUsername(r))
+	if err != nil {
+		respondSyncTaskTemplateError(w, r, "创建同步任务模板失败", err)
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("创建同步任务模板成功", template))
+}
+
+// UpdateSyncTaskTemplate 修改同步任务模板
+// @Summary 修改同步任务模板
+// @Description 修改模板的全部配置，模板版本加1。propagate为true时把新配置同步到关联的任务（沿用同步任务的修改逻辑，重算下次执行时间并刷新调度），返回每个任务的同步结果；为false时关联任务标记为版本落后，可稍后调用同步接口
+// @Tags 基础库同步任务
+// @Accept json
+// @Produce json
+// @Param id path string true "模板ID"
+// @Param If-Match header string false "期望的模板版本"
+// @Param request body SyncTaskTemplateRequest true "同步任务模板"
+// @Success 200 {object} APIResponse[SyncTaskTemplateUpdateResult] "修改成功"
+// @Failure 400 {object} APIResponse[any] "模板不合法"
+// @Failure 404 {object} APIResponse[any] "模板不存在"
+// @Failure 409 {object} APIResponse[VersionConflictData] "版本冲突"
+// @Router /sync/task-templates/{id} [put]
+func (c *SyncTaskTemplateController) UpdateSyncTaskTemplate(w http.ResponseWriter, r *http.Request) {
+	var req SyncTaskTemplateRequest
+	if !decodeAndValidate(w, r, &req) {
+		return
+	}
+	expectedVersion, err := expectedRowVersion(r, req.RowVersion)
+	if err != nil {
+		render.JSON(w, r, BadRequestResponse("请求参数错误", err))
+		return
+	}
+
+	template, propagation, err := service.GlobalSyncTaskTemplateService.UpdateTemplate(r.Context(), chi.URLParam(r, "id"), req.toTemplate(), expectedVersion, req.Propagate, getCurrentUsername(r))
+	if err != nil {
+		if handleVersionConflict(w, r, err) {
+			return
+		}
+		respondSyncTaskTemplateError(w, r, "修改同步任务模板失败", err)
+		return
+	}
+
+	setETag(w, template.RowVersion)
+	render.JSON(w, r, SuccessResponse("修改同步任务模板成功", SyncTaskTemplateUpdateResult{Template: template, Propagation: propagation}))
+}
+
+// DeleteSyncTaskTemplate 删除同步任务模板
+// @Summary 删除同步任务模板
+// @Description 删除模板，关联的任务解除关联，任务自身的配置不变
+// @Tags 基础库同步任务
+// @Produce json
+// @Param id path string true "模板ID"
+// @Success 200 {object} APIResponse[any] "删除成功"
+// @Failure 404 {object} APIResponse[any] "模板不存在"
+// @Router /sync/task-templates/{id} [delete]
+func (c *SyncTaskTemplateController) DeleteSyncTaskTemplate(w http.ResponseWriter, r *http.Request) {
+	if err := service.GlobalSyncTaskTemplateService.DeleteTemplate(r.Context(), chi.URLParam(r, "id"), getCurrentUsername(r)); err != nil {
+		render.JSON(w, r, MapErrorResponse("删除同步任务模板失败", err))
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("删除同步任务模板成功", nil))
+}
+
+// CreateSyncTaskFromTemplate 按模板创建同步任务
+// @Summary 按模板创建同步任务
+// @Description 按模板的任务类型、执行时机、批次参数、重试策略和质量门禁创建基础库同步任务（草稿状态），请求只需提供基础库、数据源和接口。默认关联模板，模板修改后可同步到该任务；detach为true时只复制模板配置
+// @Tags 基础库同步任务
+// @Accept json
+// @Produce json
+// @Param id path string true "模板ID"
+// @Param request body CreateTaskFromTemplateRequest true "任务的基础库、数据源和接口"
+// @Success 200 {object} APIResponse[models.SyncTask] "创建成功"
+// @Failure 400 {object} APIResponse[any] "请求参数错误或模板的质量门禁不适用于任务接口"
+// @Failure 404 {object} APIResponse[any] "模板不存在"
+// @Router /sync/task-templates/{id}/tasks [post]
+func (c *SyncTaskTemplateController) CreateSyncTaskFromTemplate(w http.ResponseWriter, r *http.Request) {
+	var req CreateTaskFromTemplateRequest
+	if !decodeAndValidate(w, r, &req) {
+		return
+	}
+
+	interfaceConfigs := make([]basic_library.SyncTaskInterfaceConfig, 0, len(req.InterfaceConfigs))
+	for _, config := range req.InterfaceConfigs {
+		interfaceConfigs = append(interfaceConfigs, basic_library.SyncTaskInterfaceConfig{InterfaceID: config.InterfaceID, Config: config.Config})
+	}
+	task, err := service.GlobalSyncTaskTemplateService.CreateTask(r.Context(), chi.URLParam(r, "id"), &basic_library.CreateTaskFromTemplateRequest{
+		LibraryID:        req.LibraryID,
+		DataSourceID:     req.DataSourceID,
+		InterfaceIDs:     req.InterfaceIDs,
+		InterfaceConfigs: interfaceConfigs,
+		Config:           req.Config,
+		Detach:           req.Detach,
+		CreatedBy:        getCurrentUsername(r),
+	})
+	if err != nil {
+		respondSyncTaskTemplateError(w, r, "按模板创建同步任务失败", err)
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("按模板创建同步任务成功", task))
+}
+
+// GetSyncTaskTemplateTasks 获取关联模板的同步任务
+// @Summary 获取关联模板的同步任务
+// @Description 获取关联模板的同步任务，outdated为true表示任务应用的模板版本落后于模板，尚未同步最新修改
+// @Tags 基础库同步任务
+// @Produce json
+// @Param id path string true "模板ID"
+// @Success 200 {object} APIResponse[[]basic_library.LinkedSyncTask] "获取成功"
+// @Failure 404 {object} APIResponse[any] "模板不存在"
+// @Router /sync/task-templates/{id}/tasks [get]
+func (c *SyncTaskTemplateController) GetSyncTaskTemplateTasks(w http.ResponseWriter, r *http.Request) {
+	tasks, err := service.GlobalSyncTaskTemplateService.ListTasks(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		render.JSON(w, r, MapErrorResponse("获取关联任务失败", err))
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("获取关联任务成功", tasks))
+}
+
+// PropagateSyncTaskTemplate 同步模板到关联任务
+// @Summary 同步模板到关联任务
+// @Description 把模板当前版本同步到版本落后的关联任务，单个任务失败（如任务正被修改导致版本冲突、接口上没有质量检测）不影响其他任务，失败的任务可再次调用同步
+// @Tags 基础库同步任务
+// @Produce json
+// @Param id path string true "模板ID"
+// @Success 200 {object} APIResponse[basic_library.TemplatePropagationResult] "同步完成"
+// @Failure 404 {object} APIResponse[any] "模板不存在"
+// @Router /sync/task-templates/{id}/propagate [post]
+func (c *SyncTaskTemplateController) PropagateSyncTaskTemplate(w http.ResponseWriter, r *http.Request) {
+	result, err := service.GlobalSyncTaskTemplateService.Propagate(r.Context(), chi.URLParam(r, "id"), getCurrentUsername(r))
+	if err != nil {
+		render.JSON(w, r, MapErrorResponse("同步模板到关联任务失败", err))
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("同步模板到关联任务完成", result))
+}
+
+// UnlinkSyncTaskTemplateTask 解除任务与模板的关联
+// @Summary 解除任务与模板的关联
+// @Description 解除后任务保留当前配置，模板后续的修改不再同步到该任务
+// @Tags 基础库同步任务
+// @Produce json
+// @Param id path string true "模板ID"
+// @Param task_id path string true "同步任务ID"
+// @Success 200 {object} APIResponse[any] "解除成功"
+// @Failure 404 {object} APIResponse[any] "任务未关联该模板"
+// @Router /sync/task-templates/{id}/tasks/{task_id} [delete]
+func (c *SyncTaskTemplateController) UnlinkSyncTaskTemplateTask(w http.ResponseWriter, r *http.Request) {
+	if err := service.GlobalSyncTaskTemplateService.UnlinkTask(r.Context(), chi.URLParam(r, "id"), chi.URLParam(r, "task_id"), getCurrentUsername(r)); err != nil {
+		render.JSON(w, r, MapErrorResponse("解除模板关联失败", err))
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("解除模板关联成功", nil))
+}
+
+// respondSyncTaskTemplateError 模板不合法时返回400，其余按错误类型映射
+func respondSyncTaskTemplateError(w http.ResponseWriter, r *http.Request, msg string, err error) {
+	if errors.Is(err, basic_library.ErrInvalidSyncTaskTemplate) {
+		render.JSON(w, r, BadRequestResponse(msg, err))
+		return
+	}
+	render.JSON(w, r, MapErrorResponse(msg, err))
+}
@@ -26,20 +26,19 @@ func NewTableController() *TableController {
 // @Accept json
 // @Produce json
 // @Param request body models.TableSchemaRequest true "表结构操作请求"
-// @Success 200 {object} APIResponse
-// @Failure 400 {object} APIResponse
-// @Failure 500 {object} APIResponse
+// @Success 200 {object} APIResponse[any]
+// @Failure 400 {object} APIResponse[any]
+// @Failure 500 {object} APIResponse[any]
 // @Router /tables/manage-schema [post]
 func (c *TableController) ManageTableSchema(w http.ResponseWriter, r *http.Request) {
 	var req models.TableSchemaRequest
-	if err := render.DecodeJSON(r.Body, &req); err != nil {
-		render.JSON(w, r, BadRequestResponse( "请求参数格式错误", err))
+	if !decodeAndValidate(w, r, &req) {
 		return
 	}
 
 	err := c.service.ManageTableSchema(req.InterfaceID, req.Operation, req.SchemaName, req.TableName, req.Fields)
 	if err != nil {
-		render.JSON(w, r, InternalErrorResponse( "表结构操作失败: "+err.Error(), err))
+		render.JSON(w, r, MapErrorResponse( "表结构操作失败: "+err.Error(), err))
 		return
 	}
 
@@ -0,0 +1,92 @@
+/*
+ * @module api/controllers/telemetry_controller
+ * @description 设备遥测数据快速写入控制器，接收行协议或JSON数组格式的批量数据
+ * @architecture 分层架构 - 控制器层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow 机器调用方POST -> ApiKey认证(ingest:write) -> 读取请求体 -> 遥测写入服务批量写入 -> 返回写入行数
+ * @rules 与其他管理接口不同，响应的HTTP状态码与业务状态码一致，便于采集端按状态码重试：
+ *        格式错误400、接口未开启遥测写入403、接口不存在404、请求体过大413、繁忙或写入失败503；请求体最大maxTelemetryBodySize
+ * @dependencies datahub-service/service, datahub-service/service/telemetry, github.com/go-chi/chi/v5
+ * @refs service/telemetry/service.go
+ */
+
+package controllers
+
+import (
+	"datahub-service/service"
+	"datahub-service/service/telemetry"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"gorm.io/gorm"
+)
+
+// maxTelemetryBodySize 遥测请求体最大字节数
+const maxTelemetryBodySize = 16 << 20
+
+// TelemetryController 设备遥测数据写入控制器
+type TelemetryController struct {
+}
+
+// NewTelemetryController 创建设备遥测数据写入控制器实例
+func NewTelemetryController() *TelemetryController {
+	return &TelemetryController{}
+}
+
+// IngestTelemetry 批量写入设备遥测数据
+// @Summary 批量写入设备遥测数据
+// @Description 接收InfluxDB行协议（measurement,标签=值 字段=值 时间戳）或JSON对象数组，标签和字段按列名写入接口表，时间戳写入接口的时间列，未知字段丢弃。多个请求的数据汇成批次后一次写入，写入成功后才返回，失败返回503，调用方重试时主键冲突的行被忽略。目标接口须已开启时间序列存储并允许遥测写入
+// @Tags 数据基础库
+// @Accept plain
+// @Accept json
+// @Produce json
+// @Param interface_id path string true "基础库接口ID"
+// @Param format query string false "格式：line或json，默认按Content-Type和请求体判断"
+// @Param precision query string false "整数时间戳精度：ns、us、ms、s，行协议默认ns，JSON默认ms"
+// @Param Authorization header string true "Bearer Token格式的API Key，须有ingest:write范围"
+// @Success 200 {object} APIResponse[telemetry.IngestResult] "写入成功"
+// @Failure 400 {object} APIResponse[any] "数据格式不正确"
+// @Failure 403 {object} APIResponse[any] "接口未允许遥测写入"
+// @Failure 404 {object} APIResponse[any] "接口不存在"
+// @Failure 413 {object} APIResponse[any] "请求体过大"
+// @Failure 503 {object} APIResponse[any] "繁忙或写入失败，请重试"
+// @Router /api/v1/ingest/telemetry/{interface_id} [post]
+func (c *TelemetryController) IngestTelemetry(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxTelemetryBodySize))
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			respondTelemetry(w, r, http.StatusRequestEntityTooLarge, ErrorResponse(http.StatusRequestEntityTooLarge, "请求体过大，最大16MB", nil))
+			return
+		}
+		respondTelemetry(w, r, http.StatusBadRequest, BadRequestResponse("读取请求体失败", err))
+		return
+	}
+
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = telemetry.DetectFormat(r.Header.Get("Content-Type"), body)
+	}
+	result, err := service.GlobalTelemetryService.Ingest(r.Context(), chi.URLParam(r, "interface_id"), body, format, r.URL.Query().Get("precision"))
+	switch {
+	case err == nil:
+		render.JSON(w, r, SuccessResponse("遥测数据写入成功", result))
+	case errors.Is(err, telemetry.ErrInvalidPayload):
+		respondTelemetry(w, r, http.StatusBadRequest, BadRequestResponse("遥测数据写入失败", err))
+	case errors.Is(err, telemetry.ErrIngestNotEnabled):
+		respondTelemetry(w, r, http.StatusForbidden, ErrorResponse(StatusForbidden, "遥测数据写入失败", err))
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		respondTelemetry(w, r, http.StatusNotFound, NotFoundResponse("遥测数据写入失败: 接口不存在", err))
+	default:
+		respondTelemetry(w, r, http.StatusServiceUnavailable, ErrorResponse(StatusServiceUnavailable, "遥测数据写入失败，请重试", err))
+	}
+}
+
+// respondTelemetry 以与业务状态码一致的HTTP状态码返回
+func respondTelemetry(w http.ResponseWriter, r *http.Request, httpStatus int, response render.Renderer) {
+	render.Status(r, httpStatus)
+	render.JSON(w, r, response)
+}
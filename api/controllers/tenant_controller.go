@@ -0,0 +1,291 @@
+/*
+ * @module api/controllers/tenant_controller
+ * @description 租户管理控制器，提供租户的增删改查、成员维护和当前租户查询接口
+ * @architecture 分层架构 - 控制器层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow HTTP请求 -> 控制器 -> 租户服务 -> 数据库
+ * @rules 统一的错误处理和响应格式；租户编码创建后不可修改
+ * @dependencies datahub-service/service, github.com/go-chi/chi/v5
+ * @refs service/tenant, api/middleware/tenant.go
+ */
+
+package controllers
+
+import (
+	"datahub-service/service"
+	"datahub-service/service/models"
+	"datahub-service/service/tenant"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"gorm.io/gorm"
+)
+
+// TenantController 租户管理控制器
+type TenantController struct {
+}
+
+// NewTenantController 创建租户管理控制器实例
+func NewTenantController() *TenantController {
+	return &TenantController{}
+}
+
+// CreateTenantRequest 创建租户请求结构
+type CreateTenantRequest struct {
+	Code        string `json:"code" validate:"required" example:"park_a"`
+	Name        string `json:"name" validate:"required" example:"A园区运营公司"`
+	Description string `json:"description"`
+}
+
+// UpdateTenantRequest 更新租户请求结构
+type UpdateTenantRequest struct {
+	Name        *string `json:"name,omitempty"`
+	Description *string `json:"description,omitempty"`
+	Status      *string `json:"status,omitempty" example:"disabled"`
+}
+
+// AddTenantMemberRequest 添加租户成员请求结构
+type AddTenantMemberRequest struct {
+	Username string `json:"username" validate:"required"`
+}
+
+// TenantListResponse 租户列表响应结构
+type TenantListResponse struct {
+	List []models.Tenant `json:"list"`
+	models.PageMeta
+}
+
+// CurrentTenantResponse 当前租户响应结构
+type CurrentTenantResponse struct {
+	Enabled bool   `json:"enabled"`        // 是否启用多租户
+	Scoped  bool   `json:"scoped"`         // 当前请求是否限定租户，管理员未指定租户时为false
+	ID      string `json:"id,omitempty"`   // 租户ID
+	Code    string `json:"code,omitempty"` // 租户编码
+}
+
+// GetCurrentTenant 获取当前请求的租户
+// @Summary 获取当前租户
+// @Description 获取当前用户本次请求解析出的租户，可通过X-Tenant-ID请求头切换
+// @Tags 租户管理
+// @Produce json
+// @Success 200 {object} APIResponse[CurrentTenantResponse] "获取成功"
+// @Router /tenants/current [get]
+func (c *TenantController) GetCurrentTenant(w http.ResponseWriter, r *http.Request) {
+	resp := CurrentTenantResponse{Enabled: service.GlobalTenantService.IsEnabled()}
+	if info, ok := tenant.FromContext(r.Context()); ok {
+		resp.Scoped = true
+		resp.ID = info.ID
+		resp.Code = info.Code
+	}
+	render.JSON(w, r, SuccessResponse("获取当前租户成功", resp))
+}
+
+// GetTenants 获取租户列表
+// @Summary 获取租户列表
+// @Description 分页获取租户列表
+// @Tags 租户管理
+// @Produce json
+// @Param page query int false "页码" default(1)
+// @Param size query int false "每页数量" default(10)
+// @Param status query string false "状态" Enums(active, disabled)
+// @Success 200 {object} APIResponse[TenantListResponse] "获取成功"
+// @Router /tenants [get]
+func (c *TenantController) GetTenants(w http.ResponseWriter, r *http.Request) {
+	page, size := parsePagination(r)
+
+	tenants, total, err := service.GlobalTenantService.GetTenants(page, size, r.URL.Query().Get("status"))
+	if err != nil {
+		render.JSON(w, r, MapErrorResponse("获取租户列表失败", err))
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("获取租户列表成功", TenantListResponse{
+		List:     tenants,
+		PageMeta: models.NewPageMeta(total, page, size),
+	}))
+}
+
+// CreateTenant 创建租户
+// @Summary 创建租户
+// @Description 创建租户，编码只能包含小写字母、数字和下划线，作为该租户接口表schema的前缀
+// @Tags 租户管理
+// @Accept json
+// @Produce json
+// @Param request body CreateTenantRequest true "租户信息"
+// @Success 200 {object} APIResponse[models.Tenant] "创建成功"
+// @Failure 400 {object} APIResponse[any] "请求参数错误"
+// @Router /tenants [post]
+func (c *TenantController) CreateTenant(w http.ResponseWriter, r *http.Request) {
+	var req CreateTenantRequest
+	if !decodeAndValidate(w, r, &req) {
+		return
+	}
+
+	operator := getCurrentUsername(r)
+	t := &models.Tenant{
+		Code:        req.Code,
+		Name:        req.Name,
+		Description: req.Description,
+		CreatedBy:   operator,
+		UpdatedBy:   operator,
+	}
+	if err := service.GlobalTenantService.CreateTenant(t); err != nil {
+		render.JSON(w, r, BadRequestResponse("创建租户失败", err))
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("创建租户成功", t))
+}
+
+// GetTenant 获取租户详情
+// @Summary 获取租户详情
+// @Description 根据ID获取租户详情
+// @Tags 租户管理
+// @Produce json
+// @Param id path string true "租户ID"
+// @Success 200 {object} APIResponse[models.Tenant] "获取成功"
+// @Failure 404 {object} APIResponse[any] "租户不存在"
+// @Router /tenants/{id} [get]
+func (c *TenantController) GetTenant(w http.ResponseWriter, r *http.Request) {
+	t, err := service.GlobalTenantService.GetTenant(chi.URLParam(r, "id"))
+	if err != nil {
+		render.JSON(w, r, MapErrorResponse("获取租户详情失败", err))
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("获取租户详情成功", t))
+}
+
+// UpdateTenant 更新租户
+// @Summary 更新租户
+// @Description 更新租户名称、描述和状态，停用后其成员无法访问该租户
+// @Tags 租户管理
+// @Accept json
+// @Produce json
+// @Param id path string true "租户ID"
+// @Param request body UpdateTenantRequest true "更新内容"
+// @Success 200 {object} APIResponse[any] "更新成功"
+// @Failure 400 {object} APIResponse[any] "请求参数错误"
+// @Failure 404 {object} APIResponse[any] "租户不存在"
+// @Router /tenants/{id} [put]
+func (c *TenantController) UpdateTenant(w http.ResponseWriter, r *http.Request) {
+	var req UpdateTenantRequest
+	if !decodeAndValidate(w, r, &req) {
+		return
+	}
+
+	updates := map[string]interface{}{"updated_by": getCurrentUsername(r)}
+	if req.Name != nil {
+		updates["name"] = *req.Name
+	}
+	if req.Description != nil {
+		updates["description"] = *req.Description
+	}
+	if req.Status != nil {
+		updates["status"] = *req.Status
+	}
+
+	if err := service.GlobalTenantService.UpdateTenant(chi.URLParam(r, "id"), updates); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			render.JSON(w, r, NotFoundResponse("租户不存在", err))
+			return
+		}
+		render.JSON(w, r, BadRequestResponse("更新租户失败", err))
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("更新租户成功", nil))
+}
+
+// DeleteTenant 删除租户
+// @Summary 删除租户
+// @Description 删除租户及其成员关系，租户下仍有数据基础库或主题库时不允许删除
+// @Tags 租户管理
+// @Produce json
+// @Param id path string true "租户ID"
+// @Success 200 {object} APIResponse[any] "删除成功"
+// @Failure 400 {object} APIResponse[any] "租户下存在数据"
+// @Failure 404 {object} APIResponse[any] "租户不存在"
+// @Router /tenants/{id} [delete]
+func (c *TenantController) DeleteTenant(w http.ResponseWriter, r *http.Request) {
+	if err := service.GlobalTenantService.DeleteTenant(chi.URLParam(r, "id")); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			render.JSON(w, r, NotFoundResponse("租户不存在", err))
+			return
+		}
+		render.JSON(w, r, BadRequestResponse("删除租户失败", err))
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("删除租户成功", nil))
+}
+
+// GetTenantMembers 获取租户成员列表
+// @Summary 获取租户成员列表
+// @Description 获取指定租户的全部成员
+// @Tags 租户管理
+// @Produce json
+// @Param id path string true "租户ID"
+// @Success 200 {object} APIResponse[[]models.TenantMember] "获取成功"
+// @Router /tenants/{id}/members [get]
+func (c *TenantController) GetTenantMembers(w http.ResponseWriter, r *http.Request) {
+	members, err := service.GlobalTenantService.GetMembers(chi.URLParam(r, "id"))
+	if err != nil {
+		render.JSON(w, r, MapErrorResponse("获取租户成员失败", err))
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("获取租户成员成功", members))
+}
+
+// AddTenantMember 添加租户成员
+// @Summary 添加租户成员
+// @Description 将用户加入租户，用户可加入多个租户并通过X-Tenant-ID请求头切换
+// @Tags 租户管理
+// @Accept json
+// @Produce json
+// @Param id path string true "租户ID"
+// @Param request body AddTenantMemberRequest true "成员信息"
+// @Success 200 {object} APIResponse[models.TenantMember] "添加成功"
+// @Failure 400 {object} APIResponse[any] "请求参数错误"
+// @Failure 404 {object} APIResponse[any] "租户不存在"
+// @Router /tenants/{id}/members [post]
+func (c *TenantController) AddTenantMember(w http.ResponseWriter, r *http.Request) {
+	var req AddTenantMemberRequest
+	if !decodeAndValidate(w, r, &req) {
+		return
+	}
+
+	member, err := service.GlobalTenantService.AddMember(chi.URLParam(r, "id"), req.Username, getCurrentUsername(r))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			render.JSON(w, r, NotFoundResponse("租户不存在", err))
+			return
+		}
+		render.JSON(w, r, BadRequestResponse("添加租户成员失败", err))
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("添加租户成员成功", member))
+}
+
+// RemoveTenantMember 移除租户成员
+// @Summary 移除租户成员
+// @Description 将用户移出租户
+// @Tags 租户管理
+// @Produce json
+// @Param id path string true "租户ID"
+// @Param username path string true "用户名"
+// @Success 200 {object} APIResponse[any] "移除成功"
+// @Failure 404 {object} APIResponse[any] "成员不存在"
+// @Router /tenants/{id}/members/{username} [delete]
+func (c *TenantController) RemoveTenantMember(w http.ResponseWriter, r *http.Request) {
+	if err := service.GlobalTenantService.RemoveMember(chi.URLParam(r, "id"), chi.URLParam(r, "username")); err != nil {
+		render.JSON(w, r, MapErrorResponse("移除租户成员失败", err))
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("移除租户成员成功", nil))
+}
@@ -0,0 +1,95 @@
+/*
+ * @module api/controllers/thematic_changelog_controller
+ * @description 主题接口变更日志API，提供变更日志策略的查询、设置和删除，下游通过共享API按游标拉取变更
+ * @architecture MVC架构 - 控制器层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow 设置变更日志策略 -> 主题同步写入时记录变更 -> 下游通过/api/v1/share/changelog/{interface_id}拉取
+ * @rules 操作人取自当前登录用户；策略不合法返回400
+ * @dependencies datahub-service/service/thematic_library, github.com/go-chi/render
+ * @refs service/thematic_library/changelog.go, service/models/thematic_changelog.go
+ */
+
+package controllers
+
+import (
+	"datahub-service/service/thematic_library"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+)
+
+// UpdateChangelogPolicyRequest 设置变更日志策略请求
+type UpdateChangelogPolicyRequest struct {
+	Enabled       bool `json:"enabled" example:"true"`                             // 是否记录变更日志
+	RetentionDays int  `json:"retention_days" validate:"min=0,max=90" example:"7"` // 保留天数，为0时使用默认值7
+}
+
+// GetThematicInterfaceChangelogPolicy 获取主题接口变更日志策略
+// @Summary 获取主题接口变更日志策略
+// @Description 获取主题接口的变更日志策略，未设置时返回默认策略（不开启）
+// @Tags 主题接口
+// @Produce json
+// @Param id path string true "主题接口ID"
+// @Success 200 {object} APIResponse[models.ThematicChangelogPolicy] "获取成功"
+// @Failure 404 {object} APIResponse[any] "主题接口不存在"
+// @Router /thematic-interfaces/{id}/changelog-policy [get]
+func (c *ThematicLibraryController) GetThematicInterfaceChangelogPolicy(w http.ResponseWriter, r *http.Request) {
+	policy, err := c.service.GetChangelogPolicy(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		render.JSON(w, r, MapErrorResponse("获取变更日志策略失败", err))
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("获取变更日志策略成功", policy))
+}
+
+// UpdateThematicInterfaceChangelogPolicy 设置主题接口变更日志策略
+// @Summary 设置主题接口变更日志策略
+// @Description 开启后主题同步写入时把新增、修改、删除的主键和修改的列记入接口的变更日志表，下游通过/api/v1/share/changelog/{interface_id}按游标拉取增量。只有PostgreSQL存储、配置了主键的table类型接口可以开启；超出保留天数的变更日志在同步后清理；关闭后保留已有的变更日志
+// @Tags 主题接口
+// @Accept json
+// @Produce json
+// @Param id path string true "主题接口ID"
+// @Param request body UpdateChangelogPolicyRequest true "变更日志策略"
+// @Success 200 {object} APIResponse[models.ThematicChangelogPolicy] "设置成功"
+// @Failure 400 {object} APIResponse[any] "策略不合法或接口不支持变更日志"
+// @Failure 404 {object} APIResponse[any] "主题接口不存在"
+// @Router /thematic-interfaces/{id}/changelog-policy [put]
+func (c *ThematicLibraryController) UpdateThematicInterfaceChangelogPolicy(w http.ResponseWriter, r *http.Request) {
+	var req UpdateChangelogPolicyRequest
+	if !decodeAndValidate(w, r, &req) {
+		return
+	}
+
+	policy, err := c.service.UpdateChangelogPolicy(r.Context(), chi.URLParam(r, "id"), req.Enabled, req.RetentionDays, getCurrentUsername(r))
+	if err != nil {
+		if errors.Is(err, thematic_library.ErrInvalidChangelogPolicy) {
+			render.JSON(w, r, BadRequestResponse("设置变更日志策略失败: "+err.Error(), err))
+			return
+		}
+		render.JSON(w, r, MapErrorResponse("设置变更日志策略失败", err))
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("设置变更日志策略成功", policy))
+}
+
+// DeleteThematicInterfaceChangelogPolicy 删除主题接口变更日志策略
+// @Summary 删除主题接口变更日志策略
+// @Description 删除变更日志策略和接口的变更日志表，下游不能再拉取该接口的变更日志
+// @Tags 主题接口
+// @Produce json
+// @Param id path string true "主题接口ID"
+// @Success 200 {object} APIResponse[any] "删除成功"
+// @Failure 404 {object} APIResponse[any] "主题接口不存在或未设置变更日志策略"
+// @Router /thematic-interfaces/{id}/changelog-policy [delete]
+func (c *ThematicLibraryController) DeleteThematicInterfaceChangelogPolicy(w http.ResponseWriter, r *http.Request) {
+	if err := c.service.DeleteChangelogPolicy(r.Context(), chi.URLParam(r, "id"), getCurrentUsername(r)); err != nil {
+		render.JSON(w, r, MapErrorResponse("删除变更日志策略失败", err))
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("删除变更日志策略成功", nil))
+}
@@ -14,10 +14,9 @@ package controllers
 import (
 	"datahub-service/service"
 	"datahub-service/service/models"
+	"datahub-service/service/rbac"
 	"datahub-service/service/thematic_library"
-	"fmt"
 	"net/http"
-	"strconv"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/render"
@@ -37,18 +36,14 @@ func NewThematicLibraryController() *ThematicLibraryController {
 
 // ThematicLibraryListResponse 数据主题库列表响应结构
 type ThematicLibraryListResponse struct {
-	List  []models.ThematicLibrary `json:"list"`
-	Total int64                    `json:"total"`
-	Page  int                      `json:"page"`
-	Size  int                      `json:"size"`
+	List []models.ThematicLibrary `json:"list"`
+	models.PageMeta
 }
 
 // ThematicInterfaceListResponse 主题接口列表响应结构
 type ThematicInterfaceListResponse struct {
-	List  []models.ThematicInterface `json:"list"`
-	Total int64                      `json:"total"`
-	Page  int                        `json:"page"`
-	Size  int                        `json:"size"`
+	List []models.ThematicInterface `json:"list"`
+	models.PageMeta
 }
 
 // UpdateThematicInterfaceFieldsRequest 更新主题接口字段配置请求结构
@@ -76,18 +71,17 @@ type UpdateThematicInterfaceViewRequest struct {
 // @Accept json
 // @Produce json
 // @Param library body models.ThematicLibrary true "数据主题库信息"
-// @Success 201 {object} APIResponse{data=models.ThematicLibrary}
-// @Failure 400 {object} APIResponse
-// @Failure 500 {object} APIResponse
+// @Success 201 {object} APIResponse[models.ThematicLibrary]
+// @Failure 400 {object} APIResponse[any]
+// @Failure 500 {object} APIResponse[any]
 // @Router /thematic-libraries [post]
 func (c *ThematicLibraryController) CreateThematicLibrary(w http.ResponseWriter, r *http.Request) {
 	var req models.ThematicLibrary
-	if err := render.DecodeJSON(r.Body, &req); err != nil {
-		render.JSON(w, r, BadRequestResponse(fmt.Sprintf("请求参数格式错误:%s", err.Error()), nil))
+	if !decodeAndValidate(w, r, &req) {
 		return
 	}
 
-	if err := c.service.CreateThematicLibrary(&req); err != nil {
+	if err := c.service.CreateThematicLibrary(r.Context(), &req); err != nil {
 		render.JSON(w, r, BadRequestResponse(err.Error(), nil))
 		return
 	}
@@ -101,9 +95,9 @@ func (c *ThematicLibraryController) CreateThematicLibrary(w http.ResponseWriter,
 // @Tags 数据主题库
 // @Produce json
 // @Param id path string true "数据主题库ID"
-// @Success 200 {object} APIResponse{data=models.ThematicLibrary}
-// @Failure 404 {object} APIResponse
-// @Failure 500 {object} APIResponse
+// @Success 200 {object} APIResponse[models.ThematicLibrary]
+// @Failure 404 {object} APIResponse[any]
+// @Failure 500 {object} APIResponse[any]
 // @Router /thematic-libraries/{id} [get]
 func (c *ThematicLibraryController) GetThematicLibrary(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
@@ -112,11 +106,14 @@ func (c *ThematicLibraryController) GetThematicLibrary(w http.ResponseWriter, r
 		return
 	}
 
-	library, err := c.service.GetThematicLibrary(id)
+	library, err := c.service.GetThematicLibrary(r.Context(), id)
 	if err != nil {
 		render.JSON(w, r, NotFoundResponse("数据主题库不存在", nil))
 		return
 	}
+	if !visibleOrNotFound(w, r, rbac.ResourceThematicLibrary, library.ID, "数据主题库不存在") {
+		return
+	}
 
 	render.JSON(w, r, SuccessResponse("查询成功", library))
 }
@@ -129,10 +126,10 @@ func (c *ThematicLibraryController) GetThematicLibrary(w http.ResponseWriter, r
 // @Produce json
 // @Param id path string true "数据主题库ID"
 // @Param library body models.ThematicLibrary true "更新信息"
-// @Success 200 {object} APIResponse
-// @Failure 400 {object} APIResponse
-// @Failure 404 {object} APIResponse
-// @Failure 500 {object} APIResponse
+// @Success 200 {object} APIResponse[any]
+// @Failure 400 {object} APIResponse[any]
+// @Failure 404 {object} APIResponse[any]
+// @Failure 500 {object} APIResponse[any]
 // @Router /thematic-libraries/{id} [put]
 func (c *ThematicLibraryController) UpdateThematicLibrary(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
@@ -142,12 +139,11 @@ func (c *ThematicLibraryController) UpdateThematicLibrary(w http.ResponseWriter,
 	}
 
 	var req models.ThematicLibrary
-	if err := render.DecodeJSON(r.Body, &req); err != nil {
-		render.JSON(w, r, BadRequestResponse("请求参数格式错误", err))
+	if !decodeAndValidate(w, r, &req) {
 		return
 	}
 
-	if err := c.service.UpdateThematicLibrary(id, &req); err != nil {
+	if err := c.service.UpdateThematicLibrary(r.Context(), id, &req); err != nil {
 		render.JSON(w, r, BadRequestResponse(err.Error(), nil))
 		return
 	}
@@ -161,10 +157,10 @@ func (c *ThematicLibraryController) UpdateThematicLibrary(w http.ResponseWriter,
 // @Tags 数据主题库
 // @Produce json
 // @Param id path string true "数据主题库ID"
-// @Success 200 {object} APIResponse
-// @Failure 400 {object} APIResponse
-// @Failure 404 {object} APIResponse
-// @Failure 500 {object} APIResponse
+// @Success 200 {object} APIResponse[any]
+// @Failure 400 {object} APIResponse[any]
+// @Failure 404 {object} APIResponse[any]
+// @Failure 500 {object} APIResponse[any]
 // @Router /thematic-libraries/{id} [delete]
 func (c *ThematicLibraryController) DeleteThematicLibrary(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
@@ -173,7 +169,7 @@ func (c *ThematicLibraryController) DeleteThematicLibrary(w http.ResponseWriter,
 		return
 	}
 
-	if err := c.service.DeleteThematicLibrary(id); err != nil {
+	if err := c.service.DeleteThematicLibrary(r.Context(), id); err != nil {
 		render.JSON(w, r, BadRequestResponse(err.Error(), nil))
 		return
 	}
@@ -187,10 +183,10 @@ func (c *ThematicLibraryController) DeleteThematicLibrary(w http.ResponseWriter,
 // @Tags 数据主题库
 // @Produce json
 // @Param id path string true "数据主题库ID"
-// @Success 200 {object} APIResponse
-// @Failure 400 {object} APIResponse
-// @Failure 404 {object} APIResponse
-// @Failure 500 {object} APIResponse
+// @Success 200 {object} APIResponse[any]
+// @Failure 400 {object} APIResponse[any]
+// @Failure 404 {object} APIResponse[any]
+// @Failure 500 {object} APIResponse[any]
 // @Router /thematic-libraries/{id}/publish [post]
 func (c *ThematicLibraryController) PublishThematicLibrary(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
@@ -214,14 +210,13 @@ func (c *ThematicLibraryController) PublishThematicLibrary(w http.ResponseWriter
 // @Accept json
 // @Produce json
 // @Param thematic_interface body models.ThematicInterface true "主题接口信息"
-// @Success 201 {object} APIResponse{data=models.ThematicInterface}
-// @Failure 400 {object} APIResponse
-// @Failure 500 {object} APIResponse
+// @Success 201 {object} APIResponse[models.ThematicInterface]
+// @Failure 400 {object} APIResponse[any]
+// @Failure 500 {object} APIResponse[any]
 // @Router /thematic-interfaces [post]
 func (c *ThematicLibraryController) CreateThematicInterface(w http.ResponseWriter, r *http.Request) {
 	var req models.ThematicInterface
-	if err := render.DecodeJSON(r.Body, &req); err != nil {
-		render.JSON(w, r, BadRequestResponse("请求参数格式错误", err))
+	if !decodeAndValidate(w, r, &req) {
 		return
 	}
 
@@ -239,10 +234,10 @@ func (c *ThematicLibraryController) CreateThematicInterface(w http.ResponseWrite
 // @Tags 主题接口
 // @Produce json
 // @Param id path string true "主题接口ID" example:"550e8400-e29b-41d4-a716-446655440000"
-// @Success 200 {object} APIResponse{data=models.ThematicInterface} "获取成功"
-// @Failure 400 {object} APIResponse "请求参数错误"
-// @Failure 404 {object} APIResponse "主题接口不存在"
-// @Failure 500 {object} APIResponse "服务器内部错误"
+// @Success 200 {object} APIResponse[models.ThematicInterface] "获取成功"
+// @Failure 400 {object} APIResponse[any] "请求参数错误"
+// @Failure 404 {object} APIResponse[any] "主题接口不存在"
+// @Failure 500 {object} APIResponse[any] "服务器内部错误"
 // @Router /thematic-interfaces/{id} [get]
 func (c *ThematicLibraryController) GetThematicInterface(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
@@ -257,7 +252,11 @@ func (c *ThematicLibraryController) GetThematicInterface(w http.ResponseWriter,
 		render.JSON(w, r, NotFoundResponse("主题接口不存在", err))
 		return
 	}
+	if !visibleOrNotFound(w, r, rbac.ResourceThematicLibrary, thematicInterface.LibraryID, "主题接口不存在") {
+		return
+	}
 
+	setETag(w, thematicInterface.RowVersion)
 	render.JSON(w, r, SuccessResponse("查询成功", thematicInterface))
 }
 
@@ -269,10 +268,12 @@ func (c *ThematicLibraryController) GetThematicInterface(w http.ResponseWriter,
 // @Produce json
 // @Param id path string true "主题接口ID"
 // @Param thematic_interface body models.ThematicInterface true "更新信息"
-// @Success 200 {object} APIResponse
-// @Failure 400 {object} APIResponse
-// @Failure 404 {object} APIResponse
-// @Failure 500 {object} APIResponse
+// @Param If-Match header string false "期望版本（ETag），不一致时返回409"
+// @Success 200 {object} APIResponse[any]
+// @Failure 400 {object} APIResponse[any]
+// @Failure 404 {object} APIResponse[any]
+// @Failure 409 {object} APIResponse[VersionConflictData] "版本冲突"
+// @Failure 500 {object} APIResponse[any]
 // @Router /thematic-interfaces/{id} [put]
 func (c *ThematicLibraryController) UpdateThematicInterface(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
@@ -282,12 +283,21 @@ func (c *ThematicLibraryController) UpdateThematicInterface(w http.ResponseWrite
 	}
 
 	var req models.ThematicInterface
-	if err := render.DecodeJSON(r.Body, &req); err != nil {
-		render.JSON(w, r, BadRequestResponse("请求参数格式错误", err))
+	if !decodeAndValidate(w, r, &req) {
+		return
+	}
+
+	expectedVersion, err := expectedRowVersion(r, req.RowVersion)
+	if err != nil {
+		render.JSON(w, r, BadRequestResponse(err.Error(), err))
 		return
 	}
+	req.RowVersion = expectedVersion
 
 	if err := c.service.UpdateThematicInterface(id, &req); err != nil {
+		if handleVersionConflict(w, r, err) {
+			return
+		}
 		render.JSON(w, r, BadRequestResponse(err.Error(), nil))
 		return
 	}
@@ -307,39 +317,30 @@ func (c *ThematicLibraryController) UpdateThematicInterface(w http.ResponseWrite
 // @Param domain query string false "数据域过滤"
 // @Param status query string false "状态过滤" Enums(draft,published,archived)
 // @Param name query string false "名称搜索（支持中英文）"
-// @Success 200 {object} APIResponse{data=ThematicLibraryListResponse} "获取成功"
-// @Failure 400 {object} APIResponse "请求参数错误"
-// @Failure 500 {object} APIResponse "服务器内部错误"
+// @Success 200 {object} APIResponse[ThematicLibraryListResponse] "获取成功"
+// @Failure 400 {object} APIResponse[any] "请求参数错误"
+// @Failure 500 {object} APIResponse[any] "服务器内部错误"
 // @Router /thematic-libraries [get]
 func (c *ThematicLibraryController) GetThematicLibraryList(w http.ResponseWriter, r *http.Request) {
 	// 解析查询参数
-	page := 1
-	size := 10
 	category := r.URL.Query().Get("category")
 	domain := r.URL.Query().Get("domain")
 	status := r.URL.Query().Get("status")
 	name := r.URL.Query().Get("name")
 
-	if p, err := strconv.Atoi(r.URL.Query().Get("page")); err == nil && p > 0 {
-		page = p
-	}
-	if s, err := strconv.Atoi(r.URL.Query().Get("size")); err == nil && s > 0 && s <= 100 {
-		size = s
-	}
+	page, size := parsePagination(r)
 
 	// 调用服务层方法
-	libraries, total, err := c.service.GetThematicLibraryList(page, size, category, domain, status, name)
+	libraries, total, err := c.service.GetThematicLibraryList(r.Context(), page, size, category, domain, status, name)
 	if err != nil {
-		render.JSON(w, r, InternalErrorResponse("获取数据主题库列表失败", err))
+		render.JSON(w, r, MapErrorResponse("获取数据主题库列表失败", err))
 		return
 	}
 
 	// 构建响应
 	response := ThematicLibraryListResponse{
-		List:  libraries,
-		Total: total,
-		Page:  page,
-		Size:  size,
+		List:     libraries,
+		PageMeta: models.NewPageMeta(total, page, size),
 	}
 
 	render.JSON(w, r, SuccessResponse("获取数据主题库列表成功", response))
@@ -353,46 +354,40 @@ func (c *ThematicLibraryController) GetThematicLibraryList(w http.ResponseWriter
 // @Produce json
 // @Param page query int false "页码" default(1)
 // @Param size query int false "每页大小" default(10)
+// @Param fields query string false "返回字段，逗号分隔，始终包含id"
+// @Param light query bool false "轻量模式，不返回interface_config,parse_config,table_fields_config,view_config及关联对象"
 // @Param library_id query string false "主题库ID过滤"
 // @Param interface_type query string false "接口类型过滤" Enums(table,view)
 // @Param status query string false "状态过滤" Enums(active,inactive)
 // @Param name query string false "名称搜索（支持中英文）"
-// @Success 200 {object} APIResponse{data=ThematicInterfaceListResponse} "获取成功"
-// @Failure 400 {object} APIResponse "请求参数错误"
-// @Failure 500 {object} APIResponse "服务器内部错误"
+// @Success 200 {object} APIResponse[ThematicInterfaceListResponse] "获取成功"
+// @Failure 400 {object} APIResponse[any] "请求参数错误"
+// @Failure 500 {object} APIResponse[any] "服务器内部错误"
 // @Router /thematic-interfaces [get]
 func (c *ThematicLibraryController) GetThematicInterfaceList(w http.ResponseWriter, r *http.Request) {
 	// 解析查询参数
-	page := 1
-	size := 10
 	libraryID := r.URL.Query().Get("library_id")
 	interfaceType := r.URL.Query().Get("interface_type")
 	status := r.URL.Query().Get("status")
 	name := r.URL.Query().Get("name")
 
-	if p, err := strconv.Atoi(r.URL.Query().Get("page")); err == nil && p > 0 {
-		page = p
-	}
-	if s, err := strconv.Atoi(r.URL.Query().Get("size")); err == nil && s > 0 && s <= 100 {
-		size = s
-	}
+	page, size := parsePagination(r)
 
 	// 调用服务层方法
-	interfaces, total, err := c.service.GetThematicInterfaceList(page, size, libraryID, interfaceType, status, name)
+	interfaces, total, err := c.service.GetThematicInterfaceList(r.Context(), page, size, libraryID, interfaceType, status, name)
 	if err != nil {
-		render.JSON(w, r, InternalErrorResponse("获取主题接口列表失败", err))
+		render.JSON(w, r, MapErrorResponse("获取主题接口列表失败", err))
 		return
 	}
 
 	// 构建响应
 	response := ThematicInterfaceListResponse{
-		List:  interfaces,
-		Total: total,
-		Page:  page,
-		Size:  size,
+		List:     interfaces,
+		PageMeta: models.NewPageMeta(total, page, size),
 	}
 
-	render.JSON(w, r, SuccessResponse("获取主题接口列表成功", response))
+	sel := parseFieldSelection(r, thematicInterfaceHeavyFields...)
+	render.JSON(w, r, SuccessResponse("获取主题接口列表成功", sel.applyToList(response)))
 }
 
 // DeleteThematicInterface 删除主题接口
@@ -401,10 +396,10 @@ func (c *ThematicLibraryController) GetThematicInterfaceList(w http.ResponseWrit
 // @Tags 主题接口
 // @Produce json
 // @Param id path string true "主题接口ID"
-// @Success 200 {object} APIResponse
-// @Failure 400 {object} APIResponse
-// @Failure 404 {object} APIResponse
-// @Failure 500 {object} APIResponse
+// @Success 200 {object} APIResponse[any]
+// @Failure 400 {object} APIResponse[any]
+// @Failure 404 {object} APIResponse[any]
+// @Failure 500 {object} APIResponse[any]
 // @Router /thematic-interfaces/{id} [delete]
 func (c *ThematicLibraryController) DeleteThematicInterface(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
@@ -428,14 +423,13 @@ func (c *ThematicLibraryController) DeleteThematicInterface(w http.ResponseWrite
 // @Accept json
 // @Produce json
 // @Param request body UpdateThematicInterfaceFieldsRequest true "更新字段配置请求"
-// @Success 200 {object} APIResponse
-// @Failure 400 {object} APIResponse
-// @Failure 500 {object} APIResponse
+// @Success 200 {object} APIResponse[any]
+// @Failure 400 {object} APIResponse[any]
+// @Failure 500 {object} APIResponse[any]
 // @Router /thematic-interfaces/update-fields [post]
 func (c *ThematicLibraryController) UpdateThematicInterfaceFields(w http.ResponseWriter, r *http.Request) {
 	var req UpdateThematicInterfaceFieldsRequest
-	if err := render.DecodeJSON(r.Body, &req); err != nil {
-		render.JSON(w, r, BadRequestResponse("请求参数格式错误", err))
+	if !decodeAndValidate(w, r, &req) {
 		return
 	}
 
@@ -451,7 +445,7 @@ func (c *ThematicLibraryController) UpdateThematicInterfaceFields(w http.Respons
 
 	err := c.service.UpdateThematicInterfaceFields(req.InterfaceID, req.Fields)
 	if err != nil {
-		render.JSON(w, r, InternalErrorResponse("更新主题接口字段配置失败", err))
+		render.JSON(w, r, MapErrorResponse("更新主题接口字段配置失败", err))
 		return
 	}
 
@@ -465,14 +459,13 @@ func (c *ThematicLibraryController) UpdateThematicInterfaceFields(w http.Respons
 // @Accept json
 // @Produce json
 // @Param request body CreateThematicInterfaceViewRequest true "创建视图请求"
-// @Success 200 {object} APIResponse
-// @Failure 400 {object} APIResponse
-// @Failure 500 {object} APIResponse
+// @Success 200 {object} APIResponse[any]
+// @Failure 400 {object} APIResponse[any]
+// @Failure 500 {object} APIResponse[any]
 // @Router /thematic-interfaces/create-view [post]
 func (c *ThematicLibraryController) CreateThematicInterfaceView(w http.ResponseWriter, r *http.Request) {
 	var req CreateThematicInterfaceViewRequest
-	if err := render.DecodeJSON(r.Body, &req); err != nil {
-		render.JSON(w, r, BadRequestResponse("请求参数格式错误", err))
+	if !decodeAndValidate(w, r, &req) {
 		return
 	}
 
@@ -488,7 +481,7 @@ func (c *ThematicLibraryController) CreateThematicInterfaceView(w http.ResponseW
 
 	err := c.service.CreateThematicInterfaceView(req.InterfaceID, req.ViewSQL)
 	if err != nil {
-		render.JSON(w, r, InternalErrorResponse("创建主题接口视图失败", err))
+		render.JSON(w, r, MapErrorResponse("创建主题接口视图失败", err))
 		return
 	}
 
@@ -502,14 +495,13 @@ func (c *ThematicLibraryController) CreateThematicInterfaceView(w http.ResponseW
 // @Accept json
 // @Produce json
 // @Param request body UpdateThematicInterfaceViewRequest true "更新视图请求"
-// @Success 200 {object} APIResponse
-// @Failure 400 {object} APIResponse
-// @Failure 500 {object} APIResponse
+// @Success 200 {object} APIResponse[any]
+// @Failure 400 {object} APIResponse[any]
+// @Failure 500 {object} APIResponse[any]
 // @Router /thematic-interfaces/update-view [post]
 func (c *ThematicLibraryController) UpdateThematicInterfaceView(w http.ResponseWriter, r *http.Request) {
 	var req UpdateThematicInterfaceViewRequest
-	if err := render.DecodeJSON(r.Body, &req); err != nil {
-		render.JSON(w, r, BadRequestResponse("请求参数格式错误", err))
+	if !decodeAndValidate(w, r, &req) {
 		return
 	}
 
@@ -525,7 +517,7 @@ func (c *ThematicLibraryController) UpdateThematicInterfaceView(w http.ResponseW
 
 	err := c.service.UpdateThematicInterfaceView(req.InterfaceID, req.ViewSQL)
 	if err != nil {
-		render.JSON(w, r, InternalErrorResponse("更新主题接口视图失败", err))
+		render.JSON(w, r, MapErrorResponse("更新主题接口视图失败", err))
 		return
 	}
 
@@ -538,10 +530,10 @@ func (c *ThematicLibraryController) UpdateThematicInterfaceView(w http.ResponseW
 // @Tags 主题接口视图
 // @Produce json
 // @Param id path string true "主题接口ID"
-// @Success 200 {object} APIResponse
-// @Failure 400 {object} APIResponse
-// @Failure 404 {object} APIResponse
-// @Failure 500 {object} APIResponse
+// @Success 200 {object} APIResponse[any]
+// @Failure 400 {object} APIResponse[any]
+// @Failure 404 {object} APIResponse[any]
+// @Failure 500 {object} APIResponse[any]
 // @Router /thematic-interfaces/{id}/delete-view [delete]
 func (c *ThematicLibraryController) DeleteThematicInterfaceView(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
@@ -552,7 +544,7 @@ func (c *ThematicLibraryController) DeleteThematicInterfaceView(w http.ResponseW
 
 	err := c.service.DeleteThematicInterfaceView(id)
 	if err != nil {
-		render.JSON(w, r, InternalErrorResponse("删除主题接口视图失败", err))
+		render.JSON(w, r, MapErrorResponse("删除主题接口视图失败", err))
 		return
 	}
 
@@ -565,10 +557,10 @@ func (c *ThematicLibraryController) DeleteThematicInterfaceView(w http.ResponseW
 // @Tags 主题接口视图
 // @Produce json
 // @Param id path string true "主题接口ID"
-// @Success 200 {object} APIResponse{data=string}
-// @Failure 400 {object} APIResponse
-// @Failure 404 {object} APIResponse
-// @Failure 500 {object} APIResponse
+// @Success 200 {object} APIResponse[string]
+// @Failure 400 {object} APIResponse[any]
+// @Failure 404 {object} APIResponse[any]
+// @Failure 500 {object} APIResponse[any]
 // @Router /thematic-interfaces/{id}/view-sql [get]
 func (c *ThematicLibraryController) GetThematicInterfaceViewSQL(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
@@ -579,7 +571,7 @@ func (c *ThematicLibraryController) GetThematicInterfaceViewSQL(w http.ResponseW
 
 	viewSQL, err := c.service.GetThematicInterfaceViewSQL(id)
 	if err != nil {
-		render.JSON(w, r, InternalErrorResponse("获取主题接口视图SQL失败", err))
+		render.JSON(w, r, MapErrorResponse("获取主题接口视图SQL失败", err))
 		return
 	}
 
@@ -592,9 +584,9 @@ func (c *ThematicLibraryController) GetThematicInterfaceViewSQL(w http.ResponseW
 // @Tags 主题接口
 // @Produce json
 // @Param id path string true "主题接口ID" example:"550e8400-e29b-41d4-a716-446655440000"
-// @Success 200 {object} APIResponse{data=[]database.ColumnDefinition} "返回表字段列表"
-// @Failure 400 {object} APIResponse "请求参数错误或表未创建"
-// @Failure 500 {object} APIResponse "服务器内部错误"
+// @Success 200 {object} APIResponse[[]database.ColumnDefinition] "返回表字段列表"
+// @Failure 400 {object} APIResponse[any] "请求参数错误或表未创建"
+// @Failure 500 {object} APIResponse[any] "服务器内部错误"
 // @Router /thematic-interfaces/{id}/table-columns [get]
 func (c *ThematicLibraryController) GetThematicInterfaceTableColumns(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
@@ -606,7 +598,7 @@ func (c *ThematicLibraryController) GetThematicInterfaceTableColumns(w http.Resp
 	// 获取主题接口信息
 	interfaceData, err := c.service.GetThematicInterface(id)
 	if err != nil {
-		render.JSON(w, r, InternalErrorResponse("获取主题接口信息失败", err))
+		render.JSON(w, r, MapErrorResponse("获取主题接口信息失败", err))
 		return
 	}
 
@@ -617,9 +609,9 @@ func (c *ThematicLibraryController) GetThematicInterfaceTableColumns(w http.Resp
 	}
 
 	// 从数据库获取表/视图字段信息
-	columns, err := c.service.GetSchemaService().GetTableColumns(interfaceData.ThematicLibrary.NameEn, interfaceData.NameEn)
+	columns, err := c.service.GetSchemaService().GetTableColumns(interfaceData.ThematicLibrary.GetSchemaName(), interfaceData.NameEn)
 	if err != nil {
-		render.JSON(w, r, InternalErrorResponse("获取字段信息失败", err))
+		render.JSON(w, r, MapErrorResponse("获取字段信息失败", err))
 		return
 	}
 
@@ -632,9 +624,9 @@ func (c *ThematicLibraryController) GetThematicInterfaceTableColumns(w http.Resp
 // @Tags 主题接口
 // @Produce json
 // @Param id path string true "主题接口ID" example:"550e8400-e29b-41d4-a716-446655440000"
-// @Success 200 {object} APIResponse{data=[]database.IndexDefinition} "返回索引列表"
-// @Failure 400 {object} APIResponse "请求参数错误、表未创建或视图类型不支持"
-// @Failure 500 {object} APIResponse "服务器内部错误"
+// @Success 200 {object} APIResponse[[]database.IndexDefinition] "返回索引列表"
+// @Failure 400 {object} APIResponse[any] "请求参数错误、表未创建或视图类型不支持"
+// @Failure 500 {object} APIResponse[any] "服务器内部错误"
 // @Router /thematic-interfaces/{id}/table-indexes [get]
 func (c *ThematicLibraryController) GetThematicInterfaceTableIndexes(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
@@ -646,7 +638,7 @@ func (c *ThematicLibraryController) GetThematicInterfaceTableIndexes(w http.Resp
 	// 获取主题接口信息
 	interfaceData, err := c.service.GetThematicInterface(id)
 	if err != nil {
-		render.JSON(w, r, InternalErrorResponse("获取主题接口信息失败", err))
+		render.JSON(w, r, MapErrorResponse("获取主题接口信息失败", err))
 		return
 	}
 
@@ -662,9 +654,9 @@ func (c *ThematicLibraryController) GetThematicInterfaceTableIndexes(w http.Resp
 	}
 
 	// 从数据库获取索引信息
-	indexes, err := c.service.GetSchemaService().GetTableIndexes(interfaceData.ThematicLibrary.NameEn, interfaceData.NameEn)
+	indexes, err := c.service.GetSchemaService().GetTableIndexes(interfaceData.ThematicLibrary.GetSchemaName(), interfaceData.NameEn)
 	if err != nil {
-		render.JSON(w, r, InternalErrorResponse("获取索引信息失败", err))
+		render.JSON(w, r, MapErrorResponse("获取索引信息失败", err))
 		return
 	}
 
@@ -687,14 +679,13 @@ type CreateThematicInterfaceTableIndexRequest struct {
 // @Accept json
 // @Produce json
 // @Param request body CreateThematicInterfaceTableIndexRequest true "创建索引请求"
-// @Success 200 {object} APIResponse{data=nil} "创建成功"
-// @Failure 400 {object} APIResponse "请求参数错误、视图类型不支持或表未创建"
-// @Failure 500 {object} APIResponse "服务器内部错误"
+// @Success 200 {object} APIResponse[any] "创建成功"
+// @Failure 400 {object} APIResponse[any] "请求参数错误、视图类型不支持或表未创建"
+// @Failure 500 {object} APIResponse[any] "服务器内部错误"
 // @Router /thematic-interfaces/create-table-index [post]
 func (c *ThematicLibraryController) CreateThematicInterfaceTableIndex(w http.ResponseWriter, r *http.Request) {
 	var req CreateThematicInterfaceTableIndexRequest
-	if err := render.DecodeJSON(r.Body, &req); err != nil {
-		render.JSON(w, r, BadRequestResponse("请求参数格式错误", err))
+	if !decodeAndValidate(w, r, &req) {
 		return
 	}
 
@@ -716,7 +707,7 @@ func (c *ThematicLibraryController) CreateThematicInterfaceTableIndex(w http.Res
 	// 获取主题接口信息
 	interfaceData, err := c.service.GetThematicInterface(req.InterfaceID)
 	if err != nil {
-		render.JSON(w, r, InternalErrorResponse("获取主题接口信息失败", err))
+		render.JSON(w, r, MapErrorResponse("获取主题接口信息失败", err))
 		return
 	}
 
@@ -734,7 +725,7 @@ func (c *ThematicLibraryController) CreateThematicInterfaceTableIndex(w http.Res
 
 	// 创建索引
 	err = c.service.GetSchemaService().CreateIndex(
-		interfaceData.ThematicLibrary.NameEn,
+		interfaceData.ThematicLibrary.GetSchemaName(),
 		interfaceData.NameEn,
 		req.IndexName,
 		req.Columns,
@@ -742,7 +733,7 @@ func (c *ThematicLibraryController) CreateThematicInterfaceTableIndex(w http.Res
 		req.IndexType,
 	)
 	if err != nil {
-		render.JSON(w, r, InternalErrorResponse("创建索引失败", err))
+		render.JSON(w, r, MapErrorResponse("创建索引失败", err))
 		return
 	}
 
@@ -762,14 +753,13 @@ type DropThematicInterfaceTableIndexRequest struct {
 // @Accept json
 // @Produce json
 // @Param request body DropThematicInterfaceTableIndexRequest true "删除索引请求"
-// @Success 200 {object} APIResponse{data=nil} "删除成功"
-// @Failure 400 {object} APIResponse "请求参数错误"
-// @Failure 500 {object} APIResponse "服务器内部错误"
+// @Success 200 {object} APIResponse[any] "删除成功"
+// @Failure 400 {object} APIResponse[any] "请求参数错误"
+// @Failure 500 {object} APIResponse[any] "服务器内部错误"
 // @Router /thematic-interfaces/drop-table-index [post]
 func (c *ThematicLibraryController) DropThematicInterfaceTableIndex(w http.ResponseWriter, r *http.Request) {
 	var req DropThematicInterfaceTableIndexRequest
-	if err := render.DecodeJSON(r.Body, &req); err != nil {
-		render.JSON(w, r, BadRequestResponse("请求参数格式错误", err))
+	if !decodeAndValidate(w, r, &req) {
 		return
 	}
 
@@ -786,14 +776,14 @@ func (c *ThematicLibraryController) DropThematicInterfaceTableIndex(w http.Respo
 	// 获取主题接口信息
 	interfaceData, err := c.service.GetThematicInterface(req.InterfaceID)
 	if err != nil {
-		render.JSON(w, r, InternalErrorResponse("获取主题接口信息失败", err))
+		render.JSON(w, r, MapErrorResponse("获取主题接口信息失败", err))
 		return
 	}
 
 	// 删除索引
-	err = c.service.GetSchemaService().DropIndex(interfaceData.ThematicLibrary.NameEn, req.IndexName)
+	err = c.service.GetSchemaService().DropIndex(interfaceData.ThematicLibrary.GetSchemaName(), req.IndexName)
 	if err != nil {
-		render.JSON(w, r, InternalErrorResponse("删除索引失败", err))
+		render.JSON(w, r, MapErrorResponse("删除索引失败", err))
 		return
 	}
 
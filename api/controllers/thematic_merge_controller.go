@@ -0,0 +1,185 @@
+/*
+ * @module api/controllers/thematic_merge_controller
+ * @description 主题表多源写入合并API，提供合并策略的查询、设置、删除以及合并冲突的查询和评审
+ * @architecture MVC架构 - 控制器层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow 设置合并策略 -> 同步写入时记录冲突 -> 查询冲突 -> 确认或忽略
+ * @rules 操作人取自当前登录用户；策略不合法返回400；冲突已评审返回409
+ * @dependencies datahub-service/service/thematic_library, github.com/go-chi/render
+ * @refs service/thematic_library/merge_policy.go, service/models/thematic_merge.go
+ */
+
+package controllers
+
+import (
+	"datahub-service/service/models"
+	"datahub-service/service/thematic_library"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+)
+
+// UpdateMergePolicyRequest 设置合并策略请求
+type UpdateMergePolicyRequest struct {
+	Enabled         *bool               `json:"enabled" example:"true"`                                                                                         // 是否启用，默认启用
+	Strategy        string              `json:"strategy" validate:"required,oneof=source_priority latest_timestamp field_precedence" example:"source_priority"` // 合并策略
+	SourcePriority  []string            `json:"source_priority"`                                                                                                // 同步任务ID，靠前的优先级高
+	TimestampField  string              `json:"timestamp_field" example:"updated_at"`                                                                           // latest_timestamp时比较的字段
+	FieldPrecedence map[string][]string `json:"field_precedence"`                                                                                               // field_precedence时字段名 -> 同步任务ID列表
+}
+
+// ReviewMergeConflictRequest 评审合并冲突请求
+type ReviewMergeConflictRequest struct {
+	Status string `json:"status" validate:"required,oneof=confirmed dismissed" example:"confirmed"` // 确认或忽略
+	Note   string `json:"note" validate:"max=1000" example:"以人口库数据为准"`
+}
+
+// MergeConflictListResponse 合并冲突分页结果
+type MergeConflictListResponse struct {
+	List []models.ThematicMergeConflict `json:"list"`
+	models.PageMeta
+}
+
+// GetThematicInterfaceMergePolicy 获取主题接口合并策略
+// @Summary 获取主题接口合并策略
+// @Description 获取多个同步任务写入同一主题表时的合并策略
+// @Tags 主题接口
+// @Produce json
+// @Param id path string true "主题接口ID"
+// @Success 200 {object} APIResponse[models.ThematicMergePolicy] "获取成功"
+// @Failure 404 {object} APIResponse[any] "主题接口不存在或未设置合并策略"
+// @Router /thematic-interfaces/{id}/merge-policy [get]
+func (c *ThematicLibraryController) GetThematicInterfaceMergePolicy(w http.ResponseWriter, r *http.Request) {
+	policy, err := c.service.GetMergePolicy(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		render.JSON(w, r, MapErrorResponse("获取合并策略失败", err))
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("获取合并策略成功", policy))
+}
+
+// UpdateThematicInterfaceMergePolicy 设置主题接口合并策略
+// @Summary 设置主题接口合并策略
+// @Description source_priority按任务优先级整条保留高优先级任务的数据；latest_timestamp按时间字段保留较新的数据；field_precedence按字段分别指定任务优先级，未配置的字段使用source_priority。启用后全量同步只删除完全由本任务写入的记录，被拒绝的写入记为合并冲突
+// @Tags 主题接口
+// @Accept json
+// @Produce json
+// @Param id path string true "主题接口ID"
+// @Param request body UpdateMergePolicyRequest true "合并策略"
+// @Success 200 {object} APIResponse[models.ThematicMergePolicy] "设置成功"
+// @Failure 400 {object} APIResponse[any] "合并策略不合法"
+// @Failure 404 {object} APIResponse[any] "主题接口不存在"
+// @Router /thematic-interfaces/{id}/merge-policy [put]
+func (c *ThematicLibraryController) UpdateThematicInterfaceMergePolicy(w http.ResponseWriter, r *http.Request) {
+	var req UpdateMergePolicyRequest
+	if !decodeAndValidate(w, r, &req) {
+		return
+	}
+
+	policy := &models.ThematicMergePolicy{
+		Enabled:        req.Enabled == nil || *req.Enabled,
+		Strategy:       req.Strategy,
+		SourcePriority: req.SourcePriority,
+		TimestampField: req.TimestampField,
+	}
+	if len(req.FieldPrecedence) > 0 {
+		policy.FieldPrecedence = models.JSONB{}
+		for field, order := range req.FieldPrecedence {
+			policy.FieldPrecedence[field] = order
+		}
+	}
+	result, err := c.service.UpdateMergePolicy(r.Context(), chi.URLParam(r, "id"), policy, getCurrentUsername(r))
+	if err != nil {
+		respondMergeError(w, r, "设置合并策略失败", err)
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("设置合并策略成功", result))
+}
+
+// DeleteThematicInterfaceMergePolicy 删除主题接口合并策略
+// @Summary 删除主题接口合并策略
+// @Description 删除后各任务按原方式覆盖写入，记录来源一并清除，冲突记录保留
+// @Tags 主题接口
+// @Produce json
+// @Param id path string true "主题接口ID"
+// @Success 200 {object} APIResponse[any] "删除成功"
+// @Failure 404 {object} APIResponse[any] "主题接口不存在或未设置合并策略"
+// @Router /thematic-interfaces/{id}/merge-policy [delete]
+func (c *ThematicLibraryController) DeleteThematicInterfaceMergePolicy(w http.ResponseWriter, r *http.Request) {
+	if err := c.service.DeleteMergePolicy(r.Context(), chi.URLParam(r, "id"), getCurrentUsername(r)); err != nil {
+		render.JSON(w, r, MapErrorResponse("删除合并策略失败", err))
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("删除合并策略成功", nil))
+}
+
+// GetThematicInterfaceMergeConflicts 获取主题接口合并冲突
+// @Summary 获取主题接口合并冲突
+// @Description 按最近出现时间倒序分页返回合并冲突，同一记录被同一任务重复拒绝时累加次数
+// @Tags 主题接口
+// @Produce json
+// @Param id path string true "主题接口ID"
+// @Param status query string false "冲突状态" Enums(open, confirmed, dismissed)
+// @Param page query int false "页码" default(1)
+// @Param size query int false "每页数量" default(10)
+// @Success 200 {object} APIResponse[MergeConflictListResponse] "获取成功"
+// @Failure 404 {object} APIResponse[any] "主题接口不存在"
+// @Router /thematic-interfaces/{id}/merge-conflicts [get]
+func (c *ThematicLibraryController) GetThematicInterfaceMergeConflicts(w http.ResponseWriter, r *http.Request) {
+	page, size := parsePagination(r)
+	conflicts, total, err := c.service.ListMergeConflicts(r.Context(), chi.URLParam(r, "id"), r.URL.Query().Get("status"), page, size)
+	if err != nil {
+		render.JSON(w, r, MapErrorResponse("获取合并冲突失败", err))
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("获取合并冲突成功", MergeConflictListResponse{
+		List:     conflicts,
+		PageMeta: models.NewPageMeta(total, page, size),
+	}))
+}
+
+// ReviewThematicInterfaceMergeConflict 评审合并冲突
+// @Summary 评审合并冲突
+// @Description 确认按策略处理正确或忽略冲突，评审后同一记录再次冲突时新建冲突
+// @Tags 主题接口
+// @Accept json
+// @Produce json
+// @Param id path string true "主题接口ID"
+// @Param conflictId path string true "冲突ID"
+// @Param request body ReviewMergeConflictRequest true "评审结果"
+// @Success 200 {object} APIResponse[models.ThematicMergeConflict] "评审成功"
+// @Failure 404 {object} APIResponse[any] "冲突不存在"
+// @Failure 409 {object} APIResponse[any] "冲突已评审"
+// @Router /thematic-interfaces/{id}/merge-conflicts/{conflictId}/review [post]
+func (c *ThematicLibraryController) ReviewThematicInterfaceMergeConflict(w http.ResponseWriter, r *http.Request) {
+	var req ReviewMergeConflictRequest
+	if !decodeAndValidate(w, r, &req) {
+		return
+	}
+
+	conflict, err := c.service.ReviewMergeConflict(r.Context(), chi.URLParam(r, "id"), chi.URLParam(r, "conflictId"), req.Status, getCurrentUsername(r), req.Note)
+	if err != nil {
+		respondMergeError(w, r, "评审合并冲突失败", err)
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("评审合并冲突成功", conflict))
+}
+
+// respondMergeError 策略不合法返回400，冲突已评审返回409，其余按错误类型映射
+func respondMergeError(w http.ResponseWriter, r *http.Request, msg string, err error) {
+	switch {
+	case errors.Is(err, thematic_library.ErrInvalidMergePolicy):
+		render.JSON(w, r, BadRequestResponse(msg, err))
+	case errors.Is(err, thematic_library.ErrMergeConflictReviewed):
+		render.JSON(w, r, ConflictResponse(msg+": "+err.Error(), err))
+	default:
+		render.JSON(w, r, MapErrorResponse(msg, err))
+	}
+}
@@ -0,0 +1,59 @@
+/*
+ * @module api/controllers/thematic_preview_controller
+ * @description 主题接口预览API，用同步任务的配置对少量样例执行获取、合并、映射和治理，返回每条输出记录的来源和转换过程
+ * @architecture MVC架构 - 控制器层
+ * @documentReference ai_docs/thematic_sync_design.md
+ * @stateFlow 解析任务ID和样例行数 -> 同步服务预览 -> 返回预览报告
+ * @rules 预览只读，不写入目标表；请求不合法或无法确定同步任务时返回400
+ * @dependencies datahub-service/service/thematic_library, github.com/go-chi/render
+ * @refs service/thematic_library/preview.go, service/thematic_library/thematic_sync/preview.go
+ */
+
+package controllers
+
+import (
+	"datahub-service/service"
+	"datahub-service/service/thematic_library"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+)
+
+// PreviewThematicInterface 预览主题接口的同步结果
+// @Summary 预览主题接口的同步结果
+// @Description 用接口同步任务的配置对少量样例执行数据获取、合并、字段映射和数据治理，不写入数据。返回每条输出记录合并了哪些源记录，以及每个字段取自哪个源记录的哪个字段、经过了哪些转换（数据源转换、类型转换、数据治理），用于排查关联和映射配置。接口模式下首个数据源取样例，其余数据源按样例的合并键（目标主键）取数；SQL模式下每条查询最多取limit行
+// @Tags 主题接口
+// @Produce json
+// @Param id path string true "主题接口ID"
+// @Param task_id query string false "同步任务ID，接口只有一个同步任务时可不传"
+// @Param limit query int false "每个数据源的样例行数，默认10，最多100"
+// @Success 200 {object} APIResponse[thematic_sync.PreviewReport] "预览成功"
+// @Failure 400 {object} APIResponse[any] "请求不合法或无法确定同步任务"
+// @Failure 404 {object} APIResponse[any] "主题接口不存在"
+// @Router /thematic-interfaces/{id}/preview [get]
+func (c *ThematicLibraryController) PreviewThematicInterface(w http.ResponseWriter, r *http.Request) {
+	limit := 0
+	if value := r.URL.Query().Get("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil {
+			render.JSON(w, r, BadRequestResponse("limit必须是整数", err))
+			return
+		}
+		limit = parsed
+	}
+
+	report, err := service.GlobalThematicSyncService.PreviewInterface(r.Context(), chi.URLParam(r, "id"), r.URL.Query().Get("task_id"), limit)
+	if err != nil {
+		if errors.Is(err, thematic_library.ErrInvalidPreviewRequest) {
+			render.JSON(w, r, BadRequestResponse("预览主题接口失败: "+err.Error(), err))
+			return
+		}
+		render.JSON(w, r, MapErrorResponse("预览主题接口失败", err))
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("预览主题接口成功", report))
+}
@@ -0,0 +1,226 @@
+/*
+ * @module api/controllers/thematic_publication_controller
+ * @description 主题接口发布流程API，提供发布状态查询、提交评审、审批、驳回、发布和撤销发布
+ * @architecture MVC架构 - 控制器层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow 提交评审 -> 审批/驳回 -> 发布(检查质量评分和脱敏覆盖) -> 撤销发布
+ * @rules 操作人取自当前登录用户；状态不允许、审批不足、仍被引用返回409；发布检查未通过返回409并携带检查结果；提交人审批自己的申请返回403
+ * @dependencies datahub-service/service/thematic_library, github.com/go-chi/render
+ * @refs service/thematic_library/publication.go, service/models/thematic_publication.go
+ */
+
+package controllers
+
+import (
+	"datahub-service/service/thematic_library"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+)
+
+// PublicationCommentRequest 提交评审或审批的说明
+type PublicationCommentRequest struct {
+	Comment string `json:"comment" validate:"max=1000" example:"字段口径已与业务方确认"`
+}
+
+// PublicationReasonRequest 驳回或撤销发布的原因
+type PublicationReasonRequest struct {
+	Reason string `json:"reason" validate:"max=1000" example:"质量评分不足，需补充清洗规则"`
+}
+
+// GetThematicInterfacePublication 获取主题接口发布状态
+// @Summary 获取主题接口发布状态
+// @Description 返回接口当前发布状态、所需审批人数、最近一次评审和实时计算的发布检查结果
+// @Tags 主题接口
+// @Produce json
+// @Param id path string true "主题接口ID"
+// @Success 200 {object} APIResponse[thematic_library.PublicationStatus] "获取成功"
+// @Failure 404 {object} APIResponse[any] "主题接口不存在"
+// @Router /thematic-interfaces/{id}/publication [get]
+func (c *ThematicLibraryController) GetThematicInterfacePublication(w http.ResponseWriter, r *http.Request) {
+	status, err := c.service.GetPublication(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		render.JSON(w, r, MapErrorResponse("获取发布状态失败", err))
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("获取发布状态成功", status))
+}
+
+// GetThematicInterfacePublicationReviews 获取主题接口发布评审记录
+// @Summary 获取主题接口发布评审记录
+// @Description 按提交时间倒序返回最近的发布评审，包含审批意见和发布检查结果
+// @Tags 主题接口
+// @Produce json
+// @Param id path string true "主题接口ID"
+// @Success 200 {object} APIResponse[[]models.ThematicPublicationReview] "获取成功"
+// @Failure 404 {object} APIResponse[any] "主题接口不存在"
+// @Router /thematic-interfaces/{id}/publication/reviews [get]
+func (c *ThematicLibraryController) GetThematicInterfacePublicationReviews(w http.ResponseWriter, r *http.Request) {
+	reviews, err := c.service.GetPublicationReviews(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		render.JSON(w, r, MapErrorResponse("获取发布评审记录失败", err))
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("获取发布评审记录成功", reviews))
+}
+
+// SubmitThematicInterfacePublication 提交发布评审
+// @Summary 提交主题接口发布评审
+// @Description 草稿状态的接口提交评审，提交时计算一次发布检查结果并通知审批人
+// @Tags 主题接口
+// @Accept json
+// @Produce json
+// @Param id path string true "主题接口ID"
+// @Param request body PublicationCommentRequest false "提交说明"
+// @Success 200 {object} APIResponse[models.ThematicPublicationReview] "提交成功"
+// @Failure 404 {object} APIResponse[any] "主题接口不存在"
+// @Failure 409 {object} APIResponse[any] "接口不是草稿状态"
+// @Router /thematic-interfaces/{id}/publication/submit [post]
+func (c *ThematicLibraryController) SubmitThematicInterfacePublication(w http.ResponseWriter, r *http.Request) {
+	var req PublicationCommentRequest
+	if r.ContentLength > 0 {
+		if !decodeAndValidate(w, r, &req) {
+			return
+		}
+	}
+
+	review, err := c.service.SubmitForPublication(r.Context(), chi.URLParam(r, "id"), getCurrentUsername(r), req.Comment)
+	if err != nil {
+		respondPublicationError(w, r, "提交发布评审失败", err, review)
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("提交发布评审成功", review))
+}
+
+// ApproveThematicInterfacePublication 审批发布申请
+// @Summary 审批主题接口发布申请
+// @Description 当前用户审批待处理的发布申请，提交人不能审批自己的申请，同一审批人只计一次
+// @Tags 主题接口
+// @Accept json
+// @Produce json
+// @Param id path string true "主题接口ID"
+// @Param request body PublicationCommentRequest false "审批意见"
+// @Success 200 {object} APIResponse[models.ThematicPublicationReview] "审批成功"
+// @Failure 403 {object} APIResponse[any] "提交人不能审批自己的申请"
+// @Failure 404 {object} APIResponse[any] "主题接口不存在"
+// @Failure 409 {object} APIResponse[any] "没有待处理的评审或已审批"
+// @Router /thematic-interfaces/{id}/publication/approve [post]
+func (c *ThematicLibraryController) ApproveThematicInterfacePublication(w http.ResponseWriter, r *http.Request) {
+	var req PublicationCommentRequest
+	if r.ContentLength > 0 {
+		if !decodeAndValidate(w, r, &req) {
+			return
+		}
+	}
+
+	review, err := c.service.ApprovePublication(r.Context(), chi.URLParam(r, "id"), getCurrentUsername(r), req.Comment)
+	if err != nil {
+		respondPublicationError(w, r, "审批发布申请失败", err, review)
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("审批发布申请成功", review))
+}
+
+// RejectThematicInterfacePublication 驳回发布申请
+// @Summary 驳回主题接口发布申请
+// @Description 驳回待处理的发布申请，接口回到草稿状态
+// @Tags 主题接口
+// @Accept json
+// @Produce json
+// @Param id path string true "主题接口ID"
+// @Param request body PublicationReasonRequest false "驳回原因"
+// @Success 200 {object} APIResponse[models.ThematicPublicationReview] "驳回成功"
+// @Failure 404 {object} APIResponse[any] "主题接口不存在"
+// @Failure 409 {object} APIResponse[any] "没有待处理的评审"
+// @Router /thematic-interfaces/{id}/publication/reject [post]
+func (c *ThematicLibraryController) RejectThematicInterfacePublication(w http.ResponseWriter, r *http.Request) {
+	var req PublicationReasonRequest
+	if r.ContentLength > 0 {
+		if !decodeAndValidate(w, r, &req) {
+			return
+		}
+	}
+
+	review, err := c.service.RejectPublication(r.Context(), chi.URLParam(r, "id"), getCurrentUsername(r), req.Reason)
+	if err != nil {
+		respondPublicationError(w, r, "驳回发布申请失败", err, review)
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("驳回发布申请成功", review))
+}
+
+// PublishThematicInterface 发布主题接口
+// @Summary 发布主题接口
+// @Description 审批人数达到要求后重新计算发布检查，质量评分和敏感字段脱敏覆盖都通过才发布；未通过时返回409并携带检查结果
+// @Tags 主题接口
+// @Produce json
+// @Param id path string true "主题接口ID"
+// @Success 200 {object} APIResponse[models.ThematicPublicationReview] "发布成功"
+// @Failure 404 {object} APIResponse[any] "主题接口不存在"
+// @Failure 409 {object} APIResponse[models.ThematicPublicationReview] "审批不足或发布检查未通过"
+// @Router /thematic-interfaces/{id}/publication/publish [post]
+func (c *ThematicLibraryController) PublishThematicInterface(w http.ResponseWriter, r *http.Request) {
+	review, err := c.service.PublishThematicInterface(r.Context(), chi.URLParam(r, "id"), getCurrentUsername(r))
+	if err != nil {
+		respondPublicationError(w, r, "发布主题接口失败", err, review)
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("发布主题接口成功", review))
+}
+
+// UnpublishThematicInterface 撤销发布
+// @Summary 撤销主题接口发布
+// @Description 已发布的接口回到草稿状态；仍被启用的共享接口或灰度发布引用时不能撤销
+// @Tags 主题接口
+// @Accept json
+// @Produce json
+// @Param id path string true "主题接口ID"
+// @Param request body PublicationReasonRequest false "撤销原因"
+// @Success 200 {object} APIResponse[any] "撤销成功"
+// @Failure 404 {object} APIResponse[any] "主题接口不存在"
+// @Failure 409 {object} APIResponse[any] "接口未发布或仍被数据共享引用"
+// @Router /thematic-interfaces/{id}/publication/unpublish [post]
+func (c *ThematicLibraryController) UnpublishThematicInterface(w http.ResponseWriter, r *http.Request) {
+	var req PublicationReasonRequest
+	if r.ContentLength > 0 {
+		if !decodeAndValidate(w, r, &req) {
+			return
+		}
+	}
+
+	if err := c.service.UnpublishThematicInterface(r.Context(), chi.URLParam(r, "id"), getCurrentUsername(r), req.Reason); err != nil {
+		respondPublicationError(w, r, "撤销发布失败", err, nil)
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("撤销发布成功", nil))
+}
+
+// respondPublicationError 发布流程错误映射，发布检查未通过时携带评审和检查结果
+func respondPublicationError(w http.ResponseWriter, r *http.Request, msg string, err error, review interface{}) {
+	switch {
+	case errors.Is(err, thematic_library.ErrPublicationGateFailed):
+		render.JSON(w, r, &APIResponse[any]{
+			Status: StatusConflict,
+			Code:   CodeConflict,
+			Msg:    msg + ": " + err.Error(),
+			Data:   review,
+		})
+	case errors.Is(err, thematic_library.ErrPublicationStateInvalid),
+		errors.Is(err, thematic_library.ErrPublicationNotApproved),
+		errors.Is(err, thematic_library.ErrPublicationInUse):
+		render.JSON(w, r, ConflictResponse(msg+": "+err.Error(), err))
+	case errors.Is(err, thematic_library.ErrPublicationSelfApproval):
+		render.JSON(w, r, ErrorResponse(StatusForbidden, msg+": "+err.Error(), err))
+	default:
+		render.JSON(w, r, MapErrorResponse(msg, err))
+	}
+}
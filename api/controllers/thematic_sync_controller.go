@@ -12,11 +12,12 @@
 package controllers
 
 import (
+	"context"
 	"datahub-service/service"
+	"datahub-service/service/models"
 	"datahub-service/service/thematic_library"
 	"fmt"
 	"net/http"
-	"strconv"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/render"
@@ -53,23 +54,18 @@ type SQLDataSourceConfig struct {
 type ExecuteSyncTaskRequest struct {
 	ExecutionType string                                 `json:"execution_type,omitempty" example:"manual"` // manual, auto
 	Options       *thematic_library.SyncExecutionOptions `json:"options,omitempty"`                         // 执行选项
-	ExecutedBy    string                                 `json:"executed_by" validate:"required" example:"admin"`
 }
 
 // SyncTaskListResponse 同步任务列表响应结构
 type SyncTaskListResponse struct {
-	List  []interface{} `json:"list"`
-	Total int64         `json:"total"`
-	Page  int           `json:"page"`
-	Size  int           `json:"size"`
+	List []interface{} `json:"list"`
+	models.PageMeta
 }
 
 // SyncExecutionListResponse 同步执行记录列表响应结构
 type SyncExecutionListResponse struct {
-	List  []interface{} `json:"list"`
-	Total int64         `json:"total"`
-	Page  int           `json:"page"`
-	Size  int           `json:"size"`
+	List []interface{} `json:"list"`
+	models.PageMeta
 }
 
 // @Summary 创建同步任务
@@ -78,14 +74,13 @@ type SyncExecutionListResponse struct {
 // @Accept json
 // @Produce json
 // @Param request body thematic_library.CreateThematicSyncTaskRequest true "创建同步任务请求"
-// @Success 200 {object} APIResponse
-// @Failure 400 {object} APIResponse
-// @Failure 500 {object} APIResponse
+// @Success 200 {object} APIResponse[any]
+// @Failure 400 {object} APIResponse[any]
+// @Failure 500 {object} APIResponse[any]
 // @Router /thematic-sync/tasks [post]
 func (c *ThematicSyncController) CreateSyncTask(w http.ResponseWriter, r *http.Request) {
 	var req thematic_library.CreateThematicSyncTaskRequest
-	if err := render.DecodeJSON(r.Body, &req); err != nil {
-		render.JSON(w, r, BadRequestResponse("请求参数格式错误", err))
+	if !decodeAndValidate(w, r, &req) {
 		return
 	}
 
@@ -161,17 +156,15 @@ func (c *ThematicSyncController) CreateSyncTask(w http.ResponseWriter, r *http.R
 		return
 	}
 
-	// 设置默认值
-	if req.CreatedBy == "" {
-		req.CreatedBy = "system"
-	}
+	// 创建人取自认证身份
+	req.CreatedBy = getCurrentUsername(r)
 
 	// 直接使用请求结构传递给服务层
 	serviceReq := &req
 
 	task, err := c.thematicSyncService.CreateSyncTask(r.Context(), serviceReq)
 	if err != nil {
-		render.JSON(w, r, InternalErrorResponse("创建同步任务失败", err))
+		render.JSON(w, r, MapErrorResponse("创建同步任务失败", err))
 		return
 	}
 
@@ -189,24 +182,17 @@ func (c *ThematicSyncController) CreateSyncTask(w http.ResponseWriter, r *http.R
 // @Param status query string false "状态过滤" Enums(active,inactive,paused)
 // @Param sync_mode query string false "同步模式过滤" Enums(manual,one_time,timed,cron)
 // @Param thematic_library_id query string false "主题库ID过滤"
-// @Success 200 {object} APIResponse{data=SyncTaskListResponse} "获取成功"
-// @Failure 400 {object} APIResponse "请求参数错误"
-// @Failure 500 {object} APIResponse "服务器内部错误"
+// @Success 200 {object} APIResponse[SyncTaskListResponse] "获取成功"
+// @Failure 400 {object} APIResponse[any] "请求参数错误"
+// @Failure 500 {object} APIResponse[any] "服务器内部错误"
 // @Router /thematic-sync/tasks [get]
 func (c *ThematicSyncController) GetSyncTaskList(w http.ResponseWriter, r *http.Request) {
 	// 解析查询参数
-	page := 1
-	size := 10
 	status := r.URL.Query().Get("status")
 	syncMode := r.URL.Query().Get("sync_mode")
 	thematicLibraryID := r.URL.Query().Get("thematic_library_id")
 
-	if p, err := strconv.Atoi(r.URL.Query().Get("page")); err == nil && p > 0 {
-		page = p
-	}
-	if s, err := strconv.Atoi(r.URL.Query().Get("size")); err == nil && s > 0 && s <= 100 {
-		size = s
-	}
+	page, size := parsePagination(r)
 
 	// 调用服务层方法 - 使用实际的服务接口
 	listReq := &thematic_library.ListSyncTasksRequest{
@@ -219,7 +205,7 @@ func (c *ThematicSyncController) GetSyncTaskList(w http.ResponseWriter, r *http.
 
 	listResp, err := c.thematicSyncService.ListSyncTasks(r.Context(), listReq)
 	if err != nil {
-		render.JSON(w, r, InternalErrorResponse("获取同步任务列表失败", err))
+		render.JSON(w, r, MapErrorResponse("获取同步任务列表失败", err))
 		return
 	}
 
@@ -230,10 +216,8 @@ func (c *ThematicSyncController) GetSyncTaskList(w http.ResponseWriter, r *http.
 	}
 
 	response := SyncTaskListResponse{
-		List:  taskList,
-		Total: listResp.Total,
-		Page:  page,
-		Size:  size,
+		List:     taskList,
+		PageMeta: models.NewPageMeta(listResp.Total, page, size),
 	}
 
 	render.JSON(w, r, SuccessResponse("获取同步任务列表成功", response))
@@ -244,10 +228,10 @@ func (c *ThematicSyncController) GetSyncTaskList(w http.ResponseWriter, r *http.
 // @Tags 主题同步
 // @Produce json
 // @Param id path string true "任务ID"
-// @Success 200 {object} APIResponse
-// @Failure 400 {object} APIResponse
-// @Failure 404 {object} APIResponse
-// @Failure 500 {object} APIResponse
+// @Success 200 {object} APIResponse[any]
+// @Failure 400 {object} APIResponse[any]
+// @Failure 404 {object} APIResponse[any]
+// @Failure 500 {object} APIResponse[any]
 // @Router /thematic-sync/tasks/{id} [get]
 func (c *ThematicSyncController) GetSyncTask(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
@@ -258,10 +242,11 @@ func (c *ThematicSyncController) GetSyncTask(w http.ResponseWriter, r *http.Requ
 
 	task, err := c.thematicSyncService.GetSyncTask(r.Context(), id)
 	if err != nil {
-		render.JSON(w, r, InternalErrorResponse("获取同步任务详情失败", err))
+		render.JSON(w, r, MapErrorResponse("获取同步任务详情失败", err))
 		return
 	}
 
+	setETag(w, task.RowVersion)
 	render.JSON(w, r, SuccessResponse("获取同步任务详情成功", task))
 }
 
@@ -272,10 +257,12 @@ func (c *ThematicSyncController) GetSyncTask(w http.ResponseWriter, r *http.Requ
 // @Produce json
 // @Param id path string true "任务ID"
 // @Param request body thematic_library.UpdateThematicSyncTaskRequest true "更新同步任务请求"
-// @Success 200 {object} APIResponse
-// @Failure 400 {object} APIResponse
-// @Failure 404 {object} APIResponse
-// @Failure 500 {object} APIResponse
+// @Param If-Match header string false "期望版本（ETag），不一致时返回409"
+// @Success 200 {object} APIResponse[any]
+// @Failure 400 {object} APIResponse[any]
+// @Failure 404 {object} APIResponse[any]
+// @Failure 409 {object} APIResponse[VersionConflictData] "版本冲突"
+// @Failure 500 {object} APIResponse[any]
 // @Router /thematic-sync/tasks/{id} [put]
 func (c *ThematicSyncController) UpdateSyncTask(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
@@ -285,25 +272,33 @@ func (c *ThematicSyncController) UpdateSyncTask(w http.ResponseWriter, r *http.R
 	}
 
 	var req thematic_library.UpdateThematicSyncTaskRequest
-	if err := render.DecodeJSON(r.Body, &req); err != nil {
-		render.JSON(w, r, BadRequestResponse("请求参数格式错误", err))
+	if !decodeAndValidate(w, r, &req) {
 		return
 	}
 
-	// 设置默认值
-	if req.UpdatedBy == "" {
-		req.UpdatedBy = "system"
+	// 更新人取自认证身份
+	req.UpdatedBy = getCurrentUsername(r)
+
+	expectedVersion, err := expectedRowVersion(r, req.RowVersion)
+	if err != nil {
+		render.JSON(w, r, BadRequestResponse(err.Error(), err))
+		return
 	}
+	req.RowVersion = expectedVersion
 
 	// 直接使用服务层请求结构
 	serviceReq := &req
 
 	task, err := c.thematicSyncService.UpdateSyncTask(r.Context(), id, serviceReq)
 	if err != nil {
-		render.JSON(w, r, InternalErrorResponse("更新同步任务失败", err))
+		if handleVersionConflict(w, r, err) {
+			return
+		}
+		render.JSON(w, r, MapErrorResponse("更新同步任务失败", err))
 		return
 	}
 
+	setETag(w, task.RowVersion)
 	render.JSON(w, r, SuccessResponse("更新同步任务成功", task))
 }
 
@@ -312,10 +307,10 @@ func (c *ThematicSyncController) UpdateSyncTask(w http.ResponseWriter, r *http.R
 // @Tags 主题同步
 // @Produce json
 // @Param id path string true "任务ID"
-// @Success 200 {object} APIResponse
-// @Failure 400 {object} APIResponse
-// @Failure 404 {object} APIResponse
-// @Failure 500 {object} APIResponse
+// @Success 200 {object} APIResponse[any]
+// @Failure 400 {object} APIResponse[any]
+// @Failure 404 {object} APIResponse[any]
+// @Failure 500 {object} APIResponse[any]
 // @Router /thematic-sync/tasks/{id} [delete]
 func (c *ThematicSyncController) DeleteSyncTask(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
@@ -326,7 +321,7 @@ func (c *ThematicSyncController) DeleteSyncTask(w http.ResponseWriter, r *http.R
 
 	err := c.thematicSyncService.DeleteSyncTask(r.Context(), id)
 	if err != nil {
-		render.JSON(w, r, InternalErrorResponse("删除同步任务失败", err))
+		render.JSON(w, r, MapErrorResponse("删除同步任务失败", err))
 		return
 	}
 
@@ -334,16 +329,16 @@ func (c *ThematicSyncController) DeleteSyncTask(w http.ResponseWriter, r *http.R
 }
 
 // @Summary 执行同步任务
-// @Description 立即异步执行指定的同步任务，返回执行记录ID用于查询进度
+// @Description 立即异步执行指定的同步任务，返回长时操作，execution_id为执行记录ID，通过/operations/{id}查询进度或取消；options.dry_run为true时只试运行不写入，试运行报告记录在执行记录的processing_result中
 // @Tags 主题同步
 // @Accept json
 // @Produce json
 // @Param id path string true "任务ID"
 // @Param request body ExecuteSyncTaskRequest true "执行同步任务请求"
-// @Success 200 {object} APIResponse "返回执行记录ID"
-// @Failure 400 {object} APIResponse
-// @Failure 404 {object} APIResponse
-// @Failure 500 {object} APIResponse
+// @Success 200 {object} APIResponse[models.Operation] "返回长时操作"
+// @Failure 400 {object} APIResponse[any]
+// @Failure 404 {object} APIResponse[any]
+// @Failure 500 {object} APIResponse[any]
 // @Router /thematic-sync/tasks/{id}/execute [post]
 func (c *ThematicSyncController) ExecuteSyncTask(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
@@ -353,15 +348,11 @@ func (c *ThematicSyncController) ExecuteSyncTask(w http.ResponseWriter, r *http.
 	}
 
 	var req ExecuteSyncTaskRequest
-	if err := render.DecodeJSON(r.Body, &req); err != nil {
-		render.JSON(w, r, BadRequestResponse("请求参数格式错误", err))
+	if !decodeAndValidate(w, r, &req) {
 		return
 	}
 
 	// 设置默认值
-	if req.ExecutedBy == "" {
-		req.ExecutedBy = "system"
-	}
 	if req.ExecutionType == "" {
 		req.ExecutionType = "manual"
 	}
@@ -372,19 +363,17 @@ func (c *ThematicSyncController) ExecuteSyncTask(w http.ResponseWriter, r *http.
 		Options:       req.Options,
 	}
 
-	// 异步执行同步任务，立即返回执行记录ID
-	executionID, err := c.thematicSyncService.ExecuteSyncTaskAsync(r.Context(), id, execReq)
+	// 异步执行同步任务，立即返回长时操作，前端可以通过操作查询执行进度和结果
+	op, err := service.GlobalOperationService.Start(r.Context(), models.OperationKindThematicSync, id, getCurrentUsername(r),
+		func(ctx context.Context) (string, error) {
+			return c.thematicSyncService.ExecuteSyncTaskAsync(ctx, id, execReq)
+		})
 	if err != nil {
-		render.JSON(w, r, InternalErrorResponse("启动同步任务失败", err))
+		render.JSON(w, r, MapErrorResponse("启动同步任务失败", err))
 		return
 	}
 
-	// 返回执行记录ID，前端可以通过此ID查询执行进度和结果
-	render.JSON(w, r, SuccessResponse("同步任务已提交执行", map[string]interface{}{
-		"execution_id": executionID,
-		"task_id":      id,
-		"message":      "任务正在后台执行，请通过execution_id查询执行进度",
-	}))
+	render.JSON(w, r, SuccessResponse("同步任务已提交执行", op))
 }
 
 // @Summary 暂停同步任务
@@ -392,10 +381,10 @@ func (c *ThematicSyncController) ExecuteSyncTask(w http.ResponseWriter, r *http.
 // @Tags 主题同步
 // @Produce json
 // @Param id path string true "任务ID"
-// @Success 200 {object} APIResponse
-// @Failure 400 {object} APIResponse
-// @Failure 404 {object} APIResponse
-// @Failure 500 {object} APIResponse
+// @Success 200 {object} APIResponse[any]
+// @Failure 400 {object} APIResponse[any]
+// @Failure 404 {object} APIResponse[any]
+// @Failure 500 {object} APIResponse[any]
 // @Router /thematic-sync/tasks/{id}/pause [post]
 func (c *ThematicSyncController) PauseSyncTask(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
@@ -406,7 +395,7 @@ func (c *ThematicSyncController) PauseSyncTask(w http.ResponseWriter, r *http.Re
 
 	err := c.thematicSyncService.PauseSyncTask(r.Context(), id)
 	if err != nil {
-		render.JSON(w, r, InternalErrorResponse("暂停同步任务失败", err))
+		render.JSON(w, r, MapErrorResponse("暂停同步任务失败", err))
 		return
 	}
 
@@ -418,10 +407,10 @@ func (c *ThematicSyncController) PauseSyncTask(w http.ResponseWriter, r *http.Re
 // @Tags 主题同步
 // @Produce json
 // @Param id path string true "任务ID"
-// @Success 200 {object} APIResponse
-// @Failure 400 {object} APIResponse
-// @Failure 404 {object} APIResponse
-// @Failure 500 {object} APIResponse
+// @Success 200 {object} APIResponse[any]
+// @Failure 400 {object} APIResponse[any]
+// @Failure 404 {object} APIResponse[any]
+// @Failure 500 {object} APIResponse[any]
 // @Router /thematic-sync/tasks/{id}/activate [post]
 func (c *ThematicSyncController) ActivateSyncTask(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
@@ -432,7 +421,7 @@ func (c *ThematicSyncController) ActivateSyncTask(w http.ResponseWriter, r *http
 
 	err := c.thematicSyncService.ActivateSyncTask(r.Context(), id)
 	if err != nil {
-		render.JSON(w, r, InternalErrorResponse("激活同步任务失败", err))
+		render.JSON(w, r, MapErrorResponse("激活同步任务失败", err))
 		return
 	}
 
@@ -444,10 +433,10 @@ func (c *ThematicSyncController) ActivateSyncTask(w http.ResponseWriter, r *http
 // @Tags 主题同步
 // @Produce json
 // @Param id path string true "任务ID"
-// @Success 200 {object} APIResponse
-// @Failure 400 {object} APIResponse
-// @Failure 404 {object} APIResponse
-// @Failure 500 {object} APIResponse
+// @Success 200 {object} APIResponse[any]
+// @Failure 400 {object} APIResponse[any]
+// @Failure 404 {object} APIResponse[any]
+// @Failure 500 {object} APIResponse[any]
 // @Router /thematic-sync/tasks/{id}/status [get]
 func (c *ThematicSyncController) GetSyncTaskStatus(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
@@ -458,7 +447,7 @@ func (c *ThematicSyncController) GetSyncTaskStatus(w http.ResponseWriter, r *htt
 
 	status, err := c.thematicSyncService.GetSyncTaskStatus(r.Context(), id)
 	if err != nil {
-		render.JSON(w, r, InternalErrorResponse("获取同步任务状态失败", err))
+		render.JSON(w, r, MapErrorResponse("获取同步任务状态失败", err))
 		return
 	}
 
@@ -474,9 +463,9 @@ func (c *ThematicSyncController) GetSyncTaskStatus(w http.ResponseWriter, r *htt
 // @Param page query int false "页码" default(1)
 // @Param size query int false "每页大小" default(10)
 // @Param status query string false "执行状态过滤" Enums(running,completed,failed,cancelled)
-// @Success 200 {object} APIResponse{data=SyncExecutionListResponse} "获取成功"
-// @Failure 400 {object} APIResponse "请求参数错误"
-// @Failure 500 {object} APIResponse "服务器内部错误"
+// @Success 200 {object} APIResponse[SyncExecutionListResponse] "获取成功"
+// @Failure 400 {object} APIResponse[any] "请求参数错误"
+// @Failure 500 {object} APIResponse[any] "服务器内部错误"
 // @Router /thematic-sync/tasks/{id}/executions [get]
 func (c *ThematicSyncController) GetSyncTaskExecutions(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
@@ -486,30 +475,21 @@ func (c *ThematicSyncController) GetSyncTaskExecutions(w http.ResponseWriter, r
 	}
 
 	// 解析查询参数
-	page := 1
-	size := 10
 	status := r.URL.Query().Get("status")
 
-	if p, err := strconv.Atoi(r.URL.Query().Get("page")); err == nil && p > 0 {
-		page = p
-	}
-	if s, err := strconv.Atoi(r.URL.Query().Get("size")); err == nil && s > 0 && s <= 100 {
-		size = s
-	}
+	page, size := parsePagination(r)
 
 	// 调用服务层方法
 	executions, total, err := c.thematicSyncService.GetSyncTaskExecutions(r.Context(), id, page, size, status)
 	if err != nil {
-		render.JSON(w, r, InternalErrorResponse("获取同步执行记录失败", err))
+		render.JSON(w, r, MapErrorResponse("获取同步执行记录失败", err))
 		return
 	}
 
 	// 构建响应
 	response := SyncExecutionListResponse{
-		List:  executions,
-		Total: total,
-		Page:  page,
-		Size:  size,
+		List:     executions,
+		PageMeta: models.NewPageMeta(total, page, size),
 	}
 
 	render.JSON(w, r, SuccessResponse("获取同步执行记录成功", response))
@@ -520,10 +500,10 @@ func (c *ThematicSyncController) GetSyncTaskExecutions(w http.ResponseWriter, r
 // @Tags 主题同步
 // @Produce json
 // @Param id path string true "执行记录ID"
-// @Success 200 {object} APIResponse
-// @Failure 400 {object} APIResponse
-// @Failure 404 {object} APIResponse
-// @Failure 500 {object} APIResponse
+// @Success 200 {object} APIResponse[any]
+// @Failure 400 {object} APIResponse[any]
+// @Failure 404 {object} APIResponse[any]
+// @Failure 500 {object} APIResponse[any]
 // @Router /thematic-sync/executions/{id} [get]
 func (c *ThematicSyncController) GetSyncExecution(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
@@ -534,7 +514,7 @@ func (c *ThematicSyncController) GetSyncExecution(w http.ResponseWriter, r *http
 
 	execution, err := c.thematicSyncService.GetSyncExecution(r.Context(), id)
 	if err != nil {
-		render.JSON(w, r, InternalErrorResponse("获取同步执行记录详情失败", err))
+		render.JSON(w, r, MapErrorResponse("获取同步执行记录详情失败", err))
 		return
 	}
 
@@ -546,15 +526,15 @@ func (c *ThematicSyncController) GetSyncExecution(w http.ResponseWriter, r *http
 // @Tags 主题同步
 // @Produce json
 // @Param thematic_library_id query string false "主题库ID过滤"
-// @Success 200 {object} APIResponse
-// @Failure 500 {object} APIResponse
+// @Success 200 {object} APIResponse[any]
+// @Failure 500 {object} APIResponse[any]
 // @Router /thematic-sync/tasks/statistics [get]
 func (c *ThematicSyncController) GetSyncTaskStatistics(w http.ResponseWriter, r *http.Request) {
 	thematicLibraryID := r.URL.Query().Get("thematic_library_id")
 
 	stats, err := c.thematicSyncService.GetSyncTaskStatistics(r.Context(), thematicLibraryID)
 	if err != nil {
-		render.JSON(w, r, InternalErrorResponse("获取同步任务统计信息失败", err))
+		render.JSON(w, r, MapErrorResponse("获取同步任务统计信息失败", err))
 		return
 	}
 
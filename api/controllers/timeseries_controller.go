@@ -0,0 +1,228 @@
+/*
+ * @module api/controllers/timeseries_controller
+ * @description 接口时间序列存储控制器，提供查询、开启或修改、关闭时间序列存储，以及管理降采样连续聚合的接口
+ * @architecture 分层架构 - 控制器层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow HTTP请求 -> 控制器 -> 时间序列存储配置服务 -> 超表、策略和连续聚合DDL并保存接口配置
+ * @rules 统一的错误处理和响应格式；配置不合法时返回400；If-Match与接口版本不一致时返回409；关闭不会把超表恢复为普通表
+ * @dependencies datahub-service/service, datahub-service/service/basic_library, github.com/go-chi/chi/v5
+ * @refs service/basic_library/timeseries_service.go
+ */
+
+package controllers
+
+import (
+	"datahub-service/service"
+	"datahub-service/service/basic_library"
+	"datahub-service/service/models"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+)
+
+// TimeSeriesController 接口时间序列存储控制器
+type TimeSeriesController struct {
+}
+
+// NewTimeSeriesController 创建接口时间序列存储控制器实例
+func NewTimeSeriesController() *TimeSeriesController {
+	return &TimeSeriesController{}
+}
+
+// SaveTimeSeriesRequest 开启或修改时间序列存储请求
+type SaveTimeSeriesRequest struct {
+	TimeColumn      string   `json:"time_column" validate:"required" example:"collected_at"` // 时间列，须为时间戳或日期类型；转换为超表后不能修改
+	TagColumns      []string `json:"tag_columns" example:"device_id"`                        // 标签列；转换为超表后不能修改
+	ChunkInterval   string   `json:"chunk_interval" example:"1 day"`                         // 分块时间间隔，默认7 days
+	CompressAfter   string   `json:"compress_after" example:"7 days"`                        // 超过该时间的分块自动压缩，为空时不压缩
+	RetentionPeriod string   `json:"retention_period" example:"1 year"`                      // 超过该时间的分块自动删除，为空时永久保留
+	IngestEnabled   bool     `json:"ingest_enabled" example:"true"`                          // 是否接收遥测快速写入（/api/v1/ingest/telemetry）
+}
+
+// SaveTimeSeriesAggregateRequest 创建或替换连续聚合请求
+type SaveTimeSeriesAggregateRequest struct {
+	BucketInterval     string                    `json:"bucket_interval" validate:"required" example:"1 hour"`      // 时间桶宽度
+	Metrics            []models.TimeSeriesMetric `json:"metrics" validate:"required"`                               // 聚合指标
+	RefreshStartOffset string                    `json:"refresh_start_offset" validate:"required" example:"3 days"` // 刷新窗口起点距当前的时间
+	RefreshEndOffset   string                    `json:"refresh_end_offset" example:"1 hour"`                       // 刷新窗口终点距当前的时间，默认一个时间桶
+	ScheduleInterval   string                    `json:"schedule_interval" example:"1 hour"`                        // 刷新周期，默认一个时间桶
+}
+
+// GetTimeSeries 获取接口时间序列存储配置
+// @Summary 获取接口时间序列存储配置
+// @Description 获取接口的时间列、标签列、分块间隔、压缩和保留策略、连续聚合配置，以及接口表的超表状态
+// @Tags 数据基础库
+// @Produce json
+// @Param id path string true "接口ID"
+// @Success 200 {object} APIResponse[basic_library.TimeSeriesStatus] "获取成功"
+// @Failure 404 {object} APIResponse[any] "接口不存在"
+// @Router /basic-libraries/interfaces/{id}/timeseries [get]
+func (c *TimeSeriesController) GetTimeSeries(w http.ResponseWriter, r *http.Request) {
+	status, err := service.GlobalTimeSeriesService.GetConfig(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		render.JSON(w, r, MapErrorResponse("获取时间序列存储配置失败", err))
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("获取时间序列存储配置成功", status))
+}
+
+// SaveTimeSeries 开启或修改接口时间序列存储
+// @Summary 开启或修改接口时间序列存储
+// @Description 接口表已创建时立即转换为TimescaleDB超表（已有数据迁移到分块中），未创建时在建表后转换；按配置设置压缩和保留策略。已开启时可修改分块间隔、压缩和保留时间，时间列和标签列不能修改。超表的主键须包含时间列
+// @Tags 数据基础库
+// @Accept json
+// @Produce json
+// @Param id path string true "接口ID"
+// @Param If-Match header string false "期望版本（ETag），不一致时返回409"
+// @Param request body SaveTimeSeriesRequest true "时间序列存储配置"
+// @Success 200 {object} APIResponse[models.TimeSeriesConfig] "设置成功"
+// @Failure 400 {object} APIResponse[any] "配置不合法"
+// @Failure 404 {object} APIResponse[any] "接口不存在"
+// @Failure 409 {object} APIResponse[VersionConflictData] "版本冲突"
+// @Router /basic-libraries/interfaces/{id}/timeseries [put]
+func (c *TimeSeriesController) SaveTimeSeries(w http.ResponseWriter, r *http.Request) {
+	var req SaveTimeSeriesRequest
+	if !decodeAndValidate(w, r, &req) {
+		return
+	}
+
+	expectedVersion, err := expectedRowVersion(r, 0)
+	if err != nil {
+		render.JSON(w, r, BadRequestResponse(err.Error(), err))
+		return
+	}
+
+	config := &models.TimeSeriesConfig{
+		TimeColumn:      req.TimeColumn,
+		TagColumns:      req.TagColumns,
+		ChunkInterval:   req.ChunkInterval,
+		CompressAfter:   req.CompressAfter,
+		RetentionPeriod: req.RetentionPeriod,
+		IngestEnabled:   req.IngestEnabled,
+	}
+	if config.TagColumns == nil {
+		config.TagColumns = []string{}
+	}
+	config, err = service.GlobalTimeSeriesService.SaveConfig(r.Context(), chi.URLParam(r, "id"), expectedVersion, config, getCurrentUsername(r))
+	if err != nil {
+		respondTimeSeriesError(w, r, "设置时间序列存储失败", err)
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("设置时间序列存储成功", config))
+}
+
+// DisableTimeSeries 关闭接口时间序列存储
+// @Summary 关闭接口时间序列存储
+// @Description 删除全部连续聚合及压缩、保留策略并移除配置；接口表保持为超表，已压缩的分块保持压缩
+// @Tags 数据基础库
+// @Produce json
+// @Param id path string true "接口ID"
+// @Param If-Match header string false "期望版本（ETag），不一致时返回409"
+// @Success 200 {object} APIResponse[any] "关闭成功"
+// @Failure 400 {object} APIResponse[any] "接口未开启时间序列存储"
+// @Failure 404 {object} APIResponse[any] "接口不存在"
+// @Failure 409 {object} APIResponse[VersionConflictData] "版本冲突"
+// @Router /basic-libraries/interfaces/{id}/timeseries [delete]
+func (c *TimeSeriesController) DisableTimeSeries(w http.ResponseWriter, r *http.Request) {
+	expectedVersion, err := expectedRowVersion(r, 0)
+	if err != nil {
+		render.JSON(w, r, BadRequestResponse(err.Error(), err))
+		return
+	}
+
+	if err := service.GlobalTimeSeriesService.DisableConfig(r.Context(), chi.URLParam(r, "id"), expectedVersion, getCurrentUsername(r)); err != nil {
+		respondTimeSeriesError(w, r, "关闭时间序列存储失败", err)
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("关闭时间序列存储成功", nil))
+}
+
+// SaveTimeSeriesAggregate 创建或替换接口的降采样连续聚合
+// @Summary 创建或替换降采样连续聚合
+// @Description 创建物化视图"接口表名_聚合名"，按时间桶和标签列分组，指标列名为"列名_函数"，并按刷新周期刷新窗口内的时间桶；同名聚合已存在时重建。avg、sum只能用于数值列，刷新窗口须至少覆盖两个时间桶
+// @Tags 数据基础库
+// @Accept json
+// @Produce json
+// @Param id path string true "接口ID"
+// @Param name path string true "聚合名"
+// @Param If-Match header string false "期望版本（ETag），不一致时返回409"
+// @Param request body SaveTimeSeriesAggregateRequest true "连续聚合配置"
+// @Success 200 {object} APIResponse[models.TimeSeriesConfig] "设置成功"
+// @Failure 400 {object} APIResponse[any] "配置不合法"
+// @Failure 404 {object} APIResponse[any] "接口不存在"
+// @Failure 409 {object} APIResponse[VersionConflictData] "版本冲突"
+// @Router /basic-libraries/interfaces/{id}/timeseries/aggregates/{name} [put]
+func (c *TimeSeriesController) SaveTimeSeriesAggregate(w http.ResponseWriter, r *http.Request) {
+	var req SaveTimeSeriesAggregateRequest
+	if !decodeAndValidate(w, r, &req) {
+		return
+	}
+
+	expectedVersion, err := expectedRowVersion(r, 0)
+	if err != nil {
+		render.JSON(w, r, BadRequestResponse(err.Error(), err))
+		return
+	}
+
+	aggregate := models.TimeSeriesAggregate{
+		Name:               chi.URLParam(r, "name"),
+		BucketInterval:     req.BucketInterval,
+		Metrics:            req.Metrics,
+		RefreshStartOffset: req.RefreshStartOffset,
+		RefreshEndOffset:   req.RefreshEndOffset,
+		ScheduleInterval:   req.ScheduleInterval,
+	}
+	config, err := service.GlobalTimeSeriesService.SaveAggregate(r.Context(), chi.URLParam(r, "id"), expectedVersion, aggregate, getCurrentUsername(r))
+	if err != nil {
+		respondTimeSeriesError(w, r, "设置连续聚合失败", err)
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("设置连续聚合成功", config))
+}
+
+// DeleteTimeSeriesAggregate 删除接口的降采样连续聚合
+// @Summary 删除降采样连续聚合
+// @Description 删除连续聚合的物化视图及其刷新策略
+// @Tags 数据基础库
+// @Produce json
+// @Param id path string true "接口ID"
+// @Param name path string true "聚合名"
+// @Param If-Match header string false "期望版本（ETag），不一致时返回409"
+// @Success 200 {object} APIResponse[any] "删除成功"
+// @Failure 400 {object} APIResponse[any] "连续聚合不存在"
+// @Failure 404 {object} APIResponse[any] "接口不存在"
+// @Failure 409 {object} APIResponse[VersionConflictData] "版本冲突"
+// @Router /basic-libraries/interfaces/{id}/timeseries/aggregates/{name} [delete]
+func (c *TimeSeriesController) DeleteTimeSeriesAggregate(w http.ResponseWriter, r *http.Request) {
+	expectedVersion, err := expectedRowVersion(r, 0)
+	if err != nil {
+		render.JSON(w, r, BadRequestResponse(err.Error(), err))
+		return
+	}
+
+	err = service.GlobalTimeSeriesService.DeleteAggregate(r.Context(), chi.URLParam(r, "id"), expectedVersion, chi.URLParam(r, "name"), getCurrentUsername(r))
+	if err != nil {
+		respondTimeSeriesError(w, r, "删除连续聚合失败", err)
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("删除连续聚合成功", nil))
+}
+
+// respondTimeSeriesError 版本冲突时返回409，配置不合法时返回400，其余按错误类型映射
+func respondTimeSeriesError(w http.ResponseWriter, r *http.Request, msg string, err error) {
+	if handleVersionConflict(w, r, err) {
+		return
+	}
+	if errors.Is(err, basic_library.ErrInvalidTimeSeriesRequest) {
+		render.JSON(w, r, BadRequestResponse(msg, err))
+		return
+	}
+	render.JSON(w, r, MapErrorResponse(msg, err))
+}
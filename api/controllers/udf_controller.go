@@ -0,0 +1,365 @@
+/*
+ * @module api/controllers/udf_controller
+ * @description 自定义函数控制器，提供表达式函数及其版本的登记、提交评审、审批、驳回和试运行接口
+ * @architecture 分层架构 - 控制器层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow HTTP请求 -> 控制器 -> 自定义函数注册表
+ * @rules 统一的错误处理和响应格式；函数或版本不合法返回400，提交人审批自己的版本返回403，版本状态不允许、示例未通过或仍被引用返回409
+ * @dependencies datahub-service/service, datahub-service/service/udf, github.com/go-chi/chi/v5
+ * @refs service/udf/registry.go
+ */
+
+package controllers
+
+import (
+	"datahub-service/service"
+	"datahub-service/service/models"
+	"datahub-service/service/udf"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+)
+
+// UDFController 自定义函数控制器
+type UDFController struct {
+}
+
+// NewUDFController 创建自定义函数控制器实例
+func NewUDFController() *UDFController {
+	return &UDFController{}
+}
+
+// UDFListResponse 自定义函数列表响应结构
+type UDFListResponse struct {
+	List []models.UDFDefinition `json:"list"`
+	models.PageMeta
+}
+
+// UDFReviewRequest 审批或驳回自定义函数版本请求
+type UDFReviewRequest struct {
+	Comment string `json:"comment" validate:"max=1000"`
+}
+
+// UDFSubmitResponse 提交评审结果，包含示例的执行结果
+type UDFSubmitResponse struct {
+	Version  *models.UDFVersion  `json:"version,omitempty"`
+	Examples []udf.ExampleResult `json:"examples"`
+}
+
+// GetUDFs 获取自定义函数列表
+// @Summary 获取自定义函数列表
+// @Description 分页获取自定义函数，current_version为最近审批的版本号，0表示还没有可引用的版本
+// @Tags 自定义函数
+// @Produce json
+// @Param page query int false "页码" default(1)
+// @Param size query int false "每页数量" default(10)
+// @Param keyword query string false "名称或显示名称关键字"
+// @Param kind query string false "用途(quality/cleansing)"
+// @Success 200 {object} APIResponse[UDFListResponse] "获取成功"
+// @Router /udfs [get]
+func (c *UDFController) GetUDFs(w http.ResponseWriter, r *http.Request) {
+	page, size := parsePagination(r)
+	defs, total, err := service.GlobalUDFService.ListUDFs(r.Context(), r.URL.Query().Get("keyword"), r.URL.Query().Get("kind"), page, size)
+	if err != nil {
+		render.JSON(w, r, MapErrorResponse("获取自定义函数列表失败", err))
+		return
+	}
+	render.JSON(w, r, SuccessResponse("获取自定义函数列表成功", UDFListResponse{
+		List:     defs,
+		PageMeta: models.NewPageMeta(total, page, size),
+	}))
+}
+
+// CreateUDF 创建自定义函数
+// @Summary 创建自定义函数
+// @Description 创建自定义函数和第一个草稿版本。kind为quality时表达式必须返回布尔值，true为通过；kind为cleansing时返回清洗后的值。表达式中value为字段值，params声明的参数按名称引用，examples在提交评审时逐个执行
+// @Tags 自定义函数
+// @Accept json
+// @Produce json
+// @Param request body udf.UDFRequest true "函数信息"
+// @Success 200 {object} APIResponse[udf.UDFDetail] "创建成功"
+// @Failure 400 {object} APIResponse[any] "函数不合法"
+// @Router /udfs [post]
+func (c *UDFController) CreateUDF(w http.ResponseWriter, r *http.Request) {
+	var req udf.UDFRequest
+	if !decodeAndValidate(w, r, &req) {
+		return
+	}
+	detail, err := service.GlobalUDFService.CreateUDF(r.Context(), &req, getCurrentUsername(r))
+	if err != nil {
+		render.JSON(w, r, udfErrorResponse("创建自定义函数失败", err))
+		return
+	}
+	render.JSON(w, r, SuccessResponse("创建自定义函数成功", detail))
+}
+
+// GetUDF 获取自定义函数
+// @Summary 获取自定义函数
+// @Description 获取自定义函数及其全部版本，版本按版本号倒序
+// @Tags 自定义函数
+// @Produce json
+// @Param id path string true "函数ID"
+// @Success 200 {object} APIResponse[udf.UDFDetail] "获取成功"
+// @Failure 404 {object} APIResponse[any] "函数不存在"
+// @Router /udfs/{id} [get]
+func (c *UDFController) GetUDF(w http.ResponseWriter, r *http.Request) {
+	detail, err := service.GlobalUDFService.GetUDF(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		render.JSON(w, r, MapErrorResponse("获取自定义函数失败", err))
+		return
+	}
+	render.JSON(w, r, SuccessResponse("获取自定义函数成功", detail))
+}
+
+// UpdateUDF 修改自定义函数
+// @Summary 修改自定义函数
+// @Description 修改显示名称和说明，名称和用途创建后不能修改
+// @Tags 自定义函数
+// @Accept json
+// @Produce json
+// @Param id path string true "函数ID"
+// @Param request body udf.UDFUpdateRequest true "函数信息"
+// @Success 200 {object} APIResponse[models.UDFDefinition] "修改成功"
+// @Failure 404 {object} APIResponse[any] "函数不存在"
+// @Router /udfs/{id} [put]
+func (c *UDFController) UpdateUDF(w http.ResponseWriter, r *http.Request) {
+	var req udf.UDFUpdateRequest
+	if !decodeAndValidate(w, r, &req) {
+		return
+	}
+	def, err := service.GlobalUDFService.UpdateUDF(r.Context(), chi.URLParam(r, "id"), &req, getCurrentUsername(r))
+	if err != nil {
+		render.JSON(w, r, udfErrorResponse("修改自定义函数失败", err))
+		return
+	}
+	render.JSON(w, r, SuccessResponse("修改自定义函数成功", def))
+}
+
+// DeleteUDF 删除自定义函数
+// @Summary 删除自定义函数
+// @Description 删除自定义函数及其全部版本，仍被质量规则或清洗规则引用时不能删除
+// @Tags 自定义函数
+// @Produce json
+// @Param id path string true "函数ID"
+// @Success 200 {object} APIResponse[any] "删除成功"
+// @Failure 404 {object} APIResponse[any] "函数不存在"
+// @Failure 409 {object} APIResponse[any] "仍被规则引用"
+// @Router /udfs/{id} [delete]
+func (c *UDFController) DeleteUDF(w http.ResponseWriter, r *http.Request) {
+	if err := service.GlobalUDFService.DeleteUDF(r.Context(), chi.URLParam(r, "id")); err != nil {
+		render.JSON(w, r, udfErrorResponse("删除自定义函数失败", err))
+		return
+	}
+	render.JSON(w, r, SuccessResponse("删除自定义函数成功", nil))
+}
+
+// CreateUDFVersion 创建自定义函数版本
+// @Summary 创建自定义函数版本
+// @Description 创建新的草稿版本，版本号为已有最大版本号加一；已审批的版本不可修改，修改逻辑需要创建新版本
+// @Tags 自定义函数
+// @Accept json
+// @Produce json
+// @Param id path string true "函数ID"
+// @Param request body udf.VersionRequest true "版本信息"
+// @Success 200 {object} APIResponse[models.UDFVersion] "创建成功"
+// @Failure 400 {object} APIResponse[any] "版本不合法"
+// @Failure 404 {object} APIResponse[any] "函数不存在"
+// @Router /udfs/{id}/versions [post]
+func (c *UDFController) CreateUDFVersion(w http.ResponseWriter, r *http.Request) {
+	var req udf.VersionRequest
+	if !decodeAndValidate(w, r, &req) {
+		return
+	}
+	version, err := service.GlobalUDFService.CreateVersion(r.Context(), chi.URLParam(r, "id"), &req, getCurrentUsername(r))
+	if err != nil {
+		render.JSON(w, r, udfErrorResponse("创建自定义函数版本失败", err))
+		return
+	}
+	render.JSON(w, r, SuccessResponse("创建自定义函数版本成功", version))
+}
+
+// UpdateUDFVersion 修改自定义函数版本
+// @Summary 修改自定义函数版本
+// @Description 修改草稿或被驳回的版本，被驳回的版本回到草稿
+// @Tags 自定义函数
+// @Accept json
+// @Produce json
+// @Param id path string true "函数ID"
+// @Param version path int true "版本号"
+// @Param request body udf.VersionRequest true "版本信息"
+// @Success 200 {object} APIResponse[models.UDFVersion] "修改成功"
+// @Failure 400 {object} APIResponse[any] "版本不合法"
+// @Failure 404 {object} APIResponse[any] "版本不存在"
+// @Failure 409 {object} APIResponse[any] "版本已提交评审或已审批"
+// @Router /udfs/{id}/versions/{version} [put]
+func (c *UDFController) UpdateUDFVersion(w http.ResponseWriter, r *http.Request) {
+	number, ok := parseUDFVersion(w, r)
+	if !ok {
+		return
+	}
+	var req udf.VersionRequest
+	if !decodeAndValidate(w, r, &req) {
+		return
+	}
+	version, err := service.GlobalUDFService.UpdateVersion(r.Context(), chi.URLParam(r, "id"), number, &req, getCurrentUsername(r))
+	if err != nil {
+		render.JSON(w, r, udfErrorResponse("修改自定义函数版本失败", err))
+		return
+	}
+	render.JSON(w, r, SuccessResponse("修改自定义函数版本成功", version))
+}
+
+// SubmitUDFVersion 提交自定义函数版本评审
+// @Summary 提交自定义函数版本评审
+// @Description 执行版本的全部示例，至少有一个示例且全部通过时进入待评审；未通过时返回409并携带示例的执行结果
+// @Tags 自定义函数
+// @Produce json
+// @Param id path string true "函数ID"
+// @Param version path int true "版本号"
+// @Success 200 {object} APIResponse[UDFSubmitResponse] "提交成功"
+// @Failure 404 {object} APIResponse[any] "版本不存在"
+// @Failure 409 {object} APIResponse[UDFSubmitResponse] "不是草稿或示例未通过"
+// @Router /udfs/{id}/versions/{version}/submit [post]
+func (c *UDFController) SubmitUDFVersion(w http.ResponseWriter, r *http.Request) {
+	number, ok := parseUDFVersion(w, r)
+	if !ok {
+		return
+	}
+	version, examples, err := service.GlobalUDFService.SubmitVersion(r.Context(), chi.URLParam(r, "id"), number, getCurrentUsername(r))
+	if err != nil {
+		if errors.Is(err, udf.ErrExamplesFailed) {
+			render.JSON(w, r, &APIResponse[any]{
+				Status: StatusConflict,
+				Code:   CodeConflict,
+				Msg:    "提交评审失败: " + err.Error(),
+				Data:   UDFSubmitResponse{Examples: examples},
+			})
+			return
+		}
+		render.JSON(w, r, udfErrorResponse("提交评审失败", err))
+		return
+	}
+	render.JSON(w, r, SuccessResponse("提交评审成功", UDFSubmitResponse{Version: version, Examples: examples}))
+}
+
+// ApproveUDFVersion 审批自定义函数版本
+// @Summary 审批自定义函数版本
+// @Description 审批待评审的版本，提交人不能审批自己提交的版本；审批时重新执行示例，通过后版本号大于当前版本时成为当前版本
+// @Tags 自定义函数
+// @Accept json
+// @Produce json
+// @Param id path string true "函数ID"
+// @Param version path int true "版本号"
+// @Param request body UDFReviewRequest false "审批意见"
+// @Success 200 {object} APIResponse[models.UDFVersion] "审批成功"
+// @Failure 403 {object} APIResponse[any] "提交人不能审批自己的版本"
+// @Failure 404 {object} APIResponse[any] "版本不存在"
+// @Failure 409 {object} APIResponse[any] "版本不是待评审状态"
+// @Router /udfs/{id}/versions/{version}/approve [post]
+func (c *UDFController) ApproveUDFVersion(w http.ResponseWriter, r *http.Request) {
+	number, ok := parseUDFVersion(w, r)
+	if !ok {
+		return
+	}
+	var req UDFReviewRequest
+	if r.ContentLength > 0 {
+		if !decodeAndValidate(w, r, &req) {
+			return
+		}
+	}
+	version, err := service.GlobalUDFService.ApproveVersion(r.Context(), chi.URLParam(r, "id"), number, getCurrentUsername(r), req.Comment)
+	if err != nil {
+		render.JSON(w, r, udfErrorResponse("审批自定义函数版本失败", err))
+		return
+	}
+	render.JSON(w, r, SuccessResponse("审批自定义函数版本成功", version))
+}
+
+// RejectUDFVersion 驳回自定义函数版本
+// @Summary 驳回自定义函数版本
+// @Description 驳回待评审的版本，驳回后可以修改并重新提交
+// @Tags 自定义函数
+// @Accept json
+// @Produce json
+// @Param id path string true "函数ID"
+// @Param version path int true "版本号"
+// @Param request body UDFReviewRequest false "驳回原因"
+// @Success 200 {object} APIResponse[models.UDFVersion] "驳回成功"
+// @Failure 404 {object} APIResponse[any] "版本不存在"
+// @Failure 409 {object} APIResponse[any] "版本不是待评审状态"
+// @Router /udfs/{id}/versions/{version}/reject [post]
+func (c *UDFController) RejectUDFVersion(w http.ResponseWriter, r *http.Request) {
+	number, ok := parseUDFVersion(w, r)
+	if !ok {
+		return
+	}
+	var req UDFReviewRequest
+	if r.ContentLength > 0 {
+		if !decodeAndValidate(w, r, &req) {
+			return
+		}
+	}
+	version, err := service.GlobalUDFService.RejectVersion(r.Context(), chi.URLParam(r, "id"), number, getCurrentUsername(r), req.Comment)
+	if err != nil {
+		render.JSON(w, r, udfErrorResponse("驳回自定义函数版本失败", err))
+		return
+	}
+	render.JSON(w, r, SuccessResponse("驳回自定义函数版本成功", version))
+}
+
+// TestUDFVersion 试运行自定义函数版本
+// @Summary 试运行自定义函数版本
+// @Description 用给定的字段值和参数试运行任意状态的版本，并返回版本示例的执行结果；表达式执行错误写入结果的error
+// @Tags 自定义函数
+// @Accept json
+// @Produce json
+// @Param id path string true "函数ID"
+// @Param version path int true "版本号"
+// @Param request body udf.TestRequest true "字段值和参数"
+// @Success 200 {object} APIResponse[udf.TestResult] "试运行完成"
+// @Failure 400 {object} APIResponse[any] "表达式不合法"
+// @Failure 404 {object} APIResponse[any] "版本不存在"
+// @Router /udfs/{id}/versions/{version}/test [post]
+func (c *UDFController) TestUDFVersion(w http.ResponseWriter, r *http.Request) {
+	number, ok := parseUDFVersion(w, r)
+	if !ok {
+		return
+	}
+	var req udf.TestRequest
+	if !decodeAndValidate(w, r, &req) {
+		return
+	}
+	result, err := service.GlobalUDFService.TestVersion(r.Context(), chi.URLParam(r, "id"), number, &req)
+	if err != nil {
+		render.JSON(w, r, udfErrorResponse("试运行自定义函数失败", err))
+		return
+	}
+	render.JSON(w, r, SuccessResponse("试运行自定义函数完成", result))
+}
+
+// parseUDFVersion 读取路径中的版本号
+func parseUDFVersion(w http.ResponseWriter, r *http.Request) (int, bool) {
+	number, err := strconv.Atoi(chi.URLParam(r, "version"))
+	if err != nil || number < 1 {
+		render.JSON(w, r, BadRequestResponse("版本号必须是正整数", err))
+		return 0, false
+	}
+	return number, true
+}
+
+// udfErrorResponse 按自定义函数注册表的错误类型创建错误响应
+func udfErrorResponse(msg string, err error) render.Renderer {
+	switch {
+	case errors.Is(err, udf.ErrInvalidUDF):
+		return BadRequestResponse(msg+": "+err.Error(), err)
+	case errors.Is(err, udf.ErrSelfApproval):
+		return ErrorResponse(StatusForbidden, msg+": "+err.Error(), err)
+	case errors.Is(err, udf.ErrVersionState), errors.Is(err, udf.ErrExamplesFailed), errors.Is(err, udf.ErrUDFInUse):
+		return ConflictResponse(msg+": "+err.Error(), err)
+	default:
+		return MapErrorResponse(msg, err)
+	}
+}
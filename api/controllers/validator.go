@@ -0,0 +1,263 @@
+/*
+ * @module api/controllers/validator
+ * @description 请求参数校验层，按请求结构体的validate标签校验，失败时返回字段级错误列表和VALIDATION_FAILED错误码
+ * @architecture 分层架构 - 控制器层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow 解析JSON请求体 -> 按validate标签逐字段校验 -> 嵌套结构体递归校验 -> 返回字段错误
+ * @rules 标签语法与go-playground/validator一致；支持required、omitempty、min、max、len、gt、gte、lt、lte、oneof、email、uuid、dive；字段名使用json标签
+ * @dependencies reflect, github.com/go-chi/render
+ * @refs api/controllers/error_codes.go, api/controllers/response.go
+ */
+
+package controllers
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/go-chi/render"
+)
+
+var (
+	emailPattern = regexp.MustCompile(`^[^@\s]+@[^@\s]+\.[^@\s]+$`)
+	uuidPattern  = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+)
+
+// FieldError 字段校验错误
+type FieldError struct {
+	Field   string `json:"field" example:"interface_ids"`
+	Rule    string `json:"rule" example:"min"`
+	Param   string `json:"param,omitempty" example:"1"`
+	Message string `json:"message" example:"interface_ids长度不能小于1"`
+}
+
+// ValidationErrors 请求参数校验错误
+type ValidationErrors []FieldError
+
+// Error 实现error接口
+func (e ValidationErrors) Error() string {
+	messages := make([]string, 0, len(e))
+	for _, fe := range e {
+		messages = append(messages, fe.Message)
+	}
+	return strings.Join(messages, "; ")
+}
+
+// decodeAndValidate 解析JSON请求体并校验，失败时直接写入错误响应并返回false
+func decodeAndValidate(w http.ResponseWriter, r *http.Request, dst interface{}) bool {
+	if err := render.DecodeJSON(r.Body, dst); err != nil {
+		render.JSON(w, r, ErrorResponseWithCode(StatusBadRequest, CodeInvalidRequest, "请求参数格式错误", err))
+		return false
+	}
+	if errs := validateStruct(dst); len(errs) > 0 {
+		render.JSON(w, r, ValidationErrorResponse(errs))
+		return false
+	}
+	return true
+}
+
+// validateStruct 按validate标签校验结构体，非结构体返回nil
+func validateStruct(v interface{}) ValidationErrors {
+	var errs ValidationErrors
+	validateValue(reflect.ValueOf(v), "", &errs)
+	return errs
+}
+
+// validateValue 校验结构体的各字段，并递归校验嵌套结构体
+func validateValue(v reflect.Value, prefix string, errs *ValidationErrors) {
+	v = indirect(v)
+	if !v.IsValid() || v.Kind() != reflect.Struct {
+		return
+	}
+
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+		jsonName := strings.Split(sf.Tag.Get("json"), ",")[0]
+		if jsonName == "-" {
+			continue
+		}
+		fv := v.Field(i)
+		if sf.Anonymous && jsonName == "" {
+			validateValue(fv, prefix, errs)
+			continue
+		}
+		if jsonName == "" {
+			jsonName = sf.Name
+		}
+		name := jsonName
+		if prefix != "" {
+			name = prefix + "." + jsonName
+		}
+
+		tag := sf.Tag.Get("validate")
+		if tag == "-" {
+			continue
+		}
+		if tag != "" && !validateField(fv, name, tag, errs) {
+			continue
+		}
+		validateValue(fv, name, errs)
+	}
+}
+
+// validateField 按规则校验字段，返回false表示字段为空值或已有错误，无需继续校验嵌套结构
+func validateField(v reflect.Value, name, tag string, errs *ValidationErrors) bool {
+	rules := strings.Split(tag, ",")
+	for i, rule := range rules {
+		ruleName, param, _ := strings.Cut(rule, "=")
+		switch ruleName {
+		case "omitempty":
+			if isEmptyValue(v) {
+				return false
+			}
+		case "required":
+			if isEmptyValue(v) {
+				*errs = append(*errs, FieldError{Field: name, Rule: ruleName, Message: name + "为必填项"})
+				return false
+			}
+		case "dive":
+			elems := indirect(v)
+			if elems.Kind() != reflect.Slice && elems.Kind() != reflect.Array {
+				return true
+			}
+			elemTag := strings.Join(rules[i+1:], ",")
+			for j := 0; j < elems.Len(); j++ {
+				elemName := fmt.Sprintf("%s[%d]", name, j)
+				if elemTag == "" || validateField(elems.Index(j), elemName, elemTag, errs) {
+					validateValue(elems.Index(j), elemName, errs)
+				}
+			}
+			return false
+		default:
+			if msg, ok := checkRule(indirect(v), ruleName, param, name); !ok {
+				*errs = append(*errs, FieldError{Field: name, Rule: ruleName, Param: param, Message: msg})
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// checkRule 校验单条规则，失败时返回错误信息
+func checkRule(v reflect.Value, rule, param, name string) (string, bool) {
+	if !v.IsValid() {
+		return "", true
+	}
+
+	switch rule {
+	case "min", "max", "len", "gt", "gte", "lt", "lte":
+		value, isLength, ok := measure(v)
+		if !ok {
+			return "", true
+		}
+		limit, err := strconv.ParseFloat(param, 64)
+		if err != nil {
+			return fmt.Sprintf("%s的校验规则%s参数无效", name, rule), false
+		}
+		return compareRule(value, limit, isLength, rule, param, name)
+	case "oneof":
+		options := strings.Fields(param)
+		actual := fmt.Sprint(v.Interface())
+		for _, option := range options {
+			if actual == option {
+				return "", true
+			}
+		}
+		return fmt.Sprintf("%s必须是[%s]之一", name, strings.Join(options, " ")), false
+	case "email":
+		if v.Kind() == reflect.String && !emailPattern.MatchString(v.String()) {
+			return name + "不是有效的邮箱地址", false
+		}
+		return "", true
+	case "uuid":
+		if v.Kind() == reflect.String && !uuidPattern.MatchString(v.String()) {
+			return name + "不是有效的UUID", false
+		}
+		return "", true
+	default:
+		return fmt.Sprintf("%s使用了不支持的校验规则%s", name, rule), false
+	}
+}
+
+// compareRule 比较长度或数值与规则参数
+func compareRule(value, limit float64, isLength bool, rule, param, name string) (string, bool) {
+	subject := name
+	if isLength {
+		subject = name + "长度"
+	}
+	switch rule {
+	case "min", "gte":
+		if value < limit {
+			return fmt.Sprintf("%s不能小于%s", subject, param), false
+		}
+	case "max", "lte":
+		if value > limit {
+			return fmt.Sprintf("%s不能大于%s", subject, param), false
+		}
+	case "len":
+		if value != limit {
+			return fmt.Sprintf("%s必须为%s", subject, param), false
+		}
+	case "gt":
+		if value <= limit {
+			return fmt.Sprintf("%s必须大于%s", subject, param), false
+		}
+	case "lt":
+		if value >= limit {
+			return fmt.Sprintf("%s必须小于%s", subject, param), false
+		}
+	}
+	return "", true
+}
+
+// measure 获取字段的比较值：字符串、切片、映射取长度，数字取值
+func measure(v reflect.Value) (value float64, isLength bool, ok bool) {
+	switch v.Kind() {
+	case reflect.String:
+		return float64(utf8.RuneCountInString(v.String())), true, true
+	case reflect.Slice, reflect.Map, reflect.Array:
+		return float64(v.Len()), true, true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int()), false, true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(v.Uint()), false, true
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), false, true
+	default:
+		return 0, false, false
+	}
+}
+
+// isEmptyValue 判断字段是否为空值：引用类型为nil，结构体视为非空，其他类型为零值
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Invalid:
+		return true
+	case reflect.Ptr, reflect.Interface, reflect.Slice, reflect.Map, reflect.Chan, reflect.Func:
+		return v.IsNil()
+	case reflect.Struct:
+		return false
+	default:
+		return v.IsZero()
+	}
+}
+
+// indirect 解引用指针和接口
+func indirect(v reflect.Value) reflect.Value {
+	for v.IsValid() && (v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface) {
+		if v.IsNil() {
+			return reflect.Value{}
+		}
+		v = v.Elem()
+	}
+	return v
+}
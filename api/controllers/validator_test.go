@@ -0,0 +1,133 @@
+/*
+ * @module api/controllers/validator_test
+ * @description 请求参数校验层测试，覆盖校验规则、嵌套结构、错误响应格式
+ * @architecture 测试层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow 构造请求结构体 -> 校验 -> 验证字段错误和响应错误码
+ * @rules 不依赖外部服务
+ * @dependencies testing, net/http/httptest, stretchr/testify
+ * @refs api/controllers/validator.go, api/controllers/error_codes.go
+ */
+
+package controllers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type validatorItem struct {
+	Field string `json:"field" validate:"required"`
+	Order string `json:"order" validate:"omitempty,oneof=ASC DESC"`
+}
+
+type validatorRequest struct {
+	Name     string          `json:"name" validate:"required,max=4"`
+	Email    string          `json:"email" validate:"omitempty,email"`
+	Count    int             `json:"count" validate:"gte=1,lte=10"`
+	Tags     []string        `json:"tags" validate:"required,min=1,dive,required"`
+	Items    []validatorItem `json:"items" validate:"dive"`
+	Nested   *validatorItem  `json:"nested,omitempty"`
+	Ignored  string          `json:"-" validate:"required"`
+	Optional string          `json:"optional"`
+}
+
+func TestValidateStructPasses(t *testing.T) {
+	req := validatorRequest{
+		Name:  "数据中台",
+		Count: 3,
+		Tags:  []string{"a"},
+		Items: []validatorItem{{Field: "id", Order: "ASC"}},
+	}
+	assert.Empty(t, validateStruct(&req))
+	assert.Nil(t, validateStruct("not a struct"))
+}
+
+func TestValidateStructErrors(t *testing.T) {
+	req := validatorRequest{
+		Name:   "too long",
+		Email:  "invalid",
+		Count:  0,
+		Tags:   []string{""},
+		Items:  []validatorItem{{Order: "RANDOM"}},
+		Nested: &validatorItem{},
+	}
+	errs := validateStruct(&req)
+
+	fields := map[string]string{}
+	for _, fe := range errs {
+		fields[fe.Field] = fe.Rule
+	}
+	assert.Equal(t, map[string]string{
+		"name":           "max",
+		"email":          "email",
+		"count":          "gte",
+		"tags[0]":        "required",
+		"items[0].field": "required",
+		"items[0].order": "oneof",
+		"nested.field":   "required",
+	}, fields)
+	assert.Contains(t, errs.Error(), "name长度不能大于4")
+}
+
+func TestValidateRequired(t *testing.T) {
+	errs := validateStruct(&validatorRequest{Count: 1})
+	require.Len(t, errs, 2)
+	assert.Equal(t, FieldError{Field: "name", Rule: "required", Message: "name为必填项"}, errs[0])
+	assert.Equal(t, "tags", errs[1].Field)
+}
+
+func TestDecodeAndValidateResponses(t *testing.T) {
+	cases := []struct {
+		name string
+		body string
+		ok   bool
+		code string
+	}{
+		{name: "格式错误", body: "{", code: CodeInvalidRequest},
+		{name: "校验失败", body: `{"name":"a","count":1}`, code: CodeValidationFailed},
+		{name: "通过", body: `{"name":"a","count":1,"tags":["x"]}`, ok: true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tc.body))
+			r.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			var req validatorRequest
+			assert.Equal(t, tc.ok, decodeAndValidate(w, r, &req))
+			if tc.ok {
+				assert.Equal(t, 0, w.Body.Len())
+				return
+			}
+
+			var resp struct {
+				Status int    `json:"status"`
+				Code   string `json:"code"`
+				Data   struct {
+					Errors []FieldError `json:"errors"`
+				} `json:"data"`
+			}
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+			assert.Equal(t, StatusBadRequest, resp.Status)
+			assert.Equal(t, tc.code, resp.Code)
+			if tc.code == CodeValidationFailed {
+				require.Len(t, resp.Data.Errors, 1)
+				assert.Equal(t, "tags", resp.Data.Errors[0].Field)
+			}
+		})
+	}
+}
+
+func TestErrorResponseDefaultCode(t *testing.T) {
+	assert.Equal(t, CodeNotFound, NotFoundResponse("不存在", nil).(*APIResponse[any]).Code)
+	assert.Equal(t, CodeInternalError, InternalErrorResponse("失败", nil).(*APIResponse[any]).Code)
+	assert.Empty(t, SuccessResponse("成功", nil).(*APIResponse[any]).Code)
+}
@@ -0,0 +1,222 @@
+/*
+ * @module api/controllers/value_alert_controller
+ * @description 数据值告警控制器，提供接口上阈值和断流告警规则的查询、创建、修改、删除、立即判定以及告警记录查询的接口
+ * @architecture 分层架构 - 控制器层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow HTTP请求 -> 控制器 -> 数据值告警服务 -> 规则、告警记录和应用事件
+ * @rules 统一的错误处理和响应格式；规则不合法时返回400；告警通过应用事件推送到通知中心，按接口订阅即可收到
+ * @dependencies datahub-service/service, datahub-service/service/basic_library, github.com/go-chi/chi/v5
+ * @refs service/basic_library/value_alert_service.go
+ */
+
+package controllers
+
+import (
+	"datahub-service/service"
+	"datahub-service/service/basic_library"
+	"datahub-service/service/models"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+)
+
+// ValueAlertController 数据值告警控制器
+type ValueAlertController struct {
+}
+
+// NewValueAlertController 创建数据值告警控制器实例
+func NewValueAlertController() *ValueAlertController {
+	return &ValueAlertController{}
+}
+
+// ValueAlertRuleRequest 创建或修改数据值告警规则请求
+type ValueAlertRuleRequest struct {
+	Name        string   `json:"name" validate:"required" example:"温度过高"`
+	RuleType    string   `json:"rule_type" validate:"required,oneof=threshold offline" example:"threshold"` // threshold：窗口内聚合值与阈值比较；offline：窗口内没有新数据
+	TimeColumn  string   `json:"time_column" example:"collected_at"`                                        // 判定窗口依据的时间列，默认取时间序列配置的时间列
+	Column      string   `json:"column" example:"temperature"`                                              // threshold规则比较的列
+	Aggregate   string   `json:"aggregate" example:"last"`                                                  // threshold规则的聚合函数：avg/min/max/sum/count/last
+	Operator    string   `json:"operator" example:"gt"`                                                     // threshold规则的比较符：gt/gte/lt/lte/eq/ne
+	Threshold   *float64 `json:"threshold,omitempty" example:"35"`                                          // threshold规则的阈值
+	Window      string   `json:"window" validate:"required" example:"5 minutes"`                            // 判定窗口
+	GroupColumn string   `json:"group_column" example:"device_id"`                                          // 分组列，如设备ID，为空时对整个接口判定
+	Severity    string   `json:"severity" example:"warning"`                                                // info/warning/critical，默认warning
+	Enabled     *bool    `json:"enabled,omitempty" example:"true"`                                          // 默认启用
+	Description string   `json:"description" example:"机房温度超过35度"`
+}
+
+// ValueAlertListResponse 数据值告警列表响应结构
+type ValueAlertListResponse struct {
+	List []models.ValueAlert `json:"list"`
+	models.PageMeta
+}
+
+// toRule 转换为规则
+func (req *ValueAlertRuleRequest) toRule() *models.ValueAlertRule {
+	return &models.ValueAlertRule{
+		Name:        req.Name,
+		RuleType:    req.RuleType,
+		TimeColumn:  req.TimeColumn,
+		Column:      req.Column,
+		Aggregate:   req.Aggregate,
+		Operator:    req.Operator,
+		Threshold:   req.Threshold,
+		Window:      req.Window,
+		GroupColumn: req.GroupColumn,
+		Severity:    req.Severity,
+		Enabled:     req.Enabled == nil || *req.Enabled,
+		Description: req.Description,
+	}
+}
+
+// ListValueAlertRules 获取接口的数据值告警规则
+// @Summary 获取接口的数据值告警规则
+// @Description 获取接口上配置的阈值和断流告警规则，包括最近一次判定时间和判定失败原因
+// @Tags 数据基础库
+// @Produce json
+// @Param id path string true "接口ID"
+// @Success 200 {object} APIResponse[[]models.ValueAlertRule] "获取成功"
+// @Failure 404 {object} APIResponse[any] "接口不存在"
+// @Router /basic-libraries/interfaces/{id}/value-alert-rules [get]
+func (c *ValueAlertController) ListValueAlertRules(w http.ResponseWriter, r *http.Request) {
+	rules, err := service.GlobalValueAlertService.ListRules(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		render.JSON(w, r, MapErrorResponse("获取数据值告警规则失败", err))
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("获取数据值告警规则成功", rules))
+}
+
+// CreateValueAlertRule 创建数据值告警规则
+// @Summary 创建数据值告警规则
+// @Description 在接口上创建告警规则。threshold规则对判定窗口内的数据按聚合函数计算后与阈值比较，如近1小时能耗合计大于X；offline规则在窗口内没有新数据时告警，如温度传感器离线。设置分组列时按分组分别判定。规则在同步写入、遥测写入后及定时判定，命中时产生告警并以value_alert_firing事件推送到通知中心，恢复时推送value_alert_resolved事件
+// @Tags 数据基础库
+// @Accept json
+// @Produce json
+// @Param id path string true "接口ID"
+// @Param request body ValueAlertRuleRequest true "告警规则"
+// @Success 200 {object} APIResponse[models.ValueAlertRule] "创建成功"
+// @Failure 400 {object} APIResponse[any] "规则不合法"
+// @Failure 404 {object} APIResponse[any] "接口不存在"
+// @Router /basic-libraries/interfaces/{id}/value-alert-rules [post]
+func (c *ValueAlertController) CreateValueAlertRule(w http.ResponseWriter, r *http.Request) {
+	var req ValueAlertRuleRequest
+	if !decodeAndValidate(w, r, &req) {
+		return
+	}
+
+	rule, err := service.GlobalValueAlertService.CreateRule(r.Context(), chi.URLParam(r, "id"), req.toRule(), getCurrentUsername(r))
+	if err != nil {
+		respondValueAlertError(w, r, "创建数据值告警规则失败", err)
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("创建数据值告警规则成功", rule))
+}
+
+// UpdateValueAlertRule 修改数据值告警规则
+// @Summary 修改数据值告警规则
+// @Description 修改规则的全部配置；判定条件变化或停用规则时，规则当前的告警直接结束，不推送恢复通知
+// @Tags 数据基础库
+// @Accept json
+// @Produce json
+// @Param id path string true "接口ID"
+// @Param rule_id path string true "规则ID"
+// @Param request body ValueAlertRuleRequest true "告警规则"
+// @Success 200 {object} APIResponse[models.ValueAlertRule] "修改成功"
+// @Failure 400 {object} APIResponse[any] "规则不合法"
+// @Failure 404 {object} APIResponse[any] "接口或规则不存在"
+// @Router /basic-libraries/interfaces/{id}/value-alert-rules/{rule_id} [put]
+func (c *ValueAlertController) UpdateValueAlertRule(w http.ResponseWriter, r *http.Request) {
+	var req ValueAlertRuleRequest
+	if !decodeAndValidate(w, r, &req) {
+		return
+	}
+
+	rule, err := service.GlobalValueAlertService.UpdateRule(r.Context(), chi.URLParam(r, "id"), chi.URLParam(r, "rule_id"), req.toRule(), getCurrentUsername(r))
+	if err != nil {
+		respondValueAlertError(w, r, "修改数据值告警规则失败", err)
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("修改数据值告警规则成功", rule))
+}
+
+// DeleteValueAlertRule 删除数据值告警规则
+// @Summary 删除数据值告警规则
+// @Description 删除规则及其告警记录
+// @Tags 数据基础库
+// @Produce json
+// @Param id path string true "接口ID"
+// @Param rule_id path string true "规则ID"
+// @Success 200 {object} APIResponse[any] "删除成功"
+// @Failure 404 {object} APIResponse[any] "接口或规则不存在"
+// @Router /basic-libraries/interfaces/{id}/value-alert-rules/{rule_id} [delete]
+func (c *ValueAlertController) DeleteValueAlertRule(w http.ResponseWriter, r *http.Request) {
+	err := service.GlobalValueAlertService.DeleteRule(r.Context(), chi.URLParam(r, "id"), chi.URLParam(r, "rule_id"), getCurrentUsername(r))
+	if err != nil {
+		render.JSON(w, r, MapErrorResponse("删除数据值告警规则失败", err))
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("删除数据值告警规则成功", nil))
+}
+
+// EvaluateValueAlertRule 立即判定数据值告警规则
+// @Summary 立即判定数据值告警规则
+// @Description 立即按规则查询接口数据并更新告警状态，停用的规则也可以判定，便于配置后验证
+// @Tags 数据基础库
+// @Produce json
+// @Param id path string true "接口ID"
+// @Param rule_id path string true "规则ID"
+// @Success 200 {object} APIResponse[basic_library.ValueAlertEvaluation] "判定完成"
+// @Failure 404 {object} APIResponse[any] "接口或规则不存在"
+// @Failure 500 {object} APIResponse[any] "判定失败"
+// @Router /basic-libraries/interfaces/{id}/value-alert-rules/{rule_id}/evaluate [post]
+func (c *ValueAlertController) EvaluateValueAlertRule(w http.ResponseWriter, r *http.Request) {
+	evaluation, err := service.GlobalValueAlertService.EvaluateRule(r.Context(), chi.URLParam(r, "id"), chi.URLParam(r, "rule_id"))
+	if err != nil {
+		render.JSON(w, r, MapErrorResponse("判定数据值告警规则失败", err))
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("判定数据值告警规则完成", evaluation))
+}
+
+// ListValueAlerts 获取接口的数据值告警
+// @Summary 获取接口的数据值告警
+// @Description 分页获取接口的告警记录，告警中的排在前面
+// @Tags 数据基础库
+// @Produce json
+// @Param id path string true "接口ID"
+// @Param status query string false "告警状态" Enums(firing, resolved)
+// @Param page query int false "页码" default(1)
+// @Param size query int false "每页数量" default(10)
+// @Success 200 {object} APIResponse[ValueAlertListResponse] "获取成功"
+// @Failure 404 {object} APIResponse[any] "接口不存在"
+// @Router /basic-libraries/interfaces/{id}/value-alerts [get]
+func (c *ValueAlertController) ListValueAlerts(w http.ResponseWriter, r *http.Request) {
+	page, size := parsePagination(r)
+	alerts, total, err := service.GlobalValueAlertService.ListAlerts(r.Context(), chi.URLParam(r, "id"), r.URL.Query().Get("status"), page, size)
+	if err != nil {
+		render.JSON(w, r, MapErrorResponse("获取数据值告警失败", err))
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("获取数据值告警成功", ValueAlertListResponse{
+		List:     alerts,
+		PageMeta: models.NewPageMeta(total, page, size),
+	}))
+}
+
+// respondValueAlertError 规则不合法时返回400，其余按错误类型映射
+func respondValueAlertError(w http.ResponseWriter, r *http.Request, msg string, err error) {
+	if errors.Is(err, basic_library.ErrInvalidValueAlertRequest) {
+		render.JSON(w, r, BadRequestResponse(msg, err))
+		return
+	}
+	render.JSON(w, r, MapErrorResponse(msg, err))
+}
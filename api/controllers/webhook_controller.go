@@ -0,0 +1,298 @@
+/*
+ * @module api/controllers/webhook_controller
+ * @description 平台事件Webhook控制器，提供Webhook的登记、修改、删除、密钥轮换、测试推送，以及投递日志的查询和重新投递接口
+ * @architecture 分层架构 - 控制器层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow HTTP请求 -> 控制器 -> Webhook服务 -> 数据库/后台投递器
+ * @rules 统一的错误处理和响应格式；签名密钥只在创建和轮换时返回；配置不合法返回400，对停用的Webhook重新投递返回409
+ * @dependencies datahub-service/service, datahub-service/service/webhook, github.com/go-chi/chi/v5
+ * @refs service/webhook/webhook_service.go, service/webhook/dispatcher.go
+ */
+
+package controllers
+
+import (
+	"datahub-service/service"
+	"datahub-service/service/models"
+	"datahub-service/service/webhook"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+)
+
+// WebhookController 平台事件Webhook控制器
+type WebhookController struct {
+}
+
+// NewWebhookController 创建平台事件Webhook控制器实例
+func NewWebhookController() *WebhookController {
+	return &WebhookController{}
+}
+
+// WebhookSecretResponse 创建Webhook或轮换密钥的响应结构
+type WebhookSecretResponse struct {
+	Webhook models.WebhookEndpoint `json:"webhook"`
+	Secret  string                 `json:"secret" example:"whsec_3f9a..."` // 签名密钥，仅返回一次
+}
+
+// WebhookListResponse Webhook列表响应结构
+type WebhookListResponse struct {
+	List []models.WebhookEndpoint `json:"list"`
+	models.PageMeta
+}
+
+// WebhookDeliveryListResponse 投递日志列表响应结构
+type WebhookDeliveryListResponse struct {
+	List []models.WebhookDelivery `json:"list"`
+	models.PageMeta
+}
+
+// GetWebhookEventTypes 获取平台事件类型
+// @Summary 获取平台事件类型
+// @Description 获取Webhook可订阅的平台事件类型及其对应的内部应用事件
+// @Tags Webhook
+// @Produce json
+// @Success 200 {object} APIResponse[[]webhook.EventTypeInfo] "获取成功"
+// @Router /webhooks/event-types [get]
+func (c *WebhookController) GetWebhookEventTypes(w http.ResponseWriter, r *http.Request) {
+	render.JSON(w, r, SuccessResponse("获取平台事件类型成功", service.GlobalWebhookService.EventTypes()))
+}
+
+// GetWebhooks 获取Webhook列表
+// @Summary 获取Webhook列表
+// @Description 分页获取外部系统登记的Webhook，不返回签名密钥
+// @Tags Webhook
+// @Produce json
+// @Param page query int false "页码" default(1)
+// @Param size query int false "每页数量" default(10)
+// @Success 200 {object} APIResponse[WebhookListResponse] "获取成功"
+// @Router /webhooks [get]
+func (c *WebhookController) GetWebhooks(w http.ResponseWriter, r *http.Request) {
+	page, size := parsePagination(r)
+	endpoints, total, err := service.GlobalWebhookService.ListEndpoints(r.Context(), page, size)
+	if err != nil {
+		render.JSON(w, r, MapErrorResponse("获取Webhook列表失败", err))
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("获取Webhook列表成功", WebhookListResponse{
+		List:     endpoints,
+		PageMeta: models.NewPageMeta(total, page, size),
+	}))
+}
+
+// CreateWebhook 创建Webhook
+// @Summary 创建Webhook
+// @Description 登记接收地址、签名密钥和订阅的平台事件类型，事件类型为空时订阅全部平台事件；密钥为空时自动生成，响应中返回密钥（仅此一次）
+// @Tags Webhook
+// @Accept json
+// @Produce json
+// @Param request body webhook.EndpointRequest true "Webhook信息"
+// @Success 200 {object} APIResponse[WebhookSecretResponse] "创建成功"
+// @Failure 400 {object} APIResponse[any] "配置不合法"
+// @Failure 409 {object} APIResponse[any] "名称已存在"
+// @Router /webhooks [post]
+func (c *WebhookController) CreateWebhook(w http.ResponseWriter, r *http.Request) {
+	var req webhook.EndpointRequest
+	if !decodeAndValidate(w, r, &req) {
+		return
+	}
+
+	endpoint, secret, err := service.GlobalWebhookService.CreateEndpoint(r.Context(), &req, getCurrentUsername(r))
+	if err != nil {
+		render.JSON(w, r, webhookErrorResponse("创建Webhook失败", err))
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("创建Webhook成功", WebhookSecretResponse{Webhook: *endpoint, Secret: secret}))
+}
+
+// GetWebhook 获取Webhook
+// @Summary 获取Webhook
+// @Description 获取Webhook配置，不返回签名密钥
+// @Tags Webhook
+// @Produce json
+// @Param id path string true "Webhook ID"
+// @Success 200 {object} APIResponse[models.WebhookEndpoint] "获取成功"
+// @Failure 404 {object} APIResponse[any] "Webhook不存在"
+// @Router /webhooks/{id} [get]
+func (c *WebhookController) GetWebhook(w http.ResponseWriter, r *http.Request) {
+	endpoint, err := service.GlobalWebhookService.GetEndpoint(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		render.JSON(w, r, MapErrorResponse("获取Webhook失败", err))
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("获取Webhook成功", endpoint))
+}
+
+// UpdateWebhook 修改Webhook
+// @Summary 修改Webhook
+// @Description 修改接收地址、订阅的事件类型和启用状态，密钥为空时保持不变；停用后未完成的投递记为失败
+// @Tags Webhook
+// @Accept json
+// @Produce json
+// @Param id path string true "Webhook ID"
+// @Param request body webhook.EndpointRequest true "Webhook信息"
+// @Success 200 {object} APIResponse[models.WebhookEndpoint] "修改成功"
+// @Failure 400 {object} APIResponse[any] "配置不合法"
+// @Failure 404 {object} APIResponse[any] "Webhook不存在"
+// @Router /webhooks/{id} [put]
+func (c *WebhookController) UpdateWebhook(w http.ResponseWriter, r *http.Request) {
+	var req webhook.EndpointRequest
+	if !decodeAndValidate(w, r, &req) {
+		return
+	}
+
+	endpoint, err := service.GlobalWebhookService.UpdateEndpoint(r.Context(), chi.URLParam(r, "id"), &req, getCurrentUsername(r))
+	if err != nil {
+		render.JSON(w, r, webhookErrorResponse("修改Webhook失败", err))
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("修改Webhook成功", endpoint))
+}
+
+// DeleteWebhook 删除Webhook
+// @Summary 删除Webhook
+// @Description 删除Webhook及其投递日志
+// @Tags Webhook
+// @Produce json
+// @Param id path string true "Webhook ID"
+// @Success 200 {object} APIResponse[any] "删除成功"
+// @Failure 404 {object} APIResponse[any] "Webhook不存在"
+// @Router /webhooks/{id} [delete]
+func (c *WebhookController) DeleteWebhook(w http.ResponseWriter, r *http.Request) {
+	if err := service.GlobalWebhookService.DeleteEndpoint(r.Context(), chi.URLParam(r, "id")); err != nil {
+		render.JSON(w, r, MapErrorResponse("删除Webhook失败", err))
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("删除Webhook成功", nil))
+}
+
+// RotateWebhookSecret 轮换签名密钥
+// @Summary 轮换签名密钥
+// @Description 生成新的签名密钥并返回（仅此一次），之后的推送（含重试）使用新密钥签名
+// @Tags Webhook
+// @Produce json
+// @Param id path string true "Webhook ID"
+// @Success 200 {object} APIResponse[WebhookSecretResponse] "轮换成功"
+// @Failure 404 {object} APIResponse[any] "Webhook不存在"
+// @Router /webhooks/{id}/rotate [post]
+func (c *WebhookController) RotateWebhookSecret(w http.ResponseWriter, r *http.Request) {
+	endpoint, secret, err := service.GlobalWebhookService.RotateSecret(r.Context(), chi.URLParam(r, "id"), getCurrentUsername(r))
+	if err != nil {
+		render.JSON(w, r, MapErrorResponse("轮换签名密钥失败", err))
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("轮换签名密钥成功", WebhookSecretResponse{Webhook: *endpoint, Secret: secret}))
+}
+
+// PingWebhook 测试推送
+// @Summary 测试推送
+// @Description 向Webhook同步推送一次ping事件，不重试，返回投递日志，可从中查看响应状态码和响应体
+// @Tags Webhook
+// @Produce json
+// @Param id path string true "Webhook ID"
+// @Success 200 {object} APIResponse[models.WebhookDelivery] "推送完成"
+// @Failure 404 {object} APIResponse[any] "Webhook不存在"
+// @Router /webhooks/{id}/test [post]
+func (c *WebhookController) PingWebhook(w http.ResponseWriter, r *http.Request) {
+	delivery, err := service.GlobalWebhookService.Ping(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		render.JSON(w, r, MapErrorResponse("测试推送失败", err))
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("测试推送完成", delivery))
+}
+
+// GetWebhookDeliveries 获取投递日志
+// @Summary 获取投递日志
+// @Description 分页获取投递日志，按创建时间倒序，可按Webhook、事件类型、事件ID和状态过滤
+// @Tags Webhook
+// @Produce json
+// @Param webhook_id query string false "Webhook ID"
+// @Param event_type query string false "平台事件类型"
+// @Param event_id query string false "平台事件ID"
+// @Param status query string false "投递状态" Enums(pending, delivering, succeeded, failed)
+// @Param page query int false "页码" default(1)
+// @Param size query int false "每页数量" default(10)
+// @Success 200 {object} APIResponse[WebhookDeliveryListResponse] "获取成功"
+// @Router /webhooks/deliveries [get]
+func (c *WebhookController) GetWebhookDeliveries(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	page, size := parsePagination(r)
+	deliveries, total, err := service.GlobalWebhookService.ListDeliveries(r.Context(), webhook.DeliveryQuery{
+		EndpointID: query.Get("webhook_id"),
+		EventType:  query.Get("event_type"),
+		EventID:    query.Get("event_id"),
+		Status:     query.Get("status"),
+		Page:       page,
+		Size:       size,
+	})
+	if err != nil {
+		render.JSON(w, r, MapErrorResponse("获取投递日志失败", err))
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("获取投递日志成功", WebhookDeliveryListResponse{
+		List:     deliveries,
+		PageMeta: models.NewPageMeta(total, page, size),
+	}))
+}
+
+// GetWebhookDelivery 获取投递详情
+// @Summary 获取投递详情
+// @Description 获取投递的请求体、尝试次数和最近一次尝试的响应
+// @Tags Webhook
+// @Produce json
+// @Param id path string true "投递ID"
+// @Success 200 {object} APIResponse[models.WebhookDelivery] "获取成功"
+// @Failure 404 {object} APIResponse[any] "投递不存在"
+// @Router /webhooks/deliveries/{id} [get]
+func (c *WebhookController) GetWebhookDelivery(w http.ResponseWriter, r *http.Request) {
+	delivery, err := service.GlobalWebhookService.GetDelivery(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		render.JSON(w, r, MapErrorResponse("获取投递详情失败", err))
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("获取投递详情成功", delivery))
+}
+
+// RedeliverWebhookDelivery 重新投递
+// @Summary 重新投递
+// @Description 以相同的事件ID和请求体创建新的投递，由后台投递器推送并按退避策略重试
+// @Tags Webhook
+// @Produce json
+// @Param id path string true "投递ID"
+// @Success 200 {object} APIResponse[models.WebhookDelivery] "已提交重新投递"
+// @Failure 404 {object} APIResponse[any] "投递不存在"
+// @Failure 409 {object} APIResponse[any] "Webhook已停用"
+// @Router /webhooks/deliveries/{id}/redeliver [post]
+func (c *WebhookController) RedeliverWebhookDelivery(w http.ResponseWriter, r *http.Request) {
+	delivery, err := service.GlobalWebhookService.Redeliver(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		render.JSON(w, r, webhookErrorResponse("重新投递失败", err))
+		return
+	}
+
+	render.JSON(w, r, SuccessResponse("已提交重新投递", delivery))
+}
+
+// webhookErrorResponse 按Webhook的错误类型创建错误响应
+func webhookErrorResponse(msg string, err error) render.Renderer {
+	switch {
+	case errors.Is(err, webhook.ErrInvalidWebhook):
+		return BadRequestResponse(msg+": "+err.Error(), err)
+	case errors.Is(err, webhook.ErrWebhookDisabled):
+		return ConflictResponse(msg, err)
+	default:
+		return MapErrorResponse(msg, err)
+	}
+}
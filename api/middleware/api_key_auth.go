@@ -0,0 +1,114 @@
+/*
+ * @module api/middleware/api_key_auth
+ * @description ApiKey认证中间件，供Webhook推送、共享数据API等机器调用方使用
+ * @architecture 中间件模式 - HTTP请求拦截和验证
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow Key提取 -> 状态/过期/授权范围校验 -> 上下文注入 -> 下一个处理器
+ * @rules 支持X-API-Key头或Bearer格式的Authorization头；路由需加入PostgREST认证白名单
+ * @dependencies datahub-service/service/sharing, net/http
+ * @refs service/sharing/api_key.go, api/routes.go
+ */
+
+package middleware
+
+import (
+	"context"
+	"datahub-service/service/models"
+	"datahub-service/service/sharing"
+	"errors"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/render"
+)
+
+// ApiKeyContextKey ApiKey在上下文中的键
+const ApiKeyContextKey ContextKey = "api_key"
+
+// ApiKeyHeader ApiKey请求头
+const ApiKeyHeader = "X-API-Key"
+
+// ApiKeyAuthenticator ApiKey认证接口
+type ApiKeyAuthenticator interface {
+	AuthenticateApiKey(keyValue, scope, clientIP string) (*models.ApiKey, error)
+}
+
+// ApiKeyAuthMiddleware ApiKey认证中间件
+type ApiKeyAuthMiddleware struct {
+	authenticator ApiKeyAuthenticator
+}
+
+// NewApiKeyAuthMiddleware 创建ApiKey认证中间件实例
+func NewApiKeyAuthMiddleware(authenticator ApiKeyAuthenticator) *ApiKeyAuthMiddleware {
+	return &ApiKeyAuthMiddleware{authenticator: authenticator}
+}
+
+// RequireScope 创建需要指定授权范围的ApiKey认证中间件
+func (m *ApiKeyAuthMiddleware) RequireScope(scope string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			keyValue := ExtractApiKey(r)
+			if keyValue == "" {
+				respondJSONError(w, r, http.StatusUnauthorized, "缺少API Key，请使用X-API-Key头或Bearer Token")
+				return
+			}
+
+			apiKey, err := m.authenticator.AuthenticateApiKey(keyValue, scope, requestClientIP(r))
+			if err != nil {
+				if errors.Is(err, sharing.ErrApiKeyScopeDenied) {
+					respondJSONError(w, r, http.StatusForbidden, err.Error())
+					return
+				}
+				respondJSONError(w, r, http.StatusUnauthorized, "API Key验证失败: "+err.Error())
+				return
+			}
+
+			ctx := context.WithValue(r.Context(), ApiKeyContextKey, apiKey)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// ExtractApiKey 从请求头中提取ApiKey，优先使用X-API-Key头
+func ExtractApiKey(r *http.Request) string {
+	if key := strings.TrimSpace(r.Header.Get(ApiKeyHeader)); key != "" {
+		return key
+	}
+	authHeader := r.Header.Get("Authorization")
+	if strings.HasPrefix(authHeader, "Bearer ") {
+		return strings.TrimSpace(strings.TrimPrefix(authHeader, "Bearer "))
+	}
+	return ""
+}
+
+// GetApiKeyFromContext 从上下文中获取已认证的ApiKey
+func GetApiKeyFromContext(ctx context.Context) (*models.ApiKey, bool) {
+	apiKey, ok := ctx.Value(ApiKeyContextKey).(*models.ApiKey)
+	return apiKey, ok
+}
+
+// requestClientIP 获取客户端IP，优先使用代理转发头
+func requestClientIP(r *http.Request) string {
+	if forwarded := r.Header.Get("X-Forwarded-For"); forwarded != "" {
+		return strings.TrimSpace(strings.Split(forwarded, ",")[0])
+	}
+	if realIP := r.Header.Get("X-Real-IP"); realIP != "" {
+		return realIP
+	}
+	if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		return host
+	}
+	return r.RemoteAddr
+}
+
+// respondJSONError 返回统一格式的错误响应
+func respondJSONError(w http.ResponseWriter, r *http.Request, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	render.JSON(w, r, map[string]interface{}{
+		"status":  status,
+		"message": message,
+		"error":   http.StatusText(status),
+	})
+}
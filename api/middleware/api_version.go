@@ -0,0 +1,254 @@
+/*
+ * @module api/middleware/api_version
+ * @description API版本路由，按版本创建子路由并登记版本级和路由级的弃用计划，响应头返回API-Version、Deprecation、Sunset和后继版本链接
+ * @architecture 中间件模式 - HTTP请求拦截
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow 请求进入版本子路由 -> 设置API-Version -> 按版本内路径匹配弃用路由，未登记时取版本弃用计划 -> 设置弃用响应头 -> 执行处理器
+ * @rules 版本路径前缀为/api/{version}，旧版无前缀路径为legacy版本；新版本只需登记有破坏性变更的路由，未登记的路由回落到上一版本处理；
+ *        回落时由外层版本设置响应头；到达停用时间后仍正常响应，停用由运维下线路由完成
+ * @dependencies github.com/go-chi/chi/v5
+ * @refs api/routes.go, api/controllers/api_version_controller.go
+ */
+
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// API版本
+const (
+	APIVersionLegacy = "legacy" // 无版本前缀的旧路径
+	APIVersionV1     = "v1"
+	APIVersionV2     = "v2"
+)
+
+// API版本相关的响应头
+const (
+	APIVersionHeader  = "API-Version"
+	DeprecationHeader = "Deprecation"
+	SunsetHeader      = "Sunset"
+)
+
+// APIVersionPrefix 返回版本的路径前缀，legacy版本没有前缀
+func APIVersionPrefix(version string) string {
+	if version == APIVersionLegacy {
+		return ""
+	}
+	return "/api/" + version
+}
+
+// APIDeprecation 弃用计划
+type APIDeprecation struct {
+	Since     time.Time // 弃用时间，为零时Deprecation头返回true
+	Sunset    time.Time // 计划停用时间，为零时不返回Sunset头
+	Successor string    // 后继版本，非空时返回Link头指向后继版本的同一路径
+}
+
+// APIDeprecatedRoute 弃用的路由
+type APIDeprecatedRoute struct {
+	Method          string     `json:"method"`
+	Pattern         string     `json:"pattern"`
+	DeprecatedSince *time.Time `json:"deprecated_since,omitempty"`
+	Sunset          *time.Time `json:"sunset,omitempty"`
+	Successor       string     `json:"successor,omitempty"`
+}
+
+// APIVersionInfo 版本说明
+type APIVersionInfo struct {
+	Version          string               `json:"version"`
+	PathPrefix       string               `json:"path_prefix"`
+	Current          bool                 `json:"current"`
+	Deprecated       bool                 `json:"deprecated"`
+	DeprecatedSince  *time.Time           `json:"deprecated_since,omitempty"`
+	Sunset           *time.Time           `json:"sunset,omitempty"`
+	Successor        string               `json:"successor,omitempty"`
+	DeprecatedRoutes []APIDeprecatedRoute `json:"deprecated_routes,omitempty"`
+}
+
+// apiVersion 一个版本的弃用计划
+type apiVersion struct {
+	deprecation *APIDeprecation
+	// routes 登记弃用的路由，只用于按版本内路径匹配路由模板
+	routes            *chi.Mux
+	routeDeprecations map[string]APIDeprecation // method+" "+pattern -> 弃用计划
+}
+
+// APIVersionRegistry API版本登记表
+type APIVersionRegistry struct {
+	mu       sync.RWMutex
+	current  string
+	versions map[string]*apiVersion
+	order    []string
+}
+
+// NewAPIVersionRegistry 创建API版本登记表，current为当前推荐使用的版本
+func NewAPIVersionRegistry(current string) *APIVersionRegistry {
+	return &APIVersionRegistry{
+		current:  current,
+		versions: make(map[string]*apiVersion),
+	}
+}
+
+// version 获取版本，不存在时登记，调用方须持有写锁
+func (reg *APIVersionRegistry) version(version string) *apiVersion {
+	v, ok := reg.versions[version]
+	if !ok {
+		v = &apiVersion{
+			routes:            chi.NewRouter(),
+			routeDeprecations: make(map[string]APIDeprecation),
+		}
+		reg.versions[version] = v
+		reg.order = append(reg.order, version)
+	}
+	return v
+}
+
+// Router 创建版本子路由，build登记该版本的路由；fallback非空时未登记的路由和方法交给fallback处理，用于新版本只覆盖有变更的路由
+func (reg *APIVersionRegistry) Router(version string, fallback http.Handler, build func(r chi.Router)) *chi.Mux {
+	reg.mu.Lock()
+	reg.version(version)
+	reg.mu.Unlock()
+
+	router := chi.NewRouter()
+	router.Use(reg.Middleware(version))
+	if fallback != nil {
+		router.NotFound(fallback.ServeHTTP)
+		router.MethodNotAllowed(fallback.ServeHTTP)
+	}
+	build(router)
+	return router
+}
+
+// DeprecateVersion 登记整个版本的弃用计划
+func (reg *APIVersionRegistry) DeprecateVersion(version string, deprecation APIDeprecation) {
+	reg.mu.Lock()
+	defer reg.mu.Unlock()
+	reg.version(version).deprecation = &deprecation
+}
+
+// DeprecateRoute 登记版本内单个路由的弃用计划，pattern为版本内的路由模板（不含版本前缀），优先于版本的弃用计划
+func (reg *APIVersionRegistry) DeprecateRoute(version, method, pattern string, deprecation APIDeprecation) {
+	method = strings.ToUpper(method)
+	reg.mu.Lock()
+	defer reg.mu.Unlock()
+	v := reg.version(version)
+	if _, exists := v.routeDeprecations[method+" "+pattern]; !exists {
+		v.routes.Method(method, pattern, http.NotFoundHandler())
+	}
+	v.routeDeprecations[method+" "+pattern] = deprecation
+}
+
+// deprecationFor 获取请求适用的弃用计划
+func (reg *APIVersionRegistry) deprecationFor(version, method, routePath string) *APIDeprecation {
+	reg.mu.RLock()
+	defer reg.mu.RUnlock()
+	v, ok := reg.versions[version]
+	if !ok {
+		return nil
+	}
+	if len(v.routeDeprecations) > 0 {
+		rctx := chi.NewRouteContext()
+		if v.routes.Match(rctx, method, routePath) {
+			if deprecation, ok := v.routeDeprecations[method+" "+rctx.RoutePattern()]; ok {
+				return &deprecation
+			}
+		}
+	}
+	return v.deprecation
+}
+
+// Middleware 版本中间件，设置API-Version和弃用相关的响应头；外层版本已设置时直接放行
+func (reg *APIVersionRegistry) Middleware(version string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if w.Header().Get(APIVersionHeader) != "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+			w.Header().Set(APIVersionHeader, version)
+
+			routePath := r.URL.Path
+			if rctx := chi.RouteContext(r.Context()); rctx != nil && rctx.RoutePath != "" {
+				routePath = rctx.RoutePath
+			}
+			if deprecation := reg.deprecationFor(version, r.Method, routePath); deprecation != nil {
+				setDeprecationHeaders(w, r, version, routePath, deprecation)
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// setDeprecationHeaders 设置Deprecation、Sunset和指向后继版本同一路径的Link头
+func setDeprecationHeaders(w http.ResponseWriter, r *http.Request, version, routePath string, deprecation *APIDeprecation) {
+	if deprecation.Since.IsZero() {
+		w.Header().Set(DeprecationHeader, "true")
+	} else {
+		w.Header().Set(DeprecationHeader, fmt.Sprintf("@%d", deprecation.Since.Unix()))
+	}
+	if !deprecation.Sunset.IsZero() {
+		w.Header().Set(SunsetHeader, deprecation.Sunset.UTC().Format(http.TimeFormat))
+	}
+	if deprecation.Successor != "" {
+		// 请求路径去掉版本内路径和版本前缀后为服务挂载路径
+		basePath := strings.TrimSuffix(strings.TrimSuffix(r.URL.Path, routePath), APIVersionPrefix(version))
+		successor := basePath + APIVersionPrefix(deprecation.Successor) + routePath
+		w.Header().Add("Link", fmt.Sprintf(`<%s>; rel="successor-version"`, successor))
+	}
+}
+
+// Describe 返回已登记版本的说明，按登记顺序排列
+func (reg *APIVersionRegistry) Describe() []APIVersionInfo {
+	reg.mu.RLock()
+	defer reg.mu.RUnlock()
+
+	infos := make([]APIVersionInfo, 0, len(reg.order))
+	for _, version := range reg.order {
+		v := reg.versions[version]
+		info := APIVersionInfo{
+			Version:    version,
+			PathPrefix: APIVersionPrefix(version),
+			Current:    version == reg.current,
+		}
+		if v.deprecation != nil {
+			info.Deprecated = true
+			info.DeprecatedSince = optionalTime(v.deprecation.Since)
+			info.Sunset = optionalTime(v.deprecation.Sunset)
+			info.Successor = v.deprecation.Successor
+		}
+		for key, deprecation := range v.routeDeprecations {
+			method, pattern, _ := strings.Cut(key, " ")
+			info.DeprecatedRoutes = append(info.DeprecatedRoutes, APIDeprecatedRoute{
+				Method:          method,
+				Pattern:         pattern,
+				DeprecatedSince: optionalTime(deprecation.Since),
+				Sunset:          optionalTime(deprecation.Sunset),
+				Successor:       deprecation.Successor,
+			})
+		}
+		sort.Slice(info.DeprecatedRoutes, func(i, j int) bool {
+			if info.DeprecatedRoutes[i].Pattern != info.DeprecatedRoutes[j].Pattern {
+				return info.DeprecatedRoutes[i].Pattern < info.DeprecatedRoutes[j].Pattern
+			}
+			return info.DeprecatedRoutes[i].Method < info.DeprecatedRoutes[j].Method
+		})
+		infos = append(infos, info)
+	}
+	return infos
+}
+
+// optionalTime 零值时间返回nil
+func optionalTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
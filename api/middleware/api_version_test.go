@@ -0,0 +1,130 @@
+/*
+ * @module api/middleware/api_version_test
+ * @description API版本路由测试，覆盖legacy、v1、v2共存、新版本回落到旧版本处理、版本和路由的弃用响应头、后继版本链接以及版本说明
+ * @architecture 测试层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow 按版本挂载路由 -> 请求各版本路径 -> 验证处理器和响应头
+ * @rules 使用httptest，不依赖外部服务
+ * @dependencies net/http/httptest, stretchr/testify
+ * @refs api/middleware/api_version.go
+ */
+
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeBody(body string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(body))
+	}
+}
+
+// newVersionedRouter 按routes.go的方式挂载legacy、v1和只覆盖部分路由的v2
+func newVersionedRouter(reg *APIVersionRegistry) *chi.Mux {
+	shared := chi.NewRouter()
+	shared.Get("/items/{id}", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("v1 item " + chi.URLParam(r, "id")))
+	})
+	shared.Post("/items", writeBody("v1 create"))
+	shared.Get("/reports", writeBody("v1 reports"))
+
+	v1 := reg.Router(APIVersionV1, nil, func(r chi.Router) {
+		r.Mount("/", shared)
+	})
+	v2 := reg.Router(APIVersionV2, v1, func(r chi.Router) {
+		r.Get("/items/{id}", func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte("v2 item " + chi.URLParam(r, "id")))
+		})
+	})
+
+	root := chi.NewRouter()
+	root.Route("/datahub", func(r chi.Router) {
+		r.Mount(APIVersionPrefix(APIVersionV1), v1)
+		r.Mount(APIVersionPrefix(APIVersionV2), v2)
+		r.Get("/health", writeBody("ok"))
+		r.Mount("/", reg.Router(APIVersionLegacy, nil, func(r chi.Router) {
+			r.Mount("/", shared)
+		}))
+	})
+	return root
+}
+
+func serveVersioned(router http.Handler, method, path string) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(method, path, nil))
+	return rec
+}
+
+func TestAPIVersionRouting(t *testing.T) {
+	reg := NewAPIVersionRegistry(APIVersionV1)
+	router := newVersionedRouter(reg)
+
+	cases := []struct {
+		method, path, body, version string
+	}{
+		{http.MethodGet, "/datahub/items/7", "v1 item 7", APIVersionLegacy},
+		{http.MethodGet, "/datahub/api/v1/items/7", "v1 item 7", APIVersionV1},
+		{http.MethodGet, "/datahub/api/v2/items/7", "v2 item 7", APIVersionV2},
+		{http.MethodGet, "/datahub/api/v2/reports", "v1 reports", APIVersionV2},
+		{http.MethodPost, "/datahub/api/v2/items", "v1 create", APIVersionV2},
+	}
+	for _, c := range cases {
+		rec := serveVersioned(router, c.method, c.path)
+		require.Equal(t, http.StatusOK, rec.Code, c.path)
+		assert.Equal(t, c.body, rec.Body.String(), c.path)
+		assert.Equal(t, c.version, rec.Header().Get(APIVersionHeader), "v2未覆盖的路由由v1处理，响应头仍为请求的版本: %s", c.path)
+		assert.Empty(t, rec.Header().Get(DeprecationHeader), c.path)
+	}
+
+	health := serveVersioned(router, http.MethodGet, "/datahub/health")
+	assert.Equal(t, "ok", health.Body.String())
+	assert.Empty(t, health.Header().Get(APIVersionHeader), "版本路由外的路径不受影响")
+	assert.Equal(t, http.StatusNotFound, serveVersioned(router, http.MethodGet, "/datahub/api/v2/missing").Code)
+}
+
+func TestAPIVersionDeprecation(t *testing.T) {
+	reg := NewAPIVersionRegistry(APIVersionV1)
+	router := newVersionedRouter(reg)
+
+	sunset := time.Date(2027, 6, 30, 0, 0, 0, 0, time.UTC)
+	since := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	reg.DeprecateVersion(APIVersionLegacy, APIDeprecation{Sunset: sunset, Successor: APIVersionV1})
+	reg.DeprecateRoute(APIVersionV1, "get", "/items/{id}", APIDeprecation{Since: since, Successor: APIVersionV2})
+
+	legacy := serveVersioned(router, http.MethodGet, "/datahub/reports")
+	assert.Equal(t, "v1 reports", legacy.Body.String(), "弃用的路径仍正常响应")
+	assert.Equal(t, "true", legacy.Header().Get(DeprecationHeader))
+	assert.Equal(t, "Wed, 30 Jun 2027 00:00:00 GMT", legacy.Header().Get(SunsetHeader))
+	assert.Equal(t, `</datahub/api/v1/reports>; rel="successor-version"`, legacy.Header().Get("Link"))
+
+	deprecatedRoute := serveVersioned(router, http.MethodGet, "/datahub/api/v1/items/7")
+	assert.Equal(t, "@1790812800", deprecatedRoute.Header().Get(DeprecationHeader))
+	assert.Empty(t, deprecatedRoute.Header().Get(SunsetHeader))
+	assert.Equal(t, `</datahub/api/v2/items/7>; rel="successor-version"`, deprecatedRoute.Header().Get("Link"))
+
+	assert.Empty(t, serveVersioned(router, http.MethodPost, "/datahub/api/v1/items").Header().Get(DeprecationHeader), "只有登记的方法和路由弃用")
+	assert.Empty(t, serveVersioned(router, http.MethodGet, "/datahub/api/v1/reports").Header().Get(DeprecationHeader))
+	assert.Empty(t, serveVersioned(router, http.MethodGet, "/datahub/api/v2/reports").Header().Get(DeprecationHeader), "v2回落到v1处理时不返回v1的弃用计划")
+
+	infos := reg.Describe()
+	require.Len(t, infos, 3)
+	assert.Equal(t, APIVersionV1, infos[0].Version)
+	assert.True(t, infos[0].Current)
+	assert.Equal(t, "/api/v1", infos[0].PathPrefix)
+	assert.False(t, infos[0].Deprecated)
+	assert.Equal(t, []APIDeprecatedRoute{{Method: http.MethodGet, Pattern: "/items/{id}", DeprecatedSince: &since, Successor: APIVersionV2}}, infos[0].DeprecatedRoutes)
+	assert.Equal(t, APIVersionLegacy, infos[2].Version)
+	assert.Equal(t, "", infos[2].PathPrefix)
+	assert.True(t, infos[2].Deprecated)
+	assert.Equal(t, &sunset, infos[2].Sunset)
+	assert.Nil(t, infos[2].DeprecatedSince)
+}
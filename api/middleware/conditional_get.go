@@ -0,0 +1,167 @@
+/*
+ * @module api/middleware/conditional_get
+ * @description 条件请求中间件，目录类查询接口（库、接口、规则、元数据）返回ETag和Last-Modified，客户端携带If-None-Match或If-Modified-Since且内容未变化时返回304，减少重复传输
+ * @architecture 中间件模式 - HTTP请求拦截和响应缓冲
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow 缓冲GET响应 -> 成功的JSON响应计算ETag（对象版本加响应内容摘要） -> 比较If-None-Match/If-Modified-Since -> 返回304或原响应
+ * @rules
+ *   - 只处理GET请求中业务状态码为0的JSON响应；错误响应、非JSON响应（CSV等）和流式响应原样透传
+ *   - 处理器已按row_version设置ETag时保留版本号作为前缀（"版本-摘要"），If-Match仍按版本号校验；响应中的关联对象变化时摘要随之变化
+ *   - If-None-Match优先于If-Modified-Since；Last-Modified由处理器按updated_at设置，只用于响应仅包含对象本身的详情接口（规则等），含同步状态等关联数据的接口只依赖ETag
+ *   - 未设置Cache-Control时返回private, no-cache，客户端可缓存但每次使用前须重新验证
+ * @dependencies net/http, crypto/sha256
+ * @refs api/routes.go, api/controllers/row_version.go
+ */
+
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+)
+
+// successResponsePrefix 业务状态码为0的统一响应体前缀
+var successResponsePrefix = []byte(`{"status":0,`)
+
+// ConditionalGET 条件请求中间件
+func ConditionalGET(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			next.ServeHTTP(w, r)
+			return
+		}
+		cw := &conditionalWriter{ResponseWriter: w}
+		next.ServeHTTP(cw, r)
+		cw.finish(r)
+	})
+}
+
+// conditionalWriter 缓冲响应，非JSON或非200的响应切换为透传
+type conditionalWriter struct {
+	http.ResponseWriter
+	status      int
+	passthrough bool
+	body        bytes.Buffer
+}
+
+// WriteHeader 记录状态码，非200时透传
+func (cw *conditionalWriter) WriteHeader(status int) {
+	if cw.passthrough {
+		cw.ResponseWriter.WriteHeader(status)
+		return
+	}
+	if cw.status != 0 {
+		return
+	}
+	cw.status = status
+	if status != http.StatusOK {
+		cw.startPassthrough()
+	}
+}
+
+// Write 缓冲JSON响应体，其他响应透传
+func (cw *conditionalWriter) Write(b []byte) (int, error) {
+	if !cw.passthrough {
+		if cw.status == 0 {
+			cw.status = http.StatusOK
+		}
+		if cw.body.Len() == 0 && !strings.HasPrefix(cw.Header().Get("Content-Type"), "application/json") {
+			cw.startPassthrough()
+		}
+	}
+	if cw.passthrough {
+		return cw.ResponseWriter.Write(b)
+	}
+	return cw.body.Write(b)
+}
+
+// Flush 流式响应不做条件请求处理
+func (cw *conditionalWriter) Flush() {
+	if !cw.passthrough {
+		cw.startPassthrough()
+	}
+	_ = http.NewResponseController(cw.ResponseWriter).Flush()
+}
+
+// Unwrap 供http.ResponseController访问底层ResponseWriter
+func (cw *conditionalWriter) Unwrap() http.ResponseWriter {
+	return cw.ResponseWriter
+}
+
+// startPassthrough 写出已缓冲的状态码和响应体，之后的写入直接透传
+func (cw *conditionalWriter) startPassthrough() {
+	cw.passthrough = true
+	if cw.status != 0 {
+		cw.ResponseWriter.WriteHeader(cw.status)
+	}
+	if cw.body.Len() > 0 {
+		_, _ = cw.ResponseWriter.Write(cw.body.Bytes())
+		cw.body.Reset()
+	}
+}
+
+// finish 按条件请求头返回304或写出缓冲的响应
+func (cw *conditionalWriter) finish(r *http.Request) {
+	if cw.passthrough || cw.status == 0 {
+		return
+	}
+	body := cw.body.Bytes()
+	if !bytes.HasPrefix(body, successResponsePrefix) {
+		cw.startPassthrough()
+		return
+	}
+
+	header := cw.Header()
+	etag := contentETag(header.Get("ETag"), body)
+	header.Set("ETag", etag)
+	if header.Get("Cache-Control") == "" {
+		header.Set("Cache-Control", "private, no-cache")
+	}
+	if notModified(r, etag, header.Get("Last-Modified")) {
+		header.Del("Content-Type")
+		header.Del("Content-Length")
+		cw.ResponseWriter.WriteHeader(http.StatusNotModified)
+		return
+	}
+	cw.startPassthrough()
+}
+
+// contentETag 按响应内容摘要生成ETag，处理器设置的版本ETag作为前缀保留
+func contentETag(versionETag string, body []byte) string {
+	sum := sha256.Sum256(body)
+	digest := hex.EncodeToString(sum[:8])
+	if version := strings.Trim(strings.TrimPrefix(versionETag, "W/"), `"`); version != "" {
+		return `"` + version + "-" + digest + `"`
+	}
+	return `"` + digest + `"`
+}
+
+// notModified 判断客户端缓存是否仍然有效
+func notModified(r *http.Request, etag, lastModified string) bool {
+	if ifNoneMatch := r.Header.Get("If-None-Match"); ifNoneMatch != "" {
+		for _, candidate := range strings.Split(ifNoneMatch, ",") {
+			candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+			if candidate == "*" || candidate == etag {
+				return true
+			}
+		}
+		return false
+	}
+
+	ifModifiedSince := r.Header.Get("If-Modified-Since")
+	if ifModifiedSince == "" || lastModified == "" {
+		return false
+	}
+	since, err := http.ParseTime(ifModifiedSince)
+	if err != nil {
+		return false
+	}
+	modified, err := http.ParseTime(lastModified)
+	if err != nil {
+		return false
+	}
+	return !modified.After(since)
+}
@@ -0,0 +1,127 @@
+/*
+ * @module api/middleware/conditional_get_test
+ * @description 条件请求中间件测试，覆盖内容摘要ETag、保留版本前缀、If-None-Match和If-Modified-Since返回304、错误响应和流式响应透传
+ * @architecture 测试层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow 构造处理器 -> 携带条件请求头访问 -> 验证状态码、响应头和响应体
+ * @rules 使用httptest，不依赖外部服务
+ * @dependencies net/http/httptest, stretchr/testify
+ * @refs api/middleware/conditional_get.go
+ */
+
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func jsonHandler(body string, header map[string]string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		for key, value := range header {
+			w.Header().Set(key, value)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(body))
+	}
+}
+
+func serveConditional(handler http.Handler, method string, header map[string]string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, "/items/1", nil)
+	for key, value := range header {
+		req.Header.Set(key, value)
+	}
+	rec := httptest.NewRecorder()
+	ConditionalGET(handler).ServeHTTP(rec, req)
+	return rec
+}
+
+func TestConditionalGETIfNoneMatch(t *testing.T) {
+	handler := jsonHandler(`{"status":0,"msg":"ok","data":{"id":1}}`, nil)
+
+	first := serveConditional(handler, http.MethodGet, nil)
+	require.Equal(t, http.StatusOK, first.Code)
+	etag := first.Header().Get("ETag")
+	require.NotEmpty(t, etag)
+	assert.Equal(t, "private, no-cache", first.Header().Get("Cache-Control"))
+	assert.Equal(t, `{"status":0,"msg":"ok","data":{"id":1}}`, first.Body.String())
+
+	second := serveConditional(handler, http.MethodGet, map[string]string{"If-None-Match": `"other", W/` + etag})
+	assert.Equal(t, http.StatusNotModified, second.Code)
+	assert.Empty(t, second.Body.String())
+	assert.Equal(t, etag, second.Header().Get("ETag"))
+	assert.Empty(t, second.Header().Get("Content-Type"))
+
+	changed := serveConditional(jsonHandler(`{"status":0,"msg":"ok","data":{"id":2}}`, nil), http.MethodGet, map[string]string{"If-None-Match": etag})
+	assert.Equal(t, http.StatusOK, changed.Code, "内容变化后ETag不再匹配")
+	assert.NotEqual(t, etag, changed.Header().Get("ETag"))
+}
+
+func TestConditionalGETKeepsRowVersion(t *testing.T) {
+	handler := jsonHandler(`{"status":0,"msg":"ok","data":{"row_version":3}}`, map[string]string{"ETag": `"3"`})
+
+	rec := serveConditional(handler, http.MethodGet, nil)
+	etag := rec.Header().Get("ETag")
+	assert.Regexp(t, `^"3-[0-9a-f]{16}"$`, etag, "保留版本号作为前缀，供If-Match校验")
+
+	rec = serveConditional(handler, http.MethodGet, map[string]string{"If-None-Match": `"3"`})
+	assert.Equal(t, http.StatusOK, rec.Code, "只有版本号的旧ETag不匹配")
+
+	rec = serveConditional(handler, http.MethodPut, map[string]string{"If-None-Match": etag})
+	assert.Equal(t, http.StatusOK, rec.Code, "只处理GET请求")
+	assert.Equal(t, `"3"`, rec.Header().Get("ETag"))
+}
+
+func TestConditionalGETIfModifiedSince(t *testing.T) {
+	updatedAt := time.Date(2026, 10, 16, 8, 0, 0, 0, time.UTC)
+	handler := jsonHandler(`{"status":0,"msg":"ok","data":{}}`, map[string]string{"Last-Modified": updatedAt.Format(http.TimeFormat)})
+
+	rec := serveConditional(handler, http.MethodGet, map[string]string{"If-Modified-Since": updatedAt.Format(http.TimeFormat)})
+	assert.Equal(t, http.StatusNotModified, rec.Code)
+
+	rec = serveConditional(handler, http.MethodGet, map[string]string{"If-Modified-Since": updatedAt.Add(-time.Minute).Format(http.TimeFormat)})
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	rec = serveConditional(handler, http.MethodGet, map[string]string{
+		"If-Modified-Since": updatedAt.Format(http.TimeFormat),
+		"If-None-Match":     `"stale"`,
+	})
+	assert.Equal(t, http.StatusOK, rec.Code, "If-None-Match优先于If-Modified-Since")
+
+	rec = serveConditional(jsonHandler(`{"status":0,"msg":"ok","data":{}}`, nil), http.MethodGet, map[string]string{"If-Modified-Since": updatedAt.Format(http.TimeFormat)})
+	assert.Equal(t, http.StatusOK, rec.Code, "处理器未设置Last-Modified时不按时间判断")
+}
+
+func TestConditionalGETPassthrough(t *testing.T) {
+	errorHandler := jsonHandler(`{"status":404,"msg":"不存在"}`, nil)
+	rec := serveConditional(errorHandler, http.MethodGet, map[string]string{"If-None-Match": "*"})
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Empty(t, rec.Header().Get("ETag"), "错误响应不生成ETag")
+	assert.Equal(t, `{"status":404,"msg":"不存在"}`, rec.Body.String())
+
+	csvHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+		_, _ = w.Write([]byte("id\n1\n"))
+		http.NewResponseController(w).Flush()
+		_, _ = w.Write([]byte("2\n"))
+	})
+	rec = serveConditional(csvHandler, http.MethodGet, map[string]string{"If-None-Match": "*"})
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Empty(t, rec.Header().Get("ETag"))
+	assert.Equal(t, "id\n1\n2\n", rec.Body.String())
+	assert.True(t, rec.Flushed, "流式响应的Flush传递到底层ResponseWriter")
+
+	statusHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusPartialContent)
+		_, _ = w.Write([]byte(`{"status":0}`))
+	})
+	rec = serveConditional(statusHandler, http.MethodGet, map[string]string{"If-None-Match": "*"})
+	assert.Equal(t, http.StatusPartialContent, rec.Code)
+	assert.Empty(t, rec.Header().Get("ETag"))
+}
@@ -0,0 +1,163 @@
+/*
+ * @module api/middleware/idempotency
+ * @description 幂等请求中间件，支持Idempotency-Key头，客户端重试时重放首次响应，避免重复创建任务或重复推送数据
+ * @architecture 中间件模式 - HTTP请求拦截和响应缓存
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow 读取Key和请求体 -> 登记/查找记录 -> 重放响应 或 执行处理器并保存响应
+ * @rules 未携带Idempotency-Key时不做处理；服务端错误（HTTP 5xx或业务状态码>=500）不保存，允许使用相同Key重试
+ * @dependencies datahub-service/service/idempotency, net/http
+ * @refs service/idempotency/idempotency_service.go, api/routes.go
+ */
+
+package middleware
+
+import (
+	"bytes"
+	"datahub-service/service/idempotency"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+)
+
+const (
+	// IdempotencyKeyHeader 幂等Key请求头
+	IdempotencyKeyHeader = "Idempotency-Key"
+	// IdempotentReplayedHeader 标记响应为重放的响应头
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+	// maxIdempotentBodySize 参与指纹计算的请求体上限
+	maxIdempotentBodySize = 10 << 20
+)
+
+// IdempotencyMiddleware 幂等请求中间件
+type IdempotencyMiddleware struct {
+	service *idempotency.Service
+}
+
+// NewIdempotencyMiddleware 创建幂等请求中间件实例
+func NewIdempotencyMiddleware(service *idempotency.Service) *IdempotencyMiddleware {
+	return &IdempotencyMiddleware{service: service}
+}
+
+// Middleware 幂等请求处理函数
+func (m *IdempotencyMiddleware) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := strings.TrimSpace(r.Header.Get(IdempotencyKeyHeader))
+		if key == "" || m.service == nil || r.Method == http.MethodGet || r.Method == http.MethodHead {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if len(key) > idempotency.MaxKeyLength {
+			respondJSONError(w, r, http.StatusBadRequest, "Idempotency-Key过长")
+			return
+		}
+
+		body, err := io.ReadAll(io.LimitReader(r.Body, maxIdempotentBodySize+1))
+		if err != nil {
+			respondJSONError(w, r, http.StatusBadRequest, "读取请求体失败")
+			return
+		}
+		if len(body) > maxIdempotentBodySize {
+			respondJSONError(w, r, http.StatusRequestEntityTooLarge, "请求体过大，不支持幂等处理")
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		scope := r.Method + " " + r.URL.Path
+		record, replay, err := m.service.Begin(key, idempotencyPrincipal(r), scope, idempotency.Fingerprint(scope, body))
+		switch {
+		case errors.Is(err, idempotency.ErrKeyReused):
+			respondJSONError(w, r, http.StatusUnprocessableEntity, err.Error())
+			return
+		case errors.Is(err, idempotency.ErrRequestInProgress):
+			respondJSONError(w, r, http.StatusConflict, err.Error())
+			return
+		case err != nil:
+			slog.Error("登记幂等请求失败", "key", key, "scope", scope, "error", err)
+			respondJSONError(w, r, http.StatusInternalServerError, "登记幂等请求失败")
+			return
+		}
+
+		if replay {
+			if record.ContentType != "" {
+				w.Header().Set("Content-Type", record.ContentType)
+			}
+			w.Header().Set(IdempotentReplayedHeader, "true")
+			w.WriteHeader(record.ResponseStatus)
+			w.Write(record.ResponseBody)
+			return
+		}
+
+		recorder := &responseRecorder{ResponseWriter: w, status: http.StatusOK}
+		completed := false
+		defer func() {
+			if !completed {
+				if abandonErr := m.service.Abandon(record.ID); abandonErr != nil {
+					slog.Error("释放幂等记录失败", "record_id", record.ID, "error", abandonErr)
+				}
+			}
+		}()
+
+		next.ServeHTTP(recorder, r)
+
+		if isServerError(recorder.status, recorder.body.Bytes()) {
+			return
+		}
+		if err := m.service.Complete(record.ID, recorder.status, recorder.Header().Get("Content-Type"), recorder.body.Bytes()); err != nil {
+			slog.Error("保存幂等响应失败", "record_id", record.ID, "error", err)
+			return
+		}
+		completed = true
+	})
+}
+
+// idempotencyPrincipal 获取调用方标识，幂等Key在调用方范围内唯一
+func idempotencyPrincipal(r *http.Request) string {
+	if userInfo, ok := GetUserInfoFromContext(r.Context()); ok && userInfo.Username != "" {
+		return "user:" + userInfo.Username
+	}
+	if apiKey, ok := GetApiKeyFromContext(r.Context()); ok {
+		return "api_key:" + apiKey.ID
+	}
+	return "anonymous"
+}
+
+// isServerError 判断响应是否为服务端错误，控制器统一返回HTTP 200时以业务状态码为准
+func isServerError(status int, body []byte) bool {
+	if status >= http.StatusInternalServerError {
+		return true
+	}
+	var payload struct {
+		Status int `json:"status"`
+	}
+	if json.Unmarshal(body, &payload) == nil && payload.Status >= http.StatusInternalServerError {
+		return true
+	}
+	return false
+}
+
+// responseRecorder 记录响应状态码和响应体，同时写入客户端
+type responseRecorder struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+	body        bytes.Buffer
+}
+
+// WriteHeader 记录状态码
+func (rr *responseRecorder) WriteHeader(status int) {
+	if !rr.wroteHeader {
+		rr.status = status
+		rr.wroteHeader = true
+	}
+	rr.ResponseWriter.WriteHeader(status)
+}
+
+// Write 记录响应体
+func (rr *responseRecorder) Write(b []byte) (int, error) {
+	rr.wroteHeader = true
+	rr.body.Write(b)
+	return rr.ResponseWriter.Write(b)
+}
@@ -1,12 +1,12 @@
 /*
  * @module api/middleware/postgrest_auth
- * @description PostgREST Token鉴权中间件，验证JWT Token的有效性
+ * @description PostgREST Token鉴权中间件，验证JWT Token的有效性，并按OIDC声明解析操作人身份
  * @architecture 中间件模式 - HTTP请求拦截和验证
  * @documentReference deploy/local_dev/datahub/docker-compose/db/postgrest.sql
- * @stateFlow Token提取 -> Token验证 -> 上下文注入 -> 下一个处理器
- * @rules 统一鉴权、安全验证、错误处理
- * @dependencies net/http, encoding/json, strings, context
- * @refs client/postgrest_client.go, api/routes.go
+ * @stateFlow Token提取 -> Token验证 -> 声明解析身份 -> 上下文注入(用户信息、认证身份) -> 下一个处理器
+ * @rules 统一鉴权、安全验证、错误处理；审计字段和系统日志的操作人统一取自认证身份
+ * @dependencies net/http, encoding/json, strings, context, datahub-service/service/identity
+ * @refs client/postgrest_client.go, api/routes.go, service/identity
  */
 
 package middleware
@@ -14,9 +14,12 @@ package middleware
 import (
 	"bytes"
 	"context"
+	"datahub-service/service/identity"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
 	"os"
 	"strings"
@@ -50,6 +53,9 @@ type TokenVerificationResponse struct {
 // UserInfo 用户信息结构
 type UserInfo struct {
 	Username    string    `json:"username"`
+	Subject     string    `json:"subject,omitempty"` // 身份提供方中的用户唯一标识
+	DisplayName string    `json:"display_name,omitempty"`
+	Email       string    `json:"email,omitempty"`
 	Roles       []string  `json:"roles"`
 	Permissions []string  `json:"permissions"`
 	ExpiresAt   time.Time `json:"expires_at"`
@@ -65,6 +71,8 @@ type PostgRESTAuthMiddleware struct {
 	cacheTTL   time.Duration
 	// 白名单路径（不需要鉴权）
 	whitelistPaths []string
+	// 从Token声明解析身份时使用的声明名称
+	claimConfig identity.ClaimConfig
 }
 
 // cacheEntry 缓存条目
@@ -88,11 +96,14 @@ func NewPostgRESTAuthMiddleware() *PostgRESTAuthMiddleware {
 		cache:    make(map[string]*cacheEntry),
 		cacheTTL: 5 * time.Minute, // 缓存5分钟
 		whitelistPaths: []string{
-			"/health",       // 健康检查
-			"/ready",        // 就绪检查
-			"/swagger",      // Swagger文档
-			"/api/v1/share", // 数据访问代理API（有自己的鉴权机制）
+			"/health",        // 健康检查
+			"/ready",         // 就绪检查
+			"/swagger",       // Swagger文档
+			"/api/versions",  // API版本说明
+			"/api/v1/share",  // 数据访问代理API（有自己的鉴权机制）
+			"/api/v1/ingest", // 机器调用方数据推送（使用ApiKey认证）
 		},
+		claimConfig: identity.ClaimConfigFromEnv(),
 	}
 }
 
@@ -101,11 +112,12 @@ func (m *PostgRESTAuthMiddleware) AddWhitelistPath(path string) {
 	m.whitelistPaths = append(m.whitelistPaths, path)
 }
 
-// IsWhitelistPath 检查路径是否在白名单中
+// IsWhitelistPath 检查路径是否在白名单中，白名单项匹配路径本身及其下级路径，
+// 按路径段匹配，避免/api/v1/share误放行/api/v1/sharing等同前缀的管理接口
 func (m *PostgRESTAuthMiddleware) IsWhitelistPath(path string) bool {
 	for _, whitelistPath := range m.whitelistPaths {
-		// 支持前缀匹配
-		if strings.HasPrefix(path, whitelistPath) {
+		prefix := strings.TrimSuffix(whitelistPath, "/")
+		if path == prefix || strings.HasPrefix(path, prefix+"/") {
 			return true
 		}
 	}
@@ -144,9 +156,7 @@ func (m *PostgRESTAuthMiddleware) Middleware(next http.Handler) http.Handler {
 		// 先检查缓存
 		if userInfo := m.getFromCache(token); userInfo != nil {
 			// 缓存命中，直接使用
-			ctx := context.WithValue(r.Context(), TokenKey, token)
-			ctx = context.WithValue(ctx, UserInfoKey, userInfo)
-			next.ServeHTTP(w, r.WithContext(ctx))
+			next.ServeHTTP(w, withUser(r, token, userInfo))
 			return
 		}
 
@@ -160,13 +170,23 @@ func (m *PostgRESTAuthMiddleware) Middleware(next http.Handler) http.Handler {
 		// 保存到缓存
 		m.saveToCache(token, userInfo)
 
-		// 将Token和用户信息注入到上下文中
-		ctx := context.WithValue(r.Context(), TokenKey, token)
-		ctx = context.WithValue(ctx, UserInfoKey, userInfo)
+		// 将Token、用户信息和认证身份注入到上下文中，调用下一个处理器
+		next.ServeHTTP(w, withUser(r, token, userInfo))
+	})
+}
 
-		// 调用下一个处理器
-		next.ServeHTTP(w, r.WithContext(ctx))
+// withUser 将Token、用户信息和认证身份写入请求context
+func withUser(r *http.Request, token string, userInfo *UserInfo) *http.Request {
+	ctx := context.WithValue(r.Context(), TokenKey, token)
+	ctx = context.WithValue(ctx, UserInfoKey, userInfo)
+	ctx = identity.WithIdentity(ctx, identity.Identity{
+		Username:    userInfo.Username,
+		Subject:     userInfo.Subject,
+		DisplayName: userInfo.DisplayName,
+		Email:       userInfo.Email,
+		ClientIP:    requestClientIP(r),
 	})
+	return r.WithContext(ctx)
 }
 
 // verifyToken 调用PostgREST验证Token
@@ -222,9 +242,17 @@ func (m *PostgRESTAuthMiddleware) verifyToken(token string) (*UserInfo, error) {
 		return nil, fmt.Errorf("Token无效: %s", verifyResp.Message)
 	}
 
-	// 构建用户信息
+	// 构建用户信息，Token为OIDC/JWT时按声明补充用户标识、姓名和邮箱
+	claims, err := identity.DecodeClaims(token)
+	if err != nil && !errors.Is(err, identity.ErrNotJWT) {
+		slog.Warn("解析Token声明失败，使用校验结果中的用户名", "error", err)
+	}
+	id := m.claimConfig.Resolve(verifyResp.Username, claims)
 	userInfo := &UserInfo{
-		Username:    verifyResp.Username,
+		Username:    id.Username,
+		Subject:     id.Subject,
+		DisplayName: id.DisplayName,
+		Email:       id.Email,
 		Roles:       verifyResp.Roles,
 		Permissions: verifyResp.Permissions,
 	}
@@ -0,0 +1,49 @@
+/*
+ * @module api/middleware/postgrest_auth_test
+ * @description PostgREST认证中间件白名单测试，覆盖按路径段匹配，同前缀的管理接口（如/api/v1/sharing）不被放行
+ * @architecture 测试层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow 构造中间件 -> 请求白名单和非白名单路径 -> 验证是否跳过鉴权
+ * @rules 使用httptest，不依赖PostgREST
+ * @dependencies net/http/httptest, stretchr/testify
+ * @refs api/middleware/postgrest_auth.go
+ */
+
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsWhitelistPath(t *testing.T) {
+	m := NewPostgRESTAuthMiddleware()
+
+	for _, path := range []string{"/health", "/swagger/index.html", "/api/v1/share", "/api/v1/share/app/users", "/api/v1/ingest/app/orders"} {
+		assert.True(t, m.IsWhitelistPath(path), path)
+	}
+	for _, path := range []string{"/api/v1/sharing/api-keys", "/api/v1/sharing/api-applications", "/api/v1/shares", "/healthz", "/api/v1/ingestion"} {
+		assert.False(t, m.IsWhitelistPath(path), path)
+	}
+
+	m.AddWhitelistPath("/metrics/")
+	assert.True(t, m.IsWhitelistPath("/metrics"))
+	assert.True(t, m.IsWhitelistPath("/metrics/sync"))
+}
+
+func TestMiddlewareRequiresTokenForVersionedSharingManagement(t *testing.T) {
+	m := NewPostgRESTAuthMiddleware()
+	handler := m.Middleware(writeBody("ok"))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/sharing/api-keys", nil))
+	assert.NotContains(t, rec.Body.String(), "ok", "没有Token的管理接口请求不能放行")
+	assert.Contains(t, rec.Body.String(), "Authorization")
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/share/app/users", nil))
+	assert.Equal(t, "ok", rec.Body.String())
+}
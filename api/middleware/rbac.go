@@ -0,0 +1,175 @@
+/*
+ * @module api/middleware/rbac
+ * @description 基于角色的访问控制中间件，按资源和操作校验当前用户权限
+ * @architecture 中间件模式 - HTTP请求拦截和鉴权
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow 用户信息 -> 有效角色 -> 资源操作判定 -> 放行(写入访问主体)/拒绝
+ * @rules 必须在PostgREST认证中间件之后使用，GET为read、DELETE为delete、任务控制类POST为execute、其余为write；
+ *        POST .../batch按请求体中的action确定操作类型，delete为delete、任务控制类动作为execute，POST .../batch-delete为delete，
+ *        避免只有write权限的角色批量删除或执行；
+ *        放行时将访问主体写入context，服务层据此按对象授权过滤目录
+ * @dependencies datahub-service/service/rbac, net/http
+ * @refs api/middleware/postgrest_auth.go, service/rbac/rbac_service.go
+ */
+
+package middleware
+
+import (
+	"bytes"
+	"datahub-service/service/models"
+	"datahub-service/service/rbac"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/render"
+)
+
+// executeSegments 视为执行操作的路径末段（任务控制、测试类接口）
+var executeSegments = map[string]bool{
+	"start":            true,
+	"stop":             true,
+	"cancel":           true,
+	"retry":            true,
+	"activate":         true,
+	"pause":            true,
+	"resume":           true,
+	"execute":          true,
+	"publish":          true,
+	"checks":           true,
+	"check":            true,
+	"health-check-all": true,
+	"verify":           true,
+	"rotate":           true,
+	"decrypt":          true,
+	"refresh":          true,
+	"send":             true,
+}
+
+// deleteSegments 视为删除操作的路径末段（POST方式的批量删除）
+var deleteSegments = map[string]bool{
+	"batch-delete": true,
+}
+
+// batchSegment 批量操作接口的路径末段，操作类型取决于请求体中的action
+const batchSegment = "batch"
+
+// batchExecuteActions 批量操作中视为执行、且没有同名单对象路径末段的动作
+var batchExecuteActions = map[string]bool{
+	"run": true, // 同步任务按筛选条件启动执行
+}
+
+// RBACMiddleware 访问控制中间件
+type RBACMiddleware struct {
+	rbacService *rbac.RBACService
+}
+
+// NewRBACMiddleware 创建访问控制中间件实例
+func NewRBACMiddleware(rbacService *rbac.RBACService) *RBACMiddleware {
+	return &RBACMiddleware{rbacService: rbacService}
+}
+
+// RequireResource 创建按请求方法推断操作类型的资源鉴权中间件
+func (m *RBACMiddleware) RequireResource(resource string) func(http.Handler) http.Handler {
+	return m.require(resource, "")
+}
+
+// RequireAction 创建指定资源和操作的鉴权中间件
+func (m *RBACMiddleware) RequireAction(resource, action string) func(http.Handler) http.Handler {
+	return m.require(resource, action)
+}
+
+func (m *RBACMiddleware) require(resource, action string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if m.rbacService == nil || !m.rbacService.IsEnabled() {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			userInfo, ok := GetUserInfoFromContext(r.Context())
+			if !ok {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusUnauthorized)
+				render.JSON(w, r, map[string]interface{}{
+					"status":  http.StatusUnauthorized,
+					"message": "未找到用户信息",
+					"error":   "Unauthorized",
+				})
+				return
+			}
+
+			requiredAction := action
+			if requiredAction == "" {
+				requiredAction = ActionForRequest(r)
+			}
+
+			subject := m.rbacService.NewSubject(userInfo.Username, userInfo.Roles, userInfo.Permissions)
+			if !m.rbacService.HasPermission(subject.Roles, userInfo.Permissions, resource, requiredAction) {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusForbidden)
+				render.JSON(w, r, map[string]interface{}{
+					"status":  http.StatusForbidden,
+					"message": fmt.Sprintf("缺少所需权限: %s:%s", resource, requiredAction),
+					"error":   "Forbidden",
+				})
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(rbac.WithSubject(r.Context(), subject)))
+		})
+	}
+}
+
+// ActionForRequest 根据请求方法和路径推断操作类型
+func ActionForRequest(r *http.Request) string {
+	switch r.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return models.ActionRead
+	case http.MethodDelete:
+		return models.ActionDelete
+	}
+
+	path := strings.TrimSuffix(r.URL.Path, "/")
+	lastSegment := path[strings.LastIndex(path, "/")+1:]
+	if lastSegment == batchSegment {
+		return batchAction(r)
+	}
+	if deleteSegments[lastSegment] {
+		return models.ActionDelete
+	}
+	if executeSegments[lastSegment] || strings.Contains(path, "/test") {
+		return models.ActionExecute
+	}
+	return models.ActionWrite
+}
+
+// batchAction 按批量操作请求体中的action推断操作类型，与单个对象的同名操作一致，
+// 读取后恢复请求体供处理器解析；请求体无法解析时按write校验，由处理器返回参数错误
+func batchAction(r *http.Request) string {
+	if r.Body == nil {
+		return models.ActionWrite
+	}
+	body, err := io.ReadAll(r.Body)
+	r.Body.Close()
+	r.Body = io.NopCloser(bytes.NewReader(body))
+	if err != nil {
+		return models.ActionWrite
+	}
+
+	var payload struct {
+		Action string `json:"action"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return models.ActionWrite
+	}
+	switch {
+	case payload.Action == models.ActionDelete:
+		return models.ActionDelete
+	case executeSegments[payload.Action] || batchExecuteActions[payload.Action]:
+		return models.ActionExecute
+	}
+	return models.ActionWrite
+}
@@ -0,0 +1,109 @@
+/*
+ * @module api/middleware/rbac_test
+ * @description 访问控制中间件测试，覆盖批量操作接口按请求体中的action校验权限，以及同步任务的批量删除和按筛选条件批量执行
+ * @architecture 测试层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow 构造带用户信息的请求 -> 经过鉴权中间件 -> 验证放行或403，放行后处理器仍能读取请求体
+ * @rules 使用SQLite内存数据库初始化访问控制服务，用户权限通过Token中的permissions指定
+ * @dependencies net/http/httptest, gorm.io/driver/sqlite, stretchr/testify
+ * @refs api/middleware/rbac.go
+ */
+
+package middleware
+
+import (
+	"context"
+	"datahub-service/service/models"
+	"datahub-service/service/rbac"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func setupRBACMiddleware(t *testing.T) *RBACMiddleware {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&models.RolePermission{}, &models.UserRoleAssignment{}))
+	t.Setenv("RBAC_ENABLED", "true")
+	return NewRBACMiddleware(rbac.NewRBACService(db))
+}
+
+// serveAs 以指定Token权限发送POST请求，返回状态码
+func serveAs(handler http.Handler, path, body string, permissions ...string) int {
+	req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+	req = req.WithContext(context.WithValue(req.Context(), UserInfoKey, &UserInfo{Username: "bob", Permissions: permissions}))
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	return rec.Code
+}
+
+func TestBatchOperationRequiresActionPermission(t *testing.T) {
+	m := setupRBACMiddleware(t)
+	var received string
+	handler := m.RequireResource(rbac.ResourceQualityRule)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		received = string(data)
+	}))
+
+	deleteBody := `{"ids":["r1","r2"],"action":"delete"}`
+	code := serveAs(handler, "/api/v1/data-quality/rules/batch", deleteBody, "quality_rule:write")
+	assert.Equal(t, http.StatusForbidden, code, "只有write权限不能批量删除")
+
+	code = serveAs(handler, "/api/v1/data-quality/rules/batch", `{"ids":["r1"],"action":"disable"}`, "quality_rule:write")
+	assert.Equal(t, http.StatusOK, code, "批量启用/停用按write校验")
+
+	received = ""
+	code = serveAs(handler, "/api/v1/data-quality/rules/batch", deleteBody, "quality_rule:delete")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, deleteBody, received, "鉴权读取后请求体仍可被处理器解析")
+
+	code = serveAs(handler, "/api/v1/data-quality/rules/batch", `not json`, "quality_rule:write")
+	assert.Equal(t, http.StatusOK, code, "请求体无法解析时按write校验，由处理器返回参数错误")
+
+	// 脱敏和清洗规则的批量删除同样需要delete权限
+	for resource, path := range map[string]string{
+		rbac.ResourceMaskingRule:   "/api/v1/data-quality/masking-rules/batch",
+		rbac.ResourceCleansingRule: "/api/v1/data-quality/cleansing-rules/batch",
+	} {
+		handler := m.RequireResource(resource)(writeBody("ok"))
+		assert.Equal(t, http.StatusForbidden, serveAs(handler, path, deleteBody, resource+":write"), resource)
+		assert.Equal(t, http.StatusOK, serveAs(handler, path, deleteBody, resource+":delete"), resource)
+	}
+}
+
+func TestSyncTaskBatchRequiresActionPermission(t *testing.T) {
+	m := setupRBACMiddleware(t)
+	handler := m.RequireResource(rbac.ResourceSyncTask)(writeBody("ok"))
+	filter := `"filter":{"status":"active"}`
+
+	cases := []struct {
+		action   string
+		required string
+	}{
+		{"delete", "sync_task:delete"},
+		{"run", "sync_task:execute"},
+		{"activate", "sync_task:execute"},
+		{"pause", "sync_task:execute"},
+		{"resume", "sync_task:execute"},
+	}
+	for _, c := range cases {
+		body := `{"action":"` + c.action + `",` + filter + `}`
+		assert.Equal(t, http.StatusForbidden, serveAs(handler, "/api/v1/sync/tasks/batch", body, "sync_task:write"), c.action)
+		assert.Equal(t, http.StatusOK, serveAs(handler, "/api/v1/sync/tasks/batch", body, c.required), c.action)
+	}
+
+	// 按任务ID批量删除同样需要delete权限
+	body := `{"task_ids":["t1","t2"]}`
+	assert.Equal(t, http.StatusForbidden, serveAs(handler, "/api/v1/sync/tasks/batch-delete", body, "sync_task:write"))
+	assert.Equal(t, http.StatusOK, serveAs(handler, "/api/v1/sync/tasks/batch-delete", body, "sync_task:delete"))
+}
@@ -0,0 +1,103 @@
+/*
+ * @module api/middleware/request_id
+ * @description 请求ID中间件，生成或沿用调用方传入的X-Request-ID，写入context、响应头和统一响应体，便于从用户反馈的响应追溯日志和事件
+ * @architecture 中间件模式 - HTTP请求拦截
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow 读取X-Request-ID -> 校验或生成 -> 写入context和响应头 -> 统一响应体首次写出时补充request_id字段
+ * @rules 调用方传入的ID只接受字母数字和.-_:，长度不超过64，否则重新生成；只改写Content-Type为JSON且以status字段开头的统一响应体，其他响应原样透传
+ * @dependencies datahub-service/logger, github.com/go-chi/chi/v5/middleware
+ * @refs logger/context.go, api/controllers/response.go, api/routes.go
+ */
+
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"datahub-service/logger"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	chimiddleware "github.com/go-chi/chi/v5/middleware"
+	"github.com/google/uuid"
+)
+
+// maxRequestIDLength 调用方传入的请求ID长度上限
+const maxRequestIDLength = 64
+
+// apiResponsePrefix 统一响应体（controllers.APIResponse）序列化后的开头
+var apiResponsePrefix = []byte(`{"status":`)
+
+// RequestID 请求ID中间件，替代chi自带的RequestID，同时写入chi的请求ID以便访问日志输出
+func RequestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestID := r.Header.Get(logger.RequestIDHeader)
+		if !validRequestID(requestID) {
+			requestID = uuid.New().String()
+		}
+
+		ctx := logger.WithRequestID(r.Context(), requestID)
+		ctx = context.WithValue(ctx, chimiddleware.RequestIDKey, requestID)
+		w.Header().Set(logger.RequestIDHeader, requestID)
+
+		next.ServeHTTP(&requestIDWriter{ResponseWriter: w, requestID: requestID}, r.WithContext(ctx))
+	})
+}
+
+// validRequestID 检查调用方传入的请求ID是否可直接使用
+func validRequestID(requestID string) bool {
+	if requestID == "" || len(requestID) > maxRequestIDLength {
+		return false
+	}
+	for _, c := range requestID {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+		case strings.ContainsRune(".-_:", c):
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// requestIDWriter 在统一响应体中补充request_id字段
+type requestIDWriter struct {
+	http.ResponseWriter
+	requestID string
+	written   bool
+}
+
+// Write 首次写出统一响应体时在开头插入request_id
+func (rw *requestIDWriter) Write(b []byte) (int, error) {
+	if rw.written {
+		return rw.ResponseWriter.Write(b)
+	}
+	rw.written = true
+
+	if !bytes.HasPrefix(b, apiResponsePrefix) || !strings.HasPrefix(rw.Header().Get("Content-Type"), "application/json") {
+		return rw.ResponseWriter.Write(b)
+	}
+	field, _ := json.Marshal(rw.requestID)
+	body := make([]byte, 0, len(b)+len(field)+16)
+	body = append(body, `{"request_id":`...)
+	body = append(body, field...)
+	body = append(body, ',')
+	body = append(body, b[1:]...)
+	if _, err := rw.ResponseWriter.Write(body); err != nil {
+		return 0, err
+	}
+	return len(b), nil
+}
+
+// Flush 支持流式响应
+func (rw *requestIDWriter) Flush() {
+	if flusher, ok := rw.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Unwrap 供http.ResponseController访问底层ResponseWriter
+func (rw *requestIDWriter) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
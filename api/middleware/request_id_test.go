@@ -0,0 +1,85 @@
+/*
+ * @module api/middleware/request_id_test
+ * @description 请求ID中间件测试，覆盖ID生成与沿用、响应头、统一响应体补充request_id以及非统一响应透传
+ * @architecture 测试层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow 构造请求 -> 经过中间件 -> 验证context、响应头和响应体
+ * @rules 使用httptest，不依赖外部服务
+ * @dependencies net/http/httptest, stretchr/testify
+ * @refs api/middleware/request_id.go
+ */
+
+package middleware
+
+import (
+	"datahub-service/logger"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	chimiddleware "github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testAPIResponse struct {
+	RequestID string `json:"request_id,omitempty"`
+	Status    int    `json:"status"`
+	Msg       string `json:"msg"`
+}
+
+func serveWithRequestID(handler http.HandlerFunc, requestID string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/api/test", nil)
+	if requestID != "" {
+		req.Header.Set(logger.RequestIDHeader, requestID)
+	}
+	rec := httptest.NewRecorder()
+	RequestID(handler).ServeHTTP(rec, req)
+	return rec
+}
+
+func TestRequestIDPropagation(t *testing.T) {
+	var ctxRequestID, chiRequestID string
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		ctxRequestID = logger.RequestIDFromContext(r.Context())
+		chiRequestID = chimiddleware.GetReqID(r.Context())
+		render.JSON(w, r, testAPIResponse{Status: 0, Msg: "操作成功"})
+	}
+
+	rec := serveWithRequestID(handler, "client-req.1:a_b")
+	assert.Equal(t, "client-req.1:a_b", rec.Header().Get(logger.RequestIDHeader))
+	assert.Equal(t, "client-req.1:a_b", ctxRequestID)
+	assert.Equal(t, ctxRequestID, chiRequestID)
+
+	var body testAPIResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	assert.Equal(t, "client-req.1:a_b", body.RequestID)
+	assert.Equal(t, "操作成功", body.Msg)
+
+	// 非法或过长的请求ID重新生成
+	for _, invalid := range []string{"", "bad id", "<script>", string(make([]byte, 65))} {
+		rec = serveWithRequestID(handler, invalid)
+		generated := rec.Header().Get(logger.RequestIDHeader)
+		assert.Len(t, generated, 36, invalid)
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+		assert.Equal(t, generated, body.RequestID)
+	}
+}
+
+func TestRequestIDPassThrough(t *testing.T) {
+	// 非统一响应结构的JSON原样返回
+	rec := serveWithRequestID(func(w http.ResponseWriter, r *http.Request) {
+		render.JSON(w, r, []string{"a"})
+	}, "req-1")
+	assert.JSONEq(t, `["a"]`, rec.Body.String())
+
+	// 非JSON响应原样返回
+	rec = serveWithRequestID(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		w.Write([]byte(`{"status":0}`))
+	}, "req-1")
+	assert.Equal(t, `{"status":0}`, rec.Body.String())
+	assert.Equal(t, "req-1", rec.Header().Get(logger.RequestIDHeader))
+}
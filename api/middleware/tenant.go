@@ -0,0 +1,89 @@
+/*
+ * @module api/middleware/tenant
+ * @description 租户中间件，按X-Tenant-ID请求头和用户成员关系解析当前租户并写入context，服务层据此隔离数据
+ * @architecture 中间件模式 - HTTP请求拦截
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow 用户信息 + X-Tenant-ID -> 租户服务解析 -> tenant.WithTenant写入context -> 放行/拒绝
+ * @rules 必须在PostgREST认证中间件之后使用；未启用多租户或无用户信息（白名单接口）时直接放行；管理员不指定租户时不限定租户
+ * @dependencies datahub-service/service/tenant, datahub-service/service/rbac
+ * @refs service/tenant/tenant_service.go, service/tenant/gorm_callback.go
+ */
+
+package middleware
+
+import (
+	"datahub-service/service/models"
+	"datahub-service/service/rbac"
+	"datahub-service/service/tenant"
+	"errors"
+	"log/slog"
+	"net/http"
+	"slices"
+
+	"github.com/go-chi/render"
+)
+
+// TenantHeader 指定当前租户的请求头，值为租户ID或编码
+const TenantHeader = "X-Tenant-ID"
+
+// TenantMiddleware 租户中间件
+type TenantMiddleware struct {
+	tenantService *tenant.Service
+	rbacService   *rbac.RBACService
+}
+
+// NewTenantMiddleware 创建租户中间件实例
+func NewTenantMiddleware(tenantService *tenant.Service, rbacService *rbac.RBACService) *TenantMiddleware {
+	return &TenantMiddleware{tenantService: tenantService, rbacService: rbacService}
+}
+
+// Middleware 解析当前租户并写入context
+func (m *TenantMiddleware) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if m.tenantService == nil || !m.tenantService.IsEnabled() {
+			next.ServeHTTP(w, r)
+			return
+		}
+		userInfo, ok := GetUserInfoFromContext(r.Context())
+		if !ok {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		info, scoped, err := m.tenantService.ResolveTenant(userInfo.Username, m.isAdmin(userInfo), r.Header.Get(TenantHeader))
+		if err != nil {
+			status := http.StatusForbidden
+			switch {
+			case errors.Is(err, tenant.ErrTenantNotFound):
+				status = http.StatusNotFound
+			case !errors.Is(err, tenant.ErrTenantForbidden) && !errors.Is(err, tenant.ErrTenantDisabled):
+				slog.ErrorContext(r.Context(), "解析租户失败", "username", userInfo.Username, "error", err)
+				status = http.StatusInternalServerError
+			}
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(status)
+			render.JSON(w, r, map[string]interface{}{
+				"status":  status,
+				"message": err.Error(),
+				"error":   http.StatusText(status),
+			})
+			return
+		}
+		if !scoped {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		w.Header().Set(TenantHeader, info.ID)
+		next.ServeHTTP(w, r.WithContext(tenant.WithTenant(r.Context(), info)))
+	})
+}
+
+// isAdmin 判断用户是否为管理员，管理员可跨租户访问
+func (m *TenantMiddleware) isAdmin(userInfo *UserInfo) bool {
+	roles := userInfo.Roles
+	if m.rbacService != nil && m.rbacService.IsEnabled() {
+		roles = m.rbacService.ResolveRoles(userInfo.Username, userInfo.Roles)
+	}
+	return slices.Contains(roles, models.RoleAdmin)
+}
@@ -0,0 +1,58 @@
+/*
+ * @module api/middleware/tracing
+ * @description 链路追踪中间件，为每个HTTP请求创建服务端Span，继承调用方或Dapr sidecar传入的traceparent
+ * @architecture 中间件模式 - HTTP请求拦截
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow 解析traceparent -> 创建服务端Span -> 执行处理器 -> 按路由模板命名Span并记录状态码
+ * @rules 未启用链路追踪时直接放行；Span名使用路由模板而非实际路径，避免高基数；响应头X-Trace-Id返回链路ID便于排查
+ * @dependencies datahub-service/service/tracing, github.com/go-chi/chi/v5
+ * @refs service/tracing/tracing.go, api/routes.go
+ */
+
+package middleware
+
+import (
+	"datahub-service/service/tracing"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	chimiddleware "github.com/go-chi/chi/v5/middleware"
+)
+
+// TraceIDHeader 返回链路ID的响应头
+const TraceIDHeader = "X-Trace-Id"
+
+// Tracing 链路追踪中间件
+func Tracing(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !tracing.Enabled() {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		ctx := tracing.Extract(r.Context(), r.Header)
+		ctx, span := tracing.StartKind(ctx, r.Method+" "+r.URL.Path, tracing.SpanKindServer)
+		defer span.End()
+
+		w.Header().Set(TraceIDHeader, span.SpanContext().TraceID().String())
+		ww := chimiddleware.NewWrapResponseWriter(w, r.ProtoMajor)
+		next.ServeHTTP(ww, r.WithContext(ctx))
+
+		if rctx := chi.RouteContext(r.Context()); rctx != nil {
+			if pattern := rctx.RoutePattern(); pattern != "" {
+				span.SetName(r.Method + " " + pattern)
+				span.SetAttribute("http.route", pattern)
+			}
+		}
+		status := ww.Status()
+		if status == 0 {
+			status = http.StatusOK
+		}
+		span.SetAttribute("http.method", r.Method)
+		span.SetAttribute("http.target", r.URL.Path)
+		span.SetAttribute("http.status_code", status)
+		if status >= http.StatusInternalServerError {
+			span.SetError("HTTP %d", status)
+		}
+	})
+}
@@ -4,7 +4,7 @@
  * @architecture RESTful API架构
  * @documentReference dev_docs/backend_requirements.md
  * @stateFlow 无状态HTTP请求处理
- * @rules 遵循RESTful API设计规范，统一错误处理和响应格式
+ * @rules 遵循RESTful API设计规范，统一错误处理和响应格式；接口挂载在/api/v1下，无版本前缀的旧路径保留并返回弃用响应头；目录类查询（元数据、库、接口、规则）经ConditionalGET支持If-None-Match/If-Modified-Since返回304
  * @dependencies github.com/go-chi/chi/v5, github.com/go-chi/cors, github.com/go-chi/render
  * @refs dev_docs/model.md
  */
@@ -14,10 +14,17 @@ package api
 import (
 	"datahub-service/api/controllers"
 	"datahub-service/api/middleware"
+	"datahub-service/logger"
 	"datahub-service/service"
+	"datahub-service/service/execution"
 	"datahub-service/service/governance"
+	"datahub-service/service/models"
+	"datahub-service/service/rbac"
 	"datahub-service/service/sharing"
+	"log/slog"
 	"net/http"
+	"os"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	chimiddleware "github.com/go-chi/chi/v5/middleware"
@@ -28,17 +35,18 @@ import (
 // InitRoute 初始化所有API路由
 func InitRoute(r *chi.Mux) {
 	// 基础中间件
+	r.Use(middleware.RequestID)
 	r.Use(chimiddleware.Logger)
 	r.Use(chimiddleware.Recoverer)
-	r.Use(chimiddleware.RequestID)
+	r.Use(middleware.Tracing)
 	r.Use(render.SetContentType(render.ContentTypeJSON))
 
 	// CORS配置
 	r.Use(cors.Handler(cors.Options{
 		AllowedOrigins:   []string{"*"},
 		AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE", "HEAD", "OPTIONS"},
-		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token"},
-		ExposedHeaders:   []string{"Link"},
+		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token", "If-Match", "If-None-Match", "If-Modified-Since", logger.RequestIDHeader, middleware.TenantHeader},
+		ExposedHeaders:   []string{"Link", "ETag", middleware.TraceIDHeader, logger.RequestIDHeader, middleware.TenantHeader, middleware.APIVersionHeader, middleware.DeprecationHeader, middleware.SunsetHeader},
 		AllowCredentials: true,
 		MaxAge:           300,
 	}))
@@ -47,17 +55,46 @@ func InitRoute(r *chi.Mux) {
 	postgrestAuth := middleware.NewPostgRESTAuthMiddleware()
 	r.Use(postgrestAuth.Middleware)
 
+	// 租户中间件，按X-Tenant-ID和用户成员关系解析当前租户（未启用多租户时直接放行）
+	r.Use(middleware.NewTenantMiddleware(service.GlobalTenantService, service.GlobalRBACService).Middleware)
+
+	// 访问控制中间件，在各路由组中按资源启用
+	rbacMiddleware := middleware.NewRBACMiddleware(service.GlobalRBACService)
+
+	// 幂等请求中间件，用于任务创建、执行触发和数据推送接口
+	idempotencyMiddleware := middleware.NewIdempotencyMiddleware(service.GlobalIdempotencyService)
+
 	// 健康检查（无需认证，在白名单中）
 	healthController := controllers.NewHealthController()
 	r.Get("/health", healthController.Health)
 	r.Get("/ready", healthController.Ready)
 
+	// API版本：/api/v1下为当前版本；无版本前缀的旧路径与v1由同一组处理器提供，返回弃用响应头引导迁移
+	versions := middleware.NewAPIVersionRegistry(middleware.APIVersionV1)
+	versions.DeprecateVersion(middleware.APIVersionLegacy, legacyAPIDeprecation())
+	r.Get("/api/versions", controllers.NewAPIVersionController(versions).GetAPIVersions)
+
+	management := chi.NewRouter()
+	initManagementRoutes(management, rbacMiddleware, idempotencyMiddleware, postgrestAuth)
+
+	r.Mount(middleware.APIVersionPrefix(middleware.APIVersionV1), versions.Router(middleware.APIVersionV1, nil, func(r chi.Router) {
+		initDataAccessRoutes(r, idempotencyMiddleware)
+		r.Mount("/", management)
+	}))
+	r.Mount("/", versions.Router(middleware.APIVersionLegacy, nil, func(r chi.Router) {
+		r.Mount("/", management)
+	}))
+}
+
+// initManagementRoutes 初始化管理API路由，同时挂载在/api/v1和无版本前缀的旧路径下
+func initManagementRoutes(r chi.Router, rbacMiddleware *middleware.RBACMiddleware, idempotencyMiddleware *middleware.IdempotencyMiddleware, postgrestAuth *middleware.PostgRESTAuthMiddleware) {
 	// SSE事件订阅（需要认证）
 	eventController := controllers.NewEventController()
-	r.Get("/sse/{user_name}", eventController.HandleSSE)
+	r.With(rbacMiddleware.RequireResource(rbac.ResourceEvent)).Get("/sse/{user_name}", eventController.HandleSSE)
 
 	// 事件管理（需要认证）
 	r.Route("/events", func(r chi.Router) {
+		r.Use(rbacMiddleware.RequireResource(rbac.ResourceEvent))
 		r.Post("/send", eventController.SendEvent)
 		r.Post("/broadcast", eventController.BroadcastEvent)
 
@@ -68,12 +105,15 @@ func InitRoute(r *chi.Mux) {
 
 	// 表管理（需要认证）
 	r.Route("/tables", func(r chi.Router) {
+		r.Use(rbacMiddleware.RequireResource(rbac.ResourceTable))
 		tableController := controllers.NewTableController()
 		r.Post("/manage-schema", tableController.ManageTableSchema)
 	})
 
 	// 元数据管理（需要认证）
 	r.Route("/meta", func(r chi.Router) {
+		r.Use(rbacMiddleware.RequireResource(rbac.ResourceMetadata))
+		r.Use(middleware.ConditionalGET)
 		metaController := controllers.NewMetaController()
 
 		// 通用同步任务元数据（基础库和主题库共用）
@@ -102,10 +142,18 @@ func InitRoute(r *chi.Mux) {
 
 		// 数据治理相关元数据（统一接口）
 		r.Get("/data-governance", metaController.GetDataGovernanceMetadata)
+
+		// 错误码目录
+		r.Get("/error-codes", metaController.GetErrorCodes)
+
+		// 同步失败码目录
+		r.Get("/sync-failure-codes", metaController.GetSyncFailureCodes)
 	})
 
 	// 基础库管理（保留现有功能接口）
 	r.Route("/basic-libraries", func(r chi.Router) {
+		r.Use(rbacMiddleware.RequireResource(rbac.ResourceBasicLibrary))
+		r.Use(middleware.ConditionalGET)
 		basicLibraryController := controllers.NewBasicLibraryController()
 
 		// 列表查询接口
@@ -114,6 +162,121 @@ func InitRoute(r *chi.Mux) {
 		r.Get("/interfaces", basicLibraryController.GetDataInterfaceList)
 		r.Get("/interfaces/{id}", basicLibraryController.GetDataInterface)
 
+		// 接口数据新鲜度
+		freshnessController := controllers.NewFreshnessController()
+		r.Get("/interfaces/freshness", freshnessController.GetInterfaceFreshnessList)
+		r.Post("/interfaces/freshness/check", freshnessController.CheckAllInterfaceFreshness)
+		r.Get("/interfaces/{id}/freshness", freshnessController.GetInterfaceFreshness)
+		r.Put("/interfaces/{id}/freshness", freshnessController.UpdateInterfaceFreshnessSLA)
+		r.Post("/interfaces/{id}/freshness/check", freshnessController.CheckInterfaceFreshness)
+
+		// 接口数据契约
+		contractController := controllers.NewContractController()
+		r.Get("/interfaces/{id}/contract", contractController.GetInterfaceContract)
+		r.Put("/interfaces/{id}/contract", contractController.SaveInterfaceContract)
+		r.Delete("/interfaces/{id}/contract", contractController.DeleteInterfaceContract)
+		r.Get("/interfaces/{id}/contract/compliance", contractController.GetContractComplianceReport)
+
+		// 接口表重复数据检测
+		duplicateReportController := controllers.NewDuplicateReportController()
+		r.Post("/interfaces/{id}/duplicate-reports", duplicateReportController.CreateDuplicateReport)
+		r.Get("/interfaces/{id}/duplicate-reports", duplicateReportController.GetDuplicateReports)
+		r.Get("/interfaces/{id}/duplicate-reports/{report_id}", duplicateReportController.GetDuplicateReport)
+
+		// 接口表快照与时间点查询
+		snapshotController := controllers.NewSnapshotController()
+		r.Get("/interfaces/{id}/snapshot-policy", snapshotController.GetSnapshotPolicy)
+		r.Put("/interfaces/{id}/snapshot-policy", snapshotController.UpdateSnapshotPolicy)
+		r.Get("/interfaces/{id}/snapshots", snapshotController.GetSnapshots)
+		r.Post("/interfaces/{id}/snapshots", snapshotController.CreateSnapshot)
+		r.Get("/interfaces/{id}/snapshots/as-of", snapshotController.GetDataAsOf)
+		r.Get("/interfaces/{id}/snapshots/compare", snapshotController.CompareSnapshots)
+		r.Get("/interfaces/{id}/snapshots/{snapshot_id}/data", snapshotController.GetSnapshotData)
+		r.Delete("/interfaces/{id}/snapshots/{snapshot_id}", snapshotController.DeleteSnapshot)
+
+		// 接口基准数据集（上传或取自快照的基准数据，比较接口表作为回归测试）
+		goldenDatasetController := controllers.NewGoldenDatasetController()
+		r.Get("/interfaces/{id}/golden-datasets", goldenDatasetController.GetGoldenDatasets)
+		r.Post("/interfaces/{id}/golden-datasets", goldenDatasetController.CreateGoldenDataset)
+		r.Get("/interfaces/{id}/golden-datasets/{dataset_id}", goldenDatasetController.GetGoldenDataset)
+		r.Put("/interfaces/{id}/golden-datasets/{dataset_id}", goldenDatasetController.UpdateGoldenDataset)
+		r.Delete("/interfaces/{id}/golden-datasets/{dataset_id}", goldenDatasetController.DeleteGoldenDataset)
+		r.Get("/interfaces/{id}/golden-datasets/{dataset_id}/rows", goldenDatasetController.GetGoldenDatasetRows)
+		r.Get("/interfaces/{id}/golden-datasets/{dataset_id}/runs", goldenDatasetController.GetGoldenComparisonRuns)
+		r.Post("/interfaces/{id}/golden-datasets/{dataset_id}/runs", goldenDatasetController.RunGoldenComparison)
+		r.Get("/interfaces/{id}/golden-datasets/{dataset_id}/runs/{run_id}", goldenDatasetController.GetGoldenComparisonRun)
+
+		// 接口历史追踪（SCD Type 2）
+		scdController := controllers.NewSCDController()
+		r.Get("/interfaces/{id}/scd", scdController.GetSCDConfig)
+		r.Put("/interfaces/{id}/scd", scdController.EnableSCD)
+		r.Delete("/interfaces/{id}/scd", scdController.DisableSCD)
+
+		// 接口源端删除同步
+		deletePropagationController := controllers.NewDeletePropagationController()
+		r.Get("/interfaces/{id}/delete-propagation", deletePropagationController.GetDeletePropagation)
+		r.Put("/interfaces/{id}/delete-propagation", deletePropagationController.UpdateDeletePropagation)
+		r.Delete("/interfaces/{id}/delete-propagation", deletePropagationController.DisableDeletePropagation)
+
+		// 记录级来源追溯
+		provenanceController := controllers.NewProvenanceController()
+		r.Get("/interfaces/{id}/provenance", provenanceController.GetProvenance)
+		r.Put("/interfaces/{id}/provenance", provenanceController.EnableProvenance)
+		r.Delete("/interfaces/{id}/provenance", provenanceController.DisableProvenance)
+
+		// 接口时间序列存储（TimescaleDB超表）
+		timeSeriesController := controllers.NewTimeSeriesController()
+		r.Get("/interfaces/{id}/timeseries", timeSeriesController.GetTimeSeries)
+		r.Put("/interfaces/{id}/timeseries", timeSeriesController.SaveTimeSeries)
+		r.Delete("/interfaces/{id}/timeseries", timeSeriesController.DisableTimeSeries)
+		r.Put("/interfaces/{id}/timeseries/aggregates/{name}", timeSeriesController.SaveTimeSeriesAggregate)
+		r.Delete("/interfaces/{id}/timeseries/aggregates/{name}", timeSeriesController.DeleteTimeSeriesAggregate)
+
+		// 时间序列降采样任务（定时汇总到独立的汇总表）
+		rollupController := controllers.NewRollupController()
+		r.Get("/interfaces/{id}/timeseries/rollups", rollupController.ListRollups)
+		r.Post("/interfaces/{id}/timeseries/rollups", rollupController.CreateRollup)
+		r.Put("/interfaces/{id}/timeseries/rollups/{name}", rollupController.UpdateRollup)
+		r.Delete("/interfaces/{id}/timeseries/rollups/{name}", rollupController.DeleteRollup)
+		r.Post("/interfaces/{id}/timeseries/rollups/{name}/run", rollupController.RunRollup)
+		r.Post("/interfaces/{id}/timeseries/rollups/{name}/register", rollupController.RegisterRollup)
+
+		// 数据值告警
+		valueAlertController := controllers.NewValueAlertController()
+		r.Get("/interfaces/{id}/value-alert-rules", valueAlertController.ListValueAlertRules)
+		r.Post("/interfaces/{id}/value-alert-rules", valueAlertController.CreateValueAlertRule)
+		r.Put("/interfaces/{id}/value-alert-rules/{rule_id}", valueAlertController.UpdateValueAlertRule)
+		r.Delete("/interfaces/{id}/value-alert-rules/{rule_id}", valueAlertController.DeleteValueAlertRule)
+		r.Post("/interfaces/{id}/value-alert-rules/{rule_id}/evaluate", valueAlertController.EvaluateValueAlertRule)
+		r.Get("/interfaces/{id}/value-alerts", valueAlertController.ListValueAlerts)
+
+		// 接口Protobuf解析配置（设备网关推送的二进制消息）
+		protobufParseController := controllers.NewProtobufParseController()
+		r.Get("/interfaces/{id}/protobuf", protobufParseController.GetProtobufParse)
+		r.Put("/interfaces/{id}/protobuf", protobufParseController.SaveProtobufParse)
+		r.Delete("/interfaces/{id}/protobuf", protobufParseController.DeleteProtobufParse)
+		r.Post("/interfaces/{id}/protobuf/decode", protobufParseController.DecodeProtobuf)
+
+		// 接口同步容量规划
+		syncPlanController := controllers.NewSyncPlanController()
+		r.Post("/interfaces/{id}/sync-plan", syncPlanController.EstimateSyncPlan)
+
+		// 接口配置检查（首次同步前）
+		configLintController := controllers.NewConfigLintController()
+		r.Post("/interfaces/{id}/validate-config", configLintController.ValidateInterfaceConfig)
+
+		// 接口引用完整性（逻辑外键）
+		referenceController := controllers.NewReferenceController()
+		r.Get("/references", referenceController.GetInterfaceReferences)
+		r.Post("/references", referenceController.CreateInterfaceReference)
+		r.Post("/references/check", referenceController.CheckAllInterfaceReferences)
+		r.Get("/references/{id}", referenceController.GetInterfaceReference)
+		r.Put("/references/{id}", referenceController.UpdateInterfaceReference)
+		r.Delete("/references/{id}", referenceController.DeleteInterfaceReference)
+		r.Post("/references/{id}/check", referenceController.CheckInterfaceReference)
+		r.Get("/references/{id}/checks", referenceController.GetInterfaceReferenceChecks)
+		r.Get("/references/{id}/issues", referenceController.GetInterfaceReferenceIssues)
+
 		// 数据源测试
 		r.Post("/test-datasource", basicLibraryController.TestDataSource)
 
@@ -123,9 +286,53 @@ func InitRoute(r *chi.Mux) {
 		// 数据源状态查询
 		r.Get("/datasource-status/{id}", basicLibraryController.GetDataSourceStatus)
 
+		// 数据源接口健康报告（可导出给源系统厂商）
+		r.Get("/datasources/{id}/health-report", basicLibraryController.ExportDataSourceHealthReport)
+
+		// 数据源使用情况（下线或轮换前评估影响）
+		dataSourceUsageController := controllers.NewDataSourceUsageController()
+		r.Get("/datasources/{id}/usage", dataSourceUsageController.GetDataSourceUsage)
+
+		// 消息数据源的schema注册中心字段映射和兼容性检查
+		schemaRegistryController := controllers.NewSchemaRegistryController()
+		r.Get("/datasources/{id}/registry-schema", schemaRegistryController.GetRegistrySchema)
+
+		// 数据源凭据轮换（连通性测试后替换，宽限期内可回滚）
+		credentialRotationController := controllers.NewCredentialRotationController()
+		r.Post("/datasources/{id}/rotate-credentials", credentialRotationController.RotateCredentials)
+		r.Post("/datasources/{id}/rotate-credentials/rollback", credentialRotationController.RollbackCredentials)
+		r.Get("/datasources/{id}/credential-rotations", credentialRotationController.ListCredentialRotations)
+
+		// 数据源维护模式（上游计划停机期间跳过依赖任务的调度，结束时补跑）
+		dataSourceMaintenanceController := controllers.NewDataSourceMaintenanceController()
+		r.Get("/datasources/{id}/maintenance", dataSourceMaintenanceController.GetDataSourceMaintenance)
+		r.Post("/datasources/{id}/maintenance", dataSourceMaintenanceController.StartDataSourceMaintenance)
+		r.Delete("/datasources/{id}/maintenance", dataSourceMaintenanceController.EndDataSourceMaintenance)
+
+		// 数据源熔断（连续拉取失败后在冷却期内短路依赖任务，冷却结束后探测恢复）
+		dataSourceCircuitController := controllers.NewDataSourceCircuitController()
+		r.Get("/datasources/{id}/circuit", dataSourceCircuitController.GetDataSourceCircuit)
+		r.Delete("/datasources/{id}/circuit", dataSourceCircuitController.ResetDataSourceCircuit)
+
+		// 数据源模板（园区常见子系统的数据源和接口模板）
+		dataSourceTemplateController := controllers.NewDataSourceTemplateController()
+		r.Get("/datasource-templates", dataSourceTemplateController.ListDataSourceTemplates)
+		r.Get("/datasource-templates/{id}", dataSourceTemplateController.GetDataSourceTemplate)
+		r.Post("/datasource-templates/{id}/instantiate", dataSourceTemplateController.InstantiateDataSourceTemplate)
+
 		// 接口数据预览
 		r.Get("/interface-preview/{id}", basicLibraryController.PreviewInterfaceData)
 
+		// 字段映射建议
+		r.Post("/interfaces/{id}/suggest-mapping", basicLibraryController.SuggestFieldMapping)
+
+		// 增量字段识别
+		r.Post("/interfaces/{id}/suggest-incremental-key", basicLibraryController.SuggestIncrementalKeys)
+
+		// 接口数据JSON Schema
+		r.Get("/interfaces/{id}/json-schema", basicLibraryController.GetInterfaceJSONSchema)
+		r.Post("/interfaces/{id}/json-schema/validate", basicLibraryController.ValidateInterfacePayload)
+
 		// 添加数据基础库,需要创建schema
 		r.Post("/add-basic-library", basicLibraryController.AddBasicLibrary)
 
@@ -144,6 +351,9 @@ func InitRoute(r *chi.Mux) {
 		// 删除数据源
 		r.Delete("/datasources/{id}", basicLibraryController.DeleteDataSource)
 
+		// 上传模拟数据源样例
+		r.Post("/datasources/{id}/fixtures", basicLibraryController.UploadFixture)
+
 		// 添加数据接口
 		r.Post("/add-interface", basicLibraryController.AddInterface)
 
@@ -175,6 +385,8 @@ func InitRoute(r *chi.Mux) {
 
 	// 主题库管理
 	r.Route("/thematic-libraries", func(r chi.Router) {
+		r.Use(rbacMiddleware.RequireResource(rbac.ResourceThematicLibrary))
+		r.Use(middleware.ConditionalGET)
 		thematicLibraryController := controllers.NewThematicLibraryController()
 
 		// 列表查询接口
@@ -192,6 +404,8 @@ func InitRoute(r *chi.Mux) {
 
 	// 主题接口管理
 	r.Route("/thematic-interfaces", func(r chi.Router) {
+		r.Use(rbacMiddleware.RequireResource(rbac.ResourceThematicLibrary))
+		r.Use(middleware.ConditionalGET)
 		thematicLibraryController := controllers.NewThematicLibraryController()
 
 		// 列表查询接口
@@ -217,26 +431,52 @@ func InitRoute(r *chi.Mux) {
 		r.Get("/{id}/table-indexes", thematicLibraryController.GetThematicInterfaceTableIndexes)
 		r.Post("/create-table-index", thematicLibraryController.CreateThematicInterfaceTableIndex)
 		r.Post("/drop-table-index", thematicLibraryController.DropThematicInterfaceTableIndex)
+
+		// 按同步任务配置预览样例输出及其来源
+		r.Get("/{id}/preview", thematicLibraryController.PreviewThematicInterface)
+
+		// 发布流程
+		r.Get("/{id}/publication", thematicLibraryController.GetThematicInterfacePublication)
+		r.Get("/{id}/publication/reviews", thematicLibraryController.GetThematicInterfacePublicationReviews)
+		r.Post("/{id}/publication/submit", thematicLibraryController.SubmitThematicInterfacePublication)
+		r.Post("/{id}/publication/approve", thematicLibraryController.ApproveThematicInterfacePublication)
+		r.Post("/{id}/publication/reject", thematicLibraryController.RejectThematicInterfacePublication)
+		r.Post("/{id}/publication/publish", thematicLibraryController.PublishThematicInterface)
+		r.Post("/{id}/publication/unpublish", thematicLibraryController.UnpublishThematicInterface)
+
+		// 多源写入合并策略和冲突
+		r.Get("/{id}/merge-policy", thematicLibraryController.GetThematicInterfaceMergePolicy)
+		r.Put("/{id}/merge-policy", thematicLibraryController.UpdateThematicInterfaceMergePolicy)
+		r.Delete("/{id}/merge-policy", thematicLibraryController.DeleteThematicInterfaceMergePolicy)
+		r.Get("/{id}/merge-conflicts", thematicLibraryController.GetThematicInterfaceMergeConflicts)
+		r.Post("/{id}/merge-conflicts/{conflictId}/review", thematicLibraryController.ReviewThematicInterfaceMergeConflict)
+
+		// 变更日志策略，下游通过/api/v1/share/changelog/{interface_id}拉取
+		r.Get("/{id}/changelog-policy", thematicLibraryController.GetThematicInterfaceChangelogPolicy)
+		r.Put("/{id}/changelog-policy", thematicLibraryController.UpdateThematicInterfaceChangelogPolicy)
+		r.Delete("/{id}/changelog-policy", thematicLibraryController.DeleteThematicInterfaceChangelogPolicy)
 	})
 
 	// 通用同步任务管理（统一接口）
 	r.Route("/sync", func(r chi.Router) {
+		r.Use(rbacMiddleware.RequireResource(rbac.ResourceSyncTask))
 		// 使用全局服务初始化控制器
 		syncTaskController := controllers.NewSyncTaskController()
+		qualityGateController := controllers.NewQualityGateController()
 
 		r.Route("/tasks", func(r chi.Router) {
 			// 基础CRUD操作
-			r.Post("/", syncTaskController.CreateSyncTask)
+			r.With(idempotencyMiddleware.Middleware).Post("/", syncTaskController.CreateSyncTask)
 			r.Get("/", syncTaskController.GetSyncTaskList)
 			r.Get("/{id}", syncTaskController.GetSyncTask)
 			r.Put("/{id}", syncTaskController.UpdateSyncTask)
 			r.Delete("/{id}", syncTaskController.DeleteSyncTask)
 
 			// 任务控制操作
-			r.Post("/{id}/start", syncTaskController.StartSyncTask)
+			r.With(idempotencyMiddleware.Middleware).Post("/{id}/start", syncTaskController.StartSyncTask)
 			r.Post("/{id}/stop", syncTaskController.StopSyncTask)
 			r.Post("/{id}/cancel", syncTaskController.CancelSyncTask) // 保留向后兼容，实际为暂停
-			r.Post("/{id}/retry", syncTaskController.RetrySyncTask)
+			r.With(idempotencyMiddleware.Middleware).Post("/{id}/retry", syncTaskController.RetrySyncTask)
 			r.Get("/{id}/status", syncTaskController.GetSyncTaskStatus)
 
 			// 任务状态管理（新增）
@@ -246,9 +486,11 @@ func InitRoute(r *chi.Mux) {
 
 			// 任务执行记录
 			r.Get("/{id}/executions", syncTaskController.GetTaskExecutions)
+			r.Get("/{id}/failure-analysis", syncTaskController.GetSyncTaskFailureAnalysis)
 
 			// 批量操作
 			r.Post("/batch-delete", syncTaskController.BatchDeleteSyncTasks)
+			r.Post("/batch", syncTaskController.BatchOperateSyncTasks) // 按筛选条件激活/暂停/恢复/删除/启动执行
 
 			// 统计信息
 			r.Get("/statistics", syncTaskController.GetSyncTaskStatistics)
@@ -256,6 +498,29 @@ func InitRoute(r *chi.Mux) {
 			// 执行记录管理
 			r.Get("/executions", syncTaskController.GetSyncTaskExecutions)
 			r.Get("/executions/{id}", syncTaskController.GetSyncTaskExecution)
+
+			// 质量门禁
+			r.Get("/{id}/quality-gate", qualityGateController.GetQualityGate)
+			r.Put("/{id}/quality-gate", qualityGateController.UpdateQualityGate)
+			r.Delete("/{id}/quality-gate", qualityGateController.DisableQualityGate)
+		})
+
+		// 被质量门禁阻断的接口
+		r.Get("/quality-gate-blocks", qualityGateController.GetQualityGateBlocks)
+		r.Delete("/quality-gate-blocks/{interface_id}", qualityGateController.ReleaseQualityGateBlock)
+
+		// 同步任务模板，按模板创建任务，模板修改可同步到关联任务
+		syncTaskTemplateController := controllers.NewSyncTaskTemplateController()
+		r.Route("/task-templates", func(r chi.Router) {
+			r.Get("/", syncTaskTemplateController.GetSyncTaskTemplates)
+			r.Post("/", syncTaskTemplateController.CreateSyncTaskTemplate)
+			r.Get("/{id}", syncTaskTemplateController.GetSyncTaskTemplate)
+			r.Put("/{id}", syncTaskTemplateController.UpdateSyncTaskTemplate)
+			r.Delete("/{id}", syncTaskTemplateController.DeleteSyncTaskTemplate)
+			r.Get("/{id}/tasks", syncTaskTemplateController.GetSyncTaskTemplateTasks)
+			r.With(idempotencyMiddleware.Middleware).Post("/{id}/tasks", syncTaskTemplateController.CreateSyncTaskFromTemplate)
+			r.Delete("/{id}/tasks/{task_id}", syncTaskTemplateController.UnlinkSyncTaskTemplateTask)
+			r.Post("/{id}/propagate", syncTaskTemplateController.PropagateSyncTaskTemplate)
 		})
 	})
 
@@ -265,64 +530,89 @@ func InitRoute(r *chi.Mux) {
 
 		// 质量规则管理
 		r.Route("/rules", func(r chi.Router) {
+			r.Use(rbacMiddleware.RequireResource(rbac.ResourceQualityRule))
+			r.Use(middleware.ConditionalGET)
 			r.Post("/", dataQualityController.CreateQualityRule)
 			r.Get("/", dataQualityController.GetQualityRules)
 			r.Get("/{id}", dataQualityController.GetQualityRuleByID)
 			r.Put("/{id}", dataQualityController.UpdateQualityRule)
 			r.Delete("/{id}", dataQualityController.DeleteQualityRule)
+			r.Post("/batch", dataQualityController.BatchOperateQualityRules)
 		})
 
 		// 数据脱敏规则管理
 		r.Route("/masking-rules", func(r chi.Router) {
+			r.Use(rbacMiddleware.RequireResource(rbac.ResourceMaskingRule))
+			r.Use(middleware.ConditionalGET)
 			r.Post("/", dataQualityController.CreateMaskingRule)
 			r.Get("/", dataQualityController.GetMaskingRules)
 			r.Get("/{id}", dataQualityController.GetMaskingRuleByID)
 			r.Put("/{id}", dataQualityController.UpdateMaskingRule)
 			r.Delete("/{id}", dataQualityController.DeleteMaskingRule)
+			r.Post("/batch", dataQualityController.BatchOperateMaskingRules)
 		})
 
 		// 数据清洗规则管理
 		r.Route("/cleansing-rules", func(r chi.Router) {
+			r.Use(rbacMiddleware.RequireResource(rbac.ResourceCleansingRule))
+			r.Use(middleware.ConditionalGET)
 			r.Post("/", dataQualityController.CreateCleansingRule)
 			r.Get("/", dataQualityController.GetCleansingRules)
 			r.Get("/{id}", dataQualityController.GetCleansingRuleByID)
 			r.Put("/{id}", dataQualityController.UpdateCleansingRule)
 			r.Delete("/{id}", dataQualityController.DeleteCleansingRule)
+			r.Post("/batch", dataQualityController.BatchOperateCleansingRules)
 		})
 
 		// 数据质量检测任务管理
 		r.Route("/tasks", func(r chi.Router) {
-			r.Post("/", dataQualityController.CreateQualityTask)
+			r.Use(rbacMiddleware.RequireResource(rbac.ResourceQualityTask))
+			r.With(idempotencyMiddleware.Middleware).Post("/", dataQualityController.CreateQualityTask)
 			r.Get("/", dataQualityController.GetQualityTasks)
 			r.Get("/{id}", dataQualityController.GetQualityTaskByID)
 			r.Put("/{id}", dataQualityController.UpdateQualityTask)
 			r.Delete("/{id}", dataQualityController.DeleteQualityTask)
-			r.Post("/{id}/start", dataQualityController.StartQualityTask)
+			r.Post("/batch", dataQualityController.BatchOperateQualityTasks)
+			r.With(idempotencyMiddleware.Middleware).Post("/{id}/start", dataQualityController.StartQualityTask)
 			r.Post("/{id}/stop", dataQualityController.StopQualityTask)
 			r.Get("/{id}/executions", dataQualityController.GetQualityTaskExecutions)
 			r.Get("/{id}/issue-records", dataQualityController.GetTaskIssueRecords)
 		})
 
+		// 规则推荐（基于字段画像）
+		r.Route("/recommendations", func(r chi.Router) {
+			r.Use(rbacMiddleware.RequireResource(rbac.ResourceQualityRule))
+			r.Get("/", dataQualityController.GetRuleRecommendations)
+			r.Post("/generate", dataQualityController.GenerateRuleRecommendations)
+			r.Post("/apply", dataQualityController.ApplyRuleRecommendations)
+			r.Post("/reject", dataQualityController.RejectRuleRecommendations)
+			r.Get("/adoption-stats", dataQualityController.GetRecommendationAdoptionStats)
+		})
+
 		// 质量问题记录管理
-		r.Get("/issue-records", dataQualityController.GetQualityIssueRecords)
+		r.With(rbacMiddleware.RequireResource(rbac.ResourceQualityTask)).Get("/issue-records", dataQualityController.GetQualityIssueRecords)
 
 		// 数据血缘管理
 		r.Route("/data-lineage", func(r chi.Router) {
+			r.Use(rbacMiddleware.RequireResource(rbac.ResourceMetadata))
 			r.Post("/", dataQualityController.CreateDataLineage)
 			r.Get("/", dataQualityController.GetDataLineage)
 		})
 
 		// 质量检查
-		r.Post("/checks", dataQualityController.RunQualityCheck)
+		r.With(rbacMiddleware.RequireResource(rbac.ResourceQualityRule)).Post("/checks", dataQualityController.RunQualityCheck)
 
 		// 质量报告
 		r.Route("/reports", func(r chi.Router) {
+			r.Use(rbacMiddleware.RequireResource(rbac.ResourceQualityTask))
 			r.Get("/", dataQualityController.GetQualityReports)
 			r.Get("/{id}", dataQualityController.GetQualityReportByID)
 		})
 
 		// 元数据管理
 		r.Route("/metadata", func(r chi.Router) {
+			r.Use(rbacMiddleware.RequireResource(rbac.ResourceMetadata))
+			r.Use(middleware.ConditionalGET)
 			r.Post("/", dataQualityController.CreateMetadata)
 			r.Get("/", dataQualityController.GetMetadataList)
 			r.Get("/{id}", dataQualityController.GetMetadataByID)
@@ -331,10 +621,11 @@ func InitRoute(r *chi.Mux) {
 		})
 
 		// 系统日志管理
-		r.Get("/system-logs", dataQualityController.GetSystemLogs)
+		r.With(rbacMiddleware.RequireResource(rbac.ResourceMonitoring)).Get("/system-logs", dataQualityController.GetSystemLogs)
 
 		// 模板管理
 		r.Route("/templates", func(r chi.Router) {
+			r.Use(rbacMiddleware.RequireResource(rbac.ResourceQualityRule))
 			r.Get("/quality-rules", dataQualityController.GetQualityRuleTemplates)
 			r.Get("/masking-rules", dataQualityController.GetDataMaskingTemplates)
 			r.Get("/cleansing-rules", dataQualityController.GetDataCleansingTemplates)
@@ -342,6 +633,7 @@ func InitRoute(r *chi.Mux) {
 
 		// 规则测试
 		r.Route("/test", func(r chi.Router) {
+			r.Use(rbacMiddleware.RequireResource(rbac.ResourceQualityRule))
 			r.Post("/quality-rule", dataQualityController.TestQualityRule)
 			r.Post("/masking-rule", dataQualityController.TestMaskingRule)
 			r.Post("/cleansing-rule", dataQualityController.TestCleansingRule)
@@ -352,6 +644,7 @@ func InitRoute(r *chi.Mux) {
 
 	// 数据共享服务
 	r.Route("/sharing", func(r chi.Router) {
+		r.Use(rbacMiddleware.RequireResource(rbac.ResourceSharing))
 		sharingController := controllers.NewSharingController(sharing.NewSharingService(service.DB))
 
 		// API应用管理
@@ -371,6 +664,9 @@ func InitRoute(r *chi.Mux) {
 			r.Put("/{id}", sharingController.UpdateApiKey)
 			r.Delete("/{id}", sharingController.DeleteApiKey)
 			r.Put("/{id}/applications", sharingController.UpdateApiKeyApplications)
+			r.Put("/{id}/scopes", sharingController.UpdateApiKeyScopes)
+			r.Post("/{id}/rotate", sharingController.RotateApiKey)
+			r.Post("/{id}/revoke", sharingController.RevokeApiKey)
 		})
 
 		// API限流管理
@@ -399,6 +695,13 @@ func InitRoute(r *chi.Mux) {
 			r.Post("/{id}/approve", sharingController.ApproveDataAccessRequest)
 		})
 
+		// 数据使用授权（审批通过的限期授权，供合规审计查询）
+		r.Route("/data-access-grants", func(r chi.Router) {
+			r.Get("/", sharingController.GetDataAccessGrants)
+			r.Get("/{id}", sharingController.GetDataAccessGrantByID)
+			r.Post("/{id}/revoke", sharingController.RevokeDataAccessGrant)
+		})
+
 		// API使用日志管理
 		r.Route("/api-usage-logs", func(r chi.Router) {
 			r.Get("/", sharingController.GetApiUsageLogs)
@@ -415,32 +718,35 @@ func InitRoute(r *chi.Mux) {
 			// 脱敏规则管理
 			r.Put("/{id}/masking-rules", sharingController.UpdateApiInterfaceMaskingRules)
 			r.Get("/{id}/masking-rules", sharingController.GetApiInterfaceMaskingRules)
+			// 灰度发布管理
+			r.Get("/{id}/releases", sharingController.GetApiInterfaceReleases)
+			r.Post("/{id}/releases", sharingController.CreateApiInterfaceRelease)
+			r.Put("/{id}/releases/{release_id}/subscribers", sharingController.UpdateApiInterfaceReleaseSubscribers)
+			r.Post("/{id}/releases/{release_id}/promote", sharingController.PromoteApiInterfaceRelease)
+			r.Post("/{id}/releases/{release_id}/rollback", sharingController.RollbackApiInterfaceRelease)
 		})
-	})
-
-	// 数据访问代理API（只读查询）
-	r.Route("/api/v1", func(r chi.Router) {
-		sharingService := sharing.NewSharingService(service.DB)
-		governanceService := governance.NewGovernanceService(service.DB)
-		dataProxyController := controllers.NewDataProxyController(sharingService, governanceService)
 
-		// 数据代理接口，URL格式：/api/v1/share/{app_path}/{interface_path}
-		r.Route("/share", func(r chi.Router) {
-			// 通过API Key获取应用信息和接口列表，URL格式：/api/v1/share/
-			r.Get("/", dataProxyController.GetApiApplicationByKey)
-			// 获取应用信息和接口列表，URL格式：/api/v1/share/{app_path}
-			r.Get("/{app_path}", dataProxyController.GetApplicationInfo)
+		// 接口弃用管理
+		r.Route("/deprecations", func(r chi.Router) {
+			r.Get("/", sharingController.GetInterfaceDeprecations)
+			r.Get("/{resource_type}/{resource_id}", sharingController.GetInterfaceDeprecation)
+			r.Put("/{resource_type}/{resource_id}", sharingController.DeprecateInterface)
+			r.Delete("/{resource_type}/{resource_id}", sharingController.CancelInterfaceDeprecation)
+		})
 
-			// 只支持GET和HEAD方法的代理请求
-			r.Get("/{app_path}/{interface_path}", dataProxyController.ProxyDataAccess)
-			r.Head("/{app_path}/{interface_path}", dataProxyController.ProxyDataAccess)
-			r.Get("/{app_path}/{interface_path}/*", dataProxyController.ProxyDataAccess)
-			r.Head("/{app_path}/{interface_path}/*", dataProxyController.ProxyDataAccess)
+		// 行级安全策略管理
+		r.Route("/row-policies", func(r chi.Router) {
+			r.Post("/", sharingController.CreateRowLevelPolicy)
+			r.Get("/", sharingController.GetRowLevelPolicies)
+			r.Get("/{id}", sharingController.GetRowLevelPolicyByID)
+			r.Put("/{id}", sharingController.UpdateRowLevelPolicy)
+			r.Delete("/{id}", sharingController.DeleteRowLevelPolicy)
 		})
 	})
 
 	// 监控管理（简化版 - 仅基于 VictoriaMetrics 和 Loki）
 	r.Route("/monitoring", func(r chi.Router) {
+		r.Use(rbacMiddleware.RequireResource(rbac.ResourceMonitoring))
 		monitoringController := controllers.NewMonitoringController()
 
 		// 通用查询接口
@@ -466,19 +772,20 @@ func InitRoute(r *chi.Mux) {
 
 	// 主题同步管理
 	r.Route("/thematic-sync", func(r chi.Router) {
+		r.Use(rbacMiddleware.RequireResource(rbac.ResourceThematicSync))
 		thematicSyncController := controllers.NewThematicSyncController()
 
 		// 同步任务管理
 		r.Route("/tasks", func(r chi.Router) {
 			// 基础CRUD操作
-			r.Post("/", thematicSyncController.CreateSyncTask)
+			r.With(idempotencyMiddleware.Middleware).Post("/", thematicSyncController.CreateSyncTask)
 			r.Get("/", thematicSyncController.GetSyncTaskList)
 			r.Get("/{id}", thematicSyncController.GetSyncTask)
 			r.Put("/{id}", thematicSyncController.UpdateSyncTask)
 			r.Delete("/{id}", thematicSyncController.DeleteSyncTask)
 
 			// 任务控制操作
-			r.Post("/{id}/execute", thematicSyncController.ExecuteSyncTask)
+			r.With(idempotencyMiddleware.Middleware).Post("/{id}/execute", thematicSyncController.ExecuteSyncTask)
 			r.Get("/{id}/status", thematicSyncController.GetSyncTaskStatus)
 
 			// 任务执行记录
@@ -497,6 +804,7 @@ func InitRoute(r *chi.Mux) {
 	// 数据查看路由
 	dataViewController := controllers.NewDataViewController(service.DB)
 	r.Route("/data-view", func(r chi.Router) {
+		r.Use(rbacMiddleware.RequireResource(rbac.ResourceDataView))
 		// 获取库的所有表
 		r.Get("/{library_type}/{library_id}/tables", dataViewController.GetLibraryTables)
 
@@ -512,10 +820,13 @@ func InitRoute(r *chi.Mux) {
 
 	// HTTP POST数据源管理（需要认证）
 	r.Route("/http-post", func(r chi.Router) {
+		r.Use(rbacMiddleware.RequireResource(rbac.ResourceBasicLibrary))
 		httpPostController := controllers.NewHTTPPostController()
 
-		// webhook接收
-		r.Post("/webhook/{suffix}", httpPostController.HandleWebhook)
+		// webhook接收，常驻数据源只在执行面运行，control模式推送需路由到worker
+		if execution.RunsExecutionPlane(service.GlobalDeployMode) {
+			r.With(idempotencyMiddleware.Middleware).Post("/webhook/{suffix}", httpPostController.HandleWebhook)
+		}
 
 		// 数据源管理
 		r.Get("/datasources", httpPostController.GetDataSourceList)
@@ -526,6 +837,7 @@ func InitRoute(r *chi.Mux) {
 
 	// Dashboard统计数据（需要认证）
 	r.Route("/dashboard", func(r chi.Router) {
+		r.Use(rbacMiddleware.RequireResource(rbac.ResourceDashboard))
 		dashboardController := controllers.NewDashboardController()
 
 		// 总览数据
@@ -542,6 +854,7 @@ func InitRoute(r *chi.Mux) {
 
 	// 系统配置管理（需要认证）
 	r.Route("/config", func(r chi.Router) {
+		r.Use(rbacMiddleware.RequireResource(rbac.ResourceConfig))
 		configController := controllers.NewConfigController()
 		r.Get("/", configController.GetAllConfigs)
 		r.Get("/{key}", configController.GetConfig)
@@ -549,6 +862,313 @@ func InitRoute(r *chi.Mux) {
 		r.Post("/batch", configController.BatchUpdateConfigs)
 	})
 
+	// 运行时配置管理（需要认证），修改后热加载
+	r.Route("/admin/config", func(r chi.Router) {
+		r.Use(rbacMiddleware.RequireResource(rbac.ResourceConfig))
+		runtimeConfigController := controllers.NewRuntimeConfigController()
+		r.Get("/", runtimeConfigController.GetRuntimeConfig)
+		r.Put("/", runtimeConfigController.UpdateRuntimeConfig)
+		r.Post("/reload", runtimeConfigController.ReloadRuntimeConfig)
+	})
+
+	// 同步执行数据变更摘要（需要认证），查看一次增量同步实际新增和更新的数据
+	r.Route("/sync-executions", func(r chi.Router) {
+		r.Use(rbacMiddleware.RequireResource(rbac.ResourceSyncTask))
+		syncTaskController := controllers.NewSyncTaskController()
+		r.Get("/{id}/diff", syncTaskController.GetSyncExecutionDiff)
+	})
+
+	// 同步执行诊断（需要认证），供技术支持排查单次执行
+	r.Route("/admin/executions", func(r chi.Router) {
+		r.Use(rbacMiddleware.RequireResource(rbac.ResourceSyncTask))
+		executionDiagnosticsController := controllers.NewExecutionDiagnosticsController()
+		r.Get("/{id}/diagnostics", executionDiagnosticsController.GetExecutionDiagnostics)
+	})
+
+	// 应用事件查询（需要认证），按响应中的request_id追溯任务事件
+	r.Route("/admin/events", func(r chi.Router) {
+		r.Use(rbacMiddleware.RequireResource(rbac.ResourceConfig))
+		eventLogController := controllers.NewEventLogController()
+		r.Get("/", eventLogController.GetApplicationEvents)
+	})
+
+	// 系统台账导入（需要认证），从Excel台账批量创建草稿基础库、数据源和元数据
+	r.Route("/admin/catalog", func(r chi.Router) {
+		r.Use(rbacMiddleware.RequireResource(rbac.ResourceBasicLibrary))
+		catalogImportController := controllers.NewCatalogImportController()
+		r.Get("/import/template", catalogImportController.GetCatalogImportTemplate)
+		r.Post("/import", catalogImportController.ImportCatalog)
+	})
+
+	// 目录数据缓存统计和清空（需要认证）
+	r.Route("/admin/catalog-cache", func(r chi.Router) {
+		r.Use(rbacMiddleware.RequireResource(rbac.ResourceConfig))
+		catalogCacheController := controllers.NewCatalogCacheController()
+		r.Get("/", catalogCacheController.GetCatalogCacheStats)
+		r.Delete("/", catalogCacheController.FlushCatalogCache)
+	})
+
+	// 当前用户可执行的操作（所有已认证用户可查询），前端据此隐藏无权操作
+	r.Get("/me/permissions", controllers.NewRBACController().GetMyPermissions)
+
+	// 访问控制管理（需要认证）
+	r.Route("/rbac", func(r chi.Router) {
+		rbacController := controllers.NewRBACController()
+
+		// 当前用户的有效权限（所有已认证用户可查询）
+		r.Get("/me", rbacController.GetMyAccess)
+
+		r.Group(func(r chi.Router) {
+			r.Use(rbacMiddleware.RequireResource(rbac.ResourceRBAC))
+
+			// 角色权限管理
+			r.Get("/roles", rbacController.GetRoles)
+			r.Put("/roles/{role}/permissions", rbacController.UpdateRolePermissions)
+
+			// 用户角色分配
+			r.Get("/assignments", rbacController.GetRoleAssignments)
+			r.Post("/assignments", rbacController.AssignRole)
+			r.Delete("/assignments/{id}", rbacController.RevokeRoleAssignment)
+
+			// 目录对象授权，限定库和规则模板的可见范围
+			r.Get("/grants", rbacController.GetResourceGrants)
+			r.Post("/grants", rbacController.CreateResourceGrant)
+			r.Delete("/grants/{id}", rbacController.DeleteResourceGrant)
+		})
+	})
+
+	// 租户管理（需要认证）
+	r.Route("/tenants", func(r chi.Router) {
+		tenantController := controllers.NewTenantController()
+
+		// 当前请求的租户（所有已认证用户可查询）
+		r.Get("/current", tenantController.GetCurrentTenant)
+
+		r.Group(func(r chi.Router) {
+			r.Use(rbacMiddleware.RequireResource(rbac.ResourceTenant))
+
+			r.Get("/", tenantController.GetTenants)
+			r.Post("/", tenantController.CreateTenant)
+			r.Get("/{id}", tenantController.GetTenant)
+			r.Put("/{id}", tenantController.UpdateTenant)
+			r.Delete("/{id}", tenantController.DeleteTenant)
+
+			// 租户成员
+			r.Get("/{id}/members", tenantController.GetTenantMembers)
+			r.Post("/{id}/members", tenantController.AddTenantMember)
+			r.Delete("/{id}/members/{username}", tenantController.RemoveTenantMember)
+		})
+	})
+
+	// 备份记录与完整性校验（需要认证）
+	r.Route("/backups", func(r chi.Router) {
+		r.Use(rbacMiddleware.RequireResource(rbac.ResourceBackup))
+		backupController := controllers.NewBackupController()
+
+		r.Get("/records", backupController.GetBackupRecords)
+		r.Post("/records/{id}/verify", backupController.VerifyBackupRecord)
+	})
+
+	// 存储容量报表（需要认证）
+	r.Route("/capacity", func(r chi.Router) {
+		r.Use(rbacMiddleware.RequireResource(rbac.ResourceCapacity))
+		capacityController := controllers.NewCapacityController()
+
+		r.Get("/libraries", capacityController.GetLibraryCapacityReports)
+		r.Get("/libraries/{id}", capacityController.GetLibraryCapacityDetail)
+		r.Get("/tenants", capacityController.GetTenantCapacityReports)
+		r.Post("/snapshots", capacityController.CollectStorageSnapshots)
+
+		// 库和租户配额
+		quotaController := controllers.NewQuotaController()
+		r.Get("/quotas", quotaController.ListQuotaUsage)
+		r.Get("/quotas/{scope_type}/{scope_id}", quotaController.GetQuotaUsage)
+		r.Put("/quotas/{scope_type}/{scope_id}", quotaController.SetQuota)
+		r.Delete("/quotas/{scope_type}/{scope_id}", quotaController.DeleteQuota)
+	})
+
+	// 元数据一致性核对（需要认证）
+	r.Route("/reconciliation", func(r chi.Router) {
+		r.Use(rbacMiddleware.RequireResource(rbac.ResourceMetadata))
+		reconciliationController := controllers.NewReconciliationController()
+
+		r.Get("/reports", reconciliationController.GetReconciliationReports)
+		r.Post("/reports", reconciliationController.RunReconciliation)
+		r.Get("/reports/{id}", reconciliationController.GetReconciliationReport)
+		r.Post("/reports/{id}/fix", reconciliationController.FixReconciliationFindings)
+	})
+
+	// 共享接口表查询性能分析与索引建议（需要认证）
+	r.Route("/query-insights", func(r chi.Router) {
+		r.Use(rbacMiddleware.RequireResource(rbac.ResourceTable))
+		queryInsightController := controllers.NewQueryInsightController()
+
+		r.Get("/tables", queryInsightController.GetTableInsights)
+		r.Get("/tables/{id}", queryInsightController.GetTableInsight)
+		r.Post("/tables/{id}/indexes", queryInsightController.CreateRecommendedIndex)
+	})
+
+	// SQL工作台（需要认证）
+	r.Route("/workbench", func(r chi.Router) {
+		r.Use(rbacMiddleware.RequireResource(rbac.ResourceWorkbench))
+		workbenchController := controllers.NewSQLWorkbenchController()
+
+		r.Post("/execute", workbenchController.ExecuteQuery)
+		r.Get("/history", workbenchController.GetHistory)
+		r.Get("/saved-queries", workbenchController.GetSavedQueries)
+		r.Post("/saved-queries", workbenchController.CreateSavedQuery)
+		r.Get("/saved-queries/{id}", workbenchController.GetSavedQuery)
+		r.Put("/saved-queries/{id}", workbenchController.UpdateSavedQuery)
+		r.Delete("/saved-queries/{id}", workbenchController.DeleteSavedQuery)
+	})
+
+	// 指标层：业务指标定义与物化指标值（需要认证）
+	r.Route("/metrics-store", func(r chi.Router) {
+		r.Use(rbacMiddleware.RequireResource(rbac.ResourceMetricStore))
+		metricStoreController := controllers.NewMetricStoreController()
+
+		r.Get("/metrics", metricStoreController.GetMetrics)
+		r.Post("/metrics", metricStoreController.CreateMetric)
+		r.Get("/metrics/{id}", metricStoreController.GetMetric)
+		r.Put("/metrics/{id}", metricStoreController.UpdateMetric)
+		r.Delete("/metrics/{id}", metricStoreController.DeleteMetric)
+		r.Post("/metrics/{id}/refresh", metricStoreController.RefreshMetric)
+		r.Get("/metrics/{id}/values", metricStoreController.GetMetricValues)
+	})
+
+	// 报表订阅：保存的查询和指标定时生成文件发送（需要认证）
+	r.Route("/report-subscriptions", func(r chi.Router) {
+		r.Use(rbacMiddleware.RequireResource(rbac.ResourceReport))
+		reportController := controllers.NewReportSubscriptionController()
+
+		r.Get("/", reportController.GetReportSubscriptions)
+		r.Post("/", reportController.CreateReportSubscription)
+		r.Get("/{id}", reportController.GetReportSubscription)
+		r.Put("/{id}", reportController.UpdateReportSubscription)
+		r.Delete("/{id}", reportController.DeleteReportSubscription)
+		r.Post("/{id}/send", reportController.SendReportSubscription)
+	})
+
+	// 自定义函数注册表：质量和清洗规则引用的表达式函数，管理员登记和评审（需要认证）
+	r.Route("/udfs", func(r chi.Router) {
+		r.Use(rbacMiddleware.RequireResource(rbac.ResourceUDF))
+		udfController := controllers.NewUDFController()
+
+		r.Get("/", udfController.GetUDFs)
+		r.Post("/", udfController.CreateUDF)
+		r.Get("/{id}", udfController.GetUDF)
+		r.Put("/{id}", udfController.UpdateUDF)
+		r.Delete("/{id}", udfController.DeleteUDF)
+		r.Post("/{id}/versions", udfController.CreateUDFVersion)
+		r.Put("/{id}/versions/{version}", udfController.UpdateUDFVersion)
+		r.Post("/{id}/versions/{version}/submit", udfController.SubmitUDFVersion)
+		r.Post("/{id}/versions/{version}/approve", udfController.ApproveUDFVersion)
+		r.Post("/{id}/versions/{version}/reject", udfController.RejectUDFVersion)
+		r.Post("/{id}/versions/{version}/test", udfController.TestUDFVersion)
+	})
+
+	// 平台事件Webhook：外部系统登记地址订阅平台事件，签名推送并记录投递日志（需要认证）
+	r.Route("/webhooks", func(r chi.Router) {
+		r.Use(rbacMiddleware.RequireResource(rbac.ResourceWebhook))
+		webhookController := controllers.NewWebhookController()
+
+		r.Get("/", webhookController.GetWebhooks)
+		r.Post("/", webhookController.CreateWebhook)
+		r.Get("/event-types", webhookController.GetWebhookEventTypes)
+		r.Get("/deliveries", webhookController.GetWebhookDeliveries)
+		r.Get("/deliveries/{id}", webhookController.GetWebhookDelivery)
+		r.Post("/deliveries/{id}/redeliver", webhookController.RedeliverWebhookDelivery)
+		r.Get("/{id}", webhookController.GetWebhook)
+		r.Put("/{id}", webhookController.UpdateWebhook)
+		r.Delete("/{id}", webhookController.DeleteWebhook)
+		r.Post("/{id}/rotate", webhookController.RotateWebhookSecret)
+		r.Post("/{id}/test", webhookController.PingWebhook)
+	})
+
+	// 合规证据包：个人信息保护法、等保2.0审计用的证据汇总和导出（需要认证）
+	r.Route("/compliance", func(r chi.Router) {
+		r.Use(rbacMiddleware.RequireResource(rbac.ResourceCompliance))
+		complianceController := controllers.NewComplianceController()
+
+		r.Get("/report", complianceController.GetComplianceReport)
+	})
+
+	// 长时操作：异步启动接口返回的操作资源，按操作类型在控制器中鉴权（需要认证）
+	r.Route("/operations", func(r chi.Router) {
+		operationController := controllers.NewOperationController()
+
+		r.Get("/", operationController.GetOperations)
+		r.Get("/{id}", operationController.GetOperation)
+		r.Post("/{id}/cancel", operationController.CancelOperation)
+	})
+
+	// 敏感列加密配置与密钥轮换（需要认证）
+	r.Route("/encryption", func(r chi.Router) {
+		r.Use(rbacMiddleware.RequireResource(rbac.ResourceEncryption))
+		encryptionController := controllers.NewEncryptionController()
+
+		r.Get("/status", encryptionController.GetEncryptionStatus)
+		r.Get("/columns", encryptionController.GetColumnEncryptions)
+		r.Post("/columns", encryptionController.CreateColumnEncryption)
+		r.Get("/columns/{id}", encryptionController.GetColumnEncryption)
+		r.Put("/columns/{id}", encryptionController.UpdateColumnEncryption)
+		r.Delete("/columns/{id}", encryptionController.DeleteColumnEncryption)
+		r.Get("/columns/{id}/key-usage", encryptionController.GetColumnKeyUsage)
+		r.Post("/columns/{id}/rotate", encryptionController.RotateColumnKey)
+		r.Post("/columns/{id}/decrypt", encryptionController.DecryptColumn)
+	})
+
+	// 通知中心（需要认证）
+	r.Route("/notifications", func(r chi.Router) {
+		notificationController := controllers.NewNotificationController()
+
+		// 站内通知，有读权限即可查看和标记已读
+		r.Group(func(r chi.Router) {
+			r.Use(rbacMiddleware.RequireAction(rbac.ResourceNotification, models.ActionRead))
+			r.Get("/", notificationController.GetNotifications)
+			r.Get("/unread-count", notificationController.GetUnreadNotificationCount)
+			r.Post("/read-all", notificationController.MarkAllNotificationsRead)
+			r.Get("/{id}", notificationController.GetNotification)
+			r.Post("/{id}/read", notificationController.MarkNotificationRead)
+		})
+
+		// 通知渠道和订阅规则
+		r.Group(func(r chi.Router) {
+			r.Use(rbacMiddleware.RequireResource(rbac.ResourceNotification))
+			r.Get("/channels", notificationController.GetNotifyChannels)
+			r.Post("/channels", notificationController.CreateNotifyChannel)
+			r.Get("/channels/{id}", notificationController.GetNotifyChannel)
+			r.Put("/channels/{id}", notificationController.UpdateNotifyChannel)
+			r.Delete("/channels/{id}", notificationController.DeleteNotifyChannel)
+			r.Post("/channels/{id}/test", notificationController.TestNotifyChannel)
+
+			r.Get("/subscriptions", notificationController.GetNotifySubscriptions)
+			r.Post("/subscriptions", notificationController.CreateNotifySubscription)
+			r.Get("/subscriptions/{id}", notificationController.GetNotifySubscription)
+			r.Put("/subscriptions/{id}", notificationController.UpdateNotifySubscription)
+			r.Delete("/subscriptions/{id}", notificationController.DeleteNotifySubscription)
+
+			r.Get("/templates", notificationController.GetNotifyTemplates)
+			r.Post("/templates", notificationController.CreateNotifyTemplate)
+			r.Post("/templates/preview", notificationController.PreviewNotifyTemplate)
+			r.Get("/templates/{id}", notificationController.GetNotifyTemplate)
+			r.Put("/templates/{id}", notificationController.UpdateNotifyTemplate)
+			r.Delete("/templates/{id}", notificationController.DeleteNotifyTemplate)
+		})
+	})
+
+	// 故障演练（仅非生产环境启用，审计记录见系统日志）
+	r.Route("/chaos", func(r chi.Router) {
+		r.Use(rbacMiddleware.RequireResource(rbac.ResourceChaos))
+		chaosController := controllers.NewChaosController()
+
+		r.Get("/status", chaosController.GetChaosStatus)
+		r.Get("/faults", chaosController.GetFaultInjections)
+		r.Post("/faults", chaosController.CreateFaultInjection)
+		r.Post("/faults/stop-all", chaosController.StopAllFaultInjections)
+		r.Post("/faults/{id}/stop", chaosController.StopFaultInjection)
+	})
+
 	// 认证中间件管理接口（需要管理员权限）
 	r.Route("/admin/auth", func(r chi.Router) {
 		// 需要管理员权限（全局中间件已经处理了基本认证）
@@ -576,3 +1196,87 @@ func InitRoute(r *chi.Mux) {
 		})
 	})
 }
+
+// initDataAccessRoutes 初始化数据访问路由（数据代理和机器调用方数据推送），只在/api/v1下提供
+func initDataAccessRoutes(r chi.Router, idempotencyMiddleware *middleware.IdempotencyMiddleware) {
+	sharingService := sharing.NewSharingService(service.DB)
+	apiKeyAuth := middleware.NewApiKeyAuthMiddleware(sharingService)
+	governanceService := governance.NewGovernanceService(service.DB)
+	dataProxyController := controllers.NewDataProxyController(sharingService, governanceService)
+
+	// 数据代理接口，URL格式：/api/v1/share/{app_path}/{interface_path}
+	r.Route("/share", func(r chi.Router) {
+		// 通过API Key获取应用信息和接口列表，URL格式：/api/v1/share/
+		r.Get("/", dataProxyController.GetApiApplicationByKey)
+		// 按游标拉取共享接口的变更日志，URL格式：/api/v1/share/changelog/{interface_id}
+		r.Get("/changelog/{interface_id}", dataProxyController.GetInterfaceChangelog)
+		// 获取应用信息和接口列表，URL格式：/api/v1/share/{app_path}
+		r.Get("/{app_path}", dataProxyController.GetApplicationInfo)
+
+		// 只支持GET和HEAD方法的代理请求
+		r.Get("/{app_path}/{interface_path}", dataProxyController.ProxyDataAccess)
+		r.Head("/{app_path}/{interface_path}", dataProxyController.ProxyDataAccess)
+		r.Get("/{app_path}/{interface_path}/*", dataProxyController.ProxyDataAccess)
+		r.Head("/{app_path}/{interface_path}/*", dataProxyController.ProxyDataAccess)
+	})
+
+	// 机器调用方数据推送，使用ApiKey认证，URL格式：/api/v1/ingest/webhook/{suffix}
+	r.Route("/ingest", func(r chi.Router) {
+		r.Use(apiKeyAuth.RequireScope(models.ApiKeyScopeIngestWrite))
+		r.Use(idempotencyMiddleware.Middleware)
+		// 常驻数据源只在执行面运行，control模式不接收webhook推送
+		if execution.RunsExecutionPlane(service.GlobalDeployMode) {
+			httpPostController := controllers.NewHTTPPostController()
+			r.Post("/webhook/{suffix}", httpPostController.HandleWebhook)
+		}
+		// 设备遥测批量写入，URL格式：/api/v1/ingest/telemetry/{interface_id}
+		r.Post("/telemetry/{interface_id}", controllers.NewTelemetryController().IngestTelemetry)
+	})
+}
+
+// legacyAPIDeprecation 无版本前缀旧路径的弃用计划，API_LEGACY_DEPRECATED_SINCE和API_LEGACY_SUNSET配置弃用和计划停用日期（格式2006-01-02或RFC3339）
+func legacyAPIDeprecation() middleware.APIDeprecation {
+	deprecation := middleware.APIDeprecation{Successor: middleware.APIVersionV1}
+	deprecation.Since = parseAPIDeprecationDate("API_LEGACY_DEPRECATED_SINCE")
+	deprecation.Sunset = parseAPIDeprecationDate("API_LEGACY_SUNSET")
+	return deprecation
+}
+
+// parseAPIDeprecationDate 读取环境变量配置的日期，未配置或格式错误时返回零值
+func parseAPIDeprecationDate(env string) time.Time {
+	value := os.Getenv(env)
+	if value == "" {
+		return time.Time{}
+	}
+	for _, layout := range []string{"2006-01-02", time.RFC3339} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t
+		}
+	}
+	slog.Warn("API弃用日期格式错误，已忽略", "env", env, "value", value)
+	return time.Time{}
+}
+
+// InitWorkerRoute 初始化执行面（worker模式）路由，只包含健康检查和机器调用方数据推送
+func InitWorkerRoute(r *chi.Mux) {
+	r.Use(middleware.RequestID)
+	r.Use(chimiddleware.Logger)
+	r.Use(chimiddleware.Recoverer)
+	r.Use(middleware.Tracing)
+	r.Use(render.SetContentType(render.ContentTypeJSON))
+
+	healthController := controllers.NewHealthController()
+	r.Get("/health", healthController.Health)
+	r.Get("/ready", healthController.Ready)
+
+	// HTTP POST常驻数据源运行在执行面，推送请求需路由到worker
+	apiKeyAuth := middleware.NewApiKeyAuthMiddleware(sharing.NewSharingService(service.DB))
+	idempotencyMiddleware := middleware.NewIdempotencyMiddleware(service.GlobalIdempotencyService)
+	r.Route("/api/v1/ingest", func(r chi.Router) {
+		r.Use(apiKeyAuth.RequireScope(models.ApiKeyScopeIngestWrite))
+		r.Use(idempotencyMiddleware.Middleware)
+		httpPostController := controllers.NewHTTPPostController()
+		r.Post("/webhook/{suffix}", httpPostController.HandleWebhook)
+		r.Post("/telemetry/{interface_id}", controllers.NewTelemetryController().IngestTelemetry)
+	})
+}
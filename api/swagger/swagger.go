@@ -0,0 +1,118 @@
+/*
+ * @module api/swagger/swagger
+ * @description Swagger文档路由，分别提供管理API和数据共享API两份文档，并按部署配置和网关转发头改写文档的basePath、host和schemes
+ * @architecture RESTful API架构
+ * @documentReference dev_docs/backend_requirements.md
+ * @stateFlow 请求文档 -> 读取swag生成的文档 -> 按环境变量或X-Forwarded-Prefix计算basePath -> 返回改写后的文档
+ * @rules 环境变量配置的basePath优先；未配置时为网关转发前缀加服务挂载路径；host和schemes只在配置时改写；文档页面可在两份文档间切换
+ * @dependencies github.com/swaggo/swag, github.com/swaggo/http-swagger, github.com/go-chi/chi/v5
+ * @refs main.go, swagger.sh, docs
+ */
+
+package swagger
+
+// 数据共享API文档的通用信息，由swagger.sh以本文件为入口生成sharing实例的文档
+//
+// @title 数据底座数据共享 API
+// @version 1.0
+// @description 面向数据使用方的只读数据访问接口，使用API Key认证（Authorization: Bearer <API Key>）
+// @BasePath /
+
+import (
+	"encoding/json"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/go-chi/chi/v5"
+	httpSwagger "github.com/swaggo/http-swagger"
+	"github.com/swaggo/swag"
+)
+
+// swag生成的文档实例名
+const (
+	ManagementInstance = "management"
+	SharingInstance    = "sharing"
+)
+
+// swaggerSpec 一份文档及其basePath配置
+type swaggerSpec struct {
+	instance    string
+	basePathEnv string
+
+	once sync.Once
+	doc  map[string]interface{}
+	err  error
+}
+
+// load 读取并缓存swag生成的文档
+func (s *swaggerSpec) load() (map[string]interface{}, error) {
+	s.once.Do(func() {
+		raw, err := swag.ReadDoc(s.instance)
+		if err != nil {
+			s.err = err
+			return
+		}
+		s.err = json.Unmarshal([]byte(raw), &s.doc)
+	})
+	return s.doc, s.err
+}
+
+// swaggerHandler 返回改写basePath、host和schemes后的文档
+func (s *swaggerSpec) swaggerHandler(mountPath string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		doc, err := s.load()
+		if err != nil {
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+
+		served := make(map[string]interface{}, len(doc))
+		for key, value := range doc {
+			served[key] = value
+		}
+		served["basePath"] = swaggerBasePath(r, os.Getenv(s.basePathEnv), mountPath)
+		if host := os.Getenv("SWAGGER_HOST"); host != "" {
+			served["host"] = host
+		}
+		if schemes := os.Getenv("SWAGGER_SCHEMES"); schemes != "" {
+			served["schemes"] = strings.Split(schemes, ",")
+		}
+
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		_ = json.NewEncoder(w).Encode(served)
+	}
+}
+
+// swaggerBasePath 计算文档的basePath：配置值优先，否则为网关转发前缀加服务挂载路径
+func swaggerBasePath(r *http.Request, configured, mountPath string) string {
+	if configured != "" {
+		return configured
+	}
+	basePath := strings.TrimSuffix(r.Header.Get("X-Forwarded-Prefix"), "/") + strings.TrimSuffix(mountPath, "/")
+	if basePath == "" {
+		return "/"
+	}
+	return basePath
+}
+
+// InitRoute 挂载Swagger文档路由，mountPath为服务路由的挂载路径(BASE_CONTEXT)。
+// 管理API和数据共享API的basePath分别可通过SWAGGER_BASE_PATH、SWAGGER_SHARING_BASE_PATH配置
+func InitRoute(r chi.Router, mountPath string) {
+	management := &swaggerSpec{instance: ManagementInstance, basePathEnv: "SWAGGER_BASE_PATH"}
+	sharing := &swaggerSpec{instance: SharingInstance, basePathEnv: "SWAGGER_SHARING_BASE_PATH"}
+
+	r.Get("/swagger/management.json", management.swaggerHandler(mountPath))
+	r.Get("/swagger/sharing.json", sharing.swaggerHandler(mountPath))
+	// 兼容旧的文档地址
+	r.Get("/swagger/doc.json", management.swaggerHandler(mountPath))
+	r.Handle("/swagger*", httpSwagger.Handler(
+		httpSwagger.InstanceName(ManagementInstance),
+		httpSwagger.URL("management.json"),
+		httpSwagger.UIConfig(map[string]string{
+			"urls":               `[{url: "management.json", name: "管理API"}, {url: "sharing.json", name: "数据共享API"}]`,
+			`"urls.primaryName"`: `"管理API"`,
+		}),
+	))
+}
@@ -0,0 +1,89 @@
+/*
+ * @module api/swagger/swagger_test
+ * @description Swagger文档路由测试，覆盖两份文档的内容划分、basePath按挂载路径和网关转发前缀计算、环境变量配置的basePath、host和schemes以及文档页面
+ * @architecture 测试层
+ * @documentReference dev_docs/backend_requirements.md
+ * @stateFlow 挂载文档路由 -> 请求文档 -> 验证改写后的字段
+ * @rules 使用docs包中swag生成的文档，不依赖数据库
+ * @dependencies stretchr/testify
+ * @refs swagger.go
+ */
+
+package swagger
+
+import (
+	_ "datahub-service/docs"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// getDoc 请求文档并解析
+func getDoc(t *testing.T, router http.Handler, path string, header http.Header) map[string]interface{} {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	for key, values := range header {
+		req.Header[key] = values
+	}
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	var doc map[string]interface{}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &doc))
+	return doc
+}
+
+func TestInitRoute(t *testing.T) {
+	router := chi.NewRouter()
+	router.Route("/datahub", func(r chi.Router) {
+		InitRoute(r, "/datahub")
+	})
+
+	management := getDoc(t, router, "/datahub/swagger/management.json", nil)
+	sharing := getDoc(t, router, "/datahub/swagger/sharing.json", nil)
+	assert.Equal(t, "/datahub", management["basePath"])
+	assert.Equal(t, "/datahub", sharing["basePath"])
+
+	for path := range management["paths"].(map[string]interface{}) {
+		assert.False(t, strings.HasPrefix(path, "/api/v1/share"), "管理API文档不包含数据访问接口: %s", path)
+	}
+	sharingPaths := sharing["paths"].(map[string]interface{})
+	require.NotEmpty(t, sharingPaths)
+	for path := range sharingPaths {
+		assert.True(t, strings.HasPrefix(path, "/api/v1/share"), "数据共享API文档只包含数据访问接口: %s", path)
+	}
+	assert.Equal(t, management, getDoc(t, router, "/datahub/swagger/doc.json", nil), "兼容旧的文档地址")
+
+	// 网关转发前缀
+	forwarded := getDoc(t, router, "/datahub/swagger/sharing.json", http.Header{"X-Forwarded-Prefix": {"/gateway/"}})
+	assert.Equal(t, "/gateway/datahub", forwarded["basePath"])
+
+	// 环境变量配置优先
+	t.Setenv("SWAGGER_SHARING_BASE_PATH", "/open-api")
+	t.Setenv("SWAGGER_HOST", "data.park.example.com")
+	t.Setenv("SWAGGER_SCHEMES", "https")
+	configured := getDoc(t, router, "/datahub/swagger/sharing.json", http.Header{"X-Forwarded-Prefix": {"/gateway"}})
+	assert.Equal(t, "/open-api", configured["basePath"])
+	assert.Equal(t, "data.park.example.com", configured["host"])
+	assert.Equal(t, []interface{}{"https"}, configured["schemes"])
+	assert.Equal(t, "/gateway/datahub", getDoc(t, router, "/datahub/swagger/management.json", http.Header{"X-Forwarded-Prefix": {"/gateway"}})["basePath"])
+
+	req := httptest.NewRequest(http.MethodGet, "/datahub/swagger/index.html", nil)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `url: "sharing.json"`, "文档页面可切换到数据共享API")
+}
+
+func TestSwaggerBasePath(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/swagger/doc.json", nil)
+	assert.Equal(t, "/", swaggerBasePath(req, "", ""))
+	assert.Equal(t, "/datahub", swaggerBasePath(req, "", "/datahub/"))
+	assert.Equal(t, "/custom", swaggerBasePath(req, "/custom", "/datahub"))
+}
@@ -1,19741 +0,0 @@
-// Package docs Code generated by swaggo/swag. DO NOT EDIT
-package docs
-
-import "github.com/swaggo/swag"
-
-const docTemplate = `{
-    "schemes": {{ marshal .Schemes }},
-    "swagger": "2.0",
-    "info": {
-        "description": "{{escape .Description}}",
-        "title": "{{.Title}}",
-        "contact": {},
-        "version": "{{.Version}}"
-    },
-    "host": "{{.Host}}",
-    "basePath": "{{.BasePath}}",
-    "paths": {
-        "/api/v1/share/": {
-            "get": {
-                "description": "根据API Key获取该Key所属的API应用详细信息以及该应用下的所有接口信息，包括主题接口的字段定义",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "数据共享服务"
-                ],
-                "summary": "通过API Key获取API应用信息和接口列表",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Bearer Token格式的API Key",
-                        "name": "Authorization",
-                        "in": "header",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "获取成功",
-                        "schema": {}
-                    },
-                    "401": {
-                        "description": "未授权",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "404": {
-                        "description": "应用不存在",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "服务器内部错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/api/v1/share/{app_path}/{interface_path}": {
-            "get": {
-                "description": "代理对PostgREST的查询请求，实现统一的鉴权、日志、限流和路由功能，仅支持数据查询操作",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "数据共享服务"
-                ],
-                "summary": "数据访问代理（只读查询）",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "应用路径",
-                        "name": "app_path",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "接口路径",
-                        "name": "interface_path",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Bearer Token格式的API Key",
-                        "name": "Authorization",
-                        "in": "header",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "查询成功",
-                        "schema": {}
-                    },
-                    "401": {
-                        "description": "未授权",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "404": {
-                        "description": "资源不存在",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "429": {
-                        "description": "请求过于频繁",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "服务器内部错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            },
-            "head": {
-                "description": "代理对PostgREST的查询请求，实现统一的鉴权、日志、限流和路由功能，仅支持数据查询操作",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "数据共享服务"
-                ],
-                "summary": "数据访问代理（只读查询）",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "应用路径",
-                        "name": "app_path",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "接口路径",
-                        "name": "interface_path",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Bearer Token格式的API Key",
-                        "name": "Authorization",
-                        "in": "header",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "查询成功",
-                        "schema": {}
-                    },
-                    "401": {
-                        "description": "未授权",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "404": {
-                        "description": "资源不存在",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "429": {
-                        "description": "请求过于频繁",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "服务器内部错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/api/v1/{app_path}": {
-            "get": {
-                "description": "根据应用路径获取API应用的详细信息以及该应用下的所有接口信息，包括主题接口的字段定义",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "数据共享服务"
-                ],
-                "summary": "获取API应用信息和接口列表",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "应用路径",
-                        "name": "app_path",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Bearer Token格式的API Key",
-                        "name": "Authorization",
-                        "in": "header",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "获取成功",
-                        "schema": {}
-                    },
-                    "401": {
-                        "description": "未授权",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "404": {
-                        "description": "应用不存在",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "服务器内部错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/basic-libraries": {
-            "get": {
-                "description": "分页获取数据基础库列表，支持多种过滤条件",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "数据基础库"
-                ],
-                "summary": "获取数据基础库列表",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "default": 1,
-                        "description": "页码",
-                        "name": "page",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "default": 10,
-                        "description": "每页大小",
-                        "name": "size",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "名称搜索（支持中英文）",
-                        "name": "name",
-                        "in": "query"
-                    },
-                    {
-                        "enum": [
-                            "active",
-                            "inactive"
-                        ],
-                        "type": "string",
-                        "description": "状态过滤",
-                        "name": "status",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "创建者过滤",
-                        "name": "created_by",
-                        "in": "query"
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "获取成功",
-                        "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/controllers.APIResponse"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "$ref": "#/definitions/controllers.BasicLibraryListResponse"
-                                        }
-                                    }
-                                }
-                            ]
-                        }
-                    },
-                    "400": {
-                        "description": "请求参数错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "服务器内部错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/basic-libraries/add-basic-library": {
-            "post": {
-                "description": "添加数据基础库",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "数据基础库"
-                ],
-                "summary": "添加数据基础库",
-                "parameters": [
-                    {
-                        "description": "数据基础库请求",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.BasicLibrary"
-                        }
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/basic-libraries/add-datasource": {
-            "post": {
-                "description": "添加数据源",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "数据基础库"
-                ],
-                "summary": "添加数据源",
-                "parameters": [
-                    {
-                        "description": "数据源请求",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.DataSource"
-                        }
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/basic-libraries/add-interface": {
-            "post": {
-                "description": "添加数据接口",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "数据基础库"
-                ],
-                "summary": "添加数据接口",
-                "parameters": [
-                    {
-                        "description": "数据接口请求",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.DataInterface"
-                        }
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/basic-libraries/datasource-manager-stats": {
-            "get": {
-                "description": "获取数据源管理器的运行统计信息，包括总数、类型分布、在线状态等",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "数据基础库"
-                ],
-                "summary": "获取数据源管理器统计信息",
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/basic-libraries/datasource-status/{id}": {
-            "get": {
-                "description": "获取数据源的连接状态、最近同步时间等信息",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "数据基础库"
-                ],
-                "summary": "获取数据源运行状态",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "数据源ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/basic-libraries/datasources": {
-            "get": {
-                "description": "分页获取数据源列表，支持多种过滤条件",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "数据基础库"
-                ],
-                "summary": "获取数据源列表",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "default": 1,
-                        "description": "页码",
-                        "name": "page",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "default": 10,
-                        "description": "每页大小",
-                        "name": "size",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "基础库ID过滤",
-                        "name": "library_id",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "数据源分类过滤（如：stream, http, db, file等）",
-                        "name": "category",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "数据源类型过滤（如：mysql, postgresql, http等）",
-                        "name": "type",
-                        "in": "query"
-                    },
-                    {
-                        "enum": [
-                            "active",
-                            "inactive"
-                        ],
-                        "type": "string",
-                        "description": "状态过滤",
-                        "name": "status",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "名称搜索",
-                        "name": "name",
-                        "in": "query"
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "获取成功",
-                        "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/controllers.APIResponse"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "$ref": "#/definitions/controllers.DataSourceListResponse"
-                                        }
-                                    }
-                                }
-                            ]
-                        }
-                    },
-                    "400": {
-                        "description": "请求参数错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "服务器内部错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/basic-libraries/datasources/{id}": {
-            "delete": {
-                "description": "删除数据源",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "数据基础库"
-                ],
-                "summary": "删除数据源",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "数据源ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/basic-libraries/health-check-all": {
-            "post": {
-                "description": "对管理器中的所有数据源进行健康检查",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "数据基础库"
-                ],
-                "summary": "健康检查所有数据源",
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/basic-libraries/import-csv": {
-            "post": {
-                "description": "将CSV数据导入到指定接口的数据表中，CSV第一行为字段名（name_en）",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "数据基础库"
-                ],
-                "summary": "导入CSV数据",
-                "parameters": [
-                    {
-                        "description": "CSV导入请求",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/controllers.ImportCSVRequest"
-                        }
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/controllers.APIResponse"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "$ref": "#/definitions/controllers.ImportCSVResponse"
-                                        }
-                                    }
-                                }
-                            ]
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/basic-libraries/interface-preview/{id}": {
-            "get": {
-                "description": "获取接口的样例数据用于预览",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "数据基础库"
-                ],
-                "summary": "预览接口数据",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "接口ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "default": 10,
-                        "description": "数据条数",
-                        "name": "limit",
-                        "in": "query"
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/basic-libraries/interfaces": {
-            "get": {
-                "description": "分页获取数据接口列表，支持多种过滤条件",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "数据基础库"
-                ],
-                "summary": "获取数据接口列表",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "default": 1,
-                        "description": "页码",
-                        "name": "page",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "default": 10,
-                        "description": "每页大小",
-                        "name": "size",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "基础库ID过滤",
-                        "name": "library_id",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "数据源ID过滤",
-                        "name": "data_source_id",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "接口类型过滤（如：realtime, batch）",
-                        "name": "interface_type",
-                        "in": "query"
-                    },
-                    {
-                        "enum": [
-                            "active",
-                            "inactive"
-                        ],
-                        "type": "string",
-                        "description": "状态过滤",
-                        "name": "status",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "名称搜索",
-                        "name": "name",
-                        "in": "query"
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "获取成功",
-                        "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/controllers.APIResponse"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "$ref": "#/definitions/controllers.DataInterfaceListResponse"
-                                        }
-                                    }
-                                }
-                            ]
-                        }
-                    },
-                    "400": {
-                        "description": "请求参数错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "服务器内部错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/basic-libraries/interfaces/create-table-index": {
-            "post": {
-                "description": "为接口对应的数据表创建索引，支持普通索引、唯一索引，支持多种索引类型（btree、hash、gin、gist等）",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "数据基础库"
-                ],
-                "summary": "创建接口表索引",
-                "parameters": [
-                    {
-                        "description": "创建索引请求",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/controllers.CreateInterfaceTableIndexRequest"
-                        }
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "创建成功",
-                        "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/controllers.APIResponse"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "type": "object"
-                                        }
-                                    }
-                                }
-                            ]
-                        }
-                    },
-                    "400": {
-                        "description": "请求参数错误或表未创建",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "服务器内部错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/basic-libraries/interfaces/drop-table-index": {
-            "post": {
-                "description": "删除接口对应数据表的指定索引",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "数据基础库"
-                ],
-                "summary": "删除接口表索引",
-                "parameters": [
-                    {
-                        "description": "删除索引请求",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/controllers.DropInterfaceTableIndexRequest"
-                        }
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "删除成功",
-                        "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/controllers.APIResponse"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "type": "object"
-                                        }
-                                    }
-                                }
-                            ]
-                        }
-                    },
-                    "400": {
-                        "description": "请求参数错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "服务器内部错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/basic-libraries/interfaces/{id}": {
-            "get": {
-                "description": "根据ID获取数据接口详细信息，自动同步数据库实际字段到配置",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "数据基础库"
-                ],
-                "summary": "获取数据接口详情",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "接口ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "获取成功",
-                        "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/controllers.APIResponse"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "$ref": "#/definitions/models.DataInterface"
-                                        }
-                                    }
-                                }
-                            ]
-                        }
-                    },
-                    "400": {
-                        "description": "请求参数错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "404": {
-                        "description": "接口不存在",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "服务器内部错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            },
-            "delete": {
-                "description": "删除数据接口",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "数据基础库"
-                ],
-                "summary": "删除数据接口",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "数据接口ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/basic-libraries/interfaces/{id}/table-columns": {
-            "get": {
-                "description": "从数据库动态获取接口对应表的字段信息，包含字段名、类型、约束等完整信息",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "数据基础库"
-                ],
-                "summary": "获取接口表字段",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "接口ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "返回表字段列表",
-                        "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/controllers.APIResponse"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "type": "array",
-                                            "items": {
-                                                "$ref": "#/definitions/database.ColumnDefinition"
-                                            }
-                                        }
-                                    }
-                                }
-                            ]
-                        }
-                    },
-                    "400": {
-                        "description": "请求参数错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "服务器内部错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/basic-libraries/interfaces/{id}/table-indexes": {
-            "get": {
-                "description": "从数据库获取接口对应表的索引信息，包含索引名称、类型、包含的列等",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "数据基础库"
-                ],
-                "summary": "获取接口表索引",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "接口ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "返回索引列表",
-                        "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/controllers.APIResponse"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "type": "array",
-                                            "items": {
-                                                "$ref": "#/definitions/database.IndexDefinition"
-                                            }
-                                        }
-                                    }
-                                }
-                            ]
-                        }
-                    },
-                    "400": {
-                        "description": "请求参数错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "服务器内部错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/basic-libraries/reload-datasource/{id}": {
-            "post": {
-                "description": "重新从数据库加载数据源配置并更新管理器中的实例",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "数据基础库"
-                ],
-                "summary": "重新加载数据源",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "数据源ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/basic-libraries/resident-datasources": {
-            "get": {
-                "description": "获取所有常驻数据源的运行状态和统计信息",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "数据基础库"
-                ],
-                "summary": "获取常驻数据源状态",
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/basic-libraries/restart-resident-datasource/{id}": {
-            "post": {
-                "description": "重启指定的常驻数据源",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "数据基础库"
-                ],
-                "summary": "重启常驻数据源",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "数据源ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/basic-libraries/test-datasource": {
-            "post": {
-                "description": "测试数据源连接和数据获取能力",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "数据基础库"
-                ],
-                "summary": "测试数据源连接",
-                "parameters": [
-                    {
-                        "description": "测试请求",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/controllers.DataSourceTestRequest"
-                        }
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/controllers.APIResponse"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "$ref": "#/definitions/controllers.DataSourceTestResponse"
-                                        }
-                                    }
-                                }
-                            ]
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/basic-libraries/test-interface": {
-            "post": {
-                "description": "测试数据接口的调用和数据获取能力",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "数据基础库"
-                ],
-                "summary": "测试接口调用",
-                "parameters": [
-                    {
-                        "description": "测试请求",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/controllers.InterfaceTestRequest"
-                        }
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/controllers.APIResponse"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "$ref": "#/definitions/controllers.InterfaceTestResponse"
-                                        }
-                                    }
-                                }
-                            ]
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/basic-libraries/update-basic-library": {
-            "post": {
-                "description": "修改数据基础库信息",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "数据基础库"
-                ],
-                "summary": "修改数据基础库",
-                "parameters": [
-                    {
-                        "description": "修改数据基础库请求",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/controllers.UpdateBasicLibraryRequest"
-                        }
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/basic-libraries/update-datasource": {
-            "post": {
-                "description": "修改数据源信息",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "数据基础库"
-                ],
-                "summary": "修改数据源",
-                "parameters": [
-                    {
-                        "description": "修改数据源请求",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/controllers.UpdateDataSourceRequest"
-                        }
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/basic-libraries/update-interface": {
-            "post": {
-                "description": "修改数据接口信息",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "数据基础库"
-                ],
-                "summary": "修改数据接口",
-                "parameters": [
-                    {
-                        "description": "修改数据接口请求",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/controllers.UpdateDataInterfaceRequest"
-                        }
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/basic-libraries/update-interface-fields": {
-            "post": {
-                "description": "更新数据接口的字段配置，并可选择同时更新数据库表结构",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "数据基础库"
-                ],
-                "summary": "更新接口字段配置",
-                "parameters": [
-                    {
-                        "description": "更新字段配置请求",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/controllers.UpdateInterfaceFieldsRequest"
-                        }
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/basic-libraries/{id}": {
-            "delete": {
-                "description": "删除数据基础库",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "数据基础库"
-                ],
-                "summary": "删除数据基础库",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "数据基础库ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/config": {
-            "get": {
-                "description": "获取系统所有配置项",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "系统配置"
-                ],
-                "summary": "获取所有系统配置",
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": true
-                        }
-                    }
-                }
-            }
-        },
-        "/config/batch": {
-            "post": {
-                "description": "批量更新多个配置项",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "系统配置"
-                ],
-                "summary": "批量更新配置",
-                "parameters": [
-                    {
-                        "description": "批量更新配置请求",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/controllers.BatchUpdateConfigsRequest"
-                        }
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": true
-                        }
-                    }
-                }
-            }
-        },
-        "/config/{key}": {
-            "get": {
-                "description": "根据键名获取配置值",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "系统配置"
-                ],
-                "summary": "获取单个配置",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "配置键",
-                        "name": "key",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": true
-                        }
-                    }
-                }
-            },
-            "put": {
-                "description": "更新指定键的配置值",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "系统配置"
-                ],
-                "summary": "更新配置",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "配置键",
-                        "name": "key",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "更新配置请求",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/controllers.UpdateConfigRequest"
-                        }
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": true
-                        }
-                    }
-                }
-            }
-        },
-        "/dashboard/basic-library-stats": {
-            "get": {
-                "description": "获取基础库的详细统计信息",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Dashboard"
-                ],
-                "summary": "获取基础库统计数据",
-                "responses": {
-                    "200": {
-                        "description": "获取成功",
-                        "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/controllers.APIResponse"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "$ref": "#/definitions/controllers.BasicLibraryStats"
-                                        }
-                                    }
-                                }
-                            ]
-                        }
-                    },
-                    "500": {
-                        "description": "服务器内部错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/dashboard/data-quality-stats": {
-            "get": {
-                "description": "获取数据质量的详细统计信息",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Dashboard"
-                ],
-                "summary": "获取数据质量统计数据",
-                "responses": {
-                    "200": {
-                        "description": "获取成功",
-                        "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/controllers.APIResponse"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "$ref": "#/definitions/controllers.DataQualityStats"
-                                        }
-                                    }
-                                }
-                            ]
-                        }
-                    },
-                    "500": {
-                        "description": "服务器内部错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/dashboard/data-sharing-stats": {
-            "get": {
-                "description": "获取数据共享的详细统计信息",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Dashboard"
-                ],
-                "summary": "获取数据共享统计数据",
-                "responses": {
-                    "200": {
-                        "description": "获取成功",
-                        "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/controllers.APIResponse"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "$ref": "#/definitions/controllers.DataSharingStats"
-                                        }
-                                    }
-                                }
-                            ]
-                        }
-                    },
-                    "500": {
-                        "description": "服务器内部错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/dashboard/overview": {
-            "get": {
-                "description": "获取系统各模块的统计数据和关键指标",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Dashboard"
-                ],
-                "summary": "获取Dashboard总览数据",
-                "responses": {
-                    "200": {
-                        "description": "获取成功",
-                        "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/controllers.APIResponse"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "$ref": "#/definitions/controllers.DashboardOverviewResponse"
-                                        }
-                                    }
-                                }
-                            ]
-                        }
-                    },
-                    "500": {
-                        "description": "服务器内部错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/dashboard/sync-task-stats": {
-            "get": {
-                "description": "获取同步任务的详细统计信息",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Dashboard"
-                ],
-                "summary": "获取同步任务统计数据",
-                "responses": {
-                    "200": {
-                        "description": "获取成功",
-                        "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/controllers.APIResponse"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "$ref": "#/definitions/controllers.SyncTaskStats"
-                                        }
-                                    }
-                                }
-                            ]
-                        }
-                    },
-                    "500": {
-                        "description": "服务器内部错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/dashboard/system-activity-stats": {
-            "get": {
-                "description": "获取系统活动的详细统计信息",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Dashboard"
-                ],
-                "summary": "获取系统活动统计数据",
-                "responses": {
-                    "200": {
-                        "description": "获取成功",
-                        "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/controllers.APIResponse"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "$ref": "#/definitions/controllers.SystemActivityStats"
-                                        }
-                                    }
-                                }
-                            ]
-                        }
-                    },
-                    "500": {
-                        "description": "服务器内部错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/dashboard/thematic-library-stats": {
-            "get": {
-                "description": "获取主题库的详细统计信息",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Dashboard"
-                ],
-                "summary": "获取主题库统计数据",
-                "responses": {
-                    "200": {
-                        "description": "获取成功",
-                        "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/controllers.APIResponse"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "$ref": "#/definitions/controllers.ThematicLibraryStats"
-                                        }
-                                    }
-                                }
-                            ]
-                        }
-                    },
-                    "500": {
-                        "description": "服务器内部错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/data-quality/checks": {
-            "post": {
-                "description": "对指定对象执行数据质量检查并生成报告",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "数据质量"
-                ],
-                "summary": "执行数据质量检查",
-                "parameters": [
-                    {
-                        "description": "质量检查请求",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/governance.RunQualityCheckRequest"
-                        }
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "检查成功",
-                        "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/controllers.APIResponse"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "$ref": "#/definitions/governance.QualityReportResponse"
-                                        }
-                                    }
-                                }
-                            ]
-                        }
-                    },
-                    "400": {
-                        "description": "请求参数错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "服务器内部错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/data-quality/cleansing-rules": {
-            "get": {
-                "description": "分页获取数据清洗规则列表",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "数据质量"
-                ],
-                "summary": "获取数据清洗规则列表",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "default": 1,
-                        "description": "页码",
-                        "name": "page",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "default": 10,
-                        "description": "每页数量",
-                        "name": "size",
-                        "in": "query"
-                    },
-                    {
-                        "enum": [
-                            "standardization",
-                            "deduplication",
-                            "validation",
-                            "transformation",
-                            "enrichment"
-                        ],
-                        "type": "string",
-                        "description": "规则类型",
-                        "name": "rule_type",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "目标表",
-                        "name": "target_table",
-                        "in": "query"
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "获取成功",
-                        "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/controllers.APIResponse"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "$ref": "#/definitions/governance.CleansingRuleListResponse"
-                                        }
-                                    }
-                                }
-                            ]
-                        }
-                    },
-                    "500": {
-                        "description": "服务器内部错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            },
-            "post": {
-                "description": "创建新的数据清洗规则",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "数据质量"
-                ],
-                "summary": "创建数据清洗规则",
-                "parameters": [
-                    {
-                        "description": "数据清洗规则信息",
-                        "name": "rule",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/governance.CreateCleansingRuleRequest"
-                        }
-                    }
-                ],
-                "responses": {
-                    "201": {
-                        "description": "创建成功",
-                        "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/controllers.APIResponse"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "$ref": "#/definitions/governance.CleansingRuleResponse"
-                                        }
-                                    }
-                                }
-                            ]
-                        }
-                    },
-                    "400": {
-                        "description": "请求参数错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "服务器内部错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/data-quality/cleansing-rules/{id}": {
-            "get": {
-                "description": "根据ID获取数据清洗规则详情",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "数据质量"
-                ],
-                "summary": "根据ID获取数据清洗规则",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "规则ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "获取成功",
-                        "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/controllers.APIResponse"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "$ref": "#/definitions/governance.CleansingRuleResponse"
-                                        }
-                                    }
-                                }
-                            ]
-                        }
-                    },
-                    "404": {
-                        "description": "规则不存在",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "服务器内部错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            },
-            "put": {
-                "description": "更新数据清洗规则信息",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "数据质量"
-                ],
-                "summary": "更新数据清洗规则",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "规则ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "更新信息",
-                        "name": "updates",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/governance.UpdateCleansingRuleRequest"
-                        }
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "更新成功",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "400": {
-                        "description": "请求参数错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "404": {
-                        "description": "规则不存在",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "服务器内部错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            },
-            "delete": {
-                "description": "删除指定的数据清洗规则",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "数据质量"
-                ],
-                "summary": "删除数据清洗规则",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "规则ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "删除成功",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "404": {
-                        "description": "规则不存在",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "服务器内部错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/data-quality/data-lineage": {
-            "post": {
-                "description": "创建新的数据血缘关系",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "数据质量"
-                ],
-                "summary": "创建数据血缘关系",
-                "parameters": [
-                    {
-                        "description": "数据血缘信息",
-                        "name": "lineage",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/governance.CreateDataLineageRequest"
-                        }
-                    }
-                ],
-                "responses": {
-                    "201": {
-                        "description": "创建成功",
-                        "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/controllers.APIResponse"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "$ref": "#/definitions/governance.DataLineageResponse"
-                                        }
-                                    }
-                                }
-                            ]
-                        }
-                    },
-                    "400": {
-                        "description": "请求参数错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "服务器内部错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/data-quality/data-lineage/{object_id}": {
-            "get": {
-                "description": "获取指定数据对象的血缘关系图",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "数据质量"
-                ],
-                "summary": "获取数据血缘图",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "数据对象ID",
-                        "name": "object_id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "enum": [
-                            "table",
-                            "interface",
-                            "thematic_interface"
-                        ],
-                        "type": "string",
-                        "description": "对象类型",
-                        "name": "object_type",
-                        "in": "query"
-                    },
-                    {
-                        "enum": [
-                            "upstream",
-                            "downstream",
-                            "both"
-                        ],
-                        "type": "string",
-                        "default": "both",
-                        "description": "血缘方向",
-                        "name": "direction",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "default": 3,
-                        "description": "血缘深度",
-                        "name": "depth",
-                        "in": "query"
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "获取成功",
-                        "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/controllers.APIResponse"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "$ref": "#/definitions/governance.DataLineageGraphResponse"
-                                        }
-                                    }
-                                }
-                            ]
-                        }
-                    },
-                    "500": {
-                        "description": "服务器内部错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/data-quality/issue-records": {
-            "get": {
-                "description": "根据任务ID、执行ID等条件查询质量问题记录列表",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "数据质量"
-                ],
-                "summary": "获取质量问题记录列表",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "任务ID",
-                        "name": "task_id",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "执行ID",
-                        "name": "execution_id",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "字段名",
-                        "name": "field_name",
-                        "in": "query"
-                    },
-                    {
-                        "enum": [
-                            "low",
-                            "medium",
-                            "high",
-                            "critical"
-                        ],
-                        "type": "string",
-                        "description": "严重程度",
-                        "name": "severity",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "default": 1,
-                        "description": "页码",
-                        "name": "page",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "default": 10,
-                        "description": "每页数量",
-                        "name": "size",
-                        "in": "query"
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "获取成功",
-                        "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/controllers.APIResponse"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "$ref": "#/definitions/governance.QualityIssueRecordListResponse"
-                                        }
-                                    }
-                                }
-                            ]
-                        }
-                    },
-                    "500": {
-                        "description": "服务器内部错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/data-quality/masking-rules": {
-            "get": {
-                "description": "分页获取数据脱敏规则列表",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "数据质量"
-                ],
-                "summary": "获取数据脱敏规则列表",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "default": 1,
-                        "description": "页码",
-                        "name": "page",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "default": 10,
-                        "description": "每页数量",
-                        "name": "page_size",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "数据源",
-                        "name": "data_source",
-                        "in": "query"
-                    },
-                    {
-                        "enum": [
-                            "mask",
-                            "replace",
-                            "encrypt",
-                            "pseudonymize"
-                        ],
-                        "type": "string",
-                        "description": "脱敏类型",
-                        "name": "masking_type",
-                        "in": "query"
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "获取成功",
-                        "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/controllers.APIResponse"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "$ref": "#/definitions/governance.MaskingRuleListResponse"
-                                        }
-                                    }
-                                }
-                            ]
-                        }
-                    },
-                    "500": {
-                        "description": "服务器内部错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            },
-            "post": {
-                "description": "创建新的数据脱敏规则",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "数据质量"
-                ],
-                "summary": "创建数据脱敏规则",
-                "parameters": [
-                    {
-                        "description": "数据脱敏规则信息",
-                        "name": "rule",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/governance.CreateMaskingRuleRequest"
-                        }
-                    }
-                ],
-                "responses": {
-                    "201": {
-                        "description": "创建成功",
-                        "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/controllers.APIResponse"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "$ref": "#/definitions/governance.MaskingRuleResponse"
-                                        }
-                                    }
-                                }
-                            ]
-                        }
-                    },
-                    "400": {
-                        "description": "请求参数错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "服务器内部错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/data-quality/masking-rules/{id}": {
-            "get": {
-                "description": "根据ID获取数据脱敏规则详情",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "数据质量"
-                ],
-                "summary": "根据ID获取数据脱敏规则",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "规则ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "获取成功",
-                        "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/controllers.APIResponse"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "$ref": "#/definitions/governance.MaskingRuleResponse"
-                                        }
-                                    }
-                                }
-                            ]
-                        }
-                    },
-                    "404": {
-                        "description": "规则不存在",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "服务器内部错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            },
-            "put": {
-                "description": "更新数据脱敏规则信息",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "数据质量"
-                ],
-                "summary": "更新数据脱敏规则",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "规则ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "更新信息",
-                        "name": "updates",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/governance.UpdateMaskingRuleRequest"
-                        }
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "更新成功",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "400": {
-                        "description": "请求参数错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "404": {
-                        "description": "规则不存在",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "服务器内部错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            },
-            "delete": {
-                "description": "删除指定的数据脱敏规则",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "数据质量"
-                ],
-                "summary": "删除数据脱敏规则",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "规则ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "删除成功",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "404": {
-                        "description": "规则不存在",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "服务器内部错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/data-quality/metadata": {
-            "get": {
-                "description": "分页获取元数据列表",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "数据质量"
-                ],
-                "summary": "获取元数据列表",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "default": 1,
-                        "description": "页码",
-                        "name": "page",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "default": 10,
-                        "description": "每页数量",
-                        "name": "size",
-                        "in": "query"
-                    },
-                    {
-                        "enum": [
-                            "technical",
-                            "business",
-                            "management"
-                        ],
-                        "type": "string",
-                        "description": "元数据类型",
-                        "name": "type",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "元数据名称",
-                        "name": "name",
-                        "in": "query"
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "获取成功",
-                        "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/controllers.APIResponse"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "$ref": "#/definitions/governance.MetadataListResponse"
-                                        }
-                                    }
-                                }
-                            ]
-                        }
-                    },
-                    "500": {
-                        "description": "服务器内部错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            },
-            "post": {
-                "description": "创建新的元数据",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "数据质量"
-                ],
-                "summary": "创建元数据",
-                "parameters": [
-                    {
-                        "description": "元数据信息",
-                        "name": "metadata",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/governance.CreateMetadataRequest"
-                        }
-                    }
-                ],
-                "responses": {
-                    "201": {
-                        "description": "创建成功",
-                        "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/controllers.APIResponse"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "$ref": "#/definitions/governance.MetadataResponse"
-                                        }
-                                    }
-                                }
-                            ]
-                        }
-                    },
-                    "400": {
-                        "description": "请求参数错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "服务器内部错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/data-quality/metadata/{id}": {
-            "get": {
-                "description": "根据ID获取元数据详情",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "数据质量"
-                ],
-                "summary": "根据ID获取元数据",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "元数据ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "获取成功",
-                        "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/controllers.APIResponse"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "$ref": "#/definitions/governance.MetadataResponse"
-                                        }
-                                    }
-                                }
-                            ]
-                        }
-                    },
-                    "404": {
-                        "description": "元数据不存在",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "服务器内部错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            },
-            "put": {
-                "description": "更新元数据信息",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "数据质量"
-                ],
-                "summary": "更新元数据",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "元数据ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "更新信息",
-                        "name": "updates",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/governance.UpdateMetadataRequest"
-                        }
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "更新成功",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "400": {
-                        "description": "请求参数错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "404": {
-                        "description": "元数据不存在",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "服务器内部错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            },
-            "delete": {
-                "description": "删除指定的元数据",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "数据质量"
-                ],
-                "summary": "删除元数据",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "元数据ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "删除成功",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "404": {
-                        "description": "元数据不存在",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "服务器内部错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/data-quality/reports": {
-            "get": {
-                "description": "分页获取数据质量报告列表",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "数据质量"
-                ],
-                "summary": "获取数据质量报告列表",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "default": 1,
-                        "description": "页码",
-                        "name": "page",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "default": 10,
-                        "description": "每页数量",
-                        "name": "page_size",
-                        "in": "query"
-                    },
-                    {
-                        "enum": [
-                            "interface",
-                            "thematic_interface"
-                        ],
-                        "type": "string",
-                        "description": "对象类型",
-                        "name": "object_type",
-                        "in": "query"
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "获取成功",
-                        "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/controllers.APIResponse"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "$ref": "#/definitions/governance.QualityReportListResponse"
-                                        }
-                                    }
-                                }
-                            ]
-                        }
-                    },
-                    "500": {
-                        "description": "服务器内部错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/data-quality/reports/{id}": {
-            "get": {
-                "description": "根据ID获取数据质量报告详情",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "数据质量"
-                ],
-                "summary": "根据ID获取数据质量报告",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "报告ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "获取成功",
-                        "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/controllers.APIResponse"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "$ref": "#/definitions/governance.QualityReportResponse"
-                                        }
-                                    }
-                                }
-                            ]
-                        }
-                    },
-                    "404": {
-                        "description": "报告不存在",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "服务器内部错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/data-quality/rules": {
-            "get": {
-                "description": "分页获取数据质量规则列表",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "数据质量"
-                ],
-                "summary": "获取数据质量规则列表",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "default": 1,
-                        "description": "页码",
-                        "name": "page",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "default": 10,
-                        "description": "每页数量",
-                        "name": "size",
-                        "in": "query"
-                    },
-                    {
-                        "enum": [
-                            "completeness",
-                            "accuracy",
-                            "consistency",
-                            "validity",
-                            "uniqueness",
-                            "timeliness",
-                            "standardization"
-                        ],
-                        "type": "string",
-                        "description": "规则类型",
-                        "name": "type",
-                        "in": "query"
-                    },
-                    {
-                        "enum": [
-                            "interface",
-                            "thematic_interface"
-                        ],
-                        "type": "string",
-                        "description": "关联对象类型",
-                        "name": "object_type",
-                        "in": "query"
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "获取成功",
-                        "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/controllers.APIResponse"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "$ref": "#/definitions/governance.QualityRuleListResponse"
-                                        }
-                                    }
-                                }
-                            ]
-                        }
-                    },
-                    "500": {
-                        "description": "服务器内部错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            },
-            "post": {
-                "description": "创建新的数据质量规则",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "数据质量"
-                ],
-                "summary": "创建数据质量规则",
-                "parameters": [
-                    {
-                        "description": "数据质量规则信息",
-                        "name": "rule",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/governance.CreateQualityRuleRequest"
-                        }
-                    }
-                ],
-                "responses": {
-                    "201": {
-                        "description": "创建成功",
-                        "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/controllers.APIResponse"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "$ref": "#/definitions/governance.QualityRuleResponse"
-                                        }
-                                    }
-                                }
-                            ]
-                        }
-                    },
-                    "400": {
-                        "description": "请求参数错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "服务器内部错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/data-quality/rules/{id}": {
-            "get": {
-                "description": "根据ID获取数据质量规则详情",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "数据质量"
-                ],
-                "summary": "根据ID获取数据质量规则",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "规则ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "获取成功",
-                        "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/controllers.APIResponse"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "$ref": "#/definitions/governance.QualityRuleResponse"
-                                        }
-                                    }
-                                }
-                            ]
-                        }
-                    },
-                    "404": {
-                        "description": "规则不存在",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "服务器内部错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            },
-            "put": {
-                "description": "更新数据质量规则信息",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "数据质量"
-                ],
-                "summary": "更新数据质量规则",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "规则ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "更新信息",
-                        "name": "updates",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/governance.UpdateQualityRuleRequest"
-                        }
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "更新成功",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "400": {
-                        "description": "请求参数错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "404": {
-                        "description": "规则不存在",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "服务器内部错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            },
-            "delete": {
-                "description": "删除指定的数据质量规则",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "数据质量"
-                ],
-                "summary": "删除数据质量规则",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "规则ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "删除成功",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "404": {
-                        "description": "规则不存在",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "服务器内部错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/data-quality/system-logs": {
-            "get": {
-                "description": "分页获取系统日志列表",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "数据质量"
-                ],
-                "summary": "获取系统日志列表",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "default": 1,
-                        "description": "页码",
-                        "name": "page",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "default": 10,
-                        "description": "每页数量",
-                        "name": "page_size",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "操作类型",
-                        "name": "operation_type",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "对象类型",
-                        "name": "object_type",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "format": "date-time",
-                        "description": "开始时间",
-                        "name": "start_time",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "format": "date-time",
-                        "description": "结束时间",
-                        "name": "end_time",
-                        "in": "query"
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "获取成功",
-                        "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/controllers.APIResponse"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "$ref": "#/definitions/governance.SystemLogListResponse"
-                                        }
-                                    }
-                                }
-                            ]
-                        }
-                    },
-                    "500": {
-                        "description": "服务器内部错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/data-quality/tasks": {
-            "get": {
-                "description": "分页获取数据质量检测任务列表",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "数据质量"
-                ],
-                "summary": "获取数据质量检测任务列表",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "default": 1,
-                        "description": "页码",
-                        "name": "page",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "default": 10,
-                        "description": "每页数量",
-                        "name": "size",
-                        "in": "query"
-                    },
-                    {
-                        "enum": [
-                            "pending",
-                            "running",
-                            "completed",
-                            "failed",
-                            "cancelled"
-                        ],
-                        "type": "string",
-                        "description": "任务状态",
-                        "name": "status",
-                        "in": "query"
-                    },
-                    {
-                        "enum": [
-                            "thematic",
-                            "basic"
-                        ],
-                        "type": "string",
-                        "description": "库类型",
-                        "name": "library_type",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "库ID",
-                        "name": "library_id",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "接口ID",
-                        "name": "interface_id",
-                        "in": "query"
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "获取成功",
-                        "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/controllers.APIResponse"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "$ref": "#/definitions/governance.QualityTaskListResponse"
-                                        }
-                                    }
-                                }
-                            ]
-                        }
-                    },
-                    "500": {
-                        "description": "服务器内部错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            },
-            "post": {
-                "description": "创建新的数据质量检测任务",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "数据质量"
-                ],
-                "summary": "创建数据质量检测任务",
-                "parameters": [
-                    {
-                        "description": "质量检测任务信息",
-                        "name": "task",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/governance.CreateQualityTaskRequest"
-                        }
-                    }
-                ],
-                "responses": {
-                    "201": {
-                        "description": "创建成功",
-                        "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/controllers.APIResponse"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "$ref": "#/definitions/governance.QualityTaskResponse"
-                                        }
-                                    }
-                                }
-                            ]
-                        }
-                    },
-                    "400": {
-                        "description": "请求参数错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "服务器内部错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/data-quality/tasks/{id}": {
-            "get": {
-                "description": "根据ID获取数据质量检测任务详情",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "数据质量"
-                ],
-                "summary": "根据ID获取数据质量检测任务",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "任务ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "获取成功",
-                        "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/controllers.APIResponse"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "$ref": "#/definitions/governance.QualityTaskResponse"
-                                        }
-                                    }
-                                }
-                            ]
-                        }
-                    },
-                    "404": {
-                        "description": "任务不存在",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "服务器内部错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            },
-            "put": {
-                "description": "更新数据质量检测任务信息",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "数据质量"
-                ],
-                "summary": "更新数据质量检测任务",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "任务ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "更新信息",
-                        "name": "updates",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/governance.UpdateQualityTaskRequest"
-                        }
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "更新成功",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "400": {
-                        "description": "请求参数错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "404": {
-                        "description": "任务不存在",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "服务器内部错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            },
-            "delete": {
-                "description": "删除指定的数据质量检测任务及其相关执行记录",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "数据质量"
-                ],
-                "summary": "删除数据质量检测任务",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "任务ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "删除成功",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "404": {
-                        "description": "任务不存在",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "服务器内部错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/data-quality/tasks/{id}/executions": {
-            "get": {
-                "description": "获取指定任务的执行记录列表",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "数据质量"
-                ],
-                "summary": "获取数据质量检测任务执行记录",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "任务ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "default": 1,
-                        "description": "页码",
-                        "name": "page",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "default": 10,
-                        "description": "每页数量",
-                        "name": "size",
-                        "in": "query"
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "获取成功",
-                        "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/controllers.APIResponse"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "$ref": "#/definitions/governance.QualityTaskExecutionListResponse"
-                                        }
-                                    }
-                                }
-                            ]
-                        }
-                    },
-                    "500": {
-                        "description": "服务器内部错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/data-quality/tasks/{id}/issue-records": {
-            "get": {
-                "description": "获取指定质量检测任务的所有问题记录",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "数据质量"
-                ],
-                "summary": "获取指定任务的问题记录",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "任务ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "执行ID",
-                        "name": "execution_id",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "字段名",
-                        "name": "field_name",
-                        "in": "query"
-                    },
-                    {
-                        "enum": [
-                            "low",
-                            "medium",
-                            "high",
-                            "critical"
-                        ],
-                        "type": "string",
-                        "description": "严重程度",
-                        "name": "severity",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "default": 1,
-                        "description": "页码",
-                        "name": "page",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "default": 10,
-                        "description": "每页数量",
-                        "name": "size",
-                        "in": "query"
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "获取成功",
-                        "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/controllers.APIResponse"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "$ref": "#/definitions/governance.QualityIssueRecordListResponse"
-                                        }
-                                    }
-                                }
-                            ]
-                        }
-                    },
-                    "500": {
-                        "description": "服务器内部错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/data-quality/tasks/{id}/start": {
-            "post": {
-                "description": "手动启动指定的数据质量检测任务",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "数据质量"
-                ],
-                "summary": "启动数据质量检测任务",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "任务ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "启动成功",
-                        "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/controllers.APIResponse"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "$ref": "#/definitions/governance.QualityTaskExecutionResponse"
-                                        }
-                                    }
-                                }
-                            ]
-                        }
-                    },
-                    "404": {
-                        "description": "任务不存在",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "服务器内部错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/data-quality/tasks/{id}/stop": {
-            "post": {
-                "description": "停止正在运行的数据质量检测任务",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "数据质量"
-                ],
-                "summary": "停止数据质量检测任务",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "任务ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "停止成功",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "404": {
-                        "description": "任务不存在",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "服务器内部错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/data-quality/templates/cleansing-rules": {
-            "get": {
-                "description": "分页获取数据清洗模板列表，包括内置模板",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "数据质量"
-                ],
-                "summary": "获取数据清洗模板列表",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "default": 1,
-                        "description": "页码",
-                        "name": "page",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "default": 10,
-                        "description": "每页数量",
-                        "name": "size",
-                        "in": "query"
-                    },
-                    {
-                        "enum": [
-                            "standardization",
-                            "deduplication",
-                            "validation",
-                            "transformation",
-                            "enrichment"
-                        ],
-                        "type": "string",
-                        "description": "规则类型",
-                        "name": "rule_type",
-                        "in": "query"
-                    },
-                    {
-                        "enum": [
-                            "data_format",
-                            "data_quality",
-                            "data_integrity"
-                        ],
-                        "type": "string",
-                        "description": "分类",
-                        "name": "category",
-                        "in": "query"
-                    },
-                    {
-                        "type": "boolean",
-                        "description": "是否为内置模板",
-                        "name": "is_built_in",
-                        "in": "query"
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "获取成功",
-                        "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/controllers.APIResponse"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "$ref": "#/definitions/governance.DataCleansingTemplateListResponse"
-                                        }
-                                    }
-                                }
-                            ]
-                        }
-                    },
-                    "500": {
-                        "description": "服务器内部错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/data-quality/templates/masking-rules": {
-            "get": {
-                "description": "分页获取数据脱敏模板列表，包括内置模板",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "数据质量"
-                ],
-                "summary": "获取数据脱敏模板列表",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "default": 1,
-                        "description": "页码",
-                        "name": "page",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "default": 10,
-                        "description": "每页数量",
-                        "name": "size",
-                        "in": "query"
-                    },
-                    {
-                        "enum": [
-                            "mask",
-                            "replace",
-                            "encrypt",
-                            "pseudonymize"
-                        ],
-                        "type": "string",
-                        "description": "脱敏类型",
-                        "name": "masking_type",
-                        "in": "query"
-                    },
-                    {
-                        "enum": [
-                            "personal_info",
-                            "financial",
-                            "medical",
-                            "business",
-                            "custom"
-                        ],
-                        "type": "string",
-                        "description": "分类",
-                        "name": "category",
-                        "in": "query"
-                    },
-                    {
-                        "enum": [
-                            "low",
-                            "medium",
-                            "high",
-                            "critical"
-                        ],
-                        "type": "string",
-                        "description": "安全级别",
-                        "name": "security_level",
-                        "in": "query"
-                    },
-                    {
-                        "type": "boolean",
-                        "description": "是否为内置模板",
-                        "name": "is_built_in",
-                        "in": "query"
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "获取成功",
-                        "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/controllers.APIResponse"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "$ref": "#/definitions/governance.DataMaskingTemplateListResponse"
-                                        }
-                                    }
-                                }
-                            ]
-                        }
-                    },
-                    "500": {
-                        "description": "服务器内部错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/data-quality/templates/quality-rules": {
-            "get": {
-                "description": "分页获取数据质量规则模板列表，包括内置模板",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "数据质量"
-                ],
-                "summary": "获取数据质量规则模板列表",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "default": 1,
-                        "description": "页码",
-                        "name": "page",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "default": 10,
-                        "description": "每页数量",
-                        "name": "size",
-                        "in": "query"
-                    },
-                    {
-                        "enum": [
-                            "completeness",
-                            "accuracy",
-                            "consistency",
-                            "validity",
-                            "uniqueness",
-                            "timeliness",
-                            "standardization"
-                        ],
-                        "type": "string",
-                        "description": "规则类型",
-                        "name": "rule_type",
-                        "in": "query"
-                    },
-                    {
-                        "enum": [
-                            "basic_quality",
-                            "data_cleansing",
-                            "data_validation"
-                        ],
-                        "type": "string",
-                        "description": "分类",
-                        "name": "category",
-                        "in": "query"
-                    },
-                    {
-                        "type": "boolean",
-                        "description": "是否为内置模板",
-                        "name": "is_built_in",
-                        "in": "query"
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "获取成功",
-                        "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/controllers.APIResponse"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "$ref": "#/definitions/governance.QualityRuleTemplateListResponse"
-                                        }
-                                    }
-                                }
-                            ]
-                        }
-                    },
-                    "500": {
-                        "description": "服务器内部错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/data-quality/test/batch-rules": {
-            "post": {
-                "description": "批量测试质量规则、脱敏规则和清洗规则的组合执行效果",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "数据质量"
-                ],
-                "summary": "批量测试多个规则",
-                "parameters": [
-                    {
-                        "description": "批量规则测试请求",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/governance.TestBatchRulesRequest"
-                        }
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "测试成功",
-                        "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/controllers.APIResponse"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "$ref": "#/definitions/governance.TestRuleResponse"
-                                        }
-                                    }
-                                }
-                            ]
-                        }
-                    },
-                    "400": {
-                        "description": "请求参数错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "服务器内部错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/data-quality/test/cleansing-rule": {
-            "post": {
-                "description": "使用测试数据验证数据清洗规则的执行效果",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "数据质量"
-                ],
-                "summary": "测试数据清洗规则",
-                "parameters": [
-                    {
-                        "description": "清洗规则测试请求",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/governance.TestCleansingRuleRequest"
-                        }
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "测试成功",
-                        "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/controllers.APIResponse"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "$ref": "#/definitions/governance.TestRuleResponse"
-                                        }
-                                    }
-                                }
-                            ]
-                        }
-                    },
-                    "400": {
-                        "description": "请求参数错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "服务器内部错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/data-quality/test/masking-rule": {
-            "post": {
-                "description": "使用测试数据验证数据脱敏规则的执行效果",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "数据质量"
-                ],
-                "summary": "测试数据脱敏规则",
-                "parameters": [
-                    {
-                        "description": "脱敏规则测试请求",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/governance.TestMaskingRuleRequest"
-                        }
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "测试成功",
-                        "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/controllers.APIResponse"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "$ref": "#/definitions/governance.TestRuleResponse"
-                                        }
-                                    }
-                                }
-                            ]
-                        }
-                    },
-                    "400": {
-                        "description": "请求参数错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "服务器内部错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/data-quality/test/quality-rule": {
-            "post": {
-                "description": "使用测试数据验证数据质量规则的执行效果",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "数据质量"
-                ],
-                "summary": "测试数据质量规则",
-                "parameters": [
-                    {
-                        "description": "质量规则测试请求",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/governance.TestQualityRuleRequest"
-                        }
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "测试成功",
-                        "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/controllers.APIResponse"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "$ref": "#/definitions/governance.TestRuleResponse"
-                                        }
-                                    }
-                                }
-                            ]
-                        }
-                    },
-                    "400": {
-                        "description": "请求参数错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "服务器内部错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/data-quality/test/rule-preview": {
-            "post": {
-                "description": "预览规则执行效果，不实际执行规则，仅显示预期变化",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "数据质量"
-                ],
-                "summary": "预览规则执行效果",
-                "parameters": [
-                    {
-                        "description": "规则预览请求",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/governance.TestRulePreviewRequest"
-                        }
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "预览成功",
-                        "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/controllers.APIResponse"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "$ref": "#/definitions/governance.TestRulePreviewResponse"
-                                        }
-                                    }
-                                }
-                            ]
-                        }
-                    },
-                    "400": {
-                        "description": "请求参数错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "服务器内部错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/data-view/record-by-pk": {
-            "get": {
-                "description": "根据schema、table和主键值查询单条记录（用于查看质量问题的原始数据）",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "数据查看"
-                ],
-                "summary": "根据主键值获取单条记录",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Schema名称",
-                        "name": "schema_name",
-                        "in": "query",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "表名",
-                        "name": "table_name",
-                        "in": "query",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "example": "\"id=123\" or \"key1=val1\u0026key2=val2\"",
-                        "description": "记录标识符",
-                        "name": "record_identifier",
-                        "in": "query",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "404": {
-                        "description": "Not Found",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/data-view/{library_type}/{library_id}/tables": {
-            "get": {
-                "description": "获取指定基础库或主题库的所有数据接口(表)信息",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "数据查看"
-                ],
-                "summary": "获取库的所有数据接口",
-                "parameters": [
-                    {
-                        "enum": [
-                            "basic_library",
-                            "thematic_library"
-                        ],
-                        "type": "string",
-                        "description": "库类型",
-                        "name": "library_type",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "format": "uuid",
-                        "description": "库ID",
-                        "name": "library_id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "boolean",
-                        "default": false,
-                        "description": "是否包含列信息",
-                        "name": "include_columns",
-                        "in": "query"
-                    },
-                    {
-                        "type": "boolean",
-                        "default": false,
-                        "description": "是否包含关系信息",
-                        "name": "include_relationships",
-                        "in": "query"
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/controllers.APIResponse"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "$ref": "#/definitions/controllers.LibraryTablesResponse"
-                                        }
-                                    }
-                                }
-                            ]
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "404": {
-                        "description": "Not Found",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/data-view/{library_type}/{library_id}/tables/{table_name}/data": {
-            "get": {
-                "description": "获取指定表的数据内容",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "数据查看"
-                ],
-                "summary": "获取表数据",
-                "parameters": [
-                    {
-                        "enum": [
-                            "basic_library",
-                            "thematic_library"
-                        ],
-                        "type": "string",
-                        "description": "库类型",
-                        "name": "library_type",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "format": "uuid",
-                        "description": "库ID",
-                        "name": "library_id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "表名",
-                        "name": "table_name",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "maximum": 1000,
-                        "minimum": 1,
-                        "type": "integer",
-                        "default": 100,
-                        "description": "限制返回行数",
-                        "name": "limit",
-                        "in": "query"
-                    },
-                    {
-                        "minimum": 0,
-                        "type": "integer",
-                        "default": 0,
-                        "description": "偏移量",
-                        "name": "offset",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "example": "\"age \u003e 18 AND status = 'active'\"",
-                        "description": "WHERE条件(不包含WHERE关键字，由前端拼好并转义)",
-                        "name": "where",
-                        "in": "query"
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "404": {
-                        "description": "Not Found",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/data-view/{library_type}/{library_id}/tables/{table_name}/structure": {
-            "get": {
-                "description": "获取指定表的结构信息",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "数据查看"
-                ],
-                "summary": "获取表结构",
-                "parameters": [
-                    {
-                        "enum": [
-                            "basic_library",
-                            "thematic_library"
-                        ],
-                        "type": "string",
-                        "description": "库类型",
-                        "name": "library_type",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "format": "uuid",
-                        "description": "库ID",
-                        "name": "library_id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "表名",
-                        "name": "table_name",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "404": {
-                        "description": "Not Found",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/events/broadcast": {
-            "post": {
-                "description": "向所有连接的用户广播SSE事件",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "事件管理"
-                ],
-                "summary": "广播事件",
-                "parameters": [
-                    {
-                        "description": "广播事件请求",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/controllers.BroadcastEventRequest"
-                        }
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/events/connections": {
-            "get": {
-                "description": "分页获取SSE连接列表，支持多种过滤条件",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "事件管理"
-                ],
-                "summary": "获取SSE连接列表",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "default": 1,
-                        "description": "页码",
-                        "name": "page",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "default": 10,
-                        "description": "每页大小",
-                        "name": "size",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "用户名过滤",
-                        "name": "user_name",
-                        "in": "query"
-                    },
-                    {
-                        "type": "boolean",
-                        "description": "连接状态过滤",
-                        "name": "is_active",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "客户端IP过滤",
-                        "name": "client_ip",
-                        "in": "query"
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "获取成功",
-                        "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/controllers.APIResponse"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "$ref": "#/definitions/controllers.SSEConnectionListResponse"
-                                        }
-                                    }
-                                }
-                            ]
-                        }
-                    },
-                    "400": {
-                        "description": "请求参数错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "服务器内部错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/events/history": {
-            "get": {
-                "description": "分页获取事件历史列表，支持多种过滤条件",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "事件管理"
-                ],
-                "summary": "获取事件历史列表",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "default": 1,
-                        "description": "页码",
-                        "name": "page",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "default": 10,
-                        "description": "每页大小",
-                        "name": "size",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "用户名过滤",
-                        "name": "user_name",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "事件类型过滤",
-                        "name": "event_type",
-                        "in": "query"
-                    },
-                    {
-                        "type": "boolean",
-                        "description": "发送状态过滤",
-                        "name": "sent",
-                        "in": "query"
-                    },
-                    {
-                        "type": "boolean",
-                        "description": "读取状态过滤",
-                        "name": "read",
-                        "in": "query"
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "获取成功",
-                        "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/controllers.APIResponse"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "$ref": "#/definitions/controllers.EventHistoryListResponse"
-                                        }
-                                    }
-                                }
-                            ]
-                        }
-                    },
-                    "400": {
-                        "description": "请求参数错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "服务器内部错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/events/send": {
-            "post": {
-                "description": "向指定用户发送SSE事件",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "事件管理"
-                ],
-                "summary": "发送事件",
-                "parameters": [
-                    {
-                        "description": "发送事件请求",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/controllers.SendEventRequest"
-                        }
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/health": {
-            "get": {
-                "description": "检查服务健康状态",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "系统"
-                ],
-                "summary": "健康检查",
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.HealthResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/meta/basic-libraries/data-interface-configs": {
-            "get": {
-                "description": "获取所有数据接口配置元数据",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "元数据"
-                ],
-                "summary": "获取所有数据接口配置元数据",
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/controllers.APIResponse"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "type": "object",
-                                            "additionalProperties": {
-                                                "$ref": "#/definitions/meta.DataInterfaceConfigDefinition"
-                                            }
-                                        }
-                                    }
-                                }
-                            ]
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/meta/basic-libraries/data-sources": {
-            "get": {
-                "description": "获取所有数据源类型元数据",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "元数据"
-                ],
-                "summary": "获取所有数据源类型元数据",
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/controllers.APIResponse"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "type": "object",
-                                            "additionalProperties": {
-                                                "$ref": "#/definitions/meta.DataSourceTypeDefinition"
-                                            }
-                                        }
-                                    }
-                                }
-                            ]
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/meta/data-governance": {
-            "get": {
-                "description": "获取数据治理相关的所有元数据，包括质量规则类型、检查状态、脱敏类型、转换规则类型、校验规则类型、任务类型、血缘关系类型等",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "元数据"
-                ],
-                "summary": "获取数据治理完整元数据",
-                "responses": {
-                    "200": {
-                        "description": "获取成功",
-                        "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/controllers.APIResponse"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "$ref": "#/definitions/controllers.DataGovernanceMetadataResponse"
-                                        }
-                                    }
-                                }
-                            ]
-                        }
-                    },
-                    "500": {
-                        "description": "服务器内部错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/meta/sync-tasks": {
-            "get": {
-                "description": "获取所有同步任务相关元数据，包括任务类型、状态、调度类型等",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "元数据"
-                ],
-                "summary": "获取所有同步任务元数据",
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/controllers.APIResponse"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "type": "object",
-                                            "additionalProperties": true
-                                        }
-                                    }
-                                }
-                            ]
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/meta/thematic-libraries/access-levels": {
-            "get": {
-                "description": "获取所有数据主题库访问级别元数据",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "元数据"
-                ],
-                "summary": "获取所有数据主题库访问级别元数据",
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/controllers.APIResponse"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "type": "array",
-                                            "items": {
-                                                "$ref": "#/definitions/meta.ThematicLibraryAccessLevel"
-                                            }
-                                        }
-                                    }
-                                }
-                            ]
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/meta/thematic-libraries/all": {
-            "get": {
-                "description": "获取主题库相关的所有元数据，包括分类、数据域、访问级别、状态等",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "元数据"
-                ],
-                "summary": "获取主题库完整元数据",
-                "responses": {
-                    "200": {
-                        "description": "获取成功",
-                        "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/controllers.APIResponse"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "$ref": "#/definitions/controllers.ThematicLibraryMetadataResponse"
-                                        }
-                                    }
-                                }
-                            ]
-                        }
-                    },
-                    "500": {
-                        "description": "服务器内部错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/meta/thematic-libraries/categories": {
-            "get": {
-                "description": "获取所有数据主题库分类元数据",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "元数据"
-                ],
-                "summary": "获取所有数据主题库分类元数据",
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/controllers.APIResponse"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "type": "array",
-                                            "items": {
-                                                "$ref": "#/definitions/meta.ThematicLibraryCategory"
-                                            }
-                                        }
-                                    }
-                                }
-                            ]
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/meta/thematic-libraries/domains": {
-            "get": {
-                "description": "获取所有数据主题库域元数据",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "元数据"
-                ],
-                "summary": "获取所有数据主题库域元数据",
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/controllers.APIResponse"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "type": "array",
-                                            "items": {
-                                                "$ref": "#/definitions/meta.ThematicLibraryDomain"
-                                            }
-                                        }
-                                    }
-                                }
-                            ]
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/meta/thematic-libraries/interface-statuses": {
-            "get": {
-                "description": "获取主题接口状态元数据",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "元数据"
-                ],
-                "summary": "获取主题接口状态元数据",
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/controllers.APIResponse"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "type": "array",
-                                            "items": {
-                                                "$ref": "#/definitions/meta.ThematicInterfaceStatus"
-                                            }
-                                        }
-                                    }
-                                }
-                            ]
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/meta/thematic-libraries/interface-types": {
-            "get": {
-                "description": "获取主题接口类型元数据",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "元数据"
-                ],
-                "summary": "获取主题接口类型元数据",
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/controllers.APIResponse"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "type": "array",
-                                            "items": {
-                                                "$ref": "#/definitions/meta.ThematicInterfaceType"
-                                            }
-                                        }
-                                    }
-                                }
-                            ]
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/meta/thematic-libraries/statuses": {
-            "get": {
-                "description": "获取主题库状态元数据",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "元数据"
-                ],
-                "summary": "获取主题库状态元数据",
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/controllers.APIResponse"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "type": "array",
-                                            "items": {
-                                                "$ref": "#/definitions/meta.ThematicLibraryStatus"
-                                            }
-                                        }
-                                    }
-                                }
-                            ]
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/meta/thematic-sync-configs": {
-            "get": {
-                "description": "获取主题库同步各种配置的字段定义，用于前端动态生成配置表单",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "元数据"
-                ],
-                "summary": "获取主题库同步配置定义",
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/controllers.APIResponse"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "type": "object",
-                                            "additionalProperties": {
-                                                "$ref": "#/definitions/meta.ThematicSyncConfigDefinition"
-                                            }
-                                        }
-                                    }
-                                }
-                            ]
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/meta/thematic-sync-tasks": {
-            "get": {
-                "description": "获取所有主题库同步任务相关元数据，包括任务状态、触发类型、执行状态等",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "元数据"
-                ],
-                "summary": "获取所有主题库同步任务元数据",
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/controllers.APIResponse"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "type": "object",
-                                            "additionalProperties": true
-                                        }
-                                    }
-                                }
-                            ]
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/monitoring/config": {
-            "get": {
-                "description": "获取VictoriaMetrics和Loki的连接配置",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "监控配置"
-                ],
-                "summary": "获取监控配置",
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/controllers.APIResponse"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "type": "object"
-                                        }
-                                    }
-                                }
-                            ]
-                        }
-                    }
-                }
-            }
-        },
-        "/monitoring/logs/descriptions": {
-            "get": {
-                "description": "获取所有日志查询模板的中文描述信息",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "监控模板"
-                ],
-                "summary": "获取日志模板描述",
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/controllers.APIResponse"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "type": "object",
-                                            "additionalProperties": {
-                                                "type": "string"
-                                            }
-                                        }
-                                    }
-                                }
-                            ]
-                        }
-                    }
-                }
-            }
-        },
-        "/monitoring/loki/labels/{label}/values": {
-            "get": {
-                "description": "获取指定标签的所有可能值",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "监控查询"
-                ],
-                "summary": "获取日志标签值",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "标签名称",
-                        "name": "label",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/controllers.APIResponse"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "type": "array",
-                                            "items": {
-                                                "type": "string"
-                                            }
-                                        }
-                                    }
-                                }
-                            ]
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/monitoring/metrics/descriptions": {
-            "get": {
-                "description": "获取所有指标的中文描述信息",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "监控模板"
-                ],
-                "summary": "获取指标描述",
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/controllers.APIResponse"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "type": "object",
-                                            "additionalProperties": {
-                                                "type": "string"
-                                            }
-                                        }
-                                    }
-                                }
-                            ]
-                        }
-                    }
-                }
-            }
-        },
-        "/monitoring/query": {
-            "post": {
-                "description": "根据查询类型自动选择 Metrics 或 Logs 查询",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "监控查询"
-                ],
-                "summary": "执行自定义查询",
-                "parameters": [
-                    {
-                        "description": "查询请求",
-                        "name": "query",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/meta.MonitorQueryRequest"
-                        }
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/controllers.APIResponse"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "type": "object"
-                                        }
-                                    }
-                                }
-                            ]
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/monitoring/query/logs": {
-            "post": {
-                "description": "执行自定义的 LogQL 查询",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "监控查询"
-                ],
-                "summary": "通用日志查询",
-                "parameters": [
-                    {
-                        "description": "查询请求",
-                        "name": "query",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/meta.MonitorQueryRequest"
-                        }
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/controllers.APIResponse"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "type": "object"
-                                        }
-                                    }
-                                }
-                            ]
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/monitoring/query/metrics": {
-            "post": {
-                "description": "执行自定义的 PromQL 查询，支持即时查询和区间查询",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "监控查询"
-                ],
-                "summary": "通用指标查询",
-                "parameters": [
-                    {
-                        "description": "查询请求",
-                        "name": "query",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/meta.MonitorQueryRequest"
-                        }
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/controllers.APIResponse"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "type": "object"
-                                        }
-                                    }
-                                }
-                            ]
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/monitoring/query/validate": {
-            "post": {
-                "description": "验证 PromQL 或 LogQL 查询语法是否正确",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "监控查询"
-                ],
-                "summary": "验证查询语法",
-                "parameters": [
-                    {
-                        "description": "查询请求",
-                        "name": "query",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/meta.MonitorQueryRequest"
-                        }
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/controllers.APIResponse"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "type": "object"
-                                        }
-                                    }
-                                }
-                            ]
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/monitoring/templates/logs": {
-            "get": {
-                "description": "获取常用的日志查询模板列表",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "监控模板"
-                ],
-                "summary": "获取日志查询模板",
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/controllers.APIResponse"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "type": "object",
-                                            "additionalProperties": {
-                                                "type": "string"
-                                            }
-                                        }
-                                    }
-                                }
-                            ]
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/monitoring/templates/metrics": {
-            "get": {
-                "description": "获取常用的指标查询模板列表",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "监控模板"
-                ],
-                "summary": "获取指标查询模板",
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/controllers.APIResponse"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "type": "object",
-                                            "additionalProperties": {
-                                                "type": "string"
-                                            }
-                                        }
-                                    }
-                                }
-                            ]
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/ready": {
-            "get": {
-                "description": "检查服务是否就绪",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "系统"
-                ],
-                "summary": "就绪检查",
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.HealthResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/sharing/api-applications": {
-            "get": {
-                "description": "分页获取API应用列表",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "数据共享服务"
-                ],
-                "summary": "获取API应用列表",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "default": 1,
-                        "description": "页码",
-                        "name": "page",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "default": 10,
-                        "description": "每页数量",
-                        "name": "size",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "应用状态",
-                        "name": "status",
-                        "in": "query"
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "获取成功",
-                        "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/controllers.APIResponse"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "$ref": "#/definitions/controllers.ApiApplicationListResponse"
-                                        }
-                                    }
-                                }
-                            ]
-                        }
-                    },
-                    "500": {
-                        "description": "服务器内部错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            },
-            "post": {
-                "description": "创建新的API接入应用",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "数据共享服务"
-                ],
-                "summary": "创建API应用",
-                "parameters": [
-                    {
-                        "description": "API应用信息",
-                        "name": "app",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/controllers.CreateApiApplicationRequest"
-                        }
-                    }
-                ],
-                "responses": {
-                    "201": {
-                        "description": "创建成功",
-                        "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/controllers.APIResponse"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "$ref": "#/definitions/models.ApiApplication"
-                                        }
-                                    }
-                                }
-                            ]
-                        }
-                    },
-                    "400": {
-                        "description": "请求参数错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "服务器内部错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/sharing/api-applications/{id}": {
-            "get": {
-                "description": "根据ID获取API应用详情",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "数据共享服务"
-                ],
-                "summary": "根据ID获取API应用",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "应用ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "获取成功",
-                        "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/controllers.APIResponse"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "$ref": "#/definitions/models.ApiApplication"
-                                        }
-                                    }
-                                }
-                            ]
-                        }
-                    },
-                    "404": {
-                        "description": "应用不存在",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "服务器内部错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            },
-            "put": {
-                "description": "更新API应用信息",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "数据共享服务"
-                ],
-                "summary": "更新API应用",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "应用ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "更新信息",
-                        "name": "updates",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": true
-                        }
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "更新成功",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "400": {
-                        "description": "请求参数错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "404": {
-                        "description": "应用不存在",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "服务器内部错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            },
-            "delete": {
-                "description": "删除指定的API应用",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "数据共享服务"
-                ],
-                "summary": "删除API应用",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "应用ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "删除成功",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "404": {
-                        "description": "应用不存在",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "服务器内部错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/sharing/api-interfaces": {
-            "get": {
-                "description": "查询共享接口列表，可按 api_application_id 过滤",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "数据共享服务"
-                ],
-                "summary": "获取共享接口列表",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "应用ID",
-                        "name": "api_application_id",
-                        "in": "query"
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "获取成功",
-                        "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/controllers.APIResponse"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "type": "array",
-                                            "items": {
-                                                "$ref": "#/definitions/models.ApiInterface"
-                                            }
-                                        }
-                                    }
-                                }
-                            ]
-                        }
-                    },
-                    "500": {
-                        "description": "服务器内部错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            },
-            "post": {
-                "description": "创建一个共享接口，请求体包含 api_application_id, thematic_interface_id, path, masking_rules",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "数据共享服务"
-                ],
-                "summary": "创建共享接口",
-                "parameters": [
-                    {
-                        "description": "接口信息",
-                        "name": "interface_param",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/controllers.CreateApiInterfaceRequest"
-                        }
-                    }
-                ],
-                "responses": {
-                    "201": {
-                        "description": "创建成功",
-                        "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/controllers.APIResponse"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "$ref": "#/definitions/models.ApiInterface"
-                                        }
-                                    }
-                                }
-                            ]
-                        }
-                    },
-                    "400": {
-                        "description": "请求参数错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "服务器内部错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/sharing/api-interfaces/{id}": {
-            "get": {
-                "description": "根据ID获取共享接口详细信息，包括关联的应用和主题接口信息",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "数据共享服务"
-                ],
-                "summary": "根据ID获取共享接口",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "接口ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "获取成功",
-                        "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/controllers.APIResponse"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "$ref": "#/definitions/models.ApiInterface"
-                                        }
-                                    }
-                                }
-                            ]
-                        }
-                    },
-                    "404": {
-                        "description": "接口不存在",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "服务器内部错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            },
-            "put": {
-                "description": "更新共享接口信息（路径、描述、状态等）",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "数据共享服务"
-                ],
-                "summary": "更新共享接口",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "接口ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "更新信息",
-                        "name": "updates",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/controllers.UpdateApiInterfaceRequest"
-                        }
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "更新成功",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "400": {
-                        "description": "请求参数错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "404": {
-                        "description": "接口不存在",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "服务器内部错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            },
-            "delete": {
-                "description": "删除一个共享接口",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "数据共享服务"
-                ],
-                "summary": "删除共享接口",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "接口ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "删除成功",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "服务器内部错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/sharing/api-interfaces/{id}/masking-rules": {
-            "get": {
-                "description": "获取指定API接口的数据脱敏规则配置",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "数据共享服务"
-                ],
-                "summary": "获取API接口脱敏规则",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "接口ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "获取成功",
-                        "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/controllers.APIResponse"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "type": "array",
-                                            "items": {
-                                                "$ref": "#/definitions/models.DataMaskingConfig"
-                                            }
-                                        }
-                                    }
-                                }
-                            ]
-                        }
-                    },
-                    "404": {
-                        "description": "接口不存在",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "服务器内部错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            },
-            "put": {
-                "description": "更新指定API接口的数据脱敏规则配置",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "数据共享服务"
-                ],
-                "summary": "更新API接口脱敏规则",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "接口ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "脱敏规则配置",
-                        "name": "rules",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/controllers.UpdateApiInterfaceMaskingRulesRequest"
-                        }
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "更新成功",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "400": {
-                        "description": "请求参数错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "404": {
-                        "description": "接口不存在",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "服务器内部错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/sharing/api-keys": {
-            "get": {
-                "description": "获取API密钥列表（不包含Key本身），可选择按应用过滤",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "数据共享服务"
-                ],
-                "summary": "获取API密钥列表",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "应用ID，用于过滤特定应用的ApiKey",
-                        "name": "app_id",
-                        "in": "query"
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "获取成功",
-                        "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/controllers.APIResponse"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "type": "array",
-                                            "items": {
-                                                "$ref": "#/definitions/models.ApiKey"
-                                            }
-                                        }
-                                    }
-                                }
-                            ]
-                        }
-                    },
-                    "500": {
-                        "description": "服务器内部错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            },
-            "post": {
-                "description": "创建一个新的ApiKey并关联到指定的应用，返回完整的Key值（仅此一次）",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "数据共享服务"
-                ],
-                "summary": "生成API密钥",
-                "parameters": [
-                    {
-                        "description": "ApiKey信息",
-                        "name": "key",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/controllers.CreateApiKeyRequest"
-                        }
-                    }
-                ],
-                "responses": {
-                    "201": {
-                        "description": "创建成功",
-                        "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/controllers.APIResponse"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "$ref": "#/definitions/controllers.CreateApiKeyResponse"
-                                        }
-                                    }
-                                }
-                            ]
-                        }
-                    },
-                    "400": {
-                        "description": "请求参数错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "服务器内部错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/sharing/api-keys/{id}": {
-            "get": {
-                "description": "根据ID获取API密钥详情（不包含Key本身）",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "数据共享服务"
-                ],
-                "summary": "获取API密钥详情",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "密钥ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "获取成功",
-                        "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/controllers.APIResponse"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "$ref": "#/definitions/models.ApiKey"
-                                        }
-                                    }
-                                }
-                            ]
-                        }
-                    },
-                    "404": {
-                        "description": "密钥不存在",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "服务器内部错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            },
-            "put": {
-                "description": "更新ApiKey信息（如名称、描述、状态）",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "数据共享服务"
-                ],
-                "summary": "更新API密钥",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "密钥ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "更新信息",
-                        "name": "updates",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": true
-                        }
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "更新成功",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "400": {
-                        "description": "请求参数错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "服务器内部错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            },
-            "delete": {
-                "description": "吊销（删除）一个ApiKey",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "数据共享服务"
-                ],
-                "summary": "删除API密钥",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "密钥ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "删除成功",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "服务器内部错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/sharing/api-keys/{id}/applications": {
-            "put": {
-                "description": "更新ApiKey关联的应用列表",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "数据共享服务"
-                ],
-                "summary": "更新API密钥关联应用",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "密钥ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "关联应用信息",
-                        "name": "applications",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/controllers.UpdateApiKeyApplicationsRequest"
-                        }
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "更新成功",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "400": {
-                        "description": "请求参数错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "服务器内部错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/sharing/api-rate-limits": {
-            "get": {
-                "description": "分页获取API限流规则列表，可按限流类型和目标ID过滤",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "数据共享服务"
-                ],
-                "summary": "获取API限流规则列表",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "default": 1,
-                        "description": "页码",
-                        "name": "page",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "default": 10,
-                        "description": "每页数量",
-                        "name": "size",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "限流类型：global/api_key/application",
-                        "name": "rate_limit_type",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "目标ID（api_key_id或application_id）",
-                        "name": "target_id",
-                        "in": "query"
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "获取成功",
-                        "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/controllers.APIResponse"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "$ref": "#/definitions/controllers.ApiRateLimitListResponse"
-                                        }
-                                    }
-                                }
-                            ]
-                        }
-                    },
-                    "500": {
-                        "description": "服务器内部错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            },
-            "post": {
-                "description": "创建新的API限流规则，支持三层限流：全局（global）、API密钥（api_key）、应用（application）",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "数据共享服务"
-                ],
-                "summary": "创建API限流规则",
-                "parameters": [
-                    {
-                        "description": "API限流规则信息",
-                        "name": "limit",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/controllers.CreateApiRateLimitRequest"
-                        }
-                    }
-                ],
-                "responses": {
-                    "201": {
-                        "description": "创建成功",
-                        "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/controllers.APIResponse"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "$ref": "#/definitions/models.ApiRateLimit"
-                                        }
-                                    }
-                                }
-                            ]
-                        }
-                    },
-                    "400": {
-                        "description": "请求参数错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "服务器内部错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/sharing/api-rate-limits/statistics": {
-            "get": {
-                "description": "获取API限流规则的统计信息，包括总数、启用数、类型分布等",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "数据共享服务"
-                ],
-                "summary": "获取API限流统计信息",
-                "responses": {
-                    "200": {
-                        "description": "获取成功",
-                        "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/controllers.APIResponse"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "$ref": "#/definitions/models.ApiRateLimitStatistics"
-                                        }
-                                    }
-                                }
-                            ]
-                        }
-                    },
-                    "500": {
-                        "description": "服务器内部错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/sharing/api-rate-limits/{id}": {
-            "put": {
-                "description": "更新API限流规则信息",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "数据共享服务"
-                ],
-                "summary": "更新API限流规则",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "规则ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "更新信息",
-                        "name": "updates",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": true
-                        }
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "更新成功",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "400": {
-                        "description": "请求参数错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "404": {
-                        "description": "规则不存在",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "服务器内部错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            },
-            "delete": {
-                "description": "删除指定的API限流规则",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "数据共享服务"
-                ],
-                "summary": "删除API限流规则",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "规则ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "删除成功",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "404": {
-                        "description": "规则不存在",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "服务器内部错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/sharing/api-usage-logs": {
-            "get": {
-                "description": "分页获取API使用日志列表",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "数据共享服务"
-                ],
-                "summary": "获取API使用日志列表",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "default": 1,
-                        "description": "页码",
-                        "name": "page",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "default": 10,
-                        "description": "每页数量",
-                        "name": "size",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "应用ID",
-                        "name": "application_id",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "用户ID",
-                        "name": "user_id",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "开始时间",
-                        "name": "start_time",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "结束时间",
-                        "name": "end_time",
-                        "in": "query"
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "获取成功",
-                        "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/controllers.APIResponse"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "$ref": "#/definitions/controllers.ApiUsageLogListResponse"
-                                        }
-                                    }
-                                }
-                            ]
-                        }
-                    },
-                    "500": {
-                        "description": "服务器内部错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/sharing/api-usage-logs/statistics": {
-            "get": {
-                "description": "获取API使用日志的统计信息，包括总请求数、成功率、平均响应时间、TOP应用等",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "数据共享服务"
-                ],
-                "summary": "获取API使用统计信息",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "开始时间（RFC3339格式）",
-                        "name": "start_time",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "结束时间（RFC3339格式）",
-                        "name": "end_time",
-                        "in": "query"
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "获取成功",
-                        "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/controllers.APIResponse"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "$ref": "#/definitions/models.ApiUsageStatistics"
-                                        }
-                                    }
-                                }
-                            ]
-                        }
-                    },
-                    "500": {
-                        "description": "服务器内部错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/sharing/data-access-requests": {
-            "get": {
-                "description": "分页获取数据使用申请列表",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "数据共享服务"
-                ],
-                "summary": "获取数据使用申请列表",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "default": 1,
-                        "description": "页码",
-                        "name": "page",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "default": 10,
-                        "description": "每页数量",
-                        "name": "size",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "申请人ID",
-                        "name": "requester_id",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "资源类型",
-                        "name": "resource_type",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "申请状态",
-                        "name": "status",
-                        "in": "query"
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "获取成功",
-                        "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/controllers.APIResponse"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "$ref": "#/definitions/controllers.DataAccessRequestListResponse"
-                                        }
-                                    }
-                                }
-                            ]
-                        }
-                    },
-                    "500": {
-                        "description": "服务器内部错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            },
-            "post": {
-                "description": "创建新的数据使用申请",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "数据共享服务"
-                ],
-                "summary": "创建数据使用申请",
-                "parameters": [
-                    {
-                        "description": "数据使用申请信息",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.DataAccessRequest"
-                        }
-                    }
-                ],
-                "responses": {
-                    "201": {
-                        "description": "创建成功",
-                        "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/controllers.APIResponse"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "$ref": "#/definitions/models.DataAccessRequest"
-                                        }
-                                    }
-                                }
-                            ]
-                        }
-                    },
-                    "400": {
-                        "description": "请求参数错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "服务器内部错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/sharing/data-access-requests/{id}": {
-            "get": {
-                "description": "根据ID获取数据使用申请详情",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "数据共享服务"
-                ],
-                "summary": "根据ID获取数据使用申请",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "申请ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "获取成功",
-                        "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/controllers.APIResponse"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "$ref": "#/definitions/models.DataAccessRequest"
-                                        }
-                                    }
-                                }
-                            ]
-                        }
-                    },
-                    "404": {
-                        "description": "申请不存在",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "服务器内部错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/sharing/data-access-requests/{id}/approve": {
-            "post": {
-                "description": "审批数据使用申请",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "数据共享服务"
-                ],
-                "summary": "审批数据使用申请",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "申请ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "审批信息",
-                        "name": "approval",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/controllers.ApproveDataAccessRequestRequest"
-                        }
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "审批成功",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "400": {
-                        "description": "请求参数错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "404": {
-                        "description": "申请不存在",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "服务器内部错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/sharing/data-subscriptions": {
-            "get": {
-                "description": "分页获取数据订阅列表",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "数据共享服务"
-                ],
-                "summary": "获取数据订阅列表",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "default": 1,
-                        "description": "页码",
-                        "name": "page",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "default": 10,
-                        "description": "每页数量",
-                        "name": "size",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "订阅者ID",
-                        "name": "subscriber_id",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "资源类型",
-                        "name": "resource_type",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "订阅状态",
-                        "name": "status",
-                        "in": "query"
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "获取成功",
-                        "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/controllers.APIResponse"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "$ref": "#/definitions/controllers.DataSubscriptionListResponse"
-                                        }
-                                    }
-                                }
-                            ]
-                        }
-                    },
-                    "500": {
-                        "description": "服务器内部错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            },
-            "post": {
-                "description": "创建新的数据订阅",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "数据共享服务"
-                ],
-                "summary": "创建数据订阅",
-                "parameters": [
-                    {
-                        "description": "数据订阅信息",
-                        "name": "subscription",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.DataSubscription"
-                        }
-                    }
-                ],
-                "responses": {
-                    "201": {
-                        "description": "创建成功",
-                        "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/controllers.APIResponse"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "$ref": "#/definitions/models.DataSubscription"
-                                        }
-                                    }
-                                }
-                            ]
-                        }
-                    },
-                    "400": {
-                        "description": "请求参数错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "服务器内部错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/sharing/data-subscriptions/{id}": {
-            "get": {
-                "description": "根据ID获取数据订阅详情",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "数据共享服务"
-                ],
-                "summary": "根据ID获取数据订阅",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "订阅ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "获取成功",
-                        "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/controllers.APIResponse"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "$ref": "#/definitions/models.DataSubscription"
-                                        }
-                                    }
-                                }
-                            ]
-                        }
-                    },
-                    "404": {
-                        "description": "订阅不存在",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "服务器内部错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            },
-            "put": {
-                "description": "更新数据订阅信息",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "数据共享服务"
-                ],
-                "summary": "更新数据订阅",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "订阅ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "更新信息",
-                        "name": "updates",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": true
-                        }
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "更新成功",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "400": {
-                        "description": "请求参数错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "404": {
-                        "description": "订阅不存在",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "服务器内部错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            },
-            "delete": {
-                "description": "删除指定的数据订阅",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "数据共享服务"
-                ],
-                "summary": "删除数据订阅",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "订阅ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "删除成功",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "404": {
-                        "description": "订阅不存在",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "服务器内部错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/sse/{user_name}": {
-            "get": {
-                "description": "前端页面通过此接口建立SSE连接，接收实时事件推送",
-                "tags": [
-                    "事件管理"
-                ],
-                "summary": "建立SSE连接",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "用户名",
-                        "name": "user_name",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "SSE事件流",
-                        "schema": {
-                            "type": "string"
-                        }
-                    }
-                }
-            }
-        },
-        "/sync/tasks": {
-            "get": {
-                "description": "分页获取基础库同步任务列表，支持多种过滤条件\n\n**查询参数说明:**\n- page: 页码，默认1\n- size: 每页大小，默认10，最大100\n- library_id: 基础库ID过滤\n- data_source_id: 数据源ID过滤\n- status: 任务状态过滤\n- task_type: 任务类型过滤",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "基础库同步任务"
-                ],
-                "summary": "获取基础库同步任务列表",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "default": 1,
-                        "description": "页码",
-                        "name": "page",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "default": 10,
-                        "description": "每页大小",
-                        "name": "size",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "基础库ID",
-                        "name": "library_id",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "数据源ID",
-                        "name": "data_source_id",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "任务状态",
-                        "name": "status",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "任务类型",
-                        "name": "task_type",
-                        "in": "query"
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "获取成功",
-                        "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/controllers.APIResponse"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "$ref": "#/definitions/basic_library.SyncTaskListResponse"
-                                        }
-                                    }
-                                }
-                            ]
-                        }
-                    },
-                    "400": {
-                        "description": "请求参数错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "服务器内部错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            },
-            "post": {
-                "description": "创建新的基础库数据同步任务，专门处理基础库数据同步\n\n**支持的任务类型:**\n- batch_sync: 批量同步（根据接口配置自动判断全量/增量）\n- realtime_sync: 实时同步\n\n**任务生命周期状态:**\n- draft: 草稿，正在编辑，不参与调度\n- active: 激活，已配置完成，参与调度\n- paused: 暂停，临时停止调度，但保留配置\n\n**任务执行状态:**\n- idle: 空闲，等待下次执行\n- running: 执行中，正在执行同步\n- success: 成功，最近一次执行成功\n- failed: 失败，最近一次执行失败\n\n**状态流转:**\n生命周期: draft → active ↔ paused\n执行状态: idle → running → success/failed → idle\n\n**注意:**\n- 新创建的任务默认为 draft 状态，需要手动激活后才会参与调度\n- 此接口仅支持基础库同步任务，不支持主题库同步",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "基础库同步任务"
-                ],
-                "summary": "创建基础库同步任务",
-                "parameters": [
-                    {
-                        "description": "基础库同步任务创建信息",
-                        "name": "task",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/controllers.SyncTaskCreateRequest"
-                        }
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "创建成功",
-                        "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/controllers.APIResponse"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "$ref": "#/definitions/models.SyncTask"
-                                        }
-                                    }
-                                }
-                            ]
-                        }
-                    },
-                    "400": {
-                        "description": "请求参数错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "服务器内部错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/sync/tasks/batch-delete": {
-            "post": {
-                "description": "批量删除多个同步任务，只能删除已完成、失败或已取消的任务",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "基础库同步任务"
-                ],
-                "summary": "批量删除同步任务",
-                "parameters": [
-                    {
-                        "description": "批量删除请求",
-                        "name": "tasks",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/controllers.BatchDeleteRequest"
-                        }
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "删除成功",
-                        "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/controllers.APIResponse"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "$ref": "#/definitions/basic_library.BatchDeleteResponse"
-                                        }
-                                    }
-                                }
-                            ]
-                        }
-                    },
-                    "400": {
-                        "description": "请求参数错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "服务器内部错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/sync/tasks/executions": {
-            "get": {
-                "description": "分页获取同步任务执行记录列表，支持多种过滤条件",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "基础库同步任务"
-                ],
-                "summary": "获取同步任务执行记录列表",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "default": 1,
-                        "description": "页码",
-                        "name": "page",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "default": 10,
-                        "description": "每页大小",
-                        "name": "size",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "任务ID",
-                        "name": "task_id",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "执行状态",
-                        "name": "status",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "执行类型",
-                        "name": "execution_type",
-                        "in": "query"
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "获取成功",
-                        "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/controllers.APIResponse"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "$ref": "#/definitions/basic_library.SyncTaskExecutionListResponse"
-                                        }
-                                    }
-                                }
-                            ]
-                        }
-                    },
-                    "400": {
-                        "description": "请求参数错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "服务器内部错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/sync/tasks/executions/{id}": {
-            "get": {
-                "description": "根据执行记录ID获取同步任务执行记录的详细信息",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "基础库同步任务"
-                ],
-                "summary": "获取同步任务执行记录详情",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "执行记录ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "获取成功",
-                        "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/controllers.APIResponse"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "$ref": "#/definitions/models.SyncTaskExecution"
-                                        }
-                                    }
-                                }
-                            ]
-                        }
-                    },
-                    "400": {
-                        "description": "请求参数错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "404": {
-                        "description": "执行记录不存在",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "服务器内部错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/sync/tasks/statistics": {
-            "get": {
-                "description": "获取同步任务的统计数据，包括各状态任务数量、成功率等",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "基础库同步任务"
-                ],
-                "summary": "获取同步任务统计信息",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "基础库ID过滤",
-                        "name": "library_id",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "数据源ID过滤",
-                        "name": "data_source_id",
-                        "in": "query"
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "获取成功",
-                        "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/controllers.APIResponse"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "$ref": "#/definitions/basic_library.SyncTaskStatistics"
-                                        }
-                                    }
-                                }
-                            ]
-                        }
-                    },
-                    "500": {
-                        "description": "服务器内部错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/sync/tasks/{id}": {
-            "get": {
-                "description": "根据任务ID获取同步任务的详细信息，包括关联的库信息、数据源信息等",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "基础库同步任务"
-                ],
-                "summary": "获取同步任务详情",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "任务ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "获取成功",
-                        "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/controllers.APIResponse"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "$ref": "#/definitions/models.SyncTask"
-                                        }
-                                    }
-                                }
-                            ]
-                        }
-                    },
-                    "400": {
-                        "description": "请求参数错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "404": {
-                        "description": "任务不存在",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "服务器内部错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            },
-            "put": {
-                "description": "更新同步任务的配置信息和状态\n\n**可更新状态:**\n- draft 和 paused 状态的任务可以更新任何配置\n- active 状态且非运行中的任务也可以更新\n- running 状态的任务不允许更新\n\n**状态变更:**\n- 可以通过 status 字段变更任务状态（draft/active/paused）\n- 状态变更会自动触发调度器的添加/移除操作",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "基础库同步任务"
-                ],
-                "summary": "更新同步任务",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "任务ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "更新信息",
-                        "name": "task",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/controllers.SyncTaskUpdateRequest"
-                        }
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "更新成功",
-                        "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/controllers.APIResponse"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "$ref": "#/definitions/models.SyncTask"
-                                        }
-                                    }
-                                }
-                            ]
-                        }
-                    },
-                    "400": {
-                        "description": "请求参数错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "404": {
-                        "description": "任务不存在",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "409": {
-                        "description": "任务状态不允许更新",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "服务器内部错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            },
-            "delete": {
-                "description": "删除指定的同步任务，只能删除已完成、失败或已取消的任务",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "基础库同步任务"
-                ],
-                "summary": "删除同步任务",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "任务ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "删除成功",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "400": {
-                        "description": "请求参数错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "404": {
-                        "description": "任务不存在",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "409": {
-                        "description": "任务状态不允许删除",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "服务器内部错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/sync/tasks/{id}/activate": {
-            "post": {
-                "description": "激活同步任务，将 draft 或 paused 状态改为 active，并添加到调度器\n\n**状态转换:**\n- draft → active: 首次激活任务\n- paused → active: 恢复已暂停的任务\n\n**注意:**\n- 只有 draft 或 paused 状态的任务可以激活\n- 激活后，如果配置了调度（cron/interval/once），任务会自动加入调度器",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "基础库同步任务"
-                ],
-                "summary": "激活同步任务",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "任务ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "激活成功",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "400": {
-                        "description": "请求参数错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "404": {
-                        "description": "任务不存在",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "409": {
-                        "description": "任务状态不允许激活",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "服务器内部错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/sync/tasks/{id}/cancel": {
-            "post": {
-                "description": "暂停指定的同步任务，将 active 状态改为 paused，并从调度器中移除\n\n**注意:**\n- 此接口已重命名为 PauseSyncTask，但保留 cancel 路由以保持向后兼容\n- 建议使用 /sync/tasks/{id}/pause 接口",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "基础库同步任务"
-                ],
-                "summary": "暂停同步任务",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "任务ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "暂停成功",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "400": {
-                        "description": "请求参数错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "404": {
-                        "description": "任务不存在",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "409": {
-                        "description": "任务状态不允许暂停",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "服务器内部错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/sync/tasks/{id}/executions": {
-            "get": {
-                "description": "获取指定同步任务的所有执行记录",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "基础库同步任务"
-                ],
-                "summary": "获取指定任务的执行记录",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "任务ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "default": 1,
-                        "description": "页码",
-                        "name": "page",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "default": 10,
-                        "description": "每页大小",
-                        "name": "size",
-                        "in": "query"
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "获取成功",
-                        "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/controllers.APIResponse"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "$ref": "#/definitions/basic_library.SyncTaskExecutionListResponse"
-                                        }
-                                    }
-                                }
-                            ]
-                        }
-                    },
-                    "400": {
-                        "description": "请求参数错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "服务器内部错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/sync/tasks/{id}/pause": {
-            "post": {
-                "description": "暂停同步任务，将 active 状态改为 paused，并从调度器中移除\n\n**状态转换:**\n- active → paused: 暂停任务调度\n\n**注意:**\n- 只有 active 状态的任务可以暂停\n- 暂停后，任务会从调度器中移除，不再自动执行\n- 如果任务正在运行，会先停止执行，然后更新为 paused 状态",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "基础库同步任务"
-                ],
-                "summary": "暂停同步任务",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "任务ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "暂停成功",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "400": {
-                        "description": "请求参数错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "404": {
-                        "description": "任务不存在",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "409": {
-                        "description": "任务状态不允许暂停",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "服务器内部错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/sync/tasks/{id}/resume": {
-            "post": {
-                "description": "恢复已暂停的同步任务，将 paused 状态改为 active，并重新添加到调度器\n\n**状态转换:**\n- paused → active: 恢复任务调度\n\n**注意:**\n- 只有 paused 状态的任务可以恢复\n- 恢复后，任务会重新加入调度器\n- 此接口等同于 ActivateSyncTask，但语义更明确",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "基础库同步任务"
-                ],
-                "summary": "恢复同步任务",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "任务ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "恢复成功",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "400": {
-                        "description": "请求参数错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "404": {
-                        "description": "任务不存在",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "409": {
-                        "description": "任务状态不允许恢复",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "服务器内部错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/sync/tasks/{id}/retry": {
-            "post": {
-                "description": "重试失败的同步任务，会创建一个新的任务实例",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "基础库同步任务"
-                ],
-                "summary": "重试同步任务",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "任务ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "重试成功",
-                        "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/controllers.APIResponse"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "$ref": "#/definitions/models.SyncTask"
-                                        }
-                                    }
-                                }
-                            ]
-                        }
-                    },
-                    "400": {
-                        "description": "请求参数错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "404": {
-                        "description": "任务不存在",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "409": {
-                        "description": "任务状态不允许重试",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "服务器内部错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/sync/tasks/{id}/start": {
-            "post": {
-                "description": "启动指定的同步任务，将任务提交给同步引擎执行",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "基础库同步任务"
-                ],
-                "summary": "启动同步任务",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "任务ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "启动成功",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "400": {
-                        "description": "请求参数错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "404": {
-                        "description": "任务不存在",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "409": {
-                        "description": "任务状态不允许启动",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "服务器内部错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/sync/tasks/{id}/status": {
-            "get": {
-                "description": "获取同步任务的实时执行状态和进度信息",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "基础库同步任务"
-                ],
-                "summary": "获取同步任务状态",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "任务ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "获取成功",
-                        "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/controllers.APIResponse"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "$ref": "#/definitions/basic_library.SyncTaskStatusResponse"
-                                        }
-                                    }
-                                }
-                            ]
-                        }
-                    },
-                    "400": {
-                        "description": "请求参数错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "404": {
-                        "description": "任务不存在",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "服务器内部错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/sync/tasks/{id}/stop": {
-            "post": {
-                "description": "停止正在执行的同步任务",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "基础库同步任务"
-                ],
-                "summary": "停止同步任务",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "任务ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "停止成功",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "400": {
-                        "description": "请求参数错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "404": {
-                        "description": "任务不存在",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "409": {
-                        "description": "任务状态不允许停止",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "服务器内部错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/tables/manage-schema": {
-            "post": {
-                "description": "通过PgMetaApi动态创建、修改、删除数据库表结构",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "数据基础库"
-                ],
-                "summary": "管理数据库表结构",
-                "parameters": [
-                    {
-                        "description": "表结构操作请求",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.TableSchemaRequest"
-                        }
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/thematic-interfaces": {
-            "get": {
-                "description": "分页获取主题接口列表，支持多种过滤条件",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "主题接口"
-                ],
-                "summary": "获取主题接口列表",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "default": 1,
-                        "description": "页码",
-                        "name": "page",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "default": 10,
-                        "description": "每页大小",
-                        "name": "size",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "主题库ID过滤",
-                        "name": "library_id",
-                        "in": "query"
-                    },
-                    {
-                        "enum": [
-                            "table",
-                            "view"
-                        ],
-                        "type": "string",
-                        "description": "接口类型过滤",
-                        "name": "interface_type",
-                        "in": "query"
-                    },
-                    {
-                        "enum": [
-                            "active",
-                            "inactive"
-                        ],
-                        "type": "string",
-                        "description": "状态过滤",
-                        "name": "status",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "名称搜索（支持中英文）",
-                        "name": "name",
-                        "in": "query"
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "获取成功",
-                        "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/controllers.APIResponse"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "$ref": "#/definitions/controllers.ThematicInterfaceListResponse"
-                                        }
-                                    }
-                                }
-                            ]
-                        }
-                    },
-                    "400": {
-                        "description": "请求参数错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "服务器内部错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            },
-            "post": {
-                "description": "创建新的主题接口",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "主题接口"
-                ],
-                "summary": "创建主题接口",
-                "parameters": [
-                    {
-                        "description": "主题接口信息",
-                        "name": "thematic_interface",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.ThematicInterface"
-                        }
-                    }
-                ],
-                "responses": {
-                    "201": {
-                        "description": "Created",
-                        "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/controllers.APIResponse"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "$ref": "#/definitions/models.ThematicInterface"
-                                        }
-                                    }
-                                }
-                            ]
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/thematic-interfaces/create-table-index": {
-            "post": {
-                "description": "为主题接口对应的数据表创建索引，支持普通索引、唯一索引，支持多种索引类型（btree、hash、gin、gist等），视图类型不支持",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "主题接口"
-                ],
-                "summary": "创建主题接口表索引",
-                "parameters": [
-                    {
-                        "description": "创建索引请求",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/controllers.CreateThematicInterfaceTableIndexRequest"
-                        }
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "创建成功",
-                        "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/controllers.APIResponse"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "type": "object"
-                                        }
-                                    }
-                                }
-                            ]
-                        }
-                    },
-                    "400": {
-                        "description": "请求参数错误、视图类型不支持或表未创建",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "服务器内部错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/thematic-interfaces/create-view": {
-            "post": {
-                "description": "为主题接口创建数据库视图，支持CREATE OR REPLACE VIEW语法",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "主题接口视图"
-                ],
-                "summary": "创建主题接口视图",
-                "parameters": [
-                    {
-                        "description": "创建视图请求",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/controllers.CreateThematicInterfaceViewRequest"
-                        }
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/thematic-interfaces/drop-table-index": {
-            "post": {
-                "description": "删除主题接口对应数据表的指定索引",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "主题接口"
-                ],
-                "summary": "删除主题接口表索引",
-                "parameters": [
-                    {
-                        "description": "删除索引请求",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/controllers.DropThematicInterfaceTableIndexRequest"
-                        }
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "删除成功",
-                        "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/controllers.APIResponse"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "type": "object"
-                                        }
-                                    }
-                                }
-                            ]
-                        }
-                    },
-                    "400": {
-                        "description": "请求参数错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "服务器内部错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/thematic-interfaces/update-fields": {
-            "post": {
-                "description": "更新主题接口的字段配置，自动同步数据库表结构（如表不存在则创建，存在则修改）",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "主题接口"
-                ],
-                "summary": "更新主题接口字段配置",
-                "parameters": [
-                    {
-                        "description": "更新字段配置请求",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/controllers.UpdateThematicInterfaceFieldsRequest"
-                        }
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/thematic-interfaces/update-view": {
-            "post": {
-                "description": "更新主题接口的数据库视图SQL",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "主题接口视图"
-                ],
-                "summary": "更新主题接口视图",
-                "parameters": [
-                    {
-                        "description": "更新视图请求",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/controllers.UpdateThematicInterfaceViewRequest"
-                        }
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/thematic-interfaces/{id}": {
-            "get": {
-                "description": "根据ID获取主题接口详细信息，自动同步数据库实际字段到配置",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "主题接口"
-                ],
-                "summary": "获取主题接口详情",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "主题接口ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "获取成功",
-                        "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/controllers.APIResponse"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "$ref": "#/definitions/models.ThematicInterface"
-                                        }
-                                    }
-                                }
-                            ]
-                        }
-                    },
-                    "400": {
-                        "description": "请求参数错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "404": {
-                        "description": "主题接口不存在",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "服务器内部错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            },
-            "put": {
-                "description": "更新主题接口信息",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "主题接口"
-                ],
-                "summary": "更新主题接口",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "主题接口ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "更新信息",
-                        "name": "thematic_interface",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.ThematicInterface"
-                        }
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "404": {
-                        "description": "Not Found",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            },
-            "delete": {
-                "description": "软删除主题接口（更新状态为inactive）",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "主题接口"
-                ],
-                "summary": "删除主题接口",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "主题接口ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "404": {
-                        "description": "Not Found",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/thematic-interfaces/{id}/delete-view": {
-            "delete": {
-                "description": "删除主题接口的数据库视图",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "主题接口视图"
-                ],
-                "summary": "删除主题接口视图",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "主题接口ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "404": {
-                        "description": "Not Found",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/thematic-interfaces/{id}/table-columns": {
-            "get": {
-                "description": "从数据库动态获取主题接口对应表或视图的字段信息，包含字段名、类型、约束等完整信息",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "主题接口"
-                ],
-                "summary": "获取主题接口表字段",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "主题接口ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "返回表字段列表",
-                        "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/controllers.APIResponse"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "type": "array",
-                                            "items": {
-                                                "$ref": "#/definitions/database.ColumnDefinition"
-                                            }
-                                        }
-                                    }
-                                }
-                            ]
-                        }
-                    },
-                    "400": {
-                        "description": "请求参数错误或表未创建",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "服务器内部错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/thematic-interfaces/{id}/table-indexes": {
-            "get": {
-                "description": "从数据库获取主题接口对应表的索引信息，包含索引名称、类型、包含的列等（视图类型不支持）",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "主题接口"
-                ],
-                "summary": "获取主题接口表索引",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "主题接口ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "返回索引列表",
-                        "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/controllers.APIResponse"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "type": "array",
-                                            "items": {
-                                                "$ref": "#/definitions/database.IndexDefinition"
-                                            }
-                                        }
-                                    }
-                                }
-                            ]
-                        }
-                    },
-                    "400": {
-                        "description": "请求参数错误、表未创建或视图类型不支持",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "服务器内部错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/thematic-interfaces/{id}/view-sql": {
-            "get": {
-                "description": "获取主题接口的视图SQL语句",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "主题接口视图"
-                ],
-                "summary": "获取主题接口视图SQL",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "主题接口ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/controllers.APIResponse"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "type": "string"
-                                        }
-                                    }
-                                }
-                            ]
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "404": {
-                        "description": "Not Found",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/thematic-libraries": {
-            "get": {
-                "description": "分页获取数据主题库列表，支持多种过滤条件",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "数据主题库"
-                ],
-                "summary": "获取数据主题库列表",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "default": 1,
-                        "description": "页码",
-                        "name": "page",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "default": 10,
-                        "description": "每页大小",
-                        "name": "size",
-                        "in": "query"
-                    },
-                    {
-                        "enum": [
-                            "business",
-                            "technical",
-                            "analysis",
-                            "report"
-                        ],
-                        "type": "string",
-                        "description": "主题分类过滤",
-                        "name": "category",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "数据域过滤",
-                        "name": "domain",
-                        "in": "query"
-                    },
-                    {
-                        "enum": [
-                            "draft",
-                            "published",
-                            "archived"
-                        ],
-                        "type": "string",
-                        "description": "状态过滤",
-                        "name": "status",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "名称搜索（支持中英文）",
-                        "name": "name",
-                        "in": "query"
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "获取成功",
-                        "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/controllers.APIResponse"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "$ref": "#/definitions/controllers.ThematicLibraryListResponse"
-                                        }
-                                    }
-                                }
-                            ]
-                        }
-                    },
-                    "400": {
-                        "description": "请求参数错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "服务器内部错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            },
-            "post": {
-                "description": "创建新的数据主题库",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "数据主题库"
-                ],
-                "summary": "创建数据主题库",
-                "parameters": [
-                    {
-                        "description": "数据主题库信息",
-                        "name": "library",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.ThematicLibrary"
-                        }
-                    }
-                ],
-                "responses": {
-                    "201": {
-                        "description": "Created",
-                        "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/controllers.APIResponse"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "$ref": "#/definitions/models.ThematicLibrary"
-                                        }
-                                    }
-                                }
-                            ]
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/thematic-libraries/{id}": {
-            "get": {
-                "description": "根据ID获取数据主题库详细信息",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "数据主题库"
-                ],
-                "summary": "获取数据主题库详情",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "数据主题库ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/controllers.APIResponse"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "$ref": "#/definitions/models.ThematicLibrary"
-                                        }
-                                    }
-                                }
-                            ]
-                        }
-                    },
-                    "404": {
-                        "description": "Not Found",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            },
-            "put": {
-                "description": "更新数据主题库信息",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "数据主题库"
-                ],
-                "summary": "更新数据主题库",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "数据主题库ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "更新信息",
-                        "name": "library",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.ThematicLibrary"
-                        }
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "404": {
-                        "description": "Not Found",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            },
-            "delete": {
-                "description": "删除数据主题库",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "数据主题库"
-                ],
-                "summary": "删除数据主题库",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "数据主题库ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "404": {
-                        "description": "Not Found",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/thematic-libraries/{id}/publish": {
-            "post": {
-                "description": "将主题库状态更新为已发布",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "数据主题库"
-                ],
-                "summary": "发布数据主题库",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "数据主题库ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "404": {
-                        "description": "Not Found",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/thematic-sync/executions/{id}": {
-            "get": {
-                "description": "获取指定执行记录的详细信息",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "主题同步"
-                ],
-                "summary": "获取同步执行记录详情",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "执行记录ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "404": {
-                        "description": "Not Found",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/thematic-sync/tasks": {
-            "get": {
-                "description": "分页获取主题同步任务列表，支持多种过滤条件",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "主题同步"
-                ],
-                "summary": "获取同步任务列表",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "default": 1,
-                        "description": "页码",
-                        "name": "page",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "default": 10,
-                        "description": "每页大小",
-                        "name": "size",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "任务名称搜索",
-                        "name": "name",
-                        "in": "query"
-                    },
-                    {
-                        "enum": [
-                            "active",
-                            "inactive",
-                            "paused"
-                        ],
-                        "type": "string",
-                        "description": "状态过滤",
-                        "name": "status",
-                        "in": "query"
-                    },
-                    {
-                        "enum": [
-                            "manual",
-                            "one_time",
-                            "timed",
-                            "cron"
-                        ],
-                        "type": "string",
-                        "description": "同步模式过滤",
-                        "name": "sync_mode",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "主题库ID过滤",
-                        "name": "thematic_library_id",
-                        "in": "query"
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "获取成功",
-                        "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/controllers.APIResponse"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "$ref": "#/definitions/controllers.SyncTaskListResponse"
-                                        }
-                                    }
-                                }
-                            ]
-                        }
-                    },
-                    "400": {
-                        "description": "请求参数错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "服务器内部错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            },
-            "post": {
-                "description": "创建主题数据同步任务",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "主题同步"
-                ],
-                "summary": "创建同步任务",
-                "parameters": [
-                    {
-                        "description": "创建同步任务请求",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/thematic_library.CreateThematicSyncTaskRequest"
-                        }
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/thematic-sync/tasks/statistics": {
-            "get": {
-                "description": "获取主题同步任务的统计信息，包括总数、状态分布等",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "主题同步"
-                ],
-                "summary": "获取同步任务统计信息",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "主题库ID过滤",
-                        "name": "thematic_library_id",
-                        "in": "query"
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/thematic-sync/tasks/{id}": {
-            "get": {
-                "description": "获取指定ID的同步任务详细信息",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "主题同步"
-                ],
-                "summary": "获取同步任务详情",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "任务ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "404": {
-                        "description": "Not Found",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            },
-            "put": {
-                "description": "更新指定ID的同步任务信息",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "主题同步"
-                ],
-                "summary": "更新同步任务",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "任务ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "更新同步任务请求",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/thematic_library.UpdateThematicSyncTaskRequest"
-                        }
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "404": {
-                        "description": "Not Found",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            },
-            "delete": {
-                "description": "删除指定ID的同步任务",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "主题同步"
-                ],
-                "summary": "删除同步任务",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "任务ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "404": {
-                        "description": "Not Found",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/thematic-sync/tasks/{id}/activate": {
-            "post": {
-                "description": "激活同步任务，任务将可以被调度执行",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "主题同步"
-                ],
-                "summary": "激活同步任务",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "任务ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "404": {
-                        "description": "Not Found",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/thematic-sync/tasks/{id}/execute": {
-            "post": {
-                "description": "立即异步执行指定的同步任务，返回执行记录ID用于查询进度",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "主题同步"
-                ],
-                "summary": "执行同步任务",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "任务ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "执行同步任务请求",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/controllers.ExecuteSyncTaskRequest"
-                        }
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "返回执行记录ID",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "404": {
-                        "description": "Not Found",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/thematic-sync/tasks/{id}/executions": {
-            "get": {
-                "description": "分页获取指定任务的同步执行记录列表",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "主题同步"
-                ],
-                "summary": "获取同步执行记录列表",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "任务ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "default": 1,
-                        "description": "页码",
-                        "name": "page",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "default": 10,
-                        "description": "每页大小",
-                        "name": "size",
-                        "in": "query"
-                    },
-                    {
-                        "enum": [
-                            "running",
-                            "completed",
-                            "failed",
-                            "cancelled"
-                        ],
-                        "type": "string",
-                        "description": "执行状态过滤",
-                        "name": "status",
-                        "in": "query"
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "获取成功",
-                        "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/controllers.APIResponse"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "$ref": "#/definitions/controllers.SyncExecutionListResponse"
-                                        }
-                                    }
-                                }
-                            ]
-                        }
-                    },
-                    "400": {
-                        "description": "请求参数错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "服务器内部错误",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/thematic-sync/tasks/{id}/pause": {
-            "post": {
-                "description": "暂停同步任务，任务将不会被调度执行，但可以手动执行",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "主题同步"
-                ],
-                "summary": "暂停同步任务",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "任务ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "404": {
-                        "description": "Not Found",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/thematic-sync/tasks/{id}/status": {
-            "get": {
-                "description": "获取指定同步任务的当前状态信息",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "主题同步"
-                ],
-                "summary": "获取同步任务状态",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "任务ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "404": {
-                        "description": "Not Found",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.APIResponse"
-                        }
-                    }
-                }
-            }
-        }
-    },
-    "definitions": {
-        "basic_library.BatchDeleteResponse": {
-            "type": "object",
-            "properties": {
-                "deleted_count": {
-                    "type": "integer"
-                },
-                "errors": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    }
-                },
-                "failed_ids": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    }
-                }
-            }
-        },
-        "basic_library.PaginationInfo": {
-            "type": "object",
-            "properties": {
-                "page": {
-                    "type": "integer"
-                },
-                "size": {
-                    "type": "integer"
-                },
-                "total": {
-                    "type": "integer"
-                },
-                "total_pages": {
-                    "type": "integer"
-                }
-            }
-        },
-        "basic_library.SyncTaskExecutionListResponse": {
-            "type": "object",
-            "properties": {
-                "executions": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/models.SyncTaskExecution"
-                    }
-                },
-                "pagination": {
-                    "$ref": "#/definitions/basic_library.PaginationInfo"
-                }
-            }
-        },
-        "basic_library.SyncTaskListResponse": {
-            "type": "object",
-            "properties": {
-                "pagination": {
-                    "$ref": "#/definitions/basic_library.PaginationInfo"
-                },
-                "tasks": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/models.SyncTask"
-                    }
-                }
-            }
-        },
-        "basic_library.SyncTaskStatistics": {
-            "type": "object",
-            "properties": {
-                "cancelled_tasks": {
-                    "type": "integer"
-                },
-                "failed_tasks": {
-                    "type": "integer"
-                },
-                "pending_tasks": {
-                    "type": "integer"
-                },
-                "running_tasks": {
-                    "type": "integer"
-                },
-                "success_rate": {
-                    "type": "number"
-                },
-                "success_tasks": {
-                    "type": "integer"
-                },
-                "total_tasks": {
-                    "type": "integer"
-                }
-            }
-        },
-        "basic_library.SyncTaskStatusResponse": {
-            "type": "object",
-            "properties": {
-                "error": {
-                    "type": "string"
-                },
-                "processor": {
-                    "type": "string"
-                },
-                "progress": {
-                    "$ref": "#/definitions/models.SyncProgress"
-                },
-                "result": {
-                    "$ref": "#/definitions/models.SyncResult"
-                },
-                "start_time": {
-                    "type": "string"
-                },
-                "status": {
-                    "type": "string"
-                },
-                "task": {
-                    "$ref": "#/definitions/models.SyncTask"
-                }
-            }
-        },
-        "controllers.APIResponse": {
-            "type": "object",
-            "properties": {
-                "data": {},
-                "msg": {
-                    "type": "string",
-                    "example": "操作成功"
-                },
-                "status": {
-                    "type": "integer",
-                    "example": 0
-                }
-            }
-        },
-        "controllers.AccessLevelCount": {
-            "type": "object",
-            "properties": {
-                "access_level": {
-                    "type": "string"
-                },
-                "count": {
-                    "type": "integer"
-                }
-            }
-        },
-        "controllers.ApiApplicationListResponse": {
-            "type": "object",
-            "properties": {
-                "list": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/models.ApiApplication"
-                    }
-                },
-                "page": {
-                    "type": "integer"
-                },
-                "size": {
-                    "type": "integer"
-                },
-                "total": {
-                    "type": "integer"
-                }
-            }
-        },
-        "controllers.ApiApplicationStats": {
-            "type": "object",
-            "properties": {
-                "application_id": {
-                    "type": "string"
-                },
-                "application_name": {
-                    "type": "string"
-                },
-                "call_count": {
-                    "type": "integer"
-                },
-                "last_call_time": {
-                    "type": "string"
-                }
-            }
-        },
-        "controllers.ApiCallTrendData": {
-            "type": "object",
-            "properties": {
-                "call_count": {
-                    "type": "integer"
-                },
-                "date": {
-                    "type": "string"
-                }
-            }
-        },
-        "controllers.ApiRateLimitListResponse": {
-            "type": "object",
-            "properties": {
-                "list": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/models.ApiRateLimit"
-                    }
-                },
-                "page": {
-                    "type": "integer"
-                },
-                "size": {
-                    "type": "integer"
-                },
-                "total": {
-                    "type": "integer"
-                }
-            }
-        },
-        "controllers.ApiUsageLogListResponse": {
-            "type": "object",
-            "properties": {
-                "list": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/models.ApiUsageLog"
-                    }
-                },
-                "page": {
-                    "type": "integer"
-                },
-                "size": {
-                    "type": "integer"
-                },
-                "total": {
-                    "type": "integer"
-                }
-            }
-        },
-        "controllers.ApproveDataAccessRequestRequest": {
-            "type": "object",
-            "properties": {
-                "approved": {
-                    "type": "boolean"
-                },
-                "comment": {
-                    "type": "string"
-                }
-            }
-        },
-        "controllers.BasicLibraryListResponse": {
-            "type": "object",
-            "properties": {
-                "list": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/models.BasicLibrary"
-                    }
-                },
-                "page": {
-                    "type": "integer"
-                },
-                "size": {
-                    "type": "integer"
-                },
-                "total": {
-                    "type": "integer"
-                }
-            }
-        },
-        "controllers.BasicLibraryStats": {
-            "type": "object",
-            "properties": {
-                "active_data_sources": {
-                    "description": "活跃数据源",
-                    "type": "integer"
-                },
-                "active_interfaces": {
-                    "description": "活跃接口",
-                    "type": "integer"
-                },
-                "active_libraries": {
-                    "description": "活跃数",
-                    "type": "integer"
-                },
-                "category_breakdown": {
-                    "description": "分类统计",
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/controllers.CategoryCount"
-                    }
-                },
-                "inactive_libraries": {
-                    "description": "非活跃数",
-                    "type": "integer"
-                },
-                "recent_libraries": {
-                    "description": "最近创建的库",
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/controllers.RecentLibraryInfo"
-                    }
-                },
-                "top_data_sources": {
-                    "description": "数据源使用排行",
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/controllers.DataSourceUsageStats"
-                    }
-                },
-                "total_data_sources": {
-                    "description": "数据源总数",
-                    "type": "integer"
-                },
-                "total_interfaces": {
-                    "description": "接口总数",
-                    "type": "integer"
-                },
-                "total_libraries": {
-                    "description": "总数",
-                    "type": "integer"
-                }
-            }
-        },
-        "controllers.BatchDeleteRequest": {
-            "type": "object",
-            "required": [
-                "task_ids"
-            ],
-            "properties": {
-                "task_ids": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    }
-                }
-            }
-        },
-        "controllers.BatchUpdateConfigsRequest": {
-            "type": "object",
-            "required": [
-                "configs"
-            ],
-            "properties": {
-                "configs": {
-                    "type": "array",
-                    "items": {
-                        "type": "object",
-                        "required": [
-                            "key",
-                            "value"
-                        ],
-                        "properties": {
-                            "description": {
-                                "type": "string"
-                            },
-                            "key": {
-                                "type": "string"
-                            },
-                            "value": {
-                                "type": "string"
-                            }
-                        }
-                    }
-                }
-            }
-        },
-        "controllers.BroadcastEventRequest": {
-            "type": "object",
-            "properties": {
-                "data": {
-                    "type": "object",
-                    "additionalProperties": true
-                },
-                "event_type": {
-                    "type": "string",
-                    "example": "system_notification"
-                }
-            }
-        },
-        "controllers.CategoryCount": {
-            "type": "object",
-            "properties": {
-                "category": {
-                    "type": "string"
-                },
-                "count": {
-                    "type": "integer"
-                }
-            }
-        },
-        "controllers.ColumnInfo": {
-            "type": "object",
-            "properties": {
-                "comment": {
-                    "type": "string"
-                },
-                "data_type": {
-                    "type": "string"
-                },
-                "default_value": {},
-                "format": {
-                    "type": "string"
-                },
-                "id": {
-                    "type": "string"
-                },
-                "identity_generation": {
-                    "type": "string"
-                },
-                "is_generated": {
-                    "type": "boolean"
-                },
-                "is_identity": {
-                    "type": "boolean"
-                },
-                "is_nullable": {
-                    "type": "boolean"
-                },
-                "is_updatable": {
-                    "type": "boolean"
-                },
-                "name": {
-                    "type": "string"
-                }
-            }
-        },
-        "controllers.CreateApiApplicationRequest": {
-            "type": "object",
-            "required": [
-                "contact_person",
-                "contact_phone",
-                "name",
-                "path",
-                "thematic_library_id"
-            ],
-            "properties": {
-                "contact_person": {
-                    "type": "string"
-                },
-                "contact_phone": {
-                    "type": "string"
-                },
-                "description": {
-                    "type": "string"
-                },
-                "name": {
-                    "type": "string"
-                },
-                "path": {
-                    "type": "string"
-                },
-                "thematic_library_id": {
-                    "type": "string"
-                }
-            }
-        },
-        "controllers.CreateApiInterfaceRequest": {
-            "type": "object",
-            "required": [
-                "api_application_id",
-                "path",
-                "thematic_interface_id"
-            ],
-            "properties": {
-                "api_application_id": {
-                    "type": "string"
-                },
-                "description": {
-                    "type": "string"
-                },
-                "masking_rules": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/models.DataMaskingConfig"
-                    }
-                },
-                "path": {
-                    "type": "string"
-                },
-                "thematic_interface_id": {
-                    "type": "string"
-                }
-            }
-        },
-        "controllers.CreateApiKeyRequest": {
-            "type": "object",
-            "required": [
-                "application_ids",
-                "name"
-            ],
-            "properties": {
-                "application_ids": {
-                    "description": "关联的应用ID列表",
-                    "type": "array",
-                    "minItems": 1,
-                    "items": {
-                        "type": "string"
-                    }
-                },
-                "description": {
-                    "type": "string"
-                },
-                "expires_at": {
-                    "type": "string"
-                },
-                "name": {
-                    "type": "string"
-                }
-            }
-        },
-        "controllers.CreateApiKeyResponse": {
-            "type": "object",
-            "properties": {
-                "api_key": {
-                    "$ref": "#/definitions/models.ApiKey"
-                },
-                "key_value": {
-                    "description": "完整的Key值，仅返回一次",
-                    "type": "string"
-                }
-            }
-        },
-        "controllers.CreateApiRateLimitRequest": {
-            "type": "object",
-            "required": [
-                "max_requests",
-                "rate_limit_type",
-                "time_window"
-            ],
-            "properties": {
-                "description": {
-                    "type": "string"
-                },
-                "max_requests": {
-                    "description": "最大请求数",
-                    "type": "integer"
-                },
-                "rate_limit_type": {
-                    "description": "global/api_key/application",
-                    "type": "string"
-                },
-                "target_id": {
-                    "description": "api_key_id或application_id，全局时为null",
-                    "type": "string"
-                },
-                "time_window": {
-                    "description": "时间窗口（秒）",
-                    "type": "integer"
-                }
-            }
-        },
-        "controllers.CreateInterfaceTableIndexRequest": {
-            "type": "object",
-            "required": [
-                "columns",
-                "index_name",
-                "interface_id"
-            ],
-            "properties": {
-                "columns": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    },
-                    "example": [
-                        "email",
-                        "created_at"
-                    ]
-                },
-                "index_name": {
-                    "type": "string",
-                    "example": "idx_user_email"
-                },
-                "index_type": {
-                    "description": "btree, hash, gin, gist, etc.",
-                    "type": "string",
-                    "example": "btree"
-                },
-                "interface_id": {
-                    "type": "string",
-                    "example": "550e8400-e29b-41d4-a716-446655440000"
-                },
-                "is_unique": {
-                    "type": "boolean",
-                    "example": false
-                }
-            }
-        },
-        "controllers.CreateThematicInterfaceTableIndexRequest": {
-            "type": "object",
-            "required": [
-                "columns",
-                "index_name",
-                "interface_id"
-            ],
-            "properties": {
-                "columns": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    },
-                    "example": [
-                        "order_id",
-                        "created_at"
-                    ]
-                },
-                "index_name": {
-                    "type": "string",
-                    "example": "idx_order_created_at"
-                },
-                "index_type": {
-                    "description": "btree, hash, gin, gist, etc.",
-                    "type": "string",
-                    "example": "btree"
-                },
-                "interface_id": {
-                    "type": "string",
-                    "example": "550e8400-e29b-41d4-a716-446655440000"
-                },
-                "is_unique": {
-                    "type": "boolean",
-                    "example": false
-                }
-            }
-        },
-        "controllers.CreateThematicInterfaceViewRequest": {
-            "type": "object",
-            "required": [
-                "interface_id",
-                "view_sql"
-            ],
-            "properties": {
-                "interface_id": {
-                    "type": "string",
-                    "example": "550e8400-e29b-41d4-a716-446655440000"
-                },
-                "view_sql": {
-                    "type": "string",
-                    "example": "SELECT * FROM users WHERE status = 'active'"
-                }
-            }
-        },
-        "controllers.DashboardOverviewResponse": {
-            "type": "object",
-            "properties": {
-                "basic_library_stats": {
-                    "description": "基础库统计",
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/controllers.BasicLibraryStats"
-                        }
-                    ]
-                },
-                "data_quality_stats": {
-                    "description": "数据质量统计",
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/controllers.DataQualityStats"
-                        }
-                    ]
-                },
-                "data_sharing_stats": {
-                    "description": "数据共享统计",
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/controllers.DataSharingStats"
-                        }
-                    ]
-                },
-                "sync_task_stats": {
-                    "description": "同步任务统计",
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/controllers.SyncTaskStats"
-                        }
-                    ]
-                },
-                "system_activity_stats": {
-                    "description": "系统活动统计",
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/controllers.SystemActivityStats"
-                        }
-                    ]
-                },
-                "thematic_library_stats": {
-                    "description": "主题库统计",
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/controllers.ThematicLibraryStats"
-                        }
-                    ]
-                },
-                "updated_at": {
-                    "description": "更新时间",
-                    "type": "string"
-                }
-            }
-        },
-        "controllers.DataAccessRequestListResponse": {
-            "type": "object",
-            "properties": {
-                "list": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/models.DataAccessRequest"
-                    }
-                },
-                "page": {
-                    "type": "integer"
-                },
-                "size": {
-                    "type": "integer"
-                },
-                "total": {
-                    "type": "integer"
-                }
-            }
-        },
-        "controllers.DataGovernanceMetadataResponse": {
-            "type": "object",
-            "properties": {
-                "cleansing_rule_types": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/meta.CleansingRuleType"
-                    }
-                },
-                "lineage_relation_types": {
-                    "description": "血缘关系相关",
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/meta.LineageRelationType"
-                    }
-                },
-                "masking_types": {
-                    "description": "脱敏和清洗相关",
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/meta.DataMaskingType"
-                    }
-                },
-                "metadata_types": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/meta.MetadataType"
-                    }
-                },
-                "object_types": {
-                    "description": "对象和任务相关",
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/meta.DataGovernanceObjectType"
-                    }
-                },
-                "quality_check_statuses": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/meta.QualityCheckStatus"
-                    }
-                },
-                "quality_issue_severities": {
-                    "description": "问题管理相关",
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/meta.QualityIssueSeverity"
-                    }
-                },
-                "quality_issue_statuses": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/meta.QualityIssueStatus"
-                    }
-                },
-                "quality_metric_types": {
-                    "description": "质量指标和报告相关",
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/meta.QualityMetricType"
-                    }
-                },
-                "quality_report_types": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/meta.QualityReportType"
-                    }
-                },
-                "quality_rule_types": {
-                    "description": "质量规则相关",
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/meta.QualityRuleType"
-                    }
-                },
-                "task_statuses": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/meta.TaskStatus"
-                    }
-                },
-                "task_types": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/meta.TaskType"
-                    }
-                },
-                "transformation_rule_types": {
-                    "description": "转换和校验相关",
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/meta.TransformationRuleType"
-                    }
-                },
-                "validation_rule_types": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/meta.ValidationRuleType"
-                    }
-                }
-            }
-        },
-        "controllers.DataInterfaceListResponse": {
-            "type": "object",
-            "properties": {
-                "list": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/models.DataInterface"
-                    }
-                },
-                "page": {
-                    "type": "integer"
-                },
-                "size": {
-                    "type": "integer"
-                },
-                "total": {
-                    "type": "integer"
-                }
-            }
-        },
-        "controllers.DataQualityStats": {
-            "type": "object",
-            "properties": {
-                "avg_quality_score": {
-                    "description": "平均质量分数",
-                    "type": "number"
-                },
-                "enabled_quality_rules": {
-                    "description": "启用的规则",
-                    "type": "integer"
-                },
-                "issue_severity_stats": {
-                    "description": "问题严重程度统计",
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/controllers.IssueSeverityCount"
-                    }
-                },
-                "issue_type_stats": {
-                    "description": "问题类型统计",
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/controllers.IssueTypeCount"
-                    }
-                },
-                "quality_issue_count": {
-                    "description": "质量问题数量",
-                    "type": "integer"
-                },
-                "quality_trend_data": {
-                    "description": "质量趋势",
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/controllers.QualityTrendData"
-                    }
-                },
-                "recent_quality_reports": {
-                    "description": "最近质量报告",
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/controllers.RecentQualityReport"
-                    }
-                },
-                "rule_type_breakdown": {
-                    "description": "规则类型分布",
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/controllers.RuleTypeCount"
-                    }
-                },
-                "running_quality_tasks": {
-                    "description": "运行中的任务",
-                    "type": "integer"
-                },
-                "total_cleansing_rules": {
-                    "description": "清洗规则总数",
-                    "type": "integer"
-                },
-                "total_masking_rules": {
-                    "description": "脱敏规则总数",
-                    "type": "integer"
-                },
-                "total_quality_reports": {
-                    "description": "质量报告总数",
-                    "type": "integer"
-                },
-                "total_quality_rules": {
-                    "description": "质量规则总数",
-                    "type": "integer"
-                },
-                "total_quality_tasks": {
-                    "description": "质量检测任务总数",
-                    "type": "integer"
-                }
-            }
-        },
-        "controllers.DataSharingStats": {
-            "type": "object",
-            "properties": {
-                "active_api_applications": {
-                    "description": "活跃应用",
-                    "type": "integer"
-                },
-                "active_api_keys": {
-                    "description": "活跃密钥",
-                    "type": "integer"
-                },
-                "active_data_subscriptions": {
-                    "description": "活跃订阅",
-                    "type": "integer"
-                },
-                "api_call_trend_data": {
-                    "description": "API调用趋势",
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/controllers.ApiCallTrendData"
-                    }
-                },
-                "approved_access_requests": {
-                    "description": "已批准申请",
-                    "type": "integer"
-                },
-                "error_rate_stats": {
-                    "description": "错误率统计",
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/controllers.ErrorRateStatistics"
-                        }
-                    ]
-                },
-                "pending_access_requests": {
-                    "description": "待审批申请",
-                    "type": "integer"
-                },
-                "recent_api_usage_logs": {
-                    "description": "最近API使用日志",
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/controllers.RecentApiUsageLog"
-                    }
-                },
-                "response_time_stats": {
-                    "description": "响应时间统计",
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/controllers.ResponseTimeStatistics"
-                        }
-                    ]
-                },
-                "today_api_calls": {
-                    "description": "今日调用",
-                    "type": "integer"
-                },
-                "top_api_applications": {
-                    "description": "热门应用",
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/controllers.ApiApplicationStats"
-                    }
-                },
-                "total_access_requests": {
-                    "description": "访问申请总数",
-                    "type": "integer"
-                },
-                "total_api_applications": {
-                    "description": "API应用总数",
-                    "type": "integer"
-                },
-                "total_api_calls": {
-                    "description": "API调用总数",
-                    "type": "integer"
-                },
-                "total_api_interfaces": {
-                    "description": "API接口总数",
-                    "type": "integer"
-                },
-                "total_api_keys": {
-                    "description": "API密钥总数",
-                    "type": "integer"
-                },
-                "total_data_subscriptions": {
-                    "description": "数据订阅总数",
-                    "type": "integer"
-                }
-            }
-        },
-        "controllers.DataSourceListResponse": {
-            "type": "object",
-            "properties": {
-                "list": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/models.DataSource"
-                    }
-                },
-                "page": {
-                    "type": "integer"
-                },
-                "size": {
-                    "type": "integer"
-                },
-                "total": {
-                    "type": "integer"
-                }
-            }
-        },
-        "controllers.DataSourceTestRequest": {
-            "type": "object",
-            "required": [
-                "data_source_id",
-                "test_type"
-            ],
-            "properties": {
-                "config": {
-                    "description": "额外测试配置",
-                    "type": "object",
-                    "additionalProperties": true
-                },
-                "data_source_id": {
-                    "type": "string",
-                    "example": "550e8400-e29b-41d4-a716-446655440000"
-                },
-                "test_type": {
-                    "description": "connection, data_preview",
-                    "type": "string",
-                    "example": "connection"
-                }
-            }
-        },
-        "controllers.DataSourceTestResponse": {
-            "type": "object",
-            "properties": {
-                "data": {
-                    "description": "预览数据"
-                },
-                "duration": {
-                    "description": "测试耗时（毫秒）",
-                    "type": "integer",
-                    "example": 250
-                },
-                "error": {
-                    "description": "错误信息",
-                    "type": "string",
-                    "example": ""
-                },
-                "message": {
-                    "type": "string",
-                    "example": "连接成功"
-                },
-                "metadata": {
-                    "description": "元数据信息",
-                    "type": "object",
-                    "additionalProperties": true
-                },
-                "success": {
-                    "type": "boolean",
-                    "example": true
-                },
-                "suggestions": {
-                    "description": "优化建议",
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    },
-                    "example": [
-                        "检查网络连接",
-                        "验证数据库权限"
-                    ]
-                },
-                "test_type": {
-                    "type": "string",
-                    "example": "connection"
-                }
-            }
-        },
-        "controllers.DataSourceUsageStats": {
-            "type": "object",
-            "properties": {
-                "data_source_id": {
-                    "type": "string"
-                },
-                "data_source_name": {
-                    "type": "string"
-                },
-                "last_used_at": {
-                    "type": "string"
-                },
-                "usage_count": {
-                    "type": "integer"
-                }
-            }
-        },
-        "controllers.DataSubscriptionListResponse": {
-            "type": "object",
-            "properties": {
-                "list": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/models.DataSubscription"
-                    }
-                },
-                "page": {
-                    "type": "integer"
-                },
-                "size": {
-                    "type": "integer"
-                },
-                "total": {
-                    "type": "integer"
-                }
-            }
-        },
-        "controllers.DomainCount": {
-            "type": "object",
-            "properties": {
-                "count": {
-                    "type": "integer"
-                },
-                "domain": {
-                    "type": "string"
-                }
-            }
-        },
-        "controllers.DropInterfaceTableIndexRequest": {
-            "type": "object",
-            "required": [
-                "index_name",
-                "interface_id"
-            ],
-            "properties": {
-                "index_name": {
-                    "type": "string",
-                    "example": "idx_user_email"
-                },
-                "interface_id": {
-                    "type": "string",
-                    "example": "550e8400-e29b-41d4-a716-446655440000"
-                }
-            }
-        },
-        "controllers.DropThematicInterfaceTableIndexRequest": {
-            "type": "object",
-            "required": [
-                "index_name",
-                "interface_id"
-            ],
-            "properties": {
-                "index_name": {
-                    "type": "string",
-                    "example": "idx_order_created_at"
-                },
-                "interface_id": {
-                    "type": "string",
-                    "example": "550e8400-e29b-41d4-a716-446655440000"
-                }
-            }
-        },
-        "controllers.ErrorRateStatistics": {
-            "type": "object",
-            "properties": {
-                "error_rate": {
-                    "description": "错误率百分比",
-                    "type": "number"
-                },
-                "error_requests": {
-                    "type": "integer"
-                },
-                "total_requests": {
-                    "type": "integer"
-                }
-            }
-        },
-        "controllers.EventHistoryListResponse": {
-            "type": "object",
-            "properties": {
-                "list": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/models.SSEEvent"
-                    }
-                },
-                "page": {
-                    "type": "integer"
-                },
-                "size": {
-                    "type": "integer"
-                },
-                "total": {
-                    "type": "integer"
-                }
-            }
-        },
-        "controllers.ExecuteSyncTaskRequest": {
-            "type": "object",
-            "required": [
-                "executed_by"
-            ],
-            "properties": {
-                "executed_by": {
-                    "type": "string",
-                    "example": "admin"
-                },
-                "execution_type": {
-                    "description": "manual, auto",
-                    "type": "string",
-                    "example": "manual"
-                },
-                "options": {
-                    "description": "执行选项",
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/thematic_library.SyncExecutionOptions"
-                        }
-                    ]
-                }
-            }
-        },
-        "controllers.ExecutionTrendData": {
-            "type": "object",
-            "properties": {
-                "date": {
-                    "type": "string"
-                },
-                "failure_count": {
-                    "type": "integer"
-                },
-                "success_count": {
-                    "type": "integer"
-                }
-            }
-        },
-        "controllers.FailureReasonStats": {
-            "type": "object",
-            "properties": {
-                "count": {
-                    "type": "integer"
-                },
-                "reason": {
-                    "type": "string"
-                }
-            }
-        },
-        "controllers.HealthResponse": {
-            "type": "object",
-            "properties": {
-                "service": {
-                    "type": "string",
-                    "example": "datahub-service"
-                },
-                "status": {
-                    "type": "string",
-                    "example": "ok"
-                },
-                "timestamp": {
-                    "type": "string",
-                    "example": "2024-01-01T00:00:00Z"
-                },
-                "version": {
-                    "type": "string",
-                    "example": "1.0.0"
-                }
-            }
-        },
-        "controllers.ImportCSVRequest": {
-            "type": "object",
-            "required": [
-                "csv_content",
-                "interface_id"
-            ],
-            "properties": {
-                "csv_content": {
-                    "type": "string",
-                    "example": "id,name,age\n1,张三,25\n2,李四,30"
-                },
-                "interface_id": {
-                    "type": "string",
-                    "example": "550e8400-e29b-41d4-a716-446655440000"
-                }
-            }
-        },
-        "controllers.ImportCSVResponse": {
-            "type": "object",
-            "properties": {
-                "error_messages": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    }
-                },
-                "failed_rows": {
-                    "type": "integer",
-                    "example": 0
-                },
-                "imported_rows": {
-                    "type": "integer",
-                    "example": 100
-                },
-                "message": {
-                    "type": "string",
-                    "example": "CSV导入成功"
-                },
-                "success": {
-                    "type": "boolean",
-                    "example": true
-                },
-                "total_rows": {
-                    "type": "integer",
-                    "example": 100
-                }
-            }
-        },
-        "controllers.InterfaceTestRequest": {
-            "type": "object",
-            "required": [
-                "interface_id",
-                "test_type"
-            ],
-            "properties": {
-                "interface_id": {
-                    "type": "string",
-                    "example": "550e8400-e29b-41d4-a716-446655440000"
-                },
-                "options": {
-                    "description": "测试选项",
-                    "type": "object",
-                    "additionalProperties": true
-                },
-                "parameters": {
-                    "description": "测试参数",
-                    "type": "object",
-                    "additionalProperties": true
-                },
-                "test_type": {
-                    "description": "data_fetch, performance, validation",
-                    "type": "string",
-                    "example": "data_fetch"
-                }
-            }
-        },
-        "controllers.InterfaceTestResponse": {
-            "type": "object",
-            "properties": {
-                "column_count": {
-                    "description": "字段数",
-                    "type": "integer",
-                    "example": 8
-                },
-                "data": {
-                    "description": "返回数据"
-                },
-                "data_types": {
-                    "description": "字段类型",
-                    "type": "object",
-                    "additionalProperties": {
-                        "type": "string"
-                    }
-                },
-                "duration": {
-                    "description": "调用耗时（毫秒）",
-                    "type": "integer",
-                    "example": 150
-                },
-                "error": {
-                    "description": "错误信息",
-                    "type": "string",
-                    "example": ""
-                },
-                "message": {
-                    "type": "string",
-                    "example": "接口调用成功"
-                },
-                "performance": {
-                    "description": "性能指标",
-                    "type": "object",
-                    "additionalProperties": true
-                },
-                "row_count": {
-                    "description": "数据行数",
-                    "type": "integer",
-                    "example": 25
-                },
-                "success": {
-                    "type": "boolean",
-                    "example": true
-                },
-                "test_type": {
-                    "type": "string",
-                    "example": "data_fetch"
-                },
-                "validation": {
-                    "description": "数据验证结果",
-                    "type": "object",
-                    "additionalProperties": true
-                },
-                "warnings": {
-                    "description": "警告信息",
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    },
-                    "example": [
-                        "数据量较大，建议分页查询"
-                    ]
-                }
-            }
-        },
-        "controllers.InterfaceTypeCount": {
-            "type": "object",
-            "properties": {
-                "count": {
-                    "type": "integer"
-                },
-                "interface_type": {
-                    "type": "string"
-                }
-            }
-        },
-        "controllers.IssueSeverityCount": {
-            "type": "object",
-            "properties": {
-                "count": {
-                    "type": "integer"
-                },
-                "severity": {
-                    "type": "string"
-                }
-            }
-        },
-        "controllers.IssueTypeCount": {
-            "type": "object",
-            "properties": {
-                "count": {
-                    "type": "integer"
-                },
-                "issue_type": {
-                    "type": "string"
-                }
-            }
-        },
-        "controllers.LibraryAccessStats": {
-            "type": "object",
-            "properties": {
-                "access_count": {
-                    "type": "integer"
-                },
-                "library_id": {
-                    "type": "string"
-                },
-                "library_name": {
-                    "type": "string"
-                }
-            }
-        },
-        "controllers.LibraryTablesResponse": {
-            "type": "object",
-            "properties": {
-                "library_id": {
-                    "type": "string"
-                },
-                "library_name": {
-                    "type": "string"
-                },
-                "library_type": {
-                    "description": "basic_library, thematic_library",
-                    "type": "string"
-                },
-                "schema_name": {
-                    "type": "string"
-                },
-                "tables": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/controllers.TableInfo"
-                    }
-                },
-                "total_count": {
-                    "type": "integer"
-                }
-            }
-        },
-        "controllers.LibraryTrendData": {
-            "type": "object",
-            "properties": {
-                "count": {
-                    "type": "integer"
-                },
-                "date": {
-                    "type": "string"
-                }
-            }
-        },
-        "controllers.OperationTypeCount": {
-            "type": "object",
-            "properties": {
-                "count": {
-                    "type": "integer"
-                },
-                "operation_type": {
-                    "type": "string"
-                }
-            }
-        },
-        "controllers.PeakUsageTimeInfo": {
-            "type": "object",
-            "properties": {
-                "avg_operations": {
-                    "description": "该时段平均操作数",
-                    "type": "number"
-                },
-                "hour": {
-                    "description": "小时 (0-23)",
-                    "type": "integer"
-                }
-            }
-        },
-        "controllers.PrimaryKey": {
-            "type": "object",
-            "properties": {
-                "columns": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    }
-                },
-                "id": {
-                    "type": "string"
-                },
-                "name": {
-                    "type": "string"
-                }
-            }
-        },
-        "controllers.QualityTrendData": {
-            "type": "object",
-            "properties": {
-                "avg_score": {
-                    "type": "number"
-                },
-                "date": {
-                    "type": "string"
-                },
-                "issue_count": {
-                    "type": "integer"
-                }
-            }
-        },
-        "controllers.RecentApiUsageLog": {
-            "type": "object",
-            "properties": {
-                "api_path": {
-                    "type": "string"
-                },
-                "created_at": {
-                    "type": "string"
-                },
-                "log_id": {
-                    "type": "string"
-                },
-                "method": {
-                    "type": "string"
-                },
-                "request_ip": {
-                    "type": "string"
-                },
-                "response_time": {
-                    "type": "integer"
-                },
-                "status_code": {
-                    "type": "integer"
-                }
-            }
-        },
-        "controllers.RecentExecutionInfo": {
-            "type": "object",
-            "properties": {
-                "end_time": {
-                    "type": "string"
-                },
-                "error_message": {
-                    "type": "string"
-                },
-                "execution_id": {
-                    "type": "string"
-                },
-                "record_count": {
-                    "type": "integer"
-                },
-                "start_time": {
-                    "type": "string"
-                },
-                "status": {
-                    "type": "string"
-                },
-                "task_id": {
-                    "type": "string"
-                },
-                "task_name": {
-                    "type": "string"
-                }
-            }
-        },
-        "controllers.RecentLibraryInfo": {
-            "type": "object",
-            "properties": {
-                "created_at": {
-                    "type": "string"
-                },
-                "created_by": {
-                    "type": "string"
-                },
-                "id": {
-                    "type": "string"
-                },
-                "name": {
-                    "type": "string"
-                },
-                "status": {
-                    "type": "string"
-                }
-            }
-        },
-        "controllers.RecentQualityReport": {
-            "type": "object",
-            "properties": {
-                "generated_at": {
-                    "type": "string"
-                },
-                "object_type": {
-                    "type": "string"
-                },
-                "quality_score": {
-                    "type": "number"
-                },
-                "report_id": {
-                    "type": "string"
-                },
-                "report_name": {
-                    "type": "string"
-                }
-            }
-        },
-        "controllers.RecentSystemLog": {
-            "type": "object",
-            "properties": {
-                "log_id": {
-                    "type": "string"
-                },
-                "object_type": {
-                    "type": "string"
-                },
-                "operation_result": {
-                    "type": "string"
-                },
-                "operation_time": {
-                    "type": "string"
-                },
-                "operation_type": {
-                    "type": "string"
-                },
-                "operator_name": {
-                    "type": "string"
-                }
-            }
-        },
-        "controllers.RecentThematicLibraryInfo": {
-            "type": "object",
-            "properties": {
-                "category": {
-                    "type": "string"
-                },
-                "created_at": {
-                    "type": "string"
-                },
-                "created_by": {
-                    "type": "string"
-                },
-                "id": {
-                    "type": "string"
-                },
-                "name_en": {
-                    "type": "string"
-                },
-                "name_zh": {
-                    "type": "string"
-                },
-                "status": {
-                    "type": "string"
-                }
-            }
-        },
-        "controllers.Relationship": {
-            "type": "object",
-            "properties": {
-                "constraint_name": {
-                    "type": "string"
-                },
-                "id": {
-                    "type": "string"
-                },
-                "source_column_name": {
-                    "type": "string"
-                },
-                "source_table_name": {
-                    "type": "string"
-                },
-                "target_column_name": {
-                    "type": "string"
-                },
-                "target_table_name": {
-                    "type": "string"
-                }
-            }
-        },
-        "controllers.ResponseTimeStatistics": {
-            "type": "object",
-            "properties": {
-                "avg_response_time": {
-                    "description": "平均响应时间(ms)",
-                    "type": "number"
-                },
-                "max_response_time": {
-                    "description": "最大响应时间(ms)",
-                    "type": "integer"
-                },
-                "min_response_time": {
-                    "description": "最小响应时间(ms)",
-                    "type": "integer"
-                },
-                "p50_response_time": {
-                    "description": "P50响应时间(ms)",
-                    "type": "number"
-                },
-                "p95_response_time": {
-                    "description": "P95响应时间(ms)",
-                    "type": "number"
-                },
-                "p99_response_time": {
-                    "description": "P99响应时间(ms)",
-                    "type": "number"
-                }
-            }
-        },
-        "controllers.RuleTypeCount": {
-            "type": "object",
-            "properties": {
-                "count": {
-                    "type": "integer"
-                },
-                "rule_type": {
-                    "type": "string"
-                }
-            }
-        },
-        "controllers.SSEConnectionListResponse": {
-            "type": "object",
-            "properties": {
-                "list": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/models.SSEConnection"
-                    }
-                },
-                "page": {
-                    "type": "integer"
-                },
-                "size": {
-                    "type": "integer"
-                },
-                "total": {
-                    "type": "integer"
-                }
-            }
-        },
-        "controllers.SendEventRequest": {
-            "type": "object",
-            "properties": {
-                "data": {
-                    "type": "object",
-                    "additionalProperties": true
-                },
-                "event_type": {
-                    "type": "string",
-                    "example": "system_notification"
-                },
-                "user_name": {
-                    "type": "string",
-                    "example": "admin"
-                }
-            }
-        },
-        "controllers.SyncExecutionListResponse": {
-            "type": "object",
-            "properties": {
-                "list": {
-                    "type": "array",
-                    "items": {}
-                },
-                "page": {
-                    "type": "integer"
-                },
-                "size": {
-                    "type": "integer"
-                },
-                "total": {
-                    "type": "integer"
-                }
-            }
-        },
-        "controllers.SyncTaskCreateRequest": {
-            "type": "object",
-            "required": [
-                "data_source_id",
-                "interface_ids",
-                "library_id",
-                "task_type",
-                "trigger_type"
-            ],
-            "properties": {
-                "config": {
-                    "description": "任务级别的全局配置",
-                    "type": "object",
-                    "additionalProperties": true
-                },
-                "created_by": {
-                    "type": "string",
-                    "example": "admin"
-                },
-                "cron_expression": {
-                    "type": "string",
-                    "example": "0 0 * * *"
-                },
-                "data_source_id": {
-                    "type": "string",
-                    "example": "550e8400-e29b-41d4-a716-446655440000"
-                },
-                "interface_configs": {
-                    "description": "接口级别的配置，可选",
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/controllers.SyncTaskInterfaceConfig"
-                    }
-                },
-                "interface_id": {
-                    "description": "向后兼容字段（已废弃，但保留以支持旧版本API）",
-                    "type": "string",
-                    "example": "550e8400-e29b-41d4-a716-446655440000"
-                },
-                "interface_ids": {
-                    "type": "array",
-                    "minItems": 1,
-                    "items": {
-                        "type": "string"
-                    },
-                    "example": [
-                        "[\"550e8400-e29b-41d4-a716-446655440000\"]"
-                    ]
-                },
-                "interval_seconds": {
-                    "type": "integer",
-                    "example": 3600
-                },
-                "library_id": {
-                    "type": "string",
-                    "example": "550e8400-e29b-41d4-a716-446655440000"
-                },
-                "scheduled_time": {
-                    "type": "string",
-                    "example": "2024-01-01T00:00:00Z"
-                },
-                "task_type": {
-                    "type": "string",
-                    "example": "batch_sync"
-                },
-                "trigger_type": {
-                    "type": "string",
-                    "example": "manual"
-                }
-            }
-        },
-        "controllers.SyncTaskInterfaceConfig": {
-            "type": "object",
-            "required": [
-                "interface_id"
-            ],
-            "properties": {
-                "config": {
-                    "description": "接口级别的特殊配置",
-                    "type": "object",
-                    "additionalProperties": true
-                },
-                "interface_id": {
-                    "type": "string",
-                    "example": "550e8400-e29b-41d4-a716-446655440000"
-                }
-            }
-        },
-        "controllers.SyncTaskListResponse": {
-            "type": "object",
-            "properties": {
-                "list": {
-                    "type": "array",
-                    "items": {}
-                },
-                "page": {
-                    "type": "integer"
-                },
-                "size": {
-                    "type": "integer"
-                },
-                "total": {
-                    "type": "integer"
-                }
-            }
-        },
-        "controllers.SyncTaskStats": {
-            "type": "object",
-            "properties": {
-                "avg_execution_time": {
-                    "description": "平均执行时间(秒)",
-                    "type": "number"
-                },
-                "completed_tasks": {
-                    "description": "已完成",
-                    "type": "integer"
-                },
-                "execution_trend_data": {
-                    "description": "执行趋势数据",
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/controllers.ExecutionTrendData"
-                    }
-                },
-                "failed_tasks": {
-                    "description": "失败",
-                    "type": "integer"
-                },
-                "failure_reasons": {
-                    "description": "失败原因统计",
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/controllers.FailureReasonStats"
-                    }
-                },
-                "pending_tasks": {
-                    "description": "等待中",
-                    "type": "integer"
-                },
-                "recent_executions": {
-                    "description": "最近执行记录",
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/controllers.RecentExecutionInfo"
-                    }
-                },
-                "running_tasks": {
-                    "description": "运行中",
-                    "type": "integer"
-                },
-                "task_type_breakdown": {
-                    "description": "任务类型分布",
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/controllers.TaskTypeCount"
-                    }
-                },
-                "today_executions": {
-                    "description": "今日执行次数",
-                    "type": "integer"
-                },
-                "today_success_rate": {
-                    "description": "今日成功率",
-                    "type": "number"
-                },
-                "total_data_synced": {
-                    "description": "总同步数据量",
-                    "type": "integer"
-                },
-                "total_tasks": {
-                    "description": "总任务数",
-                    "type": "integer"
-                },
-                "trigger_type_stats": {
-                    "description": "触发类型统计",
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/controllers.TriggerTypeCount"
-                    }
-                }
-            }
-        },
-        "controllers.SyncTaskUpdateRequest": {
-            "type": "object",
-            "properties": {
-                "config": {
-                    "description": "任务级别的全局配置",
-                    "type": "object",
-                    "additionalProperties": true
-                },
-                "cron_expression": {
-                    "type": "string",
-                    "example": "0 0 * * *"
-                },
-                "interface_configs": {
-                    "description": "更新接口级别的配置",
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/controllers.SyncTaskInterfaceConfig"
-                    }
-                },
-                "interface_ids": {
-                    "description": "更新接口列表",
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    }
-                },
-                "interval_seconds": {
-                    "type": "integer",
-                    "example": 3600
-                },
-                "scheduled_time": {
-                    "type": "string",
-                    "example": "2024-01-01T00:00:00Z"
-                },
-                "status": {
-                    "description": "draft, active, paused",
-                    "type": "string",
-                    "example": "active"
-                },
-                "task_type": {
-                    "type": "string",
-                    "example": "batch_sync"
-                },
-                "trigger_type": {
-                    "type": "string",
-                    "example": "manual"
-                },
-                "updated_by": {
-                    "type": "string",
-                    "example": "admin"
-                }
-            }
-        },
-        "controllers.SystemActivityStats": {
-            "type": "object",
-            "properties": {
-                "active_users": {
-                    "description": "活跃用户",
-                    "type": "integer"
-                },
-                "operation_type_breakdown": {
-                    "description": "操作类型分布",
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/controllers.OperationTypeCount"
-                    }
-                },
-                "peak_usage_time": {
-                    "description": "高峰使用时段",
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/controllers.PeakUsageTimeInfo"
-                        }
-                    ]
-                },
-                "recent_system_logs": {
-                    "description": "最近系统日志",
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/controllers.RecentSystemLog"
-                    }
-                },
-                "today_active_users": {
-                    "description": "今日活跃",
-                    "type": "integer"
-                },
-                "today_operations": {
-                    "description": "今日操作",
-                    "type": "integer"
-                },
-                "total_operations": {
-                    "description": "总操作数",
-                    "type": "integer"
-                },
-                "total_users": {
-                    "description": "用户总数",
-                    "type": "integer"
-                },
-                "user_activity_trend_data": {
-                    "description": "用户活动趋势",
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/controllers.UserActivityTrendData"
-                    }
-                }
-            }
-        },
-        "controllers.TableInfo": {
-            "type": "object",
-            "properties": {
-                "columns": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/controllers.ColumnInfo"
-                    }
-                },
-                "comment": {
-                    "type": "string"
-                },
-                "id": {
-                    "type": "integer"
-                },
-                "live_rows_estimate": {
-                    "type": "integer"
-                },
-                "name": {
-                    "type": "string"
-                },
-                "primary_keys": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/controllers.PrimaryKey"
-                    }
-                },
-                "relationships": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/controllers.Relationship"
-                    }
-                },
-                "schema": {
-                    "type": "string"
-                },
-                "size": {
-                    "type": "string"
-                }
-            }
-        },
-        "controllers.TaskTypeCount": {
-            "type": "object",
-            "properties": {
-                "count": {
-                    "type": "integer"
-                },
-                "task_type": {
-                    "type": "string"
-                }
-            }
-        },
-        "controllers.ThematicInterfaceListResponse": {
-            "type": "object",
-            "properties": {
-                "list": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/models.ThematicInterface"
-                    }
-                },
-                "page": {
-                    "type": "integer"
-                },
-                "size": {
-                    "type": "integer"
-                },
-                "total": {
-                    "type": "integer"
-                }
-            }
-        },
-        "controllers.ThematicLibraryListResponse": {
-            "type": "object",
-            "properties": {
-                "list": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/models.ThematicLibrary"
-                    }
-                },
-                "page": {
-                    "type": "integer"
-                },
-                "size": {
-                    "type": "integer"
-                },
-                "total": {
-                    "type": "integer"
-                }
-            }
-        },
-        "controllers.ThematicLibraryMetadataResponse": {
-            "type": "object",
-            "properties": {
-                "access_levels": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/meta.ThematicLibraryAccessLevel"
-                    }
-                },
-                "categories": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/meta.ThematicLibraryCategory"
-                    }
-                },
-                "domains": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/meta.ThematicLibraryDomain"
-                    }
-                },
-                "interface_statuses": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/meta.ThematicInterfaceStatus"
-                    }
-                },
-                "interface_types": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/meta.ThematicInterfaceType"
-                    }
-                },
-                "library_statuses": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/meta.ThematicLibraryStatus"
-                    }
-                }
-            }
-        },
-        "controllers.ThematicLibraryStats": {
-            "type": "object",
-            "properties": {
-                "access_level_stats": {
-                    "description": "访问级别统计",
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/controllers.AccessLevelCount"
-                    }
-                },
-                "archived_libraries": {
-                    "description": "已归档",
-                    "type": "integer"
-                },
-                "category_breakdown": {
-                    "description": "分类统计",
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/controllers.CategoryCount"
-                    }
-                },
-                "domain_breakdown": {
-                    "description": "领域统计",
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/controllers.DomainCount"
-                    }
-                },
-                "draft_libraries": {
-                    "description": "草稿",
-                    "type": "integer"
-                },
-                "interface_type_stats": {
-                    "description": "接口类型统计",
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/controllers.InterfaceTypeCount"
-                    }
-                },
-                "published_libraries": {
-                    "description": "已发布",
-                    "type": "integer"
-                },
-                "recent_libraries": {
-                    "description": "最近创建的主题库",
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/controllers.RecentThematicLibraryInfo"
-                    }
-                },
-                "table_interfaces": {
-                    "description": "表类型接口",
-                    "type": "integer"
-                },
-                "top_access_libraries": {
-                    "description": "访问量排行",
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/controllers.LibraryAccessStats"
-                    }
-                },
-                "total_interfaces": {
-                    "description": "接口总数",
-                    "type": "integer"
-                },
-                "total_libraries": {
-                    "description": "总数",
-                    "type": "integer"
-                },
-                "trend_data": {
-                    "description": "趋势数据",
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/controllers.LibraryTrendData"
-                    }
-                },
-                "view_interfaces": {
-                    "description": "视图类型接口",
-                    "type": "integer"
-                }
-            }
-        },
-        "controllers.TriggerTypeCount": {
-            "type": "object",
-            "properties": {
-                "count": {
-                    "type": "integer"
-                },
-                "trigger_type": {
-                    "type": "string"
-                }
-            }
-        },
-        "controllers.UpdateApiInterfaceMaskingRulesRequest": {
-            "type": "object",
-            "required": [
-                "masking_rules"
-            ],
-            "properties": {
-                "masking_rules": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/models.DataMaskingConfig"
-                    }
-                }
-            }
-        },
-        "controllers.UpdateApiInterfaceRequest": {
-            "type": "object",
-            "properties": {
-                "description": {
-                    "type": "string"
-                },
-                "path": {
-                    "type": "string"
-                },
-                "status": {
-                    "type": "string"
-                }
-            }
-        },
-        "controllers.UpdateApiKeyApplicationsRequest": {
-            "type": "object",
-            "required": [
-                "application_ids"
-            ],
-            "properties": {
-                "application_ids": {
-                    "description": "关联的应用ID列表",
-                    "type": "array",
-                    "minItems": 1,
-                    "items": {
-                        "type": "string"
-                    }
-                }
-            }
-        },
-        "controllers.UpdateBasicLibraryRequest": {
-            "type": "object",
-            "required": [
-                "id"
-            ],
-            "properties": {
-                "description": {
-                    "type": "string",
-                    "example": "存储用户相关数据的基础库"
-                },
-                "id": {
-                    "type": "string",
-                    "example": "550e8400-e29b-41d4-a716-446655440000"
-                },
-                "name_en": {
-                    "type": "string",
-                    "example": "user_database"
-                },
-                "name_zh": {
-                    "type": "string",
-                    "example": "用户数据库"
-                },
-                "status": {
-                    "type": "string",
-                    "example": "active"
-                }
-            }
-        },
-        "controllers.UpdateConfigRequest": {
-            "type": "object",
-            "required": [
-                "value"
-            ],
-            "properties": {
-                "description": {
-                    "type": "string"
-                },
-                "value": {
-                    "type": "string"
-                }
-            }
-        },
-        "controllers.UpdateDataInterfaceRequest": {
-            "type": "object",
-            "required": [
-                "id"
-            ],
-            "properties": {
-                "data_source_id": {
-                    "type": "string",
-                    "example": "550e8400-e29b-41d4-a716-446655440001"
-                },
-                "description": {
-                    "type": "string",
-                    "example": "用户数据查询接口"
-                },
-                "id": {
-                    "type": "string",
-                    "example": "550e8400-e29b-41d4-a716-446655440000"
-                },
-                "interface_config": {
-                    "type": "object",
-                    "additionalProperties": true
-                },
-                "name_en": {
-                    "type": "string",
-                    "example": "user_interface"
-                },
-                "name_zh": {
-                    "type": "string",
-                    "example": "用户接口"
-                },
-                "parse_config": {
-                    "type": "object",
-                    "additionalProperties": true
-                },
-                "status": {
-                    "type": "string",
-                    "example": "active"
-                },
-                "table_fields_config": {
-                    "type": "object",
-                    "additionalProperties": true
-                },
-                "type": {
-                    "description": "realtime, batch",
-                    "type": "string",
-                    "example": "realtime"
-                }
-            }
-        },
-        "controllers.UpdateDataSourceRequest": {
-            "type": "object",
-            "required": [
-                "id"
-            ],
-            "properties": {
-                "category": {
-                    "type": "string",
-                    "example": "db"
-                },
-                "connection_config": {
-                    "type": "object",
-                    "additionalProperties": true
-                },
-                "id": {
-                    "type": "string",
-                    "example": "550e8400-e29b-41d4-a716-446655440000"
-                },
-                "name": {
-                    "type": "string",
-                    "example": "用户数据源"
-                },
-                "params_config": {
-                    "type": "object",
-                    "additionalProperties": true
-                },
-                "script": {
-                    "type": "string"
-                },
-                "script_enabled": {
-                    "type": "boolean"
-                },
-                "type": {
-                    "type": "string",
-                    "example": "mysql"
-                }
-            }
-        },
-        "controllers.UpdateInterfaceFieldsRequest": {
-            "type": "object",
-            "required": [
-                "fields",
-                "interface_id"
-            ],
-            "properties": {
-                "backup_table": {
-                    "description": "是否备份原表",
-                    "type": "boolean",
-                    "example": false
-                },
-                "fields": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/models.TableField"
-                    }
-                },
-                "interface_id": {
-                    "type": "string",
-                    "example": "550e8400-e29b-41d4-a716-446655440000"
-                },
-                "update_table": {
-                    "description": "是否同时更新数据库表结构",
-                    "type": "boolean",
-                    "example": true
-                }
-            }
-        },
-        "controllers.UpdateThematicInterfaceFieldsRequest": {
-            "type": "object",
-            "required": [
-                "fields",
-                "interface_id"
-            ],
-            "properties": {
-                "fields": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/models.TableField"
-                    }
-                },
-                "interface_id": {
-                    "type": "string",
-                    "example": "550e8400-e29b-41d4-a716-446655440000"
-                }
-            }
-        },
-        "controllers.UpdateThematicInterfaceViewRequest": {
-            "type": "object",
-            "required": [
-                "interface_id",
-                "view_sql"
-            ],
-            "properties": {
-                "interface_id": {
-                    "type": "string",
-                    "example": "550e8400-e29b-41d4-a716-446655440000"
-                },
-                "view_sql": {
-                    "type": "string",
-                    "example": "SELECT * FROM users WHERE status = 'active'"
-                }
-            }
-        },
-        "controllers.UserActivityTrendData": {
-            "type": "object",
-            "properties": {
-                "active_users": {
-                    "type": "integer"
-                },
-                "date": {
-                    "type": "string"
-                },
-                "operations": {
-                    "type": "integer"
-                }
-            }
-        },
-        "database.ColumnDefinition": {
-            "type": "object",
-            "properties": {
-                "comment": {
-                    "type": "string"
-                },
-                "data_type": {
-                    "type": "string"
-                },
-                "default_value": {},
-                "is_nullable": {
-                    "type": "boolean"
-                },
-                "is_primary_key": {
-                    "type": "boolean"
-                },
-                "is_unique": {
-                    "type": "boolean"
-                },
-                "max_length": {
-                    "type": "integer"
-                },
-                "name": {
-                    "type": "string"
-                },
-                "numeric_precision": {
-                    "type": "integer"
-                },
-                "numeric_scale": {
-                    "type": "integer"
-                },
-                "ordinal_position": {
-                    "type": "integer"
-                }
-            }
-        },
-        "database.IndexDefinition": {
-            "type": "object",
-            "properties": {
-                "columns": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    }
-                },
-                "definition": {
-                    "description": "索引定义SQL",
-                    "type": "string"
-                },
-                "index_type": {
-                    "description": "btree, hash, gist, gin, etc.",
-                    "type": "string"
-                },
-                "is_primary": {
-                    "type": "boolean"
-                },
-                "is_unique": {
-                    "type": "boolean"
-                },
-                "name": {
-                    "type": "string"
-                }
-            }
-        },
-        "governance.CleansingRuleListResponse": {
-            "type": "object",
-            "properties": {
-                "list": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/governance.CleansingRuleResponse"
-                    }
-                },
-                "page": {
-                    "type": "integer",
-                    "example": 1
-                },
-                "size": {
-                    "type": "integer",
-                    "example": 10
-                },
-                "total": {
-                    "type": "integer",
-                    "example": 18
-                }
-            }
-        },
-        "governance.CleansingRuleResponse": {
-            "type": "object",
-            "properties": {
-                "category": {
-                    "type": "string",
-                    "example": "data_format"
-                },
-                "cleansing_logic": {
-                    "type": "object"
-                },
-                "complexity_level": {
-                    "type": "string",
-                    "example": "medium"
-                },
-                "created_at": {
-                    "type": "string",
-                    "example": "2024-01-01T00:00:00Z"
-                },
-                "created_by": {
-                    "type": "string",
-                    "example": "admin"
-                },
-                "default_config": {
-                    "type": "object"
-                },
-                "description": {
-                    "type": "string",
-                    "example": "统一邮箱格式为小写的通用模板"
-                },
-                "id": {
-                    "type": "string",
-                    "example": "uuid-123"
-                },
-                "is_built_in": {
-                    "type": "boolean",
-                    "example": false
-                },
-                "is_enabled": {
-                    "type": "boolean",
-                    "example": true
-                },
-                "name": {
-                    "type": "string",
-                    "example": "邮箱格式标准化模板"
-                },
-                "parameters": {
-                    "type": "object"
-                },
-                "rule_type": {
-                    "type": "string",
-                    "example": "standardization"
-                },
-                "tags": {
-                    "type": "object"
-                },
-                "updated_at": {
-                    "type": "string",
-                    "example": "2024-01-01T00:00:00Z"
-                },
-                "updated_by": {
-                    "type": "string",
-                    "example": "admin"
-                },
-                "version": {
-                    "type": "string",
-                    "example": "1.0"
-                }
-            }
-        },
-        "governance.CreateCleansingRuleRequest": {
-            "type": "object",
-            "required": [
-                "category",
-                "cleansing_logic",
-                "name",
-                "rule_type"
-            ],
-            "properties": {
-                "category": {
-                    "type": "string",
-                    "enum": [
-                        "data_format",
-                        "data_quality",
-                        "data_integrity"
-                    ],
-                    "example": "data_format"
-                },
-                "cleansing_logic": {
-                    "type": "object"
-                },
-                "complexity_level": {
-                    "type": "string",
-                    "enum": [
-                        "low",
-                        "medium",
-                        "high"
-                    ],
-                    "example": "medium"
-                },
-                "default_config": {
-                    "type": "object"
-                },
-                "description": {
-                    "type": "string",
-                    "example": "统一邮箱格式为小写的通用模板"
-                },
-                "is_enabled": {
-                    "type": "boolean",
-                    "example": true
-                },
-                "name": {
-                    "type": "string",
-                    "example": "邮箱格式标准化模板"
-                },
-                "parameters": {
-                    "type": "object"
-                },
-                "rule_type": {
-                    "type": "string",
-                    "enum": [
-                        "standardization",
-                        "deduplication",
-                        "validation",
-                        "transformation",
-                        "enrichment"
-                    ],
-                    "example": "standardization"
-                },
-                "tags": {
-                    "type": "object"
-                }
-            }
-        },
-        "governance.CreateDataLineageRequest": {
-            "type": "object",
-            "required": [
-                "relation_type",
-                "source_object_id",
-                "source_object_type",
-                "target_object_id",
-                "target_object_type"
-            ],
-            "properties": {
-                "column_mapping": {
-                    "type": "object"
-                },
-                "confidence": {
-                    "type": "number",
-                    "example": 1
-                },
-                "description": {
-                    "type": "string",
-                    "example": "用户表到用户接口的直接映射"
-                },
-                "is_active": {
-                    "type": "boolean",
-                    "example": true
-                },
-                "relation_type": {
-                    "type": "string",
-                    "enum": [
-                        "direct",
-                        "derived",
-                        "aggregated",
-                        "transformed"
-                    ],
-                    "example": "direct"
-                },
-                "source_object_id": {
-                    "type": "string",
-                    "example": "uuid-123"
-                },
-                "source_object_type": {
-                    "type": "string",
-                    "example": "table"
-                },
-                "target_object_id": {
-                    "type": "string",
-                    "example": "uuid-456"
-                },
-                "target_object_type": {
-                    "type": "string",
-                    "example": "interface"
-                },
-                "transform_rule": {
-                    "type": "object"
-                }
-            }
-        },
-        "governance.CreateMaskingRuleRequest": {
-            "type": "object",
-            "required": [
-                "category",
-                "masking_logic",
-                "masking_type",
-                "name"
-            ],
-            "properties": {
-                "category": {
-                    "type": "string",
-                    "enum": [
-                        "personal_info",
-                        "financial",
-                        "medical",
-                        "business",
-                        "custom"
-                    ],
-                    "example": "personal_info"
-                },
-                "description": {
-                    "type": "string",
-                    "example": "对手机号进行脱敏处理的通用模板"
-                },
-                "is_enabled": {
-                    "type": "boolean",
-                    "example": true
-                },
-                "masking_logic": {
-                    "type": "object"
-                },
-                "masking_type": {
-                    "type": "string",
-                    "enum": [
-                        "mask",
-                        "replace",
-                        "encrypt",
-                        "pseudonymize"
-                    ],
-                    "example": "mask"
-                },
-                "name": {
-                    "type": "string",
-                    "example": "手机号脱敏模板"
-                },
-                "parameters": {
-                    "type": "object"
-                },
-                "security_level": {
-                    "type": "string",
-                    "enum": [
-                        "low",
-                        "medium",
-                        "high",
-                        "critical"
-                    ],
-                    "example": "high"
-                },
-                "tags": {
-                    "type": "object"
-                }
-            }
-        },
-        "governance.CreateMetadataRequest": {
-            "type": "object",
-            "required": [
-                "content",
-                "name",
-                "type"
-            ],
-            "properties": {
-                "content": {
-                    "type": "object"
-                },
-                "name": {
-                    "type": "string",
-                    "example": "用户表元数据"
-                },
-                "related_object_id": {
-                    "type": "string",
-                    "example": "uuid-123"
-                },
-                "related_object_type": {
-                    "type": "string",
-                    "example": "interface"
-                },
-                "type": {
-                    "type": "string",
-                    "enum": [
-                        "technical",
-                        "business",
-                        "management"
-                    ],
-                    "example": "technical"
-                }
-            }
-        },
-        "governance.CreateQualityRuleRequest": {
-            "type": "object",
-            "required": [
-                "category",
-                "name",
-                "rule_logic",
-                "type"
-            ],
-            "properties": {
-                "category": {
-                    "type": "string",
-                    "enum": [
-                        "basic_quality",
-                        "data_cleansing",
-                        "data_validation"
-                    ],
-                    "example": "basic_quality"
-                },
-                "default_config": {
-                    "type": "object"
-                },
-                "description": {
-                    "type": "string",
-                    "example": "检查数据完整性的通用模板"
-                },
-                "is_enabled": {
-                    "type": "boolean",
-                    "example": true
-                },
-                "name": {
-                    "type": "string",
-                    "example": "完整性检查模板"
-                },
-                "parameters": {
-                    "type": "object"
-                },
-                "rule_logic": {
-                    "type": "object"
-                },
-                "tags": {
-                    "type": "object"
-                },
-                "type": {
-                    "type": "string",
-                    "enum": [
-                        "completeness",
-                        "accuracy",
-                        "consistency",
-                        "validity",
-                        "uniqueness",
-                        "timeliness",
-                        "standardization"
-                    ],
-                    "example": "completeness"
-                }
-            }
-        },
-        "governance.CreateQualityTaskRequest": {
-            "type": "object",
-            "required": [
-                "field_rules",
-                "interface_id",
-                "library_id",
-                "library_type",
-                "name",
-                "schedule_config",
-                "target_schema",
-                "target_table"
-            ],
-            "properties": {
-                "description": {
-                    "type": "string",
-                    "example": "定期检测用户表数据质量"
-                },
-                "field_rules": {
-                    "type": "array",
-                    "minItems": 1,
-                    "items": {
-                        "$ref": "#/definitions/governance.FieldRuleConfig"
-                    }
-                },
-                "interface_id": {
-                    "type": "string",
-                    "example": "uuid-interface-123"
-                },
-                "is_enabled": {
-                    "type": "boolean",
-                    "example": true
-                },
-                "library_id": {
-                    "type": "string",
-                    "example": "uuid-lib-123"
-                },
-                "library_type": {
-                    "type": "string",
-                    "enum": [
-                        "thematic",
-                        "basic"
-                    ],
-                    "example": "thematic"
-                },
-                "name": {
-                    "type": "string",
-                    "example": "用户表质量检测任务"
-                },
-                "notification_config": {
-                    "$ref": "#/definitions/governance.NotificationConfigRequest"
-                },
-                "priority": {
-                    "type": "integer",
-                    "example": 50
-                },
-                "schedule_config": {
-                    "$ref": "#/definitions/governance.ScheduleConfigRequest"
-                },
-                "target_schema": {
-                    "type": "string",
-                    "example": "public"
-                },
-                "target_table": {
-                    "type": "string",
-                    "example": "users"
-                }
-            }
-        },
-        "governance.DataCleansingTemplateListResponse": {
-            "type": "object",
-            "properties": {
-                "list": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/governance.DataCleansingTemplateResponse"
-                    }
-                },
-                "page": {
-                    "type": "integer",
-                    "example": 1
-                },
-                "size": {
-                    "type": "integer",
-                    "example": 10
-                },
-                "total": {
-                    "type": "integer",
-                    "example": 25
-                }
-            }
-        },
-        "governance.DataCleansingTemplateResponse": {
-            "type": "object",
-            "properties": {
-                "applicable_types": {
-                    "type": "object"
-                },
-                "category": {
-                    "type": "string",
-                    "example": "data_format"
-                },
-                "cleansing_logic": {
-                    "type": "object"
-                },
-                "complexity_level": {
-                    "type": "string",
-                    "example": "medium"
-                },
-                "created_at": {
-                    "type": "string",
-                    "example": "2024-01-01T00:00:00Z"
-                },
-                "created_by": {
-                    "type": "string",
-                    "example": "system"
-                },
-                "default_config": {
-                    "type": "object"
-                },
-                "description": {
-                    "type": "string",
-                    "example": "统一邮箱格式为小写"
-                },
-                "id": {
-                    "type": "string",
-                    "example": "uuid-123"
-                },
-                "is_built_in": {
-                    "type": "boolean",
-                    "example": true
-                },
-                "is_enabled": {
-                    "type": "boolean",
-                    "example": true
-                },
-                "name": {
-                    "type": "string",
-                    "example": "邮箱格式标准化模板"
-                },
-                "parameters": {
-                    "type": "object"
-                },
-                "rule_type": {
-                    "type": "string",
-                    "example": "standardization"
-                },
-                "tags": {
-                    "type": "object"
-                },
-                "updated_at": {
-                    "type": "string",
-                    "example": "2024-01-01T00:00:00Z"
-                },
-                "updated_by": {
-                    "type": "string",
-                    "example": "system"
-                },
-                "version": {
-                    "type": "string",
-                    "example": "1.0"
-                }
-            }
-        },
-        "governance.DataLineageEdge": {
-            "type": "object",
-            "properties": {
-                "confidence": {
-                    "type": "number",
-                    "example": 1
-                },
-                "id": {
-                    "type": "string",
-                    "example": "uuid-123"
-                },
-                "relation_type": {
-                    "type": "string",
-                    "example": "direct"
-                },
-                "source_id": {
-                    "type": "string",
-                    "example": "uuid-456"
-                },
-                "target_id": {
-                    "type": "string",
-                    "example": "uuid-789"
-                }
-            }
-        },
-        "governance.DataLineageGraphResponse": {
-            "type": "object",
-            "properties": {
-                "edges": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/governance.DataLineageEdge"
-                    }
-                },
-                "nodes": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/governance.DataLineageNode"
-                    }
-                },
-                "stats": {
-                    "type": "object",
-                    "properties": {
-                        "max_depth": {
-                            "type": "integer",
-                            "example": 3
-                        },
-                        "total_edges": {
-                            "type": "integer",
-                            "example": 8
-                        },
-                        "total_nodes": {
-                            "type": "integer",
-                            "example": 10
-                        }
-                    }
-                }
-            }
-        },
-        "governance.DataLineageNode": {
-            "type": "object",
-            "properties": {
-                "id": {
-                    "type": "string",
-                    "example": "uuid-123"
-                },
-                "level": {
-                    "type": "integer",
-                    "example": 0
-                },
-                "name": {
-                    "type": "string",
-                    "example": "users_table"
-                },
-                "object_type": {
-                    "type": "string",
-                    "example": "table"
-                }
-            }
-        },
-        "governance.DataLineageResponse": {
-            "type": "object",
-            "properties": {
-                "column_mapping": {
-                    "type": "object"
-                },
-                "confidence": {
-                    "type": "number",
-                    "example": 1
-                },
-                "created_at": {
-                    "type": "string",
-                    "example": "2024-01-01T00:00:00Z"
-                },
-                "created_by": {
-                    "type": "string",
-                    "example": "admin"
-                },
-                "description": {
-                    "type": "string",
-                    "example": "用户表到用户接口的直接映射"
-                },
-                "id": {
-                    "type": "string",
-                    "example": "uuid-123"
-                },
-                "is_active": {
-                    "type": "boolean",
-                    "example": true
-                },
-                "relation_type": {
-                    "type": "string",
-                    "example": "direct"
-                },
-                "source_object_id": {
-                    "type": "string",
-                    "example": "uuid-456"
-                },
-                "source_object_type": {
-                    "type": "string",
-                    "example": "table"
-                },
-                "target_object_id": {
-                    "type": "string",
-                    "example": "uuid-789"
-                },
-                "target_object_type": {
-                    "type": "string",
-                    "example": "interface"
-                },
-                "transform_rule": {
-                    "type": "object"
-                },
-                "updated_at": {
-                    "type": "string",
-                    "example": "2024-01-01T00:00:00Z"
-                },
-                "updated_by": {
-                    "type": "string",
-                    "example": "admin"
-                }
-            }
-        },
-        "governance.DataMaskingTemplateListResponse": {
-            "type": "object",
-            "properties": {
-                "list": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/governance.DataMaskingTemplateResponse"
-                    }
-                },
-                "page": {
-                    "type": "integer",
-                    "example": 1
-                },
-                "size": {
-                    "type": "integer",
-                    "example": 10
-                },
-                "total": {
-                    "type": "integer",
-                    "example": 30
-                }
-            }
-        },
-        "governance.DataMaskingTemplateResponse": {
-            "type": "object",
-            "properties": {
-                "applicable_types": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    },
-                    "example": [
-                        "[\"string\"]"
-                    ]
-                },
-                "category": {
-                    "type": "string",
-                    "example": "personal_info"
-                },
-                "created_at": {
-                    "type": "string",
-                    "example": "2024-01-01T00:00:00Z"
-                },
-                "created_by": {
-                    "type": "string",
-                    "example": "system"
-                },
-                "default_config": {
-                    "type": "object"
-                },
-                "description": {
-                    "type": "string",
-                    "example": "对手机号进行脱敏处理"
-                },
-                "id": {
-                    "type": "string",
-                    "example": "uuid-123"
-                },
-                "is_built_in": {
-                    "type": "boolean",
-                    "example": true
-                },
-                "is_enabled": {
-                    "type": "boolean",
-                    "example": true
-                },
-                "masking_logic": {
-                    "type": "object"
-                },
-                "masking_type": {
-                    "type": "string",
-                    "example": "mask"
-                },
-                "name": {
-                    "type": "string",
-                    "example": "手机号脱敏模板"
-                },
-                "parameters": {
-                    "type": "object"
-                },
-                "security_level": {
-                    "type": "string",
-                    "example": "high"
-                },
-                "tags": {
-                    "type": "object"
-                },
-                "updated_at": {
-                    "type": "string",
-                    "example": "2024-01-01T00:00:00Z"
-                },
-                "updated_by": {
-                    "type": "string",
-                    "example": "system"
-                },
-                "version": {
-                    "type": "string",
-                    "example": "1.0"
-                }
-            }
-        },
-        "governance.FieldRuleConfig": {
-            "type": "object",
-            "required": [
-                "field_name",
-                "rule_template_id"
-            ],
-            "properties": {
-                "field_name": {
-                    "type": "string",
-                    "example": "email"
-                },
-                "is_enabled": {
-                    "type": "boolean",
-                    "example": true
-                },
-                "priority": {
-                    "type": "integer",
-                    "example": 50
-                },
-                "rule_template_id": {
-                    "type": "string",
-                    "example": "uuid-rule-123"
-                },
-                "runtime_config": {
-                    "$ref": "#/definitions/governance.RuntimeConfig"
-                },
-                "threshold": {
-                    "$ref": "#/definitions/governance.ThresholdConfig"
-                }
-            }
-        },
-        "governance.FieldRuleResponse": {
-            "type": "object",
-            "properties": {
-                "field_name": {
-                    "type": "string",
-                    "example": "email"
-                },
-                "id": {
-                    "type": "string",
-                    "example": "uuid-123"
-                },
-                "is_enabled": {
-                    "type": "boolean",
-                    "example": true
-                },
-                "priority": {
-                    "type": "integer",
-                    "example": 50
-                },
-                "rule_name": {
-                    "type": "string",
-                    "example": "邮箱格式检查"
-                },
-                "rule_template_id": {
-                    "type": "string",
-                    "example": "uuid-rule-123"
-                },
-                "runtime_config": {
-                    "$ref": "#/definitions/governance.RuntimeConfig"
-                },
-                "threshold": {
-                    "$ref": "#/definitions/governance.ThresholdConfig"
-                }
-            }
-        },
-        "governance.MaskingRuleListResponse": {
-            "type": "object",
-            "properties": {
-                "list": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/governance.MaskingRuleResponse"
-                    }
-                },
-                "page": {
-                    "type": "integer",
-                    "example": 1
-                },
-                "size": {
-                    "type": "integer",
-                    "example": 10
-                },
-                "total": {
-                    "type": "integer",
-                    "example": 12
-                }
-            }
-        },
-        "governance.MaskingRuleResponse": {
-            "type": "object",
-            "properties": {
-                "category": {
-                    "type": "string",
-                    "example": "personal_info"
-                },
-                "created_at": {
-                    "type": "string",
-                    "example": "2024-01-01T00:00:00Z"
-                },
-                "created_by": {
-                    "type": "string",
-                    "example": "admin"
-                },
-                "description": {
-                    "type": "string",
-                    "example": "对手机号进行脱敏处理的通用模板"
-                },
-                "id": {
-                    "type": "string",
-                    "example": "uuid-123"
-                },
-                "is_built_in": {
-                    "type": "boolean",
-                    "example": false
-                },
-                "is_enabled": {
-                    "type": "boolean",
-                    "example": true
-                },
-                "masking_logic": {
-                    "type": "object"
-                },
-                "masking_type": {
-                    "type": "string",
-                    "example": "mask"
-                },
-                "name": {
-                    "type": "string",
-                    "example": "手机号脱敏模板"
-                },
-                "parameters": {
-                    "type": "object"
-                },
-                "security_level": {
-                    "type": "string",
-                    "example": "high"
-                },
-                "tags": {
-                    "type": "object"
-                },
-                "updated_at": {
-                    "type": "string",
-                    "example": "2024-01-01T00:00:00Z"
-                },
-                "updated_by": {
-                    "type": "string",
-                    "example": "admin"
-                },
-                "version": {
-                    "type": "string",
-                    "example": "1.0"
-                }
-            }
-        },
-        "governance.MetadataListResponse": {
-            "type": "object",
-            "properties": {
-                "list": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/governance.MetadataResponse"
-                    }
-                },
-                "page": {
-                    "type": "integer",
-                    "example": 1
-                },
-                "size": {
-                    "type": "integer",
-                    "example": 10
-                },
-                "total": {
-                    "type": "integer",
-                    "example": 30
-                }
-            }
-        },
-        "governance.MetadataResponse": {
-            "type": "object",
-            "properties": {
-                "content": {
-                    "type": "object"
-                },
-                "created_at": {
-                    "type": "string",
-                    "example": "2024-01-01T00:00:00Z"
-                },
-                "created_by": {
-                    "type": "string",
-                    "example": "admin"
-                },
-                "id": {
-                    "type": "string",
-                    "example": "uuid-123"
-                },
-                "name": {
-                    "type": "string",
-                    "example": "用户表元数据"
-                },
-                "related_object_id": {
-                    "type": "string",
-                    "example": "uuid-456"
-                },
-                "related_object_type": {
-                    "type": "string",
-                    "example": "interface"
-                },
-                "type": {
-                    "type": "string",
-                    "example": "technical"
-                },
-                "updated_at": {
-                    "type": "string",
-                    "example": "2024-01-01T00:00:00Z"
-                },
-                "updated_by": {
-                    "type": "string",
-                    "example": "admin"
-                }
-            }
-        },
-        "governance.NotificationConfigRequest": {
-            "type": "object",
-            "properties": {
-                "channels": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    },
-                    "example": [
-                        "[\"email\"",
-                        "\"webhook\"]"
-                    ]
-                },
-                "enabled": {
-                    "type": "boolean",
-                    "example": true
-                },
-                "notify_on_failure": {
-                    "type": "boolean",
-                    "example": true
-                },
-                "notify_on_success": {
-                    "type": "boolean",
-                    "example": false
-                },
-                "recipients": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    },
-                    "example": [
-                        "[\"admin@example.com\"]"
-                    ]
-                }
-            }
-        },
-        "governance.NotificationConfigResponse": {
-            "type": "object",
-            "properties": {
-                "channels": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    },
-                    "example": [
-                        "[\"email\"]"
-                    ]
-                },
-                "enabled": {
-                    "type": "boolean",
-                    "example": true
-                },
-                "notify_on_failure": {
-                    "type": "boolean",
-                    "example": true
-                },
-                "notify_on_success": {
-                    "type": "boolean",
-                    "example": false
-                },
-                "recipients": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    },
-                    "example": [
-                        "[\"admin@example.com\"]"
-                    ]
-                }
-            }
-        },
-        "governance.QualityIssueRecordListResponse": {
-            "type": "object",
-            "properties": {
-                "list": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/governance.QualityIssueRecordResponse"
-                    }
-                },
-                "page": {
-                    "type": "integer",
-                    "example": 1
-                },
-                "size": {
-                    "type": "integer",
-                    "example": 10
-                },
-                "total": {
-                    "type": "integer",
-                    "example": 100
-                }
-            }
-        },
-        "governance.QualityIssueRecordResponse": {
-            "type": "object",
-            "properties": {
-                "created_at": {
-                    "type": "string",
-                    "example": "2024-01-01T00:00:00Z"
-                },
-                "execution_id": {
-                    "type": "string",
-                    "example": "uuid-456"
-                },
-                "expected_value": {
-                    "type": "string",
-                    "example": "valid email format"
-                },
-                "field_name": {
-                    "type": "string",
-                    "example": "email"
-                },
-                "field_value": {
-                    "type": "string",
-                    "example": "invalid-email"
-                },
-                "id": {
-                    "type": "string",
-                    "example": "uuid-123"
-                },
-                "issue_description": {
-                    "type": "string",
-                    "example": "邮箱格式不正确"
-                },
-                "issue_type": {
-                    "type": "string",
-                    "example": "format_invalid"
-                },
-                "record_identifier": {
-                    "type": "string",
-                    "example": "id=123"
-                },
-                "rule_template_id": {
-                    "type": "string",
-                    "example": "uuid-rule-123"
-                },
-                "rule_template_name": {
-                    "type": "string",
-                    "example": "邮箱格式检查"
-                },
-                "severity": {
-                    "type": "string",
-                    "example": "high"
-                },
-                "task_id": {
-                    "type": "string",
-                    "example": "uuid-789"
-                }
-            }
-        },
-        "governance.QualityReportListResponse": {
-            "type": "object",
-            "properties": {
-                "list": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/governance.QualityReportResponse"
-                    }
-                },
-                "page": {
-                    "type": "integer",
-                    "example": 1
-                },
-                "size": {
-                    "type": "integer",
-                    "example": 10
-                },
-                "total": {
-                    "type": "integer",
-                    "example": 8
-                }
-            }
-        },
-        "governance.QualityReportResponse": {
-            "type": "object",
-            "properties": {
-                "generated_at": {
-                    "type": "string",
-                    "example": "2024-01-01T00:00:00Z"
-                },
-                "generated_by": {
-                    "type": "string",
-                    "example": "system"
-                },
-                "id": {
-                    "type": "string",
-                    "example": "uuid-123"
-                },
-                "issues": {
-                    "type": "object"
-                },
-                "quality_metrics": {
-                    "type": "object"
-                },
-                "quality_score": {
-                    "type": "number",
-                    "example": 85.5
-                },
-                "recommendations": {
-                    "type": "object"
-                },
-                "related_object_id": {
-                    "type": "string",
-                    "example": "uuid-456"
-                },
-                "related_object_type": {
-                    "type": "string",
-                    "example": "interface"
-                },
-                "report_name": {
-                    "type": "string",
-                    "example": "接口质量检查报告"
-                }
-            }
-        },
-        "governance.QualityRuleListResponse": {
-            "type": "object",
-            "properties": {
-                "list": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/governance.QualityRuleResponse"
-                    }
-                },
-                "page": {
-                    "type": "integer",
-                    "example": 1
-                },
-                "size": {
-                    "type": "integer",
-                    "example": 10
-                },
-                "total": {
-                    "type": "integer",
-                    "example": 25
-                }
-            }
-        },
-        "governance.QualityRuleResponse": {
-            "type": "object",
-            "properties": {
-                "category": {
-                    "type": "string",
-                    "example": "basic_quality"
-                },
-                "created_at": {
-                    "type": "string",
-                    "example": "2024-01-01T00:00:00Z"
-                },
-                "created_by": {
-                    "type": "string",
-                    "example": "admin"
-                },
-                "default_config": {
-                    "type": "object"
-                },
-                "description": {
-                    "type": "string",
-                    "example": "检查数据完整性的通用模板"
-                },
-                "id": {
-                    "type": "string",
-                    "example": "uuid-123"
-                },
-                "is_built_in": {
-                    "type": "boolean",
-                    "example": false
-                },
-                "is_enabled": {
-                    "type": "boolean",
-                    "example": true
-                },
-                "name": {
-                    "type": "string",
-                    "example": "完整性检查模板"
-                },
-                "parameters": {
-                    "type": "object"
-                },
-                "rule_logic": {
-                    "type": "object"
-                },
-                "tags": {
-                    "type": "object"
-                },
-                "type": {
-                    "type": "string",
-                    "example": "completeness"
-                },
-                "updated_at": {
-                    "type": "string",
-                    "example": "2024-01-01T00:00:00Z"
-                },
-                "updated_by": {
-                    "type": "string",
-                    "example": "admin"
-                },
-                "version": {
-                    "type": "string",
-                    "example": "1.0"
-                }
-            }
-        },
-        "governance.QualityRuleTemplateListResponse": {
-            "type": "object",
-            "properties": {
-                "list": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/governance.QualityRuleTemplateResponse"
-                    }
-                },
-                "page": {
-                    "type": "integer",
-                    "example": 1
-                },
-                "size": {
-                    "type": "integer",
-                    "example": 10
-                },
-                "total": {
-                    "type": "integer",
-                    "example": 50
-                }
-            }
-        },
-        "governance.QualityRuleTemplateResponse": {
-            "type": "object",
-            "properties": {
-                "category": {
-                    "type": "string",
-                    "example": "basic_quality"
-                },
-                "created_at": {
-                    "type": "string",
-                    "example": "2024-01-01T00:00:00Z"
-                },
-                "created_by": {
-                    "type": "string",
-                    "example": "system"
-                },
-                "default_config": {
-                    "type": "object"
-                },
-                "description": {
-                    "type": "string",
-                    "example": "检查数据完整性的通用模板"
-                },
-                "id": {
-                    "type": "string",
-                    "example": "uuid-123"
-                },
-                "is_built_in": {
-                    "type": "boolean",
-                    "example": true
-                },
-                "is_enabled": {
-                    "type": "boolean",
-                    "example": true
-                },
-                "name": {
-                    "type": "string",
-                    "example": "完整性检查模板"
-                },
-                "parameters": {
-                    "type": "object"
-                },
-                "rule_logic": {
-                    "type": "object"
-                },
-                "tags": {
-                    "type": "object"
-                },
-                "type": {
-                    "type": "string",
-                    "example": "completeness"
-                },
-                "updated_at": {
-                    "type": "string",
-                    "example": "2024-01-01T00:00:00Z"
-                },
-                "updated_by": {
-                    "type": "string",
-                    "example": "system"
-                },
-                "version": {
-                    "type": "string",
-                    "example": "1.0"
-                }
-            }
-        },
-        "governance.QualityTaskExecutionListResponse": {
-            "type": "object",
-            "properties": {
-                "list": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/governance.QualityTaskExecutionResponse"
-                    }
-                },
-                "page": {
-                    "type": "integer",
-                    "example": 1
-                },
-                "size": {
-                    "type": "integer",
-                    "example": 10
-                },
-                "total": {
-                    "type": "integer",
-                    "example": 50
-                }
-            }
-        },
-        "governance.QualityTaskExecutionResponse": {
-            "type": "object",
-            "properties": {
-                "created_at": {
-                    "type": "string",
-                    "example": "2024-01-01T00:00:00Z"
-                },
-                "duration": {
-                    "type": "integer",
-                    "example": 300000
-                },
-                "end_time": {
-                    "type": "string",
-                    "example": "2024-01-01T00:05:00Z"
-                },
-                "error_message": {
-                    "type": "string"
-                },
-                "executed_by": {
-                    "type": "string",
-                    "example": "admin"
-                },
-                "execution_results": {
-                    "type": "object"
-                },
-                "execution_type": {
-                    "type": "string",
-                    "example": "manual"
-                },
-                "failed_rules": {
-                    "type": "integer",
-                    "example": 1
-                },
-                "id": {
-                    "type": "string",
-                    "example": "uuid-123"
-                },
-                "issue_count": {
-                    "type": "integer",
-                    "example": 2
-                },
-                "overall_score": {
-                    "type": "number",
-                    "example": 0.8
-                },
-                "passed_rules": {
-                    "type": "integer",
-                    "example": 4
-                },
-                "start_time": {
-                    "type": "string",
-                    "example": "2024-01-01T00:00:00Z"
-                },
-                "status": {
-                    "type": "string",
-                    "example": "completed"
-                },
-                "task_id": {
-                    "type": "string",
-                    "example": "uuid-456"
-                },
-                "total_rules_executed": {
-                    "type": "integer",
-                    "example": 5
-                },
-                "trigger_source": {
-                    "type": "string",
-                    "example": "manual"
-                },
-                "updated_at": {
-                    "type": "string",
-                    "example": "2024-01-01T00:05:00Z"
-                }
-            }
-        },
-        "governance.QualityTaskListResponse": {
-            "type": "object",
-            "properties": {
-                "list": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/governance.QualityTaskResponse"
-                    }
-                },
-                "page": {
-                    "type": "integer",
-                    "example": 1
-                },
-                "size": {
-                    "type": "integer",
-                    "example": 10
-                },
-                "total": {
-                    "type": "integer",
-                    "example": 20
-                }
-            }
-        },
-        "governance.QualityTaskResponse": {
-            "type": "object",
-            "properties": {
-                "created_at": {
-                    "type": "string",
-                    "example": "2024-01-01T00:00:00Z"
-                },
-                "created_by": {
-                    "type": "string",
-                    "example": "admin"
-                },
-                "description": {
-                    "type": "string",
-                    "example": "定期检测用户表数据质量"
-                },
-                "execution_count": {
-                    "type": "integer",
-                    "example": 5
-                },
-                "failure_count": {
-                    "type": "integer",
-                    "example": 1
-                },
-                "field_rules": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/governance.FieldRuleResponse"
-                    }
-                },
-                "id": {
-                    "type": "string",
-                    "example": "uuid-123"
-                },
-                "interface_id": {
-                    "type": "string",
-                    "example": "uuid-interface-123"
-                },
-                "is_enabled": {
-                    "type": "boolean",
-                    "example": true
-                },
-                "last_executed": {
-                    "type": "string",
-                    "example": "2024-01-01T00:00:00Z"
-                },
-                "library_id": {
-                    "type": "string",
-                    "example": "uuid-lib-123"
-                },
-                "library_type": {
-                    "type": "string",
-                    "example": "thematic"
-                },
-                "name": {
-                    "type": "string",
-                    "example": "用户表质量检测任务"
-                },
-                "next_execution": {
-                    "type": "string",
-                    "example": "2024-01-02T00:00:00Z"
-                },
-                "notification_config": {
-                    "$ref": "#/definitions/governance.NotificationConfigResponse"
-                },
-                "priority": {
-                    "type": "integer",
-                    "example": 50
-                },
-                "schedule_config": {
-                    "$ref": "#/definitions/governance.ScheduleConfigResponse"
-                },
-                "status": {
-                    "type": "string",
-                    "example": "pending"
-                },
-                "success_count": {
-                    "type": "integer",
-                    "example": 4
-                },
-                "target_schema": {
-                    "type": "string",
-                    "example": "public"
-                },
-                "target_table": {
-                    "type": "string",
-                    "example": "users"
-                },
-                "updated_at": {
-                    "type": "string",
-                    "example": "2024-01-01T00:00:00Z"
-                },
-                "updated_by": {
-                    "type": "string",
-                    "example": "admin"
-                }
-            }
-        },
-        "governance.RuleTestResult": {
-            "type": "object",
-            "properties": {
-                "error_message": {
-                    "type": "string"
-                },
-                "execution_time": {
-                    "type": "integer",
-                    "example": 50
-                },
-                "field_results": {
-                    "type": "object"
-                },
-                "issues": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    },
-                    "example": [
-                        "[\"字段name为空\"]"
-                    ]
-                },
-                "modifications": {
-                    "type": "object"
-                },
-                "original_data": {
-                    "type": "object"
-                },
-                "processed_data": {
-                    "type": "object"
-                },
-                "quality_score": {
-                    "type": "number",
-                    "example": 0.85
-                },
-                "rule_name": {
-                    "type": "string",
-                    "example": "完整性检查"
-                },
-                "rule_template_id": {
-                    "type": "string",
-                    "example": "uuid-123"
-                },
-                "rule_type": {
-                    "type": "string",
-                    "example": "quality"
-                },
-                "success": {
-                    "type": "boolean",
-                    "example": true
-                }
-            }
-        },
-        "governance.RunQualityCheckRequest": {
-            "type": "object",
-            "required": [
-                "object_id",
-                "object_type"
-            ],
-            "properties": {
-                "object_id": {
-                    "type": "string",
-                    "example": "uuid-123"
-                },
-                "object_type": {
-                    "type": "string",
-                    "enum": [
-                        "interface",
-                        "thematic_interface"
-                    ],
-                    "example": "interface"
-                }
-            }
-        },
-        "governance.RuntimeConfig": {
-            "type": "object",
-            "properties": {
-                "case_sensitive": {
-                    "type": "boolean",
-                    "example": false
-                },
-                "check_nullable": {
-                    "type": "boolean",
-                    "example": true
-                },
-                "custom_params": {
-                    "description": "自定义参数",
-                    "type": "object"
-                },
-                "trim_whitespace": {
-                    "type": "boolean",
-                    "example": true
-                }
-            }
-        },
-        "governance.ScheduleConfigRequest": {
-            "type": "object",
-            "required": [
-                "type"
-            ],
-            "properties": {
-                "cron_expr": {
-                    "type": "string",
-                    "example": "0 0 * * *"
-                },
-                "interval": {
-                    "type": "integer",
-                    "example": 3600
-                },
-                "start_time": {
-                    "type": "string",
-                    "example": "2024-01-01T00:00:00Z"
-                },
-                "type": {
-                    "type": "string",
-                    "enum": [
-                        "cron",
-                        "interval",
-                        "once",
-                        "manual"
-                    ],
-                    "example": "cron"
-                }
-            }
-        },
-        "governance.ScheduleConfigResponse": {
-            "type": "object",
-            "properties": {
-                "cron_expr": {
-                    "type": "string",
-                    "example": "0 0 * * *"
-                },
-                "interval": {
-                    "type": "integer",
-                    "example": 3600
-                },
-                "start_time": {
-                    "type": "string",
-                    "example": "2024-01-01T00:00:00Z"
-                },
-                "type": {
-                    "type": "string",
-                    "example": "cron"
-                }
-            }
-        },
-        "governance.SystemLogListResponse": {
-            "type": "object",
-            "properties": {
-                "list": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/governance.SystemLogResponse"
-                    }
-                },
-                "page": {
-                    "type": "integer",
-                    "example": 1
-                },
-                "size": {
-                    "type": "integer",
-                    "example": 10
-                },
-                "total": {
-                    "type": "integer",
-                    "example": 100
-                }
-            }
-        },
-        "governance.SystemLogResponse": {
-            "type": "object",
-            "properties": {
-                "id": {
-                    "type": "string",
-                    "example": "uuid-123"
-                },
-                "object_id": {
-                    "type": "string",
-                    "example": "uuid-456"
-                },
-                "object_type": {
-                    "type": "string",
-                    "example": "quality_rule"
-                },
-                "operation_content": {
-                    "type": "object"
-                },
-                "operation_result": {
-                    "type": "string",
-                    "example": "success"
-                },
-                "operation_time": {
-                    "type": "string",
-                    "example": "2024-01-01T00:00:00Z"
-                },
-                "operation_type": {
-                    "type": "string",
-                    "example": "create"
-                },
-                "operator_id": {
-                    "type": "string",
-                    "example": "uuid-789"
-                },
-                "operator_ip": {
-                    "type": "string",
-                    "example": "192.168.1.1"
-                },
-                "operator_name": {
-                    "type": "string",
-                    "example": "admin"
-                }
-            }
-        },
-        "governance.TestBatchRulesRequest": {
-            "type": "object",
-            "required": [
-                "test_data"
-            ],
-            "properties": {
-                "cleansing_rules": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/governance.TestCleansingRuleItem"
-                    }
-                },
-                "execution_order": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    },
-                    "example": [
-                        "[\"quality\"",
-                        "\"cleansing\"",
-                        "\"masking\"]"
-                    ]
-                },
-                "masking_rules": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/governance.TestMaskingRuleItem"
-                    }
-                },
-                "quality_rules": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/governance.TestQualityRuleItem"
-                    }
-                },
-                "test_data": {
-                    "type": "object"
-                }
-            }
-        },
-        "governance.TestCleansingRuleItem": {
-            "type": "object",
-            "properties": {
-                "backup_original": {
-                    "type": "boolean",
-                    "example": true
-                },
-                "cleansing_config": {
-                    "type": "object"
-                },
-                "target_fields": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    },
-                    "example": [
-                        "[\"email\"]"
-                    ]
-                },
-                "template_id": {
-                    "type": "string",
-                    "example": "uuid-123"
-                },
-                "trigger_condition": {
-                    "type": "string"
-                }
-            }
-        },
-        "governance.TestCleansingRuleRequest": {
-            "type": "object",
-            "required": [
-                "target_fields",
-                "template_id",
-                "test_data"
-            ],
-            "properties": {
-                "backup_original": {
-                    "type": "boolean",
-                    "example": true
-                },
-                "cleansing_config": {
-                    "type": "object"
-                },
-                "target_fields": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    },
-                    "example": [
-                        "[\"email\"",
-                        "\"address\"]"
-                    ]
-                },
-                "template_id": {
-                    "type": "string",
-                    "example": "uuid-123"
-                },
-                "test_data": {
-                    "type": "object"
-                },
-                "trigger_condition": {
-                    "type": "string",
-                    "example": "email != ''"
-                }
-            }
-        },
-        "governance.TestMaskingRuleItem": {
-            "type": "object",
-            "properties": {
-                "masking_config": {
-                    "type": "object"
-                },
-                "preserve_format": {
-                    "type": "boolean",
-                    "example": true
-                },
-                "target_fields": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    },
-                    "example": [
-                        "[\"phone\"]"
-                    ]
-                },
-                "template_id": {
-                    "type": "string",
-                    "example": "uuid-123"
-                }
-            }
-        },
-        "governance.TestMaskingRuleRequest": {
-            "type": "object",
-            "required": [
-                "target_fields",
-                "template_id",
-                "test_data"
-            ],
-            "properties": {
-                "masking_config": {
-                    "type": "object"
-                },
-                "preserve_format": {
-                    "type": "boolean",
-                    "example": true
-                },
-                "target_fields": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    },
-                    "example": [
-                        "[\"phone\"",
-                        "\"id_card\"]"
-                    ]
-                },
-                "template_id": {
-                    "type": "string",
-                    "example": "uuid-123"
-                },
-                "test_data": {
-                    "type": "object"
-                }
-            }
-        },
-        "governance.TestQualityRuleItem": {
-            "type": "object",
-            "properties": {
-                "rule_template_id": {
-                    "type": "string",
-                    "example": "uuid-123"
-                },
-                "runtime_config": {
-                    "type": "object"
-                },
-                "target_fields": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    },
-                    "example": [
-                        "[\"name\"]"
-                    ]
-                },
-                "threshold": {
-                    "type": "object"
-                }
-            }
-        },
-        "governance.TestQualityRuleRequest": {
-            "type": "object",
-            "required": [
-                "rule_template_id",
-                "target_fields",
-                "test_data"
-            ],
-            "properties": {
-                "rule_template_id": {
-                    "type": "string",
-                    "example": "uuid-123"
-                },
-                "runtime_config": {
-                    "type": "object"
-                },
-                "target_fields": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    },
-                    "example": [
-                        "[\"name\"",
-                        "\"email\"]"
-                    ]
-                },
-                "test_data": {
-                    "type": "object"
-                },
-                "threshold": {
-                    "type": "object"
-                }
-            }
-        },
-        "governance.TestRulePreviewRequest": {
-            "type": "object",
-            "required": [
-                "rule_type",
-                "sample_data",
-                "target_fields",
-                "template_id"
-            ],
-            "properties": {
-                "configuration": {
-                    "type": "object"
-                },
-                "rule_type": {
-                    "type": "string",
-                    "enum": [
-                        "quality",
-                        "masking",
-                        "cleansing"
-                    ],
-                    "example": "quality"
-                },
-                "sample_data": {
-                    "type": "object"
-                },
-                "target_fields": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    },
-                    "example": [
-                        "[\"name\"]"
-                    ]
-                },
-                "template_id": {
-                    "type": "string",
-                    "example": "uuid-123"
-                }
-            }
-        },
-        "governance.TestRulePreviewResponse": {
-            "type": "object",
-            "properties": {
-                "config_validation": {
-                    "type": "object",
-                    "properties": {
-                        "is_valid": {
-                            "type": "boolean",
-                            "example": true
-                        },
-                        "issues": {
-                            "type": "array",
-                            "items": {
-                                "type": "string"
-                            },
-                            "example": [
-                                "[\"阈值配置缺失\"]"
-                            ]
-                        }
-                    }
-                },
-                "estimated_impact": {
-                    "type": "object",
-                    "properties": {
-                        "affected_fields": {
-                            "type": "integer",
-                            "example": 2
-                        },
-                        "confidence": {
-                            "type": "number",
-                            "example": 0.9
-                        },
-                        "risk_level": {
-                            "type": "string",
-                            "enum": [
-                                "low",
-                                "medium",
-                                "high"
-                            ],
-                            "example": "low"
-                        }
-                    }
-                },
-                "expected_changes": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    },
-                    "example": [
-                        "[\"字段name将被检查是否为空\"]"
-                    ]
-                },
-                "original_data": {
-                    "type": "object"
-                },
-                "preview_result": {
-                    "type": "object"
-                },
-                "rule_name": {
-                    "type": "string",
-                    "example": "完整性检查"
-                },
-                "rule_type": {
-                    "type": "string",
-                    "example": "quality"
-                }
-            }
-        },
-        "governance.TestRuleResponse": {
-            "type": "object",
-            "properties": {
-                "execution_time": {
-                    "type": "integer",
-                    "example": 150
-                },
-                "failed_rules": {
-                    "type": "integer",
-                    "example": 1
-                },
-                "overall_success": {
-                    "type": "boolean",
-                    "example": false
-                },
-                "recommendations": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    },
-                    "example": [
-                        "[\"建议调整完整性检查阈值\"]"
-                    ]
-                },
-                "results": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/governance.RuleTestResult"
-                    }
-                },
-                "successful_rules": {
-                    "type": "integer",
-                    "example": 2
-                },
-                "summary": {
-                    "type": "object",
-                    "properties": {
-                        "cleansing_rules": {
-                            "type": "integer",
-                            "example": 1
-                        },
-                        "masking_rules": {
-                            "type": "integer",
-                            "example": 1
-                        },
-                        "overall_score": {
-                            "type": "number",
-                            "example": 0.75
-                        },
-                        "quality_checks": {
-                            "type": "integer",
-                            "example": 1
-                        }
-                    }
-                },
-                "test_id": {
-                    "type": "string",
-                    "example": "uuid-test-123"
-                },
-                "total_rules": {
-                    "type": "integer",
-                    "example": 3
-                }
-            }
-        },
-        "governance.ThresholdConfig": {
-            "type": "object",
-            "properties": {
-                "allowed_values": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    },
-                    "example": [
-                        "[\"active\"",
-                        "\"inactive\"]"
-                    ]
-                },
-                "custom_threshold": {
-                    "description": "扩展阈值",
-                    "type": "object"
-                },
-                "max_length": {
-                    "type": "integer",
-                    "example": 255
-                },
-                "max_value": {
-                    "type": "number",
-                    "example": 100
-                },
-                "min_length": {
-                    "type": "integer",
-                    "example": 1
-                },
-                "min_value": {
-                    "type": "number",
-                    "example": 0
-                },
-                "pattern": {
-                    "type": "string",
-                    "example": "^[a-zA-Z0-9]+$"
-                }
-            }
-        },
-        "governance.UpdateCleansingRuleRequest": {
-            "type": "object",
-            "properties": {
-                "cleansing_logic": {
-                    "type": "object"
-                },
-                "default_config": {
-                    "type": "object"
-                },
-                "description": {
-                    "type": "string",
-                    "example": "更新后的描述"
-                },
-                "is_enabled": {
-                    "type": "boolean",
-                    "example": false
-                },
-                "name": {
-                    "type": "string",
-                    "example": "更新后的清洗规则模板"
-                },
-                "parameters": {
-                    "type": "object"
-                },
-                "tags": {
-                    "type": "object"
-                }
-            }
-        },
-        "governance.UpdateMaskingRuleRequest": {
-            "type": "object",
-            "properties": {
-                "description": {
-                    "type": "string",
-                    "example": "更新后的描述"
-                },
-                "is_enabled": {
-                    "type": "boolean",
-                    "example": false
-                },
-                "masking_logic": {
-                    "type": "object"
-                },
-                "name": {
-                    "type": "string",
-                    "example": "更新后的脱敏规则模板"
-                },
-                "parameters": {
-                    "type": "object"
-                },
-                "tags": {
-                    "type": "object"
-                }
-            }
-        },
-        "governance.UpdateMetadataRequest": {
-            "type": "object",
-            "properties": {
-                "content": {
-                    "type": "object"
-                },
-                "description": {
-                    "type": "string",
-                    "example": "更新后的描述"
-                },
-                "name": {
-                    "type": "string",
-                    "example": "更新后的元数据"
-                }
-            }
-        },
-        "governance.UpdateQualityRuleRequest": {
-            "type": "object",
-            "properties": {
-                "default_config": {
-                    "type": "object"
-                },
-                "description": {
-                    "type": "string",
-                    "example": "更新后的描述"
-                },
-                "is_enabled": {
-                    "type": "boolean",
-                    "example": false
-                },
-                "name": {
-                    "type": "string",
-                    "example": "更新后的质量规则模板"
-                },
-                "parameters": {
-                    "type": "object"
-                },
-                "rule_logic": {
-                    "type": "object"
-                },
-                "tags": {
-                    "type": "object"
-                }
-            }
-        },
-        "governance.UpdateQualityTaskRequest": {
-            "type": "object",
-            "properties": {
-                "description": {
-                    "type": "string",
-                    "example": "更新后的描述"
-                },
-                "field_rules": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/governance.FieldRuleConfig"
-                    }
-                },
-                "is_enabled": {
-                    "type": "boolean",
-                    "example": false
-                },
-                "name": {
-                    "type": "string",
-                    "example": "更新后的质量检测任务"
-                },
-                "notification_config": {
-                    "$ref": "#/definitions/governance.NotificationConfigRequest"
-                },
-                "priority": {
-                    "type": "integer",
-                    "example": 80
-                },
-                "schedule_config": {
-                    "$ref": "#/definitions/governance.ScheduleConfigRequest"
-                }
-            }
-        },
-        "meta.CleansingRuleType": {
-            "type": "object",
-            "properties": {
-                "category": {
-                    "type": "string"
-                },
-                "code": {
-                    "type": "string"
-                },
-                "description": {
-                    "type": "string"
-                },
-                "name": {
-                    "type": "string"
-                }
-            }
-        },
-        "meta.DataGovernanceObjectType": {
-            "type": "object",
-            "properties": {
-                "code": {
-                    "type": "string"
-                },
-                "description": {
-                    "type": "string"
-                },
-                "name": {
-                    "type": "string"
-                }
-            }
-        },
-        "meta.DataInterfaceConfigDefinition": {
-            "type": "object",
-            "properties": {
-                "description": {
-                    "type": "string"
-                },
-                "icon": {
-                    "type": "string"
-                },
-                "id": {
-                    "type": "string"
-                },
-                "meta_config": {
-                    "description": "连接配置字段",
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/meta.DataInterfaceConfigField"
-                    }
-                },
-                "name": {
-                    "type": "string"
-                },
-                "type": {
-                    "description": "database, messaging, api, file",
-                    "type": "string"
-                }
-            }
-        },
-        "meta.DataInterfaceConfigField": {
-            "type": "object",
-            "properties": {
-                "default_value": {},
-                "dependencies": {
-                    "description": "依赖关系配置",
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/meta.FieldDependency"
-                    }
-                },
-                "description": {
-                    "type": "string"
-                },
-                "display_name": {
-                    "type": "string"
-                },
-                "group": {
-                    "description": "字段分组",
-                    "type": "string"
-                },
-                "help_text": {
-                    "description": "帮助文本",
-                    "type": "string"
-                },
-                "map_variable": {
-                    "description": "变量",
-                    "type": "object",
-                    "additionalProperties": {
-                        "$ref": "#/definitions/meta.DataInterfaceConfigVariable"
-                    }
-                },
-                "max": {
-                    "description": "用于number类型",
-                    "type": "number"
-                },
-                "min": {
-                    "description": "用于number类型",
-                    "type": "number"
-                },
-                "name": {
-                    "type": "string"
-                },
-                "options": {
-                    "description": "用于select类型",
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    }
-                },
-                "pattern": {
-                    "description": "用于string类型的正则验证",
-                    "type": "string"
-                },
-                "placeholder": {
-                    "description": "前端显示的占位符",
-                    "type": "string"
-                },
-                "required": {
-                    "type": "boolean"
-                },
-                "type": {
-                    "description": "string,text, number, boolean, array, map, enum,map_variable",
-                    "type": "string"
-                }
-            }
-        },
-        "meta.DataInterfaceConfigVariable": {
-            "type": "object",
-            "properties": {
-                "db_field": {
-                    "description": "db_field 类型时，字段名",
-                    "type": "string"
-                },
-                "db_table": {
-                    "description": "db_field 类型时，表名",
-                    "type": "string"
-                },
-                "description": {
-                    "type": "string"
-                },
-                "format": {
-                    "description": "timestamp 类型时，格式为 \"2006-01-02T15:04:05Z\"",
-                    "type": "string"
-                },
-                "name": {
-                    "type": "string"
-                },
-                "type": {
-                    "description": "string,number,timestamp,db_field",
-                    "type": "string"
-                },
-                "value": {
-                    "description": "固定值或者 current_time 表示当前时间,current_time+1d 表示当前时间加1天",
-                    "type": "string"
-                }
-            }
-        },
-        "meta.DataMaskingType": {
-            "type": "object",
-            "properties": {
-                "code": {
-                    "type": "string"
-                },
-                "description": {
-                    "type": "string"
-                },
-                "example": {
-                    "type": "string"
-                },
-                "name": {
-                    "type": "string"
-                }
-            }
-        },
-        "meta.DataSourceConfigField": {
-            "type": "object",
-            "properties": {
-                "default_value": {},
-                "dependencies": {
-                    "description": "依赖关系配置",
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/meta.DataSourceFieldDependency"
-                    }
-                },
-                "description": {
-                    "type": "string"
-                },
-                "display_name": {
-                    "type": "string"
-                },
-                "group": {
-                    "description": "字段分组",
-                    "type": "string"
-                },
-                "help_text": {
-                    "description": "帮助文本",
-                    "type": "string"
-                },
-                "max": {
-                    "description": "用于number类型",
-                    "type": "number"
-                },
-                "min": {
-                    "description": "用于number类型",
-                    "type": "number"
-                },
-                "name": {
-                    "type": "string"
-                },
-                "options": {
-                    "description": "用于select类型",
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    }
-                },
-                "pattern": {
-                    "description": "用于string类型的正则验证",
-                    "type": "string"
-                },
-                "placeholder": {
-                    "description": "前端显示的占位符",
-                    "type": "string"
-                },
-                "required": {
-                    "type": "boolean"
-                },
-                "type": {
-                    "description": "string, number, boolean, array, object, enum",
-                    "type": "string"
-                }
-            }
-        },
-        "meta.DataSourceExample": {
-            "type": "object",
-            "properties": {
-                "connection_config": {
-                    "type": "object",
-                    "additionalProperties": true
-                },
-                "description": {
-                    "type": "string"
-                },
-                "name": {
-                    "type": "string"
-                },
-                "params_config": {
-                    "type": "object",
-                    "additionalProperties": true
-                }
-            }
-        },
-        "meta.DataSourceFieldDependency": {
-            "type": "object",
-            "properties": {
-                "action": {
-                    "description": "动作：show, hide, enable, disable, require, optional",
-                    "type": "string"
-                },
-                "condition": {
-                    "description": "条件：equals, not_equals, in, not_in, greater_than, less_than, contains, not_contains",
-                    "type": "string"
-                },
-                "field": {
-                    "description": "依赖的字段名",
-                    "type": "string"
-                },
-                "value": {
-                    "description": "条件值"
-                }
-            }
-        },
-        "meta.DataSourceTypeDefinition": {
-            "type": "object",
-            "properties": {
-                "category": {
-                    "type": "string"
-                },
-                "description": {
-                    "type": "string"
-                },
-                "documentation": {
-                    "type": "string"
-                },
-                "examples": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/meta.DataSourceExample"
-                    }
-                },
-                "icon": {
-                    "type": "string"
-                },
-                "id": {
-                    "type": "string"
-                },
-                "is_active": {
-                    "type": "boolean"
-                },
-                "meta_config": {
-                    "description": "连接配置字段",
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/meta.DataSourceConfigField"
-                    }
-                },
-                "name": {
-                    "type": "string"
-                },
-                "params_config": {
-                    "description": "参数配置字段",
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/meta.DataSourceConfigField"
-                    }
-                },
-                "supported_features": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    }
-                },
-                "type": {
-                    "description": "database, messaging, api, file",
-                    "type": "string"
-                },
-                "validation_rules": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/meta.DataSourceValidationRule"
-                    }
-                }
-            }
-        },
-        "meta.DataSourceValidationRule": {
-            "type": "object",
-            "properties": {
-                "condition": {
-                    "type": "object",
-                    "additionalProperties": true
-                },
-                "field": {
-                    "type": "string"
-                },
-                "level": {
-                    "description": "error, warning",
-                    "type": "string"
-                },
-                "message": {
-                    "type": "string"
-                },
-                "name": {
-                    "type": "string"
-                },
-                "type": {
-                    "description": "required, format, range, custom",
-                    "type": "string"
-                }
-            }
-        },
-        "meta.FieldDependency": {
-            "type": "object",
-            "properties": {
-                "action": {
-                    "description": "动作：show, hide, enable, disable, require, optional",
-                    "type": "string"
-                },
-                "condition": {
-                    "description": "条件：equals, not_equals, in, not_in, greater_than, less_than, contains, not_contains",
-                    "type": "string"
-                },
-                "field": {
-                    "description": "依赖的字段名",
-                    "type": "string"
-                },
-                "value": {
-                    "description": "条件值"
-                }
-            }
-        },
-        "meta.LineageRelationType": {
-            "type": "object",
-            "properties": {
-                "code": {
-                    "type": "string"
-                },
-                "description": {
-                    "type": "string"
-                },
-                "name": {
-                    "type": "string"
-                }
-            }
-        },
-        "meta.MetadataType": {
-            "type": "object",
-            "properties": {
-                "code": {
-                    "type": "string"
-                },
-                "description": {
-                    "type": "string"
-                },
-                "name": {
-                    "type": "string"
-                }
-            }
-        },
-        "meta.MonitorQueryRequest": {
-            "type": "object",
-            "properties": {
-                "end_time": {
-                    "description": "Unix 时间戳（秒），可选",
-                    "type": "integer"
-                },
-                "limit": {
-                    "description": "限制结果数量，主要用于日志查询",
-                    "type": "integer"
-                },
-                "query": {
-                    "description": "PromQL 或 LogQL 查询语句",
-                    "type": "string"
-                },
-                "query_type": {
-                    "description": "metrics 或 logs",
-                    "type": "string"
-                },
-                "start_time": {
-                    "description": "Unix 时间戳（秒），可选",
-                    "type": "integer"
-                },
-                "step": {
-                    "description": "步长（秒），仅用于区间查询",
-                    "type": "integer"
-                }
-            }
-        },
-        "meta.QualityCheckStatus": {
-            "type": "object",
-            "properties": {
-                "code": {
-                    "type": "string"
-                },
-                "color": {
-                    "type": "string"
-                },
-                "description": {
-                    "type": "string"
-                },
-                "name": {
-                    "type": "string"
-                }
-            }
-        },
-        "meta.QualityIssueSeverity": {
-            "type": "object",
-            "properties": {
-                "code": {
-                    "type": "string"
-                },
-                "color": {
-                    "type": "string"
-                },
-                "description": {
-                    "type": "string"
-                },
-                "name": {
-                    "type": "string"
-                },
-                "priority": {
-                    "type": "integer"
-                }
-            }
-        },
-        "meta.QualityIssueStatus": {
-            "type": "object",
-            "properties": {
-                "code": {
-                    "type": "string"
-                },
-                "color": {
-                    "type": "string"
-                },
-                "description": {
-                    "type": "string"
-                },
-                "name": {
-                    "type": "string"
-                }
-            }
-        },
-        "meta.QualityMetricType": {
-            "type": "object",
-            "properties": {
-                "code": {
-                    "type": "string"
-                },
-                "description": {
-                    "type": "string"
-                },
-                "name": {
-                    "type": "string"
-                },
-                "unit": {
-                    "type": "string"
-                }
-            }
-        },
-        "meta.QualityReportType": {
-            "type": "object",
-            "properties": {
-                "code": {
-                    "type": "string"
-                },
-                "description": {
-                    "type": "string"
-                },
-                "name": {
-                    "type": "string"
-                }
-            }
-        },
-        "meta.QualityRuleType": {
-            "type": "object",
-            "properties": {
-                "category": {
-                    "type": "string"
-                },
-                "code": {
-                    "type": "string"
-                },
-                "description": {
-                    "type": "string"
-                },
-                "name": {
-                    "type": "string"
-                }
-            }
-        },
-        "meta.TaskStatus": {
-            "type": "object",
-            "properties": {
-                "code": {
-                    "type": "string"
-                },
-                "color": {
-                    "type": "string"
-                },
-                "description": {
-                    "type": "string"
-                },
-                "name": {
-                    "type": "string"
-                }
-            }
-        },
-        "meta.TaskType": {
-            "type": "object",
-            "properties": {
-                "code": {
-                    "type": "string"
-                },
-                "description": {
-                    "type": "string"
-                },
-                "name": {
-                    "type": "string"
-                }
-            }
-        },
-        "meta.ThematicInterfaceStatus": {
-            "type": "object",
-            "properties": {
-                "description": {
-                    "type": "string"
-                },
-                "id": {
-                    "type": "string"
-                },
-                "name": {
-                    "type": "string"
-                }
-            }
-        },
-        "meta.ThematicInterfaceType": {
-            "type": "object",
-            "properties": {
-                "description": {
-                    "type": "string"
-                },
-                "id": {
-                    "type": "string"
-                },
-                "name": {
-                    "type": "string"
-                }
-            }
-        },
-        "meta.ThematicLibraryAccessLevel": {
-            "type": "object",
-            "properties": {
-                "description": {
-                    "type": "string"
-                },
-                "id": {
-                    "type": "string"
-                },
-                "name": {
-                    "type": "string"
-                }
-            }
-        },
-        "meta.ThematicLibraryCategory": {
-            "type": "object",
-            "properties": {
-                "description": {
-                    "type": "string"
-                },
-                "id": {
-                    "type": "string"
-                },
-                "name": {
-                    "type": "string"
-                }
-            }
-        },
-        "meta.ThematicLibraryDomain": {
-            "type": "object",
-            "properties": {
-                "description": {
-                    "type": "string"
-                },
-                "id": {
-                    "type": "string"
-                },
-                "name": {
-                    "type": "string"
-                }
-            }
-        },
-        "meta.ThematicLibraryStatus": {
-            "type": "object",
-            "properties": {
-                "description": {
-                    "type": "string"
-                },
-                "id": {
-                    "type": "string"
-                },
-                "name": {
-                    "type": "string"
-                }
-            }
-        },
-        "meta.ThematicSyncConfigDefinition": {
-            "type": "object",
-            "properties": {
-                "category": {
-                    "description": "aggregation, key_matching, cleansing, privacy, quality",
-                    "type": "string"
-                },
-                "description": {
-                    "type": "string"
-                },
-                "fields": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/meta.ThematicSyncConfigField"
-                    }
-                },
-                "id": {
-                    "type": "string"
-                },
-                "name": {
-                    "type": "string"
-                }
-            }
-        },
-        "meta.ThematicSyncConfigField": {
-            "type": "object",
-            "properties": {
-                "default_value": {},
-                "dependencies": {
-                    "description": "字段依赖关系",
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/meta.FieldDependency"
-                    }
-                },
-                "description": {
-                    "type": "string"
-                },
-                "display_name": {
-                    "type": "string"
-                },
-                "group": {
-                    "description": "字段分组",
-                    "type": "string"
-                },
-                "help_text": {
-                    "description": "帮助文本",
-                    "type": "string"
-                },
-                "max": {
-                    "description": "用于number类型",
-                    "type": "number"
-                },
-                "min": {
-                    "description": "用于number类型",
-                    "type": "number"
-                },
-                "name": {
-                    "type": "string"
-                },
-                "options": {
-                    "description": "用于enum类型",
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    }
-                },
-                "pattern": {
-                    "description": "用于string类型的正则验证",
-                    "type": "string"
-                },
-                "placeholder": {
-                    "description": "前端显示的占位符",
-                    "type": "string"
-                },
-                "required": {
-                    "type": "boolean"
-                },
-                "type": {
-                    "description": "string, number, boolean, array, object, enum",
-                    "type": "string"
-                }
-            }
-        },
-        "meta.TransformationRuleType": {
-            "type": "object",
-            "properties": {
-                "category": {
-                    "type": "string"
-                },
-                "code": {
-                    "type": "string"
-                },
-                "description": {
-                    "type": "string"
-                },
-                "name": {
-                    "type": "string"
-                }
-            }
-        },
-        "meta.ValidationRuleType": {
-            "type": "object",
-            "properties": {
-                "category": {
-                    "type": "string"
-                },
-                "code": {
-                    "type": "string"
-                },
-                "description": {
-                    "type": "string"
-                },
-                "name": {
-                    "type": "string"
-                }
-            }
-        },
-        "models.ApiApplication": {
-            "type": "object",
-            "properties": {
-                "api_interfaces": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/models.ApiInterface"
-                    }
-                },
-                "api_keys": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/models.ApiKey"
-                    }
-                },
-                "contact_person": {
-                    "type": "string"
-                },
-                "contact_phone": {
-                    "type": "string"
-                },
-                "created_at": {
-                    "type": "string"
-                },
-                "created_by": {
-                    "type": "string"
-                },
-                "description": {
-                    "type": "string"
-                },
-                "id": {
-                    "type": "string"
-                },
-                "name": {
-                    "type": "string"
-                },
-                "path": {
-                    "description": "应用访问路径，例如 \"user-center\"",
-                    "type": "string"
-                },
-                "status": {
-                    "description": "active/inactive",
-                    "type": "string"
-                },
-                "thematic_library": {
-                    "description": "关联关系",
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/models.ThematicLibrary"
-                        }
-                    ]
-                },
-                "thematic_library_id": {
-                    "type": "string"
-                },
-                "updated_at": {
-                    "type": "string"
-                },
-                "updated_by": {
-                    "type": "string"
-                }
-            }
-        },
-        "models.ApiInterface": {
-            "type": "object",
-            "properties": {
-                "api_application": {
-                    "$ref": "#/definitions/models.ApiApplication"
-                },
-                "api_application_id": {
-                    "type": "string"
-                },
-                "created_at": {
-                    "type": "string"
-                },
-                "created_by": {
-                    "type": "string"
-                },
-                "description": {
-                    "type": "string"
-                },
-                "id": {
-                    "type": "string"
-                },
-                "masking_rules": {
-                    "description": "数据脱敏规则配置",
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/models.JSONB"
-                        }
-                    ]
-                },
-                "path": {
-                    "description": "对外暴露的路径，例如 \"users\"",
-                    "type": "string"
-                },
-                "status": {
-                    "description": "active, inactive",
-                    "type": "string"
-                },
-                "thematic_interface": {
-                    "$ref": "#/definitions/models.ThematicInterface"
-                },
-                "thematic_interface_id": {
-                    "type": "string"
-                }
-            }
-        },
-        "models.ApiKey": {
-            "type": "object",
-            "properties": {
-                "applications": {
-                    "description": "多对多关系：一个ApiKey可以访问多个ApiApplication",
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/models.ApiApplication"
-                    }
-                },
-                "created_at": {
-                    "type": "string"
-                },
-                "created_by": {
-                    "type": "string"
-                },
-                "description": {
-                    "type": "string"
-                },
-                "expires_at": {
-                    "type": "string"
-                },
-                "id": {
-                    "type": "string"
-                },
-                "key_prefix": {
-                    "description": "Key的前缀，用于快速识别",
-                    "type": "string"
-                },
-                "last_used_at": {
-                    "type": "string"
-                },
-                "name": {
-                    "description": "ApiKey名称",
-                    "type": "string"
-                },
-                "status": {
-                    "description": "active, inactive, revoked",
-                    "type": "string"
-                },
-                "updated_at": {
-                    "type": "string"
-                },
-                "updated_by": {
-                    "type": "string"
-                },
-                "usage_count": {
-                    "type": "integer"
-                }
-            }
-        },
-        "models.ApiRateLimit": {
-            "type": "object",
-            "properties": {
-                "api_key": {
-                    "$ref": "#/definitions/models.ApiKey"
-                },
-                "api_key_id": {
-                    "description": "冗余字段，便于查询",
-                    "type": "string"
-                },
-                "application": {
-                    "$ref": "#/definitions/models.ApiApplication"
-                },
-                "application_id": {
-                    "description": "冗余字段，便于查询",
-                    "type": "string"
-                },
-                "created_at": {
-                    "type": "string"
-                },
-                "created_by": {
-                    "type": "string"
-                },
-                "description": {
-                    "type": "string"
-                },
-                "id": {
-                    "type": "string"
-                },
-                "is_enabled": {
-                    "type": "boolean"
-                },
-                "max_requests": {
-                    "description": "最大请求数",
-                    "type": "integer"
-                },
-                "rate_limit_type": {
-                    "description": "global/api_key/application",
-                    "type": "string"
-                },
-                "target_id": {
-                    "description": "目标ID：api_key_id或application_id，全局时为null",
-                    "type": "string"
-                },
-                "time_window": {
-                    "description": "时间窗口，单位秒",
-                    "type": "integer"
-                },
-                "updated_at": {
-                    "type": "string"
-                },
-                "updated_by": {
-                    "type": "string"
-                }
-            }
-        },
-        "models.ApiRateLimitStatistics": {
-            "type": "object",
-            "properties": {
-                "enabled_rules": {
-                    "description": "启用的限流规则数",
-                    "type": "integer"
-                },
-                "recent_rules": {
-                    "description": "最近7天创建的规则数",
-                    "type": "integer"
-                },
-                "total_rules": {
-                    "description": "总限流规则数",
-                    "type": "integer"
-                },
-                "type_distribution": {
-                    "description": "按类型统计的规则分布",
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/models.RateLimitTypeStats"
-                    }
-                }
-            }
-        },
-        "models.ApiUsageLog": {
-            "type": "object",
-            "properties": {
-                "api_path": {
-                    "type": "string"
-                },
-                "application": {
-                    "$ref": "#/definitions/models.ApiApplication"
-                },
-                "application_id": {
-                    "type": "string"
-                },
-                "created_by": {
-                    "type": "string"
-                },
-                "error_message": {
-                    "type": "string"
-                },
-                "id": {
-                    "type": "string"
-                },
-                "method": {
-                    "type": "string"
-                },
-                "request_ip": {
-                    "type": "string"
-                },
-                "request_size": {
-                    "type": "integer"
-                },
-                "request_time": {
-                    "type": "string"
-                },
-                "response_size": {
-                    "type": "integer"
-                },
-                "response_time": {
-                    "description": "响应时间，毫秒",
-                    "type": "integer"
-                },
-                "status_code": {
-                    "type": "integer"
-                },
-                "user_agent": {
-                    "type": "string"
-                },
-                "user_id": {
-                    "type": "string"
-                },
-                "user_name": {
-                    "type": "string"
-                }
-            }
-        },
-        "models.ApiUsageStatistics": {
-            "type": "object",
-            "properties": {
-                "avg_response_time": {
-                    "description": "平均响应时间（毫秒）",
-                    "type": "integer"
-                },
-                "failed_requests": {
-                    "description": "失败请求数（4xx和5xx状态码）",
-                    "type": "integer"
-                },
-                "rate_limited_requests": {
-                    "description": "被限流的请求数（429状态码）",
-                    "type": "integer"
-                },
-                "status_distribution": {
-                    "description": "按状态码统计的请求分布",
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/models.StatusDistribution"
-                    }
-                },
-                "success_requests": {
-                    "description": "成功请求数（2xx状态码）",
-                    "type": "integer"
-                },
-                "top_applications": {
-                    "description": "请求量TOP5的应用",
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/models.TopApplication"
-                    }
-                },
-                "total_requests": {
-                    "description": "总请求数",
-                    "type": "integer"
-                }
-            }
-        },
-        "models.BasicLibrary": {
-            "type": "object",
-            "properties": {
-                "created_at": {
-                    "type": "string"
-                },
-                "created_by": {
-                    "type": "string"
-                },
-                "data_sources": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/models.DataSource"
-                    }
-                },
-                "description": {
-                    "type": "string",
-                    "example": "存储用户基础信息的数据库"
-                },
-                "id": {
-                    "type": "string",
-                    "example": "550e8400-e29b-41d4-a716-446655440000"
-                },
-                "interfaces": {
-                    "description": "关联关系",
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/models.DataInterface"
-                    }
-                },
-                "name_en": {
-                    "type": "string",
-                    "example": "user_basic_library"
-                },
-                "name_zh": {
-                    "type": "string",
-                    "example": "用户数据基础库"
-                },
-                "status": {
-                    "type": "string",
-                    "example": "active"
-                },
-                "updated_at": {
-                    "type": "string"
-                },
-                "updated_by": {
-                    "type": "string"
-                }
-            }
-        },
-        "models.CleansingRule": {
-            "type": "object",
-            "properties": {
-                "config": {
-                    "type": "object",
-                    "additionalProperties": true
-                },
-                "created_at": {
-                    "type": "string"
-                },
-                "created_by": {
-                    "type": "string"
-                },
-                "id": {
-                    "type": "string"
-                },
-                "interface_id": {
-                    "type": "string"
-                },
-                "is_enabled": {
-                    "type": "boolean"
-                },
-                "order_num": {
-                    "type": "integer"
-                },
-                "type": {
-                    "type": "string"
-                }
-            }
-        },
-        "models.DataAccessRequest": {
-            "type": "object",
-            "properties": {
-                "access_permission": {
-                    "description": "read/write",
-                    "type": "string"
-                },
-                "approval_comment": {
-                    "type": "string"
-                },
-                "approved_at": {
-                    "type": "string"
-                },
-                "approver_id": {
-                    "type": "string"
-                },
-                "approver_name": {
-                    "type": "string"
-                },
-                "created_by": {
-                    "type": "string"
-                },
-                "id": {
-                    "type": "string"
-                },
-                "request_reason": {
-                    "type": "string"
-                },
-                "requested_at": {
-                    "type": "string"
-                },
-                "requester_id": {
-                    "type": "string"
-                },
-                "requester_name": {
-                    "type": "string"
-                },
-                "resource_id": {
-                    "type": "string"
-                },
-                "resource_type": {
-                    "description": "thematic_library/basic_library/interface",
-                    "type": "string"
-                },
-                "status": {
-                    "description": "pending/approved/rejected/expired",
-                    "type": "string"
-                },
-                "valid_until": {
-                    "type": "string"
-                }
-            }
-        },
-        "models.DataCleansingConfig": {
-            "type": "object",
-            "properties": {
-                "backup_original": {
-                    "description": "是否备份原始数据",
-                    "type": "boolean"
-                },
-                "cleansing_config": {
-                    "description": "运行时清洗配置",
-                    "type": "object",
-                    "additionalProperties": true
-                },
-                "error_handling": {
-                    "description": "错误处理策略",
-                    "type": "object",
-                    "additionalProperties": true
-                },
-                "is_enabled": {
-                    "type": "boolean"
-                },
-                "post_condition": {
-                    "description": "后置条件",
-                    "type": "string"
-                },
-                "pre_condition": {
-                    "description": "前置条件",
-                    "type": "string"
-                },
-                "target_fields": {
-                    "description": "目标字段列表",
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    }
-                },
-                "template_id": {
-                    "type": "string"
-                },
-                "trigger_condition": {
-                    "description": "触发条件",
-                    "type": "string"
-                },
-                "validation_rules": {
-                    "description": "验证规则",
-                    "type": "object",
-                    "additionalProperties": true
-                }
-            }
-        },
-        "models.DataInterface": {
-            "type": "object",
-            "properties": {
-                "basic_library": {
-                    "description": "关联关系",
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/models.BasicLibrary"
-                        }
-                    ]
-                },
-                "clean_rules": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/models.CleansingRule"
-                    }
-                },
-                "created_at": {
-                    "type": "string"
-                },
-                "created_by": {
-                    "type": "string"
-                },
-                "data_source": {
-                    "$ref": "#/definitions/models.DataSource"
-                },
-                "data_source_id": {
-                    "type": "string"
-                },
-                "description": {
-                    "type": "string"
-                },
-                "id": {
-                    "type": "string"
-                },
-                "interface_config": {
-                    "$ref": "#/definitions/models.JSONB"
-                },
-                "is_table_created": {
-                    "type": "boolean"
-                },
-                "library_id": {
-                    "type": "string"
-                },
-                "name_en": {
-                    "type": "string"
-                },
-                "name_zh": {
-                    "type": "string"
-                },
-                "parse_config": {
-                    "$ref": "#/definitions/models.JSONB"
-                },
-                "status": {
-                    "type": "string"
-                },
-                "table_fields_config": {
-                    "$ref": "#/definitions/models.JSONB"
-                },
-                "type": {
-                    "description": "realtime, batch",
-                    "type": "string"
-                },
-                "updated_at": {
-                    "type": "string"
-                },
-                "updated_by": {
-                    "type": "string"
-                }
-            }
-        },
-        "models.DataMaskingConfig": {
-            "type": "object",
-            "properties": {
-                "apply_condition": {
-                    "description": "应用条件",
-                    "type": "string"
-                },
-                "is_enabled": {
-                    "type": "boolean"
-                },
-                "masking_config": {
-                    "description": "运行时脱敏配置",
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/models.JSONB"
-                        }
-                    ]
-                },
-                "preserve_format": {
-                    "description": "是否保持格式",
-                    "type": "boolean"
-                },
-                "reversible_config": {
-                    "description": "可逆配置（如果支持）",
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/models.JSONB"
-                        }
-                    ]
-                },
-                "target_fields": {
-                    "description": "目标字段列表",
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    }
-                },
-                "template_id": {
-                    "type": "string"
-                }
-            }
-        },
-        "models.DataSource": {
-            "type": "object",
-            "properties": {
-                "basic_library": {
-                    "description": "关联关系",
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/models.BasicLibrary"
-                        }
-                    ]
-                },
-                "category": {
-                    "description": "stream, http, db, file",
-                    "type": "string"
-                },
-                "connection_config": {
-                    "$ref": "#/definitions/models.JSONB"
-                },
-                "created_at": {
-                    "type": "string"
-                },
-                "created_by": {
-                    "type": "string"
-                },
-                "id": {
-                    "type": "string"
-                },
-                "library_id": {
-                    "type": "string"
-                },
-                "name": {
-                    "type": "string"
-                },
-                "params_config": {
-                    "$ref": "#/definitions/models.JSONB"
-                },
-                "script": {
-                    "description": "动态执行脚本，用于特殊认证处理",
-                    "type": "string"
-                },
-                "script_enabled": {
-                    "description": "是否启用脚本执行",
-                    "type": "boolean"
-                },
-                "status": {
-                    "description": "active, inactive",
-                    "type": "string"
-                },
-                "type": {
-                    "type": "string"
-                },
-                "updated_at": {
-                    "type": "string"
-                },
-                "updated_by": {
-                    "type": "string"
-                }
-            }
-        },
-        "models.DataSubscription": {
-            "type": "object",
-            "properties": {
-                "created_at": {
-                    "type": "string"
-                },
-                "created_by": {
-                    "type": "string"
-                },
-                "filter_condition": {
-                    "type": "object",
-                    "additionalProperties": true
-                },
-                "id": {
-                    "type": "string"
-                },
-                "notification_config": {
-                    "type": "object",
-                    "additionalProperties": true
-                },
-                "notification_method": {
-                    "description": "webhook/message_queue/email",
-                    "type": "string"
-                },
-                "resource_id": {
-                    "type": "string"
-                },
-                "resource_type": {
-                    "description": "thematic_interface/basic_interface",
-                    "type": "string"
-                },
-                "status": {
-                    "description": "active/paused/terminated",
-                    "type": "string"
-                },
-                "subscriber_id": {
-                    "type": "string"
-                },
-                "subscriber_type": {
-                    "description": "user/application",
-                    "type": "string"
-                },
-                "updated_at": {
-                    "type": "string"
-                },
-                "updated_by": {
-                    "type": "string"
-                }
-            }
-        },
-        "models.JSONB": {
-            "type": "object",
-            "additionalProperties": true
-        },
-        "models.QualityRuleConfig": {
-            "type": "object",
-            "properties": {
-                "is_enabled": {
-                    "type": "boolean"
-                },
-                "rule_template_id": {
-                    "type": "string"
-                },
-                "runtime_config": {
-                    "description": "运行时配置",
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/models.JSONB"
-                        }
-                    ]
-                },
-                "target_fields": {
-                    "description": "目标字段列表",
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    }
-                },
-                "threshold": {
-                    "description": "阈值配置",
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/models.JSONB"
-                        }
-                    ]
-                }
-            }
-        },
-        "models.RateLimitTypeStats": {
-            "type": "object",
-            "properties": {
-                "count": {
-                    "type": "integer"
-                },
-                "rate_limit_type": {
-                    "description": "global/api_key/application",
-                    "type": "string"
-                }
-            }
-        },
-        "models.SSEConnection": {
-            "type": "object",
-            "properties": {
-                "client_ip": {
-                    "type": "string"
-                },
-                "connected_at": {
-                    "type": "string"
-                },
-                "connection_id": {
-                    "type": "string"
-                },
-                "created_at": {
-                    "type": "string"
-                },
-                "created_by": {
-                    "type": "string"
-                },
-                "id": {
-                    "type": "string"
-                },
-                "is_active": {
-                    "type": "boolean"
-                },
-                "last_ping_at": {
-                    "type": "string"
-                },
-                "user_agent": {
-                    "type": "string"
-                },
-                "user_name": {
-                    "type": "string"
-                }
-            }
-        },
-        "models.SSEEvent": {
-            "type": "object",
-            "properties": {
-                "created_at": {
-                    "type": "string"
-                },
-                "created_by": {
-                    "type": "string"
-                },
-                "data": {
-                    "type": "object",
-                    "additionalProperties": true
-                },
-                "event_type": {
-                    "description": "data_change, system_notification, user_message等",
-                    "type": "string"
-                },
-                "id": {
-                    "type": "string"
-                },
-                "read": {
-                    "type": "boolean"
-                },
-                "read_at": {
-                    "type": "string"
-                },
-                "read_by": {
-                    "type": "string"
-                },
-                "sent": {
-                    "type": "boolean"
-                },
-                "sent_at": {
-                    "type": "string"
-                },
-                "sent_by": {
-                    "type": "string"
-                },
-                "user_name": {
-                    "type": "string"
-                }
-            }
-        },
-        "models.StatusDistribution": {
-            "type": "object",
-            "properties": {
-                "count": {
-                    "description": "该状态码的请求数量",
-                    "type": "integer"
-                },
-                "status_code": {
-                    "description": "HTTP状态码",
-                    "type": "integer"
-                }
-            }
-        },
-        "models.SyncInterfaceResult": {
-            "type": "object",
-            "properties": {
-                "details": {
-                    "type": "object",
-                    "additionalProperties": true
-                },
-                "duration": {
-                    "description": "持续时间，单位：毫秒",
-                    "type": "integer"
-                },
-                "end_time": {
-                    "type": "string"
-                },
-                "error_message": {
-                    "type": "string"
-                },
-                "processed_rows": {
-                    "type": "integer"
-                },
-                "start_time": {
-                    "type": "string"
-                },
-                "success": {
-                    "type": "boolean"
-                }
-            }
-        },
-        "models.SyncProgress": {
-            "type": "object",
-            "properties": {
-                "current_phase": {
-                    "type": "string"
-                },
-                "error_count": {
-                    "type": "integer"
-                },
-                "estimated_time": {
-                    "type": "string"
-                },
-                "processed_rows": {
-                    "type": "integer"
-                },
-                "progress_percent": {
-                    "type": "integer"
-                },
-                "speed": {
-                    "description": "每秒处理行数",
-                    "type": "integer"
-                },
-                "total_rows": {
-                    "type": "integer"
-                },
-                "updated_at": {
-                    "type": "string"
-                }
-            }
-        },
-        "models.SyncResult": {
-            "type": "object",
-            "properties": {
-                "details": {
-                    "type": "object",
-                    "additionalProperties": true
-                },
-                "duration_ms": {
-                    "description": "毫秒数，便于JSON序列化",
-                    "type": "integer"
-                },
-                "end_time": {
-                    "type": "string"
-                },
-                "error_count": {
-                    "type": "integer"
-                },
-                "error_message": {
-                    "type": "string"
-                },
-                "error_rows": {
-                    "type": "integer"
-                },
-                "interfaces": {
-                    "description": "各接口的处理结果",
-                    "type": "object",
-                    "additionalProperties": {
-                        "$ref": "#/definitions/models.SyncInterfaceResult"
-                    }
-                },
-                "metadata": {
-                    "type": "object",
-                    "additionalProperties": true
-                },
-                "processed_rows": {
-                    "type": "integer"
-                },
-                "start_time": {
-                    "type": "string"
-                },
-                "statistics": {
-                    "type": "object",
-                    "additionalProperties": true
-                },
-                "status": {
-                    "type": "string"
-                },
-                "success": {
-                    "description": "向后兼容字段",
-                    "type": "boolean"
-                },
-                "success_rows": {
-                    "type": "integer"
-                },
-                "task_id": {
-                    "type": "string"
-                }
-            }
-        },
-        "models.SyncTask": {
-            "type": "object",
-            "properties": {
-                "basic_library": {
-                    "description": "动态关联 - 这些字段不存储在数据库中，在运行时根据LibraryType动态加载",
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/models.BasicLibrary"
-                        }
-                    ]
-                },
-                "config": {
-                    "description": "配置和结果",
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/models.JSONB"
-                        }
-                    ]
-                },
-                "created_at": {
-                    "description": "基础字段",
-                    "type": "string"
-                },
-                "created_by": {
-                    "type": "string",
-                    "example": "system"
-                },
-                "cron_expression": {
-                    "description": "Cron表达式",
-                    "type": "string",
-                    "example": "0 0 * * *"
-                },
-                "data_interfaces": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/models.DataInterface"
-                    }
-                },
-                "data_source": {
-                    "$ref": "#/definitions/models.DataSource"
-                },
-                "data_source_id": {
-                    "type": "string",
-                    "example": "550e8400-e29b-41d4-a716-446655440000"
-                },
-                "end_time": {
-                    "type": "string"
-                },
-                "error_count": {
-                    "type": "integer",
-                    "example": 0
-                },
-                "error_message": {
-                    "type": "string"
-                },
-                "execution_status": {
-                    "description": "idle, running, success, failed (任务执行状态)",
-                    "type": "string",
-                    "example": "idle"
-                },
-                "executions": {
-                    "description": "执行记录关联",
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/models.SyncTaskExecution"
-                    }
-                },
-                "id": {
-                    "type": "string",
-                    "example": "550e8400-e29b-41d4-a716-446655440000"
-                },
-                "interval_seconds": {
-                    "description": "间隔秒数",
-                    "type": "integer",
-                    "example": 3600
-                },
-                "last_run_time": {
-                    "description": "上次执行时间",
-                    "type": "string"
-                },
-                "library_id": {
-                    "description": "基础库ID或主题库ID",
-                    "type": "string",
-                    "example": "550e8400-e29b-41d4-a716-446655440000"
-                },
-                "library_type": {
-                    "description": "basic_library, thematic_library",
-                    "type": "string",
-                    "example": "basic_library"
-                },
-                "next_run_time": {
-                    "description": "下次执行时间",
-                    "type": "string"
-                },
-                "processed_rows": {
-                    "type": "integer",
-                    "example": 0
-                },
-                "progress": {
-                    "description": "进度百分比 0-100",
-                    "type": "integer",
-                    "example": 0
-                },
-                "result": {
-                    "description": "同步结果",
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/models.JSONB"
-                        }
-                    ]
-                },
-                "scheduled_time": {
-                    "description": "计划执行时间",
-                    "type": "string"
-                },
-                "start_time": {
-                    "description": "执行状态相关字段",
-                    "type": "string"
-                },
-                "status": {
-                    "description": "draft, active, paused (任务生命周期状态)",
-                    "type": "string",
-                    "example": "draft"
-                },
-                "task_interfaces": {
-                    "description": "多接口关联",
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/models.SyncTaskInterface"
-                    }
-                },
-                "task_type": {
-                    "description": "batch_sync, realtime_sync",
-                    "type": "string",
-                    "example": "batch_sync"
-                },
-                "thematic_library": {
-                    "$ref": "#/definitions/models.ThematicLibrary"
-                },
-                "total_rows": {
-                    "type": "integer",
-                    "example": 0
-                },
-                "trigger_type": {
-                    "description": "执行时机相关字段",
-                    "type": "string",
-                    "example": "manual"
-                },
-                "updated_at": {
-                    "type": "string"
-                },
-                "updated_by": {
-                    "type": "string",
-                    "example": "system"
-                }
-            }
-        },
-        "models.SyncTaskExecution": {
-            "type": "object",
-            "properties": {
-                "created_at": {
-                    "type": "string"
-                },
-                "duration": {
-                    "description": "执行时长，毫秒",
-                    "type": "integer"
-                },
-                "end_time": {
-                    "type": "string"
-                },
-                "error_count": {
-                    "type": "integer"
-                },
-                "error_message": {
-                    "type": "string"
-                },
-                "execution_type": {
-                    "description": "manual, scheduled, retry",
-                    "type": "string",
-                    "example": "manual"
-                },
-                "id": {
-                    "type": "string"
-                },
-                "processed_rows": {
-                    "type": "integer"
-                },
-                "progress": {
-                    "description": "进度百分比 0-100",
-                    "type": "integer"
-                },
-                "result": {
-                    "description": "执行结果详情",
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/models.JSONB"
-                        }
-                    ]
-                },
-                "start_time": {
-                    "type": "string"
-                },
-                "status": {
-                    "description": "running, success, failed, cancelled",
-                    "type": "string",
-                    "example": "running"
-                },
-                "task": {
-                    "description": "关联关系",
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/models.SyncTask"
-                        }
-                    ]
-                },
-                "task_id": {
-                    "type": "string"
-                },
-                "total_rows": {
-                    "type": "integer"
-                },
-                "updated_at": {
-                    "type": "string"
-                }
-            }
-        },
-        "models.SyncTaskInterface": {
-            "type": "object",
-            "properties": {
-                "config": {
-                    "description": "接口级别的配置",
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/models.JSONB"
-                        }
-                    ]
-                },
-                "created_at": {
-                    "type": "string"
-                },
-                "data_interface": {
-                    "$ref": "#/definitions/models.DataInterface"
-                },
-                "end_time": {
-                    "type": "string"
-                },
-                "error_count": {
-                    "type": "integer",
-                    "example": 0
-                },
-                "error_message": {
-                    "type": "string"
-                },
-                "id": {
-                    "type": "string"
-                },
-                "interface_id": {
-                    "type": "string",
-                    "example": "550e8400-e29b-41d4-a716-446655440000"
-                },
-                "processed_rows": {
-                    "type": "integer",
-                    "example": 0
-                },
-                "progress": {
-                    "description": "进度百分比 0-100",
-                    "type": "integer",
-                    "example": 0
-                },
-                "result": {
-                    "description": "接口级别的结果",
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/models.JSONB"
-                        }
-                    ]
-                },
-                "start_time": {
-                    "type": "string"
-                },
-                "status": {
-                    "description": "pending, running, success, failed, cancelled",
-                    "type": "string",
-                    "example": "pending"
-                },
-                "sync_task": {
-                    "description": "关联关系",
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/models.SyncTask"
-                        }
-                    ]
-                },
-                "task_id": {
-                    "type": "string",
-                    "example": "550e8400-e29b-41d4-a716-446655440000"
-                },
-                "total_rows": {
-                    "type": "integer",
-                    "example": 0
-                },
-                "updated_at": {
-                    "type": "string"
-                }
-            }
-        },
-        "models.TableField": {
-            "type": "object",
-            "properties": {
-                "check_constraint": {
-                    "type": "string"
-                },
-                "data_type": {
-                    "type": "string"
-                },
-                "default_value": {
-                    "type": "string"
-                },
-                "description": {
-                    "type": "string"
-                },
-                "is_increment_field": {
-                    "description": "是否为增量字段，增量更新时根据这个字段判断条件",
-                    "type": "boolean"
-                },
-                "is_nullable": {
-                    "type": "boolean"
-                },
-                "is_primary_key": {
-                    "type": "boolean"
-                },
-                "is_unique": {
-                    "type": "boolean"
-                },
-                "name_en": {
-                    "type": "string"
-                },
-                "name_zh": {
-                    "type": "string"
-                },
-                "order_num": {
-                    "type": "integer"
-                }
-            }
-        },
-        "models.TableSchemaRequest": {
-            "type": "object",
-            "required": [
-                "interface_id",
-                "operation",
-                "schema_name",
-                "table_name"
-            ],
-            "properties": {
-                "fields": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/models.TableField"
-                    }
-                },
-                "interface_id": {
-                    "type": "string",
-                    "example": "550e8400-e29b-41d4-a716-446655440000"
-                },
-                "operation": {
-                    "description": "create_table, alter_table, drop_table",
-                    "type": "string",
-                    "example": "create_table"
-                },
-                "schema_name": {
-                    "type": "string",
-                    "example": "user_basic_library"
-                },
-                "table_name": {
-                    "type": "string",
-                    "example": "users"
-                }
-            }
-        },
-        "models.ThematicInterface": {
-            "type": "object",
-            "properties": {
-                "created_at": {
-                    "type": "string"
-                },
-                "created_by": {
-                    "type": "string"
-                },
-                "description": {
-                    "type": "string"
-                },
-                "id": {
-                    "type": "string"
-                },
-                "interface_config": {
-                    "$ref": "#/definitions/models.JSONB"
-                },
-                "is_table_created": {
-                    "type": "boolean"
-                },
-                "is_view_created": {
-                    "type": "boolean"
-                },
-                "library_id": {
-                    "type": "string"
-                },
-                "name_en": {
-                    "type": "string"
-                },
-                "name_zh": {
-                    "type": "string"
-                },
-                "parse_config": {
-                    "$ref": "#/definitions/models.JSONB"
-                },
-                "status": {
-                    "type": "string"
-                },
-                "table_fields_config": {
-                    "$ref": "#/definitions/models.JSONB"
-                },
-                "thematic_library": {
-                    "description": "关联关系",
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/models.ThematicLibrary"
-                        }
-                    ]
-                },
-                "type": {
-                    "description": "table, view",
-                    "type": "string"
-                },
-                "updated_at": {
-                    "type": "string"
-                },
-                "updated_by": {
-                    "type": "string"
-                },
-                "view_config": {
-                    "$ref": "#/definitions/models.JSONB"
-                },
-                "view_sql": {
-                    "type": "string"
-                }
-            }
-        },
-        "models.ThematicLibrary": {
-            "type": "object",
-            "properties": {
-                "access_level": {
-                    "description": "public, internal, private",
-                    "type": "string"
-                },
-                "authorized_roles": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/models.JSONB"
-                    }
-                },
-                "authorized_users": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/models.JSONB"
-                    }
-                },
-                "category": {
-                    "description": "business, technical, analysis, report",
-                    "type": "string"
-                },
-                "created_at": {
-                    "type": "string"
-                },
-                "created_by": {
-                    "type": "string"
-                },
-                "description": {
-                    "type": "string"
-                },
-                "domain": {
-                    "description": "user, order, product, finance, marketing",
-                    "type": "string"
-                },
-                "id": {
-                    "type": "string"
-                },
-                "interfaces": {
-                    "description": "关联关系",
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/models.ThematicInterface"
-                    }
-                },
-                "name_en": {
-                    "type": "string"
-                },
-                "name_zh": {
-                    "type": "string"
-                },
-                "publish_status": {
-                    "description": "draft, published, archived",
-                    "type": "string"
-                },
-                "retention_period": {
-                    "type": "integer"
-                },
-                "source_libraries": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/models.JSONB"
-                    }
-                },
-                "status": {
-                    "type": "string"
-                },
-                "tags": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/models.JSONB"
-                    }
-                },
-                "update_frequency": {
-                    "description": "realtime, hourly, daily, weekly, monthly",
-                    "type": "string"
-                },
-                "updated_at": {
-                    "type": "string"
-                },
-                "updated_by": {
-                    "type": "string"
-                },
-                "version": {
-                    "type": "string"
-                }
-            }
-        },
-        "models.TopApplication": {
-            "type": "object",
-            "properties": {
-                "app_name": {
-                    "description": "应用名称",
-                    "type": "string"
-                },
-                "application_id": {
-                    "description": "应用ID",
-                    "type": "string"
-                },
-                "count": {
-                    "description": "请求次数",
-                    "type": "integer"
-                }
-            }
-        },
-        "thematic_library.ConditionalRule": {
-            "type": "object",
-            "required": [
-                "condition"
-            ],
-            "properties": {
-                "condition": {
-                    "type": "string"
-                },
-                "transform": {
-                    "type": "string"
-                },
-                "value": {}
-            }
-        },
-        "thematic_library.ConversionRule": {
-            "type": "object",
-            "required": [
-                "from_type",
-                "rule",
-                "to_type"
-            ],
-            "properties": {
-                "from_type": {
-                    "type": "string"
-                },
-                "rule": {
-                    "type": "string"
-                },
-                "to_type": {
-                    "type": "string"
-                }
-            }
-        },
-        "thematic_library.CreateThematicSyncTaskRequest": {
-            "type": "object",
-            "required": [
-                "created_by",
-                "schedule_config",
-                "task_name",
-                "thematic_interface_id",
-                "thematic_library_id"
-            ],
-            "properties": {
-                "cleansing_rule_configs": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/models.DataCleansingConfig"
-                    }
-                },
-                "created_by": {
-                    "type": "string"
-                },
-                "description": {
-                    "type": "string"
-                },
-                "field_mapping_rules": {
-                    "$ref": "#/definitions/thematic_library.FieldMappingRules"
-                },
-                "governance_config": {
-                    "$ref": "#/definitions/thematic_library.GovernanceExecutionConfig"
-                },
-                "key_matching_rules": {
-                    "description": "规则配置",
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/thematic_library.KeyMatchingRules"
-                        }
-                    ]
-                },
-                "masking_rule_configs": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/models.DataMaskingConfig"
-                    }
-                },
-                "quality_rule_configs": {
-                    "description": "数据治理规则配置",
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/models.QualityRuleConfig"
-                    }
-                },
-                "schedule_config": {
-                    "$ref": "#/definitions/thematic_library.ScheduleConfig"
-                },
-                "source_libraries": {
-                    "description": "数据源配置 - 两种模式二选一\n模式1: 接口模式 - 从基础库的数据接口获取数据",
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/thematic_library.SourceLibraryConfig"
-                    }
-                },
-                "sql_queries": {
-                    "description": "模式2: SQL模式 - 直接执行SQL查询获取数据（优先级更高）",
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/thematic_library.SQLQueryConfig"
-                    }
-                },
-                "task_name": {
-                    "type": "string"
-                },
-                "thematic_interface_id": {
-                    "type": "string"
-                },
-                "thematic_library_id": {
-                    "type": "string"
-                }
-            }
-        },
-        "thematic_library.DataFilterRule": {
-            "type": "object",
-            "required": [
-                "field",
-                "operator",
-                "value"
-            ],
-            "properties": {
-                "field": {
-                    "type": "string"
-                },
-                "logic_op": {
-                    "type": "string",
-                    "default": "AND",
-                    "enum": [
-                        "AND",
-                        "OR"
-                    ]
-                },
-                "operator": {
-                    "type": "string",
-                    "enum": [
-                        "eq",
-                        "ne",
-                        "gt",
-                        "lt",
-                        "ge",
-                        "le",
-                        "in",
-                        "nin",
-                        "like"
-                    ]
-                },
-                "value": {}
-            }
-        },
-        "thematic_library.ExecutionDataRange": {
-            "type": "object",
-            "properties": {
-                "conditions": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    }
-                },
-                "date_field": {
-                    "type": "string"
-                },
-                "end_time": {
-                    "type": "string"
-                },
-                "partition_key": {
-                    "type": "string"
-                },
-                "start_time": {
-                    "type": "string"
-                }
-            }
-        },
-        "thematic_library.ExecutionFilter": {
-            "type": "object",
-            "required": [
-                "field",
-                "operator",
-                "value"
-            ],
-            "properties": {
-                "field": {
-                    "type": "string"
-                },
-                "logic_op": {
-                    "type": "string",
-                    "default": "AND",
-                    "enum": [
-                        "AND",
-                        "OR"
-                    ]
-                },
-                "operator": {
-                    "type": "string",
-                    "enum": [
-                        "eq",
-                        "ne",
-                        "gt",
-                        "lt",
-                        "ge",
-                        "le",
-                        "in",
-                        "nin",
-                        "like"
-                    ]
-                },
-                "value": {}
-            }
-        },
-        "thematic_library.ExecutionOutput": {
-            "type": "object",
-            "properties": {
-                "detailed_log": {
-                    "type": "boolean",
-                    "default": false
-                },
-                "exclude_fields": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    }
-                },
-                "export_format": {
-                    "type": "string",
-                    "default": "json"
-                },
-                "export_path": {
-                    "type": "string"
-                },
-                "export_results": {
-                    "type": "boolean",
-                    "default": false
-                },
-                "include_fields": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    }
-                },
-                "log_level": {
-                    "type": "string",
-                    "default": "INFO"
-                },
-                "progress_report": {
-                    "type": "boolean",
-                    "default": true
-                },
-                "report_interval": {
-                    "type": "integer",
-                    "default": 1000
-                },
-                "save_metrics": {
-                    "type": "boolean",
-                    "default": true
-                }
-            }
-        },
-        "thematic_library.ExecutionWindow": {
-            "type": "object",
-            "properties": {
-                "days": {
-                    "type": "array",
-                    "items": {
-                        "type": "integer"
-                    }
-                },
-                "end_time": {
-                    "type": "string",
-                    "example": "18:00"
-                },
-                "holidays": {
-                    "type": "boolean",
-                    "default": false
-                },
-                "start_time": {
-                    "type": "string",
-                    "example": "09:00"
-                }
-            }
-        },
-        "thematic_library.FieldMapping": {
-            "type": "object",
-            "required": [
-                "source_field",
-                "target_field"
-            ],
-            "properties": {
-                "default_value": {},
-                "required": {
-                    "type": "boolean",
-                    "default": false
-                },
-                "source_field": {
-                    "type": "string"
-                },
-                "target_field": {
-                    "type": "string"
-                },
-                "transform": {
-                    "type": "string"
-                }
-            }
-        },
-        "thematic_library.FieldMappingRule": {
-            "type": "object",
-            "required": [
-                "source_field",
-                "target_field"
-            ],
-            "properties": {
-                "data_type": {
-                    "type": "string"
-                },
-                "default_value": {},
-                "nullable": {
-                    "type": "boolean",
-                    "default": true
-                },
-                "required": {
-                    "type": "boolean",
-                    "default": false
-                },
-                "source_field": {
-                    "type": "string"
-                },
-                "target_field": {
-                    "type": "string"
-                },
-                "transform": {
-                    "$ref": "#/definitions/thematic_library.FieldTransform"
-                },
-                "validation": {
-                    "$ref": "#/definitions/thematic_library.FieldValidationRule"
-                }
-            }
-        },
-        "thematic_library.FieldMappingRules": {
-            "type": "object",
-            "required": [
-                "mappings"
-            ],
-            "properties": {
-                "case_sensitive": {
-                    "type": "boolean",
-                    "default": true
-                },
-                "default_policy": {
-                    "type": "string",
-                    "default": "ignore"
-                },
-                "mappings": {
-                    "type": "array",
-                    "minItems": 1,
-                    "items": {
-                        "$ref": "#/definitions/thematic_library.FieldMappingRule"
-                    }
-                },
-                "type_conversion": {
-                    "$ref": "#/definitions/thematic_library.TypeConversionConfig"
-                },
-                "validation": {
-                    "$ref": "#/definitions/thematic_library.MappingValidation"
-                }
-            }
-        },
-        "thematic_library.FieldTransform": {
-            "type": "object",
-            "required": [
-                "type"
-            ],
-            "properties": {
-                "conditional": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/thematic_library.ConditionalRule"
-                    }
-                },
-                "expression": {
-                    "type": "string"
-                },
-                "parameters": {
-                    "type": "object",
-                    "additionalProperties": {
-                        "type": "string"
-                    }
-                },
-                "type": {
-                    "type": "string"
-                }
-            }
-        },
-        "thematic_library.FieldValidationRule": {
-            "type": "object",
-            "properties": {
-                "allowed_values": {
-                    "type": "array",
-                    "items": {}
-                },
-                "custom_rules": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    }
-                },
-                "max_length": {
-                    "type": "integer"
-                },
-                "max_value": {},
-                "min_length": {
-                    "type": "integer"
-                },
-                "min_value": {},
-                "pattern": {
-                    "type": "string"
-                }
-            }
-        },
-        "thematic_library.FuzzyMatchConfig": {
-            "type": "object",
-            "properties": {
-                "algorithm": {
-                    "type": "string",
-                    "default": "levenshtein"
-                },
-                "case_ignore": {
-                    "type": "boolean",
-                    "default": true
-                },
-                "enabled": {
-                    "type": "boolean",
-                    "default": false
-                },
-                "fields": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    }
-                },
-                "preprocessing": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/thematic_library.PreprocessRule"
-                    }
-                },
-                "threshold": {
-                    "type": "number",
-                    "default": 0.8
-                }
-            }
-        },
-        "thematic_library.GovernanceExecutionConfig": {
-            "type": "object",
-            "properties": {
-                "batch_size": {
-                    "type": "integer"
-                },
-                "custom_config": {
-                    "type": "object",
-                    "additionalProperties": true
-                },
-                "enable_cleansing": {
-                    "type": "boolean"
-                },
-                "enable_masking": {
-                    "type": "boolean"
-                },
-                "enable_quality_check": {
-                    "type": "boolean"
-                },
-                "max_retries": {
-                    "type": "integer"
-                },
-                "quality_threshold": {
-                    "type": "number"
-                },
-                "stop_on_quality_failure": {
-                    "type": "boolean"
-                },
-                "timeout_seconds": {
-                    "type": "integer"
-                }
-            }
-        },
-        "thematic_library.IncrementalConfig": {
-            "type": "object",
-            "properties": {
-                "batch_size": {
-                    "description": "增量同步批次大小",
-                    "type": "integer",
-                    "default": 1000
-                },
-                "check_deleted_field": {
-                    "description": "软删除字段名称",
-                    "type": "string"
-                },
-                "compare_operator": {
-                    "description": "比较操作符",
-                    "type": "string",
-                    "default": "\u003e"
-                },
-                "deleted_value": {
-                    "description": "删除标记值",
-                    "type": "string"
-                },
-                "enabled": {
-                    "description": "是否启用增量同步",
-                    "type": "boolean"
-                },
-                "field_type": {
-                    "description": "字段类型：timestamp, number, string",
-                    "type": "string"
-                },
-                "incremental_field": {
-                    "description": "增量字段名称",
-                    "type": "string"
-                },
-                "initial_value": {
-                    "description": "初始值",
-                    "type": "string"
-                },
-                "last_sync_value": {
-                    "description": "上次同步的值",
-                    "type": "string"
-                },
-                "max_lookback_hours": {
-                    "description": "最大回溯小时数",
-                    "type": "integer"
-                },
-                "sync_deleted_records": {
-                    "description": "是否同步已删除的记录",
-                    "type": "boolean"
-                },
-                "timestamp_format": {
-                    "description": "时间戳格式",
-                    "type": "string"
-                },
-                "timezone": {
-                    "description": "时区",
-                    "type": "string",
-                    "default": "Asia/Shanghai"
-                }
-            }
-        },
-        "thematic_library.KeyMatchingRules": {
-            "type": "object",
-            "required": [
-                "conflict_policy",
-                "primary_keys"
-            ],
-            "properties": {
-                "case_sensitive": {
-                    "type": "boolean",
-                    "default": true
-                },
-                "conflict_policy": {
-                    "type": "string",
-                    "enum": [
-                        "first",
-                        "last",
-                        "merge",
-                        "error"
-                    ]
-                },
-                "fuzzy_matching": {
-                    "$ref": "#/definitions/thematic_library.FuzzyMatchConfig"
-                },
-                "match_threshold": {
-                    "type": "number",
-                    "default": 0.8
-                },
-                "primary_keys": {
-                    "type": "array",
-                    "minItems": 1,
-                    "items": {
-                        "$ref": "#/definitions/thematic_library.PrimaryKeyRule"
-                    }
-                }
-            }
-        },
-        "thematic_library.KeyTransform": {
-            "type": "object",
-            "required": [
-                "type"
-            ],
-            "properties": {
-                "parameters": {
-                    "type": "object",
-                    "additionalProperties": {
-                        "type": "string"
-                    }
-                },
-                "type": {
-                    "type": "string",
-                    "enum": [
-                        "trim",
-                        "upper",
-                        "lower",
-                        "hash",
-                        "normalize"
-                    ]
-                }
-            }
-        },
-        "thematic_library.KeyValidation": {
-            "type": "object",
-            "properties": {
-                "allow_empty": {
-                    "type": "boolean",
-                    "default": false
-                },
-                "max_length": {
-                    "type": "integer"
-                },
-                "min_length": {
-                    "type": "integer"
-                },
-                "pattern": {
-                    "type": "string"
-                },
-                "required": {
-                    "type": "boolean",
-                    "default": true
-                }
-            }
-        },
-        "thematic_library.MappingValidation": {
-            "type": "object",
-            "properties": {
-                "allow_unmapped": {
-                    "type": "boolean",
-                    "default": true
-                },
-                "check_duplicates": {
-                    "type": "boolean",
-                    "default": true
-                },
-                "required_fields": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    }
-                },
-                "validate_types": {
-                    "type": "boolean",
-                    "default": true
-                }
-            }
-        },
-        "thematic_library.PreprocessRule": {
-            "type": "object",
-            "required": [
-                "type"
-            ],
-            "properties": {
-                "parameters": {
-                    "type": "string"
-                },
-                "type": {
-                    "type": "string",
-                    "enum": [
-                        "trim",
-                        "normalize",
-                        "remove_special"
-                    ]
-                }
-            }
-        },
-        "thematic_library.PrimaryKeyRule": {
-            "type": "object",
-            "required": [
-                "fields"
-            ],
-            "properties": {
-                "fields": {
-                    "type": "array",
-                    "minItems": 1,
-                    "items": {
-                        "type": "string"
-                    }
-                },
-                "transform": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/thematic_library.KeyTransform"
-                    }
-                },
-                "validation": {
-                    "$ref": "#/definitions/thematic_library.KeyValidation"
-                },
-                "weight": {
-                    "type": "number",
-                    "default": 1
-                }
-            }
-        },
-        "thematic_library.SQLQueryConfig": {
-            "type": "object",
-            "required": [
-                "sql_query"
-            ],
-            "properties": {
-                "max_rows": {
-                    "description": "最大返回行数",
-                    "type": "integer",
-                    "default": 10000
-                },
-                "parameters": {
-                    "description": "查询参数(支持参数化查询)",
-                    "type": "object",
-                    "additionalProperties": true
-                },
-                "sql_query": {
-                    "description": "SQL查询语句",
-                    "type": "string"
-                },
-                "timeout": {
-                    "description": "查询超时时间（秒）",
-                    "type": "integer",
-                    "default": 30
-                }
-            }
-        },
-        "thematic_library.ScheduleConfig": {
-            "type": "object",
-            "required": [
-                "type"
-            ],
-            "properties": {
-                "cron_expression": {
-                    "type": "string"
-                },
-                "enabled": {
-                    "type": "boolean",
-                    "default": true
-                },
-                "end_date": {
-                    "type": "string"
-                },
-                "execution_window": {
-                    "$ref": "#/definitions/thematic_library.ExecutionWindow"
-                },
-                "interval_seconds": {
-                    "type": "integer"
-                },
-                "max_retries": {
-                    "type": "integer",
-                    "default": 3
-                },
-                "retry_interval": {
-                    "type": "integer",
-                    "default": 300
-                },
-                "scheduled_time": {
-                    "type": "string"
-                },
-                "start_date": {
-                    "type": "string"
-                },
-                "timezone": {
-                    "type": "string",
-                    "default": "Asia/Shanghai"
-                },
-                "type": {
-                    "type": "string",
-                    "enum": [
-                        "manual",
-                        "one_time",
-                        "interval",
-                        "cron"
-                    ]
-                }
-            }
-        },
-        "thematic_library.SortField": {
-            "type": "object",
-            "required": [
-                "field"
-            ],
-            "properties": {
-                "field": {
-                    "type": "string"
-                },
-                "order": {
-                    "type": "string",
-                    "default": "ASC",
-                    "enum": [
-                        "ASC",
-                        "DESC"
-                    ]
-                }
-            }
-        },
-        "thematic_library.SourceInterfaceConfig": {
-            "type": "object",
-            "required": [
-                "interface_id"
-            ],
-            "properties": {
-                "batch_size": {
-                    "type": "integer",
-                    "default": 1000
-                },
-                "field_mapping": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/thematic_library.FieldMapping"
-                    }
-                },
-                "filter_condition": {
-                    "type": "string"
-                },
-                "incremental_config": {
-                    "$ref": "#/definitions/thematic_library.IncrementalConfig"
-                },
-                "interface_id": {
-                    "type": "string"
-                },
-                "parameters": {
-                    "type": "object",
-                    "additionalProperties": {
-                        "type": "string"
-                    }
-                },
-                "sort_order": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/thematic_library.SortField"
-                    }
-                }
-            }
-        },
-        "thematic_library.SourceLibraryConfig": {
-            "type": "object",
-            "required": [
-                "interfaces",
-                "library_id"
-            ],
-            "properties": {
-                "enabled": {
-                    "type": "boolean",
-                    "default": true
-                },
-                "filter_rules": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/thematic_library.DataFilterRule"
-                    }
-                },
-                "interfaces": {
-                    "type": "array",
-                    "minItems": 1,
-                    "items": {
-                        "$ref": "#/definitions/thematic_library.SourceInterfaceConfig"
-                    }
-                },
-                "library_id": {
-                    "type": "string"
-                },
-                "priority": {
-                    "type": "integer",
-                    "default": 1
-                },
-                "sync_mode": {
-                    "description": "full, incremental, realtime",
-                    "type": "string",
-                    "default": "full"
-                }
-            }
-        },
-        "thematic_library.SyncExecutionOptions": {
-            "type": "object",
-            "required": [
-                "mode"
-            ],
-            "properties": {
-                "batch_size": {
-                    "type": "integer",
-                    "default": 1000
-                },
-                "custom_parameters": {
-                    "type": "object",
-                    "additionalProperties": {
-                        "type": "string"
-                    }
-                },
-                "data_range": {
-                    "$ref": "#/definitions/thematic_library.ExecutionDataRange"
-                },
-                "debug_mode": {
-                    "type": "boolean",
-                    "default": false
-                },
-                "dry_run": {
-                    "type": "boolean",
-                    "default": false
-                },
-                "filter_conditions": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/thematic_library.ExecutionFilter"
-                    }
-                },
-                "force_refresh": {
-                    "type": "boolean",
-                    "default": false
-                },
-                "ignore_errors": {
-                    "type": "boolean",
-                    "default": false
-                },
-                "max_records": {
-                    "type": "integer"
-                },
-                "mode": {
-                    "type": "string",
-                    "enum": [
-                        "full",
-                        "incremental",
-                        "realtime",
-                        "test"
-                    ]
-                },
-                "output_settings": {
-                    "$ref": "#/definitions/thematic_library.ExecutionOutput"
-                },
-                "parallel_workers": {
-                    "type": "integer",
-                    "default": 1
-                },
-                "skip_validation": {
-                    "type": "boolean",
-                    "default": false
-                },
-                "timeout": {
-                    "type": "integer",
-                    "default": 3600
-                }
-            }
-        },
-        "thematic_library.TypeConversionConfig": {
-            "type": "object",
-            "properties": {
-                "auto_convert": {
-                    "type": "boolean",
-                    "default": true
-                },
-                "boolean_map": {
-                    "type": "object",
-                    "additionalProperties": {
-                        "type": "boolean"
-                    }
-                },
-                "custom_rules": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/thematic_library.ConversionRule"
-                    }
-                },
-                "date_format": {
-                    "type": "string",
-                    "default": "2006-01-02 15:04:05"
-                },
-                "number_format": {
-                    "type": "string"
-                },
-                "strict_mode": {
-                    "type": "boolean",
-                    "default": false
-                }
-            }
-        },
-        "thematic_library.UpdateThematicSyncTaskRequest": {
-            "type": "object",
-            "required": [
-                "updated_by"
-            ],
-            "properties": {
-                "cleansing_rule_configs": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/models.DataCleansingConfig"
-                    }
-                },
-                "description": {
-                    "type": "string"
-                },
-                "field_mapping_rules": {
-                    "$ref": "#/definitions/thematic_library.FieldMappingRules"
-                },
-                "governance_config": {
-                    "$ref": "#/definitions/thematic_library.GovernanceExecutionConfig"
-                },
-                "key_matching_rules": {
-                    "$ref": "#/definitions/thematic_library.KeyMatchingRules"
-                },
-                "masking_rule_configs": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/models.DataMaskingConfig"
-                    }
-                },
-                "quality_rule_configs": {
-                    "description": "数据治理规则配置",
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/models.QualityRuleConfig"
-                    }
-                },
-                "schedule_config": {
-                    "description": "调度和规则配置",
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/thematic_library.ScheduleConfig"
-                        }
-                    ]
-                },
-                "source_libraries": {
-                    "description": "数据源配置 - 两种模式二选一\n模式1: 接口模式 - 从基础库的数据接口获取数据",
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/thematic_library.SourceLibraryConfig"
-                    }
-                },
-                "sql_queries": {
-                    "description": "模式2: SQL模式 - 直接执行SQL查询获取数据（优先级更高）",
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/thematic_library.SQLQueryConfig"
-                    }
-                },
-                "status": {
-                    "type": "string"
-                },
-                "task_name": {
-                    "type": "string"
-                },
-                "updated_by": {
-                    "type": "string"
-                }
-            }
-        }
-    }
-}`
-
-// SwaggerInfo holds exported Swagger Info so clients can modify it
-var SwaggerInfo = &swag.Spec{
-	Version:          "1.0",
-	Host:             "",
-	BasePath:         "/swagger/datahub-service",
-	Schemes:          []string{},
-	Title:            "数据底座服务 API",
-	Description:      "智慧园区数据底座后台服务，提供数据采集、处理、存储、治理和共享功能",
-	InfoInstanceName: "swagger",
-	SwaggerTemplate:  docTemplate,
-	LeftDelim:        "{{",
-	RightDelim:       "}}",
-}
-
-func init() {
-	swag.Register(SwaggerInfo.InstanceName(), SwaggerInfo)
-}
@@ -0,0 +1,523 @@
+// Package docs Code generated by swaggo/swag. DO NOT EDIT
+package docs
+
+import "github.com/swaggo/swag"
+
+const docTemplatesharing = `{
+    "schemes": [[ marshal .Schemes ]],
+    "swagger": "2.0",
+    "info": {
+        "description": "[[escape .Description]]",
+        "title": "[[.Title]]",
+        "contact": {},
+        "version": "[[.Version]]"
+    },
+    "host": "[[.Host]]",
+    "basePath": "[[.BasePath]]",
+    "paths": {
+        "/api/v1/share/": {
+            "get": {
+                "description": "根据API Key获取该Key所属的API应用详细信息以及该应用下的所有接口信息，包括主题接口的字段定义",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "数据访问"
+                ],
+                "summary": "通过API Key获取API应用信息和接口列表",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer Token格式的API Key",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "获取成功",
+                        "schema": {}
+                    },
+                    "401": {
+                        "description": "未授权",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.APIResponse-any"
+                        }
+                    },
+                    "404": {
+                        "description": "应用不存在",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.APIResponse-any"
+                        }
+                    },
+                    "500": {
+                        "description": "服务器内部错误",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.APIResponse-any"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/share/changelog/{interface_id}": {
+            "get": {
+                "description": "按游标拉取共享接口对应主题接口的变更日志（新增、修改、删除的主键和修改的列，不含数据值），下游按主键补读变化的行即可，不必重新读取整张表。since为上次返回的next_since，也可以是RFC3339时间；为空时从保留的第一条开始。resync_required为true表示since之后有变更已超出保留期被清理，需要重新读取全量数据。主题接口须由管理员开启变更日志；接口配置了匹配该API Key的行级安全策略时不能拉取变更日志；主键字段按接口的脱敏规则脱敏",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "数据访问"
+                ],
+                "summary": "拉取共享接口的变更日志",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "共享接口ID",
+                        "name": "interface_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "起始游标（不含），变更序号或RFC3339时间",
+                        "name": "since",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "每页条数，默认1000，最多10000",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Bearer Token格式的API Key",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "获取成功",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.APIResponse-sharing_ChangelogPage"
+                        }
+                    },
+                    "400": {
+                        "description": "since不合法",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.APIResponse-any"
+                        }
+                    },
+                    "401": {
+                        "description": "未授权",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.APIResponse-any"
+                        }
+                    },
+                    "403": {
+                        "description": "接口配置了行级安全策略",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.APIResponse-any"
+                        }
+                    },
+                    "404": {
+                        "description": "接口不存在或未开启变更日志",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.APIResponse-any"
+                        }
+                    },
+                    "429": {
+                        "description": "请求过于频繁",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.APIResponse-any"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/share/{app_path}": {
+            "get": {
+                "description": "根据应用路径获取API应用的详细信息以及该应用下的所有接口信息，包括主题接口的字段定义",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "数据访问"
+                ],
+                "summary": "获取API应用信息和接口列表",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "应用路径",
+                        "name": "app_path",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Bearer Token格式的API Key",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "获取成功",
+                        "schema": {}
+                    },
+                    "401": {
+                        "description": "未授权",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.APIResponse-any"
+                        }
+                    },
+                    "404": {
+                        "description": "应用不存在",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.APIResponse-any"
+                        }
+                    },
+                    "500": {
+                        "description": "服务器内部错误",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.APIResponse-any"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/share/{app_path}/{interface_path}": {
+            "get": {
+                "description": "代理对PostgREST的查询请求，实现统一的鉴权、日志、限流和路由功能，仅支持数据查询操作。带bbox或near参数时按空间范围直接查询接口表，只能与geo_field、select、limit、offset参数组合，空间字段以GeoJSON返回，Content-Range给出本页范围和总数。请求头Accept为text/csv或application/x-ndjson时逐行返回CSV（首行为列名，顺序与查询返回的字段一致）或NDJSON，不需要在内存中组装整个JSON数组，适合拉取大结果集；脱敏规则同样逐行应用。接口或其主题接口已弃用时响应带Deprecation头（弃用时间）、Sunset头（下线时间）和指向替代接口的Link头，到下线时间后返回410",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "text/csv",
+                    "application/x-ndjson"
+                ],
+                "tags": [
+                    "数据访问"
+                ],
+                "summary": "数据访问代理（只读查询）",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "应用路径",
+                        "name": "app_path",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "接口路径",
+                        "name": "interface_path",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "example": "\"120.1,30.2,120.3,30.4\"",
+                        "description": "与矩形范围相交：最小经度,最小纬度,最大经度,最大纬度",
+                        "name": "bbox",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "\"120.2,30.3,500\"",
+                        "description": "在圆形范围内：经度,纬度,半径(米)",
+                        "name": "near",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "空间筛选字段，接口只有一个空间字段时可不填",
+                        "name": "geo_field",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Bearer Token格式的API Key",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "查询成功",
+                        "schema": {}
+                    },
+                    "401": {
+                        "description": "未授权",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.APIResponse-any"
+                        }
+                    },
+                    "404": {
+                        "description": "资源不存在",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.APIResponse-any"
+                        }
+                    },
+                    "410": {
+                        "description": "接口已到下线时间",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.APIResponse-any"
+                        }
+                    },
+                    "429": {
+                        "description": "请求过于频繁",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.APIResponse-any"
+                        }
+                    },
+                    "500": {
+                        "description": "服务器内部错误",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.APIResponse-any"
+                        }
+                    }
+                }
+            },
+            "head": {
+                "description": "代理对PostgREST的查询请求，实现统一的鉴权、日志、限流和路由功能，仅支持数据查询操作。带bbox或near参数时按空间范围直接查询接口表，只能与geo_field、select、limit、offset参数组合，空间字段以GeoJSON返回，Content-Range给出本页范围和总数。请求头Accept为text/csv或application/x-ndjson时逐行返回CSV（首行为列名，顺序与查询返回的字段一致）或NDJSON，不需要在内存中组装整个JSON数组，适合拉取大结果集；脱敏规则同样逐行应用。接口或其主题接口已弃用时响应带Deprecation头（弃用时间）、Sunset头（下线时间）和指向替代接口的Link头，到下线时间后返回410",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "text/csv",
+                    "application/x-ndjson"
+                ],
+                "tags": [
+                    "数据访问"
+                ],
+                "summary": "数据访问代理（只读查询）",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "应用路径",
+                        "name": "app_path",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "接口路径",
+                        "name": "interface_path",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "example": "\"120.1,30.2,120.3,30.4\"",
+                        "description": "与矩形范围相交：最小经度,最小纬度,最大经度,最大纬度",
+                        "name": "bbox",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "\"120.2,30.3,500\"",
+                        "description": "在圆形范围内：经度,纬度,半径(米)",
+                        "name": "near",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "空间筛选字段，接口只有一个空间字段时可不填",
+                        "name": "geo_field",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Bearer Token格式的API Key",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "查询成功",
+                        "schema": {}
+                    },
+                    "401": {
+                        "description": "未授权",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.APIResponse-any"
+                        }
+                    },
+                    "404": {
+                        "description": "资源不存在",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.APIResponse-any"
+                        }
+                    },
+                    "410": {
+                        "description": "接口已到下线时间",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.APIResponse-any"
+                        }
+                    },
+                    "429": {
+                        "description": "请求过于频繁",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.APIResponse-any"
+                        }
+                    },
+                    "500": {
+                        "description": "服务器内部错误",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.APIResponse-any"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "controllers.APIResponse-any": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "错误码，成功时为空，取值见ErrorCodeCatalog",
+                    "type": "string",
+                    "example": "VALIDATION_FAILED"
+                },
+                "data": {},
+                "msg": {
+                    "type": "string",
+                    "example": "操作成功"
+                },
+                "request_id": {
+                    "description": "请求ID，由RequestID中间件写出，与响应头X-Request-ID一致",
+                    "type": "string",
+                    "example": "6f1c2a8e-3b7d-4c1e-9a2f-0d5e8b7c4a31"
+                },
+                "status": {
+                    "type": "integer",
+                    "example": 0
+                }
+            }
+        },
+        "controllers.APIResponse-sharing_ChangelogPage": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "错误码，成功时为空，取值见ErrorCodeCatalog",
+                    "type": "string",
+                    "example": "VALIDATION_FAILED"
+                },
+                "data": {
+                    "$ref": "#/definitions/sharing.ChangelogPage"
+                },
+                "msg": {
+                    "type": "string",
+                    "example": "操作成功"
+                },
+                "request_id": {
+                    "description": "请求ID，由RequestID中间件写出，与响应头X-Request-ID一致",
+                    "type": "string",
+                    "example": "6f1c2a8e-3b7d-4c1e-9a2f-0d5e8b7c4a31"
+                },
+                "status": {
+                    "type": "integer",
+                    "example": 0
+                }
+            }
+        },
+        "models.ThematicChangelogEntry": {
+            "type": "object",
+            "properties": {
+                "changed_at": {
+                    "type": "string"
+                },
+                "changed_columns": {
+                    "description": "修改的列，只有update有",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "execution_id": {
+                    "description": "产生变更的主题同步执行",
+                    "type": "string"
+                },
+                "op": {
+                    "description": "insert, update, delete",
+                    "type": "string"
+                },
+                "pk": {
+                    "description": "主键字段 -\u003e 取值",
+                    "type": "object",
+                    "additionalProperties": true
+                },
+                "seq": {
+                    "description": "单调递增的序号，作为拉取游标",
+                    "type": "integer"
+                }
+            }
+        },
+        "sharing.ChangelogPage": {
+            "type": "object",
+            "properties": {
+                "entries": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ThematicChangelogEntry"
+                    }
+                },
+                "has_more": {
+                    "description": "是否还有更多变更",
+                    "type": "boolean"
+                },
+                "interface_id": {
+                    "description": "共享接口ID，由调用方填写",
+                    "type": "string"
+                },
+                "next_since": {
+                    "description": "下次拉取时传入的since",
+                    "type": "integer"
+                },
+                "resync_required": {
+                    "description": "since之后的部分变更已被清理，需要重新读取全量数据",
+                    "type": "boolean"
+                },
+                "since": {
+                    "description": "本次拉取的起始序号（不含）",
+                    "type": "integer"
+                }
+            }
+        }
+    }
+}`
+
+// SwaggerInfosharing holds exported Swagger Info so clients can modify it
+var SwaggerInfosharing = &swag.Spec{
+	Version:          "1.0",
+	Host:             "",
+	BasePath:         "/",
+	Schemes:          []string{},
+	Title:            "数据底座服务 API",
+	Description:      "智慧园区数据底座后台服务，提供数据采集、处理、存储、治理和共享功能",
+	InfoInstanceName: "sharing",
+	SwaggerTemplate:  docTemplatesharing,
+	LeftDelim:        "[[",
+	RightDelim:       "]]",
+}
+
+func init() {
+	swag.Register(SwaggerInfosharing.InstanceName(), SwaggerInfosharing)
+}
@@ -0,0 +1,71 @@
+/*
+ * @module logger/context
+ * @description 日志关联ID，在context中传递请求ID，并在slog日志中自动附带请求ID和链路ID
+ * @architecture 基础设施层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow 请求中间件写入请求ID -> 业务代码使用slog.XxxContext记录日志 -> 处理器从context取出request_id/trace_id追加到日志
+ * @rules 只有携带context的日志调用才会附带关联ID；context中没有对应值时不追加空字段
+ * @dependencies log/slog, datahub-service/service/tracing
+ * @refs logger/logger.go, api/middleware/request_id.go
+ */
+
+package logger
+
+import (
+	"context"
+	"datahub-service/service/tracing"
+	"log/slog"
+)
+
+// RequestIDHeader 请求ID的请求头和响应头
+const RequestIDHeader = "X-Request-ID"
+
+type requestIDKey struct{}
+
+// WithRequestID 把请求ID写入context
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	if requestID == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
+// RequestIDFromContext 获取context中的请求ID，没有时返回空字符串
+func RequestIDFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	requestID, _ := ctx.Value(requestIDKey{}).(string)
+	return requestID
+}
+
+// contextHandler 从context中提取关联ID追加到日志记录
+type contextHandler struct {
+	slog.Handler
+}
+
+// NewContextHandler 包装日志处理器，使携带context的日志自动附带request_id和trace_id
+func NewContextHandler(handler slog.Handler) slog.Handler {
+	return &contextHandler{Handler: handler}
+}
+
+// Handle 追加关联ID后交给下层处理器
+func (h *contextHandler) Handle(ctx context.Context, record slog.Record) error {
+	if requestID := RequestIDFromContext(ctx); requestID != "" {
+		record.AddAttrs(slog.String("request_id", requestID))
+	}
+	if traceID := tracing.TraceIDFromContext(ctx); traceID != "" {
+		record.AddAttrs(slog.String("trace_id", traceID))
+	}
+	return h.Handler.Handle(ctx, record)
+}
+
+// WithAttrs 保持包装关系
+func (h *contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &contextHandler{Handler: h.Handler.WithAttrs(attrs)}
+}
+
+// WithGroup 保持包装关系
+func (h *contextHandler) WithGroup(name string) slog.Handler {
+	return &contextHandler{Handler: h.Handler.WithGroup(name)}
+}
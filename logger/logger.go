@@ -1,16 +1,35 @@
 package logger
 
 import (
+	"log"
 	"log/slog"
 	"os"
+	"strings"
+	"time"
+
+	gormlogger "gorm.io/gorm/logger"
 )
 
 // InitLogger 初始化全局日志记录器
-// 创建 JSON 格式的日志处理器,输出到 stdout
+// 创建 JSON 格式的日志处理器,输出到 stdout,携带context的日志自动附带请求ID和链路ID,
+// 日志消息和属性中的敏感字段输出前脱敏,LOG_MASK_FIELDS可追加敏感字段名(逗号分隔)
 func InitLogger() {
+	if fields := os.Getenv("LOG_MASK_FIELDS"); fields != "" {
+		SetMaskFields(MaskSourceConfig, strings.Split(fields, ","))
+	}
 	handler := slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{
 		Level: slog.LevelDebug,
 	})
-	logger := slog.New(handler)
+	logger := slog.New(NewContextHandler(NewSanitizeHandler(handler)))
 	slog.SetDefault(logger)
 }
+
+// NewGormLogger 创建数据库日志记录器,与gorm默认配置相同,但SQL中的参数以占位符输出,避免行数据写入日志
+func NewGormLogger() gormlogger.Interface {
+	return gormlogger.New(log.New(os.Stdout, "\r\n", log.LstdFlags), gormlogger.Config{
+		SlowThreshold:        200 * time.Millisecond,
+		LogLevel:             gormlogger.Warn,
+		Colorful:             true,
+		ParameterizedQueries: true,
+	})
+}
@@ -0,0 +1,303 @@
+/*
+ * @module logger/sanitize
+ * @description 日志和错误信息脱敏，把日志属性、日志消息、返回给调用方的错误详情中敏感字段的值替换为***
+ * @architecture 基础设施层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow 启动时读取LOG_MASK_FIELDS -> 业务服务登记接口上标记为敏感的字段 -> 合并为敏感字段集合 ->
+ *            日志处理器/错误响应调用Sanitize -> 按键名脱敏map和结构体，按"字段=值"、"字段: 值"、JSON等形式脱敏文本
+ * @rules 字段名不区分大小写且须完全相同（phone不匹配phone_type）；内置常见的密码、令牌、证件、手机号等字段名；
+ *        PostgreSQL约束错误中的"Key (列)=(值)"在列含敏感字段时整体脱敏，"Failing row contains (...)"包含整行数据，始终脱敏；
+ *        结构体按JSON序列化后脱敏，序列化失败时原样保留
+ * @dependencies log/slog
+ * @refs logger/logger.go, api/controllers/response.go, service/eventlog/eventlog_service.go, service/logmask
+ */
+
+package logger
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// MaskedValue 脱敏后的值
+const MaskedValue = "***"
+
+// 敏感字段来源
+const (
+	MaskSourceConfig    = "config"    // LOG_MASK_FIELDS配置
+	MaskSourceInterface = "interface" // 接口字段配置中标记为敏感的字段
+)
+
+// maxSanitizeDepth 嵌套数据的最大脱敏深度，更深的数据整体替换
+const maxSanitizeDepth = 16
+
+// defaultMaskFields 内置的敏感字段名
+var defaultMaskFields = []string{
+	"password", "passwd", "pwd", "secret", "client_secret", "token", "access_token", "refresh_token",
+	"api_key", "apikey", "authorization", "private_key", "credential", "credentials",
+	"id_card", "id_number", "phone", "mobile", "email", "bank_card", "card_no",
+}
+
+var (
+	// failingRowPattern 非空、检查约束错误附带的整行数据
+	failingRowPattern = regexp.MustCompile(`Failing row contains \([^\n]*\)`)
+	// constraintKeyPattern 唯一、外键约束错误中的"Key (列)=(值)"
+	constraintKeyPattern = regexp.MustCompile(`\(([^()]*)\)=\(([^()\n]*)\)`)
+)
+
+// maskRules 当前生效的脱敏规则
+type maskRules struct {
+	fields  map[string]bool
+	pattern *regexp.Regexp // 匹配"字段=值"等形式，为空时不按文本脱敏
+}
+
+var (
+	maskMu      sync.Mutex
+	maskSources = map[string][]string{}
+	activeRules atomic.Pointer[maskRules]
+)
+
+func init() {
+	activeRules.Store(buildMaskRules(nil))
+}
+
+// SetMaskFields 设置某个来源的敏感字段，与内置字段和其他来源合并生效
+func SetMaskFields(source string, fields []string) {
+	maskMu.Lock()
+	defer maskMu.Unlock()
+	maskSources[source] = fields
+	var all []string
+	for _, sourceFields := range maskSources {
+		all = append(all, sourceFields...)
+	}
+	activeRules.Store(buildMaskRules(all))
+}
+
+// MaskFields 当前生效的敏感字段，按字母序
+func MaskFields() []string {
+	rules := activeRules.Load()
+	fields := make([]string, 0, len(rules.fields))
+	for field := range rules.fields {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+// IsSensitiveField 字段名是否为敏感字段
+func IsSensitiveField(name string) bool {
+	return activeRules.Load().fields[strings.ToLower(strings.TrimSpace(name))]
+}
+
+// buildMaskRules 合并内置字段和配置的字段，生成匹配文本的正则
+func buildMaskRules(extra []string) *maskRules {
+	rules := &maskRules{fields: map[string]bool{}}
+	for _, field := range append(append([]string{}, defaultMaskFields...), extra...) {
+		if field = strings.ToLower(strings.TrimSpace(field)); field != "" {
+			rules.fields[field] = true
+		}
+	}
+	if len(rules.fields) == 0 {
+		return rules
+	}
+
+	names := make([]string, 0, len(rules.fields))
+	for field := range rules.fields {
+		names = append(names, regexp.QuoteMeta(field))
+	}
+	// 长的字段名优先，避免前缀相同的短字段名先匹配
+	sort.Slice(names, func(i, j int) bool {
+		if len(names[i]) != len(names[j]) {
+			return len(names[i]) > len(names[j])
+		}
+		return names[i] < names[j]
+	})
+	rules.pattern = regexp.MustCompile(`(?i)(["']?)\b(` + strings.Join(names, "|") + `)\b(["']?\s*[:=]\s*)` +
+		`("(?:[^"\\]|\\.)*"|'[^']*'|[^\s,;&)}\]]+)`)
+	return rules
+}
+
+// SanitizeString 脱敏文本中敏感字段的值以及数据库错误附带的行数据
+func SanitizeString(s string) string {
+	if s == "" {
+		return s
+	}
+	rules := activeRules.Load()
+	if strings.Contains(s, "Failing row contains") {
+		s = failingRowPattern.ReplaceAllString(s, "Failing row contains ("+MaskedValue+")")
+	}
+	if strings.Contains(s, ")=(") {
+		s = constraintKeyPattern.ReplaceAllStringFunc(s, func(match string) string {
+			parts := constraintKeyPattern.FindStringSubmatch(match)
+			for _, column := range strings.Split(parts[1], ",") {
+				if rules.fields[strings.ToLower(strings.Trim(strings.TrimSpace(column), `"`))] {
+					return "(" + parts[1] + ")=(" + MaskedValue + ")"
+				}
+			}
+			return match
+		})
+	}
+	if rules.pattern == nil {
+		return s
+	}
+	return rules.pattern.ReplaceAllStringFunc(s, func(match string) string {
+		parts := rules.pattern.FindStringSubmatch(match)
+		value := MaskedValue
+		if quote := parts[4][0]; quote == '"' || quote == '\'' {
+			value = string(quote) + MaskedValue + string(quote)
+		}
+		return parts[1] + parts[2] + parts[3] + value
+	})
+}
+
+// SanitizeValue 脱敏任意值：map的敏感键整体替换，其余字符串和错误按文本脱敏，结构体按JSON序列化后处理
+func SanitizeValue(value interface{}) interface{} {
+	return sanitizeValue(value, 0)
+}
+
+func sanitizeValue(value interface{}, depth int) interface{} {
+	if depth > maxSanitizeDepth {
+		return MaskedValue
+	}
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case string:
+		return SanitizeString(v)
+	case error:
+		return SanitizeString(v.Error())
+	case []byte:
+		return SanitizeString(string(v))
+	case bool, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+		return v
+	case json.Number:
+		return v
+	case map[string]interface{}:
+		result := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			if IsSensitiveField(key) {
+				result[key] = MaskedValue
+				continue
+			}
+			result[key] = sanitizeValue(item, depth+1)
+		}
+		return result
+	case []interface{}:
+		result := make([]interface{}, len(v))
+		for i, item := range v {
+			result[i] = sanitizeValue(item, depth+1)
+		}
+		return result
+	}
+
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Map:
+		if rv.Type().Key().Kind() != reflect.String {
+			return sanitizeJSON(value, depth)
+		}
+		result := make(map[string]interface{}, rv.Len())
+		iter := rv.MapRange()
+		for iter.Next() {
+			key := iter.Key().String()
+			if IsSensitiveField(key) {
+				result[key] = MaskedValue
+				continue
+			}
+			result[key] = sanitizeValue(iter.Value().Interface(), depth+1)
+		}
+		return result
+	case reflect.Slice, reflect.Array:
+		result := make([]interface{}, rv.Len())
+		for i := 0; i < rv.Len(); i++ {
+			result[i] = sanitizeValue(rv.Index(i).Interface(), depth+1)
+		}
+		return result
+	case reflect.Pointer, reflect.Interface:
+		if rv.IsNil() {
+			return value
+		}
+		return sanitizeJSON(value, depth)
+	case reflect.Struct:
+		return sanitizeJSON(value, depth)
+	case reflect.String:
+		return SanitizeString(rv.String())
+	}
+	return value
+}
+
+// sanitizeJSON 按JSON序列化后脱敏，序列化失败时原样返回
+func sanitizeJSON(value interface{}, depth int) interface{} {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return value
+	}
+	var decoded interface{}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return value
+	}
+	return sanitizeValue(decoded, depth+1)
+}
+
+// sanitizeHandler 脱敏日志消息和属性
+type sanitizeHandler struct {
+	slog.Handler
+}
+
+// NewSanitizeHandler 包装日志处理器，输出前脱敏日志消息和全部属性
+func NewSanitizeHandler(handler slog.Handler) slog.Handler {
+	return &sanitizeHandler{Handler: handler}
+}
+
+// Handle 脱敏后交给下层处理器
+func (h *sanitizeHandler) Handle(ctx context.Context, record slog.Record) error {
+	sanitized := slog.NewRecord(record.Time, record.Level, SanitizeString(record.Message), record.PC)
+	record.Attrs(func(attr slog.Attr) bool {
+		sanitized.AddAttrs(sanitizeAttr(attr))
+		return true
+	})
+	return h.Handler.Handle(ctx, sanitized)
+}
+
+// WithAttrs 预置的属性同样脱敏
+func (h *sanitizeHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	sanitized := make([]slog.Attr, len(attrs))
+	for i, attr := range attrs {
+		sanitized[i] = sanitizeAttr(attr)
+	}
+	return &sanitizeHandler{Handler: h.Handler.WithAttrs(sanitized)}
+}
+
+// WithGroup 保持包装关系
+func (h *sanitizeHandler) WithGroup(name string) slog.Handler {
+	return &sanitizeHandler{Handler: h.Handler.WithGroup(name)}
+}
+
+// sanitizeAttr 敏感键整体替换，其余按值的类型脱敏
+func sanitizeAttr(attr slog.Attr) slog.Attr {
+	if IsSensitiveField(attr.Key) {
+		return slog.String(attr.Key, MaskedValue)
+	}
+	value := attr.Value.Resolve()
+	switch value.Kind() {
+	case slog.KindString:
+		return slog.String(attr.Key, SanitizeString(value.String()))
+	case slog.KindGroup:
+		group := value.Group()
+		sanitized := make([]any, len(group))
+		for i, item := range group {
+			sanitized[i] = sanitizeAttr(item)
+		}
+		return slog.Group(attr.Key, sanitized...)
+	case slog.KindAny:
+		return slog.Any(attr.Key, SanitizeValue(value.Any()))
+	}
+	return slog.Attr{Key: attr.Key, Value: value}
+}
@@ -0,0 +1,83 @@
+/*
+ * @module logger/sanitize_test
+ * @description 日志脱敏测试，覆盖文本中各种形式的敏感字段、数据库错误附带的行数据、map和结构体的脱敏以及日志处理器
+ * @architecture 测试层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow 登记敏感字段 -> 脱敏文本/值/日志记录 -> 检查输出
+ * @rules 纯函数测试，测试结束恢复登记的字段
+ * @dependencies stretchr/testify
+ * @refs sanitize.go
+ */
+
+package logger
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSanitizeString(t *testing.T) {
+	SetMaskFields(MaskSourceInterface, []string{"person_name"})
+	defer SetMaskFields(MaskSourceInterface, nil)
+
+	cases := map[string]string{
+		`插入失败: password=abc123 dbname=hub`:                  `插入失败: password=*** dbname=hub`,
+		`{"person_name":"张三","age":30}`:                     `{"person_name":"***","age":30}`,
+		`map[person_name:张三 phone:13800000000 region:east]`: `map[person_name:*** phone:*** region:east]`,
+		`PHONE: 13800000000, city: x`:                       `PHONE: ***, city: x`,
+		`phone_type=mobile`:                                 `phone_type=mobile`,
+		`url?token=abc&page=1`:                              `url?token=***&page=1`,
+		`ERROR: duplicate key value violates unique constraint "uk" (SQLSTATE 23505) DETAIL: Key (person_name, org)=(张三, A) already exists.`: `ERROR: duplicate key value violates unique constraint "uk" (SQLSTATE 23505) DETAIL: Key (person_name, org)=(***) already exists.`,
+		`DETAIL: Key (org_id)=(A) is not present`: `DETAIL: Key (org_id)=(A) is not present`,
+		`null value in column "age" violates not-null constraint DETAIL: Failing row contains (1, 张三, null).`: `null value in column "age" violates not-null constraint DETAIL: Failing row contains (***).`,
+	}
+	for input, expected := range cases {
+		assert.Equal(t, expected, SanitizeString(input), input)
+	}
+
+	assert.True(t, IsSensitiveField("Person_Name"))
+	SetMaskFields(MaskSourceInterface, nil)
+	assert.False(t, IsSensitiveField("person_name"), "取消登记后不再脱敏")
+}
+
+func TestSanitizeValue(t *testing.T) {
+	type credential struct {
+		User     string `json:"user"`
+		Password string `json:"password"`
+	}
+	row := map[string]interface{}{
+		"id":       1,
+		"token":    "t-1",
+		"note":     "api_key=xyz",
+		"nested":   []interface{}{map[string]interface{}{"email": "a@b.c", "ok": true}},
+		"login":    credential{User: "u", Password: "p"},
+		"failures": errors.New("secret: s3"),
+	}
+	sanitized := SanitizeValue(row).(map[string]interface{})
+	assert.Equal(t, 1, sanitized["id"])
+	assert.Equal(t, MaskedValue, sanitized["token"])
+	assert.Equal(t, "api_key=***", sanitized["note"])
+	assert.Equal(t, map[string]interface{}{"email": MaskedValue, "ok": true}, sanitized["nested"].([]interface{})[0])
+	assert.Equal(t, map[string]interface{}{"user": "u", "password": MaskedValue}, sanitized["login"])
+	assert.Equal(t, "secret: ***", sanitized["failures"])
+	assert.Equal(t, "t-1", row["token"], "不修改原始数据")
+}
+
+func TestSanitizeHandler(t *testing.T) {
+	var buf bytes.Buffer
+	log := slog.New(NewSanitizeHandler(slog.NewJSONHandler(&buf, nil))).With("password", "p")
+	log.Info("连接失败 token=abc", "data", map[string]interface{}{"mobile": "138", "name": "n"}, "error", errors.New("pwd=1"))
+
+	output := buf.String()
+	assert.NotContains(t, output, `"p"`)
+	assert.NotContains(t, output, "abc")
+	assert.NotContains(t, output, "138")
+	assert.Contains(t, output, `"password":"***"`)
+	assert.Contains(t, output, `"mobile":"***"`)
+	assert.Contains(t, output, `"name":"n"`)
+	assert.Contains(t, output, `"error":"pwd=***"`)
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"datahub-service/api"
+	"datahub-service/api/swagger"
 	_ "datahub-service/docs"
 	"datahub-service/logger"
 	"log"
@@ -9,12 +10,12 @@ import (
 	"os"
 	"strconv"
 
-	_ "datahub-service/service"
+	"datahub-service/service"
+	"datahub-service/service/execution"
 
 	daprd "github.com/dapr/go-sdk/service/http"
 	"github.com/go-chi/chi/v5"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
-	httpSwagger "github.com/swaggo/http-swagger"
 )
 
 var (
@@ -35,29 +36,41 @@ func init() {
 // @title 数据底座服务 API
 // @version 1.0
 // @description 智慧园区数据底座后台服务，提供数据采集、处理、存储、治理和共享功能
-// @BasePath /swagger/datahub-service
+// @BasePath /
 func main() {
 	// 初始化日志系统
 	logger.InitLogger()
 
 	mux := chi.NewRouter()
 
-	// 如果有BASE_CONTEXT，则在该路径下挂载所有路由
-	if BASE_CONTEXT != "" {
-		mux.Route(BASE_CONTEXT, func(r chi.Router) {
-			// 创建子路由器并初始化路由
-			subMux := r.(*chi.Mux)
-			api.InitRoute(subMux)
+	mountRoutes := func(r chi.Router) {
+		api.InitRoute(r.(*chi.Mux))
+		r.Handle("/metrics", promhttp.Handler())
+		swagger.InitRoute(r, BASE_CONTEXT)
+	}
+	// worker模式只挂载健康检查和数据推送路由
+	if service.GlobalDeployMode == execution.ModeWorker {
+		mountRoutes = func(r chi.Router) {
+			api.InitWorkerRoute(r.(*chi.Mux))
 			r.Handle("/metrics", promhttp.Handler())
-			r.Handle("/swagger*", httpSwagger.WrapHandler)
-		})
+		}
+	}
+	// 如果有BASE_CONTEXT，则在该路径下挂载所有路由，worker与standalone、control的路径一致
+	if BASE_CONTEXT != "" {
+		mux.Route(BASE_CONTEXT, mountRoutes)
 	} else {
-		api.InitRoute(mux)
-		mux.Handle("/metrics", promhttp.Handler())
-		mux.Handle("/swagger*", httpSwagger.WrapHandler)
+		mountRoutes(mux)
 	}
 
 	s := daprd.NewServiceWithMux(":"+strconv.Itoa(PORT), mux)
+
+	// worker订阅执行队列，接收控制面分发的执行命令
+	if service.GlobalDeployMode == execution.ModeWorker {
+		if err := s.AddTopicEventHandler(service.GlobalExecutionWorker.Subscription(), service.GlobalExecutionWorker.TopicEventHandler); err != nil {
+			log.Fatalf("订阅执行队列失败: %v", err)
+		}
+	}
+
 	if err := s.Start(); err != nil && err != http.ErrServerClosed {
 		log.Fatalf("error: %v", err)
 	}
@@ -0,0 +1,127 @@
+/*
+ * @module service/backup/artifact
+ * @description 备份文件格式定义与解析，备份文件为（可gzip压缩的）JSON清单，包含每张表的列、数据行、行数和校验和
+ * @architecture 分层架构 - 业务服务层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow 下载的备份文件 -> 解压 -> 解析清单 -> 结构校验 -> 供还原和比对使用
+ * @rules 单元格值统一序列化为字符串，null表示空值；表校验和为每行JSON数组编码排序后以换行连接的SHA-256，与行顺序无关
+ * @dependencies 无
+ * @refs service/backup/verifier.go, service/backup/restorer.go
+ */
+
+package backup
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+)
+
+// ArtifactFormatVersion 当前支持的备份文件格式版本
+const ArtifactFormatVersion = 1
+
+// Artifact 备份文件清单
+type Artifact struct {
+	FormatVersion int             `json:"format_version"`
+	Schema        string          `json:"schema"` // 备份来源schema
+	CreatedAt     time.Time       `json:"created_at"`
+	Tables        []ArtifactTable `json:"tables"`
+}
+
+// ArtifactTable 备份文件中的单张表
+type ArtifactTable struct {
+	Name     string      `json:"name"`
+	Columns  []string    `json:"columns"`
+	RowCount int64       `json:"row_count"` // 备份时记录的行数
+	Checksum string      `json:"checksum"`  // 备份时记录的校验和，见TableChecksum
+	Rows     [][]*string `json:"rows"`
+}
+
+// ParseArtifact 解析备份文件，自动识别gzip压缩
+func ParseArtifact(data []byte) (*Artifact, error) {
+	var reader io.Reader = bytes.NewReader(data)
+	if len(data) >= 2 && data[0] == 0x1f && data[1] == 0x8b {
+		gz, err := gzip.NewReader(reader)
+		if err != nil {
+			return nil, fmt.Errorf("解压备份文件失败: %w", err)
+		}
+		defer gz.Close()
+		reader = gz
+	}
+
+	var artifact Artifact
+	if err := json.NewDecoder(reader).Decode(&artifact); err != nil {
+		return nil, fmt.Errorf("解析备份清单失败: %w", err)
+	}
+	if err := artifact.validate(); err != nil {
+		return nil, err
+	}
+	return &artifact, nil
+}
+
+// validate 校验清单结构，结构不完整的备份无法还原
+func (a *Artifact) validate() error {
+	if a.FormatVersion != ArtifactFormatVersion {
+		return fmt.Errorf("不支持的备份文件格式版本: %d", a.FormatVersion)
+	}
+	if len(a.Tables) == 0 {
+		return errors.New("备份清单中没有数据表")
+	}
+
+	tableNames := make(map[string]bool, len(a.Tables))
+	for _, table := range a.Tables {
+		if table.Name == "" {
+			return errors.New("备份清单中存在未命名的数据表")
+		}
+		if tableNames[table.Name] {
+			return fmt.Errorf("备份清单中数据表重复: %s", table.Name)
+		}
+		tableNames[table.Name] = true
+
+		if len(table.Columns) == 0 {
+			return fmt.Errorf("数据表 %s 没有列定义", table.Name)
+		}
+		columns := make(map[string]bool, len(table.Columns))
+		for _, column := range table.Columns {
+			if column == "" || columns[column] {
+				return fmt.Errorf("数据表 %s 的列定义无效: %q", table.Name, column)
+			}
+			columns[column] = true
+		}
+		if table.Checksum == "" {
+			return fmt.Errorf("数据表 %s 缺少校验和", table.Name)
+		}
+		for i, row := range table.Rows {
+			if len(row) != len(table.Columns) {
+				return fmt.Errorf("数据表 %s 第%d行列数为%d，应为%d", table.Name, i+1, len(row), len(table.Columns))
+			}
+		}
+	}
+	return nil
+}
+
+// TableChecksum 计算表数据的校验和，备份生成方和校验方须使用相同算法
+func TableChecksum(rows [][]*string) string {
+	lines := make([]string, 0, len(rows))
+	for _, row := range rows {
+		encoded, _ := json.Marshal(row)
+		lines = append(lines, string(encoded))
+	}
+	sort.Strings(lines)
+
+	hash := sha256.New()
+	for i, line := range lines {
+		if i > 0 {
+			hash.Write([]byte{'\n'})
+		}
+		hash.Write([]byte(line))
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
@@ -0,0 +1,149 @@
+/*
+ * @module service/backup/restorer
+ * @description 备份还原器，将备份清单中的表还原到临时schema，再从数据库读回计算行数和校验和
+ * @architecture 分层架构 - 业务服务层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow 创建临时schema -> 建表（列统一为text） -> 分批写入 -> 读回统计 -> 删除临时schema
+ * @rules 临时schema仅用于校验，校验结束后无论成功与否都会删除；统计结果必须从数据库读回，不能直接使用清单数据
+ * @dependencies gorm.io/gorm
+ * @refs service/backup/verifier.go, service/backup/artifact.go
+ */
+
+package backup
+
+import (
+	"context"
+	"database/sql"
+	"datahub-service/service/utils"
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// restoreBatchSize 还原时单条INSERT写入的行数
+const restoreBatchSize = 500
+
+// RestoredTable 还原后从数据库读回的表统计
+type RestoredTable struct {
+	Name     string
+	RowCount int64
+	Checksum string
+}
+
+// Restorer 备份还原器
+type Restorer interface {
+	// Restore 将表还原到临时schema并返回读回的统计
+	Restore(ctx context.Context, scratchSchema string, tables []ArtifactTable) ([]RestoredTable, error)
+	// Drop 删除临时schema
+	Drop(ctx context.Context, scratchSchema string) error
+}
+
+// PostgresRestorer 基于PostgreSQL的还原器
+type PostgresRestorer struct {
+	db *gorm.DB
+}
+
+// NewPostgresRestorer 创建PostgreSQL还原器
+func NewPostgresRestorer(db *gorm.DB) *PostgresRestorer {
+	return &PostgresRestorer{db: db}
+}
+
+// Restore 将表还原到临时schema并返回读回的统计
+func (r *PostgresRestorer) Restore(ctx context.Context, scratchSchema string, tables []ArtifactTable) ([]RestoredTable, error) {
+	db := r.db.WithContext(ctx)
+	if err := db.Exec("CREATE SCHEMA " + utils.QuoteIdent(scratchSchema)).Error; err != nil {
+		return nil, fmt.Errorf("创建临时schema失败: %w", err)
+	}
+
+	results := make([]RestoredTable, 0, len(tables))
+	for _, table := range tables {
+		if err := r.restoreTable(db, scratchSchema, table); err != nil {
+			return nil, fmt.Errorf("还原数据表 %s 失败: %w", table.Name, err)
+		}
+		restored, err := r.readBack(db, scratchSchema, table)
+		if err != nil {
+			return nil, fmt.Errorf("读取还原数据表 %s 失败: %w", table.Name, err)
+		}
+		results = append(results, *restored)
+	}
+	return results, nil
+}
+
+// Drop 删除临时schema
+func (r *PostgresRestorer) Drop(ctx context.Context, scratchSchema string) error {
+	return r.db.WithContext(ctx).Exec("DROP SCHEMA IF EXISTS " + utils.QuoteIdent(scratchSchema) + " CASCADE").Error
+}
+
+// restoreTable 建表并分批写入数据
+func (r *PostgresRestorer) restoreTable(db *gorm.DB, scratchSchema string, table ArtifactTable) error {
+	tableName := utils.QuoteTable(scratchSchema, table.Name)
+
+	columnDefs := make([]string, len(table.Columns))
+	columnNames := make([]string, len(table.Columns))
+	for i, column := range table.Columns {
+		columnNames[i] = utils.QuoteIdent(column)
+		columnDefs[i] = columnNames[i] + " text"
+	}
+	if err := db.Exec(fmt.Sprintf("CREATE TABLE %s (%s)", tableName, strings.Join(columnDefs, ", "))).Error; err != nil {
+		return err
+	}
+
+	placeholder := "(" + strings.TrimSuffix(strings.Repeat("?, ", len(table.Columns)), ", ") + ")"
+	for start := 0; start < len(table.Rows); start += restoreBatchSize {
+		end := min(start+restoreBatchSize, len(table.Rows))
+		values := make([]string, 0, end-start)
+		args := make([]interface{}, 0, (end-start)*len(table.Columns))
+		for _, row := range table.Rows[start:end] {
+			values = append(values, placeholder)
+			for _, cell := range row {
+				args = append(args, cell)
+			}
+		}
+		sqlStr := fmt.Sprintf("INSERT INTO %s (%s) VALUES %s", tableName, strings.Join(columnNames, ", "), strings.Join(values, ", "))
+		if err := db.Exec(sqlStr, args...).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// readBack 从数据库读回还原后的数据，计算行数和校验和
+func (r *PostgresRestorer) readBack(db *gorm.DB, scratchSchema string, table ArtifactTable) (*RestoredTable, error) {
+	columnNames := make([]string, len(table.Columns))
+	for i, column := range table.Columns {
+		columnNames[i] = utils.QuoteIdent(column)
+	}
+
+	rows, err := db.Raw(fmt.Sprintf("SELECT %s FROM %s.%s", strings.Join(columnNames, ", "), utils.QuoteIdent(scratchSchema), utils.QuoteIdent(table.Name))).Rows()
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var data [][]*string
+	for rows.Next() {
+		cells := make([]sql.NullString, len(table.Columns))
+		dest := make([]interface{}, len(cells))
+		for i := range cells {
+			dest[i] = &cells[i]
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, err
+		}
+
+		row := make([]*string, len(cells))
+		for i, cell := range cells {
+			if cell.Valid {
+				value := cell.String
+				row[i] = &value
+			}
+		}
+		data = append(data, row)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return &RestoredTable{Name: table.Name, RowCount: int64(len(data)), Checksum: TableChecksum(data)}, nil
+}
@@ -0,0 +1,436 @@
+/*
+ * @module service/backup/verifier
+ * @description 备份完整性校验服务，定期下载备份文件并还原到临时schema，比对行数和校验和后标记备份记录为verified/corrupt，避免到真正恢复时才发现备份不可用
+ * @architecture 分层架构 - 业务服务层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow 定时触发/手动触发 -> 下载备份文件 -> 解析清单 -> 还原到临时schema -> 比对行数和校验和 -> 删除临时schema -> 写回校验结果
+ * @rules 只校验状态为success的备份；文件缺失、无法解析、大小或数据不一致标记为corrupt；下载或还原过程出错标记为failed并在下次调度重试；存储位置和备份策略storage_headers中的请求头可以是密钥引用，下载时解析；同一备份记录同时只允许一个校验
+ * @dependencies datahub-service/service/models, datahub-service/service/distributed_lock, datahub-service/service/secrets, gorm.io/gorm, github.com/robfig/cron/v3
+ * @refs service/backup/artifact.go, service/backup/restorer.go, api/controllers/backup_controller.go
+ */
+
+package backup
+
+import (
+	"context"
+	"datahub-service/service/distributed_lock"
+	"datahub-service/service/eventlog"
+	"datahub-service/service/models"
+	"datahub-service/service/secrets"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	"gorm.io/gorm"
+)
+
+const (
+	// defaultVerifySchedule 默认每天凌晨3点校验，错开日志清理任务
+	defaultVerifySchedule = "0 0 3 * * *"
+	// defaultVerifyBatchSize 每次调度最多校验的备份数量
+	defaultVerifyBatchSize = 5
+	// maxArtifactSize 备份文件大小上限
+	maxArtifactSize = 1 << 30
+	// verifyLockTTL 校验锁的过期时间
+	verifyLockTTL = time.Hour
+)
+
+// 校验错误
+var (
+	ErrBackupNotVerifiable = errors.New("只能校验状态为success的备份记录")
+	ErrVerificationRunning = errors.New("该备份记录正在校验中")
+)
+
+// Verifier 备份完整性校验服务
+type Verifier struct {
+	db         *gorm.DB
+	restorer   Restorer
+	httpClient *http.Client
+	lock       distributed_lock.DistributedLock
+	schedule   string
+	batchSize  int
+	running    sync.Map // 本实例正在校验的备份记录ID
+	cron       *cron.Cron
+	ctx        context.Context
+	cancel     context.CancelFunc
+	started    bool
+}
+
+// NewVerifier 创建备份完整性校验服务实例，调度周期和批量可通过BACKUP_VERIFY_SCHEDULE、BACKUP_VERIFY_BATCH_SIZE配置
+func NewVerifier(db *gorm.DB) *Verifier {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	schedule := os.Getenv("BACKUP_VERIFY_SCHEDULE")
+	if schedule == "" {
+		schedule = defaultVerifySchedule
+	}
+	batchSize := defaultVerifyBatchSize
+	if value, err := strconv.Atoi(os.Getenv("BACKUP_VERIFY_BATCH_SIZE")); err == nil && value > 0 {
+		batchSize = value
+	}
+
+	return &Verifier{
+		db:         db,
+		restorer:   NewPostgresRestorer(db),
+		httpClient: &http.Client{Timeout: 10 * time.Minute},
+		schedule:   schedule,
+		batchSize:  batchSize,
+		cron:       cron.New(cron.WithSeconds()),
+		ctx:        ctx,
+		cancel:     cancel,
+	}
+}
+
+// SetDistributedLock 设置分布式锁，多实例部署时避免重复校验
+func (v *Verifier) SetDistributedLock(lock distributed_lock.DistributedLock) {
+	v.lock = lock
+}
+
+// VerifyRecord 校验指定备份记录并写回校验结果
+func (v *Verifier) VerifyRecord(ctx context.Context, recordID string) (*models.BackupRecord, error) {
+	var record models.BackupRecord
+	if err := v.db.WithContext(ctx).Preload("BackupConfig").First(&record, "id = ?", recordID).Error; err != nil {
+		return nil, err
+	}
+	if record.Status != models.BackupStatusSuccess {
+		return nil, ErrBackupNotVerifiable
+	}
+
+	if _, loaded := v.running.LoadOrStore(record.ID, true); loaded {
+		return nil, ErrVerificationRunning
+	}
+	defer v.running.Delete(record.ID)
+
+	if v.lock != nil {
+		lockKey := "backup_verify:" + record.ID
+		locked, err := v.lock.TryLock(ctx, lockKey, verifyLockTTL)
+		if err != nil {
+			return nil, fmt.Errorf("获取校验锁失败: %w", err)
+		}
+		if !locked {
+			return nil, ErrVerificationRunning
+		}
+		defer func() {
+			if err := v.lock.Unlock(context.Background(), lockKey); err != nil {
+				slog.Error("释放备份校验锁失败", "record_id", record.ID, "error", err)
+			}
+		}()
+	}
+
+	status, details, verifyErr := v.verify(ctx, &record)
+	now := time.Now()
+	updates := map[string]interface{}{
+		"verification_status":  status,
+		"verified_at":          now,
+		"verification_details": details,
+		"verification_error":   nil,
+	}
+	if verifyErr != nil {
+		updates["verification_error"] = verifyErr.Error()
+	}
+	if err := v.db.WithContext(ctx).Model(&models.BackupRecord{}).Where("id = ?", record.ID).Updates(updates).Error; err != nil {
+		return nil, fmt.Errorf("保存校验结果失败: %w", err)
+	}
+
+	v.recordResult(ctx, &record, status, verifyErr)
+
+	if err := v.db.WithContext(ctx).Preload("BackupConfig").First(&record, "id = ?", record.ID).Error; err != nil {
+		return nil, err
+	}
+	return &record, nil
+}
+
+// VerifyPending 校验未校验或上次校验出错的备份，返回本次校验的数量
+func (v *Verifier) VerifyPending(ctx context.Context) (int, error) {
+	var ids []string
+	err := v.db.WithContext(ctx).Model(&models.BackupRecord{}).
+		Where("status = ?", models.BackupStatusSuccess).
+		Where("verification_status IS NULL OR verification_status IN ?", []string{"", models.BackupVerificationFailed}).
+		Order("start_time ASC").
+		Limit(v.batchSize).
+		Pluck("id", &ids).Error
+	if err != nil {
+		return 0, err
+	}
+
+	verified := 0
+	for _, id := range ids {
+		if ctx.Err() != nil {
+			break
+		}
+		if _, err := v.VerifyRecord(ctx, id); err != nil {
+			slog.Error("备份完整性校验失败", "record_id", id, "error", err)
+			continue
+		}
+		verified++
+	}
+	return verified, nil
+}
+
+// Start 启动定时校验任务
+func (v *Verifier) Start() error {
+	if v.started {
+		return fmt.Errorf("备份校验调度器已经启动")
+	}
+
+	_, err := v.cron.AddFunc(v.schedule, func() {
+		count, err := v.VerifyPending(v.ctx)
+		if err != nil {
+			slog.Error("定时备份校验任务失败", "error", err)
+			return
+		}
+		slog.Info("定时备份校验任务完成", "verified_count", count)
+	})
+	if err != nil {
+		return fmt.Errorf("添加定时任务失败: %w", err)
+	}
+
+	v.cron.Start()
+	v.started = true
+	slog.Info("备份校验调度器启动成功", "schedule", v.schedule, "batch_size", v.batchSize)
+	return nil
+}
+
+// Stop 停止定时校验任务
+func (v *Verifier) Stop() {
+	if !v.started {
+		return
+	}
+	v.cancel()
+	v.cron.Stop()
+	v.started = false
+}
+
+// verify 执行校验，返回校验状态、明细和原因
+func (v *Verifier) verify(ctx context.Context, record *models.BackupRecord) (string, models.JSONB, error) {
+	details := models.JSONB{}
+
+	access, err := resolveStorageAccess(ctx, record.BackupConfig)
+	if err != nil {
+		return models.BackupVerificationFailed, details, err
+	}
+	location, err := artifactLocation(record, access.location)
+	if err != nil {
+		return models.BackupVerificationCorrupt, details, err
+	}
+	// 存储位置来自密钥引用时可能包含凭据，只记录文件路径
+	if access.fromSecret {
+		details["artifact_location"] = *record.FilePath
+	} else {
+		details["artifact_location"] = location
+	}
+
+	data, err := v.fetchArtifact(ctx, location, access.headers)
+	if err != nil {
+		status, message := models.BackupVerificationFailed, "下载备份文件失败"
+		if errors.Is(err, os.ErrNotExist) {
+			status, message = models.BackupVerificationCorrupt, "备份文件不存在"
+		}
+		if access.fromSecret {
+			// 错误信息中的地址替换为文件路径，避免凭据写入校验结果
+			return status, details, fmt.Errorf("%s: %s", message, strings.ReplaceAll(err.Error(), location, *record.FilePath))
+		}
+		return status, details, fmt.Errorf("%s: %w", message, err)
+	}
+	details["artifact_size"] = len(data)
+	if record.BackupSize != nil && *record.BackupSize != int64(len(data)) {
+		return models.BackupVerificationCorrupt, details, fmt.Errorf("备份文件大小为%d，与记录的%d不一致", len(data), *record.BackupSize)
+	}
+
+	artifact, err := ParseArtifact(data)
+	if err != nil {
+		return models.BackupVerificationCorrupt, details, err
+	}
+
+	scratchSchema := scratchSchemaName(record.ID)
+	details["scratch_schema"] = scratchSchema
+	defer func() {
+		if err := v.restorer.Drop(context.Background(), scratchSchema); err != nil {
+			slog.Error("删除备份校验临时schema失败", "schema", scratchSchema, "error", err)
+		}
+	}()
+
+	restored, err := v.restorer.Restore(ctx, scratchSchema, artifact.Tables)
+	if err != nil {
+		return models.BackupVerificationFailed, details, err
+	}
+
+	tables, mismatched := compareTables(artifact.Tables, restored)
+	details["tables"] = tables
+	if len(mismatched) > 0 {
+		return models.BackupVerificationCorrupt, details, fmt.Errorf("以下数据表行数或校验和不一致: %s", strings.Join(mismatched, ", "))
+	}
+	return models.BackupVerificationVerified, details, nil
+}
+
+// compareTables 比对清单与还原结果，返回每张表的比对明细和不一致的表名
+func compareTables(expected []ArtifactTable, restored []RestoredTable) ([]map[string]interface{}, []string) {
+	restoredByName := make(map[string]RestoredTable, len(restored))
+	for _, table := range restored {
+		restoredByName[table.Name] = table
+	}
+
+	results := make([]map[string]interface{}, 0, len(expected))
+	var mismatched []string
+	for _, table := range expected {
+		result := map[string]interface{}{
+			"name":              table.Name,
+			"expected_rows":     table.RowCount,
+			"expected_checksum": table.Checksum,
+		}
+		actual, ok := restoredByName[table.Name]
+		matched := ok && actual.RowCount == table.RowCount && actual.Checksum == table.Checksum
+		if ok {
+			result["restored_rows"] = actual.RowCount
+			result["restored_checksum"] = actual.Checksum
+		}
+		result["matched"] = matched
+		if !matched {
+			mismatched = append(mismatched, table.Name)
+		}
+		results = append(results, result)
+	}
+	return results, mismatched
+}
+
+// fetchArtifact 下载备份文件，支持http(s)地址和本地路径，下载http(s)地址时附带存储请求头
+func (v *Verifier) fetchArtifact(ctx context.Context, location string, headers map[string]string) ([]byte, error) {
+	var reader io.ReadCloser
+	if strings.HasPrefix(location, "http://") || strings.HasPrefix(location, "https://") {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, location, nil)
+		if err != nil {
+			return nil, err
+		}
+		for name, value := range headers {
+			req.Header.Set(name, value)
+		}
+		resp, err := v.httpClient.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		if resp.StatusCode == http.StatusNotFound {
+			resp.Body.Close()
+			return nil, fmt.Errorf("%s: %w", location, os.ErrNotExist)
+		}
+		if resp.StatusCode != http.StatusOK {
+			resp.Body.Close()
+			return nil, fmt.Errorf("下载返回状态码: %d", resp.StatusCode)
+		}
+		reader = resp.Body
+	} else {
+		file, err := os.Open(strings.TrimPrefix(location, "file://"))
+		if err != nil {
+			return nil, err
+		}
+		reader = file
+	}
+	defer reader.Close()
+
+	data, err := io.ReadAll(io.LimitReader(reader, maxArtifactSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxArtifactSize {
+		return nil, fmt.Errorf("备份文件超过大小上限%d字节", maxArtifactSize)
+	}
+	return data, nil
+}
+
+// recordResult 记录校验结果日志和应用事件，损坏的备份以警告级别记录
+func (v *Verifier) recordResult(ctx context.Context, record *models.BackupRecord, status string, verifyErr error) {
+	event := eventlog.Event{
+		Type:       eventlog.EventBackupVerified,
+		Message:    "备份完整性校验通过",
+		ObjectType: "backup_record",
+		ObjectID:   record.ID,
+		Attributes: map[string]interface{}{"backup_config_id": record.BackupConfigID, "verification_status": status},
+	}
+	if verifyErr != nil {
+		event.Attributes["error"] = verifyErr.Error()
+	}
+
+	switch status {
+	case models.BackupVerificationCorrupt:
+		event.Type = eventlog.EventBackupCorrupt
+		event.Level = models.EventLevelWarn
+		event.Message = "备份文件已损坏"
+		slog.WarnContext(ctx, "备份文件已损坏", "record_id", record.ID, "error", verifyErr)
+	case models.BackupVerificationFailed:
+		event.Type = eventlog.EventBackupVerifyFailed
+		event.Level = models.EventLevelError
+		event.Message = "备份完整性校验出错"
+		slog.ErrorContext(ctx, "备份完整性校验出错", "record_id", record.ID, "error", verifyErr)
+	default:
+		slog.InfoContext(ctx, "备份完整性校验通过", "record_id", record.ID)
+	}
+	eventlog.Record(ctx, event)
+}
+
+// storageAccess 解析密钥引用后的备份存储访问信息
+type storageAccess struct {
+	location   string            // 存储位置
+	headers    map[string]string // 下载备份文件时附带的请求头，来自备份策略的storage_headers
+	fromSecret bool              // 存储位置是否来自密钥引用
+}
+
+// resolveStorageAccess 解析备份配置中存储位置和存储请求头的密钥引用
+func resolveStorageAccess(ctx context.Context, config *models.BackupConfig) (*storageAccess, error) {
+	access := &storageAccess{headers: map[string]string{}}
+	if config == nil {
+		return access, nil
+	}
+	location, err := secrets.Resolve(ctx, config.StorageLocation)
+	if err != nil {
+		return nil, fmt.Errorf("解析备份存储位置失败: %w", err)
+	}
+	access.location = location
+	access.fromSecret = secrets.IsReference(config.StorageLocation)
+
+	headers, _ := config.Strategy["storage_headers"].(map[string]interface{})
+	for name, value := range headers {
+		text, ok := value.(string)
+		if !ok {
+			continue
+		}
+		resolved, err := secrets.Resolve(ctx, text)
+		if err != nil {
+			return nil, fmt.Errorf("解析备份存储请求头%s失败: %w", name, err)
+		}
+		access.headers[name] = resolved
+	}
+	return access, nil
+}
+
+// artifactLocation 计算备份文件地址，相对路径基于备份配置的存储位置
+func artifactLocation(record *models.BackupRecord, base string) (string, error) {
+	if record.FilePath == nil || *record.FilePath == "" {
+		return "", errors.New("备份记录缺少文件路径")
+	}
+	location := *record.FilePath
+	if strings.Contains(location, "://") || filepath.IsAbs(location) || base == "" {
+		return location, nil
+	}
+
+	if strings.HasPrefix(base, "http://") || strings.HasPrefix(base, "https://") {
+		return strings.TrimSuffix(base, "/") + "/" + strings.TrimPrefix(location, "/"), nil
+	}
+	return filepath.Join(strings.TrimPrefix(base, "file://"), location), nil
+}
+
+// scratchSchemaName 计算备份记录对应的临时schema名称
+func scratchSchemaName(recordID string) string {
+	id := strings.ReplaceAll(recordID, "-", "")
+	if len(id) > 32 {
+		id = id[:32]
+	}
+	return "backup_verify_" + strings.ToLower(id)
+}
@@ -0,0 +1,253 @@
+/*
+ * @module service/backup/verifier_test
+ * @description 备份完整性校验测试，覆盖校验通过、数据不一致、文件缺失或损坏、还原出错重试和http下载
+ * @architecture 测试层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow 生成备份文件和备份记录 -> 使用内存还原器校验 -> 验证记录的校验状态
+ * @rules 使用内存sqlite和内存还原器，不依赖PostgreSQL
+ * @dependencies gorm.io/driver/sqlite, stretchr/testify
+ * @refs service/backup/verifier.go, service/backup/artifact.go
+ */
+
+package backup
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"datahub-service/service/models"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+// memoryRestorer 内存还原器，按清单数据计算统计，可模拟还原丢行和出错
+type memoryRestorer struct {
+	dropRows int
+	err      error
+	dropped  []string
+}
+
+func (m *memoryRestorer) Restore(ctx context.Context, scratchSchema string, tables []ArtifactTable) ([]RestoredTable, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	results := make([]RestoredTable, 0, len(tables))
+	for _, table := range tables {
+		rows := table.Rows[:max(len(table.Rows)-m.dropRows, 0)]
+		results = append(results, RestoredTable{Name: table.Name, RowCount: int64(len(rows)), Checksum: TableChecksum(rows)})
+	}
+	return results, nil
+}
+
+func (m *memoryRestorer) Drop(ctx context.Context, scratchSchema string) error {
+	m.dropped = append(m.dropped, scratchSchema)
+	return nil
+}
+
+func setupVerifier(t *testing.T) (*Verifier, *memoryRestorer) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: gormlogger.Default.LogMode(gormlogger.Silent),
+	})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&models.BackupConfig{}, &models.BackupRecord{}))
+
+	restorer := &memoryRestorer{}
+	v := NewVerifier(db)
+	v.restorer = restorer
+	return v, restorer
+}
+
+func strPtr(s string) *string {
+	return &s
+}
+
+func sampleTable() ArtifactTable {
+	rows := [][]*string{
+		{strPtr("1"), strPtr("张三"), nil},
+		{strPtr("2"), strPtr("李四"), strPtr("2024-01-01")},
+	}
+	return ArtifactTable{Name: "persons", Columns: []string{"id", "name", "birthday"}, RowCount: 2, Checksum: TableChecksum(rows), Rows: rows}
+}
+
+// writeArtifact 将清单gzip压缩写入文件，返回文件路径和大小
+func writeArtifact(t *testing.T, dir string, tables ...ArtifactTable) (string, int64) {
+	encoded, err := json.Marshal(Artifact{FormatVersion: ArtifactFormatVersion, Schema: "lib_a", CreatedAt: time.Now(), Tables: tables})
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	_, err = gz.Write(encoded)
+	require.NoError(t, err)
+	require.NoError(t, gz.Close())
+
+	path := filepath.Join(dir, "backup.json.gz")
+	require.NoError(t, os.WriteFile(path, buf.Bytes(), 0o644))
+	return path, int64(buf.Len())
+}
+
+func createRecord(t *testing.T, v *Verifier, storageLocation, filePath string, size *int64) *models.BackupRecord {
+	config := &models.BackupConfig{Name: "每日全量", Type: "full", ObjectType: "basic_library", ObjectID: "lib-1", Strategy: models.JSONB{}, StorageLocation: storageLocation}
+	require.NoError(t, v.db.Create(config).Error)
+	record := &models.BackupRecord{BackupConfigID: config.ID, StartTime: time.Now(), Status: models.BackupStatusSuccess, BackupSize: size, FilePath: &filePath}
+	require.NoError(t, v.db.Create(record).Error)
+	return record
+}
+
+func TestVerifyRecordVerified(t *testing.T) {
+	v, restorer := setupVerifier(t)
+	path, size := writeArtifact(t, t.TempDir(), sampleTable())
+	record := createRecord(t, v, filepath.Dir(path), filepath.Base(path), &size)
+
+	result, err := v.VerifyRecord(context.Background(), record.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.BackupVerificationVerified, result.VerificationStatus)
+	assert.NotNil(t, result.VerifiedAt)
+	assert.Nil(t, result.VerificationError)
+	assert.Equal(t, []string{scratchSchemaName(record.ID)}, restorer.dropped)
+
+	tables, ok := result.VerificationDetails["tables"].([]interface{})
+	require.True(t, ok)
+	require.Len(t, tables, 1)
+	assert.Equal(t, true, tables[0].(map[string]interface{})["matched"])
+}
+
+func TestVerifyRecordCorrupt(t *testing.T) {
+	t.Run("还原数据与清单不一致", func(t *testing.T) {
+		v, restorer := setupVerifier(t)
+		restorer.dropRows = 1
+		path, _ := writeArtifact(t, t.TempDir(), sampleTable())
+		record := createRecord(t, v, "/backups", path, nil)
+
+		result, err := v.VerifyRecord(context.Background(), record.ID)
+		require.NoError(t, err)
+		assert.Equal(t, models.BackupVerificationCorrupt, result.VerificationStatus)
+		require.NotNil(t, result.VerificationError)
+		assert.Contains(t, *result.VerificationError, "persons")
+		assert.Len(t, restorer.dropped, 1)
+	})
+
+	t.Run("清单校验和与数据不一致", func(t *testing.T) {
+		v, _ := setupVerifier(t)
+		table := sampleTable()
+		table.Rows = table.Rows[:1]
+		path, _ := writeArtifact(t, t.TempDir(), table)
+		record := createRecord(t, v, "/backups", path, nil)
+
+		result, err := v.VerifyRecord(context.Background(), record.ID)
+		require.NoError(t, err)
+		assert.Equal(t, models.BackupVerificationCorrupt, result.VerificationStatus)
+	})
+
+	t.Run("文件大小与记录不一致", func(t *testing.T) {
+		v, _ := setupVerifier(t)
+		path, size := writeArtifact(t, t.TempDir(), sampleTable())
+		size++
+		record := createRecord(t, v, "/backups", path, &size)
+
+		result, err := v.VerifyRecord(context.Background(), record.ID)
+		require.NoError(t, err)
+		assert.Equal(t, models.BackupVerificationCorrupt, result.VerificationStatus)
+	})
+
+	t.Run("文件被截断", func(t *testing.T) {
+		v, restorer := setupVerifier(t)
+		path, size := writeArtifact(t, t.TempDir(), sampleTable())
+		data, err := os.ReadFile(path)
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(path, data[:size/2], 0o644))
+		record := createRecord(t, v, "/backups", path, nil)
+
+		result, err := v.VerifyRecord(context.Background(), record.ID)
+		require.NoError(t, err)
+		assert.Equal(t, models.BackupVerificationCorrupt, result.VerificationStatus)
+		assert.Empty(t, restorer.dropped)
+	})
+
+	t.Run("文件不存在", func(t *testing.T) {
+		v, _ := setupVerifier(t)
+		record := createRecord(t, v, "/backups", filepath.Join(t.TempDir(), "missing.json.gz"), nil)
+
+		result, err := v.VerifyRecord(context.Background(), record.ID)
+		require.NoError(t, err)
+		assert.Equal(t, models.BackupVerificationCorrupt, result.VerificationStatus)
+	})
+}
+
+func TestVerifyPendingRetriesFailed(t *testing.T) {
+	v, restorer := setupVerifier(t)
+	restorer.err = errors.New("临时schema创建失败")
+	path, _ := writeArtifact(t, t.TempDir(), sampleTable())
+	record := createRecord(t, v, "/backups", path, nil)
+
+	failedRecord := &models.BackupRecord{BackupConfigID: record.BackupConfigID, StartTime: time.Now(), Status: models.BackupStatusFailure}
+	require.NoError(t, v.db.Create(failedRecord).Error)
+
+	count, err := v.VerifyPending(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+
+	var saved models.BackupRecord
+	require.NoError(t, v.db.First(&saved, "id = ?", record.ID).Error)
+	assert.Equal(t, models.BackupVerificationFailed, saved.VerificationStatus)
+
+	// 校验出错的记录在下次调度重试，通过后不再重复校验
+	restorer.err = nil
+	count, err = v.VerifyPending(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+	require.NoError(t, v.db.First(&saved, "id = ?", record.ID).Error)
+	assert.Equal(t, models.BackupVerificationVerified, saved.VerificationStatus)
+	assert.Nil(t, saved.VerificationError)
+
+	count, err = v.VerifyPending(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 0, count)
+
+	// 失败的备份不可校验
+	_, err = v.VerifyRecord(context.Background(), failedRecord.ID)
+	assert.ErrorIs(t, err, ErrBackupNotVerifiable)
+}
+
+func TestVerifyRecordFromHTTP(t *testing.T) {
+	v, _ := setupVerifier(t)
+	path, _ := writeArtifact(t, t.TempDir(), sampleTable())
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/backups/backup.json.gz" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write(data)
+	}))
+	defer server.Close()
+
+	record := createRecord(t, v, server.URL+"/backups/", "backup.json.gz", nil)
+
+	result, err := v.VerifyRecord(context.Background(), record.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.BackupVerificationVerified, result.VerificationStatus)
+	assert.Equal(t, server.URL+"/backups/backup.json.gz", result.VerificationDetails["artifact_location"])
+}
+
+func TestTableChecksumIgnoresRowOrder(t *testing.T) {
+	table := sampleTable()
+	reversed := [][]*string{table.Rows[1], table.Rows[0]}
+	assert.Equal(t, TableChecksum(table.Rows), TableChecksum(reversed))
+
+	// 空值与空字符串不同
+	assert.NotEqual(t, TableChecksum([][]*string{{nil}}), TableChecksum([][]*string{{strPtr("")}}))
+}
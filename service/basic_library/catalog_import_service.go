@@ -0,0 +1,542 @@
+/*
+ * @module service/basic_library/catalog_import_service
+ * @description 从Excel系统台账批量导入目录，按系统创建草稿基础库和数据源，联系人和数据项写入元数据，由数据管理员补全后启用
+ * @architecture 分层架构 - 业务服务层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow 读取xlsx -> 按表头解析系统、联系人、数据项工作表 -> 逐行校验并按系统归集 -> (试导入时返回) -> 逐个系统创建基础库 -> 事务写入数据源和元数据，失败时删除该基础库
+ * @rules
+ *   - "系统"工作表必填，"联系人"和"数据项"工作表可省略；工作表和列按名称匹配，支持中英文表头，列顺序不限
+ *   - 系统编码即基础库英文名，为空时由系统名称转拼音生成；编码在台账中或已有基础库中重复的系统跳过
+ *   - 基础库和数据源以draft状态创建，数据源不校验连接配置、不注册到数据源管理器；接入地址按数据源类别写入base_url或host
+ *   - 每个系统生成一条management元数据（建设单位、接入地址和联系人），每个数据项生成一条business元数据，均关联到基础库
+ *   - 单行校验失败只跳过该行并记录问题，不影响其他行；联系人和数据项所属系统按系统编码或名称匹配台账中的系统
+ * @dependencies datahub-service/service/utils, datahub-service/service/meta, gorm.io/gorm
+ * @refs service/utils/xlsx.go, api/controllers/catalog_import_controller.go
+ */
+
+package basic_library
+
+import (
+	"context"
+	"datahub-service/service/meta"
+	"datahub-service/service/models"
+	"datahub-service/service/utils"
+	"errors"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// 台账工作表
+const (
+	CatalogSheetSystems   = "系统"
+	CatalogSheetContacts  = "联系人"
+	CatalogSheetDataItems = "数据项"
+)
+
+// CatalogDraftStatus 台账导入的基础库和数据源状态
+const CatalogDraftStatus = "draft"
+
+// ErrInvalidCatalogImport 台账文件不合法
+var ErrInvalidCatalogImport = errors.New("系统台账不合法")
+
+// catalogCodePattern 系统编码即基础库英文名和schema名
+var catalogCodePattern = regexp.MustCompile(`^[a-z][a-z0-9_]{0,62}$`)
+
+// catalogColumn 台账列，headers第一个为模板中的表头
+type catalogColumn struct {
+	key      string
+	headers  []string
+	required bool
+	example  string
+}
+
+// catalogSheet 台账工作表定义
+type catalogSheet struct {
+	names   []string
+	columns []catalogColumn
+}
+
+var (
+	catalogSystemSheet = catalogSheet{
+		names: []string{CatalogSheetSystems, "systems"},
+		columns: []catalogColumn{
+			{key: "code", headers: []string{"系统编码", "code"}, example: "parking_system"},
+			{key: "name", headers: []string{"系统名称", "name"}, required: true, example: "停车管理系统"},
+			{key: "description", headers: []string{"系统描述", "description"}, example: "园区停车场出入口和车位管理"},
+			{key: "vendor", headers: []string{"建设单位", "vendor"}, example: "某某科技有限公司"},
+			{key: "datasource_type", headers: []string{"数据源类型", "datasource_type"}, example: meta.DataSourceTypeApiHTTP},
+			{key: "address", headers: []string{"接入地址", "address"}, example: "http://10.0.1.20:8080"},
+		},
+	}
+	catalogContactSheet = catalogSheet{
+		names: []string{CatalogSheetContacts, "contacts"},
+		columns: []catalogColumn{
+			{key: "system", headers: []string{"所属系统", "system"}, required: true, example: "parking_system"},
+			{key: "name", headers: []string{"姓名", "name"}, required: true, example: "张三"},
+			{key: "role", headers: []string{"角色", "role"}, example: "系统负责人"},
+			{key: "phone", headers: []string{"电话", "phone"}, example: "13800000000"},
+			{key: "email", headers: []string{"邮箱", "email"}, example: "zhangsan@example.com"},
+		},
+	}
+	catalogDataItemSheet = catalogSheet{
+		names: []string{CatalogSheetDataItems, "data_items"},
+		columns: []catalogColumn{
+			{key: "system", headers: []string{"所属系统", "system"}, required: true, example: "parking_system"},
+			{key: "name", headers: []string{"数据项名称", "name"}, required: true, example: "车辆进出记录"},
+			{key: "name_en", headers: []string{"英文名", "name_en"}, example: "vehicle_access_records"},
+			{key: "description", headers: []string{"说明", "description"}, example: "车辆在出入口的进出时间和车牌"},
+			{key: "data_type", headers: []string{"数据类型", "data_type"}, example: "业务记录"},
+			{key: "update_frequency", headers: []string{"更新频率", "update_frequency"}, example: "实时"},
+			{key: "sensitive", headers: []string{"是否敏感", "sensitive"}, example: "是"},
+		},
+	}
+	// catalogSheets 按系统、联系人、数据项排列
+	catalogSheets = []catalogSheet{catalogSystemSheet, catalogContactSheet, catalogDataItemSheet}
+)
+
+// CatalogLibraryCreator 创建和删除基础库（含schema），由基础库服务实现
+type CatalogLibraryCreator interface {
+	CreateBasicLibrary(ctx context.Context, library *models.BasicLibrary) error
+	DeleteBasicLibrary(ctx context.Context, library *models.BasicLibrary) error
+}
+
+// CatalogImportIssue 台账中被跳过的行
+type CatalogImportIssue struct {
+	Sheet   string `json:"sheet"`
+	Row     int    `json:"row"` // Excel行号
+	Message string `json:"message"`
+}
+
+// CatalogImportSystem 台账中的系统及导入结果
+type CatalogImportSystem struct {
+	Row          int    `json:"row"`
+	Code         string `json:"code"`
+	Name         string `json:"name"`
+	LibraryID    string `json:"library_id,omitempty"`
+	DataSourceID string `json:"data_source_id,omitempty"`
+	Contacts     int    `json:"contacts"`
+	DataItems    int    `json:"data_items"`
+}
+
+// CatalogImportResult 台账导入结果，试导入时只校验不创建
+type CatalogImportResult struct {
+	DryRun      bool                  `json:"dry_run"`
+	Libraries   int                   `json:"libraries"`
+	DataSources int                   `json:"data_sources"`
+	Metadata    int                   `json:"metadata"`
+	Systems     []CatalogImportSystem `json:"systems"`
+	Issues      []CatalogImportIssue  `json:"issues"`
+}
+
+// catalogRow 工作表中的一行，按列key取值
+type catalogRow struct {
+	row    int
+	values map[string]string
+}
+
+// catalogSystem 校验通过的系统及其联系人和数据项
+type catalogSystem struct {
+	catalogRow
+	code      string
+	contacts  []catalogRow
+	dataItems []catalogRow
+}
+
+// CatalogImportService 系统台账导入服务
+type CatalogImportService struct {
+	db      *gorm.DB
+	creator CatalogLibraryCreator
+}
+
+// NewCatalogImportService 创建系统台账导入服务
+func NewCatalogImportService(db *gorm.DB, creator CatalogLibraryCreator) *CatalogImportService {
+	return &CatalogImportService{db: db, creator: creator}
+}
+
+// Template 生成台账导入模板，每个工作表包含表头和一行示例
+func (s *CatalogImportService) Template() ([]byte, error) {
+	sheets := make([]utils.XLSXSheet, 0, len(catalogSheets))
+	for _, sheet := range catalogSheets {
+		headers := make([]string, len(sheet.columns))
+		examples := make([]string, len(sheet.columns))
+		for i, column := range sheet.columns {
+			headers[i] = column.headers[0]
+			examples[i] = column.example
+		}
+		sheets = append(sheets, utils.XLSXSheet{Name: sheet.names[0], Rows: [][]string{headers, examples}})
+	}
+	return utils.WriteXLSX(sheets)
+}
+
+// Import 导入系统台账，dryRun为true时只校验并返回将要创建的系统
+func (s *CatalogImportService) Import(ctx context.Context, data []byte, dryRun bool, operator string) (*CatalogImportResult, error) {
+	workbook, err := utils.ReadXLSX(data)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCatalogImport, err)
+	}
+
+	result := &CatalogImportResult{DryRun: dryRun, Systems: []CatalogImportSystem{}, Issues: []CatalogImportIssue{}}
+	sheetRows := make([][]catalogRow, len(catalogSheets))
+	for i, sheet := range catalogSheets {
+		rows, issues, err := readCatalogSheet(workbook, sheet, i == 0)
+		if err != nil {
+			return nil, err
+		}
+		sheetRows[i] = rows
+		result.Issues = append(result.Issues, issues...)
+	}
+	systemRows, contactRows, dataItemRows := sheetRows[0], sheetRows[1], sheetRows[2]
+
+	systems, err := s.validateSystems(ctx, systemRows, result)
+	if err != nil {
+		return nil, err
+	}
+	attachCatalogRows(systems, contactRows, CatalogSheetContacts, result, func(system *catalogSystem, row catalogRow) {
+		system.contacts = append(system.contacts, row)
+	})
+	attachCatalogRows(systems, dataItemRows, CatalogSheetDataItems, result, func(system *catalogSystem, row catalogRow) {
+		system.dataItems = append(system.dataItems, row)
+	})
+
+	for _, system := range systems {
+		item := CatalogImportSystem{
+			Row:       system.row,
+			Code:      system.code,
+			Name:      system.values["name"],
+			Contacts:  len(system.contacts),
+			DataItems: len(system.dataItems),
+		}
+		if !dryRun {
+			library, dataSource, metadataCount, err := s.importSystem(ctx, system, operator)
+			if err != nil {
+				result.Issues = append(result.Issues, CatalogImportIssue{Sheet: CatalogSheetSystems, Row: system.row, Message: err.Error()})
+				continue
+			}
+			item.LibraryID = library.ID
+			item.DataSourceID = dataSource.ID
+			result.Metadata += metadataCount
+		} else {
+			result.Metadata += 1 + len(system.dataItems)
+		}
+		result.Libraries++
+		result.DataSources++
+		result.Systems = append(result.Systems, item)
+	}
+
+	if !dryRun {
+		slog.Info("系统台账导入完成", "libraries", result.Libraries, "metadata", result.Metadata,
+			"issues", len(result.Issues), "operator", operator)
+	}
+	return result, nil
+}
+
+// validateSystems 校验系统行，返回校验通过的系统，问题行记录到结果中
+func (s *CatalogImportService) validateSystems(ctx context.Context, rows []catalogRow, result *CatalogImportResult) ([]*catalogSystem, error) {
+	systems := make([]*catalogSystem, 0, len(rows))
+	codes := make([]string, 0, len(rows))
+	seen := make(map[string]int)
+	for _, row := range rows {
+		code := strings.ToLower(row.values["code"])
+		if code == "" {
+			if full, _, ok := utils.ToPinyin(row.values["name"]); ok {
+				code = full
+			}
+		}
+		var message string
+		switch {
+		case code == "":
+			message = "系统名称无法转换为编码，请填写系统编码"
+		case !catalogCodePattern.MatchString(code):
+			message = fmt.Sprintf("系统编码%s只能包含小写字母、数字和下划线，以字母开头且不超过63个字符", code)
+		case seen[code] > 0:
+			message = fmt.Sprintf("系统编码%s与第%d行重复", code, seen[code])
+		}
+		if dataSourceType := row.values["datasource_type"]; message == "" && dataSourceType != "" {
+			if _, ok := meta.DataSourceTypes[dataSourceType]; !ok {
+				message = fmt.Sprintf("不支持的数据源类型%s", dataSourceType)
+			}
+		}
+		if message != "" {
+			result.Issues = append(result.Issues, CatalogImportIssue{Sheet: CatalogSheetSystems, Row: row.row, Message: message})
+			continue
+		}
+		seen[code] = row.row
+		codes = append(codes, code)
+		systems = append(systems, &catalogSystem{catalogRow: row, code: code})
+	}
+
+	var existing []string
+	if len(codes) > 0 {
+		if err := s.db.WithContext(ctx).Model(&models.BasicLibrary{}).Where("name_en IN ?", codes).Pluck("name_en", &existing).Error; err != nil {
+			return nil, fmt.Errorf("查询基础库失败: %w", err)
+		}
+	}
+	if len(existing) == 0 {
+		return systems, nil
+	}
+	existingSet := toStringSet(existing)
+	valid := systems[:0]
+	for _, system := range systems {
+		if existingSet[system.code] {
+			result.Issues = append(result.Issues, CatalogImportIssue{Sheet: CatalogSheetSystems, Row: system.row,
+				Message: fmt.Sprintf("基础库英文名%s已存在", system.code)})
+			continue
+		}
+		valid = append(valid, system)
+	}
+	return valid, nil
+}
+
+// attachCatalogRows 将联系人或数据项按所属系统归集，所属系统按编码或名称匹配
+func attachCatalogRows(systems []*catalogSystem, rows []catalogRow, sheet string, result *CatalogImportResult, attach func(*catalogSystem, catalogRow)) {
+	bySystem := make(map[string]*catalogSystem, len(systems)*2)
+	for _, system := range systems {
+		bySystem[system.code] = system
+		bySystem[system.values["name"]] = system
+	}
+	for _, row := range rows {
+		ref := row.values["system"]
+		system, ok := bySystem[ref]
+		if !ok {
+			system, ok = bySystem[strings.ToLower(ref)]
+		}
+		if !ok {
+			result.Issues = append(result.Issues, CatalogImportIssue{Sheet: sheet, Row: row.row,
+				Message: fmt.Sprintf("所属系统%s不在本次导入的系统中", ref)})
+			continue
+		}
+		attach(system, row)
+	}
+}
+
+// importSystem 创建系统对应的草稿基础库、数据源和元数据，数据源或元数据写入失败时删除基础库
+func (s *CatalogImportService) importSystem(ctx context.Context, system *catalogSystem, operator string) (*models.BasicLibrary, *models.DataSource, int, error) {
+	library := &models.BasicLibrary{
+		ID:          uuid.New().String(),
+		NameZh:      system.values["name"],
+		NameEn:      system.code,
+		Description: system.values["description"],
+		Status:      CatalogDraftStatus,
+		CreatedBy:   operator,
+		UpdatedBy:   operator,
+	}
+	if err := s.creator.CreateBasicLibrary(ctx, library); err != nil {
+		return nil, nil, 0, fmt.Errorf("创建基础库失败: %w", err)
+	}
+
+	dataSource := buildCatalogDataSource(library, system, operator)
+	records := buildCatalogMetadata(library, system, operator)
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(dataSource).Error; err != nil {
+			return fmt.Errorf("创建数据源失败: %w", err)
+		}
+		if err := tx.Create(&records).Error; err != nil {
+			return fmt.Errorf("创建元数据失败: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		if deleteErr := s.creator.DeleteBasicLibrary(ctx, library); deleteErr != nil {
+			slog.Error("删除台账导入创建的基础库失败", "library_id", library.ID, "error", deleteErr)
+		}
+		return nil, nil, 0, err
+	}
+	return library, dataSource, len(records), nil
+}
+
+// buildCatalogDataSource 生成草稿数据源，接入地址按数据源类别写入连接配置
+func buildCatalogDataSource(library *models.BasicLibrary, system *catalogSystem, operator string) *models.DataSource {
+	dataSource := &models.DataSource{
+		ID:               uuid.New().String(),
+		LibraryID:        library.ID,
+		Name:             library.NameZh,
+		Type:             system.values["datasource_type"],
+		Status:           CatalogDraftStatus,
+		ConnectionConfig: models.JSONB{},
+		CreatedBy:        operator,
+		UpdatedBy:        operator,
+	}
+	if definition, ok := meta.DataSourceTypes[dataSource.Type]; ok {
+		dataSource.Category = definition.Category
+	}
+	if address := system.values["address"]; address != "" {
+		if dataSource.Category == meta.DataSourceCategoryAPI {
+			dataSource.ConnectionConfig[meta.DataSourceFieldBaseUrl] = address
+		} else {
+			dataSource.ConnectionConfig[meta.DataSourceFieldHost] = address
+		}
+	}
+	return dataSource
+}
+
+// buildCatalogMetadata 生成系统的管理元数据和各数据项的业务元数据
+func buildCatalogMetadata(library *models.BasicLibrary, system *catalogSystem, operator string) []models.Metadata {
+	objectType := "basic_library"
+	contacts := make([]interface{}, 0, len(system.contacts))
+	for _, contact := range system.contacts {
+		contacts = append(contacts, catalogRowContent(contact, "system"))
+	}
+	content := catalogRowContent(system.catalogRow, "code", "name", "description")
+	content["system_code"] = system.code
+	content["contacts"] = contacts
+	content["imported_at"] = time.Now().Format(time.RFC3339)
+
+	records := make([]models.Metadata, 0, 1+len(system.dataItems))
+	records = append(records, models.Metadata{
+		ID:                uuid.New().String(),
+		Type:              "management",
+		Name:              library.NameZh + "台账",
+		Content:           content,
+		RelatedObjectID:   &library.ID,
+		RelatedObjectType: &objectType,
+		CreatedBy:         operator,
+		UpdatedBy:         operator,
+	})
+	for _, item := range system.dataItems {
+		itemContent := catalogRowContent(item, "system", "name", "sensitive")
+		if sensitive := item.values["sensitive"]; sensitive != "" {
+			itemContent["sensitive"] = parseCatalogBool(sensitive)
+		}
+		records = append(records, models.Metadata{
+			ID:                uuid.New().String(),
+			Type:              "business",
+			Name:              item.values["name"],
+			Content:           itemContent,
+			RelatedObjectID:   &library.ID,
+			RelatedObjectType: &objectType,
+			CreatedBy:         operator,
+			UpdatedBy:         operator,
+		})
+	}
+	return records
+}
+
+// catalogRowContent 将一行的非空值转换为元数据内容，排除指定的列
+func catalogRowContent(row catalogRow, exclude ...string) models.JSONB {
+	content := models.JSONB{}
+	for key, value := range row.values {
+		if value != "" {
+			content[key] = value
+		}
+	}
+	for _, key := range exclude {
+		delete(content, key)
+	}
+	return content
+}
+
+// parseCatalogBool 解析台账中的是/否
+func parseCatalogBool(value string) bool {
+	switch strings.ToLower(value) {
+	case "是", "y", "yes", "true", "1":
+		return true
+	}
+	return false
+}
+
+// readCatalogSheet 按表头读取工作表，表头为第一个非空行，必填列为空的行作为问题返回；工作表不存在时required为true报错，否则返回空
+func readCatalogSheet(workbook []utils.XLSXSheet, sheet catalogSheet, required bool) ([]catalogRow, []CatalogImportIssue, error) {
+	var found *utils.XLSXSheet
+	for i := range workbook {
+		for _, name := range sheet.names {
+			if strings.EqualFold(strings.TrimSpace(workbook[i].Name), name) {
+				found = &workbook[i]
+			}
+		}
+		if found != nil {
+			break
+		}
+	}
+	if found == nil {
+		if required {
+			return nil, nil, fmt.Errorf("%w: 缺少工作表%s", ErrInvalidCatalogImport, sheet.names[0])
+		}
+		return nil, nil, nil
+	}
+
+	headerIndex := -1
+	for i, row := range found.Rows {
+		if !isBlankRow(row) {
+			headerIndex = i
+			break
+		}
+	}
+	if headerIndex < 0 {
+		return nil, nil, nil
+	}
+
+	columnIndex := make(map[string]int, len(sheet.columns))
+	for i, header := range found.Rows[headerIndex] {
+		normalized := strings.ToLower(strings.Trim(strings.TrimSpace(header), "*"))
+		for _, column := range sheet.columns {
+			for _, name := range column.headers {
+				if _, exists := columnIndex[column.key]; !exists && normalized == strings.ToLower(name) {
+					columnIndex[column.key] = i
+				}
+			}
+		}
+	}
+	for _, column := range sheet.columns {
+		if _, ok := columnIndex[column.key]; column.required && !ok {
+			return nil, nil, fmt.Errorf("%w: 工作表%s缺少列%s", ErrInvalidCatalogImport, found.Name, column.headers[0])
+		}
+	}
+
+	var rows []catalogRow
+	for i := headerIndex + 1; i < len(found.Rows); i++ {
+		if isBlankRow(found.Rows[i]) {
+			continue
+		}
+		values := make(map[string]string, len(columnIndex))
+		for key, index := range columnIndex {
+			if index < len(found.Rows[i]) {
+				values[key] = strings.TrimSpace(found.Rows[i][index])
+			}
+		}
+		rows = append(rows, catalogRow{row: i + 1, values: values})
+	}
+
+	// 必填列为空的行跳过
+	valid := rows[:0]
+	var issues []CatalogImportIssue
+	for _, row := range rows {
+		missing := ""
+		for _, column := range sheet.columns {
+			if column.required && row.values[column.key] == "" {
+				missing = column.headers[0]
+				break
+			}
+		}
+		if missing != "" {
+			issues = append(issues, CatalogImportIssue{Sheet: sheet.names[0], Row: row.row, Message: "缺少" + missing})
+			continue
+		}
+		valid = append(valid, row)
+	}
+	return valid, issues, nil
+}
+
+// isBlankRow 判断是否为空行
+func isBlankRow(row []string) bool {
+	for _, value := range row {
+		if strings.TrimSpace(value) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// toStringSet 字符串切片转集合
+func toStringSet(values []string) map[string]bool {
+	set := make(map[string]bool, len(values))
+	for _, value := range values {
+		set[value] = true
+	}
+	return set
+}
@@ -0,0 +1,181 @@
+/*
+ * @module service/basic_library/catalog_import_service_test
+ * @description 系统台账导入测试，覆盖模板可读回、试导入、草稿基础库/数据源/元数据的创建、问题行跳过以及写入失败时删除基础库
+ * @architecture 测试层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow 生成xlsx台账 -> 导入 -> 验证sqlite中的基础库、数据源、元数据和问题清单
+ * @rules 使用内存sqlite，基础库的创建和删除由写入sqlite的模拟实现代替，不建真实schema
+ * @dependencies gorm.io/driver/sqlite, stretchr/testify
+ * @refs catalog_import_service.go, service/utils/xlsx.go
+ */
+
+package basic_library
+
+import (
+	"context"
+	"datahub-service/service/models"
+	"datahub-service/service/utils"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// fakeLibraryCreator 把基础库写入sqlite，记录删除的基础库
+type fakeLibraryCreator struct {
+	db      *gorm.DB
+	deleted []string
+}
+
+func (f *fakeLibraryCreator) CreateBasicLibrary(ctx context.Context, library *models.BasicLibrary) error {
+	return f.db.Create(library).Error
+}
+
+func (f *fakeLibraryCreator) DeleteBasicLibrary(ctx context.Context, library *models.BasicLibrary) error {
+	f.deleted = append(f.deleted, library.NameEn)
+	return f.db.Delete(library).Error
+}
+
+func setupCatalogImport(t *testing.T) (*CatalogImportService, *fakeLibraryCreator, *gorm.DB) {
+	db, _ := setupDiagnosticsDB(t)
+	require.NoError(t, db.AutoMigrate(&models.BasicLibrary{}, &models.Metadata{}))
+	require.NoError(t, db.Create(&models.BasicLibrary{ID: "lib-existing", NameZh: "能耗系统", NameEn: "energy"}).Error)
+	creator := &fakeLibraryCreator{db: db}
+	return NewCatalogImportService(db, creator), creator, db
+}
+
+func catalogWorkbook(t *testing.T) []byte {
+	content, err := utils.WriteXLSX([]utils.XLSXSheet{
+		{Name: "系统", Rows: [][]string{
+			{"系统名称", "系统编码*", "数据源类型", "接入地址", "建设单位"},
+			{"停车管理系统", "parking", "http", "http://10.0.1.20:8080", "某某科技"},
+			{"门禁系统", "", "postgresql", "10.0.1.30"},
+			nil,
+			{"能耗监测", "energy"},
+			{"视频监控", "video", "ftp"},
+			{"", "no_name"},
+		}},
+		{Name: "联系人", Rows: [][]string{
+			{"所属系统", "姓名", "角色", "电话"},
+			{"parking", "张三", "系统负责人", "13800000000"},
+			{"门禁系统", "李四", "运维"},
+			{"energy", "王五"},
+		}},
+		{Name: "数据项", Rows: [][]string{
+			{"所属系统", "数据项名称", "英文名", "是否敏感"},
+			{"停车管理系统", "车辆进出记录", "vehicle_access", "是"},
+			{"parking", "车位状态", "", "否"},
+			{"parking", ""},
+		}},
+	})
+	require.NoError(t, err)
+	return content
+}
+
+func TestCatalogImportTemplate(t *testing.T) {
+	svc, _, _ := setupCatalogImport(t)
+	content, err := svc.Template()
+	require.NoError(t, err)
+
+	result, err := svc.Import(context.Background(), content, true, "curator")
+	require.NoError(t, err, "模板本身可以导入")
+	assert.Empty(t, result.Issues)
+	require.Len(t, result.Systems, 1)
+	assert.Equal(t, "parking_system", result.Systems[0].Code)
+	assert.Equal(t, 1, result.Systems[0].Contacts)
+	assert.Equal(t, 1, result.Systems[0].DataItems)
+}
+
+func TestCatalogImport(t *testing.T) {
+	svc, _, db := setupCatalogImport(t)
+	ctx := context.Background()
+
+	preview, err := svc.Import(ctx, catalogWorkbook(t), true, "curator")
+	require.NoError(t, err)
+	assert.True(t, preview.DryRun)
+	assert.Equal(t, 2, preview.Libraries)
+	assert.Equal(t, 4, preview.Metadata, "每个系统一条管理元数据，每个数据项一条业务元数据")
+	var count int64
+	db.Model(&models.BasicLibrary{}).Count(&count)
+	assert.Equal(t, int64(1), count, "试导入不创建")
+
+	result, err := svc.Import(ctx, catalogWorkbook(t), false, "curator")
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.Libraries)
+	assert.Equal(t, 2, result.DataSources)
+	assert.Equal(t, 4, result.Metadata)
+	require.Len(t, result.Systems, 2)
+	assert.Equal(t, "menjinxitong", result.Systems[1].Code, "未填写编码时由名称转拼音")
+
+	issues := map[string]int{}
+	for _, issue := range result.Issues {
+		issues[issue.Sheet]++
+	}
+	assert.Equal(t, map[string]int{"系统": 3, "联系人": 1, "数据项": 1}, issues, "已存在、数据源类型不支持和缺少名称的系统，以及所属系统被跳过的联系人和缺少名称的数据项")
+
+	var library models.BasicLibrary
+	require.NoError(t, db.First(&library, "name_en = ?", "parking").Error)
+	assert.Equal(t, CatalogDraftStatus, library.Status)
+	assert.Equal(t, "curator", library.CreatedBy)
+
+	var dataSource models.DataSource
+	require.NoError(t, db.First(&dataSource, "library_id = ?", library.ID).Error)
+	assert.Equal(t, CatalogDraftStatus, dataSource.Status)
+	assert.Equal(t, "api", dataSource.Category)
+	assert.Equal(t, "http://10.0.1.20:8080", dataSource.ConnectionConfig["base_url"])
+	var accessSource models.DataSource
+	require.NoError(t, db.First(&accessSource, "id = ?", result.Systems[1].DataSourceID).Error)
+	assert.Equal(t, "10.0.1.30", accessSource.ConnectionConfig["host"], "数据库类数据源的地址写入host")
+
+	var records []models.Metadata
+	require.NoError(t, db.Where("related_object_id = ?", library.ID).Order("type").Find(&records).Error)
+	require.Len(t, records, 3)
+	assert.Equal(t, "business", records[0].Type)
+	management := records[2]
+	assert.Equal(t, "management", management.Type)
+	assert.Equal(t, "停车管理系统台账", management.Name)
+	assert.Equal(t, "某某科技", management.Content["vendor"])
+	contacts := management.Content["contacts"].([]interface{})
+	require.Len(t, contacts, 1)
+	assert.Equal(t, "张三", contacts[0].(map[string]interface{})["name"])
+
+	var item models.Metadata
+	require.NoError(t, db.First(&item, "name = ?", "车辆进出记录").Error)
+	assert.Equal(t, true, item.Content["sensitive"])
+	assert.Equal(t, "vehicle_access", item.Content["name_en"])
+
+	again, err := svc.Import(ctx, catalogWorkbook(t), false, "curator")
+	require.NoError(t, err)
+	assert.Zero(t, again.Libraries, "重复导入时基础库已存在")
+}
+
+func TestCatalogImportRollback(t *testing.T) {
+	svc, creator, db := setupCatalogImport(t)
+	require.NoError(t, db.Migrator().DropTable(&models.Metadata{}))
+
+	result, err := svc.Import(context.Background(), catalogWorkbook(t), false, "curator")
+	require.NoError(t, err)
+	assert.Zero(t, result.Libraries)
+	assert.Equal(t, []string{"parking", "menjinxitong"}, creator.deleted, "元数据写入失败时删除已创建的基础库")
+	var count int64
+	db.Model(&models.DataSource{}).Where("status = ?", CatalogDraftStatus).Count(&count)
+	assert.Zero(t, count, "数据源随事务回滚")
+}
+
+func TestCatalogImportInvalidWorkbook(t *testing.T) {
+	svc, _, _ := setupCatalogImport(t)
+
+	_, err := svc.Import(context.Background(), []byte("not xlsx"), true, "curator")
+	assert.ErrorIs(t, err, ErrInvalidCatalogImport)
+
+	content, err := utils.WriteXLSX([]utils.XLSXSheet{{Name: "联系人", Rows: [][]string{{"所属系统", "姓名"}}}})
+	require.NoError(t, err)
+	_, err = svc.Import(context.Background(), content, true, "curator")
+	assert.ErrorIs(t, err, ErrInvalidCatalogImport, "缺少系统工作表")
+
+	content, err = utils.WriteXLSX([]utils.XLSXSheet{{Name: "systems", Rows: [][]string{{"code", "description"}, {"parking"}}}})
+	require.NoError(t, err)
+	_, err = svc.Import(context.Background(), content, true, "curator")
+	assert.ErrorIs(t, err, ErrInvalidCatalogImport, "缺少系统名称列")
+}
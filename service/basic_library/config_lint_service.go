@@ -0,0 +1,421 @@
+/*
+ * @module service/basic_library/config_lint_service
+ * @description 接口配置检查，在首次同步前静态检查接口配置、解析配置和表字段配置之间是否一致，返回结构化的问题列表
+ * @architecture 分层架构 - 业务服务层，检查逻辑为纯函数
+ * @documentReference ai_docs/interfaces.md
+ * @stateFlow 查询接口和数据源 -> 检查请求配置和分页参数 -> 检查表字段 -> 检查字段映射目标 -> 检查增量字段 -> 按级别汇总
+ * @rules 只读检查，不访问源系统和接口表；error级问题会导致同步失败或写入失败，warning级问题会导致结果不符合预期，info级为提示；
+ *        字段映射支持数组格式和旧的对象格式；增量同步按parseConfig.field_mapping（没有时按增量字段原名）在接口表中查询最近同步值，
+ *        该列须存在且类型可比较（时间、数值，字符串按字典序比较给出警告）
+ * @dependencies datahub-service/service/meta, datahub-service/service/models, datahub-service/service/tenant, gorm.io/gorm
+ * @refs service/interface_executor/execute_operations.go, service/interface_executor/field_mapping.go, service/datasource/query_builder.go, api/controllers/config_lint_controller.go
+ */
+
+package basic_library
+
+import (
+	"context"
+	"datahub-service/service/meta"
+	"datahub-service/service/models"
+	"datahub-service/service/tenant"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cast"
+	"gorm.io/gorm"
+)
+
+// 配置问题级别
+const (
+	ConfigFindingError   = "error"   // 同步会失败或写入失败
+	ConfigFindingWarning = "warning" // 同步可执行但结果不符合预期
+	ConfigFindingInfo    = "info"    // 提示
+)
+
+// 表字段类型分类，用于判断增量字段是否可比较
+const (
+	fieldTypeClassTemporal = "temporal"
+	fieldTypeClassNumeric  = "numeric"
+	fieldTypeClassString   = "string"
+	fieldTypeClassOther    = "other" // 布尔、JSON、UUID等不可比较的类型
+	fieldTypeClassUnknown  = "unknown"
+)
+
+// ConfigFinding 配置检查发现的问题
+type ConfigFinding struct {
+	Level   string `json:"level"`   // error, warning, info
+	Code    string `json:"code"`    // 问题代码，如 pagination_param_missing
+	Path    string `json:"path"`    // 问题所在配置项，如 interface_config.pagination_page_param
+	Message string `json:"message"` // 问题说明
+}
+
+// ConfigLintResult 接口配置检查结果
+type ConfigLintResult struct {
+	InterfaceID string          `json:"interface_id"`
+	Valid       bool            `json:"valid"` // 没有error级问题
+	Errors      int             `json:"errors"`
+	Warnings    int             `json:"warnings"`
+	Findings    []ConfigFinding `json:"findings"` // 按error、warning、info排列
+	CheckedAt   time.Time       `json:"checked_at"`
+}
+
+// ConfigLintService 接口配置检查服务
+type ConfigLintService struct {
+	db *gorm.DB
+}
+
+// NewConfigLintService 创建接口配置检查服务
+func NewConfigLintService(db *gorm.DB) *ConfigLintService {
+	return &ConfigLintService{db: db}
+}
+
+// Lint 检查接口的请求配置、字段映射、表字段和增量配置是否一致
+func (s *ConfigLintService) Lint(ctx context.Context, interfaceID string) (*ConfigLintResult, error) {
+	var iface models.DataInterface
+	if err := s.db.WithContext(ctx).Scopes(tenant.OwnerScope(ctx, "library_id", "basic_libraries")).
+		Preload("DataSource").First(&iface, "id = ?", interfaceID).Error; err != nil {
+		return nil, err
+	}
+
+	findings := lintInterfaceConfig(&iface)
+	result := &ConfigLintResult{InterfaceID: iface.ID, Findings: findings, CheckedAt: time.Now()}
+	for _, finding := range findings {
+		switch finding.Level {
+		case ConfigFindingError:
+			result.Errors++
+		case ConfigFindingWarning:
+			result.Warnings++
+		}
+	}
+	result.Valid = result.Errors == 0
+	return result, nil
+}
+
+// configLinter 收集一次检查中发现的问题
+type configLinter struct {
+	findings []ConfigFinding
+}
+
+func (l *configLinter) add(level, code, path, format string, args ...interface{}) {
+	l.findings = append(l.findings, ConfigFinding{Level: level, Code: code, Path: path, Message: fmt.Sprintf(format, args...)})
+}
+
+// lintInterfaceConfig 检查接口配置，iface.DataSource须已加载
+func lintInterfaceConfig(iface *models.DataInterface) []ConfigFinding {
+	l := &configLinter{}
+	config := map[string]interface{}(iface.InterfaceConfig)
+	if config == nil {
+		config = map[string]interface{}{}
+	}
+
+	if iface.DataSourceID == "" || iface.DataSource.ID == "" {
+		l.add(ConfigFindingError, "datasource_missing", "data_source_id", "接口未关联数据源或数据源不存在")
+	}
+	switch iface.DataSource.Category {
+	case meta.DataSourceCategoryAPI:
+		l.lintAPIRequest(config)
+		l.lintPagination(config)
+	case meta.DataSourceCategoryDatabase:
+		l.lintDatabaseQuery(config)
+	}
+
+	fields := sortedTableFields(iface.TableFieldsConfig)
+	columns := l.lintTableFields(fields)
+	if !iface.IsTableCreated {
+		l.add(ConfigFindingWarning, "table_not_created", "is_table_created", "接口表尚未创建，同步会失败，请先保存表字段并建表")
+	}
+	mappedTargets := l.lintFieldMapping(iface.ParseConfig, columns)
+	l.lintIncremental(config, iface.ParseConfig, columns, mappedTargets)
+
+	sort.SliceStable(l.findings, func(i, j int) bool {
+		return configFindingRank(l.findings[i].Level) < configFindingRank(l.findings[j].Level)
+	})
+	if l.findings == nil {
+		return []ConfigFinding{}
+	}
+	return l.findings
+}
+
+// lintAPIRequest 检查API接口的请求方法和地址
+func (l *configLinter) lintAPIRequest(config map[string]interface{}) {
+	method := strings.ToUpper(cast.ToString(config[meta.DataInterfaceConfigFieldMethod]))
+	switch method {
+	case "":
+		l.add(ConfigFindingError, "method_missing", "interface_config.method", "未配置请求方法")
+	case "GET", "POST", "PUT", "DELETE":
+	default:
+		l.add(ConfigFindingError, "method_invalid", "interface_config.method", "不支持的请求方法%s，可选GET、POST、PUT、DELETE", method)
+	}
+	if cast.ToString(config[meta.DataInterfaceConfigFieldUrlSuffix]) == "" && cast.ToString(config[meta.DataInterfaceConfigFieldUrlPattern]) == "" {
+		l.add(ConfigFindingWarning, "url_missing", "interface_config.url_suffix", "未配置URL后缀，将直接请求数据源的基础地址")
+	}
+}
+
+// lintPagination 检查启用分页时分页参数是否完整
+func (l *configLinter) lintPagination(config map[string]interface{}) {
+	if !cast.ToBool(config[meta.DataInterfaceConfigFieldPaginationEnabled]) {
+		return
+	}
+	for _, key := range []string{meta.DataInterfaceConfigFieldPaginationPageParam, meta.DataInterfaceConfigFieldPaginationSizeParam} {
+		if strings.TrimSpace(cast.ToString(config[key])) == "" {
+			l.add(ConfigFindingError, "pagination_param_missing", "interface_config."+key, "已启用分页但未配置%s", key)
+		}
+	}
+	location := cast.ToString(config[meta.DataInterfaceConfigFieldPaginationParamLocation])
+	switch location {
+	case "", "query":
+	case "body":
+		method := strings.ToUpper(cast.ToString(config[meta.DataInterfaceConfigFieldMethod]))
+		if method == "GET" || method == "DELETE" {
+			l.add(ConfigFindingWarning, "pagination_location_mismatch", "interface_config."+meta.DataInterfaceConfigFieldPaginationParamLocation,
+				"分页参数放在请求体中，但请求方法为%s，源系统通常不读取该方法的请求体", method)
+		}
+	default:
+		l.add(ConfigFindingError, "pagination_location_invalid", "interface_config."+meta.DataInterfaceConfigFieldPaginationParamLocation,
+			"分页参数位置%s无效，可选query或body", location)
+	}
+	if value, exists := config[meta.DataInterfaceConfigFieldPaginationDefaultSize]; exists {
+		if size := cast.ToInt(value); size < 1 || size > 1000 {
+			l.add(ConfigFindingError, "pagination_size_invalid", "interface_config."+meta.DataInterfaceConfigFieldPaginationDefaultSize,
+				"每页条数%v须在1-1000之间", value)
+		}
+	}
+	if value, exists := config[meta.DataInterfaceConfigFieldPaginationStartValue]; exists && cast.ToInt(value) < 0 {
+		l.add(ConfigFindingError, "pagination_start_invalid", "interface_config."+meta.DataInterfaceConfigFieldPaginationStartValue,
+			"起始页码%v不能为负数", value)
+	}
+}
+
+// lintDatabaseQuery 检查数据库接口的表名和自定义SQL
+func (l *configLinter) lintDatabaseQuery(config map[string]interface{}) {
+	queryType := cast.ToString(config[meta.DataInterfaceConfigFieldQueryType])
+	switch queryType {
+	case "", "select":
+		if strings.TrimSpace(cast.ToString(config[meta.DataInterfaceConfigFieldTableName])) == "" {
+			l.add(ConfigFindingError, "table_name_missing", "interface_config.table_name", "未配置源表名")
+		}
+	case "custom", "procedure", "function":
+		if strings.TrimSpace(cast.ToString(config[meta.DataInterfaceConfigFieldCustomSQL])) == "" {
+			l.add(ConfigFindingError, "custom_sql_missing", "interface_config.custom_sql", "查询类型为%s时须配置自定义SQL", queryType)
+		}
+	default:
+		l.add(ConfigFindingError, "query_type_invalid", "interface_config.query_type", "查询类型%s无效，可选select、custom、procedure、function", queryType)
+	}
+}
+
+// lintTableFields 检查表字段，返回按英文名索引的字段
+func (l *configLinter) lintTableFields(fields []models.TableField) map[string]models.TableField {
+	columns := make(map[string]models.TableField, len(fields))
+	if len(fields) == 0 {
+		l.add(ConfigFindingError, "table_fields_missing", "table_fields_config", "未配置表字段，数据无处写入")
+		return columns
+	}
+
+	primaryKeys := 0
+	for _, field := range fields {
+		if _, exists := columns[field.NameEn]; exists {
+			l.add(ConfigFindingError, "table_field_duplicate", "table_fields_config."+field.NameEn, "表字段%s重复", field.NameEn)
+			continue
+		}
+		columns[field.NameEn] = field
+		if field.IsPrimaryKey {
+			primaryKeys++
+		}
+		if classifyFieldType(field.DataType) == fieldTypeClassUnknown {
+			l.add(ConfigFindingWarning, "table_field_type_unknown", "table_fields_config."+field.NameEn,
+				"表字段%s的类型%s无法识别，建表时按varchar(255)处理", field.NameEn, field.DataType)
+		}
+	}
+	if primaryKeys == 0 {
+		l.add(ConfigFindingWarning, "primary_key_missing", "table_fields_config",
+			"表字段中没有主键，重复同步时无法按主键更新已有数据")
+	}
+	return columns
+}
+
+// lintFieldMapping 检查字段映射的源字段和目标字段，返回源字段到目标字段的映射
+func (l *configLinter) lintFieldMapping(parseConfig models.JSONB, columns map[string]models.TableField) map[string]string {
+	sourceToTarget := map[string]string{}
+	raw, exists := parseConfig["fieldMapping"]
+	if !exists || raw == nil {
+		l.add(ConfigFindingInfo, "field_mapping_missing", "parse_config.fieldMapping", "未配置字段映射，源数据字段按原名写入接口表")
+		return sourceToTarget
+	}
+
+	targetCount := map[string]int{}
+	checkTarget := func(path, source, target string) {
+		if target == "" {
+			l.add(ConfigFindingError, "mapping_target_missing", path, "源字段%s的映射未配置目标字段", source)
+			return
+		}
+		targetCount[target]++
+		if _, ok := columns[target]; !ok && len(columns) > 0 {
+			l.add(ConfigFindingError, "mapping_target_not_in_table", path, "映射目标字段%s在接口表字段中不存在", target)
+		}
+	}
+
+	switch mapping := raw.(type) {
+	case []interface{}:
+		for i, item := range mapping {
+			path := fmt.Sprintf("parse_config.fieldMapping[%d]", i)
+			entry, ok := item.(map[string]interface{})
+			if !ok {
+				l.add(ConfigFindingError, "mapping_entry_invalid", path, "字段映射条目须为包含source和target的对象")
+				continue
+			}
+			source := cast.ToString(entry["source"])
+			target := cast.ToString(entry["target"])
+			transforms, _ := entry["transforms"].([]interface{})
+			if source == "" && len(transforms) == 0 {
+				l.add(ConfigFindingError, "mapping_source_missing", path, "字段映射未配置源字段")
+				continue
+			}
+			checkTarget(path, source, target)
+			if source != "" && target != "" {
+				sourceToTarget[source] = target
+			}
+		}
+	case map[string]interface{}:
+		for source, value := range mapping {
+			target := cast.ToString(value)
+			checkTarget("parse_config.fieldMapping."+source, source, target)
+			if target != "" {
+				sourceToTarget[source] = target
+			}
+		}
+	default:
+		l.add(ConfigFindingError, "mapping_format_invalid", "parse_config.fieldMapping", "字段映射格式不支持，须为数组或对象，映射不会生效")
+		return sourceToTarget
+	}
+
+	duplicates := make([]string, 0)
+	for target, count := range targetCount {
+		if count > 1 {
+			duplicates = append(duplicates, target)
+		}
+	}
+	sort.Strings(duplicates)
+	for _, target := range duplicates {
+		l.add(ConfigFindingWarning, "mapping_target_duplicate", "parse_config.fieldMapping",
+			"有%d个源字段映射到%s，后处理的值会覆盖先处理的值", targetCount[target], target)
+	}
+
+	if len(sourceToTarget) > 0 {
+		for _, name := range sortedColumnNames(columns) {
+			column := columns[name]
+			if targetCount[name] == 0 && !column.IsNullable && !column.IsPrimaryKey && column.DefaultValue == "" && column.Expression == "" {
+				l.add(ConfigFindingWarning, "required_column_unmapped", "table_fields_config."+name,
+					"非空字段%s没有映射来源也没有默认值，写入时会违反非空约束", name)
+			}
+		}
+	}
+	return sourceToTarget
+}
+
+// lintIncremental 检查增量配置的增量字段在接口表中是否存在且可比较
+func (l *configLinter) lintIncremental(config map[string]interface{}, parseConfig models.JSONB, columns map[string]models.TableField, sourceToTarget map[string]string) {
+	incrementalConfig, ok := config[meta.DataInterfaceConfigFieldIncrementalConfig].(map[string]interface{})
+	if !ok || !cast.ToBool(incrementalConfig["enabled"]) {
+		return
+	}
+	const path = "interface_config.incremental_config"
+
+	sourceField := cast.ToString(incrementalConfig["incremental_field"])
+	if sourceField == "" {
+		sourceField = cast.ToString(incrementalConfig["increment_field"])
+	}
+	if sourceField == "" {
+		l.add(ConfigFindingError, "incremental_field_missing", path+".incremental_field", "已启用增量同步但未配置增量字段")
+		return
+	}
+
+	fieldType := cast.ToString(incrementalConfig["field_type"])
+	switch fieldType {
+	case "", "timestamp", "number", "string":
+	default:
+		l.add(ConfigFindingError, "incremental_type_invalid", path+".field_type", "增量字段类型%s无效，可选timestamp、number、string", fieldType)
+	}
+
+	// 同步时按parseConfig.field_mapping查找接口表中的列，没有映射时按增量字段原名
+	column := sourceField
+	if legacy, ok := parseConfig["field_mapping"].(map[string]interface{}); ok {
+		if mapped := cast.ToString(legacy[sourceField]); mapped != "" {
+			column = mapped
+		}
+	}
+	field, exists := columns[column]
+	if !exists {
+		if len(columns) == 0 {
+			return
+		}
+		message := fmt.Sprintf("增量字段%s在接口表中不存在，无法查询最近同步值，每次同步都会退化为全量同步", column)
+		if target := sourceToTarget[sourceField]; target != "" && target != column {
+			message += fmt.Sprintf("（字段映射目标为%s，增量字段须与接口表字段同名）", target)
+		}
+		l.add(ConfigFindingError, "incremental_field_not_in_table", path+".incremental_field", "%s", message)
+		return
+	}
+
+	class := classifyFieldType(field.DataType)
+	switch class {
+	case fieldTypeClassOther:
+		l.add(ConfigFindingError, "incremental_field_not_comparable", "table_fields_config."+column,
+			"增量字段%s的类型%s不可比较，无法按最近同步值增量查询", column, field.DataType)
+		return
+	case fieldTypeClassString:
+		l.add(ConfigFindingWarning, "incremental_field_string", "table_fields_config."+column,
+			"增量字段%s的类型为%s，最近同步值按字符串字典序比较，须保证取值格式定长且可排序", column, field.DataType)
+	}
+	expected := map[string]string{"timestamp": fieldTypeClassTemporal, "number": fieldTypeClassNumeric}[fieldType]
+	if expected != "" && class != expected && class != fieldTypeClassUnknown {
+		l.add(ConfigFindingWarning, "incremental_type_mismatch", path+".field_type",
+			"增量字段类型为%s，但接口表字段%s的类型为%s，增量参数的格式可能与源系统不一致", fieldType, column, field.DataType)
+	}
+	if !field.IsIndexed && !field.IsPrimaryKey {
+		l.add(ConfigFindingInfo, "incremental_field_not_indexed", "table_fields_config."+column,
+			"增量字段%s未建索引，每次同步查询最近同步值需要扫描全表", column)
+	}
+}
+
+// classifyFieldType 按表字段类型判断是否可比较
+func classifyFieldType(dataType string) string {
+	t := strings.ToLower(strings.TrimSpace(dataType))
+	switch {
+	case t == "":
+		return fieldTypeClassUnknown
+	case strings.Contains(t, "timestamp"), strings.Contains(t, "date"), strings.HasPrefix(t, "time"):
+		return fieldTypeClassTemporal
+	case strings.Contains(t, "int"), strings.Contains(t, "serial"), strings.Contains(t, "numeric"), strings.Contains(t, "decimal"),
+		strings.Contains(t, "float"), strings.Contains(t, "real"), strings.Contains(t, "double"):
+		return fieldTypeClassNumeric
+	case strings.Contains(t, "char"), strings.Contains(t, "text"), t == "string":
+		return fieldTypeClassString
+	case strings.HasPrefix(t, "bool"), strings.HasPrefix(t, "json"), t == "uuid", t == "inet", t == "cidr", t == "macaddr", t == "bytea",
+		models.IsGeoDataType(t):
+		return fieldTypeClassOther
+	}
+	return fieldTypeClassUnknown
+}
+
+// sortedColumnNames 返回按名称排序的表字段名
+func sortedColumnNames(columns map[string]models.TableField) []string {
+	names := make([]string, 0, len(columns))
+	for name := range columns {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// configFindingRank 问题级别的排序值
+func configFindingRank(level string) int {
+	switch level {
+	case ConfigFindingError:
+		return 0
+	case ConfigFindingWarning:
+		return 1
+	}
+	return 2
+}
@@ -0,0 +1,141 @@
+/*
+ * @module service/basic_library/config_lint_service_test
+ * @description 接口配置检查测试，覆盖分页参数、映射目标、增量字段存在性和可比较性以及查询接口的检查结果汇总
+ * @architecture 测试层
+ * @documentReference ai_docs/interfaces.md
+ * @stateFlow 构造接口配置 -> 执行检查 -> 验证问题代码和级别
+ * @rules 检查逻辑为纯函数，直接构造接口；查询接口使用内存sqlite
+ * @dependencies gorm.io/driver/sqlite, stretchr/testify
+ * @refs config_lint_service.go
+ */
+
+package basic_library
+
+import (
+	"context"
+	"datahub-service/service/meta"
+	"datahub-service/service/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// lintTestInterface 构造一个配置完整的API接口
+func lintTestInterface() *models.DataInterface {
+	return &models.DataInterface{
+		ID: "if-lint", DataSourceID: "ds-1", IsTableCreated: true,
+		DataSource: models.DataSource{ID: "ds-1", Category: meta.DataSourceCategoryAPI},
+		InterfaceConfig: models.JSONB{
+			"method": "GET", "url_suffix": "/records",
+			"pagination_enabled": true, "pagination_page_param": "page", "pagination_size_param": "size",
+			"incremental_config": map[string]interface{}{"enabled": true, "incremental_field": "updated_at", "field_type": "timestamp"},
+		},
+		ParseConfig: models.JSONB{"fieldMapping": []interface{}{
+			map[string]interface{}{"source": "recordId", "target": "record_id"},
+			map[string]interface{}{"source": "updated_at", "target": "updated_at"},
+		}},
+		TableFieldsConfig: models.JSONB{
+			"field_0": map[string]interface{}{"name_en": "record_id", "data_type": "varchar", "is_primary_key": true, "order_num": 1},
+			"field_1": map[string]interface{}{"name_en": "updated_at", "data_type": "timestamp", "is_nullable": true, "is_indexed": true, "order_num": 2},
+		},
+	}
+}
+
+// findingCodes 返回问题代码及其级别
+func findingCodes(findings []ConfigFinding) map[string]string {
+	codes := make(map[string]string, len(findings))
+	for _, finding := range findings {
+		codes[finding.Code] = finding.Level
+	}
+	return codes
+}
+
+func TestLintInterfaceConfig(t *testing.T) {
+	assert.Empty(t, lintInterfaceConfig(lintTestInterface()), "配置完整时没有问题")
+
+	iface := lintTestInterface()
+	iface.InterfaceConfig["pagination_size_param"] = ""
+	iface.InterfaceConfig["pagination_param_location"] = "header"
+	iface.ParseConfig["fieldMapping"] = append(iface.ParseConfig["fieldMapping"].([]interface{}),
+		map[string]interface{}{"source": "plate", "target": "plate_no"},
+		map[string]interface{}{"source": "recordNo", "target": "record_id"},
+	)
+	codes := findingCodes(lintInterfaceConfig(iface))
+	assert.Equal(t, ConfigFindingError, codes["pagination_param_missing"])
+	assert.Equal(t, ConfigFindingError, codes["pagination_location_invalid"])
+	assert.Equal(t, ConfigFindingError, codes["mapping_target_not_in_table"])
+	assert.Equal(t, ConfigFindingWarning, codes["mapping_target_duplicate"])
+
+	// 增量字段映射到不同名称的列时，同步按原名查询最近同步值
+	iface = lintTestInterface()
+	iface.InterfaceConfig["incremental_config"] = map[string]interface{}{"enabled": true, "increment_field": "modifyTime", "field_type": "timestamp"}
+	iface.ParseConfig["fieldMapping"] = []interface{}{
+		map[string]interface{}{"source": "recordId", "target": "record_id"},
+		map[string]interface{}{"source": "modifyTime", "target": "updated_at"},
+	}
+	findings := lintInterfaceConfig(iface)
+	require.NotEmpty(t, findings)
+	assert.Equal(t, "incremental_field_not_in_table", findings[0].Code)
+	assert.Contains(t, findings[0].Message, "updated_at")
+
+	iface.ParseConfig["field_mapping"] = map[string]interface{}{"modifyTime": "updated_at"}
+	assert.NotContains(t, findingCodes(lintInterfaceConfig(iface)), "incremental_field_not_in_table")
+
+	// 增量字段类型
+	iface = lintTestInterface()
+	iface.TableFieldsConfig["field_1"] = map[string]interface{}{"name_en": "updated_at", "data_type": "jsonb", "is_nullable": true}
+	assert.Equal(t, ConfigFindingError, findingCodes(lintInterfaceConfig(iface))["incremental_field_not_comparable"])
+	iface.TableFieldsConfig["field_1"] = map[string]interface{}{"name_en": "updated_at", "data_type": "varchar", "is_nullable": true}
+	codes = findingCodes(lintInterfaceConfig(iface))
+	assert.Equal(t, ConfigFindingWarning, codes["incremental_field_string"])
+	assert.Equal(t, ConfigFindingWarning, codes["incremental_type_mismatch"])
+	assert.Equal(t, ConfigFindingInfo, codes["incremental_field_not_indexed"])
+
+	// 数据库接口、未建表和非空字段未映射
+	iface = lintTestInterface()
+	iface.DataSource.Category = meta.DataSourceCategoryDatabase
+	iface.IsTableCreated = false
+	iface.TableFieldsConfig["field_2"] = map[string]interface{}{"name_en": "plate_no", "data_type": "varchar", "order_num": 3}
+	codes = findingCodes(lintInterfaceConfig(iface))
+	assert.Equal(t, ConfigFindingError, codes["table_name_missing"])
+	assert.Equal(t, ConfigFindingWarning, codes["table_not_created"])
+	assert.Equal(t, ConfigFindingWarning, codes["required_column_unmapped"])
+	assert.NotContains(t, codes, "pagination_param_missing", "数据库接口不检查API分页参数")
+}
+
+func TestClassifyFieldType(t *testing.T) {
+	assert.Equal(t, fieldTypeClassTemporal, classifyFieldType("timestamp"))
+	assert.Equal(t, fieldTypeClassTemporal, classifyFieldType("datetime"))
+	assert.Equal(t, fieldTypeClassNumeric, classifyFieldType("bigint"))
+	assert.Equal(t, fieldTypeClassNumeric, classifyFieldType("double precision"))
+	assert.Equal(t, fieldTypeClassString, classifyFieldType("character varying"))
+	assert.Equal(t, fieldTypeClassOther, classifyFieldType("boolean"))
+	assert.Equal(t, fieldTypeClassOther, classifyFieldType("uuid"))
+	assert.Equal(t, fieldTypeClassOther, classifyFieldType("geometry"))
+	assert.Equal(t, fieldTypeClassUnknown, classifyFieldType("tsvector"))
+}
+
+func TestConfigLintService(t *testing.T) {
+	db, _ := setupDiagnosticsDB(t)
+	result, err := NewConfigLintService(db).Lint(context.Background(), "if-1")
+	require.NoError(t, err)
+	assert.False(t, result.Valid)
+	assert.Equal(t, countFindings(result.Findings, ConfigFindingError), result.Errors)
+	assert.Equal(t, ConfigFindingError, result.Findings[0].Level, "error级问题排在前面")
+	assert.Contains(t, findingCodes(result.Findings), "table_fields_missing")
+
+	_, err = NewConfigLintService(db).Lint(context.Background(), "missing")
+	assert.Error(t, err)
+}
+
+// countFindings 统计指定级别的问题数
+func countFindings(findings []ConfigFinding, level string) int {
+	count := 0
+	for _, finding := range findings {
+		if finding.Level == level {
+			count++
+		}
+	}
+	return count
+}
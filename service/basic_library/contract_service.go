@@ -0,0 +1,396 @@
+/*
+ * @module service/basic_library/contract_service
+ * @description 接口数据契约服务，管理数据提供方为接口声明的契约，记录每次同步的契约校验结果并生成合规报告
+ * @architecture 分层架构 - 业务服务层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow 配置契约(校验后保存，version递增) -> 同步执行时执行器按契约校验 -> 记录校验结果(附加交付周期检查) -> 违规时记录应用事件 -> 按接口汇总合规报告
+ * @rules 列名不能重复，类型只能是支持的契约类型，strictness只能是warn或fail；交付周期按两次有数据交付的间隔计算，只记违规不拒绝写入；
+ *        没有写入数据的同步不记录校验结果；合规率 = 合规次数 / 校验次数
+ * @dependencies datahub-service/service/eventlog, datahub-service/service/interface_executor, datahub-service/service/models, gorm.io/gorm
+ * @refs service/models/interface_contract.go, service/interface_executor/contract.go, service/basic_library/sync_task_service.go, api/controllers/contract_controller.go
+ */
+
+package basic_library
+
+import (
+	"context"
+	"datahub-service/service/eventlog"
+	"datahub-service/service/interface_executor"
+	"datahub-service/service/models"
+	"datahub-service/service/tenant"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	defaultContractReportDays = 30  // 合规报告默认统计天数
+	maxContractReportDays     = 180 // 合规报告最大统计天数
+	contractReportRecentLimit = 20  // 合规报告中最近校验记录条数
+)
+
+// ErrInvalidContract 数据契约配置不合法
+var ErrInvalidContract = errors.New("数据契约配置不合法")
+
+// ContractInput 数据契约配置
+type ContractInput struct {
+	Columns        models.ContractColumns `json:"columns"`
+	CadenceSeconds int                    `json:"cadence_seconds"`
+	Strictness     string                 `json:"strictness"`
+	Enabled        *bool                  `json:"enabled,omitempty"` // 默认启用
+	Description    string                 `json:"description"`
+}
+
+// ContractViolationSummary 合规报告中按列和违规类型汇总的违规
+type ContractViolationSummary struct {
+	Column string `json:"column,omitempty"`
+	Kind   string `json:"kind"`
+	Checks int    `json:"checks"` // 出现该违规的校验次数
+	Count  int    `json:"count"`  // 违规次数合计
+	Sample string `json:"sample,omitempty"`
+}
+
+// ContractComplianceReport 接口数据契约合规报告
+type ContractComplianceReport struct {
+	InterfaceID    string                          `json:"interface_id"`
+	InterfaceName  string                          `json:"interface_name"`
+	Contract       *models.InterfaceContract       `json:"contract,omitempty"`
+	PeriodStart    time.Time                       `json:"period_start"`
+	PeriodEnd      time.Time                       `json:"period_end"`
+	TotalChecks    int                             `json:"total_checks"`
+	Compliant      int                             `json:"compliant"`
+	Violated       int                             `json:"violated"`
+	Rejected       int                             `json:"rejected"`
+	ComplianceRate float64                         `json:"compliance_rate"` // 百分比
+	RowsChecked    int64                           `json:"rows_checked"`
+	Violations     []ContractViolationSummary      `json:"violations"`
+	RecentChecks   []models.InterfaceContractCheck `json:"recent_checks"`
+}
+
+// ContractService 接口数据契约服务
+type ContractService struct {
+	db *gorm.DB
+}
+
+// NewContractService 创建接口数据契约服务实例
+func NewContractService(db *gorm.DB) *ContractService {
+	return &ContractService{db: db}
+}
+
+// GetContract 获取接口的数据契约
+func (s *ContractService) GetContract(ctx context.Context, interfaceID string) (*models.InterfaceContract, error) {
+	if _, err := s.getInterface(ctx, interfaceID); err != nil {
+		return nil, err
+	}
+	var contract models.InterfaceContract
+	if err := s.db.WithContext(ctx).First(&contract, "interface_id = ?", interfaceID).Error; err != nil {
+		return nil, err
+	}
+	return &contract, nil
+}
+
+// SaveContract 创建或更新接口的数据契约，更新时版本号加一
+func (s *ContractService) SaveContract(ctx context.Context, interfaceID string, input *ContractInput, username string) (*models.InterfaceContract, error) {
+	if err := ValidateContractInput(input); err != nil {
+		return nil, err
+	}
+	if _, err := s.getInterface(ctx, interfaceID); err != nil {
+		return nil, err
+	}
+
+	var contract models.InterfaceContract
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&contract, "interface_id = ?", interfaceID).Error
+		exists := err == nil
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+		if !exists {
+			contract = models.InterfaceContract{InterfaceID: interfaceID, CreatedAt: time.Now(), CreatedBy: username}
+		}
+
+		contract.Columns = input.Columns
+		contract.CadenceSeconds = input.CadenceSeconds
+		contract.Strictness = input.Strictness
+		contract.Enabled = input.Enabled == nil || *input.Enabled
+		contract.Description = input.Description
+		contract.Version++
+		contract.UpdatedAt = time.Now()
+		contract.UpdatedBy = username
+		if exists {
+			return tx.Save(&contract).Error
+		}
+		return tx.Create(&contract).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	slog.Info("保存接口数据契约", "interface_id", interfaceID, "version", contract.Version, "strictness", contract.Strictness, "enabled", contract.Enabled)
+	return &contract, nil
+}
+
+// DeleteContract 删除接口的数据契约，保留历史校验记录
+func (s *ContractService) DeleteContract(ctx context.Context, interfaceID string) error {
+	if _, err := s.getInterface(ctx, interfaceID); err != nil {
+		return err
+	}
+	result := s.db.WithContext(ctx).Where("interface_id = ?", interfaceID).Delete(&models.InterfaceContract{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// GetComplianceReport 汇总接口近days天的数据契约校验结果
+func (s *ContractService) GetComplianceReport(ctx context.Context, interfaceID string, days int) (*ContractComplianceReport, error) {
+	if days <= 0 {
+		days = defaultContractReportDays
+	}
+	if days > maxContractReportDays {
+		days = maxContractReportDays
+	}
+	iface, err := s.getInterface(ctx, interfaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	report := &ContractComplianceReport{
+		InterfaceID:   interfaceID,
+		InterfaceName: iface.NameZh,
+		PeriodStart:   now.AddDate(0, 0, -days),
+		PeriodEnd:     now,
+		Violations:    []ContractViolationSummary{},
+		RecentChecks:  []models.InterfaceContractCheck{},
+	}
+	var contract models.InterfaceContract
+	err = s.db.WithContext(ctx).First(&contract, "interface_id = ?", interfaceID).Error
+	if err == nil {
+		report.Contract = &contract
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	var checks []models.InterfaceContractCheck
+	err = s.db.WithContext(ctx).
+		Where("interface_id = ? AND checked_at >= ?", interfaceID, report.PeriodStart).
+		Order("checked_at DESC").
+		Find(&checks).Error
+	if err != nil {
+		return nil, fmt.Errorf("查询数据契约校验记录失败: %w", err)
+	}
+	summarizeContractChecks(report, checks)
+	return report, nil
+}
+
+// summarizeContractChecks 按状态、列和违规类型汇总校验记录，checks按时间倒序
+func summarizeContractChecks(report *ContractComplianceReport, checks []models.InterfaceContractCheck) {
+	summaries := make(map[string]*ContractViolationSummary)
+	for _, check := range checks {
+		report.TotalChecks++
+		report.RowsChecked += int64(check.RowsChecked)
+		switch check.Status {
+		case models.ContractStatusCompliant:
+			report.Compliant++
+		case models.ContractStatusRejected:
+			report.Rejected++
+		default:
+			report.Violated++
+		}
+		for _, violation := range check.Violations {
+			key := violation.Column + "\x00" + violation.Kind
+			summary, exists := summaries[key]
+			if !exists {
+				summary = &ContractViolationSummary{Column: violation.Column, Kind: violation.Kind, Sample: violation.Sample}
+				summaries[key] = summary
+			}
+			summary.Checks++
+			summary.Count += violation.Count
+		}
+		if len(report.RecentChecks) < contractReportRecentLimit {
+			report.RecentChecks = append(report.RecentChecks, check)
+		}
+	}
+	if report.TotalChecks > 0 {
+		report.ComplianceRate = roundPercent(float64(report.Compliant) / float64(report.TotalChecks))
+	}
+
+	for _, summary := range summaries {
+		report.Violations = append(report.Violations, *summary)
+	}
+	sort.Slice(report.Violations, func(i, j int) bool {
+		a, b := report.Violations[i], report.Violations[j]
+		if a.Checks != b.Checks {
+			return a.Checks > b.Checks
+		}
+		if a.Column != b.Column {
+			return a.Column < b.Column
+		}
+		return a.Kind < b.Kind
+	})
+}
+
+// getInterface 按租户范围获取接口
+func (s *ContractService) getInterface(ctx context.Context, interfaceID string) (*models.DataInterface, error) {
+	var iface models.DataInterface
+	err := s.db.WithContext(ctx).Select("id", "library_id", "name_zh").
+		Scopes(tenant.OwnerScope(ctx, "library_id", "basic_libraries")).
+		First(&iface, "id = ?", interfaceID).Error
+	if err != nil {
+		return nil, err
+	}
+	return &iface, nil
+}
+
+// ValidateContractInput 校验数据契约配置，错误包装ErrInvalidContract
+func ValidateContractInput(input *ContractInput) error {
+	if len(input.Columns) == 0 {
+		return fmt.Errorf("%w: 至少需要声明一列", ErrInvalidContract)
+	}
+	if input.Strictness == "" {
+		input.Strictness = models.ContractStrictnessWarn
+	}
+	if input.Strictness != models.ContractStrictnessWarn && input.Strictness != models.ContractStrictnessFail {
+		return fmt.Errorf("%w: strictness只能是 %s 或 %s", ErrInvalidContract, models.ContractStrictnessWarn, models.ContractStrictnessFail)
+	}
+	if input.CadenceSeconds < 0 {
+		return fmt.Errorf("%w: 交付周期不能小于0", ErrInvalidContract)
+	}
+
+	supported := interface_executor.ContractTypes()
+	seen := make(map[string]bool, len(input.Columns))
+	for i := range input.Columns {
+		column := &input.Columns[i]
+		column.Name = strings.TrimSpace(column.Name)
+		column.Type = strings.ToLower(strings.TrimSpace(column.Type))
+		if column.Name == "" {
+			return fmt.Errorf("%w: 第 %d 列缺少列名", ErrInvalidContract, i+1)
+		}
+		if seen[column.Name] {
+			return fmt.Errorf("%w: 列 %s 重复", ErrInvalidContract, column.Name)
+		}
+		seen[column.Name] = true
+		if column.Type != "" && !containsContractType(supported, column.Type) {
+			return fmt.Errorf("%w: 列 %s 的类型 %s 不支持，可选值: %s", ErrInvalidContract, column.Name, column.Type, strings.Join(supported, ", "))
+		}
+		if len(column.EnumValues) > 0 && (column.Type == models.ContractTypeJSON || column.Type == models.ContractTypeBoolean) {
+			return fmt.Errorf("%w: 列 %s 的类型 %s 不支持枚举取值", ErrInvalidContract, column.Name, column.Type)
+		}
+	}
+	return nil
+}
+
+func containsContractType(types []string, target string) bool {
+	for _, t := range types {
+		if t == target {
+			return true
+		}
+	}
+	return false
+}
+
+// recordContractCompliance 记录一次同步的数据契约校验结果并附加交付周期检查，返回校验状态；接口未配置契约或没有写入数据时不记录
+func recordContractCompliance(ctx context.Context, db *gorm.DB, taskID, executionID, interfaceID string, report *interface_executor.ContractReport, at time.Time) string {
+	if report == nil {
+		return ""
+	}
+
+	check := models.InterfaceContractCheck{
+		ID:              uuid.New().String(),
+		InterfaceID:     interfaceID,
+		TaskID:          taskID,
+		ExecutionID:     executionID,
+		ContractVersion: report.ContractVersion,
+		Strictness:      report.Strictness,
+		Status:          report.Status,
+		RowsChecked:     report.RowsChecked,
+		Violations:      append(models.ContractViolations{}, report.Violations...),
+		CheckedAt:       at,
+	}
+	if violation := checkDeliveryCadence(ctx, db, interfaceID, at); violation != nil {
+		check.Violations = append(check.Violations, *violation)
+		if check.Status == models.ContractStatusCompliant {
+			check.Status = models.ContractStatusViolated
+		}
+	}
+	for _, violation := range check.Violations {
+		check.ViolationCount += violation.Count
+	}
+
+	if err := db.WithContext(ctx).Create(&check).Error; err != nil {
+		slog.Warn("记录数据契约校验结果失败", "interface_id", interfaceID, "error", err)
+	}
+	if check.Status != models.ContractStatusCompliant {
+		recordContractViolated(ctx, taskID, &check)
+	}
+	return check.Status
+}
+
+// checkDeliveryCadence 距上一次有数据交付的校验超过约定的交付周期时返回违规，被拒绝的交付不算
+func checkDeliveryCadence(ctx context.Context, db *gorm.DB, interfaceID string, at time.Time) *models.ContractViolation {
+	var contract models.InterfaceContract
+	if err := db.WithContext(ctx).Select("interface_id", "cadence_seconds").First(&contract, "interface_id = ?", interfaceID).Error; err != nil || contract.CadenceSeconds <= 0 {
+		return nil
+	}
+	var previous models.InterfaceContractCheck
+	err := db.WithContext(ctx).Select("checked_at").
+		Where("interface_id = ? AND status <> ? AND checked_at < ?", interfaceID, models.ContractStatusRejected, at).
+		Order("checked_at DESC").
+		First(&previous).Error
+	if err != nil {
+		return nil
+	}
+
+	gap := at.Sub(previous.CheckedAt)
+	if gap <= time.Duration(contract.CadenceSeconds)*time.Second {
+		return nil
+	}
+	return &models.ContractViolation{
+		Kind:    models.ContractViolationCadence,
+		Count:   1,
+		Sample:  fmt.Sprintf("%d", int64(gap/time.Second)),
+		Message: fmt.Sprintf("距上次交付 %d 秒，超过约定的交付周期 %d 秒", int64(gap/time.Second), contract.CadenceSeconds),
+	}
+}
+
+// recordContractViolated 记录数据契约违规事件，拒绝写入时以错误级别记录
+func recordContractViolated(ctx context.Context, taskID string, check *models.InterfaceContractCheck) {
+	level := models.EventLevelWarn
+	message := "接口数据不符合数据契约"
+	if check.Status == models.ContractStatusRejected {
+		level = models.EventLevelError
+		message = "接口数据不符合数据契约，已拒绝写入"
+	}
+	parts := make([]string, len(check.Violations))
+	for i, violation := range check.Violations {
+		parts[i] = violation.Message
+		if violation.Column != "" {
+			parts[i] = violation.Column + ": " + violation.Message
+		}
+	}
+	eventlog.Record(ctx, eventlog.Event{
+		Type:       eventlog.EventContractViolated,
+		Level:      level,
+		Message:    message,
+		ObjectType: "data_interface",
+		ObjectID:   check.InterfaceID,
+		Attributes: map[string]interface{}{
+			"task_id":          taskID,
+			"execution_id":     check.ExecutionID,
+			"status":           check.Status,
+			"contract_version": check.ContractVersion,
+			"violation_count":  check.ViolationCount,
+			"error":            strings.Join(parts, "; "),
+		},
+	})
+}
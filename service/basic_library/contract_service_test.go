@@ -0,0 +1,130 @@
+/*
+ * @module service/basic_library/contract_service_test
+ * @description 接口数据契约服务测试，覆盖契约配置校验、保存时版本递增、校验结果记录(含交付周期检查和违规事件)以及合规报告汇总
+ * @architecture 测试层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow 准备sqlite中的接口 -> 保存契约/记录校验结果 -> 验证契约、校验记录、应用事件和合规报告
+ * @rules 使用内存sqlite，不依赖外部服务
+ * @dependencies gorm.io/driver/sqlite, stretchr/testify
+ * @refs contract_service.go
+ */
+
+package basic_library
+
+import (
+	"context"
+	"datahub-service/service/eventlog"
+	"datahub-service/service/interface_executor"
+	"datahub-service/service/models"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// setupContractDB 准备接口if-1，并把应用事件写入测试库
+func setupContractDB(t *testing.T) *gorm.DB {
+	db, _ := setupDiagnosticsDB(t)
+	require.NoError(t, db.AutoMigrate(&models.BasicLibrary{}, &models.InterfaceContract{}, &models.InterfaceContractCheck{}))
+
+	eventlog.SetDefault(eventlog.NewService(db))
+	t.Cleanup(func() { eventlog.SetDefault(nil) })
+	return db
+}
+
+func TestValidateContractInput(t *testing.T) {
+	input := &ContractInput{Columns: models.ContractColumns{{Name: " plate ", Type: "STRING"}}}
+	require.NoError(t, ValidateContractInput(input))
+	assert.Equal(t, models.ContractStrictnessWarn, input.Strictness, "默认只告警")
+	assert.Equal(t, models.ContractColumn{Name: "plate", Type: "string"}, input.Columns[0])
+
+	invalid := map[string]*ContractInput{
+		"没有列":     {},
+		"列名重复":    {Columns: models.ContractColumns{{Name: "a"}, {Name: "a"}}},
+		"类型不支持":   {Columns: models.ContractColumns{{Name: "a", Type: "uuid"}}},
+		"布尔值不能枚举": {Columns: models.ContractColumns{{Name: "a", Type: "boolean", EnumValues: []string{"true"}}}},
+		"未知处理方式":  {Columns: models.ContractColumns{{Name: "a"}}, Strictness: "block"},
+		"交付周期为负":  {Columns: models.ContractColumns{{Name: "a"}}, CadenceSeconds: -1},
+	}
+	for name, input := range invalid {
+		assert.True(t, errors.Is(ValidateContractInput(input), ErrInvalidContract), name)
+	}
+}
+
+func TestSaveContractIncrementsVersion(t *testing.T) {
+	db := setupContractDB(t)
+	s := NewContractService(db)
+	ctx := context.Background()
+
+	disabled := false
+	contract, err := s.SaveContract(ctx, "if-1", &ContractInput{
+		Columns: models.ContractColumns{{Name: "plate", Type: "string"}}, Enabled: &disabled,
+	}, "alice")
+	require.NoError(t, err)
+	assert.Equal(t, 1, contract.Version)
+	assert.False(t, contract.Enabled)
+
+	_, err = s.SaveContract(ctx, "if-1", &ContractInput{
+		Columns: models.ContractColumns{{Name: "plate", Type: "string"}}, Strictness: models.ContractStrictnessFail, CadenceSeconds: 3600,
+	}, "bob")
+	require.NoError(t, err)
+
+	saved, err := s.GetContract(ctx, "if-1")
+	require.NoError(t, err)
+	assert.Equal(t, 2, saved.Version)
+	assert.True(t, saved.Enabled)
+	assert.Equal(t, models.ContractStrictnessFail, saved.Strictness)
+	assert.Equal(t, "alice", saved.CreatedBy)
+	assert.Equal(t, "bob", saved.UpdatedBy)
+
+	_, err = s.SaveContract(ctx, "if-missing", &ContractInput{Columns: models.ContractColumns{{Name: "plate"}}}, "bob")
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+
+	require.NoError(t, s.DeleteContract(ctx, "if-1"))
+	assert.ErrorIs(t, s.DeleteContract(ctx, "if-1"), gorm.ErrRecordNotFound)
+}
+
+func TestRecordContractComplianceAndReport(t *testing.T) {
+	db := setupContractDB(t)
+	s := NewContractService(db)
+	ctx := context.Background()
+	_, err := s.SaveContract(ctx, "if-1", &ContractInput{
+		Columns: models.ContractColumns{{Name: "plate", Type: "string"}}, CadenceSeconds: 3600,
+	}, "alice")
+	require.NoError(t, err)
+
+	now := time.Now()
+	compliant := &interface_executor.ContractReport{ContractVersion: 1, Strictness: "warn", Status: models.ContractStatusCompliant, RowsChecked: 10}
+	assert.Equal(t, "", recordContractCompliance(ctx, db, "task-1", "exec-0", "if-1", nil, now), "没有写入数据不记录")
+	assert.Equal(t, models.ContractStatusCompliant, recordContractCompliance(ctx, db, "task-1", "exec-1", "if-1", compliant, now.Add(-3*time.Hour)))
+
+	// 距上次交付3小时，超过1小时的交付周期
+	assert.Equal(t, models.ContractStatusViolated, recordContractCompliance(ctx, db, "task-1", "exec-2", "if-1", compliant, now))
+
+	rejected := &interface_executor.ContractReport{
+		ContractVersion: 1, Strictness: "fail", Status: models.ContractStatusRejected, RowsChecked: 5,
+		Violations: models.ContractViolations{{Column: "plate", Kind: models.ContractViolationNull, Count: 2, Message: "不可为空的列为空或缺失"}},
+	}
+	assert.Equal(t, models.ContractStatusRejected, recordContractCompliance(ctx, db, "task-1", "exec-3", "if-1", rejected, now.Add(time.Minute)))
+
+	var events int64
+	require.NoError(t, db.Model(&models.ApplicationEvent{}).Where("event_type = ? AND object_id = ?", eventlog.EventContractViolated, "if-1").Count(&events).Error)
+	assert.Equal(t, int64(2), events)
+
+	report, err := s.GetComplianceReport(ctx, "if-1", 0)
+	require.NoError(t, err)
+	assert.Equal(t, 3, report.TotalChecks)
+	assert.Equal(t, 1, report.Compliant)
+	assert.Equal(t, 1, report.Violated)
+	assert.Equal(t, 1, report.Rejected)
+	assert.Equal(t, 33.33, report.ComplianceRate)
+	assert.Equal(t, int64(25), report.RowsChecked)
+	require.Len(t, report.Violations, 2)
+	assert.Equal(t, models.ContractViolationCadence, report.Violations[0].Kind)
+	assert.Equal(t, ContractViolationSummary{Column: "plate", Kind: models.ContractViolationNull, Checks: 1, Count: 2}, report.Violations[1])
+	assert.Equal(t, "exec-3", report.RecentChecks[0].ExecutionID)
+	require.NotNil(t, report.Contract)
+}
@@ -0,0 +1,397 @@
+/*
+ * @module service/basic_library/credential_rotation_service
+ * @description 数据源凭据轮换，用新凭据测试连通后原子替换连接配置中的敏感项，宽限期内保留旧凭据以便回滚，轮换和回滚写入审计日志
+ * @architecture 分层架构 - 业务服务层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow 校验新凭据 -> 候选配置连通性测试(失败记审计并返回) -> 事务内锁定数据源、替换凭据、取代旧的轮换记录、记录轮换和审计 -> 重新加载数据源实例；回滚 -> 事务内恢复宽限期内的旧凭据 -> 重新加载
+ * @rules 只允许轮换名称为敏感字段的配置项，嵌套配置用点号分隔路径；新值必须是非空字符串且与当前值不同，可以是密钥引用，SECRETS_REQUIRE_REFERENCE=true时只接受密钥引用；宽限期默认24小时，最长7天；
+ *        替换时在锁定的最新配置上合并，不覆盖并发修改的其他配置项；旧凭据不通过接口返回，宽限期结束、被取代或回滚后清除；审计日志不记录凭据值
+ * @dependencies datahub-service/service/models, datahub-service/service/secrets, datahub-service/service/tenant, gorm.io/gorm
+ * @refs service/models/credential_rotation.go, datasource_service.go, datasource_init_service.go, api/controllers/credential_rotation_controller.go
+ */
+
+package basic_library
+
+import (
+	"context"
+	"datahub-service/service/models"
+	"datahub-service/service/secrets"
+	"datahub-service/service/tenant"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	// defaultCredentialGracePeriod 默认保留旧凭据24小时
+	defaultCredentialGracePeriod = 24 * time.Hour
+	// maxCredentialGracePeriod 旧凭据最长保留7天
+	maxCredentialGracePeriod = 7 * 24 * time.Hour
+	// credentialAuditObjectType 审计日志对象类型
+	credentialAuditObjectType = "data_source"
+)
+
+var (
+	// ErrInvalidCredentialRotation 轮换请求不合法
+	ErrInvalidCredentialRotation = errors.New("凭据轮换请求不合法")
+	// ErrCredentialTestFailed 新凭据连通性测试失败
+	ErrCredentialTestFailed = errors.New("新凭据连通性测试失败")
+	// ErrNoRollbackableRotation 没有宽限期内可回滚的轮换
+	ErrNoRollbackableRotation = errors.New("没有宽限期内可回滚的凭据轮换")
+)
+
+// CredentialTester 使用候选配置测试数据源连通性
+type CredentialTester interface {
+	TestCandidateConnection(ctx context.Context, dataSource *models.DataSource) error
+}
+
+// DataSourceReloader 配置变更后重新加载数据源实例
+type DataSourceReloader interface {
+	ReloadDataSource(ctx context.Context, dataSourceID string) error
+}
+
+// CredentialRotationRequest 凭据轮换请求
+type CredentialRotationRequest struct {
+	Credentials        map[string]interface{} // 配置项路径到新凭据，如password、auth.access_token
+	GracePeriodSeconds int                    // 旧凭据保留秒数，0表示默认24小时
+}
+
+// CredentialRotationService 数据源凭据轮换服务
+type CredentialRotationService struct {
+	db       *gorm.DB
+	tester   CredentialTester
+	reloader DataSourceReloader
+}
+
+// NewCredentialRotationService 创建数据源凭据轮换服务
+func NewCredentialRotationService(db *gorm.DB, tester CredentialTester, reloader DataSourceReloader) *CredentialRotationService {
+	return &CredentialRotationService{db: db, tester: tester, reloader: reloader}
+}
+
+// Rotate 测试新凭据连通后替换数据源凭据，旧凭据在宽限期内保留
+func (s *CredentialRotationService) Rotate(ctx context.Context, dataSourceID string, req *CredentialRotationRequest, operator, operatorIP string) (*models.DataSourceCredentialRotation, error) {
+	s.purgeExpired(ctx)
+
+	gracePeriod, keys, err := validateCredentialRotation(req)
+	if err != nil {
+		return nil, err
+	}
+	dataSource, err := s.getDataSource(ctx, dataSourceID)
+	if err != nil {
+		return nil, err
+	}
+
+	candidate := *dataSource
+	candidate.ConnectionConfig = copyConnectionConfig(dataSource.ConnectionConfig)
+	for _, key := range keys {
+		current, _ := getConfigPath(candidate.ConnectionConfig, key)
+		if current == req.Credentials[key] {
+			return nil, fmt.Errorf("%w: %s 的新值与当前值相同", ErrInvalidCredentialRotation, key)
+		}
+		if err := setConfigPath(candidate.ConnectionConfig, key, req.Credentials[key]); err != nil {
+			return nil, err
+		}
+	}
+
+	startTime := time.Now()
+	if err := s.tester.TestCandidateConnection(ctx, &candidate); err != nil {
+		s.audit(s.db.WithContext(ctx), "rotate_credentials", dataSourceID, operator, operatorIP, "failure", models.JSONB{
+			"rotated_keys": keys,
+			"error":        err.Error(),
+		})
+		return nil, fmt.Errorf("%w: %v", ErrCredentialTestFailed, err)
+	}
+	testDuration := time.Since(startTime).Milliseconds()
+
+	now := time.Now()
+	rotation := &models.DataSourceCredentialRotation{
+		DataSourceID:   dataSourceID,
+		RotatedKeys:    keys,
+		Status:         models.CredentialRotationActive,
+		GraceUntil:     now.Add(gracePeriod),
+		TestDurationMs: testDuration,
+		RotatedAt:      now,
+		RotatedBy:      operator,
+	}
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var locked models.DataSource
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&locked, "id = ?", dataSourceID).Error; err != nil {
+			return err
+		}
+		config := copyConnectionConfig(locked.ConnectionConfig)
+		previous := models.JSONB{}
+		for _, key := range keys {
+			value, _ := getConfigPath(config, key)
+			previous[key] = value
+			if err := setConfigPath(config, key, req.Credentials[key]); err != nil {
+				return err
+			}
+		}
+		if err := tx.Model(&models.DataSource{}).Where("id = ?", dataSourceID).Updates(map[string]interface{}{
+			"connection_config": config,
+			"updated_at":        now,
+			"updated_by":        operator,
+		}).Error; err != nil {
+			return fmt.Errorf("更新数据源凭据失败: %w", err)
+		}
+
+		if err := tx.Model(&models.DataSourceCredentialRotation{}).
+			Where("data_source_id = ? AND status = ?", dataSourceID, models.CredentialRotationActive).
+			Updates(map[string]interface{}{"status": models.CredentialRotationSuperseded, "previous_secrets": models.JSONB{}}).Error; err != nil {
+			return fmt.Errorf("更新旧的轮换记录失败: %w", err)
+		}
+		rotation.PreviousSecrets = previous
+		if err := tx.Create(rotation).Error; err != nil {
+			return fmt.Errorf("保存轮换记录失败: %w", err)
+		}
+		return s.audit(tx, "rotate_credentials", dataSourceID, operator, operatorIP, "success", models.JSONB{
+			"rotation_id":      rotation.ID,
+			"rotated_keys":     keys,
+			"grace_until":      rotation.GraceUntil,
+			"test_duration_ms": testDuration,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.reload(ctx, dataSourceID)
+	slog.Info("数据源凭据轮换成功", "datasource_id", dataSourceID, "rotation_id", rotation.ID, "keys", keys, "grace_until", rotation.GraceUntil)
+	return rotation, nil
+}
+
+// Rollback 恢复最近一次轮换前的凭据，只在宽限期内可用
+func (s *CredentialRotationService) Rollback(ctx context.Context, dataSourceID, operator, operatorIP string) (*models.DataSourceCredentialRotation, error) {
+	s.purgeExpired(ctx)
+	if _, err := s.getDataSource(ctx, dataSourceID); err != nil {
+		return nil, err
+	}
+
+	var rotation models.DataSourceCredentialRotation
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var locked models.DataSource
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&locked, "id = ?", dataSourceID).Error; err != nil {
+			return err
+		}
+		err := tx.Where("data_source_id = ? AND status = ? AND grace_until > ?", dataSourceID, models.CredentialRotationActive, time.Now()).
+			Order("rotated_at DESC").First(&rotation).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrNoRollbackableRotation
+		}
+		if err != nil {
+			return fmt.Errorf("查询轮换记录失败: %w", err)
+		}
+
+		config := copyConnectionConfig(locked.ConnectionConfig)
+		for key, value := range rotation.PreviousSecrets {
+			if value == nil {
+				deleteConfigPath(config, key)
+				continue
+			}
+			if err := setConfigPath(config, key, value); err != nil {
+				return err
+			}
+		}
+		now := time.Now()
+		if err := tx.Model(&models.DataSource{}).Where("id = ?", dataSourceID).Updates(map[string]interface{}{
+			"connection_config": config,
+			"updated_at":        now,
+			"updated_by":        operator,
+		}).Error; err != nil {
+			return fmt.Errorf("恢复数据源凭据失败: %w", err)
+		}
+
+		rotation.Status = models.CredentialRotationRolledBack
+		rotation.PreviousSecrets = models.JSONB{}
+		rotation.RolledBackAt = &now
+		rotation.RolledBackBy = operator
+		if err := tx.Model(&rotation).Select("status", "previous_secrets", "rolled_back_at", "rolled_back_by").Updates(&rotation).Error; err != nil {
+			return fmt.Errorf("更新轮换记录失败: %w", err)
+		}
+		return s.audit(tx, "rollback_credentials", dataSourceID, operator, operatorIP, "success", models.JSONB{
+			"rotation_id":  rotation.ID,
+			"rotated_keys": []string(rotation.RotatedKeys),
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.reload(ctx, dataSourceID)
+	slog.Info("数据源凭据已回滚", "datasource_id", dataSourceID, "rotation_id", rotation.ID)
+	return &rotation, nil
+}
+
+// ListRotations 查询数据源的凭据轮换记录，最近的在前
+func (s *CredentialRotationService) ListRotations(ctx context.Context, dataSourceID string) ([]models.DataSourceCredentialRotation, error) {
+	s.purgeExpired(ctx)
+	if _, err := s.getDataSource(ctx, dataSourceID); err != nil {
+		return nil, err
+	}
+	var rotations []models.DataSourceCredentialRotation
+	if err := s.db.WithContext(ctx).Where("data_source_id = ?", dataSourceID).Order("rotated_at DESC").Find(&rotations).Error; err != nil {
+		return nil, fmt.Errorf("查询轮换记录失败: %w", err)
+	}
+	return rotations, nil
+}
+
+// getDataSource 按租户范围查询数据源
+func (s *CredentialRotationService) getDataSource(ctx context.Context, dataSourceID string) (*models.DataSource, error) {
+	var dataSource models.DataSource
+	if err := s.db.WithContext(ctx).Scopes(tenant.OwnerScope(ctx, "library_id", "basic_libraries")).
+		First(&dataSource, "id = ?", dataSourceID).Error; err != nil {
+		return nil, err
+	}
+	return &dataSource, nil
+}
+
+// purgeExpired 清除宽限期已结束的旧凭据
+func (s *CredentialRotationService) purgeExpired(ctx context.Context) {
+	result := s.db.WithContext(ctx).Model(&models.DataSourceCredentialRotation{}).
+		Where("status = ? AND grace_until <= ?", models.CredentialRotationActive, time.Now()).
+		Updates(map[string]interface{}{"status": models.CredentialRotationExpired, "previous_secrets": models.JSONB{}})
+	if result.Error != nil {
+		slog.Error("清除过期的旧凭据失败", "error", result.Error)
+	} else if result.RowsAffected > 0 {
+		slog.Info("已清除过期的旧凭据", "count", result.RowsAffected)
+	}
+}
+
+// reload 重新加载数据源实例，失败时只记录日志，下次使用时会按新配置注册
+func (s *CredentialRotationService) reload(ctx context.Context, dataSourceID string) {
+	if s.reloader == nil {
+		return
+	}
+	if err := s.reloader.ReloadDataSource(ctx, dataSourceID); err != nil {
+		slog.Warn("凭据已更新但重新加载数据源失败", "datasource_id", dataSourceID, "error", err)
+	}
+}
+
+// audit 写入凭据轮换审计日志，内容不包含凭据值
+func (s *CredentialRotationService) audit(tx *gorm.DB, operation, dataSourceID, operator, operatorIP, result string, content models.JSONB) error {
+	log := &models.SystemLog{
+		OperationType:    operation,
+		ObjectType:       credentialAuditObjectType,
+		ObjectID:         &dataSourceID,
+		OperatorName:     &operator,
+		OperationContent: content,
+		OperationTime:    time.Now(),
+		OperationResult:  result,
+		CreatedBy:        operator,
+	}
+	if operatorIP != "" {
+		log.OperatorIP = &operatorIP
+	}
+	if err := tx.Create(log).Error; err != nil {
+		slog.Error("写入凭据轮换审计日志失败", "datasource_id", dataSourceID, "operation", operation, "error", err)
+		return fmt.Errorf("写入审计日志失败: %w", err)
+	}
+	return nil
+}
+
+// validateCredentialRotation 校验轮换请求，返回宽限期和排序后的配置项路径
+func validateCredentialRotation(req *CredentialRotationRequest) (time.Duration, []string, error) {
+	if req == nil || len(req.Credentials) == 0 {
+		return 0, nil, fmt.Errorf("%w: 没有提供新凭据", ErrInvalidCredentialRotation)
+	}
+	gracePeriod := defaultCredentialGracePeriod
+	if req.GracePeriodSeconds < 0 {
+		return 0, nil, fmt.Errorf("%w: 宽限期不能小于0", ErrInvalidCredentialRotation)
+	}
+	if req.GracePeriodSeconds > 0 {
+		gracePeriod = time.Duration(req.GracePeriodSeconds) * time.Second
+	}
+	if gracePeriod > maxCredentialGracePeriod {
+		return 0, nil, fmt.Errorf("%w: 宽限期不能超过7天", ErrInvalidCredentialRotation)
+	}
+
+	keys := make([]string, 0, len(req.Credentials))
+	for key, value := range req.Credentials {
+		segments := strings.Split(key, ".")
+		if !isSensitiveConfigKey(segments[len(segments)-1]) {
+			return 0, nil, fmt.Errorf("%w: %s 不是凭据配置项", ErrInvalidCredentialRotation, key)
+		}
+		str, ok := value.(string)
+		if !ok || str == "" {
+			return 0, nil, fmt.Errorf("%w: %s 的新值必须是非空字符串", ErrInvalidCredentialRotation, key)
+		}
+		if problems := secrets.CheckConfig(map[string]interface{}{key: str}); len(problems) > 0 {
+			return 0, nil, fmt.Errorf("%w: %s", ErrInvalidCredentialRotation, problems[0])
+		}
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return gracePeriod, keys, nil
+}
+
+// copyConnectionConfig 深拷贝连接配置，避免修改已加载的数据源
+func copyConnectionConfig(config models.JSONB) models.JSONB {
+	copied := models.JSONB{}
+	if config == nil {
+		return copied
+	}
+	data, err := json.Marshal(config)
+	if err != nil {
+		return copied
+	}
+	_ = json.Unmarshal(data, &copied)
+	return copied
+}
+
+// getConfigPath 按点号分隔的路径读取配置项
+func getConfigPath(config map[string]interface{}, path string) (interface{}, bool) {
+	segments := strings.Split(path, ".")
+	current := config
+	for _, segment := range segments[:len(segments)-1] {
+		next, ok := current[segment].(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		current = next
+	}
+	value, exists := current[segments[len(segments)-1]]
+	return value, exists
+}
+
+// setConfigPath 按点号分隔的路径写入配置项，中间层不存在时创建
+func setConfigPath(config map[string]interface{}, path string, value interface{}) error {
+	segments := strings.Split(path, ".")
+	current := config
+	for _, segment := range segments[:len(segments)-1] {
+		raw, exists := current[segment]
+		if !exists {
+			next := map[string]interface{}{}
+			current[segment] = next
+			current = next
+			continue
+		}
+		next, ok := raw.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("%w: %s 中的 %s 不是对象", ErrInvalidCredentialRotation, path, segment)
+		}
+		current = next
+	}
+	current[segments[len(segments)-1]] = value
+	return nil
+}
+
+// deleteConfigPath 按点号分隔的路径删除配置项
+func deleteConfigPath(config map[string]interface{}, path string) {
+	segments := strings.Split(path, ".")
+	current := config
+	for _, segment := range segments[:len(segments)-1] {
+		next, ok := current[segment].(map[string]interface{})
+		if !ok {
+			return
+		}
+		current = next
+	}
+	delete(current, segments[len(segments)-1])
+}
@@ -0,0 +1,133 @@
+/*
+ * @module service/basic_library/credential_rotation_service_test
+ * @description 数据源凭据轮换测试，覆盖请求校验、连通性测试失败时不修改配置、轮换替换嵌套凭据并保留旧凭据、宽限期内回滚和过期清除
+ * @architecture 测试层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow 准备sqlite中的数据源 -> 轮换/回滚 -> 验证连接配置、轮换记录和审计日志
+ * @rules 使用内存sqlite，连通性测试和数据源重新加载由模拟实现代替
+ * @dependencies gorm.io/driver/sqlite, stretchr/testify
+ * @refs credential_rotation_service.go
+ */
+
+package basic_library
+
+import (
+	"context"
+	"datahub-service/service/models"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeCredentialTester 记录测试时的候选配置，按预设返回结果
+type fakeCredentialTester struct {
+	err    error
+	tested models.JSONB
+}
+
+func (f *fakeCredentialTester) TestCandidateConnection(ctx context.Context, dataSource *models.DataSource) error {
+	f.tested = dataSource.ConnectionConfig
+	return f.err
+}
+
+// fakeReloader 记录重新加载的数据源
+type fakeReloader struct {
+	reloaded []string
+}
+
+func (f *fakeReloader) ReloadDataSource(ctx context.Context, dataSourceID string) error {
+	f.reloaded = append(f.reloaded, dataSourceID)
+	return nil
+}
+
+func TestValidateCredentialRotation(t *testing.T) {
+	grace, keys, err := validateCredentialRotation(&CredentialRotationRequest{Credentials: map[string]interface{}{"password": "x", "auth.access_token": "y"}})
+	require.NoError(t, err)
+	assert.Equal(t, defaultCredentialGracePeriod, grace)
+	assert.Equal(t, []string{"auth.access_token", "password"}, keys)
+
+	invalid := []*CredentialRotationRequest{
+		{},
+		{Credentials: map[string]interface{}{"base_url": "http://other"}},
+		{Credentials: map[string]interface{}{"password": ""}},
+		{Credentials: map[string]interface{}{"password": 123}},
+		{Credentials: map[string]interface{}{"password": "x"}, GracePeriodSeconds: 8 * 24 * 3600},
+	}
+	for _, req := range invalid {
+		_, _, err := validateCredentialRotation(req)
+		assert.ErrorIs(t, err, ErrInvalidCredentialRotation)
+	}
+}
+
+func TestCredentialRotation(t *testing.T) {
+	db, _ := setupDiagnosticsDB(t)
+	require.NoError(t, db.AutoMigrate(&models.DataSourceCredentialRotation{}, &models.SystemLog{}))
+	tester := &fakeCredentialTester{err: errors.New("authentication failed")}
+	reloader := &fakeReloader{}
+	s := NewCredentialRotationService(db, tester, reloader)
+	ctx := context.Background()
+
+	loadConfig := func() models.JSONB {
+		var ds models.DataSource
+		require.NoError(t, db.First(&ds, "id = ?", "ds-1").Error)
+		return ds.ConnectionConfig
+	}
+
+	req := &CredentialRotationRequest{Credentials: map[string]interface{}{"password": "n3w-p@ss", "auth.access_token": "def"}}
+	_, err := s.Rotate(ctx, "ds-1", req, "admin", "10.0.0.1")
+	assert.ErrorIs(t, err, ErrCredentialTestFailed)
+	assert.Equal(t, "n3w-p@ss", tester.tested["password"], "使用候选配置测试")
+	assert.Equal(t, "p@ss", loadConfig()["password"], "测试失败时不修改配置")
+	assert.Empty(t, reloader.reloaded)
+
+	tester.err = nil
+	rotation, err := s.Rotate(ctx, "ds-1", req, "admin", "10.0.0.1")
+	require.NoError(t, err)
+	assert.Equal(t, models.CredentialRotationActive, rotation.Status)
+	assert.Equal(t, []string{"auth.access_token", "password"}, []string(rotation.RotatedKeys))
+	config := loadConfig()
+	assert.Equal(t, "n3w-p@ss", config["password"])
+	assert.Equal(t, "def", config["auth"].(map[string]interface{})["access_token"])
+	assert.Equal(t, "http://parking", config["base_url"], "其他配置项不变")
+	assert.Equal(t, []string{"ds-1"}, reloader.reloaded)
+
+	var stored models.DataSourceCredentialRotation
+	require.NoError(t, db.First(&stored, "id = ?", rotation.ID).Error)
+	assert.Equal(t, "p@ss", stored.PreviousSecrets["password"])
+	assert.Equal(t, "abc", stored.PreviousSecrets["auth.access_token"])
+
+	var logs []models.SystemLog
+	require.NoError(t, db.Where("object_id = ?", "ds-1").Order("operation_time").Find(&logs).Error)
+	require.Len(t, logs, 2)
+	assert.Equal(t, "failure", logs[0].OperationResult)
+	assert.Equal(t, "success", logs[1].OperationResult)
+	assert.NotContains(t, logs[1].OperationContent, "credentials", "审计日志不记录凭据值")
+
+	_, err = s.Rotate(ctx, "ds-1", req, "admin", "")
+	assert.ErrorIs(t, err, ErrInvalidCredentialRotation, "新值与当前值相同")
+
+	rolledBack, err := s.Rollback(ctx, "ds-1", "admin", "")
+	require.NoError(t, err)
+	assert.Equal(t, models.CredentialRotationRolledBack, rolledBack.Status)
+	config = loadConfig()
+	assert.Equal(t, "p@ss", config["password"])
+	assert.Equal(t, "abc", config["auth"].(map[string]interface{})["access_token"])
+	_, err = s.Rollback(ctx, "ds-1", "admin", "")
+	assert.ErrorIs(t, err, ErrNoRollbackableRotation, "已回滚的轮换不能再次回滚")
+
+	// 宽限期结束后清除旧凭据
+	_, err = s.Rotate(ctx, "ds-1", &CredentialRotationRequest{Credentials: map[string]interface{}{"password": "another"}}, "admin", "")
+	require.NoError(t, err)
+	require.NoError(t, db.Model(&models.DataSourceCredentialRotation{}).Where("status = ?", models.CredentialRotationActive).
+		Update("grace_until", time.Now().Add(-time.Minute)).Error)
+	rotations, err := s.ListRotations(ctx, "ds-1")
+	require.NoError(t, err)
+	require.Len(t, rotations, 2)
+	assert.Equal(t, models.CredentialRotationExpired, rotations[0].Status)
+	assert.Empty(t, rotations[0].PreviousSecrets)
+	_, err = s.Rollback(ctx, "ds-1", "admin", "")
+	assert.ErrorIs(t, err, ErrNoRollbackableRotation)
+}
@@ -0,0 +1,365 @@
+/*
+ * @module service/basic_library/datasource_circuit_service
+ * @description 数据源熔断，数据源连续拉取失败达到阈值后在冷却期内短路依赖同步任务的执行并告警，冷却结束后先探测数据源连接，探测成功才恢复执行
+ * @architecture 分层架构 - 业务服务层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow closed(累计连续失败) -> 达到阈值 -> open(拒绝执行，调度记录为skipped) -> 冷却结束后下一次执行 -> half_open(探测连接) -> 探测成功closed / 探测失败重新open
+ *            人工恢复：任意状态 -> closed
+ * @rules 只统计源端失败(网络、源系统不可用、认证、限流)，表结构、数据类型和约束等写入侧失败不计入也不清零；任一接口拉取成功即清零；
+ *        熔断状态保存在数据源上，多实例共享；冷却结束后只有一个调用方抢到半开状态执行探测，其他调用方继续被拒绝，探测方异常退出时半开超过冷却时间后可重新探测；
+ *        阈值和冷却时间取运行时配置datasource_circuit_threshold、datasource_circuit_cooldown_seconds，阈值为0时不熔断；打开和恢复写入事件，人工恢复写入审计日志
+ * @dependencies gorm.io/gorm, service/models, service/config, service/eventlog, service/interface_executor
+ * @refs sync_task_service.go, datasource_maintenance_service.go, service/interface_executor/failure_classification.go, api/controllers/datasource_circuit_controller.go
+ */
+
+package basic_library
+
+import (
+	"context"
+	"datahub-service/service/config"
+	"datahub-service/service/eventlog"
+	"datahub-service/service/interface_executor"
+	"datahub-service/service/meta"
+	"datahub-service/service/models"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// circuitSkipReason skipped执行记录中的跳过原因
+const circuitSkipReason = "datasource_circuit_open"
+
+// circuitErrorMaxRunes 保存的最近一次失败信息的最大字符数
+const circuitErrorMaxRunes = 1000
+
+// ErrDataSourceCircuitOpen 数据源已熔断
+var ErrDataSourceCircuitOpen = errors.New("数据源已熔断")
+
+// DataSourceProbeFunc 半开时探测数据源是否恢复，返回nil表示可用
+type DataSourceProbeFunc func(ctx context.Context, dataSource *models.DataSource) error
+
+// DataSourceCircuitService 数据源熔断服务
+type DataSourceCircuitService struct {
+	db    *gorm.DB
+	probe DataSourceProbeFunc
+}
+
+// NewDataSourceCircuitService 创建数据源熔断服务，probe为空时冷却结束直接恢复执行
+func NewDataSourceCircuitService(db *gorm.DB, probe DataSourceProbeFunc) *DataSourceCircuitService {
+	return &DataSourceCircuitService{db: db, probe: probe}
+}
+
+// DataSourceCircuitStatus 数据源熔断状态
+type DataSourceCircuitStatus struct {
+	DataSourceID        string     `json:"data_source_id"`
+	State               string     `json:"state" example:"open"` // closed, open, half_open
+	ConsecutiveFailures int        `json:"consecutive_failures"`
+	Threshold           int        `json:"threshold"`        // 熔断阈值，0表示不熔断
+	CooldownSeconds     int        `json:"cooldown_seconds"` // 冷却秒数
+	Since               *time.Time `json:"since,omitempty"`
+	RetryAt             *time.Time `json:"retry_at,omitempty"` // 熔断中时冷却结束、可以探测的时间
+	LastError           string     `json:"last_error,omitempty"`
+}
+
+// IsDataSourceFailure 判断失败是否由源端引起(网络、源系统不可用、认证、限流)，只有源端失败计入熔断
+func IsDataSourceFailure(classification interface_executor.FailureClassification) bool {
+	switch classification.Class {
+	case interface_executor.FailureClassNetwork, interface_executor.FailureClassAuth:
+		return true
+	}
+	return classification.Code == interface_executor.FailureCodeQuotaRateLimited
+}
+
+// GetCircuit 获取数据源熔断状态
+func (s *DataSourceCircuitService) GetCircuit(ctx context.Context, dataSourceID string) (*DataSourceCircuitStatus, error) {
+	var dataSource models.DataSource
+	if err := s.db.WithContext(ctx).First(&dataSource, "id = ?", dataSourceID).Error; err != nil {
+		return nil, err
+	}
+	return circuitStatus(&dataSource), nil
+}
+
+// Allow 检查数据源是否允许执行：熔断中且未过冷却期时返回ErrDataSourceCircuitOpen；冷却结束时抢占半开状态并探测，探测成功恢复执行
+func (s *DataSourceCircuitService) Allow(ctx context.Context, dataSourceID string) error {
+	if config.DataSourceCircuitThreshold() <= 0 {
+		return nil
+	}
+
+	var dataSource models.DataSource
+	if err := s.db.WithContext(ctx).Where("id = ?", dataSourceID).Limit(1).Find(&dataSource).Error; err != nil {
+		return fmt.Errorf("查询数据源熔断状态失败: %w", err)
+	}
+	if dataSource.ID == "" || !circuitOpen(dataSource.CircuitState) {
+		return nil
+	}
+
+	cooldown := config.DataSourceCircuitCooldown()
+	now := time.Now()
+	if dataSource.CircuitSince != nil && now.Before(dataSource.CircuitSince.Add(cooldown)) {
+		if dataSource.CircuitState == models.CircuitStateHalfOpen {
+			return fmt.Errorf("%w，正在探测数据源", ErrDataSourceCircuitOpen)
+		}
+		return fmt.Errorf("%w，%s后重试", ErrDataSourceCircuitOpen, dataSource.CircuitSince.Add(cooldown).Format(time.RFC3339))
+	}
+
+	// 冷却结束，只有抢到半开状态的调用方执行探测
+	claimed := s.db.WithContext(ctx).Model(&models.DataSource{}).
+		Where("id = ? AND circuit_state = ? AND (circuit_since IS NULL OR circuit_since <= ?)", dataSourceID, dataSource.CircuitState, now.Add(-cooldown)).
+		Updates(map[string]interface{}{"circuit_state": models.CircuitStateHalfOpen, "circuit_since": now})
+	if claimed.Error != nil {
+		return fmt.Errorf("更新数据源熔断状态失败: %w", claimed.Error)
+	}
+	if claimed.RowsAffected == 0 {
+		return fmt.Errorf("%w，正在探测数据源", ErrDataSourceCircuitOpen)
+	}
+
+	slog.Info("数据源熔断冷却结束，探测数据源", "datasource_id", dataSourceID)
+	if s.probe != nil {
+		if err := s.probe(ctx, &dataSource); err != nil {
+			s.open(ctx, &dataSource, dataSource.CircuitFailures, "探测失败: "+err.Error(), models.CircuitStateHalfOpen)
+			return fmt.Errorf("%w，探测失败: %v", ErrDataSourceCircuitOpen, err)
+		}
+	}
+	s.close(ctx, dataSourceID, "probe_succeeded", "")
+	return nil
+}
+
+// RecordSuccess 数据源拉取成功，清零连续失败次数；熔断中(执行在熔断前已开始)时结束熔断
+func (s *DataSourceCircuitService) RecordSuccess(ctx context.Context, dataSourceID string) {
+	if config.DataSourceCircuitThreshold() <= 0 {
+		return
+	}
+
+	var dataSource models.DataSource
+	if err := s.db.WithContext(ctx).Select("id", "circuit_state", "circuit_failures").
+		Where("id = ?", dataSourceID).Limit(1).Find(&dataSource).Error; err != nil || dataSource.ID == "" {
+		return
+	}
+	if circuitOpen(dataSource.CircuitState) {
+		s.close(ctx, dataSourceID, "sync_succeeded", "")
+		return
+	}
+	if dataSource.CircuitFailures > 0 {
+		if err := s.db.WithContext(ctx).Model(&models.DataSource{}).Where("id = ?", dataSourceID).
+			Update("circuit_failures", 0).Error; err != nil {
+			slog.Warn("清零数据源连续失败次数失败", "datasource_id", dataSourceID, "error", err)
+		}
+	}
+}
+
+// RecordFailure 数据源拉取失败，累计连续失败次数，达到阈值时熔断并告警
+func (s *DataSourceCircuitService) RecordFailure(ctx context.Context, dataSourceID, message string) {
+	threshold := config.DataSourceCircuitThreshold()
+	if threshold <= 0 {
+		return
+	}
+	message = truncateCircuitError(message)
+
+	db := s.db.WithContext(ctx)
+	counted := db.Model(&models.DataSource{}).
+		Where("id = ? AND (circuit_state = ? OR circuit_state = '' OR circuit_state IS NULL)", dataSourceID, models.CircuitStateClosed).
+		Updates(map[string]interface{}{"circuit_failures": gorm.Expr("circuit_failures + 1"), "circuit_last_error": message})
+	if counted.Error != nil {
+		slog.Warn("累计数据源连续失败次数失败", "datasource_id", dataSourceID, "error", counted.Error)
+		return
+	}
+	if counted.RowsAffected == 0 {
+		// 已熔断(执行在熔断前已开始)或数据源不存在，只更新最近一次失败信息
+		db.Model(&models.DataSource{}).Where("id = ?", dataSourceID).Update("circuit_last_error", message)
+		return
+	}
+
+	var dataSource models.DataSource
+	if err := db.First(&dataSource, "id = ?", dataSourceID).Error; err != nil {
+		return
+	}
+	if dataSource.CircuitFailures >= threshold {
+		s.open(ctx, &dataSource, dataSource.CircuitFailures, message, models.CircuitStateClosed)
+	}
+}
+
+// ResetCircuit 人工结束熔断，连续失败次数清零并写入审计日志
+func (s *DataSourceCircuitService) ResetCircuit(ctx context.Context, dataSourceID, operator, operatorIP string) (*DataSourceCircuitStatus, error) {
+	var previous string
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var dataSource models.DataSource
+		if err := tx.First(&dataSource, "id = ?", dataSourceID).Error; err != nil {
+			return err
+		}
+		previous = dataSource.CircuitState
+		if err := tx.Model(&dataSource).Updates(circuitClosedUpdates()).Error; err != nil {
+			return fmt.Errorf("更新数据源熔断状态失败: %w", err)
+		}
+		return s.audit(tx, "reset_circuit", dataSourceID, operator, operatorIP, models.JSONB{
+			"previous_state":       previous,
+			"consecutive_failures": dataSource.CircuitFailures,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if circuitOpen(previous) {
+		s.recordClosed(ctx, dataSourceID, "manual_reset", operator)
+	}
+	return s.GetCircuit(ctx, dataSourceID)
+}
+
+// open 数据源进入熔断状态并记录告警事件，from为期望的当前状态，状态已被其他调用方改变时不重复告警
+func (s *DataSourceCircuitService) open(ctx context.Context, dataSource *models.DataSource, failures int, message, from string) {
+	now := time.Now()
+	opened := s.db.WithContext(ctx).Model(&models.DataSource{}).
+		Where("id = ? AND circuit_state = ?", dataSource.ID, from).
+		Updates(map[string]interface{}{
+			"circuit_state":      models.CircuitStateOpen,
+			"circuit_since":      now,
+			"circuit_last_error": truncateCircuitError(message),
+		})
+	if opened.Error != nil {
+		slog.Error("更新数据源熔断状态失败", "datasource_id", dataSource.ID, "error", opened.Error)
+		return
+	}
+	if opened.RowsAffected == 0 {
+		return
+	}
+
+	cooldown := config.DataSourceCircuitCooldown()
+	slog.Warn("数据源已熔断", "datasource_id", dataSource.ID, "consecutive_failures", failures, "cooldown", cooldown, "error", message)
+	eventlog.Record(ctx, eventlog.Event{
+		Type:       eventlog.EventDataSourceCircuitOpened,
+		Level:      models.EventLevelError,
+		Message:    "数据源连续拉取失败，已熔断依赖的同步任务",
+		ObjectType: "data_source",
+		ObjectID:   dataSource.ID,
+		Attributes: map[string]interface{}{
+			"consecutive_failures": failures,
+			"threshold":            config.DataSourceCircuitThreshold(),
+			"cooldown_seconds":     int(cooldown.Seconds()),
+			"retry_at":             now.Add(cooldown).Format(time.RFC3339),
+			"probe_failed":         from == models.CircuitStateHalfOpen,
+			"error":                message,
+		},
+	})
+}
+
+// close 结束熔断并记录恢复事件
+func (s *DataSourceCircuitService) close(ctx context.Context, dataSourceID, reason, operator string) {
+	closed := s.db.WithContext(ctx).Model(&models.DataSource{}).
+		Where("id = ? AND circuit_state IN ?", dataSourceID, []string{models.CircuitStateOpen, models.CircuitStateHalfOpen}).
+		Updates(circuitClosedUpdates())
+	if closed.Error != nil {
+		slog.Error("更新数据源熔断状态失败", "datasource_id", dataSourceID, "error", closed.Error)
+		return
+	}
+	if closed.RowsAffected > 0 {
+		s.recordClosed(ctx, dataSourceID, reason, operator)
+	}
+}
+
+// recordClosed 记录熔断恢复事件
+func (s *DataSourceCircuitService) recordClosed(ctx context.Context, dataSourceID, reason, operator string) {
+	slog.Info("数据源熔断已恢复", "datasource_id", dataSourceID, "reason", reason)
+	attributes := map[string]interface{}{"reason": reason}
+	if operator != "" {
+		attributes["operator"] = operator
+	}
+	eventlog.Record(ctx, eventlog.Event{
+		Type:       eventlog.EventDataSourceCircuitClosed,
+		Message:    "数据源已恢复，结束熔断",
+		ObjectType: "data_source",
+		ObjectID:   dataSourceID,
+		Attributes: attributes,
+	})
+}
+
+// audit 写入熔断操作审计日志
+func (s *DataSourceCircuitService) audit(tx *gorm.DB, operation, dataSourceID, operator, operatorIP string, content models.JSONB) error {
+	log := &models.SystemLog{
+		OperationType:    operation,
+		ObjectType:       "data_source",
+		ObjectID:         &dataSourceID,
+		OperatorName:     &operator,
+		OperationContent: content,
+		OperationTime:    time.Now(),
+		OperationResult:  "success",
+		CreatedBy:        operator,
+	}
+	if operatorIP != "" {
+		log.OperatorIP = &operatorIP
+	}
+	if err := tx.Create(log).Error; err != nil {
+		return fmt.Errorf("写入审计日志失败: %w", err)
+	}
+	return nil
+}
+
+// circuitClosedUpdates 结束熔断时更新的字段
+func circuitClosedUpdates() map[string]interface{} {
+	return map[string]interface{}{
+		"circuit_state":    models.CircuitStateClosed,
+		"circuit_failures": 0,
+		"circuit_since":    nil,
+	}
+}
+
+// circuitOpen 熔断状态是否拒绝执行(open或half_open)
+func circuitOpen(state string) bool {
+	return state == models.CircuitStateOpen || state == models.CircuitStateHalfOpen
+}
+
+// circuitStatus 由数据源构造熔断状态
+func circuitStatus(dataSource *models.DataSource) *DataSourceCircuitStatus {
+	cooldown := config.DataSourceCircuitCooldown()
+	status := &DataSourceCircuitStatus{
+		DataSourceID:        dataSource.ID,
+		State:               dataSource.CircuitState,
+		ConsecutiveFailures: dataSource.CircuitFailures,
+		Threshold:           config.DataSourceCircuitThreshold(),
+		CooldownSeconds:     int(cooldown.Seconds()),
+		Since:               dataSource.CircuitSince,
+		LastError:           dataSource.CircuitLastError,
+	}
+	if status.State == "" {
+		status.State = models.CircuitStateClosed
+	}
+	if status.State == models.CircuitStateOpen && dataSource.CircuitSince != nil {
+		retryAt := dataSource.CircuitSince.Add(cooldown)
+		status.RetryAt = &retryAt
+	}
+	return status
+}
+
+// truncateCircuitError 截断失败信息以适应字段长度
+func truncateCircuitError(message string) string {
+	runes := []rune(message)
+	if len(runes) <= circuitErrorMaxRunes {
+		return message
+	}
+	return string(runes[:circuitErrorMaxRunes])
+}
+
+// skipForCircuit 数据源熔断中，本次调度写入skipped执行记录，不改变任务的执行状态
+func (s *SyncTaskService) skipForCircuit(ctx context.Context, task *models.SyncTask, cause error) {
+	now := time.Now()
+	execution := &models.SyncTaskExecution{
+		TaskID:        task.ID,
+		ExecutionType: "scheduled",
+		Status:        meta.SyncExecutionRecordStatusSkipped,
+		StartTime:     now,
+		EndTime:       &now,
+		ErrorMessage:  "数据源已熔断，跳过本次调度",
+		Result: models.JSONB{
+			"reason":         circuitSkipReason,
+			"data_source_id": task.DataSourceID,
+			"detail":         cause.Error(),
+		},
+	}
+	if err := s.db.WithContext(ctx).Create(execution).Error; err != nil {
+		slog.Error("写入跳过的执行记录失败", "task_id", task.ID, "error", err)
+		return
+	}
+	slog.Info("数据源已熔断，跳过调度", "task_id", task.ID, "datasource_id", task.DataSourceID, "execution_id", execution.ID)
+}
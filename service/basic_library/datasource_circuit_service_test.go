@@ -0,0 +1,168 @@
+/*
+ * @module service/basic_library/datasource_circuit_service_test
+ * @description 数据源熔断测试，覆盖连续源端失败达到阈值后熔断、写入侧失败不计入、冷却期内调度跳过和手动启动被拒绝、冷却结束后探测失败重新熔断和探测成功恢复，以及人工恢复
+ * @architecture 测试层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow 准备sqlite中的数据源和激活的间隔任务 -> 记录失败 -> 触发调度或手动启动 -> 调整熔断开始时间模拟冷却结束 -> 探测 -> 验证状态、执行记录和事件
+ * @rules 使用内存sqlite和默认运行时配置(阈值5次、冷却300秒)，探测由模拟函数代替，不启动调度器
+ * @dependencies gorm.io/driver/sqlite, stretchr/testify
+ * @refs datasource_circuit_service.go, sync_task_service.go
+ */
+
+package basic_library
+
+import (
+	"context"
+	"datahub-service/service/config"
+	"datahub-service/service/eventlog"
+	"datahub-service/service/interface_executor"
+	"datahub-service/service/meta"
+	"datahub-service/service/models"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+var networkFailure = interface_executor.FailureClassification{
+	Code: interface_executor.FailureCodeNetworkTimeout, Class: interface_executor.FailureClassNetwork, Retryable: true,
+}
+
+func circuitEvents(t *testing.T, db *gorm.DB, eventType string) int64 {
+	var count int64
+	require.NoError(t, db.Model(&models.ApplicationEvent{}).Where("event_type = ? AND object_id = ?", eventType, "ds-1").Count(&count).Error)
+	return count
+}
+
+// expireCooldown 把熔断开始时间提前到冷却期之前
+func expireCooldown(t *testing.T, db *gorm.DB) {
+	since := time.Now().Add(-config.DataSourceCircuitCooldown() - time.Second)
+	require.NoError(t, db.Model(&models.DataSource{}).Where("id = ?", "ds-1").Update("circuit_since", since).Error)
+}
+
+func TestDataSourceCircuit_OpenAndSkip(t *testing.T) {
+	db, _, tasks := setupMaintenanceDB(t)
+	svc := NewDataSourceCircuitService(db, nil)
+	tasks.SetCircuitBreaker(svc)
+	ctx := context.Background()
+	threshold := config.DataSourceCircuitThreshold()
+
+	// 写入侧失败不计入
+	tasks.recordDataSourceFailure(ctx, "ds-1", interface_executor.FailureClassification{
+		Code: interface_executor.FailureCodeConstraintUnique, Class: interface_executor.FailureClassConstraint,
+	}, "duplicate key")
+	status, err := svc.GetCircuit(ctx, "ds-1")
+	require.NoError(t, err)
+	assert.Equal(t, models.CircuitStateClosed, status.State)
+	assert.Zero(t, status.ConsecutiveFailures)
+
+	// 中途成功一次清零
+	for i := 0; i < threshold-1; i++ {
+		tasks.recordDataSourceFailure(ctx, "ds-1", networkFailure, "connection refused")
+	}
+	svc.RecordSuccess(ctx, "ds-1")
+	status, err = svc.GetCircuit(ctx, "ds-1")
+	require.NoError(t, err)
+	assert.Zero(t, status.ConsecutiveFailures)
+
+	for i := 0; i < threshold; i++ {
+		tasks.recordDataSourceFailure(ctx, "ds-1", networkFailure, "connection refused")
+	}
+	status, err = svc.GetCircuit(ctx, "ds-1")
+	require.NoError(t, err)
+	assert.Equal(t, models.CircuitStateOpen, status.State)
+	assert.Equal(t, threshold, status.ConsecutiveFailures)
+	assert.Equal(t, "connection refused", status.LastError)
+	require.NotNil(t, status.RetryAt)
+	assert.Equal(t, int64(1), circuitEvents(t, db, eventlog.EventDataSourceCircuitOpened))
+
+	// 熔断后的失败不重复告警
+	tasks.recordDataSourceFailure(ctx, "ds-1", networkFailure, "connection reset")
+	assert.Equal(t, int64(1), circuitEvents(t, db, eventlog.EventDataSourceCircuitOpened))
+
+	// 冷却期内调度跳过，手动启动被拒绝
+	tasks.executeScheduledTask("task-1")
+	var execution models.SyncTaskExecution
+	require.NoError(t, db.First(&execution, "task_id = ?", "task-1").Error)
+	assert.Equal(t, meta.SyncExecutionRecordStatusSkipped, execution.Status)
+	assert.Equal(t, circuitSkipReason, execution.Result["reason"])
+	var task models.SyncTask
+	require.NoError(t, db.First(&task, "id = ?", "task-1").Error)
+	assert.Equal(t, meta.SyncExecutionStatusIdle, task.ExecutionStatus)
+	assert.NotNil(t, task.NextRunTime, "跳过后推进下次执行时间")
+	assert.ErrorIs(t, tasks.StartSyncTask(ctx, "task-1"), ErrDataSourceCircuitOpen)
+}
+
+func TestDataSourceCircuit_HalfOpenProbe(t *testing.T) {
+	db, _, _ := setupMaintenanceDB(t)
+	probeErr := errors.New("dial tcp: connection refused")
+	probes := 0
+	svc := NewDataSourceCircuitService(db, func(ctx context.Context, dataSource *models.DataSource) error {
+		probes++
+		return probeErr
+	})
+	ctx := context.Background()
+
+	for i := 0; i < config.DataSourceCircuitThreshold(); i++ {
+		svc.RecordFailure(ctx, "ds-1", "timeout")
+	}
+	assert.ErrorIs(t, svc.Allow(ctx, "ds-1"), ErrDataSourceCircuitOpen)
+	assert.Zero(t, probes, "冷却期内不探测")
+
+	// 冷却结束后探测失败重新熔断并重新计算冷却时间
+	expireCooldown(t, db)
+	assert.ErrorIs(t, svc.Allow(ctx, "ds-1"), ErrDataSourceCircuitOpen)
+	assert.Equal(t, 1, probes)
+	status, err := svc.GetCircuit(ctx, "ds-1")
+	require.NoError(t, err)
+	assert.Equal(t, models.CircuitStateOpen, status.State)
+	assert.Contains(t, status.LastError, "探测失败")
+	assert.Equal(t, int64(2), circuitEvents(t, db, eventlog.EventDataSourceCircuitOpened))
+	assert.ErrorIs(t, svc.Allow(ctx, "ds-1"), ErrDataSourceCircuitOpen)
+	assert.Equal(t, 1, probes)
+
+	// 探测成功恢复执行
+	expireCooldown(t, db)
+	probeErr = nil
+	require.NoError(t, svc.Allow(ctx, "ds-1"))
+	assert.Equal(t, 2, probes)
+	status, err = svc.GetCircuit(ctx, "ds-1")
+	require.NoError(t, err)
+	assert.Equal(t, models.CircuitStateClosed, status.State)
+	assert.Zero(t, status.ConsecutiveFailures)
+	assert.Nil(t, status.Since)
+	assert.Equal(t, int64(1), circuitEvents(t, db, eventlog.EventDataSourceCircuitClosed))
+	require.NoError(t, svc.Allow(ctx, "ds-1"))
+	assert.Equal(t, 2, probes, "恢复后不再探测")
+}
+
+func TestDataSourceCircuit_ManualReset(t *testing.T) {
+	db, _, _ := setupMaintenanceDB(t)
+	svc := NewDataSourceCircuitService(db, nil)
+	ctx := context.Background()
+
+	for i := 0; i < config.DataSourceCircuitThreshold(); i++ {
+		svc.RecordFailure(ctx, "ds-1", "401 unauthorized")
+	}
+	status, err := svc.ResetCircuit(ctx, "ds-1", "admin", "10.0.0.1")
+	require.NoError(t, err)
+	assert.Equal(t, models.CircuitStateClosed, status.State)
+	assert.Zero(t, status.ConsecutiveFailures)
+	require.NoError(t, svc.Allow(ctx, "ds-1"))
+	assert.Equal(t, int64(1), circuitEvents(t, db, eventlog.EventDataSourceCircuitClosed))
+
+	var audit models.SystemLog
+	require.NoError(t, db.First(&audit, "object_type = ? AND object_id = ?", "data_source", "ds-1").Error)
+	assert.Equal(t, "reset_circuit", audit.OperationType)
+
+	// 未熔断时恢复不记录事件
+	_, err = svc.ResetCircuit(ctx, "ds-1", "admin", "")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), circuitEvents(t, db, eventlog.EventDataSourceCircuitClosed))
+
+	_, err = svc.ResetCircuit(ctx, "ds-x", "admin", "")
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+}
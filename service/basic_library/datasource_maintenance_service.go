@@ -0,0 +1,273 @@
+/*
+ * @module service/basic_library/datasource_maintenance_service
+ * @description 数据源维护模式，上游系统计划停机期间跳过依赖同步任务的调度并记录为skipped，结束维护时为跳过过的任务补跑一次
+ * @architecture 分层架构 - 业务服务层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow 开始维护(记录原因和开始时间) -> 调度触发时跳过并写入skipped执行记录 -> 结束维护 -> 维护期间跳过过的激活任务各补跑一次
+ * @rules 维护期间不改变任务的生命周期状态，调度照常推进下次执行时间，手动启动返回维护中错误；skipped执行记录不计为失败，也不产生失败事件；
+ *        维护期间接口过期不告警；开始和结束维护写入审计日志；补跑由调用方提供启动函数，单个任务补跑失败不影响其他任务
+ * @dependencies gorm.io/gorm, service/models, service/meta
+ * @refs sync_task_service.go, freshness_service.go, api/controllers/datasource_maintenance_controller.go
+ */
+
+package basic_library
+
+import (
+	"context"
+	"datahub-service/service/meta"
+	"datahub-service/service/models"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// maintenanceSkipReason skipped执行记录中的跳过原因
+const maintenanceSkipReason = "datasource_maintenance"
+
+var (
+	// ErrDataSourceInMaintenance 数据源处于维护模式
+	ErrDataSourceInMaintenance = errors.New("数据源处于维护模式")
+	// ErrDataSourceNotInMaintenance 数据源未处于维护模式
+	ErrDataSourceNotInMaintenance = errors.New("数据源未处于维护模式")
+)
+
+// DataSourceMaintenanceService 数据源维护模式服务
+type DataSourceMaintenanceService struct {
+	db *gorm.DB
+}
+
+// NewDataSourceMaintenanceService 创建数据源维护模式服务
+func NewDataSourceMaintenanceService(db *gorm.DB) *DataSourceMaintenanceService {
+	return &DataSourceMaintenanceService{db: db}
+}
+
+// DataSourceMaintenanceStatus 数据源维护状态
+type DataSourceMaintenanceStatus struct {
+	DataSourceID      string     `json:"data_source_id"`
+	MaintenanceMode   bool       `json:"maintenance_mode"`
+	Reason            string     `json:"reason,omitempty"`
+	Since             *time.Time `json:"since,omitempty"`
+	By                string     `json:"by,omitempty"`
+	AffectedTasks     int64      `json:"affected_tasks"`     // 依赖数据源的激活调度任务数
+	SkippedExecutions int64      `json:"skipped_executions"` // 本次维护期间跳过的调度次数
+}
+
+// MaintenanceCatchUpResult 结束维护时单个任务的补跑结果
+type MaintenanceCatchUpResult struct {
+	TaskID      string `json:"task_id"`
+	Success     bool   `json:"success"`
+	Error       string `json:"error,omitempty"`
+	OperationID string `json:"operation_id,omitempty"`
+}
+
+// EndMaintenanceResult 结束维护的结果
+type EndMaintenanceResult struct {
+	DataSourceID      string                     `json:"data_source_id"`
+	Since             *time.Time                 `json:"since,omitempty"`
+	EndedAt           time.Time                  `json:"ended_at"`
+	SkippedExecutions int64                      `json:"skipped_executions"`
+	CatchUp           []MaintenanceCatchUpResult `json:"catch_up"`
+}
+
+// GetMaintenance 获取数据源维护状态和受影响的任务数
+func (s *DataSourceMaintenanceService) GetMaintenance(ctx context.Context, dataSourceID string) (*DataSourceMaintenanceStatus, error) {
+	var dataSource models.DataSource
+	if err := s.db.WithContext(ctx).First(&dataSource, "id = ?", dataSourceID).Error; err != nil {
+		return nil, err
+	}
+
+	status := &DataSourceMaintenanceStatus{
+		DataSourceID:    dataSource.ID,
+		MaintenanceMode: dataSource.MaintenanceMode,
+		Reason:          dataSource.MaintenanceReason,
+		Since:           dataSource.MaintenanceSince,
+		By:              dataSource.MaintenanceBy,
+	}
+	if err := s.db.WithContext(ctx).Model(&models.SyncTask{}).
+		Where("data_source_id = ? AND library_type = ? AND status = ?", dataSourceID, meta.LibraryTypeBasic, meta.SyncTaskStatusActive).
+		Where("trigger_type IN ?", []string{meta.SyncTaskTriggerOnce, meta.SyncTaskTriggerInterval, meta.SyncTaskTriggerCron}).
+		Count(&status.AffectedTasks).Error; err != nil {
+		return nil, fmt.Errorf("统计依赖任务失败: %w", err)
+	}
+	if dataSource.MaintenanceMode && dataSource.MaintenanceSince != nil {
+		if err := skippedExecutions(s.db.WithContext(ctx), dataSourceID, *dataSource.MaintenanceSince).
+			Count(&status.SkippedExecutions).Error; err != nil {
+			return nil, fmt.Errorf("统计跳过的调度失败: %w", err)
+		}
+	}
+	return status, nil
+}
+
+// StartMaintenance 数据源进入维护模式，依赖任务的调度在结束维护前跳过
+func (s *DataSourceMaintenanceService) StartMaintenance(ctx context.Context, dataSourceID, reason, operator, operatorIP string) (*DataSourceMaintenanceStatus, error) {
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var dataSource models.DataSource
+		if err := tx.First(&dataSource, "id = ?", dataSourceID).Error; err != nil {
+			return err
+		}
+		if dataSource.MaintenanceMode {
+			return ErrDataSourceInMaintenance
+		}
+
+		now := time.Now()
+		if err := tx.Model(&dataSource).Updates(map[string]interface{}{
+			"maintenance_mode":   true,
+			"maintenance_reason": reason,
+			"maintenance_since":  now,
+			"maintenance_by":     operator,
+			"updated_at":         now,
+			"updated_by":         operator,
+		}).Error; err != nil {
+			return fmt.Errorf("更新数据源维护状态失败: %w", err)
+		}
+		return s.audit(tx, "start_maintenance", dataSourceID, operator, operatorIP, models.JSONB{"reason": reason})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	slog.Info("数据源进入维护模式", "datasource_id", dataSourceID, "reason", reason, "operator", operator)
+	return s.GetMaintenance(ctx, dataSourceID)
+}
+
+// EndMaintenance 数据源结束维护模式，catchUp为true时用run为维护期间跳过过调度的激活任务各补跑一次
+func (s *DataSourceMaintenanceService) EndMaintenance(ctx context.Context, dataSourceID string, catchUp bool, operator, operatorIP string, run SyncTaskRunFunc) (*EndMaintenanceResult, error) {
+	result := &EndMaintenanceResult{DataSourceID: dataSourceID, CatchUp: []MaintenanceCatchUpResult{}}
+	var taskIDs []string
+
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var dataSource models.DataSource
+		if err := tx.First(&dataSource, "id = ?", dataSourceID).Error; err != nil {
+			return err
+		}
+		if !dataSource.MaintenanceMode {
+			return ErrDataSourceNotInMaintenance
+		}
+		result.Since = dataSource.MaintenanceSince
+
+		if dataSource.MaintenanceSince != nil {
+			if err := skippedExecutions(tx, dataSourceID, *dataSource.MaintenanceSince).
+				Count(&result.SkippedExecutions).Error; err != nil {
+				return fmt.Errorf("统计跳过的调度失败: %w", err)
+			}
+			if err := skippedExecutions(tx, dataSourceID, *dataSource.MaintenanceSince).
+				Where("sync_tasks.status = ?", meta.SyncTaskStatusActive).
+				Distinct("sync_task_executions.task_id").Order("sync_task_executions.task_id").
+				Pluck("sync_task_executions.task_id", &taskIDs).Error; err != nil {
+				return fmt.Errorf("查询跳过调度的任务失败: %w", err)
+			}
+		}
+
+		result.EndedAt = time.Now()
+		if err := tx.Model(&dataSource).Updates(map[string]interface{}{
+			"maintenance_mode":   false,
+			"maintenance_reason": "",
+			"maintenance_since":  nil,
+			"maintenance_by":     "",
+			"updated_at":         result.EndedAt,
+			"updated_by":         operator,
+		}).Error; err != nil {
+			return fmt.Errorf("更新数据源维护状态失败: %w", err)
+		}
+		return s.audit(tx, "end_maintenance", dataSourceID, operator, operatorIP, models.JSONB{
+			"skipped_executions": result.SkippedExecutions,
+			"catch_up":           catchUp,
+			"catch_up_tasks":     len(taskIDs),
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	slog.Info("数据源结束维护模式", "datasource_id", dataSourceID, "skipped_executions", result.SkippedExecutions,
+		"catch_up_tasks", len(taskIDs), "operator", operator)
+	if !catchUp || run == nil {
+		return result, nil
+	}
+	for _, taskID := range taskIDs {
+		item := MaintenanceCatchUpResult{TaskID: taskID}
+		operationID, err := run(ctx, taskID)
+		if err != nil {
+			item.Error = err.Error()
+			slog.Warn("维护结束后补跑任务失败", "datasource_id", dataSourceID, "task_id", taskID, "error", err)
+		} else {
+			item.Success = true
+			item.OperationID = operationID
+		}
+		result.CatchUp = append(result.CatchUp, item)
+	}
+	return result, nil
+}
+
+// skippedExecutions 数据源的任务在维护开始后因维护跳过的执行记录
+func skippedExecutions(db *gorm.DB, dataSourceID string, since time.Time) *gorm.DB {
+	return db.Table("sync_task_executions").
+		Joins("JOIN sync_tasks ON sync_tasks.id = sync_task_executions.task_id").
+		Where("sync_tasks.data_source_id = ? AND sync_task_executions.status = ? AND sync_task_executions.start_time >= ?",
+			dataSourceID, meta.SyncExecutionRecordStatusSkipped, since)
+}
+
+// audit 写入维护模式审计日志
+func (s *DataSourceMaintenanceService) audit(tx *gorm.DB, operation, dataSourceID, operator, operatorIP string, content models.JSONB) error {
+	log := &models.SystemLog{
+		OperationType:    operation,
+		ObjectType:       "data_source",
+		ObjectID:         &dataSourceID,
+		OperatorName:     &operator,
+		OperationContent: content,
+		OperationTime:    time.Now(),
+		OperationResult:  "success",
+		CreatedBy:        operator,
+	}
+	if operatorIP != "" {
+		log.OperatorIP = &operatorIP
+	}
+	if err := tx.Create(log).Error; err != nil {
+		return fmt.Errorf("写入审计日志失败: %w", err)
+	}
+	return nil
+}
+
+// maintenanceDataSource 数据源处于维护模式时返回数据源，否则返回nil
+func maintenanceDataSource(ctx context.Context, db *gorm.DB, dataSourceID string) (*models.DataSource, error) {
+	var dataSource models.DataSource
+	err := db.WithContext(ctx).Select("id", "maintenance_mode", "maintenance_reason", "maintenance_since").
+		Where("id = ? AND maintenance_mode = ?", dataSourceID, true).Limit(1).Find(&dataSource).Error
+	if err != nil {
+		return nil, fmt.Errorf("查询数据源维护状态失败: %w", err)
+	}
+	if dataSource.ID == "" {
+		return nil, nil
+	}
+	return &dataSource, nil
+}
+
+// skipForMaintenance 数据源维护中，本次调度写入skipped执行记录，不改变任务的执行状态
+func (s *SyncTaskService) skipForMaintenance(ctx context.Context, task *models.SyncTask, dataSource *models.DataSource) {
+	now := time.Now()
+	result := models.JSONB{
+		"reason":             maintenanceSkipReason,
+		"data_source_id":     dataSource.ID,
+		"maintenance_reason": dataSource.MaintenanceReason,
+	}
+	if dataSource.MaintenanceSince != nil {
+		result["maintenance_since"] = dataSource.MaintenanceSince.Format(time.RFC3339)
+	}
+	execution := &models.SyncTaskExecution{
+		TaskID:        task.ID,
+		ExecutionType: "scheduled",
+		Status:        meta.SyncExecutionRecordStatusSkipped,
+		StartTime:     now,
+		EndTime:       &now,
+		ErrorMessage:  "数据源维护中，跳过本次调度",
+		Result:        result,
+	}
+	if err := s.db.WithContext(ctx).Create(execution).Error; err != nil {
+		slog.Error("写入跳过的执行记录失败", "task_id", task.ID, "error", err)
+		return
+	}
+	slog.Info("数据源维护中，跳过调度", "task_id", task.ID, "datasource_id", dataSource.ID, "execution_id", execution.ID)
+}
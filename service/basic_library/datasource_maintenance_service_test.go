@@ -0,0 +1,132 @@
+/*
+ * @module service/basic_library/datasource_maintenance_service_test
+ * @description 数据源维护模式测试，覆盖开启和结束维护、重复操作、调度跳过并写入skipped执行记录、手动启动被拒绝、结束时补跑，以及维护期间接口过期不告警
+ * @architecture 测试层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow 准备sqlite中的数据源和激活的间隔任务 -> 开启维护 -> 触发调度 -> 结束维护并补跑 -> 验证执行记录和补跑结果
+ * @rules 使用内存sqlite，补跑由记录任务ID的模拟函数代替，不启动调度器
+ * @dependencies gorm.io/driver/sqlite, stretchr/testify, github.com/robfig/cron/v3
+ * @refs datasource_maintenance_service.go, sync_task_service.go, freshness_service.go
+ */
+
+package basic_library
+
+import (
+	"context"
+	"datahub-service/service/eventlog"
+	"datahub-service/service/meta"
+	"datahub-service/service/models"
+	"testing"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func setupMaintenanceDB(t *testing.T) (*gorm.DB, *DataSourceMaintenanceService, *SyncTaskService) {
+	db := setupFreshnessDB(t)
+	require.NoError(t, db.AutoMigrate(&models.SystemLog{}))
+	require.NoError(t, db.Create(&models.SyncTask{
+		ID: "task-2", LibraryType: "basic_library", LibraryID: "lib-1", DataSourceID: "ds-1", TaskType: "batch_sync",
+		Status: "paused", TriggerType: "interval", IntervalSeconds: 3600,
+	}).Error)
+	tasks := &SyncTaskService{db: db, handler: NewBasicLibraryHandler(db, NewService(db, nil)), cron: cron.New(cron.WithSeconds()), ctx: context.Background()}
+	return db, NewDataSourceMaintenanceService(db), tasks
+}
+
+func TestDataSourceMaintenance_SkipAndCatchUp(t *testing.T) {
+	db, svc, tasks := setupMaintenanceDB(t)
+	ctx := context.Background()
+
+	status, err := svc.StartMaintenance(ctx, "ds-1", "停车系统升级", "admin", "10.0.0.1")
+	require.NoError(t, err)
+	assert.True(t, status.MaintenanceMode)
+	assert.Equal(t, "停车系统升级", status.Reason)
+	assert.Equal(t, int64(1), status.AffectedTasks, "只统计激活的调度任务")
+
+	_, err = svc.StartMaintenance(ctx, "ds-1", "", "admin", "")
+	assert.ErrorIs(t, err, ErrDataSourceInMaintenance)
+
+	// 调度触发时跳过，任务执行状态不变
+	tasks.executeScheduledTask("task-1")
+	var execution models.SyncTaskExecution
+	require.NoError(t, db.First(&execution, "task_id = ?", "task-1").Error)
+	assert.Equal(t, meta.SyncExecutionRecordStatusSkipped, execution.Status)
+	assert.Equal(t, maintenanceSkipReason, execution.Result["reason"])
+	assert.Equal(t, "停车系统升级", execution.Result["maintenance_reason"])
+	var task models.SyncTask
+	require.NoError(t, db.First(&task, "id = ?", "task-1").Error)
+	assert.Equal(t, meta.SyncExecutionStatusIdle, task.ExecutionStatus)
+	assert.NotNil(t, task.NextRunTime, "跳过后推进下次执行时间")
+
+	// 手动启动返回维护中
+	assert.ErrorIs(t, tasks.StartSyncTask(ctx, "task-1"), ErrDataSourceInMaintenance)
+
+	// 暂停的任务在维护期间也有跳过记录时不补跑
+	require.NoError(t, db.Create(&models.SyncTaskExecution{
+		TaskID: "task-2", ExecutionType: "scheduled", Status: meta.SyncExecutionRecordStatusSkipped, StartTime: time.Now(),
+	}).Error)
+
+	status, err = svc.GetMaintenance(ctx, "ds-1")
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), status.SkippedExecutions)
+
+	var started []string
+	result, err := svc.EndMaintenance(ctx, "ds-1", true, "admin", "10.0.0.1", func(ctx context.Context, taskID string) (string, error) {
+		started = append(started, taskID)
+		return "op-" + taskID, nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), result.SkippedExecutions)
+	assert.Equal(t, []string{"task-1"}, started)
+	require.Len(t, result.CatchUp, 1)
+	assert.True(t, result.CatchUp[0].Success)
+	assert.Equal(t, "op-task-1", result.CatchUp[0].OperationID)
+
+	var dataSource models.DataSource
+	require.NoError(t, db.First(&dataSource, "id = ?", "ds-1").Error)
+	assert.False(t, dataSource.MaintenanceMode)
+	assert.Nil(t, dataSource.MaintenanceSince)
+
+	var audits int64
+	require.NoError(t, db.Model(&models.SystemLog{}).Where("object_type = ? AND object_id = ?", "data_source", "ds-1").Count(&audits).Error)
+	assert.Equal(t, int64(2), audits)
+
+	_, err = svc.EndMaintenance(ctx, "ds-1", true, "admin", "", nil)
+	assert.ErrorIs(t, err, ErrDataSourceNotInMaintenance)
+}
+
+func TestDataSourceMaintenance_EndWithoutCatchUp(t *testing.T) {
+	_, svc, tasks := setupMaintenanceDB(t)
+	ctx := context.Background()
+
+	_, err := svc.StartMaintenance(ctx, "ds-1", "", "admin", "")
+	require.NoError(t, err)
+	tasks.executeScheduledTask("task-1")
+
+	called := false
+	result, err := svc.EndMaintenance(ctx, "ds-1", false, "admin", "", func(ctx context.Context, taskID string) (string, error) {
+		called = true
+		return "", nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), result.SkippedExecutions)
+	assert.Empty(t, result.CatchUp)
+	assert.False(t, called)
+}
+
+func TestDataSourceMaintenance_SuppressesFreshnessAlerts(t *testing.T) {
+	db, svc, _ := setupMaintenanceDB(t)
+	ctx := context.Background()
+	recordInterfaceSynced(ctx, db, "if-1", time.Now().Add(-3*time.Hour))
+
+	_, err := svc.StartMaintenance(ctx, "ds-1", "停车系统升级", "admin", "")
+	require.NoError(t, err)
+
+	result, err := NewFreshnessService(db).CheckAll(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Stale, "维护期间仍标记过期")
+	assert.Zero(t, countEvents(t, db, eventlog.EventInterfaceStale), "维护期间不告警")
+}
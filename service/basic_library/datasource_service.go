@@ -14,7 +14,9 @@ package basic_library
 import (
 	"context"
 	"datahub-service/service/datasource"
+	"datahub-service/service/meta"
 	"datahub-service/service/models"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"log/slog"
@@ -181,6 +183,37 @@ func (s *DatasourceService) UpdateDataSource(id string, updates map[string]inter
 	return nil
 }
 
+// SaveFixture 保存模拟数据源的一份样例，content为JSON文本，key为URL路径(可带方法前缀)，已存在时覆盖
+func (s *DatasourceService) SaveFixture(id, key string, content []byte) error {
+	var dataSource models.DataSource
+	if err := s.db.First(&dataSource, "id = ?", id).Error; err != nil {
+		return err
+	}
+	if dataSource.Type != meta.DataSourceTypeApiMock {
+		return fmt.Errorf("只有模拟数据源支持上传样例数据，当前类型: %s", dataSource.Type)
+	}
+
+	var payload interface{}
+	if err := json.Unmarshal(content, &payload); err != nil {
+		return fmt.Errorf("样例数据不是合法的JSON: %w", err)
+	}
+
+	connectionConfig := make(map[string]interface{}, len(dataSource.ConnectionConfig)+1)
+	for k, v := range dataSource.ConnectionConfig {
+		connectionConfig[k] = v
+	}
+	fixtures := make(map[string]interface{})
+	if existing, ok := connectionConfig[meta.DataSourceFieldFixtures].(map[string]interface{}); ok {
+		for k, v := range existing {
+			fixtures[k] = v
+		}
+	}
+	fixtures[key] = payload
+	connectionConfig[meta.DataSourceFieldFixtures] = fixtures
+
+	return s.UpdateDataSource(id, map[string]interface{}{"connection_config": connectionConfig})
+}
+
 // DeleteDataSource 删除数据源
 func (s *DatasourceService) DeleteDataSource(dataSource *models.DataSource) error {
 	// 检查是否存在关联的接口
@@ -330,6 +363,33 @@ func (s *DatasourceService) testConnection(dataSource *models.DataSource, startT
 	return result, nil
 }
 
+// TestCandidateConnection 使用候选配置测试数据源连接，不注册到管理器，也不更新数据源状态
+func (s *DatasourceService) TestCandidateConnection(ctx context.Context, dataSource *models.DataSource) error {
+	instance, err := s.datasourceManager.CreateTestInstance(dataSource.Type)
+	if err != nil {
+		return fmt.Errorf("创建数据源实例失败: %w", err)
+	}
+	if err := instance.Init(ctx, dataSource); err != nil {
+		return fmt.Errorf("初始化数据源失败: %w", err)
+	}
+	defer func() {
+		if instance.IsStarted() {
+			if err := instance.Stop(context.Background()); err != nil {
+				slog.Warn("停止测试数据源实例失败", "datasource_id", dataSource.ID, "error", err)
+			}
+		}
+	}()
+
+	healthStatus, err := instance.HealthCheck(ctx)
+	if err != nil {
+		return fmt.Errorf("数据源连接测试失败: %w", err)
+	}
+	if healthStatus.Status != "online" {
+		return fmt.Errorf("数据源连接测试失败: %s", healthStatus.Message)
+	}
+	return nil
+}
+
 // testDataPreview 测试数据预览
 func (s *DatasourceService) testDataPreview(dataSource *models.DataSource, config map[string]interface{}, startTime time.Time) (*DataSourceTestResult, error) {
 	result := &DataSourceTestResult{
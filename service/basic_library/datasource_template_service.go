@@ -0,0 +1,273 @@
+/*
+ * @module service/basic_library/datasource_template_service
+ * @description 数据源模板实例化，按模板目录在基础库中创建数据源和预置接口（接口配置、字段映射和接口表），用户只需填写地址和凭据
+ * @architecture 分层架构 - 业务服务层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow 校验模板、基础库和必填连接项 -> 检查接口英文名冲突 -> 创建数据源 -> 逐个创建接口并建表 -> 任一步失败时删除已创建的接口和数据源
+ * @rules 只接受模板RequiredFields中的连接配置项，其余连接配置取模板预置值；接口英文名可加前缀，在基础库中不能重复；
+ *        字段映射按模板字段的Source生成；数据源和接口的创建沿用基础库服务的校验和建表逻辑
+ * @dependencies datahub-service/service/meta, datahub-service/service/models, gorm.io/gorm
+ * @refs service/meta/datasource_template.go, datasource_service.go, interface_service.go, api/controllers/datasource_template_controller.go
+ */
+
+package basic_library
+
+import (
+	"context"
+	"datahub-service/service/meta"
+	"datahub-service/service/models"
+	"errors"
+	"fmt"
+	"log/slog"
+	"regexp"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrDataSourceTemplateNotFound 模板不存在
+	ErrDataSourceTemplateNotFound = errors.New("数据源模板不存在")
+	// ErrInvalidTemplateInstance 实例化请求不合法
+	ErrInvalidTemplateInstance = errors.New("数据源模板实例化请求不合法")
+)
+
+// templateInterfaceNamePattern 接口英文名即表名，只允许小写字母、数字和下划线
+var templateInterfaceNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_]{0,62}$`)
+
+// DataSourceProvisioner 创建和删除数据源、接口及接口表，由基础库服务实现
+type DataSourceProvisioner interface {
+	CreateDataSource(dataSource *models.DataSource) error
+	DeleteDataSource(dataSource *models.DataSource) error
+	CreateDataInterface(interfaceData *models.DataInterface) error
+	DeleteDataInterface(interfaceData *models.DataInterface) error
+	UpdateInterfaceFields(interfaceID string, fields []models.TableField, updateTable bool) error
+}
+
+// TemplateInstanceRequest 模板实例化请求
+type TemplateInstanceRequest struct {
+	LibraryID        string                 // 目标基础库
+	Name             string                 // 数据源名称，为空时使用模板名称
+	ConnectionConfig map[string]interface{} // 模板RequiredFields对应的地址和凭据
+	Interfaces       []string               // 要创建的模板接口英文名，为空时创建全部
+	InterfacePrefix  string                 // 接口英文名前缀，同一基础库中多次实例化同一模板时使用
+}
+
+// TemplateInstance 模板实例化结果
+type TemplateInstance struct {
+	TemplateID string                 `json:"template_id"`
+	DataSource *models.DataSource     `json:"data_source"`
+	Interfaces []models.DataInterface `json:"interfaces"`
+}
+
+// DataSourceTemplateService 数据源模板服务
+type DataSourceTemplateService struct {
+	db          *gorm.DB
+	provisioner DataSourceProvisioner
+}
+
+// NewDataSourceTemplateService 创建数据源模板服务
+func NewDataSourceTemplateService(db *gorm.DB, provisioner DataSourceProvisioner) *DataSourceTemplateService {
+	return &DataSourceTemplateService{db: db, provisioner: provisioner}
+}
+
+// ListTemplates 查询模板目录，subsystem为空时返回全部
+func (s *DataSourceTemplateService) ListTemplates(subsystem string) []*meta.DataSourceTemplate {
+	return meta.ListDataSourceTemplates(subsystem)
+}
+
+// GetTemplate 查询模板详情
+func (s *DataSourceTemplateService) GetTemplate(templateID string) (*meta.DataSourceTemplate, error) {
+	template, ok := meta.DataSourceTemplates[templateID]
+	if !ok {
+		return nil, ErrDataSourceTemplateNotFound
+	}
+	return template, nil
+}
+
+// Instantiate 按模板在基础库中创建数据源和接口
+func (s *DataSourceTemplateService) Instantiate(ctx context.Context, templateID string, req *TemplateInstanceRequest, operator string) (*TemplateInstance, error) {
+	template, err := s.GetTemplate(templateID)
+	if err != nil {
+		return nil, err
+	}
+	var library models.BasicLibrary
+	if err := s.db.WithContext(ctx).First(&library, "id = ?", req.LibraryID).Error; err != nil {
+		return nil, err
+	}
+	connectionConfig, err := buildTemplateConnectionConfig(template, req.ConnectionConfig)
+	if err != nil {
+		return nil, err
+	}
+	interfaces, err := selectTemplateInterfaces(template, req.Interfaces, req.InterfacePrefix)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, len(interfaces))
+	for i, iface := range interfaces {
+		names[i] = req.InterfacePrefix + iface.NameEn
+	}
+	var existing []string
+	if err := s.db.WithContext(ctx).Model(&models.DataInterface{}).
+		Where("library_id = ? AND name_en IN ?", req.LibraryID, names).Pluck("name_en", &existing).Error; err != nil {
+		return nil, fmt.Errorf("查询接口名称失败: %w", err)
+	}
+	if len(existing) > 0 {
+		return nil, fmt.Errorf("%w: 接口英文名在该基础库中已存在: %v，可通过接口名前缀区分", ErrInvalidTemplateInstance, existing)
+	}
+
+	name := req.Name
+	if name == "" {
+		name = template.Name
+	}
+	dataSource := &models.DataSource{
+		ID:               uuid.New().String(),
+		LibraryID:        req.LibraryID,
+		Name:             name,
+		Category:         template.DataSourceCategory,
+		Type:             template.DataSourceType,
+		Status:           "active",
+		ConnectionConfig: connectionConfig,
+		ParamsConfig:     copyTemplateConfig(template.ParamsConfig),
+		CreatedBy:        operator,
+		UpdatedBy:        operator,
+	}
+	if err := s.provisioner.CreateDataSource(dataSource); err != nil {
+		return nil, fmt.Errorf("创建数据源失败: %w", err)
+	}
+
+	instance := &TemplateInstance{TemplateID: template.ID, DataSource: dataSource, Interfaces: []models.DataInterface{}}
+	for _, templateInterface := range interfaces {
+		iface, err := s.createTemplateInterface(dataSource, templateInterface, req.InterfacePrefix, operator)
+		if iface != nil {
+			instance.Interfaces = append(instance.Interfaces, *iface)
+		}
+		if err != nil {
+			s.rollbackInstance(instance)
+			return nil, err
+		}
+	}
+
+	slog.Info("数据源模板实例化成功", "template_id", template.ID, "library_id", req.LibraryID,
+		"datasource_id", dataSource.ID, "interfaces", len(instance.Interfaces))
+	return instance, nil
+}
+
+// createTemplateInterface 创建模板接口并建表，接口记录已创建但建表失败时同时返回接口和错误
+func (s *DataSourceTemplateService) createTemplateInterface(dataSource *models.DataSource, templateInterface meta.DataSourceTemplateInterface, prefix, operator string) (*models.DataInterface, error) {
+	fieldMapping := make([]interface{}, len(templateInterface.Fields))
+	fields := make([]models.TableField, len(templateInterface.Fields))
+	for i, field := range templateInterface.Fields {
+		fieldMapping[i] = map[string]interface{}{"source": field.Source, "target": field.NameEn}
+		fields[i] = models.TableField{
+			NameZh:           field.NameZh,
+			NameEn:           field.NameEn,
+			DataType:         field.DataType,
+			IsPrimaryKey:     field.IsPrimaryKey,
+			IsNullable:       !field.IsPrimaryKey,
+			IsIncrementField: field.IsIncrementField,
+			IsIndexed:        field.IsIndexed,
+			IsSensitive:      field.IsSensitive,
+			OrderNum:         i + 1,
+		}
+	}
+
+	iface := &models.DataInterface{
+		ID:              uuid.New().String(),
+		LibraryID:       dataSource.LibraryID,
+		NameZh:          templateInterface.NameZh,
+		NameEn:          prefix + templateInterface.NameEn,
+		Type:            templateInterface.Type,
+		Description:     templateInterface.Description,
+		Status:          "active",
+		DataSourceID:    dataSource.ID,
+		InterfaceConfig: copyTemplateConfig(templateInterface.InterfaceConfig),
+		ParseConfig:     models.JSONB{"fieldMapping": fieldMapping},
+		CreatedBy:       operator,
+		UpdatedBy:       operator,
+	}
+	if err := s.provisioner.CreateDataInterface(iface); err != nil {
+		return nil, fmt.Errorf("创建接口%s失败: %w", iface.NameEn, err)
+	}
+	if err := s.provisioner.UpdateInterfaceFields(iface.ID, fields, true); err != nil {
+		return iface, fmt.Errorf("创建接口%s的数据表失败: %w", iface.NameEn, err)
+	}
+	iface.IsTableCreated = true
+	return iface, nil
+}
+
+// rollbackInstance 删除实例化过程中已创建的接口和数据源
+func (s *DataSourceTemplateService) rollbackInstance(instance *TemplateInstance) {
+	for i := range instance.Interfaces {
+		if err := s.provisioner.DeleteDataInterface(&instance.Interfaces[i]); err != nil {
+			slog.Error("删除模板实例化创建的接口失败", "interface_id", instance.Interfaces[i].ID, "error", err)
+		}
+	}
+	if err := s.provisioner.DeleteDataSource(instance.DataSource); err != nil {
+		slog.Error("删除模板实例化创建的数据源失败", "datasource_id", instance.DataSource.ID, "error", err)
+	}
+}
+
+// buildTemplateConnectionConfig 合并模板预置的连接配置和用户填写的必填项
+func buildTemplateConnectionConfig(template *meta.DataSourceTemplate, provided map[string]interface{}) (models.JSONB, error) {
+	allowed := make(map[string]bool, len(template.RequiredFields))
+	for _, field := range template.RequiredFields {
+		allowed[field.Name] = true
+	}
+	for key := range provided {
+		if !allowed[key] {
+			return nil, fmt.Errorf("%w: 模板不需要填写连接配置项 %s", ErrInvalidTemplateInstance, key)
+		}
+	}
+
+	config := copyTemplateConfig(template.ConnectionConfig)
+	for _, field := range template.RequiredFields {
+		value, exists := provided[field.Name]
+		if !exists || value == nil || value == "" {
+			if field.Required {
+				return nil, fmt.Errorf("%w: 缺少%s(%s)", ErrInvalidTemplateInstance, field.DisplayName, field.Name)
+			}
+			continue
+		}
+		config[field.Name] = value
+	}
+	return config, nil
+}
+
+// selectTemplateInterfaces 按英文名选择要创建的模板接口，并校验加前缀后的名称
+func selectTemplateInterfaces(template *meta.DataSourceTemplate, names []string, prefix string) ([]meta.DataSourceTemplateInterface, error) {
+	selected := template.Interfaces
+	if len(names) > 0 {
+		byName := make(map[string]meta.DataSourceTemplateInterface, len(template.Interfaces))
+		for _, iface := range template.Interfaces {
+			byName[iface.NameEn] = iface
+		}
+		selected = make([]meta.DataSourceTemplateInterface, 0, len(names))
+		seen := make(map[string]bool, len(names))
+		for _, name := range names {
+			iface, ok := byName[name]
+			if !ok {
+				return nil, fmt.Errorf("%w: 模板中没有接口 %s", ErrInvalidTemplateInstance, name)
+			}
+			if !seen[name] {
+				seen[name] = true
+				selected = append(selected, iface)
+			}
+		}
+	}
+	for _, iface := range selected {
+		if !templateInterfaceNamePattern.MatchString(prefix + iface.NameEn) {
+			return nil, fmt.Errorf("%w: 接口英文名 %s 只能包含小写字母、数字和下划线，以字母开头且不超过63个字符", ErrInvalidTemplateInstance, prefix+iface.NameEn)
+		}
+	}
+	return selected, nil
+}
+
+// copyTemplateConfig 深拷贝模板配置，避免实例修改影响模板目录
+func copyTemplateConfig(config map[string]interface{}) models.JSONB {
+	if config == nil {
+		return nil
+	}
+	return copyConnectionConfig(config)
+}
@@ -0,0 +1,158 @@
+/*
+ * @module service/basic_library/datasource_template_service_test
+ * @description 数据源模板测试，覆盖内置模板的连接配置校验、必填连接项和接口选择校验、实例化生成字段映射和表字段、接口名冲突以及建表失败时回滚
+ * @architecture 测试层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow 准备sqlite中的基础库 -> 按模板实例化 -> 验证数据源、接口和回滚
+ * @rules 使用内存sqlite，数据源和接口的创建由写入sqlite的模拟实现代替，不建真实数据表
+ * @dependencies gorm.io/driver/sqlite, stretchr/testify
+ * @refs datasource_template_service.go, service/meta/datasource_template.go
+ */
+
+package basic_library
+
+import (
+	"context"
+	"datahub-service/service/meta"
+	"datahub-service/service/models"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// fakeProvisioner 把数据源和接口写入sqlite，记录建表的字段
+type fakeProvisioner struct {
+	db          *gorm.DB
+	fields      map[string][]models.TableField
+	failTableOf string // 对该英文名的接口建表失败
+}
+
+func (f *fakeProvisioner) CreateDataSource(dataSource *models.DataSource) error {
+	return f.db.Create(dataSource).Error
+}
+
+func (f *fakeProvisioner) DeleteDataSource(dataSource *models.DataSource) error {
+	return f.db.Delete(dataSource).Error
+}
+
+func (f *fakeProvisioner) CreateDataInterface(interfaceData *models.DataInterface) error {
+	return f.db.Create(interfaceData).Error
+}
+
+func (f *fakeProvisioner) DeleteDataInterface(interfaceData *models.DataInterface) error {
+	return f.db.Delete(interfaceData).Error
+}
+
+func (f *fakeProvisioner) UpdateInterfaceFields(interfaceID string, fields []models.TableField, updateTable bool) error {
+	var iface models.DataInterface
+	if err := f.db.First(&iface, "id = ?", interfaceID).Error; err != nil {
+		return err
+	}
+	if iface.NameEn == f.failTableOf {
+		return errors.New("创建表结构失败")
+	}
+	f.fields[iface.NameEn] = fields
+	return nil
+}
+
+func TestDataSourceTemplatesValid(t *testing.T) {
+	require.NotEmpty(t, meta.DataSourceTemplates)
+	for id, template := range meta.DataSourceTemplates {
+		definition, ok := meta.DataSourceTypes[template.DataSourceType]
+		require.True(t, ok, "模板%s的数据源类型未注册", id)
+
+		provided := map[string]interface{}{}
+		for _, field := range template.RequiredFields {
+			provided[field.Name] = "value"
+		}
+		if _, ok := provided[meta.DataSourceFieldBaseUrl]; ok {
+			provided[meta.DataSourceFieldBaseUrl] = "https://park.example.com"
+		}
+		config, err := buildTemplateConnectionConfig(template, provided)
+		require.NoError(t, err)
+		result := definition.ValidateConfig(config, template.ParamsConfig)
+		assert.True(t, result.IsValid, "模板%s的连接配置校验失败: %v", id, result.Errors)
+
+		for _, iface := range template.Interfaces {
+			assert.Regexp(t, templateInterfaceNamePattern, iface.NameEn)
+			primaryKeys := 0
+			for _, field := range iface.Fields {
+				assert.NotEmpty(t, field.Source)
+				if field.IsPrimaryKey {
+					primaryKeys++
+				}
+			}
+			assert.Equal(t, 1, primaryKeys, "模板%s接口%s须有且只有一个主键", id, iface.NameEn)
+		}
+	}
+}
+
+func TestBuildTemplateConnectionConfig(t *testing.T) {
+	template := meta.DataSourceTemplates["parking_db"]
+	_, err := buildTemplateConnectionConfig(template, map[string]interface{}{"host": "10.0.0.5"})
+	assert.ErrorIs(t, err, ErrInvalidTemplateInstance, "缺少必填项")
+
+	_, err = buildTemplateConnectionConfig(template, map[string]interface{}{
+		"host": "10.0.0.5", "database": "parking", "username": "reader", "password": "x", "port": 6432,
+	})
+	assert.ErrorIs(t, err, ErrInvalidTemplateInstance, "不接受模板预置的配置项")
+
+	config, err := buildTemplateConnectionConfig(template, map[string]interface{}{
+		"host": "10.0.0.5", "database": "parking", "username": "reader", "password": "x",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "10.0.0.5", config["host"])
+	assert.Equal(t, float64(5432), config["port"])
+	config["port"] = float64(1)
+	assert.Equal(t, float64(5432), template.ConnectionConfig["port"], "不修改模板目录")
+}
+
+func TestInstantiateDataSourceTemplate(t *testing.T) {
+	db, _ := setupDiagnosticsDB(t)
+	require.NoError(t, db.AutoMigrate(&models.BasicLibrary{}))
+	require.NoError(t, db.Create(&models.BasicLibrary{ID: "lib-park", NameZh: "园区基础库", NameEn: "park"}).Error)
+	provisioner := &fakeProvisioner{db: db, fields: map[string][]models.TableField{}}
+	s := NewDataSourceTemplateService(db, provisioner)
+	ctx := context.Background()
+
+	credentials := map[string]interface{}{"host": "10.0.0.5", "database": "parking", "username": "reader", "password": "x"}
+	_, err := s.Instantiate(ctx, "unknown", &TemplateInstanceRequest{LibraryID: "lib-park"}, "admin")
+	assert.ErrorIs(t, err, ErrDataSourceTemplateNotFound)
+	_, err = s.Instantiate(ctx, "parking_db", &TemplateInstanceRequest{LibraryID: "lib-park", ConnectionConfig: credentials, Interfaces: []string{"unknown"}}, "admin")
+	assert.ErrorIs(t, err, ErrInvalidTemplateInstance)
+
+	instance, err := s.Instantiate(ctx, "parking_db", &TemplateInstanceRequest{
+		LibraryID: "lib-park", ConnectionConfig: credentials, Interfaces: []string{"parking_records"},
+	}, "admin")
+	require.NoError(t, err)
+	assert.Equal(t, "停车管理系统数据库", instance.DataSource.Name)
+	assert.Equal(t, meta.DataSourceTypeDBPostgreSQL, instance.DataSource.Type)
+	require.Len(t, instance.Interfaces, 1)
+	iface := instance.Interfaces[0]
+	assert.Equal(t, "parking_records", iface.NameEn)
+	assert.Equal(t, instance.DataSource.ID, iface.DataSourceID)
+	assert.Equal(t, "parking_record", iface.InterfaceConfig["table_name"])
+	mapping := iface.ParseConfig["fieldMapping"].([]interface{})
+	assert.Equal(t, map[string]interface{}{"source": "plate_no", "target": "plate_no"}, mapping[2])
+	fields := provisioner.fields["parking_records"]
+	require.NotEmpty(t, fields)
+	assert.True(t, fields[0].IsPrimaryKey)
+	assert.False(t, fields[0].IsNullable)
+	assert.True(t, fields[2].IsSensitive)
+
+	_, err = s.Instantiate(ctx, "parking_db", &TemplateInstanceRequest{LibraryID: "lib-park", ConnectionConfig: credentials}, "admin")
+	assert.ErrorIs(t, err, ErrInvalidTemplateInstance, "接口英文名已存在")
+
+	// 第二个接口建表失败时删除已创建的接口和数据源
+	provisioner.failTableOf = "p2_parking_records"
+	_, err = s.Instantiate(ctx, "parking_db", &TemplateInstanceRequest{LibraryID: "lib-park", ConnectionConfig: credentials, InterfacePrefix: "p2_"}, "admin")
+	require.Error(t, err)
+	var dataSources, interfaces int64
+	require.NoError(t, db.Model(&models.DataSource{}).Where("library_id = ?", "lib-park").Count(&dataSources).Error)
+	require.NoError(t, db.Model(&models.DataInterface{}).Where("library_id = ?", "lib-park").Count(&interfaces).Error)
+	assert.Equal(t, int64(1), dataSources, "只保留第一次实例化的数据源")
+	assert.Equal(t, int64(1), interfaces)
+}
@@ -0,0 +1,272 @@
+/*
+ * @module service/basic_library/datasource_usage_service
+ * @description 数据源使用情况报告，列出依赖数据源的接口、同步任务和主题同步任务及各自最近使用时间，供下线或轮换上游系统前评估影响
+ * @architecture 分层架构 - 业务服务层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow 查询数据源 -> 查询使用该数据源的接口及最近成功同步时间 -> 查询引用数据源或其接口的同步任务 -> 查询源配置包含这些接口的主题同步任务 -> 汇总
+ * @rules 只读查询；接口最近使用时间取最近成功同步时间；主题同步任务只识别接口模式的源配置，SQL模式不计入；
+ *        激活状态的同步任务和主题同步任务计为活跃依赖，活跃依赖为0时才建议下线
+ * @dependencies datahub-service/service/models, datahub-service/service/tenant, gorm.io/gorm
+ * @refs freshness_service.go, service/models/thematic_sync.go, api/controllers/datasource_usage_controller.go
+ */
+
+package basic_library
+
+import (
+	"context"
+	"datahub-service/service/models"
+	"datahub-service/service/tenant"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/spf13/cast"
+	"gorm.io/gorm"
+)
+
+// DataSourceUsage 数据源使用情况
+type DataSourceUsage struct {
+	DataSourceID   string                     `json:"data_source_id"`
+	DataSourceName string                     `json:"data_source_name"`
+	DataSourceType string                     `json:"data_source_type"`
+	LibraryID      string                     `json:"library_id"`
+	LastUsedAt     *time.Time                 `json:"last_used_at,omitempty"` // 接口最近成功同步时间和数据源最近同步时间中的最晚值
+	Summary        DataSourceUsageSummary     `json:"summary"`
+	Interfaces     []DataSourceInterfaceUsage `json:"interfaces"`
+	SyncTasks      []DataSourceSyncTaskUsage  `json:"sync_tasks"`
+	ThematicFlows  []DataSourceThematicUsage  `json:"thematic_flows"`
+	GeneratedAt    time.Time                  `json:"generated_at"`
+}
+
+// DataSourceUsageSummary 数据源使用情况汇总
+type DataSourceUsageSummary struct {
+	Interfaces          int  `json:"interfaces"`
+	SyncTasks           int  `json:"sync_tasks"`
+	ActiveSyncTasks     int  `json:"active_sync_tasks"`
+	ThematicFlows       int  `json:"thematic_flows"`
+	ActiveThematicFlows int  `json:"active_thematic_flows"`
+	SafeToDecommission  bool `json:"safe_to_decommission"` // 没有激活的同步任务和主题同步任务
+}
+
+// DataSourceInterfaceUsage 使用数据源的接口
+type DataSourceInterfaceUsage struct {
+	InterfaceID  string     `json:"interface_id"`
+	NameZh       string     `json:"name_zh"`
+	NameEn       string     `json:"name_en"`
+	Status       string     `json:"status"`
+	LastSyncedAt *time.Time `json:"last_synced_at,omitempty"` // 最近成功同步时间
+}
+
+// DataSourceSyncTaskUsage 依赖数据源的同步任务
+type DataSourceSyncTaskUsage struct {
+	TaskID          string     `json:"task_id"`
+	Status          string     `json:"status"`
+	ExecutionStatus string     `json:"execution_status"`
+	TriggerType     string     `json:"trigger_type"`
+	InterfaceIDs    []string   `json:"interface_ids"` // 任务中使用该数据源的接口
+	LastRunAt       *time.Time `json:"last_run_at,omitempty"`
+	NextRunAt       *time.Time `json:"next_run_at,omitempty"`
+}
+
+// DataSourceThematicUsage 读取数据源接口的主题同步任务
+type DataSourceThematicUsage struct {
+	TaskID              string     `json:"task_id"`
+	TaskName            string     `json:"task_name"`
+	Status              string     `json:"status"`
+	ThematicLibraryID   string     `json:"thematic_library_id"`
+	ThematicInterfaceID string     `json:"thematic_interface_id"`
+	InterfaceIDs        []string   `json:"interface_ids"` // 读取的该数据源的接口
+	LastSyncAt          *time.Time `json:"last_sync_at,omitempty"`
+	LastSyncStatus      string     `json:"last_sync_status,omitempty"`
+}
+
+// DataSourceUsageService 数据源使用情况服务
+type DataSourceUsageService struct {
+	db *gorm.DB
+}
+
+// NewDataSourceUsageService 创建数据源使用情况服务
+func NewDataSourceUsageService(db *gorm.DB) *DataSourceUsageService {
+	return &DataSourceUsageService{db: db}
+}
+
+// GetUsage 获取依赖数据源的接口、同步任务和主题同步任务
+func (s *DataSourceUsageService) GetUsage(ctx context.Context, dataSourceID string) (*DataSourceUsage, error) {
+	db := s.db.WithContext(ctx)
+	var dataSource models.DataSource
+	if err := db.Scopes(tenant.OwnerScope(ctx, "library_id", "basic_libraries")).
+		First(&dataSource, "id = ?", dataSourceID).Error; err != nil {
+		return nil, err
+	}
+
+	usage := &DataSourceUsage{
+		DataSourceID:   dataSource.ID,
+		DataSourceName: dataSource.Name,
+		DataSourceType: dataSource.Type,
+		LibraryID:      dataSource.LibraryID,
+		Interfaces:     []DataSourceInterfaceUsage{},
+		SyncTasks:      []DataSourceSyncTaskUsage{},
+		ThematicFlows:  []DataSourceThematicUsage{},
+		GeneratedAt:    time.Now(),
+	}
+
+	var interfaces []models.DataInterface
+	if err := db.Where("data_source_id = ?", dataSourceID).Order("name_zh").Find(&interfaces).Error; err != nil {
+		return nil, fmt.Errorf("查询数据源接口失败: %w", err)
+	}
+	interfaceIDs := make([]string, len(interfaces))
+	isSourceInterface := make(map[string]bool, len(interfaces))
+	for i, iface := range interfaces {
+		interfaceIDs[i] = iface.ID
+		isSourceInterface[iface.ID] = true
+	}
+
+	lastSynced := make(map[string]*time.Time, len(interfaces))
+	if len(interfaceIDs) > 0 {
+		var freshness []models.InterfaceFreshness
+		if err := db.Where("interface_id IN ?", interfaceIDs).Find(&freshness).Error; err != nil {
+			return nil, fmt.Errorf("查询接口同步时间失败: %w", err)
+		}
+		for _, item := range freshness {
+			lastSynced[item.InterfaceID] = item.LastSuccessAt
+		}
+	}
+	for _, iface := range interfaces {
+		usage.Interfaces = append(usage.Interfaces, DataSourceInterfaceUsage{
+			InterfaceID:  iface.ID,
+			NameZh:       iface.NameZh,
+			NameEn:       iface.NameEn,
+			Status:       iface.Status,
+			LastSyncedAt: lastSynced[iface.ID],
+		})
+		usage.LastUsedAt = laterTime(usage.LastUsedAt, lastSynced[iface.ID])
+	}
+	var status models.DataSourceStatus
+	if result := db.Select("last_sync_time").Where("data_source_id = ?", dataSourceID).Limit(1).Find(&status); result.Error == nil && result.RowsAffected > 0 {
+		usage.LastUsedAt = laterTime(usage.LastUsedAt, status.LastSyncTime)
+	}
+
+	if err := s.collectSyncTasks(ctx, usage, dataSourceID, interfaceIDs, isSourceInterface); err != nil {
+		return nil, err
+	}
+	if err := s.collectThematicFlows(ctx, usage, isSourceInterface); err != nil {
+		return nil, err
+	}
+
+	usage.Summary.Interfaces = len(usage.Interfaces)
+	usage.Summary.SyncTasks = len(usage.SyncTasks)
+	usage.Summary.ThematicFlows = len(usage.ThematicFlows)
+	usage.Summary.SafeToDecommission = usage.Summary.ActiveSyncTasks == 0 && usage.Summary.ActiveThematicFlows == 0
+	return usage, nil
+}
+
+// collectSyncTasks 查询数据源或其接口上的同步任务
+func (s *DataSourceUsageService) collectSyncTasks(ctx context.Context, usage *DataSourceUsage, dataSourceID string, interfaceIDs []string, isSourceInterface map[string]bool) error {
+	query := s.db.WithContext(ctx).Preload("TaskInterfaces")
+	if len(interfaceIDs) > 0 {
+		query = query.Where("data_source_id = ? OR id IN (?)", dataSourceID,
+			s.db.Model(&models.SyncTaskInterface{}).Select("task_id").Where("interface_id IN ?", interfaceIDs))
+	} else {
+		query = query.Where("data_source_id = ?", dataSourceID)
+	}
+	var tasks []models.SyncTask
+	if err := query.Order("created_at").Find(&tasks).Error; err != nil {
+		return fmt.Errorf("查询同步任务失败: %w", err)
+	}
+
+	for _, task := range tasks {
+		item := DataSourceSyncTaskUsage{
+			TaskID:          task.ID,
+			Status:          task.Status,
+			ExecutionStatus: task.ExecutionStatus,
+			TriggerType:     task.TriggerType,
+			InterfaceIDs:    []string{},
+			LastRunAt:       task.LastRunTime,
+			NextRunAt:       task.NextRunTime,
+		}
+		for _, taskInterface := range task.TaskInterfaces {
+			if isSourceInterface[taskInterface.InterfaceID] {
+				item.InterfaceIDs = append(item.InterfaceIDs, taskInterface.InterfaceID)
+			}
+		}
+		if task.Status == "active" {
+			usage.Summary.ActiveSyncTasks++
+		}
+		usage.SyncTasks = append(usage.SyncTasks, item)
+	}
+	return nil
+}
+
+// collectThematicFlows 查询源配置读取数据源接口的主题同步任务
+func (s *DataSourceUsageService) collectThematicFlows(ctx context.Context, usage *DataSourceUsage, isSourceInterface map[string]bool) error {
+	if len(isSourceInterface) == 0 {
+		return nil
+	}
+	var tasks []models.ThematicSyncTask
+	if err := s.db.WithContext(ctx).Select("id", "task_name", "status", "thematic_library_id", "thematic_interface_id", "source_libraries", "last_sync_time", "last_sync_status").
+		Order("created_at").Find(&tasks).Error; err != nil {
+		return fmt.Errorf("查询主题同步任务失败: %w", err)
+	}
+
+	for _, task := range tasks {
+		var matched []string
+		for _, interfaceID := range thematicSourceInterfaceIDs(task.SourceLibraries) {
+			if isSourceInterface[interfaceID] {
+				matched = append(matched, interfaceID)
+			}
+		}
+		if len(matched) == 0 {
+			continue
+		}
+		if task.Status == "active" {
+			usage.Summary.ActiveThematicFlows++
+		}
+		usage.ThematicFlows = append(usage.ThematicFlows, DataSourceThematicUsage{
+			TaskID:              task.ID,
+			TaskName:            task.TaskName,
+			Status:              task.Status,
+			ThematicLibraryID:   task.ThematicLibraryID,
+			ThematicInterfaceID: task.ThematicInterfaceID,
+			InterfaceIDs:        matched,
+			LastSyncAt:          task.LastSyncTime,
+			LastSyncStatus:      task.LastSyncStatus,
+		})
+	}
+	return nil
+}
+
+// thematicSourceInterfaceIDs 主题同步任务源配置中的接口ID，兼容按库嵌套interfaces和直接配置interface_id两种格式
+func thematicSourceInterfaceIDs(sourceLibraries models.JSONBGenericArray) []string {
+	seen := make(map[string]bool)
+	for _, raw := range sourceLibraries {
+		library, ok := raw.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if id := cast.ToString(library["interface_id"]); id != "" {
+			seen[id] = true
+		}
+		interfaces, _ := library["interfaces"].([]interface{})
+		for _, item := range interfaces {
+			if iface, ok := item.(map[string]interface{}); ok {
+				if id := cast.ToString(iface["interface_id"]); id != "" {
+					seen[id] = true
+				}
+			}
+		}
+	}
+	ids := make([]string, 0, len(seen))
+	for id := range seen {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// laterTime 返回两个时间中较晚的一个，均为空时返回nil
+func laterTime(a, b *time.Time) *time.Time {
+	if a == nil || (b != nil && b.After(*a)) {
+		return b
+	}
+	return a
+}
@@ -0,0 +1,94 @@
+/*
+ * @module service/basic_library/datasource_usage_service_test
+ * @description 数据源使用情况测试，覆盖接口最近同步时间、按数据源和接口识别同步任务、按源配置识别主题同步任务以及是否可下线的判断
+ * @architecture 测试层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow 准备sqlite中的数据源、接口、同步任务和主题同步任务 -> 查询使用情况 -> 验证依赖和汇总
+ * @rules 使用内存sqlite，不依赖外部服务
+ * @dependencies gorm.io/driver/sqlite, stretchr/testify
+ * @refs datasource_usage_service.go
+ */
+
+package basic_library
+
+import (
+	"context"
+	"datahub-service/service/models"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestDataSourceUsage(t *testing.T) {
+	db, _ := setupDiagnosticsDB(t)
+	require.NoError(t, db.AutoMigrate(&models.InterfaceFreshness{}, &models.ThematicSyncTask{}))
+	s := NewDataSourceUsageService(db)
+	ctx := context.Background()
+
+	syncedAt := time.Now().Add(-2 * time.Hour).Truncate(time.Second)
+	require.NoError(t, db.Create(&models.InterfaceFreshness{InterfaceID: "if-1", LastSuccessAt: &syncedAt, UpdatedAt: time.Now()}).Error)
+
+	// 其他数据源上的任务引用了本数据源的接口
+	require.NoError(t, db.Create(&models.SyncTask{
+		ID: "task-2", LibraryType: "basic_library", LibraryID: "lib-1", DataSourceID: "ds-other", TaskType: "batch_sync",
+		TaskInterfaces: []models.SyncTaskInterface{{ID: "ti-2", InterfaceID: "if-1"}, {ID: "ti-3", InterfaceID: "if-x"}},
+	}).Error)
+	require.NoError(t, db.Create(&models.SyncTask{
+		ID: "task-3", LibraryType: "basic_library", LibraryID: "lib-1", DataSourceID: "ds-other", TaskType: "batch_sync", Status: "active",
+	}).Error)
+	require.NoError(t, db.Create(&[]models.ThematicSyncTask{
+		{
+			ID: "tt-1", ThematicLibraryID: "tl-1", ThematicInterfaceID: "ti-a", TaskName: "车辆主题", TriggerType: "manual", Status: "active",
+			SourceLibraries: models.JSONBGenericArray{map[string]interface{}{"library_id": "lib-1", "interfaces": []interface{}{map[string]interface{}{"interface_id": "if-1"}}}},
+		},
+		{
+			ID: "tt-2", ThematicLibraryID: "tl-1", ThematicInterfaceID: "ti-b", TaskName: "其他主题", TriggerType: "manual", Status: "active",
+			SourceLibraries: models.JSONBGenericArray{map[string]interface{}{"library_id": "lib-1", "interface_id": "if-x"}},
+		},
+	}).Error)
+
+	usage, err := s.GetUsage(ctx, "ds-1")
+	require.NoError(t, err)
+	require.Len(t, usage.Interfaces, 1)
+	require.NotNil(t, usage.Interfaces[0].LastSyncedAt)
+	assert.True(t, syncedAt.Equal(*usage.Interfaces[0].LastSyncedAt))
+	assert.True(t, syncedAt.Equal(*usage.LastUsedAt))
+
+	taskIDs := make([]string, len(usage.SyncTasks))
+	for i, task := range usage.SyncTasks {
+		taskIDs[i] = task.TaskID
+	}
+	assert.ElementsMatch(t, []string{"task-1", "task-2"}, taskIDs, "按数据源和按接口引用的任务，不含无关任务")
+	for _, task := range usage.SyncTasks {
+		assert.Equal(t, []string{"if-1"}, task.InterfaceIDs)
+	}
+
+	require.Len(t, usage.ThematicFlows, 1)
+	assert.Equal(t, "tt-1", usage.ThematicFlows[0].TaskID)
+	assert.Equal(t, []string{"if-1"}, usage.ThematicFlows[0].InterfaceIDs)
+	assert.Equal(t, 1, usage.Summary.ActiveThematicFlows)
+	assert.Equal(t, 0, usage.Summary.ActiveSyncTasks)
+	assert.False(t, usage.Summary.SafeToDecommission)
+
+	require.NoError(t, db.Model(&models.ThematicSyncTask{}).Where("id = ?", "tt-1").Update("status", "paused").Error)
+	usage, err = s.GetUsage(ctx, "ds-1")
+	require.NoError(t, err)
+	assert.True(t, usage.Summary.SafeToDecommission)
+
+	_, err = s.GetUsage(ctx, "ds-missing")
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+}
+
+func TestThematicSourceInterfaceIDs(t *testing.T) {
+	ids := thematicSourceInterfaceIDs(models.JSONBGenericArray{
+		map[string]interface{}{"library_id": "lib-1", "interfaces": []interface{}{
+			map[string]interface{}{"interface_id": "b"}, map[string]interface{}{"interface_id": "a"},
+		}},
+		map[string]interface{}{"library_id": "lib-2", "interface_id": "a"},
+		"invalid",
+	})
+	assert.Equal(t, []string{"a", "b"}, ids)
+}
@@ -0,0 +1,171 @@
+/*
+ * @module service/basic_library/delete_propagation_service
+ * @description 接口源端删除同步配置服务，设置识别源端删除的方式和接口表中的处理方式，软删除时为接口表增加删除时间列，归档时创建归档表
+ * @architecture 分层架构 - 业务服务层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow 设置：校验识别方式和处理方式 -> 软删除时字段配置追加_deleted_at并修改表结构 / 归档时创建同结构的归档表 -> 保存配置；关闭：移除配置
+ * @rules 按接口版本条件保存配置，版本不一致返回冲突；不能与历史追踪同时开启；接口表必须有主键；关闭或改为其他处理方式时保留软删除列和归档表，已删除的数据不恢复
+ * @dependencies datahub-service/service/database, datahub-service/service/models, gorm.io/gorm
+ * @refs service/models/delete_propagation.go, service/interface_executor/delete_propagation.go, api/controllers/delete_propagation_controller.go
+ */
+
+package basic_library
+
+import (
+	"context"
+	"datahub-service/service/database"
+	"datahub-service/service/models"
+	"datahub-service/service/utils"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"gorm.io/gorm"
+)
+
+// ErrInvalidDeletePropagationRequest 删除同步配置不合法
+var ErrInvalidDeletePropagationRequest = errors.New("删除同步配置不合法")
+
+// DeletePropagationService 接口源端删除同步配置服务
+type DeletePropagationService struct {
+	db            *gorm.DB
+	schemaService *database.SchemaService
+}
+
+// NewDeletePropagationService 创建接口源端删除同步配置服务
+func NewDeletePropagationService(db *gorm.DB) *DeletePropagationService {
+	return &DeletePropagationService{db: db, schemaService: database.NewSchemaService(db)}
+}
+
+// GetConfig 获取接口的删除同步配置，未开启时返回enabled=false
+func (s *DeletePropagationService) GetConfig(ctx context.Context, interfaceID string) (*models.DeletePropagationConfig, error) {
+	iface, err := getTenantInterface(ctx, s.db, interfaceID)
+	if err != nil {
+		return nil, err
+	}
+	if config := models.ParseDeletePropagationConfig(iface.InterfaceConfig); config != nil {
+		return config, nil
+	}
+	return &models.DeletePropagationConfig{FlagValues: []string{}}, nil
+}
+
+// UpdateConfig 开启或修改接口的删除同步；expectedVersion不为0时须与接口当前版本一致
+func (s *DeletePropagationService) UpdateConfig(ctx context.Context, interfaceID string, expectedVersion int64, config *models.DeletePropagationConfig, username string) (*models.DeletePropagationConfig, error) {
+	iface, err := getTenantInterface(ctx, s.db, interfaceID)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkInterfaceVersion(iface, expectedVersion); err != nil {
+		return nil, err
+	}
+	if !iface.IsTableCreated {
+		return nil, fmt.Errorf("%w: 接口表尚未创建", ErrInvalidDeletePropagationRequest)
+	}
+	if models.ParseSCDConfig(iface.InterfaceConfig) != nil {
+		return nil, fmt.Errorf("%w: 接口已开启历史追踪，不能同时开启删除同步", ErrInvalidDeletePropagationRequest)
+	}
+	config.Enabled = true
+	fields := sortedTableFields(iface.TableFieldsConfig)
+	if err := validateDeletePropagation(fields, config); err != nil {
+		return nil, err
+	}
+
+	schema, table := iface.BasicLibrary.GetSchemaName(), iface.NameEn
+	var newFields []models.TableField
+	switch config.Action {
+	case models.DeleteActionSoftDelete:
+		if newFields = applySoftDeleteField(fields); newFields != nil {
+			if err := s.schemaService.ManageTableSchema(interfaceID, "alter_table", schema, table, newFields); err != nil {
+				return nil, fmt.Errorf("修改表结构失败: %w", err)
+			}
+		}
+	case models.DeleteActionArchive:
+		if err := s.createArchiveTable(ctx, schema, table); err != nil {
+			return nil, err
+		}
+	}
+	if err := saveInterfaceConfig(ctx, s.db, iface, "interface_config", models.DeletePropagationConfigKey, config, newFields); err != nil {
+		return nil, fmt.Errorf("保存删除同步配置失败: %w", err)
+	}
+
+	slog.Info("设置接口删除同步", "interface_id", interfaceID, "detection", config.Detection, "action", config.Action, "username", username)
+	return config, nil
+}
+
+// DisableConfig 关闭接口的删除同步，软删除列和归档表保留；expectedVersion不为0时须与接口当前版本一致
+func (s *DeletePropagationService) DisableConfig(ctx context.Context, interfaceID string, expectedVersion int64, username string) error {
+	iface, err := getTenantInterface(ctx, s.db, interfaceID)
+	if err != nil {
+		return err
+	}
+	if err := checkInterfaceVersion(iface, expectedVersion); err != nil {
+		return err
+	}
+	if models.ParseDeletePropagationConfig(iface.InterfaceConfig) == nil {
+		return fmt.Errorf("%w: 接口未开启删除同步", ErrInvalidDeletePropagationRequest)
+	}
+	if err := saveInterfaceConfig(ctx, s.db, iface, "interface_config", models.DeletePropagationConfigKey, nil, nil); err != nil {
+		return fmt.Errorf("保存删除同步配置失败: %w", err)
+	}
+
+	slog.Info("关闭接口删除同步", "interface_id", interfaceID, "username", username)
+	return nil
+}
+
+// createArchiveTable 创建与接口表同结构的归档表（不含约束和默认值），并增加归档时间列
+func (s *DeletePropagationService) createArchiveTable(ctx context.Context, schema, table string) error {
+	fullTableName := utils.QuoteTable(schema, table)
+	archiveTable := utils.QuoteTable(schema, table+models.DeleteArchiveSuffix)
+	db := s.db.WithContext(ctx)
+	if err := db.Exec(fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (LIKE %s)", archiveTable, fullTableName)).Error; err != nil {
+		return fmt.Errorf("创建归档表失败: %w", err)
+	}
+	if err := db.Exec(fmt.Sprintf("ALTER TABLE %s ADD COLUMN IF NOT EXISTS %s timestamp", archiveTable, utils.QuoteIdent(models.DeleteArchivedAtColumn))).Error; err != nil {
+		return fmt.Errorf("创建归档表失败: %w", err)
+	}
+	return nil
+}
+
+// validateDeletePropagation 校验识别方式、删除标记字段和处理方式，接口表必须有主键
+func validateDeletePropagation(fields []models.TableField, config *models.DeletePropagationConfig) error {
+	switch config.Detection {
+	case models.DeleteDetectionFullCompare:
+		config.FlagField = ""
+		config.FlagValues = nil
+	case models.DeleteDetectionSourceFlag:
+		if config.FlagField == "" {
+			return fmt.Errorf("%w: 按删除标记识别时须指定源数据中的删除标记字段", ErrInvalidDeletePropagationRequest)
+		}
+	default:
+		return fmt.Errorf("%w: 不支持的识别方式%s", ErrInvalidDeletePropagationRequest, config.Detection)
+	}
+	if config.Action == "" {
+		config.Action = models.DeleteActionHardDelete
+	}
+	switch config.Action {
+	case models.DeleteActionHardDelete, models.DeleteActionSoftDelete, models.DeleteActionArchive:
+	default:
+		return fmt.Errorf("%w: 不支持的处理方式%s", ErrInvalidDeletePropagationRequest, config.Action)
+	}
+
+	for _, field := range fields {
+		if field.IsPrimaryKey {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: 接口表没有主键，无法按主键识别删除", ErrInvalidDeletePropagationRequest)
+}
+
+// applySoftDeleteField 字段配置中没有软删除列时追加，已有时返回nil
+func applySoftDeleteField(fields []models.TableField) []models.TableField {
+	maxOrder := 0
+	for _, field := range fields {
+		if field.NameEn == models.SoftDeleteColumn {
+			return nil
+		}
+		if field.OrderNum > maxOrder {
+			maxOrder = field.OrderNum
+		}
+	}
+	return append(append([]models.TableField{}, fields...), models.SoftDeleteField(maxOrder))
+}
@@ -0,0 +1,65 @@
+/*
+ * @module service/basic_library/delete_propagation_service_test
+ * @description 接口源端删除同步配置测试，覆盖识别方式和处理方式的校验、软删除列的追加以及接口配置的解析
+ * @architecture 测试层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow 构造字段配置和删除同步配置 -> 校验 -> 追加软删除列
+ * @rules 纯函数测试，不依赖数据库
+ * @dependencies stretchr/testify
+ * @refs delete_propagation_service.go
+ */
+
+package basic_library
+
+import (
+	"datahub-service/service/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateDeletePropagation(t *testing.T) {
+	fields := []models.TableField{
+		{NameEn: "id", DataType: "integer", IsPrimaryKey: true, OrderNum: 1},
+		{NameEn: "name", DataType: "varchar", OrderNum: 3},
+	}
+
+	config := &models.DeletePropagationConfig{Detection: models.DeleteDetectionFullCompare, FlagField: "is_deleted", FlagValues: []string{"1"}}
+	require.NoError(t, validateDeletePropagation(fields, config))
+	assert.Equal(t, models.DeleteActionHardDelete, config.Action, "默认硬删除")
+	assert.Empty(t, config.FlagField, "全量比对不使用删除标记")
+
+	for name, bad := range map[string]*models.DeletePropagationConfig{
+		"未知识别方式": {Detection: "cdc"},
+		"缺少标记字段": {Detection: models.DeleteDetectionSourceFlag},
+		"未知处理方式": {Detection: models.DeleteDetectionFullCompare, Action: "truncate"},
+	} {
+		assert.ErrorIs(t, validateDeletePropagation(fields, bad), ErrInvalidDeletePropagationRequest, name)
+	}
+	noKey := []models.TableField{{NameEn: "name", DataType: "varchar"}}
+	assert.ErrorIs(t, validateDeletePropagation(noKey, &models.DeletePropagationConfig{Detection: models.DeleteDetectionFullCompare}), ErrInvalidDeletePropagationRequest, "没有主键")
+
+	withColumn := applySoftDeleteField(fields)
+	require.Len(t, withColumn, 3)
+	assert.Equal(t, models.SoftDeleteColumn, withColumn[2].NameEn)
+	assert.Equal(t, 4, withColumn[2].OrderNum)
+	assert.True(t, withColumn[2].IsNullable)
+	assert.Len(t, fields, 2, "不修改原字段配置")
+	assert.Nil(t, applySoftDeleteField(withColumn), "已有软删除列")
+}
+
+func TestParseDeletePropagationConfig(t *testing.T) {
+	assert.Nil(t, models.ParseDeletePropagationConfig(map[string]interface{}{}))
+	assert.Nil(t, models.ParseDeletePropagationConfig(map[string]interface{}{"delete_config": map[string]interface{}{"enabled": false, "detection": "full_compare"}}))
+	assert.Nil(t, models.ParseDeletePropagationConfig(map[string]interface{}{"delete_config": map[string]interface{}{"enabled": true, "detection": "source_flag"}}), "缺少标记字段")
+
+	config := models.ParseDeletePropagationConfig(map[string]interface{}{"delete_config": map[string]interface{}{
+		"enabled": true, "detection": "source_flag", "flag_field": "status", "flag_values": []interface{}{"REMOVED"},
+	}})
+	require.NotNil(t, config)
+	assert.Equal(t, models.DeleteActionHardDelete, config.Action)
+	assert.True(t, config.IsDeletedFlag("removed"))
+	assert.False(t, config.IsDeletedFlag("true"), "配置了取值时不使用默认取值")
+	assert.False(t, config.IsDeletedFlag(nil))
+}
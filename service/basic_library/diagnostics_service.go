@@ -0,0 +1,385 @@
+/*
+ * @module service/basic_library/diagnostics_service
+ * @description 同步执行诊断服务，把任务配置、运行时接口配置快照、数据源健康状态、批次耗时、错误调用栈和相关事件汇总为一份诊断数据
+ * @architecture 分层架构 - 业务服务层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow 执行开始时采集快照写入执行结果 -> 查询执行记录 -> 解析快照和interface_results -> 查询执行期间的应用事件 -> 汇总返回
+ * @rules 配置中的密码、密钥、令牌等敏感字段一律脱敏；写入失败时附带接口JSON Schema的违规清单；没有运行时快照的旧执行记录使用当前配置并标注来源；事件最多返回maxDiagnosticsEvents条
+ * @dependencies datahub-service/service/models, gorm.io/gorm
+ * @refs service/basic_library/sync_task_service.go, service/eventlog/eventlog_service.go, api/controllers/execution_diagnostics_controller.go
+ */
+
+package basic_library
+
+import (
+	"context"
+	"datahub-service/service/interface_executor"
+	"datahub-service/service/models"
+	"datahub-service/service/utils"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// 诊断快照来源
+const (
+	SnapshotSourceExecution = "execution" // 执行开始时采集的快照
+	SnapshotSourceCurrent   = "current"   // 执行记录没有快照，使用当前配置
+)
+
+const (
+	maxDiagnosticsEvents = 200      // 诊断数据中事件最大条数
+	redactedValue        = "******" // 敏感字段脱敏后的值
+)
+
+// sensitiveConfigKeys 配置项名称包含这些关键字时视为敏感字段
+var sensitiveConfigKeys = []string{"password", "passwd", "secret", "token", "api_key", "apikey", "access_key", "private_key", "credential", "authorization"}
+
+// InterfaceSnapshot 接口配置快照
+type InterfaceSnapshot struct {
+	InterfaceID       string                 `json:"interface_id"`
+	NameZh            string                 `json:"name_zh"`
+	NameEn            string                 `json:"name_en"`
+	Type              string                 `json:"type"`
+	Status            string                 `json:"status"`
+	DataSourceID      string                 `json:"data_source_id"`
+	InterfaceConfig   map[string]interface{} `json:"interface_config,omitempty"`
+	ParseConfig       map[string]interface{} `json:"parse_config,omitempty"`
+	TableFieldsConfig map[string]interface{} `json:"table_fields_config,omitempty"`
+	TaskConfig        map[string]interface{} `json:"task_config,omitempty"` // 同步任务中该接口的配置
+	RowVersion        int64                  `json:"row_version"`
+	UpdatedAt         time.Time              `json:"updated_at"`
+}
+
+// DataSourceHealthSnapshot 数据源健康快照
+type DataSourceHealthSnapshot struct {
+	DataSourceID     string                 `json:"data_source_id"`
+	Name             string                 `json:"name"`
+	Category         string                 `json:"category"`
+	Type             string                 `json:"type"`
+	Status           string                 `json:"status"`        // 数据源启用状态 active/inactive
+	HealthStatus     string                 `json:"health_status"` // 连接状态 online/offline/error/testing/unknown
+	HealthScore      int                    `json:"health_score"`
+	LastTestTime     *time.Time             `json:"last_test_time,omitempty"`
+	LastSyncTime     *time.Time             `json:"last_sync_time,omitempty"`
+	LastErrorTime    *time.Time             `json:"last_error_time,omitempty"`
+	ErrorMessage     string                 `json:"error_message,omitempty"`
+	ConnectionConfig map[string]interface{} `json:"connection_config,omitempty"`
+}
+
+// ExecutionSnapshot 执行时的接口配置和数据源健康快照
+type ExecutionSnapshot struct {
+	CapturedAt  time.Time                  `json:"captured_at"`
+	Interfaces  []InterfaceSnapshot        `json:"interfaces"`
+	DataSources []DataSourceHealthSnapshot `json:"data_sources"`
+}
+
+// BatchTiming 单个接口调用（批次）的耗时和结果
+type BatchTiming struct {
+	InterfaceID   string     `json:"interface_id"`
+	StartedAt     *time.Time `json:"started_at,omitempty"`
+	DurationMs    int64      `json:"duration_ms"`
+	UpdatedRows   int64      `json:"updated_rows"`
+	Success       bool       `json:"success"`
+	Error         string     `json:"error,omitempty"`
+	ErrorCategory string     `json:"error_category,omitempty"`
+}
+
+// ExecutionError 执行错误，接口ID为空表示任务级错误
+type ExecutionError struct {
+	InterfaceID string `json:"interface_id,omitempty"`
+	Message     string `json:"message"`
+	Category    string `json:"category"`
+	FailureCode string `json:"failure_code"` // 根因失败码，处理建议见失败分析接口
+	Stack       string `json:"stack,omitempty"`
+	// SchemaViolations 写入失败时按接口JSON Schema校验出的违规清单
+	SchemaViolations *utils.PayloadValidationResult `json:"schema_violations,omitempty"`
+}
+
+// ExecutionDiagnostics 同步执行诊断数据
+type ExecutionDiagnostics struct {
+	Execution      models.SyncTaskExecution  `json:"execution"`
+	Task           models.SyncTask           `json:"task"`
+	Snapshot       *ExecutionSnapshot        `json:"snapshot"`
+	SnapshotSource string                    `json:"snapshot_source" example:"execution"` // execution/current
+	Batches        []BatchTiming             `json:"batches"`
+	Errors         []ExecutionError          `json:"errors"`
+	Events         []models.ApplicationEvent `json:"events"`
+	GeneratedAt    time.Time                 `json:"generated_at"`
+}
+
+// ExecutionDiagnosticsService 同步执行诊断服务
+type ExecutionDiagnosticsService struct {
+	db *gorm.DB
+}
+
+// NewExecutionDiagnosticsService 创建同步执行诊断服务
+func NewExecutionDiagnosticsService(db *gorm.DB) *ExecutionDiagnosticsService {
+	return &ExecutionDiagnosticsService{db: db}
+}
+
+// GetExecutionDiagnostics 获取同步执行的诊断数据
+func (s *ExecutionDiagnosticsService) GetExecutionDiagnostics(ctx context.Context, executionID string) (*ExecutionDiagnostics, error) {
+	var execution models.SyncTaskExecution
+	if err := s.db.WithContext(ctx).First(&execution, "id = ?", executionID).Error; err != nil {
+		return nil, err
+	}
+	var task models.SyncTask
+	if err := s.db.WithContext(ctx).Preload("TaskInterfaces").First(&task, "id = ?", execution.TaskID).Error; err != nil {
+		return nil, fmt.Errorf("获取同步任务失败: %w", err)
+	}
+
+	diagnostics := &ExecutionDiagnostics{
+		Task:        task,
+		Batches:     []BatchTiming{},
+		Errors:      []ExecutionError{},
+		GeneratedAt: time.Now(),
+	}
+
+	// 快照和接口调用结果单独展开，执行记录中只保留汇总信息
+	var interfaceResults []map[string]interface{}
+	summary := models.JSONB{}
+	for key, value := range execution.Result {
+		switch key {
+		case "snapshot":
+			var snapshot ExecutionSnapshot
+			if decodeJSON(value, &snapshot) == nil && !snapshot.CapturedAt.IsZero() {
+				diagnostics.Snapshot = &snapshot
+			}
+		case "interface_results":
+			_ = decodeJSON(value, &interfaceResults)
+		default:
+			summary[key] = value
+		}
+	}
+	execution.Result = summary
+	diagnostics.Execution = execution
+
+	diagnostics.SnapshotSource = SnapshotSourceExecution
+	if diagnostics.Snapshot == nil {
+		diagnostics.Snapshot = captureExecutionSnapshot(s.db.WithContext(ctx), &task)
+		diagnostics.SnapshotSource = SnapshotSourceCurrent
+	}
+
+	for _, result := range interfaceResults {
+		batch := BatchTiming{
+			InterfaceID:   toString(result["interface_id"]),
+			DurationMs:    toInt64(result["duration_ms"]),
+			UpdatedRows:   toInt64(result["updated_rows"]),
+			Success:       result["success"] == true,
+			Error:         toString(result["error"]),
+			ErrorCategory: toString(result["error_category"]),
+		}
+		if startedAt, err := time.Parse(time.RFC3339Nano, toString(result["started_at"])); err == nil {
+			batch.StartedAt = &startedAt
+		}
+		diagnostics.Batches = append(diagnostics.Batches, batch)
+
+		if !batch.Success && batch.Error != "" {
+			executionError := ExecutionError{
+				InterfaceID: batch.InterfaceID,
+				Message:     batch.Error,
+				Category:    ClassifySyncError(batch.Error),
+				FailureCode: failureCodeOf(result, batch.Error),
+				Stack:       toString(result["stack"]),
+			}
+			if violations, ok := result["schema_violations"]; ok {
+				var validation utils.PayloadValidationResult
+				if decodeJSON(violations, &validation) == nil {
+					executionError.SchemaViolations = &validation
+				}
+			}
+			diagnostics.Errors = append(diagnostics.Errors, executionError)
+		}
+	}
+	if execution.ErrorMessage != "" {
+		diagnostics.Errors = append(diagnostics.Errors, ExecutionError{
+			Message:     execution.ErrorMessage,
+			Category:    ClassifySyncError(execution.ErrorMessage),
+			FailureCode: interface_executor.ClassifyFailureMessage(execution.ErrorMessage).Code,
+		})
+	}
+
+	events, err := s.executionEvents(ctx, &execution)
+	if err != nil {
+		return nil, fmt.Errorf("获取执行事件失败: %w", err)
+	}
+	diagnostics.Events = events
+
+	return diagnostics, nil
+}
+
+// executionEvents 查询执行期间该任务的应用事件，排除明确属于其他执行的事件
+func (s *ExecutionDiagnosticsService) executionEvents(ctx context.Context, execution *models.SyncTaskExecution) ([]models.ApplicationEvent, error) {
+	query := s.db.WithContext(ctx).
+		Where("object_id = ? AND created_at >= ?", execution.TaskID, execution.StartTime.Add(-time.Second))
+	if execution.EndTime != nil {
+		query = query.Where("created_at <= ?", execution.EndTime.Add(time.Second))
+	}
+
+	var events []models.ApplicationEvent
+	if err := query.Order("created_at ASC").Limit(maxDiagnosticsEvents).Find(&events).Error; err != nil {
+		return nil, err
+	}
+
+	filtered := make([]models.ApplicationEvent, 0, len(events))
+	for _, event := range events {
+		if executionID, ok := event.Attributes["execution_id"].(string); ok && executionID != execution.ID {
+			continue
+		}
+		filtered = append(filtered, event)
+	}
+	return filtered, nil
+}
+
+// captureExecutionSnapshot 采集任务关联接口的配置和数据源健康状态，失败时只记录已采集的部分
+func captureExecutionSnapshot(db *gorm.DB, task *models.SyncTask) *ExecutionSnapshot {
+	snapshot := &ExecutionSnapshot{
+		CapturedAt:  time.Now(),
+		Interfaces:  []InterfaceSnapshot{},
+		DataSources: []DataSourceHealthSnapshot{},
+	}
+
+	interfaceIDs := make([]string, 0, len(task.TaskInterfaces))
+	taskConfigs := make(map[string]models.JSONB, len(task.TaskInterfaces))
+	for _, taskInterface := range task.TaskInterfaces {
+		interfaceIDs = append(interfaceIDs, taskInterface.InterfaceID)
+		taskConfigs[taskInterface.InterfaceID] = taskInterface.Config
+	}
+
+	var interfaces []models.DataInterface
+	if len(interfaceIDs) > 0 {
+		db.Where("id IN ?", interfaceIDs).Find(&interfaces)
+	}
+
+	dataSourceIDs := []string{}
+	seen := map[string]bool{}
+	addDataSource := func(id string) {
+		if id != "" && !seen[id] {
+			seen[id] = true
+			dataSourceIDs = append(dataSourceIDs, id)
+		}
+	}
+	addDataSource(task.DataSourceID)
+	for _, iface := range interfaces {
+		snapshot.Interfaces = append(snapshot.Interfaces, InterfaceSnapshot{
+			InterfaceID:       iface.ID,
+			NameZh:            iface.NameZh,
+			NameEn:            iface.NameEn,
+			Type:              iface.Type,
+			Status:            iface.Status,
+			DataSourceID:      iface.DataSourceID,
+			InterfaceConfig:   redactConfig(iface.InterfaceConfig),
+			ParseConfig:       redactConfig(iface.ParseConfig),
+			TableFieldsConfig: redactConfig(iface.TableFieldsConfig),
+			TaskConfig:        redactConfig(taskConfigs[iface.ID]),
+			RowVersion:        iface.RowVersion,
+			UpdatedAt:         iface.UpdatedAt,
+		})
+		addDataSource(iface.DataSourceID)
+	}
+
+	if len(dataSourceIDs) == 0 {
+		return snapshot
+	}
+	var dataSources []models.DataSource
+	db.Where("id IN ?", dataSourceIDs).Find(&dataSources)
+	var statuses []models.DataSourceStatus
+	// 只查询健康相关字段，统计类map字段不参与诊断
+	db.Select("data_source_id", "status", "health_score", "last_test_time", "last_sync_time", "last_error_time", "error_message").
+		Where("data_source_id IN ?", dataSourceIDs).Find(&statuses)
+	statusByID := make(map[string]models.DataSourceStatus, len(statuses))
+	for _, status := range statuses {
+		statusByID[status.DataSourceID] = status
+	}
+
+	for _, dataSource := range dataSources {
+		health := DataSourceHealthSnapshot{
+			DataSourceID:     dataSource.ID,
+			Name:             dataSource.Name,
+			Category:         dataSource.Category,
+			Type:             dataSource.Type,
+			Status:           dataSource.Status,
+			HealthStatus:     "unknown",
+			ConnectionConfig: redactConfig(dataSource.ConnectionConfig),
+		}
+		if status, ok := statusByID[dataSource.ID]; ok {
+			health.HealthStatus = status.Status
+			health.HealthScore = status.HealthScore
+			health.LastTestTime = status.LastTestTime
+			health.LastSyncTime = status.LastSyncTime
+			health.LastErrorTime = status.LastErrorTime
+			health.ErrorMessage = status.ErrorMessage
+		}
+		snapshot.DataSources = append(snapshot.DataSources, health)
+	}
+	return snapshot
+}
+
+// redactConfig 复制配置并脱敏敏感字段
+func redactConfig(config map[string]interface{}) map[string]interface{} {
+	if config == nil {
+		return nil
+	}
+	redacted := make(map[string]interface{}, len(config))
+	for key, value := range config {
+		if isSensitiveConfigKey(key) {
+			if value != nil && value != "" {
+				value = redactedValue
+			}
+		} else {
+			value = redactValue(value)
+		}
+		redacted[key] = value
+	}
+	return redacted
+}
+
+// redactValue 递归脱敏嵌套配置
+func redactValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		return redactConfig(v)
+	case models.JSONB:
+		return redactConfig(v)
+	case []interface{}:
+		items := make([]interface{}, len(v))
+		for i, item := range v {
+			items[i] = redactValue(item)
+		}
+		return items
+	default:
+		return value
+	}
+}
+
+// isSensitiveConfigKey 判断配置项是否为敏感字段
+func isSensitiveConfigKey(key string) bool {
+	lower := strings.ToLower(key)
+	for _, sensitive := range sensitiveConfigKeys {
+		if strings.Contains(lower, sensitive) {
+			return true
+		}
+	}
+	return false
+}
+
+// decodeJSON 把JSONB中的任意值解码到目标结构
+func decodeJSON(value interface{}, target interface{}) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, target)
+}
+
+// toString 把JSONB中的值转为字符串
+func toString(value interface{}) string {
+	if s, ok := value.(string); ok {
+		return s
+	}
+	return ""
+}
@@ -0,0 +1,138 @@
+/*
+ * @module service/basic_library/diagnostics_service_test
+ * @description 同步执行诊断测试，覆盖快照解析、敏感配置脱敏、批次耗时、错误调用栈、事件筛选和无快照时使用当前配置
+ * @architecture 测试层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow 准备sqlite中的任务、接口、数据源和执行记录 -> 获取诊断数据 -> 验证各部分内容
+ * @rules 使用内存sqlite，不依赖外部服务
+ * @dependencies gorm.io/driver/sqlite, stretchr/testify
+ * @refs diagnostics_service.go
+ */
+
+package basic_library
+
+import (
+	"context"
+	"datahub-service/service/models"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func setupDiagnosticsDB(t *testing.T) (*gorm.DB, *models.SyncTask) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(
+		&models.DataSource{}, &models.DataSourceStatus{}, &models.DataInterface{},
+		&models.SyncTask{}, &models.SyncTaskInterface{}, &models.SyncTaskExecution{}, &models.ApplicationEvent{},
+	))
+
+	now := time.Now()
+	require.NoError(t, db.Create(&models.DataSource{
+		ID: "ds-1", LibraryID: "lib-1", Name: "停车系统", Category: "http", Type: "http_with_auth",
+		ConnectionConfig: models.JSONB{"base_url": "http://parking", "password": "p@ss", "auth": map[string]interface{}{"access_token": "abc"}},
+	}).Error)
+	// 统计类map字段在sqlite下无法写入，测试中省略
+	require.NoError(t, db.Omit("ConnectionInfo", "PerformanceInfo", "SyncStatistics").Create(&models.DataSourceStatus{
+		ID: "dss-1", DataSourceID: "ds-1", Status: "error", HealthScore: 40, LastErrorTime: &now, ErrorMessage: "connection refused",
+	}).Error)
+	require.NoError(t, db.Create(&models.DataInterface{
+		ID: "if-1", LibraryID: "lib-1", NameZh: "车辆进出", NameEn: "vehicle_pass", Type: "batch", DataSourceID: "ds-1",
+		InterfaceConfig: models.JSONB{"path": "/records", "headers": map[string]interface{}{"Authorization": "Bearer x"}},
+	}).Error)
+
+	task := &models.SyncTask{
+		ID: "task-1", LibraryType: "basic_library", LibraryID: "lib-1", DataSourceID: "ds-1", TaskType: "batch_sync",
+		TaskInterfaces: []models.SyncTaskInterface{{ID: "ti-1", InterfaceID: "if-1", Config: models.JSONB{"secret_key": "k"}}},
+	}
+	require.NoError(t, db.Create(task).Error)
+	return db, task
+}
+
+func TestGetExecutionDiagnostics(t *testing.T) {
+	db, task := setupDiagnosticsDB(t)
+	snapshot := captureExecutionSnapshot(db, task)
+
+	start := time.Now().Add(-time.Minute)
+	end := time.Now()
+	require.NoError(t, db.Create(&models.SyncTaskExecution{
+		ID: "exec-1", TaskID: task.ID, ExecutionType: "manual", Status: "failed", StartTime: start, EndTime: &end,
+		ErrorMessage: "所有接口执行失败",
+		Result: models.JSONB{
+			"processed_rows": 0,
+			"snapshot":       snapshot,
+			"interface_results": []map[string]interface{}{
+				{"interface_id": "if-1", "success": false, "duration_ms": 1500, "updated_rows": 0, "started_at": start.Add(time.Second),
+					"error": "接口执行panic: nil map", "error_category": ErrorCategoryOther, "stack": "goroutine 1 [running]"},
+			},
+		},
+	}).Error)
+
+	// 执行期间的本执行事件、其他执行的事件和执行之外的事件
+	require.NoError(t, db.Create(&models.ApplicationEvent{EventType: "task_started", ObjectID: task.ID, RequestID: "req-1",
+		Attributes: models.JSONB{"execution_id": "exec-1"}, CreatedAt: start.Add(time.Second)}).Error)
+	require.NoError(t, db.Create(&models.ApplicationEvent{EventType: "task_started", ObjectID: task.ID,
+		Attributes: models.JSONB{"execution_id": "exec-2"}, CreatedAt: start.Add(2 * time.Second)}).Error)
+	require.NoError(t, db.Create(&models.ApplicationEvent{EventType: "task_started", ObjectID: task.ID,
+		Attributes: models.JSONB{"execution_id": "exec-0"}, CreatedAt: start.Add(-time.Hour)}).Error)
+
+	diagnostics, err := NewExecutionDiagnosticsService(db).GetExecutionDiagnostics(context.Background(), "exec-1")
+	require.NoError(t, err)
+
+	assert.Equal(t, SnapshotSourceExecution, diagnostics.SnapshotSource)
+	assert.Equal(t, task.ID, diagnostics.Task.ID)
+	assert.NotContains(t, diagnostics.Execution.Result, "snapshot")
+	assert.NotContains(t, diagnostics.Execution.Result, "interface_results")
+	assert.Contains(t, diagnostics.Execution.Result, "processed_rows")
+
+	require.Len(t, diagnostics.Snapshot.Interfaces, 1)
+	iface := diagnostics.Snapshot.Interfaces[0]
+	assert.Equal(t, redactedValue, iface.InterfaceConfig["headers"].(map[string]interface{})["Authorization"])
+	assert.Equal(t, redactedValue, iface.TaskConfig["secret_key"])
+	assert.Equal(t, "/records", iface.InterfaceConfig["path"])
+
+	require.Len(t, diagnostics.Snapshot.DataSources, 1)
+	health := diagnostics.Snapshot.DataSources[0]
+	assert.Equal(t, "error", health.HealthStatus)
+	assert.Equal(t, 40, health.HealthScore)
+	assert.Equal(t, redactedValue, health.ConnectionConfig["password"])
+	assert.Equal(t, redactedValue, health.ConnectionConfig["auth"].(map[string]interface{})["access_token"])
+	assert.Equal(t, "http://parking", health.ConnectionConfig["base_url"])
+
+	require.Len(t, diagnostics.Batches, 1)
+	assert.Equal(t, int64(1500), diagnostics.Batches[0].DurationMs)
+	require.NotNil(t, diagnostics.Batches[0].StartedAt)
+
+	require.Len(t, diagnostics.Errors, 2)
+	assert.Equal(t, "if-1", diagnostics.Errors[0].InterfaceID)
+	assert.Equal(t, "goroutine 1 [running]", diagnostics.Errors[0].Stack)
+	assert.Equal(t, "所有接口执行失败", diagnostics.Errors[1].Message)
+
+	require.Len(t, diagnostics.Events, 1)
+	assert.Equal(t, "req-1", diagnostics.Events[0].RequestID)
+}
+
+func TestGetExecutionDiagnosticsWithoutSnapshot(t *testing.T) {
+	db, task := setupDiagnosticsDB(t)
+	require.NoError(t, db.Create(&models.SyncTaskExecution{
+		ID: "exec-old", TaskID: task.ID, ExecutionType: "manual", Status: "running", StartTime: time.Now(),
+	}).Error)
+
+	service := NewExecutionDiagnosticsService(db)
+	diagnostics, err := service.GetExecutionDiagnostics(context.Background(), "exec-old")
+	require.NoError(t, err)
+	assert.Equal(t, SnapshotSourceCurrent, diagnostics.SnapshotSource)
+	assert.Len(t, diagnostics.Snapshot.Interfaces, 1)
+	assert.Empty(t, diagnostics.Batches)
+	assert.Empty(t, diagnostics.Errors)
+
+	_, err = service.GetExecutionDiagnostics(context.Background(), "missing")
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+}
@@ -0,0 +1,459 @@
+/*
+ * @module service/basic_library/duplicate_report_service
+ * @description 接口表重复数据检测服务，按需对接口表采样，按键字段精确或模糊匹配找出重复簇，给出行数、样例行和建议的去重配置
+ * @architecture 分层架构 - 业务服务层，分组逻辑为纯函数
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow 发起分析 -> 校验键字段并保存running报告 -> 后台采样接口表 -> 按键字段归一化分组 -> 保存重复簇和去重建议(completed) / 错误信息(failed)
+ * @rules 未指定键字段时使用除主键和派生列外的全部字段(即整行重复)；键字段全部为空的行不参与比较；统计结果只针对采样数据；
+ *        fuzzy模式未指定任何归一化选项时启用全部选项；重复簇按行数从多到少最多保存maxDuplicateClusters个
+ * @dependencies datahub-service/service/models, datahub-service/service/tenant, gorm.io/gorm
+ * @refs service/models/duplicate_report.go, api/controllers/duplicate_report_controller.go
+ */
+
+package basic_library
+
+import (
+	"context"
+	"datahub-service/service/models"
+	"datahub-service/service/utils"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"gorm.io/gorm"
+)
+
+const (
+	defaultDuplicateSampleSize  = 10000  // 默认采样行数
+	maxDuplicateSampleSize      = 100000 // 最大采样行数
+	maxDuplicateClusters        = 50     // 报告保存的重复簇上限
+	duplicateClusterSampleRows  = 3      // 每个重复簇保存的样例行数
+	duplicateClusterMaxVariants = 5      // 每个重复簇保存的原始键取值数
+	duplicateReportListLimit    = 20     // 报告列表返回条数
+)
+
+// ErrInvalidDuplicateRequest 重复检测请求不合法
+var ErrInvalidDuplicateRequest = errors.New("重复数据检测请求不合法")
+
+// DuplicateReportRequest 发起重复数据检测的参数
+type DuplicateReportRequest struct {
+	KeyFields    []string                     `json:"key_fields"`
+	MatchOptions models.DuplicateMatchOptions `json:"match_options"`
+	SampleSize   int                          `json:"sample_size"`
+}
+
+// DuplicateAnalysis 一次采样的重复检测结果
+type DuplicateAnalysis struct {
+	RowsSampled   int
+	RowsSkipped   int
+	ClusterCount  int
+	DuplicateRows int
+	DuplicateRate float64
+	Clusters      models.DuplicateClusters
+	Truncated     bool
+}
+
+// DuplicateReportService 接口表重复数据检测服务
+type DuplicateReportService struct {
+	db     *gorm.DB
+	readDB *gorm.DB
+}
+
+// NewDuplicateReportService 创建重复数据检测服务实例
+func NewDuplicateReportService(db *gorm.DB) *DuplicateReportService {
+	return &DuplicateReportService{db: db, readDB: db}
+}
+
+// SetReadDB 设置只读副本连接，采样查询走只读副本
+func (s *DuplicateReportService) SetReadDB(readDB *gorm.DB) {
+	if readDB != nil {
+		s.readDB = readDB
+	}
+}
+
+// StartReport 校验参数并创建running状态的报告，采样分析在后台执行
+func (s *DuplicateReportService) StartReport(ctx context.Context, interfaceID string, req *DuplicateReportRequest, username string) (*models.DuplicateReport, error) {
+	iface, err := getTenantInterface(ctx, s.db, interfaceID)
+	if err != nil {
+		return nil, err
+	}
+	if !iface.IsTableCreated {
+		return nil, fmt.Errorf("%w: 接口表尚未创建", ErrInvalidDuplicateRequest)
+	}
+	keyFields, err := resolveDuplicateKeyFields(iface.TableFieldsConfig, req.KeyFields)
+	if err != nil {
+		return nil, err
+	}
+	options, err := normalizeDuplicateMatchOptions(req.MatchOptions)
+	if err != nil {
+		return nil, err
+	}
+	sampleSize := req.SampleSize
+	if sampleSize <= 0 {
+		sampleSize = defaultDuplicateSampleSize
+	}
+	if sampleSize > maxDuplicateSampleSize {
+		sampleSize = maxDuplicateSampleSize
+	}
+
+	report := &models.DuplicateReport{
+		InterfaceID:  interfaceID,
+		Status:       models.DuplicateReportStatusRunning,
+		KeyFields:    keyFields,
+		MatchOptions: options,
+		SampleSize:   sampleSize,
+		Clusters:     models.DuplicateClusters{},
+		CreatedBy:    username,
+		CreatedAt:    time.Now(),
+	}
+	if err := s.db.WithContext(ctx).Create(report).Error; err != nil {
+		return nil, fmt.Errorf("创建重复数据检测报告失败: %w", err)
+	}
+
+	go s.runReport(context.WithoutCancel(ctx), iface, report)
+	return report, nil
+}
+
+// GetReport 获取接口的重复数据检测报告
+func (s *DuplicateReportService) GetReport(ctx context.Context, interfaceID, reportID string) (*models.DuplicateReport, error) {
+	if _, err := getTenantInterface(ctx, s.db, interfaceID); err != nil {
+		return nil, err
+	}
+	var report models.DuplicateReport
+	if err := s.db.WithContext(ctx).First(&report, "id = ? AND interface_id = ?", reportID, interfaceID).Error; err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
+
+// ListReports 获取接口最近的重复数据检测报告，不含重复簇明细
+func (s *DuplicateReportService) ListReports(ctx context.Context, interfaceID string) ([]models.DuplicateReport, error) {
+	if _, err := getTenantInterface(ctx, s.db, interfaceID); err != nil {
+		return nil, err
+	}
+	reports := make([]models.DuplicateReport, 0)
+	err := s.db.WithContext(ctx).Omit("clusters").
+		Where("interface_id = ?", interfaceID).
+		Order("created_at DESC").
+		Limit(duplicateReportListLimit).
+		Find(&reports).Error
+	if err != nil {
+		return nil, fmt.Errorf("查询重复数据检测报告失败: %w", err)
+	}
+	return reports, nil
+}
+
+// runReport 采样接口表并保存检测结果
+func (s *DuplicateReportService) runReport(ctx context.Context, iface *models.DataInterface, report *models.DuplicateReport) {
+	updates := map[string]interface{}{}
+	analysis, err := s.analyze(ctx, iface, report)
+	now := time.Now()
+	if err != nil {
+		slog.WarnContext(ctx, "重复数据检测失败", "interface_id", iface.ID, "report_id", report.ID, "error", err)
+		updates["status"] = models.DuplicateReportStatusFailed
+		updates["error_message"] = err.Error()
+	} else {
+		updates["status"] = models.DuplicateReportStatusCompleted
+		updates["rows_sampled"] = analysis.RowsSampled
+		updates["rows_skipped"] = analysis.RowsSkipped
+		updates["cluster_count"] = analysis.ClusterCount
+		updates["duplicate_rows"] = analysis.DuplicateRows
+		updates["duplicate_rate"] = analysis.DuplicateRate
+		updates["clusters"] = analysis.Clusters
+		updates["truncated"] = analysis.Truncated
+		if suggestion := SuggestDedupConfig(iface.TableFieldsConfig, report.KeyFields, report.MatchOptions, analysis); suggestion != nil {
+			updates["suggested_config"] = suggestion
+		}
+		slog.InfoContext(ctx, "重复数据检测完成", "interface_id", iface.ID, "report_id", report.ID,
+			"rows_sampled", analysis.RowsSampled, "clusters", analysis.ClusterCount, "duplicate_rows", analysis.DuplicateRows)
+	}
+	updates["completed_at"] = now
+
+	if err := s.db.WithContext(ctx).Model(&models.DuplicateReport{}).Where("id = ?", report.ID).Updates(updates).Error; err != nil {
+		slog.ErrorContext(ctx, "保存重复数据检测报告失败", "report_id", report.ID, "error", err)
+	}
+}
+
+// analyze 采样接口表并按键字段分组
+func (s *DuplicateReportService) analyze(ctx context.Context, iface *models.DataInterface, report *models.DuplicateReport) (*DuplicateAnalysis, error) {
+	schema := iface.BasicLibrary.GetSchemaName()
+	if schema == "" || iface.NameEn == "" {
+		return nil, errors.New("接口所属基础库或接口英文名为空")
+	}
+
+	var rows []map[string]interface{}
+	sampleSQL := fmt.Sprintf("SELECT * FROM %s.%s LIMIT %d", utils.QuoteIdent(schema), utils.QuoteIdent(iface.NameEn), report.SampleSize)
+	if err := s.readDB.WithContext(ctx).Raw(sampleSQL).Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("采样数据失败: %w", err)
+	}
+	return DetectDuplicates(rows, report.KeyFields, report.MatchOptions), nil
+}
+
+// sortedTableFields 按order_num排序的表字段配置
+func sortedTableFields(tableFieldsConfig models.JSONB) []models.TableField {
+	fields := make([]models.TableField, 0, len(tableFieldsConfig))
+	for _, item := range tableFieldsConfig {
+		fieldMap, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		var field models.TableField
+		data, _ := json.Marshal(fieldMap)
+		if err := json.Unmarshal(data, &field); err != nil || field.NameEn == "" {
+			continue
+		}
+		fields = append(fields, field)
+	}
+	sort.SliceStable(fields, func(i, j int) bool {
+		if fields[i].OrderNum != fields[j].OrderNum {
+			return fields[i].OrderNum < fields[j].OrderNum
+		}
+		return fields[i].NameEn < fields[j].NameEn
+	})
+	return fields
+}
+
+// resolveDuplicateKeyFields 校验指定的键字段，未指定时取除主键和派生列外的全部字段
+func resolveDuplicateKeyFields(tableFieldsConfig models.JSONB, requested []string) ([]string, error) {
+	fields := sortedTableFields(tableFieldsConfig)
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("%w: 接口未配置表字段", ErrInvalidDuplicateRequest)
+	}
+
+	if len(requested) == 0 {
+		keyFields := make([]string, 0, len(fields))
+		for _, field := range fields {
+			if !field.IsPrimaryKey && !field.IsComputed() {
+				keyFields = append(keyFields, field.NameEn)
+			}
+		}
+		if len(keyFields) == 0 {
+			return nil, fmt.Errorf("%w: 除主键和派生列外没有可比较的字段，请指定键字段", ErrInvalidDuplicateRequest)
+		}
+		return keyFields, nil
+	}
+
+	known := make(map[string]bool, len(fields))
+	for _, field := range fields {
+		known[field.NameEn] = true
+	}
+	keyFields := make([]string, 0, len(requested))
+	seen := make(map[string]bool, len(requested))
+	for _, name := range requested {
+		name = strings.TrimSpace(name)
+		if !known[name] {
+			return nil, fmt.Errorf("%w: 键字段 %s 不在接口表字段中", ErrInvalidDuplicateRequest, name)
+		}
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+		keyFields = append(keyFields, name)
+	}
+	return keyFields, nil
+}
+
+// normalizeDuplicateMatchOptions 校验匹配方式，fuzzy模式未指定归一化选项时启用全部选项
+func normalizeDuplicateMatchOptions(options models.DuplicateMatchOptions) (models.DuplicateMatchOptions, error) {
+	switch options.Mode {
+	case "", models.DuplicateMatchExact:
+		return models.DuplicateMatchOptions{Mode: models.DuplicateMatchExact}, nil
+	case models.DuplicateMatchFuzzy:
+		if !options.IgnoreCase && !options.IgnoreWhitespace && !options.IgnorePunctuation && !options.NormalizeWidth && !options.NormalizeNumber {
+			options = models.DuplicateMatchOptions{
+				Mode:              models.DuplicateMatchFuzzy,
+				IgnoreCase:        true,
+				IgnoreWhitespace:  true,
+				IgnorePunctuation: true,
+				NormalizeWidth:    true,
+				NormalizeNumber:   true,
+			}
+		}
+		return options, nil
+	default:
+		return options, fmt.Errorf("%w: 不支持的匹配方式 %s", ErrInvalidDuplicateRequest, options.Mode)
+	}
+}
+
+// DetectDuplicates 按键字段对采样行分组，返回包含多行的重复簇，按行数从多到少排列
+func DetectDuplicates(rows []map[string]interface{}, keyFields []string, options models.DuplicateMatchOptions) *DuplicateAnalysis {
+	type cluster struct {
+		key      map[string]interface{}
+		rows     []map[string]interface{}
+		count    int
+		variants []string
+		seen     map[string]bool
+		first    int
+	}
+
+	fuzzy := options.Mode == models.DuplicateMatchFuzzy
+	analysis := &DuplicateAnalysis{RowsSampled: len(rows), Clusters: models.DuplicateClusters{}}
+	groups := make(map[string]*cluster)
+	for index, row := range rows {
+		parts := make([]string, len(keyFields))
+		raw := make([]string, len(keyFields))
+		empty := true
+		for i, field := range keyFields {
+			value, isNull := duplicateValueString(row[field])
+			raw[i] = value
+			if fuzzy {
+				value = normalizeDuplicateValue(value, options)
+			}
+			parts[i] = value
+			if !isNull && value != "" {
+				empty = false
+			}
+		}
+		if empty {
+			analysis.RowsSkipped++
+			continue
+		}
+
+		groupKey := strings.Join(parts, "\x1f")
+		group, exists := groups[groupKey]
+		if !exists {
+			group = &cluster{key: make(map[string]interface{}, len(keyFields)), seen: make(map[string]bool), first: index}
+			for i, field := range keyFields {
+				if fuzzy {
+					group.key[field] = parts[i]
+				} else {
+					group.key[field] = row[field]
+				}
+			}
+			groups[groupKey] = group
+		}
+		group.count++
+		if len(group.rows) < duplicateClusterSampleRows {
+			group.rows = append(group.rows, duplicateSampleRow(row))
+		}
+		if fuzzy {
+			variant := strings.Join(raw, " | ")
+			if !group.seen[variant] && len(group.variants) < duplicateClusterMaxVariants {
+				group.seen[variant] = true
+				group.variants = append(group.variants, variant)
+			}
+		}
+	}
+
+	duplicates := make([]*cluster, 0)
+	for _, group := range groups {
+		if group.count > 1 {
+			duplicates = append(duplicates, group)
+			analysis.DuplicateRows += group.count - 1
+		}
+	}
+	sort.Slice(duplicates, func(i, j int) bool {
+		if duplicates[i].count != duplicates[j].count {
+			return duplicates[i].count > duplicates[j].count
+		}
+		return duplicates[i].first < duplicates[j].first
+	})
+
+	analysis.ClusterCount = len(duplicates)
+	if len(duplicates) > maxDuplicateClusters {
+		duplicates = duplicates[:maxDuplicateClusters]
+		analysis.Truncated = true
+	}
+	for _, group := range duplicates {
+		analysis.Clusters = append(analysis.Clusters, models.DuplicateCluster{
+			Key:        group.key,
+			RowCount:   group.count,
+			Variants:   group.variants,
+			SampleRows: group.rows,
+		})
+	}
+	if analysis.RowsSampled > 0 {
+		analysis.DuplicateRate = math.Round(float64(analysis.DuplicateRows)/float64(analysis.RowsSampled)*10000) / 100
+	}
+	return analysis
+}
+
+// SuggestDedupConfig 根据检测结果给出去重配置建议：键字段和匹配选项沿用本次检测，有增量字段时保留最新的一行；没有重复时不给建议
+func SuggestDedupConfig(tableFieldsConfig models.JSONB, keyFields []string, options models.DuplicateMatchOptions, analysis *DuplicateAnalysis) models.JSONB {
+	if analysis == nil || analysis.ClusterCount == 0 {
+		return nil
+	}
+	suggestion := models.JSONB{
+		"rule_type":     "deduplication",
+		"key_fields":    keyFields,
+		"match_options": options,
+		"keep":          "first",
+	}
+	for _, field := range sortedTableFields(tableFieldsConfig) {
+		if field.IsIncrementField {
+			suggestion["keep"] = "latest"
+			suggestion["order_by"] = field.NameEn
+			break
+		}
+	}
+	suggestion["description"] = fmt.Sprintf("采样%d行中发现%d组重复、%d行可去除(%.2f%%)，按键字段 %s 去重",
+		analysis.RowsSampled, analysis.ClusterCount, analysis.DuplicateRows, analysis.DuplicateRate, strings.Join(keyFields, ", "))
+	return suggestion
+}
+
+// duplicateValueString 键字段取值转为可比较的字符串，第二个返回值表示是否为空值
+func duplicateValueString(value interface{}) (string, bool) {
+	switch v := value.(type) {
+	case nil:
+		return "", true
+	case string:
+		return v, false
+	case []byte:
+		return string(v), false
+	case time.Time:
+		return v.Format(time.RFC3339Nano), false
+	default:
+		return fmt.Sprint(v), false
+	}
+}
+
+// normalizeDuplicateValue 按fuzzy匹配选项归一化取值
+func normalizeDuplicateValue(value string, options models.DuplicateMatchOptions) string {
+	if options.NormalizeWidth {
+		value = strings.Map(func(r rune) rune {
+			switch {
+			case r == '　':
+				return ' '
+			case r >= '！' && r <= '～':
+				return r - 0xFEE0
+			}
+			return r
+		}, value)
+	}
+	if options.NormalizeNumber {
+		if number, err := strconv.ParseFloat(strings.TrimSpace(value), 64); err == nil && !math.IsInf(number, 0) && !math.IsNaN(number) {
+			return strconv.FormatFloat(number, 'f', -1, 64)
+		}
+	}
+	if options.IgnoreCase {
+		value = strings.ToLower(value)
+	}
+	return strings.Map(func(r rune) rune {
+		if options.IgnoreWhitespace && unicode.IsSpace(r) {
+			return -1
+		}
+		if options.IgnorePunctuation && (unicode.IsPunct(r) || unicode.IsSymbol(r)) {
+			return -1
+		}
+		return r
+	}, value)
+}
+
+// duplicateSampleRow 复制样例行，字节值转为字符串以便序列化
+func duplicateSampleRow(row map[string]interface{}) map[string]interface{} {
+	sample := make(map[string]interface{}, len(row))
+	for key, value := range row {
+		if data, ok := value.([]byte); ok {
+			value = string(data)
+		}
+		sample[key] = value
+	}
+	return sample
+}
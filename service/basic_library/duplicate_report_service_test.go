@@ -0,0 +1,128 @@
+/*
+ * @module service/basic_library/duplicate_report_service_test
+ * @description 重复数据检测测试，覆盖精确/模糊匹配分组、空键跳过、重复簇排序与截断、键字段解析和去重配置建议
+ * @architecture 测试层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow 构造采样行 -> 分组检测 -> 验证重复簇、行数和建议配置
+ * @rules 分组逻辑为纯函数，不依赖数据库
+ * @dependencies stretchr/testify
+ * @refs duplicate_report_service.go
+ */
+
+package basic_library
+
+import (
+	"datahub-service/service/models"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetectDuplicatesExact(t *testing.T) {
+	rows := []map[string]interface{}{
+		{"id": 1, "plate": "粤B12345", "gate": "A"},
+		{"id": 2, "plate": "粤B12345", "gate": "A"},
+		{"id": 3, "plate": "粤b12345", "gate": "A"},
+		{"id": 4, "plate": "粤C00001", "gate": "B"},
+		{"id": 5, "plate": nil, "gate": nil},
+		{"id": 6, "plate": nil, "gate": nil},
+		{"id": 7, "plate": "粤C00001", "gate": []byte("B")},
+		{"id": 8, "plate": "粤C00001", "gate": "B"},
+	}
+
+	analysis := DetectDuplicates(rows, []string{"plate", "gate"}, models.DuplicateMatchOptions{Mode: models.DuplicateMatchExact})
+	assert.Equal(t, 8, analysis.RowsSampled)
+	assert.Equal(t, 2, analysis.RowsSkipped, "键字段全部为空的行不算重复")
+	assert.Equal(t, 2, analysis.ClusterCount)
+	assert.Equal(t, 3, analysis.DuplicateRows)
+	assert.Equal(t, 37.5, analysis.DuplicateRate)
+	assert.False(t, analysis.Truncated)
+
+	require.Len(t, analysis.Clusters, 2)
+	assert.Equal(t, 3, analysis.Clusters[0].RowCount, "行数多的簇排在前面")
+	assert.Equal(t, map[string]interface{}{"plate": "粤C00001", "gate": "B"}, analysis.Clusters[0].Key)
+	assert.Equal(t, "B", analysis.Clusters[0].SampleRows[1]["gate"], "字节值转为字符串")
+	assert.Equal(t, 2, analysis.Clusters[1].RowCount)
+	assert.Empty(t, analysis.Clusters[1].Variants)
+}
+
+func TestDetectDuplicatesFuzzy(t *testing.T) {
+	rows := []map[string]interface{}{
+		{"name": "ACME Co., Ltd.", "amount": "100"},
+		{"name": "acme co ltd", "amount": 100.0},
+		{"name": "ＡＣＭＥ　Co. Ltd", "amount": "100.00"},
+		{"name": "acme co ltd", "amount": "101"},
+	}
+	options, err := normalizeDuplicateMatchOptions(models.DuplicateMatchOptions{Mode: models.DuplicateMatchFuzzy})
+	require.NoError(t, err)
+	assert.True(t, options.IgnoreCase && options.IgnoreWhitespace && options.IgnorePunctuation && options.NormalizeWidth && options.NormalizeNumber,
+		"未指定归一化选项时启用全部选项")
+
+	analysis := DetectDuplicates(rows, []string{"name", "amount"}, options)
+	require.Len(t, analysis.Clusters, 1)
+	cluster := analysis.Clusters[0]
+	assert.Equal(t, 3, cluster.RowCount)
+	assert.Equal(t, map[string]interface{}{"name": "acmecoltd", "amount": "100"}, cluster.Key)
+	assert.Equal(t, []string{"ACME Co., Ltd. | 100", "acme co ltd | 100", "ＡＣＭＥ　Co. Ltd | 100.00"}, cluster.Variants)
+
+	// 只忽略大小写时全角和标点差异仍算不同
+	onlyCase := DetectDuplicates(rows, []string{"name"}, models.DuplicateMatchOptions{Mode: models.DuplicateMatchFuzzy, IgnoreCase: true})
+	require.Len(t, onlyCase.Clusters, 1)
+	assert.Equal(t, 2, onlyCase.Clusters[0].RowCount)
+
+	_, err = normalizeDuplicateMatchOptions(models.DuplicateMatchOptions{Mode: "phonetic"})
+	assert.True(t, errors.Is(err, ErrInvalidDuplicateRequest))
+}
+
+func TestDetectDuplicatesTruncatesClusters(t *testing.T) {
+	rows := make([]map[string]interface{}, 0)
+	for i := 0; i < maxDuplicateClusters+5; i++ {
+		for j := 0; j < 2; j++ {
+			rows = append(rows, map[string]interface{}{"code": fmt.Sprintf("c%d", i)})
+		}
+	}
+	rows = append(rows, map[string]interface{}{"code": "c7"}, map[string]interface{}{"code": "c7"})
+
+	analysis := DetectDuplicates(rows, []string{"code"}, models.DuplicateMatchOptions{Mode: models.DuplicateMatchExact})
+	assert.Equal(t, maxDuplicateClusters+5, analysis.ClusterCount)
+	assert.True(t, analysis.Truncated)
+	require.Len(t, analysis.Clusters, maxDuplicateClusters)
+	assert.Equal(t, "c7", analysis.Clusters[0].Key["code"])
+	assert.Len(t, analysis.Clusters[0].SampleRows, duplicateClusterSampleRows)
+	assert.Equal(t, "c0", analysis.Clusters[1].Key["code"], "行数相同时按首次出现顺序")
+}
+
+func TestResolveDuplicateKeyFieldsAndSuggestion(t *testing.T) {
+	config := models.JSONB{
+		"field_0": map[string]interface{}{"name_en": "id", "data_type": "integer", "is_primary_key": true, "order_num": 1},
+		"field_1": map[string]interface{}{"name_en": "plate", "data_type": "varchar", "order_num": 2},
+		"field_2": map[string]interface{}{"name_en": "pass_time", "data_type": "timestamp", "order_num": 3, "is_increment_field": true},
+		"field_3": map[string]interface{}{"name_en": "plate_len", "data_type": "integer", "order_num": 4, "expression": "length(plate)"},
+	}
+
+	keyFields, err := resolveDuplicateKeyFields(config, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"plate", "pass_time"}, keyFields, "默认排除主键和派生列")
+
+	keyFields, err = resolveDuplicateKeyFields(config, []string{" plate ", "plate", "id"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"plate", "id"}, keyFields)
+
+	_, err = resolveDuplicateKeyFields(config, []string{"owner"})
+	assert.True(t, errors.Is(err, ErrInvalidDuplicateRequest))
+	_, err = resolveDuplicateKeyFields(models.JSONB{}, nil)
+	assert.True(t, errors.Is(err, ErrInvalidDuplicateRequest))
+
+	options := models.DuplicateMatchOptions{Mode: models.DuplicateMatchExact}
+	assert.Nil(t, SuggestDedupConfig(config, []string{"plate"}, options, &DuplicateAnalysis{RowsSampled: 10}), "没有重复时不给建议")
+
+	suggestion := SuggestDedupConfig(config, []string{"plate"}, options, &DuplicateAnalysis{RowsSampled: 10, ClusterCount: 1, DuplicateRows: 2, DuplicateRate: 20})
+	require.NotNil(t, suggestion)
+	assert.Equal(t, "deduplication", suggestion["rule_type"])
+	assert.Equal(t, "latest", suggestion["keep"], "有增量字段时保留最新的一行")
+	assert.Equal(t, "pass_time", suggestion["order_by"])
+	assert.Equal(t, "采样10行中发现1组重复、2行可去除(20.00%)，按键字段 plate 去重", suggestion["description"])
+}
@@ -0,0 +1,130 @@
+/*
+ * @module service/basic_library/execution_diff_service
+ * @description 同步执行数据变更摘要服务，保存增量同步中每个接口的变更摘要，按执行查询实际新增和更新了哪些数据
+ * @architecture 分层架构 - 业务服务层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow 接口执行成功 -> 取出执行响应中的变更摘要 -> 按接口保存；查询时按执行汇总各接口的摘要
+ * @rules 只有增量同步和按主键写入的全量同步（历史追踪、删除同步）有变更摘要，清空重写的全量同步接口不出现在结果中；执行记录按当前租户过滤
+ * @dependencies datahub-service/service/interface_executor, gorm.io/gorm
+ * @refs service/interface_executor/sync_diff.go, service/models/sync_execution_diff.go, api/controllers/sync_task_controller.go
+ */
+
+package basic_library
+
+import (
+	"context"
+	"datahub-service/service/interface_executor"
+	"datahub-service/service/models"
+	"datahub-service/service/tenant"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// InterfaceDiff 单个接口的变更摘要
+type InterfaceDiff struct {
+	models.SyncExecutionDiff
+	InterfaceName string `json:"interface_name"`
+}
+
+// ExecutionDiff 一次同步执行的数据变更摘要
+type ExecutionDiff struct {
+	ExecutionID   string          `json:"execution_id"`
+	TaskID        string          `json:"task_id"`
+	Status        string          `json:"status"`
+	StartTime     time.Time       `json:"start_time"`
+	EndTime       *time.Time      `json:"end_time,omitempty"`
+	RowsCompared  int64           `json:"rows_compared"`
+	InsertedCount int64           `json:"inserted_count"`
+	UpdatedCount  int64           `json:"updated_count"`
+	UnchangedRows int64           `json:"unchanged_rows"`
+	DeletedCount  int64           `json:"deleted_count"`
+	Interfaces    []InterfaceDiff `json:"interfaces"`
+}
+
+// ExecutionDiffService 同步执行数据变更摘要服务
+type ExecutionDiffService struct {
+	db *gorm.DB
+}
+
+// NewExecutionDiffService 创建同步执行数据变更摘要服务
+func NewExecutionDiffService(db *gorm.DB) *ExecutionDiffService {
+	return &ExecutionDiffService{db: db}
+}
+
+// GetExecutionDiff 获取同步执行的数据变更摘要
+func (s *ExecutionDiffService) GetExecutionDiff(ctx context.Context, executionID string) (*ExecutionDiff, error) {
+	var execution models.SyncTaskExecution
+	if err := s.db.WithContext(ctx).Scopes(tenant.OwnerScope(ctx, "task_id", "sync_tasks")).
+		First(&execution, "id = ?", executionID).Error; err != nil {
+		return nil, err
+	}
+
+	var diffs []models.SyncExecutionDiff
+	if err := s.db.WithContext(ctx).Where("execution_id = ?", executionID).Order("created_at").Find(&diffs).Error; err != nil {
+		return nil, err
+	}
+
+	names := make(map[string]string)
+	if len(diffs) > 0 {
+		interfaceIDs := make([]string, 0, len(diffs))
+		for _, diff := range diffs {
+			interfaceIDs = append(interfaceIDs, diff.InterfaceID)
+		}
+		var interfaces []models.DataInterface
+		if err := s.db.WithContext(ctx).Select("id", "name_zh").Where("id IN ?", interfaceIDs).Find(&interfaces).Error; err != nil {
+			return nil, err
+		}
+		for _, iface := range interfaces {
+			names[iface.ID] = iface.NameZh
+		}
+	}
+
+	result := &ExecutionDiff{
+		ExecutionID: execution.ID,
+		TaskID:      execution.TaskID,
+		Status:      execution.Status,
+		StartTime:   execution.StartTime,
+		EndTime:     execution.EndTime,
+		Interfaces:  make([]InterfaceDiff, 0, len(diffs)),
+	}
+	for _, diff := range diffs {
+		result.RowsCompared += diff.RowsCompared
+		result.InsertedCount += diff.InsertedCount
+		result.UpdatedCount += diff.UpdatedCount
+		result.UnchangedRows += diff.UnchangedRows
+		result.DeletedCount += diff.DeletedCount
+		result.Interfaces = append(result.Interfaces, InterfaceDiff{SyncExecutionDiff: diff, InterfaceName: names[diff.InterfaceID]})
+	}
+	return result, nil
+}
+
+// recordExecutionDiff 保存接口本次同步的变更摘要，没有摘要（全量同步）时不保存
+func recordExecutionDiff(ctx context.Context, db *gorm.DB, taskID, executionID, interfaceID string, report *interface_executor.SyncDiffReport) {
+	if report == nil {
+		return
+	}
+
+	diff := models.SyncExecutionDiff{
+		ID:            uuid.New().String(),
+		ExecutionID:   executionID,
+		TaskID:        taskID,
+		InterfaceID:   interfaceID,
+		RowsCompared:  report.RowsCompared,
+		InsertedCount: report.Inserted,
+		UpdatedCount:  report.Updated,
+		UnchangedRows: report.Unchanged,
+		InsertedKeys:  report.InsertedKeys,
+		UpdatedKeys:   report.UpdatedKeys,
+		DeletedCount:  report.Deleted,
+		DeletedKeys:   report.DeletedKeys,
+		KeysTruncated: report.KeysTruncated,
+		ColumnChanges: report.ColumnChanges,
+		Incomplete:    report.Incomplete,
+	}
+	if err := db.WithContext(ctx).Create(&diff).Error; err != nil {
+		slog.Warn("记录同步数据变更摘要失败", "execution_id", executionID, "interface_id", interfaceID, "error", err)
+	}
+}
@@ -0,0 +1,53 @@
+/*
+ * @module service/basic_library/execution_diff_service_test
+ * @description 同步执行数据变更摘要服务测试，覆盖按接口保存摘要、全量同步不保存以及按执行汇总
+ * @architecture 测试层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow 准备sqlite中的任务和执行记录 -> 保存接口变更摘要 -> 查询执行的变更摘要
+ * @rules 使用内存sqlite，不依赖外部服务
+ * @dependencies gorm.io/driver/sqlite, stretchr/testify
+ * @refs execution_diff_service.go
+ */
+
+package basic_library
+
+import (
+	"context"
+	"datahub-service/service/interface_executor"
+	"datahub-service/service/models"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestGetExecutionDiff(t *testing.T) {
+	db, task := setupDiagnosticsDB(t)
+	require.NoError(t, db.AutoMigrate(&models.SyncExecutionDiff{}))
+	require.NoError(t, db.Create(&models.SyncTaskExecution{ID: "exec-1", TaskID: task.ID, ExecutionType: "manual", Status: "success", StartTime: time.Now()}).Error)
+	ctx := context.Background()
+
+	recordExecutionDiff(ctx, db, task.ID, "exec-1", "if-1", &interface_executor.SyncDiffReport{
+		RowsCompared: 5, Inserted: 2, Updated: 1, Unchanged: 2,
+		InsertedKeys:  models.SyncDiffKeys{{"id": 7}, {"id": 8}},
+		UpdatedKeys:   models.SyncDiffKeys{{"id": 3}},
+		ColumnChanges: models.SyncColumnChanges{{Column: "status", Changed: 1}},
+	})
+	recordExecutionDiff(ctx, db, task.ID, "exec-1", "if-full", nil)
+
+	diff, err := NewExecutionDiffService(db).GetExecutionDiff(ctx, "exec-1")
+	require.NoError(t, err)
+	assert.Equal(t, task.ID, diff.TaskID)
+	assert.Equal(t, int64(5), diff.RowsCompared)
+	assert.Equal(t, int64(2), diff.InsertedCount)
+	assert.Equal(t, int64(1), diff.UpdatedCount)
+	require.Len(t, diff.Interfaces, 1, "全量同步的接口没有变更摘要")
+	assert.Equal(t, "车辆进出", diff.Interfaces[0].InterfaceName)
+	assert.Len(t, diff.Interfaces[0].InsertedKeys, 2)
+	assert.Equal(t, models.SyncColumnChanges{{Column: "status", Changed: 1}}, diff.Interfaces[0].ColumnChanges)
+
+	_, err = NewExecutionDiffService(db).GetExecutionDiff(ctx, "exec-missing")
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+}
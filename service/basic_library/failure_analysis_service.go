@@ -0,0 +1,232 @@
+/*
+ * @module service/basic_library/failure_analysis_service
+ * @description 同步失败根因分析，按失败分类和失败码汇总同步任务一段时间内的失败次数和每日趋势，并给出处理建议
+ * @architecture 分层架构 - 业务服务层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow 查询任务近days天的执行记录 -> 提取失败的接口调用(无调用明细时使用执行错误信息) -> 按失败码分类 -> 汇总分类、失败码和每日趋势
+ * @rules 执行结果中已记录failure_code时直接使用，早期执行记录按错误信息重新分类；失败码按次数倒序，次数相同按最近出现时间倒序
+ * @dependencies datahub-service/service/interface_executor, datahub-service/service/meta, datahub-service/service/models, gorm.io/gorm
+ * @refs service/interface_executor/failure_classification.go, service/basic_library/health_report_service.go, api/controllers/sync_task_controller.go
+ */
+
+package basic_library
+
+import (
+	"context"
+	"datahub-service/service/interface_executor"
+	"datahub-service/service/meta"
+	"datahub-service/service/models"
+	"fmt"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	defaultFailureAnalysisDays = 30  // 默认分析天数
+	maxFailureAnalysisDays     = 180 // 最大分析天数
+)
+
+// FailureClassCount 失败分类统计
+type FailureClassCount struct {
+	Class      interface_executor.FailureClass `json:"class" example:"network"`
+	Name       string                          `json:"name" example:"网络或源系统不可用"`
+	Count      int                             `json:"count"`
+	Percentage float64                         `json:"percentage"` // 占失败总数的百分比
+}
+
+// FailureCodeCount 失败码统计，附带说明和处理建议
+type FailureCodeCount struct {
+	interface_executor.FailureCodeInfo
+	Count        int       `json:"count"`
+	FirstSeen    time.Time `json:"first_seen"`
+	LastSeen     time.Time `json:"last_seen"`
+	LastMessage  string    `json:"last_message"`
+	InterfaceIDs []string  `json:"interface_ids"` // 出现该失败的接口，任务级错误不计入
+}
+
+// FailureTrendPoint 每日失败趋势
+type FailureTrendPoint struct {
+	Date    string         `json:"date" example:"2024-05-01"`
+	Total   int            `json:"total"`
+	Classes map[string]int `json:"classes"` // 失败分类 -> 次数
+}
+
+// TaskFailureAnalysis 同步任务失败根因分析
+type TaskFailureAnalysis struct {
+	TaskID           string              `json:"task_id"`
+	PeriodStart      time.Time           `json:"period_start"`
+	PeriodEnd        time.Time           `json:"period_end"`
+	GeneratedAt      time.Time           `json:"generated_at"`
+	TotalExecutions  int                 `json:"total_executions"`
+	FailedExecutions int                 `json:"failed_executions"`
+	TotalFailures    int                 `json:"total_failures"` // 失败的接口调用次数
+	Classes          []FailureClassCount `json:"classes"`
+	Codes            []FailureCodeCount  `json:"codes"`
+	Trend            []FailureTrendPoint `json:"trend"`
+}
+
+// syncFailure 单次失败
+type syncFailure struct {
+	interfaceID string
+	message     string
+	code        string
+	at          time.Time
+}
+
+// FailureAnalysisService 同步失败根因分析服务
+type FailureAnalysisService struct {
+	db *gorm.DB
+}
+
+// NewFailureAnalysisService 创建同步失败根因分析服务实例
+func NewFailureAnalysisService(db *gorm.DB) *FailureAnalysisService {
+	return &FailureAnalysisService{db: db}
+}
+
+// AnalyzeTaskFailures 分析同步任务近days天的失败根因
+func (s *FailureAnalysisService) AnalyzeTaskFailures(ctx context.Context, taskID string, days int) (*TaskFailureAnalysis, error) {
+	if days <= 0 {
+		days = defaultFailureAnalysisDays
+	}
+	if days > maxFailureAnalysisDays {
+		days = maxFailureAnalysisDays
+	}
+
+	var task models.SyncTask
+	if err := s.db.WithContext(ctx).Select("id").First(&task, "id = ?", taskID).Error; err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	start := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location()).AddDate(0, 0, -(days - 1))
+	analysis := &TaskFailureAnalysis{
+		TaskID:      taskID,
+		PeriodStart: start,
+		PeriodEnd:   now,
+		GeneratedAt: now,
+		Classes:     []FailureClassCount{},
+		Codes:       []FailureCodeCount{},
+		Trend:       make([]FailureTrendPoint, 0, days),
+	}
+
+	var executions []models.SyncTaskExecution
+	err := s.db.WithContext(ctx).
+		Select("id", "status", "start_time", "error_message", "result").
+		Where("task_id = ? AND start_time >= ?", taskID, start).
+		Order("start_time ASC").
+		Find(&executions).Error
+	if err != nil {
+		return nil, fmt.Errorf("查询执行记录失败: %w", err)
+	}
+
+	trendIndex := make(map[string]int, days)
+	for day := start; !day.After(now); day = day.AddDate(0, 0, 1) {
+		date := day.Format("2006-01-02")
+		trendIndex[date] = len(analysis.Trend)
+		analysis.Trend = append(analysis.Trend, FailureTrendPoint{Date: date, Classes: map[string]int{}})
+	}
+
+	classCounts := make(map[interface_executor.FailureClass]int)
+	codeStats := make(map[string]*FailureCodeCount)
+	for _, execution := range executions {
+		analysis.TotalExecutions++
+		if execution.Status == meta.SyncExecutionStatusFailed {
+			analysis.FailedExecutions++
+		}
+
+		for _, failure := range executionFailures(&execution) {
+			info := interface_executor.LookupFailureCode(failure.code)
+			analysis.TotalFailures++
+			classCounts[info.Class]++
+
+			stat, ok := codeStats[info.Code]
+			if !ok {
+				stat = &FailureCodeCount{FailureCodeInfo: info, FirstSeen: failure.at, InterfaceIDs: []string{}}
+				codeStats[info.Code] = stat
+			}
+			stat.Count++
+			stat.LastSeen = failure.at
+			stat.LastMessage = failure.message
+			if failure.interfaceID != "" && !contains(stat.InterfaceIDs, failure.interfaceID) {
+				stat.InterfaceIDs = append(stat.InterfaceIDs, failure.interfaceID)
+			}
+
+			if i, ok := trendIndex[failure.at.In(now.Location()).Format("2006-01-02")]; ok {
+				analysis.Trend[i].Total++
+				analysis.Trend[i].Classes[string(info.Class)]++
+			}
+		}
+	}
+
+	for class, count := range classCounts {
+		analysis.Classes = append(analysis.Classes, FailureClassCount{
+			Class:      class,
+			Name:       interface_executor.FailureClassNames[class],
+			Count:      count,
+			Percentage: float64(count) * 100 / float64(analysis.TotalFailures),
+		})
+	}
+	sort.Slice(analysis.Classes, func(i, j int) bool {
+		if analysis.Classes[i].Count != analysis.Classes[j].Count {
+			return analysis.Classes[i].Count > analysis.Classes[j].Count
+		}
+		return analysis.Classes[i].Class < analysis.Classes[j].Class
+	})
+
+	for _, stat := range codeStats {
+		analysis.Codes = append(analysis.Codes, *stat)
+	}
+	sort.Slice(analysis.Codes, func(i, j int) bool {
+		if analysis.Codes[i].Count != analysis.Codes[j].Count {
+			return analysis.Codes[i].Count > analysis.Codes[j].Count
+		}
+		return analysis.Codes[i].LastSeen.After(analysis.Codes[j].LastSeen)
+	})
+
+	return analysis, nil
+}
+
+// executionFailures 提取执行中失败的接口调用；没有失败的调用明细但执行失败时，使用执行错误信息
+func executionFailures(execution *models.SyncTaskExecution) []syncFailure {
+	var failures []syncFailure
+	var results []map[string]interface{}
+	_ = decodeJSON(execution.Result["interface_results"], &results)
+	for _, result := range results {
+		if result["success"] == true {
+			continue
+		}
+		message := toString(result["error"])
+		if message == "" {
+			continue
+		}
+		at := execution.StartTime
+		if startedAt, err := time.Parse(time.RFC3339Nano, toString(result["started_at"])); err == nil {
+			at = startedAt
+		}
+		failures = append(failures, syncFailure{
+			interfaceID: toString(result["interface_id"]),
+			message:     message,
+			code:        failureCodeOf(result, message),
+			at:          at,
+		})
+	}
+
+	if len(failures) == 0 && execution.Status == meta.SyncExecutionStatusFailed && execution.ErrorMessage != "" {
+		failures = append(failures, syncFailure{
+			message: execution.ErrorMessage,
+			code:    interface_executor.ClassifyFailureMessage(execution.ErrorMessage).Code,
+			at:      execution.StartTime,
+		})
+	}
+	return failures
+}
+
+// failureCodeOf 读取接口调用结果中记录的失败码，早期记录没有失败码时按错误信息分类
+func failureCodeOf(result map[string]interface{}, message string) string {
+	if code := toString(result["failure_code"]); code != "" {
+		return code
+	}
+	return interface_executor.ClassifyFailureMessage(message).Code
+}
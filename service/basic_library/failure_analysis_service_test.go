@@ -0,0 +1,88 @@
+/*
+ * @module service/basic_library/failure_analysis_service_test
+ * @description 同步失败根因分析测试，覆盖按分类和失败码汇总、早期记录按错误信息重新分类、任务级错误和每日趋势
+ * @architecture 测试层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow 准备sqlite中的任务和执行记录 -> 分析失败根因 -> 验证统计结果
+ * @rules 使用内存sqlite，不依赖外部服务
+ * @dependencies gorm.io/driver/sqlite, stretchr/testify
+ * @refs failure_analysis_service.go
+ */
+
+package basic_library
+
+import (
+	"context"
+	"datahub-service/service/interface_executor"
+	"datahub-service/service/models"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestAnalyzeTaskFailures(t *testing.T) {
+	db, task := setupDiagnosticsDB(t)
+	now := time.Now()
+	yesterday := now.AddDate(0, 0, -1)
+
+	uniqueErr := `ERROR: duplicate key value violates unique constraint "t_pkey" (SQLSTATE 23505)`
+	createExecution := func(id, status string, start time.Time, errorMessage string, results ...map[string]interface{}) {
+		items := make([]interface{}, len(results))
+		for i, result := range results {
+			items[i] = result
+		}
+		require.NoError(t, db.Create(&models.SyncTaskExecution{
+			ID: id, TaskID: task.ID, ExecutionType: "scheduled", Status: status, StartTime: start,
+			ErrorMessage: errorMessage, Result: models.JSONB{"interface_results": items},
+		}).Error)
+	}
+
+	createExecution("exec-1", "failed", yesterday, "", newInterfaceCallResult("if-1", false, 10, 0, uniqueErr))
+	createExecution("exec-2", "failed", now, "", newInterfaceCallResult("if-1", false, 10, 0, uniqueErr), newInterfaceCallResult("if-2", true, 10, 5, ""))
+	// 早期执行记录没有failure_code，按错误信息分类
+	createExecution("exec-3", "failed", now, "", map[string]interface{}{"interface_id": "if-2", "success": false, "error": "HTTP请求失败，状态码: 401"})
+	// 没有接口调用明细的任务级错误
+	createExecution("exec-4", "failed", now, "dial tcp 10.0.0.1:80: connect: connection refused")
+	createExecution("exec-5", "success", now, "", newInterfaceCallResult("if-1", true, 10, 5, ""))
+	// 超出统计范围
+	createExecution("exec-6", "failed", now.AddDate(0, 0, -10), "", newInterfaceCallResult("if-1", false, 10, 0, uniqueErr))
+
+	analysis, err := NewFailureAnalysisService(db).AnalyzeTaskFailures(context.Background(), task.ID, 7)
+	require.NoError(t, err)
+
+	assert.Equal(t, 5, analysis.TotalExecutions)
+	assert.Equal(t, 4, analysis.FailedExecutions)
+	assert.Equal(t, 4, analysis.TotalFailures)
+
+	require.Len(t, analysis.Classes, 3)
+	assert.Equal(t, interface_executor.FailureClassConstraint, analysis.Classes[0].Class)
+	assert.Equal(t, 2, analysis.Classes[0].Count)
+	assert.InDelta(t, 50.0, analysis.Classes[0].Percentage, 0.01)
+
+	require.Len(t, analysis.Codes, 3)
+	top := analysis.Codes[0]
+	assert.Equal(t, interface_executor.FailureCodeConstraintUnique, top.Code)
+	assert.Equal(t, 2, top.Count)
+	assert.Equal(t, []string{"if-1"}, top.InterfaceIDs)
+	assert.NotEmpty(t, top.Remediation)
+	assert.Equal(t, uniqueErr, top.LastMessage)
+
+	codes := map[string]FailureCodeCount{}
+	for _, code := range analysis.Codes {
+		codes[code.Code] = code
+	}
+	assert.Equal(t, []string{"if-2"}, codes[interface_executor.FailureCodeAuthInvalidCredentials].InterfaceIDs)
+	assert.Empty(t, codes[interface_executor.FailureCodeNetworkUnreachable].InterfaceIDs)
+
+	require.Len(t, analysis.Trend, 7)
+	last := analysis.Trend[len(analysis.Trend)-1]
+	assert.Equal(t, now.Format("2006-01-02"), last.Date)
+	assert.Equal(t, 3, last.Total)
+	assert.Equal(t, 1, analysis.Trend[len(analysis.Trend)-2].Classes[string(interface_executor.FailureClassConstraint)])
+
+	_, err = NewFailureAnalysisService(db).AnalyzeTaskFailures(context.Background(), "missing", 7)
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+}
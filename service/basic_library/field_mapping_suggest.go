@@ -0,0 +1,431 @@
+/*
+ * @module service/basic_library/field_mapping_suggest
+ * @description 字段映射自动建议，对比源数据样例字段与接口表字段，生成可直接写入parseConfig.fieldMapping的映射方案
+ * @architecture 分层架构 - 业务服务层，评分逻辑为纯函数
+ * @documentReference ai_docs/interfaces.md
+ * @stateFlow 获取样例数据 -> 推断源字段类型 -> 名称与类型评分 -> 一对一分配 -> 按得分排序
+ * @rules 名称相似度支持驼峰/下划线转换、中文名及其拼音全拼/首字母；类型不兼容的字段不建议映射；每个源字段和目标字段最多映射一次
+ * @dependencies datahub-service/service/utils, datahub-service/service/interface_executor
+ * @refs interface_service.go, service/interface_executor/field_mapping.go
+ */
+
+package basic_library
+
+import (
+	"context"
+	"datahub-service/service/interface_executor"
+	"datahub-service/service/models"
+	"datahub-service/service/utils"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+const (
+	// suggestSampleLimit 未提供样例时从数据源读取的样例行数
+	suggestSampleLimit = 20
+	// suggestMinNameScore 名称相似度低于该值的字段不参与映射
+	suggestMinNameScore = 0.6
+	// suggestMaxAlternatives 每个源字段返回的候选目标字段数
+	suggestMaxAlternatives = 3
+)
+
+// FieldMappingSuggestion 字段映射建议结果
+type FieldMappingSuggestion struct {
+	InterfaceID      string                   `json:"interface_id"`
+	SampleRows       int                      `json:"sample_rows"`
+	FieldMapping     []map[string]interface{} `json:"fieldMapping"` // 与parseConfig.fieldMapping格式一致
+	Suggestions      []FieldMatch             `json:"suggestions"`  // 按得分从高到低排列
+	UnmatchedSources []string                 `json:"unmatched_sources"`
+	UnmatchedTargets []string                 `json:"unmatched_targets"`
+}
+
+// FieldMatch 单个源字段的映射建议
+type FieldMatch struct {
+	Source         string           `json:"source"`
+	Target         string           `json:"target"`
+	TargetNameZh   string           `json:"target_name_zh,omitempty"`
+	Score          float64          `json:"score"`
+	NameScore      float64          `json:"name_score"`
+	TypeScore      float64          `json:"type_score"`
+	SourceType     string           `json:"source_type"`
+	TargetType     string           `json:"target_type"`
+	Reason         string           `json:"reason"`
+	Alternatives   []FieldCandidate `json:"alternatives,omitempty"`
+	targetPosition int
+}
+
+// FieldCandidate 候选目标字段
+type FieldCandidate struct {
+	Target string  `json:"target"`
+	Score  float64 `json:"score"`
+	Reason string  `json:"reason"`
+}
+
+// SuggestFieldMapping 生成字段映射建议，sample为空时通过接口预览从数据源读取样例
+func (s *InterfaceService) SuggestFieldMapping(interfaceID string, sample []map[string]interface{}) (*FieldMappingSuggestion, error) {
+	interfaceData, err := s.GetDataInterface(interfaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	targets := s.extractFieldsFromConfig(interfaceData.TableFieldsConfig)
+	if len(targets) == 0 {
+		return nil, fmt.Errorf("接口未配置表字段，无法生成映射建议")
+	}
+
+	if len(sample) == 0 {
+		response, err := s.executor.Execute(context.Background(), &interface_executor.ExecuteRequest{
+			InterfaceID:   interfaceID,
+			InterfaceType: "basic_library",
+			ExecuteType:   "preview",
+			Limit:         suggestSampleLimit,
+			Parameters:    map[string]interface{}{"limit": suggestSampleLimit},
+			Options:       map[string]interface{}{},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("获取样例数据失败: %w", err)
+		}
+		sample, _ = response.Data.([]map[string]interface{})
+	}
+	if len(sample) == 0 {
+		return nil, fmt.Errorf("样例数据为空，无法生成映射建议")
+	}
+
+	result := s.suggestFieldMatches(sample, targets)
+	result.InterfaceID = interfaceID
+	return result, nil
+}
+
+// suggestFieldMatches 对样例字段与目标字段两两评分，按得分从高到低贪心分配
+func (s *InterfaceService) suggestFieldMatches(sample []map[string]interface{}, targets []models.TableField) *FieldMappingSuggestion {
+	sources, sourceTypes := s.sampleFieldTypes(sample)
+
+	type pair struct {
+		source, target int
+		match          FieldMatch
+	}
+	pairs := make([]pair, 0, len(sources)*len(targets))
+	for i, source := range sources {
+		for j, target := range targets {
+			if target.NameEn == "" {
+				continue
+			}
+			nameScore, reason := fieldNameScore(source, target)
+			typeScore := typeCompatibility(sourceTypes[source], target.DataType)
+			if nameScore < suggestMinNameScore || typeScore == 0 {
+				continue
+			}
+			pairs = append(pairs, pair{source: i, target: j, match: FieldMatch{
+				Source:         source,
+				Target:         target.NameEn,
+				TargetNameZh:   target.NameZh,
+				Score:          roundScore(0.8*nameScore + 0.2*typeScore),
+				NameScore:      roundScore(nameScore),
+				TypeScore:      typeScore,
+				SourceType:     sourceTypes[source],
+				TargetType:     target.DataType,
+				Reason:         reason,
+				targetPosition: j,
+			}})
+		}
+	}
+
+	// 得分相同时按源字段、目标字段出现顺序，保证结果稳定
+	sort.SliceStable(pairs, func(a, b int) bool {
+		if pairs[a].match.Score != pairs[b].match.Score {
+			return pairs[a].match.Score > pairs[b].match.Score
+		}
+		if pairs[a].source != pairs[b].source {
+			return pairs[a].source < pairs[b].source
+		}
+		return pairs[a].target < pairs[b].target
+	})
+
+	assignedSource := make(map[int]int)
+	assignedTarget := make(map[int]bool)
+	matches := make([]FieldMatch, 0)
+	for _, p := range pairs {
+		if _, done := assignedSource[p.source]; done || assignedTarget[p.target] {
+			continue
+		}
+		assignedSource[p.source] = len(matches)
+		assignedTarget[p.target] = true
+		matches = append(matches, p.match)
+	}
+
+	// 其余得分较高的目标字段作为候选，便于人工调整
+	for _, p := range pairs {
+		index, ok := assignedSource[p.source]
+		if !ok || matches[index].targetPosition == p.target || len(matches[index].Alternatives) >= suggestMaxAlternatives {
+			continue
+		}
+		matches[index].Alternatives = append(matches[index].Alternatives, FieldCandidate{
+			Target: p.match.Target,
+			Score:  p.match.Score,
+			Reason: p.match.Reason,
+		})
+	}
+
+	result := &FieldMappingSuggestion{
+		SampleRows:       len(sample),
+		FieldMapping:     make([]map[string]interface{}, 0, len(matches)),
+		Suggestions:      matches,
+		UnmatchedSources: make([]string, 0),
+		UnmatchedTargets: make([]string, 0),
+	}
+	for _, match := range matches {
+		result.FieldMapping = append(result.FieldMapping, map[string]interface{}{
+			"source": match.Source,
+			"target": match.Target,
+		})
+	}
+	for i, source := range sources {
+		if _, ok := assignedSource[i]; !ok {
+			result.UnmatchedSources = append(result.UnmatchedSources, source)
+		}
+	}
+	for j, target := range targets {
+		if !assignedTarget[j] && target.NameEn != "" {
+			result.UnmatchedTargets = append(result.UnmatchedTargets, target.NameEn)
+		}
+	}
+	return result
+}
+
+// sampleFieldTypes 按首次出现的行收集样例字段（同一行内按字段名排序），并取每个字段第一个非空值推断类型
+func (s *InterfaceService) sampleFieldTypes(sample []map[string]interface{}) ([]string, map[string]string) {
+	fields := make([]string, 0)
+	types := make(map[string]string)
+	for _, row := range sample {
+		keys := make([]string, 0, len(row))
+		for key := range row {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			if _, seen := types[key]; !seen {
+				fields = append(fields, key)
+				types[key] = "null"
+			}
+			if types[key] == "null" && row[key] != nil {
+				types[key] = s.sampleValueType(row[key])
+			}
+		}
+	}
+	return fields, types
+}
+
+// sampleValueType 推断样例值的类型
+func (s *InterfaceService) sampleValueType(value interface{}) string {
+	switch v := value.(type) {
+	case bool:
+		return "boolean"
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return "integer"
+	case float32:
+		if float64(v) == math.Trunc(float64(v)) {
+			return "integer"
+		}
+		return "float"
+	case float64:
+		// JSON数字统一解析为float64，整数值按整数处理
+		if v == math.Trunc(v) {
+			return "integer"
+		}
+		return "float"
+	case string:
+		if s.isDateTime(v) {
+			return "datetime"
+		}
+		return "string"
+	case map[string]interface{}, []interface{}:
+		return "object"
+	default:
+		return "string"
+	}
+}
+
+// typeCategory 将数据库字段类型归类
+func typeCategory(dataType string) string {
+	t := strings.ToLower(strings.TrimSpace(dataType))
+	if i := strings.IndexAny(t, "( "); i >= 0 {
+		t = t[:i]
+	}
+	switch t {
+	case "int", "integer", "int2", "int4", "int8", "smallint", "bigint", "serial", "bigserial":
+		return "integer"
+	case "float", "float4", "float8", "double", "real", "decimal", "numeric":
+		return "float"
+	case "bool", "boolean":
+		return "boolean"
+	case "timestamp", "timestamptz", "datetime", "date", "time":
+		return "datetime"
+	case "json", "jsonb":
+		return "object"
+	default:
+		return "string"
+	}
+}
+
+// typeCompatibility 源字段类型写入目标字段类型的兼容程度，0表示不兼容
+func typeCompatibility(sourceType, targetType string) float64 {
+	target := typeCategory(targetType)
+	if sourceType == "null" || sourceType == "" {
+		return 0.7
+	}
+	if sourceType == target {
+		return 1
+	}
+
+	switch target {
+	case "string":
+		if sourceType == "object" {
+			return 0.5
+		}
+		return 0.8
+	case "float":
+		if sourceType == "integer" {
+			return 1
+		}
+	case "integer":
+		if sourceType == "boolean" {
+			return 0.5
+		}
+	case "object":
+		return 0.5
+	}
+
+	// 字符串可能承载数值、时间等任意类型，由写入时转换
+	if sourceType == "string" {
+		return 0.6
+	}
+	return 0
+}
+
+// fieldNameScore 计算源字段名与目标字段的名称相似度
+func fieldNameScore(source string, target models.TableField) (float64, string) {
+	sourceTokens := splitFieldName(source)
+	sourceJoined := strings.Join(sourceTokens, "")
+	targetTokens := splitFieldName(target.NameEn)
+	targetJoined := strings.Join(targetTokens, "")
+
+	if sourceJoined != "" && sourceJoined == targetJoined {
+		return 1, "名称一致"
+	}
+	if target.NameZh != "" && strings.TrimSpace(source) == target.NameZh {
+		return 1, "与中文名一致"
+	}
+
+	if target.NameZh != "" && sourceJoined != "" {
+		full, initials, ok := utils.ToPinyin(target.NameZh)
+		if ok && full != "" {
+			if sourceJoined == full {
+				return 0.95, "与中文名全拼一致"
+			}
+			if len(initials) >= 2 && sourceJoined == initials {
+				return 0.9, "与中文名拼音首字母一致"
+			}
+		}
+	}
+
+	best := stringSimilarity(sourceJoined, targetJoined)
+	reason := "名称相似"
+	if jaccard := tokenJaccard(sourceTokens, targetTokens); jaccard > best {
+		best = jaccard
+		reason = "名称分词重合"
+	}
+	return best, reason
+}
+
+// splitFieldName 按驼峰、下划线、中划线、空格和数字边界拆分字段名并转为小写
+func splitFieldName(name string) []string {
+	tokens := make([]string, 0)
+	var current []rune
+	flush := func() {
+		if len(current) > 0 {
+			tokens = append(tokens, strings.ToLower(string(current)))
+			current = current[:0]
+		}
+	}
+
+	runes := []rune(name)
+	for i, ch := range runes {
+		switch {
+		case !unicode.IsLetter(ch) && !unicode.IsDigit(ch):
+			flush()
+			continue
+		case len(current) > 0 && unicode.IsUpper(ch):
+			prev := runes[i-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			// userID -> user,id；HTTPCode -> http,code
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextLower) {
+				flush()
+			}
+		case len(current) > 0 && unicode.IsDigit(ch) != unicode.IsDigit(runes[i-1]):
+			flush()
+		}
+		current = append(current, ch)
+	}
+	flush()
+	return tokens
+}
+
+// stringSimilarity 基于编辑距离的相似度，取值0~1
+func stringSimilarity(a, b string) float64 {
+	if a == "" || b == "" {
+		return 0
+	}
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return 1 - float64(prev[len(rb)])/float64(max(len(ra), len(rb)))
+}
+
+// tokenJaccard 分词集合的Jaccard相似度
+func tokenJaccard(a, b []string) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	set := make(map[string]bool, len(a))
+	for _, token := range a {
+		set[token] = true
+	}
+	union := len(set)
+	intersection := 0
+	counted := make(map[string]bool, len(b))
+	for _, token := range b {
+		if counted[token] {
+			continue
+		}
+		counted[token] = true
+		if set[token] {
+			intersection++
+		} else {
+			union++
+		}
+	}
+	return float64(intersection) / float64(union)
+}
+
+// roundScore 得分保留两位小数
+func roundScore(score float64) float64 {
+	return math.Round(score*100) / 100
+}
@@ -0,0 +1,78 @@
+/*
+ * @module service/basic_library/field_mapping_suggest_test
+ * @description 字段映射建议测试，覆盖驼峰/下划线转换、中文名拼音匹配、类型兼容过滤和一对一分配
+ * @architecture 测试层
+ * @documentReference ai_docs/interfaces.md
+ * @stateFlow 构造样例数据和表字段 -> 生成建议 -> 验证映射和未匹配字段
+ * @rules 纯函数测试，不依赖数据库和数据源
+ * @dependencies stretchr/testify
+ * @refs field_mapping_suggest.go, service/utils/pinyin.go
+ */
+
+package basic_library
+
+import (
+	"datahub-service/service/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestSuggestFieldMatches 测试按名称和类型生成一对一的映射建议
+func TestSuggestFieldMatches(t *testing.T) {
+	s := &InterfaceService{}
+	sample := []map[string]interface{}{
+		{"userId": float64(1), "xm": "张三", "sfzh": "110101199001011234", "createTime": "2026-01-02 08:00:00", "remark": map[string]interface{}{"a": 1}},
+		{"userId": float64(2), "xm": "李四", "HTTPCode": float64(200)},
+	}
+	targets := []models.TableField{
+		{NameEn: "user_id", NameZh: "用户ID", DataType: "bigint"},
+		{NameEn: "name", NameZh: "姓名", DataType: "varchar"},
+		{NameEn: "id_card", NameZh: "身份证号", DataType: "varchar"},
+		{NameEn: "create_time", NameZh: "创建时间", DataType: "timestamp"},
+		{NameEn: "http_code", NameZh: "状态码", DataType: "int"},
+		{NameEn: "remark", NameZh: "备注", DataType: "boolean"},
+	}
+
+	result := s.suggestFieldMatches(sample, targets)
+
+	mapping := make(map[string]string)
+	for _, m := range result.Suggestions {
+		mapping[m.Source] = m.Target
+	}
+	assert.Equal(t, map[string]string{
+		"userId":     "user_id",
+		"xm":         "name",
+		"sfzh":       "id_card",
+		"createTime": "create_time",
+		"HTTPCode":   "http_code",
+	}, mapping)
+	assert.Len(t, result.FieldMapping, 5)
+	assert.Equal(t, []string{"remark"}, result.UnmatchedSources, "对象值不能写入布尔字段")
+	assert.Equal(t, []string{"remark"}, result.UnmatchedTargets)
+	assert.Equal(t, 2, result.SampleRows)
+
+	for i := 1; i < len(result.Suggestions); i++ {
+		assert.GreaterOrEqual(t, result.Suggestions[i-1].Score, result.Suggestions[i].Score, "建议按得分从高到低排列")
+	}
+}
+
+// TestSuggestFieldMatchesOneToOne 测试多个源字段竞争同一目标字段时只分配给得分最高的字段
+func TestSuggestFieldMatchesOneToOne(t *testing.T) {
+	s := &InterfaceService{}
+	sample := []map[string]interface{}{{"device_code": "d1", "devicecode2": "d2"}}
+	targets := []models.TableField{{NameEn: "device_code", DataType: "varchar"}}
+
+	result := s.suggestFieldMatches(sample, targets)
+
+	assert.Equal(t, []map[string]interface{}{{"source": "device_code", "target": "device_code"}}, result.FieldMapping)
+	assert.Equal(t, []string{"devicecode2"}, result.UnmatchedSources)
+}
+
+// TestSplitFieldName 测试字段名分词
+func TestSplitFieldName(t *testing.T) {
+	assert.Equal(t, []string{"user", "id"}, splitFieldName("userID"))
+	assert.Equal(t, []string{"http", "code"}, splitFieldName("HTTPCode"))
+	assert.Equal(t, []string{"create", "time"}, splitFieldName("create_time"))
+	assert.Equal(t, []string{"addr", "2", "line"}, splitFieldName("addr2-line"))
+}
@@ -0,0 +1,521 @@
+/*
+ * @module service/basic_library/freshness_service
+ * @description 接口数据新鲜度监控，比较每个接口最近一次成功同步的时间和预期同步周期，超过周期未同步成功时标记为过期并发出告警事件
+ * @architecture 分层架构 - 业务服务层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow 同步成功 -> 记录最近成功时间(过期接口恢复为fresh)；定时检查 -> 计算预期周期(SLA优先，否则按激活任务调度推导) -> 判断fresh/stale/unknown -> 状态变化时记录应用事件
+ * @rules 按调度推导时取接口所属激活任务中最短的周期，cron任务取未来若干次触发的最大间隔，超过周期的freshnessGraceFactor倍才算过期；配置SLA时超过SLA即过期；从未同步成功的接口以创建时间为起点；只在进入过期状态时告警一次，恢复时记录恢复事件；数据源维护中进入过期状态时不告警
+ * @dependencies datahub-service/service/distributed_lock, datahub-service/service/eventlog, datahub-service/service/meta, datahub-service/service/models, gorm.io/gorm, github.com/robfig/cron/v3
+ * @refs service/models/interface_freshness.go, service/basic_library/sync_task_service.go, api/controllers/freshness_controller.go
+ */
+
+package basic_library
+
+import (
+	"context"
+	"datahub-service/service/distributed_lock"
+	"datahub-service/service/eventlog"
+	"datahub-service/service/meta"
+	"datahub-service/service/models"
+	"datahub-service/service/tenant"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	// defaultFreshnessSchedule 默认每5分钟检查一次
+	defaultFreshnessSchedule = "0 */5 * * * *"
+	// freshnessGraceFactor 按调度推导时，超过预期周期的倍数才算过期，避免单次调度延迟误报
+	freshnessGraceFactor = 2
+	// cronCadenceSamples 计算cron任务周期时采样的触发次数
+	cronCadenceSamples = 8
+	// freshnessLockKey 多实例部署时检查任务的锁
+	freshnessLockKey = "interface_freshness_check"
+	freshnessLockTTL = 5 * time.Minute
+)
+
+// ErrInvalidFreshnessSLA SLA不合法
+var ErrInvalidFreshnessSLA = errors.New("新鲜度SLA不能小于0")
+
+// InterfaceFreshnessView 接口数据新鲜度，附带接口和基础库名称
+type InterfaceFreshnessView struct {
+	models.InterfaceFreshness
+	InterfaceName string `json:"interface_name"`
+	LibraryID     string `json:"library_id"`
+	LibraryName   string `json:"library_name"`
+	AgeSeconds    *int64 `json:"age_seconds,omitempty"` // 距最近一次成功同步的秒数
+}
+
+// FreshnessCheckResult 一次新鲜度检查的结果
+type FreshnessCheckResult struct {
+	Checked    int `json:"checked"`
+	Fresh      int `json:"fresh"`
+	Stale      int `json:"stale"`
+	Unknown    int `json:"unknown"`
+	NewlyStale int `json:"newly_stale"` // 本次检查新进入过期状态的接口数
+	Recovered  int `json:"recovered"`
+}
+
+// freshnessCheckColumns 检查时写回的列，不覆盖同步过程中写入的最近成功时间和手工配置的SLA
+var freshnessCheckColumns = []string{"expected_interval_seconds", "expectation_source", "status", "stale_since", "last_checked_at", "last_alerted_at", "updated_at"}
+
+// FreshnessService 接口数据新鲜度监控服务
+type FreshnessService struct {
+	db       *gorm.DB
+	lock     distributed_lock.DistributedLock
+	schedule string
+	cron     *cron.Cron
+	ctx      context.Context
+	cancel   context.CancelFunc
+	started  bool
+}
+
+// NewFreshnessService 创建接口数据新鲜度监控服务实例，检查周期可通过FRESHNESS_CHECK_SCHEDULE配置
+func NewFreshnessService(db *gorm.DB) *FreshnessService {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	schedule := os.Getenv("FRESHNESS_CHECK_SCHEDULE")
+	if schedule == "" {
+		schedule = defaultFreshnessSchedule
+	}
+
+	return &FreshnessService{
+		db:       db,
+		schedule: schedule,
+		cron:     cron.New(cron.WithSeconds()),
+		ctx:      ctx,
+		cancel:   cancel,
+	}
+}
+
+// SetDistributedLock 设置分布式锁，多实例部署时避免重复检查和重复告警
+func (s *FreshnessService) SetDistributedLock(lock distributed_lock.DistributedLock) {
+	s.lock = lock
+}
+
+// CheckAll 检查所有启用接口的数据新鲜度
+func (s *FreshnessService) CheckAll(ctx context.Context) (*FreshnessCheckResult, error) {
+	var interfaces []models.DataInterface
+	err := s.db.WithContext(ctx).Select("id", "library_id", "data_source_id", "name_zh", "created_at").
+		Where("status = ?", "active").
+		Find(&interfaces).Error
+	if err != nil {
+		return nil, fmt.Errorf("查询数据接口失败: %w", err)
+	}
+	return s.check(ctx, interfaces)
+}
+
+// CheckInterface 立即检查指定接口的数据新鲜度
+func (s *FreshnessService) CheckInterface(ctx context.Context, interfaceID string) (*InterfaceFreshnessView, error) {
+	var iface models.DataInterface
+	err := s.db.WithContext(ctx).Select("id", "library_id", "data_source_id", "name_zh", "created_at").
+		Scopes(tenant.OwnerScope(ctx, "library_id", "basic_libraries")).
+		First(&iface, "id = ?", interfaceID).Error
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.check(ctx, []models.DataInterface{iface}); err != nil {
+		return nil, err
+	}
+	return s.GetFreshness(ctx, interfaceID)
+}
+
+// GetFreshness 获取指定接口的数据新鲜度，尚未检查过的接口返回unknown状态
+func (s *FreshnessService) GetFreshness(ctx context.Context, interfaceID string) (*InterfaceFreshnessView, error) {
+	iface, err := getTenantInterface(ctx, s.db, interfaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	view := &InterfaceFreshnessView{
+		InterfaceFreshness: models.InterfaceFreshness{
+			InterfaceID:       interfaceID,
+			ExpectationSource: models.FreshnessSourceNone,
+			Status:            models.FreshnessStatusUnknown,
+		},
+		InterfaceName: iface.NameZh,
+		LibraryID:     iface.LibraryID,
+		LibraryName:   iface.BasicLibrary.NameZh,
+	}
+	err = s.db.WithContext(ctx).First(&view.InterfaceFreshness, "interface_id = ?", interfaceID).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	view.AgeSeconds = freshnessAge(view.LastSuccessAt, time.Now())
+	return view, nil
+}
+
+// GetFreshnessList 分页获取已检查过的接口数据新鲜度，过期的接口排在前面
+func (s *FreshnessService) GetFreshnessList(ctx context.Context, page, size int, status, libraryID string) ([]InterfaceFreshnessView, int64, error) {
+	query := s.db.WithContext(ctx).Table("interface_freshness").
+		Joins("JOIN data_interfaces ON data_interfaces.id = interface_freshness.interface_id").
+		Joins("LEFT JOIN basic_libraries ON basic_libraries.id = data_interfaces.library_id").
+		Scopes(tenant.OwnerScope(ctx, "data_interfaces.library_id", "basic_libraries"))
+	if status != "" {
+		query = query.Where("interface_freshness.status = ?", status)
+	}
+	if libraryID != "" {
+		query = query.Where("data_interfaces.library_id = ?", libraryID)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	views := []InterfaceFreshnessView{}
+	err := query.Select("interface_freshness.*, data_interfaces.name_zh AS interface_name, data_interfaces.library_id AS library_id, basic_libraries.name_zh AS library_name").
+		Order(clause.Expr{SQL: "CASE interface_freshness.status WHEN ? THEN 0 ELSE 1 END, interface_freshness.stale_since ASC, data_interfaces.name_zh ASC", Vars: []interface{}{models.FreshnessStatusStale}}).
+		Offset((page - 1) * size).Limit(size).
+		Scan(&views).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	now := time.Now()
+	for i := range views {
+		views[i].AgeSeconds = freshnessAge(views[i].LastSuccessAt, now)
+	}
+	return views, total, nil
+}
+
+// UpdateSLA 配置接口的新鲜度SLA并立即重新检查，slaSeconds为0表示按同步任务调度推导
+func (s *FreshnessService) UpdateSLA(ctx context.Context, interfaceID string, slaSeconds int, username string) (*InterfaceFreshnessView, error) {
+	if slaSeconds < 0 {
+		return nil, ErrInvalidFreshnessSLA
+	}
+	var iface models.DataInterface
+	err := s.db.WithContext(ctx).Select("id").
+		Scopes(tenant.OwnerScope(ctx, "library_id", "basic_libraries")).
+		First(&iface, "id = ?", interfaceID).Error
+	if err != nil {
+		return nil, err
+	}
+
+	record := &models.InterfaceFreshness{
+		InterfaceID:       interfaceID,
+		SLASeconds:        slaSeconds,
+		ExpectationSource: models.FreshnessSourceNone,
+		Status:            models.FreshnessStatusUnknown,
+		UpdatedAt:         time.Now(),
+		UpdatedBy:         username,
+	}
+	err = s.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "interface_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"sla_seconds", "updated_at", "updated_by"}),
+	}).Create(record).Error
+	if err != nil {
+		return nil, fmt.Errorf("保存新鲜度SLA失败: %w", err)
+	}
+	return s.CheckInterface(ctx, interfaceID)
+}
+
+// Start 启动定时检查任务
+func (s *FreshnessService) Start() error {
+	if s.started {
+		return fmt.Errorf("数据新鲜度检查调度器已经启动")
+	}
+
+	_, err := s.cron.AddFunc(s.schedule, func() {
+		if s.lock != nil {
+			locked, err := s.lock.TryLock(s.ctx, freshnessLockKey, freshnessLockTTL)
+			if err != nil || !locked {
+				return
+			}
+			defer func() {
+				if err := s.lock.Unlock(context.Background(), freshnessLockKey); err != nil {
+					slog.Error("释放数据新鲜度检查锁失败", "error", err)
+				}
+			}()
+		}
+
+		result, err := s.CheckAll(s.ctx)
+		if err != nil {
+			slog.Error("定时数据新鲜度检查失败", "error", err)
+			return
+		}
+		slog.Debug("定时数据新鲜度检查完成", "checked", result.Checked, "stale", result.Stale, "newly_stale", result.NewlyStale)
+	})
+	if err != nil {
+		return fmt.Errorf("添加定时任务失败: %w", err)
+	}
+
+	s.cron.Start()
+	s.started = true
+	slog.Info("数据新鲜度检查调度器启动成功", "schedule", s.schedule)
+	return nil
+}
+
+// Stop 停止定时检查任务
+func (s *FreshnessService) Stop() {
+	if !s.started {
+		return
+	}
+	s.cancel()
+	s.cron.Stop()
+	s.started = false
+}
+
+// check 检查一批接口的数据新鲜度并写回结果
+func (s *FreshnessService) check(ctx context.Context, interfaces []models.DataInterface) (*FreshnessCheckResult, error) {
+	result := &FreshnessCheckResult{}
+	if len(interfaces) == 0 {
+		return result, nil
+	}
+
+	ids := make([]string, len(interfaces))
+	for i, iface := range interfaces {
+		ids[i] = iface.ID
+	}
+
+	now := time.Now()
+	cadences, err := s.scheduleCadences(ctx, ids, now)
+	if err != nil {
+		return nil, err
+	}
+
+	// 维护中的数据源，接口过期不告警
+	var maintenanceIDs []string
+	if err := s.db.WithContext(ctx).Model(&models.DataSource{}).Where("maintenance_mode = ?", true).
+		Pluck("id", &maintenanceIDs).Error; err != nil {
+		return nil, fmt.Errorf("查询维护中的数据源失败: %w", err)
+	}
+	inMaintenance := make(map[string]bool, len(maintenanceIDs))
+	for _, id := range maintenanceIDs {
+		inMaintenance[id] = true
+	}
+
+	var existing []models.InterfaceFreshness
+	if err := s.db.WithContext(ctx).Where("interface_id IN ?", ids).Find(&existing).Error; err != nil {
+		return nil, fmt.Errorf("查询数据新鲜度记录失败: %w", err)
+	}
+	records := make(map[string]models.InterfaceFreshness, len(existing))
+	for _, record := range existing {
+		records[record.InterfaceID] = record
+	}
+
+	for i := range interfaces {
+		iface := &interfaces[i]
+		record, ok := records[iface.ID]
+		if !ok {
+			record = models.InterfaceFreshness{InterfaceID: iface.ID, Status: models.FreshnessStatusUnknown}
+		}
+		previous := record.Status
+		evaluateFreshness(&record, iface.CreatedAt, cadences[iface.ID], now)
+
+		switch {
+		case record.Status == models.FreshnessStatusStale && previous != models.FreshnessStatusStale:
+			record.StaleSince = &now
+			result.NewlyStale++
+			if inMaintenance[iface.DataSourceID] {
+				slog.DebugContext(ctx, "数据源维护中，接口过期不告警", "interface_id", iface.ID, "datasource_id", iface.DataSourceID)
+				break
+			}
+			record.LastAlertedAt = &now
+			recordFreshnessEvent(ctx, iface, &record, now)
+		case record.Status != models.FreshnessStatusStale && previous == models.FreshnessStatusStale:
+			record.StaleSince = nil
+			if record.Status == models.FreshnessStatusFresh {
+				result.Recovered++
+				recordFreshnessEvent(ctx, iface, &record, now)
+			}
+		}
+
+		record.LastCheckedAt = &now
+		record.UpdatedAt = now
+		if record.UpdatedBy == "" {
+			record.UpdatedBy = "system"
+		}
+		err := s.db.WithContext(ctx).Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "interface_id"}},
+			DoUpdates: clause.AssignmentColumns(freshnessCheckColumns),
+		}).Create(&record).Error
+		if err != nil {
+			return nil, fmt.Errorf("保存接口%s的数据新鲜度失败: %w", iface.ID, err)
+		}
+
+		result.Checked++
+		switch record.Status {
+		case models.FreshnessStatusFresh:
+			result.Fresh++
+		case models.FreshnessStatusStale:
+			result.Stale++
+		default:
+			result.Unknown++
+		}
+	}
+	return result, nil
+}
+
+// scheduleCadences 按接口所属的激活周期任务计算预期同步周期，同一接口属于多个任务时取最短周期
+func (s *FreshnessService) scheduleCadences(ctx context.Context, interfaceIDs []string, now time.Time) (map[string]time.Duration, error) {
+	var rows []struct {
+		InterfaceID     string
+		TriggerType     string
+		CronExpression  string
+		IntervalSeconds int
+	}
+	err := s.db.WithContext(ctx).Table("sync_task_interfaces").
+		Select("sync_task_interfaces.interface_id, sync_tasks.trigger_type, sync_tasks.cron_expression, sync_tasks.interval_seconds").
+		Joins("JOIN sync_tasks ON sync_tasks.id = sync_task_interfaces.task_id").
+		Where("sync_task_interfaces.interface_id IN ?", interfaceIDs).
+		Where("sync_tasks.library_type = ? AND sync_tasks.status = ?", meta.LibraryTypeBasic, meta.SyncTaskStatusActive).
+		Where("sync_tasks.trigger_type IN ?", []string{meta.SyncTaskTriggerInterval, meta.SyncTaskTriggerCron}).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("查询同步任务调度失败: %w", err)
+	}
+
+	cadences := make(map[string]time.Duration)
+	for _, row := range rows {
+		cadence := scheduleCadence(row.TriggerType, row.CronExpression, row.IntervalSeconds, now)
+		if cadence <= 0 {
+			continue
+		}
+		if current, ok := cadences[row.InterfaceID]; !ok || cadence < current {
+			cadences[row.InterfaceID] = cadence
+		}
+	}
+	return cadences, nil
+}
+
+// scheduleCadence 计算单个任务的调度周期，cron任务取未来若干次触发的最大间隔，无法计算时返回0
+func scheduleCadence(triggerType, cronExpression string, intervalSeconds int, now time.Time) time.Duration {
+	switch triggerType {
+	case meta.SyncTaskTriggerInterval:
+		return time.Duration(intervalSeconds) * time.Second
+	case meta.SyncTaskTriggerCron:
+		parser := cron.NewParser(cron.SecondOptional | cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow | cron.Descriptor)
+		schedule, err := parser.Parse(cronExpression)
+		if err != nil {
+			return 0
+		}
+		var cadence time.Duration
+		previous := schedule.Next(now)
+		for i := 0; i < cronCadenceSamples && !previous.IsZero(); i++ {
+			next := schedule.Next(previous)
+			if next.IsZero() {
+				break
+			}
+			if gap := next.Sub(previous); gap > cadence {
+				cadence = gap
+			}
+			previous = next
+		}
+		return cadence
+	}
+	return 0
+}
+
+// evaluateFreshness 按SLA或调度周期计算接口的新鲜度状态，从未同步成功时以接口创建时间为起点
+func evaluateFreshness(record *models.InterfaceFreshness, createdAt time.Time, cadence time.Duration, now time.Time) {
+	var threshold time.Duration
+	switch {
+	case record.SLASeconds > 0:
+		record.ExpectationSource = models.FreshnessSourceSLA
+		record.ExpectedIntervalSeconds = record.SLASeconds
+		threshold = time.Duration(record.SLASeconds) * time.Second
+	case cadence > 0:
+		record.ExpectationSource = models.FreshnessSourceSchedule
+		record.ExpectedIntervalSeconds = int(cadence / time.Second)
+		threshold = cadence * freshnessGraceFactor
+	default:
+		record.ExpectationSource = models.FreshnessSourceNone
+		record.ExpectedIntervalSeconds = 0
+		record.Status = models.FreshnessStatusUnknown
+		return
+	}
+
+	reference := createdAt
+	if record.LastSuccessAt != nil {
+		reference = *record.LastSuccessAt
+	}
+	if now.Sub(reference) > threshold {
+		record.Status = models.FreshnessStatusStale
+	} else {
+		record.Status = models.FreshnessStatusFresh
+	}
+}
+
+// recordFreshnessEvent 记录接口数据过期或恢复的应用事件，过期事件以警告级别记录并由通知中心推送
+func recordFreshnessEvent(ctx context.Context, iface *models.DataInterface, record *models.InterfaceFreshness, now time.Time) {
+	event := eventlog.Event{
+		Type:       eventlog.EventInterfaceFresh,
+		Message:    "接口数据恢复新鲜",
+		ObjectType: "data_interface",
+		ObjectID:   iface.ID,
+		Attributes: map[string]interface{}{
+			"library_id":                iface.LibraryID,
+			"expected_interval_seconds": record.ExpectedIntervalSeconds,
+			"expectation_source":        record.ExpectationSource,
+		},
+	}
+	if record.LastSuccessAt != nil {
+		event.Attributes["last_success_at"] = record.LastSuccessAt.Format(time.RFC3339)
+	}
+	if record.Status == models.FreshnessStatusStale {
+		event.Type = eventlog.EventInterfaceStale
+		event.Level = models.EventLevelWarn
+		event.Message = "接口数据已过期"
+		if age := freshnessAge(record.LastSuccessAt, now); age != nil {
+			event.Attributes["age_seconds"] = *age
+		}
+		slog.WarnContext(ctx, "接口数据已过期", "interface_id", iface.ID, "expected_interval_seconds", record.ExpectedIntervalSeconds, "last_success_at", record.LastSuccessAt)
+	}
+	eventlog.Record(ctx, event)
+}
+
+// recordInterfaceSynced 记录接口最近一次成功同步的时间，已过期的接口立即恢复为fresh
+func recordInterfaceSynced(ctx context.Context, db *gorm.DB, interfaceID string, at time.Time) {
+	var record models.InterfaceFreshness
+	err := db.WithContext(ctx).First(&record, "interface_id = ?", interfaceID).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		slog.Warn("查询数据新鲜度记录失败", "interface_id", interfaceID, "error", err)
+		return
+	}
+
+	recovered := record.Status == models.FreshnessStatusStale
+	record.InterfaceID = interfaceID
+	record.LastSuccessAt = &at
+	record.UpdatedAt = time.Now()
+	switch {
+	case recovered || record.ExpectationSource == models.FreshnessSourceSLA || record.ExpectationSource == models.FreshnessSourceSchedule:
+		record.Status = models.FreshnessStatusFresh
+		record.StaleSince = nil
+	case record.Status == "":
+		record.Status = models.FreshnessStatusUnknown
+		record.ExpectationSource = models.FreshnessSourceNone
+		record.UpdatedBy = "system"
+	}
+
+	err = db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "interface_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"last_success_at", "status", "stale_since", "updated_at"}),
+	}).Create(&record).Error
+	if err != nil {
+		slog.Warn("记录接口最近成功同步时间失败", "interface_id", interfaceID, "error", err)
+		return
+	}
+
+	if recovered {
+		iface := models.DataInterface{ID: interfaceID}
+		_ = db.WithContext(ctx).Select("id", "library_id").First(&iface, "id = ?", interfaceID).Error
+		recordFreshnessEvent(ctx, &iface, &record, at)
+	}
+}
+
+// freshnessAge 计算距最近一次成功同步的秒数
+func freshnessAge(lastSuccessAt *time.Time, now time.Time) *int64 {
+	if lastSuccessAt == nil {
+		return nil
+	}
+	age := int64(now.Sub(*lastSuccessAt) / time.Second)
+	return &age
+}
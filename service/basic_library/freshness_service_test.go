@@ -0,0 +1,139 @@
+/*
+ * @module service/basic_library/freshness_service_test
+ * @description 接口数据新鲜度监控测试，覆盖按调度推导预期周期、SLA优先、进入过期时告警一次、同步成功后恢复以及cron任务周期计算
+ * @architecture 测试层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow 准备sqlite中的接口和同步任务 -> 检查新鲜度/记录同步成功 -> 验证状态和应用事件
+ * @rules 使用内存sqlite，不依赖外部服务
+ * @dependencies gorm.io/driver/sqlite, stretchr/testify
+ * @refs freshness_service.go
+ */
+
+package basic_library
+
+import (
+	"context"
+	"datahub-service/service/eventlog"
+	"datahub-service/service/models"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// setupFreshnessDB 准备一个每小时执行一次的激活任务，并把应用事件写入测试库
+func setupFreshnessDB(t *testing.T) *gorm.DB {
+	db, task := setupDiagnosticsDB(t)
+	require.NoError(t, db.AutoMigrate(&models.BasicLibrary{}, &models.CleansingRule{}, &models.InterfaceFreshness{}))
+	require.NoError(t, db.Create(&models.BasicLibrary{ID: "lib-1", NameZh: "交通基础库", NameEn: "traffic"}).Error)
+	require.NoError(t, db.Model(&models.SyncTask{}).Where("id = ?", task.ID).
+		Updates(map[string]interface{}{"status": "active", "trigger_type": "interval", "interval_seconds": 3600}).Error)
+
+	eventlog.SetDefault(eventlog.NewService(db))
+	t.Cleanup(func() { eventlog.SetDefault(nil) })
+	return db
+}
+
+func countEvents(t *testing.T, db *gorm.DB, eventType string) int64 {
+	var count int64
+	require.NoError(t, db.Model(&models.ApplicationEvent{}).Where("event_type = ? AND object_id = ?", eventType, "if-1").Count(&count).Error)
+	return count
+}
+
+func TestFreshnessStaleAndRecover(t *testing.T) {
+	db := setupFreshnessDB(t)
+	s := NewFreshnessService(db)
+	ctx := context.Background()
+
+	lastSuccess := time.Now().Add(-3 * time.Hour)
+	recordInterfaceSynced(ctx, db, "if-1", lastSuccess)
+
+	result, err := s.CheckAll(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Stale)
+	assert.Equal(t, 1, result.NewlyStale)
+
+	view, err := s.GetFreshness(ctx, "if-1")
+	require.NoError(t, err)
+	assert.True(t, view.IsStale())
+	assert.Equal(t, models.FreshnessSourceSchedule, view.ExpectationSource)
+	assert.Equal(t, 3600, view.ExpectedIntervalSeconds)
+	assert.Equal(t, "交通基础库", view.LibraryName)
+	require.NotNil(t, view.StaleSince)
+	require.NotNil(t, view.AgeSeconds)
+	assert.GreaterOrEqual(t, *view.AgeSeconds, int64(3*3600-1))
+
+	// 持续过期不重复告警
+	result, err = s.CheckAll(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 0, result.NewlyStale)
+	assert.Equal(t, int64(1), countEvents(t, db, eventlog.EventInterfaceStale))
+
+	// 列表中过期接口带过期标记
+	interfaces, _, err := NewService(db, nil).GetDataInterfaceList(ctx, 1, 10, "", "", "", "", "")
+	require.NoError(t, err)
+	require.Len(t, interfaces, 1)
+	require.NotNil(t, interfaces[0].Freshness)
+	assert.True(t, interfaces[0].Freshness.IsStale())
+
+	// 同步成功后立即恢复
+	recordInterfaceSynced(ctx, db, "if-1", time.Now())
+	view, err = s.GetFreshness(ctx, "if-1")
+	require.NoError(t, err)
+	assert.Equal(t, models.FreshnessStatusFresh, view.Status)
+	assert.Nil(t, view.StaleSince)
+	assert.Equal(t, int64(1), countEvents(t, db, eventlog.EventInterfaceFresh))
+
+	views, total, err := s.GetFreshnessList(ctx, 1, 10, models.FreshnessStatusFresh, "lib-1")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	assert.Equal(t, "车辆进出", views[0].InterfaceName)
+}
+
+func TestFreshnessSLAOverridesSchedule(t *testing.T) {
+	db := setupFreshnessDB(t)
+	s := NewFreshnessService(db)
+	ctx := context.Background()
+
+	// 按调度推导允许2小时未同步，配置1小时SLA后即过期
+	recordInterfaceSynced(ctx, db, "if-1", time.Now().Add(-90*time.Minute))
+	result, err := s.CheckAll(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Fresh)
+
+	view, err := s.UpdateSLA(ctx, "if-1", 3600, "admin")
+	require.NoError(t, err)
+	assert.Equal(t, models.FreshnessSourceSLA, view.ExpectationSource)
+	assert.Equal(t, models.FreshnessStatusStale, view.Status)
+	assert.Equal(t, "admin", view.UpdatedBy)
+	require.NotNil(t, view.LastSuccessAt, "配置SLA不覆盖最近成功时间")
+
+	_, err = s.UpdateSLA(ctx, "if-1", -1, "admin")
+	assert.ErrorIs(t, err, ErrInvalidFreshnessSLA)
+	_, err = s.UpdateSLA(ctx, "missing", 60, "admin")
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+}
+
+func TestFreshnessUnknownWithoutSchedule(t *testing.T) {
+	db := setupFreshnessDB(t)
+	require.NoError(t, db.Model(&models.SyncTask{}).Where("id = ?", "task-1").Update("trigger_type", "manual").Error)
+
+	view, err := NewFreshnessService(db).CheckInterface(context.Background(), "if-1")
+	require.NoError(t, err)
+	assert.Equal(t, models.FreshnessStatusUnknown, view.Status)
+	assert.Equal(t, models.FreshnessSourceNone, view.ExpectationSource)
+	assert.Nil(t, view.LastSuccessAt)
+}
+
+func TestScheduleCadence(t *testing.T) {
+	now := time.Date(2024, 5, 1, 10, 0, 0, 0, time.Local)
+	assert.Equal(t, 30*time.Minute, scheduleCadence("interval", "", 1800, now))
+	assert.Equal(t, time.Hour, scheduleCadence("cron", "0 0 * * * *", 0, now))
+	assert.Equal(t, 24*time.Hour, scheduleCadence("cron", "0 2 * * *", 0, now))
+	// 工作日任务取周末的最大间隔，避免周一误报
+	assert.Equal(t, 72*time.Hour, scheduleCadence("cron", "0 0 9 * * 1-5", 0, now))
+	assert.Zero(t, scheduleCadence("cron", "invalid", 0, now))
+	assert.Zero(t, scheduleCadence("manual", "", 0, now))
+}
@@ -0,0 +1,752 @@
+/*
+ * @module service/basic_library/golden_dataset_service
+ * @description 接口基准数据集服务，保存上传或取自快照的基准数据，把接口表与基准数据比较表结构和容差内的取值，修改接口配置后作为回归测试
+ * @architecture 分层架构 - 业务服务层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow 上传基准行或选择快照 -> 校验键字段并按字段配置记录列类型 -> 保存数据集和基准行；
+ *            比较：对照列和类型 -> 逐行扫描接口表按键匹配基准行 -> 统计缺失、多出和取值超出容差的行 -> 保存比较记录 -> 未通过时记录事件
+ * @rules 基准数据集必须有键字段，未指定时取接口主键；最多goldenMaxRows行；键值按文本匹配(整数形式的小数按整数、时间按UTC RFC3339)；
+ *        数值按绝对误差或相对误差比较，满足其一即一致，列误差覆盖数据集误差；时间按时刻比较，其他取值按文本比较；
+ *        缺少基准列或字段类型变化算失败，接口表多出的列只提示；auto_compare开启时接口同步成功后自动比较，比较失败不影响同步结果
+ * @dependencies datahub-service/service/models, datahub-service/service/eventlog, datahub-service/service/tenant, gorm.io/gorm
+ * @refs service/models/interface_golden.go, snapshot_service.go, sync_task_service.go, api/controllers/golden_dataset_controller.go
+ */
+
+package basic_library
+
+import (
+	"context"
+	"datahub-service/service/eventlog"
+	"datahub-service/service/models"
+	"datahub-service/service/utils"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cast"
+	"gorm.io/gorm"
+)
+
+const (
+	goldenMaxRows         = 10000 // 基准数据集最多行数
+	goldenSampleLimit     = 100   // 比较结果中差异样例和缺失键样例的个数
+	goldenRunListLimit    = 50    // 比较记录列表返回的条数
+	goldenRowBatchSize    = 500   // 保存基准行时每批条数
+	defaultGoldenPageSize = 100   // 基准行默认每页行数
+	maxGoldenPageSize     = 1000  // 基准行每页最多行数
+)
+
+// ErrInvalidGoldenDataset 基准数据集不合法
+var ErrInvalidGoldenDataset = errors.New("基准数据集不合法")
+
+// GoldenDatasetSettings 基准数据集的比较设置，创建和修改共用
+type GoldenDatasetSettings struct {
+	Name             string
+	Description      string
+	MatchMode        string             // subset/exact，为空时为subset
+	AbsTolerance     float64            // 数值允许的绝对误差
+	RelTolerance     float64            // 数值允许的相对误差
+	ColumnTolerances map[string]float64 // 按列覆盖的绝对误差
+	IgnoreColumns    []string           // 不比较取值的列
+	AutoCompare      bool               // 接口同步成功后自动比较
+}
+
+// GoldenDatasetInput 创建基准数据集的参数，Rows和SnapshotID二选一
+type GoldenDatasetInput struct {
+	GoldenDatasetSettings
+	KeyFields  []string                 // 匹配行的键字段，为空时取接口主键
+	Rows       []map[string]interface{} // 上传的基准行
+	SnapshotID string                   // 取该接口快照中的数据作为基准
+}
+
+// GoldenDatasetRows 基准数据集中的一页基准行
+type GoldenDatasetRows struct {
+	Dataset models.InterfaceGoldenDataset `json:"dataset"`
+	Page    int                           `json:"page"`
+	Size    int                           `json:"size"`
+	Rows    []models.JSONB                `json:"rows"`
+}
+
+// GoldenDatasetService 接口基准数据集服务
+type GoldenDatasetService struct {
+	db *gorm.DB
+}
+
+// NewGoldenDatasetService 创建接口基准数据集服务实例
+func NewGoldenDatasetService(db *gorm.DB) *GoldenDatasetService {
+	return &GoldenDatasetService{db: db}
+}
+
+// CreateDataset 创建基准数据集，基准行来自上传的数据或接口的快照
+func (s *GoldenDatasetService) CreateDataset(ctx context.Context, interfaceID string, input *GoldenDatasetInput, username string) (*models.InterfaceGoldenDataset, error) {
+	iface, err := getTenantInterface(ctx, s.db, interfaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	dataset := &models.InterfaceGoldenDataset{
+		InterfaceID: interfaceID,
+		Source:      models.GoldenSourceUpload,
+		CreatedBy:   username,
+		UpdatedBy:   username,
+	}
+	rows := input.Rows
+	switch {
+	case input.SnapshotID != "" && len(rows) > 0:
+		return nil, fmt.Errorf("%w: 上传的数据和快照只能选择一个", ErrInvalidGoldenDataset)
+	case input.SnapshotID != "":
+		if rows, err = s.readSnapshotRows(ctx, interfaceID, input.SnapshotID); err != nil {
+			return nil, err
+		}
+		dataset.Source = models.GoldenSourceSnapshot
+		dataset.SnapshotID = input.SnapshotID
+	}
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("%w: 基准数据为空", ErrInvalidGoldenDataset)
+	}
+	if len(rows) > goldenMaxRows {
+		return nil, fmt.Errorf("%w: 基准数据最多%d行", ErrInvalidGoldenDataset, goldenMaxRows)
+	}
+
+	keys := trimFields(input.KeyFields)
+	if len(keys) == 0 {
+		keys = snapshotKeyFields(iface.TableFieldsConfig)
+	}
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("%w: 接口没有主键，须指定键字段", ErrInvalidGoldenDataset)
+	}
+	seen := make(map[string]int, len(rows))
+	for i, row := range rows {
+		key, err := goldenRowKey(row, keys)
+		if err != nil {
+			return nil, fmt.Errorf("%w: 第%d行%v", ErrInvalidGoldenDataset, i+1, err)
+		}
+		if previous, ok := seen[key]; ok {
+			return nil, fmt.Errorf("%w: 第%d行与第%d行的键重复", ErrInvalidGoldenDataset, i+1, previous+1)
+		}
+		seen[key] = i
+	}
+
+	dataset.KeyFields = keys
+	dataset.Columns = goldenColumns(iface.TableFieldsConfig, rows)
+	dataset.RowCount = len(rows)
+	if err := applyGoldenSettings(dataset, &input.GoldenDatasetSettings); err != nil {
+		return nil, err
+	}
+
+	goldenRows := make([]models.InterfaceGoldenRow, len(rows))
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(dataset).Error; err != nil {
+			return fmt.Errorf("保存基准数据集失败: %w", err)
+		}
+		for i, row := range rows {
+			goldenRows[i] = models.InterfaceGoldenRow{DatasetID: dataset.ID, RowIndex: i, Data: goldenRowData(row)}
+		}
+		if err := tx.CreateInBatches(goldenRows, goldenRowBatchSize).Error; err != nil {
+			return fmt.Errorf("保存基准行失败: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	slog.Info("基准数据集已创建", "interface_id", interfaceID, "dataset_id", dataset.ID, "source", dataset.Source, "rows", dataset.RowCount)
+	return dataset, nil
+}
+
+// UpdateDataset 修改基准数据集的名称和比较设置，基准行不变
+func (s *GoldenDatasetService) UpdateDataset(ctx context.Context, interfaceID, datasetID string, settings *GoldenDatasetSettings, username string) (*models.InterfaceGoldenDataset, error) {
+	dataset, err := s.GetDataset(ctx, interfaceID, datasetID)
+	if err != nil {
+		return nil, err
+	}
+	if err := applyGoldenSettings(dataset, settings); err != nil {
+		return nil, err
+	}
+	dataset.UpdatedBy = username
+	if err := s.db.WithContext(ctx).Save(dataset).Error; err != nil {
+		return nil, fmt.Errorf("保存基准数据集失败: %w", err)
+	}
+	return dataset, nil
+}
+
+// ListDatasets 获取接口的基准数据集
+func (s *GoldenDatasetService) ListDatasets(ctx context.Context, interfaceID string) ([]models.InterfaceGoldenDataset, error) {
+	if _, err := getTenantInterface(ctx, s.db, interfaceID); err != nil {
+		return nil, err
+	}
+	datasets := make([]models.InterfaceGoldenDataset, 0)
+	if err := s.db.WithContext(ctx).Where("interface_id = ?", interfaceID).Order("created_at DESC").Find(&datasets).Error; err != nil {
+		return nil, fmt.Errorf("查询基准数据集失败: %w", err)
+	}
+	return datasets, nil
+}
+
+// GetDataset 获取接口的基准数据集
+func (s *GoldenDatasetService) GetDataset(ctx context.Context, interfaceID, datasetID string) (*models.InterfaceGoldenDataset, error) {
+	if _, err := getTenantInterface(ctx, s.db, interfaceID); err != nil {
+		return nil, err
+	}
+	var dataset models.InterfaceGoldenDataset
+	if err := s.db.WithContext(ctx).First(&dataset, "id = ? AND interface_id = ?", datasetID, interfaceID).Error; err != nil {
+		return nil, err
+	}
+	return &dataset, nil
+}
+
+// GetRows 分页查询基准行
+func (s *GoldenDatasetService) GetRows(ctx context.Context, interfaceID, datasetID string, page, size int) (*GoldenDatasetRows, error) {
+	dataset, err := s.GetDataset(ctx, interfaceID, datasetID)
+	if err != nil {
+		return nil, err
+	}
+	if page <= 0 {
+		page = 1
+	}
+	if size <= 0 {
+		size = defaultGoldenPageSize
+	}
+	if size > maxGoldenPageSize {
+		size = maxGoldenPageSize
+	}
+
+	var goldenRows []models.InterfaceGoldenRow
+	if err := s.db.WithContext(ctx).Where("dataset_id = ?", datasetID).Order("row_index").
+		Limit(size).Offset((page - 1) * size).Find(&goldenRows).Error; err != nil {
+		return nil, fmt.Errorf("查询基准行失败: %w", err)
+	}
+	rows := make([]models.JSONB, len(goldenRows))
+	for i := range goldenRows {
+		rows[i] = goldenRows[i].Data
+	}
+	return &GoldenDatasetRows{Dataset: *dataset, Page: page, Size: size, Rows: rows}, nil
+}
+
+// DeleteDataset 删除基准数据集及其基准行和比较记录
+func (s *GoldenDatasetService) DeleteDataset(ctx context.Context, interfaceID, datasetID string) error {
+	if _, err := s.GetDataset(ctx, interfaceID, datasetID); err != nil {
+		return err
+	}
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("dataset_id = ?", datasetID).Delete(&models.InterfaceGoldenRow{}).Error; err != nil {
+			return fmt.Errorf("删除基准行失败: %w", err)
+		}
+		if err := tx.Where("dataset_id = ?", datasetID).Delete(&models.InterfaceGoldenRun{}).Error; err != nil {
+			return fmt.Errorf("删除比较记录失败: %w", err)
+		}
+		return tx.Delete(&models.InterfaceGoldenDataset{}, "id = ?", datasetID).Error
+	})
+}
+
+// RunComparison 立即把接口表与基准数据集比较并保存比较记录
+func (s *GoldenDatasetService) RunComparison(ctx context.Context, interfaceID, datasetID, username string) (*models.InterfaceGoldenRun, error) {
+	iface, err := getTenantInterface(ctx, s.db, interfaceID)
+	if err != nil {
+		return nil, err
+	}
+	dataset, err := s.GetDataset(ctx, interfaceID, datasetID)
+	if err != nil {
+		return nil, err
+	}
+	run := &models.InterfaceGoldenRun{Trigger: models.GoldenTriggerManual, CreatedBy: username}
+	if err := runGoldenComparison(ctx, s.db, iface, dataset, run); err != nil {
+		return nil, err
+	}
+	return run, nil
+}
+
+// ListRuns 按时间倒序获取基准数据集最近的比较记录
+func (s *GoldenDatasetService) ListRuns(ctx context.Context, interfaceID, datasetID string) ([]models.InterfaceGoldenRun, error) {
+	if _, err := s.GetDataset(ctx, interfaceID, datasetID); err != nil {
+		return nil, err
+	}
+	runs := make([]models.InterfaceGoldenRun, 0)
+	if err := s.db.WithContext(ctx).Where("dataset_id = ?", datasetID).
+		Order("started_at DESC").Limit(goldenRunListLimit).Find(&runs).Error; err != nil {
+		return nil, fmt.Errorf("查询比较记录失败: %w", err)
+	}
+	return runs, nil
+}
+
+// GetRun 获取一次比较的结果
+func (s *GoldenDatasetService) GetRun(ctx context.Context, interfaceID, datasetID, runID string) (*models.InterfaceGoldenRun, error) {
+	if _, err := s.GetDataset(ctx, interfaceID, datasetID); err != nil {
+		return nil, err
+	}
+	var run models.InterfaceGoldenRun
+	if err := s.db.WithContext(ctx).First(&run, "id = ? AND dataset_id = ?", runID, datasetID).Error; err != nil {
+		return nil, err
+	}
+	return &run, nil
+}
+
+// readSnapshotRows 读取接口快照中的全部行作为基准数据
+func (s *GoldenDatasetService) readSnapshotRows(ctx context.Context, interfaceID, snapshotID string) ([]map[string]interface{}, error) {
+	var snapshot models.InterfaceTableSnapshot
+	if err := s.db.WithContext(ctx).First(&snapshot, "id = ? AND interface_id = ?", snapshotID, interfaceID).Error; err != nil {
+		return nil, err
+	}
+	if snapshot.RowCount > goldenMaxRows {
+		return nil, fmt.Errorf("%w: 快照有%d行，基准数据最多%d行", ErrInvalidGoldenDataset, snapshot.RowCount, goldenMaxRows)
+	}
+	rows := make([]map[string]interface{}, 0, snapshot.RowCount)
+	query := fmt.Sprintf("SELECT * FROM %s LIMIT %d", snapshotTableRef(snapshot.SnapshotTable), goldenMaxRows+1)
+	if err := s.db.WithContext(ctx).Raw(query).Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("读取快照数据失败: %w", err)
+	}
+	return rows, nil
+}
+
+// recordGoldenComparisons 接口同步成功后比较开启了auto_compare的基准数据集，返回最差的比较结果，没有需要比较的数据集时返回空
+func recordGoldenComparisons(ctx context.Context, db *gorm.DB, taskID, executionID, interfaceID string) string {
+	var datasets []models.InterfaceGoldenDataset
+	if err := db.WithContext(ctx).Where("interface_id = ? AND auto_compare = ?", interfaceID, true).Find(&datasets).Error; err != nil {
+		slog.Warn("查询基准数据集失败", "interface_id", interfaceID, "error", err)
+		return ""
+	}
+	if len(datasets) == 0 {
+		return ""
+	}
+
+	var iface models.DataInterface
+	if err := db.WithContext(ctx).Preload("BasicLibrary").First(&iface, "id = ?", interfaceID).Error; err != nil {
+		slog.Warn("查询基准数据集接口失败", "interface_id", interfaceID, "error", err)
+		return ""
+	}
+	status := models.GoldenRunPassed
+	for i := range datasets {
+		run := &models.InterfaceGoldenRun{Trigger: models.GoldenTriggerSync, TaskID: taskID, ExecutionID: executionID, CreatedBy: "system"}
+		if err := runGoldenComparison(ctx, db, &iface, &datasets[i], run); err != nil {
+			slog.Warn("同步后比较基准数据集失败", "dataset_id", datasets[i].ID, "execution_id", executionID, "error", err)
+			continue
+		}
+		if run.Status != models.GoldenRunPassed {
+			status = models.GoldenRunFailed
+		}
+	}
+	return status
+}
+
+// runGoldenComparison 比较接口表与基准数据集，保存比较记录并更新数据集的最近结果，未通过时记录事件；
+// 比较过程中的错误记为error状态的比较记录，只有保存失败时返回错误
+func runGoldenComparison(ctx context.Context, db *gorm.DB, iface *models.DataInterface, dataset *models.InterfaceGoldenDataset, run *models.InterfaceGoldenRun) error {
+	run.DatasetID = dataset.ID
+	run.InterfaceID = dataset.InterfaceID
+	run.ExpectedRows = dataset.RowCount
+	run.SchemaIssues = models.GoldenSchemaIssues{}
+	run.ColumnMismatches = models.SyncColumnChanges{}
+	run.Mismatches = models.GoldenMismatches{}
+	run.MissingKeys = models.SyncDiffKeys{}
+	run.StartedAt = time.Now()
+
+	if err := compareGoldenDataset(ctx, db, iface, dataset, run); err != nil {
+		run.Status = models.GoldenRunError
+		run.ErrorMessage = err.Error()
+	}
+	run.FinishedAt = time.Now()
+
+	err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(run).Error; err != nil {
+			return fmt.Errorf("保存比较记录失败: %w", err)
+		}
+		return tx.Model(&models.InterfaceGoldenDataset{}).Where("id = ?", dataset.ID).
+			Updates(map[string]interface{}{"last_status": run.Status, "last_run_at": run.FinishedAt}).Error
+	})
+	if err != nil {
+		return err
+	}
+	dataset.LastStatus = run.Status
+	dataset.LastRunAt = &run.FinishedAt
+
+	slog.Info("基准数据比较完成", "interface_id", dataset.InterfaceID, "dataset_id", dataset.ID, "status", run.Status,
+		"missing", run.MissingRows, "mismatched", run.MismatchedRows, "unexpected", run.UnexpectedRows)
+	if run.Status != models.GoldenRunPassed {
+		recordGoldenComparisonFailed(ctx, dataset, run)
+	}
+	return nil
+}
+
+// compareGoldenDataset 对照表结构，逐行扫描接口表按键匹配基准行并比较取值，结果写入run
+func compareGoldenDataset(ctx context.Context, db *gorm.DB, iface *models.DataInterface, dataset *models.InterfaceGoldenDataset, run *models.InterfaceGoldenRun) error {
+	if !iface.IsTableCreated {
+		return errors.New("接口表尚未创建")
+	}
+	schema := iface.BasicLibrary.GetSchemaName()
+	if schema == "" || iface.NameEn == "" {
+		return errors.New("接口所属基础库或接口英文名为空")
+	}
+	table := utils.QuoteTable(schema, iface.NameEn)
+	tx := db.WithContext(ctx)
+
+	columns, err := tableColumns(tx, table)
+	if err != nil {
+		return err
+	}
+	schemaFailed := compareGoldenSchema(dataset.Columns, columns, iface.TableFieldsConfig, run)
+	if !containsAll(columns, dataset.KeyFields) {
+		run.Status = models.GoldenRunFailed
+		run.MissingRows = dataset.RowCount
+		return nil
+	}
+
+	var goldenRows []models.InterfaceGoldenRow
+	if err := tx.Where("dataset_id = ?", dataset.ID).Order("row_index").Find(&goldenRows).Error; err != nil {
+		return fmt.Errorf("读取基准行失败: %w", err)
+	}
+	expected := make(map[string]models.JSONB, len(goldenRows))
+	order := make([]string, 0, len(goldenRows))
+	for _, row := range goldenRows {
+		key, err := goldenRowKey(row.Data, dataset.KeyFields)
+		if err != nil {
+			return fmt.Errorf("基准行%d%v", row.RowIndex+1, err)
+		}
+		expected[key] = row.Data
+		order = append(order, key)
+	}
+
+	ignored := make(map[string]bool, len(dataset.KeyFields)+len(dataset.IgnoreColumns))
+	for _, column := range append(append([]string{}, dataset.KeyFields...), dataset.IgnoreColumns...) {
+		ignored[column] = true
+	}
+	var compared []string
+	for _, column := range dataset.Columns {
+		if !ignored[column.Name] && containsAll(columns, []string{column.Name}) {
+			compared = append(compared, column.Name)
+		}
+	}
+
+	rows, err := tx.Raw("SELECT * FROM " + table).Rows()
+	if err != nil {
+		return fmt.Errorf("读取接口表失败: %w", err)
+	}
+	defer rows.Close()
+
+	matched := make(map[string]bool, len(expected))
+	columnCounts := make(map[string]int64)
+	for rows.Next() {
+		actual := make(map[string]interface{})
+		if err := tx.ScanRows(rows, &actual); err != nil {
+			return fmt.Errorf("读取接口表失败: %w", err)
+		}
+		run.ActualRows++
+		key, err := goldenRowKey(actual, dataset.KeyFields)
+		want, ok := expected[key]
+		if err != nil || !ok || matched[key] {
+			run.UnexpectedRows++
+			continue
+		}
+		matched[key] = true
+
+		mismatched := false
+		for _, column := range compared {
+			if goldenValuesEqual(want[column], actual[column], dataset.ToleranceFor(column), dataset.RelTolerance) {
+				continue
+			}
+			mismatched = true
+			columnCounts[column]++
+			if len(run.Mismatches) < goldenSampleLimit {
+				run.Mismatches = append(run.Mismatches, models.GoldenMismatch{
+					Key:      goldenKeyValues(want, dataset.KeyFields),
+					Column:   column,
+					Expected: want[column],
+					Actual:   goldenSampleValue(actual[column]),
+				})
+			}
+		}
+		if mismatched {
+			run.MismatchedRows++
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("读取接口表失败: %w", err)
+	}
+
+	run.MatchedRows = len(matched)
+	for _, key := range order {
+		if matched[key] {
+			continue
+		}
+		run.MissingRows++
+		if len(run.MissingKeys) < goldenSampleLimit {
+			run.MissingKeys = append(run.MissingKeys, goldenKeyValues(expected[key], dataset.KeyFields))
+		}
+	}
+	for _, column := range compared {
+		if count := columnCounts[column]; count > 0 {
+			run.ColumnMismatches = append(run.ColumnMismatches, models.SyncColumnChange{Column: column, Changed: count})
+		}
+	}
+	sort.SliceStable(run.ColumnMismatches, func(i, j int) bool {
+		return run.ColumnMismatches[i].Changed > run.ColumnMismatches[j].Changed
+	})
+	if dataset.MatchMode != models.GoldenMatchExact {
+		run.UnexpectedRows = 0
+	}
+
+	run.Status = models.GoldenRunPassed
+	if schemaFailed || run.MissingRows > 0 || run.MismatchedRows > 0 || run.UnexpectedRows > 0 {
+		run.Status = models.GoldenRunFailed
+	}
+	return nil
+}
+
+// compareGoldenSchema 对照基准数据集的列和接口表，返回是否有导致失败的差异(缺少列或字段类型变化)
+func compareGoldenSchema(goldenColumns models.GoldenColumns, columns []string, tableFieldsConfig models.JSONB, run *models.InterfaceGoldenRun) bool {
+	fieldTypes := make(map[string]string)
+	for _, field := range sortedTableFields(tableFieldsConfig) {
+		fieldTypes[field.NameEn] = field.DataType
+	}
+	known := make(map[string]bool, len(goldenColumns))
+	failed := false
+	for _, column := range goldenColumns {
+		known[column.Name] = true
+		if !containsAll(columns, []string{column.Name}) {
+			run.SchemaIssues = append(run.SchemaIssues, models.GoldenSchemaIssue{Column: column.Name, Kind: models.GoldenSchemaMissingColumn})
+			failed = true
+			continue
+		}
+		if actual := fieldTypes[column.Name]; column.Type != "" && actual != "" && !strings.EqualFold(column.Type, actual) {
+			run.SchemaIssues = append(run.SchemaIssues, models.GoldenSchemaIssue{
+				Column: column.Name, Kind: models.GoldenSchemaTypeChanged, Expected: column.Type, Actual: actual,
+			})
+			failed = true
+		}
+	}
+	for _, column := range columns {
+		if !known[column] {
+			run.SchemaIssues = append(run.SchemaIssues, models.GoldenSchemaIssue{Column: column, Kind: models.GoldenSchemaExtraColumn})
+		}
+	}
+	return failed
+}
+
+// recordGoldenComparisonFailed 记录基准数据比较未通过事件
+func recordGoldenComparisonFailed(ctx context.Context, dataset *models.InterfaceGoldenDataset, run *models.InterfaceGoldenRun) {
+	level := models.EventLevelWarn
+	message := "接口数据与基准数据集不一致"
+	if run.Status == models.GoldenRunError {
+		level = models.EventLevelError
+		message = "接口数据无法与基准数据集比较"
+	}
+	failedSchema := 0
+	for _, issue := range run.SchemaIssues {
+		if issue.Kind != models.GoldenSchemaExtraColumn {
+			failedSchema++
+		}
+	}
+	eventlog.Record(ctx, eventlog.Event{
+		Type:       eventlog.EventGoldenComparisonFailed,
+		Level:      level,
+		Message:    message,
+		ObjectType: "data_interface",
+		ObjectID:   dataset.InterfaceID,
+		Attributes: map[string]interface{}{
+			"dataset_id":      dataset.ID,
+			"dataset_name":    dataset.Name,
+			"run_id":          run.ID,
+			"trigger":         run.Trigger,
+			"execution_id":    run.ExecutionID,
+			"status":          run.Status,
+			"missing_rows":    run.MissingRows,
+			"mismatched_rows": run.MismatchedRows,
+			"unexpected_rows": run.UnexpectedRows,
+			"schema_issues":   failedSchema,
+			"error":           run.ErrorMessage,
+		},
+	})
+}
+
+// applyGoldenSettings 校验比较设置并写入数据集
+func applyGoldenSettings(dataset *models.InterfaceGoldenDataset, settings *GoldenDatasetSettings) error {
+	name := strings.TrimSpace(settings.Name)
+	if name == "" || len([]rune(name)) > 100 {
+		return fmt.Errorf("%w: 名称不能为空且不超过100个字符", ErrInvalidGoldenDataset)
+	}
+	matchMode := settings.MatchMode
+	if matchMode == "" {
+		matchMode = models.GoldenMatchSubset
+	}
+	if matchMode != models.GoldenMatchSubset && matchMode != models.GoldenMatchExact {
+		return fmt.Errorf("%w: 不支持的匹配模式%s", ErrInvalidGoldenDataset, matchMode)
+	}
+	if settings.AbsTolerance < 0 || settings.RelTolerance < 0 {
+		return fmt.Errorf("%w: 误差不能为负数", ErrInvalidGoldenDataset)
+	}
+
+	columns := make([]string, len(dataset.Columns))
+	for i, column := range dataset.Columns {
+		columns[i] = column.Name
+	}
+	tolerances := models.JSONB{}
+	for column, tolerance := range settings.ColumnTolerances {
+		if !containsAll(columns, []string{column}) {
+			return fmt.Errorf("%w: 列%s不在基准数据集中", ErrInvalidGoldenDataset, column)
+		}
+		if tolerance < 0 {
+			return fmt.Errorf("%w: 列%s的误差不能为负数", ErrInvalidGoldenDataset, column)
+		}
+		tolerances[column] = tolerance
+	}
+	ignore := trimFields(settings.IgnoreColumns)
+	for _, column := range ignore {
+		if !containsAll(columns, []string{column}) {
+			return fmt.Errorf("%w: 列%s不在基准数据集中", ErrInvalidGoldenDataset, column)
+		}
+		if containsAll(dataset.KeyFields, []string{column}) {
+			return fmt.Errorf("%w: 键字段%s不能忽略", ErrInvalidGoldenDataset, column)
+		}
+	}
+
+	dataset.Name = name
+	dataset.Description = strings.TrimSpace(settings.Description)
+	dataset.MatchMode = matchMode
+	dataset.AbsTolerance = settings.AbsTolerance
+	dataset.RelTolerance = settings.RelTolerance
+	dataset.ColumnTolerances = tolerances
+	dataset.IgnoreColumns = ignore
+	dataset.AutoCompare = settings.AutoCompare
+	return nil
+}
+
+// goldenColumns 基准行中出现的列，按接口字段配置的顺序排列并记录字段类型，不在字段配置中的列按名称排在最后
+func goldenColumns(tableFieldsConfig models.JSONB, rows []map[string]interface{}) models.GoldenColumns {
+	present := make(map[string]bool)
+	for _, row := range rows {
+		for column := range row {
+			present[column] = true
+		}
+	}
+	columns := make(models.GoldenColumns, 0, len(present))
+	for _, field := range sortedTableFields(tableFieldsConfig) {
+		if present[field.NameEn] {
+			columns = append(columns, models.GoldenColumn{Name: field.NameEn, Type: field.DataType})
+			delete(present, field.NameEn)
+		}
+	}
+	rest := make([]string, 0, len(present))
+	for column := range present {
+		rest = append(rest, column)
+	}
+	sort.Strings(rest)
+	for _, column := range rest {
+		columns = append(columns, models.GoldenColumn{Name: column})
+	}
+	return columns
+}
+
+// goldenRowKey 由键字段的值生成匹配用的键，键值为空时返回错误
+func goldenRowKey(row map[string]interface{}, keys []string) (string, error) {
+	parts := make([]string, len(keys))
+	for i, key := range keys {
+		value, ok := row[key]
+		if !ok || value == nil {
+			return "", fmt.Errorf("键字段%s为空", key)
+		}
+		parts[i] = goldenText(value)
+	}
+	return strings.Join(parts, "\x1f"), nil
+}
+
+// goldenKeyValues 取出行中键字段的值，用于差异样例
+func goldenKeyValues(row map[string]interface{}, keys []string) map[string]interface{} {
+	values := make(map[string]interface{}, len(keys))
+	for _, key := range keys {
+		values[key] = row[key]
+	}
+	return values
+}
+
+// goldenRowData 转换为可保存的基准行，二进制值转为文本
+func goldenRowData(row map[string]interface{}) models.JSONB {
+	data := make(models.JSONB, len(row))
+	for column, value := range row {
+		data[column] = goldenSampleValue(value)
+	}
+	return data
+}
+
+// goldenSampleValue 二进制值转为文本，其他值不变
+func goldenSampleValue(value interface{}) interface{} {
+	if bytes, ok := value.([]byte); ok {
+		return string(bytes)
+	}
+	return value
+}
+
+// goldenValuesEqual 判断基准值和接口表中的值是否一致：数值在绝对误差或相对误差内，时间为同一时刻，其他按文本相等
+func goldenValuesEqual(expected, actual interface{}, absTolerance, relTolerance float64) bool {
+	expected, actual = goldenSampleValue(expected), goldenSampleValue(actual)
+	if expected == nil || actual == nil {
+		return expected == nil && actual == nil
+	}
+	if isGoldenNumber(expected) || isGoldenNumber(actual) {
+		want, errWant := cast.ToFloat64E(expected)
+		got, errGot := cast.ToFloat64E(actual)
+		if errWant == nil && errGot == nil {
+			diff := math.Abs(want - got)
+			return diff <= absTolerance || diff <= relTolerance*math.Abs(want)
+		}
+	}
+	if got, ok := actual.(time.Time); ok {
+		if want, err := cast.ToTimeE(expected); err == nil {
+			return want.Equal(got)
+		}
+	}
+	if got, ok := actual.(bool); ok {
+		if want, err := cast.ToBoolE(expected); err == nil {
+			return want == got
+		}
+	}
+	return goldenText(expected) == goldenText(actual)
+}
+
+// isGoldenNumber 是否为数值类型
+func isGoldenNumber(value interface{}) bool {
+	switch value.(type) {
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64, json.Number:
+		return true
+	}
+	return false
+}
+
+// goldenText 值的文本形式，整数形式的小数按整数，时间按UTC RFC3339
+func goldenText(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case []byte:
+		return string(v)
+	case string:
+		return v
+	case time.Time:
+		return v.UTC().Format(time.RFC3339Nano)
+	case float32:
+		return goldenText(float64(v))
+	case float64:
+		if v == math.Trunc(v) && math.Abs(v) < 1e15 {
+			return strconv.FormatInt(int64(v), 10)
+		}
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case map[string]interface{}, []interface{}:
+		data, _ := json.Marshal(v)
+		return string(data)
+	}
+	return cast.ToString(value)
+}
+
+// trimFields 去掉字段名两端空白和空字段名
+func trimFields(fields []string) []string {
+	trimmed := make([]string, 0, len(fields))
+	for _, field := range fields {
+		if field = strings.TrimSpace(field); field != "" {
+			trimmed = append(trimmed, field)
+		}
+	}
+	return trimmed
+}
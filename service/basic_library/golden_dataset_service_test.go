@@ -0,0 +1,187 @@
+/*
+ * @module service/basic_library/golden_dataset_service_test
+ * @description 接口基准数据集服务测试，覆盖上传校验、误差内取值视为一致、取值差异和缺失行、exact模式多出的行、字段类型变化、从快照创建以及同步后自动比较
+ * @architecture 测试层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow 准备sqlite中的基础库schema和接口表 -> 上传基准数据或从快照创建 -> 修改接口表或字段配置 -> 比较 -> 验证比较记录和事件
+ * @rules 使用内存sqlite，用ATTACH模拟schema，复用快照测试的楼栋接口
+ * @dependencies gorm.io/driver/sqlite, stretchr/testify
+ * @refs golden_dataset_service.go
+ */
+
+package basic_library
+
+import (
+	"context"
+	"datahub-service/service/eventlog"
+	"datahub-service/service/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func setupGoldenDB(t *testing.T) *gorm.DB {
+	db := setupSnapshotDB(t)
+	require.NoError(t, db.AutoMigrate(&models.InterfaceGoldenDataset{}, &models.InterfaceGoldenRow{}, &models.InterfaceGoldenRun{}))
+	require.NoError(t, db.Exec(`ALTER TABLE campus.buildings ADD COLUMN area REAL`).Error)
+	require.NoError(t, db.Exec(`UPDATE campus.buildings SET area = CASE id WHEN 1 THEN 1200.4 ELSE 860 END`).Error)
+	return db
+}
+
+func goldenBuildingRows() []map[string]interface{} {
+	return []map[string]interface{}{
+		{"id": float64(1), "name": "A栋", "area": 1200.0},
+		{"id": "2", "name": "B栋", "area": float64(860)},
+	}
+}
+
+func goldenFailedEvents(t *testing.T, db *gorm.DB) int64 {
+	var count int64
+	require.NoError(t, db.Model(&models.ApplicationEvent{}).Where("event_type = ? AND object_id = ?", eventlog.EventGoldenComparisonFailed, "if-building").Count(&count).Error)
+	return count
+}
+
+func TestGoldenDataset_Validation(t *testing.T) {
+	db := setupGoldenDB(t)
+	svc := NewGoldenDatasetService(db)
+	ctx := context.Background()
+
+	invalid := map[string]*GoldenDatasetInput{
+		"没有基准数据": {GoldenDatasetSettings: GoldenDatasetSettings{Name: "基准"}},
+		"没有名称":   {Rows: goldenBuildingRows()},
+		"键值为空":   {GoldenDatasetSettings: GoldenDatasetSettings{Name: "基准"}, Rows: []map[string]interface{}{{"name": "A栋"}}},
+		"键重复":    {GoldenDatasetSettings: GoldenDatasetSettings{Name: "基准"}, Rows: []map[string]interface{}{{"id": 1}, {"id": "1"}}},
+		"匹配模式错误": {GoldenDatasetSettings: GoldenDatasetSettings{Name: "基准", MatchMode: "all"}, Rows: goldenBuildingRows()},
+		"忽略键字段":  {GoldenDatasetSettings: GoldenDatasetSettings{Name: "基准", IgnoreColumns: []string{"id"}}, Rows: goldenBuildingRows()},
+		"误差列不存在": {GoldenDatasetSettings: GoldenDatasetSettings{Name: "基准", ColumnTolerances: map[string]float64{"height": 1}}, Rows: goldenBuildingRows()},
+	}
+	for name, input := range invalid {
+		_, err := svc.CreateDataset(ctx, "if-building", input, "admin")
+		assert.ErrorIs(t, err, ErrInvalidGoldenDataset, name)
+	}
+
+	dataset, err := svc.CreateDataset(ctx, "if-building", &GoldenDatasetInput{
+		GoldenDatasetSettings: GoldenDatasetSettings{Name: " 楼栋基准 "}, Rows: goldenBuildingRows(),
+	}, "admin")
+	require.NoError(t, err)
+	assert.Equal(t, "楼栋基准", dataset.Name)
+	assert.Equal(t, models.GoldenMatchSubset, dataset.MatchMode)
+	assert.Equal(t, models.JSONBStringArray{"id"}, dataset.KeyFields, "默认取接口主键")
+	assert.Equal(t, models.GoldenColumns{{Name: "id", Type: "integer"}, {Name: "name", Type: "varchar"}, {Name: "area"}}, dataset.Columns)
+
+	rows, err := svc.GetRows(ctx, "if-building", dataset.ID, 1, 10)
+	require.NoError(t, err)
+	require.Len(t, rows.Rows, 2)
+	assert.Equal(t, "B栋", rows.Rows[1]["name"])
+}
+
+func TestGoldenDataset_Compare(t *testing.T) {
+	db := setupGoldenDB(t)
+	svc := NewGoldenDatasetService(db)
+	ctx := context.Background()
+
+	dataset, err := svc.CreateDataset(ctx, "if-building", &GoldenDatasetInput{
+		GoldenDatasetSettings: GoldenDatasetSettings{Name: "楼栋基准", ColumnTolerances: map[string]float64{"area": 0.5}},
+		Rows:                  goldenBuildingRows(),
+	}, "admin")
+	require.NoError(t, err)
+
+	// 面积在误差内，整数键和文本键都能匹配
+	run, err := svc.RunComparison(ctx, "if-building", dataset.ID, "admin")
+	require.NoError(t, err)
+	assert.Equal(t, models.GoldenRunPassed, run.Status)
+	assert.Equal(t, 2, run.MatchedRows)
+	assert.Equal(t, int64(2), run.ActualRows)
+	assert.Zero(t, goldenFailedEvents(t, db))
+
+	// 修改配置后名称变化、一行丢失、多出一行
+	require.NoError(t, db.Exec(`UPDATE campus.buildings SET name = 'A座', area = 1300 WHERE id = 1`).Error)
+	require.NoError(t, db.Exec(`DELETE FROM campus.buildings WHERE id = 2`).Error)
+	require.NoError(t, db.Exec(`INSERT INTO campus.buildings (id, name) VALUES (3, 'C栋')`).Error)
+	run, err = svc.RunComparison(ctx, "if-building", dataset.ID, "admin")
+	require.NoError(t, err)
+	assert.Equal(t, models.GoldenRunFailed, run.Status)
+	assert.Equal(t, 1, run.MismatchedRows)
+	assert.Equal(t, 1, run.MissingRows)
+	assert.Zero(t, run.UnexpectedRows, "subset模式不统计多出的行")
+	assert.Equal(t, models.SyncColumnChanges{{Column: "name", Changed: 1}, {Column: "area", Changed: 1}}, run.ColumnMismatches)
+	require.Len(t, run.Mismatches, 2)
+	assert.Equal(t, "A栋", run.Mismatches[0].Expected)
+	assert.Equal(t, "A座", run.Mismatches[0].Actual)
+	require.Len(t, run.MissingKeys, 1)
+	assert.Equal(t, "2", run.MissingKeys[0]["id"])
+	assert.Equal(t, int64(1), goldenFailedEvents(t, db))
+
+	// 忽略名称、放宽误差、改为exact模式后只剩缺失和多出的行
+	settings := GoldenDatasetSettings{Name: "楼栋基准", MatchMode: models.GoldenMatchExact, RelTolerance: 0.1, IgnoreColumns: []string{"name"}}
+	dataset, err = svc.UpdateDataset(ctx, "if-building", dataset.ID, &settings, "ops")
+	require.NoError(t, err)
+	run, err = svc.RunComparison(ctx, "if-building", dataset.ID, "admin")
+	require.NoError(t, err)
+	assert.Zero(t, run.MismatchedRows)
+	assert.Equal(t, int64(1), run.UnexpectedRows)
+
+	// 字段类型变化算作表结构差异
+	require.NoError(t, db.Model(&models.DataInterface{}).Where("id = ?", "if-building").Update("table_fields_config", models.JSONB{
+		"field_0": map[string]interface{}{"name_en": "id", "data_type": "bigint", "is_primary_key": true, "order_num": 1},
+		"field_1": map[string]interface{}{"name_en": "name", "data_type": "varchar", "order_num": 2},
+	}).Error)
+	run, err = svc.RunComparison(ctx, "if-building", dataset.ID, "admin")
+	require.NoError(t, err)
+	assert.Contains(t, run.SchemaIssues, models.GoldenSchemaIssue{Column: "id", Kind: models.GoldenSchemaTypeChanged, Expected: "integer", Actual: "bigint"})
+
+	runs, err := svc.ListRuns(ctx, "if-building", dataset.ID)
+	require.NoError(t, err)
+	assert.Len(t, runs, 4)
+	dataset, err = svc.GetDataset(ctx, "if-building", dataset.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.GoldenRunFailed, dataset.LastStatus)
+
+	require.NoError(t, svc.DeleteDataset(ctx, "if-building", dataset.ID))
+	var remaining int64
+	require.NoError(t, db.Model(&models.InterfaceGoldenRun{}).Count(&remaining).Error)
+	assert.Zero(t, remaining, "比较记录随数据集删除")
+}
+
+func TestGoldenDataset_FromSnapshotAndAutoCompare(t *testing.T) {
+	db := setupGoldenDB(t)
+	svc := NewGoldenDatasetService(db)
+	ctx := context.Background()
+
+	snapshot, err := NewSnapshotService(db).CaptureSnapshot(ctx, "if-building", "admin")
+	require.NoError(t, err)
+	dataset, err := svc.CreateDataset(ctx, "if-building", &GoldenDatasetInput{
+		GoldenDatasetSettings: GoldenDatasetSettings{Name: "上线前快照", MatchMode: models.GoldenMatchExact, AutoCompare: true},
+		SnapshotID:            snapshot.ID,
+	}, "admin")
+	require.NoError(t, err)
+	assert.Equal(t, models.GoldenSourceSnapshot, dataset.Source)
+	assert.Equal(t, 2, dataset.RowCount)
+
+	assert.Equal(t, models.GoldenRunPassed, recordGoldenComparisons(ctx, db, "task-1", "exec-1", "if-building"))
+	assert.Empty(t, recordGoldenComparisons(ctx, db, "task-1", "exec-1", "if-device"), "没有基准数据集的接口不比较")
+
+	require.NoError(t, db.Exec(`UPDATE campus.buildings SET area = 900 WHERE id = 2`).Error)
+	assert.Equal(t, models.GoldenRunFailed, recordGoldenComparisons(ctx, db, "task-1", "exec-2", "if-building"))
+	runs, err := svc.ListRuns(ctx, "if-building", dataset.ID)
+	require.NoError(t, err)
+	require.Len(t, runs, 2)
+	assert.Equal(t, models.GoldenTriggerSync, runs[0].Trigger)
+	assert.Equal(t, "exec-2", runs[0].ExecutionID)
+	assert.Equal(t, int64(1), goldenFailedEvents(t, db))
+}
+
+func TestGoldenValuesEqual(t *testing.T) {
+	assert.True(t, goldenValuesEqual(100.0, int64(100), 0, 0))
+	assert.True(t, goldenValuesEqual("100.2", 100.0, 0.5, 0))
+	assert.True(t, goldenValuesEqual(1000.0, 1009.0, 0, 0.01), "相对误差内")
+	assert.False(t, goldenValuesEqual(1000.0, 1011.0, 0, 0.01))
+	assert.False(t, goldenValuesEqual("001", "1", 0, 0), "文本不按数值比较")
+	assert.True(t, goldenValuesEqual(nil, nil, 0, 0))
+	assert.False(t, goldenValuesEqual("", nil, 0, 0))
+	assert.True(t, goldenValuesEqual("true", true, 0, 0))
+	assert.Equal(t, "3", goldenText(3.0))
+	assert.Equal(t, "3.5", goldenText(float32(3.5)))
+}
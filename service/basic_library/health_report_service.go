@@ -0,0 +1,443 @@
+/*
+ * @module service/basic_library/health_report_service
+ * @description 接口健康报告服务，按数据源统计接口调用成功率、延迟和错误分类，并导出为xlsx工作簿或可打印的HTML页面
+ * @architecture 分层架构 - 业务服务层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow 执行记录查询 -> 按接口聚合 -> 错误分类 -> 报告渲染
+ * @rules 统计数据来源于同步任务执行记录中的interface_results，报告面向源系统厂商沟通使用；配置了数据契约的接口同时统计契约违规次数；统计查询走只读副本
+ * @dependencies datahub-service/service/models, gorm.io/gorm
+ * @refs service/basic_library/sync_task_service.go, api/controllers/basic_library_controller.go
+ */
+
+package basic_library
+
+import (
+	"bytes"
+	"context"
+	"datahub-service/logger"
+	"datahub-service/service/interface_executor"
+	"datahub-service/service/models"
+	"datahub-service/service/utils"
+	"fmt"
+	"html/template"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// 错误分类
+const (
+	ErrorCategoryTimeout    = "timeout"    // 超时
+	ErrorCategoryConnection = "connection" // 网络连接失败
+	ErrorCategoryAuth       = "auth"       // 认证/授权失败
+	ErrorCategoryRateLimit  = "rate_limit" // 被源系统限流
+	ErrorCategoryClient     = "http_4xx"   // 源系统返回4xx
+	ErrorCategoryServer     = "http_5xx"   // 源系统返回5xx
+	ErrorCategoryParse      = "parse"      // 响应解析失败
+	ErrorCategoryDataWrite  = "data_write" // 写入数据底座失败
+	ErrorCategoryOther      = "other"      // 其他
+)
+
+const (
+	defaultHealthReportDays = 30  // 报告默认统计天数
+	maxHealthReportDays     = 180 // 报告最大统计天数
+	maxHealthReportErrors   = 500 // 报告错误明细最大条数
+)
+
+// ErrorCategoryNames 错误分类中文名称
+var ErrorCategoryNames = map[string]string{
+	ErrorCategoryTimeout:    "请求超时",
+	ErrorCategoryConnection: "网络连接失败",
+	ErrorCategoryAuth:       "认证授权失败",
+	ErrorCategoryRateLimit:  "源系统限流",
+	ErrorCategoryClient:     "请求错误(4xx)",
+	ErrorCategoryServer:     "源系统错误(5xx)",
+	ErrorCategoryParse:      "响应解析失败",
+	ErrorCategoryDataWrite:  "数据写入失败",
+	ErrorCategoryOther:      "其他错误",
+}
+
+// errorCategoryKeywords 错误分类关键字，按顺序匹配
+var errorCategoryKeywords = []struct {
+	category string
+	keywords []string
+}{
+	{ErrorCategoryTimeout, []string{"timeout", "deadline exceeded", "超时"}},
+	{ErrorCategoryRateLimit, []string{"429", "too many requests", "rate limit", "限流"}},
+	{ErrorCategoryAuth, []string{"401", "403", "unauthorized", "forbidden", "token", "认证", "授权", "鉴权"}},
+	{ErrorCategoryConnection, []string{"connection refused", "connection reset", "no such host", "dial tcp", "eof", "network", "连接失败", "连接"}},
+	{ErrorCategoryServer, []string{"500", "502", "503", "504", "internal server error", "bad gateway", "service unavailable"}},
+	{ErrorCategoryClient, []string{"400", "404", "405", "bad request", "not found"}},
+	{ErrorCategoryParse, []string{"unmarshal", "parse", "invalid character", "解析"}},
+	{ErrorCategoryDataWrite, []string{"insert", "sql", "duplicate key", "violates", "column", "写入", "插入"}},
+}
+
+// ClassifySyncError 根据错误信息对接口调用错误进行分类
+func ClassifySyncError(message string) string {
+	lower := strings.ToLower(message)
+	for _, rule := range errorCategoryKeywords {
+		for _, keyword := range rule.keywords {
+			if strings.Contains(lower, keyword) {
+				return rule.category
+			}
+		}
+	}
+	return ErrorCategoryOther
+}
+
+// newInterfaceCallResult 构建单个接口调用结果，写入执行记录的interface_results
+func newInterfaceCallResult(interfaceID string, success bool, durationMs, updatedRows int64, errorMessage string) map[string]interface{} {
+	result := map[string]interface{}{
+		"interface_id": interfaceID,
+		"success":      success,
+		"duration_ms":  durationMs,
+		"updated_rows": updatedRows,
+	}
+	if errorMessage != "" {
+		// 按原始错误分类，保存脱敏后的错误
+		result["error"] = logger.SanitizeString(errorMessage)
+		result["error_category"] = ClassifySyncError(errorMessage)
+		classification := interface_executor.ClassifyFailureMessage(errorMessage)
+		result["failure_code"] = classification.Code
+		result["failure_class"] = string(classification.Class)
+	}
+	return result
+}
+
+// InterfaceHealthStat 单个接口的健康统计
+type InterfaceHealthStat struct {
+	InterfaceID    string         `json:"interface_id"`
+	InterfaceName  string         `json:"interface_name"`
+	InterfaceCode  string         `json:"interface_code"`
+	TotalCalls     int            `json:"total_calls"`
+	SuccessCalls   int            `json:"success_calls"`
+	FailedCalls    int            `json:"failed_calls"`
+	SuccessRate    float64        `json:"success_rate"` // 百分比
+	AvgLatencyMs   float64        `json:"avg_latency_ms"`
+	MaxLatencyMs   int64          `json:"max_latency_ms"`
+	ErrorBreakdown map[string]int `json:"error_breakdown"` // 错误分类 -> 次数
+	LastCallTime   *time.Time     `json:"last_call_time,omitempty"`
+	// 数据契约校验次数和其中不合规(违规或被拒绝)的次数，接口未配置契约时为0
+	ContractChecks     int `json:"contract_checks"`
+	ContractViolations int `json:"contract_violations"`
+	totalLatencyMs     int64
+}
+
+// InterfaceErrorDetail 接口错误明细
+type InterfaceErrorDetail struct {
+	Time          time.Time `json:"time"`
+	InterfaceID   string    `json:"interface_id"`
+	InterfaceName string    `json:"interface_name"`
+	Category      string    `json:"category"`
+	Message       string    `json:"message"`
+}
+
+// DataSourceHealthReport 数据源接口健康报告
+type DataSourceHealthReport struct {
+	DataSourceID   string                 `json:"data_source_id"`
+	DataSourceName string                 `json:"data_source_name"`
+	DataSourceType string                 `json:"data_source_type"`
+	PeriodStart    time.Time              `json:"period_start"`
+	PeriodEnd      time.Time              `json:"period_end"`
+	GeneratedAt    time.Time              `json:"generated_at"`
+	TotalCalls     int                    `json:"total_calls"`
+	SuccessRate    float64                `json:"success_rate"`
+	AvgLatencyMs   float64                `json:"avg_latency_ms"`
+	ErrorBreakdown map[string]int         `json:"error_breakdown"`
+	Interfaces     []*InterfaceHealthStat `json:"interfaces"`
+	Errors         []InterfaceErrorDetail `json:"errors"`
+	ErrorsTotal    int                    `json:"errors_total"` // 错误总数，明细最多保留maxHealthReportErrors条
+}
+
+// HealthReportService 接口健康报告服务
+type HealthReportService struct {
+	db     *gorm.DB
+	readDB *gorm.DB
+}
+
+// NewHealthReportService 创建接口健康报告服务实例
+func NewHealthReportService(db *gorm.DB) *HealthReportService {
+	return &HealthReportService{db: db, readDB: db}
+}
+
+// SetReadDB 设置只读副本连接，报告统计和导出查询走只读副本
+func (s *HealthReportService) SetReadDB(readDB *gorm.DB) {
+	if readDB != nil {
+		s.readDB = readDB
+	}
+}
+
+// GenerateDataSourceHealthReport 生成数据源下所有接口近days天的健康报告
+func (s *HealthReportService) GenerateDataSourceHealthReport(ctx context.Context, dataSourceID string, days int) (*DataSourceHealthReport, error) {
+	if days <= 0 {
+		days = defaultHealthReportDays
+	}
+	if days > maxHealthReportDays {
+		days = maxHealthReportDays
+	}
+
+	var dataSource models.DataSource
+	if err := s.readDB.WithContext(ctx).First(&dataSource, "id = ?", dataSourceID).Error; err != nil {
+		return nil, err
+	}
+
+	var interfaces []models.DataInterface
+	if err := s.readDB.WithContext(ctx).Where("data_source_id = ?", dataSourceID).Order("name_zh").Find(&interfaces).Error; err != nil {
+		return nil, fmt.Errorf("查询数据源接口失败: %w", err)
+	}
+
+	now := time.Now()
+	report := &DataSourceHealthReport{
+		DataSourceID:   dataSource.ID,
+		DataSourceName: dataSource.Name,
+		DataSourceType: dataSource.Type,
+		PeriodStart:    now.AddDate(0, 0, -days),
+		PeriodEnd:      now,
+		GeneratedAt:    now,
+		ErrorBreakdown: make(map[string]int),
+		Interfaces:     make([]*InterfaceHealthStat, 0, len(interfaces)),
+		Errors:         make([]InterfaceErrorDetail, 0),
+	}
+
+	stats := make(map[string]*InterfaceHealthStat, len(interfaces))
+	for _, iface := range interfaces {
+		stat := &InterfaceHealthStat{
+			InterfaceID:    iface.ID,
+			InterfaceName:  iface.NameZh,
+			InterfaceCode:  iface.NameEn,
+			ErrorBreakdown: make(map[string]int),
+		}
+		stats[iface.ID] = stat
+		report.Interfaces = append(report.Interfaces, stat)
+	}
+
+	var executions []models.SyncTaskExecution
+	err := s.readDB.WithContext(ctx).
+		Joins("JOIN sync_tasks ON sync_tasks.id = sync_task_executions.task_id").
+		Where("sync_tasks.data_source_id = ? AND sync_task_executions.start_time >= ?", dataSourceID, report.PeriodStart).
+		Order("sync_task_executions.start_time DESC").
+		Find(&executions).Error
+	if err != nil {
+		return nil, fmt.Errorf("查询执行记录失败: %w", err)
+	}
+
+	var totalLatency int64
+	var successCalls int
+	for _, execution := range executions {
+		results, ok := execution.Result["interface_results"].([]interface{})
+		if !ok {
+			continue
+		}
+		for _, item := range results {
+			call, ok := item.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			interfaceID, _ := call["interface_id"].(string)
+			stat, exists := stats[interfaceID]
+			if !exists {
+				continue
+			}
+
+			duration := toInt64(call["duration_ms"])
+			stat.TotalCalls++
+			stat.totalLatencyMs += duration
+			if duration > stat.MaxLatencyMs {
+				stat.MaxLatencyMs = duration
+			}
+			if stat.LastCallTime == nil || execution.StartTime.After(*stat.LastCallTime) {
+				callTime := execution.StartTime
+				stat.LastCallTime = &callTime
+			}
+			report.TotalCalls++
+			totalLatency += duration
+			if status, _ := call["contract_status"].(string); status != "" {
+				stat.ContractChecks++
+				if status != models.ContractStatusCompliant {
+					stat.ContractViolations++
+				}
+			}
+
+			if success, _ := call["success"].(bool); success {
+				stat.SuccessCalls++
+				successCalls++
+				continue
+			}
+
+			stat.FailedCalls++
+			message, _ := call["error"].(string)
+			category, _ := call["error_category"].(string)
+			if category == "" {
+				category = ClassifySyncError(message)
+			}
+			stat.ErrorBreakdown[category]++
+			report.ErrorBreakdown[category]++
+			report.ErrorsTotal++
+			if len(report.Errors) < maxHealthReportErrors {
+				report.Errors = append(report.Errors, InterfaceErrorDetail{
+					Time:          execution.StartTime,
+					InterfaceID:   interfaceID,
+					InterfaceName: stat.InterfaceName,
+					Category:      category,
+					Message:       message,
+				})
+			}
+		}
+	}
+
+	for _, stat := range report.Interfaces {
+		if stat.TotalCalls > 0 {
+			stat.SuccessRate = roundPercent(float64(stat.SuccessCalls) / float64(stat.TotalCalls))
+			stat.AvgLatencyMs = float64(stat.totalLatencyMs) / float64(stat.TotalCalls)
+		}
+	}
+	if report.TotalCalls > 0 {
+		report.SuccessRate = roundPercent(float64(successCalls) / float64(report.TotalCalls))
+		report.AvgLatencyMs = float64(totalLatency) / float64(report.TotalCalls)
+	}
+
+	// 成功率低的接口排在前面，便于与厂商沟通
+	sort.SliceStable(report.Interfaces, func(i, j int) bool {
+		return report.Interfaces[i].SuccessRate < report.Interfaces[j].SuccessRate
+	})
+
+	return report, nil
+}
+
+// RenderHealthReportXLSX 将报告写成xlsx工作簿，包含概览、接口统计和错误明细三个工作表
+func RenderHealthReportXLSX(report *DataSourceHealthReport) ([]byte, error) {
+	summary := [][]string{
+		{"数据源", report.DataSourceName},
+		{"数据源类型", report.DataSourceType},
+		{"统计周期", report.PeriodStart.Format("2006-01-02") + " ~ " + report.PeriodEnd.Format("2006-01-02")},
+		{"生成时间", report.GeneratedAt.Format("2006-01-02 15:04:05")},
+		{"调用总次数", strconv.Itoa(report.TotalCalls)},
+		{"调用成功率(%)", fmt.Sprint(report.SuccessRate)},
+		{"平均延迟(ms)", fmt.Sprintf("%.1f", report.AvgLatencyMs)},
+	}
+	for _, category := range sortedCategories(report.ErrorBreakdown) {
+		summary = append(summary, []string{"错误-" + ErrorCategoryNames[category], strconv.Itoa(report.ErrorBreakdown[category])})
+	}
+
+	interfaceRows := [][]string{{"接口名称", "接口编码", "调用次数", "成功次数", "失败次数", "成功率(%)", "平均延迟(ms)", "最大延迟(ms)", "错误分类", "契约校验次数", "契约违规次数", "最近调用时间"}}
+	for _, stat := range report.Interfaces {
+		lastCall := ""
+		if stat.LastCallTime != nil {
+			lastCall = stat.LastCallTime.Format("2006-01-02 15:04:05")
+		}
+		interfaceRows = append(interfaceRows, []string{
+			stat.InterfaceName, stat.InterfaceCode, strconv.Itoa(stat.TotalCalls), strconv.Itoa(stat.SuccessCalls), strconv.Itoa(stat.FailedCalls),
+			fmt.Sprint(stat.SuccessRate), fmt.Sprintf("%.1f", stat.AvgLatencyMs), strconv.FormatInt(stat.MaxLatencyMs, 10), formatBreakdown(stat.ErrorBreakdown),
+			strconv.Itoa(stat.ContractChecks), strconv.Itoa(stat.ContractViolations), lastCall,
+		})
+	}
+
+	errorRows := [][]string{{"时间", "接口名称", "错误分类", "错误信息"}}
+	for _, detail := range report.Errors {
+		errorRows = append(errorRows, []string{
+			detail.Time.Format("2006-01-02 15:04:05"), detail.InterfaceName, ErrorCategoryNames[detail.Category], detail.Message,
+		})
+	}
+
+	content, err := utils.WriteXLSX([]utils.XLSXSheet{
+		{Name: "概览", Rows: summary},
+		{Name: "接口统计", Rows: interfaceRows},
+		{Name: "错误明细", Rows: errorRows},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("生成xlsx报告失败: %w", err)
+	}
+	return content, nil
+}
+
+// healthReportHTMLTemplate 可打印的HTML报告模板
+var healthReportHTMLTemplate = template.Must(template.New("health_report").Funcs(template.FuncMap{
+	"categoryName": func(category string) string { return ErrorCategoryNames[category] },
+	"breakdown":    formatBreakdown,
+	"datetime":     func(t time.Time) string { return t.Format("2006-01-02 15:04:05") },
+	"date":         func(t time.Time) string { return t.Format("2006-01-02") },
+	"latency":      func(v float64) string { return fmt.Sprintf("%.1f", v) },
+}).Parse(`<!DOCTYPE html>
+<html lang="zh-CN">
+<head>
+<meta charset="UTF-8">
+<title>{{.DataSourceName}} 接口健康报告</title>
+<style>
+body { font-family: sans-serif; margin: 24px; color: #222; }
+table { border-collapse: collapse; width: 100%; margin-bottom: 24px; font-size: 12px; }
+th, td { border: 1px solid #999; padding: 4px 6px; text-align: left; }
+th { background: #eee; }
+@media print { body { margin: 0; } }
+</style>
+</head>
+<body>
+<h1>{{.DataSourceName}} 接口健康报告</h1>
+<p>统计周期：{{date .PeriodStart}} ~ {{date .PeriodEnd}}，生成时间：{{datetime .GeneratedAt}}</p>
+<p>调用总次数：{{.TotalCalls}}，成功率：{{.SuccessRate}}%，平均延迟：{{latency .AvgLatencyMs}} ms</p>
+<h2>接口统计</h2>
+<table>
+<tr><th>接口名称</th><th>接口编码</th><th>调用次数</th><th>失败次数</th><th>成功率(%)</th><th>平均延迟(ms)</th><th>最大延迟(ms)</th><th>错误分类</th><th>契约违规/校验</th></tr>
+{{range .Interfaces}}<tr><td>{{.InterfaceName}}</td><td>{{.InterfaceCode}}</td><td>{{.TotalCalls}}</td><td>{{.FailedCalls}}</td><td>{{.SuccessRate}}</td><td>{{latency .AvgLatencyMs}}</td><td>{{.MaxLatencyMs}}</td><td>{{breakdown .ErrorBreakdown}}</td><td>{{.ContractViolations}}/{{.ContractChecks}}</td></tr>
+{{end}}</table>
+<h2>错误明细（共 {{.ErrorsTotal}} 条）</h2>
+<table>
+<tr><th>时间</th><th>接口名称</th><th>错误分类</th><th>错误信息</th></tr>
+{{range .Errors}}<tr><td>{{datetime .Time}}</td><td>{{.InterfaceName}}</td><td>{{categoryName .Category}}</td><td>{{.Message}}</td></tr>
+{{end}}</table>
+</body>
+</html>
+`))
+
+// RenderHealthReportHTML 将报告渲染为可打印的HTML页面
+func RenderHealthReportHTML(report *DataSourceHealthReport) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := healthReportHTMLTemplate.Execute(&buf, report); err != nil {
+		return nil, fmt.Errorf("渲染HTML报告失败: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// formatBreakdown 格式化错误分类统计
+func formatBreakdown(breakdown map[string]int) string {
+	parts := make([]string, 0, len(breakdown))
+	for _, category := range sortedCategories(breakdown) {
+		parts = append(parts, fmt.Sprintf("%s:%d", ErrorCategoryNames[category], breakdown[category]))
+	}
+	return strings.Join(parts, "; ")
+}
+
+// sortedCategories 按次数降序返回错误分类
+func sortedCategories(breakdown map[string]int) []string {
+	categories := make([]string, 0, len(breakdown))
+	for category := range breakdown {
+		categories = append(categories, category)
+	}
+	sort.Slice(categories, func(i, j int) bool {
+		if breakdown[categories[i]] != breakdown[categories[j]] {
+			return breakdown[categories[i]] > breakdown[categories[j]]
+		}
+		return categories[i] < categories[j]
+	})
+	return categories
+}
+
+// roundPercent 将比例转换为保留两位小数的百分比
+func roundPercent(ratio float64) float64 {
+	return float64(int64(ratio*10000+0.5)) / 100
+}
+
+// toInt64 将JSON数值转换为int64
+func toInt64(value interface{}) int64 {
+	switch v := value.(type) {
+	case float64:
+		return int64(v)
+	case int64:
+		return v
+	case int:
+		return int64(v)
+	}
+	return 0
+}
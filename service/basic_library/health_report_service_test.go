@@ -0,0 +1,101 @@
+/*
+ * @module service/basic_library/health_report_service_test
+ * @description 接口健康报告的错误分类、报告渲染和只读副本测试
+ * @architecture 测试层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow 构造报告 -> 渲染 -> 验证输出；主库和副本两个sqlite写入不同执行记录 -> 生成报告 -> 验证读取的连接
+ * @rules 只读副本测试使用两个内存sqlite分别作为主库和副本
+ * @dependencies testing, testify
+ * @refs health_report_service.go
+ */
+
+package basic_library
+
+import (
+	"context"
+	"datahub-service/service/models"
+	"datahub-service/service/utils"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClassifySyncError(t *testing.T) {
+	cases := map[string]string{
+		"context deadline exceeded":                         ErrorCategoryTimeout,
+		"HTTP请求失败，状态码: 429":                                 ErrorCategoryRateLimit,
+		"获取token失败: 401 Unauthorized":                       ErrorCategoryAuth,
+		"dial tcp 10.0.0.1:80: connect: connection refused": ErrorCategoryConnection,
+		"HTTP请求失败，状态码: 502":                                 ErrorCategoryServer,
+		"invalid character '<' looking for beginning":       ErrorCategoryParse,
+		"ERROR: duplicate key value violates unique":        ErrorCategoryDataWrite,
+		"未知错误": ErrorCategoryOther,
+	}
+	for message, expected := range cases {
+		assert.Equal(t, expected, ClassifySyncError(message), message)
+	}
+}
+
+func TestRenderHealthReport(t *testing.T) {
+	now := time.Now()
+	report := &DataSourceHealthReport{
+		DataSourceName: "停车系统<厂商A>",
+		PeriodStart:    now.AddDate(0, 0, -30),
+		PeriodEnd:      now,
+		GeneratedAt:    now,
+		TotalCalls:     10,
+		SuccessRate:    90,
+		ErrorBreakdown: map[string]int{ErrorCategoryTimeout: 1},
+		Interfaces: []*InterfaceHealthStat{{
+			InterfaceName:  "车辆进出记录",
+			TotalCalls:     10,
+			SuccessCalls:   9,
+			FailedCalls:    1,
+			SuccessRate:    90,
+			ErrorBreakdown: map[string]int{ErrorCategoryTimeout: 1},
+		}},
+		Errors: []InterfaceErrorDetail{{Time: now, InterfaceName: "车辆进出记录", Category: ErrorCategoryTimeout, Message: "timeout"}},
+	}
+
+	content, err := RenderHealthReportXLSX(report)
+	require.NoError(t, err)
+	sheets, err := utils.ReadXLSX(content)
+	require.NoError(t, err)
+	require.Len(t, sheets, 3)
+	assert.Equal(t, []string{"概览", "接口统计", "错误明细"}, []string{sheets[0].Name, sheets[1].Name, sheets[2].Name})
+	assert.Equal(t, []string{"数据源", "停车系统<厂商A>"}, sheets[0].Rows[0])
+	assert.Equal(t, "90", sheets[1].Rows[1][5])
+	assert.Equal(t, "请求超时", sheets[2].Rows[1][2])
+
+	html, err := RenderHealthReportHTML(report)
+	require.NoError(t, err)
+	assert.Contains(t, string(html), "请求超时:1")
+}
+
+func TestHealthReportReadsFromReplica(t *testing.T) {
+	primary, task := setupDiagnosticsDB(t)
+	replica, _ := setupDiagnosticsDB(t)
+	// 只有副本上有执行记录
+	require.NoError(t, replica.Create(&models.SyncTaskExecution{
+		ID: "exec-1", TaskID: task.ID, ExecutionType: "manual", Status: "failed", StartTime: time.Now().Add(-time.Hour),
+		Result: models.JSONB{"interface_results": []map[string]interface{}{
+			{"interface_id": "if-1", "success": false, "duration_ms": 1500, "error": "context deadline exceeded"},
+		}},
+	}).Error)
+
+	service := NewHealthReportService(primary)
+	service.SetReadDB(replica)
+	report, err := service.GenerateDataSourceHealthReport(context.Background(), "ds-1", 7)
+	require.NoError(t, err)
+	assert.Equal(t, 1, report.TotalCalls, "配置副本时从副本读取")
+	assert.Equal(t, 1, report.ErrorBreakdown[ErrorCategoryTimeout])
+
+	// 未配置副本时回退到主库
+	service = NewHealthReportService(primary)
+	service.SetReadDB(nil)
+	report, err = service.GenerateDataSourceHealthReport(context.Background(), "ds-1", 7)
+	require.NoError(t, err)
+	assert.Equal(t, 0, report.TotalCalls)
+}
@@ -0,0 +1,456 @@
+/*
+ * @module service/basic_library/incremental_key_suggest
+ * @description 增量字段自动识别，分析源数据样例和接口表字段，给出可作为增量同步字段的候选（更新时间、创建时间、自增ID等）及置信度
+ * @architecture 分层架构 - 业务服务层，评分逻辑为纯函数
+ * @documentReference ai_docs/interfaces.md
+ * @stateFlow 获取样例数据 -> 识别时间和整数字段 -> 统计非空率、去重率和有序度 -> 结合字段名和接口表字段评分 -> 按置信度排序并生成增量配置
+ * @rules 只有能解析为时间或整数的字段才是候选；字段名称提示占35%，有序度、非空率各占25%，去重率占15%；
+ *        更新时间类字段能捕获更新，创建时间和自增ID只能捕获新增；最佳候选置信度不低于0.6时才生成incremental_config
+ * @dependencies datahub-service/service/interface_executor, datahub-service/service/models
+ * @refs field_mapping_suggest.go, config_lint_service.go, service/interface_executor/execute_operations.go
+ */
+
+package basic_library
+
+import (
+	"context"
+	"datahub-service/service/interface_executor"
+	"datahub-service/service/models"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cast"
+)
+
+const (
+	// incrementalSampleLimit 未提供样例时从数据源读取的样例行数
+	incrementalSampleLimit = 100
+	// incrementalMinSampleRows 样例行数少于该值时有序度不可靠
+	incrementalMinSampleRows = 10
+	// incrementalMinNonNullRatio 非空率低于该值的字段不作为候选
+	incrementalMinNonNullRatio = 0.5
+	// incrementalConfigMinConfidence 生成增量配置所需的最低置信度
+	incrementalConfigMinConfidence = 0.6
+)
+
+// 增量字段类别
+const (
+	IncrementalKeyUpdateTime = "update_time" // 更新时间，能捕获新增和更新
+	IncrementalKeyCreateTime = "create_time" // 创建时间，只能捕获新增
+	IncrementalKeyTimestamp  = "timestamp"   // 含义不明的时间字段
+	IncrementalKeyVersion    = "version"     // 版本号或修改序号，能捕获新增和更新
+	IncrementalKeySequence   = "sequence"    // 自增ID或序号，只能捕获新增
+)
+
+// incrementalTimeLayouts 识别时间字符串的格式，与增量配置的field_format一致
+var incrementalTimeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04:05.000",
+	"2006/01/02 15:04:05",
+	"20060102150405",
+	"2006-01-02",
+}
+
+// IncrementalKeySuggestion 增量字段识别结果
+type IncrementalKeySuggestion struct {
+	InterfaceID       string                    `json:"interface_id"`
+	SampleRows        int                       `json:"sample_rows"`
+	Candidates        []IncrementalKeyCandidate `json:"candidates"`                   // 按置信度从高到低排列
+	IncrementalConfig map[string]interface{}    `json:"incremental_config,omitempty"` // 最佳候选对应的增量配置，可写入interface_config.incremental_config
+	Warnings          []string                  `json:"warnings"`
+}
+
+// IncrementalKeyCandidate 增量字段候选
+type IncrementalKeyCandidate struct {
+	Field           string   `json:"field"`            // 源字段名
+	Column          string   `json:"column,omitempty"` // 对应的接口表字段
+	Kind            string   `json:"kind"`             // update_time, create_time, timestamp, version, sequence
+	FieldType       string   `json:"field_type"`       // 增量配置的field_type：timestamp或number
+	FieldFormat     string   `json:"field_format,omitempty"`
+	Confidence      float64  `json:"confidence"`
+	NonNullRatio    float64  `json:"non_null_ratio"`
+	DistinctRatio   float64  `json:"distinct_ratio"`
+	OrderedRatio    float64  `json:"ordered_ratio"`    // 样例中相邻行保持同一方向的比例
+	CapturesUpdates bool     `json:"captures_updates"` // 源记录更新时该字段是否变化
+	MaxValue        string   `json:"max_value"`        // 样例中的最大值，可作为initial_value参考
+	Reasons         []string `json:"reasons"`
+}
+
+// incrementalValue 解析后的样例值，用于比较
+type incrementalValue struct {
+	order float64
+	raw   string
+}
+
+// SuggestIncrementalKeys 识别可作为增量同步字段的候选，sample为空时通过接口预览从数据源读取样例
+func (s *InterfaceService) SuggestIncrementalKeys(interfaceID string, sample []map[string]interface{}) (*IncrementalKeySuggestion, error) {
+	interfaceData, err := s.GetDataInterface(interfaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(sample) == 0 {
+		response, err := s.executor.Execute(context.Background(), &interface_executor.ExecuteRequest{
+			InterfaceID:   interfaceID,
+			InterfaceType: "basic_library",
+			ExecuteType:   "preview",
+			Limit:         incrementalSampleLimit,
+			Parameters:    map[string]interface{}{"limit": incrementalSampleLimit},
+			Options:       map[string]interface{}{},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("获取样例数据失败: %w", err)
+		}
+		sample, _ = response.Data.([]map[string]interface{})
+	}
+	if len(sample) == 0 {
+		return nil, fmt.Errorf("样例数据为空，无法识别增量字段")
+	}
+
+	result := suggestIncrementalKeys(sample, sortedTableFields(interfaceData.TableFieldsConfig), parseSourceToTarget(interfaceData.ParseConfig))
+	result.InterfaceID = interfaceID
+	return result, nil
+}
+
+// suggestIncrementalKeys 对样例中的每个字段评分，返回候选和最佳候选的增量配置
+func suggestIncrementalKeys(sample []map[string]interface{}, fields []models.TableField, sourceToTarget map[string]string) *IncrementalKeySuggestion {
+	result := &IncrementalKeySuggestion{
+		SampleRows: len(sample),
+		Candidates: make([]IncrementalKeyCandidate, 0),
+		Warnings:   make([]string, 0),
+	}
+	if len(sample) < incrementalMinSampleRows {
+		result.Warnings = append(result.Warnings, fmt.Sprintf("样例只有%d行，有序度和去重率仅供参考", len(sample)))
+	}
+
+	columns := make(map[string]models.TableField, len(fields))
+	for _, field := range fields {
+		columns[field.NameEn] = field
+	}
+
+	for _, name := range sampleFieldNames(sample) {
+		values := make([]interface{}, len(sample))
+		for i, row := range sample {
+			values[i] = row[name]
+		}
+		candidate, ok := scoreIncrementalField(name, values)
+		if !ok {
+			continue
+		}
+		applyTableFieldHints(&candidate, columns, sourceToTarget)
+		result.Candidates = append(result.Candidates, candidate)
+	}
+
+	sort.SliceStable(result.Candidates, func(i, j int) bool {
+		if result.Candidates[i].Confidence != result.Candidates[j].Confidence {
+			return result.Candidates[i].Confidence > result.Candidates[j].Confidence
+		}
+		return result.Candidates[i].Field < result.Candidates[j].Field
+	})
+
+	if len(result.Candidates) == 0 {
+		result.Warnings = append(result.Warnings, "样例中没有可解析为时间或整数的字段，建议使用全量同步")
+		return result
+	}
+	best := result.Candidates[0]
+	if best.Confidence < incrementalConfigMinConfidence {
+		result.Warnings = append(result.Warnings, "没有置信度足够的增量字段，请人工确认后再启用增量同步")
+		return result
+	}
+	result.IncrementalConfig = map[string]interface{}{
+		"enabled":           true,
+		"incremental_field": best.Field,
+		"field_type":        best.FieldType,
+	}
+	if best.FieldFormat != "" {
+		result.IncrementalConfig["field_format"] = best.FieldFormat
+	}
+	if !best.CapturesUpdates {
+		result.Warnings = append(result.Warnings, fmt.Sprintf("建议的增量字段%s只能捕获新增记录，源记录更新后不会被同步", best.Field))
+	}
+	return result
+}
+
+// scoreIncrementalField 统计单个字段的样例值并评分，字段值不是时间或整数时返回false
+func scoreIncrementalField(name string, values []interface{}) (IncrementalKeyCandidate, bool) {
+	candidate := IncrementalKeyCandidate{Field: name, Reasons: make([]string, 0)}
+	nameKind, nameScore := incrementalNameHint(name)
+
+	var parsed []incrementalValue
+	var ok bool
+	candidate.FieldType, candidate.FieldFormat, parsed, ok = parseIncrementalValues(values)
+	if !ok {
+		return candidate, false
+	}
+	candidate.NonNullRatio = roundScore(float64(len(parsed)) / float64(len(values)))
+	if candidate.NonNullRatio < incrementalMinNonNullRatio {
+		return candidate, false
+	}
+
+	distinct := make(map[float64]bool, len(parsed))
+	maxIndex := 0
+	for i, value := range parsed {
+		distinct[value.order] = true
+		if value.order > parsed[maxIndex].order {
+			maxIndex = i
+		}
+	}
+	candidate.DistinctRatio = roundScore(float64(len(distinct)) / float64(len(parsed)))
+	candidate.OrderedRatio = roundScore(orderedRatio(parsed))
+	candidate.MaxValue = parsed[maxIndex].raw
+
+	// 数值型时间戳（秒或毫秒）按时间字段处理，增量参数仍按数值传递
+	isTime := candidate.FieldType == "timestamp" ||
+		(nameKind == IncrementalKeyUpdateTime || nameKind == IncrementalKeyCreateTime || nameKind == IncrementalKeyTimestamp) &&
+			(allInRange(parsed, 1e9, 1e10) || allInRange(parsed, 1e12, 1e13))
+	switch {
+	case isTime && (nameKind == IncrementalKeyUpdateTime || nameKind == IncrementalKeyCreateTime):
+		candidate.Kind = nameKind
+	case isTime:
+		candidate.Kind = IncrementalKeyTimestamp
+	case nameKind == IncrementalKeyVersion:
+		candidate.Kind = IncrementalKeyVersion
+	default:
+		candidate.Kind = IncrementalKeySequence
+		if nameKind != IncrementalKeySequence {
+			nameScore = 0.2
+		}
+	}
+	candidate.CapturesUpdates = candidate.Kind == IncrementalKeyUpdateTime || candidate.Kind == IncrementalKeyVersion
+
+	// 时间字段允许多条记录同一时间更新，去重率达到一半即视为满分
+	distinctScore := candidate.DistinctRatio
+	if isTime {
+		distinctScore = math.Min(1, 2*candidate.DistinctRatio)
+	}
+	candidate.Confidence = roundScore(0.35*nameScore + 0.25*candidate.OrderedRatio + 0.25*candidate.NonNullRatio + 0.15*distinctScore)
+
+	switch candidate.Kind {
+	case IncrementalKeyUpdateTime:
+		candidate.Reasons = append(candidate.Reasons, "字段名表示更新时间，能捕获新增和更新")
+	case IncrementalKeyCreateTime:
+		candidate.Reasons = append(candidate.Reasons, "字段名表示创建时间，只能捕获新增")
+	case IncrementalKeyVersion:
+		candidate.Reasons = append(candidate.Reasons, "字段名表示版本号，能捕获新增和更新")
+	case IncrementalKeySequence:
+		candidate.Reasons = append(candidate.Reasons, "整数字段，按自增序号使用时只能捕获新增")
+	default:
+		candidate.Reasons = append(candidate.Reasons, "时间字段，无法从名称判断是否随更新变化")
+	}
+	if candidate.NonNullRatio < 1 {
+		candidate.Reasons = append(candidate.Reasons, fmt.Sprintf("%.0f%%的样例为空，空值记录不会被增量查询到", (1-candidate.NonNullRatio)*100))
+	}
+	if candidate.OrderedRatio >= 0.95 && len(parsed) > 2 {
+		candidate.Reasons = append(candidate.Reasons, "样例按该字段有序，可能是源系统的默认排序字段")
+	}
+	if candidate.Kind == IncrementalKeySequence && candidate.DistinctRatio < 1 {
+		candidate.Reasons = append(candidate.Reasons, "存在重复值，不是自增ID")
+	}
+	return candidate, true
+}
+
+// parseIncrementalValues 将样例值解析为时间或整数，返回增量配置的field_type和field_format
+func parseIncrementalValues(values []interface{}) (string, string, []incrementalValue, bool) {
+	var layout string
+	fieldType := ""
+	parsed := make([]incrementalValue, 0, len(values))
+	for _, value := range values {
+		if value == nil || value == "" {
+			continue
+		}
+		raw := cast.ToString(value)
+		if number, ok := incrementalInteger(value); ok {
+			if fieldType == "" {
+				fieldType = "number"
+			}
+			if fieldType != "number" {
+				return "", "", nil, false
+			}
+			parsed = append(parsed, incrementalValue{order: number, raw: raw})
+			continue
+		}
+		text, isString := value.(string)
+		if !isString || (fieldType != "" && fieldType != "timestamp") {
+			return "", "", nil, false
+		}
+		if layout == "" {
+			layout = detectTimeLayout(text)
+			if layout == "" {
+				return "", "", nil, false
+			}
+			fieldType = "timestamp"
+		}
+		t, err := time.Parse(layout, text)
+		if err != nil {
+			return "", "", nil, false
+		}
+		parsed = append(parsed, incrementalValue{order: float64(t.UnixNano()), raw: text})
+	}
+	if len(parsed) == 0 {
+		return "", "", nil, false
+	}
+
+	return fieldType, layout, parsed, true
+}
+
+// incrementalInteger 判断样例值是否为整数，数字字符串也视为整数
+func incrementalInteger(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return cast.ToFloat64(v), true
+	case float64:
+		return v, v == math.Trunc(v)
+	case float32:
+		return float64(v), float64(v) == math.Trunc(float64(v))
+	case string:
+		if len(v) == 14 {
+			// yyyyMMddHHmmss格式的时间按时间解析
+			return 0, false
+		}
+		n, err := strconv.ParseInt(v, 10, 64)
+		return float64(n), err == nil
+	}
+	return 0, false
+}
+
+// detectTimeLayout 返回能解析该时间字符串的格式
+func detectTimeLayout(text string) string {
+	for _, layout := range incrementalTimeLayouts {
+		if _, err := time.Parse(layout, text); err == nil {
+			return layout
+		}
+	}
+	return ""
+}
+
+// incrementalNameHint 按字段名判断字段类别，返回类别和名称得分
+func incrementalNameHint(name string) (string, float64) {
+	joined := strings.Join(splitFieldName(name), "")
+	contains := func(parts ...string) bool {
+		for _, part := range parts {
+			if strings.Contains(joined, part) {
+				return true
+			}
+		}
+		return false
+	}
+	switch {
+	case contains("update", "modif", "change", "mtime", "lastedit"):
+		return IncrementalKeyUpdateTime, 1
+	case contains("version", "rowver", "revision"):
+		return IncrementalKeyVersion, 0.9
+	case contains("create", "insert", "ctime", "addtime"):
+		return IncrementalKeyCreateTime, 0.6
+	case contains("time", "date") || joined == "ts":
+		return IncrementalKeyTimestamp, 0.5
+	case joined == "id" || contains("seq", "serial", "rowid", "autoid"):
+		return IncrementalKeySequence, 0.7
+	}
+	return "", 0.2
+}
+
+// applyTableFieldHints 结合字段映射和接口表字段调整候选
+func applyTableFieldHints(candidate *IncrementalKeyCandidate, columns map[string]models.TableField, sourceToTarget map[string]string) {
+	column := sourceToTarget[candidate.Field]
+	if column == "" {
+		if _, ok := columns[candidate.Field]; ok {
+			column = candidate.Field
+		}
+	}
+	if column == "" {
+		if len(columns) > 0 {
+			candidate.Confidence = roundScore(candidate.Confidence * 0.8)
+			candidate.Reasons = append(candidate.Reasons, "未映射到接口表字段，启用前需在字段映射和表字段中添加")
+		}
+		return
+	}
+	candidate.Column = column
+	if column != candidate.Field {
+		candidate.Reasons = append(candidate.Reasons, fmt.Sprintf("映射到接口表字段%s，需在parse_config.field_mapping中登记", column))
+	}
+
+	field, ok := columns[column]
+	if !ok {
+		return
+	}
+	if class := classifyFieldType(field.DataType); class == fieldTypeClassOther || class == fieldTypeClassString {
+		candidate.Confidence = roundScore(candidate.Confidence * 0.7)
+		candidate.Reasons = append(candidate.Reasons, fmt.Sprintf("接口表字段类型为%s，最近同步值无法按时间或数值比较", field.DataType))
+	}
+	if field.IsIncrementField {
+		candidate.Confidence = roundScore(math.Min(1, candidate.Confidence+0.1))
+		candidate.Reasons = append(candidate.Reasons, "接口表字段已标记为增量字段")
+	}
+}
+
+// orderedRatio 相邻值保持同一方向（不减或不增）的比例，取两个方向中较大者
+func orderedRatio(values []incrementalValue) float64 {
+	if len(values) < 2 {
+		return 0
+	}
+	ascending, descending := 0, 0
+	for i := 1; i < len(values); i++ {
+		if values[i].order >= values[i-1].order {
+			ascending++
+		}
+		if values[i].order <= values[i-1].order {
+			descending++
+		}
+	}
+	return float64(max(ascending, descending)) / float64(len(values)-1)
+}
+
+// allInRange 判断全部值是否在[low, high)之间
+func allInRange(values []incrementalValue, low, high float64) bool {
+	for _, value := range values {
+		if value.order < low || value.order >= high {
+			return false
+		}
+	}
+	return true
+}
+
+// sampleFieldNames 按字段名排序返回样例中出现过的字段
+func sampleFieldNames(sample []map[string]interface{}) []string {
+	seen := make(map[string]bool)
+	names := make([]string, 0)
+	for _, row := range sample {
+		for key := range row {
+			if !seen[key] {
+				seen[key] = true
+				names = append(names, key)
+			}
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// parseSourceToTarget 解析parseConfig.fieldMapping的源字段到目标字段映射，支持数组格式和旧的对象格式
+func parseSourceToTarget(parseConfig models.JSONB) map[string]string {
+	result := make(map[string]string)
+	switch mapping := parseConfig["fieldMapping"].(type) {
+	case []interface{}:
+		for _, item := range mapping {
+			if entry, ok := item.(map[string]interface{}); ok {
+				source, target := cast.ToString(entry["source"]), cast.ToString(entry["target"])
+				if source != "" && target != "" {
+					result[source] = target
+				}
+			}
+		}
+	case map[string]interface{}:
+		for source, target := range mapping {
+			if name := cast.ToString(target); name != "" {
+				result[source] = name
+			}
+		}
+	}
+	return result
+}
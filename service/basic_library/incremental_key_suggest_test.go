@@ -0,0 +1,119 @@
+/*
+ * @module service/basic_library/incremental_key_suggest_test
+ * @description 增量字段识别测试，覆盖更新时间、创建时间和自增ID的识别与排序、数值时间戳、空值和非时间字段的过滤以及接口表字段提示
+ * @architecture 测试层
+ * @documentReference ai_docs/interfaces.md
+ * @stateFlow 构造样例数据和表字段 -> 识别增量字段 -> 验证候选顺序、类别和增量配置
+ * @rules 纯函数测试，不依赖数据库和数据源
+ * @dependencies stretchr/testify
+ * @refs incremental_key_suggest.go
+ */
+
+package basic_library
+
+import (
+	"datahub-service/service/models"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// incrementalTestSample 构造按id递增、更新时间乱序的样例
+func incrementalTestSample(rows int) []map[string]interface{} {
+	base := time.Date(2026, 3, 1, 8, 0, 0, 0, time.UTC)
+	sample := make([]map[string]interface{}, rows)
+	for i := range sample {
+		sample[i] = map[string]interface{}{
+			"id":         float64(i + 1),
+			"plateNo":    fmt.Sprintf("粤B%05d", i),
+			"createTime": base.Add(time.Duration(i) * time.Minute).Format("2006-01-02 15:04:05"),
+			"updateTime": base.Add(time.Duration((i*7)%rows) * time.Hour).Format(time.RFC3339),
+			"status":     float64(i % 2),
+			"enabled":    i%3 == 0,
+		}
+	}
+	return sample
+}
+
+func TestSuggestIncrementalKeys(t *testing.T) {
+	result := suggestIncrementalKeys(incrementalTestSample(20), nil, nil)
+
+	candidates := make(map[string]IncrementalKeyCandidate)
+	for _, c := range result.Candidates {
+		candidates[c.Field] = c
+	}
+	assert.NotContains(t, candidates, "plateNo", "非时间和整数字段不是候选")
+	assert.NotContains(t, candidates, "enabled")
+
+	require.NotEmpty(t, result.Candidates)
+	assert.Equal(t, "updateTime", result.Candidates[0].Field, "更新时间优先")
+	assert.Equal(t, IncrementalKeyUpdateTime, result.Candidates[0].Kind)
+	assert.True(t, result.Candidates[0].CapturesUpdates)
+	assert.Equal(t, time.RFC3339Nano, result.Candidates[0].FieldFormat)
+
+	assert.Equal(t, IncrementalKeyCreateTime, candidates["createTime"].Kind)
+	assert.Equal(t, "2006-01-02 15:04:05", candidates["createTime"].FieldFormat)
+	assert.Equal(t, 1.0, candidates["createTime"].OrderedRatio)
+	assert.Equal(t, IncrementalKeySequence, candidates["id"].Kind)
+	assert.Equal(t, "number", candidates["id"].FieldType)
+	assert.Equal(t, "20", candidates["id"].MaxValue)
+	assert.Less(t, candidates["status"].Confidence, candidates["id"].Confidence, "重复值多的整数字段置信度低")
+
+	assert.Equal(t, map[string]interface{}{
+		"enabled": true, "incremental_field": "updateTime", "field_type": "timestamp", "field_format": time.RFC3339Nano,
+	}, result.IncrementalConfig)
+	assert.Empty(t, result.Warnings)
+}
+
+func TestSuggestIncrementalKeysEdgeCases(t *testing.T) {
+	// 只有创建时间时给出只能捕获新增的提示；样例太少时提示仅供参考
+	sample := []map[string]interface{}{
+		{"gmt_create": float64(1767225600000), "name": "a"},
+		{"gmt_create": float64(1767225660000), "name": "b"},
+		{"gmt_create": nil, "name": "c"},
+	}
+	result := suggestIncrementalKeys(sample, nil, nil)
+	require.Len(t, result.Candidates, 1)
+	candidate := result.Candidates[0]
+	assert.Equal(t, IncrementalKeyCreateTime, candidate.Kind, "毫秒时间戳按时间字段处理")
+	assert.Equal(t, "number", candidate.FieldType)
+	assert.Equal(t, 0.67, candidate.NonNullRatio)
+	assert.Len(t, result.Warnings, 2)
+
+	// 字段映射到接口表中的非时间类型字段时降低置信度
+	fields := []models.TableField{{NameEn: "update_time", DataType: "jsonb"}}
+	plain := suggestIncrementalKeys(incrementalTestSample(20), nil, nil)
+	mapped := suggestIncrementalKeys(incrementalTestSample(20), fields, map[string]string{"updateTime": "update_time"})
+	var before, after IncrementalKeyCandidate
+	for i := range plain.Candidates {
+		if plain.Candidates[i].Field == "updateTime" {
+			before = plain.Candidates[i]
+		}
+		if mapped.Candidates[i].Field == "updateTime" {
+			after = mapped.Candidates[i]
+		}
+	}
+	assert.Equal(t, "update_time", after.Column)
+	assert.Less(t, after.Confidence, before.Confidence)
+
+	assert.Empty(t, suggestIncrementalKeys([]map[string]interface{}{{"name": "a"}}, nil, nil).Candidates)
+}
+
+func TestIncrementalNameHint(t *testing.T) {
+	kind, _ := incrementalNameHint("gmtModified")
+	assert.Equal(t, IncrementalKeyUpdateTime, kind)
+	kind, _ = incrementalNameHint("UPDATE_TIME")
+	assert.Equal(t, IncrementalKeyUpdateTime, kind)
+	kind, _ = incrementalNameHint("row_version")
+	assert.Equal(t, IncrementalKeyVersion, kind)
+	kind, _ = incrementalNameHint("passDate")
+	assert.Equal(t, IncrementalKeyTimestamp, kind)
+	kind, _ = incrementalNameHint("id")
+	assert.Equal(t, IncrementalKeySequence, kind)
+	kind, score := incrementalNameHint("amount")
+	assert.Empty(t, kind)
+	assert.Equal(t, 0.2, score)
+}
@@ -17,6 +17,7 @@ import (
 	"datahub-service/service/datasource"
 	"datahub-service/service/interface_executor"
 	"datahub-service/service/models"
+	"datahub-service/service/tenant"
 	"encoding/csv"
 	"encoding/json"
 	"errors"
@@ -110,15 +111,29 @@ func (s *InterfaceService) CreateDataInterface(interfaceData *models.DataInterfa
 
 		// 如果有字段配置，创建数据表
 		if len(fields) > 0 {
-			schemaName := library.NameEn
+			schemaName := library.GetSchemaName()
 			tableName := interfaceData.NameEn
 
+			if config := models.ParseTimeSeriesConfig(interfaceData.InterfaceConfig); config != nil {
+				if err := validateTimeSeriesConfig(fields, config); err != nil {
+					tx.Rollback()
+					return err
+				}
+			}
+
 			// 创建数据表
 			if err := s.schemaService.ManageTableSchema(interfaceData.ID, "create_table", schemaName, tableName, fields); err != nil {
 				tx.Rollback()
 				return fmt.Errorf("创建数据表失败: %w", err)
 			}
 
+			// 时间序列接口转换为超表
+			if err := createTimeSeriesStorage(s.db, schemaName, tableName, interfaceData.InterfaceConfig); err != nil {
+				tx.Rollback()
+				s.schemaService.ManageTableSchema(interfaceData.ID, "drop_table", schemaName, tableName, []models.TableField{})
+				return err
+			}
+
 			// 更新表创建状态
 			if err := tx.Model(&models.DataInterface{}).Where("id = ?", interfaceData.ID).Update("is_table_created", true).Error; err != nil {
 				tx.Rollback()
@@ -135,8 +150,8 @@ func (s *InterfaceService) CreateDataInterface(interfaceData *models.DataInterfa
 	return tx.Commit().Error
 }
 
-// UpdateDataInterface 更新数据接口
-func (s *InterfaceService) UpdateDataInterface(id string, updates map[string]interface{}) error {
+// UpdateDataInterface 更新数据接口，expectedVersion为0时不校验版本
+func (s *InterfaceService) UpdateDataInterface(id string, expectedVersion int64, updates map[string]interface{}) error {
 	// 检查是否存在
 	var interfaceData models.DataInterface
 	if err := s.db.First(&interfaceData, "id = ?", id).Error; err != nil {
@@ -151,7 +166,7 @@ func (s *InterfaceService) UpdateDataInterface(id string, updates map[string]int
 		}
 	}
 
-	return s.db.Model(&interfaceData).Updates(updates).Error
+	return models.UpdateWithRowVersion(s.db, &models.DataInterface{}, id, expectedVersion, updates)
 }
 
 // DeleteDataInterface 删除数据接口
@@ -188,16 +203,38 @@ func (s *InterfaceService) DeleteDataInterface(interfaceData *models.DataInterfa
 		return fmt.Errorf("删除接口状态记录失败: %w", err)
 	}
 
-	// 4. 删除表结构（如果表已创建）
+	// 4. 删除数据新鲜度记录和数据契约
+	if err := tx.Where("interface_id = ?", interfaceData.ID).Delete(&models.InterfaceFreshness{}).Error; err != nil {
+		tx.Rollback()
+		return fmt.Errorf("删除数据新鲜度记录失败: %w", err)
+	}
+	if err := tx.Where("interface_id = ?", interfaceData.ID).Delete(&models.InterfaceContract{}).Error; err != nil {
+		tx.Rollback()
+		return fmt.Errorf("删除数据契约失败: %w", err)
+	}
+
+	// 5. 删除接口作为源或汇总接口的降采样任务
+	if err := deleteInterfaceRollups(tx, s.schemaService, interfaceData); err != nil {
+		tx.Rollback()
+		return fmt.Errorf("删除降采样任务失败: %w", err)
+	}
+
+	// 6. 删除数据值告警规则和告警记录
+	if err := deleteInterfaceValueAlerts(tx, interfaceData.ID); err != nil {
+		tx.Rollback()
+		return fmt.Errorf("删除数据值告警规则失败: %w", err)
+	}
+
+	// 7. 删除表结构（如果表已创建）
 	if interfaceData.IsTableCreated {
-		err := s.schemaService.ManageTableSchema(interfaceData.ID, "drop_table", interfaceData.BasicLibrary.NameEn, interfaceData.NameEn, []models.TableField{})
+		err := s.schemaService.ManageTableSchema(interfaceData.ID, "drop_table", interfaceData.BasicLibrary.GetSchemaName(), interfaceData.NameEn, []models.TableField{})
 		if err != nil {
 			tx.Rollback()
 			return fmt.Errorf("删除表结构失败: %w", err)
 		}
 	}
 
-	// 5. 删除接口记录本身
+	// 8. 删除接口记录本身
 	if err := tx.Delete(interfaceData).Error; err != nil {
 		tx.Rollback()
 		return fmt.Errorf("删除接口记录失败: %w", err)
@@ -248,7 +285,7 @@ func (s *InterfaceService) GetDataInterfaceWithSync(id string) (*models.DataInte
 
 // syncTableFieldsConfig 同步表字段配置
 func (s *InterfaceService) syncTableFieldsConfig(interfaceData *models.DataInterface) (bool, error) {
-	schemaName := interfaceData.BasicLibrary.NameEn
+	schemaName := interfaceData.BasicLibrary.GetSchemaName()
 	tableName := interfaceData.NameEn
 
 	// 检查表是否存在
@@ -299,6 +336,7 @@ func (s *InterfaceService) syncTableFieldsConfig(interfaceData *models.DataInter
 	updates := map[string]interface{}{
 		"table_fields_config": fieldsData,
 		"updated_at":          time.Now(),
+		"row_version":         gorm.Expr("row_version + 1"),
 	}
 
 	if err := s.db.Model(&models.DataInterface{}).Where("id = ?", interfaceData.ID).Updates(updates).Error; err != nil {
@@ -348,6 +386,7 @@ func (s *InterfaceService) mergeFieldsWithConfig(actualColumns []database.Column
 			IsNullable:   col.IsNullable,
 			Description:  col.Comment,
 			OrderNum:     col.OrdinalPosition,
+			Expression:   col.GenerationExpression,
 		}
 
 		// 处理默认值
@@ -359,7 +398,7 @@ func (s *InterfaceService) mergeFieldsWithConfig(actualColumns []database.Column
 
 		// 如果配置中存在该字段，合并配置信息
 		if existingField, exists := existingFieldMap[col.Name]; exists {
-			// 保留原有的 OrderNum、NameZh（如果更有意义）、IsIncrementField 等配置
+			// 保留原有的 OrderNum、NameZh（如果更有意义）、IsIncrementField、索引标记、派生列表达式、敏感标记等配置
 			if existingField.OrderNum > 0 {
 				field.OrderNum = existingField.OrderNum
 			}
@@ -367,6 +406,13 @@ func (s *InterfaceService) mergeFieldsWithConfig(actualColumns []database.Column
 				field.NameZh = existingField.NameZh
 			}
 			field.IsIncrementField = existingField.IsIncrementField
+			field.IsIndexed = existingField.IsIndexed
+			field.CompositeIndex = existingField.CompositeIndex
+			field.IsSensitive = existingField.IsSensitive
+			if existingField.IsComputed() {
+				field.Expression = existingField.Expression
+				field.ComputeMode = existingField.ComputeMode
+			}
 
 			// 如果配置中的描述更详细，使用配置中的
 			if existingField.Description != "" && len(existingField.Description) > len(col.Comment) {
@@ -409,6 +455,7 @@ func (s *InterfaceService) convertColumnsToTableFields(columns []database.Column
 			IsNullable:   col.IsNullable,
 			Description:  col.Comment,
 			OrderNum:     col.OrdinalPosition,
+			Expression:   col.GenerationExpression,
 		}
 
 		// 处理默认值
@@ -867,23 +914,84 @@ func (s *InterfaceService) updateInterfaceStatus(interfaceID, status string, las
 func (s *InterfaceService) UpdateInterfaceFields(interfaceID string, fields []models.TableField, updateTable bool) error {
 	// 验证和修正字段配置
 	fields = s.validateAndFixFields(fields)
+	if err := database.ValidateComputedFields(fields); err != nil {
+		return err
+	}
 
 	// 获取接口信息
 	interfaceData, err := s.GetDataInterface(interfaceID)
 	if err != nil {
 		return fmt.Errorf("获取接口信息失败: %w", err)
 	}
-	schemaName := interfaceData.BasicLibrary.NameEn
+	schemaName := interfaceData.BasicLibrary.GetSchemaName()
 	tableName := interfaceData.NameEn
 
 	if schemaName == "" || tableName == "" {
 		return fmt.Errorf("接口信息中没有找到库名或表名")
 	}
+	if models.ParseSCDConfig(interfaceData.InterfaceConfig) != nil {
+		// 历史追踪的系统列和代理键主键由历史追踪配置维护
+		systemColumns := 0
+		for _, field := range fields {
+			if models.IsSCDColumn(field.NameEn) {
+				systemColumns++
+			}
+		}
+		if systemColumns != len(models.SCDSystemFields(0)) {
+			return fmt.Errorf("接口已开启历史追踪，字段配置须保留%s开头的系统列，如需调整请先关闭历史追踪", models.SCDColumnPrefix)
+		}
+	}
+	if deletes := models.ParseDeletePropagationConfig(interfaceData.InterfaceConfig); deletes != nil && deletes.Action == models.DeleteActionSoftDelete {
+		// 软删除列由删除同步维护
+		hasColumn := false
+		for _, field := range fields {
+			hasColumn = hasColumn || field.NameEn == models.SoftDeleteColumn
+		}
+		if !hasColumn {
+			return fmt.Errorf("接口已开启软删除方式的删除同步，字段配置须保留%s列", models.SoftDeleteColumn)
+		}
+	}
+	if models.ParseProvenanceConfig(interfaceData.InterfaceConfig) != nil {
+		// 来源追溯系统列由同步填写
+		systemColumns := 0
+		for _, field := range fields {
+			if models.IsProvenanceColumn(field.NameEn) {
+				systemColumns++
+			}
+		}
+		if systemColumns != len(models.ProvenanceFields(0)) {
+			return fmt.Errorf("接口已开启来源追溯，字段配置须保留%s、%s、%s列，如需调整请先关闭来源追溯",
+				models.ProvenanceSourceColumn, models.ProvenanceExecutionColumn, models.ProvenanceIngestedAtColumn)
+		}
+	}
+	if config := models.ParseTimeSeriesConfig(interfaceData.InterfaceConfig); config != nil {
+		// 超表的时间列和标签列由时间序列存储配置维护
+		if err := validateTimeSeriesConfig(fields, config); err != nil {
+			return err
+		}
+		if interfaceData.IsTableCreated {
+			oldType := ""
+			for _, field := range sortedTableFields(interfaceData.TableFieldsConfig) {
+				if field.NameEn == config.TimeColumn {
+					oldType = field.DataType
+				}
+			}
+			for _, field := range fields {
+				if field.NameEn == config.TimeColumn && oldType != "" && !strings.EqualFold(field.DataType, oldType) {
+					return fmt.Errorf("接口已开启时间序列存储，不能修改时间列%s的类型", field.NameEn)
+				}
+			}
+		}
+	}
 	if !interfaceData.IsTableCreated {
 		err := s.schemaService.ManageTableSchema(interfaceID, "create_table", schemaName, tableName, fields)
 		if err != nil {
 			return fmt.Errorf("创建表结构失败: %w", err)
 		}
+		if err := createTimeSeriesStorage(s.db, schemaName, tableName, interfaceData.InterfaceConfig); err != nil {
+			s.schemaService.ManageTableSchema(interfaceID, "drop_table", schemaName, tableName, []models.TableField{})
+			return err
+		}
 		interfaceData.IsTableCreated = true
 		if err := s.db.Model(&interfaceData).Where("id = ?", interfaceID).Updates(map[string]interface{}{"is_table_created": true}).Error; err != nil {
 			s.schemaService.ManageTableSchema(interfaceID, "drop_table", schemaName, tableName, []models.TableField{})
@@ -901,6 +1009,7 @@ func (s *InterfaceService) UpdateInterfaceFields(interfaceID string, fields []mo
 	updates := map[string]interface{}{
 		"table_fields_config": fieldsData,
 		"updated_at":          time.Now(),
+		"row_version":         gorm.Expr("row_version + 1"),
 	}
 
 	if err := s.db.Model(&models.DataInterface{}).Where("id = ?", interfaceID).Updates(updates).Error; err != nil {
@@ -912,7 +1021,7 @@ func (s *InterfaceService) UpdateInterfaceFields(interfaceID string, fields []mo
 
 	// 如果需要更新表结构
 	if updateTable && interfaceData.IsTableCreated {
-		schemaName := interfaceData.BasicLibrary.NameEn
+		schemaName := interfaceData.BasicLibrary.GetSchemaName()
 		tableName := interfaceData.NameEn
 
 		// 调用SchemaService更新表结构
@@ -960,6 +1069,25 @@ func (s *InterfaceService) parseTableFields(tableFieldsConfig models.JSONB) ([]m
 						field.IsPrimaryKey = isPrimaryKey
 					}
 
+					// 解析是否唯一、是否建索引和组合索引分组
+					if isUnique, ok := fieldMap["is_unique"].(bool); ok {
+						field.IsUnique = isUnique
+					}
+					if isIndexed, ok := fieldMap["is_indexed"].(bool); ok {
+						field.IsIndexed = isIndexed
+					}
+					if compositeIndex, ok := fieldMap["composite_index"].(string); ok {
+						field.CompositeIndex = compositeIndex
+					}
+
+					// 解析派生列表达式和计算方式
+					if expression, ok := fieldMap["expression"].(string); ok {
+						field.Expression = expression
+					}
+					if computeMode, ok := fieldMap["compute_mode"].(string); ok {
+						field.ComputeMode = computeMode
+					}
+
 					// 解析是否可为空
 					if isNullable, ok := fieldMap["is_nullable"].(bool); ok {
 						field.IsNullable = isNullable
@@ -1288,3 +1416,58 @@ func (s *InterfaceService) convertCSVValue(value string, field models.TableField
 		return value, nil
 	}
 }
+
+// getTenantInterface 获取当前租户可见的接口及其所属基础库
+func getTenantInterface(ctx context.Context, db *gorm.DB, interfaceID string) (*models.DataInterface, error) {
+	var iface models.DataInterface
+	err := db.WithContext(ctx).Preload("BasicLibrary").
+		Scopes(tenant.OwnerScope(ctx, "library_id", "basic_libraries")).
+		First(&iface, "id = ?", interfaceID).Error
+	if err != nil {
+		return nil, err
+	}
+	return &iface, nil
+}
+
+// checkInterfaceVersion 校验调用方携带的期望版本与读取到的接口版本一致，期望版本为0时不校验
+func checkInterfaceVersion(iface *models.DataInterface, expectedVersion int64) error {
+	if expectedVersion > 0 && expectedVersion != iface.RowVersion {
+		return &models.VersionConflictError{Expected: expectedVersion, Current: iface.RowVersion}
+	}
+	return nil
+}
+
+// saveInterfaceConfig 保存接口配置列(interface_config/parse_config)中的一项，value为nil时移除该项，fields不为nil时同时保存字段配置。
+// 按读取接口时的版本条件更新，读取后接口被其他请求修改时返回版本冲突
+func saveInterfaceConfig(ctx context.Context, db *gorm.DB, iface *models.DataInterface, column, key string, value interface{}, fields []models.TableField) error {
+	current := iface.InterfaceConfig
+	if column == "parse_config" {
+		current = iface.ParseConfig
+	}
+	config := models.JSONB{}
+	for k, v := range current {
+		config[k] = v
+	}
+	if value != nil {
+		config[key] = value
+	} else {
+		delete(config, key)
+	}
+
+	updates := map[string]interface{}{
+		column:       config,
+		"updated_at": time.Now(),
+	}
+	if fields != nil {
+		fieldsData := make(models.JSONB)
+		for i, field := range fields {
+			fieldsData[fmt.Sprintf("field_%d", i)] = field
+		}
+		updates["table_fields_config"] = fieldsData
+	}
+	if err := models.UpdateWithRowVersion(db.WithContext(ctx), &models.DataInterface{}, iface.ID, iface.RowVersion, updates); err != nil {
+		return err
+	}
+	iface.RowVersion++
+	return nil
+}
@@ -0,0 +1,52 @@
+/*
+ * @module service/basic_library/payload_schema
+ * @description 接口JSON Schema服务，根据接口表字段配置生成JSON Schema，并按Schema校验源数据，返回结构化的违规清单
+ * @architecture 分层架构 - 业务服务层
+ * @documentReference ai_docs/interfaces.md
+ * @stateFlow 加载接口 -> 表字段配置生成JSON Schema -> (校验时)按parseConfig字段映射源数据 -> 逐行校验 -> 违规清单
+ * @rules 校验使用与同步写入相同的字段映射，传入的是源数据；接口未配置表字段时无法生成Schema
+ * @dependencies datahub-service/service/interface_executor, datahub-service/service/utils
+ * @refs service/utils/json_schema.go, service/interface_executor/payload_schema.go
+ */
+
+package basic_library
+
+import (
+	"datahub-service/service/interface_executor"
+	"datahub-service/service/models"
+	"datahub-service/service/utils"
+	"fmt"
+)
+
+// GetInterfaceJSONSchema 生成接口数据的JSON Schema
+func (s *InterfaceService) GetInterfaceJSONSchema(interfaceID string) (*utils.JSONSchema, error) {
+	interfaceData, err := s.GetDataInterface(interfaceID)
+	if err != nil {
+		return nil, err
+	}
+	if err := requireTableFields(interfaceData); err != nil {
+		return nil, err
+	}
+	return interface_executor.InterfaceJSONSchema(&interface_executor.BasicLibraryInterfaceInfo{DataInterface: interfaceData}), nil
+}
+
+// ValidateInterfacePayload 按接口的JSON Schema校验源数据
+func (s *InterfaceService) ValidateInterfacePayload(interfaceID string, data []map[string]interface{}) (*utils.PayloadValidationResult, error) {
+	interfaceData, err := s.GetDataInterface(interfaceID)
+	if err != nil {
+		return nil, err
+	}
+	if err := requireTableFields(interfaceData); err != nil {
+		return nil, err
+	}
+	info := &interface_executor.BasicLibraryInterfaceInfo{DataInterface: interfaceData}
+	return interface_executor.NewFieldMapper().ValidatePayload(info, data), nil
+}
+
+// requireTableFields 接口未配置表字段时无法生成Schema
+func requireTableFields(interfaceData *models.DataInterface) error {
+	if len(interfaceData.TableFieldsConfig) == 0 {
+		return fmt.Errorf("接口未配置表字段，无法生成JSON Schema")
+	}
+	return nil
+}
@@ -0,0 +1,213 @@
+/*
+ * @module service/basic_library/protobuf_parse_service
+ * @description 接口Protobuf解析配置服务，保存上传的.proto描述、消息类型、记录路径和字段路径，并可用样例消息试解码
+ * @architecture 分层架构 - 业务服务层
+ * @documentReference ai_docs/datasource_req1.md
+ * @stateFlow 保存：校验数据源类型 -> 编译.proto并校验路径 -> 写入解析配置的protobuf -> 重新注册到实时处理器；试解码：按已保存的配置解码 -> 字段映射
+ * @rules 只有MQTT和HTTP POST数据源的接口支持；按接口版本条件保存配置，版本不一致返回冲突；更新时未上传.proto则沿用已保存的文件；保存和删除后立即重新注册到实时处理器，使新配置生效；
+ *        试解码最多返回100行
+ * @dependencies datahub-service/service/datasource, datahub-service/service/interface_executor, datahub-service/service/models, gorm.io/gorm
+ * @refs service/models/protobuf_parse.go, service/datasource/protobuf_rows.go, api/controllers/protobuf_parse_controller.go
+ */
+
+package basic_library
+
+import (
+	"context"
+	"datahub-service/service/datasource"
+	"datahub-service/service/interface_executor"
+	"datahub-service/service/meta"
+	"datahub-service/service/models"
+	"datahub-service/service/tenant"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ErrInvalidProtobufParseRequest Protobuf解析配置不合法
+var ErrInvalidProtobufParseRequest = errors.New("Protobuf解析配置不合法")
+
+// maxProtobufPreviewRows 试解码返回的最多行数
+const maxProtobufPreviewRows = 100
+
+// ProtobufParseRequest 保存Protobuf解析配置的请求
+type ProtobufParseRequest struct {
+	FileName    string
+	ProtoFile   string // 为空时沿用已保存的.proto
+	MessageType string
+	RecordsPath string
+	FieldPaths  map[string]string
+}
+
+// ProtobufParseConfigView 接口的Protobuf解析配置
+type ProtobufParseConfigView struct {
+	Configured   bool                        `json:"configured"`
+	Config       *models.ProtobufParseConfig `json:"config,omitempty"`
+	MessageType  string                      `json:"message_type,omitempty"`  // 实际使用的消息全名
+	MessageTypes []string                    `json:"message_types,omitempty"` // .proto中定义的全部消息
+	Error        string                      `json:"error,omitempty"`         // 已保存的配置无法编译时的原因
+}
+
+// ProtobufDecodeResult 试解码结果
+type ProtobufDecodeResult struct {
+	Total      int                      `json:"total"`       // 解码出的行数
+	Rows       []map[string]interface{} `json:"rows"`        // 按字段路径取值后的行
+	MappedRows []map[string]interface{} `json:"mapped_rows"` // 再经过解析配置字段映射后的行
+}
+
+// ProtobufParseService 接口Protobuf解析配置服务
+type ProtobufParseService struct {
+	db          *gorm.DB
+	fieldMapper *interface_executor.FieldMapper
+}
+
+// NewProtobufParseService 创建接口Protobuf解析配置服务
+func NewProtobufParseService(db *gorm.DB) *ProtobufParseService {
+	return &ProtobufParseService{db: db, fieldMapper: interface_executor.NewFieldMapper()}
+}
+
+// GetConfig 获取接口的Protobuf解析配置，未配置时configured为false
+func (s *ProtobufParseService) GetConfig(ctx context.Context, interfaceID string) (*ProtobufParseConfigView, error) {
+	iface, err := s.getInterface(ctx, interfaceID)
+	if err != nil {
+		return nil, err
+	}
+	config := models.ParseProtobufParseConfig(iface.ParseConfig)
+	if config == nil {
+		return &ProtobufParseConfigView{}, nil
+	}
+	view := &ProtobufParseConfigView{Configured: true, Config: config}
+	decoder, err := datasource.NewProtobufRowDecoder(config)
+	if err != nil {
+		view.Error = err.Error()
+		return view, nil
+	}
+	view.MessageType = decoder.MessageType()
+	view.MessageTypes = decoder.MessageTypes()
+	return view, nil
+}
+
+// SaveConfig 保存接口的Protobuf解析配置；expectedVersion不为0时须与接口当前版本一致
+func (s *ProtobufParseService) SaveConfig(ctx context.Context, interfaceID string, expectedVersion int64, username string, req *ProtobufParseRequest) (*ProtobufParseConfigView, error) {
+	iface, err := s.getInterface(ctx, interfaceID)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkInterfaceVersion(iface, expectedVersion); err != nil {
+		return nil, err
+	}
+	if iface.DataSource.Type != meta.DataSourceTypeMessagingMQTT && iface.DataSource.Type != meta.DataSourceTypeMessagingHttpPost {
+		return nil, fmt.Errorf("%w: 只有MQTT和HTTP POST数据源的接口支持Protobuf解析", ErrInvalidProtobufParseRequest)
+	}
+
+	config := &models.ProtobufParseConfig{
+		FileName:    req.FileName,
+		ProtoFile:   req.ProtoFile,
+		MessageType: req.MessageType,
+		RecordsPath: req.RecordsPath,
+		FieldPaths:  req.FieldPaths,
+		UpdatedBy:   username,
+		UpdatedAt:   time.Now(),
+	}
+	if config.ProtoFile == "" {
+		existing := models.ParseProtobufParseConfig(iface.ParseConfig)
+		if existing == nil {
+			return nil, fmt.Errorf("%w: 缺少.proto文件", ErrInvalidProtobufParseRequest)
+		}
+		config.FileName, config.ProtoFile = existing.FileName, existing.ProtoFile
+	}
+	decoder, err := datasource.NewProtobufRowDecoder(config)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidProtobufParseRequest, err)
+	}
+
+	if err := saveInterfaceConfig(ctx, s.db, iface, "parse_config", models.ProtobufParseConfigKey, config, nil); err != nil {
+		return nil, fmt.Errorf("保存Protobuf解析配置失败: %w", err)
+	}
+	s.refreshRealtime(ctx, iface)
+
+	slog.Info("保存接口Protobuf解析配置", "interface_id", interfaceID, "message_type", decoder.MessageType(), "username", username)
+	return &ProtobufParseConfigView{
+		Configured:   true,
+		Config:       config,
+		MessageType:  decoder.MessageType(),
+		MessageTypes: decoder.MessageTypes(),
+	}, nil
+}
+
+// DeleteConfig 删除接口的Protobuf解析配置，之后接口重新接收JSON数据；expectedVersion不为0时须与接口当前版本一致
+func (s *ProtobufParseService) DeleteConfig(ctx context.Context, interfaceID string, expectedVersion int64, username string) error {
+	iface, err := s.getInterface(ctx, interfaceID)
+	if err != nil {
+		return err
+	}
+	if err := checkInterfaceVersion(iface, expectedVersion); err != nil {
+		return err
+	}
+	if models.ParseProtobufParseConfig(iface.ParseConfig) == nil {
+		return fmt.Errorf("%w: 接口未配置Protobuf解析", ErrInvalidProtobufParseRequest)
+	}
+	if err := saveInterfaceConfig(ctx, s.db, iface, "parse_config", models.ProtobufParseConfigKey, nil, nil); err != nil {
+		return fmt.Errorf("保存Protobuf解析配置失败: %w", err)
+	}
+	s.refreshRealtime(ctx, iface)
+
+	slog.Info("删除接口Protobuf解析配置", "interface_id", interfaceID, "username", username)
+	return nil
+}
+
+// Decode 按接口已保存的配置试解码一条消息，不写入接口表
+func (s *ProtobufParseService) Decode(ctx context.Context, interfaceID string, payload []byte) (*ProtobufDecodeResult, error) {
+	iface, err := s.getInterface(ctx, interfaceID)
+	if err != nil {
+		return nil, err
+	}
+	config := models.ParseProtobufParseConfig(iface.ParseConfig)
+	if config == nil {
+		return nil, fmt.Errorf("%w: 接口未配置Protobuf解析", ErrInvalidProtobufParseRequest)
+	}
+	decoder, err := datasource.NewProtobufRowDecoder(config)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidProtobufParseRequest, err)
+	}
+	rows, err := decoder.Decode(payload)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidProtobufParseRequest, err)
+	}
+
+	result := &ProtobufDecodeResult{Total: len(rows)}
+	if len(rows) > maxProtobufPreviewRows {
+		rows = rows[:maxProtobufPreviewRows]
+	}
+	result.Rows = rows
+	result.MappedRows = make([]map[string]interface{}, len(rows))
+	for i, row := range rows {
+		result.MappedRows[i] = s.fieldMapper.ApplyFieldMapping(row, iface.ParseConfig)
+	}
+	return result, nil
+}
+
+// getInterface 获取当前租户可见的接口及其数据源
+func (s *ProtobufParseService) getInterface(ctx context.Context, interfaceID string) (*models.DataInterface, error) {
+	var iface models.DataInterface
+	err := s.db.WithContext(ctx).Preload("DataSource").
+		Scopes(tenant.OwnerScope(ctx, "library_id", "basic_libraries")).
+		First(&iface, "id = ?", interfaceID).Error
+	if err != nil {
+		return nil, err
+	}
+	return &iface, nil
+}
+
+// refreshRealtime 重新注册到实时处理器，使新配置立即生效
+func (s *ProtobufParseService) refreshRealtime(ctx context.Context, iface *models.DataInterface) {
+	if !iface.IsTableCreated || iface.DataSourceID == "" {
+		return
+	}
+	if err := datasource.GetGlobalRealtimeProcessor().RegisterInterface(ctx, iface.ID, iface.DataSourceID); err != nil {
+		slog.Warn("接口重新注册到实时处理器失败", "interface_id", iface.ID, "error", err)
+	}
+}
@@ -0,0 +1,138 @@
+/*
+ * @module service/basic_library/provenance_service
+ * @description 接口记录级来源追溯配置服务，开启时为接口表增加来源数据源、同步执行ID和入库时间三个系统列，关闭时删除这些列
+ * @architecture 分层架构 - 业务服务层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow 开启：字段配置追加系统列并修改表结构 -> 保存配置；关闭：字段配置去掉系统列并修改表结构 -> 移除配置
+ * @rules 接口表须已创建；按接口版本条件保存配置，版本不一致返回冲突；开启前写入的行系统列为空，下次同步写入后填写；关闭时删除系统列，已记录的来源信息不保留
+ * @dependencies datahub-service/service/database, datahub-service/service/models, gorm.io/gorm
+ * @refs service/models/provenance.go, service/interface_executor/provenance.go, api/controllers/provenance_controller.go
+ */
+
+package basic_library
+
+import (
+	"context"
+	"datahub-service/service/database"
+	"datahub-service/service/models"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"gorm.io/gorm"
+)
+
+// ErrInvalidProvenanceRequest 来源追溯配置不合法
+var ErrInvalidProvenanceRequest = errors.New("来源追溯配置不合法")
+
+// ProvenanceService 接口记录级来源追溯配置服务
+type ProvenanceService struct {
+	db            *gorm.DB
+	schemaService *database.SchemaService
+}
+
+// NewProvenanceService 创建接口记录级来源追溯配置服务
+func NewProvenanceService(db *gorm.DB) *ProvenanceService {
+	return &ProvenanceService{db: db, schemaService: database.NewSchemaService(db)}
+}
+
+// GetConfig 获取接口的来源追溯配置，未开启时返回enabled=false
+func (s *ProvenanceService) GetConfig(ctx context.Context, interfaceID string) (*models.ProvenanceConfig, error) {
+	iface, err := getTenantInterface(ctx, s.db, interfaceID)
+	if err != nil {
+		return nil, err
+	}
+	if config := models.ParseProvenanceConfig(iface.InterfaceConfig); config != nil {
+		return config, nil
+	}
+	return &models.ProvenanceConfig{}, nil
+}
+
+// EnableConfig 开启接口的来源追溯，接口表没有系统列时增加；expectedVersion不为0时须与接口当前版本一致
+func (s *ProvenanceService) EnableConfig(ctx context.Context, interfaceID string, expectedVersion int64, username string) (*models.ProvenanceConfig, error) {
+	iface, err := getTenantInterface(ctx, s.db, interfaceID)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkInterfaceVersion(iface, expectedVersion); err != nil {
+		return nil, err
+	}
+	if !iface.IsTableCreated {
+		return nil, fmt.Errorf("%w: 接口表尚未创建", ErrInvalidProvenanceRequest)
+	}
+
+	fields := sortedTableFields(iface.TableFieldsConfig)
+	newFields := applyProvenanceFields(fields)
+	if newFields != nil {
+		if err := s.schemaService.ManageTableSchema(interfaceID, "alter_table", iface.BasicLibrary.GetSchemaName(), iface.NameEn, newFields); err != nil {
+			return nil, fmt.Errorf("修改表结构失败: %w", err)
+		}
+	}
+	config := &models.ProvenanceConfig{Enabled: true}
+	if err := saveInterfaceConfig(ctx, s.db, iface, "interface_config", models.ProvenanceConfigKey, config, newFields); err != nil {
+		return nil, fmt.Errorf("保存来源追溯配置失败: %w", err)
+	}
+
+	slog.Info("开启接口来源追溯", "interface_id", interfaceID, "username", username)
+	return config, nil
+}
+
+// DisableConfig 关闭接口的来源追溯并删除系统列；expectedVersion不为0时须与接口当前版本一致
+func (s *ProvenanceService) DisableConfig(ctx context.Context, interfaceID string, expectedVersion int64, username string) error {
+	iface, err := getTenantInterface(ctx, s.db, interfaceID)
+	if err != nil {
+		return err
+	}
+	if err := checkInterfaceVersion(iface, expectedVersion); err != nil {
+		return err
+	}
+	if models.ParseProvenanceConfig(iface.InterfaceConfig) == nil {
+		return fmt.Errorf("%w: 接口未开启来源追溯", ErrInvalidProvenanceRequest)
+	}
+
+	newFields := removeProvenanceFields(sortedTableFields(iface.TableFieldsConfig))
+	if iface.IsTableCreated {
+		if err := s.schemaService.ManageTableSchema(interfaceID, "alter_table", iface.BasicLibrary.GetSchemaName(), iface.NameEn, newFields); err != nil {
+			return fmt.Errorf("修改表结构失败: %w", err)
+		}
+	}
+	if err := saveInterfaceConfig(ctx, s.db, iface, "interface_config", models.ProvenanceConfigKey, nil, newFields); err != nil {
+		return fmt.Errorf("保存来源追溯配置失败: %w", err)
+	}
+
+	slog.Info("关闭接口来源追溯", "interface_id", interfaceID, "username", username)
+	return nil
+}
+
+// applyProvenanceFields 字段配置中缺少的系统列追加到最后，都已存在时返回nil
+func applyProvenanceFields(fields []models.TableField) []models.TableField {
+	maxOrder := 0
+	existing := make(map[string]bool, len(fields))
+	for _, field := range fields {
+		existing[field.NameEn] = true
+		if field.OrderNum > maxOrder {
+			maxOrder = field.OrderNum
+		}
+	}
+	result := append([]models.TableField{}, fields...)
+	for _, field := range models.ProvenanceFields(maxOrder) {
+		if !existing[field.NameEn] {
+			result = append(result, field)
+		}
+	}
+	if len(result) == len(fields) {
+		return nil
+	}
+	return result
+}
+
+// removeProvenanceFields 生成关闭来源追溯后的字段配置：去掉系统列
+func removeProvenanceFields(fields []models.TableField) []models.TableField {
+	result := make([]models.TableField, 0, len(fields))
+	for _, field := range fields {
+		if !models.IsProvenanceColumn(field.NameEn) {
+			result = append(result, field)
+		}
+	}
+	return result
+}
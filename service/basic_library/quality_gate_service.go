@@ -0,0 +1,366 @@
+/*
+ * @module service/basic_library/quality_gate_service
+ * @description 同步任务质量门禁服务，管理门禁配置，在同步写入后执行接口上的质量检测并判定是否通过，未通过时阻断接口并告警
+ * @architecture 分层架构 - 业务服务层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow 同步写入完成 -> 执行写入接口上启用的质量检测任务 -> 汇总评分和规则结果 -> 通过：解除接口阻断；未通过：执行失败、阻断接口、记录quality_gate_failed事件
+ * @rules 只检查本次写入成功的接口；门禁开启但没有可执行的质量检测时按未通过处理；综合评分按全部检测项加权(0-100)；
+ *        必须通过的规则须在本次检测中执行且没有不通过的记录；人工解除阻断只影响下游，不改变执行结果
+ * @dependencies datahub-service/service/governance, datahub-service/service/models, gorm.io/gorm
+ * @refs service/models/quality_gate.go, sync_task_service.go, api/controllers/quality_gate_controller.go
+ */
+
+package basic_library
+
+import (
+	"context"
+	"datahub-service/service/eventlog"
+	"datahub-service/service/governance"
+	"datahub-service/service/models"
+	"datahub-service/service/tenant"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrInvalidQualityGate 质量门禁配置不合法
+var ErrInvalidQualityGate = errors.New("质量门禁配置不合法")
+
+// qualityGateTriggerSource 门禁触发的质量检测执行记录的触发来源
+const qualityGateTriggerSource = "sync_quality_gate"
+
+// QualityTaskRunner 同步执行质量检测任务
+type QualityTaskRunner interface {
+	RunQualityTaskNow(ctx context.Context, taskID, triggerSource string) (*governance.QualityTaskRunResult, error)
+}
+
+// QualityGateResult 一次同步执行的质量门禁结果
+type QualityGateResult struct {
+	Passed        bool                               `json:"passed"`
+	Score         *float64                           `json:"score,omitempty"` // 综合评分(0-100)，没有执行检测时为空
+	MinScore      *float64                           `json:"min_score,omitempty"`
+	InterfaceIDs  []string                           `json:"interface_ids"` // 检查的接口
+	QualityRuns   []*governance.QualityTaskRunResult `json:"quality_runs"`
+	RequiredRules []QualityGateRuleResult            `json:"required_rules,omitempty"`
+	Reasons       []string                           `json:"reasons,omitempty"` // 未通过的原因
+	CheckedAt     time.Time                          `json:"checked_at"`
+}
+
+// QualityGateRuleResult 必须通过的规则的检测结果
+type QualityGateRuleResult struct {
+	RuleID    string `json:"rule_id"`
+	FieldName string `json:"field_name,omitempty"`
+	Executed  bool   `json:"executed"`
+	Failures  int64  `json:"failures"`
+}
+
+// QualityGateService 同步任务质量门禁服务
+type QualityGateService struct {
+	db     *gorm.DB
+	runner QualityTaskRunner
+}
+
+// NewQualityGateService 创建同步任务质量门禁服务
+func NewQualityGateService(db *gorm.DB, runner QualityTaskRunner) *QualityGateService {
+	return &QualityGateService{db: db, runner: runner}
+}
+
+// GetConfig 获取同步任务的质量门禁配置，未开启时返回enabled=false
+func (s *QualityGateService) GetConfig(ctx context.Context, taskID string) (*models.QualityGateConfig, error) {
+	var task models.SyncTask
+	if err := s.db.WithContext(ctx).First(&task, "id = ?", taskID).Error; err != nil {
+		return nil, err
+	}
+	if gate := models.ParseQualityGateConfig(task.Config); gate != nil {
+		return gate, nil
+	}
+	return &models.QualityGateConfig{RequiredRules: []string{}}, nil
+}
+
+// UpdateConfig 开启或修改同步任务的质量门禁
+func (s *QualityGateService) UpdateConfig(ctx context.Context, taskID string, gate *models.QualityGateConfig, username string) (*models.QualityGateConfig, error) {
+	var task models.SyncTask
+	if err := s.db.WithContext(ctx).Preload("TaskInterfaces").First(&task, "id = ?", taskID).Error; err != nil {
+		return nil, err
+	}
+	if gate.MinScore == nil && len(gate.RequiredRules) == 0 {
+		return nil, fmt.Errorf("%w: 至少需要设置最低评分或必须通过的规则", ErrInvalidQualityGate)
+	}
+	if gate.MinScore != nil && (*gate.MinScore < 0 || *gate.MinScore > 100) {
+		return nil, fmt.Errorf("%w: 最低评分须在0-100之间", ErrInvalidQualityGate)
+	}
+
+	qualityTasks, err := s.qualityTasksFor(ctx, taskInterfaceIDs(&task))
+	if err != nil {
+		return nil, err
+	}
+	if len(qualityTasks) == 0 {
+		return nil, fmt.Errorf("%w: 任务的接口上没有启用的质量检测任务", ErrInvalidQualityGate)
+	}
+	if len(gate.RequiredRules) > 0 {
+		qualityTaskIDs := make([]string, len(qualityTasks))
+		for i, qualityTask := range qualityTasks {
+			qualityTaskIDs[i] = qualityTask.ID
+		}
+		var found int64
+		if err := s.db.WithContext(ctx).Model(&models.QualityTaskFieldRule{}).
+			Where("id IN ? AND task_id IN ? AND is_enabled = ?", gate.RequiredRules, qualityTaskIDs, true).Count(&found).Error; err != nil {
+			return nil, err
+		}
+		if int(found) != len(uniqueStrings(gate.RequiredRules)) {
+			return nil, fmt.Errorf("%w: 必须通过的规则须是任务接口上启用的质量检测字段规则", ErrInvalidQualityGate)
+		}
+	}
+
+	gate.Enabled = true
+	gate.RequiredRules = uniqueStrings(gate.RequiredRules)
+	if err := s.saveConfig(ctx, &task, gate, username); err != nil {
+		return nil, err
+	}
+	slog.Info("设置同步任务质量门禁", "task_id", taskID, "min_score", gate.MinScore, "required_rules", len(gate.RequiredRules), "operator", username)
+	return gate, nil
+}
+
+// DisableConfig 关闭同步任务的质量门禁，任务写入的接口上的阻断一并解除
+func (s *QualityGateService) DisableConfig(ctx context.Context, taskID, username string) error {
+	var task models.SyncTask
+	if err := s.db.WithContext(ctx).First(&task, "id = ?", taskID).Error; err != nil {
+		return err
+	}
+	if models.ParseQualityGateConfig(task.Config) == nil {
+		return fmt.Errorf("%w: 任务未开启质量门禁", ErrInvalidQualityGate)
+	}
+	if err := s.saveConfig(ctx, &task, nil, username); err != nil {
+		return err
+	}
+	return s.db.WithContext(ctx).Where("sync_task_id = ?", taskID).Delete(&models.QualityGateBlock{}).Error
+}
+
+// ListBlocks 获取被质量门禁阻断的接口，按阻断时间倒序
+func (s *QualityGateService) ListBlocks(ctx context.Context) ([]models.QualityGateBlock, error) {
+	var blocks []models.QualityGateBlock
+	err := s.db.WithContext(ctx).Scopes(tenant.OwnerScope(ctx, "sync_task_id", "sync_tasks")).
+		Order("blocked_at DESC").Find(&blocks).Error
+	return blocks, err
+}
+
+// ReleaseBlock 人工解除接口的阻断，下游主题同步恢复执行
+func (s *QualityGateService) ReleaseBlock(ctx context.Context, interfaceID, username string) error {
+	var block models.QualityGateBlock
+	if err := s.db.WithContext(ctx).Scopes(tenant.OwnerScope(ctx, "sync_task_id", "sync_tasks")).
+		First(&block, "interface_id = ?", interfaceID).Error; err != nil {
+		return err
+	}
+	if err := s.db.WithContext(ctx).Delete(&block).Error; err != nil {
+		return err
+	}
+	eventlog.Record(ctx, eventlog.Event{
+		Type:       eventlog.EventQualityGateReleased,
+		Level:      models.EventLevelWarn,
+		Message:    "人工解除质量门禁阻断",
+		ObjectType: "data_interface",
+		ObjectID:   interfaceID,
+		Attributes: map[string]interface{}{"sync_task_id": block.SyncTaskID, "execution_id": block.ExecutionID, "released_by": username},
+	})
+	return nil
+}
+
+// Evaluate 同步写入后检查质量门禁，任务未开启门禁或没有写入成功的接口时返回nil；
+// 通过时解除这些接口的阻断，未通过时阻断这些接口并记录quality_gate_failed事件
+func (s *QualityGateService) Evaluate(ctx context.Context, task *models.SyncTask, executionID string, interfaceIDs []string) *QualityGateResult {
+	gate := models.ParseQualityGateConfig(task.Config)
+	if gate == nil || len(interfaceIDs) == 0 {
+		return nil
+	}
+
+	result := &QualityGateResult{MinScore: gate.MinScore, InterfaceIDs: interfaceIDs, QualityRuns: []*governance.QualityTaskRunResult{}, CheckedAt: time.Now()}
+	qualityTasks, err := s.qualityTasksFor(ctx, interfaceIDs)
+	if err != nil {
+		result.Reasons = append(result.Reasons, fmt.Sprintf("获取质量检测任务失败: %v", err))
+	}
+	for _, qualityTask := range qualityTasks {
+		run, err := s.runner.RunQualityTaskNow(ctx, qualityTask.ID, qualityGateTriggerSource)
+		if err != nil {
+			result.Reasons = append(result.Reasons, fmt.Sprintf("质量检测任务%s执行失败: %v", qualityTask.Name, err))
+			continue
+		}
+		result.QualityRuns = append(result.QualityRuns, run)
+	}
+
+	var rules []models.QualityTaskFieldRule
+	if len(gate.RequiredRules) > 0 {
+		if err := s.db.WithContext(ctx).Where("id IN ?", gate.RequiredRules).Find(&rules).Error; err != nil {
+			result.Reasons = append(result.Reasons, fmt.Sprintf("获取必须通过的规则失败: %v", err))
+		}
+	}
+	judgeQualityGate(gate, result, rules)
+
+	s.applyBlocks(ctx, task.ID, executionID, result)
+	if !result.Passed {
+		slog.Warn("同步任务未通过质量门禁", "task_id", task.ID, "execution_id", executionID, "reasons", result.Reasons)
+		eventlog.Record(ctx, eventlog.Event{
+			Type:       eventlog.EventQualityGateFailed,
+			Level:      models.EventLevelError,
+			Message:    "同步任务未通过质量门禁",
+			ObjectType: "sync_task",
+			ObjectID:   task.ID,
+			Attributes: map[string]interface{}{
+				"execution_id":  executionID,
+				"score":         formatGateScore(result.Score),
+				"min_score":     formatGateScore(result.MinScore),
+				"error":         strings.Join(result.Reasons, "; "),
+				"interface_ids": interfaceIDs,
+			},
+		})
+	}
+	return result
+}
+
+// judgeQualityGate 按质量检测结果判定门禁，rules为必须通过的规则
+func judgeQualityGate(gate *models.QualityGateConfig, result *QualityGateResult, rules []models.QualityTaskFieldRule) {
+	var totalChecks, failedChecks int
+	failedByRule := make(map[string]int64)
+	executedRules := make(map[string]bool)
+	for _, run := range result.QualityRuns {
+		if run.FailedByRule == nil {
+			result.Reasons = append(result.Reasons, fmt.Sprintf("质量检测执行失败: %s", run.ErrorMessage))
+			continue
+		}
+		totalChecks += run.TotalChecks
+		failedChecks += run.FailedChecks
+		for ruleID, failures := range run.FailedByRule {
+			failedByRule[ruleID] += failures
+		}
+		executedRules[run.TaskID] = true
+	}
+	if len(result.QualityRuns) == 0 && len(result.Reasons) == 0 {
+		result.Reasons = append(result.Reasons, "写入的接口上没有启用的质量检测任务")
+	}
+
+	if totalChecks > 0 {
+		score := math.Round(float64(totalChecks-failedChecks)/float64(totalChecks)*10000) / 100
+		result.Score = &score
+	}
+	if gate.MinScore != nil {
+		switch {
+		case result.Score == nil:
+			result.Reasons = append(result.Reasons, "没有可计算评分的检测记录")
+		case *result.Score < *gate.MinScore:
+			result.Reasons = append(result.Reasons, fmt.Sprintf("综合评分%.2f低于最低评分%.2f", *result.Score, *gate.MinScore))
+		}
+	}
+
+	ruleByID := make(map[string]models.QualityTaskFieldRule, len(rules))
+	for _, rule := range rules {
+		ruleByID[rule.ID] = rule
+	}
+	for _, ruleID := range gate.RequiredRules {
+		rule, known := ruleByID[ruleID]
+		ruleResult := QualityGateRuleResult{RuleID: ruleID, FieldName: rule.FieldName, Executed: known && executedRules[rule.TaskID], Failures: failedByRule[ruleID]}
+		result.RequiredRules = append(result.RequiredRules, ruleResult)
+		switch {
+		case !ruleResult.Executed:
+			result.Reasons = append(result.Reasons, fmt.Sprintf("必须通过的规则%s未执行", ruleID))
+		case ruleResult.Failures > 0:
+			result.Reasons = append(result.Reasons, fmt.Sprintf("字段%s的规则%s有%d条记录不通过", rule.FieldName, ruleID, ruleResult.Failures))
+		}
+	}
+	result.Passed = len(result.Reasons) == 0
+}
+
+// applyBlocks 门禁通过时解除接口阻断，未通过时阻断接口
+func (s *QualityGateService) applyBlocks(ctx context.Context, taskID, executionID string, result *QualityGateResult) {
+	db := s.db.WithContext(ctx)
+	var err error
+	if result.Passed {
+		err = db.Where("interface_id IN ?", result.InterfaceIDs).Delete(&models.QualityGateBlock{}).Error
+	} else {
+		blocks := make([]models.QualityGateBlock, len(result.InterfaceIDs))
+		for i, interfaceID := range result.InterfaceIDs {
+			blocks[i] = models.QualityGateBlock{
+				InterfaceID: interfaceID,
+				SyncTaskID:  taskID,
+				ExecutionID: executionID,
+				Score:       result.Score,
+				Reasons:     result.Reasons,
+				BlockedAt:   result.CheckedAt,
+			}
+		}
+		err = db.Clauses(clause.OnConflict{UpdateAll: true}).Create(&blocks).Error
+	}
+	if err != nil {
+		slog.Error("更新质量门禁阻断失败", "task_id", taskID, "execution_id", executionID, "error", err)
+	}
+}
+
+// qualityTasksFor 接口上启用的质量检测任务
+func (s *QualityGateService) qualityTasksFor(ctx context.Context, interfaceIDs []string) ([]models.QualityTask, error) {
+	var qualityTasks []models.QualityTask
+	if len(interfaceIDs) == 0 {
+		return qualityTasks, nil
+	}
+	err := s.db.WithContext(ctx).Where("interface_id IN ? AND is_enabled = ?", interfaceIDs, true).
+		Order("priority DESC").Find(&qualityTasks).Error
+	return qualityTasks, err
+}
+
+// saveConfig 保存同步任务配置中的质量门禁，gate为nil时移除
+func (s *QualityGateService) saveConfig(ctx context.Context, task *models.SyncTask, gate *models.QualityGateConfig, username string) error {
+	config := models.JSONB{}
+	for key, value := range task.Config {
+		config[key] = value
+	}
+	if gate == nil {
+		delete(config, models.QualityGateConfigKey)
+	} else {
+		raw := map[string]interface{}{"enabled": true, "required_rules": gate.RequiredRules}
+		if gate.MinScore != nil {
+			raw["min_score"] = *gate.MinScore
+		}
+		config[models.QualityGateConfigKey] = raw
+	}
+	return s.db.WithContext(ctx).Model(&models.SyncTask{}).Where("id = ?", task.ID).Updates(map[string]interface{}{
+		"config":      config,
+		"updated_by":  username,
+		"updated_at":  time.Now(),
+		"row_version": gorm.Expr("row_version + 1"),
+	}).Error
+}
+
+// taskInterfaceIDs 同步任务关联的接口ID
+func taskInterfaceIDs(task *models.SyncTask) []string {
+	ids := make([]string, 0, len(task.TaskInterfaces))
+	for _, taskInterface := range task.TaskInterfaces {
+		ids = append(ids, taskInterface.InterfaceID)
+	}
+	return ids
+}
+
+// uniqueStrings 去重并排序
+func uniqueStrings(values []string) []string {
+	seen := make(map[string]bool, len(values))
+	result := make([]string, 0, len(values))
+	for _, value := range values {
+		if value != "" && !seen[value] {
+			seen[value] = true
+			result = append(result, value)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
+
+// formatGateScore 事件中的评分，为空时显示"-"
+func formatGateScore(score *float64) string {
+	if score == nil {
+		return "-"
+	}
+	return fmt.Sprintf("%.2f", *score)
+}
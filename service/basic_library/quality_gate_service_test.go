@@ -0,0 +1,157 @@
+/*
+ * @module service/basic_library/quality_gate_service_test
+ * @description 同步任务质量门禁测试，覆盖门禁配置校验、评分和必须通过规则的判定、未通过时阻断接口并告警、再次通过后解除阻断
+ * @architecture 测试层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow 准备sqlite中的同步任务和质量检测任务 -> 设置门禁 -> 按模拟的检测结果判定 -> 验证阻断记录和应用事件
+ * @rules 使用内存sqlite，质量检测执行由模拟实现代替
+ * @dependencies gorm.io/driver/sqlite, stretchr/testify
+ * @refs quality_gate_service.go
+ */
+
+package basic_library
+
+import (
+	"context"
+	"datahub-service/service/eventlog"
+	"datahub-service/service/governance"
+	"datahub-service/service/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// fakeQualityRunner 按质量检测任务ID返回预设结果
+type fakeQualityRunner struct {
+	results map[string]*governance.QualityTaskRunResult
+}
+
+func (f *fakeQualityRunner) RunQualityTaskNow(ctx context.Context, taskID, triggerSource string) (*governance.QualityTaskRunResult, error) {
+	return f.results[taskID], nil
+}
+
+// setupQualityGateDB 在接口if-1上准备一个带两条字段规则的质量检测任务
+func setupQualityGateDB(t *testing.T) (*gorm.DB, *models.SyncTask) {
+	db, task := setupDiagnosticsDB(t)
+	require.NoError(t, db.AutoMigrate(&models.QualityTask{}, &models.QualityTaskFieldRule{}, &models.QualityGateBlock{}))
+	require.NoError(t, db.Create(&models.QualityTask{
+		ID: "qt-1", Name: "车辆进出检测", LibraryType: "basic", LibraryID: "lib-1", InterfaceID: "if-1", ScheduleType: "manual", IsEnabled: true,
+	}).Error)
+	require.NoError(t, db.Create(&[]models.QualityTaskFieldRule{
+		{ID: "rule-plate", TaskID: "qt-1", FieldName: "plate_no", RuleTemplateID: "not_null", IsEnabled: true},
+		{ID: "rule-time", TaskID: "qt-1", FieldName: "pass_time", RuleTemplateID: "not_null", IsEnabled: true},
+	}).Error)
+
+	eventlog.SetDefault(eventlog.NewService(db))
+	t.Cleanup(func() { eventlog.SetDefault(nil) })
+	return db, task
+}
+
+func TestQualityGateUpdateConfig(t *testing.T) {
+	db, task := setupQualityGateDB(t)
+	s := NewQualityGateService(db, &fakeQualityRunner{})
+	ctx := context.Background()
+
+	_, err := s.UpdateConfig(ctx, task.ID, &models.QualityGateConfig{}, "admin")
+	assert.ErrorIs(t, err, ErrInvalidQualityGate, "没有检查条件")
+
+	tooHigh := 120.0
+	_, err = s.UpdateConfig(ctx, task.ID, &models.QualityGateConfig{MinScore: &tooHigh}, "admin")
+	assert.ErrorIs(t, err, ErrInvalidQualityGate)
+
+	_, err = s.UpdateConfig(ctx, task.ID, &models.QualityGateConfig{RequiredRules: []string{"rule-unknown"}}, "admin")
+	assert.ErrorIs(t, err, ErrInvalidQualityGate, "规则不在任务接口上")
+
+	minScore := 90.0
+	gate, err := s.UpdateConfig(ctx, task.ID, &models.QualityGateConfig{MinScore: &minScore, RequiredRules: []string{"rule-plate", "rule-plate"}}, "admin")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"rule-plate"}, gate.RequiredRules)
+
+	stored, err := s.GetConfig(ctx, task.ID)
+	require.NoError(t, err)
+	assert.True(t, stored.Enabled)
+	assert.Equal(t, 90.0, *stored.MinScore)
+	assert.Equal(t, []string{"rule-plate"}, stored.RequiredRules)
+
+	require.NoError(t, s.DisableConfig(ctx, task.ID, "admin"))
+	stored, err = s.GetConfig(ctx, task.ID)
+	require.NoError(t, err)
+	assert.False(t, stored.Enabled)
+	assert.ErrorIs(t, s.DisableConfig(ctx, task.ID, "admin"), ErrInvalidQualityGate)
+}
+
+func TestQualityGateEvaluate(t *testing.T) {
+	db, task := setupQualityGateDB(t)
+	runner := &fakeQualityRunner{results: map[string]*governance.QualityTaskRunResult{}}
+	s := NewQualityGateService(db, runner)
+	ctx := context.Background()
+
+	minScore := 95.0
+	_, err := s.UpdateConfig(ctx, task.ID, &models.QualityGateConfig{MinScore: &minScore, RequiredRules: []string{"rule-plate"}}, "admin")
+	require.NoError(t, err)
+	require.NoError(t, db.First(task, "id = ?", task.ID).Error)
+
+	assert.Nil(t, s.Evaluate(ctx, task, "exec-0", nil), "没有写入成功的接口时不检查")
+
+	runner.results["qt-1"] = &governance.QualityTaskRunResult{
+		TaskID: "qt-1", Status: "completed_with_issues", TotalChecks: 200, FailedChecks: 20,
+		FailedByRule: map[string]int64{"rule-plate": 20},
+	}
+	result := s.Evaluate(ctx, task, "exec-1", []string{"if-1"})
+	require.NotNil(t, result)
+	assert.False(t, result.Passed)
+	assert.Equal(t, 90.0, *result.Score)
+	assert.Len(t, result.Reasons, 2, "评分不足且必须通过的规则不通过")
+	require.Len(t, result.RequiredRules, 1)
+	assert.Equal(t, QualityGateRuleResult{RuleID: "rule-plate", FieldName: "plate_no", Executed: true, Failures: 20}, result.RequiredRules[0])
+
+	blocks, err := s.ListBlocks(ctx)
+	require.NoError(t, err)
+	require.Len(t, blocks, 1)
+	assert.Equal(t, "if-1", blocks[0].InterfaceID)
+	assert.Equal(t, "exec-1", blocks[0].ExecutionID)
+
+	var events int64
+	require.NoError(t, db.Model(&models.ApplicationEvent{}).Where("event_type = ?", eventlog.EventQualityGateFailed).Count(&events).Error)
+	assert.Equal(t, int64(1), events)
+
+	runner.results["qt-1"] = &governance.QualityTaskRunResult{
+		TaskID: "qt-1", Status: "completed", TotalChecks: 200, FailedChecks: 2,
+		FailedByRule: map[string]int64{"rule-time": 2},
+	}
+	result = s.Evaluate(ctx, task, "exec-2", []string{"if-1"})
+	require.NotNil(t, result)
+	assert.True(t, result.Passed, "只有非必须规则少量不通过")
+	blocks, err = s.ListBlocks(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, blocks, "通过后解除阻断")
+}
+
+func TestJudgeQualityGate(t *testing.T) {
+	minScore := 80.0
+	gate := &models.QualityGateConfig{Enabled: true, MinScore: &minScore, RequiredRules: []string{"rule-a"}}
+
+	t.Run("没有执行检测", func(t *testing.T) {
+		result := &QualityGateResult{}
+		judgeQualityGate(gate, result, nil)
+		assert.False(t, result.Passed)
+		assert.Nil(t, result.Score)
+		assert.Contains(t, result.Reasons, "写入的接口上没有启用的质量检测任务")
+	})
+
+	t.Run("检测执行失败", func(t *testing.T) {
+		result := &QualityGateResult{QualityRuns: []*governance.QualityTaskRunResult{{TaskID: "qt-1", Status: "failed", ErrorMessage: "连接超时"}}}
+		judgeQualityGate(gate, result, []models.QualityTaskFieldRule{{ID: "rule-a", TaskID: "qt-1"}})
+		assert.False(t, result.Passed)
+		assert.False(t, result.RequiredRules[0].Executed, "执行失败的检测不算规则已执行")
+	})
+
+	t.Run("全部通过", func(t *testing.T) {
+		result := &QualityGateResult{QualityRuns: []*governance.QualityTaskRunResult{{TaskID: "qt-1", TotalChecks: 10, FailedByRule: map[string]int64{}}}}
+		judgeQualityGate(gate, result, []models.QualityTaskFieldRule{{ID: "rule-a", TaskID: "qt-1"}})
+		assert.True(t, result.Passed)
+		assert.Equal(t, 100.0, *result.Score)
+	})
+}
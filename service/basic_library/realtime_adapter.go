@@ -83,3 +83,6 @@ func (a *InterfaceInfoAdapter) GetTableName() string  { return a.info.GetTableNa
 func (a *InterfaceInfoAdapter) GetParseConfig() map[string]interface{} {
 	return a.info.GetParseConfig()
 }
+func (a *InterfaceInfoAdapter) GetTableFieldsConfig() []interface{} {
+	return a.info.GetTableFieldsConfig()
+}
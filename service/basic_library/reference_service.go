@@ -0,0 +1,642 @@
+/*
+ * @module service/basic_library/reference_service
+ * @description 接口引用完整性服务，管理基础库接口间声明的逻辑外键，定时或手动检查子接口表中在父接口表找不到的孤立引用，并作为质量问题跟踪
+ * @architecture 分层架构 - 业务服务层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow 声明逻辑外键(校验字段存在) -> 定时/手动检查 -> 统计子表行数和孤立引用 -> 保存检查记录 -> 同步质量问题(新增/更新/已恢复) -> 存在孤立引用时记录应用事件
+ * @rules 子字段与父字段数量一致且按顺序对应；字段类型不同时按文本比较；子字段任一为空的行不检查；孤立取值按行数从多到少保存前maxReferenceOrphanSamples个并各对应一条质量问题；
+ *        孤立取值全部保存时，不再出现的取值对应的未处理问题标记为已解决；引用关系删除时其未处理问题标记为忽略；多实例部署时通过分布式锁只由一个实例检查
+ * @dependencies datahub-service/service/distributed_lock, datahub-service/service/eventlog, datahub-service/service/models, datahub-service/service/tenant, gorm.io/gorm, github.com/robfig/cron/v3
+ * @refs service/models/interface_reference.go, api/controllers/reference_controller.go
+ */
+
+package basic_library
+
+import (
+	"context"
+	"datahub-service/service/distributed_lock"
+	"datahub-service/service/eventlog"
+	"datahub-service/service/models"
+	"datahub-service/service/tenant"
+	"datahub-service/service/utils"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	"github.com/spf13/cast"
+	"gorm.io/gorm"
+)
+
+const (
+	// defaultReferenceSchedule 默认每天凌晨2点检查，错开存储快照采集
+	defaultReferenceSchedule = "0 0 2 * * *"
+	// maxReferenceOrphanSamples 每次检查保存的孤立取值数，也是对应的质量问题数上限
+	maxReferenceOrphanSamples = 100
+	// referenceCheckListLimit 检查记录列表返回条数
+	referenceCheckListLimit = 20
+	// referenceLockKey 多实例部署时检查任务的锁
+	referenceLockKey = "interface_reference_check"
+	referenceLockTTL = 30 * time.Minute
+)
+
+// ErrInvalidReference 引用关系配置不合法
+var ErrInvalidReference = errors.New("引用关系配置不合法")
+
+// referenceSeverities 孤立引用质量问题的严重程度
+var referenceSeverities = map[string]bool{"low": true, "medium": true, "high": true, "critical": true}
+
+// ReferenceInput 引用关系配置
+type ReferenceInput struct {
+	Name              string   `json:"name"`
+	ChildInterfaceID  string   `json:"child_interface_id"`
+	ChildFields       []string `json:"child_fields"`
+	ParentInterfaceID string   `json:"parent_interface_id"`
+	ParentFields      []string `json:"parent_fields"`
+	Severity          string   `json:"severity"`
+	Enabled           *bool    `json:"enabled,omitempty"` // 默认启用
+	Description       string   `json:"description"`
+}
+
+// ReferenceCheckSummary 一次批量检查的结果
+type ReferenceCheckSummary struct {
+	Checked  int `json:"checked"`
+	Passed   int `json:"passed"`
+	Violated int `json:"violated"`
+	Failed   int `json:"failed"`
+}
+
+// referenceTable 检查时使用的接口表和字段类型
+type referenceTable struct {
+	name          string            // 带引号的schema.table
+	qualifiedName string            // 不带引号的schema.table，用于质量问题
+	types         map[string]string // 字段名 -> 配置的数据类型
+}
+
+// ReferenceService 接口引用完整性服务
+type ReferenceService struct {
+	db       *gorm.DB
+	readDB   *gorm.DB
+	lock     distributed_lock.DistributedLock
+	schedule string
+	cron     *cron.Cron
+	ctx      context.Context
+	cancel   context.CancelFunc
+	started  bool
+}
+
+// NewReferenceService 创建接口引用完整性服务实例，检查周期可通过REFERENCE_CHECK_SCHEDULE配置
+func NewReferenceService(db *gorm.DB) *ReferenceService {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	schedule := os.Getenv("REFERENCE_CHECK_SCHEDULE")
+	if schedule == "" {
+		schedule = defaultReferenceSchedule
+	}
+
+	return &ReferenceService{
+		db:       db,
+		readDB:   db,
+		schedule: schedule,
+		cron:     cron.New(cron.WithSeconds()),
+		ctx:      ctx,
+		cancel:   cancel,
+	}
+}
+
+// SetReadDB 设置只读副本连接，检查查询走只读副本
+func (s *ReferenceService) SetReadDB(readDB *gorm.DB) {
+	if readDB != nil {
+		s.readDB = readDB
+	}
+}
+
+// SetDistributedLock 设置分布式锁，多实例部署时避免重复检查
+func (s *ReferenceService) SetDistributedLock(lock distributed_lock.DistributedLock) {
+	s.lock = lock
+}
+
+// CreateReference 声明接口间的逻辑外键
+func (s *ReferenceService) CreateReference(ctx context.Context, input *ReferenceInput, username string) (*models.InterfaceReference, error) {
+	if err := s.validateInput(ctx, input); err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	reference := &models.InterfaceReference{
+		CreatedAt: now,
+		CreatedBy: username,
+	}
+	applyReferenceInput(reference, input, username, now)
+	if err := s.db.WithContext(ctx).Create(reference).Error; err != nil {
+		return nil, fmt.Errorf("创建引用关系失败: %w", err)
+	}
+	slog.InfoContext(ctx, "创建接口引用关系", "reference_id", reference.ID, "child_interface_id", reference.ChildInterfaceID, "parent_interface_id", reference.ParentInterfaceID)
+	return reference, nil
+}
+
+// UpdateReference 修改引用关系，从下一次检查开始生效
+func (s *ReferenceService) UpdateReference(ctx context.Context, id string, input *ReferenceInput, username string) (*models.InterfaceReference, error) {
+	reference, err := s.GetReference(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.validateInput(ctx, input); err != nil {
+		return nil, err
+	}
+	applyReferenceInput(reference, input, username, time.Now())
+	if err := s.db.WithContext(ctx).Save(reference).Error; err != nil {
+		return nil, fmt.Errorf("更新引用关系失败: %w", err)
+	}
+	return reference, nil
+}
+
+// DeleteReference 删除引用关系和检查记录，未处理的孤立引用问题标记为忽略
+func (s *ReferenceService) DeleteReference(ctx context.Context, id, username string) error {
+	reference, err := s.GetReference(ctx, id)
+	if err != nil {
+		return err
+	}
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&models.InterfaceReferenceCheck{}, "reference_id = ?", reference.ID).Error; err != nil {
+			return err
+		}
+		if err := s.closeIssues(tx, reference.ID, nil, "ignored", "引用关系已删除", username); err != nil {
+			return err
+		}
+		return tx.Delete(reference).Error
+	})
+}
+
+// GetReference 获取引用关系，子接口须对当前租户可见
+func (s *ReferenceService) GetReference(ctx context.Context, id string) (*models.InterfaceReference, error) {
+	var reference models.InterfaceReference
+	if err := s.visibleReferences(ctx).First(&reference, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &reference, nil
+}
+
+// ListReferences 获取引用关系列表，interfaceID不为空时返回该接口作为子接口或父接口的引用关系
+func (s *ReferenceService) ListReferences(ctx context.Context, interfaceID string) ([]models.InterfaceReference, error) {
+	references := make([]models.InterfaceReference, 0)
+	query := s.visibleReferences(ctx)
+	if interfaceID != "" {
+		query = query.Where("child_interface_id = ? OR parent_interface_id = ?", interfaceID, interfaceID)
+	}
+	if err := query.Order("created_at DESC").Find(&references).Error; err != nil {
+		return nil, fmt.Errorf("查询引用关系失败: %w", err)
+	}
+	return references, nil
+}
+
+// GetChecks 获取引用关系最近的检查记录
+func (s *ReferenceService) GetChecks(ctx context.Context, id string) ([]models.InterfaceReferenceCheck, error) {
+	if _, err := s.GetReference(ctx, id); err != nil {
+		return nil, err
+	}
+	checks := make([]models.InterfaceReferenceCheck, 0)
+	err := s.db.WithContext(ctx).Where("reference_id = ?", id).
+		Order("checked_at DESC").Limit(referenceCheckListLimit).
+		Find(&checks).Error
+	return checks, err
+}
+
+// GetIssues 获取引用关系的孤立引用质量问题，status为空时返回全部
+func (s *ReferenceService) GetIssues(ctx context.Context, id, status string) ([]models.QualityIssueTracker, error) {
+	if _, err := s.GetReference(ctx, id); err != nil {
+		return nil, err
+	}
+	issues := make([]models.QualityIssueTracker, 0)
+	query := s.db.WithContext(ctx).Where("quality_rule_id = ? AND issue_type = ?", id, models.QualityIssueTypeOrphanedReference)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	err := query.Order("detection_time DESC").Find(&issues).Error
+	return issues, err
+}
+
+// CheckReference 立即检查一个引用关系
+func (s *ReferenceService) CheckReference(ctx context.Context, id string) (*models.InterfaceReferenceCheck, error) {
+	reference, err := s.GetReference(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return s.check(ctx, reference)
+}
+
+// CheckAll 检查所有启用的引用关系，单个引用关系检查失败不影响其他引用关系
+func (s *ReferenceService) CheckAll(ctx context.Context) (*ReferenceCheckSummary, error) {
+	var references []models.InterfaceReference
+	if err := s.visibleReferences(ctx).Where("enabled = ?", true).Find(&references).Error; err != nil {
+		return nil, fmt.Errorf("查询引用关系失败: %w", err)
+	}
+
+	summary := &ReferenceCheckSummary{}
+	for i := range references {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		check, err := s.check(ctx, &references[i])
+		if err != nil {
+			return nil, err
+		}
+		summary.Checked++
+		switch check.Status {
+		case models.ReferenceCheckPassed:
+			summary.Passed++
+		case models.ReferenceCheckViolated:
+			summary.Violated++
+		default:
+			summary.Failed++
+		}
+	}
+	return summary, nil
+}
+
+// Start 启动定时检查
+func (s *ReferenceService) Start() error {
+	if s.started {
+		return fmt.Errorf("引用完整性检查调度器已经启动")
+	}
+
+	_, err := s.cron.AddFunc(s.schedule, func() {
+		if s.lock != nil {
+			locked, err := s.lock.TryLock(s.ctx, referenceLockKey, referenceLockTTL)
+			if err != nil || !locked {
+				return
+			}
+			defer func() {
+				if err := s.lock.Unlock(context.Background(), referenceLockKey); err != nil {
+					slog.Error("释放引用完整性检查锁失败", "error", err)
+				}
+			}()
+		}
+
+		summary, err := s.CheckAll(s.ctx)
+		if err != nil {
+			slog.Error("定时引用完整性检查失败", "error", err)
+			return
+		}
+		slog.Info("定时引用完整性检查完成", "checked", summary.Checked, "violated", summary.Violated, "failed", summary.Failed)
+	})
+	if err != nil {
+		return fmt.Errorf("添加定时任务失败: %w", err)
+	}
+
+	s.cron.Start()
+	s.started = true
+	slog.Info("引用完整性检查调度器启动成功", "schedule", s.schedule)
+	return nil
+}
+
+// Stop 停止定时检查
+func (s *ReferenceService) Stop() {
+	if !s.started {
+		return
+	}
+	s.cancel()
+	s.cron.Stop()
+	s.started = false
+}
+
+// check 检查一个引用关系并保存结果，检查本身出错时记为failed而不返回错误
+func (s *ReferenceService) check(ctx context.Context, reference *models.InterfaceReference) (*models.InterfaceReferenceCheck, error) {
+	started := time.Now()
+	check := &models.InterfaceReferenceCheck{
+		ReferenceID: reference.ID,
+		Samples:     models.ReferenceOrphans{},
+	}
+	var parent *referenceTable
+	child, err := s.loadReferenceTable(ctx, reference.ChildInterfaceID)
+	if err == nil {
+		parent, err = s.loadReferenceTable(ctx, reference.ParentInterfaceID)
+		if err == nil {
+			err = s.findOrphans(ctx, reference, child, parent, check)
+		}
+	}
+	check.CheckedAt = time.Now()
+	check.DurationMs = check.CheckedAt.Sub(started).Milliseconds()
+	switch {
+	case err != nil:
+		check.Status = models.ReferenceCheckFailed
+		check.ErrorMessage = err.Error()
+		slog.WarnContext(ctx, "引用完整性检查失败", "reference_id", reference.ID, "error", err)
+	case check.OrphanRows > 0:
+		check.Status = models.ReferenceCheckViolated
+	default:
+		check.Status = models.ReferenceCheckPassed
+	}
+
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(check).Error; err != nil {
+			return err
+		}
+		if check.Status != models.ReferenceCheckFailed {
+			if err := s.syncIssues(tx, reference, child, parent, check); err != nil {
+				return err
+			}
+		}
+		return tx.Model(&models.InterfaceReference{}).Where("id = ?", reference.ID).Updates(map[string]interface{}{
+			"last_checked_at":  check.CheckedAt,
+			"last_status":      check.Status,
+			"last_orphan_rows": check.OrphanRows,
+		}).Error
+	})
+	if err != nil {
+		return nil, fmt.Errorf("保存引用完整性检查结果失败: %w", err)
+	}
+
+	if check.Status == models.ReferenceCheckViolated {
+		eventlog.Record(ctx, eventlog.Event{
+			Type:       eventlog.EventReferenceOrphansFound,
+			Level:      models.EventLevelWarn,
+			Message:    fmt.Sprintf("引用关系 %s 存在%d行孤立引用", reference.Name, check.OrphanRows),
+			ObjectType: "data_interface",
+			ObjectID:   reference.ChildInterfaceID,
+			Attributes: map[string]interface{}{
+				"reference_id":        reference.ID,
+				"reference_name":      reference.Name,
+				"parent_interface_id": reference.ParentInterfaceID,
+				"orphan_rows":         check.OrphanRows,
+				"orphan_values":       check.OrphanValues,
+			},
+		})
+	}
+	return check, nil
+}
+
+// findOrphans 统计子表行数，查询在父表中找不到的子字段取值
+func (s *ReferenceService) findOrphans(ctx context.Context, reference *models.InterfaceReference, child, parent *referenceTable, check *models.InterfaceReferenceCheck) error {
+	notNull := make([]string, len(reference.ChildFields))
+	matches := make([]string, len(reference.ChildFields))
+	selects := make([]string, len(reference.ChildFields))
+	for i, childField := range reference.ChildFields {
+		parentField := reference.ParentFields[i]
+		childColumn := "c." + utils.QuoteIdent(childField)
+		parentColumn := "p." + utils.QuoteIdent(parentField)
+		notNull[i] = childColumn + " IS NOT NULL"
+		if !strings.EqualFold(child.types[childField], parent.types[parentField]) {
+			matches[i] = fmt.Sprintf("CAST(%s AS TEXT) = CAST(%s AS TEXT)", parentColumn, childColumn)
+		} else {
+			matches[i] = parentColumn + " = " + childColumn
+		}
+		selects[i] = childColumn
+	}
+
+	var totals struct {
+		ChildRows   int64
+		CheckedRows int64
+	}
+	totalSQL := fmt.Sprintf("SELECT COUNT(*) AS child_rows, COALESCE(SUM(CASE WHEN %s THEN 1 ELSE 0 END), 0) AS checked_rows FROM %s c",
+		strings.Join(notNull, " AND "), child.name)
+	if err := s.readDB.WithContext(ctx).Raw(totalSQL).Scan(&totals).Error; err != nil {
+		return fmt.Errorf("统计子接口表行数失败: %w", err)
+	}
+	check.ChildRows = totals.ChildRows
+	check.CheckedRows = totals.CheckedRows
+
+	orphanSQL := fmt.Sprintf("SELECT %s, COUNT(*) AS row_count FROM %s c WHERE %s AND NOT EXISTS (SELECT 1 FROM %s p WHERE %s) GROUP BY %s",
+		strings.Join(selects, ", "), child.name, strings.Join(notNull, " AND "), parent.name, strings.Join(matches, " AND "), strings.Join(selects, ", "))
+
+	var orphanTotals struct {
+		OrphanValues int64
+		OrphanRows   int64
+	}
+	if err := s.readDB.WithContext(ctx).Raw("SELECT COUNT(*) AS orphan_values, COALESCE(SUM(row_count), 0) AS orphan_rows FROM (" + orphanSQL + ") o").Scan(&orphanTotals).Error; err != nil {
+		return fmt.Errorf("统计孤立引用失败: %w", err)
+	}
+	check.OrphanValues = orphanTotals.OrphanValues
+	check.OrphanRows = orphanTotals.OrphanRows
+	if check.OrphanRows == 0 {
+		return nil
+	}
+
+	var rows []map[string]interface{}
+	sampleSQL := fmt.Sprintf("%s ORDER BY row_count DESC, %s LIMIT %d", orphanSQL, strings.Join(selects, ", "), maxReferenceOrphanSamples)
+	if err := s.readDB.WithContext(ctx).Raw(sampleSQL).Scan(&rows).Error; err != nil {
+		return fmt.Errorf("查询孤立引用失败: %w", err)
+	}
+	for _, row := range rows {
+		orphan := models.ReferenceOrphan{Values: make(map[string]interface{}, len(reference.ChildFields)), RowCount: cast.ToInt64(row["row_count"])}
+		for _, field := range reference.ChildFields {
+			value := row[field]
+			if data, ok := value.([]byte); ok {
+				value = string(data)
+			}
+			orphan.Values[field] = value
+		}
+		check.Samples = append(check.Samples, orphan)
+	}
+	return nil
+}
+
+// syncIssues 按本次检查的孤立取值新增或更新质量问题，孤立取值全部保存时关闭不再出现的问题
+func (s *ReferenceService) syncIssues(tx *gorm.DB, reference *models.InterfaceReference, child, parent *referenceTable, check *models.InterfaceReferenceCheck) error {
+	var open []models.QualityIssueTracker
+	if err := tx.Where("quality_rule_id = ? AND issue_type = ? AND status = ?", reference.ID, models.QualityIssueTypeOrphanedReference, "open").
+		Find(&open).Error; err != nil {
+		return err
+	}
+	openByIdentifier := make(map[string]*models.QualityIssueTracker, len(open))
+	for i := range open {
+		openByIdentifier[open[i].RecordIdentifier] = &open[i]
+	}
+
+	current := make([]string, 0, len(check.Samples))
+	for _, orphan := range check.Samples {
+		identifier := referenceIdentifier(orphan.Values)
+		current = append(current, identifier)
+		issueContext := models.JSONB{
+			"reference_id":        reference.ID,
+			"child_interface_id":  reference.ChildInterfaceID,
+			"parent_interface_id": reference.ParentInterfaceID,
+			"row_count":           orphan.RowCount,
+		}
+		if existing, ok := openByIdentifier[identifier]; ok {
+			if err := tx.Model(existing).Updates(map[string]interface{}{
+				"quality_check_id": check.ID,
+				"actual_value":     fmt.Sprint(orphan.RowCount),
+				"issue_context":    issueContext,
+				"severity":         reference.Severity,
+				"detection_time":   check.CheckedAt,
+				"updated_at":       check.CheckedAt,
+			}).Error; err != nil {
+				return err
+			}
+			continue
+		}
+		issue := &models.QualityIssueTracker{
+			QualityCheckID:   check.ID,
+			QualityRuleID:    reference.ID,
+			IssueType:        models.QualityIssueTypeOrphanedReference,
+			Severity:         reference.Severity,
+			TargetTable:      child.qualifiedName,
+			TargetColumn:     strings.Join(reference.ChildFields, ","),
+			RecordIdentifier: identifier,
+			IssueDescription: fmt.Sprintf("%s 的取值 %s 在父接口中不存在，涉及%d行", strings.Join(reference.ChildFields, ","), identifier, orphan.RowCount),
+			ExpectedValue:    fmt.Sprintf("%s(%s) 中存在该取值", parent.qualifiedName, strings.Join(reference.ParentFields, ",")),
+			ActualValue:      fmt.Sprint(orphan.RowCount),
+			IssueContext:     issueContext,
+			DetectionTime:    check.CheckedAt,
+			Status:           "open",
+		}
+		if err := tx.Create(issue).Error; err != nil {
+			return err
+		}
+	}
+
+	// 孤立取值超过保存上限时无法确认未保存的取值已恢复，不关闭问题
+	if check.OrphanValues > int64(len(check.Samples)) {
+		return nil
+	}
+	return s.closeIssues(tx, reference.ID, current, "resolved", "复查时引用已恢复", "system")
+}
+
+// closeIssues 关闭引用关系未处理的孤立引用问题，except中的问题保持不变
+func (s *ReferenceService) closeIssues(tx *gorm.DB, referenceID string, except []string, status, note, username string) error {
+	query := tx.Model(&models.QualityIssueTracker{}).
+		Where("quality_rule_id = ? AND issue_type = ? AND status = ?", referenceID, models.QualityIssueTypeOrphanedReference, "open")
+	if len(except) > 0 {
+		query = query.Where("record_identifier NOT IN ?", except)
+	}
+	now := time.Now()
+	return query.Updates(map[string]interface{}{
+		"status":          status,
+		"resolution_note": note,
+		"resolved_by":     username,
+		"resolved_at":     now,
+		"updated_at":      now,
+	}).Error
+}
+
+// loadReferenceTable 加载接口及其表名和字段类型，接口表尚未创建时报错
+func (s *ReferenceService) loadReferenceTable(ctx context.Context, interfaceID string) (*referenceTable, error) {
+	var iface models.DataInterface
+	if err := s.db.WithContext(ctx).Preload("BasicLibrary").First(&iface, "id = ?", interfaceID).Error; err != nil {
+		return nil, fmt.Errorf("接口 %s 不存在: %w", interfaceID, err)
+	}
+	if !iface.IsTableCreated {
+		return nil, fmt.Errorf("接口 %s 的表尚未创建", iface.NameZh)
+	}
+	schema := iface.BasicLibrary.GetSchemaName()
+	table := &referenceTable{
+		name:          utils.QuoteTable(schema, iface.NameEn),
+		qualifiedName: schema + "." + iface.NameEn,
+		types:         make(map[string]string),
+	}
+	for _, field := range sortedTableFields(iface.TableFieldsConfig) {
+		table.types[field.NameEn] = field.DataType
+	}
+	return table, nil
+}
+
+// validateInput 校验引用关系配置，子接口和父接口须对当前租户可见且字段都在表字段配置中
+func (s *ReferenceService) validateInput(ctx context.Context, input *ReferenceInput) error {
+	if err := ValidateReferenceInput(input); err != nil {
+		return err
+	}
+	for _, side := range []struct {
+		label       string
+		interfaceID string
+		fields      []string
+	}{
+		{"子接口", input.ChildInterfaceID, input.ChildFields},
+		{"父接口", input.ParentInterfaceID, input.ParentFields},
+	} {
+		var iface models.DataInterface
+		err := s.db.WithContext(ctx).Select("id", "library_id", "table_fields_config").
+			Scopes(tenant.OwnerScope(ctx, "library_id", "basic_libraries")).
+			First(&iface, "id = ?", side.interfaceID).Error
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return fmt.Errorf("%w: %s不存在", ErrInvalidReference, side.label)
+			}
+			return err
+		}
+		known := make(map[string]bool)
+		for _, field := range sortedTableFields(iface.TableFieldsConfig) {
+			known[field.NameEn] = true
+		}
+		for _, field := range side.fields {
+			if !known[field] {
+				return fmt.Errorf("%w: 字段 %s 不在%s的表字段中", ErrInvalidReference, field, side.label)
+			}
+		}
+	}
+	return nil
+}
+
+// visibleReferences 按当前租户过滤引用关系，以子接口所属基础库的租户为准
+func (s *ReferenceService) visibleReferences(ctx context.Context) *gorm.DB {
+	query := s.db.WithContext(ctx).Model(&models.InterfaceReference{})
+	if info, ok := tenant.FromContext(ctx); ok {
+		query = query.Where("child_interface_id IN (SELECT id FROM data_interfaces WHERE library_id IN (SELECT id FROM basic_libraries WHERE tenant_id = ?))", info.ID)
+	}
+	return query
+}
+
+// ValidateReferenceInput 校验引用关系配置本身，错误包装ErrInvalidReference
+func ValidateReferenceInput(input *ReferenceInput) error {
+	input.Name = strings.TrimSpace(input.Name)
+	if input.Name == "" {
+		return fmt.Errorf("%w: 名称不能为空", ErrInvalidReference)
+	}
+	if input.ChildInterfaceID == "" || input.ParentInterfaceID == "" {
+		return fmt.Errorf("%w: 子接口和父接口不能为空", ErrInvalidReference)
+	}
+	if len(input.ChildFields) == 0 || len(input.ChildFields) != len(input.ParentFields) {
+		return fmt.Errorf("%w: 子字段和父字段数量须一致且至少一个", ErrInvalidReference)
+	}
+	seen := make(map[string]bool, len(input.ChildFields))
+	for i := range input.ChildFields {
+		input.ChildFields[i] = strings.TrimSpace(input.ChildFields[i])
+		input.ParentFields[i] = strings.TrimSpace(input.ParentFields[i])
+		if input.ChildFields[i] == "" || input.ParentFields[i] == "" {
+			return fmt.Errorf("%w: 字段名不能为空", ErrInvalidReference)
+		}
+		if seen[input.ChildFields[i]] {
+			return fmt.Errorf("%w: 子字段 %s 重复", ErrInvalidReference, input.ChildFields[i])
+		}
+		seen[input.ChildFields[i]] = true
+	}
+	if input.ChildInterfaceID == input.ParentInterfaceID && strings.Join(input.ChildFields, ",") == strings.Join(input.ParentFields, ",") {
+		return fmt.Errorf("%w: 字段不能引用自身", ErrInvalidReference)
+	}
+	input.Severity = strings.ToLower(strings.TrimSpace(input.Severity))
+	if input.Severity == "" {
+		input.Severity = "medium"
+	}
+	if !referenceSeverities[input.Severity] {
+		return fmt.Errorf("%w: 不支持的严重程度 %s", ErrInvalidReference, input.Severity)
+	}
+	return nil
+}
+
+// applyReferenceInput 把配置写入引用关系
+func applyReferenceInput(reference *models.InterfaceReference, input *ReferenceInput, username string, now time.Time) {
+	reference.Name = input.Name
+	reference.ChildInterfaceID = input.ChildInterfaceID
+	reference.ChildFields = input.ChildFields
+	reference.ParentInterfaceID = input.ParentInterfaceID
+	reference.ParentFields = input.ParentFields
+	reference.Severity = input.Severity
+	reference.Enabled = input.Enabled == nil || *input.Enabled
+	reference.Description = input.Description
+	reference.UpdatedAt = now
+	reference.UpdatedBy = username
+}
+
+// referenceIdentifier 孤立取值的标识，单字段时为取值本身，多字段时为JSON
+func referenceIdentifier(values map[string]interface{}) string {
+	if len(values) == 1 {
+		for _, value := range values {
+			return fmt.Sprint(value)
+		}
+	}
+	data, _ := json.Marshal(values)
+	return string(data)
+}
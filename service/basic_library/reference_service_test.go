@@ -0,0 +1,149 @@
+/*
+ * @module service/basic_library/reference_service_test
+ * @description 接口引用完整性服务测试，覆盖引用关系配置校验、孤立引用统计(含类型不同按文本比较、空值跳过)、质量问题的新增/恢复/忽略和违规事件
+ * @architecture 测试层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow 准备sqlite中的基础库、接口和附加库中的接口表 -> 声明引用关系 -> 检查 -> 修复数据后复查 -> 删除引用关系
+ * @rules 使用内存sqlite，以ATTACH的数据库模拟基础库schema，不依赖外部服务
+ * @dependencies gorm.io/driver/sqlite, stretchr/testify
+ * @refs reference_service.go
+ */
+
+package basic_library
+
+import (
+	"context"
+	"datahub-service/service/eventlog"
+	"datahub-service/service/models"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// setupReferenceDB 准备基础库campus、楼栋接口if-building和设备接口if-device，接口表建在附加库campus中
+func setupReferenceDB(t *testing.T) *gorm.DB {
+	db, _ := setupDiagnosticsDB(t)
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	sqlDB.SetMaxOpenConns(1) // ATTACH只对当前连接生效
+	require.NoError(t, db.AutoMigrate(&models.BasicLibrary{}, &models.InterfaceReference{}, &models.InterfaceReferenceCheck{}, &models.QualityIssueTracker{}))
+
+	require.NoError(t, db.Exec(`ATTACH DATABASE ':memory:' AS campus`).Error)
+	require.NoError(t, db.Exec(`CREATE TABLE campus.buildings (id INTEGER PRIMARY KEY, name TEXT)`).Error)
+	require.NoError(t, db.Exec(`CREATE TABLE campus.devices (id INTEGER PRIMARY KEY, building_id TEXT)`).Error)
+	require.NoError(t, db.Exec(`INSERT INTO campus.buildings (id, name) VALUES (1, 'A栋'), (2, 'B栋')`).Error)
+	require.NoError(t, db.Exec(`INSERT INTO campus.devices (id, building_id) VALUES (1, '1'), (2, '2'), (3, '9'), (4, '9'), (5, NULL), (6, '8')`).Error)
+
+	require.NoError(t, db.Create(&models.BasicLibrary{ID: "lib-campus", NameZh: "园区", NameEn: "campus"}).Error)
+	require.NoError(t, db.Create(&models.DataInterface{
+		ID: "if-building", LibraryID: "lib-campus", NameZh: "楼栋", NameEn: "buildings", Type: "batch", DataSourceID: "ds-1", IsTableCreated: true,
+		TableFieldsConfig: models.JSONB{
+			"field_0": map[string]interface{}{"name_en": "id", "data_type": "integer", "is_primary_key": true, "order_num": 1},
+			"field_1": map[string]interface{}{"name_en": "name", "data_type": "varchar", "order_num": 2},
+		},
+	}).Error)
+	require.NoError(t, db.Create(&models.DataInterface{
+		ID: "if-device", LibraryID: "lib-campus", NameZh: "设备", NameEn: "devices", Type: "batch", DataSourceID: "ds-1", IsTableCreated: true,
+		TableFieldsConfig: models.JSONB{
+			"field_0": map[string]interface{}{"name_en": "id", "data_type": "integer", "is_primary_key": true, "order_num": 1},
+			"field_1": map[string]interface{}{"name_en": "building_id", "data_type": "varchar", "order_num": 2},
+		},
+	}).Error)
+
+	eventlog.SetDefault(eventlog.NewService(db))
+	t.Cleanup(func() { eventlog.SetDefault(nil) })
+	return db
+}
+
+func TestValidateReferenceInput(t *testing.T) {
+	input := &ReferenceInput{Name: " 设备所属楼栋 ", ChildInterfaceID: "if-device", ChildFields: []string{" building_id "}, ParentInterfaceID: "if-building", ParentFields: []string{"id"}}
+	require.NoError(t, ValidateReferenceInput(input))
+	assert.Equal(t, "设备所属楼栋", input.Name)
+	assert.Equal(t, []string{"building_id"}, input.ChildFields)
+	assert.Equal(t, "medium", input.Severity, "默认严重程度")
+
+	invalid := map[string]*ReferenceInput{
+		"没有名称":   {ChildInterfaceID: "a", ChildFields: []string{"x"}, ParentInterfaceID: "b", ParentFields: []string{"id"}},
+		"字段数不一致": {Name: "r", ChildInterfaceID: "a", ChildFields: []string{"x", "y"}, ParentInterfaceID: "b", ParentFields: []string{"id"}},
+		"子字段重复":  {Name: "r", ChildInterfaceID: "a", ChildFields: []string{"x", "x"}, ParentInterfaceID: "b", ParentFields: []string{"id", "code"}},
+		"引用自身":   {Name: "r", ChildInterfaceID: "a", ChildFields: []string{"id"}, ParentInterfaceID: "a", ParentFields: []string{"id"}},
+		"严重程度未知": {Name: "r", ChildInterfaceID: "a", ChildFields: []string{"x"}, ParentInterfaceID: "b", ParentFields: []string{"id"}, Severity: "urgent"},
+	}
+	for name, input := range invalid {
+		assert.True(t, errors.Is(ValidateReferenceInput(input), ErrInvalidReference), name)
+	}
+}
+
+func TestReferenceCheckTracksOrphansAsQualityIssues(t *testing.T) {
+	db := setupReferenceDB(t)
+	s := NewReferenceService(db)
+	ctx := context.Background()
+
+	_, err := s.CreateReference(ctx, &ReferenceInput{
+		Name: "设备所属楼栋", ChildInterfaceID: "if-device", ChildFields: []string{"building"}, ParentInterfaceID: "if-building", ParentFields: []string{"id"},
+	}, "alice")
+	assert.True(t, errors.Is(err, ErrInvalidReference), "字段不在表字段配置中")
+
+	reference, err := s.CreateReference(ctx, &ReferenceInput{
+		Name: "设备所属楼栋", ChildInterfaceID: "if-device", ChildFields: []string{"building_id"}, ParentInterfaceID: "if-building", ParentFields: []string{"id"}, Severity: "high",
+	}, "alice")
+	require.NoError(t, err)
+
+	// building_id为文本、id为整数，按文本比较；空值不检查
+	check, err := s.CheckReference(ctx, reference.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.ReferenceCheckViolated, check.Status, check.ErrorMessage)
+	assert.Equal(t, int64(6), check.ChildRows)
+	assert.Equal(t, int64(5), check.CheckedRows)
+	assert.Equal(t, int64(3), check.OrphanRows)
+	assert.Equal(t, int64(2), check.OrphanValues)
+	require.Len(t, check.Samples, 2)
+	assert.Equal(t, models.ReferenceOrphan{Values: map[string]interface{}{"building_id": "9"}, RowCount: 2}, check.Samples[0])
+
+	issues, err := s.GetIssues(ctx, reference.ID, "open")
+	require.NoError(t, err)
+	require.Len(t, issues, 2)
+	assert.Equal(t, "high", issues[0].Severity)
+	assert.Equal(t, "campus.devices", issues[0].TargetTable)
+	assert.Equal(t, "building_id", issues[0].TargetColumn)
+
+	// 补齐楼栋9后复查，9对应的问题已恢复，8的问题保持打开并关联到新的检查
+	require.NoError(t, db.Exec(`INSERT INTO campus.buildings (id, name) VALUES (9, 'C栋')`).Error)
+	recheck, err := s.CheckReference(ctx, reference.ID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), recheck.OrphanRows)
+
+	open, err := s.GetIssues(ctx, reference.ID, "open")
+	require.NoError(t, err)
+	require.Len(t, open, 1)
+	assert.Equal(t, "8", open[0].RecordIdentifier)
+	assert.Equal(t, recheck.ID, open[0].QualityCheckID)
+	resolved, err := s.GetIssues(ctx, reference.ID, "resolved")
+	require.NoError(t, err)
+	require.Len(t, resolved, 1)
+	assert.Equal(t, "9", resolved[0].RecordIdentifier)
+
+	saved, err := s.GetReference(ctx, reference.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.ReferenceCheckViolated, saved.LastStatus)
+	assert.Equal(t, int64(1), saved.LastOrphanRows)
+
+	var events int64
+	require.NoError(t, db.Model(&models.ApplicationEvent{}).Where("event_type = ? AND object_id = ?", eventlog.EventReferenceOrphansFound, "if-device").Count(&events).Error)
+	assert.Equal(t, int64(2), events)
+
+	summary, err := s.CheckAll(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, ReferenceCheckSummary{Checked: 1, Violated: 1}, *summary)
+
+	require.NoError(t, s.DeleteReference(ctx, reference.ID, "bob"))
+	ignored, err := s.GetIssues(ctx, reference.ID, "")
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	assert.Nil(t, ignored)
+	var ignoredCount int64
+	require.NoError(t, db.Model(&models.QualityIssueTracker{}).Where("quality_rule_id = ? AND status = ?", reference.ID, "ignored").Count(&ignoredCount).Error)
+	assert.Equal(t, int64(1), ignoredCount)
+}
@@ -0,0 +1,666 @@
+/*
+ * @module service/basic_library/rollup_service
+ * @description 时间序列降采样任务服务，按时间桶把时间序列接口的原始数据定时汇总到独立的汇总表，汇总表有自己的保留期，可注册为新的数据接口并自动建立血缘
+ * @architecture 分层架构 - 业务服务层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow 创建：校验时间桶和指标 -> 建汇总表并转换为超表、设置保留策略 -> 保存任务 -> 可选注册为接口；
+ *            定时运行：取启用的任务 -> 计算窗口(水位线减回看时间 ~ 当前未完整时间桶) -> 事务内删除窗口内汇总行并重新汇总写入 -> 推进水位线；
+ *            注册：创建批量接口(时间序列配置指向bucket列) -> 建立源接口到汇总接口的aggregated血缘
+ * @rules 源接口须已开启时间序列存储且接口表已创建；任务名不能与连续聚合同名；时间桶和指标创建后不能修改，须删除重建；
+ *        原始数据的保留期由源接口时间序列配置的保留时间控制，汇总表的保留期由任务控制；
+ *        只汇总完整的时间桶，单次运行最多汇总一千个时间桶，补齐历史数据时分多次运行；
+ *        删除任务时未注册的汇总表随之删除，已注册的汇总表归接口所有，只停用血缘；源接口或汇总接口删除时任务随之删除
+ * @dependencies datahub-service/service/database, datahub-service/service/distributed_lock, datahub-service/service/models, gorm.io/gorm, github.com/robfig/cron/v3, github.com/google/uuid
+ * @refs service/models/timeseries_rollup.go, service/database/timeseries.go, service/basic_library/timeseries_service.go, api/controllers/rollup_controller.go
+ */
+
+package basic_library
+
+import (
+	"context"
+	"datahub-service/service/database"
+	"datahub-service/service/distributed_lock"
+	"datahub-service/service/models"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/robfig/cron/v3"
+	"gorm.io/gorm"
+)
+
+const (
+	// defaultRollupSchedule 默认每分钟检查一次有无新的完整时间桶
+	defaultRollupSchedule = "0 * * * * *"
+	// rollupLockKey 多实例部署时降采样任务的锁
+	rollupLockKey = "timeseries_rollup_run"
+	rollupLockTTL = 10 * time.Minute
+	// rollupChunkInterval 汇总表的分块时间间隔，汇总表数据量小，分块比原始表大
+	rollupChunkInterval = "30 days"
+)
+
+// ErrInvalidRollupRequest 降采样任务配置不合法
+var ErrInvalidRollupRequest = errors.New("降采样任务配置不合法")
+
+// RollupService 时间序列降采样任务服务
+type RollupService struct {
+	db            *gorm.DB
+	schemaService *database.SchemaService
+	lock          distributed_lock.DistributedLock
+	schedule      string
+	cron          *cron.Cron
+	ctx           context.Context
+	cancel        context.CancelFunc
+	started       bool
+}
+
+// NewRollupService 创建时间序列降采样任务服务，检查周期可通过TIMESERIES_ROLLUP_SCHEDULE配置
+func NewRollupService(db *gorm.DB) *RollupService {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	schedule := os.Getenv("TIMESERIES_ROLLUP_SCHEDULE")
+	if schedule == "" {
+		schedule = defaultRollupSchedule
+	}
+
+	return &RollupService{
+		db:            db,
+		schemaService: database.NewSchemaService(db),
+		schedule:      schedule,
+		cron:          cron.New(cron.WithSeconds()),
+		ctx:           ctx,
+		cancel:        cancel,
+	}
+}
+
+// SetDistributedLock 设置分布式锁，多实例部署时避免重复汇总
+func (s *RollupService) SetDistributedLock(lock distributed_lock.DistributedLock) {
+	s.lock = lock
+}
+
+// RollupRunResult 一次定时运行的结果
+type RollupRunResult struct {
+	Checked   int   `json:"checked"`
+	Succeeded int   `json:"succeeded"` // 本次有新的时间桶并汇总成功的任务数
+	Failed    int   `json:"failed"`
+	Rows      int64 `json:"rows"` // 写入的汇总行数
+}
+
+// ListRollups 获取接口的降采样任务
+func (s *RollupService) ListRollups(ctx context.Context, interfaceID string) ([]models.TimeSeriesRollup, error) {
+	if _, err := getTenantInterface(ctx, s.db, interfaceID); err != nil {
+		return nil, err
+	}
+	rollups := []models.TimeSeriesRollup{}
+	if err := s.db.WithContext(ctx).Where("source_interface_id = ?", interfaceID).Order("created_at").Find(&rollups).Error; err != nil {
+		return nil, fmt.Errorf("查询降采样任务失败: %w", err)
+	}
+	return rollups, nil
+}
+
+// CreateRollup 创建降采样任务：建汇总表并转换为超表，register为true时同时注册为接口
+func (s *RollupService) CreateRollup(ctx context.Context, interfaceID string, rollup *models.TimeSeriesRollup, register bool, interfaceNameZh, username string) (*models.TimeSeriesRollup, error) {
+	iface, err := getTenantInterface(ctx, s.db, interfaceID)
+	if err != nil {
+		return nil, err
+	}
+	config := models.ParseTimeSeriesConfig(iface.InterfaceConfig)
+	if config == nil || !iface.IsTableCreated {
+		return nil, fmt.Errorf("%w: 接口未开启时间序列存储或接口表尚未创建", ErrInvalidRollupRequest)
+	}
+	if config.FindAggregate(rollup.Name) >= 0 {
+		return nil, fmt.Errorf("%w: 已有同名的连续聚合%s", ErrInvalidRollupRequest, rollup.Name)
+	}
+
+	rollup.SourceInterfaceID = interfaceID
+	rollup.TimeColumn = config.TimeColumn
+	rollup.TagColumns = models.JSONBStringArray(config.TagColumns)
+	rollup.RollupTable = database.RollupTableName(iface.NameEn, rollup.Name)
+	rollup.Enabled = true
+	rollup.CreatedBy = username
+	rollup.UpdatedBy = username
+	fields := sortedTableFields(iface.TableFieldsConfig)
+	if err := validateRollup(fields, config, rollup); err != nil {
+		return nil, err
+	}
+
+	var existing int64
+	err = s.db.WithContext(ctx).Model(&models.TimeSeriesRollup{}).Where("source_interface_id = ? AND name = ?", interfaceID, rollup.Name).Count(&existing).Error
+	if err != nil {
+		return nil, err
+	}
+	if existing > 0 {
+		return nil, fmt.Errorf("%w: 降采样任务%s已存在", ErrInvalidRollupRequest, rollup.Name)
+	}
+	schema := iface.BasicLibrary.GetSchemaName()
+	if err := s.checkTableAvailable(ctx, iface.LibraryID, schema, rollup.RollupTable); err != nil {
+		return nil, err
+	}
+
+	if err := s.schemaService.ManageTableSchema(interfaceID, "create_table", schema, rollup.RollupTable, rollupTableFields(fields, rollup)); err != nil {
+		return nil, fmt.Errorf("创建汇总表失败: %w", err)
+	}
+	if err := applyTimeSeriesStorage(s.db.WithContext(ctx), schema, rollup.RollupTable, rollupTimeSeriesConfig(rollup), false); err != nil {
+		s.schemaService.ManageTableSchema(interfaceID, "drop_table", schema, rollup.RollupTable, []models.TableField{})
+		return nil, err
+	}
+	if err := s.db.WithContext(ctx).Create(rollup).Error; err != nil {
+		s.schemaService.ManageTableSchema(interfaceID, "drop_table", schema, rollup.RollupTable, []models.TableField{})
+		return nil, fmt.Errorf("保存降采样任务失败: %w", err)
+	}
+	slog.Info("创建降采样任务", "interface_id", interfaceID, "rollup", rollup.Name, "bucket_interval", rollup.BucketInterval,
+		"table", rollup.RollupTable, "username", username)
+
+	if register {
+		if _, err := s.register(ctx, iface, rollup, interfaceNameZh, username); err != nil {
+			return nil, fmt.Errorf("降采样任务已创建，注册为接口失败: %w", err)
+		}
+	}
+	return rollup, nil
+}
+
+// UpdateRollup 启停降采样任务，修改汇总表的保留时间和回看时间
+func (s *RollupService) UpdateRollup(ctx context.Context, interfaceID, name string, enabled bool, retentionPeriod, lookback, username string) (*models.TimeSeriesRollup, error) {
+	iface, rollup, err := s.getRollup(ctx, interfaceID, name)
+	if err != nil {
+		return nil, err
+	}
+	current := rollupTimeSeriesConfig(rollup)
+	if lookback == "" {
+		lookback = rollup.BucketInterval
+	}
+	rollup.Enabled = enabled
+	rollup.RetentionPeriod = retentionPeriod
+	rollup.Lookback = lookback
+	if err := validateRollupIntervals(rollup); err != nil {
+		return nil, err
+	}
+
+	db := s.db.WithContext(ctx)
+	schema := iface.BasicLibrary.GetSchemaName()
+	config := rollupTimeSeriesConfig(rollup)
+	if config.RetentionPeriod != current.RetentionPeriod {
+		if err := updateTimeSeriesPolicies(db, schema, rollup.RollupTable, current, config, false); err != nil {
+			return nil, err
+		}
+		if err := s.syncTargetRetention(ctx, rollup, username); err != nil {
+			return nil, err
+		}
+	}
+
+	updates := map[string]interface{}{
+		"enabled":          rollup.Enabled,
+		"retention_period": rollup.RetentionPeriod,
+		"lookback":         rollup.Lookback,
+		"updated_at":       time.Now(),
+		"updated_by":       username,
+	}
+	if err := db.Model(rollup).Updates(updates).Error; err != nil {
+		return nil, fmt.Errorf("保存降采样任务失败: %w", err)
+	}
+
+	slog.Info("修改降采样任务", "interface_id", interfaceID, "rollup", name, "enabled", enabled,
+		"retention_period", retentionPeriod, "username", username)
+	return rollup, nil
+}
+
+// DeleteRollup 删除降采样任务，未注册为接口的汇总表随之删除，已注册的保留汇总表和接口并停用血缘
+func (s *RollupService) DeleteRollup(ctx context.Context, interfaceID, name, username string) error {
+	iface, rollup, err := s.getRollup(ctx, interfaceID, name)
+	if err != nil {
+		return err
+	}
+
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if rollup.LineageID != "" {
+			err := tx.Model(&models.DataLineage{}).Where("id = ?", rollup.LineageID).
+				Updates(map[string]interface{}{"is_active": false, "updated_by": username, "updated_at": time.Now()}).Error
+			if err != nil {
+				return fmt.Errorf("停用降采样血缘失败: %w", err)
+			}
+		}
+		if err := tx.Delete(rollup).Error; err != nil {
+			return fmt.Errorf("删除降采样任务失败: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if rollup.TargetInterfaceID == "" {
+		if err := s.schemaService.ManageTableSchema(interfaceID, "drop_table", iface.BasicLibrary.GetSchemaName(), rollup.RollupTable, []models.TableField{}); err != nil {
+			slog.Warn("删除汇总表失败", "interface_id", interfaceID, "table", rollup.RollupTable, "error", err)
+		}
+	}
+
+	slog.Info("删除降采样任务", "interface_id", interfaceID, "rollup", name,
+		"target_interface_id", rollup.TargetInterfaceID, "username", username)
+	return nil
+}
+
+// RunRollup 立即运行一次降采样任务，返回运行后的任务状态
+func (s *RollupService) RunRollup(ctx context.Context, interfaceID, name string) (*models.TimeSeriesRollup, error) {
+	_, rollup, err := s.getRollup(ctx, interfaceID, name)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.run(ctx, rollup); err != nil {
+		return nil, err
+	}
+	if err := s.db.WithContext(ctx).First(rollup, "id = ?", rollup.ID).Error; err != nil {
+		return nil, err
+	}
+	return rollup, nil
+}
+
+// RegisterRollup 把汇总表注册为源接口所在基础库的批量接口，并建立源接口到汇总接口的血缘
+func (s *RollupService) RegisterRollup(ctx context.Context, interfaceID, name, interfaceNameZh, username string) (*models.DataInterface, error) {
+	iface, rollup, err := s.getRollup(ctx, interfaceID, name)
+	if err != nil {
+		return nil, err
+	}
+	if rollup.TargetInterfaceID != "" {
+		return nil, fmt.Errorf("%w: 汇总表已注册为接口%s", ErrInvalidRollupRequest, rollup.TargetInterfaceID)
+	}
+	return s.register(ctx, iface, rollup, interfaceNameZh, username)
+}
+
+// RunAll 运行全部启用的降采样任务，有新的完整时间桶时汇总
+func (s *RollupService) RunAll(ctx context.Context) (*RollupRunResult, error) {
+	var rollups []models.TimeSeriesRollup
+	if err := s.db.WithContext(ctx).Where("enabled = ?", true).Find(&rollups).Error; err != nil {
+		return nil, fmt.Errorf("查询降采样任务失败: %w", err)
+	}
+
+	result := &RollupRunResult{}
+	for i := range rollups {
+		if ctx.Err() != nil {
+			break
+		}
+		result.Checked++
+		rows, err := s.run(ctx, &rollups[i])
+		switch {
+		case err != nil:
+			result.Failed++
+		case rows >= 0:
+			result.Succeeded++
+			result.Rows += rows
+		}
+	}
+	return result, nil
+}
+
+// Start 启动定时降采样
+func (s *RollupService) Start() error {
+	if s.started {
+		return fmt.Errorf("降采样任务调度器已经启动")
+	}
+
+	_, err := s.cron.AddFunc(s.schedule, func() {
+		if s.lock != nil {
+			locked, err := s.lock.TryLock(s.ctx, rollupLockKey, rollupLockTTL)
+			if err != nil || !locked {
+				return
+			}
+			defer func() {
+				if err := s.lock.Unlock(context.Background(), rollupLockKey); err != nil {
+					slog.Error("释放降采样任务锁失败", "error", err)
+				}
+			}()
+		}
+
+		result, err := s.RunAll(s.ctx)
+		if err != nil {
+			slog.Error("定时降采样失败", "error", err)
+			return
+		}
+		if result.Succeeded > 0 || result.Failed > 0 {
+			slog.Debug("定时降采样完成", "checked", result.Checked, "succeeded", result.Succeeded, "failed", result.Failed, "rows", result.Rows)
+		}
+	})
+	if err != nil {
+		return fmt.Errorf("添加定时任务失败: %w", err)
+	}
+
+	s.cron.Start()
+	s.started = true
+	slog.Info("降采样任务调度器启动成功", "schedule", s.schedule)
+	return nil
+}
+
+// Stop 停止定时降采样
+func (s *RollupService) Stop() {
+	if !s.started {
+		return
+	}
+	s.cancel()
+	s.cron.Stop()
+	s.started = false
+}
+
+// run 汇总一次新的完整时间桶，返回写入的汇总行数，没有新的时间桶时返回-1；失败时记录到任务
+func (s *RollupService) run(ctx context.Context, rollup *models.TimeSeriesRollup) (int64, error) {
+	rows, err := s.aggregate(ctx, rollup)
+	if err != nil {
+		slog.Error("降采样任务运行失败", "rollup_id", rollup.ID, "rollup", rollup.Name, "error", err)
+		updates := map[string]interface{}{
+			"last_run_at": time.Now(),
+			"last_status": models.RollupRunStatusFailed,
+			"last_error":  err.Error(),
+		}
+		if err := s.db.WithContext(ctx).Model(rollup).Updates(updates).Error; err != nil {
+			slog.Error("记录降采样任务运行结果失败", "rollup_id", rollup.ID, "error", err)
+		}
+		return 0, err
+	}
+	return rows, nil
+}
+
+// aggregate 在事务中删除窗口内的汇总行并重新汇总写入，同时推进水位线
+func (s *RollupService) aggregate(ctx context.Context, rollup *models.TimeSeriesRollup) (int64, error) {
+	var source models.DataInterface
+	if err := s.db.WithContext(ctx).Preload("BasicLibrary").First(&source, "id = ?", rollup.SourceInterfaceID).Error; err != nil {
+		return 0, fmt.Errorf("查询源接口失败: %w", err)
+	}
+	schema := source.BasicLibrary.GetSchemaName()
+
+	var window struct {
+		WindowStart *time.Time
+		WindowEnd   *time.Time
+	}
+	if err := s.db.WithContext(ctx).Raw(database.BuildRollupWindowSQL(schema, source.NameEn, rollup), rollup.Watermark).Scan(&window).Error; err != nil {
+		return 0, fmt.Errorf("计算汇总窗口失败: %w", err)
+	}
+	if window.WindowStart == nil || window.WindowEnd == nil || !window.WindowEnd.After(*window.WindowStart) ||
+		(rollup.Watermark != nil && !window.WindowEnd.After(*rollup.Watermark)) {
+		return -1, nil
+	}
+
+	var rows int64
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec(database.BuildRollupDeleteSQL(schema, rollup), *window.WindowStart, *window.WindowEnd).Error; err != nil {
+			return fmt.Errorf("删除窗口内的汇总数据失败: %w", err)
+		}
+		result := tx.Exec(database.BuildRollupInsertSQL(schema, source.NameEn, rollup), *window.WindowStart, *window.WindowEnd)
+		if result.Error != nil {
+			return fmt.Errorf("写入汇总数据失败: %w", result.Error)
+		}
+		rows = result.RowsAffected
+		return tx.Model(rollup).Updates(map[string]interface{}{
+			"watermark":      *window.WindowEnd,
+			"last_run_at":    time.Now(),
+			"last_status":    models.RollupRunStatusSuccess,
+			"last_error":     "",
+			"last_row_count": rows,
+		}).Error
+	})
+	if err != nil {
+		return 0, err
+	}
+	slog.Debug("降采样任务汇总完成", "rollup_id", rollup.ID, "rollup", rollup.Name,
+		"window_start", *window.WindowStart, "window_end", *window.WindowEnd, "rows", rows)
+	return rows, nil
+}
+
+// register 创建汇总表对应的批量接口和血缘，并记录到任务
+func (s *RollupService) register(ctx context.Context, source *models.DataInterface, rollup *models.TimeSeriesRollup, nameZh, username string) (*models.DataInterface, error) {
+	var existing int64
+	err := s.db.WithContext(ctx).Model(&models.DataInterface{}).Where("library_id = ? AND name_en = ?", source.LibraryID, rollup.RollupTable).Count(&existing).Error
+	if err != nil {
+		return nil, err
+	}
+	if existing > 0 {
+		return nil, fmt.Errorf("%w: 基础库中已有英文名为%s的接口", ErrInvalidRollupRequest, rollup.RollupTable)
+	}
+	if nameZh == "" {
+		nameZh = fmt.Sprintf("%s（%s汇总）", source.NameZh, rollup.BucketInterval)
+	}
+
+	fieldsConfig := models.JSONB{}
+	for i, field := range rollupTableFields(sortedTableFields(source.TableFieldsConfig), rollup) {
+		fieldsConfig[fmt.Sprintf("field_%d", i)] = field
+	}
+	config := rollupTimeSeriesConfig(rollup)
+	config.UpdatedBy = username
+	config.UpdatedAt = time.Now()
+	description := fmt.Sprintf("由接口%s的降采样任务%s按%s汇总生成", source.NameEn, rollup.Name, rollup.BucketInterval)
+	target := &models.DataInterface{
+		ID:                uuid.New().String(),
+		LibraryID:         source.LibraryID,
+		NameZh:            nameZh,
+		NameEn:            rollup.RollupTable,
+		Type:              "batch",
+		Description:       description,
+		CreatedBy:         username,
+		UpdatedBy:         username,
+		Status:            "active",
+		IsTableCreated:    true,
+		DataSourceID:      source.DataSourceID,
+		InterfaceConfig:   models.JSONB{models.TimeSeriesConfigKey: config},
+		TableFieldsConfig: fieldsConfig,
+	}
+
+	columnMapping := models.JSONB{models.RollupBucketColumn: rollup.TimeColumn}
+	for _, tag := range rollup.TagColumns {
+		columnMapping[tag] = tag
+	}
+	for _, metric := range rollup.Metrics {
+		columnMapping[metric.Alias()] = metric.Column
+	}
+	lineage := &models.DataLineage{
+		SourceObjectID:   source.ID,
+		SourceObjectType: "interface",
+		TargetObjectID:   target.ID,
+		TargetObjectType: "interface",
+		RelationType:     "aggregated",
+		TransformRule: models.JSONB{
+			"rollup_id":       rollup.ID,
+			"bucket_interval": rollup.BucketInterval,
+			"metrics":         rollup.Metrics,
+		},
+		ColumnMapping: columnMapping,
+		Confidence:    1,
+		IsActive:      true,
+		Description:   description,
+		CreatedBy:     username,
+		UpdatedBy:     username,
+	}
+
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(target).Error; err != nil {
+			return fmt.Errorf("创建汇总接口失败: %w", err)
+		}
+		if err := tx.Create(lineage).Error; err != nil {
+			return fmt.Errorf("创建降采样血缘失败: %w", err)
+		}
+		return tx.Model(rollup).Updates(map[string]interface{}{
+			"target_interface_id": target.ID,
+			"lineage_id":          lineage.ID,
+			"updated_at":          time.Now(),
+			"updated_by":          username,
+		}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	rollup.TargetInterfaceID = target.ID
+	rollup.LineageID = lineage.ID
+
+	slog.Info("降采样汇总表注册为接口", "source_interface_id", source.ID, "rollup", rollup.Name,
+		"target_interface_id", target.ID, "lineage_id", lineage.ID, "username", username)
+	return target, nil
+}
+
+// syncTargetRetention 汇总表已注册为接口时，把保留时间同步到接口的时间序列配置
+func (s *RollupService) syncTargetRetention(ctx context.Context, rollup *models.TimeSeriesRollup, username string) error {
+	if rollup.TargetInterfaceID == "" {
+		return nil
+	}
+	var target models.DataInterface
+	if err := s.db.WithContext(ctx).First(&target, "id = ?", rollup.TargetInterfaceID).Error; err != nil {
+		return fmt.Errorf("查询汇总接口失败: %w", err)
+	}
+	config := models.ParseTimeSeriesConfig(target.InterfaceConfig)
+	if config == nil {
+		return nil
+	}
+	config.RetentionPeriod = rollup.RetentionPeriod
+	config.UpdatedBy = username
+	config.UpdatedAt = time.Now()
+	if err := saveInterfaceConfig(ctx, s.db, &target, "interface_config", models.TimeSeriesConfigKey, config, nil); err != nil {
+		return fmt.Errorf("保存时间序列存储配置失败: %w", err)
+	}
+	return nil
+}
+
+// checkTableAvailable 汇总表名不能与基础库中的接口或已有的表重名
+func (s *RollupService) checkTableAvailable(ctx context.Context, libraryID, schema, table string) error {
+	var count int64
+	if err := s.db.WithContext(ctx).Model(&models.DataInterface{}).Where("library_id = ? AND name_en = ?", libraryID, table).Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return fmt.Errorf("%w: 基础库中已有英文名为%s的接口", ErrInvalidRollupRequest, table)
+	}
+	exists, err := s.schemaService.CheckTableExists(schema, table)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return fmt.Errorf("%w: 表%s已存在", ErrInvalidRollupRequest, table)
+	}
+	return nil
+}
+
+// getRollup 获取接口的降采样任务
+func (s *RollupService) getRollup(ctx context.Context, interfaceID, name string) (*models.DataInterface, *models.TimeSeriesRollup, error) {
+	iface, err := getTenantInterface(ctx, s.db, interfaceID)
+	if err != nil {
+		return nil, nil, err
+	}
+	var rollup models.TimeSeriesRollup
+	if err := s.db.WithContext(ctx).First(&rollup, "source_interface_id = ? AND name = ?", interfaceID, name).Error; err != nil {
+		return nil, nil, err
+	}
+	return iface, &rollup, nil
+}
+
+// deleteInterfaceRollups 删除接口作为源或汇总接口的降采样任务，作为源接口时未注册的汇总表随之删除
+func deleteInterfaceRollups(tx *gorm.DB, schemaService *database.SchemaService, iface *models.DataInterface) error {
+	var rollups []models.TimeSeriesRollup
+	if err := tx.Where("source_interface_id = ? OR target_interface_id = ?", iface.ID, iface.ID).Find(&rollups).Error; err != nil {
+		return err
+	}
+	for _, rollup := range rollups {
+		if rollup.SourceInterfaceID == iface.ID && rollup.TargetInterfaceID == "" {
+			if err := schemaService.ManageTableSchema(iface.ID, "drop_table", iface.BasicLibrary.GetSchemaName(), rollup.RollupTable, []models.TableField{}); err != nil {
+				return err
+			}
+		}
+		if err := tx.Delete(&rollup).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// rollupTimeSeriesConfig 汇总表的时间序列存储配置，按bucket列分块
+func rollupTimeSeriesConfig(rollup *models.TimeSeriesRollup) *models.TimeSeriesConfig {
+	return &models.TimeSeriesConfig{
+		Enabled:         true,
+		TimeColumn:      models.RollupBucketColumn,
+		TagColumns:      append([]string{}, rollup.TagColumns...),
+		ChunkInterval:   rollupChunkInterval,
+		RetentionPeriod: rollup.RetentionPeriod,
+	}
+}
+
+// rollupTableFields 汇总表字段：时间桶、标签列和指标列；avg为双精度，sum为numeric，count为bigint，其余与源列类型相同
+func rollupTableFields(sourceFields []models.TableField, rollup *models.TimeSeriesRollup) []models.TableField {
+	byName := make(map[string]models.TableField, len(sourceFields))
+	for _, field := range sourceFields {
+		byName[field.NameEn] = field
+	}
+
+	fields := []models.TableField{{
+		NameZh:   "时间桶",
+		NameEn:   models.RollupBucketColumn,
+		DataType: byName[rollup.TimeColumn].DataType,
+		OrderNum: 1,
+	}}
+	for _, tag := range rollup.TagColumns {
+		fields = append(fields, models.TableField{
+			NameZh:     byName[tag].NameZh,
+			NameEn:     tag,
+			DataType:   byName[tag].DataType,
+			IsNullable: true,
+			OrderNum:   len(fields) + 1,
+		})
+	}
+	for _, metric := range rollup.Metrics {
+		source := byName[metric.Column]
+		dataType := source.DataType
+		switch metric.Function {
+		case models.TimeSeriesFuncAvg:
+			dataType = "double"
+		case models.TimeSeriesFuncSum:
+			dataType = "numeric"
+		case models.TimeSeriesFuncCount:
+			dataType = "bigint"
+		}
+		nameZh := source.NameZh
+		if nameZh == "" {
+			nameZh = metric.Column
+		}
+		fields = append(fields, models.TableField{
+			NameZh:     nameZh + "(" + metric.Function + ")",
+			NameEn:     metric.Alias(),
+			DataType:   dataType,
+			IsNullable: true,
+			OrderNum:   len(fields) + 1,
+		})
+	}
+	return fields
+}
+
+// validateRollup 校验任务名、时间桶、指标和间隔，回看时间默认为一个时间桶；指标不合法时返回ErrInvalidTimeSeriesRequest
+func validateRollup(fields []models.TableField, config *models.TimeSeriesConfig, rollup *models.TimeSeriesRollup) error {
+	if !timeSeriesAggregateNamePattern.MatchString(rollup.Name) {
+		return fmt.Errorf("%w: 任务名须以小写字母开头，只含小写字母、数字和下划线，不超过31个字符", ErrInvalidRollupRequest)
+	}
+	if rollup.Lookback == "" {
+		rollup.Lookback = rollup.BucketInterval
+	}
+	if err := validateRollupIntervals(rollup); err != nil {
+		return err
+	}
+	return validateTimeSeriesMetrics(fields, config, rollup.Metrics)
+}
+
+// validateRollupIntervals 校验时间桶、保留时间和回看时间，保留时间须至少两个时间桶
+func validateRollupIntervals(rollup *models.TimeSeriesRollup) error {
+	bucket, ok := models.ParseTimeSeriesInterval(rollup.BucketInterval)
+	if !ok {
+		return fmt.Errorf("%w: 时间桶宽度%s格式不正确", ErrInvalidRollupRequest, rollup.BucketInterval)
+	}
+	lookback, ok := models.ParseTimeSeriesInterval(rollup.Lookback)
+	if !ok {
+		return fmt.Errorf("%w: 回看时间%s格式不正确", ErrInvalidRollupRequest, rollup.Lookback)
+	}
+	if lookback < bucket {
+		return fmt.Errorf("%w: 回看时间不能小于一个时间桶", ErrInvalidRollupRequest)
+	}
+	if rollup.RetentionPeriod != "" {
+		retention, ok := models.ParseTimeSeriesInterval(rollup.RetentionPeriod)
+		if !ok {
+			return fmt.Errorf("%w: 保留时间%s格式不正确", ErrInvalidRollupRequest, rollup.RetentionPeriod)
+		}
+		if retention < 2*bucket {
+			return fmt.Errorf("%w: 保留时间须至少覆盖两个时间桶", ErrInvalidRollupRequest)
+		}
+	}
+	return nil
+}
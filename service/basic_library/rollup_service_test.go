@@ -0,0 +1,84 @@
+/*
+ * @module service/basic_library/rollup_service_test
+ * @description 时间序列降采样任务测试，覆盖任务名、时间桶、回看和保留时间的校验及默认值，以及汇总表字段的推导
+ * @architecture 测试层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow 构造源接口字段和时间序列配置 -> 校验降采样任务 -> 推导汇总表字段
+ * @rules 纯函数测试，不依赖数据库
+ * @dependencies stretchr/testify
+ * @refs rollup_service.go
+ */
+
+package basic_library
+
+import (
+	"datahub-service/service/models"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateRollup(t *testing.T) {
+	fields := timeSeriesTestFields()
+	config := &models.TimeSeriesConfig{Enabled: true, TimeColumn: "collected_at", TagColumns: []string{"device_id"}}
+
+	rollup := &models.TimeSeriesRollup{
+		Name: "avg_5m", BucketInterval: "5 minutes", RetentionPeriod: "1 year",
+		Metrics: models.TimeSeriesMetrics{{Column: "temperature", Function: "avg"}},
+	}
+	require.NoError(t, validateRollup(fields, config, rollup))
+	assert.Equal(t, "5 minutes", rollup.Lookback, "回看时间默认为一个时间桶")
+
+	valid := func() *models.TimeSeriesRollup {
+		return &models.TimeSeriesRollup{
+			Name: "hourly", BucketInterval: "1 hour", Lookback: "3 hours",
+			Metrics: models.TimeSeriesMetrics{{Column: "temperature", Function: "max"}},
+		}
+	}
+	for name, mutate := range map[string]func(*models.TimeSeriesRollup){
+		"任务名不合法":      func(r *models.TimeSeriesRollup) { r.Name = "Hourly" },
+		"时间桶格式错误":     func(r *models.TimeSeriesRollup) { r.BucketInterval = "hourly" },
+		"回看时间小于时间桶":   func(r *models.TimeSeriesRollup) { r.Lookback = "30 minutes" },
+		"保留时间格式错误":    func(r *models.TimeSeriesRollup) { r.RetentionPeriod = "forever" },
+		"保留时间不足两个时间桶": func(r *models.TimeSeriesRollup) { r.RetentionPeriod = "1 hour" },
+	} {
+		bad := valid()
+		mutate(bad)
+		err := validateRollup(fields, config, bad)
+		assert.True(t, errors.Is(err, ErrInvalidRollupRequest), name)
+	}
+
+	bad := valid()
+	bad.Metrics = models.TimeSeriesMetrics{{Column: "status", Function: "avg"}}
+	assert.True(t, errors.Is(validateRollup(fields, config, bad), ErrInvalidTimeSeriesRequest), "非数值列求平均")
+}
+
+func TestRollupTableFields(t *testing.T) {
+	rollup := &models.TimeSeriesRollup{
+		TimeColumn: "collected_at", TagColumns: models.JSONBStringArray{"device_id", "region"},
+		Metrics: models.TimeSeriesMetrics{
+			{Column: "temperature", Function: "avg"},
+			{Column: "temperature", Function: "sum"},
+			{Column: "status", Function: "count"},
+			{Column: "status", Function: "last"},
+		},
+	}
+	fields := rollupTableFields(timeSeriesTestFields(), rollup)
+
+	names := make([]string, len(fields))
+	types := make([]string, len(fields))
+	for i, field := range fields {
+		names[i], types[i] = field.NameEn, field.DataType
+		assert.Equal(t, i+1, field.OrderNum)
+		assert.False(t, field.IsPrimaryKey || field.IsUnique, "汇总表没有主键和唯一约束")
+	}
+	assert.Equal(t, []string{"bucket", "device_id", "region", "temperature_avg", "temperature_sum", "status_count", "status_last"}, names)
+	assert.Equal(t, []string{"timestamp", "varchar", "varchar", "double", "numeric", "bigint", "varchar"}, types)
+	assert.False(t, fields[0].IsNullable, "时间桶列不能为空")
+
+	config := rollupTimeSeriesConfig(rollup)
+	assert.Equal(t, "bucket", config.TimeColumn)
+	assert.Equal(t, []string{"device_id", "region"}, config.TagColumns)
+}
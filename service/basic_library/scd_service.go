@@ -0,0 +1,265 @@
+/*
+ * @module service/basic_library/scd_service
+ * @description 接口历史追踪（SCD Type 2）配置服务，开启时为接口表增加版本列并把主键改为代理键，关闭时删除历史版本并恢复自然键主键
+ * @architecture 分层架构 - 业务服务层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow 开启：校验自然键和跟踪列 -> 字段配置追加系统列、主键改为代理键 -> 修改表结构 -> 已有数据标记为当前版本 -> 建当前版本的自然键唯一索引 -> 保存配置；
+ *            关闭：删除非当前版本 -> 删除唯一索引 -> 字段配置去掉系统列、自然键恢复为主键 -> 修改表结构 -> 移除配置
+ * @rules 按接口版本条件保存配置，携带的期望版本或读取后被修改时返回版本冲突；不能与删除同步、时间序列存储同时开启；已开启时只能修改跟踪列，修改自然键须先关闭；历史追踪下同一自然键有多行，除系统列外不能有唯一约束；自然键不能是派生列
+ * @dependencies datahub-service/service/database, datahub-service/service/models, gorm.io/gorm
+ * @refs service/models/scd.go, service/interface_executor/scd.go, api/controllers/scd_controller.go
+ */
+
+package basic_library
+
+import (
+	"context"
+	"datahub-service/service/database"
+	"datahub-service/service/models"
+	"datahub-service/service/utils"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"gorm.io/gorm"
+)
+
+// ErrInvalidSCDRequest 历史追踪配置不合法
+var ErrInvalidSCDRequest = errors.New("历史追踪配置不合法")
+
+// SCDService 接口历史追踪配置服务
+type SCDService struct {
+	db            *gorm.DB
+	schemaService *database.SchemaService
+}
+
+// NewSCDService 创建接口历史追踪配置服务
+func NewSCDService(db *gorm.DB) *SCDService {
+	return &SCDService{db: db, schemaService: database.NewSchemaService(db)}
+}
+
+// GetSCDConfig 获取接口的历史追踪配置，未开启时返回enabled=false
+func (s *SCDService) GetSCDConfig(ctx context.Context, interfaceID string) (*models.SCDConfig, error) {
+	iface, err := getTenantInterface(ctx, s.db, interfaceID)
+	if err != nil {
+		return nil, err
+	}
+	if config := models.ParseSCDConfig(iface.InterfaceConfig); config != nil {
+		return config, nil
+	}
+	return &models.SCDConfig{NaturalKeys: []string{}, TrackedColumns: []string{}}, nil
+}
+
+// EnableSCD 开启或修改接口的历史追踪，自然键为空时使用当前主键；expectedVersion不为0时须与接口当前版本一致
+func (s *SCDService) EnableSCD(ctx context.Context, interfaceID string, expectedVersion int64, naturalKeys, trackedColumns []string, username string) (*models.SCDConfig, error) {
+	iface, err := getTenantInterface(ctx, s.db, interfaceID)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkInterfaceVersion(iface, expectedVersion); err != nil {
+		return nil, err
+	}
+	if !iface.IsTableCreated {
+		return nil, fmt.Errorf("%w: 接口表尚未创建", ErrInvalidSCDRequest)
+	}
+	fields := sortedTableFields(iface.TableFieldsConfig)
+
+	if models.ParseDeletePropagationConfig(iface.InterfaceConfig) != nil {
+		return nil, fmt.Errorf("%w: 接口已开启删除同步，不能同时开启历史追踪", ErrInvalidSCDRequest)
+	}
+	if models.ParseTimeSeriesConfig(iface.InterfaceConfig) != nil {
+		return nil, fmt.Errorf("%w: 接口已开启时间序列存储，不能同时开启历史追踪", ErrInvalidSCDRequest)
+	}
+
+	if current := models.ParseSCDConfig(iface.InterfaceConfig); current != nil {
+		// 已开启时只修改跟踪列
+		if len(naturalKeys) > 0 && !stringSliceEqual(naturalKeys, current.NaturalKeys) {
+			return nil, fmt.Errorf("%w: 已开启历史追踪，修改自然键须先关闭", ErrInvalidSCDRequest)
+		}
+		config := &models.SCDConfig{Enabled: true, NaturalKeys: current.NaturalKeys, TrackedColumns: trackedColumns}
+		if err := validateTrackedColumns(fields, config); err != nil {
+			return nil, err
+		}
+		if err := saveInterfaceConfig(ctx, s.db, iface, "interface_config", models.SCDConfigKey, config, nil); err != nil {
+			return nil, fmt.Errorf("保存历史追踪配置失败: %w", err)
+		}
+		slog.Info("修改接口历史追踪列", "interface_id", interfaceID, "tracked_columns", trackedColumns, "username", username)
+		return config, nil
+	}
+
+	if len(naturalKeys) == 0 {
+		for _, field := range fields {
+			if field.IsPrimaryKey {
+				naturalKeys = append(naturalKeys, field.NameEn)
+			}
+		}
+	}
+	config := &models.SCDConfig{Enabled: true, NaturalKeys: naturalKeys, TrackedColumns: trackedColumns}
+	newFields, err := applySCDFields(fields, config)
+	if err != nil {
+		return nil, err
+	}
+
+	schema, table := iface.BasicLibrary.GetSchemaName(), iface.NameEn
+	if err := s.schemaService.ManageTableSchema(interfaceID, "alter_table", schema, table, newFields); err != nil {
+		return nil, fmt.Errorf("修改表结构失败: %w", err)
+	}
+	fullTableName := utils.QuoteTable(schema, table)
+	if err := s.db.WithContext(ctx).Exec(fmt.Sprintf("UPDATE %s SET %s = true", fullTableName, utils.QuoteIdent(models.SCDCurrentColumn))).Error; err != nil {
+		return nil, fmt.Errorf("标记当前版本失败: %w", err)
+	}
+	indexSQL := fmt.Sprintf("CREATE UNIQUE INDEX IF NOT EXISTS %s ON %s (%s) WHERE %s",
+		utils.QuoteIdent(scdIndexName(table)), fullTableName, quoteColumns("", config.NaturalKeys), utils.QuoteIdent(models.SCDCurrentColumn))
+	if err := s.db.WithContext(ctx).Exec(indexSQL).Error; err != nil {
+		return nil, fmt.Errorf("创建当前版本唯一索引失败: %w", err)
+	}
+	if err := saveInterfaceConfig(ctx, s.db, iface, "interface_config", models.SCDConfigKey, config, newFields); err != nil {
+		return nil, fmt.Errorf("保存历史追踪配置失败: %w", err)
+	}
+
+	slog.Info("开启接口历史追踪", "interface_id", interfaceID, "natural_keys", config.NaturalKeys, "username", username)
+	return config, nil
+}
+
+// DisableSCD 关闭接口的历史追踪，删除历史版本，只保留当前版本并恢复自然键主键；expectedVersion不为0时须与接口当前版本一致
+func (s *SCDService) DisableSCD(ctx context.Context, interfaceID string, expectedVersion int64, username string) error {
+	iface, err := getTenantInterface(ctx, s.db, interfaceID)
+	if err != nil {
+		return err
+	}
+	if err := checkInterfaceVersion(iface, expectedVersion); err != nil {
+		return err
+	}
+	config := models.ParseSCDConfig(iface.InterfaceConfig)
+	if config == nil {
+		return fmt.Errorf("%w: 接口未开启历史追踪", ErrInvalidSCDRequest)
+	}
+
+	schema, table := iface.BasicLibrary.GetSchemaName(), iface.NameEn
+	fullTableName := utils.QuoteTable(schema, table)
+	result := s.db.WithContext(ctx).Exec(fmt.Sprintf("DELETE FROM %s WHERE %s = false", fullTableName, utils.QuoteIdent(models.SCDCurrentColumn)))
+	if result.Error != nil {
+		return fmt.Errorf("删除历史版本失败: %w", result.Error)
+	}
+	if err := s.db.WithContext(ctx).Exec(fmt.Sprintf("DROP INDEX IF EXISTS %s.%s", utils.QuoteIdent(schema), utils.QuoteIdent(scdIndexName(table)))).Error; err != nil {
+		return fmt.Errorf("删除当前版本唯一索引失败: %w", err)
+	}
+
+	newFields := removeSCDFields(sortedTableFields(iface.TableFieldsConfig), config)
+	if err := s.schemaService.ManageTableSchema(interfaceID, "alter_table", schema, table, newFields); err != nil {
+		return fmt.Errorf("修改表结构失败: %w", err)
+	}
+	if err := saveInterfaceConfig(ctx, s.db, iface, "interface_config", models.SCDConfigKey, nil, newFields); err != nil {
+		return fmt.Errorf("保存历史追踪配置失败: %w", err)
+	}
+
+	slog.Info("关闭接口历史追踪", "interface_id", interfaceID, "deleted_versions", result.RowsAffected, "username", username)
+	return nil
+}
+
+// applySCDFields 校验配置并生成开启历史追踪后的字段配置：自然键和原主键不再是主键，追加系统列，代理键为主键
+func applySCDFields(fields []models.TableField, config *models.SCDConfig) ([]models.TableField, error) {
+	if len(config.NaturalKeys) == 0 {
+		return nil, fmt.Errorf("%w: 自然键不能为空，接口表也没有主键", ErrInvalidSCDRequest)
+	}
+	byName := make(map[string]models.TableField, len(fields))
+	for _, field := range fields {
+		if models.IsSCDColumn(field.NameEn) {
+			return nil, fmt.Errorf("%w: 字段%s与历史追踪系统列前缀%s冲突", ErrInvalidSCDRequest, field.NameEn, models.SCDColumnPrefix)
+		}
+		byName[field.NameEn] = field
+	}
+	isKey := make(map[string]bool, len(config.NaturalKeys))
+	for _, key := range config.NaturalKeys {
+		field, ok := byName[key]
+		if !ok {
+			return nil, fmt.Errorf("%w: 自然键%s不存在", ErrInvalidSCDRequest, key)
+		}
+		if field.IsComputed() {
+			return nil, fmt.Errorf("%w: 自然键%s不能是派生列", ErrInvalidSCDRequest, key)
+		}
+		isKey[key] = true
+	}
+	if err := validateTrackedColumns(fields, config); err != nil {
+		return nil, err
+	}
+
+	result := make([]models.TableField, 0, len(fields)+4)
+	maxOrder := 0
+	for _, field := range fields {
+		if field.IsUnique && !field.IsPrimaryKey && !isKey[field.NameEn] {
+			return nil, fmt.Errorf("%w: 字段%s有唯一约束，历史追踪会为同一记录保留多行，请先取消唯一约束", ErrInvalidSCDRequest, field.NameEn)
+		}
+		if isKey[field.NameEn] {
+			field.IsNullable = false
+		}
+		if field.IsPrimaryKey || isKey[field.NameEn] {
+			field.IsPrimaryKey = false
+			field.IsUnique = false
+		}
+		if field.OrderNum > maxOrder {
+			maxOrder = field.OrderNum
+		}
+		result = append(result, field)
+	}
+	return append(result, models.SCDSystemFields(maxOrder)...), nil
+}
+
+// validateTrackedColumns 跟踪列必须存在，且不能是自然键或派生列
+func validateTrackedColumns(fields []models.TableField, config *models.SCDConfig) error {
+	byName := make(map[string]models.TableField, len(fields))
+	for _, field := range fields {
+		byName[field.NameEn] = field
+	}
+	for _, column := range config.TrackedColumns {
+		field, ok := byName[column]
+		switch {
+		case !ok || models.IsSCDColumn(column):
+			return fmt.Errorf("%w: 跟踪列%s不存在", ErrInvalidSCDRequest, column)
+		case field.IsComputed():
+			return fmt.Errorf("%w: 跟踪列%s不能是派生列", ErrInvalidSCDRequest, column)
+		case containsAll(config.NaturalKeys, []string{column}):
+			return fmt.Errorf("%w: 跟踪列%s不能是自然键", ErrInvalidSCDRequest, column)
+		}
+	}
+	return nil
+}
+
+// removeSCDFields 生成关闭历史追踪后的字段配置：去掉系统列，自然键恢复为主键
+func removeSCDFields(fields []models.TableField, config *models.SCDConfig) []models.TableField {
+	result := make([]models.TableField, 0, len(fields))
+	for _, field := range fields {
+		if models.IsSCDColumn(field.NameEn) {
+			continue
+		}
+		if containsAll(config.NaturalKeys, []string{field.NameEn}) {
+			field.IsPrimaryKey = true
+			field.IsUnique = true
+			field.IsNullable = false
+		}
+		result = append(result, field)
+	}
+	return result
+}
+
+// scdIndexName 当前版本自然键唯一索引名，不超过PostgreSQL标识符长度
+func scdIndexName(table string) string {
+	name := "scd_current_" + table
+	if len(name) > 63 {
+		name = name[:63]
+	}
+	return name
+}
+
+// stringSliceEqual 判断两个字符串切片是否按顺序相同
+func stringSliceEqual(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
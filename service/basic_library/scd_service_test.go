@@ -0,0 +1,123 @@
+/*
+ * @module service/basic_library/scd_service_test
+ * @description 接口历史追踪配置测试，覆盖开启时的字段配置转换和校验、关闭时恢复自然键主键、接口配置的解析以及按接口版本保存配置
+ * @architecture 测试层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow 构造字段配置 -> 开启转换 -> 关闭转换；读取接口 -> 按版本保存配置 -> 过期版本返回冲突
+ * @rules 字段转换为纯函数测试；保存配置使用内存sqlite
+ * @dependencies stretchr/testify, gorm.io/driver/sqlite
+ * @refs scd_service.go
+ */
+
+package basic_library
+
+import (
+	"context"
+	"datahub-service/service/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func scdTestFields() []models.TableField {
+	return []models.TableField{
+		{NameEn: "plate_no", DataType: "varchar", IsPrimaryKey: true, IsUnique: true, OrderNum: 1},
+		{NameEn: "owner_id", DataType: "varchar", IsNullable: true, OrderNum: 2},
+		{NameEn: "color", DataType: "varchar", IsNullable: true, OrderNum: 3},
+		{NameEn: "plate_len", DataType: "integer", Expression: "length(plate_no)", OrderNum: 4},
+	}
+}
+
+func TestApplySCDFields(t *testing.T) {
+	fields := scdTestFields()
+	config := &models.SCDConfig{Enabled: true, NaturalKeys: []string{"plate_no"}, TrackedColumns: []string{"owner_id"}}
+
+	result, err := applySCDFields(fields, config)
+	require.NoError(t, err)
+	require.Len(t, result, len(fields)+4)
+	assert.False(t, result[0].IsPrimaryKey, "自然键不再是主键")
+	assert.False(t, result[0].IsUnique)
+	assert.False(t, result[0].IsNullable)
+	assert.Equal(t, models.SCDKeyColumn, result[4].NameEn)
+	assert.True(t, result[4].IsPrimaryKey, "代理键为主键")
+	assert.Equal(t, 5, result[4].OrderNum)
+	assert.Equal(t, models.SCDCurrentColumn, result[7].NameEn)
+
+	for name, bad := range map[string]*models.SCDConfig{
+		"自然键不存在":  {NaturalKeys: []string{"vin"}},
+		"自然键为派生列": {NaturalKeys: []string{"plate_len"}},
+		"跟踪列为自然键": {NaturalKeys: []string{"plate_no"}, TrackedColumns: []string{"plate_no"}},
+		"跟踪列为系统列": {NaturalKeys: []string{"plate_no"}, TrackedColumns: []string{models.SCDCurrentColumn}},
+		"没有自然键":   {},
+	} {
+		_, err := applySCDFields(fields, bad)
+		assert.ErrorIs(t, err, ErrInvalidSCDRequest, name)
+	}
+
+	unique := scdTestFields()
+	unique[2].IsUnique = true
+	_, err = applySCDFields(unique, config)
+	assert.ErrorIs(t, err, ErrInvalidSCDRequest, "其他唯一字段")
+
+	_, err = applySCDFields(result, config)
+	assert.ErrorIs(t, err, ErrInvalidSCDRequest, "已有系统列")
+
+	restored := removeSCDFields(result, config)
+	require.Len(t, restored, len(fields))
+	assert.True(t, restored[0].IsPrimaryKey, "自然键恢复为主键")
+	assert.True(t, restored[0].IsUnique)
+	assert.Equal(t, fields[1:], restored[1:])
+}
+
+func TestParseSCDConfig(t *testing.T) {
+	assert.Nil(t, models.ParseSCDConfig(map[string]interface{}{}))
+	assert.Nil(t, models.ParseSCDConfig(map[string]interface{}{"scd_config": map[string]interface{}{"enabled": false, "natural_keys": []interface{}{"plate_no"}}}))
+	assert.Nil(t, models.ParseSCDConfig(map[string]interface{}{"scd_config": map[string]interface{}{"enabled": true}}), "没有自然键")
+
+	config := models.ParseSCDConfig(map[string]interface{}{"scd_config": map[string]interface{}{
+		"enabled": true, "natural_keys": []interface{}{"plate_no"}, "tracked_columns": []interface{}{"owner_id"},
+	}})
+	require.NotNil(t, config)
+	assert.Equal(t, []string{"plate_no"}, config.NaturalKeys)
+	assert.Equal(t, []string{"owner_id"}, config.TrackedColumns)
+}
+
+func TestSaveInterfaceConfigChecksRowVersion(t *testing.T) {
+	db := setupContractDB(t)
+	s := NewSCDService(db)
+	ctx := context.Background()
+
+	var conflict *models.VersionConflictError
+	_, err := s.EnableSCD(ctx, "if-1", 5, nil, nil, "alice")
+	require.ErrorAs(t, err, &conflict, "携带的期望版本与接口版本不一致")
+	assert.Equal(t, int64(1), conflict.Current)
+
+	iface, err := getTenantInterface(ctx, db, "if-1")
+	require.NoError(t, err)
+	stale := *iface
+	config := &models.SCDConfig{Enabled: true, NaturalKeys: []string{"plate_no"}}
+	require.NoError(t, saveInterfaceConfig(ctx, db, iface, "interface_config", models.SCDConfigKey, config, scdTestFields()))
+	assert.Equal(t, int64(2), iface.RowVersion)
+
+	saved, err := getTenantInterface(ctx, db, "if-1")
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), saved.RowVersion)
+	assert.NotNil(t, models.ParseSCDConfig(saved.InterfaceConfig))
+	assert.Equal(t, "/records", saved.InterfaceConfig["path"], "保留其他配置项")
+	assert.Len(t, sortedTableFields(saved.TableFieldsConfig), len(scdTestFields()))
+
+	// 读取后接口已被修改，按旧版本保存返回冲突且不覆盖
+	err = saveInterfaceConfig(ctx, db, &stale, "interface_config", models.SCDConfigKey, nil, nil)
+	require.ErrorAs(t, err, &conflict)
+	assert.Equal(t, int64(2), conflict.Current)
+	saved, err = getTenantInterface(ctx, db, "if-1")
+	require.NoError(t, err)
+	assert.NotNil(t, models.ParseSCDConfig(saved.InterfaceConfig))
+
+	require.NoError(t, saveInterfaceConfig(ctx, db, saved, "interface_config", models.SCDConfigKey, nil, nil))
+	saved, err = getTenantInterface(ctx, db, "if-1")
+	require.NoError(t, err)
+	assert.Nil(t, models.ParseSCDConfig(saved.InterfaceConfig), "value为nil时移除")
+	assert.Equal(t, int64(3), saved.RowVersion)
+}
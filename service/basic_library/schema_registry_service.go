@@ -0,0 +1,85 @@
+/*
+ * @module service/basic_library/schema_registry_service
+ * @description 消息类数据源的schema注册中心查询服务，返回基准版本映射的接口表字段，并可检查主题的其他版本与基准版本是否兼容
+ * @architecture 分层架构 - 业务服务层
+ * @documentReference ai_docs/datasource_req1.md
+ * @stateFlow 查询数据源 -> 解析参数中的注册中心配置 -> 获取基准版本 -> (指定版本时)获取该版本并比较兼容性 -> 返回字段映射
+ * @rules 只读查询；数据源未配置注册中心时返回参数错误；字段映射可直接作为接口的表字段配置，兼容性问题与消息解码时的判断一致
+ * @dependencies datahub-service/service/datasource, datahub-service/service/models, datahub-service/service/tenant, gorm.io/gorm
+ * @refs service/datasource/schema_registry.go, api/controllers/schema_registry_controller.go
+ */
+
+package basic_library
+
+import (
+	"context"
+	"datahub-service/service/datasource"
+	"datahub-service/service/models"
+	"datahub-service/service/tenant"
+	"errors"
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// ErrSchemaRegistryNotConfigured 数据源未配置schema注册中心
+var ErrSchemaRegistryNotConfigured = errors.New("数据源未配置schema注册中心")
+
+// RegistrySchema 注册中心schema及其映射的接口表字段
+type RegistrySchema struct {
+	DataSourceID string                `json:"data_source_id"`
+	Baseline     datasource.SchemaInfo `json:"baseline"` // 数据源配置的基准版本
+	Schema       datasource.SchemaInfo `json:"schema"`   // 本次查询的版本，未指定版本时同基准版本
+	Fields       []models.TableField   `json:"fields"`
+	Compatible   bool                  `json:"compatible"`         // 本次查询的版本能否按基准版本的字段写入
+	Problems     []string              `json:"problems,omitempty"` // 不兼容的原因
+}
+
+// SchemaRegistryService schema注册中心查询服务
+type SchemaRegistryService struct {
+	db *gorm.DB
+}
+
+// NewSchemaRegistryService 创建schema注册中心查询服务
+func NewSchemaRegistryService(db *gorm.DB) *SchemaRegistryService {
+	return &SchemaRegistryService{db: db}
+}
+
+// GetSchema 获取数据源注册中心主题的字段映射，version为空时返回基准版本，否则同时检查该版本与基准版本的兼容性
+func (s *SchemaRegistryService) GetSchema(ctx context.Context, dataSourceID, version string) (*RegistrySchema, error) {
+	var dataSource models.DataSource
+	if err := s.db.WithContext(ctx).Scopes(tenant.OwnerScope(ctx, "library_id", "basic_libraries")).
+		First(&dataSource, "id = ?", dataSourceID).Error; err != nil {
+		return nil, err
+	}
+
+	config, err := datasource.ParseSchemaRegistryConfig(dataSource.ParamsConfig)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrSchemaRegistryNotConfigured, err)
+	}
+	if config == nil {
+		return nil, ErrSchemaRegistryNotConfigured
+	}
+
+	decoder, err := datasource.NewSchemaDecoder(ctx, config)
+	if err != nil {
+		return nil, err
+	}
+	result := &RegistrySchema{
+		DataSourceID: dataSource.ID,
+		Baseline:     decoder.Baseline(),
+		Schema:       decoder.Baseline(),
+		Fields:       decoder.TableFields(),
+		Compatible:   true,
+	}
+	if version == "" {
+		return result, nil
+	}
+
+	result.Schema, result.Fields, result.Problems, err = decoder.CheckVersion(ctx, version)
+	if err != nil {
+		return nil, err
+	}
+	result.Compatible = len(result.Problems) == 0
+	return result, nil
+}
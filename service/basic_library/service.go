@@ -12,12 +12,18 @@
 package basic_library
 
 import (
+	"context"
+	"datahub-service/service/catalog_cache"
 	"datahub-service/service/database"
 	"datahub-service/service/datasource"
 	"datahub-service/service/models"
+	"datahub-service/service/rbac"
+	"datahub-service/service/tenant"
+	"datahub-service/service/utils"
 	"errors"
 	"fmt"
 	"log/slog"
+	"strings"
 
 	"gorm.io/gorm"
 )
@@ -30,6 +36,7 @@ type Service struct {
 	statusService         *StatusService
 	schemaService         *database.SchemaService
 	datasourceInitService *DatasourceInitService
+	healthReportService   *HealthReportService
 }
 
 // NewService 创建数据基础库服务实例
@@ -47,6 +54,7 @@ func NewService(db *gorm.DB, eventListener models.EventListener) *Service {
 	serviceInstance.interfaceService = NewInterfaceService(db, datasourceManager)
 	serviceInstance.statusService = NewStatusService(db)
 	serviceInstance.datasourceInitService = NewDatasourceInitService(db)
+	serviceInstance.healthReportService = NewHealthReportService(db)
 
 	// 如果提供了事件处理器，则注册DB事件处理器,不使用事件通知方式。代码保留备查
 	// eventListener.RegisterDBEventProcessor(serviceInstance)
@@ -110,27 +118,28 @@ func (s *Service) TableName() string {
 // === 基础CRUD操作 ===
 
 // CreateBasicLibrary 创建数据基础库
-func (s *Service) CreateBasicLibrary(library *models.BasicLibrary) error {
-	// 检查英文名称是否重复
+func (s *Service) CreateBasicLibrary(ctx context.Context, library *models.BasicLibrary) error {
+	// 检查英文名称是否重复（英文名称全局唯一，不区分租户）
 	var existing models.BasicLibrary
 	if err := s.db.Where("name_en = ?", library.NameEn).First(&existing).Error; err == nil {
 		return errors.New("基础库英文名称已存在")
 	}
-	if database.CheckSchemaExists(s.db, library.NameEn) {
+	library.SchemaName = tenant.SchemaName(ctx, library.NameEn)
+	if database.CheckSchemaExists(s.db, library.GetSchemaName()) {
 		return errors.New("基础库英文名称已存在")
 	}
-	err := database.CreateSchema(s.db, library.NameEn)
+	err := database.CreateSchema(s.db, library.GetSchemaName())
 	if err != nil {
 		return err
 	}
 
-	return s.db.Create(library).Error
+	return s.db.WithContext(ctx).Create(library).Error
 }
 
 // GetBasicLibrary 获取数据基础库详情
-func (s *Service) GetBasicLibrary(id string) (*models.BasicLibrary, error) {
+func (s *Service) GetBasicLibrary(ctx context.Context, id string) (*models.BasicLibrary, error) {
 	var library models.BasicLibrary
-	err := s.db.Preload("DataSources").Preload("Interfaces").First(&library, "id = ?", id).Error
+	err := s.db.WithContext(ctx).Preload("DataSources").Preload("Interfaces").First(&library, "id = ?", id).Error
 	if err != nil {
 		return nil, err
 	}
@@ -138,28 +147,41 @@ func (s *Service) GetBasicLibrary(id string) (*models.BasicLibrary, error) {
 }
 
 // GetBasicLibraries 获取数据基础库列表
-func (s *Service) GetBasicLibraries(page, pageSize int) ([]models.BasicLibrary, int64, error) {
+func (s *Service) GetBasicLibraries(ctx context.Context, page, pageSize int) ([]models.BasicLibrary, int64, error) {
 	var libraries []models.BasicLibrary
 	var total int64
 
 	// 获取总数
-	if err := s.db.Model(&models.BasicLibrary{}).Count(&total).Error; err != nil {
+	if err := s.db.WithContext(ctx).Model(&models.BasicLibrary{}).Count(&total).Error; err != nil {
 		return nil, 0, err
 	}
 
 	// 分页查询
 	offset := (page - 1) * pageSize
-	err := s.db.Offset(offset).Limit(pageSize).Find(&libraries).Error
+	err := s.db.WithContext(ctx).Offset(offset).Limit(pageSize).Find(&libraries).Error
 
 	return libraries, total, err
 }
 
 // GetBasicLibraryList 获取数据基础库列表（支持过滤条件）
-func (s *Service) GetBasicLibraryList(page, pageSize int, name, status, createdBy string) ([]models.BasicLibrary, int64, error) {
+func (s *Service) GetBasicLibraryList(ctx context.Context, page, pageSize int, name, status, createdBy string) ([]models.BasicLibrary, int64, error) {
+	result, err := catalog_cache.Fetch(ctx, catalog_cache.Key{
+		Name:   "basic_libraries",
+		Tables: []string{"basic_libraries", "data_sources", "data_interfaces", "resource_grants"},
+		Params: []interface{}{page, pageSize, name, status, createdBy},
+	}, func() (catalog_cache.Page[models.BasicLibrary], error) {
+		items, total, err := s.loadBasicLibraryList(ctx, page, pageSize, name, status, createdBy)
+		return catalog_cache.Page[models.BasicLibrary]{Items: items, Total: total}, err
+	})
+	return result.Items, result.Total, err
+}
+
+// loadBasicLibraryList 从数据库查询数据基础库列表（支持过滤条件）
+func (s *Service) loadBasicLibraryList(ctx context.Context, page, pageSize int, name, status, createdBy string) ([]models.BasicLibrary, int64, error) {
 	var libraries []models.BasicLibrary
 	var total int64
 
-	query := s.db.Model(&models.BasicLibrary{})
+	query := s.db.WithContext(ctx).Model(&models.BasicLibrary{}).Scopes(rbac.GrantScope(ctx, rbac.ResourceBasicLibrary, "basic_libraries.id"))
 
 	// 添加过滤条件
 	if name != "" {
@@ -187,11 +209,11 @@ func (s *Service) GetBasicLibraryList(page, pageSize int, name, status, createdB
 }
 
 // GetDataSourceList 获取数据源列表
-func (s *Service) GetDataSourceList(page, pageSize int, libraryID, category, source_type, status, name string) ([]models.DataSource, int64, error) {
+func (s *Service) GetDataSourceList(ctx context.Context, page, pageSize int, libraryID, category, source_type, status, name string) ([]models.DataSource, int64, error) {
 	var dataSources []models.DataSource
 	var total int64
 
-	query := s.db.Model(&models.DataSource{})
+	query := s.db.Model(&models.DataSource{}).Scopes(tenant.OwnerScope(ctx, "library_id", "basic_libraries"))
 
 	// 添加过滤条件
 	if libraryID != "" {
@@ -225,11 +247,25 @@ func (s *Service) GetDataSourceList(page, pageSize int, libraryID, category, sou
 }
 
 // GetDataInterfaceList 获取数据接口列表
-func (s *Service) GetDataInterfaceList(page, pageSize int, libraryID, dataSourceID, interfaceType, status, name string) ([]models.DataInterface, int64, error) {
+func (s *Service) GetDataInterfaceList(ctx context.Context, page, pageSize int, libraryID, dataSourceID, interfaceType, status, name string) ([]models.DataInterface, int64, error) {
+	result, err := catalog_cache.Fetch(ctx, catalog_cache.Key{
+		Name:   "data_interfaces",
+		Tables: []string{"data_interfaces", "basic_libraries", "data_sources", "cleansing_rules", "interface_freshness", "resource_grants"},
+		Params: []interface{}{page, pageSize, libraryID, dataSourceID, interfaceType, status, name},
+	}, func() (catalog_cache.Page[models.DataInterface], error) {
+		items, total, err := s.loadDataInterfaceList(ctx, page, pageSize, libraryID, dataSourceID, interfaceType, status, name)
+		return catalog_cache.Page[models.DataInterface]{Items: items, Total: total}, err
+	})
+	return result.Items, result.Total, err
+}
+
+// loadDataInterfaceList 从数据库查询数据接口列表
+func (s *Service) loadDataInterfaceList(ctx context.Context, page, pageSize int, libraryID, dataSourceID, interfaceType, status, name string) ([]models.DataInterface, int64, error) {
 	var interfaces []models.DataInterface
 	var total int64
 
-	query := s.db.Model(&models.DataInterface{})
+	query := s.db.Model(&models.DataInterface{}).Scopes(tenant.OwnerScope(ctx, "library_id", "basic_libraries"),
+		rbac.GrantScope(ctx, rbac.ResourceBasicLibrary, "data_interfaces.library_id"))
 
 	// 添加过滤条件
 	if libraryID != "" {
@@ -256,7 +292,7 @@ func (s *Service) GetDataInterfaceList(page, pageSize int, libraryID, dataSource
 	// 分页查询，预加载关联数据
 	offset := (page - 1) * pageSize
 	err := query.Preload("BasicLibrary").Preload("DataSource").
-		Preload("CleanRules").
+		Preload("CleanRules").Preload("Freshness").
 		Order("created_at DESC").
 		Offset(offset).Limit(pageSize).Find(&interfaces).Error
 
@@ -264,10 +300,10 @@ func (s *Service) GetDataInterfaceList(page, pageSize int, libraryID, dataSource
 }
 
 // UpdateBasicLibrary 更新数据基础库
-func (s *Service) UpdateBasicLibrary(id string, updates map[string]interface{}) error {
+func (s *Service) UpdateBasicLibrary(ctx context.Context, id string, updates map[string]interface{}) error {
 	// 检查是否存在
 	var library models.BasicLibrary
-	if err := s.db.First(&library, "id = ?", id).Error; err != nil {
+	if err := s.db.WithContext(ctx).First(&library, "id = ?", id).Error; err != nil {
 		return err
 	}
 
@@ -278,22 +314,24 @@ func (s *Service) UpdateBasicLibrary(id string, updates map[string]interface{})
 			return errors.New("基础库英文名称已存在")
 		}
 
-		// 如果更新英文名称，需要检查并创建对应的schema
+		// 如果更新英文名称，需要检查并创建对应的schema，保留原schema的租户前缀
 		if nameEnStr, ok := nameEn.(string); ok && nameEnStr != "" {
-			if !database.CheckSchemaExists(s.db, nameEnStr) {
-				err := database.CreateSchema(s.db, nameEnStr)
+			schemaName := strings.TrimSuffix(library.GetSchemaName(), library.NameEn) + nameEnStr
+			if !database.CheckSchemaExists(s.db, schemaName) {
+				err := database.CreateSchema(s.db, schemaName)
 				if err != nil {
 					return fmt.Errorf("创建schema失败: %v", err)
 				}
 			}
+			updates["schema_name"] = schemaName
 		}
 	}
 
-	return s.db.Model(&library).Updates(updates).Error
+	return s.db.WithContext(ctx).Model(&library).Updates(updates).Error
 }
 
 // DeleteBasicLibrary 删除数据基础库
-func (s *Service) DeleteBasicLibrary(library *models.BasicLibrary) error {
+func (s *Service) DeleteBasicLibrary(ctx context.Context, library *models.BasicLibrary) error {
 	// 检查是否存在关联的数据源或接口
 	var dataSourceCount, interfaceCount int64
 
@@ -319,13 +357,13 @@ func (s *Service) DeleteBasicLibrary(library *models.BasicLibrary) error {
 	}
 
 	// 2. 删除基础库记录
-	if err := tx.Delete(&models.BasicLibrary{}, "id = ?", library.ID).Error; err != nil {
+	if err := tx.WithContext(ctx).Delete(&models.BasicLibrary{}, "id = ?", library.ID).Error; err != nil {
 		tx.Rollback()
 		return fmt.Errorf("删除基础库记录失败: %w", err)
 	}
 
 	// 3. 删除数据库schema
-	err := database.DeleteSchema(s.db, library.NameEn)
+	err := database.DeleteSchema(s.db, library.GetSchemaName())
 	if err != nil {
 		tx.Rollback()
 		return fmt.Errorf("删除数据基础库schema失败: %v", err)
@@ -342,9 +380,9 @@ func (s *Service) CreateDataInterface(interfaceData *models.DataInterface) error
 	return s.interfaceService.CreateDataInterface(interfaceData)
 }
 
-// UpdateDataInterface 更新数据接口
-func (s *Service) UpdateDataInterface(id string, updates map[string]interface{}) error {
-	return s.interfaceService.UpdateDataInterface(id, updates)
+// UpdateDataInterface 更新数据接口，expectedVersion为0时不校验版本
+func (s *Service) UpdateDataInterface(id string, expectedVersion int64, updates map[string]interface{}) error {
+	return s.interfaceService.UpdateDataInterface(id, expectedVersion, updates)
 }
 
 // DeleteDataInterface 删除数据接口
@@ -389,6 +427,26 @@ func (s *Service) PreviewInterfaceData(id string, limit int) (interface{}, error
 	return s.interfaceService.PreviewInterfaceData(id, limit)
 }
 
+// SuggestFieldMapping 根据样例数据生成字段映射建议
+func (s *Service) SuggestFieldMapping(id string, sample []map[string]interface{}) (*FieldMappingSuggestion, error) {
+	return s.interfaceService.SuggestFieldMapping(id, sample)
+}
+
+// SuggestIncrementalKeys 识别可作为增量同步字段的候选
+func (s *Service) SuggestIncrementalKeys(id string, sample []map[string]interface{}) (*IncrementalKeySuggestion, error) {
+	return s.interfaceService.SuggestIncrementalKeys(id, sample)
+}
+
+// GetInterfaceJSONSchema 生成接口数据的JSON Schema
+func (s *Service) GetInterfaceJSONSchema(id string) (*utils.JSONSchema, error) {
+	return s.interfaceService.GetInterfaceJSONSchema(id)
+}
+
+// ValidateInterfacePayload 按接口的JSON Schema校验源数据
+func (s *Service) ValidateInterfacePayload(id string, data []map[string]interface{}) (*utils.PayloadValidationResult, error) {
+	return s.interfaceService.ValidateInterfacePayload(id, data)
+}
+
 // === 工具函数 ===
 
 // isValidSchemaName 验证数据库schema名称格式
@@ -444,6 +502,11 @@ func (s *Service) GetDataSource(id string) (*models.DataSource, error) {
 	return s.datasourceService.GetDataSource(id)
 }
 
+// SaveFixture 保存模拟数据源的样例数据
+func (s *Service) SaveFixture(id, key string, content []byte) error {
+	return s.datasourceService.SaveFixture(id, key, content)
+}
+
 // DeleteDataSource 删除数据源
 func (s *Service) DeleteDataSource(dataSource *models.DataSource) error {
 	return s.datasourceService.DeleteDataSource(dataSource)
@@ -458,3 +521,8 @@ func (s *Service) UpdateInterfaceFields(interfaceID string, fields []models.Tabl
 func (s *Service) GetDatasourceInitService() *DatasourceInitService {
 	return s.datasourceInitService
 }
+
+// GetHealthReportService 获取接口健康报告服务
+func (s *Service) GetHealthReportService() *HealthReportService {
+	return s.healthReportService
+}
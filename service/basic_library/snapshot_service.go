@@ -0,0 +1,535 @@
+/*
+ * @module service/basic_library/snapshot_service
+ * @description 接口表快照服务，按接口的快照策略在同步成功后复制接口表，支持按快照或时间点查询历史数据，以及比较两个快照（或快照与当前数据）的差异
+ * @architecture 分层架构 - 业务服务层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow 开启快照策略 -> 接口同步成功 -> CREATE TABLE AS复制接口表到快照schema -> 记录快照 -> 超出保留个数时删除最早的快照；
+ *            时间点查询取该时间之前最近的快照；比较按主键匹配行，统计新增、删除、修改的行和各列修改行数
+ * @rules 快照表放在models.SnapshotSchema下；比较时各列按文本比较，接口表结构变化时只比较两边都有的列；
+ *        没有主键的接口按整行比较（去重后），没有修改的概念；主键样例最多snapshotDiffSampleLimit个；快照查询走只读副本
+ * @dependencies datahub-service/service/models, datahub-service/service/tenant, gorm.io/gorm
+ * @refs service/models/interface_snapshot.go, api/controllers/snapshot_controller.go, service/basic_library/sync_task_service.go
+ */
+
+package basic_library
+
+import (
+	"context"
+	"datahub-service/service/models"
+	"datahub-service/service/utils"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/spf13/cast"
+	"gorm.io/gorm"
+)
+
+const (
+	defaultSnapshotRetention = 30   // 默认保留的快照个数
+	maxSnapshotRetention     = 365  // 最多保留的快照个数
+	defaultSnapshotPageSize  = 100  // 快照数据默认每页行数
+	maxSnapshotPageSize      = 1000 // 快照数据每页最多行数
+	snapshotDiffSampleLimit  = 100  // 比较结果中每类主键样例的个数
+)
+
+// ErrInvalidSnapshotRequest 快照请求不合法
+var ErrInvalidSnapshotRequest = errors.New("快照请求不合法")
+
+// SnapshotData 快照中的一页数据
+type SnapshotData struct {
+	Snapshot models.InterfaceTableSnapshot `json:"snapshot"`
+	Page     int                           `json:"page"`
+	Size     int                           `json:"size"`
+	Rows     []map[string]interface{}      `json:"rows"`
+}
+
+// SnapshotComparison 两个快照（或快照与当前数据）的比较结果
+type SnapshotComparison struct {
+	From           models.InterfaceTableSnapshot  `json:"from"`
+	To             *models.InterfaceTableSnapshot `json:"to"` // 为空表示与当前接口表比较
+	KeyFields      []string                       `json:"key_fields"`
+	FromRows       int64                          `json:"from_rows"`
+	ToRows         int64                          `json:"to_rows"`
+	Added          int64                          `json:"added"`
+	Removed        int64                          `json:"removed"`
+	Changed        int64                          `json:"changed"`
+	AddedKeys      models.SyncDiffKeys            `json:"added_keys"`
+	RemovedKeys    models.SyncDiffKeys            `json:"removed_keys"`
+	ChangedKeys    models.SyncDiffKeys            `json:"changed_keys"`
+	ColumnChanges  models.SyncColumnChanges       `json:"column_changes"`
+	AddedColumns   []string                       `json:"added_columns"`   // 只在新数据中存在的列
+	RemovedColumns []string                       `json:"removed_columns"` // 只在旧数据中存在的列
+}
+
+// SnapshotService 接口表快照服务
+type SnapshotService struct {
+	db     *gorm.DB
+	readDB *gorm.DB
+}
+
+// NewSnapshotService 创建接口表快照服务实例
+func NewSnapshotService(db *gorm.DB) *SnapshotService {
+	return &SnapshotService{db: db, readDB: db}
+}
+
+// SetReadDB 设置只读副本连接，快照数据查询和比较走只读副本
+func (s *SnapshotService) SetReadDB(readDB *gorm.DB) {
+	if readDB != nil {
+		s.readDB = readDB
+	}
+}
+
+// GetPolicy 获取接口的快照策略，未配置时返回默认策略（不开启）
+func (s *SnapshotService) GetPolicy(ctx context.Context, interfaceID string) (*models.InterfaceSnapshotPolicy, error) {
+	if _, err := getTenantInterface(ctx, s.db, interfaceID); err != nil {
+		return nil, err
+	}
+	policy := models.InterfaceSnapshotPolicy{InterfaceID: interfaceID, Retention: defaultSnapshotRetention}
+	if err := s.db.WithContext(ctx).Where("interface_id = ?", interfaceID).Limit(1).Find(&policy).Error; err != nil {
+		return nil, fmt.Errorf("查询快照策略失败: %w", err)
+	}
+	return &policy, nil
+}
+
+// UpdatePolicy 保存接口的快照策略，保留个数为0时使用默认值；减少保留个数时立即删除多余的快照
+func (s *SnapshotService) UpdatePolicy(ctx context.Context, interfaceID string, enabled bool, retention int, username string) (*models.InterfaceSnapshotPolicy, error) {
+	if _, err := getTenantInterface(ctx, s.db, interfaceID); err != nil {
+		return nil, err
+	}
+	if retention < 0 || retention > maxSnapshotRetention {
+		return nil, fmt.Errorf("%w: 保留个数须在1-%d之间", ErrInvalidSnapshotRequest, maxSnapshotRetention)
+	}
+	if retention == 0 {
+		retention = defaultSnapshotRetention
+	}
+
+	policy := &models.InterfaceSnapshotPolicy{
+		InterfaceID: interfaceID,
+		Enabled:     enabled,
+		Retention:   retention,
+		UpdatedBy:   username,
+		UpdatedAt:   time.Now(),
+	}
+	if err := s.db.WithContext(ctx).Save(policy).Error; err != nil {
+		return nil, fmt.Errorf("保存快照策略失败: %w", err)
+	}
+	pruneSnapshots(ctx, s.db, interfaceID, retention)
+	return policy, nil
+}
+
+// CaptureSnapshot 立即为接口表保存一次快照，不要求开启快照策略
+func (s *SnapshotService) CaptureSnapshot(ctx context.Context, interfaceID, username string) (*models.InterfaceTableSnapshot, error) {
+	iface, err := getTenantInterface(ctx, s.db, interfaceID)
+	if err != nil {
+		return nil, err
+	}
+	policy, err := s.GetPolicy(ctx, interfaceID)
+	if err != nil {
+		return nil, err
+	}
+	return captureSnapshot(ctx, s.db, iface, "", "", username, policy.Retention)
+}
+
+// ListSnapshots 按时间倒序获取接口的快照
+func (s *SnapshotService) ListSnapshots(ctx context.Context, interfaceID string) ([]models.InterfaceTableSnapshot, error) {
+	if _, err := getTenantInterface(ctx, s.db, interfaceID); err != nil {
+		return nil, err
+	}
+	snapshots := make([]models.InterfaceTableSnapshot, 0)
+	if err := s.db.WithContext(ctx).Where("interface_id = ?", interfaceID).Order("captured_at DESC").Find(&snapshots).Error; err != nil {
+		return nil, fmt.Errorf("查询快照失败: %w", err)
+	}
+	return snapshots, nil
+}
+
+// DeleteSnapshot 删除快照及其快照表
+func (s *SnapshotService) DeleteSnapshot(ctx context.Context, interfaceID, snapshotID string) error {
+	snapshot, err := s.getSnapshot(ctx, interfaceID, snapshotID)
+	if err != nil {
+		return err
+	}
+	return dropSnapshot(ctx, s.db, snapshot)
+}
+
+// GetSnapshotData 分页查询快照中的数据
+func (s *SnapshotService) GetSnapshotData(ctx context.Context, interfaceID, snapshotID string, page, size int) (*SnapshotData, error) {
+	iface, err := getTenantInterface(ctx, s.db, interfaceID)
+	if err != nil {
+		return nil, err
+	}
+	snapshot, err := s.getSnapshot(ctx, interfaceID, snapshotID)
+	if err != nil {
+		return nil, err
+	}
+	return s.readSnapshot(ctx, iface, snapshot, page, size)
+}
+
+// GetDataAsOf 分页查询接口在某一时间点的数据，即该时间之前最近一次快照的数据
+func (s *SnapshotService) GetDataAsOf(ctx context.Context, interfaceID string, asOf time.Time, page, size int) (*SnapshotData, error) {
+	iface, err := getTenantInterface(ctx, s.db, interfaceID)
+	if err != nil {
+		return nil, err
+	}
+	var snapshot models.InterfaceTableSnapshot
+	err = s.db.WithContext(ctx).Where("interface_id = ? AND captured_at <= ?", interfaceID, asOf).
+		Order("captured_at DESC").First(&snapshot).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("%w: %s之前没有快照", gorm.ErrRecordNotFound, asOf.Format(time.RFC3339))
+	}
+	if err != nil {
+		return nil, err
+	}
+	return s.readSnapshot(ctx, iface, &snapshot, page, size)
+}
+
+// CompareSnapshots 比较两个快照，toID为空时与当前接口表比较
+func (s *SnapshotService) CompareSnapshots(ctx context.Context, interfaceID, fromID, toID string) (*SnapshotComparison, error) {
+	iface, err := getTenantInterface(ctx, s.db, interfaceID)
+	if err != nil {
+		return nil, err
+	}
+	from, err := s.getSnapshot(ctx, interfaceID, fromID)
+	if err != nil {
+		return nil, err
+	}
+
+	comparison := &SnapshotComparison{
+		From:           *from,
+		KeyFields:      snapshotKeyFields(iface.TableFieldsConfig),
+		AddedKeys:      models.SyncDiffKeys{},
+		RemovedKeys:    models.SyncDiffKeys{},
+		ChangedKeys:    models.SyncDiffKeys{},
+		ColumnChanges:  models.SyncColumnChanges{},
+		AddedColumns:   []string{},
+		RemovedColumns: []string{},
+	}
+	toTable := utils.QuoteTable(iface.BasicLibrary.GetSchemaName(), iface.NameEn)
+	if toID != "" {
+		to, err := s.getSnapshot(ctx, interfaceID, toID)
+		if err != nil {
+			return nil, err
+		}
+		comparison.To = to
+		toTable = snapshotTableRef(to.SnapshotTable)
+	}
+	if err := compareTables(s.readDB.WithContext(ctx), snapshotTableRef(from.SnapshotTable), toTable, comparison); err != nil {
+		return nil, err
+	}
+	return comparison, nil
+}
+
+// readSnapshot 读取快照表的一页数据，有主键时按主键排序
+func (s *SnapshotService) readSnapshot(ctx context.Context, iface *models.DataInterface, snapshot *models.InterfaceTableSnapshot, page, size int) (*SnapshotData, error) {
+	if page <= 0 {
+		page = 1
+	}
+	if size <= 0 {
+		size = defaultSnapshotPageSize
+	}
+	if size > maxSnapshotPageSize {
+		size = maxSnapshotPageSize
+	}
+
+	query := "SELECT * FROM " + snapshotTableRef(snapshot.SnapshotTable)
+	if keys := snapshotKeyFields(iface.TableFieldsConfig); len(keys) > 0 {
+		columns, err := tableColumns(s.readDB.WithContext(ctx), snapshotTableRef(snapshot.SnapshotTable))
+		if err != nil {
+			return nil, err
+		}
+		if containsAll(columns, keys) {
+			query += " ORDER BY " + quoteColumns("", keys)
+		}
+	}
+	query += fmt.Sprintf(" LIMIT %d OFFSET %d", size, (page-1)*size)
+
+	rows := make([]map[string]interface{}, 0)
+	if err := s.readDB.WithContext(ctx).Raw(query).Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("查询快照数据失败: %w", err)
+	}
+	return &SnapshotData{Snapshot: *snapshot, Page: page, Size: size, Rows: rows}, nil
+}
+
+// getSnapshot 获取接口的快照
+func (s *SnapshotService) getSnapshot(ctx context.Context, interfaceID, snapshotID string) (*models.InterfaceTableSnapshot, error) {
+	var snapshot models.InterfaceTableSnapshot
+	if err := s.db.WithContext(ctx).First(&snapshot, "id = ? AND interface_id = ?", snapshotID, interfaceID).Error; err != nil {
+		return nil, err
+	}
+	return &snapshot, nil
+}
+
+// recordSyncSnapshot 接口同步成功后按快照策略保存快照，失败只记录日志
+func recordSyncSnapshot(ctx context.Context, db *gorm.DB, taskID, executionID, interfaceID string) {
+	var policy models.InterfaceSnapshotPolicy
+	if err := db.WithContext(ctx).Where("interface_id = ? AND enabled = ?", interfaceID, true).Limit(1).Find(&policy).Error; err != nil {
+		slog.Warn("查询快照策略失败", "interface_id", interfaceID, "error", err)
+		return
+	}
+	if policy.InterfaceID == "" {
+		return
+	}
+
+	var iface models.DataInterface
+	if err := db.WithContext(ctx).Preload("BasicLibrary").First(&iface, "id = ?", interfaceID).Error; err != nil {
+		slog.Warn("查询快照接口失败", "interface_id", interfaceID, "error", err)
+		return
+	}
+	if _, err := captureSnapshot(ctx, db, &iface, taskID, executionID, "system", policy.Retention); err != nil {
+		slog.Warn("保存接口表快照失败", "interface_id", interfaceID, "execution_id", executionID, "error", err)
+	}
+}
+
+// captureSnapshot 复制接口表到快照schema并记录快照，之后按保留个数清理
+func captureSnapshot(ctx context.Context, db *gorm.DB, iface *models.DataInterface, taskID, executionID, createdBy string, retention int) (*models.InterfaceTableSnapshot, error) {
+	if !iface.IsTableCreated {
+		return nil, fmt.Errorf("%w: 接口表尚未创建", ErrInvalidSnapshotRequest)
+	}
+	schema := iface.BasicLibrary.GetSchemaName()
+	if schema == "" || iface.NameEn == "" {
+		return nil, errors.New("接口所属基础库或接口英文名为空")
+	}
+
+	id := uuid.New().String()
+	snapshot := &models.InterfaceTableSnapshot{
+		ID:            id,
+		InterfaceID:   iface.ID,
+		TaskID:        taskID,
+		ExecutionID:   executionID,
+		SourceTable:   schema + "." + iface.NameEn,
+		SnapshotTable: "s_" + strings.ReplaceAll(id, "-", ""),
+		CreatedBy:     createdBy,
+		CapturedAt:    time.Now(),
+	}
+	source := utils.QuoteTable(schema, iface.NameEn)
+	if err := db.WithContext(ctx).Exec(fmt.Sprintf("CREATE TABLE %s AS SELECT * FROM %s", snapshotTableRef(snapshot.SnapshotTable), source)).Error; err != nil {
+		return nil, fmt.Errorf("复制接口表失败: %w", err)
+	}
+	if err := db.WithContext(ctx).Raw("SELECT COUNT(*) FROM " + snapshotTableRef(snapshot.SnapshotTable)).Scan(&snapshot.RowCount).Error; err != nil {
+		slog.Warn("统计快照行数失败", "snapshot_table", snapshot.SnapshotTable, "error", err)
+	}
+	if err := db.WithContext(ctx).Create(snapshot).Error; err != nil {
+		db.WithContext(ctx).Exec("DROP TABLE IF EXISTS " + snapshotTableRef(snapshot.SnapshotTable))
+		return nil, fmt.Errorf("记录快照失败: %w", err)
+	}
+	slog.Info("接口表快照完成", "interface_id", iface.ID, "snapshot_id", snapshot.ID, "rows", snapshot.RowCount)
+
+	pruneSnapshots(ctx, db, iface.ID, retention)
+	return snapshot, nil
+}
+
+// pruneSnapshots 删除超出保留个数的最早快照
+func pruneSnapshots(ctx context.Context, db *gorm.DB, interfaceID string, retention int) {
+	if retention <= 0 {
+		return
+	}
+	var expired []models.InterfaceTableSnapshot
+	if err := db.WithContext(ctx).Where("interface_id = ?", interfaceID).
+		Order("captured_at DESC").Offset(retention).Find(&expired).Error; err != nil {
+		slog.Warn("查询过期快照失败", "interface_id", interfaceID, "error", err)
+		return
+	}
+	for i := range expired {
+		if err := dropSnapshot(ctx, db, &expired[i]); err != nil {
+			slog.Warn("删除过期快照失败", "snapshot_id", expired[i].ID, "error", err)
+		}
+	}
+}
+
+// dropSnapshot 删除快照表和快照记录
+func dropSnapshot(ctx context.Context, db *gorm.DB, snapshot *models.InterfaceTableSnapshot) error {
+	if err := db.WithContext(ctx).Exec("DROP TABLE IF EXISTS " + snapshotTableRef(snapshot.SnapshotTable)).Error; err != nil {
+		return fmt.Errorf("删除快照表失败: %w", err)
+	}
+	return db.WithContext(ctx).Delete(&models.InterfaceTableSnapshot{}, "id = ?", snapshot.ID).Error
+}
+
+// compareTables 按主键比较旧表from和新表to，结果写入comparison
+func compareTables(db *gorm.DB, from, to string, comparison *SnapshotComparison) error {
+	fromColumns, err := tableColumns(db, from)
+	if err != nil {
+		return err
+	}
+	toColumns, err := tableColumns(db, to)
+	if err != nil {
+		return err
+	}
+	common := make([]string, 0, len(toColumns))
+	for _, column := range toColumns {
+		if containsAll(fromColumns, []string{column}) {
+			common = append(common, column)
+		} else {
+			comparison.AddedColumns = append(comparison.AddedColumns, column)
+		}
+	}
+	for _, column := range fromColumns {
+		if !containsAll(toColumns, []string{column}) {
+			comparison.RemovedColumns = append(comparison.RemovedColumns, column)
+		}
+	}
+
+	if err := db.Raw("SELECT COUNT(*) FROM " + from).Scan(&comparison.FromRows).Error; err != nil {
+		return fmt.Errorf("统计快照行数失败: %w", err)
+	}
+	if err := db.Raw("SELECT COUNT(*) FROM " + to).Scan(&comparison.ToRows).Error; err != nil {
+		return fmt.Errorf("统计快照行数失败: %w", err)
+	}
+
+	keys := comparison.KeyFields
+	if len(keys) == 0 || !containsAll(common, keys) {
+		// 没有主键或主键列已变化，按两边都有的列整行比较
+		comparison.KeyFields = []string{}
+		return compareRows(db, from, to, common, comparison)
+	}
+
+	keyJoin := make([]string, len(keys))
+	for i, key := range keys {
+		keyJoin[i] = fmt.Sprintf("CAST(t.%s AS TEXT) = CAST(f.%s AS TEXT)", utils.QuoteIdent(key), utils.QuoteIdent(key))
+	}
+	join := strings.Join(keyJoin, " AND ")
+	missing := func(outer, inner string) string {
+		return fmt.Sprintf("FROM %s t WHERE NOT EXISTS (SELECT 1 FROM %s f WHERE %s)", outer, inner, join)
+	}
+
+	if err := db.Raw("SELECT COUNT(*) " + missing(to, from)).Scan(&comparison.Added).Error; err != nil {
+		return fmt.Errorf("统计新增行失败: %w", err)
+	}
+	if err := db.Raw("SELECT COUNT(*) " + missing(from, to)).Scan(&comparison.Removed).Error; err != nil {
+		return fmt.Errorf("统计删除行失败: %w", err)
+	}
+	sampleSQL := func(where string) string {
+		return fmt.Sprintf("SELECT %s %s ORDER BY %s LIMIT %d", quoteColumns("t", keys), where, quoteColumns("t", keys), snapshotDiffSampleLimit)
+	}
+	if err := scanKeys(db, sampleSQL(missing(to, from)), &comparison.AddedKeys); err != nil {
+		return fmt.Errorf("查询新增行失败: %w", err)
+	}
+	if err := scanKeys(db, sampleSQL(missing(from, to)), &comparison.RemovedKeys); err != nil {
+		return fmt.Errorf("查询删除行失败: %w", err)
+	}
+
+	var compared []string
+	for _, column := range common {
+		if !containsAll(keys, []string{column}) {
+			compared = append(compared, column)
+		}
+	}
+	if len(compared) == 0 {
+		return nil
+	}
+	conditions := make([]string, len(compared))
+	sums := make([]string, len(compared))
+	for i, column := range compared {
+		conditions[i] = fmt.Sprintf("CAST(t.%s AS TEXT) IS DISTINCT FROM CAST(f.%s AS TEXT)", utils.QuoteIdent(column), utils.QuoteIdent(column))
+		sums[i] = fmt.Sprintf("SUM(CASE WHEN %s THEN 1 ELSE 0 END) AS c%d", conditions[i], i)
+	}
+	matched := fmt.Sprintf("FROM %s t JOIN %s f ON %s", to, from, join)
+	changed := matched + " WHERE " + strings.Join(conditions, " OR ")
+	if err := db.Raw("SELECT COUNT(*) " + changed).Scan(&comparison.Changed).Error; err != nil {
+		return fmt.Errorf("统计修改行失败: %w", err)
+	}
+	if err := scanKeys(db, sampleSQL(changed), &comparison.ChangedKeys); err != nil {
+		return fmt.Errorf("查询修改行失败: %w", err)
+	}
+
+	var totals map[string]interface{}
+	if err := db.Raw("SELECT " + strings.Join(sums, ", ") + " " + matched).Scan(&totals).Error; err != nil {
+		return fmt.Errorf("统计列修改失败: %w", err)
+	}
+	for i, column := range compared {
+		if count := cast.ToInt64(totals[fmt.Sprintf("c%d", i)]); count > 0 {
+			comparison.ColumnChanges = append(comparison.ColumnChanges, models.SyncColumnChange{Column: column, Changed: count})
+		}
+	}
+	sort.SliceStable(comparison.ColumnChanges, func(i, j int) bool {
+		return comparison.ColumnChanges[i].Changed > comparison.ColumnChanges[j].Changed
+	})
+	return nil
+}
+
+// compareRows 没有主键时按整行比较，统计只在一边出现的行（去重后）
+func compareRows(db *gorm.DB, from, to string, columns []string, comparison *SnapshotComparison) error {
+	if len(columns) == 0 {
+		return nil
+	}
+	casts := make([]string, len(columns))
+	for i, column := range columns {
+		casts[i] = fmt.Sprintf("CAST(%s AS TEXT)", utils.QuoteIdent(column))
+	}
+	selectList := strings.Join(casts, ", ")
+	except := func(left, right string) string {
+		return fmt.Sprintf("SELECT COUNT(*) FROM (SELECT %s FROM %s EXCEPT SELECT %s FROM %s) d", selectList, left, selectList, right)
+	}
+	if err := db.Raw(except(to, from)).Scan(&comparison.Added).Error; err != nil {
+		return fmt.Errorf("统计新增行失败: %w", err)
+	}
+	if err := db.Raw(except(from, to)).Scan(&comparison.Removed).Error; err != nil {
+		return fmt.Errorf("统计删除行失败: %w", err)
+	}
+	return nil
+}
+
+// scanKeys 查询主键样例
+func scanKeys(db *gorm.DB, query string, keys *models.SyncDiffKeys) error {
+	rows := make([]map[string]interface{}, 0)
+	if err := db.Raw(query).Scan(&rows).Error; err != nil {
+		return err
+	}
+	*keys = rows
+	return nil
+}
+
+// tableColumns 读取表的列名
+func tableColumns(db *gorm.DB, table string) ([]string, error) {
+	rows, err := db.Raw("SELECT * FROM " + table + " LIMIT 0").Rows()
+	if err != nil {
+		return nil, fmt.Errorf("读取表结构失败: %w", err)
+	}
+	defer rows.Close()
+	return rows.Columns()
+}
+
+// snapshotKeyFields 表字段配置中的主键字段
+func snapshotKeyFields(tableFieldsConfig models.JSONB) []string {
+	keys := []string{}
+	for _, field := range sortedTableFields(tableFieldsConfig) {
+		if field.IsPrimaryKey {
+			keys = append(keys, field.NameEn)
+		}
+	}
+	return keys
+}
+
+// snapshotTableRef 快照表的完整引用
+func snapshotTableRef(table string) string {
+	return utils.QuoteTable(models.SnapshotSchema, table)
+}
+
+// quoteColumns 为列名加引号并以逗号连接，alias不为空时加表别名前缀
+func quoteColumns(alias string, columns []string) string {
+	quoted := make([]string, len(columns))
+	for i, column := range columns {
+		quoted[i] = utils.QuoteIdent(column)
+		if alias != "" {
+			quoted[i] = alias + "." + quoted[i]
+		}
+	}
+	return strings.Join(quoted, ", ")
+}
+
+// containsAll 判断columns是否包含全部names
+func containsAll(columns, names []string) bool {
+	set := make(map[string]bool, len(columns))
+	for _, column := range columns {
+		set[column] = true
+	}
+	for _, name := range names {
+		if !set[name] {
+			return false
+		}
+	}
+	return true
+}
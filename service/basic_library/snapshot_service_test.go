@@ -0,0 +1,113 @@
+/*
+ * @module service/basic_library/snapshot_service_test
+ * @description 接口表快照服务测试，覆盖按策略在同步后保存快照、保留个数清理、时间点查询以及快照与当前数据的比较
+ * @architecture 测试层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow 准备sqlite中的基础库schema和快照schema -> 保存快照 -> 修改接口表 -> 查询和比较快照
+ * @rules 使用内存sqlite，用ATTACH模拟schema，不依赖外部服务
+ * @dependencies gorm.io/driver/sqlite, stretchr/testify
+ * @refs snapshot_service.go
+ */
+
+package basic_library
+
+import (
+	"context"
+	"datahub-service/service/models"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func setupSnapshotDB(t *testing.T) *gorm.DB {
+	db := setupReferenceDB(t)
+	require.NoError(t, db.AutoMigrate(&models.InterfaceSnapshotPolicy{}, &models.InterfaceTableSnapshot{}))
+	require.NoError(t, db.Exec(`ATTACH DATABASE ':memory:' AS `+models.SnapshotSchema).Error)
+	return db
+}
+
+func TestSyncSnapshotPolicyAndRetention(t *testing.T) {
+	db := setupSnapshotDB(t)
+	ctx := context.Background()
+	svc := NewSnapshotService(db)
+
+	recordSyncSnapshot(ctx, db, "task-1", "exec-0", "if-building")
+	snapshots, err := svc.ListSnapshots(ctx, "if-building")
+	require.NoError(t, err)
+	assert.Empty(t, snapshots, "未开启快照策略")
+
+	_, err = svc.UpdatePolicy(ctx, "if-building", true, 400, "admin")
+	assert.ErrorIs(t, err, ErrInvalidSnapshotRequest)
+	policy, err := svc.UpdatePolicy(ctx, "if-building", true, 2, "admin")
+	require.NoError(t, err)
+	assert.Equal(t, 2, policy.Retention)
+
+	for _, executionID := range []string{"exec-1", "exec-2", "exec-3"} {
+		recordSyncSnapshot(ctx, db, "task-1", executionID, "if-building")
+		time.Sleep(5 * time.Millisecond)
+	}
+	snapshots, err = svc.ListSnapshots(ctx, "if-building")
+	require.NoError(t, err)
+	require.Len(t, snapshots, 2, "超出保留个数的快照被删除")
+	assert.Equal(t, "exec-3", snapshots[0].ExecutionID)
+	assert.Equal(t, "exec-2", snapshots[1].ExecutionID)
+	assert.Equal(t, int64(2), snapshots[0].RowCount)
+	assert.Equal(t, "campus.buildings", snapshots[0].SourceTable)
+
+	var tables int64
+	require.NoError(t, db.Raw(`SELECT COUNT(*) FROM `+models.SnapshotSchema+`.sqlite_master WHERE type = 'table'`).Scan(&tables).Error)
+	assert.Equal(t, int64(2), tables, "快照表随快照一起删除")
+}
+
+func TestSnapshotAsOfAndCompare(t *testing.T) {
+	db := setupSnapshotDB(t)
+	ctx := context.Background()
+	svc := NewSnapshotService(db)
+
+	first, err := svc.CaptureSnapshot(ctx, "if-building", "admin")
+	require.NoError(t, err)
+	asOf := time.Now()
+	time.Sleep(5 * time.Millisecond)
+
+	require.NoError(t, db.Exec(`UPDATE campus.buildings SET name = 'A座' WHERE id = 1`).Error)
+	require.NoError(t, db.Exec(`DELETE FROM campus.buildings WHERE id = 2`).Error)
+	require.NoError(t, db.Exec(`INSERT INTO campus.buildings (id, name) VALUES (3, 'C栋')`).Error)
+	second, err := svc.CaptureSnapshot(ctx, "if-building", "admin")
+	require.NoError(t, err)
+
+	data, err := svc.GetDataAsOf(ctx, "if-building", asOf, 1, 10)
+	require.NoError(t, err)
+	assert.Equal(t, first.ID, data.Snapshot.ID)
+	require.Len(t, data.Rows, 2)
+	assert.Equal(t, "A栋", data.Rows[0]["name"])
+
+	_, err = svc.GetDataAsOf(ctx, "if-building", asOf.Add(-time.Hour), 1, 10)
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+
+	comparison, err := svc.CompareSnapshots(ctx, "if-building", first.ID, second.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"id"}, comparison.KeyFields)
+	assert.Equal(t, int64(2), comparison.FromRows)
+	assert.Equal(t, int64(2), comparison.ToRows)
+	assert.Equal(t, int64(1), comparison.Added)
+	assert.Equal(t, int64(1), comparison.Removed)
+	assert.Equal(t, int64(1), comparison.Changed)
+	assert.Equal(t, models.SyncDiffKeys{{"id": int64(3)}}, comparison.AddedKeys)
+	assert.Equal(t, models.SyncDiffKeys{{"id": int64(2)}}, comparison.RemovedKeys)
+	assert.Equal(t, models.SyncDiffKeys{{"id": int64(1)}}, comparison.ChangedKeys)
+	assert.Equal(t, models.SyncColumnChanges{{Column: "name", Changed: 1}}, comparison.ColumnChanges)
+
+	// 不传to时与当前数据比较
+	require.NoError(t, db.Exec(`ALTER TABLE campus.buildings ADD COLUMN floors INTEGER`).Error)
+	comparison, err = svc.CompareSnapshots(ctx, "if-building", second.ID, "")
+	require.NoError(t, err)
+	assert.Nil(t, comparison.To)
+	assert.Equal(t, int64(0), comparison.Changed)
+	assert.Equal(t, []string{"floors"}, comparison.AddedColumns)
+
+	_, err = svc.CompareSnapshots(ctx, "if-building", "missing", "")
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+}
@@ -0,0 +1,376 @@
+/*
+ * @module service/basic_library/sync_plan_service
+ * @description 同步容量规划服务，根据接口的历史执行指标估算给定批次大小和并发数下全量同步的耗时、批次数和数据库负载，供上线前调优
+ * @architecture 分层架构 - 业务服务层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow 查询接口近期成功的调用 -> 拟合单行耗时和单批开销 -> 确定待同步行数 -> 按提议参数和当前参数分别估算 -> 汇总提示
+ * @rules 历史数据取自同步任务执行记录的interface_results，只使用成功且写入行数和耗时大于0的调用；
+ *        有批次数的样本不少于3条且拟合结果合理时按"单行耗时+单批开销"估算，否则按平均吞吐估算；
+ *        待同步行数依次取请求指定值、最近一次存储容量快照、历史单次最大写入行数；估算只读，不访问源系统
+ * @dependencies datahub-service/service/config, datahub-service/service/models, gorm.io/gorm
+ * @refs health_report_service.go, sync_task_service.go, service/capacity/service.go, api/controllers/sync_plan_controller.go
+ */
+
+package basic_library
+
+import (
+	"context"
+	"datahub-service/service/config"
+	"datahub-service/service/meta"
+	"datahub-service/service/models"
+	"datahub-service/service/tenant"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/spf13/cast"
+	"gorm.io/gorm"
+)
+
+// 同步容量规划错误
+var (
+	// ErrInvalidSyncPlanRequest 规划参数不合法
+	ErrInvalidSyncPlanRequest = errors.New("同步容量规划参数不合法")
+	// ErrNoSyncHistory 接口没有可用于估算的历史执行记录
+	ErrNoSyncHistory = errors.New("接口没有可用于估算的成功执行记录")
+)
+
+const (
+	defaultSyncPlanDays = 30  // 默认统计的历史天数
+	maxSyncPlanDays     = 180 // 最多统计的历史天数
+	maxSyncPlanSamples  = 200 // 最多使用的历史样本数
+	// minFitSamples 按单行耗时和单批开销拟合所需的最少样本数
+	minFitSamples = 3
+	// parallelEfficiency 每增加一个并发的有效加速比例，源系统和数据库争用使并发无法线性加速
+	parallelEfficiency = 0.7
+	// maxSyncPlanConcurrency 并发数上限
+	maxSyncPlanConcurrency = 64
+)
+
+// 耗时模型
+const (
+	SyncCostModelRowAndBatch = "row_and_batch" // 单行耗时+单批开销，由带批次数的样本拟合
+	SyncCostModelThroughput  = "throughput"    // 平均吞吐
+)
+
+// 待同步行数来源
+const (
+	SyncPlanRowsFromRequest  = "request"
+	SyncPlanRowsFromSnapshot = "storage_snapshot"
+	SyncPlanRowsFromHistory  = "history"
+)
+
+// SyncPlanRequest 同步容量规划请求
+type SyncPlanRequest struct {
+	BatchSize   int   `json:"batch_size"`  // 提议的每批条数
+	Concurrency int   `json:"concurrency"` // 提议的并发数，默认1
+	TotalRows   int64 `json:"total_rows"`  // 待同步行数，为0时按存储快照或历史估计
+	Days        int   `json:"days"`        // 统计的历史天数，默认30
+}
+
+// SyncPlanHistory 用于估算的历史指标
+type SyncPlanHistory struct {
+	Since          time.Time `json:"since"`
+	Samples        int       `json:"samples"`         // 成功调用数
+	BatchedSamples int       `json:"batched_samples"` // 记录了批次数的调用数
+	MaxRows        int64     `json:"max_rows"`        // 单次最大写入行数
+	RowsPerSecond  float64   `json:"rows_per_second"` // 平均吞吐
+	CostModel      string    `json:"cost_model"`
+	PerRowMs       float64   `json:"per_row_ms"`   // 单行耗时
+	PerBatchMs     float64   `json:"per_batch_ms"` // 单批开销（拉取请求、事务提交等），按平均吞吐估算时为0
+}
+
+// SyncPlanEstimate 一组批次大小和并发数下的估算结果
+type SyncPlanEstimate struct {
+	BatchSize           int     `json:"batch_size"`
+	Concurrency         int     `json:"concurrency"`
+	Batches             int64   `json:"batches"`
+	DurationSeconds     float64 `json:"duration_seconds"`
+	BatchDurationMs     float64 `json:"batch_duration_ms"` // 单批耗时
+	RowsPerSecond       float64 `json:"rows_per_second"`
+	DBConnections       int     `json:"db_connections"`                   // 同时占用的数据库连接数
+	TransactionsPerSec  float64 `json:"transactions_per_second"`          // 每批一个写入事务
+	WriteBytesPerSecond float64 `json:"write_bytes_per_second,omitempty"` // 有存储快照时按平均行大小估算
+	PeakBufferedRows    int64   `json:"peak_buffered_rows"`               // 拉取和写入流水线中同时驻留内存的最大行数
+	PeakBufferedBytes   int64   `json:"peak_buffered_bytes,omitempty"`    // 有存储快照时按平均行大小估算
+}
+
+// SyncPlan 同步容量规划结果
+type SyncPlan struct {
+	InterfaceID string           `json:"interface_id"`
+	TotalRows   int64            `json:"total_rows"`
+	RowsSource  string           `json:"rows_source"`             // request, storage_snapshot, history
+	AvgRowBytes int64            `json:"avg_row_bytes,omitempty"` // 最近一次存储快照的平均行大小
+	History     SyncPlanHistory  `json:"history"`
+	Proposed    SyncPlanEstimate `json:"proposed"`
+	Current     SyncPlanEstimate `json:"current"` // 接口当前批量配置、单并发下的估算，供对比
+	Warnings    []string         `json:"warnings"`
+	GeneratedAt time.Time        `json:"generated_at"`
+}
+
+// syncCallSample 一次成功的接口调用
+type syncCallSample struct {
+	rows       int64
+	batches    int64 // 未记录批次数时为0
+	durationMs int64
+}
+
+// syncCostModel 同步耗时模型
+type syncCostModel struct {
+	name       string
+	perRowMs   float64
+	perBatchMs float64
+}
+
+// SyncPlanService 同步容量规划服务
+type SyncPlanService struct {
+	db *gorm.DB
+}
+
+// NewSyncPlanService 创建同步容量规划服务
+func NewSyncPlanService(db *gorm.DB) *SyncPlanService {
+	return &SyncPlanService{db: db}
+}
+
+// Estimate 按接口的历史执行指标估算提议参数下的全量同步耗时、批次数和数据库负载
+func (s *SyncPlanService) Estimate(ctx context.Context, interfaceID string, req *SyncPlanRequest) (*SyncPlan, error) {
+	if req.Concurrency == 0 {
+		req.Concurrency = 1
+	}
+	if req.Days == 0 {
+		req.Days = defaultSyncPlanDays
+	}
+	switch {
+	case req.BatchSize <= 0:
+		return nil, fmt.Errorf("%w: 每批条数须大于0", ErrInvalidSyncPlanRequest)
+	case req.Concurrency < 1 || req.Concurrency > maxSyncPlanConcurrency:
+		return nil, fmt.Errorf("%w: 并发数须在1-%d之间", ErrInvalidSyncPlanRequest, maxSyncPlanConcurrency)
+	case req.TotalRows < 0:
+		return nil, fmt.Errorf("%w: 待同步行数不能为负数", ErrInvalidSyncPlanRequest)
+	case req.Days < 1 || req.Days > maxSyncPlanDays:
+		return nil, fmt.Errorf("%w: 统计天数须在1-%d之间", ErrInvalidSyncPlanRequest, maxSyncPlanDays)
+	}
+
+	var iface models.DataInterface
+	if err := s.db.WithContext(ctx).Scopes(tenant.OwnerScope(ctx, "library_id", "basic_libraries")).
+		First(&iface, "id = ?", interfaceID).Error; err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	since := now.AddDate(0, 0, -req.Days)
+	samples, err := s.loadSamples(ctx, interfaceID, since)
+	if err != nil {
+		return nil, err
+	}
+	if len(samples) == 0 {
+		return nil, fmt.Errorf("%w: 最近%d天", ErrNoSyncHistory, req.Days)
+	}
+
+	model := fitSyncCostModel(samples)
+	plan := &SyncPlan{
+		InterfaceID: interfaceID,
+		History:     summarizeSyncHistory(samples, model, since),
+		Warnings:    []string{},
+		GeneratedAt: now,
+	}
+
+	var snapshot models.StorageSnapshot
+	hasSnapshot := false
+	if result := s.db.WithContext(ctx).Where("interface_id = ?", interfaceID).Order("captured_at DESC").Limit(1).Find(&snapshot); result.Error == nil && result.RowsAffected > 0 {
+		hasSnapshot = true
+		if snapshot.RowCount > 0 {
+			plan.AvgRowBytes = snapshot.TableBytes / snapshot.RowCount
+		}
+	}
+	switch {
+	case req.TotalRows > 0:
+		plan.TotalRows, plan.RowsSource = req.TotalRows, SyncPlanRowsFromRequest
+	case hasSnapshot && snapshot.RowCount > 0:
+		plan.TotalRows, plan.RowsSource = snapshot.RowCount, SyncPlanRowsFromSnapshot
+	default:
+		plan.TotalRows, plan.RowsSource = plan.History.MaxRows, SyncPlanRowsFromHistory
+		plan.Warnings = append(plan.Warnings, "没有存储容量快照，待同步行数按历史单次最大写入行数估计")
+	}
+
+	buffer := config.SyncPipelineBuffer()
+	plan.Proposed = estimateSyncPlan(model, plan.TotalRows, req.BatchSize, req.Concurrency, buffer, plan.AvgRowBytes)
+	plan.Current = estimateSyncPlan(model, plan.TotalRows, currentBatchSize(iface.InterfaceConfig, plan.TotalRows), 1, buffer, plan.AvgRowBytes)
+
+	if len(samples) < minFitSamples {
+		plan.Warnings = append(plan.Warnings, fmt.Sprintf("只有%d条历史样本，估算仅供参考", len(samples)))
+	}
+	if model.name == SyncCostModelThroughput {
+		plan.Warnings = append(plan.Warnings, "历史样本不足以区分单批开销，按平均吞吐估算，批次大小对耗时的影响未计入")
+	}
+	if maxBatchSize := config.SyncMaxBatchSize(); req.BatchSize > maxBatchSize {
+		plan.Warnings = append(plan.Warnings, fmt.Sprintf("每批条数超过上限%d，实际同步时会按上限拉取", maxBatchSize))
+	}
+	if maxBatches := config.SyncMaxBatches(); maxBatches > 0 && plan.Proposed.Batches > int64(maxBatches) {
+		plan.Warnings = append(plan.Warnings, fmt.Sprintf("批次数%d超过单次同步最多批次数%d，超出部分不会同步，需增大每批条数", plan.Proposed.Batches, maxBatches))
+	}
+	if historicalMax := maxHistoricalRowsPerSecond(samples); historicalMax > 0 && plan.Proposed.RowsPerSecond > historicalMax*2 {
+		plan.Warnings = append(plan.Warnings, "估算吞吐超过历史最高吞吐的2倍，源系统可能无法支撑该并发")
+	}
+	return plan, nil
+}
+
+// loadSamples 查询接口近期成功的调用，按执行时间倒序最多取maxSyncPlanSamples条
+func (s *SyncPlanService) loadSamples(ctx context.Context, interfaceID string, since time.Time) ([]syncCallSample, error) {
+	var executions []models.SyncTaskExecution
+	err := s.db.WithContext(ctx).
+		Where("task_id IN (?)", s.db.Model(&models.SyncTaskInterface{}).Select("task_id").Where("interface_id = ?", interfaceID)).
+		Where("start_time >= ?", since).
+		Order("start_time DESC").
+		Find(&executions).Error
+	if err != nil {
+		return nil, fmt.Errorf("查询执行记录失败: %w", err)
+	}
+
+	samples := make([]syncCallSample, 0)
+	for _, execution := range executions {
+		var results []map[string]interface{}
+		_ = decodeJSON(execution.Result["interface_results"], &results)
+		for _, result := range results {
+			if toString(result["interface_id"]) != interfaceID || result["success"] != true {
+				continue
+			}
+			sample := syncCallSample{
+				rows:       toInt64(result["updated_rows"]),
+				batches:    toInt64(result["batch_count"]),
+				durationMs: toInt64(result["duration_ms"]),
+			}
+			if sample.rows <= 0 || sample.durationMs <= 0 {
+				continue
+			}
+			samples = append(samples, sample)
+			if len(samples) >= maxSyncPlanSamples {
+				return samples, nil
+			}
+		}
+	}
+	return samples, nil
+}
+
+// fitSyncCostModel 按最小二乘拟合 耗时 = 单行耗时×行数 + 单批开销×批次数，
+// 样本不足、批次数与行数共线或拟合出负值时退化为平均吞吐
+func fitSyncCostModel(samples []syncCallSample) syncCostModel {
+	var totalRows, totalMs float64
+	var srr, srb, sbb, srd, sbd float64
+	batched := 0
+	for _, sample := range samples {
+		rows, duration := float64(sample.rows), float64(sample.durationMs)
+		totalRows += rows
+		totalMs += duration
+		if sample.batches <= 0 {
+			continue
+		}
+		batches := float64(sample.batches)
+		batched++
+		srr += rows * rows
+		srb += rows * batches
+		sbb += batches * batches
+		srd += rows * duration
+		sbd += batches * duration
+	}
+	throughput := syncCostModel{name: SyncCostModelThroughput, perRowMs: totalMs / totalRows}
+
+	det := srr*sbb - srb*srb
+	if batched < minFitSamples || det <= 1e-9*srr*sbb {
+		return throughput
+	}
+	perRowMs := (srd*sbb - sbd*srb) / det
+	perBatchMs := (srr*sbd - srb*srd) / det
+	if perRowMs <= 0 || perBatchMs < 0 {
+		return throughput
+	}
+	return syncCostModel{name: SyncCostModelRowAndBatch, perRowMs: perRowMs, perBatchMs: perBatchMs}
+}
+
+// estimateSyncPlan 估算一组批次大小和并发数下的全量同步；并发按parallelEfficiency递减加速，不超过批次数
+func estimateSyncPlan(model syncCostModel, totalRows int64, batchSize, concurrency, buffer int, avgRowBytes int64) SyncPlanEstimate {
+	estimate := SyncPlanEstimate{BatchSize: batchSize, Concurrency: concurrency, DBConnections: concurrency}
+	if totalRows <= 0 {
+		return estimate
+	}
+	estimate.Batches = (totalRows + int64(batchSize) - 1) / int64(batchSize)
+	workers := concurrency
+	if int64(workers) > estimate.Batches {
+		workers = int(estimate.Batches)
+		estimate.DBConnections = workers
+	}
+	speedup := 1 + float64(workers-1)*parallelEfficiency
+
+	serialMs := model.perRowMs*float64(totalRows) + model.perBatchMs*float64(estimate.Batches)
+	durationSeconds := serialMs / speedup / 1000
+	estimate.DurationSeconds = roundTo(durationSeconds, 2)
+	estimate.BatchDurationMs = roundTo(model.perRowMs*float64(min(int64(batchSize), totalRows))+model.perBatchMs, 2)
+	if durationSeconds > 0 {
+		rowsPerSecond := float64(totalRows) / durationSeconds
+		estimate.RowsPerSecond = roundTo(rowsPerSecond, 2)
+		estimate.TransactionsPerSec = roundTo(float64(estimate.Batches)/durationSeconds, 2)
+		if avgRowBytes > 0 {
+			estimate.WriteBytesPerSecond = roundTo(rowsPerSecond*float64(avgRowBytes), 0)
+		}
+	}
+
+	inFlight := int64(workers + max(buffer, 0))
+	estimate.PeakBufferedRows = min(inFlight*int64(batchSize), totalRows)
+	estimate.PeakBufferedBytes = estimate.PeakBufferedRows * avgRowBytes
+	return estimate
+}
+
+// summarizeSyncHistory 汇总历史样本
+func summarizeSyncHistory(samples []syncCallSample, model syncCostModel, since time.Time) SyncPlanHistory {
+	history := SyncPlanHistory{
+		Since:      since,
+		Samples:    len(samples),
+		CostModel:  model.name,
+		PerRowMs:   roundTo(model.perRowMs, 4),
+		PerBatchMs: roundTo(model.perBatchMs, 2),
+	}
+	var totalRows, totalMs int64
+	for _, sample := range samples {
+		totalRows += sample.rows
+		totalMs += sample.durationMs
+		if sample.batches > 0 {
+			history.BatchedSamples++
+		}
+		history.MaxRows = max(history.MaxRows, sample.rows)
+	}
+	history.RowsPerSecond = roundTo(float64(totalRows)/float64(totalMs)*1000, 2)
+	return history
+}
+
+// maxHistoricalRowsPerSecond 历史单次调用的最高吞吐
+func maxHistoricalRowsPerSecond(samples []syncCallSample) float64 {
+	var best float64
+	for _, sample := range samples {
+		best = math.Max(best, float64(sample.rows)/float64(sample.durationMs)*1000)
+	}
+	return best
+}
+
+// currentBatchSize 接口当前的每批条数，与同步执行一致：开启limit_config时取default_limit并受max_limit限制，否则一次拉取全部数据
+func currentBatchSize(interfaceConfig models.JSONB, totalRows int64) int {
+	limitConfig, ok := interfaceConfig[meta.DataInterfaceConfigFieldLimitConfig].(map[string]interface{})
+	if !ok || !cast.ToBool(limitConfig["enabled"]) {
+		return int(max(totalRows, 1))
+	}
+	batchSize := cast.ToInt(limitConfig["default_limit"])
+	if batchSize <= 0 {
+		batchSize = config.SyncDefaultBatchSize()
+	}
+	maxLimit := cast.ToInt(limitConfig["max_limit"])
+	if maxLimit <= 0 {
+		maxLimit = config.SyncMaxBatchSize()
+	}
+	return min(batchSize, maxLimit)
+}
+
+// roundTo 保留指定位数小数
+func roundTo(value float64, digits int) float64 {
+	scale := math.Pow(10, float64(digits))
+	return math.Round(value*scale) / scale
+}
@@ -0,0 +1,126 @@
+/*
+ * @module service/basic_library/sync_plan_service_test
+ * @description 同步容量规划测试，覆盖单行耗时和单批开销的拟合、样本不足时退化为平均吞吐、并发加速和负载估算，以及按执行记录和存储快照生成规划
+ * @architecture 测试层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow 准备sqlite中的执行记录和存储快照 -> 估算 -> 验证批次数、耗时和提示
+ * @rules 使用内存sqlite，不依赖外部服务
+ * @dependencies gorm.io/driver/sqlite, stretchr/testify
+ * @refs sync_plan_service.go
+ */
+
+package basic_library
+
+import (
+	"context"
+	"datahub-service/service/models"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFitSyncCostModel(t *testing.T) {
+	// 耗时 = 0.5ms/行 + 100ms/批
+	samples := []syncCallSample{
+		{rows: 10000, batches: 10, durationMs: 6000},
+		{rows: 10000, batches: 2, durationMs: 5200},
+		{rows: 4000, batches: 4, durationMs: 2400},
+	}
+	model := fitSyncCostModel(samples)
+	assert.Equal(t, SyncCostModelRowAndBatch, model.name)
+	assert.InDelta(t, 0.5, model.perRowMs, 1e-6)
+	assert.InDelta(t, 100, model.perBatchMs, 1e-6)
+
+	// 批次数与行数成比例时无法区分单批开销
+	collinear := []syncCallSample{
+		{rows: 1000, batches: 1, durationMs: 1000},
+		{rows: 2000, batches: 2, durationMs: 2000},
+		{rows: 3000, batches: 3, durationMs: 3000},
+	}
+	model = fitSyncCostModel(collinear)
+	assert.Equal(t, SyncCostModelThroughput, model.name)
+	assert.InDelta(t, 1, model.perRowMs, 1e-9)
+
+	model = fitSyncCostModel([]syncCallSample{{rows: 500, durationMs: 1000}})
+	assert.Equal(t, SyncCostModelThroughput, model.name, "没有批次数的样本")
+	assert.InDelta(t, 2, model.perRowMs, 1e-9)
+}
+
+func TestEstimateSyncPlan(t *testing.T) {
+	model := syncCostModel{name: SyncCostModelRowAndBatch, perRowMs: 0.5, perBatchMs: 100}
+
+	single := estimateSyncPlan(model, 100000, 1000, 1, 2, 200)
+	assert.Equal(t, int64(100), single.Batches)
+	assert.Equal(t, 60.0, single.DurationSeconds, "100000×0.5ms + 100×100ms")
+	assert.Equal(t, 600.0, single.BatchDurationMs)
+	assert.Equal(t, int64(3000), single.PeakBufferedRows, "1个写入中的批次加2个缓冲批次")
+	assert.Equal(t, int64(600000), single.PeakBufferedBytes)
+	assert.Equal(t, 1, single.DBConnections)
+
+	parallel := estimateSyncPlan(model, 100000, 1000, 4, 2, 200)
+	assert.InDelta(t, 60/3.1, parallel.DurationSeconds, 0.01, "并发按0.7递减加速")
+	assert.Equal(t, 4, parallel.DBConnections)
+	assert.Greater(t, parallel.TransactionsPerSec, single.TransactionsPerSec)
+
+	few := estimateSyncPlan(model, 1500, 1000, 8, 2, 0)
+	assert.Equal(t, int64(2), few.Batches)
+	assert.Equal(t, 2, few.DBConnections, "并发不超过批次数")
+	assert.Equal(t, int64(1500), few.PeakBufferedRows)
+	assert.Zero(t, few.WriteBytesPerSecond, "没有平均行大小")
+}
+
+func TestSyncPlanEstimate(t *testing.T) {
+	db, task := setupDiagnosticsDB(t)
+	require.NoError(t, db.AutoMigrate(&models.StorageSnapshot{}))
+	s := NewSyncPlanService(db)
+	ctx := context.Background()
+
+	_, err := s.Estimate(ctx, "if-1", &SyncPlanRequest{BatchSize: 1000})
+	assert.ErrorIs(t, err, ErrNoSyncHistory)
+	_, err = s.Estimate(ctx, "if-1", &SyncPlanRequest{BatchSize: 0})
+	assert.ErrorIs(t, err, ErrInvalidSyncPlanRequest)
+
+	now := time.Now()
+	calls := []map[string]interface{}{
+		{"rows": 10000, "batches": 10, "ms": 6000},
+		{"rows": 10000, "batches": 2, "ms": 5200},
+		{"rows": 4000, "batches": 4, "ms": 2400},
+	}
+	for i, call := range calls {
+		result := newInterfaceCallResult("if-1", true, int64(call["ms"].(int)), int64(call["rows"].(int)), "")
+		result["batch_count"] = call["batches"]
+		require.NoError(t, db.Create(&models.SyncTaskExecution{
+			ID: fmt.Sprintf("exec-%d", i), TaskID: task.ID, ExecutionType: "scheduled", Status: "success", StartTime: now.Add(-time.Duration(i) * time.Hour),
+			Result: models.JSONB{"interface_results": []interface{}{result, newInterfaceCallResult("if-other", true, 10, 10, "")}},
+		}).Error)
+	}
+	require.NoError(t, db.Create(&models.SyncTaskExecution{
+		ID: "exec-failed", TaskID: task.ID, ExecutionType: "scheduled", Status: "failed", StartTime: now,
+		Result: models.JSONB{"interface_results": []interface{}{newInterfaceCallResult("if-1", false, 30000, 0, "timeout")}},
+	}).Error)
+
+	plan, err := s.Estimate(ctx, "if-1", &SyncPlanRequest{BatchSize: 2000, Concurrency: 2})
+	require.NoError(t, err)
+	assert.Equal(t, 3, plan.History.Samples, "失败的调用和其他接口不计入")
+	assert.Equal(t, SyncCostModelRowAndBatch, plan.History.CostModel)
+	assert.Equal(t, SyncPlanRowsFromHistory, plan.RowsSource)
+	assert.Equal(t, int64(10000), plan.TotalRows)
+	assert.Equal(t, int64(5), plan.Proposed.Batches)
+	assert.Equal(t, int64(1), plan.Current.Batches, "接口未开启批量配置时一次拉取")
+	assert.NotEmpty(t, plan.Warnings)
+
+	require.NoError(t, db.Create(&models.StorageSnapshot{
+		LibraryType: "basic_library", LibraryID: "lib-1", InterfaceID: "if-1", SchemaName: "traffic", TableName: "vehicle_pass",
+		RowCount: 1000000, TableBytes: 150000000, CapturedAt: now,
+	}).Error)
+	plan, err = s.Estimate(ctx, "if-1", &SyncPlanRequest{BatchSize: 5000, Concurrency: 4})
+	require.NoError(t, err)
+	assert.Equal(t, SyncPlanRowsFromSnapshot, plan.RowsSource)
+	assert.Equal(t, int64(150), plan.AvgRowBytes)
+	assert.Equal(t, int64(200), plan.Proposed.Batches)
+	assert.InDelta(t, (1000000*0.5+200*100)/1000/3.1, plan.Proposed.DurationSeconds, 0.01)
+	assert.Greater(t, plan.Proposed.WriteBytesPerSecond, 0.0)
+}
@@ -0,0 +1,192 @@
+/*
+ * @module service/basic_library/sync_task_batch_service
+ * @description 同步任务批量操作，按基础库、数据源、状态等条件选出任务后逐个激活、暂停、恢复、删除或启动执行，返回每个任务的处理结果
+ * @architecture 分层架构 - 业务服务层
+ * @documentReference ai_docs/refactor_sync_task.md
+ * @stateFlow 校验动作和筛选条件 -> 查询匹配任务 -> (dry_run时直接返回匹配任务) -> 逐个执行动作 -> 汇总成功和失败数
+ * @rules 至少指定一个筛选条件，避免误操作全部任务；单次最多处理500个任务；
+ *        每个任务复用单任务操作的状态校验，单个任务失败不影响其他任务；启动执行可由调用方注入，便于登记长时操作
+ * @dependencies gorm.io/gorm, service/models, service/meta
+ * @refs sync_task_service.go, api/controllers/sync_task_controller.go
+ */
+
+package basic_library
+
+import (
+	"context"
+	"datahub-service/service/meta"
+	"datahub-service/service/models"
+	"errors"
+	"fmt"
+	"log/slog"
+)
+
+// 同步任务批量操作动作
+const (
+	SyncTaskBatchActionActivate = "activate"
+	SyncTaskBatchActionPause    = "pause"
+	SyncTaskBatchActionResume   = "resume"
+	SyncTaskBatchActionDelete   = "delete"
+	SyncTaskBatchActionRun      = "run"
+)
+
+// maxSyncTaskBatchSize 单次批量操作最多处理的任务数
+const maxSyncTaskBatchSize = 500
+
+// ErrInvalidSyncTaskBatch 批量操作请求不合法
+var ErrInvalidSyncTaskBatch = errors.New("批量操作请求不合法")
+
+// SyncTaskBatchFilter 批量操作的任务筛选条件，多个条件同时满足
+type SyncTaskBatchFilter struct {
+	TaskIDs      []string `json:"task_ids,omitempty"`
+	LibraryID    string   `json:"library_id,omitempty"`
+	DataSourceID string   `json:"data_source_id,omitempty"`
+	Status       string   `json:"status,omitempty"` // draft, active, paused
+	TaskType     string   `json:"task_type,omitempty"`
+}
+
+// IsEmpty 是否未指定任何筛选条件
+func (f SyncTaskBatchFilter) IsEmpty() bool {
+	return len(f.TaskIDs) == 0 && f.LibraryID == "" && f.DataSourceID == "" && f.Status == "" && f.TaskType == ""
+}
+
+// SyncTaskBatchRequest 同步任务批量操作请求
+type SyncTaskBatchRequest struct {
+	Action string              `json:"action"` // activate, pause, resume, delete, run
+	Filter SyncTaskBatchFilter `json:"filter"`
+	DryRun bool                `json:"dry_run"` // 只返回匹配的任务，不执行动作
+}
+
+// SyncTaskBatchItemResult 单个任务的批量操作结果
+type SyncTaskBatchItemResult struct {
+	TaskID       string `json:"task_id"`
+	LibraryID    string `json:"library_id"`
+	DataSourceID string `json:"data_source_id"`
+	Status       string `json:"status"` // 操作前的任务状态
+	Success      bool   `json:"success"`
+	Error        string `json:"error,omitempty"`
+	OperationID  string `json:"operation_id,omitempty"` // 启动执行时登记的长时操作ID
+}
+
+// SyncTaskBatchResponse 同步任务批量操作响应
+type SyncTaskBatchResponse struct {
+	Action       string                    `json:"action"`
+	DryRun       bool                      `json:"dry_run"`
+	Matched      int                       `json:"matched"`
+	SuccessCount int                       `json:"success_count"`
+	FailedCount  int                       `json:"failed_count"`
+	Results      []SyncTaskBatchItemResult `json:"results"`
+}
+
+// SyncTaskRunFunc 启动单个任务的执行，返回登记的长时操作ID
+type SyncTaskRunFunc func(ctx context.Context, taskID string) (string, error)
+
+// BatchOperateSyncTasks 按筛选条件批量操作同步任务，run为空时直接启动任务执行
+func (s *SyncTaskService) BatchOperateSyncTasks(ctx context.Context, req *SyncTaskBatchRequest, run SyncTaskRunFunc) (*SyncTaskBatchResponse, error) {
+	if err := validateSyncTaskBatchRequest(req); err != nil {
+		return nil, err
+	}
+
+	query := s.db.WithContext(ctx).Model(&models.SyncTask{}).Where("library_type = ?", meta.LibraryTypeBasic)
+	if len(req.Filter.TaskIDs) > 0 {
+		query = query.Where("id IN ?", req.Filter.TaskIDs)
+	}
+	if req.Filter.LibraryID != "" {
+		query = query.Where("library_id = ?", req.Filter.LibraryID)
+	}
+	if req.Filter.DataSourceID != "" {
+		query = query.Where("data_source_id = ?", req.Filter.DataSourceID)
+	}
+	if req.Filter.Status != "" {
+		query = query.Where("status = ?", req.Filter.Status)
+	}
+	if req.Filter.TaskType != "" {
+		query = query.Where("task_type = ?", req.Filter.TaskType)
+	}
+
+	var tasks []models.SyncTask
+	if err := query.Select("id", "library_id", "data_source_id", "status").Order("created_at").
+		Limit(maxSyncTaskBatchSize + 1).Find(&tasks).Error; err != nil {
+		return nil, fmt.Errorf("查询匹配任务失败: %w", err)
+	}
+	if len(tasks) > maxSyncTaskBatchSize {
+		return nil, fmt.Errorf("%w: 匹配的任务超过%d个，请缩小筛选范围", ErrInvalidSyncTaskBatch, maxSyncTaskBatchSize)
+	}
+
+	response := &SyncTaskBatchResponse{
+		Action:  req.Action,
+		DryRun:  req.DryRun,
+		Matched: len(tasks),
+		Results: make([]SyncTaskBatchItemResult, 0, len(tasks)),
+	}
+	for _, task := range tasks {
+		item := SyncTaskBatchItemResult{
+			TaskID:       task.ID,
+			LibraryID:    task.LibraryID,
+			DataSourceID: task.DataSourceID,
+			Status:       task.Status,
+		}
+		if req.DryRun {
+			item.Success = true
+			response.Results = append(response.Results, item)
+			continue
+		}
+
+		operationID, err := s.applySyncTaskBatchAction(ctx, req.Action, task.ID, run)
+		if err != nil {
+			item.Error = err.Error()
+			response.FailedCount++
+		} else {
+			item.Success = true
+			item.OperationID = operationID
+			response.SuccessCount++
+		}
+		response.Results = append(response.Results, item)
+	}
+
+	if !req.DryRun {
+		slog.Info("同步任务批量操作完成", "action", req.Action, "matched", response.Matched,
+			"success", response.SuccessCount, "failed", response.FailedCount)
+	}
+	return response, nil
+}
+
+// applySyncTaskBatchAction 对单个任务执行批量操作动作
+func (s *SyncTaskService) applySyncTaskBatchAction(ctx context.Context, action, taskID string, run SyncTaskRunFunc) (string, error) {
+	switch action {
+	case SyncTaskBatchActionActivate:
+		return "", s.ActivateSyncTask(ctx, taskID)
+	case SyncTaskBatchActionPause:
+		return "", s.PauseSyncTask(ctx, taskID)
+	case SyncTaskBatchActionResume:
+		return "", s.ResumeSyncTask(ctx, taskID)
+	case SyncTaskBatchActionDelete:
+		return "", s.DeleteSyncTask(ctx, taskID)
+	case SyncTaskBatchActionRun:
+		if run != nil {
+			return run(ctx, taskID)
+		}
+		return "", s.StartSyncTask(ctx, taskID)
+	}
+	return "", fmt.Errorf("%w: 不支持的动作%s", ErrInvalidSyncTaskBatch, action)
+}
+
+// validateSyncTaskBatchRequest 校验批量操作的动作和筛选条件
+func validateSyncTaskBatchRequest(req *SyncTaskBatchRequest) error {
+	switch req.Action {
+	case SyncTaskBatchActionActivate, SyncTaskBatchActionPause, SyncTaskBatchActionResume,
+		SyncTaskBatchActionDelete, SyncTaskBatchActionRun:
+	default:
+		return fmt.Errorf("%w: 不支持的动作%s", ErrInvalidSyncTaskBatch, req.Action)
+	}
+	if req.Filter.IsEmpty() {
+		return fmt.Errorf("%w: 至少指定一个筛选条件", ErrInvalidSyncTaskBatch)
+	}
+	if req.Filter.Status != "" && !meta.IsValidTaskStatus(req.Filter.Status) {
+		return fmt.Errorf("%w: 无效的任务状态%s", ErrInvalidSyncTaskBatch, req.Filter.Status)
+	}
+	if len(req.Filter.TaskIDs) > maxSyncTaskBatchSize {
+		return fmt.Errorf("%w: 任务ID最多%d个", ErrInvalidSyncTaskBatch, maxSyncTaskBatchSize)
+	}
+	return nil
+}
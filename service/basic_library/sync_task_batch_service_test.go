@@ -0,0 +1,129 @@
+/*
+ * @module service/basic_library/sync_task_batch_service_test
+ * @description 同步任务批量操作测试，覆盖请求校验、dry_run、按条件暂停和激活的逐个结果、启动执行的注入和按任务ID删除
+ * @architecture 测试层
+ * @documentReference ai_docs/refactor_sync_task.md
+ * @stateFlow 准备sqlite中不同基础库和状态的任务 -> 批量操作 -> 验证每个任务的结果和任务状态
+ * @rules 使用内存sqlite，任务均为手动触发，不向调度器添加任务
+ * @dependencies gorm.io/driver/sqlite, stretchr/testify, github.com/robfig/cron/v3
+ * @refs sync_task_batch_service.go
+ */
+
+package basic_library
+
+import (
+	"context"
+	"datahub-service/service/models"
+	"errors"
+	"testing"
+
+	"github.com/robfig/cron/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func setupSyncTaskBatchDB(t *testing.T) (*gorm.DB, *SyncTaskService) {
+	db, _ := setupDiagnosticsDB(t)
+	for _, task := range []*models.SyncTask{
+		{ID: "task-2", LibraryType: "basic_library", LibraryID: "lib-1", DataSourceID: "ds-1", TaskType: "batch_sync", Status: "active"},
+		{ID: "task-3", LibraryType: "basic_library", LibraryID: "lib-1", DataSourceID: "ds-1", TaskType: "batch_sync", Status: "paused"},
+		{ID: "task-4", LibraryType: "basic_library", LibraryID: "lib-2", DataSourceID: "ds-2", TaskType: "batch_sync", Status: "active"},
+	} {
+		require.NoError(t, db.Create(task).Error)
+	}
+	return db, &SyncTaskService{db: db, cron: cron.New(cron.WithSeconds()), ctx: context.Background()}
+}
+
+func taskStatus(t *testing.T, db *gorm.DB, taskID string) string {
+	var task models.SyncTask
+	require.NoError(t, db.First(&task, "id = ?", taskID).Error)
+	return task.Status
+}
+
+func TestBatchOperateSyncTasks_Validation(t *testing.T) {
+	_, svc := setupSyncTaskBatchDB(t)
+	ctx := context.Background()
+
+	for i, req := range []*SyncTaskBatchRequest{
+		{Action: "archive", Filter: SyncTaskBatchFilter{LibraryID: "lib-1"}},
+		{Action: SyncTaskBatchActionPause},
+		{Action: SyncTaskBatchActionPause, Filter: SyncTaskBatchFilter{Status: "running"}},
+	} {
+		_, err := svc.BatchOperateSyncTasks(ctx, req, nil)
+		assert.ErrorIs(t, err, ErrInvalidSyncTaskBatch, "case %d", i)
+	}
+}
+
+func TestBatchOperateSyncTasks_DryRunAndPause(t *testing.T) {
+	db, svc := setupSyncTaskBatchDB(t)
+	ctx := context.Background()
+
+	resp, err := svc.BatchOperateSyncTasks(ctx, &SyncTaskBatchRequest{
+		Action: SyncTaskBatchActionPause, Filter: SyncTaskBatchFilter{LibraryID: "lib-1"}, DryRun: true,
+	}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 3, resp.Matched)
+	assert.Zero(t, resp.SuccessCount)
+	assert.Equal(t, "active", taskStatus(t, db, "task-2"), "dry_run不改变任务")
+
+	resp, err = svc.BatchOperateSyncTasks(ctx, &SyncTaskBatchRequest{
+		Action: SyncTaskBatchActionPause, Filter: SyncTaskBatchFilter{LibraryID: "lib-1"},
+	}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 3, resp.Matched)
+	assert.Equal(t, 1, resp.SuccessCount)
+	assert.Equal(t, 2, resp.FailedCount)
+	results := make(map[string]SyncTaskBatchItemResult)
+	for _, item := range resp.Results {
+		results[item.TaskID] = item
+	}
+	assert.True(t, results["task-2"].Success)
+	assert.Equal(t, "active", results["task-2"].Status, "结果记录操作前的状态")
+	assert.False(t, results["task-1"].Success)
+	assert.Contains(t, results["task-1"].Error, "不允许暂停")
+	assert.Equal(t, "paused", taskStatus(t, db, "task-2"))
+	assert.Equal(t, "active", taskStatus(t, db, "task-4"), "其他基础库的任务不受影响")
+
+	resp, err = svc.BatchOperateSyncTasks(ctx, &SyncTaskBatchRequest{
+		Action: SyncTaskBatchActionActivate, Filter: SyncTaskBatchFilter{LibraryID: "lib-1", Status: "paused"},
+	}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 2, resp.SuccessCount)
+	assert.Equal(t, "active", taskStatus(t, db, "task-3"))
+}
+
+func TestBatchOperateSyncTasks_RunAndDelete(t *testing.T) {
+	db, svc := setupSyncTaskBatchDB(t)
+	ctx := context.Background()
+
+	var started []string
+	run := func(ctx context.Context, taskID string) (string, error) {
+		if taskID == "task-4" {
+			return "", errors.New("任务状态不允许启动")
+		}
+		started = append(started, taskID)
+		return "op-" + taskID, nil
+	}
+	resp, err := svc.BatchOperateSyncTasks(ctx, &SyncTaskBatchRequest{
+		Action: SyncTaskBatchActionRun, Filter: SyncTaskBatchFilter{Status: "active"},
+	}, run)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"task-2"}, started)
+	assert.Equal(t, 1, resp.SuccessCount)
+	assert.Equal(t, 1, resp.FailedCount)
+	for _, item := range resp.Results {
+		if item.TaskID == "task-2" {
+			assert.Equal(t, "op-task-2", item.OperationID)
+		}
+	}
+
+	resp, err = svc.BatchOperateSyncTasks(ctx, &SyncTaskBatchRequest{
+		Action: SyncTaskBatchActionDelete, Filter: SyncTaskBatchFilter{TaskIDs: []string{"task-3", "task-4"}},
+	}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 2, resp.SuccessCount)
+	var count int64
+	require.NoError(t, db.Model(&models.SyncTask{}).Where("id IN ?", []string{"task-3", "task-4"}).Count(&count).Error)
+	assert.Zero(t, count)
+}
@@ -0,0 +1,85 @@
+/*
+ * @module service/basic_library/sync_task_operation
+ * @description 基础库同步任务启动操作的执行方，按任务和执行记录刷新长时操作的状态、进度和结果链接，取消时停止任务执行
+ * @architecture 分层架构 - 业务服务层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow 操作创建后执行面创建执行记录 -> 按任务ID和开始时间找到本次执行 -> running/success/failed/cancelled映射为操作状态
+ * @rules 执行记录由执行面异步创建，找到前按任务的执行状态和进度判断；任务已删除时操作记为失败；取消沿用停止同步任务的语义
+ * @dependencies datahub-service/service/models, gorm.io/gorm
+ * @refs service/operation/service.go, sync_task_service.go
+ */
+
+package basic_library
+
+import (
+	"context"
+	"datahub-service/service/meta"
+	"datahub-service/service/models"
+	"errors"
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// RefreshOperation 按同步任务和本次执行记录刷新启动操作
+func (s *SyncTaskService) RefreshOperation(ctx context.Context, op *models.Operation) error {
+	var task models.SyncTask
+	if err := s.db.WithContext(ctx).Select("id", "execution_status", "progress", "processed_rows", "error_message", "updated_at").
+		First(&task, "id = ?", op.TargetID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			op.Status = models.OperationStatusFailed
+			op.Error = "同步任务已删除"
+			return nil
+		}
+		return fmt.Errorf("获取同步任务失败: %w", err)
+	}
+
+	var execution models.SyncTaskExecution
+	query := s.db.WithContext(ctx).Where("task_id = ?", op.TargetID)
+	if op.ExecutionID != "" {
+		query = query.Where("id = ?", op.ExecutionID)
+	} else {
+		query = query.Where("start_time >= ?", op.CreatedAt).Order("start_time")
+	}
+	err := query.First(&execution).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return fmt.Errorf("获取执行记录失败: %w", err)
+	}
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		// 执行记录尚未创建：分发失败或任务没有接口时任务直接记为失败
+		switch {
+		case task.ExecutionStatus == meta.SyncExecutionStatusRunning:
+			op.Status = models.OperationStatusRunning
+			op.Progress = task.Progress
+			op.Message = "等待执行面开始执行"
+		case task.ExecutionStatus == meta.SyncExecutionStatusFailed && !task.UpdatedAt.Before(op.CreatedAt):
+			op.Status = models.OperationStatusFailed
+			op.Error = task.ErrorMessage
+		}
+		return nil
+	}
+
+	op.ExecutionID = execution.ID
+	op.ResultLink = "/sync/tasks/executions/" + execution.ID
+	switch execution.Status {
+	case meta.SyncExecutionStatusRunning:
+		op.Status = models.OperationStatusRunning
+		op.Progress = max(execution.Progress, task.Progress)
+		op.Message = fmt.Sprintf("已处理%d行", max(execution.ProcessedRows, task.ProcessedRows))
+	case meta.SyncExecutionStatusSuccess:
+		op.Status = models.OperationStatusSucceeded
+		op.Message = fmt.Sprintf("同步完成，处理%d行", execution.ProcessedRows)
+	case meta.SyncExecutionStatusFailed:
+		op.Status = models.OperationStatusFailed
+		op.Error = execution.ErrorMessage
+	case "cancelled":
+		op.Status = models.OperationStatusCanceled
+	}
+	return nil
+}
+
+// CancelOperation 取消启动操作，停止正在执行的同步任务
+func (s *SyncTaskService) CancelOperation(ctx context.Context, op *models.Operation) error {
+	return s.StopSyncTask(ctx, op.TargetID)
+}
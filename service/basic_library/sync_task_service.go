@@ -4,7 +4,10 @@
  * @architecture 分层架构 - 服务层，集成调度功能
  * @documentReference ai_docs/refactor_sync_task.md
  * @stateFlow 服务初始化 -> 任务CRUD操作 -> 任务执行管理 -> 调度器管理
- * @rules 专门支持基础库同步任务，统一使用interface_executor执行
+ * @rules 专门支持基础库同步任务，统一使用interface_executor执行；任务配置了retry_policy时，可重试的接口失败按策略重试；
+ *        数据源维护中调度跳过并记录skipped执行，手动启动返回维护中错误；设置了配额时创建任务检查任务数、启动同步检查行数和存储；
+ *        数据源熔断中调度跳过并记录skipped执行，手动启动返回熔断错误，接口执行的源端失败和成功计入熔断
+ *        接口同步成功后比较开启了自动比较的基准数据集，结果记入接口执行结果的golden_status，不影响同步结果
  * @dependencies gorm.io/gorm, service/models, service/meta, service/interface_executor, github.com/robfig/cron/v3
  * @refs api/controllers/sync_task_controller.go, service/interface_executor
  */
@@ -13,13 +16,24 @@ package basic_library
 
 import (
 	"context"
+	"datahub-service/logger"
+	"datahub-service/service/capacity"
+	"datahub-service/service/chaos"
 	"datahub-service/service/datasource"
 	"datahub-service/service/distributed_lock"
+	"datahub-service/service/eventlog"
+	"datahub-service/service/execution"
 	"datahub-service/service/interface_executor"
 	"datahub-service/service/meta"
+	"datahub-service/service/metrics"
 	"datahub-service/service/models"
+	"datahub-service/service/tenant"
+	"datahub-service/service/tracing"
+	"errors"
 	"fmt"
 	"log/slog"
+	"runtime/debug"
+	"strings"
 	"time"
 
 	"github.com/robfig/cron/v3"
@@ -41,8 +55,8 @@ func NewBasicLibraryHandler(db *gorm.DB, service *Service) *BasicLibraryHandler
 }
 
 // ValidateLibrary 验证基础库是否存在
-func (h *BasicLibraryHandler) ValidateLibrary(libraryID string) error {
-	_, err := h.service.GetBasicLibrary(libraryID)
+func (h *BasicLibraryHandler) ValidateLibrary(ctx context.Context, libraryID string) error {
+	_, err := h.service.GetBasicLibrary(ctx, libraryID)
 	if err != nil {
 		return fmt.Errorf("基础库不存在: %w", err)
 	}
@@ -72,8 +86,8 @@ func (h *BasicLibraryHandler) ValidateInterface(libraryID, interfaceID string) e
 }
 
 // GetLibraryInfo 获取基础库信息
-func (h *BasicLibraryHandler) GetLibraryInfo(libraryID string) (interface{}, error) {
-	return h.service.GetBasicLibrary(libraryID)
+func (h *BasicLibraryHandler) GetLibraryInfo(ctx context.Context, libraryID string) (interface{}, error) {
+	return h.service.GetBasicLibrary(ctx, libraryID)
 }
 
 // PrepareTaskConfig 准备基础库任务配置
@@ -125,6 +139,16 @@ type SyncTaskService struct {
 	schedulerStarted bool
 	// 分布式锁
 	distributedLock distributed_lock.DistributedLock
+	// 执行命令分发器，为空时在本进程直接执行
+	dispatcher execution.Dispatcher
+	// 质量门禁，为空时不检查
+	qualityGate *QualityGateService
+	// 数据值告警，为空时写入后不判定
+	valueAlerts *ValueAlertService
+	// 库和租户配额，为空时不检查
+	quota *capacity.QuotaService
+	// 数据源熔断，为空时不检查
+	circuit *DataSourceCircuitService
 }
 
 // NewSyncTaskService 创建基础库同步任务服务
@@ -155,10 +179,17 @@ func NewSyncTaskService(db *gorm.DB, basicLibService *Service) *SyncTaskService
 // CreateSyncTask 创建基础库同步任务
 func (s *SyncTaskService) CreateSyncTask(ctx context.Context, req *CreateSyncTaskRequest) (*models.SyncTask, error) {
 	// 验证库存在
-	if err := s.handler.ValidateLibrary(req.LibraryID); err != nil {
+	if err := s.handler.ValidateLibrary(ctx, req.LibraryID); err != nil {
 		return nil, err
 	}
 
+	// 检查库和所属租户的任务数配额
+	if s.quota != nil {
+		if err := s.quota.CheckTaskCreation(ctx, req.LibraryID); err != nil {
+			return nil, err
+		}
+	}
+
 	// 验证数据源
 	if err := s.handler.ValidateDataSource(req.LibraryID, req.DataSourceID); err != nil {
 		return nil, err
@@ -182,7 +213,7 @@ func (s *SyncTaskService) CreateSyncTask(ctx context.Context, req *CreateSyncTas
 	}
 
 	// 开启事务
-	tx := s.db.Begin()
+	tx := s.db.WithContext(ctx).Begin()
 	defer func() {
 		if r := recover(); r != nil {
 			tx.Rollback()
@@ -204,6 +235,10 @@ func (s *SyncTaskService) CreateSyncTask(ctx context.Context, req *CreateSyncTas
 		Config:          config,
 		CreatedBy:       req.CreatedBy,
 	}
+	if req.TemplateID != "" {
+		task.TemplateID = &req.TemplateID
+		task.TemplateVersion = req.TemplateVersion
+	}
 
 	// 根据触发类型设置下次执行时间
 	if err := s.calculateNextRunTime(task); err != nil {
@@ -243,7 +278,7 @@ func (s *SyncTaskService) CreateSyncTask(ctx context.Context, req *CreateSyncTas
 	}
 
 	// 重新加载任务以包含关联数据
-	if err := s.db.Preload("TaskInterfaces").Preload("DataSource").First(task, "id = ?", task.ID).Error; err != nil {
+	if err := s.db.WithContext(ctx).Preload("TaskInterfaces").Preload("DataSource").First(task, "id = ?", task.ID).Error; err != nil {
 		return nil, fmt.Errorf("重新加载任务失败: %w", err)
 	}
 
@@ -257,7 +292,7 @@ func (s *SyncTaskService) CreateSyncTask(ctx context.Context, req *CreateSyncTas
 // GetSyncTaskByID 根据ID获取基础库同步任务
 func (s *SyncTaskService) GetSyncTaskByID(ctx context.Context, taskID string) (*models.SyncTask, error) {
 	var task models.SyncTask
-	if err := s.db.Preload("DataSource").
+	if err := s.db.WithContext(ctx).Preload("DataSource").
 		Preload("TaskInterfaces").
 		Preload("TaskInterfaces.DataInterface").
 		Preload("DataInterfaces").
@@ -266,7 +301,7 @@ func (s *SyncTaskService) GetSyncTaskByID(ctx context.Context, taskID string) (*
 	}
 
 	// 加载基础库信息
-	if err := s.loadLibraryInfo(&task); err != nil {
+	if err := s.loadLibraryInfo(ctx, &task); err != nil {
 		return nil, fmt.Errorf("加载库信息失败: %w", err)
 	}
 
@@ -274,8 +309,8 @@ func (s *SyncTaskService) GetSyncTaskByID(ctx context.Context, taskID string) (*
 }
 
 // loadLibraryInfo 加载基础库信息
-func (s *SyncTaskService) loadLibraryInfo(task *models.SyncTask) error {
-	libraryInfo, err := s.handler.GetLibraryInfo(task.LibraryID)
+func (s *SyncTaskService) loadLibraryInfo(ctx context.Context, task *models.SyncTask) error {
+	libraryInfo, err := s.handler.GetLibraryInfo(ctx, task.LibraryID)
 	if err != nil {
 		return err
 	}
@@ -308,6 +343,8 @@ type CreateSyncTaskRequest struct {
 	ScheduledTime    *time.Time                `json:"scheduled_time,omitempty"`
 	Config           map[string]interface{}    `json:"config,omitempty"`
 	CreatedBy        string                    `json:"created_by"`
+	TemplateID       string                    `json:"template_id,omitempty"`      // 关联的同步任务模板
+	TemplateVersion  int64                     `json:"template_version,omitempty"` // 创建时的模板版本
 }
 
 // UpdateSyncTaskRequest 更新基础库同步任务请求
@@ -322,6 +359,7 @@ type UpdateSyncTaskRequest struct {
 	UpdatedBy        string                    `json:"updated_by"`
 	TaskType         string                    `json:"task_type,omitempty"`
 	ScheduledTime    *time.Time                `json:"scheduled_time,omitempty"`
+	RowVersion       int64                     `json:"row_version,omitempty"` // 期望版本，也可通过If-Match头传递
 }
 
 // GetSyncTaskListRequest 获取基础库同步任务列表请求
@@ -337,16 +375,8 @@ type GetSyncTaskListRequest struct {
 
 // SyncTaskListResponse 基础库同步任务列表响应
 type SyncTaskListResponse struct {
-	Tasks      []models.SyncTask `json:"tasks"`
-	Pagination PaginationInfo    `json:"pagination"`
-}
-
-// PaginationInfo 分页信息
-type PaginationInfo struct {
-	Page       int   `json:"page"`
-	Size       int   `json:"size"`
-	Total      int64 `json:"total"`
-	TotalPages int64 `json:"total_pages"`
+	List []models.SyncTask `json:"list"`
+	models.PageMeta
 }
 
 // SyncTaskStatusResponse 基础库同步任务状态响应
@@ -371,8 +401,8 @@ type GetSyncTaskExecutionListRequest struct {
 
 // SyncTaskExecutionListResponse 基础库同步任务执行记录列表响应
 type SyncTaskExecutionListResponse struct {
-	Executions []models.SyncTaskExecution `json:"executions"`
-	Pagination PaginationInfo             `json:"pagination"`
+	List []models.SyncTaskExecution `json:"list"`
+	models.PageMeta
 }
 
 // BatchDeleteResponse 批量删除响应
@@ -395,7 +425,9 @@ type SyncTaskStatistics struct {
 
 // GetSyncTaskList 获取基础库同步任务列表
 func (s *SyncTaskService) GetSyncTaskList(ctx context.Context, req *GetSyncTaskListRequest) (*SyncTaskListResponse, error) {
-	query := s.db.Model(&models.SyncTask{}).Where("library_type = ?", meta.LibraryTypeBasic)
+	req.Page, req.Size = models.NormalizePage(req.Page, req.Size)
+
+	query := s.db.WithContext(ctx).Model(&models.SyncTask{}).Where("library_type = ?", meta.LibraryTypeBasic)
 
 	// 应用过滤条件
 	if req.LibraryID != "" {
@@ -432,23 +464,15 @@ func (s *SyncTaskService) GetSyncTaskList(ctx context.Context, req *GetSyncTaskL
 
 	// 加载库信息
 	for i := range tasks {
-		if err := s.loadLibraryInfo(&tasks[i]); err != nil {
+		if err := s.loadLibraryInfo(ctx, &tasks[i]); err != nil {
 			// 记录错误但不阻塞
 			slog.Error("加载库信息失败", "error", err)
 		}
 	}
 
-	// 计算总页数
-	totalPages := (total + int64(req.Size) - 1) / int64(req.Size)
-
 	return &SyncTaskListResponse{
-		Tasks: tasks,
-		Pagination: PaginationInfo{
-			Page:       req.Page,
-			Size:       req.Size,
-			Total:      total,
-			TotalPages: totalPages,
-		},
+		List:     tasks,
+		PageMeta: models.NewPageMeta(total, req.Page, req.Size),
 	}, nil
 }
 
@@ -456,7 +480,7 @@ func (s *SyncTaskService) GetSyncTaskList(ctx context.Context, req *GetSyncTaskL
 func (s *SyncTaskService) UpdateSyncTask(ctx context.Context, taskID string, req *UpdateSyncTaskRequest) (*models.SyncTask, error) {
 	// 获取任务
 	var task models.SyncTask
-	if err := s.db.Preload("TaskInterfaces").First(&task, "id = ?", taskID).Error; err != nil {
+	if err := s.db.WithContext(ctx).Preload("TaskInterfaces").First(&task, "id = ?", taskID).Error; err != nil {
 		return nil, fmt.Errorf("任务不存在: %w", err)
 	}
 
@@ -464,13 +488,19 @@ func (s *SyncTaskService) UpdateSyncTask(ctx context.Context, taskID string, req
 	oldStatus := task.Status
 
 	// 开启事务
-	tx := s.db.Begin()
+	tx := s.db.WithContext(ctx).Begin()
 	defer func() {
 		if r := recover(); r != nil {
 			tx.Rollback()
 		}
 	}()
 
+	// 校验并递增版本，防止并发修改互相覆盖
+	if _, err := models.BumpRowVersion(tx, &models.SyncTask{}, taskID, req.RowVersion); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
 	// 准备更新数据
 	updates := map[string]interface{}{
 		"updated_at": time.Now(),
@@ -597,7 +627,7 @@ func (s *SyncTaskService) UpdateSyncTask(ctx context.Context, taskID string, req
 	}
 
 	// 重新获取更新后的任务
-	if err := s.db.Preload("DataSource").
+	if err := s.db.WithContext(ctx).Preload("DataSource").
 		Preload("TaskInterfaces").
 		Preload("TaskInterfaces.DataInterface").
 		Preload("DataInterfaces").
@@ -606,7 +636,7 @@ func (s *SyncTaskService) UpdateSyncTask(ctx context.Context, taskID string, req
 	}
 
 	// 加载库信息
-	if err := s.loadLibraryInfo(&task); err != nil {
+	if err := s.loadLibraryInfo(ctx, &task); err != nil {
 		return nil, fmt.Errorf("加载库信息失败: %w", err)
 	}
 
@@ -649,7 +679,7 @@ func (s *SyncTaskService) UpdateSyncTask(ctx context.Context, taskID string, req
 func (s *SyncTaskService) DeleteSyncTask(ctx context.Context, taskID string) error {
 	// 获取任务
 	var task models.SyncTask
-	if err := s.db.First(&task, "id = ?", taskID).Error; err != nil {
+	if err := s.db.WithContext(ctx).First(&task, "id = ?", taskID).Error; err != nil {
 		return fmt.Errorf("任务不存在: %w", err)
 	}
 
@@ -659,7 +689,7 @@ func (s *SyncTaskService) DeleteSyncTask(ctx context.Context, taskID string) err
 	}
 
 	// 开启事务删除任务和相关记录
-	return s.db.Transaction(func(tx *gorm.DB) error {
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
 		// 删除任务接口关联记录
 		if err := tx.Where("task_id = ?", taskID).Delete(&models.SyncTaskInterface{}).Error; err != nil {
 			return fmt.Errorf("删除任务接口关联记录失败: %w", err)
@@ -685,13 +715,36 @@ func (s *SyncTaskService) StartSyncTask(ctx context.Context, taskID string) erro
 
 	// 获取任务详细信息
 	var task models.SyncTask
-	if err := s.db.Preload("TaskInterfaces").First(&task, "id = ?", taskID).Error; err != nil {
+	if err := s.db.WithContext(ctx).Preload("TaskInterfaces").First(&task, "id = ?", taskID).Error; err != nil {
 		slog.Error("SyncTaskService.StartSyncTask - 任务不存在", "value1", taskID, "value2", err)
 		return fmt.Errorf("任务不存在: %w", err)
 	}
 
 	slog.Debug("SyncTaskService.StartSyncTask - 找到任务", "value1", task.ID, "value2", task.Status, "value3", task.TaskType)
 
+	// 数据源维护中不启动，结束维护时补跑
+	dataSource, err := maintenanceDataSource(ctx, s.db, task.DataSourceID)
+	if err != nil {
+		return err
+	}
+	if dataSource != nil {
+		return fmt.Errorf("%w，结束维护后再启动", ErrDataSourceInMaintenance)
+	}
+
+	// 数据源熔断中不启动，冷却结束后先探测数据源
+	if s.circuit != nil {
+		if err := s.circuit.Allow(ctx, task.DataSourceID); err != nil {
+			return err
+		}
+	}
+
+	// 库或所属租户的数据量达到配额上限时不启动
+	if s.quota != nil {
+		if err := s.quota.CheckSync(ctx, task.LibraryID); err != nil {
+			return err
+		}
+	}
+
 	// 检查任务是否可以启动
 	if !task.CanStart() {
 		slog.Error("SyncTaskService.StartSyncTask - 任务状态不允许启动",
@@ -710,18 +763,40 @@ func (s *SyncTaskService) StartSyncTask(ctx context.Context, taskID string) erro
 		return fmt.Errorf("更新任务执行状态失败: %w", err)
 	}
 
-	// 创建独立的context用于任务执行，避免HTTP请求context被取消影响任务执行
-	taskCtx := context.Background()
+	// 没有指定接口的情况，返回错误
+	if len(task.TaskInterfaces) == 0 {
+		s.updateTaskExecutionStatus(task.ID, meta.SyncExecutionStatusFailed, "任务必须关联至少一个接口")
+		return fmt.Errorf("任务必须关联至少一个接口")
+	}
 
-	// 如果有指定接口，使用InterfaceExecutor执行
-	if len(task.TaskInterfaces) > 0 {
-		go s.executeTaskWithInterfaces(taskCtx, &task)
-	} else {
-		// 没有指定接口的情况，返回错误
+	// 交给执行面处理
+	if s.dispatcher != nil {
+		if err := s.dispatcher.Dispatch(ctx, execution.NewCommand(execution.CommandKindBasicSync, task.ID)); err != nil {
+			s.updateTaskExecutionStatus(task.ID, meta.SyncExecutionStatusFailed, err.Error())
+			return fmt.Errorf("分发任务执行命令失败: %w", err)
+		}
+		return nil
+	}
+
+	// 使用不随HTTP请求取消的context执行任务，保留请求ID和链路信息
+	go s.executeTaskWithInterfaces(context.WithoutCancel(ctx), &task)
+
+	return nil
+}
+
+// HandleExecutionCommand 执行面处理基础库同步命令，同步执行任务的所有接口
+func (s *SyncTaskService) HandleExecutionCommand(ctx context.Context, cmd *execution.Command) error {
+	var task models.SyncTask
+	if err := s.db.Preload("TaskInterfaces").First(&task, "id = ?", cmd.TaskID).Error; err != nil {
+		return fmt.Errorf("任务不存在: %w", err)
+	}
+
+	if len(task.TaskInterfaces) == 0 {
 		s.updateTaskExecutionStatus(task.ID, meta.SyncExecutionStatusFailed, "任务必须关联至少一个接口")
 		return fmt.Errorf("任务必须关联至少一个接口")
 	}
 
+	s.executeTaskWithInterfaces(ctx, &task)
 	return nil
 }
 
@@ -731,6 +806,13 @@ func (s *SyncTaskService) StartSyncTask(ctx context.Context, taskID string) erro
 // executeTaskWithInterfaces 使用InterfaceExecutor执行任务
 func (s *SyncTaskService) executeTaskWithInterfaces(ctx context.Context, task *models.SyncTask) {
 	slog.Debug("SyncTaskService.executeTaskWithInterfaces - 开始执行任务", "value", task.ID)
+	startTime := time.Now()
+
+	ctx, span := tracing.Start(ctx, "sync.basic_library.execute")
+	defer span.End()
+	span.SetAttribute("sync.task_id", task.ID)
+	span.SetAttribute("sync.library_id", task.LibraryID)
+	span.SetAttribute("sync.interface_count", len(task.TaskInterfaces))
 
 	// 创建执行记录
 	execution, err := s.CreateSyncTaskExecution(ctx, task.ID, "interface_executor")
@@ -739,10 +821,26 @@ func (s *SyncTaskService) executeTaskWithInterfaces(ctx context.Context, task *m
 		s.updateTaskExecutionStatus(task.ID, meta.SyncExecutionStatusFailed, err.Error())
 		return
 	}
+	eventlog.Record(ctx, eventlog.Event{
+		Type:       eventlog.EventTaskStarted,
+		Message:    "基础库同步任务开始执行",
+		ObjectType: "sync_task",
+		ObjectID:   task.ID,
+		Attributes: map[string]interface{}{"execution_id": execution.ID, "library_id": task.LibraryID, "interface_count": len(task.TaskInterfaces)},
+	})
+
+	// 运行时的接口配置和数据源健康快照，供执行诊断使用
+	snapshot := captureExecutionSnapshot(s.db, task)
 
 	var totalProcessed int64
 	var hasError bool
 	var errorMessages []string
+	// 每个接口的调用结果，用于接口健康报告统计
+	interfaceResults := make([]map[string]interface{}, 0, len(task.TaskInterfaces))
+	// 写入成功的接口，供质量门禁检查
+	syncedInterfaceIDs := make([]string, 0, len(task.TaskInterfaces))
+	// 接口可重试失败的重试策略，未配置时不重试
+	retryPolicy := models.ParseSyncRetryPolicy(task.Config)
 
 	// 执行每个接口
 	for _, taskInterface := range task.TaskInterfaces {
@@ -759,15 +857,47 @@ func (s *SyncTaskService) executeTaskWithInterfaces(ctx context.Context, task *m
 			InterfaceType: "basic_library", // 固定为基础库
 			ExecuteType:   executeType,     // 统一使用sync
 			Parameters:    taskInterface.Config,
+			ExecutionID:   execution.ID,
 		}
 
 		// 执行接口
-		response, err := s.interfaceExecutor.Execute(ctx, executeRequest)
+		callStart := time.Now()
+		batchCtx, batchSpan := tracing.Start(ctx, "sync.batch")
+		batchSpan.SetAttribute("interface.id", taskInterface.InterfaceID)
+		response, stack, attempts, err := s.executeInterfaceWithRetry(batchCtx, executeRequest, retryPolicy)
+		if err != nil {
+			batchSpan.RecordError(err)
+		} else if !response.Success {
+			batchSpan.SetError("%s", response.Error)
+		}
+		batchSpan.End()
+		metrics.ObserveSyncBatch(task.LibraryType, time.Since(callStart))
+		callDuration := time.Since(callStart).Milliseconds()
 		if err != nil {
 			hasError = true
 			errorMsg := fmt.Sprintf("接口 %s 执行失败: %v", taskInterface.InterfaceID, err)
 			errorMessages = append(errorMessages, errorMsg)
+			callResult := newInterfaceCallResult(taskInterface.InterfaceID, false, callDuration, 0, err.Error())
+			callResult["started_at"] = callStart
+			callResult["attempts"] = attempts
+			// 有原始错误时按错误类型(SQLSTATE、网络错误)重新分类，比错误文本更准确
+			classification := interface_executor.ClassifyFailure(err)
+			callResult["failure_code"] = classification.Code
+			callResult["failure_class"] = string(classification.Class)
+			s.recordDataSourceFailure(ctx, task.DataSourceID, classification, err.Error())
+			if stack != "" {
+				callResult["stack"] = stack
+			}
+			// 数据不符合接口JSON Schema时记录结构化的违规清单
+			if validation := interface_executor.PayloadValidationOf(err); validation != nil {
+				callResult["schema_violations"] = validation
+			}
+			if status := recordContractCompliance(ctx, s.db, task.ID, execution.ID, taskInterface.InterfaceID, interface_executor.ContractReportOf(response, err), time.Now()); status != "" {
+				callResult["contract_status"] = status
+			}
+			interfaceResults = append(interfaceResults, callResult)
 			slog.Error("Error occurred", "message", errorMsg)
+			recordBatchFailed(ctx, task.ID, execution.ID, taskInterface.InterfaceID, err.Error())
 			continue
 		}
 
@@ -775,11 +905,39 @@ func (s *SyncTaskService) executeTaskWithInterfaces(ctx context.Context, task *m
 			hasError = true
 			errorMsg := fmt.Sprintf("接口 %s 执行失败: %s", taskInterface.InterfaceID, response.Error)
 			errorMessages = append(errorMessages, errorMsg)
+			callResult := newInterfaceCallResult(taskInterface.InterfaceID, false, callDuration, 0, response.Error)
+			callResult["started_at"] = callStart
+			callResult["attempts"] = attempts
+			interfaceResults = append(interfaceResults, callResult)
+			s.recordDataSourceFailure(ctx, task.DataSourceID, interface_executor.ClassifyFailureMessage(response.Error), response.Error)
 			slog.Error("Error occurred", "message", errorMsg)
+			recordBatchFailed(ctx, task.ID, execution.ID, taskInterface.InterfaceID, response.Error)
 			continue
 		}
 
+		callResult := newInterfaceCallResult(taskInterface.InterfaceID, true, callDuration, response.UpdatedRows, "")
+		callResult["started_at"] = callStart
+		callResult["attempts"] = attempts
+		if s.circuit != nil {
+			s.circuit.RecordSuccess(ctx, task.DataSourceID)
+		}
+		// 批量同步的批次数和每批条数，供同步容量规划估算单批开销
+		if batches := toInt64(response.Metadata["batch_count"]); batches > 0 {
+			callResult["batch_count"] = batches
+			callResult["batch_size"] = toInt64(response.Metadata["batch_size"])
+		}
+		if status := recordContractCompliance(ctx, s.db, task.ID, execution.ID, taskInterface.InterfaceID, interface_executor.ContractReportOf(response, nil), time.Now()); status != "" {
+			callResult["contract_status"] = status
+		}
+		if status := recordGoldenComparisons(ctx, s.db, task.ID, execution.ID, taskInterface.InterfaceID); status != "" {
+			callResult["golden_status"] = status
+		}
+		interfaceResults = append(interfaceResults, callResult)
+		recordExecutionDiff(ctx, s.db, task.ID, execution.ID, taskInterface.InterfaceID, interface_executor.SyncDiffReportOf(response))
 		totalProcessed += response.UpdatedRows
+		recordInterfaceSynced(ctx, s.db, taskInterface.InterfaceID, time.Now())
+		recordSyncSnapshot(ctx, s.db, task.ID, execution.ID, taskInterface.InterfaceID)
+		syncedInterfaceIDs = append(syncedInterfaceIDs, taskInterface.InterfaceID)
 		slog.Debug("接口执行成功", "interface_id", taskInterface.InterfaceID, "updated_rows", response.UpdatedRows)
 	}
 
@@ -799,6 +957,36 @@ func (s *SyncTaskService) executeTaskWithInterfaces(ctx context.Context, task *m
 		finalExecutionStatus = meta.SyncExecutionStatusSuccess
 	}
 
+	// 写入后检查质量门禁，未通过时执行失败，写入的接口被阻断
+	var qualityGate *QualityGateResult
+	if s.qualityGate != nil {
+		qualityGate = s.qualityGate.Evaluate(ctx, task, execution.ID, syncedInterfaceIDs)
+	}
+	if qualityGate != nil && !qualityGate.Passed {
+		finalExecutionStatus = meta.SyncExecutionStatusFailed
+		gateMessage := fmt.Sprintf("质量门禁未通过: %s", strings.Join(qualityGate.Reasons, "; "))
+		if errorMessage != "" {
+			errorMessage = gateMessage + "; " + errorMessage
+		} else {
+			errorMessage = gateMessage
+		}
+	}
+
+	// 错误信息会保存到任务和执行记录并通过接口返回，上游数据中的敏感字段先脱敏
+	errorMessage = logger.SanitizeString(errorMessage)
+
+	// 写入后判定接口上的数据值告警规则
+	if s.valueAlerts != nil {
+		s.valueAlerts.EvaluateInterfaces(ctx, syncedInterfaceIDs)
+	}
+
+	metrics.ObserveSyncExecution(task.LibraryType, task.LibraryID, finalExecutionStatus, totalProcessed, time.Since(startTime))
+	recordTaskFinished(ctx, task.ID, execution.ID, finalExecutionStatus, totalProcessed, errorMessage)
+	span.SetAttribute("sync.processed_rows", totalProcessed)
+	if errorMessage != "" {
+		span.SetError("%s", errorMessage)
+	}
+
 	// 更新任务
 	updates := map[string]interface{}{
 		"execution_status": finalExecutionStatus,
@@ -820,10 +1008,15 @@ func (s *SyncTaskService) executeTaskWithInterfaces(ctx context.Context, task *m
 
 	// 更新执行记录
 	result := map[string]interface{}{
-		"processed_rows":  totalProcessed,
-		"interface_count": len(task.TaskInterfaces),
-		"success_count":   len(task.TaskInterfaces) - len(errorMessages),
-		"failed_count":    len(errorMessages),
+		"processed_rows":    totalProcessed,
+		"interface_count":   len(task.TaskInterfaces),
+		"success_count":     len(task.TaskInterfaces) - len(errorMessages),
+		"failed_count":      len(errorMessages),
+		"interface_results": interfaceResults,
+		"snapshot":          snapshot,
+	}
+	if qualityGate != nil {
+		result["quality_gate"] = qualityGate
 	}
 
 	if err := s.UpdateSyncTaskExecution(ctx, execution.ID, finalExecutionStatus, result, errorMessage); err != nil {
@@ -835,6 +1028,84 @@ func (s *SyncTaskService) executeTaskWithInterfaces(ctx context.Context, task *m
 	slog.Debug("任务执行完成", "task_id", task.ID, "execution_status", finalExecutionStatus, "processed_rows", totalProcessed)
 }
 
+// executeInterface 执行单个接口，接口执行panic时转为错误并返回调用栈，避免中断整个任务
+func (s *SyncTaskService) executeInterface(ctx context.Context, request *interface_executor.ExecuteRequest) (response *interface_executor.ExecuteResponse, stack string, err error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			stack = string(debug.Stack())
+			err = fmt.Errorf("接口执行panic: %v", recovered)
+			slog.ErrorContext(ctx, "接口执行panic", "interface_id", request.InterfaceID, "error", recovered, "stack", stack)
+		}
+	}()
+	response, err = s.interfaceExecutor.Execute(ctx, request)
+	return response, "", err
+}
+
+// executeInterfaceWithRetry 按重试策略执行单个接口，只重试可重试的失败（网络超时、连接中断、限流等），第n次重试前等待n倍间隔，返回实际执行次数
+func (s *SyncTaskService) executeInterfaceWithRetry(ctx context.Context, request *interface_executor.ExecuteRequest, policy *models.SyncRetryPolicy) (*interface_executor.ExecuteResponse, string, int, error) {
+	for attempts := 1; ; attempts++ {
+		response, stack, err := s.executeInterface(ctx, request)
+		if policy == nil || attempts > policy.MaxRetries || !isRetryableInterfaceFailure(response, err) {
+			return response, stack, attempts, err
+		}
+		wait := time.Duration(attempts*policy.IntervalSeconds) * time.Second
+		slog.WarnContext(ctx, "接口执行失败，按重试策略重试", "interface_id", request.InterfaceID, "attempt", attempts, "max_retries", policy.MaxRetries, "wait", wait)
+		select {
+		case <-ctx.Done():
+			return response, stack, attempts, err
+		case <-time.After(wait):
+		}
+	}
+}
+
+// isRetryableInterfaceFailure 判断接口执行失败是否可重试，执行成功时返回false
+func isRetryableInterfaceFailure(response *interface_executor.ExecuteResponse, err error) bool {
+	if err != nil {
+		return interface_executor.ClassifyFailure(err).Retryable
+	}
+	return response != nil && !response.Success && interface_executor.ClassifyFailureMessage(response.Error).Retryable
+}
+
+// recordDataSourceFailure 接口执行的源端失败计入数据源熔断，写入侧失败不计入
+func (s *SyncTaskService) recordDataSourceFailure(ctx context.Context, dataSourceID string, classification interface_executor.FailureClassification, message string) {
+	if s.circuit != nil && IsDataSourceFailure(classification) {
+		s.circuit.RecordFailure(ctx, dataSourceID, message)
+	}
+}
+
+// recordBatchFailed 记录单个接口批次执行失败事件
+func recordBatchFailed(ctx context.Context, taskID, executionID, interfaceID, errorMessage string) {
+	eventlog.Record(ctx, eventlog.Event{
+		Type:       eventlog.EventBatchFailed,
+		Level:      models.EventLevelError,
+		Message:    "同步批次执行失败",
+		ObjectType: "sync_task",
+		ObjectID:   taskID,
+		Attributes: map[string]interface{}{"execution_id": executionID, "interface_id": interfaceID, "error": errorMessage},
+	})
+}
+
+// recordTaskFinished 记录任务执行结束事件，部分接口失败时记为警告
+func recordTaskFinished(ctx context.Context, taskID, executionID, status string, processedRows int64, errorMessage string) {
+	event := eventlog.Event{
+		Type:       eventlog.EventTaskCompleted,
+		Message:    "基础库同步任务执行完成",
+		ObjectType: "sync_task",
+		ObjectID:   taskID,
+		Attributes: map[string]interface{}{"execution_id": executionID, "status": status, "processed_rows": processedRows},
+	}
+	if errorMessage != "" {
+		event.Level = models.EventLevelWarn
+		event.Attributes["error"] = errorMessage
+	}
+	if status == meta.SyncExecutionStatusFailed {
+		event.Type = eventlog.EventTaskFailed
+		event.Level = models.EventLevelError
+		event.Message = "基础库同步任务执行失败"
+	}
+	eventlog.Record(ctx, event)
+}
+
 // updateTaskExecutionStatus 更新任务执行状态的辅助方法
 func (s *SyncTaskService) updateTaskExecutionStatus(taskID, executionStatus, errorMessage string) {
 	updates := map[string]interface{}{
@@ -858,7 +1129,7 @@ func (s *SyncTaskService) updateTaskExecutionStatus(taskID, executionStatus, err
 func (s *SyncTaskService) StopSyncTask(ctx context.Context, taskID string) error {
 	// 获取任务
 	var task models.SyncTask
-	if err := s.db.First(&task, "id = ?", taskID).Error; err != nil {
+	if err := s.db.WithContext(ctx).First(&task, "id = ?", taskID).Error; err != nil {
 		return fmt.Errorf("任务不存在: %w", err)
 	}
 
@@ -887,7 +1158,7 @@ func (s *SyncTaskService) StopSyncTask(ctx context.Context, taskID string) error
 func (s *SyncTaskService) CancelSyncTask(ctx context.Context, taskID string) error {
 	// 获取任务
 	var task models.SyncTask
-	if err := s.db.First(&task, "id = ?", taskID).Error; err != nil {
+	if err := s.db.WithContext(ctx).First(&task, "id = ?", taskID).Error; err != nil {
 		return fmt.Errorf("任务不存在: %w", err)
 	}
 
@@ -926,7 +1197,7 @@ func (s *SyncTaskService) CancelSyncTask(ctx context.Context, taskID string) err
 func (s *SyncTaskService) RetrySyncTask(ctx context.Context, taskID string) (*models.SyncTask, error) {
 	// 获取原任务及其接口关联
 	var originalTask models.SyncTask
-	if err := s.db.Preload("TaskInterfaces").First(&originalTask, "id = ?", taskID).Error; err != nil {
+	if err := s.db.WithContext(ctx).Preload("TaskInterfaces").First(&originalTask, "id = ?", taskID).Error; err != nil {
 		return nil, fmt.Errorf("任务不存在: %w", err)
 	}
 
@@ -936,7 +1207,7 @@ func (s *SyncTaskService) RetrySyncTask(ctx context.Context, taskID string) (*mo
 	}
 
 	// 开启事务
-	tx := s.db.Begin()
+	tx := s.db.WithContext(ctx).Begin()
 	defer func() {
 		if r := recover(); r != nil {
 			tx.Rollback()
@@ -956,6 +1227,7 @@ func (s *SyncTaskService) RetrySyncTask(ctx context.Context, taskID string) (*mo
 		ExecutionStatus: meta.SyncExecutionStatusIdle, // 重置执行状态为空闲
 		Config:          originalTask.Config,
 		CreatedBy:       originalTask.CreatedBy,
+		TenantID:        originalTask.TenantID,
 	}
 
 	if err := tx.Create(newTask).Error; err != nil {
@@ -984,7 +1256,7 @@ func (s *SyncTaskService) RetrySyncTask(ctx context.Context, taskID string) (*mo
 	}
 
 	// 加载关联信息
-	if err := s.db.Preload("DataSource").
+	if err := s.db.WithContext(ctx).Preload("DataSource").
 		Preload("TaskInterfaces").
 		Preload("TaskInterfaces.DataInterface").
 		Preload("DataInterfaces").
@@ -993,7 +1265,7 @@ func (s *SyncTaskService) RetrySyncTask(ctx context.Context, taskID string) (*mo
 	}
 
 	// 加载库信息
-	if err := s.loadLibraryInfo(newTask); err != nil {
+	if err := s.loadLibraryInfo(ctx, newTask); err != nil {
 		return nil, fmt.Errorf("加载库信息失败: %w", err)
 	}
 
@@ -1004,12 +1276,12 @@ func (s *SyncTaskService) RetrySyncTask(ctx context.Context, taskID string) (*mo
 func (s *SyncTaskService) GetSyncTaskStatus(ctx context.Context, taskID string) (*SyncTaskStatusResponse, error) {
 	// 获取任务
 	var task models.SyncTask
-	if err := s.db.Preload("DataSource").Preload("DataInterfaces").First(&task, "id = ?", taskID).Error; err != nil {
+	if err := s.db.WithContext(ctx).Preload("DataSource").Preload("DataInterfaces").First(&task, "id = ?", taskID).Error; err != nil {
 		return nil, fmt.Errorf("任务不存在: %w", err)
 	}
 
 	// 加载库信息
-	if err := s.loadLibraryInfo(&task); err != nil {
+	if err := s.loadLibraryInfo(ctx, &task); err != nil {
 		return nil, fmt.Errorf("加载库信息失败: %w", err)
 	}
 
@@ -1056,7 +1328,7 @@ func (s *SyncTaskService) BatchDeleteSyncTasks(ctx context.Context, taskIDs []st
 
 // GetSyncTaskStatistics 获取基础库同步任务统计信息
 func (s *SyncTaskService) GetSyncTaskStatistics(ctx context.Context, libraryType, libraryID, dataSourceID string) (*SyncTaskStatistics, error) {
-	query := s.db.Model(&models.SyncTask{}).Where("library_type = ?", meta.LibraryTypeBasic)
+	query := s.db.WithContext(ctx).Model(&models.SyncTask{}).Where("library_type = ?", meta.LibraryTypeBasic)
 
 	// 应用过滤条件
 	if libraryID != "" {
@@ -1074,11 +1346,11 @@ func (s *SyncTaskService) GetSyncTaskStatistics(ctx context.Context, libraryType
 	}
 
 	// 获取各执行状态任务数
-	s.db.Model(&models.SyncTask{}).Where("library_type = ? AND execution_status = ?", meta.LibraryTypeBasic, meta.SyncExecutionStatusIdle).Count(&stats.PendingTasks)
-	s.db.Model(&models.SyncTask{}).Where("library_type = ? AND execution_status = ?", meta.LibraryTypeBasic, meta.SyncExecutionStatusRunning).Count(&stats.RunningTasks)
-	s.db.Model(&models.SyncTask{}).Where("library_type = ? AND execution_status = ?", meta.LibraryTypeBasic, meta.SyncExecutionStatusSuccess).Count(&stats.SuccessTasks)
-	s.db.Model(&models.SyncTask{}).Where("library_type = ? AND execution_status = ?", meta.LibraryTypeBasic, meta.SyncExecutionStatusFailed).Count(&stats.FailedTasks)
-	s.db.Model(&models.SyncTask{}).Where("library_type = ? AND status = ?", meta.LibraryTypeBasic, meta.SyncTaskStatusPaused).Count(&stats.CancelledTasks)
+	s.db.WithContext(ctx).Model(&models.SyncTask{}).Where("library_type = ? AND execution_status = ?", meta.LibraryTypeBasic, meta.SyncExecutionStatusIdle).Count(&stats.PendingTasks)
+	s.db.WithContext(ctx).Model(&models.SyncTask{}).Where("library_type = ? AND execution_status = ?", meta.LibraryTypeBasic, meta.SyncExecutionStatusRunning).Count(&stats.RunningTasks)
+	s.db.WithContext(ctx).Model(&models.SyncTask{}).Where("library_type = ? AND execution_status = ?", meta.LibraryTypeBasic, meta.SyncExecutionStatusSuccess).Count(&stats.SuccessTasks)
+	s.db.WithContext(ctx).Model(&models.SyncTask{}).Where("library_type = ? AND execution_status = ?", meta.LibraryTypeBasic, meta.SyncExecutionStatusFailed).Count(&stats.FailedTasks)
+	s.db.WithContext(ctx).Model(&models.SyncTask{}).Where("library_type = ? AND status = ?", meta.LibraryTypeBasic, meta.SyncTaskStatusPaused).Count(&stats.CancelledTasks)
 
 	// 计算成功率
 	if stats.TotalTasks > 0 {
@@ -1090,14 +1362,10 @@ func (s *SyncTaskService) GetSyncTaskStatistics(ctx context.Context, libraryType
 
 // GetSyncTaskExecutionList 获取基础库同步任务执行记录列表
 func (s *SyncTaskService) GetSyncTaskExecutionList(ctx context.Context, req *GetSyncTaskExecutionListRequest) (*SyncTaskExecutionListResponse, error) {
-	if req.Page <= 0 {
-		req.Page = 1
-	}
-	if req.Size <= 0 || req.Size > 100 {
-		req.Size = 10
-	}
+	req.Page, req.Size = models.NormalizePage(req.Page, req.Size)
 
-	query := s.db.Model(&models.SyncTaskExecution{}).Preload("Task")
+	query := s.db.WithContext(ctx).Model(&models.SyncTaskExecution{}).Preload("Task").
+		Scopes(tenant.OwnerScope(ctx, "task_id", "sync_tasks"))
 
 	// 应用过滤条件
 	if req.TaskID != "" {
@@ -1123,23 +1391,16 @@ func (s *SyncTaskService) GetSyncTaskExecutionList(ctx context.Context, req *Get
 		return nil, fmt.Errorf("获取执行记录列表失败: %w", err)
 	}
 
-	totalPages := (total + int64(req.Size) - 1) / int64(req.Size)
-
 	return &SyncTaskExecutionListResponse{
-		Executions: executions,
-		Pagination: PaginationInfo{
-			Page:       req.Page,
-			Size:       req.Size,
-			Total:      total,
-			TotalPages: totalPages,
-		},
+		List:     executions,
+		PageMeta: models.NewPageMeta(total, req.Page, req.Size),
 	}, nil
 }
 
 // GetSyncTaskExecutionByID 根据ID获取基础库同步任务执行记录
 func (s *SyncTaskService) GetSyncTaskExecutionByID(ctx context.Context, executionID string) (*models.SyncTaskExecution, error) {
 	var execution models.SyncTaskExecution
-	if err := s.db.Preload("Task").Where("id = ?", executionID).First(&execution).Error; err != nil {
+	if err := s.db.Preload("Task").Scopes(tenant.OwnerScope(ctx, "task_id", "sync_tasks")).Where("id = ?", executionID).First(&execution).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return nil, fmt.Errorf("执行记录不存在")
 		}
@@ -1302,7 +1563,7 @@ func (s *SyncTaskService) UpdateTaskNextRunTime(ctx context.Context, taskID stri
 func (s *SyncTaskService) ActivateSyncTask(ctx context.Context, taskID string) error {
 	// 获取任务
 	var task models.SyncTask
-	if err := s.db.Preload("TaskInterfaces").First(&task, "id = ?", taskID).Error; err != nil {
+	if err := s.db.WithContext(ctx).Preload("TaskInterfaces").First(&task, "id = ?", taskID).Error; err != nil {
 		return fmt.Errorf("任务不存在: %w", err)
 	}
 
@@ -1352,6 +1613,31 @@ func (s *SyncTaskService) SetDistributedLock(lock distributed_lock.DistributedLo
 	}
 }
 
+// SetDispatcher 设置执行命令分发器
+func (s *SyncTaskService) SetDispatcher(dispatcher execution.Dispatcher) {
+	s.dispatcher = dispatcher
+}
+
+// SetQualityGate 设置质量门禁服务
+func (s *SyncTaskService) SetQualityGate(qualityGate *QualityGateService) {
+	s.qualityGate = qualityGate
+}
+
+// SetQuota 设置库和租户配额，创建任务和启动同步前检查
+func (s *SyncTaskService) SetQuota(quota *capacity.QuotaService) {
+	s.quota = quota
+}
+
+// SetCircuitBreaker 设置数据源熔断
+func (s *SyncTaskService) SetCircuitBreaker(circuit *DataSourceCircuitService) {
+	s.circuit = circuit
+}
+
+// SetValueAlerts 设置数据值告警服务
+func (s *SyncTaskService) SetValueAlerts(valueAlerts *ValueAlertService) {
+	s.valueAlerts = valueAlerts
+}
+
 // StartScheduler 启动调度器
 func (s *SyncTaskService) StartScheduler() error {
 	if s.schedulerStarted {
@@ -1534,6 +1820,13 @@ func (s *SyncTaskService) checkIntervalTasks() {
 // executeScheduledTask 执行调度任务（带分布式锁）
 func (s *SyncTaskService) executeScheduledTask(taskID string) {
 	slog.Info("执行调度任务", "task_id", taskID)
+	defer metrics.TrackScheduledTask(metrics.SchedulerSync)()
+
+	// 故障注入：模拟调度延迟
+	if err := chaos.Inject(s.ctx, models.FaultTypeScheduleDelay, taskID); err != nil {
+		slog.Warn("调度延迟注入被中断，跳过本次执行", "task_id", taskID, "error", err)
+		return
+	}
 
 	// 如果有分布式锁，使用锁保护执行
 	if s.distributedLock != nil {
@@ -1573,6 +1866,35 @@ func (s *SyncTaskService) executeScheduledTask(taskID string) {
 		return
 	}
 
+	// 数据源维护中，跳过本次调度并推进下次执行时间
+	dataSource, err := maintenanceDataSource(s.ctx, s.db, task.DataSourceID)
+	if err != nil {
+		slog.Error("查询数据源维护状态失败", "task_id", taskID, "error", err)
+		return
+	}
+	if dataSource != nil {
+		s.skipForMaintenance(s.ctx, task, dataSource)
+		if err := s.UpdateTaskNextRunTime(s.ctx, taskID); err != nil {
+			slog.Error("更新下次执行时间失败", "task_id", taskID, "error", err)
+		}
+		return
+	}
+
+	// 数据源熔断中，跳过本次调度并推进下次执行时间；冷却结束时本次调度先探测数据源
+	if s.circuit != nil {
+		if err := s.circuit.Allow(s.ctx, task.DataSourceID); err != nil {
+			if !errors.Is(err, ErrDataSourceCircuitOpen) {
+				slog.Error("检查数据源熔断状态失败", "task_id", taskID, "error", err)
+				return
+			}
+			s.skipForCircuit(s.ctx, task, err)
+			if err := s.UpdateTaskNextRunTime(s.ctx, taskID); err != nil {
+				slog.Error("更新下次执行时间失败", "task_id", taskID, "error", err)
+			}
+			return
+		}
+	}
+
 	// 直接调用启动任务方法
 	if err := s.StartSyncTask(s.ctx, taskID); err != nil {
 		slog.Error("启动调度任务失败", "task_id", taskID, "error", err)
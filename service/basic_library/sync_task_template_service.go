@@ -0,0 +1,470 @@
+/*
+ * @module service/basic_library/sync_task_template_service
+ * @description 同步任务模板服务，管理模板，按模板创建基础库同步任务，模板修改后把调度、批次参数、重试策略和质量门禁同步到关联的任务，减少复制配置造成的差异
+ * @architecture 分层架构 - 业务服务层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow 创建/修改模板(版本+1) -> 按模板创建任务并关联 -> 修改模板时选择同步或稍后同步 -> 逐个更新模板版本落后的关联任务 -> 记录成功和失败的任务
+ * @rules 模板管理任务的类型、执行时机、批次参数、重试策略和质量门禁最低评分，其余配置（接口、增量等）由任务自己维护；
+ *        按模板创建任务时请求中的配置与模板批次参数重名的以模板为准；模板删除的批次参数不从任务中删除；
+ *        模板未设置最低评分时不改变任务的质量门禁，设置时保留任务自己的必须通过规则，任务接口上没有启用的质量检测时不能应用；
+ *        同步到任务沿用同步任务的修改逻辑（乐观锁、重算下次执行时间、刷新调度），单个任务失败不影响其他任务；删除模板时解除关联，任务配置不变
+ * @dependencies datahub-service/service/meta, datahub-service/service/models, gorm.io/gorm, github.com/robfig/cron/v3
+ * @refs service/models/sync_task_template.go, sync_task_service.go, quality_gate_service.go, api/controllers/sync_task_template_controller.go
+ */
+
+package basic_library
+
+import (
+	"context"
+	"datahub-service/service/meta"
+	"datahub-service/service/models"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	"github.com/spf13/cast"
+	"gorm.io/gorm"
+)
+
+// ErrInvalidSyncTaskTemplate 同步任务模板配置不合法
+var ErrInvalidSyncTaskTemplate = errors.New("同步任务模板配置不合法")
+
+const (
+	maxTemplateRetries       = 10   // 最大重试次数上限
+	maxTemplateRetryInterval = 3600 // 重试间隔秒数上限
+)
+
+// templateReservedConfigKeys 批次参数中不允许出现的任务配置键，由系统或模板的其他配置项维护
+var templateReservedConfigKeys = []string{"library_type", "library_id", models.SyncRetryPolicyConfigKey, models.QualityGateConfigKey}
+
+// SyncTaskWriter 创建和修改同步任务，由同步任务服务实现
+type SyncTaskWriter interface {
+	CreateSyncTask(ctx context.Context, req *CreateSyncTaskRequest) (*models.SyncTask, error)
+	UpdateSyncTask(ctx context.Context, taskID string, req *UpdateSyncTaskRequest) (*models.SyncTask, error)
+}
+
+// CreateTaskFromTemplateRequest 按模板创建同步任务请求
+type CreateTaskFromTemplateRequest struct {
+	LibraryID        string
+	DataSourceID     string
+	InterfaceIDs     []string
+	InterfaceConfigs []SyncTaskInterfaceConfig
+	Config           map[string]interface{} // 任务自己的配置，与模板批次参数重名的以模板为准
+	Detach           bool                   // 只按模板创建，不关联模板
+	CreatedBy        string
+}
+
+// LinkedSyncTask 关联模板的同步任务
+type LinkedSyncTask struct {
+	TaskID          string `json:"task_id"`
+	LibraryID       string `json:"library_id"`
+	DataSourceID    string `json:"data_source_id"`
+	Status          string `json:"status"`
+	ExecutionStatus string `json:"execution_status"`
+	TemplateVersion int64  `json:"template_version"`
+	Outdated        bool   `json:"outdated"` // 任务应用的模板版本落后于模板
+}
+
+// TemplatePropagationFailure 同步失败的任务
+type TemplatePropagationFailure struct {
+	TaskID string `json:"task_id"`
+	Error  string `json:"error"`
+}
+
+// TemplatePropagationResult 模板同步到关联任务的结果
+type TemplatePropagationResult struct {
+	TemplateVersion int64                        `json:"template_version"`
+	Updated         []string                     `json:"updated"`
+	Failed          []TemplatePropagationFailure `json:"failed,omitempty"`
+}
+
+// SyncTaskTemplateService 同步任务模板服务
+type SyncTaskTemplateService struct {
+	db     *gorm.DB
+	writer SyncTaskWriter
+}
+
+// NewSyncTaskTemplateService 创建同步任务模板服务
+func NewSyncTaskTemplateService(db *gorm.DB, writer SyncTaskWriter) *SyncTaskTemplateService {
+	return &SyncTaskTemplateService{db: db, writer: writer}
+}
+
+// ListTemplates 获取模板列表及各模板关联的任务数，name不为空时按名称模糊匹配
+func (s *SyncTaskTemplateService) ListTemplates(ctx context.Context, name string) ([]models.SyncTaskTemplate, error) {
+	query := s.db.WithContext(ctx).Model(&models.SyncTaskTemplate{})
+	if name != "" {
+		query = query.Where("name LIKE ?", "%"+name+"%")
+	}
+	templates := []models.SyncTaskTemplate{}
+	if err := query.Order("name").Find(&templates).Error; err != nil {
+		return nil, fmt.Errorf("查询同步任务模板失败: %w", err)
+	}
+
+	var counts []struct {
+		TemplateID string
+		Total      int64
+	}
+	if err := s.db.WithContext(ctx).Model(&models.SyncTask{}).Select("template_id, COUNT(*) AS total").
+		Where("template_id IS NOT NULL").Group("template_id").Scan(&counts).Error; err != nil {
+		return nil, fmt.Errorf("统计关联任务失败: %w", err)
+	}
+	linked := make(map[string]int64, len(counts))
+	for _, count := range counts {
+		linked[count.TemplateID] = count.Total
+	}
+	for i := range templates {
+		templates[i].LinkedTasks = linked[templates[i].ID]
+	}
+	return templates, nil
+}
+
+// GetTemplate 获取模板详情
+func (s *SyncTaskTemplateService) GetTemplate(ctx context.Context, templateID string) (*models.SyncTaskTemplate, error) {
+	var template models.SyncTaskTemplate
+	if err := s.db.WithContext(ctx).First(&template, "id = ?", templateID).Error; err != nil {
+		return nil, err
+	}
+	if err := s.db.WithContext(ctx).Model(&models.SyncTask{}).Where("template_id = ?", templateID).Count(&template.LinkedTasks).Error; err != nil {
+		return nil, fmt.Errorf("统计关联任务失败: %w", err)
+	}
+	return &template, nil
+}
+
+// CreateTemplate 创建模板
+func (s *SyncTaskTemplateService) CreateTemplate(ctx context.Context, template *models.SyncTaskTemplate, username string) (*models.SyncTaskTemplate, error) {
+	if err := validateSyncTaskTemplate(template); err != nil {
+		return nil, err
+	}
+	if err := s.checkNameAvailable(ctx, template.Name, ""); err != nil {
+		return nil, err
+	}
+	template.ID = ""
+	template.RowVersion = 1
+	template.CreatedBy = username
+	template.UpdatedBy = username
+	if err := s.db.WithContext(ctx).Create(template).Error; err != nil {
+		return nil, fmt.Errorf("保存同步任务模板失败: %w", err)
+	}
+	slog.Info("创建同步任务模板", "template_id", template.ID, "name", template.Name, "trigger_type", template.TriggerType, "username", username)
+	return template, nil
+}
+
+// UpdateTemplate 修改模板并递增版本，propagate为true时同步到关联的任务
+func (s *SyncTaskTemplateService) UpdateTemplate(ctx context.Context, templateID string, input *models.SyncTaskTemplate, expectedVersion int64, propagate bool, username string) (*models.SyncTaskTemplate, *TemplatePropagationResult, error) {
+	if err := validateSyncTaskTemplate(input); err != nil {
+		return nil, nil, err
+	}
+	if err := s.checkNameAvailable(ctx, input.Name, templateID); err != nil {
+		return nil, nil, err
+	}
+
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if _, err := models.BumpRowVersion(tx, &models.SyncTaskTemplate{}, templateID, expectedVersion); err != nil {
+			return err
+		}
+		return tx.Model(&models.SyncTaskTemplate{}).Where("id = ?", templateID).Updates(map[string]interface{}{
+			"name":                   input.Name,
+			"description":            input.Description,
+			"task_type":              input.TaskType,
+			"trigger_type":           input.TriggerType,
+			"cron_expression":        input.CronExpression,
+			"interval_seconds":       input.IntervalSeconds,
+			"batch_config":           input.BatchConfig,
+			"max_retries":            input.MaxRetries,
+			"retry_interval_seconds": input.RetryIntervalSeconds,
+			"quality_gate_min_score": input.QualityGateMinScore,
+			"updated_by":             username,
+			"updated_at":             time.Now(),
+		}).Error
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	template, err := s.GetTemplate(ctx, templateID)
+	if err != nil {
+		return nil, nil, err
+	}
+	slog.Info("修改同步任务模板", "template_id", templateID, "version", template.RowVersion, "propagate", propagate, "username", username)
+	if !propagate {
+		return template, nil, nil
+	}
+	result, err := s.propagate(ctx, template, username)
+	if err != nil {
+		return nil, nil, err
+	}
+	return template, result, nil
+}
+
+// DeleteTemplate 删除模板，关联的任务解除关联，任务配置不变
+func (s *SyncTaskTemplateService) DeleteTemplate(ctx context.Context, templateID, username string) error {
+	template, err := s.GetTemplate(ctx, templateID)
+	if err != nil {
+		return err
+	}
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.SyncTask{}).Where("template_id = ?", templateID).
+			UpdateColumns(map[string]interface{}{"template_id": nil, "template_version": 0}).Error; err != nil {
+			return fmt.Errorf("解除任务关联失败: %w", err)
+		}
+		if err := tx.Delete(&models.SyncTaskTemplate{}, "id = ?", templateID).Error; err != nil {
+			return fmt.Errorf("删除同步任务模板失败: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	slog.Info("删除同步任务模板", "template_id", templateID, "name", template.Name, "unlinked_tasks", template.LinkedTasks, "username", username)
+	return nil
+}
+
+// CreateTask 按模板创建基础库同步任务，Detach为false时任务关联模板
+func (s *SyncTaskTemplateService) CreateTask(ctx context.Context, templateID string, req *CreateTaskFromTemplateRequest) (*models.SyncTask, error) {
+	template, err := s.GetTemplate(ctx, templateID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkQualityGateApplicable(ctx, template, req.InterfaceIDs); err != nil {
+		return nil, err
+	}
+
+	createReq := &CreateSyncTaskRequest{
+		LibraryType:      meta.LibraryTypeBasic,
+		LibraryID:        req.LibraryID,
+		DataSourceID:     req.DataSourceID,
+		InterfaceIDs:     req.InterfaceIDs,
+		InterfaceConfigs: req.InterfaceConfigs,
+		TaskType:         template.TaskType,
+		TriggerType:      template.TriggerType,
+		CronExpression:   template.CronExpression,
+		IntervalSeconds:  template.IntervalSeconds,
+		Config:           applySyncTaskTemplate(req.Config, template),
+		CreatedBy:        req.CreatedBy,
+	}
+	if !req.Detach {
+		createReq.TemplateID = template.ID
+		createReq.TemplateVersion = template.RowVersion
+	}
+	task, err := s.writer.CreateSyncTask(ctx, createReq)
+	if err != nil {
+		return nil, err
+	}
+	slog.Info("按模板创建同步任务", "template_id", templateID, "task_id", task.ID, "detach", req.Detach, "username", req.CreatedBy)
+	return task, nil
+}
+
+// ListTasks 获取关联模板的任务，标记模板版本落后的任务
+func (s *SyncTaskTemplateService) ListTasks(ctx context.Context, templateID string) ([]LinkedSyncTask, error) {
+	template, err := s.GetTemplate(ctx, templateID)
+	if err != nil {
+		return nil, err
+	}
+	var tasks []models.SyncTask
+	if err := s.db.WithContext(ctx).Where("template_id = ?", templateID).Order("created_at").Find(&tasks).Error; err != nil {
+		return nil, fmt.Errorf("查询关联任务失败: %w", err)
+	}
+	linked := make([]LinkedSyncTask, 0, len(tasks))
+	for _, task := range tasks {
+		linked = append(linked, LinkedSyncTask{
+			TaskID:          task.ID,
+			LibraryID:       task.LibraryID,
+			DataSourceID:    task.DataSourceID,
+			Status:          task.Status,
+			ExecutionStatus: task.ExecutionStatus,
+			TemplateVersion: task.TemplateVersion,
+			Outdated:        task.TemplateVersion < template.RowVersion,
+		})
+	}
+	return linked, nil
+}
+
+// Propagate 把模板同步到模板版本落后的关联任务
+func (s *SyncTaskTemplateService) Propagate(ctx context.Context, templateID, username string) (*TemplatePropagationResult, error) {
+	template, err := s.GetTemplate(ctx, templateID)
+	if err != nil {
+		return nil, err
+	}
+	return s.propagate(ctx, template, username)
+}
+
+// UnlinkTask 解除任务与模板的关联，任务配置不变
+func (s *SyncTaskTemplateService) UnlinkTask(ctx context.Context, templateID, taskID, username string) error {
+	result := s.db.WithContext(ctx).Model(&models.SyncTask{}).Where("id = ? AND template_id = ?", taskID, templateID).
+		UpdateColumns(map[string]interface{}{"template_id": nil, "template_version": 0})
+	if result.Error != nil {
+		return fmt.Errorf("解除任务关联失败: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	slog.Info("同步任务解除模板关联", "template_id", templateID, "task_id", taskID, "username", username)
+	return nil
+}
+
+// propagate 逐个更新模板版本落后的关联任务，单个任务失败时记录原因并继续
+func (s *SyncTaskTemplateService) propagate(ctx context.Context, template *models.SyncTaskTemplate, username string) (*TemplatePropagationResult, error) {
+	var tasks []models.SyncTask
+	if err := s.db.WithContext(ctx).Preload("TaskInterfaces").
+		Where("template_id = ? AND template_version < ?", template.ID, template.RowVersion).
+		Order("created_at").Find(&tasks).Error; err != nil {
+		return nil, fmt.Errorf("查询关联任务失败: %w", err)
+	}
+
+	result := &TemplatePropagationResult{TemplateVersion: template.RowVersion, Updated: []string{}}
+	for i := range tasks {
+		if err := s.applyToTask(ctx, template, &tasks[i], username); err != nil {
+			slog.Warn("同步任务模板应用失败", "template_id", template.ID, "task_id", tasks[i].ID, "error", err)
+			result.Failed = append(result.Failed, TemplatePropagationFailure{TaskID: tasks[i].ID, Error: err.Error()})
+			continue
+		}
+		result.Updated = append(result.Updated, tasks[i].ID)
+	}
+	slog.Info("同步任务模板已同步到关联任务", "template_id", template.ID, "version", template.RowVersion,
+		"updated", len(result.Updated), "failed", len(result.Failed), "username", username)
+	return result, nil
+}
+
+// applyToTask 按模板修改一个任务并记录应用的模板版本
+func (s *SyncTaskTemplateService) applyToTask(ctx context.Context, template *models.SyncTaskTemplate, task *models.SyncTask, username string) error {
+	if err := s.checkQualityGateApplicable(ctx, template, taskInterfaceIDs(task)); err != nil {
+		return err
+	}
+	_, err := s.writer.UpdateSyncTask(ctx, task.ID, &UpdateSyncTaskRequest{
+		TaskType:        template.TaskType,
+		TriggerType:     template.TriggerType,
+		CronExpression:  template.CronExpression,
+		IntervalSeconds: template.IntervalSeconds,
+		Config:          applySyncTaskTemplate(task.Config, template),
+		UpdatedBy:       username,
+		RowVersion:      task.RowVersion,
+	})
+	if err != nil {
+		return err
+	}
+	return s.db.WithContext(ctx).Model(&models.SyncTask{}).Where("id = ?", task.ID).
+		UpdateColumn("template_version", template.RowVersion).Error
+}
+
+// checkQualityGateApplicable 模板设置了质量门禁时，要求任务接口上有启用的质量检测，否则每次执行都会因门禁未通过而失败
+func (s *SyncTaskTemplateService) checkQualityGateApplicable(ctx context.Context, template *models.SyncTaskTemplate, interfaceIDs []string) error {
+	if template.QualityGateMinScore == nil {
+		return nil
+	}
+	var count int64
+	if len(interfaceIDs) > 0 {
+		if err := s.db.WithContext(ctx).Model(&models.QualityTask{}).
+			Where("interface_id IN ? AND is_enabled = ?", interfaceIDs, true).Count(&count).Error; err != nil {
+			return fmt.Errorf("查询质量检测任务失败: %w", err)
+		}
+	}
+	if count == 0 {
+		return fmt.Errorf("%w: 模板开启了质量门禁，但任务的接口上没有启用的质量检测任务", ErrInvalidSyncTaskTemplate)
+	}
+	return nil
+}
+
+// checkNameAvailable 检查模板名称是否已被其他模板使用
+func (s *SyncTaskTemplateService) checkNameAvailable(ctx context.Context, name, excludeID string) error {
+	query := s.db.WithContext(ctx).Model(&models.SyncTaskTemplate{}).Where("name = ?", name)
+	if excludeID != "" {
+		query = query.Where("id <> ?", excludeID)
+	}
+	var count int64
+	if err := query.Count(&count).Error; err != nil {
+		return fmt.Errorf("检查模板名称失败: %w", err)
+	}
+	if count > 0 {
+		return fmt.Errorf("%w: 模板名称已存在", ErrInvalidSyncTaskTemplate)
+	}
+	return nil
+}
+
+// validateSyncTaskTemplate 校验模板并清理与执行时机无关的调度参数
+func validateSyncTaskTemplate(template *models.SyncTaskTemplate) error {
+	if template.Name == "" {
+		return fmt.Errorf("%w: 模板名称不能为空", ErrInvalidSyncTaskTemplate)
+	}
+	if !meta.IsValidSyncType(template.TaskType) {
+		return fmt.Errorf("%w: 无效的任务类型%s", ErrInvalidSyncTaskTemplate, template.TaskType)
+	}
+
+	switch template.TriggerType {
+	case meta.SyncTaskTriggerManual:
+		template.CronExpression = ""
+		template.IntervalSeconds = 0
+	case meta.SyncTaskTriggerInterval:
+		if template.IntervalSeconds <= 0 {
+			return fmt.Errorf("%w: 间隔执行的间隔秒数必须大于0", ErrInvalidSyncTaskTemplate)
+		}
+		template.CronExpression = ""
+	case meta.SyncTaskTriggerCron:
+		parser := cron.NewParser(cron.SecondOptional | cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow | cron.Descriptor)
+		if _, err := parser.Parse(template.CronExpression); err != nil {
+			return fmt.Errorf("%w: 解析Cron表达式失败: %v", ErrInvalidSyncTaskTemplate, err)
+		}
+		template.IntervalSeconds = 0
+	default:
+		// 单次执行的计划时间因任务而异，不适合放在模板中
+		return fmt.Errorf("%w: 执行时机只支持manual、interval和cron", ErrInvalidSyncTaskTemplate)
+	}
+
+	for _, key := range templateReservedConfigKeys {
+		if _, exists := template.BatchConfig[key]; exists {
+			return fmt.Errorf("%w: 批次参数不能包含%s", ErrInvalidSyncTaskTemplate, key)
+		}
+	}
+	if value, exists := template.BatchConfig["batch_size"]; exists {
+		if size, err := cast.ToIntE(value); err != nil || size <= 0 {
+			return fmt.Errorf("%w: batch_size必须是正整数", ErrInvalidSyncTaskTemplate)
+		}
+	}
+
+	if template.MaxRetries < 0 || template.MaxRetries > maxTemplateRetries {
+		return fmt.Errorf("%w: 最大重试次数须在0-%d之间", ErrInvalidSyncTaskTemplate, maxTemplateRetries)
+	}
+	if template.RetryIntervalSeconds < 0 || template.RetryIntervalSeconds > maxTemplateRetryInterval {
+		return fmt.Errorf("%w: 重试间隔须在0-%d秒之间", ErrInvalidSyncTaskTemplate, maxTemplateRetryInterval)
+	}
+	if template.MaxRetries == 0 {
+		template.RetryIntervalSeconds = 0
+	}
+	if score := template.QualityGateMinScore; score != nil && (*score < 0 || *score > 100) {
+		return fmt.Errorf("%w: 质量门禁最低评分须在0-100之间", ErrInvalidSyncTaskTemplate)
+	}
+	return nil
+}
+
+// applySyncTaskTemplate 复制任务配置并写入模板管理的批次参数、重试策略和质量门禁
+func applySyncTaskTemplate(config map[string]interface{}, template *models.SyncTaskTemplate) models.JSONB {
+	applied := models.JSONB{}
+	for key, value := range config {
+		applied[key] = value
+	}
+	for key, value := range template.BatchConfig {
+		applied[key] = value
+	}
+
+	if policy := template.RetryPolicy(); policy != nil {
+		applied[models.SyncRetryPolicyConfigKey] = map[string]interface{}{
+			"max_retries":      policy.MaxRetries,
+			"interval_seconds": policy.IntervalSeconds,
+		}
+	} else {
+		delete(applied, models.SyncRetryPolicyConfigKey)
+	}
+
+	if template.QualityGateMinScore != nil {
+		gate := map[string]interface{}{"enabled": true, "min_score": *template.QualityGateMinScore, "required_rules": []string{}}
+		// 必须通过的规则与任务的接口相关，保留任务自己的设置
+		if existing, ok := applied[models.QualityGateConfigKey].(map[string]interface{}); ok {
+			if rules := cast.ToStringSlice(existing["required_rules"]); len(rules) > 0 {
+				gate["required_rules"] = rules
+			}
+		}
+		applied[models.QualityGateConfigKey] = gate
+	}
+	return applied
+}
@@ -0,0 +1,236 @@
+/*
+ * @module service/basic_library/sync_task_template_service_test
+ * @description 同步任务模板测试，覆盖模板校验、按模板创建任务（关联和不关联）、质量门禁适用性检查、修改模板后同步到关联任务、单个任务失败、解除关联和删除模板，以及重试策略解析
+ * @architecture 测试层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow 准备sqlite中的数据源、接口和质量检测任务 -> 创建模板 -> 按模板创建任务 -> 修改并同步模板 -> 验证任务配置和模板版本
+ * @rules 使用内存sqlite，同步任务的创建和修改由写入sqlite的模拟实现代替，不启动调度器
+ * @dependencies gorm.io/driver/sqlite, stretchr/testify
+ * @refs sync_task_template_service.go, service/models/sync_task_template.go
+ */
+
+package basic_library
+
+import (
+	"context"
+	"datahub-service/service/interface_executor"
+	"datahub-service/service/models"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// fakeSyncTaskWriter 把同步任务写入sqlite，对failTask的修改返回错误
+type fakeSyncTaskWriter struct {
+	db       *gorm.DB
+	failTask string
+}
+
+func (f *fakeSyncTaskWriter) CreateSyncTask(ctx context.Context, req *CreateSyncTaskRequest) (*models.SyncTask, error) {
+	task := &models.SyncTask{
+		LibraryType: "basic_library", LibraryID: req.LibraryID, DataSourceID: req.DataSourceID, TaskType: req.TaskType,
+		TriggerType: req.TriggerType, CronExpression: req.CronExpression, IntervalSeconds: req.IntervalSeconds,
+		Config: req.Config, CreatedBy: req.CreatedBy,
+	}
+	if req.TemplateID != "" {
+		task.TemplateID = &req.TemplateID
+		task.TemplateVersion = req.TemplateVersion
+	}
+	for _, interfaceID := range req.InterfaceIDs {
+		task.TaskInterfaces = append(task.TaskInterfaces, models.SyncTaskInterface{InterfaceID: interfaceID})
+	}
+	return task, f.db.Create(task).Error
+}
+
+func (f *fakeSyncTaskWriter) UpdateSyncTask(ctx context.Context, taskID string, req *UpdateSyncTaskRequest) (*models.SyncTask, error) {
+	if taskID == f.failTask {
+		return nil, errors.New("任务正在被修改")
+	}
+	if _, err := models.BumpRowVersion(f.db, &models.SyncTask{}, taskID, req.RowVersion); err != nil {
+		return nil, err
+	}
+	if err := f.db.Model(&models.SyncTask{}).Where("id = ?", taskID).Updates(map[string]interface{}{
+		"task_type": req.TaskType, "trigger_type": req.TriggerType, "cron_expression": req.CronExpression,
+		"interval_seconds": req.IntervalSeconds, "config": models.JSONB(req.Config), "updated_by": req.UpdatedBy,
+	}).Error; err != nil {
+		return nil, err
+	}
+	var task models.SyncTask
+	return &task, f.db.First(&task, "id = ?", taskID).Error
+}
+
+func setupSyncTaskTemplateDB(t *testing.T) (*gorm.DB, *SyncTaskTemplateService, *fakeSyncTaskWriter) {
+	db, _ := setupDiagnosticsDB(t)
+	require.NoError(t, db.AutoMigrate(&models.SyncTaskTemplate{}, &models.QualityTask{}))
+	require.NoError(t, db.Create(&models.DataInterface{
+		ID: "if-2", LibraryID: "lib-1", NameZh: "车位状态", NameEn: "parking_space", Type: "batch", DataSourceID: "ds-1",
+	}).Error)
+	require.NoError(t, db.Create(&models.QualityTask{
+		ID: "qt-1", Name: "车辆进出检测", LibraryType: "basic", LibraryID: "lib-1", InterfaceID: "if-1", ScheduleType: "manual", IsEnabled: true,
+	}).Error)
+	writer := &fakeSyncTaskWriter{db: db}
+	return db, NewSyncTaskTemplateService(db, writer), writer
+}
+
+func nightlyTemplate() *models.SyncTaskTemplate {
+	minScore := 90.0
+	return &models.SyncTaskTemplate{
+		Name: "夜间全量同步", TaskType: "batch_sync", TriggerType: "cron", CronExpression: "0 0 2 * * *", IntervalSeconds: 60,
+		BatchConfig: models.JSONB{"batch_size": float64(500), "timeout": "1h"}, MaxRetries: 3, RetryIntervalSeconds: 30,
+		QualityGateMinScore: &minScore,
+	}
+}
+
+func TestSyncTaskTemplateValidation(t *testing.T) {
+	_, s, _ := setupSyncTaskTemplateDB(t)
+	ctx := context.Background()
+
+	invalid := []func(*models.SyncTaskTemplate){
+		func(tpl *models.SyncTaskTemplate) { tpl.TriggerType = "once" },
+		func(tpl *models.SyncTaskTemplate) { tpl.CronExpression = "every night" },
+		func(tpl *models.SyncTaskTemplate) { tpl.TriggerType, tpl.IntervalSeconds = "interval", 0 },
+		func(tpl *models.SyncTaskTemplate) { tpl.TaskType = "full_sync" },
+		func(tpl *models.SyncTaskTemplate) { tpl.BatchConfig = models.JSONB{"library_id": "lib-2"} },
+		func(tpl *models.SyncTaskTemplate) { tpl.BatchConfig = models.JSONB{"batch_size": -1} },
+		func(tpl *models.SyncTaskTemplate) { tpl.MaxRetries = 11 },
+	}
+	for i, mutate := range invalid {
+		template := nightlyTemplate()
+		mutate(template)
+		_, err := s.CreateTemplate(ctx, template, "admin")
+		assert.ErrorIs(t, err, ErrInvalidSyncTaskTemplate, "case %d", i)
+	}
+
+	template, err := s.CreateTemplate(ctx, nightlyTemplate(), "admin")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), template.RowVersion)
+	assert.Zero(t, template.IntervalSeconds, "cron模板不保留间隔秒数")
+
+	_, err = s.CreateTemplate(ctx, nightlyTemplate(), "admin")
+	assert.ErrorIs(t, err, ErrInvalidSyncTaskTemplate, "名称重复")
+}
+
+func TestCreateTaskFromTemplate(t *testing.T) {
+	_, s, _ := setupSyncTaskTemplateDB(t)
+	ctx := context.Background()
+	template, err := s.CreateTemplate(ctx, nightlyTemplate(), "admin")
+	require.NoError(t, err)
+
+	_, err = s.CreateTask(ctx, template.ID, &CreateTaskFromTemplateRequest{
+		LibraryID: "lib-1", DataSourceID: "ds-1", InterfaceIDs: []string{"if-2"}, CreatedBy: "alice",
+	})
+	assert.ErrorIs(t, err, ErrInvalidSyncTaskTemplate, "接口上没有质量检测时不能应用门禁")
+
+	task, err := s.CreateTask(ctx, template.ID, &CreateTaskFromTemplateRequest{
+		LibraryID: "lib-1", DataSourceID: "ds-1", InterfaceIDs: []string{"if-1"}, CreatedBy: "alice",
+		Config: models.JSONB{"batch_size": 10, "incremental": true},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "cron", task.TriggerType)
+	assert.Equal(t, "0 0 2 * * *", task.CronExpression)
+	require.NotNil(t, task.TemplateID)
+	assert.Equal(t, template.ID, *task.TemplateID)
+	assert.Equal(t, int64(1), task.TemplateVersion)
+	assert.Equal(t, float64(500), task.Config["batch_size"], "批次参数以模板为准")
+	assert.Equal(t, true, task.Config["incremental"])
+	assert.Equal(t, &models.SyncRetryPolicy{MaxRetries: 3, IntervalSeconds: 30}, models.ParseSyncRetryPolicy(task.Config))
+	gate := models.ParseQualityGateConfig(task.Config)
+	require.NotNil(t, gate)
+	assert.Equal(t, 90.0, *gate.MinScore)
+
+	detached, err := s.CreateTask(ctx, template.ID, &CreateTaskFromTemplateRequest{
+		LibraryID: "lib-1", DataSourceID: "ds-1", InterfaceIDs: []string{"if-1"}, Detach: true,
+	})
+	require.NoError(t, err)
+	assert.Nil(t, detached.TemplateID)
+
+	template, err = s.GetTemplate(ctx, template.ID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), template.LinkedTasks)
+}
+
+func TestPropagateSyncTaskTemplate(t *testing.T) {
+	db, s, writer := setupSyncTaskTemplateDB(t)
+	ctx := context.Background()
+	template, err := s.CreateTemplate(ctx, nightlyTemplate(), "admin")
+	require.NoError(t, err)
+
+	request := &CreateTaskFromTemplateRequest{LibraryID: "lib-1", DataSourceID: "ds-1", InterfaceIDs: []string{"if-1"}}
+	first, err := s.CreateTask(ctx, template.ID, request)
+	require.NoError(t, err)
+	second, err := s.CreateTask(ctx, template.ID, request)
+	require.NoError(t, err)
+	// 任务自己设置的必须通过规则在同步模板时保留
+	require.NoError(t, db.Model(&models.SyncTask{}).Where("id = ?", first.ID).Update("config", models.JSONB{
+		"batch_size": 500, "quality_gate": map[string]interface{}{"enabled": true, "min_score": 90, "required_rules": []string{"rule-plate"}},
+	}).Error)
+
+	update := nightlyTemplate()
+	update.TriggerType, update.IntervalSeconds = "interval", 1800
+	update.BatchConfig = models.JSONB{"batch_size": float64(2000)}
+	update.MaxRetries = 0
+	minScore := 80.0
+	update.QualityGateMinScore = &minScore
+
+	_, _, err = s.UpdateTemplate(ctx, template.ID, update, 5, false, "admin")
+	var conflict *models.VersionConflictError
+	assert.ErrorAs(t, err, &conflict)
+
+	template, result, err := s.UpdateTemplate(ctx, template.ID, update, 1, false, "admin")
+	require.NoError(t, err)
+	assert.Nil(t, result)
+	assert.Equal(t, int64(2), template.RowVersion)
+	linked, err := s.ListTasks(ctx, template.ID)
+	require.NoError(t, err)
+	require.Len(t, linked, 2)
+	assert.True(t, linked[0].Outdated)
+	assert.True(t, linked[1].Outdated)
+
+	writer.failTask = second.ID
+	result, err = s.Propagate(ctx, template.ID, "admin")
+	require.NoError(t, err)
+	assert.Equal(t, []string{first.ID}, result.Updated)
+	require.Len(t, result.Failed, 1)
+	assert.Equal(t, second.ID, result.Failed[0].TaskID)
+
+	var task models.SyncTask
+	require.NoError(t, db.First(&task, "id = ?", first.ID).Error)
+	assert.Equal(t, "interval", task.TriggerType)
+	assert.Equal(t, 1800, task.IntervalSeconds)
+	assert.Equal(t, int64(2), task.TemplateVersion)
+	assert.Equal(t, float64(2000), task.Config["batch_size"])
+	assert.Nil(t, models.ParseSyncRetryPolicy(task.Config), "模板不再重试")
+	gate := models.ParseQualityGateConfig(task.Config)
+	require.NotNil(t, gate)
+	assert.Equal(t, 80.0, *gate.MinScore)
+	assert.Equal(t, []string{"rule-plate"}, gate.RequiredRules)
+
+	// 失败的任务再次同步
+	writer.failTask = ""
+	result, err = s.Propagate(ctx, template.ID, "admin")
+	require.NoError(t, err)
+	assert.Equal(t, []string{second.ID}, result.Updated)
+
+	require.NoError(t, s.UnlinkTask(ctx, template.ID, second.ID, "admin"))
+	assert.ErrorIs(t, s.UnlinkTask(ctx, template.ID, second.ID, "admin"), gorm.ErrRecordNotFound)
+
+	require.NoError(t, s.DeleteTemplate(ctx, template.ID, "admin"))
+	require.NoError(t, db.First(&task, "id = ?", first.ID).Error)
+	assert.Nil(t, task.TemplateID)
+	assert.Equal(t, float64(2000), task.Config["batch_size"], "删除模板后任务配置不变")
+}
+
+func TestSyncRetryPolicy(t *testing.T) {
+	assert.Nil(t, models.ParseSyncRetryPolicy(map[string]interface{}{}))
+	assert.Nil(t, models.ParseSyncRetryPolicy(map[string]interface{}{"retry_policy": map[string]interface{}{"max_retries": 0}}))
+	assert.Equal(t, &models.SyncRetryPolicy{MaxRetries: 2, IntervalSeconds: 5},
+		models.ParseSyncRetryPolicy(map[string]interface{}{"retry_policy": map[string]interface{}{"max_retries": float64(2), "interval_seconds": "5"}}))
+
+	assert.True(t, isRetryableInterfaceFailure(nil, errors.New("dial tcp 10.0.0.1:5432: connection refused")))
+	assert.False(t, isRetryableInterfaceFailure(nil, errors.New("duplicate key value violates unique constraint")))
+	assert.True(t, isRetryableInterfaceFailure(&interface_executor.ExecuteResponse{Success: false, Error: "请求超时"}, nil))
+	assert.False(t, isRetryableInterfaceFailure(&interface_executor.ExecuteResponse{Success: true}, nil))
+}
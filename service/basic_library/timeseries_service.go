@@ -0,0 +1,472 @@
+/*
+ * @module service/basic_library/timeseries_service
+ * @description 接口时间序列存储配置服务，把接口表转换为TimescaleDB超表，维护压缩、保留策略和降采样连续聚合
+ * @architecture 分层架构 - 业务服务层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow 开启：校验时间列和标签列 -> 接口表已创建时转换为超表并设置压缩、保留策略，未创建时在建表后转换 -> 保存配置；
+ *            修改：按差异调整分块间隔、压缩和保留策略；连续聚合：创建物化视图和刷新策略 -> 保存配置；
+ *            关闭：删除连续聚合和策略 -> 移除配置，接口表保持为超表
+ * @rules 按接口版本条件保存配置，版本不一致返回冲突；不能与历史追踪同时开启；有降采样任务时不能关闭；接口表有主键时主键须包含时间列，除时间列外不能有单列唯一约束；
+ *        时间列和标签列在接口表转换为超表后不能修改；关闭后再开启时时间列须与超表原有的分块列一致；
+ *        连续聚合的刷新窗口至少覆盖两个时间桶，聚合名不能与降采样任务同名
+ * @dependencies datahub-service/service/database, datahub-service/service/models, gorm.io/gorm
+ * @refs service/models/timeseries.go, service/database/timeseries.go, api/controllers/timeseries_controller.go
+ */
+
+package basic_library
+
+import (
+	"context"
+	"datahub-service/service/database"
+	"datahub-service/service/models"
+	"errors"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ErrInvalidTimeSeriesRequest 时间序列存储配置不合法
+var ErrInvalidTimeSeriesRequest = errors.New("时间序列存储配置不合法")
+
+// timeSeriesAggregateNamePattern 连续聚合名，作为物化视图名的后缀
+var timeSeriesAggregateNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_]{0,30}$`)
+
+// timeSeriesFunctions 连续聚合支持的函数，值表示是否只能用于数值列
+var timeSeriesFunctions = map[string]bool{
+	models.TimeSeriesFuncAvg:   true,
+	models.TimeSeriesFuncSum:   true,
+	models.TimeSeriesFuncMin:   false,
+	models.TimeSeriesFuncMax:   false,
+	models.TimeSeriesFuncCount: false,
+	models.TimeSeriesFuncFirst: false,
+	models.TimeSeriesFuncLast:  false,
+}
+
+// TimeSeriesService 接口时间序列存储配置服务
+type TimeSeriesService struct {
+	db *gorm.DB
+}
+
+// NewTimeSeriesService 创建接口时间序列存储配置服务
+func NewTimeSeriesService(db *gorm.DB) *TimeSeriesService {
+	return &TimeSeriesService{db: db}
+}
+
+// TimeSeriesStatus 接口的时间序列存储配置和超表状态
+type TimeSeriesStatus struct {
+	Config     *models.TimeSeriesConfig   `json:"config"`
+	Hypertable *database.HypertableStatus `json:"hypertable,omitempty"` // 接口表尚未创建时为空
+}
+
+// GetConfig 获取接口的时间序列存储配置，未开启时返回enabled=false
+func (s *TimeSeriesService) GetConfig(ctx context.Context, interfaceID string) (*TimeSeriesStatus, error) {
+	iface, err := getTenantInterface(ctx, s.db, interfaceID)
+	if err != nil {
+		return nil, err
+	}
+	result := &TimeSeriesStatus{Config: models.ParseTimeSeriesConfig(iface.InterfaceConfig)}
+	if result.Config == nil {
+		result.Config = &models.TimeSeriesConfig{TagColumns: []string{}}
+	}
+	if iface.IsTableCreated {
+		status, err := database.QueryHypertableStatus(s.db.WithContext(ctx), iface.BasicLibrary.GetSchemaName(), iface.NameEn)
+		if err != nil {
+			return nil, err
+		}
+		result.Hypertable = status
+	}
+	return result, nil
+}
+
+// SaveConfig 开启或修改接口的时间序列存储，已有的连续聚合保留；expectedVersion不为0时须与接口当前版本一致
+func (s *TimeSeriesService) SaveConfig(ctx context.Context, interfaceID string, expectedVersion int64, config *models.TimeSeriesConfig, username string) (*models.TimeSeriesConfig, error) {
+	iface, err := getTenantInterface(ctx, s.db, interfaceID)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkInterfaceVersion(iface, expectedVersion); err != nil {
+		return nil, err
+	}
+	if models.ParseSCDConfig(iface.InterfaceConfig) != nil {
+		return nil, fmt.Errorf("%w: 接口已开启历史追踪，不能同时开启时间序列存储", ErrInvalidTimeSeriesRequest)
+	}
+
+	config.Enabled = true
+	if config.ChunkInterval == "" {
+		config.ChunkInterval = models.DefaultTimeSeriesChunkInterval
+	}
+	current := models.ParseTimeSeriesConfig(iface.InterfaceConfig)
+	config.Aggregates = nil
+	if current != nil {
+		config.Aggregates = current.Aggregates
+		if iface.IsTableCreated && (config.TimeColumn != current.TimeColumn || !stringSliceEqual(config.TagColumns, current.TagColumns)) {
+			return nil, fmt.Errorf("%w: 接口表已转换为超表，不能修改时间列和标签列", ErrInvalidTimeSeriesRequest)
+		}
+	}
+	if err := validateTimeSeriesConfig(sortedTableFields(iface.TableFieldsConfig), config); err != nil {
+		return nil, err
+	}
+	config.UpdatedBy = username
+	config.UpdatedAt = time.Now()
+
+	if iface.IsTableCreated {
+		db := s.db.WithContext(ctx)
+		schema, table := iface.BasicLibrary.GetSchemaName(), iface.NameEn
+		status, err := database.QueryHypertableStatus(db, schema, table)
+		if err != nil {
+			return nil, err
+		}
+		if current == nil {
+			if status.IsHypertable && status.TimeColumn != config.TimeColumn {
+				return nil, fmt.Errorf("%w: 接口表已是按%s分块的超表，时间列须保持一致", ErrInvalidTimeSeriesRequest, status.TimeColumn)
+			}
+			err = applyTimeSeriesStorage(db, schema, table, config, status.CompressionEnabled)
+		} else {
+			err = updateTimeSeriesPolicies(db, schema, table, current, config, status.CompressionEnabled)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	if err := saveInterfaceConfig(ctx, s.db, iface, "interface_config", models.TimeSeriesConfigKey, config, nil); err != nil {
+		return nil, fmt.Errorf("保存时间序列存储配置失败: %w", err)
+	}
+
+	slog.Info("设置接口时间序列存储", "interface_id", interfaceID, "time_column", config.TimeColumn,
+		"table_created", iface.IsTableCreated, "username", username)
+	return config, nil
+}
+
+// DisableConfig 关闭接口的时间序列存储，删除连续聚合和压缩、保留策略，接口表保持为超表；expectedVersion不为0时须与接口当前版本一致
+func (s *TimeSeriesService) DisableConfig(ctx context.Context, interfaceID string, expectedVersion int64, username string) error {
+	iface, err := getTenantInterface(ctx, s.db, interfaceID)
+	if err != nil {
+		return err
+	}
+	if err := checkInterfaceVersion(iface, expectedVersion); err != nil {
+		return err
+	}
+	config := models.ParseTimeSeriesConfig(iface.InterfaceConfig)
+	if config == nil {
+		return fmt.Errorf("%w: 接口未开启时间序列存储", ErrInvalidTimeSeriesRequest)
+	}
+	var rollups int64
+	if err := s.db.WithContext(ctx).Model(&models.TimeSeriesRollup{}).Where("source_interface_id = ?", interfaceID).Count(&rollups).Error; err != nil {
+		return err
+	}
+	if rollups > 0 {
+		return fmt.Errorf("%w: 接口有%d个降采样任务，请先删除", ErrInvalidTimeSeriesRequest, rollups)
+	}
+
+	if iface.IsTableCreated {
+		db := s.db.WithContext(ctx)
+		schema, table := iface.BasicLibrary.GetSchemaName(), iface.NameEn
+		statements := []string{}
+		for _, aggregate := range config.Aggregates {
+			statements = append(statements, database.BuildDropContinuousAggregateSQL(schema, table, aggregate.Name))
+		}
+		statements = append(statements,
+			database.BuildRemoveCompressionPolicySQL(schema, table),
+			database.BuildRemoveRetentionPolicySQL(schema, table))
+		if err := execTimeSeriesStatements(db, statements); err != nil {
+			return err
+		}
+	}
+	if err := saveInterfaceConfig(ctx, s.db, iface, "interface_config", models.TimeSeriesConfigKey, nil, nil); err != nil {
+		return fmt.Errorf("保存时间序列存储配置失败: %w", err)
+	}
+
+	slog.Info("关闭接口时间序列存储", "interface_id", interfaceID, "username", username)
+	return nil
+}
+
+// SaveAggregate 创建或替换接口的降采样连续聚合，替换时重建物化视图；expectedVersion不为0时须与接口当前版本一致
+func (s *TimeSeriesService) SaveAggregate(ctx context.Context, interfaceID string, expectedVersion int64, aggregate models.TimeSeriesAggregate, username string) (*models.TimeSeriesConfig, error) {
+	iface, err := getTenantInterface(ctx, s.db, interfaceID)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkInterfaceVersion(iface, expectedVersion); err != nil {
+		return nil, err
+	}
+	config := models.ParseTimeSeriesConfig(iface.InterfaceConfig)
+	if config == nil {
+		return nil, fmt.Errorf("%w: 接口未开启时间序列存储", ErrInvalidTimeSeriesRequest)
+	}
+	if err := validateTimeSeriesAggregate(sortedTableFields(iface.TableFieldsConfig), config, &aggregate); err != nil {
+		return nil, err
+	}
+	var rollups int64
+	if err := s.db.WithContext(ctx).Model(&models.TimeSeriesRollup{}).Where("source_interface_id = ? AND name = ?", interfaceID, aggregate.Name).Count(&rollups).Error; err != nil {
+		return nil, err
+	}
+	if rollups > 0 {
+		return nil, fmt.Errorf("%w: 已有同名的降采样任务%s", ErrInvalidTimeSeriesRequest, aggregate.Name)
+	}
+
+	if iface.IsTableCreated {
+		schema, table := iface.BasicLibrary.GetSchemaName(), iface.NameEn
+		statements := []string{
+			database.BuildDropContinuousAggregateSQL(schema, table, aggregate.Name),
+			database.BuildContinuousAggregateSQL(schema, table, config, aggregate),
+			database.BuildContinuousAggregatePolicySQL(schema, table, aggregate),
+		}
+		if err := execTimeSeriesStatements(s.db.WithContext(ctx), statements); err != nil {
+			return nil, err
+		}
+	}
+
+	if i := config.FindAggregate(aggregate.Name); i >= 0 {
+		config.Aggregates[i] = aggregate
+	} else {
+		config.Aggregates = append(config.Aggregates, aggregate)
+	}
+	config.UpdatedBy = username
+	config.UpdatedAt = time.Now()
+	if err := saveInterfaceConfig(ctx, s.db, iface, "interface_config", models.TimeSeriesConfigKey, config, nil); err != nil {
+		return nil, fmt.Errorf("保存时间序列存储配置失败: %w", err)
+	}
+
+	slog.Info("设置接口连续聚合", "interface_id", interfaceID, "aggregate", aggregate.Name,
+		"bucket_interval", aggregate.BucketInterval, "username", username)
+	return config, nil
+}
+
+// DeleteAggregate 删除接口的降采样连续聚合及其物化视图；expectedVersion不为0时须与接口当前版本一致
+func (s *TimeSeriesService) DeleteAggregate(ctx context.Context, interfaceID string, expectedVersion int64, name, username string) error {
+	iface, err := getTenantInterface(ctx, s.db, interfaceID)
+	if err != nil {
+		return err
+	}
+	if err := checkInterfaceVersion(iface, expectedVersion); err != nil {
+		return err
+	}
+	config := models.ParseTimeSeriesConfig(iface.InterfaceConfig)
+	if config == nil {
+		return fmt.Errorf("%w: 接口未开启时间序列存储", ErrInvalidTimeSeriesRequest)
+	}
+	i := config.FindAggregate(name)
+	if i < 0 {
+		return fmt.Errorf("%w: 连续聚合%s不存在", ErrInvalidTimeSeriesRequest, name)
+	}
+
+	if iface.IsTableCreated {
+		sql := database.BuildDropContinuousAggregateSQL(iface.BasicLibrary.GetSchemaName(), iface.NameEn, name)
+		if err := execTimeSeriesStatements(s.db.WithContext(ctx), []string{sql}); err != nil {
+			return err
+		}
+	}
+	config.Aggregates = append(config.Aggregates[:i], config.Aggregates[i+1:]...)
+	config.UpdatedBy = username
+	config.UpdatedAt = time.Now()
+	if err := saveInterfaceConfig(ctx, s.db, iface, "interface_config", models.TimeSeriesConfigKey, config, nil); err != nil {
+		return fmt.Errorf("保存时间序列存储配置失败: %w", err)
+	}
+
+	slog.Info("删除接口连续聚合", "interface_id", interfaceID, "aggregate", name, "username", username)
+	return nil
+}
+
+// createTimeSeriesStorage 接口表创建后，按接口配置转换为超表，未开启时间序列存储时不做处理
+func createTimeSeriesStorage(db *gorm.DB, schemaName, tableName string, interfaceConfig map[string]interface{}) error {
+	config := models.ParseTimeSeriesConfig(interfaceConfig)
+	if config == nil {
+		return nil
+	}
+	if err := applyTimeSeriesStorage(db, schemaName, tableName, config, false); err != nil {
+		return err
+	}
+	slog.Info("接口表已创建为超表", "schema", schemaName, "table", tableName, "time_column", config.TimeColumn)
+	return nil
+}
+
+// applyTimeSeriesStorage 把接口表转换为超表，并设置压缩、保留策略和已配置的连续聚合
+func applyTimeSeriesStorage(db *gorm.DB, schemaName, tableName string, config *models.TimeSeriesConfig, compressionEnabled bool) error {
+	statements := []string{
+		database.TimescaleExtensionSQL,
+		database.BuildCreateHypertableSQL(schemaName, tableName, config),
+	}
+	if config.CompressAfter != "" {
+		if !compressionEnabled {
+			statements = append(statements, database.BuildCompressionSettingsSQL(schemaName, tableName, config))
+		}
+		statements = append(statements, database.BuildCompressionPolicySQL(schemaName, tableName, config.CompressAfter))
+	}
+	if config.RetentionPeriod != "" {
+		statements = append(statements, database.BuildRetentionPolicySQL(schemaName, tableName, config.RetentionPeriod))
+	}
+	for _, aggregate := range config.Aggregates {
+		statements = append(statements,
+			database.BuildDropContinuousAggregateSQL(schemaName, tableName, aggregate.Name),
+			database.BuildContinuousAggregateSQL(schemaName, tableName, config, aggregate),
+			database.BuildContinuousAggregatePolicySQL(schemaName, tableName, aggregate))
+	}
+	return execTimeSeriesStatements(db, statements)
+}
+
+// updateTimeSeriesPolicies 按新旧配置的差异调整分块间隔、压缩和保留策略
+func updateTimeSeriesPolicies(db *gorm.DB, schemaName, tableName string, current, config *models.TimeSeriesConfig, compressionEnabled bool) error {
+	var statements []string
+	if config.ChunkInterval != current.ChunkInterval {
+		statements = append(statements, database.BuildSetChunkIntervalSQL(schemaName, tableName, config.ChunkInterval))
+	}
+	if config.CompressAfter != current.CompressAfter {
+		statements = append(statements, database.BuildRemoveCompressionPolicySQL(schemaName, tableName))
+		if config.CompressAfter != "" {
+			if !compressionEnabled {
+				statements = append(statements, database.BuildCompressionSettingsSQL(schemaName, tableName, config))
+			}
+			statements = append(statements, database.BuildCompressionPolicySQL(schemaName, tableName, config.CompressAfter))
+		}
+	}
+	if config.RetentionPeriod != current.RetentionPeriod {
+		statements = append(statements, database.BuildRemoveRetentionPolicySQL(schemaName, tableName))
+		if config.RetentionPeriod != "" {
+			statements = append(statements, database.BuildRetentionPolicySQL(schemaName, tableName, config.RetentionPeriod))
+		}
+	}
+	return execTimeSeriesStatements(db, statements)
+}
+
+// execTimeSeriesStatements 逐条执行，连续聚合不能在事务中创建，因此不使用事务
+func execTimeSeriesStatements(db *gorm.DB, statements []string) error {
+	for _, sql := range statements {
+		if err := db.Exec(sql).Error; err != nil {
+			return fmt.Errorf("设置时间序列存储失败: %w", err)
+		}
+	}
+	return nil
+}
+
+// validateTimeSeriesConfig 校验时间列、标签列、间隔，以及主键和唯一约束是否满足超表要求
+func validateTimeSeriesConfig(fields []models.TableField, config *models.TimeSeriesConfig) error {
+	byName := make(map[string]models.TableField, len(fields))
+	for _, field := range fields {
+		byName[field.NameEn] = field
+	}
+	timeField, ok := byName[config.TimeColumn]
+	switch {
+	case config.TimeColumn == "":
+		return fmt.Errorf("%w: 时间列不能为空", ErrInvalidTimeSeriesRequest)
+	case !ok:
+		return fmt.Errorf("%w: 时间列%s不存在", ErrInvalidTimeSeriesRequest, config.TimeColumn)
+	case !isTimeSeriesTimeType(timeField.DataType):
+		return fmt.Errorf("%w: 时间列%s须为时间戳或日期类型", ErrInvalidTimeSeriesRequest, config.TimeColumn)
+	}
+
+	seen := make(map[string]bool, len(config.TagColumns))
+	for _, tag := range config.TagColumns {
+		switch _, ok := byName[tag]; {
+		case !ok:
+			return fmt.Errorf("%w: 标签列%s不存在", ErrInvalidTimeSeriesRequest, tag)
+		case tag == config.TimeColumn:
+			return fmt.Errorf("%w: 标签列%s不能是时间列", ErrInvalidTimeSeriesRequest, tag)
+		case seen[tag]:
+			return fmt.Errorf("%w: 标签列%s重复", ErrInvalidTimeSeriesRequest, tag)
+		}
+		seen[tag] = true
+	}
+
+	hasPrimaryKey := false
+	for _, field := range fields {
+		hasPrimaryKey = hasPrimaryKey || field.IsPrimaryKey
+		if field.IsUnique && !field.IsPrimaryKey && field.NameEn != config.TimeColumn {
+			return fmt.Errorf("%w: 字段%s有唯一约束，超表的唯一约束须包含时间列，请先取消唯一约束", ErrInvalidTimeSeriesRequest, field.NameEn)
+		}
+	}
+	if hasPrimaryKey && !timeField.IsPrimaryKey {
+		return fmt.Errorf("%w: 超表的主键须包含时间列%s", ErrInvalidTimeSeriesRequest, config.TimeColumn)
+	}
+
+	for _, interval := range []struct{ name, value string }{
+		{"分块间隔", config.ChunkInterval},
+		{"压缩时间", config.CompressAfter},
+		{"保留时间", config.RetentionPeriod},
+	} {
+		if interval.value != "" && !models.TimeSeriesIntervalPattern.MatchString(interval.value) {
+			return fmt.Errorf("%w: %s%s格式不正确，应为\"数字 单位\"，如7 days", ErrInvalidTimeSeriesRequest, interval.name, interval.value)
+		}
+	}
+	return nil
+}
+
+// validateTimeSeriesAggregate 校验连续聚合配置并补齐默认值：刷新周期和刷新窗口终点默认为一个时间桶
+func validateTimeSeriesAggregate(fields []models.TableField, config *models.TimeSeriesConfig, aggregate *models.TimeSeriesAggregate) error {
+	if !timeSeriesAggregateNamePattern.MatchString(aggregate.Name) {
+		return fmt.Errorf("%w: 聚合名须以小写字母开头，只含小写字母、数字和下划线，不超过31个字符", ErrInvalidTimeSeriesRequest)
+	}
+	bucket, ok := models.ParseTimeSeriesInterval(aggregate.BucketInterval)
+	if !ok {
+		return fmt.Errorf("%w: 时间桶宽度%s格式不正确", ErrInvalidTimeSeriesRequest, aggregate.BucketInterval)
+	}
+	if aggregate.RefreshEndOffset == "" {
+		aggregate.RefreshEndOffset = aggregate.BucketInterval
+	}
+	if aggregate.ScheduleInterval == "" {
+		aggregate.ScheduleInterval = aggregate.BucketInterval
+	}
+	start, ok := models.ParseTimeSeriesInterval(aggregate.RefreshStartOffset)
+	if !ok {
+		return fmt.Errorf("%w: 刷新窗口起点%s格式不正确", ErrInvalidTimeSeriesRequest, aggregate.RefreshStartOffset)
+	}
+	end, ok := models.ParseTimeSeriesInterval(aggregate.RefreshEndOffset)
+	if !ok {
+		return fmt.Errorf("%w: 刷新窗口终点%s格式不正确", ErrInvalidTimeSeriesRequest, aggregate.RefreshEndOffset)
+	}
+	if !models.TimeSeriesIntervalPattern.MatchString(aggregate.ScheduleInterval) {
+		return fmt.Errorf("%w: 刷新周期%s格式不正确", ErrInvalidTimeSeriesRequest, aggregate.ScheduleInterval)
+	}
+	if start-end < 2*bucket {
+		return fmt.Errorf("%w: 刷新窗口须至少覆盖两个时间桶", ErrInvalidTimeSeriesRequest)
+	}
+	return validateTimeSeriesMetrics(fields, config, aggregate.Metrics)
+}
+
+// validateTimeSeriesMetrics 校验聚合指标：函数受支持，指标列存在且不是时间列或标签列，avg、sum只用于数值列，指标不重复
+func validateTimeSeriesMetrics(fields []models.TableField, config *models.TimeSeriesConfig, metrics []models.TimeSeriesMetric) error {
+	if len(metrics) == 0 {
+		return fmt.Errorf("%w: 聚合指标不能为空", ErrInvalidTimeSeriesRequest)
+	}
+	byName := make(map[string]models.TableField, len(fields))
+	for _, field := range fields {
+		byName[field.NameEn] = field
+	}
+	aliases := make(map[string]bool, len(metrics))
+	for _, metric := range metrics {
+		numericOnly, supported := timeSeriesFunctions[metric.Function]
+		field, ok := byName[metric.Column]
+		switch {
+		case !supported:
+			return fmt.Errorf("%w: 不支持聚合函数%s", ErrInvalidTimeSeriesRequest, metric.Function)
+		case !ok:
+			return fmt.Errorf("%w: 指标列%s不存在", ErrInvalidTimeSeriesRequest, metric.Column)
+		case metric.Column == config.TimeColumn || containsAll(config.TagColumns, []string{metric.Column}):
+			return fmt.Errorf("%w: 指标列%s不能是时间列或标签列", ErrInvalidTimeSeriesRequest, metric.Column)
+		case numericOnly && !isTimeSeriesNumericType(field.DataType):
+			return fmt.Errorf("%w: 聚合函数%s只能用于数值列，%s不是数值列", ErrInvalidTimeSeriesRequest, metric.Function, metric.Column)
+		case aliases[metric.Alias()]:
+			return fmt.Errorf("%w: 指标%s重复", ErrInvalidTimeSeriesRequest, metric.Alias())
+		}
+		aliases[metric.Alias()] = true
+	}
+	return nil
+}
+
+// isTimeSeriesTimeType 超表分块列支持的字段类型
+func isTimeSeriesTimeType(dataType string) bool {
+	dataType = strings.ToLower(dataType)
+	return strings.HasPrefix(dataType, "timestamp") || dataType == "datetime" || dataType == "date"
+}
+
+// isTimeSeriesNumericType avg、sum支持的字段类型
+func isTimeSeriesNumericType(dataType string) bool {
+	switch strings.ToLower(dataType) {
+	case "integer", "int", "bigint", "smallint", "decimal", "numeric", "float", "real", "double", "double precision":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,104 @@
+/*
+ * @module service/basic_library/timeseries_service_test
+ * @description 接口时间序列存储配置测试，覆盖时间列、标签列、主键和唯一约束、间隔格式的校验，以及连续聚合的校验和默认值
+ * @architecture 测试层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow 构造字段配置 -> 校验时间序列配置 -> 校验连续聚合
+ * @rules 纯函数测试，不依赖数据库
+ * @dependencies stretchr/testify
+ * @refs timeseries_service.go
+ */
+
+package basic_library
+
+import (
+	"datahub-service/service/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func timeSeriesTestFields() []models.TableField {
+	return []models.TableField{
+		{NameEn: "device_id", DataType: "varchar", IsPrimaryKey: true, IsUnique: true, OrderNum: 1},
+		{NameEn: "collected_at", DataType: "timestamp", IsPrimaryKey: true, IsUnique: true, OrderNum: 2},
+		{NameEn: "region", DataType: "varchar", IsNullable: true, OrderNum: 3},
+		{NameEn: "temperature", DataType: "double", IsNullable: true, OrderNum: 4},
+		{NameEn: "status", DataType: "varchar", IsNullable: true, OrderNum: 5},
+	}
+}
+
+func TestValidateTimeSeriesConfig(t *testing.T) {
+	fields := timeSeriesTestFields()
+	config := &models.TimeSeriesConfig{
+		TimeColumn: "collected_at", TagColumns: []string{"device_id", "region"},
+		ChunkInterval: "1 day", CompressAfter: "7 days", RetentionPeriod: "1 year",
+	}
+	require.NoError(t, validateTimeSeriesConfig(fields, config))
+
+	for name, bad := range map[string]*models.TimeSeriesConfig{
+		"时间列不存在":  {TimeColumn: "ts"},
+		"时间列不是时间": {TimeColumn: "region"},
+		"标签列不存在":  {TimeColumn: "collected_at", TagColumns: []string{"city"}},
+		"标签列为时间列": {TimeColumn: "collected_at", TagColumns: []string{"collected_at"}},
+		"标签列重复":   {TimeColumn: "collected_at", TagColumns: []string{"region", "region"}},
+		"间隔格式错误":  {TimeColumn: "collected_at", ChunkInterval: "1d"},
+		"保留时间注入":  {TimeColumn: "collected_at", RetentionPeriod: "1 day'; DROP TABLE x; --"},
+	} {
+		assert.ErrorIs(t, validateTimeSeriesConfig(fields, bad), ErrInvalidTimeSeriesRequest, name)
+	}
+
+	noTimeKey := timeSeriesTestFields()
+	noTimeKey[1].IsPrimaryKey = false
+	noTimeKey[1].IsUnique = false
+	assert.ErrorIs(t, validateTimeSeriesConfig(noTimeKey, config), ErrInvalidTimeSeriesRequest, "主键不含时间列")
+
+	unique := timeSeriesTestFields()
+	unique[4].IsUnique = true
+	assert.ErrorIs(t, validateTimeSeriesConfig(unique, config), ErrInvalidTimeSeriesRequest, "其他唯一字段")
+
+	noKey := timeSeriesTestFields()
+	noKey[0].IsPrimaryKey, noKey[0].IsUnique = false, false
+	noKey[1].IsPrimaryKey, noKey[1].IsUnique = false, false
+	assert.NoError(t, validateTimeSeriesConfig(noKey, config), "没有主键")
+}
+
+func TestValidateTimeSeriesAggregate(t *testing.T) {
+	fields := timeSeriesTestFields()
+	config := &models.TimeSeriesConfig{Enabled: true, TimeColumn: "collected_at", TagColumns: []string{"device_id"}}
+
+	aggregate := models.TimeSeriesAggregate{
+		Name: "hourly", BucketInterval: "1 hour", RefreshStartOffset: "3 days",
+		Metrics: []models.TimeSeriesMetric{{Column: "temperature", Function: "avg"}, {Column: "status", Function: "last"}},
+	}
+	require.NoError(t, validateTimeSeriesAggregate(fields, config, &aggregate))
+	assert.Equal(t, "1 hour", aggregate.RefreshEndOffset, "刷新窗口终点默认为一个时间桶")
+	assert.Equal(t, "1 hour", aggregate.ScheduleInterval, "刷新周期默认为一个时间桶")
+
+	valid := func() models.TimeSeriesAggregate {
+		return models.TimeSeriesAggregate{
+			Name: "daily", BucketInterval: "1 day", RefreshStartOffset: "7 days",
+			Metrics: []models.TimeSeriesMetric{{Column: "temperature", Function: "max"}},
+		}
+	}
+	for name, mutate := range map[string]func(*models.TimeSeriesAggregate){
+		"聚合名不合法":    func(a *models.TimeSeriesAggregate) { a.Name = "Daily-1" },
+		"时间桶格式错误":   func(a *models.TimeSeriesAggregate) { a.BucketInterval = "daily" },
+		"刷新窗口不足两个桶": func(a *models.TimeSeriesAggregate) { a.RefreshStartOffset = "2 days" },
+		"没有指标":      func(a *models.TimeSeriesAggregate) { a.Metrics = nil },
+		"不支持的函数":    func(a *models.TimeSeriesAggregate) { a.Metrics[0].Function = "median" },
+		"指标列不存在":    func(a *models.TimeSeriesAggregate) { a.Metrics[0].Column = "humidity" },
+		"指标列为标签列":   func(a *models.TimeSeriesAggregate) { a.Metrics[0].Column = "device_id" },
+		"非数值列求平均": func(a *models.TimeSeriesAggregate) {
+			a.Metrics[0] = models.TimeSeriesMetric{Column: "status", Function: "avg"}
+		},
+		"指标重复": func(a *models.TimeSeriesAggregate) {
+			a.Metrics = append(a.Metrics, models.TimeSeriesMetric{Column: "temperature", Function: "max"})
+		},
+	} {
+		bad := valid()
+		mutate(&bad)
+		assert.ErrorIs(t, validateTimeSeriesAggregate(fields, config, &bad), ErrInvalidTimeSeriesRequest, name)
+	}
+}
@@ -13,6 +13,7 @@ package basic_library
 
 import (
 	"datahub-service/service/meta"
+	"datahub-service/service/secrets"
 	"fmt"
 	"strings"
 
@@ -56,6 +57,12 @@ func (s *ValidationService) ValidateDataSourceConfig(dataSourceType string, conn
 	// 使用统一的验证逻辑
 	validationResult := definition.ValidateConfig(connectionConfig, paramsConfig)
 
+	// 密钥引用格式，要求凭据使用引用时检查明文凭据
+	for _, problem := range append(secrets.CheckConfig(connectionConfig), secrets.CheckConfig(paramsConfig)...) {
+		validationResult.Errors = append(validationResult.Errors, problem)
+		validationResult.IsValid = false
+	}
+
 	// 转换为兼容的结果格式
 	result := &ConfigValidationResult{
 		IsValid:  validationResult.IsValid,
@@ -0,0 +1,738 @@
+/*
+ * @module service/basic_library/value_alert_service
+ * @description 数据值告警服务，管理接口上的阈值和断流告警规则，在同步写入、遥测写入后及定时判定入库数据，命中时产生告警并推送到通知中心
+ * @architecture 分层架构 - 业务服务层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow 同步写入完成/遥测批次写入/定时调度 -> 取接口启用的规则 -> 按窗口查询接口表(可按分组列分组) -> 与上次状态比较 ->
+ *            新命中：创建firing告警并记录value_alert_firing事件；恢复：告警标记为resolved并记录value_alert_resolved事件
+ * @rules 同一规则同一分组同时只有一条firing告警，由部分唯一索引保证，多实例并发判定不会重复告警；
+ *        threshold规则窗口内没有数据的分组不判定，保持原状态（未分组的count规则没有数据时按0判定）；
+ *        offline规则按分组判定时只考虑最近十个窗口内出现过的分组，更早消失的分组保持告警直到恢复；
+ *        遥测写入触发的判定每个接口最短间隔valueAlertMinInterval；单条规则最多判定maxValueAlertGroups个分组；
+ *        修改规则的判定条件时结束当前告警，下次判定重新开始
+ * @dependencies datahub-service/service/distributed_lock, datahub-service/service/eventlog, datahub-service/service/models, gorm.io/gorm, github.com/robfig/cron/v3
+ * @refs service/models/value_alert.go, sync_task_service.go, service/telemetry/service.go, service/notification, api/controllers/value_alert_controller.go
+ */
+
+package basic_library
+
+import (
+	"context"
+	"datahub-service/service/distributed_lock"
+	"datahub-service/service/eventlog"
+	"datahub-service/service/models"
+	"datahub-service/service/utils"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	// defaultValueAlertSchedule 默认每分钟判定一次，断流规则依赖定时判定
+	defaultValueAlertSchedule = "30 * * * * *"
+	// valueAlertLockKey 多实例部署时定时判定的锁
+	valueAlertLockKey = "value_alert_evaluate"
+	valueAlertLockTTL = 5 * time.Minute
+	// valueAlertMinInterval 写入触发判定的最短间隔，遥测每个批次都会触发
+	valueAlertMinInterval = 10 * time.Second
+	// maxValueAlertGroups 单条规则最多判定的分组数
+	maxValueAlertGroups = 1000
+	// offlineHorizonWindows 断流规则按分组判定时，只考虑最近这么多个窗口内出现过的分组
+	offlineHorizonWindows = 10
+)
+
+// ErrInvalidValueAlertRequest 数据值告警规则不合法
+var ErrInvalidValueAlertRequest = errors.New("数据值告警规则不合法")
+
+// valueAlertAggregates threshold规则支持的聚合函数及名称，count以外只能用于数值列
+var valueAlertAggregates = map[string]string{
+	"avg":   "平均值",
+	"min":   "最小值",
+	"max":   "最大值",
+	"sum":   "合计",
+	"count": "行数",
+	"last":  "最新值",
+}
+
+// valueAlertOperator 比较符
+type valueAlertOperator struct {
+	label   string
+	compare func(value, threshold float64) bool
+}
+
+// valueAlertOperators threshold规则支持的比较符
+var valueAlertOperators = map[string]valueAlertOperator{
+	"gt":  {"大于", func(v, t float64) bool { return v > t }},
+	"gte": {"不小于", func(v, t float64) bool { return v >= t }},
+	"lt":  {"小于", func(v, t float64) bool { return v < t }},
+	"lte": {"不大于", func(v, t float64) bool { return v <= t }},
+	"eq":  {"等于", func(v, t float64) bool { return v == t }},
+	"ne":  {"不等于", func(v, t float64) bool { return v != t }},
+}
+
+// ValueAlertService 数据值告警服务
+type ValueAlertService struct {
+	db       *gorm.DB
+	lock     distributed_lock.DistributedLock
+	schedule string
+	cron     *cron.Cron
+	ctx      context.Context
+	cancel   context.CancelFunc
+	started  bool
+
+	mu            sync.Mutex
+	lastTriggered map[string]time.Time // 写入触发判定的最近时间，按接口
+}
+
+// NewValueAlertService 创建数据值告警服务，定时判定周期可通过VALUE_ALERT_SCHEDULE配置
+func NewValueAlertService(db *gorm.DB) *ValueAlertService {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	schedule := os.Getenv("VALUE_ALERT_SCHEDULE")
+	if schedule == "" {
+		schedule = defaultValueAlertSchedule
+	}
+
+	return &ValueAlertService{
+		db:            db,
+		schedule:      schedule,
+		cron:          cron.New(cron.WithSeconds()),
+		ctx:           ctx,
+		cancel:        cancel,
+		lastTriggered: make(map[string]time.Time),
+	}
+}
+
+// SetDistributedLock 设置分布式锁，多实例部署时只有一个实例定时判定
+func (s *ValueAlertService) SetDistributedLock(lock distributed_lock.DistributedLock) {
+	s.lock = lock
+}
+
+// ValueAlertEvaluation 一条规则一次判定的结果
+type ValueAlertEvaluation struct {
+	RuleID    string `json:"rule_id"`
+	Evaluated int    `json:"evaluated"` // 判定的分组数，规则未分组时为0或1
+	Breached  int    `json:"breached"`  // 命中规则的分组数
+	Fired     int    `json:"fired"`     // 本次新产生的告警数
+	Resolved  int    `json:"resolved"`  // 本次恢复的告警数
+}
+
+// ValueAlertRunResult 一次定时判定的结果
+type ValueAlertRunResult struct {
+	Checked  int `json:"checked"`
+	Failed   int `json:"failed"`
+	Fired    int `json:"fired"`
+	Resolved int `json:"resolved"`
+}
+
+// valueAlertObservation 一个分组在判定窗口内的观测值
+type valueAlertObservation struct {
+	GroupValue string
+	Value      *float64
+	LastSeenAt *time.Time
+	Breached   bool
+}
+
+// ListRules 获取接口的数据值告警规则
+func (s *ValueAlertService) ListRules(ctx context.Context, interfaceID string) ([]models.ValueAlertRule, error) {
+	if _, err := getTenantInterface(ctx, s.db, interfaceID); err != nil {
+		return nil, err
+	}
+	rules := []models.ValueAlertRule{}
+	if err := s.db.WithContext(ctx).Where("interface_id = ?", interfaceID).Order("created_at").Find(&rules).Error; err != nil {
+		return nil, fmt.Errorf("查询数据值告警规则失败: %w", err)
+	}
+	return rules, nil
+}
+
+// CreateRule 在接口上创建数据值告警规则
+func (s *ValueAlertService) CreateRule(ctx context.Context, interfaceID string, rule *models.ValueAlertRule, username string) (*models.ValueAlertRule, error) {
+	iface, err := getTenantInterface(ctx, s.db, interfaceID)
+	if err != nil {
+		return nil, err
+	}
+	rule.ID = ""
+	rule.InterfaceID = interfaceID
+	rule.CreatedBy = username
+	rule.UpdatedBy = username
+	if err := validateValueAlertRule(iface, rule); err != nil {
+		return nil, err
+	}
+	if err := s.checkNameAvailable(ctx, interfaceID, rule.Name, ""); err != nil {
+		return nil, err
+	}
+
+	if err := s.db.WithContext(ctx).Create(rule).Error; err != nil {
+		return nil, fmt.Errorf("保存数据值告警规则失败: %w", err)
+	}
+	slog.Info("创建数据值告警规则", "interface_id", interfaceID, "rule_id", rule.ID, "rule", rule.Name,
+		"rule_type", rule.RuleType, "severity", rule.Severity, "username", username)
+	return rule, nil
+}
+
+// UpdateRule 修改数据值告警规则，判定条件变化时结束规则当前的告警
+func (s *ValueAlertService) UpdateRule(ctx context.Context, interfaceID, ruleID string, input *models.ValueAlertRule, username string) (*models.ValueAlertRule, error) {
+	iface, rule, err := s.getRule(ctx, interfaceID, ruleID)
+	if err != nil {
+		return nil, err
+	}
+	input.ID = rule.ID
+	input.InterfaceID = interfaceID
+	if err := validateValueAlertRule(iface, input); err != nil {
+		return nil, err
+	}
+	if err := s.checkNameAvailable(ctx, interfaceID, input.Name, rule.ID); err != nil {
+		return nil, err
+	}
+	conditionChanged := valueAlertCondition(rule) != valueAlertCondition(input)
+
+	updates := map[string]interface{}{
+		"name":         input.Name,
+		"rule_type":    input.RuleType,
+		"time_column":  input.TimeColumn,
+		"column":       input.Column,
+		"aggregate":    input.Aggregate,
+		"operator":     input.Operator,
+		"threshold":    input.Threshold,
+		"window":       input.Window,
+		"group_column": input.GroupColumn,
+		"severity":     input.Severity,
+		"enabled":      input.Enabled,
+		"description":  input.Description,
+		"last_error":   "",
+		"updated_at":   time.Now(),
+		"updated_by":   username,
+	}
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(rule).Updates(updates).Error; err != nil {
+			return fmt.Errorf("保存数据值告警规则失败: %w", err)
+		}
+		if conditionChanged || !input.Enabled {
+			return resolveRuleAlerts(tx, rule.ID, time.Now())
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := s.db.WithContext(ctx).First(rule, "id = ?", rule.ID).Error; err != nil {
+		return nil, err
+	}
+
+	slog.Info("修改数据值告警规则", "interface_id", interfaceID, "rule_id", rule.ID, "rule", rule.Name,
+		"enabled", rule.Enabled, "condition_changed", conditionChanged, "username", username)
+	return rule, nil
+}
+
+// DeleteRule 删除数据值告警规则及其告警记录
+func (s *ValueAlertService) DeleteRule(ctx context.Context, interfaceID, ruleID, username string) error {
+	_, rule, err := s.getRule(ctx, interfaceID, ruleID)
+	if err != nil {
+		return err
+	}
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("rule_id = ?", rule.ID).Delete(&models.ValueAlert{}).Error; err != nil {
+			return fmt.Errorf("删除数据值告警记录失败: %w", err)
+		}
+		if err := tx.Delete(rule).Error; err != nil {
+			return fmt.Errorf("删除数据值告警规则失败: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	slog.Info("删除数据值告警规则", "interface_id", interfaceID, "rule_id", rule.ID, "rule", rule.Name, "username", username)
+	return nil
+}
+
+// ListAlerts 分页获取接口的数据值告警，告警中的排在前面
+func (s *ValueAlertService) ListAlerts(ctx context.Context, interfaceID, status string, page, size int) ([]models.ValueAlert, int64, error) {
+	if _, err := getTenantInterface(ctx, s.db, interfaceID); err != nil {
+		return nil, 0, err
+	}
+	query := s.db.WithContext(ctx).Model(&models.ValueAlert{}).Where("interface_id = ?", interfaceID)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("查询数据值告警失败: %w", err)
+	}
+	alerts := []models.ValueAlert{}
+	// firing按字母序排在resolved前面
+	err := query.Order("status, fired_at DESC").Offset((page - 1) * size).Limit(size).Find(&alerts).Error
+	if err != nil {
+		return nil, 0, fmt.Errorf("查询数据值告警失败: %w", err)
+	}
+	return alerts, total, nil
+}
+
+// EvaluateRule 立即判定一条规则，停用的规则也可以判定
+func (s *ValueAlertService) EvaluateRule(ctx context.Context, interfaceID, ruleID string) (*ValueAlertEvaluation, error) {
+	_, rule, err := s.getRule(ctx, interfaceID, ruleID)
+	if err != nil {
+		return nil, err
+	}
+	return s.evaluate(ctx, rule)
+}
+
+// EvaluateInterfaces 判定接口上启用的规则，同步写入完成后调用，失败只记录到规则
+func (s *ValueAlertService) EvaluateInterfaces(ctx context.Context, interfaceIDs []string) {
+	if len(interfaceIDs) == 0 {
+		return
+	}
+	var rules []models.ValueAlertRule
+	if err := s.db.WithContext(ctx).Where("interface_id IN ? AND enabled = ?", interfaceIDs, true).Find(&rules).Error; err != nil {
+		slog.Warn("查询数据值告警规则失败", "interface_ids", interfaceIDs, "error", err)
+		return
+	}
+	for i := range rules {
+		s.evaluate(ctx, &rules[i])
+	}
+}
+
+// NotifyWritten 接口有新数据写入，距上次写入触发的判定超过valueAlertMinInterval时在后台判定，不阻塞调用方
+func (s *ValueAlertService) NotifyWritten(interfaceID string) {
+	now := time.Now()
+	s.mu.Lock()
+	if last, ok := s.lastTriggered[interfaceID]; ok && now.Sub(last) < valueAlertMinInterval {
+		s.mu.Unlock()
+		return
+	}
+	s.lastTriggered[interfaceID] = now
+	s.mu.Unlock()
+
+	go func() {
+		ctx, cancel := context.WithTimeout(s.ctx, time.Minute)
+		defer cancel()
+		s.EvaluateInterfaces(ctx, []string{interfaceID})
+	}()
+}
+
+// EvaluateAll 判定全部启用的规则
+func (s *ValueAlertService) EvaluateAll(ctx context.Context) (*ValueAlertRunResult, error) {
+	var rules []models.ValueAlertRule
+	if err := s.db.WithContext(ctx).Where("enabled = ?", true).Find(&rules).Error; err != nil {
+		return nil, fmt.Errorf("查询数据值告警规则失败: %w", err)
+	}
+
+	result := &ValueAlertRunResult{}
+	for i := range rules {
+		if ctx.Err() != nil {
+			break
+		}
+		result.Checked++
+		evaluation, err := s.evaluate(ctx, &rules[i])
+		if err != nil {
+			result.Failed++
+			continue
+		}
+		result.Fired += evaluation.Fired
+		result.Resolved += evaluation.Resolved
+	}
+	return result, nil
+}
+
+// Start 启动定时判定
+func (s *ValueAlertService) Start() error {
+	if s.started {
+		return fmt.Errorf("数据值告警调度器已经启动")
+	}
+
+	_, err := s.cron.AddFunc(s.schedule, func() {
+		if s.lock != nil {
+			locked, err := s.lock.TryLock(s.ctx, valueAlertLockKey, valueAlertLockTTL)
+			if err != nil || !locked {
+				return
+			}
+			defer func() {
+				if err := s.lock.Unlock(context.Background(), valueAlertLockKey); err != nil {
+					slog.Error("释放数据值告警判定锁失败", "error", err)
+				}
+			}()
+		}
+
+		result, err := s.EvaluateAll(s.ctx)
+		if err != nil {
+			slog.Error("定时判定数据值告警失败", "error", err)
+			return
+		}
+		if result.Fired > 0 || result.Resolved > 0 || result.Failed > 0 {
+			slog.Debug("定时判定数据值告警完成", "checked", result.Checked, "failed", result.Failed,
+				"fired", result.Fired, "resolved", result.Resolved)
+		}
+	})
+	if err != nil {
+		return fmt.Errorf("添加定时任务失败: %w", err)
+	}
+
+	s.cron.Start()
+	s.started = true
+	slog.Info("数据值告警调度器启动成功", "schedule", s.schedule)
+	return nil
+}
+
+// Stop 停止定时判定
+func (s *ValueAlertService) Stop() {
+	if !s.started {
+		return
+	}
+	s.cancel()
+	s.cron.Stop()
+	s.started = false
+}
+
+// evaluate 判定一条规则并更新告警状态，判定结果记录到规则
+func (s *ValueAlertService) evaluate(ctx context.Context, rule *models.ValueAlertRule) (*ValueAlertEvaluation, error) {
+	evaluation, err := s.observe(ctx, rule)
+	now := time.Now()
+	updates := map[string]interface{}{"last_evaluated_at": now, "last_error": ""}
+	if err != nil {
+		slog.Warn("数据值告警规则判定失败", "rule_id", rule.ID, "rule", rule.Name, "interface_id", rule.InterfaceID, "error", err)
+		updates["last_error"] = err.Error()
+	}
+	if err := s.db.WithContext(ctx).Model(rule).UpdateColumns(updates).Error; err != nil {
+		slog.Warn("记录数据值告警规则判定结果失败", "rule_id", rule.ID, "error", err)
+	}
+	return evaluation, err
+}
+
+// observe 查询判定窗口内的数据，逐个分组比较上次状态，产生或恢复告警
+func (s *ValueAlertService) observe(ctx context.Context, rule *models.ValueAlertRule) (*ValueAlertEvaluation, error) {
+	var iface models.DataInterface
+	if err := s.db.WithContext(ctx).Preload("BasicLibrary").First(&iface, "id = ?", rule.InterfaceID).Error; err != nil {
+		return nil, fmt.Errorf("查询接口失败: %w", err)
+	}
+	if !iface.IsTableCreated {
+		return nil, fmt.Errorf("接口表尚未创建")
+	}
+
+	table := utils.QuoteTable(iface.BasicLibrary.GetSchemaName(), iface.NameEn)
+	sql, args := buildValueAlertSQL(table, rule)
+	var observations []valueAlertObservation
+	if err := s.db.WithContext(ctx).Raw(sql, args...).Scan(&observations).Error; err != nil {
+		return nil, fmt.Errorf("查询接口数据失败: %w", err)
+	}
+
+	var firing []models.ValueAlert
+	if err := s.db.WithContext(ctx).Where("rule_id = ? AND status = ?", rule.ID, models.ValueAlertStatusFiring).Find(&firing).Error; err != nil {
+		return nil, fmt.Errorf("查询告警记录失败: %w", err)
+	}
+	firingByGroup := make(map[string]*models.ValueAlert, len(firing))
+	for i := range firing {
+		firingByGroup[firing[i].GroupValue] = &firing[i]
+	}
+
+	evaluation := &ValueAlertEvaluation{RuleID: rule.ID}
+	now := time.Now()
+	for i := range observations {
+		observation := &observations[i]
+		if rule.RuleType == models.ValueAlertTypeThreshold {
+			if observation.Value == nil {
+				continue
+			}
+			observation.Breached = valueAlertBreached(rule, *observation.Value)
+		}
+		evaluation.Evaluated++
+		alert, isFiring := firingByGroup[observation.GroupValue]
+		switch {
+		case observation.Breached:
+			evaluation.Breached++
+			if isFiring {
+				continue
+			}
+			fired, err := s.fire(ctx, &iface, rule, observation, now)
+			if err != nil {
+				return nil, err
+			}
+			if fired {
+				evaluation.Fired++
+			}
+		case isFiring:
+			resolved, err := s.resolve(ctx, &iface, rule, alert, now)
+			if err != nil {
+				return nil, err
+			}
+			if resolved {
+				evaluation.Resolved++
+			}
+		}
+	}
+	return evaluation, nil
+}
+
+// fire 创建firing告警并记录事件，其他实例已创建时返回false
+func (s *ValueAlertService) fire(ctx context.Context, iface *models.DataInterface, rule *models.ValueAlertRule, observation *valueAlertObservation, now time.Time) (bool, error) {
+	alert := &models.ValueAlert{
+		RuleID:      rule.ID,
+		InterfaceID: rule.InterfaceID,
+		GroupValue:  observation.GroupValue,
+		Severity:    rule.Severity,
+		Status:      models.ValueAlertStatusFiring,
+		Value:       observation.Value,
+		LastSeenAt:  observation.LastSeenAt,
+		Message:     valueAlertMessage(rule, observation),
+		FiredAt:     now,
+	}
+	result := s.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(alert)
+	if result.Error != nil {
+		return false, fmt.Errorf("保存告警记录失败: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return false, nil
+	}
+
+	event := valueAlertEvent(iface, rule, alert)
+	event.Type = eventlog.EventValueAlertFiring
+	event.Level = rule.EventLevel()
+	event.Message = alert.Message
+	if alert.Value != nil {
+		event.Attributes["value"] = *alert.Value
+	}
+	if alert.LastSeenAt != nil {
+		event.Attributes["last_seen_at"] = alert.LastSeenAt.Format(time.RFC3339)
+	}
+	eventlog.Record(ctx, event)
+	return true, nil
+}
+
+// resolve 告警标记为resolved并记录事件，其他实例已恢复时返回false
+func (s *ValueAlertService) resolve(ctx context.Context, iface *models.DataInterface, rule *models.ValueAlertRule, alert *models.ValueAlert, now time.Time) (bool, error) {
+	result := s.db.WithContext(ctx).Model(&models.ValueAlert{}).
+		Where("id = ? AND status = ?", alert.ID, models.ValueAlertStatusFiring).
+		Updates(map[string]interface{}{"status": models.ValueAlertStatusResolved, "resolved_at": now})
+	if result.Error != nil {
+		return false, fmt.Errorf("恢复告警记录失败: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return false, nil
+	}
+
+	event := valueAlertEvent(iface, rule, alert)
+	event.Type = eventlog.EventValueAlertResolved
+	event.Message = "数据值告警恢复: " + rule.Name
+	eventlog.Record(ctx, event)
+	return true, nil
+}
+
+// checkNameAvailable 同一接口的规则不能重名
+func (s *ValueAlertService) checkNameAvailable(ctx context.Context, interfaceID, name, excludeID string) error {
+	var count int64
+	err := s.db.WithContext(ctx).Model(&models.ValueAlertRule{}).
+		Where("interface_id = ? AND name = ? AND id <> ?", interfaceID, name, excludeID).Count(&count).Error
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return fmt.Errorf("%w: 接口上已有名为%s的规则", ErrInvalidValueAlertRequest, name)
+	}
+	return nil
+}
+
+// getRule 获取接口的数据值告警规则
+func (s *ValueAlertService) getRule(ctx context.Context, interfaceID, ruleID string) (*models.DataInterface, *models.ValueAlertRule, error) {
+	iface, err := getTenantInterface(ctx, s.db, interfaceID)
+	if err != nil {
+		return nil, nil, err
+	}
+	var rule models.ValueAlertRule
+	if err := s.db.WithContext(ctx).First(&rule, "id = ? AND interface_id = ?", ruleID, interfaceID).Error; err != nil {
+		return nil, nil, err
+	}
+	return iface, &rule, nil
+}
+
+// resolveRuleAlerts 结束规则当前的告警，不记录恢复事件
+func resolveRuleAlerts(tx *gorm.DB, ruleID string, now time.Time) error {
+	err := tx.Model(&models.ValueAlert{}).Where("rule_id = ? AND status = ?", ruleID, models.ValueAlertStatusFiring).
+		Updates(map[string]interface{}{"status": models.ValueAlertStatusResolved, "resolved_at": now}).Error
+	if err != nil {
+		return fmt.Errorf("结束数据值告警失败: %w", err)
+	}
+	return nil
+}
+
+// deleteInterfaceValueAlerts 删除接口的数据值告警规则和告警记录
+func deleteInterfaceValueAlerts(tx *gorm.DB, interfaceID string) error {
+	if err := tx.Where("interface_id = ?", interfaceID).Delete(&models.ValueAlert{}).Error; err != nil {
+		return err
+	}
+	return tx.Where("interface_id = ?", interfaceID).Delete(&models.ValueAlertRule{}).Error
+}
+
+// validateValueAlertRule 校验规则并补齐默认值：时间列默认取接口时间序列配置的时间列，告警级别默认warning
+func validateValueAlertRule(iface *models.DataInterface, rule *models.ValueAlertRule) error {
+	if rule.Name == "" || len([]rune(rule.Name)) > 100 {
+		return fmt.Errorf("%w: 规则名不能为空且不超过100个字符", ErrInvalidValueAlertRequest)
+	}
+	if window, ok := models.ParseTimeSeriesInterval(rule.Window); !ok || window <= 0 {
+		return fmt.Errorf("%w: 判定窗口%s格式不正确", ErrInvalidValueAlertRequest, rule.Window)
+	}
+	if rule.Severity == "" {
+		rule.Severity = models.ValueAlertSeverityWarning
+	}
+	switch rule.Severity {
+	case models.ValueAlertSeverityInfo, models.ValueAlertSeverityWarning, models.ValueAlertSeverityCritical:
+	default:
+		return fmt.Errorf("%w: 不支持告警级别%s", ErrInvalidValueAlertRequest, rule.Severity)
+	}
+
+	fields := make(map[string]models.TableField)
+	for _, field := range sortedTableFields(iface.TableFieldsConfig) {
+		fields[field.NameEn] = field
+	}
+	if rule.TimeColumn == "" {
+		if config := models.ParseTimeSeriesConfig(iface.InterfaceConfig); config != nil {
+			rule.TimeColumn = config.TimeColumn
+		}
+	}
+	if field, ok := fields[rule.TimeColumn]; !ok || !isTimeSeriesTimeType(field.DataType) {
+		return fmt.Errorf("%w: 时间列须是接口的时间类型字段", ErrInvalidValueAlertRequest)
+	}
+	if rule.GroupColumn != "" {
+		if _, ok := fields[rule.GroupColumn]; !ok || rule.GroupColumn == rule.TimeColumn {
+			return fmt.Errorf("%w: 分组列%s不存在或是时间列", ErrInvalidValueAlertRequest, rule.GroupColumn)
+		}
+	}
+
+	switch rule.RuleType {
+	case models.ValueAlertTypeThreshold:
+		field, ok := fields[rule.Column]
+		if !ok {
+			return fmt.Errorf("%w: 比较列%s不存在", ErrInvalidValueAlertRequest, rule.Column)
+		}
+		if _, ok := valueAlertAggregates[rule.Aggregate]; !ok {
+			return fmt.Errorf("%w: 不支持聚合函数%s", ErrInvalidValueAlertRequest, rule.Aggregate)
+		}
+		if rule.Aggregate != "count" && !isTimeSeriesNumericType(field.DataType) {
+			return fmt.Errorf("%w: 聚合函数%s只能用于数值列，%s不是数值列", ErrInvalidValueAlertRequest, rule.Aggregate, rule.Column)
+		}
+		if _, ok := valueAlertOperators[rule.Operator]; !ok {
+			return fmt.Errorf("%w: 不支持比较符%s", ErrInvalidValueAlertRequest, rule.Operator)
+		}
+		if rule.Threshold == nil {
+			return fmt.Errorf("%w: 阈值不能为空", ErrInvalidValueAlertRequest)
+		}
+	case models.ValueAlertTypeOffline:
+		rule.Column, rule.Aggregate, rule.Operator, rule.Threshold = "", "", "", nil
+	default:
+		return fmt.Errorf("%w: 不支持规则类型%s", ErrInvalidValueAlertRequest, rule.RuleType)
+	}
+	return nil
+}
+
+// valueAlertCondition 规则的判定条件，变化时当前告警不再适用
+func valueAlertCondition(rule *models.ValueAlertRule) string {
+	threshold := ""
+	if rule.Threshold != nil {
+		threshold = strconv.FormatFloat(*rule.Threshold, 'f', -1, 64)
+	}
+	return fmt.Sprintf("%s|%s|%s|%s|%s|%s|%s|%s", rule.RuleType, rule.TimeColumn, rule.Column, rule.Aggregate,
+		rule.Operator, threshold, rule.Window, rule.GroupColumn)
+}
+
+// valueAlertBreached threshold规则的观测值是否命中
+func valueAlertBreached(rule *models.ValueAlertRule, value float64) bool {
+	operator, ok := valueAlertOperators[rule.Operator]
+	if !ok || rule.Threshold == nil {
+		return false
+	}
+	return operator.compare(value, *rule.Threshold)
+}
+
+// buildValueAlertSQL 生成判定窗口内各分组观测值的查询，threshold规则返回聚合值，offline规则返回最近数据时间和是否断流
+func buildValueAlertSQL(table string, rule *models.ValueAlertRule) (string, []interface{}) {
+	timeColumn := utils.QuoteIdent(rule.TimeColumn)
+	group := `''`
+	if rule.GroupColumn != "" {
+		group = fmt.Sprintf("COALESCE(CAST(%s AS text), '')", utils.QuoteIdent(rule.GroupColumn))
+	}
+	groupBy := ""
+	if rule.GroupColumn != "" {
+		groupBy = fmt.Sprintf(" GROUP BY 1 ORDER BY 1 LIMIT %d", maxValueAlertGroups)
+	}
+
+	if rule.RuleType == models.ValueAlertTypeOffline {
+		sql := fmt.Sprintf(`SELECT %s AS group_value, CAST(max(%s) AS timestamptz) AS last_seen_at, `+
+			`COALESCE(max(%s) < now() - CAST(? AS interval), true) AS breached `+
+			`FROM %s WHERE %s >= now() - CAST(? AS interval) * %d%s`,
+			group, timeColumn, timeColumn, table, timeColumn, offlineHorizonWindows, groupBy)
+		return sql, []interface{}{rule.Window, rule.Window}
+	}
+
+	column := utils.QuoteIdent(rule.Column)
+	var value string
+	switch rule.Aggregate {
+	case "count":
+		value = fmt.Sprintf("count(%s)", column)
+	case "last":
+		value = fmt.Sprintf("(array_agg(%s ORDER BY %s DESC))[1]", column, timeColumn)
+	default:
+		value = fmt.Sprintf("%s(%s)", rule.Aggregate, column)
+	}
+	sql := fmt.Sprintf(`SELECT %s AS group_value, CAST(%s AS double precision) AS value `+
+		`FROM %s WHERE %s >= now() - CAST(? AS interval) AND %s IS NOT NULL%s`,
+		group, value, table, timeColumn, column, groupBy)
+	return sql, []interface{}{rule.Window}
+}
+
+// valueAlertMessage 告警内容，如"device_id=D01 近5 minutes内temperature的最新值为36.5，大于阈值35"
+func valueAlertMessage(rule *models.ValueAlertRule, observation *valueAlertObservation) string {
+	prefix := ""
+	if rule.GroupColumn != "" {
+		prefix = fmt.Sprintf("%s=%s ", rule.GroupColumn, observation.GroupValue)
+	}
+	if rule.RuleType == models.ValueAlertTypeOffline {
+		if observation.LastSeenAt == nil {
+			return fmt.Sprintf("%s近%s内没有新数据", prefix, rule.Window)
+		}
+		return fmt.Sprintf("%s超过%s没有新数据，最近一条数据时间%s", prefix, rule.Window, observation.LastSeenAt.Format(time.DateTime))
+	}
+
+	value, threshold := "", ""
+	if observation.Value != nil {
+		value = strconv.FormatFloat(*observation.Value, 'f', -1, 64)
+	}
+	if rule.Threshold != nil {
+		threshold = strconv.FormatFloat(*rule.Threshold, 'f', -1, 64)
+	}
+	return fmt.Sprintf("%s近%s内%s的%s为%s，%s阈值%s", prefix, rule.Window, rule.Column,
+		valueAlertAggregates[rule.Aggregate], value, valueAlertOperators[rule.Operator].label, threshold)
+}
+
+// valueAlertEvent 告警事件的公共部分，关联到接口以便按接口订阅通知
+func valueAlertEvent(iface *models.DataInterface, rule *models.ValueAlertRule, alert *models.ValueAlert) eventlog.Event {
+	attributes := map[string]interface{}{
+		"library_id":   iface.LibraryID,
+		"alert_id":     alert.ID,
+		"rule_id":      rule.ID,
+		"rule_name":    rule.Name,
+		"rule_type":    rule.RuleType,
+		"severity":     rule.Severity,
+		"window":       rule.Window,
+		"group_column": rule.GroupColumn,
+		"group_value":  alert.GroupValue,
+	}
+	if rule.RuleType == models.ValueAlertTypeThreshold {
+		attributes["column"] = rule.Column
+		attributes["aggregate"] = rule.Aggregate
+		attributes["operator"] = rule.Operator
+		if rule.Threshold != nil {
+			attributes["threshold"] = *rule.Threshold
+		}
+	}
+	return eventlog.Event{
+		ObjectType: "data_interface",
+		ObjectID:   iface.ID,
+		Attributes: attributes,
+	}
+}
@@ -0,0 +1,145 @@
+/*
+ * @module service/basic_library/value_alert_service_test
+ * @description 数据值告警测试，覆盖规则校验及默认值、判定SQL的生成、阈值比较和告警内容
+ * @architecture 测试层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow 构造接口字段和时间序列配置 -> 校验规则 -> 生成判定SQL -> 比较观测值并生成告警内容
+ * @rules 纯函数测试，不依赖数据库
+ * @dependencies stretchr/testify
+ * @refs value_alert_service.go
+ */
+
+package basic_library
+
+import (
+	"datahub-service/service/models"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func valueAlertTestInterface() *models.DataInterface {
+	fieldsConfig := models.JSONB{}
+	for i, field := range timeSeriesTestFields() {
+		// 与从数据库读出的配置一致，字段为map
+		var fieldMap map[string]interface{}
+		data, _ := json.Marshal(field)
+		json.Unmarshal(data, &fieldMap)
+		fieldsConfig[fmt.Sprintf("field_%d", i)] = fieldMap
+	}
+	return &models.DataInterface{
+		ID:                "if-1",
+		TableFieldsConfig: fieldsConfig,
+		InterfaceConfig: models.JSONB{models.TimeSeriesConfigKey: map[string]interface{}{
+			"enabled": true, "time_column": "collected_at", "tag_columns": []interface{}{"device_id"},
+		}},
+	}
+}
+
+func TestValidateValueAlertRule(t *testing.T) {
+	iface := valueAlertTestInterface()
+	threshold := 35.0
+
+	rule := &models.ValueAlertRule{
+		Name: "温度过高", RuleType: models.ValueAlertTypeThreshold, Column: "temperature",
+		Aggregate: "last", Operator: "gt", Threshold: &threshold, Window: "5 minutes", GroupColumn: "device_id",
+	}
+	require.NoError(t, validateValueAlertRule(iface, rule))
+	assert.Equal(t, "collected_at", rule.TimeColumn, "时间列默认取时间序列配置")
+	assert.Equal(t, models.ValueAlertSeverityWarning, rule.Severity, "告警级别默认warning")
+
+	offline := &models.ValueAlertRule{
+		Name: "传感器离线", RuleType: models.ValueAlertTypeOffline, Window: "10 minutes",
+		Column: "temperature", Aggregate: "avg", Operator: "gt", Threshold: &threshold, Severity: models.ValueAlertSeverityCritical,
+	}
+	require.NoError(t, validateValueAlertRule(iface, offline))
+	assert.Empty(t, offline.Column, "offline规则不使用比较列")
+	assert.Nil(t, offline.Threshold)
+	assert.Equal(t, models.EventLevelError, offline.EventLevel())
+
+	valid := func() *models.ValueAlertRule {
+		return &models.ValueAlertRule{
+			Name: "行数过少", RuleType: models.ValueAlertTypeThreshold, TimeColumn: "collected_at", Column: "status",
+			Aggregate: "count", Operator: "lt", Threshold: &threshold, Window: "1 hour",
+		}
+	}
+	require.NoError(t, validateValueAlertRule(iface, valid()), "count可用于非数值列")
+	for name, mutate := range map[string]func(*models.ValueAlertRule){
+		"规则名为空":     func(r *models.ValueAlertRule) { r.Name = "" },
+		"规则类型不支持":   func(r *models.ValueAlertRule) { r.RuleType = "anomaly" },
+		"窗口格式错误":    func(r *models.ValueAlertRule) { r.Window = "1h" },
+		"告警级别不支持":   func(r *models.ValueAlertRule) { r.Severity = "fatal" },
+		"时间列不是时间类型": func(r *models.ValueAlertRule) { r.TimeColumn = "region" },
+		"分组列不存在":    func(r *models.ValueAlertRule) { r.GroupColumn = "city" },
+		"比较列不存在":    func(r *models.ValueAlertRule) { r.Column = "humidity" },
+		"非数值列求平均":   func(r *models.ValueAlertRule) { r.Aggregate = "avg" },
+		"聚合函数不支持":   func(r *models.ValueAlertRule) { r.Aggregate = "median" },
+		"比较符不支持":    func(r *models.ValueAlertRule) { r.Operator = ">" },
+		"阈值为空":      func(r *models.ValueAlertRule) { r.Threshold = nil },
+	} {
+		bad := valid()
+		mutate(bad)
+		assert.True(t, errors.Is(validateValueAlertRule(iface, bad), ErrInvalidValueAlertRequest), name)
+	}
+}
+
+func TestBuildValueAlertSQL(t *testing.T) {
+	threshold := 100.0
+	rule := &models.ValueAlertRule{
+		RuleType: models.ValueAlertTypeThreshold, TimeColumn: "collected_at", Column: "energy",
+		Aggregate: "sum", Operator: "gt", Threshold: &threshold, Window: "1 hour",
+	}
+	sql, args := buildValueAlertSQL(`"s"."meter"`, rule)
+	assert.Equal(t, `SELECT '' AS group_value, CAST(sum("energy") AS double precision) AS value FROM "s"."meter" `+
+		`WHERE "collected_at" >= now() - CAST(? AS interval) AND "energy" IS NOT NULL`, sql)
+	assert.Equal(t, []interface{}{"1 hour"}, args)
+
+	rule.Aggregate = "last"
+	rule.GroupColumn = "device_id"
+	sql, _ = buildValueAlertSQL(`"s"."meter"`, rule)
+	assert.Contains(t, sql, `COALESCE(CAST("device_id" AS text), '') AS group_value`)
+	assert.Contains(t, sql, `(array_agg("energy" ORDER BY "collected_at" DESC))[1]`)
+	assert.Contains(t, sql, "GROUP BY 1 ORDER BY 1 LIMIT 1000")
+
+	offline := &models.ValueAlertRule{RuleType: models.ValueAlertTypeOffline, TimeColumn: "collected_at", Window: "10 minutes"}
+	sql, args = buildValueAlertSQL(`"s"."meter"`, offline)
+	assert.Equal(t, `SELECT '' AS group_value, CAST(max("collected_at") AS timestamptz) AS last_seen_at, `+
+		`COALESCE(max("collected_at") < now() - CAST(? AS interval), true) AS breached `+
+		`FROM "s"."meter" WHERE "collected_at" >= now() - CAST(? AS interval) * 10`, sql)
+	assert.Equal(t, []interface{}{"10 minutes", "10 minutes"}, args)
+}
+
+func TestValueAlertBreachedAndMessage(t *testing.T) {
+	threshold := 35.0
+	rule := &models.ValueAlertRule{
+		RuleType: models.ValueAlertTypeThreshold, Column: "temperature", Aggregate: "last",
+		Operator: "gt", Threshold: &threshold, Window: "5 minutes", GroupColumn: "device_id",
+	}
+	assert.True(t, valueAlertBreached(rule, 36.5))
+	assert.False(t, valueAlertBreached(rule, 35))
+	rule.Operator = "gte"
+	assert.True(t, valueAlertBreached(rule, 35))
+	rule.Operator = "gt"
+
+	value := 36.5
+	assert.Equal(t, "device_id=D01 近5 minutes内temperature的最新值为36.5，大于阈值35",
+		valueAlertMessage(rule, &valueAlertObservation{GroupValue: "D01", Value: &value}))
+
+	offline := &models.ValueAlertRule{RuleType: models.ValueAlertTypeOffline, Window: "10 minutes"}
+	assert.Equal(t, "近10 minutes内没有新数据", valueAlertMessage(offline, &valueAlertObservation{Breached: true}))
+	lastSeen := time.Date(2026, 3, 1, 8, 0, 0, 0, time.Local)
+	assert.Equal(t, "超过10 minutes没有新数据，最近一条数据时间2026-03-01 08:00:00",
+		valueAlertMessage(offline, &valueAlertObservation{LastSeenAt: &lastSeen, Breached: true}))
+
+	changed := *rule
+	changed.Window = "10 minutes"
+	assert.NotEqual(t, valueAlertCondition(rule), valueAlertCondition(&changed))
+	changed = *rule
+	changed.Severity = models.ValueAlertSeverityCritical
+	assert.Equal(t, valueAlertCondition(rule), valueAlertCondition(&changed), "告警级别不属于判定条件")
+}
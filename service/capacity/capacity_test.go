@@ -0,0 +1,177 @@
+/*
+ * @module service/capacity/capacity_test
+ * @description 存储容量统计测试，覆盖快照采集（跳过不存在的表）、按库和租户汇总增长、库明细和每日趋势以及租户过滤
+ * @architecture 测试层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow 准备sqlite中的库和接口 -> 使用模拟探测器采集快照 -> 查询报表 -> 验证汇总结果
+ * @rules 使用内存sqlite和模拟探测器，不依赖PostgreSQL系统目录
+ * @dependencies gorm.io/driver/sqlite, stretchr/testify
+ * @refs service.go, report.go
+ */
+
+package capacity
+
+import (
+	"context"
+	"datahub-service/service/models"
+	"datahub-service/service/tenant"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// fakeProbe 按"schema.table"返回预设的表大小
+type fakeProbe map[string]TableSize
+
+func (p fakeProbe) Measure(ctx context.Context, schema, table string) (*TableSize, error) {
+	size, ok := p[schema+"."+table]
+	if !ok {
+		return nil, ErrTableNotFound
+	}
+	return &size, nil
+}
+
+func setupCapacityService(t *testing.T) *Service {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+	require.NoError(t, tenant.RegisterGormCallbacks(db))
+	require.NoError(t, db.AutoMigrate(
+		&models.Tenant{}, &models.BasicLibrary{}, &models.DataInterface{},
+		&models.ThematicLibrary{}, &models.ThematicInterface{}, &models.StorageSnapshot{},
+	))
+
+	require.NoError(t, db.Create(&models.Tenant{ID: "tenant-a", Code: "park_a", Name: "A园区"}).Error)
+	require.NoError(t, db.Create(&models.BasicLibrary{ID: "lib-1", TenantID: "default", NameZh: "人口基础库", NameEn: "population"}).Error)
+	require.NoError(t, db.Create(&models.BasicLibrary{ID: "lib-2", TenantID: "tenant-a", NameZh: "车辆基础库", NameEn: "vehicle", SchemaName: "park_a_vehicle"}).Error)
+	for _, iface := range []models.DataInterface{
+		{ID: "if-1", LibraryID: "lib-1", NameZh: "人员", NameEn: "person", Type: "batch", IsTableCreated: true},
+		{ID: "if-2", LibraryID: "lib-1", NameZh: "户籍", NameEn: "household", Type: "batch", IsTableCreated: true},
+		{ID: "if-3", LibraryID: "lib-1", NameZh: "未建表", NameEn: "draft", Type: "batch"},
+		{ID: "if-4", LibraryID: "lib-2", NameZh: "通行记录", NameEn: "pass", Type: "batch", IsTableCreated: true},
+		{ID: "if-5", LibraryID: "lib-2", NameZh: "表已删除", NameEn: "dropped", Type: "batch", IsTableCreated: true},
+	} {
+		require.NoError(t, db.Create(&iface).Error)
+	}
+
+	s := NewService(db)
+	s.probe = fakeProbe{
+		"population.person":    {RowCount: 1000, TotalBytes: 8000, TableBytes: 6000, IndexBytes: 2000},
+		"population.household": {RowCount: 200, TotalBytes: 2000, TableBytes: 1500, IndexBytes: 500},
+		"park_a_vehicle.pass":  {RowCount: 5000, TotalBytes: 50000, TableBytes: 40000, IndexBytes: 10000},
+	}
+	return s
+}
+
+// createSnapshot 写入一条历史快照
+func createSnapshot(t *testing.T, s *Service, tenantID, libraryID, interfaceID string, rows, bytes int64, at time.Time) {
+	require.NoError(t, s.db.Create(&models.StorageSnapshot{
+		TenantID: tenantID, LibraryType: "basic_library", LibraryID: libraryID, InterfaceID: interfaceID,
+		SchemaName: "s", TableName: "t", RowCount: rows, TotalBytes: bytes, CapturedAt: at,
+	}).Error)
+}
+
+func TestCollect(t *testing.T) {
+	s := setupCapacityService(t)
+	ctx := context.Background()
+
+	result, err := s.Collect(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 4, result.Interfaces)
+	assert.Equal(t, 3, result.Captured)
+	assert.Equal(t, 1, result.Skipped)
+	assert.Equal(t, int64(60000), result.TotalBytes)
+
+	var snapshots []models.StorageSnapshot
+	require.NoError(t, s.db.Order("interface_id").Find(&snapshots).Error)
+	require.Len(t, snapshots, 3)
+	assert.Equal(t, "default", snapshots[0].TenantID)
+	assert.Equal(t, "tenant-a", snapshots[2].TenantID)
+	assert.Equal(t, "park_a_vehicle", snapshots[2].SchemaName)
+	assert.True(t, snapshots[0].CapturedAt.Equal(snapshots[2].CapturedAt), "同一次采集使用相同的采集时间")
+
+	// 超过保留期的快照在采集后删除
+	createSnapshot(t, s, "default", "lib-1", "if-1", 1, 1, time.Now().AddDate(0, 0, -(defaultRetentionDays+1)))
+	result, err = s.Collect(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), result.Purged)
+
+	// 携带租户时只采集该租户的接口
+	result, err = s.Collect(tenant.WithTenant(ctx, tenant.Info{ID: "tenant-a", Code: "park_a"}))
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Captured)
+}
+
+func TestLibraryAndTenantReports(t *testing.T) {
+	s := setupCapacityService(t)
+	ctx := context.Background()
+	tenDaysAgo := time.Now().AddDate(0, 0, -10)
+	createSnapshot(t, s, "default", "lib-1", "if-1", 600, 4000, tenDaysAgo)
+	createSnapshot(t, s, "default", "lib-1", "if-2", 200, 1000, tenDaysAgo)
+	createSnapshot(t, s, "default", "lib-1", "if-1", 1, 1, time.Now().AddDate(0, 0, -60))
+	_, err := s.Collect(ctx)
+	require.NoError(t, err)
+
+	reports, err := s.GetLibraryReports(ctx, 30, "")
+	require.NoError(t, err)
+	require.Len(t, reports, 2)
+	assert.Equal(t, "lib-2", reports[0].LibraryID, "按占用空间倒序")
+
+	population := reports[1]
+	assert.Equal(t, "人口基础库", population.LibraryName)
+	assert.Equal(t, 2, population.InterfaceCount)
+	assert.Equal(t, int64(1200), population.RowCount)
+	assert.Equal(t, int64(10000), population.TotalBytes)
+	assert.Equal(t, int64(400), population.RowGrowth)
+	assert.Equal(t, int64(5000), population.BytesGrowth)
+	assert.InDelta(t, 500, population.BytesPerDay, 1)
+	// 只采集过一次的库不计算日均增长
+	assert.Zero(t, reports[0].BytesPerDay)
+
+	tenants, err := s.GetTenantReports(ctx, 30)
+	require.NoError(t, err)
+	require.Len(t, tenants, 2)
+	assert.Equal(t, "tenant-a", tenants[0].TenantID)
+	assert.Equal(t, "A园区", tenants[0].TenantName)
+	assert.Equal(t, int64(5000), tenants[1].BytesGrowth)
+
+	// 租户只能看到自己的库
+	reports, err = s.GetLibraryReports(tenant.WithTenant(ctx, tenant.Info{ID: "tenant-a", Code: "park_a"}), 30, "")
+	require.NoError(t, err)
+	require.Len(t, reports, 1)
+	assert.Equal(t, "lib-2", reports[0].LibraryID)
+}
+
+func TestLibraryDetail(t *testing.T) {
+	s := setupCapacityService(t)
+	ctx := context.Background()
+	yesterday := time.Now().AddDate(0, 0, -1)
+	createSnapshot(t, s, "default", "lib-1", "if-1", 900, 7000, yesterday)
+	createSnapshot(t, s, "default", "lib-1", "if-2", 200, 2000, yesterday)
+	// 已删除的接口只出现在历史快照中
+	createSnapshot(t, s, "default", "lib-1", "if-removed", 10, 100, yesterday)
+	_, err := s.Collect(ctx)
+	require.NoError(t, err)
+
+	detail, err := s.GetLibraryDetail(ctx, "lib-1", 7)
+	require.NoError(t, err)
+	assert.Equal(t, "人口基础库", detail.LibraryName)
+	require.Len(t, detail.Interfaces, 2)
+	assert.Equal(t, "if-1", detail.Interfaces[0].InterfaceID)
+	assert.Equal(t, "人员", detail.Interfaces[0].InterfaceName)
+	assert.Equal(t, int64(100), detail.Interfaces[0].RowGrowth)
+	assert.Equal(t, int64(2000), detail.Interfaces[0].IndexBytes)
+
+	require.Len(t, detail.Trend, 2)
+	assert.Equal(t, int64(9100), detail.Trend[0].TotalBytes)
+	assert.Equal(t, int64(10000), detail.Trend[1].TotalBytes)
+
+	_, err = s.GetLibraryDetail(ctx, "missing", 7)
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+}
@@ -0,0 +1,351 @@
+/*
+ * @module service/capacity/quota
+ * @description 库和租户配额，限制单个基础库/主题库或整个租户的数据行数、存储空间和同步任务数，在创建同步任务和执行同步前检查，超出时拒绝或告警
+ * @architecture 分层架构 - 业务服务层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow 设置配额 -> 创建任务时检查任务数、执行同步时检查行数和存储 -> 同时检查库配额和所属租户配额 -> 达到上限时记录quota_exceeded事件，处理方式为reject时返回ErrQuotaExceeded
+ * @rules 上限为0表示不限制；行数和存储用量取该库或租户最近一次存储快照，快照之后写入的数据在下次采集后计入；
+ *        任务数统计基础库和主题库的同步任务，租户配额统计租户下全部库；用量达到上限即视为超出
+ * @dependencies datahub-service/service/eventlog, datahub-service/service/meta, datahub-service/service/models, gorm.io/gorm
+ * @refs service/models/library_quota.go, service/capacity/report.go, service/basic_library/sync_task_service.go, service/thematic_library/thematic_sync_service.go
+ */
+
+package capacity
+
+import (
+	"context"
+	"datahub-service/service/eventlog"
+	"datahub-service/service/meta"
+	"datahub-service/service/models"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// 配额项
+const (
+	QuotaItemRows    = "rows"
+	QuotaItemStorage = "storage"
+	QuotaItemTasks   = "tasks"
+)
+
+// 配额错误
+var (
+	ErrQuotaExceeded = errors.New("超出配额")
+	ErrInvalidQuota  = errors.New("配额设置不合法")
+)
+
+// QuotaUsage 配额及当前用量
+type QuotaUsage struct {
+	Quota      models.LibraryQuota `json:"quota"`
+	ScopeName  string              `json:"scope_name"`            // 库或租户名称
+	RowCount   int64               `json:"row_count"`             // 最近一次存储快照的行数
+	TotalBytes int64               `json:"total_bytes"`           // 最近一次存储快照的占用空间
+	TaskCount  int64               `json:"task_count"`            // 同步任务数
+	CapturedAt *time.Time          `json:"captured_at,omitempty"` // 行数和存储用量对应的快照时间，没有快照时为空
+	Exceeded   []string            `json:"exceeded"`              // 已达到上限的配额项：rows, storage, tasks
+}
+
+// quotaScope 配额检查的对象
+type quotaScope struct {
+	libraryType string
+	libraryID   string
+	tenantID    string
+	name        string
+}
+
+// QuotaService 配额服务
+type QuotaService struct {
+	db *gorm.DB
+}
+
+// NewQuotaService 创建配额服务实例
+func NewQuotaService(db *gorm.DB) *QuotaService {
+	return &QuotaService{db: db}
+}
+
+// SetQuota 设置库或租户的配额，已存在时覆盖
+func (s *QuotaService) SetQuota(ctx context.Context, quota *models.LibraryQuota, operator string) (*models.LibraryQuota, error) {
+	if quota.MaxRows < 0 || quota.MaxStorageBytes < 0 || quota.MaxTasks < 0 {
+		return nil, fmt.Errorf("%w: 上限不能为负数", ErrInvalidQuota)
+	}
+	if quota.Enforcement == "" {
+		quota.Enforcement = models.QuotaEnforcementReject
+	}
+	if quota.Enforcement != models.QuotaEnforcementReject && quota.Enforcement != models.QuotaEnforcementWarn {
+		return nil, fmt.Errorf("%w: 不支持的处理方式%s", ErrInvalidQuota, quota.Enforcement)
+	}
+
+	switch quota.ScopeType {
+	case models.QuotaScopeLibrary:
+		scope, err := s.libraryScope(ctx, quota.ScopeID)
+		if err != nil {
+			return nil, err
+		}
+		quota.LibraryType = scope.libraryType
+	case models.QuotaScopeTenant:
+		var count int64
+		if err := s.db.WithContext(ctx).Model(&models.Tenant{}).Where("id = ?", quota.ScopeID).Count(&count).Error; err != nil {
+			return nil, fmt.Errorf("查询租户失败: %w", err)
+		}
+		if count == 0 {
+			return nil, fmt.Errorf("租户不存在: %w", gorm.ErrRecordNotFound)
+		}
+		quota.LibraryType = ""
+	default:
+		return nil, fmt.Errorf("%w: 不支持的配额范围%s", ErrInvalidQuota, quota.ScopeType)
+	}
+
+	var existing models.LibraryQuota
+	err := s.db.WithContext(ctx).Where("scope_type = ? AND scope_id = ?", quota.ScopeType, quota.ScopeID).First(&existing).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		quota.ID = ""
+		quota.CreatedBy = operator
+		quota.UpdatedBy = operator
+		if err := s.db.WithContext(ctx).Create(quota).Error; err != nil {
+			return nil, fmt.Errorf("创建配额失败: %w", err)
+		}
+		return quota, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("查询配额失败: %w", err)
+	}
+
+	existing.LibraryType = quota.LibraryType
+	existing.MaxRows = quota.MaxRows
+	existing.MaxStorageBytes = quota.MaxStorageBytes
+	existing.MaxTasks = quota.MaxTasks
+	existing.Enforcement = quota.Enforcement
+	existing.UpdatedBy = operator
+	if err := s.db.WithContext(ctx).Save(&existing).Error; err != nil {
+		return nil, fmt.Errorf("更新配额失败: %w", err)
+	}
+	return &existing, nil
+}
+
+// DeleteQuota 删除库或租户的配额
+func (s *QuotaService) DeleteQuota(ctx context.Context, scopeType, scopeID string) error {
+	result := s.db.WithContext(ctx).Where("scope_type = ? AND scope_id = ?", scopeType, scopeID).Delete(&models.LibraryQuota{})
+	if result.Error != nil {
+		return fmt.Errorf("删除配额失败: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("配额不存在: %w", gorm.ErrRecordNotFound)
+	}
+	return nil
+}
+
+// GetUsage 获取库或租户的配额和当前用量
+func (s *QuotaService) GetUsage(ctx context.Context, scopeType, scopeID string) (*QuotaUsage, error) {
+	var quota models.LibraryQuota
+	if err := s.db.WithContext(ctx).Where("scope_type = ? AND scope_id = ?", scopeType, scopeID).First(&quota).Error; err != nil {
+		return nil, fmt.Errorf("配额不存在: %w", err)
+	}
+	return s.usage(ctx, quota)
+}
+
+// ListUsage 获取全部配额和当前用量，已达到上限的排在前面
+func (s *QuotaService) ListUsage(ctx context.Context) ([]QuotaUsage, error) {
+	var quotas []models.LibraryQuota
+	if err := s.db.WithContext(ctx).Order("scope_type, created_at").Find(&quotas).Error; err != nil {
+		return nil, fmt.Errorf("查询配额失败: %w", err)
+	}
+
+	usages := make([]QuotaUsage, 0, len(quotas))
+	var exceeded []QuotaUsage
+	for _, quota := range quotas {
+		usage, err := s.usage(ctx, quota)
+		if err != nil {
+			return nil, err
+		}
+		if len(usage.Exceeded) > 0 {
+			exceeded = append(exceeded, *usage)
+			continue
+		}
+		usages = append(usages, *usage)
+	}
+	return append(exceeded, usages...), nil
+}
+
+// CheckTaskCreation 创建同步任务前检查库和所属租户的任务数配额
+func (s *QuotaService) CheckTaskCreation(ctx context.Context, libraryID string) error {
+	return s.check(ctx, libraryID, "创建同步任务", QuotaItemTasks)
+}
+
+// CheckSync 执行同步前检查库和所属租户的行数和存储配额
+func (s *QuotaService) CheckSync(ctx context.Context, libraryID string) error {
+	return s.check(ctx, libraryID, "执行同步", QuotaItemRows, QuotaItemStorage)
+}
+
+// check 检查库配额和所属租户配额中的指定配额项，达到上限时记录事件，处理方式为reject时返回错误
+func (s *QuotaService) check(ctx context.Context, libraryID, action string, items ...string) error {
+	scope, err := s.libraryScope(ctx, libraryID)
+	if err != nil {
+		return err
+	}
+
+	var quotas []models.LibraryQuota
+	if err := s.db.WithContext(ctx).
+		Where("(scope_type = ? AND scope_id = ?) OR (scope_type = ? AND scope_id = ?)",
+			models.QuotaScopeLibrary, scope.libraryID, models.QuotaScopeTenant, scope.tenantID).
+		Find(&quotas).Error; err != nil {
+		return fmt.Errorf("查询配额失败: %w", err)
+	}
+
+	var rejected []string
+	for _, quota := range quotas {
+		usage, err := s.usage(ctx, quota)
+		if err != nil {
+			return err
+		}
+		var reached []string
+		for _, item := range usage.Exceeded {
+			for _, checked := range items {
+				if item == checked {
+					reached = append(reached, describeQuotaItem(usage, item))
+				}
+			}
+		}
+		if len(reached) == 0 {
+			continue
+		}
+
+		message := fmt.Sprintf("%s%s%s", scopeLabel(quota.ScopeType), usage.ScopeName, strings.Join(reached, "，"))
+		s.recordExceeded(ctx, usage, scope, action, message)
+		if quota.Enforcement == models.QuotaEnforcementReject {
+			rejected = append(rejected, message)
+		}
+	}
+	if len(rejected) > 0 {
+		return fmt.Errorf("%w，不能%s: %s", ErrQuotaExceeded, action, strings.Join(rejected, "；"))
+	}
+	return nil
+}
+
+// recordExceeded 记录超出配额的日志和事件
+func (s *QuotaService) recordExceeded(ctx context.Context, usage *QuotaUsage, scope *quotaScope, action, message string) {
+	slog.WarnContext(ctx, "超出配额", "scope_type", usage.Quota.ScopeType, "scope_id", usage.Quota.ScopeID,
+		"library_id", scope.libraryID, "action", action, "enforcement", usage.Quota.Enforcement, "detail", message)
+	eventlog.Record(ctx, eventlog.Event{
+		Type:       eventlog.EventQuotaExceeded,
+		Level:      models.EventLevelWarn,
+		Message:    message,
+		ObjectType: usage.Quota.ScopeType,
+		ObjectID:   usage.Quota.ScopeID,
+		Attributes: map[string]interface{}{
+			"library_id":  scope.libraryID,
+			"action":      action,
+			"enforcement": usage.Quota.Enforcement,
+			"exceeded":    usage.Exceeded,
+			"row_count":   usage.RowCount,
+			"total_bytes": usage.TotalBytes,
+			"task_count":  usage.TaskCount,
+		},
+	})
+}
+
+// usage 计算配额的当前用量
+func (s *QuotaService) usage(ctx context.Context, quota models.LibraryQuota) (*QuotaUsage, error) {
+	usage := &QuotaUsage{Quota: quota, Exceeded: []string{}}
+	db := s.db.WithContext(ctx)
+
+	column := "library_id"
+	if quota.ScopeType == models.QuotaScopeTenant {
+		column = "tenant_id"
+		var t models.Tenant
+		if err := db.Select("id", "name").First(&t, "id = ?", quota.ScopeID).Error; err == nil {
+			usage.ScopeName = t.Name
+		}
+	} else if scope, err := s.libraryScope(ctx, quota.ScopeID); err == nil {
+		usage.ScopeName = scope.name
+	}
+
+	// 行数和存储取最近一次采集，同一次采集的快照使用相同的采集时间
+	var latest models.StorageSnapshot
+	err := db.Select("captured_at").Where(column+" = ?", quota.ScopeID).Order("captured_at DESC").First(&latest).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("查询存储快照失败: %w", err)
+	}
+	if err == nil {
+		var totals struct {
+			RowCount   int64
+			TotalBytes int64
+		}
+		if err := db.Model(&models.StorageSnapshot{}).
+			Select("COALESCE(SUM(row_count), 0) AS row_count, COALESCE(SUM(total_bytes), 0) AS total_bytes").
+			Where(column+" = ? AND captured_at = ?", quota.ScopeID, latest.CapturedAt).
+			Scan(&totals).Error; err != nil {
+			return nil, fmt.Errorf("汇总存储快照失败: %w", err)
+		}
+		usage.RowCount = totals.RowCount
+		usage.TotalBytes = totals.TotalBytes
+		usage.CapturedAt = &latest.CapturedAt
+	}
+
+	// 任务数统计基础库和主题库的同步任务
+	var basicTasks, thematicTasks int64
+	thematicColumn := "thematic_library_id"
+	if quota.ScopeType == models.QuotaScopeTenant {
+		thematicColumn = "tenant_id"
+	}
+	if err := db.Model(&models.SyncTask{}).Where(column+" = ?", quota.ScopeID).Count(&basicTasks).Error; err != nil {
+		return nil, fmt.Errorf("统计同步任务数失败: %w", err)
+	}
+	if err := db.Model(&models.ThematicSyncTask{}).Where(thematicColumn+" = ?", quota.ScopeID).Count(&thematicTasks).Error; err != nil {
+		return nil, fmt.Errorf("统计主题同步任务数失败: %w", err)
+	}
+	usage.TaskCount = basicTasks + thematicTasks
+
+	if quota.MaxRows > 0 && usage.RowCount >= quota.MaxRows {
+		usage.Exceeded = append(usage.Exceeded, QuotaItemRows)
+	}
+	if quota.MaxStorageBytes > 0 && usage.TotalBytes >= quota.MaxStorageBytes {
+		usage.Exceeded = append(usage.Exceeded, QuotaItemStorage)
+	}
+	if quota.MaxTasks > 0 && usage.TaskCount >= quota.MaxTasks {
+		usage.Exceeded = append(usage.Exceeded, QuotaItemTasks)
+	}
+	return usage, nil
+}
+
+// libraryScope 查询基础库或主题库的库类型、租户和名称
+func (s *QuotaService) libraryScope(ctx context.Context, libraryID string) (*quotaScope, error) {
+	var basic models.BasicLibrary
+	err := s.db.WithContext(ctx).Select("id", "tenant_id", "name_zh").First(&basic, "id = ?", libraryID).Error
+	if err == nil {
+		return &quotaScope{libraryType: meta.LibraryTypeBasic, libraryID: basic.ID, tenantID: basic.TenantID, name: basic.NameZh}, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("查询基础库失败: %w", err)
+	}
+
+	var thematic models.ThematicLibrary
+	if err := s.db.WithContext(ctx).Select("id", "tenant_id", "name_zh").First(&thematic, "id = ?", libraryID).Error; err != nil {
+		return nil, fmt.Errorf("库不存在: %w", err)
+	}
+	return &quotaScope{libraryType: meta.LibraryTypeThematic, libraryID: thematic.ID, tenantID: thematic.TenantID, name: thematic.NameZh}, nil
+}
+
+// describeQuotaItem 描述已达到上限的配额项
+func describeQuotaItem(usage *QuotaUsage, item string) string {
+	switch item {
+	case QuotaItemRows:
+		return fmt.Sprintf("数据行数%d已达到上限%d", usage.RowCount, usage.Quota.MaxRows)
+	case QuotaItemStorage:
+		return fmt.Sprintf("存储空间%d字节已达到上限%d字节", usage.TotalBytes, usage.Quota.MaxStorageBytes)
+	default:
+		return fmt.Sprintf("同步任务数%d已达到上限%d", usage.TaskCount, usage.Quota.MaxTasks)
+	}
+}
+
+// scopeLabel 配额范围的中文名称
+func scopeLabel(scopeType string) string {
+	if scopeType == models.QuotaScopeTenant {
+		return "租户"
+	}
+	return "库"
+}
@@ -0,0 +1,114 @@
+/*
+ * @module service/capacity/quota_test
+ * @description 配额测试，覆盖配额设置校验、按最近一次快照和任务数计算用量、创建任务和执行同步时按库和租户配额拒绝或告警
+ * @architecture 测试层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow 准备sqlite中的库、快照和同步任务 -> 设置配额 -> 计算用量和检查 -> 验证错误和quota_exceeded事件
+ * @rules 使用内存sqlite，复用存储容量测试的库和租户数据
+ * @dependencies gorm.io/driver/sqlite, stretchr/testify
+ * @refs quota.go
+ */
+
+package capacity
+
+import (
+	"context"
+	"datahub-service/service/eventlog"
+	"datahub-service/service/models"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func setupQuotaService(t *testing.T) (*gorm.DB, *QuotaService) {
+	s := setupCapacityService(t)
+	db := s.db
+	require.NoError(t, db.AutoMigrate(&models.LibraryQuota{}, &models.SyncTask{}, &models.ThematicSyncTask{}, &models.ApplicationEvent{}))
+	eventlog.SetDefault(eventlog.NewService(db))
+	t.Cleanup(func() { eventlog.SetDefault(nil) })
+
+	// 旧快照不计入用量，只取最近一次采集
+	now := time.Now()
+	createSnapshot(t, s, "default", "lib-1", "if-1", 100, 1000, now.AddDate(0, 0, -1))
+	createSnapshot(t, s, "default", "lib-1", "if-1", 800, 6000, now)
+	createSnapshot(t, s, "default", "lib-1", "if-2", 200, 2000, now)
+	createSnapshot(t, s, "tenant-a", "lib-2", "if-4", 5000, 50000, now)
+	for _, task := range []models.SyncTask{
+		{ID: "task-1", TenantID: "default", LibraryType: "basic_library", LibraryID: "lib-1", DataSourceID: "ds-1", TaskType: "batch_sync"},
+		{ID: "task-2", TenantID: "default", LibraryType: "basic_library", LibraryID: "lib-1", DataSourceID: "ds-1", TaskType: "batch_sync"},
+		{ID: "task-3", TenantID: "tenant-a", LibraryType: "basic_library", LibraryID: "lib-2", DataSourceID: "ds-2", TaskType: "batch_sync"},
+	} {
+		require.NoError(t, db.Create(&task).Error)
+	}
+	return db, NewQuotaService(db)
+}
+
+func quotaEvents(t *testing.T, db *gorm.DB) int64 {
+	var count int64
+	require.NoError(t, db.Model(&models.ApplicationEvent{}).Where("event_type = ?", eventlog.EventQuotaExceeded).Count(&count).Error)
+	return count
+}
+
+func TestSetQuota_Validation(t *testing.T) {
+	_, svc := setupQuotaService(t)
+	ctx := context.Background()
+
+	_, err := svc.SetQuota(ctx, &models.LibraryQuota{ScopeType: "team", ScopeID: "lib-1"}, "admin")
+	assert.ErrorIs(t, err, ErrInvalidQuota)
+	_, err = svc.SetQuota(ctx, &models.LibraryQuota{ScopeType: models.QuotaScopeLibrary, ScopeID: "lib-1", MaxRows: -1}, "admin")
+	assert.ErrorIs(t, err, ErrInvalidQuota)
+	_, err = svc.SetQuota(ctx, &models.LibraryQuota{ScopeType: models.QuotaScopeLibrary, ScopeID: "lib-x"}, "admin")
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+
+	quota, err := svc.SetQuota(ctx, &models.LibraryQuota{ScopeType: models.QuotaScopeLibrary, ScopeID: "lib-1", MaxTasks: 5}, "admin")
+	require.NoError(t, err)
+	assert.Equal(t, "basic_library", quota.LibraryType)
+	assert.Equal(t, models.QuotaEnforcementReject, quota.Enforcement)
+
+	updated, err := svc.SetQuota(ctx, &models.LibraryQuota{ScopeType: models.QuotaScopeLibrary, ScopeID: "lib-1", MaxTasks: 10, Enforcement: "warn"}, "ops")
+	require.NoError(t, err)
+	assert.Equal(t, quota.ID, updated.ID, "同一库只有一条配额")
+	assert.Equal(t, int64(10), updated.MaxTasks)
+
+	require.NoError(t, svc.DeleteQuota(ctx, models.QuotaScopeLibrary, "lib-1"))
+	assert.ErrorIs(t, svc.DeleteQuota(ctx, models.QuotaScopeLibrary, "lib-1"), gorm.ErrRecordNotFound)
+}
+
+func TestQuotaUsageAndChecks(t *testing.T) {
+	db, svc := setupQuotaService(t)
+	ctx := context.Background()
+
+	_, err := svc.SetQuota(ctx, &models.LibraryQuota{ScopeType: models.QuotaScopeLibrary, ScopeID: "lib-1", MaxRows: 1000, MaxTasks: 3}, "admin")
+	require.NoError(t, err)
+	usage, err := svc.GetUsage(ctx, models.QuotaScopeLibrary, "lib-1")
+	require.NoError(t, err)
+	assert.Equal(t, "人口基础库", usage.ScopeName)
+	assert.Equal(t, int64(1000), usage.RowCount)
+	assert.Equal(t, int64(8000), usage.TotalBytes)
+	assert.Equal(t, int64(2), usage.TaskCount)
+	assert.Equal(t, []string{QuotaItemRows}, usage.Exceeded)
+
+	// 行数达到上限时拒绝同步，任务数未达上限时可以创建任务
+	assert.NoError(t, svc.CheckTaskCreation(ctx, "lib-1"))
+	err = svc.CheckSync(ctx, "lib-1")
+	assert.ErrorIs(t, err, ErrQuotaExceeded)
+	assert.Contains(t, err.Error(), "数据行数1000已达到上限1000")
+	assert.Equal(t, int64(1), quotaEvents(t, db))
+
+	// 租户配额告警时只记录事件
+	_, err = svc.SetQuota(ctx, &models.LibraryQuota{ScopeType: models.QuotaScopeTenant, ScopeID: "tenant-a", MaxTasks: 1, Enforcement: "warn"}, "admin")
+	require.NoError(t, err)
+	assert.NoError(t, svc.CheckTaskCreation(ctx, "lib-2"))
+	assert.NoError(t, svc.CheckSync(ctx, "lib-2"), "只检查存储和行数，租户未设置这两项上限")
+	assert.Equal(t, int64(2), quotaEvents(t, db))
+
+	list, err := svc.ListUsage(ctx)
+	require.NoError(t, err)
+	require.Len(t, list, 2)
+	assert.Equal(t, "A园区", list[1].ScopeName)
+	assert.Equal(t, int64(5000), list[1].RowCount)
+	assert.Equal(t, []string{QuotaItemTasks}, list[1].Exceeded)
+}
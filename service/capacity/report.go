@@ -0,0 +1,339 @@
+/*
+ * @module service/capacity/report
+ * @description 存储容量报表，按库、租户汇总接口表的行数和占用空间，计算统计期内的增长量和日均增长，按库查看各接口明细和每日趋势
+ * @architecture 分层架构 - 业务服务层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow 查询统计期内的快照 -> 按库和采集时间汇总 -> 取统计期内最早和最新一次采集计算增长 -> 按租户再汇总
+ * @rules 当前值取统计期内最新一次采集；增长量为最新一次减最早一次，采集时间跨度不足一天时不计算日均增长；趋势按天取当天最后一次采集；快照带租户字段，查询时自动按请求租户过滤
+ * @dependencies datahub-service/service/meta, datahub-service/service/models, gorm.io/gorm
+ * @refs service/capacity/service.go, api/controllers/capacity_controller.go
+ */
+
+package capacity
+
+import (
+	"context"
+	"datahub-service/service/meta"
+	"datahub-service/service/models"
+	"fmt"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	defaultReportDays = 30  // 默认统计天数
+	maxReportDays     = 365 // 最大统计天数
+)
+
+// CapacityTotals 行数和占用空间及统计期内的增长
+type CapacityTotals struct {
+	RowCount    int64   `json:"row_count"`
+	TotalBytes  int64   `json:"total_bytes"`
+	RowGrowth   int64   `json:"row_growth"`    // 统计期内行数增长
+	BytesGrowth int64   `json:"bytes_growth"`  // 统计期内占用空间增长
+	BytesPerDay float64 `json:"bytes_per_day"` // 日均空间增长
+}
+
+// LibraryCapacity 库的存储容量
+type LibraryCapacity struct {
+	TenantID       string `json:"tenant_id"`
+	LibraryType    string `json:"library_type" example:"basic_library"`
+	LibraryID      string `json:"library_id"`
+	LibraryName    string `json:"library_name"`
+	InterfaceCount int    `json:"interface_count"`
+	CapacityTotals
+	FirstCapturedAt time.Time `json:"first_captured_at"`
+	LastCapturedAt  time.Time `json:"last_captured_at"`
+}
+
+// TenantCapacity 租户的存储容量
+type TenantCapacity struct {
+	TenantID       string `json:"tenant_id"`
+	TenantName     string `json:"tenant_name"`
+	LibraryCount   int    `json:"library_count"`
+	InterfaceCount int    `json:"interface_count"`
+	CapacityTotals
+}
+
+// InterfaceCapacity 接口表的存储容量
+type InterfaceCapacity struct {
+	InterfaceID   string `json:"interface_id"`
+	InterfaceName string `json:"interface_name"`
+	SchemaName    string `json:"schema_name"`
+	TableName     string `json:"table_name"`
+	TableBytes    int64  `json:"table_bytes"`
+	IndexBytes    int64  `json:"index_bytes"`
+	CapacityTotals
+}
+
+// CapacityTrendPoint 每日容量
+type CapacityTrendPoint struct {
+	Date       string `json:"date" example:"2024-05-01"`
+	RowCount   int64  `json:"row_count"`
+	TotalBytes int64  `json:"total_bytes"`
+}
+
+// LibraryCapacityDetail 库的存储容量明细
+type LibraryCapacityDetail struct {
+	LibraryCapacity
+	Interfaces []InterfaceCapacity  `json:"interfaces"`
+	Trend      []CapacityTrendPoint `json:"trend"`
+}
+
+// captureTotals 一次采集的汇总
+type captureTotals struct {
+	at         time.Time
+	interfaces int
+	rows       int64
+	bytes      int64
+}
+
+// GetLibraryReports 获取各库近days天的存储容量，按占用空间倒序，libraryType为空表示基础库和主题库
+func (s *Service) GetLibraryReports(ctx context.Context, days int, libraryType string) ([]LibraryCapacity, error) {
+	start, _ := reportPeriod(days)
+	query := s.db.WithContext(ctx).Where("captured_at >= ?", start)
+	if libraryType != "" {
+		query = query.Where("library_type = ?", libraryType)
+	}
+	var snapshots []models.StorageSnapshot
+	if err := query.Order("captured_at ASC").Find(&snapshots).Error; err != nil {
+		return nil, fmt.Errorf("查询存储快照失败: %w", err)
+	}
+
+	grouped := make(map[string][]models.StorageSnapshot)
+	var libraryIDs []string
+	for _, snapshot := range snapshots {
+		if _, ok := grouped[snapshot.LibraryID]; !ok {
+			libraryIDs = append(libraryIDs, snapshot.LibraryID)
+		}
+		grouped[snapshot.LibraryID] = append(grouped[snapshot.LibraryID], snapshot)
+	}
+
+	names := s.libraryNames(ctx, libraryIDs)
+	reports := make([]LibraryCapacity, 0, len(grouped))
+	for _, libraryID := range libraryIDs {
+		report := summarizeLibrary(grouped[libraryID])
+		report.LibraryName = names[libraryID]
+		reports = append(reports, report)
+	}
+	sort.Slice(reports, func(i, j int) bool {
+		if reports[i].TotalBytes != reports[j].TotalBytes {
+			return reports[i].TotalBytes > reports[j].TotalBytes
+		}
+		return reports[i].LibraryName < reports[j].LibraryName
+	})
+	return reports, nil
+}
+
+// GetTenantReports 获取各租户近days天的存储容量，按占用空间倒序
+func (s *Service) GetTenantReports(ctx context.Context, days int) ([]TenantCapacity, error) {
+	libraries, err := s.GetLibraryReports(ctx, days, "")
+	if err != nil {
+		return nil, err
+	}
+
+	byTenant := make(map[string]*TenantCapacity)
+	var tenantIDs []string
+	for _, library := range libraries {
+		report, ok := byTenant[library.TenantID]
+		if !ok {
+			report = &TenantCapacity{TenantID: library.TenantID}
+			byTenant[library.TenantID] = report
+			tenantIDs = append(tenantIDs, library.TenantID)
+		}
+		report.LibraryCount++
+		report.InterfaceCount += library.InterfaceCount
+		report.RowCount += library.RowCount
+		report.TotalBytes += library.TotalBytes
+		report.RowGrowth += library.RowGrowth
+		report.BytesGrowth += library.BytesGrowth
+		report.BytesPerDay += library.BytesPerDay
+	}
+
+	var tenants []models.Tenant
+	if len(tenantIDs) > 0 {
+		if err := s.db.WithContext(ctx).Select("id", "name").Where("id IN ?", tenantIDs).Find(&tenants).Error; err != nil {
+			return nil, fmt.Errorf("查询租户失败: %w", err)
+		}
+	}
+	for _, t := range tenants {
+		byTenant[t.ID].TenantName = t.Name
+	}
+
+	reports := make([]TenantCapacity, 0, len(byTenant))
+	for _, tenantID := range tenantIDs {
+		reports = append(reports, *byTenant[tenantID])
+	}
+	sort.Slice(reports, func(i, j int) bool {
+		if reports[i].TotalBytes != reports[j].TotalBytes {
+			return reports[i].TotalBytes > reports[j].TotalBytes
+		}
+		return reports[i].TenantID < reports[j].TenantID
+	})
+	return reports, nil
+}
+
+// GetLibraryDetail 获取库近days天的存储容量明细，包括各接口的容量和每日趋势
+func (s *Service) GetLibraryDetail(ctx context.Context, libraryID string, days int) (*LibraryCapacityDetail, error) {
+	start, now := reportPeriod(days)
+	var snapshots []models.StorageSnapshot
+	err := s.db.WithContext(ctx).
+		Where("library_id = ? AND captured_at >= ?", libraryID, start).
+		Order("captured_at ASC").
+		Find(&snapshots).Error
+	if err != nil {
+		return nil, fmt.Errorf("查询存储快照失败: %w", err)
+	}
+	if len(snapshots) == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	detail := &LibraryCapacityDetail{
+		LibraryCapacity: summarizeLibrary(snapshots),
+		Interfaces:      []InterfaceCapacity{},
+		Trend:           []CapacityTrendPoint{},
+	}
+	detail.LibraryName = s.libraryNames(ctx, []string{libraryID})[libraryID]
+
+	// 各接口取统计期内最早和最新的快照
+	first := make(map[string]models.StorageSnapshot)
+	last := make(map[string]models.StorageSnapshot)
+	var interfaceIDs []string
+	for _, snapshot := range snapshots {
+		if _, ok := first[snapshot.InterfaceID]; !ok {
+			first[snapshot.InterfaceID] = snapshot
+			interfaceIDs = append(interfaceIDs, snapshot.InterfaceID)
+		}
+		last[snapshot.InterfaceID] = snapshot
+	}
+	interfaceNames := s.interfaceNames(ctx, detail.LibraryType, interfaceIDs)
+	for _, interfaceID := range interfaceIDs {
+		latest := last[interfaceID]
+		// 已删除或不再建表的接口不出现在最近一次采集中
+		if !latest.CapturedAt.Equal(detail.LastCapturedAt) {
+			continue
+		}
+		earliest := first[interfaceID]
+		detail.Interfaces = append(detail.Interfaces, InterfaceCapacity{
+			InterfaceID:    interfaceID,
+			InterfaceName:  interfaceNames[interfaceID],
+			SchemaName:     latest.SchemaName,
+			TableName:      latest.TableName,
+			TableBytes:     latest.TableBytes,
+			IndexBytes:     latest.IndexBytes,
+			CapacityTotals: growthTotals(earliest.CapturedAt, earliest.RowCount, earliest.TotalBytes, latest.CapturedAt, latest.RowCount, latest.TotalBytes),
+		})
+	}
+	sort.Slice(detail.Interfaces, func(i, j int) bool {
+		return detail.Interfaces[i].TotalBytes > detail.Interfaces[j].TotalBytes
+	})
+
+	// 每日趋势取当天最后一次采集
+	daily := make(map[string]captureTotals)
+	for _, capture := range captures(snapshots) {
+		daily[capture.at.In(now.Location()).Format("2006-01-02")] = capture
+	}
+	for day := start; !day.After(now); day = day.AddDate(0, 0, 1) {
+		date := day.Format("2006-01-02")
+		if capture, ok := daily[date]; ok {
+			detail.Trend = append(detail.Trend, CapacityTrendPoint{Date: date, RowCount: capture.rows, TotalBytes: capture.bytes})
+		}
+	}
+	return detail, nil
+}
+
+// summarizeLibrary 按采集时间汇总一个库的快照，snapshots需按采集时间升序
+func summarizeLibrary(snapshots []models.StorageSnapshot) LibraryCapacity {
+	all := captures(snapshots)
+	earliest, latest := all[0], all[len(all)-1]
+	return LibraryCapacity{
+		TenantID:        snapshots[len(snapshots)-1].TenantID,
+		LibraryType:     snapshots[0].LibraryType,
+		LibraryID:       snapshots[0].LibraryID,
+		InterfaceCount:  latest.interfaces,
+		CapacityTotals:  growthTotals(earliest.at, earliest.rows, earliest.bytes, latest.at, latest.rows, latest.bytes),
+		FirstCapturedAt: earliest.at,
+		LastCapturedAt:  latest.at,
+	}
+}
+
+// captures 按采集时间汇总快照，snapshots需按采集时间升序
+func captures(snapshots []models.StorageSnapshot) []captureTotals {
+	var result []captureTotals
+	for _, snapshot := range snapshots {
+		if len(result) == 0 || !result[len(result)-1].at.Equal(snapshot.CapturedAt) {
+			result = append(result, captureTotals{at: snapshot.CapturedAt})
+		}
+		current := &result[len(result)-1]
+		current.interfaces++
+		current.rows += snapshot.RowCount
+		current.bytes += snapshot.TotalBytes
+	}
+	return result
+}
+
+// growthTotals 计算当前值、增长量和日均增长
+func growthTotals(firstAt time.Time, firstRows, firstBytes int64, lastAt time.Time, lastRows, lastBytes int64) CapacityTotals {
+	totals := CapacityTotals{
+		RowCount:    lastRows,
+		TotalBytes:  lastBytes,
+		RowGrowth:   lastRows - firstRows,
+		BytesGrowth: lastBytes - firstBytes,
+	}
+	if span := lastAt.Sub(firstAt).Hours() / 24; span >= 1 {
+		totals.BytesPerDay = float64(totals.BytesGrowth) / span
+	}
+	return totals
+}
+
+// reportPeriod 计算统计期的开始时间（当天零点往前days-1天）和结束时间
+func reportPeriod(days int) (time.Time, time.Time) {
+	if days <= 0 {
+		days = defaultReportDays
+	}
+	if days > maxReportDays {
+		days = maxReportDays
+	}
+	now := time.Now()
+	start := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location()).AddDate(0, 0, -(days - 1))
+	return start, now
+}
+
+// libraryNames 查询基础库和主题库的中文名称
+func (s *Service) libraryNames(ctx context.Context, libraryIDs []string) map[string]string {
+	names := make(map[string]string, len(libraryIDs))
+	if len(libraryIDs) == 0 {
+		return names
+	}
+	var basic []models.BasicLibrary
+	s.db.WithContext(ctx).Select("id", "name_zh").Where("id IN ?", libraryIDs).Find(&basic)
+	for _, library := range basic {
+		names[library.ID] = library.NameZh
+	}
+	var thematic []models.ThematicLibrary
+	s.db.WithContext(ctx).Select("id", "name_zh").Where("id IN ?", libraryIDs).Find(&thematic)
+	for _, library := range thematic {
+		names[library.ID] = library.NameZh
+	}
+	return names
+}
+
+// interfaceNames 查询接口的中文名称
+func (s *Service) interfaceNames(ctx context.Context, libraryType string, interfaceIDs []string) map[string]string {
+	names := make(map[string]string, len(interfaceIDs))
+	var rows []struct {
+		ID     string
+		NameZh string
+	}
+	model := interface{}(&models.DataInterface{})
+	if libraryType == meta.LibraryTypeThematic {
+		model = &models.ThematicInterface{}
+	}
+	s.db.WithContext(ctx).Model(model).Select("id", "name_zh").Where("id IN ?", interfaceIDs).Scan(&rows)
+	for _, row := range rows {
+		names[row.ID] = row.NameZh
+	}
+	return names
+}
@@ -0,0 +1,252 @@
+/*
+ * @module service/capacity/service
+ * @description 存储容量统计服务，定期采集基础库和主题库每个接口表的行数和占用空间快照，并清理超过保留期的快照
+ * @architecture 分层架构 - 业务服务层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow 定时触发/手动触发 -> 查询已建表的接口 -> 探测表大小 -> 写入快照(同一次采集使用相同的采集时间) -> 删除超过保留期的快照
+ * @rules 表不存在的接口跳过不报错；单个接口探测失败不影响其他接口；快照的租户取接口所属库的租户；多实例部署时通过分布式锁只由一个实例采集
+ * @dependencies datahub-service/service/distributed_lock, datahub-service/service/models, datahub-service/service/tenant, gorm.io/gorm, github.com/robfig/cron/v3
+ * @refs service/capacity/probe.go, service/capacity/report.go, api/controllers/capacity_controller.go
+ */
+
+package capacity
+
+import (
+	"context"
+	"datahub-service/service/distributed_lock"
+	"datahub-service/service/meta"
+	"datahub-service/service/models"
+	"datahub-service/service/tenant"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	"gorm.io/gorm"
+)
+
+const (
+	// defaultSnapshotSchedule 默认每天凌晨1点半采集，错开备份校验
+	defaultSnapshotSchedule = "0 30 1 * * *"
+	// defaultRetentionDays 快照默认保留天数
+	defaultRetentionDays = 365
+	// snapshotLockKey 多实例部署时采集任务的锁
+	snapshotLockKey = "capacity_snapshot"
+	snapshotLockTTL = 30 * time.Minute
+)
+
+// CollectResult 一次采集的结果
+type CollectResult struct {
+	CapturedAt time.Time `json:"captured_at"`
+	Interfaces int       `json:"interfaces"` // 已建表的接口数
+	Captured   int       `json:"captured"`   // 写入快照的接口数
+	Skipped    int       `json:"skipped"`    // 表不存在或探测失败的接口数
+	TotalBytes int64     `json:"total_bytes"`
+	Purged     int64     `json:"purged"` // 删除的过期快照数
+}
+
+// interfaceTable 待采集的接口表
+type interfaceTable struct {
+	tenantID    string
+	libraryType string
+	libraryID   string
+	interfaceID string
+	schema      string
+	table       string
+}
+
+// Service 存储容量统计服务
+type Service struct {
+	db            *gorm.DB
+	probe         SizeProbe
+	lock          distributed_lock.DistributedLock
+	schedule      string
+	retentionDays int
+	cron          *cron.Cron
+	ctx           context.Context
+	cancel        context.CancelFunc
+	started       bool
+}
+
+// NewService 创建存储容量统计服务实例，采集周期和保留天数可通过CAPACITY_SNAPSHOT_SCHEDULE、CAPACITY_SNAPSHOT_RETENTION_DAYS配置
+func NewService(db *gorm.DB) *Service {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	schedule := os.Getenv("CAPACITY_SNAPSHOT_SCHEDULE")
+	if schedule == "" {
+		schedule = defaultSnapshotSchedule
+	}
+	retentionDays := defaultRetentionDays
+	if value, err := strconv.Atoi(os.Getenv("CAPACITY_SNAPSHOT_RETENTION_DAYS")); err == nil && value > 0 {
+		retentionDays = value
+	}
+
+	return &Service{
+		db:            db,
+		probe:         NewPostgresProbe(db),
+		schedule:      schedule,
+		retentionDays: retentionDays,
+		cron:          cron.New(cron.WithSeconds()),
+		ctx:           ctx,
+		cancel:        cancel,
+	}
+}
+
+// SetDistributedLock 设置分布式锁，多实例部署时避免重复采集
+func (s *Service) SetDistributedLock(lock distributed_lock.DistributedLock) {
+	s.lock = lock
+}
+
+// Collect 采集所有已建表接口的存储快照，请求携带租户时只采集该租户的接口
+func (s *Service) Collect(ctx context.Context) (*CollectResult, error) {
+	tables, err := s.interfaceTables(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	result := &CollectResult{CapturedAt: now, Interfaces: len(tables)}
+	snapshots := make([]models.StorageSnapshot, 0, len(tables))
+	for _, table := range tables {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		size, err := s.probe.Measure(ctx, table.schema, table.table)
+		if err != nil {
+			if !errors.Is(err, ErrTableNotFound) {
+				slog.WarnContext(ctx, "读取接口表大小失败", "interface_id", table.interfaceID, "table", table.schema+"."+table.table, "error", err)
+			}
+			result.Skipped++
+			continue
+		}
+		snapshots = append(snapshots, models.StorageSnapshot{
+			TenantID:    table.tenantID,
+			LibraryType: table.libraryType,
+			LibraryID:   table.libraryID,
+			InterfaceID: table.interfaceID,
+			SchemaName:  table.schema,
+			TableName:   table.table,
+			RowCount:    size.RowCount,
+			TotalBytes:  size.TotalBytes,
+			TableBytes:  size.TableBytes,
+			IndexBytes:  size.IndexBytes,
+			CapturedAt:  now,
+		})
+		result.TotalBytes += size.TotalBytes
+	}
+
+	if len(snapshots) > 0 {
+		if err := s.db.WithContext(ctx).CreateInBatches(&snapshots, 200).Error; err != nil {
+			return nil, fmt.Errorf("保存存储快照失败: %w", err)
+		}
+	}
+	result.Captured = len(snapshots)
+
+	purge := s.db.WithContext(ctx).Where("captured_at < ?", now.AddDate(0, 0, -s.retentionDays)).Delete(&models.StorageSnapshot{})
+	if purge.Error != nil {
+		slog.WarnContext(ctx, "删除过期存储快照失败", "error", purge.Error)
+	}
+	result.Purged = purge.RowsAffected
+	return result, nil
+}
+
+// Start 启动定时采集任务
+func (s *Service) Start() error {
+	if s.started {
+		return fmt.Errorf("存储快照调度器已经启动")
+	}
+
+	_, err := s.cron.AddFunc(s.schedule, func() {
+		if s.lock != nil {
+			locked, err := s.lock.TryLock(s.ctx, snapshotLockKey, snapshotLockTTL)
+			if err != nil || !locked {
+				return
+			}
+			defer func() {
+				if err := s.lock.Unlock(context.Background(), snapshotLockKey); err != nil {
+					slog.Error("释放存储快照锁失败", "error", err)
+				}
+			}()
+		}
+
+		result, err := s.Collect(s.ctx)
+		if err != nil {
+			slog.Error("定时存储快照采集失败", "error", err)
+			return
+		}
+		slog.Info("定时存储快照采集完成", "captured", result.Captured, "skipped", result.Skipped, "total_bytes", result.TotalBytes)
+	})
+	if err != nil {
+		return fmt.Errorf("添加定时任务失败: %w", err)
+	}
+
+	s.cron.Start()
+	s.started = true
+	slog.Info("存储快照调度器启动成功", "schedule", s.schedule, "retention_days", s.retentionDays)
+	return nil
+}
+
+// Stop 停止定时采集任务
+func (s *Service) Stop() {
+	if !s.started {
+		return
+	}
+	s.cancel()
+	s.cron.Stop()
+	s.started = false
+}
+
+// interfaceTables 查询基础库和主题库中已建表的接口
+func (s *Service) interfaceTables(ctx context.Context) ([]interfaceTable, error) {
+	var tables []interfaceTable
+
+	var basicInterfaces []models.DataInterface
+	err := s.db.WithContext(ctx).Select("id", "library_id", "name_en").
+		Scopes(tenant.OwnerScope(ctx, "library_id", "basic_libraries")).
+		Where("is_table_created = ?", true).
+		Preload("BasicLibrary").
+		Find(&basicInterfaces).Error
+	if err != nil {
+		return nil, fmt.Errorf("查询基础库接口失败: %w", err)
+	}
+	for _, iface := range basicInterfaces {
+		if iface.BasicLibrary.ID == "" {
+			continue
+		}
+		tables = append(tables, interfaceTable{
+			tenantID:    iface.BasicLibrary.TenantID,
+			libraryType: meta.LibraryTypeBasic,
+			libraryID:   iface.LibraryID,
+			interfaceID: iface.ID,
+			schema:      iface.BasicLibrary.GetSchemaName(),
+			table:       iface.NameEn,
+		})
+	}
+
+	var thematicInterfaces []models.ThematicInterface
+	err = s.db.WithContext(ctx).Select("id", "library_id", "name_en").
+		Scopes(tenant.OwnerScope(ctx, "library_id", "thematic_libraries")).
+		Where("is_table_created = ?", true).
+		Preload("ThematicLibrary").
+		Find(&thematicInterfaces).Error
+	if err != nil {
+		return nil, fmt.Errorf("查询主题库接口失败: %w", err)
+	}
+	for _, iface := range thematicInterfaces {
+		if iface.ThematicLibrary.ID == "" {
+			continue
+		}
+		tables = append(tables, interfaceTable{
+			tenantID:    iface.ThematicLibrary.TenantID,
+			libraryType: meta.LibraryTypeThematic,
+			libraryID:   iface.LibraryID,
+			interfaceID: iface.ID,
+			schema:      iface.ThematicLibrary.GetSchemaName(),
+			table:       iface.NameEn,
+		})
+	}
+	return tables, nil
+}
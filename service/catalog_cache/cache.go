@@ -0,0 +1,287 @@
+/*
+ * @module service/catalog_cache/cache
+ * @description 目录数据进程内缓存，缓存控制台每次加载都会读取的基础库、接口、主题库、元数据和模板列表，按TTL过期并在相关表写入时失效
+ * @architecture 分层架构 - 业务服务层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow 列表查询 -> Fetch按名称、租户、对象授权可见范围和查询参数查缓存 -> 命中直接返回/未命中调用加载函数查库并写入缓存；gorm写回调 -> 按表名失效依赖该表的缓存项
+ * @rules 缓存键包含租户和受对象授权限制的访问主体，不同租户、不同可见范围的结果互不共享；缓存的值由多个请求共享，调用方只读不改；表写入后writeQuietPeriod内的加载结果不写入缓存，避免事务提交前读到旧数据又被缓存；
+ *        原生SQL写入和其他实例的写入不触发失效，由TTL限制不一致时长；未设置全局缓存时Fetch直接调用加载函数
+ * @dependencies datahub-service/service/metrics, datahub-service/service/tenant, datahub-service/service/rbac, gorm.io/gorm
+ * @refs service/basic_library/service.go, service/thematic_library/service.go, service/governance/template_service.go
+ */
+
+package catalog_cache
+
+import (
+	"context"
+	"datahub-service/service/metrics"
+	"datahub-service/service/rbac"
+	"datahub-service/service/tenant"
+	"fmt"
+	"log/slog"
+	"os"
+	"reflect"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// defaultTTLSeconds 默认缓存时间
+	defaultTTLSeconds = 30
+	// defaultMaxEntries 默认最大缓存项数
+	defaultMaxEntries = 2000
+	// writeQuietPeriod 表写入后的静默期，期间加载的结果不写入缓存
+	writeQuietPeriod = 5 * time.Second
+)
+
+// Key 缓存键
+type Key struct {
+	Name   string        // 列表名称，如basic_libraries
+	Tables []string      // 结果依赖的表，这些表写入时缓存失效
+	Params []interface{} // 查询参数，如分页和过滤条件
+}
+
+// Page 分页列表结果
+type Page[T any] struct {
+	Items []T
+	Total int64
+}
+
+// Stats 缓存统计
+type Stats struct {
+	Enabled    bool   `json:"enabled"`
+	TTLSeconds int    `json:"ttl_seconds"`
+	Entries    int    `json:"entries"`
+	MaxEntries int    `json:"max_entries"`
+	Hits       uint64 `json:"hits"`
+	Misses     uint64 `json:"misses"`
+}
+
+// entry 缓存项
+type entry struct {
+	value     interface{}
+	tables    []string
+	expiresAt time.Time
+}
+
+// Cache 目录数据缓存
+type Cache struct {
+	ttl        time.Duration
+	maxEntries int
+
+	mu         sync.RWMutex
+	entries    map[string]*entry
+	lastWrites map[string]time.Time // 表名 -> 最近写入时间
+
+	hits   atomic.Uint64
+	misses atomic.Uint64
+}
+
+// defaultCache 全局目录数据缓存
+var defaultCache atomic.Pointer[Cache]
+
+// NewCache 创建目录数据缓存，TTL和容量通过CATALOG_CACHE_TTL_SECONDS、CATALOG_CACHE_MAX_ENTRIES配置
+func NewCache() *Cache {
+	return NewCacheWithTTL(time.Duration(envInt("CATALOG_CACHE_TTL_SECONDS", defaultTTLSeconds))*time.Second,
+		envInt("CATALOG_CACHE_MAX_ENTRIES", defaultMaxEntries))
+}
+
+// NewCacheWithTTL 使用指定TTL和容量创建目录数据缓存
+func NewCacheWithTTL(ttl time.Duration, maxEntries int) *Cache {
+	return &Cache{
+		ttl:        ttl,
+		maxEntries: maxEntries,
+		entries:    make(map[string]*entry),
+		lastWrites: make(map[string]time.Time),
+	}
+}
+
+// Enabled 是否启用目录数据缓存，CATALOG_CACHE_ENABLED=false时关闭
+func Enabled() bool {
+	return os.Getenv("CATALOG_CACHE_ENABLED") != "false"
+}
+
+// SetDefault 设置全局目录数据缓存，传入nil关闭缓存
+func SetDefault(c *Cache) {
+	defaultCache.Store(c)
+}
+
+// Default 返回全局目录数据缓存，未设置时返回nil
+func Default() *Cache {
+	return defaultCache.Load()
+}
+
+// Fetch 从全局缓存读取，未命中时调用load加载并写入缓存
+func Fetch[T any](ctx context.Context, key Key, load func() (T, error)) (T, error) {
+	c := defaultCache.Load()
+	if c == nil {
+		return load()
+	}
+
+	cacheKey := c.buildKey(ctx, key)
+	if value, ok := c.get(cacheKey); ok {
+		if typed, ok := value.(T); ok {
+			metrics.ObserveCatalogCache(key.Name, true)
+			return typed, nil
+		}
+	}
+	metrics.ObserveCatalogCache(key.Name, false)
+
+	startedAt := time.Now()
+	value, err := load()
+	if err != nil {
+		return value, err
+	}
+	c.set(cacheKey, key.Tables, value, startedAt)
+	return value, nil
+}
+
+// RegisterGormCallbacks 在数据库连接上注册写回调，表写入后失效依赖该表的缓存
+func (c *Cache) RegisterGormCallbacks(db *gorm.DB) error {
+	if err := db.Callback().Create().After("gorm:create").Register("catalog_cache:create", c.invalidateStatement); err != nil {
+		return err
+	}
+	if err := db.Callback().Update().After("gorm:update").Register("catalog_cache:update", c.invalidateStatement); err != nil {
+		return err
+	}
+	return db.Callback().Delete().After("gorm:delete").Register("catalog_cache:delete", c.invalidateStatement)
+}
+
+// Invalidate 失效依赖指定表的缓存项
+func (c *Cache) Invalidate(tables ...string) {
+	now := time.Now()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, table := range tables {
+		c.lastWrites[table] = now
+	}
+	for cacheKey, e := range c.entries {
+		if dependsOn(e.tables, tables) {
+			delete(c.entries, cacheKey)
+		}
+	}
+}
+
+// Flush 清空缓存
+func (c *Cache) Flush() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	count := len(c.entries)
+	c.entries = make(map[string]*entry)
+	return count
+}
+
+// Stats 返回缓存统计
+func (c *Cache) Stats() Stats {
+	c.mu.RLock()
+	entries := len(c.entries)
+	c.mu.RUnlock()
+	return Stats{
+		Enabled:    true,
+		TTLSeconds: int(c.ttl / time.Second),
+		Entries:    entries,
+		MaxEntries: c.maxEntries,
+		Hits:       c.hits.Load(),
+		Misses:     c.misses.Load(),
+	}
+}
+
+// invalidateStatement 写操作成功后按语句的表名失效缓存
+func (c *Cache) invalidateStatement(tx *gorm.DB) {
+	if tx.Error != nil || tx.Statement.Table == "" {
+		return
+	}
+	c.Invalidate(tx.Statement.Table)
+}
+
+// buildKey 构建缓存键，包含租户和查询参数
+func (c *Cache) buildKey(ctx context.Context, key Key) string {
+	tenantID := ""
+	if info, ok := tenant.FromContext(ctx); ok {
+		tenantID = info.ID
+	}
+	params := make([]interface{}, len(key.Params))
+	for i, param := range key.Params {
+		params[i] = paramValue(param)
+	}
+	return fmt.Sprintf("%s|%s|%s|%#v", key.Name, tenantID, rbac.ScopeKey(ctx), params)
+}
+
+// paramValue 指针参数取其指向的值，避免同一条件因地址不同生成不同的键
+func paramValue(param interface{}) interface{} {
+	value := reflect.ValueOf(param)
+	if value.Kind() != reflect.Pointer {
+		return param
+	}
+	if value.IsNil() {
+		return nil
+	}
+	return value.Elem().Interface()
+}
+
+// get 读取未过期的缓存项
+func (c *Cache) get(cacheKey string) (interface{}, bool) {
+	c.mu.RLock()
+	e, ok := c.entries[cacheKey]
+	c.mu.RUnlock()
+	if !ok || time.Now().After(e.expiresAt) {
+		c.misses.Add(1)
+		return nil, false
+	}
+	c.hits.Add(1)
+	return e.value, true
+}
+
+// set 写入缓存项，加载期间或静默期内依赖的表有写入时不写入
+func (c *Cache) set(cacheKey string, tables []string, value interface{}, startedAt time.Time) {
+	now := time.Now()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, table := range tables {
+		if lastWrite, ok := c.lastWrites[table]; ok && lastWrite.After(startedAt.Add(-writeQuietPeriod)) {
+			return
+		}
+	}
+	if len(c.entries) >= c.maxEntries {
+		c.evictExpired(now)
+		if len(c.entries) >= c.maxEntries {
+			slog.Debug("目录数据缓存已满，跳过写入", "entries", len(c.entries))
+			return
+		}
+	}
+	c.entries[cacheKey] = &entry{value: value, tables: tables, expiresAt: now.Add(c.ttl)}
+}
+
+// evictExpired 删除过期的缓存项，调用方需持有写锁
+func (c *Cache) evictExpired(now time.Time) {
+	for cacheKey, e := range c.entries {
+		if now.After(e.expiresAt) {
+			delete(c.entries, cacheKey)
+		}
+	}
+}
+
+// dependsOn 缓存项是否依赖任一表
+func dependsOn(entryTables, tables []string) bool {
+	for _, table := range tables {
+		for _, entryTable := range entryTables {
+			if entryTable == table {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// envInt 读取正整数环境变量，未设置或无效时使用默认值
+func envInt(name string, defaultValue int) int {
+	if value, err := strconv.Atoi(os.Getenv(name)); err == nil && value > 0 {
+		return value
+	}
+	return defaultValue
+}
@@ -0,0 +1,165 @@
+/*
+ * @module service/catalog_cache/cache_test
+ * @description 目录数据缓存测试，覆盖命中、按表写入失效、写入静默期、租户隔离、TTL过期和未启用时直接加载
+ * @architecture 测试层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow 设置全局缓存 -> 多次Fetch -> 通过gorm写入相关表 -> 验证加载次数
+ * @rules 使用内存sqlite，测试结束后清除全局缓存
+ * @dependencies gorm.io/driver/sqlite, stretchr/testify
+ * @refs cache.go
+ */
+
+package catalog_cache
+
+import (
+	"context"
+	"datahub-service/service/models"
+	"datahub-service/service/tenant"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func setupCache(t *testing.T, ttl time.Duration) (*Cache, *gorm.DB) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&models.BasicLibrary{}, &models.Metadata{}))
+
+	c := NewCacheWithTTL(ttl, 100)
+	require.NoError(t, c.RegisterGormCallbacks(db))
+	SetDefault(c)
+	t.Cleanup(func() { SetDefault(nil) })
+	return c, db
+}
+
+// countingLoader 返回记录加载次数的加载函数
+func countingLoader(db *gorm.DB, loads *int) func() (Page[models.BasicLibrary], error) {
+	return func() (Page[models.BasicLibrary], error) {
+		*loads++
+		var libraries []models.BasicLibrary
+		err := db.Find(&libraries).Error
+		return Page[models.BasicLibrary]{Items: libraries, Total: int64(len(libraries))}, err
+	}
+}
+
+// settle 将表的最近写入时间移出静默期
+func settle(c *Cache, tables ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, table := range tables {
+		if _, ok := c.lastWrites[table]; ok {
+			c.lastWrites[table] = time.Now().Add(-2 * writeQuietPeriod)
+		}
+	}
+}
+
+func TestFetchInvalidatesOnWrite(t *testing.T) {
+	c, db := setupCache(t, time.Minute)
+	ctx := context.Background()
+	key := Key{Name: "basic_libraries", Tables: []string{"basic_libraries"}, Params: []interface{}{1, 10, ""}}
+	loads := 0
+
+	page, err := Fetch(ctx, key, countingLoader(db, &loads))
+	require.NoError(t, err)
+	assert.Empty(t, page.Items)
+	_, err = Fetch(ctx, key, countingLoader(db, &loads))
+	require.NoError(t, err)
+	assert.Equal(t, 1, loads, "第二次读取命中缓存")
+
+	// 不同查询参数使用不同的缓存项
+	_, err = Fetch(ctx, Key{Name: "basic_libraries", Tables: key.Tables, Params: []interface{}{2, 10, ""}}, countingLoader(db, &loads))
+	require.NoError(t, err)
+	assert.Equal(t, 2, loads)
+
+	// 写入依赖的表后缓存失效，静默期内的加载结果不写入缓存
+	require.NoError(t, db.Create(&models.BasicLibrary{ID: "lib-1", NameZh: "人口库", NameEn: "population"}).Error)
+	page, err = Fetch(ctx, key, countingLoader(db, &loads))
+	require.NoError(t, err)
+	assert.Len(t, page.Items, 1)
+	_, err = Fetch(ctx, key, countingLoader(db, &loads))
+	require.NoError(t, err)
+	assert.Equal(t, 4, loads, "静默期内不缓存")
+
+	settle(c, "basic_libraries")
+	_, err = Fetch(ctx, key, countingLoader(db, &loads))
+	require.NoError(t, err)
+	_, err = Fetch(ctx, key, countingLoader(db, &loads))
+	require.NoError(t, err)
+	assert.Equal(t, 5, loads, "静默期过后恢复缓存")
+
+	// 写入无关的表不影响缓存
+	require.NoError(t, db.Create(&models.Metadata{Name: "m", Type: "business"}).Error)
+	_, err = Fetch(ctx, key, countingLoader(db, &loads))
+	require.NoError(t, err)
+	assert.Equal(t, 5, loads)
+
+	// 更新和删除同样失效
+	require.NoError(t, db.Model(&models.BasicLibrary{}).Where("id = ?", "lib-1").Update("name_zh", "人口基础库").Error)
+	page, err = Fetch(ctx, key, countingLoader(db, &loads))
+	require.NoError(t, err)
+	assert.Equal(t, "人口基础库", page.Items[0].NameZh)
+	assert.Equal(t, 6, loads)
+
+	stats := c.Stats()
+	assert.True(t, stats.Enabled)
+	assert.Equal(t, uint64(3), stats.Hits)
+	assert.Zero(t, stats.Entries, "写入后的加载仍在静默期内")
+
+	settle(c, "basic_libraries")
+	_, err = Fetch(ctx, key, countingLoader(db, &loads))
+	require.NoError(t, err)
+	assert.Equal(t, 1, c.Flush())
+}
+
+func TestFetchSeparatesTenantsAndExpires(t *testing.T) {
+	_, db := setupCache(t, 50*time.Millisecond)
+	key := Key{Name: "basic_libraries", Tables: []string{"basic_libraries"}}
+	loads := 0
+	ctxA := tenant.WithTenant(context.Background(), tenant.Info{ID: "tenant-a", Code: "park_a"})
+	ctxB := tenant.WithTenant(context.Background(), tenant.Info{ID: "tenant-b", Code: "park_b"})
+
+	_, err := Fetch(ctxA, key, countingLoader(db, &loads))
+	require.NoError(t, err)
+	_, err = Fetch(ctxB, key, countingLoader(db, &loads))
+	require.NoError(t, err)
+	assert.Equal(t, 2, loads, "不同租户不共享缓存")
+
+	time.Sleep(80 * time.Millisecond)
+	_, err = Fetch(ctxA, key, countingLoader(db, &loads))
+	require.NoError(t, err)
+	assert.Equal(t, 3, loads, "过期后重新加载")
+}
+
+func TestFetchWithoutCache(t *testing.T) {
+	SetDefault(nil)
+	loads := 0
+	builtIn := true
+	for i := 0; i < 2; i++ {
+		_, err := Fetch(context.Background(), Key{Name: "templates", Params: []interface{}{&builtIn}}, func() (int, error) {
+			loads++
+			return loads, nil
+		})
+		require.NoError(t, err)
+	}
+	assert.Equal(t, 2, loads)
+}
+
+func TestBuildKeyDereferencesPointers(t *testing.T) {
+	c := NewCacheWithTTL(time.Minute, 10)
+	first, second := true, true
+	ctx := context.Background()
+	assert.Equal(t,
+		c.buildKey(ctx, Key{Name: "templates", Params: []interface{}{&first}}),
+		c.buildKey(ctx, Key{Name: "templates", Params: []interface{}{&second}}))
+	var none *bool
+	assert.NotEqual(t,
+		c.buildKey(ctx, Key{Name: "templates", Params: []interface{}{&first}}),
+		c.buildKey(ctx, Key{Name: "templates", Params: []interface{}{none}}))
+}
@@ -0,0 +1,500 @@
+/*
+ * @module service/chaos/chaos_service
+ * @description 故障注入服务，在数据源调用、写库、分布式锁和调度环节注入超时、失败、争用和延迟，用于演练告警与自愈流程
+ * @architecture 分层架构 - 业务服务层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow 创建故障(active) -> 各环节调用Inject命中故障 -> 命中统计定期落库审计 -> 停止(stopped) / 到期(expired)
+ * @rules 仅在APP_ENV为非生产环境且CHAOS_ENABLED=true时启用，未启用时Inject直接返回；故障必须设置结束时间且不超过MaxDuration；创建、命中、停止、到期均写入系统日志
+ * @dependencies datahub-service/service/models, gorm.io/gorm
+ * @refs service/chaos/gorm_callback.go, api/controllers/chaos_controller.go
+ */
+
+package chaos
+
+import (
+	"context"
+	"datahub-service/service/models"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/rand"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrChaosDisabled 当前环境未启用故障注入
+	ErrChaosDisabled = errors.New("故障注入未启用，仅在非生产环境且CHAOS_ENABLED=true时可用")
+	// ErrFaultNotActive 故障已停止或已到期
+	ErrFaultNotActive = errors.New("故障注入已停止或已到期")
+	// ErrInjectedFault 注入的故障，可通过errors.Is识别
+	ErrInjectedFault = errors.New("故障注入")
+)
+
+const (
+	// MaxDuration 单个故障的最长生效时间
+	MaxDuration = 24 * time.Hour
+	// MaxDelay 单次注入的最大延迟
+	MaxDelay = 10 * time.Minute
+	// syncInterval 多实例间同步故障配置和落库命中统计的间隔
+	syncInterval = 10 * time.Second
+	// auditObjectType 审计日志对象类型
+	auditObjectType = "fault_injection"
+)
+
+// FaultTypes 支持的故障类型
+var FaultTypes = []string{
+	models.FaultTypeDataSourceTimeout,
+	models.FaultTypeDBWriteFailure,
+	models.FaultTypeLockContention,
+	models.FaultTypeScheduleDelay,
+}
+
+// faultMessages 各故障类型的默认错误信息
+var faultMessages = map[string]string{
+	models.FaultTypeDataSourceTimeout: "数据源请求超时",
+	models.FaultTypeDBWriteFailure:    "写库失败",
+	models.FaultTypeLockContention:    "分布式锁被其他实例持有",
+	models.FaultTypeScheduleDelay:     "调度延迟",
+}
+
+// InjectedError 注入的故障错误
+type InjectedError struct {
+	FaultID   string
+	FaultType string
+	Message   string
+}
+
+// Error 实现error接口
+func (e *InjectedError) Error() string {
+	return fmt.Sprintf("[故障注入:%s] %s", e.FaultType, e.Message)
+}
+
+// Unwrap 支持errors.Is(err, ErrInjectedFault)
+func (e *InjectedError) Unwrap() error {
+	return ErrInjectedFault
+}
+
+// StatusInfo 故障注入运行状态
+type StatusInfo struct {
+	Enabled      bool     `json:"enabled"`
+	Environment  string   `json:"environment"`
+	ActiveFaults int      `json:"active_faults"`
+	FaultTypes   []string `json:"fault_types"`
+}
+
+// hitStat 故障命中统计，定期落库
+type hitStat struct {
+	count   int64
+	lastHit time.Time
+	targets map[string]int64
+}
+
+// Service 故障注入服务
+type Service struct {
+	db          *gorm.DB
+	enabled     bool
+	environment string
+
+	mu     sync.RWMutex
+	faults []models.FaultInjection
+	hits   map[string]*hitStat
+
+	randFloat func() float64
+	stopCh    chan struct{}
+	stopOnce  sync.Once
+}
+
+// defaultService 全局故障注入服务，供各环节的注入点使用
+var defaultService atomic.Pointer[Service]
+
+// NewService 创建故障注入服务，环境由APP_ENV指定，未配置视为生产环境
+func NewService(db *gorm.DB) *Service {
+	environment := strings.TrimSpace(os.Getenv("APP_ENV"))
+	requested := os.Getenv("CHAOS_ENABLED") == "true"
+	enabled := requested && !IsProductionEnv(environment)
+	if requested && !enabled {
+		slog.Warn("生产环境禁止故障注入，已忽略CHAOS_ENABLED", "app_env", environment)
+	}
+	return newService(db, environment, enabled)
+}
+
+func newService(db *gorm.DB, environment string, enabled bool) *Service {
+	return &Service{
+		db:          db,
+		enabled:     enabled,
+		environment: environment,
+		hits:        make(map[string]*hitStat),
+		randFloat:   rand.Float64,
+		stopCh:      make(chan struct{}),
+	}
+}
+
+// IsProductionEnv 是否为生产环境，未配置环境时按生产环境处理
+func IsProductionEnv(environment string) bool {
+	switch strings.ToLower(strings.TrimSpace(environment)) {
+	case "", "prod", "production":
+		return true
+	default:
+		return false
+	}
+}
+
+// SetDefault 设置全局故障注入服务
+func SetDefault(s *Service) {
+	defaultService.Store(s)
+}
+
+// Inject 使用全局服务注入故障，未设置或未启用时返回nil
+func Inject(ctx context.Context, faultType, target string) error {
+	s := defaultService.Load()
+	if s == nil {
+		return nil
+	}
+	return s.Inject(ctx, faultType, target)
+}
+
+// Enabled 是否启用故障注入
+func (s *Service) Enabled() bool {
+	return s.enabled
+}
+
+// Start 加载故障配置并定期同步，多实例部署时其他实例创建的故障在同步间隔内生效
+func (s *Service) Start() {
+	if !s.enabled {
+		return
+	}
+	if err := s.reload(); err != nil {
+		slog.Error("加载故障注入配置失败", "error", err)
+	}
+
+	go func() {
+		ticker := time.NewTicker(syncInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				s.flushHits()
+				if err := s.reload(); err != nil {
+					slog.Error("同步故障注入配置失败", "error", err)
+				}
+			case <-s.stopCh:
+				s.flushHits()
+				return
+			}
+		}
+	}()
+	slog.Warn("故障注入已启用", "app_env", s.environment)
+}
+
+// Stop 停止后台同步并落库剩余命中统计
+func (s *Service) Stop() {
+	s.stopOnce.Do(func() { close(s.stopCh) })
+}
+
+// Status 获取故障注入运行状态
+func (s *Service) Status() StatusInfo {
+	info := StatusInfo{Enabled: s.enabled, Environment: s.environment}
+	info.FaultTypes = FaultTypes
+	now := time.Now()
+	s.mu.RLock()
+	for i := range s.faults {
+		if s.faults[i].IsEffective(now) {
+			info.ActiveFaults++
+		}
+	}
+	s.mu.RUnlock()
+	return info
+}
+
+// CreateFault 创建故障注入并审计
+func (s *Service) CreateFault(fault *models.FaultInjection, operator, operatorIP string) error {
+	if !s.enabled {
+		return ErrChaosDisabled
+	}
+	if _, ok := faultMessages[fault.FaultType]; !ok {
+		return fmt.Errorf("不支持的故障类型: %s", fault.FaultType)
+	}
+	if fault.Probability == 0 {
+		fault.Probability = 1
+	}
+	if fault.Probability < 0 || fault.Probability > 1 {
+		return errors.New("命中概率必须在0到1之间")
+	}
+	if fault.DelayMs < 0 || time.Duration(fault.DelayMs)*time.Millisecond > MaxDelay {
+		return fmt.Errorf("注入延迟必须在0到%d毫秒之间", MaxDelay.Milliseconds())
+	}
+	if fault.FaultType == models.FaultTypeScheduleDelay && fault.DelayMs == 0 {
+		return errors.New("调度延迟故障必须设置注入延迟")
+	}
+	now := time.Now()
+	if !fault.ExpiresAt.After(now) {
+		return errors.New("故障结束时间必须晚于当前时间")
+	}
+	if fault.ExpiresAt.Sub(now) > MaxDuration {
+		return fmt.Errorf("故障生效时间不能超过%s", MaxDuration)
+	}
+
+	fault.ID = ""
+	fault.Status = models.FaultInjectionStatusActive
+	fault.Environment = s.environment
+	fault.CreatedBy = operator
+	fault.HitCount = 0
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(fault).Error; err != nil {
+			return err
+		}
+		return s.audit(tx, "create", fault.ID, operator, operatorIP, models.JSONB{
+			"fault_type":    fault.FaultType,
+			"target":        fault.Target,
+			"probability":   fault.Probability,
+			"delay_ms":      fault.DelayMs,
+			"expires_at":    fault.ExpiresAt,
+			"environment":   fault.Environment,
+			"description":   fault.Description,
+			"error_message": fault.ErrorMessage,
+		})
+	})
+	if err != nil {
+		return err
+	}
+
+	slog.Warn("故障注入已创建", "fault_id", fault.ID, "fault_type", fault.FaultType, "target", fault.Target, "operator", operator)
+	return s.reload()
+}
+
+// ListFaults 获取故障注入列表，status为空时返回全部
+func (s *Service) ListFaults(status string) ([]models.FaultInjection, error) {
+	var faults []models.FaultInjection
+	query := s.db.Model(&models.FaultInjection{})
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	err := query.Order("created_at DESC").Find(&faults).Error
+	return faults, err
+}
+
+// StopFault 停止故障注入并审计
+func (s *Service) StopFault(id, operator, operatorIP string) error {
+	var fault models.FaultInjection
+	if err := s.db.First(&fault, "id = ?", id).Error; err != nil {
+		return err
+	}
+	if fault.Status != models.FaultInjectionStatusActive {
+		return ErrFaultNotActive
+	}
+
+	if _, err := s.stopFaults(s.db.Where("id = ?", id), operator, operatorIP); err != nil {
+		return err
+	}
+	return s.reload()
+}
+
+// StopAllFaults 停止全部生效中的故障，用于中止演练，返回停止的数量
+func (s *Service) StopAllFaults(operator, operatorIP string) (int, error) {
+	count, err := s.stopFaults(s.db, operator, operatorIP)
+	if err != nil {
+		return 0, err
+	}
+	return count, s.reload()
+}
+
+// stopFaults 将条件内的生效故障置为停止并逐条审计
+func (s *Service) stopFaults(scope *gorm.DB, operator, operatorIP string) (int, error) {
+	s.flushHits()
+
+	var faults []models.FaultInjection
+	if err := scope.Where("status = ?", models.FaultInjectionStatusActive).Find(&faults).Error; err != nil {
+		return 0, err
+	}
+
+	now := time.Now()
+	return len(faults), s.db.Transaction(func(tx *gorm.DB) error {
+		for _, fault := range faults {
+			if err := tx.Model(&models.FaultInjection{}).Where("id = ?", fault.ID).Updates(map[string]interface{}{
+				"status":     models.FaultInjectionStatusStopped,
+				"stopped_at": &now,
+				"stopped_by": operator,
+				"updated_at": now,
+			}).Error; err != nil {
+				return err
+			}
+			if err := s.audit(tx, "stop", fault.ID, operator, operatorIP, models.JSONB{
+				"fault_type": fault.FaultType,
+				"target":     fault.Target,
+				"hit_count":  fault.HitCount,
+			}); err != nil {
+				return err
+			}
+			slog.Warn("故障注入已停止", "fault_id", fault.ID, "fault_type", fault.FaultType, "operator", operator)
+		}
+		return nil
+	})
+}
+
+// Inject 在注入点检查并触发故障：命中后按配置延迟，调度延迟故障只延迟不返回错误，其他故障返回InjectedError
+func (s *Service) Inject(ctx context.Context, faultType, target string) error {
+	if !s.enabled {
+		return nil
+	}
+
+	fault := s.match(faultType, target)
+	if fault == nil {
+		return nil
+	}
+	if fault.Probability < 1 && s.randFloat() >= fault.Probability {
+		return nil
+	}
+
+	s.recordHit(fault.ID, target)
+	slog.Warn("故障注入命中", "fault_id", fault.ID, "fault_type", faultType, "target", target, "delay_ms", fault.DelayMs)
+
+	if fault.DelayMs > 0 {
+		if ctx == nil {
+			ctx = context.Background()
+		}
+		timer := time.NewTimer(time.Duration(fault.DelayMs) * time.Millisecond)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		}
+	}
+
+	if faultType == models.FaultTypeScheduleDelay {
+		return nil
+	}
+	message := fault.ErrorMessage
+	if message == "" {
+		message = faultMessages[faultType]
+	}
+	return &InjectedError{FaultID: fault.ID, FaultType: faultType, Message: message}
+}
+
+// match 查找匹配的生效故障，目标为空的故障作用于全部
+func (s *Service) match(faultType, target string) *models.FaultInjection {
+	now := time.Now()
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for i := range s.faults {
+		fault := s.faults[i]
+		if fault.FaultType != faultType || !fault.IsEffective(now) {
+			continue
+		}
+		if fault.Target == "" || fault.Target == target {
+			return &fault
+		}
+	}
+	return nil
+}
+
+// recordHit 记录命中统计
+func (s *Service) recordHit(faultID, target string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stat, ok := s.hits[faultID]
+	if !ok {
+		stat = &hitStat{targets: make(map[string]int64)}
+		s.hits[faultID] = stat
+	}
+	stat.count++
+	stat.lastHit = time.Now()
+	stat.targets[target]++
+}
+
+// flushHits 将命中统计累加到故障记录并写入审计日志
+func (s *Service) flushHits() {
+	s.mu.Lock()
+	hits := s.hits
+	s.hits = make(map[string]*hitStat)
+	s.mu.Unlock()
+
+	for faultID, stat := range hits {
+		lastHit := stat.lastHit
+		err := s.db.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Model(&models.FaultInjection{}).Where("id = ?", faultID).Updates(map[string]interface{}{
+				"hit_count":   gorm.Expr("hit_count + ?", stat.count),
+				"last_hit_at": &lastHit,
+			}).Error; err != nil {
+				return err
+			}
+			targets := make(map[string]interface{}, len(stat.targets))
+			for target, count := range stat.targets {
+				targets[target] = count
+			}
+			return s.audit(tx, "inject", faultID, "system", "", models.JSONB{
+				"hit_count":   stat.count,
+				"last_hit_at": lastHit,
+				"targets":     targets,
+			})
+		})
+		if err != nil {
+			slog.Error("保存故障注入命中统计失败", "fault_id", faultID, "error", err)
+		}
+	}
+}
+
+// reload 从数据库加载生效中的故障，并将已到期的故障置为expired
+func (s *Service) reload() error {
+	now := time.Now()
+
+	var expired []models.FaultInjection
+	if err := s.db.Where("status = ? AND expires_at <= ?", models.FaultInjectionStatusActive, now).
+		Find(&expired).Error; err != nil {
+		return err
+	}
+	for _, fault := range expired {
+		err := s.db.Transaction(func(tx *gorm.DB) error {
+			result := tx.Model(&models.FaultInjection{}).
+				Where("id = ? AND status = ?", fault.ID, models.FaultInjectionStatusActive).
+				Updates(map[string]interface{}{"status": models.FaultInjectionStatusExpired, "updated_at": now})
+			if result.Error != nil || result.RowsAffected == 0 {
+				return result.Error
+			}
+			return s.audit(tx, "expire", fault.ID, "system", "", models.JSONB{
+				"fault_type": fault.FaultType,
+				"target":     fault.Target,
+				"expires_at": fault.ExpiresAt,
+			})
+		})
+		if err != nil {
+			return err
+		}
+		slog.Warn("故障注入已到期", "fault_id", fault.ID, "fault_type", fault.FaultType)
+	}
+
+	var faults []models.FaultInjection
+	if err := s.db.Where("status = ? AND expires_at > ?", models.FaultInjectionStatusActive, now).
+		Find(&faults).Error; err != nil {
+		return err
+	}
+	s.mu.Lock()
+	s.faults = faults
+	s.mu.Unlock()
+	return nil
+}
+
+// audit 写入故障注入审计日志
+func (s *Service) audit(tx *gorm.DB, operation, faultID, operator, operatorIP string, content models.JSONB) error {
+	log := &models.SystemLog{
+		OperationType:    operation,
+		ObjectType:       auditObjectType,
+		ObjectID:         &faultID,
+		OperatorName:     &operator,
+		OperationContent: content,
+		OperationTime:    time.Now(),
+		OperationResult:  "success",
+		CreatedBy:        operator,
+	}
+	if operatorIP != "" {
+		log.OperatorIP = &operatorIP
+	}
+	return tx.Create(log).Error
+}
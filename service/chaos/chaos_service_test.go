@@ -0,0 +1,187 @@
+/*
+ * @module service/chaos/chaos_service_test
+ * @description 故障注入服务测试，覆盖环境开关、目标匹配、调度延迟、写库失败回调、停止、到期和审计
+ * @architecture 测试层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow 初始化内存数据库 -> 创建故障 -> 注入点命中 -> 停止/到期 -> 验证审计日志
+ * @rules 使用SQLite内存数据库，不依赖外部服务
+ * @dependencies github.com/stretchr/testify, gorm.io/driver/sqlite
+ * @refs service/chaos/chaos_service.go, service/chaos/gorm_callback.go
+ */
+
+package chaos
+
+import (
+	"context"
+	"datahub-service/service/models"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// chaosProbe 用于验证写库失败注入的测试表
+type chaosProbe struct {
+	ID   string `gorm:"primary_key"`
+	Name string
+}
+
+func setupChaosService(t *testing.T, enabled bool) *Service {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&models.FaultInjection{}, &models.SystemLog{}, &chaosProbe{}))
+
+	return newService(db, "test", enabled)
+}
+
+func createFault(t *testing.T, s *Service, faultType, target string, delayMs int) *models.FaultInjection {
+	fault := &models.FaultInjection{
+		FaultType: faultType,
+		Target:    target,
+		DelayMs:   delayMs,
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+	require.NoError(t, s.CreateFault(fault, "tester", "127.0.0.1"))
+	return fault
+}
+
+func countAuditLogs(t *testing.T, s *Service, operation string) int64 {
+	var count int64
+	require.NoError(t, s.db.Model(&models.SystemLog{}).
+		Where("object_type = ? AND operation_type = ?", auditObjectType, operation).
+		Count(&count).Error)
+	return count
+}
+
+func TestIsProductionEnv(t *testing.T) {
+	assert.True(t, IsProductionEnv(""))
+	assert.True(t, IsProductionEnv("Production"))
+	assert.True(t, IsProductionEnv("prod"))
+	assert.False(t, IsProductionEnv("staging"))
+	assert.False(t, IsProductionEnv("dev"))
+}
+
+func TestDisabledService(t *testing.T) {
+	s := setupChaosService(t, false)
+
+	err := s.CreateFault(&models.FaultInjection{
+		FaultType: models.FaultTypeDBWriteFailure,
+		ExpiresAt: time.Now().Add(time.Hour),
+	}, "tester", "")
+	assert.ErrorIs(t, err, ErrChaosDisabled)
+	assert.NoError(t, s.Inject(context.Background(), models.FaultTypeDBWriteFailure, "any"))
+}
+
+func TestCreateFaultValidation(t *testing.T) {
+	s := setupChaosService(t, true)
+
+	err := s.CreateFault(&models.FaultInjection{FaultType: "unknown", ExpiresAt: time.Now().Add(time.Hour)}, "tester", "")
+	assert.Error(t, err)
+
+	err = s.CreateFault(&models.FaultInjection{FaultType: models.FaultTypeDBWriteFailure, ExpiresAt: time.Now().Add(MaxDuration + time.Hour)}, "tester", "")
+	assert.Error(t, err, "生效时间不能超过上限")
+
+	err = s.CreateFault(&models.FaultInjection{FaultType: models.FaultTypeScheduleDelay, ExpiresAt: time.Now().Add(time.Hour)}, "tester", "")
+	assert.Error(t, err, "调度延迟必须设置延迟")
+}
+
+func TestInjectMatchesTarget(t *testing.T) {
+	s := setupChaosService(t, true)
+	fault := createFault(t, s, models.FaultTypeDataSourceTimeout, "ds-1", 0)
+	assert.Equal(t, int64(1), countAuditLogs(t, s, "create"))
+
+	err := s.Inject(context.Background(), models.FaultTypeDataSourceTimeout, "ds-1")
+	require.ErrorIs(t, err, ErrInjectedFault)
+	var injected *InjectedError
+	require.ErrorAs(t, err, &injected)
+	assert.Equal(t, fault.ID, injected.FaultID)
+
+	assert.NoError(t, s.Inject(context.Background(), models.FaultTypeDataSourceTimeout, "ds-2"))
+	assert.NoError(t, s.Inject(context.Background(), models.FaultTypeDBWriteFailure, "ds-1"))
+
+	// 命中统计落库并审计
+	s.flushHits()
+	var stored models.FaultInjection
+	require.NoError(t, s.db.First(&stored, "id = ?", fault.ID).Error)
+	assert.Equal(t, int64(1), stored.HitCount)
+	assert.NotNil(t, stored.LastHitAt)
+	assert.Equal(t, int64(1), countAuditLogs(t, s, "inject"))
+}
+
+func TestInjectProbability(t *testing.T) {
+	s := setupChaosService(t, true)
+	fault := &models.FaultInjection{
+		FaultType:   models.FaultTypeLockContention,
+		Probability: 0.3,
+		ExpiresAt:   time.Now().Add(time.Hour),
+	}
+	require.NoError(t, s.CreateFault(fault, "tester", ""))
+
+	s.randFloat = func() float64 { return 0.5 }
+	assert.NoError(t, s.Inject(context.Background(), models.FaultTypeLockContention, "lock"))
+	s.randFloat = func() float64 { return 0.1 }
+	assert.Error(t, s.Inject(context.Background(), models.FaultTypeLockContention, "lock"))
+}
+
+func TestScheduleDelay(t *testing.T) {
+	s := setupChaosService(t, true)
+	createFault(t, s, models.FaultTypeScheduleDelay, "", 20)
+
+	start := time.Now()
+	assert.NoError(t, s.Inject(context.Background(), models.FaultTypeScheduleDelay, "task-1"))
+	assert.GreaterOrEqual(t, time.Since(start), 20*time.Millisecond)
+
+	// 上下文取消时中断延迟
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.ErrorIs(t, s.Inject(ctx, models.FaultTypeScheduleDelay, "task-1"), context.Canceled)
+}
+
+func TestGormWriteFailure(t *testing.T) {
+	s := setupChaosService(t, true)
+	require.NoError(t, s.RegisterGormCallbacks(s.db))
+	createFault(t, s, models.FaultTypeDBWriteFailure, "chaos_probes", 0)
+
+	err := s.db.Create(&chaosProbe{ID: "1", Name: "probe"}).Error
+	assert.ErrorIs(t, err, ErrInjectedFault)
+	var count int64
+	require.NoError(t, s.db.Model(&chaosProbe{}).Count(&count).Error)
+	assert.Equal(t, int64(0), count)
+
+	// 审计日志不受写库故障影响
+	s.flushHits()
+	assert.Equal(t, int64(1), countAuditLogs(t, s, "inject"))
+}
+
+func TestStopAndExpireFaults(t *testing.T) {
+	s := setupChaosService(t, true)
+	first := createFault(t, s, models.FaultTypeDataSourceTimeout, "", 0)
+	second := createFault(t, s, models.FaultTypeDBWriteFailure, "", 0)
+	third := createFault(t, s, models.FaultTypeLockContention, "", 0)
+
+	require.NoError(t, s.StopFault(first.ID, "tester", ""))
+	assert.NoError(t, s.Inject(context.Background(), models.FaultTypeDataSourceTimeout, "ds-1"))
+	assert.ErrorIs(t, s.StopFault(first.ID, "tester", ""), ErrFaultNotActive)
+	assert.Equal(t, int64(1), countAuditLogs(t, s, "stop"))
+
+	// 到期的故障在同步时置为expired
+	require.NoError(t, s.db.Model(&models.FaultInjection{}).Where("id = ?", second.ID).
+		Update("expires_at", time.Now().Add(-time.Minute)).Error)
+	require.NoError(t, s.reload())
+	var stored models.FaultInjection
+	require.NoError(t, s.db.First(&stored, "id = ?", second.ID).Error)
+	assert.Equal(t, models.FaultInjectionStatusExpired, stored.Status)
+	assert.Equal(t, int64(1), countAuditLogs(t, s, "expire"))
+
+	count, err := s.StopAllFaults("tester", "")
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+	assert.NoError(t, s.Inject(context.Background(), models.FaultTypeLockContention, third.ID))
+	assert.Equal(t, 0, s.Status().ActiveFaults)
+}
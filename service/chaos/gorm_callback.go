@@ -0,0 +1,45 @@
+/*
+ * @module service/chaos/gorm_callback
+ * @description 写库失败注入点，通过GORM回调在创建、更新、删除前注入故障
+ * @architecture 分层架构 - 业务服务层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow GORM写操作 -> 回调按表名匹配故障 -> 命中时中止写入并返回注入错误
+ * @rules 故障注入自身的配置表和系统日志表不参与注入，保证审计可写
+ * @dependencies gorm.io/gorm
+ * @refs service/chaos/chaos_service.go, service/init.go
+ */
+
+package chaos
+
+import (
+	"datahub-service/service/models"
+
+	"gorm.io/gorm"
+)
+
+// excludedTables 不参与写库失败注入的表
+var excludedTables = map[string]bool{
+	"fault_injections": true,
+	"system_logs":      true,
+}
+
+// RegisterGormCallbacks 在数据库连接上注册写库失败注入回调，目标为表名
+func (s *Service) RegisterGormCallbacks(db *gorm.DB) error {
+	if err := db.Callback().Create().Before("gorm:create").Register("chaos:create", s.injectWriteFailure); err != nil {
+		return err
+	}
+	if err := db.Callback().Update().Before("gorm:update").Register("chaos:update", s.injectWriteFailure); err != nil {
+		return err
+	}
+	return db.Callback().Delete().Before("gorm:delete").Register("chaos:delete", s.injectWriteFailure)
+}
+
+// injectWriteFailure 写操作前检查写库失败故障
+func (s *Service) injectWriteFailure(tx *gorm.DB) {
+	if tx.Error != nil || excludedTables[tx.Statement.Table] {
+		return
+	}
+	if err := s.Inject(tx.Statement.Context, models.FaultTypeDBWriteFailure, tx.Statement.Table); err != nil {
+		tx.AddError(err)
+	}
+}
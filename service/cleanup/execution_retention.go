@@ -0,0 +1,280 @@
+/*
+ * @module service/cleanup/execution_retention
+ * @description 执行产物保留策略，按保留天数和保留条数分批清理同步任务执行记录、质量检测执行记录和系统操作日志，可在删除前归档到对象存储或本地目录
+ * @architecture 分层架构 - 业务服务层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow 读取保留配置 -> 选出超过保留天数或超出保留条数的一批记录 -> (配置了归档位置时)写入gzip压缩的JSON Lines归档文件 -> 删除该批记录 -> 记录清理指标 -> 直到没有待清理记录
+ * @rules 保留天数和条数为0时不按该维度清理；执行中的记录不清理；按条数保留时同步和质量检测执行记录按任务分组保留最新N条，系统日志整表保留最新N条；
+ *        归档失败时该批记录不删除并结束本表清理，下次定时清理重试；归档位置和请求头支持secretstore://引用
+ * @dependencies datahub-service/service/config, datahub-service/service/metrics, datahub-service/service/secrets, gorm.io/gorm
+ * @refs log_cleanup_service.go, service/config/config_manager.go, service/backup/verifier.go
+ */
+
+package cleanup
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"datahub-service/service/config"
+	"datahub-service/service/metrics"
+	"datahub-service/service/models"
+	"datahub-service/service/secrets"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// retentionBatchSize 每批清理的记录数
+const retentionBatchSize = 1000
+
+// retentionPolicy 单张表的保留策略
+type retentionPolicy struct {
+	table       string // 表名，同时作为指标标签和归档目录
+	model       interface{}
+	timeColumn  string
+	groupColumn string // 按条数保留时的分组列，为空时整表保留最新N条
+	skipRunning bool   // 是否跳过执行中的记录
+	days        int
+	count       int
+	load        func(db *gorm.DB, ids []string) ([]interface{}, error)
+}
+
+// RetentionResult 单张表的清理结果
+type RetentionResult struct {
+	Table    string `json:"table"`
+	Days     int    `json:"days"`
+	Count    int    `json:"count"`
+	Purged   int64  `json:"purged"`
+	Archived int64  `json:"archived"`
+}
+
+// syncExecutionPolicy 基础库同步任务执行记录的保留策略
+func (s *LogCleanupService) syncExecutionPolicy(days int) retentionPolicy {
+	return retentionPolicy{
+		table:       models.SyncTaskExecution{}.TableName(),
+		model:       &models.SyncTaskExecution{},
+		timeColumn:  "created_at",
+		groupColumn: "task_id",
+		skipRunning: true,
+		days:        days,
+		count:       s.configService.GetIntConfig(config.ConfigKeyBasicSyncLogRetentionCount, 0),
+		load:        loadRetentionRows[models.SyncTaskExecution],
+	}
+}
+
+// qualityExecutionPolicy 质量检测任务执行记录的保留策略
+func (s *LogCleanupService) qualityExecutionPolicy() retentionPolicy {
+	return retentionPolicy{
+		table:       models.QualityTaskExecution{}.TableName(),
+		model:       &models.QualityTaskExecution{},
+		timeColumn:  "created_at",
+		groupColumn: "task_id",
+		skipRunning: true,
+		days:        s.configService.GetIntConfig(config.ConfigKeyQualityExecRetentionDays, config.DefaultQualityExecRetentionDays),
+		count:       s.configService.GetIntConfig(config.ConfigKeyQualityExecRetentionCount, 0),
+		load:        loadRetentionRows[models.QualityTaskExecution],
+	}
+}
+
+// systemLogPolicy 系统操作日志的保留策略
+func (s *LogCleanupService) systemLogPolicy() retentionPolicy {
+	return retentionPolicy{
+		table:      "system_logs",
+		model:      &models.SystemLog{},
+		timeColumn: "operation_time",
+		days:       s.configService.GetIntConfig(config.ConfigKeySystemLogRetentionDays, config.DefaultSystemLogRetentionDays),
+		count:      s.configService.GetIntConfig(config.ConfigKeySystemLogRetentionCount, 0),
+		load:       loadRetentionRows[models.SystemLog],
+	}
+}
+
+// applyRetention 按保留策略分批归档并删除记录，记录清理指标
+func (s *LogCleanupService) applyRetention(ctx context.Context, policy retentionPolicy, archiver *executionArchiver) (*RetentionResult, error) {
+	result := &RetentionResult{Table: policy.table, Days: policy.days, Count: policy.count}
+	err := s.pruneTable(ctx, policy, archiver, result)
+	metrics.ObserveRetention(policy.table, result.Purged, result.Archived, err)
+	return result, err
+}
+
+// pruneTable 循环选出一批待清理记录，归档后删除，直到没有待清理记录
+func (s *LogCleanupService) pruneTable(ctx context.Context, policy retentionPolicy, archiver *executionArchiver, result *RetentionResult) error {
+	if policy.days <= 0 && policy.count <= 0 {
+		return nil
+	}
+	db := s.db.WithContext(ctx)
+
+	for batch := 0; ; batch++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		var ids []string
+		if err := retentionCandidates(db, policy).Limit(retentionBatchSize).Pluck("id", &ids).Error; err != nil {
+			return fmt.Errorf("查询待清理记录失败: %w", err)
+		}
+		if len(ids) == 0 {
+			return nil
+		}
+
+		if archiver != nil {
+			rows, err := policy.load(db, ids)
+			if err != nil {
+				return fmt.Errorf("读取待归档记录失败: %w", err)
+			}
+			if err := archiver.Archive(ctx, policy.table, batch, rows); err != nil {
+				return fmt.Errorf("归档记录失败，本批记录未删除: %w", err)
+			}
+			result.Archived += int64(len(rows))
+		}
+
+		deleted := db.Where("id IN ?", ids).Delete(policy.model)
+		if deleted.Error != nil {
+			return fmt.Errorf("删除记录失败: %w", deleted.Error)
+		}
+		result.Purged += deleted.RowsAffected
+		if deleted.RowsAffected == 0 || len(ids) < retentionBatchSize {
+			return nil
+		}
+	}
+}
+
+// retentionCandidates 构造待清理记录的查询：超过保留天数，或在分组内按时间倒序排在保留条数之后
+func retentionCandidates(db *gorm.DB, policy retentionPolicy) *gorm.DB {
+	query := db.Model(policy.model)
+	if policy.skipRunning {
+		query = query.Where("status <> ?", "running")
+	}
+
+	conditions := db.Where("1 = 0")
+	if policy.days > 0 {
+		conditions = conditions.Or(policy.timeColumn+" < ?", time.Now().AddDate(0, 0, -policy.days))
+	}
+	if policy.count > 0 {
+		partition := ""
+		if policy.groupColumn != "" {
+			partition = "PARTITION BY " + policy.groupColumn + " "
+		}
+		ranked := fmt.Sprintf("SELECT id FROM (SELECT id, ROW_NUMBER() OVER (%sORDER BY %s DESC, id DESC) AS rn FROM %s) ranked WHERE rn > ?",
+			partition, policy.timeColumn, policy.table)
+		conditions = conditions.Or("id IN ("+ranked+")", policy.count)
+	}
+	return query.Where(conditions).Order(policy.timeColumn)
+}
+
+// loadRetentionRows 读取待归档的完整记录
+func loadRetentionRows[T any](db *gorm.DB, ids []string) ([]interface{}, error) {
+	var rows []T
+	if err := db.Where("id IN ?", ids).Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	result := make([]interface{}, len(rows))
+	for i := range rows {
+		result[i] = rows[i]
+	}
+	return result, nil
+}
+
+// executionArchiver 清理前把记录写入归档位置
+type executionArchiver struct {
+	location   string            // 归档位置，http(s)地址或本地目录
+	headers    map[string]string // 上传http(s)地址时附带的请求头
+	httpClient *http.Client
+}
+
+// newExecutionArchiver 按配置创建归档器，未配置归档位置时返回nil
+func (s *LogCleanupService) newExecutionArchiver(ctx context.Context) (*executionArchiver, error) {
+	rawLocation, _ := s.configService.GetSystemConfig(config.ConfigKeyExecutionArchiveLocation)
+	if strings.TrimSpace(rawLocation) == "" {
+		return nil, nil
+	}
+	location, err := secrets.Resolve(ctx, strings.TrimSpace(rawLocation))
+	if err != nil {
+		return nil, fmt.Errorf("解析归档位置失败: %w", err)
+	}
+
+	archiver := &executionArchiver{
+		location:   location,
+		headers:    map[string]string{},
+		httpClient: &http.Client{Timeout: 5 * time.Minute},
+	}
+	rawHeaders, _ := s.configService.GetSystemConfig(config.ConfigKeyExecutionArchiveHeaders)
+	if strings.TrimSpace(rawHeaders) == "" {
+		return archiver, nil
+	}
+	var headers map[string]string
+	if err := json.Unmarshal([]byte(rawHeaders), &headers); err != nil {
+		return nil, fmt.Errorf("归档请求头不是合法的JSON对象: %w", err)
+	}
+	for name, value := range headers {
+		resolved, err := secrets.Resolve(ctx, value)
+		if err != nil {
+			return nil, fmt.Errorf("解析归档请求头%s失败: %w", name, err)
+		}
+		archiver.headers[name] = resolved
+	}
+	return archiver, nil
+}
+
+// Archive 把一批记录写成gzip压缩的JSON Lines文件，路径为{表名}/{日期}/{表名}-{时间}-{批次}.jsonl.gz
+func (a *executionArchiver) Archive(ctx context.Context, table string, batch int, rows []interface{}) error {
+	if len(rows) == 0 {
+		return nil
+	}
+
+	var buf bytes.Buffer
+	writer := gzip.NewWriter(&buf)
+	encoder := json.NewEncoder(writer)
+	for _, row := range rows {
+		if err := encoder.Encode(row); err != nil {
+			return fmt.Errorf("序列化记录失败: %w", err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		return fmt.Errorf("压缩归档文件失败: %w", err)
+	}
+
+	now := time.Now().UTC()
+	name := fmt.Sprintf("%s/%s/%s-%s-%04d.jsonl.gz", table, now.Format("20060102"), table, now.Format("150405.000000000"), batch)
+	if strings.HasPrefix(a.location, "http://") || strings.HasPrefix(a.location, "https://") {
+		return a.upload(ctx, strings.TrimSuffix(a.location, "/")+"/"+name, buf.Bytes())
+	}
+
+	path := filepath.Join(strings.TrimPrefix(a.location, "file://"), filepath.FromSlash(name))
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("创建归档目录失败: %w", err)
+	}
+	if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+		return fmt.Errorf("写入归档文件失败: %w", err)
+	}
+	slog.Debug("执行记录已归档", "table", table, "rows", len(rows), "path", path)
+	return nil
+}
+
+// upload 通过PUT上传归档文件，附带配置的请求头
+func (a *executionArchiver) upload(ctx context.Context, url string, data []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, url, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/gzip")
+	for name, value := range a.headers {
+		req.Header.Set(name, value)
+	}
+	resp, err := a.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("上传归档文件返回状态码: %d", resp.StatusCode)
+	}
+	return nil
+}
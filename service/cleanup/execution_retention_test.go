@@ -0,0 +1,131 @@
+/*
+ * @module service/cleanup/execution_retention_test
+ * @description 执行产物保留策略测试，覆盖按任务保留最新N条且不清理执行中记录、按天数清理并归档到本地目录、归档失败时不删除记录
+ * @architecture 测试层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow 准备sqlite中的执行记录和系统日志 -> 通过环境变量设置保留配置 -> 执行保留策略 -> 验证剩余记录和归档文件
+ * @rules 使用内存sqlite，保留配置通过DATAHUB_前缀环境变量覆盖，http归档使用httptest服务
+ * @dependencies gorm.io/driver/sqlite, stretchr/testify
+ * @refs execution_retention.go
+ */
+
+package cleanup
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"datahub-service/service/config"
+	"datahub-service/service/models"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func setupRetentionService(t *testing.T) (*gorm.DB, *LogCleanupService) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&models.SystemConfig{}, &models.SyncTaskExecution{}, &models.SystemLog{}))
+	return db, NewLogCleanupService(db, config.NewConfigService(db))
+}
+
+func createExecution(t *testing.T, db *gorm.DB, id, taskID, status string, age time.Duration) {
+	createdAt := time.Now().Add(-age)
+	require.NoError(t, db.Create(&models.SyncTaskExecution{
+		ID: id, TaskID: taskID, ExecutionType: "scheduled", Status: status, StartTime: createdAt, CreatedAt: createdAt,
+	}).Error)
+}
+
+func remainingIDs(t *testing.T, db *gorm.DB, model interface{}) []string {
+	var ids []string
+	require.NoError(t, db.Model(model).Order("id").Pluck("id", &ids).Error)
+	return ids
+}
+
+func TestApplyRetention_KeepsNewestPerTask(t *testing.T) {
+	t.Setenv("DATAHUB_BASIC_SYNC_LOG_RETENTION_COUNT", "2")
+	db, svc := setupRetentionService(t)
+	createExecution(t, db, "a-1", "task-a", "success", 5*time.Hour)
+	createExecution(t, db, "a-2", "task-a", "running", 4*time.Hour)
+	createExecution(t, db, "a-3", "task-a", "failed", 3*time.Hour)
+	createExecution(t, db, "a-4", "task-a", "success", 2*time.Hour)
+	createExecution(t, db, "a-5", "task-a", "success", time.Hour)
+	createExecution(t, db, "b-1", "task-b", "success", 10*time.Hour)
+
+	result, err := svc.applyRetention(context.Background(), svc.syncExecutionPolicy(0), nil)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), result.Purged)
+	assert.Zero(t, result.Archived)
+	assert.Equal(t, []string{"a-2", "a-4", "a-5", "b-1"}, remainingIDs(t, db, &models.SyncTaskExecution{}), "执行中的记录不清理")
+}
+
+func TestApplyRetention_ArchivesToLocalDirectory(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("DATAHUB_EXECUTION_ARCHIVE_LOCATION", "file://"+dir)
+	t.Setenv("DATAHUB_SYSTEM_LOG_RETENTION_DAYS", "30")
+	db, svc := setupRetentionService(t)
+	for id, age := range map[string]time.Duration{"log-1": 40 * 24 * time.Hour, "log-2": 31 * 24 * time.Hour, "log-3": time.Hour} {
+		require.NoError(t, db.Create(&models.SystemLog{
+			ID: id, OperationType: "update", ObjectType: "data_source", OperationContent: models.JSONB{},
+			OperationTime: time.Now().Add(-age), OperationResult: "success",
+		}).Error)
+	}
+
+	ctx := context.Background()
+	archiver, err := svc.newExecutionArchiver(ctx)
+	require.NoError(t, err)
+	require.NotNil(t, archiver)
+	result, err := svc.applyRetention(ctx, svc.systemLogPolicy(), archiver)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), result.Purged)
+	assert.Equal(t, int64(2), result.Archived)
+	assert.Equal(t, []string{"log-3"}, remainingIDs(t, db, &models.SystemLog{}))
+
+	files, err := filepath.Glob(filepath.Join(dir, "system_logs", "*", "system_logs-*.jsonl.gz"))
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	file, err := os.Open(files[0])
+	require.NoError(t, err)
+	defer file.Close()
+	reader, err := gzip.NewReader(file)
+	require.NoError(t, err)
+	lines := 0
+	for scanner := bufio.NewScanner(reader); scanner.Scan(); {
+		assert.Contains(t, scanner.Text(), `"operation_type":"update"`)
+		lines++
+	}
+	assert.Equal(t, 2, lines)
+}
+
+func TestApplyRetention_ArchiveFailureKeepsRecords(t *testing.T) {
+	var authorization string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+	t.Setenv("DATAHUB_EXECUTION_ARCHIVE_LOCATION", server.URL+"/archive")
+	t.Setenv("DATAHUB_EXECUTION_ARCHIVE_HEADERS", `{"Authorization":"Bearer token-1"}`)
+	db, svc := setupRetentionService(t)
+	createExecution(t, db, "a-1", "task-a", "success", 10*24*time.Hour)
+
+	ctx := context.Background()
+	archiver, err := svc.newExecutionArchiver(ctx)
+	require.NoError(t, err)
+	result, err := svc.applyRetention(ctx, svc.syncExecutionPolicy(7), archiver)
+	assert.Error(t, err)
+	assert.Zero(t, result.Purged)
+	assert.Equal(t, "Bearer token-1", authorization)
+	assert.Equal(t, []string{"a-1"}, remainingIDs(t, db, &models.SyncTaskExecution{}), "归档失败时不删除")
+}
@@ -1,12 +1,12 @@
 /*
  * @module service/cleanup/log_cleanup_service
- * @description 日志清理服务，负责定期清理过期的同步任务执行日志
+ * @description 日志清理服务，负责定期清理过期的同步任务执行日志，并按保留策略清理质量检测执行记录和系统操作日志
  * @architecture 分层架构 - 业务服务层
  * @documentReference ai_docs/basic_library_process_impl.md
- * @stateFlow 定时触发 -> 读取配置 -> 执行清理 -> 记录结果
+ * @stateFlow 定时触发 -> 读取配置 -> (配置了归档位置时)归档 -> 执行清理 -> 记录结果和指标
  * @rules 确保日志清理不影响系统正常运行
  * @dependencies datahub-service/service/config, gorm.io/gorm, github.com/robfig/cron/v3
- * @refs service/config
+ * @refs service/config, execution_retention.go
  */
 
 package cleanup
@@ -17,12 +17,17 @@ import (
 	"datahub-service/service/models"
 	"fmt"
 	"log/slog"
+	"os"
+	"strconv"
 	"time"
 
 	"github.com/robfig/cron/v3"
 	"gorm.io/gorm"
 )
 
+// defaultWebhookDeliveryRetentionDays Webhook投递日志默认保留天数，可通过WEBHOOK_DELIVERY_RETENTION_DAYS配置
+const defaultWebhookDeliveryRetentionDays = 30
+
 // LogCleanupService 日志清理服务
 type LogCleanupService struct {
 	db            *gorm.DB
@@ -59,11 +64,22 @@ func (s *LogCleanupService) CleanupExpiredLogs(ctx context.Context) error {
 		basicRetentionDays = config.DefaultBasicSyncLogRetentionDays
 	}
 
-	basicDeleted, err := s.CleanupBasicSyncLogs(ctx, basicRetentionDays)
-	if err != nil {
-		slog.Error("清理基础库同步日志失败", "error", err)
-	} else {
-		slog.Info("清理基础库同步日志完成", "deleted_count", basicDeleted, "retention_days", basicRetentionDays)
+	// 配置了归档位置时，同步、质量检测执行记录和系统日志在删除前归档；归档器创建失败时跳过这些表的清理，避免未归档的数据被删除
+	archiver, archiverErr := s.newExecutionArchiver(ctx)
+	if archiverErr != nil {
+		slog.Error("创建执行记录归档器失败，跳过执行记录和系统日志清理", "error", archiverErr)
+	}
+
+	var basicDeleted int64
+	if archiverErr == nil {
+		basicResult, err := s.applyRetention(ctx, s.syncExecutionPolicy(basicRetentionDays), archiver)
+		basicDeleted = basicResult.Purged
+		if err != nil {
+			slog.Error("清理基础库同步日志失败", "error", err, "deleted_count", basicDeleted)
+		} else {
+			slog.Info("清理基础库同步日志完成", "deleted_count", basicDeleted, "archived_count", basicResult.Archived,
+				"retention_days", basicRetentionDays, "retention_count", basicResult.Count)
+		}
 	}
 
 	// 2. 清理主题库同步日志
@@ -80,6 +96,45 @@ func (s *LogCleanupService) CleanupExpiredLogs(ctx context.Context) error {
 		slog.Info("清理主题库同步日志完成", "deleted_count", thematicDeleted, "retention_days", thematicRetentionDays)
 	}
 
+	// 3. 清理过期的幂等请求记录
+	if idempotencyDeleted, err := s.CleanupIdempotencyRecords(ctx); err != nil {
+		slog.Error("清理幂等请求记录失败", "error", err)
+	} else {
+		slog.Info("清理幂等请求记录完成", "deleted_count", idempotencyDeleted)
+	}
+
+	// 4. 清理已结束的长时操作，保留期与执行日志一致，执行记录清理后操作的结果链接不再有效
+	operationRetentionDays := max(basicRetentionDays, thematicRetentionDays)
+	if operationDeleted, err := s.CleanupOperations(ctx, operationRetentionDays); err != nil {
+		slog.Error("清理长时操作记录失败", "error", err)
+	} else {
+		slog.Info("清理长时操作记录完成", "deleted_count", operationDeleted, "retention_days", operationRetentionDays)
+	}
+
+	// 5. 清理已结束的Webhook投递日志
+	webhookRetentionDays := defaultWebhookDeliveryRetentionDays
+	if value, err := strconv.Atoi(os.Getenv("WEBHOOK_DELIVERY_RETENTION_DAYS")); err == nil && value > 0 {
+		webhookRetentionDays = value
+	}
+	if webhookDeleted, err := s.CleanupWebhookDeliveries(ctx, webhookRetentionDays); err != nil {
+		slog.Error("清理Webhook投递日志失败", "error", err)
+	} else {
+		slog.Info("清理Webhook投递日志完成", "deleted_count", webhookDeleted, "retention_days", webhookRetentionDays)
+	}
+
+	// 6. 按保留策略清理质量检测执行记录和系统操作日志
+	if archiverErr == nil {
+		for _, policy := range []retentionPolicy{s.qualityExecutionPolicy(), s.systemLogPolicy()} {
+			result, err := s.applyRetention(ctx, policy, archiver)
+			if err != nil {
+				slog.Error("按保留策略清理失败", "table", policy.table, "error", err, "deleted_count", result.Purged)
+				continue
+			}
+			slog.Info("按保留策略清理完成", "table", policy.table, "deleted_count", result.Purged,
+				"archived_count", result.Archived, "retention_days", policy.days, "retention_count", policy.count)
+		}
+	}
+
 	duration := time.Since(startTime)
 	slog.Info("日志清理完成", 
 		"basic_deleted", basicDeleted, 
@@ -124,6 +179,37 @@ func (s *LogCleanupService) CleanupThematicSyncLogs(ctx context.Context, retenti
 	return result.RowsAffected, nil
 }
 
+// CleanupOperations 清理超过保留天数且已结束的长时操作
+func (s *LogCleanupService) CleanupOperations(ctx context.Context, retentionDays int) (int64, error) {
+	cutoffDate := time.Now().AddDate(0, 0, -retentionDays)
+	result := s.db.Where("finished_at IS NOT NULL AND finished_at < ?", cutoffDate).Delete(&models.Operation{})
+	if result.Error != nil {
+		return 0, fmt.Errorf("删除长时操作记录失败: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
+
+// CleanupWebhookDeliveries 清理超过保留天数且已结束的Webhook投递日志
+func (s *LogCleanupService) CleanupWebhookDeliveries(ctx context.Context, retentionDays int) (int64, error) {
+	cutoffDate := time.Now().AddDate(0, 0, -retentionDays)
+	result := s.db.Where("status IN ? AND created_at < ?",
+		[]string{models.WebhookDeliverySucceeded, models.WebhookDeliveryFailed}, cutoffDate).
+		Delete(&models.WebhookDelivery{})
+	if result.Error != nil {
+		return 0, fmt.Errorf("删除Webhook投递日志失败: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
+
+// CleanupIdempotencyRecords 清理已过期的幂等请求记录
+func (s *LogCleanupService) CleanupIdempotencyRecords(ctx context.Context) (int64, error) {
+	result := s.db.Where("expires_at < ?", time.Now()).Delete(&models.IdempotencyRecord{})
+	if result.Error != nil {
+		return 0, fmt.Errorf("删除过期幂等请求记录失败: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
+
 // StartScheduledCleanup 启动定时清理任务
 func (s *LogCleanupService) StartScheduledCleanup() error {
 	if s.started {
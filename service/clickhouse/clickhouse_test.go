@@ -0,0 +1,99 @@
+/*
+ * @module service/clickhouse/clickhouse_test
+ * @description ClickHouse客户端和建表语句测试，覆盖类型映射、引擎选择、JSONEachRow写入、只读查询和错误透传
+ * @architecture 测试层 - 单元测试
+ * @rules 使用httptest模拟ClickHouse HTTP接口，不依赖真实ClickHouse
+ */
+
+package clickhouse
+
+import (
+	"context"
+	"datahub-service/service/models"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestBuildCreateTable 测试建表语句的类型映射和引擎选择
+func TestBuildCreateTable(t *testing.T) {
+	fields := []models.TableField{
+		{NameEn: "reading", NameZh: "读数", DataType: "decimal", IsNullable: true, OrderNum: 3},
+		{NameEn: "device_id", NameZh: "设备'编号", DataType: "varchar", IsPrimaryKey: true, IsNullable: true, OrderNum: 1},
+		{NameEn: "collected_at", DataType: "timestamp", IsPrimaryKey: true, OrderNum: 2},
+		{NameEn: "payload", DataType: "jsonb", OrderNum: 4},
+	}
+
+	ddl, err := BuildCreateTable("iot", "readings", fields)
+	require.NoError(t, err)
+	assert.Equal(t, "CREATE TABLE IF NOT EXISTS `iot`.`readings` (\n"+
+		"  `device_id` String COMMENT '设备\\'编号',\n"+
+		"  `collected_at` DateTime64(3),\n"+
+		"  `reading` Nullable(Decimal(38, 10)) COMMENT '读数',\n"+
+		"  `payload` String\n"+
+		") ENGINE = ReplacingMergeTree ORDER BY (`device_id`, `collected_at`)", ddl)
+
+	ddl, err = BuildCreateTable("iot", "events", []models.TableField{{NameEn: "value", DataType: "bigint"}})
+	require.NoError(t, err)
+	assert.True(t, strings.HasSuffix(ddl, "ENGINE = MergeTree ORDER BY tuple()"), "无主键时追加写入")
+
+	_, err = BuildCreateTable("iot", "empty", nil)
+	assert.Error(t, err)
+
+	assert.Equal(t, []string{"ALTER TABLE `iot`.`events` ADD COLUMN IF NOT EXISTS `value` Int64"},
+		BuildAddColumns("iot", "events", []models.TableField{{NameEn: "value", DataType: "bigint"}}))
+}
+
+// TestClientInsertAndQuery 测试写入格式、只读查询参数和结果解析
+func TestClientInsertAndQuery(t *testing.T) {
+	var lastQuery string
+	var lastBody string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, password, _ := r.BasicAuth()
+		assert.Equal(t, "reader", user)
+		assert.Equal(t, "secret", password)
+		body, _ := io.ReadAll(r.Body)
+		lastQuery = r.URL.RawQuery
+		lastBody = string(body)
+
+		if strings.Contains(lastBody, "bad_column") {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte("Code: 47. DB::Exception: Unknown identifier bad_column"))
+			return
+		}
+		if strings.HasSuffix(lastBody, "FORMAT JSON") {
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{
+				"data": []map[string]interface{}{{"total": 9007199254740993}},
+			})
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL+"/", "reader", "secret")
+	ctx := context.Background()
+
+	require.NoError(t, client.Insert(ctx, "iot", "readings", []map[string]interface{}{
+		{"device_id": "d1", "reading": 1.5},
+		{"device_id": "d2", "reading": nil},
+	}))
+	assert.Contains(t, lastQuery, "date_time_input_format=best_effort")
+	lines := strings.Split(strings.TrimSpace(lastBody), "\n")
+	assert.Len(t, lines, 2, "每行一个JSON对象")
+	assert.JSONEq(t, `{"device_id":"d2","reading":null}`, lines[1])
+
+	rows, err := client.Query(ctx, "SELECT count() AS total FROM `iot`.`readings`")
+	require.NoError(t, err)
+	assert.Contains(t, lastQuery, "readonly=2", "查询以只读方式执行")
+	require.Len(t, rows, 1)
+	assert.Equal(t, json.Number("9007199254740993"), rows[0]["total"], "大整数不丢失精度")
+
+	_, err = client.Query(ctx, "SELECT bad_column FROM `iot`.`readings`")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "Unknown identifier")
+}
@@ -0,0 +1,159 @@
+/*
+ * @module service/clickhouse/client
+ * @description ClickHouse HTTP接口客户端，为高写入量的主题接口提供建表、批量写入和只读查询
+ * @architecture 分层架构 - 基础设施层
+ * @documentReference ai_docs/thematic_sync_design.md
+ * @stateFlow 建表/写入 -> POST语句到HTTP接口；查询 -> POST语句(readonly=2) -> 解析FORMAT JSON结果
+ * @rules 通过CLICKHOUSE_URL启用，未配置时Default返回nil；写入使用JSONEachRow格式，时间字段按best_effort解析；
+ *        查询以readonly=2执行，拒绝写入和DDL，数据浏览的前端条件原样透传也不会修改数据；主题库schema名即ClickHouse数据库名
+ * @dependencies net/http, encoding/json
+ * @refs ddl.go, service/thematic_library/thematic_sync/data_writer.go, api/controllers/data_view_controller.go
+ */
+
+package clickhouse
+
+import (
+	"bytes"
+	"context"
+	"datahub-service/service/utils"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// defaultTimeout 单次请求超时时间
+const defaultTimeout = 60 * time.Second
+
+// Client ClickHouse HTTP客户端
+type Client struct {
+	baseURL    string
+	user       string
+	password   string
+	httpClient *http.Client
+}
+
+// defaultClient 全局ClickHouse客户端
+var defaultClient atomic.Pointer[Client]
+
+// NewClient 创建ClickHouse客户端，baseURL形如http://clickhouse:8123
+func NewClient(baseURL, user, password string) *Client {
+	return &Client{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		user:       user,
+		password:   password,
+		httpClient: &http.Client{Timeout: defaultTimeout},
+	}
+}
+
+// NewClientFromEnv 根据CLICKHOUSE_URL、CLICKHOUSE_USER、CLICKHOUSE_PASSWORD创建客户端，未配置地址时返回nil
+func NewClientFromEnv() *Client {
+	baseURL := os.Getenv("CLICKHOUSE_URL")
+	if baseURL == "" {
+		return nil
+	}
+	return NewClient(baseURL, os.Getenv("CLICKHOUSE_USER"), os.Getenv("CLICKHOUSE_PASSWORD"))
+}
+
+// SetDefault 设置全局ClickHouse客户端，传入nil表示未启用
+func SetDefault(c *Client) {
+	defaultClient.Store(c)
+}
+
+// Default 返回全局ClickHouse客户端，未启用时返回nil
+func Default() *Client {
+	return defaultClient.Load()
+}
+
+// Ping 检查服务是否可用
+func (c *Client) Ping(ctx context.Context) error {
+	return c.Exec(ctx, "SELECT 1")
+}
+
+// Exec 执行不返回结果的语句，如DDL
+func (c *Client) Exec(ctx context.Context, query string) error {
+	body, err := c.do(ctx, nil, strings.NewReader(query))
+	if err != nil {
+		return err
+	}
+	return body.Close()
+}
+
+// Insert 以JSONEachRow格式批量写入，rows中的键为列名
+func (c *Client) Insert(ctx context.Context, database, table string, rows []map[string]interface{}) error {
+	if len(rows) == 0 {
+		return nil
+	}
+
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	for _, row := range rows {
+		if err := encoder.Encode(row); err != nil {
+			return fmt.Errorf("序列化写入数据失败: %w", err)
+		}
+	}
+
+	params := url.Values{}
+	params.Set("query", fmt.Sprintf("INSERT INTO %s FORMAT JSONEachRow", utils.QuoteTableFor(utils.DialectClickHouse, database, table)))
+	params.Set("date_time_input_format", "best_effort")
+	params.Set("input_format_skip_unknown_fields", "1")
+	body, err := c.do(ctx, params, &buf)
+	if err != nil {
+		return err
+	}
+	return body.Close()
+}
+
+// Query 执行只读查询，返回按列名组织的行
+func (c *Client) Query(ctx context.Context, query string) ([]map[string]interface{}, error) {
+	params := url.Values{}
+	params.Set("readonly", "2")
+	params.Set("output_format_json_quote_64bit_integers", "0")
+	body, err := c.do(ctx, params, strings.NewReader(query+" FORMAT JSON"))
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	var result struct {
+		Data []map[string]interface{} `json:"data"`
+	}
+	decoder := json.NewDecoder(body)
+	// 保留整数精度，避免Int64/UInt64经float64丢失
+	decoder.UseNumber()
+	if err := decoder.Decode(&result); err != nil {
+		return nil, fmt.Errorf("解析查询结果失败: %w", err)
+	}
+	return result.Data, nil
+}
+
+// do 发送请求，状态码异常时返回ClickHouse的错误信息
+func (c *Client) do(ctx context.Context, params url.Values, payload io.Reader) (io.ReadCloser, error) {
+	endpoint := c.baseURL + "/"
+	if len(params) > 0 {
+		endpoint += "?" + params.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, payload)
+	if err != nil {
+		return nil, fmt.Errorf("创建ClickHouse请求失败: %w", err)
+	}
+	if c.user != "" {
+		req.SetBasicAuth(c.user, c.password)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("请求ClickHouse失败: %w", err)
+	}
+	if resp.StatusCode >= http.StatusMultipleChoices {
+		defer resp.Body.Close()
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return nil, fmt.Errorf("ClickHouse返回错误，状态码: %d, 响应: %s", resp.StatusCode, strings.TrimSpace(string(respBody)))
+	}
+	return resp.Body, nil
+}
@@ -0,0 +1,173 @@
+/*
+ * @module service/clickhouse/ddl
+ * @description ClickHouse方言的建表语句生成，以及基于HTTP客户端的建表、加列、删表和数据浏览
+ * @architecture 分层架构 - 基础设施层
+ * @documentReference ai_docs/thematic_sync_design.md
+ * @stateFlow 字段配置 -> 映射ClickHouse类型 -> CREATE TABLE(ReplacingMergeTree) / ALTER TABLE ADD COLUMN
+ * @rules 有主键时使用ReplacingMergeTree按主键排序，同主键的行在后台合并时只保留最后写入的一行，实现与PostgreSQL upsert相近的语义；
+ *        无主键时使用MergeTree追加写入；主键列不能为Nullable；已存在列的类型变化不自动修改，需要手工迁移
+ * @dependencies datahub-service/service/models
+ * @refs client.go, service/database/schema_service.go
+ */
+
+package clickhouse
+
+import (
+	"context"
+	"datahub-service/service/models"
+	"datahub-service/service/utils"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// columnTypes 字段配置数据类型到ClickHouse类型的映射，未列出的类型使用String
+var columnTypes = map[string]string{
+	"smallint":    "Int16",
+	"int":         "Int32",
+	"integer":     "Int32",
+	"int4":        "Int32",
+	"bigint":      "Int64",
+	"int8":        "Int64",
+	"float":       "Float32",
+	"float4":      "Float32",
+	"real":        "Float32",
+	"double":      "Float64",
+	"float8":      "Float64",
+	"decimal":     "Decimal(38, 10)",
+	"numeric":     "Decimal(38, 10)",
+	"bool":        "Bool",
+	"boolean":     "Bool",
+	"date":        "Date32",
+	"datetime":    "DateTime64(3)",
+	"timestamp":   "DateTime64(3)",
+	"timestamptz": "DateTime64(3)",
+	"uuid":        "UUID",
+}
+
+// ColumnType 返回字段的ClickHouse列类型
+func ColumnType(field models.TableField) string {
+	columnType, ok := columnTypes[strings.ToLower(field.DataType)]
+	if !ok {
+		columnType = "String"
+	}
+	if field.IsNullable && !field.IsPrimaryKey {
+		return "Nullable(" + columnType + ")"
+	}
+	return columnType
+}
+
+// BuildCreateTable 生成建表语句
+func BuildCreateTable(database, table string, fields []models.TableField) (string, error) {
+	if len(fields) == 0 {
+		return "", errors.New("字段配置为空")
+	}
+	ordered := orderedFields(fields)
+
+	columns := make([]string, 0, len(ordered))
+	var primaryKeys []string
+	for _, field := range ordered {
+		columns = append(columns, columnDefinition(field))
+		if field.IsPrimaryKey {
+			primaryKeys = append(primaryKeys, utils.QuoteIdentFor(utils.DialectClickHouse, field.NameEn))
+		}
+	}
+
+	engine := "MergeTree"
+	orderBy := "tuple()"
+	if len(primaryKeys) > 0 {
+		engine = "ReplacingMergeTree"
+		orderBy = "(" + strings.Join(primaryKeys, ", ") + ")"
+	}
+
+	return fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (\n  %s\n) ENGINE = %s ORDER BY %s",
+		utils.QuoteTableFor(utils.DialectClickHouse, database, table),
+		strings.Join(columns, ",\n  "),
+		engine,
+		orderBy,
+	), nil
+}
+
+// BuildAddColumns 生成补充新增字段的语句，已存在的列不受影响
+func BuildAddColumns(database, table string, fields []models.TableField) []string {
+	ordered := orderedFields(fields)
+	statements := make([]string, 0, len(ordered))
+	for _, field := range ordered {
+		statements = append(statements, fmt.Sprintf("ALTER TABLE %s ADD COLUMN IF NOT EXISTS %s",
+			utils.QuoteTableFor(utils.DialectClickHouse, database, table), columnDefinition(field)))
+	}
+	return statements
+}
+
+// EnsureTable 确保数据库和表存在，并补充字段配置中新增的列
+func (c *Client) EnsureTable(ctx context.Context, database, table string, fields []models.TableField) error {
+	if err := c.Exec(ctx, "CREATE DATABASE IF NOT EXISTS "+utils.QuoteIdentFor(utils.DialectClickHouse, database)); err != nil {
+		return fmt.Errorf("创建数据库失败: %w", err)
+	}
+
+	createSQL, err := BuildCreateTable(database, table, fields)
+	if err != nil {
+		return err
+	}
+	if err := c.Exec(ctx, createSQL); err != nil {
+		return fmt.Errorf("创建表失败: %w", err)
+	}
+
+	for _, statement := range BuildAddColumns(database, table, fields) {
+		if err := c.Exec(ctx, statement); err != nil {
+			return fmt.Errorf("添加列失败: %w", err)
+		}
+	}
+	return nil
+}
+
+// DropTable 删除表
+func (c *Client) DropTable(ctx context.Context, database, table string) error {
+	return c.Exec(ctx, fmt.Sprintf("DROP TABLE IF EXISTS %s", utils.QuoteTableFor(utils.DialectClickHouse, database, table)))
+}
+
+// BrowseTable 分页查询表数据，whereCondition为不含WHERE关键字的过滤条件，原样透传给ClickHouse
+func (c *Client) BrowseTable(ctx context.Context, database, table, whereCondition string, limit, offset int) ([]map[string]interface{}, int64, error) {
+	source := utils.QuoteTableFor(utils.DialectClickHouse, database, table)
+	whereClause := ""
+	if whereCondition != "" {
+		whereClause = " WHERE " + whereCondition
+	}
+
+	countRows, err := c.Query(ctx, fmt.Sprintf("SELECT count() AS total FROM %s%s", source, whereClause))
+	if err != nil {
+		return nil, 0, fmt.Errorf("获取总行数失败: %w", err)
+	}
+	var total int64
+	if len(countRows) > 0 {
+		if _, err := fmt.Sscan(fmt.Sprint(countRows[0]["total"]), &total); err != nil {
+			return nil, 0, fmt.Errorf("解析总行数失败: %w", err)
+		}
+	}
+
+	rows, err := c.Query(ctx, fmt.Sprintf("SELECT * FROM %s%s LIMIT %d OFFSET %d", source, whereClause, limit, offset))
+	if err != nil {
+		return nil, 0, fmt.Errorf("查询数据失败: %w", err)
+	}
+	return rows, total, nil
+}
+
+// columnDefinition 生成列定义
+func columnDefinition(field models.TableField) string {
+	definition := utils.QuoteIdentFor(utils.DialectClickHouse, field.NameEn) + " " + ColumnType(field)
+	if field.NameZh != "" {
+		definition += " COMMENT '" + strings.ReplaceAll(strings.ReplaceAll(field.NameZh, `\`, `\\`), "'", `\'`) + "'"
+	}
+	return definition
+}
+
+// orderedFields 按OrderNum排序字段
+func orderedFields(fields []models.TableField) []models.TableField {
+	ordered := make([]models.TableField, len(fields))
+	copy(ordered, fields)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return ordered[i].OrderNum < ordered[j].OrderNum
+	})
+	return ordered
+}
@@ -0,0 +1,637 @@
+/*
+ * @module service/compliance/compliance_service
+ * @description 合规证据包生成服务，汇总敏感数据清单、脱敏覆盖、访问审计、备份状态和保留期执行情况，供个人信息保护法合规审计和等保测评使用
+ * @architecture 分层架构 - 业务服务层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow 指定时段 -> 采集各部分证据 -> 生成问题 -> 汇总控制项结论 -> 返回JSON或导出xlsx
+ * @rules
+ *   - 敏感字段取接口字段配置中is_sensitive的字段；被主题同步任务或共享接口启用的脱敏规则覆盖计为脱敏，配置了列加密计为加密，二者都没有的为未保护字段
+ *   - 访问审计和备份记录按时段统计，时段默认最近30天；清单、覆盖率、备份配置和保留期按生成时的状态
+ *   - 最近一次成功备份超过COMPLIANCE_BACKUP_MAX_AGE_DAYS（默认7天）的启用备份配置视为过期
+ *   - 清理任务每天执行一次，超过保留期一天以上仍未清理的记录才计为超期
+ *   - 请求携带租户时，库和接口只统计该租户的
+ *   - 证据包只读取数据，统计查询走只读副本，未配置只读副本时使用主库
+ * @dependencies datahub-service/service/models, datahub-service/service/config, datahub-service/service/tenant, gorm.io/gorm
+ * @refs service/compliance/report.go, service/compliance/export.go, service/thematic_library/publication.go, service/cleanup/log_cleanup_service.go
+ */
+
+package compliance
+
+import (
+	"context"
+	"datahub-service/service/config"
+	"datahub-service/service/models"
+	"datahub-service/service/tenant"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// defaultPeriodDays 未指定时段时统计最近的天数
+	defaultPeriodDays = 30
+	// defaultBackupMaxAgeDays 最近一次成功备份的最长间隔
+	defaultBackupMaxAgeDays = 7
+	// topApplications 访问审计中列出的调用量最多的应用数
+	topApplications = 10
+	// cleanupGraceDays 清理任务的执行间隔，保留期之后这段时间内未清理不计为超期
+	cleanupGraceDays = 1
+	// verificationUnverified 未校验的备份
+	verificationUnverified = "unverified"
+)
+
+// ErrInvalidPeriod 统计时段无效
+var ErrInvalidPeriod = errors.New("统计时段无效，开始时间必须早于结束时间")
+
+// RetentionSource 日志保留期配置，由配置服务提供
+type RetentionSource interface {
+	GetBasicSyncLogRetentionDays() (int, error)
+	GetThematicSyncLogRetentionDays() (int, error)
+}
+
+// ReportQuery 证据包生成参数
+type ReportQuery struct {
+	Start    *time.Time
+	End      *time.Time
+	Operator string
+}
+
+// Service 合规证据包生成服务
+type Service struct {
+	db               *gorm.DB
+	readDB           *gorm.DB // 证据包统计查询使用的只读连接
+	retention        RetentionSource
+	backupMaxAgeDays int
+	now              func() time.Time
+}
+
+// NewService 创建合规证据包生成服务，retention为空时使用默认保留期
+func NewService(db *gorm.DB, retention RetentionSource) *Service {
+	maxAge := defaultBackupMaxAgeDays
+	if value, err := strconv.Atoi(os.Getenv("COMPLIANCE_BACKUP_MAX_AGE_DAYS")); err == nil && value > 0 {
+		maxAge = value
+	}
+	return &Service{
+		db:               db,
+		readDB:           db,
+		retention:        retention,
+		backupMaxAgeDays: maxAge,
+		now:              time.Now,
+	}
+}
+
+// SetReadDB 设置只读副本连接，为空时回退到主库连接
+func (s *Service) SetReadDB(readDB *gorm.DB) {
+	if readDB == nil {
+		readDB = s.db
+	}
+	s.readDB = readDB
+}
+
+// Generate 生成合规证据包
+func (s *Service) Generate(ctx context.Context, q ReportQuery) (*Report, error) {
+	now := s.now()
+	report := &Report{
+		GeneratedAt: now,
+		GeneratedBy: q.Operator,
+		PeriodEnd:   now,
+		PeriodStart: now.AddDate(0, 0, -defaultPeriodDays),
+		Findings:    []Finding{},
+	}
+	if q.End != nil {
+		report.PeriodEnd = *q.End
+	}
+	if q.Start != nil {
+		report.PeriodStart = *q.Start
+	} else if q.End != nil {
+		report.PeriodStart = q.End.AddDate(0, 0, -defaultPeriodDays)
+	}
+	if !report.PeriodStart.Before(report.PeriodEnd) {
+		return nil, ErrInvalidPeriod
+	}
+
+	if err := s.collectSensitiveInventory(ctx, report); err != nil {
+		return nil, err
+	}
+	if err := s.collectAccessAudit(ctx, report); err != nil {
+		return nil, err
+	}
+	if err := s.collectBackupStatus(ctx, report); err != nil {
+		return nil, err
+	}
+	if err := s.collectRetention(ctx, report); err != nil {
+		return nil, err
+	}
+	summarize(report)
+	return report, nil
+}
+
+// === 敏感数据清单与脱敏覆盖 ===
+
+// interfaceRow 接口及所属库
+type interfaceRow struct {
+	ID                string
+	NameZh            string
+	NameEn            string
+	LibraryID         string
+	TableFieldsConfig models.JSONB
+}
+
+// collectSensitiveInventory 收集含敏感字段的接口，按脱敏规则和列加密计算保护情况
+func (s *Service) collectSensitiveInventory(ctx context.Context, report *Report) error {
+	var encryptions []models.ColumnEncryption
+	if err := s.readDB.WithContext(ctx).Order("schema_name, table_name, column_name").Find(&encryptions).Error; err != nil {
+		return fmt.Errorf("查询列加密配置失败: %w", err)
+	}
+	encrypted := make(map[string]bool, len(encryptions))
+	report.ColumnEncryptions = make([]ColumnEncryption, 0, len(encryptions))
+	for _, enc := range encryptions {
+		encrypted[enc.SchemaName+"."+enc.TableName+"."+enc.ColumnName] = true
+		report.ColumnEncryptions = append(report.ColumnEncryptions, ColumnEncryption{
+			SchemaName:      enc.SchemaName,
+			TableName:       enc.TableName,
+			ColumnName:      enc.ColumnName,
+			Mode:            enc.Mode,
+			AuthorizedRoles: enc.AuthorizedRoles,
+		})
+	}
+
+	var basicLibraries []models.BasicLibrary
+	if err := s.readDB.WithContext(ctx).Select("id", "name_zh", "name_en", "schema_name").Find(&basicLibraries).Error; err != nil {
+		return fmt.Errorf("查询基础库失败: %w", err)
+	}
+	var basicInterfaces []interfaceRow
+	if err := s.readDB.WithContext(ctx).Model(&models.DataInterface{}).
+		Scopes(tenant.OwnerScope(ctx, "library_id", "basic_libraries")).
+		Select("id", "name_zh", "name_en", "library_id", "table_fields_config").
+		Order("name_en").Find(&basicInterfaces).Error; err != nil {
+		return fmt.Errorf("查询基础库接口失败: %w", err)
+	}
+	basicByID := make(map[string]models.BasicLibrary, len(basicLibraries))
+	for _, library := range basicLibraries {
+		basicByID[library.ID] = library
+	}
+
+	var thematicLibraries []models.ThematicLibrary
+	if err := s.readDB.WithContext(ctx).Select("id", "name_zh", "name_en", "schema_name").Find(&thematicLibraries).Error; err != nil {
+		return fmt.Errorf("查询主题库失败: %w", err)
+	}
+	var thematicInterfaces []interfaceRow
+	if err := s.readDB.WithContext(ctx).Model(&models.ThematicInterface{}).
+		Scopes(tenant.OwnerScope(ctx, "library_id", "thematic_libraries")).
+		Select("id", "name_zh", "name_en", "library_id", "table_fields_config").
+		Order("name_en").Find(&thematicInterfaces).Error; err != nil {
+		return fmt.Errorf("查询主题接口失败: %w", err)
+	}
+	thematicByID := make(map[string]models.ThematicLibrary, len(thematicLibraries))
+	for _, library := range thematicLibraries {
+		thematicByID[library.ID] = library
+	}
+	masked, err := s.thematicMaskedFields(ctx)
+	if err != nil {
+		return err
+	}
+
+	report.SensitiveInventory = []SensitiveAsset{}
+	for _, iface := range basicInterfaces {
+		library, ok := basicByID[iface.LibraryID]
+		if !ok {
+			continue
+		}
+		asset := SensitiveAsset{
+			LibraryType: "basic_library", LibraryID: library.ID, LibraryName: library.NameZh,
+			InterfaceID: iface.ID, InterfaceName: iface.NameZh,
+			SchemaName: library.GetSchemaName(), TableName: iface.NameEn,
+		}
+		s.addSensitiveAsset(report, asset, iface.TableFieldsConfig, nil, encrypted)
+	}
+	for _, iface := range thematicInterfaces {
+		library, ok := thematicByID[iface.LibraryID]
+		if !ok {
+			continue
+		}
+		asset := SensitiveAsset{
+			LibraryType: "thematic_library", LibraryID: library.ID, LibraryName: library.NameZh,
+			InterfaceID: iface.ID, InterfaceName: iface.NameZh,
+			SchemaName: library.GetSchemaName(), TableName: iface.NameEn,
+		}
+		s.addSensitiveAsset(report, asset, iface.TableFieldsConfig, masked[iface.ID], encrypted)
+	}
+
+	coverage := &report.MaskingCoverage
+	coverage.SensitiveInterfaces = len(report.SensitiveInventory)
+	coverage.Coverage = 100
+	if coverage.SensitiveFields > 0 {
+		coverage.Coverage = float64(coverage.ProtectedFields) * 100 / float64(coverage.SensitiveFields)
+	}
+	return nil
+}
+
+// addSensitiveAsset 计算接口敏感字段的保护情况，没有敏感字段的接口不列入清单
+func (s *Service) addSensitiveAsset(report *Report, asset SensitiveAsset, fieldsConfig models.JSONB, masked map[string]bool, encrypted map[string]bool) {
+	asset.SensitiveFields = sensitiveFields(fieldsConfig)
+	if len(asset.SensitiveFields) == 0 {
+		return
+	}
+	asset.MaskedFields = []string{}
+	asset.EncryptedFields = []string{}
+	asset.UnprotectedFields = []string{}
+	for _, field := range asset.SensitiveFields {
+		isMasked := masked[field]
+		isEncrypted := encrypted[asset.SchemaName+"."+asset.TableName+"."+field]
+		if isMasked {
+			asset.MaskedFields = append(asset.MaskedFields, field)
+		}
+		if isEncrypted {
+			asset.EncryptedFields = append(asset.EncryptedFields, field)
+		}
+		if !isMasked && !isEncrypted {
+			asset.UnprotectedFields = append(asset.UnprotectedFields, field)
+		}
+	}
+
+	coverage := &report.MaskingCoverage
+	coverage.SensitiveFields += len(asset.SensitiveFields)
+	coverage.MaskedFields += len(asset.MaskedFields)
+	coverage.EncryptedFields += len(asset.EncryptedFields)
+	coverage.ProtectedFields += len(asset.SensitiveFields) - len(asset.UnprotectedFields)
+	report.SensitiveInventory = append(report.SensitiveInventory, asset)
+
+	if len(asset.UnprotectedFields) > 0 {
+		report.Findings = append(report.Findings, Finding{
+			Section: SectionMasking, Severity: SeverityHigh,
+			ObjectType: asset.LibraryType + "_interface", ObjectID: asset.InterfaceID,
+			Message: fmt.Sprintf("%s/%s 的敏感字段未脱敏或加密: %v", asset.LibraryName, asset.InterfaceName, asset.UnprotectedFields),
+		})
+	}
+}
+
+// thematicMaskedFields 主题接口被启用的脱敏规则覆盖的字段，来源为主题同步任务和启用的共享接口
+func (s *Service) thematicMaskedFields(ctx context.Context) (map[string]map[string]bool, error) {
+	masked := make(map[string]map[string]bool)
+	add := func(interfaceID string, configs []models.DataMaskingConfig) {
+		for _, rule := range configs {
+			if !rule.IsEnabled {
+				continue
+			}
+			if masked[interfaceID] == nil {
+				masked[interfaceID] = make(map[string]bool)
+			}
+			for _, field := range rule.TargetFields {
+				masked[interfaceID][field] = true
+			}
+		}
+	}
+
+	var tasks []models.ThematicSyncTask
+	if err := s.readDB.WithContext(ctx).Select("id", "thematic_interface_id", "masking_rule_configs").Find(&tasks).Error; err != nil {
+		return nil, fmt.Errorf("查询主题同步任务失败: %w", err)
+	}
+	for _, task := range tasks {
+		var configs []models.DataMaskingConfig
+		if data, err := json.Marshal(task.MaskingRuleConfigs); err == nil {
+			json.Unmarshal(data, &configs)
+		}
+		add(task.ThematicInterfaceID, configs)
+	}
+
+	var apis []models.ApiInterface
+	if err := s.readDB.WithContext(ctx).Select("id", "thematic_interface_id", "masking_rules").
+		Where("status = ?", "active").Find(&apis).Error; err != nil {
+		return nil, fmt.Errorf("查询共享接口失败: %w", err)
+	}
+	for _, api := range apis {
+		configs := make([]models.DataMaskingConfig, 0, len(api.MaskingRules))
+		for _, value := range api.MaskingRules {
+			var rule models.DataMaskingConfig
+			if data, err := json.Marshal(value); err == nil && json.Unmarshal(data, &rule) == nil {
+				configs = append(configs, rule)
+			}
+		}
+		add(api.ThematicInterfaceID, configs)
+	}
+	return masked, nil
+}
+
+// sensitiveFields 字段配置中标记为敏感的字段英文名，按字母序
+func sensitiveFields(fieldsConfig models.JSONB) []string {
+	var fields []string
+	for _, value := range fieldsConfig {
+		var field models.TableField
+		data, err := json.Marshal(value)
+		if err != nil || json.Unmarshal(data, &field) != nil {
+			continue
+		}
+		if field.IsSensitive && field.NameEn != "" {
+			fields = append(fields, field.NameEn)
+		}
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+// === 访问审计 ===
+
+// collectAccessAudit 汇总时段内的共享接口调用、数据使用申请和授权、系统操作日志
+func (s *Service) collectAccessAudit(ctx context.Context, report *Report) error {
+	audit := &report.AccessAudit
+	start, end := report.PeriodStart, report.PeriodEnd
+	db := s.readDB.WithContext(ctx)
+
+	usage := func() *gorm.DB {
+		return db.Model(&models.ApiUsageLog{}).Where("request_time >= ? AND request_time < ?", start, end)
+	}
+	if err := usage().Count(&audit.ApiCalls).Error; err != nil {
+		return fmt.Errorf("统计共享接口调用失败: %w", err)
+	}
+	if err := usage().Where("status_code >= ?", 400).Count(&audit.FailedApiCalls).Error; err != nil {
+		return fmt.Errorf("统计共享接口调用失败: %w", err)
+	}
+	if err := usage().Where("application_id IS NOT NULL").Distinct("application_id").Count(&audit.ApiApplications).Error; err != nil {
+		return fmt.Errorf("统计共享接口调用失败: %w", err)
+	}
+	audit.TopApplications = []ApplicationUsage{}
+	if err := usage().Select("application_id, COUNT(*) AS calls, SUM(CASE WHEN status_code >= 400 THEN 1 ELSE 0 END) AS failed_calls").
+		Where("application_id IS NOT NULL").Group("application_id").Order("calls DESC").Limit(topApplications).
+		Scan(&audit.TopApplications).Error; err != nil {
+		return fmt.Errorf("统计共享接口调用失败: %w", err)
+	}
+	for i := range audit.TopApplications {
+		var app models.ApiApplication
+		if err := db.Select("id", "name").First(&app, "id = ?", audit.TopApplications[i].ApplicationID).Error; err == nil {
+			audit.TopApplications[i].ApplicationName = app.Name
+		}
+	}
+
+	audit.AccessRequests = map[string]int64{}
+	if err := countBy(db.Model(&models.DataAccessRequest{}).Where("requested_at >= ? AND requested_at < ?", start, end),
+		"status", audit.AccessRequests); err != nil {
+		return fmt.Errorf("统计数据使用申请失败: %w", err)
+	}
+
+	grants := func() *gorm.DB { return db.Model(&models.DataAccessGrant{}) }
+	if err := grants().Where("valid_from >= ? AND valid_from < ?", start, end).Count(&audit.GrantsIssued).Error; err != nil {
+		return fmt.Errorf("统计数据使用授权失败: %w", err)
+	}
+	if err := grants().Where("status = ? AND revoked_at >= ? AND revoked_at < ?", models.DataAccessGrantExpired, start, end).
+		Count(&audit.GrantsExpired).Error; err != nil {
+		return fmt.Errorf("统计数据使用授权失败: %w", err)
+	}
+	if err := grants().Where("status = ? AND revoked_at >= ? AND revoked_at < ?", models.DataAccessGrantRevoked, start, end).
+		Count(&audit.GrantsRevoked).Error; err != nil {
+		return fmt.Errorf("统计数据使用授权失败: %w", err)
+	}
+	audit.ActiveGrants = []models.DataAccessGrant{}
+	if err := grants().Where("status = ?", models.DataAccessGrantActive).Order("valid_until").
+		Find(&audit.ActiveGrants).Error; err != nil {
+		return fmt.Errorf("查询生效的数据使用授权失败: %w", err)
+	}
+	for _, grant := range audit.ActiveGrants {
+		if !grant.ValidUntil.After(report.GeneratedAt) {
+			audit.OverdueGrants++
+		}
+		if grant.Purpose == "" {
+			report.Findings = append(report.Findings, Finding{
+				Section: SectionAccessAudit, Severity: SeverityMedium, ObjectType: "data_access_grant", ObjectID: grant.ID,
+				Message: fmt.Sprintf("%s 对 %s %s 的授权没有绑定使用目的", grant.RequesterID, grant.ResourceType, grant.ResourceID),
+			})
+		}
+	}
+	if audit.OverdueGrants > 0 {
+		report.Findings = append(report.Findings, Finding{
+			Section: SectionAccessAudit, Severity: SeverityHigh,
+			Message: fmt.Sprintf("%d 个数据使用授权已过有效期但未回收", audit.OverdueGrants),
+		})
+	}
+
+	logs := func() *gorm.DB {
+		return db.Model(&models.SystemLog{}).Where("operation_time >= ? AND operation_time < ?", start, end)
+	}
+	if err := logs().Count(&audit.Operations).Error; err != nil {
+		return fmt.Errorf("统计系统操作日志失败: %w", err)
+	}
+	if err := logs().Where("operation_result = ?", "failure").Count(&audit.FailedOperations).Error; err != nil {
+		return fmt.Errorf("统计系统操作日志失败: %w", err)
+	}
+	if err := logs().Where("operator_id IS NOT NULL").Distinct("operator_id").Count(&audit.DistinctOperators).Error; err != nil {
+		return fmt.Errorf("统计系统操作日志失败: %w", err)
+	}
+	audit.OperationsByType = map[string]int64{}
+	if err := countBy(logs(), "operation_type", audit.OperationsByType); err != nil {
+		return fmt.Errorf("统计系统操作日志失败: %w", err)
+	}
+	if audit.Operations == 0 {
+		report.Findings = append(report.Findings, Finding{
+			Section: SectionAccessAudit, Severity: SeverityMedium,
+			Message: "统计时段内没有系统操作日志，无法提供操作审计证据",
+		})
+	}
+	return nil
+}
+
+// countBy 按列分组计数
+func countBy(query *gorm.DB, column string, counts map[string]int64) error {
+	var rows []struct {
+		Key   string
+		Count int64
+	}
+	if err := query.Select(column + " AS key, COUNT(*) AS count").Group(column).Scan(&rows).Error; err != nil {
+		return err
+	}
+	for _, row := range rows {
+		counts[row.Key] = row.Count
+	}
+	return nil
+}
+
+// === 备份状态 ===
+
+// collectBackupStatus 汇总备份配置的最近结果、时段内的备份和校验结果，以及没有备份配置的库
+func (s *Service) collectBackupStatus(ctx context.Context, report *Report) error {
+	backup := &report.Backup
+	backup.MaxAgeDays = s.backupMaxAgeDays
+	db := s.readDB.WithContext(ctx)
+
+	var configs []models.BackupConfig
+	if err := db.Order("name").Find(&configs).Error; err != nil {
+		return fmt.Errorf("查询备份配置失败: %w", err)
+	}
+	covered := make(map[string]bool)
+	staleBefore := report.GeneratedAt.AddDate(0, 0, -s.backupMaxAgeDays)
+	backup.Configs = []BackupConfigInfo{}
+	for _, backupConfig := range configs {
+		if !backupConfig.IsEnabled {
+			continue
+		}
+		backup.EnabledConfigs++
+		covered[backupConfig.ObjectType+":"+backupConfig.ObjectID] = true
+
+		info := BackupConfigInfo{ID: backupConfig.ID, Name: backupConfig.Name, ObjectType: backupConfig.ObjectType, ObjectID: backupConfig.ObjectID}
+		var last models.BackupRecord
+		err := db.Where("backup_config_id = ? AND status = ?", backupConfig.ID, models.BackupStatusSuccess).
+			Order("start_time DESC").First(&last).Error
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("查询备份记录失败: %w", err)
+		}
+		if err == nil {
+			finished := last.StartTime
+			if last.EndTime != nil {
+				finished = *last.EndTime
+			}
+			info.LastSuccessAt = &finished
+			info.VerificationStatus = last.VerificationStatus
+		}
+		info.Stale = info.LastSuccessAt == nil || info.LastSuccessAt.Before(staleBefore)
+		backup.Configs = append(backup.Configs, info)
+
+		switch {
+		case info.Stale:
+			report.Findings = append(report.Findings, Finding{
+				Section: SectionBackup, Severity: SeverityMedium, ObjectType: "backup_config", ObjectID: backupConfig.ID,
+				Message: fmt.Sprintf("备份配置 %s 最近%d天没有成功的备份", backupConfig.Name, s.backupMaxAgeDays),
+			})
+		case info.VerificationStatus == models.BackupVerificationCorrupt:
+			report.Findings = append(report.Findings, Finding{
+				Section: SectionBackup, Severity: SeverityHigh, ObjectType: "backup_config", ObjectID: backupConfig.ID,
+				Message: fmt.Sprintf("备份配置 %s 最近一次成功备份的完整性校验未通过", backupConfig.Name),
+			})
+		}
+	}
+
+	records := func() *gorm.DB {
+		return db.Model(&models.BackupRecord{}).Where("start_time >= ? AND start_time < ?", report.PeriodStart, report.PeriodEnd)
+	}
+	backup.RecordsByStatus = map[string]int64{}
+	if err := countBy(records(), "status", backup.RecordsByStatus); err != nil {
+		return fmt.Errorf("统计备份记录失败: %w", err)
+	}
+	backup.Verifications = map[string]int64{}
+	if err := countBy(records().Where("status = ?", models.BackupStatusSuccess), "verification_status", backup.Verifications); err != nil {
+		return fmt.Errorf("统计备份校验结果失败: %w", err)
+	}
+	if count, ok := backup.Verifications[""]; ok {
+		delete(backup.Verifications, "")
+		backup.Verifications[verificationUnverified] += count
+	}
+
+	backup.UncoveredLibraries = []LibraryRef{}
+	var basicLibraries []models.BasicLibrary
+	if err := db.Select("id", "name_zh").Order("name_zh").Find(&basicLibraries).Error; err != nil {
+		return fmt.Errorf("查询基础库失败: %w", err)
+	}
+	for _, library := range basicLibraries {
+		if !covered["basic_library:"+library.ID] {
+			backup.UncoveredLibraries = append(backup.UncoveredLibraries, LibraryRef{"basic_library", library.ID, library.NameZh})
+		}
+	}
+	var thematicLibraries []models.ThematicLibrary
+	if err := db.Select("id", "name_zh").Order("name_zh").Find(&thematicLibraries).Error; err != nil {
+		return fmt.Errorf("查询主题库失败: %w", err)
+	}
+	for _, library := range thematicLibraries {
+		if !covered["thematic_library:"+library.ID] {
+			backup.UncoveredLibraries = append(backup.UncoveredLibraries, LibraryRef{"thematic_library", library.ID, library.NameZh})
+		}
+	}
+	for _, library := range backup.UncoveredLibraries {
+		report.Findings = append(report.Findings, Finding{
+			Section: SectionBackup, Severity: SeverityLow, ObjectType: library.LibraryType, ObjectID: library.LibraryID,
+			Message: fmt.Sprintf("%s 没有启用的备份配置", library.LibraryName),
+		})
+	}
+	return nil
+}
+
+// === 数据保留 ===
+
+// collectRetention 检查同步执行日志是否按保留期清理
+func (s *Service) collectRetention(ctx context.Context, report *Report) error {
+	basicDays, thematicDays := config.DefaultBasicSyncLogRetentionDays, config.DefaultThematicSyncLogRetentionDays
+	if s.retention != nil {
+		if days, err := s.retention.GetBasicSyncLogRetentionDays(); err == nil && days > 0 {
+			basicDays = days
+		}
+		if days, err := s.retention.GetThematicSyncLogRetentionDays(); err == nil && days > 0 {
+			thematicDays = days
+		}
+	}
+
+	checks := []struct {
+		name  string
+		model interface{}
+		days  int
+	}{
+		{"基础库同步执行日志", &models.SyncTaskExecution{}, basicDays},
+		{"主题库同步执行日志", &models.ThematicSyncExecution{}, thematicDays},
+	}
+	report.Retention = make([]RetentionCheck, 0, len(checks))
+	for _, item := range checks {
+		check := RetentionCheck{Name: item.name, RetentionDays: item.days}
+		cutoff := report.GeneratedAt.AddDate(0, 0, -(item.days + cleanupGraceDays))
+		if err := s.readDB.WithContext(ctx).Model(item.model).Where("created_at < ?", cutoff).Count(&check.OverdueCount).Error; err != nil {
+			return fmt.Errorf("检查%s保留期失败: %w", item.name, err)
+		}
+		var oldest []time.Time
+		if err := s.readDB.WithContext(ctx).Model(item.model).Order("created_at").Limit(1).Pluck("created_at", &oldest).Error; err != nil {
+			return fmt.Errorf("检查%s保留期失败: %w", item.name, err)
+		}
+		if len(oldest) > 0 {
+			check.OldestAt = &oldest[0]
+		}
+		check.Conformant = check.OverdueCount == 0
+		report.Retention = append(report.Retention, check)
+
+		if !check.Conformant {
+			report.Findings = append(report.Findings, Finding{
+				Section: SectionRetention, Severity: SeverityMedium,
+				Message: fmt.Sprintf("%s有 %d 条超过保留期 %d 天仍未清理", item.name, check.OverdueCount, item.days),
+			})
+		}
+	}
+	return nil
+}
+
+// summarize 统计问题并按证据部分的问题得出控制项结论
+func summarize(report *Report) {
+	failedSections := make(map[string]bool)
+	for _, finding := range report.Findings {
+		switch finding.Severity {
+		case SeverityHigh:
+			report.Summary.HighFindings++
+			failedSections[finding.Section] = true
+		case SeverityMedium:
+			report.Summary.MediumFindings++
+			failedSections[finding.Section] = true
+		default:
+			report.Summary.LowFindings++
+		}
+	}
+	// 未保护的敏感字段同时说明分类清单对应的措施未落实
+	if failedSections[SectionMasking] {
+		failedSections[SectionSensitiveInventory] = true
+	}
+
+	report.Controls = make([]Control, 0, len(controlCatalog))
+	for _, control := range controlCatalog {
+		control.Status = ControlPass
+		for _, section := range control.Sections {
+			if failedSections[section] {
+				control.Status = ControlFail
+				break
+			}
+		}
+		if control.Status == ControlPass {
+			report.Summary.ControlsPassed++
+		} else {
+			report.Summary.ControlsFailed++
+		}
+		report.Controls = append(report.Controls, control)
+	}
+	report.Summary.Passed = report.Summary.HighFindings == 0 && report.Summary.MediumFindings == 0
+}
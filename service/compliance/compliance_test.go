@@ -0,0 +1,244 @@
+/*
+ * @module service/compliance/compliance_test
+ * @description 合规证据包测试，覆盖敏感字段保护判定、访问审计统计、过期备份和未覆盖的库、超期未清理的执行日志、控制项结论、xlsx导出以及只读副本
+ * @architecture 测试层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow 准备sqlite中的库、接口、脱敏和加密配置、授权、备份和执行日志 -> 生成证据包 -> 验证各部分和控制项
+ * @rules 使用内存sqlite，不依赖外部服务
+ * @dependencies gorm.io/driver/sqlite, stretchr/testify
+ * @refs compliance_service.go, export.go
+ */
+
+package compliance
+
+import (
+	"context"
+	"datahub-service/service/models"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// fixedRetention 固定的日志保留期
+type fixedRetention struct{ days int }
+
+func (r fixedRetention) GetBasicSyncLogRetentionDays() (int, error)    { return r.days, nil }
+func (r fixedRetention) GetThematicSyncLogRetentionDays() (int, error) { return r.days, nil }
+
+// setupComplianceDB 准备基础库basic-1（接口含敏感字段id_no、phone，其中id_no加密）和主题库lib-1（接口phone由同步任务脱敏）
+func setupComplianceDB(t *testing.T) (*gorm.DB, *Service) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(
+		&models.BasicLibrary{}, &models.DataInterface{}, &models.ThematicLibrary{}, &models.ThematicInterface{},
+		&models.ThematicSyncTask{}, &models.ThematicSyncExecution{}, &models.SyncTaskExecution{},
+		&models.ApiApplication{}, &models.ApiInterface{}, &models.ApiUsageLog{}, &models.ColumnEncryption{},
+		&models.DataAccessRequest{}, &models.DataAccessGrant{}, &models.SystemLog{},
+		&models.BackupConfig{}, &models.BackupRecord{},
+	))
+
+	require.NoError(t, db.Create(&models.BasicLibrary{ID: "basic-1", NameZh: "户籍", NameEn: "household", Status: "active"}).Error)
+	require.NoError(t, db.Create(&models.DataInterface{
+		ID: "if-resident", LibraryID: "basic-1", NameZh: "居民", NameEn: "resident", Type: "realtime", Status: "active",
+		TableFieldsConfig: models.JSONB{
+			"field_0": map[string]interface{}{"name_en": "id", "data_type": "varchar", "is_primary_key": true, "order_num": 1},
+			"field_1": map[string]interface{}{"name_en": "id_no", "data_type": "varchar", "is_sensitive": true, "order_num": 2},
+			"field_2": map[string]interface{}{"name_en": "phone", "data_type": "varchar", "is_sensitive": true, "order_num": 3},
+		},
+	}).Error)
+	require.NoError(t, db.Create(&models.ColumnEncryption{SchemaName: "household", TableName: "resident", ColumnName: "id_no", Mode: "aes"}).Error)
+
+	require.NoError(t, db.Create(&models.ThematicLibrary{ID: "lib-1", NameZh: "人口", NameEn: "population"}).Error)
+	require.NoError(t, db.Create(&models.ThematicInterface{
+		ID: "if-person", LibraryID: "lib-1", NameZh: "人员", NameEn: "person", Type: "table", Status: "active",
+		TableFieldsConfig: models.JSONB{
+			"field_0": map[string]interface{}{"name_en": "phone", "data_type": "varchar", "is_sensitive": true, "order_num": 1},
+		},
+	}).Error)
+	require.NoError(t, db.Create(&models.ThematicSyncTask{
+		ID: "task-person", ThematicLibraryID: "lib-1", ThematicInterfaceID: "if-person", TaskName: "人员汇聚", TriggerType: "manual", Status: "active",
+		MaskingRuleConfigs: models.JSONBGenericArray{
+			map[string]interface{}{"template_id": "mask-phone", "target_fields": []string{"phone"}, "is_enabled": true},
+		},
+	}).Error)
+
+	operator := "alice"
+	require.NoError(t, db.Create(&models.SystemLog{
+		OperationType: "update", ObjectType: "basic_library", OperatorID: &operator,
+		OperationContent: models.JSONB{}, OperationTime: time.Now().Add(-time.Hour), OperationResult: "success",
+	}).Error)
+
+	return db, NewService(db, fixedRetention{days: 30})
+}
+
+func findingsOf(report *Report, section string) []Finding {
+	var findings []Finding
+	for _, finding := range report.Findings {
+		if finding.Section == section {
+			findings = append(findings, finding)
+		}
+	}
+	return findings
+}
+
+func controlStatus(report *Report, clause string) string {
+	for _, control := range report.Controls {
+		if control.Clause == clause {
+			return control.Status
+		}
+	}
+	return ""
+}
+
+func TestSensitiveInventory(t *testing.T) {
+	db, s := setupComplianceDB(t)
+	ctx := context.Background()
+
+	report, err := s.Generate(ctx, ReportQuery{Operator: "auditor"})
+	require.NoError(t, err)
+	assert.Equal(t, "auditor", report.GeneratedBy)
+	require.Len(t, report.SensitiveInventory, 2)
+
+	basic := report.SensitiveInventory[0]
+	assert.Equal(t, "if-resident", basic.InterfaceID)
+	assert.Equal(t, []string{"id_no", "phone"}, basic.SensitiveFields)
+	assert.Equal(t, []string{"id_no"}, basic.EncryptedFields)
+	assert.Equal(t, []string{"phone"}, basic.UnprotectedFields)
+	thematic := report.SensitiveInventory[1]
+	assert.Equal(t, []string{"phone"}, thematic.MaskedFields)
+	assert.Empty(t, thematic.UnprotectedFields)
+
+	assert.Equal(t, 3, report.MaskingCoverage.SensitiveFields)
+	assert.Equal(t, 2, report.MaskingCoverage.ProtectedFields)
+	assert.InDelta(t, 66.67, report.MaskingCoverage.Coverage, 0.01)
+	require.Len(t, findingsOf(report, SectionMasking), 1)
+	assert.Equal(t, SeverityHigh, findingsOf(report, SectionMasking)[0].Severity)
+	assert.Equal(t, ControlFail, controlStatus(report, "第五十一条"))
+	assert.Equal(t, ControlFail, controlStatus(report, "安全计算环境-数据保密性"))
+
+	// 加密剩余的敏感字段后脱敏相关控制项通过
+	require.NoError(t, db.Create(&models.ColumnEncryption{SchemaName: "household", TableName: "resident", ColumnName: "phone", Mode: "aes"}).Error)
+	report, err = s.Generate(ctx, ReportQuery{})
+	require.NoError(t, err)
+	assert.Equal(t, 100.0, report.MaskingCoverage.Coverage)
+	assert.Empty(t, findingsOf(report, SectionMasking))
+	assert.Equal(t, ControlPass, controlStatus(report, "第五十一条"))
+}
+
+func TestGenerateReadsFromReplica(t *testing.T) {
+	primary, s := setupComplianceDB(t)
+	replica, _ := setupComplianceDB(t)
+	// 只有副本上加密了phone
+	require.NoError(t, replica.Create(&models.ColumnEncryption{SchemaName: "household", TableName: "resident", ColumnName: "phone", Mode: "aes"}).Error)
+	ctx := context.Background()
+
+	s.SetReadDB(replica)
+	report, err := s.Generate(ctx, ReportQuery{})
+	require.NoError(t, err)
+	assert.Equal(t, 100.0, report.MaskingCoverage.Coverage, "配置副本时从副本读取")
+
+	// 未配置副本时回退到主库
+	s.SetReadDB(nil)
+	report, err = s.Generate(ctx, ReportQuery{})
+	require.NoError(t, err)
+	assert.InDelta(t, 66.67, report.MaskingCoverage.Coverage, 0.01)
+	assert.Same(t, primary, s.readDB)
+}
+
+func TestAccessAuditAndRetention(t *testing.T) {
+	db, s := setupComplianceDB(t)
+	ctx := context.Background()
+	now := time.Now()
+
+	require.NoError(t, db.Create(&models.DataAccessGrant{
+		RequestID: "req-1", RequesterID: "bob", ResourceType: "thematic_library", ResourceID: "lib-1", AccessPermission: "read",
+		Purpose: "统计分析", ValidFrom: now.AddDate(0, 0, -10), ValidUntil: now.AddDate(0, 0, -1), Status: models.DataAccessGrantActive, GrantedBy: "admin",
+	}).Error)
+	require.NoError(t, db.Create(&models.SyncTaskExecution{
+		ID: "exec-old", TaskID: "task-1", ExecutionType: "scheduled", Status: "success", CreatedAt: now.AddDate(0, 0, -40),
+	}).Error)
+	require.NoError(t, db.Create(&models.SyncTaskExecution{
+		ID: "exec-new", TaskID: "task-1", ExecutionType: "scheduled", Status: "success", CreatedAt: now.AddDate(0, 0, -2),
+	}).Error)
+
+	report, err := s.Generate(ctx, ReportQuery{})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), report.AccessAudit.GrantsIssued)
+	assert.Equal(t, int64(1), report.AccessAudit.OverdueGrants)
+	assert.Equal(t, int64(1), report.AccessAudit.Operations)
+	assert.Equal(t, int64(1), report.AccessAudit.DistinctOperators)
+	assert.Equal(t, ControlFail, controlStatus(report, "第六条"))
+
+	require.Len(t, report.Retention, 2)
+	assert.Equal(t, int64(1), report.Retention[0].OverdueCount)
+	assert.False(t, report.Retention[0].Conformant)
+	require.NotNil(t, report.Retention[0].OldestAt)
+	assert.True(t, report.Retention[1].Conformant)
+	assert.Equal(t, ControlFail, controlStatus(report, "第十九条"))
+
+	// 时段外没有操作日志
+	end := now.AddDate(0, 0, -60)
+	report, err = s.Generate(ctx, ReportQuery{End: &end})
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), report.AccessAudit.Operations)
+	assert.Equal(t, int64(0), report.AccessAudit.GrantsIssued)
+
+	_, err = s.Generate(ctx, ReportQuery{Start: &now, End: &end})
+	assert.ErrorIs(t, err, ErrInvalidPeriod)
+}
+
+func TestBackupStatus(t *testing.T) {
+	db, s := setupComplianceDB(t)
+	ctx := context.Background()
+	now := time.Now()
+
+	fresh := &models.BackupConfig{Name: "户籍全量", Type: "full", ObjectType: "basic_library", ObjectID: "basic-1",
+		Strategy: models.JSONB{}, StorageLocation: "minio", IsEnabled: true}
+	require.NoError(t, db.Create(fresh).Error)
+	require.NoError(t, db.Create(&models.BackupRecord{BackupConfigID: fresh.ID, StartTime: now.Add(-time.Hour),
+		Status: models.BackupStatusSuccess, VerificationStatus: models.BackupVerificationVerified}).Error)
+	stale := &models.BackupConfig{Name: "人口全量", Type: "full", ObjectType: "thematic_library", ObjectID: "lib-1",
+		Strategy: models.JSONB{}, StorageLocation: "minio", IsEnabled: true}
+	require.NoError(t, db.Create(stale).Error)
+	require.NoError(t, db.Create(&models.BackupRecord{BackupConfigID: stale.ID, StartTime: now.AddDate(0, 0, -20),
+		Status: models.BackupStatusSuccess}).Error)
+
+	report, err := s.Generate(ctx, ReportQuery{})
+	require.NoError(t, err)
+	assert.Equal(t, 2, report.Backup.EnabledConfigs)
+	assert.Empty(t, report.Backup.UncoveredLibraries)
+	require.Len(t, report.Backup.Configs, 2)
+	assert.True(t, report.Backup.Configs[0].Stale, "人口全量20天没有成功备份")
+	assert.False(t, report.Backup.Configs[1].Stale)
+	assert.Equal(t, int64(1), report.Backup.Verifications[models.BackupVerificationVerified])
+	assert.Equal(t, int64(1), report.Backup.Verifications["unverified"])
+	assert.Equal(t, ControlFail, controlStatus(report, "安全计算环境-数据备份恢复"))
+
+	// 停用后库不再被覆盖，只作为低等级问题
+	require.NoError(t, db.Model(stale).Update("is_enabled", false).Error)
+	report, err = s.Generate(ctx, ReportQuery{})
+	require.NoError(t, err)
+	require.Len(t, report.Backup.UncoveredLibraries, 1)
+	assert.Equal(t, "lib-1", report.Backup.UncoveredLibraries[0].LibraryID)
+	require.Len(t, findingsOf(report, SectionBackup), 1)
+	assert.Equal(t, SeverityLow, findingsOf(report, SectionBackup)[0].Severity)
+	assert.Equal(t, ControlPass, controlStatus(report, "安全计算环境-数据备份恢复"))
+}
+
+func TestRenderXLSX(t *testing.T) {
+	_, s := setupComplianceDB(t)
+
+	report, err := s.Generate(context.Background(), ReportQuery{Operator: "auditor"})
+	require.NoError(t, err)
+	content, err := RenderXLSX(report)
+	require.NoError(t, err)
+	assert.Equal(t, "PK", string(content[:2]))
+	assert.Contains(t, FileName(report), "compliance_report_")
+}
@@ -0,0 +1,222 @@
+/*
+ * @module service/compliance/export
+ * @description 合规证据包导出，按部分写成多工作表的xlsx文件，供安全测评时提交
+ * @architecture 分层架构 - 业务服务层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow 证据包 -> 概览/控制项/问题/敏感数据清单/加密列/访问审计/生效授权/备份/数据保留工作表 -> xlsx
+ * @rules 每个工作表第一行为表头；时间按服务所在时区格式化为"2006-01-02 15:04:05"，空时间写为空字符串；字段列表用顿号分隔
+ * @dependencies datahub-service/service/utils
+ * @refs service/compliance/report.go, service/utils/xlsx.go
+ */
+
+package compliance
+
+import (
+	"datahub-service/service/utils"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 枚举值的中文说明
+var (
+	sectionNames = map[string]string{
+		SectionSensitiveInventory: "敏感数据清单",
+		SectionMasking:            "脱敏覆盖",
+		SectionAccessAudit:        "访问审计",
+		SectionBackup:             "备份状态",
+		SectionRetention:          "数据保留",
+	}
+	severityNames = map[string]string{SeverityHigh: "高", SeverityMedium: "中", SeverityLow: "低"}
+	statusNames   = map[string]string{ControlPass: "符合", ControlFail: "不符合"}
+)
+
+// FileName 导出文件名
+func FileName(report *Report) string {
+	return "compliance_report_" + report.GeneratedAt.In(time.Local).Format("20060102150405") + ".xlsx"
+}
+
+// RenderXLSX 将证据包写成xlsx
+func RenderXLSX(report *Report) ([]byte, error) {
+	content, err := utils.WriteXLSX([]utils.XLSXSheet{
+		overviewSheet(report),
+		controlsSheet(report),
+		findingsSheet(report),
+		inventorySheet(report),
+		encryptionSheet(report),
+		accessAuditSheet(report),
+		activeGrantsSheet(report),
+		backupSheet(report),
+		retentionSheet(report),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("生成合规证据包文件失败: %w", err)
+	}
+	return content, nil
+}
+
+func overviewSheet(report *Report) utils.XLSXSheet {
+	conclusion := "未发现高、中等级问题"
+	if !report.Summary.Passed {
+		conclusion = "存在需要整改的问题"
+	}
+	coverage := report.MaskingCoverage
+	return utils.XLSXSheet{Name: "概览", Rows: [][]string{
+		{"项目", "值"},
+		{"生成时间", formatTime(&report.GeneratedAt)},
+		{"生成人", report.GeneratedBy},
+		{"统计时段", formatTime(&report.PeriodStart) + " 至 " + formatTime(&report.PeriodEnd)},
+		{"结论", conclusion},
+		{"符合的控制项", strconv.Itoa(report.Summary.ControlsPassed)},
+		{"不符合的控制项", strconv.Itoa(report.Summary.ControlsFailed)},
+		{"高等级问题", strconv.Itoa(report.Summary.HighFindings)},
+		{"中等级问题", strconv.Itoa(report.Summary.MediumFindings)},
+		{"低等级问题", strconv.Itoa(report.Summary.LowFindings)},
+		{"含敏感字段的接口", strconv.Itoa(coverage.SensitiveInterfaces)},
+		{"敏感字段", strconv.Itoa(coverage.SensitiveFields)},
+		{"已保护的敏感字段", strconv.Itoa(coverage.ProtectedFields)},
+		{"敏感字段保护覆盖率(%)", strconv.FormatFloat(coverage.Coverage, 'f', 2, 64)},
+	}}
+}
+
+func controlsSheet(report *Report) utils.XLSXSheet {
+	rows := [][]string{{"标准", "条款", "要求", "证据部分", "结论"}}
+	for _, control := range report.Controls {
+		sections := make([]string, 0, len(control.Sections))
+		for _, section := range control.Sections {
+			sections = append(sections, sectionNames[section])
+		}
+		rows = append(rows, []string{control.Standard, control.Clause, control.Title, joinFields(sections), statusNames[control.Status]})
+	}
+	return utils.XLSXSheet{Name: "控制项", Rows: rows}
+}
+
+func findingsSheet(report *Report) utils.XLSXSheet {
+	rows := [][]string{{"部分", "等级", "对象类型", "对象ID", "说明"}}
+	for _, finding := range report.Findings {
+		rows = append(rows, []string{sectionNames[finding.Section], severityNames[finding.Severity], finding.ObjectType, finding.ObjectID, finding.Message})
+	}
+	return utils.XLSXSheet{Name: "问题", Rows: rows}
+}
+
+func inventorySheet(report *Report) utils.XLSXSheet {
+	rows := [][]string{{"库类型", "库名称", "接口名称", "接口ID", "表", "敏感字段", "已脱敏", "已加密", "未保护"}}
+	for _, asset := range report.SensitiveInventory {
+		rows = append(rows, []string{
+			asset.LibraryType, asset.LibraryName, asset.InterfaceName, asset.InterfaceID, asset.SchemaName + "." + asset.TableName,
+			joinFields(asset.SensitiveFields), joinFields(asset.MaskedFields), joinFields(asset.EncryptedFields), joinFields(asset.UnprotectedFields),
+		})
+	}
+	return utils.XLSXSheet{Name: "敏感数据清单", Rows: rows}
+}
+
+func encryptionSheet(report *Report) utils.XLSXSheet {
+	rows := [][]string{{"schema", "表", "列", "加密方式", "可见明文的角色"}}
+	for _, enc := range report.ColumnEncryptions {
+		rows = append(rows, []string{enc.SchemaName, enc.TableName, enc.ColumnName, enc.Mode, joinFields(enc.AuthorizedRoles)})
+	}
+	return utils.XLSXSheet{Name: "加密列", Rows: rows}
+}
+
+func accessAuditSheet(report *Report) utils.XLSXSheet {
+	audit := report.AccessAudit
+	rows := [][]string{
+		{"项目", "值"},
+		{"共享接口调用", formatInt(audit.ApiCalls)},
+		{"失败的共享接口调用", formatInt(audit.FailedApiCalls)},
+		{"有调用的共享应用", formatInt(audit.ApiApplications)},
+		{"生效的数据使用授权", formatInt(audit.GrantsIssued)},
+		{"到期回收的授权", formatInt(audit.GrantsExpired)},
+		{"人工撤销的授权", formatInt(audit.GrantsRevoked)},
+		{"当前生效的授权", strconv.Itoa(len(audit.ActiveGrants))},
+		{"过期未回收的授权", formatInt(audit.OverdueGrants)},
+		{"系统操作日志", formatInt(audit.Operations)},
+		{"失败的操作", formatInt(audit.FailedOperations)},
+		{"操作人数", formatInt(audit.DistinctOperators)},
+	}
+	for _, key := range sortedKeys(audit.AccessRequests) {
+		rows = append(rows, []string{"数据使用申请(" + key + ")", formatInt(audit.AccessRequests[key])})
+	}
+	for _, key := range sortedKeys(audit.OperationsByType) {
+		rows = append(rows, []string{"操作日志(" + key + ")", formatInt(audit.OperationsByType[key])})
+	}
+	rows = append(rows, []string{}, []string{"应用ID", "应用名称", "调用次数", "失败次数"})
+	for _, app := range audit.TopApplications {
+		rows = append(rows, []string{app.ApplicationID, app.ApplicationName, formatInt(app.Calls), formatInt(app.FailedCalls)})
+	}
+	return utils.XLSXSheet{Name: "访问审计", Rows: rows}
+}
+
+func activeGrantsSheet(report *Report) utils.XLSXSheet {
+	rows := [][]string{{"授权ID", "申请人", "资源类型", "资源ID", "权限", "使用目的", "生效时间", "到期时间", "审批人"}}
+	for _, grant := range report.AccessAudit.ActiveGrants {
+		rows = append(rows, []string{
+			grant.ID, grant.RequesterID, grant.ResourceType, grant.ResourceID, grant.AccessPermission, grant.Purpose,
+			formatTime(&grant.ValidFrom), formatTime(&grant.ValidUntil), grant.GrantedBy,
+		})
+	}
+	return utils.XLSXSheet{Name: "生效授权", Rows: rows}
+}
+
+func backupSheet(report *Report) utils.XLSXSheet {
+	backup := report.Backup
+	rows := [][]string{{"备份配置", "对象类型", "对象ID", "最近成功备份", "校验结果", "是否过期"}}
+	for _, info := range backup.Configs {
+		stale := "否"
+		if info.Stale {
+			stale = "是"
+		}
+		rows = append(rows, []string{info.Name, info.ObjectType, info.ObjectID, formatTime(info.LastSuccessAt), info.VerificationStatus, stale})
+	}
+	rows = append(rows, []string{}, []string{"时段内备份状态", "数量"})
+	for _, key := range sortedKeys(backup.RecordsByStatus) {
+		rows = append(rows, []string{key, formatInt(backup.RecordsByStatus[key])})
+	}
+	rows = append(rows, []string{}, []string{"时段内校验结果", "数量"})
+	for _, key := range sortedKeys(backup.Verifications) {
+		rows = append(rows, []string{key, formatInt(backup.Verifications[key])})
+	}
+	rows = append(rows, []string{}, []string{"没有备份配置的库", "库类型", "库ID"})
+	for _, library := range backup.UncoveredLibraries {
+		rows = append(rows, []string{library.LibraryName, library.LibraryType, library.LibraryID})
+	}
+	return utils.XLSXSheet{Name: "备份", Rows: rows}
+}
+
+func retentionSheet(report *Report) utils.XLSXSheet {
+	rows := [][]string{{"数据", "保留天数", "超期记录", "最早记录时间", "是否符合"}}
+	for _, check := range report.Retention {
+		conformant := "否"
+		if check.Conformant {
+			conformant = "是"
+		}
+		rows = append(rows, []string{check.Name, strconv.Itoa(check.RetentionDays), formatInt(check.OverdueCount), formatTime(check.OldestAt), conformant})
+	}
+	return utils.XLSXSheet{Name: "数据保留", Rows: rows}
+}
+
+func formatTime(t *time.Time) string {
+	if t == nil || t.IsZero() {
+		return ""
+	}
+	return t.In(time.Local).Format("2006-01-02 15:04:05")
+}
+
+func formatInt(n int64) string {
+	return strconv.FormatInt(n, 10)
+}
+
+func joinFields(fields []string) string {
+	return strings.Join(fields, "、")
+}
+
+func sortedKeys(counts map[string]int64) []string {
+	keys := make([]string, 0, len(counts))
+	for key := range counts {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
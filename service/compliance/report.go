@@ -0,0 +1,201 @@
+/*
+ * @module service/compliance/report
+ * @description 合规证据包的结构，按敏感数据清单、脱敏覆盖、访问审计、备份状态、数据保留五个部分组织，并给出发现的问题和对应的个人信息保护法、等保2.0控制项
+ * @architecture 分层架构 - 业务服务层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow 各部分采集结果 -> 按规则生成问题 -> 按部分的问题汇总控制项结论
+ * @rules 控制项只引用本平台能提供证据的条款，结论为pass/fail，fail表示其证据部分存在高或中等级的问题；低等级问题只作为改进建议
+ * @dependencies datahub-service/service/models
+ * @refs service/compliance/compliance_service.go, service/compliance/export.go
+ */
+
+package compliance
+
+import (
+	"datahub-service/service/models"
+	"time"
+)
+
+// 证据部分
+const (
+	SectionSensitiveInventory = "sensitive_inventory"
+	SectionMasking            = "masking"
+	SectionAccessAudit        = "access_audit"
+	SectionBackup             = "backup"
+	SectionRetention          = "retention"
+)
+
+// 问题等级
+const (
+	SeverityHigh   = "high"
+	SeverityMedium = "medium"
+	SeverityLow    = "low"
+)
+
+// 控制项结论
+const (
+	ControlPass = "pass"
+	ControlFail = "fail"
+)
+
+// Report 合规证据包
+type Report struct {
+	GeneratedAt        time.Time          `json:"generated_at"`
+	GeneratedBy        string             `json:"generated_by"`
+	PeriodStart        time.Time          `json:"period_start"` // 访问审计和备份统计的时段
+	PeriodEnd          time.Time          `json:"period_end"`
+	Summary            Summary            `json:"summary"`
+	Controls           []Control          `json:"controls"`
+	Findings           []Finding          `json:"findings"`
+	SensitiveInventory []SensitiveAsset   `json:"sensitive_inventory"` // 含敏感字段的接口
+	MaskingCoverage    MaskingCoverage    `json:"masking_coverage"`
+	AccessAudit        AccessAudit        `json:"access_audit"`
+	Backup             BackupStatus       `json:"backup"`
+	Retention          []RetentionCheck   `json:"retention"`
+	ColumnEncryptions  []ColumnEncryption `json:"column_encryptions"` // 加密存储的列
+}
+
+// Summary 证据包结论
+type Summary struct {
+	Passed         bool `json:"passed"` // 没有高、中等级问题
+	ControlsPassed int  `json:"controls_passed"`
+	ControlsFailed int  `json:"controls_failed"`
+	HighFindings   int  `json:"high_findings"`
+	MediumFindings int  `json:"medium_findings"`
+	LowFindings    int  `json:"low_findings"`
+}
+
+// Control 合规控制项及其结论
+type Control struct {
+	Standard string   `json:"standard" example:"个人信息保护法"`
+	Clause   string   `json:"clause" example:"第五十一条"`
+	Title    string   `json:"title" example:"采取加密、去标识化等安全技术措施"`
+	Sections []string `json:"sections"` // 提供证据的部分
+	Status   string   `json:"status"`   // pass/fail
+}
+
+// Finding 发现的问题
+type Finding struct {
+	Section    string `json:"section"`
+	Severity   string `json:"severity"` // high/medium/low
+	ObjectType string `json:"object_type,omitempty"`
+	ObjectID   string `json:"object_id,omitempty"`
+	Message    string `json:"message"`
+}
+
+// SensitiveAsset 含敏感字段的接口及字段的保护情况
+type SensitiveAsset struct {
+	LibraryType       string   `json:"library_type"` // basic_library/thematic_library
+	LibraryID         string   `json:"library_id"`
+	LibraryName       string   `json:"library_name"`
+	InterfaceID       string   `json:"interface_id"`
+	InterfaceName     string   `json:"interface_name"`
+	SchemaName        string   `json:"schema_name"`
+	TableName         string   `json:"table_name"`
+	SensitiveFields   []string `json:"sensitive_fields"`
+	MaskedFields      []string `json:"masked_fields"`      // 被同步或共享接口启用的脱敏规则覆盖
+	EncryptedFields   []string `json:"encrypted_fields"`   // 配置了列加密
+	UnprotectedFields []string `json:"unprotected_fields"` // 既未脱敏也未加密
+}
+
+// MaskingCoverage 敏感字段保护覆盖率
+type MaskingCoverage struct {
+	SensitiveInterfaces int     `json:"sensitive_interfaces"`
+	SensitiveFields     int     `json:"sensitive_fields"`
+	MaskedFields        int     `json:"masked_fields"`
+	EncryptedFields     int     `json:"encrypted_fields"`
+	ProtectedFields     int     `json:"protected_fields"` // 脱敏或加密的字段，同时满足的只计一次
+	Coverage            float64 `json:"coverage"`         // 保护覆盖率(0-100)，没有敏感字段时为100
+}
+
+// ColumnEncryption 加密列清单
+type ColumnEncryption struct {
+	SchemaName      string   `json:"schema_name"`
+	TableName       string   `json:"table_name"`
+	ColumnName      string   `json:"column_name"`
+	Mode            string   `json:"mode"`
+	AuthorizedRoles []string `json:"authorized_roles"`
+}
+
+// AccessAudit 时段内的访问审计汇总
+type AccessAudit struct {
+	ApiCalls          int64                    `json:"api_calls"`
+	FailedApiCalls    int64                    `json:"failed_api_calls"` // 状态码>=400
+	ApiApplications   int64                    `json:"api_applications"` // 有调用的共享应用数
+	TopApplications   []ApplicationUsage       `json:"top_applications"`
+	AccessRequests    map[string]int64         `json:"access_requests"` // 时段内提交的数据使用申请，按状态计数
+	GrantsIssued      int64                    `json:"grants_issued"`   // 时段内生效的授权
+	GrantsExpired     int64                    `json:"grants_expired"`  // 时段内到期回收的授权
+	GrantsRevoked     int64                    `json:"grants_revoked"`  // 时段内人工撤销的授权
+	ActiveGrants      []models.DataAccessGrant `json:"active_grants"`   // 生成时仍生效的授权
+	Operations        int64                    `json:"operations"`      // 时段内的系统操作日志
+	FailedOperations  int64                    `json:"failed_operations"`
+	OperationsByType  map[string]int64         `json:"operations_by_type"`
+	DistinctOperators int64                    `json:"distinct_operators"`
+	OverdueGrants     int64                    `json:"overdue_grants"` // 已过有效期但仍为生效状态的授权
+}
+
+// ApplicationUsage 共享应用的调用量
+type ApplicationUsage struct {
+	ApplicationID   string `json:"application_id"`
+	ApplicationName string `json:"application_name"`
+	Calls           int64  `json:"calls"`
+	FailedCalls     int64  `json:"failed_calls"`
+}
+
+// BackupStatus 备份状态
+type BackupStatus struct {
+	MaxAgeDays         int                `json:"max_age_days"` // 最近一次成功备份超过该天数视为过期
+	EnabledConfigs     int                `json:"enabled_configs"`
+	Configs            []BackupConfigInfo `json:"configs"`
+	RecordsByStatus    map[string]int64   `json:"records_by_status"`   // 时段内的备份记录
+	Verifications      map[string]int64   `json:"verifications"`       // 时段内备份的完整性校验结果，未校验计为unverified
+	UncoveredLibraries []LibraryRef       `json:"uncovered_libraries"` // 没有启用备份配置的库
+}
+
+// BackupConfigInfo 备份配置的最近结果
+type BackupConfigInfo struct {
+	ID                 string     `json:"id"`
+	Name               string     `json:"name"`
+	ObjectType         string     `json:"object_type"`
+	ObjectID           string     `json:"object_id"`
+	LastSuccessAt      *time.Time `json:"last_success_at"`
+	VerificationStatus string     `json:"verification_status"` // 最近一次成功备份的校验结果，空表示未校验
+	Stale              bool       `json:"stale"`
+}
+
+// LibraryRef 库的引用
+type LibraryRef struct {
+	LibraryType string `json:"library_type"`
+	LibraryID   string `json:"library_id"`
+	LibraryName string `json:"library_name"`
+}
+
+// RetentionCheck 保留期检查结果
+type RetentionCheck struct {
+	Name          string     `json:"name"`
+	RetentionDays int        `json:"retention_days"`
+	OverdueCount  int64      `json:"overdue_count"` // 超过保留期和一天清理宽限期仍未清理的记录
+	OldestAt      *time.Time `json:"oldest_at"`
+	Conformant    bool       `json:"conformant"`
+}
+
+// controlCatalog 证据包覆盖的控制项
+var controlCatalog = []Control{
+	{Standard: "个人信息保护法", Clause: "第六条", Title: "处理个人信息应当具有明确、合理的目的，限于实现处理目的的最小范围",
+		Sections: []string{SectionAccessAudit}},
+	{Standard: "个人信息保护法", Clause: "第十九条", Title: "个人信息的保存期限应当为实现处理目的所必要的最短时间",
+		Sections: []string{SectionRetention}},
+	{Standard: "个人信息保护法", Clause: "第五十一条", Title: "实行分类管理，采取加密、去标识化等安全技术措施",
+		Sections: []string{SectionSensitiveInventory, SectionMasking}},
+	{Standard: "个人信息保护法", Clause: "第五十四条", Title: "定期对处理个人信息遵守法律、行政法规的情况进行合规审计",
+		Sections: []string{SectionAccessAudit}},
+	{Standard: "等保2.0", Clause: "安全计算环境-安全审计", Title: "对重要的用户行为和重要安全事件进行审计",
+		Sections: []string{SectionAccessAudit}},
+	{Standard: "等保2.0", Clause: "安全计算环境-数据保密性", Title: "重要数据在存储和传输过程中的保密性",
+		Sections: []string{SectionMasking}},
+	{Standard: "等保2.0", Clause: "安全计算环境-数据备份恢复", Title: "提供重要数据的本地数据备份与恢复功能",
+		Sections: []string{SectionBackup}},
+	{Standard: "等保2.0", Clause: "安全计算环境-个人信息保护", Title: "仅采集和保存业务必需的用户个人信息，禁止未授权访问和非法使用",
+		Sections: []string{SectionSensitiveInventory, SectionMasking, SectionAccessAudit, SectionRetention}},
+}
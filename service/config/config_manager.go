@@ -36,6 +36,20 @@ const (
 	EnvPrefix = "DATAHUB_"
 )
 
+// 执行产物保留配置键，保留天数或条数为0时不按该维度清理，归档位置为空时清理前不归档
+const (
+	ConfigKeyBasicSyncLogRetentionCount = "basic_sync_log_retention_count"
+	ConfigKeyQualityExecRetentionDays   = "quality_execution_retention_days"
+	ConfigKeyQualityExecRetentionCount  = "quality_execution_retention_count"
+	ConfigKeySystemLogRetentionDays     = "system_log_retention_days"
+	ConfigKeySystemLogRetentionCount    = "system_log_retention_count"
+	ConfigKeyExecutionArchiveLocation   = "execution_archive_location"
+	ConfigKeyExecutionArchiveHeaders    = "execution_archive_headers"
+
+	DefaultQualityExecRetentionDays = 30
+	DefaultSystemLogRetentionDays   = 365
+)
+
 // ConfigManager 配置管理器（简化版）
 type ConfigManager struct {
 	db         *gorm.DB
@@ -48,6 +62,13 @@ type ConfigManager struct {
 var defaultConfigs = map[string]string{
 	ConfigKeyBasicSyncLogRetentionDays:    strconv.Itoa(DefaultBasicSyncLogRetentionDays),
 	ConfigKeyThematicSyncLogRetentionDays: strconv.Itoa(DefaultThematicSyncLogRetentionDays),
+	ConfigKeyBasicSyncLogRetentionCount:   "0",
+	ConfigKeyQualityExecRetentionDays:     strconv.Itoa(DefaultQualityExecRetentionDays),
+	ConfigKeyQualityExecRetentionCount:    "0",
+	ConfigKeySystemLogRetentionDays:       strconv.Itoa(DefaultSystemLogRetentionDays),
+	ConfigKeySystemLogRetentionCount:      "0",
+	ConfigKeyExecutionArchiveLocation:     "",
+	ConfigKeyExecutionArchiveHeaders:      "",
 }
 
 // NewConfigManager 创建配置管理器实例
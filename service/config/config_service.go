@@ -88,9 +88,26 @@ func (s *ConfigService) GetAllSystemConfigs() ([]models.SystemConfigItem, error)
 		})
 	}
 
+	for _, item := range retentionConfigItems {
+		if !existingKeys[item.Key] {
+			items = append(items, item)
+		}
+	}
+
 	return items, nil
 }
 
+// retentionConfigItems 执行产物保留相关配置的默认项
+var retentionConfigItems = []models.SystemConfigItem{
+	{Key: ConfigKeyBasicSyncLogRetentionCount, Value: "0", Description: "每个基础库同步任务最多保留的执行记录条数，0表示不限", ValueType: "int"},
+	{Key: ConfigKeyQualityExecRetentionDays, Value: strconv.Itoa(DefaultQualityExecRetentionDays), Description: "质量检测任务执行记录保存天数，0表示不按天数清理", ValueType: "int"},
+	{Key: ConfigKeyQualityExecRetentionCount, Value: "0", Description: "每个质量检测任务最多保留的执行记录条数，0表示不限", ValueType: "int"},
+	{Key: ConfigKeySystemLogRetentionDays, Value: strconv.Itoa(DefaultSystemLogRetentionDays), Description: "系统操作日志保存天数，0表示不按天数清理", ValueType: "int"},
+	{Key: ConfigKeySystemLogRetentionCount, Value: "0", Description: "系统操作日志最多保留条数，0表示不限", ValueType: "int"},
+	{Key: ConfigKeyExecutionArchiveLocation, Value: "", Description: "清理前归档执行记录的位置，http(s)地址或本地目录，支持secretstore://引用，为空时不归档", ValueType: "string"},
+	{Key: ConfigKeyExecutionArchiveHeaders, Value: "", Description: "上传归档文件时附带的请求头，JSON对象，值支持secretstore://引用", ValueType: "string"},
+}
+
 // GetBasicSyncLogRetentionDays 获取基础库同步日志保留天数
 func (s *ConfigService) GetBasicSyncLogRetentionDays() (int, error) {
 	valueStr, err := s.manager.GetConfig(ConfigKeyBasicSyncLogRetentionDays)
@@ -121,6 +138,21 @@ func (s *ConfigService) GetThematicSyncLogRetentionDays() (int, error) {
 	return value, nil
 }
 
+// GetIntConfig 获取整数配置，读取或解析失败时返回默认值
+func (s *ConfigService) GetIntConfig(key string, defaultValue int) int {
+	valueStr, err := s.manager.GetConfig(key)
+	if err != nil || valueStr == "" {
+		return defaultValue
+	}
+
+	value, err := strconv.Atoi(valueStr)
+	if err != nil {
+		return defaultValue
+	}
+
+	return value
+}
+
 // ClearCache 清除配置缓存
 func (s *ConfigService) ClearCache() {
 	s.manager.ClearCache()
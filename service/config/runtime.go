@@ -0,0 +1,351 @@
+/*
+ * @module service/config/runtime
+ * @description 运行时配置，集中管理同步批量大小、批次上限、批量大小自适应调整、数据源熔断、预览条数等调优参数，支持数据库和环境变量覆盖并在运行中热加载
+ * @architecture 分层架构 - 业务服务层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow 启动加载 -> 快照(原子替换) -> 业务代码按键读取；PUT更新数据库 -> 立即重新加载；各实例定时重新加载以获取其他实例的修改
+ * @rules 优先级：数据库 > 环境变量(DATAHUB_前缀) > 默认值；超出取值范围的覆盖值被忽略并回退到下一来源；未加载时读取默认值
+ * @dependencies datahub-service/service/models, gorm.io/gorm
+ * @refs service/config/config_manager.go, api/controllers/runtime_config_controller.go, service/interface_executor/execute_operations.go
+ */
+
+package config
+
+import (
+	"context"
+	"datahub-service/service/models"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// 运行时配置键
+const (
+	ConfigKeySyncDefaultBatchSize = "sync_default_batch_size"
+	ConfigKeySyncMaxBatchSize     = "sync_max_batch_size"
+	ConfigKeySyncMaxBatches       = "sync_max_batches"
+	ConfigKeySyncPipelineBuffer   = "sync_pipeline_buffer"
+	ConfigKeySyncAutoTune         = "sync_auto_tune"
+	ConfigKeySyncTuneMinBatchSize = "sync_tune_min_batch_size"
+	ConfigKeySyncTuneTargetMs     = "sync_tune_target_latency_ms"
+	ConfigKeyCircuitThreshold     = "datasource_circuit_threshold"
+	ConfigKeyCircuitCooldown      = "datasource_circuit_cooldown_seconds"
+	ConfigKeyPreviewDefaultLimit  = "preview_default_limit"
+	ConfigKeyPreviewMaxLimit      = "preview_max_limit"
+	ConfigKeyWorkbenchMaxRows     = "workbench_max_rows"
+	ConfigKeyWorkbenchTimeout     = "workbench_statement_timeout_seconds"
+)
+
+// 运行时配置来源
+const (
+	RuntimeSourceDatabase = "database"
+	RuntimeSourceEnv      = "env"
+	RuntimeSourceDefault  = "default"
+)
+
+// RuntimeReloadInterval 各实例定时重新加载运行时配置的间隔
+const RuntimeReloadInterval = 30 * time.Second
+
+// ErrInvalidRuntimeConfig 运行时配置键不存在或取值不合法
+var ErrInvalidRuntimeConfig = errors.New("运行时配置不合法")
+
+// RuntimeSetting 运行时配置项定义
+type RuntimeSetting struct {
+	Key         string `json:"key" example:"sync_default_batch_size"`
+	Description string `json:"description" example:"批量同步默认每批条数"`
+	Default     int    `json:"default" example:"1000"`
+	Min         int    `json:"min" example:"1"`
+	Max         int    `json:"max" example:"100000"`
+}
+
+// RuntimeValue 运行时配置项的当前生效值
+type RuntimeValue struct {
+	RuntimeSetting
+	Value  int    `json:"value" example:"1000"`
+	Source string `json:"source" example:"default"` // database, env, default
+}
+
+// RuntimeConfig 运行时配置快照
+type RuntimeConfig struct {
+	Values   []RuntimeValue `json:"values"`
+	LoadedAt time.Time      `json:"loaded_at"`
+}
+
+// runtimeSettings 全部运行时配置项，按展示顺序排列
+var runtimeSettings = []RuntimeSetting{
+	{Key: ConfigKeySyncDefaultBatchSize, Description: "批量同步默认每批条数，接口未配置default_limit时使用", Default: 1000, Min: 1, Max: 100000},
+	{Key: ConfigKeySyncMaxBatchSize, Description: "批量同步每批条数上限，接口未配置max_limit时使用", Default: 10000, Min: 1, Max: 100000},
+	{Key: ConfigKeySyncMaxBatches, Description: "单次同步最多拉取的批次数，防止无限循环", Default: 1000, Min: 1, Max: 100000},
+	{Key: ConfigKeySyncPipelineBuffer, Description: "批量同步写入阶段前缓冲的批次数，写入慢于拉取时拉取阶段等待", Default: 2, Min: 1, Max: 32},
+	{Key: ConfigKeySyncAutoTune, Description: "批量同步是否按耗时和错误自适应调整页大小和写入批大小，1开启，0关闭，接口limit_config.auto_tune优先", Default: 0, Min: 0, Max: 1},
+	{Key: ConfigKeySyncTuneMinBatchSize, Description: "自适应调整时每批条数下限，接口未配置min_limit时使用，上限为max_limit", Default: 100, Min: 1, Max: 100000},
+	{Key: ConfigKeySyncTuneTargetMs, Description: "自适应调整的目标耗时(毫秒)，单次拉取或写入不超过该耗时时增大批量，超过或出错时减半", Default: 2000, Min: 10, Max: 600000},
+	{Key: ConfigKeyCircuitThreshold, Description: "数据源连续拉取失败多少次后熔断，熔断期间依赖的同步任务不执行，0表示不熔断", Default: 5, Min: 0, Max: 100},
+	{Key: ConfigKeyCircuitCooldown, Description: "数据源熔断的冷却秒数，冷却结束后先探测数据源连接，探测成功才恢复执行", Default: 300, Min: 10, Max: 86400},
+	{Key: ConfigKeyPreviewDefaultLimit, Description: "数据预览和接口测试默认返回条数", Default: 10, Min: 1, Max: 10000},
+	{Key: ConfigKeyPreviewMaxLimit, Description: "数据预览和接口测试最多返回条数", Default: 1000, Min: 1, Max: 10000},
+	{Key: ConfigKeyWorkbenchMaxRows, Description: "SQL工作台单次查询最多返回行数，超出部分截断", Default: 1000, Min: 1, Max: 10000},
+	{Key: ConfigKeyWorkbenchTimeout, Description: "SQL工作台语句超时秒数", Default: 30, Min: 1, Max: 600},
+}
+
+var runtimeSnapshot atomic.Pointer[RuntimeConfig]
+
+// RuntimeSettings 返回全部运行时配置项定义
+func RuntimeSettings() []RuntimeSetting {
+	return append([]RuntimeSetting(nil), runtimeSettings...)
+}
+
+// findRuntimeSetting 按键查找配置项定义
+func findRuntimeSetting(key string) (RuntimeSetting, bool) {
+	for _, setting := range runtimeSettings {
+		if setting.Key == key {
+			return setting, true
+		}
+	}
+	return RuntimeSetting{}, false
+}
+
+// RuntimeInt 读取运行时配置当前值，未加载或键不存在时返回默认值
+func RuntimeInt(key string) int {
+	if snapshot := runtimeSnapshot.Load(); snapshot != nil {
+		for _, value := range snapshot.Values {
+			if value.Key == key {
+				return value.Value
+			}
+		}
+	}
+	setting, _ := findRuntimeSetting(key)
+	return setting.Default
+}
+
+// SyncDefaultBatchSize 批量同步默认每批条数
+func SyncDefaultBatchSize() int { return RuntimeInt(ConfigKeySyncDefaultBatchSize) }
+
+// SyncMaxBatchSize 批量同步每批条数上限
+func SyncMaxBatchSize() int { return RuntimeInt(ConfigKeySyncMaxBatchSize) }
+
+// SyncMaxBatches 单次同步最多拉取的批次数
+func SyncMaxBatches() int { return RuntimeInt(ConfigKeySyncMaxBatches) }
+
+// SyncPipelineBuffer 批量同步流水线缓冲的批次数
+func SyncPipelineBuffer() int { return RuntimeInt(ConfigKeySyncPipelineBuffer) }
+
+// SyncAutoTuneEnabled 批量同步是否默认开启批量大小自适应调整
+func SyncAutoTuneEnabled() bool { return RuntimeInt(ConfigKeySyncAutoTune) == 1 }
+
+// SyncTuneMinBatchSize 自适应调整时每批条数下限
+func SyncTuneMinBatchSize() int { return RuntimeInt(ConfigKeySyncTuneMinBatchSize) }
+
+// SyncTuneTargetLatency 自适应调整的目标耗时
+func SyncTuneTargetLatency() time.Duration {
+	return time.Duration(RuntimeInt(ConfigKeySyncTuneTargetMs)) * time.Millisecond
+}
+
+// DataSourceCircuitThreshold 数据源连续拉取失败多少次后熔断，0表示不熔断
+func DataSourceCircuitThreshold() int { return RuntimeInt(ConfigKeyCircuitThreshold) }
+
+// DataSourceCircuitCooldown 数据源熔断的冷却时间
+func DataSourceCircuitCooldown() time.Duration {
+	return time.Duration(RuntimeInt(ConfigKeyCircuitCooldown)) * time.Second
+}
+
+// PreviewDefaultLimit 数据预览默认返回条数
+func PreviewDefaultLimit() int { return RuntimeInt(ConfigKeyPreviewDefaultLimit) }
+
+// PreviewMaxLimit 数据预览最多返回条数
+func PreviewMaxLimit() int { return RuntimeInt(ConfigKeyPreviewMaxLimit) }
+
+// WorkbenchMaxRows SQL工作台单次查询最多返回行数
+func WorkbenchMaxRows() int { return RuntimeInt(ConfigKeyWorkbenchMaxRows) }
+
+// WorkbenchStatementTimeout SQL工作台语句超时时间
+func WorkbenchStatementTimeout() time.Duration {
+	return time.Duration(RuntimeInt(ConfigKeyWorkbenchTimeout)) * time.Second
+}
+
+// GetRuntimeConfig 获取当前生效的运行时配置
+func (s *ConfigService) GetRuntimeConfig() *RuntimeConfig {
+	if snapshot := runtimeSnapshot.Load(); snapshot != nil {
+		return snapshot
+	}
+	return buildRuntimeConfig(nil)
+}
+
+// ReloadRuntimeConfig 从数据库和环境变量重新加载运行时配置并原子替换快照
+func (s *ConfigService) ReloadRuntimeConfig() (*RuntimeConfig, error) {
+	keys := make([]string, 0, len(runtimeSettings))
+	for _, setting := range runtimeSettings {
+		keys = append(keys, setting.Key)
+	}
+
+	var rows []models.SystemConfig
+	if err := s.db.Where("key IN ? AND environment = ?", keys, "default").Find(&rows).Error; err != nil {
+		return nil, fmt.Errorf("加载运行时配置失败: %w", err)
+	}
+
+	overrides := make(map[string]string, len(rows))
+	for _, row := range rows {
+		overrides[row.Key] = row.Value
+	}
+
+	snapshot := buildRuntimeConfig(overrides)
+	if previous := runtimeSnapshot.Swap(snapshot); previous != nil {
+		logRuntimeChanges(previous, snapshot)
+	}
+	return snapshot, nil
+}
+
+// UpdateRuntimeConfig 校验并保存运行时配置，保存成功后立即重新加载
+func (s *ConfigService) UpdateRuntimeConfig(values map[string]int, operator string) (*RuntimeConfig, error) {
+	if len(values) == 0 {
+		return nil, fmt.Errorf("%w: 未指定要修改的配置项", ErrInvalidRuntimeConfig)
+	}
+
+	merged := make(map[string]int, len(runtimeSettings))
+	for _, value := range s.GetRuntimeConfig().Values {
+		merged[value.Key] = value.Value
+	}
+	for key, value := range values {
+		setting, ok := findRuntimeSetting(key)
+		if !ok {
+			return nil, fmt.Errorf("%w: 配置项%s不存在", ErrInvalidRuntimeConfig, key)
+		}
+		if value < setting.Min || value > setting.Max {
+			return nil, fmt.Errorf("%w: %s取值范围为%d-%d", ErrInvalidRuntimeConfig, key, setting.Min, setting.Max)
+		}
+		merged[key] = value
+	}
+	if err := validateRuntimeRelations(merged); err != nil {
+		return nil, err
+	}
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		for key, value := range values {
+			if err := upsertRuntimeValue(tx, key, value); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("保存运行时配置失败: %w", err)
+	}
+
+	s.manager.ClearCache()
+	slog.Info("运行时配置已更新", "operator", operator, "values", values)
+	return s.ReloadRuntimeConfig()
+}
+
+// StartRuntimeReload 启动定时重新加载，使多实例部署时其他实例的修改在一个周期内生效
+func (s *ConfigService) StartRuntimeReload(ctx context.Context, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				if _, err := s.ReloadRuntimeConfig(); err != nil {
+					slog.Warn("定时重新加载运行时配置失败", "error", err)
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+// upsertRuntimeValue 写入或更新一个配置项
+func upsertRuntimeValue(tx *gorm.DB, key string, value int) error {
+	setting, _ := findRuntimeSetting(key)
+	now := time.Now()
+
+	var row models.SystemConfig
+	err := tx.Where("key = ? AND environment = ?", key, "default").First(&row).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return tx.Create(&models.SystemConfig{
+			ID:          uuid.New().String(),
+			Key:         key,
+			Value:       strconv.Itoa(value),
+			Environment: "default",
+			Description: setting.Description,
+			CreatedAt:   now,
+			UpdatedAt:   now,
+		}).Error
+	}
+	if err != nil {
+		return err
+	}
+
+	return tx.Model(&row).Updates(map[string]interface{}{
+		"value":      strconv.Itoa(value),
+		"updated_at": now,
+	}).Error
+}
+
+// validateRuntimeRelations 校验配置项之间的约束
+func validateRuntimeRelations(values map[string]int) error {
+	if values[ConfigKeySyncDefaultBatchSize] > values[ConfigKeySyncMaxBatchSize] {
+		return fmt.Errorf("%w: %s不能大于%s", ErrInvalidRuntimeConfig, ConfigKeySyncDefaultBatchSize, ConfigKeySyncMaxBatchSize)
+	}
+	if values[ConfigKeySyncTuneMinBatchSize] > values[ConfigKeySyncMaxBatchSize] {
+		return fmt.Errorf("%w: %s不能大于%s", ErrInvalidRuntimeConfig, ConfigKeySyncTuneMinBatchSize, ConfigKeySyncMaxBatchSize)
+	}
+	if values[ConfigKeyPreviewDefaultLimit] > values[ConfigKeyPreviewMaxLimit] {
+		return fmt.Errorf("%w: %s不能大于%s", ErrInvalidRuntimeConfig, ConfigKeyPreviewDefaultLimit, ConfigKeyPreviewMaxLimit)
+	}
+	return nil
+}
+
+// buildRuntimeConfig 按数据库 > 环境变量 > 默认值的优先级计算各配置项
+func buildRuntimeConfig(overrides map[string]string) *RuntimeConfig {
+	values := make([]RuntimeValue, 0, len(runtimeSettings))
+	for _, setting := range runtimeSettings {
+		value := RuntimeValue{RuntimeSetting: setting, Value: setting.Default, Source: RuntimeSourceDefault}
+
+		if raw, ok := overrides[setting.Key]; ok {
+			if parsed, ok := parseRuntimeValue(setting, raw, RuntimeSourceDatabase); ok {
+				value.Value, value.Source = parsed, RuntimeSourceDatabase
+			}
+		}
+		if value.Source == RuntimeSourceDefault {
+			if raw := os.Getenv(EnvPrefix + convertToEnvKey(setting.Key)); raw != "" {
+				if parsed, ok := parseRuntimeValue(setting, raw, RuntimeSourceEnv); ok {
+					value.Value, value.Source = parsed, RuntimeSourceEnv
+				}
+			}
+		}
+		values = append(values, value)
+	}
+	return &RuntimeConfig{Values: values, LoadedAt: time.Now()}
+}
+
+// parseRuntimeValue 解析覆盖值，非整数或超出范围时忽略
+func parseRuntimeValue(setting RuntimeSetting, raw, source string) (int, bool) {
+	parsed, err := strconv.Atoi(strings.TrimSpace(raw))
+	if err != nil || parsed < setting.Min || parsed > setting.Max {
+		slog.Warn("运行时配置值不合法，已忽略", "key", setting.Key, "value", raw, "source", source)
+		return 0, false
+	}
+	return parsed, true
+}
+
+// logRuntimeChanges 记录重新加载后发生变化的配置项
+func logRuntimeChanges(previous, current *RuntimeConfig) {
+	old := make(map[string]int, len(previous.Values))
+	for _, value := range previous.Values {
+		old[value.Key] = value.Value
+	}
+	for _, value := range current.Values {
+		if before, ok := old[value.Key]; ok && before != value.Value {
+			slog.Info("运行时配置已生效", "key", value.Key, "old_value", before, "new_value", value.Value, "source", value.Source)
+		}
+	}
+}
@@ -0,0 +1,119 @@
+/*
+ * @module service/config/runtime_test
+ * @description 运行时配置测试，覆盖默认值、环境变量与数据库覆盖优先级、取值校验和更新后热加载
+ * @architecture 测试层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow 准备sqlite和环境变量 -> 加载/更新运行时配置 -> 验证生效值和来源
+ * @rules 使用内存sqlite，不依赖外部服务；每个用例结束后清空全局快照
+ * @dependencies gorm.io/driver/sqlite, stretchr/testify
+ * @refs service/config/runtime.go
+ */
+
+package config
+
+import (
+	"datahub-service/service/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func setupRuntimeConfigService(t *testing.T) *ConfigService {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&models.SystemConfig{}))
+
+	t.Cleanup(func() { runtimeSnapshot.Store(nil) })
+	return NewConfigService(db)
+}
+
+func runtimeValue(t *testing.T, cfg *RuntimeConfig, key string) RuntimeValue {
+	t.Helper()
+	for _, value := range cfg.Values {
+		if value.Key == key {
+			return value
+		}
+	}
+	t.Fatalf("配置项%s不存在", key)
+	return RuntimeValue{}
+}
+
+func TestRuntimeConfigDefaults(t *testing.T) {
+	runtimeSnapshot.Store(nil)
+	assert.Equal(t, 1000, SyncDefaultBatchSize())
+	assert.Equal(t, 10000, SyncMaxBatchSize())
+	assert.Equal(t, 1000, SyncMaxBatches())
+	assert.Equal(t, 10, PreviewDefaultLimit())
+	assert.Equal(t, 1000, PreviewMaxLimit())
+}
+
+func TestRuntimeConfigPriority(t *testing.T) {
+	s := setupRuntimeConfigService(t)
+
+	t.Setenv("DATAHUB_SYNC_MAX_BATCHES", "50")
+	t.Setenv("DATAHUB_SYNC_MAX_BATCH_SIZE", "2000")
+	// 超出范围的环境变量被忽略
+	t.Setenv("DATAHUB_PREVIEW_MAX_LIMIT", "999999")
+	require.NoError(t, s.db.Create(&models.SystemConfig{
+		ID: "cfg-1", Key: ConfigKeySyncMaxBatchSize, Value: "3000", Environment: "default",
+	}).Error)
+
+	cfg, err := s.ReloadRuntimeConfig()
+	require.NoError(t, err)
+
+	// 数据库优先于环境变量
+	assert.Equal(t, RuntimeSourceDatabase, runtimeValue(t, cfg, ConfigKeySyncMaxBatchSize).Source)
+	assert.Equal(t, 3000, SyncMaxBatchSize())
+	assert.Equal(t, RuntimeSourceEnv, runtimeValue(t, cfg, ConfigKeySyncMaxBatches).Source)
+	assert.Equal(t, 50, SyncMaxBatches())
+	assert.Equal(t, RuntimeSourceDefault, runtimeValue(t, cfg, ConfigKeyPreviewMaxLimit).Source)
+	assert.Equal(t, 1000, PreviewMaxLimit())
+}
+
+func TestUpdateRuntimeConfig(t *testing.T) {
+	s := setupRuntimeConfigService(t)
+	_, err := s.ReloadRuntimeConfig()
+	require.NoError(t, err)
+
+	cfg, err := s.UpdateRuntimeConfig(map[string]int{ConfigKeySyncDefaultBatchSize: 500, ConfigKeyPreviewDefaultLimit: 20}, "admin")
+	require.NoError(t, err)
+	assert.Equal(t, RuntimeSourceDatabase, runtimeValue(t, cfg, ConfigKeySyncDefaultBatchSize).Source)
+	// 更新后无需重启立即生效
+	assert.Equal(t, 500, SyncDefaultBatchSize())
+	assert.Equal(t, 20, PreviewDefaultLimit())
+
+	// 再次更新走更新分支
+	_, err = s.UpdateRuntimeConfig(map[string]int{ConfigKeySyncDefaultBatchSize: 800}, "admin")
+	require.NoError(t, err)
+	assert.Equal(t, 800, SyncDefaultBatchSize())
+
+	var count int64
+	s.db.Model(&models.SystemConfig{}).Where("key = ?", ConfigKeySyncDefaultBatchSize).Count(&count)
+	assert.Equal(t, int64(1), count)
+}
+
+func TestUpdateRuntimeConfigValidation(t *testing.T) {
+	s := setupRuntimeConfigService(t)
+
+	cases := []map[string]int{
+		{},
+		{"unknown_key": 1},
+		{ConfigKeySyncMaxBatches: 0},
+		{ConfigKeySyncDefaultBatchSize: 20000},
+		{ConfigKeyPreviewDefaultLimit: 500, ConfigKeyPreviewMaxLimit: 100},
+	}
+	for _, values := range cases {
+		_, err := s.UpdateRuntimeConfig(values, "admin")
+		assert.ErrorIs(t, err, ErrInvalidRuntimeConfig, values)
+	}
+
+	var count int64
+	s.db.Model(&models.SystemConfig{}).Count(&count)
+	assert.Equal(t, int64(0), count)
+}
@@ -0,0 +1,170 @@
+/*
+ * @module service/database/computed_columns
+ * @description 派生列支持，字段配置中带expression的字段按compute_mode建为PostgreSQL生成列或同步后刷新的普通列
+ * @architecture 分层架构 - 业务服务层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow 字段配置 -> 校验表达式和引用关系 -> 生成列: GENERATED ALWAYS AS (expr) STORED；同步刷新列: 普通列 -> 每次同步写入后 UPDATE SET col = (expr)
+ * @rules 表达式只能引用同表的列，不允许多条语句和注释；派生列不能作为主键或增量字段；生成列不能设置默认值，且不能引用其他派生列（PostgreSQL不允许生成列引用生成列，
+ *        同步刷新列在写入后才计算）；同步刷新列可以引用生成列；已有生成列的表达式变化时删除后重建该列，由数据库重新计算
+ * @dependencies datahub-service/service/models
+ * @refs service/database/schema_service.go, service/interface_executor/bulk_insert.go, service/thematic_library/thematic_sync/data_writer.go
+ */
+
+package database
+
+import (
+	"datahub-service/service/models"
+	"datahub-service/service/utils"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var (
+	// expressionLiteralPattern 表达式中的字符串字面量，提取列引用前先去掉
+	expressionLiteralPattern = regexp.MustCompile(`'(?:[^']|'')*'`)
+	// expressionIdentifierPattern 表达式中的标识符，包括带双引号的标识符
+	expressionIdentifierPattern = regexp.MustCompile(`"(?:[^"]|"")+"|[A-Za-z_][A-Za-z0-9_]*`)
+	// expressionCastPattern PostgreSQL回显生成列表达式时附加的类型转换
+	expressionCastPattern = regexp.MustCompile(`::[A-Za-z_ ]+`)
+)
+
+// ValidateComputedFields 校验字段配置中的派生列
+func ValidateComputedFields(fields []models.TableField) error {
+	byName := make(map[string]models.TableField, len(fields))
+	for _, field := range fields {
+		byName[field.NameEn] = field
+	}
+
+	for _, field := range fields {
+		if !field.IsComputed() {
+			if field.ComputeMode != "" {
+				return fmt.Errorf("字段 %s 配置了compute_mode但没有expression", field.NameEn)
+			}
+			continue
+		}
+
+		mode := field.EffectiveComputeMode()
+		if mode != models.ComputeModeGenerated && mode != models.ComputeModeSync {
+			return fmt.Errorf("字段 %s 的派生列计算方式 %s 不支持，可选值: %s, %s", field.NameEn, field.ComputeMode, models.ComputeModeGenerated, models.ComputeModeSync)
+		}
+		if strings.Contains(field.Expression, ";") || strings.Contains(field.Expression, "--") || strings.Contains(field.Expression, "/*") {
+			return fmt.Errorf("字段 %s 的派生列表达式不能包含分号或注释", field.NameEn)
+		}
+		if field.IsPrimaryKey || field.IsIncrementField {
+			return fmt.Errorf("派生列 %s 不能作为主键或增量字段", field.NameEn)
+		}
+		if mode == models.ComputeModeGenerated && field.DefaultValue != "" {
+			return fmt.Errorf("生成列 %s 不能设置默认值", field.NameEn)
+		}
+
+		for _, ref := range ExpressionColumnRefs(field.Expression) {
+			if ref == field.NameEn {
+				return fmt.Errorf("派生列 %s 的表达式不能引用自身", field.NameEn)
+			}
+			referenced, ok := byName[ref]
+			if !ok || !referenced.IsComputed() {
+				continue
+			}
+			if mode == models.ComputeModeGenerated || referenced.EffectiveComputeMode() == models.ComputeModeSync {
+				return fmt.Errorf("派生列 %s 不能引用派生列 %s，只有同步刷新列可以引用生成列", field.NameEn, ref)
+			}
+		}
+	}
+	return nil
+}
+
+// ExpressionColumnRefs 提取表达式中可能引用的列名，跳过函数调用，关键字也会返回，由调用方按字段配置过滤
+func ExpressionColumnRefs(expression string) []string {
+	stripped := expressionLiteralPattern.ReplaceAllString(expression, "''")
+	var refs []string
+	seen := make(map[string]bool)
+	for _, loc := range expressionIdentifierPattern.FindAllStringIndex(stripped, -1) {
+		token := stripped[loc[0]:loc[1]]
+		if strings.HasPrefix(token, `"`) {
+			token = strings.ReplaceAll(token[1:len(token)-1], `""`, `"`)
+		} else if strings.HasPrefix(strings.TrimLeft(stripped[loc[1]:], " \t\n"), "(") {
+			continue
+		}
+		if !seen[token] {
+			seen[token] = true
+			refs = append(refs, token)
+		}
+	}
+	return refs
+}
+
+// SyncComputedFields 需要在同步写入后刷新的派生列
+func SyncComputedFields(fields []models.TableField) []models.TableField {
+	var computed []models.TableField
+	for _, field := range fields {
+		if field.IsComputed() && field.EffectiveComputeMode() == models.ComputeModeSync {
+			computed = append(computed, field)
+		}
+	}
+	return computed
+}
+
+// BuildComputedRefreshSQL 构建刷新同步刷新列的UPDATE语句，只更新值有变化的行；没有需要刷新的列时返回空字符串
+func BuildComputedRefreshSQL(fullTableName string, fields []models.TableField) string {
+	computed := SyncComputedFields(fields)
+	if len(computed) == 0 {
+		return ""
+	}
+
+	assignments := make([]string, len(computed))
+	conditions := make([]string, len(computed))
+	for i, field := range computed {
+		column := utils.QuoteIdent(field.NameEn)
+		assignments[i] = fmt.Sprintf("%s = (%s)", column, field.Expression)
+		conditions[i] = fmt.Sprintf("%s IS DISTINCT FROM (%s)", column, field.Expression)
+	}
+	return fmt.Sprintf("UPDATE %s SET %s WHERE %s",
+		fullTableName,
+		strings.Join(assignments, ", "),
+		strings.Join(conditions, " OR "),
+	)
+}
+
+// generatedExpressionChanged 比较配置的表达式与数据库中生成列的表达式，忽略空白、括号、引号、大小写和数据库附加的类型转换
+func generatedExpressionChanged(configured, current string) bool {
+	return normalizeExpression(configured) != normalizeExpression(current)
+}
+
+// normalizeExpression 规范化表达式用于比较
+func normalizeExpression(expression string) string {
+	normalized := expressionCastPattern.ReplaceAllString(expression, "")
+	normalized = strings.Map(func(r rune) rune {
+		switch r {
+		case ' ', '\t', '\n', '\r', '(', ')', '"':
+			return -1
+		}
+		return r
+	}, normalized)
+	return strings.ToLower(normalized)
+}
+
+// computedColumnNeedsRebuild 已有列的派生方式或生成列表达式与配置不一致，需要删除后重建
+func computedColumnNeedsRebuild(field models.TableField, col ColumnDefinition) bool {
+	wantGenerated := field.IsComputed() && field.EffectiveComputeMode() == models.ComputeModeGenerated
+	if wantGenerated != col.IsGenerated {
+		return true
+	}
+	return wantGenerated && generatedExpressionChanged(field.Expression, col.GenerationExpression)
+}
+
+// computedFieldsLast 普通字段在前、派生列在后，各自保持原有顺序
+func computedFieldsLast(fields []models.TableField) []models.TableField {
+	ordered := make([]models.TableField, 0, len(fields))
+	for _, field := range fields {
+		if !field.IsComputed() {
+			ordered = append(ordered, field)
+		}
+	}
+	for _, field := range fields {
+		if field.IsComputed() {
+			ordered = append(ordered, field)
+		}
+	}
+	return ordered
+}
@@ -0,0 +1,95 @@
+/*
+ * @module service/database/computed_columns_test
+ * @description 派生列测试，覆盖配置校验、列引用提取、生成列定义、同步刷新语句和生成列表达式变化判断
+ * @architecture 测试层 - 单元测试
+ */
+
+package database
+
+import (
+	"datahub-service/service/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestValidateComputedFields 测试派生列配置校验
+func TestValidateComputedFields(t *testing.T) {
+	base := []models.TableField{
+		{NameEn: "id", IsPrimaryKey: true},
+		{NameEn: "length"},
+		{NameEn: "width"},
+		{NameEn: "birthdate"},
+	}
+	with := func(extra ...models.TableField) []models.TableField {
+		return append(append([]models.TableField{}, base...), extra...)
+	}
+
+	assert.NoError(t, ValidateComputedFields(with(
+		models.TableField{NameEn: "area", Expression: "length * width"},
+		models.TableField{NameEn: "age", Expression: "date_part('year', age(birthdate))", ComputeMode: models.ComputeModeSync},
+		models.TableField{NameEn: "area_m2", Expression: "area / 10000", ComputeMode: models.ComputeModeSync},
+	)))
+
+	cases := map[string]models.TableField{
+		"未知计算方式":    {NameEn: "area", Expression: "length * width", ComputeMode: "virtual"},
+		"只有计算方式":    {NameEn: "area", ComputeMode: models.ComputeModeSync},
+		"多条语句":      {NameEn: "area", Expression: "1; DROP TABLE x"},
+		"引用自身":      {NameEn: "area", Expression: "area + 1", ComputeMode: models.ComputeModeSync},
+		"生成列默认值":    {NameEn: "area", Expression: "length * width", DefaultValue: "0"},
+		"派生列作为增量字段": {NameEn: "area", Expression: "length * width", IsIncrementField: true},
+	}
+	for name, field := range cases {
+		assert.Error(t, ValidateComputedFields(with(field)), name)
+	}
+
+	// 生成列不能引用其他派生列，同步刷新列不能引用同步刷新列
+	assert.Error(t, ValidateComputedFields(with(
+		models.TableField{NameEn: "area", Expression: "length * width"},
+		models.TableField{NameEn: "area_m2", Expression: "area / 10000"},
+	)))
+	assert.Error(t, ValidateComputedFields(with(
+		models.TableField{NameEn: "age", Expression: "age(birthdate)", ComputeMode: models.ComputeModeSync},
+		models.TableField{NameEn: "age_text", Expression: "age::text", ComputeMode: models.ComputeModeSync},
+	)))
+}
+
+// TestExpressionColumnRefs 测试提取表达式中的列引用，字符串字面量中的内容不算引用
+func TestExpressionColumnRefs(t *testing.T) {
+	assert.Equal(t, []string{"length", "width"}, ExpressionColumnRefs("length * width"))
+	assert.Equal(t, []string{"Full Name", "name"},
+		ExpressionColumnRefs(`coalesce("Full Name", name, 'name unknown')`))
+}
+
+// TestBuildComputedColumnDefinition 测试生成列定义和同步刷新列定义
+func TestBuildComputedColumnDefinition(t *testing.T) {
+	s := &SchemaService{}
+	assert.Equal(t, `"area" numeric GENERATED ALWAYS AS (length * width) STORED`,
+		s.buildColumnDefinition(models.TableField{NameEn: "area", DataType: "numeric", IsNullable: true, Expression: "length * width"}))
+	assert.Equal(t, `"age" integer`,
+		s.buildColumnDefinition(models.TableField{NameEn: "age", DataType: "integer", IsNullable: true, Expression: "age(birthdate)", ComputeMode: models.ComputeModeSync}))
+}
+
+// TestBuildComputedRefreshSQL 测试同步刷新列的UPDATE语句
+func TestBuildComputedRefreshSQL(t *testing.T) {
+	fields := []models.TableField{
+		{NameEn: "area", Expression: "length * width"},
+		{NameEn: "age", Expression: "date_part('year', age(birthdate))", ComputeMode: models.ComputeModeSync},
+	}
+	assert.Equal(t,
+		`UPDATE "s"."t" SET "age" = (date_part('year', age(birthdate))) WHERE "age" IS DISTINCT FROM (date_part('year', age(birthdate)))`,
+		BuildComputedRefreshSQL(`"s"."t"`, fields))
+	assert.Empty(t, BuildComputedRefreshSQL(`"s"."t"`, fields[:1]))
+}
+
+// TestComputedColumnNeedsRebuild 测试已有列是否需要按新的派生配置重建
+func TestComputedColumnNeedsRebuild(t *testing.T) {
+	generated := models.TableField{NameEn: "area", Expression: "length * width"}
+	assert.False(t, computedColumnNeedsRebuild(generated, ColumnDefinition{IsGenerated: true, GenerationExpression: "(length * width)"}))
+	assert.False(t, computedColumnNeedsRebuild(models.TableField{NameEn: "label", Expression: "name || 'x'"},
+		ColumnDefinition{IsGenerated: true, GenerationExpression: "((name)::text || 'x'::text)"}))
+	assert.True(t, computedColumnNeedsRebuild(generated, ColumnDefinition{IsGenerated: true, GenerationExpression: "(length + width)"}))
+	assert.True(t, computedColumnNeedsRebuild(generated, ColumnDefinition{}))
+	assert.True(t, computedColumnNeedsRebuild(models.TableField{NameEn: "area"}, ColumnDefinition{IsGenerated: true}))
+	assert.False(t, computedColumnNeedsRebuild(models.TableField{NameEn: "age", Expression: "age(x)", ComputeMode: models.ComputeModeSync}, ColumnDefinition{}))
+}
@@ -0,0 +1,271 @@
+/*
+ * @module service/database/field_indexes
+ * @description 字段配置声明的索引维护，根据表字段配置中的is_indexed、is_unique和composite_index标记创建、重建和删除接口表索引
+ * @architecture 分层架构 - 业务服务层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow 字段配置 -> 规划期望索引 -> 对比表上已有的托管索引 -> 创建缺失/重建字段变化的索引 -> 删除不再声明的托管索引；修改表时同步单字段唯一约束
+ * @rules 只维护fieldIndexPrefix前缀的托管索引，不触碰手工或查询性能分析创建的索引；已有表上的索引使用CONCURRENTLY创建和删除，避免长时间锁表阻塞同步和共享接口读写；CONCURRENTLY创建失败会留下无效索引，需要立即删除；单字段唯一通过唯一约束实现，已有表先并发建唯一索引再挂为约束
+ * @dependencies datahub-service/service/models, gorm.io/gorm
+ * @refs service/database/schema_service.go
+ */
+
+package database
+
+import (
+	"crypto/sha1"
+	"datahub-service/service/models"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+)
+
+const (
+	// fieldIndexPrefix 字段配置声明的托管索引名前缀
+	fieldIndexPrefix = "fidx_"
+	// fieldUniquePrefix 修改表时新增的单字段唯一约束名前缀
+	fieldUniquePrefix = "fuq_"
+	// maxIdentifierLength PostgreSQL标识符最大长度
+	maxIdentifierLength = 63
+)
+
+// FieldIndex 字段配置声明的索引
+type FieldIndex struct {
+	Name    string   `json:"name"`
+	Columns []string `json:"columns"`
+	Method  string   `json:"method,omitempty"` // 索引方法，为空时为btree，空间字段的单字段索引为gist
+}
+
+// PlanFieldIndexes 根据字段配置规划托管索引：is_indexed的字段单独建索引（主键和唯一字段已有索引，跳过），
+// composite_index相同的字段按OrderNum顺序组成组合索引，空间字段的单字段索引使用GiST
+func PlanFieldIndexes(tableName string, fields []models.TableField) []FieldIndex {
+	ordered := make([]models.TableField, len(fields))
+	copy(ordered, fields)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return ordered[i].OrderNum < ordered[j].OrderNum
+	})
+
+	var indexes []FieldIndex
+	groups := make(map[string][]string)
+	var groupNames []string
+	for _, field := range ordered {
+		if field.IsIndexed && !field.IsPrimaryKey && !field.IsUnique {
+			index := FieldIndex{
+				Name:    fieldIndexName(tableName, field.NameEn),
+				Columns: []string{field.NameEn},
+			}
+			if models.IsGeoDataType(field.DataType) {
+				index.Method = "gist"
+			}
+			indexes = append(indexes, index)
+		}
+		group := strings.TrimSpace(field.CompositeIndex)
+		if group == "" {
+			continue
+		}
+		if _, ok := groups[group]; !ok {
+			groupNames = append(groupNames, group)
+		}
+		groups[group] = append(groups[group], field.NameEn)
+	}
+
+	for _, group := range groupNames {
+		indexes = append(indexes, FieldIndex{
+			Name:    fieldIndexName(tableName, group),
+			Columns: groups[group],
+		})
+	}
+	return indexes
+}
+
+// syncFieldIndexes 按字段配置维护托管索引，concurrently为true时使用CONCURRENTLY，用于已有数据的表
+func (s *SchemaService) syncFieldIndexes(schemaName, tableName string, fields []models.TableField, concurrently bool) error {
+	existing, err := s.GetTableIndexes(schemaName, tableName)
+	if err != nil {
+		return err
+	}
+	managed := make(map[string]IndexDefinition)
+	for _, index := range existing {
+		if strings.HasPrefix(index.Name, fieldIndexPrefix) {
+			managed[index.Name] = index
+		}
+	}
+
+	desired := PlanFieldIndexes(tableName, fields)
+	wanted := make(map[string]bool, len(desired))
+	for _, index := range desired {
+		wanted[index.Name] = true
+		if current, ok := managed[index.Name]; ok {
+			if stringSliceEqual(current.Columns, index.Columns) && current.IndexType == index.indexMethod() {
+				continue
+			}
+			// 组合索引的字段或索引方法有变化，删除后按新字段重建
+			if err := s.dropFieldIndex(schemaName, index.Name, concurrently); err != nil {
+				return fmt.Errorf("删除索引 %s 失败: %v", index.Name, err)
+			}
+		}
+		if err := s.createFieldIndex(schemaName, tableName, index.Name, index.Columns, index.Method, false, concurrently); err != nil {
+			return fmt.Errorf("创建索引 %s 失败: %v", index.Name, err)
+		}
+	}
+
+	for name := range managed {
+		if wanted[name] {
+			continue
+		}
+		if err := s.dropFieldIndex(schemaName, name, concurrently); err != nil {
+			return fmt.Errorf("删除索引 %s 失败: %v", name, err)
+		}
+	}
+	return nil
+}
+
+// indexMethod 索引实际使用的方法
+func (index FieldIndex) indexMethod() string {
+	if index.Method == "" {
+		return "btree"
+	}
+	return index.Method
+}
+
+// syncUniqueConstraints 修改表时同步单字段唯一约束，新增列的唯一约束已在列定义中创建
+func (s *SchemaService) syncUniqueConstraints(schemaName, tableName string, fields []models.TableField, currentColMap map[string]ColumnDefinition) error {
+	constraints, err := s.getTableConstraints(schemaName, tableName)
+	if err != nil {
+		return err
+	}
+	// 单字段唯一约束，按字段名索引
+	uniqueByColumn := make(map[string][]string)
+	for _, constraint := range constraints {
+		if constraint.Type == "unique" && len(constraint.Columns) == 1 {
+			uniqueByColumn[constraint.Columns[0]] = append(uniqueByColumn[constraint.Columns[0]], constraint.Name)
+		}
+	}
+
+	for _, field := range fields {
+		if field.IsPrimaryKey {
+			continue
+		}
+		if _, exists := currentColMap[field.NameEn]; !exists {
+			continue
+		}
+		names := uniqueByColumn[field.NameEn]
+		switch {
+		case field.IsUnique && len(names) == 0:
+			if err := s.addUniqueConstraint(schemaName, tableName, field.NameEn); err != nil {
+				return fmt.Errorf("添加列 %s 唯一约束失败: %v", field.NameEn, err)
+			}
+		case !field.IsUnique:
+			for _, name := range names {
+				dropSQL := fmt.Sprintf(
+					"ALTER TABLE %s.%s DROP CONSTRAINT IF EXISTS %s",
+					s.quoteIdentifier(schemaName),
+					s.quoteIdentifier(tableName),
+					s.quoteIdentifier(name),
+				)
+				slog.Debug("SchemaService.syncUniqueConstraints - 删除唯一约束", "sql", dropSQL)
+				if err := s.db.Exec(dropSQL).Error; err != nil {
+					return fmt.Errorf("删除列 %s 唯一约束失败: %v", field.NameEn, err)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// addUniqueConstraint 先并发创建唯一索引再挂为唯一约束，建索引期间不阻塞表的读写
+func (s *SchemaService) addUniqueConstraint(schemaName, tableName, columnName string) error {
+	name := boundedIdentifier(fieldUniquePrefix + tableName + "_" + columnName)
+	if err := s.createFieldIndex(schemaName, tableName, name, []string{columnName}, "", true, true); err != nil {
+		return err
+	}
+
+	alterSQL := fmt.Sprintf(
+		"ALTER TABLE %s.%s ADD CONSTRAINT %s UNIQUE USING INDEX %s",
+		s.quoteIdentifier(schemaName),
+		s.quoteIdentifier(tableName),
+		s.quoteIdentifier(name),
+		s.quoteIdentifier(name),
+	)
+	slog.Debug("SchemaService.addUniqueConstraint", "sql", alterSQL)
+	if err := s.db.Exec(alterSQL).Error; err != nil {
+		s.DropIndex(schemaName, name)
+		return err
+	}
+	return nil
+}
+
+// createFieldIndex 创建索引，method为空时使用默认的btree，CONCURRENTLY失败时删除留下的无效索引
+func (s *SchemaService) createFieldIndex(schemaName, tableName, indexName string, columns []string, method string, isUnique, concurrently bool) error {
+	quotedColumns := make([]string, len(columns))
+	for i, col := range columns {
+		quotedColumns[i] = s.quoteIdentifier(col)
+	}
+
+	modifiers := ""
+	if isUnique {
+		modifiers = "UNIQUE "
+	}
+	using := ""
+	if method != "" {
+		using = "USING " + method + " "
+	}
+	createSQL := fmt.Sprintf(
+		"CREATE %sINDEX %sIF NOT EXISTS %s ON %s.%s %s(%s)",
+		modifiers,
+		concurrentlyKeyword(concurrently),
+		s.quoteIdentifier(indexName),
+		s.quoteIdentifier(schemaName),
+		s.quoteIdentifier(tableName),
+		using,
+		strings.Join(quotedColumns, ", "),
+	)
+
+	slog.Debug("SchemaService.createFieldIndex", "sql", createSQL)
+	if err := s.db.Exec(createSQL).Error; err != nil {
+		if concurrently {
+			if dropErr := s.dropFieldIndex(schemaName, indexName, true); dropErr != nil {
+				slog.Warn("删除无效索引失败", "index", indexName, "error", dropErr)
+			}
+		}
+		return err
+	}
+	return nil
+}
+
+// dropFieldIndex 删除托管索引
+func (s *SchemaService) dropFieldIndex(schemaName, indexName string, concurrently bool) error {
+	dropSQL := fmt.Sprintf(
+		"DROP INDEX %sIF EXISTS %s.%s",
+		concurrentlyKeyword(concurrently),
+		s.quoteIdentifier(schemaName),
+		s.quoteIdentifier(indexName),
+	)
+
+	slog.Debug("SchemaService.dropFieldIndex", "sql", dropSQL)
+	return s.db.Exec(dropSQL).Error
+}
+
+// concurrentlyKeyword 返回CONCURRENTLY关键字
+func concurrentlyKeyword(concurrently bool) string {
+	if concurrently {
+		return "CONCURRENTLY "
+	}
+	return ""
+}
+
+// fieldIndexName 托管索引名
+func fieldIndexName(tableName, suffix string) string {
+	return boundedIdentifier(fieldIndexPrefix + tableName + "_" + suffix)
+}
+
+// boundedIdentifier 超过标识符长度时截断并附加哈希，避免PostgreSQL截断后重名
+func boundedIdentifier(name string) string {
+	if len(name) <= maxIdentifierLength {
+		return name
+	}
+	sum := sha1.Sum([]byte(name))
+	suffix := "_" + hex.EncodeToString(sum[:])[:8]
+	return name[:maxIdentifierLength-len(suffix)] + suffix
+}
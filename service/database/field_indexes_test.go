@@ -0,0 +1,48 @@
+/*
+ * @module service/database/field_indexes_test
+ * @description 字段配置索引规划测试，覆盖单字段索引、空间字段GiST索引、跳过主键和唯一字段、组合索引按字段顺序组成以及长索引名截断
+ * @architecture 测试层 - 单元测试
+ */
+
+package database
+
+import (
+	"datahub-service/service/models"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestPlanFieldIndexes 测试根据字段配置规划托管索引
+func TestPlanFieldIndexes(t *testing.T) {
+	fields := []models.TableField{
+		{NameEn: "updated_at", OrderNum: 4, CompositeIndex: "status_time"},
+		{NameEn: "id", OrderNum: 1, IsPrimaryKey: true, IsIndexed: true},
+		{NameEn: "id_card", OrderNum: 2, IsUnique: true, IsIndexed: true},
+		{NameEn: "status", OrderNum: 3, IsIndexed: true, CompositeIndex: "status_time"},
+		{NameEn: "district", OrderNum: 5, IsIndexed: true},
+		{NameEn: "location", OrderNum: 6, DataType: "geometry", IsIndexed: true},
+	}
+
+	indexes := PlanFieldIndexes("person", fields)
+	assert.Equal(t, []FieldIndex{
+		{Name: "fidx_person_status", Columns: []string{"status"}},
+		{Name: "fidx_person_district", Columns: []string{"district"}},
+		{Name: "fidx_person_location", Columns: []string{"location"}, Method: "gist"},
+		{Name: "fidx_person_status_time", Columns: []string{"status", "updated_at"}},
+	}, indexes)
+
+	assert.Empty(t, PlanFieldIndexes("person", []models.TableField{{NameEn: "name", OrderNum: 1}}))
+}
+
+// TestBoundedIdentifier 测试超长索引名截断
+func TestBoundedIdentifier(t *testing.T) {
+	assert.Equal(t, "fidx_person_status", boundedIdentifier("fidx_person_status"))
+
+	a := fieldIndexName(strings.Repeat("t", 50), strings.Repeat("a", 20))
+	b := fieldIndexName(strings.Repeat("t", 50), strings.Repeat("b", 20))
+	assert.Len(t, a, maxIdentifierLength)
+	assert.True(t, strings.HasPrefix(a, fieldIndexPrefix))
+	assert.NotEqual(t, a, b, "截断后仍区分不同分组")
+}
@@ -0,0 +1,108 @@
+/*
+ * @module service/database/geo
+ * @description 接口表空间列支持，建表时启用PostGIS扩展并创建geometry/geography列，查询时识别空间列并以GeoJSON返回
+ * @architecture 分层架构 - 业务服务层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow 表字段配置含空间字段 -> 启用PostGIS扩展 -> 创建SRID 4326的空间列和GiST索引；数据查看/共享查询 -> 识别空间列 -> ST_AsGeoJSON输出
+ * @rules 空间列统一为WGS84（SRID 4326）；数据库未安装PostGIS时建表失败并提示；空间列的普通索引使用GiST
+ * @dependencies datahub-service/service/models, gorm.io/gorm
+ * @refs service/models/geo.go, service/database/schema_service.go, service/database/field_indexes.go
+ */
+
+package database
+
+import (
+	"datahub-service/service/models"
+	"datahub-service/service/utils"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// geoColumnType 空间字段类型对应的PostgreSQL列类型
+func geoColumnType(dataType string) string {
+	return fmt.Sprintf("%s(Geometry,%d)", strings.ToLower(strings.TrimSpace(dataType)), models.GeoSRID)
+}
+
+// hasGeoField 字段配置中是否有空间字段
+func hasGeoField(fields []models.TableField) bool {
+	for _, field := range fields {
+		if models.IsGeoDataType(field.DataType) {
+			return true
+		}
+	}
+	return false
+}
+
+// ensurePostGIS 字段配置中有空间字段时启用PostGIS扩展
+func (s *SchemaService) ensurePostGIS(fields []models.TableField) error {
+	if !hasGeoField(fields) {
+		return nil
+	}
+	slog.Debug("SchemaService.ensurePostGIS - 启用PostGIS扩展")
+	if err := s.db.Exec("CREATE EXTENSION IF NOT EXISTS postgis").Error; err != nil {
+		return fmt.Errorf("启用PostGIS扩展失败，请确认数据库已安装PostGIS: %v", err)
+	}
+	return nil
+}
+
+// GetGeoColumns 获取表的空间列，返回列名 -> geometry/geography
+func (s *SchemaService) GetGeoColumns(schemaName, tableName string) (map[string]string, error) {
+	return QueryGeoColumns(s.readDB, schemaName, tableName)
+}
+
+// QueryGeoColumns 查询表的空间列，返回列名 -> geometry/geography
+func QueryGeoColumns(db *gorm.DB, schemaName, tableName string) (map[string]string, error) {
+	var columns []struct {
+		ColumnName string
+		UdtName    string
+	}
+	err := db.Raw(`SELECT column_name, udt_name FROM information_schema.columns
+		WHERE table_schema = ? AND table_name = ? AND udt_name IN (?, ?)`,
+		schemaName, tableName, models.GeoTypeGeometry, models.GeoTypeGeography).Scan(&columns).Error
+	if err != nil {
+		return nil, fmt.Errorf("查询空间列失败: %v", err)
+	}
+	geoColumns := make(map[string]string, len(columns))
+	for _, column := range columns {
+		geoColumns[column.ColumnName] = column.UdtName
+	}
+	return geoColumns, nil
+}
+
+// QueryTableColumnNames 按列顺序查询表的列名
+func QueryTableColumnNames(db *gorm.DB, schemaName, tableName string) ([]string, error) {
+	var columns []string
+	if err := db.Raw(`SELECT column_name FROM information_schema.columns
+		WHERE table_schema = ? AND table_name = ? ORDER BY ordinal_position`,
+		schemaName, tableName).Scan(&columns).Error; err != nil {
+		return nil, fmt.Errorf("查询表列失败: %v", err)
+	}
+	return columns, nil
+}
+
+// GeoSelectList 构建查询列表，空间列以GeoJSON返回；columns为空时取表的全部列，没有空间列时返回*
+func GeoSelectList(db *gorm.DB, schemaName, tableName string, columns []string, geoColumns map[string]string) (string, error) {
+	if len(columns) == 0 {
+		if len(geoColumns) == 0 {
+			return "*", nil
+		}
+		var err error
+		if columns, err = QueryTableColumnNames(db, schemaName, tableName); err != nil {
+			return "", err
+		}
+	}
+
+	items := make([]string, len(columns))
+	for i, column := range columns {
+		quoted := utils.QuoteIdent(column)
+		if _, ok := geoColumns[column]; ok {
+			items[i] = fmt.Sprintf("ST_AsGeoJSON(%s)::json AS %s", quoted, quoted)
+		} else {
+			items[i] = quoted
+		}
+	}
+	return strings.Join(items, ", "), nil
+}
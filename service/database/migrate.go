@@ -72,7 +72,18 @@ func AutoMigrate(db *gorm.DB) error {
 		slog.Warn("❌ thematic_interfaces 表创建失败")
 	}
 
-	// 访问控制相关表已移除，改为使用PostgREST RBAC
+	// 访问控制相关表（用户认证由PostgREST负责，此处仅管理业务角色和权限）
+	slog.Info("正在迁移访问控制相关表...")
+	err = db.AutoMigrate(
+		&models.RolePermission{},
+		&models.UserRoleAssignment{},
+		&models.ResourceGrant{},
+	)
+	if err != nil {
+		slog.Error("访问控制表迁移失败", "error", err)
+		return err
+	}
+	slog.Info("访问控制表迁移完成")
 
 	// 数据治理相关表
 	slog.Info("正在迁移数据治理相关表...")
@@ -91,6 +102,8 @@ func AutoMigrate(db *gorm.DB) error {
 		&models.QualityTaskFieldRule{},
 		&models.QualityIssueRecord{},
 		&models.DataLineage{},
+		&models.RuleRecommendation{},
+		&models.ColumnEncryption{},
 	)
 	if err != nil {
 		slog.Error("数据治理表迁移失败", "error", err)
@@ -105,10 +118,14 @@ func AutoMigrate(db *gorm.DB) error {
 		&models.ApiKey{},
 		&models.ApiKeyApplication{},
 		&models.ApiInterface{},
+		&models.ApiInterfaceRelease{},
+		&models.InterfaceDeprecation{},
 		&models.ApiRateLimit{},
 		&models.DataSubscription{},
 		&models.DataAccessRequest{},
+		&models.DataAccessGrant{},
 		&models.ApiUsageLog{},
+		&models.RowLevelPolicy{},
 	)
 	if err != nil {
 		slog.Error("数据共享服务表迁移失败", "error", err)
@@ -177,6 +194,188 @@ func AutoMigrate(db *gorm.DB) error {
 	}
 	slog.Info("监控和告警表迁移完成")
 
+	// 幂等请求记录表
+	if err := db.AutoMigrate(&models.IdempotencyRecord{}); err != nil {
+		slog.Error("幂等请求记录表迁移失败", "error", err)
+		return err
+	}
+
+	// 故障注入配置表
+	if err := db.AutoMigrate(&models.FaultInjection{}); err != nil {
+		slog.Error("故障注入配置表迁移失败", "error", err)
+		return err
+	}
+
+	// 应用事件表
+	if err := db.AutoMigrate(&models.ApplicationEvent{}); err != nil {
+		slog.Error("应用事件表迁移失败", "error", err)
+		return err
+	}
+
+	// 租户相关表
+	if err := db.AutoMigrate(&models.Tenant{}, &models.TenantMember{}); err != nil {
+		slog.Error("租户表迁移失败", "error", err)
+		return err
+	}
+
+	// 通知中心相关表
+	if err := db.AutoMigrate(&models.NotifyChannel{}, &models.NotifySubscription{}, &models.Notification{}, &models.NotifyDelivery{}, &models.NotifyTemplate{}); err != nil {
+		slog.Error("通知中心表迁移失败", "error", err)
+		return err
+	}
+
+	// 接口数据新鲜度表
+	if err := db.AutoMigrate(&models.InterfaceFreshness{}); err != nil {
+		slog.Error("接口数据新鲜度表迁移失败", "error", err)
+		return err
+	}
+
+	// 接口数据契约和契约校验记录表
+	if err := db.AutoMigrate(&models.InterfaceContract{}, &models.InterfaceContractCheck{}); err != nil {
+		slog.Error("接口数据契约表迁移失败", "error", err)
+		return err
+	}
+
+	// 接口引用关系和引用完整性检查记录表
+	if err := db.AutoMigrate(&models.InterfaceReference{}, &models.InterfaceReferenceCheck{}); err != nil {
+		slog.Error("接口引用关系表迁移失败", "error", err)
+		return err
+	}
+
+	// 同步执行数据变更摘要表
+	if err := db.AutoMigrate(&models.SyncExecutionDiff{}); err != nil {
+		slog.Error("同步执行数据变更摘要表迁移失败", "error", err)
+		return err
+	}
+
+	// 接口表快照策略和快照记录表，快照表本身放在单独的schema中
+	if err := db.AutoMigrate(&models.InterfaceSnapshotPolicy{}, &models.InterfaceTableSnapshot{}); err != nil {
+		slog.Error("接口表快照表迁移失败", "error", err)
+		return err
+	}
+	if err := db.Exec("CREATE SCHEMA IF NOT EXISTS " + models.SnapshotSchema).Error; err != nil {
+		slog.Error("创建快照schema失败", "error", err)
+		return err
+	}
+
+	// 接口基准数据集、基准行和比较记录表
+	if err := db.AutoMigrate(&models.InterfaceGoldenDataset{}, &models.InterfaceGoldenRow{}, &models.InterfaceGoldenRun{}); err != nil {
+		slog.Error("接口基准数据集表迁移失败", "error", err)
+		return err
+	}
+
+	// 主题接口发布评审表
+	if err := db.AutoMigrate(&models.ThematicPublicationReview{}); err != nil {
+		slog.Error("主题接口发布评审表迁移失败", "error", err)
+		return err
+	}
+
+	// 主题表多源写入合并策略、记录来源和冲突表
+	if err := db.AutoMigrate(&models.ThematicMergePolicy{}, &models.ThematicRecordSource{}, &models.ThematicMergeConflict{}); err != nil {
+		slog.Error("主题表合并策略表迁移失败", "error", err)
+		return err
+	}
+
+	// 主题接口变更日志策略表，变更日志表本身放在单独的schema中
+	if err := db.AutoMigrate(&models.ThematicChangelogPolicy{}); err != nil {
+		slog.Error("主题接口变更日志策略表迁移失败", "error", err)
+		return err
+	}
+	if err := db.Exec("CREATE SCHEMA IF NOT EXISTS " + models.ChangelogSchema).Error; err != nil {
+		slog.Error("创建变更日志schema失败", "error", err)
+		return err
+	}
+
+	// 同步任务模板表
+	if err := db.AutoMigrate(&models.SyncTaskTemplate{}); err != nil {
+		slog.Error("同步任务模板表迁移失败", "error", err)
+		return err
+	}
+
+	// 同步任务质量门禁阻断表
+	if err := db.AutoMigrate(&models.QualityGateBlock{}); err != nil {
+		slog.Error("质量门禁阻断表迁移失败", "error", err)
+		return err
+	}
+
+	// 数据源凭据轮换记录表
+	if err := db.AutoMigrate(&models.DataSourceCredentialRotation{}); err != nil {
+		slog.Error("数据源凭据轮换记录表迁移失败", "error", err)
+		return err
+	}
+
+	// 接口表重复数据检测报告表
+	if err := db.AutoMigrate(&models.DuplicateReport{}); err != nil {
+		slog.Error("重复数据检测报告表迁移失败", "error", err)
+		return err
+	}
+
+	// 接口表存储快照表
+	if err := db.AutoMigrate(&models.StorageSnapshot{}); err != nil {
+		slog.Error("存储快照表迁移失败", "error", err)
+		return err
+	}
+
+	// 库和租户配额表
+	if err := db.AutoMigrate(&models.LibraryQuota{}); err != nil {
+		slog.Error("配额表迁移失败", "error", err)
+		return err
+	}
+
+	// 元数据一致性核对报告表
+	if err := db.AutoMigrate(&models.ReconciliationReport{}, &models.ReconciliationFinding{}); err != nil {
+		slog.Error("元数据核对报告表迁移失败", "error", err)
+		return err
+	}
+
+	// SQL工作台保存的查询表
+	if err := db.AutoMigrate(&models.SavedQuery{}); err != nil {
+		slog.Error("SQL工作台查询表迁移失败", "error", err)
+		return err
+	}
+
+	// 指标定义和物化指标值表
+	if err := db.AutoMigrate(&models.MetricDefinition{}, &models.MetricValue{}); err != nil {
+		slog.Error("指标层表迁移失败", "error", err)
+		return err
+	}
+
+	// 报表订阅表
+	if err := db.AutoMigrate(&models.ReportSubscription{}); err != nil {
+		slog.Error("报表订阅表迁移失败", "error", err)
+		return err
+	}
+
+	// 自定义函数注册表
+	if err := db.AutoMigrate(&models.UDFDefinition{}, &models.UDFVersion{}); err != nil {
+		slog.Error("自定义函数表迁移失败", "error", err)
+		return err
+	}
+
+	// 时间序列降采样任务表，汇总表本身建在源接口所在的schema中
+	if err := db.AutoMigrate(&models.TimeSeriesRollup{}); err != nil {
+		slog.Error("时间序列降采样任务表迁移失败", "error", err)
+		return err
+	}
+
+	// 数据值告警规则和告警记录表
+	if err := db.AutoMigrate(&models.ValueAlertRule{}, &models.ValueAlert{}); err != nil {
+		slog.Error("数据值告警表迁移失败", "error", err)
+		return err
+	}
+
+	// 长时操作表
+	if err := db.AutoMigrate(&models.Operation{}); err != nil {
+		slog.Error("长时操作表迁移失败", "error", err)
+		return err
+	}
+
+	// 平台事件Webhook和投递日志表
+	if err := db.AutoMigrate(&models.WebhookEndpoint{}, &models.WebhookDelivery{}); err != nil {
+		slog.Error("Webhook表迁移失败", "error", err)
+		return err
+	}
+
 	// 创建同步相关索引
 	if err := CreateSyncIndexes(db); err != nil {
 		slog.Error("创建同步索引失败", "error", err)
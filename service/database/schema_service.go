@@ -13,6 +13,7 @@ package database
 
 import (
 	"datahub-service/service/models"
+	"encoding/json"
 	"fmt"
 	"log/slog"
 	"strings"
@@ -23,16 +24,26 @@ import (
 
 // SchemaService 表结构管理服务
 type SchemaService struct {
-	db *gorm.DB
+	db     *gorm.DB
+	readDB *gorm.DB // 数据浏览使用的只读连接
 }
 
 // NewSchemaService 创建表结构管理服务实例
 func NewSchemaService(db *gorm.DB) *SchemaService {
 	return &SchemaService{
-		db: db,
+		db:     db,
+		readDB: db,
 	}
 }
 
+// SetReadDB 设置数据浏览使用的只读副本连接，传入nil时使用主库
+func (s *SchemaService) SetReadDB(readDB *gorm.DB) {
+	if readDB == nil {
+		readDB = s.db
+	}
+	s.readDB = readDB
+}
+
 // TableDefinition 表定义结构
 type TableDefinition struct {
 	Name        string                 `json:"name"`
@@ -56,6 +67,9 @@ type ColumnDefinition struct {
 	NumericPrecision *int        `json:"numeric_precision,omitempty"`
 	NumericScale     *int        `json:"numeric_scale,omitempty"`
 	OrdinalPosition  int         `json:"ordinal_position"`
+	// 生成列信息
+	IsGenerated          bool   `json:"is_generated,omitempty"`
+	GenerationExpression string `json:"generation_expression,omitempty"`
 }
 
 // ConstraintDefinition 约束定义结构
@@ -78,6 +92,12 @@ type IndexDefinition struct {
 
 // ManageTableSchema 管理表结构
 func (s *SchemaService) ManageTableSchema(interfaceID, operation, schemaName, tableName string, fields []models.TableField) error {
+	if operation == "create_table" || operation == "alter_table" {
+		if err := ValidateComputedFields(fields); err != nil {
+			return err
+		}
+	}
+
 	switch operation {
 	case "create_table":
 		return s.createTable(schemaName, tableName, fields)
@@ -106,6 +126,10 @@ func (s *SchemaService) ManageViewSchema(interfaceID, operation, schemaName, vie
 
 // createTable 创建表
 func (s *SchemaService) createTable(schemaName, tableName string, fields []models.TableField) error {
+	if err := s.ensurePostGIS(fields); err != nil {
+		return err
+	}
+
 	// 构建CREATE TABLE语句
 	var columnDefs []string
 	var primaryKeys []string
@@ -168,6 +192,11 @@ func (s *SchemaService) createTable(schemaName, tableName string, fields []model
 		}
 	}
 
+	// 创建字段配置声明的索引，新表没有数据，无需并发创建
+	if err := s.syncFieldIndexes(schemaName, tableName, fields, false); err != nil {
+		return fmt.Errorf("创建字段索引失败: %v", err)
+	}
+
 	return nil
 }
 
@@ -184,8 +213,11 @@ func (s *SchemaService) buildColumnDefinition(field models.TableField) string {
 		parts = append(parts, "NOT NULL")
 	}
 
-	// 默认值
-	if field.DefaultValue != "" {
+	// 生成列表达式，生成列不能有默认值
+	if field.IsComputed() && field.EffectiveComputeMode() == models.ComputeModeGenerated {
+		parts = append(parts, "GENERATED ALWAYS AS", "("+field.Expression+")", "STORED")
+	} else if field.DefaultValue != "" {
+		// 默认值
 		parts = append(parts, "DEFAULT", s.formatDefaultValue(field.DefaultValue, dataType))
 	}
 
@@ -232,16 +264,28 @@ func (s *SchemaService) alterTable(schemaName, tableName string, fields []models
 		return fmt.Errorf("获取主键失败: %v", err)
 	}
 
-	// 执行列的添加、删除、修改
-	for _, field := range fields {
-		if _, exists := currentColMap[field.NameEn]; !exists {
+	if err := s.ensurePostGIS(fields); err != nil {
+		return err
+	}
+
+	// 执行列的添加、删除、修改，派生列放在最后处理，保证表达式引用的新列已经添加
+	for _, field := range computedFieldsLast(fields) {
+		currentCol, exists := currentColMap[field.NameEn]
+		if exists && computedColumnNeedsRebuild(field, currentCol) {
+			// 派生方式或生成列表达式变化，删除后重建，由数据库或同步刷新重新计算
+			if err := s.dropColumn(schemaName, tableName, field.NameEn); err != nil {
+				return fmt.Errorf("重建派生列 %s 失败: %v", field.NameEn, err)
+			}
+			exists = false
+		}
+		if !exists {
 			// 添加新列
 			if err := s.addColumn(schemaName, tableName, field); err != nil {
 				return fmt.Errorf("添加列 %s 失败: %v", field.NameEn, err)
 			}
 		} else {
 			// 修改现有列
-			if err := s.modifyColumn(schemaName, tableName, field, currentColMap[field.NameEn]); err != nil {
+			if err := s.modifyColumn(schemaName, tableName, field, currentCol); err != nil {
 				return fmt.Errorf("修改列 %s 失败: %v", field.NameEn, err)
 			}
 		}
@@ -263,6 +307,23 @@ func (s *SchemaService) alterTable(schemaName, tableName string, fields []models
 		}
 	}
 
+	// 同步唯一约束和字段配置声明的索引，已有表可能数据量很大，并发创建避免锁表
+	if err := s.syncUniqueConstraints(schemaName, tableName, fields, currentColMap); err != nil {
+		return fmt.Errorf("更新唯一约束失败: %v", err)
+	}
+	if err := s.syncFieldIndexes(schemaName, tableName, fields, true); err != nil {
+		return fmt.Errorf("更新字段索引失败: %v", err)
+	}
+
+	// 按新表达式刷新已有数据的同步刷新列
+	fullTableName := s.quoteIdentifier(schemaName) + "." + s.quoteIdentifier(tableName)
+	if refreshSQL := BuildComputedRefreshSQL(fullTableName, fields); refreshSQL != "" {
+		slog.Debug("SchemaService.alterTable - 刷新派生列", "sql", refreshSQL)
+		if err := s.db.Exec(refreshSQL).Error; err != nil {
+			return fmt.Errorf("刷新派生列失败: %v", err)
+		}
+	}
+
 	return nil
 }
 
@@ -342,8 +403,8 @@ func (s *SchemaService) modifyColumn(schemaName, tableName string, newField mode
 		}
 	}
 
-	// 更新默认值
-	if newField.DefaultValue != "" {
+	// 更新默认值，生成列不能有默认值
+	if newField.DefaultValue != "" && !oldCol.IsGenerated {
 		alterSQL := fmt.Sprintf(
 			"ALTER TABLE %s.%s ALTER COLUMN %s SET DEFAULT %s",
 			s.quoteIdentifier(schemaName),
@@ -493,7 +554,9 @@ func (s *SchemaService) GetTableColumns(schemaName, tableName string) ([]ColumnD
 					AND tc.table_schema = c.table_schema
 					AND tc.table_name = c.table_name
 					AND kcu.column_name = c.column_name
-			) as is_unique
+			) as is_unique,
+			c.is_generated = 'ALWAYS' as is_generated,
+			c.generation_expression
 		FROM information_schema.columns c
 		LEFT JOIN pg_catalog.pg_statio_all_tables st 
 			ON c.table_schema = st.schemaname 
@@ -514,7 +577,7 @@ func (s *SchemaService) GetTableColumns(schemaName, tableName string) ([]ColumnD
 
 	for rows.Next() {
 		var col ColumnDefinition
-		var defaultValue, comment *string
+		var defaultValue, comment, generationExpression *string
 		var maxLength, numericPrecision, numericScale *int
 
 		err := rows.Scan(
@@ -529,6 +592,8 @@ func (s *SchemaService) GetTableColumns(schemaName, tableName string) ([]ColumnD
 			&comment,
 			&col.IsPrimaryKey,
 			&col.IsUnique,
+			&col.IsGenerated,
+			&generationExpression,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("扫描列信息失败: %v", err)
@@ -540,6 +605,9 @@ func (s *SchemaService) GetTableColumns(schemaName, tableName string) ([]ColumnD
 		if comment != nil {
 			col.Comment = *comment
 		}
+		if generationExpression != nil {
+			col.GenerationExpression = *generationExpression
+		}
 		col.MaxLength = maxLength
 		col.NumericPrecision = numericPrecision
 		col.NumericScale = numericScale
@@ -1043,7 +1111,7 @@ func (s *SchemaService) ListTables(schemaName string) ([]string, error) {
 	return tableNames, nil
 }
 
-// ListSchemas 列出所有Schema
+// ListSchemas 列出所有Schema，不含接口表快照和变更日志所在的schema
 func (s *SchemaService) ListSchemas() ([]string, error) {
 	query := `
 		SELECT schema_name
@@ -1051,11 +1119,12 @@ func (s *SchemaService) ListSchemas() ([]string, error) {
 		WHERE schema_name NOT IN ('pg_catalog', 'information_schema', 'pg_toast')
 			AND schema_name NOT LIKE 'pg_temp%'
 			AND schema_name NOT LIKE 'pg_toast_temp%'
+			AND schema_name NOT IN (?, ?)
 		ORDER BY schema_name
 	`
 
 	var schemaNames []string
-	rows, err := s.db.Raw(query).Rows()
+	rows, err := s.db.Raw(query, models.SnapshotSchema, models.ChangelogSchema).Rows()
 	if err != nil {
 		return nil, err
 	}
@@ -1075,6 +1144,12 @@ func (s *SchemaService) ListSchemas() ([]string, error) {
 // GetTableData 获取表数据
 // whereCondition: SQL WHERE 条件，由前端拼好并做好转义后传递（不包含 WHERE 关键字）
 func (s *SchemaService) GetTableData(fullTableName string, limit, offset int, whereCondition string) ([]map[string]interface{}, int, error) {
+	return s.GetTableDataWithRowFilter(fullTableName, limit, offset, whereCondition, "")
+}
+
+// GetTableDataWithRowFilter 获取表数据，并在行级安全过滤后的结果上应用前端WHERE条件，数据从只读副本读取
+// rowFilter: 参数化的行过滤条件（不包含 WHERE 关键字），先于 whereCondition 生效，无法被其绕过
+func (s *SchemaService) GetTableDataWithRowFilter(fullTableName string, limit, offset int, whereCondition, rowFilter string, rowFilterArgs ...interface{}) ([]map[string]interface{}, int, error) {
 	parts := strings.Split(fullTableName, ".")
 	if len(parts) != 2 {
 		return nil, 0, fmt.Errorf("无效的表名格式，应为 schema.table")
@@ -1098,25 +1173,36 @@ func (s *SchemaService) GetTableData(fullTableName string, limit, offset int, wh
 		slog.Debug("SchemaService.GetTableData - 使用 WHERE 条件", "condition", whereCondition)
 	}
 
+	// 数据来源，存在行过滤条件时使用同名子查询，前端条件只能在过滤后的结果上生效
+	source := fmt.Sprintf("%s.%s", s.quoteIdentifier(schemaName), s.quoteIdentifier(tableName))
+	if rowFilter != "" {
+		source = fmt.Sprintf("(SELECT * FROM %s WHERE %s) AS %s", source, rowFilter, s.quoteIdentifier(tableName))
+	}
+
 	// 获取总行数（应用 WHERE 条件）
 	var totalCount int64
-	countSQL := fmt.Sprintf("SELECT COUNT(*) FROM %s.%s%s",
-		s.quoteIdentifier(schemaName),
-		s.quoteIdentifier(tableName),
-		whereClause)
-	err = s.db.Raw(countSQL).Scan(&totalCount).Error
+	countSQL := fmt.Sprintf("SELECT COUNT(*) FROM %s%s", source, whereClause)
+	err = s.readDB.Raw(countSQL, rowFilterArgs...).Scan(&totalCount).Error
 	if err != nil {
 		return nil, 0, fmt.Errorf("获取总行数失败: %v", err)
 	}
 
-	// 获取数据（应用 WHERE 条件）
-	dataSQL := fmt.Sprintf("SELECT * FROM %s.%s%s ORDER BY 1 LIMIT %d OFFSET %d",
-		s.quoteIdentifier(schemaName),
-		s.quoteIdentifier(tableName),
+	// 获取数据（应用 WHERE 条件），空间列以GeoJSON返回
+	geoColumns, err := QueryGeoColumns(s.readDB, schemaName, tableName)
+	if err != nil {
+		return nil, 0, err
+	}
+	selectList, err := GeoSelectList(s.readDB, schemaName, tableName, nil, geoColumns)
+	if err != nil {
+		return nil, 0, err
+	}
+	dataSQL := fmt.Sprintf("SELECT %s FROM %s%s ORDER BY 1 LIMIT %d OFFSET %d",
+		selectList,
+		source,
 		whereClause,
 		limit, offset)
 
-	rows, err := s.db.Raw(dataSQL).Rows()
+	rows, err := s.readDB.Raw(dataSQL, rowFilterArgs...).Rows()
 	if err != nil {
 		return nil, 0, fmt.Errorf("查询数据失败: %v", err)
 	}
@@ -1127,6 +1213,17 @@ func (s *SchemaService) GetTableData(fullTableName string, limit, offset int, wh
 		return nil, 0, fmt.Errorf("获取列名失败: %v", err)
 	}
 
+	// JSON/JSONB列以结构化JSON返回
+	jsonColumns := make([]bool, len(columns))
+	if columnTypes, err := rows.ColumnTypes(); err == nil {
+		for i, columnType := range columnTypes {
+			switch strings.ToUpper(columnType.DatabaseTypeName()) {
+			case "JSON", "JSONB":
+				jsonColumns[i] = true
+			}
+		}
+	}
+
 	var data []map[string]interface{}
 	for rows.Next() {
 		values := make([]interface{}, len(columns))
@@ -1143,7 +1240,11 @@ func (s *SchemaService) GetTableData(fullTableName string, limit, offset int, wh
 		for i, col := range columns {
 			val := values[i]
 			if b, ok := val.([]byte); ok {
-				rowData[col] = string(b)
+				if jsonColumns[i] && json.Valid(b) {
+					rowData[col] = json.RawMessage(b)
+				} else {
+					rowData[col] = string(b)
+				}
 			} else if b, ok := val.(time.Time); ok {
 				rowData[col] = b.Format("2006-01-02 15:04:05")
 			} else if b, ok := val.(*time.Time); ok {
@@ -1221,6 +1322,10 @@ func (s *SchemaService) extractSelectFromViewSQL(viewSQL string) string {
 
 // mapDataType 映射数据类型到PostgreSQL类型
 func (s *SchemaService) mapDataType(dataType string) string {
+	if models.IsGeoDataType(dataType) {
+		return geoColumnType(dataType)
+	}
+
 	typeMap := map[string]string{
 		"integer":   "integer",
 		"int":       "integer",
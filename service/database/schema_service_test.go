@@ -939,6 +939,8 @@ func TestMapDataType(t *testing.T) {
 		{"json", "json"},
 		{"jsonb", "jsonb"},
 		{"uuid", "uuid"},
+		{"geometry", "geometry(Geometry,4326)"},
+		{"geography", "geography(Geometry,4326)"},
 		{"unknown_type", "varchar(255)"}, // 未知类型默认为varchar
 	}
 
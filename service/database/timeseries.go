@@ -0,0 +1,222 @@
+/*
+ * @module service/database/timeseries
+ * @description TimescaleDB时间序列存储的DDL构建，包括超表转换、压缩和保留策略、降采样连续聚合以及降采样任务的汇总SQL
+ * @architecture 分层架构 - 业务服务层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow 时间序列配置 -> 启用timescaledb扩展 -> create_hypertable -> 压缩设置和压缩策略 -> 保留策略 -> 连续聚合物化视图和刷新策略
+ * @rules 除超表状态查询外只构建SQL，不执行；间隔须先经models.TimeSeriesIntervalPattern校验再拼入SQL；超表转换使用migrate_data迁移已有数据；
+ *        连续聚合的物化视图建在接口表所在schema，创建时不物化历史数据，由刷新策略按窗口补齐；连续聚合不能在事务中创建；
+ *        降采样任务按窗口先删除再汇总写入，窗口边界按不带时区的时间比较，重复运行结果相同
+ * @dependencies datahub-service/service/models, gorm.io/gorm
+ * @refs service/models/timeseries.go, service/basic_library/timeseries_service.go
+ */
+
+package database
+
+import (
+	"datahub-service/service/models"
+	"datahub-service/service/utils"
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// TimescaleExtensionSQL 启用TimescaleDB扩展
+const TimescaleExtensionSQL = "CREATE EXTENSION IF NOT EXISTS timescaledb"
+
+// timeSeriesTable 引用接口表的标识符和regclass字面量
+func timeSeriesTable(schemaName, tableName string) (string, string) {
+	table := utils.QuoteTable(schemaName, tableName)
+	return table, "'" + strings.ReplaceAll(table, "'", "''") + "'"
+}
+
+// BuildCreateHypertableSQL 把接口表转换为按时间列分块的超表，已有数据迁移到分块中
+func BuildCreateHypertableSQL(schemaName, tableName string, config *models.TimeSeriesConfig) string {
+	_, regclass := timeSeriesTable(schemaName, tableName)
+	chunkInterval := config.ChunkInterval
+	if chunkInterval == "" {
+		chunkInterval = models.DefaultTimeSeriesChunkInterval
+	}
+	return fmt.Sprintf("SELECT create_hypertable(%s, '%s', chunk_time_interval => INTERVAL '%s', migrate_data => true, if_not_exists => true)",
+		regclass, config.TimeColumn, chunkInterval)
+}
+
+// BuildSetChunkIntervalSQL 修改分块时间间隔，只对之后新建的分块生效
+func BuildSetChunkIntervalSQL(schemaName, tableName, chunkInterval string) string {
+	_, regclass := timeSeriesTable(schemaName, tableName)
+	return fmt.Sprintf("SELECT set_chunk_time_interval(%s, INTERVAL '%s')", regclass, chunkInterval)
+}
+
+// BuildCompressionSettingsSQL 开启超表压缩，按标签列分段、时间列倒序
+func BuildCompressionSettingsSQL(schemaName, tableName string, config *models.TimeSeriesConfig) string {
+	table, _ := timeSeriesTable(schemaName, tableName)
+	settings := []string{"timescaledb.compress"}
+	if len(config.TagColumns) > 0 {
+		settings = append(settings, fmt.Sprintf("timescaledb.compress_segmentby = '%s'", strings.ReplaceAll(quoteColumnList(config.TagColumns), "'", "''")))
+	}
+	settings = append(settings, fmt.Sprintf("timescaledb.compress_orderby = '%s DESC'", strings.ReplaceAll(utils.QuoteIdent(config.TimeColumn), "'", "''")))
+	return fmt.Sprintf("ALTER TABLE %s SET (%s)", table, strings.Join(settings, ", "))
+}
+
+// BuildCompressionPolicySQL 压缩超过compressAfter的分块
+func BuildCompressionPolicySQL(schemaName, tableName, compressAfter string) string {
+	_, regclass := timeSeriesTable(schemaName, tableName)
+	return fmt.Sprintf("SELECT add_compression_policy(%s, INTERVAL '%s', if_not_exists => true)", regclass, compressAfter)
+}
+
+// BuildRemoveCompressionPolicySQL 移除压缩策略，已压缩的分块保持压缩
+func BuildRemoveCompressionPolicySQL(schemaName, tableName string) string {
+	_, regclass := timeSeriesTable(schemaName, tableName)
+	return fmt.Sprintf("SELECT remove_compression_policy(%s, if_exists => true)", regclass)
+}
+
+// BuildRetentionPolicySQL 删除超过retention的分块
+func BuildRetentionPolicySQL(schemaName, tableName, retention string) string {
+	_, regclass := timeSeriesTable(schemaName, tableName)
+	return fmt.Sprintf("SELECT add_retention_policy(%s, INTERVAL '%s', if_not_exists => true)", regclass, retention)
+}
+
+// BuildRemoveRetentionPolicySQL 移除保留策略
+func BuildRemoveRetentionPolicySQL(schemaName, tableName string) string {
+	_, regclass := timeSeriesTable(schemaName, tableName)
+	return fmt.Sprintf("SELECT remove_retention_policy(%s, if_exists => true)", regclass)
+}
+
+// ContinuousAggregateViewName 连续聚合物化视图名
+func ContinuousAggregateViewName(tableName, aggregateName string) string {
+	return boundedIdentifier(tableName + "_" + aggregateName)
+}
+
+// BuildContinuousAggregateSQL 创建降采样连续聚合，按时间桶和标签列分组，创建时不物化历史数据
+func BuildContinuousAggregateSQL(schemaName, tableName string, config *models.TimeSeriesConfig, aggregate models.TimeSeriesAggregate) string {
+	table, _ := timeSeriesTable(schemaName, tableName)
+	view := utils.QuoteTable(schemaName, ContinuousAggregateViewName(tableName, aggregate.Name))
+	columns, groupBy := timeSeriesAggregateColumns(config.TimeColumn, config.TagColumns, aggregate.BucketInterval, aggregate.Metrics)
+	return fmt.Sprintf("CREATE MATERIALIZED VIEW %s WITH (timescaledb.continuous) AS SELECT %s FROM %s GROUP BY %s WITH NO DATA",
+		view, strings.Join(columns, ", "), table, strings.Join(groupBy, ", "))
+}
+
+// timeSeriesAggregateColumns 按时间桶和标签列分组的查询列和分组列，时间桶列名为bucket，指标列名为"列名_函数"
+func timeSeriesAggregateColumns(timeColumn string, tagColumns []string, bucketInterval string, metrics []models.TimeSeriesMetric) ([]string, []string) {
+	quotedTime := utils.QuoteIdent(timeColumn)
+	columns := []string{fmt.Sprintf("time_bucket(INTERVAL '%s', %s) AS %s", bucketInterval, quotedTime, models.RollupBucketColumn)}
+	groupBy := []string{models.RollupBucketColumn}
+	for _, tag := range tagColumns {
+		columns = append(columns, utils.QuoteIdent(tag))
+		groupBy = append(groupBy, utils.QuoteIdent(tag))
+	}
+	for _, metric := range metrics {
+		column := utils.QuoteIdent(metric.Column)
+		var expr string
+		switch metric.Function {
+		case models.TimeSeriesFuncFirst, models.TimeSeriesFuncLast:
+			expr = fmt.Sprintf("%s(%s, %s)", metric.Function, column, quotedTime)
+		default:
+			expr = fmt.Sprintf("%s(%s)", metric.Function, column)
+		}
+		columns = append(columns, expr+" AS "+utils.QuoteIdent(metric.Alias()))
+	}
+	return columns, groupBy
+}
+
+// BuildContinuousAggregatePolicySQL 连续聚合的定时刷新策略
+func BuildContinuousAggregatePolicySQL(schemaName, tableName string, aggregate models.TimeSeriesAggregate) string {
+	view := utils.QuoteTable(schemaName, ContinuousAggregateViewName(tableName, aggregate.Name))
+	return fmt.Sprintf("SELECT add_continuous_aggregate_policy('%s', start_offset => INTERVAL '%s', end_offset => INTERVAL '%s', schedule_interval => INTERVAL '%s')",
+		strings.ReplaceAll(view, "'", "''"), aggregate.RefreshStartOffset, aggregate.RefreshEndOffset, aggregate.ScheduleInterval)
+}
+
+// BuildDropContinuousAggregateSQL 删除连续聚合，刷新策略随之删除
+func BuildDropContinuousAggregateSQL(schemaName, tableName, aggregateName string) string {
+	return "DROP MATERIALIZED VIEW IF EXISTS " + utils.QuoteTable(schemaName, ContinuousAggregateViewName(tableName, aggregateName))
+}
+
+// rollupMaxBucketsPerRun 降采样任务单次运行最多汇总的时间桶数，补齐历史数据时分多次运行
+const rollupMaxBucketsPerRun = 1000
+
+// RollupTableName 降采样汇总表名
+func RollupTableName(tableName, rollupName string) string {
+	return boundedIdentifier(tableName + "_" + rollupName)
+}
+
+// BuildRollupWindowSQL 计算本次汇总的时间窗口[window_start, window_end)，参数为水位线，为NULL时从源表最早的数据开始；
+// 窗口起点为水位线减去回看时间所在的时间桶，终点为当前未完整时间桶的起点，最多rollupMaxBucketsPerRun个时间桶
+func BuildRollupWindowSQL(schemaName, sourceTable string, rollup *models.TimeSeriesRollup) string {
+	table, _ := timeSeriesTable(schemaName, sourceTable)
+	bucket := rollup.BucketInterval
+	return fmt.Sprintf(`SELECT w.window_start, LEAST(time_bucket(INTERVAL '%[1]s', LOCALTIMESTAMP), time_bucket(INTERVAL '%[1]s', w.window_start + INTERVAL '%[1]s' * %[2]d)) AS window_end
+		FROM (SELECT COALESCE(time_bucket(INTERVAL '%[1]s', ?::timestamp - INTERVAL '%[3]s'), (SELECT time_bucket(INTERVAL '%[1]s', min(%[4]s))::timestamp FROM %[5]s)) AS window_start) w`,
+		bucket, rollupMaxBucketsPerRun, rollup.Lookback, utils.QuoteIdent(rollup.TimeColumn), table)
+}
+
+// BuildRollupDeleteSQL 删除汇总表中窗口内的时间桶，参数为窗口起点和终点
+func BuildRollupDeleteSQL(schemaName string, rollup *models.TimeSeriesRollup) string {
+	table, _ := timeSeriesTable(schemaName, rollup.RollupTable)
+	bucket := utils.QuoteIdent(models.RollupBucketColumn)
+	return fmt.Sprintf("DELETE FROM %s WHERE %s >= ?::timestamp AND %s < ?::timestamp", table, bucket, bucket)
+}
+
+// BuildRollupInsertSQL 汇总源表窗口内的数据写入汇总表，参数为窗口起点和终点
+func BuildRollupInsertSQL(schemaName, sourceTable string, rollup *models.TimeSeriesRollup) string {
+	source, _ := timeSeriesTable(schemaName, sourceTable)
+	target, _ := timeSeriesTable(schemaName, rollup.RollupTable)
+	columns, groupBy := timeSeriesAggregateColumns(rollup.TimeColumn, rollup.TagColumns, rollup.BucketInterval, rollup.Metrics)
+	targetColumns := append([]string{models.RollupBucketColumn}, rollup.TagColumns...)
+	for _, metric := range rollup.Metrics {
+		targetColumns = append(targetColumns, metric.Alias())
+	}
+	timeColumn := utils.QuoteIdent(rollup.TimeColumn)
+	return fmt.Sprintf("INSERT INTO %s (%s) SELECT %s FROM %s WHERE %s >= ?::timestamp AND %s < ?::timestamp GROUP BY %s",
+		target, quoteColumnList(targetColumns), strings.Join(columns, ", "), source, timeColumn, timeColumn, strings.Join(groupBy, ", "))
+}
+
+// quoteColumnList 逗号分隔的带引号列名
+func quoteColumnList(columns []string) string {
+	quoted := make([]string, len(columns))
+	for i, column := range columns {
+		quoted[i] = utils.QuoteIdent(column)
+	}
+	return strings.Join(quoted, ",")
+}
+
+// HypertableStatus 接口表的超表状态
+type HypertableStatus struct {
+	ExtensionInstalled bool   `json:"extension_installed"` // 数据库是否已启用timescaledb扩展
+	IsHypertable       bool   `json:"is_hypertable"`
+	TimeColumn         string `json:"time_column,omitempty"` // 超表的分块时间列
+	NumChunks          int64  `json:"num_chunks"`
+	CompressionEnabled bool   `json:"compression_enabled"`
+}
+
+// QueryHypertableStatus 查询接口表是否已是超表，未启用timescaledb扩展时返回ExtensionInstalled=false
+func QueryHypertableStatus(db *gorm.DB, schemaName, tableName string) (*HypertableStatus, error) {
+	status := &HypertableStatus{}
+	if err := db.Raw("SELECT EXISTS (SELECT 1 FROM pg_extension WHERE extname = 'timescaledb')").Scan(&status.ExtensionInstalled).Error; err != nil {
+		return nil, fmt.Errorf("查询timescaledb扩展失败: %w", err)
+	}
+	if !status.ExtensionInstalled {
+		return status, nil
+	}
+
+	var rows []struct {
+		NumChunks          int64
+		CompressionEnabled bool
+		ColumnName         string
+	}
+	err := db.Raw(`SELECT h.num_chunks, h.compression_enabled, d.column_name
+		FROM timescaledb_information.hypertables h
+		JOIN timescaledb_information.dimensions d
+		  ON d.hypertable_schema = h.hypertable_schema AND d.hypertable_name = h.hypertable_name AND d.dimension_number = 1
+		WHERE h.hypertable_schema = ? AND h.hypertable_name = ?`, schemaName, tableName).Scan(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("查询超表状态失败: %w", err)
+	}
+	if len(rows) > 0 {
+		status.IsHypertable = true
+		status.TimeColumn = rows[0].ColumnName
+		status.NumChunks = rows[0].NumChunks
+		status.CompressionEnabled = rows[0].CompressionEnabled
+	}
+	return status, nil
+}
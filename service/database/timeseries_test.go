@@ -0,0 +1,74 @@
+/*
+ * @module service/database/timeseries_test
+ * @description TimescaleDB时间序列DDL构建测试，覆盖超表转换、压缩设置和策略、保留策略、连续聚合物化视图以及降采样任务的汇总SQL
+ * @architecture 测试层 - 单元测试
+ */
+
+package database
+
+import (
+	"datahub-service/service/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestTimeSeriesDDL 测试超表及其策略的DDL
+func TestTimeSeriesDDL(t *testing.T) {
+	config := &models.TimeSeriesConfig{TimeColumn: "collected_at", TagColumns: []string{"device_id", "region"}}
+
+	assert.Equal(t, `SELECT create_hypertable('"iot"."readings"', 'collected_at', chunk_time_interval => INTERVAL '7 days', migrate_data => true, if_not_exists => true)`,
+		BuildCreateHypertableSQL("iot", "readings", config))
+	assert.Equal(t, `ALTER TABLE "iot"."readings" SET (timescaledb.compress, timescaledb.compress_segmentby = '"device_id","region"', timescaledb.compress_orderby = '"collected_at" DESC')`,
+		BuildCompressionSettingsSQL("iot", "readings", config))
+	assert.Equal(t, `SELECT add_compression_policy('"iot"."readings"', INTERVAL '30 days', if_not_exists => true)`,
+		BuildCompressionPolicySQL("iot", "readings", "30 days"))
+	assert.Equal(t, `SELECT remove_retention_policy('"iot"."readings"', if_exists => true)`,
+		BuildRemoveRetentionPolicySQL("iot", "readings"))
+
+	config.TagColumns = nil
+	assert.Equal(t, `ALTER TABLE "iot"."readings" SET (timescaledb.compress, timescaledb.compress_orderby = '"collected_at" DESC')`,
+		BuildCompressionSettingsSQL("iot", "readings", config), "没有标签列时不分段")
+}
+
+// TestContinuousAggregateDDL 测试连续聚合物化视图和刷新策略的DDL
+func TestContinuousAggregateDDL(t *testing.T) {
+	config := &models.TimeSeriesConfig{TimeColumn: "collected_at", TagColumns: []string{"device_id"}}
+	aggregate := models.TimeSeriesAggregate{
+		Name: "hourly", BucketInterval: "1 hour",
+		Metrics:            []models.TimeSeriesMetric{{Column: "temperature", Function: "avg"}, {Column: "status", Function: "last"}},
+		RefreshStartOffset: "3 days", RefreshEndOffset: "1 hour", ScheduleInterval: "30 minutes",
+	}
+
+	assert.Equal(t, `CREATE MATERIALIZED VIEW "iot"."readings_hourly" WITH (timescaledb.continuous) AS `+
+		`SELECT time_bucket(INTERVAL '1 hour', "collected_at") AS bucket, "device_id", avg("temperature") AS "temperature_avg", last("status", "collected_at") AS "status_last" `+
+		`FROM "iot"."readings" GROUP BY bucket, "device_id" WITH NO DATA`,
+		BuildContinuousAggregateSQL("iot", "readings", config, aggregate))
+	assert.Equal(t, `SELECT add_continuous_aggregate_policy('"iot"."readings_hourly"', start_offset => INTERVAL '3 days', end_offset => INTERVAL '1 hour', schedule_interval => INTERVAL '30 minutes')`,
+		BuildContinuousAggregatePolicySQL("iot", "readings", aggregate))
+	assert.Equal(t, `DROP MATERIALIZED VIEW IF EXISTS "iot"."readings_hourly"`,
+		BuildDropContinuousAggregateSQL("iot", "readings", "hourly"))
+}
+
+// TestRollupSQL 测试降采样任务的窗口、删除和汇总写入SQL
+func TestRollupSQL(t *testing.T) {
+	rollup := &models.TimeSeriesRollup{
+		Name: "avg_5m", RollupTable: RollupTableName("readings", "avg_5m"),
+		TimeColumn: "collected_at", TagColumns: models.JSONBStringArray{"device_id"},
+		BucketInterval: "5 minutes", Lookback: "1 hour",
+		Metrics: models.TimeSeriesMetrics{{Column: "temperature", Function: "avg"}, {Column: "temperature", Function: "max"}},
+	}
+	assert.Equal(t, "readings_avg_5m", rollup.RollupTable)
+
+	window := BuildRollupWindowSQL("iot", "readings", rollup)
+	assert.Contains(t, window, `time_bucket(INTERVAL '5 minutes', ?::timestamp - INTERVAL '1 hour')`, "从水位线减回看时间开始")
+	assert.Contains(t, window, `(SELECT time_bucket(INTERVAL '5 minutes', min("collected_at"))::timestamp FROM "iot"."readings")`, "没有水位线时从最早的数据开始")
+	assert.Contains(t, window, `LEAST(time_bucket(INTERVAL '5 minutes', LOCALTIMESTAMP), time_bucket(INTERVAL '5 minutes', w.window_start + INTERVAL '5 minutes' * 1000))`, "只到当前未完整时间桶，且限制单次桶数")
+
+	assert.Equal(t, `DELETE FROM "iot"."readings_avg_5m" WHERE "bucket" >= ?::timestamp AND "bucket" < ?::timestamp`,
+		BuildRollupDeleteSQL("iot", rollup))
+	assert.Equal(t, `INSERT INTO "iot"."readings_avg_5m" ("bucket","device_id","temperature_avg","temperature_max") `+
+		`SELECT time_bucket(INTERVAL '5 minutes', "collected_at") AS bucket, "device_id", avg("temperature") AS "temperature_avg", max("temperature") AS "temperature_max" `+
+		`FROM "iot"."readings" WHERE "collected_at" >= ?::timestamp AND "collected_at" < ?::timestamp GROUP BY bucket, "device_id"`,
+		BuildRollupInsertSQL("iot", "readings", rollup))
+}
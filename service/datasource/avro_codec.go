@@ -0,0 +1,461 @@
+/*
+ * @module service/datasource/avro_codec
+ * @description Avro schema解析和二进制解码，把注册中心的Avro schema编译为字段清单和解码器，消息解码为字段名到取值的映射
+ * @architecture 工具函数 - 消息解码
+ * @documentReference ai_docs/datasource_req1.md
+ * @stateFlow Avro schema JSON -> 解析命名类型 -> 顶层record的字段清单 -> 按schema顺序读取二进制编码 -> 字段名 -> 取值
+ * @rules 顶层类型须为record；int/long解码为int64，float/double为float64，bytes/fixed为base64字符串，enum为符号名，嵌套record/map为对象，array为数组；
+ *        逻辑类型date、timestamp-millis、timestamp-micros解码为time.Time，decimal按scale解码为十进制字符串；联合类型直接取分支的值
+ * @dependencies encoding/json, encoding/binary, math/big
+ * @refs schema_registry.go
+ */
+
+package datasource
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"strings"
+	"time"
+)
+
+// avroType 编译后的Avro类型
+type avroType struct {
+	kind      string // null, boolean, int, long, float, double, bytes, string, record, enum, array, map, fixed, union
+	logical   string // date, timestamp-millis, timestamp-micros, decimal, uuid 等
+	name      string // 命名类型的全名
+	fields    []avroField
+	symbols   []string
+	items     *avroType   // array元素
+	values    *avroType   // map取值
+	branches  []*avroType // union分支
+	size      int         // fixed长度
+	scale     int         // decimal小数位数
+	precision int
+}
+
+// avroField record字段
+type avroField struct {
+	name       string
+	typ        *avroType
+	hasDefault bool
+}
+
+// avroParser 解析Avro schema，记录已定义的命名类型
+type avroParser struct {
+	named map[string]*avroType
+}
+
+// parseAvroSchema 解析Avro schema JSON，顶层须为record
+func parseAvroSchema(text string) (*avroType, error) {
+	var raw interface{}
+	if err := json.Unmarshal([]byte(text), &raw); err != nil {
+		return nil, fmt.Errorf("Avro schema不是合法的JSON: %w", err)
+	}
+	parser := &avroParser{named: make(map[string]*avroType)}
+	root, err := parser.parse(raw, "")
+	if err != nil {
+		return nil, err
+	}
+	if root.kind != "record" {
+		return nil, fmt.Errorf("Avro schema顶层须为record，实际为%s", root.kind)
+	}
+	return root, nil
+}
+
+// parse 解析一个类型定义，namespace为外层命名空间
+func (p *avroParser) parse(raw interface{}, namespace string) (*avroType, error) {
+	switch def := raw.(type) {
+	case string:
+		return p.reference(def, namespace)
+	case []interface{}:
+		union := &avroType{kind: "union"}
+		for _, branch := range def {
+			typ, err := p.parse(branch, namespace)
+			if err != nil {
+				return nil, err
+			}
+			union.branches = append(union.branches, typ)
+		}
+		return union, nil
+	case map[string]interface{}:
+		return p.parseComplex(def, namespace)
+	default:
+		return nil, fmt.Errorf("无法识别的Avro类型定义: %v", raw)
+	}
+}
+
+// reference 基本类型或已定义的命名类型
+func (p *avroParser) reference(name, namespace string) (*avroType, error) {
+	switch name {
+	case "null", "boolean", "int", "long", "float", "double", "bytes", "string":
+		return &avroType{kind: name}, nil
+	}
+	if typ, ok := p.named[name]; ok {
+		return typ, nil
+	}
+	if namespace != "" {
+		if typ, ok := p.named[namespace+"."+name]; ok {
+			return typ, nil
+		}
+	}
+	return nil, fmt.Errorf("Avro schema引用了未定义的类型%s", name)
+}
+
+// parseComplex 解析对象形式的类型定义
+func (p *avroParser) parseComplex(def map[string]interface{}, namespace string) (*avroType, error) {
+	kind, _ := def["type"].(string)
+	if kind == "" {
+		// {"type": {...}} 或 {"type": [...]} 形式的嵌套定义
+		if nested, ok := def["type"]; ok {
+			return p.parse(nested, namespace)
+		}
+		return nil, fmt.Errorf("Avro类型定义缺少type")
+	}
+	logical, _ := def["logicalType"].(string)
+
+	switch kind {
+	case "record", "error", "enum", "fixed":
+		typ := &avroType{kind: kind, logical: logical}
+		if kind == "error" {
+			typ.kind = "record"
+		}
+		typ.name, namespace = avroFullName(def, namespace)
+		if typ.name == "" {
+			return nil, fmt.Errorf("Avro %s类型缺少name", kind)
+		}
+		p.named[typ.name] = typ
+		if short := typ.name[strings.LastIndex(typ.name, ".")+1:]; short != typ.name {
+			if _, exists := p.named[short]; !exists {
+				p.named[short] = typ
+			}
+		}
+		switch typ.kind {
+		case "record":
+			fields, _ := def["fields"].([]interface{})
+			for _, item := range fields {
+				fieldDef, ok := item.(map[string]interface{})
+				if !ok {
+					return nil, fmt.Errorf("Avro record %s的字段定义格式错误", typ.name)
+				}
+				name, _ := fieldDef["name"].(string)
+				if name == "" {
+					return nil, fmt.Errorf("Avro record %s有字段缺少name", typ.name)
+				}
+				fieldType, err := p.parse(fieldDef["type"], namespace)
+				if err != nil {
+					return nil, fmt.Errorf("Avro字段%s.%s: %w", typ.name, name, err)
+				}
+				_, hasDefault := fieldDef["default"]
+				typ.fields = append(typ.fields, avroField{name: name, typ: fieldType, hasDefault: hasDefault})
+			}
+		case "enum":
+			symbols, _ := def["symbols"].([]interface{})
+			for _, symbol := range symbols {
+				typ.symbols = append(typ.symbols, fmt.Sprint(symbol))
+			}
+		case "fixed":
+			size, ok := def["size"].(float64)
+			if !ok || size < 0 {
+				return nil, fmt.Errorf("Avro fixed %s缺少size", typ.name)
+			}
+			typ.size = int(size)
+			typ.scale, typ.precision = avroDecimalScale(def)
+		}
+		return typ, nil
+	case "array":
+		items, err := p.parse(def["items"], namespace)
+		if err != nil {
+			return nil, err
+		}
+		return &avroType{kind: "array", items: items}, nil
+	case "map":
+		values, err := p.parse(def["values"], namespace)
+		if err != nil {
+			return nil, err
+		}
+		return &avroType{kind: "map", values: values}, nil
+	default:
+		typ, err := p.reference(kind, namespace)
+		if err != nil {
+			return nil, err
+		}
+		if logical == "" {
+			return typ, nil
+		}
+		annotated := *typ
+		annotated.logical = logical
+		annotated.scale, annotated.precision = avroDecimalScale(def)
+		return &annotated, nil
+	}
+}
+
+// avroFullName 命名类型的全名和其内部使用的命名空间
+func avroFullName(def map[string]interface{}, namespace string) (string, string) {
+	name, _ := def["name"].(string)
+	if ns, ok := def["namespace"].(string); ok {
+		namespace = ns
+	}
+	if strings.Contains(name, ".") {
+		return name, name[:strings.LastIndex(name, ".")]
+	}
+	if namespace != "" && name != "" {
+		return namespace + "." + name, namespace
+	}
+	return name, namespace
+}
+
+// avroDecimalScale 读取decimal的scale和precision
+func avroDecimalScale(def map[string]interface{}) (int, int) {
+	scale, _ := def["scale"].(float64)
+	precision, _ := def["precision"].(float64)
+	return int(scale), int(precision)
+}
+
+// avroFieldKind Avro类型对应的统一字段类型和是否可为空
+func avroFieldKind(typ *avroType) (string, bool) {
+	if typ.kind == "union" {
+		var nonNull []*avroType
+		for _, branch := range typ.branches {
+			if branch.kind != "null" {
+				nonNull = append(nonNull, branch)
+			}
+		}
+		nullable := len(nonNull) < len(typ.branches)
+		if len(nonNull) == 1 {
+			kind, _ := avroFieldKind(nonNull[0])
+			return kind, nullable
+		}
+		return schemaKindJSON, nullable
+	}
+	switch typ.logical {
+	case "date":
+		return schemaKindDate, false
+	case "timestamp-millis", "timestamp-micros", "local-timestamp-millis", "local-timestamp-micros":
+		return schemaKindTimestamp, false
+	case "decimal":
+		return schemaKindDecimal, false
+	}
+	switch typ.kind {
+	case "boolean", "int", "long", "float", "double", "bytes", "string", "enum":
+		return typ.kind, false
+	case "fixed":
+		return "bytes", false
+	case "null":
+		return schemaKindJSON, true
+	default:
+		return schemaKindJSON, false
+	}
+}
+
+// avroSchemaFields 顶层record的统一字段清单
+func avroSchemaFields(root *avroType) []schemaField {
+	fields := make([]schemaField, len(root.fields))
+	for i, field := range root.fields {
+		kind, nullable := avroFieldKind(field.typ)
+		fields[i] = schemaField{Name: field.name, Kind: kind, Nullable: nullable, HasDefault: field.hasDefault}
+	}
+	return fields
+}
+
+// avroReader Avro二进制读取器
+type avroReader struct {
+	data []byte
+	pos  int
+}
+
+// decodeAvroRecord 按schema解码一条消息，返回字段名 -> 取值
+func decodeAvroRecord(root *avroType, data []byte) (map[string]interface{}, error) {
+	reader := &avroReader{data: data}
+	value, err := reader.read(root)
+	if err != nil {
+		return nil, fmt.Errorf("Avro解码失败(偏移%d): %w", reader.pos, err)
+	}
+	if reader.pos != len(data) {
+		return nil, fmt.Errorf("Avro解码失败: 消息末尾有%d字节未按schema读取，消息可能不是用该schema编码的", len(data)-reader.pos)
+	}
+	return value.(map[string]interface{}), nil
+}
+
+func (r *avroReader) read(typ *avroType) (interface{}, error) {
+	switch typ.kind {
+	case "null":
+		return nil, nil
+	case "boolean":
+		b, err := r.bytes(1)
+		if err != nil {
+			return nil, err
+		}
+		return b[0] != 0, nil
+	case "int", "long":
+		v, err := r.long()
+		if err != nil {
+			return nil, err
+		}
+		return avroLogicalLong(typ.logical, v), nil
+	case "float":
+		b, err := r.bytes(4)
+		if err != nil {
+			return nil, err
+		}
+		return float64(math.Float32frombits(binary.LittleEndian.Uint32(b))), nil
+	case "double":
+		b, err := r.bytes(8)
+		if err != nil {
+			return nil, err
+		}
+		return math.Float64frombits(binary.LittleEndian.Uint64(b)), nil
+	case "bytes", "string":
+		size, err := r.long()
+		if err != nil {
+			return nil, err
+		}
+		if size < 0 {
+			return nil, fmt.Errorf("长度为负数")
+		}
+		b, err := r.bytes(int(size))
+		if err != nil {
+			return nil, err
+		}
+		if typ.kind == "string" {
+			return string(b), nil
+		}
+		return avroBytesValue(typ, b), nil
+	case "fixed":
+		b, err := r.bytes(typ.size)
+		if err != nil {
+			return nil, err
+		}
+		return avroBytesValue(typ, b), nil
+	case "enum":
+		index, err := r.long()
+		if err != nil {
+			return nil, err
+		}
+		if index < 0 || int(index) >= len(typ.symbols) {
+			return nil, fmt.Errorf("enum %s的序号%d超出范围", typ.name, index)
+		}
+		return typ.symbols[index], nil
+	case "union":
+		index, err := r.long()
+		if err != nil {
+			return nil, err
+		}
+		if index < 0 || int(index) >= len(typ.branches) {
+			return nil, fmt.Errorf("union分支序号%d超出范围", index)
+		}
+		return r.read(typ.branches[index])
+	case "record":
+		record := make(map[string]interface{}, len(typ.fields))
+		for _, field := range typ.fields {
+			value, err := r.read(field.typ)
+			if err != nil {
+				return nil, fmt.Errorf("字段%s: %w", field.name, err)
+			}
+			record[field.name] = value
+		}
+		return record, nil
+	case "array":
+		items := []interface{}{}
+		err := r.blocks(func() error {
+			value, err := r.read(typ.items)
+			items = append(items, value)
+			return err
+		})
+		return items, err
+	case "map":
+		entries := map[string]interface{}{}
+		err := r.blocks(func() error {
+			key, err := r.read(&avroType{kind: "string"})
+			if err != nil {
+				return err
+			}
+			value, err := r.read(typ.values)
+			entries[key.(string)] = value
+			return err
+		})
+		return entries, err
+	default:
+		return nil, fmt.Errorf("不支持的Avro类型%s", typ.kind)
+	}
+}
+
+// blocks 读取array/map的分块编码，块计数为负时后跟块字节数
+func (r *avroReader) blocks(readItem func() error) error {
+	for {
+		count, err := r.long()
+		if err != nil {
+			return err
+		}
+		if count == 0 {
+			return nil
+		}
+		if count < 0 {
+			count = -count
+			if _, err := r.long(); err != nil {
+				return err
+			}
+		}
+		for i := int64(0); i < count; i++ {
+			if err := readItem(); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// long 读取zigzag变长整数
+func (r *avroReader) long() (int64, error) {
+	var value uint64
+	for shift := uint(0); shift < 64; shift += 7 {
+		if r.pos >= len(r.data) {
+			return 0, fmt.Errorf("数据意外结束")
+		}
+		b := r.data[r.pos]
+		r.pos++
+		value |= uint64(b&0x7f) << shift
+		if b&0x80 == 0 {
+			return int64(value>>1) ^ -int64(value&1), nil
+		}
+	}
+	return 0, fmt.Errorf("变长整数过长")
+}
+
+func (r *avroReader) bytes(n int) ([]byte, error) {
+	if n < 0 || r.pos+n > len(r.data) {
+		return nil, fmt.Errorf("数据意外结束")
+	}
+	b := r.data[r.pos : r.pos+n]
+	r.pos += n
+	return b, nil
+}
+
+// avroLogicalLong 按逻辑类型转换整数取值
+func avroLogicalLong(logical string, v int64) interface{} {
+	switch logical {
+	case "date":
+		return time.Unix(v*86400, 0).UTC()
+	case "timestamp-millis", "local-timestamp-millis":
+		return time.UnixMilli(v).UTC()
+	case "timestamp-micros", "local-timestamp-micros":
+		return time.UnixMicro(v).UTC()
+	}
+	return v
+}
+
+// avroBytesValue decimal按scale转为十进制字符串，其余字节取值转为base64
+func avroBytesValue(typ *avroType, b []byte) interface{} {
+	if typ.logical != "decimal" {
+		return base64.StdEncoding.EncodeToString(b)
+	}
+	unscaled := new(big.Int).SetBytes(b)
+	if len(b) > 0 && b[0]&0x80 != 0 {
+		// 二进制补码表示的负数
+		unscaled.Sub(unscaled, new(big.Int).Lsh(big.NewInt(1), uint(len(b)*8)))
+	}
+	return new(big.Rat).SetFrac(unscaled, new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(typ.scale)), nil)).FloatString(typ.scale)
+}
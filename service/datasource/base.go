@@ -19,6 +19,7 @@ import (
 	"time"
 
 	"datahub-service/service/models"
+	"datahub-service/service/secrets"
 
 	"github.com/traefik/yaegi/interp"
 	"github.com/traefik/yaegi/stdlib"
@@ -46,7 +47,7 @@ func NewBaseDataSource(dsType string, isResident bool) *BaseDataSource {
 	}
 }
 
-// Init 初始化数据源
+// Init 初始化数据源，子类应通过GetDataSource读取已解析密钥引用的配置
 func (b *BaseDataSource) Init(ctx context.Context, ds *models.DataSource) error {
 	b.mu.Lock()
 	defer b.mu.Unlock()
@@ -59,13 +60,34 @@ func (b *BaseDataSource) Init(ctx context.Context, ds *models.DataSource) error
 		return fmt.Errorf("数据源 %s 已经初始化", ds.ID)
 	}
 
+	resolved, err := resolveDataSourceSecrets(ctx, ds)
+	if err != nil {
+		return err
+	}
+
 	b.id = ds.ID
-	b.dataSource = ds
+	b.dataSource = resolved
 	b.isInitialized = true
 
 	return nil
 }
 
+// resolveDataSourceSecrets 复制数据源并解析连接配置和参数配置中的密钥引用，原数据源保持不变
+func resolveDataSourceSecrets(ctx context.Context, ds *models.DataSource) (*models.DataSource, error) {
+	connectionConfig, err := secrets.ResolveConfig(ctx, ds.ConnectionConfig)
+	if err != nil {
+		return nil, fmt.Errorf("解析数据源 %s 的连接配置失败: %w", ds.ID, err)
+	}
+	paramsConfig, err := secrets.ResolveConfig(ctx, ds.ParamsConfig)
+	if err != nil {
+		return nil, fmt.Errorf("解析数据源 %s 的参数配置失败: %w", ds.ID, err)
+	}
+	resolved := *ds
+	resolved.ConnectionConfig = connectionConfig
+	resolved.ParamsConfig = paramsConfig
+	return &resolved, nil
+}
+
 // Start 启动数据源（基础实现为空，子类重写）
 func (b *BaseDataSource) Start(ctx context.Context) error {
 	b.mu.Lock()
@@ -13,6 +13,7 @@ package datasource
 
 import (
 	"context"
+	"datahub-service/service/tracing"
 	"fmt"
 	"net/http"
 	"sync"
@@ -180,11 +181,11 @@ func (p *HTTPConnectionPool) Stats() map[string]interface{} {
 func (p *HTTPConnectionPool) createConnection() *PooledConnection {
 	client := &http.Client{
 		Timeout: p.timeout,
-		Transport: &http.Transport{
+		Transport: tracing.NewTransport(&http.Transport{
 			MaxIdleConns:        100,
 			MaxIdleConnsPerHost: 10,
 			IdleConnTimeout:     90 * time.Second,
-		},
+		}),
 	}
 
 	return &PooledConnection{
@@ -0,0 +1,270 @@
+/*
+ * @module service/basic_library/datasource/fixture
+ * @description 模拟数据源实现，按接口URL后缀返回预置样例数据，用于在无法访问源系统时调试和测试接口的解析、映射和清洗配置
+ * @architecture 与HTTP数据源相同的API类别，请求构建和响应解析流程不变，只把网络请求替换为样例查找
+ * @documentReference ai_docs/datasource_req.md, service/meta/datasource.go
+ * @stateFlow 请求 -> 按 "方法 路径"、路径、"*" 依次查找样例 -> 按分页参数切分数据路径下的数组 -> 序列化为响应体 -> 响应解析器或默认解析
+ * @rules 样例可以是JSON对象、数组或JSON字符串；带页码和页大小参数时按页返回，超出范围返回空数组，保证批量同步能正常结束；
+ *        未找到样例时返回错误而不是空数据，避免配置错误被当作源系统无数据
+ * @dependencies encoding/json, github.com/spf13/cast
+ * @refs http_no_auth.go, response_parser.go, preview_reader.go, query_builder.go
+ */
+
+package datasource
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"datahub-service/service/meta"
+	"datahub-service/service/models"
+
+	"github.com/spf13/cast"
+)
+
+// FixtureDataSource 模拟数据源实现
+type FixtureDataSource struct {
+	*BaseDataSource
+	fixtures map[string]interface{}
+}
+
+// NewFixtureDataSource 创建模拟数据源
+func NewFixtureDataSource() DataSourceInterface {
+	return &FixtureDataSource{
+		BaseDataSource: NewBaseDataSource(meta.DataSourceTypeApiMock, false),
+	}
+}
+
+// Init 初始化模拟数据源，读取连接配置中的样例数据
+func (f *FixtureDataSource) Init(ctx context.Context, ds *models.DataSource) error {
+	if err := f.BaseDataSource.Init(ctx, ds); err != nil {
+		return err
+	}
+	ds = f.GetDataSource()
+
+	fixtures, ok := ds.ConnectionConfig[meta.DataSourceFieldFixtures].(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("样例数据配置错误，%s 必须是路径到响应内容的映射", meta.DataSourceFieldFixtures)
+	}
+	f.fixtures = fixtures
+	return nil
+}
+
+// Execute 返回与请求路径匹配的样例数据
+func (f *FixtureDataSource) Execute(ctx context.Context, request *ExecuteRequest) (*ExecuteResponse, error) {
+	startTime := time.Now()
+	response := &ExecuteResponse{
+		Success:   false,
+		Timestamp: startTime,
+		Metadata:  make(map[string]interface{}),
+	}
+
+	if !f.IsInitialized() {
+		response.Error = "数据源未初始化"
+		response.Duration = time.Since(startTime)
+		return response, fmt.Errorf("数据源未初始化")
+	}
+
+	requestData, _ := request.Data.(map[string]interface{})
+	method := strings.ToUpper(cast.ToString(requestData["method"]))
+	if method == "" {
+		method = http.MethodGet
+	}
+	dataPath := "data"
+	if dp, ok := requestData["data_path"].(string); ok {
+		dataPath = dp
+	}
+	parserConfig, _ := requestData["response_parser"].(map[string]interface{})
+	if dp := cast.ToString(parserConfig[meta.DataInterfaceConfigFieldDataPath]); dp != "" {
+		dataPath = dp
+	}
+
+	path := normalizeFixturePath(request.Query)
+	key, payload, err := f.lookupFixture(method, path)
+	if err != nil {
+		response.Error = err.Error()
+		response.Duration = time.Since(startTime)
+		return response, err
+	}
+
+	if page, size, ok := fixturePageParams(request.Params, requestData); ok {
+		payload = pageFixturePayload(payload, dataPath, page, size)
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		response.Error = fmt.Sprintf("序列化样例数据失败: %v", err)
+		response.Duration = time.Since(startTime)
+		return response, err
+	}
+	if str, ok := payload.(string); ok {
+		// 非JSON样例按原文返回，交给解析器按响应类型处理
+		body = []byte(str)
+	}
+	body, err = readResponseBody(bytes.NewReader(body), request)
+	if err != nil {
+		response.Error = fmt.Sprintf("读取样例数据失败: %v", err)
+		response.Duration = time.Since(startTime)
+		return response, err
+	}
+
+	response.Metadata["status_code"] = http.StatusOK
+	response.Metadata["url"] = path
+	response.Metadata["method"] = method
+	response.Metadata["data_path"] = dataPath
+	response.Metadata["fixture"] = key
+
+	if parserConfig != nil {
+		parsed, err := NewResponseParser(parserConfig).Parse(http.StatusOK, body, nil)
+		if err != nil {
+			response.Error = fmt.Sprintf("响应解析失败: %v", err)
+			response.Data = string(body)
+		} else {
+			response.Success = parsed.Success
+			response.Data = parsed.Data
+			response.Error = parsed.ErrorMessage
+			if parsed.Total > 0 {
+				response.Metadata["total"] = parsed.Total
+			}
+			for k, v := range parsed.Metadata {
+				response.Metadata[k] = v
+			}
+		}
+	} else {
+		(&HTTPNoAuthDataSource{}).handleResponseFallback(http.StatusOK, body, dataPath, response)
+	}
+
+	response.Duration = time.Since(startTime)
+	return response, nil
+}
+
+// HealthCheck 模拟数据源不依赖外部系统，初始化后即在线
+func (f *FixtureDataSource) HealthCheck(ctx context.Context) (*HealthStatus, error) {
+	status, err := f.BaseDataSource.HealthCheck(ctx)
+	if err != nil || status.Status != "online" {
+		return status, err
+	}
+	status.Details["fixtures"] = len(f.fixtures)
+	return status, nil
+}
+
+// lookupFixture 按 "方法 路径"、路径、默认键的顺序查找样例，字符串样例是合法JSON时先解析
+func (f *FixtureDataSource) lookupFixture(method, path string) (string, interface{}, error) {
+	for _, key := range []string{method + " " + path, path, meta.MockFixtureFallbackKey} {
+		payload, exists := f.fixtures[key]
+		if !exists {
+			continue
+		}
+		if str, ok := payload.(string); ok {
+			var parsed interface{}
+			if err := json.Unmarshal([]byte(str), &parsed); err == nil {
+				payload = parsed
+			}
+		}
+		return key, payload, nil
+	}
+	return "", nil, fmt.Errorf("未找到路径 %s %s 的样例数据", method, path)
+}
+
+// normalizeFixturePath 去掉查询串并补全前导斜杠，使接口的URL后缀与样例键一致
+func normalizeFixturePath(query string) string {
+	if idx := strings.IndexByte(query, '?'); idx >= 0 {
+		query = query[:idx]
+	}
+	if !strings.HasPrefix(query, "/") {
+		query = "/" + query
+	}
+	return query
+}
+
+// fixturePageParams 按分页配置中的参数名读取页码和页大小，返回从0开始的页序号
+func fixturePageParams(params, requestData map[string]interface{}) (int, int, bool) {
+	pageParam, sizeParam, pageStart := "page", "size", 1
+	if pagination, ok := requestData["pagination"].(map[string]interface{}); ok {
+		if name := cast.ToString(pagination["page_param"]); name != "" {
+			pageParam = name
+		}
+		if name := cast.ToString(pagination["size_param"]); name != "" {
+			sizeParam = name
+		}
+		if _, exists := pagination["page_start"]; exists {
+			pageStart = cast.ToInt(pagination["page_start"])
+		}
+	}
+
+	pageValue, hasPage := params[pageParam]
+	sizeValue, hasSize := params[sizeParam]
+	if !hasPage || !hasSize {
+		return 0, 0, false
+	}
+	size := cast.ToInt(sizeValue)
+	page := cast.ToInt(pageValue) - pageStart
+	if size <= 0 || page < 0 {
+		return 0, 0, false
+	}
+	return page, size, true
+}
+
+// pageFixturePayload 截取数据路径下数组的一页，路径上的对象浅拷贝，不修改样例本身；
+// 路径不存在时与默认解析一致，对顶层数组分页
+func pageFixturePayload(payload interface{}, dataPath string, page, size int) interface{} {
+	var parts []string
+	for _, part := range strings.Split(dataPath, ".") {
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+
+	var paged func(node interface{}, depth int) (interface{}, bool)
+	paged = func(node interface{}, depth int) (interface{}, bool) {
+		if depth == len(parts) {
+			items, ok := node.([]interface{})
+			if !ok {
+				return node, false
+			}
+			start := page * size
+			if start >= len(items) {
+				return []interface{}{}, true
+			}
+			end := start + size
+			if end > len(items) {
+				end = len(items)
+			}
+			return items[start:end], true
+		}
+
+		obj, ok := node.(map[string]interface{})
+		if !ok {
+			return node, false
+		}
+		child, exists := obj[parts[depth]]
+		if !exists {
+			return node, false
+		}
+		replaced, ok := paged(child, depth+1)
+		if !ok {
+			return node, false
+		}
+		copied := make(map[string]interface{}, len(obj))
+		for k, v := range obj {
+			copied[k] = v
+		}
+		copied[parts[depth]] = replaced
+		return copied, true
+	}
+
+	if result, ok := paged(payload, 0); ok {
+		return result
+	}
+	if _, ok := payload.([]interface{}); ok {
+		parts = nil
+		result, _ := paged(payload, 0)
+		return result
+	}
+	return payload
+}
@@ -0,0 +1,88 @@
+/*
+ * @module service/datasource/fixture_test
+ * @description 模拟数据源测试，覆盖样例查找顺序、JSON字符串样例、分页切分和响应解析配置
+ * @architecture 单元测试
+ * @refs fixture.go, response_parser.go
+ */
+
+package datasource
+
+import (
+	"context"
+	"testing"
+
+	"datahub-service/service/meta"
+	"datahub-service/service/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestFixtureDataSource(t *testing.T, fixtures map[string]interface{}) DataSourceInterface {
+	ds := NewFixtureDataSource()
+	require.NoError(t, ds.Init(context.Background(), &models.DataSource{
+		ID:               "fixture-ds",
+		Type:             meta.DataSourceTypeApiMock,
+		ConnectionConfig: models.JSONB{meta.DataSourceFieldFixtures: fixtures},
+	}))
+	return ds
+}
+
+func TestFixtureDataSourceLookup(t *testing.T) {
+	ds := newTestFixtureDataSource(t, map[string]interface{}{
+		"/devices":      map[string]interface{}{"data": []interface{}{map[string]interface{}{"id": "get"}}},
+		"POST /devices": `{"data": [{"id": "post"}]}`,
+		"*":             map[string]interface{}{"data": []interface{}{}},
+	})
+	ctx := context.Background()
+
+	resp, err := ds.Execute(ctx, &ExecuteRequest{Query: "devices?x=1", Data: map[string]interface{}{"method": "GET"}})
+	require.NoError(t, err)
+	assert.True(t, resp.Success)
+	assert.Equal(t, []interface{}{map[string]interface{}{"id": "get"}}, resp.Data)
+	assert.Equal(t, "/devices", resp.Metadata["fixture"])
+
+	resp, err = ds.Execute(ctx, &ExecuteRequest{Query: "/devices", Data: map[string]interface{}{"method": "post"}})
+	require.NoError(t, err)
+	assert.Equal(t, []interface{}{map[string]interface{}{"id": "post"}}, resp.Data, "带方法前缀的样例优先，JSON字符串先解析")
+
+	resp, err = ds.Execute(ctx, &ExecuteRequest{Query: "/unknown"})
+	require.NoError(t, err)
+	assert.Equal(t, "*", resp.Metadata["fixture"])
+
+	strict := newTestFixtureDataSource(t, map[string]interface{}{"/devices": []interface{}{}})
+	_, err = strict.Execute(ctx, &ExecuteRequest{Query: "/unknown"})
+	assert.ErrorContains(t, err, "未找到路径 GET /unknown 的样例数据")
+}
+
+func TestFixtureDataSourcePagination(t *testing.T) {
+	items := make([]interface{}, 5)
+	for i := range items {
+		items[i] = map[string]interface{}{"id": float64(i + 1)}
+	}
+	fixture := map[string]interface{}{"code": float64(0), "result": map[string]interface{}{"list": items}}
+	ds := newTestFixtureDataSource(t, map[string]interface{}{"/devices": fixture})
+
+	page := func(pageNo int) []interface{} {
+		resp, err := ds.Execute(context.Background(), &ExecuteRequest{
+			Query:  "/devices",
+			Params: map[string]interface{}{"pageNo": pageNo, "pageSize": 2},
+			Data: map[string]interface{}{
+				"pagination":      map[string]interface{}{"page_param": "pageNo", "size_param": "pageSize", "page_start": 0},
+				"response_parser": map[string]interface{}{"data_path": "result.list"},
+			},
+		})
+		require.NoError(t, err)
+		require.True(t, resp.Success)
+		list, _ := resp.Data.([]interface{})
+		return list
+	}
+
+	assert.Len(t, page(0), 2)
+	assert.Equal(t, []interface{}{map[string]interface{}{"id": float64(5)}}, page(2))
+	assert.Empty(t, page(3), "超出范围返回空页，批量同步据此结束")
+	assert.Len(t, fixture["result"].(map[string]interface{})["list"], 5, "分页不修改样例本身")
+
+	// 路径不存在时对顶层数组分页
+	assert.Equal(t, []interface{}{items[2], items[3]}, pageFixturePayload(items, "data", 1, 2))
+}
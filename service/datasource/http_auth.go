@@ -26,6 +26,7 @@ import (
 
 	"datahub-service/service/meta"
 	"datahub-service/service/models"
+	"datahub-service/service/tracing"
 )
 
 // HTTPAuthDataSource HTTP认证数据源实现
@@ -57,7 +58,8 @@ func NewHTTPAuthDataSource() DataSourceInterface {
 	return &HTTPAuthDataSource{
 		BaseDataSource: base,
 		client: &http.Client{
-			Timeout: 30 * time.Second,
+			Timeout:   30 * time.Second,
+			Transport: tracing.NewTransport(nil),
 		},
 		credentials:            make(map[string]interface{}),
 		sessionData:            make(map[string]interface{}),
@@ -73,6 +75,7 @@ func (h *HTTPAuthDataSource) Init(ctx context.Context, ds *models.DataSource) er
 	if err := h.BaseDataSource.Init(ctx, ds); err != nil {
 		return err
 	}
+	ds = h.GetDataSource()
 
 	// 解析连接配置
 	config := ds.ConnectionConfig
@@ -384,7 +387,7 @@ func (h *HTTPAuthDataSource) executeHTTPRequest(ctx context.Context, request *Ex
 	defer httpResp.Body.Close()
 
 	// 读取响应体
-	respBody, err := io.ReadAll(httpResp.Body)
+	respBody, err := readResponseBody(httpResp.Body, request)
 	if err != nil {
 		response.Error = fmt.Sprintf("读取响应体失败: %v", err)
 		response.Duration = time.Since(startTime)
@@ -25,6 +25,7 @@ import (
 
 	"datahub-service/service/meta"
 	"datahub-service/service/models"
+	"datahub-service/service/tracing"
 )
 
 // HTTPNoAuthDataSource HTTP无认证数据源实现
@@ -40,7 +41,8 @@ func NewHTTPNoAuthDataSource() DataSourceInterface {
 	return &HTTPNoAuthDataSource{
 		BaseDataSource: base,
 		client: &http.Client{
-			Timeout: 30 * time.Second,
+			Timeout:   30 * time.Second,
+			Transport: tracing.NewTransport(nil),
 		},
 	}
 }
@@ -50,6 +52,7 @@ func (h *HTTPNoAuthDataSource) Init(ctx context.Context, ds *models.DataSource)
 	if err := h.BaseDataSource.Init(ctx, ds); err != nil {
 		return err
 	}
+	ds = h.GetDataSource()
 
 	// 解析连接配置
 	config := ds.ConnectionConfig
@@ -266,7 +269,7 @@ func (h *HTTPNoAuthDataSource) executeHTTPRequest(ctx context.Context, request *
 
 	// 读取响应体
 	slog.Debug("HTTPNoAuthDataSource.executeHTTPRequest - 读取响应体")
-	respBody, err := io.ReadAll(httpResp.Body)
+	respBody, err := readResponseBody(httpResp.Body, request)
 	if err != nil {
 		slog.Error("HTTPNoAuthDataSource.executeHTTPRequest - 读取响应体失败", "error", err)
 		response.Error = fmt.Sprintf("读取响应体失败: %v", err)
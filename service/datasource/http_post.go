@@ -4,7 +4,8 @@
  * @architecture 观察者模式 - 监听HTTP请求并处理数据
  * @documentReference ai_docs/datasource_req1.md
  * @stateFlow HTTP服务器生命周期：创建 -> 启动监听 -> 接收请求 -> 处理数据 -> 停止服务
- * @rules 支持认证、请求体大小限制、超时控制
+ * @rules 支持认证、请求体大小限制、超时控制；自动写入时按关联接口的JSON Schema校验JSON数据，不合规返回422和违规清单(validate_payload=false关闭)；
+ *        非JSON请求体另交给配置了Protobuf解析的接口按接口的.proto解码
  * @dependencies net/http, context, sync, encoding/json
  * @refs interface.go, base.go
  */
@@ -24,6 +25,12 @@ import (
 	"log/slog"
 )
 
+// 非JSON请求体保存为原始内容时使用的键
+const (
+	webhookRawDataKey     = "raw_data"
+	webhookContentTypeKey = "content_type"
+)
+
 // HTTPPostDataSource HTTP POST数据源实现
 type HTTPPostDataSource struct {
 	*BaseDataSource
@@ -41,15 +48,17 @@ type HTTPPostDataSource struct {
 	// 实时数据处理
 	realtimeProcessor RealtimeDataProcessor // 实时数据处理器
 	enableAutoWrite   bool                  // 是否启用自动写入
+	validatePayload   bool                  // 自动写入前是否按关联接口的JSON Schema校验
 }
 
 // NewHTTPPostDataSource 创建HTTP POST数据源
 func NewHTTPPostDataSource() DataSourceInterface {
 	return &HTTPPostDataSource{
-		BaseDataSource: NewBaseDataSource("http_post", true), // 常驻数据源
-		receivedData:   make([]map[string]interface{}, 0),
-		dataChannel:    make(chan map[string]interface{}, 1000), // 缓冲通道
-		subscribers:    make([]chan map[string]interface{}, 0),
+		BaseDataSource:  NewBaseDataSource("http_post", true), // 常驻数据源
+		receivedData:    make([]map[string]interface{}, 0),
+		dataChannel:     make(chan map[string]interface{}, 1000), // 缓冲通道
+		subscribers:     make([]chan map[string]interface{}, 0),
+		validatePayload: true,
 	}
 }
 
@@ -58,6 +67,7 @@ func (h *HTTPPostDataSource) Init(ctx context.Context, ds *models.DataSource) er
 	if err := h.BaseDataSource.Init(ctx, ds); err != nil {
 		return err
 	}
+	ds = h.GetDataSource()
 
 	// 解析连接配置
 	config := ds.ConnectionConfig
@@ -143,6 +153,13 @@ func (h *HTTPPostDataSource) parseParamsConfig(params map[string]interface{}) {
 			h.enableAutoWrite = enabled
 		}
 	}
+
+	// 是否校验JSON Schema
+	if validatePayload, exists := params["validate_payload"]; exists {
+		if enabled, ok := validatePayload.(bool); ok {
+			h.validatePayload = enabled
+		}
+	}
 }
 
 // Start 启动HTTP POST数据源
@@ -205,9 +222,20 @@ func (h *HTTPPostDataSource) HandleWebhook(w http.ResponseWriter, r *http.Reques
 	if err := json.Unmarshal(body, &data); err != nil {
 		// 如果不是JSON，保存为字符串
 		data = map[string]interface{}{
-			"raw_data":     string(body),
-			"content_type": r.Header.Get("Content-Type"),
+			webhookRawDataKey:     string(body),
+			webhookContentTypeKey: r.Header.Get("Content-Type"),
 		}
+	} else if failures := h.validateWebhookPayload(data); len(failures) > 0 {
+		// 不符合关联接口JSON Schema的数据直接拒绝，返回违规清单，避免写入时才失败
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"success":    false,
+			"message":    "数据不符合接口的JSON Schema",
+			"code":       http.StatusUnprocessableEntity,
+			"violations": failures,
+		})
+		return
 	}
 
 	// 添加元数据
@@ -241,6 +269,14 @@ func (h *HTTPPostDataSource) HandleWebhook(w http.ResponseWriter, r *http.Reques
 	})
 }
 
+// validateWebhookPayload 自动写入时按关联接口的JSON Schema校验数据
+func (h *HTTPPostDataSource) validateWebhookPayload(data map[string]interface{}) []InterfacePayloadValidation {
+	if !h.validatePayload || !h.enableAutoWrite || h.realtimeProcessor == nil {
+		return nil
+	}
+	return h.realtimeProcessor.ValidateRealtimeData(h.GetID(), data)
+}
+
 // processData 处理接收到的数据
 func (h *HTTPPostDataSource) processData() {
 	for data := range h.dataChannel {
@@ -265,10 +301,30 @@ func (h *HTTPPostDataSource) processData() {
 					"datasource_id", h.GetID(),
 					"error", err)
 			}
+			// 非JSON请求体交给配置了Protobuf解析的接口解码
+			if payload, ok := webhookRawPayload(data); ok {
+				if err := h.realtimeProcessor.ProcessRealtimePayload(ctx, h.GetID(), payload); err != nil {
+					slog.Error("实时处理二进制数据失败",
+						"datasource_id", h.GetID(),
+						"error", err)
+				}
+			}
 		}
 	}
 }
 
+// webhookRawPayload 取非JSON请求体的原始内容，JSON请求体返回false
+func webhookRawPayload(data map[string]interface{}) ([]byte, bool) {
+	raw, ok := data[webhookRawDataKey].(string)
+	if !ok {
+		return nil, false
+	}
+	if _, nonJSON := data[webhookContentTypeKey]; !nonJSON {
+		return nil, false
+	}
+	return []byte(raw), true
+}
+
 // notifySubscribers 通知所有订阅者
 func (h *HTTPPostDataSource) notifySubscribers(data map[string]interface{}) {
 	h.subscribersMu.RLock()
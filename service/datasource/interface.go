@@ -58,6 +58,7 @@ type ExecuteRequest struct {
 	Data      interface{}            `json:"data,omitempty"`
 	Params    map[string]interface{} `json:"params,omitempty"`
 	Timeout   time.Duration          `json:"timeout,omitempty"`
+	MaxRows   int                    `json:"max_rows,omitempty"` // 最多读取的行数，0表示不限制；预览时设置，数据源读到足够行数后停止
 }
 
 // ExecuteResponse 执行响应结果
@@ -4,9 +4,11 @@
  * @architecture 发布订阅模式 - 连接MQTT broker并订阅主题
  * @documentReference ai_docs/datasource_req1.md
  * @stateFlow MQTT客户端生命周期：连接 -> 订阅主题 -> 接收消息 -> 处理数据 -> 断开连接
- * @rules 支持QoS、自动重连、消息持久化
+ * @rules 支持QoS、自动重连、消息持久化；参数配置了schema注册中心时按Avro/Protobuf schema解码消息，基准版本获取失败时初始化失败，
+ *        解码失败或schema不兼容的消息不自动写入，计入schema错误数并在状态和健康检查中显示最近一次错误
+ *        未配置schema注册中心时，非JSON消息交给配置了Protobuf解析的接口按接口的.proto解码
  * @dependencies github.com/eclipse/paho.mqtt.golang, context, sync, time
- * @refs interface.go, base.go
+ * @refs interface.go, base.go, schema_registry.go
  */
 
 package datasource
@@ -51,6 +53,11 @@ type MQTTDataSource struct {
 	// 实时数据处理
 	realtimeProcessor RealtimeDataProcessor // 实时数据处理器
 	enableAutoWrite   bool                  // 是否启用自动写入
+
+	// schema注册中心解码，未配置时按JSON解析
+	schemaDecoder   *SchemaDecoder
+	schemaErrors    int64  // 解码失败的消息数，受mu保护
+	lastSchemaError string // 最近一次解码错误，受mu保护
 }
 
 // MQTTMessage MQTT消息结构
@@ -85,6 +92,7 @@ func (m *MQTTDataSource) Init(ctx context.Context, ds *models.DataSource) error
 	if err := m.BaseDataSource.Init(ctx, ds); err != nil {
 		return err
 	}
+	ds = m.GetDataSource()
 
 	// 解析连接配置
 	config := ds.ConnectionConfig
@@ -150,6 +158,19 @@ func (m *MQTTDataSource) Init(ctx context.Context, ds *models.DataSource) error
 	// 解析参数配置
 	if ds.ParamsConfig != nil {
 		m.parseParamsConfig(ds.ParamsConfig)
+
+		// 配置了schema注册中心时按schema解码消息，基准版本获取失败则不启动
+		registryConfig, err := ParseSchemaRegistryConfig(ds.ParamsConfig)
+		if err != nil {
+			return fmt.Errorf("schema注册中心配置错误: %w", err)
+		}
+		if registryConfig != nil {
+			decoder, err := NewSchemaDecoder(ctx, registryConfig)
+			if err != nil {
+				return fmt.Errorf("初始化schema解码失败: %w", err)
+			}
+			m.schemaDecoder = decoder
+		}
 	}
 
 	// 默认订阅主题（如果没有在参数中指定）
@@ -320,10 +341,20 @@ func (m *MQTTDataSource) messageHandler(client mqtt.Client, msg mqtt.Message) {
 		ReceivedAt: time.Now(),
 	}
 
-	// 尝试解析JSON数据
-	var parsedData map[string]interface{}
-	if err := json.Unmarshal(msg.Payload(), &parsedData); err == nil {
-		message.ParsedData = parsedData
+	if m.schemaDecoder != nil {
+		// 按注册中心schema解码，失败的消息保留原文但不自动写入
+		parsedData, err := m.schemaDecoder.Decode(context.Background(), msg.Payload())
+		if err != nil {
+			m.recordSchemaError(msg.Topic(), err)
+		} else {
+			message.ParsedData = parsedData
+		}
+	} else {
+		// 尝试解析JSON数据
+		var parsedData map[string]interface{}
+		if err := json.Unmarshal(msg.Payload(), &parsedData); err == nil {
+			message.ParsedData = parsedData
+		}
 	}
 
 	// 发送到消息通道
@@ -336,6 +367,32 @@ func (m *MQTTDataSource) messageHandler(client mqtt.Client, msg mqtt.Message) {
 	}
 }
 
+// recordSchemaError 记录schema解码失败
+func (m *MQTTDataSource) recordSchemaError(topic string, err error) {
+	m.mu.Lock()
+	m.schemaErrors++
+	m.lastSchemaError = err.Error()
+	m.mu.Unlock()
+	slog.Error("MQTT消息按schema解码失败",
+		"datasource_id", m.GetID(),
+		"topic", topic,
+		"error", err)
+}
+
+// schemaStatus schema注册中心解码状态，未配置时返回nil
+func (m *MQTTDataSource) schemaStatus() map[string]interface{} {
+	if m.schemaDecoder == nil {
+		return nil
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return map[string]interface{}{
+		"baseline":   m.schemaDecoder.Baseline(),
+		"errors":     m.schemaErrors,
+		"last_error": m.lastSchemaError,
+	}
+}
+
 // connectionLostHandler 连接丢失处理器
 func (m *MQTTDataSource) connectionLostHandler(client mqtt.Client, err error) {
 	slog.Error("MQTT连接丢失: %v，尝试重连...\n", err.Error())
@@ -373,6 +430,15 @@ func (m *MQTTDataSource) processMessages() {
 					"topic", msg.Topic,
 					"error", err)
 			}
+		} else if m.enableAutoWrite && m.realtimeProcessor != nil && m.schemaDecoder == nil {
+			// 非JSON消息交给配置了Protobuf解析的接口解码
+			ctx := context.Background()
+			if err := m.realtimeProcessor.ProcessRealtimePayload(ctx, m.GetID(), []byte(msg.Payload)); err != nil {
+				slog.Error("MQTT实时处理二进制消息失败",
+					"datasource_id", m.GetID(),
+					"topic", msg.Topic,
+					"error", err)
+			}
 		}
 	}
 }
@@ -746,7 +812,7 @@ func (m *MQTTDataSource) handleStatus(ctx context.Context, request *ExecuteReque
 		connected = m.client.IsConnected()
 	}
 
-	response.Data = map[string]interface{}{
+	data := map[string]interface{}{
 		"broker":           fmt.Sprintf("%s:%d", m.broker, m.port),
 		"client_id":        m.clientID,
 		"connected":        connected,
@@ -758,6 +824,10 @@ func (m *MQTTDataSource) handleStatus(ctx context.Context, request *ExecuteReque
 		"reconnect_count":  m.reconnectCount,
 		"max_reconnects":   m.maxReconnects,
 	}
+	if schema := m.schemaStatus(); schema != nil {
+		data["schema_registry"] = schema
+	}
+	response.Data = data
 	response.Duration = time.Since(startTime)
 
 	return response, nil
@@ -816,6 +886,9 @@ func (m *MQTTDataSource) HealthCheck(ctx context.Context) (*HealthStatus, error)
 		status.Details["topics"] = m.topics
 		status.Details["message_count"] = msgCount
 		status.Details["reconnect_count"] = m.reconnectCount
+		if schema := m.schemaStatus(); schema != nil {
+			status.Details["schema_registry"] = schema
+		}
 	} else {
 		status.Status = "offline"
 		status.Message = "MQTT客户端未连接"
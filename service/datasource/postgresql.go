@@ -50,6 +50,7 @@ func (p *PostgreSQLDataSource) Init(ctx context.Context, ds *models.DataSource)
 	if err := p.BaseDataSource.Init(ctx, ds); err != nil {
 		return err
 	}
+	ds = p.GetDataSource()
 
 	// 解析连接配置
 	config := ds.ConnectionConfig
@@ -331,7 +332,7 @@ func (p *PostgreSQLDataSource) executeSQLQuery(ctx context.Context, request *Exe
 	// 根据操作类型执行不同的SQL操作
 	switch strings.ToLower(request.Operation) {
 	case "query", "select", "":
-		return p.executeSelectQuery(queryCtx, request.Query, response, startTime)
+		return p.executeSelectQuery(queryCtx, request.Query, request.MaxRows, response, startTime)
 	case "insert", "update", "delete":
 		return p.executeModifyQuery(queryCtx, request.Query, response, startTime)
 	case "batch":
@@ -343,8 +344,8 @@ func (p *PostgreSQLDataSource) executeSQLQuery(ctx context.Context, request *Exe
 	}
 }
 
-// executeSelectQuery 执行查询操作
-func (p *PostgreSQLDataSource) executeSelectQuery(ctx context.Context, query string, response *ExecuteResponse, startTime time.Time) (*ExecuteResponse, error) {
+// executeSelectQuery 执行查询操作，maxRows大于0时读到足够行数后停止扫描
+func (p *PostgreSQLDataSource) executeSelectQuery(ctx context.Context, query string, maxRows int, response *ExecuteResponse, startTime time.Time) (*ExecuteResponse, error) {
 	rows, err := p.db.QueryContext(ctx, query)
 	if err != nil {
 		response.Error = fmt.Sprintf("执行查询失败: %v", err)
@@ -389,6 +390,9 @@ func (p *PostgreSQLDataSource) executeSelectQuery(ctx context.Context, query str
 			}
 		}
 		results = append(results, row)
+		if maxRows > 0 && len(results) >= maxRows {
+			break
+		}
 	}
 
 	if err := rows.Err(); err != nil {
@@ -0,0 +1,166 @@
+/*
+ * @module service/datasource/preview_reader
+ * @description 限制行数的响应体读取，预览时流式解析JSON，只保留数据路径下数组的前MaxRows个元素
+ * @architecture 工具函数 - 响应读取
+ * @documentReference ai_docs/interface_executor.md
+ * @stateFlow 响应体 -> 按token流式复制JSON -> 到达数据数组时保留前N个元素、逐个跳过其余元素 -> 截断后的JSON
+ * @rules 数据数组之外的字段（成功标识、总数等）原样保留，响应解析器照常工作；跳过的元素逐个解码后丢弃，内存占用与单个元素相当；
+ *        未设置MaxRows或响应不是JSON时读取完整响应体
+ * @dependencies encoding/json
+ * @refs http_no_auth.go, http_auth.go, query_builder.go
+ */
+
+package datasource
+
+import (
+	"bufio"
+	"bytes"
+	"datahub-service/service/meta"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// readResponseBody 读取响应体，请求设置了MaxRows时截断数据数组
+func readResponseBody(body io.Reader, request *ExecuteRequest) ([]byte, error) {
+	if request == nil || request.MaxRows <= 0 {
+		return io.ReadAll(body)
+	}
+
+	reader := bufio.NewReader(body)
+	if !startsWithJSONContainer(reader) {
+		return io.ReadAll(reader)
+	}
+	return truncateJSONArray(reader, responseDataPath(request), request.MaxRows)
+}
+
+// responseDataPath 获取请求配置的数据路径，优先使用响应解析配置
+func responseDataPath(request *ExecuteRequest) string {
+	requestData, ok := request.Data.(map[string]interface{})
+	if !ok {
+		return "data"
+	}
+	if parserConfig, ok := requestData["response_parser"].(map[string]interface{}); ok {
+		if path, ok := parserConfig[meta.DataInterfaceConfigFieldDataPath].(string); ok {
+			return path
+		}
+	}
+	if path, ok := requestData["data_path"].(string); ok {
+		return path
+	}
+	return "data"
+}
+
+// startsWithJSONContainer 跳过前导空白后是否以对象或数组开始
+func startsWithJSONContainer(reader *bufio.Reader) bool {
+	for {
+		b, err := reader.Peek(1)
+		if err != nil {
+			return false
+		}
+		switch b[0] {
+		case ' ', '\t', '\r', '\n':
+			_, _ = reader.ReadByte()
+		case '{', '[':
+			return true
+		default:
+			return false
+		}
+	}
+}
+
+// truncateJSONArray 流式复制JSON，dataPath指向的数组只保留前maxRows个元素
+func truncateJSONArray(r io.Reader, dataPath string, maxRows int) ([]byte, error) {
+	var path []string
+	for _, part := range strings.Split(dataPath, ".") {
+		if part != "" {
+			path = append(path, part)
+		}
+	}
+
+	decoder := json.NewDecoder(r)
+	decoder.UseNumber()
+	var buf bytes.Buffer
+	if err := copyJSONValue(decoder, &buf, path, maxRows); err != nil {
+		return nil, fmt.Errorf("解析响应JSON失败: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// copyJSONValue 复制一个JSON值；沿数据路径进入对象，路径上遇到的数组视为数据数组并截断
+func copyJSONValue(decoder *json.Decoder, buf *bytes.Buffer, path []string, maxRows int) error {
+	token, err := decoder.Token()
+	if err != nil {
+		return err
+	}
+
+	switch token {
+	case json.Delim('{'):
+		buf.WriteByte('{')
+		for first := true; decoder.More(); first = false {
+			keyToken, err := decoder.Token()
+			if err != nil {
+				return err
+			}
+			key, _ := keyToken.(string)
+			if !first {
+				buf.WriteByte(',')
+			}
+			keyJSON, _ := json.Marshal(key)
+			buf.Write(keyJSON)
+			buf.WriteByte(':')
+
+			if len(path) > 0 && key == path[0] {
+				err = copyJSONValue(decoder, buf, path[1:], maxRows)
+			} else {
+				err = copyRawValue(decoder, buf)
+			}
+			if err != nil {
+				return err
+			}
+		}
+		if _, err := decoder.Token(); err != nil {
+			return err
+		}
+		buf.WriteByte('}')
+	case json.Delim('['):
+		buf.WriteByte('[')
+		for count := 0; decoder.More(); count++ {
+			if count >= maxRows {
+				var skipped json.RawMessage
+				if err := decoder.Decode(&skipped); err != nil {
+					return err
+				}
+				continue
+			}
+			if count > 0 {
+				buf.WriteByte(',')
+			}
+			if err := copyRawValue(decoder, buf); err != nil {
+				return err
+			}
+		}
+		if _, err := decoder.Token(); err != nil {
+			return err
+		}
+		buf.WriteByte(']')
+	default:
+		data, err := json.Marshal(token)
+		if err != nil {
+			return err
+		}
+		buf.Write(data)
+	}
+	return nil
+}
+
+// copyRawValue 原样复制下一个JSON值
+func copyRawValue(decoder *json.Decoder, buf *bytes.Buffer) error {
+	var raw json.RawMessage
+	if err := decoder.Decode(&raw); err != nil {
+		return err
+	}
+	buf.Write(raw)
+	return nil
+}
@@ -0,0 +1,53 @@
+/*
+ * @module service/datasource/preview_reader_test
+ * @description 预览限行读取测试，覆盖数据数组截断、保留数组外字段、根数组、非JSON响应和数据库查询包装
+ * @architecture 单元测试
+ * @refs preview_reader.go, query_builder.go
+ */
+
+package datasource
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadResponseBodyTruncatesDataArray(t *testing.T) {
+	body := `{"code": 0, "data": {"list": [{"id": 1}, {"id": 2}, {"id": 3, "tags": ["a"]}], "total": 3}, "message": "ok"}`
+	request := &ExecuteRequest{
+		MaxRows: 2,
+		Data: map[string]interface{}{
+			"data_path":       "data",
+			"response_parser": map[string]interface{}{"data_path": "data.list"},
+		},
+	}
+
+	truncated, err := readResponseBody(strings.NewReader(body), request)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"code": 0, "data": {"list": [{"id": 1}, {"id": 2}], "total": 3}, "message": "ok"}`, string(truncated),
+		"响应解析配置的数据路径优先，数组外的字段原样保留")
+
+	// 根数组
+	truncated, err = readResponseBody(strings.NewReader(` [1, 2, 3]`), &ExecuteRequest{MaxRows: 1})
+	require.NoError(t, err)
+	assert.JSONEq(t, `[1]`, string(truncated))
+
+	// 未设置MaxRows或非JSON响应读取完整内容
+	full, err := readResponseBody(strings.NewReader(body), &ExecuteRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, body, string(full))
+	text, err := readResponseBody(strings.NewReader("plain text"), &ExecuteRequest{MaxRows: 1})
+	require.NoError(t, err)
+	assert.Equal(t, "plain text", string(text))
+
+	_, err = readResponseBody(strings.NewReader(`{"data": [1, 2`), &ExecuteRequest{MaxRows: 1})
+	assert.Error(t, err)
+}
+
+func TestLimitQuery(t *testing.T) {
+	assert.Equal(t, "SELECT * FROM (SELECT id FROM devices ORDER BY id) AS preview_source LIMIT 10",
+		limitQuery(" SELECT id FROM devices ORDER BY id; ", 10))
+}
@@ -0,0 +1,810 @@
+/*
+ * @module service/datasource/protobuf_codec
+ * @description Protobuf schema解析和二进制解码，把注册中心的.proto文本编译为消息定义，按线格式把消息解码为字段名到取值的映射
+ * @architecture 工具函数 - 消息解码
+ * @documentReference ai_docs/datasource_req1.md
+ * @stateFlow .proto文本 -> 词法分析 -> 消息和枚举定义(含嵌套) -> 解析字段类型引用 -> 按字段编号读取线格式 -> 字段名 -> 取值
+ * @rules 支持proto2/proto3的message、enum、oneof、map、repeated(含packed)和嵌套定义，忽略service、option、reserved、extensions；
+ *        只支持google.protobuf.Timestamp一种外部引用类型，其余引用的类型须在同一schema中定义（不支持注册中心的schema references）；
+ *        proto3未出现的标量字段取零值，optional和oneof字段未出现时为null；64位整数解码为int64/uint64，bytes为base64字符串，枚举为名称，Timestamp为time.Time
+ * @dependencies encoding/binary, math
+ * @refs schema_registry.go
+ */
+
+package datasource
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// protoTimestampType 支持的外部引用类型
+const protoTimestampType = "google.protobuf.Timestamp"
+
+// protoFile 编译后的.proto文件
+type protoFile struct {
+	syntax   string
+	pkg      string
+	messages []*protoMessage          // 顶层消息，按声明顺序
+	types    map[string]*protoMessage // 全名 -> 消息
+	enums    map[string]*protoEnum    // 全名 -> 枚举
+}
+
+// protoMessage 消息定义
+type protoMessage struct {
+	name     string // 全名（不含包名）
+	fields   []*protoField
+	byNumber map[int]*protoField
+	nested   []*protoMessage // 嵌套消息，按声明顺序
+	mapEntry bool
+}
+
+// protoEnum 枚举定义
+type protoEnum struct {
+	values map[int32]string
+}
+
+// protoField 字段定义
+type protoField struct {
+	name     string
+	number   int
+	typeName string // 标量类型名或引用的类型名
+	repeated bool
+	optional bool // proto3 optional、proto2 optional或oneof成员，未出现时为null
+	oneof    bool
+	mapKey   string // map字段的键类型
+	message  *protoMessage
+	enum     *protoEnum
+}
+
+// protoScalarKinds 标量类型对应的统一字段类型
+var protoScalarKinds = map[string]string{
+	"double": schemaKindDouble, "float": schemaKindFloat,
+	"int32": schemaKindInt, "sint32": schemaKindInt, "sfixed32": schemaKindInt, "uint32": schemaKindLong, "fixed32": schemaKindLong,
+	"int64": schemaKindLong, "sint64": schemaKindLong, "sfixed64": schemaKindLong, "uint64": schemaKindLong, "fixed64": schemaKindLong,
+	"bool": schemaKindBoolean, "string": schemaKindString, "bytes": schemaKindBytes,
+}
+
+// parseProtoSchema 解析.proto文本
+func parseProtoSchema(text string) (*protoFile, error) {
+	tokens, err := tokenizeProto(text)
+	if err != nil {
+		return nil, err
+	}
+	p := &protoParser{tokens: tokens, file: &protoFile{
+		syntax: "proto2",
+		types:  make(map[string]*protoMessage),
+		enums:  make(map[string]*protoEnum),
+	}}
+	if err := p.parseFile(); err != nil {
+		return nil, err
+	}
+	if len(p.file.messages) == 0 {
+		return nil, fmt.Errorf("Protobuf schema中没有消息定义")
+	}
+	if err := p.file.resolve(); err != nil {
+		return nil, err
+	}
+	return p.file, nil
+}
+
+// tokenizeProto 词法分析，去掉注释，字符串保留引号
+func tokenizeProto(text string) ([]string, error) {
+	var tokens []string
+	for i := 0; i < len(text); {
+		c := text[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case strings.HasPrefix(text[i:], "//"):
+			for i < len(text) && text[i] != '\n' {
+				i++
+			}
+		case strings.HasPrefix(text[i:], "/*"):
+			end := strings.Index(text[i+2:], "*/")
+			if end < 0 {
+				return nil, fmt.Errorf("Protobuf schema中的注释没有结束")
+			}
+			i += end + 4
+		case c == '"' || c == '\'':
+			j := i + 1
+			for j < len(text) && text[j] != c {
+				if text[j] == '\\' {
+					j++
+				}
+				j++
+			}
+			if j >= len(text) {
+				return nil, fmt.Errorf("Protobuf schema中的字符串没有结束")
+			}
+			tokens = append(tokens, text[i:j+1])
+			i = j + 1
+		case strings.ContainsRune("{}[]()<>;=,", rune(c)):
+			tokens = append(tokens, string(c))
+			i++
+		default:
+			j := i
+			for j < len(text) && !strings.ContainsRune(" \t\n\r{}[]()<>;=,\"'", rune(text[j])) && !strings.HasPrefix(text[j:], "//") && !strings.HasPrefix(text[j:], "/*") {
+				j++
+			}
+			tokens = append(tokens, text[i:j])
+			i = j
+		}
+	}
+	return tokens, nil
+}
+
+// protoParser .proto语法分析
+type protoParser struct {
+	tokens []string
+	pos    int
+	file   *protoFile
+}
+
+func (p *protoParser) peek() string {
+	if p.pos < len(p.tokens) {
+		return p.tokens[p.pos]
+	}
+	return ""
+}
+
+func (p *protoParser) next() string {
+	token := p.peek()
+	p.pos++
+	return token
+}
+
+func (p *protoParser) expect(token string) error {
+	if got := p.next(); got != token {
+		return fmt.Errorf("Protobuf schema语法错误: 期望%s，实际为%q", token, got)
+	}
+	return nil
+}
+
+// skipStatement 跳过到分号为止的语句，中间的花括号块整体跳过
+func (p *protoParser) skipStatement() error {
+	for p.pos < len(p.tokens) {
+		switch p.next() {
+		case ";":
+			return nil
+		case "{":
+			if err := p.skipBlock(); err != nil {
+				return err
+			}
+			if p.peek() == ";" {
+				p.next()
+			}
+			return nil
+		}
+	}
+	return fmt.Errorf("Protobuf schema语法错误: 语句没有结束")
+}
+
+// skipBlock 跳过已读入左花括号的块
+func (p *protoParser) skipBlock() error {
+	for depth := 1; depth > 0; {
+		if p.pos >= len(p.tokens) {
+			return fmt.Errorf("Protobuf schema语法错误: 花括号不匹配")
+		}
+		switch p.next() {
+		case "{":
+			depth++
+		case "}":
+			depth--
+		}
+	}
+	return nil
+}
+
+func (p *protoParser) parseFile() error {
+	for p.pos < len(p.tokens) {
+		switch token := p.next(); token {
+		case "syntax", "edition":
+			if err := p.expect("="); err != nil {
+				return err
+			}
+			p.file.syntax = strings.Trim(p.next(), `"'`)
+			if err := p.expect(";"); err != nil {
+				return err
+			}
+		case "package":
+			p.file.pkg = p.next()
+			if err := p.expect(";"); err != nil {
+				return err
+			}
+		case "message":
+			message, err := p.parseMessage("")
+			if err != nil {
+				return err
+			}
+			p.file.messages = append(p.file.messages, message)
+		case "enum":
+			if err := p.parseEnum(""); err != nil {
+				return err
+			}
+		case ";":
+		default:
+			// import、option、service、extend等不影响消息解码
+			if err := p.skipStatement(); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// parseMessage 解析message，已读入message关键字
+func (p *protoParser) parseMessage(scope string) (*protoMessage, error) {
+	name := p.next()
+	if scope != "" {
+		name = scope + "." + name
+	}
+	message := &protoMessage{name: name, byNumber: make(map[int]*protoField)}
+	p.file.types[name] = message
+	if err := p.expect("{"); err != nil {
+		return nil, err
+	}
+	if err := p.parseMessageBody(message, false); err != nil {
+		return nil, err
+	}
+	return message, nil
+}
+
+// parseMessageBody 解析消息体直到右花括号，inOneof时字段都是oneof成员
+func (p *protoParser) parseMessageBody(message *protoMessage, inOneof bool) error {
+	for {
+		switch token := p.peek(); token {
+		case "":
+			return fmt.Errorf("Protobuf schema语法错误: 消息%s没有结束", message.name)
+		case "}":
+			p.next()
+			return nil
+		case ";":
+			p.next()
+		case "message":
+			p.next()
+			nested, err := p.parseMessage(message.name)
+			if err != nil {
+				return err
+			}
+			message.nested = append(message.nested, nested)
+		case "enum":
+			p.next()
+			if err := p.parseEnum(message.name); err != nil {
+				return err
+			}
+		case "oneof":
+			p.next()
+			p.next() // oneof名称
+			if err := p.expect("{"); err != nil {
+				return err
+			}
+			if err := p.parseMessageBody(message, true); err != nil {
+				return err
+			}
+		case "option", "reserved", "extensions", "extend", "group":
+			p.next()
+			if err := p.skipStatement(); err != nil {
+				return err
+			}
+		default:
+			field, err := p.parseField()
+			if err != nil {
+				return fmt.Errorf("消息%s: %w", message.name, err)
+			}
+			field.oneof = inOneof
+			field.optional = field.optional || inOneof
+			message.fields = append(message.fields, field)
+			message.byNumber[field.number] = field
+		}
+	}
+}
+
+// parseField 解析字段：[repeated|optional|required] 类型 名称 = 编号 [选项];  或 map<K, V> 名称 = 编号;
+func (p *protoParser) parseField() (*protoField, error) {
+	field := &protoField{}
+	switch p.peek() {
+	case "repeated":
+		p.next()
+		field.repeated = true
+	case "optional":
+		p.next()
+		field.optional = true
+	case "required":
+		p.next()
+	}
+	field.typeName = p.next()
+	if field.typeName == "map" {
+		if err := p.expect("<"); err != nil {
+			return nil, err
+		}
+		field.mapKey = p.next()
+		if err := p.expect(","); err != nil {
+			return nil, err
+		}
+		field.typeName = p.next()
+		if err := p.expect(">"); err != nil {
+			return nil, err
+		}
+	}
+	field.name = p.next()
+	if err := p.expect("="); err != nil {
+		return nil, err
+	}
+	number, err := strconv.Atoi(p.next())
+	if err != nil {
+		return nil, fmt.Errorf("字段%s的编号不是整数", field.name)
+	}
+	field.number = number
+	if p.peek() == "[" {
+		for p.pos < len(p.tokens) && p.next() != "]" {
+		}
+	}
+	if err := p.expect(";"); err != nil {
+		return nil, err
+	}
+	return field, nil
+}
+
+// parseEnum 解析enum，已读入enum关键字
+func (p *protoParser) parseEnum(scope string) error {
+	name := p.next()
+	if scope != "" {
+		name = scope + "." + name
+	}
+	enum := &protoEnum{values: make(map[int32]string)}
+	p.file.enums[name] = enum
+	if err := p.expect("{"); err != nil {
+		return err
+	}
+	for {
+		token := p.next()
+		switch token {
+		case "":
+			return fmt.Errorf("Protobuf schema语法错误: 枚举%s没有结束", name)
+		case "}":
+			return nil
+		case ";":
+			continue
+		case "option", "reserved":
+			if err := p.skipStatement(); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := p.expect("="); err != nil {
+			return err
+		}
+		number, err := strconv.ParseInt(p.next(), 0, 32)
+		if err != nil {
+			return fmt.Errorf("枚举%s.%s的取值不是整数", name, token)
+		}
+		if _, exists := enum.values[int32(number)]; !exists {
+			enum.values[int32(number)] = token
+		}
+		if p.peek() == "[" {
+			for p.pos < len(p.tokens) && p.next() != "]" {
+			}
+		}
+		if err := p.expect(";"); err != nil {
+			return err
+		}
+	}
+}
+
+// resolve 解析字段引用的消息和枚举类型，proto3非optional标量字段未出现时取零值
+func (f *protoFile) resolve() error {
+	for _, message := range f.types {
+		for _, field := range message.fields {
+			if _, scalar := protoScalarKinds[field.typeName]; scalar {
+				continue
+			}
+			if f.normalize(field.typeName) == protoTimestampType {
+				field.typeName = protoTimestampType
+				continue
+			}
+			name, ok := f.lookup(message.name, field.typeName)
+			if !ok {
+				return fmt.Errorf("Protobuf schema引用的类型%s未在本schema中定义（暂不支持schema references）", field.typeName)
+			}
+			field.message = f.types[name]
+			field.enum = f.enums[name]
+		}
+	}
+	return nil
+}
+
+// normalize 去掉开头的点和包名
+func (f *protoFile) normalize(typeName string) string {
+	typeName = strings.TrimPrefix(typeName, ".")
+	if f.pkg != "" {
+		typeName = strings.TrimPrefix(typeName, f.pkg+".")
+	}
+	return typeName
+}
+
+// lookup 按作用域由内向外查找类型，返回全名
+func (f *protoFile) lookup(scope, typeName string) (string, bool) {
+	absolute := strings.HasPrefix(typeName, ".")
+	typeName = f.normalize(typeName)
+	if !absolute {
+		for scope != "" {
+			candidate := scope + "." + typeName
+			if f.types[candidate] != nil || f.enums[candidate] != nil {
+				return candidate, true
+			}
+			if i := strings.LastIndex(scope, "."); i >= 0 {
+				scope = scope[:i]
+			} else {
+				scope = ""
+			}
+		}
+	}
+	if f.types[typeName] != nil || f.enums[typeName] != nil {
+		return typeName, true
+	}
+	return "", false
+}
+
+// message 按名称取消息，名称为空时取第一个顶层消息
+func (f *protoFile) message(name string) (*protoMessage, error) {
+	if name == "" {
+		return f.messages[0], nil
+	}
+	if message, ok := f.types[f.normalize(name)]; ok {
+		return message, nil
+	}
+	return nil, fmt.Errorf("Protobuf schema中没有消息%s", name)
+}
+
+// messageByIndexes 按Confluent线格式的消息序号路径取消息
+func (f *protoFile) messageByIndexes(indexes []int) (*protoMessage, error) {
+	candidates := f.messages
+	var message *protoMessage
+	for _, index := range indexes {
+		if index < 0 || index >= len(candidates) {
+			return nil, fmt.Errorf("消息序号%v在schema中不存在", indexes)
+		}
+		message = candidates[index]
+		candidates = message.nested
+	}
+	return message, nil
+}
+
+// protoSchemaFields 消息的统一字段清单
+func protoSchemaFields(file *protoFile, message *protoMessage) []schemaField {
+	fields := make([]schemaField, len(message.fields))
+	for i, field := range message.fields {
+		kind := schemaKindJSON
+		switch {
+		case field.repeated || field.mapKey != "":
+		case field.typeName == protoTimestampType:
+			kind = schemaKindTimestamp
+		case field.enum != nil:
+			kind = schemaKindEnum
+		case field.message == nil:
+			kind = protoScalarKinds[field.typeName]
+		}
+		nullable := field.optional || field.message != nil || field.typeName == protoTimestampType
+		// proto3的标量字段都有零值默认值
+		fields[i] = schemaField{Name: field.name, Kind: kind, Nullable: nullable, HasDefault: file.syntax == "proto3" || nullable}
+	}
+	return fields
+}
+
+// protoReader 线格式读取器
+type protoReader struct {
+	data []byte
+	pos  int
+}
+
+func (r *protoReader) varint() (uint64, error) {
+	var value uint64
+	for shift := uint(0); shift < 64; shift += 7 {
+		if r.pos >= len(r.data) {
+			return 0, fmt.Errorf("数据意外结束")
+		}
+		b := r.data[r.pos]
+		r.pos++
+		value |= uint64(b&0x7f) << shift
+		if b&0x80 == 0 {
+			return value, nil
+		}
+	}
+	return 0, fmt.Errorf("变长整数过长")
+}
+
+func (r *protoReader) bytes(n int) ([]byte, error) {
+	if n < 0 || r.pos+n > len(r.data) {
+		return nil, fmt.Errorf("数据意外结束")
+	}
+	b := r.data[r.pos : r.pos+n]
+	r.pos += n
+	return b, nil
+}
+
+// skip 跳过未知字段
+func (r *protoReader) skip(wireType uint64) error {
+	switch wireType {
+	case 0:
+		_, err := r.varint()
+		return err
+	case 1:
+		_, err := r.bytes(8)
+		return err
+	case 2:
+		size, err := r.varint()
+		if err != nil {
+			return err
+		}
+		_, err = r.bytes(int(size))
+		return err
+	case 5:
+		_, err := r.bytes(4)
+		return err
+	default:
+		return fmt.Errorf("不支持的线格式类型%d", wireType)
+	}
+}
+
+// decodeProtoMessage 按消息定义解码一条消息，返回字段名 -> 取值
+func decodeProtoMessage(file *protoFile, message *protoMessage, data []byte) (map[string]interface{}, error) {
+	record, err := decodeProtoFields(file, message, data)
+	if err != nil {
+		return nil, fmt.Errorf("Protobuf解码失败: %w", err)
+	}
+	return record, nil
+}
+
+func decodeProtoFields(file *protoFile, message *protoMessage, data []byte) (map[string]interface{}, error) {
+	record := make(map[string]interface{}, len(message.fields))
+	reader := &protoReader{data: data}
+	for reader.pos < len(reader.data) {
+		tag, err := reader.varint()
+		if err != nil {
+			return nil, err
+		}
+		number, wireType := int(tag>>3), tag&7
+		field, known := message.byNumber[number]
+		if !known {
+			if err := reader.skip(wireType); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		if field.mapKey != "" {
+			entry, err := reader.lengthDelimited(wireType, field.name)
+			if err != nil {
+				return nil, err
+			}
+			key, value, err := decodeProtoMapEntry(file, field, entry)
+			if err != nil {
+				return nil, fmt.Errorf("字段%s: %w", field.name, err)
+			}
+			entries, _ := record[field.name].(map[string]interface{})
+			if entries == nil {
+				entries = make(map[string]interface{})
+				record[field.name] = entries
+			}
+			entries[key] = value
+			continue
+		}
+
+		var values []interface{}
+		if wireType == 2 && field.repeated && field.message == nil && field.typeName != protoTimestampType && field.typeName != "string" && field.typeName != "bytes" {
+			// packed编码的repeated标量
+			packed, err := reader.lengthDelimited(wireType, field.name)
+			if err != nil {
+				return nil, err
+			}
+			inner := &protoReader{data: packed}
+			for inner.pos < len(inner.data) {
+				value, err := inner.value(file, field, protoPackedWireType(field.typeName))
+				if err != nil {
+					return nil, fmt.Errorf("字段%s: %w", field.name, err)
+				}
+				values = append(values, value)
+			}
+		} else {
+			value, err := reader.value(file, field, wireType)
+			if err != nil {
+				return nil, fmt.Errorf("字段%s: %w", field.name, err)
+			}
+			values = []interface{}{value}
+		}
+
+		if field.repeated {
+			items, _ := record[field.name].([]interface{})
+			record[field.name] = append(items, values...)
+		} else {
+			record[field.name] = values[len(values)-1]
+		}
+	}
+
+	for _, field := range message.fields {
+		if _, present := record[field.name]; present {
+			continue
+		}
+		switch {
+		case field.mapKey != "":
+			record[field.name] = map[string]interface{}{}
+		case field.repeated:
+			record[field.name] = []interface{}{}
+		case field.optional || field.message != nil || field.typeName == protoTimestampType || file.syntax != "proto3":
+			record[field.name] = nil
+		default:
+			record[field.name] = protoZeroValue(field)
+		}
+	}
+	return record, nil
+}
+
+// lengthDelimited 读取长度前缀的字段内容
+func (r *protoReader) lengthDelimited(wireType uint64, name string) ([]byte, error) {
+	if wireType != 2 {
+		return nil, fmt.Errorf("字段%s的线格式类型%d与定义不符", name, wireType)
+	}
+	size, err := r.varint()
+	if err != nil {
+		return nil, err
+	}
+	return r.bytes(int(size))
+}
+
+// value 读取一个字段取值
+func (r *protoReader) value(file *protoFile, field *protoField, wireType uint64) (interface{}, error) {
+	if field.message != nil || field.typeName == protoTimestampType || field.typeName == "string" || field.typeName == "bytes" {
+		data, err := r.lengthDelimited(wireType, field.name)
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case field.typeName == protoTimestampType:
+			return decodeProtoTimestamp(data)
+		case field.message != nil:
+			return decodeProtoFields(file, field.message, data)
+		case field.typeName == "string":
+			return string(data), nil
+		default:
+			return base64.StdEncoding.EncodeToString(data), nil
+		}
+	}
+
+	switch wireType {
+	case 0:
+		v, err := r.varint()
+		if err != nil {
+			return nil, err
+		}
+		if field.enum != nil {
+			if name, ok := field.enum.values[int32(v)]; ok {
+				return name, nil
+			}
+			return int64(int32(v)), nil
+		}
+		switch field.typeName {
+		case "bool":
+			return v != 0, nil
+		case "int32":
+			return int64(int32(v)), nil
+		case "int64":
+			return int64(v), nil
+		case "uint32", "uint64":
+			return v, nil
+		case "sint32", "sint64":
+			return int64(v>>1) ^ -int64(v&1), nil
+		}
+	case 1:
+		b, err := r.bytes(8)
+		if err != nil {
+			return nil, err
+		}
+		v := binary.LittleEndian.Uint64(b)
+		switch field.typeName {
+		case "double":
+			return math.Float64frombits(v), nil
+		case "fixed64":
+			return v, nil
+		case "sfixed64":
+			return int64(v), nil
+		}
+	case 5:
+		b, err := r.bytes(4)
+		if err != nil {
+			return nil, err
+		}
+		v := binary.LittleEndian.Uint32(b)
+		switch field.typeName {
+		case "float":
+			return float64(math.Float32frombits(v)), nil
+		case "fixed32":
+			return uint64(v), nil
+		case "sfixed32":
+			return int64(int32(v)), nil
+		}
+	}
+	return nil, fmt.Errorf("线格式类型%d与字段类型%s不符", wireType, field.typeName)
+}
+
+// decodeProtoMapEntry 解码map条目，键固定为字段1、值为字段2
+func decodeProtoMapEntry(file *protoFile, field *protoField, data []byte) (string, interface{}, error) {
+	entry := &protoMessage{byNumber: map[int]*protoField{}, mapEntry: true}
+	key := &protoField{name: "key", number: 1, typeName: field.mapKey}
+	value := &protoField{name: "value", number: 2, typeName: field.typeName, message: field.message, enum: field.enum}
+	entry.fields = []*protoField{key, value}
+	entry.byNumber[1], entry.byNumber[2] = key, value
+	decoded, err := decodeProtoFields(&protoFile{syntax: "proto3"}, entry, data)
+	if err != nil {
+		return "", nil, err
+	}
+	return fmt.Sprint(decoded["key"]), decoded["value"], nil
+}
+
+// decodeProtoTimestamp 解码google.protobuf.Timestamp
+func decodeProtoTimestamp(data []byte) (interface{}, error) {
+	reader := &protoReader{data: data}
+	var seconds, nanos int64
+	for reader.pos < len(reader.data) {
+		tag, err := reader.varint()
+		if err != nil {
+			return nil, err
+		}
+		if tag&7 != 0 {
+			if err := reader.skip(tag & 7); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		v, err := reader.varint()
+		if err != nil {
+			return nil, err
+		}
+		switch tag >> 3 {
+		case 1:
+			seconds = int64(v)
+		case 2:
+			nanos = int64(int32(v))
+		}
+	}
+	return time.Unix(seconds, nanos).UTC(), nil
+}
+
+// protoPackedWireType packed编码中元素的线格式类型
+func protoPackedWireType(typeName string) uint64 {
+	switch typeName {
+	case "double", "fixed64", "sfixed64":
+		return 1
+	case "float", "fixed32", "sfixed32":
+		return 5
+	default:
+		return 0
+	}
+}
+
+// protoZeroValue proto3标量字段的零值
+func protoZeroValue(field *protoField) interface{} {
+	if field.enum != nil {
+		if name, ok := field.enum.values[0]; ok {
+			return name
+		}
+		return int64(0)
+	}
+	switch field.typeName {
+	case "bool":
+		return false
+	case "string", "bytes":
+		return ""
+	case "double", "float":
+		return float64(0)
+	case "uint32", "uint64", "fixed32", "fixed64":
+		return uint64(0)
+	default:
+		return int64(0)
+	}
+}
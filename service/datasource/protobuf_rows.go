@@ -0,0 +1,169 @@
+/*
+ * @module service/datasource/protobuf_rows
+ * @description 按接口的Protobuf解析配置把二进制消息解码为行，供实时数据源把设备网关推送的Protobuf消息写入接口表
+ * @architecture 工具函数 - 消息解码
+ * @documentReference ai_docs/datasource_req1.md
+ * @stateFlow 解析配置 -> 编译.proto并校验消息类型、记录路径和字段路径 -> 解码消息 -> 按记录路径展开为记录 -> 按字段路径取值为行
+ * @rules 记录路径的中间字段须为非repeated消息字段，最后一个须为repeated消息字段；字段路径的中间字段须为非repeated消息字段；
+ *        路径上的消息未出现时取值为null；以$.开头的字段路径从消息根部取值
+ * @dependencies datahub-service/service/models
+ * @refs protobuf_codec.go, realtime_processor.go, service/models/protobuf_parse.go
+ */
+
+package datasource
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"datahub-service/service/models"
+)
+
+// ProtobufRowDecoder 按接口Protobuf解析配置解码消息
+type ProtobufRowDecoder struct {
+	file        *protoFile
+	message     *protoMessage
+	recordsPath []string
+	fieldPaths  map[string]protobufFieldPath
+}
+
+// protobufFieldPath 编译后的字段路径
+type protobufFieldPath struct {
+	fromRoot bool
+	segments []string
+}
+
+// NewProtobufRowDecoder 编译Protobuf解析配置，.proto无法解析或路径与消息定义不符时返回错误
+func NewProtobufRowDecoder(config *models.ProtobufParseConfig) (*ProtobufRowDecoder, error) {
+	file, err := parseProtoSchema(config.ProtoFile)
+	if err != nil {
+		return nil, err
+	}
+	message, err := file.message(config.MessageType)
+	if err != nil {
+		return nil, err
+	}
+	decoder := &ProtobufRowDecoder{file: file, message: message, fieldPaths: make(map[string]protobufFieldPath)}
+
+	record := message
+	if config.RecordsPath != "" {
+		decoder.recordsPath = strings.Split(config.RecordsPath, ".")
+		field, err := resolveProtoPath(message, decoder.recordsPath)
+		if err != nil {
+			return nil, fmt.Errorf("记录路径%s: %w", config.RecordsPath, err)
+		}
+		if !field.repeated || field.message == nil || field.mapKey != "" {
+			return nil, fmt.Errorf("记录路径%s须指向repeated消息字段", config.RecordsPath)
+		}
+		record = field.message
+	}
+
+	for name, path := range config.FieldPaths {
+		if name == "" || path == "" {
+			return nil, fmt.Errorf("字段路径的字段名和路径不能为空")
+		}
+		compiled := protobufFieldPath{}
+		base := record
+		if strings.HasPrefix(path, models.ProtobufRootPathPrefix) {
+			compiled.fromRoot = true
+			base = message
+			path = strings.TrimPrefix(path, models.ProtobufRootPathPrefix)
+		}
+		compiled.segments = strings.Split(path, ".")
+		if _, err := resolveProtoPath(base, compiled.segments); err != nil {
+			return nil, fmt.Errorf("字段%s的路径%s: %w", name, config.FieldPaths[name], err)
+		}
+		decoder.fieldPaths[name] = compiled
+	}
+	return decoder, nil
+}
+
+// resolveProtoPath 沿字段路径查找字段，中间字段须为非repeated消息字段
+func resolveProtoPath(message *protoMessage, segments []string) (*protoField, error) {
+	var field *protoField
+	for i, segment := range segments {
+		if i > 0 {
+			if field.message == nil || field.repeated || field.mapKey != "" {
+				return nil, fmt.Errorf("%s不是非repeated的消息字段", strings.Join(segments[:i], "."))
+			}
+			message = field.message
+		}
+		field = nil
+		for _, candidate := range message.fields {
+			if candidate.name == segment {
+				field = candidate
+				break
+			}
+		}
+		if field == nil {
+			return nil, fmt.Errorf("消息%s中没有字段%s", message.name, segment)
+		}
+	}
+	return field, nil
+}
+
+// MessageType 解码使用的消息全名
+func (d *ProtobufRowDecoder) MessageType() string {
+	return d.message.name
+}
+
+// MessageTypes .proto中定义的全部消息（不含map条目）
+func (d *ProtobufRowDecoder) MessageTypes() []string {
+	names := make([]string, 0, len(d.file.types))
+	for name := range d.file.types {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Decode 解码一条消息，返回按记录路径展开并按字段路径取值的行
+func (d *ProtobufRowDecoder) Decode(payload []byte) ([]map[string]interface{}, error) {
+	root, err := decodeProtoMessage(d.file, d.message, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	records := []map[string]interface{}{root}
+	if len(d.recordsPath) > 0 {
+		items, _ := protobufValueAt(root, d.recordsPath).([]interface{})
+		records = make([]map[string]interface{}, 0, len(items))
+		for _, item := range items {
+			if record, ok := item.(map[string]interface{}); ok {
+				records = append(records, record)
+			}
+		}
+	}
+
+	rows := make([]map[string]interface{}, len(records))
+	for i, record := range records {
+		if len(d.fieldPaths) == 0 {
+			rows[i] = record
+			continue
+		}
+		row := make(map[string]interface{}, len(d.fieldPaths))
+		for name, path := range d.fieldPaths {
+			source := record
+			if path.fromRoot {
+				source = root
+			}
+			row[name] = protobufValueAt(source, path.segments)
+		}
+		rows[i] = row
+	}
+	return rows, nil
+}
+
+// protobufValueAt 按路径从解码结果取值，路径上的消息未出现时返回nil
+func protobufValueAt(record map[string]interface{}, segments []string) interface{} {
+	var value interface{} = record
+	for _, segment := range segments {
+		current, ok := value.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		value = current[segment]
+	}
+	return value
+}
@@ -0,0 +1,147 @@
+/*
+ * @module service/datasource/protobuf_rows_test
+ * @description 按接口Protobuf解析配置解码的测试，覆盖记录路径展开、根部字段路径、路径校验以及实时处理器按接口分发二进制消息
+ * @architecture 单元测试
+ * @refs protobuf_rows.go, realtime_processor.go
+ */
+
+package datasource
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"datahub-service/service/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testGatewayProto = `
+syntax = "proto3";
+package gw;
+
+message Batch {
+  Gateway gateway = 1;
+  repeated Reading readings = 2;
+}
+message Gateway { string id = 1; }
+message Reading {
+  string sensor = 1;
+  int32 value = 2;
+  Meta meta = 3;
+}
+message Meta { string unit = 1; }
+`
+
+// testGatewayBatch 网关gw-1上报两条读数，第二条没有meta
+func testGatewayBatch() []byte {
+	reading1 := append(protoBytes(1, []byte("t1")), protoTag(2, 0), 7)
+	reading1 = append(reading1, protoBytes(3, protoBytes(1, []byte("C")))...)
+	reading2 := append(protoBytes(1, []byte("t2")), protoTag(2, 0), 9)
+
+	var data []byte
+	data = append(data, protoBytes(1, protoBytes(1, []byte("gw-1")))...)
+	data = append(data, protoBytes(2, reading1)...)
+	data = append(data, protoBytes(2, reading2)...)
+	return data
+}
+
+func TestProtobufRowDecoderExpandsRecords(t *testing.T) {
+	decoder, err := NewProtobufRowDecoder(&models.ProtobufParseConfig{
+		ProtoFile:   testGatewayProto,
+		MessageType: "gw.Batch",
+		RecordsPath: "readings",
+		FieldPaths: map[string]string{
+			"gateway_id": "$.gateway.id",
+			"sensor":     "sensor",
+			"value":      "value",
+			"unit":       "meta.unit",
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "Batch", decoder.MessageType())
+	assert.Equal(t, []string{"Batch", "Gateway", "Meta", "Reading"}, decoder.MessageTypes())
+
+	rows, err := decoder.Decode(testGatewayBatch())
+	require.NoError(t, err)
+	assert.Equal(t, []map[string]interface{}{
+		{"gateway_id": "gw-1", "sensor": "t1", "value": int64(7), "unit": "C"},
+		{"gateway_id": "gw-1", "sensor": "t2", "value": int64(9), "unit": nil},
+	}, rows)
+
+	// 不配置记录路径和字段路径时一条消息一行，取顶层字段
+	decoder, err = NewProtobufRowDecoder(&models.ProtobufParseConfig{ProtoFile: testGatewayProto})
+	require.NoError(t, err)
+	rows, err = decoder.Decode(testGatewayBatch())
+	require.NoError(t, err)
+	require.Len(t, rows, 1)
+	assert.Equal(t, map[string]interface{}{"id": "gw-1"}, rows[0]["gateway"])
+	assert.Len(t, rows[0]["readings"], 2)
+}
+
+func TestProtobufRowDecoderValidatesPaths(t *testing.T) {
+	tests := []struct {
+		name   string
+		config models.ProtobufParseConfig
+		errMsg string
+	}{
+		{"消息类型不存在", models.ProtobufParseConfig{MessageType: "gw.Missing"}, "没有消息gw.Missing"},
+		{"记录路径不是repeated", models.ProtobufParseConfig{MessageType: "Batch", RecordsPath: "gateway"}, "须指向repeated消息字段"},
+		{"字段不存在", models.ProtobufParseConfig{MessageType: "Batch", RecordsPath: "readings", FieldPaths: map[string]string{"x": "missing"}}, "没有字段missing"},
+		{"穿过标量字段", models.ProtobufParseConfig{MessageType: "Batch", RecordsPath: "readings", FieldPaths: map[string]string{"x": "value.unit"}}, "value不是非repeated的消息字段"},
+		{"穿过repeated字段", models.ProtobufParseConfig{MessageType: "Batch", FieldPaths: map[string]string{"x": "readings.sensor"}}, "readings不是非repeated的消息字段"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.config.ProtoFile = testGatewayProto
+			_, err := NewProtobufRowDecoder(&tt.config)
+			assert.ErrorContains(t, err, tt.errMsg)
+		})
+	}
+}
+
+func TestDefaultRealtimeDataProcessor_ProcessRealtimePayload(t *testing.T) {
+	processor := NewDefaultRealtimeDataProcessor()
+	processor.batchTimeout = time.Hour
+	loader := &MockInterfaceLoader{
+		Interfaces: map[string]InterfaceInfo{
+			"proto-interface": &MockInterfaceInfo{
+				ID: "proto-interface",
+				ParseConfig: map[string]interface{}{
+					models.ProtobufParseConfigKey: map[string]interface{}{
+						"proto_file":   testGatewayProto,
+						"message_type": "gw.Batch",
+						"records_path": "readings",
+						"field_paths":  map[string]interface{}{"gateway_id": "$.gateway.id", "sensor": "sensor"},
+					},
+					"fieldMapping": []interface{}{
+						map[string]interface{}{"source": "sensor", "target": "sensor_code"},
+						map[string]interface{}{"source": "gateway_id", "target": "gateway_id"},
+					},
+				},
+			},
+			"json-interface": &MockInterfaceInfo{ID: "json-interface"},
+		},
+	}
+	processor.SetInterfaceLoader(loader)
+
+	ctx := context.Background()
+	require.NoError(t, processor.RegisterInterface(ctx, "proto-interface", "gateway-source"))
+	require.NoError(t, processor.RegisterInterface(ctx, "json-interface", "gateway-source"))
+
+	require.NoError(t, processor.ProcessRealtimePayload(ctx, "gateway-source", testGatewayBatch()))
+	require.NoError(t, processor.ProcessRealtimeData(ctx, "gateway-source", map[string]interface{}{"sensor": "t3"}))
+
+	assert.Equal(t, []map[string]interface{}{
+		{"gateway_id": "gw-1", "sensor_code": "t1"},
+		{"gateway_id": "gw-1", "sensor_code": "t2"},
+	}, processor.dataBatches["proto-interface"], "配置了Protobuf解析的接口只接收二进制消息")
+	assert.Equal(t, []map[string]interface{}{{"sensor": "t3"}}, processor.dataBatches["json-interface"], "其他接口不接收二进制消息")
+
+	// 解码失败计入失败数，不影响其他消息
+	require.NoError(t, processor.ProcessRealtimePayload(ctx, "gateway-source", []byte{0x0a, 0x05}))
+	assert.Len(t, processor.dataBatches["proto-interface"], 2)
+	assert.Equal(t, int64(1), processor.GetProcessorStats()["total_failed"])
+}
@@ -74,6 +74,42 @@ func (qb *QueryBuilder) BuildTestRequest(parameters map[string]interface{}) (*Ex
 	}
 }
 
+// BuildPreviewRequest 构建预览请求，把行数限制下推到查询：数据库查询加LIMIT，
+// 支持分页的API只请求第一页且页大小为limit，其余数据源由MaxRows限制读取行数
+func (qb *QueryBuilder) BuildPreviewRequest(parameters map[string]interface{}, limit int) (*ExecuteRequest, error) {
+	var request *ExecuteRequest
+	var err error
+	switch qb.sourceTypeDef.Category {
+	case meta.DataSourceCategoryDatabase:
+		request, err = qb.buildDatabaseFullRequest(parameters)
+		if err == nil && limit > 0 {
+			request.Query = limitQuery(request.Query, limit)
+			request.Timeout = 30 * time.Second
+		}
+	case meta.DataSourceCategoryAPI:
+		if qb.IsPaginationEnabled() && limit > 0 {
+			pageStart := cast.ToInt(qb.GetPaginationConfig()["page_start"])
+			request, err = qb.buildAPIRequestWithPagination(parameters, false, qb.BuildNextPageParams(pageStart, limit))
+		} else {
+			request, err = qb.buildAPIRequest(parameters, false)
+		}
+	default:
+		request, err = qb.BuildTestRequest(parameters)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	request.MaxRows = limit
+	return request, nil
+}
+
+// limitQuery 把查询包装为子查询并限制行数，自定义查询自带的LIMIT/ORDER BY不受影响
+func limitQuery(query string, limit int) string {
+	query = strings.TrimRight(strings.TrimSpace(query), ";")
+	return fmt.Sprintf("SELECT * FROM (%s) AS preview_source LIMIT %d", query, limit)
+}
+
 // BuildSyncRequest 构建同步请求（仅用于全量同步）
 func (qb *QueryBuilder) BuildSyncRequest(syncStrategy string, parameters map[string]interface{}) (*ExecuteRequest, error) {
 	// 增量同步应使用 BuildIncrementalRequest
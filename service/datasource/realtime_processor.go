@@ -3,8 +3,9 @@
  * @description 实时数据处理器，负责将实时数据源接收的数据自动写入关联的数据接口表
  * @architecture 观察者模式 - 实时数据源推送数据，处理器负责分发和写入
  * @documentReference ai_docs/datasource_req1.md
- * @stateFlow 注册接口 -> 接收数据 -> 应用字段映射 -> 批量写入表
- * @rules 支持多接口绑定、批量优化、字段映射、错误容错
+ * @stateFlow 注册接口(生成JSON Schema，编译Protobuf解析配置) -> 接收数据 -> (webhook)按JSON Schema校验 -> 应用字段映射 -> 批量写入表；
+ *            接收二进制消息 -> 按接口的Protobuf解析配置解码为行 -> 应用字段映射 -> 批量写入表
+ * @rules 支持多接口绑定、批量优化、字段映射、错误容错；配置了Protobuf解析的接口只接收二进制消息，不接收JSON数据
  * @dependencies gorm.io/gorm, sync
  * @refs interface_executor/field_mapping.go, interface_executor/interface_info.go
  */
@@ -13,6 +14,8 @@ package datasource
 
 import (
 	"context"
+	"datahub-service/service/models"
+	"datahub-service/service/utils"
 	"fmt"
 	"log/slog"
 	"sync"
@@ -27,6 +30,7 @@ type InterfaceInfo interface {
 	GetSchemaName() string
 	GetTableName() string
 	GetParseConfig() map[string]interface{}
+	GetTableFieldsConfig() []interface{}
 }
 
 // DataWriter 数据写入器接口（避免循环依赖）
@@ -52,6 +56,9 @@ type RealtimeDataProcessor interface {
 	// ProcessRealtimeData 处理实时接收的数据
 	ProcessRealtimeData(ctx context.Context, dataSourceID string, data map[string]interface{}) error
 
+	// ProcessRealtimePayload 处理实时接收的二进制消息，只分发给配置了Protobuf解析的接口
+	ProcessRealtimePayload(ctx context.Context, dataSourceID string, payload []byte) error
+
 	// GetProcessorStats 获取处理器统计信息
 	GetProcessorStats() map[string]interface{}
 
@@ -63,6 +70,15 @@ type RealtimeDataProcessor interface {
 
 	// SetInterfaceLoader 设置接口加载器
 	SetInterfaceLoader(loader InterfaceLoader)
+
+	// ValidateRealtimeData 按关联接口的JSON Schema校验实时数据，返回不通过的接口及违规清单
+	ValidateRealtimeData(dataSourceID string, data map[string]interface{}) []InterfacePayloadValidation
+}
+
+// InterfacePayloadValidation 实时数据按一个关联接口校验的结果
+type InterfacePayloadValidation struct {
+	InterfaceID string `json:"interface_id"`
+	*utils.PayloadValidationResult
 }
 
 // DefaultRealtimeDataProcessor 默认实时数据处理器实现
@@ -75,6 +91,12 @@ type DefaultRealtimeDataProcessor struct {
 	// 接口ID -> 接口信息的缓存
 	interfaceCache map[string]InterfaceInfo
 
+	// 接口ID -> 按表字段配置生成的JSON Schema
+	schemaCache map[string]*utils.JSONSchema
+
+	// 接口ID -> Protobuf解码器，配置了Protobuf解析的接口才有条目，配置无效时为nil
+	protobufCache map[string]*ProtobufRowDecoder
+
 	// 批量写入缓冲
 	dataBatches      map[string][]map[string]interface{} // interfaceID -> data batch
 	batchMu          sync.RWMutex
@@ -105,6 +127,8 @@ func NewDefaultRealtimeDataProcessor() *DefaultRealtimeDataProcessor {
 	return &DefaultRealtimeDataProcessor{
 		dataSourceInterfaces: make(map[string][]string),
 		interfaceCache:       make(map[string]InterfaceInfo),
+		schemaCache:          make(map[string]*utils.JSONSchema),
+		protobufCache:        make(map[string]*ProtobufRowDecoder),
 		dataBatches:          make(map[string][]map[string]interface{}),
 		lastFlushTime:        make(map[string]time.Time),
 		flushTimerCancel:     make(map[string]context.CancelFunc),
@@ -149,8 +173,17 @@ func (p *DefaultRealtimeDataProcessor) RegisterInterface(ctx context.Context, in
 		return fmt.Errorf("加载接口信息失败: %w", err)
 	}
 
-	// 缓存接口信息
+	// 缓存接口信息和JSON Schema
 	p.interfaceCache[interfaceID] = interfaceInfo
+	p.schemaCache[interfaceID] = utils.BuildTableJSONSchema(interfaceID, interfaceInfo.GetTableFieldsConfig())
+	delete(p.protobufCache, interfaceID)
+	if config := models.ParseProtobufParseConfig(interfaceInfo.GetParseConfig()); config != nil {
+		decoder, err := NewProtobufRowDecoder(config)
+		if err != nil {
+			slog.Warn("接口Protobuf解析配置无效，不接收二进制消息", "interface_id", interfaceID, "error", err)
+		}
+		p.protobufCache[interfaceID] = decoder
+	}
 
 	// 添加到数据源-接口映射
 	interfaces := p.dataSourceInterfaces[dataSourceID]
@@ -207,6 +240,8 @@ func (p *DefaultRealtimeDataProcessor) UnregisterInterface(interfaceID string) e
 
 	// 清理缓存
 	delete(p.interfaceCache, interfaceID)
+	delete(p.schemaCache, interfaceID)
+	delete(p.protobufCache, interfaceID)
 
 	// 更新统计
 	p.updateStats()
@@ -234,6 +269,9 @@ func (p *DefaultRealtimeDataProcessor) ProcessRealtimeData(ctx context.Context,
 
 	// 为每个关联的接口处理数据
 	for _, interfaceID := range interfaces {
+		if p.isProtobufInterface(interfaceID) {
+			continue
+		}
 		if err := p.processDataForInterface(ctx, interfaceID, data); err != nil {
 			slog.Error("处理接口数据失败", "interface_id", interfaceID, "error", err)
 			p.stats.Lock()
@@ -246,6 +284,79 @@ func (p *DefaultRealtimeDataProcessor) ProcessRealtimeData(ctx context.Context,
 	return nil
 }
 
+// ProcessRealtimePayload 按各关联接口的Protobuf解析配置解码二进制消息，解码出的每一行按实时数据处理
+func (p *DefaultRealtimeDataProcessor) ProcessRealtimePayload(ctx context.Context, dataSourceID string, payload []byte) error {
+	p.mu.RLock()
+	interfaces := p.dataSourceInterfaces[dataSourceID]
+	decoders := make(map[string]*ProtobufRowDecoder, len(interfaces))
+	for _, interfaceID := range interfaces {
+		if decoder := p.protobufCache[interfaceID]; decoder != nil {
+			decoders[interfaceID] = decoder
+		}
+	}
+	p.mu.RUnlock()
+
+	if len(decoders) == 0 {
+		slog.Debug("数据源没有配置Protobuf解析的接口，跳过二进制消息", "datasource_id", dataSourceID)
+		return nil
+	}
+
+	p.stats.Lock()
+	p.stats.totalProcessed++
+	p.stats.lastProcessedAt = time.Now()
+	p.stats.Unlock()
+
+	for interfaceID, decoder := range decoders {
+		rows, err := decoder.Decode(payload)
+		if err != nil {
+			slog.Error("按Protobuf解析配置解码消息失败", "interface_id", interfaceID, "error", err)
+			p.stats.Lock()
+			p.stats.totalFailed++
+			p.stats.Unlock()
+			continue
+		}
+		for _, row := range rows {
+			if err := p.processDataForInterface(ctx, interfaceID, row); err != nil {
+				slog.Error("处理接口数据失败", "interface_id", interfaceID, "error", err)
+				p.stats.Lock()
+				p.stats.totalFailed++
+				p.stats.Unlock()
+				break
+			}
+		}
+	}
+
+	return nil
+}
+
+// isProtobufInterface 接口是否配置了Protobuf解析
+func (p *DefaultRealtimeDataProcessor) isProtobufInterface(interfaceID string) bool {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	_, exists := p.protobufCache[interfaceID]
+	return exists
+}
+
+// ValidateRealtimeData 对数据按各关联接口做字段映射后校验JSON Schema，只返回不通过的接口
+func (p *DefaultRealtimeDataProcessor) ValidateRealtimeData(dataSourceID string, data map[string]interface{}) []InterfacePayloadValidation {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	var failures []InterfacePayloadValidation
+	for _, interfaceID := range p.dataSourceInterfaces[dataSourceID] {
+		interfaceInfo, exists := p.interfaceCache[interfaceID]
+		schema := p.schemaCache[interfaceID]
+		if _, binary := p.protobufCache[interfaceID]; !exists || schema == nil || binary {
+			continue
+		}
+		mappedData := applyFieldMapping(data, interfaceInfo.GetParseConfig())
+		if result := schema.ValidateRows([]map[string]interface{}{mappedData}); !result.Valid {
+			failures = append(failures, InterfacePayloadValidation{InterfaceID: interfaceID, PayloadValidationResult: result})
+		}
+	}
+	return failures
+}
+
 // processDataForInterface 为特定接口处理数据
 func (p *DefaultRealtimeDataProcessor) processDataForInterface(ctx context.Context, interfaceID string, data map[string]interface{}) error {
 	p.mu.RLock()
@@ -298,14 +409,23 @@ func applyFieldMapping(data map[string]interface{}, parseConfig map[string]inter
 		return data
 	}
 
-	// 构建源字段到目标字段的映射表
+	// 构建源字段到目标字段的映射表，带transforms的条目与批量同步一致单独处理
 	sourceToTargetMap := make(map[string]string)
+	transformSources := make(map[string]bool)
+	var transformEntries []map[string]interface{}
 	for _, mappingItem := range fieldMappingArray {
 		if mappingObj, ok := mappingItem.(map[string]interface{}); ok {
-			if source, ok1 := mappingObj["source"].(string); ok1 {
-				if target, ok2 := mappingObj["target"].(string); ok2 {
-					sourceToTargetMap[source] = target
+			source, _ := mappingObj["source"].(string)
+			target, _ := mappingObj["target"].(string)
+			if transforms, ok := mappingObj["transforms"].([]interface{}); ok && len(transforms) > 0 && target != "" {
+				transformEntries = append(transformEntries, mappingObj)
+				if source != "" {
+					transformSources[source] = true
 				}
+				continue
+			}
+			if source != "" && target != "" {
+				sourceToTargetMap[source] = target
 			}
 		}
 	}
@@ -316,10 +436,27 @@ func applyFieldMapping(data map[string]interface{}, parseConfig map[string]inter
 		targetField := sourceField
 		if target, exists := sourceToTargetMap[sourceField]; exists {
 			targetField = target
+		} else if transformSources[sourceField] {
+			continue
 		}
 		mappedData[targetField] = value
 	}
 
+	// 执行字段转换
+	for _, entry := range transformEntries {
+		source, _ := entry["source"].(string)
+		target, _ := entry["target"].(string)
+		var value interface{}
+		if source != "" {
+			value = data[source]
+		}
+		result, err := utils.ApplyFieldTransforms(value, data, entry["transforms"].([]interface{}))
+		if err != nil {
+			slog.Warn("实时数据字段转换失败", "source", source, "target", target, "error", err)
+		}
+		mappedData[target] = result
+	}
+
 	return mappedData
 }
 
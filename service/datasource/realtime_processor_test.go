@@ -19,16 +19,18 @@ import (
 
 // MockInterfaceInfo Mock接口信息
 type MockInterfaceInfo struct {
-	ID          string
-	SchemaName  string
-	TableName   string
-	ParseConfig map[string]interface{}
+	ID                string
+	SchemaName        string
+	TableName         string
+	ParseConfig       map[string]interface{}
+	TableFieldsConfig []interface{}
 }
 
 func (m *MockInterfaceInfo) GetID() string                          { return m.ID }
 func (m *MockInterfaceInfo) GetSchemaName() string                  { return m.SchemaName }
 func (m *MockInterfaceInfo) GetTableName() string                   { return m.TableName }
 func (m *MockInterfaceInfo) GetParseConfig() map[string]interface{} { return m.ParseConfig }
+func (m *MockInterfaceInfo) GetTableFieldsConfig() []interface{}    { return m.TableFieldsConfig }
 
 // MockDataWriter Mock数据写入器
 type MockDataWriter struct {
@@ -513,3 +515,42 @@ func TestDefaultRealtimeDataProcessor_ProcessWithoutInterfaces(t *testing.T) {
 	stats := processor.GetProcessorStats()
 	assert.Equal(t, int64(0), stats["total_processed"]) // 没有接口，不处理
 }
+
+// TestDefaultRealtimeDataProcessor_ValidateRealtimeData 测试按关联接口的JSON Schema校验实时数据
+func TestDefaultRealtimeDataProcessor_ValidateRealtimeData(t *testing.T) {
+	processor := NewDefaultRealtimeDataProcessor()
+	loader := &MockInterfaceLoader{
+		Interfaces: map[string]InterfaceInfo{
+			"interface-1": &MockInterfaceInfo{
+				ID: "interface-1",
+				ParseConfig: map[string]interface{}{
+					"fieldMapping": []interface{}{
+						map[string]interface{}{"source": "deviceId", "target": "device_id"},
+					},
+				},
+				TableFieldsConfig: []interface{}{
+					map[string]interface{}{"name_en": "device_id", "data_type": "varchar", "is_nullable": false, "order_num": float64(1)},
+					map[string]interface{}{"name_en": "temperature", "data_type": "numeric", "order_num": float64(2)},
+				},
+			},
+			"interface-2": &MockInterfaceInfo{ID: "interface-2"},
+		},
+	}
+	processor.SetInterfaceLoader(loader)
+
+	ctx := context.Background()
+	assert.NoError(t, processor.RegisterInterface(ctx, "interface-1", "datasource-1"))
+	assert.NoError(t, processor.RegisterInterface(ctx, "interface-2", "datasource-1"))
+
+	assert.Empty(t, processor.ValidateRealtimeData("datasource-1", map[string]interface{}{"deviceId": "d-1", "temperature": "36.5"}))
+
+	failures := processor.ValidateRealtimeData("datasource-1", map[string]interface{}{"temperature": "hot"})
+	assert.Len(t, failures, 1, "未配置表字段的接口不校验")
+	assert.Equal(t, "interface-1", failures[0].InterfaceID)
+	assert.Equal(t, 2, failures[0].ViolationCount)
+	assert.Equal(t, "device_id", failures[0].Violations[0].Field)
+	assert.Equal(t, "required", failures[0].Violations[0].Keyword)
+
+	assert.NoError(t, processor.UnregisterInterface("interface-1"))
+	assert.Empty(t, processor.ValidateRealtimeData("datasource-1", map[string]interface{}{"temperature": "hot"}))
+}
@@ -128,6 +128,11 @@ func (r *DataSourceRegistry) registerBuiltinTypes() {
 		r.logger.Printf("注册HTTP数据源失败: %v", err)
 	}
 
+	// 注册模拟数据源
+	if err := r.factory.RegisterType(meta.DataSourceTypeApiMock, NewFixtureDataSource); err != nil {
+		r.logger.Printf("注册模拟数据源失败: %v", err)
+	}
+
 	// 注册HTTP POST数据源
 	if err := r.factory.RegisterType(meta.DataSourceTypeMessagingHttpPost, NewHTTPPostDataSource); err != nil {
 		r.logger.Printf("注册HTTP POST数据源失败: %v", err)
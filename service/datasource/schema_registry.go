@@ -0,0 +1,538 @@
+/*
+ * @module service/datasource/schema_registry
+ * @description 消息类数据源的Schema Registry集成，按主题从Confluent或Apicurio注册中心获取Avro/Protobuf schema，解码消息并映射为表字段，schema演进不兼容时明确报错
+ * @architecture 适配器模式 - 注册中心客户端 + 按schema ID缓存的解码器
+ * @documentReference ai_docs/datasource_req1.md
+ * @stateFlow 数据源初始化：解析参数 -> 获取主题的基准版本并编译(失败则初始化失败) -> 收到消息：读取线格式中的schema ID -> 首次出现时获取并与基准版本比较兼容性 -> 兼容则解码，不兼容则拒绝该ID的所有消息
+ * @rules 基准版本即接口表字段的来源；按字段名比较兼容性，基准字段被删除（且不可为空、没有默认值）或类型变化超出允许的提升时不兼容；
+ *        允许的类型提升：int->long/float/double，long->float/double，float->double，string<->bytes，enum->string；新增字段兼容但不写入接口表；
+ *        线格式confluent为魔数0+4字节schema ID(Protobuf再跟消息序号)，raw为不带前缀的消息体、始终按基准版本解码；
+ *        Apicurio通过其Confluent兼容接口(/apis/ccompat/v7)访问
+ * @dependencies net/http, encoding/json, datahub-service/service/models
+ * @refs avro_codec.go, protobuf_codec.go, mqtt.go, service/basic_library/schema_registry_service.go
+ */
+
+package datasource
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"datahub-service/service/models"
+)
+
+// 统一字段类型，Avro和Protobuf的类型都先归到这些类型再比较兼容性和映射表字段
+const (
+	schemaKindBoolean   = "boolean"
+	schemaKindInt       = "int"
+	schemaKindLong      = "long"
+	schemaKindFloat     = "float"
+	schemaKindDouble    = "double"
+	schemaKindString    = "string"
+	schemaKindBytes     = "bytes"
+	schemaKindEnum      = "enum"
+	schemaKindDate      = "date"
+	schemaKindTimestamp = "timestamp"
+	schemaKindDecimal   = "decimal"
+	schemaKindJSON      = "json" // 嵌套消息、数组、map和多类型联合
+)
+
+// schema格式、注册中心类型和线格式
+const (
+	SchemaFormatAvro     = "avro"
+	SchemaFormatProtobuf = "protobuf"
+
+	SchemaRegistryConfluent = "confluent"
+	SchemaRegistryApicurio  = "apicurio"
+
+	SchemaWireConfluent = "confluent"
+	SchemaWireRaw       = "raw"
+)
+
+// ErrIncompatibleSchema 消息使用的schema与基准版本不兼容
+var ErrIncompatibleSchema = errors.New("schema与基准版本不兼容")
+
+// schemaKindTableTypes 统一字段类型对应的表字段类型
+var schemaKindTableTypes = map[string]string{
+	schemaKindBoolean:   "boolean",
+	schemaKindInt:       "integer",
+	schemaKindLong:      "bigint",
+	schemaKindFloat:     "float",
+	schemaKindDouble:    "double",
+	schemaKindString:    "text",
+	schemaKindBytes:     "text",
+	schemaKindEnum:      "varchar",
+	schemaKindDate:      "date",
+	schemaKindTimestamp: "timestamp",
+	schemaKindDecimal:   "numeric",
+	schemaKindJSON:      "jsonb",
+}
+
+// schemaKindPromotions 兼容的类型提升，基准类型 -> 新版本可用的类型
+var schemaKindPromotions = map[string][]string{
+	schemaKindInt:    {schemaKindLong, schemaKindFloat, schemaKindDouble},
+	schemaKindLong:   {schemaKindFloat, schemaKindDouble},
+	schemaKindFloat:  {schemaKindDouble},
+	schemaKindString: {schemaKindBytes},
+	schemaKindBytes:  {schemaKindString},
+	schemaKindEnum:   {schemaKindString},
+}
+
+// schemaField schema顶层字段
+type schemaField struct {
+	Name       string
+	Kind       string
+	Nullable   bool
+	HasDefault bool
+}
+
+// SchemaRegistryConfig 数据源参数中的注册中心配置
+type SchemaRegistryConfig struct {
+	URL         string `json:"schema_registry_url"`
+	Flavor      string `json:"schema_registry_flavor"` // confluent(默认)、apicurio
+	Username    string `json:"schema_registry_username,omitempty"`
+	Password    string `json:"-"`
+	Subject     string `json:"schema_subject"`
+	Version     string `json:"schema_version"`                // latest(默认)或版本号，作为基准版本
+	Format      string `json:"schema_format,omitempty"`       // avro、protobuf，为空时按注册中心返回的schemaType
+	MessageType string `json:"schema_message_type,omitempty"` // Protobuf消息全名，为空时取第一个顶层消息
+	WireFormat  string `json:"schema_wire_format"`            // confluent(默认)、raw
+}
+
+// ParseSchemaRegistryConfig 从数据源参数配置读取注册中心配置，未配置地址时返回nil
+func ParseSchemaRegistryConfig(params map[string]interface{}) (*SchemaRegistryConfig, error) {
+	text := func(key string) string {
+		if value, ok := params[key]; ok && value != nil {
+			return strings.TrimSpace(fmt.Sprint(value))
+		}
+		return ""
+	}
+	config := &SchemaRegistryConfig{
+		URL:         strings.TrimRight(text("schema_registry_url"), "/"),
+		Flavor:      strings.ToLower(text("schema_registry_flavor")),
+		Username:    text("schema_registry_username"),
+		Password:    text("schema_registry_password"),
+		Subject:     text("schema_subject"),
+		Version:     strings.ToLower(text("schema_version")),
+		Format:      strings.ToLower(text("schema_format")),
+		MessageType: text("schema_message_type"),
+		WireFormat:  strings.ToLower(text("schema_wire_format")),
+	}
+	if config.URL == "" {
+		return nil, nil
+	}
+	if config.Flavor == "" {
+		config.Flavor = SchemaRegistryConfluent
+	}
+	if config.Version == "" {
+		config.Version = "latest"
+	}
+	if config.WireFormat == "" {
+		config.WireFormat = SchemaWireConfluent
+	}
+
+	if _, err := url.ParseRequestURI(config.URL); err != nil {
+		return nil, fmt.Errorf("schema_registry_url格式错误: %v", err)
+	}
+	if config.Subject == "" {
+		return nil, fmt.Errorf("配置了schema_registry_url时schema_subject不能为空")
+	}
+	if config.Flavor != SchemaRegistryConfluent && config.Flavor != SchemaRegistryApicurio {
+		return nil, fmt.Errorf("不支持的注册中心类型: %s", config.Flavor)
+	}
+	if config.Version != "latest" {
+		if version, err := strconv.Atoi(config.Version); err != nil || version <= 0 {
+			return nil, fmt.Errorf("schema_version须为latest或正整数")
+		}
+	}
+	if config.Format != "" && config.Format != SchemaFormatAvro && config.Format != SchemaFormatProtobuf {
+		return nil, fmt.Errorf("不支持的schema格式: %s", config.Format)
+	}
+	if config.WireFormat != SchemaWireConfluent && config.WireFormat != SchemaWireRaw {
+		return nil, fmt.Errorf("不支持的消息线格式: %s", config.WireFormat)
+	}
+	return config, nil
+}
+
+// RegisteredSchema 注册中心中的一个schema
+type RegisteredSchema struct {
+	ID         int    `json:"id"`
+	Subject    string `json:"subject,omitempty"`
+	Version    int    `json:"version,omitempty"`
+	SchemaType string `json:"schemaType,omitempty"` // AVRO(省略时)、PROTOBUF、JSON
+	Schema     string `json:"schema"`
+}
+
+// SchemaRegistryClient 注册中心客户端，使用Confluent兼容的REST接口
+type SchemaRegistryClient struct {
+	baseURL  string
+	username string
+	password string
+	client   *http.Client
+}
+
+// NewSchemaRegistryClient 创建注册中心客户端
+func NewSchemaRegistryClient(config *SchemaRegistryConfig) *SchemaRegistryClient {
+	baseURL := config.URL
+	if config.Flavor == SchemaRegistryApicurio && !strings.Contains(baseURL, "/apis/ccompat/") {
+		baseURL += "/apis/ccompat/v7"
+	}
+	return &SchemaRegistryClient{
+		baseURL:  baseURL,
+		username: config.Username,
+		password: config.Password,
+		client:   &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// SubjectVersion 获取主题的指定版本，version为latest或版本号
+func (c *SchemaRegistryClient) SubjectVersion(ctx context.Context, subject, version string) (*RegisteredSchema, error) {
+	var schema RegisteredSchema
+	path := fmt.Sprintf("/subjects/%s/versions/%s", url.PathEscape(subject), url.PathEscape(version))
+	if err := c.get(ctx, path, &schema); err != nil {
+		return nil, fmt.Errorf("获取主题%s的版本%s失败: %w", subject, version, err)
+	}
+	return &schema, nil
+}
+
+// SchemaByID 按schema ID获取schema
+func (c *SchemaRegistryClient) SchemaByID(ctx context.Context, id int) (*RegisteredSchema, error) {
+	var schema RegisteredSchema
+	if err := c.get(ctx, fmt.Sprintf("/schemas/ids/%d", id), &schema); err != nil {
+		return nil, fmt.Errorf("获取schema %d失败: %w", id, err)
+	}
+	schema.ID = id
+	return &schema, nil
+}
+
+func (c *SchemaRegistryClient) get(ctx context.Context, path string, result interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+path, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/vnd.schemaregistry.v1+json, application/json")
+	if c.username != "" {
+		req.SetBasicAuth(c.username, c.password)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("请求注册中心失败: %w", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 4<<20))
+	if err != nil {
+		return fmt.Errorf("读取注册中心响应失败: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		var registryErr struct {
+			ErrorCode int    `json:"error_code"`
+			Message   string `json:"message"`
+		}
+		if json.Unmarshal(body, &registryErr) == nil && registryErr.Message != "" {
+			return fmt.Errorf("注册中心返回%d: %s", resp.StatusCode, registryErr.Message)
+		}
+		return fmt.Errorf("注册中心返回%d", resp.StatusCode)
+	}
+	if err := json.Unmarshal(body, result); err != nil {
+		return fmt.Errorf("解析注册中心响应失败: %w", err)
+	}
+	return nil
+}
+
+// SchemaInfo schema摘要
+type SchemaInfo struct {
+	ID          int    `json:"id"`
+	Subject     string `json:"subject,omitempty"`
+	Version     int    `json:"version,omitempty"`
+	Format      string `json:"format"`
+	MessageType string `json:"message_type,omitempty"`
+}
+
+// compiledSchema 编译后的schema
+type compiledSchema struct {
+	info   SchemaInfo
+	fields []schemaField
+	decode func(indexes []int, data []byte) (map[string]interface{}, error)
+}
+
+// compileSchema 编译注册中心返回的schema
+func compileSchema(registered *RegisteredSchema, config *SchemaRegistryConfig) (*compiledSchema, error) {
+	format := config.Format
+	switch strings.ToUpper(registered.SchemaType) {
+	case "", "AVRO":
+		if format == "" {
+			format = SchemaFormatAvro
+		}
+	case "PROTOBUF":
+		if format == "" {
+			format = SchemaFormatProtobuf
+		}
+	default:
+		return nil, fmt.Errorf("不支持%s类型的schema，只支持Avro和Protobuf", registered.SchemaType)
+	}
+	info := SchemaInfo{ID: registered.ID, Subject: registered.Subject, Version: registered.Version, Format: format}
+
+	switch format {
+	case SchemaFormatAvro:
+		root, err := parseAvroSchema(registered.Schema)
+		if err != nil {
+			return nil, err
+		}
+		info.MessageType = root.name
+		return &compiledSchema{
+			info:   info,
+			fields: avroSchemaFields(root),
+			decode: func(_ []int, data []byte) (map[string]interface{}, error) {
+				return decodeAvroRecord(root, data)
+			},
+		}, nil
+	default:
+		file, err := parseProtoSchema(registered.Schema)
+		if err != nil {
+			return nil, err
+		}
+		message, err := file.message(config.MessageType)
+		if err != nil {
+			return nil, err
+		}
+		info.MessageType = message.name
+		return &compiledSchema{
+			info:   info,
+			fields: protoSchemaFields(file, message),
+			decode: func(indexes []int, data []byte) (map[string]interface{}, error) {
+				target := message
+				if indexes != nil {
+					indexed, err := file.messageByIndexes(indexes)
+					if err != nil {
+						return nil, err
+					}
+					if indexed.name != message.name {
+						return nil, fmt.Errorf("消息类型%s与配置的%s不一致", indexed.name, message.name)
+					}
+					target = indexed
+				}
+				return decodeProtoMessage(file, target, data)
+			},
+		}, nil
+	}
+}
+
+// IncompatibleSchemaError 消息使用的schema与基准版本不兼容的详细信息
+type IncompatibleSchemaError struct {
+	SchemaID int
+	Baseline SchemaInfo
+	Problems []string
+}
+
+func (e *IncompatibleSchemaError) Error() string {
+	return fmt.Sprintf("schema %d与主题%s的基准版本%d(schema %d)不兼容: %s",
+		e.SchemaID, e.Baseline.Subject, e.Baseline.Version, e.Baseline.ID, strings.Join(e.Problems, "; "))
+}
+
+func (e *IncompatibleSchemaError) Unwrap() error {
+	return ErrIncompatibleSchema
+}
+
+// checkSchemaCompatibility 检查新版本能否按基准版本的字段写入接口表，返回不兼容的原因
+func checkSchemaCompatibility(baseline, candidate []schemaField) []string {
+	candidates := make(map[string]schemaField, len(candidate))
+	for _, field := range candidate {
+		candidates[field.Name] = field
+	}
+	var problems []string
+	for _, field := range baseline {
+		next, ok := candidates[field.Name]
+		if !ok {
+			if !field.Nullable && !field.HasDefault {
+				problems = append(problems, fmt.Sprintf("删除了必填字段%s", field.Name))
+			}
+			continue
+		}
+		if next.Kind != field.Kind && !schemaKindPromotable(field.Kind, next.Kind) {
+			problems = append(problems, fmt.Sprintf("字段%s的类型由%s变为%s", field.Name, field.Kind, next.Kind))
+		}
+	}
+	return problems
+}
+
+func schemaKindPromotable(from, to string) bool {
+	for _, kind := range schemaKindPromotions[from] {
+		if kind == to {
+			return true
+		}
+	}
+	return false
+}
+
+// SchemaDecoder 按注册中心schema解码消息，每个schema ID只获取和检查一次
+type SchemaDecoder struct {
+	config   *SchemaRegistryConfig
+	client   *SchemaRegistryClient
+	baseline *compiledSchema
+
+	mu       sync.RWMutex
+	schemas  map[int]*compiledSchema // 兼容的schema
+	rejected map[int]error           // 不兼容或无法获取的schema
+}
+
+// NewSchemaDecoder 获取并编译基准版本，注册中心不可用或schema无法解析时直接返回错误
+func NewSchemaDecoder(ctx context.Context, config *SchemaRegistryConfig) (*SchemaDecoder, error) {
+	client := NewSchemaRegistryClient(config)
+	registered, err := client.SubjectVersion(ctx, config.Subject, config.Version)
+	if err != nil {
+		return nil, err
+	}
+	baseline, err := compileSchema(registered, config)
+	if err != nil {
+		return nil, fmt.Errorf("编译主题%s的schema失败: %w", config.Subject, err)
+	}
+	return &SchemaDecoder{
+		config:   config,
+		client:   client,
+		baseline: baseline,
+		schemas:  map[int]*compiledSchema{baseline.info.ID: baseline},
+		rejected: make(map[int]error),
+	}, nil
+}
+
+// Baseline 基准版本摘要
+func (d *SchemaDecoder) Baseline() SchemaInfo {
+	return d.baseline.info
+}
+
+// TableFields 基准版本映射的接口表字段
+func (d *SchemaDecoder) TableFields() []models.TableField {
+	return schemaTableFields(d.baseline)
+}
+
+// CheckVersion 获取主题的另一个版本并检查与基准版本的兼容性
+func (d *SchemaDecoder) CheckVersion(ctx context.Context, version string) (SchemaInfo, []models.TableField, []string, error) {
+	registered, err := d.client.SubjectVersion(ctx, d.config.Subject, version)
+	if err != nil {
+		return SchemaInfo{}, nil, nil, err
+	}
+	compiled, err := compileSchema(registered, d.config)
+	if err != nil {
+		return SchemaInfo{}, nil, nil, fmt.Errorf("编译主题%s的版本%s失败: %w", d.config.Subject, version, err)
+	}
+	return compiled.info, schemaTableFields(compiled), checkSchemaCompatibility(d.baseline.fields, compiled.fields), nil
+}
+
+// Decode 解码一条消息
+func (d *SchemaDecoder) Decode(ctx context.Context, payload []byte) (map[string]interface{}, error) {
+	if d.config.WireFormat == SchemaWireRaw {
+		return d.baseline.decode(nil, payload)
+	}
+
+	if len(payload) < 5 || payload[0] != 0 {
+		return nil, fmt.Errorf("消息不是Confluent线格式（缺少魔数0和schema ID）")
+	}
+	id := int(binary.BigEndian.Uint32(payload[1:5]))
+	body := payload[5:]
+
+	var indexes []int
+	if d.baseline.info.Format == SchemaFormatProtobuf {
+		var err error
+		indexes, body, err = readMessageIndexes(body)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	compiled, err := d.schemaFor(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return compiled.decode(indexes, body)
+}
+
+// schemaFor 取schema ID对应的已编译schema，首次出现时获取并检查兼容性
+func (d *SchemaDecoder) schemaFor(ctx context.Context, id int) (*compiledSchema, error) {
+	d.mu.RLock()
+	compiled, ok := d.schemas[id]
+	rejected := d.rejected[id]
+	d.mu.RUnlock()
+	if ok {
+		return compiled, nil
+	}
+	if rejected != nil {
+		return nil, rejected
+	}
+
+	compiled, err := d.resolve(ctx, id)
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if err != nil {
+		d.rejected[id] = err
+		return nil, err
+	}
+	d.schemas[id] = compiled
+	return compiled, nil
+}
+
+func (d *SchemaDecoder) resolve(ctx context.Context, id int) (*compiledSchema, error) {
+	registered, err := d.client.SchemaByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	compiled, err := compileSchema(registered, d.config)
+	if err != nil {
+		return nil, fmt.Errorf("编译schema %d失败: %w", id, err)
+	}
+	if compiled.info.Format != d.baseline.info.Format {
+		return nil, &IncompatibleSchemaError{SchemaID: id, Baseline: d.baseline.info,
+			Problems: []string{fmt.Sprintf("schema格式由%s变为%s", d.baseline.info.Format, compiled.info.Format)}}
+	}
+	if problems := checkSchemaCompatibility(d.baseline.fields, compiled.fields); len(problems) > 0 {
+		return nil, &IncompatibleSchemaError{SchemaID: id, Baseline: d.baseline.info, Problems: problems}
+	}
+	return compiled, nil
+}
+
+// readMessageIndexes 读取Confluent Protobuf线格式中的消息序号，单个0表示第一个顶层消息
+func readMessageIndexes(data []byte) ([]int, []byte, error) {
+	reader := &avroReader{data: data}
+	count, err := reader.long()
+	if err != nil || count < 0 || count > 100 {
+		return nil, nil, fmt.Errorf("消息序号格式错误")
+	}
+	if count == 0 {
+		return []int{0}, data[reader.pos:], nil
+	}
+	indexes := make([]int, count)
+	for i := range indexes {
+		index, err := reader.long()
+		if err != nil {
+			return nil, nil, fmt.Errorf("消息序号格式错误")
+		}
+		indexes[i] = int(index)
+	}
+	return indexes, data[reader.pos:], nil
+}
+
+// schemaTableFields schema字段映射为接口表字段
+func schemaTableFields(compiled *compiledSchema) []models.TableField {
+	fields := make([]models.TableField, len(compiled.fields))
+	for i, field := range compiled.fields {
+		fields[i] = models.TableField{
+			NameZh:      field.Name,
+			NameEn:      field.Name,
+			DataType:    schemaKindTableTypes[field.Kind],
+			IsNullable:  field.Nullable || field.HasDefault,
+			Description: fmt.Sprintf("来自schema注册中心主题%s第%d版(%s)", compiled.info.Subject, compiled.info.Version, field.Kind),
+			OrderNum:    i + 1,
+		}
+	}
+	return fields
+}
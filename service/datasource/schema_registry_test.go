@@ -0,0 +1,255 @@
+/*
+ * @module service/datasource/schema_registry_test
+ * @description Schema Registry集成测试，覆盖Avro和Protobuf解码、兼容性检查、配置解析以及按schema ID拒绝不兼容版本
+ * @architecture 单元测试
+ * @refs schema_registry.go, avro_codec.go, protobuf_codec.go
+ */
+
+package datasource
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testAvroSchemaV1 = `{
+  "type": "record", "name": "Reading", "namespace": "iot",
+  "fields": [
+    {"name": "device_id", "type": "string"},
+    {"name": "value", "type": "int"},
+    {"name": "status", "type": {"type": "enum", "name": "Status", "symbols": ["OK", "FAULT"]}},
+    {"name": "ts", "type": {"type": "long", "logicalType": "timestamp-millis"}},
+    {"name": "note", "type": ["null", "string"], "default": null}
+  ]
+}`
+
+// avroLong zigzag编码
+func avroLong(v int64) []byte {
+	buf := make([]byte, binary.MaxVarintLen64)
+	return buf[:binary.PutVarint(buf, v)]
+}
+
+func avroString(s string) []byte {
+	return append(avroLong(int64(len(s))), s...)
+}
+
+func testAvroReading() []byte {
+	var data []byte
+	data = append(data, avroString("dev-1")...)
+	data = append(data, avroLong(-42)...)
+	data = append(data, avroLong(1)...)
+	data = append(data, avroLong(1700000000123)...)
+	data = append(data, avroLong(1)...) // union分支1: string
+	data = append(data, avroString("hi")...)
+	return data
+}
+
+func confluentFrame(id uint32, body []byte) []byte {
+	frame := []byte{0, 0, 0, 0, 0}
+	binary.BigEndian.PutUint32(frame[1:], id)
+	return append(frame, body...)
+}
+
+func TestDecodeAvroRecord(t *testing.T) {
+	root, err := parseAvroSchema(testAvroSchemaV1)
+	require.NoError(t, err)
+
+	record, err := decodeAvroRecord(root, testAvroReading())
+	require.NoError(t, err)
+	assert.Equal(t, "dev-1", record["device_id"])
+	assert.Equal(t, int64(-42), record["value"])
+	assert.Equal(t, "FAULT", record["status"])
+	assert.Equal(t, time.UnixMilli(1700000000123).UTC(), record["ts"])
+	assert.Equal(t, "hi", record["note"])
+
+	fields := avroSchemaFields(root)
+	assert.Equal(t, schemaField{Name: "status", Kind: schemaKindEnum}, fields[2])
+	assert.Equal(t, schemaField{Name: "ts", Kind: schemaKindTimestamp}, fields[3])
+	assert.Equal(t, schemaField{Name: "note", Kind: schemaKindString, Nullable: true, HasDefault: true}, fields[4])
+
+	_, err = decodeAvroRecord(root, append(testAvroReading(), 0))
+	assert.Error(t, err, "多余的字节说明消息不是用该schema编码的")
+}
+
+func TestDecodeAvroDecimal(t *testing.T) {
+	root, err := parseAvroSchema(`{"type": "record", "name": "Price", "fields": [
+		{"name": "amount", "type": {"type": "bytes", "logicalType": "decimal", "precision": 9, "scale": 2}}]}`)
+	require.NoError(t, err)
+
+	record, err := decodeAvroRecord(root, append(avroLong(2), 0xfb, 0x2e)) // -1234
+	require.NoError(t, err)
+	assert.Equal(t, "-12.34", record["amount"])
+}
+
+const testProtoSchema = `
+syntax = "proto3";
+package iot;
+
+import "google/protobuf/timestamp.proto";
+
+// 设备读数
+message Reading {
+  string device_id = 1;
+  sint64 value = 2;
+  Status status = 3;
+  repeated int32 samples = 4;
+  google.protobuf.Timestamp ts = 5;
+  map<string, string> labels = 6;
+  Location location = 7;
+  optional double temperature = 8;
+  oneof extra { string note = 9; }
+
+  message Location { double lat = 1; double lng = 2; }
+  enum Status { OK = 0; FAULT = 1; }
+}
+`
+
+func protoTag(number, wireType int) byte {
+	return byte(number<<3 | wireType)
+}
+
+func protoBytes(number int, data []byte) []byte {
+	return append([]byte{protoTag(number, 2), byte(len(data))}, data...)
+}
+
+func TestDecodeProtoMessage(t *testing.T) {
+	file, err := parseProtoSchema(testProtoSchema)
+	require.NoError(t, err)
+	message, err := file.message("")
+	require.NoError(t, err)
+	assert.Equal(t, "Reading", message.name)
+
+	var data []byte
+	data = append(data, protoBytes(1, []byte("dev-1"))...)
+	data = append(data, protoTag(2, 0), 3) // sint64 -2
+	data = append(data, protoTag(3, 0), 1)
+	data = append(data, protoBytes(4, []byte{1, 2, 3})...) // packed
+	data = append(data, protoBytes(5, []byte{protoTag(1, 0), 100})...)
+	data = append(data, protoBytes(6, append(protoBytes(1, []byte("k")), protoBytes(2, []byte("v"))...))...)
+	data = append(data, 0xf8, 0x01, 0x00) // 未知字段31，varint
+
+	record, err := decodeProtoMessage(file, message, data)
+	require.NoError(t, err)
+	assert.Equal(t, "dev-1", record["device_id"])
+	assert.Equal(t, int64(-2), record["value"])
+	assert.Equal(t, "FAULT", record["status"])
+	assert.Equal(t, []interface{}{int64(1), int64(2), int64(3)}, record["samples"])
+	assert.Equal(t, time.Unix(100, 0).UTC(), record["ts"])
+	assert.Equal(t, map[string]interface{}{"k": "v"}, record["labels"])
+	assert.Nil(t, record["location"])
+	assert.Nil(t, record["temperature"], "optional字段未出现时为null")
+	assert.Nil(t, record["note"], "oneof字段未出现时为null")
+
+	fields := protoSchemaFields(file, message)
+	assert.Equal(t, schemaField{Name: "value", Kind: schemaKindLong, HasDefault: true}, fields[1])
+	assert.Equal(t, schemaKindJSON, fields[3].Kind)
+	assert.Equal(t, schemaKindTimestamp, fields[4].Kind)
+
+	_, err = parseProtoSchema(`syntax = "proto3"; message A { other.B b = 1; }`)
+	assert.ErrorContains(t, err, "schema references")
+}
+
+func TestCheckSchemaCompatibility(t *testing.T) {
+	baseline := []schemaField{
+		{Name: "id", Kind: schemaKindString},
+		{Name: "count", Kind: schemaKindInt},
+		{Name: "note", Kind: schemaKindString, Nullable: true},
+		{Name: "flag", Kind: schemaKindBoolean},
+	}
+
+	assert.Empty(t, checkSchemaCompatibility(baseline, []schemaField{
+		{Name: "id", Kind: schemaKindString},
+		{Name: "count", Kind: schemaKindLong},
+		{Name: "flag", Kind: schemaKindBoolean},
+		{Name: "added", Kind: schemaKindDouble},
+	}), "类型提升、删除可为空字段和新增字段都兼容")
+
+	assert.Equal(t, []string{"字段count的类型由int变为string", "删除了必填字段flag"}, checkSchemaCompatibility(baseline, []schemaField{
+		{Name: "id", Kind: schemaKindString},
+		{Name: "count", Kind: schemaKindString},
+	}))
+}
+
+func TestParseSchemaRegistryConfig(t *testing.T) {
+	config, err := ParseSchemaRegistryConfig(map[string]interface{}{"qos": 1})
+	require.NoError(t, err)
+	assert.Nil(t, config)
+
+	config, err = ParseSchemaRegistryConfig(map[string]interface{}{
+		"schema_registry_url":    "http://registry:8080/",
+		"schema_registry_flavor": "apicurio",
+		"schema_subject":         "readings-value",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "latest", config.Version)
+	assert.Equal(t, SchemaWireConfluent, config.WireFormat)
+	assert.Equal(t, "http://registry:8080/apis/ccompat/v7", NewSchemaRegistryClient(config).baseURL)
+
+	_, err = ParseSchemaRegistryConfig(map[string]interface{}{"schema_registry_url": "http://registry:8081"})
+	assert.ErrorContains(t, err, "schema_subject")
+	_, err = ParseSchemaRegistryConfig(map[string]interface{}{
+		"schema_registry_url": "http://registry:8081", "schema_subject": "s", "schema_version": "v2",
+	})
+	assert.Error(t, err)
+}
+
+func TestSchemaDecoderRejectsIncompatibleSchema(t *testing.T) {
+	incompatible := `{"type": "record", "name": "Reading", "namespace": "iot", "fields": [
+		{"name": "device_id", "type": "long"}, {"name": "value", "type": "long"}]}`
+	requests := map[string]int{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests[r.URL.Path]++
+		switch r.URL.Path {
+		case "/subjects/readings-value/versions/latest":
+			json.NewEncoder(w).Encode(RegisteredSchema{ID: 1, Subject: "readings-value", Version: 3, Schema: testAvroSchemaV1})
+		case "/schemas/ids/2":
+			json.NewEncoder(w).Encode(RegisteredSchema{Schema: incompatible})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error_code": 40403, "message": "Schema not found"}`))
+		}
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	decoder, err := NewSchemaDecoder(ctx, &SchemaRegistryConfig{
+		URL: server.URL, Flavor: SchemaRegistryConfluent, Subject: "readings-value", Version: "latest", WireFormat: SchemaWireConfluent,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, SchemaInfo{ID: 1, Subject: "readings-value", Version: 3, Format: SchemaFormatAvro, MessageType: "iot.Reading"}, decoder.Baseline())
+
+	fields := decoder.TableFields()
+	require.Len(t, fields, 5)
+	assert.Equal(t, "integer", fields[1].DataType)
+	assert.Equal(t, "varchar", fields[2].DataType)
+	assert.False(t, fields[0].IsNullable)
+	assert.True(t, fields[4].IsNullable)
+
+	record, err := decoder.Decode(ctx, confluentFrame(1, testAvroReading()))
+	require.NoError(t, err)
+	assert.Equal(t, "dev-1", record["device_id"])
+
+	for i := 0; i < 2; i++ {
+		_, err = decoder.Decode(ctx, confluentFrame(2, testAvroReading()))
+		var incompatibleErr *IncompatibleSchemaError
+		require.True(t, errors.As(err, &incompatibleErr))
+		assert.ErrorIs(t, err, ErrIncompatibleSchema)
+		assert.Equal(t, 2, incompatibleErr.SchemaID)
+		assert.Contains(t, incompatibleErr.Problems, "字段device_id的类型由string变为long")
+	}
+	assert.Equal(t, 1, requests["/schemas/ids/2"], "不兼容的schema只获取一次")
+
+	_, err = decoder.Decode(ctx, confluentFrame(9, nil))
+	assert.ErrorContains(t, err, "Schema not found")
+	_, err = decoder.Decode(ctx, []byte(`{"device_id": "dev-1"}`))
+	assert.ErrorContains(t, err, "Confluent线格式")
+}
@@ -13,6 +13,8 @@ package distributed_lock
 
 import (
 	"context"
+	"datahub-service/service/chaos"
+	"datahub-service/service/models"
 	"fmt"
 	"log/slog"
 	"os"
@@ -88,6 +90,12 @@ func NewRedisLock() (*RedisLock, error) {
 // TryLock 尝试获取锁
 // 使用SET NX命令，只有当key不存在时才会设置成功
 func (r *RedisLock) TryLock(ctx context.Context, key string, ttl time.Duration) (bool, error) {
+	// 故障注入：模拟锁被其他实例持有
+	if err := chaos.Inject(ctx, models.FaultTypeLockContention, key); err != nil {
+		slog.Warn("分布式锁: 故障注入模拟锁争用", "key", key, "error", err)
+		return false, nil
+	}
+
 	// 构造锁的键
 	lockKey := fmt.Sprintf("sync_task_scheduler:lock:%s", key)
 
@@ -0,0 +1,154 @@
+/*
+ * @module service/encryption/cipher
+ * @description 列值加解密实现，支持应用侧AES-256-GCM和数据库侧pgcrypto两种方式，密文自带加密方式和密钥版本
+ * @architecture 分层架构 - 业务服务层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow 明文 -> 按加密方式和当前密钥加密 -> enc:<方式>:v<版本>:<base64> ；密文 -> 解析方式和版本 -> 选择密钥解密
+ * @rules pgcrypto批量在一条SQL中完成，密钥作为参数传入且关闭SQL日志，避免密钥出现在日志中
+ * @dependencies gorm.io/gorm
+ * @refs service/encryption/keyring.go, service/encryption/encryption_service.go
+ */
+
+package encryption
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+// ciphertextPrefix 密文前缀
+const ciphertextPrefix = "enc:"
+
+// pgcryptoBatchSize pgcrypto单条SQL处理的值数量
+const pgcryptoBatchSize = 500
+
+// ciphertext 解析后的密文
+type ciphertext struct {
+	mode    string
+	version int
+	payload string
+}
+
+// IsCiphertext 判断值是否为本服务生成的密文
+func IsCiphertext(value string) bool {
+	_, ok := parseCiphertext(value)
+	return ok
+}
+
+// formatCiphertext 生成密文字符串
+func formatCiphertext(mode string, version int, payload string) string {
+	return fmt.Sprintf("%s%s:v%d:%s", ciphertextPrefix, mode, version, payload)
+}
+
+// parseCiphertext 解析密文字符串
+func parseCiphertext(value string) (ciphertext, bool) {
+	if !strings.HasPrefix(value, ciphertextPrefix) {
+		return ciphertext{}, false
+	}
+	parts := strings.SplitN(strings.TrimPrefix(value, ciphertextPrefix), ":", 3)
+	if len(parts) != 3 || !strings.HasPrefix(parts[1], "v") {
+		return ciphertext{}, false
+	}
+	version, err := strconv.Atoi(strings.TrimPrefix(parts[1], "v"))
+	if err != nil || version <= 0 {
+		return ciphertext{}, false
+	}
+	return ciphertext{mode: parts[0], version: version, payload: parts[2]}, true
+}
+
+// aesEncrypt AES-256-GCM加密，返回base64编码的nonce+密文
+func aesEncrypt(key []byte, plaintext string) (string, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	sealed := gcm.Seal(nonce, nonce, []byte(plaintext), nil)
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// aesDecrypt AES-256-GCM解密
+func aesDecrypt(key []byte, payload string) (string, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return "", err
+	}
+	sealed, err := base64.StdEncoding.DecodeString(payload)
+	if err != nil {
+		return "", err
+	}
+	if len(sealed) < gcm.NonceSize() {
+		return "", errors.New("密文长度不足")
+	}
+	plaintext, err := gcm.Open(nil, sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():], nil)
+	if err != nil {
+		return "", err
+	}
+	return string(plaintext), nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// pgcryptoPassphrase pgcrypto对称加密使用的口令
+func pgcryptoPassphrase(key []byte) string {
+	return hex.EncodeToString(key)
+}
+
+// pgcryptoEncrypt 使用pgcrypto批量加密，返回base64编码的密文
+func pgcryptoEncrypt(ctx context.Context, db *gorm.DB, key []byte, values []string) ([]string, error) {
+	return pgcryptoBatch(ctx, db, "replace(encode(pgp_sym_encrypt(t.v, ?), 'base64'), E'\\n', '')", key, values)
+}
+
+// pgcryptoDecrypt 使用pgcrypto批量解密base64编码的密文
+func pgcryptoDecrypt(ctx context.Context, db *gorm.DB, key []byte, payloads []string) ([]string, error) {
+	return pgcryptoBatch(ctx, db, "pgp_sym_decrypt(decode(t.v, 'base64'), ?)", key, payloads)
+}
+
+// pgcryptoBatch 分批执行pgcrypto表达式，结果与输入顺序一致
+func pgcryptoBatch(ctx context.Context, db *gorm.DB, expr string, key []byte, values []string) ([]string, error) {
+	// 关闭SQL日志，避免慢查询或错误日志输出密钥
+	session := db.Session(&gorm.Session{Logger: gormlogger.Discard}).WithContext(ctx)
+	passphrase := pgcryptoPassphrase(key)
+
+	results := make([]string, 0, len(values))
+	for start := 0; start < len(values); start += pgcryptoBatchSize {
+		end := min(start+pgcryptoBatchSize, len(values))
+		rows := make([]string, 0, end-start)
+		args := []interface{}{passphrase}
+		for i, value := range values[start:end] {
+			rows = append(rows, "(?::text, ?::int)")
+			args = append(args, value, i)
+		}
+
+		var batch []string
+		sqlStr := fmt.Sprintf("SELECT %s FROM (VALUES %s) AS t(v, i) ORDER BY t.i", expr, strings.Join(rows, ", "))
+		if err := session.Raw(sqlStr, args...).Scan(&batch).Error; err != nil {
+			return nil, fmt.Errorf("pgcrypto处理失败: %w", err)
+		}
+		if len(batch) != end-start {
+			return nil, fmt.Errorf("pgcrypto返回%d条结果，应为%d条", len(batch), end-start)
+		}
+		results = append(results, batch...)
+	}
+	return results, nil
+}
@@ -0,0 +1,661 @@
+/*
+ * @module service/encryption/encryption_service
+ * @description 敏感列加密服务，维护列加密配置，在同步写入时透明加密、在数据查看时按角色解密，并提供密钥使用统计和轮换工具
+ * @architecture 分层架构 - 业务服务层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow 同步写入 -> EncryptRows加密配置列 -> 入库密文；数据查看 -> DecryptRows -> 授权角色得到明文，其他角色得到遮蔽值；轮换 -> RotateColumn逐批重新加密
+ * @rules 存在加密配置但未配置密钥时拒绝写入，避免明文落库；已是密文的值不重复加密；无权限或解密失败的值一律遮蔽，不返回密文
+ * @dependencies datahub-service/service/models, gorm.io/gorm
+ * @refs service/interface_executor/field_mapping.go, service/thematic_library/thematic_sync/data_writer.go, api/controllers/data_view_controller.go
+ */
+
+package encryption
+
+import (
+	"context"
+	"datahub-service/service/models"
+	"datahub-service/service/utils"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"slices"
+	"sync/atomic"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// MaskedValue 无权查看明文时返回的遮蔽值
+const MaskedValue = "******"
+
+// identifierPattern schema、表和列名校验规则
+var identifierPattern = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+// textDataTypes 可加密的列类型
+var textDataTypes = []string{"text", "character varying", "character"}
+
+// 加密错误
+var (
+	ErrKeyringNotConfigured = errors.New("未配置数据加密密钥，请设置DATA_ENCRYPTION_KEYS")
+	ErrColumnHasCiphertext  = errors.New("该列仍有加密数据，请先解密后再删除配置")
+)
+
+// ColumnInfo 加密列在数据库中的定义
+type ColumnInfo struct {
+	DataType     string
+	IsPrimaryKey bool
+}
+
+// RewriteResult 重新加密或解密的结果
+type RewriteResult struct {
+	ConfigID   string `json:"config_id"`
+	KeyVersion int    `json:"key_version,omitempty"` // 重新加密使用的密钥版本
+	Scanned    int64  `json:"scanned"`               // 需要处理的值数量
+	Rewritten  int64  `json:"rewritten"`             // 成功处理的值数量
+	Failed     int64  `json:"failed"`                // 解密失败的值数量（如旧密钥已移除）
+}
+
+// KeyUsage 列中各密钥版本的值数量
+type KeyUsage struct {
+	ConfigID         string           `json:"config_id"`
+	ActiveKeyVersion int              `json:"active_key_version"`
+	Counts           map[string]int64 `json:"counts"` // 键为"aes:v1"、"pgcrypto:v2"或"plaintext"
+}
+
+// Status 加密功能状态
+type Status struct {
+	KeyringConfigured bool   `json:"keyring_configured"`
+	ActiveKeyVersion  int    `json:"active_key_version,omitempty"`
+	KeyVersions       []int  `json:"key_versions,omitempty"`
+	KeyringError      string `json:"keyring_error,omitempty"`
+}
+
+// Service 敏感列加密服务
+type Service struct {
+	db         *gorm.DB
+	keyring    *Keyring
+	keyringErr error
+	// inspectColumn 查询列定义，默认查询PostgreSQL的information_schema
+	inspectColumn func(ctx context.Context, schemaName, tableName, columnName string) (*ColumnInfo, error)
+}
+
+// defaultService 全局加密服务，供同步写入和数据查看使用
+var defaultService atomic.Pointer[Service]
+
+// NewService 创建敏感列加密服务实例，密钥从环境变量加载
+func NewService(db *gorm.DB) *Service {
+	keyring, err := LoadKeyring()
+	s := NewServiceWithKeyring(db, keyring)
+	s.keyringErr = err
+	return s
+}
+
+// NewServiceWithKeyring 使用指定密钥环创建敏感列加密服务实例
+func NewServiceWithKeyring(db *gorm.DB, keyring *Keyring) *Service {
+	s := &Service{db: db, keyring: keyring}
+	s.inspectColumn = s.inspectPostgresColumn
+	return s
+}
+
+// SetDefault 设置全局加密服务
+func SetDefault(s *Service) {
+	defaultService.Store(s)
+}
+
+// EncryptRows 使用全局加密服务加密写入数据，未设置全局服务时原样返回
+func EncryptRows(ctx context.Context, schemaName, tableName string, rows []map[string]interface{}) ([]map[string]interface{}, error) {
+	if s := defaultService.Load(); s != nil {
+		return s.EncryptRows(ctx, schemaName, tableName, rows)
+	}
+	return rows, nil
+}
+
+// DecryptRows 使用全局加密服务解密查看数据，未设置全局服务时原样返回
+func DecryptRows(ctx context.Context, schemaName, tableName string, rows []map[string]interface{}, roles []string) []map[string]interface{} {
+	if s := defaultService.Load(); s != nil {
+		return s.DecryptRows(ctx, schemaName, tableName, rows, roles)
+	}
+	return rows
+}
+
+// GetStatus 获取加密功能状态
+func (s *Service) GetStatus() Status {
+	status := Status{KeyringConfigured: s.keyring != nil}
+	if s.keyring != nil {
+		status.ActiveKeyVersion = s.keyring.Active()
+		status.KeyVersions = s.keyring.Versions()
+	} else if s.keyringErr != nil {
+		status.KeyringError = s.keyringErr.Error()
+	}
+	return status
+}
+
+// === 配置管理 ===
+
+// CreateColumnEncryption 创建列加密配置，已有数据不会自动加密，需调用RotateColumn
+func (s *Service) CreateColumnEncryption(ctx context.Context, cfg *models.ColumnEncryption) error {
+	if err := s.validateConfig(ctx, cfg); err != nil {
+		return err
+	}
+
+	var count int64
+	if err := s.db.WithContext(ctx).Model(&models.ColumnEncryption{}).
+		Where("schema_name = ? AND table_name = ? AND column_name = ?", cfg.SchemaName, cfg.TableName, cfg.ColumnName).
+		Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return errors.New("该列已配置加密")
+	}
+
+	cfg.ID = ""
+	return s.db.WithContext(ctx).Create(cfg).Error
+}
+
+// GetColumnEncryptions 分页获取列加密配置
+func (s *Service) GetColumnEncryptions(ctx context.Context, page, pageSize int, schemaName, tableName string) ([]models.ColumnEncryption, int64, error) {
+	var configs []models.ColumnEncryption
+	var total int64
+
+	query := s.db.WithContext(ctx).Model(&models.ColumnEncryption{})
+	if schemaName != "" {
+		query = query.Where("schema_name = ?", schemaName)
+	}
+	if tableName != "" {
+		query = query.Where("table_name = ?", tableName)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * pageSize
+	if err := query.Order("schema_name, table_name, column_name").Offset(offset).Limit(pageSize).Find(&configs).Error; err != nil {
+		return nil, 0, err
+	}
+	return configs, total, nil
+}
+
+// GetColumnEncryption 获取列加密配置详情
+func (s *Service) GetColumnEncryption(ctx context.Context, id string) (*models.ColumnEncryption, error) {
+	var cfg models.ColumnEncryption
+	if err := s.db.WithContext(ctx).First(&cfg, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
+
+// UpdateColumnEncryption 更新加密方式、授权角色和描述，列位置不可修改；修改加密方式后需调用RotateColumn转换已有数据
+func (s *Service) UpdateColumnEncryption(ctx context.Context, id string, updates map[string]interface{}) error {
+	cfg, err := s.GetColumnEncryption(ctx, id)
+	if err != nil {
+		return err
+	}
+	if mode, ok := updates["mode"].(string); ok && mode != cfg.Mode {
+		if err := s.ensureMode(ctx, mode); err != nil {
+			return err
+		}
+	}
+	delete(updates, "id")
+	delete(updates, "schema_name")
+	delete(updates, "table_name")
+	delete(updates, "column_name")
+
+	return s.db.WithContext(ctx).Model(&models.ColumnEncryption{}).Where("id = ?", id).Updates(updates).Error
+}
+
+// DeleteColumnEncryption 删除列加密配置，列中仍有密文时不允许删除
+func (s *Service) DeleteColumnEncryption(ctx context.Context, id string) error {
+	cfg, err := s.GetColumnEncryption(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	var count int64
+	err = s.db.WithContext(ctx).Raw(fmt.Sprintf("SELECT COUNT(*) FROM %s WHERE %s LIKE ?", qualifiedTable(cfg), utils.QuoteIdent(cfg.ColumnName)),
+		ciphertextPrefix+"%").Scan(&count).Error
+	if err != nil {
+		return fmt.Errorf("检查加密数据失败: %w", err)
+	}
+	if count > 0 {
+		return ErrColumnHasCiphertext
+	}
+
+	return s.db.WithContext(ctx).Delete(&models.ColumnEncryption{}, "id = ?", id).Error
+}
+
+// validateConfig 校验加密配置
+func (s *Service) validateConfig(ctx context.Context, cfg *models.ColumnEncryption) error {
+	for _, name := range []string{cfg.SchemaName, cfg.TableName, cfg.ColumnName} {
+		if !identifierPattern.MatchString(name) {
+			return fmt.Errorf("无效的标识符: %q", name)
+		}
+	}
+	if cfg.Mode == "" {
+		cfg.Mode = models.EncryptionModeAES
+	}
+	if err := s.ensureMode(ctx, cfg.Mode); err != nil {
+		return err
+	}
+
+	info, err := s.inspectColumn(ctx, cfg.SchemaName, cfg.TableName, cfg.ColumnName)
+	if err != nil {
+		return err
+	}
+	if !slices.Contains(textDataTypes, info.DataType) {
+		return fmt.Errorf("列类型为%s，只能加密文本类型的列", info.DataType)
+	}
+	if info.IsPrimaryKey {
+		return errors.New("主键列不能加密")
+	}
+	return nil
+}
+
+// ensureMode 校验加密方式，pgcrypto方式确保扩展已安装
+func (s *Service) ensureMode(ctx context.Context, mode string) error {
+	if s.keyring == nil {
+		return ErrKeyringNotConfigured
+	}
+	switch mode {
+	case models.EncryptionModeAES:
+		return nil
+	case models.EncryptionModePgcrypto:
+		if err := s.db.WithContext(ctx).Exec("CREATE EXTENSION IF NOT EXISTS pgcrypto").Error; err != nil {
+			return fmt.Errorf("启用pgcrypto扩展失败: %w", err)
+		}
+		return nil
+	default:
+		return fmt.Errorf("不支持的加密方式: %s", mode)
+	}
+}
+
+// inspectPostgresColumn 查询PostgreSQL中列的类型和是否为主键
+func (s *Service) inspectPostgresColumn(ctx context.Context, schemaName, tableName, columnName string) (*ColumnInfo, error) {
+	var dataTypes []string
+	err := s.db.WithContext(ctx).Raw(`SELECT data_type FROM information_schema.columns
+		WHERE table_schema = ? AND table_name = ? AND column_name = ?`, schemaName, tableName, columnName).
+		Scan(&dataTypes).Error
+	if err != nil {
+		return nil, err
+	}
+	if len(dataTypes) == 0 {
+		return nil, fmt.Errorf("列 %s.%s.%s 不存在", schemaName, tableName, columnName)
+	}
+
+	var pkCount int64
+	err = s.db.WithContext(ctx).Raw(`SELECT COUNT(*) FROM information_schema.table_constraints tc
+		JOIN information_schema.key_column_usage kcu ON tc.constraint_name = kcu.constraint_name AND tc.table_schema = kcu.table_schema
+		WHERE tc.constraint_type = 'PRIMARY KEY' AND tc.table_schema = ? AND tc.table_name = ? AND kcu.column_name = ?`,
+		schemaName, tableName, columnName).Scan(&pkCount).Error
+	if err != nil {
+		return nil, err
+	}
+	return &ColumnInfo{DataType: dataTypes[0], IsPrimaryKey: pkCount > 0}, nil
+}
+
+// === 加解密 ===
+
+// tableConfigs 获取表的列加密配置
+func (s *Service) tableConfigs(ctx context.Context, schemaName, tableName string) ([]models.ColumnEncryption, error) {
+	var configs []models.ColumnEncryption
+	err := s.db.WithContext(ctx).Where("schema_name = ? AND table_name = ?", schemaName, tableName).Find(&configs).Error
+	return configs, err
+}
+
+// EncryptRows 加密写入数据中的配置列，返回的行为副本，不修改入参
+func (s *Service) EncryptRows(ctx context.Context, schemaName, tableName string, rows []map[string]interface{}) ([]map[string]interface{}, error) {
+	if len(rows) == 0 {
+		return rows, nil
+	}
+	configs, err := s.tableConfigs(ctx, schemaName, tableName)
+	if err != nil {
+		return nil, fmt.Errorf("获取列加密配置失败: %w", err)
+	}
+	if len(configs) == 0 {
+		return rows, nil
+	}
+	if s.keyring == nil {
+		return nil, ErrKeyringNotConfigured
+	}
+
+	result := make([]map[string]interface{}, len(rows))
+	for i, row := range rows {
+		copied := make(map[string]interface{}, len(row))
+		for k, v := range row {
+			copied[k] = v
+		}
+		result[i] = copied
+	}
+
+	for _, cfg := range configs {
+		var indexes []int
+		var plaintexts []string
+		for i, row := range result {
+			value, ok := row[cfg.ColumnName]
+			if !ok || value == nil {
+				continue
+			}
+			text := valueToString(value)
+			if IsCiphertext(text) {
+				continue
+			}
+			indexes = append(indexes, i)
+			plaintexts = append(plaintexts, text)
+		}
+		if len(plaintexts) == 0 {
+			continue
+		}
+
+		encrypted, err := s.encryptValues(ctx, cfg.Mode, plaintexts)
+		if err != nil {
+			return nil, fmt.Errorf("加密列 %s 失败: %w", cfg.ColumnName, err)
+		}
+		for j, i := range indexes {
+			result[i][cfg.ColumnName] = encrypted[j]
+		}
+	}
+	return result, nil
+}
+
+// DecryptRows 解密查看数据中的配置列，授权角色得到明文，其他角色和解密失败的值得到遮蔽值
+func (s *Service) DecryptRows(ctx context.Context, schemaName, tableName string, rows []map[string]interface{}, roles []string) []map[string]interface{} {
+	if len(rows) == 0 {
+		return rows
+	}
+	configs, err := s.tableConfigs(ctx, schemaName, tableName)
+	if err != nil {
+		slog.ErrorContext(ctx, "获取列加密配置失败", "schema", schemaName, "table", tableName, "error", err)
+		return rows
+	}
+
+	for _, cfg := range configs {
+		if !isAuthorized(cfg, roles) {
+			for _, row := range rows {
+				if value, ok := row[cfg.ColumnName]; ok && value != nil {
+					row[cfg.ColumnName] = MaskedValue
+				}
+			}
+			continue
+		}
+
+		var indexes []int
+		var values []string
+		for i, row := range rows {
+			value, ok := row[cfg.ColumnName]
+			if !ok || value == nil {
+				continue
+			}
+			indexes = append(indexes, i)
+			values = append(values, valueToString(value))
+		}
+		decrypted := s.decryptValues(ctx, values)
+		for j, i := range indexes {
+			rows[i][cfg.ColumnName] = decrypted[j]
+		}
+	}
+	return rows
+}
+
+// isAuthorized 判断角色是否可查看该列明文
+func isAuthorized(cfg models.ColumnEncryption, roles []string) bool {
+	for _, role := range roles {
+		if role == models.RoleAdmin || slices.Contains(cfg.AuthorizedRoles, role) {
+			return true
+		}
+	}
+	return false
+}
+
+// encryptValues 使用当前密钥加密
+func (s *Service) encryptValues(ctx context.Context, mode string, plaintexts []string) ([]string, error) {
+	version := s.keyring.Active()
+	key, _ := s.keyring.Key(version)
+
+	var payloads []string
+	switch mode {
+	case models.EncryptionModeAES:
+		payloads = make([]string, len(plaintexts))
+		for i, plaintext := range plaintexts {
+			payload, err := aesEncrypt(key, plaintext)
+			if err != nil {
+				return nil, err
+			}
+			payloads[i] = payload
+		}
+	case models.EncryptionModePgcrypto:
+		var err error
+		if payloads, err = pgcryptoEncrypt(ctx, s.db, key, plaintexts); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("不支持的加密方式: %s", mode)
+	}
+
+	results := make([]string, len(payloads))
+	for i, payload := range payloads {
+		results[i] = formatCiphertext(mode, version, payload)
+	}
+	return results, nil
+}
+
+// decryptValues 解密，非密文按历史明文原样返回，解密失败的值返回遮蔽值
+func (s *Service) decryptValues(ctx context.Context, values []string) []string {
+	results := make([]string, len(values))
+	// pgcrypto密文按密钥版本分组批量解密
+	pgcryptoGroups := make(map[int][]int)
+
+	for i, value := range values {
+		parsed, ok := parseCiphertext(value)
+		if !ok {
+			results[i] = value
+			continue
+		}
+		results[i] = MaskedValue
+		if s.keyring == nil {
+			continue
+		}
+		key, ok := s.keyring.Key(parsed.version)
+		if !ok {
+			slog.WarnContext(ctx, "解密失败，密钥版本不存在", "key_version", parsed.version)
+			continue
+		}
+
+		switch parsed.mode {
+		case models.EncryptionModeAES:
+			plaintext, err := aesDecrypt(key, parsed.payload)
+			if err != nil {
+				slog.WarnContext(ctx, "解密失败", "key_version", parsed.version, "error", err)
+				continue
+			}
+			results[i] = plaintext
+		case models.EncryptionModePgcrypto:
+			pgcryptoGroups[parsed.version] = append(pgcryptoGroups[parsed.version], i)
+		}
+	}
+
+	for version, indexes := range pgcryptoGroups {
+		key, _ := s.keyring.Key(version)
+		payloads := make([]string, len(indexes))
+		for j, i := range indexes {
+			parsed, _ := parseCiphertext(values[i])
+			payloads[j] = parsed.payload
+		}
+		plaintexts, err := pgcryptoDecrypt(ctx, s.db, key, payloads)
+		if err != nil {
+			slog.WarnContext(ctx, "解密失败", "key_version", version, "error", err)
+			continue
+		}
+		for j, i := range indexes {
+			results[i] = plaintexts[j]
+		}
+	}
+	return results
+}
+
+// rewrapValue 将单个值转换为目标形式：decrypt为true时还原明文，否则使用当前密钥和配置的加密方式重新加密；changed为false表示无需处理
+func (s *Service) rewrapValue(ctx context.Context, cfg *models.ColumnEncryption, value string, decrypt bool) (string, bool, error) {
+	parsed, isCiphertext := parseCiphertext(value)
+	if decrypt && !isCiphertext {
+		return value, false, nil
+	}
+	if !decrypt && isCiphertext && parsed.mode == cfg.Mode && parsed.version == s.keyring.Active() {
+		return value, false, nil
+	}
+
+	plaintext := value
+	if isCiphertext {
+		plaintexts := s.decryptValues(ctx, []string{value})
+		if plaintexts[0] == MaskedValue {
+			return "", false, fmt.Errorf("无法解密密钥版本%d的数据", parsed.version)
+		}
+		plaintext = plaintexts[0]
+	}
+	if decrypt {
+		return plaintext, true, nil
+	}
+
+	encrypted, err := s.encryptValues(ctx, cfg.Mode, []string{plaintext})
+	if err != nil {
+		return "", false, err
+	}
+	return encrypted[0], true, nil
+}
+
+// === 密钥轮换工具 ===
+
+// GetKeyUsage 统计列中各密钥版本的值数量，用于确认旧密钥是否还在使用
+func (s *Service) GetKeyUsage(ctx context.Context, id string) (*KeyUsage, error) {
+	cfg, err := s.GetColumnEncryption(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	column := utils.QuoteIdent(cfg.ColumnName)
+	var rows []struct {
+		KeyLabel string
+		Total    int64
+	}
+	err = s.db.WithContext(ctx).Raw(fmt.Sprintf(`SELECT CASE WHEN %[1]s LIKE 'enc:%%' THEN split_part(%[1]s, ':', 2) || ':' || split_part(%[1]s, ':', 3) ELSE 'plaintext' END AS key_label,
+		COUNT(*) AS total FROM %[2]s WHERE %[1]s IS NOT NULL GROUP BY 1`, column, qualifiedTable(cfg))).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("统计密钥使用情况失败: %w", err)
+	}
+
+	usage := &KeyUsage{ConfigID: cfg.ID, Counts: make(map[string]int64, len(rows))}
+	if s.keyring != nil {
+		usage.ActiveKeyVersion = s.keyring.Active()
+	}
+	for _, row := range rows {
+		usage.Counts[row.KeyLabel] = row.Total
+	}
+	return usage, nil
+}
+
+// RotateColumn 使用当前密钥和配置的加密方式重新加密列中的旧版本密文和历史明文
+func (s *Service) RotateColumn(ctx context.Context, id string) (*RewriteResult, error) {
+	return s.rewriteColumn(ctx, id, false)
+}
+
+// DecryptColumn 将列中的密文还原为明文，用于取消列加密前的数据转换
+func (s *Service) DecryptColumn(ctx context.Context, id string) (*RewriteResult, error) {
+	return s.rewriteColumn(ctx, id, true)
+}
+
+// rewriteBatchSize 轮换时每批处理的行数
+const rewriteBatchSize = 500
+
+// rewriteColumn 按ctid分批读取需要处理的值并逐行更新，解密失败的值跳过并计数
+func (s *Service) rewriteColumn(ctx context.Context, id string, decrypt bool) (*RewriteResult, error) {
+	if s.keyring == nil {
+		return nil, ErrKeyringNotConfigured
+	}
+	cfg, err := s.GetColumnEncryption(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	column := utils.QuoteIdent(cfg.ColumnName)
+	table := qualifiedTable(cfg)
+	// 已是目标形式的值不需要处理
+	condition := column + " LIKE ?"
+	pattern := ciphertextPrefix + "%"
+	if !decrypt {
+		condition = column + " NOT LIKE ?"
+		pattern = formatCiphertext(cfg.Mode, s.keyring.Active(), "%")
+	}
+
+	result := &RewriteResult{ConfigID: cfg.ID}
+	if !decrypt {
+		result.KeyVersion = s.keyring.Active()
+	}
+	startTime := time.Now()
+	lastRowID := "(0,0)"
+	for {
+		var batch []struct {
+			RowID string
+			Value string
+		}
+		err := s.db.WithContext(ctx).Raw(fmt.Sprintf(`SELECT ctid::text AS row_id, %[1]s AS value FROM %[2]s
+			WHERE %[1]s IS NOT NULL AND %[3]s AND ctid > ?::tid ORDER BY ctid LIMIT %[4]d`, column, table, condition, rewriteBatchSize),
+			pattern, lastRowID).Scan(&batch).Error
+		if err != nil {
+			return result, fmt.Errorf("读取待处理数据失败: %w", err)
+		}
+		if len(batch) == 0 {
+			break
+		}
+		lastRowID = batch[len(batch)-1].RowID
+		result.Scanned += int64(len(batch))
+
+		err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			for _, row := range batch {
+				value, changed, err := s.rewrapValue(ctx, cfg, row.Value, decrypt)
+				if err != nil {
+					result.Failed++
+					continue
+				}
+				if !changed {
+					continue
+				}
+				if err := tx.Exec(fmt.Sprintf("UPDATE %s SET %s = ? WHERE ctid = ?::tid", table, column), value, row.RowID).Error; err != nil {
+					return err
+				}
+				result.Rewritten++
+			}
+			return nil
+		})
+		if err != nil {
+			return result, fmt.Errorf("更新数据失败: %w", err)
+		}
+	}
+
+	slog.InfoContext(ctx, "列数据重新处理完成", "config_id", cfg.ID, "decrypt", decrypt, "scanned", result.Scanned,
+		"rewritten", result.Rewritten, "failed", result.Failed, "key_version", result.KeyVersion, "duration", time.Since(startTime))
+	return result, nil
+}
+
+// valueToString 将写入值转换为加密用的字符串
+func valueToString(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case []byte:
+		return string(v)
+	case time.Time:
+		return v.Format(time.RFC3339Nano)
+	case map[string]interface{}, []interface{}:
+		encoded, err := json.Marshal(v)
+		if err == nil {
+			return string(encoded)
+		}
+	}
+	return fmt.Sprint(value)
+}
+
+// qualifiedTable 带引号的schema.表名
+func qualifiedTable(cfg *models.ColumnEncryption) string {
+	return utils.QuoteTable(cfg.SchemaName, cfg.TableName)
+}
@@ -0,0 +1,265 @@
+/*
+ * @module service/encryption/encryption_service_test
+ * @description 敏感列加密测试，覆盖密钥环加载、AES加解密、写入加密、按角色解密遮蔽、密钥轮换和配置校验
+ * @architecture 测试层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow 创建密钥环和加密配置 -> 加密写入数据 -> 按角色解密 -> 轮换密钥重新加密
+ * @rules 使用内存sqlite和桩列定义，不依赖PostgreSQL和pgcrypto
+ * @dependencies gorm.io/driver/sqlite, stretchr/testify
+ * @refs service/encryption/encryption_service.go, service/encryption/keyring.go, service/encryption/cipher.go
+ */
+
+package encryption
+
+import (
+	"bytes"
+	"context"
+	"datahub-service/service/models"
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+func testKey(b byte) []byte {
+	return bytes.Repeat([]byte{b}, keySize)
+}
+
+func setupService(t *testing.T, keyring *Keyring) *Service {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: gormlogger.Default.LogMode(gormlogger.Silent),
+	})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&models.ColumnEncryption{}))
+
+	s := NewServiceWithKeyring(db, keyring)
+	s.inspectColumn = func(ctx context.Context, schemaName, tableName, columnName string) (*ColumnInfo, error) {
+		switch columnName {
+		case "id":
+			return &ColumnInfo{DataType: "character varying", IsPrimaryKey: true}, nil
+		case "age":
+			return &ColumnInfo{DataType: "integer"}, nil
+		default:
+			return &ColumnInfo{DataType: "text"}, nil
+		}
+	}
+	return s
+}
+
+func createConfig(t *testing.T, s *Service, column string, roles ...string) *models.ColumnEncryption {
+	cfg := &models.ColumnEncryption{
+		SchemaName:      "main",
+		TableName:       "residents",
+		ColumnName:      column,
+		AuthorizedRoles: roles,
+	}
+	require.NoError(t, s.CreateColumnEncryption(context.Background(), cfg))
+	return cfg
+}
+
+func TestLoadKeyring(t *testing.T) {
+	t.Setenv("DATA_ENCRYPTION_KEYS", fmt.Sprintf("1:%s, 2:%s",
+		base64.StdEncoding.EncodeToString(testKey(1)), base64.StdEncoding.EncodeToString(testKey(2))))
+	t.Setenv("DATA_ENCRYPTION_ACTIVE_KEY", "")
+
+	keyring, err := LoadKeyring()
+	require.NoError(t, err)
+	assert.Equal(t, 2, keyring.Active())
+	assert.Equal(t, []int{1, 2}, keyring.Versions())
+
+	t.Setenv("DATA_ENCRYPTION_ACTIVE_KEY", "1")
+	keyring, err = LoadKeyring()
+	require.NoError(t, err)
+	assert.Equal(t, 1, keyring.Active())
+
+	t.Setenv("DATA_ENCRYPTION_ACTIVE_KEY", "3")
+	_, err = LoadKeyring()
+	assert.Error(t, err)
+
+	t.Setenv("DATA_ENCRYPTION_KEYS", "1:"+base64.StdEncoding.EncodeToString([]byte("short")))
+	t.Setenv("DATA_ENCRYPTION_ACTIVE_KEY", "")
+	_, err = LoadKeyring()
+	assert.Error(t, err)
+}
+
+func TestAESRoundTrip(t *testing.T) {
+	payload, err := aesEncrypt(testKey(1), "110101199001011234")
+	require.NoError(t, err)
+
+	plaintext, err := aesDecrypt(testKey(1), payload)
+	require.NoError(t, err)
+	assert.Equal(t, "110101199001011234", plaintext)
+
+	_, err = aesDecrypt(testKey(2), payload)
+	assert.Error(t, err)
+
+	value := formatCiphertext(models.EncryptionModeAES, 3, payload)
+	assert.True(t, IsCiphertext(value))
+	parsed, ok := parseCiphertext(value)
+	require.True(t, ok)
+	assert.Equal(t, 3, parsed.version)
+	assert.Equal(t, payload, parsed.payload)
+	assert.False(t, IsCiphertext("enc:aes:x:abc"))
+	assert.False(t, IsCiphertext("plain"))
+}
+
+func TestEncryptAndDecryptRows(t *testing.T) {
+	keyring, err := NewKeyring(map[int][]byte{1: testKey(1)}, 0)
+	require.NoError(t, err)
+	s := setupService(t, keyring)
+	createConfig(t, s, "id_card_no", "steward")
+	ctx := context.Background()
+
+	existing, err := s.encryptValues(ctx, models.EncryptionModeAES, []string{"already"})
+	require.NoError(t, err)
+
+	input := []map[string]interface{}{
+		{"name": "张三", "id_card_no": "110101199001011234"},
+		{"name": "李四", "id_card_no": nil},
+		{"name": "王五", "id_card_no": existing[0]},
+	}
+	rows, err := s.EncryptRows(ctx, "main", "residents", input)
+	require.NoError(t, err)
+
+	// 入参不被修改，空值和已加密的值保持不变
+	assert.Equal(t, "110101199001011234", input[0]["id_card_no"])
+	assert.True(t, strings.HasPrefix(rows[0]["id_card_no"].(string), "enc:aes:v1:"))
+	assert.Equal(t, "张三", rows[0]["name"])
+	assert.Nil(t, rows[1]["id_card_no"])
+	assert.Equal(t, existing[0], rows[2]["id_card_no"])
+
+	copyRows := func() []map[string]interface{} {
+		copied := make([]map[string]interface{}, len(rows))
+		for i, row := range rows {
+			copied[i] = map[string]interface{}{"name": row["name"], "id_card_no": row["id_card_no"]}
+		}
+		return copied
+	}
+
+	authorized := s.DecryptRows(ctx, "main", "residents", copyRows(), []string{"steward"})
+	assert.Equal(t, "110101199001011234", authorized[0]["id_card_no"])
+	assert.Nil(t, authorized[1]["id_card_no"])
+	assert.Equal(t, "already", authorized[2]["id_card_no"])
+
+	admin := s.DecryptRows(ctx, "main", "residents", copyRows(), []string{models.RoleAdmin})
+	assert.Equal(t, "110101199001011234", admin[0]["id_card_no"])
+
+	masked := s.DecryptRows(ctx, "main", "residents", copyRows(), []string{"viewer"})
+	assert.Equal(t, MaskedValue, masked[0]["id_card_no"])
+	assert.Nil(t, masked[1]["id_card_no"])
+	assert.Equal(t, "张三", masked[0]["name"])
+
+	// 未配置加密的表原样返回
+	other, err := s.EncryptRows(ctx, "main", "other", input)
+	require.NoError(t, err)
+	assert.Equal(t, "110101199001011234", other[0]["id_card_no"])
+}
+
+func TestEncryptRowsWithoutKeyring(t *testing.T) {
+	keyring, err := NewKeyring(map[int][]byte{1: testKey(1)}, 0)
+	require.NoError(t, err)
+	s := setupService(t, keyring)
+	createConfig(t, s, "id_card_no")
+
+	s.keyring = nil
+	_, err = s.EncryptRows(context.Background(), "main", "residents", []map[string]interface{}{{"id_card_no": "x"}})
+	assert.ErrorIs(t, err, ErrKeyringNotConfigured)
+}
+
+func TestRewrapValueRotatesKey(t *testing.T) {
+	keyring, err := NewKeyring(map[int][]byte{1: testKey(1), 2: testKey(2)}, 1)
+	require.NoError(t, err)
+	s := setupService(t, keyring)
+	cfg := createConfig(t, s, "id_card_no")
+	ctx := context.Background()
+
+	old, err := s.encryptValues(ctx, models.EncryptionModeAES, []string{"secret"})
+	require.NoError(t, err)
+
+	_, changed, err := s.rewrapValue(ctx, cfg, old[0], false)
+	require.NoError(t, err)
+	assert.False(t, changed, "当前版本的密文无需重新加密")
+
+	// 切换当前版本后重新加密
+	s.keyring, err = NewKeyring(map[int][]byte{1: testKey(1), 2: testKey(2)}, 2)
+	require.NoError(t, err)
+	rotated, changed, err := s.rewrapValue(ctx, cfg, old[0], false)
+	require.NoError(t, err)
+	assert.True(t, changed)
+	assert.True(t, strings.HasPrefix(rotated, "enc:aes:v2:"))
+
+	// 明文也会被加密
+	encrypted, changed, err := s.rewrapValue(ctx, cfg, "plain", false)
+	require.NoError(t, err)
+	assert.True(t, changed)
+	assert.True(t, IsCiphertext(encrypted))
+
+	// 移除旧密钥后仍可用新密钥解密
+	s.keyring, err = NewKeyring(map[int][]byte{2: testKey(2)}, 0)
+	require.NoError(t, err)
+	plaintext, changed, err := s.rewrapValue(ctx, cfg, rotated, true)
+	require.NoError(t, err)
+	assert.True(t, changed)
+	assert.Equal(t, "secret", plaintext)
+
+	_, _, err = s.rewrapValue(ctx, cfg, old[0], true)
+	assert.Error(t, err, "旧密钥移除后无法解密旧版本密文")
+}
+
+func TestCreateColumnEncryptionValidation(t *testing.T) {
+	keyring, err := NewKeyring(map[int][]byte{1: testKey(1)}, 0)
+	require.NoError(t, err)
+	s := setupService(t, keyring)
+	ctx := context.Background()
+
+	cases := []struct {
+		name string
+		cfg  models.ColumnEncryption
+	}{
+		{"无效标识符", models.ColumnEncryption{SchemaName: "main", TableName: "residents; drop", ColumnName: "name"}},
+		{"非文本列", models.ColumnEncryption{SchemaName: "main", TableName: "residents", ColumnName: "age"}},
+		{"主键列", models.ColumnEncryption{SchemaName: "main", TableName: "residents", ColumnName: "id"}},
+		{"不支持的方式", models.ColumnEncryption{SchemaName: "main", TableName: "residents", ColumnName: "name", Mode: "rot13"}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := tc.cfg
+			assert.Error(t, s.CreateColumnEncryption(ctx, &cfg))
+		})
+	}
+
+	createConfig(t, s, "name")
+	assert.Error(t, s.CreateColumnEncryption(ctx, &models.ColumnEncryption{SchemaName: "main", TableName: "residents", ColumnName: "name"}),
+		"同一列不能重复配置")
+
+	s.keyring = nil
+	assert.ErrorIs(t, s.CreateColumnEncryption(ctx, &models.ColumnEncryption{SchemaName: "main", TableName: "residents", ColumnName: "phone"}),
+		ErrKeyringNotConfigured)
+}
+
+func TestDeleteColumnEncryptionRequiresPlaintext(t *testing.T) {
+	keyring, err := NewKeyring(map[int][]byte{1: testKey(1)}, 0)
+	require.NoError(t, err)
+	s := setupService(t, keyring)
+	ctx := context.Background()
+	require.NoError(t, s.db.Exec(`CREATE TABLE residents (id TEXT PRIMARY KEY, id_card_no TEXT)`).Error)
+	cfg := createConfig(t, s, "id_card_no")
+
+	rows, err := s.EncryptRows(ctx, "main", "residents", []map[string]interface{}{{"id": "1", "id_card_no": "secret"}})
+	require.NoError(t, err)
+	require.NoError(t, s.db.Table("residents").Create(rows[0]).Error)
+
+	assert.ErrorIs(t, s.DeleteColumnEncryption(ctx, cfg.ID), ErrColumnHasCiphertext)
+
+	require.NoError(t, s.db.Exec(`UPDATE residents SET id_card_no = 'secret'`).Error)
+	require.NoError(t, s.DeleteColumnEncryption(ctx, cfg.ID))
+
+	_, err = s.GetColumnEncryption(ctx, cfg.ID)
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+}
@@ -0,0 +1,114 @@
+/*
+ * @module service/encryption/keyring
+ * @description 数据加密密钥环，从环境变量加载带版本号的AES-256密钥，新写入使用当前版本，历史版本用于解密
+ * @architecture 分层架构 - 业务服务层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow DATA_ENCRYPTION_KEYS + DATA_ENCRYPTION_ACTIVE_KEY -> 密钥环 -> 加密使用当前版本，解密按密文中的版本选择密钥
+ * @rules 密钥为base64编码的32字节；未指定当前版本时使用最大版本；轮换时先追加新版本密钥并切换当前版本，重新加密完成前不得移除旧版本
+ * @dependencies 无
+ * @refs service/encryption/cipher.go, service/encryption/encryption_service.go
+ */
+
+package encryption
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// keySize AES-256密钥长度
+const keySize = 32
+
+// Keyring 带版本号的密钥环
+type Keyring struct {
+	keys   map[int][]byte
+	active int
+}
+
+// NewKeyring 创建密钥环，active为0时使用最大版本
+func NewKeyring(keys map[int][]byte, active int) (*Keyring, error) {
+	if len(keys) == 0 {
+		return nil, errors.New("未配置数据加密密钥")
+	}
+	latest := 0
+	for version, key := range keys {
+		if version <= 0 {
+			return nil, fmt.Errorf("密钥版本必须为正整数: %d", version)
+		}
+		if len(key) != keySize {
+			return nil, fmt.Errorf("密钥版本%d长度为%d字节，应为%d字节", version, len(key), keySize)
+		}
+		latest = max(latest, version)
+	}
+	if active == 0 {
+		active = latest
+	}
+	if _, ok := keys[active]; !ok {
+		return nil, fmt.Errorf("当前密钥版本%d不存在", active)
+	}
+	return &Keyring{keys: keys, active: active}, nil
+}
+
+// LoadKeyring 从环境变量加载密钥环，DATA_ENCRYPTION_KEYS格式为"1:base64密钥,2:base64密钥"
+func LoadKeyring() (*Keyring, error) {
+	raw := strings.TrimSpace(os.Getenv("DATA_ENCRYPTION_KEYS"))
+	if raw == "" {
+		return nil, errors.New("未配置数据加密密钥")
+	}
+
+	keys := make(map[int][]byte)
+	for _, item := range strings.Split(raw, ",") {
+		versionStr, encoded, found := strings.Cut(strings.TrimSpace(item), ":")
+		if !found {
+			return nil, fmt.Errorf("密钥配置格式错误，应为 版本:base64密钥")
+		}
+		version, err := strconv.Atoi(versionStr)
+		if err != nil {
+			return nil, fmt.Errorf("密钥版本无效: %s", versionStr)
+		}
+		key, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("密钥版本%d不是有效的base64: %w", version, err)
+		}
+		if _, exists := keys[version]; exists {
+			return nil, fmt.Errorf("密钥版本%d重复", version)
+		}
+		keys[version] = key
+	}
+
+	active := 0
+	if value := os.Getenv("DATA_ENCRYPTION_ACTIVE_KEY"); value != "" {
+		version, err := strconv.Atoi(value)
+		if err != nil || version <= 0 {
+			return nil, fmt.Errorf("当前密钥版本无效: %s", value)
+		}
+		active = version
+	}
+	return NewKeyring(keys, active)
+}
+
+// Active 当前用于加密的密钥版本
+func (k *Keyring) Active() int {
+	return k.active
+}
+
+// Key 获取指定版本的密钥
+func (k *Keyring) Key(version int) ([]byte, bool) {
+	key, ok := k.keys[version]
+	return key, ok
+}
+
+// Versions 已配置的密钥版本，升序
+func (k *Keyring) Versions() []int {
+	versions := make([]int, 0, len(k.keys))
+	for version := range k.keys {
+		versions = append(versions, version)
+	}
+	sort.Ints(versions)
+	return versions
+}
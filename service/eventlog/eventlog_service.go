@@ -0,0 +1,213 @@
+/*
+ * @module service/eventlog/eventlog_service
+ * @description 应用事件日志服务，记录任务启动、批次失败等重要事件，自动关联请求ID和链路ID，同时输出结构化日志
+ * @architecture 分层架构 - 业务服务层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow 业务代码调用Record -> 从context取request_id/trace_id -> 输出slog日志 -> 写入application_events -> 通知监听器
+ * @rules 消息和属性中的敏感字段记录前脱敏；记录事件失败只输出日志，不影响业务流程；未设置全局服务时只输出日志；监听器在调用方goroutine中执行，不能阻塞
+ * @dependencies datahub-service/logger, datahub-service/service/tracing, gorm.io/gorm
+ * @refs service/models/application_event.go, api/middleware/request_id.go, api/controllers/event_log_controller.go, service/notification
+ */
+
+package eventlog
+
+import (
+	"context"
+	"datahub-service/logger"
+	"datahub-service/service/models"
+	"datahub-service/service/tracing"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+
+	"gorm.io/gorm"
+)
+
+// 事件类型
+const (
+	EventTaskStarted   = "task_started"
+	EventTaskCompleted = "task_completed"
+	EventTaskFailed    = "task_failed"
+	EventBatchFailed   = "batch_failed"
+
+	EventBackupVerified     = "backup_verified"
+	EventBackupCorrupt      = "backup_corrupt"
+	EventBackupVerifyFailed = "backup_verify_failed"
+
+	EventQualityTaskCompleted = "quality_task_completed"
+	EventQualityIssuesFound   = "quality_issues_found"
+	EventQualityTaskFailed    = "quality_task_failed"
+
+	EventInterfaceStale = "interface_stale" // 接口超过预期周期未同步成功
+	EventInterfaceFresh = "interface_fresh" // 过期接口恢复
+
+	EventContractViolated = "contract_violated" // 同步的数据不符合接口数据契约
+
+	EventReferenceOrphansFound = "reference_orphans_found" // 引用完整性检查发现孤立引用
+
+	EventPublicationReviewRequested   = "publication_review_requested"   // 主题接口提交发布评审
+	EventThematicInterfacePublished   = "thematic_interface_published"   // 主题接口通过评审并发布
+	EventThematicInterfaceUnpublished = "thematic_interface_unpublished" // 主题接口撤销发布
+
+	EventQualityGateFailed   = "quality_gate_failed"   // 同步写入后未通过质量门禁，接口被阻断
+	EventQualityGateReleased = "quality_gate_released" // 人工解除接口的质量门禁阻断
+	EventTaskSkipped         = "task_skipped"          // 上游接口被阻断，主题同步跳过执行
+
+	EventValueAlertFiring   = "value_alert_firing"   // 入库数据命中数据值告警规则
+	EventValueAlertResolved = "value_alert_resolved" // 数据值告警恢复
+
+	EventDataAccessGranted = "data_access_granted" // 数据使用申请审批通过，生成限期授权
+	EventDataAccessRevoked = "data_access_revoked" // 数据使用授权到期回收或被撤销
+
+	EventQuotaExceeded = "quota_exceeded" // 库或租户用量达到配额上限
+
+	EventDataSourceCircuitOpened = "datasource_circuit_opened" // 数据源连续拉取失败或探测失败，熔断依赖任务的执行
+	EventDataSourceCircuitClosed = "datasource_circuit_closed" // 数据源探测成功或人工恢复，结束熔断
+
+	EventGoldenComparisonFailed = "golden_comparison_failed" // 接口数据与基准数据集不一致或无法比较
+
+	EventInterfaceDeprecated           = "interface_deprecated"            // 接口被标记弃用或修改了下线时间
+	EventInterfaceDeprecationCancelled = "interface_deprecation_cancelled" // 接口撤销弃用
+	EventDeprecationNotice             = "deprecation_notice"              // 向接口的一个订阅方发出的弃用通知
+)
+
+// Event 待记录的事件
+type Event struct {
+	Type       string
+	Level      string // 默认为info
+	Message    string
+	ObjectType string
+	ObjectID   string
+	Attributes map[string]interface{}
+}
+
+// ListQuery 事件查询条件
+type ListQuery struct {
+	RequestID string
+	EventType string
+	ObjectID  string
+	Page      int
+	Size      int
+}
+
+// Listener 事件监听器，事件记录后调用
+type Listener func(ctx context.Context, event Event)
+
+// Service 应用事件日志服务
+type Service struct {
+	db *gorm.DB
+}
+
+// defaultService 全局事件日志服务，供同步执行等环节记录事件
+var defaultService atomic.Pointer[Service]
+
+// listeners 已注册的事件监听器，注册时整体替换切片
+var (
+	listeners   atomic.Pointer[[]Listener]
+	listenersMu sync.Mutex
+)
+
+// NewService 创建应用事件日志服务
+func NewService(db *gorm.DB) *Service {
+	return &Service{db: db}
+}
+
+// SetDefault 设置全局事件日志服务
+func SetDefault(s *Service) {
+	defaultService.Store(s)
+}
+
+// AddListener 注册事件监听器，如通知中心订阅同步、质量和备份事件
+func AddListener(listener Listener) {
+	listenersMu.Lock()
+	defer listenersMu.Unlock()
+	var updated []Listener
+	if current := listeners.Load(); current != nil {
+		updated = append(updated, *current...)
+	}
+	updated = append(updated, listener)
+	listeners.Store(&updated)
+}
+
+// Record 使用全局服务记录事件，未设置时只输出日志；记录后通知监听器
+func Record(ctx context.Context, event Event) {
+	// 事件会保存、在事件日志中返回并推送给订阅方，错误信息中的敏感字段先脱敏
+	event.Message = logger.SanitizeString(event.Message)
+	if event.Attributes != nil {
+		event.Attributes, _ = logger.SanitizeValue(event.Attributes).(map[string]interface{})
+	}
+
+	if s := defaultService.Load(); s != nil {
+		s.Record(ctx, event)
+	} else {
+		logEvent(ctx, event)
+	}
+
+	if current := listeners.Load(); current != nil {
+		if event.Level == "" {
+			event.Level = models.EventLevelInfo
+		}
+		for _, listener := range *current {
+			listener(ctx, event)
+		}
+	}
+}
+
+// Record 记录事件
+func (s *Service) Record(ctx context.Context, event Event) {
+	logEvent(ctx, event)
+
+	record := &models.ApplicationEvent{
+		EventType:  event.Type,
+		Level:      event.Level,
+		Message:    event.Message,
+		RequestID:  logger.RequestIDFromContext(ctx),
+		TraceID:    tracing.TraceIDFromContext(ctx),
+		ObjectType: event.ObjectType,
+		ObjectID:   event.ObjectID,
+		Attributes: event.Attributes,
+	}
+	// 事件写入不参与业务事务，也不受请求取消影响
+	if err := s.db.Create(record).Error; err != nil {
+		slog.ErrorContext(ctx, "记录应用事件失败", "event_type", event.Type, "object_id", event.ObjectID, "error", err)
+	}
+}
+
+// ListEvents 按条件分页查询事件，按时间倒序
+func (s *Service) ListEvents(query ListQuery) ([]models.ApplicationEvent, int64, error) {
+	db := s.db.Model(&models.ApplicationEvent{})
+	if query.RequestID != "" {
+		db = db.Where("request_id = ?", query.RequestID)
+	}
+	if query.EventType != "" {
+		db = db.Where("event_type = ?", query.EventType)
+	}
+	if query.ObjectID != "" {
+		db = db.Where("object_id = ?", query.ObjectID)
+	}
+
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	page, size := models.NormalizePage(query.Page, query.Size)
+	var events []models.ApplicationEvent
+	err := db.Order("created_at DESC").Offset((page - 1) * size).Limit(size).Find(&events).Error
+	return events, total, err
+}
+
+// logEvent 输出结构化日志，request_id和trace_id由日志处理器从context追加
+func logEvent(ctx context.Context, event Event) {
+	level := slog.LevelInfo
+	switch event.Level {
+	case models.EventLevelWarn:
+		level = slog.LevelWarn
+	case models.EventLevelError:
+		level = slog.LevelError
+	}
+	slog.Log(ctx, level, event.Message,
+		"event_type", event.Type,
+		"object_type", event.ObjectType,
+		"object_id", event.ObjectID)
+}
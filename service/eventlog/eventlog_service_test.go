@@ -0,0 +1,123 @@
+/*
+ * @module service/eventlog/eventlog_service_test
+ * @description 应用事件日志测试，覆盖事件关联请求ID、按请求ID查询以及结构化日志附带关联ID
+ * @architecture 测试层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow 准备sqlite和带请求ID的context -> 记录事件 -> 验证数据库记录和日志输出
+ * @rules 使用内存sqlite，不依赖外部服务；用例结束后恢复全局服务和默认日志
+ * @dependencies gorm.io/driver/sqlite, stretchr/testify
+ * @refs service/eventlog/eventlog_service.go, logger/context.go
+ */
+
+package eventlog
+
+import (
+	"bytes"
+	"context"
+	"datahub-service/logger"
+	"datahub-service/service/models"
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+func setupEventLogService(t *testing.T) *Service {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: gormlogger.Default.LogMode(gormlogger.Silent),
+	})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&models.ApplicationEvent{}))
+
+	s := NewService(db)
+	SetDefault(s)
+	t.Cleanup(func() { SetDefault(nil) })
+	return s
+}
+
+func captureLogs(t *testing.T) *bytes.Buffer {
+	var buf bytes.Buffer
+	previous := slog.Default()
+	slog.SetDefault(slog.New(logger.NewContextHandler(slog.NewJSONHandler(&buf, nil))))
+	t.Cleanup(func() { slog.SetDefault(previous) })
+	return &buf
+}
+
+func TestRecordWithRequestID(t *testing.T) {
+	s := setupEventLogService(t)
+	logs := captureLogs(t)
+
+	ctx := logger.WithRequestID(context.Background(), "req-123")
+	Record(ctx, Event{Type: EventTaskStarted, Message: "任务开始", ObjectType: "sync_task", ObjectID: "task-1"})
+	Record(ctx, Event{
+		Type: EventBatchFailed, Level: models.EventLevelError, Message: "批次失败",
+		ObjectType: "sync_task", ObjectID: "task-1", Attributes: map[string]interface{}{"interface_id": "if-1"},
+	})
+	Record(context.Background(), Event{Type: EventTaskStarted, Message: "定时任务开始", ObjectID: "task-2"})
+
+	events, total, err := s.ListEvents(ListQuery{RequestID: "req-123"})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+	require.Len(t, events, 2)
+	for _, event := range events {
+		assert.Equal(t, "req-123", event.RequestID)
+		assert.Equal(t, "task-1", event.ObjectID)
+	}
+
+	events, total, err = s.ListEvents(ListQuery{EventType: EventBatchFailed})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	assert.Equal(t, models.EventLevelError, events[0].Level)
+	assert.Equal(t, "if-1", events[0].Attributes["interface_id"])
+
+	_, total, err = s.ListEvents(ListQuery{ObjectID: "task-2"})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+
+	// 日志中携带请求ID和事件类型
+	decoder := json.NewDecoder(logs)
+	var entry map[string]interface{}
+	require.NoError(t, decoder.Decode(&entry))
+	assert.Equal(t, "req-123", entry["request_id"])
+	assert.Equal(t, EventTaskStarted, entry["event_type"])
+	require.NoError(t, decoder.Decode(&entry))
+	assert.Equal(t, "ERROR", entry["level"])
+	entry = map[string]interface{}{}
+	require.NoError(t, decoder.Decode(&entry))
+	assert.NotContains(t, entry, "request_id")
+}
+
+func TestRecordWithoutDefaultService(t *testing.T) {
+	SetDefault(nil)
+	logs := captureLogs(t)
+
+	Record(logger.WithRequestID(context.Background(), "req-9"), Event{Type: EventTaskFailed, Level: models.EventLevelWarn, Message: "任务失败"})
+
+	var entry map[string]interface{}
+	require.NoError(t, json.Unmarshal(logs.Bytes(), &entry))
+	assert.Equal(t, "WARN", entry["level"])
+	assert.Equal(t, "req-9", entry["request_id"])
+}
+
+func TestRecordNotifiesListeners(t *testing.T) {
+	t.Cleanup(func() { listeners.Store(nil) })
+
+	var received []Event
+	AddListener(func(ctx context.Context, event Event) {
+		received = append(received, event)
+	})
+
+	// 未设置全局服务时仍通知监听器，未指定级别时按info传递
+	Record(context.Background(), Event{Type: EventBackupCorrupt, Level: models.EventLevelWarn, Message: "备份文件已损坏", ObjectID: "backup-1"})
+	Record(context.Background(), Event{Type: EventTaskCompleted, Message: "任务完成", ObjectID: "task-1"})
+
+	require.Len(t, received, 2)
+	assert.Equal(t, EventBackupCorrupt, received[0].Type)
+	assert.Equal(t, models.EventLevelWarn, received[0].Level)
+	assert.Equal(t, models.EventLevelInfo, received[1].Level)
+}
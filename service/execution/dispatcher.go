@@ -0,0 +1,190 @@
+/*
+ * @module service/execution/dispatcher
+ * @description 执行命令分发器，控制面通过它把任务执行交给本进程或经Dapr发布订阅交给独立的worker
+ * @architecture 控制面/执行面分离架构 - 命令分发
+ * @documentReference ai_docs/refactor_sync_task.md
+ * @stateFlow 控制面创建执行命令 -> 分发器(本地goroutine / Dapr publish) -> Worker按命令类型执行
+ * @rules 命令只携带任务ID和执行参数，执行面从数据库加载任务详情；分发失败由调用方回写任务状态
+ * @dependencies net/http, encoding/json, datahub-service/service/tracing
+ * @refs service/execution/worker.go, service/basic_library/sync_task_service.go, service/thematic_library/thematic_sync_service.go
+ */
+
+package execution
+
+import (
+	"bytes"
+	"context"
+	"datahub-service/logger"
+	"datahub-service/service/tracing"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// 命令类型
+const (
+	CommandKindBasicSync    = "basic_sync"    // 基础库同步任务
+	CommandKindThematicSync = "thematic_sync" // 主题库同步任务
+)
+
+// Command 执行命令
+type Command struct {
+	ID          string                 `json:"id"`
+	Kind        string                 `json:"kind"`
+	TaskID      string                 `json:"task_id"`
+	ExecutionID string                 `json:"execution_id,omitempty"`
+	Payload     map[string]interface{} `json:"payload,omitempty"`
+	CreatedAt   time.Time              `json:"created_at"`
+	// TraceParent 分发时的W3C traceparent，执行面据此延续控制面的链路
+	TraceParent string `json:"traceparent,omitempty"`
+	// RequestID 触发执行的请求ID，执行面的日志和事件据此关联到原始请求
+	RequestID string `json:"request_id,omitempty"`
+}
+
+// NewCommand 创建执行命令
+func NewCommand(kind, taskID string) *Command {
+	return &Command{
+		ID:        uuid.New().String(),
+		Kind:      kind,
+		TaskID:    taskID,
+		CreatedAt: time.Now(),
+	}
+}
+
+// Dispatcher 执行命令分发器接口
+type Dispatcher interface {
+	// Dispatch 分发执行命令，返回时命令已被接收但不保证执行完成
+	Dispatch(ctx context.Context, cmd *Command) error
+	// IsRemote 命令是否在其他进程中执行
+	IsRemote() bool
+}
+
+// LocalDispatcher 本地分发器，在当前进程的goroutine中执行命令
+type LocalDispatcher struct {
+	worker *Worker
+}
+
+// NewLocalDispatcher 创建本地分发器
+func NewLocalDispatcher(worker *Worker) *LocalDispatcher {
+	return &LocalDispatcher{worker: worker}
+}
+
+// Dispatch 异步执行命令
+func (d *LocalDispatcher) Dispatch(ctx context.Context, cmd *Command) error {
+	if !d.worker.HasHandler(cmd.Kind) {
+		return fmt.Errorf("未注册的执行命令类型: %s", cmd.Kind)
+	}
+	if cmd.TraceParent == "" {
+		cmd.TraceParent = tracing.TraceParent(ctx)
+	}
+	if cmd.RequestID == "" {
+		cmd.RequestID = logger.RequestIDFromContext(ctx)
+	}
+
+	go func() {
+		// 使用独立的context，避免HTTP请求context被取消影响任务执行
+		if err := d.worker.Handle(context.Background(), cmd); err != nil {
+			slog.Error("执行命令失败", "command_id", cmd.ID, "kind", cmd.Kind, "task_id", cmd.TaskID, "error", err)
+		}
+	}()
+	return nil
+}
+
+// IsRemote 本地执行
+func (d *LocalDispatcher) IsRemote() bool {
+	return false
+}
+
+// DaprDispatcher Dapr发布订阅分发器，把命令发布到执行队列由worker消费
+type DaprDispatcher struct {
+	baseURL    string
+	pubsubName string
+	topic      string
+	httpClient *http.Client
+}
+
+// NewDaprDispatcher 创建Dapr分发器，读取DAPR_HTTP_PORT、EXECUTION_PUBSUB_NAME、EXECUTION_TOPIC环境变量
+func NewDaprDispatcher() *DaprDispatcher {
+	daprPort := getEnvWithDefault("DAPR_HTTP_PORT", "3500")
+	return &DaprDispatcher{
+		baseURL:    fmt.Sprintf("http://localhost:%s", daprPort),
+		pubsubName: PubsubName(),
+		topic:      Topic(),
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Dispatch 发布命令到Dapr pubsub
+func (d *DaprDispatcher) Dispatch(ctx context.Context, cmd *Command) error {
+	if cmd.TraceParent == "" {
+		cmd.TraceParent = tracing.TraceParent(ctx)
+	}
+	if cmd.RequestID == "" {
+		cmd.RequestID = logger.RequestIDFromContext(ctx)
+	}
+
+	body, err := json.Marshal(cmd)
+	if err != nil {
+		return fmt.Errorf("序列化执行命令失败: %w", err)
+	}
+
+	url := fmt.Sprintf("%s/v1.0/publish/%s/%s", d.baseURL, d.pubsubName, d.topic)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("创建发布请求失败: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	// Dapr把traceparent写入CloudEvent并传给订阅方，使sidecar的Span加入同一链路
+	tracing.Inject(ctx, req.Header)
+
+	resp, err := d.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("发布执行命令失败: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusMultipleChoices {
+		respBody, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("发布执行命令失败，状态码: %d, 响应: %s", resp.StatusCode, string(respBody))
+	}
+
+	slog.Info("执行命令已发布", "command_id", cmd.ID, "kind", cmd.Kind, "task_id", cmd.TaskID, "topic", d.topic)
+	return nil
+}
+
+// IsRemote 由worker进程执行
+func (d *DaprDispatcher) IsRemote() bool {
+	return true
+}
+
+// NewDispatcher 根据部署模式创建分发器：control模式经Dapr发布，其余模式本地执行
+func NewDispatcher(mode string, worker *Worker) Dispatcher {
+	if mode == ModeControl {
+		return NewDaprDispatcher()
+	}
+	return NewLocalDispatcher(worker)
+}
+
+// PubsubName 执行队列使用的Dapr pubsub组件名
+func PubsubName() string {
+	return getEnvWithDefault("EXECUTION_PUBSUB_NAME", "pubsub")
+}
+
+// Topic 执行队列主题
+func Topic() string {
+	return getEnvWithDefault("EXECUTION_TOPIC", "datahub-execution")
+}
+
+// getEnvWithDefault 获取环境变量，如果不存在则返回默认值
+func getEnvWithDefault(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
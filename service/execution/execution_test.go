@@ -0,0 +1,125 @@
+/*
+ * @module service/execution/execution_test
+ * @description 执行面测试，覆盖部署模式解析、本地分发、Dapr发布和订阅处理
+ * @architecture 测试层
+ * @documentReference ai_docs/refactor_sync_task.md
+ * @stateFlow 注册处理器 -> 分发命令 -> 验证处理器收到的命令
+ * @rules 使用httptest模拟Dapr sidecar，不依赖外部服务
+ * @dependencies github.com/stretchr/testify
+ * @refs service/execution/dispatcher.go, service/execution/worker.go
+ */
+
+package execution
+
+import (
+	"context"
+	"datahub-service/logger"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/dapr/go-sdk/service/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseMode(t *testing.T) {
+	assert.Equal(t, ModeStandalone, ParseMode(""))
+	assert.Equal(t, ModeControl, ParseMode(" Control "))
+	assert.Equal(t, ModeWorker, ParseMode("worker"))
+	assert.Equal(t, ModeStandalone, ParseMode("unknown"))
+
+	assert.True(t, RunsControlPlane(ModeStandalone))
+	assert.True(t, RunsExecutionPlane(ModeStandalone))
+	assert.False(t, RunsExecutionPlane(ModeControl))
+	assert.False(t, RunsControlPlane(ModeWorker))
+}
+
+func TestLocalDispatcher(t *testing.T) {
+	worker := NewWorker()
+	received := make(chan *Command, 1)
+	var requestID string
+	worker.RegisterHandler(CommandKindBasicSync, func(ctx context.Context, cmd *Command) error {
+		requestID = logger.RequestIDFromContext(ctx)
+		received <- cmd
+		return nil
+	})
+
+	dispatcher := NewDispatcher(ModeStandalone, worker)
+	assert.False(t, dispatcher.IsRemote())
+
+	cmd := NewCommand(CommandKindBasicSync, "task-1")
+	require.NoError(t, dispatcher.Dispatch(logger.WithRequestID(context.Background(), "req-1"), cmd))
+
+	select {
+	case got := <-received:
+		assert.Equal(t, "task-1", got.TaskID)
+		// 执行面沿用触发请求的请求ID
+		assert.Equal(t, "req-1", got.RequestID)
+		assert.Equal(t, "req-1", requestID)
+	case <-time.After(time.Second):
+		t.Fatal("命令未被执行")
+	}
+
+	err := dispatcher.Dispatch(context.Background(), NewCommand(CommandKindThematicSync, "task-2"))
+	assert.Error(t, err)
+}
+
+func TestDaprDispatcher(t *testing.T) {
+	var published Command
+	var path string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&published))
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	dispatcher := NewDaprDispatcher()
+	dispatcher.baseURL = server.URL
+	assert.True(t, dispatcher.IsRemote())
+
+	cmd := NewCommand(CommandKindThematicSync, "task-1")
+	cmd.ExecutionID = "exec-1"
+	require.NoError(t, dispatcher.Dispatch(context.Background(), cmd))
+
+	assert.Equal(t, "/v1.0/publish/pubsub/datahub-execution", path)
+	assert.Equal(t, cmd.ID, published.ID)
+	assert.Equal(t, "exec-1", published.ExecutionID)
+}
+
+func TestDaprDispatcherError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	dispatcher := NewDaprDispatcher()
+	dispatcher.baseURL = server.URL
+	assert.Error(t, dispatcher.Dispatch(context.Background(), NewCommand(CommandKindBasicSync, "task-1")))
+}
+
+func TestWorkerTopicEventHandler(t *testing.T) {
+	worker := NewWorker()
+	var handled *Command
+	worker.RegisterHandler(CommandKindBasicSync, func(ctx context.Context, cmd *Command) error {
+		handled = cmd
+		return nil
+	})
+
+	raw, err := json.Marshal(NewCommand(CommandKindBasicSync, "task-1"))
+	require.NoError(t, err)
+
+	retry, err := worker.TopicEventHandler(context.Background(), &common.TopicEvent{RawData: raw})
+	require.NoError(t, err)
+	assert.False(t, retry)
+	require.NotNil(t, handled)
+	assert.Equal(t, "task-1", handled.TaskID)
+
+	// 无法解析的消息直接丢弃，不重试
+	retry, err = worker.TopicEventHandler(context.Background(), &common.TopicEvent{RawData: []byte("not-json")})
+	assert.NoError(t, err)
+	assert.False(t, retry)
+}
@@ -0,0 +1,54 @@
+/*
+ * @module service/execution/mode
+ * @description 部署模式定义，决定当前进程承担控制面（API/调度）、执行面（worker）还是两者兼有
+ * @architecture 控制面/执行面分离架构 - 部署配置
+ * @documentReference ai_docs/refactor_sync_task.md
+ * @stateFlow 读取DEPLOY_MODE -> 决定启动的组件 -> 选择本地或Dapr分发器
+ * @rules 默认standalone单体模式，保持与原有部署兼容；未知取值回退为standalone
+ * @dependencies os
+ * @refs service/init.go, main.go
+ */
+
+package execution
+
+import (
+	"log/slog"
+	"os"
+	"strings"
+)
+
+// 部署模式
+const (
+	ModeStandalone = "standalone" // 单体模式：API、调度和执行同进程
+	ModeControl    = "control"    // 控制面：API和调度，执行命令经队列分发给worker
+	ModeWorker     = "worker"     // 执行面：只订阅并执行任务命令
+)
+
+// CurrentMode 读取DEPLOY_MODE环境变量获取当前部署模式
+func CurrentMode() string {
+	return ParseMode(os.Getenv("DEPLOY_MODE"))
+}
+
+// ParseMode 解析部署模式，未配置或未知取值时返回standalone
+func ParseMode(value string) string {
+	mode := strings.ToLower(strings.TrimSpace(value))
+	switch mode {
+	case ModeStandalone, ModeControl, ModeWorker:
+		return mode
+	case "":
+		return ModeStandalone
+	default:
+		slog.Warn("未知的部署模式，使用单体模式", "deploy_mode", value)
+		return ModeStandalone
+	}
+}
+
+// RunsControlPlane 当前模式是否承担控制面（API、调度器）
+func RunsControlPlane(mode string) bool {
+	return mode == ModeStandalone || mode == ModeControl
+}
+
+// RunsExecutionPlane 当前模式是否承担执行面（任务执行、常驻数据源）
+func RunsExecutionPlane(mode string) bool {
+	return mode == ModeStandalone || mode == ModeWorker
+}
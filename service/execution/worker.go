@@ -0,0 +1,97 @@
+/*
+ * @module service/execution/worker
+ * @description 执行面Worker，按命令类型路由到已注册的执行处理器，并提供Dapr订阅处理函数
+ * @architecture 控制面/执行面分离架构 - 命令执行
+ * @documentReference ai_docs/refactor_sync_task.md
+ * @stateFlow 接收命令(本地/Dapr订阅) -> 查找处理器 -> 执行 -> 处理器自行回写任务和执行记录状态
+ * @rules 处理器失败不重投递，避免重复执行同步任务；无法解析或未知类型的命令直接丢弃并记录日志
+ * @dependencies github.com/dapr/go-sdk/service/common
+ * @refs service/execution/dispatcher.go, main.go
+ */
+
+package execution
+
+import (
+	"context"
+	"datahub-service/logger"
+	"datahub-service/service/tracing"
+	"fmt"
+	"log/slog"
+	"sync"
+
+	"github.com/dapr/go-sdk/service/common"
+)
+
+// Handler 执行命令处理器
+type Handler func(ctx context.Context, cmd *Command) error
+
+// Worker 执行命令处理器注册表
+type Worker struct {
+	mu       sync.RWMutex
+	handlers map[string]Handler
+}
+
+// NewWorker 创建Worker
+func NewWorker() *Worker {
+	return &Worker{handlers: make(map[string]Handler)}
+}
+
+// RegisterHandler 注册命令处理器
+func (w *Worker) RegisterHandler(kind string, handler Handler) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.handlers[kind] = handler
+}
+
+// HasHandler 是否已注册指定类型的处理器
+func (w *Worker) HasHandler(kind string) bool {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	_, ok := w.handlers[kind]
+	return ok
+}
+
+// Handle 执行命令
+func (w *Worker) Handle(ctx context.Context, cmd *Command) error {
+	w.mu.RLock()
+	handler, ok := w.handlers[cmd.Kind]
+	w.mu.RUnlock()
+	if !ok {
+		return fmt.Errorf("未注册的执行命令类型: %s", cmd.Kind)
+	}
+
+	ctx = tracing.ContextWithTraceParent(ctx, cmd.TraceParent)
+	ctx = logger.WithRequestID(ctx, cmd.RequestID)
+	ctx, span := tracing.StartKind(ctx, "execution."+cmd.Kind, tracing.SpanKindConsumer)
+	defer span.End()
+	span.SetAttribute("execution.command_id", cmd.ID)
+	span.SetAttribute("execution.task_id", cmd.TaskID)
+
+	slog.InfoContext(ctx, "开始执行命令", "command_id", cmd.ID, "kind", cmd.Kind, "task_id", cmd.TaskID)
+	err := handler(ctx, cmd)
+	span.RecordError(err)
+	return err
+}
+
+// Subscription 执行队列的Dapr订阅配置
+func (w *Worker) Subscription() *common.Subscription {
+	return &common.Subscription{
+		PubsubName: PubsubName(),
+		Topic:      Topic(),
+		Route:      "/internal/execution/commands",
+	}
+}
+
+// TopicEventHandler Dapr订阅处理函数，同步执行命令
+func (w *Worker) TopicEventHandler(ctx context.Context, e *common.TopicEvent) (retry bool, err error) {
+	var cmd Command
+	if err := e.Struct(&cmd); err != nil {
+		slog.Error("解析执行命令失败，丢弃消息", "event_id", e.ID, "error", err)
+		return false, nil
+	}
+
+	if err := w.Handle(ctx, &cmd); err != nil {
+		slog.Error("执行命令失败", "command_id", cmd.ID, "kind", cmd.Kind, "task_id", cmd.TaskID, "error", err)
+	}
+	return false, nil
+}
@@ -0,0 +1,168 @@
+/*
+ * @module service/governance/batch_operation
+ * @description 规则和质量检测任务的批量启用、停用、删除，在单个事务中执行并返回逐项结果
+ * @architecture 分层架构 - 服务层
+ * @documentReference ai_docs/data_governance_task_req.md
+ * @stateFlow 校验动作和ID -> 开启事务 -> 逐项在保存点内执行 -> 全部成功提交 / 任一失败整体回滚
+ * @rules 批量操作要么全部生效要么全部不生效；单项失败回滚到保存点以便继续执行其余项并收集完整结果；启用/停用递增row_version
+ * @dependencies gorm.io/gorm, service/models
+ * @refs governance_service.go, quality_task_service.go, service/models/row_version.go
+ */
+
+package governance
+
+import (
+	"datahub-service/service/models"
+	"errors"
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// 批量操作动作
+const (
+	BatchActionEnable  = "enable"
+	BatchActionDisable = "disable"
+	BatchActionDelete  = "delete"
+)
+
+// MaxBatchSize 单次批量操作的最大数量
+const MaxBatchSize = 200
+
+// errBatchRolledBack 存在失败项，事务整体回滚
+var errBatchRolledBack = errors.New("批量操作存在失败项，已整体回滚")
+
+// BatchOperationRequest 批量操作请求
+type BatchOperationRequest struct {
+	IDs    []string `json:"ids" validate:"required,min=1,max=200" example:"550e8400-e29b-41d4-a716-446655440000"`
+	Action string   `json:"action" validate:"required,oneof=enable disable delete" example:"disable"` // enable/disable/delete
+}
+
+// BatchItemResult 批量操作单项结果
+type BatchItemResult struct {
+	ID      string `json:"id"`
+	Success bool   `json:"success"`
+	Error   string `json:"error,omitempty"`
+}
+
+// BatchOperationResult 批量操作结果
+type BatchOperationResult struct {
+	Action         string            `json:"action"`
+	Committed      bool              `json:"committed"` // 全部成功并已提交；为false时所有项均未生效
+	Total          int               `json:"total"`
+	SucceededCount int               `json:"succeeded_count"`
+	FailedCount    int               `json:"failed_count"`
+	Items          []BatchItemResult `json:"items"`
+}
+
+// BatchOperateQualityRules 批量启用、停用或删除数据质量规则
+func (s *GovernanceService) BatchOperateQualityRules(action string, ids []string) (*BatchOperationResult, error) {
+	return s.runBatch(action, ids, templateBatchHandler(&models.QualityRuleTemplate{}, action))
+}
+
+// BatchOperateMaskingRules 批量启用、停用或删除脱敏规则
+func (s *GovernanceService) BatchOperateMaskingRules(action string, ids []string) (*BatchOperationResult, error) {
+	return s.runBatch(action, ids, templateBatchHandler(&models.DataMaskingTemplate{}, action))
+}
+
+// BatchOperateCleansingRules 批量启用、停用或删除清洗规则
+func (s *GovernanceService) BatchOperateCleansingRules(action string, ids []string) (*BatchOperationResult, error) {
+	return s.runBatch(action, ids, templateBatchHandler(&models.DataCleansingTemplate{}, action))
+}
+
+// BatchOperateQualityTasks 批量启用、停用或删除质量检测任务，正在运行的任务不能删除
+func (s *GovernanceService) BatchOperateQualityTasks(action string, ids []string) (*BatchOperationResult, error) {
+	if action == BatchActionDelete {
+		return s.runBatch(action, ids, deleteQualityTaskTx)
+	}
+	return s.runBatch(action, ids, templateBatchHandler(&models.QualityTask{}, action))
+}
+
+// templateBatchHandler 返回带is_enabled和row_version列对象的单项处理函数
+func templateBatchHandler(model interface{}, action string) func(tx *gorm.DB, id string) error {
+	return func(tx *gorm.DB, id string) error {
+		if action == BatchActionDelete {
+			result := tx.Delete(model, "id = ?", id)
+			if result.Error != nil {
+				return result.Error
+			}
+			if result.RowsAffected == 0 {
+				return gorm.ErrRecordNotFound
+			}
+			return nil
+		}
+
+		if _, err := models.BumpRowVersion(tx, model, id, 0); err != nil {
+			return err
+		}
+		return tx.Model(model).Where("id = ?", id).Update("is_enabled", action == BatchActionEnable).Error
+	}
+}
+
+// runBatch 在单个事务中逐项执行，每项使用保存点隔离失败，存在失败项时整体回滚
+func (s *GovernanceService) runBatch(action string, ids []string, apply func(tx *gorm.DB, id string) error) (*BatchOperationResult, error) {
+	if action != BatchActionEnable && action != BatchActionDisable && action != BatchActionDelete {
+		return nil, fmt.Errorf("不支持的批量操作: %s", action)
+	}
+	ids = uniqueIDs(ids)
+	if len(ids) == 0 {
+		return nil, errors.New("ID列表不能为空")
+	}
+	if len(ids) > MaxBatchSize {
+		return nil, fmt.Errorf("单次批量操作不能超过%d项", MaxBatchSize)
+	}
+
+	result := &BatchOperationResult{
+		Action: action,
+		Total:  len(ids),
+		Items:  make([]BatchItemResult, 0, len(ids)),
+	}
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		for i, id := range ids {
+			savePoint := fmt.Sprintf("batch_item_%d", i)
+			if err := tx.SavePoint(savePoint).Error; err != nil {
+				return err
+			}
+			if err := apply(tx, id); err != nil {
+				if rbErr := tx.RollbackTo(savePoint).Error; rbErr != nil {
+					return rbErr
+				}
+				result.Items = append(result.Items, BatchItemResult{ID: id, Error: err.Error()})
+				result.FailedCount++
+				continue
+			}
+			result.Items = append(result.Items, BatchItemResult{ID: id, Success: true})
+			result.SucceededCount++
+		}
+
+		if result.FailedCount > 0 {
+			return errBatchRolledBack
+		}
+		return nil
+	})
+
+	switch {
+	case err == nil:
+		result.Committed = true
+	case errors.Is(err, errBatchRolledBack):
+		result.Committed = false
+	default:
+		return nil, err
+	}
+	return result, nil
+}
+
+// uniqueIDs 去除空值和重复ID，保持原有顺序
+func uniqueIDs(ids []string) []string {
+	seen := make(map[string]bool, len(ids))
+	unique := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if id == "" || seen[id] {
+			continue
+		}
+		seen[id] = true
+		unique = append(unique, id)
+	}
+	return unique
+}
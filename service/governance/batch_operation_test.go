@@ -0,0 +1,123 @@
+/*
+ * @module service/governance/batch_operation_test
+ * @description 批量操作测试，覆盖批量启用停用、删除、失败整体回滚和逐项结果
+ * @architecture 测试层
+ * @documentReference ai_docs/data_governance_task_req.md
+ * @stateFlow 初始化内存数据库 -> 创建规则和任务 -> 批量操作 -> 验证提交或回滚
+ * @rules 使用SQLite内存数据库，不依赖外部服务
+ * @dependencies github.com/stretchr/testify, gorm.io/driver/sqlite
+ * @refs service/governance/batch_operation.go
+ */
+
+package governance
+
+import (
+	"datahub-service/service/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func setupBatchService(t *testing.T) *GovernanceService {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(
+		&models.QualityRuleTemplate{},
+		&models.QualityTask{},
+		&models.QualityTaskFieldRule{},
+		&models.QualityTaskExecution{},
+		&models.QualityIssueRecord{},
+	))
+	return &GovernanceService{db: db}
+}
+
+func createBatchRules(t *testing.T, s *GovernanceService, count int) []string {
+	ids := make([]string, 0, count)
+	for i := 0; i < count; i++ {
+		rule := &models.QualityRuleTemplate{
+			Name:      "规则",
+			Type:      "completeness",
+			Category:  "basic_quality",
+			RuleLogic: models.JSONB{"type": "not_null"},
+			IsEnabled: true,
+		}
+		require.NoError(t, s.db.Create(rule).Error)
+		ids = append(ids, rule.ID)
+	}
+	return ids
+}
+
+func TestBatchDisableQualityRules(t *testing.T) {
+	s := setupBatchService(t)
+	ids := createBatchRules(t, s, 3)
+
+	// 重复ID只处理一次
+	result, err := s.BatchOperateQualityRules(BatchActionDisable, append(ids, ids[0]))
+	require.NoError(t, err)
+	assert.True(t, result.Committed)
+	assert.Equal(t, 3, result.Total)
+	assert.Equal(t, 3, result.SucceededCount)
+
+	var rules []models.QualityRuleTemplate
+	require.NoError(t, s.db.Find(&rules).Error)
+	for _, rule := range rules {
+		assert.False(t, rule.IsEnabled)
+		assert.Equal(t, int64(2), rule.RowVersion)
+	}
+}
+
+func TestBatchDeleteRollsBackOnFailure(t *testing.T) {
+	s := setupBatchService(t)
+	ids := createBatchRules(t, s, 2)
+
+	result, err := s.BatchOperateQualityRules(BatchActionDelete, []string{ids[0], "missing", ids[1]})
+	require.NoError(t, err)
+	assert.False(t, result.Committed)
+	assert.Equal(t, 2, result.SucceededCount)
+	assert.Equal(t, 1, result.FailedCount)
+	require.Len(t, result.Items, 3)
+	assert.True(t, result.Items[0].Success)
+	assert.False(t, result.Items[1].Success)
+	assert.Equal(t, "missing", result.Items[1].ID)
+	assert.NotEmpty(t, result.Items[1].Error)
+
+	// 整体回滚，规则仍然存在
+	var count int64
+	require.NoError(t, s.db.Model(&models.QualityRuleTemplate{}).Count(&count).Error)
+	assert.Equal(t, int64(2), count)
+}
+
+func TestBatchDeleteQualityTasksRejectsRunning(t *testing.T) {
+	s := setupBatchService(t)
+	idle := &models.QualityTask{Name: "空闲任务", LibraryType: "basic", LibraryID: "lib", InterfaceID: "if1", ScheduleType: "manual", Status: "pending"}
+	running := &models.QualityTask{Name: "运行中任务", LibraryType: "basic", LibraryID: "lib", InterfaceID: "if2", ScheduleType: "manual", Status: "running"}
+	require.NoError(t, s.db.Create(idle).Error)
+	require.NoError(t, s.db.Create(running).Error)
+
+	result, err := s.BatchOperateQualityTasks(BatchActionDelete, []string{idle.ID, running.ID})
+	require.NoError(t, err)
+	assert.False(t, result.Committed)
+	assert.Equal(t, "正在运行的任务不能删除", result.Items[1].Error)
+
+	result, err = s.BatchOperateQualityTasks(BatchActionDelete, []string{idle.ID})
+	require.NoError(t, err)
+	assert.True(t, result.Committed)
+	var count int64
+	require.NoError(t, s.db.Model(&models.QualityTask{}).Count(&count).Error)
+	assert.Equal(t, int64(1), count)
+}
+
+func TestBatchInvalidRequest(t *testing.T) {
+	s := setupBatchService(t)
+
+	_, err := s.BatchOperateQualityRules("archive", []string{"a"})
+	assert.Error(t, err)
+	_, err = s.BatchOperateQualityRules(BatchActionEnable, []string{""})
+	assert.Error(t, err)
+}
@@ -12,7 +12,12 @@
 package governance
 
 import (
+	"context"
+	"datahub-service/service/catalog_cache"
 	"datahub-service/service/models"
+	"datahub-service/service/rbac"
+	"datahub-service/service/secrets"
+	"datahub-service/service/udf"
 	"errors"
 	"fmt"
 	"strings"
@@ -25,6 +30,7 @@ import (
 // GovernanceService 数据治理服务
 type GovernanceService struct {
 	db               *gorm.DB
+	readDB           *gorm.DB // 质量检测扫描和采样使用的只读连接
 	ruleEngine       *RuleEngine
 	templateService  *TemplateService
 	qualityScheduler *QualityScheduler
@@ -34,6 +40,7 @@ type GovernanceService struct {
 func NewGovernanceService(db *gorm.DB) *GovernanceService {
 	service := &GovernanceService{
 		db:              db,
+		readDB:          db,
 		ruleEngine:      NewRuleEngine(db),
 		templateService: NewTemplateService(db),
 	}
@@ -44,6 +51,14 @@ func NewGovernanceService(db *gorm.DB) *GovernanceService {
 	return service
 }
 
+// SetReadDB 设置质量检测扫描目标表使用的只读副本连接，传入nil时使用主库
+func (s *GovernanceService) SetReadDB(readDB *gorm.DB) {
+	if readDB == nil {
+		readDB = s.db
+	}
+	s.readDB = readDB
+}
+
 // GetQualityScheduler 获取质量检测任务调度器
 func (s *GovernanceService) GetQualityScheduler() *QualityScheduler {
 	return s.qualityScheduler
@@ -62,7 +77,7 @@ func (s *GovernanceService) ApplyMaskingRules(data map[string]interface{}, confi
 // === 数据质量规则管理 ===
 
 // CreateQualityRule 创建数据质量规则
-func (s *GovernanceService) CreateQualityRule(rule *models.QualityRuleTemplate) error {
+func (s *GovernanceService) CreateQualityRule(ctx context.Context, rule *models.QualityRuleTemplate) error {
 	// 验证规则类型
 	validTypes := []string{"completeness", "accuracy", "consistency", "validity", "uniqueness", "timeliness", "standardization"}
 	isValidType := false
@@ -89,15 +104,20 @@ func (s *GovernanceService) CreateQualityRule(rule *models.QualityRuleTemplate)
 		return errors.New("无效的数据质量规则分类")
 	}
 
-	return s.db.Create(rule).Error
+	// 规则逻辑引用的自定义函数须存在且已审批
+	if err := udf.ValidateLogic(ctx, rule.RuleLogic, models.UDFKindQuality); err != nil {
+		return err
+	}
+
+	return s.db.WithContext(ctx).Create(rule).Error
 }
 
 // GetQualityRules 获取数据质量规则列表
-func (s *GovernanceService) GetQualityRules(page, pageSize int, ruleType, objectType string) ([]models.QualityRuleTemplate, int64, error) {
+func (s *GovernanceService) GetQualityRules(ctx context.Context, page, pageSize int, ruleType, objectType string) ([]models.QualityRuleTemplate, int64, error) {
 	var rules []models.QualityRuleTemplate
 	var total int64
 
-	query := s.db.Model(&models.QualityRuleTemplate{})
+	query := s.db.WithContext(ctx).Model(&models.QualityRuleTemplate{}).Scopes(rbac.GrantScope(ctx, rbac.ResourceQualityRule, "quality_rule_templates.id"))
 
 	if ruleType != "" {
 		query = query.Where("type = ?", ruleType)
@@ -119,22 +139,26 @@ func (s *GovernanceService) GetQualityRules(page, pageSize int, ruleType, object
 }
 
 // GetQualityRuleByID 根据ID获取数据质量规则
-func (s *GovernanceService) GetQualityRuleByID(id string) (*models.QualityRuleTemplate, error) {
+func (s *GovernanceService) GetQualityRuleByID(ctx context.Context, id string) (*models.QualityRuleTemplate, error) {
 	var rule models.QualityRuleTemplate
-	if err := s.db.First(&rule, "id = ?", id).Error; err != nil {
+	if err := s.db.WithContext(ctx).First(&rule, "id = ?", id).Error; err != nil {
 		return nil, err
 	}
 	return &rule, nil
 }
 
-// UpdateQualityRule 更新数据质量规则
-func (s *GovernanceService) UpdateQualityRule(id string, updates map[string]interface{}) error {
-	return s.db.Model(&models.QualityRuleTemplate{}).Where("id = ?", id).Updates(updates).Error
+// UpdateQualityRule 更新数据质量规则，expectedVersion为0时不校验版本
+func (s *GovernanceService) UpdateQualityRule(ctx context.Context, id string, expectedVersion int64, updates map[string]interface{}) error {
+	delete(updates, "tenant_id")
+	if err := validateLogicUpdate(ctx, updates, "rule_logic", models.UDFKindQuality); err != nil {
+		return err
+	}
+	return models.UpdateWithRowVersion(s.db.WithContext(ctx), &models.QualityRuleTemplate{}, id, expectedVersion, updates)
 }
 
 // DeleteQualityRule 删除数据质量规则
-func (s *GovernanceService) DeleteQualityRule(id string) error {
-	return s.db.Delete(&models.QualityRuleTemplate{}, "id = ?", id).Error
+func (s *GovernanceService) DeleteQualityRule(ctx context.Context, id string) error {
+	return s.db.WithContext(ctx).Delete(&models.QualityRuleTemplate{}, "id = ?", id).Error
 }
 
 // === 元数据管理 ===
@@ -159,6 +183,19 @@ func (s *GovernanceService) CreateMetadata(metadata *models.Metadata) error {
 
 // GetMetadataList 获取元数据列表
 func (s *GovernanceService) GetMetadataList(page, pageSize int, metadataType, name string) ([]models.Metadata, int64, error) {
+	result, err := catalog_cache.Fetch(context.Background(), catalog_cache.Key{
+		Name:   "metadata",
+		Tables: []string{"metadata"},
+		Params: []interface{}{page, pageSize, metadataType, name},
+	}, func() (catalog_cache.Page[models.Metadata], error) {
+		items, total, err := s.loadMetadataList(page, pageSize, metadataType, name)
+		return catalog_cache.Page[models.Metadata]{Items: items, Total: total}, err
+	})
+	return result.Items, result.Total, err
+}
+
+// loadMetadataList 从数据库查询元数据列表
+func (s *GovernanceService) loadMetadataList(page, pageSize int, metadataType, name string) ([]models.Metadata, int64, error) {
 	var metadataList []models.Metadata
 	var total int64
 
@@ -205,7 +242,7 @@ func (s *GovernanceService) DeleteMetadata(id string) error {
 // === 数据脱敏规则管理 ===
 
 // CreateMaskingRule 创建脱敏规则
-func (s *GovernanceService) CreateMaskingRule(rule *models.DataMaskingTemplate) error {
+func (s *GovernanceService) CreateMaskingRule(ctx context.Context, rule *models.DataMaskingTemplate) error {
 	// 验证脱敏类型
 	validTypes := []string{"mask", "replace", "encrypt", "pseudonymize"}
 	isValidType := false
@@ -219,15 +256,15 @@ func (s *GovernanceService) CreateMaskingRule(rule *models.DataMaskingTemplate)
 		return errors.New("无效的数据脱敏类型")
 	}
 
-	return s.db.Create(rule).Error
+	return s.db.WithContext(ctx).Create(rule).Error
 }
 
 // GetMaskingRules 获取脱敏规则列表
-func (s *GovernanceService) GetMaskingRules(page, pageSize int, dataSource, maskingType string) ([]models.DataMaskingTemplate, int64, error) {
+func (s *GovernanceService) GetMaskingRules(ctx context.Context, page, pageSize int, dataSource, maskingType string) ([]models.DataMaskingTemplate, int64, error) {
 	var rules []models.DataMaskingTemplate
 	var total int64
 
-	query := s.db.Model(&models.DataMaskingTemplate{})
+	query := s.db.WithContext(ctx).Model(&models.DataMaskingTemplate{}).Scopes(rbac.GrantScope(ctx, rbac.ResourceMaskingRule, "data_masking_templates.id"))
 
 	if dataSource != "" {
 		// 这里可以根据数据源进行过滤，暂时忽略
@@ -249,22 +286,23 @@ func (s *GovernanceService) GetMaskingRules(page, pageSize int, dataSource, mask
 }
 
 // GetMaskingRuleByID 根据ID获取脱敏规则
-func (s *GovernanceService) GetMaskingRuleByID(id string) (*models.DataMaskingTemplate, error) {
+func (s *GovernanceService) GetMaskingRuleByID(ctx context.Context, id string) (*models.DataMaskingTemplate, error) {
 	var rule models.DataMaskingTemplate
-	if err := s.db.First(&rule, "id = ?", id).Error; err != nil {
+	if err := s.db.WithContext(ctx).First(&rule, "id = ?", id).Error; err != nil {
 		return nil, err
 	}
 	return &rule, nil
 }
 
-// UpdateMaskingRule 更新脱敏规则
-func (s *GovernanceService) UpdateMaskingRule(id string, updates map[string]interface{}) error {
-	return s.db.Model(&models.DataMaskingTemplate{}).Where("id = ?", id).Updates(updates).Error
+// UpdateMaskingRule 更新脱敏规则，expectedVersion为0时不校验版本
+func (s *GovernanceService) UpdateMaskingRule(ctx context.Context, id string, expectedVersion int64, updates map[string]interface{}) error {
+	delete(updates, "tenant_id")
+	return models.UpdateWithRowVersion(s.db.WithContext(ctx), &models.DataMaskingTemplate{}, id, expectedVersion, updates)
 }
 
 // DeleteMaskingRule 删除脱敏规则
-func (s *GovernanceService) DeleteMaskingRule(id string) error {
-	return s.db.Delete(&models.DataMaskingTemplate{}, "id = ?", id).Error
+func (s *GovernanceService) DeleteMaskingRule(ctx context.Context, id string) error {
+	return s.db.WithContext(ctx).Delete(&models.DataMaskingTemplate{}, "id = ?", id).Error
 }
 
 // === 系统日志管理 ===
@@ -336,6 +374,11 @@ func (s *GovernanceService) CreateBackupConfig(config *models.BackupConfig) erro
 		return errors.New("无效的备份对象类型")
 	}
 
+	// 存储位置和策略中的凭据可以是密钥引用
+	if err := secrets.ValidateConfig(map[string]interface{}{"storage_location": config.StorageLocation, "strategy": config.Strategy}); err != nil {
+		return err
+	}
+
 	return s.db.Create(config).Error
 }
 
@@ -367,8 +410,8 @@ func (s *GovernanceService) CreateBackupRecord(record *models.BackupRecord) erro
 	return s.db.Create(record).Error
 }
 
-// GetBackupRecords 获取备份记录列表
-func (s *GovernanceService) GetBackupRecords(page, pageSize int, configID, status string) ([]models.BackupRecord, int64, error) {
+// GetBackupRecords 获取备份记录列表，verificationStatus为unverified时筛选未校验的记录
+func (s *GovernanceService) GetBackupRecords(page, pageSize int, configID, status, verificationStatus string) ([]models.BackupRecord, int64, error) {
 	var records []models.BackupRecord
 	var total int64
 
@@ -380,6 +423,13 @@ func (s *GovernanceService) GetBackupRecords(page, pageSize int, configID, statu
 	if status != "" {
 		query = query.Where("status = ?", status)
 	}
+	switch verificationStatus {
+	case "":
+	case "unverified":
+		query = query.Where("verification_status IS NULL OR verification_status = ''")
+	default:
+		query = query.Where("verification_status = ?", verificationStatus)
+	}
 
 	if err := query.Count(&total).Error; err != nil {
 		return nil, 0, err
@@ -474,7 +524,7 @@ func (s *GovernanceService) RunQualityCheck(objectID, objectType string) (*model
 // === 数据清洗规则管理 ===
 
 // CreateCleansingRule 创建清洗规则
-func (s *GovernanceService) CreateCleansingRule(rule *models.DataCleansingTemplate) error {
+func (s *GovernanceService) CreateCleansingRule(ctx context.Context, rule *models.DataCleansingTemplate) error {
 	// 验证清洗规则类型
 	validTypes := []string{"standardization", "deduplication", "validation", "transformation", "enrichment"}
 	isValidType := false
@@ -488,15 +538,20 @@ func (s *GovernanceService) CreateCleansingRule(rule *models.DataCleansingTempla
 		return errors.New("无效的数据清洗规则类型")
 	}
 
-	return s.db.Create(rule).Error
+	// 清洗逻辑引用的自定义函数须存在且已审批
+	if err := udf.ValidateLogic(ctx, rule.CleansingLogic, models.UDFKindCleansing); err != nil {
+		return err
+	}
+
+	return s.db.WithContext(ctx).Create(rule).Error
 }
 
 // GetCleansingRules 获取清洗规则列表
-func (s *GovernanceService) GetCleansingRules(page, pageSize int, ruleType, targetTable string) ([]models.DataCleansingTemplate, int64, error) {
+func (s *GovernanceService) GetCleansingRules(ctx context.Context, page, pageSize int, ruleType, targetTable string) ([]models.DataCleansingTemplate, int64, error) {
 	var rules []models.DataCleansingTemplate
 	var total int64
 
-	query := s.db.Model(&models.DataCleansingTemplate{})
+	query := s.db.WithContext(ctx).Model(&models.DataCleansingTemplate{}).Scopes(rbac.GrantScope(ctx, rbac.ResourceCleansingRule, "data_cleansing_templates.id"))
 
 	if ruleType != "" {
 		query = query.Where("rule_type = ?", ruleType)
@@ -518,22 +573,26 @@ func (s *GovernanceService) GetCleansingRules(page, pageSize int, ruleType, targ
 }
 
 // GetCleansingRuleByID 根据ID获取清洗规则
-func (s *GovernanceService) GetCleansingRuleByID(id string) (*models.DataCleansingTemplate, error) {
+func (s *GovernanceService) GetCleansingRuleByID(ctx context.Context, id string) (*models.DataCleansingTemplate, error) {
 	var rule models.DataCleansingTemplate
-	if err := s.db.First(&rule, "id = ?", id).Error; err != nil {
+	if err := s.db.WithContext(ctx).First(&rule, "id = ?", id).Error; err != nil {
 		return nil, err
 	}
 	return &rule, nil
 }
 
-// UpdateCleansingRule 更新清洗规则
-func (s *GovernanceService) UpdateCleansingRule(id string, updates map[string]interface{}) error {
-	return s.db.Model(&models.DataCleansingTemplate{}).Where("id = ?", id).Updates(updates).Error
+// UpdateCleansingRule 更新清洗规则，expectedVersion为0时不校验版本
+func (s *GovernanceService) UpdateCleansingRule(ctx context.Context, id string, expectedVersion int64, updates map[string]interface{}) error {
+	delete(updates, "tenant_id")
+	if err := validateLogicUpdate(ctx, updates, "cleansing_logic", models.UDFKindCleansing); err != nil {
+		return err
+	}
+	return models.UpdateWithRowVersion(s.db.WithContext(ctx), &models.DataCleansingTemplate{}, id, expectedVersion, updates)
 }
 
 // DeleteCleansingRule 删除清洗规则
-func (s *GovernanceService) DeleteCleansingRule(id string) error {
-	return s.db.Delete(&models.DataCleansingTemplate{}, "id = ?", id).Error
+func (s *GovernanceService) DeleteCleansingRule(ctx context.Context, id string) error {
+	return s.db.WithContext(ctx).Delete(&models.DataCleansingTemplate{}, "id = ?", id).Error
 }
 
 // === 数据血缘管理 ===
@@ -718,12 +777,12 @@ func createTestSummary(qualityChecks, maskingRules, cleansingRules int, overallS
 }
 
 // TestQualityRule 测试数据质量规则
-func (s *GovernanceService) TestQualityRule(req *TestQualityRuleRequest) (*TestRuleResponse, error) {
+func (s *GovernanceService) TestQualityRule(ctx context.Context, req *TestQualityRuleRequest) (*TestRuleResponse, error) {
 	startTime := time.Now()
 	testID := uuid.New().String()
 
 	// 获取规则模板
-	template, err := s.GetQualityRuleByID(req.RuleTemplateID)
+	template, err := s.GetQualityRuleByID(ctx, req.RuleTemplateID)
 	if err != nil {
 		return nil, fmt.Errorf("获取质量规则模板失败: %v", err)
 	}
@@ -795,12 +854,12 @@ func (s *GovernanceService) TestQualityRule(req *TestQualityRuleRequest) (*TestR
 }
 
 // TestMaskingRule 测试数据脱敏规则
-func (s *GovernanceService) TestMaskingRule(req *TestMaskingRuleRequest) (*TestRuleResponse, error) {
+func (s *GovernanceService) TestMaskingRule(ctx context.Context, req *TestMaskingRuleRequest) (*TestRuleResponse, error) {
 	startTime := time.Now()
 	testID := uuid.New().String()
 
 	// 获取脱敏模板
-	template, err := s.GetMaskingRuleByID(req.TemplateID)
+	template, err := s.GetMaskingRuleByID(ctx, req.TemplateID)
 	if err != nil {
 		return nil, fmt.Errorf("获取脱敏规则模板失败: %v", err)
 	}
@@ -871,12 +930,12 @@ func (s *GovernanceService) TestMaskingRule(req *TestMaskingRuleRequest) (*TestR
 }
 
 // TestCleansingRule 测试数据清洗规则
-func (s *GovernanceService) TestCleansingRule(req *TestCleansingRuleRequest) (*TestRuleResponse, error) {
+func (s *GovernanceService) TestCleansingRule(ctx context.Context, req *TestCleansingRuleRequest) (*TestRuleResponse, error) {
 	startTime := time.Now()
 	testID := uuid.New().String()
 
 	// 获取清洗模板
-	template, err := s.GetCleansingRuleByID(req.TemplateID)
+	template, err := s.GetCleansingRuleByID(ctx, req.TemplateID)
 	if err != nil {
 		return nil, fmt.Errorf("获取清洗规则模板失败: %v", err)
 	}
@@ -948,7 +1007,7 @@ func (s *GovernanceService) TestCleansingRule(req *TestCleansingRuleRequest) (*T
 }
 
 // TestBatchRules 批量测试多个规则
-func (s *GovernanceService) TestBatchRules(req *TestBatchRulesRequest) (*TestRuleResponse, error) {
+func (s *GovernanceService) TestBatchRules(ctx context.Context, req *TestBatchRulesRequest) (*TestRuleResponse, error) {
 	startTime := time.Now()
 	testID := uuid.New().String()
 
@@ -971,7 +1030,7 @@ func (s *GovernanceService) TestBatchRules(req *TestBatchRulesRequest) (*TestRul
 				qualityChecks++
 				totalRules++
 
-				template, err := s.GetQualityRuleByID(qRule.RuleTemplateID)
+				template, err := s.GetQualityRuleByID(ctx, qRule.RuleTemplateID)
 				if err != nil {
 					results = append(results, RuleTestResult{
 						RuleType:       "quality",
@@ -1037,7 +1096,7 @@ func (s *GovernanceService) TestBatchRules(req *TestBatchRulesRequest) (*TestRul
 				cleansingRules++
 				totalRules++
 
-				template, err := s.GetCleansingRuleByID(cRule.TemplateID)
+				template, err := s.GetCleansingRuleByID(ctx, cRule.TemplateID)
 				if err != nil {
 					results = append(results, RuleTestResult{
 						RuleType:       "cleansing",
@@ -1103,7 +1162,7 @@ func (s *GovernanceService) TestBatchRules(req *TestBatchRulesRequest) (*TestRul
 				maskingRules++
 				totalRules++
 
-				template, err := s.GetMaskingRuleByID(mRule.TemplateID)
+				template, err := s.GetMaskingRuleByID(ctx, mRule.TemplateID)
 				if err != nil {
 					results = append(results, RuleTestResult{
 						RuleType:       "masking",
@@ -1195,7 +1254,7 @@ func (s *GovernanceService) TestBatchRules(req *TestBatchRulesRequest) (*TestRul
 }
 
 // TestRulePreview 预览规则执行效果（不实际执行）
-func (s *GovernanceService) TestRulePreview(req *TestRulePreviewRequest) (*TestRulePreviewResponse, error) {
+func (s *GovernanceService) TestRulePreview(ctx context.Context, req *TestRulePreviewRequest) (*TestRulePreviewResponse, error) {
 	var templateName string
 	var expectedChanges []string
 	configValidation := struct {
@@ -1215,7 +1274,7 @@ func (s *GovernanceService) TestRulePreview(req *TestRulePreviewRequest) (*TestR
 
 	switch req.RuleType {
 	case "quality":
-		template, err := s.GetQualityRuleByID(req.TemplateID)
+		template, err := s.GetQualityRuleByID(ctx, req.TemplateID)
 		if err != nil {
 			return nil, fmt.Errorf("获取质量规则模板失败: %v", err)
 		}
@@ -1241,7 +1300,7 @@ func (s *GovernanceService) TestRulePreview(req *TestRulePreviewRequest) (*TestR
 		}
 
 	case "masking":
-		template, err := s.GetMaskingRuleByID(req.TemplateID)
+		template, err := s.GetMaskingRuleByID(ctx, req.TemplateID)
 		if err != nil {
 			return nil, fmt.Errorf("获取脱敏规则模板失败: %v", err)
 		}
@@ -1263,7 +1322,7 @@ func (s *GovernanceService) TestRulePreview(req *TestRulePreviewRequest) (*TestR
 		}
 
 	case "cleansing":
-		template, err := s.GetCleansingRuleByID(req.TemplateID)
+		template, err := s.GetCleansingRuleByID(ctx, req.TemplateID)
 		if err != nil {
 			return nil, fmt.Errorf("获取清洗规则模板失败: %v", err)
 		}
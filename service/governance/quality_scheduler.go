@@ -13,7 +13,9 @@ package governance
 
 import (
 	"context"
+	"datahub-service/service/chaos"
 	"datahub-service/service/distributed_lock"
+	"datahub-service/service/metrics"
 	"datahub-service/service/models"
 	"fmt"
 	"log/slog"
@@ -258,6 +260,13 @@ func (qs *QualityScheduler) checkIntervalTasks() {
 // executeScheduledTask 执行调度任务（带分布式锁）
 func (qs *QualityScheduler) executeScheduledTask(taskID string) {
 	slog.Info("执行质量检测调度任务", "task_id", taskID)
+	defer metrics.TrackScheduledTask(metrics.SchedulerQuality)()
+
+	// 故障注入：模拟调度延迟
+	if err := chaos.Inject(qs.ctx, models.FaultTypeScheduleDelay, taskID); err != nil {
+		slog.Warn("调度延迟注入被中断，跳过本次执行", "task_id", taskID, "error", err)
+		return
+	}
 
 	// 如果有分布式锁，使用锁保护执行
 	if qs.distributedLock != nil {
@@ -0,0 +1,102 @@
+/*
+ * @module service/governance/quality_task_operation
+ * @description 质量检测任务启动操作的执行方，按执行记录刷新长时操作的状态和结果链接，取消时把运行中的执行记为已取消，执行过程定期检查并提前结束
+ * @architecture 分层架构 - 业务服务层
+ * @documentReference ai_docs/data_governance_task_req.md
+ * @stateFlow 启动创建执行记录 -> running -> completed/completed_with_issues/failed/cancelled映射为操作状态；取消：running -> cancelled -> 执行过程检查到后停止扫描
+ * @rules 取消只作用于运行中的执行；取消状态写入数据库，执行所在实例每扫描qualityCancelCheckInterval行检查一次，多实例部署时同样生效；已取消的执行不再回写结果
+ * @dependencies gorm.io/gorm, datahub-service/service/models
+ * @refs service/operation/service.go, quality_task_service.go
+ */
+
+package governance
+
+import (
+	"context"
+	"datahub-service/service/models"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// qualityCancelCheckInterval 质量检测每扫描多少行检查一次是否已取消
+const qualityCancelCheckInterval = 1000
+
+// qualityExecutionCancelled 质量检测执行的取消状态
+const qualityExecutionCancelled = "cancelled"
+
+// ErrQualityExecutionFinished 质量检测执行已结束
+var ErrQualityExecutionFinished = errors.New("质量检测执行已结束，无法取消")
+
+// RefreshOperation 按执行记录刷新质量检测任务启动操作
+func (s *GovernanceService) RefreshOperation(ctx context.Context, op *models.Operation) error {
+	var execution models.QualityTaskExecution
+	if err := s.db.WithContext(ctx).First(&execution, "id = ?", op.ExecutionID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			op.Status = models.OperationStatusFailed
+			op.Error = "质量检测执行记录不存在"
+			return nil
+		}
+		return fmt.Errorf("获取质量检测执行记录失败: %w", err)
+	}
+
+	op.ResultLink = "/data-quality/tasks/" + execution.TaskID + "/executions"
+	switch execution.Status {
+	case "running":
+		op.Status = models.OperationStatusRunning
+	case "completed", "completed_with_issues":
+		op.Status = models.OperationStatusSucceeded
+		op.Message = fmt.Sprintf("检测完成，评分%.2f，问题%d条", execution.OverallScore, execution.IssueCount)
+	case "failed":
+		op.Status = models.OperationStatusFailed
+		op.Error = execution.ErrorMessage
+	case qualityExecutionCancelled:
+		op.Status = models.OperationStatusCanceled
+	}
+	return nil
+}
+
+// CancelOperation 取消质量检测任务启动操作
+func (s *GovernanceService) CancelOperation(ctx context.Context, op *models.Operation) error {
+	return s.CancelQualityTaskExecution(ctx, op.ExecutionID)
+}
+
+// CancelQualityTaskExecution 取消运行中的质量检测执行，任务状态同步改为已取消
+func (s *GovernanceService) CancelQualityTaskExecution(ctx context.Context, executionID string) error {
+	var execution models.QualityTaskExecution
+	if err := s.db.WithContext(ctx).First(&execution, "id = ?", executionID).Error; err != nil {
+		return err
+	}
+
+	endTime := time.Now()
+	result := s.db.WithContext(ctx).Model(&models.QualityTaskExecution{}).
+		Where("id = ? AND status = ?", executionID, "running").
+		Updates(map[string]interface{}{
+			"status":        qualityExecutionCancelled,
+			"end_time":      &endTime,
+			"duration":      endTime.Sub(execution.StartTime).Milliseconds(),
+			"error_message": "执行已取消",
+		})
+	if result.Error != nil {
+		return fmt.Errorf("取消质量检测执行失败: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrQualityExecutionFinished
+	}
+
+	return s.db.WithContext(ctx).Model(&models.QualityTask{}).
+		Where("id = ? AND status = ?", execution.TaskID, "running").
+		Update("status", qualityExecutionCancelled).Error
+}
+
+// isQualityExecutionCancelled 执行是否已被取消
+func (s *GovernanceService) isQualityExecutionCancelled(executionID string) bool {
+	var status string
+	if err := s.db.Model(&models.QualityTaskExecution{}).Where("id = ?", executionID).
+		Select("status").Scan(&status).Error; err != nil {
+		return false
+	}
+	return status == qualityExecutionCancelled
+}
@@ -5,13 +5,16 @@
  * @documentReference ai_docs/data_governance_task_req.md
  * @stateFlow 任务创建 -> 任务激活 -> 调度执行 -> 结果记录
  * @rules 每个任务针对单个表，支持字段级规则配置
- * @dependencies gorm.io/gorm, service/models, github.com/robfig/cron/v3
+ * @dependencies gorm.io/gorm, service/models, service/eventlog, github.com/robfig/cron/v3
  * @refs quality_scheduler.go, governance_service.go
  */
 
 package governance
 
 import (
+	"context"
+	"datahub-service/service/eventlog"
+	"datahub-service/service/metrics"
 	"datahub-service/service/models"
 	"errors"
 	"fmt"
@@ -285,6 +288,7 @@ func (s *GovernanceService) buildTaskResponse(task *models.QualityTask) (*Qualit
 		CreatedBy:          task.CreatedBy,
 		UpdatedAt:          task.UpdatedAt,
 		UpdatedBy:          task.UpdatedBy,
+		RowVersion:         task.RowVersion,
 	}, nil
 }
 
@@ -380,6 +384,48 @@ func (s *GovernanceService) StartQualityTask(id string) (*QualityTaskExecutionRe
 	}, nil
 }
 
+// QualityTaskRunResult 同步执行一次质量检测的结果
+type QualityTaskRunResult struct {
+	TaskID       string           `json:"task_id"`
+	ExecutionID  string           `json:"execution_id"`
+	Status       string           `json:"status"`        // completed, completed_with_issues, failed
+	OverallScore float64          `json:"overall_score"` // 0-1
+	TotalChecks  int              `json:"total_checks"`
+	FailedChecks int              `json:"failed_checks"`
+	FailedByRule map[string]int64 `json:"failed_by_rule"` // 字段规则ID -> 不通过次数
+	ErrorMessage string           `json:"error_message,omitempty"`
+}
+
+// RunQualityTaskNow 同步执行一次质量检测并返回结果，供同步任务的质量门禁在写入后调用；
+// 不检查任务是否正在运行，本次执行单独记录
+func (s *GovernanceService) RunQualityTaskNow(ctx context.Context, taskID, triggerSource string) (*QualityTaskRunResult, error) {
+	execution := &models.QualityTaskExecution{
+		TaskID:        taskID,
+		ExecutionType: "triggered",
+		StartTime:     time.Now(),
+		Status:        "running",
+		TriggerSource: triggerSource,
+	}
+	if err := s.db.WithContext(ctx).Create(execution).Error; err != nil {
+		return nil, err
+	}
+
+	failedByRule := s.executeQualityTask(execution)
+	if err := s.db.WithContext(ctx).First(execution, "id = ?", execution.ID).Error; err != nil {
+		return nil, err
+	}
+	return &QualityTaskRunResult{
+		TaskID:       taskID,
+		ExecutionID:  execution.ID,
+		Status:       execution.Status,
+		OverallScore: execution.OverallScore,
+		TotalChecks:  execution.TotalRulesExecuted,
+		FailedChecks: execution.FailedRules,
+		FailedByRule: failedByRule,
+		ErrorMessage: execution.ErrorMessage,
+	}, nil
+}
+
 // StopQualityTask 停止质量检测任务
 func (s *GovernanceService) StopQualityTask(id string) error {
 	return s.db.Model(&models.QualityTask{}).Where("id = ?", id).Updates(map[string]interface{}{
@@ -402,6 +448,11 @@ func (s *GovernanceService) UpdateQualityTask(id string, req *UpdateQualityTaskR
 
 	// 使用事务更新
 	return s.db.Transaction(func(tx *gorm.DB) error {
+		// 校验并递增版本，防止并发修改互相覆盖
+		if _, err := models.BumpRowVersion(tx, &models.QualityTask{}, id, req.RowVersion); err != nil {
+			return err
+		}
+
 		// 构建任务更新数据
 		updates := make(map[string]interface{})
 
@@ -520,9 +571,17 @@ func (s *GovernanceService) UpdateQualityTask(id string, req *UpdateQualityTaskR
 
 // DeleteQualityTask 删除质量检测任务
 func (s *GovernanceService) DeleteQualityTask(id string) error {
+	// 使用事务删除任务和相关数据
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		return deleteQualityTaskTx(tx, id)
+	})
+}
+
+// deleteQualityTaskTx 在事务中删除质量检测任务及其问题记录、执行记录和字段规则
+func deleteQualityTaskTx(tx *gorm.DB, id string) error {
 	// 检查任务是否存在
 	var task models.QualityTask
-	if err := s.db.First(&task, "id = ?", id).Error; err != nil {
+	if err := tx.First(&task, "id = ?", id).Error; err != nil {
 		return err
 	}
 
@@ -531,30 +590,27 @@ func (s *GovernanceService) DeleteQualityTask(id string) error {
 		return errors.New("正在运行的任务不能删除")
 	}
 
-	// 使用事务删除任务和相关数据
-	return s.db.Transaction(func(tx *gorm.DB) error {
-		// 删除问题记录
-		if err := tx.Delete(&models.QualityIssueRecord{}, "task_id = ?", id).Error; err != nil {
-			return fmt.Errorf("删除问题记录失败: %w", err)
-		}
+	// 删除问题记录
+	if err := tx.Delete(&models.QualityIssueRecord{}, "task_id = ?", id).Error; err != nil {
+		return fmt.Errorf("删除问题记录失败: %w", err)
+	}
 
-		// 删除执行记录
-		if err := tx.Delete(&models.QualityTaskExecution{}, "task_id = ?", id).Error; err != nil {
-			return fmt.Errorf("删除执行记录失败: %w", err)
-		}
+	// 删除执行记录
+	if err := tx.Delete(&models.QualityTaskExecution{}, "task_id = ?", id).Error; err != nil {
+		return fmt.Errorf("删除执行记录失败: %w", err)
+	}
 
-		// 删除字段规则
-		if err := tx.Delete(&models.QualityTaskFieldRule{}, "task_id = ?", id).Error; err != nil {
-			return fmt.Errorf("删除字段规则失败: %w", err)
-		}
+	// 删除字段规则
+	if err := tx.Delete(&models.QualityTaskFieldRule{}, "task_id = ?", id).Error; err != nil {
+		return fmt.Errorf("删除字段规则失败: %w", err)
+	}
 
-		// 删除任务
-		if err := tx.Delete(&models.QualityTask{}, "id = ?", id).Error; err != nil {
-			return fmt.Errorf("删除任务失败: %w", err)
-		}
+	// 删除任务
+	if err := tx.Delete(&models.QualityTask{}, "id = ?", id).Error; err != nil {
+		return fmt.Errorf("删除任务失败: %w", err)
+	}
 
-		return nil
-	})
+	return nil
 }
 
 // GetQualityTaskExecutions 获取质量检测任务执行记录
@@ -593,13 +649,13 @@ func (s *GovernanceService) GetQualityTaskExecutions(taskID string, page, pageSi
 	return responses, total, nil
 }
 
-// executeQualityTask 执行质量检测任务（实际实现版本）
-func (s *GovernanceService) executeQualityTask(execution *models.QualityTaskExecution) {
+// executeQualityTask 执行质量检测任务（实际实现版本），返回各字段规则的不通过次数，执行失败时为nil
+func (s *GovernanceService) executeQualityTask(execution *models.QualityTaskExecution) map[string]int64 {
 	// 获取任务详情
 	var task models.QualityTask
 	if err := s.db.First(&task, "id = ?", execution.TaskID).Error; err != nil {
 		s.finishExecution(execution.ID, "failed", 0, 0, 0, 0, 0, fmt.Sprintf("获取任务失败: %v", err))
-		return
+		return nil
 	}
 
 	// 获取字段规则
@@ -607,12 +663,12 @@ func (s *GovernanceService) executeQualityTask(execution *models.QualityTaskExec
 	if err := s.db.Where("task_id = ? AND is_enabled = ?", task.ID, true).
 		Order("priority DESC").Find(&fieldRules).Error; err != nil {
 		s.finishExecution(execution.ID, "failed", 0, 0, 0, 0, 0, fmt.Sprintf("获取字段规则失败: %v", err))
-		return
+		return nil
 	}
 
 	if len(fieldRules) == 0 {
 		s.finishExecution(execution.ID, "completed", 0, 0, 0, 1.0, 0, "没有启用的规则")
-		return
+		return map[string]int64{}
 	}
 
 	// 获取目标表的主键字段列表（用于构建记录标识）
@@ -631,11 +687,11 @@ func (s *GovernanceService) executeQualityTask(execution *models.QualityTaskExec
 	// 构建查询SQL：SELECT * FROM schema.table
 	tableName := fmt.Sprintf("%s.%s", task.TargetSchema, task.TargetTable)
 
-	// 查询目标表的所有数据
-	rows, err := s.db.Table(tableName).Rows()
+	// 查询目标表的所有数据，全表扫描走只读副本
+	rows, err := s.readDB.Table(tableName).Rows()
 	if err != nil {
 		s.finishExecution(execution.ID, "failed", 0, 0, 0, 0, 0, fmt.Sprintf("查询目标表失败: %v", err))
-		return
+		return nil
 	}
 	defer rows.Close()
 
@@ -643,7 +699,7 @@ func (s *GovernanceService) executeQualityTask(execution *models.QualityTaskExec
 	columnTypes, err := rows.ColumnTypes()
 	if err != nil {
 		s.finishExecution(execution.ID, "failed", 0, 0, 0, 0, 0, fmt.Sprintf("获取列信息失败: %v", err))
-		return
+		return nil
 	}
 
 	// 创建列名到索引的映射
@@ -663,12 +719,19 @@ func (s *GovernanceService) executeQualityTask(execution *models.QualityTaskExec
 	// 统计变量
 	var totalChecks, passedChecks, failedChecks int64
 	var issueCount int64
+	failedByRule := make(map[string]int64)
 
 	// 遍历每一行数据
 	rowNum := 0
 	for rows.Next() {
 		rowNum++
 
+		// 执行被取消时停止扫描，已记录的问题数据保留，执行记录保持已取消状态
+		if rowNum%qualityCancelCheckInterval == 0 && s.isQualityExecutionCancelled(execution.ID) {
+			slog.Info("质量检测执行已取消，停止扫描", "execution_id", execution.ID, "task_id", task.ID, "scanned_rows", rowNum)
+			return nil
+		}
+
 		// 创建值容器
 		values := make([]interface{}, len(columnTypes))
 		valuePtrs := make([]interface{}, len(columnTypes))
@@ -702,6 +765,7 @@ func (s *GovernanceService) executeQualityTask(execution *models.QualityTaskExec
 			} else {
 				failedChecks++
 				issueCount++
+				failedByRule[fieldRule.ID]++
 
 				// 记录问题数据
 				s.recordIssue(execution.ID, task.ID, &fieldRule, recordID, fieldValue, issueDesc)
@@ -724,6 +788,7 @@ func (s *GovernanceService) executeQualityTask(execution *models.QualityTaskExec
 	}
 
 	s.finishExecution(execution.ID, status, totalChecks, passedChecks, failedChecks, overallScore, issueCount, "")
+	return failedByRule
 }
 
 // checkFieldRule 检查字段规则
@@ -1228,7 +1293,11 @@ func (s *GovernanceService) finishExecution(executionID, status string, totalRul
 		updates["error_message"] = errorMessage
 	}
 
-	s.db.Model(&models.QualityTaskExecution{}).Where("id = ?", executionID).Updates(updates)
+	// 执行已被取消时保留取消状态，不再回写结果
+	if result := s.db.Model(&models.QualityTaskExecution{}).Where("id = ? AND status <> ?", executionID, qualityExecutionCancelled).
+		Updates(updates); result.Error == nil && result.RowsAffected == 0 {
+		return
+	}
 
 	// 更新任务状态
 	taskUpdates := map[string]interface{}{
@@ -1244,6 +1313,40 @@ func (s *GovernanceService) finishExecution(executionID, status string, totalRul
 	}
 
 	s.db.Model(&models.QualityTask{}).Where("id = ?", execution.TaskID).Updates(taskUpdates)
+
+	// 只有执行完成时才更新接口质量得分
+	var task models.QualityTask
+	if status == "completed" || status == "completed_with_issues" {
+		s.db.Select("library_type", "interface_id").First(&task, "id = ?", execution.TaskID)
+	}
+	metrics.ObserveQualityExecution(task.LibraryType, task.InterfaceID, status, overallScore)
+	recordQualityFinished(execution.TaskID, executionID, status, overallScore, issueCount, errorMessage)
+}
+
+// recordQualityFinished 记录质量检测结束事件，发现问题记为警告，执行失败记为错误
+func recordQualityFinished(taskID, executionID, status string, overallScore float64, issueCount int64, errorMessage string) {
+	event := eventlog.Event{
+		Type:       eventlog.EventQualityTaskCompleted,
+		Message:    "质量检测任务执行完成",
+		ObjectType: "quality_task",
+		ObjectID:   taskID,
+		Attributes: map[string]interface{}{"execution_id": executionID, "status": status, "overall_score": overallScore, "issue_count": issueCount},
+	}
+	switch status {
+	case "completed":
+	case "completed_with_issues":
+		event.Type = eventlog.EventQualityIssuesFound
+		event.Level = models.EventLevelWarn
+		event.Message = "质量检测发现数据问题"
+	default:
+		event.Type = eventlog.EventQualityTaskFailed
+		event.Level = models.EventLevelError
+		event.Message = "质量检测任务执行失败"
+	}
+	if errorMessage != "" {
+		event.Attributes["error"] = errorMessage
+	}
+	eventlog.Record(context.Background(), event)
 }
 
 // === 调度和执行相关方法 ===
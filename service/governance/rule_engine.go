@@ -5,14 +5,16 @@
  * @documentReference ai_docs/data_governance_req.md
  * @stateFlow 规则加载 -> 数据处理 -> 结果返回
  * @rules 确保数据治理规则的正确应用和执行
- * @dependencies datahub-service/service/models, gorm.io/gorm
- * @refs ai_docs/data_governance_example.md
+ * @dependencies datahub-service/service/models, datahub-service/service/udf, gorm.io/gorm
+ * @refs ai_docs/data_governance_example.md, service/udf/registry.go
  */
 
 package governance
 
 import (
+	"context"
 	"datahub-service/service/models"
+	"datahub-service/service/udf"
 	"fmt"
 	"regexp"
 	"strconv"
@@ -295,6 +297,13 @@ func (re *RuleEngine) executeQualityRule(template *models.QualityRuleTemplate, f
 		mergedConfig[k] = v
 	}
 
+	// 逻辑引用自定义函数时由函数检查，不再按规则类型检查
+	if ref, err := udf.ParseReference(mergedConfig); err != nil {
+		return false, fmt.Sprintf("字段 %s 的自定义函数引用无效: %v", fieldName, err)
+	} else if ref != nil {
+		return re.checkWithUDF(ref, fieldName, fieldValue)
+	}
+
 	switch template.Type {
 	case "completeness":
 		return re.checkCompletenessWithConfig(fieldName, fieldValue, mergedConfig, threshold)
@@ -364,6 +373,13 @@ func (re *RuleEngine) executeCleansingRule(template *models.DataCleansingTemplat
 		mergedConfig[k] = v
 	}
 
+	// 逻辑引用自定义函数时由函数清洗，不再按规则类型清洗
+	if ref, err := udf.ParseReference(mergedConfig); err != nil {
+		return fieldValue, err
+	} else if ref != nil {
+		return udf.Call(context.Background(), ref, models.UDFKindCleansing, fieldValue)
+	}
+
 	switch template.RuleType {
 	case "standardization":
 		return re.standardizeValue(fieldValue, mergedConfig)
@@ -380,6 +396,18 @@ func (re *RuleEngine) executeCleansingRule(template *models.DataCleansingTemplat
 	}
 }
 
+// 使用自定义函数检查，函数返回false或执行失败都视为未通过
+func (re *RuleEngine) checkWithUDF(ref *udf.Reference, fieldName string, fieldValue interface{}) (bool, string) {
+	result, err := udf.Call(context.Background(), ref, models.UDFKindQuality, fieldValue)
+	if err != nil {
+		return false, fmt.Sprintf("字段 %s 执行自定义函数 %s 失败: %v", fieldName, ref.Name, err)
+	}
+	if passed, _ := result.(bool); !passed {
+		return false, fmt.Sprintf("字段 %s 未通过自定义函数 %s 检查", fieldName, ref.Name)
+	}
+	return true, ""
+}
+
 // 完整性检查
 func (re *RuleEngine) checkCompleteness(fieldName string, fieldValue interface{}, threshold map[string]interface{}) (bool, string) {
 	if fieldValue == nil {
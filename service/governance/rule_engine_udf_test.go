@@ -0,0 +1,98 @@
+/*
+ * @module service/governance/rule_engine_udf_test
+ * @description 规则引擎调用自定义函数的测试，覆盖质量规则和清洗规则的逻辑引用已审批的自定义函数，以及引用无效时的处理
+ * @architecture 测试层
+ * @documentReference ai_docs/data_governance_req.md
+ * @stateFlow 登记并审批自定义函数 -> 规则模板逻辑引用函数 -> 规则引擎应用规则 -> 验证检查结果和清洗结果
+ * @rules 使用SQLite内存数据库，不依赖外部服务
+ * @dependencies github.com/stretchr/testify, gorm.io/driver/sqlite
+ * @refs service/governance/rule_engine.go, service/udf/registry.go
+ */
+
+package governance
+
+import (
+	"context"
+	"datahub-service/service/models"
+	"datahub-service/service/udf"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// approveUDF 登记并审批一个自定义函数
+func approveUDF(t *testing.T, s *udf.Service, req *udf.UDFRequest) {
+	ctx := context.Background()
+	detail, err := s.CreateUDF(ctx, req, "admin1")
+	require.NoError(t, err)
+	_, _, err = s.SubmitVersion(ctx, detail.ID, 1, "admin1")
+	require.NoError(t, err)
+	_, err = s.ApproveVersion(ctx, detail.ID, 1, "admin2", "")
+	require.NoError(t, err)
+}
+
+func TestRuleEngineWithUDF(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&models.UDFDefinition{}, &models.UDFVersion{}, &models.QualityRuleTemplate{}))
+	registry := udf.NewService(db)
+	udf.SetDefault(registry)
+	defer udf.SetDefault(nil)
+
+	approveUDF(t, registry, &udf.UDFRequest{
+		Name: "in_range", DisplayName: "数值范围", Kind: models.UDFKindQuality,
+		Version: udf.VersionRequest{
+			Expression: "value >= low && value <= high",
+			InputType:  models.UDFTypeNumber,
+			Params:     []models.UDFParam{{Name: "low", Type: models.UDFTypeNumber, Required: true}, {Name: "high", Type: models.UDFTypeNumber, Required: true}},
+			ReturnType: models.UDFTypeBoolean,
+			Examples:   []models.UDFExample{{Value: 5, Args: map[string]interface{}{"low": 1, "high": 10}, Expected: true}},
+		},
+	})
+	approveUDF(t, registry, &udf.UDFRequest{
+		Name: "upper_code", DisplayName: "编码转大写", Kind: models.UDFKindCleansing,
+		Version: udf.VersionRequest{
+			Expression: "upper(trim(value))",
+			ReturnType: models.UDFTypeString,
+			Examples:   []models.UDFExample{{Value: " ab ", Expected: "AB"}},
+		},
+	})
+
+	engine := NewRuleEngine(db)
+	rangeRule := &models.QualityRuleTemplate{
+		Type:      "validity",
+		RuleLogic: models.JSONB{"udf": "in_range", "args": map[string]interface{}{"low": 0, "high": 100}},
+	}
+	result, err := engine.ApplyQualityRulesWithTemplates(
+		map[string]interface{}{"score": 85, "age": 130},
+		[]models.QualityRuleConfig{{RuleTemplateID: "r1", TargetFields: []string{"score", "age"}, IsEnabled: true}},
+		map[string]*models.QualityRuleTemplate{"r1": rangeRule},
+	)
+	require.NoError(t, err)
+	assert.Equal(t, 0.5, result.QualityScore)
+	require.Len(t, result.Issues, 1)
+	assert.Contains(t, result.Issues[0], "字段 age 未通过自定义函数 in_range 检查")
+
+	// 运行时配置覆盖模板的参数
+	passed, _ := engine.executeQualityRule(rangeRule, "age", 130, map[string]interface{}{"args": map[string]interface{}{"low": 0, "high": 150}}, nil)
+	assert.True(t, passed)
+
+	// 引用无效时检查不通过
+	passed, issue := engine.executeQualityRule(&models.QualityRuleTemplate{Type: "validity", RuleLogic: models.JSONB{"udf": "upper_code"}}, "age", 130, nil, nil)
+	assert.False(t, passed)
+	assert.Contains(t, issue, "执行自定义函数 upper_code 失败")
+
+	cleaned, err := engine.ApplyCleansingRulesWithTemplates(
+		map[string]interface{}{"code": " sh01 "},
+		[]models.DataCleansingConfig{{TemplateID: "c1", TargetFields: []string{"code"}, IsEnabled: true}},
+		map[string]*models.DataCleansingTemplate{"c1": {RuleType: "transformation", CleansingLogic: models.JSONB{"udf": "upper_code"}}},
+	)
+	require.NoError(t, err)
+	assert.Equal(t, "SH01", cleaned.ProcessedData["code"])
+}
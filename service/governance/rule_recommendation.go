@@ -0,0 +1,820 @@
+/*
+ * @module service/governance/rule_recommendation
+ * @description 基于字段画像的轻量级规则推荐引擎，推荐质量规则与清洗模板及参数，支持按接口画像、按批次批量采纳和采纳率追踪
+ * @architecture 分层架构 - 服务层
+ * @documentReference ai_docs/data_governance_req.md
+ * @stateFlow 采样数据 -> 字段画像 -> 启发式匹配内置模板 -> 保存推荐(pending) -> 采纳(applied)/拒绝(rejected)
+ * @rules 推荐仅匹配启用的模板；同一表重新生成推荐时，旧的待处理推荐标记为superseded，不计入采纳率；
+ *        按接口画像时接口表字段配置中的主键必推荐完整性和唯一性检查；按批次采纳且未指定任务时可为接口自动创建手动执行的质量检测任务
+ * @dependencies gorm.io/gorm, github.com/google/uuid
+ * @refs service/governance/template_service.go, service/governance/quality_task_service.go
+ */
+
+package governance
+
+import (
+	"datahub-service/service/models"
+	"datahub-service/service/utils"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// 推荐类型
+const (
+	RecommendationTypeQuality   = "quality"
+	RecommendationTypeCleansing = "cleansing"
+)
+
+// 推荐状态
+const (
+	RecommendationStatusPending    = "pending"
+	RecommendationStatusApplied    = "applied"
+	RecommendationStatusRejected   = "rejected"
+	RecommendationStatusSuperseded = "superseded"
+)
+
+// 推荐依赖的内置模板名称，与 template_service.go 中的内置模板保持一致
+const (
+	templateCompleteness    = "字段完整性检查模板"
+	templateUniqueness      = "字段唯一性检查"
+	templateEmailAccuracy   = "邮箱格式准确性检查"
+	templatePhoneValidity   = "手机号有效性检查"
+	templateTimeliness      = "数据及时性检查"
+	templateStandardization = "数据格式标准化检查"
+	templateEmailCleansing  = "邮箱标准化清洗"
+	templateFormatFix       = "数据格式验证修正"
+	templateDateTransform   = "日期格式转换"
+	templateAddressEnrich   = "地址信息丰富"
+)
+
+const (
+	defaultProfileSampleSize = 1000
+	maxProfileSampleSize     = 10000
+	patternMatchThreshold    = 0.6 // 取值模式命中率达到该比例才认为字段属于该模式
+)
+
+var (
+	profileEmailPattern  = regexp.MustCompile(`^[\w.+-]+@[\w.-]+\.[a-zA-Z]{2,}$`)
+	profilePhonePattern  = regexp.MustCompile(`^(\+?86)?1[3-9]\d{9}$`)
+	profileNumberPattern = regexp.MustCompile(`^-?\d+(\.\d+)?$`)
+	profileDatePatterns  = map[string]*regexp.Regexp{
+		"yyyy-MM-dd": regexp.MustCompile(`^\d{4}-\d{1,2}-\d{1,2}`),
+		"yyyy/MM/dd": regexp.MustCompile(`^\d{4}/\d{1,2}/\d{1,2}`),
+		"dd-MM-yyyy": regexp.MustCompile(`^\d{1,2}-\d{1,2}-\d{4}`),
+		"yyyyMMdd":   regexp.MustCompile(`^(19|20)\d{2}(0[1-9]|1[0-2])(0[1-9]|[12]\d|3[01])$`),
+	}
+)
+
+// recommendationTarget 推荐的目标表，按接口或质量检测任务生成时记录所属接口
+type recommendationTarget struct {
+	schema      string
+	table       string
+	libraryType string
+	libraryID   string
+	interfaceID string
+	primaryKeys map[string]bool // 接口表字段配置中的主键
+}
+
+// GenerateRuleRecommendations 对目标表采样并生成字段画像与规则推荐
+func (s *GovernanceService) GenerateRuleRecommendations(req *GenerateRecommendationsRequest, operator string) (*GenerateRecommendationsResponse, error) {
+	target := &recommendationTarget{schema: req.TargetSchema, table: req.TargetTable}
+	if req.QualityTaskID != "" {
+		var task models.QualityTask
+		if err := s.db.First(&task, "id = ?", req.QualityTaskID).Error; err != nil {
+			return nil, fmt.Errorf("质量检测任务不存在: %w", err)
+		}
+		target = &recommendationTarget{schema: task.TargetSchema, table: task.TargetTable,
+			libraryType: task.LibraryType, libraryID: task.LibraryID, interfaceID: task.InterfaceID}
+	} else if req.InterfaceID != "" {
+		resolved, err := s.resolveInterfaceTarget(req.InterfaceID, req.LibraryType)
+		if err != nil {
+			return nil, err
+		}
+		target = resolved
+	}
+	schema, table := target.schema, target.table
+	if schema == "" || table == "" {
+		return nil, errors.New("目标schema和表名不能为空")
+	}
+
+	sampleSize := req.SampleSize
+	if sampleSize <= 0 {
+		sampleSize = defaultProfileSampleSize
+	}
+	if sampleSize > maxProfileSampleSize {
+		sampleSize = maxProfileSampleSize
+	}
+
+	columnTypes, columnOrder, err := s.getColumnTypes(schema, table)
+	if err != nil {
+		return nil, err
+	}
+	if len(columnOrder) == 0 {
+		return nil, fmt.Errorf("表 %s.%s 不存在或没有字段", schema, table)
+	}
+
+	var rows []map[string]interface{}
+	sampleSQL := fmt.Sprintf("SELECT * FROM %s.%s LIMIT %d", utils.QuoteIdent(schema), utils.QuoteIdent(table), sampleSize)
+	if err := s.readDB.Raw(sampleSQL).Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("采样数据失败: %w", err)
+	}
+
+	profiles := ProfileFields(rows, columnOrder, columnTypes)
+	for i := range profiles {
+		profiles[i].IsPrimaryKey = target.primaryKeys[profiles[i].FieldName]
+	}
+
+	var qualityTemplates []models.QualityRuleTemplate
+	if err := s.db.Where("is_enabled = ?", true).Find(&qualityTemplates).Error; err != nil {
+		return nil, fmt.Errorf("查询质量规则模板失败: %w", err)
+	}
+	var cleansingTemplates []models.DataCleansingTemplate
+	if err := s.db.Where("is_enabled = ?", true).Find(&cleansingTemplates).Error; err != nil {
+		return nil, fmt.Errorf("查询清洗规则模板失败: %w", err)
+	}
+
+	batchID := uuid.New().String()
+	recommendations := RecommendRules(profiles, qualityTemplates, cleansingTemplates)
+	for i := range recommendations {
+		recommendations[i].BatchID = batchID
+		recommendations[i].TargetSchema = schema
+		recommendations[i].TargetTable = table
+		recommendations[i].LibraryType = target.libraryType
+		recommendations[i].LibraryID = target.libraryID
+		recommendations[i].InterfaceID = target.interfaceID
+		recommendations[i].CreatedBy = operator
+	}
+
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		// 旧批次中未处理的推荐被新批次取代
+		if err := tx.Model(&models.RuleRecommendation{}).
+			Where("target_schema = ? AND target_table = ? AND status = ?", schema, table, RecommendationStatusPending).
+			Update("status", RecommendationStatusSuperseded).Error; err != nil {
+			return err
+		}
+		if len(recommendations) == 0 {
+			return nil
+		}
+		return tx.Create(&recommendations).Error
+	})
+	if err != nil {
+		return nil, fmt.Errorf("保存规则推荐失败: %w", err)
+	}
+
+	return &GenerateRecommendationsResponse{
+		BatchID:         batchID,
+		TargetSchema:    schema,
+		TargetTable:     table,
+		InterfaceID:     target.interfaceID,
+		Profiles:        profiles,
+		Recommendations: recommendations,
+	}, nil
+}
+
+// resolveInterfaceTarget 获取接口的接口表及表字段配置中的主键
+func (s *GovernanceService) resolveInterfaceTarget(interfaceID, libraryType string) (*recommendationTarget, error) {
+	target := &recommendationTarget{libraryType: libraryType, interfaceID: interfaceID}
+	var fieldsConfig models.JSONB
+	var tableCreated bool
+	switch libraryType {
+	case "", "basic":
+		var iface models.DataInterface
+		if err := s.db.Preload("BasicLibrary").First(&iface, "id = ?", interfaceID).Error; err != nil {
+			return nil, fmt.Errorf("接口不存在: %w", err)
+		}
+		target.libraryType = "basic"
+		target.libraryID, target.schema, target.table = iface.LibraryID, iface.BasicLibrary.GetSchemaName(), iface.NameEn
+		fieldsConfig, tableCreated = iface.TableFieldsConfig, iface.IsTableCreated
+	case "thematic":
+		var iface models.ThematicInterface
+		if err := s.db.Preload("ThematicLibrary").First(&iface, "id = ?", interfaceID).Error; err != nil {
+			return nil, fmt.Errorf("接口不存在: %w", err)
+		}
+		if iface.StorageEngine == models.StorageEngineClickHouse {
+			return nil, errors.New("接口表存储在ClickHouse，暂不支持字段画像")
+		}
+		target.libraryID, target.schema, target.table = iface.LibraryID, iface.ThematicLibrary.GetSchemaName(), iface.NameEn
+		fieldsConfig, tableCreated = iface.TableFieldsConfig, iface.IsTableCreated
+	default:
+		return nil, fmt.Errorf("不支持的库类型: %s", libraryType)
+	}
+	if !tableCreated {
+		return nil, errors.New("接口表尚未创建，无法生成字段画像")
+	}
+
+	target.primaryKeys = make(map[string]bool)
+	for _, item := range fieldsConfig {
+		field, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if name, _ := field["name_en"].(string); name != "" && field["is_primary_key"] == true {
+			target.primaryKeys[name] = true
+		}
+	}
+	return target, nil
+}
+
+// getColumnTypes 获取表字段及其数据类型
+func (s *GovernanceService) getColumnTypes(schema, table string) (map[string]string, []string, error) {
+	var columns []struct {
+		ColumnName string
+		DataType   string
+	}
+	err := s.db.Raw(`SELECT column_name, data_type FROM information_schema.columns
+		WHERE table_schema = ? AND table_name = ? ORDER BY ordinal_position`, schema, table).Scan(&columns).Error
+	if err != nil {
+		return nil, nil, fmt.Errorf("获取表字段失败: %w", err)
+	}
+
+	types := make(map[string]string, len(columns))
+	order := make([]string, 0, len(columns))
+	for _, col := range columns {
+		types[col.ColumnName] = col.DataType
+		order = append(order, col.ColumnName)
+	}
+	return types, order, nil
+}
+
+// GetRuleRecommendations 分页获取规则推荐
+func (s *GovernanceService) GetRuleRecommendations(page, pageSize int, schema, table, status, recommendationType string) ([]models.RuleRecommendation, int64, error) {
+	var list []models.RuleRecommendation
+	var total int64
+
+	query := s.db.Model(&models.RuleRecommendation{})
+	if schema != "" {
+		query = query.Where("target_schema = ?", schema)
+	}
+	if table != "" {
+		query = query.Where("target_table = ?", table)
+	}
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if recommendationType != "" {
+		query = query.Where("recommendation_type = ?", recommendationType)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * pageSize
+	err := query.Order("created_at DESC, confidence DESC").Offset(offset).Limit(pageSize).Find(&list).Error
+	return list, total, err
+}
+
+// ApplyRuleRecommendations 批量采纳推荐：质量规则写入质量检测任务，清洗规则写入主题同步任务；
+// 按批次采纳时采纳批次中置信度达标的全部待处理推荐
+func (s *GovernanceService) ApplyRuleRecommendations(req *ApplyRecommendationsRequest, operator string) (*ApplyRecommendationsResponse, error) {
+	if len(req.RecommendationIDs) == 0 && req.BatchID == "" {
+		return nil, errors.New("推荐ID列表和批次ID不能同时为空")
+	}
+
+	result := &ApplyRecommendationsResponse{Skipped: []SkippedRecommendation{}}
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var recommendations []models.RuleRecommendation
+		query := tx.Where("id IN ?", req.RecommendationIDs)
+		if len(req.RecommendationIDs) == 0 {
+			query = tx.Where("batch_id = ? AND status = ? AND confidence >= ?", req.BatchID, RecommendationStatusPending, req.MinConfidence)
+		}
+		if err := query.Order("confidence DESC").Find(&recommendations).Error; err != nil {
+			return err
+		}
+
+		var qualityTask *models.QualityTask
+		if req.QualityTaskID != "" {
+			qualityTask = &models.QualityTask{}
+			if err := tx.First(qualityTask, "id = ?", req.QualityTaskID).Error; err != nil {
+				return fmt.Errorf("质量检测任务不存在: %w", err)
+			}
+		}
+		var syncTask *models.ThematicSyncTask
+		if req.ThematicSyncTaskID != "" {
+			syncTask = &models.ThematicSyncTask{}
+			if err := tx.First(syncTask, "id = ?", req.ThematicSyncTaskID).Error; err != nil {
+				return fmt.Errorf("主题同步任务不存在: %w", err)
+			}
+		}
+		if qualityTask == nil && syncTask == nil && req.CreateQualityTask {
+			task, err := createRecommendedQualityTask(tx, recommendations, operator)
+			if err != nil {
+				return err
+			}
+			if task != nil {
+				qualityTask = task
+				result.QualityTaskID = task.ID
+			}
+		}
+
+		found := make(map[string]bool, len(recommendations))
+		syncTaskChanged := false
+		now := time.Now()
+
+		for _, rec := range recommendations {
+			found[rec.ID] = true
+			if rec.Status != RecommendationStatusPending {
+				result.Skipped = append(result.Skipped, SkippedRecommendation{ID: rec.ID, Reason: "推荐已处理: " + rec.Status})
+				continue
+			}
+
+			var targetType, targetID string
+			switch {
+			case rec.RecommendationType == RecommendationTypeQuality && qualityTask != nil:
+				fieldRule := &models.QualityTaskFieldRule{
+					TaskID:         qualityTask.ID,
+					FieldName:      rec.FieldName,
+					RuleTemplateID: rec.TemplateID,
+					RuntimeConfig:  jsonbValue(rec.SuggestedConfig["runtime_config"]),
+					Threshold:      jsonbValue(rec.SuggestedConfig["threshold"]),
+					IsEnabled:      true,
+					Priority:       50,
+				}
+				if err := tx.Create(fieldRule).Error; err != nil {
+					return fmt.Errorf("创建字段规则失败: %w", err)
+				}
+				targetType, targetID = "quality_task", qualityTask.ID
+			case rec.RecommendationType == RecommendationTypeQuality && syncTask != nil:
+				syncTask.QualityRuleConfigs = append(syncTask.QualityRuleConfigs, models.QualityRuleConfig{
+					RuleTemplateID: rec.TemplateID,
+					TargetFields:   []string{rec.FieldName},
+					RuntimeConfig:  jsonbValue(rec.SuggestedConfig["runtime_config"]),
+					Threshold:      jsonbValue(rec.SuggestedConfig["threshold"]),
+					IsEnabled:      true,
+				})
+				syncTaskChanged = true
+				targetType, targetID = "thematic_sync_task", syncTask.ID
+			case rec.RecommendationType == RecommendationTypeCleansing && syncTask != nil:
+				syncTask.CleansingRuleConfigs = append(syncTask.CleansingRuleConfigs, models.DataCleansingConfig{
+					TemplateID:      rec.TemplateID,
+					TargetFields:    []string{rec.FieldName},
+					CleansingConfig: jsonbValue(rec.SuggestedConfig["cleansing_config"]),
+					IsEnabled:       true,
+				})
+				syncTaskChanged = true
+				targetType, targetID = "thematic_sync_task", syncTask.ID
+			default:
+				reason := "未指定质量检测任务或主题同步任务"
+				if rec.RecommendationType == RecommendationTypeCleansing {
+					reason = "清洗规则需要指定主题同步任务"
+				}
+				result.Skipped = append(result.Skipped, SkippedRecommendation{ID: rec.ID, Reason: reason})
+				continue
+			}
+
+			if err := tx.Model(&models.RuleRecommendation{}).Where("id = ?", rec.ID).Updates(map[string]interface{}{
+				"status":              RecommendationStatusApplied,
+				"applied_target_type": targetType,
+				"applied_target_id":   targetID,
+				"handled_by":          operator,
+				"handled_at":          now,
+			}).Error; err != nil {
+				return err
+			}
+			result.AppliedCount++
+		}
+
+		for _, id := range req.RecommendationIDs {
+			if !found[id] {
+				result.Skipped = append(result.Skipped, SkippedRecommendation{ID: id, Reason: "推荐不存在"})
+			}
+		}
+
+		if syncTaskChanged {
+			if err := tx.Model(syncTask).Updates(map[string]interface{}{
+				"quality_rule_configs":   syncTask.QualityRuleConfigs,
+				"cleansing_rule_configs": syncTask.CleansingRuleConfigs,
+				"updated_by":             operator,
+			}).Error; err != nil {
+				return fmt.Errorf("更新主题同步任务规则配置失败: %w", err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// createRecommendedQualityTask 为推荐所属接口创建手动执行的质量检测任务，没有待处理的质量规则推荐时返回nil
+func createRecommendedQualityTask(tx *gorm.DB, recommendations []models.RuleRecommendation, operator string) (*models.QualityTask, error) {
+	var source *models.RuleRecommendation
+	for i := range recommendations {
+		rec := &recommendations[i]
+		if rec.Status != RecommendationStatusPending || rec.RecommendationType != RecommendationTypeQuality {
+			continue
+		}
+		if rec.InterfaceID == "" {
+			return nil, errors.New("推荐不是按接口生成的，无法自动创建质量检测任务，请指定质量检测任务")
+		}
+		if source == nil {
+			source = rec
+		} else if rec.InterfaceID != source.InterfaceID {
+			return nil, errors.New("推荐属于多个接口，无法自动创建质量检测任务，请按批次采纳")
+		}
+	}
+	if source == nil {
+		return nil, nil
+	}
+
+	task := &models.QualityTask{
+		Name:         fmt.Sprintf("%s.%s 质量检测", source.TargetSchema, source.TargetTable),
+		Description:  "采纳规则推荐时自动创建",
+		LibraryType:  source.LibraryType,
+		LibraryID:    source.LibraryID,
+		InterfaceID:  source.InterfaceID,
+		TargetSchema: source.TargetSchema,
+		TargetTable:  source.TargetTable,
+		ScheduleType: "manual",
+		CreatedBy:    operator,
+		UpdatedBy:    operator,
+	}
+	if err := tx.Create(task).Error; err != nil {
+		return nil, fmt.Errorf("创建质量检测任务失败: %w", err)
+	}
+	return task, nil
+}
+
+// RejectRuleRecommendations 批量拒绝推荐，返回实际拒绝的数量
+func (s *GovernanceService) RejectRuleRecommendations(ids []string, operator string) (int64, error) {
+	if len(ids) == 0 {
+		return 0, errors.New("推荐ID列表不能为空")
+	}
+	result := s.db.Model(&models.RuleRecommendation{}).
+		Where("id IN ? AND status = ?", ids, RecommendationStatusPending).
+		Updates(map[string]interface{}{
+			"status":     RecommendationStatusRejected,
+			"handled_by": operator,
+			"handled_at": time.Now(),
+		})
+	return result.RowsAffected, result.Error
+}
+
+// GetRecommendationAdoptionStats 统计推荐采纳率，可按表过滤
+func (s *GovernanceService) GetRecommendationAdoptionStats(schema, table string) (*RecommendationAdoptionStats, error) {
+	var rows []struct {
+		TemplateID   string
+		TemplateName string
+		Status       string
+		Count        int64
+	}
+
+	query := s.db.Model(&models.RuleRecommendation{}).
+		Select("template_id, template_name, status, COUNT(*) AS count").
+		Where("status <> ?", RecommendationStatusSuperseded)
+	if schema != "" {
+		query = query.Where("target_schema = ?", schema)
+	}
+	if table != "" {
+		query = query.Where("target_table = ?", table)
+	}
+	if err := query.Group("template_id, template_name, status").Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("统计推荐采纳率失败: %w", err)
+	}
+
+	stats := &RecommendationAdoptionStats{ByTemplate: []TemplateAdoptionStats{}}
+	byTemplate := make(map[string]*TemplateAdoptionStats)
+	for _, row := range rows {
+		item, ok := byTemplate[row.TemplateID]
+		if !ok {
+			item = &TemplateAdoptionStats{TemplateID: row.TemplateID, TemplateName: row.TemplateName}
+			byTemplate[row.TemplateID] = item
+		}
+		item.Total += row.Count
+		stats.Total += row.Count
+		switch row.Status {
+		case RecommendationStatusApplied:
+			item.Applied += row.Count
+			stats.Applied += row.Count
+		case RecommendationStatusRejected:
+			item.Rejected += row.Count
+			stats.Rejected += row.Count
+		case RecommendationStatusPending:
+			stats.Pending += row.Count
+		}
+	}
+
+	stats.AdoptionRate = ratio(stats.Applied, stats.Total)
+	for _, item := range byTemplate {
+		item.AdoptionRate = ratio(item.Applied, item.Total)
+		stats.ByTemplate = append(stats.ByTemplate, *item)
+	}
+	sort.Slice(stats.ByTemplate, func(i, j int) bool {
+		return stats.ByTemplate[i].Total > stats.ByTemplate[j].Total
+	})
+	return stats, nil
+}
+
+// ProfileFields 根据采样数据计算字段画像
+func ProfileFields(rows []map[string]interface{}, columns []string, columnTypes map[string]string) []FieldProfile {
+	profiles := make([]FieldProfile, 0, len(columns))
+	for _, column := range columns {
+		profile := FieldProfile{
+			FieldName:   column,
+			DataType:    columnTypes[column],
+			SampleCount: len(rows),
+			MinLength:   -1,
+		}
+
+		distinct := make(map[string]struct{})
+		dateFormats := make(map[string]struct{})
+		var emailHits, phoneHits, dateHits, numericHits, whitespaceHits, uppercaseHits int
+
+		for _, row := range rows {
+			value, ok := row[column]
+			if !ok || value == nil {
+				profile.NullCount++
+				continue
+			}
+			str := profileString(value)
+			trimmed := strings.TrimSpace(str)
+			if trimmed == "" {
+				profile.NullCount++
+				continue
+			}
+
+			distinct[str] = struct{}{}
+			length := len([]rune(str))
+			if profile.MinLength < 0 || length < profile.MinLength {
+				profile.MinLength = length
+			}
+			if length > profile.MaxLength {
+				profile.MaxLength = length
+			}
+
+			if trimmed != str {
+				whitespaceHits++
+			}
+			if strings.ToLower(str) != str {
+				uppercaseHits++
+			}
+			if profileEmailPattern.MatchString(trimmed) {
+				emailHits++
+			}
+			if profilePhonePattern.MatchString(trimmed) {
+				phoneHits++
+			}
+			if profileNumberPattern.MatchString(trimmed) {
+				numericHits++
+			}
+			if _, isTime := value.(time.Time); isTime {
+				dateHits++
+				dateFormats["timestamp"] = struct{}{}
+			} else {
+				for format, pattern := range profileDatePatterns {
+					if pattern.MatchString(trimmed) {
+						dateHits++
+						dateFormats[format] = struct{}{}
+						break
+					}
+				}
+			}
+		}
+
+		nonNull := profile.SampleCount - profile.NullCount
+		if profile.MinLength < 0 {
+			profile.MinLength = 0
+		}
+		profile.NullRate = ratio(int64(profile.NullCount), int64(profile.SampleCount))
+		profile.DistinctCount = len(distinct)
+		profile.DistinctRate = ratio(int64(len(distinct)), int64(nonNull))
+		profile.EmailRate = ratio(int64(emailHits), int64(nonNull))
+		profile.PhoneRate = ratio(int64(phoneHits), int64(nonNull))
+		profile.DateRate = ratio(int64(dateHits), int64(nonNull))
+		profile.DateFormats = len(dateFormats)
+		profile.NumericRate = ratio(int64(numericHits), int64(nonNull))
+		profile.WhitespaceRate = ratio(int64(whitespaceHits), int64(nonNull))
+		profile.UppercaseRate = ratio(int64(uppercaseHits), int64(nonNull))
+
+		profiles = append(profiles, profile)
+	}
+	return profiles
+}
+
+// RecommendRules 根据字段画像匹配内置模板生成推荐（未保存）
+func RecommendRules(profiles []FieldProfile, qualityTemplates []models.QualityRuleTemplate, cleansingTemplates []models.DataCleansingTemplate) []models.RuleRecommendation {
+	qualityByName := make(map[string]models.QualityRuleTemplate, len(qualityTemplates))
+	for _, t := range qualityTemplates {
+		qualityByName[t.Name] = t
+	}
+	cleansingByName := make(map[string]models.DataCleansingTemplate, len(cleansingTemplates))
+	for _, t := range cleansingTemplates {
+		cleansingByName[t.Name] = t
+	}
+
+	var recommendations []models.RuleRecommendation
+	addQuality := func(p FieldProfile, name, reason string, confidence float64, runtimeConfig, threshold map[string]interface{}) {
+		t, ok := qualityByName[name]
+		if !ok {
+			return
+		}
+		recommendations = append(recommendations, newRecommendation(p, RecommendationTypeQuality, t.ID, t.Name, reason, confidence, models.JSONB{
+			"runtime_config": runtimeConfig,
+			"threshold":      threshold,
+		}))
+	}
+	addCleansing := func(p FieldProfile, name, reason string, confidence float64, cleansingConfig map[string]interface{}) {
+		t, ok := cleansingByName[name]
+		if !ok {
+			return
+		}
+		config := map[string]interface{}{}
+		for k, v := range t.DefaultConfig {
+			config[k] = v
+		}
+		for k, v := range cleansingConfig {
+			config[k] = v
+		}
+		recommendations = append(recommendations, newRecommendation(p, RecommendationTypeCleansing, t.ID, t.Name, reason, confidence, models.JSONB{
+			"cleansing_config": config,
+		}))
+	}
+
+	for _, p := range profiles {
+		if p.SampleCount == 0 {
+			continue
+		}
+		nonNull := p.SampleCount - p.NullCount
+		isText := isTextType(p.DataType)
+		isTime := strings.Contains(p.DataType, "timestamp") || p.DataType == "date"
+
+		// 完整性：空值率很低的字段大概率是必填字段
+		if p.IsPrimaryKey {
+			addQuality(p, templateCompleteness, "接口表主键字段，不允许为空", 1,
+				map[string]interface{}{"check_nullable": true, "trim_whitespace": true},
+				map[string]interface{}{})
+		} else if p.NullRate <= 0.05 {
+			addQuality(p, templateCompleteness,
+				fmt.Sprintf("空值率 %.1f%%，字段基本必填", p.NullRate*100),
+				roundRate(1-p.NullRate*10),
+				map[string]interface{}{"check_nullable": true, "trim_whitespace": true},
+				map[string]interface{}{})
+		}
+
+		// 唯一性：主键字段，或非空值几乎不重复的候选键
+		if p.IsPrimaryKey {
+			addQuality(p, templateUniqueness, "接口表主键字段", 1,
+				map[string]interface{}{"check_nullable": false},
+				map[string]interface{}{})
+		} else if nonNull >= 10 && p.DistinctRate >= 0.98 && !isTime {
+			addQuality(p, templateUniqueness,
+				fmt.Sprintf("非空值不重复率 %.1f%%", p.DistinctRate*100),
+				roundRate(p.DistinctRate),
+				map[string]interface{}{"check_nullable": false},
+				map[string]interface{}{})
+		}
+
+		// 取值模式：邮箱
+		if p.EmailRate >= patternMatchThreshold {
+			addQuality(p, templateEmailAccuracy,
+				fmt.Sprintf("%.1f%% 的取值符合邮箱格式", p.EmailRate*100),
+				roundRate(p.EmailRate),
+				map[string]interface{}{"trim_whitespace": true, "case_sensitive": false},
+				map[string]interface{}{"pattern": profileEmailPattern.String()})
+			if p.UppercaseRate > 0 || p.WhitespaceRate > 0 {
+				addCleansing(p, templateEmailCleansing,
+					fmt.Sprintf("邮箱字段中 %.1f%% 含大写字母、%.1f%% 含首尾空白", p.UppercaseRate*100, p.WhitespaceRate*100),
+					roundRate(p.EmailRate),
+					map[string]interface{}{"case": "lower", "trim_spaces": p.WhitespaceRate > 0})
+			}
+		}
+
+		// 取值模式：手机号
+		if p.PhoneRate >= patternMatchThreshold {
+			addQuality(p, templatePhoneValidity,
+				fmt.Sprintf("%.1f%% 的取值符合手机号格式", p.PhoneRate*100),
+				roundRate(p.PhoneRate),
+				map[string]interface{}{"trim_whitespace": true},
+				map[string]interface{}{"pattern": profilePhonePattern.String()})
+		}
+
+		// 邮箱或手机号字段中存在少量不合规取值，推荐格式修正
+		if invalid := 1 - math.Max(p.EmailRate, p.PhoneRate); math.Max(p.EmailRate, p.PhoneRate) >= patternMatchThreshold && invalid > 0 {
+			addCleansing(p, templateFormatFix,
+				fmt.Sprintf("%.1f%% 的取值不符合识别出的格式", invalid*100),
+				roundRate(1-invalid),
+				map[string]interface{}{"correction_strategy": "default_value"})
+		}
+
+		// 时间字段：推荐及时性检查
+		if isTime && nonNull > 0 && isFreshnessField(p.FieldName) {
+			addQuality(p, templateTimeliness,
+				"时间类型字段，可用于检查数据时效性",
+				0.7,
+				map[string]interface{}{"custom_params": map[string]interface{}{"time_field": p.FieldName, "max_age_days": 30}},
+				map[string]interface{}{})
+		}
+
+		// 文本中的日期取值格式不统一
+		if isText && p.DateRate >= patternMatchThreshold && p.DateFormats > 1 {
+			addCleansing(p, templateDateTransform,
+				fmt.Sprintf("文本字段中存在 %d 种日期格式", p.DateFormats),
+				roundRate(p.DateRate),
+				map[string]interface{}{"target_format": "yyyy-MM-dd"})
+		}
+
+		// 文本首尾空白：推荐标准化检查
+		if isText && p.WhitespaceRate > 0 && p.EmailRate < patternMatchThreshold {
+			addQuality(p, templateStandardization,
+				fmt.Sprintf("%.1f%% 的取值含首尾空白", p.WhitespaceRate*100),
+				roundRate(math.Min(0.9, 0.5+p.WhitespaceRate)),
+				map[string]interface{}{"trim_whitespace": true},
+				map[string]interface{}{})
+		}
+
+		// 地址类字段：推荐地址信息丰富
+		if isText && isAddressField(p.FieldName) && nonNull > 0 {
+			addCleansing(p, templateAddressEnrich,
+				"字段名表明为地址信息",
+				0.5,
+				nil)
+		}
+	}
+
+	return recommendations
+}
+
+// newRecommendation 构建推荐记录
+func newRecommendation(p FieldProfile, recType, templateID, templateName, reason string, confidence float64, config models.JSONB) models.RuleRecommendation {
+	return models.RuleRecommendation{
+		FieldName:          p.FieldName,
+		RecommendationType: recType,
+		TemplateID:         templateID,
+		TemplateName:       templateName,
+		SuggestedConfig:    config,
+		FieldProfile:       jsonbValue(p),
+		Reason:             reason,
+		Confidence:         math.Max(0, math.Min(1, confidence)),
+		Status:             RecommendationStatusPending,
+	}
+}
+
+// jsonbValue 将任意结构转换为JSONB
+func jsonbValue(v interface{}) models.JSONB {
+	result := models.JSONB{}
+	if v == nil {
+		return result
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return result
+	}
+	_ = json.Unmarshal(data, &result)
+	return result
+}
+
+// profileString 将字段值转换为字符串
+func profileString(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case []byte:
+		return string(v)
+	case time.Time:
+		return v.Format(time.RFC3339)
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
+
+func isTextType(dataType string) bool {
+	return dataType == "" || strings.Contains(dataType, "char") || dataType == "text"
+}
+
+func isFreshnessField(name string) bool {
+	lower := strings.ToLower(name)
+	for _, keyword := range []string{"update", "modif", "sync", "refresh"} {
+		if strings.Contains(lower, keyword) {
+			return true
+		}
+	}
+	return false
+}
+
+func isAddressField(name string) bool {
+	lower := strings.ToLower(name)
+	return strings.Contains(lower, "address") || strings.Contains(lower, "addr") || strings.Contains(name, "地址")
+}
+
+func ratio(part, total int64) float64 {
+	if total == 0 {
+		return 0
+	}
+	return roundRate(float64(part) / float64(total))
+}
+
+func roundRate(v float64) float64 {
+	return math.Round(v*10000) / 10000
+}
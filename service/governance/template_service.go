@@ -5,14 +5,17 @@
  * @documentReference ai_docs/data_governance_req.md
  * @stateFlow 模板管理生命周期
  * @rules 提供模板化的数据治理规则管理，支持模板创建、应用和执行
- * @dependencies datahub-service/service/models, gorm.io/gorm
+ * @dependencies datahub-service/service/models, datahub-service/service/udf, gorm.io/gorm
  * @refs service/models/governance.go, service/models/quality_models.go
  */
 
 package governance
 
 import (
+	"context"
+	"datahub-service/service/catalog_cache"
 	"datahub-service/service/models"
+	"datahub-service/service/udf"
 	"errors"
 	"log/slog"
 
@@ -63,11 +66,29 @@ func (s *TemplateService) CreateQualityRuleTemplate(template *models.QualityRule
 		return errors.New("无效的规则模板分类")
 	}
 
+	// 规则逻辑引用的自定义函数须存在且已审批
+	if err := udf.ValidateLogic(context.Background(), template.RuleLogic, models.UDFKindQuality); err != nil {
+		return err
+	}
+
 	return s.db.Create(template).Error
 }
 
 // GetQualityRuleTemplates 获取数据质量规则模板列表
 func (s *TemplateService) GetQualityRuleTemplates(page, pageSize int, ruleType, category string, isBuiltIn *bool) ([]models.QualityRuleTemplate, int64, error) {
+	result, err := catalog_cache.Fetch(context.Background(), catalog_cache.Key{
+		Name:   "quality_rule_templates",
+		Tables: []string{"quality_rule_templates"},
+		Params: []interface{}{page, pageSize, ruleType, category, isBuiltIn},
+	}, func() (catalog_cache.Page[models.QualityRuleTemplate], error) {
+		items, total, err := s.loadQualityRuleTemplates(page, pageSize, ruleType, category, isBuiltIn)
+		return catalog_cache.Page[models.QualityRuleTemplate]{Items: items, Total: total}, err
+	})
+	return result.Items, result.Total, err
+}
+
+// loadQualityRuleTemplates 从数据库查询数据质量规则模板列表
+func (s *TemplateService) loadQualityRuleTemplates(page, pageSize int, ruleType, category string, isBuiltIn *bool) ([]models.QualityRuleTemplate, int64, error) {
 	var templates []models.QualityRuleTemplate
 	var total int64
 
@@ -106,9 +127,23 @@ func (s *TemplateService) GetQualityRuleTemplateByID(id string) (*models.Quality
 
 // UpdateQualityRuleTemplate 更新数据质量规则模板
 func (s *TemplateService) UpdateQualityRuleTemplate(id string, updates map[string]interface{}) error {
+	if err := validateLogicUpdate(context.Background(), updates, "rule_logic", models.UDFKindQuality); err != nil {
+		return err
+	}
 	return s.db.Model(&models.QualityRuleTemplate{}).Where("id = ?", id).Updates(updates).Error
 }
 
+// validateLogicUpdate 检查更新的规则逻辑中引用的自定义函数
+func validateLogicUpdate(ctx context.Context, updates map[string]interface{}, column, kind string) error {
+	switch logic := updates[column].(type) {
+	case models.JSONB:
+		return udf.ValidateLogic(ctx, logic, kind)
+	case map[string]interface{}:
+		return udf.ValidateLogic(ctx, logic, kind)
+	}
+	return nil
+}
+
 // DeleteQualityRuleTemplate 删除数据质量规则模板
 func (s *TemplateService) DeleteQualityRuleTemplate(id string) error {
 	// 模板删除检查（直接应用模式下不需要检查应用实例）
@@ -169,6 +204,19 @@ func (s *TemplateService) CreateDataMaskingTemplate(template *models.DataMasking
 
 // GetDataMaskingTemplates 获取数据脱敏模板列表
 func (s *TemplateService) GetDataMaskingTemplates(page, pageSize int, maskingType, category, securityLevel string, isBuiltIn *bool) ([]models.DataMaskingTemplate, int64, error) {
+	result, err := catalog_cache.Fetch(context.Background(), catalog_cache.Key{
+		Name:   "data_masking_templates",
+		Tables: []string{"data_masking_templates"},
+		Params: []interface{}{page, pageSize, maskingType, category, securityLevel, isBuiltIn},
+	}, func() (catalog_cache.Page[models.DataMaskingTemplate], error) {
+		items, total, err := s.loadDataMaskingTemplates(page, pageSize, maskingType, category, securityLevel, isBuiltIn)
+		return catalog_cache.Page[models.DataMaskingTemplate]{Items: items, Total: total}, err
+	})
+	return result.Items, result.Total, err
+}
+
+// loadDataMaskingTemplates 从数据库查询数据脱敏模板列表
+func (s *TemplateService) loadDataMaskingTemplates(page, pageSize int, maskingType, category, securityLevel string, isBuiltIn *bool) ([]models.DataMaskingTemplate, int64, error) {
 	var templates []models.DataMaskingTemplate
 	var total int64
 
@@ -255,11 +303,29 @@ func (s *TemplateService) CreateDataCleansingTemplate(template *models.DataClean
 		return errors.New("无效的清洗模板分类")
 	}
 
+	// 清洗逻辑引用的自定义函数须存在且已审批
+	if err := udf.ValidateLogic(context.Background(), template.CleansingLogic, models.UDFKindCleansing); err != nil {
+		return err
+	}
+
 	return s.db.Create(template).Error
 }
 
 // GetDataCleansingTemplates 获取数据清洗模板列表
 func (s *TemplateService) GetDataCleansingTemplates(page, pageSize int, ruleType, category string, isBuiltIn *bool) ([]models.DataCleansingTemplate, int64, error) {
+	result, err := catalog_cache.Fetch(context.Background(), catalog_cache.Key{
+		Name:   "data_cleansing_templates",
+		Tables: []string{"data_cleansing_templates"},
+		Params: []interface{}{page, pageSize, ruleType, category, isBuiltIn},
+	}, func() (catalog_cache.Page[models.DataCleansingTemplate], error) {
+		items, total, err := s.loadDataCleansingTemplates(page, pageSize, ruleType, category, isBuiltIn)
+		return catalog_cache.Page[models.DataCleansingTemplate]{Items: items, Total: total}, err
+	})
+	return result.Items, result.Total, err
+}
+
+// loadDataCleansingTemplates 从数据库查询数据清洗模板列表
+func (s *TemplateService) loadDataCleansingTemplates(page, pageSize int, ruleType, category string, isBuiltIn *bool) ([]models.DataCleansingTemplate, int64, error) {
 	var templates []models.DataCleansingTemplate
 	var total int64
 
@@ -298,6 +364,9 @@ func (s *TemplateService) GetDataCleansingTemplateByID(id string) (*models.DataC
 
 // UpdateDataCleansingTemplate 更新数据清洗模板
 func (s *TemplateService) UpdateDataCleansingTemplate(id string, updates map[string]interface{}) error {
+	if err := validateLogicUpdate(context.Background(), updates, "cleansing_logic", models.UDFKindCleansing); err != nil {
+		return err
+	}
 	return s.db.Model(&models.DataCleansingTemplate{}).Where("id = ?", id).Updates(updates).Error
 }
 
@@ -0,0 +1,233 @@
+/*
+ * @module service/governance/tests/rule_recommendation_test
+ * @description 规则推荐引擎测试，覆盖字段画像、模板匹配、批量采纳和采纳率统计
+ * @architecture 测试层
+ * @documentReference ai_docs/data_governance_req.md
+ * @stateFlow 采样数据 -> 字段画像 -> 推荐 -> 采纳/拒绝 -> 统计
+ * @rules 使用SQLite内存数据库，不依赖外部服务
+ * @dependencies testing, datahub-service/service/governance, datahub-service/service/models
+ * @refs rule_recommendation.go
+ */
+
+package tests
+
+import (
+	"datahub-service/service/governance"
+	"datahub-service/service/models"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func recommendationTemplates() ([]models.QualityRuleTemplate, []models.DataCleansingTemplate) {
+	quality := []models.QualityRuleTemplate{
+		{ID: "q-completeness", Name: "字段完整性检查模板", Type: "completeness"},
+		{ID: "q-uniqueness", Name: "字段唯一性检查", Type: "uniqueness"},
+		{ID: "q-email", Name: "邮箱格式准确性检查", Type: "accuracy"},
+		{ID: "q-phone", Name: "手机号有效性检查", Type: "validity"},
+		{ID: "q-standard", Name: "数据格式标准化检查", Type: "standardization"},
+	}
+	cleansing := []models.DataCleansingTemplate{
+		{ID: "c-email", Name: "邮箱标准化清洗", DefaultConfig: models.JSONB{"case": "lower", "trim_spaces": true}},
+		{ID: "c-date", Name: "日期格式转换", DefaultConfig: models.JSONB{"target_format": "yyyy-MM-dd"}},
+	}
+	return quality, cleansing
+}
+
+func sampleRows() []map[string]interface{} {
+	rows := make([]map[string]interface{}, 0, 20)
+	for i := 0; i < 20; i++ {
+		email := fmt.Sprintf("user%d@example.com", i)
+		if i%5 == 0 {
+			email = fmt.Sprintf("User%d@Example.com", i)
+		}
+		signDate := fmt.Sprintf("2024-01-%02d", i+1)
+		if i%2 == 0 {
+			signDate = fmt.Sprintf("2024/01/%02d", i+1)
+		}
+		var remark interface{}
+		if i%2 == 0 {
+			remark = "备注"
+		}
+		rows = append(rows, map[string]interface{}{
+			"id":        i + 1,
+			"email":     email,
+			"phone":     fmt.Sprintf("138%08d", i),
+			"sign_date": signDate,
+			"remark":    remark,
+		})
+	}
+	return rows
+}
+
+func findRecommendation(recs []models.RuleRecommendation, field, templateID string) *models.RuleRecommendation {
+	for i := range recs {
+		if recs[i].FieldName == field && recs[i].TemplateID == templateID {
+			return &recs[i]
+		}
+	}
+	return nil
+}
+
+func TestProfileFields(t *testing.T) {
+	columns := []string{"id", "email", "phone", "sign_date", "remark"}
+	types := map[string]string{"id": "integer", "email": "character varying", "phone": "character varying", "sign_date": "text", "remark": "text"}
+
+	profiles := governance.ProfileFields(sampleRows(), columns, types)
+	require.Len(t, profiles, 5)
+
+	email := profiles[1]
+	assert.Equal(t, 20, email.SampleCount)
+	assert.Equal(t, 0, email.NullCount)
+	assert.Equal(t, 1.0, email.EmailRate)
+	assert.Equal(t, 0.2, email.UppercaseRate)
+	assert.Equal(t, 1.0, email.DistinctRate)
+
+	assert.Equal(t, 1.0, profiles[2].PhoneRate)
+	assert.Equal(t, 2, profiles[3].DateFormats)
+	assert.Equal(t, 0.5, profiles[4].NullRate)
+}
+
+func TestRecommendRules(t *testing.T) {
+	columns := []string{"id", "email", "phone", "sign_date", "remark"}
+	types := map[string]string{"id": "integer", "email": "character varying", "phone": "character varying", "sign_date": "text", "remark": "text"}
+	quality, cleansing := recommendationTemplates()
+
+	recs := governance.RecommendRules(governance.ProfileFields(sampleRows(), columns, types), quality, cleansing)
+
+	assert.NotNil(t, findRecommendation(recs, "id", "q-completeness"))
+	assert.NotNil(t, findRecommendation(recs, "id", "q-uniqueness"))
+	assert.NotNil(t, findRecommendation(recs, "email", "q-email"))
+	assert.NotNil(t, findRecommendation(recs, "phone", "q-phone"))
+	assert.NotNil(t, findRecommendation(recs, "sign_date", "c-date"))
+	assert.Nil(t, findRecommendation(recs, "remark", "q-completeness"), "空值率高的字段不应推荐完整性检查")
+
+	emailCleansing := findRecommendation(recs, "email", "c-email")
+	require.NotNil(t, emailCleansing)
+	assert.Equal(t, governance.RecommendationTypeCleansing, emailCleansing.RecommendationType)
+	config := emailCleansing.SuggestedConfig["cleansing_config"].(map[string]interface{})
+	assert.Equal(t, "lower", config["case"])
+	assert.Equal(t, false, config["trim_spaces"])
+}
+
+func TestApplyRecommendationsAndAdoptionStats(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&models.RuleRecommendation{}, &models.QualityTask{}, &models.QualityTaskFieldRule{}))
+
+	service := governance.NewGovernanceService(db)
+
+	task := &models.QualityTask{Name: "楼栋检测", LibraryType: "thematic", LibraryID: "lib", InterfaceID: "if", ScheduleType: "manual", TargetSchema: "property", TargetTable: "buildings"}
+	require.NoError(t, db.Create(task).Error)
+
+	recs := []models.RuleRecommendation{
+		{BatchID: "b1", TargetSchema: "property", TargetTable: "buildings", FieldName: "id", RecommendationType: governance.RecommendationTypeQuality, TemplateID: "q-completeness", TemplateName: "字段完整性检查模板",
+			SuggestedConfig: models.JSONB{"runtime_config": map[string]interface{}{"check_nullable": true}, "threshold": map[string]interface{}{}}},
+		{BatchID: "b1", TargetSchema: "property", TargetTable: "buildings", FieldName: "email", RecommendationType: governance.RecommendationTypeQuality, TemplateID: "q-email", TemplateName: "邮箱格式准确性检查"},
+		{BatchID: "b1", TargetSchema: "property", TargetTable: "buildings", FieldName: "email", RecommendationType: governance.RecommendationTypeCleansing, TemplateID: "c-email", TemplateName: "邮箱标准化清洗"},
+	}
+	require.NoError(t, db.Create(&recs).Error)
+
+	result, err := service.ApplyRuleRecommendations(&governance.ApplyRecommendationsRequest{
+		RecommendationIDs: []string{recs[0].ID, recs[2].ID, "missing"},
+		QualityTaskID:     task.ID,
+	}, "alice")
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.AppliedCount)
+	assert.Len(t, result.Skipped, 2)
+
+	var fieldRules []models.QualityTaskFieldRule
+	require.NoError(t, db.Where("task_id = ?", task.ID).Find(&fieldRules).Error)
+	require.Len(t, fieldRules, 1)
+	assert.Equal(t, "id", fieldRules[0].FieldName)
+	assert.Equal(t, true, fieldRules[0].RuntimeConfig["check_nullable"])
+
+	rejected, err := service.RejectRuleRecommendations([]string{recs[1].ID, recs[0].ID}, "alice")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), rejected)
+
+	stats, err := service.GetRecommendationAdoptionStats("property", "buildings")
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), stats.Total)
+	assert.Equal(t, int64(1), stats.Applied)
+	assert.Equal(t, int64(1), stats.Rejected)
+	assert.Equal(t, int64(1), stats.Pending)
+	assert.InDelta(t, 0.3333, stats.AdoptionRate, 0.001)
+}
+
+func TestRecommendRulesForPrimaryKey(t *testing.T) {
+	quality, cleansing := recommendationTemplates()
+	rows := []map[string]interface{}{{"code": "A1"}, {"code": "A2"}, {"code": "A2"}}
+	profiles := governance.ProfileFields(rows, []string{"code"}, map[string]string{"code": "character varying"})
+
+	recs := governance.RecommendRules(profiles, quality, cleansing)
+	assert.Nil(t, findRecommendation(recs, "code", "q-uniqueness"), "样例太少且有重复时不推荐唯一性")
+
+	profiles[0].IsPrimaryKey = true
+	recs = governance.RecommendRules(profiles, quality, cleansing)
+	uniqueness := findRecommendation(recs, "code", "q-uniqueness")
+	require.NotNil(t, uniqueness, "主键字段必推荐唯一性检查")
+	assert.Equal(t, 1.0, uniqueness.Confidence)
+	assert.NotNil(t, findRecommendation(recs, "code", "q-completeness"))
+}
+
+func TestApplyRecommendationsByBatch(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&models.RuleRecommendation{}, &models.QualityTask{}, &models.QualityTaskFieldRule{}))
+	service := governance.NewGovernanceService(db)
+
+	newRec := func(batch, field, templateID string, confidence float64) models.RuleRecommendation {
+		return models.RuleRecommendation{BatchID: batch, TargetSchema: "parking", TargetTable: "parking_records",
+			LibraryType: "basic", LibraryID: "lib-1", InterfaceID: "if-1", FieldName: field,
+			RecommendationType: governance.RecommendationTypeQuality, TemplateID: templateID, Confidence: confidence}
+	}
+	recs := []models.RuleRecommendation{
+		newRec("b1", "record_id", "q-uniqueness", 1),
+		newRec("b1", "plate_no", "q-completeness", 0.9),
+		newRec("b1", "remark", "q-standard", 0.5),
+		newRec("b2", "record_id", "q-completeness", 1),
+	}
+	cleansing := newRec("b1", "email", "c-email", 0.95)
+	cleansing.RecommendationType = governance.RecommendationTypeCleansing
+	recs = append(recs, cleansing)
+	require.NoError(t, db.Create(&recs).Error)
+
+	_, err = service.ApplyRuleRecommendations(&governance.ApplyRecommendationsRequest{}, "alice")
+	assert.Error(t, err)
+
+	result, err := service.ApplyRuleRecommendations(&governance.ApplyRecommendationsRequest{
+		BatchID: "b1", MinConfidence: 0.8, CreateQualityTask: true,
+	}, "alice")
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.AppliedCount)
+	require.Len(t, result.Skipped, 1, "清洗规则需要主题同步任务")
+	require.NotEmpty(t, result.QualityTaskID)
+
+	var task models.QualityTask
+	require.NoError(t, db.First(&task, "id = ?", result.QualityTaskID).Error)
+	assert.Equal(t, "if-1", task.InterfaceID)
+	assert.Equal(t, "parking_records", task.TargetTable)
+	assert.Equal(t, "manual", task.ScheduleType)
+
+	var fieldRules []models.QualityTaskFieldRule
+	require.NoError(t, db.Where("task_id = ?", task.ID).Order("field_name").Find(&fieldRules).Error)
+	require.Len(t, fieldRules, 2)
+	assert.Equal(t, "plate_no", fieldRules[0].FieldName)
+	assert.Equal(t, "record_id", fieldRules[1].FieldName)
+
+	var pending int64
+	require.NoError(t, db.Model(&models.RuleRecommendation{}).Where("status = ?", governance.RecommendationStatusPending).Count(&pending).Error)
+	assert.Equal(t, int64(3), pending, "低置信度、其他批次和清洗规则推荐保持待处理")
+
+	// 未按接口生成的推荐不能自动创建任务
+	require.NoError(t, db.Create(&models.RuleRecommendation{BatchID: "b3", TargetSchema: "s", TargetTable: "t", FieldName: "id",
+		RecommendationType: governance.RecommendationTypeQuality, TemplateID: "q-completeness", Confidence: 1}).Error)
+	_, err = service.ApplyRuleRecommendations(&governance.ApplyRecommendationsRequest{BatchID: "b3", CreateQualityTask: true}, "alice")
+	assert.Error(t, err)
+}
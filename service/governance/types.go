@@ -12,6 +12,7 @@
 package governance
 
 import (
+	"datahub-service/service/models"
 	"time"
 )
 
@@ -39,6 +40,7 @@ type UpdateQualityRuleRequest struct {
 	DefaultConfig map[string]interface{} `json:"default_config,omitempty" swaggertype:"object"`
 	IsEnabled     *bool                  `json:"is_enabled,omitempty" example:"false"`
 	Tags          map[string]interface{} `json:"tags,omitempty" swaggertype:"object"`
+	RowVersion    int64                  `json:"row_version,omitempty" example:"3"` // 期望版本，也可通过If-Match头传递
 }
 
 // QualityRuleResponse 质量规则模板响应
@@ -59,14 +61,13 @@ type QualityRuleResponse struct {
 	CreatedBy     string                 `json:"created_by" example:"admin"`
 	UpdatedAt     time.Time              `json:"updated_at" example:"2024-01-01T00:00:00Z"`
 	UpdatedBy     string                 `json:"updated_by" example:"admin"`
+	RowVersion    int64                  `json:"row_version" example:"3"` // 乐观锁版本，与ETag一致
 }
 
 // QualityRuleListResponse 质量规则模板列表响应
 type QualityRuleListResponse struct {
-	List  []QualityRuleResponse `json:"list"`
-	Total int64                 `json:"total" example:"25"`
-	Page  int                   `json:"page" example:"1"`
-	Size  int                   `json:"size" example:"10"`
+	List []QualityRuleResponse `json:"list"`
+	models.PageMeta
 }
 
 // === 数据脱敏规则相关类型 ===
@@ -92,6 +93,7 @@ type UpdateMaskingRuleRequest struct {
 	Parameters   map[string]interface{} `json:"parameters,omitempty" swaggertype:"object"`
 	IsEnabled    *bool                  `json:"is_enabled,omitempty" example:"false"`
 	Tags         map[string]interface{} `json:"tags,omitempty" swaggertype:"object"`
+	RowVersion   int64                  `json:"row_version,omitempty" example:"3"` // 期望版本，也可通过If-Match头传递
 }
 
 // MaskingRuleResponse 脱敏规则模板响应
@@ -112,14 +114,13 @@ type MaskingRuleResponse struct {
 	CreatedBy     string                 `json:"created_by" example:"admin"`
 	UpdatedAt     time.Time              `json:"updated_at" example:"2024-01-01T00:00:00Z"`
 	UpdatedBy     string                 `json:"updated_by" example:"admin"`
+	RowVersion    int64                  `json:"row_version" example:"3"` // 乐观锁版本，与ETag一致
 }
 
 // MaskingRuleListResponse 脱敏规则模板列表响应
 type MaskingRuleListResponse struct {
-	List  []MaskingRuleResponse `json:"list"`
-	Total int64                 `json:"total" example:"12"`
-	Page  int                   `json:"page" example:"1"`
-	Size  int                   `json:"size" example:"10"`
+	List []MaskingRuleResponse `json:"list"`
+	models.PageMeta
 }
 
 // === 质量检查相关类型 ===
@@ -146,10 +147,8 @@ type QualityReportResponse struct {
 
 // QualityReportListResponse 质量报告列表响应
 type QualityReportListResponse struct {
-	List  []QualityReportResponse `json:"list"`
-	Total int64                   `json:"total" example:"8"`
-	Page  int                     `json:"page" example:"1"`
-	Size  int                     `json:"size" example:"10"`
+	List []QualityReportResponse `json:"list"`
+	models.PageMeta
 }
 
 // === 元数据相关类型 ===
@@ -186,10 +185,8 @@ type MetadataResponse struct {
 
 // MetadataListResponse 元数据列表响应
 type MetadataListResponse struct {
-	List  []MetadataResponse `json:"list"`
-	Total int64              `json:"total" example:"30"`
-	Page  int                `json:"page" example:"1"`
-	Size  int                `json:"size" example:"10"`
+	List []MetadataResponse `json:"list"`
+	models.PageMeta
 }
 
 // === 清洗规则相关类型 ===
@@ -217,6 +214,7 @@ type UpdateCleansingRuleRequest struct {
 	DefaultConfig  map[string]interface{} `json:"default_config,omitempty" swaggertype:"object"`
 	IsEnabled      *bool                  `json:"is_enabled,omitempty" example:"false"`
 	Tags           map[string]interface{} `json:"tags,omitempty" swaggertype:"object"`
+	RowVersion     int64                  `json:"row_version,omitempty" example:"3"` // 期望版本，也可通过If-Match头传递
 }
 
 // CleansingRuleResponse 清洗规则模板响应
@@ -238,14 +236,13 @@ type CleansingRuleResponse struct {
 	CreatedBy       string                 `json:"created_by" example:"admin"`
 	UpdatedAt       time.Time              `json:"updated_at" example:"2024-01-01T00:00:00Z"`
 	UpdatedBy       string                 `json:"updated_by" example:"admin"`
+	RowVersion      int64                  `json:"row_version" example:"3"` // 乐观锁版本，与ETag一致
 }
 
 // CleansingRuleListResponse 清洗规则模板列表响应
 type CleansingRuleListResponse struct {
-	List  []CleansingRuleResponse `json:"list"`
-	Total int64                   `json:"total" example:"18"`
-	Page  int                     `json:"page" example:"1"`
-	Size  int                     `json:"size" example:"10"`
+	List []CleansingRuleResponse `json:"list"`
+	models.PageMeta
 }
 
 // CleansingExecutionResponse 清洗执行响应
@@ -336,6 +333,7 @@ type UpdateQualityTaskRequest struct {
 	NotificationConfig *NotificationConfigRequest `json:"notification_config,omitempty"`
 	Priority           *int                       `json:"priority,omitempty" example:"80"`
 	IsEnabled          *bool                      `json:"is_enabled,omitempty" example:"false"`
+	RowVersion         int64                      `json:"row_version,omitempty" example:"3"` // 期望版本，也可通过If-Match头传递
 }
 
 // FieldRuleResponse 字段规则响应
@@ -392,14 +390,13 @@ type QualityTaskResponse struct {
 	CreatedBy          string                     `json:"created_by" example:"admin"`
 	UpdatedAt          time.Time                  `json:"updated_at" example:"2024-01-01T00:00:00Z"`
 	UpdatedBy          string                     `json:"updated_by" example:"admin"`
+	RowVersion         int64                      `json:"row_version" example:"3"` // 乐观锁版本，与ETag一致
 }
 
 // QualityTaskListResponse 质量检测任务列表响应
 type QualityTaskListResponse struct {
-	List  []QualityTaskResponse `json:"list"`
-	Total int64                 `json:"total" example:"20"`
-	Page  int                   `json:"page" example:"1"`
-	Size  int                   `json:"size" example:"10"`
+	List []QualityTaskResponse `json:"list"`
+	models.PageMeta
 }
 
 // QualityTaskExecutionResponse 质量检测任务执行响应
@@ -426,10 +423,8 @@ type QualityTaskExecutionResponse struct {
 
 // QualityTaskExecutionListResponse 质量检测任务执行记录列表响应
 type QualityTaskExecutionListResponse struct {
-	List  []QualityTaskExecutionResponse `json:"list"`
-	Total int64                          `json:"total" example:"50"`
-	Page  int                            `json:"page" example:"1"`
-	Size  int                            `json:"size" example:"10"`
+	List []QualityTaskExecutionResponse `json:"list"`
+	models.PageMeta
 }
 
 // QualityIssueRecordResponse 质量问题记录响应
@@ -451,10 +446,8 @@ type QualityIssueRecordResponse struct {
 
 // QualityIssueRecordListResponse 质量问题记录列表响应
 type QualityIssueRecordListResponse struct {
-	List  []QualityIssueRecordResponse `json:"list"`
-	Total int64                        `json:"total" example:"100"`
-	Page  int                          `json:"page" example:"1"`
-	Size  int                          `json:"size" example:"10"`
+	List []QualityIssueRecordResponse `json:"list"`
+	models.PageMeta
 }
 
 // === 系统日志相关类型 ===
@@ -475,10 +468,8 @@ type SystemLogResponse struct {
 
 // SystemLogListResponse 系统日志列表响应
 type SystemLogListResponse struct {
-	List  []SystemLogResponse `json:"list"`
-	Total int64               `json:"total" example:"100"`
-	Page  int                 `json:"page" example:"1"`
-	Size  int                 `json:"size" example:"10"`
+	List []SystemLogResponse `json:"list"`
+	models.PageMeta
 }
 
 // === 数据血缘相关类型 ===
@@ -575,10 +566,8 @@ type QualityRuleTemplateResponse struct {
 
 // QualityRuleTemplateListResponse 质量规则模板列表响应
 type QualityRuleTemplateListResponse struct {
-	List  []QualityRuleTemplateResponse `json:"list"`
-	Total int64                         `json:"total" example:"50"`
-	Page  int                           `json:"page" example:"1"`
-	Size  int                           `json:"size" example:"10"`
+	List []QualityRuleTemplateResponse `json:"list"`
+	models.PageMeta
 }
 
 // DataMaskingTemplateResponse 数据脱敏模板响应
@@ -605,10 +594,8 @@ type DataMaskingTemplateResponse struct {
 
 // DataMaskingTemplateListResponse 数据脱敏模板列表响应
 type DataMaskingTemplateListResponse struct {
-	List  []DataMaskingTemplateResponse `json:"list"`
-	Total int64                         `json:"total" example:"30"`
-	Page  int                           `json:"page" example:"1"`
-	Size  int                           `json:"size" example:"10"`
+	List []DataMaskingTemplateResponse `json:"list"`
+	models.PageMeta
 }
 
 // DataCleansingTemplateResponse 数据清洗模板响应
@@ -635,10 +622,8 @@ type DataCleansingTemplateResponse struct {
 
 // DataCleansingTemplateListResponse 数据清洗模板列表响应
 type DataCleansingTemplateListResponse struct {
-	List  []DataCleansingTemplateResponse `json:"list"`
-	Total int64                           `json:"total" example:"25"`
-	Page  int                             `json:"page" example:"1"`
-	Size  int                             `json:"size" example:"10"`
+	List []DataCleansingTemplateResponse `json:"list"`
+	models.PageMeta
 }
 
 // === 规则测试相关类型定义 ===
@@ -765,3 +750,100 @@ type TestRulePreviewResponse struct {
 		Confidence     float64 `json:"confidence" example:"0.9"`
 	} `json:"estimated_impact"`
 }
+
+// === 规则推荐相关类型 ===
+
+// GenerateRecommendationsRequest 生成规则推荐请求
+type GenerateRecommendationsRequest struct {
+	TargetSchema  string `json:"target_schema" example:"property_theme"`
+	TargetTable   string `json:"target_table" example:"buildings"`
+	QualityTaskID string `json:"quality_task_id,omitempty" example:"uuid-task-123"`             // 提供时使用该任务的目标表
+	InterfaceID   string `json:"interface_id,omitempty" example:"uuid-interface-1"`             // 提供时使用该接口的接口表，并按表字段配置识别主键
+	LibraryType   string `json:"library_type,omitempty" example:"basic" enums:"basic,thematic"` // 接口所属库类型，默认basic
+	SampleSize    int    `json:"sample_size,omitempty" example:"1000"`
+}
+
+// FieldProfile 字段画像
+type FieldProfile struct {
+	FieldName      string  `json:"field_name" example:"email"`
+	DataType       string  `json:"data_type" example:"character varying"`
+	SampleCount    int     `json:"sample_count" example:"1000"`
+	NullCount      int     `json:"null_count" example:"3"`
+	NullRate       float64 `json:"null_rate" example:"0.003"`
+	DistinctCount  int     `json:"distinct_count" example:"997"`
+	DistinctRate   float64 `json:"distinct_rate" example:"1"` // 非空值中的不重复比例
+	MinLength      int     `json:"min_length" example:"8"`
+	MaxLength      int     `json:"max_length" example:"40"`
+	EmailRate      float64 `json:"email_rate" example:"0.98"` // 以下比例均基于非空值
+	PhoneRate      float64 `json:"phone_rate" example:"0"`
+	DateRate       float64 `json:"date_rate" example:"0"`
+	DateFormats    int     `json:"date_formats" example:"0"` // 出现的日期格式种类数
+	NumericRate    float64 `json:"numeric_rate" example:"0"`
+	WhitespaceRate float64 `json:"whitespace_rate" example:"0.01"` // 含首尾空白的比例
+	UppercaseRate  float64 `json:"uppercase_rate" example:"0.2"`   // 含大写字母的比例
+	IsPrimaryKey   bool    `json:"is_primary_key,omitempty"`       // 接口表字段配置中的主键
+}
+
+// GenerateRecommendationsResponse 生成规则推荐响应
+type GenerateRecommendationsResponse struct {
+	BatchID         string                      `json:"batch_id" example:"uuid-batch-123"`
+	TargetSchema    string                      `json:"target_schema" example:"property_theme"`
+	TargetTable     string                      `json:"target_table" example:"buildings"`
+	InterfaceID     string                      `json:"interface_id,omitempty" example:"uuid-interface-1"`
+	Profiles        []FieldProfile              `json:"profiles"`
+	Recommendations []models.RuleRecommendation `json:"recommendations"`
+}
+
+// RuleRecommendationListResponse 规则推荐列表响应
+type RuleRecommendationListResponse struct {
+	List []models.RuleRecommendation `json:"list"`
+	models.PageMeta
+}
+
+// ApplyRecommendationsRequest 批量采纳推荐请求
+type ApplyRecommendationsRequest struct {
+	RecommendationIDs  []string `json:"recommendation_ids" example:"[\"uuid-rec-1\"]"`         // 与batch_id二选一
+	BatchID            string   `json:"batch_id,omitempty" example:"uuid-batch-123"`           // 采纳该批次中全部待处理的推荐
+	MinConfidence      float64  `json:"min_confidence,omitempty" example:"0.8"`                // 按批次采纳时只采纳置信度不低于该值的推荐
+	QualityTaskID      string   `json:"quality_task_id,omitempty" example:"uuid-task-123"`     // 质量规则写入的质量检测任务
+	ThematicSyncTaskID string   `json:"thematic_sync_task_id,omitempty" example:"uuid-sync-1"` // 清洗规则（及未指定质量任务时的质量规则）写入的主题同步任务
+	CreateQualityTask  bool     `json:"create_quality_task,omitempty"`                         // 未指定任务时为推荐所属接口创建手动执行的质量检测任务
+}
+
+// RejectRecommendationsRequest 批量拒绝推荐请求
+type RejectRecommendationsRequest struct {
+	RecommendationIDs []string `json:"recommendation_ids" binding:"required" example:"[\"uuid-rec-1\"]"`
+}
+
+// SkippedRecommendation 未能采纳的推荐
+type SkippedRecommendation struct {
+	ID     string `json:"id" example:"uuid-rec-1"`
+	Reason string `json:"reason" example:"未指定主题同步任务"`
+}
+
+// ApplyRecommendationsResponse 批量采纳推荐响应
+type ApplyRecommendationsResponse struct {
+	AppliedCount  int                     `json:"applied_count" example:"5"`
+	QualityTaskID string                  `json:"quality_task_id,omitempty" example:"uuid-task-123"` // 自动创建的质量检测任务
+	Skipped       []SkippedRecommendation `json:"skipped"`
+}
+
+// TemplateAdoptionStats 按模板统计的采纳情况
+type TemplateAdoptionStats struct {
+	TemplateID   string  `json:"template_id" example:"uuid-template-1"`
+	TemplateName string  `json:"template_name" example:"字段完整性检查模板"`
+	Total        int64   `json:"total" example:"10"`
+	Applied      int64   `json:"applied" example:"6"`
+	Rejected     int64   `json:"rejected" example:"2"`
+	AdoptionRate float64 `json:"adoption_rate" example:"0.6"`
+}
+
+// RecommendationAdoptionStats 推荐采纳率统计
+type RecommendationAdoptionStats struct {
+	Total        int64                   `json:"total" example:"20"` // 不含已被新批次取代的推荐
+	Pending      int64                   `json:"pending" example:"8"`
+	Applied      int64                   `json:"applied" example:"10"`
+	Rejected     int64                   `json:"rejected" example:"2"`
+	AdoptionRate float64                 `json:"adoption_rate" example:"0.5"`
+	ByTemplate   []TemplateAdoptionStats `json:"by_template"`
+}
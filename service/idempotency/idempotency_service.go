@@ -0,0 +1,141 @@
+/*
+ * @module service/idempotency/idempotency_service
+ * @description 幂等请求服务，按调用方+接口+Idempotency-Key登记请求指纹并保存响应，供客户端重试时重放
+ * @architecture 分层架构 - 业务服务层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow Begin(登记processing) -> 业务处理 -> Complete(保存响应) / Abandon(删除记录允许重试)
+ * @rules 指纹不一致返回ErrKeyReused；处理中返回ErrRequestInProgress；超过处理超时的processing记录视为已中断
+ * @dependencies datahub-service/service/models, gorm.io/gorm
+ * @refs api/middleware/idempotency.go, service/cleanup/log_cleanup_service.go
+ */
+
+package idempotency
+
+import (
+	"crypto/sha256"
+	"datahub-service/service/models"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrKeyReused 同一Idempotency-Key被用于不同的请求内容
+	ErrKeyReused = errors.New("Idempotency-Key已用于不同的请求内容")
+	// ErrRequestInProgress 相同请求仍在处理中
+	ErrRequestInProgress = errors.New("相同Idempotency-Key的请求正在处理中")
+)
+
+const (
+	// DefaultTTL 幂等记录默认保留时间
+	DefaultTTL = 24 * time.Hour
+	// processingTimeout 处理中记录的超时时间，超时后允许重新处理
+	processingTimeout = 5 * time.Minute
+	// MaxKeyLength Idempotency-Key最大长度
+	MaxKeyLength = 255
+)
+
+// Service 幂等请求服务
+type Service struct {
+	db  *gorm.DB
+	ttl time.Duration
+}
+
+// NewService 创建幂等请求服务，保留时间可通过IDEMPOTENCY_TTL_HOURS配置
+func NewService(db *gorm.DB) *Service {
+	ttl := DefaultTTL
+	if val := os.Getenv("IDEMPOTENCY_TTL_HOURS"); val != "" {
+		if hours, err := strconv.Atoi(val); err == nil && hours > 0 {
+			ttl = time.Duration(hours) * time.Hour
+		}
+	}
+	return &Service{db: db, ttl: ttl}
+}
+
+// Fingerprint 计算请求指纹
+func Fingerprint(scope string, body []byte) string {
+	hash := sha256.New()
+	hash.Write([]byte(scope))
+	hash.Write([]byte{0})
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// Begin 登记请求。返回已完成的记录时调用方应重放响应；返回新建的processing记录时调用方应继续处理
+func (s *Service) Begin(key, principal, scope, fingerprint string) (record *models.IdempotencyRecord, replay bool, err error) {
+	if key == "" || len(key) > MaxKeyLength {
+		return nil, false, fmt.Errorf("Idempotency-Key长度必须在1到%d之间", MaxKeyLength)
+	}
+
+	var existing models.IdempotencyRecord
+	err = s.db.Where("idempotency_key = ? AND principal = ? AND scope = ?", key, principal, scope).First(&existing).Error
+	switch {
+	case err == nil:
+		if reusable, checkErr := s.checkExisting(&existing, fingerprint); !reusable {
+			return &existing, checkErr == nil, checkErr
+		}
+		// 记录已过期或处理已中断，删除后重新登记
+		if err := s.db.Delete(&models.IdempotencyRecord{}, "id = ?", existing.ID).Error; err != nil {
+			return nil, false, err
+		}
+	case !errors.Is(err, gorm.ErrRecordNotFound):
+		return nil, false, err
+	}
+
+	now := time.Now()
+	record = &models.IdempotencyRecord{
+		IdempotencyKey: key,
+		Principal:      principal,
+		Scope:          scope,
+		Fingerprint:    fingerprint,
+		Status:         models.IdempotencyStatusProcessing,
+		ExpiresAt:      now.Add(s.ttl),
+	}
+	if err := s.db.Create(record).Error; err != nil {
+		// 并发请求同时登记时唯一索引冲突，视为处理中
+		var concurrent models.IdempotencyRecord
+		if s.db.Where("idempotency_key = ? AND principal = ? AND scope = ?", key, principal, scope).First(&concurrent).Error == nil {
+			return nil, false, ErrRequestInProgress
+		}
+		return nil, false, err
+	}
+	return record, false, nil
+}
+
+// checkExisting 检查已有记录，reusable为true表示记录已失效可重新登记；否则err为nil表示可重放
+func (s *Service) checkExisting(existing *models.IdempotencyRecord, fingerprint string) (reusable bool, err error) {
+	now := time.Now()
+	if existing.ExpiresAt.Before(now) {
+		return true, nil
+	}
+	if existing.Fingerprint != fingerprint {
+		return false, ErrKeyReused
+	}
+	if existing.Status == models.IdempotencyStatusProcessing {
+		if existing.CreatedAt.Add(processingTimeout).Before(now) {
+			return true, nil
+		}
+		return false, ErrRequestInProgress
+	}
+	return false, nil
+}
+
+// Complete 保存响应，之后相同请求将直接重放
+func (s *Service) Complete(recordID string, statusCode int, contentType string, body []byte) error {
+	return s.db.Model(&models.IdempotencyRecord{}).Where("id = ?", recordID).Updates(map[string]interface{}{
+		"status":          models.IdempotencyStatusCompleted,
+		"response_status": statusCode,
+		"content_type":    contentType,
+		"response_body":   body,
+	}).Error
+}
+
+// Abandon 删除处理中的记录，允许客户端使用相同Key重试
+func (s *Service) Abandon(recordID string) error {
+	return s.db.Delete(&models.IdempotencyRecord{}, "id = ?", recordID).Error
+}
@@ -0,0 +1,119 @@
+/*
+ * @module service/idempotency/idempotency_service_test
+ * @description 幂等请求服务测试，覆盖首次登记、响应重放、指纹冲突、处理中冲突和失败重试
+ * @architecture 测试层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow 初始化内存数据库 -> 登记请求 -> 保存/释放记录 -> 验证重放结果
+ * @rules 使用SQLite内存数据库，不依赖外部服务
+ * @dependencies github.com/stretchr/testify, gorm.io/driver/sqlite
+ * @refs service/idempotency/idempotency_service.go
+ */
+
+package idempotency
+
+import (
+	"datahub-service/service/models"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func setupIdempotencyService(t *testing.T) *Service {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&models.IdempotencyRecord{}))
+
+	return NewService(db)
+}
+
+func TestBeginAndReplay(t *testing.T) {
+	s := setupIdempotencyService(t)
+	scope := "POST /sync/tasks"
+	fingerprint := Fingerprint(scope, []byte(`{"name":"task"}`))
+
+	record, replay, err := s.Begin("key-1", "user:alice", scope, fingerprint)
+	require.NoError(t, err)
+	assert.False(t, replay)
+	assert.Equal(t, models.IdempotencyStatusProcessing, record.Status)
+
+	// 首次请求处理中，重复请求返回冲突
+	_, _, err = s.Begin("key-1", "user:alice", scope, fingerprint)
+	assert.ErrorIs(t, err, ErrRequestInProgress)
+
+	require.NoError(t, s.Complete(record.ID, 200, "application/json", []byte(`{"status":0}`)))
+
+	replayed, replay, err := s.Begin("key-1", "user:alice", scope, fingerprint)
+	require.NoError(t, err)
+	assert.True(t, replay)
+	assert.Equal(t, 200, replayed.ResponseStatus)
+	assert.Equal(t, `{"status":0}`, string(replayed.ResponseBody))
+
+	// 相同Key不同请求内容
+	_, _, err = s.Begin("key-1", "user:alice", scope, Fingerprint(scope, []byte(`{"name":"other"}`)))
+	assert.ErrorIs(t, err, ErrKeyReused)
+
+	// 不同调用方的相同Key互不影响
+	_, replay, err = s.Begin("key-1", "user:bob", scope, fingerprint)
+	require.NoError(t, err)
+	assert.False(t, replay)
+}
+
+func TestAbandonAllowsRetry(t *testing.T) {
+	s := setupIdempotencyService(t)
+	scope := "POST /api/v1/ingest/webhook/orders"
+	fingerprint := Fingerprint(scope, []byte(`[1,2,3]`))
+
+	record, _, err := s.Begin("key-2", "api_key:k1", scope, fingerprint)
+	require.NoError(t, err)
+	require.NoError(t, s.Abandon(record.ID))
+
+	_, replay, err := s.Begin("key-2", "api_key:k1", scope, fingerprint)
+	require.NoError(t, err)
+	assert.False(t, replay)
+}
+
+func TestExpiredAndStaleRecords(t *testing.T) {
+	s := setupIdempotencyService(t)
+	scope := "POST /sync/tasks/1/start"
+	fingerprint := Fingerprint(scope, nil)
+
+	record, _, err := s.Begin("key-3", "user:alice", scope, fingerprint)
+	require.NoError(t, err)
+
+	// 处理超时的记录允许重新处理
+	require.NoError(t, s.db.Model(&models.IdempotencyRecord{}).Where("id = ?", record.ID).
+		Update("created_at", time.Now().Add(-processingTimeout-time.Minute)).Error)
+	retried, replay, err := s.Begin("key-3", "user:alice", scope, fingerprint)
+	require.NoError(t, err)
+	assert.False(t, replay)
+	assert.NotEqual(t, record.ID, retried.ID)
+
+	// 过期记录即使指纹不同也允许重新登记
+	require.NoError(t, s.Complete(retried.ID, 200, "", nil))
+	require.NoError(t, s.db.Model(&models.IdempotencyRecord{}).Where("id = ?", retried.ID).
+		Update("expires_at", time.Now().Add(-time.Minute)).Error)
+	_, replay, err = s.Begin("key-3", "user:alice", scope, Fingerprint(scope, []byte("x")))
+	require.NoError(t, err)
+	assert.False(t, replay)
+}
+
+func TestBeginRejectsInvalidKey(t *testing.T) {
+	s := setupIdempotencyService(t)
+
+	_, _, err := s.Begin("", "user:alice", "POST /x", "fp")
+	assert.Error(t, err)
+
+	longKey := make([]byte, MaxKeyLength+1)
+	for i := range longKey {
+		longKey[i] = 'a'
+	}
+	_, _, err = s.Begin(string(longKey), "user:alice", "POST /x", "fp")
+	assert.Error(t, err)
+}
@@ -0,0 +1,96 @@
+/*
+ * @module service/identity/claims
+ * @description OIDC/JWT声明解析，从已通过校验的Token中读取用户名、sub、姓名和邮箱，声明名称可按身份提供方配置
+ * @architecture 分层架构 - 业务服务层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow 认证中间件校验Token通过 -> DecodeClaims读取载荷 -> Resolve按配置的声明名称生成身份
+ * @rules
+ *   - DecodeClaims不校验签名，只能用于已由PostgREST校验通过的Token；不透明Token（非JWT）返回错误，调用方退回校验结果中的用户名
+ *   - 用户名优先取AUTH_USERNAME_CLAIM指定的声明，其次为Token校验返回的用户名，再次为preferred_username，最后为sub
+ *   - LDAP等目录服务通过身份提供方联合认证接入，用户属性以声明形式进入Token，本服务不直接访问目录
+ * @dependencies encoding/base64, encoding/json
+ * @refs service/identity/context.go, api/middleware/postgrest_auth.go
+ */
+
+package identity
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// ErrNotJWT Token不是JWT格式
+var ErrNotJWT = errors.New("Token不是JWT格式")
+
+// ClaimConfig 解析身份时使用的声明名称
+type ClaimConfig struct {
+	UsernameClaim string // 为空时使用Token校验返回的用户名
+	NameClaim     string
+	EmailClaim    string
+}
+
+// ClaimConfigFromEnv 从环境变量读取声明名称：AUTH_USERNAME_CLAIM、AUTH_NAME_CLAIM（默认name）、AUTH_EMAIL_CLAIM（默认email）
+func ClaimConfigFromEnv() ClaimConfig {
+	cfg := ClaimConfig{
+		UsernameClaim: os.Getenv("AUTH_USERNAME_CLAIM"),
+		NameClaim:     os.Getenv("AUTH_NAME_CLAIM"),
+		EmailClaim:    os.Getenv("AUTH_EMAIL_CLAIM"),
+	}
+	if cfg.NameClaim == "" {
+		cfg.NameClaim = "name"
+	}
+	if cfg.EmailClaim == "" {
+		cfg.EmailClaim = "email"
+	}
+	return cfg
+}
+
+// DecodeClaims 读取JWT载荷中的声明，不校验签名
+func DecodeClaims(token string) (map[string]interface{}, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, ErrNotJWT
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if err != nil {
+		return nil, fmt.Errorf("解码Token载荷失败: %w", err)
+	}
+	var claims map[string]interface{}
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return nil, fmt.Errorf("解析Token声明失败: %w", err)
+	}
+	return claims, nil
+}
+
+// Resolve 按声明生成身份，username为Token校验返回的用户名，claims可以为nil
+func (c ClaimConfig) Resolve(username string, claims map[string]interface{}) Identity {
+	id := Identity{
+		Username:    username,
+		Subject:     stringClaim(claims, "sub"),
+		DisplayName: stringClaim(claims, c.NameClaim),
+		Email:       stringClaim(claims, c.EmailClaim),
+	}
+	if claimed := stringClaim(claims, c.UsernameClaim); claimed != "" {
+		id.Username = claimed
+	}
+	if id.Username == "" {
+		id.Username = stringClaim(claims, "preferred_username")
+	}
+	if id.Username == "" {
+		id.Username = id.Subject
+	}
+	return id
+}
+
+// stringClaim 读取字符串声明，不存在或不是字符串时返回空
+func stringClaim(claims map[string]interface{}, name string) string {
+	if name == "" {
+		return ""
+	}
+	value, _ := claims[name].(string)
+	return strings.TrimSpace(value)
+}
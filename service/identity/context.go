@@ -0,0 +1,50 @@
+/*
+ * @module service/identity/context
+ * @description 认证身份上下文，在请求context中传递当前操作人，供审计字段、系统日志和各服务统一取用
+ * @architecture 分层架构 - 业务服务层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow 认证中间件校验Token -> 按声明解析身份 -> WithIdentity写入context -> 控制器、服务层和GORM回调读取操作人
+ * @rules context中没有身份表示非用户发起的操作（定时任务、白名单接口等），操作人记为system；身份只由认证中间件写入，不采信请求体中的操作人字段
+ * @dependencies context
+ * @refs api/middleware/postgrest_auth.go, service/identity/gorm_callback.go
+ */
+
+package identity
+
+import "context"
+
+// SystemOperator 非用户发起的操作使用的操作人
+const SystemOperator = "system"
+
+// Identity 当前请求的认证身份
+type Identity struct {
+	Username    string // 审计字段中记录的用户名
+	Subject     string // 身份提供方中用户的唯一标识(sub)
+	DisplayName string
+	Email       string
+	ClientIP    string
+}
+
+type contextKey struct{}
+
+// WithIdentity 将认证身份写入context
+func WithIdentity(ctx context.Context, id Identity) context.Context {
+	return context.WithValue(ctx, contextKey{}, id)
+}
+
+// FromContext 从context获取认证身份，ok为false表示没有已认证的用户
+func FromContext(ctx context.Context) (Identity, bool) {
+	if ctx == nil {
+		return Identity{}, false
+	}
+	id, ok := ctx.Value(contextKey{}).(Identity)
+	return id, ok && id.Username != ""
+}
+
+// Operator 返回当前操作人用户名，没有已认证的用户时返回system
+func Operator(ctx context.Context) string {
+	if id, ok := FromContext(ctx); ok {
+		return id.Username
+	}
+	return SystemOperator
+}
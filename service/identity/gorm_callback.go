@@ -0,0 +1,103 @@
+/*
+ * @module service/identity/gorm_callback
+ * @description 审计字段的GORM回调，按context中的认证身份填充created_by、updated_by和系统日志的操作人
+ * @architecture 分层架构 - 业务服务层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow GORM操作(WithContext) -> 回调读取context身份 -> 创建时写入CreatedBy/UpdatedBy，更新时写入UpdatedBy，系统日志补充操作人
+ * @rules 只作用于带对应字段的模型；context中没有身份时不做任何处理，保留调用方设置的值；有身份时以认证身份为准覆盖调用方的值；UpdateColumn(s)跳过钩子的更新不改写updated_by；原生SQL不经过该回调
+ * @dependencies gorm.io/gorm
+ * @refs service/identity/context.go, service/init.go
+ */
+
+package identity
+
+import (
+	"reflect"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+const (
+	createdByField    = "CreatedBy"
+	updatedByField    = "UpdatedBy"
+	operatorIDField   = "OperatorID"
+	operatorNameField = "OperatorName"
+	operatorIPField   = "OperatorIP"
+)
+
+// RegisterGormCallbacks 在数据库连接上注册审计字段回调
+func RegisterGormCallbacks(db *gorm.DB) error {
+	if err := db.Callback().Create().After("gorm:before_create").Before("gorm:create").Register("identity:create", assignCreator); err != nil {
+		return err
+	}
+	return db.Callback().Update().After("gorm:before_update").Before("gorm:update").Register("identity:update", assignUpdater)
+}
+
+// assignCreator 创建时写入创建人、更新人，系统日志未指定操作人时写入当前身份
+func assignCreator(tx *gorm.DB) {
+	if tx.Error != nil || tx.Statement.Schema == nil {
+		return
+	}
+	id, ok := FromContext(tx.Statement.Context)
+	if !ok {
+		return
+	}
+
+	rv := tx.Statement.ReflectValue
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < rv.Len(); i++ {
+			fillCreated(tx, reflect.Indirect(rv.Index(i)), id)
+		}
+	case reflect.Struct:
+		fillCreated(tx, rv, id)
+	}
+}
+
+func fillCreated(tx *gorm.DB, rv reflect.Value, id Identity) {
+	s := tx.Statement.Schema
+	setField(tx, s.LookUpField(createdByField), rv, id.Username, true)
+	setField(tx, s.LookUpField(updatedByField), rv, id.Username, true)
+
+	operatorID := id.Subject
+	if operatorID == "" {
+		operatorID = id.Username
+	}
+	setField(tx, s.LookUpField(operatorIDField), rv, &operatorID, false)
+	setField(tx, s.LookUpField(operatorNameField), rv, &id.Username, false)
+	if id.ClientIP != "" {
+		setField(tx, s.LookUpField(operatorIPField), rv, &id.ClientIP, false)
+	}
+}
+
+// setField 写入字段值，override为false时只填充零值字段
+func setField(tx *gorm.DB, field *schema.Field, rv reflect.Value, value interface{}, override bool) {
+	if field == nil {
+		return
+	}
+	if !override {
+		if _, isZero := field.ValueOf(tx.Statement.Context, rv); !isZero {
+			return
+		}
+	}
+	if err := field.Set(tx.Statement.Context, rv, value); err != nil {
+		tx.AddError(err)
+	}
+}
+
+// assignUpdater 更新时写入更新人
+func assignUpdater(tx *gorm.DB) {
+	if tx.Error != nil || tx.Statement.Schema == nil || tx.Statement.SkipHooks {
+		return
+	}
+	id, ok := FromContext(tx.Statement.Context)
+	if !ok {
+		return
+	}
+	field := tx.Statement.Schema.LookUpField(updatedByField)
+	if field == nil {
+		return
+	}
+	tx.Statement.SetColumn(field.DBName, id.Username, true)
+}
@@ -0,0 +1,130 @@
+/*
+ * @module service/identity/identity_test
+ * @description 认证身份测试，覆盖声明解析和审计字段回调
+ * @architecture 测试层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow 构造JWT声明解析身份；准备sqlite并注册审计回调 -> 以带身份和不带身份的context写入 -> 验证审计字段
+ * @rules 使用内存sqlite，不依赖外部服务
+ * @dependencies gorm.io/driver/sqlite, stretchr/testify
+ * @refs service/identity/claims.go, service/identity/gorm_callback.go
+ */
+
+package identity
+
+import (
+	"context"
+	"datahub-service/service/models"
+	"encoding/base64"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+func makeToken(t *testing.T, claims map[string]interface{}) string {
+	payload, err := json.Marshal(claims)
+	require.NoError(t, err)
+	return "eyJhbGciOiJIUzI1NiJ9." + base64.RawURLEncoding.EncodeToString(payload) + ".signature"
+}
+
+func TestResolveClaims(t *testing.T) {
+	token := makeToken(t, map[string]interface{}{
+		"sub":                "8f14e45f",
+		"preferred_username": "zhangsan",
+		"name":               "张三",
+		"email":              "zhangsan@example.com",
+		"uid":                "zs001",
+	})
+	claims, err := DecodeClaims(token)
+	require.NoError(t, err)
+
+	cfg := ClaimConfig{NameClaim: "name", EmailClaim: "email"}
+	// 默认以Token校验返回的用户名为准
+	id := cfg.Resolve("zhangsan@corp", claims)
+	assert.Equal(t, Identity{Username: "zhangsan@corp", Subject: "8f14e45f", DisplayName: "张三", Email: "zhangsan@example.com"}, id)
+
+	// 校验结果没有用户名时依次取preferred_username、sub
+	assert.Equal(t, "zhangsan", cfg.Resolve("", claims).Username)
+	delete(claims, "preferred_username")
+	assert.Equal(t, "8f14e45f", cfg.Resolve("", claims).Username)
+
+	// 指定用户名声明时优先使用
+	cfg.UsernameClaim = "uid"
+	assert.Equal(t, "zs001", cfg.Resolve("zhangsan@corp", claims).Username)
+
+	_, err = DecodeClaims("opaque-token")
+	assert.ErrorIs(t, err, ErrNotJWT)
+	assert.Equal(t, "alice", cfg.Resolve("alice", nil).Username)
+}
+
+func setupIdentityDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: gormlogger.Default.LogMode(gormlogger.Silent),
+	})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&models.WebhookEndpoint{}, &models.SystemLog{}))
+	require.NoError(t, RegisterGormCallbacks(db))
+	return db
+}
+
+func TestGormCallbacksFillAuditFields(t *testing.T) {
+	db := setupIdentityDB(t)
+	ctx := WithIdentity(context.Background(), Identity{Username: "zhangsan", Subject: "8f14e45f", ClientIP: "10.0.0.8"})
+
+	// 有身份时覆盖调用方填写的操作人
+	endpoint := &models.WebhookEndpoint{Name: "erp", URL: "https://example.com", Secret: "s", CreatedBy: "forged"}
+	require.NoError(t, db.WithContext(ctx).Create(endpoint).Error)
+	assert.Equal(t, "zhangsan", endpoint.CreatedBy)
+	assert.Equal(t, "zhangsan", endpoint.UpdatedBy)
+
+	// 结构体和map更新都写入更新人
+	lisi := WithIdentity(context.Background(), Identity{Username: "lisi"})
+	require.NoError(t, db.WithContext(lisi).Model(endpoint).Updates(&models.WebhookEndpoint{Description: "d"}).Error)
+	var saved models.WebhookEndpoint
+	require.NoError(t, db.First(&saved, "id = ?", endpoint.ID).Error)
+	assert.Equal(t, "lisi", saved.UpdatedBy)
+	assert.Equal(t, "zhangsan", saved.CreatedBy)
+
+	require.NoError(t, db.WithContext(ctx).Model(&models.WebhookEndpoint{}).Where("id = ?", endpoint.ID).
+		Update("is_enabled", true).Error)
+	require.NoError(t, db.First(&saved, "id = ?", endpoint.ID).Error)
+	assert.Equal(t, "zhangsan", saved.UpdatedBy)
+
+	// 跳过钩子的更新不改写更新人
+	require.NoError(t, db.WithContext(lisi).Model(&models.WebhookEndpoint{}).Where("id = ?", endpoint.ID).
+		UpdateColumn("description", "x").Error)
+	require.NoError(t, db.First(&saved, "id = ?", endpoint.ID).Error)
+	assert.Equal(t, "zhangsan", saved.UpdatedBy)
+
+	// 没有身份时保留调用方的值
+	other := &models.WebhookEndpoint{Name: "crm", URL: "https://example.com", Secret: "s", CreatedBy: "scheduler"}
+	require.NoError(t, db.WithContext(context.Background()).Create(other).Error)
+	assert.Equal(t, "scheduler", other.CreatedBy)
+}
+
+func TestGormCallbacksFillSystemLogOperator(t *testing.T) {
+	db := setupIdentityDB(t)
+	ctx := WithIdentity(context.Background(), Identity{Username: "zhangsan", Subject: "8f14e45f", ClientIP: "10.0.0.8"})
+
+	log := &models.SystemLog{OperationType: "update", ObjectType: "webhook", OperationContent: models.JSONB{}, OperationResult: "success"}
+	require.NoError(t, db.WithContext(ctx).Create(log).Error)
+	require.NotNil(t, log.OperatorName)
+	require.NotNil(t, log.OperatorID)
+	require.NotNil(t, log.OperatorIP)
+	assert.Equal(t, "zhangsan", *log.OperatorName)
+	assert.Equal(t, "8f14e45f", *log.OperatorID)
+	assert.Equal(t, "10.0.0.8", *log.OperatorIP)
+	assert.Equal(t, "zhangsan", log.CreatedBy)
+
+	// 调用方已记录的来源IP不被覆盖
+	ip := "192.168.1.1"
+	log = &models.SystemLog{OperationType: "update", ObjectType: "webhook", OperationContent: models.JSONB{}, OperationResult: "success", OperatorIP: &ip}
+	require.NoError(t, db.WithContext(ctx).Create(log).Error)
+	assert.Equal(t, "192.168.1.1", *log.OperatorIP)
+	assert.Equal(t, "system", Operator(context.Background()))
+	assert.Equal(t, "zhangsan", Operator(ctx))
+}
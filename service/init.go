@@ -13,16 +13,44 @@ package service
 
 import (
 	"context"
+	"datahub-service/logger"
+	"datahub-service/service/backup"
 	"datahub-service/service/basic_library"
+	"datahub-service/service/capacity"
+	"datahub-service/service/catalog_cache"
+	"datahub-service/service/chaos"
 	"datahub-service/service/cleanup"
+	"datahub-service/service/clickhouse"
+	"datahub-service/service/compliance"
 	"datahub-service/service/config"
 	"datahub-service/service/database"
 	"datahub-service/service/datasource"
 	"datahub-service/service/distributed_lock"
+	"datahub-service/service/encryption"
 	"datahub-service/service/event"
+	"datahub-service/service/eventlog"
+	"datahub-service/service/execution"
 	"datahub-service/service/governance"
+	"datahub-service/service/idempotency"
+	"datahub-service/service/identity"
+	"datahub-service/service/logmask"
+	"datahub-service/service/metric_store"
+	"datahub-service/service/models"
+	"datahub-service/service/notification"
+	"datahub-service/service/operation"
+	"datahub-service/service/query_insight"
+	"datahub-service/service/rbac"
+	"datahub-service/service/reconcile"
+	"datahub-service/service/report"
+	"datahub-service/service/secrets"
 	"datahub-service/service/sharing"
+	"datahub-service/service/telemetry"
+	"datahub-service/service/tenant"
 	"datahub-service/service/thematic_library"
+	"datahub-service/service/tracing"
+	"datahub-service/service/udf"
+	"datahub-service/service/webhook"
+	"datahub-service/service/workbench"
 	"fmt"
 	"log"
 	"log/slog"
@@ -34,22 +62,77 @@ import (
 )
 
 var (
-	DB                           *gorm.DB
-	GlobalEventService           *event.EventService
-	GlobalBasicLibraryService    *basic_library.Service
-	GlobalThematicLibraryService *thematic_library.Service
-	GlobalThematicSyncService    *thematic_library.ThematicSyncService
-	GlobalSchemaService          *database.SchemaService
-	GlobalSyncTaskService        *basic_library.SyncTaskService // 现在包含调度功能
-	GlobalGovernanceService      *governance.GovernanceService
-	GlobalSharingService         *sharing.SharingService
-	GlobalDistributedLock        *distributed_lock.RedisLock // Redis分布式锁
-	GlobalConfigService          *config.ConfigService       // 配置服务
-	GlobalLogCleanupService      *cleanup.LogCleanupService  // 日志清理服务
+	DB                              *gorm.DB
+	ReadDB                          *gorm.DB // 只读副本连接，用于数据浏览和质量检测等大查询，未配置时与DB相同
+	GlobalEventService              *event.EventService
+	GlobalBasicLibraryService       *basic_library.Service
+	GlobalThematicLibraryService    *thematic_library.Service
+	GlobalThematicSyncService       *thematic_library.ThematicSyncService
+	GlobalSchemaService             *database.SchemaService
+	GlobalSyncTaskService           *basic_library.SyncTaskService // 现在包含调度功能
+	GlobalGovernanceService         *governance.GovernanceService
+	GlobalSharingService            *sharing.SharingService
+	GlobalGrantExpirer              *sharing.GrantExpirer                    // 数据使用授权到期回收
+	GlobalDistributedLock           *distributed_lock.RedisLock              // Redis分布式锁
+	GlobalConfigService             *config.ConfigService                    // 配置服务
+	GlobalLogCleanupService         *cleanup.LogCleanupService               // 日志清理服务
+	GlobalRBACService               *rbac.RBACService                        // 访问控制服务
+	GlobalDeployMode                string                                   // 部署模式：standalone/control/worker
+	GlobalExecutionWorker           *execution.Worker                        // 执行面命令处理器
+	GlobalIdempotencyService        *idempotency.Service                     // 幂等请求服务
+	GlobalChaosService              *chaos.Service                           // 故障注入服务（仅非生产环境启用）
+	GlobalEventLogService           *eventlog.Service                        // 应用事件日志服务
+	GlobalTenantService             *tenant.Service                          // 租户管理服务
+	GlobalBackupVerifier            *backup.Verifier                         // 备份完整性校验服务
+	GlobalFreshnessService          *basic_library.FreshnessService          // 接口数据新鲜度监控服务
+	GlobalContractService           *basic_library.ContractService           // 接口数据契约服务
+	GlobalDuplicateReportService    *basic_library.DuplicateReportService    // 接口表重复数据检测服务
+	GlobalReferenceService          *basic_library.ReferenceService          // 接口引用完整性服务
+	GlobalSnapshotService           *basic_library.SnapshotService           // 接口表快照服务
+	GlobalSCDService                *basic_library.SCDService                // 接口历史追踪配置服务
+	GlobalDeletePropagationService  *basic_library.DeletePropagationService  // 接口源端删除同步配置服务
+	GlobalProvenanceService         *basic_library.ProvenanceService         // 接口记录级来源追溯配置服务
+	GlobalTimeSeriesService         *basic_library.TimeSeriesService         // 接口时间序列存储配置服务
+	GlobalRollupService             *basic_library.RollupService             // 时间序列降采样任务服务
+	GlobalTelemetryService          *telemetry.Service                       // 设备遥测数据快速写入服务
+	GlobalValueAlertService         *basic_library.ValueAlertService         // 数据值告警服务
+	GlobalLogMaskService            *logmask.Service                         // 日志脱敏字段同步服务
+	GlobalCapacityService           *capacity.Service                        // 存储容量统计服务
+	GlobalQueryInsightService       *query_insight.Service                   // 共享接口表查询性能分析服务
+	GlobalWorkbenchService          *workbench.Service                       // SQL工作台服务
+	GlobalMetricStoreService        *metric_store.Service                    // 指标层服务
+	GlobalReportService             *report.Service                          // 报表订阅服务
+	GlobalUDFService                *udf.Service                             // 自定义函数注册表
+	GlobalEncryptionService         *encryption.Service                      // 敏感列加密服务
+	GlobalNotificationService       *notification.Service                    // 通知中心服务
+	GlobalWebhookService            *webhook.Service                         // 平台事件Webhook服务
+	GlobalQualityGateService        *basic_library.QualityGateService        // 同步任务质量门禁服务
+	GlobalSyncPlanService           *basic_library.SyncPlanService           // 同步容量规划服务
+	GlobalDataSourceUsageService    *basic_library.DataSourceUsageService    // 数据源使用情况服务
+	GlobalSchemaRegistryService     *basic_library.SchemaRegistryService     // 消息数据源schema注册中心查询服务
+	GlobalProtobufParseService      *basic_library.ProtobufParseService      // 接口Protobuf解析配置服务
+	GlobalCredentialRotationService *basic_library.CredentialRotationService // 数据源凭据轮换服务
+	GlobalComplianceService         *compliance.Service                      // 合规证据包服务
+	GlobalDataSourceTemplateService *basic_library.DataSourceTemplateService // 数据源模板服务
+	GlobalSyncTaskTemplateService   *basic_library.SyncTaskTemplateService   // 同步任务模板服务
+	GlobalCatalogImportService      *basic_library.CatalogImportService      // 系统台账导入服务
+	GlobalConfigLintService         *basic_library.ConfigLintService         // 接口配置检查服务
+	GlobalReconcileService          *reconcile.Service                       // 元数据一致性核对服务
+	GlobalOperationService          *operation.Service                       // 长时操作服务
+	// 数据源维护模式服务
+	GlobalMaintenanceService *basic_library.DataSourceMaintenanceService
+	// 库和租户配额服务
+	GlobalQuotaService *capacity.QuotaService
+	// 数据源熔断服务
+	GlobalCircuitService *basic_library.DataSourceCircuitService
+	// 接口基准数据集服务
+	GlobalGoldenService *basic_library.GoldenDatasetService
 )
 
 func init() {
 	initDatabase()
+	initReadDatabase()
+	initTracing()
 	runMigrations()
 	initServices()
 }
@@ -76,7 +159,7 @@ func initDatabase() {
 	}
 
 	var err error
-	DB, err = gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	DB, err = gorm.Open(postgres.Open(dsn), &gorm.Config{Logger: logger.NewGormLogger()})
 	if err != nil {
 		log.Fatalf("数据库连接失败: %v", err)
 	}
@@ -84,6 +167,56 @@ func initDatabase() {
 	slog.Info("数据库连接成功")
 }
 
+// initReadDatabase 初始化只读副本连接，配置DATABASE_READ_URL或DB_READ_HOST时启用，
+// 未配置或连接失败时回退到主库连接
+func initReadDatabase() {
+	ReadDB = DB
+
+	var dsn string
+	if readURL := os.Getenv("DATABASE_READ_URL"); readURL != "" {
+		dsn = readURL
+	} else if host := os.Getenv("DB_READ_HOST"); host != "" {
+		// 未单独配置的连接参数沿用主库配置
+		port := getEnvWithDefault("DB_READ_PORT", getEnvWithDefault("DB_PORT", "5432"))
+		user := getEnvWithDefault("DB_READ_USER", getEnvWithDefault("DB_USER", "postgres"))
+		password := getEnvWithDefault("DB_READ_PASSWORD", getEnvWithDefault("DB_PASSWORD", "things2024"))
+		dbname := getEnvWithDefault("DB_NAME", "postgres")
+		sslmode := getEnvWithDefault("DB_SSLMODE", "disable")
+		schema := getEnvWithDefault("DB_SCHEMA", "public")
+
+		dsn = fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=%s search_path=%s TimeZone=Asia/Shanghai default_transaction_read_only=on",
+			host, port, user, password, dbname, sslmode, schema)
+	} else {
+		return
+	}
+
+	readDB, err := gorm.Open(postgres.Open(dsn), &gorm.Config{Logger: logger.NewGormLogger()})
+	if err != nil {
+		slog.Error("只读副本连接失败，大查询使用主库", "error", err)
+		return
+	}
+
+	ReadDB = readDB
+	slog.Info("只读副本连接成功")
+}
+
+// initTracing 初始化链路追踪，启用时为数据库操作注册追踪回调
+func initTracing() {
+	tracing.Init()
+	if !tracing.Enabled() {
+		return
+	}
+
+	if err := tracing.RegisterGormCallbacks(DB); err != nil {
+		slog.Error("注册数据库链路追踪回调失败", "error", err)
+	}
+	if ReadDB != DB {
+		if err := tracing.RegisterGormCallbacks(ReadDB); err != nil {
+			slog.Error("注册只读副本链路追踪回调失败", "error", err)
+		}
+	}
+}
+
 // getEnvWithDefault 获取环境变量，如果不存在则返回默认值
 func getEnvWithDefault(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
@@ -118,23 +251,135 @@ func runMigrations() {
 func initServices() {
 	// 初始化配置服务（优先初始化，其他服务可能需要）
 	GlobalConfigService = config.NewConfigService(DB)
+	if _, err := GlobalConfigService.ReloadRuntimeConfig(); err != nil {
+		slog.Warn("加载运行时配置失败，使用默认值", "error", err)
+	}
+	GlobalConfigService.StartRuntimeReload(context.Background(), config.RuntimeReloadInterval)
+
+	// 配置中的密钥引用通过Dapr密钥API解析，数据源、通知渠道和备份校验使用时读取
+	secrets.SetDefault(secrets.NewDaprResolver())
+
+	// 初始化访问控制服务
+	GlobalRBACService = rbac.NewRBACService(DB)
+
+	// 初始化幂等请求服务
+	GlobalIdempotencyService = idempotency.NewService(DB)
+
+	// 初始化应用事件日志服务，同步执行等环节通过全局服务记录事件
+	GlobalEventLogService = eventlog.NewService(DB)
+	eventlog.SetDefault(GlobalEventLogService)
+
+	// 初始化通知中心，订阅应用事件并按订阅规则投递，控制面和执行面都会产生事件
+	GlobalNotificationService = notification.NewService(DB)
+	eventlog.AddListener(GlobalNotificationService.HandleEvent)
+	GlobalNotificationService.Start()
+
+	// 初始化平台事件Webhook，订阅应用事件并推送给外部系统登记的地址
+	GlobalWebhookService = webhook.NewService(DB)
+	eventlog.AddListener(GlobalWebhookService.HandleEvent)
+	GlobalWebhookService.Start()
+
+	// 初始化敏感列加密服务，同步写入和数据查看通过全局服务加解密
+	GlobalEncryptionService = encryption.NewService(DB)
+	encryption.SetDefault(GlobalEncryptionService)
+
+	// 初始化自定义函数注册表，规则引擎执行质量和清洗规则时通过全局注册表解析引用
+	GlobalUDFService = udf.NewService(DB)
+	udf.SetDefault(GlobalUDFService)
+
+	// 初始化租户服务，启用多租户时注册租户隔离回调
+	initTenant()
+
+	// 注册审计字段回调，按请求的认证身份填充created_by、updated_by和系统日志操作人
+	if err := identity.RegisterGormCallbacks(DB); err != nil {
+		slog.Error("注册审计字段回调失败", "error", err)
+	}
+
+	// 初始化故障注入服务，仅非生产环境且显式开启时注册注入点
+	initChaos()
+
+	// 初始化目录数据缓存，注册写回调按表失效
+	initCatalogCache()
+
+	// 初始化ClickHouse客户端，供高写入量主题接口使用
+	initClickHouse()
 
 	// 初始化事件服务
 	GlobalEventService = event.NewEventService(DB)
 	// 将事件服务作为参数传递给BasicLibraryService
 	GlobalBasicLibraryService = basic_library.NewService(DB, GlobalEventService)
+	GlobalBasicLibraryService.GetHealthReportService().SetReadDB(ReadDB)
 	GlobalThematicLibraryService = thematic_library.NewService(DB)
 	GlobalSchemaService = database.NewSchemaService(DB)
+	GlobalSchemaService.SetReadDB(ReadDB)
 	// 初始化同步任务服务（现在集成了调度功能）
 	GlobalSyncTaskService = basic_library.NewSyncTaskService(DB, GlobalBasicLibraryService)
 	// 初始化数据治理服务
 	GlobalGovernanceService = governance.NewGovernanceService(DB)
+	GlobalGovernanceService.SetReadDB(ReadDB)
 	// 初始化主题同步服务
 	GlobalThematicSyncService = thematic_library.NewThematicSyncService(DB, GlobalGovernanceService)
 	GlobalSharingService = sharing.NewSharingService(DB)
+	GlobalSharingService.SetReadDB(ReadDB)
+	GlobalGrantExpirer = sharing.NewGrantExpirer(GlobalSharingService)
+	GlobalBackupVerifier = backup.NewVerifier(DB)
+	GlobalFreshnessService = basic_library.NewFreshnessService(DB)
+	GlobalContractService = basic_library.NewContractService(DB)
+	GlobalDuplicateReportService = basic_library.NewDuplicateReportService(DB)
+	GlobalDuplicateReportService.SetReadDB(ReadDB)
+	GlobalReferenceService = basic_library.NewReferenceService(DB)
+	GlobalReferenceService.SetReadDB(ReadDB)
+	GlobalSnapshotService = basic_library.NewSnapshotService(DB)
+	GlobalSnapshotService.SetReadDB(ReadDB)
+	GlobalGoldenService = basic_library.NewGoldenDatasetService(DB)
+	GlobalSCDService = basic_library.NewSCDService(DB)
+	GlobalDeletePropagationService = basic_library.NewDeletePropagationService(DB)
+	GlobalProvenanceService = basic_library.NewProvenanceService(DB)
+	GlobalTimeSeriesService = basic_library.NewTimeSeriesService(DB)
+	GlobalRollupService = basic_library.NewRollupService(DB)
+	GlobalTelemetryService = telemetry.NewService(DB)
+	GlobalValueAlertService = basic_library.NewValueAlertService(DB)
+	GlobalLogMaskService = logmask.NewService(DB)
+	GlobalTelemetryService.SetWriteListener(GlobalValueAlertService.NotifyWritten)
+	GlobalQualityGateService = basic_library.NewQualityGateService(DB, GlobalGovernanceService)
+	GlobalSyncTaskService.SetQualityGate(GlobalQualityGateService)
+	GlobalSyncTaskService.SetValueAlerts(GlobalValueAlertService)
+	GlobalSyncPlanService = basic_library.NewSyncPlanService(DB)
+	GlobalDataSourceUsageService = basic_library.NewDataSourceUsageService(DB)
+	GlobalSchemaRegistryService = basic_library.NewSchemaRegistryService(DB)
+	GlobalProtobufParseService = basic_library.NewProtobufParseService(DB)
+	GlobalCredentialRotationService = basic_library.NewCredentialRotationService(DB,
+		GlobalBasicLibraryService.GetDatasourceService(), GlobalBasicLibraryService.GetDatasourceInitService())
+	GlobalMaintenanceService = basic_library.NewDataSourceMaintenanceService(DB)
+	GlobalCircuitService = basic_library.NewDataSourceCircuitService(DB, GlobalBasicLibraryService.GetDatasourceService().TestCandidateConnection)
+	GlobalSyncTaskService.SetCircuitBreaker(GlobalCircuitService)
+	GlobalDataSourceTemplateService = basic_library.NewDataSourceTemplateService(DB, GlobalBasicLibraryService)
+	GlobalSyncTaskTemplateService = basic_library.NewSyncTaskTemplateService(DB, GlobalSyncTaskService)
+	GlobalCatalogImportService = basic_library.NewCatalogImportService(DB, GlobalBasicLibraryService)
+	GlobalConfigLintService = basic_library.NewConfigLintService(DB)
+	GlobalCapacityService = capacity.NewService(DB)
+	GlobalQuotaService = capacity.NewQuotaService(DB)
+	GlobalSyncTaskService.SetQuota(GlobalQuotaService)
+	GlobalThematicSyncService.SetQuota(GlobalQuotaService)
+	GlobalComplianceService = compliance.NewService(DB, GlobalConfigService)
+	GlobalComplianceService.SetReadDB(ReadDB)
+	GlobalReconcileService = reconcile.NewService(DB)
+	GlobalQueryInsightService = query_insight.NewService(DB)
+	GlobalWorkbenchService = workbench.NewService(DB, ReadDB)
+	GlobalMetricStoreService = metric_store.NewService(DB, ReadDB)
+	GlobalReportService = report.NewService(DB, GlobalWorkbenchService, GlobalMetricStoreService, GlobalNotificationService)
+	GlobalReportService.SetRoleResolver(GlobalRBACService.ResolveRoles)
+
+	// 初始化执行面命令处理和分发
+	initExecution()
+
+	// 初始化长时操作服务，登记各类异步启动的执行方
+	initOperations()
 
 	// 初始化全局实时处理器
-	initRealtimeProcessor()
+	if execution.RunsExecutionPlane(GlobalDeployMode) {
+		initRealtimeProcessor()
+	}
 
 	// 初始化Redis分布式锁
 	if schedulerEnabled := getEnvWithDefault("SCHEDULER_ENABLED", "true"); schedulerEnabled == "true" {
@@ -149,14 +394,40 @@ func initServices() {
 			// 将分布式锁注入到服务中
 			GlobalSyncTaskService.SetDistributedLock(lock)
 			GlobalThematicSyncService.SetDistributedLock(lock)
+			GlobalBackupVerifier.SetDistributedLock(lock)
+			GlobalGrantExpirer.SetDistributedLock(lock)
+			GlobalFreshnessService.SetDistributedLock(lock)
+			GlobalRollupService.SetDistributedLock(lock)
+			GlobalValueAlertService.SetDistributedLock(lock)
+			GlobalReferenceService.SetDistributedLock(lock)
+			GlobalCapacityService.SetDistributedLock(lock)
+			GlobalReconcileService.SetDistributedLock(lock)
+			GlobalMetricStoreService.SetDistributedLock(lock)
+			GlobalReportService.SetDistributedLock(lock)
 		}
 	}
 
-	// 初始化数据源
-	initializeDataSources()
+	// 执行面：初始化数据源（常驻数据源在执行面运行）
+	if execution.RunsExecutionPlane(GlobalDeployMode) {
+		initializeDataSources()
+	}
 
 	// 重置运行中的任务状态（程序重启会中断正在执行的任务）
-	resetRunningTasksOnStartup()
+	// 分离部署时任务可能仍在其他worker上执行，不做重置
+	if GlobalDeployMode == execution.ModeStandalone {
+		resetRunningTasksOnStartup()
+	}
+
+	// 每个实例都输出日志，都需要登记接口上的敏感字段
+	if err := GlobalLogMaskService.Start(); err != nil {
+		slog.Error("启动日志脱敏字段同步失败", "error", err)
+	}
+
+	// worker只执行命令，不启动调度器
+	if !execution.RunsControlPlane(GlobalDeployMode) {
+		slog.Info("服务初始化完成", "deploy_mode", GlobalDeployMode)
+		return
+	}
 
 	// 启动基础库调度器
 	if err := GlobalSyncTaskService.StartScheduler(); err != nil {
@@ -192,7 +463,156 @@ func initServices() {
 		slog.Info("日志清理调度器启动成功")
 	}
 
-	slog.Info("服务初始化完成")
+	// 启动备份完整性校验调度器
+	if err := GlobalBackupVerifier.Start(); err != nil {
+		slog.Error("启动备份校验调度器失败", "error", err)
+	}
+
+	// 启动数据使用授权到期回收调度器
+	if err := GlobalGrantExpirer.Start(); err != nil {
+		slog.Error("启动授权回收调度器失败", "error", err)
+	}
+
+	// 启动接口数据新鲜度检查调度器
+	if err := GlobalFreshnessService.Start(); err != nil {
+		slog.Error("启动数据新鲜度检查调度器失败", "error", err)
+	}
+
+	// 启动时间序列降采样任务调度器
+	if err := GlobalRollupService.Start(); err != nil {
+		slog.Error("启动降采样任务调度器失败", "error", err)
+	}
+
+	// 启动数据值告警调度器
+	if err := GlobalValueAlertService.Start(); err != nil {
+		slog.Error("启动数据值告警调度器失败", "error", err)
+	}
+
+	// 启动接口引用完整性检查调度器
+	if err := GlobalReferenceService.Start(); err != nil {
+		slog.Error("启动引用完整性检查调度器失败", "error", err)
+	}
+
+	// 启动存储快照调度器
+	if err := GlobalCapacityService.Start(); err != nil {
+		slog.Error("启动存储快照调度器失败", "error", err)
+	}
+
+	// 启动元数据一致性核对调度器
+	if err := GlobalReconcileService.Start(); err != nil {
+		slog.Error("启动元数据核对调度器失败", "error", err)
+	}
+
+	// 启动指标刷新调度器
+	if err := GlobalMetricStoreService.Start(); err != nil {
+		slog.Error("启动指标刷新调度器失败", "error", err)
+	}
+
+	// 启动报表订阅调度器
+	if err := GlobalReportService.Start(); err != nil {
+		slog.Error("启动报表订阅调度器失败", "error", err)
+	}
+
+	slog.Info("服务初始化完成", "deploy_mode", GlobalDeployMode)
+}
+
+// initTenant 初始化租户服务
+func initTenant() {
+	GlobalTenantService = tenant.NewService(DB)
+	if err := GlobalTenantService.EnsureDefaultTenant(); err != nil {
+		slog.Error("初始化默认租户失败", "error", err)
+	}
+	if !GlobalTenantService.IsEnabled() {
+		return
+	}
+
+	if err := tenant.RegisterGormCallbacks(DB); err != nil {
+		slog.Error("注册租户隔离回调失败", "error", err)
+	}
+	if ReadDB != DB {
+		if err := tenant.RegisterGormCallbacks(ReadDB); err != nil {
+			slog.Error("注册只读副本租户隔离回调失败", "error", err)
+		}
+	}
+	slog.Info("多租户隔离已启用")
+}
+
+// initCatalogCache 初始化目录数据缓存，CATALOG_CACHE_ENABLED=false时不缓存
+func initCatalogCache() {
+	if !catalog_cache.Enabled() {
+		slog.Info("目录数据缓存未启用")
+		return
+	}
+
+	cache := catalog_cache.NewCache()
+	if err := cache.RegisterGormCallbacks(DB); err != nil {
+		slog.Error("注册目录数据缓存失效回调失败，不启用缓存", "error", err)
+		return
+	}
+	catalog_cache.SetDefault(cache)
+	slog.Info("目录数据缓存已启用", "ttl_seconds", cache.Stats().TTLSeconds)
+}
+
+// initClickHouse 配置CLICKHOUSE_URL时启用ClickHouse存储，连接检查失败只记录日志，不影响启动
+func initClickHouse() {
+	client := clickhouse.NewClientFromEnv()
+	if client == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := client.Ping(ctx); err != nil {
+		slog.Warn("ClickHouse连接检查失败", "error", err)
+	}
+	clickhouse.SetDefault(client)
+	slog.Info("ClickHouse存储已启用")
+}
+
+// initChaos 初始化故障注入服务
+func initChaos() {
+	GlobalChaosService = chaos.NewService(DB)
+	chaos.SetDefault(GlobalChaosService)
+	if !GlobalChaosService.Enabled() {
+		return
+	}
+
+	if err := GlobalChaosService.RegisterGormCallbacks(DB); err != nil {
+		slog.Error("注册写库故障注入回调失败", "error", err)
+	}
+	GlobalChaosService.Start()
+}
+
+// initExecution 根据部署模式注册执行命令处理器并为同步任务服务设置分发器
+func initExecution() {
+	GlobalDeployMode = execution.CurrentMode()
+
+	GlobalExecutionWorker = execution.NewWorker()
+	GlobalExecutionWorker.RegisterHandler(execution.CommandKindBasicSync, GlobalSyncTaskService.HandleExecutionCommand)
+	GlobalExecutionWorker.RegisterHandler(execution.CommandKindThematicSync, GlobalThematicSyncService.HandleExecutionCommand)
+
+	dispatcher := execution.NewDispatcher(GlobalDeployMode, GlobalExecutionWorker)
+	GlobalSyncTaskService.SetDispatcher(dispatcher)
+	GlobalThematicSyncService.SetDispatcher(dispatcher)
+
+	slog.Info("执行面初始化完成", "deploy_mode", GlobalDeployMode, "remote_dispatch", dispatcher.IsRemote())
+}
+
+// initOperations 初始化长时操作服务，登记同步任务启动、质量检测任务启动和主题同步执行的执行方
+func initOperations() {
+	GlobalOperationService = operation.NewService(DB)
+	GlobalOperationService.Register(models.OperationKindSyncTask, operation.TrackerFuncs{
+		RefreshFunc: GlobalSyncTaskService.RefreshOperation,
+		CancelFunc:  GlobalSyncTaskService.CancelOperation,
+	})
+	GlobalOperationService.Register(models.OperationKindQualityTask, operation.TrackerFuncs{
+		RefreshFunc: GlobalGovernanceService.RefreshOperation,
+		CancelFunc:  GlobalGovernanceService.CancelOperation,
+	})
+	GlobalOperationService.Register(models.OperationKindThematicSync, operation.TrackerFuncs{
+		RefreshFunc: GlobalThematicSyncService.RefreshOperation,
+		CancelFunc:  GlobalThematicSyncService.CancelOperation,
+	})
 }
 
 // initializeDataSources 初始化数据源
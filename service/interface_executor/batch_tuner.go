@@ -0,0 +1,148 @@
+/*
+ * @module service/interface_executor/batch_tuner
+ * @description 批量大小自适应控制器，按AIMD(加性增、乘性减)根据每次拉取或写入的耗时和错误调整拉取页大小和写入批大小
+ * @architecture 分层架构 - 业务服务层
+ * @documentReference ai_docs/interface_executor.md
+ * @stateFlow 观察一次拉取或写入的耗时和结果 -> 成功且耗时不超过目标时加一个步长 -> 出错或超过目标时减半 -> 限制在[最小值,最大值]内
+ * @rules 步长为初始大小的1/10(至少为1)；运行时配置sync_auto_tune开启或接口limit_config.auto_tune为true时启用，接口配置优先；
+ *        下限取limit_config.min_limit或运行时配置sync_tune_min_batch_size，上限与max_limit一致
+ * @dependencies datahub-service/service/config, datahub-service/service/metrics
+ * @refs pipeline.go, execute_operations.go, service/config/runtime.go
+ */
+
+package interface_executor
+
+import (
+	"datahub-service/service/config"
+	"datahub-service/service/metrics"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/spf13/cast"
+)
+
+// 自适应调整的阶段
+const (
+	tuningStageFetch = "fetch"
+	tuningStageWrite = "write"
+)
+
+// batchTuningMaxRetries 拉取出错后缩小页大小重试的最多连续次数
+const batchTuningMaxRetries = 3
+
+// batchTuner 单个阶段的批量大小控制器，拉取和写入各用一个
+type batchTuner struct {
+	mu        sync.Mutex
+	stage     string
+	size      int
+	min       int
+	max       int
+	step      int
+	target    time.Duration
+	increases int
+	decreases int
+}
+
+// newBatchTuner 创建控制器，初始大小限制在[minSize,maxSize]内
+func newBatchTuner(stage string, initial, minSize, maxSize int, target time.Duration) *batchTuner {
+	minSize = max(minSize, 1)
+	maxSize = max(maxSize, minSize)
+	size := min(max(initial, minSize), maxSize)
+	return &batchTuner{stage: stage, size: size, min: minSize, max: maxSize, step: max(size/10, 1), target: target}
+}
+
+// newBatchTuners 按接口批量配置和运行时配置创建拉取和写入的控制器，未开启时返回nil
+func newBatchTuners(limitConfig map[string]interface{}, batchSize, maxLimit int) (*batchTuner, *batchTuner) {
+	enabled := config.SyncAutoTuneEnabled()
+	if value, ok := limitConfig["auto_tune"]; ok {
+		enabled = cast.ToBool(value)
+	}
+	if !enabled {
+		return nil, nil
+	}
+
+	minLimit := cast.ToInt(limitConfig["min_limit"])
+	if minLimit <= 0 {
+		minLimit = config.SyncTuneMinBatchSize()
+	}
+	target := config.SyncTuneTargetLatency()
+	return newBatchTuner(tuningStageFetch, batchSize, minLimit, maxLimit, target),
+		newBatchTuner(tuningStageWrite, batchSize, minLimit, maxLimit, target)
+}
+
+// Size 当前批量大小，控制器为nil时返回fallback
+func (t *batchTuner) Size(fallback int) int {
+	if t == nil {
+		return fallback
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.size
+}
+
+// Observe 根据一次执行的耗时和结果调整批量大小，返回调整后的大小
+func (t *batchTuner) Observe(duration time.Duration, err error) int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	previous := t.size
+	if err != nil || duration > t.target {
+		t.size = max(t.size/2, t.min)
+	} else {
+		t.size = min(t.size+t.step, t.max)
+	}
+
+	switch {
+	case t.size < previous:
+		t.decreases++
+		metrics.ObserveSyncBatchAdjustment(t.stage, "decrease")
+		slog.Debug("批量大小已减小", "stage", t.stage, "from", previous, "to", t.size, "duration", duration, "error", err)
+	case t.size > previous:
+		t.increases++
+		metrics.ObserveSyncBatchAdjustment(t.stage, "increase")
+	}
+	return t.size
+}
+
+// Stats 调整统计，写入执行结果元数据
+func (t *batchTuner) Stats() map[string]interface{} {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return map[string]interface{}{
+		"final_size":        t.size,
+		"min_size":          t.min,
+		"max_size":          t.max,
+		"increases":         t.increases,
+		"decreases":         t.decreases,
+		"target_latency_ms": t.target.Milliseconds(),
+	}
+}
+
+// batchTuningMetadata 执行结果元数据中的自适应调整统计，未开启时返回nil
+func batchTuningMetadata(fetch, write *batchTuner) map[string]interface{} {
+	if fetch == nil || write == nil {
+		return nil
+	}
+	return map[string]interface{}{
+		tuningStageFetch: fetch.Stats(),
+		tuningStageWrite: write.Stats(),
+	}
+}
+
+// alignedPageSize 选择不超过desired且能整除offset的最大页大小，使换算后的页号正好从已拉取的行之后开始；
+// 找不到不小于minSize的页大小时保持当前大小(当前大小总能整除offset)
+func alignedPageSize(offset, current, desired, minSize int) int {
+	if desired == current || desired < 1 {
+		return current
+	}
+	if offset == 0 {
+		return desired
+	}
+	for size := desired; size >= minSize && size >= 1; size-- {
+		if offset%size == 0 {
+			return size
+		}
+	}
+	return current
+}
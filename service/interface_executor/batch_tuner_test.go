@@ -0,0 +1,124 @@
+/*
+ * @module service/interface_executor/batch_tuner_test
+ * @description 批量大小自适应控制器测试，覆盖加性增、乘性减、上下限、页大小对齐，以及流水线调整页大小后不重复不遗漏
+ * @architecture 测试层
+ * @documentReference ai_docs/interface_executor.md
+ * @stateFlow 构造控制器或按页号和页大小返回数据的内存数据源 -> 观察耗时和错误 -> 验证批量大小和写入的行
+ * @rules 不依赖数据库，耗时通过参数传入而不实际等待
+ * @dependencies testing, testify
+ * @refs batch_tuner.go, pipeline.go
+ */
+
+package interface_executor
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBatchTunerAIMD(t *testing.T) {
+	tuner := newBatchTuner(tuningStageFetch, 100, 30, 125, time.Second)
+
+	assert.Equal(t, 110, tuner.Observe(100*time.Millisecond, nil), "耗时低于目标时加一个步长")
+	assert.Equal(t, 120, tuner.Observe(100*time.Millisecond, nil))
+	assert.Equal(t, 125, tuner.Observe(100*time.Millisecond, nil), "不超过上限")
+	assert.Equal(t, 62, tuner.Observe(2*time.Second, nil), "超过目标耗时减半")
+	assert.Equal(t, 31, tuner.Observe(time.Millisecond, errors.New("timeout")), "出错减半")
+	assert.Equal(t, 30, tuner.Observe(time.Millisecond, errors.New("timeout")), "不低于下限")
+
+	stats := tuner.Stats()
+	assert.Equal(t, 3, stats["increases"])
+	assert.Equal(t, 3, stats["decreases"])
+	assert.Equal(t, 30, stats["final_size"])
+
+	var disabled *batchTuner
+	assert.Equal(t, 500, disabled.Size(500), "未开启时使用固定大小")
+	assert.Equal(t, 100, newBatchTuner(tuningStageWrite, 5000, 0, 100, time.Second).Size(0), "初始值限制在上限内")
+}
+
+func TestAlignedPageSize(t *testing.T) {
+	assert.Equal(t, 150, alignedPageSize(0, 100, 150, 10), "尚未拉取时直接使用新大小")
+	assert.Equal(t, 120, alignedPageSize(600, 100, 130, 10), "选择能整除已拉取行数的最大值")
+	assert.Equal(t, 50, alignedPageSize(300, 100, 50, 10))
+	assert.Equal(t, 100, alignedPageSize(700, 100, 60, 60), "下限内没有可整除的大小时保持当前大小")
+	assert.Equal(t, 100, alignedPageSize(700, 100, 100, 10))
+}
+
+func TestSyncPipelineAutoTune(t *testing.T) {
+	const total = 6000
+	var sizes []int
+	failures := 0
+
+	// 数据源按页号和页大小返回对应区间的行，页大小超过300时模拟超时
+	fetch := func(ctx context.Context, page, size int) (*syncPage, error) {
+		sizes = append(sizes, size)
+		if size > 300 {
+			failures++
+			return nil, errors.New("statement timeout")
+		}
+		start := (page - 1) * size
+		end := min(start+size, total)
+		rows := make([]map[string]interface{}, 0, max(end-start, 0))
+		for id := start; id < end; id++ {
+			rows = append(rows, map[string]interface{}{"id": id})
+		}
+		return &syncPage{Rows: rows}, nil
+	}
+
+	seen := make(map[int]int)
+	var chunks []int
+	result, err := (&syncPipeline{
+		BatchSize:  100,
+		Buffer:     1,
+		StartPage:  1,
+		Fetch:      fetch,
+		FetchTuner: newBatchTuner(tuningStageFetch, 100, 10, 400, time.Hour),
+		WriteTuner: newBatchTuner(tuningStageWrite, 100, 10, 400, time.Hour),
+		Write: func(ctx context.Context, batch int, rows []map[string]interface{}) (int64, error) {
+			chunks = append(chunks, len(rows))
+			for _, row := range rows {
+				seen[row["id"].(int)]++
+			}
+			return int64(len(rows)), nil
+		},
+	}).Run(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(total), result.TotalRows)
+	require.Len(t, seen, total, "调整页大小后不遗漏")
+	for id, count := range seen {
+		require.Equal(t, 1, count, "行%d重复写入", id)
+	}
+	assert.Greater(t, failures, 0, "页大小增大到超时后缩小重试")
+	assert.Greater(t, slices.Max(sizes), 100, "拉取快时增大页大小")
+	assert.Greater(t, slices.Max(chunks), 100, "写入快时增大写入批大小")
+}
+
+func TestSyncPipelineAutoTunePinsOversizedPages(t *testing.T) {
+	var sizes []int
+	result, err := (&syncPipeline{
+		BatchSize: 10,
+		StartPage: 1,
+		Fetch: func(ctx context.Context, page, size int) (*syncPage, error) {
+			sizes = append(sizes, size)
+			if page > 3 {
+				return &syncPage{}, nil
+			}
+			return &syncPage{Rows: makeRows(50)}, nil
+		},
+		FetchTuner: newBatchTuner(tuningStageFetch, 10, 1, 100, time.Hour),
+		Write: func(ctx context.Context, batch int, rows []map[string]interface{}) (int64, error) {
+			return int64(len(rows)), nil
+		},
+	}).Run(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(150), result.TotalRows)
+	assert.Equal(t, []int{10, 10, 10, 10}, sizes, "数据源不按请求的页大小返回时固定页大小")
+}
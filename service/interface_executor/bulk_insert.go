@@ -0,0 +1,265 @@
+/*
+ * @module service/interface_executor/bulk_insert
+ * @description 多行VALUES批量写入，按列集合分组、每条语句写入多行，整块语句预编译后在本次写入内复用
+ * @architecture 工具函数 - 数据写入
+ * @documentReference ai_docs/interface_executor.md
+ * @stateFlow 原始行 -> 字段映射 -> 数据契约校验(strictness=fail有违规时不写入) -> 类型转换 -> 按字段配置确定列顺序并分组(开启来源追溯时追加系统列) -> 按块拼接多行INSERT -> 预编译句柄(整块)或直接执行(尾块) -> 累计影响行数 -> 刷新同步刷新派生列；
+ *            写入失败时按接口JSON Schema校验本批数据，附带结构化的违规清单
+ * @rules 每条语句最多bulkInsertRowsPerStatement行且参数不超过PostgreSQL上限；只缓存整块语句，尾块行数不固定不缓存；
+ *        句柄在当前事务上预编译、写入结束即关闭，不在事务外另取连接；列顺序固定且块大小固定，SQL文本稳定，
+ *        跨事务由pgx按连接缓存的预编译语句复用；
+ *        DO UPDATE要求调用方先按主键去重，同一语句内主键重复会报错
+ * @dependencies database/sql, gorm.io/gorm
+ * @refs field_mapping.go, table_columns.go
+ */
+
+package interface_executor
+
+import (
+	"context"
+	"database/sql"
+	"datahub-service/service/utils"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// bulkInsertRowsPerStatement 每条INSERT语句最多写入的行数
+	bulkInsertRowsPerStatement = 500
+	// postgresMaxBindParams PostgreSQL单条语句的参数个数上限
+	postgresMaxBindParams = 65535
+)
+
+// bulkConflictMode 主键冲突处理方式
+type bulkConflictMode int
+
+const (
+	bulkConflictNone   bulkConflictMode = iota // 普通INSERT
+	bulkConflictIgnore                         // ON CONFLICT DO NOTHING
+	bulkConflictUpdate                         // ON CONFLICT DO UPDATE
+)
+
+// bulkRowGroup 列集合相同的一组行
+type bulkRowGroup struct {
+	columns []string
+	rows    [][]interface{}
+}
+
+// bulkStatementCache 单次写入内的预编译句柄缓存，按SQL文本复用，写入结束后关闭
+type bulkStatementCache struct {
+	tx    *sql.Tx
+	stmts map[string]*sql.Stmt
+}
+
+// newBulkStatementCache 创建缓存，事务不是*sql.Tx时不缓存
+func newBulkStatementCache(tx *gorm.DB) *bulkStatementCache {
+	sqlTx, _ := tx.Statement.ConnPool.(*sql.Tx)
+	return &bulkStatementCache{tx: sqlTx, stmts: make(map[string]*sql.Stmt)}
+}
+
+// get 获取或预编译语句，不可缓存时返回nil
+func (c *bulkStatementCache) get(ctx context.Context, query string) (*sql.Stmt, error) {
+	if c.tx == nil {
+		return nil, nil
+	}
+	if stmt, ok := c.stmts[query]; ok {
+		return stmt, nil
+	}
+	stmt, err := c.tx.PrepareContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	c.stmts[query] = stmt
+	return stmt, nil
+}
+
+// close 关闭全部句柄
+func (c *bulkStatementCache) close() {
+	for _, stmt := range c.stmts {
+		_ = stmt.Close()
+	}
+}
+
+// bulkInsert 对原始行做字段映射和类型转换后多行写入，返回数据库报告的影响行数
+func (fm *FieldMapper) bulkInsert(ctx context.Context, tx *gorm.DB, interfaceInfo InterfaceInfo, fullTableName string, data []map[string]interface{}, mode bulkConflictMode, primaryKeys []string) (int64, error) {
+	groups := fm.buildBulkRowGroups(interfaceInfo, data)
+	if err := fm.contractRejection(); err != nil {
+		return 0, err
+	}
+	statements := newBulkStatementCache(tx)
+	defer statements.close()
+
+	var affected int64
+	for _, group := range groups {
+		rowsPerStatement := bulkInsertRowsPerStatement
+		if limit := postgresMaxBindParams / len(group.columns); limit < rowsPerStatement {
+			rowsPerStatement = limit
+		}
+
+		for start := 0; start < len(group.rows); start += rowsPerStatement {
+			end := start + rowsPerStatement
+			if end > len(group.rows) {
+				end = len(group.rows)
+			}
+			query := buildBulkInsertSQL(fullTableName, group.columns, end-start, mode, primaryKeys)
+			args := make([]interface{}, 0, (end-start)*len(group.columns))
+			for _, row := range group.rows[start:end] {
+				args = append(args, row...)
+			}
+
+			rows, err := execBulkStatement(ctx, tx, statements, query, args, end-start == rowsPerStatement)
+			if err != nil {
+				slog.Error("bulkInsert - 写入失败", "table", fullTableName, "columns", group.columns,
+					"row_offset", start, "row_count", end-start, "error", err)
+				if mode == bulkConflictUpdate {
+					return 0, fm.explainWriteFailure(interfaceInfo, data, fmt.Errorf("UPSERT数据失败: %w", err))
+				}
+				return 0, fm.explainWriteFailure(interfaceInfo, data, fmt.Errorf("插入数据失败: %w", err))
+			}
+			affected += rows
+		}
+	}
+
+	if len(groups) > 0 {
+		if err := fm.refreshComputedColumns(ctx, tx, interfaceInfo, fullTableName); err != nil {
+			return 0, err
+		}
+	}
+	return affected, nil
+}
+
+// buildBulkRowGroups 字段映射、类型转换后按列集合分组，列顺序取自字段配置，组按首次出现的顺序排列
+func (fm *FieldMapper) buildBulkRowGroups(interfaceInfo InterfaceInfo, data []map[string]interface{}) []*bulkRowGroup {
+	parseConfig := interfaceInfo.GetParseConfig()
+	configured := fm.configuredColumns(interfaceInfo)
+	computed := fm.computedFields(interfaceInfo)
+	var groups []*bulkRowGroup
+	groupIndex := make(map[string]*bulkRowGroup)
+
+	for i, row := range data {
+		mappedRow := fm.ApplyFieldMapping(row, parseConfig, i == 0)
+		if len(mappedRow) == 0 {
+			continue
+		}
+		fm.checkContractRow(interfaceInfo, mappedRow)
+		for _, field := range computed {
+			delete(mappedRow, field.NameEn)
+		}
+
+		columns := fm.orderRowColumns(mappedRow, configured)
+		if len(columns) == 0 {
+			continue
+		}
+
+		key := strings.Join(columns, "\x00")
+		group, ok := groupIndex[key]
+		if !ok {
+			group = &bulkRowGroup{columns: columns}
+			if fm.provenance != nil {
+				group.columns = append(append([]string{}, columns...), provenanceColumns...)
+			}
+			groupIndex[key] = group
+			groups = append(groups, group)
+		}
+
+		values := make([]interface{}, len(columns))
+		for idx, col := range columns {
+			values[idx] = fm.ProcessValueForDatabase(col, mappedRow[col], interfaceInfo, i == 0)
+		}
+		if fm.provenance != nil {
+			values = append(values, fm.provenance.values...)
+		}
+		group.rows = append(group.rows, values)
+	}
+	return groups
+}
+
+// buildBulkInsertSQL 构建多行INSERT语句，占位符使用$n
+func buildBulkInsertSQL(fullTableName string, columns []string, rowCount int, mode bulkConflictMode, primaryKeys []string) string {
+	var sb strings.Builder
+	sb.WriteString("INSERT INTO ")
+	sb.WriteString(fullTableName)
+	sb.WriteString(" (")
+	for i, col := range columns {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		sb.WriteString(utils.QuoteIdent(col))
+	}
+	sb.WriteString(") VALUES ")
+
+	param := 1
+	for r := 0; r < rowCount; r++ {
+		if r > 0 {
+			sb.WriteString(", ")
+		}
+		sb.WriteByte('(')
+		for c := range columns {
+			if c > 0 {
+				sb.WriteString(", ")
+			}
+			sb.WriteByte('$')
+			sb.WriteString(strconv.Itoa(param))
+			param++
+		}
+		sb.WriteByte(')')
+	}
+
+	if mode == bulkConflictNone || len(primaryKeys) == 0 {
+		return sb.String()
+	}
+
+	conflictKeys := make([]string, len(primaryKeys))
+	isPrimaryKey := make(map[string]bool, len(primaryKeys))
+	for i, pk := range primaryKeys {
+		conflictKeys[i] = utils.QuoteIdent(pk)
+		isPrimaryKey[pk] = true
+	}
+	var updateParts []string
+	if mode == bulkConflictUpdate {
+		for _, col := range columns {
+			if !isPrimaryKey[col] {
+				quoted := utils.QuoteIdent(col)
+				updateParts = append(updateParts, quoted+" = EXCLUDED."+quoted)
+			}
+		}
+	}
+
+	sb.WriteString(" ON CONFLICT (")
+	sb.WriteString(strings.Join(conflictKeys, ", "))
+	if len(updateParts) == 0 {
+		// 只有主键列时没有可更新的字段
+		sb.WriteString(") DO NOTHING")
+	} else {
+		sb.WriteString(") DO UPDATE SET ")
+		sb.WriteString(strings.Join(updateParts, ", "))
+	}
+	return sb.String()
+}
+
+// execBulkStatement 执行多行INSERT，整块语句使用预编译句柄，尾块直接执行
+func execBulkStatement(ctx context.Context, tx *gorm.DB, statements *bulkStatementCache, query string, args []interface{}, cacheable bool) (int64, error) {
+	if cacheable {
+		stmt, err := statements.get(ctx, query)
+		if err != nil {
+			return 0, err
+		}
+		if stmt != nil {
+			result, err := stmt.ExecContext(ctx, args...)
+			if err != nil {
+				return 0, err
+			}
+			return result.RowsAffected()
+		}
+	}
+
+	result, err := tx.Statement.ConnPool.ExecContext(ctx, query, args...)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
@@ -0,0 +1,217 @@
+/*
+ * @module service/interface_executor/bulk_insert_test
+ * @description 多行批量写入测试，覆盖语句拼接、按列集合分组、分块、主键冲突处理、按字段配置校验列和来源追溯系统列
+ * @architecture 测试层
+ * @documentReference ai_docs/interface_executor.md
+ * @stateFlow 构造数据 -> 多行写入SQLite内存库 -> 验证行数和表内容
+ * @rules SQLite支持$n占位符和ON CONFLICT语法，可替代PostgreSQL验证写入逻辑
+ * @dependencies testing, testify, gorm, sqlite
+ * @refs bulk_insert.go, field_mapping.go, table_columns.go
+ */
+
+package interface_executor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// TestBuildBulkInsertSQL 测试多行语句和冲突子句
+func TestBuildBulkInsertSQL(t *testing.T) {
+	assert.Equal(t, `INSERT INTO "s"."t" ("id", "name") VALUES ($1, $2), ($3, $4)`,
+		buildBulkInsertSQL(`"s"."t"`, []string{"id", "name"}, 2, bulkConflictNone, []string{"id"}))
+	assert.Equal(t, `INSERT INTO "s"."t" ("id", "name") VALUES ($1, $2) ON CONFLICT ("id") DO NOTHING`,
+		buildBulkInsertSQL(`"s"."t"`, []string{"id", "name"}, 1, bulkConflictIgnore, []string{"id"}))
+	assert.Equal(t, `INSERT INTO "s"."t" ("id", "name") VALUES ($1, $2) ON CONFLICT ("id") DO UPDATE SET "name" = EXCLUDED."name"`,
+		buildBulkInsertSQL(`"s"."t"`, []string{"id", "name"}, 1, bulkConflictUpdate, []string{"id"}))
+	assert.Equal(t, `INSERT INTO "s"."t" ("id") VALUES ($1) ON CONFLICT ("id") DO NOTHING`,
+		buildBulkInsertSQL(`"s"."t"`, []string{"id"}, 1, bulkConflictUpdate, []string{"id"}), "只有主键列时不更新")
+}
+
+// TestBulkInsert 测试超过单条语句行数上限和列集合不同的数据都能完整写入
+func TestBulkInsert(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.Exec(`CREATE TABLE items (id INTEGER PRIMARY KEY, name TEXT, note TEXT)`).Error)
+
+	info := &MockInterfaceInfo{}
+	info.On("GetParseConfig").Return(map[string]interface{}{})
+	info.On("GetID").Return("iface-1")
+	info.On("GetTableFieldsConfig").Return([]interface{}{})
+
+	rows := make([]map[string]interface{}, 0, 1203)
+	for i := 1; i <= 1200; i++ {
+		rows = append(rows, map[string]interface{}{"id": i, "name": "n"})
+	}
+	rows = append(rows,
+		map[string]interface{}{"id": 1201, "name": "a", "note": "x"},
+		map[string]interface{}{"id": 1202, "note": "y"},
+		map[string]interface{}{"id": 1203, "name": "b", "note": "z"})
+
+	fm := NewFieldMapper()
+	ctx := context.Background()
+	tx := db.Begin()
+	inserted, err := fm.bulkInsert(ctx, tx, info, `"items"`, rows, bulkConflictNone, nil)
+	require.NoError(t, err)
+	require.NoError(t, tx.Commit().Error)
+	assert.Equal(t, int64(1203), inserted)
+
+	var count int64
+	db.Table("items").Count(&count)
+	assert.Equal(t, int64(1203), count)
+
+	// 冲突时跳过，影响行数不含跳过的行
+	tx = db.Begin()
+	inserted, err = fm.bulkInsert(ctx, tx, info, `"items"`, []map[string]interface{}{
+		{"id": 1, "name": "dup"}, {"id": 2000, "name": "new"},
+	}, bulkConflictIgnore, []string{"id"})
+	require.NoError(t, err)
+	require.NoError(t, tx.Commit().Error)
+	assert.Equal(t, int64(1), inserted)
+
+	// 冲突时更新非主键列
+	tx = db.Begin()
+	_, err = fm.bulkInsert(ctx, tx, info, `"items"`, []map[string]interface{}{
+		{"id": 1, "name": "updated"},
+	}, bulkConflictUpdate, []string{"id"})
+	require.NoError(t, err)
+	require.NoError(t, tx.Commit().Error)
+	var name string
+	db.Raw(`SELECT name FROM items WHERE id = 1`).Scan(&name)
+	assert.Equal(t, "updated", name)
+
+	// 普通插入遇到重复主键时报错
+	tx = db.Begin()
+	_, err = fm.bulkInsert(ctx, tx, info, `"items"`, []map[string]interface{}{{"id": 2}}, bulkConflictNone, nil)
+	tx.Rollback()
+	assert.ErrorContains(t, err, "插入数据失败")
+}
+
+// TestBulkInsertUsesConfiguredColumns 测试列顺序取自字段配置，配置外字段不写入并和缺失字段一起报告
+func TestBulkInsertUsesConfiguredColumns(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.Exec(`CREATE TABLE devices (code TEXT PRIMARY KEY, name TEXT, status TEXT DEFAULT 'unknown')`).Error)
+
+	info := &MockInterfaceInfo{}
+	info.On("GetID").Return("iface-devices")
+	info.On("GetParseConfig").Return(map[string]interface{}{})
+	info.On("GetTableFieldsConfig").Return([]interface{}{
+		map[string]interface{}{"name_en": "status", "data_type": "varchar", "order_num": 3},
+		map[string]interface{}{"name_en": "code", "data_type": "varchar", "order_num": 1, "is_primary_key": true},
+		map[string]interface{}{"name_en": "name", "data_type": "varchar", "order_num": 2},
+	})
+
+	fm := NewFieldMapper()
+	assert.Equal(t, []string{"code", "name", "status"}, fm.configuredColumns(info))
+
+	groups := fm.buildBulkRowGroups(info, []map[string]interface{}{
+		{"status": "on", "name": "a", "code": "d1", "vendor": "x"},
+		{"code": "d2", "name": "b"},
+	})
+	require.Len(t, groups, 2)
+	assert.Equal(t, []string{"code", "name", "status"}, groups[0].columns)
+	assert.Equal(t, []string{"code", "name"}, groups[1].columns)
+
+	tx := db.Begin()
+	inserted, err := fm.bulkInsert(context.Background(), tx, info, `"devices"`, []map[string]interface{}{
+		{"code": "d3", "name": "c", "vendor": "y"},
+	}, bulkConflictNone, nil)
+	require.NoError(t, err)
+	require.NoError(t, tx.Commit().Error)
+	assert.Equal(t, int64(1), inserted)
+
+	var status string
+	db.Raw(`SELECT status FROM devices WHERE code = 'd3'`).Scan(&status)
+	assert.Equal(t, "unknown", status, "缺失字段使用列默认值")
+
+	assert.Equal(t, []string{
+		"字段配置中不存在的字段未写入: vendor(2行)",
+		"数据缺少字段配置中的字段，使用列默认值: status(2行)",
+	}, fm.FieldValidationWarnings())
+}
+
+// TestBulkInsertSkipsComputedColumns 测试派生列不在写入列中，同步刷新列在写入后按表达式计算
+func TestBulkInsertSkipsComputedColumns(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.Exec(`CREATE TABLE rooms (code TEXT PRIMARY KEY, length REAL, width REAL, area REAL)`).Error)
+
+	info := &MockInterfaceInfo{}
+	info.On("GetID").Return("iface-rooms")
+	info.On("GetParseConfig").Return(map[string]interface{}{})
+	info.On("GetTableFieldsConfig").Return([]interface{}{
+		map[string]interface{}{"name_en": "code", "data_type": "varchar", "order_num": 1, "is_primary_key": true},
+		map[string]interface{}{"name_en": "length", "data_type": "float", "order_num": 2},
+		map[string]interface{}{"name_en": "width", "data_type": "float", "order_num": 3},
+		map[string]interface{}{"name_en": "area", "data_type": "float", "order_num": 4, "expression": "length * width", "compute_mode": "sync"},
+	})
+
+	fm := NewFieldMapper()
+	assert.Equal(t, []string{"code", "length", "width"}, fm.configuredColumns(info))
+
+	tx := db.Begin()
+	_, err = fm.bulkInsert(context.Background(), tx, info, `"rooms"`, []map[string]interface{}{
+		{"code": "r1", "length": 2, "width": 3, "area": 100},
+	}, bulkConflictNone, nil)
+	require.NoError(t, err)
+	require.NoError(t, tx.Commit().Error)
+
+	var area float64
+	db.Raw(`SELECT area FROM rooms WHERE code = 'r1'`).Scan(&area)
+	assert.Equal(t, 6.0, area)
+	assert.Empty(t, fm.FieldValidationWarnings(), "数据中的派生列字段直接丢弃，不计入配置外字段")
+}
+
+// TestBulkInsertStampsProvenance 测试开启来源追溯时每行填写系统列，数据中的同名字段不写入，UPSERT冲突时一并更新
+func TestBulkInsertStampsProvenance(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.Exec(`CREATE TABLE orders (code TEXT PRIMARY KEY, amount REAL, _source_id TEXT, _sync_execution_id TEXT, _ingested_at DATETIME)`).Error)
+
+	info := &MockInterfaceInfo{}
+	info.On("GetID").Return("iface-orders")
+	info.On("GetParseConfig").Return(map[string]interface{}{})
+	info.On("GetTableFieldsConfig").Return([]interface{}{
+		map[string]interface{}{"name_en": "code", "data_type": "varchar", "order_num": 1, "is_primary_key": true},
+		map[string]interface{}{"name_en": "amount", "data_type": "float", "order_num": 2},
+		map[string]interface{}{"name_en": "_source_id", "data_type": "varchar", "order_num": 3},
+		map[string]interface{}{"name_en": "_sync_execution_id", "data_type": "varchar", "order_num": 4},
+		map[string]interface{}{"name_en": "_ingested_at", "data_type": "timestamp", "order_num": 5},
+	})
+
+	fm := NewFieldMapper()
+	assert.Equal(t, []string{"code", "amount"}, fm.configuredColumns(info))
+	fm.EnableProvenance("ds-1", "exec-1", time.Date(2026, 1, 1, 8, 0, 0, 0, time.UTC))
+
+	groups := fm.buildBulkRowGroups(info, []map[string]interface{}{{"code": "o1", "amount": 1, "_source_id": "forged"}})
+	require.Len(t, groups, 1)
+	assert.Equal(t, []string{"code", "amount", "_source_id", "_sync_execution_id", "_ingested_at"}, groups[0].columns)
+
+	tx := db.Begin()
+	_, err = fm.bulkInsert(context.Background(), tx, info, `"orders"`, []map[string]interface{}{{"code": "o1", "amount": 1}}, bulkConflictNone, nil)
+	require.NoError(t, err)
+	require.NoError(t, tx.Commit().Error)
+
+	// 手动执行的同步没有执行ID，UPSERT时覆盖为NULL
+	fm = NewFieldMapper()
+	fm.EnableProvenance("ds-2", "", time.Date(2026, 1, 2, 8, 0, 0, 0, time.UTC))
+	tx = db.Begin()
+	_, err = fm.bulkInsert(context.Background(), tx, info, `"orders"`, []map[string]interface{}{{"code": "o1", "amount": 2}}, bulkConflictUpdate, []string{"code"})
+	require.NoError(t, err)
+	require.NoError(t, tx.Commit().Error)
+
+	var row struct {
+		SourceID    string  `gorm:"column:_source_id"`
+		ExecutionID *string `gorm:"column:_sync_execution_id"`
+	}
+	require.NoError(t, db.Raw(`SELECT _source_id, _sync_execution_id FROM orders WHERE code = 'o1'`).Scan(&row).Error)
+	assert.Equal(t, "ds-2", row.SourceID)
+	assert.Nil(t, row.ExecutionID)
+}
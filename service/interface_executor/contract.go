@@ -0,0 +1,346 @@
+/*
+ * @module service/interface_executor/contract
+ * @description 按接口数据契约校验写入的数据，检查契约列是否在字段配置中、类型是否一致，并逐行检查可空性、类型和枚举取值
+ * @architecture 工具函数 - 数据写入
+ * @documentReference ai_docs/interface_executor.md
+ * @stateFlow 数据契约 -> 首批写入前比对字段配置 -> 字段映射后的每一行 -> 违规按列和类型累计 -> strictness=fail时拒绝本批写入 / warn时计入同步警告 -> 校验结果随执行响应返回
+ * @rules 校验的是字段映射后、类型转换前的值；派生列由数据库计算，不做逐行校验；空字符串不算空值；
+ *        strictness=fail时出现违规的批次整批不写入，流水线批量同步中之前已提交的批次保留
+ * @dependencies datahub-service/service/models, datahub-service/service/utils
+ * @refs bulk_insert.go, execute_operations.go, service/basic_library/contract_service.go
+ */
+
+package interface_executor
+
+import (
+	"datahub-service/service/models"
+	"datahub-service/service/utils"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cast"
+)
+
+// contractTimeParser 校验时间类型用的解析器，只判断能否解析，不关心时区
+var contractTimeParser = &utils.TimezonePolicy{Source: time.UTC, Target: time.UTC}
+
+// contractTableTypes 契约类型对应的表字段类型，表字段类型去掉长度和精度后比较
+var contractTableTypes = map[string][]string{
+	models.ContractTypeString:    {"varchar", "character varying", "char", "character", "text", "uuid", "string"},
+	models.ContractTypeInteger:   {"int", "integer", "bigint", "smallint", "int2", "int4", "int8", "serial", "bigserial"},
+	models.ContractTypeNumber:    {"int", "integer", "bigint", "smallint", "int2", "int4", "int8", "numeric", "decimal", "real", "float", "float4", "float8", "double", "double precision"},
+	models.ContractTypeBoolean:   {"boolean", "bool"},
+	models.ContractTypeTimestamp: {"timestamp", "timestamptz", "datetime", "timestamp with time zone", "timestamp without time zone"},
+	models.ContractTypeDate:      {"date"},
+	models.ContractTypeJSON:      {"json", "jsonb"},
+}
+
+// ContractTypes 支持的契约列类型
+func ContractTypes() []string {
+	types := make([]string, 0, len(contractTableTypes))
+	for contractType := range contractTableTypes {
+		types = append(types, contractType)
+	}
+	sort.Strings(types)
+	return types
+}
+
+// ContractReport 一次同步的数据契约校验结果
+type ContractReport struct {
+	ContractVersion int                       `json:"contract_version"`
+	Strictness      string                    `json:"strictness"`
+	Status          string                    `json:"status"` // compliant/violated/rejected
+	RowsChecked     int                       `json:"rows_checked"`
+	Violations      models.ContractViolations `json:"violations"`
+}
+
+// ViolationCount 违规次数合计
+func (r *ContractReport) ViolationCount() int {
+	count := 0
+	for _, violation := range r.Violations {
+		count += violation.Count
+	}
+	return count
+}
+
+// ContractViolationError strictness=fail时数据不符合契约，拒绝写入
+type ContractViolationError struct {
+	Report *ContractReport
+}
+
+func (e *ContractViolationError) Error() string {
+	return "数据不符合数据契约，已拒绝写入: " + summarizeContractViolations(e.Report.Violations)
+}
+
+// ContractReportOf 从执行结果中取出数据契约校验结果，接口未配置契约或没有写入数据时返回nil
+func ContractReportOf(response *ExecuteResponse, err error) *ContractReport {
+	var violationErr *ContractViolationError
+	if errors.As(err, &violationErr) {
+		return violationErr.Report
+	}
+	if response == nil || response.Metadata == nil {
+		return nil
+	}
+	report, _ := response.Metadata["contract"].(*ContractReport)
+	return report
+}
+
+// contractViolationKey 违规按列和类型合并
+type contractViolationKey struct {
+	column string
+	kind   string
+}
+
+// contractChecker 单个接口的数据契约校验状态，随字段映射器在一次同步内累计
+type contractChecker struct {
+	contract      *models.InterfaceContract
+	schemaChecked bool
+	computed      map[string]bool // 派生列，不做逐行校验
+	rejected      bool
+	rowsChecked   int
+	violations    map[contractViolationKey]*models.ContractViolation
+	order         []contractViolationKey
+}
+
+// SetContract 设置写入前校验的数据契约，nil或未启用时不校验
+func (fm *FieldMapper) SetContract(contract *models.InterfaceContract) {
+	if contract == nil || !contract.Enabled {
+		fm.contract = nil
+		return
+	}
+	fm.contract = &contractChecker{
+		contract:   contract,
+		violations: make(map[contractViolationKey]*models.ContractViolation),
+	}
+}
+
+// ContractReport 返回累计的数据契约校验结果，未设置契约或没有校验过数据时返回nil
+func (fm *FieldMapper) ContractReport() *ContractReport {
+	if fm.contract == nil || (fm.contract.rowsChecked == 0 && len(fm.contract.order) == 0) {
+		return nil
+	}
+	return fm.contract.report()
+}
+
+// attachContractReport 把数据契约校验结果放入执行响应的元数据
+func (fm *FieldMapper) attachContractReport(metadata map[string]interface{}) map[string]interface{} {
+	if report := fm.ContractReport(); report != nil {
+		metadata["contract"] = report
+	}
+	return metadata
+}
+
+// checkContractRow 按数据契约校验一行映射后的数据，首行前先比对字段配置
+func (fm *FieldMapper) checkContractRow(interfaceInfo InterfaceInfo, row map[string]interface{}) {
+	checker := fm.contract
+	if checker == nil {
+		return
+	}
+
+	if !checker.schemaChecked {
+		checker.schemaChecked = true
+		checker.computed = make(map[string]bool)
+		for _, field := range fm.computedFields(interfaceInfo) {
+			checker.computed[field.NameEn] = true
+		}
+		checker.checkSchema(fm.buildFieldTypeMapping(interfaceInfo))
+	}
+	checker.checkRow(row)
+}
+
+// contractRejection strictness=fail且出现违规时返回错误，本批数据不应写入
+func (fm *FieldMapper) contractRejection() error {
+	checker := fm.contract
+	if checker == nil || !checker.contract.RejectsViolations() || len(checker.order) == 0 {
+		return nil
+	}
+	checker.rejected = true
+	return &ContractViolationError{Report: checker.report()}
+}
+
+// contractWarnings strictness=warn时的契约违规警告
+func (fm *FieldMapper) contractWarnings() []string {
+	if fm.contract == nil || fm.contract.rejected || len(fm.contract.order) == 0 {
+		return nil
+	}
+	return []string{"数据不符合数据契约: " + summarizeContractViolations(fm.contract.report().Violations)}
+}
+
+// checkSchema 比对契约列和接口字段配置，字段配置为空时不比对
+func (c *contractChecker) checkSchema(fieldTypes map[string]string) {
+	if len(fieldTypes) == 0 && len(c.computed) == 0 {
+		return
+	}
+	for _, column := range c.contract.Columns {
+		dataType, exists := fieldTypes[column.Name]
+		if !exists && !c.computed[column.Name] {
+			c.add(column.Name, models.ContractViolationMissingColumn, "", "契约中的列不在接口字段配置中")
+			continue
+		}
+		if column.Type != "" && dataType != "" && !tableTypeMatchesContract(column.Type, dataType) {
+			c.add(column.Name, models.ContractViolationSchemaType, dataType, fmt.Sprintf("字段配置类型 %s 与契约类型 %s 不一致", dataType, column.Type))
+		}
+	}
+}
+
+// checkRow 逐列检查一行数据
+func (c *contractChecker) checkRow(row map[string]interface{}) {
+	c.rowsChecked++
+	for _, column := range c.contract.Columns {
+		if c.computed[column.Name] {
+			continue
+		}
+		value := row[column.Name]
+		if value == nil {
+			if !column.Nullable {
+				c.add(column.Name, models.ContractViolationNull, "", "不可为空的列为空或缺失")
+			}
+			continue
+		}
+		if column.Type != "" && !valueMatchesContractType(column.Type, value) {
+			c.add(column.Name, models.ContractViolationType, contractSample(value), "值不符合契约类型 "+column.Type)
+			continue
+		}
+		if len(column.EnumValues) > 0 && !slices.Contains(column.EnumValues, cast.ToString(value)) {
+			c.add(column.Name, models.ContractViolationEnum, contractSample(value), "值不在允许的取值中: "+strings.Join(column.EnumValues, ", "))
+		}
+	}
+}
+
+// add 累计一次违规，保留第一个违规值作为样例
+func (c *contractChecker) add(column, kind, sample, message string) {
+	key := contractViolationKey{column: column, kind: kind}
+	if violation, exists := c.violations[key]; exists {
+		violation.Count++
+		return
+	}
+	c.violations[key] = &models.ContractViolation{Column: column, Kind: kind, Count: 1, Sample: sample, Message: message}
+	c.order = append(c.order, key)
+}
+
+// report 生成校验结果，违规按出现顺序排列
+func (c *contractChecker) report() *ContractReport {
+	report := &ContractReport{
+		ContractVersion: c.contract.Version,
+		Strictness:      c.contract.Strictness,
+		Status:          models.ContractStatusCompliant,
+		RowsChecked:     c.rowsChecked,
+		Violations:      make(models.ContractViolations, 0, len(c.order)),
+	}
+	for _, key := range c.order {
+		report.Violations = append(report.Violations, *c.violations[key])
+	}
+	switch {
+	case c.rejected:
+		report.Status = models.ContractStatusRejected
+	case len(report.Violations) > 0:
+		report.Status = models.ContractStatusViolated
+	}
+	return report
+}
+
+// tableTypeMatchesContract 表字段类型是否满足契约类型，忽略长度和精度
+func tableTypeMatchesContract(contractType, dataType string) bool {
+	normalized := strings.ToLower(strings.TrimSpace(dataType))
+	if idx := strings.Index(normalized, "("); idx >= 0 {
+		normalized = strings.TrimSpace(normalized[:idx])
+	}
+	allowed, ok := contractTableTypes[contractType]
+	if !ok {
+		return true
+	}
+	return slices.Contains(allowed, normalized)
+}
+
+// valueMatchesContractType 值是否符合契约类型，字符串形式的数值、布尔值和时间可以通过
+func valueMatchesContractType(contractType string, value interface{}) bool {
+	switch contractType {
+	case models.ContractTypeString:
+		switch value.(type) {
+		case string, []byte:
+			return true
+		}
+		return false
+	case models.ContractTypeInteger:
+		switch v := value.(type) {
+		case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+			return true
+		case float32, float64:
+			f := cast.ToFloat64(v)
+			return f == math.Trunc(f)
+		case json.Number:
+			_, err := v.Int64()
+			return err == nil
+		case string:
+			_, err := strconv.ParseInt(strings.TrimSpace(v), 10, 64)
+			return err == nil
+		}
+		return false
+	case models.ContractTypeNumber:
+		switch v := value.(type) {
+		case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+			return true
+		case json.Number:
+			_, err := v.Float64()
+			return err == nil
+		case string:
+			_, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+			return err == nil
+		}
+		return false
+	case models.ContractTypeBoolean:
+		switch v := value.(type) {
+		case bool:
+			return true
+		case string:
+			_, err := strconv.ParseBool(strings.TrimSpace(v))
+			return err == nil
+		}
+		return false
+	case models.ContractTypeTimestamp, models.ContractTypeDate:
+		if _, isBool := value.(bool); isBool {
+			return false
+		}
+		_, _, err := contractTimeParser.ParseTime(value)
+		return err == nil
+	case models.ContractTypeJSON:
+		switch v := value.(type) {
+		case map[string]interface{}, []interface{}:
+			return true
+		case string:
+			return json.Valid([]byte(v))
+		case []byte:
+			return json.Valid(v)
+		}
+		return false
+	}
+	return true
+}
+
+// contractSample 违规值样例，超长截断
+func contractSample(value interface{}) string {
+	sample := cast.ToString(value)
+	if sample == "" {
+		sample = fmt.Sprintf("%v", value)
+	}
+	if runes := []rune(sample); len(runes) > 100 {
+		sample = string(runes[:100]) + "..."
+	}
+	return sample
+}
+
+// summarizeContractViolations 格式化为 "列(违规类型N次), ..."
+func summarizeContractViolations(violations models.ContractViolations) string {
+	parts := make([]string, len(violations))
+	for i, violation := range violations {
+		parts[i] = fmt.Sprintf("%s(%s %d次)", violation.Column, violation.Kind, violation.Count)
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,167 @@
+/*
+ * @module service/interface_executor/contract_test
+ * @description 数据契约校验测试，覆盖字段配置比对、逐行的可空性/类型/枚举检查、warn模式的同步警告和fail模式拒绝写入
+ * @architecture 测试层 - 单元测试
+ */
+
+package interface_executor
+
+import (
+	"context"
+	"datahub-service/service/models"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// newContractTestInfo 订单接口，字段配置中没有契约要求的amount列
+func newContractTestInfo() *MockInterfaceInfo {
+	info := &MockInterfaceInfo{}
+	info.On("GetID").Return("iface-orders")
+	info.On("GetParseConfig").Return(map[string]interface{}{})
+	info.On("GetTableFieldsConfig").Return([]interface{}{
+		map[string]interface{}{"name_en": "id", "data_type": "integer", "order_num": 1, "is_primary_key": true},
+		map[string]interface{}{"name_en": "status", "data_type": "varchar(20)", "order_num": 2},
+		map[string]interface{}{"name_en": "created_at", "data_type": "text", "order_num": 3},
+	})
+	return info
+}
+
+func newOrderContract(strictness string) *models.InterfaceContract {
+	return &models.InterfaceContract{
+		InterfaceID: "iface-orders",
+		Version:     3,
+		Strictness:  strictness,
+		Enabled:     true,
+		Columns: models.ContractColumns{
+			{Name: "id", Type: models.ContractTypeInteger},
+			{Name: "status", Type: models.ContractTypeString, EnumValues: []string{"paid", "refunded"}},
+			{Name: "created_at", Type: models.ContractTypeTimestamp, Nullable: true},
+			{Name: "amount", Type: models.ContractTypeNumber, Nullable: true},
+		},
+	}
+}
+
+// TestContractCheckRows 测试字段配置比对和逐行检查，违规按列和类型合并计数
+func TestContractCheckRows(t *testing.T) {
+	info := newContractTestInfo()
+	fm := NewFieldMapper()
+	fm.SetContract(newOrderContract(models.ContractStrictnessWarn))
+
+	for _, row := range []map[string]interface{}{
+		{"id": 1, "status": "paid", "created_at": "2024-01-02 03:04:05"},
+		{"id": "2", "status": "paid", "created_at": nil},
+		{"id": 3.5, "status": "shipped", "created_at": "yesterday"},
+		{"status": "unknown"},
+	} {
+		fm.checkContractRow(info, row)
+	}
+	require.NoError(t, fm.contractRejection())
+
+	report := fm.ContractReport()
+	require.NotNil(t, report)
+	assert.Equal(t, models.ContractStatusViolated, report.Status)
+	assert.Equal(t, 3, report.ContractVersion)
+	assert.Equal(t, 4, report.RowsChecked)
+	assert.Equal(t, models.ContractViolations{
+		{Column: "created_at", Kind: models.ContractViolationSchemaType, Count: 1, Sample: "text", Message: "字段配置类型 text 与契约类型 timestamp 不一致"},
+		{Column: "amount", Kind: models.ContractViolationMissingColumn, Count: 1, Message: "契约中的列不在接口字段配置中"},
+		{Column: "id", Kind: models.ContractViolationType, Count: 1, Sample: "3.5", Message: "值不符合契约类型 integer"},
+		{Column: "status", Kind: models.ContractViolationEnum, Count: 2, Sample: "shipped", Message: "值不在允许的取值中: paid, refunded"},
+		{Column: "created_at", Kind: models.ContractViolationType, Count: 1, Sample: "yesterday", Message: "值不符合契约类型 timestamp"},
+		{Column: "id", Kind: models.ContractViolationNull, Count: 1, Message: "不可为空的列为空或缺失"},
+	}, report.Violations)
+	assert.Equal(t, 7, report.ViolationCount())
+}
+
+// TestContractValueTypes 测试值与契约类型的匹配，字符串形式的数值、布尔值和时间可以通过
+func TestContractValueTypes(t *testing.T) {
+	cases := []struct {
+		contractType string
+		value        interface{}
+		want         bool
+	}{
+		{models.ContractTypeString, "a", true},
+		{models.ContractTypeString, 1, false},
+		{models.ContractTypeInteger, float64(2), true},
+		{models.ContractTypeInteger, " 42 ", true},
+		{models.ContractTypeInteger, "4.2", false},
+		{models.ContractTypeNumber, "4.2", true},
+		{models.ContractTypeNumber, "abc", false},
+		{models.ContractTypeBoolean, "true", true},
+		{models.ContractTypeBoolean, 1, false},
+		{models.ContractTypeTimestamp, "2024-01-02T03:04:05Z", true},
+		{models.ContractTypeTimestamp, int64(1704164645), true},
+		{models.ContractTypeDate, true, false},
+		{models.ContractTypeJSON, `{"a":1}`, true},
+		{models.ContractTypeJSON, map[string]interface{}{"a": 1}, true},
+		{models.ContractTypeJSON, "{", false},
+	}
+	for _, c := range cases {
+		assert.Equal(t, c.want, valueMatchesContractType(c.contractType, c.value), "%s %v", c.contractType, c.value)
+	}
+
+	assert.True(t, tableTypeMatchesContract(models.ContractTypeNumber, "NUMERIC(10,2)"))
+	assert.True(t, tableTypeMatchesContract(models.ContractTypeTimestamp, "timestamptz"))
+	assert.False(t, tableTypeMatchesContract(models.ContractTypeInteger, "varchar"))
+}
+
+// TestBulkInsertContractStrictness 测试warn模式照常写入并给出警告，fail模式拒绝写入整批数据
+func TestBulkInsertContractStrictness(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.Exec(`CREATE TABLE orders (id INTEGER PRIMARY KEY, status TEXT, created_at TEXT)`).Error)
+
+	info := &MockInterfaceInfo{}
+	info.On("GetID").Return("iface-orders")
+	info.On("GetParseConfig").Return(map[string]interface{}{})
+	info.On("GetTableFieldsConfig").Return([]interface{}{
+		map[string]interface{}{"name_en": "id", "data_type": "integer", "order_num": 1, "is_primary_key": true},
+		map[string]interface{}{"name_en": "status", "data_type": "varchar", "order_num": 2},
+	})
+	contract := &models.InterfaceContract{
+		InterfaceID: "iface-orders",
+		Version:     1,
+		Enabled:     true,
+		Columns: models.ContractColumns{
+			{Name: "id", Type: models.ContractTypeInteger},
+			{Name: "status", Type: models.ContractTypeString, EnumValues: []string{"paid"}},
+		},
+	}
+	rows := []map[string]interface{}{{"id": 1, "status": "paid"}, {"id": 2, "status": "lost"}}
+	count := func() int64 {
+		var n int64
+		db.Raw(`SELECT COUNT(*) FROM orders`).Scan(&n)
+		return n
+	}
+
+	contract.Strictness = models.ContractStrictnessWarn
+	fm := NewFieldMapper()
+	fm.SetContract(contract)
+	tx := db.Begin()
+	_, err = fm.bulkInsert(context.Background(), tx, info, `"orders"`, rows, bulkConflictNone, nil)
+	require.NoError(t, err)
+	require.NoError(t, tx.Commit().Error)
+	assert.Equal(t, int64(2), count())
+	assert.Equal(t, []string{"数据不符合数据契约: status(enum 1次)"}, fm.FieldValidationWarnings())
+	assert.Equal(t, models.ContractStatusViolated, fm.ContractReport().Status)
+
+	require.NoError(t, db.Exec(`DELETE FROM orders`).Error)
+	contract.Strictness = models.ContractStrictnessFail
+	fm = NewFieldMapper()
+	fm.SetContract(contract)
+	tx = db.Begin()
+	_, err = fm.bulkInsert(context.Background(), tx, info, `"orders"`, rows, bulkConflictNone, nil)
+	tx.Rollback()
+	var violationErr *ContractViolationError
+	require.True(t, errors.As(err, &violationErr))
+	assert.Equal(t, models.ContractStatusRejected, violationErr.Report.Status)
+	assert.Equal(t, int64(0), count())
+	assert.Empty(t, fm.FieldValidationWarnings(), "被拒绝的违规体现在错误中，不重复计入警告")
+	assert.Equal(t, FailureCodeContractViolation, ClassifyFailure(err).Code)
+	assert.Same(t, violationErr.Report, ContractReportOf(nil, err))
+}
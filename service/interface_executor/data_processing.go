@@ -13,6 +13,7 @@ package interface_executor
 
 import (
 	"context"
+	"datahub-service/service/chaos"
 	"datahub-service/service/datasource"
 	"datahub-service/service/meta"
 	"datahub-service/service/models"
@@ -44,8 +45,11 @@ func (dp *DataProcessor) FetchDataFromSource(ctx context.Context, interfaceInfo
 func (dp *DataProcessor) FetchDataFromSourceWithExecuteType(ctx context.Context, interfaceInfo InterfaceInfo, parameters map[string]interface{}, executeType string) ([]map[string]interface{}, map[string]string, []string, error) {
 	// 将executeType转换为syncStrategy
 	syncStrategy := "full"
-	if executeType == "incremental_sync" {
+	switch executeType {
+	case "incremental_sync":
 		syncStrategy = "incremental"
+	case "preview":
+		syncStrategy = "preview"
 	}
 
 	return dp.FetchDataFromSourceWithSyncStrategy(ctx, interfaceInfo, parameters, syncStrategy)
@@ -160,6 +164,9 @@ func (dp *DataProcessor) FetchDataFromSourceWithSyncStrategy(ctx context.Context
 		}
 
 		executeRequest, err = queryBuilder.BuildIncrementalRequest(incrementalParams)
+	case "preview":
+		// 预览只取limit行，限制下推到数据源查询
+		executeRequest, err = queryBuilder.BuildPreviewRequest(parameters, cast.ToInt(parameters["limit"]))
 	default:
 		executeRequest, err = queryBuilder.BuildTestRequest(parameters)
 	}
@@ -174,6 +181,12 @@ func (dp *DataProcessor) FetchDataFromSourceWithSyncStrategy(ctx context.Context
 	slog.Debug("FetchDataFromSource - Query", "value", executeRequest.Query)
 	slog.Debug("FetchDataFromSource - Data", "data", executeRequest.Data)
 
+	// 故障注入：模拟数据源请求超时
+	if err := chaos.Inject(ctx, models.FaultTypeDataSourceTimeout, dataSource.ID); err != nil {
+		slog.Error("FetchDataFromSource - 执行接口查询失败", "error", err)
+		return nil, nil, nil, fmt.Errorf("执行接口查询失败: %w", err)
+	}
+
 	// 执行数据查询
 	response, err := dsInstance.Execute(ctx, executeRequest)
 	if err != nil {
@@ -344,6 +357,12 @@ func (dp *DataProcessor) FetchBatchDataFromSource(ctx context.Context, interface
 
 	slog.Debug("FetchBatchDataFromSource - 执行请求", "data", executeRequest)
 
+	// 故障注入：模拟数据源请求超时
+	if err := chaos.Inject(ctx, models.FaultTypeDataSourceTimeout, dataSource.ID); err != nil {
+		slog.Error("FetchBatchDataFromSource - 执行接口查询失败", "error", err)
+		return nil, nil, nil, fmt.Errorf("执行接口查询失败: %w", err)
+	}
+
 	// 执行数据查询
 	response, err := dsInstance.Execute(ctx, executeRequest)
 	if err != nil {
@@ -556,6 +575,12 @@ func (dp *DataProcessor) FetchBatchDataFromSourceWithStrategy(ctx context.Contex
 
 	slog.Debug("FetchBatchDataFromSourceWithStrategy - 执行请求", "data", executeRequest)
 
+	// 故障注入：模拟数据源请求超时
+	if err := chaos.Inject(ctx, models.FaultTypeDataSourceTimeout, dataSource.ID); err != nil {
+		slog.Error("FetchBatchDataFromSourceWithStrategy - 执行接口查询失败", "error", err)
+		return nil, nil, nil, fmt.Errorf("执行接口查询失败: %w", err)
+	}
+
 	// 执行数据查询
 	response, err := dsInstance.Execute(ctx, executeRequest)
 	if err != nil {
@@ -0,0 +1,323 @@
+/*
+ * @module service/interface_executor/delete_propagation
+ * @description 源端删除同步，按源端删除标记或全量比对主键识别已删除的记录，在接口表中硬删除、写入软删除时间或移入归档表，并统计删除行数
+ * @architecture 工具函数 - 数据写入
+ * @documentReference ai_docs/interface_executor.md
+ * @stateFlow 删除标记：每批写入前分出标记为删除的行 -> UPSERT其余行 -> 同一事务内处理删除行；
+ *            全量比对：拉取源端全量数据按主键UPSERT -> 记录写入后读到的主键 -> 全部批次完成后读取接口表主键 -> 不在源端的按配置处理
+ * @rules 开启后全量同步也不清空表；全量比对时源端没有返回数据或有批次读取主键失败，本次不处理删除，避免误删；
+ *        软删除时记录重新出现会清空删除时间；归档时只复制接口表和归档表共有的列；只统计接口表中实际存在（软删除时为未删除）的记录
+ * @dependencies gorm.io/gorm, datahub-service/service/models
+ * @refs sync_diff.go, field_mapping.go, execute_operations.go, service/models/delete_propagation.go
+ */
+
+package interface_executor
+
+import (
+	"context"
+	"datahub-service/service/models"
+	"datahub-service/service/utils"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// deleteTracker 删除同步状态，随字段映射器在一次同步内累计
+type deleteTracker struct {
+	config     *models.DeletePropagationConfig
+	seen       map[string]bool // 全量比对时源端出现过的主键
+	incomplete bool            // 有批次没有读到写入后的主键，全量比对结果不可靠
+	deleted    int64
+	restored   int64
+}
+
+// EnableDeletePropagation 开启源端删除同步，之后的写入都按主键UPSERT，并按配置处理源端已删除的记录
+func (fm *FieldMapper) EnableDeletePropagation(config *models.DeletePropagationConfig) {
+	fm.deletes = &deleteTracker{config: config, seen: make(map[string]bool)}
+}
+
+// DeletePropagationEnabled 是否开启了源端删除同步
+func (fm *FieldMapper) DeletePropagationEnabled() bool {
+	return fm.deletes != nil
+}
+
+// deletePropagationConfig 读取启用的删除同步配置，开启历史追踪时不做删除同步
+func deletePropagationConfig(interfaceConfig map[string]interface{}) *models.DeletePropagationConfig {
+	if models.ParseSCDConfig(interfaceConfig) != nil {
+		return nil
+	}
+	return models.ParseDeletePropagationConfig(interfaceConfig)
+}
+
+// keepsExistingRows 全量同步是否保留已有数据（历史追踪或删除同步开启时不清空表）
+func (fm *FieldMapper) keepsExistingRows() bool {
+	return fm.scd != nil || fm.deletes != nil
+}
+
+// fullCompare 是否按全量比对识别删除
+func (t *deleteTracker) fullCompare() bool {
+	return t.config.Detection == models.DeleteDetectionFullCompare
+}
+
+// attachDeleteReport 把删除行数和软删除后重新出现的行数放入执行响应的元数据
+func (fm *FieldMapper) attachDeleteReport(metadata map[string]interface{}) map[string]interface{} {
+	if fm.deletes != nil {
+		metadata["deleted_rows"] = fm.deletes.deleted
+		metadata["restored_rows"] = fm.deletes.restored
+		metadata["delete_action"] = fm.deletes.config.Action
+	}
+	return metadata
+}
+
+// splitDeletedRows 分出源端标记为删除的行，返回要写入的行和删除行的主键；未按删除标记识别时原样返回
+func (fm *FieldMapper) splitDeletedRows(interfaceInfo InterfaceInfo, data []map[string]interface{}, primaryKeys []string) ([]map[string]interface{}, [][]interface{}) {
+	if fm.deletes == nil || fm.deletes.fullCompare() {
+		return data, nil
+	}
+	kept := make([]map[string]interface{}, 0, len(data))
+	var deleted []map[string]interface{}
+	for _, row := range data {
+		if fm.deletes.config.IsDeletedFlag(row[fm.deletes.config.FlagField]) {
+			deleted = append(deleted, row)
+		} else {
+			kept = append(kept, row)
+		}
+	}
+	if len(deleted) == 0 {
+		return kept, nil
+	}
+	return kept, fm.diffKeyValues(interfaceInfo, deleted, primaryKeys)
+}
+
+// recordSeenKeys 全量比对时记录写入后读到的主键，两边都来自to_jsonb，取值格式一致
+func (t *deleteTracker) recordSeenKeys(primaryKeys []string, rows []map[string]interface{}) {
+	if !t.fullCompare() {
+		return
+	}
+	for _, row := range rows {
+		t.seen[diffRowKey(row, primaryKeys)] = true
+	}
+}
+
+// finishBatchDeletes 在写入事务内处理本批的删除：软删除时清空重新出现记录的删除时间，删除标记的记录按配置处理
+func (fm *FieldMapper) finishBatchDeletes(ctx context.Context, tx *gorm.DB, interfaceInfo InterfaceInfo, fullTableName string, primaryKeys []string, written []map[string]interface{}, deleted [][]interface{}) error {
+	if fm.deletes == nil {
+		return nil
+	}
+	if fm.deletes.config.Action == models.DeleteActionSoftDelete && len(written) > 0 {
+		restored, err := restoreSoftDeleted(ctx, tx, fullTableName, primaryKeys, fm.diffKeyValues(interfaceInfo, written, primaryKeys))
+		if err != nil {
+			return err
+		}
+		fm.deletes.restored += restored
+	}
+	if len(deleted) == 0 {
+		return nil
+	}
+	return fm.applyDeletes(ctx, tx, fullTableName, primaryKeys, deleted)
+}
+
+// PropagateFullCompareDeletes 全量比对时在全部批次写入后处理接口表中不在源端的记录，其他识别方式直接返回
+func (fm *FieldMapper) PropagateFullCompareDeletes(ctx context.Context, db *gorm.DB, interfaceInfo InterfaceInfo) error {
+	if fm.deletes == nil || !fm.deletes.fullCompare() {
+		return nil
+	}
+	schemaName, tableName := interfaceInfo.GetSchemaName(), interfaceInfo.GetTableName()
+	fullTableName := utils.QuoteTable(schemaName, tableName)
+	if fm.deletes.incomplete {
+		slog.Warn("PropagateFullCompareDeletes - 有批次未读到写入后的主键，本次不处理删除", "table", fullTableName)
+		return nil
+	}
+	if len(fm.deletes.seen) == 0 {
+		slog.Warn("PropagateFullCompareDeletes - 源端没有返回数据，本次不处理删除", "table", fullTableName)
+		return nil
+	}
+
+	primaryKeys, err := fm.getPrimaryKeys(db, schemaName, tableName)
+	if err != nil || len(primaryKeys) == 0 {
+		return fmt.Errorf("表必须有主键才能同步源端删除")
+	}
+	query := fmt.Sprintf(`SELECT to_jsonb(k)::text FROM (SELECT %s FROM %s%s) k`,
+		utils.QuoteIdentList(primaryKeys), fullTableName, fm.deletes.activeCondition(" WHERE "))
+	existing, err := decodeDiffRows(db.WithContext(ctx), query, nil)
+	if err != nil {
+		return fmt.Errorf("读取接口表主键失败: %w", err)
+	}
+
+	var missing [][]interface{}
+	for _, row := range missingKeys(existing, primaryKeys, fm.deletes.seen) {
+		values := make([]interface{}, len(primaryKeys))
+		for i, pk := range primaryKeys {
+			values[i] = fm.convertValueByDataType(fmt.Sprintf("%v", row[pk]), fm.getFieldDataType(pk, interfaceInfo), pk, false)
+		}
+		missing = append(missing, values)
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+
+	tx := db.Begin()
+	if err := fm.applyDeletes(ctx, tx, fullTableName, primaryKeys, missing); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := tx.Commit().Error; err != nil {
+		return fmt.Errorf("提交删除同步事务失败: %w", err)
+	}
+	slog.Info("PropagateFullCompareDeletes - 全量比对删除完成", "table", fullTableName,
+		"source_rows", len(fm.deletes.seen), "missing", len(missing), "deleted", fm.deletes.deleted, "action", fm.deletes.config.Action)
+	return nil
+}
+
+// missingKeys 接口表中有、源端没有出现的主键
+func missingKeys(existing []map[string]interface{}, primaryKeys []string, seen map[string]bool) []map[string]interface{} {
+	var result []map[string]interface{}
+	for _, row := range existing {
+		if !seen[diffRowKey(row, primaryKeys)] {
+			result = append(result, row)
+		}
+	}
+	return result
+}
+
+// activeCondition 软删除时只处理未删除的记录，返回带前缀的条件，其他处理方式返回空
+func (t *deleteTracker) activeCondition(prefix string) string {
+	if t.config.Action != models.DeleteActionSoftDelete {
+		return ""
+	}
+	return fmt.Sprintf(`%s"%s" IS NULL`, prefix, models.SoftDeleteColumn)
+}
+
+// applyDeletes 按配置处理一组主键对应的记录，只统计接口表中实际存在的记录
+func (fm *FieldMapper) applyDeletes(ctx context.Context, tx *gorm.DB, fullTableName string, primaryKeys []string, keys [][]interface{}) error {
+	db := tx.WithContext(ctx)
+	config := fm.deletes.config
+	keyColumns := utils.QuoteIdentList(primaryKeys)
+	now := time.Now()
+
+	var archiveTable, archiveColumns string
+	if config.Action == models.DeleteActionArchive {
+		archiveTable = strings.TrimSuffix(fullTableName, `"`) + models.DeleteArchiveSuffix + `"`
+		columns, err := sharedColumns(db, fullTableName, archiveTable)
+		if err != nil {
+			return err
+		}
+		archiveColumns = utils.QuoteIdentList(columns)
+	}
+
+	for start := 0; start < len(keys); start += syncDiffSelectChunk {
+		end := start + syncDiffSelectChunk
+		if end > len(keys) {
+			end = len(keys)
+		}
+		match, args := keyInCondition(primaryKeys, keys[start:end])
+		match += fm.deletes.activeCondition(" AND ")
+
+		var found []map[string]interface{}
+		if err := db.Raw(fmt.Sprintf("SELECT %s FROM %s WHERE %s", keyColumns, fullTableName, match), args...).Scan(&found).Error; err != nil {
+			return fmt.Errorf("查询已删除记录失败: %w", err)
+		}
+		if len(found) == 0 {
+			continue
+		}
+
+		switch config.Action {
+		case models.DeleteActionSoftDelete:
+			query := fmt.Sprintf(`UPDATE %s SET "%s" = ? WHERE %s`, fullTableName, models.SoftDeleteColumn, match)
+			if err := db.Exec(query, append([]interface{}{now}, args...)...).Error; err != nil {
+				return fmt.Errorf("标记软删除失败: %w", err)
+			}
+		case models.DeleteActionArchive:
+			query := fmt.Sprintf(`INSERT INTO %s (%s, "%s") SELECT %s, ? FROM %s WHERE %s`,
+				archiveTable, archiveColumns, models.DeleteArchivedAtColumn, archiveColumns, fullTableName, match)
+			if err := db.Exec(query, append([]interface{}{now}, args...)...).Error; err != nil {
+				return fmt.Errorf("写入归档表失败: %w", err)
+			}
+			fallthrough
+		default:
+			if err := db.Exec(fmt.Sprintf("DELETE FROM %s WHERE %s", fullTableName, match), args...).Error; err != nil {
+				return fmt.Errorf("删除记录失败: %w", err)
+			}
+		}
+
+		fm.deletes.deleted += int64(len(found))
+		if fm.diff != nil {
+			fm.diff.report.Deleted += int64(len(found))
+			for _, key := range found {
+				fm.diff.report.DeletedKeys = fm.diff.appendKey(fm.diff.report.DeletedKeys, key)
+			}
+		}
+	}
+	return nil
+}
+
+// restoreSoftDeleted 清空重新出现的记录的删除时间，返回恢复的行数
+func restoreSoftDeleted(ctx context.Context, tx *gorm.DB, fullTableName string, primaryKeys []string, keys [][]interface{}) (int64, error) {
+	var restored int64
+	for start := 0; start < len(keys); start += syncDiffSelectChunk {
+		end := start + syncDiffSelectChunk
+		if end > len(keys) {
+			end = len(keys)
+		}
+		match, args := keyInCondition(primaryKeys, keys[start:end])
+		query := fmt.Sprintf(`UPDATE %s SET "%s" = NULL WHERE "%s" IS NOT NULL AND %s`,
+			fullTableName, models.SoftDeleteColumn, models.SoftDeleteColumn, match)
+		result := tx.WithContext(ctx).Exec(query, args...)
+		if result.Error != nil {
+			return 0, fmt.Errorf("恢复软删除记录失败: %w", result.Error)
+		}
+		restored += result.RowsAffected
+	}
+	return restored, nil
+}
+
+// keyInCondition 主键在给定取值中的条件，复合主键使用行值比较
+func keyInCondition(primaryKeys []string, keys [][]interface{}) (string, []interface{}) {
+	tuple := "(" + strings.TrimSuffix(strings.Repeat("?, ", len(primaryKeys)), ", ") + ")"
+	tuples := make([]string, len(keys))
+	args := make([]interface{}, 0, len(keys)*len(primaryKeys))
+	for i, key := range keys {
+		tuples[i] = tuple
+		args = append(args, key...)
+	}
+	return fmt.Sprintf("(%s) IN (%s)", utils.QuoteIdentList(primaryKeys), strings.Join(tuples, ", ")), args
+}
+
+// sharedColumns 接口表和归档表共有的列（不含归档时间列），按接口表的列顺序
+func sharedColumns(db *gorm.DB, fullTableName, archiveTable string) ([]string, error) {
+	source, err := selectColumns(db, fullTableName)
+	if err != nil {
+		return nil, fmt.Errorf("读取接口表列失败: %w", err)
+	}
+	archive, err := selectColumns(db, archiveTable)
+	if err != nil {
+		return nil, fmt.Errorf("读取归档表列失败，请重新保存删除同步配置: %w", err)
+	}
+	inArchive := make(map[string]bool, len(archive))
+	for _, column := range archive {
+		inArchive[column] = true
+	}
+	var columns []string
+	for _, column := range source {
+		if inArchive[column] && column != models.DeleteArchivedAtColumn {
+			columns = append(columns, column)
+		}
+	}
+	if len(columns) == 0 {
+		return nil, fmt.Errorf("接口表和归档表没有共有的列")
+	}
+	return columns, nil
+}
+
+// selectColumns 读取表的列名
+func selectColumns(db *gorm.DB, table string) ([]string, error) {
+	rows, err := db.Raw(fmt.Sprintf("SELECT * FROM %s WHERE 1 = 0", table)).Rows()
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return rows.Columns()
+}
@@ -0,0 +1,131 @@
+/*
+ * @module service/interface_executor/delete_propagation_test
+ * @description 源端删除同步测试，覆盖删除标记行的分离、硬删除、软删除及重新出现时恢复、移入归档表、删除行数统计和全量比对的缺失主键计算
+ * @architecture 测试层 - 单元测试
+ */
+
+package interface_executor
+
+import (
+	"context"
+	"datahub-service/service/models"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func setupDeletePropagationTest(t *testing.T, action string) (*gorm.DB, *MockInterfaceInfo, *FieldMapper) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.Exec(`CREATE TABLE people (id INTEGER PRIMARY KEY, name TEXT, _deleted_at DATETIME)`).Error)
+	require.NoError(t, db.Exec(`CREATE TABLE people_archive (id INTEGER, name TEXT, _deleted_at DATETIME, _archived_at DATETIME)`).Error)
+	require.NoError(t, db.Exec(`INSERT INTO people (id, name) VALUES (1, 'alice'), (2, 'bob'), (3, 'carol')`).Error)
+
+	info := &MockInterfaceInfo{}
+	info.On("GetID").Return("iface-people")
+	info.On("GetParseConfig").Return(map[string]interface{}{})
+	info.On("GetTableFieldsConfig").Return([]interface{}{
+		map[string]interface{}{"name_en": "id", "data_type": "integer", "is_primary_key": true, "order_num": 1},
+		map[string]interface{}{"name_en": "name", "data_type": "varchar", "order_num": 2},
+	})
+
+	fm := NewFieldMapper()
+	fm.EnableSyncDiff()
+	fm.EnableDeletePropagation(&models.DeletePropagationConfig{
+		Enabled: true, Detection: models.DeleteDetectionSourceFlag, FlagField: "is_deleted", Action: action,
+	})
+	return db, info, fm
+}
+
+// runFlagBatch 分出删除标记行，在事务内处理删除，返回写入的行
+func runFlagBatch(t *testing.T, db *gorm.DB, info *MockInterfaceInfo, fm *FieldMapper, rows []map[string]interface{}) []map[string]interface{} {
+	written, deleted := fm.splitDeletedRows(info, rows, []string{"id"})
+	tx := db.Begin()
+	require.NoError(t, fm.finishBatchDeletes(context.Background(), tx, info, `"people"`, []string{"id"}, written, deleted))
+	require.NoError(t, tx.Commit().Error)
+	return written
+}
+
+func TestDeletePropagationSourceFlag(t *testing.T) {
+	t.Run("硬删除", func(t *testing.T) {
+		db, info, fm := setupDeletePropagationTest(t, models.DeleteActionHardDelete)
+		written := runFlagBatch(t, db, info, fm, []map[string]interface{}{
+			{"id": 1, "name": "alice", "is_deleted": "0"},
+			{"id": 2, "name": "bob", "is_deleted": true},
+			{"id": 9, "name": "nobody", "is_deleted": "Y"}, // 接口表中不存在，不计数
+		})
+		assert.Len(t, written, 1)
+
+		var ids []int
+		require.NoError(t, db.Raw(`SELECT id FROM people ORDER BY id`).Scan(&ids).Error)
+		assert.Equal(t, []int{1, 3}, ids)
+		assert.Equal(t, int64(1), fm.SyncDiffReport().Deleted)
+		assert.Len(t, fm.SyncDiffReport().DeletedKeys, 1)
+		assert.Equal(t, int64(1), fm.attachDeleteReport(map[string]interface{}{})["deleted_rows"])
+	})
+
+	t.Run("软删除并在重新出现时恢复", func(t *testing.T) {
+		db, info, fm := setupDeletePropagationTest(t, models.DeleteActionSoftDelete)
+		runFlagBatch(t, db, info, fm, []map[string]interface{}{
+			{"id": 2, "is_deleted": "deleted"},
+			{"id": 3, "is_deleted": 1},
+		})
+		runFlagBatch(t, db, info, fm, []map[string]interface{}{
+			{"id": 2, "is_deleted": 1}, // 已软删除，不重复计数
+		})
+
+		var active []int
+		require.NoError(t, db.Raw(`SELECT id FROM people WHERE _deleted_at IS NULL ORDER BY id`).Scan(&active).Error)
+		assert.Equal(t, []int{1}, active)
+		assert.Equal(t, int64(2), fm.deletes.deleted)
+
+		runFlagBatch(t, db, info, fm, []map[string]interface{}{{"id": 3, "name": "carol", "is_deleted": nil}})
+		require.NoError(t, db.Raw(`SELECT id FROM people WHERE _deleted_at IS NULL ORDER BY id`).Scan(&active).Error)
+		assert.Equal(t, []int{1, 3}, active)
+		assert.Equal(t, int64(1), fm.deletes.restored)
+	})
+
+	t.Run("移入归档表", func(t *testing.T) {
+		db, info, fm := setupDeletePropagationTest(t, models.DeleteActionArchive)
+		runFlagBatch(t, db, info, fm, []map[string]interface{}{{"id": 3, "is_deleted": "true"}})
+
+		var remaining int64
+		require.NoError(t, db.Raw(`SELECT COUNT(*) FROM people`).Scan(&remaining).Error)
+		assert.Equal(t, int64(2), remaining)
+
+		var archived []struct {
+			ID       int
+			Name     string
+			Archived bool
+		}
+		require.NoError(t, db.Raw(`SELECT id, name, _archived_at IS NOT NULL AS archived FROM people_archive`).Scan(&archived).Error)
+		require.Len(t, archived, 1)
+		assert.Equal(t, 3, archived[0].ID)
+		assert.Equal(t, "carol", archived[0].Name)
+		assert.True(t, archived[0].Archived)
+	})
+}
+
+func TestDeletePropagationMissingKeys(t *testing.T) {
+	primaryKeys := []string{"org", "id"}
+	tracker := &deleteTracker{config: &models.DeletePropagationConfig{Detection: models.DeleteDetectionFullCompare}, seen: make(map[string]bool)}
+	tracker.recordSeenKeys(primaryKeys, []map[string]interface{}{
+		{"org": "a", "id": json.Number("1"), "name": "x"},
+		{"org": "b", "id": json.Number("1"), "name": "y"},
+	})
+
+	existing := []map[string]interface{}{
+		{"org": "a", "id": json.Number("1")},
+		{"org": "a", "id": json.Number("2")},
+		{"org": "b", "id": json.Number("1")},
+	}
+	assert.Equal(t, []map[string]interface{}{{"org": "a", "id": json.Number("2")}}, missingKeys(existing, primaryKeys, tracker.seen))
+
+	flag := &deleteTracker{config: &models.DeletePropagationConfig{Detection: models.DeleteDetectionSourceFlag, FlagField: "deleted"}, seen: make(map[string]bool)}
+	flag.recordSeenKeys(primaryKeys, existing)
+	assert.Empty(t, flag.seen, "按删除标记识别时不记录主键")
+}
@@ -3,10 +3,10 @@
  * @description 统一错误处理和事务管理工具
  * @architecture 责任链模式 - 提供分层的错误处理和恢复机制
  * @documentReference design.md
- * @stateFlow 错误捕获 -> 错误分类 -> 恢复策略 -> 事务回滚 -> 日志记录
+ * @stateFlow 错误捕获 -> 错误分类(含根因失败码) -> 恢复策略 -> 事务回滚 -> 日志记录
  * @rules 确保所有错误都有明确的处理策略，事务操作具有ACID特性
  * @dependencies gorm.io/gorm, context, time
- * @refs executor.go, data_sync_engine.go
+ * @refs executor.go, data_sync_engine.go, failure_classification.go
  */
 
 package interface_executor
@@ -50,6 +50,8 @@ const (
 // ErrorDetail 详细错误信息
 type ErrorDetail struct {
 	Type        ErrorType              `json:"type"`
+	Code        string                 `json:"code,omitempty"`  // 根因失败码，见FailureCodeCatalog
+	Class       FailureClass           `json:"class,omitempty"` // 根因分类
 	Severity    ErrorSeverity          `json:"severity"`
 	Message     string                 `json:"message"`
 	Cause       error                  `json:"cause,omitempty"`
@@ -89,6 +91,11 @@ func (h *ErrorHandler) HandleError(ctx context.Context, err error, errorType Err
 		Metadata:   make(map[string]interface{}),
 	}
 
+	// 根因分类，用于失败统计和处理建议
+	classification := h.Classify(err)
+	detail.Code = classification.Code
+	detail.Class = classification.Class
+
 	// 根据错误类型设置严重级别
 	detail.Severity = h.determineSeverity(err, errorType)
 	detail.Recoverable = h.isRecoverable(err, errorType)
@@ -104,6 +111,11 @@ func (h *ErrorHandler) HandleError(ctx context.Context, err error, errorType Err
 	return detail
 }
 
+// Classify 对错误进行根因分类，返回稳定的失败码
+func (h *ErrorHandler) Classify(err error) FailureClassification {
+	return ClassifyFailure(err)
+}
+
 // WrapWithTransaction 包装事务操作
 func (h *ErrorHandler) WrapWithTransaction(db *gorm.DB, operation func(tx *gorm.DB) error) error {
 	tx := db.Begin()
@@ -281,12 +293,13 @@ func (h *ErrorHandler) logError(detail *ErrorDetail) {
 		logLevel = "FATAL"
 	}
 
-	h.logger.Printf("[%s] %s - %s: %s (Context: %s, Recoverable: %v)",
+	h.logger.Printf("[%s] %s - %s: %s (Context: %s, Code: %s, Recoverable: %v)",
 		logLevel,
 		detail.Type,
 		detail.Severity,
 		detail.Message,
 		detail.Context,
+		detail.Code,
 		detail.Recoverable,
 	)
 
@@ -4,17 +4,19 @@
  * @architecture 策略模式 - 根据执行类型选择不同的执行策略
  * @documentReference ai_docs/interface_executor.md
  * @stateFlow 执行类型判断 -> 策略选择 -> 具体执行 -> 结果返回
- * @rules 每种执行类型都有明确的执行逻辑和返回格式
+ * @rules 每种执行类型都有明确的执行逻辑和返回格式；批量同步开启自适应调整时元数据batch_tuning记录拉取和写入批量大小的调整情况
  * @dependencies datahub-service/service/datasource, datahub-service/service/meta
- * @refs executor.go, data_processing.go
+ * @refs executor.go, data_processing.go, pipeline.go, batch_tuner.go
  */
 
 package interface_executor
 
 import (
 	"context"
+	"datahub-service/service/config"
 	"datahub-service/service/meta"
 	"datahub-service/service/models"
+	"errors"
 	"fmt"
 	"log/slog"
 	"time"
@@ -37,9 +39,25 @@ func (ops *ExecuteOperations) ExecutePreview(ctx context.Context, interfaceInfo
 	// 预览操作：调用一次接口，获取数据并返回
 	slog.Debug("ExecuteOperations.ExecutePreview - 开始预览接口", "value", interfaceInfo.GetID())
 
+	// 处理数据限制
+	limit := request.Limit
+	if limit == 0 {
+		limit = cast.ToInt(request.Parameters["limit"])
+	}
+	if limit <= 0 || limit > config.PreviewMaxLimit() {
+		limit = config.PreviewDefaultLimit()
+	}
+
+	// 行数限制随参数下推到数据源，只读取需要的行
+	parameters := make(map[string]interface{}, len(request.Parameters)+1)
+	for k, v := range request.Parameters {
+		parameters[k] = v
+	}
+	parameters["limit"] = limit
+
 	// 执行数据获取
 	dataProcessor := NewDataProcessor(ops.executor)
-	data, dataTypes, warnings, err := dataProcessor.FetchDataFromSourceWithExecuteType(ctx, interfaceInfo, request.Parameters, request.ExecuteType)
+	data, dataTypes, warnings, err := dataProcessor.FetchDataFromSourceWithExecuteType(ctx, interfaceInfo, parameters, request.ExecuteType)
 	if err != nil {
 		return &ExecuteResponse{
 			Success:     false,
@@ -50,16 +68,7 @@ func (ops *ExecuteOperations) ExecutePreview(ctx context.Context, interfaceInfo
 		}, err
 	}
 
-	// 处理数据限制
-	limit := request.Limit
-	if limit == 0 {
-		limit = cast.ToInt(request.Parameters["limit"])
-	}
-	if limit <= 0 || limit > 1000 {
-		limit = 10
-	}
-
-	// 限制返回的数据量
+	// 数据源不支持下推时仍在这里截断
 	limitedData := ops.limitDataRows(data, limit)
 
 	return &ExecuteResponse{
@@ -218,6 +227,14 @@ func (ops *ExecuteOperations) ExecuteSync(ctx context.Context, interfaceInfo Int
 		slog.Debug("ExecuteSync - 接口配置中没有增量配置，使用全量同步")
 	}
 
+	// 全量比对识别源端删除需要源端全量数据
+	if deletes := deletePropagationConfig(interfaceConfig); deletes != nil && deletes.Detection == models.DeleteDetectionFullCompare && syncStrategy == "incremental" {
+		slog.Info("ExecuteSync - 删除同步为全量比对，拉取源端全量数据", "interface_id", interfaceInfo.GetID())
+		syncStrategy = "full"
+		lastSyncValue = nil
+		incrementalKey = ""
+	}
+
 	// 2. 检查是否需要批量同步
 	limitConfig, hasLimitConfig := interfaceConfig[meta.DataInterfaceConfigFieldLimitConfig]
 	if hasLimitConfig {
@@ -245,10 +262,10 @@ func (ops *ExecuteOperations) ExecuteBatchSync(ctx context.Context, interfaceInf
 	maxLimit := cast.ToInt(limitConfig["max_limit"])
 
 	if defaultLimit <= 0 {
-		defaultLimit = 1000 // 默认每批1000条
+		defaultLimit = config.SyncDefaultBatchSize()
 	}
 	if maxLimit <= 0 {
-		maxLimit = 10000 // 默认最大10000条
+		maxLimit = config.SyncMaxBatchSize()
 	}
 
 	// 确保批量大小不超过最大限制
@@ -286,16 +303,6 @@ func (ops *ExecuteOperations) ExecuteBatchSync(ctx context.Context, interfaceInf
 	fullTableName := fmt.Sprintf(`"%s"."%s"`, interfaceInfo.GetSchemaName(), interfaceInfo.GetTableName())
 	slog.Debug("ExecuteBatchSync - 开始流式批量同步，目标表", "value", fullTableName)
 
-	// 批量数据同步
-	dataProcessor := NewDataProcessor(ops.executor)
-	fieldMapper := NewFieldMapper()
-
-	var totalRows int64 = 0
-	var allDataTypes map[string]string
-	var allWarnings []string
-	currentPage := 1
-	hasMoreData := true
-
 	// 先清空目标表（全量同步）
 	slog.Debug("ExecuteBatchSync - 清空表", "value", fullTableName)
 	if err := ops.executor.db.Exec(fmt.Sprintf("DELETE FROM %s", fullTableName)).Error; err != nil {
@@ -330,140 +337,84 @@ func (ops *ExecuteOperations) ExecuteBatchSync(ctx context.Context, interfaceInf
 			startPage = 1
 		}
 
-		currentPage = startPage
 		slog.Debug("ExecuteBatchSync - API分页配置", "page_param", pageParamName, "size_param", sizeParamName, "start_page", startPage)
 	} else {
 		// 数据库类型：使用标准分页参数
 		pageParamName = "page"
 		sizeParamName = "page_size"
 		startPage = 1
-		currentPage = startPage
 		slog.Debug("ExecuteBatchSync - 数据库分页配置", "page_param", pageParamName, "size_param", sizeParamName, "start_page", startPage)
 	}
 
-	for hasMoreData {
-		slog.Debug("ExecuteBatchSync - 处理批次", "page", currentPage, "batch_size", batchSize)
-
-		// 构建分页参数
-		pageParams := map[string]interface{}{
-			pageParamName: currentPage,
-			sizeParamName: batchSize,
-		}
-
-		// 获取批量数据
-		batchData, dataTypes, warnings, err := dataProcessor.FetchBatchDataFromSource(ctx, interfaceInfo, request.Parameters, pageParams)
-		if err != nil {
-			slog.Error("ExecuteBatchSync - 获取批数据失败", "page", currentPage, "error", err)
-			return &ExecuteResponse{
-				Success:     false,
-				Message:     fmt.Sprintf("获取第 %d 批数据失败", currentPage),
-				Duration:    time.Since(startTime).Milliseconds(),
-				ExecuteType: request.ExecuteType,
-				Error:       err.Error(),
-			}, err
-		}
-
-		// 记录数据类型（使用第一批的数据类型）
-		if allDataTypes == nil {
-			allDataTypes = dataTypes
-		}
-
-		// 合并警告
-		allWarnings = append(allWarnings, warnings...)
-
-		// 判断是否还有更多数据
-		if len(batchData) == 0 {
-			slog.Debug("ExecuteBatchSync - 批次没有数据，结束同步", "batch", currentPage)
-			hasMoreData = false
-			break
-		}
-
-		// 流式处理：立即插入当前批次数据，不累积在内存中
-		slog.Debug("ExecuteBatchSync - 开始插入批次数据", "batch", currentPage, "batch_count", len(batchData))
-
-		// 为每批开启独立事务，提高稳定性和错误恢复能力
-		tx := ops.executor.db.Begin()
-		if tx.Error != nil {
-			slog.Error("ExecuteBatchSync - 开始批次事务失败", "batch", currentPage, "error", tx.Error)
-			return &ExecuteResponse{
-				Success:     false,
-				Message:     fmt.Sprintf("第 %d 批开始事务失败", currentPage),
-				Duration:    time.Since(startTime).Milliseconds(),
-				ExecuteType: request.ExecuteType,
-				Error:       tx.Error.Error(),
-			}, tx.Error
-		}
-
-		batchRows, err := fieldMapper.InsertBatchDataWithTx(ctx, tx, interfaceInfo, batchData)
-		if err != nil {
-			slog.Error("ExecuteBatchSync - 插入批次数据失败", "batch", currentPage, "error", err)
-			tx.Rollback()
-			return &ExecuteResponse{
-				Success:     false,
-				Message:     fmt.Sprintf("插入第 %d 批数据失败", currentPage),
-				Duration:    time.Since(startTime).Milliseconds(),
-				ExecuteType: request.ExecuteType,
-				Error:       err.Error(),
-			}, err
-		}
-
-		// 提交批次事务
-		if err := tx.Commit().Error; err != nil {
-			slog.Error("ExecuteBatchSync - 提交批次事务失败", "batch", currentPage, "error", err)
-			return &ExecuteResponse{
-				Success:     false,
-				Message:     fmt.Sprintf("提交第 %d 批事务失败", currentPage),
-				Duration:    time.Since(startTime).Milliseconds(),
-				ExecuteType: request.ExecuteType,
-				Error:       err.Error(),
-			}, err
-		}
-
-		totalRows += batchRows
-		slog.Debug("ExecuteBatchSync - 批次处理完成", "batch", currentPage, "batch_rows", batchRows, "total_rows", totalRows)
-
-		// 显式释放批次数据，帮助GC回收内存
-		batchData = nil
-
-		// 判断是否有更多数据的逻辑
-		if batchRows < int64(batchSize) {
-			slog.Debug("ExecuteBatchSync - 批次数据不足，停止同步", "batch", currentPage, "batch_size", batchSize)
-			hasMoreData = false
-		}
-
-		currentPage++
+	// 流水线批量同步：拉取下一页与写入当前批次并发进行，每批独立事务
+	dataProcessor := NewDataProcessor(ops.executor)
+	fieldMapper := ops.newSyncFieldMapper(interfaceInfo, request, startTime)
+	fetchTuner, writeTuner := newBatchTuners(limitConfig, batchSize, maxLimit)
+	pipeline := &syncPipeline{
+		BatchSize: batchSize,
+		Buffer:    config.SyncPipelineBuffer(),
+		StartPage: startPage,
+		MaxPages:  config.SyncMaxBatches(),
+		Fetch: func(ctx context.Context, page, size int) (*syncPage, error) {
+			slog.Debug("ExecuteBatchSync - 拉取批次", "page", page, "batch_size", size)
+			pageParams := map[string]interface{}{
+				pageParamName: page,
+				sizeParamName: size,
+			}
+			rows, dataTypes, warnings, err := dataProcessor.FetchBatchDataFromSource(ctx, interfaceInfo, request.Parameters, pageParams)
+			if err != nil {
+				return nil, err
+			}
+			return &syncPage{Rows: rows, DataTypes: dataTypes, Warnings: warnings}, nil
+		},
+		Write: func(ctx context.Context, batch int, rows []map[string]interface{}) (int64, error) {
+			return ops.writeBatchInTx(ctx, fieldMapper, interfaceInfo, batch, rows, false)
+		},
+		FetchTuner: fetchTuner,
+		WriteTuner: writeTuner,
+	}
 
-		// 防止无限循环
-		if currentPage > 1000 {
-			slog.Warn("ExecuteBatchSync - 达到最大批次限制(1000)，停止数据同步")
-			allWarnings = append(allWarnings, "达到最大批次限制，可能还有更多数据未同步")
-			break
-		}
+	result, err := pipeline.Run(ctx)
+	if err != nil {
+		slog.Error("ExecuteBatchSync - 批量同步失败", "error", err)
+		return batchSyncErrorResponse(request, startTime, err), err
+	}
+	totalRows := result.TotalRows
+	if err := fieldMapper.PropagateFullCompareDeletes(ctx, ops.executor.db, interfaceInfo); err != nil {
+		return &ExecuteResponse{
+			Success:     false,
+			Message:     "同步源端删除失败",
+			Duration:    time.Since(startTime).Milliseconds(),
+			ExecuteType: request.ExecuteType,
+			Error:       err.Error(),
+		}, err
 	}
 
-	slog.Debug("ExecuteBatchSync - 流式同步完成", "total_batches", currentPage-1, "total_rows", totalRows)
+	slog.Debug("ExecuteBatchSync - 流式同步完成", "total_pages", result.Pages, "total_batches", result.Batches, "total_rows", totalRows)
 
 	return &ExecuteResponse{
 		Success:      true,
-		Message:      fmt.Sprintf("批量数据同步成功，处理 %d 批", currentPage-1),
+		Message:      fmt.Sprintf("批量数据同步成功，处理 %d 批", result.Batches),
 		Duration:     time.Since(startTime).Milliseconds(),
 		ExecuteType:  request.ExecuteType,
 		RowCount:     int(totalRows),
-		ColumnCount:  len(allDataTypes),
-		DataTypes:    allDataTypes,
+		ColumnCount:  len(result.DataTypes),
+		DataTypes:    result.DataTypes,
 		TableUpdated: true,
 		UpdatedRows:  totalRows,
-		Warnings:     allWarnings,
-		Metadata: map[string]interface{}{
+		Warnings:     append(result.Warnings, fieldMapper.FieldValidationWarnings()...),
+		Metadata: fieldMapper.attachContractReport(map[string]interface{}{
 			"interface_id":   interfaceInfo.GetID(),
 			"interface_name": interfaceInfo.GetName(),
 			"schema_name":    interfaceInfo.GetSchemaName(),
 			"table_name":     interfaceInfo.GetTableName(),
-			"batch_count":    currentPage - 1,
+			"batch_count":    result.Batches,
+			"page_count":     result.Pages,
 			"batch_size":     batchSize,
+			"batch_tuning":   batchTuningMetadata(fetchTuner, writeTuner),
 			"total_rows":     totalRows,
 			"transaction":    "committed",
-		},
+		}),
 	}, nil
 }
 
@@ -565,22 +516,30 @@ func (ops *ExecuteOperations) ExecuteSingleSync(ctx context.Context, interfaceIn
 				"sync_strategy":   syncStrategy,
 				"last_sync_value": lastSyncValue,
 				"incremental_key": incrementalKey,
+				"diff":            newSyncDiffReport(),
 			},
 		}, nil
 	}
 
 	// 更新表数据
-	fieldMapper := NewFieldMapper()
+	fieldMapper := ops.newSyncFieldMapper(interfaceInfo, request, startTime)
+	if syncStrategy == "incremental" || fieldMapper.keepsExistingRows() {
+		fieldMapper.EnableSyncDiff()
+	}
 	var updatedRows int64
 
-	if syncStrategy == "full" {
+	if syncStrategy == "full" && !fieldMapper.keepsExistingRows() {
 		// 全量同步：先清空表，再插入新数据
 		updatedRows, err = fieldMapper.ReplaceTableData(ctx, ops.executor.db, interfaceInfo, data)
 	} else {
-		// 增量同步：使用真正的UPSERT操作（插入或更新，不删除现有数据）
+		// 增量同步、历史追踪或删除同步：使用真正的UPSERT操作（插入或更新，不清空现有数据）
 		updatedRows, err = fieldMapper.UpsertTableData(ctx, ops.executor.db, interfaceInfo, data)
+		if err == nil {
+			err = fieldMapper.PropagateFullCompareDeletes(ctx, ops.executor.db, interfaceInfo)
+		}
 	}
 
+	warnings = append(warnings, fieldMapper.FieldValidationWarnings()...)
 	if err != nil {
 		return &ExecuteResponse{
 			Success:     false,
@@ -603,7 +562,7 @@ func (ops *ExecuteOperations) ExecuteSingleSync(ctx context.Context, interfaceIn
 		TableUpdated: true,
 		UpdatedRows:  updatedRows,
 		Warnings:     warnings,
-		Metadata: map[string]interface{}{
+		Metadata: fieldMapper.attachDeleteReport(fieldMapper.attachDiffReport(fieldMapper.attachContractReport(map[string]interface{}{
 			"interface_id":    interfaceInfo.GetID(),
 			"interface_name":  interfaceInfo.GetName(),
 			"schema_name":     interfaceInfo.GetSchemaName(),
@@ -611,7 +570,7 @@ func (ops *ExecuteOperations) ExecuteSingleSync(ctx context.Context, interfaceIn
 			"sync_strategy":   syncStrategy,
 			"last_sync_value": lastSyncValue,
 			"incremental_key": incrementalKey,
-		},
+		}))),
 	}, nil
 }
 
@@ -628,10 +587,10 @@ func (ops *ExecuteOperations) ExecuteBatchSyncWithStrategy(ctx context.Context,
 	maxLimit := cast.ToInt(limitConfig["max_limit"])
 
 	if defaultLimit <= 0 {
-		defaultLimit = 1000
+		defaultLimit = config.SyncDefaultBatchSize()
 	}
 	if maxLimit <= 0 {
-		maxLimit = 10000
+		maxLimit = config.SyncMaxBatchSize()
 	}
 
 	batchSize := defaultLimit
@@ -667,9 +626,13 @@ func (ops *ExecuteOperations) ExecuteBatchSyncWithStrategy(ctx context.Context,
 
 	slog.Debug("ExecuteBatchSyncWithStrategy - 最终同步参数", "sync_params", syncParams)
 
-	// 如果是全量同步，先清空表（在事务外执行）
+	// 如果是全量同步，先清空表（在事务外执行），历史追踪和删除同步模式不清空
+	fieldMapper := ops.newSyncFieldMapper(interfaceInfo, request, startTime)
+	if syncStrategy == "incremental" || fieldMapper.keepsExistingRows() {
+		fieldMapper.EnableSyncDiff()
+	}
 	fullTableName := fmt.Sprintf(`"%s"."%s"`, interfaceInfo.GetSchemaName(), interfaceInfo.GetTableName())
-	if syncStrategy == "full" {
+	if syncStrategy == "full" && !fieldMapper.keepsExistingRows() {
 		slog.Debug("ExecuteBatchSyncWithStrategy - 清空表", "value", fullTableName)
 		if err := ops.executor.db.Exec(fmt.Sprintf("DELETE FROM %s", fullTableName)).Error; err != nil {
 			return &ExecuteResponse{
@@ -682,119 +645,62 @@ func (ops *ExecuteOperations) ExecuteBatchSyncWithStrategy(ctx context.Context,
 		}
 	}
 
-	// 流式批量获取并处理数据
+	// 流水线批量获取并处理数据
 	dataProcessor := NewDataProcessor(ops.executor)
-	fieldMapper := NewFieldMapper()
-
-	var totalRows int64
-	var allDataTypes map[string]string
-	var allWarnings []string
-	currentPage := 1
-	hasMoreData := true
-
-	for hasMoreData {
-		pageParams := map[string]interface{}{
-			"page":      currentPage,
-			"page_size": batchSize,
-		}
-
-		batchData, dataTypes, warnings, err := dataProcessor.FetchBatchDataFromSourceWithStrategy(ctx, interfaceInfo, syncParams, pageParams, syncStrategy)
-		if err != nil {
-			return &ExecuteResponse{
-				Success:     false,
-				Message:     fmt.Sprintf("获取第 %d 批数据失败", currentPage),
-				Duration:    time.Since(startTime).Milliseconds(),
-				ExecuteType: request.ExecuteType,
-				Error:       err.Error(),
-			}, err
-		}
-
-		if allDataTypes == nil {
-			allDataTypes = dataTypes
-		}
-		allWarnings = append(allWarnings, warnings...)
-
-		if len(batchData) == 0 {
-			hasMoreData = false
-			break
-		}
-
-		// 流式处理：为每批数据开启独立事务，立即处理
-		slog.Debug("ExecuteBatchSyncWithStrategy - 处理批次", "batch", currentPage, "batch_count", len(batchData), "strategy", syncStrategy)
-
-		tx := ops.executor.db.Begin()
-		if tx.Error != nil {
-			return &ExecuteResponse{
-				Success:     false,
-				Message:     fmt.Sprintf("第 %d 批开始事务失败", currentPage),
-				Duration:    time.Since(startTime).Milliseconds(),
-				ExecuteType: request.ExecuteType,
-				Error:       tx.Error.Error(),
-			}, tx.Error
-		}
-
-		// 根据策略处理数据
-		var batchRows int64
-		if syncStrategy == "full" {
-			batchRows, err = fieldMapper.InsertBatchDataWithTx(ctx, tx, interfaceInfo, batchData)
-		} else {
-			batchRows, err = fieldMapper.UpsertBatchDataWithTx(ctx, tx, interfaceInfo, batchData)
-		}
-
-		if err != nil {
-			tx.Rollback()
-			return &ExecuteResponse{
-				Success:     false,
-				Message:     fmt.Sprintf("处理第 %d 批数据失败", currentPage),
-				Duration:    time.Since(startTime).Milliseconds(),
-				ExecuteType: request.ExecuteType,
-				Error:       err.Error(),
-			}, err
-		}
-
-		// 提交批次事务
-		if err := tx.Commit().Error; err != nil {
-			return &ExecuteResponse{
-				Success:     false,
-				Message:     fmt.Sprintf("提交第 %d 批事务失败", currentPage),
-				Duration:    time.Since(startTime).Milliseconds(),
-				ExecuteType: request.ExecuteType,
-				Error:       err.Error(),
-			}, err
-		}
-
-		totalRows += batchRows
-		slog.Debug("ExecuteBatchSyncWithStrategy - 批次完成", "batch", currentPage, "batch_rows", batchRows, "total_rows", totalRows)
-
-		// 显式释放批次数据，帮助GC
-		batchData = nil
-
-		if batchRows < int64(batchSize) {
-			hasMoreData = false
-		}
-
-		currentPage++
+	fetchTuner, writeTuner := newBatchTuners(limitConfig, batchSize, maxLimit)
+	pipeline := &syncPipeline{
+		BatchSize: batchSize,
+		Buffer:    config.SyncPipelineBuffer(),
+		StartPage: 1,
+		MaxPages:  config.SyncMaxBatches(),
+		Fetch: func(ctx context.Context, page, size int) (*syncPage, error) {
+			pageParams := map[string]interface{}{
+				"page":      page,
+				"page_size": size,
+			}
+			rows, dataTypes, warnings, err := dataProcessor.FetchBatchDataFromSourceWithStrategy(ctx, interfaceInfo, syncParams, pageParams, syncStrategy)
+			if err != nil {
+				return nil, err
+			}
+			return &syncPage{Rows: rows, DataTypes: dataTypes, Warnings: warnings}, nil
+		},
+		Write: func(ctx context.Context, batch int, rows []map[string]interface{}) (int64, error) {
+			slog.Debug("ExecuteBatchSyncWithStrategy - 处理批次", "batch", batch, "batch_count", len(rows), "strategy", syncStrategy)
+			return ops.writeBatchInTx(ctx, fieldMapper, interfaceInfo, batch, rows, syncStrategy != "full" || fieldMapper.keepsExistingRows())
+		},
+		FetchTuner: fetchTuner,
+		WriteTuner: writeTuner,
+	}
 
-		if currentPage > 1000 {
-			allWarnings = append(allWarnings, "达到最大批次限制，可能还有更多数据未同步")
-			break
-		}
+	result, err := pipeline.Run(ctx)
+	if err != nil {
+		return batchSyncErrorResponse(request, startTime, err), err
+	}
+	totalRows := result.TotalRows
+	if err := fieldMapper.PropagateFullCompareDeletes(ctx, ops.executor.db, interfaceInfo); err != nil {
+		return &ExecuteResponse{
+			Success:     false,
+			Message:     "同步源端删除失败",
+			Duration:    time.Since(startTime).Milliseconds(),
+			ExecuteType: request.ExecuteType,
+			Error:       err.Error(),
+		}, err
 	}
 
-	slog.Debug("ExecuteBatchSyncWithStrategy - 流式同步完成", "total_batches", currentPage-1, "total_rows", totalRows, "strategy", syncStrategy)
+	slog.Debug("ExecuteBatchSyncWithStrategy - 流式同步完成", "total_pages", result.Pages, "total_batches", result.Batches, "total_rows", totalRows, "strategy", syncStrategy)
 
 	return &ExecuteResponse{
 		Success:      true,
-		Message:      fmt.Sprintf("批量%s同步成功，处理 %d 批", map[string]string{"full": "全量", "incremental": "增量"}[syncStrategy], currentPage-1),
+		Message:      fmt.Sprintf("批量%s同步成功，处理 %d 批", map[string]string{"full": "全量", "incremental": "增量"}[syncStrategy], result.Batches),
 		Duration:     time.Since(startTime).Milliseconds(),
 		ExecuteType:  request.ExecuteType,
 		RowCount:     int(totalRows),
-		ColumnCount:  len(allDataTypes),
-		DataTypes:    allDataTypes,
+		ColumnCount:  len(result.DataTypes),
+		DataTypes:    result.DataTypes,
 		TableUpdated: true,
 		UpdatedRows:  totalRows,
-		Warnings:     allWarnings,
-		Metadata: map[string]interface{}{
+		Warnings:     append(result.Warnings, fieldMapper.FieldValidationWarnings()...),
+		Metadata: fieldMapper.attachDeleteReport(fieldMapper.attachDiffReport(fieldMapper.attachContractReport(map[string]interface{}{
 			"interface_id":    interfaceInfo.GetID(),
 			"interface_name":  interfaceInfo.GetName(),
 			"schema_name":     interfaceInfo.GetSchemaName(),
@@ -802,13 +708,39 @@ func (ops *ExecuteOperations) ExecuteBatchSyncWithStrategy(ctx context.Context,
 			"sync_strategy":   syncStrategy,
 			"last_sync_value": lastSyncValue,
 			"incremental_key": incrementalKey,
-			"batch_count":     currentPage - 1,
+			"batch_count":     result.Batches,
+			"page_count":      result.Pages,
 			"batch_size":      batchSize,
+			"batch_tuning":    batchTuningMetadata(fetchTuner, writeTuner),
 			"total_rows":      totalRows,
-		},
+		}))),
 	}, nil
 }
 
+// newSyncFieldMapper 创建同步写入用的字段映射器，接口开启历史追踪时按自然键合并版本，开启删除同步时处理源端删除，开启来源追溯时填写系统列，配置了启用的数据契约时写入前按契约校验
+func (ops *ExecuteOperations) newSyncFieldMapper(interfaceInfo InterfaceInfo, request *ExecuteRequest, startTime time.Time) *FieldMapper {
+	fieldMapper := NewFieldMapper()
+	if scd := models.ParseSCDConfig(interfaceInfo.GetInterfaceConfig()); scd != nil {
+		fieldMapper.EnableSCD(scd)
+	}
+	if deletes := deletePropagationConfig(interfaceInfo.GetInterfaceConfig()); deletes != nil {
+		fieldMapper.EnableDeletePropagation(deletes)
+	}
+	if models.ParseProvenanceConfig(interfaceInfo.GetInterfaceConfig()) != nil {
+		fieldMapper.EnableProvenance(interfaceInfo.GetDataSourceID(), request.ExecutionID, startTime)
+	}
+	var contract models.InterfaceContract
+	err := ops.executor.db.Where("interface_id = ? AND enabled = ?", interfaceInfo.GetID(), true).Limit(1).Find(&contract).Error
+	if err != nil {
+		slog.Warn("newSyncFieldMapper - 查询数据契约失败，本次同步不做契约校验", "interface_id", interfaceInfo.GetID(), "error", err)
+		return fieldMapper
+	}
+	if contract.InterfaceID != "" {
+		fieldMapper.SetContract(&contract)
+	}
+	return fieldMapper
+}
+
 // limitDataRows 限制数据行数
 func (ops *ExecuteOperations) limitDataRows(data []map[string]interface{}, limit int) []map[string]interface{} {
 	if len(data) <= limit {
@@ -816,3 +748,44 @@ func (ops *ExecuteOperations) limitDataRows(data []map[string]interface{}, limit
 	}
 	return data[:limit]
 }
+
+// writeBatchInTx 在独立事务中写入一批数据，upsert为true时按主键插入或更新
+func (ops *ExecuteOperations) writeBatchInTx(ctx context.Context, fieldMapper *FieldMapper, interfaceInfo InterfaceInfo, batch int, rows []map[string]interface{}, upsert bool) (int64, error) {
+	tx := ops.executor.db.Begin()
+	if tx.Error != nil {
+		return 0, &syncPipelineError{Message: fmt.Sprintf("第 %d 批开始事务失败", batch), Err: tx.Error}
+	}
+
+	var written int64
+	var err error
+	if upsert {
+		written, err = fieldMapper.UpsertBatchDataWithTx(ctx, tx, interfaceInfo, rows)
+	} else {
+		written, err = fieldMapper.InsertBatchDataWithTx(ctx, tx, interfaceInfo, rows)
+	}
+	if err != nil {
+		tx.Rollback()
+		return 0, &syncPipelineError{Message: fmt.Sprintf("处理第 %d 批数据失败", batch), Err: err}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return 0, &syncPipelineError{Message: fmt.Sprintf("提交第 %d 批事务失败", batch), Err: err}
+	}
+	return written, nil
+}
+
+// batchSyncErrorResponse 将流水线错误转换为执行响应
+func batchSyncErrorResponse(request *ExecuteRequest, startTime time.Time, err error) *ExecuteResponse {
+	message, cause := "批量同步失败", err
+	var pipelineErr *syncPipelineError
+	if errors.As(err, &pipelineErr) {
+		message, cause = pipelineErr.Message, pipelineErr.Err
+	}
+	return &ExecuteResponse{
+		Success:     false,
+		Message:     message,
+		Duration:    time.Since(startTime).Milliseconds(),
+		ExecuteType: request.ExecuteType,
+		Error:       cause.Error(),
+	}
+}
@@ -14,6 +14,7 @@ package interface_executor
 import (
 	"context"
 	"datahub-service/service/datasource"
+	"datahub-service/service/tracing"
 	"fmt"
 	"time"
 
@@ -52,7 +53,8 @@ type ExecuteRequest struct {
 	SyncStrategy  string                 `json:"sync_strategy,omitempty"` // full, incremental (仅当ExecuteType=sync时使用)
 	Parameters    map[string]interface{} `json:"parameters,omitempty"`
 	Options       map[string]interface{} `json:"options,omitempty"`
-	Limit         int                    `json:"limit,omitempty"` // 用于预览时限制数据量
+	Limit         int                    `json:"limit,omitempty"`        // 用于预览时限制数据量
+	ExecutionID   string                 `json:"execution_id,omitempty"` // 同步任务执行ID，开启来源追溯时写入_sync_execution_id
 	// 已废弃字段（向后兼容，系统将自动从配置中获取）:
 	// - LastSyncTime/LastSyncValue: 由系统根据incremental_config自动查询
 	// - IncrementalKey/IncrementalField: 从incremental_config.incremental_field读取
@@ -76,8 +78,28 @@ type ExecuteResponse struct {
 	Metadata     map[string]interface{} `json:"metadata,omitempty"`
 }
 
-// Execute 执行接口操作
+// Execute 执行接口操作，记录执行器链路Span
 func (e *InterfaceExecutor) Execute(ctx context.Context, request *ExecuteRequest) (*ExecuteResponse, error) {
+	ctx, span := tracing.Start(ctx, "interface_executor.execute")
+	defer span.End()
+	span.SetAttribute("interface.id", request.InterfaceID)
+	span.SetAttribute("interface.type", request.InterfaceType)
+	span.SetAttribute("execute.type", request.ExecuteType)
+
+	response, err := e.execute(ctx, request)
+	if err != nil {
+		span.RecordError(err)
+	} else if response != nil && !response.Success {
+		span.SetError("%s", response.Error)
+	}
+	if response != nil {
+		span.SetAttribute("sync.updated_rows", response.UpdatedRows)
+	}
+	return response, err
+}
+
+// execute 执行接口操作
+func (e *InterfaceExecutor) execute(ctx context.Context, request *ExecuteRequest) (*ExecuteResponse, error) {
 	startTime := time.Now()
 
 	// 验证请求参数
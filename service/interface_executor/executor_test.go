@@ -429,7 +429,15 @@ func (suite *InterfaceExecutorTestSuite) SetupSuite() {
 			created_at TEXT,
 			created_by TEXT,
 			updated_at TEXT,
-			updated_by TEXT
+			updated_by TEXT,
+			maintenance_mode INTEGER NOT NULL DEFAULT 0,
+			maintenance_reason TEXT,
+			maintenance_since TEXT,
+			maintenance_by TEXT,
+			circuit_state TEXT NOT NULL DEFAULT 'closed',
+			circuit_failures INTEGER NOT NULL DEFAULT 0,
+			circuit_since TEXT,
+			circuit_last_error TEXT
 		)
 	`).Error
 	suite.Require().NoError(err)
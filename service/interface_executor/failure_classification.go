@@ -0,0 +1,280 @@
+/*
+ * @module service/interface_executor/failure_classification
+ * @description 同步失败根因分类，把接口调用和数据写入错误归为认证、网络、表结构、数据类型、约束、配额几类，并给出稳定的失败码和处理建议
+ * @architecture 分层架构 - 执行器工具
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow 错误 -> 数据库SQLSTATE / 网络错误类型 / HTTP状态码 / 关键字 -> 失败码 -> 分类和处理建议
+ * @rules 失败码一经发布不修改含义，新增失败码需同时加入FailureCodeCatalog；优先使用结构化信息(SQLSTATE、net.Error)，无法识别时再按错误信息关键字匹配
+ * @dependencies errors, net, regexp
+ * @refs error_handler.go, service/basic_library/failure_analysis_service.go
+ */
+
+package interface_executor
+
+import (
+	"context"
+	"errors"
+	"net"
+	"regexp"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+// FailureClass 同步失败根因分类
+type FailureClass string
+
+const (
+	FailureClassAuth       FailureClass = "auth"       // 认证授权
+	FailureClassNetwork    FailureClass = "network"    // 网络和源系统可用性
+	FailureClassSchema     FailureClass = "schema"     // 表结构不一致
+	FailureClassDataType   FailureClass = "data_type"  // 数据类型或格式不匹配
+	FailureClassConstraint FailureClass = "constraint" // 违反数据库约束
+	FailureClassQuota      FailureClass = "quota"      // 限流、连接数或存储配额
+	FailureClassUnknown    FailureClass = "unknown"    // 无法识别
+)
+
+// FailureClassNames 失败分类中文名称
+var FailureClassNames = map[FailureClass]string{
+	FailureClassAuth:       "认证授权失败",
+	FailureClassNetwork:    "网络或源系统不可用",
+	FailureClassSchema:     "表结构不一致",
+	FailureClassDataType:   "数据类型不匹配",
+	FailureClassConstraint: "违反数据约束",
+	FailureClassQuota:      "超出配额或限流",
+	FailureClassUnknown:    "未识别错误",
+}
+
+// 失败码
+const (
+	FailureCodeAuthInvalidCredentials = "SYNC_AUTH_INVALID_CREDENTIALS"
+	FailureCodeAuthForbidden          = "SYNC_AUTH_FORBIDDEN"
+	FailureCodeNetworkTimeout         = "SYNC_NETWORK_TIMEOUT"
+	FailureCodeNetworkUnreachable     = "SYNC_NETWORK_UNREACHABLE"
+	FailureCodeNetworkReset           = "SYNC_NETWORK_RESET"
+	FailureCodeSourceUnavailable      = "SYNC_SOURCE_UNAVAILABLE"
+	FailureCodeSchemaTableMissing     = "SYNC_SCHEMA_TABLE_MISSING"
+	FailureCodeSchemaColumnMissing    = "SYNC_SCHEMA_COLUMN_MISSING"
+	FailureCodeDataTypeInvalid        = "SYNC_DATA_TYPE_INVALID"
+	FailureCodeDataTypeOverflow       = "SYNC_DATA_TYPE_OVERFLOW"
+	FailureCodeDataTooLong            = "SYNC_DATA_TOO_LONG"
+	FailureCodeContractViolation      = "SYNC_CONTRACT_VIOLATION"
+	FailureCodeConstraintUnique       = "SYNC_CONSTRAINT_UNIQUE"
+	FailureCodeConstraintNotNull      = "SYNC_CONSTRAINT_NOT_NULL"
+	FailureCodeConstraintForeignKey   = "SYNC_CONSTRAINT_FOREIGN_KEY"
+	FailureCodeConstraintCheck        = "SYNC_CONSTRAINT_CHECK"
+	FailureCodeQuotaRateLimited       = "SYNC_QUOTA_RATE_LIMITED"
+	FailureCodeQuotaConnections       = "SYNC_QUOTA_CONNECTIONS"
+	FailureCodeQuotaStorage           = "SYNC_QUOTA_STORAGE"
+	FailureCodeUnknown                = "SYNC_UNKNOWN"
+)
+
+// FailureCodeInfo 失败码说明
+type FailureCodeInfo struct {
+	Code        string       `json:"code" example:"SYNC_CONSTRAINT_UNIQUE"`
+	Class       FailureClass `json:"class" example:"constraint"`
+	Description string       `json:"description"`
+	Remediation string       `json:"remediation"` // 处理建议
+	Retryable   bool         `json:"retryable"`   // 不做任何修改重试是否可能成功
+}
+
+// FailureCodeCatalog 失败码目录
+var FailureCodeCatalog = []FailureCodeInfo{
+	{FailureCodeAuthInvalidCredentials, FailureClassAuth, "源系统认证失败（401或凭证无效）", "检查数据源的用户名、密码、令牌或AppKey是否过期或被修改，更新数据源连接配置后重新测试连接", false},
+	{FailureCodeAuthForbidden, FailureClassAuth, "源系统拒绝访问（403或权限不足）", "确认数据源账号对目标接口或表有读取权限，必要时联系源系统管理员授权", false},
+	{FailureCodeNetworkTimeout, FailureClassNetwork, "请求或查询超时", "检查网络延迟和源系统负载；数据量较大时调小批次大小或增大超时时间", true},
+	{FailureCodeNetworkUnreachable, FailureClassNetwork, "无法连接源系统（连接被拒绝或域名无法解析）", "检查数据源地址和端口、DNS解析以及防火墙白名单，确认源系统服务已启动", true},
+	{FailureCodeNetworkReset, FailureClassNetwork, "连接被中断", "检查网络稳定性和中间代理的空闲超时设置，稍后重试", true},
+	{FailureCodeSourceUnavailable, FailureClassNetwork, "源系统返回服务端错误（5xx）", "源系统暂时不可用，联系源系统厂商确认服务状态，恢复后重试", true},
+	{FailureCodeSchemaTableMissing, FailureClassSchema, "目标表或视图不存在", "确认接口对应的数据表已创建，必要时在接口配置中重新生成表结构", false},
+	{FailureCodeSchemaColumnMissing, FailureClassSchema, "字段不存在", "源系统或目标表字段发生变更，检查接口字段映射并同步目标表结构", false},
+	{FailureCodeDataTypeInvalid, FailureClassDataType, "数据格式与字段类型不匹配", "检查字段映射的类型配置，为日期、数值等字段配置格式转换或清洗规则", false},
+	{FailureCodeDataTypeOverflow, FailureClassDataType, "数值超出字段范围", "调大目标字段的精度或类型（如int改为bigint、numeric），或在清洗规则中过滤异常值", false},
+	{FailureCodeDataTooLong, FailureClassDataType, "字符串超出字段长度", "调大目标字段长度或改为text类型，或在清洗规则中截断", false},
+	{FailureCodeContractViolation, FailureClassDataType, "数据不符合接口数据契约，已拒绝写入", "查看执行结果中的契约违规明细，与数据提供方确认列、类型和取值约定；临时放行可将契约strictness改为warn", false},
+	{FailureCodeConstraintUnique, FailureClassConstraint, "违反唯一约束，存在重复数据", "检查接口主键配置是否与源数据唯一键一致，或改用增量/upsert同步模式", false},
+	{FailureCodeConstraintNotNull, FailureClassConstraint, "必填字段为空", "检查源数据是否缺少该字段，为字段配置默认值或取消非空约束", false},
+	{FailureCodeConstraintForeignKey, FailureClassConstraint, "违反外键约束", "确认被引用的数据已先同步，调整同步任务的接口执行顺序", false},
+	{FailureCodeConstraintCheck, FailureClassConstraint, "违反检查约束", "检查源数据取值是否符合目标表的检查约束，配置清洗规则过滤不合规数据", false},
+	{FailureCodeQuotaRateLimited, FailureClassQuota, "被源系统限流（429）", "降低同步频率或并发，按源系统的调用配额错峰调度", true},
+	{FailureCodeQuotaConnections, FailureClassQuota, "数据库连接数超限", "减少同时运行的同步任务，或调大数据库最大连接数", true},
+	{FailureCodeQuotaStorage, FailureClassQuota, "存储空间或配额不足", "清理过期数据或扩容存储，检查租户存储配额", false},
+	{FailureCodeUnknown, FailureClassUnknown, "未识别的错误", "查看执行诊断中的错误详情和调用栈，必要时联系平台运维", true},
+}
+
+// failureCodeIndex 失败码到说明的索引
+var failureCodeIndex = func() map[string]FailureCodeInfo {
+	index := make(map[string]FailureCodeInfo, len(FailureCodeCatalog))
+	for _, info := range FailureCodeCatalog {
+		index[info.Code] = info
+	}
+	return index
+}()
+
+// LookupFailureCode 查询失败码说明，未知失败码返回SYNC_UNKNOWN的说明
+func LookupFailureCode(code string) FailureCodeInfo {
+	if info, ok := failureCodeIndex[code]; ok {
+		return info
+	}
+	return failureCodeIndex[FailureCodeUnknown]
+}
+
+// sqlStateCodes PostgreSQL SQLSTATE到失败码
+var sqlStateCodes = map[string]string{
+	"28000": FailureCodeAuthInvalidCredentials,
+	"28P01": FailureCodeAuthInvalidCredentials,
+	"42501": FailureCodeAuthForbidden,
+	"57014": FailureCodeNetworkTimeout,
+	"08001": FailureCodeNetworkUnreachable,
+	"08006": FailureCodeNetworkReset,
+	"42P01": FailureCodeSchemaTableMissing,
+	"3F000": FailureCodeSchemaTableMissing,
+	"42703": FailureCodeSchemaColumnMissing,
+	"22P02": FailureCodeDataTypeInvalid,
+	"22007": FailureCodeDataTypeInvalid,
+	"22008": FailureCodeDataTypeOverflow,
+	"42804": FailureCodeDataTypeInvalid,
+	"22003": FailureCodeDataTypeOverflow,
+	"22001": FailureCodeDataTooLong,
+	"23505": FailureCodeConstraintUnique,
+	"23502": FailureCodeConstraintNotNull,
+	"23503": FailureCodeConstraintForeignKey,
+	"23514": FailureCodeConstraintCheck,
+	"53300": FailureCodeQuotaConnections,
+	"53100": FailureCodeQuotaStorage,
+	"54000": FailureCodeQuotaStorage,
+}
+
+var (
+	sqlStatePattern   = regexp.MustCompile(`SQLSTATE ([0-9A-Z]{5})`)
+	httpStatusPattern = regexp.MustCompile(`(?i)(?:状态码|status(?: code)?)\s*[:：=]?\s*(\d{3})`)
+)
+
+// failureKeywords 按错误信息关键字识别失败码，按顺序匹配，放在前面的规则更具体
+var failureKeywords = []struct {
+	code     string
+	keywords []string
+}{
+	{FailureCodeContractViolation, []string{"数据契约"}},
+	{FailureCodeQuotaRateLimited, []string{"too many requests", "rate limit", "限流"}},
+	{FailureCodeQuotaConnections, []string{"too many connections", "too many clients", "连接数"}},
+	{FailureCodeQuotaStorage, []string{"no space left", "disk full", "quota exceeded", "存储空间不足", "配额"}},
+	{FailureCodeAuthForbidden, []string{"forbidden", "permission denied", "access denied", "无权限", "权限不足"}},
+	{FailureCodeAuthInvalidCredentials, []string{"unauthorized", "authentication failed", "invalid token", "token expired", "password authentication", "认证失败", "鉴权失败", "令牌"}},
+	{FailureCodeConstraintUnique, []string{"duplicate key", "unique constraint", "唯一约束"}},
+	{FailureCodeConstraintNotNull, []string{"not-null constraint", "not null constraint", "cannot be null", "非空约束"}},
+	{FailureCodeConstraintForeignKey, []string{"foreign key constraint", "外键约束"}},
+	{FailureCodeConstraintCheck, []string{"check constraint", "检查约束"}},
+	{FailureCodeSchemaColumnMissing, []string{"no such column", "unknown column", "has no column", "字段不存在"}},
+	{FailureCodeSchemaTableMissing, []string{"no such table", "doesn't exist", "表不存在"}},
+	{FailureCodeDataTooLong, []string{"value too long", "too long for type", "长度超出"}},
+	{FailureCodeDataTypeOverflow, []string{"out of range", "overflow", "超出范围"}},
+	{FailureCodeDataTypeInvalid, []string{"invalid input syntax", "invalid input value", "cannot parse", "type mismatch", "cannot convert", "类型转换", "格式错误"}},
+	{FailureCodeNetworkTimeout, []string{"timeout", "timed out", "deadline exceeded", "超时"}},
+	{FailureCodeNetworkUnreachable, []string{"connection refused", "no such host", "no route to host", "network is unreachable", "dial tcp", "连接失败", "无法连接"}},
+	{FailureCodeNetworkReset, []string{"connection reset", "broken pipe", "unexpected eof", "连接中断"}},
+}
+
+// FailureClassification 失败分类结果
+type FailureClassification struct {
+	Code      string       `json:"code"`
+	Class     FailureClass `json:"class"`
+	Retryable bool         `json:"retryable"`
+}
+
+// newFailureClassification 按失败码构建分类结果
+func newFailureClassification(code string) FailureClassification {
+	info := LookupFailureCode(code)
+	return FailureClassification{Code: info.Code, Class: info.Class, Retryable: info.Retryable}
+}
+
+// ClassifyFailure 对错误进行根因分类，优先使用数据库SQLSTATE和网络错误类型
+func ClassifyFailure(err error) FailureClassification {
+	if err == nil {
+		return newFailureClassification(FailureCodeUnknown)
+	}
+
+	// pgconn.PgError等数据库驱动错误实现了SQLState方法
+	var sqlErr interface{ SQLState() string }
+	if errors.As(err, &sqlErr) {
+		if code, ok := sqlStateCodes[sqlErr.SQLState()]; ok {
+			return newFailureClassification(code)
+		}
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) {
+		return newFailureClassification(FailureCodeNetworkTimeout)
+	}
+	if errors.Is(err, syscall.ECONNREFUSED) || errors.Is(err, syscall.EHOSTUNREACH) || errors.Is(err, syscall.ENETUNREACH) {
+		return newFailureClassification(FailureCodeNetworkUnreachable)
+	}
+	if errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.EPIPE) {
+		return newFailureClassification(FailureCodeNetworkReset)
+	}
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		return newFailureClassification(FailureCodeNetworkUnreachable)
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return newFailureClassification(FailureCodeNetworkTimeout)
+	}
+
+	return ClassifyFailureMessage(err.Error())
+}
+
+// ClassifyFailureMessage 根据错误信息进行根因分类，用于执行记录中只保存了错误文本的场景
+func ClassifyFailureMessage(message string) FailureClassification {
+	if match := sqlStatePattern.FindStringSubmatch(message); match != nil {
+		if code, ok := sqlStateCodes[match[1]]; ok {
+			return newFailureClassification(code)
+		}
+	}
+	if match := httpStatusPattern.FindStringSubmatch(message); match != nil {
+		if code := httpStatusFailureCode(match[1]); code != "" {
+			return newFailureClassification(code)
+		}
+	}
+
+	lower := strings.ToLower(message)
+	// PostgreSQL: column "x" of relation "t" does not exist / relation "t" does not exist
+	if strings.Contains(lower, "does not exist") {
+		if strings.Contains(lower, "column") {
+			return newFailureClassification(FailureCodeSchemaColumnMissing)
+		}
+		if strings.Contains(lower, "relation") || strings.Contains(lower, "schema") {
+			return newFailureClassification(FailureCodeSchemaTableMissing)
+		}
+	}
+	for _, rule := range failureKeywords {
+		for _, keyword := range rule.keywords {
+			if strings.Contains(lower, keyword) {
+				return newFailureClassification(rule.code)
+			}
+		}
+	}
+	return newFailureClassification(FailureCodeUnknown)
+}
+
+// httpStatusFailureCode HTTP状态码到失败码，无法对应时返回空
+func httpStatusFailureCode(status string) string {
+	code, err := strconv.Atoi(status)
+	if err != nil {
+		return ""
+	}
+	switch {
+	case code == 401:
+		return FailureCodeAuthInvalidCredentials
+	case code == 403:
+		return FailureCodeAuthForbidden
+	case code == 408:
+		return FailureCodeNetworkTimeout
+	case code == 429:
+		return FailureCodeQuotaRateLimited
+	case code == 504:
+		return FailureCodeNetworkTimeout
+	case code >= 500 && code <= 599:
+		return FailureCodeSourceUnavailable
+	}
+	return ""
+}
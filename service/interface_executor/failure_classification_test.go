@@ -0,0 +1,105 @@
+/*
+ * @module service/interface_executor/failure_classification_test
+ * @description 同步失败根因分类测试，覆盖SQLSTATE、网络错误、HTTP状态码和关键字识别以及失败码目录的完整性
+ * @architecture 测试层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow 构造错误 -> 分类 -> 验证失败码和分类
+ * @rules 失败码目录中每个失败码都必须有分类和处理建议
+ * @dependencies testing, testify
+ * @refs failure_classification.go
+ */
+
+package interface_executor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// sqlStateError 模拟数据库驱动错误
+type sqlStateError struct {
+	code string
+}
+
+func (e *sqlStateError) Error() string    { return "database error" }
+func (e *sqlStateError) SQLState() string { return e.code }
+
+func TestClassifyFailureStructuredErrors(t *testing.T) {
+	cases := []struct {
+		name string
+		err  error
+		code string
+	}{
+		{"唯一约束", fmt.Errorf("写入失败: %w", &sqlStateError{"23505"}), FailureCodeConstraintUnique},
+		{"非空约束", &sqlStateError{"23502"}, FailureCodeConstraintNotNull},
+		{"字段不存在", &sqlStateError{"42703"}, FailureCodeSchemaColumnMissing},
+		{"类型不匹配", &sqlStateError{"22P02"}, FailureCodeDataTypeInvalid},
+		{"连接数超限", &sqlStateError{"53300"}, FailureCodeQuotaConnections},
+		{"超时", fmt.Errorf("查询失败: %w", context.DeadlineExceeded), FailureCodeNetworkTimeout},
+		{"连接被拒绝", &net.OpError{Op: "dial", Err: syscall.ECONNREFUSED}, FailureCodeNetworkUnreachable},
+		{"域名解析失败", &net.DNSError{Err: "no such host", Name: "source.local"}, FailureCodeNetworkUnreachable},
+		{"连接重置", fmt.Errorf("read: %w", syscall.ECONNRESET), FailureCodeNetworkReset},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.code, ClassifyFailure(tc.err).Code)
+		})
+	}
+}
+
+func TestClassifyFailureMessage(t *testing.T) {
+	cases := []struct {
+		message string
+		code    string
+		class   FailureClass
+	}{
+		{`ERROR: duplicate key value violates unique constraint "t_pkey" (SQLSTATE 23505)`, FailureCodeConstraintUnique, FailureClassConstraint},
+		{`ERROR: value too long for type character varying(20) (SQLSTATE 22001)`, FailureCodeDataTooLong, FailureClassDataType},
+		{`column "age" of relation "person" does not exist`, FailureCodeSchemaColumnMissing, FailureClassSchema},
+		{`relation "ods.person" does not exist`, FailureCodeSchemaTableMissing, FailureClassSchema},
+		{"HTTP请求失败，状态码: 401, 响应: invalid token", FailureCodeAuthInvalidCredentials, FailureClassAuth},
+		{"HTTP请求失败，状态码: 403, 响应: {}", FailureCodeAuthForbidden, FailureClassAuth},
+		{"HTTP请求失败，状态码: 429, 响应: {}", FailureCodeQuotaRateLimited, FailureClassQuota},
+		{"服务器错误，状态码: 503", FailureCodeSourceUnavailable, FailureClassNetwork},
+		{`invalid input syntax for type integer: "abc"`, FailureCodeDataTypeInvalid, FailureClassDataType},
+		{"dial tcp 10.0.0.1:5432: connect: connection refused", FailureCodeNetworkUnreachable, FailureClassNetwork},
+		{"context deadline exceeded", FailureCodeNetworkTimeout, FailureClassNetwork},
+		{"pq: could not write: no space left on device", FailureCodeQuotaStorage, FailureClassQuota},
+		{"未知问题", FailureCodeUnknown, FailureClassUnknown},
+	}
+	for _, tc := range cases {
+		classification := ClassifyFailureMessage(tc.message)
+		assert.Equal(t, tc.code, classification.Code, tc.message)
+		assert.Equal(t, tc.class, classification.Class, tc.message)
+	}
+}
+
+func TestFailureCodeCatalog(t *testing.T) {
+	seen := map[string]bool{}
+	for _, info := range FailureCodeCatalog {
+		assert.False(t, seen[info.Code], "失败码重复: %s", info.Code)
+		seen[info.Code] = true
+		assert.NotEmpty(t, info.Remediation, info.Code)
+		assert.Contains(t, FailureClassNames, info.Class, info.Code)
+	}
+	for _, code := range sqlStateCodes {
+		assert.True(t, seen[code], "SQLSTATE映射的失败码不在目录中: %s", code)
+	}
+	for _, rule := range failureKeywords {
+		assert.True(t, seen[rule.code], "关键字规则的失败码不在目录中: %s", rule.code)
+	}
+
+	assert.Equal(t, FailureCodeUnknown, LookupFailureCode("NOT_EXIST").Code)
+}
+
+func TestHandleErrorSetsFailureCode(t *testing.T) {
+	detail := NewErrorHandler().HandleError(context.Background(), errors.New("HTTP请求失败，状态码: 401"), ErrorTypeDataSource, "test")
+	assert.Equal(t, FailureCodeAuthInvalidCredentials, detail.Code)
+	assert.Equal(t, FailureClassAuth, detail.Class)
+}
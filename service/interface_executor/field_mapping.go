@@ -13,6 +13,10 @@ package interface_executor
 
 import (
 	"context"
+	"datahub-service/service/encryption"
+	"datahub-service/service/models"
+	"datahub-service/service/utils"
+	"encoding/json"
 	"fmt"
 	"log/slog"
 	"strconv"
@@ -27,12 +31,39 @@ import (
 type FieldMapper struct {
 	// 字段类型映射缓存，提高性能
 	fieldTypeCache map[string]map[string]string // interfaceID -> fieldName -> dataType
+	// 字段配置列顺序缓存
+	columnCache map[string][]string // interfaceID -> 列名
+	// 字段配置中的派生列缓存
+	computedCache map[string][]models.TableField // interfaceID -> 派生列
+	// 时区策略缓存，未配置时为nil
+	timezoneCache map[string]*utils.TimezonePolicy // interfaceID -> 时区策略
+	// 写入时的字段校验统计
+	fieldReport fieldValidationReport
+	// 数据契约校验，未配置契约时为nil
+	contract *contractChecker
+	// 增量同步的变更统计，未开启时为nil
+	diff *syncDiffTracker
+	// 历史追踪配置，未开启时为nil
+	scd *models.SCDConfig
+	// 源端删除同步状态，未开启时为nil
+	deletes *deleteTracker
+	// 来源追溯系统列的取值，未开启时为nil
+	provenance *provenanceStamp
 }
 
 // NewFieldMapper 创建字段映射器
 func NewFieldMapper() *FieldMapper {
 	return &FieldMapper{
 		fieldTypeCache: make(map[string]map[string]string),
+		columnCache:    make(map[string][]string),
+		computedCache:  make(map[string][]models.TableField),
+		timezoneCache:  make(map[string]*utils.TimezonePolicy),
+		fieldReport: fieldValidationReport{
+			unexpected:   make(map[string]int),
+			missing:      make(map[string]int),
+			zoneMismatch: make(map[string]int),
+			unparsedTime: make(map[string]int),
+		},
 	}
 }
 
@@ -79,6 +110,13 @@ func (fm *FieldMapper) buildFieldTypeMapping(interfaceInfo InterfaceInfo) map[st
 						fieldTypeMap[fieldName] = fieldType
 					}
 				}
+
+				// 表字段配置(TableField)格式：name_en + data_type
+				if fieldName, ok := fieldConfig["name_en"].(string); ok {
+					if fieldType, ok := fieldConfig["data_type"].(string); ok {
+						fieldTypeMap[fieldName] = fieldType
+					}
+				}
 			}
 		}
 	}
@@ -133,6 +171,12 @@ func (fm *FieldMapper) UpdateTableData(ctx context.Context, db *gorm.DB, interfa
 	slog.Debug("UpdateTableData - 表名", "value", fullTableName)
 	slog.Debug("UpdateTableData - 原始数据行数", "count", len(data))
 
+	// 加密敏感列
+	data, err := fm.encryptSensitiveColumns(ctx, interfaceInfo, data)
+	if err != nil {
+		return 0, err
+	}
+
 	// 打印parseConfig信息
 	parseConfig := interfaceInfo.GetParseConfig()
 	slog.Debug("UpdateTableData - parseConfig", "data", parseConfig)
@@ -179,54 +223,11 @@ func (fm *FieldMapper) UpdateTableData(ctx context.Context, db *gorm.DB, interfa
 		return 0, fmt.Errorf("清空表数据失败: %w", err)
 	}
 
-	// 5. 插入新数据
-	var insertedRows int64
-	for i, row := range deduplicatedData {
-		// 只对第一行数据输出详细调试信息
-		if i == 0 {
-			slog.Debug("UpdateTableData - 处理第一行数据", "row_index", i+1, "row_data", row)
-		} else if i%100 == 0 {
-			slog.Debug("UpdateTableData - 已处理", "count", i+1)
-		}
-
-		// 应用parseConfig中的fieldMapping
-		mappedRow := fm.ApplyFieldMapping(row, parseConfig, i == 0)
-		if i == 0 {
-			slog.Debug("UpdateTableData - 字段映射后的数据", "data", mappedRow)
-		}
-
-		// 构建插入SQL
-		columns := make([]string, 0, len(mappedRow))
-		placeholders := make([]string, 0, len(mappedRow))
-		values := make([]interface{}, 0, len(mappedRow))
-
-		for col, val := range mappedRow {
-			columns = append(columns, fmt.Sprintf(`"%s"`, col))
-			placeholders = append(placeholders, "?")
-			// 处理数据类型转换，基于字段配置
-			processedVal := fm.ProcessValueForDatabase(col, val, interfaceInfo, i == 0)
-			values = append(values, processedVal)
-		}
-
-		// 构建插入SQL
-		insertSQL := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)",
-			fullTableName,
-			strings.Join(columns, ", "),
-			strings.Join(placeholders, ", "))
-
-		if i == 0 {
-			slog.Debug("UpdateTableData - 插入SQL", "value", insertSQL)
-			slog.Debug("UpdateTableData - 插入参数", "data", values)
-		}
-
-		if err := tx.Exec(insertSQL, values...).Error; err != nil {
-			slog.Error("UpdateTableData - 插入数据失败", "error", err)
-			slog.Error("UpdateTableData - 失败的SQL", "message", insertSQL)
-			slog.Error("UpdateTableData - 失败的参数", "data", values)
-			tx.Rollback()
-			return 0, fmt.Errorf("插入数据失败: %w", err)
-		}
-		insertedRows++
+	// 5. 多行插入新数据
+	insertedRows, err := fm.bulkInsert(ctx, tx, interfaceInfo, fullTableName, deduplicatedData, bulkConflictNone, nil)
+	if err != nil {
+		tx.Rollback()
+		return 0, err
 	}
 
 	// 6. 提交事务
@@ -251,6 +252,12 @@ func (fm *FieldMapper) InsertBatchData(ctx context.Context, db *gorm.DB, interfa
 	slog.Debug("FieldMapper.InsertBatchData - 开始插入批量数据到表", "value", fullTableName)
 	slog.Debug("InsertBatchData - 数据行数", "count", len(data))
 
+	// 加密敏感列
+	data, err := fm.encryptSensitiveColumns(ctx, interfaceInfo, data)
+	if err != nil {
+		return 0, err
+	}
+
 	// 开启事务
 	tx := db.Begin()
 	defer func() {
@@ -260,46 +267,11 @@ func (fm *FieldMapper) InsertBatchData(ctx context.Context, db *gorm.DB, interfa
 		}
 	}()
 
-	// 插入数据
-	var insertedRows int64
-	for i, row := range data {
-		slog.Debug("InsertBatchData - 处理行数据", "row_index", i+1, "row_data", row)
-
-		// 应用parseConfig中的fieldMapping
-		parseConfig := interfaceInfo.GetParseConfig()
-		mappedRow := fm.ApplyFieldMapping(row, parseConfig)
-		slog.Debug("InsertBatchData - 字段映射后的数据", "data", mappedRow)
-
-		// 构建插入SQL
-		columns := make([]string, 0, len(mappedRow))
-		placeholders := make([]string, 0, len(mappedRow))
-		values := make([]interface{}, 0, len(mappedRow))
-
-		for col, val := range mappedRow {
-			columns = append(columns, fmt.Sprintf(`"%s"`, col))
-			placeholders = append(placeholders, "?")
-			// 处理数据类型转换，基于字段配置
-			processedVal := fm.ProcessValueForDatabase(col, val, interfaceInfo)
-			values = append(values, processedVal)
-		}
-
-		// 修复SQL格式错误 - 使用strings.Join而不是fmt.Sprintf
-		insertSQL := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)",
-			fullTableName,
-			strings.Join(columns, ", "),
-			strings.Join(placeholders, ", "))
-
-		slog.Debug("InsertBatchData - 插入SQL", "value", insertSQL)
-		slog.Debug("InsertBatchData - 插入参数", "data", values)
-
-		if err := tx.Exec(insertSQL, values...).Error; err != nil {
-			slog.Error("InsertBatchData - 插入数据失败", "error", err)
-			slog.Error("InsertBatchData - 失败的SQL", "message", insertSQL)
-			slog.Error("InsertBatchData - 失败的参数", "data", values)
-			tx.Rollback()
-			return 0, fmt.Errorf("插入数据失败: %w", err)
-		}
-		insertedRows++
+	// 多行插入数据
+	insertedRows, err := fm.bulkInsert(ctx, tx, interfaceInfo, fullTableName, data, bulkConflictNone, nil)
+	if err != nil {
+		tx.Rollback()
+		return 0, err
 	}
 
 	// 提交事务
@@ -326,6 +298,12 @@ func (fm *FieldMapper) InsertBatchDataWithTx(ctx context.Context, tx *gorm.DB, i
 	slog.Debug("FieldMapper.InsertBatchDataWithTx - 开始插入批量数据到表", "value", fullTableName)
 	slog.Debug("InsertBatchDataWithTx - 原始数据行数", "count", len(data))
 
+	// 加密敏感列
+	data, err := fm.encryptSensitiveColumns(ctx, interfaceInfo, data)
+	if err != nil {
+		return 0, err
+	}
+
 	// 1. 获取表的主键信息（使用外部db连接，因为tx可能已经在事务中）
 	primaryKeys, err := fm.getPrimaryKeys(tx, schemaName, tableName)
 	if err != nil {
@@ -345,76 +323,15 @@ func (fm *FieldMapper) InsertBatchDataWithTx(ctx context.Context, tx *gorm.DB, i
 		}
 	}
 
-	// 3. 插入数据（使用提供的事务）
-	var insertedRows int64
-	parseConfig := interfaceInfo.GetParseConfig()
-
-	for i, row := range deduplicatedData {
-		if i == 0 {
-			slog.Debug("InsertBatchDataWithTx - 处理第一行数据", "row_index", i+1, "row_data", row)
-		} else if i%100 == 0 {
-			slog.Debug("InsertBatchDataWithTx - 已处理", "count", i+1)
-		}
-
-		// 应用parseConfig中的fieldMapping
-		mappedRow := fm.ApplyFieldMapping(row, parseConfig, i == 0)
-		if i == 0 {
-			slog.Debug("InsertBatchDataWithTx - 字段映射后的数据", "data", mappedRow)
-		}
-
-		// 构建插入SQL
-		columns := make([]string, 0, len(mappedRow))
-		placeholders := make([]string, 0, len(mappedRow))
-		values := make([]interface{}, 0, len(mappedRow))
-
-		for col, val := range mappedRow {
-			columns = append(columns, fmt.Sprintf(`"%s"`, col))
-			placeholders = append(placeholders, "?")
-			// 处理数据类型转换，基于字段配置
-			processedVal := fm.ProcessValueForDatabase(col, val, interfaceInfo, i == 0)
-			values = append(values, processedVal)
-		}
-
-		// 构建插入SQL，使用 ON CONFLICT DO NOTHING 处理主键冲突
-		// 这样可以优雅地跳过数据源中的重复数据，避免同步失败
-		var insertSQL string
-		if len(primaryKeys) > 0 {
-			// 有主键时，使用 ON CONFLICT DO NOTHING
-			conflictColumns := make([]string, len(primaryKeys))
-			for idx, pk := range primaryKeys {
-				conflictColumns[idx] = fmt.Sprintf(`"%s"`, pk)
-			}
-			insertSQL = fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s) ON CONFLICT (%s) DO NOTHING",
-				fullTableName,
-				strings.Join(columns, ", "),
-				strings.Join(placeholders, ", "),
-				strings.Join(conflictColumns, ", "))
-		} else {
-			// 无主键时，使用普通 INSERT
-			insertSQL = fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)",
-				fullTableName,
-				strings.Join(columns, ", "),
-				strings.Join(placeholders, ", "))
-		}
-
-		if i == 0 {
-			slog.Debug("InsertBatchDataWithTx - 插入SQL", "value", insertSQL)
-			slog.Debug("InsertBatchDataWithTx - 插入参数", "data", values)
-			if len(primaryKeys) > 0 {
-				slog.Info("InsertBatchDataWithTx - 使用 ON CONFLICT 策略，主键冲突时跳过", "primary_keys", primaryKeys)
-			}
-		}
-
-		result := tx.Exec(insertSQL, values...)
-		if result.Error != nil {
-			slog.Error("InsertBatchDataWithTx - 插入数据失败", "error", result.Error)
-			slog.Error("InsertBatchDataWithTx - 失败的SQL", "message", insertSQL)
-			slog.Error("InsertBatchDataWithTx - 失败的参数", "data", values)
-			return 0, fmt.Errorf("插入数据失败: %w", result.Error)
-		}
-		
-		// 统计实际插入的行数（如果发生冲突，RowsAffected 会是 0）
-		insertedRows += result.RowsAffected
+	// 3. 多行插入数据（使用提供的事务），有主键时使用 ON CONFLICT DO NOTHING 跳过数据源中的重复数据，
+	// 影响行数不含被跳过的行
+	mode := bulkConflictNone
+	if len(primaryKeys) > 0 {
+		mode = bulkConflictIgnore
+	}
+	insertedRows, err := fm.bulkInsert(ctx, tx, interfaceInfo, fullTableName, deduplicatedData, mode, primaryKeys)
+	if err != nil {
+		return 0, err
 	}
 
 	slog.Debug("InsertBatchDataWithTx - 成功插入", "count", insertedRows)
@@ -480,13 +397,22 @@ func (fm *FieldMapper) ApplyFieldMapping(row map[string]interface{}, parseConfig
 	mappedRow := make(map[string]interface{})
 
 	if isArrayFormat {
-		// 处理新的数组格式：[{"source": "age", "target": "age"}, ...]
-		// 构建源字段到目标字段的映射表
+		// 处理新的数组格式：[{"source": "age", "target": "age", "transforms": [...]}, ...]
+		// 构建源字段到目标字段的映射表，带transforms的条目单独处理
 		sourceToTargetMap := make(map[string]string)
+		transformSources := make(map[string]bool)
+		var transformEntries []map[string]interface{}
 		for _, mappingItem := range fieldMappingArray {
 			if mappingObj, ok := mappingItem.(map[string]interface{}); ok {
 				source := cast.ToString(mappingObj["source"])
 				target := cast.ToString(mappingObj["target"])
+				if transforms, ok := mappingObj["transforms"].([]interface{}); ok && len(transforms) > 0 && target != "" {
+					transformEntries = append(transformEntries, mappingObj)
+					if source != "" {
+						transformSources[source] = true
+					}
+					continue
+				}
 				if source != "" && target != "" {
 					sourceToTargetMap[source] = target
 					if debug {
@@ -503,6 +429,9 @@ func (fm *FieldMapper) ApplyFieldMapping(row map[string]interface{}, parseConfig
 			// 查找映射目标字段
 			if target, exists := sourceToTargetMap[sourceField]; exists {
 				targetField = target
+			} else if transformSources[sourceField] {
+				// 只用于转换的源字段不再按原名写入
+				continue
 			} else {
 				// 如果没找到映射，使用原字段名
 				targetField = sourceField
@@ -514,6 +443,24 @@ func (fm *FieldMapper) ApplyFieldMapping(row map[string]interface{}, parseConfig
 			}
 		}
 
+		// 执行转换，source为空时完全由转换生成目标字段值
+		for _, entry := range transformEntries {
+			source := cast.ToString(entry["source"])
+			target := cast.ToString(entry["target"])
+			var value interface{}
+			if source != "" {
+				value = row[source]
+			}
+			result, err := utils.ApplyFieldTransforms(value, row, entry["transforms"].([]interface{}))
+			if err != nil {
+				slog.Warn("ApplyFieldMapping - 字段转换失败", "source", source, "target", target, "value", value, "error", err)
+			}
+			mappedRow[target] = result
+			if debug {
+				slog.Debug("ApplyFieldMapping - 字段转换", "source", source, "target", target, "value", result)
+			}
+		}
+
 	} else {
 		// 处理旧的对象格式：{"age": "age", "email": "email", ...}（兼容模式）
 		for sourceField, value := range row {
@@ -545,6 +492,15 @@ func (fm *FieldMapper) ApplyFieldMapping(row map[string]interface{}, parseConfig
 	return mappedRow
 }
 
+// encryptSensitiveColumns 加密配置了列加密的敏感列，返回的数据为副本
+func (fm *FieldMapper) encryptSensitiveColumns(ctx context.Context, interfaceInfo InterfaceInfo, data []map[string]interface{}) ([]map[string]interface{}, error) {
+	encrypted, err := encryption.EncryptRows(ctx, interfaceInfo.GetSchemaName(), interfaceInfo.GetTableName(), data)
+	if err != nil {
+		return nil, fmt.Errorf("加密敏感列失败: %w", err)
+	}
+	return encrypted, nil
+}
+
 // ProcessValueForDatabase 基于字段配置处理数据库值，支持多种数据类型转换
 func (fm *FieldMapper) ProcessValueForDatabase(columnName string, value interface{}, interfaceInfo InterfaceInfo, debugLog ...bool) interface{} {
 	if value == nil {
@@ -562,6 +518,11 @@ func (fm *FieldMapper) ProcessValueForDatabase(columnName string, value interfac
 		slog.Debug("ProcessValueForDatabase - 字段数据类型", "column", columnName, "data_type", dataType)
 	}
 
+	// 配置了时区策略时，时间和日期字段统一转换到目标时区
+	if normalized, ok := fm.normalizeTimeValue(interfaceInfo, columnName, dataType, value); ok {
+		return normalized
+	}
+
 	// 根据数据类型进行转换
 	return fm.convertValueByDataType(value, dataType, columnName, debug)
 }
@@ -587,6 +548,8 @@ func (fm *FieldMapper) convertValueByDataType(value interface{}, dataType, colum
 		return fm.convertToString(value, debug)
 	case "json", "jsonb":
 		return fm.convertToJSON(value, debug)
+	case models.GeoTypeGeometry, models.GeoTypeGeography:
+		return fm.convertToGeo(value, columnName, debug)
 	default:
 		// 未知类型，使用字符串转换
 		if debug {
@@ -843,8 +806,20 @@ func (fm *FieldMapper) convertToBoolean(value interface{}, debug bool) interface
 	}
 }
 
-// convertToString 转换为字符串
+// convertToString 转换为字符串，嵌套对象和数组序列化为JSON文本
 func (fm *FieldMapper) convertToString(value interface{}, debug bool) interface{} {
+	switch value.(type) {
+	case map[string]interface{}, []interface{}:
+		data, err := json.Marshal(value)
+		if err != nil {
+			return fmt.Sprintf("%v", value)
+		}
+		if debug {
+			slog.Debug("convertToString - 嵌套结构序列化为JSON文本", "value", string(data))
+		}
+		return string(data)
+	}
+
 	strVal := cast.ToString(value)
 	if debug && fmt.Sprintf("%v", value) != strVal {
 		slog.Debug("convertToString - 类型转字符串", "from", value, "to", strVal)
@@ -852,19 +827,43 @@ func (fm *FieldMapper) convertToString(value interface{}, debug bool) interface{
 	return strVal
 }
 
-// convertToJSON 转换为JSON格式
+// convertToJSON 转换为JSON文本，已是合法JSON的字符串原样写入，其余值（含非JSON字符串）按JSON序列化
 func (fm *FieldMapper) convertToJSON(value interface{}, debug bool) interface{} {
 	switch v := value.(type) {
 	case string:
-		// 如果已经是字符串，检查是否是有效的JSON
-		return v
-	case map[string]interface{}, []interface{}:
-		// 如果是map或slice，直接返回
-		return v
-	default:
-		// 其他类型转换为字符串
-		return cast.ToString(v)
+		if json.Valid([]byte(v)) {
+			return v
+		}
+	case []byte:
+		if json.Valid(v) {
+			return string(v)
+		}
+		value = string(v)
+	case json.RawMessage:
+		return string(v)
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		if debug {
+			slog.Debug("convertToJSON - JSON序列化失败", "value", value, "error", err)
+		}
+		return nil
 	}
+	return string(data)
+}
+
+// convertToGeo 把GeoJSON、WKT或十六进制WKB转换为空间列可写入的EWKT，无法识别时按字符串写入，由数据库报告错误
+func (fm *FieldMapper) convertToGeo(value interface{}, columnName string, debug bool) interface{} {
+	geo, err := models.CoerceGeoValue(value)
+	if err != nil {
+		slog.Warn("convertToGeo - 空间数据转换失败", "column", columnName, "error", err)
+		return fm.convertToString(value, debug)
+	}
+	if debug {
+		slog.Debug("convertToGeo - 空间数据转换", "column", columnName, "to", geo)
+	}
+	return geo
 }
 
 // UpsertDataToTable 执行数据的UPSERT操作（增量同步）
@@ -979,10 +978,30 @@ func (fm *FieldMapper) UpsertTableData(ctx context.Context, db *gorm.DB, interfa
 	slog.Debug("UpsertTableData - 表名", "value", fullTableName)
 	slog.Debug("UpsertTableData - 原始数据行数", "count", len(data))
 
+	// 加密敏感列
+	data, err := fm.encryptSensitiveColumns(ctx, interfaceInfo, data)
+	if err != nil {
+		return 0, err
+	}
+
 	if len(data) == 0 {
 		return 0, nil
 	}
 
+	if fm.scd != nil {
+		// 历史追踪模式按自然键合并版本，主键是代理键
+		tx := db.Begin()
+		upsertedRows, err := fm.mergeSCDRows(ctx, tx, interfaceInfo, fullTableName, data)
+		if err != nil {
+			tx.Rollback()
+			return 0, err
+		}
+		if err := tx.Commit().Error; err != nil {
+			return 0, fmt.Errorf("提交事务失败: %w", err)
+		}
+		return upsertedRows, nil
+	}
+
 	// 1. 获取表的主键信息
 	primaryKeys, err := fm.getPrimaryKeys(db, schemaName, tableName)
 	if err != nil || len(primaryKeys) == 0 {
@@ -992,7 +1011,8 @@ func (fm *FieldMapper) UpsertTableData(ctx context.Context, db *gorm.DB, interfa
 
 	slog.Debug("UpsertTableData - 表的主键", "primary_keys", primaryKeys)
 
-	// 2. 对数据进行去重处理（基于主键）
+	// 2. 分出源端标记删除的行，对其余数据进行去重处理（基于主键）
+	data, deletedKeys := fm.splitDeletedRows(interfaceInfo, data, primaryKeys)
 	deduplicatedData := fm.deduplicateData(data, primaryKeys, interfaceInfo)
 	slog.Debug("UpsertTableData - 去重后数据行数", "count", len(deduplicatedData))
 	if len(data) > len(deduplicatedData) {
@@ -1011,77 +1031,18 @@ func (fm *FieldMapper) UpsertTableData(ctx context.Context, db *gorm.DB, interfa
 		}
 	}()
 
-	// 4. 使用UPSERT插入或更新数据
-	var upsertedRows int64
-	parseConfig := interfaceInfo.GetParseConfig()
-
-	for i, row := range deduplicatedData {
-		// 只对第一行数据输出详细调试信息
-		if i == 0 {
-			slog.Debug("UpsertTableData - 处理第一行数据", "row_index", i+1, "row_data", row)
-		} else if i%100 == 0 {
-			slog.Debug("UpsertTableData - 已处理", "count", i+1)
-		}
-
-		// 应用字段映射
-		mappedRow := fm.ApplyFieldMapping(row, parseConfig, i == 0)
-		if i == 0 {
-			slog.Debug("UpsertTableData - 字段映射后的数据", "data", mappedRow)
-		}
-
-		// 构建UPSERT SQL (PostgreSQL: INSERT ... ON CONFLICT ... DO UPDATE)
-		columns := make([]string, 0, len(mappedRow))
-		placeholders := make([]string, 0, len(mappedRow))
-		values := make([]interface{}, 0, len(mappedRow))
-		updateParts := make([]string, 0, len(mappedRow))
-
-		for col, val := range mappedRow {
-			columns = append(columns, fmt.Sprintf(`"%s"`, col))
-			placeholders = append(placeholders, "?")
-			processedVal := fm.ProcessValueForDatabase(col, val, interfaceInfo, i == 0)
-			values = append(values, processedVal)
-
-			// 非主键字段才需要更新
-			isPrimaryKey := false
-			for _, pk := range primaryKeys {
-				if pk == col {
-					isPrimaryKey = true
-					break
-				}
-			}
-			if !isPrimaryKey {
-				updateParts = append(updateParts, fmt.Sprintf(`"%s" = EXCLUDED."%s"`, col, col))
-			}
-		}
-
-		// 构建冲突键（主键列表）
-		conflictKeys := make([]string, 0, len(primaryKeys))
-		for _, pk := range primaryKeys {
-			conflictKeys = append(conflictKeys, fmt.Sprintf(`"%s"`, pk))
-		}
-
-		// 构建UPSERT SQL
-		upsertSQL := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s) ON CONFLICT (%s) DO UPDATE SET %s",
-			fullTableName,
-			strings.Join(columns, ", "),
-			strings.Join(placeholders, ", "),
-			strings.Join(conflictKeys, ", "),
-			strings.Join(updateParts, ", "))
-
-		if i == 0 {
-			slog.Debug("UpsertTableData - UPSERT SQL", "value", upsertSQL)
-			slog.Debug("UpsertTableData - UPSERT参数", "data", values)
-		}
-
-		if err := tx.Exec(upsertSQL, values...).Error; err != nil {
-			slog.Error("UpsertTableData - UPSERT数据失败", "error", err)
-			slog.Error("UpsertTableData - 失败的SQL", "message", upsertSQL)
-			slog.Error("UpsertTableData - 失败的参数", "data", values)
+	// 4. 多行UPSERT插入或更新数据，再处理源端删除
+	if len(deduplicatedData) > 0 {
+		if err := fm.upsertRows(ctx, tx, interfaceInfo, fullTableName, deduplicatedData, primaryKeys); err != nil {
 			tx.Rollback()
-			return 0, fmt.Errorf("UPSERT数据失败: %w", err)
+			return 0, err
 		}
-		upsertedRows++
 	}
+	if err := fm.finishBatchDeletes(ctx, tx, interfaceInfo, fullTableName, primaryKeys, deduplicatedData, deletedKeys); err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+	upsertedRows := int64(len(deduplicatedData))
 
 	// 5. 提交事务
 	if err := tx.Commit().Error; err != nil {
@@ -1109,6 +1070,12 @@ func (fm *FieldMapper) ReplaceTableData(ctx context.Context, db *gorm.DB, interf
 	slog.Debug("ReplaceTableData - 表名", "value", fullTableName)
 	slog.Debug("ReplaceTableData - 原始数据行数", "count", len(data))
 
+	// 加密敏感列
+	data, err := fm.encryptSensitiveColumns(ctx, interfaceInfo, data)
+	if err != nil {
+		return 0, err
+	}
+
 	// 1. 获取表的主键信息
 	primaryKeys, err := fm.getPrimaryKeys(db, schemaName, tableName)
 	if err != nil {
@@ -1147,56 +1114,11 @@ func (fm *FieldMapper) ReplaceTableData(ctx context.Context, db *gorm.DB, interf
 		return 0, fmt.Errorf("清空表数据失败: %w", err)
 	}
 
-	// 5. 批量插入新数据
-	var insertedRows int64
-	parseConfig := interfaceInfo.GetParseConfig()
-
-	for i, row := range deduplicatedData {
-		// 只对第一行数据输出详细调试信息
-		if i == 0 {
-			slog.Debug("ReplaceTableData - 处理第一行数据", "row_index", i+1, "row_data", row)
-		} else if i%100 == 0 {
-			slog.Debug("ReplaceTableData - 已处理", "count", i+1)
-		}
-
-		// 应用parseConfig中的fieldMapping
-		mappedRow := fm.ApplyFieldMapping(row, parseConfig, i == 0)
-		if i == 0 {
-			slog.Debug("ReplaceTableData - 字段映射后的数据", "data", mappedRow)
-		}
-
-		// 构建插入SQL
-		columns := make([]string, 0, len(mappedRow))
-		placeholders := make([]string, 0, len(mappedRow))
-		values := make([]interface{}, 0, len(mappedRow))
-
-		for col, val := range mappedRow {
-			columns = append(columns, fmt.Sprintf(`"%s"`, col))
-			placeholders = append(placeholders, "?")
-			// 处理数据类型转换，基于字段配置
-			processedVal := fm.ProcessValueForDatabase(col, val, interfaceInfo)
-			values = append(values, processedVal)
-		}
-
-		// 构建插入SQL
-		insertSQL := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)",
-			fullTableName,
-			strings.Join(columns, ", "),
-			strings.Join(placeholders, ", "))
-
-		if i == 0 {
-			slog.Debug("ReplaceTableData - 插入SQL", "value", insertSQL)
-			slog.Debug("ReplaceTableData - 插入参数", "data", values)
-		}
-
-		if err := tx.Exec(insertSQL, values...).Error; err != nil {
-			slog.Error("ReplaceTableData - 插入数据失败", "error", err)
-			slog.Error("ReplaceTableData - 失败的SQL", "message", insertSQL)
-			slog.Error("ReplaceTableData - 失败的参数", "data", values)
-			tx.Rollback()
-			return 0, fmt.Errorf("插入数据失败: %w", err)
-		}
-		insertedRows++
+	// 5. 多行插入新数据
+	insertedRows, err := fm.bulkInsert(ctx, tx, interfaceInfo, fullTableName, deduplicatedData, bulkConflictNone, nil)
+	if err != nil {
+		tx.Rollback()
+		return 0, err
 	}
 
 	// 6. 提交事务
@@ -1323,6 +1245,17 @@ func (fm *FieldMapper) UpsertBatchDataWithTx(ctx context.Context, tx *gorm.DB, i
 	slog.Debug("UpsertBatchDataWithTx - 表名", "value", fullTableName)
 	slog.Debug("UpsertBatchDataWithTx - 数据行数", "count", len(data))
 
+	// 加密敏感列
+	data, err := fm.encryptSensitiveColumns(ctx, interfaceInfo, data)
+	if err != nil {
+		return 0, err
+	}
+
+	if fm.scd != nil {
+		// 历史追踪模式按自然键合并版本，主键是代理键
+		return fm.mergeSCDRows(ctx, tx, interfaceInfo, fullTableName, data)
+	}
+
 	// 1. 获取表的主键信息
 	primaryKeys, err := fm.getPrimaryKeys(tx, schemaName, tableName)
 	if err != nil || len(primaryKeys) == 0 {
@@ -1332,7 +1265,8 @@ func (fm *FieldMapper) UpsertBatchDataWithTx(ctx context.Context, tx *gorm.DB, i
 
 	slog.Debug("UpsertBatchDataWithTx - 表的主键", "primary_keys", primaryKeys)
 
-	// 2. 对数据进行去重处理（基于主键）
+	// 2. 分出源端标记删除的行，对其余数据进行去重处理（基于主键）
+	data, deletedKeys := fm.splitDeletedRows(interfaceInfo, data, primaryKeys)
 	deduplicatedData := fm.deduplicateData(data, primaryKeys, interfaceInfo)
 	slog.Debug("UpsertBatchDataWithTx - 去重后数据行数", "count", len(deduplicatedData))
 	if len(data) > len(deduplicatedData) {
@@ -1342,75 +1276,16 @@ func (fm *FieldMapper) UpsertBatchDataWithTx(ctx context.Context, tx *gorm.DB, i
 			"removed_count", len(data)-len(deduplicatedData))
 	}
 
-	// 3. 使用UPSERT插入或更新数据
-	var upsertedRows int64
-	parseConfig := interfaceInfo.GetParseConfig()
-
-	for i, row := range deduplicatedData {
-		// 只对第一行和每100行输出调试信息
-		debug := (i == 0 || i%100 == 0)
-		if debug {
-			slog.Debug("UpsertBatchDataWithTx - 处理行数据", "row_index", i+1, "total", len(deduplicatedData))
-		}
-
-		// 应用字段映射
-		mappedRow := fm.ApplyFieldMapping(row, parseConfig, i == 0)
-		if i == 0 {
-			slog.Debug("UpsertBatchDataWithTx - 字段映射后的数据", "data", mappedRow)
-		}
-
-		// 构建UPSERT SQL (PostgreSQL: INSERT ... ON CONFLICT ... DO UPDATE)
-		columns := make([]string, 0, len(mappedRow))
-		placeholders := make([]string, 0, len(mappedRow))
-		values := make([]interface{}, 0, len(mappedRow))
-		updateParts := make([]string, 0, len(mappedRow))
-
-		for col, val := range mappedRow {
-			columns = append(columns, fmt.Sprintf(`"%s"`, col))
-			placeholders = append(placeholders, "?")
-			processedVal := fm.ProcessValueForDatabase(col, val, interfaceInfo, i == 0)
-			values = append(values, processedVal)
-
-			// 非主键字段才需要更新
-			isPrimaryKey := false
-			for _, pk := range primaryKeys {
-				if pk == col {
-					isPrimaryKey = true
-					break
-				}
-			}
-			if !isPrimaryKey {
-				updateParts = append(updateParts, fmt.Sprintf(`"%s" = EXCLUDED."%s"`, col, col))
-			}
-		}
-
-		// 构建冲突键（主键列表）
-		conflictKeys := make([]string, 0, len(primaryKeys))
-		for _, pk := range primaryKeys {
-			conflictKeys = append(conflictKeys, fmt.Sprintf(`"%s"`, pk))
+	// 3. 多行UPSERT插入或更新数据，再处理源端删除
+	if len(deduplicatedData) > 0 {
+		if err := fm.upsertRows(ctx, tx, interfaceInfo, fullTableName, deduplicatedData, primaryKeys); err != nil {
+			return 0, err
 		}
-
-		// 构建UPSERT SQL
-		upsertSQL := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s) ON CONFLICT (%s) DO UPDATE SET %s",
-			fullTableName,
-			strings.Join(columns, ", "),
-			strings.Join(placeholders, ", "),
-			strings.Join(conflictKeys, ", "),
-			strings.Join(updateParts, ", "))
-
-		if i == 0 {
-			slog.Debug("UpsertBatchDataWithTx - UPSERT SQL", "value", upsertSQL)
-			slog.Debug("UpsertBatchDataWithTx - UPSERT参数", "data", values)
-		}
-
-		if err := tx.Exec(upsertSQL, values...).Error; err != nil {
-			slog.Error("UpsertBatchDataWithTx - UPSERT数据失败", "error", err)
-			slog.Error("UpsertBatchDataWithTx - 失败的SQL", "message", upsertSQL)
-			slog.Error("UpsertBatchDataWithTx - 失败的参数", "data", values)
-			return 0, fmt.Errorf("UPSERT数据失败: %w", err)
-		}
-		upsertedRows++
 	}
+	if err := fm.finishBatchDeletes(ctx, tx, interfaceInfo, fullTableName, primaryKeys, deduplicatedData, deletedKeys); err != nil {
+		return 0, err
+	}
+	upsertedRows := int64(len(deduplicatedData))
 
 	slog.Debug("UpsertBatchDataWithTx - 成功UPSERT", "count", upsertedRows)
 	return upsertedRows, nil
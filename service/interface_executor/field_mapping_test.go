@@ -24,6 +24,7 @@ func TestFieldMapper_ProcessValueForDatabase_Timestamp(t *testing.T) {
 
 	// 设置模拟接口返回字段配置
 	mockInterface.On("GetID").Return("test-interface-id")
+	mockInterface.On("GetParseConfig").Return(map[string]interface{}{})
 	mockInterface.On("GetTableFieldsConfig").Return([]interface{}{
 		map[string]interface{}{
 			"fields": []interface{}{
@@ -242,6 +243,7 @@ func TestFieldMapper_ProcessValueForDatabase_UnknownType(t *testing.T) {
 
 	// 设置模拟接口返回空字段配置，应该回退到字段名推断
 	mockInterface.On("GetID").Return("test-interface-id")
+	mockInterface.On("GetParseConfig").Return(map[string]interface{}{})
 	mockInterface.On("GetTableFieldsConfig").Return([]interface{}{})
 
 	// 测试时间字段名推断
@@ -326,3 +328,132 @@ func TestFieldMapper_getFieldDataType(t *testing.T) {
 	dataType4 := fm.getFieldDataType("unknown_field", mockInterface)
 	assert.Equal(t, "varchar", dataType4)
 }
+
+func TestFieldMapper_ProcessValueForDatabase_JSON(t *testing.T) {
+	fm := NewFieldMapper()
+	mockInterface := &MockInterfaceInfo{}
+
+	// 表字段配置格式声明jsonb字段
+	mockInterface.On("GetID").Return("test-interface-id")
+	mockInterface.On("GetTableFieldsConfig").Return([]interface{}{
+		map[string]interface{}{"name_en": "payload", "data_type": "jsonb"},
+		map[string]interface{}{"name_en": "remark", "data_type": "text"},
+	})
+
+	tests := []struct {
+		name     string
+		input    interface{}
+		expected interface{}
+	}{
+		{
+			name:     "嵌套对象序列化",
+			input:    map[string]interface{}{"temp": 21.5, "tags": []interface{}{"a", "b"}},
+			expected: `{"tags":["a","b"],"temp":21.5}`,
+		},
+		{
+			name:     "数组序列化",
+			input:    []interface{}{map[string]interface{}{"id": 1}},
+			expected: `[{"id":1}]`,
+		},
+		{
+			name:     "合法JSON字符串原样写入",
+			input:    `{"a": 1}`,
+			expected: `{"a": 1}`,
+		},
+		{
+			name:     "普通字符串按JSON字符串写入",
+			input:    "plain",
+			expected: `"plain"`,
+		},
+		{
+			name:     "数字",
+			input:    42,
+			expected: `42`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, fm.ProcessValueForDatabase("payload", tt.input, mockInterface))
+		})
+	}
+
+	// 文本字段中的嵌套结构写为JSON文本而不是空字符串
+	assert.Equal(t, `{"k":"v"}`, fm.ProcessValueForDatabase("remark", map[string]interface{}{"k": "v"}, mockInterface))
+}
+
+func TestFieldMapper_ApplyFieldMapping_Transforms(t *testing.T) {
+	fm := NewFieldMapper()
+	row := map[string]interface{}{
+		"firstName": "张",
+		"lastName":  "三",
+		"birthday":  "19900102",
+		"gender":    "1",
+		"weightG":   float64(72500),
+		"remark":    "",
+	}
+	parseConfig := map[string]interface{}{
+		"fieldMapping": []interface{}{
+			map[string]interface{}{"source": "firstName", "target": "full_name", "transforms": []interface{}{
+				map[string]interface{}{"type": "concat", "fields": []interface{}{"lastName"}},
+			}},
+			map[string]interface{}{"source": "birthday", "target": "birth_date", "transforms": []interface{}{
+				map[string]interface{}{"type": "date_parse", "format": "20060102", "output_format": "2006-01-02"},
+			}},
+			map[string]interface{}{"source": "birthday", "target": "birth_year", "transforms": []interface{}{
+				map[string]interface{}{"type": "substring", "start": 0, "length": 4},
+			}},
+			map[string]interface{}{"source": "gender", "target": "gender", "transforms": []interface{}{
+				map[string]interface{}{"type": "lookup", "mapping": map[string]interface{}{"1": "男", "2": "女"}},
+			}},
+			map[string]interface{}{"source": "weightG", "target": "weight_kg", "transforms": []interface{}{
+				map[string]interface{}{"type": "unit", "from": "g", "to": "kg"},
+			}},
+			map[string]interface{}{"source": "remark", "target": "remark", "transforms": []interface{}{
+				map[string]interface{}{"type": "default", "value": "无"},
+			}},
+			map[string]interface{}{"target": "source_system", "transforms": []interface{}{
+				map[string]interface{}{"type": "default", "value": "hr"},
+			}},
+			map[string]interface{}{"source": "lastName", "target": "last_name"},
+		},
+	}
+
+	mapped := fm.ApplyFieldMapping(row, parseConfig)
+
+	assert.Equal(t, map[string]interface{}{
+		"full_name":     "张三",
+		"birth_date":    "1990-01-02",
+		"birth_year":    "1990",
+		"gender":        "男",
+		"weight_kg":     72.5,
+		"remark":        "无",
+		"source_system": "hr",
+		"last_name":     "三",
+	}, mapped, "只用于转换的源字段不保留原名，同一源字段可派生多个目标字段")
+}
+
+func TestFieldMapper_ProcessValueForDatabase_TimezonePolicy(t *testing.T) {
+	fm := NewFieldMapper()
+	mockInterface := &MockInterfaceInfo{}
+	mockInterface.On("GetID").Return("test-interface-id")
+	mockInterface.On("GetParseConfig").Return(map[string]interface{}{
+		"timezone": map[string]interface{}{"source": "Asia/Shanghai", "target": "UTC"},
+	})
+	mockInterface.On("GetTableFieldsConfig").Return([]interface{}{
+		map[string]interface{}{"name_en": "reported_at", "data_type": "timestamp"},
+		map[string]interface{}{"name_en": "received_at", "data_type": "timestamptz"},
+		map[string]interface{}{"name_en": "report_date", "data_type": "date"},
+	})
+
+	assert.Equal(t, "2024-03-01 00:00:00.000", fm.ProcessValueForDatabase("reported_at", "2024-03-01 08:00:00", mockInterface))
+	assert.Equal(t, "2024-03-01 08:00:00.000", fm.ProcessValueForDatabase("reported_at", "2024-03-01T08:00:00Z", mockInterface))
+	assert.Equal(t, "2024-03-01 00:00:00.000+00:00", fm.ProcessValueForDatabase("received_at", "2024-03-01T08:00:00+08:00", mockInterface))
+	assert.Equal(t, "2024-02-29", fm.ProcessValueForDatabase("report_date", "2024-03-01 07:00:00", mockInterface))
+	assert.Equal(t, "yesterday", fm.ProcessValueForDatabase("reported_at", "yesterday", mockInterface))
+
+	assert.Equal(t, []string{
+		"时间自带的时区与配置的源时区不一致，已按自带时区转换: reported_at(1行)",
+		"无法解析的时间值，按原值写入: reported_at(1行)",
+	}, fm.FieldValidationWarnings())
+}
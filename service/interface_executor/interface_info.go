@@ -40,7 +40,7 @@ func (b *BasicLibraryInterfaceInfo) GetID() string           { return b.ID }
 func (b *BasicLibraryInterfaceInfo) GetName() string         { return b.NameZh }
 func (b *BasicLibraryInterfaceInfo) GetType() string         { return b.Type }
 func (b *BasicLibraryInterfaceInfo) GetDataSourceID() string { return b.DataSourceID }
-func (b *BasicLibraryInterfaceInfo) GetSchemaName() string   { return b.BasicLibrary.NameEn }
+func (b *BasicLibraryInterfaceInfo) GetSchemaName() string   { return b.BasicLibrary.GetSchemaName() }
 func (b *BasicLibraryInterfaceInfo) GetTableName() string    { return b.NameEn }
 func (b *BasicLibraryInterfaceInfo) GetInterfaceConfig() map[string]interface{} {
 	return b.InterfaceConfig
@@ -68,8 +68,10 @@ func (t *ThematicLibraryInterfaceInfo) GetID() string           { return t.ID }
 func (t *ThematicLibraryInterfaceInfo) GetName() string         { return t.NameZh }
 func (t *ThematicLibraryInterfaceInfo) GetType() string         { return t.Type }
 func (t *ThematicLibraryInterfaceInfo) GetDataSourceID() string { return "" } // 主题接口不关联数据源
-func (t *ThematicLibraryInterfaceInfo) GetSchemaName() string   { return t.ThematicLibrary.NameEn }
-func (t *ThematicLibraryInterfaceInfo) GetTableName() string    { return t.NameEn }
+func (t *ThematicLibraryInterfaceInfo) GetSchemaName() string {
+	return t.ThematicLibrary.GetSchemaName()
+}
+func (t *ThematicLibraryInterfaceInfo) GetTableName() string { return t.NameEn }
 func (t *ThematicLibraryInterfaceInfo) GetInterfaceConfig() map[string]interface{} {
 	return t.InterfaceConfig
 }
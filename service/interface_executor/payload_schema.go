@@ -0,0 +1,63 @@
+/*
+ * @module service/interface_executor/payload_schema
+ * @description 按接口表字段配置生成的JSON Schema校验同步数据，写入失败时给出结构化的违规清单，代替不透明的数据库错误
+ * @architecture 工具函数 - 数据写入
+ * @documentReference ai_docs/interface_executor.md
+ * @stateFlow 写入失败 -> 对本批原始行重新做字段映射 -> 按JSON Schema逐行校验 -> 有违规时包装原错误并附带违规清单 -> 执行结果记录违规清单
+ * @rules 校验只在写入失败后进行，不影响正常写入的性能和行为；包装后的错误保留原始数据库错误，失败分类仍按SQLSTATE；
+ *        没有发现违规时原样返回数据库错误
+ * @dependencies datahub-service/service/utils
+ * @refs bulk_insert.go, service/utils/json_schema.go, service/basic_library/sync_task_service.go
+ */
+
+package interface_executor
+
+import (
+	"datahub-service/service/utils"
+	"errors"
+)
+
+// PayloadValidationError 写入失败且数据不符合接口JSON Schema
+type PayloadValidationError struct {
+	Result *utils.PayloadValidationResult
+	Err    error
+}
+
+func (e *PayloadValidationError) Error() string {
+	return e.Err.Error() + "；数据不符合接口的JSON Schema: " + e.Result.Summary()
+}
+
+func (e *PayloadValidationError) Unwrap() error { return e.Err }
+
+// PayloadValidationOf 从执行错误中取出JSON Schema校验结果，错误不是由数据不合规引起时返回nil
+func PayloadValidationOf(err error) *utils.PayloadValidationResult {
+	var validationErr *PayloadValidationError
+	if errors.As(err, &validationErr) {
+		return validationErr.Result
+	}
+	return nil
+}
+
+// InterfaceJSONSchema 根据接口的表字段配置生成JSON Schema
+func InterfaceJSONSchema(interfaceInfo InterfaceInfo) *utils.JSONSchema {
+	return utils.BuildTableJSONSchema(interfaceInfo.GetName(), interfaceInfo.GetTableFieldsConfig())
+}
+
+// ValidatePayload 对源数据做字段映射后按接口的JSON Schema校验
+func (fm *FieldMapper) ValidatePayload(interfaceInfo InterfaceInfo, data []map[string]interface{}) *utils.PayloadValidationResult {
+	parseConfig := interfaceInfo.GetParseConfig()
+	mapped := make([]map[string]interface{}, len(data))
+	for i, row := range data {
+		mapped[i] = fm.ApplyFieldMapping(row, parseConfig)
+	}
+	return utils.BuildTableJSONSchema("", interfaceInfo.GetTableFieldsConfig()).ValidateRows(mapped)
+}
+
+// explainWriteFailure 写入失败时校验本批数据，有违规时把违规清单附加到错误上
+func (fm *FieldMapper) explainWriteFailure(interfaceInfo InterfaceInfo, data []map[string]interface{}, err error) error {
+	result := fm.ValidatePayload(interfaceInfo, data)
+	if result.Valid {
+		return err
+	}
+	return &PayloadValidationError{Result: result, Err: err}
+}
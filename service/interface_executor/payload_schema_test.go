@@ -0,0 +1,67 @@
+/*
+ * @module service/interface_executor/payload_schema_test
+ * @description 写入失败时按接口JSON Schema给出违规清单的测试
+ * @architecture 测试层 - 单元测试
+ */
+
+package interface_executor
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// TestBulkInsertFailureWithSchemaViolations 测试写入失败时附带违规清单，数据合规时原样返回数据库错误
+func TestBulkInsertFailureWithSchemaViolations(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.Exec(`CREATE TABLE orders (id INTEGER PRIMARY KEY, status TEXT NOT NULL)`).Error)
+
+	info := &MockInterfaceInfo{}
+	info.On("GetID").Return("iface-orders")
+	info.On("GetParseConfig").Return(map[string]interface{}{
+		"fieldMapping": []interface{}{map[string]interface{}{"source": "orderId", "target": "id"}},
+	})
+	info.On("GetTableFieldsConfig").Return([]interface{}{
+		map[string]interface{}{"name_en": "id", "data_type": "integer", "order_num": 1, "is_primary_key": true},
+		map[string]interface{}{"name_en": "status", "data_type": "varchar(20)", "order_num": 2, "is_nullable": false},
+	})
+
+	fm := NewFieldMapper()
+	tx := db.Begin()
+	_, err = fm.bulkInsert(context.Background(), tx, info, `"orders"`, []map[string]interface{}{
+		{"orderId": 1, "status": "paid"},
+		{"orderId": "x2"},
+	}, bulkConflictNone, nil)
+	tx.Rollback()
+	require.Error(t, err)
+
+	var validationErr *PayloadValidationError
+	require.True(t, errors.As(err, &validationErr))
+	assert.Contains(t, err.Error(), "插入数据失败")
+	assert.Contains(t, err.Error(), "数据不符合接口的JSON Schema: 1行2处违规")
+
+	result := PayloadValidationOf(err)
+	require.NotNil(t, result)
+	assert.Equal(t, 2, result.RowsChecked)
+	require.Len(t, result.Violations, 2)
+	assert.Equal(t, 1, result.Violations[0].Row)
+	assert.Equal(t, "status", result.Violations[0].Field)
+	assert.Equal(t, "required", result.Violations[0].Keyword)
+	assert.Equal(t, "id", result.Violations[1].Field, "按字段映射后的列名校验")
+	assert.Equal(t, "pattern", result.Violations[1].Keyword)
+
+	// 数据符合Schema时不包装数据库错误
+	tx = db.Begin()
+	require.NoError(t, tx.Exec(`INSERT INTO orders (id, status) VALUES (1, 'paid')`).Error)
+	_, err = fm.bulkInsert(context.Background(), tx, info, `"orders"`, []map[string]interface{}{{"orderId": 1, "status": "paid"}}, bulkConflictNone, nil)
+	tx.Rollback()
+	require.Error(t, err)
+	assert.Nil(t, PayloadValidationOf(err))
+}
@@ -0,0 +1,211 @@
+/*
+ * @module service/interface_executor/pipeline
+ * @description 批量同步流水线，拉取、分批、写入三个阶段并发执行，阶段之间用有界通道传递数据
+ * @architecture 管道模式 - 拉取协程 -> 行通道 -> 分批协程 -> 批次通道 -> 写入(调用方协程)
+ * @documentReference ai_docs/interface_executor.md
+ * @stateFlow 按页拉取 -> 逐行送入行通道(送出后释放页内引用) -> 按batchSize重新分批 -> 写入 -> 汇总行数、类型和警告
+ * @rules 通道有界，写入变慢时分批和拉取阶段阻塞(背压)，在途数据约为buffer+2个批次加上当前页；
+ *        数据源返回的单页超过batchSize时按batchSize拆分写入；任一阶段出错即取消其余阶段，优先报告拉取错误；
+ *        配置了自适应控制器时按耗时调整页大小和写入批大小，页号按已拉取行数/页大小换算，新页大小须整除已拉取行数；
+ *        拉取出错时缩小页大小重试，写入出错不重试(字段映射器已记录的差异和删除状态无法随事务回滚)；数据源不按请求的页大小返回时固定页大小
+ * @dependencies context, sync
+ * @refs execute_operations.go, batch_tuner.go, service/config/runtime.go
+ */
+
+package interface_executor
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// syncPage 拉取阶段获取的一页数据
+type syncPage struct {
+	Rows      []map[string]interface{}
+	DataTypes map[string]string
+	Warnings  []string
+}
+
+// syncPipeline 批量同步流水线配置
+type syncPipeline struct {
+	BatchSize int // 每批写入行数，也用于判断是否还有下一页
+	Buffer    int // 分批和写入阶段之间缓冲的批次数
+	StartPage int
+	MaxPages  int
+
+	// Fetch 按指定页号和页大小拉取数据
+	Fetch func(ctx context.Context, page, size int) (*syncPage, error)
+	// Write 写入一批数据，batch从1开始计数，返回写入行数
+	Write func(ctx context.Context, batch int, rows []map[string]interface{}) (int64, error)
+
+	// FetchTuner、WriteTuner 不为空时自适应调整页大小和写入批大小，BatchSize为初始值
+	FetchTuner *batchTuner
+	WriteTuner *batchTuner
+}
+
+// syncPipelineResult 流水线执行结果
+type syncPipelineResult struct {
+	Pages     int
+	Batches   int
+	TotalRows int64
+	DataTypes map[string]string
+	Warnings  []string
+}
+
+// syncPipelineError 流水线阶段错误，Message描述失败的阶段和批次
+type syncPipelineError struct {
+	Message string
+	Err     error
+}
+
+func (e *syncPipelineError) Error() string { return e.Message + ": " + e.Err.Error() }
+
+func (e *syncPipelineError) Unwrap() error { return e.Err }
+
+// Run 执行流水线直到数据取完、达到页数上限或出错
+func (p *syncPipeline) Run(ctx context.Context) (*syncPipelineResult, error) {
+	batchSize := p.BatchSize
+	if batchSize <= 0 {
+		batchSize = 1
+	}
+	buffer := p.Buffer
+	if buffer <= 0 {
+		buffer = 1
+	}
+
+	parent := ctx
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	result := &syncPipelineResult{}
+	rows := make(chan map[string]interface{}, batchSize)
+	batches := make(chan []map[string]interface{}, buffer)
+	var fetchErr error
+	var wg sync.WaitGroup
+
+	// 拉取阶段：逐行送出，行通道满时阻塞
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		defer close(rows)
+		size, offset, retries, pinned := batchSize, 0, 0, false
+		for {
+			page := p.StartPage + offset/size
+			started := time.Now()
+			fetched, err := p.Fetch(ctx, page, size)
+			elapsed := time.Since(started)
+			if err != nil {
+				if ctx.Err() != nil {
+					// 写入失败或调用方取消导致的拉取中断，不覆盖原始错误
+					return
+				}
+				if p.FetchTuner != nil && !pinned && retries < batchTuningMaxRetries {
+					if next := alignedPageSize(offset, size, p.FetchTuner.Observe(elapsed, err), p.FetchTuner.min); next < size {
+						slog.Warn("拉取数据失败，缩小页大小后重试", "page", page, "page_size", size, "next_page_size", next, "error", err)
+						size = next
+						retries++
+						continue
+					}
+				}
+				fetchErr = &syncPipelineError{Message: fmt.Sprintf("获取第 %d 批数据失败", page), Err: err}
+				cancel()
+				return
+			}
+			retries = 0
+			result.Pages++
+			if result.DataTypes == nil {
+				result.DataTypes = fetched.DataTypes
+			}
+			result.Warnings = append(result.Warnings, fetched.Warnings...)
+
+			for i := range fetched.Rows {
+				select {
+				case rows <- fetched.Rows[i]:
+					fetched.Rows[i] = nil
+				case <-ctx.Done():
+					return
+				}
+			}
+			if len(fetched.Rows) < size {
+				return
+			}
+			if p.MaxPages > 0 && result.Pages >= p.MaxPages {
+				result.Warnings = append(result.Warnings, "达到最大批次限制，可能还有更多数据未同步")
+				return
+			}
+			offset += size
+			if len(fetched.Rows) > size {
+				pinned = true
+			}
+			if p.FetchTuner != nil && !pinned {
+				size = alignedPageSize(offset, size, p.FetchTuner.Observe(elapsed, nil), p.FetchTuner.min)
+			}
+		}
+	}()
+
+	// 分批阶段：凑满当前写入批大小送出，批次通道满时阻塞
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		defer close(batches)
+		chunk := p.WriteTuner.Size(batchSize)
+		batch := make([]map[string]interface{}, 0, chunk)
+		send := func() bool {
+			select {
+			case batches <- batch:
+				chunk = p.WriteTuner.Size(batchSize)
+				batch = make([]map[string]interface{}, 0, chunk)
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+		for row := range rows {
+			batch = append(batch, row)
+			if len(batch) >= chunk && !send() {
+				return
+			}
+		}
+		if len(batch) > 0 && ctx.Err() == nil {
+			send()
+		}
+	}()
+
+	// 写入阶段
+	var writeErr error
+	for batch := range batches {
+		if ctx.Err() != nil {
+			continue
+		}
+		started := time.Now()
+		written, err := p.Write(ctx, result.Batches+1, batch)
+		if p.WriteTuner != nil && ctx.Err() == nil {
+			p.WriteTuner.Observe(time.Since(started), err)
+		}
+		if err != nil {
+			if _, ok := err.(*syncPipelineError); !ok {
+				err = &syncPipelineError{Message: fmt.Sprintf("写入第 %d 批数据失败", result.Batches+1), Err: err}
+			}
+			writeErr = err
+			cancel()
+			continue
+		}
+		result.Batches++
+		result.TotalRows += written
+	}
+	wg.Wait()
+
+	if fetchErr != nil {
+		return result, fetchErr
+	}
+	if writeErr != nil {
+		return result, writeErr
+	}
+	if err := parent.Err(); err != nil {
+		return result, err
+	}
+	return result, nil
+}
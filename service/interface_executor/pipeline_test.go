@@ -0,0 +1,140 @@
+/*
+ * @module service/interface_executor/pipeline_test
+ * @description 批量同步流水线测试，覆盖超大页拆分、短页结束、背压和阶段错误
+ * @architecture 测试层
+ * @documentReference ai_docs/interface_executor.md
+ * @stateFlow 构造拉取和写入函数 -> 运行流水线 -> 验证批次、行数和错误
+ * @rules 不依赖数据库，拉取和写入均为内存函数
+ * @dependencies testing, testify
+ * @refs pipeline.go
+ */
+
+package interface_executor
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// makeRows 构造n行测试数据
+func makeRows(n int) []map[string]interface{} {
+	rows := make([]map[string]interface{}, n)
+	for i := range rows {
+		rows[i] = map[string]interface{}{"id": i}
+	}
+	return rows
+}
+
+// TestSyncPipelineRebatches 测试超过batchSize的页按batchSize拆分写入，短页后停止拉取
+func TestSyncPipelineRebatches(t *testing.T) {
+	pageSizes := map[int]int{3: 25, 4: 4}
+	var fetched []int
+	var batchSizes []int
+
+	result, err := (&syncPipeline{
+		BatchSize: 10,
+		Buffer:    1,
+		StartPage: 3,
+		Fetch: func(ctx context.Context, page, size int) (*syncPage, error) {
+			fetched = append(fetched, page)
+			return &syncPage{Rows: makeRows(pageSizes[page]), DataTypes: map[string]string{"id": "integer"}, Warnings: []string{"w"}}, nil
+		},
+		Write: func(ctx context.Context, batch int, rows []map[string]interface{}) (int64, error) {
+			batchSizes = append(batchSizes, len(rows))
+			return int64(len(rows)), nil
+		},
+	}).Run(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, []int{3, 4}, fetched)
+	assert.Equal(t, []int{10, 10, 9}, batchSizes)
+	assert.Equal(t, int64(29), result.TotalRows)
+	assert.Equal(t, 3, result.Batches)
+	assert.Equal(t, 2, result.Pages)
+	assert.Equal(t, map[string]string{"id": "integer"}, result.DataTypes)
+	assert.Equal(t, []string{"w", "w"}, result.Warnings)
+}
+
+// TestSyncPipelineBackpressure 测试写入阻塞时拉取阶段不会继续读取后续页
+func TestSyncPipelineBackpressure(t *testing.T) {
+	var pages atomic.Int32
+	release := make(chan struct{})
+
+	done := make(chan *syncPipelineResult)
+	go func() {
+		result, err := (&syncPipeline{
+			BatchSize: 5,
+			Buffer:    1,
+			StartPage: 1,
+			MaxPages:  100,
+			Fetch: func(ctx context.Context, page, size int) (*syncPage, error) {
+				pages.Add(1)
+				return &syncPage{Rows: makeRows(5)}, nil
+			},
+			Write: func(ctx context.Context, batch int, rows []map[string]interface{}) (int64, error) {
+				<-release
+				return int64(len(rows)), nil
+			},
+		}).Run(context.Background())
+		assert.NoError(t, err)
+		done <- result
+	}()
+
+	time.Sleep(100 * time.Millisecond)
+	// 写入中1批 + 批次通道1批 + 分批阶段持有1批 + 行通道1批 + 拉取阶段阻塞在当前页
+	assert.LessOrEqual(t, pages.Load(), int32(5), "写入阻塞时拉取应停止")
+	close(release)
+
+	result := <-done
+	assert.Equal(t, 100, result.Pages)
+	assert.Equal(t, int64(500), result.TotalRows)
+	assert.Contains(t, result.Warnings, "达到最大批次限制，可能还有更多数据未同步")
+}
+
+// TestSyncPipelineErrors 测试写入和拉取错误终止流水线并报告出错批次
+func TestSyncPipelineErrors(t *testing.T) {
+	writeFailure := errors.New("duplicate key")
+	_, err := (&syncPipeline{
+		BatchSize: 2,
+		Buffer:    1,
+		StartPage: 1,
+		Fetch: func(ctx context.Context, page, size int) (*syncPage, error) {
+			return &syncPage{Rows: makeRows(2)}, nil
+		},
+		Write: func(ctx context.Context, batch int, rows []map[string]interface{}) (int64, error) {
+			if batch == 2 {
+				return 0, writeFailure
+			}
+			return int64(len(rows)), nil
+		},
+	}).Run(context.Background())
+
+	var pipelineErr *syncPipelineError
+	require.ErrorAs(t, err, &pipelineErr)
+	assert.Equal(t, "写入第 2 批数据失败", pipelineErr.Message)
+	assert.ErrorIs(t, err, writeFailure)
+
+	result, err := (&syncPipeline{
+		BatchSize: 2,
+		StartPage: 1,
+		Fetch: func(ctx context.Context, page, size int) (*syncPage, error) {
+			if page == 2 {
+				return nil, errors.New("connection reset")
+			}
+			return &syncPage{Rows: makeRows(2)}, nil
+		},
+		Write: func(ctx context.Context, batch int, rows []map[string]interface{}) (int64, error) {
+			return int64(len(rows)), nil
+		},
+	}).Run(context.Background())
+
+	require.ErrorAs(t, err, &pipelineErr)
+	assert.Equal(t, "获取第 2 批数据失败", pipelineErr.Message)
+	assert.Equal(t, 1, result.Pages)
+}
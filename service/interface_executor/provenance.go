@@ -0,0 +1,52 @@
+/*
+ * @module service/interface_executor/provenance
+ * @description 记录级来源追溯写入，开启后同步写入的每一行都填写来源数据源、同步执行ID和入库时间三个系统列
+ * @architecture 工具函数 - 数据写入
+ * @documentReference ai_docs/interface_executor.md
+ * @stateFlow 同步开始时按接口和执行请求确定系统列取值 -> 多行写入分组时追加到每行的列和值 -> UPSERT冲突时一并更新 -> 历史追踪覆盖非跟踪列时一并更新
+ * @rules 同一次同步的所有行取值相同，入库时间为同步开始时间；没有同步任务执行ID时写入NULL；
+ *        系统列不计入变更摘要和历史追踪的比较，只因系统列不同的行不算修改
+ * @dependencies datahub-service/service/models
+ * @refs bulk_insert.go, scd.go, sync_diff.go, service/models/provenance.go
+ */
+
+package interface_executor
+
+import (
+	"datahub-service/service/models"
+	"time"
+)
+
+// provenanceColumns 来源追溯系统列，写入顺序固定
+var provenanceColumns = []string{models.ProvenanceSourceColumn, models.ProvenanceExecutionColumn, models.ProvenanceIngestedAtColumn}
+
+// provenanceStamp 一次同步写入的来源追溯取值
+type provenanceStamp struct {
+	values []interface{} // 与provenanceColumns一一对应
+}
+
+// EnableProvenance 开启来源追溯，之后写入的每一行都填写来源数据源、同步执行ID和入库时间
+func (fm *FieldMapper) EnableProvenance(sourceID, executionID string, ingestedAt time.Time) {
+	var execution interface{}
+	if executionID != "" {
+		execution = executionID
+	}
+	var source interface{}
+	if sourceID != "" {
+		source = sourceID
+	}
+	fm.provenance = &provenanceStamp{values: []interface{}{source, execution, ingestedAt}}
+}
+
+// ProvenanceEnabled 是否开启了来源追溯
+func (fm *FieldMapper) ProvenanceEnabled() bool {
+	return fm.provenance != nil
+}
+
+// stampedColumns 历史追踪覆盖当前版本时一并更新的系统列，未开启时为nil
+func (fm *FieldMapper) stampedColumns() []string {
+	if fm.provenance == nil {
+		return nil
+	}
+	return provenanceColumns
+}
@@ -0,0 +1,278 @@
+/*
+ * @module service/interface_executor/scd
+ * @description 缓慢变化维（SCD Type 2）历史追踪写入，按自然键比较跟踪列，变化时关闭当前版本并插入新版本，不覆盖历史
+ * @architecture 工具函数 - 数据写入
+ * @documentReference ai_docs/interface_executor.md
+ * @stateFlow 按自然键去重 -> 多行写入为暂存版本(非当前、无失效时间) -> 统计新增/新版本/仅更新/未变化 -> 仅非跟踪列变化的直接更新当前版本并丢弃暂存行 ->
+ *            未变化的丢弃暂存行 -> 关闭有暂存版本的当前版本(失效时间=本批时间) -> 暂存版本置为当前(生效时间=本批时间)
+ * @rules 全量和增量同步都不清空表、不删除数据，源端删除的记录保留当前版本；取值比较在数据库中按列类型进行(IS NOT DISTINCT FROM)；
+ *        暂存行以"非当前且无失效时间"识别，整批在调用方事务内完成；加密列密文每次不同，不应作为跟踪列
+ * @dependencies gorm.io/gorm, datahub-service/service/models
+ * @refs field_mapping.go, execute_operations.go, service/models/scd.go
+ */
+
+package interface_executor
+
+import (
+	"context"
+	"datahub-service/service/models"
+	"datahub-service/service/utils"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/spf13/cast"
+	"gorm.io/gorm"
+)
+
+// scdBatchStats 一批历史追踪写入的统计
+type scdBatchStats struct {
+	Staged      int64 `gorm:"column:staged"`
+	Inserted    int64 `gorm:"column:inserted"`    // 新的自然键
+	Unchanged   int64 `gorm:"column:unchanged"`   // 取值没有变化
+	Overwritten int64 `gorm:"column:overwritten"` // 只有非跟踪列变化，直接更新当前版本
+}
+
+// scdStatements 一张表的历史追踪合并语句
+type scdStatements struct {
+	table       string
+	naturalKeys []string
+	compared    []string // 跟踪列和非跟踪列，统计列变更用
+	overwritten []string
+	stamped     []string // 覆盖当前版本时一并更新、不参与比较的来源追溯系统列
+	keyMatch    string
+	sameTracked string
+	sameOther   string
+}
+
+// EnableSCD 开启历史追踪写入，之后的UPSERT写入都按自然键合并版本
+func (fm *FieldMapper) EnableSCD(config *models.SCDConfig) {
+	fm.scd = config
+}
+
+// SCDEnabled 是否开启了历史追踪写入
+func (fm *FieldMapper) SCDEnabled() bool {
+	return fm.scd != nil
+}
+
+// mergeSCDRows 在事务内按自然键合并一批数据，返回写入的行数（去重后）
+func (fm *FieldMapper) mergeSCDRows(ctx context.Context, tx *gorm.DB, interfaceInfo InterfaceInfo, fullTableName string, data []map[string]interface{}) (int64, error) {
+	deduplicated := fm.deduplicateData(data, fm.scd.NaturalKeys, interfaceInfo)
+	if len(deduplicated) == 0 {
+		return 0, nil
+	}
+	if _, err := fm.bulkInsert(ctx, tx, interfaceInfo, fullTableName, deduplicated, bulkConflictNone, nil); err != nil {
+		return 0, err
+	}
+
+	stmts := fm.newSCDStatements(interfaceInfo, fullTableName)
+	db := tx.WithContext(ctx)
+	var stats scdBatchStats
+	if err := db.Raw(stmts.countSQL()).Scan(&stats).Error; err != nil {
+		return 0, fmt.Errorf("统计历史版本变化失败: %w", err)
+	}
+	if fm.diff != nil {
+		fm.recordSCDDiff(db, stmts, &stats)
+	}
+
+	if stats.Overwritten > 0 {
+		if err := db.Exec(stmts.overwriteSQL()).Error; err != nil {
+			return 0, fmt.Errorf("更新当前版本失败: %w", err)
+		}
+	}
+	if err := db.Exec(stmts.discardSQL()).Error; err != nil {
+		return 0, fmt.Errorf("丢弃未变化的数据失败: %w", err)
+	}
+	now := time.Now()
+	if err := db.Exec(stmts.closeSQL(), now).Error; err != nil {
+		return 0, fmt.Errorf("关闭当前版本失败: %w", err)
+	}
+	if err := db.Exec(stmts.activateSQL(), now).Error; err != nil {
+		return 0, fmt.Errorf("生效新版本失败: %w", err)
+	}
+
+	slog.Debug("mergeSCDRows - 历史追踪合并完成", "table", fullTableName, "staged", stats.Staged,
+		"inserted", stats.Inserted, "unchanged", stats.Unchanged, "overwritten", stats.Overwritten)
+	return int64(len(deduplicated)), nil
+}
+
+// recordSCDDiff 把本批的版本变化计入变更摘要：新自然键为新增，新版本和仅更新为更新
+func (fm *FieldMapper) recordSCDDiff(db *gorm.DB, stmts *scdStatements, stats *scdBatchStats) {
+	t := fm.diff
+	t.report.RowsCompared += stats.Staged
+	t.report.Inserted += stats.Inserted
+	t.report.Unchanged += stats.Unchanged
+	t.report.Updated += stats.Staged - stats.Inserted - stats.Unchanged
+
+	for _, sample := range []struct {
+		changed bool
+		keys    *models.SyncDiffKeys
+	}{{false, &t.report.InsertedKeys}, {true, &t.report.UpdatedKeys}} {
+		var rows []map[string]interface{}
+		if err := db.Raw(stmts.sampleSQL(sample.changed)).Scan(&rows).Error; err != nil {
+			slog.Warn("recordSCDDiff - 查询主键样例失败", "table", stmts.table, "error", err)
+			t.report.Incomplete = true
+			continue
+		}
+		for _, row := range rows {
+			*sample.keys = t.appendKey(*sample.keys, row)
+		}
+	}
+
+	if len(stmts.compared) == 0 {
+		return
+	}
+	var totals map[string]interface{}
+	if err := db.Raw(stmts.columnChangesSQL()).Scan(&totals).Error; err != nil {
+		slog.Warn("recordSCDDiff - 统计列变更失败", "table", stmts.table, "error", err)
+		t.report.Incomplete = true
+		return
+	}
+	for i, column := range stmts.compared {
+		if count := cast.ToInt64(totals[fmt.Sprintf("c%d", i)]); count > 0 {
+			t.columnChanges[column] += count
+		}
+	}
+}
+
+// newSCDStatements 按字段配置划分跟踪列和非跟踪列，构建合并语句的公共条件
+func (fm *FieldMapper) newSCDStatements(interfaceInfo InterfaceInfo, fullTableName string) *scdStatements {
+	isKey := make(map[string]bool, len(fm.scd.NaturalKeys))
+	for _, key := range fm.scd.NaturalKeys {
+		isKey[key] = true
+	}
+	isTracked := make(map[string]bool, len(fm.scd.TrackedColumns))
+	for _, column := range fm.scd.TrackedColumns {
+		isTracked[column] = true
+	}
+
+	var tracked, overwritten []string
+	for _, column := range fm.configuredColumns(interfaceInfo) {
+		switch {
+		case isKey[column]:
+		case len(isTracked) == 0 || isTracked[column]:
+			tracked = append(tracked, column)
+		default:
+			overwritten = append(overwritten, column)
+		}
+	}
+
+	keyMatch := make([]string, len(fm.scd.NaturalKeys))
+	for i, key := range fm.scd.NaturalKeys {
+		keyMatch[i] = fmt.Sprintf("c.%[1]s = s.%[1]s", utils.QuoteIdent(key))
+	}
+	return &scdStatements{
+		table:       fullTableName,
+		naturalKeys: fm.scd.NaturalKeys,
+		compared:    append(append([]string{}, tracked...), overwritten...),
+		overwritten: overwritten,
+		stamped:     fm.stampedColumns(),
+		keyMatch:    strings.Join(keyMatch, " AND "),
+		sameTracked: scdSameValues(tracked),
+		sameOther:   scdSameValues(overwritten),
+	}
+}
+
+// scdSameValues 各列取值相同的条件，没有列时恒为真
+func scdSameValues(columns []string) string {
+	if len(columns) == 0 {
+		return "1 = 1"
+	}
+	conditions := make([]string, len(columns))
+	for i, column := range columns {
+		conditions[i] = fmt.Sprintf("c.%[1]s IS NOT DISTINCT FROM s.%[1]s", utils.QuoteIdent(column))
+	}
+	return strings.Join(conditions, " AND ")
+}
+
+// staged 暂存行条件：非当前且没有失效时间
+func (q *scdStatements) staged(alias string) string {
+	return fmt.Sprintf(`%s."%s" = false AND %s."%s" IS NULL`, alias, models.SCDCurrentColumn, alias, models.SCDValidToColumn)
+}
+
+// current 当前版本条件
+func (q *scdStatements) current(alias string) string {
+	return fmt.Sprintf(`%s."%s" = true`, alias, models.SCDCurrentColumn)
+}
+
+// matchCurrent 暂存行s存在自然键相同的当前版本c，extra为附加条件
+func (q *scdStatements) matchCurrent(extra string) string {
+	query := fmt.Sprintf(`SELECT 1 FROM %s c WHERE %s AND %s`, q.table, q.current("c"), q.keyMatch)
+	if extra != "" {
+		query += " AND " + extra
+	}
+	return query
+}
+
+// matchStaged 当前版本c存在自然键相同的暂存行s，extra为附加条件
+func (q *scdStatements) matchStaged(extra string) string {
+	query := fmt.Sprintf(`SELECT 1 FROM %s s WHERE %s AND %s`, q.table, q.staged("s"), q.keyMatch)
+	if extra != "" {
+		query += " AND " + extra
+	}
+	return query
+}
+
+func (q *scdStatements) countSQL() string {
+	return fmt.Sprintf(`SELECT COUNT(*) AS staged,
+		COALESCE(SUM(CASE WHEN NOT EXISTS (%s) THEN 1 ELSE 0 END), 0) AS inserted,
+		COALESCE(SUM(CASE WHEN EXISTS (%s) THEN 1 ELSE 0 END), 0) AS unchanged,
+		COALESCE(SUM(CASE WHEN EXISTS (%s) THEN 1 ELSE 0 END), 0) AS overwritten
+		FROM %s s WHERE %s`,
+		q.matchCurrent(""),
+		q.matchCurrent(q.sameTracked+" AND "+q.sameOther),
+		q.matchCurrent(q.sameTracked+" AND NOT ("+q.sameOther+")"),
+		q.table, q.staged("s"))
+}
+
+// sampleSQL 新自然键（changed=false）或有变化的自然键（changed=true）的样例
+func (q *scdStatements) sampleSQL(changed bool) string {
+	keys := make([]string, len(q.naturalKeys))
+	for i, key := range q.naturalKeys {
+		keys[i] = "s." + utils.QuoteIdent(key)
+	}
+	condition := "NOT EXISTS (" + q.matchCurrent("") + ")"
+	if changed {
+		condition = "EXISTS (" + q.matchCurrent("NOT ("+q.sameTracked+" AND "+q.sameOther+")") + ")"
+	}
+	return fmt.Sprintf(`SELECT %s FROM %s s WHERE %s AND %s LIMIT %d`,
+		strings.Join(keys, ", "), q.table, q.staged("s"), condition, models.SyncDiffKeySampleLimit)
+}
+
+func (q *scdStatements) columnChangesSQL() string {
+	sums := make([]string, len(q.compared))
+	for i, column := range q.compared {
+		sums[i] = fmt.Sprintf("COALESCE(SUM(CASE WHEN c.%[1]s IS DISTINCT FROM s.%[1]s THEN 1 ELSE 0 END), 0) AS c%[2]d", utils.QuoteIdent(column), i)
+	}
+	return fmt.Sprintf(`SELECT %s FROM %s s JOIN %s c ON %s AND %s WHERE %s`,
+		strings.Join(sums, ", "), q.table, q.table, q.current("c"), q.keyMatch, q.staged("s"))
+}
+
+// overwriteSQL 跟踪列未变化时用暂存行的非跟踪列更新当前版本
+func (q *scdStatements) overwriteSQL() string {
+	columns := append(append([]string{}, q.overwritten...), q.stamped...)
+	sets := make([]string, len(columns))
+	for i, column := range columns {
+		sets[i] = fmt.Sprintf("%[1]s = (SELECT s.%[1]s FROM %[2]s s WHERE %[3]s AND %[4]s)", utils.QuoteIdent(column), q.table, q.staged("s"), q.keyMatch)
+	}
+	return fmt.Sprintf(`UPDATE %s AS c SET %s WHERE %s AND EXISTS (%s)`,
+		q.table, strings.Join(sets, ", "), q.current("c"), q.matchStaged(q.sameTracked+" AND NOT ("+q.sameOther+")"))
+}
+
+// discardSQL 删除跟踪列没有变化的暂存行
+func (q *scdStatements) discardSQL() string {
+	return fmt.Sprintf(`DELETE FROM %s AS s WHERE %s AND EXISTS (%s)`, q.table, q.staged("s"), q.matchCurrent(q.sameTracked))
+}
+
+// closeSQL 关闭有新版本的当前版本
+func (q *scdStatements) closeSQL() string {
+	return fmt.Sprintf(`UPDATE %s AS c SET "%s" = false, "%s" = ? WHERE %s AND EXISTS (%s)`,
+		q.table, models.SCDCurrentColumn, models.SCDValidToColumn, q.current("c"), q.matchStaged(""))
+}
+
+// activateSQL 暂存行成为当前版本
+func (q *scdStatements) activateSQL() string {
+	return fmt.Sprintf(`UPDATE %s SET "%s" = true, "%s" = ? WHERE "%s" = false AND "%s" IS NULL`,
+		q.table, models.SCDCurrentColumn, models.SCDValidFromColumn, models.SCDCurrentColumn, models.SCDValidToColumn)
+}
@@ -0,0 +1,91 @@
+/*
+ * @module service/interface_executor/scd_test
+ * @description 历史追踪写入测试，覆盖新自然键插入、跟踪列变化产生新版本、只有非跟踪列变化时更新当前版本以及未变化的行不产生版本
+ * @architecture 测试层 - 单元测试
+ */
+
+package interface_executor
+
+import (
+	"context"
+	"datahub-service/service/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+type scdVersion struct {
+	Plate   string
+	Owner   string
+	Color   string
+	Current bool `gorm:"column:_scd_current"`
+	Closed  bool `gorm:"column:closed"`
+}
+
+func TestMergeSCDRows(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.Exec(`CREATE TABLE vehicles (
+		_scd_key INTEGER PRIMARY KEY AUTOINCREMENT, plate TEXT NOT NULL, owner TEXT, color TEXT,
+		_scd_valid_from DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP, _scd_valid_to DATETIME, _scd_current BOOLEAN NOT NULL DEFAULT false)`).Error)
+
+	info := &MockInterfaceInfo{}
+	info.On("GetID").Return("iface-vehicles")
+	info.On("GetParseConfig").Return(map[string]interface{}{})
+	tableFields := []interface{}{
+		map[string]interface{}{"name_en": "plate", "data_type": "varchar", "order_num": 1},
+		map[string]interface{}{"name_en": "owner", "data_type": "varchar", "order_num": 2},
+		map[string]interface{}{"name_en": "color", "data_type": "varchar", "order_num": 3},
+	}
+	for _, field := range models.SCDSystemFields(3) {
+		tableFields = append(tableFields, map[string]interface{}{"name_en": field.NameEn, "data_type": field.DataType, "order_num": field.OrderNum})
+	}
+	info.On("GetTableFieldsConfig").Return(tableFields)
+
+	fm := NewFieldMapper()
+	fm.EnableSCD(&models.SCDConfig{Enabled: true, NaturalKeys: []string{"plate"}, TrackedColumns: []string{"owner"}})
+	merge := func(rows []map[string]interface{}) {
+		tx := db.Begin()
+		_, err := fm.mergeSCDRows(context.Background(), tx, info, `"vehicles"`, rows)
+		require.NoError(t, err)
+		require.NoError(t, tx.Commit().Error)
+	}
+
+	merge([]map[string]interface{}{
+		{"plate": "A1", "owner": "alice", "color": "red"},
+		{"plate": "B2", "owner": "bob", "color": "blue"},
+	})
+	fm.EnableSyncDiff()
+	merge([]map[string]interface{}{
+		{"plate": "A1", "owner": "alice", "color": "green"}, // 只有非跟踪列变化
+		{"plate": "B2", "owner": "bob", "color": "blue"},    // 会被后面的同一自然键覆盖
+		{"plate": "B2", "owner": "carol", "color": "blue"},  // 跟踪列变化
+		{"plate": "C3", "owner": "dan", "color": "white"},   // 新自然键
+	})
+	merge([]map[string]interface{}{{"plate": "C3", "owner": "dan", "color": "white"}})
+
+	var versions []scdVersion
+	require.NoError(t, db.Raw(`SELECT plate, owner, color, _scd_current, _scd_valid_to IS NOT NULL AS closed FROM vehicles ORDER BY plate, _scd_key`).Scan(&versions).Error)
+	assert.Equal(t, []scdVersion{
+		{Plate: "A1", Owner: "alice", Color: "green", Current: true},
+		{Plate: "B2", Owner: "bob", Color: "blue", Closed: true},
+		{Plate: "B2", Owner: "carol", Color: "blue", Current: true},
+		{Plate: "C3", Owner: "dan", Color: "white", Current: true},
+	}, versions)
+
+	var sameTime int64
+	require.NoError(t, db.Raw(`SELECT COUNT(*) FROM vehicles o JOIN vehicles n ON o.plate = n.plate AND o._scd_valid_to = n._scd_valid_from WHERE o.plate = 'B2'`).Scan(&sameTime).Error)
+	assert.Equal(t, int64(1), sameTime, "旧版本的失效时间等于新版本的生效时间")
+
+	report := fm.SyncDiffReport()
+	assert.Equal(t, int64(4), report.RowsCompared)
+	assert.Equal(t, int64(1), report.Inserted)
+	assert.Equal(t, int64(2), report.Updated)
+	assert.Equal(t, int64(1), report.Unchanged)
+	assert.Len(t, report.InsertedKeys, 1)
+	assert.Len(t, report.UpdatedKeys, 2)
+	assert.Equal(t, models.SyncColumnChanges{{Column: "color", Changed: 1}, {Column: "owner", Changed: 1}}, report.ColumnChanges)
+}
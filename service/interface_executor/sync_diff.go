@@ -0,0 +1,305 @@
+/*
+ * @module service/interface_executor/sync_diff
+ * @description 增量同步的数据变更摘要，写入前后按主键读取受影响的行并比较，统计新增、更新、未变化的行数和变更最多的列
+ * @architecture 工具函数 - 数据写入
+ * @documentReference ai_docs/interface_executor.md
+ * @stateFlow 每批UPSERT前按主键读取已有行(to_jsonb) -> UPSERT -> 再次读取 -> 写入前不存在为新增、取值不同为更新 -> 按批累计 -> 摘要随执行响应返回
+ * @rules 只在增量同步（UPSERT）时统计，增量同步不删除数据，开启源端删除同步时另计删除行数；读取在保存点内执行，失败时回滚到保存点、不影响本批写入，摘要标记为不完整；
+ *        两次读取都经过to_jsonb，比较不受Go类型差异影响；加密列每次写入密文都不同，前后都是密文时视为未变化
+ * @dependencies gorm.io/gorm, datahub-service/service/encryption
+ * @refs field_mapping.go, execute_operations.go, service/models/sync_execution_diff.go
+ */
+
+package interface_executor
+
+import (
+	"bytes"
+	"context"
+	"datahub-service/service/encryption"
+	"datahub-service/service/models"
+	"datahub-service/service/utils"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"reflect"
+	"sort"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// syncDiffSelectChunk 按主键读取时每条语句的主键个数
+	syncDiffSelectChunk = 500
+	// syncDiffTopColumns 变更摘要中保留的列数
+	syncDiffTopColumns = 20
+	// syncDiffSavePoint 读取变更用的保存点
+	syncDiffSavePoint = "sync_diff"
+)
+
+// SyncDiffReport 一次增量同步的数据变更摘要
+type SyncDiffReport struct {
+	RowsCompared  int64                    `json:"rows_compared"`
+	Inserted      int64                    `json:"inserted"`
+	Updated       int64                    `json:"updated"`
+	Unchanged     int64                    `json:"unchanged"`
+	InsertedKeys  models.SyncDiffKeys      `json:"inserted_keys"`
+	UpdatedKeys   models.SyncDiffKeys      `json:"updated_keys"`
+	Deleted       int64                    `json:"deleted"` // 源端删除同步处理的行数
+	DeletedKeys   models.SyncDiffKeys      `json:"deleted_keys"`
+	KeysTruncated bool                     `json:"keys_truncated"`
+	ColumnChanges models.SyncColumnChanges `json:"column_changes"`
+	Incomplete    bool                     `json:"incomplete"`
+}
+
+// syncDiffTracker 变更统计状态，随字段映射器在一次同步内累计
+type syncDiffTracker struct {
+	report        SyncDiffReport
+	columnChanges map[string]int64
+}
+
+// newSyncDiffReport 创建空的变更摘要
+func newSyncDiffReport() *SyncDiffReport {
+	return &SyncDiffReport{
+		InsertedKeys:  models.SyncDiffKeys{},
+		UpdatedKeys:   models.SyncDiffKeys{},
+		DeletedKeys:   models.SyncDiffKeys{},
+		ColumnChanges: models.SyncColumnChanges{},
+	}
+}
+
+// EnableSyncDiff 开启变更摘要统计，之后的UPSERT写入都会比较写入前后的数据
+func (fm *FieldMapper) EnableSyncDiff() {
+	fm.diff = &syncDiffTracker{
+		report:        *newSyncDiffReport(),
+		columnChanges: make(map[string]int64),
+	}
+}
+
+// SyncDiffReport 返回累计的变更摘要，未开启统计时返回nil
+func (fm *FieldMapper) SyncDiffReport() *SyncDiffReport {
+	if fm.diff == nil {
+		return nil
+	}
+	report := fm.diff.report
+	report.ColumnChanges = topColumnChanges(fm.diff.columnChanges, syncDiffTopColumns)
+	return &report
+}
+
+// attachDiffReport 把变更摘要放入执行响应的元数据
+func (fm *FieldMapper) attachDiffReport(metadata map[string]interface{}) map[string]interface{} {
+	if report := fm.SyncDiffReport(); report != nil {
+		metadata["diff"] = report
+	}
+	return metadata
+}
+
+// SyncDiffReportOf 从执行响应中取出变更摘要，没有时返回nil
+func SyncDiffReportOf(response *ExecuteResponse) *SyncDiffReport {
+	if response == nil || response.Metadata == nil {
+		return nil
+	}
+	report, _ := response.Metadata["diff"].(*SyncDiffReport)
+	return report
+}
+
+// upsertRows 按主键UPSERT一批已去重的数据，开启变更统计时比较写入前后的行
+func (fm *FieldMapper) upsertRows(ctx context.Context, tx *gorm.DB, interfaceInfo InterfaceInfo, fullTableName string, data []map[string]interface{}, primaryKeys []string) error {
+	if fm.diff == nil {
+		if fm.deletes != nil {
+			fm.deletes.incomplete = true
+		}
+		_, err := fm.bulkInsert(ctx, tx, interfaceInfo, fullTableName, data, bulkConflictUpdate, primaryKeys)
+		return err
+	}
+
+	keys := fm.diffKeyValues(interfaceInfo, data, primaryKeys)
+	before, ok := fm.snapshotDiffRows(ctx, tx, fullTableName, primaryKeys, keys)
+	if _, err := fm.bulkInsert(ctx, tx, interfaceInfo, fullTableName, data, bulkConflictUpdate, primaryKeys); err != nil {
+		return err
+	}
+	if !ok {
+		if fm.deletes != nil {
+			fm.deletes.incomplete = true
+		}
+		return nil
+	}
+	after, ok := fm.snapshotDiffRows(ctx, tx, fullTableName, primaryKeys, keys)
+	if ok {
+		fm.diff.compare(primaryKeys, before, after)
+		if fm.deletes != nil {
+			fm.deletes.recordSeenKeys(primaryKeys, after)
+		}
+	} else if fm.deletes != nil {
+		fm.deletes.incomplete = true
+	}
+	return nil
+}
+
+// diffKeyValues 取出每行映射、类型转换后的主键值，主键不完整的行跳过
+func (fm *FieldMapper) diffKeyValues(interfaceInfo InterfaceInfo, data []map[string]interface{}, primaryKeys []string) [][]interface{} {
+	parseConfig := interfaceInfo.GetParseConfig()
+	keys := make([][]interface{}, 0, len(data))
+	for _, row := range data {
+		mappedRow := fm.ApplyFieldMapping(row, parseConfig, false)
+		values := make([]interface{}, 0, len(primaryKeys))
+		for _, pk := range primaryKeys {
+			value, exists := mappedRow[pk]
+			if !exists || value == nil {
+				break
+			}
+			values = append(values, fm.convertValueByDataType(value, fm.getFieldDataType(pk, interfaceInfo), pk, false))
+		}
+		if len(values) == len(primaryKeys) {
+			keys = append(keys, values)
+		}
+	}
+	return keys
+}
+
+// snapshotDiffRows 在保存点内按主键读取行；读取失败时回滚到保存点并标记摘要不完整
+func (fm *FieldMapper) snapshotDiffRows(ctx context.Context, tx *gorm.DB, fullTableName string, primaryKeys []string, keys [][]interface{}) ([]map[string]interface{}, bool) {
+	if err := tx.SavePoint(syncDiffSavePoint).Error; err != nil {
+		slog.Warn("snapshotDiffRows - 创建保存点失败，本批不统计变更", "table", fullTableName, "error", err)
+		fm.diff.report.Incomplete = true
+		return nil, false
+	}
+
+	keyColumns := utils.QuoteIdentList(primaryKeys)
+	tuple := "(" + strings.TrimSuffix(strings.Repeat("?, ", len(primaryKeys)), ", ") + ")"
+
+	var result []map[string]interface{}
+	for start := 0; start < len(keys); start += syncDiffSelectChunk {
+		end := start + syncDiffSelectChunk
+		if end > len(keys) {
+			end = len(keys)
+		}
+		tuples := make([]string, 0, end-start)
+		args := make([]interface{}, 0, (end-start)*len(primaryKeys))
+		for _, key := range keys[start:end] {
+			tuples = append(tuples, tuple)
+			args = append(args, key...)
+		}
+		query := fmt.Sprintf(`SELECT to_jsonb(t)::text FROM %s t WHERE (%s) IN (%s) ORDER BY %s`,
+			fullTableName, keyColumns, strings.Join(tuples, ", "), keyColumns)
+
+		rows, err := decodeDiffRows(tx.WithContext(ctx), query, args)
+		if err != nil {
+			slog.Warn("snapshotDiffRows - 读取变更失败，本批不统计变更", "table", fullTableName, "error", err)
+			tx.RollbackTo(syncDiffSavePoint)
+			fm.diff.report.Incomplete = true
+			return nil, false
+		}
+		result = append(result, rows...)
+	}
+	return result, true
+}
+
+// decodeDiffRows 执行查询并把每行的JSON解析为映射，数字保留原始文本
+func decodeDiffRows(db *gorm.DB, query string, args []interface{}) ([]map[string]interface{}, error) {
+	rows, err := db.Raw(query, args...).Rows()
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []map[string]interface{}
+	for rows.Next() {
+		var text string
+		if err := rows.Scan(&text); err != nil {
+			return nil, err
+		}
+		decoder := json.NewDecoder(bytes.NewReader([]byte(text)))
+		decoder.UseNumber()
+		row := make(map[string]interface{})
+		if err := decoder.Decode(&row); err != nil {
+			return nil, err
+		}
+		result = append(result, row)
+	}
+	return result, rows.Err()
+}
+
+// compare 比较一批写入前后的行并累计到摘要
+func (t *syncDiffTracker) compare(primaryKeys []string, before, after []map[string]interface{}) {
+	existing := make(map[string]map[string]interface{}, len(before))
+	for _, row := range before {
+		existing[diffRowKey(row, primaryKeys)] = row
+	}
+
+	for _, row := range after {
+		t.report.RowsCompared++
+		key := make(map[string]interface{}, len(primaryKeys))
+		for _, pk := range primaryKeys {
+			key[pk] = row[pk]
+		}
+
+		old, found := existing[diffRowKey(row, primaryKeys)]
+		if !found {
+			t.report.Inserted++
+			t.report.InsertedKeys = t.appendKey(t.report.InsertedKeys, key)
+			continue
+		}
+
+		changed := false
+		for column, value := range row {
+			if models.IsProvenanceColumn(column) || diffValueEqual(old[column], value) {
+				continue
+			}
+			changed = true
+			t.columnChanges[column]++
+		}
+		if changed {
+			t.report.Updated++
+			t.report.UpdatedKeys = t.appendKey(t.report.UpdatedKeys, key)
+		} else {
+			t.report.Unchanged++
+		}
+	}
+}
+
+// appendKey 追加主键样例，超过上限时标记截断
+func (t *syncDiffTracker) appendKey(keys models.SyncDiffKeys, key map[string]interface{}) models.SyncDiffKeys {
+	if len(keys) >= models.SyncDiffKeySampleLimit {
+		t.report.KeysTruncated = true
+		return keys
+	}
+	return append(keys, key)
+}
+
+// diffRowKey 主键组合，两次读取都来自to_jsonb，取值格式一致
+func diffRowKey(row map[string]interface{}, primaryKeys []string) string {
+	parts := make([]string, len(primaryKeys))
+	for i, pk := range primaryKeys {
+		parts[i] = fmt.Sprintf("%v", row[pk])
+	}
+	return strings.Join(parts, "||")
+}
+
+// diffValueEqual 比较同一列写入前后的取值，前后都是密文时视为相同
+func diffValueEqual(old, current interface{}) bool {
+	if reflect.DeepEqual(old, current) {
+		return true
+	}
+	oldText, oldOK := old.(string)
+	currentText, currentOK := current.(string)
+	return oldOK && currentOK && encryption.IsCiphertext(oldText) && encryption.IsCiphertext(currentText)
+}
+
+// topColumnChanges 按修改行数倒序取前limit列，行数相同时按列名排序
+func topColumnChanges(counts map[string]int64, limit int) models.SyncColumnChanges {
+	changes := make(models.SyncColumnChanges, 0, len(counts))
+	for column, changed := range counts {
+		changes = append(changes, models.SyncColumnChange{Column: column, Changed: changed})
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		if changes[i].Changed != changes[j].Changed {
+			return changes[i].Changed > changes[j].Changed
+		}
+		return changes[i].Column < changes[j].Column
+	})
+	if len(changes) > limit {
+		changes = changes[:limit]
+	}
+	return changes
+}
@@ -0,0 +1,102 @@
+/*
+ * @module service/interface_executor/sync_diff_test
+ * @description 增量同步变更摘要测试，覆盖新增/更新/未变化的判定、复合主键、密文列、列变更排序和主键样例截断，以及读取变更失败时不影响写入
+ * @architecture 测试层 - 单元测试
+ */
+
+package interface_executor
+
+import (
+	"context"
+	"datahub-service/service/models"
+	"encoding/json"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestSyncDiffCompare(t *testing.T) {
+	fm := NewFieldMapper()
+	fm.EnableSyncDiff()
+	primaryKeys := []string{"org", "id"}
+
+	before := []map[string]interface{}{
+		{"org": "a", "id": json.Number("1"), "status": "paid", "amount": json.Number("10.5"), "phone": "enc:aes:v1:x1"},
+		{"org": "a", "id": json.Number("2"), "status": "paid", "amount": json.Number("3"), "phone": nil},
+	}
+	after := []map[string]interface{}{
+		{"org": "a", "id": json.Number("1"), "status": "refunded", "amount": json.Number("9"), "phone": "enc:aes:v1:x2"},
+		{"org": "a", "id": json.Number("2"), "status": "paid", "amount": json.Number("3"), "phone": nil},
+		{"org": "b", "id": json.Number("1"), "status": "paid", "amount": json.Number("1"), "phone": nil},
+	}
+	fm.diff.compare(primaryKeys, before, after)
+	fm.diff.compare(primaryKeys, nil, []map[string]interface{}{
+		{"org": "b", "id": json.Number("2"), "status": "paid", "amount": json.Number("1"), "phone": nil},
+	})
+
+	report := fm.SyncDiffReport()
+	require.NotNil(t, report)
+	assert.Equal(t, int64(4), report.RowsCompared)
+	assert.Equal(t, int64(2), report.Inserted)
+	assert.Equal(t, int64(1), report.Updated)
+	assert.Equal(t, int64(1), report.Unchanged)
+	assert.Equal(t, models.SyncDiffKeys{{"org": "a", "id": json.Number("1")}}, report.UpdatedKeys)
+	assert.Equal(t, models.SyncDiffKeys{{"org": "b", "id": json.Number("1")}, {"org": "b", "id": json.Number("2")}}, report.InsertedKeys)
+	// 密文每次写入都不同，不算变更
+	assert.Equal(t, models.SyncColumnChanges{{Column: "amount", Changed: 1}, {Column: "status", Changed: 1}}, report.ColumnChanges)
+	assert.False(t, report.KeysTruncated)
+
+	metadata := fm.attachDiffReport(map[string]interface{}{})
+	assert.Equal(t, report, SyncDiffReportOf(&ExecuteResponse{Metadata: metadata}))
+	assert.Nil(t, SyncDiffReportOf(&ExecuteResponse{}))
+	assert.Nil(t, NewFieldMapper().SyncDiffReport(), "未开启统计")
+}
+
+func TestSyncDiffKeySampleLimit(t *testing.T) {
+	fm := NewFieldMapper()
+	fm.EnableSyncDiff()
+	after := make([]map[string]interface{}, 0, models.SyncDiffKeySampleLimit+5)
+	for i := 0; i < models.SyncDiffKeySampleLimit+5; i++ {
+		after = append(after, map[string]interface{}{"id": json.Number(strconv.Itoa(i))})
+	}
+	fm.diff.compare([]string{"id"}, nil, after)
+
+	report := fm.SyncDiffReport()
+	assert.Equal(t, int64(models.SyncDiffKeySampleLimit+5), report.Inserted)
+	assert.Len(t, report.InsertedKeys, models.SyncDiffKeySampleLimit)
+	assert.True(t, report.KeysTruncated)
+}
+
+// TestUpsertRowsDiffUnavailable sqlite没有to_jsonb，读取变更失败时回滚到保存点，本批照常写入并标记摘要不完整
+func TestUpsertRowsDiffUnavailable(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.Exec(`CREATE TABLE orders (id INTEGER PRIMARY KEY, status TEXT)`).Error)
+	require.NoError(t, db.Exec(`INSERT INTO orders (id, status) VALUES (1, 'paid')`).Error)
+
+	info := &MockInterfaceInfo{}
+	info.On("GetID").Return("iface-orders")
+	info.On("GetParseConfig").Return(map[string]interface{}{})
+	info.On("GetTableFieldsConfig").Return([]interface{}{
+		map[string]interface{}{"name_en": "id", "data_type": "integer", "order_num": 1, "is_primary_key": true},
+		map[string]interface{}{"name_en": "status", "data_type": "varchar", "order_num": 2},
+	})
+
+	fm := NewFieldMapper()
+	fm.EnableSyncDiff()
+	tx := db.Begin()
+	rows := []map[string]interface{}{{"id": 1, "status": "refunded"}, {"id": 2, "status": "paid"}}
+	require.NoError(t, fm.upsertRows(context.Background(), tx, info, `"orders"`, rows, []string{"id"}))
+	require.NoError(t, tx.Commit().Error)
+
+	var statuses []string
+	require.NoError(t, db.Raw(`SELECT status FROM orders ORDER BY id`).Scan(&statuses).Error)
+	assert.Equal(t, []string{"refunded", "paid"}, statuses)
+	report := fm.SyncDiffReport()
+	assert.True(t, report.Incomplete)
+	assert.Equal(t, int64(0), report.RowsCompared)
+}
@@ -0,0 +1,192 @@
+/*
+ * @module service/interface_executor/table_columns
+ * @description 从表字段配置推导稳定的列顺序，写入前按配置校验数据行并统计配置外字段和缺失字段
+ * @architecture 工具函数 - 数据写入
+ * @documentReference ai_docs/interface_executor.md
+ * @stateFlow 表字段配置 -> 按order_num排序的列清单(按接口缓存) -> 逐行校验 -> 配置外字段丢弃并计数、缺失字段计数 -> 汇总为同步警告
+ * @rules 支持field_N键值的TableField格式和fields数组的field_name格式；缺失字段不写入，保留列默认值；
+ *        派生列由数据库生成或写入后刷新，不在写入列中，数据中的同名字段直接丢弃；
+ *        字段配置为空时不做校验，列按名称排序
+ * @dependencies encoding/json, datahub-service/service/models
+ * @refs bulk_insert.go, field_mapping.go, service/basic_library/interface_service.go
+ */
+
+package interface_executor
+
+import (
+	"context"
+	"datahub-service/service/database"
+	"datahub-service/service/models"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// fieldValidationReport 字段校验统计，字段名 -> 行数
+type fieldValidationReport struct {
+	unexpected map[string]int
+	missing    map[string]int
+	// 时区规范化统计
+	zoneMismatch      map[string]int
+	unparsedTime      map[string]int
+	timezoneConfigErr string
+}
+
+// configuredColumns 获取字段配置中要写入的列，按order_num和名称排序，派生列记入computedCache，历史追踪系统列、软删除列和来源追溯系统列不从同步数据写入，结果按接口缓存
+func (fm *FieldMapper) configuredColumns(interfaceInfo InterfaceInfo) []string {
+	interfaceID := interfaceInfo.GetID()
+	if cached, exists := fm.columnCache[interfaceID]; exists {
+		return cached
+	}
+
+	type orderedColumn struct {
+		name  string
+		order int
+	}
+	var ordered []orderedColumn
+	var computed []models.TableField
+	seen := make(map[string]bool)
+	add := func(name string, order int) {
+		if name == "" || seen[name] {
+			return
+		}
+		seen[name] = true
+		ordered = append(ordered, orderedColumn{name: name, order: order})
+	}
+
+	for _, item := range interfaceInfo.GetTableFieldsConfig() {
+		fieldMap, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if fieldsArray, ok := fieldMap["fields"].([]interface{}); ok {
+			for i, fieldData := range fieldsArray {
+				if legacy, ok := fieldData.(map[string]interface{}); ok {
+					name, _ := legacy["field_name"].(string)
+					add(name, i)
+				}
+			}
+			continue
+		}
+		if name, ok := fieldMap["field_name"].(string); ok {
+			add(name, len(ordered))
+			continue
+		}
+
+		var field models.TableField
+		data, _ := json.Marshal(fieldMap)
+		if err := json.Unmarshal(data, &field); err != nil {
+			continue
+		}
+		if field.IsComputed() {
+			computed = append(computed, field)
+			continue
+		}
+		if models.IsSCDColumn(field.NameEn) || field.NameEn == models.SoftDeleteColumn || models.IsProvenanceColumn(field.NameEn) {
+			// 历史追踪系统列由合并版本时维护，软删除列由删除同步维护，来源追溯系统列由同步填写
+			continue
+		}
+		add(field.NameEn, field.OrderNum)
+	}
+
+	sort.SliceStable(ordered, func(i, j int) bool {
+		if ordered[i].order != ordered[j].order {
+			return ordered[i].order < ordered[j].order
+		}
+		return ordered[i].name < ordered[j].name
+	})
+	columns := make([]string, len(ordered))
+	for i, column := range ordered {
+		columns[i] = column.name
+	}
+
+	fm.columnCache[interfaceID] = columns
+	fm.computedCache[interfaceID] = computed
+	return columns
+}
+
+// computedFields 获取字段配置中的派生列
+func (fm *FieldMapper) computedFields(interfaceInfo InterfaceInfo) []models.TableField {
+	fm.configuredColumns(interfaceInfo)
+	return fm.computedCache[interfaceInfo.GetID()]
+}
+
+// refreshComputedColumns 写入后按表达式刷新同步刷新列，生成列由数据库维护
+func (fm *FieldMapper) refreshComputedColumns(ctx context.Context, tx *gorm.DB, interfaceInfo InterfaceInfo, fullTableName string) error {
+	refreshSQL := database.BuildComputedRefreshSQL(fullTableName, fm.computedFields(interfaceInfo))
+	if refreshSQL == "" {
+		return nil
+	}
+	slog.Debug("refreshComputedColumns - 刷新派生列", "sql", refreshSQL)
+	if err := tx.WithContext(ctx).Exec(refreshSQL).Error; err != nil {
+		return fmt.Errorf("刷新派生列失败: %w", err)
+	}
+	return nil
+}
+
+// orderRowColumns 按字段配置确定一行要写入的列，配置外字段和缺失字段计入统计；无字段配置时按名称排序
+func (fm *FieldMapper) orderRowColumns(row map[string]interface{}, configured []string) []string {
+	if len(configured) == 0 {
+		columns := make([]string, 0, len(row))
+		for col := range row {
+			columns = append(columns, col)
+		}
+		sort.Strings(columns)
+		return columns
+	}
+
+	columns := make([]string, 0, len(row))
+	for _, col := range configured {
+		if _, ok := row[col]; ok {
+			columns = append(columns, col)
+		} else {
+			fm.fieldReport.missing[col]++
+		}
+	}
+	if len(columns) < len(row) {
+		known := make(map[string]bool, len(configured))
+		for _, col := range configured {
+			known[col] = true
+		}
+		for col := range row {
+			if !known[col] {
+				fm.fieldReport.unexpected[col]++
+			}
+		}
+	}
+	return columns
+}
+
+// FieldValidationWarnings 返回累计的字段校验、时区规范化和数据契约警告，按字段名排序
+func (fm *FieldMapper) FieldValidationWarnings() []string {
+	var warnings []string
+	if summary := summarizeFieldCounts(fm.fieldReport.unexpected); summary != "" {
+		warnings = append(warnings, "字段配置中不存在的字段未写入: "+summary)
+	}
+	if summary := summarizeFieldCounts(fm.fieldReport.missing); summary != "" {
+		warnings = append(warnings, "数据缺少字段配置中的字段，使用列默认值: "+summary)
+	}
+	warnings = append(warnings, fm.timezoneWarnings()...)
+	return append(warnings, fm.contractWarnings()...)
+}
+
+// summarizeFieldCounts 格式化为 "字段(N行), ..."
+func summarizeFieldCounts(counts map[string]int) string {
+	if len(counts) == 0 {
+		return ""
+	}
+	names := make([]string, 0, len(counts))
+	for name := range counts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	parts := make([]string, len(names))
+	for i, name := range names {
+		parts[i] = fmt.Sprintf("%s(%d行)", name, counts[name])
+	}
+	return strings.Join(parts, ", ")
+}
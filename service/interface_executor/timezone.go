@@ -0,0 +1,80 @@
+/*
+ * @module service/interface_executor/timezone
+ * @description 写入前按接口时区策略规范化时间字段，时区不一致和无法解析的值计入同步警告
+ * @architecture 工具函数 - 数据写入
+ * @documentReference ai_docs/interface_executor.md
+ * @stateFlow parseConfig.timezone -> 时区策略(按接口缓存) -> 时间/日期字段值 -> 转换到目标时区 -> 按列类型格式化
+ * @rules 未配置时区策略时保持原有转换；timestamptz列输出带偏移的时间，timestamp/datetime列输出目标时区的本地时间，
+ *        date列取目标时区的日期；策略配置无效时不启用并在警告中说明；无法解析的值原样写入
+ * @dependencies datahub-service/service/utils
+ * @refs field_mapping.go, table_columns.go, service/utils/timezone_policy.go
+ */
+
+package interface_executor
+
+import (
+	"datahub-service/service/utils"
+	"fmt"
+	"log/slog"
+	"strings"
+)
+
+// timezonePolicy 获取接口的时区策略，结果按接口缓存，未配置或配置无效时返回nil
+func (fm *FieldMapper) timezonePolicy(interfaceInfo InterfaceInfo) *utils.TimezonePolicy {
+	interfaceID := interfaceInfo.GetID()
+	if policy, exists := fm.timezoneCache[interfaceID]; exists {
+		return policy
+	}
+
+	policy, err := utils.ParseTimezonePolicy(interfaceInfo.GetParseConfig())
+	if err != nil {
+		slog.Warn("timezonePolicy - 时区配置无效，不做时区规范化", "interface_id", interfaceID, "error", err)
+		fm.fieldReport.timezoneConfigErr = err.Error()
+	}
+	fm.timezoneCache[interfaceID] = policy
+	return policy
+}
+
+// normalizeTimeValue 按接口时区策略转换时间和日期字段，不是时间类型的列或未配置策略时返回false
+func (fm *FieldMapper) normalizeTimeValue(interfaceInfo InterfaceInfo, columnName, dataType string, value interface{}) (interface{}, bool) {
+	var layout string
+	switch strings.ToLower(dataType) {
+	case "timestamptz":
+		layout = "2006-01-02 15:04:05.000-07:00"
+	case "timestamp", "datetime":
+		layout = "2006-01-02 15:04:05.000"
+	case "date":
+		layout = "2006-01-02"
+	default:
+		return nil, false
+	}
+	policy := fm.timezonePolicy(interfaceInfo)
+	if policy == nil {
+		return nil, false
+	}
+
+	parsed, mismatch, err := policy.ParseTime(value)
+	if err != nil {
+		fm.fieldReport.unparsedTime[columnName]++
+		return value, true
+	}
+	if mismatch {
+		fm.fieldReport.zoneMismatch[columnName]++
+	}
+	return parsed.Format(layout), true
+}
+
+// timezoneWarnings 时区规范化的警告
+func (fm *FieldMapper) timezoneWarnings() []string {
+	var warnings []string
+	if fm.fieldReport.timezoneConfigErr != "" {
+		warnings = append(warnings, fmt.Sprintf("时区配置无效，未做时区规范化: %s", fm.fieldReport.timezoneConfigErr))
+	}
+	if summary := summarizeFieldCounts(fm.fieldReport.zoneMismatch); summary != "" {
+		warnings = append(warnings, "时间自带的时区与配置的源时区不一致，已按自带时区转换: "+summary)
+	}
+	if summary := summarizeFieldCounts(fm.fieldReport.unparsedTime); summary != "" {
+		warnings = append(warnings, "无法解析的时间值，按原值写入: "+summary)
+	}
+	return warnings
+}
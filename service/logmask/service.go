@@ -0,0 +1,131 @@
+/*
+ * @module service/logmask/service
+ * @description 日志脱敏字段同步服务，定时把基础库和主题库接口字段配置中标记为敏感的字段登记为日志脱敏字段
+ * @architecture 分层架构 - 业务服务层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow 启动时和定时触发 -> 读取全部接口的字段配置 -> 收集is_sensitive的字段英文名 -> logger.SetMaskFields(interface)
+ * @rules 每个实例各自刷新，不需要分布式锁；刷新失败时保留上次登记的字段；字段在接口上取消敏感标记后，下次刷新时不再脱敏
+ * @dependencies datahub-service/logger, datahub-service/service/models, gorm.io/gorm, github.com/robfig/cron/v3
+ * @refs logger/sanitize.go, service/models/table.go
+ */
+
+package logmask
+
+import (
+	"context"
+	"datahub-service/logger"
+	"datahub-service/service/models"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"sort"
+
+	"github.com/robfig/cron/v3"
+	"gorm.io/gorm"
+)
+
+// defaultRefreshSchedule 默认每5分钟刷新一次
+const defaultRefreshSchedule = "0 */5 * * * *"
+
+// Service 日志脱敏字段同步服务
+type Service struct {
+	db       *gorm.DB
+	schedule string
+	cron     *cron.Cron
+	ctx      context.Context
+	cancel   context.CancelFunc
+	started  bool
+}
+
+// NewService 创建日志脱敏字段同步服务，刷新周期可通过LOG_MASK_REFRESH_SCHEDULE配置
+func NewService(db *gorm.DB) *Service {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	schedule := os.Getenv("LOG_MASK_REFRESH_SCHEDULE")
+	if schedule == "" {
+		schedule = defaultRefreshSchedule
+	}
+
+	return &Service{
+		db:       db,
+		schedule: schedule,
+		cron:     cron.New(cron.WithSeconds()),
+		ctx:      ctx,
+		cancel:   cancel,
+	}
+}
+
+// Refresh 重新收集接口上的敏感字段并登记，返回登记的字段名
+func (s *Service) Refresh(ctx context.Context) ([]string, error) {
+	var configs []models.JSONB
+	if err := s.db.WithContext(ctx).Model(&models.DataInterface{}).Pluck("table_fields_config", &configs).Error; err != nil {
+		return nil, fmt.Errorf("查询基础库接口字段配置失败: %w", err)
+	}
+	var thematicConfigs []models.JSONB
+	if err := s.db.WithContext(ctx).Model(&models.ThematicInterface{}).Pluck("table_fields_config", &thematicConfigs).Error; err != nil {
+		return nil, fmt.Errorf("查询主题接口字段配置失败: %w", err)
+	}
+
+	fields := sensitiveFieldNames(append(configs, thematicConfigs...))
+	logger.SetMaskFields(logger.MaskSourceInterface, fields)
+	return fields, nil
+}
+
+// Start 立即刷新一次并启动定时刷新
+func (s *Service) Start() error {
+	if s.started {
+		return fmt.Errorf("日志脱敏字段同步已经启动")
+	}
+
+	refresh := func() {
+		fields, err := s.Refresh(s.ctx)
+		if err != nil {
+			slog.Error("刷新日志脱敏字段失败", "error", err)
+			return
+		}
+		slog.Debug("刷新日志脱敏字段", "interface_fields", len(fields))
+	}
+	if _, err := s.cron.AddFunc(s.schedule, refresh); err != nil {
+		return fmt.Errorf("添加定时任务失败: %w", err)
+	}
+	refresh()
+
+	s.cron.Start()
+	s.started = true
+	slog.Info("日志脱敏字段同步启动成功", "schedule", s.schedule)
+	return nil
+}
+
+// Stop 停止定时刷新
+func (s *Service) Stop() {
+	if !s.started {
+		return
+	}
+	s.cancel()
+	s.cron.Stop()
+	s.started = false
+}
+
+// sensitiveFieldNames 字段配置中标记为敏感的字段英文名，去重后按字母序
+func sensitiveFieldNames(configs []models.JSONB) []string {
+	seen := map[string]bool{}
+	for _, config := range configs {
+		for _, value := range config {
+			var field models.TableField
+			data, err := json.Marshal(value)
+			if err != nil || json.Unmarshal(data, &field) != nil {
+				continue
+			}
+			if field.IsSensitive && field.NameEn != "" {
+				seen[field.NameEn] = true
+			}
+		}
+	}
+	fields := make([]string, 0, len(seen))
+	for field := range seen {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	return fields
+}
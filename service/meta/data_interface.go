@@ -232,7 +232,7 @@ func initializeDefaultDataInterfaceConfigs() {
 					"default_limit": 1000,
 					"max_limit":     10000,
 				},
-				Description: "查询结果数量限制配置",
+				Description: "查询结果数量限制配置，批量同步时可设置auto_tune开启批量大小自适应调整，min_limit为调整下限",
 				Group:       "查询配置",
 			},
 			{
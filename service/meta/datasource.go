@@ -291,6 +291,7 @@ const (
 	DataSourceTypeDBPostgreSQL      = "postgresql"
 	DataSourceTypeApiHTTP           = "http"
 	DataSourceTypeApiHTTPWithAuth   = "http_with_auth"
+	DataSourceTypeApiMock           = "mock"
 	DataSourceTypeMessagingMQTT     = "mqtt"
 	DataSourceTypeMessagingHttpPost = "http_post"
 )
@@ -321,6 +322,19 @@ const DataSourceFieldAutoOffsetReset = "auto_offset_reset"
 const DataSourceFieldMaxPollRecords = "max_poll_records"
 const DataSourceFieldBootstrapServers = "bootstrap_servers"
 const DatasourceFieldCustomMap = "custom_map"
+const DataSourceFieldFixtures = "fixtures"
+const DataSourceFieldSchemaRegistryURL = "schema_registry_url"
+const DataSourceFieldSchemaRegistryFlavor = "schema_registry_flavor"
+const DataSourceFieldSchemaRegistryUsername = "schema_registry_username"
+const DataSourceFieldSchemaRegistryPassword = "schema_registry_password"
+const DataSourceFieldSchemaSubject = "schema_subject"
+const DataSourceFieldSchemaVersion = "schema_version"
+const DataSourceFieldSchemaFormat = "schema_format"
+const DataSourceFieldSchemaMessageType = "schema_message_type"
+const DataSourceFieldSchemaWireFormat = "schema_wire_format"
+
+// MockFixtureFallbackKey 模拟数据源中未匹配到路径时使用的样例键
+const MockFixtureFallbackKey = "*"
 
 const (
 	DataSourceAuthTypeBasic  = "basic"
@@ -768,6 +782,87 @@ func initializeDefaultTypes() {
 				Max:          300,
 				Group:        "性能配置",
 			},
+			{
+				Name:        DataSourceFieldSchemaRegistryURL,
+				DisplayName: "Schema注册中心地址",
+				Type:        "string",
+				Required:    false,
+				Description: "Confluent Schema Registry或Apicurio Registry地址，配置后按注册中心的Avro/Protobuf schema解码消息，不配置时消息按JSON解析",
+				Placeholder: "http://schema-registry:8081",
+				Group:       "Schema注册中心",
+			},
+			{
+				Name:         DataSourceFieldSchemaRegistryFlavor,
+				DisplayName:  "注册中心类型",
+				Type:         "enum",
+				Required:     false,
+				DefaultValue: "confluent",
+				Description:  "apicurio通过其Confluent兼容接口(/apis/ccompat/v7)访问",
+				Options:      []string{"confluent", "apicurio"},
+				Group:        "Schema注册中心",
+			},
+			{
+				Name:        DataSourceFieldSchemaRegistryUsername,
+				DisplayName: "注册中心用户名",
+				Type:        "string",
+				Required:    false,
+				Description: "注册中心Basic认证用户名（可选）",
+				Group:       "Schema注册中心",
+			},
+			{
+				Name:        DataSourceFieldSchemaRegistryPassword,
+				DisplayName: "注册中心密码",
+				Type:        "string",
+				Required:    false,
+				Description: "注册中心Basic认证密码（可选）",
+				Group:       "Schema注册中心",
+			},
+			{
+				Name:        DataSourceFieldSchemaSubject,
+				DisplayName: "Schema主题",
+				Type:        "string",
+				Required:    false,
+				Description: "注册中心的subject，配置了注册中心地址时必填",
+				Placeholder: "device-readings-value",
+				Group:       "Schema注册中心",
+			},
+			{
+				Name:         DataSourceFieldSchemaVersion,
+				DisplayName:  "基准版本",
+				Type:         "string",
+				Required:     false,
+				DefaultValue: "latest",
+				Description:  "latest或版本号，接口表字段按基准版本映射，消息使用的schema与基准版本不兼容时拒绝解码",
+				Pattern:      `^(latest|[1-9][0-9]*)$`,
+				Group:        "Schema注册中心",
+			},
+			{
+				Name:        DataSourceFieldSchemaFormat,
+				DisplayName: "Schema格式",
+				Type:        "enum",
+				Required:    false,
+				Description: "为空时按注册中心返回的schemaType判断",
+				Options:     []string{"avro", "protobuf"},
+				Group:       "Schema注册中心",
+			},
+			{
+				Name:        DataSourceFieldSchemaMessageType,
+				DisplayName: "Protobuf消息类型",
+				Type:        "string",
+				Required:    false,
+				Description: "Protobuf消息全名，为空时取schema中第一个顶层消息",
+				Group:       "Schema注册中心",
+			},
+			{
+				Name:         DataSourceFieldSchemaWireFormat,
+				DisplayName:  "消息线格式",
+				Type:         "enum",
+				Required:     false,
+				DefaultValue: "confluent",
+				Description:  "confluent：消息以魔数0和4字节schema ID开头；raw：消息只有编码后的数据，始终按基准版本解码",
+				Options:      []string{"confluent", "raw"},
+				Group:        "Schema注册中心",
+			},
 		},
 		Examples: []DataSourceExample{
 			{
@@ -782,8 +877,8 @@ func initializeDefaultTypes() {
 				},
 			},
 		},
-		SupportedFeatures: []string{"real_time_messaging", "topic_subscription", "qos_support"},
-		Documentation:     "MQTT数据源支持实时消息订阅",
+		SupportedFeatures: []string{"real_time_messaging", "topic_subscription", "qos_support", "schema_registry"},
+		Documentation:     "MQTT数据源支持实时消息订阅；配置schema注册中心后按Avro/Protobuf schema解码消息",
 		IsActive:          true,
 	}
 
@@ -879,10 +974,52 @@ func initializeDefaultTypes() {
 		IsActive:          true,
 	}
 
+	// 模拟数据源，用于在无法访问源系统时调试和测试接口配置
+	mock := &DataSourceTypeDefinition{
+		ID:          DataSourceTypeApiMock,
+		Category:    DataSourceCategoryAPI,
+		Type:        DataSourceTypeApiMock,
+		Name:        "模拟数据源",
+		Description: "返回预置样例数据的API数据源，用于接口解析、映射和清洗配置的调试与测试",
+		Icon:        "mock",
+		MetaConfig: []DataSourceConfigField{
+			{
+				Name:         DataSourceFieldFixtures,
+				DisplayName:  "样例数据",
+				Type:         "object",
+				Required:     true,
+				DefaultValue: map[string]interface{}{},
+				Description:  "URL路径到响应内容的映射，键可带请求方法前缀(如 \"POST /devices\")，键 \"*\" 为未匹配时的默认响应；响应内容可以是JSON对象、数组或JSON字符串",
+				Group:        "样例配置",
+			},
+		},
+		Examples: []DataSourceExample{
+			{
+				Name:        "设备列表样例",
+				Description: "模拟分页返回设备列表的接口",
+				ConnectionConfig: map[string]interface{}{
+					DataSourceFieldFixtures: map[string]interface{}{
+						"/devices": map[string]interface{}{
+							"code": 0,
+							"data": []interface{}{
+								map[string]interface{}{"device_id": "D001", "name": "1号泵", "status": "online"},
+								map[string]interface{}{"device_id": "D002", "name": "2号泵", "status": "offline"},
+							},
+						},
+					},
+				},
+			},
+		},
+		SupportedFeatures: []string{"rest_api", "json_data", "batch_processing", "fixture_upload"},
+		Documentation:     "模拟数据源不发起网络请求，按接口URL后缀返回预置的样例数据，同步时按分页参数切分数据路径下的数组；样例可在连接配置中直接填写，也可通过上传JSON文件写入",
+		IsActive:          true,
+	}
+
 	// 注册所有类型
 	DataSourceTypes[postgresql.ID] = postgresql
 	DataSourceTypes[httpNoAuth.ID] = httpNoAuth
 	DataSourceTypes[httpWithAuth.ID] = httpWithAuth
+	DataSourceTypes[mock.ID] = mock
 	DataSourceTypes[mqtt.ID] = mqtt
 	DataSourceTypes[httpPost.ID] = httpPost
 }
@@ -0,0 +1,310 @@
+/*
+ * @module service/meta/datasource_template
+ * @description 园区常见子系统的数据源模板目录，每个模板预置数据源连接配置和一组接口（接口配置、字段映射和表字段），实例化时只需填写地址和凭据
+ * @architecture 元数据定义
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow 查询模板目录 -> 选择模板并填写必填连接项 -> 创建数据源和接口
+ * @rules 模板ID全局唯一；RequiredFields只包含实例化时用户需要填写的连接配置项；接口英文名即接口表名，字段的Source为源数据中的字段名
+ * @dependencies 无
+ * @refs service/basic_library/datasource_template_service.go, datasource.go, data_interface.go
+ */
+
+package meta
+
+import "sort"
+
+// 模板所属的园区子系统
+const (
+	DataSourceTemplateSubsystemVideo         = "video"          // 视频监控
+	DataSourceTemplateSubsystemParking       = "parking"        // 停车管理
+	DataSourceTemplateSubsystemEnergy        = "energy"         // 能耗监测
+	DataSourceTemplateSubsystemAccessControl = "access_control" // 门禁管理
+)
+
+// DataSourceTemplate 数据源模板
+type DataSourceTemplate struct {
+	ID                 string                        `json:"id"`
+	Name               string                        `json:"name"`
+	Description        string                        `json:"description"`
+	Subsystem          string                        `json:"subsystem"`
+	Vendor             string                        `json:"vendor,omitempty"`
+	DataSourceCategory string                        `json:"data_source_category"`
+	DataSourceType     string                        `json:"data_source_type"`
+	ConnectionConfig   map[string]interface{}        `json:"connection_config"`       // 预置的连接配置，实例化时与用户填写的配置项合并
+	ParamsConfig       map[string]interface{}        `json:"params_config,omitempty"` // 预置的参数配置
+	RequiredFields     []DataSourceConfigField       `json:"required_fields"`         // 实例化时需要填写的连接配置项
+	Interfaces         []DataSourceTemplateInterface `json:"interfaces"`              // 预置的接口
+	Documentation      string                        `json:"documentation,omitempty"` // 对接说明
+}
+
+// DataSourceTemplateInterface 模板中的接口
+type DataSourceTemplateInterface struct {
+	NameZh          string                    `json:"name_zh"`
+	NameEn          string                    `json:"name_en"`
+	Type            string                    `json:"type"` // realtime, batch
+	Description     string                    `json:"description"`
+	InterfaceConfig map[string]interface{}    `json:"interface_config"`
+	Fields          []DataSourceTemplateField `json:"fields"`
+}
+
+// DataSourceTemplateField 模板接口的表字段
+type DataSourceTemplateField struct {
+	NameZh           string `json:"name_zh"`
+	NameEn           string `json:"name_en"`
+	DataType         string `json:"data_type"`
+	Source           string `json:"source"` // 源数据中的字段名
+	IsPrimaryKey     bool   `json:"is_primary_key,omitempty"`
+	IsIncrementField bool   `json:"is_increment_field,omitempty"`
+	IsIndexed        bool   `json:"is_indexed,omitempty"`
+	IsSensitive      bool   `json:"is_sensitive,omitempty"`
+}
+
+// DataSourceTemplates 数据源模板目录
+var DataSourceTemplates = make(map[string]*DataSourceTemplate)
+
+func init() {
+	initializeDataSourceTemplates()
+}
+
+// ListDataSourceTemplates 按子系统和ID排序返回模板，subsystem为空时返回全部
+func ListDataSourceTemplates(subsystem string) []*DataSourceTemplate {
+	templates := make([]*DataSourceTemplate, 0, len(DataSourceTemplates))
+	for _, template := range DataSourceTemplates {
+		if subsystem == "" || template.Subsystem == subsystem {
+			templates = append(templates, template)
+		}
+	}
+	sort.Slice(templates, func(i, j int) bool {
+		if templates[i].Subsystem != templates[j].Subsystem {
+			return templates[i].Subsystem < templates[j].Subsystem
+		}
+		return templates[i].ID < templates[j].ID
+	})
+	return templates
+}
+
+// initializeDataSourceTemplates 初始化内置的数据源模板
+func initializeDataSourceTemplates() {
+	baseURLField := DataSourceConfigField{
+		Name:        DataSourceFieldBaseUrl,
+		DisplayName: "平台地址",
+		Type:        "string",
+		Required:    true,
+		Description: "子系统平台的访问地址",
+		Group:       "连接配置",
+	}
+
+	hikvision := &DataSourceTemplate{
+		ID:                 "hikvision_isc",
+		Name:               "海康威视综合安防平台",
+		Description:        "通过综合安防管理平台OpenAPI同步监控点和监控点在线状态",
+		Subsystem:          DataSourceTemplateSubsystemVideo,
+		Vendor:             "海康威视",
+		DataSourceCategory: DataSourceCategoryAPI,
+		DataSourceType:     DataSourceTypeApiHTTPWithAuth,
+		ConnectionConfig: map[string]interface{}{
+			DataSourceFieldAuthType:     DataSourceAuthTypeAPIKey,
+			DataSourceFieldApiKeyHeader: "X-Ca-Key",
+		},
+		RequiredFields: []DataSourceConfigField{
+			withPlaceholder(baseURLField, "https://isc.example.com/artemis"),
+			{Name: DataSourceFieldApiKey, DisplayName: "AppKey", Type: "string", Required: true, Description: "平台分配给合作方的AppKey", Group: "认证配置"},
+			{Name: DataSourceFieldApiSecret, DisplayName: "AppSecret", Type: "string", Required: true, Description: "平台分配给合作方的AppSecret", Group: "认证配置"},
+		},
+		Interfaces: []DataSourceTemplateInterface{
+			{
+				NameZh: "监控点", NameEn: "hik_cameras", Type: "batch", Description: "分页获取监控点列表",
+				InterfaceConfig: pagedAPIConfig("POST", "/api/resource/v1/cameras", "data.list", "pageNo", "pageSize", 1000),
+				Fields: []DataSourceTemplateField{
+					{NameZh: "监控点编号", NameEn: "camera_index_code", DataType: "varchar", Source: "cameraIndexCode", IsPrimaryKey: true},
+					{NameZh: "监控点名称", NameEn: "camera_name", DataType: "varchar", Source: "cameraName"},
+					{NameZh: "监控点类型", NameEn: "camera_type", DataType: "integer", Source: "cameraType"},
+					{NameZh: "所属区域编号", NameEn: "region_index_code", DataType: "varchar", Source: "regionIndexCode", IsIndexed: true},
+					{NameZh: "所属编码设备编号", NameEn: "encode_dev_index_code", DataType: "varchar", Source: "encodeDevIndexCode"},
+					{NameZh: "安装位置", NameEn: "install_location", DataType: "varchar", Source: "installLocation"},
+					{NameZh: "更新时间", NameEn: "update_time", DataType: "timestamp", Source: "updateTime", IsIncrementField: true},
+				},
+			},
+			{
+				NameZh: "监控点在线状态", NameEn: "hik_camera_online", Type: "batch", Description: "分页获取监控点在线状态",
+				InterfaceConfig: pagedAPIConfig("POST", "/api/nms/v1/online/camera/get", "data.list", "pageNo", "pageSize", 1000),
+				Fields: []DataSourceTemplateField{
+					{NameZh: "监控点编号", NameEn: "camera_index_code", DataType: "varchar", Source: "indexCode", IsPrimaryKey: true},
+					{NameZh: "设备IP", NameEn: "device_ip", DataType: "varchar", Source: "ip"},
+					{NameZh: "在线状态", NameEn: "online", DataType: "integer", Source: "online"},
+					{NameZh: "采集时间", NameEn: "collect_time", DataType: "timestamp", Source: "collectTime"},
+				},
+			},
+		},
+		Documentation: "平台开启请求签名校验时，需要在数据源脚本中按平台规范补充签名请求头",
+	}
+
+	parking := &DataSourceTemplate{
+		ID:                 "parking_db",
+		Name:               "停车管理系统数据库",
+		Description:        "直连停车管理系统的PostgreSQL业务库，同步停车场和车辆进出记录",
+		Subsystem:          DataSourceTemplateSubsystemParking,
+		DataSourceCategory: DataSourceCategoryDatabase,
+		DataSourceType:     DataSourceTypeDBPostgreSQL,
+		ConnectionConfig: map[string]interface{}{
+			DataSourceFieldPort:    float64(5432),
+			DataSourceFieldSchema:  "public",
+			DataSourceFieldSSLMode: "disable",
+		},
+		RequiredFields: []DataSourceConfigField{
+			{Name: DataSourceFieldHost, DisplayName: "主机", Type: "string", Required: true, Description: "停车系统数据库地址", Group: "连接配置"},
+			{Name: DataSourceFieldDatabase, DisplayName: "数据库", Type: "string", Required: true, Description: "停车系统数据库名称", Placeholder: "parking", Group: "连接配置"},
+			{Name: DataSourceFieldUsername, DisplayName: "用户名", Type: "string", Required: true, Description: "只读账号", Group: "认证配置"},
+			{Name: DataSourceFieldPassword, DisplayName: "密码", Type: "string", Required: true, Description: "只读账号密码", Group: "认证配置"},
+		},
+		Interfaces: []DataSourceTemplateInterface{
+			{
+				NameZh: "停车场", NameEn: "parking_lots", Type: "batch", Description: "停车场及车位数",
+				InterfaceConfig: tableQueryConfig("parking_lot"),
+				Fields: []DataSourceTemplateField{
+					{NameZh: "停车场编号", NameEn: "lot_id", DataType: "varchar", Source: "lot_id", IsPrimaryKey: true},
+					{NameZh: "停车场名称", NameEn: "lot_name", DataType: "varchar", Source: "lot_name"},
+					{NameZh: "总车位数", NameEn: "total_spaces", DataType: "integer", Source: "total_spaces"},
+					{NameZh: "空余车位数", NameEn: "free_spaces", DataType: "integer", Source: "free_spaces"},
+					{NameZh: "更新时间", NameEn: "updated_at", DataType: "timestamp", Source: "updated_at", IsIncrementField: true},
+				},
+			},
+			{
+				NameZh: "车辆进出记录", NameEn: "parking_records", Type: "batch", Description: "车辆入场和出场记录",
+				InterfaceConfig: tableQueryConfig("parking_record"),
+				Fields: []DataSourceTemplateField{
+					{NameZh: "记录编号", NameEn: "record_id", DataType: "varchar", Source: "record_id", IsPrimaryKey: true},
+					{NameZh: "停车场编号", NameEn: "lot_id", DataType: "varchar", Source: "lot_id", IsIndexed: true},
+					{NameZh: "车牌号", NameEn: "plate_no", DataType: "varchar", Source: "plate_no", IsIndexed: true, IsSensitive: true},
+					{NameZh: "入场时间", NameEn: "entry_time", DataType: "timestamp", Source: "entry_time"},
+					{NameZh: "出场时间", NameEn: "exit_time", DataType: "timestamp", Source: "exit_time"},
+					{NameZh: "应收金额", NameEn: "amount", DataType: "decimal", Source: "amount"},
+					{NameZh: "更新时间", NameEn: "updated_at", DataType: "timestamp", Source: "updated_at", IsIncrementField: true},
+				},
+			},
+		},
+	}
+
+	energy := &DataSourceTemplate{
+		ID:                 "energy_meter_api",
+		Name:               "能耗监测平台",
+		Description:        "通过能耗监测平台REST接口同步电表、水表等计量表档案和抄表读数",
+		Subsystem:          DataSourceTemplateSubsystemEnergy,
+		DataSourceCategory: DataSourceCategoryAPI,
+		DataSourceType:     DataSourceTypeApiHTTPWithAuth,
+		ConnectionConfig: map[string]interface{}{
+			DataSourceFieldAuthType:     DataSourceAuthTypeAPIKey,
+			DataSourceFieldApiKeyHeader: "X-API-Key",
+		},
+		RequiredFields: []DataSourceConfigField{
+			withPlaceholder(baseURLField, "https://energy.example.com"),
+			{Name: DataSourceFieldApiKey, DisplayName: "API Key", Type: "string", Required: true, Description: "平台接口密钥", Group: "认证配置"},
+		},
+		Interfaces: []DataSourceTemplateInterface{
+			{
+				NameZh: "计量表档案", NameEn: "energy_meters", Type: "batch", Description: "电表、水表、燃气表等计量表档案",
+				InterfaceConfig: pagedAPIConfig("GET", "/api/v1/meters", "data.items", "page", "size", 500),
+				Fields: []DataSourceTemplateField{
+					{NameZh: "表号", NameEn: "meter_id", DataType: "varchar", Source: "meterId", IsPrimaryKey: true},
+					{NameZh: "表名称", NameEn: "meter_name", DataType: "varchar", Source: "meterName"},
+					{NameZh: "能源类型", NameEn: "energy_type", DataType: "varchar", Source: "energyType", IsIndexed: true},
+					{NameZh: "所属建筑", NameEn: "building_id", DataType: "varchar", Source: "buildingId", IsIndexed: true},
+					{NameZh: "倍率", NameEn: "ratio", DataType: "decimal", Source: "ratio"},
+				},
+			},
+			{
+				NameZh: "抄表读数", NameEn: "energy_readings", Type: "batch", Description: "计量表的周期抄表读数",
+				InterfaceConfig: pagedAPIConfig("GET", "/api/v1/readings", "data.items", "page", "size", 1000),
+				Fields: []DataSourceTemplateField{
+					{NameZh: "读数编号", NameEn: "reading_id", DataType: "varchar", Source: "readingId", IsPrimaryKey: true},
+					{NameZh: "表号", NameEn: "meter_id", DataType: "varchar", Source: "meterId", IsIndexed: true},
+					{NameZh: "读数", NameEn: "reading_value", DataType: "decimal", Source: "value"},
+					{NameZh: "单位", NameEn: "unit", DataType: "varchar", Source: "unit"},
+					{NameZh: "抄表时间", NameEn: "read_time", DataType: "timestamp", Source: "readTime", IsIncrementField: true},
+				},
+			},
+		},
+	}
+
+	accessControl := &DataSourceTemplate{
+		ID:                 "access_control_api",
+		Name:               "门禁管理系统",
+		Description:        "通过门禁管理系统REST接口同步门禁点和通行记录",
+		Subsystem:          DataSourceTemplateSubsystemAccessControl,
+		DataSourceCategory: DataSourceCategoryAPI,
+		DataSourceType:     DataSourceTypeApiHTTPWithAuth,
+		ConnectionConfig: map[string]interface{}{
+			DataSourceFieldAuthType: DataSourceAuthTypeBasic,
+		},
+		RequiredFields: []DataSourceConfigField{
+			withPlaceholder(baseURLField, "https://acs.example.com"),
+			{Name: DataSourceFieldUsername, DisplayName: "用户名", Type: "string", Required: true, Description: "接口账号", Group: "认证配置"},
+			{Name: DataSourceFieldPassword, DisplayName: "密码", Type: "string", Required: true, Description: "接口账号密码", Group: "认证配置"},
+		},
+		Interfaces: []DataSourceTemplateInterface{
+			{
+				NameZh: "门禁点", NameEn: "acs_doors", Type: "batch", Description: "门禁点及所属区域",
+				InterfaceConfig: pagedAPIConfig("GET", "/api/v1/doors", "data.list", "page", "size", 500),
+				Fields: []DataSourceTemplateField{
+					{NameZh: "门禁点编号", NameEn: "door_id", DataType: "varchar", Source: "doorId", IsPrimaryKey: true},
+					{NameZh: "门禁点名称", NameEn: "door_name", DataType: "varchar", Source: "doorName"},
+					{NameZh: "所属区域", NameEn: "region_id", DataType: "varchar", Source: "regionId", IsIndexed: true},
+					{NameZh: "门禁控制器编号", NameEn: "controller_id", DataType: "varchar", Source: "controllerId"},
+				},
+			},
+			{
+				NameZh: "通行记录", NameEn: "acs_pass_records", Type: "batch", Description: "刷卡、人脸等方式的门禁通行记录",
+				InterfaceConfig: pagedAPIConfig("GET", "/api/v1/events", "data.list", "page", "size", 1000),
+				Fields: []DataSourceTemplateField{
+					{NameZh: "事件编号", NameEn: "event_id", DataType: "varchar", Source: "eventId", IsPrimaryKey: true},
+					{NameZh: "门禁点编号", NameEn: "door_id", DataType: "varchar", Source: "doorId", IsIndexed: true},
+					{NameZh: "人员编号", NameEn: "person_id", DataType: "varchar", Source: "personId", IsIndexed: true},
+					{NameZh: "人员姓名", NameEn: "person_name", DataType: "varchar", Source: "personName", IsSensitive: true},
+					{NameZh: "卡号", NameEn: "card_no", DataType: "varchar", Source: "cardNo", IsSensitive: true},
+					{NameZh: "认证方式", NameEn: "verify_mode", DataType: "varchar", Source: "verifyMode"},
+					{NameZh: "进出方向", NameEn: "direction", DataType: "varchar", Source: "direction"},
+					{NameZh: "通行时间", NameEn: "event_time", DataType: "timestamp", Source: "eventTime", IsIncrementField: true},
+				},
+			},
+		},
+	}
+
+	for _, template := range []*DataSourceTemplate{hikvision, parking, energy, accessControl} {
+		DataSourceTemplates[template.ID] = template
+	}
+}
+
+// withPlaceholder 复制配置字段并设置占位符
+func withPlaceholder(field DataSourceConfigField, placeholder string) DataSourceConfigField {
+	field.Placeholder = placeholder
+	return field
+}
+
+// pagedAPIConfig 分页拉取的API接口配置
+func pagedAPIConfig(method, urlSuffix, dataPath, pageParam, sizeParam string, pageSize int) map[string]interface{} {
+	location := "query"
+	if method == "POST" {
+		location = "body"
+	}
+	return map[string]interface{}{
+		DataInterfaceConfigFieldUrlPattern:              "suffix",
+		DataInterfaceConfigFieldUrlSuffix:               urlSuffix,
+		DataInterfaceConfigFieldMethod:                  method,
+		DataInterfaceConfigFieldDataPath:                dataPath,
+		DataInterfaceConfigFieldPaginationEnabled:       true,
+		DataInterfaceConfigFieldPaginationPageParam:     pageParam,
+		DataInterfaceConfigFieldPaginationSizeParam:     sizeParam,
+		DataInterfaceConfigFieldPaginationStartValue:    1,
+		DataInterfaceConfigFieldPaginationDefaultSize:   pageSize,
+		DataInterfaceConfigFieldPaginationParamLocation: location,
+	}
+}
+
+// tableQueryConfig 按表查询的数据库接口配置
+func tableQueryConfig(tableName string) map[string]interface{} {
+	return map[string]interface{}{
+		DataSourceFieldSchema:             "public",
+		DataInterfaceConfigFieldTableName: tableName,
+		DataInterfaceConfigFieldQueryType: "select",
+	}
+}
@@ -56,6 +56,7 @@ const (
 	SyncExecutionRecordStatusSuccess   = "success"   // 成功
 	SyncExecutionRecordStatusFailed    = "failed"    // 失败
 	SyncExecutionRecordStatusCancelled = "cancelled" // 已取消
+	SyncExecutionRecordStatusSkipped   = "skipped"   // 已跳过：数据源维护中，调度未执行
 )
 
 var SyncTaskStatuses = []MetaField{
@@ -273,6 +274,7 @@ func IsValidSyncExecutionRecordStatus(status string) bool {
 		SyncExecutionRecordStatusSuccess:   true,
 		SyncExecutionRecordStatusFailed:    true,
 		SyncExecutionRecordStatusCancelled: true,
+		SyncExecutionRecordStatusSkipped:   true,
 	}
 	return validStatuses[status]
 }
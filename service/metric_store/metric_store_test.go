@@ -0,0 +1,310 @@
+/*
+ * @module service/metric_store/metric_store_test
+ * @description 指标层服务测试，覆盖定义校验、物化查询构建、按维度物化和读取、定义变化清空指标值、到期刷新以及物化失败保留上一次结果
+ * @architecture 测试层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow 准备sqlite中的基础库、接口和附加库中的接口表 -> 定义指标 -> 刷新 -> 读取指标值 -> 修改定义/制造失败后复查
+ * @rules 使用内存sqlite，以ATTACH的数据库模拟基础库schema，不依赖外部服务；按时间粒度分组使用PostgreSQL的date_trunc，只验证生成的语句
+ * @dependencies gorm.io/driver/sqlite, stretchr/testify
+ * @refs service.go, query.go
+ */
+
+package metric_store
+
+import (
+	"context"
+	"datahub-service/service/eventlog"
+	"datahub-service/service/meta"
+	"datahub-service/service/models"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// setupMetricService 准备基础库parking和车辆进出接口if-access，接口表建在附加库parking中
+func setupMetricService(t *testing.T) (*Service, *gorm.DB) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	sqlDB.SetMaxOpenConns(1) // ATTACH只对当前连接生效
+	require.NoError(t, db.AutoMigrate(
+		&models.BasicLibrary{}, &models.DataInterface{}, &models.ThematicLibrary{}, &models.ThematicInterface{},
+		&models.MetricDefinition{}, &models.MetricValue{}, &models.ApplicationEvent{},
+	))
+
+	require.NoError(t, db.Exec(`ATTACH DATABASE ':memory:' AS parking`).Error)
+	require.NoError(t, db.Exec(`CREATE TABLE parking.vehicle_access (id INTEGER PRIMARY KEY, parking_lot TEXT, direction TEXT, plate_no TEXT, fee REAL, entered_at TIMESTAMP)`).Error)
+	require.NoError(t, db.Exec(`INSERT INTO parking.vehicle_access (id, parking_lot, direction, plate_no, fee, entered_at) VALUES
+		(1, 'P1', 'in', '沪A1', 5, '2026-10-01 08:00:00'),
+		(2, 'P1', 'in', '沪A2', 7, '2026-10-01 09:00:00'),
+		(3, 'P1', 'out', '沪A1', NULL, '2026-10-01 10:00:00'),
+		(4, 'P2', 'in', '沪A3', 10, '2026-10-02 08:00:00'),
+		(5, NULL, 'in', '沪A4', NULL, '2026-10-02 09:00:00')`).Error)
+
+	require.NoError(t, db.Create(&models.BasicLibrary{ID: "lib-parking", NameZh: "停车", NameEn: "parking"}).Error)
+	require.NoError(t, db.Create(&models.DataInterface{
+		ID: "if-access", LibraryID: "lib-parking", NameZh: "车辆进出", NameEn: "vehicle_access", Type: "batch", DataSourceID: "ds-1", IsTableCreated: true,
+		TableFieldsConfig: models.JSONB{
+			"field_0": map[string]interface{}{"name_en": "id", "data_type": "integer", "is_primary_key": true, "order_num": 1},
+			"field_1": map[string]interface{}{"name_en": "parking_lot", "data_type": "varchar", "order_num": 2},
+			"field_2": map[string]interface{}{"name_en": "direction", "data_type": "varchar", "order_num": 3},
+			"field_3": map[string]interface{}{"name_en": "plate_no", "data_type": "varchar", "order_num": 4, "is_sensitive": true},
+			"field_4": map[string]interface{}{"name_en": "fee", "data_type": "numeric", "order_num": 5},
+			"field_5": map[string]interface{}{"name_en": "entered_at", "data_type": "timestamp", "order_num": 6},
+		},
+	}).Error)
+	require.NoError(t, db.Create(&models.DataInterface{
+		ID: "if-draft", LibraryID: "lib-parking", NameZh: "未建表", NameEn: "draft", Type: "batch", DataSourceID: "ds-1",
+	}).Error)
+
+	eventlog.SetDefault(eventlog.NewService(db))
+	t.Cleanup(func() { eventlog.SetDefault(nil) })
+	return NewService(db, db), db
+}
+
+func entriesRequest() *MetricRequest {
+	return &MetricRequest{
+		Name:            "vehicle_entries",
+		DisplayName:     "车辆入场数",
+		LibraryType:     meta.LibraryTypeBasic,
+		InterfaceID:     "if-access",
+		Expression:      "count(*)",
+		Filter:          "direction = 'in'",
+		Dimensions:      []string{"parking_lot"},
+		RefreshSchedule: "0 0 * * * *",
+	}
+}
+
+func TestValidateRequest(t *testing.T) {
+	req := entriesRequest()
+	req.Name = " vehicle_entries "
+	req.Dimensions = []string{" parking_lot "}
+	require.NoError(t, validateRequest(req))
+	assert.Equal(t, "vehicle_entries", req.Name)
+	assert.Equal(t, []string{"parking_lot"}, req.Dimensions)
+
+	invalid := map[string]func(r *MetricRequest){
+		"名称含大写":      func(r *MetricRequest) { r.Name = "VehicleEntries" },
+		"表达式含子查询":    func(r *MetricRequest) { r.Expression = "(SELECT count(*) FROM other.secret)" },
+		"过滤条件闭合括号":   func(r *MetricRequest) { r.Filter = "1 = 1) OR (1 = 1" },
+		"维度重复":       func(r *MetricRequest) { r.Dimensions = []string{"parking_lot", "parking_lot"} },
+		"粒度缺少时间字段":   func(r *MetricRequest) { r.TimeGrain = models.MetricGrainDay },
+		"不支持的粒度":     func(r *MetricRequest) { r.TimeGrain = "minute"; r.TimeColumn = "entered_at" },
+		"刷新周期无效":     func(r *MetricRequest) { r.RefreshSchedule = "every hour" },
+		"维度超过上限":     func(r *MetricRequest) { r.Dimensions = []string{"a", "b", "c", "d", "e", "f"} },
+		"回溯天数缺少时间字段": func(r *MetricRequest) { r.LookbackDays = 7 },
+	}
+	for name, mutate := range invalid {
+		r := entriesRequest()
+		mutate(r)
+		assert.ErrorIs(t, validateRequest(r), ErrInvalidMetric, name)
+	}
+}
+
+func TestBuildMetricQuery(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	metric := &models.MetricDefinition{
+		Expression:   "sum(fee)",
+		Filter:       "direction = 'in' -- 只统计入场",
+		Dimensions:   models.JSONBStringArray{"parking_lot"},
+		TimeColumn:   "entered_at",
+		TimeGrain:    models.MetricGrainDay,
+		LookbackDays: 30,
+	}
+	query := buildMetricQuery(metric, &metricTable{schema: "parking", table: "vehicle_access"}, now)
+	assert.Equal(t, `SELECT "parking_lot", date_trunc('day', "entered_at") AS metric_period, (
+sum(fee)
+) AS metric_value FROM "parking"."vehicle_access" WHERE "entered_at" >= ? AND (
+direction = 'in' -- 只统计入场
+) GROUP BY 1, 2 LIMIT 50001`, query.sql)
+	assert.Equal(t, []interface{}{now.AddDate(0, 0, -30)}, query.args)
+	assert.True(t, query.hasPeriod)
+
+	total := buildMetricQuery(&models.MetricDefinition{Expression: "count(*)"}, &metricTable{schema: "parking", table: "vehicle_access"}, now)
+	assert.Equal(t, "SELECT (\ncount(*)\n) AS metric_value FROM \"parking\".\"vehicle_access\" LIMIT 50001", total.sql)
+	assert.False(t, total.hasPeriod)
+}
+
+func TestCreateMetricValidatesInterfaceFields(t *testing.T) {
+	svc, _ := setupMetricService(t)
+	ctx := context.Background()
+
+	cases := map[string]func(r *MetricRequest){
+		"敏感字段作为维度":  func(r *MetricRequest) { r.Dimensions = []string{"plate_no"} },
+		"维度不在字段配置中": func(r *MetricRequest) { r.Dimensions = []string{"gate"} },
+		"时间字段不存在":   func(r *MetricRequest) { r.TimeColumn = "exited_at"; r.TimeGrain = models.MetricGrainDay },
+		"接口未建表":     func(r *MetricRequest) { r.InterfaceID = "if-draft" },
+		"接口不存在":     func(r *MetricRequest) { r.InterfaceID = "if-missing" },
+		"库类型不匹配":    func(r *MetricRequest) { r.LibraryType = meta.LibraryTypeThematic },
+	}
+	for name, mutate := range cases {
+		req := entriesRequest()
+		mutate(req)
+		_, err := svc.CreateMetric(ctx, req, "alice")
+		assert.ErrorIs(t, err, ErrInvalidMetric, name)
+	}
+
+	metric, err := svc.CreateMetric(ctx, entriesRequest(), "alice")
+	require.NoError(t, err)
+	assert.True(t, metric.Enabled, "默认启用")
+	require.NotNil(t, metric.NextRefreshAt)
+	assert.True(t, metric.NextRefreshAt.After(time.Now()))
+
+	_, err = svc.CreateMetric(ctx, entriesRequest(), "bob")
+	assert.ErrorIs(t, err, ErrInvalidMetric, "名称重复")
+
+	disabled := entriesRequest()
+	disabled.Name = "vehicle_entries_disabled"
+	enabled := false
+	disabled.Enabled = &enabled
+	metric, err = svc.CreateMetric(ctx, disabled, "alice")
+	require.NoError(t, err)
+	assert.False(t, metric.Enabled)
+	assert.Nil(t, metric.NextRefreshAt, "停用的指标不安排刷新")
+}
+
+func TestRefreshMaterializesValuesByDimension(t *testing.T) {
+	svc, _ := setupMetricService(t)
+	ctx := context.Background()
+
+	metric, err := svc.CreateMetric(ctx, entriesRequest(), "alice")
+	require.NoError(t, err)
+	metric, err = svc.RefreshMetric(ctx, metric.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.MetricRefreshSucceeded, metric.LastRefreshStatus)
+	assert.Equal(t, 3, metric.ValueCount)
+
+	result, err := svc.GetValues(ctx, metric.ID, ValueQuery{})
+	require.NoError(t, err)
+	counts := map[interface{}]float64{}
+	for _, value := range result.Values {
+		require.NotNil(t, value.Value)
+		counts[value.Dimensions["parking_lot"]] = *value.Value
+		assert.Nil(t, value.Period)
+	}
+	assert.Equal(t, map[interface{}]float64{"P1": 2, "P2": 1, nil: 1}, counts, "空维度取值单独成组")
+
+	filtered, err := svc.GetValues(ctx, metric.ID, ValueQuery{Dimensions: map[string]string{"parking_lot": "P1"}})
+	require.NoError(t, err)
+	require.Len(t, filtered.Values, 1)
+	assert.Equal(t, 2.0, *filtered.Values[0].Value)
+
+	// 再次刷新整体替换指标值
+	_, err = svc.RefreshMetric(ctx, metric.ID)
+	require.NoError(t, err)
+	result, err = svc.GetValues(ctx, metric.ID, ValueQuery{})
+	require.NoError(t, err)
+	assert.Len(t, result.Values, 3)
+
+	// 聚合结果为空时指标值为null
+	avgFee := entriesRequest()
+	avgFee.Name = "avg_fee"
+	avgFee.Expression = "avg(fee)"
+	avgFee.Filter = "direction = 'out'"
+	avgFee.Dimensions = nil
+	feeMetric, err := svc.CreateMetric(ctx, avgFee, "alice")
+	require.NoError(t, err)
+	_, err = svc.RefreshMetric(ctx, feeMetric.ID)
+	require.NoError(t, err)
+	result, err = svc.GetValues(ctx, feeMetric.ID, ValueQuery{})
+	require.NoError(t, err)
+	require.Len(t, result.Values, 1)
+	assert.Nil(t, result.Values[0].Value)
+}
+
+func TestRefreshFailureKeepsPreviousValues(t *testing.T) {
+	svc, db := setupMetricService(t)
+	ctx := context.Background()
+
+	metric, err := svc.CreateMetric(ctx, entriesRequest(), "alice")
+	require.NoError(t, err)
+	_, err = svc.RefreshMetric(ctx, metric.ID)
+	require.NoError(t, err)
+
+	// 接口表被修改后物化失败
+	require.NoError(t, db.Exec(`ALTER TABLE parking.vehicle_access RENAME COLUMN direction TO dir`).Error)
+	failed, err := svc.RefreshMetric(ctx, metric.ID)
+	require.ErrorIs(t, err, ErrMetricRefreshFailed)
+	assert.Equal(t, models.MetricRefreshFailed, failed.LastRefreshStatus)
+	assert.NotEmpty(t, failed.LastRefreshError)
+
+	stored, err := svc.GetMetric(ctx, metric.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.MetricRefreshFailed, stored.LastRefreshStatus)
+	assert.Equal(t, 3, stored.ValueCount, "保留上一次成功物化的条数")
+	result, err := svc.GetValues(ctx, metric.ID, ValueQuery{})
+	require.NoError(t, err)
+	assert.Len(t, result.Values, 3, "保留上一次的指标值")
+
+	var events int64
+	require.NoError(t, db.Model(&models.ApplicationEvent{}).
+		Where("event_type = ? AND object_id = ?", EventMetricRefreshFailed, metric.ID).Count(&events).Error)
+	assert.Equal(t, int64(1), events)
+}
+
+func TestUpdateMetricResetsValuesAndRefreshDue(t *testing.T) {
+	svc, db := setupMetricService(t)
+	ctx := context.Background()
+
+	metric, err := svc.CreateMetric(ctx, entriesRequest(), "alice")
+	require.NoError(t, err)
+	_, err = svc.RefreshMetric(ctx, metric.ID)
+	require.NoError(t, err)
+
+	// 只修改显示名称不影响已物化的指标值
+	req := entriesRequest()
+	req.DisplayName = "入场车次"
+	updated, err := svc.UpdateMetric(ctx, metric.ID, req, "bob")
+	require.NoError(t, err)
+	assert.Equal(t, 3, updated.ValueCount)
+	assert.True(t, updated.NextRefreshAt.After(time.Now()))
+
+	// 修改维度后清空指标值，下一次检查时重新物化
+	req.Dimensions = []string{"direction"}
+	req.Filter = ""
+	updated, err = svc.UpdateMetric(ctx, metric.ID, req, "bob")
+	require.NoError(t, err)
+	assert.Equal(t, 0, updated.ValueCount)
+	assert.Empty(t, updated.LastRefreshStatus)
+	var remaining int64
+	require.NoError(t, db.Model(&models.MetricValue{}).Where("metric_id = ?", metric.ID).Count(&remaining).Error)
+	assert.Zero(t, remaining)
+
+	// 未到期和停用的指标不刷新
+	later := entriesRequest()
+	later.Name = "later"
+	_, err = svc.CreateMetric(ctx, later, "alice")
+	require.NoError(t, err)
+	off := entriesRequest()
+	off.Name = "off"
+	enabled := false
+	off.Enabled = &enabled
+	_, err = svc.CreateMetric(ctx, off, "alice")
+	require.NoError(t, err)
+
+	summary, err := svc.RefreshDue(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, &RefreshSummary{Due: 1, Succeeded: 1}, summary)
+
+	refreshed, err := svc.GetMetric(ctx, metric.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.MetricRefreshSucceeded, refreshed.LastRefreshStatus)
+	assert.Equal(t, 2, refreshed.ValueCount, "按in/out分组")
+	assert.True(t, refreshed.NextRefreshAt.After(time.Now()))
+
+	summary, err = svc.RefreshDue(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, summary.Due)
+
+	require.NoError(t, svc.DeleteMetric(ctx, metric.ID))
+	require.NoError(t, db.Model(&models.MetricValue{}).Where("metric_id = ?", metric.ID).Count(&remaining).Error)
+	assert.Zero(t, remaining)
+}
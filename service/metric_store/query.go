@@ -0,0 +1,200 @@
+/*
+ * @module service/metric_store/query
+ * @description 指标定义校验和物化查询构建，按聚合表达式、维度、时间粒度和过滤条件生成对接口表的分组聚合查询
+ * @architecture 分层架构 - 业务服务层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow 指标请求 -> 名称/表达式/刷新周期校验 -> 维度和时间字段按接口字段配置校验 -> SELECT 维度, date_trunc(粒度, 时间字段), (表达式) FROM 接口表 WHERE ... GROUP BY ...
+ * @rules
+ *   - 表达式和过滤条件按工作台规则检查，不允许子查询、写操作和危险函数，拼接时放在括号内并前后换行
+ *   - 维度和时间字段必须是接口字段配置中的字段，敏感字段不能作为维度，避免明文取值进入指标值
+ *   - 分组结果最多maxMetricValues条，多取一条用于判断是否超出
+ * @dependencies datahub-service/service/models, datahub-service/service/workbench, github.com/robfig/cron/v3
+ * @refs service/metric_store/service.go
+ */
+
+package metric_store
+
+import (
+	"datahub-service/service/models"
+	"datahub-service/service/utils"
+	"datahub-service/service/workbench"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+// maxMetricValues 单个指标物化的指标值条数上限
+const maxMetricValues = 50000
+
+// maxMetricDimensions 单个指标的维度数上限
+const maxMetricDimensions = 5
+
+// ErrInvalidMetric 指标定义不合法
+var ErrInvalidMetric = errors.New("指标定义不合法")
+
+// metricNamePattern 指标名称，小写字母开头，只包含小写字母、数字和下划线
+var metricNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_]{0,62}$`)
+
+// cronParser 刷新周期解析器，与同步任务一致支持可选的秒字段
+var cronParser = cron.NewParser(cron.SecondOptional | cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow | cron.Descriptor)
+
+// metricGrains 支持的时间粒度
+var metricGrains = map[string]bool{
+	models.MetricGrainHour:  true,
+	models.MetricGrainDay:   true,
+	models.MetricGrainWeek:  true,
+	models.MetricGrainMonth: true,
+}
+
+// metricTable 指标所基于的接口表
+type metricTable struct {
+	schema string
+	table  string
+	fields map[string]models.TableField
+}
+
+// metricQuery 物化查询
+type metricQuery struct {
+	sql        string
+	args       []interface{}
+	dimensions []string
+	hasPeriod  bool
+}
+
+// validateRequest 校验与接口表无关的指标定义项
+func validateRequest(req *MetricRequest) error {
+	req.Name = strings.TrimSpace(req.Name)
+	req.DisplayName = strings.TrimSpace(req.DisplayName)
+	req.Expression = strings.TrimSpace(req.Expression)
+	req.Filter = strings.TrimSpace(req.Filter)
+	req.TimeColumn = strings.TrimSpace(req.TimeColumn)
+	req.RefreshSchedule = strings.TrimSpace(req.RefreshSchedule)
+
+	if !metricNamePattern.MatchString(req.Name) {
+		return fmt.Errorf("%w: 指标名称须以小写字母开头，只包含小写字母、数字和下划线，不超过63个字符", ErrInvalidMetric)
+	}
+	if req.DisplayName == "" {
+		return fmt.Errorf("%w: 显示名称不能为空", ErrInvalidMetric)
+	}
+	if err := workbench.CheckExpression(req.Expression); err != nil {
+		return fmt.Errorf("%w: 聚合表达式%v", ErrInvalidMetric, err)
+	}
+	if req.Filter != "" {
+		if err := workbench.CheckExpression(req.Filter); err != nil {
+			return fmt.Errorf("%w: 过滤条件%v", ErrInvalidMetric, err)
+		}
+	}
+	if len(req.Dimensions) > maxMetricDimensions {
+		return fmt.Errorf("%w: 维度不能超过%d个", ErrInvalidMetric, maxMetricDimensions)
+	}
+	seen := make(map[string]bool, len(req.Dimensions))
+	for i, dimension := range req.Dimensions {
+		dimension = strings.TrimSpace(dimension)
+		if dimension == "" || seen[dimension] {
+			return fmt.Errorf("%w: 维度字段不能为空或重复", ErrInvalidMetric)
+		}
+		seen[dimension] = true
+		req.Dimensions[i] = dimension
+	}
+	if req.TimeGrain != "" && !metricGrains[req.TimeGrain] {
+		return fmt.Errorf("%w: 时间粒度%s不支持，可选值: hour, day, week, month", ErrInvalidMetric, req.TimeGrain)
+	}
+	if (req.TimeGrain != "" || req.LookbackDays > 0) && req.TimeColumn == "" {
+		return fmt.Errorf("%w: 配置时间粒度或回溯天数时须指定时间字段", ErrInvalidMetric)
+	}
+	if req.LookbackDays < 0 {
+		return fmt.Errorf("%w: 回溯天数不能小于0", ErrInvalidMetric)
+	}
+	if _, err := cronParser.Parse(req.RefreshSchedule); err != nil {
+		return fmt.Errorf("%w: 刷新周期不是有效的cron表达式: %v", ErrInvalidMetric, err)
+	}
+	return nil
+}
+
+// validateFields 按接口字段配置校验维度和时间字段
+func validateFields(req *MetricRequest, table *metricTable) error {
+	if len(table.fields) == 0 {
+		return fmt.Errorf("%w: 接口未配置表字段", ErrInvalidMetric)
+	}
+	for _, dimension := range req.Dimensions {
+		field, ok := table.fields[dimension]
+		if !ok {
+			return fmt.Errorf("%w: 维度字段%s不在接口字段配置中", ErrInvalidMetric, dimension)
+		}
+		if field.IsSensitive {
+			return fmt.Errorf("%w: 敏感字段%s不能作为维度", ErrInvalidMetric, dimension)
+		}
+	}
+	if req.TimeColumn != "" {
+		if _, ok := table.fields[req.TimeColumn]; !ok {
+			return fmt.Errorf("%w: 时间字段%s不在接口字段配置中", ErrInvalidMetric, req.TimeColumn)
+		}
+	}
+	return nil
+}
+
+// buildMetricQuery 构建指标物化查询
+func buildMetricQuery(metric *models.MetricDefinition, table *metricTable, now time.Time) *metricQuery {
+	query := &metricQuery{dimensions: []string(metric.Dimensions), hasPeriod: metric.TimeGrain != ""}
+
+	var columns, groups []string
+	for i, dimension := range query.dimensions {
+		columns = append(columns, utils.QuoteIdent(dimension))
+		groups = append(groups, fmt.Sprint(i+1))
+	}
+	if query.hasPeriod {
+		columns = append(columns, fmt.Sprintf("date_trunc('%s', %s) AS metric_period", metric.TimeGrain, utils.QuoteIdent(metric.TimeColumn)))
+		groups = append(groups, fmt.Sprint(len(groups)+1))
+	}
+	columns = append(columns, fmt.Sprintf("(\n%s\n) AS metric_value", metric.Expression))
+
+	var conditions []string
+	if metric.LookbackDays > 0 {
+		conditions = append(conditions, utils.QuoteIdent(metric.TimeColumn)+" >= ?")
+		query.args = append(query.args, now.AddDate(0, 0, -metric.LookbackDays))
+	}
+	if metric.Filter != "" {
+		conditions = append(conditions, fmt.Sprintf("(\n%s\n)", metric.Filter))
+	}
+
+	var sql strings.Builder
+	fmt.Fprintf(&sql, "SELECT %s FROM %s.%s", strings.Join(columns, ", "), utils.QuoteIdent(table.schema), utils.QuoteIdent(table.table))
+	if len(conditions) > 0 {
+		sql.WriteString(" WHERE " + strings.Join(conditions, " AND "))
+	}
+	if len(groups) > 0 {
+		sql.WriteString(" GROUP BY " + strings.Join(groups, ", "))
+	}
+	fmt.Fprintf(&sql, " LIMIT %d", maxMetricValues+1)
+	query.sql = sql.String()
+	return query
+}
+
+// tableFields 解析接口字段配置，按英文字段名索引
+func tableFields(config models.JSONB) map[string]models.TableField {
+	fields := make(map[string]models.TableField, len(config))
+	for _, value := range config {
+		var field models.TableField
+		bytes, _ := json.Marshal(value)
+		if json.Unmarshal(bytes, &field) == nil && field.NameEn != "" {
+			fields[field.NameEn] = field
+		}
+	}
+	return fields
+}
+
+// dimensionValue 将维度取值转换为可序列化为JSON的值
+func dimensionValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case []byte:
+		return string(v)
+	case time.Time:
+		return v.Format(time.RFC3339)
+	}
+	return value
+}
@@ -0,0 +1,597 @@
+/*
+ * @module service/metric_store/service
+ * @description 指标层服务，管理基于接口表的业务指标定义，按各指标的刷新周期定期物化指标值，供看板按时间范围和维度读取
+ * @architecture 分层架构 - 业务服务层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow 定义指标 -> 计算下次刷新时间 -> 每分钟检查到期指标 -> 只读副本上执行分组聚合 -> 事务内替换指标值并记录刷新结果 -> 计算下次刷新时间；失败时保留上一次的指标值并记录应用事件
+ * @rules
+ *   - 物化查询在只读副本的只读事务中执行，超过refreshTimeout取消
+ *   - 同一指标同一时间只允许一次刷新；多实例部署时定时检查通过分布式锁只由一个实例执行
+ *   - 修改影响取值的定义项（接口、表达式、过滤条件、维度、时间字段、粒度、回溯天数）时清空已物化的指标值，下一次检查时重新物化
+ *   - 指标定义按租户隔离，接口须属于当前租户且已建表，不支持ClickHouse存储的主题接口
+ * @dependencies datahub-service/service/distributed_lock, datahub-service/service/eventlog, datahub-service/service/models, datahub-service/service/tenant, gorm.io/gorm, github.com/robfig/cron/v3
+ * @refs service/metric_store/query.go, service/models/metric_store.go, api/controllers/metric_store_controller.go
+ */
+
+package metric_store
+
+import (
+	"context"
+	"database/sql"
+	"datahub-service/service/distributed_lock"
+	"datahub-service/service/eventlog"
+	"datahub-service/service/meta"
+	"datahub-service/service/models"
+	"datahub-service/service/tenant"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	"gorm.io/gorm"
+)
+
+const (
+	// defaultCheckSchedule 默认每分钟检查一次到期的指标
+	defaultCheckSchedule = "0 * * * * *"
+	// refreshTimeout 单个指标物化查询的超时时间
+	refreshTimeout = 10 * time.Minute
+	// refreshLockKey 多实例部署时定时检查的锁
+	refreshLockKey = "metric_store_refresh"
+	refreshLockTTL = 30 * time.Minute
+)
+
+// EventMetricRefreshFailed 指标物化失败事件
+const EventMetricRefreshFailed = "metric_refresh_failed"
+
+// ObjectTypeMetric 指标在应用事件中的对象类型
+const ObjectTypeMetric = "metric_definition"
+
+var (
+	// ErrMetricRefreshing 指标正在刷新
+	ErrMetricRefreshing = errors.New("指标正在刷新，请稍后再试")
+	// ErrMetricRefreshFailed 指标物化失败
+	ErrMetricRefreshFailed = errors.New("指标物化失败")
+)
+
+// MetricRequest 创建或修改指标请求
+type MetricRequest struct {
+	Name            string   `json:"name" validate:"required,max=63" example:"daily_vehicle_entries"`
+	DisplayName     string   `json:"display_name" validate:"required,max=255" example:"每日车辆入场数"`
+	Description     string   `json:"description" validate:"max=1000"`
+	Unit            string   `json:"unit" validate:"max=50" example:"辆次"`
+	LibraryType     string   `json:"library_type" validate:"required,oneof=basic_library thematic_library" example:"thematic_library"`
+	InterfaceID     string   `json:"interface_id" validate:"required"`
+	Expression      string   `json:"expression" validate:"required,max=1000" example:"count(*)"`
+	Filter          string   `json:"filter" validate:"max=1000" example:"direction = 'in'"`
+	Dimensions      []string `json:"dimensions" example:"parking_lot"`
+	TimeColumn      string   `json:"time_column" example:"entered_at"`
+	TimeGrain       string   `json:"time_grain" validate:"omitempty,oneof=hour day week month" example:"day"`
+	LookbackDays    int      `json:"lookback_days" validate:"min=0" example:"90"`
+	RefreshSchedule string   `json:"refresh_schedule" validate:"required" example:"0 0 * * * *"`
+	Enabled         *bool    `json:"enabled"` // 默认启用
+}
+
+// ValueQuery 指标值查询条件
+type ValueQuery struct {
+	Start      *time.Time        // 时间周期起点不早于Start
+	End        *time.Time        // 时间周期起点早于End
+	Dimensions map[string]string // 按维度取值过滤，按文本比较
+}
+
+// MetricValues 指标及其物化的指标值
+type MetricValues struct {
+	Metric *models.MetricDefinition `json:"metric"`
+	Values []models.MetricValue     `json:"values"`
+}
+
+// RefreshSummary 一次定时检查的结果
+type RefreshSummary struct {
+	Due       int `json:"due"`
+	Succeeded int `json:"succeeded"`
+	Failed    int `json:"failed"`
+}
+
+// Service 指标层服务
+type Service struct {
+	db         *gorm.DB
+	readDB     *gorm.DB
+	lock       distributed_lock.DistributedLock
+	schedule   string
+	refreshing sync.Map // 正在刷新的指标ID
+	cron       *cron.Cron
+	ctx        context.Context
+	cancel     context.CancelFunc
+	started    bool
+}
+
+// NewService 创建指标层服务，物化查询在readDB上执行，检查周期可通过METRIC_STORE_CHECK_SCHEDULE配置
+func NewService(db, readDB *gorm.DB) *Service {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	schedule := os.Getenv("METRIC_STORE_CHECK_SCHEDULE")
+	if schedule == "" {
+		schedule = defaultCheckSchedule
+	}
+
+	return &Service{
+		db:       db,
+		readDB:   readDB,
+		schedule: schedule,
+		cron:     cron.New(cron.WithSeconds()),
+		ctx:      ctx,
+		cancel:   cancel,
+	}
+}
+
+// SetDistributedLock 设置分布式锁，多实例部署时避免重复刷新
+func (s *Service) SetDistributedLock(lock distributed_lock.DistributedLock) {
+	s.lock = lock
+}
+
+// CreateMetric 创建指标
+func (s *Service) CreateMetric(ctx context.Context, req *MetricRequest, operator string) (*models.MetricDefinition, error) {
+	if err := s.validate(ctx, req, ""); err != nil {
+		return nil, err
+	}
+
+	metric := &models.MetricDefinition{CreatedBy: operator, UpdatedBy: operator}
+	applyRequest(metric, req)
+	metric.NextRefreshAt = nextRefreshAt(metric, time.Now())
+	if err := s.db.WithContext(ctx).Create(metric).Error; err != nil {
+		return nil, err
+	}
+	return metric, nil
+}
+
+// ListMetrics 分页获取指标，可按名称关键字和接口过滤
+func (s *Service) ListMetrics(ctx context.Context, keyword, interfaceID string, page, pageSize int) ([]models.MetricDefinition, int64, error) {
+	db := s.db.WithContext(ctx).Model(&models.MetricDefinition{})
+	if keyword != "" {
+		db = db.Where("name LIKE ? OR display_name LIKE ?", "%"+keyword+"%", "%"+keyword+"%")
+	}
+	if interfaceID != "" {
+		db = db.Where("interface_id = ?", interfaceID)
+	}
+
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	page, pageSize = models.NormalizePage(page, pageSize)
+	var metrics []models.MetricDefinition
+	err := db.Order("name").Offset((page - 1) * pageSize).Limit(pageSize).Find(&metrics).Error
+	return metrics, total, err
+}
+
+// GetMetric 获取指标
+func (s *Service) GetMetric(ctx context.Context, id string) (*models.MetricDefinition, error) {
+	var metric models.MetricDefinition
+	if err := s.db.WithContext(ctx).First(&metric, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &metric, nil
+}
+
+// UpdateMetric 修改指标，影响取值的定义项变化时清空已物化的指标值
+func (s *Service) UpdateMetric(ctx context.Context, id string, req *MetricRequest, operator string) (*models.MetricDefinition, error) {
+	metric, err := s.GetMetric(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.validate(ctx, req, id); err != nil {
+		return nil, err
+	}
+
+	before := *metric
+	applyRequest(metric, req)
+	metric.UpdatedBy = operator
+	now := time.Now()
+	reset := definitionChanged(&before, metric)
+	if reset {
+		metric.LastRefreshedAt = nil
+		metric.LastRefreshStatus = ""
+		metric.LastRefreshError = ""
+		metric.LastDurationMs = 0
+		metric.ValueCount = 0
+		if metric.Enabled {
+			metric.NextRefreshAt = &now
+		} else {
+			metric.NextRefreshAt = nil
+		}
+	} else if before.RefreshSchedule != metric.RefreshSchedule || before.Enabled != metric.Enabled {
+		metric.NextRefreshAt = nextRefreshAt(metric, now)
+	}
+
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if reset {
+			if err := tx.Where("metric_id = ?", id).Delete(&models.MetricValue{}).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Save(metric).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return metric, nil
+}
+
+// DeleteMetric 删除指标及其指标值
+func (s *Service) DeleteMetric(ctx context.Context, id string) error {
+	if _, err := s.GetMetric(ctx, id); err != nil {
+		return err
+	}
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("metric_id = ?", id).Delete(&models.MetricValue{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.MetricDefinition{}, "id = ?", id).Error
+	})
+}
+
+// GetValues 获取指标最近一次物化的指标值，按时间周期排序；时间范围只对配置了时间粒度的指标有效
+func (s *Service) GetValues(ctx context.Context, id string, query ValueQuery) (*MetricValues, error) {
+	metric, err := s.GetMetric(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	db := s.db.WithContext(ctx).Where("metric_id = ?", id)
+	if query.Start != nil {
+		db = db.Where("period >= ?", *query.Start)
+	}
+	if query.End != nil {
+		db = db.Where("period < ?", *query.End)
+	}
+	var values []models.MetricValue
+	if err := db.Order("period").Limit(maxMetricValues).Find(&values).Error; err != nil {
+		return nil, err
+	}
+
+	result := &MetricValues{Metric: metric, Values: make([]models.MetricValue, 0, len(values))}
+	for _, value := range values {
+		if matchDimensions(value.Dimensions, query.Dimensions) {
+			result.Values = append(result.Values, value)
+		}
+	}
+	return result, nil
+}
+
+// RefreshMetric 立即物化指标，物化失败时返回ErrMetricRefreshFailed和记录了失败原因的指标
+func (s *Service) RefreshMetric(ctx context.Context, id string) (*models.MetricDefinition, error) {
+	metric, err := s.GetMetric(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.refresh(ctx, metric); err != nil {
+		if errors.Is(err, ErrMetricRefreshing) {
+			return nil, err
+		}
+		return metric, err
+	}
+	return metric, nil
+}
+
+// RefreshDue 物化所有到期的启用指标
+func (s *Service) RefreshDue(ctx context.Context) (*RefreshSummary, error) {
+	if s.lock != nil {
+		locked, err := s.lock.TryLock(ctx, refreshLockKey, refreshLockTTL)
+		if err != nil {
+			return nil, fmt.Errorf("获取指标刷新锁失败: %w", err)
+		}
+		if !locked {
+			return &RefreshSummary{}, nil
+		}
+		defer func() {
+			if err := s.lock.Unlock(context.Background(), refreshLockKey); err != nil {
+				slog.Error("释放指标刷新锁失败", "error", err)
+			}
+		}()
+	}
+
+	var metrics []models.MetricDefinition
+	err := s.db.WithContext(ctx).
+		Where("enabled = ? AND next_refresh_at IS NOT NULL AND next_refresh_at <= ?", true, time.Now()).
+		Order("next_refresh_at").
+		Find(&metrics).Error
+	if err != nil {
+		return nil, fmt.Errorf("查询到期指标失败: %w", err)
+	}
+
+	summary := &RefreshSummary{Due: len(metrics)}
+	for i := range metrics {
+		if ctx.Err() != nil {
+			break
+		}
+		err := s.refresh(ctx, &metrics[i])
+		switch {
+		case err == nil:
+			summary.Succeeded++
+		case errors.Is(err, ErrMetricRefreshing):
+		default:
+			summary.Failed++
+		}
+	}
+	return summary, nil
+}
+
+// Start 启动定时检查
+func (s *Service) Start() error {
+	if s.started {
+		return fmt.Errorf("指标刷新调度器已经启动")
+	}
+
+	_, err := s.cron.AddFunc(s.schedule, func() {
+		summary, err := s.RefreshDue(s.ctx)
+		if err != nil {
+			slog.Error("定时刷新指标失败", "error", err)
+			return
+		}
+		if summary.Due > 0 {
+			slog.Info("定时刷新指标完成", "due", summary.Due, "succeeded", summary.Succeeded, "failed", summary.Failed)
+		}
+	})
+	if err != nil {
+		return fmt.Errorf("添加定时任务失败: %w", err)
+	}
+
+	s.cron.Start()
+	s.started = true
+	slog.Info("指标刷新调度器启动成功", "schedule", s.schedule)
+	return nil
+}
+
+// Stop 停止定时检查
+func (s *Service) Stop() {
+	if !s.started {
+		return
+	}
+	s.cancel()
+	s.cron.Stop()
+	s.started = false
+}
+
+// refresh 物化指标值并记录刷新结果，完成后计算下次刷新时间
+func (s *Service) refresh(ctx context.Context, metric *models.MetricDefinition) error {
+	if _, loaded := s.refreshing.LoadOrStore(metric.ID, true); loaded {
+		return ErrMetricRefreshing
+	}
+	defer s.refreshing.Delete(metric.ID)
+
+	startedAt := time.Now()
+	values, err := s.materialize(ctx, metric, startedAt)
+	finishedAt := time.Now()
+
+	metric.LastRefreshedAt = &finishedAt
+	metric.LastDurationMs = finishedAt.Sub(startedAt).Milliseconds()
+	metric.NextRefreshAt = nextRefreshAt(metric, finishedAt)
+	updates := map[string]interface{}{
+		"last_refreshed_at": metric.LastRefreshedAt,
+		"last_duration_ms":  metric.LastDurationMs,
+		"next_refresh_at":   metric.NextRefreshAt,
+	}
+
+	if err != nil {
+		metric.LastRefreshStatus = models.MetricRefreshFailed
+		metric.LastRefreshError = err.Error()
+		updates["last_refresh_status"] = metric.LastRefreshStatus
+		updates["last_refresh_error"] = metric.LastRefreshError
+		if updateErr := s.db.WithContext(context.WithoutCancel(ctx)).Model(&models.MetricDefinition{}).
+			Where("id = ?", metric.ID).Updates(updates).Error; updateErr != nil {
+			slog.ErrorContext(ctx, "记录指标刷新结果失败", "metric_id", metric.ID, "error", updateErr)
+		}
+		eventlog.Record(ctx, eventlog.Event{
+			Type:       EventMetricRefreshFailed,
+			Level:      models.EventLevelWarn,
+			Message:    "指标物化失败: " + err.Error(),
+			ObjectType: ObjectTypeMetric,
+			ObjectID:   metric.ID,
+			Attributes: map[string]interface{}{"name": metric.Name, "interface_id": metric.InterfaceID},
+		})
+		return fmt.Errorf("%w: %v", ErrMetricRefreshFailed, err)
+	}
+
+	metric.LastRefreshStatus = models.MetricRefreshSucceeded
+	metric.LastRefreshError = ""
+	metric.ValueCount = len(values)
+	updates["last_refresh_status"] = metric.LastRefreshStatus
+	updates["last_refresh_error"] = ""
+	updates["value_count"] = metric.ValueCount
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("metric_id = ?", metric.ID).Delete(&models.MetricValue{}).Error; err != nil {
+			return err
+		}
+		if len(values) > 0 {
+			if err := tx.CreateInBatches(values, 500).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Model(&models.MetricDefinition{}).Where("id = ?", metric.ID).Updates(updates).Error
+	})
+}
+
+// materialize 在只读事务中执行物化查询
+func (s *Service) materialize(ctx context.Context, metric *models.MetricDefinition, now time.Time) ([]models.MetricValue, error) {
+	table, err := s.resolveTable(ctx, metric.LibraryType, metric.InterfaceID)
+	if err != nil {
+		return nil, err
+	}
+	query := buildMetricQuery(metric, table, now)
+
+	ctx, cancel := context.WithTimeout(ctx, refreshTimeout)
+	defer cancel()
+
+	var values []models.MetricValue
+	err = s.readDB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		rows, err := tx.Raw(query.sql, query.args...).Rows()
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		dimensions := make([]interface{}, len(query.dimensions))
+		var period sql.NullTime
+		var value sql.NullFloat64
+		dest := make([]interface{}, 0, len(dimensions)+2)
+		for i := range dimensions {
+			dest = append(dest, &dimensions[i])
+		}
+		if query.hasPeriod {
+			dest = append(dest, &period)
+		}
+		dest = append(dest, &value)
+
+		for rows.Next() {
+			if len(values) >= maxMetricValues {
+				return fmt.Errorf("分组结果超过%d条，请减少维度或缩短回溯天数", maxMetricValues)
+			}
+			if err := rows.Scan(dest...); err != nil {
+				return err
+			}
+			record := models.MetricValue{MetricID: metric.ID, RefreshedAt: now}
+			if len(dimensions) > 0 {
+				record.Dimensions = make(models.JSONB, len(dimensions))
+				for i, name := range query.dimensions {
+					record.Dimensions[name] = dimensionValue(dimensions[i])
+				}
+			}
+			if query.hasPeriod && period.Valid {
+				at := period.Time
+				record.Period = &at
+			}
+			if value.Valid && !math.IsNaN(value.Float64) && !math.IsInf(value.Float64, 0) {
+				v := value.Float64
+				record.Value = &v
+			}
+			values = append(values, record)
+		}
+		return rows.Err()
+	}, &sql.TxOptions{ReadOnly: true})
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return nil, fmt.Errorf("物化查询超过%s未完成", refreshTimeout)
+	}
+	return values, err
+}
+
+// validate 校验指标请求，excludeID为修改时的指标ID
+func (s *Service) validate(ctx context.Context, req *MetricRequest, excludeID string) error {
+	if err := validateRequest(req); err != nil {
+		return err
+	}
+	table, err := s.resolveTable(ctx, req.LibraryType, req.InterfaceID)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidMetric, err)
+	}
+	if err := validateFields(req, table); err != nil {
+		return err
+	}
+
+	var count int64
+	db := s.db.WithContext(ctx).Model(&models.MetricDefinition{}).Where("name = ?", req.Name)
+	if excludeID != "" {
+		db = db.Where("id <> ?", excludeID)
+	}
+	if err := db.Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return fmt.Errorf("%w: 指标名称%s已存在", ErrInvalidMetric, req.Name)
+	}
+	return nil
+}
+
+// resolveTable 查询指标所基于的接口表，接口须属于当前租户且已建表
+func (s *Service) resolveTable(ctx context.Context, libraryType, interfaceID string) (*metricTable, error) {
+	switch libraryType {
+	case meta.LibraryTypeBasic:
+		var iface models.DataInterface
+		err := s.db.WithContext(ctx).Scopes(tenant.OwnerScope(ctx, "library_id", "basic_libraries")).
+			Preload("BasicLibrary").First(&iface, "id = ?", interfaceID).Error
+		if err != nil {
+			return nil, fmt.Errorf("接口不存在: %w", err)
+		}
+		if !iface.IsTableCreated {
+			return nil, fmt.Errorf("接口%s尚未建表", iface.NameZh)
+		}
+		return &metricTable{schema: iface.BasicLibrary.GetSchemaName(), table: iface.NameEn, fields: tableFields(iface.TableFieldsConfig)}, nil
+	case meta.LibraryTypeThematic:
+		var iface models.ThematicInterface
+		err := s.db.WithContext(ctx).Scopes(tenant.OwnerScope(ctx, "library_id", "thematic_libraries")).
+			Preload("ThematicLibrary").First(&iface, "id = ?", interfaceID).Error
+		if err != nil {
+			return nil, fmt.Errorf("接口不存在: %w", err)
+		}
+		if !iface.IsTableCreated {
+			return nil, fmt.Errorf("接口%s尚未建表", iface.NameZh)
+		}
+		if iface.IsClickHouse() {
+			return nil, fmt.Errorf("接口%s使用ClickHouse存储，暂不支持定义指标", iface.NameZh)
+		}
+		return &metricTable{schema: iface.ThematicLibrary.GetSchemaName(), table: iface.NameEn, fields: tableFields(iface.TableFieldsConfig)}, nil
+	default:
+		return nil, fmt.Errorf("不支持的库类型: %s", libraryType)
+	}
+}
+
+// applyRequest 将请求中的定义项写入指标
+func applyRequest(metric *models.MetricDefinition, req *MetricRequest) {
+	metric.Name = req.Name
+	metric.DisplayName = req.DisplayName
+	metric.Description = req.Description
+	metric.Unit = req.Unit
+	metric.LibraryType = req.LibraryType
+	metric.InterfaceID = req.InterfaceID
+	metric.Expression = req.Expression
+	metric.Filter = req.Filter
+	metric.Dimensions = models.JSONBStringArray(req.Dimensions)
+	metric.TimeColumn = req.TimeColumn
+	metric.TimeGrain = req.TimeGrain
+	metric.LookbackDays = req.LookbackDays
+	metric.RefreshSchedule = req.RefreshSchedule
+	metric.Enabled = req.Enabled == nil || *req.Enabled
+}
+
+// definitionChanged 影响取值的定义项是否变化
+func definitionChanged(before, after *models.MetricDefinition) bool {
+	if len(before.Dimensions) != len(after.Dimensions) {
+		return true
+	}
+	for i := range before.Dimensions {
+		if before.Dimensions[i] != after.Dimensions[i] {
+			return true
+		}
+	}
+	return before.LibraryType != after.LibraryType || before.InterfaceID != after.InterfaceID ||
+		before.Expression != after.Expression || before.Filter != after.Filter ||
+		before.TimeColumn != after.TimeColumn || before.TimeGrain != after.TimeGrain ||
+		before.LookbackDays != after.LookbackDays
+}
+
+// nextRefreshAt 按刷新周期计算下次刷新时间，停用或周期无效时返回nil
+func nextRefreshAt(metric *models.MetricDefinition, now time.Time) *time.Time {
+	if !metric.Enabled {
+		return nil
+	}
+	schedule, err := cronParser.Parse(metric.RefreshSchedule)
+	if err != nil {
+		return nil
+	}
+	next := schedule.Next(now)
+	return &next
+}
+
+// matchDimensions 指标值的维度取值是否满足过滤条件
+func matchDimensions(dimensions models.JSONB, filters map[string]string) bool {
+	for name, expected := range filters {
+		value, ok := dimensions[name]
+		if !ok || value == nil || fmt.Sprint(value) != expected {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,175 @@
+/*
+ * @module service/metrics/metrics
+ * @description 业务指标采集，在默认Prometheus注册表上暴露同步(含批量大小自适应调整)、质量检测、调度、数据共享、目录缓存和保留策略清理相关指标
+ * @architecture 分层架构 - 业务服务层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow 业务执行完成 -> 调用Observe/Set方法 -> 更新指标 -> /metrics抓取
+ * @rules 指标名统一使用datahub_前缀；标签只使用库类型、库ID、接口ID、应用ID、调度器名和状态等有限取值，不使用请求路径等高基数值
+ * @dependencies github.com/prometheus/client_golang
+ * @refs main.go, service/basic_library/sync_task_service.go, service/thematic_library/thematic_sync/sync_engine.go, service/governance/quality_task_service.go, api/controllers/data_proxy_controller.go, service/catalog_cache/cache.go, service/cleanup/execution_retention.go, service/interface_executor/batch_tuner.go
+ */
+
+package metrics
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// 调度器名称
+const (
+	SchedulerSync    = "sync"
+	SchedulerQuality = "quality"
+)
+
+var (
+	syncExecutionsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "datahub_sync_executions_total",
+		Help: "同步任务执行次数，按库和执行结果统计",
+	}, []string{"library_type", "library_id", "status"})
+
+	syncRowsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "datahub_sync_rows_total",
+		Help: "同步处理的数据行数，可通过rate()计算每秒同步行数",
+	}, []string{"library_type", "library_id"})
+
+	syncRowsPerSecond = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "datahub_sync_rows_per_second",
+		Help: "最近一次同步执行的每秒处理行数",
+	}, []string{"library_type", "library_id"})
+
+	syncExecutionDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "datahub_sync_execution_duration_seconds",
+		Help:    "同步任务单次执行耗时",
+		Buckets: []float64{1, 5, 15, 30, 60, 120, 300, 600, 1800, 3600},
+	}, []string{"library_type"})
+
+	syncBatchDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "datahub_sync_batch_duration_seconds",
+		Help:    "同步批次耗时，基础库为单个接口调用，主题库为单个写入批次",
+		Buckets: prometheus.ExponentialBuckets(0.01, 2, 14),
+	}, []string{"library_type"})
+
+	qualityScore = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "datahub_quality_score",
+		Help: "最近一次质量检测的总体得分(0-1)，按接口统计",
+	}, []string{"library_type", "interface_id"})
+
+	qualityExecutionsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "datahub_quality_executions_total",
+		Help: "质量检测任务执行次数，按执行结果统计",
+	}, []string{"status"})
+
+	schedulerQueueDepth = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "datahub_scheduler_queue_depth",
+		Help: "已触发但尚未执行完成的调度任务数",
+	}, []string{"scheduler"})
+
+	sharingRequestsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "datahub_sharing_requests_total",
+		Help: "数据共享接口请求次数，按应用和响应状态码统计",
+	}, []string{"application_id", "code"})
+
+	sharingRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "datahub_sharing_request_duration_seconds",
+		Help:    "数据共享接口请求耗时",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"application_id"})
+
+	catalogCacheRequestsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "datahub_catalog_cache_requests_total",
+		Help: "目录数据缓存读取次数，按列表名称和是否命中统计",
+	}, []string{"name", "result"})
+
+	retentionPurgedRowsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "datahub_retention_purged_rows_total",
+		Help: "保留策略清理的记录数，按表统计",
+	}, []string{"table"})
+
+	retentionArchivedRowsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "datahub_retention_archived_rows_total",
+		Help: "清理前归档的记录数，按表统计",
+	}, []string{"table"})
+
+	retentionFailuresTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "datahub_retention_failures_total",
+		Help: "保留策略清理失败次数，按表统计，归档失败时该批记录不删除",
+	}, []string{"table"})
+
+	syncBatchAdjustmentsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "datahub_sync_batch_adjustments_total",
+		Help: "批量同步自适应调整批量大小的次数，按阶段(fetch、write)和方向(increase、decrease)统计",
+	}, []string{"stage", "direction"})
+)
+
+// ObserveSyncExecution 记录一次同步任务执行的结果、处理行数和耗时
+func ObserveSyncExecution(libraryType, libraryID, status string, rows int64, duration time.Duration) {
+	syncExecutionsTotal.WithLabelValues(libraryType, libraryID, status).Inc()
+	syncExecutionDuration.WithLabelValues(libraryType).Observe(duration.Seconds())
+
+	if rows > 0 {
+		syncRowsTotal.WithLabelValues(libraryType, libraryID).Add(float64(rows))
+	}
+	if seconds := duration.Seconds(); seconds > 0 {
+		syncRowsPerSecond.WithLabelValues(libraryType, libraryID).Set(float64(rows) / seconds)
+	}
+}
+
+// ObserveSyncBatch 记录一个同步批次的耗时
+func ObserveSyncBatch(libraryType string, duration time.Duration) {
+	syncBatchDuration.WithLabelValues(libraryType).Observe(duration.Seconds())
+}
+
+// ObserveSyncBatchAdjustment 记录一次批量大小自适应调整
+func ObserveSyncBatchAdjustment(stage, direction string) {
+	syncBatchAdjustmentsTotal.WithLabelValues(stage, direction).Inc()
+}
+
+// ObserveQualityExecution 记录一次质量检测执行，接口ID为空时只统计执行次数
+func ObserveQualityExecution(libraryType, interfaceID, status string, score float64) {
+	qualityExecutionsTotal.WithLabelValues(status).Inc()
+	if interfaceID != "" {
+		qualityScore.WithLabelValues(libraryType, interfaceID).Set(score)
+	}
+}
+
+// TrackScheduledTask 调度任务开始执行时调用，返回的函数在执行结束时调用
+func TrackScheduledTask(scheduler string) func() {
+	gauge := schedulerQueueDepth.WithLabelValues(scheduler)
+	gauge.Inc()
+	return gauge.Dec
+}
+
+// ObserveSharingRequest 记录一次数据共享接口请求，应用ID未知时记为unknown
+func ObserveSharingRequest(applicationID string, statusCode int, duration time.Duration) {
+	if applicationID == "" {
+		applicationID = "unknown"
+	}
+	sharingRequestsTotal.WithLabelValues(applicationID, strconv.Itoa(statusCode)).Inc()
+	sharingRequestDuration.WithLabelValues(applicationID).Observe(duration.Seconds())
+}
+
+// ObserveCatalogCache 记录一次目录数据缓存读取
+func ObserveCatalogCache(name string, hit bool) {
+	result := "miss"
+	if hit {
+		result = "hit"
+	}
+	catalogCacheRequestsTotal.WithLabelValues(name, result).Inc()
+}
+
+// ObserveRetention 记录一张表的一次保留策略清理，err不为空时计一次失败
+func ObserveRetention(table string, purged, archived int64, err error) {
+	if purged > 0 {
+		retentionPurgedRowsTotal.WithLabelValues(table).Add(float64(purged))
+	}
+	if archived > 0 {
+		retentionArchivedRowsTotal.WithLabelValues(table).Add(float64(archived))
+	}
+	if err != nil {
+		retentionFailuresTotal.WithLabelValues(table).Inc()
+	}
+}
@@ -0,0 +1,69 @@
+/*
+ * @module service/metrics/metrics_test
+ * @description 业务指标测试，验证各观测方法更新对应指标
+ * @architecture 测试层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow 调用观测方法 -> 读取指标值 -> 验证
+ * @rules 不依赖外部服务，各用例使用独立的标签值避免相互影响
+ * @dependencies github.com/prometheus/client_golang/prometheus/testutil, github.com/stretchr/testify
+ * @refs service/metrics/metrics.go
+ */
+
+package metrics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestObserveSyncExecution(t *testing.T) {
+	ObserveSyncExecution("basic_library", "lib-1", "success", 200, 2*time.Second)
+	ObserveSyncExecution("basic_library", "lib-1", "failed", 0, time.Second)
+
+	assert.Equal(t, 1.0, testutil.ToFloat64(syncExecutionsTotal.WithLabelValues("basic_library", "lib-1", "success")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(syncExecutionsTotal.WithLabelValues("basic_library", "lib-1", "failed")))
+	assert.Equal(t, 200.0, testutil.ToFloat64(syncRowsTotal.WithLabelValues("basic_library", "lib-1")))
+	// 最近一次执行失败且无处理行数
+	assert.Equal(t, 0.0, testutil.ToFloat64(syncRowsPerSecond.WithLabelValues("basic_library", "lib-1")))
+}
+
+func TestObserveQualityExecution(t *testing.T) {
+	ObserveQualityExecution("thematic_library", "if-1", "completed", 0.95)
+	ObserveQualityExecution("thematic_library", "", "failed", 0)
+
+	assert.Equal(t, 0.95, testutil.ToFloat64(qualityScore.WithLabelValues("thematic_library", "if-1")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(qualityExecutionsTotal.WithLabelValues("failed")))
+}
+
+func TestTrackScheduledTask(t *testing.T) {
+	gauge := schedulerQueueDepth.WithLabelValues(SchedulerQuality)
+
+	done1 := TrackScheduledTask(SchedulerQuality)
+	done2 := TrackScheduledTask(SchedulerQuality)
+	assert.Equal(t, 2.0, testutil.ToFloat64(gauge))
+
+	done1()
+	done2()
+	assert.Equal(t, 0.0, testutil.ToFloat64(gauge))
+}
+
+func TestObserveSharingRequest(t *testing.T) {
+	ObserveSharingRequest("", 401, 10*time.Millisecond)
+	ObserveSharingRequest("app-1", 200, 20*time.Millisecond)
+
+	assert.Equal(t, 1.0, testutil.ToFloat64(sharingRequestsTotal.WithLabelValues("unknown", "401")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(sharingRequestsTotal.WithLabelValues("app-1", "200")))
+	assert.Equal(t, 2, testutil.CollectAndCount(sharingRequestDuration))
+}
+
+func TestObserveRetention(t *testing.T) {
+	ObserveRetention("system_logs", 120, 120, nil)
+	ObserveRetention("system_logs", 0, 0, assert.AnError)
+
+	assert.Equal(t, 120.0, testutil.ToFloat64(retentionPurgedRowsTotal.WithLabelValues("system_logs")))
+	assert.Equal(t, 120.0, testutil.ToFloat64(retentionArchivedRowsTotal.WithLabelValues("system_logs")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(retentionFailuresTotal.WithLabelValues("system_logs")))
+}
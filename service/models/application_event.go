@@ -0,0 +1,51 @@
+/*
+ * @module service/models/application_event
+ * @description 应用事件模型，记录任务启动、批次失败等重要运行事件及其请求ID和链路ID，用于从响应追溯到执行过程
+ * @architecture 分层架构 - 数据模型层
+ * @documentReference ai_docs/model.md
+ * @stateFlow 业务代码记录事件 -> 写入application_events -> 按请求ID、对象或事件类型查询
+ * @rules 事件只追加不修改；由HTTP请求触发的事件必须带请求ID，异步执行时沿用触发请求的请求ID
+ * @dependencies gorm.io/gorm, github.com/google/uuid
+ * @refs service/eventlog, api/controllers/event_log_controller.go
+ */
+
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// 事件级别
+const (
+	EventLevelInfo  = "info"
+	EventLevelWarn  = "warn"
+	EventLevelError = "error"
+)
+
+// ApplicationEvent 应用事件
+type ApplicationEvent struct {
+	ID         string    `gorm:"type:uuid;primary_key" json:"id"`
+	EventType  string    `gorm:"not null;size:50;index" json:"event_type"` // task_started/task_completed/task_failed/batch_failed等
+	Level      string    `gorm:"not null;size:10;default:'info'" json:"level"`
+	Message    string    `gorm:"type:text" json:"message"`
+	RequestID  string    `gorm:"size:64;index" json:"request_id"` // 触发请求的X-Request-ID
+	TraceID    string    `gorm:"size:32" json:"trace_id"`
+	ObjectType string    `gorm:"size:50" json:"object_type"` // sync_task/interface等
+	ObjectID   string    `gorm:"size:100;index" json:"object_id"`
+	Attributes JSONB     `gorm:"type:jsonb" json:"attributes,omitempty"`
+	CreatedAt  time.Time `gorm:"not null;default:CURRENT_TIMESTAMP;index" json:"created_at"`
+}
+
+// BeforeCreate 创建前钩子
+func (e *ApplicationEvent) BeforeCreate(tx *gorm.DB) error {
+	if e.ID == "" {
+		e.ID = uuid.New().String()
+	}
+	if e.Level == "" {
+		e.Level = EventLevelInfo
+	}
+	return nil
+}
@@ -21,8 +21,10 @@ import (
 // BasicLibrary 数据基础库模型
 type BasicLibrary struct {
 	ID          string        `json:"id" gorm:"primaryKey;type:varchar(36)" example:"550e8400-e29b-41d4-a716-446655440000"`
+	TenantID    string        `json:"tenant_id" gorm:"not null;size:36;default:'default';index"` // 所属租户
 	NameZh      string        `json:"name_zh" gorm:"not null;size:255" example:"用户数据基础库"`
 	NameEn      string        `json:"name_en" gorm:"not null;unique;size:255" example:"user_basic_library"`
+	SchemaName  string        `json:"schema_name" gorm:"size:255"` // 接口表所在schema，非默认租户为"租户编码_英文名称"
 	Description string        `json:"description" gorm:"size:1000" example:"存储用户基础信息的数据库"`
 	CreatedAt   time.Time     `json:"created_at" gorm:"not null;default:CURRENT_TIMESTAMP"`
 	CreatedBy   string        `json:"created_by" gorm:"not null;default:'system';size:100"`
@@ -52,10 +54,13 @@ type DataInterface struct {
 	InterfaceConfig   JSONB     `json:"interface_config" gorm:"type:jsonb"`
 	ParseConfig       JSONB     `json:"parse_config" gorm:"type:jsonb"`
 	TableFieldsConfig JSONB     `json:"table_fields_config" gorm:"type:jsonb"`
+	RowVersion        int64     `json:"row_version" gorm:"not null;default:1"` // 乐观锁版本
 	// 关联关系
 	BasicLibrary BasicLibrary    `json:"basic_library,omitempty" gorm:"foreignKey:LibraryID"`
 	DataSource   DataSource      `json:"data_source,omitempty" gorm:"foreignKey:DataSourceID"`
 	CleanRules   []CleansingRule `json:"clean_rules,omitempty" gorm:"foreignKey:InterfaceID"`
+	// Freshness 数据新鲜度，列表中用于展示数据过期标记
+	Freshness *InterfaceFreshness `json:"freshness,omitempty" gorm:"foreignKey:InterfaceID"`
 }
 
 // DataSource 数据源模型
@@ -74,10 +79,30 @@ type DataSource struct {
 	CreatedBy        string    `json:"created_by" gorm:"not null;default:'system';size:100"`
 	UpdatedAt        time.Time `json:"updated_at" gorm:"not null;default:CURRENT_TIMESTAMP"`
 	UpdatedBy        string    `json:"updated_by" gorm:"not null;default:'system';size:100"`
+
+	// 维护模式，上游计划停机期间跳过依赖同步任务的调度
+	MaintenanceMode   bool       `json:"maintenance_mode" gorm:"not null;default:false"`
+	MaintenanceReason string     `json:"maintenance_reason,omitempty" gorm:"size:500"`
+	MaintenanceSince  *time.Time `json:"maintenance_since,omitempty"`
+	MaintenanceBy     string     `json:"maintenance_by,omitempty" gorm:"size:100"`
+
+	// 熔断，连续拉取失败达到阈值后在冷却期内短路依赖同步任务的执行
+	CircuitState     string     `json:"circuit_state" gorm:"not null;default:'closed';size:20"`
+	CircuitFailures  int        `json:"circuit_failures" gorm:"not null;default:0"` // 连续失败次数
+	CircuitSince     *time.Time `json:"circuit_since,omitempty"`                    // 进入当前熔断状态的时间
+	CircuitLastError string     `json:"circuit_last_error,omitempty" gorm:"size:1000"`
+
 	// 关联关系
 	BasicLibrary BasicLibrary `json:"basic_library,omitempty" gorm:"foreignKey:LibraryID"`
 }
 
+// 数据源熔断状态
+const (
+	CircuitStateClosed   = "closed"    // 正常执行
+	CircuitStateOpen     = "open"      // 冷却期内短路依赖任务的执行
+	CircuitStateHalfOpen = "half_open" // 冷却期已过，正在探测数据源
+)
+
 // CleansingRule 数据清洗规则模型
 type CleansingRule struct {
 	ID          string                 `json:"id" gorm:"primaryKey;type:varchar(36)"`
@@ -90,6 +115,14 @@ type CleansingRule struct {
 	CreatedBy   string                 `json:"created_by" gorm:"not null;default:'system';size:100"`
 }
 
+// GetSchemaName 获取基础库接口表所在的schema，历史数据未记录时使用英文名称
+func (bl BasicLibrary) GetSchemaName() string {
+	if bl.SchemaName != "" {
+		return bl.SchemaName
+	}
+	return bl.NameEn
+}
+
 // BeforeCreate GORM钩子，创建前生成UUID
 func (bl *BasicLibrary) BeforeCreate(tx *gorm.DB) error {
 	if bl.ID == "" {
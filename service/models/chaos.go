@@ -0,0 +1,74 @@
+/*
+ * @module service/models/chaos
+ * @description 故障注入模型，用于非生产环境演练告警与自愈流程
+ * @architecture 分层架构 - 数据模型层
+ * @documentReference ai_docs/model.md
+ * @stateFlow active（生效中） -> stopped（手动停止） / expired（到期自动失效）
+ * @rules 每个故障必须设置结束时间；注入的创建、命中、停止均写入系统日志审计
+ * @dependencies gorm.io/gorm, github.com/google/uuid
+ * @refs service/chaos, api/controllers/chaos_controller.go
+ */
+
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// 故障类型
+const (
+	FaultTypeDataSourceTimeout = "datasource_timeout" // 数据源请求超时
+	FaultTypeDBWriteFailure    = "db_write_failure"   // 写库失败
+	FaultTypeLockContention    = "lock_contention"    // 分布式锁争用
+	FaultTypeScheduleDelay     = "schedule_delay"     // 调度延迟
+)
+
+// 故障注入状态
+const (
+	FaultInjectionStatusActive  = "active"
+	FaultInjectionStatusStopped = "stopped"
+	FaultInjectionStatusExpired = "expired"
+)
+
+// FaultInjection 故障注入配置
+type FaultInjection struct {
+	ID           string     `gorm:"type:uuid;primary_key" json:"id"`
+	FaultType    string     `gorm:"not null;size:50;index" json:"fault_type"` // datasource_timeout/db_write_failure/lock_contention/schedule_delay
+	Target       string     `gorm:"size:255" json:"target"`                   // 作用目标：数据源ID、表名、锁键或任务ID，为空表示全部
+	Probability  float64    `gorm:"not null;default:1" json:"probability"`    // 命中概率 0-1
+	DelayMs      int        `gorm:"not null;default:0" json:"delay_ms"`       // 注入延迟（毫秒）
+	ErrorMessage string     `gorm:"size:500" json:"error_message"`            // 自定义错误信息
+	Status       string     `gorm:"not null;size:20;default:'active';index" json:"status"`
+	Description  string     `gorm:"type:text" json:"description"`
+	ExpiresAt    time.Time  `gorm:"not null" json:"expires_at"` // 到期自动失效
+	HitCount     int64      `gorm:"not null;default:0" json:"hit_count"`
+	LastHitAt    *time.Time `json:"last_hit_at"`
+	StoppedAt    *time.Time `json:"stopped_at"`
+	StoppedBy    string     `gorm:"size:100" json:"stopped_by"`
+	Environment  string     `gorm:"size:50" json:"environment"` // 创建时的运行环境
+	CreatedAt    time.Time  `gorm:"not null;default:CURRENT_TIMESTAMP" json:"created_at"`
+	CreatedBy    string     `gorm:"not null;default:'system';size:100" json:"created_by"`
+	UpdatedAt    time.Time  `gorm:"not null;default:CURRENT_TIMESTAMP" json:"updated_at"`
+}
+
+// BeforeCreate 创建前钩子
+func (f *FaultInjection) BeforeCreate(tx *gorm.DB) error {
+	if f.ID == "" {
+		f.ID = uuid.New().String()
+	}
+	if f.CreatedBy == "" {
+		f.CreatedBy = "system"
+	}
+	if f.Status == "" {
+		f.Status = FaultInjectionStatusActive
+	}
+	return nil
+}
+
+// IsEffective 故障在指定时间是否生效
+func (f *FaultInjection) IsEffective(now time.Time) bool {
+	return f.Status == FaultInjectionStatusActive && now.Before(f.ExpiresAt)
+}
@@ -0,0 +1,57 @@
+/*
+ * @module service/models/column_encryption
+ * @description 敏感列加密配置模型，指定接口表中需要加密存储的列、加密方式和可查看明文的角色
+ * @architecture 分层架构 - 数据模型层
+ * @documentReference ai_docs/model.md
+ * @stateFlow 创建配置 -> 同步写入时加密 -> 数据查看时按角色解密或遮蔽 -> 轮换密钥时重新加密
+ * @rules 同一列只能有一条配置；加密列必须为文本类型且不能是主键；admin始终可查看明文
+ * @dependencies gorm.io/gorm, github.com/google/uuid
+ * @refs service/encryption, api/controllers/encryption_controller.go
+ */
+
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// 列加密方式
+const (
+	EncryptionModeAES      = "aes"      // 应用侧AES-256-GCM加密
+	EncryptionModePgcrypto = "pgcrypto" // 数据库侧pgcrypto对称加密
+)
+
+// ColumnEncryption 敏感列加密配置
+type ColumnEncryption struct {
+	ID              string           `gorm:"type:uuid;primary_key" json:"id"`
+	SchemaName      string           `gorm:"not null;size:100;uniqueIndex:idx_column_encryption" json:"schema_name"` // 库schema名称
+	TableName       string           `gorm:"not null;size:100;uniqueIndex:idx_column_encryption" json:"table_name"`  // 表/接口英文名
+	ColumnName      string           `gorm:"not null;size:100;uniqueIndex:idx_column_encryption" json:"column_name"`
+	Mode            string           `gorm:"not null;size:20;default:'aes'" json:"mode"` // aes/pgcrypto
+	AuthorizedRoles JSONBStringArray `gorm:"type:jsonb" json:"authorized_roles"`         // 数据查看时可见明文的角色，admin始终可见
+	Description     string           `json:"description"`
+	CreatedAt       time.Time        `gorm:"not null;default:CURRENT_TIMESTAMP" json:"created_at"`
+	CreatedBy       string           `gorm:"not null;default:'system';size:100" json:"created_by"`
+	UpdatedAt       time.Time        `gorm:"not null;default:CURRENT_TIMESTAMP" json:"updated_at"`
+	UpdatedBy       string           `gorm:"not null;default:'system';size:100" json:"updated_by"`
+}
+
+// BeforeCreate 创建前钩子
+func (c *ColumnEncryption) BeforeCreate(tx *gorm.DB) error {
+	if c.ID == "" {
+		c.ID = uuid.New().String()
+	}
+	if c.Mode == "" {
+		c.Mode = EncryptionModeAES
+	}
+	if c.CreatedBy == "" {
+		c.CreatedBy = "system"
+	}
+	if c.UpdatedBy == "" {
+		c.UpdatedBy = "system"
+	}
+	return nil
+}
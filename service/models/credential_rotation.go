@@ -0,0 +1,55 @@
+/*
+ * @module service/models/credential_rotation
+ * @description 数据源凭据轮换记录，保存轮换的配置项和宽限期内可回滚的旧凭据
+ * @architecture 分层架构 - 数据模型层
+ * @documentReference ai_docs/model.md
+ * @stateFlow 轮换成功 -> active(保留旧凭据) -> 宽限期结束 expired / 被下一次轮换取代 superseded / 回滚 rolled_back，后三种状态清除旧凭据
+ * @rules 旧凭据只在active状态保留，不通过接口返回；每个数据源同一时间最多一条active记录
+ * @dependencies gorm.io/gorm, github.com/google/uuid
+ * @refs service/basic_library/credential_rotation_service.go
+ */
+
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// 凭据轮换记录状态
+const (
+	CredentialRotationActive     = "active"      // 宽限期内，可回滚到旧凭据
+	CredentialRotationExpired    = "expired"     // 宽限期已结束
+	CredentialRotationSuperseded = "superseded"  // 宽限期内又进行了轮换
+	CredentialRotationRolledBack = "rolled_back" // 已回滚到旧凭据
+)
+
+// DataSourceCredentialRotation 数据源凭据轮换记录
+type DataSourceCredentialRotation struct {
+	ID              string           `json:"id" gorm:"primaryKey;type:varchar(36)"`
+	DataSourceID    string           `json:"data_source_id" gorm:"not null;type:varchar(36);index"`
+	RotatedKeys     JSONBStringArray `json:"rotated_keys" gorm:"type:jsonb"` // 轮换的连接配置项
+	PreviousSecrets JSONB            `json:"-" gorm:"type:jsonb"`            // 旧凭据，宽限期结束后清除
+	Status          string           `json:"status" gorm:"not null;size:20;index"`
+	GraceUntil      time.Time        `json:"grace_until"`      // 旧凭据保留截止时间
+	TestDurationMs  int64            `json:"test_duration_ms"` // 新凭据连通性测试耗时
+	RotatedAt       time.Time        `json:"rotated_at"`
+	RotatedBy       string           `json:"rotated_by" gorm:"not null;default:'system';size:100"`
+	RolledBackAt    *time.Time       `json:"rolled_back_at,omitempty"`
+	RolledBackBy    string           `json:"rolled_back_by,omitempty" gorm:"size:100"`
+}
+
+// TableName 指定表名
+func (DataSourceCredentialRotation) TableName() string {
+	return "datasource_credential_rotations"
+}
+
+// BeforeCreate 创建前钩子
+func (r *DataSourceCredentialRotation) BeforeCreate(tx *gorm.DB) error {
+	if r.ID == "" {
+		r.ID = uuid.New().String()
+	}
+	return nil
+}
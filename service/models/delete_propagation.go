@@ -0,0 +1,107 @@
+/*
+ * @module service/models/delete_propagation
+ * @description 源端删除同步配置，同步时识别源端已删除的记录，并在接口表中按配置硬删除、标记软删除或移入归档表
+ * @architecture 分层架构 - 数据模型层
+ * @documentReference ai_docs/model.md
+ * @stateFlow 开启删除同步 -> 同步时全量比对主键或读取源端删除标记 -> 得到已删除的主键 -> 硬删除/写入软删除时间/移入归档表 -> 删除行数随执行结果返回
+ * @rules 配置保存在接口配置的delete_config中；软删除列固定为_deleted_at，由删除同步维护，不由同步数据写入；
+ *        归档表与接口表同库，表名为接口表名加_archive后缀；不能与历史追踪同时开启
+ * @dependencies github.com/spf13/cast
+ * @refs service/basic_library/delete_propagation_service.go, service/interface_executor/delete_propagation.go
+ */
+
+package models
+
+import (
+	"strings"
+
+	"github.com/spf13/cast"
+)
+
+// DeletePropagationConfigKey 接口配置中删除同步配置的键
+const DeletePropagationConfigKey = "delete_config"
+
+// 源端删除的识别方式
+const (
+	DeleteDetectionFullCompare = "full_compare" // 每次同步拉取源端全量数据，接口表中不在源端的主键视为已删除
+	DeleteDetectionSourceFlag  = "source_flag"  // 源数据中的删除标记字段为删除取值时视为已删除
+)
+
+// 已删除记录在接口表中的处理方式
+const (
+	DeleteActionHardDelete = "hard_delete" // 直接删除
+	DeleteActionSoftDelete = "soft_delete" // 写入软删除时间，记录重新出现时清空
+	DeleteActionArchive    = "archive"     // 复制到归档表后删除
+)
+
+const (
+	// SoftDeleteColumn 软删除时间列，为空表示未删除
+	SoftDeleteColumn = "_deleted_at"
+	// DeleteArchiveSuffix 归档表名后缀
+	DeleteArchiveSuffix = "_archive"
+	// DeleteArchivedAtColumn 归档表中记录移入时间的列
+	DeleteArchivedAtColumn = "_archived_at"
+)
+
+// DefaultDeleteFlagValues 未配置删除取值时视为已删除的标记取值
+var DefaultDeleteFlagValues = []string{"true", "1", "y", "yes", "deleted"}
+
+// DeletePropagationConfig 源端删除同步配置
+type DeletePropagationConfig struct {
+	Enabled    bool     `json:"enabled"`
+	Detection  string   `json:"detection"`   // full_compare 或 source_flag
+	FlagField  string   `json:"flag_field"`  // source_flag时源数据中的删除标记字段（字段映射前的名称）
+	FlagValues []string `json:"flag_values"` // 表示已删除的取值，不区分大小写，为空时使用DefaultDeleteFlagValues
+	Action     string   `json:"action"`      // hard_delete、soft_delete 或 archive
+}
+
+// ParseDeletePropagationConfig 从接口配置中读取启用的删除同步配置，未配置、未启用或配置不完整时返回nil
+func ParseDeletePropagationConfig(interfaceConfig map[string]interface{}) *DeletePropagationConfig {
+	raw, ok := interfaceConfig[DeletePropagationConfigKey].(map[string]interface{})
+	if !ok || !cast.ToBool(raw["enabled"]) {
+		return nil
+	}
+	config := &DeletePropagationConfig{
+		Enabled:    true,
+		Detection:  cast.ToString(raw["detection"]),
+		FlagField:  cast.ToString(raw["flag_field"]),
+		FlagValues: cast.ToStringSlice(raw["flag_values"]),
+		Action:     cast.ToString(raw["action"]),
+	}
+	if config.Action == "" {
+		config.Action = DeleteActionHardDelete
+	}
+	switch config.Detection {
+	case DeleteDetectionFullCompare:
+	case DeleteDetectionSourceFlag:
+		if config.FlagField == "" {
+			return nil
+		}
+	default:
+		return nil
+	}
+	return config
+}
+
+// IsDeletedFlag 判断删除标记字段的取值是否表示已删除
+func (c *DeletePropagationConfig) IsDeletedFlag(value interface{}) bool {
+	if value == nil {
+		return false
+	}
+	values := c.FlagValues
+	if len(values) == 0 {
+		values = DefaultDeleteFlagValues
+	}
+	text := strings.TrimSpace(cast.ToString(value))
+	for _, candidate := range values {
+		if strings.EqualFold(text, candidate) {
+			return true
+		}
+	}
+	return false
+}
+
+// SoftDeleteField 软删除列的字段配置，排在orderBase之后
+func SoftDeleteField(orderBase int) TableField {
+	return TableField{NameZh: "删除时间", NameEn: SoftDeleteColumn, DataType: "timestamp", IsNullable: true, Description: "源端删除该记录后同步到的时间，为空表示未删除", OrderNum: orderBase + 1, IsIndexed: true}
+}
@@ -0,0 +1,110 @@
+/*
+ * @module service/models/duplicate_report
+ * @description 接口表重复数据检测报告模型，记录一次按需采样分析的键字段、匹配方式、重复簇及建议的去重配置
+ * @architecture 分层架构 - 数据模型层
+ * @documentReference ai_docs/model.md
+ * @stateFlow 发起分析(running) -> 采样并按键字段分组 -> completed(保存重复簇和去重建议) / failed(保存错误信息)
+ * @rules 统计结果只针对采样数据；重复簇按行数从多到少保存，超过上限的部分只计数不保存明细
+ * @dependencies gorm.io/gorm, github.com/google/uuid
+ * @refs service/basic_library/duplicate_report_service.go, api/controllers/duplicate_report_controller.go
+ */
+
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// 重复数据检测报告状态
+const (
+	DuplicateReportStatusRunning   = "running"
+	DuplicateReportStatusCompleted = "completed"
+	DuplicateReportStatusFailed    = "failed"
+)
+
+// 键字段匹配方式
+const (
+	DuplicateMatchExact = "exact" // 键字段取值完全相同
+	DuplicateMatchFuzzy = "fuzzy" // 键字段取值按归一化选项处理后相同
+)
+
+// DuplicateMatchOptions 键字段匹配选项，归一化选项只在fuzzy模式下生效
+type DuplicateMatchOptions struct {
+	Mode              string `json:"mode" example:"fuzzy"` // exact/fuzzy
+	IgnoreCase        bool   `json:"ignore_case"`          // 忽略大小写
+	IgnoreWhitespace  bool   `json:"ignore_whitespace"`    // 忽略所有空白字符
+	IgnorePunctuation bool   `json:"ignore_punctuation"`   // 忽略标点和符号
+	NormalizeWidth    bool   `json:"normalize_width"`      // 全角字符按半角比较
+	NormalizeNumber   bool   `json:"normalize_number"`     // 数值按大小比较，如 1.0 与 1
+}
+
+// Scan 实现 Scanner 接口
+func (o *DuplicateMatchOptions) Scan(value interface{}) error {
+	return scanJSONValue(value, o)
+}
+
+// Value 实现 Valuer 接口
+func (o DuplicateMatchOptions) Value() (driver.Value, error) {
+	return json.Marshal(o)
+}
+
+// DuplicateCluster 一组键字段匹配的重复行
+type DuplicateCluster struct {
+	Key        map[string]interface{}   `json:"key"`                // 归一化后的键字段取值
+	RowCount   int                      `json:"row_count"`          // 采样中属于该簇的行数
+	Variants   []string                 `json:"variants,omitempty"` // fuzzy模式下簇内出现的不同原始键取值
+	SampleRows []map[string]interface{} `json:"sample_rows"`
+}
+
+// DuplicateClusters 重复簇清单
+type DuplicateClusters []DuplicateCluster
+
+// Scan 实现 Scanner 接口
+func (c *DuplicateClusters) Scan(value interface{}) error {
+	return scanJSONValue(value, c)
+}
+
+// Value 实现 Valuer 接口
+func (c DuplicateClusters) Value() (driver.Value, error) {
+	return json.Marshal(c)
+}
+
+// DuplicateReport 接口表重复数据检测报告
+type DuplicateReport struct {
+	ID              string                `json:"id" gorm:"primaryKey;type:varchar(36)"`
+	InterfaceID     string                `json:"interface_id" gorm:"not null;type:varchar(36);index:idx_duplicate_reports_interface_time,priority:1"`
+	Status          string                `json:"status" gorm:"not null;size:20"` // running/completed/failed
+	KeyFields       JSONBStringArray      `json:"key_fields" gorm:"type:jsonb"`
+	MatchOptions    DuplicateMatchOptions `json:"match_options" gorm:"type:jsonb"`
+	SampleSize      int                   `json:"sample_size" gorm:"not null;default:0"`  // 请求的采样行数
+	RowsSampled     int                   `json:"rows_sampled" gorm:"not null;default:0"` // 实际采样行数
+	RowsSkipped     int                   `json:"rows_skipped" gorm:"not null;default:0"` // 键字段全部为空而未参与比较的行数
+	ClusterCount    int                   `json:"cluster_count" gorm:"not null;default:0"`
+	DuplicateRows   int                   `json:"duplicate_rows" gorm:"not null;default:0"` // 每簇保留一行时可去除的行数
+	DuplicateRate   float64               `json:"duplicate_rate"`                           // 可去除行数占采样行数的百分比
+	Clusters        DuplicateClusters     `json:"clusters" gorm:"type:jsonb"`
+	Truncated       bool                  `json:"truncated"` // 重复簇超过上限，只保存行数最多的部分
+	SuggestedConfig JSONB                 `json:"suggested_config,omitempty" gorm:"type:jsonb"`
+	ErrorMessage    string                `json:"error_message,omitempty" gorm:"type:text"`
+	CreatedBy       string                `json:"created_by" gorm:"not null;default:'system';size:100"`
+	CreatedAt       time.Time             `json:"created_at" gorm:"not null;index:idx_duplicate_reports_interface_time,priority:2"`
+	CompletedAt     *time.Time            `json:"completed_at,omitempty"`
+}
+
+// TableName 指定表名
+func (DuplicateReport) TableName() string {
+	return "interface_duplicate_reports"
+}
+
+// BeforeCreate GORM钩子，创建前生成UUID
+func (r *DuplicateReport) BeforeCreate(tx *gorm.DB) error {
+	if r.ID == "" {
+		r.ID = uuid.New().String()
+	}
+	return nil
+}
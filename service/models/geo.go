@@ -0,0 +1,333 @@
+/*
+ * @module service/models/geo
+ * @description 空间字段类型，PostGIS geometry/geography列的类型定义、GeoJSON/WKT取值转换以及按矩形范围和半径筛选的条件
+ * @architecture 分层架构 - 数据模型层
+ * @documentReference ai_docs/model.md
+ * @stateFlow 表字段配置geometry/geography -> 建表时创建PostGIS列 -> 同步时GeoJSON/WKT转换为EWKT写入 -> 数据查看和数据共享按bbox/near筛选
+ * @rules 空间列统一使用WGS84坐标（SRID 4326）；GeoJSON按RFC 7946取第一、二个坐标为经度、纬度；WKT未带SRID时补为4326；
+ *        半径以米为单位，geometry列转为geography计算距离
+ * @dependencies encoding/json
+ * @refs service/database/geo.go, service/interface_executor/field_mapping.go, service/sharing/geo_query.go
+ */
+
+package models
+
+import (
+	"datahub-service/service/utils"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// 空间字段类型
+const (
+	GeoTypeGeometry  = "geometry"  // 平面坐标，按度计算，适合范围筛选
+	GeoTypeGeography = "geography" // 球面坐标，距离和面积按米计算
+)
+
+// GeoSRID 空间列使用的坐标系，WGS84经纬度
+const GeoSRID = 4326
+
+// IsGeoDataType 是否为空间字段类型
+func IsGeoDataType(dataType string) bool {
+	switch strings.ToLower(strings.TrimSpace(dataType)) {
+	case GeoTypeGeometry, GeoTypeGeography:
+		return true
+	}
+	return false
+}
+
+// wktPattern WKT几何类型关键字，可带Z/M/ZM维度
+var wktPattern = regexp.MustCompile(`(?i)^(POINT|LINESTRING|POLYGON|MULTIPOINT|MULTILINESTRING|MULTIPOLYGON|GEOMETRYCOLLECTION)(\s+(Z|M|ZM))?\s*(\(|EMPTY)`)
+
+// hexWKBPattern 十六进制WKB/EWKB，PostGIS导出的默认格式
+var hexWKBPattern = regexp.MustCompile(`^(?i)(00|01)[0-9a-f]+$`)
+
+// CoerceGeoValue 把GeoJSON（对象或JSON字符串，可为Feature）、WKT/EWKT、十六进制WKB或[经度,纬度]转换为可写入空间列的值
+func CoerceGeoValue(value interface{}) (string, error) {
+	switch v := value.(type) {
+	case string:
+		text := strings.TrimSpace(v)
+		switch {
+		case strings.HasPrefix(text, "{"):
+			var geoJSON map[string]interface{}
+			if err := json.Unmarshal([]byte(text), &geoJSON); err != nil {
+				return "", fmt.Errorf("GeoJSON格式错误: %w", err)
+			}
+			return CoerceGeoValue(geoJSON)
+		case strings.HasPrefix(strings.ToUpper(text), "SRID="):
+			return text, nil
+		case wktPattern.MatchString(text):
+			return fmt.Sprintf("SRID=%d;%s", GeoSRID, text), nil
+		case hexWKBPattern.MatchString(text) && len(text)%2 == 0:
+			return text, nil
+		}
+		return "", fmt.Errorf("无法识别的空间数据: %s", truncateGeoText(text))
+	case []byte:
+		return CoerceGeoValue(string(v))
+	case map[string]interface{}:
+		wkt, err := geoJSONToWKT(v)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("SRID=%d;%s", GeoSRID, wkt), nil
+	case []interface{}:
+		position, err := geoJSONPosition(v)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("SRID=%d;POINT(%s)", GeoSRID, position), nil
+	case nil:
+		return "", fmt.Errorf("空间数据为空")
+	}
+	return "", fmt.Errorf("不支持的空间数据类型: %T", value)
+}
+
+// geoJSONToWKT GeoJSON几何对象转换为WKT，Feature取其geometry
+func geoJSONToWKT(geoJSON map[string]interface{}) (string, error) {
+	geoType, _ := geoJSON["type"].(string)
+	switch geoType {
+	case "Feature":
+		geometry, ok := geoJSON["geometry"].(map[string]interface{})
+		if !ok {
+			return "", fmt.Errorf("GeoJSON Feature缺少geometry")
+		}
+		return geoJSONToWKT(geometry)
+	case "GeometryCollection":
+		geometries, _ := geoJSON["geometries"].([]interface{})
+		if len(geometries) == 0 {
+			return "GEOMETRYCOLLECTION EMPTY", nil
+		}
+		parts := make([]string, 0, len(geometries))
+		for _, item := range geometries {
+			geometry, ok := item.(map[string]interface{})
+			if !ok {
+				return "", fmt.Errorf("GeoJSON GeometryCollection的元素不是几何对象")
+			}
+			wkt, err := geoJSONToWKT(geometry)
+			if err != nil {
+				return "", err
+			}
+			parts = append(parts, wkt)
+		}
+		return "GEOMETRYCOLLECTION(" + strings.Join(parts, ",") + ")", nil
+	}
+
+	// 各几何类型坐标的嵌套层数
+	depth := map[string]int{
+		"Point": 0, "MultiPoint": 1, "LineString": 1,
+		"MultiLineString": 2, "Polygon": 2, "MultiPolygon": 3,
+	}
+	level, ok := depth[geoType]
+	if !ok {
+		return "", fmt.Errorf("不支持的GeoJSON类型: %q", geoType)
+	}
+	coordinates, ok := geoJSON["coordinates"].([]interface{})
+	if !ok {
+		return "", fmt.Errorf("GeoJSON %s缺少coordinates", geoType)
+	}
+	if len(coordinates) == 0 {
+		return strings.ToUpper(geoType) + " EMPTY", nil
+	}
+	text, err := geoJSONCoordinates(coordinates, level)
+	if err != nil {
+		return "", fmt.Errorf("GeoJSON %s坐标错误: %w", geoType, err)
+	}
+	return strings.ToUpper(geoType) + "(" + text + ")", nil
+}
+
+// geoJSONCoordinates 把嵌套坐标数组转换为WKT坐标文本，level为坐标外的数组层数
+func geoJSONCoordinates(coordinates []interface{}, level int) (string, error) {
+	if level == 0 {
+		return geoJSONPosition(coordinates)
+	}
+	parts := make([]string, 0, len(coordinates))
+	for _, item := range coordinates {
+		nested, ok := item.([]interface{})
+		if !ok {
+			return "", fmt.Errorf("坐标应为数组")
+		}
+		text, err := geoJSONCoordinates(nested, level-1)
+		if err != nil {
+			return "", err
+		}
+		if level > 1 {
+			text = "(" + text + ")"
+		}
+		parts = append(parts, text)
+	}
+	return strings.Join(parts, ","), nil
+}
+
+// geoJSONPosition 单个坐标[经度,纬度(,高程)]转换为WKT坐标
+func geoJSONPosition(position []interface{}) (string, error) {
+	if len(position) < 2 || len(position) > 3 {
+		return "", fmt.Errorf("坐标应为[经度,纬度]或[经度,纬度,高程]")
+	}
+	parts := make([]string, len(position))
+	for i, item := range position {
+		var number float64
+		switch n := item.(type) {
+		case float64:
+			number = n
+		case int:
+			number = float64(n)
+		case int64:
+			number = float64(n)
+		case json.Number:
+			parsed, err := n.Float64()
+			if err != nil {
+				return "", fmt.Errorf("坐标值%v不是数字", item)
+			}
+			number = parsed
+		default:
+			return "", fmt.Errorf("坐标值%v不是数字", item)
+		}
+		parts[i] = strconv.FormatFloat(number, 'f', -1, 64)
+	}
+	return strings.Join(parts, " "), nil
+}
+
+// truncateGeoText 错误信息中截断过长的空间数据
+func truncateGeoText(text string) string {
+	if len(text) > 64 {
+		return text[:64] + "..."
+	}
+	return text
+}
+
+// GeoBBox 矩形范围，经纬度
+type GeoBBox struct {
+	MinLon float64 `json:"min_lon"`
+	MinLat float64 `json:"min_lat"`
+	MaxLon float64 `json:"max_lon"`
+	MaxLat float64 `json:"max_lat"`
+}
+
+// GeoNear 圆形范围，中心点经纬度和半径（米）
+type GeoNear struct {
+	Lon    float64 `json:"lon"`
+	Lat    float64 `json:"lat"`
+	Radius float64 `json:"radius"`
+}
+
+// GeoFilter 按空间列筛选的条件，零值表示不筛选
+type GeoFilter struct {
+	Column    string   // 空间列名
+	Geography bool     // 空间列是否为geography类型
+	BBox      *GeoBBox // 与矩形范围相交
+	Near      *GeoNear // 与中心点的距离不超过半径
+}
+
+// ParseGeoFilter 解析bbox（最小经度,最小纬度,最大经度,最大纬度）和near（经度,纬度,半径米）参数，column为空时由ResolveColumn确定
+func ParseGeoFilter(column, bbox, near string) (GeoFilter, error) {
+	filter := GeoFilter{Column: strings.TrimSpace(column)}
+	if bbox = strings.TrimSpace(bbox); bbox != "" {
+		values, err := parseGeoNumbers("bbox", bbox, 4)
+		if err != nil {
+			return filter, err
+		}
+		filter.BBox = &GeoBBox{MinLon: values[0], MinLat: values[1], MaxLon: values[2], MaxLat: values[3]}
+		if err := validateLonLat("bbox", filter.BBox.MinLon, filter.BBox.MinLat); err != nil {
+			return filter, err
+		}
+		if err := validateLonLat("bbox", filter.BBox.MaxLon, filter.BBox.MaxLat); err != nil {
+			return filter, err
+		}
+		if filter.BBox.MinLon > filter.BBox.MaxLon || filter.BBox.MinLat > filter.BBox.MaxLat {
+			return filter, fmt.Errorf("bbox的最小经纬度不能大于最大经纬度")
+		}
+	}
+	if near = strings.TrimSpace(near); near != "" {
+		values, err := parseGeoNumbers("near", near, 3)
+		if err != nil {
+			return filter, err
+		}
+		filter.Near = &GeoNear{Lon: values[0], Lat: values[1], Radius: values[2]}
+		if err := validateLonLat("near", filter.Near.Lon, filter.Near.Lat); err != nil {
+			return filter, err
+		}
+		if filter.Near.Radius <= 0 {
+			return filter, fmt.Errorf("near的半径须大于0")
+		}
+	}
+	return filter, nil
+}
+
+// parseGeoNumbers 解析逗号分隔的数字
+func parseGeoNumbers(name, text string, count int) ([]float64, error) {
+	parts := strings.Split(text, ",")
+	if len(parts) != count {
+		return nil, fmt.Errorf("%s应为%d个逗号分隔的数字", name, count)
+	}
+	values := make([]float64, count)
+	for i, part := range parts {
+		value, err := strconv.ParseFloat(strings.TrimSpace(part), 64)
+		if err != nil {
+			return nil, fmt.Errorf("%s的第%d个值不是数字", name, i+1)
+		}
+		values[i] = value
+	}
+	return values, nil
+}
+
+// validateLonLat 校验经纬度范围
+func validateLonLat(name string, lon, lat float64) error {
+	if lon < -180 || lon > 180 || lat < -90 || lat > 90 {
+		return fmt.Errorf("%s的经度须在-180到180之间，纬度须在-90到90之间", name)
+	}
+	return nil
+}
+
+// IsEmpty 是否没有任何筛选条件
+func (f GeoFilter) IsEmpty() bool {
+	return f.BBox == nil && f.Near == nil
+}
+
+// ResolveColumn 按表的空间列（列名 -> geometry/geography）确定筛选列，未指定列名时表须只有一个空间列
+func (f *GeoFilter) ResolveColumn(geoColumns map[string]string) error {
+	if f.Column == "" {
+		switch len(geoColumns) {
+		case 0:
+			return fmt.Errorf("表没有空间字段，不能按空间范围筛选")
+		case 1:
+			for column := range geoColumns {
+				f.Column = column
+			}
+		default:
+			return fmt.Errorf("表有多个空间字段，须通过geo_field指定筛选字段")
+		}
+	}
+	geoType, ok := geoColumns[f.Column]
+	if !ok {
+		return fmt.Errorf("字段%s不是空间字段", f.Column)
+	}
+	f.Geography = geoType == GeoTypeGeography
+	return nil
+}
+
+// SQLFilter 生成参数化的筛选条件，没有条件时返回空串
+func (f GeoFilter) SQLFilter() (string, []interface{}) {
+	var conditions []string
+	var args []interface{}
+	column := utils.QuoteIdent(f.Column)
+	if f.BBox != nil {
+		envelope := fmt.Sprintf("ST_MakeEnvelope(?, ?, ?, ?, %d)", GeoSRID)
+		if f.Geography {
+			envelope += "::geography"
+		}
+		conditions = append(conditions, fmt.Sprintf("ST_Intersects(%s, %s)", column, envelope))
+		args = append(args, f.BBox.MinLon, f.BBox.MinLat, f.BBox.MaxLon, f.BBox.MaxLat)
+	}
+	if f.Near != nil {
+		target := column
+		if !f.Geography {
+			target += "::geography"
+		}
+		conditions = append(conditions, fmt.Sprintf("ST_DWithin(%s, ST_SetSRID(ST_MakePoint(?, ?), %d)::geography, ?)", target, GeoSRID))
+		args = append(args, f.Near.Lon, f.Near.Lat, f.Near.Radius)
+	}
+	return strings.Join(conditions, " AND "), args
+}
@@ -0,0 +1,100 @@
+/*
+ * @module service/models/geo_test
+ * @description 空间字段测试，覆盖GeoJSON/WKT/WKB取值转换、bbox/near参数解析、空间列确定和筛选条件生成
+ * @architecture 测试层
+ * @documentReference ai_docs/model.md
+ * @rules 纯函数测试，不依赖数据库
+ * @dependencies testing, testify
+ * @refs geo.go
+ */
+
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCoerceGeoValue(t *testing.T) {
+	tests := []struct {
+		name  string
+		value interface{}
+		want  string
+	}{
+		{"GeoJSON点", map[string]interface{}{"type": "Point", "coordinates": []interface{}{120.15, 30.28}}, "SRID=4326;POINT(120.15 30.28)"},
+		{"GeoJSON字符串", `{"type":"LineString","coordinates":[[1,2],[3,4]]}`, "SRID=4326;LINESTRING(1 2,3 4)"},
+		{"GeoJSON面", `{"type":"Polygon","coordinates":[[[0,0],[1,0],[1,1],[0,0]]]}`, "SRID=4326;POLYGON((0 0,1 0,1 1,0 0))"},
+		{"GeoJSON多面", `{"type":"MultiPolygon","coordinates":[[[[0,0],[1,0],[1,1],[0,0]]]]}`, "SRID=4326;MULTIPOLYGON(((0 0,1 0,1 1,0 0)))"},
+		{"GeoJSON Feature", `{"type":"Feature","properties":{},"geometry":{"type":"MultiPoint","coordinates":[[1,2],[3,4]]}}`, "SRID=4326;MULTIPOINT(1 2,3 4)"},
+		{"GeoJSON集合", `{"type":"GeometryCollection","geometries":[{"type":"Point","coordinates":[1,2]}]}`, "SRID=4326;GEOMETRYCOLLECTION(POINT(1 2))"},
+		{"WKT", "point(120.15 30.28)", "SRID=4326;point(120.15 30.28)"},
+		{"EWKT", "SRID=3857;POINT(1 2)", "SRID=3857;POINT(1 2)"},
+		{"十六进制WKB", "0101000020E6100000000000000000F03F0000000000000040", "0101000020E6100000000000000000F03F0000000000000040"},
+		{"经纬度数组", []interface{}{120.15, 30.28}, "SRID=4326;POINT(120.15 30.28)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := CoerceGeoValue(tt.value)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	for _, value := range []interface{}{
+		"杭州市西湖区",
+		`{"type":"Circle","coordinates":[1,2]}`,
+		map[string]interface{}{"type": "Point", "coordinates": []interface{}{"a", 2}},
+		[]interface{}{1.0},
+		42,
+	} {
+		_, err := CoerceGeoValue(value)
+		assert.Error(t, err, "%v", value)
+	}
+}
+
+func TestParseGeoFilter(t *testing.T) {
+	filter, err := ParseGeoFilter("", "120.1, 30.2, 120.3, 30.4", "120.2,30.3,500")
+	require.NoError(t, err)
+	assert.Equal(t, &GeoBBox{MinLon: 120.1, MinLat: 30.2, MaxLon: 120.3, MaxLat: 30.4}, filter.BBox)
+	assert.Equal(t, &GeoNear{Lon: 120.2, Lat: 30.3, Radius: 500}, filter.Near)
+
+	filter, err = ParseGeoFilter("", "", "")
+	require.NoError(t, err)
+	assert.True(t, filter.IsEmpty())
+
+	for _, tt := range []struct{ bbox, near string }{
+		{"1,2,3", ""},
+		{"1,2,x,4", ""},
+		{"3,2,1,4", ""},
+		{"-190,0,0,10", ""},
+		{"", "120,30,0"},
+		{"", "120,95,10"},
+	} {
+		_, err := ParseGeoFilter("", tt.bbox, tt.near)
+		assert.Error(t, err, "bbox=%s near=%s", tt.bbox, tt.near)
+	}
+}
+
+func TestGeoFilterSQL(t *testing.T) {
+	filter, err := ParseGeoFilter("", "120.1,30.2,120.3,30.4", "120.2,30.3,500")
+	require.NoError(t, err)
+
+	assert.EqualError(t, filter.ResolveColumn(map[string]string{}), "表没有空间字段，不能按空间范围筛选")
+	assert.EqualError(t, filter.ResolveColumn(map[string]string{"a": GeoTypeGeometry, "b": GeoTypeGeometry}), "表有多个空间字段，须通过geo_field指定筛选字段")
+
+	require.NoError(t, filter.ResolveColumn(map[string]string{"location": GeoTypeGeometry}))
+	sql, args := filter.SQLFilter()
+	assert.Equal(t, `ST_Intersects("location", ST_MakeEnvelope(?, ?, ?, ?, 4326)) AND `+
+		`ST_DWithin("location"::geography, ST_SetSRID(ST_MakePoint(?, ?), 4326)::geography, ?)`, sql)
+	assert.Equal(t, []interface{}{120.1, 30.2, 120.3, 30.4, 120.2, 30.3, 500.0}, args)
+
+	filter = GeoFilter{Column: "area", BBox: filter.BBox}
+	require.NoError(t, filter.ResolveColumn(map[string]string{"area": GeoTypeGeography, "location": GeoTypeGeometry}))
+	sql, _ = filter.SQLFilter()
+	assert.Equal(t, `ST_Intersects("area", ST_MakeEnvelope(?, ?, ?, ?, 4326)::geography)`, sql)
+
+	filter.Column = "name"
+	assert.EqualError(t, filter.ResolveColumn(map[string]string{"area": GeoTypeGeography}), "字段name不是空间字段")
+}
@@ -22,6 +22,7 @@ import (
 // QualityRuleTemplate 数据质量规则模板模型（不绑定具体表字段）
 type QualityRuleTemplate struct {
 	ID            string    `gorm:"type:uuid;primary_key" json:"id"`
+	TenantID      string    `gorm:"not null;size:36;default:'default';index" json:"tenant_id"` // 所属租户
 	Name          string    `gorm:"not null" json:"name"`
 	Type          string    `gorm:"not null" json:"type"`     // completeness/standardization/consistency/accuracy/uniqueness/timeliness
 	Category      string    `gorm:"not null" json:"category"` // basic_quality/data_cleansing/data_validation
@@ -37,6 +38,7 @@ type QualityRuleTemplate struct {
 	CreatedBy     string    `gorm:"not null;default:'system';size:100" json:"created_by"`
 	UpdatedAt     time.Time `gorm:"not null;default:CURRENT_TIMESTAMP" json:"updated_at"`
 	UpdatedBy     string    `gorm:"not null;default:'system';size:100" json:"updated_by"`
+	RowVersion    int64     `gorm:"not null;default:1" json:"row_version"` // 乐观锁版本
 }
 
 // QualityRuleConfig 数据质量规则配置（运行时应用）
@@ -109,6 +111,7 @@ func (m *Metadata) BeforeUpdate(tx *gorm.DB) error {
 // DataMaskingTemplate 数据脱敏规则模板模型
 type DataMaskingTemplate struct {
 	ID              string         `gorm:"type:uuid;primary_key" json:"id"`
+	TenantID        string         `gorm:"not null;size:36;default:'default';index" json:"tenant_id"` // 所属租户
 	Name            string         `gorm:"not null" json:"name"`
 	MaskingType     string         `gorm:"not null" json:"masking_type"` // mask/replace/encrypt/pseudonymize
 	Category        string         `gorm:"not null" json:"category"`     // personal_info/financial/medical/custom
@@ -126,6 +129,7 @@ type DataMaskingTemplate struct {
 	CreatedBy       string         `gorm:"not null;default:'system';size:100" json:"created_by"`
 	UpdatedAt       time.Time      `gorm:"not null;default:CURRENT_TIMESTAMP" json:"updated_at"`
 	UpdatedBy       string         `gorm:"not null;default:'system';size:100" json:"updated_by"`
+	RowVersion      int64          `gorm:"not null;default:1" json:"row_version"` // 乐观锁版本
 }
 
 // DataMaskingConfig 数据脱敏配置（运行时应用）
@@ -237,8 +241,28 @@ type BackupRecord struct {
 	FilePath       *string       `json:"file_path"`
 	ErrorMessage   *string       `json:"error_message"`
 	CreatedBy      string        `gorm:"not null;default:'system';size:100" json:"created_by"`
+
+	// 完整性校验结果，由备份校验任务还原到临时schema后比对行数和校验和写入
+	VerificationStatus  string     `gorm:"size:20;index" json:"verification_status"` // 空表示未校验，verified/corrupt/failed
+	VerifiedAt          *time.Time `json:"verified_at"`
+	VerificationDetails JSONB      `gorm:"type:jsonb" json:"verification_details,omitempty"`
+	VerificationError   *string    `json:"verification_error"`
 }
 
+// 备份记录状态
+const (
+	BackupStatusInProgress = "in_progress"
+	BackupStatusSuccess    = "success"
+	BackupStatusFailure    = "failure"
+)
+
+// 备份完整性校验状态
+const (
+	BackupVerificationVerified = "verified" // 还原后行数和校验和与清单一致
+	BackupVerificationCorrupt  = "corrupt"  // 备份文件损坏或还原结果与清单不一致
+	BackupVerificationFailed   = "failed"   // 校验过程出错（如下载失败），下次调度重试
+)
+
 // BeforeCreate 创建前钩子
 func (b *BackupRecord) BeforeCreate(tx *gorm.DB) error {
 	if b.ID == "" {
@@ -0,0 +1,49 @@
+/*
+ * @module service/models/idempotency
+ * @description 幂等请求记录模型，保存Idempotency-Key对应的请求指纹和响应，用于客户端重试时重放响应
+ * @architecture 分层架构 - 数据模型层
+ * @documentReference ai_docs/model.md
+ * @stateFlow processing -> completed（可重放） / 失败时删除记录允许重试 -> 过期后清理
+ * @rules 同一调用方、同一接口、同一Key唯一；指纹不一致视为Key被误用
+ * @dependencies gorm.io/gorm, github.com/google/uuid
+ * @refs service/idempotency, api/middleware/idempotency.go
+ */
+
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// 幂等记录状态
+const (
+	IdempotencyStatusProcessing = "processing" // 首次请求处理中
+	IdempotencyStatusCompleted  = "completed"  // 已完成，可重放响应
+)
+
+// IdempotencyRecord 幂等请求记录
+type IdempotencyRecord struct {
+	ID             string    `gorm:"type:uuid;primary_key" json:"id"`
+	IdempotencyKey string    `gorm:"not null;size:255;uniqueIndex:idx_idempotency_principal_scope_key" json:"idempotency_key"`
+	Principal      string    `gorm:"not null;size:255;uniqueIndex:idx_idempotency_principal_scope_key" json:"principal"` // 调用方：用户名或ApiKey ID
+	Scope          string    `gorm:"not null;size:500;uniqueIndex:idx_idempotency_principal_scope_key" json:"scope"`     // 请求方法+路径
+	Fingerprint    string    `gorm:"not null;size:64" json:"fingerprint"`                                                // 请求体SHA-256
+	Status         string    `gorm:"not null;size:20" json:"status"`
+	ResponseStatus int       `json:"response_status"`
+	ContentType    string    `gorm:"size:100" json:"content_type"`
+	ResponseBody   []byte    `json:"-"`
+	ExpiresAt      time.Time `gorm:"not null;index" json:"expires_at"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+// BeforeCreate 创建前钩子
+func (r *IdempotencyRecord) BeforeCreate(tx *gorm.DB) error {
+	if r.ID == "" {
+		r.ID = uuid.New().String()
+	}
+	return nil
+}
@@ -0,0 +1,157 @@
+/*
+ * @module service/models/interface_contract
+ * @description 接口数据契约模型，数据提供方为接口声明预期的列、类型、可空性、枚举取值和交付周期，每次同步按契约校验并记录合规结果
+ * @architecture 分层架构 - 数据模型层
+ * @documentReference ai_docs/model.md
+ * @stateFlow 配置契约(version递增) -> 同步写入前逐行校验 -> compliant(无违规) / violated(有违规，strictness=warn时照常写入) / rejected(strictness=fail时拒绝写入)
+ * @rules 每个接口一份契约；交付周期违规只告警不拒绝写入；合规记录按执行记录保存，用于合规报告
+ * @dependencies gorm.io/gorm
+ * @refs service/basic_library/contract_service.go, service/interface_executor/contract.go, api/controllers/contract_controller.go
+ */
+
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// 数据契约违规时的处理方式
+const (
+	ContractStrictnessWarn = "warn" // 记录违规，照常写入
+	ContractStrictnessFail = "fail" // 拒绝写入，同步失败
+)
+
+// 数据契约列类型
+const (
+	ContractTypeString    = "string"
+	ContractTypeInteger   = "integer"
+	ContractTypeNumber    = "number"
+	ContractTypeBoolean   = "boolean"
+	ContractTypeTimestamp = "timestamp"
+	ContractTypeDate      = "date"
+	ContractTypeJSON      = "json"
+)
+
+// 数据契约违规类型
+const (
+	ContractViolationMissingColumn = "missing_column" // 契约中的列不在接口字段配置中
+	ContractViolationSchemaType    = "schema_type"    // 接口字段配置的类型与契约不一致
+	ContractViolationNull          = "null"           // 不可为空的列为空或缺失
+	ContractViolationType          = "type"           // 值不符合契约类型
+	ContractViolationEnum          = "enum"           // 值不在允许的枚举取值中
+	ContractViolationCadence       = "cadence"        // 两次交付间隔超过约定的交付周期
+)
+
+// 数据契约合规状态
+const (
+	ContractStatusCompliant = "compliant"
+	ContractStatusViolated  = "violated"
+	ContractStatusRejected  = "rejected"
+)
+
+// ContractColumn 契约中的一列
+type ContractColumn struct {
+	Name       string   `json:"name" example:"status"`
+	Type       string   `json:"type" example:"string"` // string/integer/number/boolean/timestamp/date/json，为空表示不校验类型
+	Nullable   bool     `json:"nullable"`
+	EnumValues []string `json:"enum_values,omitempty" example:"active,inactive"`
+}
+
+// ContractColumns 契约列清单
+type ContractColumns []ContractColumn
+
+// Scan 实现 Scanner 接口
+func (c *ContractColumns) Scan(value interface{}) error {
+	return scanJSONValue(value, c)
+}
+
+// Value 实现 Valuer 接口
+func (c ContractColumns) Value() (driver.Value, error) {
+	return json.Marshal(c)
+}
+
+// ContractViolation 一类契约违规，同一列同一违规类型合并计数
+type ContractViolation struct {
+	Column  string `json:"column,omitempty"`
+	Kind    string `json:"kind"`
+	Count   int    `json:"count"`
+	Sample  string `json:"sample,omitempty"` // 第一个违规值
+	Message string `json:"message"`
+}
+
+// ContractViolations 契约违规清单
+type ContractViolations []ContractViolation
+
+// Scan 实现 Scanner 接口
+func (v *ContractViolations) Scan(value interface{}) error {
+	return scanJSONValue(value, v)
+}
+
+// Value 实现 Valuer 接口
+func (v ContractViolations) Value() (driver.Value, error) {
+	return json.Marshal(v)
+}
+
+// InterfaceContract 接口数据契约
+type InterfaceContract struct {
+	InterfaceID    string          `json:"interface_id" gorm:"primaryKey;type:varchar(36)"`
+	Columns        ContractColumns `json:"columns" gorm:"type:jsonb"`
+	CadenceSeconds int             `json:"cadence_seconds" gorm:"not null;default:0"`         // 约定的交付周期，0表示不约定
+	Strictness     string          `json:"strictness" gorm:"not null;size:20;default:'warn'"` // warn/fail
+	Enabled        bool            `json:"enabled" gorm:"not null"`                           // 不设列默认值，以便保存enabled=false
+	Version        int             `json:"version" gorm:"not null;default:1"`                 // 每次修改递增
+	Description    string          `json:"description" gorm:"size:500"`
+	CreatedAt      time.Time       `json:"created_at" gorm:"not null;default:CURRENT_TIMESTAMP"`
+	CreatedBy      string          `json:"created_by" gorm:"not null;default:'system';size:100"`
+	UpdatedAt      time.Time       `json:"updated_at" gorm:"not null;default:CURRENT_TIMESTAMP"`
+	UpdatedBy      string          `json:"updated_by" gorm:"not null;default:'system';size:100"`
+}
+
+// TableName 指定表名
+func (InterfaceContract) TableName() string {
+	return "interface_contracts"
+}
+
+// RejectsViolations 违规时是否拒绝写入
+func (c *InterfaceContract) RejectsViolations() bool {
+	return c.Strictness == ContractStrictnessFail
+}
+
+// InterfaceContractCheck 一次同步的数据契约校验结果
+type InterfaceContractCheck struct {
+	ID              string             `json:"id" gorm:"primaryKey;type:varchar(36)"`
+	InterfaceID     string             `json:"interface_id" gorm:"not null;type:varchar(36);index:idx_contract_checks_interface_time,priority:1"`
+	TaskID          string             `json:"task_id" gorm:"type:varchar(36)"`
+	ExecutionID     string             `json:"execution_id" gorm:"type:varchar(36);index"`
+	ContractVersion int                `json:"contract_version" gorm:"not null;default:1"`
+	Strictness      string             `json:"strictness" gorm:"not null;size:20"`
+	Status          string             `json:"status" gorm:"not null;size:20;index"` // compliant/violated/rejected
+	RowsChecked     int                `json:"rows_checked" gorm:"not null;default:0"`
+	ViolationCount  int                `json:"violation_count" gorm:"not null;default:0"` // 违规次数合计
+	Violations      ContractViolations `json:"violations" gorm:"type:jsonb"`
+	CheckedAt       time.Time          `json:"checked_at" gorm:"not null;index:idx_contract_checks_interface_time,priority:2"`
+}
+
+// TableName 指定表名
+func (InterfaceContractCheck) TableName() string {
+	return "interface_contract_checks"
+}
+
+// scanJSONValue 从数据库的JSON值解析到目标
+func scanJSONValue(value interface{}, target interface{}) error {
+	var bytes []byte
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case []byte:
+		bytes = v
+	case string:
+		bytes = []byte(v)
+	default:
+		return errors.New("类型断言失败: 不是 []byte 或 string")
+	}
+	return json.Unmarshal(bytes, target)
+}
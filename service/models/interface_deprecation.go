@@ -0,0 +1,72 @@
+/*
+ * @module service/models/interface_deprecation
+ * @description 接口弃用记录，标记共享接口、主题接口或基础接口计划下线，记录弃用原因、替代接口和下线(sunset)时间
+ * @architecture 分层架构 - 数据模型层
+ * @documentReference ai_docs/model.md
+ * @stateFlow 标记弃用(通知订阅方) -> 修改下线时间或替代接口(再次通知) -> 到达下线时间后共享接口不再返回数据；撤销弃用时删除记录并通知订阅方
+ * @rules 每个资源最多一条弃用记录；共享接口未单独标记时继承其主题接口的弃用记录；弃用的资源不能新建订阅
+ * @dependencies gorm.io/gorm, github.com/google/uuid
+ * @refs service/sharing/deprecation.go, api/controllers/deprecation_controller.go
+ */
+
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// 可标记弃用的资源类型，与数据订阅的资源类型一致，另加共享接口
+const (
+	DeprecationResourceApiInterface      = "api_interface"
+	DeprecationResourceThematicInterface = "thematic_interface"
+	DeprecationResourceBasicInterface    = "basic_interface"
+)
+
+// 弃用通知的动作
+const (
+	DeprecationNoticeDeprecated = "deprecated" // 首次标记弃用
+	DeprecationNoticeUpdated    = "updated"    // 下线时间或替代接口变化
+	DeprecationNoticeCancelled  = "cancelled"  // 撤销弃用
+)
+
+// 收到弃用通知的订阅方类型
+const (
+	DeprecationConsumerDataSubscription = "data_subscription" // 订阅该资源的数据订阅
+	DeprecationConsumerApiKey           = "api_key"           // 可访问共享接口所在应用的ApiKey
+)
+
+// InterfaceDeprecation 接口弃用记录
+type InterfaceDeprecation struct {
+	ID                string     `json:"id" gorm:"primaryKey;type:varchar(36)"`
+	ResourceType      string     `json:"resource_type" gorm:"not null;size:30;uniqueIndex:idx_interface_deprecation_resource,priority:1"` // api_interface/thematic_interface/basic_interface
+	ResourceID        string     `json:"resource_id" gorm:"not null;type:varchar(36);uniqueIndex:idx_interface_deprecation_resource,priority:2"`
+	Reason            string     `json:"reason" gorm:"type:text"`
+	Replacement       string     `json:"replacement,omitempty" gorm:"size:500"`        // 替代接口的地址或说明，共享接口响应的Link头中返回
+	SunsetAt          *time.Time `json:"sunset_at,omitempty" gorm:"index"`             // 下线时间，之后共享接口不再返回数据
+	NotifiedConsumers int        `json:"notified_consumers" gorm:"not null;default:0"` // 最近一次通知的订阅方数量
+	DeprecatedBy      string     `json:"deprecated_by" gorm:"size:100"`
+	DeprecatedAt      time.Time  `json:"deprecated_at" gorm:"not null"`
+	UpdatedBy         string     `json:"updated_by" gorm:"size:100"`
+	UpdatedAt         time.Time  `json:"updated_at"`
+}
+
+// TableName 指定表名
+func (InterfaceDeprecation) TableName() string {
+	return "interface_deprecations"
+}
+
+// BeforeCreate GORM钩子，创建前生成UUID
+func (d *InterfaceDeprecation) BeforeCreate(tx *gorm.DB) error {
+	if d.ID == "" {
+		d.ID = uuid.New().String()
+	}
+	return nil
+}
+
+// Sunset 是否已到下线时间
+func (d *InterfaceDeprecation) Sunset(now time.Time) bool {
+	return d.SunsetAt != nil && !now.Before(*d.SunsetAt)
+}
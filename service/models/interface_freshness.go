@@ -0,0 +1,55 @@
+/*
+ * @module service/models/interface_freshness
+ * @description 接口数据新鲜度模型，记录每个数据接口最近一次成功同步的时间、预期同步周期和新鲜度状态
+ * @architecture 分层架构 - 数据模型层
+ * @documentReference ai_docs/model.md
+ * @stateFlow unknown(无法确定预期周期) / fresh(在预期周期内同步成功) <-> stale(超过预期周期未同步成功)
+ * @rules 每个接口一条记录；配置了SLA时以SLA为准，否则按接口所属的激活同步任务调度推导预期周期
+ * @dependencies gorm.io/gorm
+ * @refs service/basic_library/freshness_service.go, api/controllers/freshness_controller.go
+ */
+
+package models
+
+import (
+	"time"
+)
+
+// 数据新鲜度状态
+const (
+	FreshnessStatusUnknown = "unknown" // 没有配置SLA也没有周期调度的同步任务
+	FreshnessStatusFresh   = "fresh"
+	FreshnessStatusStale   = "stale"
+)
+
+// 预期同步周期来源
+const (
+	FreshnessSourceNone     = "none"
+	FreshnessSourceSLA      = "sla"      // 手工配置的新鲜度SLA
+	FreshnessSourceSchedule = "schedule" // 按同步任务调度推导
+)
+
+// InterfaceFreshness 接口数据新鲜度
+type InterfaceFreshness struct {
+	InterfaceID             string     `json:"interface_id" gorm:"primaryKey;type:varchar(36)"`
+	SLASeconds              int        `json:"sla_seconds" gorm:"not null;default:0"`                     // 新鲜度SLA，0表示按调度推导
+	ExpectedIntervalSeconds int        `json:"expected_interval_seconds" gorm:"not null;default:0"`       // 当前生效的预期同步周期
+	ExpectationSource       string     `json:"expectation_source" gorm:"not null;size:20;default:'none'"` // none/sla/schedule
+	Status                  string     `json:"status" gorm:"not null;size:20;default:'unknown';index"`    // unknown/fresh/stale
+	LastSuccessAt           *time.Time `json:"last_success_at,omitempty"`                                 // 最近一次成功同步时间
+	StaleSince              *time.Time `json:"stale_since,omitempty"`                                     // 开始过期的时间
+	LastCheckedAt           *time.Time `json:"last_checked_at,omitempty"`
+	LastAlertedAt           *time.Time `json:"last_alerted_at,omitempty"`
+	UpdatedAt               time.Time  `json:"updated_at" gorm:"not null;default:CURRENT_TIMESTAMP"`
+	UpdatedBy               string     `json:"updated_by" gorm:"not null;default:'system';size:100"`
+}
+
+// TableName 指定表名
+func (InterfaceFreshness) TableName() string {
+	return "interface_freshness"
+}
+
+// IsStale 数据是否已过期
+func (f *InterfaceFreshness) IsStale() bool {
+	return f.Status == FreshnessStatusStale
+}
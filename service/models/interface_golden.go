@@ -0,0 +1,216 @@
+/*
+ * @module service/models/interface_golden
+ * @description 接口基准数据集模型，为接口保存一份上传或取自快照的基准数据，修改接口配置后把接口表与基准数据比较（表结构和容差内的取值），作为回归测试
+ * @architecture 分层架构 - 数据模型层
+ * @documentReference ai_docs/model.md
+ * @stateFlow 上传基准数据或选择快照 -> 保存数据集和基准行 -> 手动或同步成功后比较 -> passed(一致) / failed(有差异) / error(无法比较)
+ * @rules 基准数据集按键字段匹配接口表中的行；subset模式只检查基准行，exact模式接口表中多出的行也算差异；
+ *        列类型取创建时的接口字段配置，比较时与当前字段配置对照；每次比较保存一条记录，差异样例有上限
+ * @dependencies gorm.io/gorm, github.com/google/uuid
+ * @refs service/basic_library/golden_dataset_service.go, api/controllers/golden_dataset_controller.go
+ */
+
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// 基准数据集的匹配模式
+const (
+	GoldenMatchSubset = "subset" // 只检查基准行，接口表可以有其他行
+	GoldenMatchExact  = "exact"  // 接口表中不在基准数据集中的行也算差异
+)
+
+// 基准数据集的来源
+const (
+	GoldenSourceUpload   = "upload"
+	GoldenSourceSnapshot = "snapshot"
+)
+
+// 基准数据比较的触发方式
+const (
+	GoldenTriggerManual = "manual"
+	GoldenTriggerSync   = "sync"
+)
+
+// 基准数据比较结果
+const (
+	GoldenRunPassed = "passed"
+	GoldenRunFailed = "failed"
+	GoldenRunError  = "error"
+)
+
+// 表结构差异类型
+const (
+	GoldenSchemaMissingColumn = "missing_column" // 基准数据集中的列不在接口表中
+	GoldenSchemaExtraColumn   = "extra_column"   // 接口表中多出的列，只提示不算失败
+	GoldenSchemaTypeChanged   = "type_changed"   // 接口字段配置的类型与基准数据集不一致
+)
+
+// GoldenColumn 基准数据集中的一列
+type GoldenColumn struct {
+	Name string `json:"name" example:"amount"`
+	Type string `json:"type,omitempty" example:"numeric"` // 接口字段配置中的数据类型，为空表示不比较类型
+}
+
+// GoldenColumns 基准数据集的列清单
+type GoldenColumns []GoldenColumn
+
+// Scan 实现 Scanner 接口
+func (c *GoldenColumns) Scan(value interface{}) error {
+	return scanJSONValue(value, c)
+}
+
+// Value 实现 Valuer 接口
+func (c GoldenColumns) Value() (driver.Value, error) {
+	return json.Marshal(c)
+}
+
+// GoldenSchemaIssue 一处表结构差异
+type GoldenSchemaIssue struct {
+	Column   string `json:"column"`
+	Kind     string `json:"kind"` // missing_column/extra_column/type_changed
+	Expected string `json:"expected,omitempty"`
+	Actual   string `json:"actual,omitempty"`
+}
+
+// GoldenSchemaIssues 表结构差异清单
+type GoldenSchemaIssues []GoldenSchemaIssue
+
+// Scan 实现 Scanner 接口
+func (i *GoldenSchemaIssues) Scan(value interface{}) error {
+	return scanJSONValue(value, i)
+}
+
+// Value 实现 Valuer 接口
+func (i GoldenSchemaIssues) Value() (driver.Value, error) {
+	return json.Marshal(i)
+}
+
+// GoldenMismatch 一个取值差异样例
+type GoldenMismatch struct {
+	Key      map[string]interface{} `json:"key"`
+	Column   string                 `json:"column"`
+	Expected interface{}            `json:"expected"`
+	Actual   interface{}            `json:"actual"`
+}
+
+// GoldenMismatches 取值差异样例清单
+type GoldenMismatches []GoldenMismatch
+
+// Scan 实现 Scanner 接口
+func (m *GoldenMismatches) Scan(value interface{}) error {
+	return scanJSONValue(value, m)
+}
+
+// Value 实现 Valuer 接口
+func (m GoldenMismatches) Value() (driver.Value, error) {
+	return json.Marshal(m)
+}
+
+// InterfaceGoldenDataset 接口基准数据集
+type InterfaceGoldenDataset struct {
+	ID               string           `json:"id" gorm:"primaryKey;type:varchar(36)"`
+	InterfaceID      string           `json:"interface_id" gorm:"not null;type:varchar(36);index"`
+	Name             string           `json:"name" gorm:"not null;size:100"`
+	Description      string           `json:"description" gorm:"size:500"`
+	Source           string           `json:"source" gorm:"not null;size:20"` // upload/snapshot
+	SnapshotID       string           `json:"snapshot_id,omitempty" gorm:"type:varchar(36)"`
+	KeyFields        JSONBStringArray `json:"key_fields" gorm:"type:jsonb"`
+	Columns          GoldenColumns    `json:"columns" gorm:"type:jsonb"`
+	RowCount         int              `json:"row_count" gorm:"not null;default:0"`
+	MatchMode        string           `json:"match_mode" gorm:"not null;size:20;default:'subset'"` // subset/exact
+	AbsTolerance     float64          `json:"abs_tolerance" gorm:"not null;default:0"`             // 数值允许的绝对误差
+	RelTolerance     float64          `json:"rel_tolerance" gorm:"not null;default:0"`             // 数值允许的相对误差，如0.001表示千分之一
+	ColumnTolerances JSONB            `json:"column_tolerances,omitempty" gorm:"type:jsonb"`       // 按列覆盖的绝对误差
+	IgnoreColumns    JSONBStringArray `json:"ignore_columns" gorm:"type:jsonb"`                    // 不比较取值的列，如更新时间
+	AutoCompare      bool             `json:"auto_compare" gorm:"not null"`                        // 接口同步成功后自动比较
+	LastStatus       string           `json:"last_status,omitempty" gorm:"size:20"`
+	LastRunAt        *time.Time       `json:"last_run_at,omitempty"`
+	CreatedBy        string           `json:"created_by" gorm:"size:100"`
+	CreatedAt        time.Time        `json:"created_at"`
+	UpdatedBy        string           `json:"updated_by" gorm:"size:100"`
+	UpdatedAt        time.Time        `json:"updated_at"`
+}
+
+// TableName 指定表名
+func (InterfaceGoldenDataset) TableName() string {
+	return "interface_golden_datasets"
+}
+
+// BeforeCreate GORM钩子，创建前生成UUID
+func (d *InterfaceGoldenDataset) BeforeCreate(tx *gorm.DB) error {
+	if d.ID == "" {
+		d.ID = uuid.New().String()
+	}
+	return nil
+}
+
+// ToleranceFor 列的数值绝对误差，未按列设置时取数据集误差
+func (d *InterfaceGoldenDataset) ToleranceFor(column string) float64 {
+	if value, ok := d.ColumnTolerances[column]; ok {
+		switch v := value.(type) {
+		case float64:
+			return v
+		case int:
+			return float64(v)
+		}
+	}
+	return d.AbsTolerance
+}
+
+// InterfaceGoldenRow 基准数据集中的一行
+type InterfaceGoldenRow struct {
+	DatasetID string `json:"dataset_id" gorm:"primaryKey;type:varchar(36)"`
+	RowIndex  int    `json:"row_index" gorm:"primaryKey"`
+	Data      JSONB  `json:"data" gorm:"type:jsonb"`
+}
+
+// TableName 指定表名
+func (InterfaceGoldenRow) TableName() string {
+	return "interface_golden_rows"
+}
+
+// InterfaceGoldenRun 一次基准数据比较的结果
+type InterfaceGoldenRun struct {
+	ID               string             `json:"id" gorm:"primaryKey;type:varchar(36)"`
+	DatasetID        string             `json:"dataset_id" gorm:"not null;type:varchar(36);index:idx_golden_runs_dataset_time,priority:1"`
+	InterfaceID      string             `json:"interface_id" gorm:"not null;type:varchar(36);index"`
+	TaskID           string             `json:"task_id,omitempty" gorm:"type:varchar(36)"`
+	ExecutionID      string             `json:"execution_id,omitempty" gorm:"type:varchar(36)"` // 同步后自动比较时触发的执行
+	Trigger          string             `json:"trigger" gorm:"not null;size:20"`                // manual/sync
+	Status           string             `json:"status" gorm:"not null;size:20"`                 // passed/failed/error
+	ExpectedRows     int                `json:"expected_rows" gorm:"not null;default:0"`
+	ActualRows       int64              `json:"actual_rows" gorm:"not null;default:0"` // 比较时接口表的行数
+	MatchedRows      int                `json:"matched_rows" gorm:"not null;default:0"`
+	MissingRows      int                `json:"missing_rows" gorm:"not null;default:0"`    // 基准行在接口表中不存在
+	UnexpectedRows   int64              `json:"unexpected_rows" gorm:"not null;default:0"` // exact模式下接口表中多出的行
+	MismatchedRows   int                `json:"mismatched_rows" gorm:"not null;default:0"` // 键相同但取值超出容差
+	SchemaIssues     GoldenSchemaIssues `json:"schema_issues" gorm:"type:jsonb"`
+	ColumnMismatches SyncColumnChanges  `json:"column_mismatches" gorm:"type:jsonb"` // 各列取值不一致的行数
+	Mismatches       GoldenMismatches   `json:"mismatches" gorm:"type:jsonb"`        // 取值差异样例
+	MissingKeys      SyncDiffKeys       `json:"missing_keys" gorm:"type:jsonb"`      // 缺失行的键样例
+	ErrorMessage     string             `json:"error_message,omitempty" gorm:"type:text"`
+	CreatedBy        string             `json:"created_by" gorm:"size:100"`
+	StartedAt        time.Time          `json:"started_at" gorm:"not null;index:idx_golden_runs_dataset_time,priority:2"`
+	FinishedAt       time.Time          `json:"finished_at"`
+}
+
+// TableName 指定表名
+func (InterfaceGoldenRun) TableName() string {
+	return "interface_golden_runs"
+}
+
+// BeforeCreate GORM钩子，创建前生成UUID
+func (r *InterfaceGoldenRun) BeforeCreate(tx *gorm.DB) error {
+	if r.ID == "" {
+		r.ID = uuid.New().String()
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+/*
+ * @module service/models/interface_reference
+ * @description 基础库接口间的逻辑外键模型，声明子接口字段引用父接口字段(如 device.building_id -> building.id)，并记录每次引用完整性检查的结果
+ * @architecture 分层架构 - 数据模型层
+ * @documentReference ai_docs/model.md
+ * @stateFlow 声明逻辑外键 -> 定时/手动检查 -> passed(无孤立引用) / violated(存在孤立引用，写入质量问题) / failed(检查出错)
+ * @rules 同步表无法使用物理外键，引用关系只用于检查不约束写入；子字段与父字段按顺序一一对应；子字段任一为空的行不检查
+ * @dependencies gorm.io/gorm, github.com/google/uuid
+ * @refs service/basic_library/reference_service.go, api/controllers/reference_controller.go
+ */
+
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// 引用完整性检查结果
+const (
+	ReferenceCheckPassed   = "passed"
+	ReferenceCheckViolated = "violated"
+	ReferenceCheckFailed   = "failed"
+)
+
+// QualityIssueTypeOrphanedReference 孤立引用质量问题类型
+const QualityIssueTypeOrphanedReference = "orphaned_reference"
+
+// InterfaceReference 接口间的逻辑外键
+type InterfaceReference struct {
+	ID                string           `json:"id" gorm:"primaryKey;type:varchar(36)"`
+	Name              string           `json:"name" gorm:"not null;size:100" example:"设备所属楼栋"`
+	ChildInterfaceID  string           `json:"child_interface_id" gorm:"not null;type:varchar(36);index"`
+	ChildFields       JSONBStringArray `json:"child_fields" gorm:"type:jsonb" example:"building_id"`
+	ParentInterfaceID string           `json:"parent_interface_id" gorm:"not null;type:varchar(36);index"`
+	ParentFields      JSONBStringArray `json:"parent_fields" gorm:"type:jsonb" example:"id"`
+	Severity          string           `json:"severity" gorm:"not null;size:20;default:'medium'"` // 孤立引用质量问题的严重程度: low, medium, high, critical
+	Enabled           bool             `json:"enabled" gorm:"not null"`                           // 不设列默认值，以便保存enabled=false
+	Description       string           `json:"description" gorm:"size:500"`
+	LastCheckedAt     *time.Time       `json:"last_checked_at,omitempty"`
+	LastStatus        string           `json:"last_status,omitempty" gorm:"size:20"`
+	LastOrphanRows    int64            `json:"last_orphan_rows" gorm:"not null;default:0"`
+	CreatedAt         time.Time        `json:"created_at" gorm:"not null;default:CURRENT_TIMESTAMP"`
+	CreatedBy         string           `json:"created_by" gorm:"not null;default:'system';size:100"`
+	UpdatedAt         time.Time        `json:"updated_at" gorm:"not null;default:CURRENT_TIMESTAMP"`
+	UpdatedBy         string           `json:"updated_by" gorm:"not null;default:'system';size:100"`
+}
+
+// TableName 指定表名
+func (InterfaceReference) TableName() string {
+	return "interface_references"
+}
+
+// BeforeCreate GORM钩子，创建前生成UUID
+func (r *InterfaceReference) BeforeCreate(tx *gorm.DB) error {
+	if r.ID == "" {
+		r.ID = uuid.New().String()
+	}
+	return nil
+}
+
+// ReferenceOrphan 一个孤立的引用取值
+type ReferenceOrphan struct {
+	Values   map[string]interface{} `json:"values"` // 子字段取值
+	RowCount int64                  `json:"row_count"`
+}
+
+// ReferenceOrphans 孤立引用样例
+type ReferenceOrphans []ReferenceOrphan
+
+// Scan 实现 Scanner 接口
+func (o *ReferenceOrphans) Scan(value interface{}) error {
+	return scanJSONValue(value, o)
+}
+
+// Value 实现 Valuer 接口
+func (o ReferenceOrphans) Value() (driver.Value, error) {
+	return json.Marshal(o)
+}
+
+// InterfaceReferenceCheck 一次引用完整性检查的结果
+type InterfaceReferenceCheck struct {
+	ID           string           `json:"id" gorm:"primaryKey;type:varchar(36)"`
+	ReferenceID  string           `json:"reference_id" gorm:"not null;type:varchar(36);index:idx_reference_checks_reference_time,priority:1"`
+	Status       string           `json:"status" gorm:"not null;size:20"` // passed/violated/failed
+	ChildRows    int64            `json:"child_rows" gorm:"not null;default:0"`
+	CheckedRows  int64            `json:"checked_rows" gorm:"not null;default:0"`  // 子字段都不为空、参与检查的行数
+	OrphanRows   int64            `json:"orphan_rows" gorm:"not null;default:0"`   // 在父接口中找不到的行数
+	OrphanValues int64            `json:"orphan_values" gorm:"not null;default:0"` // 找不到的不同取值数
+	Samples      ReferenceOrphans `json:"samples" gorm:"type:jsonb"`               // 按行数从多到少的孤立取值
+	ErrorMessage string           `json:"error_message,omitempty" gorm:"type:text"`
+	DurationMs   int64            `json:"duration_ms"`
+	CheckedAt    time.Time        `json:"checked_at" gorm:"not null;index:idx_reference_checks_reference_time,priority:2"`
+}
+
+// TableName 指定表名
+func (InterfaceReferenceCheck) TableName() string {
+	return "interface_reference_checks"
+}
+
+// BeforeCreate GORM钩子，创建前生成UUID
+func (c *InterfaceReferenceCheck) BeforeCreate(tx *gorm.DB) error {
+	if c.ID == "" {
+		c.ID = uuid.New().String()
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+/*
+ * @module service/models/interface_snapshot
+ * @description 接口表快照模型，记录接口的快照策略和每次同步后保存的快照表，用于按时间点查询和比较历史数据
+ * @architecture 分层架构 - 数据模型层
+ * @documentReference ai_docs/model.md
+ * @stateFlow 开启快照策略 -> 接口同步成功后复制接口表到快照schema -> 记录快照 -> 超出保留个数时删除最早的快照表
+ * @rules 快照表统一放在SnapshotSchema下，表名由快照ID生成；每个接口一条快照策略
+ * @dependencies gorm.io/gorm, github.com/google/uuid
+ * @refs service/basic_library/snapshot_service.go, api/controllers/snapshot_controller.go
+ */
+
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// SnapshotSchema 快照表所在的schema，与基础库schema隔离，不出现在数据查看的表列表中
+const SnapshotSchema = "datahub_snapshots"
+
+// InterfaceSnapshotPolicy 接口快照策略
+type InterfaceSnapshotPolicy struct {
+	InterfaceID string    `json:"interface_id" gorm:"primaryKey;type:varchar(36)"`
+	Enabled     bool      `json:"enabled" gorm:"not null;default:false"`
+	Retention   int       `json:"retention" gorm:"not null;default:30"` // 保留的快照个数，超出时删除最早的快照
+	UpdatedBy   string    `json:"updated_by" gorm:"size:100"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// TableName 指定表名
+func (InterfaceSnapshotPolicy) TableName() string {
+	return "interface_snapshot_policies"
+}
+
+// InterfaceTableSnapshot 接口表的一次快照
+type InterfaceTableSnapshot struct {
+	ID            string    `json:"id" gorm:"primaryKey;type:varchar(36)"`
+	InterfaceID   string    `json:"interface_id" gorm:"not null;type:varchar(36);index:idx_table_snapshots_interface_time,priority:1"`
+	TaskID        string    `json:"task_id,omitempty" gorm:"type:varchar(36)"`
+	ExecutionID   string    `json:"execution_id,omitempty" gorm:"type:varchar(36);index"` // 触发快照的同步执行，手动快照为空
+	SourceTable   string    `json:"source_table" gorm:"not null;size:255"`                // 快照时的接口表，schema.table
+	SnapshotTable string    `json:"snapshot_table" gorm:"not null;size:63"`               // SnapshotSchema下的快照表名
+	RowCount      int64     `json:"row_count" gorm:"not null;default:0"`
+	CreatedBy     string    `json:"created_by" gorm:"size:100"` // 手动快照的操作人，同步触发为system
+	CapturedAt    time.Time `json:"captured_at" gorm:"not null;index:idx_table_snapshots_interface_time,priority:2"`
+}
+
+// TableName 指定表名
+func (InterfaceTableSnapshot) TableName() string {
+	return "interface_table_snapshots"
+}
+
+// BeforeCreate GORM钩子，创建前生成UUID
+func (s *InterfaceTableSnapshot) BeforeCreate(tx *gorm.DB) error {
+	if s.ID == "" {
+		s.ID = uuid.New().String()
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+/*
+ * @module service/models/library_quota
+ * @description 配额模型，为单个基础库/主题库或整个租户设置数据行数、存储空间和同步任务数上限，避免单个团队占满集群资源
+ * @architecture 分层架构 - 数据模型层
+ * @documentReference ai_docs/model.md
+ * @stateFlow 设置配额 -> 创建同步任务和同步执行前检查用量 -> 超出时按处理方式拒绝或告警
+ * @rules 同一库或租户只有一条配额；上限为0表示不限制；行数和存储用量取最近一次存储快照
+ * @dependencies gorm.io/gorm, github.com/google/uuid
+ * @refs service/capacity/quota.go, api/controllers/quota_controller.go
+ */
+
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// 配额范围
+const (
+	QuotaScopeLibrary = "library"
+	QuotaScopeTenant  = "tenant"
+)
+
+// 超出配额的处理方式
+const (
+	QuotaEnforcementReject = "reject" // 拒绝创建任务和执行同步
+	QuotaEnforcementWarn   = "warn"   // 只记录告警事件
+)
+
+// LibraryQuota 库或租户配额
+type LibraryQuota struct {
+	ID              string    `json:"id" gorm:"primaryKey;type:varchar(36)"`
+	ScopeType       string    `json:"scope_type" gorm:"not null;size:20;uniqueIndex:idx_library_quota_scope" example:"library"` // library, tenant
+	ScopeID         string    `json:"scope_id" gorm:"not null;type:varchar(36);uniqueIndex:idx_library_quota_scope"`            // 库ID或租户ID
+	LibraryType     string    `json:"library_type,omitempty" gorm:"size:20" example:"basic_library"`                            // 库范围时为basic_library或thematic_library
+	MaxRows         int64     `json:"max_rows" gorm:"not null;default:0" example:"100000000"`                                   // 数据行数上限，0表示不限制
+	MaxStorageBytes int64     `json:"max_storage_bytes" gorm:"not null;default:0" example:"107374182400"`                       // 存储空间上限（字节），0表示不限制
+	MaxTasks        int64     `json:"max_tasks" gorm:"not null;default:0" example:"50"`                                         // 同步任务数上限，0表示不限制
+	Enforcement     string    `json:"enforcement" gorm:"not null;size:20;default:'reject'" example:"reject"`                    // reject, warn
+	CreatedAt       time.Time `json:"created_at"`
+	CreatedBy       string    `json:"created_by" gorm:"size:100"`
+	UpdatedAt       time.Time `json:"updated_at"`
+	UpdatedBy       string    `json:"updated_by" gorm:"size:100"`
+}
+
+// BeforeCreate GORM钩子，创建前生成UUID
+func (q *LibraryQuota) BeforeCreate(tx *gorm.DB) error {
+	if q.ID == "" {
+		q.ID = uuid.New().String()
+	}
+	if q.Enforcement == "" {
+		q.Enforcement = QuotaEnforcementReject
+	}
+	return nil
+}
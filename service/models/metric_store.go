@@ -0,0 +1,99 @@
+/*
+ * @module service/models/metric_store
+ * @description 指标层模型，业务指标定义（基于接口表的聚合表达式、维度、时间粒度和刷新周期）及定期物化的指标值
+ * @architecture 分层架构 - 数据模型层
+ * @documentReference ai_docs/model.md
+ * @stateFlow 定义指标 -> 按刷新周期物化(succeeded/failed) -> 看板读取最近一次物化的指标值
+ * @rules 指标名称在租户内唯一；每次物化整体替换该指标的指标值，物化失败时保留上一次的结果；指标值按维度取值和时间周期区分
+ * @dependencies gorm.io/gorm, github.com/google/uuid
+ * @refs service/metric_store, api/controllers/metric_store_controller.go
+ */
+
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// 指标时间粒度
+const (
+	MetricGrainHour  = "hour"
+	MetricGrainDay   = "day"
+	MetricGrainWeek  = "week"
+	MetricGrainMonth = "month"
+)
+
+// 指标物化结果
+const (
+	MetricRefreshSucceeded = "succeeded"
+	MetricRefreshFailed    = "failed"
+)
+
+// MetricDefinition 业务指标定义
+type MetricDefinition struct {
+	ID                string           `json:"id" gorm:"primaryKey;type:varchar(36)"`
+	TenantID          string           `json:"tenant_id" gorm:"not null;size:36;default:'default';index"` // 所属租户
+	Name              string           `json:"name" gorm:"not null;size:63" example:"daily_vehicle_entries"`
+	DisplayName       string           `json:"display_name" gorm:"not null;size:255" example:"每日车辆入场数"`
+	Description       string           `json:"description" gorm:"size:1000"`
+	Unit              string           `json:"unit" gorm:"size:50" example:"辆次"`
+	LibraryType       string           `json:"library_type" gorm:"not null;size:50" example:"thematic_library"` // 接口所属库类型：basic_library, thematic_library
+	InterfaceID       string           `json:"interface_id" gorm:"not null;type:varchar(36);index"`
+	Expression        string           `json:"expression" gorm:"not null;size:1000" example:"count(*)"`         // 聚合表达式
+	Filter            string           `json:"filter" gorm:"size:1000" example:"direction = 'in'"`              // 过滤条件，为空时不过滤
+	Dimensions        JSONBStringArray `json:"dimensions" gorm:"type:jsonb" example:"parking_lot"`              // 分组维度字段
+	TimeColumn        string           `json:"time_column" gorm:"size:255" example:"entered_at"`                // 时间字段，配置时间粒度时必填
+	TimeGrain         string           `json:"time_grain" gorm:"size:20" example:"day"`                         // 时间粒度：hour, day, week, month，为空时不按时间分组
+	LookbackDays      int              `json:"lookback_days" gorm:"not null;default:0" example:"90"`            // 只物化最近若干天的数据，0表示不限
+	RefreshSchedule   string           `json:"refresh_schedule" gorm:"not null;size:100" example:"0 0 * * * *"` // 刷新周期cron表达式
+	Enabled           bool             `json:"enabled" gorm:"not null"`                                         // 不设列默认值，以便保存enabled=false
+	NextRefreshAt     *time.Time       `json:"next_refresh_at,omitempty" gorm:"index"`                          // 下次定时刷新时间，停用时为空
+	LastRefreshedAt   *time.Time       `json:"last_refreshed_at,omitempty"`
+	LastRefreshStatus string           `json:"last_refresh_status,omitempty" gorm:"size:20"`
+	LastRefreshError  string           `json:"last_refresh_error,omitempty" gorm:"type:text"`
+	LastDurationMs    int64            `json:"last_duration_ms" gorm:"not null;default:0"`
+	ValueCount        int              `json:"value_count" gorm:"not null;default:0"` // 最近一次成功物化的指标值条数
+	CreatedAt         time.Time        `json:"created_at" gorm:"not null;default:CURRENT_TIMESTAMP"`
+	CreatedBy         string           `json:"created_by" gorm:"not null;default:'system';size:100"`
+	UpdatedAt         time.Time        `json:"updated_at" gorm:"not null;default:CURRENT_TIMESTAMP"`
+	UpdatedBy         string           `json:"updated_by" gorm:"not null;default:'system';size:100"`
+}
+
+// TableName 指定表名
+func (MetricDefinition) TableName() string {
+	return "metric_definitions"
+}
+
+// BeforeCreate GORM钩子，创建前生成UUID
+func (m *MetricDefinition) BeforeCreate(tx *gorm.DB) error {
+	if m.ID == "" {
+		m.ID = uuid.New().String()
+	}
+	return nil
+}
+
+// MetricValue 物化的指标值
+type MetricValue struct {
+	ID          string     `json:"-" gorm:"primaryKey;type:varchar(36)"`
+	MetricID    string     `json:"-" gorm:"not null;type:varchar(36);index"`
+	Period      *time.Time `json:"period,omitempty" gorm:"index"` // 时间周期起点，未配置时间粒度时为空
+	Dimensions  JSONB      `json:"dimensions,omitempty" gorm:"type:jsonb"`
+	Value       *float64   `json:"value"` // 聚合结果为空时为null
+	RefreshedAt time.Time  `json:"refreshed_at" gorm:"not null"`
+}
+
+// TableName 指定表名
+func (MetricValue) TableName() string {
+	return "metric_values"
+}
+
+// BeforeCreate GORM钩子，创建前生成UUID
+func (v *MetricValue) BeforeCreate(tx *gorm.DB) error {
+	if v.ID == "" {
+		v.ID = uuid.New().String()
+	}
+	return nil
+}
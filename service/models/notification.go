@@ -0,0 +1,188 @@
+/*
+ * @module service/models/notification
+ * @description 通知中心模型，包括通知渠道、订阅规则、消息模板、站内通知和渠道投递记录
+ * @architecture 分层架构 - 数据模型层
+ * @documentReference ai_docs/model.md
+ * @stateFlow 应用事件 -> 匹配订阅规则 -> 生成站内通知 -> 按订阅的渠道投递(pending -> sent/failed)
+ * @rules 渠道名称唯一；订阅规则的对象类型、对象ID、事件类型为空表示不限；订阅未指定渠道时只生成站内通知；同一事件类型、渠道类型和语言只有一个消息模板
+ * @dependencies gorm.io/gorm, github.com/google/uuid
+ * @refs service/notification, api/controllers/notification_controller.go
+ */
+
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// 通知渠道类型
+const (
+	NotifyChannelWebhook  = "webhook"
+	NotifyChannelEmail    = "email"
+	NotifyChannelDingTalk = "dingtalk" // 钉钉群机器人
+	NotifyChannelWeCom    = "wecom"    // 企业微信群机器人
+)
+
+// 渠道投递状态
+const (
+	NotifyDeliveryPending = "pending"
+	NotifyDeliverySent    = "sent"
+	NotifyDeliveryFailed  = "failed"
+)
+
+// NotifyChannel 通知渠道
+type NotifyChannel struct {
+	ID          string    `gorm:"type:uuid;primary_key" json:"id"`
+	Name        string    `gorm:"not null;size:100;uniqueIndex" json:"name"`
+	Type        string    `gorm:"not null;size:20" json:"type"`                // webhook/email/dingtalk/wecom
+	Config      JSONB     `gorm:"type:jsonb" json:"config"`                    // webhook: url、headers；email: recipients；dingtalk/wecom: webhook_url、secret
+	Locale      string    `gorm:"not null;size:10;default:'zh'" json:"locale"` // 消息模板语言：zh/en
+	IsEnabled   bool      `gorm:"not null" json:"is_enabled"`
+	Description string    `json:"description"`
+	CreatedAt   time.Time `gorm:"not null;default:CURRENT_TIMESTAMP" json:"created_at"`
+	CreatedBy   string    `gorm:"not null;default:'system';size:100" json:"created_by"`
+	UpdatedAt   time.Time `gorm:"not null;default:CURRENT_TIMESTAMP" json:"updated_at"`
+	UpdatedBy   string    `gorm:"not null;default:'system';size:100" json:"updated_by"`
+}
+
+// NotifySubscription 通知订阅规则
+type NotifySubscription struct {
+	ID          string           `gorm:"type:uuid;primary_key" json:"id"`
+	Name        string           `gorm:"not null;size:100" json:"name"`
+	ChannelID   *string          `gorm:"type:uuid;index" json:"channel_id"`                // 为空时只生成站内通知
+	ObjectType  string           `gorm:"size:50" json:"object_type"`                       // sync_task/thematic_sync_task/quality_task/backup_record，为空不限
+	ObjectID    string           `gorm:"size:100" json:"object_id"`                        // 为空不限
+	EventTypes  JSONBStringArray `gorm:"type:jsonb" json:"event_types"`                    // 为空不限
+	MinLevel    string           `gorm:"not null;size:10;default:'warn'" json:"min_level"` // info/warn/error
+	IsEnabled   bool             `gorm:"not null" json:"is_enabled"`
+	Description string           `json:"description"`
+	CreatedAt   time.Time        `gorm:"not null;default:CURRENT_TIMESTAMP" json:"created_at"`
+	CreatedBy   string           `gorm:"not null;default:'system';size:100" json:"created_by"`
+	UpdatedAt   time.Time        `gorm:"not null;default:CURRENT_TIMESTAMP" json:"updated_at"`
+	UpdatedBy   string           `gorm:"not null;default:'system';size:100" json:"updated_by"`
+
+	Channel *NotifyChannel `gorm:"foreignKey:ChannelID" json:"channel,omitempty"`
+}
+
+// Notification 站内通知，每个匹配订阅的事件生成一条
+type Notification struct {
+	ID         string     `gorm:"type:uuid;primary_key" json:"id"`
+	EventType  string     `gorm:"not null;size:50;index" json:"event_type"`
+	Level      string     `gorm:"not null;size:10;index" json:"level"`
+	Title      string     `gorm:"not null;size:200" json:"title"`
+	Content    string     `gorm:"type:text" json:"content"`
+	ObjectType string     `gorm:"size:50" json:"object_type"`
+	ObjectID   string     `gorm:"size:100;index" json:"object_id"`
+	ObjectName string     `gorm:"size:255" json:"object_name"`
+	Link       string     `gorm:"size:500" json:"link,omitempty"` // 控制台详情链接
+	RequestID  string     `gorm:"size:64" json:"request_id"`
+	Attributes JSONB      `gorm:"type:jsonb" json:"attributes,omitempty"`
+	IsRead     bool       `gorm:"default:false;index" json:"is_read"`
+	ReadAt     *time.Time `json:"read_at,omitempty"`
+	ReadBy     string     `gorm:"size:100" json:"read_by,omitempty"`
+	CreatedAt  time.Time  `gorm:"not null;default:CURRENT_TIMESTAMP;index" json:"created_at"`
+
+	Deliveries []NotifyDelivery `gorm:"foreignKey:NotificationID" json:"deliveries,omitempty"`
+}
+
+// NotifyTemplate 自定义消息模板，使用Go text/template语法，覆盖内置模板
+type NotifyTemplate struct {
+	ID            string    `gorm:"type:uuid;primary_key" json:"id"`
+	EventType     string    `gorm:"not null;size:50;uniqueIndex:idx_notify_template" json:"event_type"` // 事件类型，default为兜底模板
+	ChannelType   string    `gorm:"size:20;uniqueIndex:idx_notify_template" json:"channel_type"`        // 为空适用于所有渠道和站内通知
+	Locale        string    `gorm:"not null;size:10;uniqueIndex:idx_notify_template" json:"locale"`     // zh/en
+	TitleTemplate string    `gorm:"not null;size:500" json:"title_template"`
+	BodyTemplate  string    `gorm:"type:text;not null" json:"body_template"`
+	IsEnabled     bool      `gorm:"not null" json:"is_enabled"`
+	Description   string    `json:"description"`
+	CreatedAt     time.Time `gorm:"not null;default:CURRENT_TIMESTAMP" json:"created_at"`
+	CreatedBy     string    `gorm:"not null;default:'system';size:100" json:"created_by"`
+	UpdatedAt     time.Time `gorm:"not null;default:CURRENT_TIMESTAMP" json:"updated_at"`
+	UpdatedBy     string    `gorm:"not null;default:'system';size:100" json:"updated_by"`
+}
+
+// NotifyDelivery 通知渠道投递记录
+type NotifyDelivery struct {
+	ID             string     `gorm:"type:uuid;primary_key" json:"id"`
+	NotificationID string     `gorm:"type:uuid;not null;index" json:"notification_id"`
+	ChannelID      string     `gorm:"type:uuid;not null;index" json:"channel_id"`
+	ChannelType    string     `gorm:"not null;size:20" json:"channel_type"`
+	Status         string     `gorm:"not null;size:20;default:'pending'" json:"status"` // pending/sent/failed
+	Attempts       int        `gorm:"default:0" json:"attempts"`
+	Error          string     `gorm:"type:text" json:"error,omitempty"`
+	SentAt         *time.Time `json:"sent_at,omitempty"`
+	CreatedAt      time.Time  `gorm:"not null;default:CURRENT_TIMESTAMP" json:"created_at"`
+}
+
+// BeforeCreate 创建前钩子
+func (c *NotifyChannel) BeforeCreate(tx *gorm.DB) error {
+	if c.ID == "" {
+		c.ID = uuid.New().String()
+	}
+	if c.CreatedBy == "" {
+		c.CreatedBy = "system"
+	}
+	if c.UpdatedBy == "" {
+		c.UpdatedBy = "system"
+	}
+	return nil
+}
+
+// BeforeCreate 创建前钩子
+func (s *NotifySubscription) BeforeCreate(tx *gorm.DB) error {
+	if s.ID == "" {
+		s.ID = uuid.New().String()
+	}
+	if s.MinLevel == "" {
+		s.MinLevel = EventLevelWarn
+	}
+	if s.CreatedBy == "" {
+		s.CreatedBy = "system"
+	}
+	if s.UpdatedBy == "" {
+		s.UpdatedBy = "system"
+	}
+	return nil
+}
+
+// BeforeCreate 创建前钩子
+func (n *Notification) BeforeCreate(tx *gorm.DB) error {
+	if n.ID == "" {
+		n.ID = uuid.New().String()
+	}
+	if n.Level == "" {
+		n.Level = EventLevelInfo
+	}
+	return nil
+}
+
+// BeforeCreate 创建前钩子
+func (t *NotifyTemplate) BeforeCreate(tx *gorm.DB) error {
+	if t.ID == "" {
+		t.ID = uuid.New().String()
+	}
+	if t.Locale == "" {
+		t.Locale = "zh"
+	}
+	if t.CreatedBy == "" {
+		t.CreatedBy = "system"
+	}
+	if t.UpdatedBy == "" {
+		t.UpdatedBy = "system"
+	}
+	return nil
+}
+
+// BeforeCreate 创建前钩子
+func (d *NotifyDelivery) BeforeCreate(tx *gorm.DB) error {
+	if d.ID == "" {
+		d.ID = uuid.New().String()
+	}
+	if d.Status == "" {
+		d.Status = NotifyDeliveryPending
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+/*
+ * @module service/models/operation
+ * @description 长时操作模型，统一描述同步任务启动、质量检测任务启动、主题同步执行等异步工作的状态、进度、结果链接和取消
+ * @architecture 分层架构 - 数据模型层
+ * @documentReference ai_docs/model.md
+ * @stateFlow 启动接口提交执行成功后创建操作(running) -> 按执行对象刷新 -> succeeded/failed/canceled
+ * @rules 操作只记录执行对象的引用（类型、目标ID、执行记录ID），状态以执行对象为准并在查询时刷新；进入结束状态后不再变化；链接为相对API根路径的路径
+ * @dependencies gorm.io/gorm, github.com/google/uuid
+ * @refs service/operation, api/controllers/operation_controller.go
+ */
+
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// 操作类型
+const (
+	OperationKindSyncTask     = "sync_task"     // 基础库同步任务启动
+	OperationKindQualityTask  = "quality_task"  // 质量检测任务启动
+	OperationKindThematicSync = "thematic_sync" // 主题同步任务执行
+)
+
+// 操作状态
+const (
+	OperationStatusPending   = "pending"
+	OperationStatusRunning   = "running"
+	OperationStatusSucceeded = "succeeded"
+	OperationStatusFailed    = "failed"
+	OperationStatusCanceled  = "canceled"
+)
+
+// Operation 长时操作
+type Operation struct {
+	ID          string     `json:"id" gorm:"primaryKey;type:varchar(36)"`
+	TenantID    string     `json:"tenant_id" gorm:"not null;size:36;default:'default';index"`                                                   // 所属租户
+	Kind        string     `json:"kind" gorm:"not null;size:30;index:idx_operation_target" example:"sync_task"`                                 // sync_task, quality_task, thematic_sync
+	TargetID    string     `json:"target_id" gorm:"not null;size:36;index:idx_operation_target"`                                                // 被操作的对象ID，如同步任务ID
+	ExecutionID string     `json:"execution_id,omitempty" gorm:"size:50"`                                                                       // 执行记录ID，执行面创建执行记录后填充
+	Status      string     `json:"status" gorm:"not null;size:20;default:'pending';index" example:"running"`                                    // pending, running, succeeded, failed, canceled
+	Progress    int        `json:"progress" gorm:"not null;default:0" example:"40"`                                                             // 进度百分比 0-100
+	Message     string     `json:"message,omitempty" gorm:"size:500"`                                                                           // 当前阶段或结果说明
+	Error       string     `json:"error,omitempty" gorm:"type:text"`                                                                            // 失败原因
+	SelfLink    string     `json:"self_link" gorm:"-" example:"/operations/550e8400-e29b-41d4-a716-446655440000"`                               // 操作资源路径
+	CancelLink  string     `json:"cancel_link,omitempty" gorm:"-" example:"/operations/550e8400-e29b-41d4-a716-446655440000/cancel"`            // 未结束时的取消路径
+	ResultLink  string     `json:"result_link,omitempty" gorm:"size:255" example:"/sync/tasks/executions/7c9e6679-7425-40de-944b-e07fc1f90ae7"` // 执行结果路径
+	RequestID   string     `json:"request_id,omitempty" gorm:"size:64"`                                                                         // 发起操作的请求ID
+	CreatedBy   string     `json:"created_by" gorm:"size:100"`
+	CreatedAt   time.Time  `json:"created_at" gorm:"index"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	FinishedAt  *time.Time `json:"finished_at,omitempty"`
+}
+
+// TableName 指定表名
+func (Operation) TableName() string {
+	return "operations"
+}
+
+// BeforeCreate GORM钩子，创建前生成UUID
+func (o *Operation) BeforeCreate(tx *gorm.DB) error {
+	if o.ID == "" {
+		o.ID = uuid.New().String()
+	}
+	if o.Status == "" {
+		o.Status = OperationStatusPending
+	}
+	if o.CreatedBy == "" {
+		o.CreatedBy = "system"
+	}
+	return nil
+}
+
+// AfterFind GORM钩子，填充操作资源和取消路径
+func (o *Operation) AfterFind(tx *gorm.DB) error {
+	o.FillLinks()
+	return nil
+}
+
+// AfterCreate GORM钩子，生成ID后填充操作资源和取消路径
+func (o *Operation) AfterCreate(tx *gorm.DB) error {
+	o.FillLinks()
+	return nil
+}
+
+// FillLinks 填充操作资源路径，未结束的操作填充取消路径
+func (o *Operation) FillLinks() {
+	o.SelfLink = "/operations/" + o.ID
+	o.CancelLink = ""
+	if !o.IsFinished() {
+		o.CancelLink = o.SelfLink + "/cancel"
+	}
+}
+
+// IsFinished 操作是否已结束
+func (o *Operation) IsFinished() bool {
+	switch o.Status {
+	case OperationStatusSucceeded, OperationStatusFailed, OperationStatusCanceled:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,58 @@
+/*
+ * @module service/models/pagination
+ * @description 统一分页参数和列表响应分页信息，所有列表接口使用page、size参数并返回total、page、size、total_pages
+ * @architecture 分层架构 - 数据模型层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow 请求分页参数 -> 规范化（默认值、上限） -> 查询 -> 列表响应嵌入PageMeta
+ * @rules 页码从1开始；每页数量默认10，上限100，超过上限按上限处理
+ * @dependencies 无
+ * @refs api/controllers/pagination.go
+ */
+
+package models
+
+// 分页参数默认值和上限
+const (
+	DefaultPageSize = 10
+	MaxPageSize     = 100
+)
+
+// PageMeta 列表响应的分页信息，匿名嵌入各列表响应结构
+type PageMeta struct {
+	Total      int64 `json:"total" example:"25"`
+	Page       int   `json:"page" example:"1"`
+	Size       int   `json:"size" example:"10"`
+	TotalPages int   `json:"total_pages" example:"3"`
+}
+
+// NewPageMeta 根据总数和分页参数创建分页信息
+func NewPageMeta(total int64, page, size int) PageMeta {
+	return PageMeta{
+		Total:      total,
+		Page:       page,
+		Size:       size,
+		TotalPages: TotalPages(total, size),
+	}
+}
+
+// TotalPages 计算总页数
+func TotalPages(total int64, size int) int {
+	if size <= 0 || total <= 0 {
+		return 0
+	}
+	return int((total + int64(size) - 1) / int64(size))
+}
+
+// NormalizePage 规范化分页参数：页码小于1取1，每页数量小于1取默认值，超过上限取上限
+func NormalizePage(page, size int) (int, int) {
+	if page < 1 {
+		page = 1
+	}
+	if size < 1 {
+		size = DefaultPageSize
+	}
+	if size > MaxPageSize {
+		size = MaxPageSize
+	}
+	return page, size
+}
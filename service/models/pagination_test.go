@@ -0,0 +1,44 @@
+/*
+ * @module service/models/pagination_test
+ * @description 分页参数规范化和分页信息测试
+ * @architecture 测试层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow 构造分页参数 -> 规范化 -> 验证默认值、上限和总页数
+ * @rules 不依赖数据库
+ * @dependencies github.com/stretchr/testify
+ * @refs service/models/pagination.go
+ */
+
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizePage(t *testing.T) {
+	cases := []struct {
+		page, size         int
+		wantPage, wantSize int
+	}{
+		{0, 0, 1, DefaultPageSize},
+		{-1, -5, 1, DefaultPageSize},
+		{3, 20, 3, 20},
+		{2, MaxPageSize + 1, 2, MaxPageSize},
+		{1, 100000, 1, MaxPageSize},
+	}
+
+	for _, tc := range cases {
+		page, size := NormalizePage(tc.page, tc.size)
+		assert.Equal(t, tc.wantPage, page)
+		assert.Equal(t, tc.wantSize, size)
+	}
+}
+
+func TestNewPageMeta(t *testing.T) {
+	assert.Equal(t, PageMeta{Total: 25, Page: 2, Size: 10, TotalPages: 3}, NewPageMeta(25, 2, 10))
+	assert.Equal(t, 0, NewPageMeta(0, 1, 10).TotalPages)
+	assert.Equal(t, 1, NewPageMeta(10, 1, 10).TotalPages)
+	assert.Equal(t, 0, TotalPages(10, 0))
+}
@@ -0,0 +1,52 @@
+/*
+ * @module service/models/protobuf_parse
+ * @description 接口解析配置中的Protobuf解析配置，保存上传的.proto描述、消息类型和字段路径，设备网关推送的二进制Protobuf消息按此解码为行
+ * @architecture 分层架构 - 数据模型层
+ * @documentReference ai_docs/model.md
+ * @stateFlow 上传.proto并选择消息类型 -> 校验记录路径和字段路径 -> 保存到解析配置的protobuf中 -> 实时数据源收到二进制消息时按配置解码 -> 字段映射 -> 写入接口表
+ * @rules 配置保存在解析配置的protobuf中；records_path为空时一条消息一行，否则按该repeated消息字段每个元素一行；
+ *        field_paths为空时取记录的顶层字段，否则只取配置的字段；以$.开头的字段路径从消息根部取值，用于把批次头信息带到每一行
+ * @dependencies encoding/json
+ * @refs service/datasource/protobuf_rows.go, service/basic_library/protobuf_parse_service.go
+ */
+
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// ProtobufParseConfigKey 解析配置中Protobuf解析配置的键
+const ProtobufParseConfigKey = "protobuf"
+
+// ProtobufRootPathPrefix 从消息根部取值的字段路径前缀
+const ProtobufRootPathPrefix = "$."
+
+// ProtobufParseConfig Protobuf解析配置
+type ProtobufParseConfig struct {
+	FileName    string            `json:"file_name,omitempty"`
+	ProtoFile   string            `json:"proto_file"`             // .proto文本
+	MessageType string            `json:"message_type"`           // 消息全名，为空时取第一个顶层消息
+	RecordsPath string            `json:"records_path,omitempty"` // repeated消息字段的路径，点号分隔
+	FieldPaths  map[string]string `json:"field_paths,omitempty"`  // 输出字段名 -> 记录中的字段路径
+	UpdatedBy   string            `json:"updated_by,omitempty"`
+	UpdatedAt   time.Time         `json:"updated_at"`
+}
+
+// ParseProtobufParseConfig 从解析配置中读取Protobuf解析配置，未配置时返回nil
+func ParseProtobufParseConfig(parseConfig map[string]interface{}) *ProtobufParseConfig {
+	raw, ok := parseConfig[ProtobufParseConfigKey]
+	if !ok || raw == nil {
+		return nil
+	}
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return nil
+	}
+	var config ProtobufParseConfig
+	if err := json.Unmarshal(data, &config); err != nil || config.ProtoFile == "" {
+		return nil
+	}
+	return &config
+}
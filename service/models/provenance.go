@@ -0,0 +1,98 @@
+/*
+ * @module service/models/provenance
+ * @description 记录级来源追溯配置，开启后接口表增加来源数据源、同步执行和入库时间三个系统列，同步写入时自动填写，任一行都能追溯到写入它的那次同步
+ * @architecture 分层架构 - 数据模型层
+ * @documentReference ai_docs/model.md
+ * @stateFlow 开启来源追溯 -> 字段配置追加系统列并修改表结构 -> 同步写入时按本次同步填写系统列 -> 数据查看按ProvenanceFilter筛选
+ * @rules 配置保存在接口配置的provenance_config中；系统列固定为_source_id、_sync_execution_id、_ingested_at，由同步维护，不由同步数据写入；
+ *        记录的是最后一次写入该行的同步，历史追踪中未变化而丢弃的数据不改写当前版本的系统列；预览、测试和实时写入不填写
+ * @dependencies github.com/spf13/cast
+ * @refs service/basic_library/provenance_service.go, service/interface_executor/provenance.go, api/controllers/data_view_controller.go
+ */
+
+package models
+
+import (
+	"strings"
+	"time"
+
+	"github.com/spf13/cast"
+)
+
+// ProvenanceConfigKey 接口配置中来源追溯配置的键
+const ProvenanceConfigKey = "provenance_config"
+
+// 来源追溯系统列
+const (
+	ProvenanceSourceColumn     = "_source_id"         // 写入该行的数据源ID
+	ProvenanceExecutionColumn  = "_sync_execution_id" // 写入该行的同步任务执行ID，手动执行的同步为空
+	ProvenanceIngestedAtColumn = "_ingested_at"       // 写入该行的同步开始时间
+)
+
+// ProvenanceConfig 记录级来源追溯配置
+type ProvenanceConfig struct {
+	Enabled bool `json:"enabled"`
+}
+
+// ParseProvenanceConfig 从接口配置中读取启用的来源追溯配置，未配置或未启用时返回nil
+func ParseProvenanceConfig(interfaceConfig map[string]interface{}) *ProvenanceConfig {
+	raw, ok := interfaceConfig[ProvenanceConfigKey].(map[string]interface{})
+	if !ok || !cast.ToBool(raw["enabled"]) {
+		return nil
+	}
+	return &ProvenanceConfig{Enabled: true}
+}
+
+// IsProvenanceColumn 判断是否为来源追溯系统列
+func IsProvenanceColumn(name string) bool {
+	switch name {
+	case ProvenanceSourceColumn, ProvenanceExecutionColumn, ProvenanceIngestedAtColumn:
+		return true
+	}
+	return false
+}
+
+// ProvenanceFields 来源追溯系统列的字段配置，依次排在orderBase之后，字段说明作为元数据文档
+func ProvenanceFields(orderBase int) []TableField {
+	return []TableField{
+		{NameZh: "来源数据源", NameEn: ProvenanceSourceColumn, DataType: "varchar", IsNullable: true, Description: "写入该行的数据源ID，由同步自动填写", OrderNum: orderBase + 1, IsIndexed: true},
+		{NameZh: "同步执行ID", NameEn: ProvenanceExecutionColumn, DataType: "varchar", IsNullable: true, Description: "最后一次写入该行的同步任务执行ID，手动执行的同步为空，由同步自动填写", OrderNum: orderBase + 2, IsIndexed: true},
+		{NameZh: "入库时间", NameEn: ProvenanceIngestedAtColumn, DataType: "timestamp", IsNullable: true, Description: "最后一次写入该行的同步开始时间，由同步自动填写", OrderNum: orderBase + 3, IsIndexed: true},
+	}
+}
+
+// ProvenanceFilter 数据查看按来源追溯系统列筛选的条件，零值表示不筛选
+type ProvenanceFilter struct {
+	SourceID     string
+	ExecutionID  string
+	IngestedFrom *time.Time // 入库时间下限（含）
+	IngestedTo   *time.Time // 入库时间上限（不含）
+}
+
+// IsEmpty 是否没有任何筛选条件
+func (f ProvenanceFilter) IsEmpty() bool {
+	return f.SourceID == "" && f.ExecutionID == "" && f.IngestedFrom == nil && f.IngestedTo == nil
+}
+
+// SQLFilter 生成参数化的筛选条件，没有条件时返回空串
+func (f ProvenanceFilter) SQLFilter() (string, []interface{}) {
+	var conditions []string
+	var args []interface{}
+	if f.SourceID != "" {
+		conditions = append(conditions, `"`+ProvenanceSourceColumn+`" = ?`)
+		args = append(args, f.SourceID)
+	}
+	if f.ExecutionID != "" {
+		conditions = append(conditions, `"`+ProvenanceExecutionColumn+`" = ?`)
+		args = append(args, f.ExecutionID)
+	}
+	if f.IngestedFrom != nil {
+		conditions = append(conditions, `"`+ProvenanceIngestedAtColumn+`" >= ?`)
+		args = append(args, *f.IngestedFrom)
+	}
+	if f.IngestedTo != nil {
+		conditions = append(conditions, `"`+ProvenanceIngestedAtColumn+`" < ?`)
+		args = append(args, *f.IngestedTo)
+	}
+	return strings.Join(conditions, " AND "), args
+}
@@ -0,0 +1,60 @@
+/*
+ * @module service/models/quality_gate
+ * @description 同步任务质量门禁，同步写入后对接口表执行质量检测，未通过时任务执行失败并阻断下游使用这些接口的主题同步任务
+ * @architecture 分层架构 - 数据模型层
+ * @documentReference ai_docs/model.md
+ * @stateFlow 同步任务写入接口表 -> 执行接口上启用的质量检测任务 -> 评分低于阈值或必须通过的规则不通过 -> 执行失败并阻断接口 -> 下游主题同步跳过 -> 门禁再次通过或人工解除后恢复
+ * @rules 配置保存在同步任务配置的quality_gate中；阻断按接口记录，同一接口只保留最近一次阻断；评分为0-100
+ * @dependencies github.com/spf13/cast
+ * @refs service/basic_library/quality_gate_service.go, service/thematic_library/thematic_sync/upstream_gate.go
+ */
+
+package models
+
+import (
+	"time"
+
+	"github.com/spf13/cast"
+)
+
+// QualityGateConfigKey 同步任务配置中质量门禁配置的键
+const QualityGateConfigKey = "quality_gate"
+
+// QualityGateConfig 同步任务的质量门禁配置
+type QualityGateConfig struct {
+	Enabled       bool     `json:"enabled"`
+	MinScore      *float64 `json:"min_score,omitempty"`      // 写入的接口上质量检测的综合评分下限(0-100)，为空时不检查评分
+	RequiredRules []string `json:"required_rules,omitempty"` // 必须全部通过的质量检测字段规则ID
+}
+
+// ParseQualityGateConfig 从同步任务配置中解析质量门禁，未开启或没有任何检查条件时返回nil
+func ParseQualityGateConfig(config map[string]interface{}) *QualityGateConfig {
+	raw, ok := config[QualityGateConfigKey].(map[string]interface{})
+	if !ok || !cast.ToBool(raw["enabled"]) {
+		return nil
+	}
+	gate := &QualityGateConfig{Enabled: true, RequiredRules: cast.ToStringSlice(raw["required_rules"])}
+	if value, exists := raw["min_score"]; exists && value != nil {
+		score := cast.ToFloat64(value)
+		gate.MinScore = &score
+	}
+	if gate.MinScore == nil && len(gate.RequiredRules) == 0 {
+		return nil
+	}
+	return gate
+}
+
+// QualityGateBlock 未通过质量门禁而被阻断的接口，下游主题同步任务读取该接口时跳过执行
+type QualityGateBlock struct {
+	InterfaceID string           `json:"interface_id" gorm:"primaryKey;type:varchar(36)"`
+	SyncTaskID  string           `json:"sync_task_id" gorm:"not null;type:varchar(36);index"`
+	ExecutionID string           `json:"execution_id" gorm:"type:varchar(36)"`
+	Score       *float64         `json:"score"`                     // 本次质量检测综合评分(0-100)，没有执行检测时为空
+	Reasons     JSONBStringArray `json:"reasons" gorm:"type:jsonb"` // 未通过的原因
+	BlockedAt   time.Time        `json:"blocked_at"`
+}
+
+// TableName 指定表名
+func (QualityGateBlock) TableName() string {
+	return "quality_gate_blocks"
+}
@@ -112,6 +112,7 @@ func (q *QualityMetricRecord) BeforeUpdate(tx *gorm.DB) error {
 // DataCleansingTemplate 数据清洗规则模板模型
 type DataCleansingTemplate struct {
 	ID              string    `gorm:"type:varchar(50);primaryKey" json:"id"`
+	TenantID        string    `gorm:"not null;size:36;default:'default';index" json:"tenant_id"` // 所属租户
 	Name            string    `gorm:"type:varchar(100);not null" json:"name"`
 	Description     string    `gorm:"type:text" json:"description"`
 	RuleType        string    `gorm:"type:varchar(30);not null" json:"rule_type"`                // standardization, deduplication, validation, transformation, enrichment
@@ -129,6 +130,7 @@ type DataCleansingTemplate struct {
 	UpdatedBy       string    `gorm:"type:varchar(50)" json:"updated_by"`
 	CreatedAt       time.Time `json:"created_at"`
 	UpdatedAt       time.Time `json:"updated_at"`
+	RowVersion      int64     `gorm:"not null;default:1" json:"row_version"` // 乐观锁版本
 	DeletedAt       time.Time `gorm:"index" json:"deleted_at,omitempty"`
 }
 
@@ -336,6 +338,7 @@ type QualityTask struct {
 	UpdatedBy       string     `gorm:"type:varchar(50)" json:"updated_by"`
 	CreatedAt       time.Time  `json:"created_at"`
 	UpdatedAt       time.Time  `json:"updated_at"`
+	RowVersion      int64      `gorm:"not null;default:1" json:"row_version"` // 乐观锁版本
 	DeletedAt       time.Time  `gorm:"index" json:"deleted_at,omitempty"`
 }
 
@@ -525,3 +528,49 @@ func (q *QualityIssueRecord) BeforeCreate(tx *gorm.DB) error {
 func (q *QualityIssueRecord) BeforeUpdate(tx *gorm.DB) error {
 	return nil
 }
+
+// RuleRecommendation 基于字段画像的规则推荐记录模型
+type RuleRecommendation struct {
+	ID                 string     `gorm:"type:varchar(50);primaryKey" json:"id"`
+	BatchID            string     `gorm:"type:varchar(50);not null;index" json:"batch_id"`                 // 同一次画像生成的推荐批次
+	TargetSchema       string     `gorm:"type:varchar(100);not null;index" json:"target_schema"`           // 目标schema
+	TargetTable        string     `gorm:"type:varchar(100);not null;index" json:"target_table"`            // 目标表名
+	LibraryType        string     `gorm:"type:varchar(30)" json:"library_type,omitempty"`                  // 按接口画像时的库类型：basic, thematic
+	LibraryID          string     `gorm:"type:varchar(50)" json:"library_id,omitempty"`                    // 按接口画像时的库ID
+	InterfaceID        string     `gorm:"type:varchar(50);index" json:"interface_id,omitempty"`            // 按接口画像时的接口ID
+	FieldName          string     `gorm:"type:varchar(100);not null" json:"field_name"`                    // 字段名称
+	RecommendationType string     `gorm:"type:varchar(20);not null;index" json:"recommendation_type"`      // quality, cleansing
+	TemplateID         string     `gorm:"type:varchar(50);not null" json:"template_id"`                    // 推荐的规则模板ID
+	TemplateName       string     `gorm:"type:varchar(100)" json:"template_name"`                          // 推荐的规则模板名称
+	SuggestedConfig    JSONB      `gorm:"type:jsonb" json:"suggested_config"`                              // 推荐的运行时参数
+	FieldProfile       JSONB      `gorm:"type:jsonb" json:"field_profile"`                                 // 推荐依据的字段画像
+	Reason             string     `gorm:"type:text" json:"reason"`                                         // 推荐理由
+	Confidence         float64    `gorm:"default:0" json:"confidence"`                                     // 置信度 (0-1)
+	Status             string     `gorm:"type:varchar(20);not null;default:'pending';index" json:"status"` // pending, applied, rejected, superseded
+	AppliedTargetType  string     `gorm:"type:varchar(30)" json:"applied_target_type,omitempty"`           // quality_task, thematic_sync_task
+	AppliedTargetID    string     `gorm:"type:varchar(50)" json:"applied_target_id,omitempty"`             // 采纳后写入的任务ID
+	HandledBy          string     `gorm:"type:varchar(50)" json:"handled_by,omitempty"`                    // 采纳/拒绝操作人
+	HandledAt          *time.Time `json:"handled_at,omitempty"`
+	CreatedBy          string     `gorm:"type:varchar(50)" json:"created_by"`
+	CreatedAt          time.Time  `json:"created_at"`
+	UpdatedAt          time.Time  `json:"updated_at"`
+}
+
+// TableName 指定表名
+func (RuleRecommendation) TableName() string {
+	return "rule_recommendations"
+}
+
+// BeforeCreate 创建前钩子
+func (r *RuleRecommendation) BeforeCreate(tx *gorm.DB) error {
+	if r.ID == "" {
+		r.ID = uuid.New().String()
+	}
+	if r.Status == "" {
+		r.Status = "pending"
+	}
+	if r.CreatedBy == "" {
+		r.CreatedBy = "system"
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+/*
+ * @module service/models/rbac
+ * @description 基于角色的访问控制模型，包括角色权限、用户角色分配和目录对象授权
+ * @architecture 分层架构 - 数据模型层
+ * @documentReference ai_docs/model.md
+ * @stateFlow 角色定义 -> 权限配置 -> 用户分配 -> 请求鉴权；对象授权 -> 目录列表和详情按授权过滤
+ * @rules 权限以"资源:操作"表示，支持通配符*；对象有授权记录时只对被授权的用户和角色可见，没有授权记录的对象对有资源读取权限的用户都可见
+ * @dependencies gorm.io/gorm, github.com/google/uuid
+ * @refs service/rbac, api/middleware/rbac.go
+ */
+
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// 内置角色
+const (
+	RoleAdmin     = "admin"     // 管理员
+	RoleSteward   = "steward"   // 数据管理员
+	RoleDeveloper = "developer" // 开发者
+	RoleConsumer  = "consumer"  // 数据使用者
+)
+
+// 权限操作
+const (
+	ActionRead    = "read"    // 查询
+	ActionWrite   = "write"   // 新增/修改
+	ActionDelete  = "delete"  // 删除
+	ActionExecute = "execute" // 执行（启动任务、测试等）
+)
+
+// PermissionWildcard 权限通配符
+const PermissionWildcard = "*"
+
+// RolePermission 角色权限模型
+type RolePermission struct {
+	ID        string    `gorm:"type:uuid;primary_key" json:"id"`
+	Role      string    `gorm:"not null;size:50;uniqueIndex:idx_role_resource_action" json:"role"`
+	Resource  string    `gorm:"not null;size:100;uniqueIndex:idx_role_resource_action" json:"resource"`
+	Action    string    `gorm:"not null;size:50;uniqueIndex:idx_role_resource_action" json:"action"`
+	CreatedAt time.Time `json:"created_at"`
+	CreatedBy string    `gorm:"size:100" json:"created_by"`
+}
+
+// BeforeCreate 创建前钩子
+func (rp *RolePermission) BeforeCreate(tx *gorm.DB) error {
+	if rp.ID == "" {
+		rp.ID = uuid.New().String()
+	}
+	if rp.CreatedBy == "" {
+		rp.CreatedBy = "system"
+	}
+	return nil
+}
+
+// UserRoleAssignment 用户角色分配模型
+type UserRoleAssignment struct {
+	ID          string    `gorm:"type:uuid;primary_key" json:"id"`
+	Username    string    `gorm:"not null;size:100;uniqueIndex:idx_user_role" json:"username"`
+	Role        string    `gorm:"not null;size:50;uniqueIndex:idx_user_role" json:"role"`
+	Description string    `json:"description"`
+	CreatedAt   time.Time `json:"created_at"`
+	CreatedBy   string    `gorm:"size:100" json:"created_by"`
+}
+
+// BeforeCreate 创建前钩子
+func (ura *UserRoleAssignment) BeforeCreate(tx *gorm.DB) error {
+	if ura.ID == "" {
+		ura.ID = uuid.New().String()
+	}
+	if ura.CreatedBy == "" {
+		ura.CreatedBy = "system"
+	}
+	return nil
+}
+
+// 对象授权的被授权方类型
+const (
+	GranteeUser = "user" // 用户
+	GranteeRole = "role" // 角色
+)
+
+// ResourceGrant 目录对象授权，授予用户或角色查看某个库、规则等对象
+type ResourceGrant struct {
+	ID          string    `gorm:"type:uuid;primary_key" json:"id"`
+	TenantID    string    `gorm:"not null;size:36;default:'default';index" json:"tenant_id"` // 所属租户
+	Resource    string    `gorm:"not null;size:100;uniqueIndex:idx_resource_grant;index:idx_resource_grant_object" json:"resource" example:"basic_library"`
+	ObjectID    string    `gorm:"not null;size:50;uniqueIndex:idx_resource_grant;index:idx_resource_grant_object" json:"object_id"`
+	GranteeType string    `gorm:"not null;size:20;uniqueIndex:idx_resource_grant" json:"grantee_type" example:"user"` // user/role
+	Grantee     string    `gorm:"not null;size:100;uniqueIndex:idx_resource_grant" json:"grantee" example:"zhangsan"`
+	Description string    `json:"description"`
+	CreatedAt   time.Time `json:"created_at"`
+	CreatedBy   string    `gorm:"size:100" json:"created_by"`
+}
+
+// BeforeCreate 创建前钩子
+func (g *ResourceGrant) BeforeCreate(tx *gorm.DB) error {
+	if g.ID == "" {
+		g.ID = uuid.New().String()
+	}
+	if g.CreatedBy == "" {
+		g.CreatedBy = "system"
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+/*
+ * @module service/models/reconciliation
+ * @description 元数据一致性核对模型，记录每次核对的报告以及目录与实际状态不一致的问题项和修复结果
+ * @architecture 分层架构 - 数据模型层
+ * @documentReference ai_docs/model.md
+ * @stateFlow 定时/手动核对 -> 生成报告和问题项(open) -> 修复 -> fixed/failed，修复前问题已消失时标记resolved
+ * @rules 每个问题项记录修复动作，修复只针对open和failed状态的问题项；报告的问题数和已修复数随修复结果更新
+ * @dependencies gorm.io/gorm, github.com/google/uuid
+ * @refs service/reconcile, api/controllers/reconciliation_controller.go
+ */
+
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// 核对触发方式
+const (
+	ReconcileTriggerScheduled = "scheduled"
+	ReconcileTriggerManual    = "manual"
+)
+
+// 核对问题类别
+const (
+	ReconcileCategoryMissingTable      = "missing_table"       // 接口标记已建表但物理表不存在
+	ReconcileCategoryDanglingLineage   = "dangling_lineage"    // 血缘关系指向已删除的接口
+	ReconcileCategoryOrphanLineage     = "orphan_lineage"      // 主题数据血缘记录指向已删除的接口
+	ReconcileCategoryStaleMaskingField = "stale_masking_field" // 脱敏配置引用了不存在的字段
+)
+
+// 核对问题修复动作
+const (
+	ReconcileFixResetTableFlag     = "reset_table_flag"     // 将接口标记为未建表
+	ReconcileFixDeactivateLineage  = "deactivate_lineage"   // 停用血缘关系
+	ReconcileFixDeleteLineage      = "delete_lineage"       // 删除主题数据血缘记录
+	ReconcileFixRemoveMaskingField = "remove_masking_field" // 从脱敏配置中移除字段
+)
+
+// 核对问题状态
+const (
+	ReconcileFindingOpen     = "open"
+	ReconcileFindingFixed    = "fixed"
+	ReconcileFindingResolved = "resolved" // 修复前问题已不存在
+	ReconcileFindingFailed   = "failed"
+)
+
+// ReconciliationReport 元数据一致性核对报告
+type ReconciliationReport struct {
+	ID            string     `json:"id" gorm:"primaryKey;type:varchar(36)"`
+	Trigger       string     `json:"trigger" gorm:"not null;size:20" example:"scheduled"` // scheduled, manual
+	StartedAt     time.Time  `json:"started_at" gorm:"not null;index"`
+	FinishedAt    *time.Time `json:"finished_at,omitempty"`
+	TotalFindings int        `json:"total_findings" gorm:"not null;default:0"`
+	FixedFindings int        `json:"fixed_findings" gorm:"not null;default:0"` // 已修复或已消失的问题数
+	Summary       JSONB      `json:"summary" gorm:"type:jsonb"`                // 按类别统计的问题数
+	CheckErrors   JSONB      `json:"check_errors,omitempty" gorm:"type:jsonb"` // 检查过程中出错的对象
+	CreatedBy     string     `json:"created_by" gorm:"size:100"`
+	CreatedAt     time.Time  `json:"created_at"`
+
+	Findings []ReconciliationFinding `json:"findings,omitempty" gorm:"foreignKey:ReportID"`
+}
+
+// BeforeCreate GORM钩子，创建前生成UUID
+func (r *ReconciliationReport) BeforeCreate(tx *gorm.DB) error {
+	if r.ID == "" {
+		r.ID = uuid.New().String()
+	}
+	if r.CreatedBy == "" {
+		r.CreatedBy = "system"
+	}
+	return nil
+}
+
+// ReconciliationFinding 元数据一致性核对问题项
+type ReconciliationFinding struct {
+	ID         string     `json:"id" gorm:"primaryKey;type:varchar(36)"`
+	ReportID   string     `json:"report_id" gorm:"not null;type:varchar(36);index"`
+	Category   string     `json:"category" gorm:"not null;size:30;index" example:"missing_table"`
+	ObjectType string     `json:"object_type" gorm:"not null;size:30" example:"interface"` // interface, thematic_interface, data_lineage, api_interface, thematic_sync_task
+	ObjectID   string     `json:"object_id" gorm:"not null;type:varchar(50);index"`
+	ObjectName string     `json:"object_name" gorm:"size:255"`
+	Message    string     `json:"message" gorm:"type:text"`
+	FixAction  string     `json:"fix_action" gorm:"not null;size:30" example:"reset_table_flag"`
+	Detail     JSONB      `json:"detail" gorm:"type:jsonb"` // 修复所需的参数，如缺失的字段
+	Status     string     `json:"status" gorm:"not null;size:20;default:'open';index" example:"open"`
+	FixError   string     `json:"fix_error,omitempty" gorm:"type:text"`
+	FixedBy    string     `json:"fixed_by,omitempty" gorm:"size:100"`
+	FixedAt    *time.Time `json:"fixed_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// BeforeCreate GORM钩子，创建前生成UUID
+func (f *ReconciliationFinding) BeforeCreate(tx *gorm.DB) error {
+	if f.ID == "" {
+		f.ID = uuid.New().String()
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+/*
+ * @module service/models/report_subscription
+ * @description 报表订阅模型，用户按日或按周订阅保存的查询或指标，服务按时生成文件并通过邮件或企业微信发送
+ * @architecture 分层架构 - 数据模型层
+ * @documentReference ai_docs/model.md
+ * @stateFlow 创建订阅 -> 计算下次发送时间 -> 到期生成文件并发送(succeeded/failed) -> 计算下次发送时间
+ * @rules 订阅以创建人的身份取数，敏感字段按创建人的角色脱敏；只有创建人和管理员可以查看和修改订阅；发送失败不补发，等待下一个周期
+ * @dependencies gorm.io/gorm, github.com/google/uuid
+ * @refs service/report, api/controllers/report_subscription_controller.go
+ */
+
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// 报表订阅的数据来源
+const (
+	ReportSourceSavedQuery = "saved_query" // SQL工作台保存的查询
+	ReportSourceMetric     = "metric"      // 指标层的指标值
+)
+
+// 报表文件格式
+const (
+	ReportFormatCSV  = "csv"
+	ReportFormatXLSX = "xlsx"
+)
+
+// 报表发送频率
+const (
+	ReportFrequencyDaily  = "daily"
+	ReportFrequencyWeekly = "weekly"
+)
+
+// 报表发送结果
+const (
+	ReportDeliverySucceeded = "succeeded"
+	ReportDeliveryFailed    = "failed"
+)
+
+// ReportSubscription 报表订阅
+type ReportSubscription struct {
+	ID           string           `json:"id" gorm:"primaryKey;type:varchar(36)"`
+	TenantID     string           `json:"tenant_id" gorm:"not null;size:36;default:'default';index"` // 所属租户
+	Name         string           `json:"name" gorm:"not null;size:255" example:"停车场每日进出明细"`
+	Description  string           `json:"description" gorm:"size:1000"`
+	SourceType   string           `json:"source_type" gorm:"not null;size:20" example:"saved_query"` // saved_query/metric
+	SourceID     string           `json:"source_id" gorm:"not null;type:varchar(36);index"`
+	Format       string           `json:"format" gorm:"not null;size:10;default:'xlsx'" example:"xlsx"` // csv/xlsx
+	Frequency    string           `json:"frequency" gorm:"not null;size:10" example:"daily"`            // daily/weekly
+	SendTime     string           `json:"send_time" gorm:"not null;size:5" example:"08:00"`             // 发送时间，服务所在时区的HH:MM
+	Weekday      int              `json:"weekday" gorm:"not null;default:1" example:"1"`                // 按周发送的星期，0为星期日
+	ChannelID    string           `json:"channel_id" gorm:"not null;type:varchar(36);index"`            // 邮件或企业微信通知渠道
+	Recipients   JSONBStringArray `json:"recipients" gorm:"type:jsonb"`                                 // 邮件收件人，为空时使用渠道配置的收件人
+	OwnerRoles   JSONBStringArray `json:"-" gorm:"type:jsonb"`                                          // 创建时的令牌角色，发送时与分配的角色合并
+	Enabled      bool             `json:"enabled" gorm:"not null"`
+	NextRunAt    *time.Time       `json:"next_run_at,omitempty" gorm:"index"` // 停用时为空
+	LastRunAt    *time.Time       `json:"last_run_at,omitempty"`
+	LastStatus   string           `json:"last_status,omitempty" gorm:"size:20"`
+	LastError    string           `json:"last_error,omitempty" gorm:"type:text"`
+	LastRowCount int              `json:"last_row_count" gorm:"not null;default:0"`
+	CreatedAt    time.Time        `json:"created_at" gorm:"not null;default:CURRENT_TIMESTAMP"`
+	CreatedBy    string           `json:"created_by" gorm:"not null;default:'system';size:100;index"`
+	UpdatedAt    time.Time        `json:"updated_at" gorm:"not null;default:CURRENT_TIMESTAMP"`
+	UpdatedBy    string           `json:"updated_by" gorm:"not null;default:'system';size:100"`
+}
+
+// TableName 指定表名
+func (ReportSubscription) TableName() string {
+	return "report_subscriptions"
+}
+
+// BeforeCreate GORM钩子，创建前生成UUID
+func (r *ReportSubscription) BeforeCreate(tx *gorm.DB) error {
+	if r.ID == "" {
+		r.ID = uuid.New().String()
+	}
+	return nil
+}
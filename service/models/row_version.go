@@ -0,0 +1,75 @@
+/*
+ * @module service/models/row_version
+ * @description 乐观并发控制，规则、任务、接口等可编辑对象通过row_version列检测并发修改
+ * @architecture 分层架构 - 数据模型层
+ * @documentReference ai_docs/model.md
+ * @stateFlow 读取对象(row_version=N) -> 携带N提交修改 -> 条件更新 version=N 并递增为N+1 / 版本不一致返回冲突
+ * @rules 期望版本为0表示调用方未携带版本，不做校验但仍递增版本；系统内部的运行状态更新不递增版本
+ * @dependencies gorm.io/gorm
+ * @refs api/controllers/row_version.go
+ */
+
+package models
+
+import (
+	"errors"
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// ErrVersionConflict 乐观锁版本冲突
+var ErrVersionConflict = errors.New("数据已被其他用户修改，请获取最新版本后重试")
+
+// VersionConflictError 版本冲突错误，携带当前最新版本
+type VersionConflictError struct {
+	Expected int64
+	Current  int64
+}
+
+// Error 实现error接口
+func (e *VersionConflictError) Error() string {
+	return fmt.Sprintf("%s（提交版本 %d，最新版本 %d）", ErrVersionConflict.Error(), e.Expected, e.Current)
+}
+
+// Unwrap 支持errors.Is(err, ErrVersionConflict)
+func (e *VersionConflictError) Unwrap() error {
+	return ErrVersionConflict
+}
+
+// BumpRowVersion 校验并递增对象版本，应在更新对象的同一事务中首先调用，返回递增后的版本
+func BumpRowVersion(tx *gorm.DB, model interface{}, id string, expectedVersion int64) (int64, error) {
+	query := tx.Model(model).Where("id = ?", id)
+	if expectedVersion > 0 {
+		query = query.Where("row_version = ?", expectedVersion)
+	}
+	result := query.Update("row_version", gorm.Expr("row_version + 1"))
+	if result.Error != nil {
+		return 0, result.Error
+	}
+
+	var current int64
+	if err := tx.Model(model).Where("id = ?", id).Select("row_version").Scan(&current).Error; err != nil {
+		return 0, err
+	}
+	if result.RowsAffected == 0 {
+		if current == 0 {
+			return 0, gorm.ErrRecordNotFound
+		}
+		return 0, &VersionConflictError{Expected: expectedVersion, Current: current}
+	}
+	return current, nil
+}
+
+// UpdateWithRowVersion 按版本条件更新对象字段并递增版本
+func UpdateWithRowVersion(db *gorm.DB, model interface{}, id string, expectedVersion int64, updates map[string]interface{}) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		if _, err := BumpRowVersion(tx, model, id, expectedVersion); err != nil {
+			return err
+		}
+		if len(updates) == 0 {
+			return nil
+		}
+		return tx.Model(model).Where("id = ?", id).Updates(updates).Error
+	})
+}
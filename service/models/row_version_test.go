@@ -0,0 +1,61 @@
+/*
+ * @module service/models/row_version_test
+ * @description 乐观并发控制测试，覆盖版本递增、版本冲突和对象不存在
+ * @architecture 测试层
+ * @documentReference ai_docs/model.md
+ * @stateFlow 创建对象 -> 按版本更新 -> 验证版本递增 / 旧版本更新返回冲突
+ * @rules 使用SQLite内存数据库，不依赖外部服务
+ * @dependencies testing, testify, gorm
+ * @refs row_version.go
+ */
+
+package models
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestUpdateWithRowVersion(t *testing.T) {
+	testDB := NewModelTestDB()
+	defer testDB.Close()
+	db := testDB.DB
+
+	rule := &QualityRuleTemplate{ID: "rule-1", Name: "非空检查", Type: "completeness", Category: "basic_quality", RuleLogic: JSONB{"check": "not_null"}}
+	require.NoError(t, db.Create(rule).Error)
+	require.NoError(t, db.First(rule, "id = ?", "rule-1").Error)
+	assert.Equal(t, int64(1), rule.RowVersion)
+
+	// 携带当前版本更新成功，版本递增
+	require.NoError(t, UpdateWithRowVersion(db, &QualityRuleTemplate{}, "rule-1", 1, map[string]interface{}{"name": "非空检查v2"}))
+	require.NoError(t, db.First(rule, "id = ?", "rule-1").Error)
+	assert.Equal(t, "非空检查v2", rule.Name)
+	assert.Equal(t, int64(2), rule.RowVersion)
+
+	// 携带旧版本更新返回冲突，且不修改数据
+	err := UpdateWithRowVersion(db, &QualityRuleTemplate{}, "rule-1", 1, map[string]interface{}{"name": "覆盖"})
+	require.ErrorIs(t, err, ErrVersionConflict)
+	var conflict *VersionConflictError
+	require.True(t, errors.As(err, &conflict))
+	assert.Equal(t, int64(1), conflict.Expected)
+	assert.Equal(t, int64(2), conflict.Current)
+	require.NoError(t, db.First(rule, "id = ?", "rule-1").Error)
+	assert.Equal(t, "非空检查v2", rule.Name)
+
+	// 未携带版本时不校验，但仍递增版本
+	require.NoError(t, UpdateWithRowVersion(db, &QualityRuleTemplate{}, "rule-1", 0, map[string]interface{}{"name": "非空检查v3"}))
+	require.NoError(t, db.First(rule, "id = ?", "rule-1").Error)
+	assert.Equal(t, int64(3), rule.RowVersion)
+}
+
+func TestBumpRowVersionNotFound(t *testing.T) {
+	testDB := NewModelTestDB()
+	defer testDB.Close()
+
+	_, err := BumpRowVersion(testDB.DB, &QualityRuleTemplate{}, "missing", 1)
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+}
@@ -0,0 +1,69 @@
+/*
+ * @module service/models/scd
+ * @description 缓慢变化维（SCD Type 2）历史追踪配置，接口开启后同步不再覆盖数据，而是按自然键为跟踪列的变化保留历史版本
+ * @architecture 分层架构 - 数据模型层
+ * @documentReference ai_docs/model.md
+ * @stateFlow 开启历史追踪 -> 表增加代理键、生效时间、失效时间、当前标记列，主键改为代理键 -> 同步按自然键合并版本 -> 关闭时删除历史版本并恢复自然键主键
+ * @rules 配置保存在接口配置的scd_config中；系统列以_scd_开头，不由同步数据写入；同一自然键最多一个当前版本
+ * @dependencies github.com/spf13/cast
+ * @refs service/basic_library/scd_service.go, service/interface_executor/scd.go
+ */
+
+package models
+
+import (
+	"strings"
+
+	"github.com/spf13/cast"
+)
+
+// SCDConfigKey 接口配置中历史追踪配置的键
+const SCDConfigKey = "scd_config"
+
+// 历史追踪系统列
+const (
+	SCDColumnPrefix    = "_scd_"
+	SCDKeyColumn       = "_scd_key"        // 代理键，每个版本一行
+	SCDValidFromColumn = "_scd_valid_from" // 版本生效时间
+	SCDValidToColumn   = "_scd_valid_to"   // 版本失效时间，当前版本为空
+	SCDCurrentColumn   = "_scd_current"    // 是否为当前版本
+)
+
+// SCDConfig 历史追踪配置
+type SCDConfig struct {
+	Enabled        bool     `json:"enabled"`
+	NaturalKeys    []string `json:"natural_keys"`    // 自然键，开启前的主键
+	TrackedColumns []string `json:"tracked_columns"` // 变化时产生新版本的列，为空表示除自然键外的全部列；其余列变化时直接更新当前版本
+}
+
+// ParseSCDConfig 从接口配置中读取启用的历史追踪配置，未配置或未启用时返回nil
+func ParseSCDConfig(interfaceConfig map[string]interface{}) *SCDConfig {
+	raw, ok := interfaceConfig[SCDConfigKey].(map[string]interface{})
+	if !ok || !cast.ToBool(raw["enabled"]) {
+		return nil
+	}
+	config := &SCDConfig{
+		Enabled:        true,
+		NaturalKeys:    cast.ToStringSlice(raw["natural_keys"]),
+		TrackedColumns: cast.ToStringSlice(raw["tracked_columns"]),
+	}
+	if len(config.NaturalKeys) == 0 {
+		return nil
+	}
+	return config
+}
+
+// IsSCDColumn 是否为历史追踪系统列
+func IsSCDColumn(name string) bool {
+	return strings.HasPrefix(name, SCDColumnPrefix)
+}
+
+// SCDSystemFields 历史追踪系统列的字段配置，orderBase之后依次排列
+func SCDSystemFields(orderBase int) []TableField {
+	return []TableField{
+		{NameZh: "版本代理键", NameEn: SCDKeyColumn, DataType: "uuid", IsPrimaryKey: true, IsUnique: true, DefaultValue: "gen_random_uuid()", Description: "历史追踪版本的代理键", OrderNum: orderBase + 1},
+		{NameZh: "生效时间", NameEn: SCDValidFromColumn, DataType: "timestamp", DefaultValue: "CURRENT_TIMESTAMP", Description: "版本开始生效的同步时间", OrderNum: orderBase + 2, IsIndexed: true},
+		{NameZh: "失效时间", NameEn: SCDValidToColumn, DataType: "timestamp", IsNullable: true, Description: "版本被新版本替代的同步时间，当前版本为空", OrderNum: orderBase + 3},
+		{NameZh: "当前版本", NameEn: SCDCurrentColumn, DataType: "boolean", DefaultValue: "false", Description: "是否为自然键的当前版本", OrderNum: orderBase + 4, IsIndexed: true},
+	}
+}
@@ -21,6 +21,7 @@ import (
 // ApiApplication API接入应用模型
 type ApiApplication struct {
 	ID                string    `gorm:"type:uuid;primary_key" json:"id"`
+	TenantID          string    `gorm:"not null;size:36;default:'default';index" json:"tenant_id"` // 所属租户
 	Name              string    `gorm:"not null;unique" json:"name"`
 	Path              string    `gorm:"not null;unique" json:"path"` // 应用访问路径，例如 "user-center"
 	ThematicLibraryID string    `gorm:"not null" json:"thematic_library_id"`
@@ -61,21 +62,41 @@ func (a *ApiApplication) BeforeUpdate(tx *gorm.DB) error {
 	return nil
 }
 
+// ApiKey状态
+const (
+	ApiKeyStatusActive   = "active"
+	ApiKeyStatusInactive = "inactive"
+	ApiKeyStatusRevoked  = "revoked"
+)
+
+// ApiKey授权范围
+const (
+	ApiKeyScopeShareRead   = "share:read"   // 通过共享数据API读取数据
+	ApiKeyScopeIngestWrite = "ingest:write" // 通过Webhook推送数据
+	ApiKeyScopeAll         = "*"            // 全部范围
+)
+
 // ApiKey API密钥模型 - 一个Key可以访问多个应用
 type ApiKey struct {
-	ID           string     `gorm:"type:uuid;primary_key" json:"id"`
-	Name         string     `gorm:"not null" json:"name"`              // ApiKey名称
-	KeyPrefix    string     `gorm:"not null;size:8" json:"key_prefix"` // Key的前缀，用于快速识别
-	KeyValueHash string     `gorm:"not null;unique" json:"-"`          // 存储Hash后的Key值
-	Description  string     `json:"description"`
-	Status       string     `gorm:"not null;default:'active'" json:"status"` // active, inactive, revoked
-	ExpiresAt    *time.Time `json:"expires_at"`
-	LastUsedAt   *time.Time `json:"last_used_at"`
-	UsageCount   int64      `gorm:"default:0" json:"usage_count"`
-	CreatedBy    string     `gorm:"size:100" json:"created_by"`
-	CreatedAt    time.Time  `json:"created_at"`
-	UpdatedAt    time.Time  `json:"updated_at"`
-	UpdatedBy    string     `gorm:"size:100" json:"updated_by"`
+	ID            string           `gorm:"type:uuid;primary_key" json:"id"`
+	Name          string           `gorm:"not null" json:"name"`              // ApiKey名称
+	KeyPrefix     string           `gorm:"not null;size:8" json:"key_prefix"` // Key的前缀，用于快速识别
+	KeyValueHash  string           `gorm:"not null;unique" json:"-"`          // 存储Hash后的Key值
+	Description   string           `json:"description"`
+	Status        string           `gorm:"not null;default:'active'" json:"status"` // active, inactive, revoked
+	Scopes        JSONBStringArray `gorm:"type:jsonb" json:"scopes"`                // 授权范围，如 share:read、ingest:write
+	ExpiresAt     *time.Time       `json:"expires_at"`
+	LastUsedAt    *time.Time       `json:"last_used_at"`
+	LastUsedIP    string           `gorm:"size:64" json:"last_used_ip"`
+	UsageCount    int64            `gorm:"default:0" json:"usage_count"`
+	RotatedFromID *string          `gorm:"type:uuid;index" json:"rotated_from_id"` // 轮换来源Key的ID
+	RevokedAt     *time.Time       `json:"revoked_at"`
+	RevokedBy     string           `gorm:"size:100" json:"revoked_by"`
+	RevokeReason  string           `json:"revoke_reason"`
+	CreatedBy     string           `gorm:"size:100" json:"created_by"`
+	CreatedAt     time.Time        `json:"created_at"`
+	UpdatedAt     time.Time        `json:"updated_at"`
+	UpdatedBy     string           `gorm:"size:100" json:"updated_by"`
 
 	// 多对多关系：一个ApiKey可以访问多个ApiApplication
 	Applications []ApiApplication `gorm:"many2many:api_key_applications;" json:"applications,omitempty"`
@@ -134,6 +155,7 @@ type ApiInterface struct {
 	MaskingRules        JSONB             `gorm:"type:jsonb" json:"masking_rules,omitempty"` // 数据脱敏规则配置
 	CreatedAt           time.Time         `json:"created_at"`
 	CreatedBy           string            `gorm:"size:100" json:"created_by"`
+	RowVersion          int64             `gorm:"not null;default:1" json:"row_version"` // 乐观锁版本
 	ApiApplication      ApiApplication    `gorm:"foreignKey:ApiApplicationID" json:"api_application,omitempty"`
 	ThematicInterface   ThematicInterface `gorm:"foreignKey:ThematicInterfaceID;constraint:OnDelete:RESTRICT" json:"thematic_interface,omitempty"`
 }
@@ -149,6 +171,62 @@ func (ai *ApiInterface) BeforeCreate(tx *gorm.DB) error {
 	return nil
 }
 
+// 共享接口灰度发布状态
+const (
+	ApiInterfaceReleaseStatusCanary     = "canary"      // 灰度中，仅灰度订阅方访问新版本
+	ApiInterfaceReleaseStatusPromoted   = "promoted"    // 已全量切换
+	ApiInterfaceReleaseStatusRolledBack = "rolled_back" // 已回退
+)
+
+// ApiInterfaceRelease 共享接口灰度发布模型 - 指定订阅方先访问新版本数据，其余订阅方继续访问旧版本
+type ApiInterfaceRelease struct {
+	ID                        string           `gorm:"type:uuid;primary_key" json:"id"`
+	ApiInterfaceID            string           `gorm:"not null;index" json:"api_interface_id"`
+	BaseThematicInterfaceID   string           `gorm:"not null" json:"base_thematic_interface_id"`   // 发布前的主题接口（旧版本）
+	TargetThematicInterfaceID string           `gorm:"not null" json:"target_thematic_interface_id"` // 新版本主题接口
+	CanaryApiKeyIDs           JSONBStringArray `gorm:"type:jsonb" json:"canary_api_key_ids"`         // 灰度订阅方的ApiKey ID
+	Status                    string           `gorm:"not null;size:20;index" json:"status"`         // canary/promoted/rolled_back
+	Description               string           `json:"description"`
+	ObserveUntil              *time.Time       `json:"observe_until"` // 观察期结束时间
+	PromotedAt                *time.Time       `json:"promoted_at"`
+	PromotedBy                string           `gorm:"size:100" json:"promoted_by"`
+	RolledBackAt              *time.Time       `json:"rolled_back_at"`
+	RolledBackBy              string           `gorm:"size:100" json:"rolled_back_by"`
+	RollbackReason            string           `json:"rollback_reason"`
+	CreatedAt                 time.Time        `gorm:"not null;default:CURRENT_TIMESTAMP" json:"created_at"`
+	CreatedBy                 string           `gorm:"not null;default:'system';size:100" json:"created_by"`
+	UpdatedAt                 time.Time        `gorm:"not null;default:CURRENT_TIMESTAMP" json:"updated_at"`
+	UpdatedBy                 string           `gorm:"not null;default:'system';size:100" json:"updated_by"`
+
+	// 关联关系
+	TargetThematicInterface ThematicInterface `gorm:"foreignKey:TargetThematicInterfaceID" json:"target_thematic_interface,omitempty"`
+}
+
+// BeforeCreate 创建前钩子
+func (r *ApiInterfaceRelease) BeforeCreate(tx *gorm.DB) error {
+	if r.ID == "" {
+		r.ID = uuid.New().String()
+	}
+	if r.Status == "" {
+		r.Status = ApiInterfaceReleaseStatusCanary
+	}
+	if r.CreatedBy == "" {
+		r.CreatedBy = "system"
+	}
+	if r.UpdatedBy == "" {
+		r.UpdatedBy = "system"
+	}
+	return nil
+}
+
+// BeforeUpdate 更新前钩子
+func (r *ApiInterfaceRelease) BeforeUpdate(tx *gorm.DB) error {
+	if r.UpdatedBy == "" {
+		r.UpdatedBy = "system"
+	}
+	return nil
+}
+
 // ApiRateLimit API调用限制模型 - 支持三层限流：全局/密钥/应用
 type ApiRateLimit struct {
 	ID            string          `gorm:"type:uuid;primary_key" json:"id"`
@@ -245,9 +323,11 @@ type DataAccessRequest struct {
 	ResourceID       string     `gorm:"not null" json:"resource_id"`
 	ResourceType     string     `gorm:"not null" json:"resource_type"` // thematic_library/basic_library/interface
 	RequestReason    string     `gorm:"not null" json:"request_reason"`
-	AccessPermission string     `gorm:"not null" json:"access_permission"` // read/write
-	ValidUntil       *time.Time `json:"valid_until"`
-	Status           string     `gorm:"not null;default:'pending'" json:"status"` // pending/approved/rejected/expired
+	Purpose          string     `gorm:"size:500" json:"purpose" example:"季度人口统计分析"`           // 使用目的，审批通过后绑定到授权
+	DurationDays     int        `gorm:"not null;default:0" json:"duration_days" example:"30"` // 申请的使用期限（天）
+	AccessPermission string     `gorm:"not null" json:"access_permission"`                    // read/write
+	ValidUntil       *time.Time `json:"valid_until"`                                          // 审批通过时按使用期限计算
+	Status           string     `gorm:"not null;default:'pending'" json:"status"`             // pending/approved/rejected/expired
 	ApprovalComment  *string    `json:"approval_comment"`
 	ApproverID       *string    `json:"approver_id"`
 	ApproverName     *string    `json:"approver_name"`
@@ -267,6 +347,53 @@ func (d *DataAccessRequest) BeforeCreate(tx *gorm.DB) error {
 	return nil
 }
 
+// 数据使用申请状态
+const (
+	DataAccessRequestPending  = "pending"
+	DataAccessRequestApproved = "approved"
+	DataAccessRequestRejected = "rejected"
+	DataAccessRequestExpired  = "expired" // 授权到期或被撤销
+)
+
+// 数据使用授权状态
+const (
+	DataAccessGrantActive  = "active"
+	DataAccessGrantExpired = "expired" // 到期自动回收
+	DataAccessGrantRevoked = "revoked" // 到期前人工撤销
+)
+
+// DataAccessGrant 数据使用授权，申请审批通过后生成，绑定使用目的和有效期，到期自动回收，记录保留供合规审计
+type DataAccessGrant struct {
+	ID               string     `gorm:"type:uuid;primary_key" json:"id"`
+	RequestID        string     `gorm:"type:uuid;not null;uniqueIndex" json:"request_id"`
+	RequesterID      string     `gorm:"not null;index" json:"requester_id"`
+	RequesterName    string     `json:"requester_name"`
+	ResourceType     string     `gorm:"not null;size:50;index:idx_data_access_grant_resource" json:"resource_type"`
+	ResourceID       string     `gorm:"not null;index:idx_data_access_grant_resource" json:"resource_id"`
+	AccessPermission string     `gorm:"not null;size:20" json:"access_permission"`
+	Purpose          string     `gorm:"size:500" json:"purpose"`
+	ValidFrom        time.Time  `gorm:"not null" json:"valid_from"`
+	ValidUntil       time.Time  `gorm:"not null;index" json:"valid_until"`
+	Status           string     `gorm:"not null;size:20;default:'active';index" json:"status"` // active/expired/revoked
+	GrantedBy        string     `gorm:"not null;size:100" json:"granted_by"`
+	RevokedAt        *time.Time `json:"revoked_at,omitempty"` // 到期回收或人工撤销的时间
+	RevokedBy        string     `gorm:"size:100" json:"revoked_by,omitempty"`
+	RevokeReason     string     `gorm:"size:500" json:"revoke_reason,omitempty"`
+	CreatedAt        time.Time  `gorm:"not null;default:CURRENT_TIMESTAMP" json:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at"`
+}
+
+// BeforeCreate 创建前钩子
+func (d *DataAccessGrant) BeforeCreate(tx *gorm.DB) error {
+	if d.ID == "" {
+		d.ID = uuid.New().String()
+	}
+	if d.Status == "" {
+		d.Status = DataAccessGrantActive
+	}
+	return nil
+}
+
 // ApiUsageLog API使用日志模型
 type ApiUsageLog struct {
 	ID            string          `gorm:"type:uuid;primary_key" json:"id"`
@@ -298,6 +425,49 @@ func (a *ApiUsageLog) BeforeCreate(tx *gorm.DB) error {
 	return nil
 }
 
+// RowLevelPolicy 行级安全策略模型 - 按消费方/角色限定可见的数据行
+type RowLevelPolicy struct {
+	ID             string     `gorm:"type:uuid;primary_key" json:"id"`
+	Name           string     `gorm:"not null;size:100" json:"name"`
+	Description    string     `json:"description"`
+	SchemaName     string     `gorm:"not null;size:100;index:idx_row_policy_table" json:"schema_name"` // 库英文名
+	TableName      string     `gorm:"not null;size:100;index:idx_row_policy_table" json:"table_name"`  // 表/接口英文名
+	SubjectType    string     `gorm:"not null;size:20;index" json:"subject_type"`                      // api_key/application/role/user
+	SubjectID      string     `gorm:"not null;size:100;index" json:"subject_id"`                       // 密钥ID、应用ID、角色名或用户名
+	Conditions     JSONBArray `gorm:"type:jsonb" json:"conditions"`                                    // 过滤条件列表 [{field, operator, value}]
+	ConditionLogic string     `gorm:"not null;size:10;default:'and'" json:"condition_logic"`           // and/or
+	IsEnabled      bool       `gorm:"not null;default:true" json:"is_enabled"`
+	CreatedAt      time.Time  `gorm:"not null;default:CURRENT_TIMESTAMP" json:"created_at"`
+	CreatedBy      string     `gorm:"not null;default:'system';size:100" json:"created_by"`
+	UpdatedAt      time.Time  `gorm:"not null;default:CURRENT_TIMESTAMP" json:"updated_at"`
+	UpdatedBy      string     `gorm:"not null;default:'system';size:100" json:"updated_by"`
+}
+
+// BeforeCreate 创建前钩子
+func (p *RowLevelPolicy) BeforeCreate(tx *gorm.DB) error {
+	if p.ID == "" {
+		p.ID = uuid.New().String()
+	}
+	if p.ConditionLogic == "" {
+		p.ConditionLogic = "and"
+	}
+	if p.CreatedBy == "" {
+		p.CreatedBy = "system"
+	}
+	if p.UpdatedBy == "" {
+		p.UpdatedBy = "system"
+	}
+	return nil
+}
+
+// BeforeUpdate 更新前钩子
+func (p *RowLevelPolicy) BeforeUpdate(tx *gorm.DB) error {
+	if p.UpdatedBy == "" {
+		p.UpdatedBy = "system"
+	}
+	return nil
+}
+
 // === 统计响应结构体 ===
 
 // RateLimitTypeStats 限流类型统计
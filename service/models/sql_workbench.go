@@ -0,0 +1,48 @@
+/*
+ * @module service/models/sql_workbench
+ * @description SQL工作台保存的查询，分析人员可保存常用语句并共享给同租户的其他用户
+ * @architecture 分层架构 - 数据模型层
+ * @documentReference ai_docs/model.md
+ * @stateFlow 创建（私有或共享） -> 执行时记录最近执行时间 -> 修改/删除
+ * @rules 只有创建人和管理员可以修改、删除；共享的查询对同租户用户可见并可执行；语句执行的审计写入系统日志（object_type=sql_workbench）
+ * @dependencies gorm.io/gorm, github.com/google/uuid
+ * @refs service/workbench, api/controllers/sql_workbench_controller.go
+ */
+
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// SavedQuery SQL工作台保存的查询
+type SavedQuery struct {
+	ID          string     `gorm:"type:uuid;primary_key" json:"id"`
+	TenantID    string     `gorm:"not null;size:36;default:'default';index" json:"tenant_id"` // 所属租户
+	Name        string     `gorm:"not null;size:255" json:"name" example:"近一天车辆进出"`
+	Description string     `gorm:"size:1000" json:"description"`
+	SQL         string     `gorm:"column:sql_text;type:text;not null" json:"sql" example:"SELECT plate_no, entered_at FROM parking.vehicle_access"`
+	IsShared    bool       `gorm:"not null;default:false" json:"is_shared"` // 是否共享给同租户的其他用户
+	LastRunAt   *time.Time `json:"last_run_at"`
+	CreatedAt   time.Time  `gorm:"not null;default:CURRENT_TIMESTAMP" json:"created_at"`
+	CreatedBy   string     `gorm:"not null;default:'system';size:100;index" json:"created_by"`
+	UpdatedAt   time.Time  `gorm:"not null;default:CURRENT_TIMESTAMP" json:"updated_at"`
+	UpdatedBy   string     `gorm:"not null;default:'system';size:100" json:"updated_by"`
+}
+
+// BeforeCreate 创建前钩子
+func (q *SavedQuery) BeforeCreate(tx *gorm.DB) error {
+	if q.ID == "" {
+		q.ID = uuid.New().String()
+	}
+	if q.CreatedBy == "" {
+		q.CreatedBy = "system"
+	}
+	if q.UpdatedBy == "" {
+		q.UpdatedBy = q.CreatedBy
+	}
+	return nil
+}
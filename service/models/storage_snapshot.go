@@ -0,0 +1,43 @@
+/*
+ * @module service/models/storage_snapshot
+ * @description 接口表存储快照模型，定期记录每个接口表的行数和占用空间，用于按基础库/主题库和租户统计存储增长
+ * @architecture 分层架构 - 数据模型层
+ * @documentReference ai_docs/model.md
+ * @stateFlow 定时采集 -> 每个已建表的接口写入一条快照 -> 按库/租户汇总报表 -> 超过保留期删除
+ * @rules 同一次采集的快照使用相同的采集时间；行数为PostgreSQL统计信息中的估算值；租户取接口所属库的租户
+ * @dependencies gorm.io/gorm, github.com/google/uuid
+ * @refs service/capacity, api/controllers/capacity_controller.go
+ */
+
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// StorageSnapshot 接口表存储快照
+type StorageSnapshot struct {
+	ID          string    `json:"id" gorm:"primaryKey;type:varchar(36)"`
+	TenantID    string    `json:"tenant_id" gorm:"not null;size:36;default:'default';index"`                                       // 所属租户
+	LibraryType string    `json:"library_type" gorm:"not null;size:20;index:idx_storage_snapshot_library" example:"basic_library"` // basic_library, thematic_library
+	LibraryID   string    `json:"library_id" gorm:"not null;type:varchar(36);index:idx_storage_snapshot_library"`
+	InterfaceID string    `json:"interface_id" gorm:"not null;type:varchar(36);index"`
+	SchemaName  string    `json:"schema_name" gorm:"not null;size:255"`
+	TableName   string    `json:"table_name" gorm:"not null;size:255"`
+	RowCount    int64     `json:"row_count" gorm:"not null;default:0"`   // 估算行数
+	TotalBytes  int64     `json:"total_bytes" gorm:"not null;default:0"` // 含索引和TOAST的总大小
+	TableBytes  int64     `json:"table_bytes" gorm:"not null;default:0"`
+	IndexBytes  int64     `json:"index_bytes" gorm:"not null;default:0"`
+	CapturedAt  time.Time `json:"captured_at" gorm:"not null;index"`
+}
+
+// BeforeCreate GORM钩子，创建前生成UUID
+func (s *StorageSnapshot) BeforeCreate(tx *gorm.DB) error {
+	if s.ID == "" {
+		s.ID = uuid.New().String()
+	}
+	return nil
+}
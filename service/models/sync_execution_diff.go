@@ -0,0 +1,78 @@
+/*
+ * @module service/models/sync_execution_diff
+ * @description 同步执行的数据变更摘要模型，记录一次增量同步中每个接口新增、更新、未变化、删除的行数，变更主键样例和变更最多的列
+ * @architecture 分层架构 - 数据模型层
+ * @documentReference ai_docs/model.md
+ * @stateFlow 增量同步写入前后按主键读取受影响的行 -> 比较得到变更摘要 -> 随执行结果按接口保存
+ * @rules 每次执行每个接口一条记录；主键样例最多保留SyncDiffKeySampleLimit个
+ * @dependencies gorm.io/gorm
+ * @refs service/interface_executor/sync_diff.go, service/basic_library/execution_diff_service.go
+ */
+
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"time"
+)
+
+// SyncDiffKeySampleLimit 新增、更新、删除主键样例的最大个数
+const SyncDiffKeySampleLimit = 100
+
+// SyncDiffKeys 主键样例，每个元素为主键列到取值的映射
+type SyncDiffKeys []map[string]interface{}
+
+// Scan 实现 Scanner 接口
+func (k *SyncDiffKeys) Scan(value interface{}) error {
+	return scanJSONValue(value, k)
+}
+
+// Value 实现 Valuer 接口
+func (k SyncDiffKeys) Value() (driver.Value, error) {
+	return json.Marshal(k)
+}
+
+// SyncColumnChange 一列在本次同步中被修改的行数
+type SyncColumnChange struct {
+	Column  string `json:"column"`
+	Changed int64  `json:"changed"`
+}
+
+// SyncColumnChanges 列变更统计，按修改行数倒序
+type SyncColumnChanges []SyncColumnChange
+
+// Scan 实现 Scanner 接口
+func (c *SyncColumnChanges) Scan(value interface{}) error {
+	return scanJSONValue(value, c)
+}
+
+// Value 实现 Valuer 接口
+func (c SyncColumnChanges) Value() (driver.Value, error) {
+	return json.Marshal(c)
+}
+
+// SyncExecutionDiff 一次同步执行中单个接口的数据变更摘要
+type SyncExecutionDiff struct {
+	ID            string            `json:"id" gorm:"primaryKey;type:varchar(36)"`
+	ExecutionID   string            `json:"execution_id" gorm:"not null;type:varchar(36);index"`
+	TaskID        string            `json:"task_id" gorm:"type:varchar(36)"`
+	InterfaceID   string            `json:"interface_id" gorm:"not null;type:varchar(36);index"`
+	RowsCompared  int64             `json:"rows_compared" gorm:"not null;default:0"` // 参与比较的写入行数（按主键去重后）
+	InsertedCount int64             `json:"inserted_count" gorm:"not null;default:0"`
+	UpdatedCount  int64             `json:"updated_count" gorm:"not null;default:0"`
+	UnchangedRows int64             `json:"unchanged_rows" gorm:"not null;default:0"` // 写入了但取值没有变化的行
+	InsertedKeys  SyncDiffKeys      `json:"inserted_keys" gorm:"type:jsonb"`
+	UpdatedKeys   SyncDiffKeys      `json:"updated_keys" gorm:"type:jsonb"`
+	DeletedCount  int64             `json:"deleted_count" gorm:"not null;default:0"` // 源端删除同步处理的行数
+	DeletedKeys   SyncDiffKeys      `json:"deleted_keys" gorm:"type:jsonb"`
+	KeysTruncated bool              `json:"keys_truncated" gorm:"not null;default:false"` // 主键样例是否被截断
+	ColumnChanges SyncColumnChanges `json:"column_changes" gorm:"type:jsonb"`
+	Incomplete    bool              `json:"incomplete" gorm:"not null;default:false"` // 部分批次读取变更失败，统计不完整
+	CreatedAt     time.Time         `json:"created_at"`
+}
+
+// TableName 指定表名
+func (SyncExecutionDiff) TableName() string {
+	return "sync_execution_diffs"
+}
@@ -95,6 +95,7 @@ func (sti *SyncTaskInterface) GetProgressPercent() string {
 // SyncTask 通用同步任务模型
 type SyncTask struct {
 	ID              string `json:"id" gorm:"primaryKey;type:varchar(36)" example:"550e8400-e29b-41d4-a716-446655440000"`
+	TenantID        string `json:"tenant_id" gorm:"not null;size:36;default:'default';index"`                                        // 所属租户
 	LibraryType     string `json:"library_type" gorm:"not null;size:20;index" example:"basic_library"`                               // basic_library, thematic_library
 	LibraryID       string `json:"library_id" gorm:"not null;type:varchar(36);index" example:"550e8400-e29b-41d4-a716-446655440000"` // 基础库ID或主题库ID
 	DataSourceID    string `json:"data_source_id" gorm:"not null;type:varchar(36);index" example:"550e8400-e29b-41d4-a716-446655440000"`
@@ -102,6 +103,10 @@ type SyncTask struct {
 	Status          string `json:"status" gorm:"not null;size:20;default:'draft'" example:"draft"`         // draft, active, paused (任务生命周期状态)
 	ExecutionStatus string `json:"execution_status" gorm:"not null;size:20;default:'idle'" example:"idle"` // idle, running, success, failed (任务执行状态)
 
+	// 同步任务模板，按模板创建并关联时记录，模板修改可同步到任务
+	TemplateID      *string `json:"template_id,omitempty" gorm:"type:varchar(36);index"`
+	TemplateVersion int64   `json:"template_version,omitempty" gorm:"default:0"` // 最近一次应用的模板版本
+
 	// 执行时机相关字段
 	TriggerType     string     `json:"trigger_type" gorm:"not null;size:20;default:'manual'" example:"manual"` // manual, once, interval, cron
 	CronExpression  string     `json:"cron_expression,omitempty" gorm:"size:100" example:"0 0 * * *"`          // Cron表达式
@@ -124,10 +129,11 @@ type SyncTask struct {
 	Result JSONB `json:"result,omitempty" gorm:"type:jsonb"` // 同步结果
 
 	// 基础字段
-	CreatedAt time.Time `json:"created_at" gorm:"not null;default:CURRENT_TIMESTAMP"`
-	CreatedBy string    `json:"created_by" gorm:"not null;default:'system';size:100" example:"system"`
-	UpdatedAt time.Time `json:"updated_at" gorm:"not null;default:CURRENT_TIMESTAMP"`
-	UpdatedBy string    `json:"updated_by" gorm:"not null;default:'system';size:100" example:"system"`
+	CreatedAt  time.Time `json:"created_at" gorm:"not null;default:CURRENT_TIMESTAMP"`
+	CreatedBy  string    `json:"created_by" gorm:"not null;default:'system';size:100" example:"system"`
+	UpdatedAt  time.Time `json:"updated_at" gorm:"not null;default:CURRENT_TIMESTAMP"`
+	UpdatedBy  string    `json:"updated_by" gorm:"not null;default:'system';size:100" example:"system"`
+	RowVersion int64     `json:"row_version" gorm:"not null;default:1"` // 乐观锁版本
 
 	// 动态关联 - 这些字段不存储在数据库中，在运行时根据LibraryType动态加载
 	BasicLibrary    *BasicLibrary    `json:"basic_library,omitempty" gorm:"-"`
@@ -0,0 +1,85 @@
+/*
+ * @module service/models/sync_task_template
+ * @description 同步任务模板，集中维护调度、批次参数、重试策略和质量门禁，新任务可按模板创建并关联模板，模板修改后可同步到关联的任务
+ * @architecture 分层架构 - 数据模型层
+ * @documentReference ai_docs/model.md
+ * @stateFlow 创建模板 -> 按模板创建任务(记录模板ID和版本) -> 修改模板(版本+1) -> 同步到关联任务(任务模板版本更新) / 任务解除关联
+ * @rules 模板版本即row_version，任务的template_version落后于模板版本表示任务未同步最新模板；
+ *        重试策略保存在同步任务配置的retry_policy中，只重试可重试的接口失败（网络超时、连接中断、限流等）
+ * @dependencies gorm.io/gorm, github.com/google/uuid, github.com/spf13/cast
+ * @refs service/basic_library/sync_task_template_service.go, service/basic_library/sync_task_service.go, api/controllers/sync_task_template_controller.go
+ */
+
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/spf13/cast"
+	"gorm.io/gorm"
+)
+
+// SyncRetryPolicyConfigKey 同步任务配置中重试策略的键
+const SyncRetryPolicyConfigKey = "retry_policy"
+
+// SyncRetryPolicy 同步任务的接口重试策略
+type SyncRetryPolicy struct {
+	MaxRetries      int `json:"max_retries"`      // 接口可重试失败的最大重试次数
+	IntervalSeconds int `json:"interval_seconds"` // 重试间隔秒数，第n次重试等待n倍间隔
+}
+
+// ParseSyncRetryPolicy 从同步任务配置中解析重试策略，未配置或最大重试次数为0时返回nil
+func ParseSyncRetryPolicy(config map[string]interface{}) *SyncRetryPolicy {
+	raw, ok := config[SyncRetryPolicyConfigKey].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	policy := &SyncRetryPolicy{MaxRetries: cast.ToInt(raw["max_retries"]), IntervalSeconds: cast.ToInt(raw["interval_seconds"])}
+	if policy.MaxRetries <= 0 {
+		return nil
+	}
+	return policy
+}
+
+// SyncTaskTemplate 同步任务模板
+type SyncTaskTemplate struct {
+	ID                   string    `json:"id" gorm:"primaryKey;type:varchar(36)"`
+	Name                 string    `json:"name" gorm:"not null;size:100;uniqueIndex"`
+	Description          string    `json:"description" gorm:"type:text"`
+	TaskType             string    `json:"task_type" gorm:"not null;size:20" example:"batch_sync"` // batch_sync, realtime_sync
+	TriggerType          string    `json:"trigger_type" gorm:"not null;size:20" example:"cron"`    // manual, interval, cron
+	CronExpression       string    `json:"cron_expression,omitempty" gorm:"size:100" example:"0 0 2 * * *"`
+	IntervalSeconds      int       `json:"interval_seconds,omitempty" gorm:"default:0"`
+	BatchConfig          JSONB     `json:"batch_config,omitempty" gorm:"type:jsonb"`         // 写入任务配置的批次参数，如batch_size、timeout
+	MaxRetries           int       `json:"max_retries" gorm:"not null;default:0"`            // 接口可重试失败的最大重试次数，0表示不重试
+	RetryIntervalSeconds int       `json:"retry_interval_seconds" gorm:"not null;default:0"` // 重试间隔秒数
+	QualityGateMinScore  *float64  `json:"quality_gate_min_score,omitempty"`                 // 质量门禁最低评分(0-100)，为空时不改变任务的质量门禁
+	RowVersion           int64     `json:"row_version" gorm:"not null;default:1"`            // 模板版本，每次修改递增
+	LinkedTasks          int64     `json:"linked_tasks" gorm:"-"`                            // 关联的任务数，查询时填充
+	CreatedAt            time.Time `json:"created_at" gorm:"not null;default:CURRENT_TIMESTAMP"`
+	CreatedBy            string    `json:"created_by" gorm:"not null;default:'system';size:100"`
+	UpdatedAt            time.Time `json:"updated_at" gorm:"not null;default:CURRENT_TIMESTAMP"`
+	UpdatedBy            string    `json:"updated_by" gorm:"not null;default:'system';size:100"`
+}
+
+// TableName 指定表名
+func (SyncTaskTemplate) TableName() string {
+	return "sync_task_templates"
+}
+
+// BeforeCreate GORM钩子，创建前生成UUID
+func (t *SyncTaskTemplate) BeforeCreate(tx *gorm.DB) error {
+	if t.ID == "" {
+		t.ID = uuid.New().String()
+	}
+	return nil
+}
+
+// RetryPolicy 模板的重试策略，不重试时返回nil
+func (t *SyncTaskTemplate) RetryPolicy() *SyncRetryPolicy {
+	if t.MaxRetries <= 0 {
+		return nil
+	}
+	return &SyncRetryPolicy{MaxRetries: t.MaxRetries, IntervalSeconds: t.RetryIntervalSeconds}
+}
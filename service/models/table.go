@@ -22,4 +22,28 @@ type TableField struct {
 	OrderNum         int    `json:"order_num" gorm:"not null"`
 	CheckConstraint  string `json:"check_constraint" gorm:"size:255"`
 	IsIncrementField bool   `json:"is_increment_field" gorm:"not null;default:false"` // 是否为增量字段，增量更新时根据这个字段判断条件
+	IsIndexed        bool   `json:"is_indexed" gorm:"not null;default:false"`         // 是否为该字段单独建立普通索引，主键和唯一字段已有索引时忽略
+	CompositeIndex   string `json:"composite_index,omitempty" gorm:"size:50"`         // 组合索引分组名，同组字段按OrderNum顺序组成一个组合索引
+	Expression       string `json:"expression,omitempty" gorm:"size:1000"`            // 派生列表达式，基于同表其他列的SQL表达式，如 length * width
+	ComputeMode      string `json:"compute_mode,omitempty" gorm:"size:20"`            // 派生列计算方式：generated(默认，数据库生成列)、sync(同步写入后刷新)
+	IsSensitive      bool   `json:"is_sensitive" gorm:"not null;default:false"`       // 是否为敏感字段，主题接口发布前须由同步任务的脱敏规则覆盖
+}
+
+// 派生列计算方式
+const (
+	ComputeModeGenerated = "generated" // PostgreSQL STORED生成列，表达式须为不可变函数
+	ComputeModeSync      = "sync"      // 普通列，每次同步写入后按表达式刷新，适用于依赖当前时间等非不可变表达式
+)
+
+// IsComputed 是否为派生列
+func (f TableField) IsComputed() bool {
+	return f.Expression != ""
+}
+
+// EffectiveComputeMode 派生列实际的计算方式，未配置时为generated
+func (f TableField) EffectiveComputeMode() string {
+	if f.ComputeMode == "" {
+		return ComputeModeGenerated
+	}
+	return f.ComputeMode
 }
@@ -0,0 +1,78 @@
+/*
+ * @module service/models/tenant
+ * @description 租户模型，一个部署承载多个运营主体时按租户隔离基础库、主题库、同步任务、治理规则和共享API
+ * @architecture 分层架构 - 数据模型层
+ * @documentReference ai_docs/model.md
+ * @stateFlow active（启用） <-> disabled（停用）；用户通过成员关系归属租户
+ * @rules 租户编码只允许小写字母、数字和下划线，作为接口表schema前缀，创建后不可修改；默认租户不可删除或停用
+ * @dependencies gorm.io/gorm, github.com/google/uuid
+ * @refs service/tenant, api/middleware/tenant.go
+ */
+
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// DefaultTenantID 默认租户ID，未启用多租户时所有数据归属默认租户
+const DefaultTenantID = "default"
+
+// 租户状态
+const (
+	TenantStatusActive   = "active"
+	TenantStatusDisabled = "disabled"
+)
+
+// Tenant 租户
+type Tenant struct {
+	ID          string    `gorm:"primaryKey;type:varchar(36)" json:"id"`
+	Code        string    `gorm:"not null;size:32;uniqueIndex" json:"code" example:"park_a"` // 租户编码，用作接口表schema前缀
+	Name        string    `gorm:"not null;size:255" json:"name" example:"A园区运营公司"`
+	Description string    `gorm:"size:1000" json:"description"`
+	Status      string    `gorm:"not null;size:20;default:'active'" json:"status"` // active/disabled
+	CreatedAt   time.Time `gorm:"not null;default:CURRENT_TIMESTAMP" json:"created_at"`
+	CreatedBy   string    `gorm:"not null;default:'system';size:100" json:"created_by"`
+	UpdatedAt   time.Time `gorm:"not null;default:CURRENT_TIMESTAMP" json:"updated_at"`
+	UpdatedBy   string    `gorm:"not null;default:'system';size:100" json:"updated_by"`
+}
+
+// BeforeCreate 创建前钩子
+func (t *Tenant) BeforeCreate(tx *gorm.DB) error {
+	if t.ID == "" {
+		t.ID = uuid.New().String()
+	}
+	if t.Status == "" {
+		t.Status = TenantStatusActive
+	}
+	if t.CreatedBy == "" {
+		t.CreatedBy = "system"
+	}
+	if t.UpdatedBy == "" {
+		t.UpdatedBy = t.CreatedBy
+	}
+	return nil
+}
+
+// TenantMember 租户成员
+type TenantMember struct {
+	ID        string    `gorm:"type:uuid;primary_key" json:"id"`
+	TenantID  string    `gorm:"not null;type:varchar(36);uniqueIndex:idx_tenant_member" json:"tenant_id"`
+	Username  string    `gorm:"not null;size:100;uniqueIndex:idx_tenant_member;index" json:"username"`
+	CreatedAt time.Time `json:"created_at"`
+	CreatedBy string    `gorm:"size:100" json:"created_by"`
+}
+
+// BeforeCreate 创建前钩子
+func (m *TenantMember) BeforeCreate(tx *gorm.DB) error {
+	if m.ID == "" {
+		m.ID = uuid.New().String()
+	}
+	if m.CreatedBy == "" {
+		m.CreatedBy = "system"
+	}
+	return nil
+}
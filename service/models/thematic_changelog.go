@@ -0,0 +1,58 @@
+/*
+ * @module service/models/thematic_changelog
+ * @description 主题接口变更日志模型，记录接口的变更日志策略，以及变更日志表的schema、表名和列定义，下游通过共享API按序号拉取增量
+ * @architecture 分层架构 - 数据模型层
+ * @documentReference ai_docs/model.md
+ * @stateFlow 开启变更日志 -> 在ChangelogSchema下建立接口的变更日志表 -> 主题同步写入时记录新增/修改/删除的主键和修改列 -> 超出保留天数的记录在同步后清理
+ * @rules 每个主题接口一条策略、一张变更日志表，表名为接口ID；变更日志只记录主键和修改的列名，不记录数据值；序号单调递增，作为下游拉取的游标
+ * @dependencies gorm.io/gorm
+ * @refs service/thematic_library/changelog.go, service/thematic_library/thematic_sync/changelog_recorder.go, service/sharing/changelog.go
+ */
+
+package models
+
+import (
+	"datahub-service/service/utils"
+	"time"
+)
+
+// ChangelogSchema 变更日志表所在的schema，与主题库schema隔离，不出现在数据查看的表列表中
+const ChangelogSchema = "datahub_changelog"
+
+// 变更日志操作
+const (
+	ChangelogOpInsert = "insert"
+	ChangelogOpUpdate = "update"
+	ChangelogOpDelete = "delete"
+)
+
+// ThematicChangelogPolicy 主题接口的变更日志策略
+type ThematicChangelogPolicy struct {
+	ThematicInterfaceID string    `json:"thematic_interface_id" gorm:"primaryKey;type:varchar(36)"`
+	Enabled             bool      `json:"enabled" gorm:"not null;default:false"`
+	RetentionDays       int       `json:"retention_days" gorm:"not null;default:7"` // 变更日志保留天数，超出的记录在同步后清理
+	CreatedBy           string    `json:"created_by" gorm:"size:100"`
+	UpdatedBy           string    `json:"updated_by" gorm:"size:100"`
+	CreatedAt           time.Time `json:"created_at"`
+	UpdatedAt           time.Time `json:"updated_at"`
+}
+
+// TableName 指定表名
+func (ThematicChangelogPolicy) TableName() string {
+	return "thematic_changelog_policies"
+}
+
+// ThematicChangelogEntry 变更日志表中的一条记录
+type ThematicChangelogEntry struct {
+	Seq            int64                  `json:"seq"`                       // 单调递增的序号，作为拉取游标
+	Op             string                 `json:"op"`                        // insert, update, delete
+	PK             map[string]interface{} `json:"pk"`                        // 主键字段 -> 取值
+	ChangedColumns []string               `json:"changed_columns,omitempty"` // 修改的列，只有update有
+	ExecutionID    string                 `json:"execution_id"`              // 产生变更的主题同步执行
+	ChangedAt      time.Time              `json:"changed_at"`
+}
+
+// ChangelogTable 主题接口变更日志表的完整表名（已加引号）
+func ChangelogTable(thematicInterfaceID string) string {
+	return utils.QuoteTable(ChangelogSchema, thematicInterfaceID)
+}
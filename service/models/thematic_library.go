@@ -21,8 +21,10 @@ import (
 // ThematicLibrary 数据主题库模型
 type ThematicLibrary struct {
 	ID              string     `json:"id" gorm:"primaryKey;type:varchar(36)"`
+	TenantID        string     `json:"tenant_id" gorm:"not null;size:36;default:'default';index"` // 所属租户
 	NameZh          string     `json:"name_zh" gorm:"not null;size:255"`
 	NameEn          string     `json:"name_en" gorm:"not null;unique;size:255"`
+	SchemaName      string     `json:"schema_name" gorm:"size:255"`      // 接口表所在schema，非默认租户为"租户编码_英文名称"
 	Category        string     `json:"category" gorm:"not null;size:50"` // business, technical, analysis, report
 	Domain          string     `json:"domain" gorm:"not null;size:50"`   // user, order, product, finance, marketing
 	Description     string     `json:"description" gorm:"size:1000"`
@@ -50,13 +52,15 @@ type ThematicInterface struct {
 	LibraryID         string    `json:"library_id" gorm:"not null;type:varchar(36);index"`
 	NameZh            string    `json:"name_zh" gorm:"not null;size:255"`
 	NameEn            string    `json:"name_en" gorm:"not null;size:255"`
-	Type              string    `json:"type" gorm:"not null;size:20"` // table, view
+	Type              string    `json:"type" gorm:"not null;size:20"`                              // table, view
+	StorageEngine     string    `json:"storage_engine" gorm:"not null;default:'postgres';size:20"` // 接口表存储引擎：postgres, clickhouse
 	Description       string    `json:"description" gorm:"size:1000"`
 	CreatedAt         time.Time `json:"created_at" gorm:"not null;default:CURRENT_TIMESTAMP"`
 	CreatedBy         string    `json:"created_by" gorm:"not null;default:'system';size:100"`
 	UpdatedAt         time.Time `json:"updated_at" gorm:"not null;default:CURRENT_TIMESTAMP"`
 	UpdatedBy         string    `json:"updated_by" gorm:"not null;default:'system';size:100"`
 	Status            string    `json:"status" gorm:"not null;default:'active';size:20"`
+	PublishStatus     string    `json:"publish_status" gorm:"not null;default:'published';size:20"` // draft, in_review, published；新建接口为draft，存量接口按已发布处理
 	IsTableCreated    bool      `json:"is_table_created" gorm:"not null;default:false"`
 	IsViewCreated     bool      `json:"is_view_created" gorm:"not null;default:false"`
 	ViewSQL           string    `json:"view_sql" gorm:"type:text"`
@@ -64,10 +68,22 @@ type ThematicInterface struct {
 	ParseConfig       JSONB     `json:"parse_config" gorm:"type:jsonb"`
 	TableFieldsConfig JSONB     `json:"table_fields_config" gorm:"type:jsonb"`
 	ViewConfig        JSONB     `json:"view_config" gorm:"type:jsonb"`
+	RowVersion        int64     `json:"row_version" gorm:"not null;default:1"` // 乐观锁版本
 	// 关联关系
 	ThematicLibrary ThematicLibrary `json:"thematic_library,omitempty" gorm:"foreignKey:LibraryID"`
 }
 
+// 主题接口表存储引擎
+const (
+	StorageEnginePostgres   = "postgres"   // 默认，接口表建在主库的主题库schema下
+	StorageEngineClickHouse = "clickhouse" // 高写入量的时序/物联网数据，接口表建在同名ClickHouse数据库下
+)
+
+// IsClickHouse 接口表是否存储在ClickHouse
+func (ti *ThematicInterface) IsClickHouse() bool {
+	return ti.StorageEngine == StorageEngineClickHouse
+}
+
 // DataFlowGraph 数据流程图模型
 type DataFlowGraph struct {
 	ID                  string                 `json:"id" gorm:"primaryKey;type:varchar(36)"`
@@ -101,6 +117,14 @@ type FlowNode struct {
 	DataFlowGraph DataFlowGraph `json:"data_flow_graph,omitempty" gorm:"foreignKey:FlowGraphID;constraint:OnDelete:CASCADE"`
 }
 
+// GetSchemaName 获取主题库接口表所在的schema，历史数据未记录时使用英文名称
+func (tl ThematicLibrary) GetSchemaName() string {
+	if tl.SchemaName != "" {
+		return tl.SchemaName
+	}
+	return tl.NameEn
+}
+
 // BeforeCreate GORM钩子，创建前生成UUID
 func (tl *ThematicLibrary) BeforeCreate(tx *gorm.DB) error {
 	if tl.ID == "" {
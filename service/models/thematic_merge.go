@@ -0,0 +1,100 @@
+/*
+ * @module service/models/thematic_merge
+ * @description 主题表多源写入合并模型，包括合并策略、记录来源和合并冲突日志
+ * @architecture 分层架构 - 数据模型层
+ * @documentReference ai_docs/model.md
+ * @stateFlow 多个同步任务写入同一主题表 -> 按合并策略和记录来源决定哪些字段可写 -> 写入后更新记录来源 -> 被拒绝的写入记为冲突 -> 人工评审
+ * @rules 每个主题接口最多一个合并策略；记录来源按主题接口和主键唯一；同一记录、同一落败任务和胜出任务的未处理冲突只保留一条，重复出现时累加次数
+ * @dependencies gorm.io/gorm, github.com/google/uuid
+ * @refs service/thematic_library/merge_policy.go, service/thematic_library/thematic_sync/merge_resolver.go
+ */
+
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// 合并策略
+const (
+	MergeStrategySourcePriority  = "source_priority"  // 按任务优先级，高优先级任务写入的记录不被低优先级任务覆盖
+	MergeStrategyLatestTimestamp = "latest_timestamp" // 按记录中的时间字段，较新的数据覆盖较旧的数据
+	MergeStrategyFieldPrecedence = "field_precedence" // 按字段分别指定任务优先级
+)
+
+// 合并冲突状态
+const (
+	MergeConflictOpen      = "open"      // 待评审
+	MergeConflictConfirmed = "confirmed" // 确认按策略处理正确
+	MergeConflictDismissed = "dismissed" // 忽略
+)
+
+// ThematicMergePolicy 主题接口的多源写入合并策略
+type ThematicMergePolicy struct {
+	ThematicInterfaceID string           `json:"thematic_interface_id" gorm:"primaryKey;type:varchar(36)"`
+	Enabled             bool             `json:"enabled" gorm:"not null;default:true"`
+	Strategy            string           `json:"strategy" gorm:"not null;size:30"`
+	SourcePriority      JSONBStringArray `json:"source_priority" gorm:"type:jsonb"`  // 同步任务ID，靠前的优先级高，未列出的任务优先级最低
+	TimestampField      string           `json:"timestamp_field" gorm:"size:100"`    // latest_timestamp时比较的字段（写入主题表的字段名）
+	FieldPrecedence     JSONB            `json:"field_precedence" gorm:"type:jsonb"` // field_precedence时字段名 -> 同步任务ID列表，未配置的字段使用source_priority
+	CreatedBy           string           `json:"created_by" gorm:"size:100"`
+	UpdatedBy           string           `json:"updated_by" gorm:"size:100"`
+	CreatedAt           time.Time        `json:"created_at"`
+	UpdatedAt           time.Time        `json:"updated_at"`
+}
+
+// TableName 指定表名
+func (ThematicMergePolicy) TableName() string {
+	return "thematic_merge_policies"
+}
+
+// ThematicRecordSource 主题表记录的来源，记录最后写入记录和各字段的同步任务
+type ThematicRecordSource struct {
+	ThematicInterfaceID string     `json:"thematic_interface_id" gorm:"primaryKey;type:varchar(36)"`
+	RecordKey           string     `json:"record_key" gorm:"primaryKey;size:500"` // 主键取值，复合主键以_连接
+	SourceTaskID        string     `json:"source_task_id" gorm:"not null;type:varchar(36);index"`
+	SourceTimestamp     *time.Time `json:"source_timestamp"`                // latest_timestamp时已写入数据的时间字段取值
+	FieldSources        JSONB      `json:"field_sources" gorm:"type:jsonb"` // 字段名 -> 写入该字段的同步任务ID
+	UpdatedAt           time.Time  `json:"updated_at"`
+}
+
+// TableName 指定表名
+func (ThematicRecordSource) TableName() string {
+	return "thematic_record_sources"
+}
+
+// ThematicMergeConflict 合并冲突，同步任务的写入因合并策略被拒绝
+type ThematicMergeConflict struct {
+	ID                  string           `json:"id" gorm:"primaryKey;type:varchar(36)"`
+	ThematicInterfaceID string           `json:"thematic_interface_id" gorm:"not null;type:varchar(36);index"`
+	RecordKey           string           `json:"record_key" gorm:"not null;size:500"`
+	Strategy            string           `json:"strategy" gorm:"not null;size:30"`
+	LosingTaskID        string           `json:"losing_task_id" gorm:"not null;type:varchar(36)"`  // 写入被拒绝的任务
+	WinningTaskID       string           `json:"winning_task_id" gorm:"not null;type:varchar(36)"` // 保留数据的任务
+	Fields              JSONBStringArray `json:"fields" gorm:"type:jsonb"`                         // 被拒绝的字段
+	IncomingValues      JSONB            `json:"incoming_values" gorm:"type:jsonb"`                // 最近一次被拒绝的取值
+	Occurrences         int              `json:"occurrences" gorm:"not null;default:1"`
+	LastExecutionID     string           `json:"last_execution_id" gorm:"type:varchar(36)"`
+	Status              string           `json:"status" gorm:"not null;size:20;default:'open';index"`
+	ReviewedBy          string           `json:"reviewed_by,omitempty" gorm:"size:100"`
+	ReviewNote          string           `json:"review_note,omitempty" gorm:"size:1000"`
+	ReviewedAt          *time.Time       `json:"reviewed_at,omitempty"`
+	FirstSeenAt         time.Time        `json:"first_seen_at"`
+	LastSeenAt          time.Time        `json:"last_seen_at"`
+}
+
+// TableName 指定表名
+func (ThematicMergeConflict) TableName() string {
+	return "thematic_merge_conflicts"
+}
+
+// BeforeCreate GORM钩子，创建前生成UUID
+func (c *ThematicMergeConflict) BeforeCreate(tx *gorm.DB) error {
+	if c.ID == "" {
+		c.ID = uuid.New().String()
+	}
+	return nil
+}
@@ -0,0 +1,122 @@
+/*
+ * @module service/models/thematic_publication
+ * @description 主题接口发布评审模型，记录一次发布申请的审批意见、发布检查结果和处理结论
+ * @architecture 分层架构 - 数据模型层
+ * @documentReference ai_docs/model.md
+ * @stateFlow 接口draft -> 提交评审(评审pending，接口in_review) -> 审批 -> 发布(检查通过，评审和接口published) / 驳回(评审rejected，接口回到draft)
+ * @rules 同一接口同时只有一个待处理的评审；提交人不能审批自己的申请；同一审批人只计一次
+ * @dependencies gorm.io/gorm, github.com/google/uuid
+ * @refs service/thematic_library/publication.go, api/controllers/thematic_publication_controller.go
+ */
+
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// 主题接口发布状态
+const (
+	ThematicPublishStatusDraft     = "draft"     // 草稿，不能被数据共享引用
+	ThematicPublishStatusInReview  = "in_review" // 评审中
+	ThematicPublishStatusPublished = "published" // 已发布，可被数据共享引用
+)
+
+// 发布评审状态
+const (
+	PublicationReviewPending   = "pending"
+	PublicationReviewPublished = "published"
+	PublicationReviewRejected  = "rejected"
+)
+
+// PublicationApproval 一条审批意见
+type PublicationApproval struct {
+	Reviewer   string    `json:"reviewer"`
+	Comment    string    `json:"comment,omitempty"`
+	ApprovedAt time.Time `json:"approved_at"`
+}
+
+// PublicationApprovals 审批意见列表
+type PublicationApprovals []PublicationApproval
+
+// Scan 实现 Scanner 接口
+func (a *PublicationApprovals) Scan(value interface{}) error {
+	return scanJSONValue(value, a)
+}
+
+// Value 实现 Valuer 接口
+func (a PublicationApprovals) Value() (driver.Value, error) {
+	return json.Marshal(a)
+}
+
+// PublicationGateResult 发布检查结果
+type PublicationGateResult struct {
+	Passed             bool      `json:"passed"`
+	QualityScore       *float64  `json:"quality_score"`                  // 接口同步任务最近一次成功执行的质量评分(0-100)，无执行记录时为空
+	QualityExecutionID string    `json:"quality_execution_id,omitempty"` // 评分来源的同步执行记录
+	MinQualityScore    float64   `json:"min_quality_score"`
+	QualityPassed      bool      `json:"quality_passed"`
+	SensitiveFields    []string  `json:"sensitive_fields"` // 字段配置中标记为敏感的字段
+	MaskedFields       []string  `json:"masked_fields"`    // 已被同步任务启用的脱敏规则覆盖的敏感字段
+	UnmaskedFields     []string  `json:"unmasked_fields"`
+	MaskingCoverage    float64   `json:"masking_coverage"` // 敏感字段脱敏覆盖率(0-100)，没有敏感字段时为100
+	MaskingPassed      bool      `json:"masking_passed"`
+	Reasons            []string  `json:"reasons,omitempty"` // 未通过的原因
+	EvaluatedAt        time.Time `json:"evaluated_at"`
+}
+
+// Scan 实现 Scanner 接口
+func (g *PublicationGateResult) Scan(value interface{}) error {
+	return scanJSONValue(value, g)
+}
+
+// Value 实现 Valuer 接口
+func (g PublicationGateResult) Value() (driver.Value, error) {
+	return json.Marshal(g)
+}
+
+// ThematicPublicationReview 主题接口发布评审
+type ThematicPublicationReview struct {
+	ID                  string                 `json:"id" gorm:"primaryKey;type:varchar(36)"`
+	ThematicInterfaceID string                 `json:"thematic_interface_id" gorm:"not null;type:varchar(36);index"`
+	Status              string                 `json:"status" gorm:"not null;size:20;index"` // pending/published/rejected
+	RequiredApprovals   int                    `json:"required_approvals" gorm:"not null;default:1"`
+	Approvals           PublicationApprovals   `json:"approvals" gorm:"type:jsonb"`
+	Gates               *PublicationGateResult `json:"gates,omitempty" gorm:"type:jsonb"` // 最近一次发布检查的结果
+	Comment             string                 `json:"comment" gorm:"size:1000"`          // 提交说明
+	SubmittedBy         string                 `json:"submitted_by" gorm:"not null;size:100"`
+	SubmittedAt         time.Time              `json:"submitted_at" gorm:"not null"`
+	RejectedBy          string                 `json:"rejected_by,omitempty" gorm:"size:100"`
+	RejectReason        string                 `json:"reject_reason,omitempty" gorm:"size:1000"`
+	PublishedBy         string                 `json:"published_by,omitempty" gorm:"size:100"`
+	ClosedAt            *time.Time             `json:"closed_at,omitempty"` // 发布或驳回时间
+	UpdatedAt           time.Time              `json:"updated_at"`
+}
+
+// TableName 指定表名
+func (ThematicPublicationReview) TableName() string {
+	return "thematic_publication_reviews"
+}
+
+// BeforeCreate GORM钩子，创建前生成UUID
+func (r *ThematicPublicationReview) BeforeCreate(tx *gorm.DB) error {
+	if r.ID == "" {
+		r.ID = uuid.New().String()
+	}
+	return nil
+}
+
+// HasApproved 审批人是否已审批
+func (r *ThematicPublicationReview) HasApproved(reviewer string) bool {
+	for _, approval := range r.Approvals {
+		if approval.Reviewer == reviewer {
+			return true
+		}
+	}
+	return false
+}
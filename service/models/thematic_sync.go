@@ -18,6 +18,7 @@ import (
 // ThematicSyncTask 主题同步任务模型
 type ThematicSyncTask struct {
 	ID                  string `json:"id" gorm:"primaryKey;type:varchar(36)"`
+	TenantID            string `json:"tenant_id" gorm:"not null;size:36;default:'default';index"` // 所属租户
 	ThematicLibraryID   string `json:"thematic_library_id" gorm:"not null;type:varchar(36);index"`
 	ThematicInterfaceID string `json:"thematic_interface_id" gorm:"not null;type:varchar(36);index"`
 	TaskName            string `json:"task_name" gorm:"not null;size:255"`
@@ -65,10 +66,11 @@ type ThematicSyncTask struct {
 	FailedSyncCount     int64 `json:"failed_sync_count" gorm:"default:0"`
 
 	// 审计字段
-	CreatedAt time.Time `json:"created_at"`
-	CreatedBy string    `json:"created_by" gorm:"size:100"`
-	UpdatedAt time.Time `json:"updated_at"`
-	UpdatedBy string    `json:"updated_by" gorm:"size:100"`
+	CreatedAt  time.Time `json:"created_at"`
+	CreatedBy  string    `json:"created_by" gorm:"size:100"`
+	UpdatedAt  time.Time `json:"updated_at"`
+	UpdatedBy  string    `json:"updated_by" gorm:"size:100"`
+	RowVersion int64     `json:"row_version" gorm:"not null;default:1"` // 乐观锁版本
 
 	// 关联关系
 	ThematicLibrary   *ThematicLibrary        `json:"thematic_library,omitempty" gorm:"foreignKey:ThematicLibraryID"`
@@ -88,7 +90,7 @@ type ThematicSyncExecution struct {
 	ExecutionType string `json:"execution_type" gorm:"not null;size:20"` // manual, scheduled, retry
 
 	// 执行状态
-	Status    string     `json:"status" gorm:"not null;default:'pending'"` // pending, running, success, failed, cancelled
+	Status    string     `json:"status" gorm:"not null;default:'pending'"` // pending, running, success, failed, cancelled, skipped
 	StartTime *time.Time `json:"start_time,omitempty"`
 	EndTime   *time.Time `json:"end_time,omitempty"`
 	Duration  int64      `json:"duration" gorm:"default:0"` // 执行时长（秒）
@@ -238,7 +240,7 @@ func (e *ThematicSyncExecution) GetDuration() time.Duration {
 
 // IsCompleted 判断执行是否完成
 func (e *ThematicSyncExecution) IsCompleted() bool {
-	return e.Status == "success" || e.Status == "failed" || e.Status == "cancelled"
+	return e.Status == "success" || e.Status == "failed" || e.Status == "cancelled" || e.Status == "skipped"
 }
 
 // IsSuccess 判断执行是否成功
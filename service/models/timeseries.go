@@ -0,0 +1,118 @@
+/*
+ * @module service/models/timeseries
+ * @description 时间序列存储配置，标记为时间序列的接口表建为TimescaleDB超表，按时间分块，并可配置压缩、保留策略和降采样连续聚合
+ * @architecture 分层架构 - 数据模型层
+ * @documentReference ai_docs/model.md
+ * @stateFlow 标记时间序列（时间列+标签列）-> 建表或开启时转换为超表 -> 配置压缩和保留策略 -> 按连续聚合配置创建降采样物化视图
+ * @rules 配置保存在接口配置的timeseries_config中；时间列和标签列在转换为超表后不能修改；间隔为"数字 单位"格式，如7 days；
+ *        连续聚合的物化视图名为"接口表名_聚合名"，按时间桶和标签列分组，指标列名为"列名_函数"
+ * @dependencies encoding/json, regexp
+ * @refs service/basic_library/timeseries_service.go, service/database/timeseries.go
+ */
+
+package models
+
+import (
+	"encoding/json"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TimeSeriesConfigKey 接口配置中时间序列存储配置的键
+const TimeSeriesConfigKey = "timeseries_config"
+
+// DefaultTimeSeriesChunkInterval 默认的分块时间间隔
+const DefaultTimeSeriesChunkInterval = "7 days"
+
+// 连续聚合支持的聚合函数
+const (
+	TimeSeriesFuncAvg   = "avg"
+	TimeSeriesFuncMin   = "min"
+	TimeSeriesFuncMax   = "max"
+	TimeSeriesFuncSum   = "sum"
+	TimeSeriesFuncCount = "count"
+	TimeSeriesFuncFirst = "first" // 时间桶内最早的值
+	TimeSeriesFuncLast  = "last"  // 时间桶内最新的值
+)
+
+// TimeSeriesIntervalPattern 间隔格式，如 30 minutes、1 hour、7 days
+var TimeSeriesIntervalPattern = regexp.MustCompile(`^[1-9][0-9]{0,5} (second|minute|hour|day|week|month|year)s?$`)
+
+// timeSeriesUnitSeconds 间隔单位对应的秒数，月按30天、年按365天近似
+var timeSeriesUnitSeconds = map[string]int64{
+	"second": 1, "minute": 60, "hour": 3600, "day": 86400, "week": 7 * 86400, "month": 30 * 86400, "year": 365 * 86400,
+}
+
+// ParseTimeSeriesInterval 把"数字 单位"格式的间隔换算为时长，用于比较间隔大小，格式不合法时返回false
+func ParseTimeSeriesInterval(interval string) (time.Duration, bool) {
+	if !TimeSeriesIntervalPattern.MatchString(interval) {
+		return 0, false
+	}
+	parts := strings.SplitN(interval, " ", 2)
+	n, _ := strconv.ParseInt(parts[0], 10, 64)
+	return time.Duration(n*timeSeriesUnitSeconds[strings.TrimSuffix(parts[1], "s")]) * time.Second, true
+}
+
+// TimeSeriesConfig 时间序列存储配置
+type TimeSeriesConfig struct {
+	Enabled         bool                  `json:"enabled"`
+	TimeColumn      string                `json:"time_column"`                // 时间列，超表按此列分块
+	TagColumns      []string              `json:"tag_columns"`                // 标签列，压缩时按标签分段，连续聚合按标签分组
+	ChunkInterval   string                `json:"chunk_interval"`             // 分块时间间隔
+	CompressAfter   string                `json:"compress_after,omitempty"`   // 超过该时间的分块自动压缩，为空时不压缩
+	RetentionPeriod string                `json:"retention_period,omitempty"` // 超过该时间的分块自动删除，为空时永久保留
+	Aggregates      []TimeSeriesAggregate `json:"aggregates,omitempty"`       // 降采样连续聚合
+	IngestEnabled   bool                  `json:"ingest_enabled"`             // 是否接收遥测快速写入
+	UpdatedBy       string                `json:"updated_by,omitempty"`
+	UpdatedAt       time.Time             `json:"updated_at"`
+}
+
+// TimeSeriesAggregate 降采样连续聚合配置
+type TimeSeriesAggregate struct {
+	Name               string             `json:"name"`                 // 聚合名，物化视图名为"接口表名_聚合名"
+	BucketInterval     string             `json:"bucket_interval"`      // 时间桶宽度
+	Metrics            []TimeSeriesMetric `json:"metrics"`              // 聚合指标
+	RefreshStartOffset string             `json:"refresh_start_offset"` // 刷新窗口起点距当前的时间
+	RefreshEndOffset   string             `json:"refresh_end_offset"`   // 刷新窗口终点距当前的时间，最近的未完整时间桶不刷新
+	ScheduleInterval   string             `json:"schedule_interval"`    // 刷新周期
+}
+
+// TimeSeriesMetric 聚合指标
+type TimeSeriesMetric struct {
+	Column   string `json:"column"`
+	Function string `json:"function"` // avg、min、max、sum、count、first、last
+}
+
+// Alias 指标在物化视图中的列名
+func (m TimeSeriesMetric) Alias() string {
+	return m.Column + "_" + m.Function
+}
+
+// ParseTimeSeriesConfig 从接口配置中读取启用的时间序列存储配置，未配置或未启用时返回nil
+func ParseTimeSeriesConfig(interfaceConfig map[string]interface{}) *TimeSeriesConfig {
+	raw, ok := interfaceConfig[TimeSeriesConfigKey]
+	if !ok || raw == nil {
+		return nil
+	}
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return nil
+	}
+	var config TimeSeriesConfig
+	if err := json.Unmarshal(data, &config); err != nil || !config.Enabled || config.TimeColumn == "" {
+		return nil
+	}
+	return &config
+}
+
+// FindAggregate 按名称查找连续聚合，不存在时返回-1
+func (c *TimeSeriesConfig) FindAggregate(name string) int {
+	for i, aggregate := range c.Aggregates {
+		if aggregate.Name == name {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,83 @@
+/*
+ * @module service/models/timeseries_rollup
+ * @description 时间序列降采样任务模型，按时间桶把时间序列接口的原始数据汇总到独立的汇总表，汇总表有自己的保留期，可注册为新的数据接口
+ * @architecture 分层架构 - 数据模型层
+ * @documentReference ai_docs/model.md
+ * @stateFlow 创建任务 -> 建汇总表(超表) -> 定时从水位线前的回看窗口起逐桶汇总 -> 推进水位线 -> 可注册为接口并自动建立血缘
+ * @rules 汇总表与源接口表在同一schema，表名为"接口表名_任务名"，时间列固定为bucket；时间桶和指标创建后不能修改；
+ *        水位线为已汇总的最后一个完整时间桶的结束时间，按数据库本地时间（不带时区）记录
+ * @dependencies gorm.io/gorm, github.com/google/uuid
+ * @refs service/basic_library/rollup_service.go, api/controllers/rollup_controller.go
+ */
+
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// RollupBucketColumn 汇总表的时间桶列
+const RollupBucketColumn = "bucket"
+
+// 降采样任务最近一次运行状态
+const (
+	RollupRunStatusSuccess = "success"
+	RollupRunStatusFailed  = "failed"
+)
+
+// TimeSeriesMetrics 聚合指标清单
+type TimeSeriesMetrics []TimeSeriesMetric
+
+// Scan 实现 Scanner 接口
+func (m *TimeSeriesMetrics) Scan(value interface{}) error {
+	return scanJSONValue(value, m)
+}
+
+// Value 实现 Valuer 接口
+func (m TimeSeriesMetrics) Value() (driver.Value, error) {
+	return json.Marshal(m)
+}
+
+// TimeSeriesRollup 时间序列降采样任务
+type TimeSeriesRollup struct {
+	ID                string            `json:"id" gorm:"primaryKey;type:varchar(36)"`
+	SourceInterfaceID string            `json:"source_interface_id" gorm:"not null;type:varchar(36);uniqueIndex:idx_timeseries_rollups_source_name,priority:1"`
+	Name              string            `json:"name" gorm:"not null;size:31;uniqueIndex:idx_timeseries_rollups_source_name,priority:2"`
+	RollupTable       string            `json:"rollup_table" gorm:"not null;size:63"`              // 汇总表名，与源接口表在同一schema
+	TimeColumn        string            `json:"time_column" gorm:"not null;size:255"`              // 源接口表的时间列
+	TagColumns        JSONBStringArray  `json:"tag_columns" gorm:"type:jsonb"`                     // 分组的标签列，取自源接口的时间序列配置
+	BucketInterval    string            `json:"bucket_interval" gorm:"not null;size:30"`           // 时间桶宽度
+	Metrics           TimeSeriesMetrics `json:"metrics" gorm:"type:jsonb"`                         // 聚合指标，汇总表列名为"列名_函数"
+	RetentionPeriod   string            `json:"retention_period" gorm:"size:30"`                   // 汇总表的保留时间，为空时永久保留
+	Lookback          string            `json:"lookback" gorm:"not null;size:30"`                  // 每次运行重新汇总水位线之前这段时间，容纳迟到数据
+	Enabled           bool              `json:"enabled" gorm:"not null"`                           // 不设列默认值，以便保存enabled=false
+	TargetInterfaceID string            `json:"target_interface_id" gorm:"type:varchar(36);index"` // 汇总表注册成的接口，未注册时为空
+	LineageID         string            `json:"lineage_id" gorm:"type:varchar(50)"`                // 注册时建立的血缘关系
+	Watermark         *time.Time        `json:"watermark,omitempty" gorm:"type:timestamp"`         // 已汇总到的时间，之前的完整时间桶已写入汇总表
+	LastRunAt         *time.Time        `json:"last_run_at,omitempty"`
+	LastStatus        string            `json:"last_status" gorm:"size:20"` // success/failed
+	LastError         string            `json:"last_error,omitempty" gorm:"type:text"`
+	LastRowCount      int64             `json:"last_row_count" gorm:"not null;default:0"` // 最近一次运行写入的汇总行数
+	CreatedAt         time.Time         `json:"created_at" gorm:"not null;default:CURRENT_TIMESTAMP"`
+	CreatedBy         string            `json:"created_by" gorm:"not null;default:'system';size:100"`
+	UpdatedAt         time.Time         `json:"updated_at" gorm:"not null;default:CURRENT_TIMESTAMP"`
+	UpdatedBy         string            `json:"updated_by" gorm:"not null;default:'system';size:100"`
+}
+
+// TableName 指定表名
+func (TimeSeriesRollup) TableName() string {
+	return "timeseries_rollups"
+}
+
+// BeforeCreate GORM钩子，创建前生成UUID
+func (r *TimeSeriesRollup) BeforeCreate(tx *gorm.DB) error {
+	if r.ID == "" {
+		r.ID = uuid.New().String()
+	}
+	return nil
+}
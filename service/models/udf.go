@@ -0,0 +1,151 @@
+/*
+ * @module service/models/udf
+ * @description 自定义函数(UDF)模型，管理员登记带签名的表达式函数，版本经评审后可在质量规则和清洗规则的逻辑中按名称引用
+ * @architecture 分层架构 - 数据模型层
+ * @documentReference ai_docs/model.md
+ * @stateFlow 新建版本(draft) -> 提交评审(pending_review，示例须全部通过) -> 审批(approved，成为当前版本)/驳回(rejected，修改后回到draft)
+ * @rules 函数名称全局唯一，不按租户隔离；已提交评审和已审批的版本不能修改；规则只能引用已审批的版本
+ * @dependencies gorm.io/gorm, github.com/google/uuid
+ * @refs service/udf, api/controllers/udf_controller.go
+ */
+
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// 自定义函数的用途
+const (
+	UDFKindQuality   = "quality"   // 质量检查，返回布尔值，true为通过
+	UDFKindCleansing = "cleansing" // 清洗转换，返回清洗后的值
+)
+
+// UDFLanguageExpression 沙箱表达式语言，目前唯一支持的函数语言
+const UDFLanguageExpression = "expression"
+
+// 自定义函数的参数和返回值类型
+const (
+	UDFTypeString  = "string"
+	UDFTypeNumber  = "number"
+	UDFTypeBoolean = "boolean"
+	UDFTypeAny     = "any"
+)
+
+// 自定义函数版本状态
+const (
+	UDFVersionDraft         = "draft"
+	UDFVersionPendingReview = "pending_review"
+	UDFVersionApproved      = "approved"
+	UDFVersionRejected      = "rejected"
+)
+
+// UDFParam 自定义函数的参数声明，字段值固定通过value传入，不在参数中声明
+type UDFParam struct {
+	Name        string      `json:"name" example:"max_length"`
+	Type        string      `json:"type" example:"number"` // string/number/boolean/any
+	Required    bool        `json:"required"`
+	Default     interface{} `json:"default,omitempty"`
+	Description string      `json:"description,omitempty"`
+}
+
+// UDFParams 参数声明列表
+type UDFParams []UDFParam
+
+// Scan 实现 Scanner 接口
+func (p *UDFParams) Scan(value interface{}) error {
+	return scanJSONValue(value, p)
+}
+
+// Value 实现 Valuer 接口
+func (p UDFParams) Value() (driver.Value, error) {
+	return json.Marshal(p)
+}
+
+// UDFExample 自定义函数的示例，提交评审和审批时逐个执行并与期望结果比较
+type UDFExample struct {
+	Value    interface{}            `json:"value"`
+	Args     map[string]interface{} `json:"args,omitempty"`
+	Expected interface{}            `json:"expected"`
+}
+
+// UDFExamples 示例列表
+type UDFExamples []UDFExample
+
+// Scan 实现 Scanner 接口
+func (e *UDFExamples) Scan(value interface{}) error {
+	return scanJSONValue(value, e)
+}
+
+// Value 实现 Valuer 接口
+func (e UDFExamples) Value() (driver.Value, error) {
+	return json.Marshal(e)
+}
+
+// UDFDefinition 自定义函数
+type UDFDefinition struct {
+	ID             string    `json:"id" gorm:"primaryKey;type:varchar(36)"`
+	Name           string    `json:"name" gorm:"not null;size:63;uniqueIndex" example:"valid_plate_no"` // 规则中引用的名称
+	DisplayName    string    `json:"display_name" gorm:"not null;size:255" example:"车牌号格式检查"`
+	Description    string    `json:"description" gorm:"size:1000"`
+	Kind           string    `json:"kind" gorm:"not null;size:20" example:"quality"`        // quality/cleansing
+	Language       string    `json:"language" gorm:"not null;size:20;default:'expression'"` // expression
+	CurrentVersion int       `json:"current_version" gorm:"not null;default:0"`             // 最近审批的版本号，0表示没有已审批的版本
+	CreatedAt      time.Time `json:"created_at" gorm:"not null;default:CURRENT_TIMESTAMP"`
+	CreatedBy      string    `json:"created_by" gorm:"not null;default:'system';size:100"`
+	UpdatedAt      time.Time `json:"updated_at" gorm:"not null;default:CURRENT_TIMESTAMP"`
+	UpdatedBy      string    `json:"updated_by" gorm:"not null;default:'system';size:100"`
+}
+
+// TableName 指定表名
+func (UDFDefinition) TableName() string {
+	return "udf_definitions"
+}
+
+// BeforeCreate GORM钩子，创建前生成UUID
+func (d *UDFDefinition) BeforeCreate(tx *gorm.DB) error {
+	if d.ID == "" {
+		d.ID = uuid.New().String()
+	}
+	return nil
+}
+
+// UDFVersion 自定义函数的一个版本
+type UDFVersion struct {
+	ID            string      `json:"id" gorm:"primaryKey;type:varchar(36)"`
+	UDFID         string      `json:"udf_id" gorm:"not null;type:varchar(36);uniqueIndex:idx_udf_version"`
+	Version       int         `json:"version" gorm:"not null;uniqueIndex:idx_udf_version" example:"1"`
+	Expression    string      `json:"expression" gorm:"type:text;not null" example:"matches(value, '^[沪京][A-Z][0-9A-Z]{5}$')"`
+	InputType     string      `json:"input_type" gorm:"not null;size:20;default:'any'" example:"string"` // 字段值value的类型
+	Params        UDFParams   `json:"params" gorm:"type:jsonb"`
+	ReturnType    string      `json:"return_type" gorm:"not null;size:20" example:"boolean"`
+	Examples      UDFExamples `json:"examples" gorm:"type:jsonb"`
+	Status        string      `json:"status" gorm:"not null;size:20;index" example:"draft"`
+	SubmittedBy   string      `json:"submitted_by,omitempty" gorm:"size:100"`
+	SubmittedAt   *time.Time  `json:"submitted_at,omitempty"`
+	ReviewedBy    string      `json:"reviewed_by,omitempty" gorm:"size:100"`
+	ReviewedAt    *time.Time  `json:"reviewed_at,omitempty"`
+	ReviewComment string      `json:"review_comment,omitempty" gorm:"size:1000"`
+	CreatedAt     time.Time   `json:"created_at" gorm:"not null;default:CURRENT_TIMESTAMP"`
+	CreatedBy     string      `json:"created_by" gorm:"not null;default:'system';size:100"`
+	UpdatedAt     time.Time   `json:"updated_at" gorm:"not null;default:CURRENT_TIMESTAMP"`
+	UpdatedBy     string      `json:"updated_by" gorm:"not null;default:'system';size:100"`
+}
+
+// TableName 指定表名
+func (UDFVersion) TableName() string {
+	return "udf_versions"
+}
+
+// BeforeCreate GORM钩子，创建前生成UUID
+func (v *UDFVersion) BeforeCreate(tx *gorm.DB) error {
+	if v.ID == "" {
+		v.ID = uuid.New().String()
+	}
+	return nil
+}
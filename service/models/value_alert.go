@@ -0,0 +1,115 @@
+/*
+ * @module service/models/value_alert
+ * @description 数据值告警模型，在基础库接口上配置针对入库数据的阈值或断流规则，命中时产生告警并推送到通知中心
+ * @architecture 分层架构 - 数据模型层
+ * @documentReference ai_docs/model.md
+ * @stateFlow 规则：启用 <-> 停用；告警：firing(规则命中) -> resolved(恢复正常)，同一规则同一分组同时只有一条firing告警
+ * @rules threshold规则对窗口内的数据按聚合函数计算后与阈值比较；offline规则在窗口内没有新数据时告警；
+ *        设置分组列时按分组（如设备ID）分别判定；告警级别info/warning/critical对应事件级别info/warn/error
+ * @dependencies gorm.io/gorm, github.com/google/uuid
+ * @refs service/basic_library/value_alert_service.go, api/controllers/value_alert_controller.go
+ */
+
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// 数据值告警规则类型
+const (
+	ValueAlertTypeThreshold = "threshold" // 窗口内聚合值与阈值比较
+	ValueAlertTypeOffline   = "offline"   // 窗口内没有新数据
+)
+
+// 数据值告警级别
+const (
+	ValueAlertSeverityInfo     = "info"
+	ValueAlertSeverityWarning  = "warning"
+	ValueAlertSeverityCritical = "critical"
+)
+
+// 数据值告警状态
+const (
+	ValueAlertStatusFiring   = "firing"
+	ValueAlertStatusResolved = "resolved"
+)
+
+// ValueAlertRule 数据值告警规则
+type ValueAlertRule struct {
+	ID              string     `json:"id" gorm:"primaryKey;type:varchar(36)"`
+	InterfaceID     string     `json:"interface_id" gorm:"not null;type:varchar(36);uniqueIndex:idx_value_alert_rules_interface_name,priority:1"`
+	Name            string     `json:"name" gorm:"not null;size:100;uniqueIndex:idx_value_alert_rules_interface_name,priority:2"`
+	RuleType        string     `json:"rule_type" gorm:"not null;size:20"`    // threshold/offline
+	TimeColumn      string     `json:"time_column" gorm:"not null;size:255"` // 判定窗口依据的时间列
+	Column          string     `json:"column" gorm:"size:255"`               // threshold规则比较的列
+	Aggregate       string     `json:"aggregate" gorm:"size:20"`             // threshold规则的聚合函数：avg/min/max/sum/count/last
+	Operator        string     `json:"operator" gorm:"size:10"`              // threshold规则的比较符：gt/gte/lt/lte/eq/ne
+	Threshold       *float64   `json:"threshold,omitempty"`                  // threshold规则的阈值
+	Window          string     `json:"window" gorm:"not null;size:30"`       // 判定窗口，如"5 minutes"
+	GroupColumn     string     `json:"group_column" gorm:"size:255"`         // 分组列，为空时对整个接口判定
+	Severity        string     `json:"severity" gorm:"not null;size:20"`     // info/warning/critical
+	Enabled         bool       `json:"enabled" gorm:"not null"`              // 不设列默认值，以便保存enabled=false
+	Description     string     `json:"description" gorm:"type:text"`
+	LastEvaluatedAt *time.Time `json:"last_evaluated_at,omitempty"`
+	LastError       string     `json:"last_error,omitempty" gorm:"type:text"` // 最近一次判定失败的原因，成功时清空
+	CreatedAt       time.Time  `json:"created_at" gorm:"not null;default:CURRENT_TIMESTAMP"`
+	CreatedBy       string     `json:"created_by" gorm:"not null;default:'system';size:100"`
+	UpdatedAt       time.Time  `json:"updated_at" gorm:"not null;default:CURRENT_TIMESTAMP"`
+	UpdatedBy       string     `json:"updated_by" gorm:"not null;default:'system';size:100"`
+}
+
+// TableName 指定表名
+func (ValueAlertRule) TableName() string {
+	return "value_alert_rules"
+}
+
+// BeforeCreate GORM钩子，创建前生成UUID
+func (r *ValueAlertRule) BeforeCreate(tx *gorm.DB) error {
+	if r.ID == "" {
+		r.ID = uuid.New().String()
+	}
+	return nil
+}
+
+// EventLevel 告警级别对应的应用事件级别
+func (r *ValueAlertRule) EventLevel() string {
+	switch r.Severity {
+	case ValueAlertSeverityCritical:
+		return EventLevelError
+	case ValueAlertSeverityWarning:
+		return EventLevelWarn
+	}
+	return EventLevelInfo
+}
+
+// ValueAlert 数据值告警，规则命中时创建，恢复后标记为resolved并保留为历史
+type ValueAlert struct {
+	ID          string     `json:"id" gorm:"primaryKey;type:varchar(36)"`
+	RuleID      string     `json:"rule_id" gorm:"not null;type:varchar(36);index;uniqueIndex:idx_value_alerts_firing,where:status = 'firing'"`
+	InterfaceID string     `json:"interface_id" gorm:"not null;type:varchar(36);index"`
+	GroupValue  string     `json:"group_value" gorm:"not null;default:'';size:255;uniqueIndex:idx_value_alerts_firing,where:status = 'firing'"` // 分组值，规则未分组时为空
+	Severity    string     `json:"severity" gorm:"not null;size:20"`
+	Status      string     `json:"status" gorm:"not null;size:20;index"` // firing/resolved
+	Value       *float64   `json:"value,omitempty"`                      // 触发时的聚合值，offline规则为空
+	LastSeenAt  *time.Time `json:"last_seen_at,omitempty"`               // offline规则触发时最近一条数据的时间
+	Message     string     `json:"message" gorm:"type:text"`
+	FiredAt     time.Time  `json:"fired_at" gorm:"not null"`
+	ResolvedAt  *time.Time `json:"resolved_at,omitempty"`
+}
+
+// TableName 指定表名
+func (ValueAlert) TableName() string {
+	return "value_alerts"
+}
+
+// BeforeCreate GORM钩子，创建前生成UUID
+func (a *ValueAlert) BeforeCreate(tx *gorm.DB) error {
+	if a.ID == "" {
+		a.ID = uuid.New().String()
+	}
+	return nil
+}
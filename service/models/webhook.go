@@ -0,0 +1,90 @@
+/*
+ * @module service/models/webhook
+ * @description 平台事件Webhook模型，外部系统登记接收地址、签名密钥和订阅的事件类型，每次推送记录一条投递日志
+ * @architecture 分层架构 - 数据模型层
+ * @documentReference ai_docs/model.md
+ * @stateFlow 应用事件 -> 映射为平台事件类型 -> 匹配启用的Webhook -> 创建投递(pending) -> delivering -> succeeded，失败时退避后重新pending，超过次数failed
+ * @rules 密钥只在创建和轮换时返回一次；事件类型为空表示订阅全部平台事件；同一平台事件在各Webhook的投递中使用相同的event_id，接收方据此去重
+ * @dependencies gorm.io/gorm, github.com/google/uuid
+ * @refs service/webhook, api/controllers/webhook_controller.go
+ */
+
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Webhook投递状态
+const (
+	WebhookDeliveryPending    = "pending"    // 等待投递或等待重试
+	WebhookDeliveryDelivering = "delivering" // 投递中
+	WebhookDeliverySucceeded  = "succeeded"
+	WebhookDeliveryFailed     = "failed" // 超过最大次数仍未成功
+)
+
+// WebhookEndpoint 外部系统登记的Webhook
+type WebhookEndpoint struct {
+	ID          string           `gorm:"type:uuid;primary_key" json:"id"`
+	Name        string           `gorm:"not null;size:100;uniqueIndex" json:"name"`
+	URL         string           `gorm:"not null;size:500" json:"url" example:"https://example.com/hooks/datahub"`
+	Secret      string           `gorm:"not null;size:100" json:"-"`                                            // 签名密钥
+	EventTypes  JSONBStringArray `gorm:"type:jsonb" json:"event_types" example:"task.succeeded,quality.failed"` // 为空订阅全部平台事件
+	IsEnabled   bool             `gorm:"not null" json:"is_enabled"`
+	Description string           `json:"description"`
+	CreatedAt   time.Time        `gorm:"not null;default:CURRENT_TIMESTAMP" json:"created_at"`
+	CreatedBy   string           `gorm:"not null;default:'system';size:100" json:"created_by"`
+	UpdatedAt   time.Time        `gorm:"not null;default:CURRENT_TIMESTAMP" json:"updated_at"`
+	UpdatedBy   string           `gorm:"not null;default:'system';size:100" json:"updated_by"`
+}
+
+// WebhookDelivery Webhook投递日志，记录推送内容和最近一次尝试的结果
+type WebhookDelivery struct {
+	ID             string     `gorm:"type:uuid;primary_key" json:"id"`
+	EndpointID     string     `gorm:"type:uuid;not null;index" json:"endpoint_id"`
+	EventID        string     `gorm:"type:uuid;not null;index" json:"event_id"`               // 平台事件ID，同一事件的各投递相同
+	EventType      string     `gorm:"not null;size:50;index" json:"event_type"`               // 平台事件类型，如task.succeeded
+	Payload        JSONB      `gorm:"type:jsonb" json:"payload"`                              // 推送的请求体
+	Status         string     `gorm:"not null;size:20;default:'pending';index" json:"status"` // pending/delivering/succeeded/failed
+	Attempts       int        `gorm:"not null;default:0" json:"attempts"`
+	NextAttemptAt  time.Time  `gorm:"not null;index" json:"next_attempt_at"` // 下次尝试时间，投递中时为租约到期时间
+	LastAttemptAt  *time.Time `json:"last_attempt_at,omitempty"`
+	ResponseStatus int        `json:"response_status,omitempty"`                // 最近一次响应的HTTP状态码
+	ResponseBody   string     `gorm:"type:text" json:"response_body,omitempty"` // 最近一次响应体，截断保存
+	DurationMs     int64      `json:"duration_ms"`                              // 最近一次请求耗时
+	Error          string     `gorm:"type:text" json:"error,omitempty"`
+	DeliveredAt    *time.Time `json:"delivered_at,omitempty"`
+	CreatedAt      time.Time  `gorm:"not null;default:CURRENT_TIMESTAMP;index" json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+// BeforeCreate 创建前钩子
+func (w *WebhookEndpoint) BeforeCreate(tx *gorm.DB) error {
+	if w.ID == "" {
+		w.ID = uuid.New().String()
+	}
+	if w.CreatedBy == "" {
+		w.CreatedBy = "system"
+	}
+	if w.UpdatedBy == "" {
+		w.UpdatedBy = "system"
+	}
+	return nil
+}
+
+// BeforeCreate 创建前钩子
+func (d *WebhookDelivery) BeforeCreate(tx *gorm.DB) error {
+	if d.ID == "" {
+		d.ID = uuid.New().String()
+	}
+	if d.Status == "" {
+		d.Status = WebhookDeliveryPending
+	}
+	if d.NextAttemptAt.IsZero() {
+		d.NextAttemptAt = time.Now()
+	}
+	return nil
+}
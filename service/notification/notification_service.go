@@ -0,0 +1,630 @@
+/*
+ * @module service/notification/notification_service
+ * @description 通知中心服务，监听同步、质量检测和备份校验等应用事件，按订阅规则生成站内通知并投递到Webhook、邮件、钉钉和企业微信渠道
+ * @architecture 分层架构 - 业务服务层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow eventlog.Record -> HandleEvent入队 -> 后台处理 -> 匹配订阅规则 -> 按模板渲染并写入站内通知 -> 按渠道类型和语言渲染后投递(失败重试) -> 更新投递状态
+ * @rules 事件入队不阻塞业务流程，队列满时丢弃并输出日志；同一事件匹配多个订阅时只生成一条通知，同一渠道只投递一次；停用的渠道和订阅不参与匹配；渠道被订阅引用时不能删除；文件只能通过邮件和企业微信渠道发送；渠道配置中的密钥引用在每次发送时解析
+ * @dependencies datahub-service/service/eventlog, datahub-service/service/models, datahub-service/service/secrets, gorm.io/gorm
+ * @refs service/notification/sender.go, service/notification/templates.go, service/models/notification.go, api/controllers/notification_controller.go
+ */
+
+package notification
+
+import (
+	"context"
+	"datahub-service/logger"
+	"datahub-service/service/eventlog"
+	"datahub-service/service/models"
+	"datahub-service/service/secrets"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// queueSize 待处理事件队列长度
+	queueSize = 1000
+	// maxDeliveryAttempts 单个渠道的最大投递次数
+	maxDeliveryAttempts = 3
+)
+
+// 通知中心错误
+var (
+	ErrChannelInUse       = errors.New("该渠道仍被订阅规则引用，请先删除或修改相关订阅")
+	ErrUnsupportedChannel = errors.New("不支持的通知渠道类型")
+	ErrReportChannelInUse = errors.New("该渠道仍被报表订阅引用，请先删除或修改相关订阅")
+	ErrFileNotSupported   = errors.New("该渠道不支持发送文件，请使用邮件或企业微信渠道")
+)
+
+// levelRank 事件级别排序，用于订阅的最低级别过滤
+var levelRank = map[string]int{
+	models.EventLevelInfo:  0,
+	models.EventLevelWarn:  1,
+	models.EventLevelError: 2,
+}
+
+// NotificationQuery 站内通知查询条件
+type NotificationQuery struct {
+	IsRead     *bool
+	Level      string
+	EventType  string
+	ObjectType string
+	ObjectID   string
+	Page       int
+	Size       int
+}
+
+// queuedEvent 待处理的事件
+type queuedEvent struct {
+	ctx   context.Context
+	event eventlog.Event
+	at    time.Time
+}
+
+// Service 通知中心服务
+type Service struct {
+	db         *gorm.DB
+	senders    map[string]Sender
+	queue      chan queuedEvent
+	retryDelay time.Duration
+	wg         sync.WaitGroup
+	ctx        context.Context
+	cancel     context.CancelFunc
+	startOnce  sync.Once
+}
+
+// NewService 创建通知中心服务实例
+func NewService(db *gorm.DB) *Service {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Service{
+		db:         db,
+		senders:    defaultSenders(),
+		queue:      make(chan queuedEvent, queueSize),
+		retryDelay: 2 * time.Second,
+		ctx:        ctx,
+		cancel:     cancel,
+	}
+}
+
+// Start 启动后台事件处理
+func (s *Service) Start() {
+	s.startOnce.Do(func() {
+		s.wg.Add(1)
+		go s.run()
+		slog.Info("通知中心已启动")
+	})
+}
+
+// Stop 停止后台事件处理，等待进行中的投递结束
+func (s *Service) Stop() {
+	s.cancel()
+	s.wg.Wait()
+}
+
+// HandleEvent 事件监听入口，注册到eventlog，只入队不阻塞调用方
+func (s *Service) HandleEvent(ctx context.Context, event eventlog.Event) {
+	item := queuedEvent{ctx: context.WithoutCancel(ctx), event: event, at: time.Now()}
+	select {
+	case s.queue <- item:
+	default:
+		slog.WarnContext(ctx, "通知队列已满，丢弃事件", "event_type", event.Type, "object_id", event.ObjectID)
+	}
+}
+
+func (s *Service) run() {
+	defer s.wg.Done()
+	for {
+		select {
+		case <-s.ctx.Done():
+			return
+		case item := <-s.queue:
+			if err := s.process(item.ctx, item.event, item.at); err != nil {
+				slog.ErrorContext(item.ctx, "处理通知事件失败", "event_type", item.event.Type, "object_id", item.event.ObjectID, "error", err)
+			}
+		}
+	}
+}
+
+// process 匹配订阅、生成站内通知并投递到渠道
+func (s *Service) process(ctx context.Context, event eventlog.Event, at time.Time) error {
+	subscriptions, err := s.matchSubscriptions(event)
+	if err != nil {
+		return fmt.Errorf("匹配订阅规则失败: %w", err)
+	}
+	if len(subscriptions) == 0 {
+		return nil
+	}
+
+	data := s.buildTemplateData(event, logger.RequestIDFromContext(ctx), at)
+	title, content := s.render(data, "", defaultLocale())
+	notification := &models.Notification{
+		EventType:  event.Type,
+		Level:      event.Level,
+		Title:      title,
+		Content:    content,
+		ObjectType: event.ObjectType,
+		ObjectID:   event.ObjectID,
+		ObjectName: data.ObjectName,
+		Link:       data.Link,
+		RequestID:  data.RequestID,
+		Attributes: event.Attributes,
+		CreatedAt:  at,
+	}
+	if err := s.db.Create(notification).Error; err != nil {
+		return fmt.Errorf("保存站内通知失败: %w", err)
+	}
+
+	channelIDs := make([]string, 0, len(subscriptions))
+	for _, sub := range subscriptions {
+		if sub.ChannelID != nil && !slices.Contains(channelIDs, *sub.ChannelID) {
+			channelIDs = append(channelIDs, *sub.ChannelID)
+		}
+	}
+	if len(channelIDs) == 0 {
+		return nil
+	}
+
+	var channels []models.NotifyChannel
+	if err := s.db.Where("id IN ? AND is_enabled = ?", channelIDs, true).Find(&channels).Error; err != nil {
+		return fmt.Errorf("获取通知渠道失败: %w", err)
+	}
+
+	var wg sync.WaitGroup
+	for i := range channels {
+		msg := toMessage(notification)
+		msg.Title, msg.Content = s.render(data, channels[i].Type, channels[i].Locale)
+		wg.Add(1)
+		go func(channel *models.NotifyChannel) {
+			defer wg.Done()
+			s.deliver(ctx, channel, msg)
+		}(&channels[i])
+	}
+	wg.Wait()
+	return nil
+}
+
+// matchSubscriptions 查找与事件匹配的启用订阅
+func (s *Service) matchSubscriptions(event eventlog.Event) ([]models.NotifySubscription, error) {
+	var candidates []models.NotifySubscription
+	err := s.db.Where("is_enabled = ?", true).
+		Where("object_type = '' OR object_type IS NULL OR object_type = ?", event.ObjectType).
+		Where("object_id = '' OR object_id IS NULL OR object_id = ?", event.ObjectID).
+		Find(&candidates).Error
+	if err != nil {
+		return nil, err
+	}
+
+	matched := make([]models.NotifySubscription, 0, len(candidates))
+	for _, sub := range candidates {
+		if len(sub.EventTypes) > 0 && !slices.Contains(sub.EventTypes, event.Type) {
+			continue
+		}
+		if levelRank[event.Level] < levelRank[sub.MinLevel] {
+			continue
+		}
+		matched = append(matched, sub)
+	}
+	return matched, nil
+}
+
+// deliver 投递到单个渠道，失败时重试
+func (s *Service) deliver(ctx context.Context, channel *models.NotifyChannel, msg Message) {
+	delivery := &models.NotifyDelivery{
+		NotificationID: msg.NotificationID,
+		ChannelID:      channel.ID,
+		ChannelType:    channel.Type,
+	}
+	if err := s.db.Create(delivery).Error; err != nil {
+		slog.ErrorContext(ctx, "创建通知投递记录失败", "channel_id", channel.ID, "error", err)
+		return
+	}
+
+	var sendErr error
+retry:
+	for attempt := 1; attempt <= maxDeliveryAttempts; attempt++ {
+		delivery.Attempts = attempt
+		if sendErr = s.send(s.ctx, channel, msg); sendErr == nil {
+			break
+		}
+		slog.WarnContext(ctx, "通知投递失败", "channel", channel.Name, "attempt", attempt, "error", sendErr)
+		if attempt == maxDeliveryAttempts {
+			break
+		}
+		select {
+		case <-s.ctx.Done():
+			break retry
+		case <-time.After(s.retryDelay * time.Duration(attempt)):
+		}
+	}
+
+	updates := map[string]interface{}{"attempts": delivery.Attempts}
+	if sendErr != nil {
+		updates["status"] = models.NotifyDeliveryFailed
+		updates["error"] = sendErr.Error()
+	} else {
+		updates["status"] = models.NotifyDeliverySent
+		updates["sent_at"] = time.Now()
+	}
+	if err := s.db.Model(&models.NotifyDelivery{}).Where("id = ?", delivery.ID).Updates(updates).Error; err != nil {
+		slog.ErrorContext(ctx, "更新通知投递记录失败", "delivery_id", delivery.ID, "error", err)
+	}
+}
+
+// send 使用渠道对应的发送器发送
+func (s *Service) send(ctx context.Context, channel *models.NotifyChannel, msg Message) error {
+	sender, ok := s.senders[channel.Type]
+	if !ok {
+		return ErrUnsupportedChannel
+	}
+	config, err := resolveChannelConfig(ctx, channel)
+	if err != nil {
+		return err
+	}
+	return sender.Send(ctx, config, msg)
+}
+
+// resolveChannelConfig 复制渠道配置并解析其中的密钥引用
+func resolveChannelConfig(ctx context.Context, channel *models.NotifyChannel) (models.JSONB, error) {
+	config, err := secrets.ResolveConfig(ctx, channel.Config)
+	if err != nil {
+		return nil, fmt.Errorf("解析渠道%s的配置失败: %w", channel.Name, err)
+	}
+	if config == nil {
+		return models.JSONB{}, nil
+	}
+	return config, nil
+}
+
+// toMessage 站内通知转换为渠道消息
+func toMessage(n *models.Notification) Message {
+	return Message{
+		NotificationID: n.ID,
+		EventType:      n.EventType,
+		Level:          n.Level,
+		Title:          n.Title,
+		Content:        n.Content,
+		ObjectType:     n.ObjectType,
+		ObjectID:       n.ObjectID,
+		ObjectName:     n.ObjectName,
+		Link:           n.Link,
+		Attributes:     n.Attributes,
+		CreatedAt:      n.CreatedAt,
+	}
+}
+
+// === 通知渠道管理 ===
+
+// validateChannel 校验渠道类型和配置
+func (s *Service) validateChannel(channel *models.NotifyChannel) error {
+	if strings.TrimSpace(channel.Name) == "" {
+		return errors.New("渠道名称不能为空")
+	}
+	sender, ok := s.senders[channel.Type]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrUnsupportedChannel, channel.Type)
+	}
+	if channel.Locale == "" {
+		channel.Locale = LocaleZh
+	}
+	if _, ok := builtinBodies[channel.Locale]; !ok {
+		return fmt.Errorf("不支持的语言: %s", channel.Locale)
+	}
+	if err := secrets.ValidateConfig(channel.Config); err != nil {
+		return err
+	}
+	return sender.Validate(channel.Config)
+}
+
+// CreateChannel 创建通知渠道
+func (s *Service) CreateChannel(channel *models.NotifyChannel) error {
+	if err := s.validateChannel(channel); err != nil {
+		return err
+	}
+	var count int64
+	if err := s.db.Model(&models.NotifyChannel{}).Where("name = ?", channel.Name).Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return fmt.Errorf("渠道名称已存在: %s", channel.Name)
+	}
+	return s.db.Create(channel).Error
+}
+
+// GetChannels 分页获取通知渠道
+func (s *Service) GetChannels(page, pageSize int, channelType string) ([]models.NotifyChannel, int64, error) {
+	db := s.db.Model(&models.NotifyChannel{})
+	if channelType != "" {
+		db = db.Where("type = ?", channelType)
+	}
+
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	page, pageSize = models.NormalizePage(page, pageSize)
+	var channels []models.NotifyChannel
+	err := db.Order("created_at DESC").Offset((page - 1) * pageSize).Limit(pageSize).Find(&channels).Error
+	return channels, total, err
+}
+
+// GetChannel 获取通知渠道
+func (s *Service) GetChannel(id string) (*models.NotifyChannel, error) {
+	var channel models.NotifyChannel
+	if err := s.db.First(&channel, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &channel, nil
+}
+
+// UpdateChannel 更新通知渠道，修改类型或配置时重新校验
+func (s *Service) UpdateChannel(id string, updates map[string]interface{}) error {
+	channel, err := s.GetChannel(id)
+	if err != nil {
+		return err
+	}
+	if value, ok := updates["name"].(string); ok {
+		channel.Name = value
+	}
+	if value, ok := updates["type"].(string); ok {
+		channel.Type = value
+	}
+	if value, ok := updates["config"].(models.JSONB); ok {
+		channel.Config = value
+	}
+	if value, ok := updates["locale"].(string); ok {
+		channel.Locale = value
+	}
+	if err := s.validateChannel(channel); err != nil {
+		return err
+	}
+
+	var count int64
+	if err := s.db.Model(&models.NotifyChannel{}).Where("name = ? AND id <> ?", channel.Name, id).Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return fmt.Errorf("渠道名称已存在: %s", channel.Name)
+	}
+	return s.db.Model(&models.NotifyChannel{}).Where("id = ?", id).Updates(updates).Error
+}
+
+// DeleteChannel 删除通知渠道
+func (s *Service) DeleteChannel(id string) error {
+	if _, err := s.GetChannel(id); err != nil {
+		return err
+	}
+	var count int64
+	if err := s.db.Model(&models.NotifySubscription{}).Where("channel_id = ?", id).Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrChannelInUse
+	}
+	if err := s.db.Model(&models.ReportSubscription{}).Where("channel_id = ?", id).Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrReportChannelInUse
+	}
+	return s.db.Delete(&models.NotifyChannel{}, "id = ?", id).Error
+}
+
+// TestChannel 向渠道发送一条测试消息，直接返回发送结果
+func (s *Service) TestChannel(ctx context.Context, id string) error {
+	channel, err := s.GetChannel(id)
+	if err != nil {
+		return err
+	}
+	return s.send(ctx, channel, Message{
+		EventType: "test",
+		Level:     models.EventLevelInfo,
+		Title:     "通知渠道测试",
+		Content:   fmt.Sprintf("渠道: %s\n这是一条测试消息", channel.Name),
+		CreatedAt: time.Now(),
+	})
+}
+
+// GetFileChannel 获取可发送文件的启用渠道
+func (s *Service) GetFileChannel(id string) (*models.NotifyChannel, error) {
+	channel, err := s.GetChannel(id)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := s.senders[channel.Type].(FileSender); !ok {
+		return nil, ErrFileNotSupported
+	}
+	if !channel.IsEnabled {
+		return nil, fmt.Errorf("渠道%s已停用", channel.Name)
+	}
+	return channel, nil
+}
+
+// SendFile 通过渠道发送带文件的消息，失败时重试；recipients不为空时替换邮件渠道配置的收件人
+func (s *Service) SendFile(ctx context.Context, channelID string, recipients []string, msg Message, file Attachment) error {
+	channel, err := s.GetFileChannel(channelID)
+	if err != nil {
+		return err
+	}
+	if len(file.Data) > maxAttachmentSize {
+		return fmt.Errorf("文件大小%d字节超过上限%d字节", len(file.Data), maxAttachmentSize)
+	}
+	config, err := resolveChannelConfig(ctx, channel)
+	if err != nil {
+		return err
+	}
+	if len(recipients) > 0 && channel.Type == models.NotifyChannelEmail {
+		config["recipients"] = recipients
+	}
+
+	sender := s.senders[channel.Type].(FileSender)
+	for attempt := 1; ; attempt++ {
+		if err = sender.SendFile(ctx, config, msg, file); err == nil || attempt == maxDeliveryAttempts {
+			return err
+		}
+		slog.WarnContext(ctx, "文件投递失败", "channel", channel.Name, "attempt", attempt, "error", err)
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(s.retryDelay * time.Duration(attempt)):
+		}
+	}
+}
+
+// === 订阅规则管理 ===
+
+// validateSubscription 校验订阅规则
+func (s *Service) validateSubscription(sub *models.NotifySubscription) error {
+	if strings.TrimSpace(sub.Name) == "" {
+		return errors.New("订阅名称不能为空")
+	}
+	if sub.MinLevel == "" {
+		sub.MinLevel = models.EventLevelWarn
+	}
+	if _, ok := levelRank[sub.MinLevel]; !ok {
+		return fmt.Errorf("无效的最低级别: %s", sub.MinLevel)
+	}
+	if sub.ChannelID != nil && *sub.ChannelID == "" {
+		sub.ChannelID = nil
+	}
+	if sub.ChannelID != nil {
+		if _, err := s.GetChannel(*sub.ChannelID); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return fmt.Errorf("通知渠道不存在: %s", *sub.ChannelID)
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// CreateSubscription 创建订阅规则
+func (s *Service) CreateSubscription(sub *models.NotifySubscription) error {
+	if err := s.validateSubscription(sub); err != nil {
+		return err
+	}
+	return s.db.Create(sub).Error
+}
+
+// GetSubscriptions 分页获取订阅规则
+func (s *Service) GetSubscriptions(page, pageSize int, channelID, objectType string) ([]models.NotifySubscription, int64, error) {
+	db := s.db.Model(&models.NotifySubscription{})
+	if channelID != "" {
+		db = db.Where("channel_id = ?", channelID)
+	}
+	if objectType != "" {
+		db = db.Where("object_type = ?", objectType)
+	}
+
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	page, pageSize = models.NormalizePage(page, pageSize)
+	var subs []models.NotifySubscription
+	err := db.Preload("Channel").Order("created_at DESC").Offset((page - 1) * pageSize).Limit(pageSize).Find(&subs).Error
+	return subs, total, err
+}
+
+// GetSubscription 获取订阅规则
+func (s *Service) GetSubscription(id string) (*models.NotifySubscription, error) {
+	var sub models.NotifySubscription
+	if err := s.db.Preload("Channel").First(&sub, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &sub, nil
+}
+
+// UpdateSubscription 更新订阅规则
+func (s *Service) UpdateSubscription(id string, sub *models.NotifySubscription) error {
+	existing, err := s.GetSubscription(id)
+	if err != nil {
+		return err
+	}
+	if err := s.validateSubscription(sub); err != nil {
+		return err
+	}
+	return s.db.Model(existing).Select("name", "channel_id", "object_type", "object_id", "event_types", "min_level", "is_enabled", "description", "updated_by", "updated_at").
+		Updates(sub).Error
+}
+
+// DeleteSubscription 删除订阅规则
+func (s *Service) DeleteSubscription(id string) error {
+	if _, err := s.GetSubscription(id); err != nil {
+		return err
+	}
+	return s.db.Delete(&models.NotifySubscription{}, "id = ?", id).Error
+}
+
+// === 站内通知 ===
+
+// ListNotifications 按条件分页查询站内通知，按时间倒序
+func (s *Service) ListNotifications(query NotificationQuery) ([]models.Notification, int64, error) {
+	db := s.db.Model(&models.Notification{})
+	if query.IsRead != nil {
+		db = db.Where("is_read = ?", *query.IsRead)
+	}
+	if query.Level != "" {
+		db = db.Where("level = ?", query.Level)
+	}
+	if query.EventType != "" {
+		db = db.Where("event_type = ?", query.EventType)
+	}
+	if query.ObjectType != "" {
+		db = db.Where("object_type = ?", query.ObjectType)
+	}
+	if query.ObjectID != "" {
+		db = db.Where("object_id = ?", query.ObjectID)
+	}
+
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	page, size := models.NormalizePage(query.Page, query.Size)
+	var notifications []models.Notification
+	err := db.Order("created_at DESC").Offset((page - 1) * size).Limit(size).Find(&notifications).Error
+	return notifications, total, err
+}
+
+// GetNotification 获取站内通知及其渠道投递记录
+func (s *Service) GetNotification(id string) (*models.Notification, error) {
+	var notification models.Notification
+	if err := s.db.Preload("Deliveries").First(&notification, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &notification, nil
+}
+
+// MarkRead 标记通知为已读
+func (s *Service) MarkRead(id, username string) error {
+	result := s.db.Model(&models.Notification{}).Where("id = ?", id).
+		Updates(map[string]interface{}{"is_read": true, "read_at": time.Now(), "read_by": username})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// MarkAllRead 标记全部未读通知为已读，返回标记数量
+func (s *Service) MarkAllRead(username string) (int64, error) {
+	result := s.db.Model(&models.Notification{}).Where("is_read = ?", false).
+		Updates(map[string]interface{}{"is_read": true, "read_at": time.Now(), "read_by": username})
+	return result.RowsAffected, result.Error
+}
+
+// UnreadCount 未读通知数量
+func (s *Service) UnreadCount() (int64, error) {
+	var count int64
+	err := s.db.Model(&models.Notification{}).Where("is_read = ?", false).Count(&count).Error
+	return count, err
+}
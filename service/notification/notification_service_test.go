@@ -0,0 +1,359 @@
+/*
+ * @module service/notification/notification_service_test
+ * @description 通知中心测试，覆盖订阅匹配、站内通知、渠道投递与重试、钉钉加签、邮件附件和企业微信文件发送、渠道配置校验和已读标记
+ * @architecture 测试层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow 创建渠道和订阅 -> 处理应用事件 -> 验证站内通知、投递记录和渠道收到的消息
+ * @rules 使用内存sqlite和httptest服务，不依赖外部渠道；邮件发送使用桩函数
+ * @dependencies gorm.io/driver/sqlite, stretchr/testify
+ * @refs service/notification/notification_service.go, service/notification/sender.go
+ */
+
+package notification
+
+import (
+	"bytes"
+	"context"
+	"datahub-service/service/eventlog"
+	"datahub-service/service/models"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/mail"
+	"net/smtp"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+func setupNotificationService(t *testing.T) *Service {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: gormlogger.Default.LogMode(gormlogger.Silent),
+	})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&models.NotifyChannel{}, &models.NotifySubscription{}, &models.Notification{}, &models.NotifyDelivery{}, &models.NotifyTemplate{}, &models.ReportSubscription{}))
+
+	s := NewService(db)
+	s.retryDelay = 0
+	t.Cleanup(s.Stop)
+	return s
+}
+
+// recordingServer 记录收到的请求体，按顺序返回预设状态码
+type recordingServer struct {
+	mu       sync.Mutex
+	bodies   []string
+	queries  []string
+	statuses []int
+	response string
+}
+
+func (rs *recordingServer) handler(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+	rs.bodies = append(rs.bodies, string(body))
+	rs.queries = append(rs.queries, r.URL.RawQuery)
+	status := http.StatusOK
+	if len(rs.statuses) > 0 {
+		status = rs.statuses[0]
+		rs.statuses = rs.statuses[1:]
+	}
+	w.WriteHeader(status)
+	_, _ = w.Write([]byte(rs.response))
+}
+
+func newRecordingServer(t *testing.T, response string, statuses ...int) (*recordingServer, string) {
+	rs := &recordingServer{statuses: statuses, response: response}
+	server := httptest.NewServer(http.HandlerFunc(rs.handler))
+	t.Cleanup(server.Close)
+	return rs, server.URL
+}
+
+func createChannel(t *testing.T, s *Service, name, channelType string, config models.JSONB) *models.NotifyChannel {
+	channel := &models.NotifyChannel{Name: name, Type: channelType, Config: config, IsEnabled: true}
+	require.NoError(t, s.CreateChannel(channel))
+	return channel
+}
+
+func createSubscription(t *testing.T, s *Service, sub models.NotifySubscription) *models.NotifySubscription {
+	sub.IsEnabled = true
+	require.NoError(t, s.CreateSubscription(&sub))
+	return &sub
+}
+
+func syncFailedEvent(taskID string) eventlog.Event {
+	return eventlog.Event{
+		Type:       eventlog.EventTaskFailed,
+		Level:      models.EventLevelError,
+		Message:    "基础库同步任务执行失败",
+		ObjectType: "sync_task",
+		ObjectID:   taskID,
+		Attributes: map[string]interface{}{"execution_id": "exec-1", "error": "连接超时"},
+	}
+}
+
+func TestProcessMatchesSubscriptions(t *testing.T) {
+	s := setupNotificationService(t)
+	ctx := context.Background()
+	rs, endpoint := newRecordingServer(t, "")
+	channel := createChannel(t, s, "运维Webhook", models.NotifyChannelWebhook, models.JSONB{"url": endpoint})
+
+	// 两条订阅都匹配同一事件和渠道，只生成一条通知、投递一次
+	createSubscription(t, s, models.NotifySubscription{Name: "同步失败", ChannelID: &channel.ID, ObjectType: "sync_task", EventTypes: []string{eventlog.EventTaskFailed}})
+	createSubscription(t, s, models.NotifySubscription{Name: "全部错误", ChannelID: &channel.ID, MinLevel: models.EventLevelError})
+	// 其他任务和事件类型的订阅不匹配
+	createSubscription(t, s, models.NotifySubscription{Name: "其他任务", ChannelID: &channel.ID, ObjectID: "task-2"})
+	createSubscription(t, s, models.NotifySubscription{Name: "备份损坏", ChannelID: &channel.ID, EventTypes: []string{eventlog.EventBackupCorrupt}})
+
+	require.NoError(t, s.process(ctx, syncFailedEvent("task-1"), time.Now()))
+
+	notifications, total, err := s.ListNotifications(NotificationQuery{})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), total)
+	assert.Equal(t, "同步任务失败：task-1", notifications[0].Title)
+	assert.Contains(t, notifications[0].Content, "原因：连接超时")
+	assert.Contains(t, notifications[0].Content, "执行ID：exec-1")
+
+	require.Len(t, rs.bodies, 1)
+	var msg Message
+	require.NoError(t, json.Unmarshal([]byte(rs.bodies[0]), &msg))
+	assert.Equal(t, notifications[0].ID, msg.NotificationID)
+	assert.Equal(t, eventlog.EventTaskFailed, msg.EventType)
+
+	detail, err := s.GetNotification(notifications[0].ID)
+	require.NoError(t, err)
+	require.Len(t, detail.Deliveries, 1)
+	assert.Equal(t, models.NotifyDeliverySent, detail.Deliveries[0].Status)
+	assert.Equal(t, 1, detail.Deliveries[0].Attempts)
+}
+
+func TestProcessSkipsBelowMinLevelAndDisabled(t *testing.T) {
+	s := setupNotificationService(t)
+	ctx := context.Background()
+	createSubscription(t, s, models.NotifySubscription{Name: "警告以上"})
+	disabled := createSubscription(t, s, models.NotifySubscription{Name: "全部", MinLevel: models.EventLevelInfo})
+	require.NoError(t, s.db.Model(disabled).Update("is_enabled", false).Error)
+
+	require.NoError(t, s.process(ctx, eventlog.Event{
+		Type: eventlog.EventTaskCompleted, Level: models.EventLevelInfo, Message: "完成", ObjectType: "sync_task", ObjectID: "task-1",
+	}, time.Now()))
+	count, err := s.UnreadCount()
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), count)
+
+	// 未指定渠道的订阅只生成站内通知
+	require.NoError(t, s.process(ctx, eventlog.Event{
+		Type: eventlog.EventQualityIssuesFound, Level: models.EventLevelWarn, Message: "质量检测发现数据问题", ObjectType: "quality_task", ObjectID: "qt-1",
+	}, time.Now()))
+	notifications, _, err := s.ListNotifications(NotificationQuery{ObjectType: "quality_task"})
+	require.NoError(t, err)
+	require.Len(t, notifications, 1)
+	detail, err := s.GetNotification(notifications[0].ID)
+	require.NoError(t, err)
+	assert.Empty(t, detail.Deliveries)
+}
+
+func TestDeliveryRetriesAndFails(t *testing.T) {
+	s := setupNotificationService(t)
+	ctx := context.Background()
+	rs, endpoint := newRecordingServer(t, `{"errcode":0,"errmsg":"ok"}`, http.StatusInternalServerError, http.StatusOK)
+	retried := createChannel(t, s, "企业微信", models.NotifyChannelWeCom, models.JSONB{"webhook_url": endpoint})
+	_, badURL := newRecordingServer(t, `{"errcode":93000,"errmsg":"invalid webhook url"}`)
+	failing := createChannel(t, s, "失效机器人", models.NotifyChannelWeCom, models.JSONB{"webhook_url": badURL})
+	createSubscription(t, s, models.NotifySubscription{Name: "企业微信", ChannelID: &retried.ID})
+	createSubscription(t, s, models.NotifySubscription{Name: "失效机器人", ChannelID: &failing.ID})
+
+	require.NoError(t, s.process(ctx, syncFailedEvent("task-1"), time.Now()))
+
+	var deliveries []models.NotifyDelivery
+	require.NoError(t, s.db.Find(&deliveries).Error)
+	require.Len(t, deliveries, 2)
+	for _, delivery := range deliveries {
+		switch delivery.ChannelID {
+		case retried.ID:
+			assert.Equal(t, models.NotifyDeliverySent, delivery.Status)
+			assert.Equal(t, 2, delivery.Attempts)
+		case failing.ID:
+			assert.Equal(t, models.NotifyDeliveryFailed, delivery.Status)
+			assert.Equal(t, maxDeliveryAttempts, delivery.Attempts)
+			assert.Contains(t, delivery.Error, "93000")
+		}
+	}
+	require.Len(t, rs.bodies, 2)
+	assert.Contains(t, rs.bodies[1], `"msgtype":"markdown"`)
+}
+
+func TestDingTalkSignedMessage(t *testing.T) {
+	rs, endpoint := newRecordingServer(t, `{"errcode":0,"errmsg":"ok"}`)
+	sender := &dingTalkSender{client: http.DefaultClient, now: func() time.Time { return time.UnixMilli(1700000000000) }}
+	config := models.JSONB{"webhook_url": endpoint + "/robot/send?access_token=abc", "secret": "SECtest"}
+	require.NoError(t, sender.Validate(config))
+
+	require.NoError(t, sender.Send(context.Background(), config, Message{Title: "备份文件已损坏", Content: "级别: warn\nerror: 校验和不一致"}))
+	require.Len(t, rs.queries, 1)
+	query, err := url.ParseQuery(rs.queries[0])
+	require.NoError(t, err)
+	assert.Equal(t, "abc", query.Get("access_token"))
+	assert.Equal(t, "1700000000000", query.Get("timestamp"))
+	assert.Equal(t, dingTalkSign("SECtest", "1700000000000"), query.Get("sign"))
+	assert.Contains(t, rs.bodies[0], "备份文件已损坏")
+}
+
+func TestEmailSender(t *testing.T) {
+	t.Setenv("SMTP_HOST", "smtp.example.com")
+	t.Setenv("SMTP_PORT", "")
+	t.Setenv("SMTP_USERNAME", "")
+	t.Setenv("SMTP_FROM", "datahub@example.com")
+
+	var gotAddr string
+	var gotTo []string
+	var gotMsg string
+	sender := &emailSender{sendMail: func(addr string, auth smtp.Auth, from string, to []string, msg []byte) error {
+		gotAddr, gotTo, gotMsg = addr, to, string(msg)
+		return nil
+	}}
+	config := models.JSONB{"recipients": []interface{}{"ops@example.com", "dba@example.com"}}
+	require.NoError(t, sender.Validate(config))
+	assert.Error(t, sender.Validate(models.JSONB{"recipients": []interface{}{"not-an-email"}}))
+
+	require.NoError(t, sender.Send(context.Background(), config, Message{Title: "质量检测任务执行失败", Content: "级别: error"}))
+	assert.Equal(t, "smtp.example.com:25", gotAddr)
+	assert.Equal(t, []string{"ops@example.com", "dba@example.com"}, gotTo)
+	assert.Contains(t, gotMsg, "From: datahub@example.com")
+	assert.Contains(t, gotMsg, "Subject: =?UTF-8?B?")
+
+	t.Setenv("SMTP_HOST", "")
+	assert.Error(t, sender.Send(context.Background(), config, Message{}))
+}
+
+func TestEmailSendFile(t *testing.T) {
+	t.Setenv("SMTP_HOST", "smtp.example.com")
+	t.Setenv("SMTP_FROM", "datahub@example.com")
+	t.Setenv("SMTP_USERNAME", "")
+
+	var gotTo []string
+	var gotMsg []byte
+	s := setupNotificationService(t)
+	s.senders[models.NotifyChannelEmail] = &emailSender{sendMail: func(addr string, auth smtp.Auth, from string, to []string, msg []byte) error {
+		gotTo, gotMsg = to, msg
+		return nil
+	}}
+	channel := createChannel(t, s, "报表邮件", models.NotifyChannelEmail, models.JSONB{"recipients": []interface{}{"ops@example.com"}})
+
+	file := Attachment{Name: "车辆进出_20261016.csv", ContentType: "text/csv", Data: []byte("plate_no\n粤A12345\n")}
+	require.NoError(t, s.SendFile(context.Background(), channel.ID, []string{"boss@example.com"}, Message{Title: "定期报表", Content: "行数: 1"}, file))
+	assert.Equal(t, []string{"boss@example.com"}, gotTo, "订阅指定的收件人替换渠道配置")
+
+	parsed, err := mail.ReadMessage(bytes.NewReader(gotMsg))
+	require.NoError(t, err)
+	mediaType, params, err := mime.ParseMediaType(parsed.Header.Get("Content-Type"))
+	require.NoError(t, err)
+	require.Equal(t, "multipart/mixed", mediaType)
+	reader := multipart.NewReader(parsed.Body, params["boundary"])
+	_, err = reader.NextPart()
+	require.NoError(t, err)
+	part, err := reader.NextPart()
+	require.NoError(t, err)
+	name, err := new(mime.WordDecoder).DecodeHeader(part.FileName())
+	require.NoError(t, err)
+	assert.Equal(t, file.Name, name)
+	data, err := io.ReadAll(base64.NewDecoder(base64.StdEncoding, part))
+	require.NoError(t, err)
+	assert.Equal(t, file.Data, data)
+
+	dingTalk := createChannel(t, s, "钉钉", models.NotifyChannelDingTalk, models.JSONB{"webhook_url": "https://oapi.dingtalk.com/robot/send?access_token=abc"})
+	assert.ErrorIs(t, s.SendFile(context.Background(), dingTalk.ID, nil, Message{}, file), ErrFileNotSupported)
+}
+
+func TestWeComSendFile(t *testing.T) {
+	rs, endpoint := newRecordingServer(t, `{"errcode":0,"errmsg":"ok","media_id":"media-1"}`)
+	sender := &weComSender{client: http.DefaultClient}
+	config := models.JSONB{"webhook_url": endpoint + "/cgi-bin/webhook/send?key=abc"}
+
+	file := Attachment{Name: "report.csv", Data: []byte("a,b\n1,2\n")}
+	require.NoError(t, sender.SendFile(context.Background(), config, Message{Title: "定期报表", Content: "行数: 1"}, file))
+	require.Len(t, rs.bodies, 3)
+	assert.Contains(t, rs.bodies[0], "定期报表")
+	upload, err := url.ParseQuery(rs.queries[1])
+	require.NoError(t, err)
+	assert.Equal(t, "abc", upload.Get("key"))
+	assert.Equal(t, "file", upload.Get("type"))
+	assert.Contains(t, rs.bodies[1], "a,b")
+	assert.JSONEq(t, `{"msgtype":"file","file":{"media_id":"media-1"}}`, rs.bodies[2])
+
+	_, err = weComUploadURL("https://qyapi.weixin.qq.com/cgi-bin/webhook/send")
+	assert.Error(t, err)
+}
+
+func TestChannelAndSubscriptionValidation(t *testing.T) {
+	s := setupNotificationService(t)
+
+	assert.Error(t, s.CreateChannel(&models.NotifyChannel{Name: "短信", Type: "sms"}))
+	assert.Error(t, s.CreateChannel(&models.NotifyChannel{Name: "webhook", Type: models.NotifyChannelWebhook, Config: models.JSONB{"url": "ftp://example.com"}}))
+	channel := createChannel(t, s, "webhook", models.NotifyChannelWebhook, models.JSONB{"url": "https://example.com/hook"})
+	assert.Error(t, s.CreateChannel(&models.NotifyChannel{Name: "webhook", Type: models.NotifyChannelWebhook, Config: models.JSONB{"url": "https://example.com/other"}}),
+		"渠道名称不能重复")
+
+	missing := "00000000-0000-0000-0000-000000000000"
+	assert.Error(t, s.CreateSubscription(&models.NotifySubscription{Name: "无效渠道", ChannelID: &missing}))
+	assert.Error(t, s.CreateSubscription(&models.NotifySubscription{Name: "无效级别", MinLevel: "fatal"}))
+
+	sub := createSubscription(t, s, models.NotifySubscription{Name: "同步失败", ChannelID: &channel.ID})
+	assert.Equal(t, models.EventLevelWarn, sub.MinLevel)
+	assert.ErrorIs(t, s.DeleteChannel(channel.ID), ErrChannelInUse)
+
+	require.NoError(t, s.DeleteSubscription(sub.ID))
+	require.NoError(t, s.DeleteChannel(channel.ID))
+}
+
+func TestMarkRead(t *testing.T) {
+	s := setupNotificationService(t)
+	ctx := context.Background()
+	createSubscription(t, s, models.NotifySubscription{Name: "全部"})
+	for _, taskID := range []string{"task-1", "task-2", "task-3"} {
+		require.NoError(t, s.process(ctx, syncFailedEvent(taskID), time.Now()))
+	}
+
+	notifications, _, err := s.ListNotifications(NotificationQuery{ObjectID: "task-1"})
+	require.NoError(t, err)
+	require.Len(t, notifications, 1)
+	require.NoError(t, s.MarkRead(notifications[0].ID, "alice"))
+	assert.ErrorIs(t, s.MarkRead("missing", "alice"), gorm.ErrRecordNotFound)
+
+	unread := false
+	list, total, err := s.ListNotifications(NotificationQuery{IsRead: &unread})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+	assert.Len(t, list, 2)
+
+	marked, err := s.MarkAllRead("alice")
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), marked)
+	count, err := s.UnreadCount()
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), count)
+}
+
+func TestHandleEventProcessesInBackground(t *testing.T) {
+	s := setupNotificationService(t)
+	createSubscription(t, s, models.NotifySubscription{Name: "全部"})
+	s.Start()
+
+	s.HandleEvent(context.Background(), syncFailedEvent("task-1"))
+	assert.Eventually(t, func() bool {
+		count, err := s.UnreadCount()
+		return err == nil && count == 1
+	}, 2*time.Second, 10*time.Millisecond)
+}
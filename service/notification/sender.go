@@ -0,0 +1,435 @@
+/*
+ * @module service/notification/sender
+ * @description 通知渠道发送实现，支持通用Webhook、邮件、钉钉群机器人和企业微信群机器人；邮件和企业微信群机器人支持发送文件
+ * @architecture 分层架构 - 业务服务层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow 通知消息 + 渠道配置 -> 按渠道格式组装请求 -> 发送 -> 检查响应
+ * @rules 非2xx响应或机器人返回errcode非0视为失败；钉钉配置了secret时按加签方式发送；邮件服务器通过SMTP_HOST等环境变量配置；文件不超过maxAttachmentSize，邮件作为附件发送，企业微信先上传文件再发送文件消息
+ * @dependencies net/http, net/smtp, mime/multipart
+ * @refs service/notification/notification_service.go, service/models/notification.go
+ */
+
+package notification
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"datahub-service/service/models"
+	"datahub-service/service/secrets"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/smtp"
+	"net/textproto"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Message 待发送的通知消息
+type Message struct {
+	NotificationID string                 `json:"notification_id"`
+	EventType      string                 `json:"event_type"`
+	Level          string                 `json:"level"`
+	Title          string                 `json:"title"`
+	Content        string                 `json:"content"`
+	ObjectType     string                 `json:"object_type"`
+	ObjectID       string                 `json:"object_id"`
+	ObjectName     string                 `json:"object_name,omitempty"`
+	Link           string                 `json:"link,omitempty"`
+	Attributes     map[string]interface{} `json:"attributes,omitempty"`
+	CreatedAt      time.Time              `json:"created_at"`
+}
+
+// Sender 通知渠道发送器
+type Sender interface {
+	// Validate 校验渠道配置
+	Validate(config models.JSONB) error
+	// Send 按渠道配置发送消息
+	Send(ctx context.Context, config models.JSONB, msg Message) error
+}
+
+// maxAttachmentSize 文件大小上限，与企业微信群机器人的文件上限一致
+const maxAttachmentSize = 20 << 20
+
+// Attachment 随消息发送的文件
+type Attachment struct {
+	Name        string
+	ContentType string
+	Data        []byte
+}
+
+// FileSender 支持发送文件的渠道发送器
+type FileSender interface {
+	// SendFile 发送消息并附带文件
+	SendFile(ctx context.Context, config models.JSONB, msg Message, file Attachment) error
+}
+
+// configString 读取渠道配置中的字符串
+func configString(config models.JSONB, key string) string {
+	value, _ := config[key].(string)
+	return strings.TrimSpace(value)
+}
+
+// configStrings 读取渠道配置中的字符串列表
+func configStrings(config models.JSONB, key string) []string {
+	var result []string
+	switch values := config[key].(type) {
+	case []string:
+		result = values
+	case []interface{}:
+		for _, value := range values {
+			if text, ok := value.(string); ok && strings.TrimSpace(text) != "" {
+				result = append(result, strings.TrimSpace(text))
+			}
+		}
+	}
+	return result
+}
+
+// validateURL 校验http(s)地址，密钥引用在发送时解析，保存时只校验引用格式
+func validateURL(config models.JSONB, key string) error {
+	raw := configString(config, key)
+	if raw == "" {
+		return fmt.Errorf("渠道配置缺少%s", key)
+	}
+	if secrets.IsReference(raw) {
+		return nil
+	}
+	parsed, err := url.Parse(raw)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return fmt.Errorf("%s必须是http(s)地址", key)
+	}
+	return nil
+}
+
+// postJSON 发送JSON请求，返回响应体
+func postJSON(ctx context.Context, client *http.Client, target string, headers map[string]string, payload interface{}) ([]byte, error) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("HTTP %d: %s", resp.StatusCode, strings.TrimSpace(string(respBody)))
+	}
+	return respBody, nil
+}
+
+// checkRobotResponse 检查钉钉和企业微信机器人的响应
+func checkRobotResponse(body []byte) error {
+	var result struct {
+		ErrCode int    `json:"errcode"`
+		ErrMsg  string `json:"errmsg"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return fmt.Errorf("解析机器人响应失败: %w", err)
+	}
+	if result.ErrCode != 0 {
+		return fmt.Errorf("机器人返回错误 %d: %s", result.ErrCode, result.ErrMsg)
+	}
+	return nil
+}
+
+// markdownText 组装机器人markdown消息正文
+func markdownText(msg Message) string {
+	return fmt.Sprintf("### %s\n\n%s", msg.Title, strings.ReplaceAll(msg.Content, "\n", "\n\n"))
+}
+
+// webhookSender 通用Webhook，POST通知消息JSON
+type webhookSender struct {
+	client *http.Client
+}
+
+func (s *webhookSender) Validate(config models.JSONB) error {
+	return validateURL(config, "url")
+}
+
+func (s *webhookSender) Send(ctx context.Context, config models.JSONB, msg Message) error {
+	headers := make(map[string]string)
+	if values, ok := config["headers"].(map[string]interface{}); ok {
+		for key, value := range values {
+			if text, ok := value.(string); ok {
+				headers[key] = text
+			}
+		}
+	}
+	_, err := postJSON(ctx, s.client, configString(config, "url"), headers, msg)
+	return err
+}
+
+// dingTalkSender 钉钉群机器人
+type dingTalkSender struct {
+	client *http.Client
+	now    func() time.Time
+}
+
+func (s *dingTalkSender) Validate(config models.JSONB) error {
+	return validateURL(config, "webhook_url")
+}
+
+func (s *dingTalkSender) Send(ctx context.Context, config models.JSONB, msg Message) error {
+	target := configString(config, "webhook_url")
+	if secret := configString(config, "secret"); secret != "" {
+		timestamp := strconv.FormatInt(s.now().UnixMilli(), 10)
+		separator := "?"
+		if strings.Contains(target, "?") {
+			separator = "&"
+		}
+		target += separator + "timestamp=" + timestamp + "&sign=" + url.QueryEscape(dingTalkSign(secret, timestamp))
+	}
+
+	body, err := postJSON(ctx, s.client, target, nil, map[string]interface{}{
+		"msgtype":  "markdown",
+		"markdown": map[string]string{"title": msg.Title, "text": markdownText(msg)},
+	})
+	if err != nil {
+		return err
+	}
+	return checkRobotResponse(body)
+}
+
+// dingTalkSign 钉钉加签：HmacSHA256(timestamp + "\n" + secret)后base64
+func dingTalkSign(secret, timestamp string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "\n" + secret))
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// weComSender 企业微信群机器人
+type weComSender struct {
+	client *http.Client
+}
+
+func (s *weComSender) Validate(config models.JSONB) error {
+	return validateURL(config, "webhook_url")
+}
+
+func (s *weComSender) Send(ctx context.Context, config models.JSONB, msg Message) error {
+	body, err := postJSON(ctx, s.client, configString(config, "webhook_url"), nil, map[string]interface{}{
+		"msgtype":  "markdown",
+		"markdown": map[string]string{"content": markdownText(msg)},
+	})
+	if err != nil {
+		return err
+	}
+	return checkRobotResponse(body)
+}
+
+// SendFile 先发送markdown消息，再上传文件并发送文件消息
+func (s *weComSender) SendFile(ctx context.Context, config models.JSONB, msg Message, file Attachment) error {
+	if err := s.Send(ctx, config, msg); err != nil {
+		return err
+	}
+	uploadURL, err := weComUploadURL(configString(config, "webhook_url"))
+	if err != nil {
+		return err
+	}
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	part, err := writer.CreateFormFile("media", file.Name)
+	if err != nil {
+		return err
+	}
+	if _, err := part.Write(file.Data); err != nil {
+		return err
+	}
+	if err := writer.Close(); err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, uploadURL, &body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("上传文件失败: %w", err)
+	}
+	defer resp.Body.Close()
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("上传文件失败: HTTP %d: %s", resp.StatusCode, strings.TrimSpace(string(respBody)))
+	}
+	if err := checkRobotResponse(respBody); err != nil {
+		return fmt.Errorf("上传文件失败: %w", err)
+	}
+	var uploaded struct {
+		MediaID string `json:"media_id"`
+	}
+	if err := json.Unmarshal(respBody, &uploaded); err != nil || uploaded.MediaID == "" {
+		return errors.New("上传文件失败: 响应中没有media_id")
+	}
+
+	sendBody, err := postJSON(ctx, s.client, configString(config, "webhook_url"), nil, map[string]interface{}{
+		"msgtype": "file",
+		"file":    map[string]string{"media_id": uploaded.MediaID},
+	})
+	if err != nil {
+		return err
+	}
+	return checkRobotResponse(sendBody)
+}
+
+// weComUploadURL 由机器人webhook地址推导文件上传地址，沿用其中的key
+func weComUploadURL(webhookURL string) (string, error) {
+	parsed, err := url.Parse(webhookURL)
+	if err != nil {
+		return "", err
+	}
+	query := parsed.Query()
+	if query.Get("key") == "" {
+		return "", errors.New("企业微信机器人地址缺少key参数，无法上传文件")
+	}
+	parsed.Path = strings.TrimSuffix(parsed.Path, "/send") + "/upload_media"
+	query.Set("type", "file")
+	parsed.RawQuery = query.Encode()
+	return parsed.String(), nil
+}
+
+// emailSender 邮件，SMTP服务器通过SMTP_HOST、SMTP_PORT、SMTP_USERNAME、SMTP_PASSWORD、SMTP_FROM配置
+type emailSender struct {
+	sendMail func(addr string, auth smtp.Auth, from string, to []string, msg []byte) error
+}
+
+func (s *emailSender) Validate(config models.JSONB) error {
+	recipients := configStrings(config, "recipients")
+	if len(recipients) == 0 {
+		return errors.New("渠道配置缺少recipients")
+	}
+	for _, recipient := range recipients {
+		if !strings.Contains(recipient, "@") {
+			return fmt.Errorf("无效的邮箱地址: %s", recipient)
+		}
+	}
+	return nil
+}
+
+func (s *emailSender) Send(ctx context.Context, config models.JSONB, msg Message) error {
+	return s.send(config, msg, nil)
+}
+
+// SendFile 发送带附件的邮件
+func (s *emailSender) SendFile(ctx context.Context, config models.JSONB, msg Message, file Attachment) error {
+	return s.send(config, msg, &file)
+}
+
+func (s *emailSender) send(config models.JSONB, msg Message, file *Attachment) error {
+	host := os.Getenv("SMTP_HOST")
+	if host == "" {
+		return errors.New("未配置邮件服务器，请设置SMTP_HOST")
+	}
+	port := os.Getenv("SMTP_PORT")
+	if port == "" {
+		port = "25"
+	}
+	from := os.Getenv("SMTP_FROM")
+	username := os.Getenv("SMTP_USERNAME")
+	if from == "" {
+		from = username
+	}
+	if from == "" {
+		return errors.New("未配置发件人，请设置SMTP_FROM")
+	}
+
+	var auth smtp.Auth
+	if username != "" {
+		auth = smtp.PlainAuth("", username, os.Getenv("SMTP_PASSWORD"), host)
+	}
+	recipients := configStrings(config, "recipients")
+	body := buildEmail(from, recipients, msg)
+	if file != nil {
+		body = buildEmailWithAttachment(from, recipients, msg, *file)
+	}
+	return s.sendMail(host+":"+port, auth, from, recipients, body)
+}
+
+// writeEmailHeaders 写入发件人、收件人和主题
+func writeEmailHeaders(buf *bytes.Buffer, from string, to []string, title string) {
+	buf.WriteString("From: " + from + "\r\n")
+	buf.WriteString("To: " + strings.Join(to, ", ") + "\r\n")
+	buf.WriteString("Subject: =?UTF-8?B?" + base64.StdEncoding.EncodeToString([]byte(title)) + "?=\r\n")
+	buf.WriteString("MIME-Version: 1.0\r\n")
+}
+
+// buildEmail 组装纯文本邮件
+func buildEmail(from string, to []string, msg Message) []byte {
+	var buf bytes.Buffer
+	writeEmailHeaders(&buf, from, to, msg.Title)
+	buf.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	buf.WriteString("Content-Transfer-Encoding: base64\r\n\r\n")
+	buf.WriteString(base64.StdEncoding.EncodeToString([]byte(msg.Content)))
+	buf.WriteString("\r\n")
+	return buf.Bytes()
+}
+
+// buildEmailWithAttachment 组装带附件的邮件，正文为纯文本
+func buildEmailWithAttachment(from string, to []string, msg Message, file Attachment) []byte {
+	var buf bytes.Buffer
+	writeEmailHeaders(&buf, from, to, msg.Title)
+	var parts bytes.Buffer
+	writer := multipart.NewWriter(&parts)
+	buf.WriteString("Content-Type: multipart/mixed; boundary=" + writer.Boundary() + "\r\n\r\n")
+
+	text, _ := writer.CreatePart(textproto.MIMEHeader{
+		"Content-Type":              {"text/plain; charset=UTF-8"},
+		"Content-Transfer-Encoding": {"base64"},
+	})
+	text.Write([]byte(base64.StdEncoding.EncodeToString([]byte(msg.Content)) + "\r\n"))
+
+	contentType := file.ContentType
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	name := mime.BEncoding.Encode("UTF-8", file.Name)
+	attachment, _ := writer.CreatePart(textproto.MIMEHeader{
+		"Content-Type":              {contentType + "; name=\"" + name + "\""},
+		"Content-Transfer-Encoding": {"base64"},
+		"Content-Disposition":       {"attachment; filename=\"" + name + "\""},
+	})
+	encoded := base64.StdEncoding.EncodeToString(file.Data)
+	for len(encoded) > 76 {
+		attachment.Write([]byte(encoded[:76] + "\r\n"))
+		encoded = encoded[76:]
+	}
+	attachment.Write([]byte(encoded + "\r\n"))
+	writer.Close()
+
+	buf.Write(parts.Bytes())
+	return buf.Bytes()
+}
+
+// defaultSenders 内置渠道发送器
+func defaultSenders() map[string]Sender {
+	client := &http.Client{Timeout: 10 * time.Second}
+	return map[string]Sender{
+		models.NotifyChannelWebhook:  &webhookSender{client: client},
+		models.NotifyChannelDingTalk: &dingTalkSender{client: client, now: time.Now},
+		models.NotifyChannelWeCom:    &weComSender{client: client},
+		models.NotifyChannelEmail:    &emailSender{sendMail: smtp.SendMail},
+	}
+}
@@ -0,0 +1,182 @@
+/*
+ * @module service/notification/templates_test
+ * @description 消息模板测试，覆盖内置中英文模板、控制台链接和失败摘要、自定义模板的优先级和回退、模板校验以及按渠道语言渲染
+ * @architecture 测试层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow 准备事件和模板 -> 渲染/处理事件 -> 验证标题、正文和渠道收到的消息
+ * @rules 使用内存sqlite和httptest服务，不依赖外部渠道
+ * @dependencies gorm.io/driver/sqlite, stretchr/testify
+ * @refs service/notification/templates.go
+ */
+
+package notification
+
+import (
+	"context"
+	"datahub-service/service/eventlog"
+	"datahub-service/service/models"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderBuiltinLocales(t *testing.T) {
+	t.Setenv("NOTIFY_CONSOLE_URL", "https://console.example.com/")
+	s := setupNotificationService(t)
+	require.NoError(t, s.db.AutoMigrate(&models.QualityTask{}))
+	require.NoError(t, s.db.Create(&models.QualityTask{ID: "qt-1", Name: "人口库完整性检测", LibraryType: "basic", LibraryID: "lib-1", InterfaceID: "if-1", ScheduleType: "manual"}).Error)
+
+	event := eventlog.Event{
+		Type:       eventlog.EventQualityIssuesFound,
+		Level:      models.EventLevelWarn,
+		Message:    "质量检测发现问题",
+		ObjectType: "quality_task",
+		ObjectID:   "qt-1",
+		Attributes: map[string]interface{}{"issue_count": 12, "overall_score": 0.8},
+	}
+	data := s.buildTemplateData(event, "req-1", time.Date(2024, 5, 1, 8, 0, 0, 0, time.UTC))
+	assert.Equal(t, "人口库完整性检测", data.ObjectName)
+	assert.Equal(t, "https://console.example.com/quality-tasks/qt-1", data.Link)
+
+	title, body := s.render(data, models.NotifyChannelDingTalk, LocaleZh)
+	assert.Equal(t, "质量检测发现问题：人口库完整性检测", title)
+	assert.Contains(t, body, "- 级别：警告")
+	assert.Contains(t, body, "- 问题数：12")
+	assert.Contains(t, body, "- 时间：2024-05-01 08:00:00")
+	assert.Contains(t, body, "[查看详情](https://console.example.com/quality-tasks/qt-1)")
+
+	title, body = s.render(data, models.NotifyChannelDingTalk, LocaleEn)
+	assert.Equal(t, "Data quality issues found: 人口库完整性检测", title)
+	assert.Contains(t, body, "- Level: Warning")
+	assert.Contains(t, body, "- Issues: 12")
+	assert.Contains(t, body, "[View details]")
+
+	// 不支持的语言按中文渲染，未知事件使用兜底模板
+	event.Type = "custom_event"
+	title, _ = s.render(s.buildTemplateData(event, "", time.Now()), "", "fr")
+	assert.Equal(t, "[警告] 质量检测发现问题", title)
+}
+
+func TestSummarizeFirstLine(t *testing.T) {
+	assert.Equal(t, "连接超时", summarize(map[string]interface{}{"error": "连接超时\n  at line 1\n  at line 2"}))
+	assert.Empty(t, summarize(nil))
+
+	long := strings.Repeat("错", summaryMaxRunes+10)
+	assert.Equal(t, strings.Repeat("错", summaryMaxRunes)+"...", summarize(map[string]interface{}{"error": long}))
+	assert.Empty(t, objectLink("", "sync_task", "task-1"))
+	assert.Empty(t, objectLink("https://console.example.com", "unknown", "id-1"))
+}
+
+func TestCustomTemplatePrecedence(t *testing.T) {
+	s := setupNotificationService(t)
+	data := s.buildTemplateData(syncFailedEvent("task-1"), "", time.Now())
+
+	require.NoError(t, s.CreateTemplate(&models.NotifyTemplate{
+		EventType: DefaultTemplateEvent, TitleTemplate: "默认：{{.Message}}", BodyTemplate: "{{.Summary}}", IsEnabled: true,
+	}))
+	title, body := s.render(data, models.NotifyChannelWeCom, LocaleZh)
+	assert.Equal(t, "默认：基础库同步任务执行失败", title)
+	assert.Equal(t, "连接超时", body)
+
+	require.NoError(t, s.CreateTemplate(&models.NotifyTemplate{
+		EventType: eventlog.EventTaskFailed, ChannelType: models.NotifyChannelDingTalk,
+		TitleTemplate: "钉钉：{{.ObjectName}}", BodyTemplate: `{{attr .Attributes "execution_id"}}`, IsEnabled: true,
+	}))
+	title, body = s.render(data, models.NotifyChannelDingTalk, LocaleZh)
+	assert.Equal(t, "钉钉：task-1", title)
+	assert.Equal(t, "exec-1", body)
+
+	// 其他渠道仍使用兜底模板，英文没有自定义模板时使用内置模板
+	title, _ = s.render(data, models.NotifyChannelWeCom, LocaleZh)
+	assert.Equal(t, "默认：基础库同步任务执行失败", title)
+	title, _ = s.render(data, models.NotifyChannelDingTalk, LocaleEn)
+	assert.Equal(t, "Sync task failed: task-1", title)
+
+	// 停用的模板不参与选择
+	templates, _, err := s.GetTemplates(1, 10, eventlog.EventTaskFailed, "")
+	require.NoError(t, err)
+	require.Len(t, templates, 1)
+	disabled := templates[0]
+	disabled.IsEnabled = false
+	require.NoError(t, s.UpdateTemplate(disabled.ID, &disabled))
+	title, _ = s.render(data, models.NotifyChannelDingTalk, LocaleZh)
+	assert.Equal(t, "默认：基础库同步任务执行失败", title)
+}
+
+func TestCustomTemplateFallsBackOnRenderError(t *testing.T) {
+	s := setupNotificationService(t)
+	// 直接写入无法渲染的模板，模拟字段变更后遗留的模板
+	require.NoError(t, s.db.Create(&models.NotifyTemplate{
+		EventType: eventlog.EventTaskFailed, Locale: LocaleZh, TitleTemplate: "{{.TaskName}}", BodyTemplate: "-", IsEnabled: true,
+	}).Error)
+
+	title, _ := s.render(s.buildTemplateData(syncFailedEvent("task-1"), "", time.Now()), "", LocaleZh)
+	assert.Equal(t, "同步任务失败：task-1", title)
+}
+
+func TestTemplateValidation(t *testing.T) {
+	s := setupNotificationService(t)
+
+	valid := func() *models.NotifyTemplate {
+		return &models.NotifyTemplate{EventType: eventlog.EventTaskFailed, TitleTemplate: "{{.ObjectName}}", BodyTemplate: "{{.Summary}}", IsEnabled: true}
+	}
+
+	bad := valid()
+	bad.TitleTemplate = "{{.ObjectName"
+	assert.Error(t, s.CreateTemplate(bad))
+
+	bad = valid()
+	bad.BodyTemplate = "{{.NoSuchField}}"
+	assert.Error(t, s.CreateTemplate(bad))
+
+	bad = valid()
+	bad.Locale = "fr"
+	assert.Error(t, s.CreateTemplate(bad))
+
+	bad = valid()
+	bad.ChannelType = "sms"
+	assert.ErrorIs(t, s.CreateTemplate(bad), ErrUnsupportedChannel)
+
+	first := valid()
+	require.NoError(t, s.CreateTemplate(first))
+	assert.Equal(t, LocaleZh, first.Locale)
+	assert.Error(t, s.CreateTemplate(valid()), "同一事件类型、渠道类型和语言只能有一个模板")
+	require.NoError(t, s.UpdateTemplate(first.ID, valid()))
+
+	title, body, err := s.PreviewTemplate(&models.NotifyTemplate{EventType: eventlog.EventTaskFailed, TitleTemplate: "{{.LevelText}} {{.ObjectName}}", BodyTemplate: "{{.Link}}"})
+	require.NoError(t, err)
+	assert.Equal(t, "错误 人口基础库/户籍数据源", title)
+	assert.Contains(t, body, "/sync-tasks/")
+
+	// 未提供模板内容时预览当前生效的模板
+	title, _, err = s.PreviewTemplate(&models.NotifyTemplate{EventType: eventlog.EventBackupCorrupt, Locale: LocaleEn})
+	require.NoError(t, err)
+	assert.Equal(t, "Backup corrupt: 人口基础库/户籍数据源", title)
+}
+
+func TestProcessRendersPerChannelLocale(t *testing.T) {
+	s := setupNotificationService(t)
+	rsZh, endpointZh := newRecordingServer(t, "")
+	rsEn, endpointEn := newRecordingServer(t, "")
+	zh := createChannel(t, s, "中文Webhook", models.NotifyChannelWebhook, models.JSONB{"url": endpointZh})
+	en := &models.NotifyChannel{Name: "English Webhook", Type: models.NotifyChannelWebhook, Config: models.JSONB{"url": endpointEn}, Locale: LocaleEn, IsEnabled: true}
+	require.NoError(t, s.CreateChannel(en))
+	createSubscription(t, s, models.NotifySubscription{Name: "中文", ChannelID: &zh.ID})
+	createSubscription(t, s, models.NotifySubscription{Name: "英文", ChannelID: &en.ID})
+
+	require.NoError(t, s.process(context.Background(), syncFailedEvent("task-1"), time.Now()))
+
+	require.Len(t, rsZh.bodies, 1)
+	require.Len(t, rsEn.bodies, 1)
+	var msgZh, msgEn Message
+	require.NoError(t, json.Unmarshal([]byte(rsZh.bodies[0]), &msgZh))
+	require.NoError(t, json.Unmarshal([]byte(rsEn.bodies[0]), &msgEn))
+	assert.Equal(t, "同步任务失败：task-1", msgZh.Title)
+	assert.Equal(t, "Sync task failed: task-1", msgEn.Title)
+	assert.Contains(t, msgEn.Content, "- Reason: 连接超时")
+	assert.Equal(t, "task-1", msgEn.ObjectName)
+}
@@ -0,0 +1,266 @@
+/*
+ * @module service/operation/service
+ * @description 长时操作服务，为各类异步启动接口创建统一的操作资源，按执行对象刷新状态和进度，并把取消请求交给对应的执行方
+ * @architecture 分层架构 - 业务服务层，执行方通过Tracker注册，本包不依赖具体业务服务
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow 启动：提交执行 -> 成功时创建操作(running)；查询：未结束的操作调用Tracker.Refresh -> 保存变化 -> 结束时记录完成时间；取消：Tracker.Cancel -> canceled
+ * @rules
+ *   - 每种操作类型注册一个Tracker，未注册的类型不能启动
+ *   - 状态以执行对象为准，查询时刷新，刷新失败只记录日志并返回上次保存的状态
+ *   - 已结束的操作不再刷新，也不能取消；成功结束时进度为100
+ *   - 操作按租户隔离，创建时记录发起请求的request_id
+ * @dependencies gorm.io/gorm, datahub-service/service/models
+ * @refs service/models/operation.go, api/controllers/operation_controller.go, service/init.go
+ */
+
+package operation
+
+import (
+	"context"
+	"datahub-service/logger"
+	"datahub-service/service/models"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrUnknownKind 未注册的操作类型
+	ErrUnknownKind = errors.New("不支持的操作类型")
+	// ErrOperationFinished 操作已结束
+	ErrOperationFinished = errors.New("操作已结束，无法取消")
+	// ErrCancelNotSupported 操作类型不支持取消
+	ErrCancelNotSupported = errors.New("该类型的操作不支持取消")
+)
+
+// Tracker 异步操作的执行方，按执行对象的状态刷新操作并处理取消
+type Tracker interface {
+	// Refresh 按执行对象的当前状态更新op的状态、进度、执行记录ID和结果链接
+	Refresh(ctx context.Context, op *models.Operation) error
+	// Cancel 取消执行，返回nil表示执行已停止或已确认会停止
+	Cancel(ctx context.Context, op *models.Operation) error
+}
+
+// TrackerFuncs 以函数实现Tracker，CancelFunc为空表示不支持取消
+type TrackerFuncs struct {
+	RefreshFunc func(ctx context.Context, op *models.Operation) error
+	CancelFunc  func(ctx context.Context, op *models.Operation) error
+}
+
+// Refresh 调用RefreshFunc
+func (f TrackerFuncs) Refresh(ctx context.Context, op *models.Operation) error {
+	if f.RefreshFunc == nil {
+		return nil
+	}
+	return f.RefreshFunc(ctx, op)
+}
+
+// Cancel 调用CancelFunc
+func (f TrackerFuncs) Cancel(ctx context.Context, op *models.Operation) error {
+	if f.CancelFunc == nil {
+		return ErrCancelNotSupported
+	}
+	return f.CancelFunc(ctx, op)
+}
+
+// ListFilter 操作列表过滤条件
+type ListFilter struct {
+	Kind     string
+	Kinds    []string // 限定可见的操作类型，为nil时不限定
+	TargetID string
+	Status   string
+	Page     int
+	Size     int
+}
+
+// Service 长时操作服务
+type Service struct {
+	db       *gorm.DB
+	mu       sync.RWMutex
+	trackers map[string]Tracker
+}
+
+// NewService 创建长时操作服务实例
+func NewService(db *gorm.DB) *Service {
+	return &Service{
+		db:       db,
+		trackers: make(map[string]Tracker),
+	}
+}
+
+// Register 注册操作类型的执行方
+func (s *Service) Register(kind string, tracker Tracker) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.trackers[kind] = tracker
+}
+
+// tracker 获取操作类型的执行方
+func (s *Service) tracker(kind string) (Tracker, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	tracker, ok := s.trackers[kind]
+	return tracker, ok
+}
+
+// Start 调用submit提交执行并创建操作，submit返回执行记录ID（执行记录由执行面异步创建时为空）；
+// 提交失败时不创建操作，原样返回submit的错误
+func (s *Service) Start(ctx context.Context, kind, targetID, createdBy string, submit func(ctx context.Context) (string, error)) (*models.Operation, error) {
+	if _, ok := s.tracker(kind); !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownKind, kind)
+	}
+
+	// 创建时间取提交前的时间，执行方据此识别本次操作之后开始的执行
+	op := &models.Operation{
+		Kind:      kind,
+		TargetID:  targetID,
+		Status:    models.OperationStatusRunning,
+		RequestID: logger.RequestIDFromContext(ctx),
+		CreatedBy: createdBy,
+		CreatedAt: time.Now(),
+	}
+	executionID, err := submit(ctx)
+	if err != nil {
+		return nil, err
+	}
+	op.ExecutionID = executionID
+	s.refresh(ctx, op)
+	settle(op)
+	if err := s.db.WithContext(ctx).Create(op).Error; err != nil {
+		return nil, fmt.Errorf("创建操作失败: %w", err)
+	}
+	return op, nil
+}
+
+// Get 获取操作，未结束的操作先按执行对象刷新
+func (s *Service) Get(ctx context.Context, id string) (*models.Operation, error) {
+	var op models.Operation
+	if err := s.db.WithContext(ctx).First(&op, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	s.refreshAndSave(ctx, &op)
+	return &op, nil
+}
+
+// List 分页查询操作，按创建时间倒序，本页未结束的操作先刷新
+func (s *Service) List(ctx context.Context, filter ListFilter) ([]models.Operation, int64, error) {
+	db := s.db.WithContext(ctx).Model(&models.Operation{})
+	if filter.Kind != "" {
+		db = db.Where("kind = ?", filter.Kind)
+	}
+	if filter.Kinds != nil {
+		db = db.Where("kind IN ?", append([]string{""}, filter.Kinds...))
+	}
+	if filter.TargetID != "" {
+		db = db.Where("target_id = ?", filter.TargetID)
+	}
+	if filter.Status != "" {
+		db = db.Where("status = ?", filter.Status)
+	}
+
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	page, size := models.NormalizePage(filter.Page, filter.Size)
+	var operations []models.Operation
+	if err := db.Order("created_at DESC").Offset((page - 1) * size).Limit(size).Find(&operations).Error; err != nil {
+		return nil, 0, err
+	}
+	for i := range operations {
+		s.refreshAndSave(ctx, &operations[i])
+	}
+	return operations, total, nil
+}
+
+// Cancel 取消未结束的操作
+func (s *Service) Cancel(ctx context.Context, id string) (*models.Operation, error) {
+	op, err := s.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if op.IsFinished() {
+		return nil, ErrOperationFinished
+	}
+	tracker, ok := s.tracker(op.Kind)
+	if !ok {
+		return nil, ErrCancelNotSupported
+	}
+	if err := tracker.Cancel(ctx, op); err != nil {
+		return nil, err
+	}
+
+	op.Status = models.OperationStatusCanceled
+	op.Message = "操作已取消"
+	s.save(ctx, op)
+	return op, nil
+}
+
+// refreshAndSave 刷新未结束的操作，状态有变化时保存
+func (s *Service) refreshAndSave(ctx context.Context, op *models.Operation) {
+	if op.IsFinished() {
+		return
+	}
+	before := *op
+	s.refresh(ctx, op)
+	if changed(&before, op) {
+		s.save(ctx, op)
+	}
+}
+
+// refresh 调用执行方刷新操作状态
+func (s *Service) refresh(ctx context.Context, op *models.Operation) {
+	tracker, ok := s.tracker(op.Kind)
+	if !ok {
+		return
+	}
+	if err := tracker.Refresh(ctx, op); err != nil {
+		slog.Warn("刷新操作状态失败", "operation_id", op.ID, "kind", op.Kind, "target_id", op.TargetID, "error", err)
+	}
+}
+
+// settle 规范化进度，进入结束状态时记录完成时间，并填充链接
+func settle(op *models.Operation) {
+	op.Progress = min(max(op.Progress, 0), 100)
+	if op.IsFinished() && op.FinishedAt == nil {
+		now := time.Now()
+		op.FinishedAt = &now
+		if op.Status == models.OperationStatusSucceeded {
+			op.Progress = 100
+		}
+	}
+	op.FillLinks()
+}
+
+// save 保存操作的状态字段；已结束的操作不会被覆盖
+func (s *Service) save(ctx context.Context, op *models.Operation) {
+	settle(op)
+	err := s.db.WithContext(ctx).Model(&models.Operation{}).
+		Where("id = ? AND status NOT IN ?", op.ID, []string{
+			models.OperationStatusSucceeded, models.OperationStatusFailed, models.OperationStatusCanceled,
+		}).
+		Updates(map[string]interface{}{
+			"execution_id": op.ExecutionID,
+			"status":       op.Status,
+			"progress":     op.Progress,
+			"message":      op.Message,
+			"error":        op.Error,
+			"result_link":  op.ResultLink,
+			"finished_at":  op.FinishedAt,
+			"updated_at":   time.Now(),
+		}).Error
+	if err != nil {
+		slog.Error("保存操作状态失败", "operation_id", op.ID, "status", op.Status, "error", err)
+	}
+}
+
+// changed 判断刷新后操作的状态字段是否变化
+func changed(before, after *models.Operation) bool {
+	return before.ExecutionID != after.ExecutionID || before.Status != after.Status ||
+		before.Progress != after.Progress || before.Message != after.Message ||
+		before.Error != after.Error || before.ResultLink != after.ResultLink
+}
@@ -0,0 +1,168 @@
+/*
+ * @module service/operation/service_test
+ * @description 长时操作服务测试，覆盖启动、按执行对象刷新到结束、取消、已结束操作的取消冲突、未注册类型和提交失败
+ * @architecture 测试层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow 初始化内存数据库 -> 注册测试执行方 -> 启动操作 -> 改变执行状态 -> 查询/取消 -> 验证保存的状态
+ * @rules 使用SQLite内存数据库，不依赖外部服务
+ * @dependencies github.com/stretchr/testify, gorm.io/driver/sqlite
+ * @refs service/operation/service.go
+ */
+
+package operation
+
+import (
+	"context"
+	"datahub-service/service/models"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// fakeExecution 测试执行方，状态由测试直接修改
+type fakeExecution struct {
+	status    string
+	progress  int
+	cancelled bool
+}
+
+func (f *fakeExecution) tracker() TrackerFuncs {
+	return TrackerFuncs{
+		RefreshFunc: func(ctx context.Context, op *models.Operation) error {
+			op.Status = f.status
+			op.Progress = f.progress
+			op.ResultLink = "/executions/" + op.ExecutionID
+			return nil
+		},
+		CancelFunc: func(ctx context.Context, op *models.Operation) error {
+			f.cancelled = true
+			return nil
+		},
+	}
+}
+
+func setupOperationService(t *testing.T) (*Service, *fakeExecution) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&models.Operation{}))
+
+	execution := &fakeExecution{status: models.OperationStatusRunning}
+	s := NewService(db)
+	s.Register(models.OperationKindSyncTask, execution.tracker())
+	s.Register(models.OperationKindQualityTask, TrackerFuncs{})
+	return s, execution
+}
+
+func submitExecution(id string) func(ctx context.Context) (string, error) {
+	return func(ctx context.Context) (string, error) {
+		return id, nil
+	}
+}
+
+func TestStartAndRefresh(t *testing.T) {
+	s, execution := setupOperationService(t)
+	ctx := context.Background()
+
+	execution.progress = 30
+	op, err := s.Start(ctx, models.OperationKindSyncTask, "task-1", "tester", submitExecution("exec-1"))
+	require.NoError(t, err)
+	assert.NotEmpty(t, op.ID)
+	assert.Equal(t, models.OperationStatusRunning, op.Status)
+	assert.Equal(t, 30, op.Progress)
+	assert.Equal(t, "exec-1", op.ExecutionID)
+	assert.Equal(t, "/operations/"+op.ID, op.SelfLink)
+	assert.Equal(t, "/operations/"+op.ID+"/cancel", op.CancelLink)
+	assert.Equal(t, "/executions/exec-1", op.ResultLink)
+
+	execution.status = models.OperationStatusSucceeded
+	execution.progress = 80
+	got, err := s.Get(ctx, op.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.OperationStatusSucceeded, got.Status)
+	assert.Equal(t, 100, got.Progress)
+	assert.NotNil(t, got.FinishedAt)
+	assert.Empty(t, got.CancelLink)
+
+	// 结束后不再刷新
+	execution.status = models.OperationStatusFailed
+	got, err = s.Get(ctx, op.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.OperationStatusSucceeded, got.Status)
+
+	operations, total, err := s.List(ctx, ListFilter{TargetID: "task-1"})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	require.Len(t, operations, 1)
+	assert.Equal(t, op.ID, operations[0].ID)
+
+	_, total, err = s.List(ctx, ListFilter{Kinds: []string{}})
+	require.NoError(t, err)
+	assert.Zero(t, total)
+}
+
+func TestStartFinishedImmediately(t *testing.T) {
+	s, execution := setupOperationService(t)
+	ctx := context.Background()
+
+	execution.status = models.OperationStatusFailed
+	op, err := s.Start(ctx, models.OperationKindSyncTask, "task-1", "tester", submitExecution("exec-1"))
+	require.NoError(t, err)
+
+	got, err := s.Get(ctx, op.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.OperationStatusFailed, got.Status)
+	assert.NotNil(t, got.FinishedAt)
+}
+
+func TestCancel(t *testing.T) {
+	s, execution := setupOperationService(t)
+	ctx := context.Background()
+
+	op, err := s.Start(ctx, models.OperationKindSyncTask, "task-1", "tester", submitExecution("exec-1"))
+	require.NoError(t, err)
+
+	cancelled, err := s.Cancel(ctx, op.ID)
+	require.NoError(t, err)
+	assert.True(t, execution.cancelled)
+	assert.Equal(t, models.OperationStatusCanceled, cancelled.Status)
+	assert.NotNil(t, cancelled.FinishedAt)
+
+	_, err = s.Cancel(ctx, op.ID)
+	assert.ErrorIs(t, err, ErrOperationFinished)
+}
+
+func TestCancelNotSupported(t *testing.T) {
+	s, _ := setupOperationService(t)
+	ctx := context.Background()
+
+	op, err := s.Start(ctx, models.OperationKindQualityTask, "task-1", "tester", submitExecution("exec-1"))
+	require.NoError(t, err)
+
+	_, err = s.Cancel(ctx, op.ID)
+	assert.ErrorIs(t, err, ErrCancelNotSupported)
+}
+
+func TestStartErrors(t *testing.T) {
+	s, _ := setupOperationService(t)
+	ctx := context.Background()
+
+	_, err := s.Start(ctx, "unknown", "task-1", "tester", submitExecution("exec-1"))
+	assert.ErrorIs(t, err, ErrUnknownKind)
+
+	submitErr := errors.New("任务正在执行")
+	_, err = s.Start(ctx, models.OperationKindSyncTask, "task-1", "tester", func(ctx context.Context) (string, error) {
+		return "", submitErr
+	})
+	assert.ErrorIs(t, err, submitErr)
+
+	_, total, err := s.List(ctx, ListFilter{})
+	require.NoError(t, err)
+	assert.Zero(t, total)
+}
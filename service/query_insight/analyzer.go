@@ -0,0 +1,513 @@
+/*
+ * @module service/query_insight/analyzer
+ * @description 共享接口表查询性能分析，结合扫描统计、pg_stat_statements语句统计和行级策略的过滤字段，为共享接口使用的主题接口表推荐索引并支持一键创建
+ * @architecture 分层架构 - 业务服务层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow 查询启用的共享接口对应的主题接口表 -> 读取扫描统计/已有索引/语句统计 -> 提取语句中的等值、范围和排序字段 -> 合并行级策略过滤字段 -> 去掉已有索引覆盖的候选 -> 按耗时排序输出建议
+ * @rules 只分析已建表的主题接口（视图不能建索引）；行数低于minRowsForIndex的小表不推荐索引；候选索引最多maxIndexColumns个字段，等值字段在前、范围或排序字段在后；已有索引的前缀字段与候选相同时视为已覆盖；一键创建只允许在共享接口使用的表上、对表中存在的字段执行CREATE INDEX CONCURRENTLY
+ * @dependencies datahub-service/service/models, datahub-service/service/tenant, gorm.io/gorm
+ * @refs service/query_insight/stats.go, api/controllers/query_insight_controller.go
+ */
+
+package query_insight
+
+import (
+	"context"
+	"crypto/sha1"
+	"datahub-service/service/models"
+	"datahub-service/service/tenant"
+	"datahub-service/service/utils"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// statementLimit 每张表读取的语句统计条数
+	statementLimit = 50
+	// slowStatementLimit 分析结果中展示的慢语句条数
+	slowStatementLimit = 5
+	// minRowsForIndex 小表顺序扫描通常更快，不推荐索引
+	minRowsForIndex = 10000
+	// maxIndexColumns 候选索引的最大字段数
+	maxIndexColumns = 3
+	// sampleQueryMaxLen 示例语句的最大长度
+	sampleQueryMaxLen = 500
+	// maxIdentifierLen PostgreSQL标识符最大长度
+	maxIdentifierLen = 63
+)
+
+// 推荐来源
+const (
+	SourceStatements = "statements" // pg_stat_statements语句统计
+	SourceRowPolicy  = "row_policy" // 行级策略的过滤字段，每次访问都会附加
+)
+
+// 分析和创建错误
+var (
+	ErrTableNotShared = errors.New("该接口不是共享接口使用的主题接口表")
+	ErrInvalidColumns = errors.New("索引字段不能为空，且必须是表中存在的字段")
+	ErrIndexCovered   = errors.New("已有索引覆盖这些字段")
+)
+
+// comparisonPattern 字段比较条件，匹配 "col" = / col IN / "t"."col" >= 等形式
+const comparisonPattern = `(?i)(?:^|[\s.(,])"?%s"?\s*(=|>=|<=|>|<|\bIN\b|\bBETWEEN\b)`
+
+// orderByPattern ORDER BY子句
+var orderByPattern = regexp.MustCompile(`(?is)\bORDER\s+BY\s+(.+?)(?:\bLIMIT\b|\bOFFSET\b|\)|$)`)
+
+// IndexRecommendation 索引建议
+type IndexRecommendation struct {
+	Columns     []string `json:"columns"`
+	Source      string   `json:"source" example:"statements"` // statements/row_policy
+	Reason      string   `json:"reason"`
+	Calls       int64    `json:"calls"`         // 涉及语句的调用次数
+	TotalTimeMs float64  `json:"total_time_ms"` // 涉及语句的总耗时
+	SampleQuery string   `json:"sample_query,omitempty"`
+	IndexName   string   `json:"index_name"`
+	DDL         string   `json:"ddl"`
+}
+
+// TableInsight 接口表的查询性能分析
+type TableInsight struct {
+	ThematicInterfaceID string                `json:"thematic_interface_id"`
+	InterfaceName       string                `json:"interface_name"`
+	Schema              string                `json:"schema"`
+	Table               string                `json:"table"`
+	ApiPaths            []string              `json:"api_paths"` // 使用该表的共享接口路径
+	Stats               TableStats            `json:"stats"`
+	SeqScanRatio        float64               `json:"seq_scan_ratio"` // 顺序扫描占全部扫描的比例
+	Indexes             []IndexInfo           `json:"indexes"`
+	SlowStatements      []StatementStat       `json:"slow_statements"`
+	Recommendations     []IndexRecommendation `json:"recommendations"`
+	Notes               []string              `json:"notes,omitempty"`
+}
+
+// CreatedIndex 创建的索引
+type CreatedIndex struct {
+	IndexName string `json:"index_name"`
+	DDL       string `json:"ddl"`
+}
+
+// sharedTable 共享接口使用的主题接口表
+type sharedTable struct {
+	interfaceID   string
+	interfaceName string
+	libraryNameEn string
+	schema        string
+	table         string
+	apiPaths      []string
+}
+
+// Service 查询性能分析服务
+type Service struct {
+	db    *gorm.DB
+	stats StatsSource
+}
+
+// NewService 创建查询性能分析服务实例
+func NewService(db *gorm.DB) *Service {
+	return &Service{db: db, stats: NewPostgresStats(db)}
+}
+
+// ListInsights 分析所有共享接口使用的主题接口表，有索引建议的表排在前面
+func (s *Service) ListInsights(ctx context.Context) ([]TableInsight, error) {
+	tables, err := s.sharedTables(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+
+	insights := make([]TableInsight, 0, len(tables))
+	for _, table := range tables {
+		insight, err := s.analyze(ctx, table)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				continue
+			}
+			return nil, err
+		}
+		insights = append(insights, *insight)
+	}
+	sort.SliceStable(insights, func(i, j int) bool {
+		ti, tj := recommendationTime(insights[i]), recommendationTime(insights[j])
+		if ti != tj {
+			return ti > tj
+		}
+		if len(insights[i].Recommendations) != len(insights[j].Recommendations) {
+			return len(insights[i].Recommendations) > len(insights[j].Recommendations)
+		}
+		return insights[i].SeqScanRatio > insights[j].SeqScanRatio
+	})
+	return insights, nil
+}
+
+// GetInsight 分析指定主题接口表
+func (s *Service) GetInsight(ctx context.Context, thematicInterfaceID string) (*TableInsight, error) {
+	table, err := s.sharedTable(ctx, thematicInterfaceID)
+	if err != nil {
+		return nil, err
+	}
+	return s.analyze(ctx, *table)
+}
+
+// CreateIndex 在共享接口使用的主题接口表上创建索引
+func (s *Service) CreateIndex(ctx context.Context, thematicInterfaceID string, columns []string) (*CreatedIndex, error) {
+	table, err := s.sharedTable(ctx, thematicInterfaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := s.stats.Columns(ctx, table.schema, table.table)
+	if err != nil {
+		return nil, fmt.Errorf("读取表字段失败: %w", err)
+	}
+	if len(columns) == 0 || len(columns) > maxIndexColumns || !allColumnsExist(columns, existing) {
+		return nil, ErrInvalidColumns
+	}
+
+	indexes, err := s.stats.Indexes(ctx, table.schema, table.table)
+	if err != nil {
+		return nil, fmt.Errorf("读取已有索引失败: %w", err)
+	}
+	if covered(indexes, columns) {
+		return nil, ErrIndexCovered
+	}
+
+	name := indexName(table.table, columns)
+	ddl := buildDDL(table.schema, table.table, name, columns)
+	// CONCURRENTLY不能在事务中执行，建索引期间不阻塞共享接口的读写
+	if err := s.db.WithContext(ctx).Exec(ddl).Error; err != nil {
+		return nil, fmt.Errorf("创建索引失败: %w", err)
+	}
+	slog.InfoContext(ctx, "已为共享接口表创建索引", "schema", table.schema, "table", table.table, "index", name)
+	return &CreatedIndex{IndexName: name, DDL: ddl}, nil
+}
+
+// analyze 分析单张表
+func (s *Service) analyze(ctx context.Context, table sharedTable) (*TableInsight, error) {
+	stats, err := s.stats.TableStats(ctx, table.schema, table.table)
+	if err != nil {
+		return nil, err
+	}
+	columns, err := s.stats.Columns(ctx, table.schema, table.table)
+	if err != nil {
+		return nil, fmt.Errorf("读取表字段失败: %w", err)
+	}
+	indexes, err := s.stats.Indexes(ctx, table.schema, table.table)
+	if err != nil {
+		return nil, fmt.Errorf("读取已有索引失败: %w", err)
+	}
+
+	insight := &TableInsight{
+		ThematicInterfaceID: table.interfaceID,
+		InterfaceName:       table.interfaceName,
+		Schema:              table.schema,
+		Table:               table.table,
+		ApiPaths:            table.apiPaths,
+		Stats:               *stats,
+		Indexes:             indexes,
+		SlowStatements:      []StatementStat{},
+		Recommendations:     []IndexRecommendation{},
+	}
+	if insight.Indexes == nil {
+		insight.Indexes = []IndexInfo{}
+	}
+	if scans := stats.SeqScan + stats.IdxScan; scans > 0 {
+		insight.SeqScanRatio = float64(stats.SeqScan) / float64(scans)
+	}
+
+	candidates := make(map[string]*IndexRecommendation)
+	var order []string
+	addCandidate := func(columns []string, source string) *IndexRecommendation {
+		key := strings.Join(columns, ",")
+		candidate, ok := candidates[key]
+		if !ok {
+			candidate = &IndexRecommendation{Columns: columns, Source: source}
+			candidates[key] = candidate
+			order = append(order, key)
+		}
+		return candidate
+	}
+
+	statements, err := s.stats.Statements(ctx, table.schema, table.table, statementLimit)
+	switch {
+	case errors.Is(err, ErrStatementsUnavailable):
+		insight.Notes = append(insight.Notes, err.Error())
+	case err != nil:
+		return nil, fmt.Errorf("读取语句统计失败: %w", err)
+	}
+	for i, statement := range statements {
+		if i < slowStatementLimit {
+			insight.SlowStatements = append(insight.SlowStatements, statement)
+		}
+		indexColumns := statementIndexColumns(statement.Query, columns)
+		if len(indexColumns) == 0 {
+			continue
+		}
+		candidate := addCandidate(indexColumns, SourceStatements)
+		candidate.Calls += statement.Calls
+		candidate.TotalTimeMs += statement.TotalTimeMs
+		if candidate.SampleQuery == "" {
+			candidate.SampleQuery = truncate(statement.Query, sampleQueryMaxLen)
+		}
+	}
+
+	for _, field := range s.policyFields(ctx, table, columns) {
+		addCandidate([]string{field}, SourceRowPolicy)
+	}
+
+	if stats.RowCount < minRowsForIndex {
+		if len(candidates) > 0 {
+			insight.Notes = append(insight.Notes, fmt.Sprintf("表估算行数%d低于%d，顺序扫描通常更快，暂不推荐索引", stats.RowCount, minRowsForIndex))
+		}
+		return insight, nil
+	}
+
+	for _, key := range order {
+		candidate := candidates[key]
+		if covered(indexes, candidate.Columns) {
+			continue
+		}
+		candidate.IndexName = indexName(table.table, candidate.Columns)
+		candidate.DDL = buildDDL(table.schema, table.table, candidate.IndexName, candidate.Columns)
+		if candidate.Source == SourceRowPolicy {
+			candidate.Reason = fmt.Sprintf("行级策略按字段%s过滤，每次访问都会附加该条件", candidate.Columns[0])
+		} else {
+			candidate.Reason = fmt.Sprintf("%d次调用按字段%s过滤或排序，累计耗时%.0fms", candidate.Calls, strings.Join(candidate.Columns, ", "), candidate.TotalTimeMs)
+		}
+		insight.Recommendations = append(insight.Recommendations, *candidate)
+	}
+	sort.SliceStable(insight.Recommendations, func(i, j int) bool {
+		return insight.Recommendations[i].TotalTimeMs > insight.Recommendations[j].TotalTimeMs
+	})
+	return insight, nil
+}
+
+// sharedTables 查询启用的共享接口使用的主题接口表，interfaceID不为空时只查询该接口
+func (s *Service) sharedTables(ctx context.Context, interfaceID string) ([]sharedTable, error) {
+	var apiInterfaces []models.ApiInterface
+	query := s.db.WithContext(ctx).Select("id", "thematic_interface_id", "path").Where("status = ?", "active")
+	if interfaceID != "" {
+		query = query.Where("thematic_interface_id = ?", interfaceID)
+	}
+	if err := query.Order("path").Find(&apiInterfaces).Error; err != nil {
+		return nil, fmt.Errorf("查询共享接口失败: %w", err)
+	}
+
+	paths := make(map[string][]string)
+	var interfaceIDs []string
+	for _, apiInterface := range apiInterfaces {
+		if _, ok := paths[apiInterface.ThematicInterfaceID]; !ok {
+			interfaceIDs = append(interfaceIDs, apiInterface.ThematicInterfaceID)
+		}
+		paths[apiInterface.ThematicInterfaceID] = append(paths[apiInterface.ThematicInterfaceID], apiInterface.Path)
+	}
+	if len(interfaceIDs) == 0 {
+		return nil, nil
+	}
+
+	var interfaces []models.ThematicInterface
+	err := s.db.WithContext(ctx).Select("id", "library_id", "name_zh", "name_en").
+		Scopes(tenant.OwnerScope(ctx, "library_id", "thematic_libraries")).
+		Where("id IN ? AND type = ? AND is_table_created = ?", interfaceIDs, "table", true).
+		Preload("ThematicLibrary").
+		Order("name_en").
+		Find(&interfaces).Error
+	if err != nil {
+		return nil, fmt.Errorf("查询主题接口失败: %w", err)
+	}
+
+	tables := make([]sharedTable, 0, len(interfaces))
+	for _, iface := range interfaces {
+		if iface.ThematicLibrary.ID == "" {
+			continue
+		}
+		tables = append(tables, sharedTable{
+			interfaceID:   iface.ID,
+			interfaceName: iface.NameZh,
+			libraryNameEn: iface.ThematicLibrary.NameEn,
+			schema:        iface.ThematicLibrary.GetSchemaName(),
+			table:         iface.NameEn,
+			apiPaths:      paths[iface.ID],
+		})
+	}
+	return tables, nil
+}
+
+// sharedTable 查询单个共享接口使用的主题接口表
+func (s *Service) sharedTable(ctx context.Context, thematicInterfaceID string) (*sharedTable, error) {
+	tables, err := s.sharedTables(ctx, thematicInterfaceID)
+	if err != nil {
+		return nil, err
+	}
+	if len(tables) == 0 {
+		return nil, ErrTableNotShared
+	}
+	return &tables[0], nil
+}
+
+// policyFields 启用的行级策略中可用索引加速的过滤字段
+func (s *Service) policyFields(ctx context.Context, table sharedTable, columns []string) []string {
+	var policies []models.RowLevelPolicy
+	err := s.db.WithContext(ctx).Select("conditions").
+		Where("schema_name IN ? AND table_name = ? AND is_enabled = ?", []string{table.schema, table.libraryNameEn}, table.table, true).
+		Find(&policies).Error
+	if err != nil {
+		slog.WarnContext(ctx, "查询行级策略失败", "schema", table.schema, "table", table.table, "error", err)
+		return nil
+	}
+
+	var fields []string
+	for _, policy := range policies {
+		for _, condition := range policy.Conditions {
+			field, _ := condition["field"].(string)
+			operator, _ := condition["operator"].(string)
+			switch operator {
+			case "eq", "in", "gt", "gte", "lt", "lte":
+			default:
+				continue
+			}
+			if slices.Contains(columns, field) && !slices.Contains(fields, field) {
+				fields = append(fields, field)
+			}
+		}
+	}
+	return fields
+}
+
+// statementIndexColumns 从语句中提取候选索引字段：等值字段在前，其后为第一个范围字段，没有范围字段时为第一个排序字段
+func statementIndexColumns(query string, columns []string) []string {
+	var equality, ranged []string
+	for _, column := range columns {
+		pattern, err := regexp.Compile(fmt.Sprintf(comparisonPattern, regexp.QuoteMeta(column)))
+		if err != nil {
+			continue
+		}
+		for _, match := range pattern.FindAllStringSubmatch(query, -1) {
+			switch strings.ToUpper(match[1]) {
+			case "=", "IN":
+				if !slices.Contains(equality, column) {
+					equality = append(equality, column)
+				}
+			default:
+				if !slices.Contains(ranged, column) {
+					ranged = append(ranged, column)
+				}
+			}
+		}
+	}
+
+	result := append([]string{}, equality...)
+	trailing := ""
+	for _, column := range ranged {
+		if !slices.Contains(result, column) {
+			trailing = column
+			break
+		}
+	}
+	if trailing == "" {
+		trailing = firstOrderColumn(query, columns, result)
+	}
+	if trailing != "" {
+		result = append(result, trailing)
+	}
+	if len(result) > maxIndexColumns {
+		result = append(result[:maxIndexColumns-1], result[len(result)-1])
+	}
+	return result
+}
+
+// firstOrderColumn 语句ORDER BY中第一个表字段，已在exclude中的字段跳过
+func firstOrderColumn(query string, columns, exclude []string) string {
+	match := orderByPattern.FindStringSubmatch(query)
+	if match == nil {
+		return ""
+	}
+	first := strings.TrimSpace(strings.Split(match[1], ",")[0])
+	if parts := strings.Split(first, "."); len(parts) > 1 {
+		first = parts[len(parts)-1]
+	}
+	first = strings.Trim(strings.Fields(first + " ")[0], `"`)
+	if slices.Contains(columns, first) && !slices.Contains(exclude, first) {
+		return first
+	}
+	return ""
+}
+
+// covered 已有索引的前缀字段与候选相同时视为已覆盖
+func covered(indexes []IndexInfo, columns []string) bool {
+	for _, index := range indexes {
+		if len(index.Columns) < len(columns) {
+			continue
+		}
+		matched := true
+		for i, column := range columns {
+			if index.Columns[i] != column {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return true
+		}
+	}
+	return false
+}
+
+// indexName 生成索引名，超过标识符长度时截断并附加哈希
+func indexName(table string, columns []string) string {
+	name := "idx_" + table + "_" + strings.Join(columns, "_")
+	if len(name) <= maxIdentifierLen {
+		return name
+	}
+	sum := sha1.Sum([]byte(name))
+	suffix := "_" + hex.EncodeToString(sum[:])[:8]
+	return name[:maxIdentifierLen-len(suffix)] + suffix
+}
+
+// buildDDL 生成建索引语句
+func buildDDL(schema, table, name string, columns []string) string {
+	quoted := make([]string, len(columns))
+	for i, column := range columns {
+		quoted[i] = utils.QuoteIdent(column)
+	}
+	return fmt.Sprintf("CREATE INDEX CONCURRENTLY IF NOT EXISTS %s ON %s.%s (%s)",
+		utils.QuoteIdent(name), utils.QuoteIdent(schema), utils.QuoteIdent(table), strings.Join(quoted, ", "))
+}
+
+// recommendationTime 表的索引建议涉及的总耗时
+func recommendationTime(insight TableInsight) float64 {
+	var total float64
+	for _, recommendation := range insight.Recommendations {
+		total += recommendation.TotalTimeMs
+	}
+	return total
+}
+
+// allColumnsExist 字段都存在且不重复
+func allColumnsExist(columns, existing []string) bool {
+	seen := make(map[string]bool, len(columns))
+	for _, column := range columns {
+		if seen[column] || !slices.Contains(existing, column) {
+			return false
+		}
+		seen[column] = true
+	}
+	return true
+}
+
+// truncate 按字符数截断
+func truncate(text string, maxLen int) string {
+	runes := []rune(text)
+	if len(runes) <= maxLen {
+		return text
+	}
+	return string(runes[:maxLen]) + "..."
+}
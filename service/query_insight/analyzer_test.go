@@ -0,0 +1,233 @@
+/*
+ * @module service/query_insight/analyzer_test
+ * @description 查询性能分析测试，覆盖语句字段提取、已有索引覆盖判断、行级策略字段、小表不推荐以及一键创建的校验
+ * @architecture 测试层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow 准备sqlite中的主题接口和共享接口 -> 使用模拟统计来源分析 -> 验证索引建议
+ * @rules 使用内存sqlite和模拟统计来源，不依赖PostgreSQL系统视图
+ * @dependencies gorm.io/driver/sqlite, stretchr/testify
+ * @refs analyzer.go, stats.go
+ */
+
+package query_insight
+
+import (
+	"context"
+	"datahub-service/service/models"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// fakeTable 模拟表的统计信息
+type fakeTable struct {
+	stats      TableStats
+	columns    []string
+	indexes    []IndexInfo
+	statements []StatementStat
+}
+
+// fakeStats 按"schema.table"返回预设的统计信息
+type fakeStats struct {
+	tables           map[string]*fakeTable
+	statementsAbsent bool
+}
+
+func (f *fakeStats) table(schema, table string) (*fakeTable, error) {
+	t, ok := f.tables[schema+"."+table]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return t, nil
+}
+
+func (f *fakeStats) TableStats(ctx context.Context, schema, table string) (*TableStats, error) {
+	t, err := f.table(schema, table)
+	if err != nil {
+		return nil, err
+	}
+	return &t.stats, nil
+}
+
+func (f *fakeStats) Columns(ctx context.Context, schema, table string) ([]string, error) {
+	t, err := f.table(schema, table)
+	if err != nil {
+		return nil, nil
+	}
+	return t.columns, nil
+}
+
+func (f *fakeStats) Indexes(ctx context.Context, schema, table string) ([]IndexInfo, error) {
+	t, err := f.table(schema, table)
+	if err != nil {
+		return nil, nil
+	}
+	return t.indexes, nil
+}
+
+func (f *fakeStats) Statements(ctx context.Context, schema, table string, limit int) ([]StatementStat, error) {
+	if f.statementsAbsent {
+		return nil, ErrStatementsUnavailable
+	}
+	t, err := f.table(schema, table)
+	if err != nil {
+		return nil, nil
+	}
+	return t.statements, nil
+}
+
+func setupInsightService(t *testing.T) (*Service, *fakeStats) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(
+		&models.ThematicLibrary{}, &models.ThematicInterface{}, &models.ApiInterface{}, &models.RowLevelPolicy{},
+	))
+
+	require.NoError(t, db.Create(&models.ThematicLibrary{ID: "lib-1", NameZh: "人口主题库", NameEn: "population", Category: "business", Domain: "user"}).Error)
+	for _, iface := range []models.ThematicInterface{
+		{ID: "ti-1", LibraryID: "lib-1", NameZh: "人员", NameEn: "person", Type: "table", IsTableCreated: true},
+		{ID: "ti-2", LibraryID: "lib-1", NameZh: "字典", NameEn: "dict", Type: "table", IsTableCreated: true},
+		{ID: "ti-3", LibraryID: "lib-1", NameZh: "人员视图", NameEn: "person_view", Type: "view", IsViewCreated: true},
+		{ID: "ti-4", LibraryID: "lib-1", NameZh: "未共享", NameEn: "private", Type: "table", IsTableCreated: true},
+	} {
+		require.NoError(t, db.Create(&iface).Error)
+	}
+	for _, api := range []models.ApiInterface{
+		{ApiApplicationID: "app-1", ThematicInterfaceID: "ti-1", Path: "persons", Status: "active"},
+		{ApiApplicationID: "app-2", ThematicInterfaceID: "ti-1", Path: "residents", Status: "active"},
+		{ApiApplicationID: "app-1", ThematicInterfaceID: "ti-2", Path: "dict", Status: "active"},
+		{ApiApplicationID: "app-1", ThematicInterfaceID: "ti-3", Path: "person-view", Status: "active"},
+		{ApiApplicationID: "app-1", ThematicInterfaceID: "ti-4", Path: "private", Status: "inactive"},
+	} {
+		require.NoError(t, db.Create(&api).Error)
+	}
+	require.NoError(t, db.Create(&models.RowLevelPolicy{
+		Name: "按区县过滤", SchemaName: "population", TableName: "person", SubjectType: "application", SubjectID: "app-1",
+		Conditions: models.JSONBArray{{"field": "district", "operator": "eq", "value": "${subject.district}"}},
+		IsEnabled:  true,
+	}).Error)
+
+	stats := &fakeStats{tables: map[string]*fakeTable{
+		"population.person": {
+			stats:   TableStats{RowCount: 2000000, SeqScan: 90, IdxScan: 10},
+			columns: []string{"id", "id_card", "name", "district", "status", "updated_at"},
+			indexes: []IndexInfo{{Name: "person_pkey", Columns: []string{"id"}, Unique: true, Primary: true}},
+			statements: []StatementStat{
+				{Query: `SELECT * FROM "population"."person" WHERE "status" = $1 AND "updated_at" >= $2 ORDER BY "name" LIMIT $3`, Calls: 100, TotalTimeMs: 9000},
+				{Query: `SELECT * FROM population.person WHERE id_card = $1`, Calls: 500, TotalTimeMs: 3000},
+				{Query: `SELECT * FROM population.person WHERE id_card = $1 LIMIT $2`, Calls: 200, TotalTimeMs: 1000},
+				{Query: `SELECT * FROM population.person WHERE id = $1`, Calls: 900, TotalTimeMs: 100},
+				{Query: `SELECT count(*) FROM population.person`, Calls: 10, TotalTimeMs: 50},
+			},
+		},
+		"population.dict": {
+			stats:      TableStats{RowCount: 300, SeqScan: 50},
+			columns:    []string{"code", "label"},
+			statements: []StatementStat{{Query: `SELECT * FROM population.dict WHERE code = $1`, Calls: 10, TotalTimeMs: 5}},
+		},
+	}}
+	s := NewService(db)
+	s.stats = stats
+	return s, stats
+}
+
+func TestStatementIndexColumns(t *testing.T) {
+	columns := []string{"id", "status", "updated_at", "name", "district"}
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{`SELECT * FROM t WHERE "status" = $1 AND "updated_at" >= $2`, []string{"status", "updated_at"}},
+		{`SELECT * FROM t WHERE status = $1 ORDER BY name DESC LIMIT 10`, []string{"status", "name"}},
+		{`SELECT * FROM t WHERE t.district IN ($1, $2) AND status=$3`, []string{"status", "district"}},
+		{`SELECT * FROM t ORDER BY "t"."updated_at"`, []string{"updated_at"}},
+		{`SELECT * FROM t WHERE lower(name) LIKE $1`, nil},
+	}
+	for _, tt := range tests {
+		got := statementIndexColumns(tt.query, columns)
+		assert.ElementsMatch(t, tt.want, got, tt.query)
+		if len(tt.want) > 0 {
+			assert.Equal(t, tt.want[len(tt.want)-1], got[len(got)-1], "范围或排序字段在最后: %s", tt.query)
+		}
+	}
+}
+
+func TestListInsights(t *testing.T) {
+	s, _ := setupInsightService(t)
+
+	insights, err := s.ListInsights(context.Background())
+	require.NoError(t, err)
+	require.Len(t, insights, 2, "只分析启用的共享接口使用的已建表接口")
+
+	person := insights[0]
+	assert.Equal(t, "ti-1", person.ThematicInterfaceID)
+	assert.Equal(t, []string{"persons", "residents"}, person.ApiPaths)
+	assert.InDelta(t, 0.9, person.SeqScanRatio, 0.001)
+	assert.Len(t, person.SlowStatements, 5)
+
+	require.Len(t, person.Recommendations, 3, "主键已覆盖id，count语句没有过滤字段")
+	first := person.Recommendations[0]
+	assert.Equal(t, []string{"status", "updated_at"}, first.Columns)
+	assert.Equal(t, SourceStatements, first.Source)
+	assert.Equal(t, `CREATE INDEX CONCURRENTLY IF NOT EXISTS "idx_person_status_updated_at" ON "population"."person" ("status", "updated_at")`, first.DDL)
+
+	second := person.Recommendations[1]
+	assert.Equal(t, []string{"id_card"}, second.Columns)
+	assert.Equal(t, int64(700), second.Calls, "相同字段的语句合并统计")
+	assert.Equal(t, float64(4000), second.TotalTimeMs)
+
+	policy := person.Recommendations[2]
+	assert.Equal(t, []string{"district"}, policy.Columns)
+	assert.Equal(t, SourceRowPolicy, policy.Source)
+
+	dict := insights[1]
+	assert.Empty(t, dict.Recommendations, "小表不推荐索引")
+	assert.NotEmpty(t, dict.Notes)
+}
+
+func TestGetInsightWithoutStatements(t *testing.T) {
+	s, stats := setupInsightService(t)
+	stats.statementsAbsent = true
+
+	insight, err := s.GetInsight(context.Background(), "ti-1")
+	require.NoError(t, err)
+	assert.Contains(t, insight.Notes, ErrStatementsUnavailable.Error())
+	require.Len(t, insight.Recommendations, 1, "没有语句统计时仍按行级策略给出建议")
+	assert.Equal(t, []string{"district"}, insight.Recommendations[0].Columns)
+
+	_, err = s.GetInsight(context.Background(), "ti-3")
+	assert.ErrorIs(t, err, ErrTableNotShared, "视图不能建索引")
+	_, err = s.GetInsight(context.Background(), "ti-4")
+	assert.ErrorIs(t, err, ErrTableNotShared, "停用的共享接口不分析")
+}
+
+func TestCreateIndexValidation(t *testing.T) {
+	s, _ := setupInsightService(t)
+	ctx := context.Background()
+
+	_, err := s.CreateIndex(ctx, "ti-4", []string{"id"})
+	assert.ErrorIs(t, err, ErrTableNotShared)
+	_, err = s.CreateIndex(ctx, "ti-1", nil)
+	assert.ErrorIs(t, err, ErrInvalidColumns)
+	_, err = s.CreateIndex(ctx, "ti-1", []string{"status", "missing"})
+	assert.ErrorIs(t, err, ErrInvalidColumns)
+	_, err = s.CreateIndex(ctx, "ti-1", []string{"status", "status"})
+	assert.ErrorIs(t, err, ErrInvalidColumns)
+	_, err = s.CreateIndex(ctx, "ti-1", []string{"id"})
+	assert.ErrorIs(t, err, ErrIndexCovered)
+}
+
+func TestIndexName(t *testing.T) {
+	assert.Equal(t, "idx_person_status", indexName("person", []string{"status"}))
+
+	long := indexName(strings.Repeat("t", 40), []string{strings.Repeat("a", 20), strings.Repeat("b", 20)})
+	assert.Len(t, long, maxIdentifierLen)
+	assert.NotEqual(t, long, indexName(strings.Repeat("t", 40), []string{strings.Repeat("a", 20), strings.Repeat("c", 20)}), "截断后仍区分不同字段")
+}
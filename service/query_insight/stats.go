@@ -0,0 +1,153 @@
+/*
+ * @module service/query_insight/stats
+ * @description 查询统计来源，从PostgreSQL系统视图读取接口表的扫描统计、已有索引、字段和pg_stat_statements中涉及该表的语句统计
+ * @architecture 分层架构 - 业务服务层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow pg_stat_user_tables -> 扫描统计；pg_index/pg_attribute -> 已有索引；information_schema.columns -> 字段；pg_stat_statements -> 语句统计
+ * @rules 只读系统视图；未安装pg_stat_statements扩展时返回ErrStatementsUnavailable，分析器仍可基于其他启发式规则给出建议
+ * @dependencies gorm.io/gorm
+ * @refs service/query_insight/analyzer.go
+ */
+
+package query_insight
+
+import (
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+// ErrStatementsUnavailable 未安装pg_stat_statements扩展
+var ErrStatementsUnavailable = errors.New("未安装pg_stat_statements扩展，无法分析语句统计")
+
+// TableStats 表的扫描统计
+type TableStats struct {
+	RowCount   int64 `json:"row_count"` // 估算行数
+	SeqScan    int64 `json:"seq_scan"`  // 顺序扫描次数
+	SeqTupRead int64 `json:"seq_tup_read"`
+	IdxScan    int64 `json:"idx_scan"` // 索引扫描次数
+}
+
+// IndexInfo 已有索引
+type IndexInfo struct {
+	Name    string   `json:"name"`
+	Columns []string `json:"columns"` // 按索引顺序的字段，表达式索引的表达式部分不计入
+	Unique  bool     `json:"unique"`
+	Primary bool     `json:"primary"`
+}
+
+// StatementStat pg_stat_statements中的语句统计
+type StatementStat struct {
+	Query       string  `json:"query"`
+	Calls       int64   `json:"calls"`
+	TotalTimeMs float64 `json:"total_time_ms"`
+	MeanTimeMs  float64 `json:"mean_time_ms"`
+	Rows        int64   `json:"rows"`
+}
+
+// StatsSource 查询统计来源
+type StatsSource interface {
+	// TableStats 读取表的扫描统计，表不存在时返回gorm.ErrRecordNotFound
+	TableStats(ctx context.Context, schema, table string) (*TableStats, error)
+	// Columns 读取表的字段名
+	Columns(ctx context.Context, schema, table string) ([]string, error)
+	// Indexes 读取表的已有索引
+	Indexes(ctx context.Context, schema, table string) ([]IndexInfo, error)
+	// Statements 读取涉及该表的语句统计，按总耗时倒序
+	Statements(ctx context.Context, schema, table string, limit int) ([]StatementStat, error)
+}
+
+// PostgresStats 基于PostgreSQL系统视图的统计来源
+type PostgresStats struct {
+	db *gorm.DB
+}
+
+// NewPostgresStats 创建PostgreSQL统计来源
+func NewPostgresStats(db *gorm.DB) *PostgresStats {
+	return &PostgresStats{db: db}
+}
+
+// TableStats 读取表的扫描统计
+func (p *PostgresStats) TableStats(ctx context.Context, schema, table string) (*TableStats, error) {
+	var stats []TableStats
+	err := p.db.WithContext(ctx).Raw(`
+		SELECT COALESCE(n_live_tup, 0) AS row_count, COALESCE(seq_scan, 0) AS seq_scan,
+			COALESCE(seq_tup_read, 0) AS seq_tup_read, COALESCE(idx_scan, 0) AS idx_scan
+		FROM pg_stat_user_tables
+		WHERE schemaname = ? AND relname = ?`, schema, table).
+		Scan(&stats).Error
+	if err != nil {
+		return nil, err
+	}
+	if len(stats) == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &stats[0], nil
+}
+
+// Columns 读取表的字段名
+func (p *PostgresStats) Columns(ctx context.Context, schema, table string) ([]string, error) {
+	var columns []string
+	err := p.db.WithContext(ctx).Raw(`
+		SELECT column_name FROM information_schema.columns
+		WHERE table_schema = ? AND table_name = ?
+		ORDER BY ordinal_position`, schema, table).
+		Scan(&columns).Error
+	return columns, err
+}
+
+// Indexes 读取表的已有索引
+func (p *PostgresStats) Indexes(ctx context.Context, schema, table string) ([]IndexInfo, error) {
+	var rows []struct {
+		IndexName string
+		Column    string
+		Unique    bool
+		Primary   bool
+	}
+	err := p.db.WithContext(ctx).Raw(`
+		SELECT ic.relname AS index_name, a.attname AS column, i.indisunique AS unique, i.indisprimary AS primary
+		FROM pg_index i
+		JOIN pg_class c ON c.oid = i.indrelid
+		JOIN pg_namespace n ON n.oid = c.relnamespace
+		JOIN pg_class ic ON ic.oid = i.indexrelid
+		CROSS JOIN LATERAL unnest(i.indkey) WITH ORDINALITY AS k(attnum, ord)
+		JOIN pg_attribute a ON a.attrelid = c.oid AND a.attnum = k.attnum
+		WHERE n.nspname = ? AND c.relname = ?
+		ORDER BY ic.relname, k.ord`, schema, table).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	var indexes []IndexInfo
+	for _, row := range rows {
+		if len(indexes) == 0 || indexes[len(indexes)-1].Name != row.IndexName {
+			indexes = append(indexes, IndexInfo{Name: row.IndexName, Unique: row.Unique, Primary: row.Primary})
+		}
+		last := &indexes[len(indexes)-1]
+		last.Columns = append(last.Columns, row.Column)
+	}
+	return indexes, nil
+}
+
+// Statements 读取涉及该表的语句统计，按总耗时倒序
+func (p *PostgresStats) Statements(ctx context.Context, schema, table string, limit int) ([]StatementStat, error) {
+	var installed bool
+	if err := p.db.WithContext(ctx).Raw(`SELECT to_regclass('pg_stat_statements') IS NOT NULL`).Scan(&installed).Error; err != nil {
+		return nil, err
+	}
+	if !installed {
+		return nil, ErrStatementsUnavailable
+	}
+
+	var statements []StatementStat
+	err := p.db.WithContext(ctx).Raw(`
+		SELECT query, calls, total_exec_time AS total_time_ms, mean_exec_time AS mean_time_ms, rows
+		FROM pg_stat_statements
+		WHERE query ILIKE ? OR query ILIKE ?
+		ORDER BY total_exec_time DESC
+		LIMIT ?`, "%"+schema+"."+table+"%", `%"`+schema+`"."`+table+`"%`, limit).
+		Scan(&statements).Error
+	return statements, err
+}
@@ -0,0 +1,495 @@
+/*
+ * @module service/rbac/rbac_service
+ * @description 基于角色的访问控制服务，负责角色权限管理、用户角色分配和权限判定
+ * @architecture 分层架构 - 业务服务层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow JWT角色 + 数据库分配角色 -> 有效角色 -> 角色权限 -> 鉴权结果
+ * @rules 权限以"资源:操作"表示，admin拥有全部权限，未分配角色的用户使用默认角色
+ * @dependencies datahub-service/service/models, gorm.io/gorm
+ * @refs api/middleware/rbac.go, api/controllers/rbac_controller.go
+ */
+
+package rbac
+
+import (
+	"datahub-service/service/models"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// 受保护的资源
+const (
+	ResourceBasicLibrary    = "basic_library"
+	ResourceThematicLibrary = "thematic_library"
+	ResourceSyncTask        = "sync_task"
+	ResourceThematicSync    = "thematic_sync"
+	ResourceQualityRule     = "quality_rule"
+	ResourceQualityTask     = "quality_task"
+	ResourceMaskingRule     = "masking_rule"
+	ResourceCleansingRule   = "cleansing_rule"
+	ResourceMetadata        = "metadata"
+	ResourceSharing         = "sharing"
+	ResourceDataView        = "data_view"
+	ResourceMonitoring      = "monitoring"
+	ResourceDashboard       = "dashboard"
+	ResourceEvent           = "event"
+	ResourceTable           = "table"
+	ResourceConfig          = "config"
+	ResourceRBAC            = "rbac"
+	ResourceChaos           = "chaos"
+	ResourceTenant          = "tenant"
+	ResourceBackup          = "backup"
+	ResourceEncryption      = "encryption"
+	ResourceNotification    = "notification"
+	ResourceCapacity        = "capacity"
+	ResourceWorkbench       = "sql_workbench"
+	ResourceMetricStore     = "metric_store"
+	ResourceReport          = "report_subscription"
+	ResourceUDF             = "udf"
+	ResourceWebhook         = "webhook"
+	ResourceCompliance      = "compliance"
+)
+
+// RoleDescriptions 内置角色说明
+var RoleDescriptions = map[string]string{
+	models.RoleAdmin:     "管理员，拥有全部权限",
+	models.RoleSteward:   "数据管理员，负责数据治理规则和元数据维护",
+	models.RoleDeveloper: "开发者，负责数据源、接口和同步任务的开发配置",
+	models.RoleConsumer:  "数据使用者，只读访问数据目录和共享数据",
+}
+
+// PermissionItem 权限项
+type PermissionItem struct {
+	Resource string `json:"resource"`
+	Action   string `json:"action"`
+}
+
+// RoleInfo 角色信息
+type RoleInfo struct {
+	Name        string           `json:"name"`
+	Description string           `json:"description"`
+	Permissions []PermissionItem `json:"permissions"`
+}
+
+// EffectiveAccess 用户的有效角色和权限
+type EffectiveAccess struct {
+	Username    string           `json:"username"`
+	Roles       []string         `json:"roles"`
+	Permissions []PermissionItem `json:"permissions"`
+}
+
+// defaultRolePermissions 内置角色的默认权限矩阵
+var defaultRolePermissions = map[string][]PermissionItem{
+	models.RoleAdmin: {
+		{Resource: models.PermissionWildcard, Action: models.PermissionWildcard},
+	},
+	models.RoleSteward: {
+		{Resource: models.PermissionWildcard, Action: models.ActionRead},
+		{Resource: ResourceQualityRule, Action: models.PermissionWildcard},
+		{Resource: ResourceQualityTask, Action: models.PermissionWildcard},
+		{Resource: ResourceMaskingRule, Action: models.PermissionWildcard},
+		{Resource: ResourceCleansingRule, Action: models.PermissionWildcard},
+		{Resource: ResourceMetadata, Action: models.PermissionWildcard},
+		{Resource: ResourceSharing, Action: models.PermissionWildcard},
+		{Resource: ResourceBackup, Action: models.ActionExecute},
+		{Resource: ResourceNotification, Action: models.PermissionWildcard},
+		{Resource: ResourceWorkbench, Action: models.PermissionWildcard},
+		{Resource: ResourceMetricStore, Action: models.PermissionWildcard},
+		{Resource: ResourceReport, Action: models.PermissionWildcard},
+		{Resource: ResourceUDF, Action: models.ActionExecute},
+		{Resource: ResourceThematicLibrary, Action: models.ActionWrite},
+		{Resource: ResourceThematicLibrary, Action: models.ActionExecute},
+	},
+	models.RoleDeveloper: {
+		{Resource: models.PermissionWildcard, Action: models.ActionRead},
+		{Resource: ResourceBasicLibrary, Action: models.PermissionWildcard},
+		{Resource: ResourceThematicLibrary, Action: models.PermissionWildcard},
+		{Resource: ResourceSyncTask, Action: models.PermissionWildcard},
+		{Resource: ResourceThematicSync, Action: models.PermissionWildcard},
+		{Resource: ResourceTable, Action: models.PermissionWildcard},
+		{Resource: ResourceQualityRule, Action: models.ActionExecute},
+		{Resource: ResourceEvent, Action: models.ActionWrite},
+		{Resource: ResourceMonitoring, Action: models.ActionExecute},
+		{Resource: ResourceWorkbench, Action: models.PermissionWildcard},
+		{Resource: ResourceMetricStore, Action: models.PermissionWildcard},
+		{Resource: ResourceReport, Action: models.PermissionWildcard},
+		{Resource: ResourceWebhook, Action: models.PermissionWildcard},
+	},
+	models.RoleConsumer: {
+		{Resource: ResourceBasicLibrary, Action: models.ActionRead},
+		{Resource: ResourceThematicLibrary, Action: models.ActionRead},
+		{Resource: ResourceSharing, Action: models.ActionRead},
+		{Resource: ResourceDataView, Action: models.ActionRead},
+		{Resource: ResourceMetadata, Action: models.ActionRead},
+		{Resource: ResourceDashboard, Action: models.ActionRead},
+		{Resource: ResourceEvent, Action: models.ActionRead},
+		{Resource: ResourceNotification, Action: models.ActionRead},
+		{Resource: ResourceMetricStore, Action: models.ActionRead},
+	},
+}
+
+// userRoleCacheTTL 用户角色缓存时间，多实例部署时限制角色变更的生效延迟
+const userRoleCacheTTL = time.Minute
+
+type userRoleCacheEntry struct {
+	roles     []string
+	expiresAt time.Time
+}
+
+// RBACService 访问控制服务
+type RBACService struct {
+	db          *gorm.DB
+	enabled     bool
+	defaultRole string
+
+	mu              sync.RWMutex
+	rolePermissions map[string]map[string]bool // role -> "resource:action"
+	userRoleCache   map[string]*userRoleCacheEntry
+}
+
+// NewRBACService 创建访问控制服务实例
+func NewRBACService(db *gorm.DB) *RBACService {
+	enabled := os.Getenv("RBAC_ENABLED") != "false"
+	defaultRole := os.Getenv("RBAC_DEFAULT_ROLE")
+	if defaultRole == "" {
+		defaultRole = models.RoleConsumer
+	}
+
+	s := &RBACService{
+		db:              db,
+		enabled:         enabled,
+		defaultRole:     defaultRole,
+		rolePermissions: buildPermissionIndex(defaultRolePermissions),
+		userRoleCache:   make(map[string]*userRoleCacheEntry),
+	}
+
+	if err := s.initRolePermissions(); err != nil {
+		slog.Error("初始化角色权限失败，使用内置默认权限", "error", err)
+	}
+
+	return s
+}
+
+// IsEnabled 是否启用权限控制
+func (s *RBACService) IsEnabled() bool {
+	return s.enabled
+}
+
+// IsValidRole 判断是否为内置角色
+func IsValidRole(role string) bool {
+	_, ok := RoleDescriptions[role]
+	return ok
+}
+
+// initRolePermissions 初始化角色权限，数据库中没有记录的角色写入默认权限
+func (s *RBACService) initRolePermissions() error {
+	for role, perms := range defaultRolePermissions {
+		var count int64
+		if err := s.db.Model(&models.RolePermission{}).Where("role = ?", role).Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			continue
+		}
+		for _, perm := range perms {
+			record := &models.RolePermission{Role: role, Resource: perm.Resource, Action: perm.Action}
+			if err := s.db.Create(record).Error; err != nil {
+				return fmt.Errorf("写入角色 %s 默认权限失败: %w", role, err)
+			}
+		}
+	}
+
+	return s.ReloadPermissions()
+}
+
+// ReloadPermissions 从数据库重新加载角色权限
+func (s *RBACService) ReloadPermissions() error {
+	var records []models.RolePermission
+	if err := s.db.Find(&records).Error; err != nil {
+		return fmt.Errorf("查询角色权限失败: %w", err)
+	}
+
+	perms := make(map[string][]PermissionItem)
+	for _, record := range records {
+		perms[record.Role] = append(perms[record.Role], PermissionItem{Resource: record.Resource, Action: record.Action})
+	}
+
+	s.mu.Lock()
+	s.rolePermissions = buildPermissionIndex(perms)
+	s.mu.Unlock()
+	return nil
+}
+
+// GetRoles 获取所有角色及其权限
+func (s *RBACService) GetRoles() []RoleInfo {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	roles := make([]RoleInfo, 0, len(RoleDescriptions))
+	for name, description := range RoleDescriptions {
+		roles = append(roles, RoleInfo{
+			Name:        name,
+			Description: description,
+			Permissions: permissionItems(s.rolePermissions[name]),
+		})
+	}
+	sort.Slice(roles, func(i, j int) bool { return roles[i].Name < roles[j].Name })
+	return roles
+}
+
+// SetRolePermissions 替换角色的权限配置
+func (s *RBACService) SetRolePermissions(role string, perms []PermissionItem, operator string) error {
+	if !IsValidRole(role) {
+		return fmt.Errorf("角色不存在: %s", role)
+	}
+	for _, perm := range perms {
+		if perm.Resource == "" || perm.Action == "" {
+			return errors.New("权限的资源和操作不能为空")
+		}
+	}
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("role = ?", role).Delete(&models.RolePermission{}).Error; err != nil {
+			return err
+		}
+		for _, perm := range perms {
+			record := &models.RolePermission{
+				Role:      role,
+				Resource:  perm.Resource,
+				Action:    perm.Action,
+				CreatedBy: operator,
+			}
+			if err := tx.Create(record).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("更新角色权限失败: %w", err)
+	}
+
+	return s.ReloadPermissions()
+}
+
+// GetRoleAssignments 获取用户角色分配列表
+func (s *RBACService) GetRoleAssignments(page, pageSize int, username, role string) ([]models.UserRoleAssignment, int64, error) {
+	var assignments []models.UserRoleAssignment
+	var total int64
+
+	query := s.db.Model(&models.UserRoleAssignment{})
+	if username != "" {
+		query = query.Where("username = ?", username)
+	}
+	if role != "" {
+		query = query.Where("role = ?", role)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * pageSize
+	if err := query.Offset(offset).Limit(pageSize).Order("created_at DESC").Find(&assignments).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return assignments, total, nil
+}
+
+// AssignRole 为用户分配角色
+func (s *RBACService) AssignRole(username, role, description, operator string) (*models.UserRoleAssignment, error) {
+	if username == "" {
+		return nil, errors.New("用户名不能为空")
+	}
+	if !IsValidRole(role) {
+		return nil, fmt.Errorf("角色不存在: %s", role)
+	}
+
+	var count int64
+	if err := s.db.Model(&models.UserRoleAssignment{}).Where("username = ? AND role = ?", username, role).Count(&count).Error; err != nil {
+		return nil, err
+	}
+	if count > 0 {
+		return nil, errors.New("用户已拥有该角色")
+	}
+
+	assignment := &models.UserRoleAssignment{
+		Username:    username,
+		Role:        role,
+		Description: description,
+		CreatedBy:   operator,
+	}
+	if err := s.db.Create(assignment).Error; err != nil {
+		return nil, err
+	}
+
+	s.invalidateUserRoles(username)
+	return assignment, nil
+}
+
+// RevokeRoleAssignment 撤销用户角色分配
+func (s *RBACService) RevokeRoleAssignment(id string) error {
+	var assignment models.UserRoleAssignment
+	if err := s.db.First(&assignment, "id = ?", id).Error; err != nil {
+		return err
+	}
+
+	if err := s.db.Delete(&assignment).Error; err != nil {
+		return err
+	}
+
+	s.invalidateUserRoles(assignment.Username)
+	return nil
+}
+
+// ResolveRoles 计算用户的有效角色：JWT中的内置角色 + 数据库分配的角色，均为空时使用默认角色
+func (s *RBACService) ResolveRoles(username string, tokenRoles []string) []string {
+	roleSet := make(map[string]bool)
+	for _, role := range tokenRoles {
+		if IsValidRole(role) {
+			roleSet[role] = true
+		}
+	}
+
+	for _, role := range s.getAssignedRoles(username) {
+		roleSet[role] = true
+	}
+
+	if len(roleSet) == 0 && s.defaultRole != "" {
+		roleSet[s.defaultRole] = true
+	}
+
+	roles := make([]string, 0, len(roleSet))
+	for role := range roleSet {
+		roles = append(roles, role)
+	}
+	sort.Strings(roles)
+	return roles
+}
+
+// HasPermission 判断角色集合或JWT中直接携带的权限是否允许对资源执行操作
+func (s *RBACService) HasPermission(roles []string, tokenPermissions []string, resource, action string) bool {
+	for _, perm := range tokenPermissions {
+		if matchPermission(perm, resource, action) {
+			return true
+		}
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, role := range roles {
+		for perm := range s.rolePermissions[role] {
+			if matchPermission(perm, resource, action) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// GetEffectiveAccess 获取用户的有效角色和权限
+func (s *RBACService) GetEffectiveAccess(username string, tokenRoles []string) *EffectiveAccess {
+	roles := s.ResolveRoles(username, tokenRoles)
+
+	s.mu.RLock()
+	merged := make(map[string]bool)
+	for _, role := range roles {
+		for perm := range s.rolePermissions[role] {
+			merged[perm] = true
+		}
+	}
+	s.mu.RUnlock()
+
+	return &EffectiveAccess{
+		Username:    username,
+		Roles:       roles,
+		Permissions: permissionItems(merged),
+	}
+}
+
+// getAssignedRoles 获取数据库中为用户分配的角色（带缓存）
+func (s *RBACService) getAssignedRoles(username string) []string {
+	if username == "" {
+		return nil
+	}
+
+	s.mu.RLock()
+	entry, ok := s.userRoleCache[username]
+	s.mu.RUnlock()
+	if ok && time.Now().Before(entry.expiresAt) {
+		return entry.roles
+	}
+
+	var roles []string
+	if err := s.db.Model(&models.UserRoleAssignment{}).Where("username = ?", username).Pluck("role", &roles).Error; err != nil {
+		slog.Error("查询用户角色失败", "username", username, "error", err)
+		return nil
+	}
+
+	s.mu.Lock()
+	s.userRoleCache[username] = &userRoleCacheEntry{roles: roles, expiresAt: time.Now().Add(userRoleCacheTTL)}
+	s.mu.Unlock()
+	return roles
+}
+
+// invalidateUserRoles 清除用户角色缓存
+func (s *RBACService) invalidateUserRoles(username string) {
+	s.mu.Lock()
+	delete(s.userRoleCache, username)
+	s.mu.Unlock()
+}
+
+// buildPermissionIndex 构建角色权限索引
+func buildPermissionIndex(perms map[string][]PermissionItem) map[string]map[string]bool {
+	index := make(map[string]map[string]bool, len(perms))
+	for role, items := range perms {
+		index[role] = make(map[string]bool, len(items))
+		for _, item := range items {
+			index[role][item.Resource+":"+item.Action] = true
+		}
+	}
+	return index
+}
+
+// permissionItems 将权限索引转换为有序列表
+func permissionItems(index map[string]bool) []PermissionItem {
+	items := make([]PermissionItem, 0, len(index))
+	for perm := range index {
+		resource, action := splitPermission(perm)
+		items = append(items, PermissionItem{Resource: resource, Action: action})
+	}
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].Resource != items[j].Resource {
+			return items[i].Resource < items[j].Resource
+		}
+		return items[i].Action < items[j].Action
+	})
+	return items
+}
+
+// splitPermission 拆分"资源:操作"格式的权限
+func splitPermission(perm string) (string, string) {
+	for i := len(perm) - 1; i >= 0; i-- {
+		if perm[i] == ':' {
+			return perm[:i], perm[i+1:]
+		}
+	}
+	return perm, models.PermissionWildcard
+}
+
+// matchPermission 判断权限是否匹配资源和操作，支持通配符
+func matchPermission(perm, resource, action string) bool {
+	permResource, permAction := splitPermission(perm)
+	resourceMatched := permResource == models.PermissionWildcard || permResource == resource
+	actionMatched := permAction == models.PermissionWildcard || permAction == action
+	return resourceMatched && actionMatched
+}
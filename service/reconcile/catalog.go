@@ -0,0 +1,66 @@
+/*
+ * @module service/reconcile/catalog
+ * @description 物理表结构读取器，从PostgreSQL系统目录读取接口表是否存在以及表的字段
+ * @architecture 分层架构 - 业务服务层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow 查询information_schema.columns -> 没有字段时查询information_schema.tables确认表是否存在
+ * @rules 只读系统目录，不扫描业务表；表不存在时返回ErrTableNotFound
+ * @dependencies gorm.io/gorm
+ * @refs service/reconcile/service.go
+ */
+
+package reconcile
+
+import (
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+// ErrTableNotFound 物理表不存在
+var ErrTableNotFound = errors.New("物理表不存在")
+
+// Catalog 物理表结构读取器
+type Catalog interface {
+	// Columns 读取表的字段名，表不存在时返回ErrTableNotFound
+	Columns(ctx context.Context, schema, table string) ([]string, error)
+}
+
+// PostgresCatalog 基于PostgreSQL系统目录的读取器
+type PostgresCatalog struct {
+	db *gorm.DB
+}
+
+// NewPostgresCatalog 创建PostgreSQL表结构读取器
+func NewPostgresCatalog(db *gorm.DB) *PostgresCatalog {
+	return &PostgresCatalog{db: db}
+}
+
+// Columns 读取表的字段名
+func (c *PostgresCatalog) Columns(ctx context.Context, schema, table string) ([]string, error) {
+	var columns []string
+	err := c.db.WithContext(ctx).Raw(`
+		SELECT column_name FROM information_schema.columns
+		WHERE table_schema = ? AND table_name = ?
+		ORDER BY ordinal_position`, schema, table).
+		Scan(&columns).Error
+	if err != nil {
+		return nil, err
+	}
+	if len(columns) > 0 {
+		return columns, nil
+	}
+
+	var exists bool
+	err = c.db.WithContext(ctx).Raw(`
+		SELECT EXISTS (SELECT 1 FROM information_schema.tables WHERE table_schema = ? AND table_name = ?)`,
+		schema, table).Scan(&exists).Error
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, ErrTableNotFound
+	}
+	return columns, nil
+}
@@ -0,0 +1,413 @@
+/*
+ * @module service/reconcile/checks
+ * @description 元数据一致性核对项：接口表是否存在、血缘关系和主题数据血缘记录指向的接口是否存在、脱敏配置引用的字段是否存在
+ * @architecture 分层架构 - 业务服务层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow 查询目录 -> 读取物理表结构/查询对象是否存在 -> 生成问题项
+ * @rules 主题接口的字段优先取物理表字段，未建表或存储在ClickHouse时取字段配置，都无法确定时不检查；血缘只核对接口和主题接口类型的对象；只核对启用的血缘关系
+ * @dependencies datahub-service/service/models, gorm.io/gorm
+ * @refs service/reconcile/service.go, service/reconcile/fix.go
+ */
+
+package reconcile
+
+import (
+	"context"
+	"datahub-service/service/models"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// 问题项的对象类型
+const (
+	objectInterface         = "interface"
+	objectThematicInterface = "thematic_interface"
+	objectDataLineage       = "data_lineage"
+	objectAPIInterface      = "api_interface"
+	objectThematicSyncTask  = "thematic_sync_task"
+)
+
+// findingCollector 收集核对问题项和检查出错的对象
+type findingCollector struct {
+	findings []models.ReconciliationFinding
+	errors   models.JSONB
+}
+
+func newFindingCollector() *findingCollector {
+	return &findingCollector{errors: models.JSONB{}}
+}
+
+func (c *findingCollector) add(finding models.ReconciliationFinding) {
+	finding.Status = models.ReconcileFindingOpen
+	c.findings = append(c.findings, finding)
+}
+
+// fail 记录检查出错的对象，不中断核对
+func (c *findingCollector) fail(objectType, objectID string, err error) {
+	c.errors[objectType+":"+objectID] = err.Error()
+}
+
+// summary 按类别统计问题数
+func (c *findingCollector) summary() models.JSONB {
+	summary := models.JSONB{}
+	for _, finding := range c.findings {
+		count, _ := summary[finding.Category].(int)
+		summary[finding.Category] = count + 1
+	}
+	return summary
+}
+
+// fieldResolver 解析主题接口的实际字段，结果按接口缓存
+type fieldResolver struct {
+	db      *gorm.DB
+	catalog Catalog
+	cache   map[string]map[string]bool
+}
+
+func newFieldResolver(db *gorm.DB, catalog Catalog) *fieldResolver {
+	return &fieldResolver{db: db, catalog: catalog, cache: make(map[string]map[string]bool)}
+}
+
+// fields 返回主题接口的字段集合，接口不存在或无法确定字段时返回nil
+func (r *fieldResolver) fields(ctx context.Context, thematicInterfaceID string) (map[string]bool, error) {
+	if fields, ok := r.cache[thematicInterfaceID]; ok {
+		return fields, nil
+	}
+
+	var iface models.ThematicInterface
+	err := r.db.WithContext(ctx).Preload("ThematicLibrary").First(&iface, "id = ?", thematicInterfaceID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		r.cache[thematicInterfaceID] = nil
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var fields map[string]bool
+	if iface.IsTableCreated && !iface.IsClickHouse() {
+		columns, err := r.catalog.Columns(ctx, iface.ThematicLibrary.GetSchemaName(), iface.NameEn)
+		if err != nil && !errors.Is(err, ErrTableNotFound) {
+			return nil, err
+		}
+		if err == nil {
+			fields = toSet(columns)
+		}
+	} else {
+		fields = configuredFields(iface.TableFieldsConfig)
+	}
+	r.cache[thematicInterfaceID] = fields
+	return fields, nil
+}
+
+// checkInterfaceTables 核对标记为已建表的接口的物理表是否存在，返回缓存了主题接口物理字段的解析器
+func (s *Service) checkInterfaceTables(ctx context.Context, c *findingCollector) (*fieldResolver, error) {
+	resolver := newFieldResolver(s.db, s.catalog)
+
+	var basicInterfaces []models.DataInterface
+	err := s.db.WithContext(ctx).Select("id", "library_id", "name_zh", "name_en").
+		Where("is_table_created = ?", true).
+		Preload("BasicLibrary").
+		Find(&basicInterfaces).Error
+	if err != nil {
+		return nil, fmt.Errorf("查询基础库接口失败: %w", err)
+	}
+	for _, iface := range basicInterfaces {
+		if iface.BasicLibrary.ID == "" {
+			continue
+		}
+		_, err := s.catalog.Columns(ctx, iface.BasicLibrary.GetSchemaName(), iface.NameEn)
+		if errors.Is(err, ErrTableNotFound) {
+			c.add(missingTableFinding(objectInterface, iface.ID, iface.NameZh, iface.BasicLibrary.GetSchemaName(), iface.NameEn))
+		} else if err != nil {
+			c.fail(objectInterface, iface.ID, err)
+		}
+	}
+
+	var thematicInterfaces []models.ThematicInterface
+	err = s.db.WithContext(ctx).Select("id", "library_id", "name_zh", "name_en", "storage_engine").
+		Where("is_table_created = ?", true).
+		Preload("ThematicLibrary").
+		Find(&thematicInterfaces).Error
+	if err != nil {
+		return nil, fmt.Errorf("查询主题库接口失败: %w", err)
+	}
+	for _, iface := range thematicInterfaces {
+		if iface.ThematicLibrary.ID == "" || iface.IsClickHouse() {
+			continue
+		}
+		columns, err := s.catalog.Columns(ctx, iface.ThematicLibrary.GetSchemaName(), iface.NameEn)
+		if errors.Is(err, ErrTableNotFound) {
+			resolver.cache[iface.ID] = nil
+			c.add(missingTableFinding(objectThematicInterface, iface.ID, iface.NameZh, iface.ThematicLibrary.GetSchemaName(), iface.NameEn))
+		} else if err != nil {
+			c.fail(objectThematicInterface, iface.ID, err)
+		} else {
+			resolver.cache[iface.ID] = toSet(columns)
+		}
+	}
+	return resolver, nil
+}
+
+// missingTableFinding 构造物理表不存在的问题项
+func missingTableFinding(objectType, id, name, schema, table string) models.ReconciliationFinding {
+	return models.ReconciliationFinding{
+		Category:   models.ReconcileCategoryMissingTable,
+		ObjectType: objectType,
+		ObjectID:   id,
+		ObjectName: name,
+		Message:    fmt.Sprintf("接口%s标记为已建表，但物理表%s.%s不存在", name, schema, table),
+		FixAction:  models.ReconcileFixResetTableFlag,
+		Detail:     models.JSONB{"schema": schema, "table": table},
+	}
+}
+
+// checkLineage 核对血缘关系和主题数据血缘记录指向的接口是否存在
+func (s *Service) checkLineage(ctx context.Context, c *findingCollector) error {
+	var lineages []models.DataLineage
+	err := s.db.WithContext(ctx).
+		Select("id", "source_object_id", "source_object_type", "target_object_id", "target_object_type").
+		Where("is_active = ?", true).
+		Find(&lineages).Error
+	if err != nil {
+		return fmt.Errorf("查询血缘关系失败: %w", err)
+	}
+
+	referenced := map[string][]string{}
+	for _, lineage := range lineages {
+		referenced[lineage.SourceObjectType] = append(referenced[lineage.SourceObjectType], lineage.SourceObjectID)
+		referenced[lineage.TargetObjectType] = append(referenced[lineage.TargetObjectType], lineage.TargetObjectID)
+	}
+	existing := map[string]map[string]bool{}
+	for objectType, ids := range referenced {
+		found, err := s.existingObjects(ctx, objectType, ids)
+		if err != nil {
+			return err
+		}
+		existing[objectType] = found
+	}
+
+	for _, lineage := range lineages {
+		var missing []string
+		for _, end := range []struct{ role, objectType, id string }{
+			{"source", lineage.SourceObjectType, lineage.SourceObjectID},
+			{"target", lineage.TargetObjectType, lineage.TargetObjectID},
+		} {
+			if found, ok := existing[end.objectType]; ok && found != nil && !found[end.id] {
+				missing = append(missing, end.role)
+			}
+		}
+		if len(missing) == 0 {
+			continue
+		}
+		c.add(models.ReconciliationFinding{
+			Category:   models.ReconcileCategoryDanglingLineage,
+			ObjectType: objectDataLineage,
+			ObjectID:   lineage.ID,
+			ObjectName: fmt.Sprintf("%s:%s -> %s:%s", lineage.SourceObjectType, lineage.SourceObjectID, lineage.TargetObjectType, lineage.TargetObjectID),
+			Message:    fmt.Sprintf("血缘关系的%s对象已删除", lineageRoleNames(missing)),
+			FixAction:  models.ReconcileFixDeactivateLineage,
+			Detail:     models.JSONB{"missing": missing},
+		})
+	}
+
+	for _, orphan := range []struct{ column, objectType, table string }{
+		{"thematic_interface_id", objectThematicInterface, "thematic_interfaces"},
+		{"source_interface_id", objectInterface, "data_interfaces"},
+	} {
+		var rows []struct {
+			ObjectID string
+			Records  int64
+		}
+		err := s.db.WithContext(ctx).Table("thematic_data_lineages AS l").
+			Select(fmt.Sprintf("l.%s AS object_id, COUNT(*) AS records", orphan.column)).
+			Where(fmt.Sprintf("NOT EXISTS (SELECT 1 FROM %s o WHERE o.id = l.%s)", orphan.table, orphan.column)).
+			Group("l." + orphan.column).
+			Order("object_id").
+			Scan(&rows).Error
+		if err != nil {
+			return fmt.Errorf("查询主题数据血缘记录失败: %w", err)
+		}
+		for _, row := range rows {
+			c.add(models.ReconciliationFinding{
+				Category:   models.ReconcileCategoryOrphanLineage,
+				ObjectType: orphan.objectType,
+				ObjectID:   row.ObjectID,
+				Message:    fmt.Sprintf("%d条主题数据血缘记录指向已删除的接口%s", row.Records, row.ObjectID),
+				FixAction:  models.ReconcileFixDeleteLineage,
+				Detail:     models.JSONB{"column": orphan.column, "records": row.Records},
+			})
+		}
+	}
+	return nil
+}
+
+// existingObjects 返回指定类型对象中仍存在的ID，无法核对的对象类型返回nil
+func (s *Service) existingObjects(ctx context.Context, objectType string, ids []string) (map[string]bool, error) {
+	var model interface{}
+	switch objectType {
+	case objectInterface:
+		model = &models.DataInterface{}
+	case objectThematicInterface:
+		model = &models.ThematicInterface{}
+	default:
+		return nil, nil
+	}
+
+	var found []string
+	if err := s.db.WithContext(ctx).Model(model).Where("id IN ?", ids).Pluck("id", &found).Error; err != nil {
+		return nil, fmt.Errorf("查询血缘对象失败: %w", err)
+	}
+	return toSet(found), nil
+}
+
+// lineageRoleNames 源/目标的中文名称
+func lineageRoleNames(roles []string) string {
+	names := make([]string, len(roles))
+	for i, role := range roles {
+		names[i] = map[string]string{"source": "源", "target": "目标"}[role]
+	}
+	return strings.Join(names, "和")
+}
+
+// checkMasking 核对共享接口和主题同步任务的脱敏配置引用的字段是否存在
+func (s *Service) checkMasking(ctx context.Context, c *findingCollector, resolver *fieldResolver) error {
+	var apiInterfaces []models.ApiInterface
+	err := s.db.WithContext(ctx).Select("id", "path", "thematic_interface_id", "masking_rules").
+		Where("masking_rules IS NOT NULL").
+		Find(&apiInterfaces).Error
+	if err != nil {
+		return fmt.Errorf("查询共享接口失败: %w", err)
+	}
+	for _, apiInterface := range apiInterfaces {
+		fields, err := resolver.fields(ctx, apiInterface.ThematicInterfaceID)
+		if err != nil {
+			c.fail(objectAPIInterface, apiInterface.ID, err)
+			continue
+		}
+		if stale := staleMaskingFields(apiMaskingRules(apiInterface.MaskingRules), fields); len(stale) > 0 {
+			c.add(staleMaskingFinding(objectAPIInterface, apiInterface.ID, apiInterface.Path, "共享接口", stale))
+		}
+	}
+
+	var tasks []models.ThematicSyncTask
+	err = s.db.WithContext(ctx).Select("id", "task_name", "thematic_interface_id", "masking_rule_configs").
+		Where("masking_rule_configs IS NOT NULL").
+		Find(&tasks).Error
+	if err != nil {
+		return fmt.Errorf("查询主题同步任务失败: %w", err)
+	}
+	for _, task := range tasks {
+		fields, err := resolver.fields(ctx, task.ThematicInterfaceID)
+		if err != nil {
+			c.fail(objectThematicSyncTask, task.ID, err)
+			continue
+		}
+		if stale := staleMaskingFields(taskMaskingRules(task.MaskingRuleConfigs), fields); len(stale) > 0 {
+			c.add(staleMaskingFinding(objectThematicSyncTask, task.ID, task.TaskName, "主题同步任务", stale))
+		}
+	}
+	return nil
+}
+
+// staleMaskingFinding 构造脱敏配置引用不存在字段的问题项
+func staleMaskingFinding(objectType, id, name, label string, stale []string) models.ReconciliationFinding {
+	return models.ReconciliationFinding{
+		Category:   models.ReconcileCategoryStaleMaskingField,
+		ObjectType: objectType,
+		ObjectID:   id,
+		ObjectName: name,
+		Message:    fmt.Sprintf("%s%s的脱敏配置引用了不存在的字段: %s", label, name, strings.Join(stale, ", ")),
+		FixAction:  models.ReconcileFixRemoveMaskingField,
+		Detail:     models.JSONB{"fields": stale},
+	}
+}
+
+// apiMaskingRules 共享接口的脱敏规则，按规则键排序
+func apiMaskingRules(rules models.JSONB) []map[string]interface{} {
+	keys := make([]string, 0, len(rules))
+	for key := range rules {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	result := make([]map[string]interface{}, 0, len(keys))
+	for _, key := range keys {
+		if rule, ok := rules[key].(map[string]interface{}); ok {
+			result = append(result, rule)
+		}
+	}
+	return result
+}
+
+// taskMaskingRules 主题同步任务的脱敏规则
+func taskMaskingRules(rules models.JSONBGenericArray) []map[string]interface{} {
+	result := make([]map[string]interface{}, 0, len(rules))
+	for _, value := range rules {
+		if rule, ok := value.(map[string]interface{}); ok {
+			result = append(result, rule)
+		}
+	}
+	return result
+}
+
+// staleMaskingFields 脱敏规则引用的不存在的字段，字段无法确定时不检查
+func staleMaskingFields(rules []map[string]interface{}, fields map[string]bool) []string {
+	if fields == nil {
+		return nil
+	}
+	seen := map[string]bool{}
+	var stale []string
+	for _, rule := range rules {
+		for _, field := range maskingTargetFields(rule) {
+			if !fields[field] && !seen[field] {
+				seen[field] = true
+				stale = append(stale, field)
+			}
+		}
+	}
+	sort.Strings(stale)
+	return stale
+}
+
+// maskingTargetFields 脱敏规则的目标字段
+func maskingTargetFields(rule map[string]interface{}) []string {
+	values, _ := rule["target_fields"].([]interface{})
+	fields := make([]string, 0, len(values))
+	for _, value := range values {
+		if field, ok := value.(string); ok && field != "" {
+			fields = append(fields, field)
+		}
+	}
+	return fields
+}
+
+// configuredFields 字段配置中的英文字段名，没有配置时返回nil
+func configuredFields(config models.JSONB) map[string]bool {
+	var fields map[string]bool
+	for _, value := range config {
+		field, ok := value.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if name, ok := field["name_en"].(string); ok && name != "" {
+			if fields == nil {
+				fields = map[string]bool{}
+			}
+			fields[name] = true
+		}
+	}
+	return fields
+}
+
+func toSet(values []string) map[string]bool {
+	set := make(map[string]bool, len(values))
+	for _, value := range values {
+		set[value] = true
+	}
+	return set
+}
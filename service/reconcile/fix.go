@@ -0,0 +1,233 @@
+/*
+ * @module service/reconcile/fix
+ * @description 元数据一致性问题修复：将物理表不存在的接口标记为未建表、停用指向已删除接口的血缘关系、删除孤立的主题数据血缘记录、从脱敏配置中移除不存在的字段
+ * @architecture 分层架构 - 业务服务层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow 选择待修复问题项 -> 重新确认问题仍存在 -> 执行修复动作 -> 更新问题状态 -> 更新报告已修复数
+ * @rules 只修复open和failed状态的问题项；修复前问题已不存在时标记resolved不做修改；移除字段后没有目标字段的脱敏规则一并删除；单个问题修复失败不影响其他问题
+ * @dependencies datahub-service/service/models, gorm.io/gorm
+ * @refs service/reconcile/service.go, service/reconcile/checks.go
+ */
+
+package reconcile
+
+import (
+	"context"
+	"datahub-service/service/models"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// FixFindings 修复核对报告中的问题项
+func (s *Service) FixFindings(ctx context.Context, reportID string, req *FixRequest, operator string) (*FixResult, error) {
+	var report models.ReconciliationReport
+	if err := s.db.WithContext(ctx).First(&report, "id = ?", reportID).Error; err != nil {
+		return nil, err
+	}
+
+	query := s.db.WithContext(ctx).Where("report_id = ? AND status IN ?", reportID,
+		[]string{models.ReconcileFindingOpen, models.ReconcileFindingFailed})
+	if len(req.FindingIDs) > 0 {
+		query = query.Where("id IN ?", req.FindingIDs)
+	}
+	if req.Category != "" {
+		query = query.Where("category = ?", req.Category)
+	}
+	var findings []models.ReconciliationFinding
+	if err := query.Order("category, object_name, object_id").Find(&findings).Error; err != nil {
+		return nil, err
+	}
+
+	result := &FixResult{Findings: make([]models.ReconciliationFinding, 0, len(findings))}
+	resolver := newFieldResolver(s.db, s.catalog)
+	for i := range findings {
+		finding := &findings[i]
+		resolved, err := s.fixFinding(ctx, finding, resolver, operator)
+		now := time.Now()
+		finding.FixedAt = &now
+		finding.FixedBy = operator
+		finding.FixError = ""
+		switch {
+		case err != nil:
+			finding.Status = models.ReconcileFindingFailed
+			finding.FixError = err.Error()
+			finding.FixedAt = nil
+			result.Failed++
+			slog.WarnContext(ctx, "修复元数据核对问题失败", "finding_id", finding.ID, "category", finding.Category, "error", err)
+		case resolved:
+			finding.Status = models.ReconcileFindingResolved
+			result.Resolved++
+		default:
+			finding.Status = models.ReconcileFindingFixed
+			result.Fixed++
+		}
+		err = s.db.WithContext(ctx).Model(finding).Select("status", "fix_error", "fixed_by", "fixed_at").Updates(finding).Error
+		if err != nil {
+			return nil, fmt.Errorf("保存修复结果失败: %w", err)
+		}
+		result.Findings = append(result.Findings, *finding)
+	}
+
+	var fixed int64
+	err := s.db.WithContext(ctx).Model(&models.ReconciliationFinding{}).
+		Where("report_id = ? AND status IN ?", reportID, []string{models.ReconcileFindingFixed, models.ReconcileFindingResolved}).
+		Count(&fixed).Error
+	if err == nil {
+		err = s.db.WithContext(ctx).Model(&report).Update("fixed_findings", fixed).Error
+	}
+	if err != nil {
+		return nil, fmt.Errorf("更新核对报告失败: %w", err)
+	}
+	return result, nil
+}
+
+// fixFinding 执行问题项的修复动作，问题已不存在时返回resolved
+func (s *Service) fixFinding(ctx context.Context, finding *models.ReconciliationFinding, resolver *fieldResolver, operator string) (bool, error) {
+	switch finding.FixAction {
+	case models.ReconcileFixResetTableFlag:
+		return s.resetTableFlag(ctx, finding, operator)
+	case models.ReconcileFixDeactivateLineage:
+		result := s.db.WithContext(ctx).Model(&models.DataLineage{}).
+			Where("id = ? AND is_active = ?", finding.ObjectID, true).
+			Updates(map[string]interface{}{"is_active": false, "updated_by": operator})
+		return result.RowsAffected == 0, result.Error
+	case models.ReconcileFixDeleteLineage:
+		return s.deleteOrphanLineage(ctx, finding)
+	case models.ReconcileFixRemoveMaskingField:
+		return s.removeMaskingFields(ctx, finding, resolver)
+	default:
+		return false, fmt.Errorf("不支持的修复动作: %s", finding.FixAction)
+	}
+}
+
+// resetTableFlag 物理表仍不存在时将接口标记为未建表
+func (s *Service) resetTableFlag(ctx context.Context, finding *models.ReconciliationFinding, operator string) (bool, error) {
+	schema, _ := finding.Detail["schema"].(string)
+	table, _ := finding.Detail["table"].(string)
+	_, err := s.catalog.Columns(ctx, schema, table)
+	if err == nil {
+		return true, nil
+	}
+	if !errors.Is(err, ErrTableNotFound) {
+		return false, err
+	}
+
+	var model interface{}
+	switch finding.ObjectType {
+	case objectInterface:
+		model = &models.DataInterface{}
+	case objectThematicInterface:
+		model = &models.ThematicInterface{}
+	default:
+		return false, fmt.Errorf("不支持的接口类型: %s", finding.ObjectType)
+	}
+	result := s.db.WithContext(ctx).Model(model).
+		Where("id = ? AND is_table_created = ?", finding.ObjectID, true).
+		Updates(map[string]interface{}{"is_table_created": false, "updated_by": operator})
+	return result.RowsAffected == 0, result.Error
+}
+
+// deleteOrphanLineage 接口仍不存在时删除指向它的主题数据血缘记录
+func (s *Service) deleteOrphanLineage(ctx context.Context, finding *models.ReconciliationFinding) (bool, error) {
+	column, _ := finding.Detail["column"].(string)
+	if column != "thematic_interface_id" && column != "source_interface_id" {
+		return false, fmt.Errorf("不支持的血缘字段: %s", column)
+	}
+	found, err := s.existingObjects(ctx, finding.ObjectType, []string{finding.ObjectID})
+	if err != nil {
+		return false, err
+	}
+	if found[finding.ObjectID] {
+		return true, nil
+	}
+
+	result := s.db.WithContext(ctx).Where(column+" = ?", finding.ObjectID).Delete(&models.ThematicDataLineage{})
+	return result.RowsAffected == 0, result.Error
+}
+
+// removeMaskingFields 按当前字段重新计算后从脱敏配置中移除不存在的字段
+func (s *Service) removeMaskingFields(ctx context.Context, finding *models.ReconciliationFinding, resolver *fieldResolver) (bool, error) {
+	switch finding.ObjectType {
+	case objectAPIInterface:
+		var apiInterface models.ApiInterface
+		if err := s.db.WithContext(ctx).First(&apiInterface, "id = ?", finding.ObjectID).Error; err != nil {
+			return false, err
+		}
+		fields, err := resolver.fields(ctx, apiInterface.ThematicInterfaceID)
+		if err != nil {
+			return false, err
+		}
+		if len(staleMaskingFields(apiMaskingRules(apiInterface.MaskingRules), fields)) == 0 {
+			return true, nil
+		}
+
+		rules := models.JSONB{}
+		for key, value := range apiInterface.MaskingRules {
+			rule, ok := value.(map[string]interface{})
+			if !ok {
+				rules[key] = value
+				continue
+			}
+			if kept := pruneMaskingRule(rule, fields); kept != nil {
+				rules[key] = kept
+			}
+		}
+		return false, s.db.WithContext(ctx).Model(&models.ApiInterface{}).Where("id = ?", apiInterface.ID).
+			Updates(map[string]interface{}{
+				"masking_rules": rules,
+				"row_version":   gorm.Expr("row_version + 1"),
+			}).Error
+	case objectThematicSyncTask:
+		var task models.ThematicSyncTask
+		if err := s.db.WithContext(ctx).First(&task, "id = ?", finding.ObjectID).Error; err != nil {
+			return false, err
+		}
+		fields, err := resolver.fields(ctx, task.ThematicInterfaceID)
+		if err != nil {
+			return false, err
+		}
+		if len(staleMaskingFields(taskMaskingRules(task.MaskingRuleConfigs), fields)) == 0 {
+			return true, nil
+		}
+
+		rules := models.JSONBGenericArray{}
+		for _, value := range task.MaskingRuleConfigs {
+			rule, ok := value.(map[string]interface{})
+			if !ok {
+				rules = append(rules, value)
+				continue
+			}
+			if kept := pruneMaskingRule(rule, fields); kept != nil {
+				rules = append(rules, kept)
+			}
+		}
+		return false, s.db.WithContext(ctx).Model(&models.ThematicSyncTask{}).Where("id = ?", task.ID).
+			Update("masking_rule_configs", rules).Error
+	default:
+		return false, fmt.Errorf("不支持的脱敏配置对象: %s", finding.ObjectType)
+	}
+}
+
+// pruneMaskingRule 移除脱敏规则中不存在的目标字段，没有剩余目标字段时返回nil
+func pruneMaskingRule(rule map[string]interface{}, fields map[string]bool) map[string]interface{} {
+	var kept []interface{}
+	for _, field := range maskingTargetFields(rule) {
+		if fields[field] {
+			kept = append(kept, field)
+		}
+	}
+	if len(kept) == 0 {
+		return nil
+	}
+
+	pruned := make(map[string]interface{}, len(rule))
+	for key, value := range rule {
+		pruned[key] = value
+	}
+	pruned["target_fields"] = kept
+	return pruned
+}
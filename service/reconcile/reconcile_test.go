@@ -0,0 +1,230 @@
+/*
+ * @module service/reconcile/reconcile_test
+ * @description 元数据一致性核对测试，覆盖物理表缺失、悬空血缘关系、孤立主题数据血缘记录和脱敏配置引用不存在字段的识别，以及修复、问题已消失和重复执行
+ * @architecture 测试层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow 准备sqlite中的目录数据 -> 使用模拟表结构读取器核对 -> 修复问题 -> 验证目录修改和报告状态
+ * @rules 使用内存sqlite和模拟表结构读取器，不依赖PostgreSQL系统目录
+ * @dependencies gorm.io/driver/sqlite, stretchr/testify
+ * @refs service.go, checks.go, fix.go
+ */
+
+package reconcile
+
+import (
+	"context"
+	"datahub-service/service/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// fakeCatalog 按"schema.table"返回预设的字段
+type fakeCatalog map[string][]string
+
+func (c fakeCatalog) Columns(ctx context.Context, schema, table string) ([]string, error) {
+	columns, ok := c[schema+"."+table]
+	if !ok {
+		return nil, ErrTableNotFound
+	}
+	return columns, nil
+}
+
+func setupReconcileService(t *testing.T) (*Service, fakeCatalog) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(
+		&models.BasicLibrary{}, &models.DataInterface{}, &models.ThematicLibrary{}, &models.ThematicInterface{},
+		&models.DataLineage{}, &models.ThematicDataLineage{}, &models.ApiInterface{}, &models.ThematicSyncTask{},
+		&models.ReconciliationReport{}, &models.ReconciliationFinding{},
+	))
+
+	require.NoError(t, db.Create(&models.BasicLibrary{ID: "lib-1", NameZh: "人口基础库", NameEn: "population"}).Error)
+	for _, iface := range []models.DataInterface{
+		{ID: "if-1", LibraryID: "lib-1", NameZh: "人员", NameEn: "person", Type: "batch", IsTableCreated: true},
+		{ID: "if-2", LibraryID: "lib-1", NameZh: "户籍", NameEn: "household", Type: "batch", IsTableCreated: true},
+		{ID: "if-3", LibraryID: "lib-1", NameZh: "未建表", NameEn: "draft", Type: "batch"},
+	} {
+		require.NoError(t, db.Create(&iface).Error)
+	}
+	require.NoError(t, db.Create(&models.ThematicLibrary{ID: "tl-1", NameZh: "人口主题库", NameEn: "subject"}).Error)
+	for _, iface := range []models.ThematicInterface{
+		{ID: "ti-1", LibraryID: "tl-1", NameZh: "人口信息", NameEn: "people", Type: "table", StorageEngine: "postgres", IsTableCreated: true},
+		{ID: "ti-2", LibraryID: "tl-1", NameZh: "表已删除", NameEn: "dropped", Type: "table", StorageEngine: "postgres", IsTableCreated: true},
+		{ID: "ti-3", LibraryID: "tl-1", NameZh: "时序数据", NameEn: "metrics", Type: "table", StorageEngine: "clickhouse", IsTableCreated: true},
+		{ID: "ti-4", LibraryID: "tl-1", NameZh: "待建表", NameEn: "pending", Type: "table", StorageEngine: "postgres",
+			TableFieldsConfig: models.JSONB{
+				"field_0": map[string]interface{}{"name_en": "id"},
+				"field_1": map[string]interface{}{"name_en": "id_card"},
+			}},
+	} {
+		require.NoError(t, db.Create(&iface).Error)
+	}
+
+	for _, lineage := range []models.DataLineage{
+		{ID: "l-1", SourceObjectID: "if-1", SourceObjectType: "interface", TargetObjectID: "ti-1", TargetObjectType: "thematic_interface", RelationType: "direct", IsActive: true},
+		{ID: "l-2", SourceObjectID: "if-deleted", SourceObjectType: "interface", TargetObjectID: "ti-1", TargetObjectType: "thematic_interface", RelationType: "direct", IsActive: true},
+		{ID: "l-3", SourceObjectID: "orders", SourceObjectType: "table", TargetObjectID: "ti-deleted", TargetObjectType: "thematic_interface", RelationType: "derived", IsActive: true},
+		{ID: "l-4", SourceObjectID: "if-deleted", SourceObjectType: "interface", TargetObjectID: "ti-1", TargetObjectType: "thematic_interface", RelationType: "direct"},
+	} {
+		require.NoError(t, db.Create(&lineage).Error)
+	}
+	require.NoError(t, db.Model(&models.DataLineage{}).Where("id = ?", "l-4").Update("is_active", false).Error)
+	for i, source := range []string{"if-1", "if-deleted", "if-deleted"} {
+		require.NoError(t, db.Create(&models.ThematicDataLineage{
+			ID: string(rune('a' + i)), ThematicInterfaceID: "ti-1", ThematicRecordID: "r",
+			SourceLibraryID: "lib-1", SourceInterfaceID: source, SourceRecordID: "s",
+		}).Error)
+	}
+
+	require.NoError(t, db.Create(&models.ApiInterface{
+		ID: "api-1", ApiApplicationID: "app-1", ThematicInterfaceID: "ti-1", Path: "people",
+		MaskingRules: models.JSONB{
+			"rule_0": map[string]interface{}{"template_id": "t-1", "target_fields": []interface{}{"phone", "mobile"}},
+			"rule_1": map[string]interface{}{"template_id": "t-2", "target_fields": []interface{}{"email"}},
+		},
+	}).Error)
+	require.NoError(t, db.Create(&models.ThematicSyncTask{
+		ID: "task-1", ThematicLibraryID: "tl-1", ThematicInterfaceID: "ti-4", TaskName: "待建表同步", TriggerType: "manual",
+		MaskingRuleConfigs: models.JSONBGenericArray{
+			map[string]interface{}{"template_id": "t-1", "target_fields": []interface{}{"id_card", "passport"}},
+		},
+	}).Error)
+
+	catalog := fakeCatalog{
+		"population.person": {"id", "name"},
+		"subject.people":    {"id", "name", "phone"},
+	}
+	s := NewService(db)
+	s.catalog = catalog
+	return s, catalog
+}
+
+// findingsByCategory 按类别分组问题项的对象ID
+func findingsByCategory(findings []models.ReconciliationFinding) map[string][]string {
+	grouped := map[string][]string{}
+	for _, finding := range findings {
+		grouped[finding.Category] = append(grouped[finding.Category], finding.ObjectID)
+	}
+	return grouped
+}
+
+func TestRun(t *testing.T) {
+	s, _ := setupReconcileService(t)
+	ctx := context.Background()
+
+	report, err := s.Run(ctx, models.ReconcileTriggerManual, "admin")
+	require.NoError(t, err)
+	assert.Equal(t, 7, report.TotalFindings)
+	assert.Empty(t, report.CheckErrors)
+
+	grouped := findingsByCategory(report.Findings)
+	assert.ElementsMatch(t, []string{"if-2", "ti-2"}, grouped[models.ReconcileCategoryMissingTable], "ClickHouse接口和未建表接口不检查")
+	assert.ElementsMatch(t, []string{"l-2", "l-3"}, grouped[models.ReconcileCategoryDanglingLineage], "停用的血缘不检查")
+	assert.Equal(t, []string{"if-deleted"}, grouped[models.ReconcileCategoryOrphanLineage])
+	assert.ElementsMatch(t, []string{"api-1", "task-1"}, grouped[models.ReconcileCategoryStaleMaskingField])
+	assert.Equal(t, 2, report.Summary[models.ReconcileCategoryMissingTable])
+
+	saved, err := s.GetReport(ctx, report.ID, models.ReconcileCategoryStaleMaskingField, "")
+	require.NoError(t, err)
+	require.Len(t, saved.Findings, 2)
+	for _, finding := range saved.Findings {
+		assert.Equal(t, models.ReconcileFindingOpen, finding.Status)
+		if finding.ObjectID == "api-1" {
+			assert.Equal(t, []interface{}{"email", "mobile"}, finding.Detail["fields"])
+		} else {
+			assert.Equal(t, []interface{}{"passport"}, finding.Detail["fields"], "未建表的主题接口按字段配置检查")
+		}
+	}
+	for _, finding := range report.Findings {
+		if finding.ObjectID == "l-3" {
+			assert.Contains(t, finding.Message, "目标对象已删除")
+		}
+	}
+
+	s.running.Lock()
+	_, err = s.Run(ctx, models.ReconcileTriggerManual, "admin")
+	s.running.Unlock()
+	assert.ErrorIs(t, err, ErrReconcileRunning)
+
+	reports, total, err := s.GetReports(ctx, 1, 10)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	assert.Empty(t, reports[0].Findings)
+}
+
+func TestFixFindings(t *testing.T) {
+	s, catalog := setupReconcileService(t)
+	ctx := context.Background()
+	report, err := s.Run(ctx, models.ReconcileTriggerScheduled, "system")
+	require.NoError(t, err)
+
+	// 只修复血缘类问题
+	result, err := s.FixFindings(ctx, report.ID, &FixRequest{Category: models.ReconcileCategoryDanglingLineage}, "admin")
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.Fixed)
+	var active []string
+	require.NoError(t, s.db.Model(&models.DataLineage{}).Where("is_active = ?", true).Pluck("id", &active).Error)
+	assert.Equal(t, []string{"l-1"}, active)
+
+	// 主题接口的表已重新创建，修复时标记为问题已消失
+	catalog["subject.dropped"] = []string{"id"}
+	result, err = s.FixFindings(ctx, report.ID, &FixRequest{}, "admin")
+	require.NoError(t, err)
+	assert.Equal(t, 4, result.Fixed)
+	assert.Equal(t, 1, result.Resolved)
+	assert.Zero(t, result.Failed)
+
+	var household models.DataInterface
+	require.NoError(t, s.db.First(&household, "id = ?", "if-2").Error)
+	assert.False(t, household.IsTableCreated)
+	var dropped models.ThematicInterface
+	require.NoError(t, s.db.First(&dropped, "id = ?", "ti-2").Error)
+	assert.True(t, dropped.IsTableCreated)
+
+	var sources []string
+	require.NoError(t, s.db.Model(&models.ThematicDataLineage{}).Pluck("source_interface_id", &sources).Error)
+	assert.Equal(t, []string{"if-1"}, sources)
+
+	var apiInterface models.ApiInterface
+	require.NoError(t, s.db.First(&apiInterface, "id = ?", "api-1").Error)
+	assert.Equal(t, models.JSONB{
+		"rule_0": map[string]interface{}{"template_id": "t-1", "target_fields": []interface{}{"phone"}},
+	}, apiInterface.MaskingRules, "没有剩余目标字段的规则一并删除")
+	assert.Equal(t, int64(2), apiInterface.RowVersion)
+	var task models.ThematicSyncTask
+	require.NoError(t, s.db.First(&task, "id = ?", "task-1").Error)
+	assert.Equal(t, []interface{}{"id_card"}, task.MaskingRuleConfigs[0].(map[string]interface{})["target_fields"])
+
+	saved, err := s.GetReport(ctx, report.ID, "", models.ReconcileFindingOpen)
+	require.NoError(t, err)
+	assert.Empty(t, saved.Findings)
+	assert.Equal(t, 7, saved.FixedFindings)
+
+	// 已修复的问题不再重复修复，再次核对没有问题
+	result, err = s.FixFindings(ctx, report.ID, &FixRequest{}, "admin")
+	require.NoError(t, err)
+	assert.Empty(t, result.Findings)
+	next, err := s.Run(ctx, models.ReconcileTriggerManual, "admin")
+	require.NoError(t, err)
+	assert.Zero(t, next.TotalFindings)
+
+	_, err = s.FixFindings(ctx, "missing", &FixRequest{}, "admin")
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+}
+
+func TestPruneMaskingRule(t *testing.T) {
+	rule := map[string]interface{}{"template_id": "t-1", "target_fields": []interface{}{"phone", "mobile"}}
+	pruned := pruneMaskingRule(rule, map[string]bool{"phone": true})
+	assert.Equal(t, []interface{}{"phone"}, pruned["target_fields"])
+	assert.Equal(t, []interface{}{"phone", "mobile"}, rule["target_fields"], "不修改原规则")
+	assert.Nil(t, pruneMaskingRule(rule, map[string]bool{"email": true}))
+
+	assert.Nil(t, staleMaskingFields([]map[string]interface{}{rule}, nil), "字段无法确定时不检查")
+}
@@ -0,0 +1,241 @@
+/*
+ * @module service/reconcile/service
+ * @description 元数据一致性核对服务，每晚核对目录与实际状态：标记已建表但物理表不存在的接口、指向已删除接口的血缘、引用不存在字段的脱敏配置，生成可修复的问题报告
+ * @architecture 分层架构 - 业务服务层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow 定时触发/手动触发 -> 核对接口表 -> 核对血缘 -> 核对脱敏配置 -> 保存报告和问题项 -> 删除超过保留期的报告；修复：问题项 -> 重新确认 -> 执行修复动作 -> 更新问题状态和报告
+ * @rules 核对只读不改，修复需显式调用；核对覆盖全部租户；单个对象检查出错记入报告不中断核对；同一时间只允许一次核对，多实例部署时通过分布式锁只由一个实例执行
+ * @dependencies datahub-service/service/distributed_lock, datahub-service/service/models, gorm.io/gorm, github.com/robfig/cron/v3
+ * @refs service/reconcile/checks.go, service/reconcile/fix.go, api/controllers/reconciliation_controller.go
+ */
+
+package reconcile
+
+import (
+	"context"
+	"datahub-service/service/distributed_lock"
+	"datahub-service/service/models"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	"gorm.io/gorm"
+)
+
+const (
+	// defaultReconcileSchedule 默认每天凌晨3点半核对，错开备份校验
+	defaultReconcileSchedule = "0 30 3 * * *"
+	// defaultReportRetentionDays 核对报告默认保留天数
+	defaultReportRetentionDays = 90
+	// reconcileLockKey 多实例部署时核对任务的锁
+	reconcileLockKey = "metadata_reconcile"
+	reconcileLockTTL = time.Hour
+)
+
+// ErrReconcileRunning 核对正在执行
+var ErrReconcileRunning = errors.New("元数据一致性核对正在执行，请稍后再试")
+
+// FixRequest 修复问题项请求，未指定问题项时修复报告中所有待修复的问题
+type FixRequest struct {
+	FindingIDs []string `json:"finding_ids"`
+	Category   string   `json:"category" example:"missing_table"` // 只修复该类别的问题
+}
+
+// FixResult 修复结果
+type FixResult struct {
+	Fixed    int                            `json:"fixed"`
+	Resolved int                            `json:"resolved"` // 修复前问题已不存在
+	Failed   int                            `json:"failed"`
+	Findings []models.ReconciliationFinding `json:"findings"`
+}
+
+// Service 元数据一致性核对服务
+type Service struct {
+	db            *gorm.DB
+	catalog       Catalog
+	lock          distributed_lock.DistributedLock
+	schedule      string
+	retentionDays int
+	running       sync.Mutex
+	cron          *cron.Cron
+	ctx           context.Context
+	cancel        context.CancelFunc
+	started       bool
+}
+
+// NewService 创建元数据一致性核对服务实例，核对周期和报告保留天数可通过RECONCILE_SCHEDULE、RECONCILE_REPORT_RETENTION_DAYS配置
+func NewService(db *gorm.DB) *Service {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	schedule := os.Getenv("RECONCILE_SCHEDULE")
+	if schedule == "" {
+		schedule = defaultReconcileSchedule
+	}
+	retentionDays := defaultReportRetentionDays
+	if value, err := strconv.Atoi(os.Getenv("RECONCILE_REPORT_RETENTION_DAYS")); err == nil && value > 0 {
+		retentionDays = value
+	}
+
+	return &Service{
+		db:            db,
+		catalog:       NewPostgresCatalog(db),
+		schedule:      schedule,
+		retentionDays: retentionDays,
+		cron:          cron.New(cron.WithSeconds()),
+		ctx:           ctx,
+		cancel:        cancel,
+	}
+}
+
+// SetDistributedLock 设置分布式锁，多实例部署时避免重复核对
+func (s *Service) SetDistributedLock(lock distributed_lock.DistributedLock) {
+	s.lock = lock
+}
+
+// Run 执行一次核对并保存报告
+func (s *Service) Run(ctx context.Context, trigger, operator string) (*models.ReconciliationReport, error) {
+	if !s.running.TryLock() {
+		return nil, ErrReconcileRunning
+	}
+	defer s.running.Unlock()
+
+	if s.lock != nil {
+		locked, err := s.lock.TryLock(ctx, reconcileLockKey, reconcileLockTTL)
+		if err != nil {
+			return nil, fmt.Errorf("获取核对锁失败: %w", err)
+		}
+		if !locked {
+			return nil, ErrReconcileRunning
+		}
+		defer func() {
+			if err := s.lock.Unlock(context.Background(), reconcileLockKey); err != nil {
+				slog.Error("释放元数据核对锁失败", "error", err)
+			}
+		}()
+	}
+
+	report := &models.ReconciliationReport{Trigger: trigger, StartedAt: time.Now(), CreatedBy: operator}
+	collector := newFindingCollector()
+	resolver, err := s.checkInterfaceTables(ctx, collector)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkLineage(ctx, collector); err != nil {
+		return nil, err
+	}
+	if err := s.checkMasking(ctx, collector, resolver); err != nil {
+		return nil, err
+	}
+
+	finishedAt := time.Now()
+	report.FinishedAt = &finishedAt
+	report.Findings = collector.findings
+	report.TotalFindings = len(collector.findings)
+	report.Summary = collector.summary()
+	if len(collector.errors) > 0 {
+		report.CheckErrors = collector.errors
+	}
+	if err := s.db.WithContext(ctx).Create(report).Error; err != nil {
+		return nil, fmt.Errorf("保存核对报告失败: %w", err)
+	}
+
+	s.purgeReports(ctx, finishedAt)
+	return report, nil
+}
+
+// GetReports 分页获取核对报告，不包含问题项
+func (s *Service) GetReports(ctx context.Context, page, pageSize int) ([]models.ReconciliationReport, int64, error) {
+	db := s.db.WithContext(ctx).Model(&models.ReconciliationReport{})
+
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	page, pageSize = models.NormalizePage(page, pageSize)
+	var reports []models.ReconciliationReport
+	err := db.Order("started_at DESC").Offset((page - 1) * pageSize).Limit(pageSize).Find(&reports).Error
+	return reports, total, err
+}
+
+// GetReport 获取核对报告及问题项，可按类别和状态过滤问题项
+func (s *Service) GetReport(ctx context.Context, id, category, status string) (*models.ReconciliationReport, error) {
+	var report models.ReconciliationReport
+	err := s.db.WithContext(ctx).Preload("Findings", func(db *gorm.DB) *gorm.DB {
+		if category != "" {
+			db = db.Where("category = ?", category)
+		}
+		if status != "" {
+			db = db.Where("status = ?", status)
+		}
+		return db.Order("category, object_name, object_id")
+	}).First(&report, "id = ?", id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
+
+// Start 启动定时核对任务
+func (s *Service) Start() error {
+	if s.started {
+		return fmt.Errorf("元数据核对调度器已经启动")
+	}
+
+	_, err := s.cron.AddFunc(s.schedule, func() {
+		report, err := s.Run(s.ctx, models.ReconcileTriggerScheduled, "system")
+		if err != nil {
+			if !errors.Is(err, ErrReconcileRunning) {
+				slog.Error("定时元数据核对失败", "error", err)
+			}
+			return
+		}
+		slog.Info("定时元数据核对完成", "report_id", report.ID, "findings", report.TotalFindings)
+	})
+	if err != nil {
+		return fmt.Errorf("添加定时任务失败: %w", err)
+	}
+
+	s.cron.Start()
+	s.started = true
+	slog.Info("元数据核对调度器启动成功", "schedule", s.schedule, "retention_days", s.retentionDays)
+	return nil
+}
+
+// Stop 停止定时核对任务
+func (s *Service) Stop() {
+	if !s.started {
+		return
+	}
+	s.cancel()
+	s.cron.Stop()
+	s.started = false
+}
+
+// purgeReports 删除超过保留期的报告及其问题项
+func (s *Service) purgeReports(ctx context.Context, now time.Time) {
+	var ids []string
+	err := s.db.WithContext(ctx).Model(&models.ReconciliationReport{}).
+		Where("started_at < ?", now.AddDate(0, 0, -s.retentionDays)).
+		Pluck("id", &ids).Error
+	if err != nil || len(ids) == 0 {
+		if err != nil {
+			slog.WarnContext(ctx, "查询过期核对报告失败", "error", err)
+		}
+		return
+	}
+
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("report_id IN ?", ids).Delete(&models.ReconciliationFinding{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("id IN ?", ids).Delete(&models.ReconciliationReport{}).Error
+	})
+	if err != nil {
+		slog.WarnContext(ctx, "删除过期核对报告失败", "error", err)
+	}
+}
@@ -0,0 +1,132 @@
+/*
+ * @module service/report/render
+ * @description 报表文件生成，把保存的查询结果或指标值整理为表格并写成CSV或xlsx文件
+ * @architecture 分层架构 - 业务服务层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow 查询结果/指标值 -> 表头和单元格文本 -> CSV(UTF-8 BOM)或xlsx -> 附件
+ * @rules
+ *   - CSV带UTF-8 BOM，Excel直接打开不乱码；以=、+、-、@开头且不是数字的单元格前加单引号，防止被Excel当作公式执行
+ *   - 时间按服务所在时区格式化为"2006-01-02 15:04:05"，空值写为空字符串，对象和数组写为JSON
+ *   - 文件名为"订阅名称_日期.扩展名"，名称中的路径和特殊字符替换为下划线
+ * @dependencies datahub-service/service/utils, encoding/csv
+ * @refs service/report/subscription_service.go, service/utils/xlsx.go
+ */
+
+package report
+
+import (
+	"bytes"
+	"datahub-service/service/models"
+	"datahub-service/service/notification"
+	"datahub-service/service/utils"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// dataset 待写入文件的表格
+type dataset struct {
+	Source    string // 数据来源说明，写入消息正文
+	Columns   []string
+	Rows      [][]string
+	Truncated bool // 结果超过行数上限，只包含前Limit行
+	Limit     int
+}
+
+// fileNameReplacer 替换文件名中不允许或容易出错的字符
+var fileNameReplacer = strings.NewReplacer("/", "_", "\\", "_", ":", "_", "*", "_", "?", "_", "\"", "_", "<", "_", ">", "_", "|", "_", " ", "_")
+
+// renderFile 按订阅的文件格式生成附件
+func renderFile(sub *models.ReportSubscription, data *dataset, now time.Time) (notification.Attachment, error) {
+	name := fileNameReplacer.Replace(strings.TrimSpace(sub.Name)) + "_" + now.Format("20060102")
+	switch sub.Format {
+	case models.ReportFormatCSV:
+		content, err := renderCSV(data)
+		if err != nil {
+			return notification.Attachment{}, err
+		}
+		return notification.Attachment{Name: name + ".csv", ContentType: "text/csv; charset=UTF-8", Data: content}, nil
+	case models.ReportFormatXLSX:
+		rows := make([][]string, 0, len(data.Rows)+1)
+		rows = append(rows, data.Columns)
+		rows = append(rows, data.Rows...)
+		content, err := utils.WriteXLSX([]utils.XLSXSheet{{Name: "报表", Rows: rows}})
+		if err != nil {
+			return notification.Attachment{}, fmt.Errorf("生成xlsx文件失败: %w", err)
+		}
+		return notification.Attachment{
+			Name:        name + ".xlsx",
+			ContentType: "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+			Data:        content,
+		}, nil
+	default:
+		return notification.Attachment{}, fmt.Errorf("不支持的文件格式: %s", sub.Format)
+	}
+}
+
+// renderCSV 写成带BOM的CSV
+func renderCSV(data *dataset) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString("\ufeff")
+	writer := csv.NewWriter(&buf)
+	if err := writer.Write(data.Columns); err != nil {
+		return nil, err
+	}
+	for _, row := range data.Rows {
+		escaped := make([]string, len(row))
+		for i, cell := range row {
+			escaped[i] = escapeFormula(cell)
+		}
+		if err := writer.Write(escaped); err != nil {
+			return nil, err
+		}
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return nil, fmt.Errorf("生成CSV文件失败: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// escapeFormula 在可能被Excel当作公式的单元格前加单引号
+func escapeFormula(cell string) string {
+	if cell == "" || !strings.ContainsRune("=+-@", rune(cell[0])) {
+		return cell
+	}
+	if _, err := strconv.ParseFloat(cell, 64); err == nil {
+		return cell
+	}
+	return "'" + cell
+}
+
+// cellText 单元格文本
+func cellText(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case time.Time:
+		return v.In(time.Local).Format("2006-01-02 15:04:05")
+	case *time.Time:
+		if v == nil {
+			return ""
+		}
+		return v.In(time.Local).Format("2006-01-02 15:04:05")
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case float32:
+		return strconv.FormatFloat(float64(v), 'f', -1, 32)
+	case map[string]interface{}, []interface{}:
+		content, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprint(v)
+		}
+		return string(content)
+	default:
+		return fmt.Sprint(v)
+	}
+}
@@ -0,0 +1,307 @@
+/*
+ * @module service/report/report_test
+ * @description 报表订阅测试，覆盖下次发送时间计算、订阅校验和访问范围、到期发送与重新排期、发送失败记录，以及CSV和xlsx文件生成
+ * @architecture 测试层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow 准备桩查询、桩指标和桩渠道 -> 创建订阅 -> 定时检查/立即发送 -> 验证发送的文件和记录的发送结果
+ * @rules 使用内存sqlite，SQL工作台、指标层和通知渠道使用桩实现，不依赖外部服务
+ * @dependencies gorm.io/driver/sqlite, stretchr/testify
+ * @refs subscription_service.go, render.go
+ */
+
+package report
+
+import (
+	"context"
+	"datahub-service/service/metric_store"
+	"datahub-service/service/models"
+	"datahub-service/service/notification"
+	"datahub-service/service/utils"
+	"datahub-service/service/workbench"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// fakeQueries 保存的查询q-1属于alice，执行时返回固定结果
+type fakeQueries struct {
+	err      error
+	subjects []workbench.Subject
+}
+
+func (f *fakeQueries) GetSavedQuery(ctx context.Context, id string, subject workbench.Subject) (*models.SavedQuery, error) {
+	if id != "q-1" || (subject.Username != "alice" && !subject.IsAdmin()) {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &models.SavedQuery{ID: "q-1", Name: "车辆进出", CreatedBy: "alice"}, nil
+}
+
+func (f *fakeQueries) Execute(ctx context.Context, req *workbench.QueryRequest, subject workbench.Subject) (*workbench.QueryResult, error) {
+	f.subjects = append(f.subjects, subject)
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &workbench.QueryResult{
+		Columns: []workbench.Column{{Name: "plate_no"}, {Name: "fee"}, {Name: "entered_at"}},
+		Rows: [][]interface{}{
+			{"沪A1", 5.5, time.Date(2026, 10, 1, 8, 0, 0, 0, time.Local)},
+			{"=HYPERLINK(\"x\")", nil, "-"},
+		},
+		RowCount:  2,
+		Truncated: true,
+		Limit:     2,
+	}, nil
+}
+
+// fakeMetrics 指标m-1按停车场和天分组
+type fakeMetrics struct{}
+
+func (fakeMetrics) GetMetric(ctx context.Context, id string) (*models.MetricDefinition, error) {
+	if id != "m-1" {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &models.MetricDefinition{ID: "m-1", DisplayName: "车辆入场数", Unit: "辆次", Dimensions: models.JSONBStringArray{"parking_lot"}, TimeGrain: models.MetricGrainDay}, nil
+}
+
+func (f fakeMetrics) GetValues(ctx context.Context, id string, query metric_store.ValueQuery) (*metric_store.MetricValues, error) {
+	metric, err := f.GetMetric(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	day := time.Date(2026, 10, 1, 0, 0, 0, 0, time.Local)
+	value := 12.0
+	return &metric_store.MetricValues{Metric: metric, Values: []models.MetricValue{
+		{Period: &day, Dimensions: models.JSONB{"parking_lot": "P1"}, Value: &value},
+		{Period: &day, Dimensions: models.JSONB{"parking_lot": nil}},
+	}}, nil
+}
+
+// sentFile 桩渠道收到的文件
+type sentFile struct {
+	channelID  string
+	recipients []string
+	msg        notification.Message
+	file       notification.Attachment
+}
+
+// fakeDeliverer email-1为邮件渠道，wecom-1为企业微信渠道，dingtalk-1不支持文件
+type fakeDeliverer struct {
+	err  error
+	sent []sentFile
+}
+
+func (f *fakeDeliverer) GetFileChannel(id string) (*models.NotifyChannel, error) {
+	switch id {
+	case "email-1":
+		return &models.NotifyChannel{ID: id, Type: models.NotifyChannelEmail, IsEnabled: true}, nil
+	case "wecom-1":
+		return &models.NotifyChannel{ID: id, Type: models.NotifyChannelWeCom, IsEnabled: true}, nil
+	case "dingtalk-1":
+		return nil, notification.ErrFileNotSupported
+	default:
+		return nil, gorm.ErrRecordNotFound
+	}
+}
+
+func (f *fakeDeliverer) SendFile(ctx context.Context, channelID string, recipients []string, msg notification.Message, file notification.Attachment) error {
+	if f.err != nil {
+		return f.err
+	}
+	f.sent = append(f.sent, sentFile{channelID: channelID, recipients: recipients, msg: msg, file: file})
+	return nil
+}
+
+func setupReportService(t *testing.T) (*Service, *fakeQueries, *fakeDeliverer, *gorm.DB) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&models.ReportSubscription{}))
+
+	queries := &fakeQueries{}
+	deliverer := &fakeDeliverer{}
+	s := NewService(db, queries, fakeMetrics{}, deliverer)
+	s.now = func() time.Time { return time.Date(2026, 10, 16, 9, 30, 0, 0, time.Local) }
+	return s, queries, deliverer, db
+}
+
+func dailyRequest() *SubscriptionRequest {
+	return &SubscriptionRequest{
+		Name:       "停车场 每日进出",
+		SourceType: models.ReportSourceSavedQuery,
+		SourceID:   "q-1",
+		Format:     models.ReportFormatCSV,
+		Frequency:  models.ReportFrequencyDaily,
+		SendTime:   "08:00",
+		ChannelID:  "email-1",
+		Recipients: []string{" boss@example.com "},
+	}
+}
+
+func TestNextRunAt(t *testing.T) {
+	// 2026-10-16是星期五
+	now := time.Date(2026, 10, 16, 9, 30, 0, 0, time.Local)
+	daily := &models.ReportSubscription{Enabled: true, Frequency: models.ReportFrequencyDaily, SendTime: "08:00"}
+	assert.Equal(t, time.Date(2026, 10, 17, 8, 0, 0, 0, time.Local), *nextRunAt(daily, now))
+	daily.SendTime = "10:00"
+	assert.Equal(t, time.Date(2026, 10, 16, 10, 0, 0, 0, time.Local), *nextRunAt(daily, now))
+
+	weekly := &models.ReportSubscription{Enabled: true, Frequency: models.ReportFrequencyWeekly, SendTime: "08:00", Weekday: int(time.Monday)}
+	assert.Equal(t, time.Date(2026, 10, 19, 8, 0, 0, 0, time.Local), *nextRunAt(weekly, now))
+	weekly.Weekday = int(time.Friday)
+	assert.Equal(t, time.Date(2026, 10, 23, 8, 0, 0, 0, time.Local), *nextRunAt(weekly, now), "当天已过发送时间时顺延一周")
+
+	weekly.Enabled = false
+	assert.Nil(t, nextRunAt(weekly, now))
+}
+
+func TestSubscriptionValidationAndAccess(t *testing.T) {
+	s, _, _, _ := setupReportService(t)
+	ctx := context.Background()
+	alice := workbench.Subject{Username: "alice", Roles: []string{models.RoleDeveloper}}
+
+	cases := map[string]func(req *SubscriptionRequest){
+		"发送时间格式":    func(req *SubscriptionRequest) { req.SendTime = "8:00" },
+		"渠道不存在":     func(req *SubscriptionRequest) { req.ChannelID = "missing" },
+		"渠道不支持文件":   func(req *SubscriptionRequest) { req.ChannelID = "dingtalk-1" },
+		"企业微信指定收件人": func(req *SubscriptionRequest) { req.ChannelID = "wecom-1" },
+		"无效邮箱":      func(req *SubscriptionRequest) { req.Recipients = []string{"boss"} },
+		"查询无权访问":    func(req *SubscriptionRequest) { req.SourceID = "q-2" },
+		"指标不存在": func(req *SubscriptionRequest) {
+			req.SourceType = models.ReportSourceMetric
+			req.SourceID = "m-2"
+		},
+	}
+	for name, mutate := range cases {
+		req := dailyRequest()
+		mutate(req)
+		_, err := s.CreateSubscription(ctx, req, alice)
+		assert.ErrorIs(t, err, ErrInvalidSubscription, name)
+	}
+	_, err := s.CreateSubscription(ctx, dailyRequest(), workbench.Subject{Username: "bob"})
+	assert.ErrorIs(t, err, ErrInvalidSubscription, "其他用户的私有查询不能订阅")
+
+	sub, err := s.CreateSubscription(ctx, dailyRequest(), alice)
+	require.NoError(t, err)
+	assert.Equal(t, models.ReportFormatCSV, sub.Format)
+	assert.Equal(t, []string{"boss@example.com"}, []string(sub.Recipients))
+	assert.Equal(t, time.Date(2026, 10, 17, 8, 0, 0, 0, time.Local), *sub.NextRunAt)
+
+	_, err = s.GetSubscription(ctx, sub.ID, workbench.Subject{Username: "bob"})
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	subs, total, err := s.ListSubscriptions(ctx, "", 1, 10, workbench.Subject{Username: "bob"})
+	require.NoError(t, err)
+	assert.Zero(t, total)
+	assert.Empty(t, subs)
+	_, total, err = s.ListSubscriptions(ctx, "每日", 1, 10, workbench.Subject{Username: "root", Roles: []string{models.RoleAdmin}})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+
+	disabled := false
+	req := dailyRequest()
+	req.Enabled = &disabled
+	updated, err := s.UpdateSubscription(ctx, sub.ID, req, alice)
+	require.NoError(t, err)
+	assert.Nil(t, updated.NextRunAt)
+	require.NoError(t, s.DeleteSubscription(ctx, sub.ID, alice))
+}
+
+func TestRunDueDeliversAndReschedules(t *testing.T) {
+	s, queries, deliverer, db := setupReportService(t)
+	ctx := context.Background()
+	s.SetRoleResolver(func(username string, tokenRoles []string) []string {
+		return append(tokenRoles, models.RoleSteward)
+	})
+	sub, err := s.CreateSubscription(ctx, dailyRequest(), workbench.Subject{Username: "alice", Roles: []string{models.RoleDeveloper}})
+	require.NoError(t, err)
+	due := time.Date(2026, 10, 16, 8, 0, 0, 0, time.Local)
+	require.NoError(t, db.Model(sub).Update("next_run_at", due).Error)
+
+	summary, err := s.RunDue(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, RunSummary{Due: 1, Succeeded: 1}, *summary)
+	require.Len(t, queries.subjects, 1)
+	assert.Equal(t, "alice", queries.subjects[0].Username)
+	assert.Equal(t, []string{models.RoleDeveloper, models.RoleSteward}, queries.subjects[0].Roles, "按创建人当前的角色取数")
+
+	require.Len(t, deliverer.sent, 1)
+	sent := deliverer.sent[0]
+	assert.Equal(t, "email-1", sent.channelID)
+	assert.Equal(t, []string{"boss@example.com"}, sent.recipients)
+	assert.Equal(t, "停车场_每日进出_20261016.csv", sent.file.Name)
+	content := string(sent.file.Data)
+	assert.True(t, strings.HasPrefix(content, "\ufeffplate_no,fee,entered_at\n"))
+	assert.Contains(t, content, "沪A1,5.5,2026-10-01 08:00:00\n")
+	assert.Contains(t, content, `"'=HYPERLINK(""x"")",,'-`, "公式单元格前加单引号")
+	assert.Contains(t, sent.msg.Content, "行数: 2")
+	assert.Contains(t, sent.msg.Content, "只包含前2行")
+
+	var stored models.ReportSubscription
+	require.NoError(t, db.First(&stored, "id = ?", sub.ID).Error)
+	assert.Equal(t, models.ReportDeliverySucceeded, stored.LastStatus)
+	assert.Equal(t, 2, stored.LastRowCount)
+	assert.True(t, stored.NextRunAt.After(s.now()))
+
+	summary, err = s.RunDue(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, summary.Due, "已重新排期，不会重复发送")
+}
+
+func TestDeliveryFailure(t *testing.T) {
+	s, queries, deliverer, db := setupReportService(t)
+	ctx := context.Background()
+	sub, err := s.CreateSubscription(ctx, dailyRequest(), workbench.Subject{Username: "alice"})
+	require.NoError(t, err)
+	next := *sub.NextRunAt
+
+	queries.err = errors.New("语句超时")
+	_, err = s.SendNow(ctx, sub.ID, workbench.Subject{Username: "alice"})
+	assert.ErrorIs(t, err, ErrDeliveryFailed)
+	assert.Empty(t, deliverer.sent)
+
+	var stored models.ReportSubscription
+	require.NoError(t, db.First(&stored, "id = ?", sub.ID).Error)
+	assert.Equal(t, models.ReportDeliveryFailed, stored.LastStatus)
+	assert.Contains(t, stored.LastError, "语句超时")
+	assert.Equal(t, next.Unix(), stored.NextRunAt.Unix(), "立即发送不改变下次发送时间")
+
+	queries.err = nil
+	deliverer.err = errors.New("SMTP不可用")
+	_, err = s.SendNow(ctx, sub.ID, workbench.Subject{Username: "alice"})
+	assert.ErrorIs(t, err, ErrDeliveryFailed)
+}
+
+func TestMetricReportXLSX(t *testing.T) {
+	s, _, deliverer, _ := setupReportService(t)
+	ctx := context.Background()
+	req := dailyRequest()
+	req.SourceType = models.ReportSourceMetric
+	req.SourceID = "m-1"
+	req.Format = ""
+	req.ChannelID = "wecom-1"
+	req.Recipients = nil
+	sub, err := s.CreateSubscription(ctx, req, workbench.Subject{Username: "alice"})
+	require.NoError(t, err)
+	assert.Equal(t, models.ReportFormatXLSX, sub.Format)
+
+	_, err = s.SendNow(ctx, sub.ID, workbench.Subject{Username: "alice"})
+	require.NoError(t, err)
+	require.Len(t, deliverer.sent, 1)
+	assert.Equal(t, "停车场_每日进出_20261016.xlsx", deliverer.sent[0].file.Name)
+
+	sheets, err := utils.ReadXLSX(deliverer.sent[0].file.Data)
+	require.NoError(t, err)
+	require.Len(t, sheets, 1)
+	assert.Equal(t, [][]string{
+		{"时间周期", "parking_lot", "车辆入场数(辆次)"},
+		{"2026-10-01", "P1", "12"},
+		{"2026-10-01"}, // 维度和指标值为空，末尾的空单元格读取时省略
+	}, sheets[0].Rows)
+}
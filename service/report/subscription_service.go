@@ -0,0 +1,610 @@
+/*
+ * @module service/report/subscription_service
+ * @description 报表订阅服务，用户按日或按周订阅SQL工作台保存的查询或指标层的指标值，到期时以订阅创建人的身份取数，生成CSV或xlsx文件后通过邮件或企业微信发送
+ * @architecture 分层架构 - 业务服务层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow 创建订阅 -> 计算下次发送时间 -> 每分钟检查到期订阅 -> 按所属租户和创建人身份取数 -> 生成文件 -> 通知渠道发送 -> 记录发送结果 -> 计算下次发送时间；失败时记录应用事件
+ * @rules
+ *   - 保存的查询通过SQL工作台执行，沿用其schema白名单、行数上限、脱敏和审计，敏感字段按创建人的角色脱敏
+ *   - 创建人的角色为创建时的令牌角色与当前分配的角色合并，发送时重新解析
+ *   - 订阅只有创建人和管理员可以查看、修改和删除，其他用户按不存在处理
+ *   - 只能使用邮件和企业微信渠道；邮件可指定收件人替换渠道配置的收件人
+ *   - 同一订阅同一时间只发送一次；多实例部署时定时检查通过分布式锁只由一个实例执行；发送失败不补发，等待下一个周期
+ *   - 立即发送不改变下次发送时间
+ * @dependencies datahub-service/service/distributed_lock, datahub-service/service/eventlog, datahub-service/service/notification, datahub-service/service/workbench, datahub-service/service/metric_store, gorm.io/gorm, github.com/robfig/cron/v3
+ * @refs service/report/render.go, service/models/report_subscription.go, api/controllers/report_subscription_controller.go
+ */
+
+package report
+
+import (
+	"context"
+	"datahub-service/service/distributed_lock"
+	"datahub-service/service/eventlog"
+	"datahub-service/service/metric_store"
+	"datahub-service/service/models"
+	"datahub-service/service/notification"
+	"datahub-service/service/tenant"
+	"datahub-service/service/workbench"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	"gorm.io/gorm"
+)
+
+const (
+	// defaultCheckSchedule 默认每分钟检查一次到期的订阅
+	defaultCheckSchedule = "0 * * * * *"
+	// deliveryLockKey 多实例部署时定时检查的锁
+	deliveryLockKey = "report_subscription_delivery"
+	deliveryLockTTL = 30 * time.Minute
+)
+
+// EventReportDeliveryFailed 报表发送失败事件
+const EventReportDeliveryFailed = "report_delivery_failed"
+
+// ObjectTypeReportSubscription 报表订阅在应用事件中的对象类型
+const ObjectTypeReportSubscription = "report_subscription"
+
+var (
+	// ErrInvalidSubscription 订阅不合法
+	ErrInvalidSubscription = errors.New("报表订阅不合法")
+	// ErrDeliveryRunning 订阅正在发送
+	ErrDeliveryRunning = errors.New("报表正在发送，请稍后再试")
+	// ErrDeliveryFailed 报表发送失败
+	ErrDeliveryFailed = errors.New("报表发送失败")
+)
+
+// sendTimePattern 发送时间格式HH:MM
+var sendTimePattern = regexp.MustCompile(`^([01][0-9]|2[0-3]):[0-5][0-9]$`)
+
+// SubscriptionRequest 创建或修改报表订阅请求
+type SubscriptionRequest struct {
+	Name        string   `json:"name" validate:"required,max=255" example:"停车场每日进出明细"`
+	Description string   `json:"description" validate:"max=1000"`
+	SourceType  string   `json:"source_type" validate:"required,oneof=saved_query metric" example:"saved_query"`
+	SourceID    string   `json:"source_id" validate:"required"`
+	Format      string   `json:"format" validate:"omitempty,oneof=csv xlsx" example:"xlsx"` // 默认xlsx
+	Frequency   string   `json:"frequency" validate:"required,oneof=daily weekly" example:"daily"`
+	SendTime    string   `json:"send_time" validate:"required" example:"08:00"` // 服务所在时区的HH:MM
+	Weekday     int      `json:"weekday" validate:"min=0,max=6" example:"1"`    // 按周发送的星期，0为星期日
+	ChannelID   string   `json:"channel_id" validate:"required"`
+	Recipients  []string `json:"recipients" example:"ops@example.com"` // 只用于邮件渠道，为空时使用渠道配置的收件人
+	Enabled     *bool    `json:"enabled"`                              // 默认启用
+}
+
+// RunSummary 一次定时检查的结果
+type RunSummary struct {
+	Due       int `json:"due"`
+	Succeeded int `json:"succeeded"`
+	Failed    int `json:"failed"`
+}
+
+// QueryRunner 执行保存的查询，由SQL工作台服务实现
+type QueryRunner interface {
+	GetSavedQuery(ctx context.Context, id string, subject workbench.Subject) (*models.SavedQuery, error)
+	Execute(ctx context.Context, req *workbench.QueryRequest, subject workbench.Subject) (*workbench.QueryResult, error)
+}
+
+// MetricReader 读取物化的指标值，由指标层服务实现
+type MetricReader interface {
+	GetMetric(ctx context.Context, id string) (*models.MetricDefinition, error)
+	GetValues(ctx context.Context, id string, query metric_store.ValueQuery) (*metric_store.MetricValues, error)
+}
+
+// FileDeliverer 通过通知渠道发送文件，由通知中心服务实现
+type FileDeliverer interface {
+	GetFileChannel(id string) (*models.NotifyChannel, error)
+	SendFile(ctx context.Context, channelID string, recipients []string, msg notification.Message, file notification.Attachment) error
+}
+
+// Service 报表订阅服务
+type Service struct {
+	db           *gorm.DB
+	queries      QueryRunner
+	metrics      MetricReader
+	deliverer    FileDeliverer
+	resolveRoles func(username string, tokenRoles []string) []string
+	lock         distributed_lock.DistributedLock
+	schedule     string
+	now          func() time.Time
+	running      sync.Map // 正在发送的订阅ID
+	cron         *cron.Cron
+	ctx          context.Context
+	cancel       context.CancelFunc
+	started      bool
+}
+
+// NewService 创建报表订阅服务，检查周期可通过REPORT_SUBSCRIPTION_CHECK_SCHEDULE配置
+func NewService(db *gorm.DB, queries QueryRunner, metrics MetricReader, deliverer FileDeliverer) *Service {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	schedule := os.Getenv("REPORT_SUBSCRIPTION_CHECK_SCHEDULE")
+	if schedule == "" {
+		schedule = defaultCheckSchedule
+	}
+
+	return &Service{
+		db:        db,
+		queries:   queries,
+		metrics:   metrics,
+		deliverer: deliverer,
+		schedule:  schedule,
+		now:       time.Now,
+		cron:      cron.New(cron.WithSeconds()),
+		ctx:       ctx,
+		cancel:    cancel,
+	}
+}
+
+// SetDistributedLock 设置分布式锁，多实例部署时避免重复发送
+func (s *Service) SetDistributedLock(lock distributed_lock.DistributedLock) {
+	s.lock = lock
+}
+
+// SetRoleResolver 设置角色解析函数，发送时按创建人当前分配的角色脱敏
+func (s *Service) SetRoleResolver(resolve func(username string, tokenRoles []string) []string) {
+	s.resolveRoles = resolve
+}
+
+// CreateSubscription 创建报表订阅，订阅以subject的身份取数，subject.Roles为令牌中的角色
+func (s *Service) CreateSubscription(ctx context.Context, req *SubscriptionRequest, subject workbench.Subject) (*models.ReportSubscription, error) {
+	if err := s.validate(ctx, req, s.effective(subject)); err != nil {
+		return nil, err
+	}
+	sub := &models.ReportSubscription{
+		OwnerRoles: subject.Roles,
+		CreatedBy:  subject.Username,
+	}
+	s.applyRequest(sub, req, subject.Username)
+	if err := s.db.WithContext(ctx).Create(sub).Error; err != nil {
+		return nil, err
+	}
+	return sub, nil
+}
+
+// ListSubscriptions 分页获取订阅，管理员可以看到全部订阅，其他用户只能看到自己的订阅
+func (s *Service) ListSubscriptions(ctx context.Context, keyword string, page, pageSize int, subject workbench.Subject) ([]models.ReportSubscription, int64, error) {
+	query := s.db.WithContext(ctx).Model(&models.ReportSubscription{})
+	if !s.effective(subject).IsAdmin() {
+		query = query.Where("created_by = ?", subject.Username)
+	}
+	if keyword != "" {
+		query = query.Where("name LIKE ?", "%"+keyword+"%")
+	}
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	var subs []models.ReportSubscription
+	if err := query.Order("created_at DESC").Offset((page - 1) * pageSize).Limit(pageSize).Find(&subs).Error; err != nil {
+		return nil, 0, err
+	}
+	return subs, total, nil
+}
+
+// GetSubscription 获取订阅，其他用户的订阅按不存在处理
+func (s *Service) GetSubscription(ctx context.Context, id string, subject workbench.Subject) (*models.ReportSubscription, error) {
+	var sub models.ReportSubscription
+	if err := s.db.WithContext(ctx).First(&sub, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	if sub.CreatedBy != subject.Username && !s.effective(subject).IsAdmin() {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &sub, nil
+}
+
+// UpdateSubscription 修改订阅并重新计算下次发送时间，数据来源按订阅创建人的权限检查
+func (s *Service) UpdateSubscription(ctx context.Context, id string, req *SubscriptionRequest, subject workbench.Subject) (*models.ReportSubscription, error) {
+	sub, err := s.GetSubscription(ctx, id, subject)
+	if err != nil {
+		return nil, err
+	}
+	if sub.CreatedBy == subject.Username {
+		sub.OwnerRoles = subject.Roles
+	}
+	if err := s.validate(ctx, req, s.ownerSubject(sub)); err != nil {
+		return nil, err
+	}
+	s.applyRequest(sub, req, subject.Username)
+	if err := s.db.WithContext(ctx).Model(sub).
+		Select("name", "description", "source_type", "source_id", "format", "frequency", "send_time", "weekday",
+			"channel_id", "recipients", "owner_roles", "enabled", "next_run_at", "updated_by", "updated_at").
+		Updates(sub).Error; err != nil {
+		return nil, err
+	}
+	return sub, nil
+}
+
+// DeleteSubscription 删除订阅
+func (s *Service) DeleteSubscription(ctx context.Context, id string, subject workbench.Subject) error {
+	if _, err := s.GetSubscription(ctx, id, subject); err != nil {
+		return err
+	}
+	return s.db.WithContext(ctx).Delete(&models.ReportSubscription{}, "id = ?", id).Error
+}
+
+// SendNow 立即生成并发送报表，不改变下次发送时间；发送失败时返回ErrDeliveryFailed和记录了失败原因的订阅
+func (s *Service) SendNow(ctx context.Context, id string, subject workbench.Subject) (*models.ReportSubscription, error) {
+	sub, err := s.GetSubscription(ctx, id, subject)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.deliver(ctx, sub, false); err != nil {
+		if errors.Is(err, ErrDeliveryRunning) {
+			return nil, err
+		}
+		return sub, err
+	}
+	return sub, nil
+}
+
+// RunDue 发送所有到期的启用订阅
+func (s *Service) RunDue(ctx context.Context) (*RunSummary, error) {
+	if s.lock != nil {
+		locked, err := s.lock.TryLock(ctx, deliveryLockKey, deliveryLockTTL)
+		if err != nil {
+			return nil, fmt.Errorf("获取报表发送锁失败: %w", err)
+		}
+		if !locked {
+			return &RunSummary{}, nil
+		}
+		defer func() {
+			if err := s.lock.Unlock(context.Background(), deliveryLockKey); err != nil {
+				slog.Error("释放报表发送锁失败", "error", err)
+			}
+		}()
+	}
+
+	var subs []models.ReportSubscription
+	err := s.db.WithContext(ctx).
+		Where("enabled = ? AND next_run_at IS NOT NULL AND next_run_at <= ?", true, s.now()).
+		Order("next_run_at").
+		Find(&subs).Error
+	if err != nil {
+		return nil, fmt.Errorf("查询到期报表订阅失败: %w", err)
+	}
+
+	summary := &RunSummary{Due: len(subs)}
+	for i := range subs {
+		if ctx.Err() != nil {
+			break
+		}
+		err := s.deliver(ctx, &subs[i], true)
+		switch {
+		case err == nil:
+			summary.Succeeded++
+		case errors.Is(err, ErrDeliveryRunning):
+		default:
+			summary.Failed++
+		}
+	}
+	return summary, nil
+}
+
+// Start 启动定时检查
+func (s *Service) Start() error {
+	if s.started {
+		return fmt.Errorf("报表订阅调度器已经启动")
+	}
+
+	_, err := s.cron.AddFunc(s.schedule, func() {
+		summary, err := s.RunDue(s.ctx)
+		if err != nil {
+			slog.Error("定时发送报表失败", "error", err)
+			return
+		}
+		if summary.Due > 0 {
+			slog.Info("定时发送报表完成", "due", summary.Due, "succeeded", summary.Succeeded, "failed", summary.Failed)
+		}
+	})
+	if err != nil {
+		return fmt.Errorf("添加定时任务失败: %w", err)
+	}
+
+	s.cron.Start()
+	s.started = true
+	slog.Info("报表订阅调度器启动成功", "schedule", s.schedule)
+	return nil
+}
+
+// Stop 停止定时检查
+func (s *Service) Stop() {
+	if !s.started {
+		return
+	}
+	s.cancel()
+	s.cron.Stop()
+	s.started = false
+}
+
+// deliver 生成并发送报表，记录发送结果；scheduled为true时计算下次发送时间
+func (s *Service) deliver(ctx context.Context, sub *models.ReportSubscription, scheduled bool) error {
+	if _, loaded := s.running.LoadOrStore(sub.ID, true); loaded {
+		return ErrDeliveryRunning
+	}
+	defer s.running.Delete(sub.ID)
+
+	ctx = s.withTenant(ctx, sub.TenantID)
+	startedAt := s.now()
+	data, err := s.collect(ctx, sub)
+	if err == nil {
+		var file notification.Attachment
+		if file, err = renderFile(sub, data, startedAt); err == nil {
+			err = s.deliverer.SendFile(ctx, sub.ChannelID, sub.Recipients, reportMessage(sub, data, startedAt), file)
+		}
+	}
+
+	finishedAt := s.now()
+	sub.LastRunAt = &finishedAt
+	updates := map[string]interface{}{"last_run_at": sub.LastRunAt}
+	if scheduled {
+		sub.NextRunAt = nextRunAt(sub, finishedAt)
+		updates["next_run_at"] = sub.NextRunAt
+	}
+	if err != nil {
+		sub.LastStatus = models.ReportDeliveryFailed
+		sub.LastError = err.Error()
+	} else {
+		sub.LastStatus = models.ReportDeliverySucceeded
+		sub.LastError = ""
+		sub.LastRowCount = len(data.Rows)
+		updates["last_row_count"] = sub.LastRowCount
+	}
+	updates["last_status"] = sub.LastStatus
+	updates["last_error"] = sub.LastError
+	if updateErr := s.db.WithContext(context.WithoutCancel(ctx)).Model(&models.ReportSubscription{}).
+		Where("id = ?", sub.ID).Updates(updates).Error; updateErr != nil {
+		slog.ErrorContext(ctx, "记录报表发送结果失败", "subscription_id", sub.ID, "error", updateErr)
+	}
+
+	if err != nil {
+		eventlog.Record(ctx, eventlog.Event{
+			Type:       EventReportDeliveryFailed,
+			Level:      models.EventLevelWarn,
+			Message:    "报表发送失败: " + err.Error(),
+			ObjectType: ObjectTypeReportSubscription,
+			ObjectID:   sub.ID,
+			Attributes: map[string]interface{}{"name": sub.Name, "source_type": sub.SourceType, "source_id": sub.SourceID, "owner": sub.CreatedBy},
+		})
+		return fmt.Errorf("%w: %v", ErrDeliveryFailed, err)
+	}
+	return nil
+}
+
+// collect 以订阅创建人的身份取数
+func (s *Service) collect(ctx context.Context, sub *models.ReportSubscription) (*dataset, error) {
+	switch sub.SourceType {
+	case models.ReportSourceSavedQuery:
+		subject := s.ownerSubject(sub)
+		query, err := s.queries.GetSavedQuery(ctx, sub.SourceID, subject)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, errors.New("保存的查询不存在或订阅创建人已无权访问")
+			}
+			return nil, err
+		}
+		result, err := s.queries.Execute(ctx, &workbench.QueryRequest{SavedQueryID: query.ID}, subject)
+		if err != nil {
+			return nil, err
+		}
+		data := &dataset{Source: "保存的查询 " + query.Name, Truncated: result.Truncated, Limit: result.Limit}
+		for _, column := range result.Columns {
+			data.Columns = append(data.Columns, column.Name)
+		}
+		for _, row := range result.Rows {
+			cells := make([]string, len(row))
+			for i, value := range row {
+				cells[i] = cellText(value)
+			}
+			data.Rows = append(data.Rows, cells)
+		}
+		return data, nil
+	case models.ReportSourceMetric:
+		values, err := s.metrics.GetValues(ctx, sub.SourceID, metric_store.ValueQuery{})
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, errors.New("指标不存在")
+			}
+			return nil, err
+		}
+		return metricDataset(values), nil
+	default:
+		return nil, fmt.Errorf("不支持的数据来源: %s", sub.SourceType)
+	}
+}
+
+// metricDataset 指标值整理为表格：时间周期、各维度、指标值
+func metricDataset(values *metric_store.MetricValues) *dataset {
+	metric := values.Metric
+	data := &dataset{Source: "指标 " + metric.DisplayName}
+	layout := "2006-01-02"
+	if metric.TimeGrain == models.MetricGrainHour {
+		layout = "2006-01-02 15:04"
+	}
+	if metric.TimeGrain != "" {
+		data.Columns = append(data.Columns, "时间周期")
+	}
+	data.Columns = append(data.Columns, metric.Dimensions...)
+	valueColumn := metric.DisplayName
+	if metric.Unit != "" {
+		valueColumn += "(" + metric.Unit + ")"
+	}
+	data.Columns = append(data.Columns, valueColumn)
+
+	for _, value := range values.Values {
+		var cells []string
+		if metric.TimeGrain != "" {
+			period := ""
+			if value.Period != nil {
+				period = value.Period.In(time.Local).Format(layout)
+			}
+			cells = append(cells, period)
+		}
+		for _, dimension := range metric.Dimensions {
+			cells = append(cells, cellText(value.Dimensions[dimension]))
+		}
+		if value.Value != nil {
+			cells = append(cells, cellText(*value.Value))
+		} else {
+			cells = append(cells, "")
+		}
+		data.Rows = append(data.Rows, cells)
+	}
+	return data
+}
+
+// reportMessage 随文件发送的消息
+func reportMessage(sub *models.ReportSubscription, data *dataset, now time.Time) notification.Message {
+	lines := []string{
+		"数据来源: " + data.Source,
+		fmt.Sprintf("行数: %d", len(data.Rows)),
+	}
+	if data.Truncated {
+		lines = append(lines, fmt.Sprintf("结果超过%d行，文件只包含前%d行", data.Limit, data.Limit))
+	}
+	lines = append(lines, "生成时间: "+now.Format("2006-01-02 15:04:05"))
+	return notification.Message{
+		EventType:  "report_delivery",
+		Level:      models.EventLevelInfo,
+		Title:      "报表订阅: " + sub.Name,
+		Content:    strings.Join(lines, "\n"),
+		ObjectType: ObjectTypeReportSubscription,
+		ObjectID:   sub.ID,
+		ObjectName: sub.Name,
+		CreatedAt:  now,
+	}
+}
+
+// validate 校验订阅请求，数据来源须对owner可见，渠道须能发送文件
+func (s *Service) validate(ctx context.Context, req *SubscriptionRequest, owner workbench.Subject) error {
+	req.Name = strings.TrimSpace(req.Name)
+	req.SendTime = strings.TrimSpace(req.SendTime)
+	if req.Name == "" {
+		return fmt.Errorf("%w: 名称不能为空", ErrInvalidSubscription)
+	}
+	if req.Format == "" {
+		req.Format = models.ReportFormatXLSX
+	}
+	if !sendTimePattern.MatchString(req.SendTime) {
+		return fmt.Errorf("%w: 发送时间须为HH:MM格式", ErrInvalidSubscription)
+	}
+	if req.Frequency == models.ReportFrequencyWeekly && (req.Weekday < 0 || req.Weekday > 6) {
+		return fmt.Errorf("%w: 星期须为0到6，0为星期日", ErrInvalidSubscription)
+	}
+
+	channel, err := s.deliverer.GetFileChannel(req.ChannelID)
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return fmt.Errorf("%w: 通知渠道不存在", ErrInvalidSubscription)
+	case err != nil:
+		return fmt.Errorf("%w: %v", ErrInvalidSubscription, err)
+	}
+	recipients := make([]string, 0, len(req.Recipients))
+	for _, recipient := range req.Recipients {
+		if recipient = strings.TrimSpace(recipient); recipient == "" {
+			continue
+		}
+		if !strings.Contains(recipient, "@") {
+			return fmt.Errorf("%w: 无效的邮箱地址: %s", ErrInvalidSubscription, recipient)
+		}
+		recipients = append(recipients, recipient)
+	}
+	if len(recipients) > 0 && channel.Type != models.NotifyChannelEmail {
+		return fmt.Errorf("%w: 只有邮件渠道可以指定收件人", ErrInvalidSubscription)
+	}
+	req.Recipients = recipients
+
+	switch req.SourceType {
+	case models.ReportSourceSavedQuery:
+		if _, err := s.queries.GetSavedQuery(ctx, req.SourceID, owner); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return fmt.Errorf("%w: 保存的查询不存在或无权访问", ErrInvalidSubscription)
+			}
+			return err
+		}
+	case models.ReportSourceMetric:
+		if _, err := s.metrics.GetMetric(ctx, req.SourceID); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return fmt.Errorf("%w: 指标不存在", ErrInvalidSubscription)
+			}
+			return err
+		}
+	default:
+		return fmt.Errorf("%w: 数据来源类型%s不支持，可选值: saved_query, metric", ErrInvalidSubscription, req.SourceType)
+	}
+	return nil
+}
+
+// applyRequest 把已校验的请求写入订阅，并重新计算下次发送时间
+func (s *Service) applyRequest(sub *models.ReportSubscription, req *SubscriptionRequest, operator string) {
+	sub.Name = req.Name
+	sub.Description = req.Description
+	sub.SourceType = req.SourceType
+	sub.SourceID = req.SourceID
+	sub.Format = req.Format
+	sub.Frequency = req.Frequency
+	sub.SendTime = req.SendTime
+	sub.Weekday = req.Weekday
+	sub.ChannelID = req.ChannelID
+	sub.Recipients = req.Recipients
+	sub.Enabled = req.Enabled == nil || *req.Enabled
+	sub.UpdatedBy = operator
+	sub.NextRunAt = nextRunAt(sub, s.now())
+}
+
+// effective 令牌角色与分配的角色合并后的身份
+func (s *Service) effective(subject workbench.Subject) workbench.Subject {
+	if s.resolveRoles != nil {
+		subject.Roles = s.resolveRoles(subject.Username, subject.Roles)
+	}
+	return subject
+}
+
+// ownerSubject 订阅创建人的身份
+func (s *Service) ownerSubject(sub *models.ReportSubscription) workbench.Subject {
+	return s.effective(workbench.Subject{Username: sub.CreatedBy, Roles: sub.OwnerRoles})
+}
+
+// withTenant 按订阅所属租户取数，定时发送时context中没有租户
+func (s *Service) withTenant(ctx context.Context, tenantID string) context.Context {
+	if tenantID == "" {
+		return ctx
+	}
+	info := tenant.Info{ID: tenantID}
+	var t models.Tenant
+	if err := s.db.WithContext(ctx).Select("id", "code").First(&t, "id = ?", tenantID).Error; err == nil {
+		info.Code = t.Code
+	}
+	return tenant.WithTenant(ctx, info)
+}
+
+// nextRunAt 计算after之后的下一次发送时间，停用时为空
+func nextRunAt(sub *models.ReportSubscription, after time.Time) *time.Time {
+	if !sub.Enabled {
+		return nil
+	}
+	clock, err := time.Parse("15:04", sub.SendTime)
+	if err != nil {
+		return nil
+	}
+	after = after.In(time.Local)
+	next := time.Date(after.Year(), after.Month(), after.Day(), clock.Hour(), clock.Minute(), 0, 0, time.Local)
+	step := 1
+	if sub.Frequency == models.ReportFrequencyWeekly {
+		step = 7
+		next = next.AddDate(0, 0, (sub.Weekday-int(next.Weekday())+7)%7)
+	}
+	for !next.After(after) {
+		next = next.AddDate(0, 0, step)
+	}
+	return &next
+}
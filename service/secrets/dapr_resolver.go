@@ -0,0 +1,122 @@
+/*
+ * @module service/secrets/dapr_resolver
+ * @description 通过Dapr sidecar的密钥API读取密钥，按TTL缓存读取结果，减少同步和通知发送时对sidecar的调用
+ * @architecture 分层架构 - 基础服务层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow 密钥引用 -> 缓存命中直接返回 -> 未命中时GET /v1.0/secrets/{store}/{key} -> 缓存 -> 返回
+ * @rules sidecar地址由DAPR_HTTP_PORT确定（默认3500）；缓存时长由SECRET_CACHE_TTL_SECONDS配置（默认60秒，0表示不缓存），轮换密钥后最长在一个缓存周期内生效；读取失败不缓存
+ * @dependencies net/http
+ * @refs service/secrets/secrets.go, service/execution/dispatcher.go
+ */
+
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// defaultCacheTTL 默认密钥缓存时长
+const defaultCacheTTL = 60 * time.Second
+
+// cachedSecret 缓存的密钥
+type cachedSecret struct {
+	values    map[string]string
+	expiresAt time.Time
+}
+
+// DaprResolver 通过Dapr密钥API读取密钥
+type DaprResolver struct {
+	baseURL    string
+	httpClient *http.Client
+	ttl        time.Duration
+
+	mu    sync.Mutex
+	cache map[string]cachedSecret
+}
+
+// NewDaprResolver 创建Dapr密钥解析器，读取DAPR_HTTP_PORT、SECRET_CACHE_TTL_SECONDS环境变量
+func NewDaprResolver() *DaprResolver {
+	daprPort := os.Getenv("DAPR_HTTP_PORT")
+	if daprPort == "" {
+		daprPort = "3500"
+	}
+	ttl := defaultCacheTTL
+	if value, err := strconv.Atoi(os.Getenv("SECRET_CACHE_TTL_SECONDS")); err == nil && value >= 0 {
+		ttl = time.Duration(value) * time.Second
+	}
+	return &DaprResolver{
+		baseURL:    fmt.Sprintf("http://localhost:%s", daprPort),
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+		ttl:        ttl,
+		cache:      make(map[string]cachedSecret),
+	}
+}
+
+// GetSecret 读取密钥，返回字段名到值的映射
+func (r *DaprResolver) GetSecret(ctx context.Context, store, key string) (map[string]string, error) {
+	cacheKey := store + "/" + key
+	if r.ttl > 0 {
+		r.mu.Lock()
+		cached, ok := r.cache[cacheKey]
+		r.mu.Unlock()
+		if ok && time.Now().Before(cached.expiresAt) {
+			return cached.values, nil
+		}
+	}
+
+	values, err := r.fetch(ctx, store, key)
+	if err != nil {
+		return nil, err
+	}
+	if r.ttl > 0 {
+		r.mu.Lock()
+		r.cache[cacheKey] = cachedSecret{values: values, expiresAt: time.Now().Add(r.ttl)}
+		r.mu.Unlock()
+	}
+	return values, nil
+}
+
+// fetch 调用Dapr密钥API
+func (r *DaprResolver) fetch(ctx context.Context, store, key string) (map[string]string, error) {
+	segments := strings.Split(key, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	target := fmt.Sprintf("%s/v1.0/secrets/%s/%s", r.baseURL, url.PathEscape(store), strings.Join(segments, "/"))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return nil, fmt.Errorf("创建密钥请求失败: %w", err)
+	}
+	resp, err := r.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("请求密钥存储失败: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	switch {
+	case resp.StatusCode == http.StatusNoContent || resp.StatusCode == http.StatusNotFound:
+		return nil, ErrSecretNotFound
+	case resp.StatusCode != http.StatusOK:
+		return nil, fmt.Errorf("密钥存储返回状态码%d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	var values map[string]string
+	if err := json.Unmarshal(body, &values); err != nil {
+		return nil, fmt.Errorf("解析密钥失败: %w", err)
+	}
+	if len(values) == 0 {
+		return nil, ErrSecretNotFound
+	}
+	return values, nil
+}
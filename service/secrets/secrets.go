@@ -0,0 +1,261 @@
+/*
+ * @module service/secrets/secrets
+ * @description 配置中的密钥引用，凭据字段可写为secretstore://<存储名称>/<密钥名称>，使用时经Dapr密钥API解析，数据库中只保存引用
+ * @architecture 分层架构 - 基础服务层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow 保存配置 -> 校验引用格式和明文凭据 -> 入库；使用配置 -> 复制配置并解析引用 -> 连接数据源/发送通知/下载备份
+ * @rules
+ *   - 引用格式为secretstore://<存储名称>/<密钥名称>[#字段]，多值密钥用#字段选择其中一项，单值密钥可省略
+ *   - 解析只作用于复制出的配置，解析结果不写回数据库，也不出现在日志和接口响应中
+ *   - 名称包含password、secret、token等关键字的配置项视为凭据字段；SECRETS_REQUIRE_REFERENCE=true时凭据字段只接受引用
+ *   - 未设置解析器时遇到引用返回错误，不回退为原文
+ * @dependencies datahub-service/service/models
+ * @refs service/secrets/dapr_resolver.go, service/datasource/base.go, service/notification/notification_service.go, service/backup/verifier.go
+ */
+
+package secrets
+
+import (
+	"context"
+	"datahub-service/service/models"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// ReferencePrefix 密钥引用前缀
+const ReferencePrefix = "secretstore://"
+
+var (
+	// ErrInvalidReference 密钥引用格式错误
+	ErrInvalidReference = errors.New("密钥引用格式错误，应为secretstore://<存储名称>/<密钥名称>")
+	// ErrNoResolver 未配置密钥解析器
+	ErrNoResolver = errors.New("未配置密钥存储，无法解析密钥引用")
+	// ErrSecretNotFound 密钥不存在
+	ErrSecretNotFound = errors.New("密钥不存在")
+)
+
+// credentialKeys 配置项名称包含这些关键字时视为凭据字段
+var credentialKeys = []string{"password", "passwd", "secret", "token", "api_key", "apikey", "access_key", "private_key", "credential", "authorization"}
+
+// nonCredentialSuffixes 以这些后缀结尾的配置项描述凭据的用法而不是凭据本身，如token_endpoint、api_key_header
+var nonCredentialSuffixes = []string{"_type", "_url", "_uri", "_endpoint", "_header", "_expiry", "_expires_in", "_at", "_path", "_field", "_name"}
+
+// Reference 解析后的密钥引用
+type Reference struct {
+	Store string // Dapr密钥存储组件名称
+	Key   string // 密钥名称
+	Field string // 多值密钥中的字段，为空时取与密钥同名的字段或唯一字段
+}
+
+// String 引用的文本形式
+func (r Reference) String() string {
+	value := ReferencePrefix + r.Store + "/" + r.Key
+	if r.Field != "" {
+		value += "#" + r.Field
+	}
+	return value
+}
+
+// Resolver 密钥解析器
+type Resolver interface {
+	// GetSecret 读取密钥，返回字段名到值的映射
+	GetSecret(ctx context.Context, store, key string) (map[string]string, error)
+}
+
+var (
+	resolverMu      sync.RWMutex
+	defaultResolver Resolver
+)
+
+// SetDefault 设置全局密钥解析器
+func SetDefault(r Resolver) {
+	resolverMu.Lock()
+	defer resolverMu.Unlock()
+	defaultResolver = r
+}
+
+// currentResolver 当前的全局密钥解析器
+func currentResolver() Resolver {
+	resolverMu.RLock()
+	defer resolverMu.RUnlock()
+	return defaultResolver
+}
+
+// IsReference 判断值是否为密钥引用
+func IsReference(value string) bool {
+	return strings.HasPrefix(strings.TrimSpace(value), ReferencePrefix)
+}
+
+// ParseReference 解析密钥引用
+func ParseReference(value string) (Reference, error) {
+	value = strings.TrimSpace(value)
+	if !strings.HasPrefix(value, ReferencePrefix) {
+		return Reference{}, ErrInvalidReference
+	}
+	rest := strings.TrimPrefix(value, ReferencePrefix)
+	var field string
+	if i := strings.Index(rest, "#"); i >= 0 {
+		rest, field = rest[:i], rest[i+1:]
+		if field == "" {
+			return Reference{}, ErrInvalidReference
+		}
+	}
+	store, key, ok := strings.Cut(rest, "/")
+	if !ok || store == "" || key == "" || strings.HasSuffix(key, "/") {
+		return Reference{}, ErrInvalidReference
+	}
+	return Reference{Store: store, Key: key, Field: field}, nil
+}
+
+// Resolve 解析单个值，不是引用时原样返回
+func Resolve(ctx context.Context, value string) (string, error) {
+	if !IsReference(value) {
+		return value, nil
+	}
+	ref, err := ParseReference(value)
+	if err != nil {
+		return "", err
+	}
+	resolver := currentResolver()
+	if resolver == nil {
+		return "", ErrNoResolver
+	}
+	values, err := resolver.GetSecret(ctx, ref.Store, ref.Key)
+	if err != nil {
+		return "", fmt.Errorf("读取密钥%s失败: %w", ref, err)
+	}
+
+	field := ref.Field
+	if field == "" {
+		if _, ok := values[ref.Key]; ok || len(values) != 1 {
+			field = ref.Key
+		} else {
+			for name := range values {
+				field = name
+			}
+		}
+	}
+	secret, ok := values[field]
+	if !ok {
+		return "", fmt.Errorf("%w: %s", ErrSecretNotFound, ref)
+	}
+	return secret, nil
+}
+
+// ResolveConfig 复制配置并解析其中的密钥引用，包括嵌套的对象和数组；配置为nil时返回nil
+func ResolveConfig(ctx context.Context, config map[string]interface{}) (map[string]interface{}, error) {
+	if config == nil {
+		return nil, nil
+	}
+	resolved, err := resolveValue(ctx, config)
+	if err != nil {
+		return nil, err
+	}
+	return resolved.(map[string]interface{}), nil
+}
+
+// resolveValue 递归复制并解析配置值
+func resolveValue(ctx context.Context, value interface{}) (interface{}, error) {
+	switch v := value.(type) {
+	case string:
+		return Resolve(ctx, v)
+	case models.JSONB:
+		return resolveValue(ctx, map[string]interface{}(v))
+	case map[string]interface{}:
+		resolved := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			value, err := resolveValue(ctx, item)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", key, err)
+			}
+			resolved[key] = value
+		}
+		return resolved, nil
+	case []interface{}:
+		resolved := make([]interface{}, len(v))
+		for i, item := range v {
+			value, err := resolveValue(ctx, item)
+			if err != nil {
+				return nil, err
+			}
+			resolved[i] = value
+		}
+		return resolved, nil
+	default:
+		return value, nil
+	}
+}
+
+// RequireReferences 是否要求凭据字段只使用密钥引用
+func RequireReferences() bool {
+	value := strings.ToLower(os.Getenv("SECRETS_REQUIRE_REFERENCE"))
+	return value == "true" || value == "1"
+}
+
+// IsCredentialKey 判断配置项是否为凭据字段
+func IsCredentialKey(key string) bool {
+	lower := strings.ToLower(key)
+	for _, suffix := range nonCredentialSuffixes {
+		if strings.HasSuffix(lower, suffix) {
+			return false
+		}
+	}
+	for _, credential := range credentialKeys {
+		if strings.Contains(lower, credential) {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckConfig 校验配置中的密钥引用格式，要求引用时同时检查明文凭据，返回问题列表，按配置项路径排序
+func CheckConfig(config map[string]interface{}) []string {
+	var problems []string
+	requireReferences := RequireReferences()
+	walkStrings(config, "", "", func(path, key, value string) {
+		switch {
+		case IsReference(value):
+			if _, err := ParseReference(value); err != nil {
+				problems = append(problems, fmt.Sprintf("%s: %v", path, err))
+			}
+		case requireReferences && value != "" && IsCredentialKey(key):
+			problems = append(problems, fmt.Sprintf("%s: 凭据字段必须使用密钥引用(%s<存储名称>/<密钥名称>)", path, ReferencePrefix))
+		}
+	})
+	sort.Strings(problems)
+	return problems
+}
+
+// ValidateConfig 校验配置中的密钥引用和明文凭据，有问题时返回错误
+func ValidateConfig(config map[string]interface{}) error {
+	if problems := CheckConfig(config); len(problems) > 0 {
+		return errors.New(strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// walkStrings 遍历配置中的字符串值，数组元素沿用所在配置项的名称
+func walkStrings(value interface{}, path, key string, visit func(path, key, value string)) {
+	switch v := value.(type) {
+	case string:
+		visit(path, key, v)
+	case models.JSONB:
+		walkStrings(map[string]interface{}(v), path, key, visit)
+	case map[string]interface{}:
+		for name, item := range v {
+			child := name
+			if path != "" {
+				child = path + "." + name
+			}
+			walkStrings(item, child, name, visit)
+		}
+	case []interface{}:
+		for i, item := range v {
+			walkStrings(item, fmt.Sprintf("%s[%d]", path, i), key, visit)
+		}
+	}
+}
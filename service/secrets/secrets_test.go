@@ -0,0 +1,140 @@
+/*
+ * @module service/secrets/secrets_test
+ * @description 密钥引用测试，覆盖引用解析、配置复制解析、凭据字段检查以及Dapr密钥API的调用和缓存
+ * @architecture 测试层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow 设置解析器 -> 解析引用/配置 -> 验证结果和原配置不变
+ * @rules 使用httptest模拟Dapr sidecar，不依赖外部服务
+ * @dependencies github.com/stretchr/testify
+ * @refs secrets.go, dapr_resolver.go
+ */
+
+package secrets
+
+import (
+	"context"
+	"datahub-service/service/models"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// staticResolver 固定内容的密钥解析器
+type staticResolver map[string]map[string]string
+
+func (r staticResolver) GetSecret(ctx context.Context, store, key string) (map[string]string, error) {
+	values, ok := r[store+"/"+key]
+	if !ok {
+		return nil, ErrSecretNotFound
+	}
+	return values, nil
+}
+
+func useResolver(t *testing.T, r Resolver) {
+	SetDefault(r)
+	t.Cleanup(func() { SetDefault(nil) })
+}
+
+func TestParseReference(t *testing.T) {
+	ref, err := ParseReference("secretstore://vault/db/orders#password")
+	require.NoError(t, err)
+	assert.Equal(t, Reference{Store: "vault", Key: "db/orders", Field: "password"}, ref)
+	assert.Equal(t, "secretstore://vault/db/orders#password", ref.String())
+
+	for _, value := range []string{"vault/db", "secretstore://vault", "secretstore:///db", "secretstore://vault/", "secretstore://vault/db#"} {
+		_, err := ParseReference(value)
+		assert.ErrorIs(t, err, ErrInvalidReference, value)
+	}
+}
+
+func TestResolveConfig(t *testing.T) {
+	ctx := context.Background()
+	config := models.JSONB{
+		"host":     "db.local",
+		"port":     float64(5432),
+		"password": "secretstore://vault/orders-db",
+		"auth": map[string]interface{}{
+			"client_secret": "secretstore://k8s/oauth#client_secret",
+		},
+		"headers": []interface{}{"secretstore://vault/orders-db"},
+	}
+
+	_, err := ResolveConfig(ctx, config)
+	assert.ErrorIs(t, err, ErrNoResolver, "未设置解析器时不回退为原文")
+
+	useResolver(t, staticResolver{
+		"vault/orders-db": {"orders-db": "p@ss"},
+		"k8s/oauth":       {"client_id": "hub", "client_secret": "s3cret"},
+	})
+	resolved, err := ResolveConfig(ctx, config)
+	require.NoError(t, err)
+	assert.Equal(t, "p@ss", resolved["password"])
+	assert.Equal(t, float64(5432), resolved["port"])
+	assert.Equal(t, "s3cret", resolved["auth"].(map[string]interface{})["client_secret"])
+	assert.Equal(t, []interface{}{"p@ss"}, resolved["headers"])
+	assert.Equal(t, "secretstore://vault/orders-db", config["password"], "原配置不变")
+
+	_, err = Resolve(ctx, "secretstore://k8s/oauth")
+	assert.ErrorIs(t, err, ErrSecretNotFound, "多值密钥必须指定字段")
+	_, err = Resolve(ctx, "secretstore://vault/missing")
+	assert.ErrorIs(t, err, ErrSecretNotFound)
+}
+
+func TestCheckConfig(t *testing.T) {
+	config := map[string]interface{}{
+		"username":       "hub",
+		"password":       "plain",
+		"token_endpoint": "https://auth.local/token",
+		"api_key_header": "X-Api-Key",
+		"auth":           map[string]interface{}{"access_token": "secretstore://vault"},
+	}
+	assert.Equal(t, []string{"auth.access_token: " + ErrInvalidReference.Error()}, CheckConfig(config))
+
+	t.Setenv("SECRETS_REQUIRE_REFERENCE", "true")
+	problems := CheckConfig(config)
+	require.Len(t, problems, 2)
+	assert.Contains(t, problems[1], "password")
+
+	config["password"] = "secretstore://vault/orders-db"
+	config["auth"] = map[string]interface{}{"access_token": "secretstore://vault/api"}
+	assert.NoError(t, ValidateConfig(config))
+}
+
+func TestDaprResolver(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		if r.URL.Path != "/v1.0/secrets/vault/orders-db" {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		w.Write([]byte(`{"orders-db": "p@ss"}`))
+	}))
+	defer server.Close()
+
+	resolver := NewDaprResolver()
+	resolver.baseURL = server.URL
+	useResolver(t, resolver)
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		value, err := Resolve(ctx, "secretstore://vault/orders-db")
+		require.NoError(t, err)
+		assert.Equal(t, "p@ss", value)
+	}
+	assert.Equal(t, int32(1), calls.Load(), "缓存期内只请求一次")
+
+	_, err := Resolve(ctx, "secretstore://vault/missing")
+	assert.ErrorIs(t, err, ErrSecretNotFound)
+
+	resolver.cache["vault/orders-db"] = cachedSecret{values: map[string]string{"orders-db": "old"}, expiresAt: time.Now().Add(-time.Second)}
+	value, err := Resolve(ctx, "secretstore://vault/orders-db")
+	require.NoError(t, err)
+	assert.Equal(t, "p@ss", value)
+	assert.Equal(t, int32(3), calls.Load(), "缓存过期后重新读取")
+}
@@ -0,0 +1,366 @@
+/*
+ * @module service/sharing/access_grant
+ * @description 数据使用授权，申请时声明使用目的和期限，审批通过后生成绑定目的的限期授权，到期由定时任务自动回收，授权记录保留供合规审计查询
+ * @architecture 分层架构 - 业务服务层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow 申请(pending) -> 审批通过(approved)生成授权(active) -> 到期回收(expired)/人工撤销(revoked)，申请同步记为expired；审批驳回(rejected)不生成授权
+ * @rules
+ *   - 申请必须填写使用目的，使用期限为1到MaxAccessDurationDays天，有效期从审批通过时起算
+ *   - 只能审批待审批的申请，并发审批时只有一次生效
+ *   - 授权到期或撤销后不删除，按有效期区间可查询任一时段内生效过的授权
+ * @dependencies datahub-service/service/models, datahub-service/service/eventlog, datahub-service/service/identity, gorm.io/gorm, github.com/robfig/cron/v3
+ * @refs service/sharing/sharing_service.go, api/controllers/sharing_controller.go
+ */
+
+package sharing
+
+import (
+	"context"
+	"datahub-service/service/distributed_lock"
+	"datahub-service/service/eventlog"
+	"datahub-service/service/identity"
+	"datahub-service/service/models"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	"gorm.io/gorm"
+)
+
+const (
+	// MaxAccessDurationDays 数据使用申请的最长使用期限
+	MaxAccessDurationDays = 365
+	// defaultGrantExpirySchedule 默认每5分钟回收一次到期授权
+	defaultGrantExpirySchedule = "0 */5 * * * *"
+	// grantExpiryLockKey 多实例部署时回收任务的锁
+	grantExpiryLockKey = "data_access_grant_expiry"
+	grantExpiryLockTTL = 5 * time.Minute
+	// grantExpiredReason 到期回收的原因
+	grantExpiredReason = "使用期限已到，自动回收"
+)
+
+var (
+	// ErrInvalidAccessRequest 数据使用申请参数无效
+	ErrInvalidAccessRequest = errors.New("数据使用申请参数无效")
+	// ErrAccessRequestNotPending 申请已审批，不能重复审批
+	ErrAccessRequestNotPending = errors.New("数据使用申请不是待审批状态")
+	// ErrAccessGrantNotActive 授权已到期或已撤销
+	ErrAccessGrantNotActive = errors.New("数据使用授权已到期或已撤销")
+)
+
+// DataAccessGrantQuery 数据使用授权查询条件
+type DataAccessGrantQuery struct {
+	RequesterID  string
+	ResourceType string
+	ResourceID   string
+	Status       string
+	Purpose      string     // 按使用目的模糊匹配
+	From         *time.Time // 与From、To区间有重叠的授权，即该时段内生效过的授权
+	To           *time.Time
+	Page         int
+	Size         int
+}
+
+// validateAccessPurpose 校验使用目的和期限
+func validateAccessPurpose(request *models.DataAccessRequest) error {
+	request.Purpose = strings.TrimSpace(request.Purpose)
+	if request.Purpose == "" {
+		return fmt.Errorf("%w: 使用目的不能为空", ErrInvalidAccessRequest)
+	}
+	if request.DurationDays < 1 || request.DurationDays > MaxAccessDurationDays {
+		return fmt.Errorf("%w: 使用期限必须为1到%d天", ErrInvalidAccessRequest, MaxAccessDurationDays)
+	}
+	return nil
+}
+
+// ApproveDataAccessRequest 审批数据使用申请，通过时按使用期限生成授权并返回，驳回时返回nil
+func (s *SharingService) ApproveDataAccessRequest(ctx context.Context, id, approver string, approved bool, comment string) (*models.DataAccessGrant, error) {
+	var grant *models.DataAccessGrant
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var request models.DataAccessRequest
+		if err := tx.First(&request, "id = ?", id).Error; err != nil {
+			return err
+		}
+		if request.Status != models.DataAccessRequestPending {
+			return ErrAccessRequestNotPending
+		}
+
+		now := time.Now()
+		updates := map[string]interface{}{
+			"approver_id":      approver,
+			"approver_name":    approver,
+			"approval_comment": comment,
+			"approved_at":      now,
+			"status":           models.DataAccessRequestRejected,
+		}
+		if approved {
+			if request.DurationDays < 1 {
+				return fmt.Errorf("%w: 申请未指定使用期限", ErrInvalidAccessRequest)
+			}
+			validUntil := now.AddDate(0, 0, request.DurationDays)
+			updates["status"] = models.DataAccessRequestApproved
+			updates["valid_until"] = validUntil
+			grant = &models.DataAccessGrant{
+				RequestID:        request.ID,
+				RequesterID:      request.RequesterID,
+				RequesterName:    request.RequesterName,
+				ResourceType:     request.ResourceType,
+				ResourceID:       request.ResourceID,
+				AccessPermission: request.AccessPermission,
+				Purpose:          request.Purpose,
+				ValidFrom:        now,
+				ValidUntil:       validUntil,
+				GrantedBy:        approver,
+			}
+		}
+
+		// 按状态条件更新，并发审批时只有一次生效
+		result := tx.Model(&models.DataAccessRequest{}).
+			Where("id = ? AND status = ?", id, models.DataAccessRequestPending).Updates(updates)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrAccessRequestNotPending
+		}
+		if grant == nil {
+			return nil
+		}
+		return tx.Create(grant).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if grant != nil {
+		eventlog.Record(ctx, eventlog.Event{
+			Type:       eventlog.EventDataAccessGranted,
+			Message:    fmt.Sprintf("%s 获得 %s %s 的%s授权，有效期至 %s", grant.RequesterID, grant.ResourceType, grant.ResourceID, grant.AccessPermission, grant.ValidUntil.Format(time.DateTime)),
+			ObjectType: "data_access_grant",
+			ObjectID:   grant.ID,
+			Attributes: map[string]interface{}{
+				"request_id":   grant.RequestID,
+				"requester_id": grant.RequesterID,
+				"purpose":      grant.Purpose,
+				"granted_by":   approver,
+				"valid_until":  grant.ValidUntil.Format(time.RFC3339),
+			},
+		})
+	}
+	return grant, nil
+}
+
+// ListDataAccessGrants 按条件分页查询数据使用授权，按生效时间倒序
+func (s *SharingService) ListDataAccessGrants(ctx context.Context, q DataAccessGrantQuery) ([]models.DataAccessGrant, int64, error) {
+	db := s.db.WithContext(ctx).Model(&models.DataAccessGrant{})
+	if q.RequesterID != "" {
+		db = db.Where("requester_id = ?", q.RequesterID)
+	}
+	if q.ResourceType != "" {
+		db = db.Where("resource_type = ?", q.ResourceType)
+	}
+	if q.ResourceID != "" {
+		db = db.Where("resource_id = ?", q.ResourceID)
+	}
+	if q.Status != "" {
+		db = db.Where("status = ?", q.Status)
+	}
+	if q.Purpose != "" {
+		db = db.Where("purpose LIKE ?", "%"+q.Purpose+"%")
+	}
+	// 授权实际结束时间为回收时间，未回收时为有效期
+	if q.From != nil {
+		db = db.Where("COALESCE(revoked_at, valid_until) >= ?", *q.From)
+	}
+	if q.To != nil {
+		db = db.Where("valid_from <= ?", *q.To)
+	}
+
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("查询数据使用授权失败: %w", err)
+	}
+	page, size := models.NormalizePage(q.Page, q.Size)
+	var grants []models.DataAccessGrant
+	if err := db.Order("valid_from DESC").Offset((page - 1) * size).Limit(size).Find(&grants).Error; err != nil {
+		return nil, 0, fmt.Errorf("查询数据使用授权失败: %w", err)
+	}
+	return grants, total, nil
+}
+
+// GetDataAccessGrant 获取数据使用授权
+func (s *SharingService) GetDataAccessGrant(ctx context.Context, id string) (*models.DataAccessGrant, error) {
+	var grant models.DataAccessGrant
+	if err := s.db.WithContext(ctx).First(&grant, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &grant, nil
+}
+
+// RevokeDataAccessGrant 在到期前撤销授权
+func (s *SharingService) RevokeDataAccessGrant(ctx context.Context, id, operator, reason string) (*models.DataAccessGrant, error) {
+	grant, err := s.GetDataAccessGrant(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if grant.Status != models.DataAccessGrantActive {
+		return nil, ErrAccessGrantNotActive
+	}
+	if err := s.closeGrant(ctx, grant, models.DataAccessGrantRevoked, operator, reason, time.Now()); err != nil {
+		return nil, err
+	}
+	return grant, nil
+}
+
+// ExpireDataAccessGrants 回收有效期已过的授权，返回回收的数量
+func (s *SharingService) ExpireDataAccessGrants(ctx context.Context, now time.Time) (int, error) {
+	var grants []models.DataAccessGrant
+	if err := s.db.WithContext(ctx).
+		Where("status = ? AND valid_until <= ?", models.DataAccessGrantActive, now).
+		Find(&grants).Error; err != nil {
+		return 0, fmt.Errorf("查询到期授权失败: %w", err)
+	}
+
+	expired := 0
+	for i := range grants {
+		err := s.closeGrant(ctx, &grants[i], models.DataAccessGrantExpired, identity.SystemOperator, grantExpiredReason, now)
+		if errors.Is(err, ErrAccessGrantNotActive) {
+			continue
+		}
+		if err != nil {
+			return expired, err
+		}
+		expired++
+	}
+	return expired, nil
+}
+
+// closeGrant 将生效中的授权记为到期或撤销，同时将对应申请记为expired
+func (s *SharingService) closeGrant(ctx context.Context, grant *models.DataAccessGrant, status, operator, reason string, at time.Time) error {
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.DataAccessGrant{}).
+			Where("id = ? AND status = ?", grant.ID, models.DataAccessGrantActive).
+			Updates(map[string]interface{}{
+				"status":        status,
+				"revoked_at":    at,
+				"revoked_by":    operator,
+				"revoke_reason": reason,
+				"updated_at":    at,
+			})
+		if result.Error != nil {
+			return fmt.Errorf("回收数据使用授权失败: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return ErrAccessGrantNotActive
+		}
+		return tx.Model(&models.DataAccessRequest{}).Where("id = ?", grant.RequestID).
+			Update("status", models.DataAccessRequestExpired).Error
+	})
+	if err != nil {
+		return err
+	}
+
+	grant.Status = status
+	grant.RevokedAt = &at
+	grant.RevokedBy = operator
+	grant.RevokeReason = reason
+	eventlog.Record(ctx, eventlog.Event{
+		Type:       eventlog.EventDataAccessRevoked,
+		Message:    fmt.Sprintf("%s 对 %s %s 的授权已回收: %s", grant.RequesterID, grant.ResourceType, grant.ResourceID, reason),
+		ObjectType: "data_access_grant",
+		ObjectID:   grant.ID,
+		Attributes: map[string]interface{}{
+			"request_id":   grant.RequestID,
+			"requester_id": grant.RequesterID,
+			"status":       status,
+			"revoked_by":   operator,
+		},
+	})
+	return nil
+}
+
+// GrantExpirer 到期授权回收调度器
+type GrantExpirer struct {
+	service  *SharingService
+	lock     distributed_lock.DistributedLock
+	schedule string
+	cron     *cron.Cron
+	ctx      context.Context
+	cancel   context.CancelFunc
+	started  bool
+}
+
+// NewGrantExpirer 创建到期授权回收调度器，回收周期可通过DATA_ACCESS_EXPIRY_SCHEDULE配置
+func NewGrantExpirer(service *SharingService) *GrantExpirer {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	schedule := os.Getenv("DATA_ACCESS_EXPIRY_SCHEDULE")
+	if schedule == "" {
+		schedule = defaultGrantExpirySchedule
+	}
+
+	return &GrantExpirer{
+		service:  service,
+		schedule: schedule,
+		cron:     cron.New(cron.WithSeconds()),
+		ctx:      ctx,
+		cancel:   cancel,
+	}
+}
+
+// SetDistributedLock 设置分布式锁，多实例部署时避免重复回收
+func (e *GrantExpirer) SetDistributedLock(lock distributed_lock.DistributedLock) {
+	e.lock = lock
+}
+
+// Start 启动定时回收任务
+func (e *GrantExpirer) Start() error {
+	if e.started {
+		return fmt.Errorf("授权回收调度器已经启动")
+	}
+
+	_, err := e.cron.AddFunc(e.schedule, func() {
+		if e.lock != nil {
+			locked, err := e.lock.TryLock(e.ctx, grantExpiryLockKey, grantExpiryLockTTL)
+			if err != nil || !locked {
+				return
+			}
+			defer func() {
+				if err := e.lock.Unlock(context.Background(), grantExpiryLockKey); err != nil {
+					slog.Error("释放授权回收锁失败", "error", err)
+				}
+			}()
+		}
+
+		expired, err := e.service.ExpireDataAccessGrants(e.ctx, time.Now())
+		if err != nil {
+			slog.Error("回收到期数据使用授权失败", "expired", expired, "error", err)
+			return
+		}
+		if expired > 0 {
+			slog.Info("已回收到期数据使用授权", "expired", expired)
+		}
+	})
+	if err != nil {
+		return fmt.Errorf("添加定时任务失败: %w", err)
+	}
+
+	e.cron.Start()
+	e.started = true
+	slog.Info("授权回收调度器启动成功", "schedule", e.schedule)
+	return nil
+}
+
+// Stop 停止定时回收任务
+func (e *GrantExpirer) Stop() {
+	if !e.started {
+		return
+	}
+	e.cancel()
+	e.cron.Stop()
+	e.started = false
+}
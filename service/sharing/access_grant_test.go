@@ -0,0 +1,149 @@
+/*
+ * @module service/sharing/access_grant_test
+ * @description 数据使用授权测试，覆盖申请校验、审批生成限期授权、重复审批、到期回收、人工撤销和按时段查询
+ * @architecture 测试层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow 初始化内存数据库 -> 创建申请 -> 审批 -> 回收/撤销 -> 验证授权和申请状态
+ * @rules 使用SQLite内存数据库，不依赖外部服务
+ * @dependencies github.com/stretchr/testify, gorm.io/driver/sqlite
+ * @refs service/sharing/access_grant.go
+ */
+
+package sharing
+
+import (
+	"context"
+	"datahub-service/service/models"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func setupAccessGrantService(t *testing.T) *SharingService {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&models.DataAccessRequest{}, &models.DataAccessGrant{}))
+
+	return NewSharingService(db)
+}
+
+func createAccessRequest(t *testing.T, s *SharingService, durationDays int) *models.DataAccessRequest {
+	request := &models.DataAccessRequest{
+		RequesterID:      "alice",
+		ResourceID:       "lib-1",
+		ResourceType:     "thematic_library",
+		RequestReason:    "统计分析",
+		Purpose:          "季度人口统计分析",
+		DurationDays:     durationDays,
+		AccessPermission: "read",
+		Status:           models.DataAccessRequestApproved, // 请求体中的状态不生效
+	}
+	require.NoError(t, s.CreateDataAccessRequest(request))
+	return request
+}
+
+func TestCreateDataAccessRequestRequiresPurpose(t *testing.T) {
+	s := setupAccessGrantService(t)
+
+	request := &models.DataAccessRequest{
+		RequesterID: "alice", ResourceID: "lib-1", ResourceType: "basic_library",
+		RequestReason: "分析", AccessPermission: "read", DurationDays: 30,
+	}
+	assert.ErrorIs(t, s.CreateDataAccessRequest(request), ErrInvalidAccessRequest)
+
+	request.Purpose = "分析"
+	request.DurationDays = MaxAccessDurationDays + 1
+	assert.ErrorIs(t, s.CreateDataAccessRequest(request), ErrInvalidAccessRequest)
+
+	created := createAccessRequest(t, s, 30)
+	assert.Equal(t, models.DataAccessRequestPending, created.Status)
+}
+
+func TestApproveDataAccessRequest(t *testing.T) {
+	s := setupAccessGrantService(t)
+	ctx := context.Background()
+
+	request := createAccessRequest(t, s, 30)
+	grant, err := s.ApproveDataAccessRequest(ctx, request.ID, "admin", true, "同意")
+	require.NoError(t, err)
+	require.NotNil(t, grant)
+	assert.Equal(t, models.DataAccessGrantActive, grant.Status)
+	assert.Equal(t, "季度人口统计分析", grant.Purpose)
+	assert.Equal(t, "admin", grant.GrantedBy)
+	assert.WithinDuration(t, grant.ValidFrom.AddDate(0, 0, 30), grant.ValidUntil, time.Second)
+
+	stored, err := s.GetDataAccessRequestByID(request.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.DataAccessRequestApproved, stored.Status)
+	require.NotNil(t, stored.ValidUntil)
+
+	// 已审批的申请不能重复审批
+	_, err = s.ApproveDataAccessRequest(ctx, request.ID, "admin", false, "")
+	assert.ErrorIs(t, err, ErrAccessRequestNotPending)
+
+	// 驳回不生成授权
+	rejected := createAccessRequest(t, s, 7)
+	grant, err = s.ApproveDataAccessRequest(ctx, rejected.ID, "admin", false, "用途不明确")
+	require.NoError(t, err)
+	assert.Nil(t, grant)
+	_, total, err := s.ListDataAccessGrants(ctx, DataAccessGrantQuery{RequesterID: "alice"})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+}
+
+func TestExpireAndRevokeDataAccessGrants(t *testing.T) {
+	s := setupAccessGrantService(t)
+	ctx := context.Background()
+
+	short, err := s.ApproveDataAccessRequest(ctx, createAccessRequest(t, s, 1).ID, "admin", true, "")
+	require.NoError(t, err)
+	long, err := s.ApproveDataAccessRequest(ctx, createAccessRequest(t, s, 90).ID, "admin", true, "")
+	require.NoError(t, err)
+
+	// 到期前不回收
+	expired, err := s.ExpireDataAccessGrants(ctx, time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, 0, expired)
+
+	expireAt := time.Now().Add(48 * time.Hour)
+	expired, err = s.ExpireDataAccessGrants(ctx, expireAt)
+	require.NoError(t, err)
+	assert.Equal(t, 1, expired)
+
+	stored, err := s.GetDataAccessGrant(ctx, short.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.DataAccessGrantExpired, stored.Status)
+	assert.Equal(t, "system", stored.RevokedBy)
+	request, err := s.GetDataAccessRequestByID(short.RequestID)
+	require.NoError(t, err)
+	assert.Equal(t, models.DataAccessRequestExpired, request.Status)
+
+	revoked, err := s.RevokeDataAccessGrant(ctx, long.ID, "auditor", "项目提前结束")
+	require.NoError(t, err)
+	assert.Equal(t, models.DataAccessGrantRevoked, revoked.Status)
+	_, err = s.RevokeDataAccessGrant(ctx, long.ID, "auditor", "重复撤销")
+	assert.ErrorIs(t, err, ErrAccessGrantNotActive)
+
+	// 到期和撤销的授权仍可按时段查询
+	from := time.Now().Add(-time.Hour)
+	grants, total, err := s.ListDataAccessGrants(ctx, DataAccessGrantQuery{From: &from})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+	assert.Len(t, grants, 2)
+
+	later := expireAt.Add(time.Hour)
+	_, total, err = s.ListDataAccessGrants(ctx, DataAccessGrantQuery{From: &later})
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), total)
+
+	_, total, err = s.ListDataAccessGrants(ctx, DataAccessGrantQuery{Status: models.DataAccessGrantRevoked, Purpose: "人口"})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+}
@@ -0,0 +1,215 @@
+/*
+ * @module service/sharing/api_key
+ * @description ApiKey生命周期管理，提供授权范围校验、密钥轮换、吊销和带范围的身份认证
+ * @architecture 分层架构 - 业务服务层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow 创建(active) -> 轮换(旧Key宽限期后过期) / 吊销(revoked) -> 认证时校验状态、过期时间和授权范围
+ * @rules Key值仅在创建/轮换时返回一次，数据库只存储bcrypt哈希；未配置授权范围的历史Key视为share:read
+ * @dependencies datahub-service/service/models, gorm.io/gorm
+ * @refs api/middleware/api_key_auth.go, api/controllers/sharing_controller.go
+ */
+
+package sharing
+
+import (
+	"datahub-service/service/models"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ErrApiKeyScopeDenied ApiKey未被授予所需的授权范围
+var ErrApiKeyScopeDenied = errors.New("API Key未被授予所需的授权范围")
+
+// MaxApiKeyRotationGracePeriod 轮换时旧Key的最长宽限期
+const MaxApiKeyRotationGracePeriod = 7 * 24 * time.Hour
+
+// supportedApiKeyScopes 支持的授权范围
+var supportedApiKeyScopes = map[string]bool{
+	models.ApiKeyScopeShareRead:   true,
+	models.ApiKeyScopeIngestWrite: true,
+	models.ApiKeyScopeAll:         true,
+}
+
+// NormalizeApiKeyScopes 校验并去重授权范围，为空时返回默认的share:read
+func NormalizeApiKeyScopes(scopes []string) (models.JSONBStringArray, error) {
+	if len(scopes) == 0 {
+		return models.JSONBStringArray{models.ApiKeyScopeShareRead}, nil
+	}
+
+	seen := make(map[string]bool, len(scopes))
+	normalized := make(models.JSONBStringArray, 0, len(scopes))
+	for _, scope := range scopes {
+		if !supportedApiKeyScopes[scope] {
+			return nil, fmt.Errorf("不支持的授权范围: %s", scope)
+		}
+		if seen[scope] {
+			continue
+		}
+		seen[scope] = true
+		normalized = append(normalized, scope)
+	}
+	return normalized, nil
+}
+
+// ApiKeyHasScope 判断ApiKey是否拥有指定授权范围
+func ApiKeyHasScope(key *models.ApiKey, scope string) bool {
+	if key == nil {
+		return false
+	}
+	// 兼容未配置授权范围的历史Key
+	if len(key.Scopes) == 0 {
+		return scope == models.ApiKeyScopeShareRead
+	}
+	for _, s := range key.Scopes {
+		if s == models.ApiKeyScopeAll || s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// AuthenticateApiKey 验证Key值、状态、过期时间和授权范围，并记录最后使用时间和来源IP
+func (s *SharingService) AuthenticateApiKey(keyValue, scope, clientIP string) (*models.ApiKey, error) {
+	key, err := s.matchApiKey(keyValue)
+	if err != nil {
+		return nil, err
+	}
+
+	if scope != "" && !ApiKeyHasScope(key, scope) {
+		return nil, fmt.Errorf("%w: %s", ErrApiKeyScopeDenied, scope)
+	}
+
+	s.recordApiKeyUsage(key, clientIP)
+	return key, nil
+}
+
+// recordApiKeyUsage 更新最后使用时间、来源IP和使用次数，失败不影响认证结果
+func (s *SharingService) recordApiKeyUsage(key *models.ApiKey, clientIP string) {
+	now := time.Now()
+	updates := map[string]interface{}{
+		"last_used_at": now,
+		"usage_count":  gorm.Expr("usage_count + 1"),
+	}
+	if clientIP != "" {
+		updates["last_used_ip"] = clientIP
+		key.LastUsedIP = clientIP
+	}
+	s.db.Model(&models.ApiKey{}).Where("id = ?", key.ID).UpdateColumns(updates)
+
+	key.LastUsedAt = &now
+	key.UsageCount++
+}
+
+// UpdateApiKeyScopes 更新ApiKey的授权范围
+func (s *SharingService) UpdateApiKeyScopes(keyID string, scopes []string) error {
+	normalized, err := NormalizeApiKeyScopes(scopes)
+	if err != nil {
+		return err
+	}
+
+	result := s.db.Model(&models.ApiKey{}).Where("id = ?", keyID).Update("scopes", normalized)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("ApiKey不存在")
+	}
+	return nil
+}
+
+// RotateApiKey 轮换ApiKey：生成继承名称、应用和授权范围的新Key，旧Key在宽限期后失效（宽限期为0时立即吊销）
+func (s *SharingService) RotateApiKey(keyID string, gracePeriod time.Duration, operator string) (*models.ApiKey, string, error) {
+	if gracePeriod < 0 || gracePeriod > MaxApiKeyRotationGracePeriod {
+		return nil, "", fmt.Errorf("宽限期必须在0到%s之间", MaxApiKeyRotationGracePeriod)
+	}
+
+	var oldKey models.ApiKey
+	if err := s.db.Preload("Applications").First(&oldKey, "id = ?", keyID).Error; err != nil {
+		return nil, "", errors.New("ApiKey不存在")
+	}
+	if oldKey.Status == models.ApiKeyStatusRevoked {
+		return nil, "", errors.New("已吊销的ApiKey不能轮换")
+	}
+
+	appIDs := make([]string, 0, len(oldKey.Applications))
+	for _, app := range oldKey.Applications {
+		appIDs = append(appIDs, app.ID)
+	}
+	if len(appIDs) == 0 {
+		return nil, "", errors.New("ApiKey未关联任何应用")
+	}
+
+	scopes := oldKey.Scopes
+	if len(scopes) == 0 {
+		scopes = models.JSONBStringArray{models.ApiKeyScopeShareRead}
+	}
+
+	newKey := &models.ApiKey{
+		Name:          oldKey.Name,
+		Description:   oldKey.Description,
+		Scopes:        scopes,
+		ExpiresAt:     oldKey.ExpiresAt,
+		Status:        models.ApiKeyStatusActive,
+		RotatedFromID: &oldKey.ID,
+		CreatedBy:     operator,
+		UpdatedBy:     operator,
+	}
+
+	var fullKey string
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var createErr error
+		fullKey, createErr = createApiKeyWithApplications(tx, newKey, appIDs)
+		if createErr != nil {
+			return createErr
+		}
+
+		now := time.Now()
+		if gracePeriod == 0 {
+			return revokeApiKeyTx(tx, oldKey.ID, operator, "已轮换", now)
+		}
+
+		graceExpiry := now.Add(gracePeriod)
+		if oldKey.ExpiresAt != nil && oldKey.ExpiresAt.Before(graceExpiry) {
+			return nil
+		}
+		return tx.Model(&models.ApiKey{}).Where("id = ?", oldKey.ID).Updates(map[string]interface{}{
+			"expires_at": graceExpiry,
+			"updated_by": operator,
+		}).Error
+	})
+	if err != nil {
+		return nil, "", err
+	}
+
+	if err := s.db.Preload("Applications").First(newKey, "id = ?", newKey.ID).Error; err != nil {
+		return nil, "", err
+	}
+	return newKey, fullKey, nil
+}
+
+// RevokeApiKey 吊销ApiKey，保留记录以便审计，吊销后立即无法认证
+func (s *SharingService) RevokeApiKey(keyID, operator, reason string) error {
+	var key models.ApiKey
+	if err := s.db.First(&key, "id = ?", keyID).Error; err != nil {
+		return errors.New("ApiKey不存在")
+	}
+	if key.Status == models.ApiKeyStatusRevoked {
+		return errors.New("ApiKey已吊销")
+	}
+
+	return revokeApiKeyTx(s.db, keyID, operator, reason, time.Now())
+}
+
+// revokeApiKeyTx 将ApiKey标记为已吊销
+func revokeApiKeyTx(tx *gorm.DB, keyID, operator, reason string, revokedAt time.Time) error {
+	return tx.Model(&models.ApiKey{}).Where("id = ?", keyID).Updates(map[string]interface{}{
+		"status":        models.ApiKeyStatusRevoked,
+		"revoked_at":    revokedAt,
+		"revoked_by":    operator,
+		"revoke_reason": reason,
+		"updated_by":    operator,
+	}).Error
+}
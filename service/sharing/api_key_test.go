@@ -0,0 +1,140 @@
+/*
+ * @module service/sharing/api_key_test
+ * @description ApiKey生命周期测试，覆盖授权范围校验、认证、轮换和吊销
+ * @architecture 测试层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow 初始化内存数据库 -> 创建应用和Key -> 认证/轮换/吊销 -> 验证结果
+ * @rules 使用SQLite内存数据库，不依赖外部服务
+ * @dependencies github.com/stretchr/testify, gorm.io/driver/sqlite
+ * @refs service/sharing/api_key.go
+ */
+
+package sharing
+
+import (
+	"datahub-service/service/models"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func setupApiKeyService(t *testing.T) (*SharingService, string) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+	require.NoError(t, db.SetupJoinTable(&models.ApiKey{}, "Applications", &models.ApiKeyApplication{}))
+	require.NoError(t, db.SetupJoinTable(&models.ApiApplication{}, "ApiKeys", &models.ApiKeyApplication{}))
+	require.NoError(t, db.AutoMigrate(&models.ApiKey{}))
+
+	appID := "11111111-1111-1111-1111-111111111111"
+	require.NoError(t, db.Exec(`INSERT INTO api_applications (id, name, path, thematic_library_id, contact_person, contact_phone, status)
+		VALUES (?, 'app', 'app', 'lib', 'tester', '000', 'active')`, appID).Error)
+
+	return NewSharingService(db), appID
+}
+
+func TestNormalizeApiKeyScopes(t *testing.T) {
+	scopes, err := NormalizeApiKeyScopes(nil)
+	require.NoError(t, err)
+	assert.Equal(t, models.JSONBStringArray{models.ApiKeyScopeShareRead}, scopes)
+
+	scopes, err = NormalizeApiKeyScopes([]string{models.ApiKeyScopeIngestWrite, models.ApiKeyScopeIngestWrite})
+	require.NoError(t, err)
+	assert.Equal(t, models.JSONBStringArray{models.ApiKeyScopeIngestWrite}, scopes)
+
+	_, err = NormalizeApiKeyScopes([]string{"admin"})
+	assert.Error(t, err)
+}
+
+func TestApiKeyHasScope(t *testing.T) {
+	legacy := &models.ApiKey{}
+	assert.True(t, ApiKeyHasScope(legacy, models.ApiKeyScopeShareRead))
+	assert.False(t, ApiKeyHasScope(legacy, models.ApiKeyScopeIngestWrite))
+
+	all := &models.ApiKey{Scopes: models.JSONBStringArray{models.ApiKeyScopeAll}}
+	assert.True(t, ApiKeyHasScope(all, models.ApiKeyScopeIngestWrite))
+	assert.False(t, ApiKeyHasScope(nil, models.ApiKeyScopeShareRead))
+}
+
+func TestAuthenticateApiKey(t *testing.T) {
+	s, appID := setupApiKeyService(t)
+
+	key, keyValue, err := s.CreateApiKey("ingest", "", []string{appID}, []string{models.ApiKeyScopeIngestWrite}, nil)
+	require.NoError(t, err)
+
+	authed, err := s.AuthenticateApiKey(keyValue, models.ApiKeyScopeIngestWrite, "10.0.0.1")
+	require.NoError(t, err)
+	assert.Equal(t, key.ID, authed.ID)
+
+	_, err = s.AuthenticateApiKey(keyValue, models.ApiKeyScopeShareRead, "10.0.0.1")
+	assert.ErrorIs(t, err, ErrApiKeyScopeDenied)
+
+	_, err = s.AuthenticateApiKey(keyValue[:8]+"invalid", models.ApiKeyScopeIngestWrite, "")
+	assert.Error(t, err)
+
+	stored, err := s.GetApiKeyByID(key.ID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), stored.UsageCount)
+	assert.Equal(t, "10.0.0.1", stored.LastUsedIP)
+	assert.NotNil(t, stored.LastUsedAt)
+}
+
+func TestRotateApiKey(t *testing.T) {
+	s, appID := setupApiKeyService(t)
+
+	oldKey, oldValue, err := s.CreateApiKey("share", "", []string{appID}, nil, nil)
+	require.NoError(t, err)
+
+	// 带宽限期轮换，旧Key在宽限期内仍可使用
+	newKey, newValue, err := s.RotateApiKey(oldKey.ID, time.Hour, "tester")
+	require.NoError(t, err)
+	require.NotNil(t, newKey.RotatedFromID)
+	assert.Equal(t, oldKey.ID, *newKey.RotatedFromID)
+	assert.Len(t, newKey.Applications, 1)
+	assert.NotEqual(t, oldValue, newValue)
+
+	_, err = s.AuthenticateApiKey(oldValue, models.ApiKeyScopeShareRead, "")
+	assert.NoError(t, err)
+	stored, err := s.GetApiKeyByID(oldKey.ID)
+	require.NoError(t, err)
+	require.NotNil(t, stored.ExpiresAt)
+	assert.WithinDuration(t, time.Now().Add(time.Hour), *stored.ExpiresAt, time.Minute)
+
+	// 无宽限期轮换，旧Key立即吊销
+	_, latestValue, err := s.RotateApiKey(newKey.ID, 0, "tester")
+	require.NoError(t, err)
+	_, err = s.AuthenticateApiKey(newValue, models.ApiKeyScopeShareRead, "")
+	assert.Error(t, err)
+	_, err = s.AuthenticateApiKey(latestValue, models.ApiKeyScopeShareRead, "")
+	assert.NoError(t, err)
+
+	_, _, err = s.RotateApiKey(newKey.ID, 0, "tester")
+	assert.Error(t, err)
+	_, _, err = s.RotateApiKey(oldKey.ID, 8*24*time.Hour, "tester")
+	assert.Error(t, err)
+}
+
+func TestRevokeApiKey(t *testing.T) {
+	s, appID := setupApiKeyService(t)
+
+	key, keyValue, err := s.CreateApiKey("share", "", []string{appID}, nil, nil)
+	require.NoError(t, err)
+
+	require.NoError(t, s.RevokeApiKey(key.ID, "tester", "泄露"))
+	_, err = s.AuthenticateApiKey(keyValue, models.ApiKeyScopeShareRead, "")
+	assert.Error(t, err)
+
+	stored, err := s.GetApiKeyByID(key.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.ApiKeyStatusRevoked, stored.Status)
+	assert.Equal(t, "tester", stored.RevokedBy)
+	assert.NotNil(t, stored.RevokedAt)
+
+	assert.Error(t, s.RevokeApiKey(key.ID, "tester", ""))
+}
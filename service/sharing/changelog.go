@@ -0,0 +1,150 @@
+/*
+ * @module service/sharing/changelog
+ * @description 共享接口变更日志读取，下游按序号或时间游标拉取主题接口的新增、修改、删除记录，只需按主键补读变化的行，不必重新读取整张表
+ * @architecture 分层架构 - 业务服务层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow 解析since游标(序号或RFC3339时间) -> 时间游标换算为序号 -> 按序号升序读取一页 -> 返回下次拉取的游标
+ * @rules 主题接口须配置过变更日志策略，关闭后仍可拉取已有的变更日志；since之后有变更已超出保留期被清理时标记resync_required，下游需要重新读取全量数据；
+ *        每页默认defaultChangelogPageSize条，最多maxChangelogPageSize条
+ * @dependencies datahub-service/service/models, gorm.io/gorm
+ * @refs service/models/thematic_changelog.go, service/thematic_library/thematic_sync/changelog_recorder.go, api/controllers/data_proxy_controller.go
+ */
+
+package sharing
+
+import (
+	"datahub-service/service/models"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultChangelogPageSize = 1000  // 每页默认条数
+	maxChangelogPageSize     = 10000 // 每页最多条数
+)
+
+var (
+	// ErrChangelogNotEnabled 主题接口没有开启变更日志
+	ErrChangelogNotEnabled = errors.New("接口未开启变更日志")
+	// ErrInvalidChangelogCursor since游标不合法
+	ErrInvalidChangelogCursor = errors.New("since须为变更序号或RFC3339时间")
+)
+
+// ChangelogPage 一页变更日志
+type ChangelogPage struct {
+	InterfaceID    string                          `json:"interface_id"`    // 共享接口ID，由调用方填写
+	Since          int64                           `json:"since"`           // 本次拉取的起始序号（不含）
+	NextSince      int64                           `json:"next_since"`      // 下次拉取时传入的since
+	HasMore        bool                            `json:"has_more"`        // 是否还有更多变更
+	ResyncRequired bool                            `json:"resync_required"` // since之后的部分变更已被清理，需要重新读取全量数据
+	Entries        []models.ThematicChangelogEntry `json:"entries"`
+}
+
+// GetActiveApiInterface 按ID获取启用且已发布的共享接口
+func (s *SharingService) GetActiveApiInterface(id string) (*models.ApiInterface, error) {
+	var apiInterface models.ApiInterface
+	if err := s.db.Joins("JOIN api_applications ON api_interfaces.api_application_id = api_applications.id").
+		Joins("JOIN thematic_interfaces ON api_interfaces.thematic_interface_id = thematic_interfaces.id").
+		Where("api_interfaces.id = ? AND api_interfaces.status = 'active' AND api_applications.status = 'active'", id).
+		Where("thematic_interfaces.publish_status = ?", models.ThematicPublishStatusPublished).
+		Preload("ApiApplication").Preload("ApiApplication.ThematicLibrary").Preload("ThematicInterface").
+		First(&apiInterface).Error; err != nil {
+		return nil, err
+	}
+	return &apiInterface, nil
+}
+
+// ReadChangelog 读取主题接口since之后的一页变更日志，since为空时从保留的第一条开始
+func (s *SharingService) ReadChangelog(thematicInterfaceID, since string, limit int) (*ChangelogPage, error) {
+	var policyCount int64
+	if err := s.db.Model(&models.ThematicChangelogPolicy{}).Where("thematic_interface_id = ?", thematicInterfaceID).Count(&policyCount).Error; err != nil {
+		return nil, fmt.Errorf("查询变更日志策略失败: %w", err)
+	}
+	if policyCount == 0 {
+		return nil, ErrChangelogNotEnabled
+	}
+	if limit <= 0 {
+		limit = defaultChangelogPageSize
+	}
+	if limit > maxChangelogPageSize {
+		limit = maxChangelogPageSize
+	}
+
+	table := models.ChangelogTable(thematicInterfaceID)
+	var earliest *int64
+	if err := s.db.Raw("SELECT MIN(seq) FROM " + table).Scan(&earliest).Error; err != nil {
+		return nil, fmt.Errorf("查询变更日志失败: %w", err)
+	}
+	cursor, err := s.resolveChangelogCursor(table, since)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &ChangelogPage{Entries: []models.ThematicChangelogEntry{}}
+	if since != "" && earliest != nil && cursor < *earliest-1 {
+		// since之后、保留的第一条之前的变更已被清理；时间游标早于保留的第一条时从第一条开始
+		page.ResyncRequired = true
+		if cursor == 0 {
+			cursor = *earliest - 1
+		}
+	}
+	page.Since, page.NextSince = cursor, cursor
+
+	rows, err := s.db.Raw("SELECT seq, op, pk::text, COALESCE(changed_columns::text, ''), COALESCE(execution_id, ''), changed_at FROM "+table+
+		" WHERE seq > ? ORDER BY seq LIMIT ?", cursor, limit+1).Rows()
+	if err != nil {
+		return nil, fmt.Errorf("查询变更日志失败: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var entry models.ThematicChangelogEntry
+		var pk, columns string
+		if err := rows.Scan(&entry.Seq, &entry.Op, &pk, &columns, &entry.ExecutionID, &entry.ChangedAt); err != nil {
+			return nil, fmt.Errorf("读取变更日志失败: %w", err)
+		}
+		if len(page.Entries) == limit {
+			page.HasMore = true
+			break
+		}
+		if err := json.Unmarshal([]byte(pk), &entry.PK); err != nil {
+			return nil, fmt.Errorf("解析变更主键失败: %w", err)
+		}
+		if columns != "" {
+			if err := json.Unmarshal([]byte(columns), &entry.ChangedColumns); err != nil {
+				return nil, fmt.Errorf("解析修改列失败: %w", err)
+			}
+		}
+		page.Entries = append(page.Entries, entry)
+		page.NextSince = entry.Seq
+	}
+	return page, rows.Err()
+}
+
+// resolveChangelogCursor 把since解析为序号，时间游标取该时间及之前的最后一条变更的序号
+func (s *SharingService) resolveChangelogCursor(table, since string) (int64, error) {
+	since = strings.TrimSpace(since)
+	if since == "" {
+		return 0, nil
+	}
+	if seq, err := strconv.ParseInt(since, 10, 64); err == nil {
+		if seq < 0 {
+			return 0, ErrInvalidChangelogCursor
+		}
+		return seq, nil
+	}
+	at, err := time.Parse(time.RFC3339, since)
+	if err != nil {
+		return 0, ErrInvalidChangelogCursor
+	}
+
+	var seq int64
+	if err := s.db.Raw("SELECT COALESCE(MAX(seq), 0) FROM "+table+" WHERE changed_at <= ?", at).Scan(&seq).Error; err != nil {
+		return 0, fmt.Errorf("查询变更日志失败: %w", err)
+	}
+	return seq, nil
+}
@@ -0,0 +1,333 @@
+/*
+ * @module service/sharing/deprecation
+ * @description 接口弃用生命周期，标记共享接口、主题接口或基础接口弃用并设置下线时间，通知订阅方，共享接口响应带弃用头，到下线时间后不再返回数据
+ * @architecture 分层架构 - 业务服务层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow 标记弃用 -> 逐个通知订阅方(deprecated) -> 修改下线时间或替代接口时再次通知(updated) -> 到达下线时间共享接口返回410；撤销弃用时通知订阅方(cancelled)
+ * @rules
+ *   - 标记弃用必须填写原因，下线时间须晚于当前时间；重复标记且内容未变时不重复通知
+ *   - 订阅方：订阅该资源的生效中数据订阅；共享接口另包括其主题接口的数据订阅和可访问所在应用的生效中ApiKey
+ *   - 共享接口未单独标记时继承其主题接口的弃用记录；弃用的资源不能新建数据订阅，弃用的主题接口不能新建共享接口
+ * @dependencies datahub-service/service/models, datahub-service/service/eventlog, gorm.io/gorm
+ * @refs api/controllers/deprecation_controller.go, api/controllers/data_proxy_controller.go
+ */
+
+package sharing
+
+import (
+	"context"
+	"datahub-service/service/eventlog"
+	"datahub-service/service/models"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrInvalidDeprecation 弃用参数无效
+	ErrInvalidDeprecation = errors.New("接口弃用参数无效")
+	// ErrResourceDeprecated 资源已弃用，不能新建订阅
+	ErrResourceDeprecated = errors.New("资源已弃用，不能新建订阅")
+)
+
+// DeprecationInput 标记弃用的参数
+type DeprecationInput struct {
+	Reason      string
+	Replacement string     // 替代接口的地址或说明
+	SunsetAt    *time.Time // 下线时间，为空表示暂不下线
+}
+
+// deprecationConsumer 收到弃用通知的一个订阅方
+type deprecationConsumer struct {
+	Type               string // data_subscription/api_key
+	ID                 string
+	SubscriberID       string
+	SubscriberType     string
+	NotificationMethod string
+}
+
+// DeprecateInterface 标记资源弃用或修改弃用信息，内容有变化时通知订阅方
+func (s *SharingService) DeprecateInterface(ctx context.Context, resourceType, resourceID string, input *DeprecationInput, operator string) (*models.InterfaceDeprecation, error) {
+	if err := s.validateDeprecation(ctx, resourceType, resourceID, input); err != nil {
+		return nil, err
+	}
+
+	var deprecation models.InterfaceDeprecation
+	action := ""
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.First(&deprecation, "resource_type = ? AND resource_id = ?", resourceType, resourceID).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			deprecation = models.InterfaceDeprecation{
+				ResourceType: resourceType,
+				ResourceID:   resourceID,
+				Reason:       input.Reason,
+				Replacement:  input.Replacement,
+				SunsetAt:     input.SunsetAt,
+				DeprecatedBy: operator,
+				DeprecatedAt: time.Now(),
+				UpdatedBy:    operator,
+			}
+			action = models.DeprecationNoticeDeprecated
+			return tx.Create(&deprecation).Error
+		}
+		if err != nil {
+			return err
+		}
+
+		if deprecation.Reason == input.Reason && deprecation.Replacement == input.Replacement && sameTime(deprecation.SunsetAt, input.SunsetAt) {
+			return nil
+		}
+		action = models.DeprecationNoticeUpdated
+		deprecation.Reason = input.Reason
+		deprecation.Replacement = input.Replacement
+		deprecation.SunsetAt = input.SunsetAt
+		deprecation.UpdatedBy = operator
+		return tx.Model(&deprecation).Select("reason", "replacement", "sunset_at", "updated_by", "updated_at").Updates(&deprecation).Error
+	})
+	if err != nil {
+		return nil, fmt.Errorf("保存接口弃用记录失败: %w", err)
+	}
+	if action == "" {
+		return &deprecation, nil
+	}
+
+	notified := s.notifyDeprecation(ctx, &deprecation, action, operator)
+	if err := s.db.WithContext(ctx).Model(&deprecation).UpdateColumn("notified_consumers", notified).Error; err != nil {
+		return nil, fmt.Errorf("更新弃用通知数量失败: %w", err)
+	}
+	deprecation.NotifiedConsumers = notified
+	return &deprecation, nil
+}
+
+// CancelInterfaceDeprecation 撤销弃用并通知订阅方
+func (s *SharingService) CancelInterfaceDeprecation(ctx context.Context, resourceType, resourceID, operator string) error {
+	deprecation, err := s.GetInterfaceDeprecation(ctx, resourceType, resourceID)
+	if err != nil {
+		return err
+	}
+	if err := s.db.WithContext(ctx).Delete(deprecation).Error; err != nil {
+		return fmt.Errorf("删除接口弃用记录失败: %w", err)
+	}
+	s.notifyDeprecation(ctx, deprecation, models.DeprecationNoticeCancelled, operator)
+	return nil
+}
+
+// GetInterfaceDeprecation 获取资源的弃用记录
+func (s *SharingService) GetInterfaceDeprecation(ctx context.Context, resourceType, resourceID string) (*models.InterfaceDeprecation, error) {
+	var deprecation models.InterfaceDeprecation
+	if err := s.db.WithContext(ctx).First(&deprecation, "resource_type = ? AND resource_id = ?", resourceType, resourceID).Error; err != nil {
+		return nil, err
+	}
+	return &deprecation, nil
+}
+
+// ListInterfaceDeprecations 查询弃用记录，可按资源类型过滤，按下线时间先后排列，未设置下线时间的排在最后
+func (s *SharingService) ListInterfaceDeprecations(ctx context.Context, resourceType string) ([]models.InterfaceDeprecation, error) {
+	db := s.db.WithContext(ctx).Model(&models.InterfaceDeprecation{})
+	if resourceType != "" {
+		db = db.Where("resource_type = ?", resourceType)
+	}
+	var deprecations []models.InterfaceDeprecation
+	if err := db.Order("CASE WHEN sunset_at IS NULL THEN 1 ELSE 0 END, sunset_at, deprecated_at DESC").Find(&deprecations).Error; err != nil {
+		return nil, fmt.Errorf("查询接口弃用记录失败: %w", err)
+	}
+	return deprecations, nil
+}
+
+// ResolveApiInterfaceDeprecation 获取共享接口生效的弃用记录，共享接口未单独标记时取其主题接口的记录，都没有时返回nil
+func (s *SharingService) ResolveApiInterfaceDeprecation(apiInterface *models.ApiInterface) (*models.InterfaceDeprecation, error) {
+	var deprecations []models.InterfaceDeprecation
+	if err := s.db.Where("(resource_type = ? AND resource_id = ?) OR (resource_type = ? AND resource_id = ?)",
+		models.DeprecationResourceApiInterface, apiInterface.ID,
+		models.DeprecationResourceThematicInterface, apiInterface.ThematicInterfaceID).
+		Find(&deprecations).Error; err != nil {
+		return nil, err
+	}
+	var resolved *models.InterfaceDeprecation
+	for i := range deprecations {
+		if resolved == nil || deprecations[i].ResourceType == models.DeprecationResourceApiInterface {
+			resolved = &deprecations[i]
+		}
+	}
+	return resolved, nil
+}
+
+// requireNotDeprecated 校验资源未被弃用
+func (s *SharingService) requireNotDeprecated(resourceType, resourceID string) error {
+	var deprecation models.InterfaceDeprecation
+	err := s.db.First(&deprecation, "resource_type = ? AND resource_id = ?", resourceType, resourceID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if deprecation.SunsetAt != nil {
+		return fmt.Errorf("%w，计划于 %s 下线", ErrResourceDeprecated, deprecation.SunsetAt.Format(time.DateTime))
+	}
+	return ErrResourceDeprecated
+}
+
+// validateDeprecation 校验资源类型、资源存在、弃用原因和下线时间
+func (s *SharingService) validateDeprecation(ctx context.Context, resourceType, resourceID string, input *DeprecationInput) error {
+	var model interface{}
+	switch resourceType {
+	case models.DeprecationResourceApiInterface:
+		model = &models.ApiInterface{}
+	case models.DeprecationResourceThematicInterface:
+		model = &models.ThematicInterface{}
+	case models.DeprecationResourceBasicInterface:
+		model = &models.DataInterface{}
+	default:
+		return fmt.Errorf("%w: 不支持的资源类型 %s", ErrInvalidDeprecation, resourceType)
+	}
+
+	input.Reason = strings.TrimSpace(input.Reason)
+	input.Replacement = strings.TrimSpace(input.Replacement)
+	if input.Reason == "" {
+		return fmt.Errorf("%w: 弃用原因不能为空", ErrInvalidDeprecation)
+	}
+	if input.SunsetAt != nil && !input.SunsetAt.After(time.Now()) {
+		return fmt.Errorf("%w: 下线时间须晚于当前时间", ErrInvalidDeprecation)
+	}
+
+	var count int64
+	if err := s.db.WithContext(ctx).Model(model).Where("id = ?", resourceID).Count(&count).Error; err != nil {
+		return err
+	}
+	if count == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// deprecationConsumers 查询资源的订阅方
+func (s *SharingService) deprecationConsumers(ctx context.Context, deprecation *models.InterfaceDeprecation) ([]deprecationConsumer, error) {
+	db := s.db.WithContext(ctx)
+	subscriptionType, subscriptionResourceID := deprecation.ResourceType, deprecation.ResourceID
+	var apiInterface models.ApiInterface
+	if deprecation.ResourceType == models.DeprecationResourceApiInterface {
+		if err := db.First(&apiInterface, "id = ?", deprecation.ResourceID).Error; err != nil {
+			return nil, err
+		}
+		subscriptionType, subscriptionResourceID = models.DeprecationResourceThematicInterface, apiInterface.ThematicInterfaceID
+	}
+
+	// 只查询通知需要的列，通知配置等JSON列不加载
+	var subscriptions []models.DataSubscription
+	if err := db.Select("id", "subscriber_id", "subscriber_type", "notification_method").Where("resource_type = ? AND resource_id = ? AND status = ?", subscriptionType, subscriptionResourceID, "active").
+		Order("created_at").Find(&subscriptions).Error; err != nil {
+		return nil, err
+	}
+	consumers := make([]deprecationConsumer, 0, len(subscriptions))
+	for _, subscription := range subscriptions {
+		consumers = append(consumers, deprecationConsumer{
+			Type:               models.DeprecationConsumerDataSubscription,
+			ID:                 subscription.ID,
+			SubscriberID:       subscription.SubscriberID,
+			SubscriberType:     subscription.SubscriberType,
+			NotificationMethod: subscription.NotificationMethod,
+		})
+	}
+	if deprecation.ResourceType != models.DeprecationResourceApiInterface {
+		return consumers, nil
+	}
+
+	var apiKeys []models.ApiKey
+	if err := db.Joins("JOIN api_key_applications ON api_key_applications.api_key_id = api_keys.id").
+		Where("api_key_applications.api_application_id = ? AND api_keys.status = ?", apiInterface.ApiApplicationID, "active").
+		Order("api_keys.created_at").Find(&apiKeys).Error; err != nil {
+		return nil, err
+	}
+	for _, apiKey := range apiKeys {
+		consumers = append(consumers, deprecationConsumer{
+			Type:           models.DeprecationConsumerApiKey,
+			ID:             apiKey.ID,
+			SubscriberID:   apiKey.ID,
+			SubscriberType: models.DeprecationConsumerApiKey,
+		})
+	}
+	return consumers, nil
+}
+
+// notifyDeprecation 记录资源的弃用事件，并为每个订阅方记录一条弃用通知，返回通知的订阅方数量
+func (s *SharingService) notifyDeprecation(ctx context.Context, deprecation *models.InterfaceDeprecation, action, operator string) int {
+	consumers, err := s.deprecationConsumers(ctx, deprecation)
+	if err != nil {
+		// 查询订阅方失败不影响弃用记录，资源事件中记录错误
+		consumers = nil
+	}
+
+	objectType := deprecationObjectType(deprecation.ResourceType)
+	sunsetAt := ""
+	if deprecation.SunsetAt != nil {
+		sunsetAt = deprecation.SunsetAt.Format(time.RFC3339)
+	}
+	attributes := map[string]interface{}{
+		"action":      action,
+		"operator":    operator,
+		"reason":      deprecation.Reason,
+		"replacement": deprecation.Replacement,
+		"sunset_at":   sunsetAt,
+		"consumers":   len(consumers),
+	}
+	if err != nil {
+		attributes["error"] = err.Error()
+	}
+
+	eventType, level := eventlog.EventInterfaceDeprecated, models.EventLevelWarn
+	message := fmt.Sprintf("%s %s 已弃用，通知 %d 个订阅方", deprecation.ResourceType, deprecation.ResourceID, len(consumers))
+	if action == models.DeprecationNoticeCancelled {
+		eventType, level = eventlog.EventInterfaceDeprecationCancelled, models.EventLevelInfo
+		message = fmt.Sprintf("%s %s 已撤销弃用，通知 %d 个订阅方", deprecation.ResourceType, deprecation.ResourceID, len(consumers))
+	}
+	eventlog.Record(ctx, eventlog.Event{
+		Type:       eventType,
+		Level:      level,
+		Message:    message,
+		ObjectType: objectType,
+		ObjectID:   deprecation.ResourceID,
+		Attributes: attributes,
+	})
+
+	for _, consumer := range consumers {
+		eventlog.Record(ctx, eventlog.Event{
+			Type:       eventlog.EventDeprecationNotice,
+			Level:      level,
+			Message:    fmt.Sprintf("通知订阅方 %s: %s %s %s", consumer.SubscriberID, deprecation.ResourceType, deprecation.ResourceID, action),
+			ObjectType: objectType,
+			ObjectID:   deprecation.ResourceID,
+			Attributes: map[string]interface{}{
+				"action":              action,
+				"consumer_type":       consumer.Type,
+				"consumer_id":         consumer.ID,
+				"subscriber_id":       consumer.SubscriberID,
+				"subscriber_type":     consumer.SubscriberType,
+				"notification_method": consumer.NotificationMethod,
+				"reason":              deprecation.Reason,
+				"replacement":         deprecation.Replacement,
+				"sunset_at":           sunsetAt,
+			},
+		})
+	}
+	return len(consumers)
+}
+
+// deprecationObjectType 弃用事件的对象类型，基础接口沿用事件中的data_interface
+func deprecationObjectType(resourceType string) string {
+	if resourceType == models.DeprecationResourceBasicInterface {
+		return "data_interface"
+	}
+	return resourceType
+}
+
+// sameTime 判断两个可为空的时间是否相同
+func sameTime(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return a.Equal(*b)
+}
@@ -0,0 +1,147 @@
+/*
+ * @module service/sharing/deprecation_test
+ * @description 接口弃用测试，覆盖参数校验、通知订阅方（数据订阅和ApiKey）、内容未变时不重复通知、共享接口继承主题接口的弃用、阻止新建订阅和撤销弃用
+ * @architecture 测试层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow 初始化内存数据库 -> 标记弃用 -> 验证通知事件 -> 新建订阅/共享接口被拒绝 -> 撤销弃用
+ * @rules 使用SQLite内存数据库，复用灰度发布测试的应用、接口和ApiKey
+ * @dependencies github.com/stretchr/testify, gorm.io/driver/sqlite
+ * @refs service/sharing/deprecation.go
+ */
+
+package sharing
+
+import (
+	"context"
+	"datahub-service/service/eventlog"
+	"datahub-service/service/models"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func setupDeprecationService(t *testing.T) *SharingService {
+	s := setupReleaseService(t)
+	require.NoError(t, s.db.AutoMigrate(&models.InterfaceDeprecation{}, &models.DataSubscription{}, &models.ApplicationEvent{}))
+	eventlog.SetDefault(eventlog.NewService(s.db))
+
+	// ti-v1上一个生效中和一个已暂停的订阅，另一个ApiKey已吊销
+	for id, status := range map[string]string{"sub-active": "active", "sub-paused": "paused"} {
+		require.NoError(t, s.db.Exec(`INSERT INTO data_subscriptions (id, subscriber_id, subscriber_type, resource_id, resource_type, notification_method, notification_config, status)
+			VALUES (?, 'team-a', 'user', 'ti-v1', 'thematic_interface', 'webhook', '{}', ?)`, id, status).Error)
+	}
+	require.NoError(t, s.db.Model(&models.ApiKey{}).Where("id = ?", releaseOtherKeyID).Update("status", "revoked").Error)
+	return s
+}
+
+func deprecationNotices(t *testing.T, s *SharingService, action string) []models.ApplicationEvent {
+	var events []models.ApplicationEvent
+	require.NoError(t, s.db.Where("event_type = ?", eventlog.EventDeprecationNotice).Order("created_at").Find(&events).Error)
+	matched := events[:0]
+	for _, event := range events {
+		if event.Attributes["action"] == action {
+			matched = append(matched, event)
+		}
+	}
+	return matched
+}
+
+func TestDeprecateInterfaceValidation(t *testing.T) {
+	s := setupDeprecationService(t)
+	ctx := context.Background()
+	past := time.Now().Add(-time.Hour)
+
+	_, err := s.DeprecateInterface(ctx, "api_key", releaseCanaryKeyID, &DeprecationInput{Reason: "下线"}, "admin")
+	assert.ErrorIs(t, err, ErrInvalidDeprecation)
+	_, err = s.DeprecateInterface(ctx, models.DeprecationResourceApiInterface, releaseInterfaceID, &DeprecationInput{Reason: " "}, "admin")
+	assert.ErrorIs(t, err, ErrInvalidDeprecation, "原因不能为空")
+	_, err = s.DeprecateInterface(ctx, models.DeprecationResourceApiInterface, releaseInterfaceID, &DeprecationInput{Reason: "下线", SunsetAt: &past}, "admin")
+	assert.ErrorIs(t, err, ErrInvalidDeprecation, "下线时间须晚于当前时间")
+	_, err = s.DeprecateInterface(ctx, models.DeprecationResourceThematicInterface, "ti-missing", &DeprecationInput{Reason: "下线"}, "admin")
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+}
+
+func TestDeprecateApiInterfaceNotifiesConsumers(t *testing.T) {
+	s := setupDeprecationService(t)
+	ctx := context.Background()
+	sunset := time.Now().Add(30 * 24 * time.Hour).Truncate(time.Second)
+	input := &DeprecationInput{Reason: "已由人口v2替代", Replacement: "/api/v1/share/app/population-v2", SunsetAt: &sunset}
+
+	deprecation, err := s.DeprecateInterface(ctx, models.DeprecationResourceApiInterface, releaseInterfaceID, input, "admin")
+	require.NoError(t, err)
+	assert.Equal(t, 2, deprecation.NotifiedConsumers, "主题接口的生效订阅和可访问应用的ApiKey")
+	notices := deprecationNotices(t, s, models.DeprecationNoticeDeprecated)
+	require.Len(t, notices, 2)
+	consumers := map[string]interface{}{}
+	for _, notice := range notices {
+		assert.Equal(t, releaseInterfaceID, notice.ObjectID)
+		consumers[notice.Attributes["consumer_type"].(string)] = notice.Attributes["consumer_id"]
+	}
+	assert.Equal(t, map[string]interface{}{
+		models.DeprecationConsumerDataSubscription: "sub-active",
+		models.DeprecationConsumerApiKey:           releaseCanaryKeyID,
+	}, consumers)
+
+	// 内容未变时不重复通知，修改下线时间时再次通知
+	_, err = s.DeprecateInterface(ctx, models.DeprecationResourceApiInterface, releaseInterfaceID, &DeprecationInput{
+		Reason: input.Reason, Replacement: input.Replacement, SunsetAt: &sunset,
+	}, "admin")
+	require.NoError(t, err)
+	assert.Len(t, deprecationNotices(t, s, models.DeprecationNoticeUpdated), 0)
+	later := sunset.Add(24 * time.Hour)
+	deprecation, err = s.DeprecateInterface(ctx, models.DeprecationResourceApiInterface, releaseInterfaceID, &DeprecationInput{
+		Reason: input.Reason, Replacement: input.Replacement, SunsetAt: &later,
+	}, "ops")
+	require.NoError(t, err)
+	assert.Equal(t, "ops", deprecation.UpdatedBy)
+	assert.Equal(t, "admin", deprecation.DeprecatedBy)
+	assert.Len(t, deprecationNotices(t, s, models.DeprecationNoticeUpdated), 2)
+
+	resolved, err := s.ResolveApiInterfaceDeprecation(loadReleaseInterface(t, s))
+	require.NoError(t, err)
+	require.NotNil(t, resolved)
+	assert.True(t, resolved.SunsetAt.Equal(later))
+	assert.False(t, resolved.Sunset(time.Now()))
+	assert.True(t, resolved.Sunset(later))
+
+	require.NoError(t, s.CancelInterfaceDeprecation(ctx, models.DeprecationResourceApiInterface, releaseInterfaceID, "admin"))
+	assert.Len(t, deprecationNotices(t, s, models.DeprecationNoticeCancelled), 2)
+	resolved, err = s.ResolveApiInterfaceDeprecation(loadReleaseInterface(t, s))
+	require.NoError(t, err)
+	assert.Nil(t, resolved)
+	assert.ErrorIs(t, s.CancelInterfaceDeprecation(ctx, models.DeprecationResourceApiInterface, releaseInterfaceID, "admin"), gorm.ErrRecordNotFound)
+}
+
+func TestDeprecatedThematicInterfaceBlocksSubscriptions(t *testing.T) {
+	s := setupDeprecationService(t)
+	ctx := context.Background()
+	require.NoError(t, s.db.Model(&models.ThematicInterface{}).Where("id IN ?", []string{"ti-v1", "ti-v2"}).
+		Update("publish_status", models.ThematicPublishStatusPublished).Error)
+
+	deprecation, err := s.DeprecateInterface(ctx, models.DeprecationResourceThematicInterface, "ti-v1", &DeprecationInput{Reason: "改用v2"}, "admin")
+	require.NoError(t, err)
+	assert.Equal(t, 1, deprecation.NotifiedConsumers, "只通知生效中的订阅")
+
+	// 共享接口未单独标记时继承主题接口的弃用记录
+	resolved, err := s.ResolveApiInterfaceDeprecation(loadReleaseInterface(t, s))
+	require.NoError(t, err)
+	require.NotNil(t, resolved)
+	assert.Equal(t, models.DeprecationResourceThematicInterface, resolved.ResourceType)
+	assert.False(t, resolved.Sunset(time.Now()), "未设置下线时间")
+
+	err = s.CreateDataSubscription(&models.DataSubscription{
+		SubscriberID: "team-b", SubscriberType: "user", ResourceID: "ti-v1", ResourceType: "thematic_interface",
+		NotificationMethod: "email", NotificationConfig: map[string]interface{}{},
+	})
+	assert.ErrorIs(t, err, ErrResourceDeprecated)
+	err = s.CreateApiInterface(&models.ApiInterface{ApiApplicationID: releaseAppID, ThematicInterfaceID: "ti-v1", Path: "population-copy"})
+	assert.ErrorIs(t, err, ErrResourceDeprecated)
+	require.NoError(t, s.CreateApiInterface(&models.ApiInterface{ApiApplicationID: releaseAppID, ThematicInterfaceID: "ti-v2", Path: "population-v2"}))
+
+	deprecations, err := s.ListInterfaceDeprecations(ctx, models.DeprecationResourceThematicInterface)
+	require.NoError(t, err)
+	assert.Len(t, deprecations, 1)
+}
@@ -0,0 +1,193 @@
+/*
+ * @module service/sharing/geo_query
+ * @description 共享接口的空间范围查询，PostgREST不支持PostGIS空间函数，带bbox/near参数的请求直接查询主题接口表
+ * @architecture 分层架构 - 业务服务层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow 解析bbox/near/geo_field/select/limit/offset -> 识别空间列 -> 行级安全条件与空间条件合并 -> 分页查询 -> 空间列以GeoJSON返回
+ * @rules 空间查询只支持geoQueryParams中的参数，不能与PostgREST的其他筛选、排序参数组合；行级安全策略同样生效；
+ *        每页默认defaultGeoQueryLimit条，最多maxGeoQueryLimit条，按第一列排序；查询走只读副本
+ * @dependencies datahub-service/service/database, datahub-service/service/models, gorm.io/gorm
+ * @refs service/models/geo.go, service/database/geo.go, api/controllers/data_proxy_controller.go
+ */
+
+package sharing
+
+import (
+	"datahub-service/service/database"
+	"datahub-service/service/models"
+	"datahub-service/service/utils"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	defaultGeoQueryLimit = 1000  // 每页默认条数
+	maxGeoQueryLimit     = 10000 // 每页最多条数
+)
+
+// ErrInvalidGeoQuery 空间查询参数不合法
+var ErrInvalidGeoQuery = errors.New("空间查询参数不合法")
+
+// geoQueryParams 空间查询支持的参数
+var geoQueryParams = map[string]bool{
+	"bbox": true, "near": true, "geo_field": true, "select": true, "limit": true, "offset": true,
+}
+
+// GeoQuery 共享接口的空间范围查询
+type GeoQuery struct {
+	Filter  models.GeoFilter
+	Columns []string // 返回的列，为空时返回全部列
+	Limit   int
+	Offset  int
+}
+
+// GeoQueryResult 空间范围查询结果
+type GeoQueryResult struct {
+	Rows  []map[string]interface{}
+	Total int64
+}
+
+// IsGeoQuery 请求是否带有空间范围筛选参数
+func IsGeoQuery(values url.Values) bool {
+	return values.Get("bbox") != "" || values.Get("near") != ""
+}
+
+// ParseGeoQuery 解析空间范围查询参数
+func ParseGeoQuery(values url.Values) (*GeoQuery, error) {
+	var unsupported []string
+	for key := range values {
+		if !geoQueryParams[key] {
+			unsupported = append(unsupported, key)
+		}
+	}
+	if len(unsupported) > 0 {
+		sort.Strings(unsupported)
+		return nil, fmt.Errorf("%w: 空间范围筛选不能与参数%s同时使用", ErrInvalidGeoQuery, strings.Join(unsupported, ","))
+	}
+
+	filter, err := models.ParseGeoFilter(values.Get("geo_field"), values.Get("bbox"), values.Get("near"))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidGeoQuery, err)
+	}
+	query := &GeoQuery{Filter: filter, Limit: defaultGeoQueryLimit}
+	if selectParam := strings.TrimSpace(values.Get("select")); selectParam != "" && selectParam != "*" {
+		for _, column := range strings.Split(selectParam, ",") {
+			column = strings.TrimSpace(column)
+			if !rowPolicyFieldPattern.MatchString(column) {
+				return nil, fmt.Errorf("%w: select只支持逗号分隔的列名", ErrInvalidGeoQuery)
+			}
+			query.Columns = append(query.Columns, column)
+		}
+	}
+	if limit := values.Get("limit"); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil || n <= 0 {
+			return nil, fmt.Errorf("%w: limit须为正整数", ErrInvalidGeoQuery)
+		}
+		query.Limit = n
+		if query.Limit > maxGeoQueryLimit {
+			query.Limit = maxGeoQueryLimit
+		}
+	}
+	if offset := values.Get("offset"); offset != "" {
+		n, err := strconv.Atoi(offset)
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("%w: offset须为非负整数", ErrInvalidGeoQuery)
+		}
+		query.Offset = n
+	}
+	return query, nil
+}
+
+// QueryGeoRows 按空间范围和行级安全条件查询主题接口表
+func (s *SharingService) QueryGeoRows(schemaName, tableName string, query *GeoQuery, rowFilter *RowFilter) (*GeoQueryResult, error) {
+	geoColumns, err := database.QueryGeoColumns(s.readDB, schemaName, tableName)
+	if err != nil {
+		return nil, err
+	}
+	if err := query.Filter.ResolveColumn(geoColumns); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidGeoQuery, err)
+	}
+	if len(query.Columns) > 0 {
+		tableColumns, err := database.QueryTableColumnNames(s.readDB, schemaName, tableName)
+		if err != nil {
+			return nil, err
+		}
+		known := make(map[string]bool, len(tableColumns))
+		for _, column := range tableColumns {
+			known[column] = true
+		}
+		for _, column := range query.Columns {
+			if !known[column] {
+				return nil, fmt.Errorf("%w: 接口没有字段%s", ErrInvalidGeoQuery, column)
+			}
+		}
+	}
+	selectList, err := database.GeoSelectList(s.readDB, schemaName, tableName, query.Columns, geoColumns)
+	if err != nil {
+		return nil, err
+	}
+
+	where, args := query.Filter.SQLFilter()
+	if rowFilter != nil {
+		if policySQL, policyArgs := rowFilter.SQLFilter(); policySQL != "" {
+			where = "(" + policySQL + ") AND " + where
+			args = append(policyArgs, args...)
+		}
+	}
+	table := utils.QuoteTable(schemaName, tableName)
+
+	result := &GeoQueryResult{Rows: []map[string]interface{}{}}
+	if err := s.readDB.Raw("SELECT COUNT(*) FROM "+table+" WHERE "+where, args...).Scan(&result.Total).Error; err != nil {
+		return nil, fmt.Errorf("空间范围查询失败: %w", err)
+	}
+	rows, err := s.readDB.Raw(fmt.Sprintf("SELECT %s FROM %s WHERE %s ORDER BY 1 LIMIT %d OFFSET %d",
+		selectList, table, where, query.Limit, query.Offset), args...).Rows()
+	if err != nil {
+		return nil, fmt.Errorf("空间范围查询失败: %w", err)
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, fmt.Errorf("获取列名失败: %w", err)
+	}
+	jsonColumns := make([]bool, len(columns))
+	if columnTypes, err := rows.ColumnTypes(); err == nil {
+		for i, columnType := range columnTypes {
+			switch strings.ToUpper(columnType.DatabaseTypeName()) {
+			case "JSON", "JSONB":
+				jsonColumns[i] = true
+			}
+		}
+	}
+	for rows.Next() {
+		values := make([]interface{}, len(columns))
+		pointers := make([]interface{}, len(columns))
+		for i := range values {
+			pointers[i] = &values[i]
+		}
+		if err := rows.Scan(pointers...); err != nil {
+			return nil, fmt.Errorf("读取查询结果失败: %w", err)
+		}
+		row := make(map[string]interface{}, len(columns))
+		for i, column := range columns {
+			if b, ok := values[i].([]byte); ok {
+				if jsonColumns[i] && json.Valid(b) {
+					row[column] = json.RawMessage(b)
+				} else {
+					row[column] = string(b)
+				}
+				continue
+			}
+			row[column] = values[i]
+		}
+		result.Rows = append(result.Rows, row)
+	}
+	return result, rows.Err()
+}
@@ -0,0 +1,252 @@
+/*
+ * @module service/sharing/interface_release
+ * @description 共享接口灰度发布服务，指定订阅方先访问新版本主题接口，观察期结束后全量切换，切换可回退
+ * @architecture 分层架构 - 业务服务层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow 创建灰度(canary) -> 调整灰度订阅方 -> 全量切换(promoted) / 回退(rolled_back)；已切换的发布仍可回退到旧版本
+ * @rules 同一接口同时只允许一个灰度发布；新版本必须属于应用所在主题库；灰度订阅方必须是可访问该应用的ApiKey
+ * @dependencies datahub-service/service/models, gorm.io/gorm
+ * @refs api/controllers/data_proxy_controller.go, api/controllers/interface_release_controller.go
+ */
+
+package sharing
+
+import (
+	"datahub-service/service/models"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrReleaseInProgress 接口已存在进行中的灰度发布
+	ErrReleaseInProgress = errors.New("该接口已存在进行中的灰度发布")
+	// ErrReleaseStateInvalid 发布当前状态不允许该操作
+	ErrReleaseStateInvalid = errors.New("灰度发布当前状态不允许该操作")
+)
+
+// CreateApiInterfaceRelease 创建灰度发布，灰度订阅方立即访问新版本
+func (s *SharingService) CreateApiInterfaceRelease(release *models.ApiInterfaceRelease) error {
+	var apiInterface models.ApiInterface
+	if err := s.db.Preload("ApiApplication").First(&apiInterface, "id = ?", release.ApiInterfaceID).Error; err != nil {
+		return err
+	}
+
+	var target models.ThematicInterface
+	if err := s.db.First(&target, "id = ?", release.TargetThematicInterfaceID).Error; err != nil {
+		return errors.New("新版本主题接口不存在")
+	}
+	if target.ID == apiInterface.ThematicInterfaceID {
+		return errors.New("新版本主题接口与当前版本相同")
+	}
+	if target.LibraryID != apiInterface.ApiApplication.ThematicLibraryID {
+		return errors.New("新版本主题接口必须属于应用所在的主题库")
+	}
+	if target.PublishStatus != models.ThematicPublishStatusPublished {
+		return ErrThematicInterfaceNotPublished
+	}
+
+	if err := s.validateCanaryApiKeys(apiInterface.ApiApplicationID, release.CanaryApiKeyIDs); err != nil {
+		return err
+	}
+
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		var count int64
+		if err := tx.Model(&models.ApiInterfaceRelease{}).
+			Where("api_interface_id = ? AND status = ?", apiInterface.ID, models.ApiInterfaceReleaseStatusCanary).
+			Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			return ErrReleaseInProgress
+		}
+
+		release.BaseThematicInterfaceID = apiInterface.ThematicInterfaceID
+		release.CanaryApiKeyIDs = uniqueStrings(release.CanaryApiKeyIDs)
+		release.Status = models.ApiInterfaceReleaseStatusCanary
+		release.UpdatedBy = release.CreatedBy
+		return tx.Create(release).Error
+	})
+}
+
+// GetApiInterfaceReleases 获取接口的灰度发布记录，按创建时间倒序
+func (s *SharingService) GetApiInterfaceReleases(interfaceID string) ([]models.ApiInterfaceRelease, error) {
+	var releases []models.ApiInterfaceRelease
+	err := s.db.Preload("TargetThematicInterface").
+		Where("api_interface_id = ?", interfaceID).
+		Order("created_at DESC").
+		Find(&releases).Error
+	return releases, err
+}
+
+// GetApiInterfaceRelease 获取接口下的灰度发布
+func (s *SharingService) GetApiInterfaceRelease(interfaceID, releaseID string) (*models.ApiInterfaceRelease, error) {
+	var release models.ApiInterfaceRelease
+	if err := s.db.First(&release, "id = ? AND api_interface_id = ?", releaseID, interfaceID).Error; err != nil {
+		return nil, err
+	}
+	return &release, nil
+}
+
+// UpdateApiInterfaceReleaseSubscribers 调整灰度订阅方，仅灰度中的发布可调整
+func (s *SharingService) UpdateApiInterfaceReleaseSubscribers(interfaceID, releaseID string, apiKeyIDs []string, observeUntil *time.Time, operator string) error {
+	release, err := s.GetApiInterfaceRelease(interfaceID, releaseID)
+	if err != nil {
+		return err
+	}
+	if release.Status != models.ApiInterfaceReleaseStatusCanary {
+		return ErrReleaseStateInvalid
+	}
+
+	var apiInterface models.ApiInterface
+	if err := s.db.First(&apiInterface, "id = ?", interfaceID).Error; err != nil {
+		return err
+	}
+	if err := s.validateCanaryApiKeys(apiInterface.ApiApplicationID, apiKeyIDs); err != nil {
+		return err
+	}
+
+	return s.db.Model(release).Updates(map[string]interface{}{
+		"canary_api_key_ids": models.JSONBStringArray(uniqueStrings(apiKeyIDs)),
+		"observe_until":      observeUntil,
+		"updated_by":         operator,
+	}).Error
+}
+
+// PromoteApiInterfaceRelease 全量切换，所有订阅方访问新版本
+func (s *SharingService) PromoteApiInterfaceRelease(interfaceID, releaseID, operator string) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		var release models.ApiInterfaceRelease
+		if err := tx.First(&release, "id = ? AND api_interface_id = ?", releaseID, interfaceID).Error; err != nil {
+			return err
+		}
+		if release.Status != models.ApiInterfaceReleaseStatusCanary {
+			return ErrReleaseStateInvalid
+		}
+
+		// 仅当接口仍指向发布前的版本时切换，防止覆盖期间的其他修改
+		result := tx.Model(&models.ApiInterface{}).
+			Where("id = ? AND thematic_interface_id = ?", interfaceID, release.BaseThematicInterfaceID).
+			Updates(map[string]interface{}{
+				"thematic_interface_id": release.TargetThematicInterfaceID,
+				"row_version":           gorm.Expr("row_version + 1"),
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return fmt.Errorf("%w: 接口当前版本已变更", ErrReleaseStateInvalid)
+		}
+
+		now := time.Now()
+		return tx.Model(&release).Updates(map[string]interface{}{
+			"status":      models.ApiInterfaceReleaseStatusPromoted,
+			"promoted_at": &now,
+			"promoted_by": operator,
+			"updated_by":  operator,
+		}).Error
+	})
+}
+
+// RollbackApiInterfaceRelease 回退发布。灰度中的发布直接结束；已全量切换的发布将接口恢复到旧版本
+func (s *SharingService) RollbackApiInterfaceRelease(interfaceID, releaseID, operator, reason string) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		var release models.ApiInterfaceRelease
+		if err := tx.First(&release, "id = ? AND api_interface_id = ?", releaseID, interfaceID).Error; err != nil {
+			return err
+		}
+
+		switch release.Status {
+		case models.ApiInterfaceReleaseStatusCanary:
+		case models.ApiInterfaceReleaseStatusPromoted:
+			// 只能回退接口当前生效的发布
+			result := tx.Model(&models.ApiInterface{}).
+				Where("id = ? AND thematic_interface_id = ?", interfaceID, release.TargetThematicInterfaceID).
+				Updates(map[string]interface{}{
+					"thematic_interface_id": release.BaseThematicInterfaceID,
+					"row_version":           gorm.Expr("row_version + 1"),
+				})
+			if result.Error != nil {
+				return result.Error
+			}
+			if result.RowsAffected == 0 {
+				return fmt.Errorf("%w: 接口当前版本已不是该发布的新版本", ErrReleaseStateInvalid)
+			}
+		default:
+			return ErrReleaseStateInvalid
+		}
+
+		now := time.Now()
+		return tx.Model(&release).Updates(map[string]interface{}{
+			"status":          models.ApiInterfaceReleaseStatusRolledBack,
+			"rolled_back_at":  &now,
+			"rolled_back_by":  operator,
+			"rollback_reason": reason,
+			"updated_by":      operator,
+		}).Error
+	})
+}
+
+// ResolveReleaseInterface 解析ApiKey访问接口时生效的主题接口，灰度订阅方返回新版本及对应的发布
+func (s *SharingService) ResolveReleaseInterface(apiInterface *models.ApiInterface, apiKeyID string) (*models.ThematicInterface, *models.ApiInterfaceRelease, error) {
+	var release models.ApiInterfaceRelease
+	err := s.db.Preload("TargetThematicInterface").
+		Where("api_interface_id = ? AND status = ?", apiInterface.ID, models.ApiInterfaceReleaseStatusCanary).
+		First(&release).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return &apiInterface.ThematicInterface, nil, nil
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for _, id := range release.CanaryApiKeyIDs {
+		if id == apiKeyID {
+			return &release.TargetThematicInterface, &release, nil
+		}
+	}
+	return &apiInterface.ThematicInterface, nil, nil
+}
+
+// hasActiveApiInterfaceRelease 接口是否存在灰度中的发布
+func (s *SharingService) hasActiveApiInterfaceRelease(interfaceID string) (bool, error) {
+	var count int64
+	err := s.db.Model(&models.ApiInterfaceRelease{}).
+		Where("api_interface_id = ? AND status = ?", interfaceID, models.ApiInterfaceReleaseStatusCanary).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// validateCanaryApiKeys 校验灰度订阅方均可访问接口所属应用
+func (s *SharingService) validateCanaryApiKeys(appID string, apiKeyIDs []string) error {
+	if len(apiKeyIDs) == 0 {
+		return errors.New("至少需要指定一个灰度订阅方")
+	}
+
+	var count int64
+	if err := s.db.Model(&models.ApiKeyApplication{}).
+		Where("api_application_id = ? AND api_key_id IN ?", appID, apiKeyIDs).
+		Distinct("api_key_id").
+		Count(&count).Error; err != nil {
+		return err
+	}
+	if int(count) != len(uniqueStrings(apiKeyIDs)) {
+		return errors.New("灰度订阅方必须是可访问该应用的API密钥")
+	}
+	return nil
+}
+
+// uniqueStrings 去重并保持顺序
+func uniqueStrings(values []string) []string {
+	seen := make(map[string]bool, len(values))
+	result := make([]string, 0, len(values))
+	for _, v := range values {
+		if !seen[v] {
+			seen[v] = true
+			result = append(result, v)
+		}
+	}
+	return result
+}
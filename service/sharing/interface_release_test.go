@@ -0,0 +1,163 @@
+/*
+ * @module service/sharing/interface_release_test
+ * @description 共享接口灰度发布测试，覆盖灰度路由、全量切换和回退
+ * @architecture 测试层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow 初始化内存数据库 -> 创建灰度 -> 解析生效版本 -> 切换/回退 -> 验证接口版本
+ * @rules 使用SQLite内存数据库，不依赖外部服务
+ * @dependencies github.com/stretchr/testify, gorm.io/driver/sqlite
+ * @refs service/sharing/interface_release.go
+ */
+
+package sharing
+
+import (
+	"datahub-service/service/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+const (
+	releaseAppID       = "22222222-2222-2222-2222-222222222222"
+	releaseInterfaceID = "33333333-3333-3333-3333-333333333333"
+	releaseCanaryKeyID = "44444444-4444-4444-4444-444444444444"
+	releaseOtherKeyID  = "55555555-5555-5555-5555-555555555555"
+)
+
+func setupReleaseService(t *testing.T) *SharingService {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger:                                   logger.Default.LogMode(logger.Silent),
+		DisableForeignKeyConstraintWhenMigrating: true,
+	})
+	require.NoError(t, err)
+	require.NoError(t, db.SetupJoinTable(&models.ApiKey{}, "Applications", &models.ApiKeyApplication{}))
+	require.NoError(t, db.SetupJoinTable(&models.ApiApplication{}, "ApiKeys", &models.ApiKeyApplication{}))
+	require.NoError(t, db.AutoMigrate(&models.ThematicInterface{}, &models.ApiKey{}, &models.ApiInterface{}, &models.ApiInterfaceRelease{}))
+
+	require.NoError(t, db.Exec(`INSERT INTO api_applications (id, name, path, thematic_library_id, contact_person, contact_phone, status)
+		VALUES (?, 'app', 'app', 'lib', 'tester', '000', 'active')`, releaseAppID).Error)
+	for _, ti := range []models.ThematicInterface{
+		{ID: "ti-v1", LibraryID: "lib", NameZh: "人口v1", NameEn: "population", Type: "table"},
+		{ID: "ti-v2", LibraryID: "lib", NameZh: "人口v2", NameEn: "population_v2", Type: "table"},
+		{ID: "ti-other", LibraryID: "other-lib", NameZh: "其他", NameEn: "other", Type: "table"},
+	} {
+		require.NoError(t, db.Create(&ti).Error)
+	}
+	require.NoError(t, db.Create(&models.ApiInterface{
+		ID:                  releaseInterfaceID,
+		ApiApplicationID:    releaseAppID,
+		ThematicInterfaceID: "ti-v1",
+		Path:                "population",
+	}).Error)
+	for _, keyID := range []string{releaseCanaryKeyID, releaseOtherKeyID} {
+		require.NoError(t, db.Create(&models.ApiKey{ID: keyID, Name: keyID, KeyPrefix: "k", KeyValueHash: keyID}).Error)
+		require.NoError(t, db.Create(&models.ApiKeyApplication{ApiKeyID: keyID, ApiApplicationID: releaseAppID}).Error)
+	}
+
+	return NewSharingService(db)
+}
+
+func loadReleaseInterface(t *testing.T, s *SharingService) *models.ApiInterface {
+	var apiInterface models.ApiInterface
+	require.NoError(t, s.db.Preload("ThematicInterface").First(&apiInterface, "id = ?", releaseInterfaceID).Error)
+	return &apiInterface
+}
+
+func TestCreateApiInterfaceReleaseValidation(t *testing.T) {
+	s := setupReleaseService(t)
+
+	err := s.CreateApiInterfaceRelease(&models.ApiInterfaceRelease{
+		ApiInterfaceID: releaseInterfaceID, TargetThematicInterfaceID: "ti-other", CanaryApiKeyIDs: []string{releaseCanaryKeyID},
+	})
+	assert.Error(t, err, "新版本必须属于应用所在主题库")
+
+	err = s.CreateApiInterfaceRelease(&models.ApiInterfaceRelease{
+		ApiInterfaceID: releaseInterfaceID, TargetThematicInterfaceID: "ti-v2", CanaryApiKeyIDs: []string{"unknown-key"},
+	})
+	assert.Error(t, err, "灰度订阅方必须可访问应用")
+
+	require.NoError(t, s.CreateApiInterfaceRelease(&models.ApiInterfaceRelease{
+		ApiInterfaceID: releaseInterfaceID, TargetThematicInterfaceID: "ti-v2", CanaryApiKeyIDs: []string{releaseCanaryKeyID},
+	}))
+	err = s.CreateApiInterfaceRelease(&models.ApiInterfaceRelease{
+		ApiInterfaceID: releaseInterfaceID, TargetThematicInterfaceID: "ti-v2", CanaryApiKeyIDs: []string{releaseOtherKeyID},
+	})
+	assert.ErrorIs(t, err, ErrReleaseInProgress)
+}
+
+func TestResolveReleaseInterface(t *testing.T) {
+	s := setupReleaseService(t)
+	release := &models.ApiInterfaceRelease{
+		ApiInterfaceID: releaseInterfaceID, TargetThematicInterfaceID: "ti-v2", CanaryApiKeyIDs: []string{releaseCanaryKeyID},
+	}
+	require.NoError(t, s.CreateApiInterfaceRelease(release))
+	assert.Equal(t, "ti-v1", release.BaseThematicInterfaceID)
+
+	apiInterface := loadReleaseInterface(t, s)
+	ti, matched, err := s.ResolveReleaseInterface(apiInterface, releaseCanaryKeyID)
+	require.NoError(t, err)
+	assert.Equal(t, "population_v2", ti.NameEn)
+	require.NotNil(t, matched)
+	assert.Equal(t, release.ID, matched.ID)
+
+	ti, matched, err = s.ResolveReleaseInterface(apiInterface, releaseOtherKeyID)
+	require.NoError(t, err)
+	assert.Equal(t, "population", ti.NameEn)
+	assert.Nil(t, matched)
+
+	// 灰度期间不允许直接修改接口的主题接口
+	err = s.UpdateApiInterface(releaseInterfaceID, 0, map[string]interface{}{"thematic_interface_id": "ti-v2"})
+	assert.ErrorIs(t, err, ErrReleaseInProgress)
+}
+
+func TestPromoteAndRollbackApiInterfaceRelease(t *testing.T) {
+	s := setupReleaseService(t)
+	release := &models.ApiInterfaceRelease{
+		ApiInterfaceID: releaseInterfaceID, TargetThematicInterfaceID: "ti-v2", CanaryApiKeyIDs: []string{releaseCanaryKeyID},
+	}
+	require.NoError(t, s.CreateApiInterfaceRelease(release))
+
+	require.NoError(t, s.PromoteApiInterfaceRelease(releaseInterfaceID, release.ID, "admin"))
+	apiInterface := loadReleaseInterface(t, s)
+	assert.Equal(t, "ti-v2", apiInterface.ThematicInterfaceID)
+
+	ti, matched, err := s.ResolveReleaseInterface(apiInterface, releaseOtherKeyID)
+	require.NoError(t, err)
+	assert.Equal(t, "population_v2", ti.NameEn)
+	assert.Nil(t, matched)
+
+	assert.ErrorIs(t, s.PromoteApiInterfaceRelease(releaseInterfaceID, release.ID, "admin"), ErrReleaseStateInvalid)
+
+	// 全量切换后回退到旧版本
+	require.NoError(t, s.RollbackApiInterfaceRelease(releaseInterfaceID, release.ID, "admin", "数据异常"))
+	apiInterface = loadReleaseInterface(t, s)
+	assert.Equal(t, "ti-v1", apiInterface.ThematicInterfaceID)
+
+	stored, err := s.GetApiInterfaceRelease(releaseInterfaceID, release.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.ApiInterfaceReleaseStatusRolledBack, stored.Status)
+	assert.Equal(t, "数据异常", stored.RollbackReason)
+
+	assert.ErrorIs(t, s.RollbackApiInterfaceRelease(releaseInterfaceID, release.ID, "admin", ""), ErrReleaseStateInvalid)
+}
+
+func TestRollbackCanaryRelease(t *testing.T) {
+	s := setupReleaseService(t)
+	release := &models.ApiInterfaceRelease{
+		ApiInterfaceID: releaseInterfaceID, TargetThematicInterfaceID: "ti-v2", CanaryApiKeyIDs: []string{releaseCanaryKeyID},
+	}
+	require.NoError(t, s.CreateApiInterfaceRelease(release))
+	require.NoError(t, s.RollbackApiInterfaceRelease(releaseInterfaceID, release.ID, "admin", ""))
+
+	apiInterface := loadReleaseInterface(t, s)
+	assert.Equal(t, "ti-v1", apiInterface.ThematicInterfaceID)
+	ti, matched, err := s.ResolveReleaseInterface(apiInterface, releaseCanaryKeyID)
+	require.NoError(t, err)
+	assert.Equal(t, "population", ti.NameEn)
+	assert.Nil(t, matched)
+}
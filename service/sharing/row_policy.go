@@ -0,0 +1,393 @@
+/*
+ * @module service/sharing/row_policy
+ * @description 行级安全策略服务，管理按消费方/角色定义的行过滤策略，并生成PostgREST和SQL过滤条件
+ * @architecture 分层架构 - 业务服务层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow 策略配置 -> 按表和主体匹配策略 -> 生成过滤条件 -> 数据共享/数据查看接口自动应用
+ * @rules 同一表存在启用策略时，未匹配任何策略的主体无权访问；多条匹配策略之间为OR关系
+ * @dependencies datahub-service/service/models, gorm.io/gorm
+ * @refs api/controllers/data_proxy_controller.go, api/controllers/data_view_controller.go
+ */
+
+package sharing
+
+import (
+	"datahub-service/service/models"
+	"datahub-service/service/utils"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// 行级策略主体类型
+const (
+	RowPolicySubjectApiKey      = "api_key"
+	RowPolicySubjectApplication = "application"
+	RowPolicySubjectRole        = "role"
+	RowPolicySubjectUser        = "user"
+)
+
+// ErrRowAccessDenied 表配置了行级策略但当前主体未匹配任何策略
+var ErrRowAccessDenied = errors.New("当前访问主体未被授予该表的行级访问策略")
+
+// rowPolicyFieldPattern 字段名校验规则，防止SQL注入
+var rowPolicyFieldPattern = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+// rowPolicyVariablePattern 条件值中的上下文变量，如 ${username}
+var rowPolicyVariablePattern = regexp.MustCompile(`\$\{([a-z_]+)\}`)
+
+// RowPolicyCondition 行过滤条件
+type RowPolicyCondition struct {
+	Field    string      `json:"field"`
+	Operator string      `json:"operator"` // eq/neq/gt/gte/lt/lte/in/not_in/like/is_null/not_null
+	Value    interface{} `json:"value"`    // 支持上下文变量 ${username}、${api_key_id}、${application_id}
+}
+
+// RowPolicySubject 访问主体，描述当前请求对应的密钥、应用、用户和角色
+type RowPolicySubject struct {
+	ApiKeyID      string
+	ApplicationID string
+	Username      string
+	Roles         []string
+}
+
+// RowFilter 匹配策略后生成的过滤条件
+type RowFilter struct {
+	Policies []models.RowLevelPolicy
+	groups   [][]RowPolicyCondition
+	logics   []string
+	subject  RowPolicySubject
+}
+
+// IsEmpty 是否无需过滤
+func (f *RowFilter) IsEmpty() bool {
+	return f == nil || len(f.groups) == 0
+}
+
+// === 策略管理 ===
+
+// CreateRowLevelPolicy 创建行级安全策略
+func (s *SharingService) CreateRowLevelPolicy(policy *models.RowLevelPolicy) error {
+	if err := ValidateRowLevelPolicy(policy); err != nil {
+		return err
+	}
+	return s.db.Create(policy).Error
+}
+
+// GetRowLevelPolicies 分页获取行级安全策略
+func (s *SharingService) GetRowLevelPolicies(page, pageSize int, schemaName, tableName, subjectType, subjectID string) ([]models.RowLevelPolicy, int64, error) {
+	var policies []models.RowLevelPolicy
+	var total int64
+
+	query := s.db.Model(&models.RowLevelPolicy{})
+	if schemaName != "" {
+		query = query.Where("schema_name = ?", schemaName)
+	}
+	if tableName != "" {
+		query = query.Where("table_name = ?", tableName)
+	}
+	if subjectType != "" {
+		query = query.Where("subject_type = ?", subjectType)
+	}
+	if subjectID != "" {
+		query = query.Where("subject_id = ?", subjectID)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * pageSize
+	err := query.Order("created_at DESC").Offset(offset).Limit(pageSize).Find(&policies).Error
+	return policies, total, err
+}
+
+// GetRowLevelPolicyByID 根据ID获取行级安全策略
+func (s *SharingService) GetRowLevelPolicyByID(id string) (*models.RowLevelPolicy, error) {
+	var policy models.RowLevelPolicy
+	if err := s.db.First(&policy, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &policy, nil
+}
+
+// UpdateRowLevelPolicy 更新行级安全策略
+func (s *SharingService) UpdateRowLevelPolicy(id string, policy *models.RowLevelPolicy) error {
+	existing, err := s.GetRowLevelPolicyByID(id)
+	if err != nil {
+		return err
+	}
+
+	if err := ValidateRowLevelPolicy(policy); err != nil {
+		return err
+	}
+
+	return s.db.Model(existing).Updates(map[string]interface{}{
+		"name":            policy.Name,
+		"description":     policy.Description,
+		"schema_name":     policy.SchemaName,
+		"table_name":      policy.TableName,
+		"subject_type":    policy.SubjectType,
+		"subject_id":      policy.SubjectID,
+		"conditions":      policy.Conditions,
+		"condition_logic": policy.ConditionLogic,
+		"is_enabled":      policy.IsEnabled,
+		"updated_by":      policy.UpdatedBy,
+	}).Error
+}
+
+// DeleteRowLevelPolicy 删除行级安全策略
+func (s *SharingService) DeleteRowLevelPolicy(id string) error {
+	result := s.db.Delete(&models.RowLevelPolicy{}, "id = ?", id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("行级安全策略不存在")
+	}
+	return nil
+}
+
+// ResolveRowFilter 解析指定表对当前主体生效的行过滤条件
+// 表未配置启用的策略时返回空过滤；配置了策略但主体未匹配时返回 ErrRowAccessDenied
+func (s *SharingService) ResolveRowFilter(schemaName, tableName string, subject RowPolicySubject) (*RowFilter, error) {
+	var policies []models.RowLevelPolicy
+	err := s.db.Where("schema_name = ? AND table_name = ? AND is_enabled = ?", schemaName, tableName, true).
+		Order("created_at ASC").Find(&policies).Error
+	if err != nil {
+		return nil, fmt.Errorf("查询行级安全策略失败: %w", err)
+	}
+	if len(policies) == 0 {
+		return &RowFilter{}, nil
+	}
+
+	filter := &RowFilter{subject: subject}
+	for _, policy := range policies {
+		if !subject.matches(policy) {
+			continue
+		}
+		conditions, err := ParseRowPolicyConditions(policy.Conditions)
+		if err != nil {
+			return nil, fmt.Errorf("策略 %s 条件解析失败: %w", policy.Name, err)
+		}
+		filter.Policies = append(filter.Policies, policy)
+		filter.groups = append(filter.groups, conditions)
+		filter.logics = append(filter.logics, policy.ConditionLogic)
+	}
+
+	if len(filter.Policies) == 0 {
+		return nil, ErrRowAccessDenied
+	}
+	return filter, nil
+}
+
+// matches 判断策略是否适用于当前主体
+func (sub RowPolicySubject) matches(policy models.RowLevelPolicy) bool {
+	switch policy.SubjectType {
+	case RowPolicySubjectApiKey:
+		return sub.ApiKeyID != "" && sub.ApiKeyID == policy.SubjectID
+	case RowPolicySubjectApplication:
+		return sub.ApplicationID != "" && sub.ApplicationID == policy.SubjectID
+	case RowPolicySubjectUser:
+		return sub.Username != "" && sub.Username == policy.SubjectID
+	case RowPolicySubjectRole:
+		for _, role := range sub.Roles {
+			if role == policy.SubjectID {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ValidateRowLevelPolicy 校验策略配置
+func ValidateRowLevelPolicy(policy *models.RowLevelPolicy) error {
+	if policy.Name == "" {
+		return errors.New("策略名称不能为空")
+	}
+	if policy.SchemaName == "" || policy.TableName == "" {
+		return errors.New("库名和表名不能为空")
+	}
+	switch policy.SubjectType {
+	case RowPolicySubjectApiKey, RowPolicySubjectApplication, RowPolicySubjectRole, RowPolicySubjectUser:
+	default:
+		return fmt.Errorf("不支持的主体类型: %s", policy.SubjectType)
+	}
+	if policy.SubjectID == "" {
+		return errors.New("主体标识不能为空")
+	}
+	if policy.ConditionLogic == "" {
+		policy.ConditionLogic = "and"
+	}
+	if policy.ConditionLogic != "and" && policy.ConditionLogic != "or" {
+		return fmt.Errorf("不支持的条件逻辑: %s", policy.ConditionLogic)
+	}
+
+	conditions, err := ParseRowPolicyConditions(policy.Conditions)
+	if err != nil {
+		return err
+	}
+	if len(conditions) == 0 {
+		return errors.New("至少需要一个过滤条件")
+	}
+	return nil
+}
+
+// ParseRowPolicyConditions 将JSONB条件解析为结构化条件并校验
+func ParseRowPolicyConditions(raw models.JSONBArray) ([]RowPolicyCondition, error) {
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return nil, err
+	}
+	var conditions []RowPolicyCondition
+	if err := json.Unmarshal(data, &conditions); err != nil {
+		return nil, fmt.Errorf("过滤条件格式错误: %w", err)
+	}
+
+	for _, cond := range conditions {
+		if !rowPolicyFieldPattern.MatchString(cond.Field) {
+			return nil, fmt.Errorf("无效的字段名: %s", cond.Field)
+		}
+		switch cond.Operator {
+		case "eq", "neq", "gt", "gte", "lt", "lte", "like":
+			if cond.Value == nil {
+				return nil, fmt.Errorf("字段 %s 的条件值不能为空", cond.Field)
+			}
+		case "in", "not_in":
+			if _, ok := cond.Value.([]interface{}); !ok {
+				return nil, fmt.Errorf("字段 %s 的 %s 条件值必须为数组", cond.Field, cond.Operator)
+			}
+		case "is_null", "not_null":
+		default:
+			return nil, fmt.Errorf("不支持的操作符: %s", cond.Operator)
+		}
+	}
+	return conditions, nil
+}
+
+// resolveValue 替换条件值中的上下文变量
+func (f *RowFilter) resolveValue(value interface{}) interface{} {
+	str, ok := value.(string)
+	if !ok {
+		return value
+	}
+	return rowPolicyVariablePattern.ReplaceAllStringFunc(str, func(m string) string {
+		switch rowPolicyVariablePattern.FindStringSubmatch(m)[1] {
+		case "username":
+			return f.subject.Username
+		case "api_key_id":
+			return f.subject.ApiKeyID
+		case "application_id":
+			return f.subject.ApplicationID
+		}
+		return m
+	})
+}
+
+// === PostgREST过滤 ===
+
+var postgrestOperators = map[string]string{
+	"eq": "eq", "neq": "neq", "gt": "gt", "gte": "gte", "lt": "lt", "lte": "lte", "like": "like",
+}
+
+// PostgRESTFilter 生成PostgREST逻辑过滤表达式，作为 and 查询参数的值，如 (or(and(a.eq.1),and(b.eq.2)))
+// PostgREST对顶层查询参数取AND，因此调用方追加的其他过滤无法放宽该条件
+func (f *RowFilter) PostgRESTFilter() string {
+	if f.IsEmpty() {
+		return ""
+	}
+
+	groups := make([]string, 0, len(f.groups))
+	for i, conditions := range f.groups {
+		parts := make([]string, 0, len(conditions))
+		for _, cond := range conditions {
+			parts = append(parts, f.postgrestCondition(cond))
+		}
+		groups = append(groups, fmt.Sprintf("%s(%s)", f.logics[i], strings.Join(parts, ",")))
+	}
+	return fmt.Sprintf("(or(%s))", strings.Join(groups, ","))
+}
+
+func (f *RowFilter) postgrestCondition(cond RowPolicyCondition) string {
+	switch cond.Operator {
+	case "is_null":
+		return cond.Field + ".is.null"
+	case "not_null":
+		return cond.Field + ".not.is.null"
+	case "in", "not_in":
+		values := cond.Value.([]interface{})
+		items := make([]string, 0, len(values))
+		for _, v := range values {
+			items = append(items, quotePostgRESTValue(f.resolveValue(v)))
+		}
+		op := "in"
+		if cond.Operator == "not_in" {
+			op = "not.in"
+		}
+		return fmt.Sprintf("%s.%s.(%s)", cond.Field, op, strings.Join(items, ","))
+	case "like":
+		pattern := fmt.Sprint(f.resolveValue(cond.Value))
+		return fmt.Sprintf("%s.like.%s", cond.Field, quotePostgRESTValue(strings.ReplaceAll(pattern, "%", "*")))
+	default:
+		return fmt.Sprintf("%s.%s.%s", cond.Field, postgrestOperators[cond.Operator], quotePostgRESTValue(f.resolveValue(cond.Value)))
+	}
+}
+
+// quotePostgRESTValue 使用双引号包裹值，避免逗号、括号等保留字符破坏表达式
+func quotePostgRESTValue(value interface{}) string {
+	str := fmt.Sprint(value)
+	str = strings.ReplaceAll(str, `\`, `\\`)
+	str = strings.ReplaceAll(str, `"`, `\"`)
+	return `"` + str + `"`
+}
+
+// === SQL过滤 ===
+
+var sqlOperators = map[string]string{
+	"eq": "=", "neq": "<>", "gt": ">", "gte": ">=", "lt": "<", "lte": "<=", "like": "LIKE",
+}
+
+// SQLFilter 生成参数化的SQL过滤条件（不包含WHERE关键字）及参数
+func (f *RowFilter) SQLFilter() (string, []interface{}) {
+	if f.IsEmpty() {
+		return "", nil
+	}
+
+	var args []interface{}
+	groups := make([]string, 0, len(f.groups))
+	for i, conditions := range f.groups {
+		parts := make([]string, 0, len(conditions))
+		for _, cond := range conditions {
+			column := utils.QuoteIdent(cond.Field)
+			switch cond.Operator {
+			case "is_null":
+				parts = append(parts, column+" IS NULL")
+			case "not_null":
+				parts = append(parts, column+" IS NOT NULL")
+			case "in", "not_in":
+				values := cond.Value.([]interface{})
+				resolved := make([]interface{}, 0, len(values))
+				for _, v := range values {
+					resolved = append(resolved, f.resolveValue(v))
+				}
+				op := "IN"
+				if cond.Operator == "not_in" {
+					op = "NOT IN"
+				}
+				parts = append(parts, fmt.Sprintf("%s %s ?", column, op))
+				args = append(args, resolved)
+			default:
+				parts = append(parts, fmt.Sprintf("%s %s ?", column, sqlOperators[cond.Operator]))
+				args = append(args, f.resolveValue(cond.Value))
+			}
+		}
+		joiner := " AND "
+		if f.logics[i] == "or" {
+			joiner = " OR "
+		}
+		groups = append(groups, "("+strings.Join(parts, joiner)+")")
+	}
+	return "(" + strings.Join(groups, " OR ") + ")", args
+}
@@ -12,6 +12,7 @@
 package sharing
 
 import (
+	"context"
 	"crypto/rand"
 	"datahub-service/service/database"
 	"datahub-service/service/models"
@@ -24,27 +25,40 @@ import (
 	"gorm.io/gorm"
 )
 
+// ErrThematicInterfaceNotPublished 主题接口未通过发布评审，不能被数据共享引用
+var ErrThematicInterfaceNotPublished = errors.New("主题接口未发布，不能用于数据共享")
+
 // SharingService 数据共享服务
 type SharingService struct {
-	db *gorm.DB
+	db     *gorm.DB
+	readDB *gorm.DB // 空间范围查询导出数据使用的只读连接
 }
 
 // NewSharingService 创建数据共享服务实例
 func NewSharingService(db *gorm.DB) *SharingService {
-	return &SharingService{db: db}
+	return &SharingService{db: db, readDB: db}
+}
+
+// SetReadDB 设置只读副本连接，为空时回退到主库连接
+func (s *SharingService) SetReadDB(readDB *gorm.DB) {
+	if readDB == nil {
+		readDB = s.db
+	}
+	s.readDB = readDB
 }
 
 // === API应用管理 ===
 
 // CreateApiApplication 创建API应用
-func (s *SharingService) CreateApiApplication(app *models.ApiApplication) error {
-	// 验证主题库是否存在
+func (s *SharingService) CreateApiApplication(ctx context.Context, app *models.ApiApplication) error {
+	// 验证主题库是否存在，应用与主题库归属同一租户
 	var thematicLibrary models.ThematicLibrary
-	if err := s.db.First(&thematicLibrary, "id = ?", app.ThematicLibraryID).Error; err != nil {
+	if err := s.db.WithContext(ctx).First(&thematicLibrary, "id = ?", app.ThematicLibraryID).Error; err != nil {
 		return errors.New("主题库不存在")
 	}
+	app.TenantID = thematicLibrary.TenantID
 
-	// 验证应用路径唯一性
+	// 验证应用路径唯一性（访问路径全局唯一，不区分租户）
 	var count int64
 	if err := s.db.Model(&models.ApiApplication{}).Where("path = ?", app.Path).Count(&count).Error; err != nil {
 		return err
@@ -53,15 +67,15 @@ func (s *SharingService) CreateApiApplication(app *models.ApiApplication) error
 		return errors.New("应用路径已存在")
 	}
 
-	return s.db.Create(app).Error
+	return s.db.WithContext(ctx).Create(app).Error
 }
 
 // GetApiApplications 获取API应用列表
-func (s *SharingService) GetApiApplications(page, pageSize int, status string) ([]models.ApiApplication, int64, error) {
+func (s *SharingService) GetApiApplications(ctx context.Context, page, pageSize int, status string) ([]models.ApiApplication, int64, error) {
 	var apps []models.ApiApplication
 	var total int64
 
-	query := s.db.Model(&models.ApiApplication{})
+	query := s.db.WithContext(ctx).Model(&models.ApiApplication{})
 
 	if status != "" {
 		query = query.Where("status = ?", status)
@@ -80,9 +94,9 @@ func (s *SharingService) GetApiApplications(page, pageSize int, status string) (
 }
 
 // GetApiApplicationByID 根据ID获取API应用
-func (s *SharingService) GetApiApplicationByID(id string) (*models.ApiApplication, error) {
+func (s *SharingService) GetApiApplicationByID(ctx context.Context, id string) (*models.ApiApplication, error) {
 	var app models.ApiApplication
-	if err := s.db.Preload("ThematicLibrary").Preload("ApiInterfaces").Preload("ApiInterfaces.ThematicInterface").First(&app, "id = ?", id).Error; err != nil {
+	if err := s.db.WithContext(ctx).Preload("ThematicLibrary").Preload("ApiInterfaces").Preload("ApiInterfaces.ThematicInterface").First(&app, "id = ?", id).Error; err != nil {
 		return nil, err
 	}
 	return &app, nil
@@ -150,15 +164,16 @@ func (s *SharingService) GetApiApplicationByApiKeyAndPath(apiKeyID, appPath stri
 }
 
 // UpdateApiApplication 更新API应用
-func (s *SharingService) UpdateApiApplication(id string, updates map[string]interface{}) error {
-	return s.db.Model(&models.ApiApplication{}).Where("id = ?", id).Updates(updates).Error
+func (s *SharingService) UpdateApiApplication(ctx context.Context, id string, updates map[string]interface{}) error {
+	delete(updates, "tenant_id")
+	return s.db.WithContext(ctx).Model(&models.ApiApplication{}).Where("id = ?", id).Updates(updates).Error
 }
 
 // DeleteApiApplication 删除API应用（级联删除关联数据）
-func (s *SharingService) DeleteApiApplication(id string) error {
+func (s *SharingService) DeleteApiApplication(ctx context.Context, id string) error {
 	// 检查应用是否存在
 	var existing models.ApiApplication
-	if err := s.db.First(&existing, "id = ?", id).Error; err != nil {
+	if err := s.db.WithContext(ctx).First(&existing, "id = ?", id).Error; err != nil {
 		return errors.New("API应用不存在")
 	}
 
@@ -195,8 +210,8 @@ func (s *SharingService) DeleteApiApplication(id string) error {
 
 // === ApiKey管理 ===
 
-// CreateApiKey 创建一个新的ApiKey并关联到指定的应用
-func (s *SharingService) CreateApiKey(name, description string, appIDs []string, expiresAt *time.Time) (*models.ApiKey, string, error) {
+// CreateApiKey 创建一个新的ApiKey并关联到指定的应用，未指定授权范围时默认为share:read
+func (s *SharingService) CreateApiKey(name, description string, appIDs []string, scopes []string, expiresAt *time.Time) (*models.ApiKey, string, error) {
 	// 验证应用是否存在
 	if len(appIDs) == 0 {
 		return nil, "", errors.New("至少需要关联一个应用")
@@ -211,40 +226,59 @@ func (s *SharingService) CreateApiKey(name, description string, appIDs []string,
 		return nil, "", errors.New("部分应用不存在")
 	}
 
-	// 生成API Key
-	fullKey, err := generateRandomString(64) // 生成32字节的随机字符串，转为64字符的hex
+	normalizedScopes, err := NormalizeApiKeyScopes(scopes)
 	if err != nil {
 		return nil, "", err
 	}
 
-	// 生成前缀（取前8个字符）
-	keyPrefix := fullKey[:8]
-
-	// 对完整Key进行哈希
-	hashedKey, err := bcrypt.GenerateFromPassword([]byte(fullKey), bcrypt.DefaultCost)
+	apiKey := &models.ApiKey{
+		Name:        name,
+		Description: description,
+		Scopes:      normalizedScopes,
+		ExpiresAt:   expiresAt,
+		Status:      models.ApiKeyStatusActive,
+	}
+
+	var fullKey string
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		var createErr error
+		fullKey, createErr = createApiKeyWithApplications(tx, apiKey, appIDs)
+		return createErr
+	})
 	if err != nil {
 		return nil, "", err
 	}
 
-	apiKey := &models.ApiKey{
-		Name:         name,
-		KeyPrefix:    keyPrefix,
-		KeyValueHash: string(hashedKey),
-		Description:  description,
-		ExpiresAt:    expiresAt,
-		Status:       "active",
+	// 加载关联的应用信息
+	if err := s.db.Preload("Applications").First(apiKey, "id = ?", apiKey.ID).Error; err != nil {
+		return nil, "", err
 	}
 
-	// 开始数据库事务
-	tx := s.db.Begin()
-	if tx.Error != nil {
-		return nil, "", tx.Error
+	// 返回完整的Key值（仅此一次），数据库存储其Hash
+	return apiKey, fullKey, nil
+}
+
+// createApiKeyWithApplications 生成Key值并在事务中创建ApiKey记录及应用关联，返回完整Key值
+func createApiKeyWithApplications(tx *gorm.DB, apiKey *models.ApiKey, appIDs []string) (string, error) {
+	// 生成API Key
+	fullKey, err := generateRandomString(64) // 生成32字节的随机字符串，转为64字符的hex
+	if err != nil {
+		return "", err
+	}
+
+	// 对完整Key进行哈希
+	hashedKey, err := bcrypt.GenerateFromPassword([]byte(fullKey), bcrypt.DefaultCost)
+	if err != nil {
+		return "", err
 	}
 
+	// 前缀取前8个字符，用于快速识别
+	apiKey.KeyPrefix = fullKey[:8]
+	apiKey.KeyValueHash = string(hashedKey)
+
 	// 创建API Key记录
 	if err := tx.Create(apiKey).Error; err != nil {
-		tx.Rollback()
-		return nil, "", err
+		return "", err
 	}
 
 	// 关联应用
@@ -254,23 +288,11 @@ func (s *SharingService) CreateApiKey(name, description string, appIDs []string,
 			ApiApplicationID: appID,
 		}
 		if err := tx.Create(keyApp).Error; err != nil {
-			tx.Rollback()
-			return nil, "", err
+			return "", err
 		}
 	}
 
-	// 提交事务
-	if err := tx.Commit().Error; err != nil {
-		return nil, "", err
-	}
-
-	// 加载关联的应用信息
-	if err := s.db.Preload("Applications").First(apiKey, "id = ?", apiKey.ID).Error; err != nil {
-		return nil, "", err
-	}
-
-	// 返回完整的Key值（仅此一次），数据库存储其Hash
-	return apiKey, fullKey, nil
+	return fullKey, nil
 }
 
 // GetApiKeys 获取所有ApiKey信息（不包含Key本身），可选择按应用过滤
@@ -384,8 +406,19 @@ func (s *SharingService) DeleteApiKey(keyID string) error {
 	return tx.Commit().Error
 }
 
-// VerifyApiKey 验证API Key
+// VerifyApiKey 验证API Key并记录使用情况
 func (s *SharingService) VerifyApiKey(keyValue string) (*models.ApiKey, error) {
+	key, err := s.matchApiKey(keyValue)
+	if err != nil {
+		return nil, err
+	}
+
+	s.recordApiKeyUsage(key, "")
+	return key, nil
+}
+
+// matchApiKey 根据Key值查找有效的ApiKey，不记录使用情况
+func (s *SharingService) matchApiKey(keyValue string) (*models.ApiKey, error) {
 	if len(keyValue) < 8 {
 		return nil, errors.New("无效的API Key格式")
 	}
@@ -393,25 +426,19 @@ func (s *SharingService) VerifyApiKey(keyValue string) (*models.ApiKey, error) {
 	keyPrefix := keyValue[:8]
 
 	var keys []models.ApiKey
-	if err := s.db.Where("key_prefix = ? AND status = 'active'", keyPrefix).Find(&keys).Error; err != nil {
+	if err := s.db.Where("key_prefix = ? AND status = ?", keyPrefix, models.ApiKeyStatusActive).Find(&keys).Error; err != nil {
 		return nil, err
 	}
 
 	// 遍历所有匹配前缀的Key，验证完整Key
-	for _, key := range keys {
+	for i := range keys {
+		key := &keys[i]
 		if err := bcrypt.CompareHashAndPassword([]byte(key.KeyValueHash), []byte(keyValue)); err == nil {
 			// 检查是否过期
 			if key.ExpiresAt != nil && key.ExpiresAt.Before(time.Now()) {
 				return nil, errors.New("API Key已过期")
 			}
-
-			// 更新最后使用时间和使用次数
-			s.db.Model(&key).Updates(map[string]interface{}{
-				"last_used_at": time.Now(),
-				"usage_count":  key.UsageCount + 1,
-			})
-
-			return &key, nil
+			return key, nil
 		}
 	}
 
@@ -450,10 +477,12 @@ func (s *SharingService) CreateApiInterface(apiInterface *models.ApiInterface) e
 		return errors.New("应用不存在")
 	}
 
-	// 验证主题接口是否存在
-	var thematicInterface models.ThematicInterface
-	if err := s.db.First(&thematicInterface, "id = ?", apiInterface.ThematicInterfaceID).Error; err != nil {
-		return errors.New("主题接口不存在")
+	// 验证主题接口是否存在且已发布
+	if err := s.requirePublishedThematicInterface(apiInterface.ThematicInterfaceID); err != nil {
+		return err
+	}
+	if err := s.requireNotDeprecated(models.DeprecationResourceThematicInterface, apiInterface.ThematicInterfaceID); err != nil {
+		return err
 	}
 
 	// 验证路径唯一性
@@ -468,6 +497,18 @@ func (s *SharingService) CreateApiInterface(apiInterface *models.ApiInterface) e
 	return s.db.Create(apiInterface).Error
 }
 
+// requirePublishedThematicInterface 校验主题接口存在且已通过发布评审
+func (s *SharingService) requirePublishedThematicInterface(thematicInterfaceID string) error {
+	var thematicInterface models.ThematicInterface
+	if err := s.db.First(&thematicInterface, "id = ?", thematicInterfaceID).Error; err != nil {
+		return errors.New("主题接口不存在")
+	}
+	if thematicInterface.PublishStatus != models.ThematicPublishStatusPublished {
+		return ErrThematicInterfaceNotPublished
+	}
+	return nil
+}
+
 // GetApiInterfaces 查询共享接口列表，可按 api_application_id 过滤
 func (s *SharingService) GetApiInterfaces(appID string) ([]models.ApiInterface, error) {
 	var interfaces []models.ApiInterface
@@ -492,11 +533,13 @@ func (s *SharingService) GetApiInterfaceByID(id string) (*models.ApiInterface, e
 	return &apiInterface, nil
 }
 
-// GetApiInterfaceByAppPathAndInterfacePath 根据应用路径和接口路径获取ApiInterface
+// GetApiInterfaceByAppPathAndInterfacePath 根据应用路径和接口路径获取ApiInterface，主题接口未发布时视为不存在
 func (s *SharingService) GetApiInterfaceByAppPathAndInterfacePath(appPath, interfacePath string) (*models.ApiInterface, error) {
 	var apiInterface models.ApiInterface
 	if err := s.db.Joins("JOIN api_applications ON api_interfaces.api_application_id = api_applications.id").
+		Joins("JOIN thematic_interfaces ON api_interfaces.thematic_interface_id = thematic_interfaces.id").
 		Where("api_applications.path = ? AND api_interfaces.path = ? AND api_interfaces.status = 'active' AND api_applications.status = 'active'", appPath, interfacePath).
+		Where("thematic_interfaces.publish_status = ?", models.ThematicPublishStatusPublished).
 		Preload("ApiApplication").Preload("ApiApplication.ThematicLibrary").Preload("ThematicInterface").
 		First(&apiInterface).Error; err != nil {
 		return nil, err
@@ -519,11 +562,19 @@ func (s *SharingService) GetApiInterfaceBySchemaAndPath(schema, path string) (*m
 
 // DeleteApiInterface 删除一个共享接口
 func (s *SharingService) DeleteApiInterface(id string) error {
-	return s.db.Delete(&models.ApiInterface{}, "id = ?", id).Error
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("api_interface_id = ?", id).Delete(&models.ApiInterfaceRelease{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("resource_type = ? AND resource_id = ?", models.DeprecationResourceApiInterface, id).Delete(&models.InterfaceDeprecation{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.ApiInterface{}, "id = ?", id).Error
+	})
 }
 
-// UpdateApiInterface 更新API接口
-func (s *SharingService) UpdateApiInterface(id string, updates map[string]interface{}) error {
+// UpdateApiInterface 更新API接口，expectedVersion为0时不校验版本
+func (s *SharingService) UpdateApiInterface(id string, expectedVersion int64, updates map[string]interface{}) error {
 	// 如果更新了path，需要验证唯一性
 	if newPath, ok := updates["path"].(string); ok {
 		var count int64
@@ -537,7 +588,21 @@ func (s *SharingService) UpdateApiInterface(id string, updates map[string]interf
 		}
 	}
 
-	return s.db.Model(&models.ApiInterface{}).Where("id = ?", id).Updates(updates).Error
+	// 灰度发布期间不允许直接切换主题接口，需通过全量切换或回退完成
+	if thematicInterfaceID, ok := updates["thematic_interface_id"]; ok {
+		active, err := s.hasActiveApiInterfaceRelease(id)
+		if err != nil {
+			return err
+		}
+		if active {
+			return ErrReleaseInProgress
+		}
+		if err := s.requirePublishedThematicInterface(fmt.Sprint(thematicInterfaceID)); err != nil {
+			return err
+		}
+	}
+
+	return models.UpdateWithRowVersion(s.db, &models.ApiInterface{}, id, expectedVersion, updates)
 }
 
 // === API接口脱敏规则管理 ===
@@ -606,7 +671,10 @@ func (s *SharingService) UpdateApiInterfaceMaskingRules(interfaceID string, mask
 	// 更新数据库
 	return s.db.Model(&models.ApiInterface{}).
 		Where("id = ?", interfaceID).
-		Update("masking_rules", maskingRulesJSON).Error
+		Updates(map[string]interface{}{
+			"masking_rules": maskingRulesJSON,
+			"row_version":   gorm.Expr("row_version + 1"),
+		}).Error
 }
 
 // GetApiInterfaceMaskingRules 获取API接口脱敏规则
@@ -836,6 +904,11 @@ func (s *SharingService) CreateDataSubscription(subscription *models.DataSubscri
 		return errors.New("无效的通知方式")
 	}
 
+	// 弃用的资源不能新建订阅
+	if err := s.requireNotDeprecated(subscription.ResourceType, subscription.ResourceID); err != nil {
+		return err
+	}
+
 	return s.db.Create(subscription).Error
 }
 
@@ -917,6 +990,18 @@ func (s *SharingService) CreateDataAccessRequest(request *models.DataAccessReque
 		return errors.New("无效的访问权限")
 	}
 
+	if err := validateAccessPurpose(request); err != nil {
+		return err
+	}
+
+	// 审批结果和有效期只能由审批流程写入
+	request.Status = models.DataAccessRequestPending
+	request.ValidUntil = nil
+	request.ApprovalComment = nil
+	request.ApproverID = nil
+	request.ApproverName = nil
+	request.ApprovedAt = nil
+
 	return s.db.Create(request).Error
 }
 
@@ -958,23 +1043,6 @@ func (s *SharingService) GetDataAccessRequestByID(id string) (*models.DataAccess
 	return &request, nil
 }
 
-// ApproveDataAccessRequest 审批数据使用申请
-func (s *SharingService) ApproveDataAccessRequest(id, approverID string, approved bool, comment string) error {
-	updates := map[string]interface{}{
-		"approver_id":      approverID,
-		"approval_comment": comment,
-		"approved_at":      time.Now(),
-	}
-
-	if approved {
-		updates["status"] = "approved"
-	} else {
-		updates["status"] = "rejected"
-	}
-
-	return s.db.Model(&models.DataAccessRequest{}).Where("id = ?", id).Updates(updates).Error
-}
-
 // === API使用日志管理 ===
 
 // CreateApiUsageLog 创建API使用日志
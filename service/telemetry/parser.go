@@ -0,0 +1,277 @@
+/*
+ * @module service/telemetry/parser
+ * @description 遥测数据解析，把InfluxDB行协议或JSON数组解析为按列名组织的行
+ * @architecture 分层架构 - 业务服务层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow 请求体 -> 按格式逐行/逐元素解析 -> 标签和字段按列名展开 -> 整数时间戳按精度换算为时间
+ * @rules 行协议：measurement被忽略，标签值为字符串，字段值按浮点、i结尾整数、u结尾无符号整数、布尔、双引号字符串解析，
+ *        逗号、空格、等号可用反斜杠转义，#开头的行为注释；时间戳缺失时使用接收时间；
+ *        JSON：数组或单个对象，数值保持原始精度，时间列为数值时按精度换算，为字符串时原样交给数据库解析
+ * @dependencies encoding/json, strconv
+ * @refs service.go
+ */
+
+package telemetry
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 请求体格式
+const (
+	FormatLineProtocol = "line"
+	FormatJSON         = "json"
+)
+
+// ErrInvalidPayload 遥测数据格式不正确
+var ErrInvalidPayload = errors.New("遥测数据格式不正确")
+
+// precisionUnits 整数时间戳的精度
+var precisionUnits = map[string]time.Duration{
+	"ns": time.Nanosecond,
+	"us": time.Microsecond,
+	"ms": time.Millisecond,
+	"s":  time.Second,
+}
+
+// DetectFormat 按Content-Type识别格式，未声明JSON时按请求体首个非空字符判断
+func DetectFormat(contentType string, body []byte) string {
+	if strings.Contains(strings.ToLower(contentType), "json") {
+		return FormatJSON
+	}
+	trimmed := bytes.TrimSpace(body)
+	if len(trimmed) > 0 && (trimmed[0] == '[' || trimmed[0] == '{') {
+		return FormatJSON
+	}
+	return FormatLineProtocol
+}
+
+// DefaultPrecision 未指定精度时，行协议按纳秒、JSON按毫秒
+func DefaultPrecision(format string) string {
+	if format == FormatJSON {
+		return "ms"
+	}
+	return "ns"
+}
+
+// ParsePayload 按格式解析请求体，时间戳写入timeColumn列，缺失时为receivedAt
+func ParsePayload(body []byte, format, precision, timeColumn string, receivedAt time.Time) ([]map[string]interface{}, error) {
+	unit, ok := precisionUnits[precision]
+	if !ok {
+		return nil, fmt.Errorf("%w: 不支持的时间精度%s，可选ns、us、ms、s", ErrInvalidPayload, precision)
+	}
+	switch format {
+	case FormatLineProtocol:
+		return parseLineProtocol(body, unit, timeColumn, receivedAt)
+	case FormatJSON:
+		return parseJSONRows(body, unit, timeColumn, receivedAt)
+	}
+	return nil, fmt.Errorf("%w: 不支持的格式%s，可选line、json", ErrInvalidPayload, format)
+}
+
+// parseJSONRows 解析JSON数组或单个对象
+func parseJSONRows(body []byte, unit time.Duration, timeColumn string, receivedAt time.Time) ([]map[string]interface{}, error) {
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	var payload interface{}
+	if err := decoder.Decode(&payload); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPayload, err)
+	}
+
+	var items []interface{}
+	switch value := payload.(type) {
+	case []interface{}:
+		items = value
+	case map[string]interface{}:
+		items = []interface{}{value}
+	default:
+		return nil, fmt.Errorf("%w: JSON须为对象数组", ErrInvalidPayload)
+	}
+
+	rows := make([]map[string]interface{}, 0, len(items))
+	for i, item := range items {
+		row, ok := item.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("%w: 第%d个元素不是对象", ErrInvalidPayload, i+1)
+		}
+		switch value := row[timeColumn].(type) {
+		case nil:
+			row[timeColumn] = formatTime(receivedAt)
+		case json.Number:
+			n, err := value.Int64()
+			if err != nil {
+				return nil, fmt.Errorf("%w: 第%d个元素的时间%s不是整数时间戳", ErrInvalidPayload, i+1, value)
+			}
+			row[timeColumn] = formatTime(time.Unix(0, n*int64(unit)))
+		case string:
+		default:
+			return nil, fmt.Errorf("%w: 第%d个元素的时间须为字符串或整数时间戳", ErrInvalidPayload, i+1)
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// parseLineProtocol 解析InfluxDB行协议：measurement[,tag=value...] field=value[,field=value...] [timestamp]
+func parseLineProtocol(body []byte, unit time.Duration, timeColumn string, receivedAt time.Time) ([]map[string]interface{}, error) {
+	var rows []map[string]interface{}
+	for number, line := range strings.Split(string(body), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		row, err := parseLine(line, unit, timeColumn, receivedAt)
+		if err != nil {
+			return nil, fmt.Errorf("%w: 第%d行%v", ErrInvalidPayload, number+1, err)
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// parseLine 解析一行
+func parseLine(line string, unit time.Duration, timeColumn string, receivedAt time.Time) (map[string]interface{}, error) {
+	sections := splitUnescaped(line, ' ', true)
+	if len(sections) < 2 || len(sections) > 3 {
+		return nil, errors.New("须由measurement和标签、字段、时间戳三段以空格分隔组成")
+	}
+
+	row := map[string]interface{}{}
+	seriesKey := splitUnescaped(sections[0], ',', false)
+	for _, tag := range seriesKey[1:] {
+		key, value, err := splitPair(tag)
+		if err != nil {
+			return nil, err
+		}
+		row[key] = value
+	}
+
+	for _, field := range splitUnescaped(sections[1], ',', true) {
+		key, raw, err := splitPair(field)
+		if err != nil {
+			return nil, err
+		}
+		value, err := parseFieldValue(raw)
+		if err != nil {
+			return nil, fmt.Errorf("字段%s%v", key, err)
+		}
+		row[key] = value
+	}
+
+	row[timeColumn] = formatTime(receivedAt)
+	if len(sections) == 3 {
+		n, err := strconv.ParseInt(sections[2], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("时间戳%s不是整数", sections[2])
+		}
+		row[timeColumn] = formatTime(time.Unix(0, n*int64(unit)))
+	}
+	return row, nil
+}
+
+// splitUnescaped 按未转义的分隔符切分，quoted为true时双引号内的分隔符不切分
+func splitUnescaped(s string, sep byte, quoted bool) []string {
+	var parts []string
+	inQuote := false
+	start := 0
+	for i := 0; i < len(s); i++ {
+		switch {
+		case s[i] == '\\':
+			i++
+		case quoted && s[i] == '"':
+			inQuote = !inQuote
+		case s[i] == sep && !inQuote:
+			if i > start {
+				parts = append(parts, s[start:i])
+			}
+			start = i + 1
+		}
+	}
+	if start < len(s) {
+		parts = append(parts, s[start:])
+	}
+	return parts
+}
+
+// splitPair 切分key=value，去掉键和标签值中的转义
+func splitPair(pair string) (string, string, error) {
+	for i := 0; i < len(pair); i++ {
+		switch pair[i] {
+		case '\\':
+			i++
+		case '=':
+			key := unescape(pair[:i])
+			if key == "" {
+				return "", "", fmt.Errorf("%s缺少键", pair)
+			}
+			value := pair[i+1:]
+			if !strings.HasPrefix(value, `"`) {
+				value = unescape(value)
+			}
+			return key, value, nil
+		}
+	}
+	return "", "", fmt.Errorf("%s缺少=", pair)
+}
+
+// parseFieldValue 解析字段值
+func parseFieldValue(raw string) (interface{}, error) {
+	switch {
+	case raw == "":
+		return nil, errors.New("缺少值")
+	case strings.HasPrefix(raw, `"`):
+		if len(raw) < 2 || !strings.HasSuffix(raw, `"`) {
+			return nil, errors.New("字符串缺少结束引号")
+		}
+		return strings.NewReplacer(`\"`, `"`, `\\`, `\`).Replace(raw[1 : len(raw)-1]), nil
+	case strings.HasSuffix(raw, "i"):
+		n, err := strconv.ParseInt(raw[:len(raw)-1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("整数%s格式不正确", raw)
+		}
+		return n, nil
+	case strings.HasSuffix(raw, "u"):
+		n, err := strconv.ParseUint(raw[:len(raw)-1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("无符号整数%s格式不正确", raw)
+		}
+		return n, nil
+	}
+	switch raw {
+	case "t", "T", "true", "True", "TRUE":
+		return true, nil
+	case "f", "F", "false", "False", "FALSE":
+		return false, nil
+	}
+	f, err := strconv.ParseFloat(raw, 64)
+	if err != nil {
+		return nil, fmt.Errorf("值%s格式不正确", raw)
+	}
+	return f, nil
+}
+
+// unescape 去掉反斜杠转义
+func unescape(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+1 < len(s) {
+			i++
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
+
+// formatTime 时间按RFC3339格式交给数据库解析
+func formatTime(t time.Time) string {
+	return t.Format(time.RFC3339Nano)
+}
@@ -0,0 +1,90 @@
+/*
+ * @module service/telemetry/parser_test
+ * @description 遥测数据解析测试，覆盖行协议的标签、各类型字段、转义、时间戳精度和错误行，以及JSON数组的时间列换算
+ * @architecture 测试层
+ * @documentReference ai_docs/requirements.md
+ * @rules 纯函数测试，不依赖数据库
+ * @dependencies stretchr/testify
+ * @refs parser.go
+ */
+
+package telemetry
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseLineProtocol(t *testing.T) {
+	received := time.Date(2026, 3, 1, 8, 0, 0, 0, time.UTC)
+	body := []byte(`# 注释
+meter,device_id=m-01,region=east\ gate power=12.5,count=3i,online=t,note="door \"A\", 1" 1772352000000000000
+
+meter,device_id=m-02 power=-1e3
+`)
+	rows, err := ParsePayload(body, FormatLineProtocol, "ns", "ts", received)
+	require.NoError(t, err)
+	require.Len(t, rows, 2)
+	assert.Equal(t, map[string]interface{}{
+		"device_id": "m-01", "region": "east gate", "power": 12.5, "count": int64(3), "online": true,
+		"note": `door "A", 1`, "ts": formatTime(time.Unix(1772352000, 0)),
+	}, rows[0])
+	assert.Equal(t, -1000.0, rows[1]["power"])
+	assert.Equal(t, formatTime(received), rows[1]["ts"], "缺少时间戳时使用接收时间")
+
+	rows, err = ParsePayload([]byte("cpu,host=a usage=1 1772352000"), FormatLineProtocol, "s", "ts", received)
+	require.NoError(t, err)
+	assert.Equal(t, formatTime(time.Unix(1772352000, 0)), rows[0]["ts"])
+
+	for _, line := range []string{
+		"meter",
+		"meter power",
+		"meter,device_id power=1",
+		"meter power=abc",
+		"meter power=1 soon",
+		`meter note="unterminated`,
+		"meter power=1 1 extra",
+	} {
+		_, err := ParsePayload([]byte(line), FormatLineProtocol, "ns", "ts", received)
+		assert.ErrorIs(t, err, ErrInvalidPayload, line)
+	}
+	_, err = ParsePayload([]byte("meter power=1"), FormatLineProtocol, "h", "ts", received)
+	assert.ErrorIs(t, err, ErrInvalidPayload, "不支持的精度")
+}
+
+func TestParseJSONRows(t *testing.T) {
+	received := time.Date(2026, 3, 1, 8, 0, 0, 0, time.UTC)
+	body := []byte(`[
+		{"device_id": "m-01", "power": 12.5, "ts": 1772352000123},
+		{"device_id": "m-02", "power": 90071992547409931, "ts": "2026-03-01T08:00:00+08:00"},
+		{"device_id": "m-03"}
+	]`)
+	rows, err := ParsePayload(body, FormatJSON, "ms", "ts", received)
+	require.NoError(t, err)
+	require.Len(t, rows, 3)
+	assert.Equal(t, formatTime(time.UnixMilli(1772352000123)), rows[0]["ts"])
+	assert.Equal(t, json.Number("90071992547409931"), rows[1]["power"], "数值保持原始精度")
+	assert.Equal(t, "2026-03-01T08:00:00+08:00", rows[1]["ts"])
+	assert.Equal(t, formatTime(received), rows[2]["ts"])
+
+	rows, err = ParsePayload([]byte(`{"device_id": "m-01"}`), FormatJSON, "ms", "ts", received)
+	require.NoError(t, err)
+	assert.Len(t, rows, 1, "单个对象")
+
+	for _, bad := range []string{`"text"`, `[1, 2]`, `[{"ts": 1.5}]`, `[{"ts": true}]`, `[{`} {
+		_, err := ParsePayload([]byte(bad), FormatJSON, "ms", "ts", received)
+		assert.ErrorIs(t, err, ErrInvalidPayload, bad)
+	}
+}
+
+func TestDetectFormat(t *testing.T) {
+	assert.Equal(t, FormatJSON, DetectFormat("application/json; charset=utf-8", []byte("meter power=1")))
+	assert.Equal(t, FormatJSON, DetectFormat("", []byte("  [{}]")))
+	assert.Equal(t, FormatLineProtocol, DetectFormat("text/plain", []byte("meter power=1")))
+	assert.Equal(t, "ms", DefaultPrecision(FormatJSON))
+	assert.Equal(t, "ns", DefaultPrecision(FormatLineProtocol))
+}
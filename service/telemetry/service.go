@@ -0,0 +1,298 @@
+/*
+ * @module service/telemetry/service
+ * @description 设备遥测数据快速写入，绕过逐行的实时处理流程，按接口汇集多个请求的数据批量写入时间序列接口表
+ * @architecture 分层架构 - 业务服务层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow 请求 -> 查找接口（带缓存）-> 解析行协议/JSON -> 丢弃未知字段 -> 并入接口的待写批次 -> 批次满或到时间后一条INSERT写入 -> 唤醒批次内全部请求返回 -> 通知写入回调
+ * @rules 至少一次：请求在所属批次写入成功后才返回成功，写入失败或超时返回错误，调用方重试可能产生重复，主键冲突的行被忽略；
+ *        目标接口须已开启时间序列存储并允许遥测写入；待写行数超过maxPendingRows时拒绝新请求；
+ *        批次的列为批次内出现过的已知列，行中缺失的列写入NULL；开启来源追溯时填写入库时间
+ * @dependencies datahub-service/service/models, gorm.io/gorm
+ * @refs parser.go, service/models/timeseries.go, api/controllers/telemetry_controller.go
+ */
+
+package telemetry
+
+import (
+	"context"
+	"datahub-service/service/models"
+	"datahub-service/service/utils"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	defaultMaxBatchRows  = 5000                   // 批次行数达到后立即写入
+	defaultFlushInterval = 200 * time.Millisecond // 批次最长等待时间
+	defaultMaxPending    = 200000                 // 全部接口待写行数上限
+	maxRowsPerRequest    = 100000                 // 单个请求最多行数
+	targetCacheTTL       = 30 * time.Second       // 接口配置缓存时间
+)
+
+var (
+	// ErrIngestNotEnabled 接口未开启遥测写入
+	ErrIngestNotEnabled = errors.New("接口未开启时间序列存储或未允许遥测写入")
+	// ErrBusy 待写数据过多，调用方应稍后重试
+	ErrBusy = errors.New("遥测写入繁忙，请稍后重试")
+)
+
+// IngestResult 写入结果
+type IngestResult struct {
+	Accepted      int      `json:"accepted"`                 // 写入的行数（含主键冲突被忽略的行）
+	IgnoredFields []string `json:"ignored_fields,omitempty"` // 接口表没有的字段，已丢弃
+}
+
+// target 写入目标接口表
+type target struct {
+	interfaceID string
+	table       string // 带schema的完整表名
+	timeColumn  string
+	columns     map[string]bool // 可写入的列，不含派生列
+	provenance  bool
+	expiresAt   time.Time
+}
+
+// batch 待写批次，批次内的请求共享写入结果
+type batch struct {
+	rows []map[string]interface{}
+	done chan struct{}
+	err  error
+}
+
+// buffer 单个接口的待写批次
+type buffer struct {
+	mu      sync.Mutex
+	current *batch
+}
+
+// Service 遥测写入服务
+type Service struct {
+	db            *gorm.DB
+	maxBatchRows  int
+	flushInterval time.Duration
+	maxPending    int64
+	pending       atomic.Int64
+
+	mu      sync.Mutex
+	targets map[string]*target
+	buffers map[string]*buffer
+
+	// writeBatch 批量写入，测试时替换
+	writeBatch func(ctx context.Context, t *target, rows []map[string]interface{}) error
+	// onWritten 批次写入成功后调用，不能阻塞
+	onWritten func(interfaceID string)
+}
+
+// NewService 创建遥测写入服务
+func NewService(db *gorm.DB) *Service {
+	s := &Service{
+		db:            db,
+		maxBatchRows:  defaultMaxBatchRows,
+		flushInterval: defaultFlushInterval,
+		maxPending:    defaultMaxPending,
+		targets:       make(map[string]*target),
+		buffers:       make(map[string]*buffer),
+	}
+	s.writeBatch = s.insertRows
+	return s
+}
+
+// SetWriteListener 设置批次写入成功后的回调，如判定数据值告警，回调在写入goroutine中执行，不能阻塞
+func (s *Service) SetWriteListener(listener func(interfaceID string)) {
+	s.onWritten = listener
+}
+
+// Ingest 解析请求体并写入接口表，数据写入后返回
+func (s *Service) Ingest(ctx context.Context, interfaceID string, body []byte, format, precision string) (*IngestResult, error) {
+	t, err := s.getTarget(ctx, interfaceID)
+	if err != nil {
+		return nil, err
+	}
+	if precision == "" {
+		precision = DefaultPrecision(format)
+	}
+	rows, err := ParsePayload(body, format, precision, t.timeColumn, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) > maxRowsPerRequest {
+		return nil, fmt.Errorf("%w: 单个请求最多%d行", ErrInvalidPayload, maxRowsPerRequest)
+	}
+
+	result := &IngestResult{Accepted: len(rows)}
+	ignored := map[string]bool{}
+	for _, row := range rows {
+		for key := range row {
+			if !t.columns[key] {
+				ignored[key] = true
+				delete(row, key)
+			}
+		}
+	}
+	for key := range ignored {
+		result.IgnoredFields = append(result.IgnoredFields, key)
+	}
+	sort.Strings(result.IgnoredFields)
+	if len(rows) == 0 {
+		return result, nil
+	}
+
+	if s.pending.Add(int64(len(rows))) > s.maxPending {
+		s.pending.Add(-int64(len(rows)))
+		return nil, ErrBusy
+	}
+	b := s.enqueue(t, rows)
+	select {
+	case <-b.done:
+		if b.err != nil {
+			return nil, b.err
+		}
+		return result, nil
+	case <-ctx.Done():
+		// 批次仍会写入，调用方重试可能产生重复
+		return nil, ctx.Err()
+	}
+}
+
+// enqueue 把行并入接口的当前批次，批次满时立即写入，新批次到时间后写入
+func (s *Service) enqueue(t *target, rows []map[string]interface{}) *batch {
+	s.mu.Lock()
+	buf, ok := s.buffers[t.interfaceID]
+	if !ok {
+		buf = &buffer{}
+		s.buffers[t.interfaceID] = buf
+	}
+	s.mu.Unlock()
+
+	buf.mu.Lock()
+	defer buf.mu.Unlock()
+	if buf.current == nil {
+		buf.current = &batch{done: make(chan struct{})}
+		current := buf.current
+		time.AfterFunc(s.flushInterval, func() {
+			buf.mu.Lock()
+			if buf.current != current {
+				buf.mu.Unlock()
+				return
+			}
+			buf.current = nil
+			buf.mu.Unlock()
+			s.flush(t, current)
+		})
+	}
+	b := buf.current
+	b.rows = append(b.rows, rows...)
+	if len(b.rows) >= s.maxBatchRows {
+		buf.current = nil
+		go s.flush(t, b)
+	}
+	return b
+}
+
+// flush 写入批次并唤醒等待的请求
+func (s *Service) flush(t *target, b *batch) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	start := time.Now()
+	b.err = s.writeBatch(ctx, t, b.rows)
+	s.pending.Add(-int64(len(b.rows)))
+	if b.err != nil {
+		slog.Error("遥测数据批量写入失败", "interface_id", t.interfaceID, "rows", len(b.rows), "error", b.err)
+	} else {
+		slog.Debug("遥测数据批量写入", "interface_id", t.interfaceID, "rows", len(b.rows), "duration", time.Since(start))
+	}
+	close(b.done)
+	if b.err == nil && s.onWritten != nil {
+		s.onWritten(t.interfaceID)
+	}
+}
+
+// insertRows 以一条INSERT写入整批数据，由数据库按接口表的列类型转换JSON值
+func (s *Service) insertRows(ctx context.Context, t *target, rows []map[string]interface{}) error {
+	seen := map[string]bool{}
+	for _, row := range rows {
+		for key := range row {
+			seen[key] = true
+		}
+	}
+	if t.provenance {
+		now := formatTime(time.Now())
+		for _, row := range rows {
+			row[models.ProvenanceIngestedAtColumn] = now
+		}
+		seen[models.ProvenanceIngestedAtColumn] = true
+	}
+	columns := make([]string, 0, len(seen))
+	for column := range seen {
+		columns = append(columns, `"`+column+`"`)
+	}
+	sort.Strings(columns)
+	columnList := strings.Join(columns, ", ")
+
+	payload, err := json.Marshal(rows)
+	if err != nil {
+		return fmt.Errorf("序列化遥测数据失败: %w", err)
+	}
+	sql := fmt.Sprintf("INSERT INTO %s (%s) SELECT %s FROM jsonb_populate_recordset(NULL::%s, ?::jsonb) ON CONFLICT DO NOTHING",
+		t.table, columnList, columnList, t.table)
+	if err := s.db.WithContext(ctx).Exec(sql, string(payload)).Error; err != nil {
+		return fmt.Errorf("写入遥测数据失败: %w", err)
+	}
+	return nil
+}
+
+// getTarget 获取接口的写入目标，缓存targetCacheTTL
+func (s *Service) getTarget(ctx context.Context, interfaceID string) (*target, error) {
+	s.mu.Lock()
+	cached, ok := s.targets[interfaceID]
+	s.mu.Unlock()
+	if ok && time.Now().Before(cached.expiresAt) {
+		return cached, nil
+	}
+
+	var iface models.DataInterface
+	if err := s.db.WithContext(ctx).Preload("BasicLibrary").First(&iface, "id = ?", interfaceID).Error; err != nil {
+		return nil, err
+	}
+	config := models.ParseTimeSeriesConfig(iface.InterfaceConfig)
+	if config == nil || !config.IngestEnabled || !iface.IsTableCreated {
+		return nil, ErrIngestNotEnabled
+	}
+
+	t := &target{
+		interfaceID: interfaceID,
+		table:       utils.QuoteTable(iface.BasicLibrary.GetSchemaName(), iface.NameEn),
+		timeColumn:  config.TimeColumn,
+		columns:     map[string]bool{},
+		provenance:  models.ParseProvenanceConfig(iface.InterfaceConfig) != nil,
+		expiresAt:   time.Now().Add(targetCacheTTL),
+	}
+	for _, raw := range iface.TableFieldsConfig {
+		data, err := json.Marshal(raw)
+		if err != nil {
+			continue
+		}
+		var field models.TableField
+		if err := json.Unmarshal(data, &field); err != nil || field.NameEn == "" || field.IsComputed() {
+			continue
+		}
+		if models.IsProvenanceColumn(field.NameEn) {
+			continue
+		}
+		t.columns[field.NameEn] = true
+	}
+
+	s.mu.Lock()
+	s.targets[interfaceID] = t
+	s.mu.Unlock()
+	return t, nil
+}
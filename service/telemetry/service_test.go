@@ -0,0 +1,103 @@
+/*
+ * @module service/telemetry/service_test
+ * @description 遥测写入服务测试，覆盖多个请求合并为一个批次、批次满时立即写入、写入失败时批次内请求均返回错误以及待写上限
+ * @architecture 测试层
+ * @documentReference ai_docs/requirements.md
+ * @rules 替换批量写入函数并预置写入目标，不依赖数据库
+ * @dependencies stretchr/testify
+ * @refs service.go
+ */
+
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupTelemetryService 预置接口if-1的写入目标，记录每次批量写入的行数
+func setupTelemetryService(t *testing.T, writeErr error) (*Service, *[]int, *sync.Mutex) {
+	s := NewService(nil)
+	s.targets["if-1"] = &target{
+		interfaceID: "if-1",
+		table:       `"iot"."meter"`,
+		timeColumn:  "ts",
+		columns:     map[string]bool{"ts": true, "device_id": true, "power": true},
+		expiresAt:   time.Now().Add(time.Hour),
+	}
+	var mu sync.Mutex
+	var batches []int
+	s.writeBatch = func(ctx context.Context, t *target, rows []map[string]interface{}) error {
+		mu.Lock()
+		defer mu.Unlock()
+		batches = append(batches, len(rows))
+		return writeErr
+	}
+	return s, &batches, &mu
+}
+
+func TestIngestMergesRequestsIntoBatch(t *testing.T) {
+	s, batches, mu := setupTelemetryService(t, nil)
+	s.flushInterval = 300 * time.Millisecond
+
+	var wg sync.WaitGroup
+	results := make([]*IngestResult, 3)
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			result, err := s.Ingest(context.Background(), "if-1", []byte("meter,device_id=m-01 power=1,voltage=220\nmeter,device_id=m-02 power=2"), FormatLineProtocol, "")
+			assert.NoError(t, err)
+			results[i] = result
+		}(i)
+	}
+	wg.Wait()
+
+	mu.Lock()
+	assert.Equal(t, []int{6}, *batches, "三个请求合并为一个批次")
+	mu.Unlock()
+	assert.Equal(t, 2, results[0].Accepted)
+	assert.Equal(t, []string{"voltage"}, results[0].IgnoredFields)
+	assert.Equal(t, int64(0), s.pending.Load())
+}
+
+func TestIngestFlushesFullBatch(t *testing.T) {
+	s, batches, mu := setupTelemetryService(t, nil)
+	s.flushInterval = time.Hour
+	s.maxBatchRows = 2
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := s.Ingest(context.Background(), "if-1", []byte(`[{"device_id":"m-01"},{"device_id":"m-02"}]`), FormatJSON, "")
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		require.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("批次满后未立即写入")
+	}
+	mu.Lock()
+	assert.Equal(t, []int{2}, *batches)
+	mu.Unlock()
+}
+
+func TestIngestReportsWriteFailure(t *testing.T) {
+	writeErr := errors.New("connection refused")
+	s, _, _ := setupTelemetryService(t, writeErr)
+	s.flushInterval = 10 * time.Millisecond
+
+	_, err := s.Ingest(context.Background(), "if-1", []byte("meter power=1"), FormatLineProtocol, "")
+	assert.ErrorIs(t, err, writeErr, "写入失败时请求返回错误，由调用方重试")
+
+	s.maxPending = 1
+	_, err = s.Ingest(context.Background(), "if-1", []byte("meter power=1\nmeter power=2"), FormatLineProtocol, "")
+	assert.ErrorIs(t, err, ErrBusy)
+	assert.Equal(t, int64(0), s.pending.Load())
+}
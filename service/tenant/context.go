@@ -0,0 +1,56 @@
+/*
+ * @module service/tenant/context
+ * @description 租户上下文，在请求context中传递当前租户，并据此计算接口表schema名称
+ * @architecture 分层架构 - 业务服务层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow 租户中间件解析租户 -> WithTenant写入context -> 服务层和GORM回调按context过滤
+ * @rules context中没有租户表示不限定租户（未启用多租户或管理员跨租户访问）；默认租户的schema不加前缀，兼容历史数据
+ * @dependencies datahub-service/service/models
+ * @refs api/middleware/tenant.go, service/tenant/gorm_callback.go
+ */
+
+package tenant
+
+import (
+	"context"
+	"datahub-service/service/models"
+)
+
+// Info 当前请求的租户
+type Info struct {
+	ID   string
+	Code string
+}
+
+type contextKey struct{}
+
+// WithTenant 将租户写入context
+func WithTenant(ctx context.Context, info Info) context.Context {
+	return context.WithValue(ctx, contextKey{}, info)
+}
+
+// FromContext 从context获取租户，ok为false表示不限定租户
+func FromContext(ctx context.Context) (Info, bool) {
+	if ctx == nil {
+		return Info{}, false
+	}
+	info, ok := ctx.Value(contextKey{}).(Info)
+	return info, ok && info.ID != ""
+}
+
+// IDFromContext 从context获取租户ID，未限定租户时返回默认租户ID
+func IDFromContext(ctx context.Context) string {
+	if info, ok := FromContext(ctx); ok {
+		return info.ID
+	}
+	return models.DefaultTenantID
+}
+
+// SchemaName 计算当前租户下库的schema名称，非默认租户加"租户编码_"前缀
+func SchemaName(ctx context.Context, nameEn string) string {
+	info, ok := FromContext(ctx)
+	if !ok || info.ID == models.DefaultTenantID {
+		return nameEn
+	}
+	return info.Code + "_" + nameEn
+}
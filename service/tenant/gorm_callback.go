@@ -0,0 +1,126 @@
+/*
+ * @module service/tenant/gorm_callback
+ * @description 租户隔离的GORM回调，按context中的租户自动填充tenant_id并为查询、更新、删除追加租户条件
+ * @architecture 分层架构 - 业务服务层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow GORM操作(WithContext) -> 回调读取context租户 -> 模型含TenantID字段时填充或追加tenant_id条件
+ * @rules 只作用于带TenantID字段的模型；context中没有租户时不做任何处理；带IsBuiltIn字段的模板类模型查询时内置记录对所有租户可见，但只能由其所属租户修改；原生SQL(Raw/Exec)不经过该回调，需要调用方自行过滤
+ * @dependencies gorm.io/gorm
+ * @refs service/tenant/context.go, service/init.go
+ */
+
+package tenant
+
+import (
+	"context"
+	"reflect"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+const (
+	tenantField     = "TenantID"
+	tenantColumn    = "tenant_id"
+	builtInField    = "IsBuiltIn"
+	scopedSettingID = "tenant:scoped"
+)
+
+// RegisterGormCallbacks 在数据库连接上注册租户隔离回调
+func RegisterGormCallbacks(db *gorm.DB) error {
+	if err := db.Callback().Create().Before("gorm:create").Register("tenant:create", assignTenant); err != nil {
+		return err
+	}
+	if err := db.Callback().Query().Before("gorm:query").Register("tenant:query", scopeTenantRead); err != nil {
+		return err
+	}
+	if err := db.Callback().Row().Before("gorm:row").Register("tenant:row", scopeTenantRead); err != nil {
+		return err
+	}
+	if err := db.Callback().Update().Before("gorm:update").Register("tenant:update", scopeTenantWrite); err != nil {
+		return err
+	}
+	return db.Callback().Delete().Before("gorm:delete").Register("tenant:delete", scopeTenantWrite)
+}
+
+// tenantFieldOf 返回模型的TenantID字段，模型不支持租户时返回nil
+func tenantFieldOf(tx *gorm.DB) *schema.Field {
+	if tx.Statement.Schema == nil {
+		return nil
+	}
+	return tx.Statement.Schema.LookUpField(tenantField)
+}
+
+// assignTenant 创建时为未指定租户的记录填充当前租户
+func assignTenant(tx *gorm.DB) {
+	if tx.Error != nil {
+		return
+	}
+	info, ok := FromContext(tx.Statement.Context)
+	field := tenantFieldOf(tx)
+	if !ok || field == nil {
+		return
+	}
+
+	rv := tx.Statement.ReflectValue
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < rv.Len(); i++ {
+			setTenant(tx, field, reflect.Indirect(rv.Index(i)), info.ID)
+		}
+	case reflect.Struct:
+		setTenant(tx, field, rv, info.ID)
+	}
+}
+
+func setTenant(tx *gorm.DB, field *schema.Field, rv reflect.Value, tenantID string) {
+	if _, isZero := field.ValueOf(tx.Statement.Context, rv); isZero {
+		if err := field.Set(tx.Statement.Context, rv, tenantID); err != nil {
+			tx.AddError(err)
+		}
+	}
+}
+
+// scopeTenantRead 查询时追加当前租户条件，内置模板对所有租户可见
+func scopeTenantRead(tx *gorm.DB) {
+	scopeTenant(tx, true)
+}
+
+// scopeTenantWrite 更新、删除时追加当前租户条件
+func scopeTenantWrite(tx *gorm.DB) {
+	scopeTenant(tx, false)
+}
+
+func scopeTenant(tx *gorm.DB, includeBuiltIn bool) {
+	if tx.Error != nil {
+		return
+	}
+	info, ok := FromContext(tx.Statement.Context)
+	if !ok || tenantFieldOf(tx) == nil {
+		return
+	}
+	// 同一Statement可能多次经过回调（如Count后再Find），条件只追加一次
+	if _, loaded := tx.Statement.Settings.LoadOrStore(scopedSettingID, true); loaded {
+		return
+	}
+
+	var expr clause.Expression = clause.Eq{Column: clause.Column{Table: clause.CurrentTable, Name: tenantColumn}, Value: info.ID}
+	if includeBuiltIn {
+		if field := tx.Statement.Schema.LookUpField(builtInField); field != nil {
+			expr = clause.Or(expr, clause.Eq{Column: clause.Column{Table: clause.CurrentTable, Name: field.DBName}, Value: true})
+		}
+	}
+	tx.Statement.AddClause(clause.Where{Exprs: []clause.Expression{expr}})
+}
+
+// OwnerScope 按当前租户过滤没有tenant_id的从属表（数据源、接口、执行记录等），foreignKey为指向所属表的外键列
+func OwnerScope(ctx context.Context, foreignKey, ownerTable string) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		info, ok := FromContext(ctx)
+		if !ok {
+			return db
+		}
+		return db.Where(foreignKey+" IN (SELECT id FROM "+ownerTable+" WHERE tenant_id = ?)", info.ID)
+	}
+}
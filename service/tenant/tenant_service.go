@@ -0,0 +1,255 @@
+/*
+ * @module service/tenant/tenant_service
+ * @description 租户管理服务，负责租户和租户成员的维护，以及请求租户的解析
+ * @architecture 分层架构 - 业务服务层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow 请求头租户 + 用户成员关系 -> ResolveTenant -> 当前租户
+ * @rules 通过MULTI_TENANT_ENABLED=true启用；管理员可访问任意租户，不指定时不限定租户；普通用户只能访问所属租户，未加入任何租户时归属默认租户；默认租户不可删除或停用
+ * @dependencies datahub-service/service/models, gorm.io/gorm
+ * @refs api/middleware/tenant.go, api/controllers/tenant_controller.go
+ */
+
+package tenant
+
+import (
+	"datahub-service/service/models"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+
+	"gorm.io/gorm"
+)
+
+// tenantCodePattern 租户编码规则，作为schema前缀需满足PostgreSQL标识符要求
+var tenantCodePattern = regexp.MustCompile(`^[a-z][a-z0-9_]{1,31}$`)
+
+// 租户解析错误
+var (
+	ErrTenantNotFound  = errors.New("租户不存在")
+	ErrTenantDisabled  = errors.New("租户已停用")
+	ErrTenantForbidden = errors.New("无权访问该租户")
+)
+
+// Service 租户管理服务
+type Service struct {
+	db      *gorm.DB
+	enabled bool
+}
+
+// NewService 创建租户管理服务实例
+func NewService(db *gorm.DB) *Service {
+	return &Service{
+		db:      db,
+		enabled: os.Getenv("MULTI_TENANT_ENABLED") == "true",
+	}
+}
+
+// IsEnabled 是否启用多租户隔离
+func (s *Service) IsEnabled() bool {
+	return s.enabled
+}
+
+// EnsureDefaultTenant 确保默认租户存在
+func (s *Service) EnsureDefaultTenant() error {
+	defaultTenant := models.Tenant{
+		ID:          models.DefaultTenantID,
+		Code:        models.DefaultTenantID,
+		Name:        "默认租户",
+		Description: "未启用多租户时的全部数据及未加入其他租户的用户",
+	}
+	return s.db.Where("id = ?", defaultTenant.ID).FirstOrCreate(&defaultTenant).Error
+}
+
+// CreateTenant 创建租户
+func (s *Service) CreateTenant(t *models.Tenant) error {
+	if !tenantCodePattern.MatchString(t.Code) {
+		return errors.New("租户编码只能包含小写字母、数字和下划线，以字母开头，长度2-32")
+	}
+	if t.Name == "" {
+		return errors.New("租户名称不能为空")
+	}
+
+	var count int64
+	if err := s.db.Model(&models.Tenant{}).Where("code = ?", t.Code).Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return errors.New("租户编码已存在")
+	}
+
+	t.ID = ""
+	return s.db.Create(t).Error
+}
+
+// GetTenant 获取租户详情
+func (s *Service) GetTenant(id string) (*models.Tenant, error) {
+	var t models.Tenant
+	if err := s.db.First(&t, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// GetTenants 获取租户列表
+func (s *Service) GetTenants(page, pageSize int, status string) ([]models.Tenant, int64, error) {
+	var tenants []models.Tenant
+	var total int64
+
+	query := s.db.Model(&models.Tenant{})
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * pageSize
+	if err := query.Order("created_at ASC").Offset(offset).Limit(pageSize).Find(&tenants).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return tenants, total, nil
+}
+
+// UpdateTenant 更新租户名称、描述和状态，编码不可修改
+func (s *Service) UpdateTenant(id string, updates map[string]interface{}) error {
+	if _, err := s.GetTenant(id); err != nil {
+		return err
+	}
+	if status, ok := updates["status"]; ok {
+		if status != models.TenantStatusActive && status != models.TenantStatusDisabled {
+			return fmt.Errorf("无效的租户状态: %v", status)
+		}
+		if id == models.DefaultTenantID && status == models.TenantStatusDisabled {
+			return errors.New("默认租户不可停用")
+		}
+	}
+	delete(updates, "code")
+	delete(updates, "id")
+
+	return s.db.Model(&models.Tenant{}).Where("id = ?", id).Updates(updates).Error
+}
+
+// DeleteTenant 删除租户，租户下仍有基础库或主题库时不允许删除
+func (s *Service) DeleteTenant(id string) error {
+	if id == models.DefaultTenantID {
+		return errors.New("默认租户不可删除")
+	}
+	if _, err := s.GetTenant(id); err != nil {
+		return err
+	}
+
+	var count int64
+	if err := s.db.Model(&models.BasicLibrary{}).Where("tenant_id = ?", id).Count(&count).Error; err != nil {
+		return err
+	}
+	if count == 0 {
+		if err := s.db.Model(&models.ThematicLibrary{}).Where("tenant_id = ?", id).Count(&count).Error; err != nil {
+			return err
+		}
+	}
+	if count > 0 {
+		return errors.New("租户下存在数据基础库或主题库，无法删除")
+	}
+
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("tenant_id = ?", id).Delete(&models.TenantMember{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.Tenant{}, "id = ?", id).Error
+	})
+}
+
+// GetMembers 获取租户成员列表
+func (s *Service) GetMembers(tenantID string) ([]models.TenantMember, error) {
+	var members []models.TenantMember
+	err := s.db.Where("tenant_id = ?", tenantID).Order("created_at ASC").Find(&members).Error
+	return members, err
+}
+
+// AddMember 将用户加入租户
+func (s *Service) AddMember(tenantID, username, operator string) (*models.TenantMember, error) {
+	if username == "" {
+		return nil, errors.New("用户名不能为空")
+	}
+	if _, err := s.GetTenant(tenantID); err != nil {
+		return nil, err
+	}
+
+	var count int64
+	if err := s.db.Model(&models.TenantMember{}).Where("tenant_id = ? AND username = ?", tenantID, username).Count(&count).Error; err != nil {
+		return nil, err
+	}
+	if count > 0 {
+		return nil, errors.New("用户已是该租户成员")
+	}
+
+	member := &models.TenantMember{
+		TenantID:  tenantID,
+		Username:  username,
+		CreatedBy: operator,
+	}
+	if err := s.db.Create(member).Error; err != nil {
+		return nil, err
+	}
+	return member, nil
+}
+
+// RemoveMember 将用户移出租户
+func (s *Service) RemoveMember(tenantID, username string) error {
+	result := s.db.Where("tenant_id = ? AND username = ?", tenantID, username).Delete(&models.TenantMember{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// ResolveTenant 解析请求的租户，requested为租户ID或编码，ok为false表示不限定租户（仅管理员）
+func (s *Service) ResolveTenant(username string, isAdmin bool, requested string) (Info, bool, error) {
+	if requested != "" {
+		var t models.Tenant
+		if err := s.db.Where("id = ? OR code = ?", requested, requested).First(&t).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return Info{}, false, ErrTenantNotFound
+			}
+			return Info{}, false, err
+		}
+		if t.Status != models.TenantStatusActive {
+			return Info{}, false, ErrTenantDisabled
+		}
+		if !isAdmin && t.ID != models.DefaultTenantID {
+			var count int64
+			if err := s.db.Model(&models.TenantMember{}).Where("tenant_id = ? AND username = ?", t.ID, username).Count(&count).Error; err != nil {
+				return Info{}, false, err
+			}
+			if count == 0 {
+				return Info{}, false, ErrTenantForbidden
+			}
+		}
+		return Info{ID: t.ID, Code: t.Code}, true, nil
+	}
+
+	if isAdmin {
+		return Info{}, false, nil
+	}
+
+	// 未指定租户时使用用户加入的第一个启用租户
+	var t models.Tenant
+	err := s.db.Model(&models.Tenant{}).
+		Joins("JOIN tenant_members ON tenant_members.tenant_id = tenants.id").
+		Where("tenant_members.username = ? AND tenants.status = ?", username, models.TenantStatusActive).
+		Order("tenant_members.created_at ASC").
+		First(&t).Error
+	if err == nil {
+		return Info{ID: t.ID, Code: t.Code}, true, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return Info{}, false, err
+	}
+	return Info{ID: models.DefaultTenantID, Code: models.DefaultTenantID}, true, nil
+}
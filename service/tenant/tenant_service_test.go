@@ -0,0 +1,169 @@
+/*
+ * @module service/tenant/tenant_service_test
+ * @description 租户隔离测试，覆盖GORM回调的租户填充与过滤、内置模板共享、租户解析和schema命名
+ * @architecture 测试层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow 准备sqlite并注册租户回调 -> 以不同租户context读写 -> 验证隔离结果
+ * @rules 使用内存sqlite，不依赖外部服务
+ * @dependencies gorm.io/driver/sqlite, stretchr/testify
+ * @refs service/tenant/gorm_callback.go, service/tenant/tenant_service.go
+ */
+
+package tenant
+
+import (
+	"context"
+	"datahub-service/service/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+func setupTenantDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: gormlogger.Default.LogMode(gormlogger.Silent),
+	})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&models.Tenant{}, &models.TenantMember{}, &models.BasicLibrary{}, &models.DataCleansingTemplate{}))
+	require.NoError(t, RegisterGormCallbacks(db))
+	return db
+}
+
+func createTenant(t *testing.T, s *Service, code string) *models.Tenant {
+	tn := &models.Tenant{Code: code, Name: code}
+	require.NoError(t, s.CreateTenant(tn))
+	return tn
+}
+
+func TestGormCallbacksIsolateTenants(t *testing.T) {
+	db := setupTenantDB(t)
+	ctxA := WithTenant(context.Background(), Info{ID: "tenant-a", Code: "park_a"})
+	ctxB := WithTenant(context.Background(), Info{ID: "tenant-b", Code: "park_b"})
+
+	libA := &models.BasicLibrary{NameZh: "A库", NameEn: "lib_a"}
+	require.NoError(t, db.WithContext(ctxA).Create(libA).Error)
+	assert.Equal(t, "tenant-a", libA.TenantID)
+	require.NoError(t, db.WithContext(ctxB).Create(&models.BasicLibrary{NameZh: "B库", NameEn: "lib_b"}).Error)
+
+	// 列表和计数只返回本租户数据
+	var libs []models.BasicLibrary
+	var total int64
+	query := db.WithContext(ctxA).Model(&models.BasicLibrary{})
+	require.NoError(t, query.Count(&total).Error)
+	require.NoError(t, query.Find(&libs).Error)
+	assert.Equal(t, int64(1), total)
+	require.Len(t, libs, 1)
+	assert.Equal(t, "lib_a", libs[0].NameEn)
+
+	// 其他租户按ID查询、更新、删除均不可见
+	var found models.BasicLibrary
+	err := db.WithContext(ctxB).First(&found, "id = ?", libA.ID).Error
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+
+	result := db.WithContext(ctxB).Model(&models.BasicLibrary{}).Where("id = ?", libA.ID).Update("name_zh", "篡改")
+	require.NoError(t, result.Error)
+	assert.Equal(t, int64(0), result.RowsAffected)
+
+	result = db.WithContext(ctxB).Delete(&models.BasicLibrary{}, "id = ?", libA.ID)
+	require.NoError(t, result.Error)
+	assert.Equal(t, int64(0), result.RowsAffected)
+
+	// 不限定租户的context可以看到全部数据
+	require.NoError(t, db.Model(&models.BasicLibrary{}).Count(&total).Error)
+	assert.Equal(t, int64(2), total)
+}
+
+func TestGormCallbacksShareBuiltInTemplates(t *testing.T) {
+	db := setupTenantDB(t)
+	ctxA := WithTenant(context.Background(), Info{ID: "tenant-a", Code: "park_a"})
+
+	builtIn := &models.DataCleansingTemplate{ID: "builtin-1", Name: "去空格", RuleType: "standardization", IsBuiltIn: true}
+	require.NoError(t, db.Create(builtIn).Error)
+	require.NoError(t, db.WithContext(ctxA).Create(&models.DataCleansingTemplate{ID: "own-1", Name: "自定义", RuleType: "validation"}).Error)
+	require.NoError(t, db.Create(&models.DataCleansingTemplate{ID: "other-1", Name: "默认租户规则", RuleType: "validation"}).Error)
+
+	var templates []models.DataCleansingTemplate
+	require.NoError(t, db.WithContext(ctxA).Where("rule_type IN ?", []string{"standardization", "validation"}).Order("id").Find(&templates).Error)
+	require.Len(t, templates, 2)
+	assert.Equal(t, "builtin-1", templates[0].ID)
+	assert.Equal(t, "own-1", templates[1].ID)
+
+	// 内置模板可见但不可修改
+	result := db.WithContext(ctxA).Model(&models.DataCleansingTemplate{}).Where("id = ?", "builtin-1").Update("name", "篡改")
+	require.NoError(t, result.Error)
+	assert.Equal(t, int64(0), result.RowsAffected)
+}
+
+func TestResolveTenant(t *testing.T) {
+	db := setupTenantDB(t)
+	s := NewService(db)
+	require.NoError(t, s.EnsureDefaultTenant())
+	parkA := createTenant(t, s, "park_a")
+	parkB := createTenant(t, s, "park_b")
+	_, err := s.AddMember(parkA.ID, "alice", "admin")
+	require.NoError(t, err)
+
+	// 成员未指定租户时使用所属租户，可按编码指定
+	info, scoped, err := s.ResolveTenant("alice", false, "")
+	require.NoError(t, err)
+	assert.True(t, scoped)
+	assert.Equal(t, parkA.ID, info.ID)
+
+	info, _, err = s.ResolveTenant("alice", false, "park_a")
+	require.NoError(t, err)
+	assert.Equal(t, "park_a", info.Code)
+
+	// 访问未加入的租户被拒绝
+	_, _, err = s.ResolveTenant("alice", false, parkB.ID)
+	assert.ErrorIs(t, err, ErrTenantForbidden)
+
+	// 未加入任何租户的用户归属默认租户
+	info, scoped, err = s.ResolveTenant("bob", false, "")
+	require.NoError(t, err)
+	assert.True(t, scoped)
+	assert.Equal(t, models.DefaultTenantID, info.ID)
+
+	// 管理员不指定时不限定租户，可指定任意租户
+	_, scoped, err = s.ResolveTenant("root", true, "")
+	require.NoError(t, err)
+	assert.False(t, scoped)
+	info, _, err = s.ResolveTenant("root", true, "park_b")
+	require.NoError(t, err)
+	assert.Equal(t, parkB.ID, info.ID)
+
+	// 停用的租户不可访问
+	require.NoError(t, s.UpdateTenant(parkA.ID, map[string]interface{}{"status": models.TenantStatusDisabled}))
+	_, _, err = s.ResolveTenant("alice", false, "park_a")
+	assert.ErrorIs(t, err, ErrTenantDisabled)
+
+	_, _, err = s.ResolveTenant("alice", false, "unknown")
+	assert.ErrorIs(t, err, ErrTenantNotFound)
+}
+
+func TestTenantManagementRules(t *testing.T) {
+	db := setupTenantDB(t)
+	s := NewService(db)
+	require.NoError(t, s.EnsureDefaultTenant())
+
+	assert.Error(t, s.CreateTenant(&models.Tenant{Code: "Park-A", Name: "非法编码"}))
+	parkA := createTenant(t, s, "park_a")
+	assert.Error(t, s.CreateTenant(&models.Tenant{Code: "park_a", Name: "重复编码"}))
+
+	assert.Error(t, s.DeleteTenant(models.DefaultTenantID))
+	assert.Error(t, s.UpdateTenant(models.DefaultTenantID, map[string]interface{}{"status": models.TenantStatusDisabled}))
+
+	// 租户下有基础库时不能删除
+	ctxA := WithTenant(context.Background(), Info{ID: parkA.ID, Code: parkA.Code})
+	require.NoError(t, db.WithContext(ctxA).Create(&models.BasicLibrary{NameZh: "A库", NameEn: "lib_a"}).Error)
+	assert.Error(t, s.DeleteTenant(parkA.ID))
+}
+
+func TestSchemaName(t *testing.T) {
+	assert.Equal(t, "lib_a", SchemaName(context.Background(), "lib_a"))
+	assert.Equal(t, "lib_a", SchemaName(WithTenant(context.Background(), Info{ID: models.DefaultTenantID, Code: models.DefaultTenantID}), "lib_a"))
+	assert.Equal(t, "park_a_lib_a", SchemaName(WithTenant(context.Background(), Info{ID: "tenant-a", Code: "park_a"}), "lib_a"))
+}
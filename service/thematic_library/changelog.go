@@ -0,0 +1,122 @@
+/*
+ * @module service/thematic_library/changelog
+ * @description 主题接口变更日志策略管理，开启后主题同步在写入时把新增、修改、删除的主键和修改列记入接口的变更日志表，下游通过共享API按序号拉取增量
+ * @architecture 分层架构 - 业务服务层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow 开启变更日志 -> 建立变更日志表 -> 主题同步写入时记录变更 -> 同步后清理超出保留天数的记录；删除策略时删除变更日志表
+ * @rules 只有PostgreSQL存储、配置了主键的table类型接口可以开启；保留天数为0时使用默认值；关闭时保留已有的变更日志，删除策略时一并删除变更日志表
+ * @dependencies gorm.io/gorm, datahub-service/service/models, datahub-service/service/thematic_library/thematic_sync
+ * @refs service/models/thematic_changelog.go, thematic_sync/changelog_recorder.go, api/controllers/thematic_changelog_controller.go
+ */
+
+package thematic_library
+
+import (
+	"context"
+	"datahub-service/service/models"
+	"datahub-service/service/thematic_library/thematic_sync"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"gorm.io/gorm"
+)
+
+const (
+	defaultChangelogRetentionDays = 7  // 默认保留天数
+	maxChangelogRetentionDays     = 90 // 最多保留天数
+)
+
+// ErrInvalidChangelogPolicy 变更日志策略不合法
+var ErrInvalidChangelogPolicy = errors.New("变更日志策略不合法")
+
+// GetChangelogPolicy 获取主题接口的变更日志策略，未配置时返回默认策略（不开启）
+func (s *Service) GetChangelogPolicy(ctx context.Context, interfaceID string) (*models.ThematicChangelogPolicy, error) {
+	if _, err := s.getPublicationInterface(ctx, interfaceID); err != nil {
+		return nil, err
+	}
+	policy := models.ThematicChangelogPolicy{ThematicInterfaceID: interfaceID, RetentionDays: defaultChangelogRetentionDays}
+	if err := s.db.WithContext(ctx).Where("thematic_interface_id = ?", interfaceID).Limit(1).Find(&policy).Error; err != nil {
+		return nil, fmt.Errorf("查询变更日志策略失败: %w", err)
+	}
+	return &policy, nil
+}
+
+// UpdateChangelogPolicy 保存主题接口的变更日志策略，开启时建立变更日志表
+func (s *Service) UpdateChangelogPolicy(ctx context.Context, interfaceID string, enabled bool, retentionDays int, operator string) (*models.ThematicChangelogPolicy, error) {
+	iface, err := s.getPublicationInterface(ctx, interfaceID)
+	if err != nil {
+		return nil, err
+	}
+	if retentionDays < 0 || retentionDays > maxChangelogRetentionDays {
+		return nil, fmt.Errorf("%w: 保留天数须在1-%d之间", ErrInvalidChangelogPolicy, maxChangelogRetentionDays)
+	}
+	if retentionDays == 0 {
+		retentionDays = defaultChangelogRetentionDays
+	}
+	if enabled {
+		if err := validateChangelogInterface(iface); err != nil {
+			return nil, err
+		}
+		if err := thematic_sync.EnsureChangelogTable(ctx, s.db, interfaceID); err != nil {
+			return nil, err
+		}
+	}
+
+	policy := &models.ThematicChangelogPolicy{
+		ThematicInterfaceID: interfaceID,
+		Enabled:             enabled,
+		RetentionDays:       retentionDays,
+		CreatedBy:           operator,
+		UpdatedBy:           operator,
+	}
+	var existing models.ThematicChangelogPolicy
+	err = s.db.WithContext(ctx).First(&existing, "thematic_interface_id = ?", interfaceID).Error
+	switch {
+	case err == nil:
+		policy.CreatedBy = existing.CreatedBy
+		policy.CreatedAt = existing.CreatedAt
+	case !errors.Is(err, gorm.ErrRecordNotFound):
+		return nil, err
+	}
+	if err := s.db.WithContext(ctx).Save(policy).Error; err != nil {
+		return nil, fmt.Errorf("保存变更日志策略失败: %w", err)
+	}
+
+	slog.Info("设置主题接口变更日志策略", "interface_id", interfaceID, "enabled", enabled, "retention_days", retentionDays, "operator", operator)
+	return policy, nil
+}
+
+// DeleteChangelogPolicy 删除主题接口的变更日志策略和变更日志表
+func (s *Service) DeleteChangelogPolicy(ctx context.Context, interfaceID, operator string) error {
+	if _, err := s.getPublicationInterface(ctx, interfaceID); err != nil {
+		return err
+	}
+	result := s.db.WithContext(ctx).Where("thematic_interface_id = ?", interfaceID).Delete(&models.ThematicChangelogPolicy{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	if err := thematic_sync.DropChangelogTable(ctx, s.db, interfaceID); err != nil {
+		return err
+	}
+
+	slog.Info("删除主题接口变更日志策略", "interface_id", interfaceID, "operator", operator)
+	return nil
+}
+
+// validateChangelogInterface 校验主题接口可以开启变更日志
+func validateChangelogInterface(iface *models.ThematicInterface) error {
+	if iface.Type != "table" {
+		return fmt.Errorf("%w: 只有table类型的接口可以开启变更日志", ErrInvalidChangelogPolicy)
+	}
+	if iface.IsClickHouse() {
+		return fmt.Errorf("%w: ClickHouse接口表不支持变更日志", ErrInvalidChangelogPolicy)
+	}
+	if len(thematic_sync.GetThematicPrimaryKeyFields(iface)) == 0 {
+		return fmt.Errorf("%w: 接口没有配置主键字段", ErrInvalidChangelogPolicy)
+	}
+	return nil
+}
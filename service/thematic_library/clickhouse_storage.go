@@ -0,0 +1,56 @@
+/*
+ * @module service/thematic_library/clickhouse_storage
+ * @description 主题接口ClickHouse存储，校验存储引擎配置并在ClickHouse中维护接口表结构
+ * @architecture 分层架构 - 业务服务层
+ * @documentReference ai_docs/thematic_sync_design.md
+ * @stateFlow 创建接口校验存储引擎 -> 更新字段配置时建表/补列 -> 删除接口时删表
+ * @rules 只有table类型接口可以使用ClickHouse存储，且需要配置CLICKHOUSE_URL；主题库schema名作为ClickHouse数据库名
+ * @dependencies datahub-service/service/clickhouse, datahub-service/service/models
+ * @refs service.go, service/clickhouse/ddl.go
+ */
+
+package thematic_library
+
+import (
+	"context"
+	"datahub-service/service/clickhouse"
+	"datahub-service/service/models"
+	"errors"
+)
+
+// validateStorageEngine 校验并补全接口的存储引擎
+func validateStorageEngine(thematicInterface *models.ThematicInterface) error {
+	switch thematicInterface.StorageEngine {
+	case "":
+		thematicInterface.StorageEngine = models.StorageEnginePostgres
+	case models.StorageEnginePostgres:
+	case models.StorageEngineClickHouse:
+		if thematicInterface.Type != "table" {
+			return errors.New("只有table类型的接口可以使用ClickHouse存储")
+		}
+		if clickhouse.Default() == nil {
+			return errors.New("未配置ClickHouse，无法使用ClickHouse存储")
+		}
+	default:
+		return errors.New("无效的存储引擎，支持: postgres, clickhouse")
+	}
+	return nil
+}
+
+// ensureClickHouseTable 在ClickHouse中创建接口表并补充新增字段
+func (s *Service) ensureClickHouseTable(database, table string, fields []models.TableField) error {
+	client := clickhouse.Default()
+	if client == nil {
+		return errors.New("未配置ClickHouse")
+	}
+	return client.EnsureTable(context.Background(), database, table, fields)
+}
+
+// dropClickHouseTable 删除ClickHouse中的接口表
+func (s *Service) dropClickHouseTable(database, table string) error {
+	client := clickhouse.Default()
+	if client == nil {
+		return errors.New("未配置ClickHouse")
+	}
+	return client.DropTable(context.Background(), database, table)
+}
@@ -6,6 +6,7 @@
 package thematic_library
 
 import (
+	"context"
 	"datahub-service/service/models"
 	"fmt"
 	"testing"
@@ -30,7 +31,7 @@ func TestDeleteTableInterface(t *testing.T) {
 		AccessLevel:     "internal",
 		UpdateFrequency: "daily",
 	}
-	err := service.CreateThematicLibrary(library)
+	err := service.CreateThematicLibrary(context.Background(), library)
 	require.NoError(t, err)
 
 	// 创建table类型接口
@@ -87,7 +88,7 @@ func TestDeleteViewInterface(t *testing.T) {
 		AccessLevel:     "internal",
 		UpdateFrequency: "daily",
 	}
-	err := service.CreateThematicLibrary(library)
+	err := service.CreateThematicLibrary(context.Background(), library)
 	require.NoError(t, err)
 
 	// 创建基础表
@@ -161,7 +162,7 @@ func TestDeleteInterfaceWithoutDatabaseObject(t *testing.T) {
 		AccessLevel:     "internal",
 		UpdateFrequency: "daily",
 	}
-	err := service.CreateThematicLibrary(library)
+	err := service.CreateThematicLibrary(context.Background(), library)
 	require.NoError(t, err)
 
 	// 创建table接口但不创建表
@@ -219,7 +220,7 @@ func TestDeleteInterfaceWithRelations(t *testing.T) {
 		AccessLevel:     "internal",
 		UpdateFrequency: "daily",
 	}
-	err := service.CreateThematicLibrary(library)
+	err := service.CreateThematicLibrary(context.Background(), library)
 	require.NoError(t, err)
 
 	// 创建接口
@@ -274,7 +275,7 @@ func TestDeleteMultipleInterfaces(t *testing.T) {
 		AccessLevel:     "internal",
 		UpdateFrequency: "daily",
 	}
-	err := service.CreateThematicLibrary(library)
+	err := service.CreateThematicLibrary(context.Background(), library)
 	require.NoError(t, err)
 
 	// 创建多个table接口
@@ -355,7 +356,7 @@ func TestDeleteInterfaceCascade(t *testing.T) {
 		AccessLevel:     "internal",
 		UpdateFrequency: "daily",
 	}
-	err := service.CreateThematicLibrary(library)
+	err := service.CreateThematicLibrary(context.Background(), library)
 	require.NoError(t, err)
 
 	// 创建基础表
@@ -6,6 +6,7 @@
 package thematic_library
 
 import (
+	"context"
 	"datahub-service/service/models"
 	"fmt"
 	"os"
@@ -72,7 +73,7 @@ func TestInterfaceTypeValidation(t *testing.T) {
 		AccessLevel:     "internal",
 		UpdateFrequency: "daily",
 	}
-	err := service.CreateThematicLibrary(library)
+	err := service.CreateThematicLibrary(context.Background(), library)
 	require.NoError(t, err, "创建主题库应该成功")
 
 	// 测试创建table类型接口
@@ -135,7 +136,7 @@ func TestTableInterfaceOperations(t *testing.T) {
 		AccessLevel:     "internal",
 		UpdateFrequency: "daily",
 	}
-	err := service.CreateThematicLibrary(library)
+	err := service.CreateThematicLibrary(context.Background(), library)
 	require.NoError(t, err)
 
 	// 创建table类型接口
@@ -194,7 +195,7 @@ func TestViewInterfaceOperations(t *testing.T) {
 		AccessLevel:     "internal",
 		UpdateFrequency: "daily",
 	}
-	err := service.CreateThematicLibrary(library)
+	err := service.CreateThematicLibrary(context.Background(), library)
 	require.NoError(t, err)
 
 	// 先创建基础表
@@ -261,7 +262,7 @@ func TestCrossTypeOperations(t *testing.T) {
 		AccessLevel:     "internal",
 		UpdateFrequency: "daily",
 	}
-	err := service.CreateThematicLibrary(library)
+	err := service.CreateThematicLibrary(context.Background(), library)
 	require.NoError(t, err)
 
 	// 创建table类型接口
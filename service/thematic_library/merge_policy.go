@@ -0,0 +1,225 @@
+/*
+ * @module service/thematic_library/merge_policy
+ * @description 主题表多源写入合并策略管理，提供合并策略的查询、设置、删除以及合并冲突的查询和评审
+ * @architecture 分层架构 - 业务服务层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow 设置合并策略 -> 同步引擎按策略写入并记录冲突 -> 查询待评审冲突 -> 确认或忽略
+ * @rules 策略中的任务须是写入该主题接口的同步任务；latest_timestamp须指定接口中存在的时间字段；field_precedence的字段须是接口中的非主键字段；
+ *        删除策略时同时清除记录来源，保留冲突记录；只有待评审的冲突能被确认或忽略
+ * @dependencies gorm.io/gorm, datahub-service/service/models
+ * @refs service/models/thematic_merge.go, thematic_sync/merge_resolver.go, api/controllers/thematic_merge_controller.go
+ */
+
+package thematic_library
+
+import (
+	"context"
+	"datahub-service/service/models"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/spf13/cast"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// 合并策略错误
+var (
+	// ErrInvalidMergePolicy 合并策略配置不合法
+	ErrInvalidMergePolicy = errors.New("合并策略配置不合法")
+	// ErrMergeConflictReviewed 冲突已评审
+	ErrMergeConflictReviewed = errors.New("合并冲突已评审")
+)
+
+// GetMergePolicy 获取主题接口的合并策略
+func (s *Service) GetMergePolicy(ctx context.Context, interfaceID string) (*models.ThematicMergePolicy, error) {
+	if _, err := s.getPublicationInterface(ctx, interfaceID); err != nil {
+		return nil, err
+	}
+	var policy models.ThematicMergePolicy
+	if err := s.db.WithContext(ctx).First(&policy, "thematic_interface_id = ?", interfaceID).Error; err != nil {
+		return nil, err
+	}
+	return &policy, nil
+}
+
+// UpdateMergePolicy 设置主题接口的合并策略，不存在时创建
+func (s *Service) UpdateMergePolicy(ctx context.Context, interfaceID string, policy *models.ThematicMergePolicy, operator string) (*models.ThematicMergePolicy, error) {
+	iface, err := s.getPublicationInterface(ctx, interfaceID)
+	if err != nil {
+		return nil, err
+	}
+	var taskIDs []string
+	if err := s.db.WithContext(ctx).Model(&models.ThematicSyncTask{}).
+		Where("thematic_interface_id = ?", interfaceID).Pluck("id", &taskIDs).Error; err != nil {
+		return nil, fmt.Errorf("获取主题接口的同步任务失败: %w", err)
+	}
+	if err := validateMergePolicy(policy, s.extractFieldsFromConfig(iface.TableFieldsConfig), taskIDs); err != nil {
+		return nil, err
+	}
+
+	policy.ThematicInterfaceID = interfaceID
+	policy.UpdatedBy = operator
+	var existing models.ThematicMergePolicy
+	err = s.db.WithContext(ctx).First(&existing, "thematic_interface_id = ?", interfaceID).Error
+	switch {
+	case err == nil:
+		policy.CreatedBy = existing.CreatedBy
+		policy.CreatedAt = existing.CreatedAt
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		policy.CreatedBy = operator
+	default:
+		return nil, err
+	}
+	if err := s.db.WithContext(ctx).Save(policy).Error; err != nil {
+		return nil, fmt.Errorf("保存合并策略失败: %w", err)
+	}
+
+	slog.Info("设置主题表合并策略", "interface_id", interfaceID, "strategy", policy.Strategy, "enabled", policy.Enabled, "operator", operator)
+	return policy, nil
+}
+
+// DeleteMergePolicy 删除主题接口的合并策略和记录来源，冲突记录保留
+func (s *Service) DeleteMergePolicy(ctx context.Context, interfaceID, operator string) error {
+	if _, err := s.getPublicationInterface(ctx, interfaceID); err != nil {
+		return err
+	}
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("thematic_interface_id = ?", interfaceID).Delete(&models.ThematicMergePolicy{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return tx.Where("thematic_interface_id = ?", interfaceID).Delete(&models.ThematicRecordSource{}).Error
+	})
+	if err != nil {
+		return err
+	}
+
+	slog.Info("删除主题表合并策略", "interface_id", interfaceID, "operator", operator)
+	return nil
+}
+
+// ListMergeConflicts 分页查询主题接口的合并冲突，按最近出现时间倒序
+func (s *Service) ListMergeConflicts(ctx context.Context, interfaceID, status string, page, size int) ([]models.ThematicMergeConflict, int64, error) {
+	if _, err := s.getPublicationInterface(ctx, interfaceID); err != nil {
+		return nil, 0, err
+	}
+	query := s.db.WithContext(ctx).Model(&models.ThematicMergeConflict{}).Where("thematic_interface_id = ?", interfaceID)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	var conflicts []models.ThematicMergeConflict
+	err := query.Order("last_seen_at DESC").Offset((page - 1) * size).Limit(size).Find(&conflicts).Error
+	return conflicts, total, err
+}
+
+// ReviewMergeConflict 评审合并冲突，status为confirmed或dismissed
+func (s *Service) ReviewMergeConflict(ctx context.Context, interfaceID, conflictID, status, reviewer, note string) (*models.ThematicMergeConflict, error) {
+	if status != models.MergeConflictConfirmed && status != models.MergeConflictDismissed {
+		return nil, fmt.Errorf("%w: 评审结果只能是%s或%s", ErrInvalidMergePolicy, models.MergeConflictConfirmed, models.MergeConflictDismissed)
+	}
+	if _, err := s.getPublicationInterface(ctx, interfaceID); err != nil {
+		return nil, err
+	}
+
+	var conflict models.ThematicMergeConflict
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			First(&conflict, "id = ? AND thematic_interface_id = ?", conflictID, interfaceID).Error; err != nil {
+			return err
+		}
+		if conflict.Status != models.MergeConflictOpen {
+			return fmt.Errorf("%w: 当前状态为%s", ErrMergeConflictReviewed, conflict.Status)
+		}
+		now := time.Now()
+		conflict.Status = status
+		conflict.ReviewedBy = reviewer
+		conflict.ReviewNote = note
+		conflict.ReviewedAt = &now
+		return tx.Save(&conflict).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &conflict, nil
+}
+
+// validateMergePolicy 校验合并策略，fields为主题接口字段配置，taskIDs为写入该接口的同步任务
+func validateMergePolicy(policy *models.ThematicMergePolicy, fields []models.TableField, taskIDs []string) error {
+	fieldByName := make(map[string]models.TableField, len(fields))
+	for _, field := range fields {
+		fieldByName[field.NameEn] = field
+	}
+	isTask := make(map[string]bool, len(taskIDs))
+	for _, id := range taskIDs {
+		isTask[id] = true
+	}
+	checkTasks := func(ids []string) error {
+		seen := make(map[string]bool, len(ids))
+		for _, id := range ids {
+			if !isTask[id] {
+				return fmt.Errorf("%w: 同步任务%s不写入该主题接口", ErrInvalidMergePolicy, id)
+			}
+			if seen[id] {
+				return fmt.Errorf("%w: 同步任务%s重复", ErrInvalidMergePolicy, id)
+			}
+			seen[id] = true
+		}
+		return nil
+	}
+	if err := checkTasks(policy.SourcePriority); err != nil {
+		return err
+	}
+
+	switch policy.Strategy {
+	case models.MergeStrategySourcePriority:
+		if len(policy.SourcePriority) == 0 {
+			return fmt.Errorf("%w: 按任务优先级合并须指定source_priority", ErrInvalidMergePolicy)
+		}
+		policy.TimestampField, policy.FieldPrecedence = "", nil
+	case models.MergeStrategyLatestTimestamp:
+		field, ok := fieldByName[policy.TimestampField]
+		if !ok {
+			return fmt.Errorf("%w: 时间字段%s不存在", ErrInvalidMergePolicy, policy.TimestampField)
+		}
+		if field.IsPrimaryKey {
+			return fmt.Errorf("%w: 时间字段不能是主键", ErrInvalidMergePolicy)
+		}
+		policy.FieldPrecedence = nil
+	case models.MergeStrategyFieldPrecedence:
+		if len(policy.FieldPrecedence) == 0 && len(policy.SourcePriority) == 0 {
+			return fmt.Errorf("%w: 按字段合并须指定field_precedence或source_priority", ErrInvalidMergePolicy)
+		}
+		for name, order := range policy.FieldPrecedence {
+			field, ok := fieldByName[name]
+			if !ok {
+				return fmt.Errorf("%w: 字段%s不存在", ErrInvalidMergePolicy, name)
+			}
+			if field.IsPrimaryKey {
+				return fmt.Errorf("%w: 主键字段%s不参与合并", ErrInvalidMergePolicy, name)
+			}
+			ids, err := cast.ToStringSliceE(order)
+			if err != nil || len(ids) == 0 {
+				return fmt.Errorf("%w: 字段%s的任务优先级须为非空的任务ID列表", ErrInvalidMergePolicy, name)
+			}
+			if err := checkTasks(ids); err != nil {
+				return err
+			}
+			policy.FieldPrecedence[name] = ids
+		}
+		policy.TimestampField = ""
+	default:
+		return fmt.Errorf("%w: 未知合并策略%s", ErrInvalidMergePolicy, policy.Strategy)
+	}
+	return nil
+}
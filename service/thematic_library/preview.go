@@ -0,0 +1,78 @@
+/*
+ * @module service/thematic_library/preview
+ * @description 主题接口预览，用接口同步任务的配置对少量样例执行获取、合并、映射和治理，返回每条输出记录的来源源记录和转换过程
+ * @architecture 分层架构 - 业务服务层
+ * @documentReference ai_docs/thematic_sync_design.md
+ * @stateFlow 校验接口 -> 确定同步任务 -> 按任务构建同步请求 -> 同步引擎预览 -> 返回预览报告
+ * @rules 接口只有一个同步任务时可不指定任务；有多个时须指定task_id；预览不写入数据、不创建执行记录、不要求任务处于可执行状态
+ * @dependencies gorm.io/gorm, datahub-service/service/thematic_library/thematic_sync, datahub-service/service/tenant
+ * @refs thematic_sync/preview.go, thematic_sync_service.go, api/controllers/thematic_preview_controller.go
+ */
+
+package thematic_library
+
+import (
+	"context"
+	"datahub-service/service/models"
+	"datahub-service/service/tenant"
+	"datahub-service/service/thematic_library/thematic_sync"
+	"errors"
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// ErrInvalidPreviewRequest 预览请求不合法
+var ErrInvalidPreviewRequest = errors.New("预览请求不合法")
+
+// PreviewInterface 用接口同步任务的配置预览少量样例的输出记录及其来源，taskID为空时取接口唯一的同步任务，limit为0时使用默认样例行数
+func (tss *ThematicSyncService) PreviewInterface(ctx context.Context, interfaceID, taskID string, limit int) (*thematic_sync.PreviewReport, error) {
+	if limit < 0 || limit > thematic_sync.MaxPreviewLimit {
+		return nil, fmt.Errorf("%w: 样例行数须在1-%d之间", ErrInvalidPreviewRequest, thematic_sync.MaxPreviewLimit)
+	}
+
+	var iface models.ThematicInterface
+	if err := tss.db.WithContext(ctx).Scopes(tenant.OwnerScope(ctx, "library_id", "thematic_libraries")).
+		First(&iface, "id = ?", interfaceID).Error; err != nil {
+		return nil, err
+	}
+
+	task, err := tss.getPreviewTask(ctx, interfaceID, taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	syncRequest, err := tss.buildSyncRequest(ctx, task, &ExecuteSyncTaskRequest{ExecutionType: "preview"})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPreviewRequest, err)
+	}
+	return tss.syncEngine.Preview(syncRequest, limit)
+}
+
+// getPreviewTask 获取预览使用的同步任务，未指定时取接口唯一的同步任务
+func (tss *ThematicSyncService) getPreviewTask(ctx context.Context, interfaceID, taskID string) (*models.ThematicSyncTask, error) {
+	if taskID != "" {
+		var task models.ThematicSyncTask
+		err := tss.db.WithContext(ctx).First(&task, "id = ? AND thematic_interface_id = ?", taskID, interfaceID).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w: 同步任务不存在或不属于该接口", ErrInvalidPreviewRequest)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("查询同步任务失败: %w", err)
+		}
+		return &task, nil
+	}
+
+	var tasks []models.ThematicSyncTask
+	if err := tss.db.WithContext(ctx).Where("thematic_interface_id = ?", interfaceID).Limit(2).Find(&tasks).Error; err != nil {
+		return nil, fmt.Errorf("查询同步任务失败: %w", err)
+	}
+	switch len(tasks) {
+	case 0:
+		return nil, fmt.Errorf("%w: 接口没有配置同步任务", ErrInvalidPreviewRequest)
+	case 1:
+		return &tasks[0], nil
+	default:
+		return nil, fmt.Errorf("%w: 接口有多个同步任务，请指定task_id", ErrInvalidPreviewRequest)
+	}
+}
@@ -0,0 +1,482 @@
+/*
+ * @module service/thematic_library/publication
+ * @description 主题接口发布流程，提供提交评审、审批、驳回、发布和撤销发布，发布前检查质量评分和敏感字段脱敏覆盖
+ * @architecture 分层架构 - 业务服务层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow draft -> 提交评审 -> in_review -> 审批人数达到要求且发布检查通过 -> published；驳回回到draft；撤销发布回到draft
+ * @rules 只有已发布的主题接口能被数据共享引用；提交人不能审批自己的申请；质量评分取接口同步任务最近一次成功执行的评分，视图接口不检查；敏感字段须全部被同步任务启用的脱敏规则覆盖；仍被启用的共享接口或灰度发布引用时不能撤销发布
+ * @dependencies gorm.io/gorm, datahub-service/service/eventlog
+ * @refs service/models/thematic_publication.go, api/controllers/thematic_publication_controller.go
+ */
+
+package thematic_library
+
+import (
+	"context"
+	"datahub-service/service/eventlog"
+	"datahub-service/service/models"
+	"datahub-service/service/tenant"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	// defaultPublishRequiredApprovals 发布所需的默认审批人数
+	defaultPublishRequiredApprovals = 1
+	// defaultPublishMinQualityScore 发布所需的默认最低质量评分
+	defaultPublishMinQualityScore = 80.0
+	// publicationReviewHistoryLimit 评审记录查询条数
+	publicationReviewHistoryLimit = 20
+)
+
+// 发布流程错误
+var (
+	// ErrPublicationStateInvalid 接口当前发布状态不允许该操作
+	ErrPublicationStateInvalid = errors.New("主题接口当前发布状态不允许该操作")
+	// ErrPublicationSelfApproval 提交人审批自己的申请
+	ErrPublicationSelfApproval = errors.New("提交人不能审批自己的发布申请")
+	// ErrPublicationNotApproved 审批人数不足
+	ErrPublicationNotApproved = errors.New("发布申请的审批人数不足")
+	// ErrPublicationGateFailed 未通过发布检查
+	ErrPublicationGateFailed = errors.New("主题接口未通过发布检查")
+	// ErrPublicationInUse 接口仍被数据共享引用
+	ErrPublicationInUse = errors.New("主题接口仍被数据共享引用")
+)
+
+// PublicationStatus 主题接口的发布状态概览
+type PublicationStatus struct {
+	InterfaceID       string                            `json:"interface_id"`
+	PublishStatus     string                            `json:"publish_status"`
+	RequiredApprovals int                               `json:"required_approvals"`
+	Review            *models.ThematicPublicationReview `json:"review,omitempty"` // 待处理的评审
+	Gates             *models.PublicationGateResult     `json:"gates"`            // 按当前配置和数据计算的发布检查结果
+}
+
+// publicationPolicyFromEnv 读取发布策略，可通过THEMATIC_PUBLISH_REQUIRED_APPROVALS、THEMATIC_PUBLISH_MIN_QUALITY_SCORE配置
+func publicationPolicyFromEnv() (int, float64) {
+	requiredApprovals := defaultPublishRequiredApprovals
+	if value, err := strconv.Atoi(os.Getenv("THEMATIC_PUBLISH_REQUIRED_APPROVALS")); err == nil && value > 0 {
+		requiredApprovals = value
+	}
+	minQualityScore := defaultPublishMinQualityScore
+	if value, err := strconv.ParseFloat(os.Getenv("THEMATIC_PUBLISH_MIN_QUALITY_SCORE"), 64); err == nil && value >= 0 && value <= 100 {
+		minQualityScore = value
+	}
+	return requiredApprovals, minQualityScore
+}
+
+// GetPublication 获取主题接口的发布状态、待处理评审和当前的发布检查结果
+func (s *Service) GetPublication(ctx context.Context, interfaceID string) (*PublicationStatus, error) {
+	iface, err := s.getPublicationInterface(ctx, interfaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	gates, err := s.EvaluatePublicationGates(ctx, iface)
+	if err != nil {
+		return nil, err
+	}
+	status := &PublicationStatus{
+		InterfaceID:       iface.ID,
+		PublishStatus:     iface.PublishStatus,
+		RequiredApprovals: s.publishRequiredApprovals,
+		Gates:             gates,
+	}
+
+	review, err := s.getPendingReview(s.db.WithContext(ctx), interfaceID)
+	if err == nil {
+		status.Review = review
+		status.RequiredApprovals = review.RequiredApprovals
+	} else if !errors.Is(err, ErrPublicationStateInvalid) {
+		return nil, err
+	}
+	return status, nil
+}
+
+// GetPublicationReviews 获取主题接口最近的发布评审记录
+func (s *Service) GetPublicationReviews(ctx context.Context, interfaceID string) ([]models.ThematicPublicationReview, error) {
+	if _, err := s.getPublicationInterface(ctx, interfaceID); err != nil {
+		return nil, err
+	}
+
+	var reviews []models.ThematicPublicationReview
+	err := s.db.WithContext(ctx).Where("thematic_interface_id = ?", interfaceID).
+		Order("submitted_at DESC").Limit(publicationReviewHistoryLimit).Find(&reviews).Error
+	return reviews, err
+}
+
+// SubmitForPublication 提交发布评审，接口进入评审中
+func (s *Service) SubmitForPublication(ctx context.Context, interfaceID, submitter, comment string) (*models.ThematicPublicationReview, error) {
+	iface, err := s.getPublicationInterface(ctx, interfaceID)
+	if err != nil {
+		return nil, err
+	}
+	if iface.PublishStatus != models.ThematicPublishStatusDraft {
+		return nil, fmt.Errorf("%w: 只有草稿状态的接口能提交发布评审，当前为%s", ErrPublicationStateInvalid, iface.PublishStatus)
+	}
+
+	gates, err := s.EvaluatePublicationGates(ctx, iface)
+	if err != nil {
+		return nil, err
+	}
+	review := &models.ThematicPublicationReview{
+		ThematicInterfaceID: interfaceID,
+		Status:              models.PublicationReviewPending,
+		RequiredApprovals:   s.publishRequiredApprovals,
+		Approvals:           models.PublicationApprovals{},
+		Gates:               gates,
+		Comment:             comment,
+		SubmittedBy:         submitter,
+		SubmittedAt:         time.Now(),
+	}
+
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := updatePublishStatus(tx, interfaceID, models.ThematicPublishStatusDraft, models.ThematicPublishStatusInReview, submitter); err != nil {
+			return err
+		}
+		return tx.Create(review).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	eventlog.Record(ctx, eventlog.Event{
+		Type:       eventlog.EventPublicationReviewRequested,
+		Message:    "主题接口提交发布评审",
+		ObjectType: "thematic_interface",
+		ObjectID:   interfaceID,
+		Attributes: map[string]interface{}{
+			"review_id":          review.ID,
+			"submitted_by":       submitter,
+			"required_approvals": review.RequiredApprovals,
+			"gates_passed":       gates.Passed,
+		},
+	})
+	return review, nil
+}
+
+// ApprovePublication 审批发布申请，同一审批人只计一次
+func (s *Service) ApprovePublication(ctx context.Context, interfaceID, reviewer, comment string) (*models.ThematicPublicationReview, error) {
+	if _, err := s.getPublicationInterface(ctx, interfaceID); err != nil {
+		return nil, err
+	}
+
+	var review *models.ThematicPublicationReview
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var err error
+		review, err = s.getPendingReview(tx, interfaceID)
+		if err != nil {
+			return err
+		}
+		if reviewer == review.SubmittedBy {
+			return ErrPublicationSelfApproval
+		}
+		if review.HasApproved(reviewer) {
+			return fmt.Errorf("%w: %s已审批该申请", ErrPublicationStateInvalid, reviewer)
+		}
+
+		review.Approvals = append(review.Approvals, models.PublicationApproval{Reviewer: reviewer, Comment: comment, ApprovedAt: time.Now()})
+		return updatePendingReview(tx, review, map[string]interface{}{"approvals": review.Approvals})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return review, nil
+}
+
+// RejectPublication 驳回发布申请，接口回到草稿状态
+func (s *Service) RejectPublication(ctx context.Context, interfaceID, reviewer, reason string) (*models.ThematicPublicationReview, error) {
+	if _, err := s.getPublicationInterface(ctx, interfaceID); err != nil {
+		return nil, err
+	}
+
+	var review *models.ThematicPublicationReview
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var err error
+		review, err = s.getPendingReview(tx, interfaceID)
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		review.Status = models.PublicationReviewRejected
+		review.RejectedBy = reviewer
+		review.RejectReason = reason
+		review.ClosedAt = &now
+		if err := updatePendingReview(tx, review, map[string]interface{}{
+			"status":        review.Status,
+			"rejected_by":   reviewer,
+			"reject_reason": reason,
+			"closed_at":     now,
+		}); err != nil {
+			return err
+		}
+		return updatePublishStatus(tx, interfaceID, models.ThematicPublishStatusInReview, models.ThematicPublishStatusDraft, reviewer)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return review, nil
+}
+
+// PublishThematicInterface 审批人数达到要求且发布检查通过后发布接口；检查未通过时返回保存了检查结果的评审
+func (s *Service) PublishThematicInterface(ctx context.Context, interfaceID, operator string) (*models.ThematicPublicationReview, error) {
+	iface, err := s.getPublicationInterface(ctx, interfaceID)
+	if err != nil {
+		return nil, err
+	}
+	review, err := s.getPendingReview(s.db.WithContext(ctx), interfaceID)
+	if err != nil {
+		return nil, err
+	}
+	if len(review.Approvals) < review.RequiredApprovals {
+		return review, fmt.Errorf("%w: 需要%d人审批，当前%d人", ErrPublicationNotApproved, review.RequiredApprovals, len(review.Approvals))
+	}
+
+	gates, err := s.EvaluatePublicationGates(ctx, iface)
+	if err != nil {
+		return nil, err
+	}
+	review.Gates = gates
+	if !gates.Passed {
+		if err := updatePendingReview(s.db.WithContext(ctx), review, map[string]interface{}{"gates": gates}); err != nil {
+			return nil, err
+		}
+		return review, fmt.Errorf("%w: %s", ErrPublicationGateFailed, strings.Join(gates.Reasons, "；"))
+	}
+
+	now := time.Now()
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		review.Status = models.PublicationReviewPublished
+		review.PublishedBy = operator
+		review.ClosedAt = &now
+		if err := updatePendingReview(tx, review, map[string]interface{}{
+			"status":       review.Status,
+			"gates":        gates,
+			"published_by": operator,
+			"closed_at":    now,
+		}); err != nil {
+			return err
+		}
+		return updatePublishStatus(tx, interfaceID, models.ThematicPublishStatusInReview, models.ThematicPublishStatusPublished, operator)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	attributes := map[string]interface{}{
+		"review_id":        review.ID,
+		"published_by":     operator,
+		"approvals":        len(review.Approvals),
+		"masking_coverage": math.Round(gates.MaskingCoverage*100) / 100,
+	}
+	if gates.QualityScore != nil {
+		attributes["quality_score"] = math.Round(*gates.QualityScore*100) / 100
+	}
+	eventlog.Record(ctx, eventlog.Event{
+		Type:       eventlog.EventThematicInterfacePublished,
+		Message:    "主题接口已发布",
+		ObjectType: "thematic_interface",
+		ObjectID:   interfaceID,
+		Attributes: attributes,
+	})
+	return review, nil
+}
+
+// UnpublishThematicInterface 撤销发布，接口回到草稿状态；仍被启用的共享接口或进行中的灰度发布引用时不能撤销
+func (s *Service) UnpublishThematicInterface(ctx context.Context, interfaceID, operator, reason string) error {
+	iface, err := s.getPublicationInterface(ctx, interfaceID)
+	if err != nil {
+		return err
+	}
+	if iface.PublishStatus != models.ThematicPublishStatusPublished {
+		return fmt.Errorf("%w: 接口未发布", ErrPublicationStateInvalid)
+	}
+
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var sharedCount, releaseCount int64
+		if err := tx.Model(&models.ApiInterface{}).
+			Where("thematic_interface_id = ? AND status = ?", interfaceID, "active").
+			Count(&sharedCount).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&models.ApiInterfaceRelease{}).
+			Where("target_thematic_interface_id = ? AND status = ?", interfaceID, models.ApiInterfaceReleaseStatusCanary).
+			Count(&releaseCount).Error; err != nil {
+			return err
+		}
+		if sharedCount > 0 || releaseCount > 0 {
+			return fmt.Errorf("%w: %d个启用的共享接口、%d个进行中的灰度发布", ErrPublicationInUse, sharedCount, releaseCount)
+		}
+		return updatePublishStatus(tx, interfaceID, models.ThematicPublishStatusPublished, models.ThematicPublishStatusDraft, operator)
+	})
+	if err != nil {
+		return err
+	}
+
+	eventlog.Record(ctx, eventlog.Event{
+		Type:       eventlog.EventThematicInterfaceUnpublished,
+		Message:    "主题接口已撤销发布",
+		ObjectType: "thematic_interface",
+		ObjectID:   interfaceID,
+		Attributes: map[string]interface{}{"operator": operator, "reason": reason},
+	})
+	return nil
+}
+
+// EvaluatePublicationGates 按当前的字段配置、同步任务和执行记录计算发布检查结果
+func (s *Service) EvaluatePublicationGates(ctx context.Context, iface *models.ThematicInterface) (*models.PublicationGateResult, error) {
+	gates := &models.PublicationGateResult{
+		MinQualityScore: s.publishMinQualityScore,
+		SensitiveFields: []string{},
+		MaskedFields:    []string{},
+		UnmaskedFields:  []string{},
+		EvaluatedAt:     time.Now(),
+	}
+
+	var tasks []models.ThematicSyncTask
+	if err := s.db.WithContext(ctx).Select("id", "masking_rule_configs").
+		Where("thematic_interface_id = ?", iface.ID).Find(&tasks).Error; err != nil {
+		return nil, fmt.Errorf("查询主题同步任务失败: %w", err)
+	}
+
+	// 质量评分：视图接口没有同步任务，不检查
+	if iface.Type == "view" {
+		gates.QualityPassed = true
+	} else if len(tasks) > 0 {
+		taskIDs := make([]string, 0, len(tasks))
+		for _, task := range tasks {
+			taskIDs = append(taskIDs, task.ID)
+		}
+		var execution models.ThematicSyncExecution
+		err := s.db.WithContext(ctx).Select("id", "quality_score").
+			Where("task_id IN ? AND status = ?", taskIDs, "success").
+			Order("created_at DESC").First(&execution).Error
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("查询主题同步执行记录失败: %w", err)
+		}
+		if err == nil {
+			score := execution.QualityScore
+			gates.QualityScore = &score
+			gates.QualityExecutionID = execution.ID
+			gates.QualityPassed = score >= gates.MinQualityScore
+		}
+	}
+	if !gates.QualityPassed {
+		if gates.QualityScore == nil {
+			gates.Reasons = append(gates.Reasons, "接口没有成功的同步执行记录，无法评估质量评分")
+		} else {
+			gates.Reasons = append(gates.Reasons, fmt.Sprintf("质量评分%.2f低于%.2f", *gates.QualityScore, gates.MinQualityScore))
+		}
+	}
+
+	// 脱敏覆盖：敏感字段须出现在同步任务启用的脱敏规则的目标字段中
+	masked := make(map[string]bool)
+	for _, task := range tasks {
+		var configs []models.DataMaskingConfig
+		if bytes, err := json.Marshal(task.MaskingRuleConfigs); err == nil {
+			json.Unmarshal(bytes, &configs)
+		}
+		for _, config := range configs {
+			if !config.IsEnabled {
+				continue
+			}
+			for _, field := range config.TargetFields {
+				masked[field] = true
+			}
+		}
+	}
+	for _, field := range s.sortedInterfaceFields(iface.TableFieldsConfig) {
+		if !field.IsSensitive {
+			continue
+		}
+		gates.SensitiveFields = append(gates.SensitiveFields, field.NameEn)
+		if masked[field.NameEn] {
+			gates.MaskedFields = append(gates.MaskedFields, field.NameEn)
+		} else {
+			gates.UnmaskedFields = append(gates.UnmaskedFields, field.NameEn)
+		}
+	}
+	gates.MaskingCoverage = 100
+	if len(gates.SensitiveFields) > 0 {
+		gates.MaskingCoverage = float64(len(gates.MaskedFields)) * 100 / float64(len(gates.SensitiveFields))
+	}
+	gates.MaskingPassed = len(gates.UnmaskedFields) == 0
+	if !gates.MaskingPassed {
+		gates.Reasons = append(gates.Reasons, "敏感字段未配置脱敏: "+strings.Join(gates.UnmaskedFields, "、"))
+	}
+
+	gates.Passed = gates.QualityPassed && gates.MaskingPassed
+	return gates, nil
+}
+
+// getPublicationInterface 获取当前租户可见的主题接口
+func (s *Service) getPublicationInterface(ctx context.Context, interfaceID string) (*models.ThematicInterface, error) {
+	var iface models.ThematicInterface
+	if err := s.db.WithContext(ctx).Scopes(tenant.OwnerScope(ctx, "library_id", "thematic_libraries")).
+		First(&iface, "id = ?", interfaceID).Error; err != nil {
+		return nil, err
+	}
+	return &iface, nil
+}
+
+// getPendingReview 获取接口待处理的评审并加行锁，没有时返回ErrPublicationStateInvalid
+func (s *Service) getPendingReview(db *gorm.DB, interfaceID string) (*models.ThematicPublicationReview, error) {
+	var review models.ThematicPublicationReview
+	err := db.Clauses(clause.Locking{Strength: "UPDATE"}).Where("thematic_interface_id = ? AND status = ?", interfaceID, models.PublicationReviewPending).
+		Order("submitted_at DESC").First(&review).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("%w: 接口没有待处理的发布评审", ErrPublicationStateInvalid)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &review, nil
+}
+
+// sortedInterfaceFields 按order_num排序的表字段配置
+func (s *Service) sortedInterfaceFields(tableFieldsConfig models.JSONB) []models.TableField {
+	fields := s.extractFieldsFromConfig(tableFieldsConfig)
+	sort.SliceStable(fields, func(i, j int) bool { return fields[i].OrderNum < fields[j].OrderNum })
+	return fields
+}
+
+// updatePendingReview 更新仍处于待处理状态的评审，并发处理导致状态已变化时返回ErrPublicationStateInvalid
+func updatePendingReview(tx *gorm.DB, review *models.ThematicPublicationReview, updates map[string]interface{}) error {
+	result := tx.Model(&models.ThematicPublicationReview{}).
+		Where("id = ? AND status = ?", review.ID, models.PublicationReviewPending).
+		Updates(updates)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("%w: 发布评审已被处理", ErrPublicationStateInvalid)
+	}
+	return nil
+}
+
+// updatePublishStatus 按状态条件切换接口发布状态，并发处理导致状态已变化时返回ErrPublicationStateInvalid
+func updatePublishStatus(tx *gorm.DB, interfaceID, from, to, operator string) error {
+	result := tx.Model(&models.ThematicInterface{}).
+		Where("id = ? AND publish_status = ?", interfaceID, from).
+		Updates(map[string]interface{}{"publish_status": to, "updated_by": operator, "updated_at": time.Now()})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("%w: 接口发布状态已变化", ErrPublicationStateInvalid)
+	}
+	slog.Info("主题接口发布状态变更", "interface_id", interfaceID, "from", from, "to", to, "operator", operator)
+	return nil
+}
@@ -0,0 +1,156 @@
+/*
+ * @module service/thematic_library/publication_test
+ * @description 主题接口发布流程测试，覆盖提交评审、自审拦截、审批不足、质量评分和敏感字段脱敏检查、驳回、发布以及被共享接口引用时拦截撤销发布
+ * @architecture 测试层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow 准备sqlite中的主题库、接口和同步任务 -> 提交 -> 审批 -> 发布检查未通过 -> 补齐评分和脱敏 -> 发布 -> 撤销发布
+ * @rules 使用内存sqlite，不依赖外部服务
+ * @dependencies gorm.io/driver/sqlite, stretchr/testify
+ * @refs publication.go
+ */
+
+package thematic_library
+
+import (
+	"context"
+	"datahub-service/service/eventlog"
+	"datahub-service/service/models"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// setupPublicationDB 准备主题库、含敏感字段phone的表接口if-person及其同步任务task-person
+func setupPublicationDB(t *testing.T) (*gorm.DB, *Service) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(
+		&models.ThematicLibrary{}, &models.ThematicInterface{}, &models.ThematicSyncTask{}, &models.ThematicSyncExecution{},
+		&models.ThematicPublicationReview{}, &models.ApiInterface{}, &models.ApiInterfaceRelease{}, &models.ApplicationEvent{},
+	))
+
+	require.NoError(t, db.Create(&models.ThematicLibrary{ID: "lib-1", NameZh: "人口", NameEn: "population"}).Error)
+	require.NoError(t, db.Create(&models.ThematicInterface{
+		ID: "if-person", LibraryID: "lib-1", NameZh: "人员", NameEn: "person", Type: "table", Status: "active",
+		PublishStatus: models.ThematicPublishStatusDraft,
+		TableFieldsConfig: models.JSONB{
+			"field_0": map[string]interface{}{"name_en": "id", "data_type": "varchar", "is_primary_key": true, "order_num": 1},
+			"field_1": map[string]interface{}{"name_en": "phone", "data_type": "varchar", "is_sensitive": true, "order_num": 2},
+		},
+	}).Error)
+	require.NoError(t, db.Create(&models.ThematicSyncTask{
+		ID: "task-person", ThematicLibraryID: "lib-1", ThematicInterfaceID: "if-person", TaskName: "人员汇聚", TriggerType: "manual", Status: "active",
+	}).Error)
+
+	eventlog.SetDefault(eventlog.NewService(db))
+	t.Cleanup(func() { eventlog.SetDefault(nil) })
+
+	s := NewService(db)
+	s.publishRequiredApprovals = 1
+	s.publishMinQualityScore = 80
+	return db, s
+}
+
+func TestPublicationWorkflow(t *testing.T) {
+	db, s := setupPublicationDB(t)
+	ctx := context.Background()
+
+	_, err := s.ApprovePublication(ctx, "if-person", "bob", "")
+	assert.True(t, errors.Is(err, ErrPublicationStateInvalid), "未提交评审不能审批")
+
+	review, err := s.SubmitForPublication(ctx, "if-person", "alice", "首次发布")
+	require.NoError(t, err)
+	assert.Equal(t, models.PublicationReviewPending, review.Status)
+	require.NotNil(t, review.Gates)
+	assert.False(t, review.Gates.Passed)
+	assert.Nil(t, review.Gates.QualityScore, "没有成功的执行记录")
+	assert.Equal(t, []string{"phone"}, review.Gates.UnmaskedFields)
+
+	_, err = s.SubmitForPublication(ctx, "if-person", "alice", "")
+	assert.True(t, errors.Is(err, ErrPublicationStateInvalid), "评审中不能重复提交")
+
+	_, err = s.PublishThematicInterface(ctx, "if-person", "alice")
+	assert.True(t, errors.Is(err, ErrPublicationNotApproved))
+
+	_, err = s.ApprovePublication(ctx, "if-person", "alice", "")
+	assert.True(t, errors.Is(err, ErrPublicationSelfApproval))
+	_, err = s.ApprovePublication(ctx, "if-person", "bob", "口径已确认")
+	require.NoError(t, err)
+	_, err = s.ApprovePublication(ctx, "if-person", "bob", "")
+	assert.True(t, errors.Is(err, ErrPublicationStateInvalid), "同一审批人只计一次")
+
+	// 质量评分不足且敏感字段未脱敏，检查结果保存到评审
+	require.NoError(t, db.Create(&models.ThematicSyncExecution{ID: "exec-1", TaskID: "task-person", ExecutionType: "manual", Status: "success", QualityScore: 60}).Error)
+	review, err = s.PublishThematicInterface(ctx, "if-person", "bob")
+	assert.True(t, errors.Is(err, ErrPublicationGateFailed))
+	require.NotNil(t, review)
+	assert.Equal(t, 60.0, *review.Gates.QualityScore)
+	assert.False(t, review.Gates.QualityPassed)
+	assert.Equal(t, 0.0, review.Gates.MaskingCoverage)
+	assert.Len(t, review.Gates.Reasons, 2)
+
+	// 补齐评分和脱敏规则后发布
+	require.NoError(t, db.Create(&models.ThematicSyncExecution{
+		ID: "exec-2", TaskID: "task-person", ExecutionType: "manual", Status: "success", QualityScore: 92.5, CreatedAt: time.Now().Add(time.Minute),
+	}).Error)
+	require.NoError(t, db.Model(&models.ThematicSyncTask{}).Where("id = ?", "task-person").Update("masking_rule_configs", models.JSONBGenericArray{
+		map[string]interface{}{"template_id": "mask-phone", "target_fields": []string{"phone"}, "is_enabled": true},
+	}).Error)
+	review, err = s.PublishThematicInterface(ctx, "if-person", "bob")
+	require.NoError(t, err)
+	assert.Equal(t, models.PublicationReviewPublished, review.Status)
+	assert.True(t, review.Gates.Passed)
+	assert.Equal(t, "exec-2", review.Gates.QualityExecutionID)
+	assert.Equal(t, 100.0, review.Gates.MaskingCoverage)
+
+	status, err := s.GetPublication(ctx, "if-person")
+	require.NoError(t, err)
+	assert.Equal(t, models.ThematicPublishStatusPublished, status.PublishStatus)
+
+	var events int64
+	require.NoError(t, db.Model(&models.ApplicationEvent{}).Where("event_type = ? AND object_id = ?", eventlog.EventThematicInterfacePublished, "if-person").Count(&events).Error)
+	assert.Equal(t, int64(1), events)
+
+	// 被启用的共享接口引用时不能撤销发布
+	require.NoError(t, db.Create(&models.ApiInterface{ID: "api-1", ApiApplicationID: "app-1", ThematicInterfaceID: "if-person", Path: "person", Status: "active"}).Error)
+	err = s.UnpublishThematicInterface(ctx, "if-person", "bob", "下线")
+	assert.True(t, errors.Is(err, ErrPublicationInUse))
+
+	require.NoError(t, db.Model(&models.ApiInterface{}).Where("id = ?", "api-1").Update("status", "inactive").Error)
+	require.NoError(t, s.UnpublishThematicInterface(ctx, "if-person", "bob", "下线"))
+	status, err = s.GetPublication(ctx, "if-person")
+	require.NoError(t, err)
+	assert.Equal(t, models.ThematicPublishStatusDraft, status.PublishStatus)
+}
+
+func TestRejectPublicationReturnsToDraft(t *testing.T) {
+	_, s := setupPublicationDB(t)
+	ctx := context.Background()
+
+	_, err := s.SubmitForPublication(ctx, "if-person", "alice", "")
+	require.NoError(t, err)
+	review, err := s.RejectPublication(ctx, "if-person", "bob", "手机号需要脱敏")
+	require.NoError(t, err)
+	assert.Equal(t, models.PublicationReviewRejected, review.Status)
+	assert.Equal(t, "手机号需要脱敏", review.RejectReason)
+
+	status, err := s.GetPublication(ctx, "if-person")
+	require.NoError(t, err)
+	assert.Equal(t, models.ThematicPublishStatusDraft, status.PublishStatus)
+
+	reviews, err := s.GetPublicationReviews(ctx, "if-person")
+	require.NoError(t, err)
+	require.Len(t, reviews, 1)
+
+	// 驳回后可以重新提交
+	_, err = s.SubmitForPublication(ctx, "if-person", "alice", "已补充脱敏")
+	require.NoError(t, err)
+}
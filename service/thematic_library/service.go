@@ -12,8 +12,13 @@
 package thematic_library
 
 import (
+	"context"
+	"datahub-service/service/catalog_cache"
 	"datahub-service/service/database"
 	"datahub-service/service/models"
+	"datahub-service/service/rbac"
+	"datahub-service/service/tenant"
+	"datahub-service/service/thematic_library/thematic_sync"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -29,14 +34,20 @@ import (
 type Service struct {
 	db            *gorm.DB
 	schemaService *database.SchemaService
+
+	publishRequiredApprovals int     // 主题接口发布所需的审批人数
+	publishMinQualityScore   float64 // 主题接口发布所需的最低质量评分
 }
 
 // NewThematicLibraryService 创建数据主题库服务实例
 func NewService(db *gorm.DB) *Service {
 	schemaService := database.NewSchemaService(db)
+	requiredApprovals, minQualityScore := publicationPolicyFromEnv()
 	service := &Service{
-		db:            db,
-		schemaService: schemaService,
+		db:                       db,
+		schemaService:            schemaService,
+		publishRequiredApprovals: requiredApprovals,
+		publishMinQualityScore:   minQualityScore,
 	}
 	return service
 }
@@ -47,8 +58,8 @@ func (s *Service) GetSchemaService() *database.SchemaService {
 }
 
 // CreateThematicLibrary 创建数据主题库
-func (s *Service) CreateThematicLibrary(library *models.ThematicLibrary) error {
-	// 检查编码是否已存在
+func (s *Service) CreateThematicLibrary(ctx context.Context, library *models.ThematicLibrary) error {
+	// 检查编码是否已存在（英文名称全局唯一，不区分租户）
 	var existing models.ThematicLibrary
 	if err := s.db.Where("name_en = ?", library.NameEn).First(&existing).Error; err == nil {
 		return errors.New("主题库名称已存在")
@@ -82,21 +93,22 @@ func (s *Service) CreateThematicLibrary(library *models.ThematicLibrary) error {
 	if !contains(validFrequencies, library.UpdateFrequency) {
 		return errors.New("无效的更新频率")
 	}
-	if database.CheckSchemaExists(s.db, library.NameEn) {
+	library.SchemaName = tenant.SchemaName(ctx, library.NameEn)
+	if database.CheckSchemaExists(s.db, library.GetSchemaName()) {
 		return errors.New("主题库英文名称已存在")
 	}
-	err := database.CreateSchema(s.db, library.NameEn)
+	err := database.CreateSchema(s.db, library.GetSchemaName())
 	if err != nil {
 		return err
 	}
 
-	return s.db.Create(library).Error
+	return s.db.WithContext(ctx).Create(library).Error
 }
 
 // GetThematicLibrary 根据ID获取数据主题库
-func (s *Service) GetThematicLibrary(id string) (*models.ThematicLibrary, error) {
+func (s *Service) GetThematicLibrary(ctx context.Context, id string) (*models.ThematicLibrary, error) {
 	var library models.ThematicLibrary
-	err := s.db.Preload("Interfaces").First(&library, "id = ?", id).Error
+	err := s.db.WithContext(ctx).Preload("Interfaces").First(&library, "id = ?", id).Error
 	if err != nil {
 		return nil, fmt.Errorf("获取主题库失败: %w", err)
 	}
@@ -104,11 +116,11 @@ func (s *Service) GetThematicLibrary(id string) (*models.ThematicLibrary, error)
 }
 
 // GetThematicLibraries 获取数据主题库列表
-func (s *Service) GetThematicLibraries(page, pageSize int, category, domain, status string) ([]models.ThematicLibrary, int64, error) {
+func (s *Service) GetThematicLibraries(ctx context.Context, page, pageSize int, category, domain, status string) ([]models.ThematicLibrary, int64, error) {
 	var libraries []models.ThematicLibrary
 	var total int64
 
-	query := s.db.Model(&models.ThematicLibrary{})
+	query := s.db.WithContext(ctx).Model(&models.ThematicLibrary{})
 
 	if category != "" {
 		query = query.Where("category = ?", category)
@@ -133,11 +145,24 @@ func (s *Service) GetThematicLibraries(page, pageSize int, category, domain, sta
 }
 
 // GetThematicLibraryList 获取数据主题库列表（支持名称搜索）
-func (s *Service) GetThematicLibraryList(page, pageSize int, category, domain, status, name string) ([]models.ThematicLibrary, int64, error) {
+func (s *Service) GetThematicLibraryList(ctx context.Context, page, pageSize int, category, domain, status, name string) ([]models.ThematicLibrary, int64, error) {
+	result, err := catalog_cache.Fetch(ctx, catalog_cache.Key{
+		Name:   "thematic_libraries",
+		Tables: []string{"thematic_libraries", "thematic_interfaces", "resource_grants"},
+		Params: []interface{}{page, pageSize, category, domain, status, name},
+	}, func() (catalog_cache.Page[models.ThematicLibrary], error) {
+		items, total, err := s.loadThematicLibraryList(ctx, page, pageSize, category, domain, status, name)
+		return catalog_cache.Page[models.ThematicLibrary]{Items: items, Total: total}, err
+	})
+	return result.Items, result.Total, err
+}
+
+// loadThematicLibraryList 从数据库查询数据主题库列表（支持名称搜索）
+func (s *Service) loadThematicLibraryList(ctx context.Context, page, pageSize int, category, domain, status, name string) ([]models.ThematicLibrary, int64, error) {
 	var libraries []models.ThematicLibrary
 	var total int64
 
-	query := s.db.Model(&models.ThematicLibrary{})
+	query := s.db.WithContext(ctx).Model(&models.ThematicLibrary{}).Scopes(rbac.GrantScope(ctx, rbac.ResourceThematicLibrary, "thematic_libraries.id"))
 
 	// 添加过滤条件
 	if category != "" {
@@ -172,11 +197,25 @@ func (s *Service) GetThematicLibraryList(page, pageSize int, category, domain, s
 }
 
 // GetThematicInterfaceList 获取主题接口列表（支持名称搜索）
-func (s *Service) GetThematicInterfaceList(page, pageSize int, libraryID, interfaceType, status, name string) ([]models.ThematicInterface, int64, error) {
+func (s *Service) GetThematicInterfaceList(ctx context.Context, page, pageSize int, libraryID, interfaceType, status, name string) ([]models.ThematicInterface, int64, error) {
+	result, err := catalog_cache.Fetch(ctx, catalog_cache.Key{
+		Name:   "thematic_interfaces",
+		Tables: []string{"thematic_interfaces", "thematic_libraries", "resource_grants"},
+		Params: []interface{}{page, pageSize, libraryID, interfaceType, status, name},
+	}, func() (catalog_cache.Page[models.ThematicInterface], error) {
+		items, total, err := s.loadThematicInterfaceList(ctx, page, pageSize, libraryID, interfaceType, status, name)
+		return catalog_cache.Page[models.ThematicInterface]{Items: items, Total: total}, err
+	})
+	return result.Items, result.Total, err
+}
+
+// loadThematicInterfaceList 从数据库查询主题接口列表（支持名称搜索）
+func (s *Service) loadThematicInterfaceList(ctx context.Context, page, pageSize int, libraryID, interfaceType, status, name string) ([]models.ThematicInterface, int64, error) {
 	var interfaces []models.ThematicInterface
 	var total int64
 
-	query := s.db.Model(&models.ThematicInterface{})
+	query := s.db.Model(&models.ThematicInterface{}).Scopes(tenant.OwnerScope(ctx, "library_id", "thematic_libraries"),
+		rbac.GrantScope(ctx, rbac.ResourceThematicLibrary, "thematic_interfaces.library_id"))
 
 	// 添加过滤条件
 	if libraryID != "" {
@@ -211,38 +250,42 @@ func (s *Service) GetThematicInterfaceList(page, pageSize int, libraryID, interf
 }
 
 // UpdateThematicLibrary 更新数据主题库
-func (s *Service) UpdateThematicLibrary(id string, updates *models.ThematicLibrary) error {
+func (s *Service) UpdateThematicLibrary(ctx context.Context, id string, updates *models.ThematicLibrary) error {
+	var existing models.ThematicLibrary
+	if err := s.db.WithContext(ctx).First(&existing, "id = ?", id).Error; err != nil {
+		return errors.New("主题库不存在")
+	}
+	updates.TenantID = ""
+	updates.SchemaName = ""
 	if updates.NameEn != "" {
-		var existing models.ThematicLibrary
-		if err := s.db.First(&existing, "id = ?", id).Error; err != nil {
-			return errors.New("主题库不存在")
-		}
 		if !isValidSchemaName(updates.NameEn) {
 			return errors.New("主题库英文名称格式不符合数据库schema命名规范")
 		}
 		if existing.NameEn != updates.NameEn {
-			if database.CheckSchemaExists(s.db, updates.NameEn) {
+			// 保留原schema的租户前缀
+			schemaName := strings.TrimSuffix(existing.GetSchemaName(), existing.NameEn) + updates.NameEn
+			if database.CheckSchemaExists(s.db, schemaName) {
 				return errors.New("主题库英文名称已存在")
 			}
-			err := database.DeleteSchema(s.db, existing.NameEn)
+			err := database.DeleteSchema(s.db, existing.GetSchemaName())
 			if err != nil {
 				return err
 			}
-			err = database.CreateSchema(s.db, updates.NameEn)
+			err = database.CreateSchema(s.db, schemaName)
 			if err != nil {
 				return err
 			}
-
+			updates.SchemaName = schemaName
 		}
 
 	}
-	return s.db.Model(&models.ThematicLibrary{}).Where("id = ?", id).Updates(updates).Error
+	return s.db.WithContext(ctx).Model(&models.ThematicLibrary{}).Where("id = ?", id).Updates(updates).Error
 }
 
 // DeleteThematicLibrary 删除数据主题库
-func (s *Service) DeleteThematicLibrary(id string) error {
+func (s *Service) DeleteThematicLibrary(ctx context.Context, id string) error {
 	var existing models.ThematicLibrary
-	if err := s.db.First(&existing, "id = ?", id).Error; err != nil {
+	if err := s.db.WithContext(ctx).First(&existing, "id = ?", id).Error; err != nil {
 		return errors.New("主题库不存在")
 	}
 	interfaces, _, err := s.GetThematicInterfaces(1, 10000, id, "", "")
@@ -252,11 +295,11 @@ func (s *Service) DeleteThematicLibrary(id string) error {
 	if len(interfaces) > 0 {
 		return errors.New("存在关联的主题接口，无法删除主题库")
 	}
-	err = database.DeleteSchema(s.db, existing.NameEn)
+	err = database.DeleteSchema(s.db, existing.GetSchemaName())
 	if err != nil {
 		return err
 	}
-	return s.db.Delete(&models.ThematicLibrary{}, "id = ?", id).Error
+	return s.db.WithContext(ctx).Delete(&models.ThematicLibrary{}, "id = ?", id).Error
 }
 
 // CreateDataFlowGraph 创建数据流程图
@@ -318,6 +361,13 @@ func (s *Service) CreateThematicInterface(thematicInterface *models.ThematicInte
 		return errors.New("同一主题库下接口英文名称已存在")
 	}
 
+	if err := validateStorageEngine(thematicInterface); err != nil {
+		return err
+	}
+
+	// 新建接口须经发布评审后才能被数据共享引用
+	thematicInterface.PublishStatus = models.ThematicPublishStatusDraft
+
 	// 先创建接口记录
 	if err := s.db.Create(thematicInterface).Error; err != nil {
 		return err
@@ -325,7 +375,7 @@ func (s *Service) CreateThematicInterface(thematicInterface *models.ThematicInte
 
 	// 如果是view类型且提供了ViewSQL，自动创建视图
 	if thematicInterface.Type == "view" && thematicInterface.ViewSQL != "" {
-		schemaName := library.NameEn
+		schemaName := library.GetSchemaName()
 		viewName := thematicInterface.NameEn
 
 		err := s.schemaService.ManageViewSchema(thematicInterface.ID, "create_view", schemaName, viewName, thematicInterface.ViewSQL)
@@ -360,8 +410,8 @@ func (s *Service) GetThematicInterfaceWithSync(id string) (*models.ThematicInter
 		return nil, err
 	}
 
-	// 如果表已创建，检查并同步字段配置
-	if interfaceData.IsTableCreated || interfaceData.IsViewCreated {
+	// 如果表已创建，检查并同步字段配置；ClickHouse接口表以字段配置为准，不反向同步
+	if (interfaceData.IsTableCreated || interfaceData.IsViewCreated) && !interfaceData.IsClickHouse() {
 		synced, err := s.syncTableFieldsConfig(interfaceData)
 		if err != nil {
 			slog.Warn("同步表字段配置失败", "interface_id", id, "error", err)
@@ -380,7 +430,7 @@ func (s *Service) GetThematicInterfaceWithSync(id string) (*models.ThematicInter
 
 // syncTableFieldsConfig 同步表字段配置
 func (s *Service) syncTableFieldsConfig(interfaceData *models.ThematicInterface) (bool, error) {
-	schemaName := interfaceData.ThematicLibrary.NameEn
+	schemaName := interfaceData.ThematicLibrary.GetSchemaName()
 	tableName := interfaceData.NameEn
 
 	// 检查表或视图是否存在
@@ -487,6 +537,7 @@ func (s *Service) mergeFieldsWithConfig(actualColumns []database.ColumnDefinitio
 			IsNullable:   col.IsNullable,
 			Description:  col.Comment,
 			OrderNum:     col.OrdinalPosition,
+			Expression:   col.GenerationExpression,
 		}
 
 		// 处理默认值
@@ -498,7 +549,7 @@ func (s *Service) mergeFieldsWithConfig(actualColumns []database.ColumnDefinitio
 
 		// 如果配置中存在该字段，合并配置信息
 		if existingField, exists := existingFieldMap[col.Name]; exists {
-			// 保留原有的 OrderNum、NameZh（如果更有意义）、IsIncrementField 等配置
+			// 保留原有的 OrderNum、NameZh（如果更有意义）、IsIncrementField、索引标记、派生列表达式、敏感标记等配置
 			if existingField.OrderNum > 0 {
 				field.OrderNum = existingField.OrderNum
 			}
@@ -506,6 +557,13 @@ func (s *Service) mergeFieldsWithConfig(actualColumns []database.ColumnDefinitio
 				field.NameZh = existingField.NameZh
 			}
 			field.IsIncrementField = existingField.IsIncrementField
+			field.IsIndexed = existingField.IsIndexed
+			field.CompositeIndex = existingField.CompositeIndex
+			field.IsSensitive = existingField.IsSensitive
+			if existingField.IsComputed() {
+				field.Expression = existingField.Expression
+				field.ComputeMode = existingField.ComputeMode
+			}
 
 			// 如果配置中的描述更详细，使用配置中的
 			if existingField.Description != "" && len(existingField.Description) > len(col.Comment) {
@@ -548,6 +606,7 @@ func (s *Service) convertColumnsToTableFields(columns []database.ColumnDefinitio
 			IsNullable:   col.IsNullable,
 			Description:  col.Comment,
 			OrderNum:     col.OrdinalPosition,
+			Expression:   col.GenerationExpression,
 		}
 
 		// 处理默认值
@@ -683,7 +742,7 @@ func (s *Service) GetThematicInterfaces(page, pageSize int, libraryID, interface
 	return interfaces, total, nil
 }
 
-// UpdateThematicInterface 更新主题接口
+// UpdateThematicInterface 更新主题接口，updates.RowVersion为期望版本，为0时不校验版本
 func (s *Service) UpdateThematicInterface(id string, updates *models.ThematicInterface) error {
 	// 检查接口是否存在
 	var existing models.ThematicInterface
@@ -691,6 +750,15 @@ func (s *Service) UpdateThematicInterface(id string, updates *models.ThematicInt
 		return errors.New("主题接口不存在")
 	}
 
+	// 提前校验版本，避免在版本冲突时执行视图变更
+	expectedVersion := updates.RowVersion
+	updates.RowVersion = 0
+	// 发布状态只能通过发布流程变更
+	updates.PublishStatus = ""
+	if expectedVersion > 0 && expectedVersion != existing.RowVersion {
+		return &models.VersionConflictError{Expected: expectedVersion, Current: existing.RowVersion}
+	}
+
 	// 如果更新英文名称，需要验证格式和唯一性
 	if updates.NameEn != "" && updates.NameEn != existing.NameEn {
 		if !isValidSchemaName(updates.NameEn) {
@@ -710,6 +778,14 @@ func (s *Service) UpdateThematicInterface(id string, updates *models.ThematicInt
 		if !contains(validTypes, updates.Type) {
 			return errors.New("无效的接口类型")
 		}
+		if updates.Type != "table" && existing.IsClickHouse() {
+			return errors.New("ClickHouse存储的接口只能是table类型")
+		}
+	}
+
+	// 存储引擎决定接口表建在哪个数据库，创建后不能修改
+	if updates.StorageEngine != "" && updates.StorageEngine != existing.StorageEngine {
+		return errors.New("接口表存储引擎创建后不能修改")
 	}
 
 	// 处理view类型接口的view_sql更新
@@ -726,7 +802,7 @@ func (s *Service) UpdateThematicInterface(id string, updates *models.ThematicInt
 		}
 
 		// 获取schema名称和视图名称
-		schemaName := existing.ThematicLibrary.NameEn
+		schemaName := existing.ThematicLibrary.GetSchemaName()
 		viewName := existing.NameEn
 		if updates.NameEn != "" {
 			viewName = updates.NameEn
@@ -742,7 +818,12 @@ func (s *Service) UpdateThematicInterface(id string, updates *models.ThematicInt
 		updates.IsViewCreated = true
 	}
 
-	return s.db.Model(&models.ThematicInterface{}).Where("id = ?", id).Updates(updates).Error
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		if _, err := models.BumpRowVersion(tx, &models.ThematicInterface{}, id, expectedVersion); err != nil {
+			return err
+		}
+		return tx.Model(&models.ThematicInterface{}).Where("id = ?", id).Updates(updates).Error
+	})
 }
 
 // DeleteThematicInterface 删除主题接口
@@ -754,7 +835,7 @@ func (s *Service) DeleteThematicInterface(id string) error {
 	}
 
 	// 开启事务删除接口和相关记录
-	return s.db.Transaction(func(tx *gorm.DB) error {
+	err := s.db.Transaction(func(tx *gorm.DB) error {
 		// 检查是否有关联的数据流程图
 		var flowGraphCount int64
 		tx.Model(&models.DataFlowGraph{}).Where("thematic_interface_id = ? AND status != 'inactive'", id).Count(&flowGraphCount)
@@ -777,7 +858,7 @@ func (s *Service) DeleteThematicInterface(id string) error {
 		}
 
 		// 根据接口类型删除对应的数据库对象
-		schemaName := existing.ThematicLibrary.NameEn
+		schemaName := existing.ThematicLibrary.GetSchemaName()
 		tableName := existing.NameEn
 
 		if existing.Type == "view" && existing.IsViewCreated {
@@ -789,6 +870,13 @@ func (s *Service) DeleteThematicInterface(id string) error {
 			} else {
 				slog.Info("成功删除主题接口视图", "interface_id", id, "schema", schemaName, "view", tableName)
 			}
+		} else if existing.Type == "table" && existing.IsTableCreated && existing.IsClickHouse() {
+			// 删除ClickHouse表
+			if err := s.dropClickHouseTable(schemaName, tableName); err != nil {
+				slog.Warn("删除主题接口ClickHouse表失败", "interface_id", id, "database", schemaName, "table", tableName, "error", err)
+			} else {
+				slog.Info("成功删除主题接口ClickHouse表", "interface_id", id, "database", schemaName, "table", tableName)
+			}
 		} else if existing.Type == "table" && existing.IsTableCreated {
 			// 删除表
 			err := s.schemaService.ManageTableSchema(id, "drop_table", schemaName, tableName, nil)
@@ -800,6 +888,11 @@ func (s *Service) DeleteThematicInterface(id string) error {
 			}
 		}
 
+		// 删除变更日志策略，变更日志表在提交后删除
+		if err := tx.Where("thematic_interface_id = ?", id).Delete(&models.ThematicChangelogPolicy{}).Error; err != nil {
+			return fmt.Errorf("删除变更日志策略失败: %w", err)
+		}
+
 		// 删除主题接口记录
 		if err := tx.Delete(&models.ThematicInterface{}, "id = ?", id).Error; err != nil {
 			return fmt.Errorf("删除主题接口失败: %w", err)
@@ -807,6 +900,14 @@ func (s *Service) DeleteThematicInterface(id string) error {
 
 		return nil
 	})
+	if err != nil {
+		return err
+	}
+
+	if err := thematic_sync.DropChangelogTable(context.Background(), s.db, id); err != nil {
+		slog.Warn("删除主题接口变更日志表失败", "interface_id", id, "error", err)
+	}
+	return nil
 }
 
 // contains 检查字符串切片是否包含指定值
@@ -854,6 +955,16 @@ func (s *Service) UpdateThematicInterfaceFields(interfaceID string, fields []mod
 	if interfaceData.Type != "table" {
 		return errors.New("只有table类型的接口才能更新字段配置，view类型请使用视图管理接口")
 	}
+	if err := database.ValidateComputedFields(fields); err != nil {
+		return err
+	}
+	if interfaceData.IsClickHouse() {
+		for _, field := range fields {
+			if field.IsComputed() {
+				return fmt.Errorf("ClickHouse接口表不支持派生列: %s", field.NameEn)
+			}
+		}
+	}
 
 	// 获取主题库信息
 	var library models.ThematicLibrary
@@ -861,7 +972,7 @@ func (s *Service) UpdateThematicInterfaceFields(interfaceID string, fields []mod
 		return fmt.Errorf("获取主题库信息失败: %w", err)
 	}
 
-	schemaName := library.NameEn
+	schemaName := library.GetSchemaName()
 	tableName := interfaceData.NameEn
 
 	// 转换字段为JSONB格式
@@ -873,12 +984,23 @@ func (s *Service) UpdateThematicInterfaceFields(interfaceID string, fields []mod
 	// 更新主题接口字段配置
 	updates := map[string]interface{}{
 		"table_fields_config": fieldsData,
+		"row_version":         gorm.Expr("row_version + 1"),
 	}
 
 	if err := s.db.Model(&models.ThematicInterface{}).Where("id = ?", interfaceID).Updates(updates).Error; err != nil {
 		return fmt.Errorf("更新主题接口字段配置失败: %w", err)
 	}
 
+	if interfaceData.IsClickHouse() {
+		if err := s.ensureClickHouseTable(schemaName, tableName, fields); err != nil {
+			return fmt.Errorf("管理ClickHouse表结构失败: %w", err)
+		}
+		if err := s.db.Model(&models.ThematicInterface{}).Where("id = ?", interfaceID).Update("is_table_created", true).Error; err != nil {
+			return fmt.Errorf("更新主题接口表创建状态失败: %w", err)
+		}
+		return nil
+	}
+
 	// 检查表是否存在
 	tableExists, err := s.schemaService.CheckTableExists(schemaName, tableName)
 	if err != nil {
@@ -898,8 +1020,7 @@ func (s *Service) UpdateThematicInterfaceFields(interfaceID string, fields []mod
 	if err != nil {
 		return fmt.Errorf("管理表结构失败: %w", err)
 	}
-	updates["is_table_created"] = true
-	if err := s.db.Model(&models.ThematicInterface{}).Where("id = ?", interfaceID).Updates(updates).Error; err != nil {
+	if err := s.db.Model(&models.ThematicInterface{}).Where("id = ?", interfaceID).Update("is_table_created", true).Error; err != nil {
 		return fmt.Errorf("更新主题接口表创建状态失败: %w", err)
 	}
 	return nil
@@ -924,7 +1045,7 @@ func (s *Service) CreateThematicInterfaceView(interfaceID, viewSQL string) error
 		return fmt.Errorf("获取主题库信息失败: %w", err)
 	}
 
-	schemaName := library.NameEn
+	schemaName := library.GetSchemaName()
 	viewName := interfaceData.NameEn
 
 	// 检查视图是否已存在
@@ -984,7 +1105,7 @@ func (s *Service) UpdateThematicInterfaceView(interfaceID, viewSQL string) error
 		return fmt.Errorf("获取主题库信息失败: %w", err)
 	}
 
-	schemaName := library.NameEn
+	schemaName := library.GetSchemaName()
 	viewName := interfaceData.NameEn
 
 	// 调用SchemaService更新视图
@@ -1000,6 +1121,7 @@ func (s *Service) UpdateThematicInterfaceView(interfaceID, viewSQL string) error
 			"last_updated": fmt.Sprintf("%d", time.Now().Unix()),
 			"operation":    "update_view",
 		},
+		"row_version": gorm.Expr("row_version + 1"),
 	}
 
 	if err := s.db.Model(&models.ThematicInterface{}).Where("id = ?", interfaceID).Updates(updates).Error; err != nil {
@@ -1028,7 +1150,7 @@ func (s *Service) DeleteThematicInterfaceView(interfaceID string) error {
 		return fmt.Errorf("获取主题库信息失败: %w", err)
 	}
 
-	schemaName := library.NameEn
+	schemaName := library.GetSchemaName()
 	viewName := interfaceData.NameEn
 
 	// 调用SchemaService删除视图
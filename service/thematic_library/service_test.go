@@ -6,6 +6,7 @@
 package thematic_library
 
 import (
+	"context"
 	"datahub-service/service/models"
 	"fmt"
 	"log/slog"
@@ -106,7 +107,7 @@ func TestCreateThematicLibrary(t *testing.T) {
 		RetentionPeriod: 365,
 	}
 
-	err := testService.CreateThematicLibrary(library)
+	err := testService.CreateThematicLibrary(context.Background(), library)
 	assert.NoError(t, err, "创建主题库应该成功")
 	assert.NotEmpty(t, library.ID, "主题库ID应该被生成")
 
@@ -133,7 +134,7 @@ func TestCreateThematicInterfaceWithTable(t *testing.T) {
 		AccessLevel:     "internal",
 		UpdateFrequency: "daily",
 	}
-	err := testService.CreateThematicLibrary(library)
+	err := testService.CreateThematicLibrary(context.Background(), library)
 	require.NoError(t, err)
 
 	// 创建表类型主题接口
@@ -166,7 +167,7 @@ func TestUpdateThematicInterfaceFields(t *testing.T) {
 		AccessLevel:     "internal",
 		UpdateFrequency: "daily",
 	}
-	err := testService.CreateThematicLibrary(library)
+	err := testService.CreateThematicLibrary(context.Background(), library)
 	require.NoError(t, err)
 
 	// 创建表类型接口
@@ -254,7 +255,7 @@ func TestCreateThematicInterfaceWithView(t *testing.T) {
 		AccessLevel:     "internal",
 		UpdateFrequency: "daily",
 	}
-	err := testService.CreateThematicLibrary(library)
+	err := testService.CreateThematicLibrary(context.Background(), library)
 	require.NoError(t, err)
 
 	// 先创建一个基础表供视图使用
@@ -344,7 +345,7 @@ func TestUpdateThematicInterfaceView(t *testing.T) {
 		AccessLevel:     "internal",
 		UpdateFrequency: "daily",
 	}
-	err := testService.CreateThematicLibrary(library)
+	err := testService.CreateThematicLibrary(context.Background(), library)
 	require.NoError(t, err)
 
 	baseTable := &models.ThematicInterface{
@@ -406,7 +407,7 @@ func TestDeleteThematicInterfaceView(t *testing.T) {
 		AccessLevel:     "internal",
 		UpdateFrequency: "daily",
 	}
-	err := testService.CreateThematicLibrary(library)
+	err := testService.CreateThematicLibrary(context.Background(), library)
 	require.NoError(t, err)
 
 	baseTable := &models.ThematicInterface{
@@ -470,7 +471,7 @@ func TestInvalidOperations(t *testing.T) {
 		AccessLevel:     "internal",
 		UpdateFrequency: "daily",
 	}
-	err := testService.CreateThematicLibrary(library)
+	err := testService.CreateThematicLibrary(context.Background(), library)
 	require.NoError(t, err)
 
 	// 创建表类型接口
@@ -526,7 +527,7 @@ func TestGetThematicInterfaceWithSync(t *testing.T) {
 		AccessLevel:     "internal",
 		UpdateFrequency: "daily",
 	}
-	err := testService.CreateThematicLibrary(library)
+	err := testService.CreateThematicLibrary(context.Background(), library)
 	require.NoError(t, err)
 
 	tableInterface := &models.ThematicInterface{
@@ -587,7 +588,7 @@ func TestAlterTable(t *testing.T) {
 		AccessLevel:     "internal",
 		UpdateFrequency: "daily",
 	}
-	err := testService.CreateThematicLibrary(library)
+	err := testService.CreateThematicLibrary(context.Background(), library)
 	require.NoError(t, err)
 
 	tableInterface := &models.ThematicInterface{
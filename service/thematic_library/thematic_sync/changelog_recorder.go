@@ -0,0 +1,298 @@
+/*
+ * @module service/thematic_sync/changelog_recorder
+ * @description 主题接口变更日志记录，开启变更日志的接口在同步写入时比较每个批次写入前后的行，把新增、修改的主键和修改列以及全量同步删除的主键写入接口的变更日志表
+ * @architecture 分层架构 - 同步引擎写入阶段的附加记录
+ * @documentReference ai_docs/thematic_sync_design.md
+ * @stateFlow 读取启用的变更日志策略 -> 批次写入前按主键读取现有行 -> 写入 -> 再次读取 -> 比较得到新增/修改 -> 写入变更日志表；全量同步删除后记录删除 -> 写入完成后清理超出保留天数的记录
+ * @rules 只记录主键和修改的列名，不记录数据值；写入前后都不存在的记录（被合并策略跳过）不记录；写入前后完全相同的记录不记录；
+ *        没有主键的接口和ClickHouse接口表不记录；复合主键的删除记录按_拆分主键取值，与全量同步删除的处理一致
+ * @dependencies gorm.io/gorm, datahub-service/service/models
+ * @refs data_writer.go, sync_strategy.go, service/models/thematic_changelog.go
+ */
+
+package thematic_sync
+
+import (
+	"bytes"
+	"context"
+	"datahub-service/service/models"
+	"datahub-service/service/utils"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// changelogInsertChunk 每条INSERT语句写入的变更日志条数
+const changelogInsertChunk = 500
+
+// changelogChange 一条待记录的变更
+type changelogChange struct {
+	op      string
+	pk      map[string]interface{}
+	columns []string
+}
+
+// changelogRecorder 一次主题同步写入的变更日志记录器
+type changelogRecorder struct {
+	db          *gorm.DB
+	policy      *models.ThematicChangelogPolicy
+	table       string // 变更日志表
+	target      string // 主题表
+	executionID string
+	primaryKeys []string
+}
+
+// EnsureChangelogTable 创建主题接口的变更日志表，已存在时不做处理
+func EnsureChangelogTable(ctx context.Context, db *gorm.DB, interfaceID string) error {
+	table := models.ChangelogTable(interfaceID)
+	statements := []string{
+		fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+			seq BIGSERIAL PRIMARY KEY,
+			op VARCHAR(10) NOT NULL,
+			pk JSONB NOT NULL,
+			changed_columns JSONB,
+			execution_id VARCHAR(36),
+			changed_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+		)`, table),
+		fmt.Sprintf(`CREATE INDEX IF NOT EXISTS "idx_%s" ON %s (changed_at)`, strings.ReplaceAll(interfaceID, `"`, `""`), table),
+	}
+	for _, statement := range statements {
+		if err := db.WithContext(ctx).Exec(statement).Error; err != nil {
+			return fmt.Errorf("创建变更日志表失败: %w", err)
+		}
+	}
+	return nil
+}
+
+// DropChangelogTable 删除主题接口的变更日志表
+func DropChangelogTable(ctx context.Context, db *gorm.DB, interfaceID string) error {
+	if err := db.WithContext(ctx).Exec("DROP TABLE IF EXISTS " + models.ChangelogTable(interfaceID)).Error; err != nil {
+		return fmt.Errorf("删除变更日志表失败: %w", err)
+	}
+	return nil
+}
+
+// newChangelogRecorder 主题接口开启变更日志时创建记录器，未开启或没有主键时返回nil
+func newChangelogRecorder(ctx context.Context, db *gorm.DB, request *SyncRequest, fullTableName string, primaryKeys []string) (*changelogRecorder, error) {
+	var policy models.ThematicChangelogPolicy
+	err := db.WithContext(ctx).First(&policy, "thematic_interface_id = ? AND enabled = ?", request.TargetInterfaceID, true).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("获取变更日志策略失败: %w", err)
+	}
+	if len(primaryKeys) == 0 {
+		slog.Warn("主题接口没有配置主键字段，不记录变更日志", "interface_id", request.TargetInterfaceID)
+		return nil, nil
+	}
+	if err := EnsureChangelogTable(ctx, db, request.TargetInterfaceID); err != nil {
+		return nil, err
+	}
+	return &changelogRecorder{
+		db:          db,
+		policy:      &policy,
+		table:       models.ChangelogTable(request.TargetInterfaceID),
+		target:      fullTableName,
+		executionID: request.ExecutionID,
+		primaryKeys: primaryKeys,
+	}, nil
+}
+
+// snapshot 按批次记录的主键读取主题表中的现有行，返回主键 -> 行
+func (c *changelogRecorder) snapshot(ctx context.Context, batch []map[string]interface{}) (map[string]map[string]interface{}, error) {
+	keyColumns := make([]string, len(c.primaryKeys))
+	for i, field := range c.primaryKeys {
+		keyColumns[i] = utils.QuoteIdent(field) + "::text"
+	}
+
+	var tuples []string
+	var args []interface{}
+	seen := make(map[string]bool, len(batch))
+	for _, record := range batch {
+		values := make([]string, 0, len(c.primaryKeys))
+		for _, field := range c.primaryKeys {
+			value, ok := record[field]
+			if !ok || value == nil {
+				break
+			}
+			values = append(values, fmt.Sprintf("%v", value))
+		}
+		if len(values) != len(c.primaryKeys) {
+			continue
+		}
+		key := strings.Join(values, "\x1f")
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		tuples = append(tuples, "("+strings.TrimSuffix(strings.Repeat("?,", len(values)), ",")+")")
+		for _, value := range values {
+			args = append(args, value)
+		}
+	}
+
+	rows := make(map[string]map[string]interface{}, len(tuples))
+	if len(tuples) == 0 {
+		return rows, nil
+	}
+	query := fmt.Sprintf("SELECT to_jsonb(t)::text FROM %s t WHERE (%s) IN (%s)",
+		c.target, strings.Join(keyColumns, ", "), strings.Join(tuples, ", "))
+	var documents []string
+	if err := c.db.WithContext(ctx).Raw(query, args...).Scan(&documents).Error; err != nil {
+		return nil, fmt.Errorf("读取主题表现有行失败: %w", err)
+	}
+	for _, document := range documents {
+		decoder := json.NewDecoder(strings.NewReader(document))
+		decoder.UseNumber()
+		var row map[string]interface{}
+		if err := decoder.Decode(&row); err != nil {
+			return nil, fmt.Errorf("解析主题表行失败: %w", err)
+		}
+		rows[changelogRowKey(row, c.primaryKeys)] = row
+	}
+	return rows, nil
+}
+
+// recordBatch 批次写入后再次读取并与写入前比较，记录新增和修改，返回记录的条数
+func (c *changelogRecorder) recordBatch(ctx context.Context, batch []map[string]interface{}, before map[string]map[string]interface{}) (int64, error) {
+	after, err := c.snapshot(ctx, batch)
+	if err != nil {
+		return 0, err
+	}
+	return c.write(ctx, diffChangelogRows(c.primaryKeys, before, after))
+}
+
+// recordDeletes 记录全量同步删除的记录，keys为以_连接的主键取值
+func (c *changelogRecorder) recordDeletes(ctx context.Context, keys []string) (int64, error) {
+	changes := make([]changelogChange, 0, len(keys))
+	for _, key := range keys {
+		parts := []string{key}
+		if len(c.primaryKeys) > 1 {
+			parts = strings.Split(key, "_")
+		}
+		if len(parts) != len(c.primaryKeys) {
+			continue
+		}
+		pk := make(map[string]interface{}, len(parts))
+		for i, field := range c.primaryKeys {
+			pk[field] = parts[i]
+		}
+		changes = append(changes, changelogChange{op: models.ChangelogOpDelete, pk: pk})
+	}
+	return c.write(ctx, changes)
+}
+
+// write 写入变更日志表
+func (c *changelogRecorder) write(ctx context.Context, changes []changelogChange) (int64, error) {
+	now := time.Now()
+	for start := 0; start < len(changes); start += changelogInsertChunk {
+		end := start + changelogInsertChunk
+		if end > len(changes) {
+			end = len(changes)
+		}
+		placeholders := make([]string, 0, end-start)
+		args := make([]interface{}, 0, (end-start)*5)
+		for _, change := range changes[start:end] {
+			pk, err := json.Marshal(change.pk)
+			if err != nil {
+				return 0, fmt.Errorf("序列化变更主键失败: %w", err)
+			}
+			var columns interface{}
+			if len(change.columns) > 0 {
+				encoded, err := json.Marshal(change.columns)
+				if err != nil {
+					return 0, fmt.Errorf("序列化修改列失败: %w", err)
+				}
+				columns = string(encoded)
+			}
+			placeholders = append(placeholders, "(?, ?::jsonb, ?::jsonb, ?, ?)")
+			args = append(args, change.op, string(pk), columns, c.executionID, now)
+		}
+		statement := fmt.Sprintf("INSERT INTO %s (op, pk, changed_columns, execution_id, changed_at) VALUES %s",
+			c.table, strings.Join(placeholders, ", "))
+		if err := c.db.WithContext(ctx).Exec(statement, args...).Error; err != nil {
+			return 0, fmt.Errorf("写入变更日志失败: %w", err)
+		}
+	}
+	return int64(len(changes)), nil
+}
+
+// prune 删除超出保留天数的变更日志，失败只记录日志
+func (c *changelogRecorder) prune(ctx context.Context) {
+	cutoff := time.Now().AddDate(0, 0, -c.policy.RetentionDays)
+	result := c.db.WithContext(ctx).Exec("DELETE FROM "+c.table+" WHERE changed_at < ?", cutoff)
+	if result.Error != nil {
+		slog.Error("清理过期变更日志失败", "interface_id", c.policy.ThematicInterfaceID, "error", result.Error)
+		return
+	}
+	if result.RowsAffected > 0 {
+		slog.Debug("清理过期变更日志", "interface_id", c.policy.ThematicInterfaceID, "deleted", result.RowsAffected)
+	}
+}
+
+// diffChangelogRows 比较批次写入前后的行，返回新增和修改的记录，按主键排序
+func diffChangelogRows(primaryKeys []string, before, after map[string]map[string]interface{}) []changelogChange {
+	keys := make([]string, 0, len(after))
+	for key := range after {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var changes []changelogChange
+	for _, key := range keys {
+		row := after[key]
+		pk := make(map[string]interface{}, len(primaryKeys))
+		for _, field := range primaryKeys {
+			pk[field] = row[field]
+		}
+		previous, existed := before[key]
+		if !existed {
+			changes = append(changes, changelogChange{op: models.ChangelogOpInsert, pk: pk})
+			continue
+		}
+		if columns := changedColumns(previous, row); len(columns) > 0 {
+			changes = append(changes, changelogChange{op: models.ChangelogOpUpdate, pk: pk, columns: columns})
+		}
+	}
+	return changes
+}
+
+// changedColumns 返回取值不同的列，只在一边存在的列也算修改
+func changedColumns(before, after map[string]interface{}) []string {
+	var columns []string
+	for column, value := range after {
+		if previous, ok := before[column]; !ok || !sameJSONValue(previous, value) {
+			columns = append(columns, column)
+		}
+	}
+	for column := range before {
+		if _, ok := after[column]; !ok {
+			columns = append(columns, column)
+		}
+	}
+	sort.Strings(columns)
+	return columns
+}
+
+// sameJSONValue 按JSON编码比较两个值
+func sameJSONValue(a, b interface{}) bool {
+	left, errA := json.Marshal(a)
+	right, errB := json.Marshal(b)
+	return errA == nil && errB == nil && bytes.Equal(left, right)
+}
+
+// changelogRowKey 行的主键取值，与写入前后比较时使用
+func changelogRowKey(row map[string]interface{}, primaryKeys []string) string {
+	parts := make([]string, len(primaryKeys))
+	for i, field := range primaryKeys {
+		parts[i] = fmt.Sprintf("%v", row[field])
+	}
+	return strings.Join(parts, "\x1f")
+}
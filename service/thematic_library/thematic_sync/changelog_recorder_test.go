@@ -0,0 +1,58 @@
+/*
+ * @module service/thematic_sync/changelog_recorder_test
+ * @description 主题接口变更日志测试，覆盖批次写入前后的比较（新增、修改、未变化、被跳过）和修改列的识别
+ * @architecture 单元测试 - 比较逻辑为纯函数
+ * @documentReference ai_docs/thematic_sync_design.md
+ * @refs changelog_recorder.go
+ */
+
+package thematic_sync
+
+import (
+	"datahub-service/service/models"
+	"encoding/json"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestDiffChangelogRows 测试按主键比较批次写入前后的行
+func TestDiffChangelogRows(t *testing.T) {
+	pks := []string{"org", "id"}
+	row := func(org string, id int, name string, score float64) map[string]interface{} {
+		return map[string]interface{}{"org": org, "id": json.Number(strconv.Itoa(id)), "name": name, "score": score}
+	}
+	before := map[string]map[string]interface{}{}
+	after := map[string]map[string]interface{}{}
+	put := func(rows map[string]map[string]interface{}, r map[string]interface{}) {
+		rows[changelogRowKey(r, pks)] = r
+	}
+
+	put(before, row("a", 1, "张三", 90))
+	put(after, row("a", 1, "张三", 95)) // 修改score
+	put(before, row("a", 2, "李四", 80))
+	put(after, row("a", 2, "李四", 80)) // 未变化
+	put(after, row("b", 1, "王五", 70)) // 新增
+
+	changes := diffChangelogRows(pks, before, after)
+	require.Len(t, changes, 2)
+	assert.Equal(t, models.ChangelogOpUpdate, changes[0].op)
+	assert.Equal(t, map[string]interface{}{"org": "a", "id": json.Number("1")}, changes[0].pk)
+	assert.Equal(t, []string{"score"}, changes[0].columns)
+	assert.Equal(t, models.ChangelogOpInsert, changes[1].op)
+	assert.Equal(t, "b", changes[1].pk["org"])
+	assert.Empty(t, changes[1].columns, "新增记录不记录修改列")
+
+	// 写入后读不到的行（如被合并策略跳过的新记录）不记录
+	assert.Empty(t, diffChangelogRows(pks, before, map[string]map[string]interface{}{}))
+}
+
+// TestChangedColumns 测试修改列的识别
+func TestChangedColumns(t *testing.T) {
+	before := map[string]interface{}{"id": json.Number("1"), "tags": []interface{}{"x"}, "memo": nil, "old": "v"}
+	after := map[string]interface{}{"id": json.Number("1"), "tags": []interface{}{"x", "y"}, "memo": "备注", "new": 1}
+	assert.Equal(t, []string{"memo", "new", "old", "tags"}, changedColumns(before, after))
+	assert.Empty(t, changedColumns(before, before))
+}
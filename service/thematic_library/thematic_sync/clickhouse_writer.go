@@ -0,0 +1,154 @@
+/*
+ * @module service/thematic_sync/clickhouse_writer
+ * @description ClickHouse写入器，将处理后的数据按大批次写入ClickHouse存储的主题接口表
+ * @architecture 适配器模式 - ClickHouse写入策略
+ * @documentReference ai_docs/thematic_sync_design.md
+ * @stateFlow 加载接口和字段配置 -> 加密敏感列 -> 按字段类型转换 -> JSONEachRow批量INSERT -> 结果统计
+ * @rules 表引擎为ReplacingMergeTree，同主键的重复写入在后台合并时去重，不执行upsert；全量同步不删除源端已不存在的记录；
+ *        JSON字段序列化为字符串写入String列
+ * @dependencies datahub-service/service/clickhouse, datahub-service/service/encryption
+ * @refs data_writer.go, service/clickhouse/client.go
+ */
+
+package thematic_sync
+
+import (
+	"datahub-service/service/clickhouse"
+	"datahub-service/service/encryption"
+	"datahub-service/service/eventlog"
+	"datahub-service/service/meta"
+	"datahub-service/service/metrics"
+	"datahub-service/service/models"
+	"datahub-service/service/tracing"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+// clickHouseBatchSize ClickHouse每批写入行数，小批量写入会产生大量数据片段
+const clickHouseBatchSize = 5000
+
+// targetsClickHouse 目标接口表是否存储在ClickHouse
+func (dw *DataWriter) targetsClickHouse(interfaceID string) bool {
+	var engines []string
+	if err := dw.db.Model(&models.ThematicInterface{}).Where("id = ?", interfaceID).Pluck("storage_engine", &engines).Error; err != nil {
+		return false
+	}
+	return len(engines) > 0 && engines[0] == models.StorageEngineClickHouse
+}
+
+// writeDataToClickHouse 写入数据到ClickHouse接口表
+func (dw *DataWriter) writeDataToClickHouse(processedRecords []map[string]interface{}, request *SyncRequest, result *SyncExecutionResult) error {
+	client := clickhouse.Default()
+	if client == nil {
+		return fmt.Errorf("目标接口使用ClickHouse存储，但未配置ClickHouse")
+	}
+
+	var thematicInterface models.ThematicInterface
+	if err := dw.db.Preload("ThematicLibrary").First(&thematicInterface, "id = ?", request.TargetInterfaceID).Error; err != nil {
+		return fmt.Errorf("获取主题接口信息失败: %w", err)
+	}
+	if thematicInterface.ThematicLibrary.NameEn == "" || thematicInterface.NameEn == "" {
+		return fmt.Errorf("主题库或主题接口英文名为空")
+	}
+	if len(processedRecords) == 0 {
+		return nil
+	}
+
+	database := thematicInterface.ThematicLibrary.GetSchemaName()
+	tableName := thematicInterface.NameEn
+	fullTableName := fmt.Sprintf("%s.%s", database, tableName)
+
+	fieldConfigs, err := dw.getFieldConfigsFromInterface(&thematicInterface)
+	if err != nil {
+		slog.Warn("获取字段配置信息失败，使用默认转换", "error", err)
+		fieldConfigs = make(map[string]FieldConfig)
+	}
+
+	processedRecords, err = encryption.EncryptRows(request.Context, database, tableName, processedRecords)
+	if err != nil {
+		return fmt.Errorf("加密敏感列失败: %w", err)
+	}
+
+	var written int64
+	for i := 0; i < len(processedRecords); i += clickHouseBatchSize {
+		end := i + clickHouseBatchSize
+		if end > len(processedRecords) {
+			end = len(processedRecords)
+		}
+
+		rows := make([]map[string]interface{}, 0, end-i)
+		for _, record := range processedRecords[i:end] {
+			if row := dw.clickHouseRow(record, fieldConfigs); len(row) > 0 {
+				rows = append(rows, row)
+			}
+		}
+
+		batchStart := time.Now()
+		batchCtx, span := tracing.Start(request.Context, "sync.batch")
+		span.SetAttribute("db.system", "clickhouse")
+		span.SetAttribute("db.sql.table", fullTableName)
+		span.SetAttribute("sync.batch_size", len(rows))
+		err := client.Insert(batchCtx, database, tableName, rows)
+		span.RecordError(err)
+		span.End()
+		metrics.ObserveSyncBatch(meta.LibraryTypeThematic, time.Since(batchStart))
+		if err != nil {
+			eventlog.Record(request.Context, eventlog.Event{
+				Type:       eventlog.EventBatchFailed,
+				Level:      models.EventLevelError,
+				Message:    "主题库ClickHouse批次写入失败",
+				ObjectType: "thematic_table",
+				ObjectID:   fullTableName,
+				Attributes: map[string]interface{}{"batch_start": i, "batch_end": end - 1, "error": err.Error()},
+			})
+			return fmt.Errorf("ClickHouse批量写入失败 (batch %d-%d): %w", i, end-1, err)
+		}
+		written += int64(len(rows))
+	}
+
+	result.ProcessedRecordCount = int64(len(processedRecords))
+	result.InsertedRecordCount = written
+	return nil
+}
+
+// clickHouseRow 将记录转换为ClickHouse写入行
+func (dw *DataWriter) clickHouseRow(record map[string]interface{}, fieldConfigs map[string]FieldConfig) map[string]interface{} {
+	validRecord := dw.filterValidFields(record)
+	if len(validRecord) == 0 {
+		return nil
+	}
+	validRecord = dw.ensureRequiredFieldsByConfig(validRecord, fieldConfigs)
+
+	row := make(map[string]interface{}, len(validRecord))
+	for k, v := range validRecord {
+		if config, exists := fieldConfigs[k]; exists {
+			v = dw.convertValueByFieldType(v, config.DataType)
+		} else {
+			v = dw.convertValueForDatabase(v)
+		}
+		row[k] = clickHouseValue(v)
+	}
+	return row
+}
+
+// clickHouseValue 转换为JSONEachRow可直接解析的值
+func clickHouseValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case nil, bool, string, int, int32, int64, float32, float64, json.Number:
+		return v
+	case time.Time:
+		// 带时区偏移，由best_effort解析，不依赖ClickHouse服务端时区
+		return v.Format(time.RFC3339Nano)
+	case []byte:
+		return string(v)
+	default:
+		// map、切片等JSON值写入String列
+		data, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprint(v)
+		}
+		return string(data)
+	}
+}
@@ -0,0 +1,44 @@
+/*
+ * @module service/thematic_sync/clickhouse_writer_test
+ * @description ClickHouse写入行转换测试
+ * @architecture 单元测试 - 验证写入ClickHouse前的类型转换
+ * @documentReference ai_docs/thematic_sync_design.md
+ * @refs clickhouse_writer.go
+ */
+
+package thematic_sync
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestClickHouseRow 测试按字段配置转换并将JSON值序列化为字符串
+func TestClickHouseRow(t *testing.T) {
+	dw := &DataWriter{}
+	collectedAt := time.Date(2026, 5, 1, 8, 30, 0, 0, time.FixedZone("CST", 8*3600))
+	fieldConfigs := map[string]FieldConfig{
+		"device_id":    {NameEn: "device_id", DataType: "varchar", IsPrimaryKey: true},
+		"online":       {NameEn: "online", DataType: "boolean", IsNullable: true},
+		"collected_at": {NameEn: "collected_at", DataType: "timestamp", IsNullable: true},
+		"payload":      {NameEn: "payload", DataType: "jsonb", IsNullable: true},
+	}
+
+	row := dw.clickHouseRow(map[string]interface{}{
+		"device_id":    "d1",
+		"online":       "yes",
+		"collected_at": collectedAt,
+		"payload":      map[string]interface{}{"temp": 21.5},
+	}, fieldConfigs)
+
+	assert.Equal(t, map[string]interface{}{
+		"device_id":    "d1",
+		"online":       true,
+		"collected_at": "2026-05-01T08:30:00+08:00",
+		"payload":      `{"temp":21.5}`,
+	}, row)
+
+	assert.Nil(t, dw.clickHouseRow(map[string]interface{}{}, fieldConfigs))
+}
@@ -162,7 +162,7 @@ func (df *DataFetcher) FetchDataFromInterface(libraryID, interfaceID string) ([]
 	}
 
 	// 构建表名：基础库的name_en作为schema，基础接口的name_en作为表名
-	schema := dataInterface.BasicLibrary.NameEn
+	schema := dataInterface.BasicLibrary.GetSchemaName()
 	tableName := dataInterface.NameEn
 	fullTableName := fmt.Sprintf("%s.%s", schema, tableName)
 
@@ -201,7 +201,7 @@ func (df *DataFetcher) FetchDataFromInterfaceWithConfig(libraryID, interfaceID s
 	}
 
 	// 构建表名：基础库的name_en作为schema，基础接口的name_en作为表名
-	schema := dataInterface.BasicLibrary.NameEn
+	schema := dataInterface.BasicLibrary.GetSchemaName()
 	tableName := dataInterface.NameEn
 	fullTableName := fmt.Sprintf("%s.%s", schema, tableName)
 
@@ -1081,7 +1081,7 @@ func (df *DataFetcher) queryMaxIncrementalValueFromThematicTable(thematicInterfa
 	}
 
 	// 构建主题表名：主题库的name_en作为schema，主题接口的name_en作为表名
-	schema := thematicInterface.ThematicLibrary.NameEn
+	schema := thematicInterface.ThematicLibrary.GetSchemaName()
 	tableName := thematicInterface.NameEn
 	fullTableName := fmt.Sprintf("\"%s\".\"%s\"", schema, tableName)
 
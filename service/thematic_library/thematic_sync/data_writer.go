@@ -12,7 +12,14 @@
 package thematic_sync
 
 import (
+	"context"
+	"datahub-service/service/database"
+	"datahub-service/service/encryption"
+	"datahub-service/service/eventlog"
+	"datahub-service/service/meta"
+	"datahub-service/service/metrics"
 	"datahub-service/service/models"
+	"datahub-service/service/tracing"
 	"fmt"
 	"log/slog"
 	"strings"
@@ -29,6 +36,13 @@ type FieldConfig struct {
 	IsPrimaryKey bool        `json:"is_primary_key"`
 	IsNullable   bool        `json:"is_nullable"`
 	DefaultValue interface{} `json:"default_value"`
+	Expression   string      `json:"expression,omitempty"`
+	ComputeMode  string      `json:"compute_mode,omitempty"`
+}
+
+// IsComputed 是否为派生列，派生列由数据库生成或写入后刷新，不写入数据
+func (c FieldConfig) IsComputed() bool {
+	return c.Expression != ""
 }
 
 // DataWriter 数据写入器
@@ -55,6 +69,11 @@ func (dw *DataWriter) WriteData(processedRecords []map[string]interface{}, reque
 
 	slog.Debug("数据写入模式", "syncMode", syncMode, "recordCount", len(processedRecords))
 
+	// ClickHouse接口表按主键合并去重，不执行基于PostgreSQL的删除同步
+	if dw.targetsClickHouse(request.TargetInterfaceID) {
+		return dw.writeDataToClickHouse(processedRecords, request, result)
+	}
+
 	// 创建同步策略
 	strategy := dw.strategyFactory.CreateStrategy(syncMode)
 
@@ -121,7 +140,7 @@ func (dw *DataWriter) writeDataToTable(processedRecords []map[string]interface{}
 	}
 
 	// 构建表名：主题库的name_en作为schema，主题接口的name_en作为表名
-	schema := thematicInterface.ThematicLibrary.NameEn
+	schema := thematicInterface.ThematicLibrary.GetSchemaName()
 	tableName := thematicInterface.NameEn
 	fullTableName := fmt.Sprintf("%s.%s", schema, tableName)
 
@@ -140,8 +159,64 @@ func (dw *DataWriter) writeDataToTable(processedRecords []map[string]interface{}
 		fieldConfigs = make(map[string]FieldConfig)
 	}
 
+	// 加密敏感列
+	processedRecords, err = encryption.EncryptRows(request.Context, schema, tableName, processedRecords)
+	if err != nil {
+		return fmt.Errorf("加密敏感列失败: %w", err)
+	}
+
+	// 多个任务写入同一主题表时按合并策略裁决
+	resolver, err := newMergeResolver(request.Context, dw.db, request, primaryKeyFields)
+	if err != nil {
+		return err
+	}
+	if resolver != nil {
+		if processedRecords, err = resolver.Resolve(request.Context, processedRecords); err != nil {
+			return err
+		}
+	}
+
+	// 开启变更日志的接口记录每个批次的新增和修改
+	changelog, err := newChangelogRecorder(request.Context, dw.db, request, fullTableName, primaryKeyFields)
+	if err != nil {
+		return err
+	}
+
 	// 批量写入数据 - 支持批处理
-	return dw.batchWriteRecordsWithConfigs(fullTableName, primaryKeyFields, processedRecords, fieldConfigs, result)
+	if err := dw.batchWriteRecordsWithConfigs(request.Context, fullTableName, primaryKeyFields, processedRecords, fieldConfigs, changelog, result); err != nil {
+		return err
+	}
+	if changelog != nil {
+		changelog.prune(request.Context)
+	}
+
+	if resolver != nil {
+		if result.Merge, err = resolver.Commit(request.Context); err != nil {
+			return err
+		}
+	}
+
+	// 写入后刷新同步刷新的派生列
+	return dw.refreshComputedColumns(request.Context, fullTableName, fieldConfigs)
+}
+
+// refreshComputedColumns 按表达式刷新同步刷新的派生列，生成列由数据库维护
+func (dw *DataWriter) refreshComputedColumns(ctx context.Context, fullTableName string, fieldConfigs map[string]FieldConfig) error {
+	var computed []models.TableField
+	for fieldName, config := range fieldConfigs {
+		if config.IsComputed() {
+			computed = append(computed, models.TableField{NameEn: fieldName, Expression: config.Expression, ComputeMode: config.ComputeMode})
+		}
+	}
+	refreshSQL := database.BuildComputedRefreshSQL(fullTableName, computed)
+	if refreshSQL == "" {
+		return nil
+	}
+	slog.Debug("刷新派生列", "sql", refreshSQL)
+	if err := dw.db.WithContext(ctx).Exec(refreshSQL).Error; err != nil {
+		return fmt.Errorf("刷新派生列失败: %w", err)
+	}
+	return nil
 }
 
 // batchWriteRecords 批量写入记录
@@ -157,7 +232,9 @@ func (dw *DataWriter) batchWriteRecords(fullTableName string, primaryKeyFields [
 		}
 
 		batch := processedRecords[i:end]
+		batchStart := time.Now()
 		inserted, updated, err := dw.writeBatch(fullTableName, primaryKeyFields, batch)
+		metrics.ObserveSyncBatch(meta.LibraryTypeThematic, time.Since(batchStart))
 		if err != nil {
 			return fmt.Errorf("批量写入失败 (batch %d-%d): %w", i, end-1, err)
 		}
@@ -290,6 +367,8 @@ func (dw *DataWriter) getFieldConfigsFromInterface(thematicInterface *models.The
 					IsPrimaryKey: dw.getBoolFromMap(fieldMap, "is_primary_key"),
 					IsNullable:   dw.getBoolFromMap(fieldMap, "is_nullable"),
 					DefaultValue: fieldMap["default_value"],
+					Expression:   dw.getStringFromMap(fieldMap, "expression"),
+					ComputeMode:  dw.getStringFromMap(fieldMap, "compute_mode"),
 				}
 
 				// 使用name_en作为字段名，如果没有则使用fieldKey
@@ -311,8 +390,8 @@ func (dw *DataWriter) getFieldConfigsFromInterface(thematicInterface *models.The
 	return fieldConfigs, nil
 }
 
-// batchWriteRecordsWithConfigs 批量写入记录 - 支持字段配置
-func (dw *DataWriter) batchWriteRecordsWithConfigs(fullTableName string, primaryKeyFields []string, processedRecords []map[string]interface{}, fieldConfigs map[string]FieldConfig, result *SyncExecutionResult) error {
+// batchWriteRecordsWithConfigs 批量写入记录 - 支持字段配置，changelog不为空时记录每个批次的变更日志
+func (dw *DataWriter) batchWriteRecordsWithConfigs(ctx context.Context, fullTableName string, primaryKeyFields []string, processedRecords []map[string]interface{}, fieldConfigs map[string]FieldConfig, changelog *changelogRecorder, result *SyncExecutionResult) error {
 	batchSize := 100 // 批处理大小
 	insertedCount := int64(0)
 	updatedCount := int64(0)
@@ -324,8 +403,36 @@ func (dw *DataWriter) batchWriteRecordsWithConfigs(fullTableName string, primary
 		}
 
 		batch := processedRecords[i:end]
-		inserted, updated, err := dw.writeBatchWithConfigs(fullTableName, primaryKeyFields, batch, fieldConfigs)
+		batchStart := time.Now()
+		batchCtx, span := tracing.Start(ctx, "sync.batch")
+		span.SetAttribute("db.sql.table", fullTableName)
+		span.SetAttribute("sync.batch_size", len(batch))
+		var before map[string]map[string]interface{}
+		var err error
+		if changelog != nil {
+			before, err = changelog.snapshot(batchCtx, batch)
+		}
+		var inserted, updated int64
+		if err == nil {
+			inserted, updated, err = dw.writeBatchWithConfigs(batchCtx, fullTableName, primaryKeyFields, batch, fieldConfigs)
+		}
+		if err == nil && changelog != nil {
+			var recorded int64
+			recorded, err = changelog.recordBatch(batchCtx, batch, before)
+			result.ChangelogEntries += recorded
+		}
+		span.RecordError(err)
+		span.End()
+		metrics.ObserveSyncBatch(meta.LibraryTypeThematic, time.Since(batchStart))
 		if err != nil {
+			eventlog.Record(ctx, eventlog.Event{
+				Type:       eventlog.EventBatchFailed,
+				Level:      models.EventLevelError,
+				Message:    "主题库批次写入失败",
+				ObjectType: "thematic_table",
+				ObjectID:   fullTableName,
+				Attributes: map[string]interface{}{"batch_start": i, "batch_end": end - 1, "error": err.Error()},
+			})
 			return fmt.Errorf("批量写入失败 (batch %d-%d): %w", i, end-1, err)
 		}
 
@@ -341,7 +448,7 @@ func (dw *DataWriter) batchWriteRecordsWithConfigs(fullTableName string, primary
 }
 
 // writeBatchWithConfigs 写入一个批次的记录 - 支持字段配置
-func (dw *DataWriter) writeBatchWithConfigs(fullTableName string, primaryKeyFields []string, batch []map[string]interface{}, fieldConfigs map[string]FieldConfig) (int64, int64, error) {
+func (dw *DataWriter) writeBatchWithConfigs(ctx context.Context, fullTableName string, primaryKeyFields []string, batch []map[string]interface{}, fieldConfigs map[string]FieldConfig) (int64, int64, error) {
 	insertedCount := int64(0)
 	updatedCount := int64(0)
 
@@ -357,8 +464,13 @@ func (dw *DataWriter) writeBatchWithConfigs(fullTableName string, primaryKeyFiel
 			continue
 		}
 
-		// 根据字段配置确保必需字段有值
+		// 根据字段配置确保必需字段有值，派生列不写入
 		validRecord = dw.ensureRequiredFieldsByConfig(validRecord, fieldConfigs)
+		for fieldName, config := range fieldConfigs {
+			if config.IsComputed() {
+				delete(validRecord, fieldName)
+			}
+		}
 
 		// 构建插入SQL
 		columns := make([]string, 0, len(validRecord))
@@ -406,7 +518,7 @@ func (dw *DataWriter) writeBatchWithConfigs(fullTableName string, primaryKeyFiel
 		}
 
 		// 执行SQL
-		result := dw.db.Exec(sql, values...)
+		result := dw.db.WithContext(ctx).Exec(sql, values...)
 		if result.Error != nil {
 			return insertedCount, updatedCount, fmt.Errorf("写入数据到表 %s 失败: %w", fullTableName, result.Error)
 		}
@@ -433,7 +545,9 @@ func (dw *DataWriter) batchWriteRecordsWithTypes(fullTableName string, primaryKe
 		}
 
 		batch := processedRecords[i:end]
+		batchStart := time.Now()
 		inserted, updated, err := dw.writeBatchWithTypes(fullTableName, primaryKeyFields, batch, fieldTypes)
+		metrics.ObserveSyncBatch(meta.LibraryTypeThematic, time.Since(batchStart))
 		if err != nil {
 			return fmt.Errorf("批量写入失败 (batch %d-%d): %w", i, end-1, err)
 		}
@@ -547,6 +661,8 @@ func (dw *DataWriter) convertValueByFieldType(value interface{}, fieldType strin
 		return dw.convertToTimestamp(value)
 	case "jsonb", "json":
 		return dw.convertToJSON(value)
+	case models.GeoTypeGeometry, models.GeoTypeGeography:
+		return dw.convertToGeo(value)
 	default:
 		// 未知类型，使用默认转换
 		return dw.convertValueForDatabase(value)
@@ -685,6 +801,16 @@ func (dw *DataWriter) convertToJSON(value interface{}) interface{} {
 	return value
 }
 
+// convertToGeo 转换为空间列可写入的EWKT，基础库空间列读出的十六进制WKB原样写入
+func (dw *DataWriter) convertToGeo(value interface{}) interface{} {
+	geo, err := models.CoerceGeoValue(value)
+	if err != nil {
+		slog.Warn("空间数据转换失败", "error", err)
+		return dw.convertValueForDatabase(value)
+	}
+	return geo
+}
+
 // getStringFromMap 从map中获取字符串值
 func (dw *DataWriter) getStringFromMap(m map[string]interface{}, key string) string {
 	if value, exists := m[key]; exists {
@@ -743,8 +869,8 @@ func (dw *DataWriter) ensureRequiredFields(record map[string]interface{}, fieldT
 func (dw *DataWriter) ensureRequiredFieldsByConfig(record map[string]interface{}, fieldConfigs map[string]FieldConfig) map[string]interface{} {
 	// 遍历所有字段配置，检查非空字段
 	for fieldName, config := range fieldConfigs {
-		// 如果字段不可为空，确保有值
-		if !config.IsNullable {
+		// 如果字段不可为空，确保有值；派生列的值由表达式计算
+		if !config.IsNullable && !config.IsComputed() {
 			needsDefault := false
 			currentValue := record[fieldName]
 
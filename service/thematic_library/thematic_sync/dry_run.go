@@ -0,0 +1,225 @@
+/*
+ * @module service/thematic_sync/dry_run
+ * @description 同步任务试运行，完整执行获取、映射、治理流程后仅模拟写入，输出预计新增/更新/删除/拒绝统计和样例数据
+ * @architecture 与DataWriter共用字段配置、主键和值转换逻辑，保证试运行结论与真实写入一致
+ * @documentReference ai_docs/thematic_sync_design.md
+ * @stateFlow 解析目标表 -> 敏感列加密 -> 逐条校验 -> 比对现有主键 -> 生成试运行报告
+ * @rules 试运行只读目标表主键，不写入数据、不删除记录、不记录血缘、不更新任务统计
+ * @dependencies gorm.io/gorm, service/encryption
+ * @refs data_writer.go, sync_strategy.go, sync_engine.go
+ */
+
+package thematic_sync
+
+import (
+	"datahub-service/service/encryption"
+	"datahub-service/service/models"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// dryRunSampleLimit 试运行报告中保留的样例条数上限
+const dryRunSampleLimit = 20
+
+// 试运行拒绝原因
+const (
+	DryRunRejectNoValidFields     = "no_valid_fields"
+	DryRunRejectMissingPrimaryKey = "missing_primary_key"
+	DryRunRejectNullNotAllowed    = "null_not_allowed"
+	DryRunRejectUnknownColumn     = "unknown_column"
+	DryRunRejectInvalidValue      = "invalid_value"
+)
+
+// isDryRun 判断请求是否为试运行
+func isDryRun(request *SyncRequest) bool {
+	if request == nil || request.Config == nil {
+		return false
+	}
+	dryRun, _ := request.Config["dry_run"].(bool)
+	return dryRun
+}
+
+// DryRunWrite 模拟写入，按真实写入的规则校验记录并统计预计变更，不修改目标表
+func (dw *DataWriter) DryRunWrite(processedRecords []map[string]interface{}, request *SyncRequest, governanceResult *GovernanceExecutionResult) (*DryRunReport, error) {
+	var thematicInterface models.ThematicInterface
+	if err := dw.db.Preload("ThematicLibrary").First(&thematicInterface, "id = ?", request.TargetInterfaceID).Error; err != nil {
+		return nil, fmt.Errorf("获取主题接口信息失败: %w", err)
+	}
+	if thematicInterface.ThematicLibrary.NameEn == "" {
+		return nil, fmt.Errorf("主题库英文名为空")
+	}
+	if thematicInterface.NameEn == "" {
+		return nil, fmt.Errorf("主题接口英文名为空")
+	}
+
+	schema := thematicInterface.ThematicLibrary.GetSchemaName()
+	tableName := thematicInterface.NameEn
+	fullTableName := fmt.Sprintf("%s.%s", schema, tableName)
+	primaryKeyFields := dw.getThematicPrimaryKeyFields(&thematicInterface)
+
+	fieldConfigs, err := dw.getFieldConfigsFromInterface(&thematicInterface)
+	if err != nil {
+		fieldConfigs = make(map[string]FieldConfig)
+	}
+
+	// 与真实写入一致先加密，样例中不会出现敏感列明文
+	records, err := encryption.EncryptRows(request.Context, schema, tableName, processedRecords)
+	if err != nil {
+		return nil, fmt.Errorf("加密敏感列失败: %w", err)
+	}
+
+	syncMode := dw.determineSyncMode(request)
+	clickHouse := thematicInterface.IsClickHouse()
+
+	// ClickHouse按主键合并去重，无法廉价区分新增与更新，全部按新增统计
+	var existingKeys map[string]bool
+	if len(primaryKeyFields) > 0 && !clickHouse {
+		ids, err := NewFullSyncStrategy(dw.db).getExistingRecordIDs(fullTableName, primaryKeyFields)
+		if err != nil {
+			return nil, fmt.Errorf("获取现有记录ID失败: %w", err)
+		}
+		existingKeys = make(map[string]bool, len(ids))
+		for _, id := range ids {
+			existingKeys[id] = true
+		}
+	}
+
+	report := dw.planDryRun(records, primaryKeyFields, fieldConfigs, existingKeys, syncMode == "full" && !clickHouse)
+	report.SyncMode = syncMode
+	report.TargetTable = fullTableName
+	if governanceResult != nil {
+		report.ValidationErrorCount = governanceResult.TotalValidationErrors
+	}
+	return report, nil
+}
+
+// planDryRun 逐条校验记录并与现有主键比对，countDeletes为true时统计全量同步将删除的记录
+func (dw *DataWriter) planDryRun(records []map[string]interface{}, primaryKeyFields []string, fieldConfigs map[string]FieldConfig, existingKeys map[string]bool, countDeletes bool) *DryRunReport {
+	report := &DryRunReport{
+		RejectReasons:   make(map[string]int64),
+		SampleRows:      make([]map[string]interface{}, 0),
+		RejectedSamples: make([]DryRunRejectedRow, 0),
+	}
+	keyExtractor := &FullSyncStrategy{}
+	seenKeys := make(map[string]bool)
+
+	for _, record := range records {
+		if len(record) == 0 {
+			continue
+		}
+
+		validRecord := dw.filterValidFields(record)
+		if len(validRecord) == 0 {
+			report.reject(DryRunRejectNoValidFields, "记录中没有有效字段", record)
+			continue
+		}
+		validRecord = dw.ensureRequiredFieldsByConfig(validRecord, fieldConfigs)
+
+		row, reason, message := dw.checkDryRunRecord(validRecord, primaryKeyFields, fieldConfigs)
+		if reason != "" {
+			report.reject(reason, message, validRecord)
+			continue
+		}
+
+		// 同一批次内主键重复的记录按更新统计，与ON CONFLICT DO UPDATE的效果一致
+		key := keyExtractor.extractPrimaryKey(row, primaryKeyFields)
+		if key != "" && (existingKeys[key] || seenKeys[key]) {
+			report.UpdateCount++
+		} else {
+			report.InsertCount++
+		}
+		if key != "" {
+			seenKeys[key] = true
+		}
+
+		if len(report.SampleRows) < dryRunSampleLimit {
+			report.SampleRows = append(report.SampleRows, row)
+		}
+	}
+
+	if countDeletes && len(primaryKeyFields) > 0 {
+		// 删除判定与FullSyncStrategy一致：以全部源记录的主键为准，不受本地校验结果影响
+		sourceKeys := make(map[string]bool, len(records))
+		for _, record := range records {
+			if key := keyExtractor.extractPrimaryKey(record, primaryKeyFields); key != "" {
+				sourceKeys[key] = true
+			}
+		}
+		for key := range existingKeys {
+			if !sourceKeys[key] {
+				report.DeleteCount++
+			}
+		}
+	}
+
+	return report
+}
+
+// checkDryRunRecord 按字段配置转换记录，返回转换后的行；不可写入时返回拒绝原因和说明
+func (dw *DataWriter) checkDryRunRecord(record map[string]interface{}, primaryKeyFields []string, fieldConfigs map[string]FieldConfig) (map[string]interface{}, string, string) {
+	// 缺失的主键会被默认值补成空字符串，真实写入时这些记录会互相覆盖，试运行按缺失主键拒绝
+	for _, field := range primaryKeyFields {
+		value, exists := record[field]
+		if str, ok := value.(string); !exists || value == nil || (ok && strings.TrimSpace(str) == "") {
+			return nil, DryRunRejectMissingPrimaryKey, fmt.Sprintf("主键字段 %s 缺失", field)
+		}
+	}
+
+	row := make(map[string]interface{}, len(record))
+	for column, value := range record {
+		config, configured := fieldConfigs[column]
+		if !configured {
+			// 未配置字段时无法判断列是否存在，交给数据库处理
+			if len(fieldConfigs) > 0 {
+				return nil, DryRunRejectUnknownColumn, fmt.Sprintf("目标表不存在字段 %s", column)
+			}
+			row[column] = dw.convertValueForDatabase(value)
+			continue
+		}
+
+		converted := dw.convertValueByFieldType(value, config.DataType)
+		if converted == nil && !config.IsNullable {
+			return nil, DryRunRejectNullNotAllowed, fmt.Sprintf("字段 %s 不允许为空", column)
+		}
+		if err := checkDryRunValue(converted, config.DataType); err != nil {
+			return nil, DryRunRejectInvalidValue, fmt.Sprintf("字段 %s: %v", column, err)
+		}
+		row[column] = converted
+	}
+
+	return row, "", ""
+}
+
+// checkDryRunValue 校验转换后仍为字符串的数值，真实写入时这类值交给数据库转换，失败会导致整批写入报错
+func checkDryRunValue(value interface{}, dataType string) error {
+	str, ok := value.(string)
+	if !ok {
+		return nil
+	}
+
+	switch strings.ToLower(dataType) {
+	case "int", "integer", "int4", "bigint", "int8":
+		if _, err := strconv.ParseInt(strings.TrimSpace(str), 10, 64); err != nil {
+			return fmt.Errorf("值 %q 不是有效的整数", str)
+		}
+	case "float", "float4", "float8", "decimal", "numeric":
+		if _, err := strconv.ParseFloat(strings.TrimSpace(str), 64); err != nil {
+			return fmt.Errorf("值 %q 不是有效的数值", str)
+		}
+	}
+	return nil
+}
+
+// reject 记录一条被拒绝的记录
+func (r *DryRunReport) reject(reason, message string, record map[string]interface{}) {
+	r.RejectCount++
+	r.RejectReasons[reason]++
+	if len(r.RejectedSamples) < dryRunSampleLimit {
+		r.RejectedSamples = append(r.RejectedSamples, DryRunRejectedRow{
+			Reason:  reason,
+			Message: message,
+			Record:  record,
+		})
+	}
+}
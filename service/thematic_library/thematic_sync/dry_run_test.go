@@ -0,0 +1,68 @@
+/*
+ * @module service/thematic_sync/dry_run_test
+ * @description 试运行规划测试，覆盖新增/更新/删除统计、拒绝原因和样例输出
+ * @architecture 单元测试 - 不依赖数据库，直接验证planDryRun
+ * @documentReference ai_docs/thematic_sync_design.md
+ * @refs dry_run.go
+ */
+
+package thematic_sync
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestPlanDryRun 测试试运行按主键区分新增与更新并统计拒绝记录
+func TestPlanDryRun(t *testing.T) {
+	dw := &DataWriter{}
+	fieldConfigs := map[string]FieldConfig{
+		"device_id": {NameEn: "device_id", DataType: "varchar", IsPrimaryKey: true},
+		"reading":   {NameEn: "reading", DataType: "integer", IsNullable: true},
+		"remark":    {NameEn: "remark", DataType: "text", IsNullable: true},
+	}
+	existing := map[string]bool{"d1": true, "d9": true}
+
+	records := []map[string]interface{}{
+		{"device_id": "d1", "reading": "12"},
+		{"device_id": "d2", "reading": 3.0},
+		{"device_id": "d2", "reading": 4.0},
+		{"reading": 5},
+		{"device_id": "d3", "reading": "abc"},
+		{"device_id": "d4", "color": "red"},
+	}
+
+	report := dw.planDryRun(records, []string{"device_id"}, fieldConfigs, existing, true)
+
+	assert.Equal(t, int64(1), report.InsertCount)
+	assert.Equal(t, int64(2), report.UpdateCount, "已存在的主键和批次内重复主键都按更新统计")
+	assert.Equal(t, int64(3), report.RejectCount)
+	assert.Equal(t, map[string]int64{
+		DryRunRejectMissingPrimaryKey: 1,
+		DryRunRejectInvalidValue:      1,
+		DryRunRejectUnknownColumn:     1,
+	}, report.RejectReasons)
+	assert.Equal(t, int64(1), report.DeleteCount, "d9不在源数据中，全量同步会删除")
+	assert.Len(t, report.SampleRows, 3)
+	assert.Equal(t, map[string]interface{}{"device_id": "d2", "reading": 3}, report.SampleRows[1])
+	assert.Len(t, report.RejectedSamples, 3)
+
+	incremental := dw.planDryRun(records, []string{"device_id"}, fieldConfigs, existing, false)
+	assert.Zero(t, incremental.DeleteCount, "增量同步不删除记录")
+}
+
+// TestPlanDryRunSampleLimit 测试样例条数受上限约束
+func TestPlanDryRunSampleLimit(t *testing.T) {
+	dw := &DataWriter{}
+	records := make([]map[string]interface{}, dryRunSampleLimit+5)
+	for i := range records {
+		records[i] = map[string]interface{}{"id": i}
+	}
+
+	report := dw.planDryRun(records, nil, nil, nil, false)
+
+	assert.Equal(t, int64(len(records)), report.InsertCount)
+	assert.Len(t, report.SampleRows, dryRunSampleLimit)
+	assert.Empty(t, report.RejectReasons)
+}
@@ -15,6 +15,7 @@ import (
 	"datahub-service/service/models"
 	"fmt"
 	"log/slog"
+	"sort"
 	"strings"
 	"time"
 
@@ -174,20 +175,49 @@ func (fm *FieldMapper) parseFieldMappingRules(fieldMappingRules interface{}) (ma
 	return mappingRules, nil
 }
 
+// 目标字段取值来源
+const (
+	FieldOriginMappingRule   = "mapping_rule"   // 字段映射规则
+	FieldOriginSameName      = "same_name"      // 源记录中的同名字段
+	FieldOriginNameZh        = "name_zh"        // 源记录中与中文名同名的字段
+	FieldOriginDefaultValue  = "default_value"  // 字段配置的默认值
+	FieldOriginSystemDefault = "system_default" // 必需字段的系统默认值
+	FieldOriginNull          = "null"           // 源记录中没有对应字段，置空
+)
+
+// FieldMappingTrace 目标字段的取值来源
+type FieldMappingTrace struct {
+	Origin      string `json:"origin"`
+	SourceField string `json:"source_field,omitempty"`
+	DataType    string `json:"data_type,omitempty"`
+}
+
 // mapSingleRecord 映射单条记录
 func (fm *FieldMapper) mapSingleRecord(
 	sourceRecord map[string]interface{},
 	targetFields map[string]TargetFieldInfo,
 	mappingRules map[string]string,
 ) (map[string]interface{}, error) {
+	mappedRecord, _, err := fm.mapSingleRecordWithTrace(sourceRecord, targetFields, mappingRules)
+	return mappedRecord, err
+}
+
+// mapSingleRecordWithTrace 映射单条记录，同时返回每个目标字段的取值来源
+func (fm *FieldMapper) mapSingleRecordWithTrace(
+	sourceRecord map[string]interface{},
+	targetFields map[string]TargetFieldInfo,
+	mappingRules map[string]string,
+) (map[string]interface{}, map[string]FieldMappingTrace, error) {
 
 	mappedRecord := make(map[string]interface{})
+	traces := make(map[string]FieldMappingTrace, len(targetFields))
 	var missingFields []string
 
 	// 遍历目标字段，进行映射
 	for targetFieldName, targetFieldInfo := range targetFields {
 		var value interface{}
 		var found bool
+		trace := FieldMappingTrace{DataType: targetFieldInfo.DataType}
 
 		// 1. 首先检查是否有显式的映射规则
 		for sourceField, mappedTarget := range mappingRules {
@@ -195,6 +225,8 @@ func (fm *FieldMapper) mapSingleRecord(
 				if sourceValue, exists := sourceRecord[sourceField]; exists {
 					value = sourceValue
 					found = true
+					trace.Origin = FieldOriginMappingRule
+					trace.SourceField = sourceField
 					break
 				}
 			}
@@ -205,6 +237,8 @@ func (fm *FieldMapper) mapSingleRecord(
 			if sourceValue, exists := sourceRecord[targetFieldName]; exists {
 				value = sourceValue
 				found = true
+				trace.Origin = FieldOriginSameName
+				trace.SourceField = targetFieldName
 			}
 		}
 
@@ -213,6 +247,8 @@ func (fm *FieldMapper) mapSingleRecord(
 			if sourceValue, exists := sourceRecord[targetFieldInfo.NameZh]; exists {
 				value = sourceValue
 				found = true
+				trace.Origin = FieldOriginNameZh
+				trace.SourceField = targetFieldInfo.NameZh
 			}
 		}
 
@@ -222,12 +258,14 @@ func (fm *FieldMapper) mapSingleRecord(
 				// 使用默认值
 				value = targetFieldInfo.DefaultValue
 				found = true
+				trace.Origin = FieldOriginDefaultValue
 			} else if targetFieldInfo.Required && !targetFieldInfo.IsNullable {
 				// 必需字段但没有找到值，尝试提供系统默认值
 				defaultValue := fm.getSystemDefaultValue(targetFieldName, targetFieldInfo.DataType)
 				if defaultValue != nil {
 					value = defaultValue
 					found = true
+					trace.Origin = FieldOriginSystemDefault
 					slog.Debug("为必需字段使用系统默认值", "field", targetFieldName, "value", defaultValue)
 				} else {
 					// 无法提供默认值的必需字段
@@ -237,6 +275,7 @@ func (fm *FieldMapper) mapSingleRecord(
 			} else {
 				// 可空字段，设置为nil
 				value = nil
+				trace.Origin = FieldOriginNull
 			}
 		}
 
@@ -248,14 +287,16 @@ func (fm *FieldMapper) mapSingleRecord(
 		}
 
 		mappedRecord[targetFieldName] = convertedValue
+		traces[targetFieldName] = trace
 	}
 
 	// 检查是否有缺失的必需字段
 	if len(missingFields) > 0 {
-		return nil, fmt.Errorf("缺失必需字段: %s", strings.Join(missingFields, ", "))
+		sort.Strings(missingFields)
+		return nil, nil, fmt.Errorf("缺失必需字段: %s", strings.Join(missingFields, ", "))
 	}
 
-	return mappedRecord, nil
+	return mappedRecord, traces, nil
 }
 
 // convertFieldValue 转换字段值类型
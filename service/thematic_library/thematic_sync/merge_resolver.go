@@ -0,0 +1,487 @@
+/*
+ * @module service/thematic_sync/merge_resolver
+ * @description 主题表多源写入合并，按主题接口的合并策略和记录来源决定本任务的每条记录哪些字段可以写入，被拒绝的写入记为合并冲突
+ * @architecture 策略模式 - 按合并策略裁决每条记录
+ * @documentReference ai_docs/thematic_sync_design.md
+ * @stateFlow 读取启用的合并策略 -> 按主键分块读取记录来源 -> 逐条裁决(整条写入/部分字段写入/跳过) -> 写入主题表 -> 更新记录来源 -> 合并同类未处理冲突并记录
+ * @rules 没有来源的记录（新记录或开启策略前写入的记录）直接写入；同一任务可以覆盖自己写入的数据；
+ *        source_priority按任务顺序整条裁决；latest_timestamp比较时间字段，缺少或无法解析时间的记录不能覆盖其他任务的数据；
+ *        field_precedence按字段裁决，空值不覆盖其他任务写入的字段且不记冲突；主键字段总是保留
+ * @dependencies gorm.io/gorm, datahub-service/service/models
+ * @refs data_writer.go, sync_strategy.go, service/models/thematic_merge.go
+ */
+
+package thematic_sync
+
+import (
+	"context"
+	"datahub-service/service/models"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cast"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// mergeLookupChunk 按主键读取记录来源和冲突时每条语句的主键个数
+const mergeLookupChunk = 500
+
+// MergeReport 一次同步的多源合并统计
+type MergeReport struct {
+	Strategy        string `json:"strategy"`
+	AcceptedRecords int64  `json:"accepted_records"` // 全部字段写入的记录
+	PartialRecords  int64  `json:"partial_records"`  // 部分字段被拒绝的记录
+	SkippedRecords  int64  `json:"skipped_records"`  // 全部字段被拒绝、未写入的记录
+	Conflicts       int64  `json:"conflicts"`        // 本次记录的冲突数（每条记录每个胜出任务一条）
+}
+
+// mergeConflict 一条记录中因同一胜出任务被拒绝的字段
+type mergeConflict struct {
+	winner string
+	fields []string
+}
+
+// mergeDecision 一条记录的裁决结果
+type mergeDecision struct {
+	record    map[string]interface{} // 要写入的字段，nil表示跳过
+	rejected  int                    // 被拒绝的字段数
+	conflicts []mergeConflict
+	source    *models.ThematicRecordSource // 写入后的记录来源，跳过时为nil
+}
+
+// mergeResolver 一次同步写入的合并裁决器
+type mergeResolver struct {
+	db          *gorm.DB
+	policy      *models.ThematicMergePolicy
+	interfaceID string
+	taskID      string
+	executionID string
+	primaryKeys []string
+	report      MergeReport
+
+	pendingSources   []models.ThematicRecordSource
+	pendingConflicts []pendingMergeConflict
+}
+
+// pendingMergeConflict 待记录的冲突
+type pendingMergeConflict struct {
+	recordKey string
+	conflict  mergeConflict
+	values    models.JSONB
+}
+
+// newMergeResolver 读取主题接口启用的合并策略，没有时返回nil
+func newMergeResolver(ctx context.Context, db *gorm.DB, request *SyncRequest, primaryKeys []string) (*mergeResolver, error) {
+	// 大多数主题接口没有合并策略，用Find避免每次同步都打印记录不存在
+	var policies []models.ThematicMergePolicy
+	if err := db.WithContext(ctx).Where("thematic_interface_id = ? AND enabled = ?", request.TargetInterfaceID, true).
+		Limit(1).Find(&policies).Error; err != nil {
+		return nil, fmt.Errorf("获取合并策略失败: %w", err)
+	}
+	if len(policies) == 0 {
+		return nil, nil
+	}
+	policy := policies[0]
+	if len(primaryKeys) == 0 {
+		return nil, fmt.Errorf("主题接口没有主键，无法按合并策略写入")
+	}
+	return &mergeResolver{
+		db:          db,
+		policy:      &policy,
+		interfaceID: request.TargetInterfaceID,
+		taskID:      request.TaskID,
+		executionID: request.ExecutionID,
+		primaryKeys: primaryKeys,
+		report:      MergeReport{Strategy: policy.Strategy},
+	}, nil
+}
+
+// Resolve 裁决全部记录，返回要写入的记录（可能只含部分字段）
+func (m *mergeResolver) Resolve(ctx context.Context, records []map[string]interface{}) ([]map[string]interface{}, error) {
+	result := make([]map[string]interface{}, 0, len(records))
+	now := time.Now()
+	for start := 0; start < len(records); start += mergeLookupChunk {
+		end := start + mergeLookupChunk
+		if end > len(records) {
+			end = len(records)
+		}
+		chunk := records[start:end]
+
+		keys := make([]string, 0, len(chunk))
+		for _, record := range chunk {
+			if key := mergeRecordKey(record, m.primaryKeys); key != "" {
+				keys = append(keys, key)
+			}
+		}
+		var sources []models.ThematicRecordSource
+		if err := m.db.WithContext(ctx).Where("thematic_interface_id = ? AND record_key IN ?", m.interfaceID, keys).Find(&sources).Error; err != nil {
+			return nil, fmt.Errorf("读取记录来源失败: %w", err)
+		}
+		existing := make(map[string]*models.ThematicRecordSource, len(sources))
+		for i := range sources {
+			existing[sources[i].RecordKey] = &sources[i]
+		}
+
+		for _, record := range chunk {
+			key := mergeRecordKey(record, m.primaryKeys)
+			if key == "" {
+				// 主键不完整的记录不做合并裁决，交给写入时报错
+				result = append(result, record)
+				continue
+			}
+			decision := resolveMergeRecord(m.policy, m.taskID, record, existing[key], m.primaryKeys, now)
+			m.collect(key, record, decision)
+			if decision.record != nil {
+				result = append(result, decision.record)
+				// 同一批中主键重复时后面的记录按本次写入后的来源裁决
+				existing[key] = decision.source
+			}
+		}
+	}
+	return result, nil
+}
+
+// collect 累计裁决统计和待记录的来源、冲突
+func (m *mergeResolver) collect(key string, record map[string]interface{}, decision mergeDecision) {
+	switch {
+	case decision.record == nil:
+		m.report.SkippedRecords++
+	case decision.rejected > 0:
+		m.report.PartialRecords++
+	default:
+		m.report.AcceptedRecords++
+	}
+	if decision.source != nil {
+		decision.source.ThematicInterfaceID = m.interfaceID
+		decision.source.RecordKey = key
+		m.pendingSources = append(m.pendingSources, *decision.source)
+	}
+	for _, conflict := range decision.conflicts {
+		values := models.JSONB{}
+		for _, field := range conflict.fields {
+			values[field] = record[field]
+		}
+		m.pendingConflicts = append(m.pendingConflicts, pendingMergeConflict{recordKey: key, conflict: conflict, values: values})
+	}
+	m.report.Conflicts += int64(len(decision.conflicts))
+}
+
+// Commit 写入主题表后保存记录来源和冲突，返回合并统计
+func (m *mergeResolver) Commit(ctx context.Context) (*MergeReport, error) {
+	db := m.db.WithContext(ctx)
+	if len(m.pendingSources) > 0 {
+		sources := dedupeRecordSources(m.pendingSources)
+		if err := db.Clauses(clause.OnConflict{UpdateAll: true}).CreateInBatches(&sources, 200).Error; err != nil {
+			return nil, fmt.Errorf("保存记录来源失败: %w", err)
+		}
+	}
+	if err := m.saveConflicts(ctx); err != nil {
+		return nil, err
+	}
+	if m.report.Conflicts > 0 {
+		slog.Info("主题表多源写入存在合并冲突", "interface_id", m.interfaceID, "task_id", m.taskID,
+			"strategy", m.policy.Strategy, "conflicts", m.report.Conflicts, "skipped", m.report.SkippedRecords)
+	}
+	report := m.report
+	return &report, nil
+}
+
+// saveConflicts 同一记录、落败任务和胜出任务已有未处理冲突时累加次数，否则新建
+func (m *mergeResolver) saveConflicts(ctx context.Context) error {
+	db := m.db.WithContext(ctx)
+	now := time.Now()
+	for start := 0; start < len(m.pendingConflicts); start += mergeLookupChunk {
+		end := start + mergeLookupChunk
+		if end > len(m.pendingConflicts) {
+			end = len(m.pendingConflicts)
+		}
+		chunk := m.pendingConflicts[start:end]
+		keys := make([]string, len(chunk))
+		for i, pending := range chunk {
+			keys[i] = pending.recordKey
+		}
+
+		var open []models.ThematicMergeConflict
+		if err := db.Where("thematic_interface_id = ? AND losing_task_id = ? AND status = ? AND record_key IN ?",
+			m.interfaceID, m.taskID, models.MergeConflictOpen, keys).Find(&open).Error; err != nil {
+			return fmt.Errorf("读取合并冲突失败: %w", err)
+		}
+		byKey := make(map[string]*models.ThematicMergeConflict, len(open))
+		for i := range open {
+			byKey[open[i].RecordKey+"\x00"+open[i].WinningTaskID] = &open[i]
+		}
+
+		for _, pending := range chunk {
+			if existing, ok := byKey[pending.recordKey+"\x00"+pending.conflict.winner]; ok {
+				existing.Fields = pending.conflict.fields
+				existing.IncomingValues = pending.values
+				existing.Occurrences++
+				existing.LastExecutionID = m.executionID
+				existing.LastSeenAt = now
+				if err := db.Save(existing).Error; err != nil {
+					return fmt.Errorf("更新合并冲突失败: %w", err)
+				}
+				continue
+			}
+			conflict := &models.ThematicMergeConflict{
+				ThematicInterfaceID: m.interfaceID,
+				RecordKey:           pending.recordKey,
+				Strategy:            m.policy.Strategy,
+				LosingTaskID:        m.taskID,
+				WinningTaskID:       pending.conflict.winner,
+				Fields:              pending.conflict.fields,
+				IncomingValues:      pending.values,
+				Occurrences:         1,
+				LastExecutionID:     m.executionID,
+				Status:              models.MergeConflictOpen,
+				FirstSeenAt:         now,
+				LastSeenAt:          now,
+			}
+			if err := db.Create(conflict).Error; err != nil {
+				return fmt.Errorf("记录合并冲突失败: %w", err)
+			}
+			byKey[pending.recordKey+"\x00"+pending.conflict.winner] = conflict
+		}
+	}
+	return nil
+}
+
+// dedupeRecordSources 同一主键保留最后一次的来源
+func dedupeRecordSources(sources []models.ThematicRecordSource) []models.ThematicRecordSource {
+	index := make(map[string]int, len(sources))
+	result := make([]models.ThematicRecordSource, 0, len(sources))
+	for _, source := range sources {
+		if i, ok := index[source.RecordKey]; ok {
+			result[i] = source
+			continue
+		}
+		index[source.RecordKey] = len(result)
+		result = append(result, source)
+	}
+	return result
+}
+
+// filterMergeOwnedKeys 全量同步删除前过滤主键：启用合并策略时只保留来源和全部字段都是本任务的记录，
+// 没有来源的记录无法确定归属，不删除
+func filterMergeOwnedKeys(ctx context.Context, db *gorm.DB, request *SyncRequest, keys []string) ([]string, error) {
+	if len(keys) == 0 {
+		return keys, nil
+	}
+	var enabled int64
+	if err := db.WithContext(ctx).Model(&models.ThematicMergePolicy{}).
+		Where("thematic_interface_id = ? AND enabled = ?", request.TargetInterfaceID, true).Count(&enabled).Error; err != nil {
+		return nil, fmt.Errorf("获取合并策略失败: %w", err)
+	}
+	if enabled == 0 {
+		return keys, nil
+	}
+
+	owned := make([]string, 0, len(keys))
+	for start := 0; start < len(keys); start += mergeLookupChunk {
+		end := start + mergeLookupChunk
+		if end > len(keys) {
+			end = len(keys)
+		}
+		var sources []models.ThematicRecordSource
+		if err := db.WithContext(ctx).Where("thematic_interface_id = ? AND record_key IN ?", request.TargetInterfaceID, keys[start:end]).
+			Find(&sources).Error; err != nil {
+			return nil, fmt.Errorf("读取记录来源失败: %w", err)
+		}
+		for _, source := range sources {
+			if ownedByTask(&source, request.TaskID) {
+				owned = append(owned, source.RecordKey)
+			}
+		}
+	}
+	if skipped := len(keys) - len(owned); skipped > 0 {
+		slog.Info("全量同步保留其他任务写入的记录", "interface_id", request.TargetInterfaceID, "task_id", request.TaskID, "kept", skipped)
+	}
+	return owned, nil
+}
+
+// ownedByTask 记录和全部字段是否都由该任务写入
+func ownedByTask(source *models.ThematicRecordSource, taskID string) bool {
+	if source.SourceTaskID != taskID {
+		return false
+	}
+	for _, task := range source.FieldSources {
+		if cast.ToString(task) != taskID {
+			return false
+		}
+	}
+	return true
+}
+
+// removeRecordSources 删除已删除记录的来源
+func removeRecordSources(ctx context.Context, db *gorm.DB, interfaceID string, keys []string) error {
+	for start := 0; start < len(keys); start += mergeLookupChunk {
+		end := start + mergeLookupChunk
+		if end > len(keys) {
+			end = len(keys)
+		}
+		if err := db.WithContext(ctx).Where("thematic_interface_id = ? AND record_key IN ?", interfaceID, keys[start:end]).
+			Delete(&models.ThematicRecordSource{}).Error; err != nil {
+			return fmt.Errorf("删除记录来源失败: %w", err)
+		}
+	}
+	return nil
+}
+
+// resolveMergeRecord 按合并策略裁决一条记录，current为记录已有的来源（没有时为nil）
+func resolveMergeRecord(policy *models.ThematicMergePolicy, taskID string, record map[string]interface{}, current *models.ThematicRecordSource, primaryKeys []string, now time.Time) mergeDecision {
+	isKey := make(map[string]bool, len(primaryKeys))
+	for _, key := range primaryKeys {
+		isKey[key] = true
+	}
+
+	source := &models.ThematicRecordSource{SourceTaskID: taskID, FieldSources: models.JSONB{}, UpdatedAt: now}
+	if current != nil {
+		source.SourceTimestamp = current.SourceTimestamp
+		for field, task := range current.FieldSources {
+			source.FieldSources[field] = task
+		}
+	}
+	accept := func(fields []string) mergeDecision {
+		for _, field := range fields {
+			if !isKey[field] {
+				source.FieldSources[field] = taskID
+			}
+		}
+		return mergeDecision{record: record, source: source}
+	}
+	rejectAll := func(winner string) mergeDecision {
+		var fields []string
+		for field := range record {
+			if !isKey[field] {
+				fields = append(fields, field)
+			}
+		}
+		sort.Strings(fields)
+		return mergeDecision{rejected: len(fields), conflicts: []mergeConflict{{winner: winner, fields: fields}}}
+	}
+
+	fields := make([]string, 0, len(record))
+	for field := range record {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	if current == nil {
+		if policy.Strategy == models.MergeStrategyLatestTimestamp {
+			source.SourceTimestamp = mergeTimestamp(record[policy.TimestampField])
+		}
+		return accept(fields)
+	}
+
+	switch policy.Strategy {
+	case models.MergeStrategySourcePriority:
+		if current.SourceTaskID == taskID || mergeRank(policy.SourcePriority, taskID) <= mergeRank(policy.SourcePriority, current.SourceTaskID) {
+			return accept(fields)
+		}
+		return rejectAll(current.SourceTaskID)
+
+	case models.MergeStrategyLatestTimestamp:
+		incoming := mergeTimestamp(record[policy.TimestampField])
+		if current.SourceTaskID == taskID || (incoming != nil && (current.SourceTimestamp == nil || !incoming.Before(*current.SourceTimestamp))) {
+			if incoming != nil {
+				source.SourceTimestamp = incoming
+			}
+			return accept(fields)
+		}
+		return rejectAll(current.SourceTaskID)
+
+	case models.MergeStrategyFieldPrecedence:
+		written := make(map[string]interface{}, len(record))
+		rejectedByWinner := make(map[string][]string)
+		var winners []string
+		rejected := 0
+		for _, field := range fields {
+			value := record[field]
+			owner := cast.ToString(current.FieldSources[field])
+			if isKey[field] || owner == "" || owner == taskID {
+				written[field] = value
+				continue
+			}
+			if value == nil {
+				// 空值不覆盖其他任务写入的字段
+				rejected++
+				continue
+			}
+			order := fieldPrecedenceOrder(policy, field)
+			if mergeRank(order, taskID) <= mergeRank(order, owner) {
+				written[field] = value
+				continue
+			}
+			rejected++
+			if _, ok := rejectedByWinner[owner]; !ok {
+				winners = append(winners, owner)
+			}
+			rejectedByWinner[owner] = append(rejectedByWinner[owner], field)
+		}
+
+		decision := mergeDecision{rejected: rejected}
+		for _, winner := range winners {
+			decision.conflicts = append(decision.conflicts, mergeConflict{winner: winner, fields: rejectedByWinner[winner]})
+		}
+		if len(written) == len(primaryKeys) {
+			// 只剩主键，不需要写入
+			return decision
+		}
+		writtenFields := make([]string, 0, len(written))
+		for field := range written {
+			writtenFields = append(writtenFields, field)
+		}
+		accepted := accept(writtenFields)
+		decision.record, decision.source = written, accepted.source
+		return decision
+	}
+
+	// 未知策略按不合并处理
+	return accept(fields)
+}
+
+// fieldPrecedenceOrder 字段的任务优先级，未单独配置时使用source_priority
+func fieldPrecedenceOrder(policy *models.ThematicMergePolicy, field string) []string {
+	if order := cast.ToStringSlice(policy.FieldPrecedence[field]); len(order) > 0 {
+		return order
+	}
+	return policy.SourcePriority
+}
+
+// mergeRank 任务在优先级列表中的位置，越小优先级越高，未列出的任务排在最后
+func mergeRank(order []string, taskID string) int {
+	for i, id := range order {
+		if id == taskID {
+			return i
+		}
+	}
+	return len(order)
+}
+
+// mergeTimestamp 解析时间字段，缺少或无法解析时返回nil
+func mergeTimestamp(value interface{}) *time.Time {
+	if value == nil {
+		return nil
+	}
+	parsed, err := cast.ToTimeE(value)
+	if err != nil || parsed.IsZero() {
+		return nil
+	}
+	return &parsed
+}
+
+// mergeRecordKey 记录的主键取值，格式与全量同步比对主键时一致，主键不完整时返回空
+func mergeRecordKey(record map[string]interface{}, primaryKeys []string) string {
+	parts := make([]string, 0, len(primaryKeys))
+	for _, field := range primaryKeys {
+		value, ok := record[field]
+		if !ok || value == nil {
+			return ""
+		}
+		parts = append(parts, fmt.Sprintf("%v", value))
+	}
+	return strings.Join(parts, "_")
+}
@@ -0,0 +1,143 @@
+/*
+ * @module service/thematic_sync/merge_resolver_test
+ * @description 多源写入合并测试，覆盖三种合并策略的裁决、冲突的记录和累加，以及全量同步只删除本任务写入的记录
+ * @architecture 单元测试 - 裁决逻辑为纯函数，来源和冲突的保存使用SQLite内存库
+ * @documentReference ai_docs/thematic_sync_design.md
+ * @refs merge_resolver.go
+ */
+
+package thematic_sync
+
+import (
+	"context"
+	"datahub-service/service/models"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// TestResolveMergeRecord 测试按合并策略裁决单条记录
+func TestResolveMergeRecord(t *testing.T) {
+	now := time.Now()
+	pks := []string{"id"}
+
+	t.Run("任务优先级", func(t *testing.T) {
+		policy := &models.ThematicMergePolicy{Strategy: models.MergeStrategySourcePriority, SourcePriority: []string{"high", "low"}}
+		current := &models.ThematicRecordSource{SourceTaskID: "high", FieldSources: models.JSONB{"name": "high"}}
+
+		decision := resolveMergeRecord(policy, "low", map[string]interface{}{"id": 1, "name": "b"}, current, pks, now)
+		assert.Nil(t, decision.record, "低优先级任务不能覆盖")
+		require.Len(t, decision.conflicts, 1)
+		assert.Equal(t, mergeConflict{winner: "high", fields: []string{"name"}}, decision.conflicts[0])
+
+		decision = resolveMergeRecord(policy, "other", map[string]interface{}{"id": 2, "name": "c"}, nil, pks, now)
+		assert.NotNil(t, decision.record, "新记录直接写入")
+		assert.Equal(t, "other", decision.source.FieldSources["name"])
+
+		decision = resolveMergeRecord(policy, "high", map[string]interface{}{"id": 1, "name": "a"}, &models.ThematicRecordSource{SourceTaskID: "low"}, pks, now)
+		assert.NotNil(t, decision.record, "高优先级任务覆盖低优先级任务")
+		assert.Empty(t, decision.conflicts)
+	})
+
+	t.Run("最新时间", func(t *testing.T) {
+		policy := &models.ThematicMergePolicy{Strategy: models.MergeStrategyLatestTimestamp, TimestampField: "updated_at"}
+		stored := time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC)
+		current := &models.ThematicRecordSource{SourceTaskID: "a", SourceTimestamp: &stored}
+
+		older := resolveMergeRecord(policy, "b", map[string]interface{}{"id": 1, "updated_at": "2026-04-30T00:00:00Z"}, current, pks, now)
+		assert.Nil(t, older.record)
+		assert.Len(t, older.conflicts, 1)
+
+		missing := resolveMergeRecord(policy, "b", map[string]interface{}{"id": 1, "updated_at": nil}, current, pks, now)
+		assert.Nil(t, missing.record, "缺少时间不能覆盖其他任务的数据")
+
+		newer := resolveMergeRecord(policy, "b", map[string]interface{}{"id": 1, "updated_at": "2026-05-02T00:00:00Z"}, current, pks, now)
+		require.NotNil(t, newer.record)
+		assert.Equal(t, "b", newer.source.SourceTaskID)
+		assert.True(t, newer.source.SourceTimestamp.After(stored))
+
+		same := resolveMergeRecord(policy, "a", map[string]interface{}{"id": 1, "updated_at": nil}, current, pks, now)
+		assert.NotNil(t, same.record, "同一任务覆盖自己的数据")
+		assert.Equal(t, stored, *same.source.SourceTimestamp, "缺少时间时保留原时间")
+	})
+
+	t.Run("字段优先级", func(t *testing.T) {
+		policy := &models.ThematicMergePolicy{
+			Strategy:        models.MergeStrategyFieldPrecedence,
+			SourcePriority:  []string{"crm", "erp"},
+			FieldPrecedence: models.JSONB{"price": []interface{}{"erp", "crm"}},
+		}
+		current := &models.ThematicRecordSource{SourceTaskID: "erp", FieldSources: models.JSONB{"name": "crm", "price": "erp", "stock": "erp"}}
+
+		decision := resolveMergeRecord(policy, "crm", map[string]interface{}{"id": 1, "name": "x", "price": 9, "stock": nil}, current, pks, now)
+		assert.Equal(t, map[string]interface{}{"id": 1, "name": "x"}, decision.record)
+		assert.Equal(t, 2, decision.rejected)
+		require.Len(t, decision.conflicts, 1, "空值不记冲突")
+		assert.Equal(t, mergeConflict{winner: "erp", fields: []string{"price"}}, decision.conflicts[0])
+		assert.Equal(t, "erp", decision.source.FieldSources["price"])
+		assert.Equal(t, "crm", decision.source.FieldSources["name"])
+
+		onlyKey := resolveMergeRecord(policy, "crm", map[string]interface{}{"id": 1, "price": 9}, current, pks, now)
+		assert.Nil(t, onlyKey.record, "只剩主键时跳过")
+	})
+}
+
+// TestMergeResolverCommit 测试来源保存、冲突累加和全量同步删除过滤
+func TestMergeResolverCommit(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&models.ThematicMergePolicy{}, &models.ThematicRecordSource{}, &models.ThematicMergeConflict{}))
+	require.NoError(t, db.Create(&models.ThematicMergePolicy{
+		ThematicInterfaceID: "iface", Enabled: true, Strategy: models.MergeStrategySourcePriority, SourcePriority: []string{"high", "low"},
+	}).Error)
+
+	ctx := context.Background()
+	run := func(taskID string, records []map[string]interface{}) ([]map[string]interface{}, *MergeReport) {
+		request := &SyncRequest{TaskID: taskID, ExecutionID: "exec-" + taskID, TargetInterfaceID: "iface"}
+		resolver, err := newMergeResolver(ctx, db, request, []string{"id"})
+		require.NoError(t, err)
+		require.NotNil(t, resolver)
+		written, err := resolver.Resolve(ctx, records)
+		require.NoError(t, err)
+		report, err := resolver.Commit(ctx)
+		require.NoError(t, err)
+		return written, report
+	}
+
+	written, report := run("high", []map[string]interface{}{{"id": 1, "name": "a"}, {"id": 2, "name": "b"}})
+	assert.Len(t, written, 2)
+	assert.Equal(t, int64(2), report.AcceptedRecords)
+
+	for i := 0; i < 2; i++ {
+		written, report = run("low", []map[string]interface{}{{"id": 1, "name": "x"}, {"id": 3, "name": "z"}})
+		assert.Equal(t, []map[string]interface{}{{"id": 3, "name": "z"}}, written)
+		assert.Equal(t, int64(1), report.SkippedRecords)
+		assert.Equal(t, int64(1), report.Conflicts)
+	}
+
+	var conflicts []models.ThematicMergeConflict
+	require.NoError(t, db.Find(&conflicts).Error)
+	require.Len(t, conflicts, 1, "重复冲突累加到同一条")
+	assert.Equal(t, 2, conflicts[0].Occurrences)
+	assert.Equal(t, "1", conflicts[0].RecordKey)
+	assert.Equal(t, "high", conflicts[0].WinningTaskID)
+	assert.Equal(t, models.MergeConflictOpen, conflicts[0].Status)
+
+	owned, err := filterMergeOwnedKeys(ctx, db, &SyncRequest{TaskID: "high", TargetInterfaceID: "iface"}, []string{"1", "2", "3", "99"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"1", "2"}, owned, "其他任务写入和没有来源的记录不删除")
+	require.NoError(t, removeRecordSources(ctx, db, "iface", owned))
+
+	var remaining int64
+	require.NoError(t, db.Model(&models.ThematicRecordSource{}).Count(&remaining).Error)
+	assert.Equal(t, int64(1), remaining)
+
+	require.NoError(t, db.Model(&models.ThematicMergePolicy{}).Where("thematic_interface_id = ?", "iface").Update("enabled", false).Error)
+	resolver, err := newMergeResolver(ctx, db, &SyncRequest{TaskID: "low", TargetInterfaceID: "iface"}, []string{"id"})
+	require.NoError(t, err)
+	assert.Nil(t, resolver, "策略停用时不裁决")
+}
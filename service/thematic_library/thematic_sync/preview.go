@@ -0,0 +1,529 @@
+/*
+ * @module service/thematic_sync/preview
+ * @description 主题接口预览，用少量样例执行任务配置的数据获取、合并、字段映射和数据治理，返回每条输出记录由哪些源记录、经过哪些转换得到
+ * @architecture 与同步管道共用数据获取、合并键、字段映射和治理逻辑，只替换取数范围并记录过程，便于排查关联和映射配置
+ * @documentReference ai_docs/thematic_sync_design.md
+ * @stateFlow 解析合并键 -> 首个数据源取样例 -> 其余数据源按样例的合并键取数 -> 按合并键合并并记录字段来源 ->
+ *            字段映射并记录取值方式 -> 数据治理并标记变化的字段 -> 生成预览报告
+ * @rules 预览不写入目标表、不创建执行记录、不更新增量同步值；SQL模式每条查询最多取样例行数；
+ *        接口模式不按增量配置取数，过滤条件在取样后应用，可能少于样例行数；加密列与同步一致保持密文
+ * @dependencies gorm.io/gorm
+ * @refs data_fetcher.go, data_processor.go, field_mapper.go, sync_engine.go
+ */
+
+package thematic_sync
+
+import (
+	"context"
+	"datahub-service/service/models"
+	"datahub-service/service/utils"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// 预览样例行数
+const (
+	DefaultPreviewLimit = 10
+	MaxPreviewLimit     = 100
+)
+
+// 预览的数据源模式
+const (
+	PreviewSourceModeInterface = "interface"
+	PreviewSourceModeSQL       = "sql"
+)
+
+// 预览中标记的转换
+const (
+	previewTransformGovernance = "governance" // 数据治理（清洗、脱敏）改变了取值
+)
+
+// PreviewReport 主题接口预览报告
+type PreviewReport struct {
+	TaskID      string              `json:"task_id"`
+	SourceMode  string              `json:"source_mode"` // interface 或 sql
+	MergeKeys   []string            `json:"merge_keys"`  // 合并源记录使用的目标主键字段，为空时按整条记录的哈希合并
+	Sources     []PreviewSource     `json:"sources"`
+	Rows        []PreviewRow        `json:"rows"`
+	DroppedRows []PreviewDroppedRow `json:"dropped_rows"` // 字段映射失败、同步时会被丢弃的记录
+}
+
+// PreviewSource 数据源的取样情况
+type PreviewSource struct {
+	LibraryID    string   `json:"library_id"`
+	InterfaceID  string   `json:"interface_id"`
+	SQLQuery     string   `json:"sql_query,omitempty"`
+	SampledRows  int      `json:"sampled_rows"`
+	MatchedByKey bool     `json:"matched_by_key"` // 按首个数据源样例的合并键取数
+	Transforms   []string `json:"transforms,omitempty"`
+}
+
+// PreviewRow 一条输出记录及其来源
+type PreviewRow struct {
+	MergeKey   string                 `json:"merge_key"`
+	Record     map[string]interface{} `json:"record"`
+	SourceRows []PreviewSourceRow     `json:"source_rows"`
+	Fields     []PreviewFieldTrace    `json:"fields"`
+}
+
+// PreviewSourceRow 参与合并的源记录
+type PreviewSourceRow struct {
+	LibraryID   string                 `json:"library_id"`
+	InterfaceID string                 `json:"interface_id"`
+	RecordID    string                 `json:"record_id"`
+	Record      map[string]interface{} `json:"record"`
+}
+
+// PreviewFieldTrace 输出字段的取值过程
+type PreviewFieldTrace struct {
+	Field               string      `json:"field"`
+	Value               interface{} `json:"value"`
+	Origin              string      `json:"origin"` // 取值方式，见FieldOrigin常量
+	SourceField         string      `json:"source_field,omitempty"`
+	SourceRecordID      string      `json:"source_record_id,omitempty"`
+	OverriddenRecordIDs []string    `json:"overridden_record_ids,omitempty"` // 合并时同名字段被覆盖的源记录
+	Transforms          []string    `json:"transforms,omitempty"`            // 依次经过的转换，如upper(name)、cast(integer)、governance
+}
+
+// PreviewDroppedRow 同步时会被丢弃的合并记录
+type PreviewDroppedRow struct {
+	MergeKey        string   `json:"merge_key"`
+	SourceRecordIDs []string `json:"source_record_ids"`
+	Reason          string   `json:"reason"`
+}
+
+// Preview 用少量样例执行同步管道的获取、合并、映射和治理阶段，返回每条输出记录的来源和转换过程，不写入数据
+func (tse *ThematicSyncEngine) Preview(request *SyncRequest, limit int) (*PreviewReport, error) {
+	if limit <= 0 {
+		limit = DefaultPreviewLimit
+	} else if limit > MaxPreviewLimit {
+		limit = MaxPreviewLimit
+	}
+	if request.Context == nil {
+		request.Context = context.Background()
+	}
+	if len(request.SourceInterfaces) == 0 && len(request.SourceLibraries) == 0 && request.Config["sql_queries"] == nil {
+		return nil, fmt.Errorf("源库或源接口列表不能为空")
+	}
+
+	var target models.ThematicInterface
+	if err := tse.db.WithContext(request.Context).First(&target, "id = ?", request.TargetInterfaceID).Error; err != nil {
+		return nil, fmt.Errorf("获取目标接口信息失败: %w", err)
+	}
+	mergeKeys := GetThematicPrimaryKeyFields(&target)
+
+	sourceRecords, sources, err := tse.dataFetcher.FetchSampleData(request, limit, mergeKeys)
+	if err != nil {
+		return nil, err
+	}
+
+	report, err := tse.dataProcessor.PreviewData(sourceRecords, request, mergeKeys, limit)
+	if err != nil {
+		return nil, err
+	}
+	report.TaskID = request.TaskID
+	report.Sources = sources
+	report.SourceMode = PreviewSourceModeInterface
+	if len(sources) > 0 && sources[0].SQLQuery != "" {
+		report.SourceMode = PreviewSourceModeSQL
+	}
+	return report, nil
+}
+
+// FetchSampleData 按任务的数据源配置取样例源记录；接口模式下其余数据源按首个数据源样例的合并键取数，便于观察关联结果
+func (df *DataFetcher) FetchSampleData(request *SyncRequest, limit int, mergeKeys []string) ([]SourceRecordInfo, []PreviewSource, error) {
+	ctx := request.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	var sourceRecords []SourceRecordInfo
+	var sources []PreviewSource
+
+	if sqlConfigs, hasSQLConfig := df.parseSQLQueryConfigs(request); hasSQLConfig && len(sqlConfigs) > 0 {
+		for i, sqlConfig := range sqlConfigs {
+			sample := *sqlConfig
+			sample.MaxRows = limit
+			records, err := df.sqlQueryExecutor.ExecuteQuery(ctx, &sample)
+			if err != nil {
+				return nil, nil, fmt.Errorf("执行SQL查询失败 [查询%d]: %w", i+1, err)
+			}
+			interfaceID := fmt.Sprintf("sql_query_%d", i+1)
+			for j, record := range records {
+				sourceRecords = append(sourceRecords, SourceRecordInfo{
+					LibraryID:   "sql_query",
+					InterfaceID: interfaceID,
+					RecordID:    df.generateRecordIDForSQL(i+1, j, record),
+					Record:      record,
+				})
+			}
+			sources = append(sources, PreviewSource{LibraryID: "sql_query", InterfaceID: interfaceID, SQLQuery: sqlConfig.SQLQuery, SampledRows: len(records)})
+		}
+		return sourceRecords, sources, nil
+	}
+
+	sourceConfigs, err := df.parseSourceConfigs(request)
+	if err != nil {
+		return nil, nil, fmt.Errorf("解析源库配置失败: %w", err)
+	}
+
+	var keyTuples [][]interface{}
+	for i, config := range sourceConfigs {
+		var match [][]interface{}
+		if i > 0 {
+			match = keyTuples
+		}
+		records, matched, err := df.fetchInterfaceSample(ctx, config, limit, mergeKeys, match)
+		if err != nil {
+			return nil, nil, fmt.Errorf("获取接口数据失败 [%s/%s]: %w", config.LibraryID, config.InterfaceID, err)
+		}
+
+		if len(config.Filters) > 0 {
+			records = df.applyFilters(records, config.Filters)
+		}
+		if len(config.Transforms) > 0 {
+			records, err = df.applyTransforms(records, config.Transforms)
+			if err != nil {
+				return nil, nil, fmt.Errorf("应用数据转换失败: %w", err)
+			}
+		}
+		if i == 0 {
+			keyTuples = previewKeyTuples(records, mergeKeys)
+		}
+
+		transforms := make(map[string]string, len(config.Transforms))
+		source := PreviewSource{LibraryID: config.LibraryID, InterfaceID: config.InterfaceID, SampledRows: len(records), MatchedByKey: matched}
+		for _, transform := range config.Transforms {
+			description := describeTransform(transform)
+			transforms[transform.TargetField] = description
+			source.Transforms = append(source.Transforms, transform.TargetField+" = "+description)
+		}
+		sources = append(sources, source)
+
+		for j, record := range records {
+			sourceRecords = append(sourceRecords, SourceRecordInfo{
+				LibraryID:   config.LibraryID,
+				InterfaceID: config.InterfaceID,
+				RecordID:    df.generateRecordID(config.LibraryID, config.InterfaceID, j, record),
+				Record:      record,
+				Metadata:    map[string]interface{}{"transforms": transforms},
+			})
+		}
+	}
+
+	return sourceRecords, sources, nil
+}
+
+// fetchInterfaceSample 读取接口表的样例，keyTuples不为空且接口包含全部合并键字段时只取合并键匹配的记录
+func (df *DataFetcher) fetchInterfaceSample(ctx context.Context, config SourceLibraryConfig, limit int, mergeKeys []string, keyTuples [][]interface{}) ([]map[string]interface{}, bool, error) {
+	var dataInterface models.DataInterface
+	if err := df.db.WithContext(ctx).Preload("BasicLibrary").First(&dataInterface, "id = ?", config.InterfaceID).Error; err != nil {
+		return nil, false, fmt.Errorf("获取接口信息失败: %w", err)
+	}
+	if dataInterface.BasicLibrary.NameEn == "" {
+		return nil, false, fmt.Errorf("基础库英文名为空")
+	}
+	if dataInterface.NameEn == "" {
+		return nil, false, fmt.Errorf("基础接口英文名为空")
+	}
+	fullTableName := fmt.Sprintf("%s.%s", dataInterface.BasicLibrary.GetSchemaName(), dataInterface.NameEn)
+
+	query := "SELECT * FROM " + fullTableName
+	var args []interface{}
+	matched := false
+	if len(keyTuples) > 0 && len(mergeKeys) > 0 && hasAllFields(dataInterfaceFieldNames(&dataInterface), mergeKeys) {
+		condition, conditionArgs := buildKeyMatchCondition(mergeKeys, keyTuples)
+		query += " WHERE " + condition
+		args = conditionArgs
+		matched = true
+	}
+	if primaryKeyFields := GetDataInterfacePrimaryKeyFields(&dataInterface); len(primaryKeyFields) > 0 {
+		query += " ORDER BY " + utils.QuoteIdent(primaryKeyFields[0])
+	}
+	query += fmt.Sprintf(" LIMIT %d", limit)
+
+	rows, err := df.db.WithContext(ctx).Raw(query, args...).Rows()
+	if err != nil {
+		return nil, false, fmt.Errorf("查询数据失败: %w", err)
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, false, fmt.Errorf("获取列信息失败: %w", err)
+	}
+	records := make([]map[string]interface{}, 0, limit)
+	for rows.Next() {
+		values := make([]interface{}, len(columns))
+		scanArgs := make([]interface{}, len(columns))
+		for i := range values {
+			scanArgs[i] = &values[i]
+		}
+		if err := rows.Scan(scanArgs...); err != nil {
+			return nil, false, fmt.Errorf("扫描数据失败: %w", err)
+		}
+		record := make(map[string]interface{}, len(columns))
+		for i, column := range columns {
+			record[column] = df.convertDatabaseValue(values[i])
+		}
+		records = append(records, record)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, false, fmt.Errorf("遍历数据失败: %w", err)
+	}
+	return records, matched, nil
+}
+
+// PreviewData 按合并键合并样例源记录，执行字段映射和数据治理，记录每个输出字段的来源
+func (dp *DataProcessor) PreviewData(sourceRecords []SourceRecordInfo, request *SyncRequest, mergeKeys []string, limit int) (*PreviewReport, error) {
+	targetFields, err := dp.fieldMapper.getTargetFieldsConfig(request.TargetInterfaceID)
+	if err != nil {
+		return nil, fmt.Errorf("获取目标字段配置失败: %w", err)
+	}
+	mappingRules, err := dp.fieldMapper.parseFieldMappingRules(request.Config["field_mapping_rules"])
+	if err != nil {
+		mappingRules = nil
+	}
+
+	report := &PreviewReport{
+		MergeKeys:   mergeKeys,
+		Rows:        make([]PreviewRow, 0),
+		DroppedRows: make([]PreviewDroppedRow, 0),
+	}
+	if report.MergeKeys == nil {
+		report.MergeKeys = []string{}
+	}
+
+	var groups []*previewGroup
+	var mappedRecords []map[string]interface{}
+	var mappingTraces []map[string]FieldMappingTrace
+	for _, group := range dp.mergeWithTrace(sourceRecords, mergeKeys) {
+		if len(groups) >= limit {
+			break
+		}
+		mapped, traces, err := dp.fieldMapper.mapSingleRecordWithTrace(group.record, targetFields, mappingRules)
+		if err != nil {
+			if len(report.DroppedRows) < limit {
+				report.DroppedRows = append(report.DroppedRows, PreviewDroppedRow{
+					MergeKey:        group.key,
+					SourceRecordIDs: group.recordIDs(sourceRecords),
+					Reason:          err.Error(),
+				})
+			}
+			continue
+		}
+		groups = append(groups, group)
+		mappedRecords = append(mappedRecords, mapped)
+		mappingTraces = append(mappingTraces, traces)
+	}
+	if len(groups) == 0 {
+		return report, nil
+	}
+
+	governedRecords, _, err := dp.performGovernanceProcessing(mappedRecords, request, &SyncExecutionResult{})
+	if err != nil {
+		return nil, err
+	}
+	if len(governedRecords) != len(mappedRecords) {
+		governedRecords = mappedRecords
+	}
+
+	for i, group := range groups {
+		row := PreviewRow{
+			MergeKey:   group.key,
+			Record:     governedRecords[i],
+			SourceRows: make([]PreviewSourceRow, 0, len(group.members)),
+		}
+		for _, member := range group.members {
+			source := sourceRecords[member]
+			row.SourceRows = append(row.SourceRows, PreviewSourceRow{
+				LibraryID:   source.LibraryID,
+				InterfaceID: source.InterfaceID,
+				RecordID:    source.RecordID,
+				Record:      source.Record,
+			})
+		}
+		row.Fields = buildFieldTraces(group, sourceRecords, mappedRecords[i], governedRecords[i], mappingTraces[i])
+		report.Rows = append(report.Rows, row)
+	}
+	return report, nil
+}
+
+// previewGroup 按合并键合并的一组源记录
+type previewGroup struct {
+	key          string
+	record       map[string]interface{}
+	members      []int            // 源记录下标，按合并顺序
+	fieldSources map[string]int   // 字段最终取值所在的源记录下标
+	overridden   map[string][]int // 同名字段被覆盖的源记录下标
+}
+
+// recordIDs 组内源记录的ID
+func (g *previewGroup) recordIDs(sourceRecords []SourceRecordInfo) []string {
+	ids := make([]string, len(g.members))
+	for i, member := range g.members {
+		ids[i] = sourceRecords[member].RecordID
+	}
+	return ids
+}
+
+// mergeWithTrace 与MergeData相同的合并规则（后到的源记录覆盖同名字段），保持首次出现的顺序并记录字段来源
+func (dp *DataProcessor) mergeWithTrace(sourceRecords []SourceRecordInfo, mergeKeys []string) []*previewGroup {
+	var groups []*previewGroup
+	index := make(map[string]*previewGroup)
+	for i, sourceRecord := range sourceRecords {
+		key := dp.extractPrimaryKeyByFields(sourceRecord.Record, mergeKeys)
+		if key == "" {
+			key = dp.generateRecordHash(sourceRecord.Record)
+		}
+		group, exists := index[key]
+		if !exists {
+			group = &previewGroup{
+				key:          key,
+				record:       make(map[string]interface{}),
+				fieldSources: make(map[string]int),
+				overridden:   make(map[string][]int),
+			}
+			index[key] = group
+			groups = append(groups, group)
+		}
+		group.members = append(group.members, i)
+		for field, value := range sourceRecord.Record {
+			if previous, exists := group.fieldSources[field]; exists {
+				group.overridden[field] = append(group.overridden[field], previous)
+			}
+			group.record[field] = value
+			group.fieldSources[field] = i
+		}
+	}
+	return groups
+}
+
+// buildFieldTraces 按字段名排序生成输出字段的取值过程
+func buildFieldTraces(group *previewGroup, sourceRecords []SourceRecordInfo, mapped, governed map[string]interface{}, traces map[string]FieldMappingTrace) []PreviewFieldTrace {
+	fields := make([]string, 0, len(traces))
+	for field := range traces {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	result := make([]PreviewFieldTrace, 0, len(fields))
+	for _, field := range fields {
+		trace := traces[field]
+		fieldTrace := PreviewFieldTrace{
+			Field:       field,
+			Value:       governed[field],
+			Origin:      trace.Origin,
+			SourceField: trace.SourceField,
+		}
+		if trace.SourceField != "" {
+			if member, exists := group.fieldSources[trace.SourceField]; exists {
+				source := sourceRecords[member]
+				fieldTrace.SourceRecordID = source.RecordID
+				for _, overridden := range group.overridden[trace.SourceField] {
+					fieldTrace.OverriddenRecordIDs = append(fieldTrace.OverriddenRecordIDs, sourceRecords[overridden].RecordID)
+				}
+				if transforms, ok := source.Metadata["transforms"].(map[string]string); ok && transforms[trace.SourceField] != "" {
+					fieldTrace.Transforms = append(fieldTrace.Transforms, transforms[trace.SourceField])
+				}
+			}
+			if !samePreviewValue(group.record[trace.SourceField], mapped[field]) {
+				fieldTrace.Transforms = append(fieldTrace.Transforms, fmt.Sprintf("cast(%s)", trace.DataType))
+			}
+		}
+		if !samePreviewValue(mapped[field], governed[field]) {
+			fieldTrace.Transforms = append(fieldTrace.Transforms, previewTransformGovernance)
+		}
+		result = append(result, fieldTrace)
+	}
+	return result
+}
+
+// samePreviewValue 类型和取值都相同
+func samePreviewValue(a, b interface{}) bool {
+	return fmt.Sprintf("%T:%v", a, a) == fmt.Sprintf("%T:%v", b, b)
+}
+
+// describeTransform 转换的描述，如upper(name)
+func describeTransform(transform TransformConfig) string {
+	return fmt.Sprintf("%s(%s)", transform.Transform, transform.SourceField)
+}
+
+// previewKeyTuples 样例记录的合并键取值，缺少任一合并键的记录不参与匹配，相同取值只保留一次
+func previewKeyTuples(records []map[string]interface{}, mergeKeys []string) [][]interface{} {
+	if len(mergeKeys) == 0 {
+		return nil
+	}
+	var tuples [][]interface{}
+	seen := make(map[string]bool)
+	for _, record := range records {
+		tuple := make([]interface{}, 0, len(mergeKeys))
+		for _, field := range mergeKeys {
+			value, exists := record[field]
+			if !exists || value == nil {
+				tuple = nil
+				break
+			}
+			tuple = append(tuple, value)
+		}
+		if tuple == nil {
+			continue
+		}
+		if key := fmt.Sprint(tuple...); !seen[key] {
+			seen[key] = true
+			tuples = append(tuples, tuple)
+		}
+	}
+	return tuples
+}
+
+// buildKeyMatchCondition 构建合并键匹配条件，如("a", "b") IN ((?, ?), (?, ?))
+func buildKeyMatchCondition(mergeKeys []string, keyTuples [][]interface{}) (string, []interface{}) {
+	columns := make([]string, len(mergeKeys))
+	for i, field := range mergeKeys {
+		columns[i] = utils.QuoteIdent(field)
+	}
+	placeholder := strings.TrimSuffix(strings.Repeat("?, ", len(mergeKeys)), ", ")
+	if len(mergeKeys) > 1 {
+		placeholder = "(" + placeholder + ")"
+	}
+
+	placeholders := make([]string, len(keyTuples))
+	args := make([]interface{}, 0, len(keyTuples)*len(mergeKeys))
+	for i, tuple := range keyTuples {
+		placeholders[i] = placeholder
+		args = append(args, tuple...)
+	}
+
+	column := columns[0]
+	if len(columns) > 1 {
+		column = "(" + strings.Join(columns, ", ") + ")"
+	}
+	return fmt.Sprintf("%s IN (%s)", column, strings.Join(placeholders, ", ")), args
+}
+
+// dataInterfaceFieldNames 基础库接口字段配置中的字段名
+func dataInterfaceFieldNames(dataInterface *models.DataInterface) map[string]bool {
+	names := make(map[string]bool)
+	for fieldKey, fieldValue := range dataInterface.TableFieldsConfig {
+		if fieldMap, ok := fieldValue.(map[string]interface{}); ok {
+			if nameEn, ok := fieldMap["name_en"].(string); ok && nameEn != "" {
+				names[nameEn] = true
+				continue
+			}
+		}
+		names[fieldKey] = true
+	}
+	return names
+}
+
+// hasAllFields 字段集合包含全部字段
+func hasAllFields(names map[string]bool, fields []string) bool {
+	for _, field := range fields {
+		if !names[field] {
+			return false
+		}
+	}
+	return true
+}
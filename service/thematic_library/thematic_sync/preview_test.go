@@ -0,0 +1,122 @@
+/*
+ * @module service/thematic_sync/preview_test
+ * @description 主题接口预览测试，覆盖按合并键合并并记录字段来源、字段映射取值方式、输出字段的转换过程和按合并键取数的条件
+ * @architecture 单元测试 - 不依赖数据库，直接验证合并、映射和追踪函数
+ * @documentReference ai_docs/thematic_sync_design.md
+ * @refs preview.go, field_mapper.go
+ */
+
+package thematic_sync
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestMergeWithTrace 测试合并保持首次出现的顺序并记录字段来源和被覆盖的源记录
+func TestMergeWithTrace(t *testing.T) {
+	dp := &DataProcessor{}
+	sourceRecords := []SourceRecordInfo{
+		{RecordID: "a1", Record: map[string]interface{}{"person_id": "p1", "name": "张三"}},
+		{RecordID: "a2", Record: map[string]interface{}{"person_id": "p2", "name": "李四"}},
+		{RecordID: "b1", Record: map[string]interface{}{"person_id": "p1", "name": "张三丰", "phone": "138"}},
+		{RecordID: "c1", Record: map[string]interface{}{"name": "无主键"}},
+	}
+
+	groups := dp.mergeWithTrace(sourceRecords, []string{"person_id"})
+
+	require.Len(t, groups, 3)
+	assert.Equal(t, "p1", groups[0].key)
+	assert.Equal(t, []string{"a1", "b1"}, groups[0].recordIDs(sourceRecords))
+	assert.Equal(t, "张三丰", groups[0].record["name"], "后到的源记录覆盖同名字段")
+	assert.Equal(t, 2, groups[0].fieldSources["name"])
+	assert.Equal(t, []int{0}, groups[0].overridden["name"])
+	assert.Equal(t, "p2", groups[1].key)
+	assert.Contains(t, groups[2].key, "hash_", "缺少合并键时按哈希合并")
+}
+
+// TestBuildFieldTraces 测试输出字段的取值方式、来源记录和转换过程
+func TestBuildFieldTraces(t *testing.T) {
+	dp := &DataProcessor{}
+	fm := &FieldMapper{}
+	sourceRecords := []SourceRecordInfo{
+		{RecordID: "a1", Record: map[string]interface{}{"person_id": "p1", "full_name": "ZHANG", "age": int64(30)},
+			Metadata: map[string]interface{}{"transforms": map[string]string{"full_name": "upper(name)"}}},
+		{RecordID: "b1", Record: map[string]interface{}{"person_id": "p1", "age": int64(31)}},
+	}
+	group := dp.mergeWithTrace(sourceRecords, []string{"person_id"})[0]
+	targetFields := map[string]TargetFieldInfo{
+		"person_id": {NameEn: "person_id", DataType: "varchar", IsPrimaryKey: true},
+		"name":      {NameEn: "name", DataType: "varchar", IsNullable: true},
+		"age":       {NameEn: "age", DataType: "integer", IsNullable: true},
+		"remark":    {NameEn: "remark", DataType: "text", IsNullable: true},
+		"region":    {NameEn: "region", DataType: "varchar", IsNullable: true, DefaultValue: "east"},
+	}
+
+	mapped, traces, err := fm.mapSingleRecordWithTrace(group.record, targetFields, map[string]string{"full_name": "name"})
+	require.NoError(t, err)
+	assert.Equal(t, FieldOriginMappingRule, traces["name"].Origin)
+	assert.Equal(t, FieldOriginSameName, traces["age"].Origin)
+	assert.Equal(t, FieldOriginNull, traces["remark"].Origin)
+	assert.Equal(t, FieldOriginDefaultValue, traces["region"].Origin)
+
+	governed := map[string]interface{}{}
+	for field, value := range mapped {
+		governed[field] = value
+	}
+	governed["name"] = "Z***"
+
+	fields := buildFieldTraces(group, sourceRecords, mapped, governed, traces)
+	require.Len(t, fields, 5)
+	byField := map[string]PreviewFieldTrace{}
+	for _, field := range fields {
+		byField[field.Field] = field
+	}
+	assert.Equal(t, "age", fields[0].Field, "按字段名排序")
+
+	assert.Equal(t, PreviewFieldTrace{
+		Field: "name", Value: "Z***", Origin: FieldOriginMappingRule, SourceField: "full_name", SourceRecordID: "a1",
+		Transforms: []string{"upper(name)", previewTransformGovernance},
+	}, byField["name"])
+	assert.Equal(t, PreviewFieldTrace{
+		Field: "age", Value: 31, Origin: FieldOriginSameName, SourceField: "age", SourceRecordID: "b1",
+		OverriddenRecordIDs: []string{"a1"}, Transforms: []string{"cast(integer)"},
+	}, byField["age"])
+	assert.Empty(t, byField["person_id"].Transforms)
+	assert.Equal(t, "east", byField["region"].Value)
+	assert.Empty(t, byField["region"].SourceRecordID)
+}
+
+// TestMapSingleRecordWithTraceMissingField 测试无法取值的必需字段导致记录被丢弃
+func TestMapSingleRecordWithTraceMissingField(t *testing.T) {
+	fm := &FieldMapper{}
+	targetFields := map[string]TargetFieldInfo{
+		"payload": {NameEn: "payload", DataType: "bytea", Required: true},
+	}
+	_, _, err := fm.mapSingleRecordWithTrace(map[string]interface{}{"id": 1}, targetFields, nil)
+	assert.EqualError(t, err, "缺失必需字段: payload")
+}
+
+// TestKeyMatchCondition 测试按首个数据源样例的合并键取数的条件
+func TestKeyMatchCondition(t *testing.T) {
+	records := []map[string]interface{}{
+		{"org": "A", "code": 1},
+		{"org": "A", "code": 1},
+		{"org": "B", "code": nil},
+		{"org": "B", "code": 2},
+	}
+	tuples := previewKeyTuples(records, []string{"org", "code"})
+	assert.Equal(t, [][]interface{}{{"A", 1}, {"B", 2}}, tuples, "缺少合并键的记录不参与匹配，相同取值只保留一次")
+
+	condition, args := buildKeyMatchCondition([]string{"org", "code"}, tuples)
+	assert.Equal(t, `("org", "code") IN ((?, ?), (?, ?))`, condition)
+	assert.Equal(t, []interface{}{"A", 1, "B", 2}, args)
+
+	condition, args = buildKeyMatchCondition([]string{"org"}, [][]interface{}{{"A"}, {"B"}})
+	assert.Equal(t, `"org" IN (?, ?)`, condition)
+	assert.Equal(t, []interface{}{"A", "B"}, args)
+
+	assert.Nil(t, previewKeyTuples(records, nil))
+}
@@ -4,7 +4,7 @@
  * @architecture 管道模式 - 通过多个处理阶段完成数据同步
  * @documentReference ai_docs/thematic_sync_design.md
  * @stateFlow 任务接收 -> 数据获取 -> 汇聚处理 -> 清洗脱敏 -> 质量检查 -> 数据写入 -> 血缘记录
- * @rules 确保同步流程的完整性和一致性，支持事务性操作和错误恢复
+ * @rules 确保同步流程的完整性和一致性，支持事务性操作和错误恢复；已取消的预建执行记录不再执行，执行期间被取消时结束后保留取消状态
  * @dependencies gorm.io/gorm, context, time
  * @refs models/thematic_sync.go, data_fetcher.go, data_processor.go
  */
@@ -13,14 +13,23 @@ package thematic_sync
 
 import (
 	"context"
+	"datahub-service/service/eventlog"
+	"datahub-service/service/meta"
+	"datahub-service/service/metrics"
 	"datahub-service/service/models"
+	"datahub-service/service/tracing"
+	"errors"
 	"fmt"
+	"log/slog"
 	"time"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
+// ErrExecutionCancelled 执行记录已被取消
+var ErrExecutionCancelled = errors.New("执行已取消")
+
 // 类型定义已移动到 types.go 文件
 
 // GovernanceIntegrationServiceInterface 数据治理集成服务接口
@@ -32,17 +41,20 @@ type GovernanceIntegrationServiceInterface interface {
 type DataFetcherInterface interface {
 	FetchSourceData(request *SyncRequest, result *SyncExecutionResult) ([]SourceRecordInfo, error)
 	FetchDataFromInterface(libraryID, interfaceID string) ([]map[string]interface{}, error)
+	FetchSampleData(request *SyncRequest, limit int, mergeKeys []string) ([]SourceRecordInfo, []PreviewSource, error)
 }
 
 // DataProcessorInterface 数据处理接口
 type DataProcessorInterface interface {
 	ProcessData(sourceRecords []SourceRecordInfo, request *SyncRequest, result *SyncExecutionResult) ([]map[string]interface{}, *GovernanceExecutionResult, error)
 	MergeData(sourceRecords []SourceRecordInfo, request *SyncRequest, result *SyncExecutionResult) ([]map[string]interface{}, error)
+	PreviewData(sourceRecords []SourceRecordInfo, request *SyncRequest, mergeKeys []string, limit int) (*PreviewReport, error)
 }
 
 // DataWriterInterface 数据写入接口
 type DataWriterInterface interface {
 	WriteData(processedRecords []map[string]interface{}, request *SyncRequest, result *SyncExecutionResult, governanceResult *GovernanceExecutionResult) error
+	DryRunWrite(processedRecords []map[string]interface{}, request *SyncRequest, governanceResult *GovernanceExecutionResult) (*DryRunReport, error)
 }
 
 // LineageRecorderInterface 血缘记录接口
@@ -89,10 +101,31 @@ func (tse *ThematicSyncEngine) SetProgressCallback(callback func(*SyncProgress))
 	tse.progressCallback = callback
 }
 
+// isExecutionCancelled 执行记录是否已被取消
+func (tse *ThematicSyncEngine) isExecutionCancelled(executionID string) bool {
+	var status string
+	if err := tse.db.Model(&models.ThematicSyncExecution{}).Where("id = ?", executionID).
+		Select("status").Scan(&status).Error; err != nil {
+		return false
+	}
+	return status == meta.ThematicSyncExecutionStatusCancelled
+}
+
 // ExecuteSync 执行同步
 func (tse *ThematicSyncEngine) ExecuteSync(request *SyncRequest) (*SyncResponse, error) {
 	startTime := time.Now()
 
+	ctx := request.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx, span := tracing.Start(ctx, "sync.thematic_library.execute")
+	defer span.End()
+	span.SetAttribute("sync.task_id", request.TaskID)
+	span.SetAttribute("sync.library_id", request.TargetLibraryID)
+	span.SetAttribute("sync.interface_id", request.TargetInterfaceID)
+	request.Context = ctx
+
 	// 检查是否已存在执行记录（异步模式下会预先创建）
 	var execution models.ThematicSyncExecution
 	var executionID string
@@ -103,6 +136,9 @@ func (tse *ThematicSyncEngine) ExecuteSync(request *SyncRequest) (*SyncResponse,
 		if err := tse.db.First(&execution, "id = ?", executionID).Error; err != nil {
 			return nil, fmt.Errorf("获取执行记录失败: %w", err)
 		}
+		if execution.Status == meta.ThematicSyncExecutionStatusCancelled {
+			return nil, ErrExecutionCancelled
+		}
 
 		// 更新状态为running
 		execution.Status = "running"
@@ -111,8 +147,9 @@ func (tse *ThematicSyncEngine) ExecuteSync(request *SyncRequest) (*SyncResponse,
 			return nil, fmt.Errorf("更新执行记录失败: %w", err)
 		}
 	} else {
-		// 同步模式：创建新的执行记录
+		// 同步模式：创建新的执行记录，执行ID回填到请求中供合并来源和变更日志记录
 		executionID = uuid.New().String()
+		request.ExecutionID = executionID
 		execution = models.ThematicSyncExecution{
 			ID:            executionID,
 			TaskID:        request.TaskID,
@@ -128,6 +165,22 @@ func (tse *ThematicSyncEngine) ExecuteSync(request *SyncRequest) (*SyncResponse,
 		}
 	}
 
+	// 源接口被上游质量门禁阻断时跳过本次执行
+	blocks, err := findUpstreamBlocks(ctx, tse.db, request)
+	if err != nil {
+		slog.Error("查询上游质量门禁阻断失败", "task_id", request.TaskID, "error", err)
+	} else if len(blocks) > 0 {
+		return tse.skipForUpstreamBlocks(ctx, request, &execution, blocks, startTime), nil
+	}
+
+	eventlog.Record(ctx, eventlog.Event{
+		Type:       eventlog.EventTaskStarted,
+		Message:    "主题库同步任务开始执行",
+		ObjectType: "thematic_sync_task",
+		ObjectID:   request.TaskID,
+		Attributes: map[string]interface{}{"execution_id": executionID, "library_id": request.TargetLibraryID, "interface_id": request.TargetInterfaceID},
+	})
+
 	// 初始化进度
 	progress := &SyncProgress{
 		ExecutionID:    executionID,
@@ -157,10 +210,52 @@ func (tse *ThematicSyncEngine) ExecuteSync(request *SyncRequest) (*SyncResponse,
 		execution.InsertedRecordCount = result.InsertedRecordCount
 		execution.UpdatedRecordCount = result.UpdatedRecordCount
 		execution.ErrorRecordCount = result.ErrorRecordCount
+		execution.QualityScore = result.QualityScore
+		if result.DryRun != nil {
+			// 试运行的预计变更只记入处理结果，不计入实际新增/更新数
+			execution.ProcessingResult = models.JSONB{"dry_run": result.DryRun}
+		}
+		if result.Merge != nil {
+			execution.ProcessingResult = models.JSONB{"merge": result.Merge}
+		}
+		if result.ChangelogEntries > 0 {
+			if execution.ProcessingResult == nil {
+				execution.ProcessingResult = models.JSONB{}
+			}
+			execution.ProcessingResult["changelog_entries"] = result.ChangelogEntries
+		}
+	}
+
+	// 执行期间被取消时保留取消状态
+	if ctx.Err() != nil || tse.isExecutionCancelled(executionID) {
+		execution.Status = meta.ThematicSyncExecutionStatusCancelled
+		execution.ErrorDetails = models.JSONB{"error": "执行已取消"}
 	}
 
 	tse.db.Save(execution)
 
+	var processedRows int64
+	if result != nil {
+		processedRows = result.ProcessedRecordCount
+	}
+	metrics.ObserveSyncExecution(meta.LibraryTypeThematic, request.TargetLibraryID, execution.Status, processedRows, endTime.Sub(startTime))
+	span.SetAttribute("sync.processed_rows", processedRows)
+	span.RecordError(err)
+	finishedEvent := eventlog.Event{
+		Type:       eventlog.EventTaskCompleted,
+		Message:    "主题库同步任务执行完成",
+		ObjectType: "thematic_sync_task",
+		ObjectID:   request.TaskID,
+		Attributes: map[string]interface{}{"execution_id": executionID, "status": execution.Status, "processed_rows": processedRows},
+	}
+	if err != nil {
+		finishedEvent.Type = eventlog.EventTaskFailed
+		finishedEvent.Level = models.EventLevelError
+		finishedEvent.Message = "主题库同步任务执行失败"
+		finishedEvent.Attributes["error"] = err.Error()
+	}
+	eventlog.Record(ctx, finishedEvent)
+
 	// 构建响应
 	response := &SyncResponse{
 		ExecutionID:    executionID,
@@ -179,6 +274,37 @@ func (tse *ThematicSyncEngine) ExecuteSync(request *SyncRequest) (*SyncResponse,
 	return response, err
 }
 
+// skipForUpstreamBlocks 源接口被质量门禁阻断，执行记为skipped并记录task_skipped事件
+func (tse *ThematicSyncEngine) skipForUpstreamBlocks(ctx context.Context, request *SyncRequest, execution *models.ThematicSyncExecution, blocks []models.QualityGateBlock, startTime time.Time) *SyncResponse {
+	interfaceIDs := blockedInterfaceIDs(blocks)
+	endTime := time.Now()
+	execution.Status = ExecutionStatusSkipped
+	execution.EndTime = &endTime
+	execution.ErrorDetails = models.JSONB{
+		"reason":             "upstream_quality_gate",
+		"blocked_interfaces": interfaceIDs,
+	}
+	if err := tse.db.Save(execution).Error; err != nil {
+		slog.Error("更新执行记录失败", "execution_id", execution.ID, "error", err)
+	}
+
+	slog.Warn("上游接口未通过质量门禁，跳过主题同步", "task_id", request.TaskID, "blocked_interfaces", interfaceIDs)
+	eventlog.Record(ctx, eventlog.Event{
+		Type:       eventlog.EventTaskSkipped,
+		Level:      models.EventLevelWarn,
+		Message:    "上游接口未通过质量门禁，主题同步任务跳过执行",
+		ObjectType: "thematic_sync_task",
+		ObjectID:   request.TaskID,
+		Attributes: map[string]interface{}{"execution_id": execution.ID, "blocked_interfaces": interfaceIDs},
+	})
+	return &SyncResponse{
+		ExecutionID:    execution.ID,
+		Status:         ExecutionStatusSkipped,
+		Error:          "上游接口未通过质量门禁",
+		ProcessingTime: time.Since(startTime),
+	}
+}
+
 // executeSyncPipeline 执行同步管道
 func (tse *ThematicSyncEngine) executeSyncPipeline(request *SyncRequest, progress *SyncProgress) (*SyncExecutionResult, error) {
 	result := &SyncExecutionResult{
@@ -213,6 +339,23 @@ func (tse *ThematicSyncEngine) executeSyncPipeline(request *SyncRequest, progres
 		return nil, err
 	}
 
+	// 试运行：模拟写入后直接返回，不记录血缘、不更新任务统计
+	if isDryRun(request) {
+		if err := tse.executePhase(PhaseDataWrite, progress, func() error {
+			report, err := tse.dataWriter.DryRunWrite(processedRecords, request, governanceResult)
+			if err != nil {
+				return err
+			}
+			result.ProcessedRecordCount = int64(len(processedRecords))
+			result.ErrorRecordCount = report.RejectCount
+			result.DryRun = report
+			return nil
+		}); err != nil {
+			return nil, err
+		}
+		return result, nil
+	}
+
 	// 4. 数据写入阶段
 	if err := tse.executePhase(PhaseDataWrite, progress, func() error {
 		return tse.dataWriter.WriteData(processedRecords, request, result, governanceResult)
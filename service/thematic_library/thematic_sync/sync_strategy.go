@@ -46,7 +46,7 @@ func (fss *FullSyncStrategy) ProcessSync(sourceRecords []map[string]interface{},
 		return fmt.Errorf("获取主题接口信息失败: %w", err)
 	}
 
-	schema := thematicInterface.ThematicLibrary.NameEn
+	schema := thematicInterface.ThematicLibrary.GetSchemaName()
 	tableName := thematicInterface.NameEn
 	fullTableName := fmt.Sprintf("%s.%s", schema, tableName)
 
@@ -92,6 +92,12 @@ func (fss *FullSyncStrategy) ProcessSync(sourceRecords []map[string]interface{},
 		}
 	}
 
+	// 启用合并策略时只删除完全由本任务写入的记录
+	idsToDelete, err = filterMergeOwnedKeys(request.Context, fss.db, request, idsToDelete)
+	if err != nil {
+		return err
+	}
+
 	// 执行删除操作
 	if len(idsToDelete) > 0 {
 		slog.Info("全量同步：开始删除不存在的记录", "deleteCount", len(idsToDelete))
@@ -99,6 +105,20 @@ func (fss *FullSyncStrategy) ProcessSync(sourceRecords []map[string]interface{},
 		if err != nil {
 			return fmt.Errorf("删除记录失败: %w", err)
 		}
+		if err := removeRecordSources(request.Context, fss.db, request.TargetInterfaceID, idsToDelete); err != nil {
+			return err
+		}
+		changelog, err := newChangelogRecorder(request.Context, fss.db, request, fullTableName, primaryKeyFields)
+		if err != nil {
+			return err
+		}
+		if changelog != nil {
+			recorded, err := changelog.recordDeletes(request.Context, idsToDelete)
+			if err != nil {
+				return err
+			}
+			result.ChangelogEntries += recorded
+		}
 		result.ErrorRecordCount += deletedCount  // 这里用ErrorRecordCount记录删除的数量
 		slog.Info("全量同步：删除完成", "deletedCount", deletedCount)
 	} else {
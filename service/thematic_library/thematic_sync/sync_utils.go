@@ -14,6 +14,7 @@ package thematic_sync
 import (
 	"datahub-service/service/models"
 	"fmt"
+	"sort"
 	"strconv"
 	"strings"
 )
@@ -179,6 +180,9 @@ func GetThematicPrimaryKeyFields(thematicInterface *models.ThematicInterface) []
 		}
 	}
 
+	// 按字段名排序，复合主键拼接记录ID时顺序稳定
+	sort.Strings(primaryKeys)
+
 	// 如果仍然没有找到主键，返回空切片
 	return primaryKeys
 }
@@ -79,6 +79,30 @@ type SyncExecutionResult struct {
 	ErrorRecordCount     int64                `json:"error_record_count"`
 	QualityScore         float64              `json:"quality_score"`
 	ProcessingSteps      []ProcessingStepInfo `json:"processing_steps"`
+	DryRun               *DryRunReport        `json:"dry_run,omitempty"`           // 试运行报告，仅试运行时返回
+	Merge                *MergeReport         `json:"merge,omitempty"`             // 多源合并统计，仅主题接口启用合并策略时返回
+	ChangelogEntries     int64                `json:"changelog_entries,omitempty"` // 写入变更日志的条数，仅主题接口开启变更日志时返回
+}
+
+// DryRunReport 试运行报告，统计本次执行若真实写入将产生的变更
+type DryRunReport struct {
+	SyncMode             string                   `json:"sync_mode"`
+	TargetTable          string                   `json:"target_table"`
+	InsertCount          int64                    `json:"insert_count"`           // 预计新增记录数
+	UpdateCount          int64                    `json:"update_count"`           // 预计更新记录数
+	DeleteCount          int64                    `json:"delete_count"`           // 全量同步预计删除记录数
+	RejectCount          int64                    `json:"reject_count"`           // 预计写入失败的记录数
+	ValidationErrorCount int64                    `json:"validation_error_count"` // 数据治理校验错误数
+	RejectReasons        map[string]int64         `json:"reject_reasons"`
+	SampleRows           []map[string]interface{} `json:"sample_rows"`      // 转换后的待写入样例
+	RejectedSamples      []DryRunRejectedRow      `json:"rejected_samples"` // 被拒绝的记录样例
+}
+
+// DryRunRejectedRow 试运行被拒绝的记录
+type DryRunRejectedRow struct {
+	Reason  string                 `json:"reason"`
+	Message string                 `json:"message"`
+	Record  map[string]interface{} `json:"record"`
 }
 
 // ProcessingStepInfo 处理步骤信息
@@ -0,0 +1,41 @@
+/*
+ * @module service/thematic_sync/upstream_gate
+ * @description 上游质量门禁检查，源接口被基础库同步任务的质量门禁阻断时跳过主题同步，避免未通过质量检测的数据流入主题库
+ * @architecture 同步引擎执行前置检查，阻断记录由基础库质量门禁服务维护
+ * @documentReference ai_docs/thematic_sync_design.md
+ * @stateFlow 创建执行记录 -> 查询源接口阻断 -> 有阻断：执行记为skipped并记录task_skipped事件 -> 无阻断：正常执行同步管道
+ * @rules 试运行不检查门禁；跳过不视为执行失败，不返回错误；上游门禁再次通过或人工解除阻断后恢复执行
+ * @dependencies gorm.io/gorm
+ * @refs service/models/quality_gate.go, service/basic_library/quality_gate_service.go, sync_engine.go
+ */
+
+package thematic_sync
+
+import (
+	"context"
+	"datahub-service/service/models"
+
+	"gorm.io/gorm"
+)
+
+// ExecutionStatusSkipped 上游接口被质量门禁阻断时的执行状态
+const ExecutionStatusSkipped = "skipped"
+
+// findUpstreamBlocks 查询源接口上的质量门禁阻断，试运行和没有源接口时返回空
+func findUpstreamBlocks(ctx context.Context, db *gorm.DB, request *SyncRequest) ([]models.QualityGateBlock, error) {
+	var blocks []models.QualityGateBlock
+	if isDryRun(request) || len(request.SourceInterfaces) == 0 {
+		return blocks, nil
+	}
+	err := db.WithContext(ctx).Where("interface_id IN ?", request.SourceInterfaces).Order("interface_id").Find(&blocks).Error
+	return blocks, err
+}
+
+// blockedInterfaceIDs 阻断记录中的接口ID
+func blockedInterfaceIDs(blocks []models.QualityGateBlock) []string {
+	ids := make([]string, len(blocks))
+	for i, block := range blocks {
+		ids[i] = block.InterfaceID
+	}
+	return ids
+}
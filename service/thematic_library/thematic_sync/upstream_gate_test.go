@@ -0,0 +1,56 @@
+/*
+ * @module service/thematic_sync/upstream_gate_test
+ * @description 上游质量门禁检查测试，覆盖源接口被阻断时跳过执行并记录事件，以及试运行不受阻断影响
+ * @architecture 单元测试 - 使用SQLite内存库
+ * @documentReference ai_docs/thematic_sync_design.md
+ * @refs upstream_gate.go
+ */
+
+package thematic_sync
+
+import (
+	"context"
+	"datahub-service/service/eventlog"
+	"datahub-service/service/models"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// TestExecuteSyncSkipsBlockedUpstream 测试源接口被质量门禁阻断时跳过主题同步
+func TestExecuteSyncSkipsBlockedUpstream(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&models.ThematicSyncExecution{}, &models.QualityGateBlock{}, &models.ApplicationEvent{}))
+	require.NoError(t, db.Create(&models.QualityGateBlock{
+		InterfaceID: "basic-if-2", SyncTaskID: "sync-1", ExecutionID: "exec-1", Reasons: []string{"综合评分80.00低于最低评分90.00"}, BlockedAt: time.Now(),
+	}).Error)
+	eventlog.SetDefault(eventlog.NewService(db))
+	t.Cleanup(func() { eventlog.SetDefault(nil) })
+
+	ctx := context.Background()
+	request := &SyncRequest{TaskID: "thematic-task", ExecutionType: "manual", SourceInterfaces: []string{"basic-if-1", "basic-if-2"}, Context: ctx}
+	dryRun := &SyncRequest{SourceInterfaces: request.SourceInterfaces, Config: map[string]interface{}{"dry_run": true}}
+	blocks, err := findUpstreamBlocks(ctx, db, dryRun)
+	require.NoError(t, err)
+	assert.Empty(t, blocks, "试运行不检查门禁")
+
+	response, err := NewThematicSyncEngine(db, nil).ExecuteSync(request)
+	require.NoError(t, err, "跳过不视为失败")
+	assert.Equal(t, ExecutionStatusSkipped, response.Status)
+
+	var execution models.ThematicSyncExecution
+	require.NoError(t, db.First(&execution, "id = ?", response.ExecutionID).Error)
+	assert.Equal(t, ExecutionStatusSkipped, execution.Status)
+	assert.True(t, execution.IsCompleted())
+	assert.Equal(t, "upstream_quality_gate", execution.ErrorDetails["reason"])
+	assert.Equal(t, []interface{}{"basic-if-2"}, execution.ErrorDetails["blocked_interfaces"])
+
+	var events int64
+	require.NoError(t, db.Model(&models.ApplicationEvent{}).Where("event_type = ? AND object_id = ?", eventlog.EventTaskSkipped, "thematic-task").Count(&events).Error)
+	assert.Equal(t, int64(1), events)
+}
@@ -0,0 +1,95 @@
+/*
+ * @module service/thematic_library/thematic_sync_operation
+ * @description 主题同步任务执行操作的执行方，按执行记录刷新长时操作的状态、进度和结果链接，取消时把待执行或运行中的执行记为已取消并中断本进程内的同步流程
+ * @architecture 分层架构 - 业务服务层
+ * @documentReference ai_docs/thematic_sync_design.md
+ * @stateFlow 提交执行记录(pending) -> running -> success/failed/cancelled映射为操作状态；取消：pending/running -> cancelled -> 本进程执行时取消context
+ * @rules 进度按已处理记录数占源记录数的比例计算；取消状态写入数据库，待执行的记录不再开始执行，执行结束时不覆盖取消状态；独立worker上运行中的流程在结束时才感知取消
+ * @dependencies gorm.io/gorm, datahub-service/service/models
+ * @refs service/operation/service.go, thematic_sync_service.go, thematic_sync/sync_engine.go
+ */
+
+package thematic_library
+
+import (
+	"context"
+	"datahub-service/service/meta"
+	"datahub-service/service/models"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ErrThematicExecutionFinished 主题同步执行已结束
+var ErrThematicExecutionFinished = errors.New("主题同步执行已结束，无法取消")
+
+// RefreshOperation 按执行记录刷新主题同步任务执行操作
+func (tss *ThematicSyncService) RefreshOperation(ctx context.Context, op *models.Operation) error {
+	var record models.ThematicSyncExecution
+	if err := tss.db.WithContext(ctx).First(&record, "id = ?", op.ExecutionID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			op.Status = models.OperationStatusFailed
+			op.Error = "主题同步执行记录不存在"
+			return nil
+		}
+		return fmt.Errorf("获取主题同步执行记录失败: %w", err)
+	}
+
+	op.ResultLink = "/thematic-sync/executions/" + record.ID
+	switch record.Status {
+	case meta.ThematicSyncExecutionStatusPending:
+		op.Status = models.OperationStatusRunning
+		op.Message = "等待执行"
+	case meta.ThematicSyncExecutionStatusRunning:
+		op.Status = models.OperationStatusRunning
+		if record.SourceRecordCount > 0 {
+			op.Progress = int(record.ProcessedRecordCount * 100 / record.SourceRecordCount)
+		}
+		op.Message = fmt.Sprintf("已处理%d条记录", record.ProcessedRecordCount)
+	case meta.ThematicSyncExecutionStatusSuccess, "skipped":
+		op.Status = models.OperationStatusSucceeded
+		op.Message = fmt.Sprintf("同步完成，新增%d条，更新%d条", record.InsertedRecordCount, record.UpdatedRecordCount)
+	case meta.ThematicSyncExecutionStatusFailed:
+		op.Status = models.OperationStatusFailed
+		if message, ok := record.ErrorDetails["error"].(string); ok {
+			op.Error = message
+		}
+	case meta.ThematicSyncExecutionStatusCancelled:
+		op.Status = models.OperationStatusCanceled
+	}
+	return nil
+}
+
+// CancelOperation 取消主题同步任务执行操作
+func (tss *ThematicSyncService) CancelOperation(ctx context.Context, op *models.Operation) error {
+	return tss.CancelExecution(ctx, op.ExecutionID)
+}
+
+// CancelExecution 取消待执行或运行中的主题同步执行
+func (tss *ThematicSyncService) CancelExecution(ctx context.Context, executionID string) error {
+	var record models.ThematicSyncExecution
+	if err := tss.db.WithContext(ctx).First(&record, "id = ?", executionID).Error; err != nil {
+		return err
+	}
+
+	result := tss.db.WithContext(ctx).Model(&models.ThematicSyncExecution{}).
+		Where("id = ? AND status IN ?", executionID, []string{meta.ThematicSyncExecutionStatusPending, meta.ThematicSyncExecutionStatusRunning}).
+		Updates(map[string]interface{}{
+			"status":        meta.ThematicSyncExecutionStatusCancelled,
+			"end_time":      time.Now(),
+			"error_details": models.JSONB{"error": "执行已取消"},
+		})
+	if result.Error != nil {
+		return fmt.Errorf("取消主题同步执行失败: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrThematicExecutionFinished
+	}
+
+	if cancel, ok := tss.runningExecutions.Load(executionID); ok {
+		cancel.(context.CancelFunc)()
+	}
+	return nil
+}
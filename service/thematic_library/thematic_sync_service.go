@@ -13,12 +13,19 @@ package thematic_library
 
 import (
 	"context"
+	"datahub-service/service/capacity"
+	"datahub-service/service/chaos"
+	"datahub-service/service/execution"
 	"datahub-service/service/governance"
+	"datahub-service/service/meta"
 	"datahub-service/service/models"
+	"datahub-service/service/tenant"
 	"datahub-service/service/thematic_library/thematic_sync"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
+	"sync"
 	"time"
 
 	"github.com/google/uuid"
@@ -89,6 +96,12 @@ type ThematicSyncService struct {
 		TryLock(ctx context.Context, key string, ttl time.Duration) (bool, error)
 		Unlock(ctx context.Context, key string) error
 	}
+	// 执行命令分发器，为空时在本进程直接执行
+	dispatcher execution.Dispatcher
+	// 本进程正在执行的执行记录ID到取消函数的映射
+	runningExecutions sync.Map
+	// 库和租户配额，为空时不检查
+	quota *capacity.QuotaService
 }
 
 // NewThematicSyncService 创建主题同步服务 - 简化版本
@@ -205,6 +218,20 @@ func (tss *ThematicSyncService) CreateSyncTask(ctx context.Context, req *CreateT
 	// 计算下次执行时间
 	task.UpdateNextRunTime()
 
+	// 任务与目标主题库归属同一租户
+	var library models.ThematicLibrary
+	if err := tss.db.WithContext(ctx).Select("id", "tenant_id").First(&library, "id = ?", req.ThematicLibraryID).Error; err != nil {
+		return nil, fmt.Errorf("主题库不存在: %w", err)
+	}
+	task.TenantID = library.TenantID
+
+	// 检查主题库和所属租户的任务数配额
+	if tss.quota != nil {
+		if err := tss.quota.CheckTaskCreation(ctx, req.ThematicLibraryID); err != nil {
+			return nil, err
+		}
+	}
+
 	if err := tss.db.Create(task).Error; err != nil {
 		return nil, fmt.Errorf("创建同步任务失败: %w", err)
 	}
@@ -224,7 +251,7 @@ func (tss *ThematicSyncService) CreateSyncTask(ctx context.Context, req *CreateT
 // UpdateSyncTask 更新同步任务
 func (tss *ThematicSyncService) UpdateSyncTask(ctx context.Context, taskID string, req *UpdateThematicSyncTaskRequest) (*models.ThematicSyncTask, error) {
 	var task models.ThematicSyncTask
-	if err := tss.db.First(&task, "id = ?", taskID).Error; err != nil {
+	if err := tss.db.WithContext(ctx).First(&task, "id = ?", taskID).Error; err != nil {
 		return nil, fmt.Errorf("获取同步任务失败: %w", err)
 	}
 
@@ -314,7 +341,15 @@ func (tss *ThematicSyncService) UpdateSyncTask(ctx context.Context, taskID strin
 	// 重新计算下次执行时间
 	task.UpdateNextRunTime()
 
-	if err := tss.db.Save(&task).Error; err != nil {
+	// 校验并递增版本后保存，防止并发修改互相覆盖
+	if err := tss.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		version, err := models.BumpRowVersion(tx, &models.ThematicSyncTask{}, taskID, req.RowVersion)
+		if err != nil {
+			return err
+		}
+		task.RowVersion = version
+		return tx.Save(&task).Error
+	}); err != nil {
 		return nil, fmt.Errorf("更新同步任务失败: %w", err)
 	}
 
@@ -355,7 +390,7 @@ func (tss *ThematicSyncService) UpdateSyncTask(ctx context.Context, taskID strin
 // GetSyncTask 获取同步任务
 func (tss *ThematicSyncService) GetSyncTask(ctx context.Context, taskID string) (*models.ThematicSyncTask, error) {
 	var task models.ThematicSyncTask
-	if err := tss.db.Preload("ThematicLibrary").Preload("ThematicInterface").
+	if err := tss.db.WithContext(ctx).Preload("ThematicLibrary").Preload("ThematicInterface").
 		First(&task, "id = ?", taskID).Error; err != nil {
 		return nil, fmt.Errorf("获取同步任务失败: %w", err)
 	}
@@ -365,7 +400,7 @@ func (tss *ThematicSyncService) GetSyncTask(ctx context.Context, taskID string)
 
 // ListSyncTasks 获取同步任务列表
 func (tss *ThematicSyncService) ListSyncTasks(ctx context.Context, req *ListSyncTasksRequest) (*ListSyncTasksResponse, error) {
-	query := tss.db.Model(&models.ThematicSyncTask{})
+	query := tss.db.WithContext(ctx).Model(&models.ThematicSyncTask{})
 
 	// 添加过滤条件
 	if req.ThematicLibraryID != "" {
@@ -406,13 +441,13 @@ func (tss *ThematicSyncService) ListSyncTasks(ctx context.Context, req *ListSync
 func (tss *ThematicSyncService) DeleteSyncTask(ctx context.Context, taskID string) error {
 	// 检查任务是否存在
 	var task models.ThematicSyncTask
-	if err := tss.db.First(&task, "id = ?", taskID).Error; err != nil {
+	if err := tss.db.WithContext(ctx).First(&task, "id = ?", taskID).Error; err != nil {
 		return fmt.Errorf("获取同步任务失败: %w", err)
 	}
 
 	// 所有状态的任务都可以删除（根据 CanDelete 方法）
 	// 开启事务删除任务和相关记录
-	return tss.db.Transaction(func(tx *gorm.DB) error {
+	return tss.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
 		// 删除执行记录
 		if err := tx.Where("task_id = ?", taskID).Delete(&models.ThematicSyncExecution{}).Error; err != nil {
 			return fmt.Errorf("删除执行记录失败: %w", err)
@@ -440,10 +475,17 @@ func (tss *ThematicSyncService) ExecuteSyncTaskAsync(ctx context.Context, taskID
 		return "", fmt.Errorf("任务状态不允许执行: %s", task.Status)
 	}
 
+	// 主题库或所属租户的数据量达到配额上限时不执行
+	if tss.quota != nil {
+		if err := tss.quota.CheckSync(ctx, task.ThematicLibraryID); err != nil {
+			return "", err
+		}
+	}
+
 	// 创建执行记录（状态为pending）
 	startTime := time.Now()
 	executionID := uuid.New().String()
-	execution := &models.ThematicSyncExecution{
+	executionRecord := &models.ThematicSyncExecution{
 		ID:            executionID,
 		TaskID:        taskID,
 		ExecutionType: req.ExecutionType,
@@ -453,347 +495,144 @@ func (tss *ThematicSyncService) ExecuteSyncTaskAsync(ctx context.Context, taskID
 		CreatedBy:     "system",
 	}
 
-	if err := tss.db.Create(execution).Error; err != nil {
+	if err := tss.db.Create(executionRecord).Error; err != nil {
 		return "", fmt.Errorf("创建执行记录失败: %w", err)
 	}
 
-	// 启动异步执行goroutine
-	go func() {
-		// 使用独立的context避免原始请求context被取消
-		execCtx := context.Background()
-		slog.Info("开始异步执行主题同步任务", "taskID", taskID, "executionID", executionID)
-
-		// 执行同步任务（带executionID）
-		_, err := tss.executeSyncTaskInternalAsync(execCtx, taskID, executionID, req)
+	// 交给执行面处理
+	if tss.dispatcher != nil {
+		cmd := execution.NewCommand(execution.CommandKindThematicSync, taskID)
+		cmd.ExecutionID = executionID
+		cmd.Payload, err = executeRequestToPayload(req)
+		if err == nil {
+			err = tss.dispatcher.Dispatch(ctx, cmd)
+		}
 		if err != nil {
-			slog.Error("异步执行主题同步任务失败", "taskID", taskID, "executionID", executionID, "error", err)
-
-			// 更新执行记录状态为失败
-			if updateErr := tss.db.Model(&models.ThematicSyncExecution{}).
-				Where("id = ?", executionID).
-				Updates(map[string]interface{}{
-					"status":        "failed",
-					"error_details": models.JSONB{"error": err.Error()},
-					"end_time":      time.Now(),
-				}).Error; updateErr != nil {
-				slog.Error("更新执行记录失败状态失败", "executionID", executionID, "error", updateErr)
-			}
-		} else {
-			slog.Info("异步执行主题同步任务成功", "taskID", taskID, "executionID", executionID)
+			tss.markExecutionFailed(executionID, err)
+			return "", fmt.Errorf("分发同步任务执行命令失败: %w", err)
 		}
-	}()
-
-	return executionID, nil
-}
-
-// ExecuteSyncTask 同步执行同步任务（保留用于调度器调用）
-func (tss *ThematicSyncService) ExecuteSyncTask(ctx context.Context, taskID string, req *ExecuteSyncTaskRequest) (*thematic_sync.SyncResponse, error) {
-	// 获取任务信息
-	task, err := tss.GetSyncTask(ctx, taskID)
-	if err != nil {
-		return nil, err
+		return executionID, nil
 	}
 
-	// 检查任务状态
-	if !task.CanStart() {
-		return nil, fmt.Errorf("任务状态不允许执行: %s", task.Status)
-	}
+	// 启动异步执行goroutine，使用独立的context避免原始请求context被取消
+	go tss.runSyncExecution(context.Background(), taskID, executionID, req)
 
-	return tss.executeSyncTaskInternal(ctx, taskID, req)
+	return executionID, nil
 }
 
-// executeSyncTaskInternal 内部同步执行方法（供同步和异步调用使用）
-func (tss *ThematicSyncService) executeSyncTaskInternal(ctx context.Context, taskID string, req *ExecuteSyncTaskRequest) (*thematic_sync.SyncResponse, error) {
-	// 获取任务信息
-	task, err := tss.GetSyncTask(ctx, taskID)
-	if err != nil {
-		return nil, err
-	}
-
-	// 解析源库配置
-	var sourceLibraryConfigs []thematic_sync.SourceLibraryConfig
-	if len(task.SourceLibraries) > 0 {
-		// 从JSONBGenericArray中解析源库配置
-		sourceLibrariesRaw := []interface{}(task.SourceLibraries)
-		if len(sourceLibrariesRaw) > 0 {
-			for _, configRaw := range sourceLibrariesRaw {
-				if configMap, ok := configRaw.(map[string]interface{}); ok {
-					libraryID := getStringFromMap(configMap, "library_id")
-
-					// 处理嵌套的interfaces结构
-					var interfaceConfigs []thematic_sync.SourceInterfaceConfig
-					if interfacesRaw, exists := configMap["interfaces"]; exists {
-						if interfacesSlice, ok := interfacesRaw.([]interface{}); ok {
-							for _, interfaceRaw := range interfacesSlice {
-								if interfaceMap, ok := interfaceRaw.(map[string]interface{}); ok {
-									interfaceConfig := thematic_sync.SourceInterfaceConfig{
-										InterfaceID: getStringFromMap(interfaceMap, "interface_id"),
-									}
-
-									// 解析增量配置
-									if incrementalRaw, exists := interfaceMap["incremental_config"]; exists {
-										if incrementalMap, ok := incrementalRaw.(map[string]interface{}); ok {
-											incrementalConfig := &thematic_sync.IncrementalConfig{
-												Enabled:            getBoolFromMap(incrementalMap, "enabled"),
-												IncrementalField:   getStringFromMap(incrementalMap, "incremental_field"),
-												FieldType:          getStringFromMap(incrementalMap, "field_type"),
-												CompareOperator:    getStringFromMap(incrementalMap, "compare_operator"),
-												LastSyncValue:      getStringFromMap(incrementalMap, "last_sync_value"),
-												InitialValue:       getStringFromMap(incrementalMap, "initial_value"),
-												MaxLookbackHours:   getIntFromMap(incrementalMap, "max_lookback_hours"),
-												CheckDeletedField:  getStringFromMap(incrementalMap, "check_deleted_field"),
-												DeletedValue:       getStringFromMap(incrementalMap, "deleted_value"),
-												BatchSize:          getIntFromMap(incrementalMap, "batch_size"),
-												SyncDeletedRecords: getBoolFromMap(incrementalMap, "sync_deleted_records"),
-												TimestampFormat:    getStringFromMap(incrementalMap, "timestamp_format"),
-												TimeZone:           getStringFromMap(incrementalMap, "timezone"),
-											}
-
-											// 设置默认值
-											if incrementalConfig.CompareOperator == "" {
-												incrementalConfig.CompareOperator = ">"
-											}
-											if incrementalConfig.BatchSize == 0 {
-												incrementalConfig.BatchSize = 1000
-											}
-											if incrementalConfig.TimeZone == "" {
-												incrementalConfig.TimeZone = "Asia/Shanghai"
-											}
-
-											interfaceConfig.IncrementalConfig = incrementalConfig
-										}
-									}
-
-									interfaceConfigs = append(interfaceConfigs, interfaceConfig)
-								}
-							}
-						}
-					}
-
-					// 如果没有找到嵌套结构，尝试直接获取interface_id（向前兼容）
-					if len(interfaceConfigs) == 0 {
-						if interfaceID := getStringFromMap(configMap, "interface_id"); interfaceID != "" {
-							interfaceConfig := thematic_sync.SourceInterfaceConfig{
-								InterfaceID: interfaceID,
-							}
-							interfaceConfigs = append(interfaceConfigs, interfaceConfig)
-						}
-					}
-
-					// 为每个接口创建一个配置
-					for _, interfaceConfig := range interfaceConfigs {
-						config := thematic_sync.SourceLibraryConfig{
-							LibraryID:         libraryID,
-							InterfaceID:       interfaceConfig.InterfaceID,
-							SQLQuery:          getStringFromMap(configMap, "sql_query"),
-							IncrementalConfig: interfaceConfig.IncrementalConfig,
-						}
-
-						// 解析参数
-						if params, exists := configMap["parameters"]; exists {
-							if paramsMap, ok := params.(map[string]interface{}); ok {
-								config.Parameters = paramsMap
-							}
-						}
-
-						// 解析过滤器
-						if filters, exists := configMap["filters"]; exists {
-							if filtersSlice, ok := filters.([]interface{}); ok {
-								for _, filterRaw := range filtersSlice {
-									if filterMap, ok := filterRaw.(map[string]interface{}); ok {
-										filter := thematic_sync.FilterConfig{
-											Field:    getStringFromMap(filterMap, "field"),
-											Operator: getStringFromMap(filterMap, "operator"),
-											Value:    filterMap["value"],
-											LogicOp:  getStringFromMap(filterMap, "logic_op"),
-										}
-										config.Filters = append(config.Filters, filter)
-									}
-								}
-							}
-						}
-
-						// 解析转换配置
-						if transforms, exists := configMap["transforms"]; exists {
-							if transformsSlice, ok := transforms.([]interface{}); ok {
-								for _, transformRaw := range transformsSlice {
-									if transformMap, ok := transformRaw.(map[string]interface{}); ok {
-										transform := thematic_sync.TransformConfig{
-											SourceField: getStringFromMap(transformMap, "source_field"),
-											TargetField: getStringFromMap(transformMap, "target_field"),
-											Transform:   getStringFromMap(transformMap, "transform"),
-										}
-
-										if transformConfig, exists := transformMap["config"]; exists {
-											if transformConfigMap, ok := transformConfig.(map[string]interface{}); ok {
-												transform.Config = transformConfigMap
-											}
-										}
-
-										config.Transforms = append(config.Transforms, transform)
-									}
-								}
-							}
-						}
-
-						sourceLibraryConfigs = append(sourceLibraryConfigs, config)
-					}
-				}
-			}
+// HandleExecutionCommand 执行面处理主题库同步命令
+func (tss *ThematicSyncService) HandleExecutionCommand(ctx context.Context, cmd *execution.Command) error {
+	req := &ExecuteSyncTaskRequest{}
+	if len(cmd.Payload) > 0 {
+		data, err := json.Marshal(cmd.Payload)
+		if err != nil {
+			return fmt.Errorf("序列化命令参数失败: %w", err)
+		}
+		if err := json.Unmarshal(data, req); err != nil {
+			return fmt.Errorf("解析命令参数失败: %w", err)
 		}
 	}
 
-	// 解析SQL查询配置（优先级更高）
-	var sqlQueryConfigs []*thematic_sync.SQLQueryConfig
-	if len(task.SQLQueries) > 0 {
-		sqlConfigsRaw := []interface{}(task.SQLQueries)
-		if len(sqlConfigsRaw) > 0 {
-			for _, configRaw := range sqlConfigsRaw {
-				if configMap, ok := configRaw.(map[string]interface{}); ok {
-					config := &thematic_sync.SQLQueryConfig{
-						SQLQuery: getStringFromMap(configMap, "sql_query"),
-						Timeout:  30,    // 默认30秒
-						MaxRows:  10000, // 默认1万行
-					}
-
-					// 解析参数
-					if params, exists := configMap["parameters"]; exists {
-						if paramsMap, ok := params.(map[string]interface{}); ok {
-							config.Parameters = paramsMap
-						}
-					}
+	tss.runSyncExecution(ctx, cmd.TaskID, cmd.ExecutionID, req)
+	return nil
+}
 
-					// 解析超时时间
-					if timeout, exists := configMap["timeout"]; exists {
-						if timeoutFloat, ok := timeout.(float64); ok {
-							config.Timeout = int(timeoutFloat)
-						} else if timeoutInt, ok := timeout.(int); ok {
-							config.Timeout = timeoutInt
-						}
-					}
+// runSyncExecution 执行已创建执行记录的同步任务，失败时回写执行记录状态
+func (tss *ThematicSyncService) runSyncExecution(ctx context.Context, taskID, executionID string, req *ExecuteSyncTaskRequest) {
+	slog.Info("开始异步执行主题同步任务", "taskID", taskID, "executionID", executionID)
 
-					// 解析最大行数
-					if maxRows, exists := configMap["max_rows"]; exists {
-						if maxRowsFloat, ok := maxRows.(float64); ok {
-							config.MaxRows = int(maxRowsFloat)
-						} else if maxRowsInt, ok := maxRows.(int); ok {
-							config.MaxRows = maxRowsInt
-						}
-					}
+	// 登记取消函数，取消执行时中断本进程内的同步流程
+	ctx, cancel := context.WithCancel(ctx)
+	tss.runningExecutions.Store(executionID, cancel)
+	defer func() {
+		tss.runningExecutions.Delete(executionID)
+		cancel()
+	}()
 
-					// 只添加有效的SQL查询
-					if config.SQLQuery != "" {
-						sqlQueryConfigs = append(sqlQueryConfigs, config)
-					}
-				}
-			}
+	// 执行同步任务（带executionID）
+	if _, err := tss.executeSyncTaskInternalAsync(ctx, taskID, executionID, req); err != nil {
+		if errors.Is(err, thematic_sync.ErrExecutionCancelled) {
+			slog.Info("主题同步执行已取消，不再执行", "taskID", taskID, "executionID", executionID)
+			return
 		}
+		slog.Error("异步执行主题同步任务失败", "taskID", taskID, "executionID", executionID, "error", err)
+		tss.markExecutionFailed(executionID, err)
+		return
 	}
 
-	// 构建同步请求配置
-	configMap := make(map[string]interface{})
-
-	// 优先添加SQL查询配置（SQL模式）
-	if len(sqlQueryConfigs) > 0 {
-		configMap["sql_queries"] = sqlQueryConfigs
-		slog.Debug("任务使用SQL查询模式", "queryCount", len(sqlQueryConfigs))
-	} else if len(sourceLibraryConfigs) > 0 {
-		// 使用接口模式
-		configMap["source_libraries"] = sourceLibraryConfigs
-		slog.Debug("任务使用接口模式", "libraryCount", len(sourceLibraryConfigs))
+	slog.Info("异步执行主题同步任务成功", "taskID", taskID, "executionID", executionID)
+}
 
-		// 调试：打印源库配置详情
-		for i, config := range sourceLibraryConfigs {
-			slog.Debug("源库配置详情", "index", i, "libraryID", config.LibraryID,
-				"interfaceID", config.InterfaceID, "hasIncrementalConfig", config.IncrementalConfig != nil)
-			if config.IncrementalConfig != nil {
-				slog.Debug("增量配置详情", "index", i,
-					"enabled", config.IncrementalConfig.Enabled,
-					"field", config.IncrementalConfig.IncrementalField,
-					"fieldType", config.IncrementalConfig.FieldType,
-					"lastSyncValue", config.IncrementalConfig.LastSyncValue,
-					"initialValue", config.IncrementalConfig.InitialValue)
-			}
-		}
-	} else {
-		return nil, fmt.Errorf("任务必须配置数据源：请配置 SQLQueries(SQL模式) 或 SourceLibraries(接口模式)")
+// markExecutionFailed 更新执行记录状态为失败，已取消的执行保留取消状态
+func (tss *ThematicSyncService) markExecutionFailed(executionID string, cause error) {
+	if err := tss.db.Model(&models.ThematicSyncExecution{}).
+		Where("id = ? AND status <> ?", executionID, meta.ThematicSyncExecutionStatusCancelled).
+		Updates(map[string]interface{}{
+			"status":        "failed",
+			"error_details": models.JSONB{"error": cause.Error()},
+			"end_time":      time.Now(),
+		}).Error; err != nil {
+		slog.Error("更新执行记录失败状态失败", "executionID", executionID, "error", err)
 	}
+}
 
-	// 添加各种规则配置
-	if len(task.KeyMatchingRules) > 0 {
-		var keyRules KeyMatchingRules
-		if err := json.Unmarshal([]byte(fmt.Sprintf("%s", task.KeyMatchingRules)), &keyRules); err == nil {
-			configMap["key_matching_rules"] = keyRules
-		} else {
-			slog.Warn("解析KeyMatchingRules失败", "error", err)
-		}
+// executeRequestToPayload 将执行请求转换为命令参数
+func executeRequestToPayload(req *ExecuteSyncTaskRequest) (map[string]interface{}, error) {
+	if req == nil {
+		return nil, nil
 	}
-
-	if len(task.FieldMappingRules) > 0 {
-		// 直接使用 task.FieldMappingRules，它已经是 map[string]interface{} 类型
-		configMap["field_mapping_rules"] = task.FieldMappingRules
-		slog.Debug("添加字段映射规则到配置", "rules", task.FieldMappingRules)
+	data, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("序列化执行请求失败: %w", err)
 	}
-
-	// 添加数据治理规则配置
-	if len(task.QualityRuleConfigs) > 0 {
-		var qualityRuleConfigs []models.QualityRuleConfig
-		if err := json.Unmarshal([]byte(fmt.Sprintf("%s", task.QualityRuleConfigs)), &qualityRuleConfigs); err == nil {
-			configMap["quality_rule_configs"] = qualityRuleConfigs
-		}
+	var payload map[string]interface{}
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return nil, fmt.Errorf("转换执行请求失败: %w", err)
 	}
+	return payload, nil
+}
 
-	if len(task.CleansingRuleConfigs) > 0 {
-		var cleansingRuleConfigs []models.DataCleansingConfig
-		if err := json.Unmarshal([]byte(fmt.Sprintf("%s", task.CleansingRuleConfigs)), &cleansingRuleConfigs); err == nil {
-			configMap["cleansing_rule_configs"] = cleansingRuleConfigs
-		}
+// ExecuteSyncTask 同步执行同步任务（保留用于调度器调用）
+func (tss *ThematicSyncService) ExecuteSyncTask(ctx context.Context, taskID string, req *ExecuteSyncTaskRequest) (*thematic_sync.SyncResponse, error) {
+	// 获取任务信息
+	task, err := tss.GetSyncTask(ctx, taskID)
+	if err != nil {
+		return nil, err
 	}
 
-	if len(task.MaskingRuleConfigs) > 0 {
-		var maskingRuleConfigs []models.DataMaskingConfig
-		if err := json.Unmarshal([]byte(fmt.Sprintf("%s", task.MaskingRuleConfigs)), &maskingRuleConfigs); err == nil {
-			configMap["masking_rule_configs"] = maskingRuleConfigs
-		}
+	// 检查任务状态
+	if !task.CanStart() {
+		return nil, fmt.Errorf("任务状态不允许执行: %s", task.Status)
 	}
 
-	if len(task.GovernanceConfig) > 0 {
-		var governanceConfig GovernanceExecutionConfig
-		if err := json.Unmarshal([]byte(fmt.Sprintf("%s", task.GovernanceConfig)), &governanceConfig); err == nil {
-			configMap["governance_config"] = governanceConfig
+	// 主题库或所属租户的数据量达到配额上限时不执行
+	if tss.quota != nil {
+		if err := tss.quota.CheckSync(ctx, task.ThematicLibraryID); err != nil {
+			return nil, err
 		}
 	}
 
-	// 添加执行选项
-	if req.Options != nil {
-		optionsBytes, _ := json.Marshal(req.Options)
-		var optionsMap map[string]interface{}
-		if err := json.Unmarshal(optionsBytes, &optionsMap); err == nil {
-			for key, value := range optionsMap {
-				configMap[key] = value
-			}
-		}
-	}
+	return tss.executeSyncTaskInternal(ctx, taskID, req)
+}
 
-	// 构建源库和接口列表
-	var sourceLibraries []string
-	var finalSourceInterfaces []string
+// executeSyncTaskInternal 内部同步执行方法（供同步和异步调用使用）
+func (tss *ThematicSyncService) executeSyncTaskInternal(ctx context.Context, taskID string, req *ExecuteSyncTaskRequest) (*thematic_sync.SyncResponse, error) {
+	return tss.executeSyncTaskInternalAsync(ctx, taskID, "", req)
+}
 
-	for _, config := range sourceLibraryConfigs {
-		sourceLibraries = append(sourceLibraries, config.LibraryID)
-		finalSourceInterfaces = append(finalSourceInterfaces, config.InterfaceID)
+// executeSyncTaskInternalAsync 内部异步执行方法（带executionID，为空时由同步引擎创建执行记录）
+func (tss *ThematicSyncService) executeSyncTaskInternalAsync(ctx context.Context, taskID string, executionID string, req *ExecuteSyncTaskRequest) (*thematic_sync.SyncResponse, error) {
+	// 获取任务信息
+	task, err := tss.GetSyncTask(ctx, taskID)
+	if err != nil {
+		return nil, err
 	}
 
-	syncRequest := &thematic_sync.SyncRequest{
-		TaskID:            taskID,
-		ExecutionType:     req.ExecutionType,
-		SourceLibraries:   sourceLibraries,
-		SourceInterfaces:  finalSourceInterfaces,
-		TargetLibraryID:   task.ThematicLibraryID,
-		TargetInterfaceID: task.ThematicInterfaceID,
-		Config:            configMap,
-		Context:           ctx,
+	syncRequest, err := tss.buildSyncRequest(ctx, task, req)
+	if err != nil {
+		return nil, err
 	}
+	syncRequest.ExecutionID = executionID // 传递执行记录ID
 
 	// 设置进度回调
 	tss.syncEngine.SetProgressCallback(func(progress *thematic_sync.SyncProgress) {
@@ -805,14 +644,8 @@ func (tss *ThematicSyncService) executeSyncTaskInternal(ctx context.Context, tas
 	return tss.syncEngine.ExecuteSync(syncRequest)
 }
 
-// executeSyncTaskInternalAsync 内部异步执行方法（带executionID）
-func (tss *ThematicSyncService) executeSyncTaskInternalAsync(ctx context.Context, taskID string, executionID string, req *ExecuteSyncTaskRequest) (*thematic_sync.SyncResponse, error) {
-	// 获取任务信息
-	task, err := tss.GetSyncTask(ctx, taskID)
-	if err != nil {
-		return nil, err
-	}
-
+// buildSyncRequest 按任务的数据源、规则和治理配置构建同步请求
+func (tss *ThematicSyncService) buildSyncRequest(ctx context.Context, task *models.ThematicSyncTask, req *ExecuteSyncTaskRequest) (*thematic_sync.SyncRequest, error) {
 	// 解析源库配置
 	var sourceLibraryConfigs []thematic_sync.SourceLibraryConfig
 	if len(task.SourceLibraries) > 0 {
@@ -1087,9 +920,8 @@ func (tss *ThematicSyncService) executeSyncTaskInternalAsync(ctx context.Context
 		finalSourceInterfaces = append(finalSourceInterfaces, config.InterfaceID)
 	}
 
-	syncRequest := &thematic_sync.SyncRequest{
-		TaskID:            taskID,
-		ExecutionID:       executionID, // 传递执行记录ID
+	return &thematic_sync.SyncRequest{
+		TaskID:            task.ID,
 		ExecutionType:     req.ExecutionType,
 		SourceLibraries:   sourceLibraries,
 		SourceInterfaces:  finalSourceInterfaces,
@@ -1097,22 +929,13 @@ func (tss *ThematicSyncService) executeSyncTaskInternalAsync(ctx context.Context
 		TargetInterfaceID: task.ThematicInterfaceID,
 		Config:            configMap,
 		Context:           ctx,
-	}
-
-	// 设置进度回调
-	tss.syncEngine.SetProgressCallback(func(progress *thematic_sync.SyncProgress) {
-		// 这里可以实现进度通知逻辑，比如通过WebSocket推送给前端
-		// 或者存储到缓存中供查询
-	})
-
-	// 执行同步
-	return tss.syncEngine.ExecuteSync(syncRequest)
+	}, nil
 }
 
 // GetSyncExecution 获取同步执行记录
 func (tss *ThematicSyncService) GetSyncExecution(ctx context.Context, executionID string) (*models.ThematicSyncExecution, error) {
 	var execution models.ThematicSyncExecution
-	if err := tss.db.Preload("Task").First(&execution, "id = ?", executionID).Error; err != nil {
+	if err := tss.db.Preload("Task").Scopes(tenant.OwnerScope(ctx, "task_id", "thematic_sync_tasks")).First(&execution, "id = ?", executionID).Error; err != nil {
 		return nil, fmt.Errorf("获取执行记录失败: %w", err)
 	}
 
@@ -1121,7 +944,7 @@ func (tss *ThematicSyncService) GetSyncExecution(ctx context.Context, executionI
 
 // ListSyncExecutions 获取同步执行记录列表
 func (tss *ThematicSyncService) ListSyncExecutions(ctx context.Context, req *ListSyncExecutionsRequest) (*ListSyncExecutionsResponse, error) {
-	query := tss.db.Model(&models.ThematicSyncExecution{})
+	query := tss.db.Model(&models.ThematicSyncExecution{}).Scopes(tenant.OwnerScope(ctx, "task_id", "thematic_sync_tasks"))
 
 	// 添加过滤条件
 	if req.TaskID != "" {
@@ -1239,7 +1062,7 @@ func (tss *ThematicSyncService) PauseSyncTask(ctx context.Context, taskID string
 		"updated_at": time.Now(),
 	}
 
-	if err := tss.db.Model(&models.ThematicSyncTask{}).Where("id = ?", taskID).Updates(updates).Error; err != nil {
+	if err := tss.db.WithContext(ctx).Model(&models.ThematicSyncTask{}).Where("id = ?", taskID).Updates(updates).Error; err != nil {
 		return fmt.Errorf("暂停同步任务失败: %w", err)
 	}
 
@@ -1272,7 +1095,7 @@ func (tss *ThematicSyncService) ActivateSyncTask(ctx context.Context, taskID str
 		"updated_at": time.Now(),
 	}
 
-	if err := tss.db.Model(&models.ThematicSyncTask{}).Where("id = ?", taskID).Updates(updates).Error; err != nil {
+	if err := tss.db.WithContext(ctx).Model(&models.ThematicSyncTask{}).Where("id = ?", taskID).Updates(updates).Error; err != nil {
 		return fmt.Errorf("激活同步任务失败: %w", err)
 	}
 
@@ -1402,6 +1225,16 @@ func getIntFromMap(m map[string]interface{}, key string) int {
 
 // ======================== 调度器功能实现 ========================
 
+// SetQuota 设置库和租户配额，创建任务和执行同步前检查
+func (tss *ThematicSyncService) SetQuota(quota *capacity.QuotaService) {
+	tss.quota = quota
+}
+
+// SetDispatcher 设置执行命令分发器
+func (tss *ThematicSyncService) SetDispatcher(dispatcher execution.Dispatcher) {
+	tss.dispatcher = dispatcher
+}
+
 // SetDistributedLock 设置分布式锁
 func (tss *ThematicSyncService) SetDistributedLock(lock interface {
 	TryLock(ctx context.Context, key string, ttl time.Duration) (bool, error)
@@ -1581,6 +1414,12 @@ func (tss *ThematicSyncService) checkIntervalTasks() {
 func (tss *ThematicSyncService) executeScheduledTask(taskID string) {
 	slog.Info("执行主题调度任务", "taskID", taskID)
 
+	// 故障注入：模拟调度延迟
+	if err := chaos.Inject(tss.ctx, models.FaultTypeScheduleDelay, taskID); err != nil {
+		slog.Warn("调度延迟注入被中断，跳过本次执行", "taskID", taskID, "error", err)
+		return
+	}
+
 	// 如果有分布式锁，使用锁保护执行
 	if tss.distributedLock != nil {
 		lockKey := fmt.Sprintf("thematic_library:%s", taskID)
@@ -1625,8 +1464,12 @@ func (tss *ThematicSyncService) executeScheduledTask(taskID string) {
 		Options:       nil,
 	}
 
-	// 执行同步任务
-	_, err = tss.ExecuteSyncTask(tss.ctx, taskID, req)
+	// 执行同步任务，执行面独立部署时只负责分发
+	if tss.dispatcher != nil && tss.dispatcher.IsRemote() {
+		_, err = tss.ExecuteSyncTaskAsync(tss.ctx, taskID, req)
+	} else {
+		_, err = tss.ExecuteSyncTask(tss.ctx, taskID, req)
+	}
 	if err != nil {
 		slog.Error("执行调度任务失败", "taskID", taskID, "error", err)
 		return
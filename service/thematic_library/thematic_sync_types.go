@@ -43,7 +43,7 @@ type CreateThematicSyncTaskRequest struct {
 	GovernanceConfig     *GovernanceExecutionConfig   `json:"governance_config,omitempty"`
 
 	ScheduleConfig *ScheduleConfig `json:"schedule_config" binding:"required"`
-	CreatedBy      string          `json:"created_by" binding:"required"`
+	CreatedBy      string          `json:"-"` // 由控制器按认证身份填充
 }
 
 // UpdateThematicSyncTaskRequest 更新主题同步任务请求
@@ -70,7 +70,8 @@ type UpdateThematicSyncTaskRequest struct {
 	MaskingRuleConfigs   []models.DataMaskingConfig   `json:"masking_rule_configs,omitempty"`
 	GovernanceConfig     *GovernanceExecutionConfig   `json:"governance_config,omitempty"`
 
-	UpdatedBy string `json:"updated_by" binding:"required"`
+	UpdatedBy  string `json:"-"`                     // 由控制器按认证身份填充
+	RowVersion int64  `json:"row_version,omitempty"` // 期望版本，也可通过If-Match头传递
 }
 
 // ListSyncTasksRequest 获取同步任务列表请求
@@ -160,7 +161,7 @@ type FieldMapping struct {
 // SortField 排序字段
 type SortField struct {
 	Field string `json:"field" validate:"required"`
-	Order string `json:"order" validate:"oneof=ASC DESC" default:"ASC"`
+	Order string `json:"order" validate:"omitempty,oneof=ASC DESC" default:"ASC"`
 }
 
 // DataFilterRule 数据过滤规则
@@ -168,7 +169,7 @@ type DataFilterRule struct {
 	Field    string      `json:"field" validate:"required"`
 	Operator string      `json:"operator" validate:"required,oneof=eq ne gt lt ge le in nin like"`
 	Value    interface{} `json:"value" validate:"required"`
-	LogicOp  string      `json:"logic_op,omitempty" validate:"oneof=AND OR" default:"AND"`
+	LogicOp  string      `json:"logic_op,omitempty" validate:"omitempty,oneof=AND OR" default:"AND"`
 }
 
 // IncrementalConfig 增量同步配置
@@ -255,7 +256,7 @@ type ExecutionFilter struct {
 	Field    string      `json:"field" validate:"required"`
 	Operator string      `json:"operator" validate:"required,oneof=eq ne gt lt ge le in nin like"`
 	Value    interface{} `json:"value" validate:"required"`
-	LogicOp  string      `json:"logic_op,omitempty" validate:"oneof=AND OR" default:"AND"`
+	LogicOp  string      `json:"logic_op,omitempty" validate:"omitempty,oneof=AND OR" default:"AND"`
 }
 
 // ExecutionOutput 执行输出设置
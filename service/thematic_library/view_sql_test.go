@@ -6,6 +6,7 @@
 package thematic_library
 
 import (
+	"context"
 	"datahub-service/service/models"
 	"fmt"
 	"testing"
@@ -30,7 +31,7 @@ func TestCreateInterfaceWithViewSQL(t *testing.T) {
 		AccessLevel:     "internal",
 		UpdateFrequency: "daily",
 	}
-	err := service.CreateThematicLibrary(library)
+	err := service.CreateThematicLibrary(context.Background(), library)
 	require.NoError(t, err)
 
 	// 先创建基础表
@@ -100,7 +101,7 @@ func TestUpdateInterfaceWithViewSQL(t *testing.T) {
 		AccessLevel:     "internal",
 		UpdateFrequency: "daily",
 	}
-	err := service.CreateThematicLibrary(library)
+	err := service.CreateThematicLibrary(context.Background(), library)
 	require.NoError(t, err)
 
 	// 创建基础表
@@ -178,7 +179,7 @@ func TestListInterfacesIncludesViewSQL(t *testing.T) {
 		AccessLevel:     "internal",
 		UpdateFrequency: "daily",
 	}
-	err := service.CreateThematicLibrary(library)
+	err := service.CreateThematicLibrary(context.Background(), library)
 	require.NoError(t, err)
 
 	// 创建基础表
@@ -213,7 +214,7 @@ func TestListInterfacesIncludesViewSQL(t *testing.T) {
 	require.NoError(t, err)
 
 	// 获取接口列表
-	interfaces, total, err := service.GetThematicInterfaceList(1, 10, library.ID, "view", "", "")
+	interfaces, total, err := service.GetThematicInterfaceList(context.Background(), 1, 10, library.ID, "view", "", "")
 	assert.NoError(t, err)
 	assert.GreaterOrEqual(t, total, int64(1), "应该至少有一个view接口")
 	assert.GreaterOrEqual(t, len(interfaces), 1, "列表应该至少有一个接口")
@@ -247,7 +248,7 @@ func TestUpdateViewSQLMultipleTimes(t *testing.T) {
 		AccessLevel:     "internal",
 		UpdateFrequency: "daily",
 	}
-	err := service.CreateThematicLibrary(library)
+	err := service.CreateThematicLibrary(context.Background(), library)
 	require.NoError(t, err)
 
 	baseTable := &models.ThematicInterface{
@@ -0,0 +1,98 @@
+/*
+ * @module service/tracing/gorm_callback
+ * @description 数据库操作追踪，通过GORM回调为携带链路上下文的查询和写入创建Span
+ * @architecture 分层架构 - 基础设施层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow GORM操作前 -> 上下文中有父Span时创建db Span -> 操作后记录表名、SQL、影响行数和错误 -> 结束Span
+ * @rules 只追踪通过WithContext传入链路上下文的操作；记录未替换参数的SQL，不记录参数值；记录不存在不视为错误
+ * @dependencies gorm.io/gorm
+ * @refs service/tracing/tracing.go, service/init.go
+ */
+
+package tracing
+
+import (
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+const (
+	gormSpanKey       = "tracing:span"
+	maxStatementBytes = 2048
+)
+
+// RegisterGormCallbacks 在数据库连接上注册追踪回调
+func RegisterGormCallbacks(db *gorm.DB) error {
+	callbacks := db.Callback()
+	registrations := []struct {
+		name   string
+		before func(string, func(*gorm.DB)) error
+		after  func(string, func(*gorm.DB)) error
+	}{
+		{"create", callbacks.Create().Before("gorm:create").Register, callbacks.Create().After("gorm:create").Register},
+		{"query", callbacks.Query().Before("gorm:query").Register, callbacks.Query().After("gorm:query").Register},
+		{"update", callbacks.Update().Before("gorm:update").Register, callbacks.Update().After("gorm:update").Register},
+		{"delete", callbacks.Delete().Before("gorm:delete").Register, callbacks.Delete().After("gorm:delete").Register},
+		{"row", callbacks.Row().Before("gorm:row").Register, callbacks.Row().After("gorm:row").Register},
+		{"raw", callbacks.Raw().Before("gorm:raw").Register, callbacks.Raw().After("gorm:raw").Register},
+	}
+
+	for _, reg := range registrations {
+		if err := reg.before("tracing:before_"+reg.name, startGormSpan(reg.name)); err != nil {
+			return err
+		}
+		if err := reg.after("tracing:after_"+reg.name, endGormSpan); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// startGormSpan 操作前创建Span
+func startGormSpan(operation string) func(*gorm.DB) {
+	return func(tx *gorm.DB) {
+		ctx := tx.Statement.Context
+		if ctx == nil {
+			return
+		}
+		if _, ok := parentSpanContext(ctx); !ok {
+			return
+		}
+
+		_, span := StartKind(ctx, "db."+operation, SpanKindClient)
+		if span == nil {
+			return
+		}
+		span.SetAttribute("db.system", tx.Dialector.Name())
+		span.SetAttribute("db.operation", operation)
+		tx.InstanceSet(gormSpanKey, span)
+	}
+}
+
+// endGormSpan 操作后记录结果并结束Span
+func endGormSpan(tx *gorm.DB) {
+	value, ok := tx.InstanceGet(gormSpanKey)
+	if !ok {
+		return
+	}
+	span, ok := value.(*Span)
+	if !ok {
+		return
+	}
+
+	if tx.Statement.Table != "" {
+		span.SetAttribute("db.sql.table", tx.Statement.Table)
+	}
+	statement := tx.Statement.SQL.String()
+	if len(statement) > maxStatementBytes {
+		statement = statement[:maxStatementBytes]
+	}
+	span.SetAttribute("db.statement", statement)
+	span.SetAttribute("db.rows_affected", tx.Statement.RowsAffected)
+
+	if tx.Error != nil && !errors.Is(tx.Error, gorm.ErrRecordNotFound) {
+		span.RecordError(tx.Error)
+	}
+	span.End()
+}
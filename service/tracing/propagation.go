@@ -0,0 +1,66 @@
+/*
+ * @module service/tracing/propagation
+ * @description W3C Trace Context传播，经HTTP头和Dapr消息传递traceparent，使控制面、Dapr sidecar和worker的Span归入同一条链路
+ * @architecture 分层架构 - 基础设施层
+ * @documentReference https://www.w3.org/TR/trace-context/
+ * @stateFlow 入站请求/执行命令 -> 解析traceparent -> 作为远端父Span写入上下文；出站请求 -> 当前Span -> 写入traceparent
+ * @rules 使用OpenTelemetry标准的TraceContext和Baggage传播器，未启用追踪时同样透传远端链路；格式错误的traceparent被忽略
+ * @dependencies go.opentelemetry.io/otel/propagation
+ * @refs service/tracing/tracing.go, service/execution/dispatcher.go
+ */
+
+package tracing
+
+import (
+	"context"
+	"net/http"
+
+	"go.opentelemetry.io/otel/propagation"
+)
+
+const (
+	// TraceParentHeader W3C traceparent请求头，Dapr使用相同的头传播链路
+	TraceParentHeader = "traceparent"
+	// TraceStateHeader W3C tracestate请求头
+	TraceStateHeader = "tracestate"
+)
+
+// propagator 标准传播器，Init时同时注册为OpenTelemetry全局传播器
+var propagator = propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{})
+
+// Extract 从请求头解析远端父Span写入上下文
+func Extract(ctx context.Context, header http.Header) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return propagator.Extract(ctx, propagation.HeaderCarrier(header))
+}
+
+// Inject 把当前Span写入请求头
+func Inject(ctx context.Context, header http.Header) {
+	if ctx == nil {
+		return
+	}
+	propagator.Inject(ctx, propagation.HeaderCarrier(header))
+}
+
+// TraceParent 当前Span的traceparent值，无链路时返回空字符串
+func TraceParent(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	carrier := propagation.MapCarrier{}
+	propagator.Inject(ctx, carrier)
+	return carrier.Get(TraceParentHeader)
+}
+
+// ContextWithTraceParent 解析traceparent作为远端父Span写入上下文，值为空或格式错误时返回原上下文
+func ContextWithTraceParent(ctx context.Context, traceParent string) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if traceParent == "" {
+		return ctx
+	}
+	return propagator.Extract(ctx, propagation.MapCarrier{TraceParentHeader: traceParent})
+}
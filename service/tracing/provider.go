@@ -0,0 +1,136 @@
+/*
+ * @module service/tracing/provider
+ * @description 链路追踪初始化，创建OpenTelemetry TracerProvider并通过OTLP/HTTP批量导出Span到OpenTelemetry Collector、Jaeger或Tempo
+ * @architecture 分层架构 - 基础设施层
+ * @documentReference https://opentelemetry.io/docs/specs/otel/configuration/sdk-environment-variables/
+ * @stateFlow Init读取OTEL_*环境变量 -> 创建otlptracehttp导出器和TracerProvider -> 注册全局Provider和传播器 -> Shutdown导出剩余Span
+ * @rules 通过OTEL_EXPORTER_OTLP_ENDPOINT或OTEL_EXPORTER_OTLP_TRACES_ENDPOINT启用，未配置时不创建Span；
+ *        导出端点、请求头、超时和采样器由SDK按OTEL_*环境变量解析，只设置OTEL_TRACES_SAMPLER_ARG时按父Span继承的比例采样；
+ *        导出失败只由SDK记录，不影响业务
+ * @dependencies go.opentelemetry.io/otel/sdk/trace, go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp
+ * @refs service/tracing/tracing.go, service/init.go
+ */
+
+package tracing
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"strconv"
+	"sync/atomic"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.25.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	defaultServiceName  = "datahub-service"
+	instrumentationName = "datahub-service/service/tracing"
+)
+
+var globalProvider atomic.Pointer[sdktrace.TracerProvider]
+
+// currentTracer 当前生效的Tracer，未启用时返回nil
+func currentTracer() trace.Tracer {
+	tp := globalProvider.Load()
+	if tp == nil {
+		return nil
+	}
+	return tp.Tracer(instrumentationName)
+}
+
+// Enabled 是否已启用链路追踪
+func Enabled() bool {
+	return globalProvider.Load() != nil
+}
+
+// Init 读取OTEL_*环境变量初始化链路追踪，未配置导出端点时不启用
+func Init() {
+	if os.Getenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT") == "" && os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT") == "" {
+		slog.Info("未配置OTLP导出端点，链路追踪未启用")
+		return
+	}
+
+	ctx := context.Background()
+	exporter, err := otlptracehttp.New(ctx)
+	if err != nil {
+		slog.Error("创建OTLP导出器失败，链路追踪未启用", "error", err)
+		return
+	}
+
+	// 默认服务名在前，OTEL_SERVICE_NAME和OTEL_RESOURCE_ATTRIBUTES可覆盖
+	res, err := resource.New(ctx,
+		resource.WithAttributes(semconv.ServiceName(defaultServiceName)),
+		resource.WithFromEnv(),
+		resource.WithTelemetrySDK(),
+	)
+	if err != nil {
+		slog.Warn("解析链路追踪资源属性失败", "error", err)
+	}
+
+	options := []sdktrace.TracerProviderOption{
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+	}
+	if sampler, ok := ratioSamplerFromEnv(); ok {
+		options = append(options, sdktrace.WithSampler(sampler))
+	}
+	setProvider(sdktrace.NewTracerProvider(options...))
+	otel.SetTextMapPropagator(propagator)
+
+	slog.Info("链路追踪已启用", "service_name", serviceName(res))
+}
+
+// ratioSamplerFromEnv 未设置OTEL_TRACES_SAMPLER而只设置OTEL_TRACES_SAMPLER_ARG时，
+// 按比例采样根Span、子Span继承父Span的采样结果；设置了采样器时交给SDK解析
+func ratioSamplerFromEnv() (sdktrace.Sampler, bool) {
+	if os.Getenv("OTEL_TRACES_SAMPLER") != "" {
+		return nil, false
+	}
+	value := os.Getenv("OTEL_TRACES_SAMPLER_ARG")
+	if value == "" {
+		return nil, false
+	}
+	ratio, err := strconv.ParseFloat(value, 64)
+	if err != nil || ratio < 0 || ratio > 1 {
+		slog.Warn("OTEL_TRACES_SAMPLER_ARG无效，按全部采样处理", "value", value)
+		return nil, false
+	}
+	return sdktrace.ParentBased(sdktrace.TraceIDRatioBased(ratio)), true
+}
+
+// serviceName 资源中的服务名，用于启动日志
+func serviceName(res *resource.Resource) string {
+	if res != nil {
+		if value, ok := res.Set().Value(semconv.ServiceNameKey); ok {
+			return value.AsString()
+		}
+	}
+	return defaultServiceName
+}
+
+// setProvider 替换生效的TracerProvider并注册为全局Provider，已有的Provider会被关闭
+func setProvider(tp *sdktrace.TracerProvider) {
+	otel.SetTracerProvider(tp)
+	if old := globalProvider.Swap(tp); old != nil {
+		if err := old.Shutdown(context.Background()); err != nil {
+			slog.Warn("关闭链路追踪失败", "error", err)
+		}
+	}
+}
+
+// Shutdown 停止链路追踪并导出剩余的Span
+func Shutdown(ctx context.Context) {
+	tp := globalProvider.Swap(nil)
+	if tp == nil {
+		return
+	}
+	if err := tp.Shutdown(ctx); err != nil {
+		slog.Warn("关闭链路追踪失败", "error", err)
+	}
+}
@@ -0,0 +1,144 @@
+/*
+ * @module service/tracing/tracing
+ * @description 链路追踪，基于OpenTelemetry SDK按控制器 -> 服务 -> 执行器 -> 数据源的调用链记录耗时和错误
+ * @architecture 分层架构 - 基础设施层
+ * @documentReference https://opentelemetry.io/docs/languages/go/
+ * @stateFlow Start创建Span(继承上下文中的父Span或远端父Span) -> 设置属性/记录错误 -> End结束并交给SDK的批量处理器导出
+ * @rules 未启用追踪时不创建Span，所有方法对nil Span安全；采样由SDK的采样器决定，子Span继承父Span的采样结果
+ * @dependencies go.opentelemetry.io/otel/trace, go.opentelemetry.io/otel/attribute
+ * @refs service/tracing/propagation.go, service/tracing/provider.go, api/middleware/tracing.go
+ */
+
+package tracing
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// SpanKind Span类型
+type SpanKind = trace.SpanKind
+
+const (
+	SpanKindInternal = trace.SpanKindInternal
+	SpanKindServer   = trace.SpanKindServer
+	SpanKindClient   = trace.SpanKindClient
+	SpanKindProducer = trace.SpanKindProducer
+	SpanKindConsumer = trace.SpanKindConsumer
+)
+
+// Span 一次操作的追踪记录，包装OpenTelemetry Span，nil表示未启用追踪
+type Span struct {
+	span trace.Span
+}
+
+// Start 创建内部Span
+func Start(ctx context.Context, name string) (context.Context, *Span) {
+	return StartKind(ctx, name, SpanKindInternal)
+}
+
+// StartKind 创建指定类型的Span，未启用追踪时返回原上下文和nil Span
+func StartKind(ctx context.Context, name string, kind SpanKind) (context.Context, *Span) {
+	t := currentTracer()
+	if t == nil {
+		return ctx, nil
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	ctx, span := t.Start(ctx, name, trace.WithSpanKind(kind))
+	return ctx, &Span{span: span}
+}
+
+// parentSpanContext 获取父Span标识，本地Span或远端传入的标识
+func parentSpanContext(ctx context.Context) (trace.SpanContext, bool) {
+	sc := trace.SpanContextFromContext(ctx)
+	return sc, sc.IsValid()
+}
+
+// SpanContext 返回Span标识
+func (s *Span) SpanContext() trace.SpanContext {
+	if s == nil {
+		return trace.SpanContext{}
+	}
+	return s.span.SpanContext()
+}
+
+// SetName 修改Span名称，用于路由匹配完成后更新服务端Span名
+func (s *Span) SetName(name string) {
+	if s == nil {
+		return
+	}
+	s.span.SetName(name)
+}
+
+// SetAttribute 设置属性，值支持字符串、整数、浮点数和布尔值，其余类型按字符串记录
+func (s *Span) SetAttribute(key string, value interface{}) {
+	if s == nil {
+		return
+	}
+	s.span.SetAttributes(keyValue(key, value))
+}
+
+// RecordError 记录错误并把Span状态置为错误
+func (s *Span) RecordError(err error) {
+	if s == nil || err == nil {
+		return
+	}
+	s.span.RecordError(err)
+	s.span.SetStatus(codes.Error, err.Error())
+}
+
+// SetError 以文本把Span状态置为错误，用于非error类型的失败结果
+func (s *Span) SetError(format string, args ...interface{}) {
+	if s == nil {
+		return
+	}
+	s.span.SetStatus(codes.Error, fmt.Sprintf(format, args...))
+}
+
+// End 结束Span，重复调用只生效一次
+func (s *Span) End() {
+	if s == nil {
+		return
+	}
+	s.span.End()
+}
+
+// TraceIDFromContext 获取上下文中的链路ID，无链路时返回空字符串
+func TraceIDFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	if sc, ok := parentSpanContext(ctx); ok {
+		return sc.TraceID().String()
+	}
+	return ""
+}
+
+// keyValue 把属性值转换为OpenTelemetry属性
+func keyValue(key string, value interface{}) attribute.KeyValue {
+	switch v := value.(type) {
+	case string:
+		return attribute.String(key, v)
+	case bool:
+		return attribute.Bool(key, v)
+	case int:
+		return attribute.Int(key, v)
+	case int32:
+		return attribute.Int64(key, int64(v))
+	case int64:
+		return attribute.Int64(key, v)
+	case float32:
+		return attribute.Float64(key, float64(v))
+	case float64:
+		return attribute.Float64(key, v)
+	default:
+		return attribute.String(key, fmt.Sprint(v))
+	}
+}
@@ -0,0 +1,244 @@
+/*
+ * @module service/tracing/tracing_test
+ * @description 链路追踪测试，覆盖traceparent传播、父子Span、采样、OTLP导出、出站请求和数据库操作追踪
+ * @architecture 测试层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow 使用SpanRecorder替换TracerProvider -> 创建Span -> 验证记录的Span；Init测试启动本地OTLP接收端验证导出请求
+ * @rules 使用httptest、tracetest和内存sqlite，不依赖外部服务
+ * @dependencies go.opentelemetry.io/otel/sdk/trace/tracetest, net/http/httptest, gorm.io/driver/sqlite, stretchr/testify
+ * @refs service/tracing/tracing.go, service/tracing/provider.go
+ */
+
+package tracing
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// startRecorder 以指定采样器启用追踪，返回记录结束Span的SpanRecorder
+func startRecorder(t *testing.T, sampler sdktrace.Sampler) *tracetest.SpanRecorder {
+	t.Helper()
+	recorder := tracetest.NewSpanRecorder()
+	setProvider(sdktrace.NewTracerProvider(sdktrace.WithSampler(sampler), sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() { Shutdown(context.Background()) })
+	return recorder
+}
+
+func byName(recorder *tracetest.SpanRecorder, name string) (sdktrace.ReadOnlySpan, bool) {
+	for _, span := range recorder.Ended() {
+		if span.Name() == name {
+			return span, true
+		}
+	}
+	return nil, false
+}
+
+func attributeValue(span sdktrace.ReadOnlySpan, key string) (attribute.Value, bool) {
+	for _, kv := range span.Attributes() {
+		if string(kv.Key) == key {
+			return kv.Value, true
+		}
+	}
+	return attribute.Value{}, false
+}
+
+func TestDisabledTracing(t *testing.T) {
+	ctx := context.Background()
+	newCtx, span := Start(ctx, "noop")
+	assert.Nil(t, span)
+	assert.Equal(t, ctx, newCtx)
+
+	// nil Span的方法均可安全调用
+	span.SetAttribute("key", "value")
+	span.RecordError(errors.New("失败"))
+	span.End()
+	assert.Equal(t, "", TraceParent(newCtx))
+}
+
+func TestTraceParentPropagation(t *testing.T) {
+	const traceParent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+	header := http.Header{}
+	header.Set(TraceParentHeader, traceParent)
+	header.Set(TraceStateHeader, "vendor=value")
+	ctx := Extract(context.Background(), header)
+
+	assert.Equal(t, traceParent, TraceParent(ctx))
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", TraceIDFromContext(ctx))
+
+	out := http.Header{}
+	Inject(ctx, out)
+	assert.Equal(t, traceParent, out.Get(TraceParentHeader))
+	assert.Equal(t, "vendor=value", out.Get(TraceStateHeader))
+
+	for _, invalid := range []string{"", "ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", "00-00000000000000000000000000000000-00f067aa0ba902b7-01", "00-xyz-00f067aa0ba902b7-01"} {
+		assert.Equal(t, "", TraceParent(ContextWithTraceParent(context.Background(), invalid)), invalid)
+	}
+}
+
+func TestSpanParentAndStatus(t *testing.T) {
+	recorder := startRecorder(t, sdktrace.AlwaysSample())
+
+	remote := ContextWithTraceParent(context.Background(), "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	ctx, parent := StartKind(remote, "parent", SpanKindServer)
+	parent.SetAttribute("http.status_code", 200)
+	_, child := Start(ctx, "child")
+	child.RecordError(errors.New("写入失败"))
+	child.End()
+	child.End()
+	parent.End()
+
+	parentSpan, ok := byName(recorder, "parent")
+	require.True(t, ok)
+	childSpan, ok := byName(recorder, "child")
+	require.True(t, ok)
+
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", parentSpan.SpanContext().TraceID().String())
+	assert.Equal(t, "00f067aa0ba902b7", parentSpan.Parent().SpanID().String())
+	assert.Equal(t, trace.SpanKindServer, parentSpan.SpanKind())
+	assert.Equal(t, codes.Unset, parentSpan.Status().Code)
+	value, ok := attributeValue(parentSpan, "http.status_code")
+	require.True(t, ok)
+	assert.Equal(t, int64(200), value.AsInt64())
+
+	assert.Equal(t, parentSpan.SpanContext().TraceID(), childSpan.SpanContext().TraceID())
+	assert.Equal(t, parentSpan.SpanContext().SpanID(), childSpan.Parent().SpanID())
+	assert.Equal(t, codes.Error, childSpan.Status().Code)
+	assert.Equal(t, "写入失败", childSpan.Status().Description)
+	assert.Len(t, childSpan.Events(), 1, "错误作为exception事件记录")
+	assert.Len(t, recorder.Ended(), 2)
+}
+
+func TestUnsampledSpansNotRecorded(t *testing.T) {
+	recorder := startRecorder(t, sdktrace.ParentBased(sdktrace.TraceIDRatioBased(0)))
+
+	ctx, span := Start(context.Background(), "root")
+	require.NotNil(t, span)
+	assert.False(t, span.SpanContext().IsSampled())
+	_, child := Start(ctx, "child")
+	assert.False(t, child.SpanContext().IsSampled())
+	child.End()
+	span.End()
+
+	assert.Empty(t, recorder.Ended())
+}
+
+func TestInitExportsOTLP(t *testing.T) {
+	var requests atomic.Int32
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost && r.URL.Path == "/v1/traces" {
+			requests.Add(1)
+		}
+	}))
+	defer receiver.Close()
+
+	t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", receiver.URL)
+	t.Setenv("OTEL_SERVICE_NAME", "datahub-test")
+	Init()
+	require.True(t, Enabled())
+
+	_, span := Start(context.Background(), "exported")
+	require.NotNil(t, span)
+	assert.True(t, span.SpanContext().IsSampled())
+	span.End()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	Shutdown(ctx)
+	assert.False(t, Enabled())
+	assert.Equal(t, int32(1), requests.Load())
+}
+
+func TestTransport(t *testing.T) {
+	recorder := startRecorder(t, sdktrace.AlwaysSample())
+
+	var received string
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r.Header.Get(TraceParentHeader)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer upstream.Close()
+
+	client := &http.Client{Transport: NewTransport(nil)}
+
+	// 无父Span时不创建客户端Span
+	resp, err := client.Get(upstream.URL + "/data?token=secret")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, "", received)
+
+	ctx, parent := Start(context.Background(), "sync")
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, upstream.URL+"/data?token=secret", nil)
+	require.NoError(t, err)
+	resp, err = client.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	parent.End()
+
+	span, ok := byName(recorder, "HTTP GET")
+	require.True(t, ok)
+	sc := span.SpanContext()
+	assert.Equal(t, "00-"+sc.TraceID().String()+"-"+sc.SpanID().String()+"-01", received)
+	assert.Equal(t, trace.SpanKindClient, span.SpanKind())
+	assert.Equal(t, codes.Error, span.Status().Code)
+	value, ok := attributeValue(span, "http.url")
+	require.True(t, ok)
+	assert.Equal(t, upstream.URL+"/data", value.AsString())
+}
+
+func TestGormCallbacks(t *testing.T) {
+	recorder := startRecorder(t, sdktrace.AlwaysSample())
+
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+	require.NoError(t, RegisterGormCallbacks(db))
+
+	type traceItem struct {
+		ID   int
+		Name string
+	}
+	require.NoError(t, db.AutoMigrate(&traceItem{}))
+
+	// 无链路上下文的操作不追踪
+	require.NoError(t, db.Create(&traceItem{ID: 1, Name: "a"}).Error)
+
+	ctx, parent := Start(context.Background(), "service")
+	require.NoError(t, db.WithContext(ctx).Create(&traceItem{ID: 2, Name: "b"}).Error)
+	var item traceItem
+	assert.ErrorIs(t, db.WithContext(ctx).First(&item, "id = ?", 99).Error, gorm.ErrRecordNotFound)
+	parent.End()
+
+	createSpan, ok := byName(recorder, "db.create")
+	require.True(t, ok)
+	assert.Equal(t, parent.SpanContext().SpanID(), createSpan.Parent().SpanID())
+	value, ok := attributeValue(createSpan, "db.sql.table")
+	require.True(t, ok)
+	assert.Equal(t, "trace_items", value.AsString())
+	value, ok = attributeValue(createSpan, "db.rows_affected")
+	require.True(t, ok)
+	assert.Equal(t, int64(1), value.AsInt64())
+
+	querySpan, ok := byName(recorder, "db.query")
+	require.True(t, ok)
+	assert.Equal(t, codes.Unset, querySpan.Status().Code)
+	assert.Len(t, recorder.Ended(), 3)
+}
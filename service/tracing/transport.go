@@ -0,0 +1,62 @@
+/*
+ * @module service/tracing/transport
+ * @description 出站HTTP调用追踪，为数据源上游API调用创建客户端Span并传播traceparent
+ * @architecture 分层架构 - 基础设施层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow 出站请求 -> 创建客户端Span -> 写入traceparent -> 发送 -> 记录状态码和错误
+ * @rules 上下文中没有父Span时不创建根Span，避免心跳、健康检查等后台调用产生大量孤立链路；http.url不含查询参数，防止记录凭证
+ * @dependencies net/http
+ * @refs service/datasource/http_auth.go, service/datasource/http_no_auth.go
+ */
+
+package tracing
+
+import (
+	"net/http"
+)
+
+// Transport 追踪出站请求的http.RoundTripper
+type Transport struct {
+	base http.RoundTripper
+}
+
+// NewTransport 包装RoundTripper，base为nil时使用http.DefaultTransport
+func NewTransport(base http.RoundTripper) *Transport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &Transport{base: base}
+}
+
+// RoundTrip 发送请求并记录客户端Span
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if _, ok := parentSpanContext(req.Context()); !ok {
+		return t.base.RoundTrip(req)
+	}
+
+	ctx, span := StartKind(req.Context(), "HTTP "+req.Method, SpanKindClient)
+	if span == nil {
+		return t.base.RoundTrip(req)
+	}
+	defer span.End()
+
+	span.SetAttribute("http.method", req.Method)
+	span.SetAttribute("http.url", req.URL.Scheme+"://"+req.URL.Host+req.URL.Path)
+	span.SetAttribute("net.peer.name", req.URL.Hostname())
+
+	// RoundTripper不能修改原请求，克隆后写入链路头
+	outReq := req.Clone(ctx)
+	Inject(ctx, outReq.Header)
+
+	resp, err := t.base.RoundTrip(outReq)
+	if err != nil {
+		span.RecordError(err)
+		return resp, err
+	}
+
+	span.SetAttribute("http.status_code", resp.StatusCode)
+	if resp.StatusCode >= http.StatusInternalServerError {
+		span.SetError("上游返回状态码%d", resp.StatusCode)
+	}
+	return resp, nil
+}
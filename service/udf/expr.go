@@ -0,0 +1,946 @@
+/*
+ * @module service/udf/expr
+ * @description 自定义函数的沙箱表达式语言，把表达式编译为语法树后对字段值和参数求值
+ * @architecture 分层架构 - 业务服务层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow 表达式 -> 词法分析 -> 语法分析(检查变量和函数) -> 语法树 -> 按字段值和参数求值
+ * @rules
+ *   - 只支持字面量、变量、算术、比较、逻辑、三元运算和白名单函数，没有循环、赋值和外部访问，求值步数受语法树节点数限制
+ *   - 表达式长度不超过maxExpressionLength，语法树节点不超过maxNodes，字符串结果不超过maxStringLength
+ *   - 变量只能是value和声明的参数，函数只能是builtinFunctions中的函数，编译时检查参数个数
+ *   - 数值统一为float64；逻辑运算的操作数必须是布尔值；类型不匹配的比较和运算返回错误，==和!=在类型不同时分别为false和true
+ *   - 正则使用RE2语法，匹配时间与输入长度线性相关
+ * @dependencies regexp, strconv
+ * @refs service/udf/registry.go
+ */
+
+package udf
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	// maxExpressionLength 表达式最大长度（字节）
+	maxExpressionLength = 2000
+	// maxNodes 语法树最大节点数
+	maxNodes = 500
+	// maxStringLength 求值过程中字符串的最大长度（字节）
+	maxStringLength = 64 << 10
+	// maxPatternLength 正则表达式最大长度
+	maxPatternLength = 256
+	// maxCachedPatterns 缓存的已编译正则数量上限
+	maxCachedPatterns = 1000
+)
+
+// valueVariable 字段值在表达式中的变量名
+const valueVariable = "value"
+
+// ErrExpression 表达式不合法
+var ErrExpression = errors.New("表达式不合法")
+
+// program 编译后的表达式
+type program struct {
+	root node
+}
+
+// compile 编译表达式，variables为允许引用的变量
+func compile(expression string, variables map[string]bool) (*program, error) {
+	if strings.TrimSpace(expression) == "" {
+		return nil, fmt.Errorf("%w: 表达式为空", ErrExpression)
+	}
+	if len(expression) > maxExpressionLength {
+		return nil, fmt.Errorf("%w: 表达式超过%d个字符", ErrExpression, maxExpressionLength)
+	}
+	tokens, err := tokenize(expression)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{tokens: tokens, variables: variables}
+	root, err := p.parseTernary()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != tokenEOF {
+		return nil, fmt.Errorf("%w: 位置%d有多余的内容%q", ErrExpression, tok.pos, tok.text)
+	}
+	return &program{root: root}, nil
+}
+
+// run 对变量求值
+func (p *program) run(vars map[string]interface{}) (interface{}, error) {
+	return p.root.eval(vars)
+}
+
+// === 词法分析 ===
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenNumber
+	tokenString
+	tokenIdent
+	tokenOperator
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	num  float64
+	pos  int
+}
+
+// operators 运算符和分隔符，双字符的放在前面
+var operators = []string{"&&", "||", "==", "!=", "<=", ">=", "<", ">", "+", "-", "*", "/", "%", "!", "?", ":", "(", ")", ","}
+
+func tokenize(src string) ([]token, error) {
+	var tokens []token
+	i := 0
+	for i < len(src) {
+		r, size := utf8.DecodeRuneInString(src[i:])
+		switch {
+		case unicode.IsSpace(r):
+			i += size
+		case r >= '0' && r <= '9' || r == '.' && i+1 < len(src) && src[i+1] >= '0' && src[i+1] <= '9':
+			start := i
+			for i < len(src) && (src[i] >= '0' && src[i] <= '9' || src[i] == '.') {
+				i++
+			}
+			if i < len(src) && (src[i] == 'e' || src[i] == 'E') {
+				i++
+				if i < len(src) && (src[i] == '+' || src[i] == '-') {
+					i++
+				}
+				for i < len(src) && src[i] >= '0' && src[i] <= '9' {
+					i++
+				}
+			}
+			num, err := strconv.ParseFloat(src[start:i], 64)
+			if err != nil {
+				return nil, fmt.Errorf("%w: 位置%d的数字%q无效", ErrExpression, start, src[start:i])
+			}
+			tokens = append(tokens, token{kind: tokenNumber, text: src[start:i], num: num, pos: start})
+		case r == '\'' || r == '"':
+			text, next, err := scanString(src, i)
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, token{kind: tokenString, text: text, pos: i})
+			i = next
+		case r == '_' || unicode.IsLetter(r) && r < utf8.RuneSelf:
+			start := i
+			for i < len(src) && (src[i] == '_' || src[i] >= 'a' && src[i] <= 'z' || src[i] >= 'A' && src[i] <= 'Z' || src[i] >= '0' && src[i] <= '9') {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokenIdent, text: src[start:i], pos: start})
+		default:
+			matched := false
+			for _, op := range operators {
+				if strings.HasPrefix(src[i:], op) {
+					tokens = append(tokens, token{kind: tokenOperator, text: op, pos: i})
+					i += len(op)
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				return nil, fmt.Errorf("%w: 位置%d有不支持的字符%q", ErrExpression, i, string(r))
+			}
+		}
+	}
+	return append(tokens, token{kind: tokenEOF, pos: len(src)}), nil
+}
+
+// scanString 读取单引号或双引号字符串，支持\\、\'、\"、\n、\t转义
+func scanString(src string, start int) (string, int, error) {
+	quote := src[start]
+	var b strings.Builder
+	for i := start + 1; i < len(src); i++ {
+		c := src[i]
+		switch {
+		case c == quote:
+			return b.String(), i + 1, nil
+		case c == '\\' && i+1 < len(src):
+			i++
+			switch src[i] {
+			case 'n':
+				b.WriteByte('\n')
+			case 't':
+				b.WriteByte('\t')
+			default:
+				b.WriteByte(src[i])
+			}
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return "", 0, fmt.Errorf("%w: 位置%d的字符串没有结束", ErrExpression, start)
+}
+
+// === 语法分析 ===
+
+type parser struct {
+	tokens    []token
+	pos       int
+	nodes     int
+	variables map[string]bool
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokenEOF {
+		p.pos++
+	}
+	return tok
+}
+
+// accept 当前是指定运算符时前进
+func (p *parser) accept(op string) bool {
+	if tok := p.peek(); tok.kind == tokenOperator && tok.text == op {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *parser) expect(op string) error {
+	if p.accept(op) {
+		return nil
+	}
+	tok := p.peek()
+	return fmt.Errorf("%w: 位置%d应为%q", ErrExpression, tok.pos, op)
+}
+
+// count 统计节点数，超过上限时返回错误
+func (p *parser) count(n node) (node, error) {
+	p.nodes++
+	if p.nodes > maxNodes {
+		return nil, fmt.Errorf("%w: 表达式超过%d个节点", ErrExpression, maxNodes)
+	}
+	return n, nil
+}
+
+func (p *parser) parseTernary() (node, error) {
+	cond, err := p.parseBinary(0)
+	if err != nil {
+		return nil, err
+	}
+	if !p.accept("?") {
+		return cond, nil
+	}
+	then, err := p.parseTernary()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.expect(":"); err != nil {
+		return nil, err
+	}
+	otherwise, err := p.parseTernary()
+	if err != nil {
+		return nil, err
+	}
+	return p.count(&ternaryNode{cond: cond, then: then, otherwise: otherwise})
+}
+
+// binaryLevels 二元运算符按优先级从低到高
+var binaryLevels = [][]string{
+	{"||"},
+	{"&&"},
+	{"==", "!="},
+	{"<", "<=", ">", ">="},
+	{"+", "-"},
+	{"*", "/", "%"},
+}
+
+func (p *parser) parseBinary(level int) (node, error) {
+	if level == len(binaryLevels) {
+		return p.parseUnary()
+	}
+	left, err := p.parseBinary(level + 1)
+	if err != nil {
+		return nil, err
+	}
+	for {
+		tok := p.peek()
+		if tok.kind != tokenOperator || !slices.Contains(binaryLevels[level], tok.text) {
+			return left, nil
+		}
+		p.next()
+		right, err := p.parseBinary(level + 1)
+		if err != nil {
+			return nil, err
+		}
+		if left, err = p.count(&binaryNode{op: tok.text, left: left, right: right}); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (p *parser) parseUnary() (node, error) {
+	if tok := p.peek(); tok.kind == tokenOperator && (tok.text == "!" || tok.text == "-") {
+		p.next()
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return p.count(&unaryNode{op: tok.text, operand: operand})
+	}
+	return p.parsePrimary()
+}
+
+func (p *parser) parsePrimary() (node, error) {
+	tok := p.next()
+	switch tok.kind {
+	case tokenNumber:
+		return p.count(&literalNode{value: tok.num})
+	case tokenString:
+		return p.count(&literalNode{value: tok.text})
+	case tokenIdent:
+		switch tok.text {
+		case "true":
+			return p.count(&literalNode{value: true})
+		case "false":
+			return p.count(&literalNode{value: false})
+		case "null":
+			return p.count(&literalNode{value: nil})
+		}
+		if p.accept("(") {
+			return p.parseCall(tok)
+		}
+		if !p.variables[tok.text] {
+			return nil, fmt.Errorf("%w: 位置%d的变量%s未声明", ErrExpression, tok.pos, tok.text)
+		}
+		return p.count(&variableNode{name: tok.text})
+	case tokenOperator:
+		if tok.text == "(" {
+			inner, err := p.parseTernary()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expect(")"); err != nil {
+				return nil, err
+			}
+			return inner, nil
+		}
+	case tokenEOF:
+		return nil, fmt.Errorf("%w: 表达式不完整", ErrExpression)
+	}
+	return nil, fmt.Errorf("%w: 位置%d不应出现%q", ErrExpression, tok.pos, tok.text)
+}
+
+func (p *parser) parseCall(name token) (node, error) {
+	fn, ok := builtinFunctions[name.text]
+	if !ok {
+		return nil, fmt.Errorf("%w: 位置%d的函数%s不支持", ErrExpression, name.pos, name.text)
+	}
+	var args []node
+	if !p.accept(")") {
+		for {
+			arg, err := p.parseTernary()
+			if err != nil {
+				return nil, err
+			}
+			args = append(args, arg)
+			if p.accept(")") {
+				break
+			}
+			if err := p.expect(","); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if len(args) < fn.minArgs || len(args) > fn.maxArgs {
+		if fn.minArgs == fn.maxArgs {
+			return nil, fmt.Errorf("%w: 函数%s需要%d个参数", ErrExpression, name.text, fn.minArgs)
+		}
+		return nil, fmt.Errorf("%w: 函数%s需要%d到%d个参数", ErrExpression, name.text, fn.minArgs, fn.maxArgs)
+	}
+	return p.count(&callNode{name: name.text, fn: fn, args: args})
+}
+
+// === 求值 ===
+
+type node interface {
+	eval(vars map[string]interface{}) (interface{}, error)
+}
+
+type literalNode struct {
+	value interface{}
+}
+
+func (n *literalNode) eval(map[string]interface{}) (interface{}, error) {
+	return n.value, nil
+}
+
+type variableNode struct {
+	name string
+}
+
+func (n *variableNode) eval(vars map[string]interface{}) (interface{}, error) {
+	return normalizeValue(vars[n.name]), nil
+}
+
+type unaryNode struct {
+	op      string
+	operand node
+}
+
+func (n *unaryNode) eval(vars map[string]interface{}) (interface{}, error) {
+	v, err := n.operand.eval(vars)
+	if err != nil {
+		return nil, err
+	}
+	if n.op == "!" {
+		b, ok := v.(bool)
+		if !ok {
+			return nil, fmt.Errorf("!的操作数必须是布尔值，实际为%s", typeName(v))
+		}
+		return !b, nil
+	}
+	num, ok := v.(float64)
+	if !ok {
+		return nil, fmt.Errorf("-的操作数必须是数值，实际为%s", typeName(v))
+	}
+	return -num, nil
+}
+
+type binaryNode struct {
+	op          string
+	left, right node
+}
+
+func (n *binaryNode) eval(vars map[string]interface{}) (interface{}, error) {
+	left, err := n.left.eval(vars)
+	if err != nil {
+		return nil, err
+	}
+
+	// 逻辑运算短路求值
+	if n.op == "&&" || n.op == "||" {
+		l, ok := left.(bool)
+		if !ok {
+			return nil, fmt.Errorf("%s的操作数必须是布尔值，实际为%s", n.op, typeName(left))
+		}
+		if n.op == "&&" && !l || n.op == "||" && l {
+			return l, nil
+		}
+		right, err := n.right.eval(vars)
+		if err != nil {
+			return nil, err
+		}
+		r, ok := right.(bool)
+		if !ok {
+			return nil, fmt.Errorf("%s的操作数必须是布尔值，实际为%s", n.op, typeName(right))
+		}
+		return r, nil
+	}
+
+	right, err := n.right.eval(vars)
+	if err != nil {
+		return nil, err
+	}
+	switch n.op {
+	case "==":
+		return valuesEqual(left, right), nil
+	case "!=":
+		return !valuesEqual(left, right), nil
+	case "<", "<=", ">", ">=":
+		return compareValues(n.op, left, right)
+	case "+":
+		if ls, ok := left.(string); ok {
+			return concat(ls, toText(right))
+		}
+		if rs, ok := right.(string); ok {
+			return concat(toText(left), rs)
+		}
+	}
+
+	l, lok := left.(float64)
+	r, rok := right.(float64)
+	if !lok || !rok {
+		return nil, fmt.Errorf("%s的操作数必须是数值，实际为%s和%s", n.op, typeName(left), typeName(right))
+	}
+	switch n.op {
+	case "+":
+		return l + r, nil
+	case "-":
+		return l - r, nil
+	case "*":
+		return l * r, nil
+	case "/":
+		if r == 0 {
+			return nil, errors.New("除数为0")
+		}
+		return l / r, nil
+	default:
+		if r == 0 {
+			return nil, errors.New("除数为0")
+		}
+		return math.Mod(l, r), nil
+	}
+}
+
+type ternaryNode struct {
+	cond, then, otherwise node
+}
+
+func (n *ternaryNode) eval(vars map[string]interface{}) (interface{}, error) {
+	cond, err := n.cond.eval(vars)
+	if err != nil {
+		return nil, err
+	}
+	b, ok := cond.(bool)
+	if !ok {
+		return nil, fmt.Errorf("三元运算的条件必须是布尔值，实际为%s", typeName(cond))
+	}
+	if b {
+		return n.then.eval(vars)
+	}
+	return n.otherwise.eval(vars)
+}
+
+type callNode struct {
+	name string
+	fn   *builtinFunction
+	args []node
+}
+
+func (n *callNode) eval(vars map[string]interface{}) (interface{}, error) {
+	args := make([]interface{}, len(n.args))
+	for i, arg := range n.args {
+		v, err := arg.eval(vars)
+		if err != nil {
+			return nil, err
+		}
+		args[i] = v
+	}
+	result, err := n.fn.call(args)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", n.name, err)
+	}
+	return result, nil
+}
+
+func concat(a, b string) (interface{}, error) {
+	if len(a)+len(b) > maxStringLength {
+		return nil, fmt.Errorf("字符串超过%d字节", maxStringLength)
+	}
+	return a + b, nil
+}
+
+// valuesEqual 按值比较，类型不同时不相等
+func valuesEqual(a, b interface{}) bool {
+	switch av := a.(type) {
+	case nil:
+		return b == nil
+	case float64:
+		bv, ok := b.(float64)
+		return ok && av == bv
+	case string:
+		bv, ok := b.(string)
+		return ok && av == bv
+	case bool:
+		bv, ok := b.(bool)
+		return ok && av == bv
+	}
+	return false
+}
+
+func compareValues(op string, a, b interface{}) (interface{}, error) {
+	var cmp int
+	switch av := a.(type) {
+	case float64:
+		bv, ok := b.(float64)
+		if !ok {
+			return nil, fmt.Errorf("%s不能比较%s和%s", op, typeName(a), typeName(b))
+		}
+		switch {
+		case av < bv:
+			cmp = -1
+		case av > bv:
+			cmp = 1
+		}
+	case string:
+		bv, ok := b.(string)
+		if !ok {
+			return nil, fmt.Errorf("%s不能比较%s和%s", op, typeName(a), typeName(b))
+		}
+		cmp = strings.Compare(av, bv)
+	default:
+		return nil, fmt.Errorf("%s不能比较%s和%s", op, typeName(a), typeName(b))
+	}
+	switch op {
+	case "<":
+		return cmp < 0, nil
+	case "<=":
+		return cmp <= 0, nil
+	case ">":
+		return cmp > 0, nil
+	default:
+		return cmp >= 0, nil
+	}
+}
+
+// normalizeValue 把外部传入的值转换为表达式的值类型：nil、float64、string、bool
+func normalizeValue(v interface{}) interface{} {
+	switch val := v.(type) {
+	case nil, float64, string, bool:
+		return val
+	case int:
+		return float64(val)
+	case int8:
+		return float64(val)
+	case int16:
+		return float64(val)
+	case int32:
+		return float64(val)
+	case int64:
+		return float64(val)
+	case uint:
+		return float64(val)
+	case uint8:
+		return float64(val)
+	case uint16:
+		return float64(val)
+	case uint32:
+		return float64(val)
+	case uint64:
+		return float64(val)
+	case float32:
+		return float64(val)
+	case json.Number:
+		if num, err := val.Float64(); err == nil {
+			return num
+		}
+		return val.String()
+	case []byte:
+		return string(val)
+	case time.Time:
+		return val.Format(time.RFC3339)
+	case *time.Time:
+		if val == nil {
+			return nil
+		}
+		return val.Format(time.RFC3339)
+	default:
+		return fmt.Sprint(val)
+	}
+}
+
+// typeName 值在表达式中的类型名
+func typeName(v interface{}) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case float64:
+		return "number"
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	}
+	return fmt.Sprintf("%T", v)
+}
+
+// toText 值的文本形式，null为空字符串
+func toText(v interface{}) string {
+	switch val := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return val
+	case float64:
+		return strconv.FormatFloat(val, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(val)
+	}
+	return fmt.Sprint(v)
+}
+
+// === 内置函数 ===
+
+type builtinFunction struct {
+	minArgs, maxArgs int
+	call             func(args []interface{}) (interface{}, error)
+}
+
+// builtinFunctions 表达式可以调用的函数白名单
+var builtinFunctions = map[string]*builtinFunction{
+	"len": {1, 1, func(args []interface{}) (interface{}, error) {
+		if args[0] == nil {
+			return 0.0, nil
+		}
+		s, err := stringArg(args, 0)
+		if err != nil {
+			return nil, err
+		}
+		return float64(utf8.RuneCountInString(s)), nil
+	}},
+	"lower": {1, 1, stringFunc(strings.ToLower)},
+	"upper": {1, 1, stringFunc(strings.ToUpper)},
+	"trim":  {1, 1, stringFunc(strings.TrimSpace)},
+	"contains": {2, 2, func(args []interface{}) (interface{}, error) {
+		return stringPredicate(args, strings.Contains)
+	}},
+	"starts_with": {2, 2, func(args []interface{}) (interface{}, error) {
+		return stringPredicate(args, strings.HasPrefix)
+	}},
+	"ends_with": {2, 2, func(args []interface{}) (interface{}, error) {
+		return stringPredicate(args, strings.HasSuffix)
+	}},
+	"replace": {3, 3, func(args []interface{}) (interface{}, error) {
+		if args[0] == nil {
+			return nil, nil
+		}
+		s, err := stringArg(args, 0)
+		if err != nil {
+			return nil, err
+		}
+		old, err := stringArg(args, 1)
+		if err != nil {
+			return nil, err
+		}
+		replacement, err := stringArg(args, 2)
+		if err != nil {
+			return nil, err
+		}
+		if old != "" && len(replacement) > len(old) && len(s)+strings.Count(s, old)*(len(replacement)-len(old)) > maxStringLength {
+			return nil, fmt.Errorf("字符串超过%d字节", maxStringLength)
+		}
+		if old == "" && len(s)+(utf8.RuneCountInString(s)+1)*len(replacement) > maxStringLength {
+			return nil, fmt.Errorf("字符串超过%d字节", maxStringLength)
+		}
+		return strings.ReplaceAll(s, old, replacement), nil
+	}},
+	"substr": {2, 3, func(args []interface{}) (interface{}, error) {
+		if args[0] == nil {
+			return nil, nil
+		}
+		s, err := stringArg(args, 0)
+		if err != nil {
+			return nil, err
+		}
+		start, err := numberArg(args, 1)
+		if err != nil {
+			return nil, err
+		}
+		runes := []rune(s)
+		from := clampIndex(int(start), len(runes))
+		to := len(runes)
+		if len(args) == 3 {
+			length, err := numberArg(args, 2)
+			if err != nil {
+				return nil, err
+			}
+			to = clampIndex(from+int(length), len(runes))
+		}
+		if to < from {
+			return "", nil
+		}
+		return string(runes[from:to]), nil
+	}},
+	"matches": {2, 2, func(args []interface{}) (interface{}, error) {
+		if args[0] == nil {
+			return false, nil
+		}
+		s, err := stringArg(args, 0)
+		if err != nil {
+			return nil, err
+		}
+		pattern, err := stringArg(args, 1)
+		if err != nil {
+			return nil, err
+		}
+		re, err := compilePattern(pattern)
+		if err != nil {
+			return nil, err
+		}
+		return re.MatchString(s), nil
+	}},
+	"to_number": {1, 1, func(args []interface{}) (interface{}, error) {
+		switch v := args[0].(type) {
+		case nil, float64:
+			return v, nil
+		case bool:
+			if v {
+				return 1.0, nil
+			}
+			return 0.0, nil
+		case string:
+			num, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+			if err != nil {
+				return nil, fmt.Errorf("%q不是数值", v)
+			}
+			return num, nil
+		}
+		return nil, fmt.Errorf("不能转换%s", typeName(args[0]))
+	}},
+	"to_string": {1, 1, func(args []interface{}) (interface{}, error) {
+		if args[0] == nil {
+			return nil, nil
+		}
+		return toText(args[0]), nil
+	}},
+	"is_empty": {1, 1, func(args []interface{}) (interface{}, error) {
+		if args[0] == nil {
+			return true, nil
+		}
+		s, ok := args[0].(string)
+		return ok && strings.TrimSpace(s) == "", nil
+	}},
+	"abs": {1, 1, func(args []interface{}) (interface{}, error) {
+		num, err := numberArg(args, 0)
+		if err != nil {
+			return nil, err
+		}
+		return math.Abs(num), nil
+	}},
+	"round": {1, 2, func(args []interface{}) (interface{}, error) {
+		num, err := numberArg(args, 0)
+		if err != nil {
+			return nil, err
+		}
+		digits := 0.0
+		if len(args) == 2 {
+			if digits, err = numberArg(args, 1); err != nil {
+				return nil, err
+			}
+			if digits < 0 || digits > 10 {
+				return nil, errors.New("小数位数应在0到10之间")
+			}
+		}
+		scale := math.Pow(10, math.Trunc(digits))
+		return math.Round(num*scale) / scale, nil
+	}},
+	"min": {2, 10, func(args []interface{}) (interface{}, error) {
+		return extremum(args, func(a, b float64) bool { return a < b })
+	}},
+	"max": {2, 10, func(args []interface{}) (interface{}, error) {
+		return extremum(args, func(a, b float64) bool { return a > b })
+	}},
+	"coalesce": {1, 10, func(args []interface{}) (interface{}, error) {
+		for _, arg := range args {
+			if arg != nil {
+				return arg, nil
+			}
+		}
+		return nil, nil
+	}},
+}
+
+// stringFunc 字符串转换函数，null返回null
+func stringFunc(fn func(string) string) func(args []interface{}) (interface{}, error) {
+	return func(args []interface{}) (interface{}, error) {
+		if args[0] == nil {
+			return nil, nil
+		}
+		s, err := stringArg(args, 0)
+		if err != nil {
+			return nil, err
+		}
+		return fn(s), nil
+	}
+}
+
+// stringPredicate 字符串判断函数，null返回false
+func stringPredicate(args []interface{}, fn func(s, sub string) bool) (interface{}, error) {
+	if args[0] == nil {
+		return false, nil
+	}
+	s, err := stringArg(args, 0)
+	if err != nil {
+		return nil, err
+	}
+	sub, err := stringArg(args, 1)
+	if err != nil {
+		return nil, err
+	}
+	return fn(s, sub), nil
+}
+
+func stringArg(args []interface{}, i int) (string, error) {
+	s, ok := args[i].(string)
+	if !ok {
+		return "", fmt.Errorf("第%d个参数必须是字符串，实际为%s", i+1, typeName(args[i]))
+	}
+	return s, nil
+}
+
+func numberArg(args []interface{}, i int) (float64, error) {
+	num, ok := args[i].(float64)
+	if !ok {
+		return 0, fmt.Errorf("第%d个参数必须是数值，实际为%s", i+1, typeName(args[i]))
+	}
+	return num, nil
+}
+
+func extremum(args []interface{}, better func(a, b float64) bool) (interface{}, error) {
+	result, err := numberArg(args, 0)
+	if err != nil {
+		return nil, err
+	}
+	for i := 1; i < len(args); i++ {
+		num, err := numberArg(args, i)
+		if err != nil {
+			return nil, err
+		}
+		if better(num, result) {
+			result = num
+		}
+	}
+	return result, nil
+}
+
+func clampIndex(i, length int) int {
+	if i < 0 {
+		return 0
+	}
+	if i > length {
+		return length
+	}
+	return i
+}
+
+// patternCache 已编译的正则，超过上限后不再缓存
+var (
+	patternCache sync.Map
+	patternCount int64
+	patternMu    sync.Mutex
+)
+
+func compilePattern(pattern string) (*regexp.Regexp, error) {
+	if cached, ok := patternCache.Load(pattern); ok {
+		return cached.(*regexp.Regexp), nil
+	}
+	if len(pattern) > maxPatternLength {
+		return nil, fmt.Errorf("正则表达式超过%d个字符", maxPatternLength)
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("正则表达式无效: %w", err)
+	}
+	patternMu.Lock()
+	if patternCount < maxCachedPatterns {
+		if _, loaded := patternCache.LoadOrStore(pattern, re); !loaded {
+			patternCount++
+		}
+	}
+	patternMu.Unlock()
+	return re, nil
+}
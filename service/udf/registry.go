@@ -0,0 +1,790 @@
+/*
+ * @module service/udf/registry
+ * @description 自定义函数注册表，管理员登记带签名和示例的表达式函数，版本经其他管理员评审后供质量规则和清洗规则的逻辑按名称引用
+ * @architecture 分层架构 - 业务服务层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow 创建函数和第一个草稿版本 -> 修改草稿 -> 提交评审(编译并执行示例) -> 其他管理员审批(成为当前版本)/驳回(修改后回到草稿) -> 规则按名称引用当前版本或固定版本
+ * @rules
+ *   - 函数名称全局唯一，不按租户隔离；用途(quality/cleansing)创建后不能修改，质量函数必须返回布尔值
+ *   - 只有草稿和被驳回的版本可以修改，已提交评审和已审批的版本不可变；提交评审时至少有一个示例且全部通过
+ *   - 提交人不能审批自己提交的版本，审批时重新执行示例
+ *   - 规则逻辑通过{"udf": 名称, "udf_version": 版本号, "args": {...}}引用，版本号为空时使用当前版本；只能引用已审批的版本
+ *   - 已编译的固定版本常驻缓存，按名称解析的当前版本缓存currentCacheTTL，其他实例审批后最多延迟currentCacheTTL生效
+ *   - 仍被质量规则模板或清洗模板引用的函数不能删除，引用检查跨租户
+ * @dependencies datahub-service/service/models, datahub-service/service/eventlog, gorm.io/gorm
+ * @refs service/udf/expr.go, service/models/udf.go, service/governance/rule_engine.go, api/controllers/udf_controller.go
+ */
+
+package udf
+
+import (
+	"context"
+	"datahub-service/service/eventlog"
+	"datahub-service/service/models"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// currentCacheTTL 按名称解析的当前版本缓存时间
+const currentCacheTTL = 30 * time.Second
+
+// EventUDFReviewRequested 自定义函数版本提交评审事件
+const EventUDFReviewRequested = "udf_review_requested"
+
+// ObjectTypeUDF 自定义函数在应用事件中的对象类型
+const ObjectTypeUDF = "udf_definition"
+
+// 规则逻辑中引用自定义函数的键
+const (
+	LogicKeyUDF     = "udf"
+	LogicKeyVersion = "udf_version"
+	LogicKeyArgs    = "args"
+)
+
+var (
+	// ErrInvalidUDF 自定义函数或引用不合法
+	ErrInvalidUDF = errors.New("自定义函数不合法")
+	// ErrVersionState 版本当前状态不允许该操作
+	ErrVersionState = errors.New("自定义函数版本当前状态不允许该操作")
+	// ErrSelfApproval 提交人审批自己提交的版本
+	ErrSelfApproval = errors.New("提交人不能审批自己提交的版本")
+	// ErrExamplesFailed 示例未通过
+	ErrExamplesFailed = errors.New("自定义函数示例未通过")
+	// ErrUDFInUse 函数仍被规则模板引用
+	ErrUDFInUse = errors.New("自定义函数仍被规则模板引用")
+	// ErrRegistryUnavailable 注册表未初始化
+	ErrRegistryUnavailable = errors.New("自定义函数注册表未初始化")
+)
+
+// nameRegexp 函数名称和参数名称
+var nameRegexp = regexp.MustCompile(`^[a-z][a-z0-9_]{0,62}$`)
+
+// UDFRequest 创建自定义函数请求，同时创建第一个草稿版本
+type UDFRequest struct {
+	Name        string         `json:"name" validate:"required,max=63" example:"valid_plate_no"`
+	DisplayName string         `json:"display_name" validate:"required,max=255" example:"车牌号格式检查"`
+	Description string         `json:"description" validate:"max=1000"`
+	Kind        string         `json:"kind" validate:"required,oneof=quality cleansing" example:"quality"`
+	Language    string         `json:"language" validate:"omitempty,oneof=expression" example:"expression"` // 默认expression
+	Version     VersionRequest `json:"version"`
+}
+
+// UDFUpdateRequest 修改自定义函数的说明
+type UDFUpdateRequest struct {
+	DisplayName string `json:"display_name" validate:"required,max=255"`
+	Description string `json:"description" validate:"max=1000"`
+}
+
+// VersionRequest 创建或修改草稿版本请求
+type VersionRequest struct {
+	Expression string              `json:"expression" validate:"required,max=2000" example:"matches(value, '^[沪京][A-Z][0-9A-Z]{5}$')"`
+	InputType  string              `json:"input_type" validate:"omitempty,oneof=string number boolean any" example:"string"` // 默认any
+	Params     []models.UDFParam   `json:"params"`
+	ReturnType string              `json:"return_type" validate:"required,oneof=string number boolean any" example:"boolean"`
+	Examples   []models.UDFExample `json:"examples"`
+}
+
+// TestRequest 试运行请求
+type TestRequest struct {
+	Value interface{}            `json:"value"`
+	Args  map[string]interface{} `json:"args"`
+}
+
+// TestResult 试运行结果
+type TestResult struct {
+	Result   interface{}     `json:"result"`
+	Error    string          `json:"error,omitempty"`
+	Examples []ExampleResult `json:"examples"` // 版本示例的执行结果
+}
+
+// ExampleResult 一个示例的执行结果
+type ExampleResult struct {
+	Index  int         `json:"index"`
+	Passed bool        `json:"passed"`
+	Actual interface{} `json:"actual"`
+	Error  string      `json:"error,omitempty"`
+}
+
+// UDFDetail 自定义函数及其全部版本
+type UDFDetail struct {
+	*models.UDFDefinition
+	Versions []models.UDFVersion `json:"versions"`
+}
+
+// Reference 规则逻辑对自定义函数的引用
+type Reference struct {
+	Name    string
+	Version int // 0表示当前版本
+	Args    map[string]interface{}
+}
+
+// compiled 已编译的版本
+type compiled struct {
+	kind    string
+	version models.UDFVersion
+	program *program
+}
+
+// cacheEntry 解析缓存，expiresAt为零表示固定版本，不过期
+type cacheEntry struct {
+	compiled  *compiled
+	expiresAt time.Time
+}
+
+// Service 自定义函数注册表
+type Service struct {
+	db    *gorm.DB
+	cache sync.Map // "名称"或"名称@版本号" -> cacheEntry
+}
+
+// defaultService 全局注册表，供规则引擎执行规则时解析引用
+var defaultService atomic.Pointer[Service]
+
+// NewService 创建自定义函数注册表
+func NewService(db *gorm.DB) *Service {
+	return &Service{db: db}
+}
+
+// SetDefault 设置全局自定义函数注册表
+func SetDefault(s *Service) {
+	defaultService.Store(s)
+}
+
+// ParseReference 读取规则逻辑中的自定义函数引用，没有引用时返回nil
+func ParseReference(logic map[string]interface{}) (*Reference, error) {
+	raw, ok := logic[LogicKeyUDF]
+	if !ok || raw == nil {
+		return nil, nil
+	}
+	name, ok := raw.(string)
+	if !ok || name == "" {
+		return nil, fmt.Errorf("%w: %s必须是函数名称", ErrInvalidUDF, LogicKeyUDF)
+	}
+	ref := &Reference{Name: name}
+	if raw, ok := logic[LogicKeyVersion]; ok && raw != nil {
+		version, ok := normalizeValue(raw).(float64)
+		if !ok || version < 1 || version != float64(int(version)) {
+			return nil, fmt.Errorf("%w: %s必须是正整数", ErrInvalidUDF, LogicKeyVersion)
+		}
+		ref.Version = int(version)
+	}
+	if raw, ok := logic[LogicKeyArgs]; ok && raw != nil {
+		switch args := raw.(type) {
+		case map[string]interface{}:
+			ref.Args = args
+		case models.JSONB:
+			ref.Args = args
+		default:
+			return nil, fmt.Errorf("%w: %s必须是对象", ErrInvalidUDF, LogicKeyArgs)
+		}
+	}
+	return ref, nil
+}
+
+// Call 使用全局注册表调用规则引用的自定义函数
+func Call(ctx context.Context, ref *Reference, kind string, value interface{}) (interface{}, error) {
+	s := defaultService.Load()
+	if s == nil {
+		return nil, ErrRegistryUnavailable
+	}
+	return s.Call(ctx, ref, kind, value)
+}
+
+// ValidateLogic 使用全局注册表检查规则逻辑中的自定义函数引用，没有引用时直接通过
+func ValidateLogic(ctx context.Context, logic map[string]interface{}, kind string) error {
+	ref, err := ParseReference(logic)
+	if err != nil || ref == nil {
+		return err
+	}
+	s := defaultService.Load()
+	if s == nil {
+		return ErrRegistryUnavailable
+	}
+	return s.ValidateReference(ctx, ref, kind)
+}
+
+// === 函数和版本管理 ===
+
+// CreateUDF 创建自定义函数和第一个草稿版本
+func (s *Service) CreateUDF(ctx context.Context, req *UDFRequest, username string) (*UDFDetail, error) {
+	if !nameRegexp.MatchString(req.Name) {
+		return nil, fmt.Errorf("%w: 名称只能包含小写字母、数字和下划线，以字母开头", ErrInvalidUDF)
+	}
+	if err := validateVersion(req.Kind, &req.Version); err != nil {
+		return nil, err
+	}
+	var count int64
+	if err := s.db.WithContext(ctx).Model(&models.UDFDefinition{}).Where("name = ?", req.Name).Count(&count).Error; err != nil {
+		return nil, err
+	}
+	if count > 0 {
+		return nil, fmt.Errorf("%w: 名称%s已存在", ErrInvalidUDF, req.Name)
+	}
+
+	def := &models.UDFDefinition{
+		Name:        req.Name,
+		DisplayName: req.DisplayName,
+		Description: req.Description,
+		Kind:        req.Kind,
+		Language:    models.UDFLanguageExpression,
+		CreatedBy:   username,
+		UpdatedBy:   username,
+	}
+	version := newVersion(&req.Version, username)
+	version.Version = 1
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(def).Error; err != nil {
+			return err
+		}
+		version.UDFID = def.ID
+		return tx.Create(version).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &UDFDetail{UDFDefinition: def, Versions: []models.UDFVersion{*version}}, nil
+}
+
+// ListUDFs 分页获取自定义函数
+func (s *Service) ListUDFs(ctx context.Context, keyword, kind string, page, pageSize int) ([]models.UDFDefinition, int64, error) {
+	query := s.db.WithContext(ctx).Model(&models.UDFDefinition{})
+	if keyword != "" {
+		query = query.Where("name LIKE ? OR display_name LIKE ?", "%"+keyword+"%", "%"+keyword+"%")
+	}
+	if kind != "" {
+		query = query.Where("kind = ?", kind)
+	}
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	var defs []models.UDFDefinition
+	if err := query.Order("name").Offset((page - 1) * pageSize).Limit(pageSize).Find(&defs).Error; err != nil {
+		return nil, 0, err
+	}
+	return defs, total, nil
+}
+
+// GetUDF 获取自定义函数及其全部版本，版本按版本号倒序
+func (s *Service) GetUDF(ctx context.Context, id string) (*UDFDetail, error) {
+	var def models.UDFDefinition
+	if err := s.db.WithContext(ctx).First(&def, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	var versions []models.UDFVersion
+	if err := s.db.WithContext(ctx).Where("udf_id = ?", id).Order("version DESC").Find(&versions).Error; err != nil {
+		return nil, err
+	}
+	return &UDFDetail{UDFDefinition: &def, Versions: versions}, nil
+}
+
+// UpdateUDF 修改自定义函数的显示名称和说明
+func (s *Service) UpdateUDF(ctx context.Context, id string, req *UDFUpdateRequest, username string) (*models.UDFDefinition, error) {
+	var def models.UDFDefinition
+	if err := s.db.WithContext(ctx).First(&def, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	def.DisplayName = req.DisplayName
+	def.Description = req.Description
+	def.UpdatedBy = username
+	if err := s.db.WithContext(ctx).Model(&def).Select("display_name", "description", "updated_by", "updated_at").Updates(&def).Error; err != nil {
+		return nil, err
+	}
+	return &def, nil
+}
+
+// DeleteUDF 删除自定义函数及其全部版本，仍被规则模板引用时返回ErrUDFInUse
+func (s *Service) DeleteUDF(ctx context.Context, id string) error {
+	var def models.UDFDefinition
+	if err := s.db.WithContext(ctx).First(&def, "id = ?", id).Error; err != nil {
+		return err
+	}
+	refs, err := s.templateReferences(def.Name)
+	if err != nil {
+		return err
+	}
+	if len(refs) > 0 {
+		return fmt.Errorf("%w: %s", ErrUDFInUse, strings.Join(refs, "、"))
+	}
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&models.UDFVersion{}, "udf_id = ?", id).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.UDFDefinition{}, "id = ?", id).Error
+	})
+	if err != nil {
+		return err
+	}
+	s.invalidate(def.Name)
+	return nil
+}
+
+// CreateVersion 创建新的草稿版本，版本号为已有最大版本号加一
+func (s *Service) CreateVersion(ctx context.Context, id string, req *VersionRequest, username string) (*models.UDFVersion, error) {
+	var def models.UDFDefinition
+	if err := s.db.WithContext(ctx).First(&def, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	if err := validateVersion(def.Kind, req); err != nil {
+		return nil, err
+	}
+	version := newVersion(req, username)
+	version.UDFID = id
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var latest int
+		if err := tx.Model(&models.UDFVersion{}).Where("udf_id = ?", id).Select("COALESCE(MAX(version), 0)").Scan(&latest).Error; err != nil {
+			return err
+		}
+		version.Version = latest + 1
+		return tx.Create(version).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return version, nil
+}
+
+// UpdateVersion 修改草稿或被驳回的版本，被驳回的版本回到草稿
+func (s *Service) UpdateVersion(ctx context.Context, id string, number int, req *VersionRequest, username string) (*models.UDFVersion, error) {
+	def, version, err := s.getVersion(ctx, id, number)
+	if err != nil {
+		return nil, err
+	}
+	if version.Status != models.UDFVersionDraft && version.Status != models.UDFVersionRejected {
+		return nil, fmt.Errorf("%w: 只能修改草稿或被驳回的版本，当前为%s", ErrVersionState, version.Status)
+	}
+	if err := validateVersion(def.Kind, req); err != nil {
+		return nil, err
+	}
+	updated := newVersion(req, username)
+	version.Expression = updated.Expression
+	version.InputType = updated.InputType
+	version.Params = updated.Params
+	version.ReturnType = updated.ReturnType
+	version.Examples = updated.Examples
+	version.Status = models.UDFVersionDraft
+	version.UpdatedBy = username
+	if err := s.db.WithContext(ctx).Model(version).
+		Select("expression", "input_type", "params", "return_type", "examples", "status", "updated_by", "updated_at").
+		Updates(version).Error; err != nil {
+		return nil, err
+	}
+	return version, nil
+}
+
+// SubmitVersion 提交草稿版本评审，至少有一个示例且全部通过，未通过时返回ErrExamplesFailed和示例结果
+func (s *Service) SubmitVersion(ctx context.Context, id string, number int, username string) (*models.UDFVersion, []ExampleResult, error) {
+	def, version, err := s.getVersion(ctx, id, number)
+	if err != nil {
+		return nil, nil, err
+	}
+	if version.Status != models.UDFVersionDraft {
+		return nil, nil, fmt.Errorf("%w: 只能提交草稿版本，当前为%s", ErrVersionState, version.Status)
+	}
+	results, err := runExamples(def.Kind, version)
+	if err != nil {
+		return nil, results, err
+	}
+
+	now := time.Now()
+	version.Status = models.UDFVersionPendingReview
+	version.SubmittedBy = username
+	version.SubmittedAt = &now
+	version.ReviewedBy = ""
+	version.ReviewedAt = nil
+	version.ReviewComment = ""
+	version.UpdatedBy = username
+	if err := s.db.WithContext(ctx).Model(version).
+		Select("status", "submitted_by", "submitted_at", "reviewed_by", "reviewed_at", "review_comment", "updated_by", "updated_at").
+		Updates(version).Error; err != nil {
+		return nil, results, err
+	}
+
+	eventlog.Record(ctx, eventlog.Event{
+		Type:       EventUDFReviewRequested,
+		Message:    fmt.Sprintf("自定义函数%s的版本%d已提交评审", def.Name, version.Version),
+		ObjectType: ObjectTypeUDF,
+		ObjectID:   def.ID,
+		Attributes: map[string]interface{}{"name": def.Name, "version": version.Version, "submitted_by": username},
+	})
+	return version, results, nil
+}
+
+// ApproveVersion 审批待评审的版本，重新执行示例，通过后版本号大于当前版本时成为当前版本
+func (s *Service) ApproveVersion(ctx context.Context, id string, number int, reviewer, comment string) (*models.UDFVersion, error) {
+	def, version, err := s.getVersion(ctx, id, number)
+	if err != nil {
+		return nil, err
+	}
+	if version.Status != models.UDFVersionPendingReview {
+		return nil, fmt.Errorf("%w: 只能审批待评审的版本，当前为%s", ErrVersionState, version.Status)
+	}
+	if version.SubmittedBy == reviewer {
+		return nil, ErrSelfApproval
+	}
+	if _, err := runExamples(def.Kind, version); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	version.Status = models.UDFVersionApproved
+	version.ReviewedBy = reviewer
+	version.ReviewedAt = &now
+	version.ReviewComment = comment
+	version.UpdatedBy = reviewer
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(version).
+			Select("status", "reviewed_by", "reviewed_at", "review_comment", "updated_by", "updated_at").
+			Updates(version).Error; err != nil {
+			return err
+		}
+		return tx.Model(&models.UDFDefinition{}).Where("id = ? AND current_version < ?", id, version.Version).
+			Updates(map[string]interface{}{"current_version": version.Version, "updated_by": reviewer, "updated_at": now}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.invalidate(def.Name)
+	return version, nil
+}
+
+// RejectVersion 驳回待评审的版本，驳回后可以修改并重新提交
+func (s *Service) RejectVersion(ctx context.Context, id string, number int, reviewer, reason string) (*models.UDFVersion, error) {
+	_, version, err := s.getVersion(ctx, id, number)
+	if err != nil {
+		return nil, err
+	}
+	if version.Status != models.UDFVersionPendingReview {
+		return nil, fmt.Errorf("%w: 只能驳回待评审的版本，当前为%s", ErrVersionState, version.Status)
+	}
+	now := time.Now()
+	version.Status = models.UDFVersionRejected
+	version.ReviewedBy = reviewer
+	version.ReviewedAt = &now
+	version.ReviewComment = reason
+	version.UpdatedBy = reviewer
+	if err := s.db.WithContext(ctx).Model(version).
+		Select("status", "reviewed_by", "reviewed_at", "review_comment", "updated_by", "updated_at").
+		Updates(version).Error; err != nil {
+		return nil, err
+	}
+	return version, nil
+}
+
+// TestVersion 用给定的字段值和参数试运行任意状态的版本，同时返回版本示例的执行结果；表达式执行错误写入结果而不是返回错误
+func (s *Service) TestVersion(ctx context.Context, id string, number int, req *TestRequest) (*TestResult, error) {
+	def, version, err := s.getVersion(ctx, id, number)
+	if err != nil {
+		return nil, err
+	}
+	c, err := compileVersion(def.Kind, version)
+	if err != nil {
+		return nil, err
+	}
+	result := &TestResult{}
+	if value, err := c.invoke(req.Value, req.Args); err != nil {
+		result.Error = err.Error()
+	} else {
+		result.Result = value
+	}
+	result.Examples = c.runExamples()
+	return result, nil
+}
+
+// === 规则引用 ===
+
+// Call 调用规则引用的自定义函数，质量函数返回布尔值，清洗函数返回清洗后的值
+func (s *Service) Call(ctx context.Context, ref *Reference, kind string, value interface{}) (interface{}, error) {
+	c, err := s.resolve(ctx, ref, kind)
+	if err != nil {
+		return nil, err
+	}
+	return c.invoke(value, ref.Args)
+}
+
+// ValidateReference 检查引用的函数存在、用途一致、有已审批的版本，且参数符合签名
+func (s *Service) ValidateReference(ctx context.Context, ref *Reference, kind string) error {
+	c, err := s.resolve(ctx, ref, kind)
+	if err != nil {
+		return err
+	}
+	_, err = c.bindArgs(ref.Args)
+	return err
+}
+
+// resolve 解析引用的已审批版本
+func (s *Service) resolve(ctx context.Context, ref *Reference, kind string) (*compiled, error) {
+	key := ref.Name
+	if ref.Version > 0 {
+		key = fmt.Sprintf("%s@%d", ref.Name, ref.Version)
+	}
+	if cached, ok := s.cache.Load(key); ok {
+		entry := cached.(cacheEntry)
+		if entry.expiresAt.IsZero() || time.Now().Before(entry.expiresAt) {
+			return checkKind(entry.compiled, ref, kind)
+		}
+	}
+
+	var def models.UDFDefinition
+	if err := s.db.WithContext(ctx).First(&def, "name = ?", ref.Name).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w: 自定义函数%s不存在", ErrInvalidUDF, ref.Name)
+		}
+		return nil, err
+	}
+	number := ref.Version
+	if number == 0 {
+		if def.CurrentVersion == 0 {
+			return nil, fmt.Errorf("%w: 自定义函数%s没有已审批的版本", ErrInvalidUDF, ref.Name)
+		}
+		number = def.CurrentVersion
+	}
+	var version models.UDFVersion
+	if err := s.db.WithContext(ctx).First(&version, "udf_id = ? AND version = ?", def.ID, number).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w: 自定义函数%s没有版本%d", ErrInvalidUDF, ref.Name, number)
+		}
+		return nil, err
+	}
+	if version.Status != models.UDFVersionApproved {
+		return nil, fmt.Errorf("%w: 自定义函数%s的版本%d未审批", ErrInvalidUDF, ref.Name, number)
+	}
+	c, err := compileVersion(def.Kind, &version)
+	if err != nil {
+		return nil, err
+	}
+
+	entry := cacheEntry{compiled: c}
+	if ref.Version == 0 {
+		entry.expiresAt = time.Now().Add(currentCacheTTL)
+	}
+	s.cache.Store(key, entry)
+	return checkKind(c, ref, kind)
+}
+
+func checkKind(c *compiled, ref *Reference, kind string) (*compiled, error) {
+	if c.kind != kind {
+		return nil, fmt.Errorf("%w: 自定义函数%s的用途为%s，不能用于%s规则", ErrInvalidUDF, ref.Name, c.kind, kind)
+	}
+	return c, nil
+}
+
+// invalidate 清除函数的解析缓存
+func (s *Service) invalidate(name string) {
+	s.cache.Range(func(key, _ interface{}) bool {
+		if k := key.(string); k == name || strings.HasPrefix(k, name+"@") {
+			s.cache.Delete(key)
+		}
+		return true
+	})
+}
+
+// templateReferences 引用函数的质量规则模板和清洗模板名称，跨租户检查
+func (s *Service) templateReferences(name string) ([]string, error) {
+	var refs []string
+	var rules []models.QualityRuleTemplate
+	if err := s.db.Select("id", "name", "rule_logic").Find(&rules).Error; err != nil {
+		return nil, err
+	}
+	for _, rule := range rules {
+		if ref, _ := ParseReference(rule.RuleLogic); ref != nil && ref.Name == name {
+			refs = append(refs, "质量规则模板"+rule.Name)
+		}
+	}
+	var cleansing []models.DataCleansingTemplate
+	if err := s.db.Select("id", "name", "cleansing_logic").Find(&cleansing).Error; err != nil {
+		return nil, err
+	}
+	for _, template := range cleansing {
+		if ref, _ := ParseReference(template.CleansingLogic); ref != nil && ref.Name == name {
+			refs = append(refs, "清洗模板"+template.Name)
+		}
+	}
+	return refs, nil
+}
+
+func (s *Service) getVersion(ctx context.Context, id string, number int) (*models.UDFDefinition, *models.UDFVersion, error) {
+	var def models.UDFDefinition
+	if err := s.db.WithContext(ctx).First(&def, "id = ?", id).Error; err != nil {
+		return nil, nil, err
+	}
+	var version models.UDFVersion
+	if err := s.db.WithContext(ctx).First(&version, "udf_id = ? AND version = ?", id, number).Error; err != nil {
+		return nil, nil, err
+	}
+	return &def, &version, nil
+}
+
+// === 签名和示例 ===
+
+func newVersion(req *VersionRequest, username string) *models.UDFVersion {
+	inputType := req.InputType
+	if inputType == "" {
+		inputType = models.UDFTypeAny
+	}
+	return &models.UDFVersion{
+		Expression: strings.TrimSpace(req.Expression),
+		InputType:  inputType,
+		Params:     models.UDFParams(req.Params),
+		ReturnType: req.ReturnType,
+		Examples:   models.UDFExamples(req.Examples),
+		Status:     models.UDFVersionDraft,
+		CreatedBy:  username,
+		UpdatedBy:  username,
+	}
+}
+
+// validateVersion 检查签名并编译表达式
+func validateVersion(kind string, req *VersionRequest) error {
+	if kind == models.UDFKindQuality && req.ReturnType != models.UDFTypeBoolean {
+		return fmt.Errorf("%w: 质量检查函数必须返回boolean", ErrInvalidUDF)
+	}
+	seen := map[string]bool{valueVariable: true}
+	for _, param := range req.Params {
+		if !nameRegexp.MatchString(param.Name) {
+			return fmt.Errorf("%w: 参数名称%q只能包含小写字母、数字和下划线，以字母开头", ErrInvalidUDF, param.Name)
+		}
+		if seen[param.Name] {
+			return fmt.Errorf("%w: 参数%s重复或与value冲突", ErrInvalidUDF, param.Name)
+		}
+		seen[param.Name] = true
+		if !validType(param.Type) {
+			return fmt.Errorf("%w: 参数%s的类型%q不支持", ErrInvalidUDF, param.Name, param.Type)
+		}
+		if param.Default != nil && !matchesType(normalizeValue(param.Default), param.Type) {
+			return fmt.Errorf("%w: 参数%s的默认值不是%s", ErrInvalidUDF, param.Name, param.Type)
+		}
+	}
+	_, err := compileVersion(kind, newVersion(req, ""))
+	return err
+}
+
+func compileVersion(kind string, version *models.UDFVersion) (*compiled, error) {
+	variables := map[string]bool{valueVariable: true}
+	for _, param := range version.Params {
+		variables[param.Name] = true
+	}
+	prog, err := compile(version.Expression, variables)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidUDF, err)
+	}
+	return &compiled{kind: kind, version: *version, program: prog}, nil
+}
+
+// runExamples 编译并执行版本的示例，没有示例或有示例未通过时返回ErrExamplesFailed
+func runExamples(kind string, version *models.UDFVersion) ([]ExampleResult, error) {
+	if len(version.Examples) == 0 {
+		return nil, fmt.Errorf("%w: 至少需要一个示例", ErrExamplesFailed)
+	}
+	c, err := compileVersion(kind, version)
+	if err != nil {
+		return nil, err
+	}
+	results := c.runExamples()
+	for _, result := range results {
+		if !result.Passed {
+			return results, fmt.Errorf("%w: 第%d个示例", ErrExamplesFailed, result.Index+1)
+		}
+	}
+	return results, nil
+}
+
+func (c *compiled) runExamples() []ExampleResult {
+	results := make([]ExampleResult, 0, len(c.version.Examples))
+	for i, example := range c.version.Examples {
+		result := ExampleResult{Index: i}
+		actual, err := c.invoke(example.Value, example.Args)
+		if err != nil {
+			result.Error = err.Error()
+		} else {
+			result.Actual = actual
+			result.Passed = reflect.DeepEqual(actual, normalizeExpected(example.Expected))
+		}
+		results = append(results, result)
+	}
+	return results
+}
+
+// normalizeExpected 期望结果按表达式的值类型比较
+func normalizeExpected(v interface{}) interface{} {
+	switch v.(type) {
+	case map[string]interface{}, []interface{}:
+		content, _ := json.Marshal(v)
+		return string(content)
+	}
+	return normalizeValue(v)
+}
+
+// invoke 绑定字段值和参数后求值，并检查返回值类型
+func (c *compiled) invoke(value interface{}, args map[string]interface{}) (interface{}, error) {
+	vars, err := c.bindArgs(args)
+	if err != nil {
+		return nil, err
+	}
+	input := normalizeValue(value)
+	if input != nil && !matchesType(input, c.version.InputType) {
+		return nil, fmt.Errorf("字段值应为%s，实际为%s", c.version.InputType, typeName(input))
+	}
+	vars[valueVariable] = input
+
+	result, err := c.program.run(vars)
+	if err != nil {
+		return nil, err
+	}
+	if result == nil && c.kind == models.UDFKindQuality {
+		return nil, errors.New("质量检查函数返回了null")
+	}
+	if result != nil && !matchesType(result, c.version.ReturnType) {
+		return nil, fmt.Errorf("返回值应为%s，实际为%s", c.version.ReturnType, typeName(result))
+	}
+	return result, nil
+}
+
+// bindArgs 按参数声明绑定参数，未传入时使用默认值
+func (c *compiled) bindArgs(args map[string]interface{}) (map[string]interface{}, error) {
+	vars := make(map[string]interface{}, len(c.version.Params)+1)
+	declared := make(map[string]bool, len(c.version.Params))
+	for _, param := range c.version.Params {
+		declared[param.Name] = true
+		value := normalizeValue(args[param.Name])
+		if value == nil {
+			value = normalizeValue(param.Default)
+		}
+		if value == nil {
+			if param.Required {
+				return nil, fmt.Errorf("%w: 缺少参数%s", ErrInvalidUDF, param.Name)
+			}
+		} else if !matchesType(value, param.Type) {
+			return nil, fmt.Errorf("%w: 参数%s应为%s，实际为%s", ErrInvalidUDF, param.Name, param.Type, typeName(value))
+		}
+		vars[param.Name] = value
+	}
+	for name := range args {
+		if !declared[name] {
+			return nil, fmt.Errorf("%w: 参数%s未声明", ErrInvalidUDF, name)
+		}
+	}
+	return vars, nil
+}
+
+func validType(t string) bool {
+	switch t {
+	case models.UDFTypeString, models.UDFTypeNumber, models.UDFTypeBoolean, models.UDFTypeAny:
+		return true
+	}
+	return false
+}
+
+// matchesType 值是否符合声明的类型，any接受任意值
+func matchesType(v interface{}, t string) bool {
+	return t == models.UDFTypeAny || t == "" || typeName(v) == t
+}
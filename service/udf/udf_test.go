@@ -0,0 +1,279 @@
+/*
+ * @module service/udf/udf_test
+ * @description 自定义函数测试，覆盖表达式求值和沙箱限制、版本的提交评审审批流程、规则引用的解析和参数检查，以及被规则模板引用时禁止删除
+ * @architecture 测试层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow 编译表达式并求值 / 创建函数 -> 提交评审 -> 审批 -> 按引用调用
+ * @rules 使用内存sqlite，不依赖外部服务
+ * @dependencies gorm.io/driver/sqlite, stretchr/testify
+ * @refs expr.go, registry.go
+ */
+
+package udf
+
+import (
+	"context"
+	"datahub-service/service/models"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func setupService(t *testing.T) *Service {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(
+		&models.UDFDefinition{},
+		&models.UDFVersion{},
+		&models.QualityRuleTemplate{},
+		&models.DataCleansingTemplate{},
+	))
+	return NewService(db)
+}
+
+func evaluate(t *testing.T, expression string, vars map[string]interface{}) (interface{}, error) {
+	allowed := map[string]bool{}
+	for name := range vars {
+		allowed[name] = true
+	}
+	prog, err := compile(expression, allowed)
+	require.NoError(t, err, expression)
+	return prog.run(vars)
+}
+
+func TestExpressionEvaluation(t *testing.T) {
+	vars := map[string]interface{}{"value": " Hello ", "n": 7, "limit": 5.5, "empty": nil}
+	cases := []struct {
+		expression string
+		expected   interface{}
+	}{
+		{"1 + 2 * 3", 7.0},
+		{"(1 + 2) * 3", 9.0},
+		{"n % 4 == 3 && n > limit", true},
+		{"!(n < 2) || 1 / 0 > 0", true},
+		{"trim(lower(value))", "hello"},
+		{"len(trim(value)) <= 5 ? 'short' : 'long'", "short"},
+		{"'a' + n", "a7"},
+		{"substr('上海市浦东新区', 0, 3)", "上海市"},
+		{"replace(value, 'l', 'L')", " HeLLo "},
+		{"matches(trim(value), '^H[a-z]+$')", true},
+		{"to_number('12.5') + 1", 13.5},
+		{"round(2.345, 2)", 2.35},
+		{"max(1, n, 3)", 7.0},
+		{"coalesce(empty, 'x')", "x"},
+		{"is_empty(empty) && !contains(empty, 'a')", true},
+		{"upper(empty) == null", true},
+		{"n == '7'", false},
+	}
+	for _, c := range cases {
+		result, err := evaluate(t, c.expression, vars)
+		require.NoError(t, err, c.expression)
+		assert.Equal(t, c.expected, result, c.expression)
+	}
+
+	// 运行时类型错误
+	for _, expression := range []string{"n && true", "value < 1", "-value", "n / 0", "substr(n, 1)"} {
+		_, err := evaluate(t, expression, vars)
+		assert.Error(t, err, expression)
+	}
+}
+
+func TestExpressionSandboxLimits(t *testing.T) {
+	vars := map[string]bool{"value": true}
+	for _, expression := range []string{
+		"",
+		"value +",
+		"undeclared > 1",
+		"exec('rm -rf /')",
+		"len(value, 1)",
+		"value = 1",
+		"'unterminated",
+		"1 2",
+		strings.Repeat("1 + ", 300) + "1",
+		strings.Repeat("a", maxExpressionLength+1),
+	} {
+		_, err := compile(expression, vars)
+		assert.ErrorIs(t, err, ErrExpression, expression)
+	}
+
+	// 字符串长度受限
+	prog, err := compile("replace(value, 'a', value)", vars)
+	require.NoError(t, err)
+	_, err = prog.run(map[string]interface{}{"value": strings.Repeat("a", 1000)})
+	assert.ErrorContains(t, err, "字符串超过")
+}
+
+func qualityRequest() *UDFRequest {
+	return &UDFRequest{
+		Name:        "max_len",
+		DisplayName: "最大长度",
+		Kind:        models.UDFKindQuality,
+		Version: VersionRequest{
+			Expression: "len(value) <= max_length",
+			InputType:  models.UDFTypeString,
+			Params:     []models.UDFParam{{Name: "max_length", Type: models.UDFTypeNumber, Default: 8}},
+			ReturnType: models.UDFTypeBoolean,
+			Examples: []models.UDFExample{
+				{Value: "沪A12345", Expected: true},
+				{Value: "沪A12345", Args: map[string]interface{}{"max_length": 3}, Expected: false},
+			},
+		},
+	}
+}
+
+func TestUDFReviewLifecycle(t *testing.T) {
+	s := setupService(t)
+	ctx := context.Background()
+
+	// 质量函数必须返回布尔值，名称须合法且唯一
+	bad := qualityRequest()
+	bad.Version.ReturnType = models.UDFTypeString
+	_, err := s.CreateUDF(ctx, bad, "admin1")
+	assert.ErrorIs(t, err, ErrInvalidUDF)
+	bad = qualityRequest()
+	bad.Name = "Bad-Name"
+	_, err = s.CreateUDF(ctx, bad, "admin1")
+	assert.ErrorIs(t, err, ErrInvalidUDF)
+
+	detail, err := s.CreateUDF(ctx, qualityRequest(), "admin1")
+	require.NoError(t, err)
+	id := detail.ID
+	require.Len(t, detail.Versions, 1)
+	assert.Equal(t, models.UDFVersionDraft, detail.Versions[0].Status)
+	_, err = s.CreateUDF(ctx, qualityRequest(), "admin1")
+	assert.ErrorIs(t, err, ErrInvalidUDF)
+
+	// 没有已审批的版本时不能引用
+	ref := &Reference{Name: "max_len"}
+	assert.ErrorIs(t, s.ValidateReference(ctx, ref, models.UDFKindQuality), ErrInvalidUDF)
+
+	// 示例未通过时不能提交
+	failing := qualityRequest().Version
+	failing.Examples[1].Expected = true
+	_, err = s.UpdateVersion(ctx, id, 1, &failing, "admin1")
+	require.NoError(t, err)
+	_, results, err := s.SubmitVersion(ctx, id, 1, "admin1")
+	assert.ErrorIs(t, err, ErrExamplesFailed)
+	require.Len(t, results, 2)
+	assert.True(t, results[0].Passed)
+	assert.False(t, results[1].Passed)
+
+	passing := qualityRequest().Version
+	_, err = s.UpdateVersion(ctx, id, 1, &passing, "admin1")
+	require.NoError(t, err)
+	version, _, err := s.SubmitVersion(ctx, id, 1, "admin1")
+	require.NoError(t, err)
+	assert.Equal(t, models.UDFVersionPendingReview, version.Status)
+
+	// 待评审的版本不能修改，提交人不能审批
+	_, err = s.UpdateVersion(ctx, id, 1, &passing, "admin1")
+	assert.ErrorIs(t, err, ErrVersionState)
+	_, err = s.ApproveVersion(ctx, id, 1, "admin1", "")
+	assert.ErrorIs(t, err, ErrSelfApproval)
+
+	version, err = s.ApproveVersion(ctx, id, 1, "admin2", "同意")
+	require.NoError(t, err)
+	assert.Equal(t, models.UDFVersionApproved, version.Status)
+	assert.Equal(t, "admin2", version.ReviewedBy)
+
+	got, err := s.GetUDF(ctx, id)
+	require.NoError(t, err)
+	assert.Equal(t, 1, got.CurrentVersion)
+
+	// 按当前版本调用，参数覆盖默认值
+	result, err := s.Call(ctx, ref, models.UDFKindQuality, "沪A12345")
+	require.NoError(t, err)
+	assert.Equal(t, true, result)
+	result, err = s.Call(ctx, &Reference{Name: "max_len", Args: map[string]interface{}{"max_length": 2}}, models.UDFKindQuality, "沪A12345")
+	require.NoError(t, err)
+	assert.Equal(t, false, result)
+
+	// 用途不一致、参数未声明或类型不符时引用无效
+	assert.ErrorIs(t, s.ValidateReference(ctx, ref, models.UDFKindCleansing), ErrInvalidUDF)
+	assert.ErrorIs(t, s.ValidateReference(ctx, &Reference{Name: "max_len", Args: map[string]interface{}{"x": 1}}, models.UDFKindQuality), ErrInvalidUDF)
+	assert.ErrorIs(t, s.ValidateReference(ctx, &Reference{Name: "max_len", Args: map[string]interface{}{"max_length": "3"}}, models.UDFKindQuality), ErrInvalidUDF)
+	_, err = s.Call(ctx, ref, models.UDFKindQuality, 12)
+	assert.ErrorContains(t, err, "字段值应为string")
+
+	// 新版本驳回后不影响当前版本，固定版本号引用未审批的版本无效
+	v2 := qualityRequest().Version
+	v2.Expression = "len(value) < max_length"
+	v2.Examples = []models.UDFExample{{Value: "abc", Expected: true}}
+	version, err = s.CreateVersion(ctx, id, &v2, "admin1")
+	require.NoError(t, err)
+	assert.Equal(t, 2, version.Version)
+	_, _, err = s.SubmitVersion(ctx, id, 2, "admin1")
+	require.NoError(t, err)
+	_, err = s.RejectVersion(ctx, id, 2, "admin2", "边界条件不对")
+	require.NoError(t, err)
+	assert.ErrorIs(t, s.ValidateReference(ctx, &Reference{Name: "max_len", Version: 2}, models.UDFKindQuality), ErrInvalidUDF)
+	got, err = s.GetUDF(ctx, id)
+	require.NoError(t, err)
+	assert.Equal(t, 1, got.CurrentVersion)
+	require.Len(t, got.Versions, 2)
+	assert.Equal(t, models.UDFVersionRejected, got.Versions[0].Status)
+
+	// 试运行任意状态的版本
+	test, err := s.TestVersion(ctx, id, 2, &TestRequest{Value: "abcdefgh"})
+	require.NoError(t, err)
+	assert.Equal(t, false, test.Result)
+	require.Len(t, test.Examples, 1)
+	assert.True(t, test.Examples[0].Passed)
+}
+
+func TestCleansingReferenceAndDelete(t *testing.T) {
+	s := setupService(t)
+	ctx := context.Background()
+	SetDefault(s)
+	defer SetDefault(nil)
+
+	detail, err := s.CreateUDF(ctx, &UDFRequest{
+		Name:        "normalize_phone",
+		DisplayName: "手机号标准化",
+		Kind:        models.UDFKindCleansing,
+		Version: VersionRequest{
+			Expression: "replace(replace(trim(value), '-', ''), ' ', '')",
+			ReturnType: models.UDFTypeString,
+			Examples:   []models.UDFExample{{Value: " 138-0000 0000 ", Expected: "13800000000"}},
+		},
+	}, "admin1")
+	require.NoError(t, err)
+	_, _, err = s.SubmitVersion(ctx, detail.ID, 1, "admin1")
+	require.NoError(t, err)
+	_, err = s.ApproveVersion(ctx, detail.ID, 1, "admin2", "")
+	require.NoError(t, err)
+
+	logic := map[string]interface{}{"udf": "normalize_phone", "udf_version": float64(1)}
+	require.NoError(t, ValidateLogic(ctx, logic, models.UDFKindCleansing))
+	assert.NoError(t, ValidateLogic(ctx, map[string]interface{}{"pattern": "x"}, models.UDFKindCleansing))
+	assert.ErrorIs(t, ValidateLogic(ctx, map[string]interface{}{"udf": "missing"}, models.UDFKindCleansing), ErrInvalidUDF)
+	assert.ErrorIs(t, ValidateLogic(ctx, map[string]interface{}{"udf": "normalize_phone", "udf_version": 1.5}, models.UDFKindCleansing), ErrInvalidUDF)
+
+	ref, err := ParseReference(logic)
+	require.NoError(t, err)
+	cleaned, err := Call(ctx, ref, models.UDFKindCleansing, "139 1111-2222")
+	require.NoError(t, err)
+	assert.Equal(t, "13911112222", cleaned)
+	cleaned, err = Call(ctx, ref, models.UDFKindCleansing, nil)
+	require.NoError(t, err)
+	assert.Nil(t, cleaned)
+
+	// 被清洗模板引用时不能删除
+	template := &models.DataCleansingTemplate{Name: "手机号清洗", RuleType: "standardization", Category: "data_format", CleansingLogic: models.JSONB(logic)}
+	require.NoError(t, s.db.Create(template).Error)
+	err = s.DeleteUDF(ctx, detail.ID)
+	assert.ErrorIs(t, err, ErrUDFInUse)
+	assert.ErrorContains(t, err, "手机号清洗")
+
+	require.NoError(t, s.db.Delete(template).Error)
+	require.NoError(t, s.DeleteUDF(ctx, detail.ID))
+	_, err = Call(ctx, ref, models.UDFKindCleansing, "1")
+	assert.ErrorIs(t, err, ErrInvalidUDF)
+}
@@ -0,0 +1,293 @@
+/*
+ * @module service/utils/field_transform
+ * @description 字段映射转换函数，支持截取、拼接、日期解析、单位换算、码值映射和默认值
+ * @architecture 工具函数模式，转换步骤按配置顺序依次执行
+ * @documentReference ai_docs/interface_executor.md
+ * @stateFlow 源字段值 -> 转换步骤1 -> 转换步骤2 -> ... -> 目标字段值
+ * @rules
+ *   - 转换配置为 parseConfig.fieldMapping 条目中的 transforms 数组
+ *   - 某一步失败时值置为nil并继续执行后续步骤，可用default步骤兜底
+ *   - 日期格式使用Go时间布局（如 2006-01-02 15:04:05），另支持 unix、unix_ms
+ * @dependencies github.com/spf13/cast
+ * @refs service/interface_executor/field_mapping.go, service/datasource/realtime_processor.go
+ */
+
+package utils
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/spf13/cast"
+)
+
+// 字段转换类型
+const (
+	FieldTransformSubstring = "substring"
+	FieldTransformConcat    = "concat"
+	FieldTransformDateParse = "date_parse"
+	FieldTransformUnit      = "unit"
+	FieldTransformLookup    = "lookup"
+	FieldTransformDefault   = "default"
+)
+
+// concatCurrentValue concat的fields中代表当前值的占位符
+const concatCurrentValue = "$value"
+
+// unitFactors 线性单位到同量纲基准单位的换算系数
+var unitFactors = map[string]struct {
+	dimension string
+	factor    float64
+}{
+	"mm": {"length", 0.001}, "cm": {"length", 0.01}, "m": {"length", 1}, "km": {"length", 1000},
+	"mg": {"weight", 1e-6}, "g": {"weight", 0.001}, "kg": {"weight", 1}, "t": {"weight", 1000},
+	"ms": {"time", 0.001}, "s": {"time", 1}, "min": {"time", 60}, "h": {"time", 3600}, "d": {"time", 86400},
+	"b": {"data", 1}, "kb": {"data", 1 << 10}, "mb": {"data", 1 << 20}, "gb": {"data", 1 << 30},
+}
+
+// ApplyFieldTransforms 依次执行转换步骤，row为原始记录供concat引用其他字段；
+// 返回最终值和第一个失败步骤的错误
+func ApplyFieldTransforms(value interface{}, row map[string]interface{}, transforms []interface{}) (interface{}, error) {
+	var firstErr error
+	for i, item := range transforms {
+		config, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		transformType := cast.ToString(config["type"])
+
+		result, err := applyFieldTransform(transformType, value, row, config)
+		if err != nil {
+			if firstErr == nil {
+				firstErr = fmt.Errorf("第%d步转换 %s 失败: %w", i+1, transformType, err)
+			}
+			value = nil
+			continue
+		}
+		value = result
+	}
+	return value, firstErr
+}
+
+// applyFieldTransform 执行单个转换步骤
+func applyFieldTransform(transformType string, value interface{}, row map[string]interface{}, config map[string]interface{}) (interface{}, error) {
+	switch transformType {
+	case FieldTransformSubstring:
+		return transformSubstring(value, config)
+	case FieldTransformConcat:
+		return transformConcat(value, row, config), nil
+	case FieldTransformDateParse:
+		return transformDateParse(value, config)
+	case FieldTransformUnit:
+		return transformUnit(value, config)
+	case FieldTransformLookup:
+		return transformLookup(value, config), nil
+	case FieldTransformDefault:
+		if isEmptyFieldValue(value) {
+			return config["value"], nil
+		}
+		return value, nil
+	default:
+		return nil, fmt.Errorf("不支持的转换类型")
+	}
+}
+
+// transformSubstring 按字符截取，start为负数时从末尾计算，未配置length时截取到末尾
+func transformSubstring(value interface{}, config map[string]interface{}) (interface{}, error) {
+	if value == nil {
+		return nil, nil
+	}
+	runes := []rune(cast.ToString(value))
+
+	start := cast.ToInt(config["start"])
+	if start < 0 {
+		start += len(runes)
+	}
+	start = max(0, min(start, len(runes)))
+
+	end := len(runes)
+	if length, ok := config["length"]; ok {
+		n := cast.ToInt(length)
+		if n < 0 {
+			return nil, fmt.Errorf("length不能为负数")
+		}
+		end = min(start+n, len(runes))
+	}
+	return string(runes[start:end]), nil
+}
+
+// transformConcat 拼接当前值和fields中的字段，fields可用$value指定当前值的位置，空值跳过
+func transformConcat(value interface{}, row map[string]interface{}, config map[string]interface{}) interface{} {
+	fields := cast.ToStringSlice(config["fields"])
+	separator := cast.ToString(config["separator"])
+
+	hasPlaceholder := false
+	for _, field := range fields {
+		if field == concatCurrentValue {
+			hasPlaceholder = true
+			break
+		}
+	}
+	if !hasPlaceholder {
+		fields = append([]string{concatCurrentValue}, fields...)
+	}
+
+	parts := make([]string, 0, len(fields))
+	for _, field := range fields {
+		part := value
+		if field != concatCurrentValue {
+			part = row[field]
+		}
+		if part == nil {
+			continue
+		}
+		parts = append(parts, cast.ToString(part))
+	}
+	return strings.Join(parts, separator)
+}
+
+// transformDateParse 按format解析日期，配置output_format时输出字符串，否则输出时间
+func transformDateParse(value interface{}, config map[string]interface{}) (interface{}, error) {
+	if isEmptyFieldValue(value) {
+		return nil, nil
+	}
+
+	location := time.Local
+	if tz := cast.ToString(config["timezone"]); tz != "" {
+		loc, err := time.LoadLocation(tz)
+		if err != nil {
+			return nil, fmt.Errorf("无效的时区 %s", tz)
+		}
+		location = loc
+	}
+
+	var parsed time.Time
+	format := cast.ToString(config["format"])
+	switch t := value.(type) {
+	case time.Time:
+		parsed = t
+	default:
+		switch format {
+		case "unix", "unix_ms":
+			n, err := cast.ToInt64E(value)
+			if err != nil {
+				return nil, fmt.Errorf("值 %v 不是有效的时间戳", value)
+			}
+			if format == "unix" {
+				parsed = time.Unix(n, 0).In(location)
+			} else {
+				parsed = time.UnixMilli(n).In(location)
+			}
+		case "":
+			return nil, fmt.Errorf("未配置format")
+		default:
+			str := strings.TrimSpace(cast.ToString(value))
+			t, err := time.ParseInLocation(format, str, location)
+			if err != nil {
+				return nil, fmt.Errorf("值 %q 不符合格式 %s", str, format)
+			}
+			parsed = t
+		}
+	}
+
+	if outputFormat := cast.ToString(config["output_format"]); outputFormat != "" {
+		return parsed.Format(outputFormat), nil
+	}
+	return parsed, nil
+}
+
+// transformUnit 单位换算，配置from/to时按内置单位表换算，否则按 value*factor+offset 计算
+func transformUnit(value interface{}, config map[string]interface{}) (interface{}, error) {
+	if isEmptyFieldValue(value) {
+		return nil, nil
+	}
+	number, err := cast.ToFloat64E(strings.TrimSpace(cast.ToString(value)))
+	if err != nil {
+		return nil, fmt.Errorf("值 %v 不是有效的数值", value)
+	}
+
+	from := strings.ToLower(cast.ToString(config["from"]))
+	to := strings.ToLower(cast.ToString(config["to"]))
+	switch {
+	case from != "" || to != "":
+		number, err = convertUnit(number, from, to)
+		if err != nil {
+			return nil, err
+		}
+	default:
+		factor := 1.0
+		if f, ok := config["factor"]; ok {
+			factor = cast.ToFloat64(f)
+		}
+		number = number*factor + cast.ToFloat64(config["offset"])
+	}
+
+	if precision, ok := config["precision"]; ok {
+		scale := math.Pow(10, float64(cast.ToInt(precision)))
+		number = math.Round(number*scale) / scale
+	}
+	return number, nil
+}
+
+// convertUnit 在同量纲单位之间换算，温度单位c/f/k单独处理
+func convertUnit(number float64, from, to string) (float64, error) {
+	if isTemperatureUnit(from) && isTemperatureUnit(to) {
+		kelvin := number
+		switch from {
+		case "c":
+			kelvin = number + 273.15
+		case "f":
+			kelvin = (number-32)*5/9 + 273.15
+		}
+		switch to {
+		case "c":
+			return kelvin - 273.15, nil
+		case "f":
+			return (kelvin-273.15)*9/5 + 32, nil
+		}
+		return kelvin, nil
+	}
+
+	fromUnit, ok := unitFactors[from]
+	if !ok {
+		return 0, fmt.Errorf("不支持的单位 %s", from)
+	}
+	toUnit, ok := unitFactors[to]
+	if !ok {
+		return 0, fmt.Errorf("不支持的单位 %s", to)
+	}
+	if fromUnit.dimension != toUnit.dimension {
+		return 0, fmt.Errorf("单位 %s 和 %s 不能互相换算", from, to)
+	}
+	return number * fromUnit.factor / toUnit.factor, nil
+}
+
+// isTemperatureUnit 是否为温度单位
+func isTemperatureUnit(unit string) bool {
+	return unit == "c" || unit == "f" || unit == "k"
+}
+
+// transformLookup 按mapping映射码值，未命中时使用default，未配置default则保留原值
+func transformLookup(value interface{}, config map[string]interface{}) interface{} {
+	mapping, _ := config["mapping"].(map[string]interface{})
+	if value != nil {
+		if mapped, ok := mapping[cast.ToString(value)]; ok {
+			return mapped
+		}
+	}
+	if defaultValue, ok := config["default"]; ok {
+		return defaultValue
+	}
+	return value
+}
+
+// isEmptyFieldValue 值为nil或空白字符串
+func isEmptyFieldValue(value interface{}) bool {
+	if value == nil {
+		return true
+	}
+	str, ok := value.(string)
+	return ok && strings.TrimSpace(str) == ""
+}
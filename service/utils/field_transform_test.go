@@ -0,0 +1,76 @@
+/*
+ * @module service/utils/field_transform_test
+ * @description 字段映射转换函数单元测试
+ * @architecture 测试层 - 纯函数测试，无外部依赖
+ * @documentReference .specify/memory/test_plan.md
+ * @stateFlow 输入参数 -> 函数调用 -> 输出验证
+ * @rules 覆盖各转换类型、步骤串联和失败兜底
+ * @dependencies testing, testify
+ * @refs field_transform.go
+ */
+
+package utils
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyFieldTransforms(t *testing.T) {
+	row := map[string]interface{}{"province": "浙江", "city": "杭州", "district": nil}
+	step := func(config map[string]interface{}) []interface{} { return []interface{}{config} }
+
+	testCases := []struct {
+		name       string
+		value      interface{}
+		transforms []interface{}
+		expected   interface{}
+	}{
+		{"截取", "310101199001011234", step(map[string]interface{}{"type": "substring", "start": 6, "length": 8}), "19900101"},
+		{"从末尾截取", "设备编号A01", step(map[string]interface{}{"type": "substring", "start": -3}), "A01"},
+		{"拼接跳过空值", "中国", step(map[string]interface{}{"type": "concat", "fields": []interface{}{"province", "district", "city"}, "separator": "-"}), "中国-浙江-杭州"},
+		{"拼接指定当前值位置", "路1号", step(map[string]interface{}{"type": "concat", "fields": []interface{}{"city", "$value"}}), "杭州路1号"},
+		{"秒级时间戳", float64(1700000000), step(map[string]interface{}{"type": "date_parse", "format": "unix", "timezone": "Asia/Shanghai", "output_format": "2006-01-02 15:04"}), "2023-11-15 06:13"},
+		{"按表达式换算", "36.6", step(map[string]interface{}{"type": "unit", "factor": 10, "offset": 1}), 367.0},
+		{"温度换算", 212, step(map[string]interface{}{"type": "unit", "from": "F", "to": "C", "precision": 1}), 100.0},
+		{"码值未命中使用默认值", "9", step(map[string]interface{}{"type": "lookup", "mapping": map[string]interface{}{"1": "正常"}, "default": "未知"}), "未知"},
+		{"码值未命中保留原值", "9", step(map[string]interface{}{"type": "lookup", "mapping": map[string]interface{}{"1": "正常"}}), "9"},
+		{"非空值不替换", "x", step(map[string]interface{}{"type": "default", "value": "y"}), "x"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := ApplyFieldTransforms(tc.value, row, tc.transforms)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, result)
+		})
+	}
+}
+
+func TestApplyFieldTransformsDateParse(t *testing.T) {
+	result, err := ApplyFieldTransforms("2026/05/01 08:30", nil, []interface{}{
+		map[string]interface{}{"type": "date_parse", "format": "2006/01/02 15:04", "timezone": "Asia/Shanghai"},
+	})
+	require.NoError(t, err)
+	parsed, ok := result.(time.Time)
+	require.True(t, ok)
+	assert.Equal(t, "2026-05-01T08:30:00+08:00", parsed.Format(time.RFC3339))
+}
+
+func TestApplyFieldTransformsFailure(t *testing.T) {
+	result, err := ApplyFieldTransforms("not-a-date", nil, []interface{}{
+		map[string]interface{}{"type": "date_parse", "format": "2006-01-02"},
+		map[string]interface{}{"type": "default", "value": "1970-01-01"},
+	})
+	assert.ErrorContains(t, err, "第1步转换 date_parse 失败")
+	assert.Equal(t, "1970-01-01", result, "失败后值置空，由default兜底")
+
+	_, err = ApplyFieldTransforms(1, nil, []interface{}{map[string]interface{}{"type": "unit", "from": "kg", "to": "m"}})
+	assert.ErrorContains(t, err, "不能互相换算")
+
+	_, err = ApplyFieldTransforms(1, nil, []interface{}{map[string]interface{}{"type": "upper"}})
+	assert.ErrorContains(t, err, "不支持的转换类型")
+}
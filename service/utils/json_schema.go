@@ -0,0 +1,478 @@
+/*
+ * @module service/utils/json_schema
+ * @description 根据接口表字段配置生成JSON Schema，并按生成的Schema校验写入的数据行，返回结构化的违规清单
+ * @architecture 工具函数模式
+ * @documentReference ai_docs/interface_executor.md
+ * @stateFlow 表字段配置 -> 按order_num排序的属性(类型、格式、长度、必填) -> JSON Schema -> 逐行逐字段校验 -> 违规清单(行号、字段、关键字、违规值)
+ * @rules
+ *   - 生成的Schema遵循JSON Schema 2020-12，校验只实现生成时用到的关键字：type、required、pattern、format、maxLength
+ *   - 与写入时的类型转换保持一致：整数、数值、布尔字段也接受可转换的字符串，pattern只约束字符串形式的值
+ *   - 不可为空且没有默认值的字段为必填，主键不可为空；派生列只读，不校验
+ *   - 只有字段类型带长度时(如varchar(50))才生成maxLength，长度按字符计算
+ *   - 允许配置外的字段(写入时丢弃并计入同步警告)
+ * @dependencies encoding/json, regexp
+ * @refs service/interface_executor/payload_schema.go, service/datasource/realtime_processor.go
+ */
+
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/spf13/cast"
+)
+
+// JSONSchemaDraft 生成的Schema遵循的规范版本
+const JSONSchemaDraft = "https://json-schema.org/draft/2020-12/schema"
+
+// MaxPayloadViolations 校验结果中最多返回的违规条数
+const MaxPayloadViolations = 100
+
+// 字段值的字符串形式需要满足的模式
+const (
+	integerPattern = `^\s*[+-]?\d+\s*$`
+	numberPattern  = `^\s*[+-]?(\d+(\.\d*)?|\.\d+)([eE][+-]?\d+)?\s*$`
+	booleanPattern = `^([Tt]rue|TRUE|[Ff]alse|FALSE|[Yy]es|YES|[Nn]o|NO|[Yy]|[Nn]|[Oo]n|ON|[Oo]ff|OFF|1|0)$`
+	uuidPattern    = `^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`
+)
+
+// patternNames 模式的中文说明，用于违规信息
+var patternNames = map[string]string{
+	integerPattern: "整数",
+	numberPattern:  "数值",
+	booleanPattern: "布尔值",
+}
+
+// compiledPatterns 已编译的模式缓存
+var compiledPatterns sync.Map
+
+// varcharLengthPattern 带长度的字符类型，如 varchar(50)、character varying(50)、char(2)
+var varcharLengthPattern = regexp.MustCompile(`^(varchar|character varying|char|character)\s*\(\s*(\d+)\s*\)$`)
+
+// JSONSchema 接口数据的JSON Schema
+type JSONSchema struct {
+	Schema               string                 `json:"$schema,omitempty"`
+	Title                string                 `json:"title,omitempty"`
+	Description          string                 `json:"description,omitempty"`
+	Type                 []string               `json:"type,omitempty"`
+	Properties           map[string]*JSONSchema `json:"properties,omitempty"`
+	Required             []string               `json:"required,omitempty"`
+	AdditionalProperties *bool                  `json:"additionalProperties,omitempty"`
+	Format               string                 `json:"format,omitempty"`
+	Pattern              string                 `json:"pattern,omitempty"`
+	MaxLength            *int                   `json:"maxLength,omitempty"`
+	ReadOnly             bool                   `json:"readOnly,omitempty"`
+	// propertyOrder 属性按字段配置顺序排列，校验时按此顺序输出违规
+	propertyOrder []string
+}
+
+// PayloadViolation 一处不符合Schema的字段值
+type PayloadViolation struct {
+	Row     int    `json:"row"` // 在本次数据中的序号，从0开始
+	Field   string `json:"field"`
+	Keyword string `json:"keyword" example:"type"` // 不满足的Schema关键字：required/type/pattern/format/maxLength
+	Value   string `json:"value,omitempty"`
+	Message string `json:"message"`
+}
+
+// PayloadValidationResult 数据按Schema校验的结果
+type PayloadValidationResult struct {
+	Valid          bool               `json:"valid"`
+	RowsChecked    int                `json:"rows_checked"`
+	InvalidRows    int                `json:"invalid_rows"`
+	ViolationCount int                `json:"violation_count"`
+	Violations     []PayloadViolation `json:"violations"`          // 最多MaxPayloadViolations条
+	Truncated      bool               `json:"truncated,omitempty"` // 违规条数超过上限，只返回了前面的部分
+}
+
+// Summary 违规摘要，用于错误信息
+func (r *PayloadValidationResult) Summary() string {
+	if r.Valid {
+		return ""
+	}
+	parts := make([]string, 0, 3)
+	for i, violation := range r.Violations {
+		if i == 3 {
+			break
+		}
+		parts = append(parts, fmt.Sprintf("第%d行 %s: %s", violation.Row+1, violation.Field, violation.Message))
+	}
+	return fmt.Sprintf("%d行%d处违规，%s", r.InvalidRows, r.ViolationCount, strings.Join(parts, "；"))
+}
+
+// schemaField 生成Schema用到的字段配置
+type schemaField struct {
+	name         string
+	title        string
+	description  string
+	dataType     string
+	nullable     bool
+	primaryKey   bool
+	defaultValue string
+	computed     bool
+	order        int
+}
+
+// BuildTableJSONSchema 根据表字段配置生成JSON Schema，支持TableField格式(name_en/data_type)和fields数组格式(field_name/field_type)
+func BuildTableJSONSchema(title string, tableFieldsConfig []interface{}) *JSONSchema {
+	fields := parseSchemaFields(tableFieldsConfig)
+	allowAdditional := true
+	schema := &JSONSchema{
+		Schema:               JSONSchemaDraft,
+		Title:                title,
+		Type:                 []string{"object"},
+		Properties:           make(map[string]*JSONSchema, len(fields)),
+		AdditionalProperties: &allowAdditional,
+	}
+	for _, field := range fields {
+		if _, exists := schema.Properties[field.name]; exists {
+			continue
+		}
+		schema.Properties[field.name] = buildFieldSchema(field)
+		schema.propertyOrder = append(schema.propertyOrder, field.name)
+		// 有默认值的字段缺失时由数据库填充
+		if !field.nullable && field.defaultValue == "" && !field.computed {
+			schema.Required = append(schema.Required, field.name)
+		}
+	}
+	return schema
+}
+
+// parseSchemaFields 解析字段配置并按order_num、名称排序
+func parseSchemaFields(tableFieldsConfig []interface{}) []schemaField {
+	var fields []schemaField
+	for _, item := range tableFieldsConfig {
+		fieldMap, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if fieldsArray, ok := fieldMap["fields"].([]interface{}); ok {
+			for i, fieldData := range fieldsArray {
+				if legacy, ok := fieldData.(map[string]interface{}); ok {
+					fields = appendSchemaField(fields, legacy, cast.ToString(legacy["field_name"]), cast.ToString(legacy["field_type"]), i)
+				}
+			}
+			continue
+		}
+		if name, ok := fieldMap["field_name"].(string); ok {
+			fields = appendSchemaField(fields, fieldMap, name, cast.ToString(fieldMap["field_type"]), len(fields))
+			continue
+		}
+		fields = appendSchemaField(fields, fieldMap, cast.ToString(fieldMap["name_en"]), cast.ToString(fieldMap["data_type"]), cast.ToInt(fieldMap["order_num"]))
+	}
+
+	sort.SliceStable(fields, func(i, j int) bool {
+		if fields[i].order != fields[j].order {
+			return fields[i].order < fields[j].order
+		}
+		return fields[i].name < fields[j].name
+	})
+	return fields
+}
+
+// appendSchemaField 追加一个字段，未配置is_nullable时默认可为空
+func appendSchemaField(fields []schemaField, fieldMap map[string]interface{}, name, dataType string, order int) []schemaField {
+	if name == "" {
+		return fields
+	}
+	field := schemaField{
+		name:         name,
+		title:        cast.ToString(fieldMap["name_zh"]),
+		description:  cast.ToString(fieldMap["description"]),
+		dataType:     dataType,
+		nullable:     true,
+		primaryKey:   cast.ToBool(fieldMap["is_primary_key"]),
+		defaultValue: cast.ToString(fieldMap["default_value"]),
+		computed:     cast.ToString(fieldMap["expression"]) != "",
+		order:        order,
+	}
+	if field.description == "" {
+		field.description = cast.ToString(fieldMap["comment"])
+	}
+	if nullable, ok := fieldMap["is_nullable"].(bool); ok {
+		field.nullable = nullable
+	}
+	if field.primaryKey {
+		field.nullable = false
+	}
+	return append(fields, field)
+}
+
+// buildFieldSchema 字段类型到属性Schema
+func buildFieldSchema(field schemaField) *JSONSchema {
+	prop := &JSONSchema{Title: field.title, Description: field.description}
+	if field.title == field.name {
+		prop.Title = ""
+	}
+	if field.computed {
+		prop.ReadOnly = true
+		return prop
+	}
+
+	dataType := strings.ToLower(strings.TrimSpace(field.dataType))
+	baseType := dataType
+	if idx := strings.Index(baseType, "("); idx > 0 {
+		baseType = strings.TrimSpace(baseType[:idx])
+	}
+
+	switch baseType {
+	case "int", "integer", "int2", "int4", "int8", "bigint", "smallint", "serial", "bigserial":
+		prop.Type = []string{"integer", "string"}
+		prop.Pattern = integerPattern
+	case "numeric", "decimal", "real", "float", "float4", "float8", "double", "double precision":
+		prop.Type = []string{"number", "string"}
+		prop.Pattern = numberPattern
+	case "boolean", "bool":
+		prop.Type = []string{"boolean", "integer", "string"}
+		prop.Pattern = booleanPattern
+	case "timestamp", "timestamptz", "datetime", "timestamp with time zone", "timestamp without time zone":
+		prop.Type = []string{"string"}
+		prop.Format = "date-time"
+	case "date":
+		prop.Type = []string{"string"}
+		prop.Format = "date"
+	case "time":
+		prop.Type = []string{"string"}
+		prop.Format = "time"
+	case "uuid":
+		prop.Type = []string{"string"}
+		prop.Pattern = uuidPattern
+	case "json", "jsonb":
+		// JSON字段接受任意值，不可为空时列出除null外的全部类型
+		if !field.nullable {
+			prop.Type = []string{"object", "array", "string", "number", "boolean"}
+		}
+	default:
+		// 字符字段写入时其他标量会转为字符串
+		prop.Type = []string{"string", "number", "boolean"}
+		if match := varcharLengthPattern.FindStringSubmatch(dataType); match != nil {
+			maxLength, _ := strconv.Atoi(match[2])
+			prop.MaxLength = &maxLength
+		}
+	}
+
+	if field.nullable && len(prop.Type) > 0 {
+		prop.Type = append(prop.Type, "null")
+	}
+	return prop
+}
+
+// ValidateRows 按Schema校验数据行
+func (s *JSONSchema) ValidateRows(rows []map[string]interface{}) *PayloadValidationResult {
+	result := &PayloadValidationResult{Valid: true, RowsChecked: len(rows), Violations: []PayloadViolation{}}
+	for i, row := range rows {
+		violations := s.validateRow(i, row)
+		if len(violations) == 0 {
+			continue
+		}
+		result.Valid = false
+		result.InvalidRows++
+		result.ViolationCount += len(violations)
+		for _, violation := range violations {
+			if len(result.Violations) >= MaxPayloadViolations {
+				result.Truncated = true
+				break
+			}
+			result.Violations = append(result.Violations, violation)
+		}
+	}
+	return result
+}
+
+// validateRow 校验一行，先检查必填字段，再按属性顺序检查各字段的值
+func (s *JSONSchema) validateRow(index int, row map[string]interface{}) []PayloadViolation {
+	var violations []PayloadViolation
+	for _, name := range s.Required {
+		if _, exists := row[name]; !exists {
+			violations = append(violations, PayloadViolation{Row: index, Field: name, Keyword: "required", Message: "缺少必填字段"})
+		}
+	}
+
+	for _, name := range s.orderedProperties() {
+		value, exists := row[name]
+		if !exists {
+			continue
+		}
+		prop := s.Properties[name]
+		if prop.ReadOnly {
+			continue
+		}
+		if keyword, message := prop.validateValue(value); keyword != "" {
+			violations = append(violations, PayloadViolation{
+				Row:     index,
+				Field:   name,
+				Keyword: keyword,
+				Value:   payloadSample(value),
+				Message: message,
+			})
+		}
+	}
+	return violations
+}
+
+// orderedProperties 属性名，生成时记录了顺序则按配置顺序，否则按名称排序
+func (s *JSONSchema) orderedProperties() []string {
+	if len(s.propertyOrder) == len(s.Properties) {
+		return s.propertyOrder
+	}
+	names := make([]string, 0, len(s.Properties))
+	for name := range s.Properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// validateValue 校验单个值，返回不满足的关键字和说明，通过时关键字为空
+func (s *JSONSchema) validateValue(value interface{}) (string, string) {
+	valueType := jsonTypeOf(value)
+	if len(s.Type) > 0 && !containsJSONType(s.Type, valueType) {
+		if valueType == "null" {
+			return "type", "不能为空"
+		}
+		return "type", fmt.Sprintf("类型应为 %s，实际为 %s", strings.Join(s.Type, "/"), valueType)
+	}
+
+	str, isString := stringValue(value)
+	if !isString {
+		return "", ""
+	}
+	if s.Pattern != "" && !matchPattern(s.Pattern, str) {
+		if name, ok := patternNames[s.Pattern]; ok {
+			return "pattern", "值不是有效的" + name
+		}
+		return "pattern", "值不匹配模式 " + s.Pattern
+	}
+	if s.Format != "" && !matchFormat(s.Format, value, str) {
+		return "format", "值不是有效的 " + s.Format
+	}
+	if s.MaxLength != nil {
+		if length := utf8.RuneCountInString(str); length > *s.MaxLength {
+			return "maxLength", fmt.Sprintf("长度 %d 超过最大长度 %d", length, *s.MaxLength)
+		}
+	}
+	return "", ""
+}
+
+// jsonTypeOf 值对应的JSON类型，整数值的浮点数视为integer，时间视为字符串
+func jsonTypeOf(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string, []byte, time.Time:
+		return "string"
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return "integer"
+	case float32:
+		return floatJSONType(float64(v))
+	case float64:
+		return floatJSONType(v)
+	case json.Number:
+		if _, err := v.Int64(); err == nil {
+			return "integer"
+		}
+		return "number"
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		return "array"
+	default:
+		return "string"
+	}
+}
+
+// floatJSONType 没有小数部分的浮点数为integer
+func floatJSONType(v float64) string {
+	if !math.IsInf(v, 0) && !math.IsNaN(v) && v == math.Trunc(v) {
+		return "integer"
+	}
+	return "number"
+}
+
+// containsJSONType 值的类型是否在允许的类型中，number包含integer
+func containsJSONType(types []string, valueType string) bool {
+	for _, t := range types {
+		if t == valueType || (t == "number" && valueType == "integer") {
+			return true
+		}
+	}
+	return false
+}
+
+// stringValue 字符串形式的值，pattern、format和maxLength只约束字符串
+func stringValue(value interface{}) (string, bool) {
+	switch v := value.(type) {
+	case string:
+		return v, true
+	case []byte:
+		return string(v), true
+	case time.Time:
+		return v.Format(time.RFC3339Nano), true
+	}
+	return "", false
+}
+
+// matchPattern 按缓存的正则匹配，模式无效时视为通过
+func matchPattern(pattern, value string) bool {
+	if cached, ok := compiledPatterns.Load(pattern); ok {
+		return cached.(*regexp.Regexp).MatchString(value)
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return true
+	}
+	compiledPatterns.Store(pattern, re)
+	return re.MatchString(value)
+}
+
+// matchFormat 校验时间格式，接受写入时能解析的带时区和不带时区的格式
+func matchFormat(format string, value interface{}, str string) bool {
+	if _, ok := value.(time.Time); ok {
+		return true
+	}
+	str = strings.TrimSpace(str)
+	var layouts []string
+	switch format {
+	case "date-time", "date":
+		layouts = append(append(layouts, zonedTimeLayouts...), naiveTimeLayouts...)
+	case "time":
+		layouts = []string{"15:04:05.999999999", "15:04", "15:04:05Z07:00"}
+	default:
+		return true
+	}
+	for _, layout := range layouts {
+		if _, err := time.Parse(layout, str); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// payloadSample 违规值的文本形式，过长时截断
+func payloadSample(value interface{}) string {
+	var text string
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case map[string]interface{}, []interface{}:
+		data, _ := json.Marshal(v)
+		text = string(data)
+	default:
+		text = cast.ToString(v)
+	}
+	if utf8.RuneCountInString(text) > 100 {
+		return string([]rune(text)[:100]) + "..."
+	}
+	return text
+}
@@ -0,0 +1,107 @@
+/*
+ * @module service/utils/json_schema_test
+ * @description 接口JSON Schema生成和校验单元测试
+ * @architecture 测试层 - 纯函数测试，无外部依赖
+ * @documentReference .specify/memory/test_plan.md
+ * @stateFlow 输入参数 -> 函数调用 -> 输出验证
+ * @rules 覆盖字段类型到Schema的转换、必填字段、可转换的字符串值、时间格式、长度限制和违规条数上限
+ * @dependencies testing, testify
+ * @refs json_schema.go
+ */
+
+package utils
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// vehicleFieldsConfig 车辆接口的表字段配置，map遍历顺序不固定，按order_num排序
+var vehicleFieldsConfig = []interface{}{
+	map[string]interface{}{"name_en": "plate", "name_zh": "车牌号", "data_type": "varchar(8)", "is_primary_key": true, "order_num": float64(1)},
+	map[string]interface{}{"name_en": "seats", "data_type": "integer", "is_nullable": false, "order_num": float64(2)},
+	map[string]interface{}{"name_en": "price", "data_type": "numeric(10,2)", "order_num": float64(3)},
+	map[string]interface{}{"name_en": "electric", "data_type": "boolean", "order_num": float64(4)},
+	map[string]interface{}{"name_en": "registered_at", "data_type": "timestamp", "is_nullable": false, "default_value": "now()", "order_num": float64(5)},
+	map[string]interface{}{"name_en": "extra", "data_type": "jsonb", "order_num": float64(6)},
+	map[string]interface{}{"name_en": "seat_price", "data_type": "numeric", "expression": "price / seats", "order_num": float64(7)},
+}
+
+func TestBuildTableJSONSchema(t *testing.T) {
+	schema := BuildTableJSONSchema("车辆", vehicleFieldsConfig)
+
+	assert.Equal(t, JSONSchemaDraft, schema.Schema)
+	assert.Equal(t, []string{"object"}, schema.Type)
+	assert.Equal(t, []string{"plate", "seats"}, schema.Required, "有默认值和可为空的字段不是必填")
+	assert.Equal(t, []string{"plate", "seats", "price", "electric", "registered_at", "extra", "seat_price"}, schema.propertyOrder)
+
+	plate := schema.Properties["plate"]
+	assert.Equal(t, "车牌号", plate.Title)
+	assert.Equal(t, []string{"string", "number", "boolean"}, plate.Type, "主键不可为空")
+	require.NotNil(t, plate.MaxLength)
+	assert.Equal(t, 8, *plate.MaxLength)
+
+	assert.Equal(t, []string{"integer", "string"}, schema.Properties["seats"].Type)
+	assert.Equal(t, []string{"number", "string", "null"}, schema.Properties["price"].Type)
+	assert.Equal(t, "date-time", schema.Properties["registered_at"].Format)
+	assert.Empty(t, schema.Properties["extra"].Type, "可为空的JSON字段不限类型")
+	assert.True(t, schema.Properties["seat_price"].ReadOnly)
+
+	data, err := json.Marshal(schema)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"$schema":"https://json-schema.org/draft/2020-12/schema"`)
+	assert.Contains(t, string(data), `"additionalProperties":true`)
+	assert.NotContains(t, string(data), "propertyOrder")
+}
+
+func TestJSONSchemaValidateRows(t *testing.T) {
+	schema := BuildTableJSONSchema("车辆", vehicleFieldsConfig)
+
+	result := schema.ValidateRows([]map[string]interface{}{
+		{"plate": "京A12345", "seats": "5", "price": 12.5, "electric": "yes", "registered_at": "2024-01-02 03:04:05", "extra": map[string]interface{}{"a": 1}, "unknown": true},
+		{"plate": "京A12345", "seats": float64(4), "registered_at": time.Now(), "seat_price": "ignored"},
+		{"plate": "京A123456789", "seats": 4.5, "price": "12,5", "electric": "maybe", "registered_at": "yesterday"},
+		{"seats": nil, "price": nil},
+	})
+
+	assert.False(t, result.Valid)
+	assert.Equal(t, 4, result.RowsChecked)
+	assert.Equal(t, 2, result.InvalidRows)
+	assert.Equal(t, 7, result.ViolationCount)
+	assert.Equal(t, []PayloadViolation{
+		{Row: 2, Field: "plate", Keyword: "maxLength", Value: "京A123456789", Message: "长度 11 超过最大长度 8"},
+		{Row: 2, Field: "seats", Keyword: "type", Value: "4.5", Message: "类型应为 integer/string，实际为 number"},
+		{Row: 2, Field: "price", Keyword: "pattern", Value: "12,5", Message: "值不是有效的数值"},
+		{Row: 2, Field: "electric", Keyword: "pattern", Value: "maybe", Message: "值不是有效的布尔值"},
+		{Row: 2, Field: "registered_at", Keyword: "format", Value: "yesterday", Message: "值不是有效的 date-time"},
+		{Row: 3, Field: "plate", Keyword: "required", Message: "缺少必填字段"},
+		{Row: 3, Field: "seats", Keyword: "type", Message: "不能为空"},
+	}, result.Violations)
+	assert.Equal(t, "2行7处违规，第3行 plate: 长度 11 超过最大长度 8；第3行 seats: 类型应为 integer/string，实际为 number；第3行 price: 值不是有效的数值", result.Summary())
+}
+
+func TestJSONSchemaValidateRowsTruncated(t *testing.T) {
+	schema := BuildTableJSONSchema("", []interface{}{
+		map[string]interface{}{"fields": []interface{}{
+			map[string]interface{}{"field_name": "id", "field_type": "uuid", "is_nullable": false},
+		}},
+	})
+
+	rows := make([]map[string]interface{}, MaxPayloadViolations+5)
+	for i := range rows {
+		rows[i] = map[string]interface{}{"id": "not-a-uuid"}
+	}
+	result := schema.ValidateRows(rows)
+	assert.Equal(t, MaxPayloadViolations+5, result.ViolationCount)
+	assert.Len(t, result.Violations, MaxPayloadViolations)
+	assert.True(t, result.Truncated)
+
+	valid := schema.ValidateRows([]map[string]interface{}{{"id": "7c9e6679-7425-40de-944b-e07fc1f90ae7"}})
+	assert.True(t, valid.Valid)
+	assert.Empty(t, valid.Violations)
+	assert.Equal(t, "", valid.Summary())
+}
@@ -0,0 +1,98 @@
+/*
+ * @module service/utils/pinyin
+ * @description 汉字转拼音，用于字段中文名与拼音字段名（全拼或首字母缩写）的匹配
+ * @architecture 内置常用字表，覆盖业务字段名中的高频汉字，不引入外部拼音库
+ * @documentReference ai_docs/basic_library_process_impl.md
+ * @stateFlow 字符串 -> 逐字查表 -> 全拼/首字母
+ * @rules 多音字取字段名中最常见的读音（如"长度"的长、"重量"的重、"行政"的行）；字表未收录的汉字视为无法转换
+ * @dependencies unicode
+ * @refs service/basic_library/field_mapping_suggest.go
+ */
+
+package utils
+
+import (
+	"strings"
+	"unicode"
+)
+
+// pinyinSyllables 读音到汉字的对照表，每个汉字只出现一次
+var pinyinSyllables = map[string]string{
+	"a": "安", "ai": "爱", "an": "按案", "bai": "白百败", "ban": "办版班半", "bang": "帮",
+	"bao": "保报包", "bei": "备被北", "ben": "本", "bi": "比笔币", "bian": "编变边", "biao": "标表",
+	"bie": "别", "bing": "病并", "bo": "博", "bu": "部不补步布", "cai": "财采材", "can": "参",
+	"cao": "操", "ce": "测策册", "ceng": "层", "cha": "查差", "chan": "产", "chang": "长场厂常",
+	"che": "车", "chen": "陈", "cheng": "成城程称乘", "chi": "持", "chu": "出处初储除", "chuan": "传船",
+	"chuang": "创", "ci": "次词", "cun": "存村", "da": "大达", "dai": "代贷带", "dan": "单担",
+	"dang": "当档党", "dao": "到导道", "de": "得德", "deng": "等登", "di": "地第底", "dian": "电点店",
+	"diao": "调", "ding": "定订", "dong": "动东栋", "du": "度读", "duan": "段端断", "dui": "对队",
+	"duo": "多", "e": "额", "er": "二儿", "fa": "发法", "fan": "范返反", "fang": "方房放访",
+	"fei": "费非", "fen": "分份", "feng": "风封", "fou": "否", "fu": "服付负复附福父",
+	"gai": "改", "gan": "感", "gang": "岗港", "gao": "高告", "ge": "个格各", "gen": "根",
+	"geng": "更", "gong": "工公供功", "gou": "购构", "gu": "固股故", "gua": "挂", "guan": "管关官馆",
+	"gui": "规归", "guo": "国过果", "han": "含", "hang": "航", "hao": "号耗", "he": "合核和",
+	"hong": "红", "hou": "后", "hu": "户护互", "hua": "化划话", "huan": "环换", "hui": "会回汇",
+	"hun": "婚", "huo": "活货获", "ji": "机计级基记籍绩积集急剂击季", "jia": "家价加假",
+	"jian": "间件检建监简键见", "jiang": "奖", "jiao": "交教校", "jie": "接结节届街", "jin": "金进近禁",
+	"jing": "经警境径", "jiu": "就", "ju": "局居据具", "jue": "决", "jun": "军均", "kai": "开",
+	"kan": "看", "kao": "考", "ke": "可课客科", "kong": "空控", "kou": "口扣", "ku": "库",
+	"kuai": "快", "kuan": "款宽", "kui": "馈", "lai": "来", "lao": "老劳", "lei": "类",
+	"li": "理历利例里离力", "lian": "联连链", "liang": "量", "liao": "料", "lie": "列", "lin": "临",
+	"ling": "领龄", "liu": "流留", "lou": "楼", "lu": "录路", "lv": "率律", "lun": "论", "ma": "码",
+	"mai": "买卖", "man": "满", "mei": "每美", "men": "门", "mi": "密", "mian": "面免", "miao": "秒描",
+	"min": "民", "ming": "名明", "mo": "模默", "mu": "目母", "na": "纳", "nan": "男南", "nei": "内",
+	"neng": "能", "nian": "年", "nv": "女", "pai": "排派牌", "pei": "配", "pi": "批", "pian": "片",
+	"pin": "品频", "ping": "评平", "pu": "普", "qi": "期其企起器启", "qian": "签前钱", "qiang": "强",
+	"qing": "请情", "qiu": "求", "qu": "区取", "quan": "权全", "que": "确", "ren": "人认任",
+	"ri": "日", "rong": "容", "ru": "入", "se": "色", "shan": "删", "shang": "商上", "she": "设社",
+	"shen": "身审申", "sheng": "生省声", "shi": "时是事市实使式师识示始失视室湿", "shou": "收手首售",
+	"shu": "数属输书述束", "shuang": "双", "shui": "水税", "si": "司私", "song": "送", "su": "诉速",
+	"suo": "所索", "tai": "台态", "te": "特", "ti": "体提题", "tian": "天填", "tiao": "条",
+	"ting": "停", "tong": "通统同", "tou": "投", "tu": "图", "tui": "退", "wai": "外", "wan": "完万",
+	"wang": "网", "wei": "位维未委纬唯", "wen": "文温", "wu": "物务无", "xi": "系细息", "xia": "下",
+	"xian": "现县限", "xiang": "项详相箱乡向响", "xiao": "小销效消", "xie": "写协", "xin": "信新薪",
+	"xing": "性姓型行形星", "xiu": "修", "xu": "序需续许", "xuan": "选", "xue": "学血", "xun": "训",
+	"ya": "压", "yan": "验研", "yang": "样", "yao": "要药", "ye": "业页", "yi": "医一已意易",
+	"yin": "银因引姻", "ying": "应营", "yong": "用", "you": "有邮", "yu": "预余域语雨", "yuan": "员原院元源",
+	"yue": "月约阅", "yun": "运", "zai": "在", "zao": "造", "ze": "责", "zeng": "增", "zhan": "站",
+	"zhang": "账章", "zhao": "照", "zhe": "者折", "zhen": "真镇诊", "zheng": "证政正整", "zhi": "值制职支指质址知置志执",
+	"zhong": "中种重终", "zhou": "周", "zhu": "主住注", "zhuan": "专转", "zhuang": "状装", "zhun": "准",
+	"zi": "资子字自", "zong": "总", "zu": "组族租", "zui": "最", "zuo": "作坐座",
+}
+
+// pinyinTable 汉字到读音的索引
+var pinyinTable = buildPinyinTable()
+
+func buildPinyinTable() map[rune]string {
+	table := make(map[rune]string)
+	for syllable, chars := range pinyinSyllables {
+		for _, ch := range chars {
+			table[ch] = syllable
+		}
+	}
+	return table
+}
+
+// ToPinyin 将字符串转换为小写全拼和首字母缩写，字母和数字原样保留，其余符号忽略；
+// 包含字表未收录的汉字时ok为false
+func ToPinyin(s string) (full string, initials string, ok bool) {
+	var fullBuilder, initialBuilder strings.Builder
+	ok = true
+	for _, ch := range s {
+		switch {
+		case unicode.Is(unicode.Han, ch):
+			syllable, found := pinyinTable[ch]
+			if !found {
+				ok = false
+				continue
+			}
+			fullBuilder.WriteString(syllable)
+			initialBuilder.WriteByte(syllable[0])
+		case ch < unicode.MaxASCII && (unicode.IsLetter(ch) || unicode.IsDigit(ch)):
+			lower := unicode.ToLower(ch)
+			fullBuilder.WriteRune(lower)
+			initialBuilder.WriteRune(lower)
+		}
+	}
+	return fullBuilder.String(), initialBuilder.String(), ok
+}
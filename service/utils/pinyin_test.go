@@ -0,0 +1,33 @@
+/*
+ * @module service/utils/pinyin_test
+ * @description 汉字转拼音单元测试
+ * @architecture 测试层 - 纯函数测试，无外部依赖
+ * @documentReference .specify/memory/test_plan.md
+ * @stateFlow 输入参数 -> 函数调用 -> 输出验证
+ * @rules 覆盖全拼、首字母、混合字母数字和未收录汉字
+ * @dependencies testing, testify
+ * @refs pinyin.go
+ */
+
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestToPinyin(t *testing.T) {
+	full, initials, ok := ToPinyin("身份证号")
+	assert.True(t, ok)
+	assert.Equal(t, "shenfenzhenghao", full)
+	assert.Equal(t, "sfzh", initials)
+
+	full, initials, ok = ToPinyin("IP地址")
+	assert.True(t, ok)
+	assert.Equal(t, "ipdizhi", full)
+	assert.Equal(t, "ipdz", initials)
+
+	_, _, ok = ToPinyin("龘")
+	assert.False(t, ok, "未收录的汉字无法转换")
+}
@@ -0,0 +1,272 @@
+/*
+ * @module service/utils/row_stream
+ * @description 按Accept请求头协商数据行的输出格式（JSON、CSV、NDJSON），并逐行写出CSV或NDJSON，逐个读取JSON数组元素，供数据查看、预览和数据共享接口流式返回大结果集
+ * @architecture 工具函数模式，只依赖标准库
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow Accept请求头 -> 按q值选择格式 -> 写表头（CSV） -> 逐行写出 -> 按批刷新 -> 结束时刷新
+ * @rules
+ *   - 只识别text/csv、application/x-ndjson和application/json，通配符和未识别的类型按JSON处理，q=0的类型不可接受
+ *   - CSV首行为列名，未指定列时取第一行的字段按名称排序；后续行缺少的列写空字符串，多出的字段忽略
+ *   - CSV单元格：空值为空字符串，时间为RFC3339，对象和数组为JSON，数字保持原始精度；不加BOM，不转义公式，原样提供给程序消费
+ *   - NDJSON每行一个JSON对象，以换行分隔
+ * @dependencies encoding/csv, encoding/json
+ * @refs api/controllers/row_format.go
+ */
+
+package utils
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RowFormat 数据行输出格式
+type RowFormat string
+
+// 数据行输出格式
+const (
+	RowFormatJSON   RowFormat = "json"
+	RowFormatCSV    RowFormat = "csv"
+	RowFormatNDJSON RowFormat = "ndjson"
+)
+
+// 数据行输出格式的媒体类型
+const (
+	MediaTypeJSON   = "application/json"
+	MediaTypeCSV    = "text/csv"
+	MediaTypeNDJSON = "application/x-ndjson"
+)
+
+// rowFormatMediaTypes 媒体类型对应的输出格式
+var rowFormatMediaTypes = map[string]RowFormat{
+	MediaTypeJSON:   RowFormatJSON,
+	MediaTypeCSV:    RowFormatCSV,
+	MediaTypeNDJSON: RowFormatNDJSON,
+}
+
+// NegotiateRowFormat 按Accept请求头选择输出格式，q值最高的已识别类型优先，同q值按出现顺序
+func NegotiateRowFormat(accept string) RowFormat {
+	best, bestQ := RowFormatJSON, 0.0
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		format, ok := rowFormatMediaTypes[mediaType]
+		if !ok {
+			continue
+		}
+		q := 1.0
+		if value, exists := params["q"]; exists {
+			if q, err = strconv.ParseFloat(value, 64); err != nil {
+				continue
+			}
+		}
+		if q > bestQ {
+			best, bestQ = format, q
+		}
+	}
+	return best
+}
+
+// ContentType 输出格式的Content-Type响应头
+func (f RowFormat) ContentType() string {
+	switch f {
+	case RowFormatCSV:
+		return MediaTypeCSV + "; charset=utf-8"
+	case RowFormatNDJSON:
+		return MediaTypeNDJSON
+	default:
+		return MediaTypeJSON + "; charset=utf-8"
+	}
+}
+
+// RowWriter 逐行写出数据
+type RowWriter interface {
+	// WriteRow 写出一行
+	WriteRow(row map[string]interface{}) error
+	// Flush 把缓冲的数据写到底层Writer
+	Flush() error
+}
+
+// NewRowWriter 创建CSV或NDJSON行写入器，columns为CSV的列顺序，为空时取第一行的字段
+func NewRowWriter(w io.Writer, format RowFormat, columns []string) (RowWriter, error) {
+	switch format {
+	case RowFormatCSV:
+		return &csvRowWriter{writer: csv.NewWriter(w), columns: columns}, nil
+	case RowFormatNDJSON:
+		return &ndjsonRowWriter{encoder: json.NewEncoder(w)}, nil
+	default:
+		return nil, fmt.Errorf("不支持逐行写出的格式: %s", format)
+	}
+}
+
+// csvRowWriter CSV行写入器
+type csvRowWriter struct {
+	writer        *csv.Writer
+	columns       []string
+	headerWritten bool
+}
+
+// WriteRow 写出一行，首次写出前先写列名
+func (cw *csvRowWriter) WriteRow(row map[string]interface{}) error {
+	if !cw.headerWritten {
+		if len(cw.columns) == 0 {
+			cw.columns = SortedKeys(row)
+		}
+		if err := cw.writeHeader(); err != nil {
+			return err
+		}
+	}
+	record := make([]string, len(cw.columns))
+	for i, column := range cw.columns {
+		record[i] = CSVCellText(row[column])
+	}
+	return cw.writer.Write(record)
+}
+
+// writeHeader 写出列名，结果为空时也写出以便调用方识别列
+func (cw *csvRowWriter) writeHeader() error {
+	if cw.headerWritten {
+		return nil
+	}
+	cw.headerWritten = true
+	if len(cw.columns) == 0 {
+		return nil
+	}
+	return cw.writer.Write(cw.columns)
+}
+
+// Flush 刷新缓冲
+func (cw *csvRowWriter) Flush() error {
+	if err := cw.writeHeader(); err != nil {
+		return err
+	}
+	cw.writer.Flush()
+	return cw.writer.Error()
+}
+
+// ndjsonRowWriter NDJSON行写入器
+type ndjsonRowWriter struct {
+	encoder *json.Encoder
+}
+
+// WriteRow 写出一行JSON对象
+func (nw *ndjsonRowWriter) WriteRow(row map[string]interface{}) error {
+	return nw.encoder.Encode(row)
+}
+
+// Flush json.Encoder不缓冲，无需刷新
+func (nw *ndjsonRowWriter) Flush() error {
+	return nil
+}
+
+// CSVCellText CSV单元格文本
+func CSVCellText(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case []byte:
+		return string(v)
+	case json.Number:
+		return v.String()
+	case bool:
+		return strconv.FormatBool(v)
+	case time.Time:
+		return v.Format(time.RFC3339Nano)
+	case *time.Time:
+		if v == nil {
+			return ""
+		}
+		return v.Format(time.RFC3339Nano)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case float32:
+		return strconv.FormatFloat(float64(v), 'f', -1, 32)
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return fmt.Sprint(v)
+	case fmt.Stringer:
+		return v.String()
+	default:
+		content, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprint(v)
+		}
+		return string(content)
+	}
+}
+
+// SortedKeys 按名称排序的字段名
+func SortedKeys(row map[string]interface{}) []string {
+	keys := make([]string, 0, len(row))
+	for key := range row {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// ErrNotJSONArray 读取的内容不是JSON数组
+var ErrNotJSONArray = errors.New("内容不是JSON数组")
+
+// StreamJSONArray 逐个读取JSON数组的元素，不把整个数组读入内存；fn返回错误时停止读取
+func StreamJSONArray(r io.Reader, fn func(element json.RawMessage) error) error {
+	decoder := json.NewDecoder(r)
+	token, err := decoder.Token()
+	if err != nil {
+		return fmt.Errorf("读取JSON数组失败: %w", err)
+	}
+	if delim, ok := token.(json.Delim); !ok || delim != '[' {
+		return ErrNotJSONArray
+	}
+	for decoder.More() {
+		var element json.RawMessage
+		if err := decoder.Decode(&element); err != nil {
+			return fmt.Errorf("读取JSON数组元素失败: %w", err)
+		}
+		if err := fn(element); err != nil {
+			return err
+		}
+	}
+	if _, err := decoder.Token(); err != nil {
+		return fmt.Errorf("读取JSON数组失败: %w", err)
+	}
+	return nil
+}
+
+// JSONObjectKeys 按出现顺序返回JSON对象的字段名，用于保持CSV列与查询返回的字段顺序一致
+func JSONObjectKeys(object json.RawMessage) ([]string, error) {
+	decoder := json.NewDecoder(bytes.NewReader(object))
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, err
+	}
+	if delim, ok := token.(json.Delim); !ok || delim != '{' {
+		return nil, fmt.Errorf("内容不是JSON对象")
+	}
+	var keys []string
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, err
+		}
+		key, _ := token.(string)
+		keys = append(keys, key)
+		var skip json.RawMessage
+		if err := decoder.Decode(&skip); err != nil {
+			return nil, err
+		}
+	}
+	return keys, nil
+}
@@ -0,0 +1,107 @@
+/*
+ * @module service/utils/row_stream_test
+ * @description 数据行输出格式单元测试
+ * @architecture 测试层 - 纯函数测试，无外部依赖
+ * @documentReference .specify/memory/test_plan.md
+ * @stateFlow 输入参数 -> 函数调用 -> 输出验证
+ * @rules 覆盖Accept协商、CSV列顺序和单元格文本、NDJSON逐行输出、逐个读取JSON数组元素和对象字段顺序
+ * @dependencies testing, testify
+ * @refs row_stream.go
+ */
+
+package utils
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNegotiateRowFormat(t *testing.T) {
+	cases := map[string]RowFormat{
+		"":                                      RowFormatJSON,
+		"*/*":                                   RowFormatJSON,
+		"text/csv":                              RowFormatCSV,
+		"application/x-ndjson":                  RowFormatNDJSON,
+		"Text/CSV; charset=utf-8":               RowFormatCSV,
+		"application/json, text/csv":            RowFormatJSON,
+		"application/json;q=0.5, text/csv":      RowFormatCSV,
+		"text/html, application/x-ndjson;q=0.8": RowFormatNDJSON,
+		"text/csv;q=0":                          RowFormatJSON,
+		"text/csv;q=abc, application/x-ndjson":  RowFormatNDJSON,
+	}
+	for accept, expected := range cases {
+		assert.Equal(t, expected, NegotiateRowFormat(accept), accept)
+	}
+	assert.Equal(t, "text/csv; charset=utf-8", RowFormatCSV.ContentType())
+	assert.Equal(t, "application/x-ndjson", RowFormatNDJSON.ContentType())
+}
+
+func TestCSVRowWriter(t *testing.T) {
+	var buf bytes.Buffer
+	writer, err := NewRowWriter(&buf, RowFormatCSV, nil)
+	require.NoError(t, err)
+
+	at := time.Date(2026, 10, 16, 8, 30, 0, 0, time.UTC)
+	require.NoError(t, writer.WriteRow(map[string]interface{}{
+		"name": "张三, \"Z\"", "age": json.Number("12345678901234567890"), "score": 9.5,
+		"active": true, "joined": at, "tags": []interface{}{"a", "b"}, "note": nil,
+	}))
+	require.NoError(t, writer.WriteRow(map[string]interface{}{"name": "李四", "extra": "ignored"}))
+	require.NoError(t, writer.Flush())
+
+	assert.Equal(t, "active,age,joined,name,note,score,tags\n"+
+		"true,12345678901234567890,2026-10-16T08:30:00Z,\"张三, \"\"Z\"\"\",,9.5,\"[\"\"a\"\",\"\"b\"\"]\"\n"+
+		",,,李四,,,\n", buf.String(), "未指定列时按第一行的字段名排序，后续行缺少的列为空、多出的字段忽略")
+
+	buf.Reset()
+	writer, err = NewRowWriter(&buf, RowFormatCSV, []string{"id", "name"})
+	require.NoError(t, err)
+	require.NoError(t, writer.Flush())
+	assert.Equal(t, "id,name\n", buf.String(), "没有数据行时也写出指定的列名")
+}
+
+func TestNDJSONRowWriter(t *testing.T) {
+	var buf bytes.Buffer
+	writer, err := NewRowWriter(&buf, RowFormatNDJSON, nil)
+	require.NoError(t, err)
+	require.NoError(t, writer.WriteRow(map[string]interface{}{"id": 1, "name": "a"}))
+	require.NoError(t, writer.WriteRow(map[string]interface{}{"id": 2}))
+	require.NoError(t, writer.Flush())
+	assert.Equal(t, "{\"id\":1,\"name\":\"a\"}\n{\"id\":2}\n", buf.String())
+
+	_, err = NewRowWriter(&buf, RowFormatJSON, nil)
+	assert.Error(t, err)
+}
+
+func TestStreamJSONArray(t *testing.T) {
+	var elements []string
+	err := StreamJSONArray(strings.NewReader(`[{"b":1,"a":{"x":[1,2]}}, {"b":2}]`), func(element json.RawMessage) error {
+		elements = append(elements, string(element))
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{`{"b":1,"a":{"x":[1,2]}}`, `{"b":2}`}, elements)
+
+	keys, err := JSONObjectKeys(json.RawMessage(elements[0]))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"b", "a"}, keys, "按出现顺序返回字段名")
+
+	assert.ErrorIs(t, StreamJSONArray(strings.NewReader(`{"message":"error"}`), func(json.RawMessage) error { return nil }), ErrNotJSONArray)
+	assert.Error(t, StreamJSONArray(strings.NewReader(`[{"a":1},`), func(json.RawMessage) error { return nil }), "截断的数组")
+
+	stop := errors.New("stop")
+	count := 0
+	err = StreamJSONArray(strings.NewReader(`[1,2,3]`), func(json.RawMessage) error {
+		count++
+		return stop
+	})
+	assert.ErrorIs(t, err, stop)
+	assert.Equal(t, 1, count, "回调返回错误时停止读取")
+}
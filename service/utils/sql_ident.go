@@ -0,0 +1,55 @@
+/*
+ * @module service/utils/sql_ident
+ * @description SQL标识符引用，拼接动态SQL时为schema、表名和列名加引号，PostgreSQL使用双引号、ClickHouse使用反引号
+ * @architecture 工具函数模式
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow 标识符 -> 按方言转义引用字符 -> 加引号
+ * @rules 标识符中的引用字符转义为两个引用字符，避免用户配置的名称截断引用；未指定方言的函数按PostgreSQL处理
+ * @dependencies strings
+ * @refs service/basic_library/scd_service.go, service/database/timeseries.go, service/interface_executor/bulk_insert.go, service/clickhouse/ddl.go
+ */
+
+package utils
+
+import "strings"
+
+// Dialect SQL方言，决定标识符的引用字符
+type Dialect int
+
+const (
+	DialectPostgres Dialect = iota
+	DialectClickHouse
+)
+
+// QuoteIdentFor 按方言引用标识符，标识符中的引用字符转义为两个引用字符
+func QuoteIdentFor(dialect Dialect, name string) string {
+	quote := `"`
+	if dialect == DialectClickHouse {
+		quote = "`"
+	}
+	return quote + strings.ReplaceAll(name, quote, quote+quote) + quote
+}
+
+// QuoteIdent 用双引号引用PostgreSQL标识符，标识符中的双引号转义为两个双引号
+func QuoteIdent(name string) string {
+	return QuoteIdentFor(DialectPostgres, name)
+}
+
+// QuoteTable 引用schema.table形式的PostgreSQL表名
+func QuoteTable(schema, table string) string {
+	return QuoteTableFor(DialectPostgres, schema, table)
+}
+
+// QuoteTableFor 按方言引用schema.table形式的表名，ClickHouse中为database.table
+func QuoteTableFor(dialect Dialect, schema, table string) string {
+	return QuoteIdentFor(dialect, schema) + "." + QuoteIdentFor(dialect, table)
+}
+
+// QuoteIdentList 引用多个列名并以逗号连接
+func QuoteIdentList(columns []string) string {
+	quoted := make([]string, len(columns))
+	for i, column := range columns {
+		quoted[i] = QuoteIdent(column)
+	}
+	return strings.Join(quoted, ", ")
+}
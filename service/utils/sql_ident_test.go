@@ -0,0 +1,35 @@
+/*
+ * @module service/utils/sql_ident_test
+ * @description SQL标识符引用单元测试
+ * @architecture 测试层 - 纯函数测试，无外部依赖
+ * @documentReference .specify/memory/test_plan.md
+ * @stateFlow 输入参数 -> 函数调用 -> 输出验证
+ * @rules 覆盖普通标识符、内嵌双引号、schema.table、列名列表和ClickHouse反引号引用
+ * @dependencies testing, testify
+ * @refs sql_ident.go
+ */
+
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQuoteIdent(t *testing.T) {
+	assert.Equal(t, `"plate_no"`, QuoteIdent("plate_no"))
+	assert.Equal(t, `"a""; DROP TABLE t; --"`, QuoteIdent(`a"; DROP TABLE t; --`), "内嵌双引号转义")
+	assert.Equal(t, `""`, QuoteIdent(""))
+	assert.Equal(t, `"lib_parking"."vehicle ""pass"""`, QuoteTable("lib_parking", `vehicle "pass"`))
+	assert.Equal(t, `"id", "x""y"`, QuoteIdentList([]string{"id", `x"y`}))
+	assert.Equal(t, "", QuoteIdentList(nil))
+}
+
+func TestQuoteIdentFor(t *testing.T) {
+	assert.Equal(t, `"plate_no"`, QuoteIdentFor(DialectPostgres, "plate_no"))
+	assert.Equal(t, "`plate_no`", QuoteIdentFor(DialectClickHouse, "plate_no"))
+	assert.Equal(t, "`a``; DROP TABLE t; --`", QuoteIdentFor(DialectClickHouse, "a`; DROP TABLE t; --"), "内嵌反引号转义")
+	assert.Equal(t, "`say \"hi\"`", QuoteIdentFor(DialectClickHouse, `say "hi"`), "双引号在反引号内无需转义")
+	assert.Equal(t, "`lib_parking`.`vehicle_pass`", QuoteTableFor(DialectClickHouse, "lib_parking", "vehicle_pass"))
+}
@@ -0,0 +1,129 @@
+/*
+ * @module service/utils/timezone_policy
+ * @description 接口级时区规范化策略，解析带或不带时区的时间值并统一转换到目标时区
+ * @architecture 工具函数模式
+ * @documentReference ai_docs/interface_executor.md
+ * @stateFlow parseConfig.timezone -> 源时区/目标时区 -> 解析时间值(带时区按自身时区，不带时区按源时区) -> 转换到目标时区
+ * @rules
+ *   - 配置为 parseConfig.timezone: {"source": "Asia/Shanghai", "target": "UTC"}，未配置时不启用策略
+ *   - target 缺省为 UTC，source 缺省与 target 相同
+ *   - 数值和10位以上的纯数字字符串按Unix时间戳解析，超过1e12视为毫秒，时间戳与时区无关；time.Time和带偏移的字符串视为带时区
+ *   - 只有显式配置了 source 时才判断带时区的值是否与源时区一致
+ * @dependencies github.com/spf13/cast
+ * @refs service/interface_executor/timezone.go
+ */
+
+package utils
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cast"
+)
+
+// zonedTimeLayouts 带时区偏移的时间格式
+var zonedTimeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05.999999999Z07:00",
+	"2006-01-02T15:04:05.999999999Z0700",
+	"2006-01-02 15:04:05.999999999 -0700",
+	"2006-01-02 15:04:05.999999999 -07:00",
+	"2006-01-02 15:04:05.999999999-07",
+	time.RFC1123Z,
+}
+
+// naiveTimeLayouts 不带时区信息的时间格式
+var naiveTimeLayouts = []string{
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02 15:04:05.999999999",
+	"2006/01/02 15:04:05",
+	"2006-01-02",
+	"2006/01/02",
+}
+
+// TimezonePolicy 接口时区规范化策略
+type TimezonePolicy struct {
+	Source *time.Location // 不带时区信息的时间按此时区解释
+	Target *time.Location // 写入前统一转换到的时区
+	// sourceConfigured 是否显式配置了源时区，未配置时不检查带时区的值
+	sourceConfigured bool
+}
+
+// ParseTimezonePolicy 从parseConfig解析时区策略，未配置timezone时返回nil
+func ParseTimezonePolicy(parseConfig map[string]interface{}) (*TimezonePolicy, error) {
+	config, ok := parseConfig["timezone"].(map[string]interface{})
+	if !ok || len(config) == 0 {
+		return nil, nil
+	}
+
+	policy := &TimezonePolicy{Target: time.UTC}
+	if target := strings.TrimSpace(cast.ToString(config["target"])); target != "" {
+		loc, err := time.LoadLocation(target)
+		if err != nil {
+			return nil, fmt.Errorf("无效的目标时区 %s", target)
+		}
+		policy.Target = loc
+	}
+	policy.Source = policy.Target
+	if source := strings.TrimSpace(cast.ToString(config["source"])); source != "" {
+		loc, err := time.LoadLocation(source)
+		if err != nil {
+			return nil, fmt.Errorf("无效的源时区 %s", source)
+		}
+		policy.Source = loc
+		policy.sourceConfigured = true
+	}
+	return policy, nil
+}
+
+// ParseTime 解析时间值并转换到目标时区，mismatch表示值自带的时区与配置的源时区偏移不一致
+func (p *TimezonePolicy) ParseTime(value interface{}) (t time.Time, mismatch bool, err error) {
+	var zoned bool
+	switch v := value.(type) {
+	case time.Time:
+		t, zoned = v, true
+	case int, int32, int64, float32, float64:
+		t = unixTime(cast.ToInt64(v))
+	default:
+		str := strings.TrimSpace(cast.ToString(value))
+		t, zoned, err = p.parseTimeString(str)
+		if err != nil {
+			return time.Time{}, false, err
+		}
+	}
+
+	if zoned && p.sourceConfigured {
+		_, valueOffset := t.Zone()
+		_, sourceOffset := t.In(p.Source).Zone()
+		mismatch = valueOffset != sourceOffset
+	}
+	return t.In(p.Target), mismatch, nil
+}
+
+// parseTimeString 依次尝试时间戳、带时区和不带时区的格式，不带时区的按源时区解释，返回值是否自带时区偏移
+func (p *TimezonePolicy) parseTimeString(str string) (time.Time, bool, error) {
+	if len(str) >= 10 && strings.Trim(str, "0123456789") == "" {
+		return unixTime(cast.ToInt64(str)), false, nil
+	}
+	for _, layout := range zonedTimeLayouts {
+		if t, err := time.Parse(layout, str); err == nil {
+			return t, true, nil
+		}
+	}
+	for _, layout := range naiveTimeLayouts {
+		if t, err := time.ParseInLocation(layout, str, p.Source); err == nil {
+			return t, false, nil
+		}
+	}
+	return time.Time{}, false, fmt.Errorf("无法解析时间 %q", str)
+}
+
+// unixTime Unix时间戳转时间，超过1e12视为毫秒
+func unixTime(n int64) time.Time {
+	if n > 1e12 || n < -1e12 {
+		return time.UnixMilli(n)
+	}
+	return time.Unix(n, 0)
+}
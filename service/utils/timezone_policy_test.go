@@ -0,0 +1,68 @@
+/*
+ * @module service/utils/timezone_policy_test
+ * @description 接口时区规范化策略单元测试
+ * @architecture 测试层 - 纯函数测试，无外部依赖
+ * @documentReference .specify/memory/test_plan.md
+ * @stateFlow 输入参数 -> 函数调用 -> 输出验证
+ * @rules 覆盖策略解析、带时区和不带时区的值、时间戳以及源时区不一致判断
+ * @dependencies testing, testify
+ * @refs timezone_policy.go
+ */
+
+package utils
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTimezonePolicy(t *testing.T) {
+	policy, err := ParseTimezonePolicy(map[string]interface{}{})
+	require.NoError(t, err)
+	assert.Nil(t, policy, "未配置时不启用")
+
+	policy, err = ParseTimezonePolicy(map[string]interface{}{"timezone": map[string]interface{}{"source": "Asia/Shanghai"}})
+	require.NoError(t, err)
+	assert.Equal(t, "UTC", policy.Target.String(), "目标时区缺省为UTC")
+	assert.Equal(t, "Asia/Shanghai", policy.Source.String())
+
+	_, err = ParseTimezonePolicy(map[string]interface{}{"timezone": map[string]interface{}{"target": "Mars/Olympus"}})
+	assert.Error(t, err)
+}
+
+func TestTimezonePolicyParseTime(t *testing.T) {
+	policy, err := ParseTimezonePolicy(map[string]interface{}{
+		"timezone": map[string]interface{}{"source": "Asia/Shanghai", "target": "UTC"},
+	})
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name     string
+		value    interface{}
+		expected string
+		mismatch bool
+	}{
+		{"不带时区按源时区解释", "2024-03-01 08:00:00", "2024-03-01 00:00:00", false},
+		{"只有日期", "2024-03-01", "2024-02-29 16:00:00", false},
+		{"与源时区一致", "2024-03-01T08:00:00+08:00", "2024-03-01 00:00:00", false},
+		{"与源时区不一致", "2024-03-01T08:00:00Z", "2024-03-01 08:00:00", true},
+		{"秒级时间戳", float64(1709251200), "2024-03-01 00:00:00", false},
+		{"毫秒级时间戳字符串", "1709251200000", "2024-03-01 00:00:00", false},
+		{"time.Time", time.Date(2024, 3, 1, 8, 0, 0, 0, time.FixedZone("CST", 8*3600)), "2024-03-01 00:00:00", false},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			parsed, mismatch, err := policy.ParseTime(tc.value)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, parsed.Format("2006-01-02 15:04:05"))
+			assert.Equal(t, time.UTC, parsed.Location())
+			assert.Equal(t, tc.mismatch, mismatch)
+		})
+	}
+
+	_, _, err = policy.ParseTime("not a time")
+	assert.Error(t, err)
+}
@@ -0,0 +1,329 @@
+/*
+ * @module service/utils/xlsx
+ * @description 读写xlsx工作簿的单元格文本，用于从Excel台账批量导入目录和下载导入模板
+ * @architecture 工具函数模式，只依赖archive/zip和encoding/xml，不引入外部Excel库
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow 读取：zip -> workbook.xml工作表列表 -> 关系文件定位工作表 -> 共享字符串 -> 逐行单元格文本；写入：工作表 -> 内联字符串单元格 -> zip
+ * @rules
+ *   - 只读取单元格的值（共享字符串、内联字符串、公式结果、数字、布尔），忽略样式、合并单元格和批注
+ *   - 行号与Excel一致，XLSXSheet.Rows[i]为第i+1行，中间的空行保留为nil
+ *   - 单个部件解压后超过maxXLSXPartSize时报错，防止压缩炸弹
+ *   - 写入时所有单元格都写成内联字符串
+ * @dependencies archive/zip, encoding/xml
+ * @refs service/basic_library/catalog_import_service.go
+ */
+
+package utils
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// maxXLSXPartSize 工作簿中单个部件解压后的大小上限
+const maxXLSXPartSize = 64 << 20
+
+// ErrInvalidXLSX 文件不是有效的xlsx工作簿
+var ErrInvalidXLSX = errors.New("文件不是有效的xlsx工作簿")
+
+// XLSXSheet 工作表及其单元格文本
+type XLSXSheet struct {
+	Name string
+	Rows [][]string
+}
+
+type xlsxWorkbook struct {
+	Sheets []struct {
+		Name  string     `xml:"name,attr"`
+		Attrs []xml.Attr `xml:",any,attr"`
+	} `xml:"sheets>sheet"`
+}
+
+type xlsxRelationships struct {
+	Relationships []struct {
+		ID     string `xml:"Id,attr"`
+		Target string `xml:"Target,attr"`
+	} `xml:"Relationship"`
+}
+
+type xlsxText struct {
+	T string `xml:"t"`
+	R []struct {
+		T string `xml:"t"`
+	} `xml:"r"`
+}
+
+func (t xlsxText) String() string {
+	if len(t.R) == 0 {
+		return t.T
+	}
+	var builder strings.Builder
+	builder.WriteString(t.T)
+	for _, run := range t.R {
+		builder.WriteString(run.T)
+	}
+	return builder.String()
+}
+
+type xlsxWorksheet struct {
+	Rows []struct {
+		R     int `xml:"r,attr"`
+		Cells []struct {
+			R  string    `xml:"r,attr"`
+			T  string    `xml:"t,attr"`
+			V  string    `xml:"v"`
+			Is *xlsxText `xml:"is"`
+		} `xml:"c"`
+	} `xml:"sheetData>row"`
+}
+
+// ReadXLSX 读取xlsx工作簿中所有工作表的单元格文本，按工作簿中的顺序返回
+func ReadXLSX(data []byte) ([]XLSXSheet, error) {
+	reader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, ErrInvalidXLSX
+	}
+	files := make(map[string]*zip.File, len(reader.File))
+	for _, file := range reader.File {
+		files[strings.TrimPrefix(file.Name, "/")] = file
+	}
+
+	var workbook xlsxWorkbook
+	if err := decodeXLSXPart(files, "xl/workbook.xml", &workbook); err != nil {
+		return nil, err
+	}
+	var relationships xlsxRelationships
+	if err := decodeXLSXPart(files, "xl/_rels/workbook.xml.rels", &relationships); err != nil {
+		return nil, err
+	}
+	targets := make(map[string]string, len(relationships.Relationships))
+	for _, rel := range relationships.Relationships {
+		target := rel.Target
+		if strings.HasPrefix(target, "/") {
+			target = strings.TrimPrefix(target, "/")
+		} else {
+			target = path.Join("xl", target)
+		}
+		targets[rel.ID] = target
+	}
+
+	var sharedStrings []string
+	if _, ok := files["xl/sharedStrings.xml"]; ok {
+		var sst struct {
+			Items []xlsxText `xml:"si"`
+		}
+		if err := decodeXLSXPart(files, "xl/sharedStrings.xml", &sst); err != nil {
+			return nil, err
+		}
+		sharedStrings = make([]string, len(sst.Items))
+		for i, item := range sst.Items {
+			sharedStrings[i] = item.String()
+		}
+	}
+
+	sheets := make([]XLSXSheet, 0, len(workbook.Sheets))
+	for _, sheet := range workbook.Sheets {
+		var relID string
+		for _, attr := range sheet.Attrs {
+			if attr.Name.Local == "id" && attr.Name.Space != "" {
+				relID = attr.Value
+			}
+		}
+		target, ok := targets[relID]
+		if !ok {
+			return nil, fmt.Errorf("%w: 找不到工作表%s", ErrInvalidXLSX, sheet.Name)
+		}
+		var worksheet xlsxWorksheet
+		if err := decodeXLSXPart(files, target, &worksheet); err != nil {
+			return nil, err
+		}
+		rows, err := worksheetRows(&worksheet, sharedStrings)
+		if err != nil {
+			return nil, fmt.Errorf("%w: 工作表%s: %v", ErrInvalidXLSX, sheet.Name, err)
+		}
+		sheets = append(sheets, XLSXSheet{Name: sheet.Name, Rows: rows})
+	}
+	return sheets, nil
+}
+
+// decodeXLSXPart 解压并解析工作簿中的XML部件
+func decodeXLSXPart(files map[string]*zip.File, name string, v interface{}) error {
+	file, ok := files[name]
+	if !ok {
+		return fmt.Errorf("%w: 缺少%s", ErrInvalidXLSX, name)
+	}
+	rc, err := file.Open()
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidXLSX, err)
+	}
+	defer rc.Close()
+	content, err := io.ReadAll(io.LimitReader(rc, maxXLSXPartSize+1))
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidXLSX, err)
+	}
+	if len(content) > maxXLSXPartSize {
+		return fmt.Errorf("%w: %s超过大小上限", ErrInvalidXLSX, name)
+	}
+	if err := xml.Unmarshal(content, v); err != nil {
+		return fmt.Errorf("%w: 解析%s失败: %v", ErrInvalidXLSX, name, err)
+	}
+	return nil
+}
+
+// worksheetRows 将工作表转换为按行号排列的单元格文本
+func worksheetRows(worksheet *xlsxWorksheet, sharedStrings []string) ([][]string, error) {
+	var rows [][]string
+	for _, row := range worksheet.Rows {
+		rowNum := row.R
+		if rowNum == 0 {
+			rowNum = len(rows) + 1
+		}
+		if rowNum <= len(rows) {
+			return nil, fmt.Errorf("行号%d重复或乱序", rowNum)
+		}
+		for len(rows) < rowNum {
+			rows = append(rows, nil)
+		}
+
+		var values []string
+		for _, cell := range row.Cells {
+			col := len(values)
+			if cell.R != "" {
+				parsed, err := xlsxColumnIndex(cell.R)
+				if err != nil {
+					return nil, err
+				}
+				col = parsed
+			}
+			value := cell.V
+			switch cell.T {
+			case "s":
+				index, err := strconv.Atoi(strings.TrimSpace(cell.V))
+				if err != nil || index < 0 || index >= len(sharedStrings) {
+					return nil, fmt.Errorf("单元格%s的共享字符串索引无效", cell.R)
+				}
+				value = sharedStrings[index]
+			case "inlineStr":
+				value = ""
+				if cell.Is != nil {
+					value = cell.Is.String()
+				}
+			case "b":
+				value = "FALSE"
+				if cell.V == "1" {
+					value = "TRUE"
+				}
+			}
+			for len(values) <= col {
+				values = append(values, "")
+			}
+			values[col] = value
+		}
+		rows[rowNum-1] = values
+	}
+	return rows, nil
+}
+
+// xlsxColumnIndex 从单元格引用（如"AB12"）解析从0开始的列号
+func xlsxColumnIndex(ref string) (int, error) {
+	col := 0
+	letters := 0
+	for _, ch := range ref {
+		if ch < 'A' || ch > 'Z' {
+			break
+		}
+		col = col*26 + int(ch-'A'+1)
+		letters++
+	}
+	if letters == 0 || letters > 3 {
+		return 0, fmt.Errorf("单元格引用%s无效", ref)
+	}
+	return col - 1, nil
+}
+
+// xlsxColumnName 将从0开始的列号转换为列字母
+func xlsxColumnName(col int) string {
+	name := ""
+	for col++; col > 0; col = (col - 1) / 26 {
+		name = string(rune('A'+(col-1)%26)) + name
+	}
+	return name
+}
+
+// WriteXLSX 将工作表写成xlsx工作簿，单元格均为文本
+func WriteXLSX(sheets []XLSXSheet) ([]byte, error) {
+	var buf bytes.Buffer
+	writer := zip.NewWriter(&buf)
+	write := func(name, content string) error {
+		w, err := writer.Create(name)
+		if err != nil {
+			return err
+		}
+		_, err = io.WriteString(w, xml.Header+content)
+		return err
+	}
+
+	var contentTypes, workbookSheets, workbookRels strings.Builder
+	for i, sheet := range sheets {
+		fmt.Fprintf(&contentTypes, `<Override PartName="/xl/worksheets/sheet%d.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>`, i+1)
+		fmt.Fprintf(&workbookSheets, `<sheet name="%s" sheetId="%d" r:id="rId%d"/>`, xmlEscape(sheet.Name), i+1, i+1)
+		fmt.Fprintf(&workbookRels, `<Relationship Id="rId%d" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet%d.xml"/>`, i+1, i+1)
+	}
+
+	parts := []struct{ name, content string }{
+		{"[Content_Types].xml", `<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+			`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+			`<Default Extension="xml" ContentType="application/xml"/>` +
+			`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+			contentTypes.String() + `</Types>`},
+		{"_rels/.rels", `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+			`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+			`</Relationships>`},
+		{"xl/workbook.xml", `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+			`<sheets>` + workbookSheets.String() + `</sheets></workbook>`},
+		{"xl/_rels/workbook.xml.rels", `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+			workbookRels.String() + `</Relationships>`},
+	}
+	for _, part := range parts {
+		if err := write(part.name, part.content); err != nil {
+			return nil, err
+		}
+	}
+
+	for i, sheet := range sheets {
+		var data strings.Builder
+		for r, row := range sheet.Rows {
+			fmt.Fprintf(&data, `<row r="%d">`, r+1)
+			for c, value := range row {
+				if value == "" {
+					continue
+				}
+				fmt.Fprintf(&data, `<c r="%s%d" t="inlineStr"><is><t xml:space="preserve">%s</t></is></c>`, xlsxColumnName(c), r+1, xmlEscape(value))
+			}
+			data.WriteString(`</row>`)
+		}
+		content := `<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>` + data.String() + `</sheetData></worksheet>`
+		if err := write(fmt.Sprintf("xl/worksheets/sheet%d.xml", i+1), content); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// xmlEscape 转义XML文本
+func xmlEscape(s string) string {
+	var buf bytes.Buffer
+	xml.EscapeText(&buf, []byte(s))
+	return buf.String()
+}
@@ -0,0 +1,89 @@
+/*
+ * @module service/utils/xlsx_test
+ * @description xlsx读写单元测试，覆盖写入后读回、共享字符串和富文本、数字和布尔单元格、空行行号以及无效文件
+ * @architecture 测试层 - 纯函数测试，无外部依赖
+ * @documentReference .specify/memory/test_plan.md
+ * @stateFlow 构造工作簿 -> 读取 -> 验证单元格文本
+ * @rules 共享字符串工作簿按Excel保存的结构手工构造
+ * @dependencies testing, testify
+ * @refs xlsx.go
+ */
+
+package utils
+
+import (
+	"archive/zip"
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteAndReadXLSX(t *testing.T) {
+	content, err := WriteXLSX([]XLSXSheet{
+		{Name: "系统", Rows: [][]string{{"系统编码", "系统名称"}, {"parking", "停车<管理>&系统"}}},
+		{Name: "联系人", Rows: [][]string{{"姓名"}, nil, {"", "张三"}}},
+	})
+	require.NoError(t, err)
+
+	sheets, err := ReadXLSX(content)
+	require.NoError(t, err)
+	require.Len(t, sheets, 2)
+	assert.Equal(t, "系统", sheets[0].Name)
+	assert.Equal(t, [][]string{{"系统编码", "系统名称"}, {"parking", "停车<管理>&系统"}}, sheets[0].Rows)
+	assert.Equal(t, [][]string{{"姓名"}, nil, {"", "张三"}}, sheets[1].Rows, "空行和空单元格保留位置")
+}
+
+func TestReadXLSXSharedStrings(t *testing.T) {
+	parts := map[string]string{
+		"xl/workbook.xml": `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+			`<sheets><sheet name="数据项" sheetId="1" r:id="rId3"/></sheets></workbook>`,
+		"xl/_rels/workbook.xml.rels": `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+			`<Relationship Id="rId3" Target="/xl/worksheets/data.xml"/></Relationships>`,
+		"xl/sharedStrings.xml": `<sst xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">` +
+			`<si><t>名称</t></si><si><r><t>车辆</t></r><r><t>进出</t></r></si></sst>`,
+		"xl/worksheets/data.xml": `<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>` +
+			`<row r="2"><c r="A2" t="s"><v>0</v></c><c r="C2"><v>13800000000</v></c></row>` +
+			`<row r="4"><c r="A4" t="s"><v>1</v></c><c r="B4" t="b"><v>1</v></c><c r="C4" t="str"><v>公式结果</v></c></row>` +
+			`</sheetData></worksheet>`,
+	}
+	var buf bytes.Buffer
+	writer := zip.NewWriter(&buf)
+	for name, content := range parts {
+		w, err := writer.Create(name)
+		require.NoError(t, err)
+		_, err = w.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, writer.Close())
+
+	sheets, err := ReadXLSX(buf.Bytes())
+	require.NoError(t, err)
+	require.Len(t, sheets, 1)
+	assert.Equal(t, [][]string{nil, {"名称", "", "13800000000"}, nil, {"车辆进出", "TRUE", "公式结果"}}, sheets[0].Rows)
+}
+
+func TestReadXLSXInvalid(t *testing.T) {
+	_, err := ReadXLSX([]byte("系统编码,系统名称"))
+	assert.ErrorIs(t, err, ErrInvalidXLSX)
+
+	var buf bytes.Buffer
+	writer := zip.NewWriter(&buf)
+	_, err = writer.Create("word/document.xml")
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+	_, err = ReadXLSX(buf.Bytes())
+	assert.ErrorIs(t, err, ErrInvalidXLSX, "不是工作簿的zip文件")
+}
+
+func TestXLSXColumn(t *testing.T) {
+	for col, name := range map[int]string{0: "A", 25: "Z", 26: "AA", 701: "ZZ", 702: "AAA"} {
+		assert.Equal(t, name, xlsxColumnName(col))
+		parsed, err := xlsxColumnIndex(name + "12")
+		require.NoError(t, err)
+		assert.Equal(t, col, parsed)
+	}
+	_, err := xlsxColumnIndex("12")
+	assert.Error(t, err)
+}
@@ -0,0 +1,282 @@
+/*
+ * @module service/webhook/dispatcher
+ * @description Webhook投递器，定时和有新投递时领取到期的投递，按HMAC-SHA256签名推送到接收地址，失败时按指数退避重试
+ * @architecture 分层架构 - 业务服务层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow 到期的pending投递 -> 条件更新领取为delivering(次数+1，租约到期时间) -> POST请求体 -> 2xx为succeeded；否则未超过次数时pending并计算下次尝试时间，超过时failed
+ * @rules
+ *   - 签名为HMAC-SHA256(密钥, 时间戳 + "." + 请求体)的十六进制，以sha256=前缀放在X-Datahub-Signature中，接收方应校验签名并拒绝时间戳过旧的请求
+ *   - 领取时按尝试次数做条件更新，多实例部署时同一投递只被一个实例领取；实例在投递中退出时，租约到期后由其他实例重新领取
+ *   - 第n次失败后等待retryBase*2^(n-1)，不超过retryMax；响应体截断保存
+ *   - Webhook被停用或删除后，未完成的投递记为失败
+ * @dependencies net/http, crypto/hmac, gorm.io/gorm
+ * @refs service/webhook/webhook_service.go, service/models/webhook.go
+ */
+
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"datahub-service/service/models"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// 推送请求头
+const (
+	SignatureHeader = "X-Datahub-Signature"
+	TimestampHeader = "X-Datahub-Timestamp"
+	EventHeader     = "X-Datahub-Event"
+	EventIDHeader   = "X-Datahub-Event-ID"
+	DeliveryHeader  = "X-Datahub-Delivery"
+)
+
+const (
+	defaultMaxAttempts  = 6
+	defaultRetryBase    = 30 * time.Second
+	defaultRetryMax     = time.Hour
+	defaultPollInterval = 10 * time.Second
+	// deliveryLease 投递中的租约，超过后视为实例已退出，可被重新领取
+	deliveryLease = 2 * time.Minute
+	// requestTimeout 单次推送的超时时间
+	requestTimeout = 10 * time.Second
+	// dispatchBatchSize 每轮领取的投递数量
+	dispatchBatchSize = 50
+	// dispatchConcurrency 同时进行的推送数量
+	dispatchConcurrency = 5
+	// maxResponseBody 保存的响应体长度上限
+	maxResponseBody = 2048
+)
+
+// Sign 计算推送签名
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func (s *Service) runDispatcher() {
+	defer s.wg.Done()
+	ticker := time.NewTicker(s.pollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.ctx.Done():
+			return
+		case <-ticker.C:
+		case <-s.wake:
+		}
+		if _, err := s.DispatchDue(s.ctx); err != nil {
+			slog.Error("Webhook投递失败", "error", err)
+		}
+	}
+}
+
+// notify 唤醒投递器
+func (s *Service) notify() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// DispatchDue 领取并推送到期的投递，返回领取的数量
+func (s *Service) DispatchDue(ctx context.Context) (int, error) {
+	now := s.now()
+	var due []models.WebhookDelivery
+	err := s.db.WithContext(ctx).
+		Where("status IN ? AND next_attempt_at <= ?", []string{models.WebhookDeliveryPending, models.WebhookDeliveryDelivering}, now).
+		Order("next_attempt_at").Limit(dispatchBatchSize).
+		Find(&due).Error
+	if err != nil {
+		return 0, fmt.Errorf("查询到期投递失败: %w", err)
+	}
+	if len(due) == 0 {
+		return 0, nil
+	}
+
+	endpointIDs := make([]string, 0, len(due))
+	for _, delivery := range due {
+		endpointIDs = append(endpointIDs, delivery.EndpointID)
+	}
+	var endpoints []models.WebhookEndpoint
+	if err := s.db.WithContext(ctx).Where("id IN ?", endpointIDs).Find(&endpoints).Error; err != nil {
+		return 0, fmt.Errorf("获取Webhook失败: %w", err)
+	}
+	byID := make(map[string]*models.WebhookEndpoint, len(endpoints))
+	for i := range endpoints {
+		byID[endpoints[i].ID] = &endpoints[i]
+	}
+
+	var wg sync.WaitGroup
+	slots := make(chan struct{}, dispatchConcurrency)
+	claimed := 0
+	for i := range due {
+		delivery := &due[i]
+		endpoint := byID[delivery.EndpointID]
+		if endpoint == nil || !endpoint.IsEnabled {
+			s.abandon(ctx, delivery, "Webhook已删除或停用")
+			continue
+		}
+		if delivery.Attempts >= s.maxAttempts {
+			// 投递中的实例已退出且次数已用完
+			s.abandon(ctx, delivery, "投递超时")
+			continue
+		}
+		if !s.claim(ctx, delivery) {
+			continue
+		}
+		claimed++
+
+		slots <- struct{}{}
+		wg.Add(1)
+		go func() {
+			defer func() {
+				<-slots
+				wg.Done()
+			}()
+			s.attempt(ctx, endpoint, delivery, false)
+		}()
+	}
+	wg.Wait()
+	return claimed, nil
+}
+
+// claim 领取投递，尝试次数加一并设置租约；已被其他实例领取时返回false
+func (s *Service) claim(ctx context.Context, delivery *models.WebhookDelivery) bool {
+	now := s.now()
+	result := s.db.WithContext(ctx).Model(&models.WebhookDelivery{}).
+		Where("id = ? AND attempts = ? AND status IN ? AND next_attempt_at <= ?", delivery.ID, delivery.Attempts,
+			[]string{models.WebhookDeliveryPending, models.WebhookDeliveryDelivering}, now).
+		Updates(map[string]interface{}{
+			"status":          models.WebhookDeliveryDelivering,
+			"attempts":        gorm.Expr("attempts + 1"),
+			"next_attempt_at": now.Add(s.lease),
+			"updated_at":      now,
+		})
+	if result.Error != nil {
+		slog.Error("领取Webhook投递失败", "delivery_id", delivery.ID, "error", result.Error)
+		return false
+	}
+	if result.RowsAffected == 0 {
+		return false
+	}
+	delivery.Status = models.WebhookDeliveryDelivering
+	delivery.Attempts++
+	return true
+}
+
+// abandon 把未完成的投递记为失败
+func (s *Service) abandon(ctx context.Context, delivery *models.WebhookDelivery, reason string) {
+	err := s.db.WithContext(ctx).Model(&models.WebhookDelivery{}).
+		Where("id = ? AND status IN ?", delivery.ID, []string{models.WebhookDeliveryPending, models.WebhookDeliveryDelivering}).
+		Updates(map[string]interface{}{
+			"status":     models.WebhookDeliveryFailed,
+			"error":      reason,
+			"updated_at": s.now(),
+		}).Error
+	if err != nil {
+		slog.Error("更新Webhook投递失败", "delivery_id", delivery.ID, "error", err)
+	}
+}
+
+// attempt 推送一次并保存结果；final为true时失败不再重试
+func (s *Service) attempt(ctx context.Context, endpoint *models.WebhookEndpoint, delivery *models.WebhookDelivery, final bool) {
+	startedAt := s.now()
+	statusCode, responseBody, sendErr := s.post(ctx, endpoint, delivery)
+	finishedAt := s.now()
+
+	delivery.LastAttemptAt = &startedAt
+	delivery.ResponseStatus = statusCode
+	delivery.ResponseBody = responseBody
+	delivery.DurationMs = finishedAt.Sub(startedAt).Milliseconds()
+	delivery.Error = ""
+	switch {
+	case sendErr == nil:
+		delivery.Status = models.WebhookDeliverySucceeded
+		delivery.DeliveredAt = &finishedAt
+	case final || delivery.Attempts >= s.maxAttempts:
+		delivery.Status = models.WebhookDeliveryFailed
+		delivery.Error = sendErr.Error()
+	default:
+		delivery.Status = models.WebhookDeliveryPending
+		delivery.Error = sendErr.Error()
+		delivery.NextAttemptAt = finishedAt.Add(s.backoff(delivery.Attempts))
+	}
+	if sendErr != nil {
+		slog.Warn("Webhook推送失败", "webhook", endpoint.Name, "delivery_id", delivery.ID, "event_type", delivery.EventType,
+			"attempt", delivery.Attempts, "status", delivery.Status, "error", sendErr)
+	}
+
+	// 推送结果不受请求取消影响
+	err := s.db.WithContext(context.WithoutCancel(ctx)).Model(&models.WebhookDelivery{}).
+		Where("id = ? AND status = ?", delivery.ID, models.WebhookDeliveryDelivering).
+		Updates(map[string]interface{}{
+			"status":          delivery.Status,
+			"next_attempt_at": delivery.NextAttemptAt,
+			"last_attempt_at": delivery.LastAttemptAt,
+			"response_status": delivery.ResponseStatus,
+			"response_body":   delivery.ResponseBody,
+			"duration_ms":     delivery.DurationMs,
+			"error":           delivery.Error,
+			"delivered_at":    delivery.DeliveredAt,
+			"updated_at":      finishedAt,
+		}).Error
+	if err != nil {
+		slog.Error("保存Webhook投递结果失败", "delivery_id", delivery.ID, "error", err)
+	}
+}
+
+// backoff 第attempts次失败后的等待时间
+func (s *Service) backoff(attempts int) time.Duration {
+	wait := s.retryBase
+	for i := 1; i < attempts && wait < s.retryMax; i++ {
+		wait *= 2
+	}
+	return min(wait, s.retryMax)
+}
+
+// post 签名并发送请求体，返回响应状态码和截断的响应体；非2xx视为失败
+func (s *Service) post(ctx context.Context, endpoint *models.WebhookEndpoint, delivery *models.WebhookDelivery) (int, string, error) {
+	body, err := json.Marshal(delivery.Payload)
+	if err != nil {
+		return 0, "", fmt.Errorf("序列化请求体失败: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, "", err
+	}
+	timestamp := s.now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "datahub-webhook/1.0")
+	req.Header.Set(EventHeader, delivery.EventType)
+	req.Header.Set(EventIDHeader, delivery.EventID)
+	req.Header.Set(DeliveryHeader, delivery.ID)
+	req.Header.Set(TimestampHeader, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(SignatureHeader, Sign(endpoint.Secret, timestamp, body))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, "", err
+	}
+	defer resp.Body.Close()
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, maxResponseBody))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, string(respBody), fmt.Errorf("HTTP %d", resp.StatusCode)
+	}
+	return resp.StatusCode, string(respBody), nil
+}
@@ -0,0 +1,515 @@
+/*
+ * @module service/webhook/webhook_service
+ * @description 平台事件Webhook服务，外部系统登记接收地址、密钥和订阅的平台事件类型；监听应用事件，映射为平台事件后为匹配的Webhook创建投递，由后台投递器签名推送
+ * @architecture 分层架构 - 业务服务层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow eventlog.Record -> HandleEvent入队 -> 映射为平台事件 -> 匹配启用的Webhook -> 创建投递(pending) -> 唤醒投递器
+ * @rules
+ *   - 平台事件类型是对外契约，与内部应用事件类型解耦，映射见platformEvents；事件类型为空的Webhook订阅全部平台事件
+ *   - 事件入队不阻塞业务流程，队列满时丢弃并输出日志
+ *   - 密钥未指定时自动生成，只在创建和轮换时返回；地址必须是http(s)地址
+ *   - 删除Webhook时一并删除其投递日志；停用后未完成的投递记为失败
+ * @dependencies datahub-service/service/eventlog, datahub-service/service/models, gorm.io/gorm
+ * @refs service/webhook/dispatcher.go, service/models/webhook.go, api/controllers/webhook_controller.go
+ */
+
+package webhook
+
+import (
+	"context"
+	"crypto/rand"
+	"datahub-service/logger"
+	"datahub-service/service/eventlog"
+	"datahub-service/service/models"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// 平台事件类型
+const (
+	EventTaskStarted          = "task.started"
+	EventTaskSucceeded        = "task.succeeded"
+	EventTaskFailed           = "task.failed"
+	EventQualitySucceeded     = "quality.succeeded"
+	EventQualityFailed        = "quality.failed"
+	EventInterfacePublished   = "interface.published"
+	EventInterfaceUnpublished = "interface.unpublished"
+	// EventPing 测试推送，不能订阅
+	EventPing = "ping"
+)
+
+const (
+	// queueSize 待处理事件队列长度
+	queueSize = 1000
+	// secretPrefix 自动生成的密钥前缀
+	secretPrefix = "whsec_"
+)
+
+var (
+	// ErrInvalidWebhook Webhook配置不合法
+	ErrInvalidWebhook = errors.New("Webhook配置不合法")
+	// ErrWebhookDisabled Webhook已停用
+	ErrWebhookDisabled = errors.New("Webhook已停用")
+)
+
+// EventTypeInfo 平台事件类型说明
+type EventTypeInfo struct {
+	Type         string   `json:"type" example:"task.succeeded"`
+	Description  string   `json:"description" example:"同步任务执行成功"`
+	SourceEvents []string `json:"source_events" example:"task_completed"` // 对应的内部应用事件类型
+}
+
+// platformEvents 平台事件类型及其对应的内部应用事件
+var platformEvents = []EventTypeInfo{
+	{Type: EventTaskStarted, Description: "同步任务开始执行", SourceEvents: []string{eventlog.EventTaskStarted}},
+	{Type: EventTaskSucceeded, Description: "基础库或主题库同步任务执行成功", SourceEvents: []string{eventlog.EventTaskCompleted}},
+	{Type: EventTaskFailed, Description: "基础库或主题库同步任务执行失败", SourceEvents: []string{eventlog.EventTaskFailed}},
+	{Type: EventQualitySucceeded, Description: "质量检测任务执行完成且未发现问题", SourceEvents: []string{eventlog.EventQualityTaskCompleted}},
+	{Type: EventQualityFailed, Description: "质量检测发现问题、执行失败或同步写入后未通过质量门禁",
+		SourceEvents: []string{eventlog.EventQualityIssuesFound, eventlog.EventQualityTaskFailed, eventlog.EventQualityGateFailed}},
+	{Type: EventInterfacePublished, Description: "主题接口通过评审并发布", SourceEvents: []string{eventlog.EventThematicInterfacePublished}},
+	{Type: EventInterfaceUnpublished, Description: "主题接口撤销发布", SourceEvents: []string{eventlog.EventThematicInterfaceUnpublished}},
+}
+
+// EndpointRequest 创建或修改Webhook请求
+type EndpointRequest struct {
+	Name        string   `json:"name" validate:"required,max=100" example:"工单系统"`
+	URL         string   `json:"url" validate:"required,max=500" example:"https://example.com/hooks/datahub"`
+	Secret      string   `json:"secret" validate:"omitempty,min=16,max=100"`                              // 签名密钥，创建时为空自动生成；修改时为空保持不变
+	EventTypes  []string `json:"event_types" example:"task.succeeded,quality.failed,interface.published"` // 为空订阅全部平台事件
+	IsEnabled   *bool    `json:"is_enabled,omitempty"`                                                    // 默认启用
+	Description string   `json:"description" validate:"max=1000"`
+}
+
+// DeliveryQuery 投递日志查询条件
+type DeliveryQuery struct {
+	EndpointID string
+	EventType  string
+	EventID    string
+	Status     string
+	Page       int
+	Size       int
+}
+
+// queuedEvent 待处理的事件
+type queuedEvent struct {
+	requestID string
+	event     eventlog.Event
+	at        time.Time
+}
+
+// Service 平台事件Webhook服务
+type Service struct {
+	db           *gorm.DB
+	client       *http.Client
+	queue        chan queuedEvent
+	wake         chan struct{}
+	maxAttempts  int
+	retryBase    time.Duration
+	retryMax     time.Duration
+	lease        time.Duration
+	pollInterval time.Duration
+	now          func() time.Time
+	wg           sync.WaitGroup
+	ctx          context.Context
+	cancel       context.CancelFunc
+	startOnce    sync.Once
+}
+
+// NewService 创建Webhook服务，最大投递次数可通过WEBHOOK_MAX_ATTEMPTS配置
+func NewService(db *gorm.DB) *Service {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	maxAttempts := defaultMaxAttempts
+	if value, err := strconv.Atoi(os.Getenv("WEBHOOK_MAX_ATTEMPTS")); err == nil && value > 0 {
+		maxAttempts = value
+	}
+
+	return &Service{
+		db:           db,
+		client:       &http.Client{Timeout: requestTimeout},
+		queue:        make(chan queuedEvent, queueSize),
+		wake:         make(chan struct{}, 1),
+		maxAttempts:  maxAttempts,
+		retryBase:    defaultRetryBase,
+		retryMax:     defaultRetryMax,
+		lease:        deliveryLease,
+		pollInterval: defaultPollInterval,
+		now:          time.Now,
+		ctx:          ctx,
+		cancel:       cancel,
+	}
+}
+
+// Start 启动事件处理和后台投递
+func (s *Service) Start() {
+	s.startOnce.Do(func() {
+		s.wg.Add(2)
+		go s.runEvents()
+		go s.runDispatcher()
+		slog.Info("Webhook投递器已启动", "max_attempts", s.maxAttempts)
+	})
+}
+
+// Stop 停止事件处理和后台投递，等待进行中的投递结束
+func (s *Service) Stop() {
+	s.cancel()
+	s.wg.Wait()
+}
+
+// EventTypes 平台事件类型列表
+func (s *Service) EventTypes() []EventTypeInfo {
+	return platformEvents
+}
+
+// HandleEvent 事件监听入口，注册到eventlog，只入队不阻塞调用方
+func (s *Service) HandleEvent(ctx context.Context, event eventlog.Event) {
+	if platformEventType(event.Type) == "" {
+		return
+	}
+	item := queuedEvent{requestID: logger.RequestIDFromContext(ctx), event: event, at: s.now()}
+	select {
+	case s.queue <- item:
+	default:
+		slog.WarnContext(ctx, "Webhook事件队列已满，丢弃事件", "event_type", event.Type, "object_id", event.ObjectID)
+	}
+}
+
+func (s *Service) runEvents() {
+	defer s.wg.Done()
+	for {
+		select {
+		case <-s.ctx.Done():
+			return
+		case item := <-s.queue:
+			created, err := s.enqueue(item)
+			if err != nil {
+				slog.Error("创建Webhook投递失败", "event_type", item.event.Type, "object_id", item.event.ObjectID, "error", err)
+				continue
+			}
+			if created > 0 {
+				s.notify()
+			}
+		}
+	}
+}
+
+// enqueue 为匹配平台事件的启用Webhook创建投递，返回创建的数量
+func (s *Service) enqueue(item queuedEvent) (int, error) {
+	eventType := platformEventType(item.event.Type)
+	if eventType == "" {
+		return 0, nil
+	}
+
+	var endpoints []models.WebhookEndpoint
+	if err := s.db.Where("is_enabled = ?", true).Find(&endpoints).Error; err != nil {
+		return 0, fmt.Errorf("获取Webhook失败: %w", err)
+	}
+
+	eventID := uuid.New().String()
+	payload := buildPayload(eventID, eventType, item)
+	var deliveries []models.WebhookDelivery
+	for _, endpoint := range endpoints {
+		if len(endpoint.EventTypes) > 0 && !slices.Contains(endpoint.EventTypes, eventType) {
+			continue
+		}
+		deliveries = append(deliveries, models.WebhookDelivery{
+			EndpointID:    endpoint.ID,
+			EventID:       eventID,
+			EventType:     eventType,
+			Payload:       payload,
+			NextAttemptAt: item.at,
+		})
+	}
+	if len(deliveries) == 0 {
+		return 0, nil
+	}
+	if err := s.db.Create(&deliveries).Error; err != nil {
+		return 0, err
+	}
+	return len(deliveries), nil
+}
+
+// platformEventType 内部应用事件对应的平台事件类型，没有对应时返回空
+func platformEventType(sourceEvent string) string {
+	for _, info := range platformEvents {
+		if slices.Contains(info.SourceEvents, sourceEvent) {
+			return info.Type
+		}
+	}
+	return ""
+}
+
+// buildPayload 组装推送的请求体
+func buildPayload(eventID, eventType string, item queuedEvent) models.JSONB {
+	level := item.event.Level
+	if level == "" {
+		level = models.EventLevelInfo
+	}
+	data := item.event.Attributes
+	if data == nil {
+		data = map[string]interface{}{}
+	}
+	return models.JSONB{
+		"id":           eventID,
+		"type":         eventType,
+		"source_event": item.event.Type,
+		"level":        level,
+		"message":      item.event.Message,
+		"object_type":  item.event.ObjectType,
+		"object_id":    item.event.ObjectID,
+		"request_id":   item.requestID,
+		"data":         data,
+		"created_at":   item.at.Format(time.RFC3339),
+	}
+}
+
+// === Webhook管理 ===
+
+// validate 校验Webhook请求
+func (s *Service) validate(req *EndpointRequest) error {
+	if strings.TrimSpace(req.Name) == "" {
+		return fmt.Errorf("%w: 名称不能为空", ErrInvalidWebhook)
+	}
+	parsed, err := url.Parse(strings.TrimSpace(req.URL))
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return fmt.Errorf("%w: 地址必须是http(s)地址", ErrInvalidWebhook)
+	}
+	for _, eventType := range req.EventTypes {
+		if !slices.ContainsFunc(platformEvents, func(info EventTypeInfo) bool { return info.Type == eventType }) {
+			return fmt.Errorf("%w: 不支持的事件类型%s", ErrInvalidWebhook, eventType)
+		}
+	}
+	return nil
+}
+
+// CreateEndpoint 创建Webhook，返回Webhook和签名密钥
+func (s *Service) CreateEndpoint(ctx context.Context, req *EndpointRequest, username string) (*models.WebhookEndpoint, string, error) {
+	if err := s.validate(req); err != nil {
+		return nil, "", err
+	}
+	secret := req.Secret
+	if secret == "" {
+		var err error
+		if secret, err = generateSecret(); err != nil {
+			return nil, "", err
+		}
+	}
+
+	endpoint := &models.WebhookEndpoint{
+		Name:        strings.TrimSpace(req.Name),
+		URL:         strings.TrimSpace(req.URL),
+		Secret:      secret,
+		EventTypes:  req.EventTypes,
+		IsEnabled:   req.IsEnabled == nil || *req.IsEnabled,
+		Description: req.Description,
+		CreatedBy:   username,
+		UpdatedBy:   username,
+	}
+	if err := s.db.WithContext(ctx).Create(endpoint).Error; err != nil {
+		return nil, "", err
+	}
+	return endpoint, secret, nil
+}
+
+// ListEndpoints 分页获取Webhook
+func (s *Service) ListEndpoints(ctx context.Context, page, size int) ([]models.WebhookEndpoint, int64, error) {
+	db := s.db.WithContext(ctx).Model(&models.WebhookEndpoint{})
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	page, size = models.NormalizePage(page, size)
+	var endpoints []models.WebhookEndpoint
+	err := db.Order("created_at DESC").Offset((page - 1) * size).Limit(size).Find(&endpoints).Error
+	return endpoints, total, err
+}
+
+// GetEndpoint 获取Webhook
+func (s *Service) GetEndpoint(ctx context.Context, id string) (*models.WebhookEndpoint, error) {
+	var endpoint models.WebhookEndpoint
+	if err := s.db.WithContext(ctx).First(&endpoint, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &endpoint, nil
+}
+
+// UpdateEndpoint 修改Webhook，密钥为空时保持不变
+func (s *Service) UpdateEndpoint(ctx context.Context, id string, req *EndpointRequest, username string) (*models.WebhookEndpoint, error) {
+	endpoint, err := s.GetEndpoint(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.validate(req); err != nil {
+		return nil, err
+	}
+
+	endpoint.Name = strings.TrimSpace(req.Name)
+	endpoint.URL = strings.TrimSpace(req.URL)
+	endpoint.EventTypes = req.EventTypes
+	if req.IsEnabled != nil {
+		endpoint.IsEnabled = *req.IsEnabled
+	}
+	if req.Secret != "" {
+		endpoint.Secret = req.Secret
+	}
+	endpoint.Description = req.Description
+	endpoint.UpdatedBy = username
+	endpoint.UpdatedAt = s.now()
+	if err := s.db.WithContext(ctx).Model(endpoint).
+		Select("name", "url", "secret", "event_types", "is_enabled", "description", "updated_by", "updated_at").
+		Updates(endpoint).Error; err != nil {
+		return nil, err
+	}
+	return endpoint, nil
+}
+
+// DeleteEndpoint 删除Webhook及其投递日志
+func (s *Service) DeleteEndpoint(ctx context.Context, id string) error {
+	if _, err := s.GetEndpoint(ctx, id); err != nil {
+		return err
+	}
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("endpoint_id = ?", id).Delete(&models.WebhookDelivery{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.WebhookEndpoint{}, "id = ?", id).Error
+	})
+}
+
+// RotateSecret 生成新的签名密钥，之后的推送使用新密钥签名
+func (s *Service) RotateSecret(ctx context.Context, id, username string) (*models.WebhookEndpoint, string, error) {
+	endpoint, err := s.GetEndpoint(ctx, id)
+	if err != nil {
+		return nil, "", err
+	}
+	secret, err := generateSecret()
+	if err != nil {
+		return nil, "", err
+	}
+	endpoint.Secret = secret
+	endpoint.UpdatedBy = username
+	endpoint.UpdatedAt = s.now()
+	if err := s.db.WithContext(ctx).Model(endpoint).Select("secret", "updated_by", "updated_at").Updates(endpoint).Error; err != nil {
+		return nil, "", err
+	}
+	return endpoint, secret, nil
+}
+
+// generateSecret 生成随机签名密钥
+func generateSecret() (string, error) {
+	buf := make([]byte, 24)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("生成签名密钥失败: %w", err)
+	}
+	return secretPrefix + hex.EncodeToString(buf), nil
+}
+
+// === 投递日志 ===
+
+// ListDeliveries 按条件分页查询投递日志，按创建时间倒序
+func (s *Service) ListDeliveries(ctx context.Context, query DeliveryQuery) ([]models.WebhookDelivery, int64, error) {
+	db := s.db.WithContext(ctx).Model(&models.WebhookDelivery{})
+	if query.EndpointID != "" {
+		db = db.Where("endpoint_id = ?", query.EndpointID)
+	}
+	if query.EventType != "" {
+		db = db.Where("event_type = ?", query.EventType)
+	}
+	if query.EventID != "" {
+		db = db.Where("event_id = ?", query.EventID)
+	}
+	if query.Status != "" {
+		db = db.Where("status = ?", query.Status)
+	}
+
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	page, size := models.NormalizePage(query.Page, query.Size)
+	var deliveries []models.WebhookDelivery
+	err := db.Order("created_at DESC").Offset((page - 1) * size).Limit(size).Find(&deliveries).Error
+	return deliveries, total, err
+}
+
+// GetDelivery 获取投递日志
+func (s *Service) GetDelivery(ctx context.Context, id string) (*models.WebhookDelivery, error) {
+	var delivery models.WebhookDelivery
+	if err := s.db.WithContext(ctx).First(&delivery, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &delivery, nil
+}
+
+// Redeliver 以相同的事件ID和请求体重新投递，创建新的投递日志
+func (s *Service) Redeliver(ctx context.Context, id string) (*models.WebhookDelivery, error) {
+	original, err := s.GetDelivery(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	endpoint, err := s.GetEndpoint(ctx, original.EndpointID)
+	if err != nil {
+		return nil, err
+	}
+	if !endpoint.IsEnabled {
+		return nil, ErrWebhookDisabled
+	}
+
+	delivery := &models.WebhookDelivery{
+		EndpointID:    original.EndpointID,
+		EventID:       original.EventID,
+		EventType:     original.EventType,
+		Payload:       original.Payload,
+		NextAttemptAt: s.now(),
+	}
+	if err := s.db.WithContext(ctx).Create(delivery).Error; err != nil {
+		return nil, err
+	}
+	s.notify()
+	return delivery, nil
+}
+
+// Ping 向Webhook同步推送一次测试事件，不重试，返回投递日志
+func (s *Service) Ping(ctx context.Context, id string) (*models.WebhookDelivery, error) {
+	endpoint, err := s.GetEndpoint(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	eventID := uuid.New().String()
+	now := s.now()
+	delivery := &models.WebhookDelivery{
+		EndpointID: endpoint.ID,
+		EventID:    eventID,
+		EventType:  EventPing,
+		Payload: models.JSONB{
+			"id":         eventID,
+			"type":       EventPing,
+			"message":    "Webhook测试推送",
+			"data":       map[string]interface{}{"webhook_id": endpoint.ID, "name": endpoint.Name},
+			"created_at": now.Format(time.RFC3339),
+		},
+		Status:        models.WebhookDeliveryDelivering,
+		Attempts:      1,
+		NextAttemptAt: now.Add(s.lease),
+	}
+	if err := s.db.WithContext(ctx).Create(delivery).Error; err != nil {
+		return nil, err
+	}
+	s.attempt(ctx, endpoint, delivery, true)
+	return delivery, nil
+}
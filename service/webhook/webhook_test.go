@@ -0,0 +1,307 @@
+/*
+ * @module service/webhook/webhook_test
+ * @description 平台事件Webhook测试，覆盖事件映射和订阅过滤、签名、失败退避重试、超过次数失败、停用后放弃、重新投递、测试推送和配置校验
+ * @architecture 测试层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow 初始化内存数据库和接收端 -> 登记Webhook -> 事件入库为投递 -> 推进时钟领取投递 -> 验证接收端收到的请求和投递日志
+ * @rules 使用SQLite内存数据库和httptest接收端，不依赖外部服务
+ * @dependencies github.com/stretchr/testify, gorm.io/driver/sqlite
+ * @refs service/webhook/webhook_service.go, service/webhook/dispatcher.go
+ */
+
+package webhook
+
+import (
+	"context"
+	"datahub-service/service/eventlog"
+	"datahub-service/service/models"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// receiver 测试接收端，按预设状态码依次响应并记录收到的请求
+type receiver struct {
+	mu       sync.Mutex
+	statuses []int
+	requests []*http.Request
+	bodies   [][]byte
+}
+
+func (rc *receiver) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	rc.requests = append(rc.requests, r)
+	rc.bodies = append(rc.bodies, body)
+	status := http.StatusOK
+	if len(rc.statuses) > 0 {
+		status = rc.statuses[0]
+		rc.statuses = rc.statuses[1:]
+	}
+	w.WriteHeader(status)
+	_, _ = w.Write([]byte("ok"))
+}
+
+func (rc *receiver) count() int {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	return len(rc.requests)
+}
+
+// testClock 可推进的时钟
+type testClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *testClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *testClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+func setupWebhookService(t *testing.T) (*Service, *testClock) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&models.WebhookEndpoint{}, &models.WebhookDelivery{}))
+
+	clock := &testClock{now: time.Now()}
+	s := NewService(db)
+	s.now = clock.Now
+	s.maxAttempts = 3
+	return s, clock
+}
+
+func createEndpoint(t *testing.T, s *Service, url string, eventTypes ...string) (*models.WebhookEndpoint, string) {
+	endpoint, secret, err := s.CreateEndpoint(context.Background(), &EndpointRequest{
+		Name:       "工单系统-" + strconv.Itoa(len(eventTypes)) + url,
+		URL:        url,
+		EventTypes: eventTypes,
+	}, "tester")
+	require.NoError(t, err)
+	return endpoint, secret
+}
+
+func recordEvent(t *testing.T, s *Service, event eventlog.Event) int {
+	created, err := s.enqueue(queuedEvent{requestID: "req-1", event: event, at: s.now()})
+	require.NoError(t, err)
+	return created
+}
+
+func deliveriesOf(t *testing.T, s *Service, endpointID string) []models.WebhookDelivery {
+	deliveries, _, err := s.ListDeliveries(context.Background(), DeliveryQuery{EndpointID: endpointID, Size: 100})
+	require.NoError(t, err)
+	return deliveries
+}
+
+func TestEnqueueMapsEventsAndFiltersSubscriptions(t *testing.T) {
+	s, _ := setupWebhookService(t)
+	all, _ := createEndpoint(t, s, "http://example.com/all")
+	quality, _ := createEndpoint(t, s, "http://example.com/quality", EventQualityFailed)
+
+	assert.Equal(t, 2, recordEvent(t, s, eventlog.Event{Type: eventlog.EventQualityGateFailed, ObjectType: "data_interface", ObjectID: "iface-1"}))
+	assert.Equal(t, 1, recordEvent(t, s, eventlog.Event{Type: eventlog.EventTaskCompleted, ObjectType: "sync_task", ObjectID: "task-1",
+		Attributes: map[string]interface{}{"execution_id": "exec-1"}}))
+	assert.Equal(t, 0, recordEvent(t, s, eventlog.Event{Type: eventlog.EventBatchFailed}))
+
+	require.Len(t, deliveriesOf(t, s, quality.ID), 1)
+	allDeliveries := deliveriesOf(t, s, all.ID)
+	require.Len(t, allDeliveries, 2)
+
+	var succeeded *models.WebhookDelivery
+	for i := range allDeliveries {
+		if allDeliveries[i].EventType == EventTaskSucceeded {
+			succeeded = &allDeliveries[i]
+		}
+	}
+	require.NotNil(t, succeeded)
+	assert.Equal(t, models.WebhookDeliveryPending, succeeded.Status)
+	assert.Equal(t, eventlog.EventTaskCompleted, succeeded.Payload["source_event"])
+	assert.Equal(t, "task-1", succeeded.Payload["object_id"])
+	assert.Equal(t, "req-1", succeeded.Payload["request_id"])
+	assert.Equal(t, "exec-1", succeeded.Payload["data"].(map[string]interface{})["execution_id"])
+}
+
+func TestDispatchSignsAndRetriesWithBackoff(t *testing.T) {
+	s, clock := setupWebhookService(t)
+	rc := &receiver{statuses: []int{http.StatusInternalServerError}}
+	server := httptest.NewServer(rc)
+	defer server.Close()
+	endpoint, secret := createEndpoint(t, s, server.URL)
+
+	recordEvent(t, s, eventlog.Event{Type: eventlog.EventTaskFailed, Level: models.EventLevelError, ObjectID: "task-1"})
+
+	claimed, err := s.DispatchDue(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, claimed)
+	delivery := deliveriesOf(t, s, endpoint.ID)[0]
+	assert.Equal(t, models.WebhookDeliveryPending, delivery.Status)
+	assert.Equal(t, 1, delivery.Attempts)
+	assert.Equal(t, http.StatusInternalServerError, delivery.ResponseStatus)
+	assert.Equal(t, "HTTP 500", delivery.Error)
+	assert.WithinDuration(t, clock.Now().Add(defaultRetryBase), delivery.NextAttemptAt, time.Second)
+
+	// 退避时间未到不会重试
+	claimed, err = s.DispatchDue(context.Background())
+	require.NoError(t, err)
+	assert.Zero(t, claimed)
+
+	clock.Advance(defaultRetryBase)
+	claimed, err = s.DispatchDue(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, claimed)
+	delivery = deliveriesOf(t, s, endpoint.ID)[0]
+	assert.Equal(t, models.WebhookDeliverySucceeded, delivery.Status)
+	assert.Equal(t, 2, delivery.Attempts)
+	assert.NotNil(t, delivery.DeliveredAt)
+	assert.Empty(t, delivery.Error)
+
+	require.Equal(t, 2, rc.count())
+	req, body := rc.requests[1], rc.bodies[1]
+	timestamp, err := strconv.ParseInt(req.Header.Get(TimestampHeader), 10, 64)
+	require.NoError(t, err)
+	assert.Equal(t, Sign(secret, timestamp, body), req.Header.Get(SignatureHeader))
+	assert.Equal(t, EventTaskFailed, req.Header.Get(EventHeader))
+	assert.Equal(t, delivery.ID, req.Header.Get(DeliveryHeader))
+	assert.Equal(t, delivery.EventID, req.Header.Get(EventIDHeader))
+
+	var payload map[string]interface{}
+	require.NoError(t, json.Unmarshal(body, &payload))
+	assert.Equal(t, EventTaskFailed, payload["type"])
+	assert.Equal(t, delivery.EventID, payload["id"])
+	assert.Equal(t, models.EventLevelError, payload["level"])
+}
+
+func TestDispatchFailsAfterMaxAttempts(t *testing.T) {
+	s, clock := setupWebhookService(t)
+	rc := &receiver{statuses: []int{http.StatusBadGateway, http.StatusBadGateway, http.StatusBadGateway}}
+	server := httptest.NewServer(rc)
+	defer server.Close()
+	endpoint, _ := createEndpoint(t, s, server.URL)
+
+	recordEvent(t, s, eventlog.Event{Type: eventlog.EventTaskFailed})
+	for i := 0; i < s.maxAttempts; i++ {
+		_, err := s.DispatchDue(context.Background())
+		require.NoError(t, err)
+		clock.Advance(s.retryMax)
+	}
+
+	delivery := deliveriesOf(t, s, endpoint.ID)[0]
+	assert.Equal(t, models.WebhookDeliveryFailed, delivery.Status)
+	assert.Equal(t, s.maxAttempts, delivery.Attempts)
+	assert.Equal(t, s.maxAttempts, rc.count())
+
+	claimed, err := s.DispatchDue(context.Background())
+	require.NoError(t, err)
+	assert.Zero(t, claimed)
+
+	assert.Equal(t, defaultRetryBase, s.backoff(1))
+	assert.Equal(t, 4*defaultRetryBase, s.backoff(3))
+	assert.Equal(t, defaultRetryMax, s.backoff(20))
+}
+
+func TestDisabledEndpointAndRedeliver(t *testing.T) {
+	s, _ := setupWebhookService(t)
+	ctx := context.Background()
+	rc := &receiver{}
+	server := httptest.NewServer(rc)
+	defer server.Close()
+	endpoint, _ := createEndpoint(t, s, server.URL)
+
+	recordEvent(t, s, eventlog.Event{Type: eventlog.EventThematicInterfacePublished, ObjectID: "iface-1"})
+	disabled := false
+	_, err := s.UpdateEndpoint(ctx, endpoint.ID, &EndpointRequest{Name: endpoint.Name, URL: endpoint.URL, IsEnabled: &disabled}, "tester")
+	require.NoError(t, err)
+
+	_, err = s.DispatchDue(ctx)
+	require.NoError(t, err)
+	original := deliveriesOf(t, s, endpoint.ID)[0]
+	assert.Equal(t, models.WebhookDeliveryFailed, original.Status)
+	assert.Zero(t, rc.count())
+
+	_, err = s.Redeliver(ctx, original.ID)
+	assert.ErrorIs(t, err, ErrWebhookDisabled)
+
+	enabled := true
+	_, err = s.UpdateEndpoint(ctx, endpoint.ID, &EndpointRequest{Name: endpoint.Name, URL: endpoint.URL, IsEnabled: &enabled}, "tester")
+	require.NoError(t, err)
+	redelivery, err := s.Redeliver(ctx, original.ID)
+	require.NoError(t, err)
+	assert.Equal(t, original.EventID, redelivery.EventID)
+	assert.NotEqual(t, original.ID, redelivery.ID)
+
+	_, err = s.DispatchDue(ctx)
+	require.NoError(t, err)
+	got, err := s.GetDelivery(ctx, redelivery.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.WebhookDeliverySucceeded, got.Status)
+	assert.Equal(t, 1, rc.count())
+}
+
+func TestPingAndRotateSecret(t *testing.T) {
+	s, _ := setupWebhookService(t)
+	ctx := context.Background()
+	rc := &receiver{statuses: []int{http.StatusUnauthorized}}
+	server := httptest.NewServer(rc)
+	defer server.Close()
+	endpoint, secret := createEndpoint(t, s, server.URL)
+	assert.Contains(t, secret, secretPrefix)
+
+	delivery, err := s.Ping(ctx, endpoint.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.WebhookDeliveryFailed, delivery.Status)
+	assert.Equal(t, http.StatusUnauthorized, delivery.ResponseStatus)
+	assert.Equal(t, "ok", delivery.ResponseBody)
+
+	_, rotated, err := s.RotateSecret(ctx, endpoint.ID, "tester")
+	require.NoError(t, err)
+	assert.NotEqual(t, secret, rotated)
+
+	delivery, err = s.Ping(ctx, endpoint.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.WebhookDeliverySucceeded, delivery.Status)
+	req, body := rc.requests[1], rc.bodies[1]
+	timestamp, _ := strconv.ParseInt(req.Header.Get(TimestampHeader), 10, 64)
+	assert.Equal(t, Sign(rotated, timestamp, body), req.Header.Get(SignatureHeader))
+	assert.Equal(t, EventPing, req.Header.Get(EventHeader))
+}
+
+func TestEndpointValidation(t *testing.T) {
+	s, _ := setupWebhookService(t)
+	ctx := context.Background()
+
+	_, _, err := s.CreateEndpoint(ctx, &EndpointRequest{Name: "a", URL: "ftp://example.com"}, "tester")
+	assert.ErrorIs(t, err, ErrInvalidWebhook)
+
+	_, _, err = s.CreateEndpoint(ctx, &EndpointRequest{Name: "a", URL: "https://example.com", EventTypes: []string{"task_failed"}}, "tester")
+	assert.ErrorIs(t, err, ErrInvalidWebhook)
+
+	endpoint, secret, err := s.CreateEndpoint(ctx, &EndpointRequest{Name: "a", URL: "https://example.com", Secret: "0123456789abcdef"}, "tester")
+	require.NoError(t, err)
+	assert.Equal(t, "0123456789abcdef", secret)
+	assert.True(t, endpoint.IsEnabled)
+
+	require.NoError(t, s.DeleteEndpoint(ctx, endpoint.ID))
+	_, err = s.GetEndpoint(ctx, endpoint.ID)
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+}
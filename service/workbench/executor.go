@@ -0,0 +1,300 @@
+/*
+ * @module service/workbench/executor
+ * @description SQL工作台执行器，在只读事务中解析语句实际访问的表并执行查询，返回结果列的来源表和字段
+ * @architecture 分层架构 - 业务服务层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow 取连接 -> BEGIN READ ONLY -> SET LOCAL statement_timeout/ROLE -> EXPLAIN取访问的表 或 执行查询 -> 按结果列的表OID和字段号查询来源 -> ROLLBACK
+ * @rules
+ *   - 每次执行都在只读事务中进行并回滚，语句超时由statement_timeout控制，超时返回ErrQueryTimeout
+ *   - 配置了执行角色时以SET LOCAL ROLE切换，数据库权限作为schema白名单之外的第二道限制
+ *   - 使用扩展协议且不缓存预备语句，数据库层面也只接受单条语句
+ *   - 结果列来源取自RowDescription的表OID和字段号，计算列没有来源
+ * @dependencies github.com/jackc/pgx/v5, gorm.io/gorm
+ * @refs service/workbench/workbench_service.go
+ */
+
+package workbench
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/stdlib"
+	"gorm.io/gorm"
+)
+
+// timeoutGrace 语句超时之外等待数据库返回取消结果的时间
+const timeoutGrace = 5 * time.Second
+
+// pgQueryCanceled 语句因超时或取消被中止的SQLSTATE
+const pgQueryCanceled = "57014"
+
+// 执行错误
+var (
+	ErrQueryFailed  = errors.New("执行查询失败")
+	ErrQueryTimeout = errors.New("查询超过语句超时时间")
+)
+
+// Relation 语句访问的表
+type Relation struct {
+	Schema string `json:"schema"`
+	Table  string `json:"table"`
+}
+
+// String 返回schema.table形式的表名
+func (r Relation) String() string {
+	return r.Schema + "." + r.Table
+}
+
+// Column 结果列
+type Column struct {
+	Name         string `json:"name"`
+	Type         string `json:"type,omitempty" example:"varchar"`
+	SourceSchema string `json:"source_schema,omitempty"` // 来源表所在schema，计算列为空
+	SourceTable  string `json:"source_table,omitempty"`
+	SourceColumn string `json:"source_column,omitempty"`
+	Masked       bool   `json:"masked"` // 是否已脱敏
+}
+
+// ResultSet 查询结果
+type ResultSet struct {
+	Columns []Column
+	Rows    [][]interface{}
+}
+
+// Executor 语句执行器
+type Executor interface {
+	// Relations 解析语句实际访问的表，不执行语句
+	Relations(ctx context.Context, sql string, timeout time.Duration) ([]Relation, error)
+	// Query 在只读事务中执行语句
+	Query(ctx context.Context, sql string, timeout time.Duration) (*ResultSet, error)
+}
+
+// PostgresExecutor 基于pgx连接的PostgreSQL执行器
+type PostgresExecutor struct {
+	db   *gorm.DB
+	role string
+}
+
+// NewPostgresExecutor 创建PostgreSQL执行器，role不为空时以该角色执行语句
+func NewPostgresExecutor(db *gorm.DB, role string) *PostgresExecutor {
+	return &PostgresExecutor{db: db, role: role}
+}
+
+// Relations 通过EXPLAIN解析语句实际访问的表，视图展开为其引用的表
+func (e *PostgresExecutor) Relations(ctx context.Context, sql string, timeout time.Duration) ([]Relation, error) {
+	var plan []byte
+	err := e.readOnly(ctx, timeout, func(ctx context.Context, tx pgx.Tx) error {
+		return tx.QueryRow(ctx, "EXPLAIN (VERBOSE, FORMAT JSON) "+sql, pgx.QueryExecModeDescribeExec).Scan(&plan)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return planRelations(plan)
+}
+
+// Query 执行查询并解析结果列的来源
+func (e *PostgresExecutor) Query(ctx context.Context, sql string, timeout time.Duration) (*ResultSet, error) {
+	result := &ResultSet{}
+	err := e.readOnly(ctx, timeout, func(ctx context.Context, tx pgx.Tx) error {
+		rows, err := tx.Query(ctx, sql, pgx.QueryExecModeDescribeExec)
+		if err != nil {
+			return err
+		}
+		fields := rows.FieldDescriptions()
+		typeMap := tx.Conn().TypeMap()
+		sources := make([]pgconn.FieldDescription, len(fields))
+		copy(sources, fields)
+		result.Columns = make([]Column, len(fields))
+		for i, field := range fields {
+			result.Columns[i].Name = field.Name
+			if t, ok := typeMap.TypeForOID(field.DataTypeOID); ok {
+				result.Columns[i].Type = t.Name
+			}
+		}
+		for rows.Next() {
+			values, err := rows.Values()
+			if err != nil {
+				rows.Close()
+				return err
+			}
+			for i, value := range values {
+				values[i] = jsonValue(value)
+			}
+			result.Rows = append(result.Rows, values)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return err
+		}
+		return resolveSources(ctx, tx, sources, result.Columns)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// readOnly 在只读事务中执行fn，结束后回滚
+func (e *PostgresExecutor) readOnly(ctx context.Context, timeout time.Duration, fn func(context.Context, pgx.Tx) error) error {
+	sqlDB, err := e.db.DB()
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout+timeoutGrace)
+	defer cancel()
+	conn, err := sqlDB.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	err = conn.Raw(func(driverConn interface{}) error {
+		stdConn, ok := driverConn.(*stdlib.Conn)
+		if !ok {
+			return fmt.Errorf("SQL工作台需要pgx驱动的PostgreSQL连接，当前连接类型为%T", driverConn)
+		}
+		tx, err := stdConn.Conn().BeginTx(ctx, pgx.TxOptions{AccessMode: pgx.ReadOnly})
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback(context.Background())
+
+		if _, err := tx.Exec(ctx, fmt.Sprintf("SET LOCAL statement_timeout = %d", timeout.Milliseconds())); err != nil {
+			return err
+		}
+		if e.role != "" {
+			if _, err := tx.Exec(ctx, "SET LOCAL ROLE "+pgx.Identifier{e.role}.Sanitize()); err != nil {
+				return fmt.Errorf("切换SQL工作台执行角色失败: %w", err)
+			}
+		}
+		return fn(ctx, tx)
+	})
+	if err == nil {
+		return nil
+	}
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		if pgErr.Code == pgQueryCanceled {
+			return ErrQueryTimeout
+		}
+		return fmt.Errorf("%w: %s", ErrQueryFailed, pgErr.Message)
+	}
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return ErrQueryTimeout
+	}
+	return err
+}
+
+// resolveSources 按表OID和字段号查询结果列的来源表和字段
+func resolveSources(ctx context.Context, tx pgx.Tx, fields []pgconn.FieldDescription, columns []Column) error {
+	var oids []int64
+	seen := make(map[uint32]bool)
+	for _, field := range fields {
+		if field.TableOID != 0 && !seen[field.TableOID] {
+			seen[field.TableOID] = true
+			oids = append(oids, int64(field.TableOID))
+		}
+	}
+	if len(oids) == 0 {
+		return nil
+	}
+
+	rows, err := tx.Query(ctx, `
+		SELECT a.attrelid::bigint, a.attnum, n.nspname, c.relname, a.attname
+		FROM pg_catalog.pg_attribute a
+		JOIN pg_catalog.pg_class c ON c.oid = a.attrelid
+		JOIN pg_catalog.pg_namespace n ON n.oid = c.relnamespace
+		WHERE a.attrelid::bigint = ANY($1) AND a.attnum > 0`, oids)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	type attribute struct {
+		oid    uint32
+		attnum uint16
+	}
+	sources := make(map[attribute]Column)
+	for rows.Next() {
+		var oid int64
+		var attnum int16
+		var source Column
+		if err := rows.Scan(&oid, &attnum, &source.SourceSchema, &source.SourceTable, &source.SourceColumn); err != nil {
+			return err
+		}
+		sources[attribute{uint32(oid), uint16(attnum)}] = source
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for i, field := range fields {
+		if source, ok := sources[attribute{field.TableOID, field.TableAttributeNumber}]; ok {
+			columns[i].SourceSchema = source.SourceSchema
+			columns[i].SourceTable = source.SourceTable
+			columns[i].SourceColumn = source.SourceColumn
+		}
+	}
+	return nil
+}
+
+// planRelations 从EXPLAIN (VERBOSE, FORMAT JSON)的执行计划中提取访问的表，按名称排序去重
+func planRelations(plan []byte) ([]Relation, error) {
+	var plans []struct {
+		Plan map[string]interface{} `json:"Plan"`
+	}
+	if err := json.Unmarshal(plan, &plans); err != nil {
+		return nil, fmt.Errorf("解析执行计划失败: %w", err)
+	}
+
+	seen := make(map[Relation]bool)
+	var walk func(node map[string]interface{})
+	walk = func(node map[string]interface{}) {
+		table, _ := node["Relation Name"].(string)
+		schema, _ := node["Schema"].(string)
+		if table != "" {
+			seen[Relation{Schema: schema, Table: table}] = true
+		}
+		children, _ := node["Plans"].([]interface{})
+		for _, child := range children {
+			if childNode, ok := child.(map[string]interface{}); ok {
+				walk(childNode)
+			}
+		}
+	}
+	for _, p := range plans {
+		walk(p.Plan)
+	}
+
+	relations := make([]Relation, 0, len(seen))
+	for relation := range seen {
+		relations = append(relations, relation)
+	}
+	sort.Slice(relations, func(i, j int) bool { return relations[i].String() < relations[j].String() })
+	return relations, nil
+}
+
+// jsonValue 将pgx解码的值转换为可序列化为JSON的值
+func jsonValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case [16]byte:
+		return uuid.UUID(v).String()
+	case json.Marshaler:
+		return value
+	case driver.Valuer:
+		if converted, err := v.Value(); err == nil {
+			return converted
+		}
+	}
+	return value
+}
@@ -0,0 +1,341 @@
+/*
+ * @module service/workbench/guard
+ * @description SQL工作台语句静态检查，在提交数据库之前拒绝多语句、非查询语句和危险函数，并为结果加上行数上限
+ * @architecture 分层架构 - 业务服务层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow 原始语句 -> 词法切分（识别字符串、引号标识符、美元引用和注释） -> 单语句/括号/首关键字/写关键字/危险函数检查 -> 去掉结尾分号的语句
+ * @rules
+ *   - 只允许以SELECT、WITH、TABLE、VALUES开头的单条语句，结尾分号可省略
+ *   - 语句中出现INSERT/UPDATE/DELETE/MERGE/INTO关键字时拒绝（数据修改CTE、SELECT INTO、FOR UPDATE），只读事务是最后一道防线
+ *   - 调用读写文件、远程连接、休眠、修改配置、终止会话和咨询锁等函数时拒绝，按函数名前缀匹配，不区分是否带schema和引号
+ *   - 行数上限通过外层子查询实现，多取一行用于判断是否截断
+ *   - 嵌入查询的表达式片段不允许子查询，括号须在片段内配对，避免闭合外层语句
+ * @dependencies strings, unicode
+ * @refs service/workbench/workbench_service.go, service/metric_store
+ */
+
+package workbench
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// maxStatementLength 语句最大长度
+const maxStatementLength = 64 << 10
+
+// ErrStatementRejected 语句未通过静态检查
+var ErrStatementRejected = errors.New("语句不允许执行")
+
+// allowedLeadingKeywords 允许的语句首关键字
+var allowedLeadingKeywords = map[string]bool{"select": true, "with": true, "table": true, "values": true}
+
+// writeKeywords 查询语句中出现即拒绝的关键字
+var writeKeywords = map[string]bool{"insert": true, "update": true, "delete": true, "merge": true, "into": true}
+
+// forbiddenFunctionPrefixes 禁止调用的函数名前缀
+var forbiddenFunctionPrefixes = []string{
+	"pg_read_", "pg_ls_", "pg_stat_file", "pg_file_", "lo_", "dblink",
+	"pg_sleep", "set_config", "pg_terminate_backend", "pg_cancel_backend", "pg_advisory",
+	"pg_reload_conf", "pg_rotate_logfile", "pg_promote", "pg_switch_wal", "pg_notify",
+	"pg_create_", "pg_drop_replication_slot", "nextval", "setval",
+	"query_to_xml", "cursor_to_xml", "table_to_xml", "schema_to_xml", "database_to_xml",
+}
+
+type tokenKind int
+
+const (
+	tokenWord tokenKind = iota
+	tokenQuotedIdent
+	tokenString
+	tokenNumber
+	tokenSymbol
+)
+
+type token struct {
+	kind tokenKind
+	text string // 关键字和标识符为小写，引号标识符为去掉引号后的内容
+	end  int    // 在原语句中的结束位置
+}
+
+// statement 通过检查的语句
+type statement struct {
+	sql         string          // 去掉结尾分号和注释后的语句
+	identifiers map[string]bool // 语句中出现的标识符（小写），用于判断计算列是否引用了敏感字段
+}
+
+// parseStatement 检查语句是否允许执行
+func parseStatement(sql string) (*statement, error) {
+	if len(sql) > maxStatementLength {
+		return nil, fmt.Errorf("%w: 语句长度超过%d字节", ErrStatementRejected, maxStatementLength)
+	}
+	tokens, err := tokenize(sql)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrStatementRejected, err)
+	}
+	for len(tokens) > 0 && tokens[len(tokens)-1].text == ";" && tokens[len(tokens)-1].kind == tokenSymbol {
+		tokens = tokens[:len(tokens)-1]
+	}
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("%w: 语句为空", ErrStatementRejected)
+	}
+
+	depth := 0
+	leading := ""
+	identifiers := make(map[string]bool)
+	for i, tok := range tokens {
+		if tok.kind == tokenSymbol {
+			switch tok.text {
+			case ";":
+				return nil, fmt.Errorf("%w: 只允许执行单条语句", ErrStatementRejected)
+			case "(":
+				depth++
+			case ")":
+				depth--
+				if depth < 0 {
+					return nil, fmt.Errorf("%w: 括号不匹配", ErrStatementRejected)
+				}
+			}
+			continue
+		}
+		if leading == "" {
+			if tok.kind != tokenWord || !allowedLeadingKeywords[tok.text] {
+				return nil, fmt.Errorf("%w: 只允许执行SELECT查询", ErrStatementRejected)
+			}
+			leading = tok.text
+		}
+		if tok.kind != tokenWord && tok.kind != tokenQuotedIdent {
+			continue
+		}
+		identifiers[strings.ToLower(tok.text)] = true
+		if tok.kind == tokenWord && writeKeywords[tok.text] {
+			return nil, fmt.Errorf("%w: 查询中不允许使用%s", ErrStatementRejected, strings.ToUpper(tok.text))
+		}
+		if i+1 < len(tokens) && tokens[i+1].kind == tokenSymbol && tokens[i+1].text == "(" {
+			if name := strings.ToLower(tok.text); isForbiddenFunction(name) {
+				return nil, fmt.Errorf("%w: 不允许调用函数%s", ErrStatementRejected, name)
+			}
+		}
+	}
+	if depth != 0 {
+		return nil, fmt.Errorf("%w: 括号不匹配", ErrStatementRejected)
+	}
+	if leading == "" {
+		return nil, fmt.Errorf("%w: 只允许执行SELECT查询", ErrStatementRejected)
+	}
+
+	return &statement{sql: strings.TrimSpace(sql[:tokens[len(tokens)-1].end]), identifiers: identifiers}, nil
+}
+
+// CheckExpression 检查嵌入查询的SQL表达式片段（如聚合表达式、过滤条件），不允许子查询、写操作、多语句和危险函数，括号须在片段内配对。
+// 调用方拼接时应将片段放在括号内并前后换行，避免片段中的行注释影响语句其余部分
+func CheckExpression(expression string) error {
+	if len(expression) > maxStatementLength {
+		return fmt.Errorf("%w: 表达式长度超过%d字节", ErrStatementRejected, maxStatementLength)
+	}
+	tokens, err := tokenize(expression)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrStatementRejected, err)
+	}
+	if len(tokens) == 0 {
+		return fmt.Errorf("%w: 表达式为空", ErrStatementRejected)
+	}
+
+	depth := 0
+	for i, tok := range tokens {
+		switch tok.kind {
+		case tokenSymbol:
+			switch tok.text {
+			case ";":
+				return fmt.Errorf("%w: 表达式中不允许使用分号", ErrStatementRejected)
+			case "(":
+				depth++
+			case ")":
+				depth--
+				if depth < 0 {
+					return fmt.Errorf("%w: 括号不匹配", ErrStatementRejected)
+				}
+			}
+		case tokenWord, tokenQuotedIdent:
+			if tok.kind == tokenWord && (tok.text == "select" || writeKeywords[tok.text]) {
+				return fmt.Errorf("%w: 表达式中不允许使用%s", ErrStatementRejected, strings.ToUpper(tok.text))
+			}
+			if i+1 < len(tokens) && tokens[i+1].kind == tokenSymbol && tokens[i+1].text == "(" {
+				if name := strings.ToLower(tok.text); isForbiddenFunction(name) {
+					return fmt.Errorf("%w: 不允许调用函数%s", ErrStatementRejected, name)
+				}
+			}
+		}
+	}
+	if depth != 0 {
+		return fmt.Errorf("%w: 括号不匹配", ErrStatementRejected)
+	}
+	return nil
+}
+
+// isForbiddenFunction 函数是否禁止调用
+func isForbiddenFunction(name string) bool {
+	for _, prefix := range forbiddenFunctionPrefixes {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// limitStatement 为语句加上行数上限，多取一行用于判断是否截断；换行避免语句结尾的行注释吞掉括号
+func limitStatement(sql string, limit int) string {
+	return fmt.Sprintf("SELECT * FROM (\n%s\n) AS workbench_query LIMIT %d", sql, limit+1)
+}
+
+// tokenize 按PostgreSQL词法切分语句，跳过空白和注释
+func tokenize(sql string) ([]token, error) {
+	var tokens []token
+	runes := []rune(sql)
+	offsets := make([]int, len(runes)+1)
+	pos := 0
+	for i, r := range runes {
+		offsets[i] = pos
+		pos += len(string(r))
+	}
+	offsets[len(runes)] = pos
+
+	n := len(runes)
+	for i := 0; i < n; {
+		ch := runes[i]
+		switch {
+		case unicode.IsSpace(ch):
+			i++
+		case ch == '-' && i+1 < n && runes[i+1] == '-':
+			for i < n && runes[i] != '\n' {
+				i++
+			}
+		case ch == '/' && i+1 < n && runes[i+1] == '*':
+			depth := 0
+			for {
+				if i+1 >= n {
+					return nil, errors.New("注释未结束")
+				}
+				if runes[i] == '/' && runes[i+1] == '*' {
+					depth++
+					i += 2
+				} else if runes[i] == '*' && runes[i+1] == '/' {
+					depth--
+					i += 2
+					if depth == 0 {
+						break
+					}
+				} else {
+					i++
+				}
+			}
+		case ch == '\'':
+			end, err := scanString(runes, i, false)
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, token{kind: tokenString, end: offsets[end]})
+			i = end
+		case ch == '"':
+			var ident strings.Builder
+			j := i + 1
+			for {
+				if j >= n {
+					return nil, errors.New("引号标识符未结束")
+				}
+				if runes[j] == '"' {
+					if j+1 < n && runes[j+1] == '"' {
+						ident.WriteRune('"')
+						j += 2
+						continue
+					}
+					break
+				}
+				ident.WriteRune(runes[j])
+				j++
+			}
+			tokens = append(tokens, token{kind: tokenQuotedIdent, text: ident.String(), end: offsets[j+1]})
+			i = j + 1
+		case ch == '$' && i+1 < n && (runes[i+1] == '$' || isIdentStart(runes[i+1])):
+			j := i + 1
+			for j < n && runes[j] != '$' && isIdentPart(runes[j]) {
+				j++
+			}
+			if j >= n || runes[j] != '$' {
+				// 不是美元引用，按普通符号处理
+				tokens = append(tokens, token{kind: tokenSymbol, text: "$", end: offsets[i+1]})
+				i++
+				continue
+			}
+			tag := string(runes[i : j+1])
+			rest := string(runes[j+1:])
+			idx := strings.Index(rest, tag)
+			if idx < 0 {
+				return nil, errors.New("美元引用字符串未结束")
+			}
+			end := j + 1 + len([]rune(rest[:idx])) + len([]rune(tag))
+			tokens = append(tokens, token{kind: tokenString, end: offsets[end]})
+			i = end
+		case isIdentStart(ch):
+			j := i + 1
+			for j < n && isIdentPart(runes[j]) {
+				j++
+			}
+			word := strings.ToLower(string(runes[i:j]))
+			if j < n && runes[j] == '\'' && (word == "e" || word == "b" || word == "x" || word == "n") {
+				// E'...'支持反斜杠转义，B/X/N前缀的字符串按普通字符串处理
+				end, err := scanString(runes, j, word == "e")
+				if err != nil {
+					return nil, err
+				}
+				tokens = append(tokens, token{kind: tokenString, end: offsets[end]})
+				i = end
+				continue
+			}
+			tokens = append(tokens, token{kind: tokenWord, text: word, end: offsets[j]})
+			i = j
+		case unicode.IsDigit(ch) || (ch == '.' && i+1 < n && unicode.IsDigit(runes[i+1])):
+			j := i + 1
+			for j < n && (unicode.IsDigit(runes[j]) || runes[j] == '.' || runes[j] == '_' ||
+				runes[j] == 'e' || runes[j] == 'E' ||
+				((runes[j] == '+' || runes[j] == '-') && (runes[j-1] == 'e' || runes[j-1] == 'E'))) {
+				j++
+			}
+			tokens = append(tokens, token{kind: tokenNumber, text: string(runes[i:j]), end: offsets[j]})
+			i = j
+		default:
+			tokens = append(tokens, token{kind: tokenSymbol, text: string(ch), end: offsets[i+1]})
+			i++
+		}
+	}
+	return tokens, nil
+}
+
+// scanString 扫描从start处单引号开始的字符串，返回结束引号之后的位置
+func scanString(runes []rune, start int, backslashEscape bool) (int, error) {
+	for j := start + 1; j < len(runes); j++ {
+		switch runes[j] {
+		case '\\':
+			if backslashEscape {
+				j++
+			}
+		case '\'':
+			if j+1 < len(runes) && runes[j+1] == '\'' {
+				j++
+				continue
+			}
+			return j + 1, nil
+		}
+	}
+	return 0, errors.New("字符串未结束")
+}
+
+func isIdentStart(ch rune) bool {
+	return ch == '_' || unicode.IsLetter(ch)
+}
+
+func isIdentPart(ch rune) bool {
+	return ch == '_' || ch == '$' || unicode.IsLetter(ch) || unicode.IsDigit(ch)
+}
@@ -0,0 +1,110 @@
+/*
+ * @module service/workbench/guard_test
+ * @description SQL工作台语句静态检查测试，覆盖允许的查询形式、多语句、写操作、危险函数、字符串和注释中的关键字以及行数上限包装
+ * @architecture 测试层 - 纯函数测试，无外部依赖
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow 构造语句 -> 检查 -> 验证是否拒绝和规范化后的语句
+ * @rules 字符串、引号标识符、美元引用和注释中的内容不参与关键字和函数检查
+ * @dependencies testing, testify
+ * @refs guard.go
+ */
+
+package workbench
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseStatementAllowed(t *testing.T) {
+	cases := map[string]string{
+		"SELECT * FROM parking.vehicle_access;":                                          "SELECT * FROM parking.vehicle_access",
+		"  with t as (select 1 as x) select x from t ;; -- 注释":                           "with t as (select 1 as x) select x from t",
+		"(SELECT 1) UNION (SELECT 2)":                                                    "(SELECT 1) UNION (SELECT 2)",
+		"SELECT 'delete; drop table x' AS note, \"insert\" FROM t":                       "SELECT 'delete; drop table x' AS note, \"insert\" FROM t",
+		"SELECT $$pg_sleep(10); update$$ /* insert into */ FROM t":                       "SELECT $$pg_sleep(10); update$$ /* insert into */ FROM t",
+		"SELECT E'it\\'s; delete' , 'it''s' FROM t":                                      "SELECT E'it\\'s; delete' , 'it''s' FROM t",
+		"SELECT plate_no FROM parking.vehicle_access WHERE entered_at > now() - '1 day'": "SELECT plate_no FROM parking.vehicle_access WHERE entered_at > now() - '1 day'",
+		"TABLE parking.vehicle_access":                                                   "TABLE parking.vehicle_access",
+		"SELECT lo_value, sleep_ms FROM t":                                               "SELECT lo_value, sleep_ms FROM t",
+	}
+	for sql, expected := range cases {
+		stmt, err := parseStatement(sql)
+		require.NoError(t, err, sql)
+		assert.Equal(t, expected, stmt.sql, sql)
+	}
+
+	stmt, err := parseStatement(`SELECT upper("Phone") FROM parking.owner`)
+	require.NoError(t, err)
+	assert.True(t, stmt.identifiers["phone"], "引号标识符按小写记录")
+	assert.True(t, stmt.identifiers["owner"])
+}
+
+func TestParseStatementRejected(t *testing.T) {
+	cases := map[string]string{
+		"":                         "语句为空",
+		" ; ":                      "语句为空",
+		"SELECT 1; DROP TABLE t":   "单条语句",
+		"DELETE FROM t":            "SELECT",
+		"EXPLAIN ANALYZE SELECT 1": "SELECT",
+		"SET ROLE admin":           "SELECT",
+		"WITH d AS (DELETE FROM t RETURNING *) SELECT * FROM d":  "DELETE",
+		"SELECT * INTO backup FROM t":                            "INTO",
+		"SELECT * FROM t FOR UPDATE":                             "UPDATE",
+		"SELECT pg_sleep(100)":                                   "pg_sleep",
+		"SELECT pg_catalog.pg_read_file ('/etc/passwd')":         "pg_read_file",
+		`SELECT "PG_LS_DIR"('.')`:                                "pg_ls_dir",
+		"SELECT * FROM dblink('host=x', 'select 1') AS t(a int)": "dblink",
+		"SELECT set_config('role', 'admin', true)":               "set_config",
+		"SELECT 1) AS x; (SELECT 2":                              "括号不匹配",
+		"SELECT 1) UNION (SELECT 2":                              "括号不匹配",
+		"SELECT (1":                                              "括号不匹配",
+		"SELECT 'unterminated":                                   "字符串未结束",
+		"SELECT 1 /* unterminated":                               "注释未结束",
+		"SELECT $tag$ body":                                      "美元引用",
+	}
+	for sql, reason := range cases {
+		_, err := parseStatement(sql)
+		require.ErrorIs(t, err, ErrStatementRejected, sql)
+		assert.Contains(t, err.Error(), reason, sql)
+	}
+}
+
+func TestLimitStatement(t *testing.T) {
+	stmt, err := parseStatement("SELECT * FROM t -- 最近的数据")
+	require.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM (\nSELECT * FROM t\n) AS workbench_query LIMIT 101", limitStatement(stmt.sql, 100))
+
+	wrapped := limitStatement("SELECT * FROM t ORDER BY id -- 注释保留时也不会吞掉括号", 10)
+	assert.Contains(t, wrapped, "\n) AS workbench_query LIMIT 11")
+}
+
+func TestCheckExpression(t *testing.T) {
+	for _, expression := range []string{
+		"count(*)",
+		"sum(amount) / nullif(count(DISTINCT plate_no), 0)",
+		"direction = 'in' AND note <> 'select; --'",
+		"date_part('hour', entered_at) BETWEEN 7 AND 9",
+		"status = 'ok' -- 行注释",
+	} {
+		assert.NoError(t, CheckExpression(expression), expression)
+	}
+
+	cases := map[string]string{
+		"":                                   "表达式为空",
+		"count(*); DROP TABLE t":             "分号",
+		"x IN (SELECT id FROM other.secret)": "SELECT",
+		"1 = 1) UNION (SELECT 1":             "括号不匹配",
+		"sum(amount":                         "括号不匹配",
+		"pg_sleep(10) IS NULL":               "pg_sleep",
+		"count(*) FILTER (WHERE x) INTO y":   "INTO",
+		"note = 'unterminated":               "字符串未结束",
+	}
+	for expression, reason := range cases {
+		err := CheckExpression(expression)
+		require.ErrorIs(t, err, ErrStatementRejected, expression)
+		assert.Contains(t, err.Error(), reason, expression)
+	}
+}
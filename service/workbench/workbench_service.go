@@ -0,0 +1,567 @@
+/*
+ * @module service/workbench/workbench_service
+ * @description SQL工作台服务，分析人员在当前租户的基础库和主题库schema上执行只读查询，结果按敏感字段配置脱敏，支持保存查询并审计每次执行
+ * @architecture 分层架构 - 业务服务层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow 语句（直接提交或保存的查询） -> 静态检查 -> EXPLAIN解析访问的表 -> schema白名单和行级策略检查 -> 加行数上限执行 -> 脱敏 -> 写入系统日志审计 -> 返回结果
+ * @rules
+ *   - 允许查询的schema为当前租户可见的基础库和主题库schema，配置SQL_WORKBENCH_SCHEMAS时再取交集；public和系统schema始终不允许
+ *   - 配置了启用的行级安全策略的表只有管理员可以查询，其他用户须通过数据查看接口访问
+ *   - 行数上限和语句超时取运行时配置workbench_max_rows、workbench_statement_timeout_seconds，请求的limit只能更小
+ *   - 来源为敏感字段（接口表字段配置is_sensitive）的列对管理员以外的用户脱敏；计算列在语句引用了所访问表的敏感字段时脱敏；加密列始终遮蔽
+ *   - 被拒绝、失败、超时和成功的执行都写入系统日志（object_type=sql_workbench），审计写入失败时不返回结果
+ *   - 保存的查询只有创建人和管理员可以修改、删除，共享的查询同租户用户可以查看和执行
+ * @dependencies datahub-service/service/config, datahub-service/service/models, gorm.io/gorm
+ * @refs service/workbench/guard.go, service/workbench/executor.go, api/controllers/sql_workbench_controller.go
+ */
+
+package workbench
+
+import (
+	"context"
+	"datahub-service/logger"
+	"datahub-service/service/config"
+	"datahub-service/service/encryption"
+	"datahub-service/service/models"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// 审计日志的对象类型和操作类型
+const (
+	AuditObjectType = "sql_workbench"
+	auditOperation  = "query"
+)
+
+// 查询执行状态
+const (
+	QueryStatusSucceeded = "succeeded"
+	QueryStatusRejected  = "rejected" // 未通过静态检查或访问控制
+	QueryStatusFailed    = "failed"
+	QueryStatusTimeout   = "timeout"
+)
+
+// 访问控制和保存查询错误
+var (
+	ErrAccessDenied        = errors.New("无权查询语句访问的表")
+	ErrSavedQueryForbidden = errors.New("只有创建人和管理员可以修改或删除该查询")
+	ErrInvalidSavedQuery   = errors.New("保存的查询不合法")
+)
+
+// systemSchemas 始终不允许查询的schema
+var systemSchemas = map[string]bool{"public": true, "pg_catalog": true, "information_schema": true, "pg_toast": true}
+
+// Subject 执行查询的用户
+type Subject struct {
+	Username string
+	Roles    []string
+	ClientIP string
+}
+
+// IsAdmin 是否为管理员
+func (s Subject) IsAdmin() bool {
+	for _, role := range s.Roles {
+		if role == models.RoleAdmin {
+			return true
+		}
+	}
+	return false
+}
+
+// QueryRequest 执行查询请求，sql和saved_query_id二选一
+type QueryRequest struct {
+	SQL          string `json:"sql" example:"SELECT plate_no, entered_at FROM parking.vehicle_access ORDER BY entered_at DESC"`
+	SavedQueryID string `json:"saved_query_id,omitempty"`
+	Limit        int    `json:"limit,omitempty" example:"100"` // 最多返回行数，不超过workbench_max_rows
+}
+
+// QueryResult 查询结果
+type QueryResult struct {
+	Columns       []Column        `json:"columns"`
+	Rows          [][]interface{} `json:"rows"` // 按列顺序排列的行，同名列不会互相覆盖
+	RowCount      int             `json:"row_count"`
+	Truncated     bool            `json:"truncated"` // 结果超过limit，只返回了前limit行
+	Limit         int             `json:"limit"`
+	Tables        []string        `json:"tables"`                   // 语句实际访问的表
+	MaskedColumns []string        `json:"masked_columns,omitempty"` // 已脱敏的列
+	DurationMs    int64           `json:"duration_ms"`
+}
+
+// SavedQueryRequest 保存查询请求
+type SavedQueryRequest struct {
+	Name        string `json:"name" validate:"required,max=255" example:"近一天车辆进出"`
+	Description string `json:"description" validate:"max=1000"`
+	SQL         string `json:"sql" validate:"required" example:"SELECT plate_no, entered_at FROM parking.vehicle_access"`
+	IsShared    bool   `json:"is_shared"`
+}
+
+// libraryRef schema所属的库
+type libraryRef struct {
+	id       string
+	thematic bool
+}
+
+// Service SQL工作台服务
+type Service struct {
+	db       *gorm.DB
+	executor Executor
+	schemas  []string // SQL_WORKBENCH_SCHEMAS配置的schema白名单
+}
+
+// NewService 创建SQL工作台服务，语句在readDB上执行
+func NewService(db, readDB *gorm.DB) *Service {
+	var schemas []string
+	for _, schema := range strings.Split(os.Getenv("SQL_WORKBENCH_SCHEMAS"), ",") {
+		if schema = strings.TrimSpace(schema); schema != "" {
+			schemas = append(schemas, schema)
+		}
+	}
+	return &Service{
+		db:       db,
+		executor: NewPostgresExecutor(readDB, os.Getenv("SQL_WORKBENCH_ROLE")),
+		schemas:  schemas,
+	}
+}
+
+// Execute 执行查询并写入审计日志
+func (s *Service) Execute(ctx context.Context, req *QueryRequest, subject Subject) (*QueryResult, error) {
+	sqlText := req.SQL
+	var saved *models.SavedQuery
+	if req.SavedQueryID != "" {
+		query, err := s.GetSavedQuery(ctx, req.SavedQueryID, subject)
+		if err != nil {
+			return nil, err
+		}
+		saved = query
+		sqlText = query.SQL
+	}
+
+	start := time.Now()
+	result, err := s.execute(ctx, sqlText, req.Limit, subject)
+	duration := time.Since(start).Milliseconds()
+
+	content := models.JSONB{
+		"status":      queryStatus(err),
+		"sql":         sqlText,
+		"duration_ms": duration,
+	}
+	if saved != nil {
+		content["saved_query_id"] = saved.ID
+		content["saved_query_name"] = saved.Name
+	}
+	if requestID := logger.RequestIDFromContext(ctx); requestID != "" {
+		content["request_id"] = requestID
+	}
+	if result != nil {
+		result.DurationMs = duration
+		content["tables"] = result.Tables
+		content["row_count"] = result.RowCount
+		content["truncated"] = result.Truncated
+		content["limit"] = result.Limit
+		content["masked_columns"] = result.MaskedColumns
+	}
+	if err != nil {
+		content["error"] = err.Error()
+	}
+	if auditErr := s.audit(ctx, subject, saved, content, err == nil); auditErr != nil {
+		return nil, auditErr
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if saved != nil {
+		now := time.Now()
+		if err := s.db.WithContext(ctx).Model(&models.SavedQuery{}).Where("id = ?", saved.ID).
+			UpdateColumn("last_run_at", now).Error; err != nil {
+			slog.WarnContext(ctx, "更新查询最近执行时间失败", "saved_query_id", saved.ID, "error", err)
+		}
+	}
+	return result, nil
+}
+
+// execute 检查并执行语句
+func (s *Service) execute(ctx context.Context, sqlText string, requestLimit int, subject Subject) (*QueryResult, error) {
+	stmt, err := parseStatement(sqlText)
+	if err != nil {
+		return nil, err
+	}
+	limit := config.WorkbenchMaxRows()
+	if requestLimit > 0 && requestLimit < limit {
+		limit = requestLimit
+	}
+	timeout := config.WorkbenchStatementTimeout()
+
+	libraries, err := s.allowedSchemas(ctx)
+	if err != nil {
+		return nil, err
+	}
+	relations, err := s.executor.Relations(ctx, stmt.sql, timeout)
+	if err != nil {
+		return nil, err
+	}
+	result := &QueryResult{Limit: limit, Tables: make([]string, 0, len(relations))}
+	for _, relation := range relations {
+		result.Tables = append(result.Tables, relation.String())
+	}
+	for _, relation := range relations {
+		if _, ok := libraries[relation.Schema]; !ok {
+			return result, fmt.Errorf("%w: %s不在允许查询的schema中", ErrAccessDenied, relation)
+		}
+	}
+	if !subject.IsAdmin() {
+		if err := s.checkRowPolicies(ctx, relations); err != nil {
+			return result, err
+		}
+	}
+
+	rs, err := s.executor.Query(ctx, limitStatement(stmt.sql, limit), timeout)
+	if err != nil {
+		return result, err
+	}
+	if len(rs.Rows) > limit {
+		rs.Rows = rs.Rows[:limit]
+		result.Truncated = true
+	}
+
+	if err := s.maskResult(ctx, rs, relations, libraries, stmt, subject); err != nil {
+		return result, err
+	}
+	result.Columns = rs.Columns
+	result.Rows = rs.Rows
+	if result.Rows == nil {
+		result.Rows = [][]interface{}{}
+	}
+	result.RowCount = len(result.Rows)
+	for _, column := range rs.Columns {
+		if column.Masked {
+			result.MaskedColumns = append(result.MaskedColumns, column.Name)
+		}
+	}
+	return result, nil
+}
+
+// allowedSchemas 当前租户允许查询的schema及其所属的库
+func (s *Service) allowedSchemas(ctx context.Context) (map[string][]libraryRef, error) {
+	var basics []models.BasicLibrary
+	if err := s.db.WithContext(ctx).Select("id", "name_en", "schema_name").Find(&basics).Error; err != nil {
+		return nil, fmt.Errorf("查询基础库失败: %w", err)
+	}
+	var thematics []models.ThematicLibrary
+	if err := s.db.WithContext(ctx).Select("id", "name_en", "schema_name").Find(&thematics).Error; err != nil {
+		return nil, fmt.Errorf("查询主题库失败: %w", err)
+	}
+
+	configured := make(map[string]bool, len(s.schemas))
+	for _, schema := range s.schemas {
+		configured[schema] = true
+	}
+	libraries := make(map[string][]libraryRef)
+	add := func(schema string, ref libraryRef) {
+		if systemSchemas[schema] || (len(configured) > 0 && !configured[schema]) {
+			return
+		}
+		libraries[schema] = append(libraries[schema], ref)
+	}
+	for _, library := range basics {
+		add(library.GetSchemaName(), libraryRef{id: library.ID})
+	}
+	for _, library := range thematics {
+		add(library.GetSchemaName(), libraryRef{id: library.ID, thematic: true})
+	}
+	return libraries, nil
+}
+
+// checkRowPolicies 语句访问的表配置了启用的行级安全策略时拒绝
+func (s *Service) checkRowPolicies(ctx context.Context, relations []Relation) error {
+	for _, relation := range relations {
+		var count int64
+		if err := s.db.WithContext(ctx).Model(&models.RowLevelPolicy{}).
+			Where("schema_name = ? AND table_name = ? AND is_enabled = ?", relation.Schema, relation.Table, true).
+			Count(&count).Error; err != nil {
+			return fmt.Errorf("查询行级安全策略失败: %w", err)
+		}
+		if count > 0 {
+			return fmt.Errorf("%w: %s配置了行级安全策略，请通过数据查看接口访问", ErrAccessDenied, relation)
+		}
+	}
+	return nil
+}
+
+// maskResult 按敏感字段和加密列配置脱敏结果
+func (s *Service) maskResult(ctx context.Context, rs *ResultSet, relations []Relation, libraries map[string][]libraryRef, stmt *statement, subject Subject) error {
+	tables := make(map[Relation]bool, len(relations))
+	for _, relation := range relations {
+		tables[relation] = true
+	}
+	for _, column := range rs.Columns {
+		if column.SourceTable != "" {
+			tables[Relation{Schema: column.SourceSchema, Table: column.SourceTable}] = true
+		}
+	}
+
+	sensitive, err := s.sensitiveColumns(ctx, tables, libraries)
+	if err != nil {
+		return err
+	}
+	masked, err := s.encryptedColumns(ctx, tables)
+	if err != nil {
+		return err
+	}
+	if !subject.IsAdmin() {
+		for relation, columns := range sensitive {
+			for column := range columns {
+				masked[relation] = addColumn(masked[relation], column)
+			}
+		}
+	}
+
+	// 计算列引用了访问表中需要脱敏的字段时脱敏
+	maskComputed := false
+	for relation := range tables {
+		for column := range masked[relation] {
+			if stmt.identifiers[strings.ToLower(column)] {
+				maskComputed = true
+			}
+		}
+	}
+
+	for i := range rs.Columns {
+		column := &rs.Columns[i]
+		if column.SourceTable == "" {
+			column.Masked = maskComputed
+		} else {
+			column.Masked = masked[Relation{Schema: column.SourceSchema, Table: column.SourceTable}][column.SourceColumn]
+		}
+		if !column.Masked {
+			continue
+		}
+		for _, row := range rs.Rows {
+			if row[i] != nil {
+				row[i] = encryption.MaskedValue
+			}
+		}
+	}
+	return nil
+}
+
+// sensitiveColumns 读取访问的表在接口字段配置中标记为敏感的字段
+func (s *Service) sensitiveColumns(ctx context.Context, tables map[Relation]bool, libraries map[string][]libraryRef) (map[Relation]map[string]bool, error) {
+	sensitive := make(map[Relation]map[string]bool)
+	for relation := range tables {
+		for _, ref := range libraries[relation.Schema] {
+			var configs []models.JSONB
+			model := interface{}(&models.DataInterface{})
+			if ref.thematic {
+				model = &models.ThematicInterface{}
+			}
+			if err := s.db.WithContext(ctx).Model(model).
+				Where("library_id = ? AND name_en = ?", ref.id, relation.Table).
+				Pluck("table_fields_config", &configs).Error; err != nil {
+				return nil, fmt.Errorf("查询接口字段配置失败: %w", err)
+			}
+			for _, fieldsConfig := range configs {
+				for _, value := range fieldsConfig {
+					var field models.TableField
+					bytes, _ := json.Marshal(value)
+					if json.Unmarshal(bytes, &field) == nil && field.IsSensitive && field.NameEn != "" {
+						sensitive[relation] = addColumn(sensitive[relation], field.NameEn)
+					}
+				}
+			}
+		}
+	}
+	return sensitive, nil
+}
+
+// encryptedColumns 读取访问的表中配置了加密的列，工作台不解密，始终遮蔽
+func (s *Service) encryptedColumns(ctx context.Context, tables map[Relation]bool) (map[Relation]map[string]bool, error) {
+	encrypted := make(map[Relation]map[string]bool)
+	if len(tables) == 0 {
+		return encrypted, nil
+	}
+	schemas := make([]string, 0, len(tables))
+	for relation := range tables {
+		schemas = append(schemas, relation.Schema)
+	}
+	var configs []models.ColumnEncryption
+	if err := s.db.WithContext(ctx).Where("schema_name IN ?", schemas).Find(&configs).Error; err != nil {
+		return nil, fmt.Errorf("查询列加密配置失败: %w", err)
+	}
+	for _, cfg := range configs {
+		relation := Relation{Schema: cfg.SchemaName, Table: cfg.TableName}
+		if tables[relation] {
+			encrypted[relation] = addColumn(encrypted[relation], cfg.ColumnName)
+		}
+	}
+	return encrypted, nil
+}
+
+func addColumn(columns map[string]bool, column string) map[string]bool {
+	if columns == nil {
+		columns = make(map[string]bool)
+	}
+	columns[column] = true
+	return columns
+}
+
+// queryStatus 执行结果对应的审计状态
+func queryStatus(err error) string {
+	switch {
+	case err == nil:
+		return QueryStatusSucceeded
+	case errors.Is(err, ErrStatementRejected) || errors.Is(err, ErrAccessDenied):
+		return QueryStatusRejected
+	case errors.Is(err, ErrQueryTimeout):
+		return QueryStatusTimeout
+	default:
+		return QueryStatusFailed
+	}
+}
+
+// audit 写入查询审计日志
+func (s *Service) audit(ctx context.Context, subject Subject, saved *models.SavedQuery, content models.JSONB, succeeded bool) error {
+	result := "success"
+	if !succeeded {
+		result = "failure"
+	}
+	log := &models.SystemLog{
+		OperationType:    auditOperation,
+		ObjectType:       AuditObjectType,
+		OperatorName:     &subject.Username,
+		OperationContent: content,
+		OperationTime:    time.Now(),
+		OperationResult:  result,
+		CreatedBy:        subject.Username,
+	}
+	if saved != nil {
+		log.ObjectID = &saved.ID
+	}
+	if subject.ClientIP != "" {
+		log.OperatorIP = &subject.ClientIP
+	}
+	if err := s.db.WithContext(ctx).Create(log).Error; err != nil {
+		slog.ErrorContext(ctx, "写入SQL工作台审计日志失败", "username", subject.Username, "error", err)
+		return fmt.Errorf("写入审计日志失败: %w", err)
+	}
+	return nil
+}
+
+// GetHistory 获取用户自己的执行记录，按执行时间倒序
+func (s *Service) GetHistory(ctx context.Context, username string, page, pageSize int) ([]models.SystemLog, int64, error) {
+	query := s.db.WithContext(ctx).Model(&models.SystemLog{}).
+		Where("object_type = ? AND operator_name = ?", AuditObjectType, username)
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	var logs []models.SystemLog
+	if err := query.Order("operation_time DESC").Offset((page - 1) * pageSize).Limit(pageSize).Find(&logs).Error; err != nil {
+		return nil, 0, err
+	}
+	return logs, total, nil
+}
+
+// === 保存的查询 ===
+
+// CreateSavedQuery 保存查询，语句须通过静态检查
+func (s *Service) CreateSavedQuery(ctx context.Context, req *SavedQueryRequest, operator string) (*models.SavedQuery, error) {
+	if err := validateSavedQuery(req); err != nil {
+		return nil, err
+	}
+	query := &models.SavedQuery{
+		Name:        strings.TrimSpace(req.Name),
+		Description: req.Description,
+		SQL:         strings.TrimSpace(req.SQL),
+		IsShared:    req.IsShared,
+		CreatedBy:   operator,
+		UpdatedBy:   operator,
+	}
+	if err := s.db.WithContext(ctx).Create(query).Error; err != nil {
+		return nil, err
+	}
+	return query, nil
+}
+
+// ListSavedQueries 列出自己创建的和共享的查询
+func (s *Service) ListSavedQueries(ctx context.Context, username, keyword string, page, pageSize int) ([]models.SavedQuery, int64, error) {
+	query := s.db.WithContext(ctx).Model(&models.SavedQuery{}).Where("created_by = ? OR is_shared = ?", username, true)
+	if keyword != "" {
+		query = query.Where("name LIKE ?", "%"+keyword+"%")
+	}
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	var queries []models.SavedQuery
+	if err := query.Order("updated_at DESC").Offset((page - 1) * pageSize).Limit(pageSize).Find(&queries).Error; err != nil {
+		return nil, 0, err
+	}
+	return queries, total, nil
+}
+
+// GetSavedQuery 获取自己创建的或共享的查询，其他用户的私有查询按不存在处理
+func (s *Service) GetSavedQuery(ctx context.Context, id string, subject Subject) (*models.SavedQuery, error) {
+	var query models.SavedQuery
+	if err := s.db.WithContext(ctx).First(&query, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	if query.CreatedBy != subject.Username && !query.IsShared && !subject.IsAdmin() {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &query, nil
+}
+
+// UpdateSavedQuery 修改保存的查询
+func (s *Service) UpdateSavedQuery(ctx context.Context, id string, req *SavedQueryRequest, subject Subject) (*models.SavedQuery, error) {
+	if err := validateSavedQuery(req); err != nil {
+		return nil, err
+	}
+	query, err := s.GetSavedQuery(ctx, id, subject)
+	if err != nil {
+		return nil, err
+	}
+	if query.CreatedBy != subject.Username && !subject.IsAdmin() {
+		return nil, ErrSavedQueryForbidden
+	}
+	query.Name = strings.TrimSpace(req.Name)
+	query.Description = req.Description
+	query.SQL = strings.TrimSpace(req.SQL)
+	query.IsShared = req.IsShared
+	query.UpdatedBy = subject.Username
+	if err := s.db.WithContext(ctx).Model(query).Select("name", "description", "sql_text", "is_shared", "updated_by", "updated_at").
+		Updates(query).Error; err != nil {
+		return nil, err
+	}
+	return query, nil
+}
+
+// DeleteSavedQuery 删除保存的查询
+func (s *Service) DeleteSavedQuery(ctx context.Context, id string, subject Subject) error {
+	query, err := s.GetSavedQuery(ctx, id, subject)
+	if err != nil {
+		return err
+	}
+	if query.CreatedBy != subject.Username && !subject.IsAdmin() {
+		return ErrSavedQueryForbidden
+	}
+	return s.db.WithContext(ctx).Delete(&models.SavedQuery{}, "id = ?", id).Error
+}
+
+// validateSavedQuery 校验保存查询请求
+func validateSavedQuery(req *SavedQueryRequest) error {
+	if strings.TrimSpace(req.Name) == "" {
+		return fmt.Errorf("%w: 名称不能为空", ErrInvalidSavedQuery)
+	}
+	if _, err := parseStatement(req.SQL); err != nil {
+		return err
+	}
+	return nil
+}
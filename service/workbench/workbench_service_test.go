@@ -0,0 +1,294 @@
+/*
+ * @module service/workbench/workbench_service_test
+ * @description SQL工作台服务测试，覆盖schema白名单、行级策略、行数截断、敏感列和加密列脱敏、超时、审计记录、执行计划解析以及保存查询的可见性和权限
+ * @architecture 测试层
+ * @documentReference ai_docs/requirements.md
+ * @stateFlow 准备sqlite中的库、接口字段配置、策略和加密配置 -> 使用模拟执行器执行 -> 验证结果、脱敏和系统日志
+ * @rules 使用内存sqlite和模拟执行器，不依赖PostgreSQL；运行时配置未加载时使用默认的行数上限和超时
+ * @dependencies gorm.io/driver/sqlite, stretchr/testify
+ * @refs workbench_service.go, guard.go, executor.go
+ */
+
+package workbench
+
+import (
+	"context"
+	"datahub-service/service/encryption"
+	"datahub-service/service/models"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// fakeExecutor 返回预设的访问表和查询结果，记录执行的语句
+type fakeExecutor struct {
+	relations []Relation
+	result    *ResultSet
+	err       error
+	queries   []string
+}
+
+func (f *fakeExecutor) Relations(ctx context.Context, sql string, timeout time.Duration) ([]Relation, error) {
+	return f.relations, nil
+}
+
+func (f *fakeExecutor) Query(ctx context.Context, sql string, timeout time.Duration) (*ResultSet, error) {
+	f.queries = append(f.queries, sql)
+	if f.err != nil {
+		return nil, f.err
+	}
+	rows := make([][]interface{}, len(f.result.Rows))
+	for i, row := range f.result.Rows {
+		rows[i] = append([]interface{}(nil), row...)
+	}
+	return &ResultSet{Columns: append([]Column(nil), f.result.Columns...), Rows: rows}, nil
+}
+
+var (
+	analyst = Subject{Username: "analyst", Roles: []string{models.RoleConsumer}, ClientIP: "10.0.0.8"}
+	admin   = Subject{Username: "root", Roles: []string{models.RoleAdmin}}
+)
+
+func setupWorkbench(t *testing.T) (*Service, *fakeExecutor, *gorm.DB) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(
+		&models.BasicLibrary{}, &models.DataInterface{}, &models.ThematicLibrary{}, &models.ThematicInterface{},
+		&models.RowLevelPolicy{}, &models.ColumnEncryption{}, &models.SystemLog{}, &models.SavedQuery{},
+	))
+
+	require.NoError(t, db.Create(&models.BasicLibrary{ID: "lib-1", NameZh: "停车管理", NameEn: "parking"}).Error)
+	require.NoError(t, db.Create(&models.ThematicLibrary{ID: "lib-2", NameZh: "人口主题库", NameEn: "population", Category: "business", Domain: "user"}).Error)
+	require.NoError(t, db.Create(&models.DataInterface{
+		ID: "if-1", LibraryID: "lib-1", NameZh: "车辆进出", NameEn: "vehicle_access", Type: "batch",
+		TableFieldsConfig: models.JSONB{
+			"field_0": map[string]interface{}{"name_en": "id", "is_primary_key": true},
+			"field_1": map[string]interface{}{"name_en": "plate_no", "is_sensitive": true},
+			"field_2": map[string]interface{}{"name_en": "entered_at"},
+		},
+	}).Error)
+	require.NoError(t, db.Create(&models.ThematicInterface{
+		ID: "ti-1", LibraryID: "lib-2", NameZh: "人员", NameEn: "person", Type: "table",
+		TableFieldsConfig: models.JSONB{
+			"field_0": map[string]interface{}{"name_en": "name"},
+			"field_1": map[string]interface{}{"name_en": "id_card", "is_sensitive": true},
+		},
+	}).Error)
+	require.NoError(t, db.Create(&models.ColumnEncryption{SchemaName: "population", TableName: "person", ColumnName: "phone"}).Error)
+
+	executor := &fakeExecutor{
+		relations: []Relation{{Schema: "parking", Table: "vehicle_access"}},
+		result: &ResultSet{
+			Columns: []Column{
+				{Name: "id", Type: "int8", SourceSchema: "parking", SourceTable: "vehicle_access", SourceColumn: "id"},
+				{Name: "plate_no", Type: "varchar", SourceSchema: "parking", SourceTable: "vehicle_access", SourceColumn: "plate_no"},
+			},
+			Rows: [][]interface{}{{int64(1), "沪A12345"}, {int64(2), nil}, {int64(3), "沪B54321"}},
+		},
+	}
+	return &Service{db: db, executor: executor}, executor, db
+}
+
+func lastAudit(t *testing.T, db *gorm.DB) models.SystemLog {
+	var log models.SystemLog
+	require.NoError(t, db.Where("object_type = ?", AuditObjectType).Order("operation_time DESC").First(&log).Error)
+	return log
+}
+
+func TestExecuteMasksSensitiveColumns(t *testing.T) {
+	svc, executor, db := setupWorkbench(t)
+	ctx := context.Background()
+
+	result, err := svc.Execute(ctx, &QueryRequest{SQL: "SELECT id, plate_no FROM parking.vehicle_access;", Limit: 2}, analyst)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"SELECT * FROM (\nSELECT id, plate_no FROM parking.vehicle_access\n) AS workbench_query LIMIT 3"}, executor.queries)
+	assert.Equal(t, [][]interface{}{{int64(1), encryption.MaskedValue}, {int64(2), nil}}, result.Rows, "空值不遮蔽")
+	assert.True(t, result.Truncated)
+	assert.Equal(t, 2, result.RowCount)
+	assert.Equal(t, []string{"plate_no"}, result.MaskedColumns)
+	assert.Equal(t, []string{"parking.vehicle_access"}, result.Tables)
+
+	log := lastAudit(t, db)
+	assert.Equal(t, "success", log.OperationResult)
+	assert.Equal(t, "analyst", *log.OperatorName)
+	assert.Equal(t, "10.0.0.8", *log.OperatorIP)
+	assert.Equal(t, QueryStatusSucceeded, log.OperationContent["status"])
+	assert.Equal(t, "SELECT id, plate_no FROM parking.vehicle_access;", log.OperationContent["sql"])
+	assert.Equal(t, float64(2), log.OperationContent["row_count"])
+	assert.Equal(t, true, log.OperationContent["truncated"])
+
+	result, err = svc.Execute(ctx, &QueryRequest{SQL: "SELECT id, plate_no FROM parking.vehicle_access"}, admin)
+	require.NoError(t, err)
+	assert.Equal(t, "沪A12345", result.Rows[0][1], "管理员看到敏感字段明文")
+	assert.Empty(t, result.MaskedColumns)
+	assert.False(t, result.Truncated)
+	assert.Equal(t, 1000, result.Limit, "未指定limit时使用workbench_max_rows")
+}
+
+func TestExecuteMasksComputedAndEncryptedColumns(t *testing.T) {
+	svc, executor, _ := setupWorkbench(t)
+	executor.relations = []Relation{{Schema: "population", Table: "person"}}
+	executor.result = &ResultSet{
+		Columns: []Column{
+			{Name: "name", SourceSchema: "population", SourceTable: "person", SourceColumn: "name"},
+			{Name: "phone", SourceSchema: "population", SourceTable: "person", SourceColumn: "phone"},
+			{Name: "birth_year"},
+		},
+		Rows: [][]interface{}{{"张三", "enc:v1:abc", "1990"}},
+	}
+
+	result, err := svc.Execute(context.Background(), &QueryRequest{SQL: "SELECT name, phone, substr(ID_CARD, 7, 4) AS birth_year FROM population.person"}, analyst)
+	require.NoError(t, err)
+	assert.Equal(t, []interface{}{"张三", encryption.MaskedValue, encryption.MaskedValue}, result.Rows[0], "计算列引用了敏感字段")
+	assert.Equal(t, []string{"phone", "birth_year"}, result.MaskedColumns)
+
+	result, err = svc.Execute(context.Background(), &QueryRequest{SQL: "SELECT name, phone, substr(ID_CARD, 7, 4) AS birth_year FROM population.person"}, admin)
+	require.NoError(t, err)
+	assert.Equal(t, []interface{}{"张三", encryption.MaskedValue, encryption.MaskedValue}, result.Rows[0], "加密列对管理员也遮蔽，语句引用了加密列时计算列同样遮蔽")
+
+	executor.result = &ResultSet{
+		Columns: []Column{{Name: "name", SourceSchema: "population", SourceTable: "person", SourceColumn: "name"}, {Name: "birth_year"}},
+		Rows:    [][]interface{}{{"张三", "1990"}},
+	}
+	result, err = svc.Execute(context.Background(), &QueryRequest{SQL: "SELECT name, substr(ID_CARD, 7, 4) AS birth_year FROM population.person"}, admin)
+	require.NoError(t, err)
+	assert.Equal(t, []interface{}{"张三", "1990"}, result.Rows[0], "管理员不脱敏敏感字段")
+
+	executor.result = &ResultSet{
+		Columns: []Column{{Name: "name", SourceSchema: "population", SourceTable: "person", SourceColumn: "name"}, {Name: "name_length"}},
+		Rows:    [][]interface{}{{"张三", int64(2)}},
+	}
+	result, err = svc.Execute(context.Background(), &QueryRequest{SQL: "SELECT name, length(name) AS name_length FROM population.person"}, analyst)
+	require.NoError(t, err)
+	assert.Equal(t, []interface{}{"张三", int64(2)}, result.Rows[0], "计算列未引用敏感字段时不脱敏")
+}
+
+func TestExecuteAccessControl(t *testing.T) {
+	svc, executor, db := setupWorkbench(t)
+	ctx := context.Background()
+
+	executor.relations = []Relation{{Schema: "parking", Table: "vehicle_access"}, {Schema: "pg_catalog", Table: "pg_authid"}}
+	_, err := svc.Execute(ctx, &QueryRequest{SQL: "SELECT * FROM pg_shadow"}, admin)
+	assert.ErrorIs(t, err, ErrAccessDenied)
+	assert.Contains(t, err.Error(), "pg_catalog.pg_authid")
+	log := lastAudit(t, db)
+	assert.Equal(t, "failure", log.OperationResult)
+	assert.Equal(t, QueryStatusRejected, log.OperationContent["status"])
+	assert.Equal(t, []interface{}{"parking.vehicle_access", "pg_catalog.pg_authid"}, log.OperationContent["tables"])
+	assert.Empty(t, executor.queries, "访问控制检查未通过时不执行语句")
+
+	_, err = svc.Execute(ctx, &QueryRequest{SQL: "DELETE FROM parking.vehicle_access"}, admin)
+	assert.ErrorIs(t, err, ErrStatementRejected)
+	assert.Equal(t, QueryStatusRejected, lastAudit(t, db).OperationContent["status"])
+
+	svc.schemas = []string{"population"}
+	executor.relations = []Relation{{Schema: "parking", Table: "vehicle_access"}}
+	_, err = svc.Execute(ctx, &QueryRequest{SQL: "SELECT * FROM parking.vehicle_access"}, admin)
+	assert.ErrorIs(t, err, ErrAccessDenied, "SQL_WORKBENCH_SCHEMAS未包含的schema")
+	svc.schemas = nil
+
+	require.NoError(t, db.Create(&models.RowLevelPolicy{
+		Name: "按停车场过滤", SchemaName: "parking", TableName: "vehicle_access", SubjectType: "role", SubjectID: models.RoleConsumer,
+		Conditions: models.JSONBArray{{"field": "lot_id", "operator": "eq", "value": "lot-1"}}, IsEnabled: true,
+	}).Error)
+	_, err = svc.Execute(ctx, &QueryRequest{SQL: "SELECT * FROM parking.vehicle_access"}, analyst)
+	assert.ErrorIs(t, err, ErrAccessDenied)
+	assert.Contains(t, err.Error(), "行级安全策略")
+	_, err = svc.Execute(ctx, &QueryRequest{SQL: "SELECT * FROM parking.vehicle_access"}, admin)
+	assert.NoError(t, err, "管理员不受行级策略限制")
+}
+
+func TestExecuteTimeout(t *testing.T) {
+	svc, executor, db := setupWorkbench(t)
+	executor.err = ErrQueryTimeout
+
+	_, err := svc.Execute(context.Background(), &QueryRequest{SQL: "SELECT * FROM parking.vehicle_access"}, analyst)
+	assert.ErrorIs(t, err, ErrQueryTimeout)
+	log := lastAudit(t, db)
+	assert.Equal(t, QueryStatusTimeout, log.OperationContent["status"])
+	assert.Equal(t, ErrQueryTimeout.Error(), log.OperationContent["error"])
+
+	history, total, err := svc.GetHistory(context.Background(), "analyst", 1, 10)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	assert.Equal(t, log.ID, history[0].ID)
+	_, total, err = svc.GetHistory(context.Background(), "root", 1, 10)
+	require.NoError(t, err)
+	assert.Zero(t, total)
+}
+
+func TestSavedQueries(t *testing.T) {
+	svc, _, db := setupWorkbench(t)
+	ctx := context.Background()
+	other := Subject{Username: "other", Roles: []string{models.RoleConsumer}}
+
+	_, err := svc.CreateSavedQuery(ctx, &SavedQueryRequest{Name: "清空", SQL: "TRUNCATE parking.vehicle_access"}, "analyst")
+	assert.ErrorIs(t, err, ErrStatementRejected, "保存时检查语句")
+	_, err = svc.CreateSavedQuery(ctx, &SavedQueryRequest{Name: " ", SQL: "SELECT 1"}, "analyst")
+	assert.ErrorIs(t, err, ErrInvalidSavedQuery)
+
+	private, err := svc.CreateSavedQuery(ctx, &SavedQueryRequest{Name: "车辆进出", SQL: "SELECT id, plate_no FROM parking.vehicle_access"}, "analyst")
+	require.NoError(t, err)
+	shared, err := svc.CreateSavedQuery(ctx, &SavedQueryRequest{Name: "共享的车辆统计", SQL: "SELECT count(*) FROM parking.vehicle_access", IsShared: true}, "analyst")
+	require.NoError(t, err)
+
+	list, total, err := svc.ListSavedQueries(ctx, "other", "", 1, 10)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	assert.Equal(t, shared.ID, list[0].ID)
+	_, total, err = svc.ListSavedQueries(ctx, "analyst", "车辆", 1, 10)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+
+	_, err = svc.GetSavedQuery(ctx, private.ID, other)
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound, "其他用户的私有查询不可见")
+	_, err = svc.Execute(ctx, &QueryRequest{SavedQueryID: private.ID}, other)
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+
+	_, err = svc.UpdateSavedQuery(ctx, shared.ID, &SavedQueryRequest{Name: "改名", SQL: "SELECT 1"}, other)
+	assert.ErrorIs(t, err, ErrSavedQueryForbidden)
+	assert.ErrorIs(t, svc.DeleteSavedQuery(ctx, shared.ID, other), ErrSavedQueryForbidden)
+
+	result, err := svc.Execute(ctx, &QueryRequest{SavedQueryID: shared.ID}, other)
+	require.NoError(t, err, "共享的查询可以执行")
+	assert.Equal(t, encryption.MaskedValue, result.Rows[0][1])
+	log := lastAudit(t, db)
+	assert.Equal(t, shared.ID, *log.ObjectID)
+	assert.Equal(t, "共享的车辆统计", log.OperationContent["saved_query_name"])
+	var reloaded models.SavedQuery
+	require.NoError(t, db.First(&reloaded, "id = ?", shared.ID).Error)
+	assert.NotNil(t, reloaded.LastRunAt)
+
+	updated, err := svc.UpdateSavedQuery(ctx, private.ID, &SavedQueryRequest{Name: "车辆进出明细", SQL: "SELECT * FROM parking.vehicle_access", IsShared: true}, analyst)
+	require.NoError(t, err)
+	var stored models.SavedQuery
+	require.NoError(t, db.First(&stored, "id = ?", private.ID).Error)
+	assert.Equal(t, updated.SQL, stored.SQL)
+	assert.True(t, stored.IsShared)
+
+	require.NoError(t, svc.DeleteSavedQuery(ctx, private.ID, admin), "管理员可以删除")
+	_, err = svc.GetSavedQuery(ctx, private.ID, analyst)
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+}
+
+func TestPlanRelations(t *testing.T) {
+	plan := `[{"Plan": {"Node Type": "Hash Join", "Plans": [
+		{"Node Type": "Seq Scan", "Relation Name": "vehicle_access", "Schema": "parking", "Alias": "v"},
+		{"Node Type": "Hash", "Plans": [{"Node Type": "Index Scan", "Relation Name": "person", "Schema": "population"}]},
+		{"Node Type": "Seq Scan", "Relation Name": "vehicle_access", "Schema": "parking", "Alias": "v2"},
+		{"Node Type": "Function Scan", "Function Name": "generate_series"}
+	]}}]`
+	relations, err := planRelations([]byte(plan))
+	require.NoError(t, err)
+	assert.Equal(t, []Relation{{Schema: "parking", Table: "vehicle_access"}, {Schema: "population", Table: "person"}}, relations)
+
+	_, err = planRelations([]byte("not json"))
+	assert.Error(t, err)
+}
@@ -1 +1,4 @@
-swag init --parseDependency --parseInternal --parseDepth 1
+# 管理API文档（排除数据访问接口），入口为main.go
+swag init --parseDependency --parseInternal --parseDepth 1 --templateDelims '[[,]]' --instanceName management --tags '!数据访问'
+# 数据共享API文档（只包含数据访问接口），入口为api/swagger/swagger.go
+swag init --parseDependency --parseInternal --parseDepth 1 --templateDelims '[[,]]' --instanceName sharing --tags '数据访问' -g api/swagger/swagger.go